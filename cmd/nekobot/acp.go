@@ -20,6 +20,7 @@ import (
 	"nekobot/pkg/config"
 	"nekobot/pkg/logger"
 	"nekobot/pkg/permissionrules"
+	"nekobot/pkg/plugins"
 	"nekobot/pkg/process"
 	"nekobot/pkg/prompts"
 	"nekobot/pkg/providers"
@@ -76,6 +77,7 @@ func runACP(cmd *cobra.Command, args []string) {
 		accountbindings.Module,
 		runtimetopology.Module,
 		agent.Module,
+		plugins.Module,
 		fx.Invoke(func(lc fx.Lifecycle, log *logger.Logger, ag *agent.Agent) {
 			lc.Append(fx.Hook{
 				OnStart: func(context.Context) error {
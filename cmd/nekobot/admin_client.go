@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"nekobot/pkg/usage"
+)
+
+// adminTokenEnv supplies the WebUI bearer token when --token is not set.
+const adminTokenEnv = "NEKOBOT_API_TOKEN"
+
+// adminAPIClient talks to the WebUI admin API of a running instance.
+type adminAPIClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// adminAPIError describes a non-2xx response from the admin API.
+type adminAPIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *adminAPIError) Error() string {
+	if e.StatusCode == http.StatusUnauthorized {
+		return fmt.Sprintf("authentication required (pass --token or set %s): %s", adminTokenEnv, e.Message)
+	}
+	return fmt.Sprintf("admin API returned %d: %s", e.StatusCode, e.Message)
+}
+
+func newAdminAPIClient(baseURL, token string) *adminAPIClient {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		baseURL = getWebUIBase()
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		token = strings.TrimSpace(os.Getenv(adminTokenEnv))
+	}
+	return &adminAPIClient{
+		baseURL:    baseURL,
+		token:      token,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (c *adminAPIClient) do(ctx context.Context, method, path string, payload, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(data))
+		var apiErr struct {
+			Error   string `json:"error"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil {
+			if strings.TrimSpace(apiErr.Error) != "" {
+				message = strings.TrimSpace(apiErr.Error)
+			} else if strings.TrimSpace(apiErr.Message) != "" {
+				message = strings.TrimSpace(apiErr.Message)
+			}
+		}
+		if message == "" {
+			message = resp.Status
+		}
+		return &adminAPIError{StatusCode: resp.StatusCode, Message: message}
+	}
+	if out == nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// providerRuntimeStatus mirrors one item of GET /api/providers/runtime.
+type providerRuntimeStatus struct {
+	Name                     string         `json:"name"`
+	Available                bool           `json:"available"`
+	InCooldown               bool           `json:"in_cooldown"`
+	ErrorCount               int            `json:"error_count"`
+	CooldownRemainingSeconds int            `json:"cooldown_remaining_seconds"`
+	FailureCounts            map[string]int `json:"failure_counts"`
+	DisabledReason           string         `json:"disabled_reason"`
+	LastFailureUnix          int64          `json:"last_failure_unix"`
+	CooldownEndUnix          int64          `json:"cooldown_end_unix"`
+	DisabledUntilUnix        int64          `json:"disabled_until_unix"`
+}
+
+// ProviderRuntime returns failover cooldown snapshots for every configured provider.
+func (c *adminAPIClient) ProviderRuntime(ctx context.Context) ([]providerRuntimeStatus, error) {
+	var items []providerRuntimeStatus
+	if err := c.do(ctx, http.MethodGet, "/api/providers/runtime", nil, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ClearProviderCooldown resets failover cooldown state for one provider.
+func (c *adminAPIClient) ClearProviderCooldown(ctx context.Context, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("provider name is required")
+	}
+	return c.do(ctx, http.MethodPost, "/api/providers/"+url.PathEscape(name)+"/clear-cooldown", nil, nil)
+}
+
+// UsageReport fetches aggregated token usage from GET /api/reports/usage.
+// Empty bounds use the server defaults.
+func (c *adminAPIClient) UsageReport(ctx context.Context, from, to, groupBy string) (*usage.Report, error) {
+	query := url.Values{}
+	if from = strings.TrimSpace(from); from != "" {
+		query.Set("from", from)
+	}
+	if to = strings.TrimSpace(to); to != "" {
+		query.Set("to", to)
+	}
+	if groupBy = strings.TrimSpace(groupBy); groupBy != "" {
+		query.Set("groupBy", groupBy)
+	}
+	path := "/api/reports/usage"
+	if encoded := query.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	var report usage.Report
+	if err := c.do(ctx, http.MethodGet, path, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"nekobot/pkg/backup"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/providerstore"
+)
+
+var backupRestoreOnly []string
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up and restore the runtime database, memory files and config",
+	Long: `Write and restore tar.gz backups holding the SQLite runtime database, the
+workspace memory files (MEMORY.md and memory/) and a config export.
+
+Archives go to the local directory or S3-compatible bucket configured in the
+backup section. With backup.enabled set, the gateway also writes one every
+backup.interval_hours and prunes old ones (backup.keep, backup.max_age_days).
+
+The config export includes API keys and channel tokens; keep archives private.
+
+Examples:
+  nekobot backup now
+  nekobot backup list
+  nekobot backup restore nekobot-backup-20261018T030000Z.tar.gz
+  nekobot backup restore ./nekobot-backup-20261018T030000Z.tar.gz --only memory`,
+}
+
+var backupNowCmd = &cobra.Command{
+	Use:          "now",
+	Short:        "Write a backup now and apply retention",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runBackupNow,
+}
+
+var backupListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List the backups in the configured target",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runBackupList,
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <archive>",
+	Short: "Restore a backup by name or local file path",
+	Long: `Restore a backup by its name in the configured target or a local file path.
+
+Stop the gateway first ("nekobot gateway stop"): the SQLite database is
+replaced on disk, and the previous one is kept with a .pre-restore suffix.
+The database already holds the runtime config, so the archived config export
+is applied only when the database is not restored, e.g. with --only config
+or a PostgreSQL/MySQL runtime database.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runBackupRestore,
+}
+
+func init() {
+	backupRestoreCmd.Flags().StringSliceVar(&backupRestoreOnly, "only", []string{"db", "memory", "config"}, "Parts to restore: db, memory, config")
+
+	backupCmd.AddCommand(backupNowCmd)
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+	rootCmd.AddCommand(backupCmd)
+}
+
+func runBackupNow(cmd *cobra.Command, args []string) error {
+	store, err := openRuntimeConfigStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	log, err := logger.New(&logger.Config{Level: logger.LevelError})
+	if err != nil {
+		return fmt.Errorf("create logger: %w", err)
+	}
+	mgr := backup.New(store.cfg, log, store.providers)
+	archive, err := mgr.Run(commandContext(cmd))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "✅ Backup %s (%s) written to %s\n",
+		archive.Name, formatBackupSize(archive.Size), mgr.Status().Location)
+	return nil
+}
+
+func runBackupList(cmd *cobra.Command, args []string) error {
+	mgr, err := newBackupCommandManager()
+	if err != nil {
+		return err
+	}
+	archives, err := mgr.List(commandContext(cmd))
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if len(archives) == 0 {
+		fmt.Fprintf(out, "No backups in %s\n", mgr.Status().Location)
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSIZE\tCREATED")
+	for _, archive := range archives {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", archive.Name, formatBackupSize(archive.Size), archive.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	return w.Flush()
+}
+
+func runBackupRestore(cmd *cobra.Command, args []string) error {
+	opts, err := parseBackupRestoreParts(backupRestoreOnly)
+	if err != nil {
+		return err
+	}
+	mgr, err := newBackupCommandManager()
+	if err != nil {
+		return err
+	}
+	ctx := commandContext(cmd)
+	result, err := mgr.Restore(ctx, args[0], opts)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if result.Database != "" {
+		fmt.Fprintf(out, "Restored database to %s (previous copy: %s.pre-restore)\n", result.Database, result.Database)
+	}
+	if opts.Memory {
+		fmt.Fprintf(out, "Restored %d memory files\n", result.MemoryFiles)
+	}
+	if result.Config != nil && result.Database == "" {
+		store, err := openRuntimeConfigStore()
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		applied, err := store.providers.ApplyDocument(ctx, result.Config, providerstore.ApplyOptions{Prune: true})
+		printConfigApplyResult(out, applied, false)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(out, "✅ Restored backup from %s\n", result.Manifest.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+// newBackupCommandManager creates a backup manager from the config with
+// database overrides applied, without holding the runtime database open.
+func newBackupCommandManager() (*backup.Manager, error) {
+	cfg, err := config.NewLoader().Load("")
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	if err := config.ApplyDatabaseOverrides(cfg); err != nil {
+		return nil, fmt.Errorf("apply runtime config: %w", err)
+	}
+	log, err := logger.New(&logger.Config{Level: logger.LevelError})
+	if err != nil {
+		return nil, fmt.Errorf("create logger: %w", err)
+	}
+	return backup.New(cfg, log, nil), nil
+}
+
+func parseBackupRestoreParts(parts []string) (backup.RestoreOptions, error) {
+	var opts backup.RestoreOptions
+	for _, part := range parts {
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "db", "database":
+			opts.Database = true
+		case "memory":
+			opts.Memory = true
+		case "config":
+			opts.Config = true
+		case "":
+		default:
+			return opts, fmt.Errorf("unknown restore part %q (use db, memory or config)", part)
+		}
+	}
+	if !opts.Database && !opts.Memory && !opts.Config {
+		return opts, fmt.Errorf("--only needs at least one of db, memory or config")
+	}
+	return opts, nil
+}
+
+func formatBackupSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestParseBackupRestoreParts(t *testing.T) {
+	opts, err := parseBackupRestoreParts([]string{"db", " Memory "})
+	if err != nil {
+		t.Fatalf("parseBackupRestoreParts failed: %v", err)
+	}
+	if !opts.Database || !opts.Memory || opts.Config {
+		t.Fatalf("unexpected options %+v", opts)
+	}
+	if _, err := parseBackupRestoreParts([]string{"skills"}); err == nil {
+		t.Fatal("expected an unknown part to fail")
+	}
+	if _, err := parseBackupRestoreParts(nil); err == nil {
+		t.Fatal("expected an empty selection to fail")
+	}
+}
+
+func TestFormatBackupSize(t *testing.T) {
+	for size, want := range map[int64]string{512: "512 B", 2048: "2.0 KB", 3 << 20: "3.0 MB"} {
+		if got := formatBackupSize(size); got != want {
+			t.Fatalf("formatBackupSize(%d) = %q, want %q", size, got, want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/providerstore"
+	"nekobot/pkg/storage/ent"
+)
+
+var (
+	configDocFile        string
+	configDocFormat      string
+	configPruneProviders bool
+	configDryRun         bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Export and apply the runtime configuration as a file",
+	Long: `Round-trip the runtime configuration stored in the database through a
+YAML or JSON file, so it can be reviewed and kept in version control.
+
+The exported file holds every runtime section (agents, channels, tools, ...)
+and the provider profiles, including API keys and channel tokens; keep it
+private.
+
+Examples:
+  nekobot config export --file config.yaml
+  nekobot config apply --file config.yaml --dry-run
+  nekobot config apply --file config.yaml --prune-providers`,
+}
+
+var configExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the runtime configuration to a file or stdout",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigExport,
+}
+
+var configApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Merge a configuration file into the runtime database",
+	Long: `Merge a configuration file into the runtime database.
+
+Each section in the file replaces the stored section; keys missing inside a
+section take their default values. Sections not in the file are left
+unchanged. Providers in the file are created or updated by name, and with
+--prune-providers stored providers missing from the file are deleted. The
+storage section is bootstrap-only and is skipped.
+
+A running gateway picks up applied sections with "nekobot gateway restart";
+use POST /api/config/apply to apply to a running instance directly.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runConfigApply,
+}
+
+func init() {
+	configExportCmd.Flags().StringVarP(&configDocFile, "file", "f", "", "Output file (default: stdout)")
+	configExportCmd.Flags().StringVar(&configDocFormat, "format", "", "yaml or json (default: from the file extension, yaml for stdout)")
+	configApplyCmd.Flags().StringVarP(&configDocFile, "file", "f", "", "Configuration file to apply (.yaml, .yml or .json)")
+	configApplyCmd.Flags().BoolVar(&configPruneProviders, "prune-providers", false, "Delete stored providers that are missing from the file")
+	configApplyCmd.Flags().BoolVar(&configDryRun, "dry-run", false, "Show what would change without writing anything")
+	_ = configApplyCmd.MarkFlagRequired("file")
+
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configApplyCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// runtimeConfigStore is the config with database overrides applied, plus
+// the provider store backing it.
+type runtimeConfigStore struct {
+	cfg       *config.Config
+	client    *ent.Client
+	providers *providerstore.Manager
+}
+
+func openRuntimeConfigStore() (*runtimeConfigStore, error) {
+	cfg, err := config.NewLoader().Load("")
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("open runtime database: %w", err)
+	}
+	if err := config.PrepareRuntimeEntSchema(cfg, client); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("ensure runtime schema: %w", err)
+	}
+	if err := config.ApplyDatabaseOverrides(cfg); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("apply runtime config: %w", err)
+	}
+	log, err := logger.New(&logger.Config{Level: logger.LevelError})
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("create logger: %w", err)
+	}
+	providers, err := providerstore.NewManager(cfg, log, client)
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("open provider store: %w", err)
+	}
+	return &runtimeConfigStore{cfg: cfg, client: client, providers: providers}, nil
+}
+
+func (s *runtimeConfigStore) Close() {
+	_ = s.client.Close()
+}
+
+func runConfigExport(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(strings.TrimSpace(configDocFormat))
+	if format == "" {
+		format = config.DocumentFormatYAML
+		if configDocFile != "" {
+			format = config.DocumentFormatFromPath(configDocFile)
+		}
+	}
+	if format != config.DocumentFormatYAML && format != config.DocumentFormatJSON {
+		return fmt.Errorf("unsupported format %q (use yaml or json)", configDocFormat)
+	}
+
+	store, err := openRuntimeConfigStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	doc, err := store.providers.ExportDocument(commandContext(cmd))
+	if err != nil {
+		return err
+	}
+	data, err := config.MarshalDocument(doc, format)
+	if err != nil {
+		return err
+	}
+
+	if configDocFile == "" {
+		_, err := cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(configDocFile, data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", configDocFile, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Exported %d sections and %d providers to %s\n",
+		len(doc.Sections), len(doc.Providers), configDocFile)
+	return nil
+}
+
+func runConfigApply(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(configDocFile)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", configDocFile, err)
+	}
+	doc, err := config.ParseDocument(data, config.DocumentFormatFromPath(configDocFile))
+	if err != nil {
+		return err
+	}
+
+	store, err := openRuntimeConfigStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	result, err := store.providers.ApplyDocument(commandContext(cmd), doc, providerstore.ApplyOptions{
+		Prune:  configPruneProviders,
+		DryRun: configDryRun,
+	})
+	printConfigApplyResult(cmd.OutOrStdout(), result, configDryRun)
+	return err
+}
+
+func printConfigApplyResult(out io.Writer, result providerstore.DocumentResult, dryRun bool) {
+	verb := "Applied"
+	if dryRun {
+		verb = "Would apply"
+	}
+	if result.Empty() {
+		fmt.Fprintln(out, "No changes.")
+	}
+	if len(result.Sections) > 0 {
+		fmt.Fprintf(out, "%s sections: %s\n", verb, strings.Join(result.Sections, ", "))
+	}
+	for _, change := range []struct {
+		label string
+		names []string
+	}{
+		{"created", result.Providers.Created},
+		{"updated", result.Providers.Updated},
+		{"deleted", result.Providers.Deleted},
+	} {
+		if len(change.names) > 0 {
+			fmt.Fprintf(out, "%s providers %s: %s\n", verb, change.label, strings.Join(change.names, ", "))
+		}
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Fprintf(out, "Skipped bootstrap sections: %s (edit the bootstrap config file instead)\n", strings.Join(result.Skipped, ", "))
+	}
+}
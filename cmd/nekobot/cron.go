@@ -19,6 +19,7 @@ import (
 	"nekobot/pkg/cron"
 	"nekobot/pkg/logger"
 	"nekobot/pkg/permissionrules"
+	"nekobot/pkg/plugins"
 	"nekobot/pkg/prompts"
 	"nekobot/pkg/providers"
 	"nekobot/pkg/providerstore"
@@ -386,6 +387,7 @@ func buildCronManagerOrExit() (*cron.Manager, func()) {
 		accountbindings.Module,
 		runtimetopology.Module,
 		agent.Module,
+		plugins.Module,
 		cron.Module,
 
 		fx.Populate(&cronManager),
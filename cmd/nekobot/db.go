@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"nekobot/pkg/config"
+)
+
+var (
+	dbMigrateDryRun bool
+	dbCopyFrom      string
+	dbCopyFromType  string
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Inspect, migrate and copy the runtime database",
+	Long: `Inspect, migrate and copy the runtime database selected by storage.db_type
+and storage.db_dsn (or NEKOBOT_DB_TYPE and NEKOBOT_DB_DSN).
+
+Schema changes are applied automatically when the database is opened. When
+several replicas share one PostgreSQL or MySQL database, set
+storage.auto_migrate to false and run "nekobot db migrate" once per upgrade.
+
+Examples:
+  nekobot db status
+  nekobot db migrate --dry-run
+  NEKOBOT_DB_TYPE=postgres NEKOBOT_DB_DSN=postgres://... nekobot db copy --from ./nekobot.db`,
+}
+
+var dbStatusCmd = &cobra.Command{
+	Use:          "status",
+	Short:        "Show the runtime database, pool settings and pending schema changes",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDBStatus,
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:          "migrate",
+	Short:        "Apply pending schema changes to the runtime database",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDBMigrate,
+}
+
+var dbCopyCmd = &cobra.Command{
+	Use:   "copy",
+	Short: "Copy another runtime database into the configured one",
+	Long: `Copy every runtime table from another database into the configured runtime
+database, e.g. to move a single-node SQLite nekobot.db to PostgreSQL or MySQL.
+
+The target schema is applied first and the target must be empty. Stop the
+gateway writing to the source before copying.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDBCopy,
+}
+
+func init() {
+	dbMigrateCmd.Flags().BoolVar(&dbMigrateDryRun, "dry-run", false, "Print the schema changes without applying them")
+	dbCopyCmd.Flags().StringVar(&dbCopyFrom, "from", "", "Source database DSN, or file path for SQLite")
+	dbCopyCmd.Flags().StringVar(&dbCopyFromType, "from-type", "sqlite", "Source database type: sqlite, postgres or mysql")
+	_ = dbCopyCmd.MarkFlagRequired("from")
+
+	dbCmd.AddCommand(dbStatusCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbCmd.AddCommand(dbCopyCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+func loadDBCommandConfig() (*config.Config, error) {
+	cfg, err := config.NewLoader().Load("")
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	return cfg, nil
+}
+
+func runDBStatus(cmd *cobra.Command, args []string) error {
+	cfg, err := loadDBCommandConfig()
+	if err != nil {
+		return err
+	}
+	location, err := config.RuntimeDBDisplayName(cfg)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Type:         %s\n", cfg.DatabaseType())
+	fmt.Fprintf(out, "Location:     %s\n", location)
+	fmt.Fprintf(out, "Pool:         %s\n", formatDBPool(cfg.Storage))
+	fmt.Fprintf(out, "Auto migrate: %t\n", cfg.Storage.AutoMigrate)
+
+	changes, err := pendingDBSchemaChanges(cmd, cfg)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "Schema:       up to date")
+	} else {
+		fmt.Fprintf(out, "Schema:       %d pending statements (run \"nekobot db migrate\")\n", len(changes))
+	}
+	return nil
+}
+
+func runDBMigrate(cmd *cobra.Command, args []string) error {
+	cfg, err := loadDBCommandConfig()
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if dbMigrateDryRun {
+		changes, err := pendingDBSchemaChanges(cmd, cfg)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			fmt.Fprintln(out, "Schema is up to date.")
+			return nil
+		}
+		for _, change := range changes {
+			fmt.Fprintln(out, change)
+		}
+		return nil
+	}
+
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+	if err := config.EnsureRuntimeEntSchema(client); err != nil {
+		return err
+	}
+	location, _ := config.RuntimeDBDisplayName(cfg)
+	fmt.Fprintf(out, "✅ Schema of %s is up to date\n", location)
+	return nil
+}
+
+func runDBCopy(cmd *cobra.Command, args []string) error {
+	cfg, err := loadDBCommandConfig()
+	if err != nil {
+		return err
+	}
+	copied, err := config.CopyRuntimeDB(commandContext(cmd), cfg, dbCopyFromType, dbCopyFrom)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	total := 0
+	for _, table := range copied {
+		if table.Rows > 0 {
+			fmt.Fprintf(out, "  %-24s %d\n", table.Table, table.Rows)
+		}
+		total += table.Rows
+	}
+	location, _ := config.RuntimeDBDisplayName(cfg)
+	fmt.Fprintf(out, "✅ Copied %d rows into %s\n", total, location)
+	return nil
+}
+
+// pendingDBSchemaChanges returns the statements "db migrate" would run.
+func pendingDBSchemaChanges(cmd *cobra.Command, cfg *config.Config) ([]string, error) {
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+	var buf bytes.Buffer
+	if err := client.Schema.WriteTo(commandContext(cmd), &buf); err != nil {
+		return nil, fmt.Errorf("plan schema changes: %w", err)
+	}
+	var changes []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "--") || strings.HasPrefix(line, "PRAGMA foreign_keys") {
+			continue
+		}
+		changes = append(changes, line)
+	}
+	return changes, nil
+}
+
+func formatDBPool(storage config.StorageConfig) string {
+	limit := func(value int, unit string) string {
+		if value <= 0 {
+			return "default"
+		}
+		return fmt.Sprintf("%d%s", value, unit)
+	}
+	return fmt.Sprintf("max_open=%s max_idle=%s max_lifetime=%s max_idle_time=%s",
+		limit(storage.MaxOpenConns, ""), limit(storage.MaxIdleConns, ""),
+		limit(storage.ConnMaxLifetimeSeconds, "s"), limit(storage.ConnMaxIdleTimeSeconds, "s"))
+}
@@ -0,0 +1,502 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/providerregistry"
+	"nekobot/pkg/providers"
+	"nekobot/pkg/providerstore"
+	"nekobot/pkg/runtimeagents"
+	"nekobot/pkg/storage/ent"
+)
+
+var doctorTimeout time.Duration
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose configuration, storage, providers and channels",
+	Long: `Run diagnostics against the local installation and print a pass/fail
+result with a suggested fix for every problem found.
+
+Checks cover config validity, runtime database connectivity and schema,
+provider reachability (a lightweight model list request), channel bot tokens,
+tmux/docker availability for tool sessions and workspace permissions.
+
+The command exits non-zero when any check fails; warnings do not fail it.
+
+Examples:
+  nekobot doctor
+  nekobot doctor --timeout 30s`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().DurationVar(&doctorTimeout, "timeout", 10*time.Second, "Timeout for each network check")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+type doctorStatus string
+
+const (
+	doctorPass doctorStatus = "pass"
+	doctorWarn doctorStatus = "warn"
+	doctorFail doctorStatus = "fail"
+)
+
+// doctorCheck is the result of one diagnostic.
+type doctorCheck struct {
+	Name   string
+	Status doctorStatus
+	Detail string
+	Hint   string
+}
+
+// doctor runs the diagnostics. The endpoints and command lookups are fields
+// so tests can point them at fakes.
+type doctor struct {
+	timeout     time.Duration
+	httpClient  *http.Client
+	telegramAPI string
+	discordAPI  string
+	slackAPI    string
+	lookPath    func(string) (string, error)
+	runCommand  func(ctx context.Context, name string, args ...string) error
+}
+
+func newDoctor(timeout time.Duration) *doctor {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &doctor{
+		timeout:     timeout,
+		httpClient:  &http.Client{},
+		telegramAPI: "https://api.telegram.org",
+		discordAPI:  "https://discord.com/api/v10",
+		slackAPI:    "https://slack.com/api",
+		lookPath:    exec.LookPath,
+		runCommand: func(ctx context.Context, name string, args ...string) error {
+			return exec.CommandContext(ctx, name, args...).Run()
+		},
+	}
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	d := newDoctor(doctorTimeout)
+	checks := make([]doctorCheck, 0, 8)
+	report := func(check doctorCheck) {
+		checks = append(checks, check)
+		printDoctorCheck(cmd.OutOrStdout(), check)
+	}
+
+	loader := config.NewLoader()
+	cfg, err := loader.Load("")
+	if err != nil {
+		report(doctorCheck{Name: "config", Status: doctorFail, Detail: err.Error(),
+			Hint: "fix the config file syntax or point " + config.ConfigPathEnv + " at a valid file"})
+		return printDoctorSummary(cmd.OutOrStdout(), checks)
+	}
+	configPath := loader.GetConfigPath()
+	if configPath == "" {
+		configPath = "none found, wrote defaults"
+	}
+	report(doctorCheck{Name: "config file", Status: doctorPass, Detail: configPath})
+
+	client, dbCheck := d.checkDatabase(cfg)
+	report(dbCheck)
+	if client != nil {
+		defer func() {
+			_ = client.Close()
+		}()
+	}
+	report(d.checkConfig(cfg))
+
+	if client != nil {
+		log, err := logger.New(&logger.Config{Level: logger.LevelError})
+		if err != nil {
+			return fmt.Errorf("create logger: %w", err)
+		}
+		store, err := providerstore.NewManager(cfg, log, client)
+		if err != nil {
+			report(doctorCheck{Name: "providers", Status: doctorFail, Detail: err.Error(),
+				Hint: "check the providers table in the runtime database"})
+		} else {
+			profiles, err := store.List(ctx)
+			if err != nil {
+				report(doctorCheck{Name: "providers", Status: doctorFail, Detail: err.Error(),
+					Hint: "check the providers table in the runtime database"})
+			} else {
+				for _, check := range d.checkProviders(ctx, profiles) {
+					report(check)
+				}
+			}
+		}
+	}
+
+	for _, check := range d.checkChannels(ctx, cfg) {
+		report(check)
+	}
+	for _, check := range d.checkToolSessions(ctx, cfg) {
+		report(check)
+	}
+	report(d.checkWorkspace(cfg.WorkspacePath()))
+
+	return printDoctorSummary(cmd.OutOrStdout(), checks)
+}
+
+func printDoctorCheck(out io.Writer, check doctorCheck) {
+	icon := "✅"
+	switch check.Status {
+	case doctorWarn:
+		icon = "⚠️ "
+	case doctorFail:
+		icon = "❌"
+	}
+	fmt.Fprintf(out, "%s %s: %s\n", icon, check.Name, check.Detail)
+	if check.Status != doctorPass && check.Hint != "" {
+		fmt.Fprintf(out, "   → %s\n", check.Hint)
+	}
+}
+
+// printDoctorSummary prints the totals and returns an error when any check
+// failed, so the command exits non-zero.
+func printDoctorSummary(out io.Writer, checks []doctorCheck) error {
+	counts := map[doctorStatus]int{}
+	for _, check := range checks {
+		counts[check.Status]++
+	}
+	fmt.Fprintf(out, "\n%d passed, %d warnings, %d failed\n", counts[doctorPass], counts[doctorWarn], counts[doctorFail])
+	if counts[doctorFail] > 0 {
+		return fmt.Errorf("%d doctor checks failed", counts[doctorFail])
+	}
+	return nil
+}
+
+// checkDatabase opens the runtime database, brings its schema up to date and
+// applies the runtime config sections stored there. The client is nil when
+// the database is unusable.
+func (d *doctor) checkDatabase(cfg *config.Config) (*ent.Client, doctorCheck) {
+	check := doctorCheck{Name: "database"}
+	dbType := cfg.DatabaseType()
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+		check.Hint = "check storage.db_type and storage.db_dsn (or " + config.DBDSNEnv + ")"
+		return nil, check
+	}
+	if err := config.PrepareRuntimeEntSchema(cfg, client); err != nil {
+		_ = client.Close()
+		check.Status, check.Detail = doctorFail, err.Error()
+		check.Hint = "make sure the " + dbType + " database is reachable and the user may create tables"
+		return nil, check
+	}
+	if err := config.ApplyDatabaseOverrides(cfg); err != nil {
+		check.Status, check.Detail = doctorWarn, "runtime config sections: "+err.Error()
+		check.Hint = "re-save the affected settings in the WebUI or check storage.master_key_file"
+		return client, check
+	}
+	check.Status, check.Detail = doctorPass, dbType+" connected, schema up to date"
+	return client, check
+}
+
+func (d *doctor) checkConfig(cfg *config.Config) doctorCheck {
+	if err := config.ValidateConfig(cfg); err != nil {
+		return doctorCheck{Name: "config", Status: doctorFail, Detail: err.Error(),
+			Hint: "correct the listed fields in the config file or the WebUI settings"}
+	}
+	return doctorCheck{Name: "config", Status: doctorPass, Detail: "valid"}
+}
+
+func (d *doctor) checkProviders(ctx context.Context, profiles []config.ProviderProfile) []doctorCheck {
+	checks := make([]doctorCheck, 0, len(profiles))
+	for i := range profiles {
+		profile := profiles[i]
+		if !profile.Enabled {
+			continue
+		}
+		checks = append(checks, d.checkProvider(ctx, &profile))
+	}
+	if len(checks) == 0 {
+		checks = append(checks, doctorCheck{Name: "providers", Status: doctorFail, Detail: "no enabled providers",
+			Hint: "add a provider with `nekobot onboard` or in the WebUI"})
+	}
+	return checks
+}
+
+// checkProvider lists the provider's models, which verifies both the
+// endpoint and the API key without spending tokens.
+func (d *doctor) checkProvider(ctx context.Context, profile *config.ProviderProfile) doctorCheck {
+	check := doctorCheck{Name: "provider " + profile.Name}
+	req, err := providerModelsRequest(ctx, profile)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+		check.Hint = "set api_base for provider " + profile.Name
+		return check
+	}
+	client, err := providers.NewHTTPClientWithProxy(profile.Proxy)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+		check.Hint = "fix the proxy URL of provider " + profile.Name
+		return check
+	}
+	status, _, err := d.do(client, req)
+	switch {
+	case err != nil:
+		check.Status, check.Detail = doctorFail, "unreachable: "+err.Error()
+		check.Hint = "check api_base, proxy and network access to " + req.URL.Host
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		check.Status, check.Detail = doctorFail, fmt.Sprintf("API key rejected (HTTP %d)", status)
+		check.Hint = "update the API key of provider " + profile.Name
+	case status < 200 || status >= 300:
+		check.Status, check.Detail = doctorWarn, fmt.Sprintf("model list returned HTTP %d", status)
+		check.Hint = "the endpoint may not support model listing; try a chat with this provider"
+	default:
+		check.Status, check.Detail = doctorPass, "reachable at "+req.URL.Host
+	}
+	return check
+}
+
+// providerModelsRequest builds the model list request for profile's kind.
+func providerModelsRequest(ctx context.Context, profile *config.ProviderProfile) (*http.Request, error) {
+	kind := strings.ToLower(strings.TrimSpace(profile.ProviderKind))
+	base := strings.TrimRight(strings.TrimSpace(profile.APIBase), "/")
+	if base == "" {
+		if item, ok := providerregistry.Get(kind); ok {
+			base = strings.TrimRight(item.DefaultAPIBase, "/")
+		}
+	}
+	if base == "" {
+		return nil, fmt.Errorf("no api_base configured for %s provider", kind)
+	}
+	apiKey := strings.TrimSpace(profile.APIKey)
+
+	endpoint := base + "/models"
+	header := http.Header{}
+	switch kind {
+	case "anthropic", "claude":
+		if !strings.HasSuffix(base, "/v1") {
+			endpoint = base + "/v1/models"
+		}
+		header.Set("x-api-key", apiKey)
+		header.Set("anthropic-version", "2023-06-01")
+	case "gemini":
+		endpoint += "?key=" + url.QueryEscape(apiKey)
+	case "azure-openai", "azure":
+		version := strings.TrimSpace(profile.APIVersion)
+		if version == "" {
+			version = "2024-10-21"
+		}
+		endpoint = base + "/openai/models?api-version=" + url.QueryEscape(version)
+		header.Set("api-key", apiKey)
+	default:
+		if apiKey != "" {
+			header.Set("Authorization", "Bearer "+apiKey)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build model list request: %w", err)
+	}
+	req.Header = header
+	return req, nil
+}
+
+func (d *doctor) checkChannels(ctx context.Context, cfg *config.Config) []doctorCheck {
+	channels := cfg.Channels
+	checks := make([]doctorCheck, 0, 3)
+	if channels.Telegram.Enabled {
+		checks = append(checks, d.checkTelegram(ctx, channels.Telegram.Token))
+	}
+	if channels.Discord.Enabled {
+		checks = append(checks, d.checkDiscord(ctx, channels.Discord.Token))
+	}
+	if channels.Slack.Enabled {
+		checks = append(checks, d.checkSlack(ctx, channels.Slack.BotToken))
+	}
+	return checks
+}
+
+func (d *doctor) checkTelegram(ctx context.Context, token string) doctorCheck {
+	check := doctorCheck{Name: "channel telegram", Hint: "update channels.telegram.token with the token from @BotFather"}
+	if strings.TrimSpace(token) == "" {
+		check.Status, check.Detail = doctorFail, "enabled without a token"
+		return check
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.telegramAPI+"/bot"+strings.TrimSpace(token)+"/getMe", nil)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+		return check
+	}
+	var payload struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+		Result      struct {
+			Username string `json:"username"`
+		} `json:"result"`
+	}
+	_, body, err := d.do(d.httpClient, req)
+	if err == nil {
+		err = json.Unmarshal(body, &payload)
+	}
+	switch {
+	case err != nil:
+		check.Status, check.Detail = doctorFail, "unreachable: "+err.Error()
+		check.Hint = "check network access to api.telegram.org or set channels.telegram.proxy"
+	case !payload.OK:
+		check.Status, check.Detail = doctorFail, "token rejected: "+payload.Description
+	default:
+		check.Status, check.Detail = doctorPass, "connected as @"+payload.Result.Username
+	}
+	return check
+}
+
+func (d *doctor) checkDiscord(ctx context.Context, token string) doctorCheck {
+	check := doctorCheck{Name: "channel discord", Hint: "update channels.discord.token with the bot token from the Discord developer portal"}
+	if strings.TrimSpace(token) == "" {
+		check.Status, check.Detail = doctorFail, "enabled without a token"
+		return check
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.discordAPI+"/users/@me", nil)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+		return check
+	}
+	req.Header.Set("Authorization", "Bot "+strings.TrimPrefix(strings.TrimSpace(token), "Bot "))
+	var payload struct {
+		Username string `json:"username"`
+	}
+	status, body, err := d.do(d.httpClient, req)
+	switch {
+	case err != nil:
+		check.Status, check.Detail = doctorFail, "unreachable: "+err.Error()
+		check.Hint = "check network access to discord.com"
+	case status < 200 || status >= 300:
+		check.Status, check.Detail = doctorFail, fmt.Sprintf("token rejected (HTTP %d)", status)
+	default:
+		_ = json.Unmarshal(body, &payload)
+		check.Status, check.Detail = doctorPass, "connected as "+payload.Username
+	}
+	return check
+}
+
+func (d *doctor) checkSlack(ctx context.Context, token string) doctorCheck {
+	check := doctorCheck{Name: "channel slack", Hint: "update channels.slack.bot_token with the xoxb- token of your Slack app"}
+	if strings.TrimSpace(token) == "" {
+		check.Status, check.Detail = doctorFail, "enabled without a bot token"
+		return check
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.slackAPI+"/auth.test", nil)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+		return check
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(token))
+	var payload struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		User  string `json:"user"`
+		Team  string `json:"team"`
+	}
+	_, body, err := d.do(d.httpClient, req)
+	if err == nil {
+		err = json.Unmarshal(body, &payload)
+	}
+	switch {
+	case err != nil:
+		check.Status, check.Detail = doctorFail, "unreachable: "+err.Error()
+		check.Hint = "check network access to slack.com"
+	case !payload.OK:
+		check.Status, check.Detail = doctorFail, "token rejected: "+payload.Error
+	default:
+		check.Status, check.Detail = doctorPass, fmt.Sprintf("connected as %s in %s", payload.User, payload.Team)
+	}
+	return check
+}
+
+// checkToolSessions verifies the terminal multiplexer used by tool sessions
+// and, when the exec sandbox is enabled, the Docker daemon.
+func (d *doctor) checkToolSessions(ctx context.Context, cfg *config.Config) []doctorCheck {
+	transport := runtimeagents.TransportByName(cfg.WebUI.ToolSessionRuntimeTransport).Name()
+	checks := make([]doctorCheck, 0, 2)
+	if transport == runtimeagents.TransportNative {
+		checks = append(checks, doctorCheck{Name: "tool sessions", Status: doctorPass,
+			Detail: "native PTY transport; sessions will not survive restarts"})
+	} else if _, err := d.lookPath(transport); err != nil {
+		checks = append(checks, doctorCheck{Name: "tool sessions", Status: doctorWarn,
+			Detail: transport + " not found; falling back to the native PTY transport, sessions will not survive restarts",
+			Hint:   "install " + transport + " or set webui.tool_session_runtime_transport to native"})
+	} else {
+		checks = append(checks, doctorCheck{Name: "tool sessions", Status: doctorPass, Detail: transport + " available"})
+	}
+
+	sandbox := cfg.Tools.Exec.Sandbox.Enabled
+	status := doctorWarn
+	if sandbox {
+		status = doctorFail
+	}
+	check := doctorCheck{Name: "docker", Hint: "install Docker and make sure the current user can reach the daemon"}
+	if !sandbox {
+		check.Hint += ", or ignore this while tools.exec.sandbox is disabled"
+	}
+	if _, err := d.lookPath("docker"); err != nil {
+		check.Status, check.Detail = status, "docker not found"
+		return append(checks, check)
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	if err := d.runCommand(ctx, "docker", "info", "--format", "{{.ServerVersion}}"); err != nil {
+		check.Status, check.Detail = status, "docker daemon unreachable: "+err.Error()
+		return append(checks, check)
+	}
+	check.Status, check.Detail = doctorPass, "daemon reachable"
+	return append(checks, check)
+}
+
+// checkWorkspace makes sure the workspace exists and is writable.
+func (d *doctor) checkWorkspace(dir string) doctorCheck {
+	check := doctorCheck{Name: "workspace",
+		Hint: "fix the permissions of " + dir + " or set agents.defaults.workspace to a writable directory"}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+		return check
+	}
+	probe, err := os.CreateTemp(dir, ".nekobot-doctor-*")
+	if err != nil {
+		check.Status, check.Detail = doctorFail, dir+" is not writable: "+err.Error()
+		return check
+	}
+	_ = probe.Close()
+	_ = os.Remove(probe.Name())
+	check.Status, check.Detail = doctorPass, filepath.Clean(dir)+" writable"
+	return check
+}
+
+// do sends req with the doctor timeout and returns the status and body.
+func (d *doctor) do(client *http.Client, req *http.Request) (int, []byte, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), d.timeout)
+	defer cancel()
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	return resp.StatusCode, body, err
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nekobot/pkg/config"
+)
+
+func TestDoctorChecksProviderModelList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"gpt-4o"}]}`))
+	}))
+	defer server.Close()
+
+	d := newDoctor(time.Second)
+	checks := d.checkProviders(context.Background(), []config.ProviderProfile{
+		{Name: "ok", ProviderKind: "openai", APIBase: server.URL + "/v1", APIKey: "good", Enabled: true},
+		{Name: "bad-key", ProviderKind: "openai", APIBase: server.URL + "/v1", APIKey: "bad", Enabled: true},
+		{Name: "off", ProviderKind: "openai", APIBase: server.URL + "/v1", Enabled: false},
+		{Name: "down", ProviderKind: "openai", APIBase: "http://127.0.0.1:1/v1", Enabled: true},
+	})
+	if len(checks) != 3 {
+		t.Fatalf("expected disabled providers to be skipped, got %+v", checks)
+	}
+	if checks[0].Status != doctorPass {
+		t.Fatalf("expected a reachable provider, got %+v", checks[0])
+	}
+	if checks[1].Status != doctorFail || !strings.Contains(checks[1].Detail, "API key rejected") {
+		t.Fatalf("expected a rejected key, got %+v", checks[1])
+	}
+	if checks[2].Status != doctorFail || !strings.Contains(checks[2].Detail, "unreachable") {
+		t.Fatalf("expected an unreachable provider, got %+v", checks[2])
+	}
+
+	if checks := d.checkProviders(context.Background(), nil); len(checks) != 1 || checks[0].Status != doctorFail {
+		t.Fatalf("expected a failure without providers, got %+v", checks)
+	}
+}
+
+func TestDoctorChecksChannelTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/telegram/botgood/getMe":
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"username":"neko_bot"}}`))
+		case "/telegram/botbad/getMe":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"ok":false,"description":"Unauthorized"}`))
+		case "/discord/users/@me":
+			if r.Header.Get("Authorization") != "Bot good" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"username":"neko"}`))
+		case "/slack/auth.test":
+			_, _ = w.Write([]byte(`{"ok":false,"error":"invalid_auth"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	d := newDoctor(time.Second)
+	d.telegramAPI = server.URL + "/telegram"
+	d.discordAPI = server.URL + "/discord"
+	d.slackAPI = server.URL + "/slack"
+
+	cfg := config.DefaultConfig()
+	cfg.Channels.Telegram = config.TelegramConfig{Enabled: true, Token: "good"}
+	cfg.Channels.Discord = config.DiscordConfig{Enabled: true, Token: "bad"}
+	cfg.Channels.Slack = config.SlackConfig{Enabled: true, BotToken: "xoxb-bad"}
+	checks := d.checkChannels(context.Background(), cfg)
+	if len(checks) != 3 {
+		t.Fatalf("expected one check per enabled channel, got %+v", checks)
+	}
+	if checks[0].Status != doctorPass || checks[0].Detail != "connected as @neko_bot" {
+		t.Fatalf("unexpected telegram check %+v", checks[0])
+	}
+	if checks[1].Status != doctorFail || checks[1].Detail != "token rejected (HTTP 401)" {
+		t.Fatalf("unexpected discord check %+v", checks[1])
+	}
+	if checks[2].Status != doctorFail || checks[2].Detail != "token rejected: invalid_auth" {
+		t.Fatalf("unexpected slack check %+v", checks[2])
+	}
+
+	if check := d.checkTelegram(context.Background(), "bad"); check.Status != doctorFail || check.Detail != "token rejected: Unauthorized" {
+		t.Fatalf("unexpected telegram check %+v", check)
+	}
+}
+
+func TestDoctorChecksToolSessionRuntimes(t *testing.T) {
+	d := newDoctor(time.Second)
+	d.lookPath = func(name string) (string, error) {
+		if name == "tmux" {
+			return "/usr/bin/tmux", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	cfg := config.DefaultConfig()
+	checks := d.checkToolSessions(context.Background(), cfg)
+	if len(checks) != 2 || checks[0].Status != doctorPass || checks[1].Status != doctorWarn {
+		t.Fatalf("expected tmux to pass and missing docker to warn, got %+v", checks)
+	}
+
+	cfg.Tools.Exec.Sandbox.Enabled = true
+	if checks := d.checkToolSessions(context.Background(), cfg); checks[1].Status != doctorFail {
+		t.Fatalf("expected missing docker to fail with the sandbox enabled, got %+v", checks[1])
+	}
+
+	d.lookPath = func(name string) (string, error) { return "/usr/bin/" + name, nil }
+	d.runCommand = func(context.Context, string, ...string) error { return nil }
+	if checks := d.checkToolSessions(context.Background(), cfg); checks[1].Status != doctorPass {
+		t.Fatalf("expected a reachable docker daemon, got %+v", checks[1])
+	}
+
+	cfg.WebUI.ToolSessionRuntimeTransport = "native"
+	if checks := d.checkToolSessions(context.Background(), cfg); checks[0].Status != doctorPass || !strings.Contains(checks[0].Detail, "native") {
+		t.Fatalf("expected the native transport to pass without a multiplexer, got %+v", checks[0])
+	}
+}
+
+func TestDoctorChecksWorkspace(t *testing.T) {
+	d := newDoctor(time.Second)
+	dir := filepath.Join(t.TempDir(), "workspace")
+	if check := d.checkWorkspace(dir); check.Status != doctorPass {
+		t.Fatalf("expected a writable workspace, got %+v", check)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected the probe file to be removed, got %v %v", entries, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if check := d.checkWorkspace(file); check.Status != doctorFail || check.Hint == "" {
+		t.Fatalf("expected an unusable workspace to fail with a hint, got %+v", check)
+	}
+}
+
+func TestPrintDoctorSummaryFailsOnFailedChecks(t *testing.T) {
+	var out bytes.Buffer
+	checks := []doctorCheck{
+		{Name: "config", Status: doctorPass, Detail: "valid"},
+		{Name: "docker", Status: doctorWarn, Detail: "docker not found", Hint: "install Docker"},
+	}
+	for _, check := range checks {
+		printDoctorCheck(&out, check)
+	}
+	if err := printDoctorSummary(&out, checks); err != nil {
+		t.Fatalf("expected warnings not to fail, got %v", err)
+	}
+	if !strings.Contains(out.String(), "   → install Docker") || !strings.Contains(out.String(), "1 passed, 1 warnings, 0 failed") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	checks = append(checks, doctorCheck{Name: "workspace", Status: doctorFail})
+	if err := printDoctorSummary(&out, checks); err == nil {
+		t.Fatal("expected a failed check to fail the command")
+	}
+}
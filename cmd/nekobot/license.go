@@ -188,7 +188,7 @@ func openLicenseEntClient() (*ent.Client, func()) {
 	if err != nil {
 		exitLicenseError("open runtime database", err)
 	}
-	if err := config.EnsureRuntimeEntSchema(client); err != nil {
+	if err := config.PrepareRuntimeEntSchema(cfg, client); err != nil {
 		_ = client.Close()
 		exitLicenseError("ensure runtime schema", err)
 	}
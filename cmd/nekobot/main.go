@@ -11,21 +11,26 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/chzyer/readline"
 	"github.com/spf13/cobra"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"golang.org/x/term"
 
 	"nekobot/pkg/accountbindings"
 	"nekobot/pkg/agent"
+	"nekobot/pkg/approval"
 	"nekobot/pkg/audit"
 	"nekobot/pkg/bus"
 	"nekobot/pkg/channelaccounts"
 	"nekobot/pkg/commands"
 	"nekobot/pkg/config"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/motd"
 	"nekobot/pkg/permissionrules"
+	"nekobot/pkg/plugins"
 	"nekobot/pkg/process"
 	"nekobot/pkg/prompts"
 	"nekobot/pkg/providers"
@@ -36,6 +41,7 @@ import (
 	"nekobot/pkg/skills"
 	"nekobot/pkg/state"
 	"nekobot/pkg/tools"
+	"nekobot/pkg/toolsessions"
 	"nekobot/pkg/version"
 	"nekobot/pkg/watch"
 	"nekobot/pkg/workspace"
@@ -50,6 +56,12 @@ var (
 	debugMode  bool
 	agentModel string
 	agentProv  string
+	plainMode  bool
+	agentStdin bool
+	persona    string
+	personaSet bool
+	// outputFormat is "text" or "json" for one-shot answers.
+	outputFormat string
 )
 
 var rootCmd = &cobra.Command{
@@ -86,8 +98,17 @@ Examples:
   # Use specific session
   nekobot agent -s my-session
 
+  # Line-based prompt instead of the terminal UI
+  nekobot agent --plain
+
+  # Give this session a persona (persisted; pass "" to clear)
+  nekobot agent -s pirate --persona "You are a pirate. Answer in pirate speak."
+
   # Use specific model/provider
-  nekobot agent -m "Hello" --model claude-opus-4-6 --provider anthropic`,
+  nekobot agent -m "Hello" --model claude-opus-4-6 --provider anthropic
+
+  # Scripts and CI: prompt from stdin, JSON result, exit code 1 on failure
+  git diff | nekobot agent --stdin -m "Review this diff" --output json`,
 	Run: runAgent,
 }
 
@@ -112,6 +133,10 @@ func init() {
 	agentCmd.Flags().BoolVarP(&debugMode, "debug", "d", false, "enable debug mode")
 	agentCmd.Flags().StringVar(&agentModel, "model", "", "override model")
 	agentCmd.Flags().StringVar(&agentProv, "provider", "", "override provider")
+	agentCmd.Flags().StringVar(&persona, "persona", "", "set a custom system prompt for the session")
+	agentCmd.Flags().BoolVar(&plainMode, "plain", false, "use the line-based prompt instead of the terminal UI")
+	agentCmd.Flags().BoolVar(&agentStdin, "stdin", false, "read the message from standard input (appended to --message)")
+	agentCmd.Flags().StringVarP(&outputFormat, "output", "o", outputText, "one-shot output format: text or json")
 
 	// Add commands
 	rootCmd.AddCommand(agentCmd)
@@ -120,6 +145,17 @@ func init() {
 }
 
 func runAgent(cmd *cobra.Command, args []string) {
+	personaSet = cmd.Flags().Changed("persona")
+	if outputFormat != outputText && outputFormat != outputJSON {
+		fmt.Fprintf(os.Stderr, "Error: unsupported --output %q (use text or json)\n", outputFormat)
+		os.Exit(2)
+	}
+	// In pipe mode stdout carries only the result; banners and console
+	// logs go to stderr.
+	resultOut := os.Stdout
+	if pipeMode() {
+		os.Stdout = os.Stderr
+	}
 	if debugMode {
 		fmt.Println("🔍 Debug mode enabled")
 	}
@@ -146,6 +182,22 @@ func runAgent(cmd *cobra.Command, args []string) {
 		cancel()
 	}()
 
+	if pipeMode() {
+		if agentStdin {
+			if message, err = readPipeMessage(os.Stdin, message); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
+		}
+		if message == "" {
+			fmt.Fprintln(os.Stderr, "Error: no message given; use --message or pipe one with --stdin")
+			os.Exit(2)
+		}
+		code := runPipe(ctx, cancel, resultOut)
+		cancel()
+		os.Exit(code)
+	}
+
 	// If message is provided, run in one-shot mode
 	if message != "" {
 		runOneShot(ctx, cancel)
@@ -170,6 +222,8 @@ func runOneShot(ctx context.Context, cancel context.CancelFunc) {
 		state.Module,
 		process.Module,
 		watch.Module,
+		approval.Module,
+		toolsessions.Module,
 		prompts.Module,
 		providerstore.Module,
 		permissionrules.Module,
@@ -178,19 +232,23 @@ func runOneShot(ctx context.Context, cancel context.CancelFunc) {
 		accountbindings.Module,
 		runtimetopology.Module,
 		agent.Module,
+		plugins.Module,
 
-		fx.Invoke(func(lc fx.Lifecycle, log *logger.Logger, ag *agent.Agent, sm *session.Manager) {
+		fx.Invoke(func(lc fx.Lifecycle, cfg *config.Config, log *logger.Logger, ag *agent.Agent, sm *session.Manager) {
 			lc.Append(fx.Hook{
 				OnStart: func(context.Context) error {
 					go func() {
 						defer cancel()
 
+						fmt.Printf("%s\n\n", interactiveBanner(ctx, cfg))
+
 						// Get or create session
 						sess, err := sm.GetWithSource(sessionID, session.SourceCLI)
 						if err != nil {
 							log.Error("Failed to get session", zap.Error(err))
 							os.Exit(1)
 						}
+						applyPersonaFlag(sess)
 
 						// Process message
 						response, err := ag.Chat(ctx, sess, message)
@@ -222,8 +280,6 @@ func runOneShot(ctx context.Context, cancel context.CancelFunc) {
 }
 
 func runInteractive(ctx context.Context, cancel context.CancelFunc) {
-	fmt.Printf("%s Interactive mode (Ctrl+C to exit)\n\n", logo)
-
 	app := fx.New(
 		config.Module,
 		logger.Module,
@@ -238,6 +294,8 @@ func runInteractive(ctx context.Context, cancel context.CancelFunc) {
 		state.Module,
 		process.Module,
 		watch.Module,
+		approval.Module,
+		toolsessions.Module,
 		prompts.Module,
 		providerstore.Module,
 		permissionrules.Module,
@@ -246,8 +304,9 @@ func runInteractive(ctx context.Context, cancel context.CancelFunc) {
 		accountbindings.Module,
 		runtimetopology.Module,
 		agent.Module,
+		plugins.Module,
 
-		fx.Invoke(func(lc fx.Lifecycle, log *logger.Logger, ag *agent.Agent, sm *session.Manager) {
+		fx.Invoke(func(lc fx.Lifecycle, log *logger.Logger, ag *agent.Agent, sm *session.Manager, cfg *config.Config) {
 			lc.Append(fx.Hook{
 				OnStart: func(context.Context) error {
 					go func() {
@@ -259,6 +318,16 @@ func runInteractive(ctx context.Context, cancel context.CancelFunc) {
 							log.Error("Failed to get session", zap.Error(err))
 							os.Exit(1)
 						}
+						applyPersonaFlag(sess)
+
+						// The TUI needs a terminal on both ends; pipes and
+						// --plain get the line-based loop.
+						if !plainMode && term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd())) {
+							if err := runTUIProgram(ctx, ag, sm, sess, session.SourceCLI, cfg); err != nil {
+								log.Error("TUI terminated with error", zap.Error(err))
+							}
+							return
+						}
 
 						// Run interactive loop
 						if err := interactiveLoop(ctx, ag, sess); err != nil {
@@ -285,6 +354,27 @@ func runInteractive(ctx context.Context, cancel context.CancelFunc) {
 	}
 }
 
+// applyPersonaFlag stores --persona on the session when the flag was given,
+// so an explicit empty value clears a previously set persona.
+func applyPersonaFlag(sess *session.Session) {
+	if personaSet {
+		sess.SetPersona(persona)
+	}
+}
+
+// interactiveBanner renders the interactive-mode header, including the
+// configured MOTD and update notice when enabled.
+func interactiveBanner(ctx context.Context, cfg *config.Config) string {
+	header := fmt.Sprintf("%s Interactive mode (Ctrl+C to exit)", logo)
+	if cfg == nil {
+		return header
+	}
+	motdCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	notice := motd.NewChecker(nil).Notice(motdCtx, cfg.MOTD, version.GetVersion())
+	return motd.Banner(notice, header)
+}
+
 func interactiveLoop(ctx context.Context, ag *agent.Agent, sess *session.Session) error {
 	prompt := fmt.Sprintf("%s You: ", logo)
 
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"go.uber.org/fx"
+
+	"nekobot/pkg/accountbindings"
+	"nekobot/pkg/agent"
+	"nekobot/pkg/approval"
+	"nekobot/pkg/audit"
+	"nekobot/pkg/bus"
+	"nekobot/pkg/channelaccounts"
+	"nekobot/pkg/commands"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/permissionrules"
+	"nekobot/pkg/plugins"
+	"nekobot/pkg/process"
+	"nekobot/pkg/prompts"
+	"nekobot/pkg/providers"
+	"nekobot/pkg/providerstore"
+	"nekobot/pkg/runtimeagents"
+	"nekobot/pkg/runtimetopology"
+	"nekobot/pkg/session"
+	"nekobot/pkg/skills"
+	"nekobot/pkg/state"
+	"nekobot/pkg/tools"
+	"nekobot/pkg/toolsessions"
+	"nekobot/pkg/watch"
+	"nekobot/pkg/workspace"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// pipeResult is the --output json result of one agent turn.
+type pipeResult struct {
+	Response  string         `json:"response"`
+	Error     string         `json:"error,omitempty"`
+	SessionID string         `json:"session_id"`
+	Provider  string         `json:"provider,omitempty"`
+	Model     string         `json:"model,omitempty"`
+	ToolCalls []pipeToolCall `json:"tool_calls"`
+	Usage     pipeUsage      `json:"usage"`
+}
+
+type pipeToolCall struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+type pipeUsage struct {
+	LLMCalls         int `json:"llm_calls"`
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// pipeMode reports whether the agent runs for scripts: the prompt comes
+// from stdin or the result is printed as JSON.
+func pipeMode() bool {
+	return agentStdin || outputFormat == outputJSON
+}
+
+// readPipeMessage appends the text read from r to the -m message, so
+// `git diff | nekobot agent --stdin -m "review this"` sends both.
+func readPipeMessage(r io.Reader, message string) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("read stdin: %w", err)
+	}
+	parts := make([]string, 0, 2)
+	for _, part := range []string{message, string(data)} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// toolCallCollector records the tool calls of a turn from its progress
+// reports.
+type toolCallCollector struct {
+	mu    sync.Mutex
+	calls []pipeToolCall
+}
+
+func (c *toolCallCollector) observe(progress agent.ToolProgress) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.calls {
+		if c.calls[i].ID == progress.ID {
+			c.calls[i].Error = progress.Error
+			return
+		}
+	}
+	c.calls = append(c.calls, pipeToolCall{ID: progress.ID, Name: progress.Name, Error: progress.Error})
+}
+
+func (c *toolCallCollector) snapshot() []pipeToolCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]pipeToolCall{}, c.calls...)
+}
+
+// writePipeResult prints result in the requested format. Text output goes
+// to out on success and to errOut on failure.
+func writePipeResult(out, errOut io.Writer, format string, result pipeResult) error {
+	if format == outputJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+	if result.Error != "" {
+		_, err := fmt.Fprintf(errOut, "Error: %s\n", result.Error)
+		return err
+	}
+	_, err := fmt.Fprintln(out, result.Response)
+	return err
+}
+
+// runPipe answers message once and prints the result to out. It returns
+// the process exit code: 0 on success, 1 when the turn failed.
+func runPipe(ctx context.Context, cancel context.CancelFunc, out io.Writer) int {
+	codes := make(chan int, 1)
+	app := fx.New(
+		config.Module,
+		logger.Module,
+		bus.Module,
+		audit.Module,
+		session.Module,
+		providers.Module,
+		tools.Module,
+		commands.Module,
+		workspace.Module,
+		skills.Module,
+		state.Module,
+		process.Module,
+		watch.Module,
+		approval.Module,
+		toolsessions.Module,
+		prompts.Module,
+		providerstore.Module,
+		permissionrules.Module,
+		runtimeagents.Module,
+		channelaccounts.Module,
+		accountbindings.Module,
+		runtimetopology.Module,
+		agent.Module,
+		plugins.Module,
+
+		fx.Invoke(func(lc fx.Lifecycle, ag *agent.Agent, sm *session.Manager) {
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					go func() {
+						defer cancel()
+
+						code := 1
+						defer func() { codes <- code }()
+
+						result := pipeResult{SessionID: sessionID, ToolCalls: []pipeToolCall{}}
+						sess, err := sm.GetWithSource(sessionID, session.SourceCLI)
+						if err == nil {
+							applyPersonaFlag(sess)
+							var calls toolCallCollector
+							var route agent.ChatRouteResult
+							result.Response, route, err = ag.ChatWithPromptContextDetailed(ctx, sess, message, agent.PromptContext{
+								SessionID:         sessionID,
+								RequestedProvider: agentProv,
+								RequestedModel:    agentModel,
+								ToolProgress:      calls.observe,
+							})
+							result.Provider = route.ActualProvider
+							result.Model = route.ActualModel
+							result.ToolCalls = calls.snapshot()
+							result.Usage = pipeUsage(route.Usage)
+						}
+						if err != nil {
+							result.Error = err.Error()
+						} else {
+							code = 0
+						}
+						if err := writePipeResult(out, os.Stderr, outputFormat, result); err != nil {
+							fmt.Fprintf(os.Stderr, "Error writing result: %v\n", err)
+							code = 1
+						}
+					}()
+					return nil
+				},
+			})
+		}),
+		fx.NopLogger, // Suppress fx logs
+	)
+
+	if err := app.Start(ctx); err != nil {
+		_ = writePipeResult(out, os.Stderr, outputFormat, pipeResult{
+			SessionID: sessionID,
+			Error:     fmt.Sprintf("starting agent: %v", err),
+			ToolCalls: []pipeToolCall{},
+		})
+		return 1
+	}
+
+	<-ctx.Done()
+
+	// An interrupted turn never reports a code and exits as a failure.
+	exitCode := 1
+	select {
+	case exitCode = <-codes:
+	default:
+	}
+	if err := app.Stop(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error stopping agent: %v\n", err)
+	}
+	return exitCode
+}
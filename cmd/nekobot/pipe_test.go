@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/agent"
+)
+
+func TestReadPipeMessageAppendsStdinToMessage(t *testing.T) {
+	got, err := readPipeMessage(strings.NewReader("diff --git a/x b/x\n"), " Review this diff ")
+	if err != nil {
+		t.Fatalf("readPipeMessage failed: %v", err)
+	}
+	if got != "Review this diff\n\ndiff --git a/x b/x" {
+		t.Fatalf("unexpected message %q", got)
+	}
+
+	if got, _ := readPipeMessage(strings.NewReader("  \n"), ""); got != "" {
+		t.Fatalf("expected an empty message, got %q", got)
+	}
+}
+
+func TestWritePipeResultFormats(t *testing.T) {
+	var calls toolCallCollector
+	calls.observe(agent.ToolProgress{ID: "call-1", Name: "exec"})
+	calls.observe(agent.ToolProgress{ID: "call-1", Name: "exec", Done: true, Error: "exit status 1"})
+	calls.observe(agent.ToolProgress{ID: "call-2", Name: "read_file"})
+	result := pipeResult{
+		Response:  "done",
+		SessionID: "cli:default",
+		Provider:  "openai",
+		Model:     "gpt-4o",
+		ToolCalls: calls.snapshot(),
+		Usage:     pipeUsage{LLMCalls: 2, PromptTokens: 30, CompletionTokens: 8, TotalTokens: 38},
+	}
+
+	var out, errOut bytes.Buffer
+	if err := writePipeResult(&out, &errOut, outputJSON, result); err != nil {
+		t.Fatalf("writePipeResult failed: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected JSON output: %v\n%s", err, out.String())
+	}
+	if decoded["response"] != "done" || decoded["provider"] != "openai" || decoded["model"] != "gpt-4o" {
+		t.Fatalf("unexpected result %v", decoded)
+	}
+	if _, ok := decoded["error"]; ok {
+		t.Fatalf("expected no error field on success, got %v", decoded)
+	}
+	toolCalls, _ := decoded["tool_calls"].([]interface{})
+	if len(toolCalls) != 2 || toolCalls[0].(map[string]interface{})["error"] != "exit status 1" {
+		t.Fatalf("unexpected tool calls %v", decoded["tool_calls"])
+	}
+	if usage, _ := decoded["usage"].(map[string]interface{}); usage["total_tokens"] != float64(38) {
+		t.Fatalf("unexpected usage %v", decoded["usage"])
+	}
+
+	out.Reset()
+	if err := writePipeResult(&out, &errOut, outputText, result); err != nil {
+		t.Fatalf("writePipeResult failed: %v", err)
+	}
+	if out.String() != "done\n" || errOut.Len() != 0 {
+		t.Fatalf("expected only the response on stdout, got %q / %q", out.String(), errOut.String())
+	}
+
+	out.Reset()
+	result.Error = "no providers configured"
+	if err := writePipeResult(&out, &errOut, outputText, result); err != nil {
+		t.Fatalf("writePipeResult failed: %v", err)
+	}
+	if out.Len() != 0 || errOut.String() != "Error: no providers configured\n" {
+		t.Fatalf("expected the error on stderr, got %q / %q", out.String(), errOut.String())
+	}
+}
+
+func TestAgentCommandRegistersPipeFlags(t *testing.T) {
+	for _, name := range []string{"stdin", "output", "plain"} {
+		if agentCmd.Flags().Lookup(name) == nil {
+			t.Fatalf("expected --%s flag on the agent command", name)
+		}
+	}
+}
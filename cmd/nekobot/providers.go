@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	providersAPIURL   string
+	providersAPIToken string
+)
+
+var providersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "Inspect provider runtime state on a running instance",
+	Long: `Inspect and recover provider failover state on a running nekobot instance.
+
+These commands call the WebUI admin API. Authenticate with a WebUI JWT via
+--token or the NEKOBOT_API_TOKEN environment variable. The API address
+defaults to the local WebUI port derived from the config file.
+
+Examples:
+  nekobot providers status --token $TOKEN
+  nekobot providers reset-cooldown openai-primary --token $TOKEN`,
+}
+
+var providersStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show provider availability and failover cooldown state",
+	Args:  cobra.NoArgs,
+	RunE:  runProvidersStatus,
+}
+
+var providersResetCooldownCmd = &cobra.Command{
+	Use:   "reset-cooldown <name>",
+	Short: "Clear the failover cooldown for one provider",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runProvidersResetCooldown,
+}
+
+func init() {
+	providersCmd.PersistentFlags().StringVar(&providersAPIURL, "url", "", "WebUI base URL of the running instance (default: local WebUI port)")
+	providersCmd.PersistentFlags().StringVar(&providersAPIToken, "token", "", "WebUI bearer token (default: $"+adminTokenEnv+")")
+
+	providersCmd.AddCommand(providersStatusCmd)
+	providersCmd.AddCommand(providersResetCooldownCmd)
+	rootCmd.AddCommand(providersCmd)
+}
+
+func runProvidersStatus(cmd *cobra.Command, args []string) error {
+	client := newAdminAPIClient(providersAPIURL, providersAPIToken)
+	ctx, cancel := context.WithTimeout(commandContext(cmd), 20*time.Second)
+	defer cancel()
+
+	items, err := client.ProviderRuntime(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch provider status: %w", err)
+	}
+	if len(items) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No providers configured.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tAVAILABLE\tCOOLDOWN\tERRORS\tDISABLED")
+	for _, item := range items {
+		cooldown := "-"
+		if item.InCooldown {
+			cooldown = (time.Duration(item.CooldownRemainingSeconds) * time.Second).String()
+		}
+		disabled := item.DisabledReason
+		if disabled == "" {
+			disabled = "-"
+		}
+		fmt.Fprintf(w, "%s\t%t\t%s\t%d\t%s\n", item.Name, item.Available, cooldown, item.ErrorCount, disabled)
+	}
+	return w.Flush()
+}
+
+func runProvidersResetCooldown(cmd *cobra.Command, args []string) error {
+	client := newAdminAPIClient(providersAPIURL, providersAPIToken)
+	ctx, cancel := context.WithTimeout(commandContext(cmd), 20*time.Second)
+	defer cancel()
+
+	if err := client.ClearProviderCooldown(ctx, args[0]); err != nil {
+		return fmt.Errorf("reset cooldown for %s: %w", args[0], err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Cooldown cleared: %s\n", args[0])
+	return nil
+}
+
+func commandContext(cmd *cobra.Command) context.Context {
+	if cmd != nil && cmd.Context() != nil {
+		return cmd.Context()
+	}
+	return context.Background()
+}
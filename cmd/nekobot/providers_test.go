@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestProvidersCommandsRegistered(t *testing.T) {
+	for _, path := range [][]string{{"providers", "status"}, {"providers", "reset-cooldown", "openai"}} {
+		cmd, _, err := rootCmd.Find(path)
+		if err != nil {
+			t.Fatalf("find command %v: %v", path, err)
+		}
+		if cmd.Parent() != providersCmd {
+			t.Fatalf("expected %v under providers command, got parent %q", path, cmd.Parent().Name())
+		}
+	}
+	if providersCmd.PersistentFlags().Lookup("token") == nil {
+		t.Fatal("expected --token flag on providers command")
+	}
+	if providersCmd.PersistentFlags().Lookup("url") == nil {
+		t.Fatal("expected --url flag on providers command")
+	}
+}
+
+func TestProvidersResetCooldownRequiresExactlyOneArg(t *testing.T) {
+	if err := providersResetCooldownCmd.Args(providersResetCooldownCmd, []string{}); err == nil {
+		t.Fatal("expected args validation error for empty args")
+	}
+	if err := providersResetCooldownCmd.Args(providersResetCooldownCmd, []string{"a", "b"}); err == nil {
+		t.Fatal("expected args validation error for extra args")
+	}
+}
+
+func newProvidersTestServer(t *testing.T, cleared *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"missing or malformed jwt"}`))
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/providers/runtime":
+			_, _ = w.Write([]byte(`[
+				{"name":"primary","available":false,"in_cooldown":true,"error_count":3,"cooldown_remaining_seconds":90},
+				{"name":"backup","available":true,"in_cooldown":false,"error_count":0}
+			]`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/clear-cooldown"):
+			name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/providers/"), "/clear-cooldown")
+			*cleared = append(*cleared, name)
+			_, _ = w.Write([]byte(`{"status":"cleared"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAdminAPIClientProviderRuntime(t *testing.T) {
+	var cleared []string
+	server := newProvidersTestServer(t, &cleared)
+
+	items, err := newAdminAPIClient(server.URL, "secret").ProviderRuntime(context.Background())
+	if err != nil {
+		t.Fatalf("ProviderRuntime failed: %v", err)
+	}
+	if len(items) != 2 || items[0].Name != "primary" || !items[0].InCooldown || items[0].CooldownRemainingSeconds != 90 {
+		t.Fatalf("unexpected runtime items: %+v", items)
+	}
+}
+
+func TestAdminAPIClientUsesTokenFromEnv(t *testing.T) {
+	var cleared []string
+	server := newProvidersTestServer(t, &cleared)
+	t.Setenv(adminTokenEnv, "secret")
+
+	if err := newAdminAPIClient(server.URL, "").ClearProviderCooldown(context.Background(), "primary"); err != nil {
+		t.Fatalf("ClearProviderCooldown failed: %v", err)
+	}
+	if len(cleared) != 1 || cleared[0] != "primary" {
+		t.Fatalf("expected primary cooldown cleared, got %v", cleared)
+	}
+}
+
+func TestAdminAPIClientReportsUnauthorized(t *testing.T) {
+	var cleared []string
+	server := newProvidersTestServer(t, &cleared)
+	t.Setenv(adminTokenEnv, "")
+
+	_, err := newAdminAPIClient(server.URL, "wrong").ProviderRuntime(context.Background())
+	var apiErr *adminAPIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized admin API error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "--token") {
+		t.Fatalf("expected token hint in error, got %v", err)
+	}
+}
+
+func TestRunProvidersStatusAndResetCooldown(t *testing.T) {
+	var cleared []string
+	server := newProvidersTestServer(t, &cleared)
+	oldURL, oldToken := providersAPIURL, providersAPIToken
+	providersAPIURL, providersAPIToken = server.URL, "secret"
+	t.Cleanup(func() { providersAPIURL, providersAPIToken = oldURL, oldToken })
+
+	var stdout bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&stdout)
+	if err := runProvidersStatus(cmd, nil); err != nil {
+		t.Fatalf("runProvidersStatus failed: %v", err)
+	}
+	out := stdout.String()
+	for _, fragment := range []string{"NAME", "primary", "1m30s", "backup"} {
+		if !strings.Contains(out, fragment) {
+			t.Fatalf("expected status output to contain %q, got:\n%s", fragment, out)
+		}
+	}
+
+	stdout.Reset()
+	if err := runProvidersResetCooldown(cmd, []string{"primary"}); err != nil {
+		t.Fatalf("runProvidersResetCooldown failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Cooldown cleared: primary") {
+		t.Fatalf("unexpected reset output: %s", stdout.String())
+	}
+	if len(cleared) != 1 || cleared[0] != "primary" {
+		t.Fatalf("expected cooldown reset call, got %v", cleared)
+	}
+}
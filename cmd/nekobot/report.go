@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"nekobot/pkg/usage"
+)
+
+var (
+	reportAPIURL   string
+	reportAPIToken string
+	reportFrom     string
+	reportTo       string
+	reportGroupBy  string
+	reportFormat   string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show reports from a running instance",
+	Long: `Show operational reports from a running nekobot instance.
+
+These commands call the WebUI admin API. Authenticate with a WebUI JWT via
+--token or the NEKOBOT_API_TOKEN environment variable.`,
+}
+
+var reportUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show token usage and cost by provider, user or model",
+	Long: `Aggregate recorded token usage and estimated cost over a time range.
+
+Bounds accept RFC3339 timestamps or YYYY-MM-DD dates; a date --to includes the
+whole day. Without --from the report covers the 30 days before --to.
+
+Examples:
+  nekobot report usage --token $TOKEN
+  nekobot report usage --from 2026-01-01 --to 2026-01-31 --group-by model
+  nekobot report usage --group-by user --format csv > usage.csv`,
+	Args: cobra.NoArgs,
+	RunE: runReportUsage,
+}
+
+func init() {
+	reportCmd.PersistentFlags().StringVar(&reportAPIURL, "url", "", "WebUI base URL of the running instance (default: local WebUI port)")
+	reportCmd.PersistentFlags().StringVar(&reportAPIToken, "token", "", "WebUI bearer token (default: $"+adminTokenEnv+")")
+
+	reportUsageCmd.Flags().StringVar(&reportFrom, "from", "", "Start of the range (RFC3339 or YYYY-MM-DD)")
+	reportUsageCmd.Flags().StringVar(&reportTo, "to", "", "End of the range (RFC3339 or YYYY-MM-DD, default: now)")
+	reportUsageCmd.Flags().StringVar(&reportGroupBy, "group-by", usage.GroupByProvider, "Group rows by provider, user, model, channel, session, day or week")
+	reportUsageCmd.Flags().StringVar(&reportFormat, "format", "table", "Output format: table or csv")
+
+	reportCmd.AddCommand(reportUsageCmd)
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReportUsage(cmd *cobra.Command, args []string) error {
+	format := strings.TrimSpace(strings.ToLower(reportFormat))
+	if format != "table" && format != "csv" {
+		return fmt.Errorf("unsupported format %q: use table or csv", reportFormat)
+	}
+	if _, err := usage.NormalizeGroupBy(reportGroupBy); err != nil {
+		return err
+	}
+
+	client := newAdminAPIClient(reportAPIURL, reportAPIToken)
+	ctx, cancel := context.WithTimeout(commandContext(cmd), 30*time.Second)
+	defer cancel()
+
+	report, err := client.UsageReport(ctx, reportFrom, reportTo, reportGroupBy)
+	if err != nil {
+		return fmt.Errorf("fetch usage report: %w", err)
+	}
+	if format == "csv" {
+		return usage.WriteCSV(cmd.OutOrStdout(), report)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Usage from %s to %s\n\n", report.From.Format(time.RFC3339), report.To.Format(time.RFC3339))
+	if len(report.Rows) == 0 {
+		fmt.Fprintln(out, "No usage recorded in this range.")
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\tREQUESTS\tPROMPT\tCOMPLETION\tTOTAL\tCOST\n", strings.ToUpper(report.GroupBy))
+	for _, row := range append(report.Rows, report.Total) {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t$%.4f\n",
+			row.Key, row.Requests, row.PromptTokens, row.CompletionTokens, row.TotalTokens, row.Cost)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestReportUsageCommandRegistered(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"report", "usage"})
+	if err != nil {
+		t.Fatalf("find report usage command: %v", err)
+	}
+	if cmd != reportUsageCmd {
+		t.Fatalf("expected report usage command, got %q", cmd.Name())
+	}
+	for _, name := range []string{"from", "to", "group-by", "format"} {
+		if reportUsageCmd.Flags().Lookup(name) == nil {
+			t.Fatalf("expected --%s flag on report usage", name)
+		}
+	}
+}
+
+func TestRunReportUsage(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/reports/usage" || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		gotQuery = r.URL.RawQuery
+		_, _ = w.Write([]byte(`{
+			"from":"2026-03-01T00:00:00Z","to":"2026-03-02T00:00:00Z","group_by":"model",
+			"rows":[{"key":"gpt-5","requests":2,"prompt_tokens":100,"completion_tokens":50,"total_tokens":150,"cost":0.012}],
+			"total":{"key":"total","requests":2,"prompt_tokens":100,"completion_tokens":50,"total_tokens":150,"cost":0.012}
+		}`))
+	}))
+	t.Cleanup(server.Close)
+
+	oldURL, oldToken := reportAPIURL, reportAPIToken
+	oldFrom, oldTo, oldGroupBy, oldFormat := reportFrom, reportTo, reportGroupBy, reportFormat
+	t.Cleanup(func() {
+		reportAPIURL, reportAPIToken = oldURL, oldToken
+		reportFrom, reportTo, reportGroupBy, reportFormat = oldFrom, oldTo, oldGroupBy, oldFormat
+	})
+	reportAPIURL, reportAPIToken = server.URL, "secret"
+	reportFrom, reportTo, reportGroupBy, reportFormat = "2026-03-01", "2026-03-01", "model", "table"
+
+	var stdout bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&stdout)
+	if err := runReportUsage(cmd, nil); err != nil {
+		t.Fatalf("runReportUsage failed: %v", err)
+	}
+	if gotQuery != "from=2026-03-01&groupBy=model&to=2026-03-01" {
+		t.Fatalf("unexpected query: %q", gotQuery)
+	}
+	for _, fragment := range []string{"MODEL", "gpt-5", "150", "$0.0120", "total"} {
+		if !strings.Contains(stdout.String(), fragment) {
+			t.Fatalf("expected table output to contain %q, got:\n%s", fragment, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	reportFormat = "csv"
+	if err := runReportUsage(cmd, nil); err != nil {
+		t.Fatalf("runReportUsage csv failed: %v", err)
+	}
+	if !strings.HasPrefix(stdout.String(), "model,requests,") || !strings.Contains(stdout.String(), "gpt-5,2,100,50,150,0,0,0.012000") {
+		t.Fatalf("unexpected csv output:\n%s", stdout.String())
+	}
+
+	reportFormat = "xml"
+	if err := runReportUsage(cmd, nil); err == nil {
+		t.Fatalf("expected unsupported format to fail")
+	}
+}
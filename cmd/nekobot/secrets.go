@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"nekobot/pkg/channelaccounts"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/providerstore"
+	"nekobot/pkg/secrets"
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage encryption of stored secrets",
+	Long: `Encrypt provider API keys, channel tokens, channel account credentials and
+proxy credentials at rest.
+
+The master key is read from $` + secrets.KeyEnv + `, or else from
+storage.master_key_file or the output of storage.master_key_command.
+
+Examples:
+  # Create a master key and keep it somewhere safe
+  nekobot secrets generate-key
+
+  # Encrypt secrets that were stored before the key was configured
+  NEKOBOT_MASTER_KEY=... nekobot secrets encrypt`,
+}
+
+var secretsGenerateKeyCmd = &cobra.Command{
+	Use:   "generate-key",
+	Short: "Print a new random master key",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := secrets.GenerateKey()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), key)
+		return nil
+	},
+}
+
+var secretsEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt secrets already stored in the runtime database",
+	Args:  cobra.NoArgs,
+	RunE:  runSecretsEncrypt,
+}
+
+func init() {
+	secretsCmd.AddCommand(secretsGenerateKeyCmd)
+	secretsCmd.AddCommand(secretsEncryptCmd)
+	rootCmd.AddCommand(secretsCmd)
+}
+
+func runSecretsEncrypt(cmd *cobra.Command, args []string) error {
+	cfg, err := config.NewLoader().Load("")
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	box, err := cfg.SecretBox()
+	if err != nil {
+		return fmt.Errorf("load master key: %w", err)
+	}
+	if box == nil {
+		return fmt.Errorf("no master key configured; set %s or storage.master_key_file", secrets.KeyEnv)
+	}
+
+	// Loading decrypts whatever is already encrypted, so saving every
+	// section again leaves all of them encrypted.
+	if err := config.ApplyDatabaseOverrides(cfg); err != nil {
+		return fmt.Errorf("load runtime config: %w", err)
+	}
+	if err := config.SaveDatabaseSections(cfg); err != nil {
+		return fmt.Errorf("encrypt runtime config: %w", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "Runtime config sections encrypted.")
+
+	log, err := logger.New(&logger.Config{Level: logger.LevelWarn})
+	if err != nil {
+		return fmt.Errorf("create logger: %w", err)
+	}
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		return fmt.Errorf("open runtime database: %w", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+	if err := config.PrepareRuntimeEntSchema(cfg, client); err != nil {
+		return fmt.Errorf("ensure runtime schema: %w", err)
+	}
+	providers, err := providerstore.NewManager(cfg, log, client)
+	if err != nil {
+		return fmt.Errorf("open provider store: %w", err)
+	}
+	changed, err := providers.EncryptSecrets(commandContext(cmd))
+	if err != nil {
+		return fmt.Errorf("encrypt providers: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Providers encrypted: %d\n", changed)
+
+	accounts, err := channelaccounts.NewManager(cfg, log, client)
+	if err != nil {
+		return fmt.Errorf("open channel account store: %w", err)
+	}
+	changed, err = accounts.EncryptSecrets(commandContext(cmd))
+	if err != nil {
+		return fmt.Errorf("encrypt channel accounts: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Channel accounts encrypted: %d\n", changed)
+	return nil
+}
@@ -16,20 +16,26 @@ import (
 
 	"nekobot/pkg/accountbindings"
 	"nekobot/pkg/agent"
+	"nekobot/pkg/alerts"
 	"nekobot/pkg/approval"
 	"nekobot/pkg/audit"
+	"nekobot/pkg/backup"
 	"nekobot/pkg/bus"
 	"nekobot/pkg/channelaccounts"
 	"nekobot/pkg/channels"
 	"nekobot/pkg/commands"
 	"nekobot/pkg/config"
 	"nekobot/pkg/cron"
+	"nekobot/pkg/feeds"
 	"nekobot/pkg/gateway"
 	"nekobot/pkg/goaldriven"
 	"nekobot/pkg/heartbeat"
 	"nekobot/pkg/inboundrouter"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/mcp"
+	"nekobot/pkg/notify"
 	"nekobot/pkg/permissionrules"
+	"nekobot/pkg/plugins"
 	"nekobot/pkg/process"
 	"nekobot/pkg/prompts"
 	"nekobot/pkg/providerstore"
@@ -40,6 +46,7 @@ import (
 	"nekobot/pkg/skills"
 	"nekobot/pkg/state"
 	"nekobot/pkg/toolsessions"
+	"nekobot/pkg/transcript"
 	"nekobot/pkg/userprefs"
 	"nekobot/pkg/watch"
 	"nekobot/pkg/webui"
@@ -101,6 +108,7 @@ func (s *GatewayService) run() {
 		state.Module,
 		userprefs.Module,
 		session.Module,
+		transcript.Module,
 		approval.Module,
 		audit.Module,
 		skills.Module,
@@ -116,15 +124,22 @@ func (s *GatewayService) run() {
 		runtimetopology.Module,
 		inboundrouter.Module,
 		agent.Module,
+		plugins.Module,
 
 		// Gateway modules
 		bus.Module,
+		alerts.Module,
 		channels.Module,
 		heartbeat.Module,
 		cron.Module,
+		feeds.Module,
+		notify.Module,
+		backup.Module,
+		mcp.Module,
 		gateway.Module,
 		goaldriven.Module,
 		webui.Module,
+		fx.Supply(bus.RoleGateway),
 
 		fx.Invoke(func(lc fx.Lifecycle, log *logger.Logger, b bus.Bus, cm *channels.Manager) {
 			lc.Append(fx.Hook{
@@ -293,6 +308,7 @@ func runGatewayForeground() {
 		state.Module,
 		userprefs.Module,
 		session.Module,
+		transcript.Module,
 		approval.Module,
 		audit.Module,
 		skills.Module,
@@ -308,15 +324,22 @@ func runGatewayForeground() {
 		runtimetopology.Module,
 		inboundrouter.Module,
 		agent.Module,
+		plugins.Module,
 
 		// Gateway modules
 		bus.Module,
+		alerts.Module,
 		channels.Module,
 		heartbeat.Module,
 		cron.Module,
+		feeds.Module,
+		notify.Module,
+		backup.Module,
+		mcp.Module,
 		gateway.Module,
 		goaldriven.Module,
 		webui.Module,
+		fx.Supply(bus.RoleGateway),
 
 		fx.Invoke(func(lc fx.Lifecycle, log *logger.Logger, b bus.Bus, cm *channels.Manager, cfg *config.Config) {
 			lc.Append(fx.Hook{
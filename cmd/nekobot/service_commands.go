@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"nekobot/pkg/servicecontrol"
+)
+
+var (
+	serviceUser       bool
+	serviceLogDir     string
+	serviceRestartSec int
+)
+
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Manage the gateway+WebUI daemon as a system service",
+	Long: `Install and control the nekobot daemon (gateway and WebUI) with the
+platform service manager: a systemd unit on Linux or a launchd plist on macOS.
+
+The generated unit appends output to log files and restarts the daemon when it
+crashes. System services need root; pass --user to install a per-user service
+(systemctl --user or a LaunchAgent) instead.
+
+Examples:
+  sudo nekobot service install
+  nekobot service install --user --log-dir ~/.nekobot/logs
+  nekobot service status --user
+  sudo nekobot service stop`,
+}
+
+var serviceInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Generate and install the service unit",
+	Args:  cobra.NoArgs,
+	RunE:  runServiceInstall,
+}
+
+var serviceUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop and remove the service unit",
+	Args:  cobra.NoArgs,
+	RunE:  serviceControlRunE("uninstall", "Service uninstalled."),
+}
+
+var serviceStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the service",
+	Args:  cobra.NoArgs,
+	RunE:  serviceControlRunE("start", "Service started."),
+}
+
+var serviceStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the service",
+	Args:  cobra.NoArgs,
+	RunE:  serviceControlRunE("stop", "Service stopped."),
+}
+
+var serviceRestartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restart the service",
+	Args:  cobra.NoArgs,
+	RunE:  serviceControlRunE("restart", "Service restarted."),
+}
+
+var serviceStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the service is installed and running",
+	Args:  cobra.NoArgs,
+	RunE:  runServiceStatus,
+}
+
+func init() {
+	serviceCmd.PersistentFlags().BoolVar(&serviceUser, "user", false, "Manage a per-user service instead of a system-wide one")
+	serviceCmd.PersistentFlags().StringVar(&serviceLogDir, "log-dir", "", "Directory for the daemon log files (default: /var/log/nekobot, or ~/.nekobot/logs with --user)")
+	serviceInstallCmd.Flags().IntVar(&serviceRestartSec, "restart-sec", servicecontrol.DefaultRestartSec, "Seconds to wait before restarting a crashed daemon")
+
+	serviceCmd.AddCommand(serviceInstallCmd)
+	serviceCmd.AddCommand(serviceUninstallCmd)
+	serviceCmd.AddCommand(serviceStartCmd)
+	serviceCmd.AddCommand(serviceStopCmd)
+	serviceCmd.AddCommand(serviceRestartCmd)
+	serviceCmd.AddCommand(serviceStatusCmd)
+	rootCmd.AddCommand(serviceCmd)
+}
+
+func serviceDaemonOptions() servicecontrol.DaemonOptions {
+	return servicecontrol.DaemonOptions{
+		LogDir:      serviceLogDir,
+		UserService: serviceUser,
+		RestartSec:  serviceRestartSec,
+	}
+}
+
+func runServiceInstall(cmd *cobra.Command, args []string) error {
+	opts := serviceDaemonOptions()
+	if err := servicecontrol.InstallGatewayDaemon(configPath, opts); err != nil {
+		return serviceError(err)
+	}
+	outPath, errPath := opts.LogPaths()
+	out := cmd.OutOrStdout()
+	fmt.Fprintln(out, "Service installed.")
+	fmt.Fprintf(out, "Logs: %s, %s\n", outPath, errPath)
+	fmt.Fprintf(out, "Start it with: %s\n", serviceCommandHint("start"))
+	return nil
+}
+
+func serviceControlRunE(action, done string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		spec := servicecontrol.GatewayDaemonSpec(serviceDaemonOptions())
+		if err := servicecontrol.ControlService(configPath, spec, action); err != nil {
+			return serviceError(err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), done)
+		return nil
+	}
+}
+
+func runServiceStatus(cmd *cobra.Command, args []string) error {
+	opts := serviceDaemonOptions()
+	status, err := servicecontrol.InspectService(configPath, servicecontrol.GatewayDaemonSpec(opts))
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Service:  %s (%s)\n", status.Name, status.Platform)
+	fmt.Fprintf(out, "Status:   %s\n", status.Status)
+	if !status.Installed {
+		fmt.Fprintf(out, "Install it with: %s\n", serviceCommandHint("install"))
+		return nil
+	}
+	if status.ConfigPath != "" {
+		fmt.Fprintf(out, "Config:   %s\n", status.ConfigPath)
+	}
+	outPath, errPath := opts.LogPaths()
+	fmt.Fprintf(out, "Logs:     %s, %s\n", outPath, errPath)
+	return nil
+}
+
+func serviceCommandHint(action string) string {
+	if serviceUser {
+		return "nekobot service " + action + " --user"
+	}
+	return "sudo nekobot service " + action
+}
+
+func serviceError(err error) error {
+	if serviceUser {
+		return err
+	}
+	return fmt.Errorf("%w (system services need root; use sudo or pass --user)", err)
+}
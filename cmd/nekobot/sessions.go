@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/session"
+	"nekobot/pkg/transcript"
+)
+
+var (
+	sessionsExportSince  string
+	sessionsExportFormat string
+	sessionsExportDir    string
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Manage persisted conversation sessions",
+}
+
+var sessionsExportCmd = &cobra.Command{
+	Use:   "export [session-id...]",
+	Short: "Export conversation transcripts as JSONL or Markdown",
+	Long: `Export persisted sessions with their messages, tool calls, tool results,
+token usage and providers used.
+
+Transcripts go to sessions.export.s3 when a bucket is configured, otherwise
+to sessions.export.dir (default: <workspace>/exports/transcripts). Exports
+older than sessions.export.retention_days are removed from the directory.
+
+--since accepts a duration such as 7d, 12h or 2w, an RFC3339 timestamp or a
+YYYY-MM-DD date. Without session IDs or --since, every session is exported.
+
+Examples:
+  nekobot sessions export --since 7d
+  nekobot sessions export --since 2026-01-01 --format markdown
+  nekobot sessions export telegram:123456 --dir ./transcripts`,
+	RunE: runSessionsExport,
+}
+
+func init() {
+	sessionsExportCmd.Flags().StringVar(&sessionsExportSince, "since", "", "Only export sessions updated since this time (e.g. 7d, 2026-01-01)")
+	sessionsExportCmd.Flags().StringVar(&sessionsExportFormat, "format", "", "Output format: jsonl or markdown (default: sessions.export.format)")
+	sessionsExportCmd.Flags().StringVar(&sessionsExportDir, "dir", "", "Write to this directory instead of the configured destination")
+
+	sessionsCmd.AddCommand(sessionsExportCmd)
+	rootCmd.AddCommand(sessionsCmd)
+}
+
+func runSessionsExport(cmd *cobra.Command, args []string) error {
+	if _, err := transcript.NormalizeFormat(sessionsExportFormat); err != nil {
+		return err
+	}
+	since, err := parseSince(sessionsExportSince, time.Now())
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.NewLoader().Load("")
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if err := config.ApplyDatabaseOverrides(cfg); err != nil {
+		return fmt.Errorf("load runtime config: %w", err)
+	}
+	if dir := strings.TrimSpace(sessionsExportDir); dir != "" {
+		cfg.Sessions.Export.Dir = dir
+		cfg.Sessions.Export.S3 = config.SessionExportS3Config{}
+	}
+
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		return fmt.Errorf("open runtime database: %w", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+	if err := config.PrepareRuntimeEntSchema(cfg, client); err != nil {
+		return fmt.Errorf("ensure runtime schema: %w", err)
+	}
+	usageSource, err := transcript.NewUsageSource(cfg, client)
+	if err != nil {
+		return err
+	}
+
+	sessions := session.NewManager(filepath.Join(cfg.WorkspacePath(), "sessions"), cfg.Sessions)
+	exporter, err := transcript.NewExporter(cfg, sessions, usageSource)
+	if err != nil {
+		return err
+	}
+
+	ctx := commandContext(cmd)
+	var locations []string
+	if len(args) > 0 {
+		for _, id := range args {
+			sess, err := sessions.LoadJSONL(id)
+			if err != nil {
+				return fmt.Errorf("load session %q: %w", id, err)
+			}
+			location, err := exporter.Export(ctx, sess, sessionsExportFormat)
+			if err != nil {
+				return fmt.Errorf("export session %q: %w", id, err)
+			}
+			locations = append(locations, location)
+		}
+	} else {
+		locations, err = exporter.ExportSince(ctx, since, sessionsExportFormat)
+		if err != nil {
+			return err
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	for _, location := range locations {
+		fmt.Fprintln(out, location)
+	}
+	fmt.Fprintf(out, "Exported %d session(s).\n", len(locations))
+
+	removed, err := exporter.Prune()
+	if err != nil {
+		return fmt.Errorf("prune old exports: %w", err)
+	}
+	if removed > 0 {
+		fmt.Fprintf(out, "Removed %d export(s) past retention.\n", removed)
+	}
+	return nil
+}
+
+// parseSince parses a lookback such as 7d, 12h or 2w, an RFC3339 timestamp or
+// a YYYY-MM-DD date. An empty value means the beginning of time.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if count, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(count)
+			if err != nil || n < 0 {
+				break
+			}
+			return now.Add(-time.Duration(n) * unit), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a duration like 7d or 12h, RFC3339 or YYYY-MM-DD", value)
+}
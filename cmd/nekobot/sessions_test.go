@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{value: "", want: time.Time{}},
+		{value: "7d", want: now.Add(-7 * 24 * time.Hour)},
+		{value: "2w", want: now.Add(-14 * 24 * time.Hour)},
+		{value: "12h", want: now.Add(-12 * time.Hour)},
+		{value: "2026-05-01T08:00:00Z", want: time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)},
+		{value: "2026-05-01", want: time.Date(2026, 5, 1, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.value, now)
+		if err != nil {
+			t.Fatalf("parseSince(%q): %v", tt.value, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Fatalf("parseSince(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"soon", "-3d", "xd"} {
+		if _, err := parseSince(value, now); err == nil {
+			t.Fatalf("expected error for %q", value)
+		}
+	}
+}
+
+func TestSessionsCommand_RegistersExportSubcommand(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"sessions", "export"})
+	if err != nil {
+		t.Fatalf("find sessions export command: %v", err)
+	}
+	if cmd != sessionsExportCmd {
+		t.Fatalf("expected sessions export command, got %q", cmd.Name())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"time"
 
@@ -46,12 +47,38 @@ var skillsSearchCmd = &cobra.Command{
 }
 
 var skillsInstallCmd = &cobra.Command{
-	Use:   "install [url|path]",
-	Short: "Install a skill from URL or local path",
+	Use:   "install [url|path|index-id]",
+	Short: "Install a skill from URL, local path or the skills index",
 	Args:  cobra.ExactArgs(1),
 	Run:   runSkillsInstall,
 }
 
+var skillsUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [skill-id...]",
+	Short: "Upgrade skills installed from the skills index",
+	Long:  `Upgrade the named skills, or all skills installed from the skills index, to their latest index version. Pinned skills are skipped.`,
+	Run:   runSkillsUpgrade,
+}
+
+var skillsPinCmd = &cobra.Command{
+	Use:   "pin [skill-id] [version]",
+	Short: "Hold an index skill at its installed or a given version",
+	Args:  cobra.RangeArgs(1, 2),
+	Run:   runSkillsPin,
+}
+
+var skillsUnpinCmd = &cobra.Command{
+	Use:   "unpin [skill-id]",
+	Short: "Let upgrades move a pinned skill again",
+	Args:  cobra.ExactArgs(1),
+	Run:   runSkillsUnpin,
+}
+
+var (
+	skillsInstallVersion string
+	skillsUpgradeCheck   bool
+)
+
 var skillsInstallDepsCmd = &cobra.Command{
 	Use:   "install-deps [skill-id]",
 	Short: "Install dependencies for a skill",
@@ -66,6 +93,11 @@ func init() {
 	skillsCmd.AddCommand(skillsSearchCmd)
 	skillsCmd.AddCommand(skillsInstallCmd)
 	skillsCmd.AddCommand(skillsInstallDepsCmd)
+	skillsCmd.AddCommand(skillsUpgradeCmd)
+	skillsCmd.AddCommand(skillsPinCmd)
+	skillsCmd.AddCommand(skillsUnpinCmd)
+	skillsInstallCmd.Flags().StringVar(&skillsInstallVersion, "version", "", "Version to install from the skills index (default latest)")
+	skillsUpgradeCmd.Flags().BoolVar(&skillsUpgradeCheck, "check", false, "Only list available updates")
 	rootCmd.AddCommand(skillsCmd)
 }
 
@@ -207,6 +239,20 @@ func runSkillsSearch(cmd *cobra.Command, args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if manager.HasIndex() {
+		index, err := manager.FetchIndex(ctx)
+		if err != nil {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nSkills index unavailable: %v\n", err)
+		} else if matches := index.Search(query); len(matches) == 0 {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\nSkills index matches: none")
+		} else {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nSkills index matches: %d\n", len(matches))
+			for _, entry := range matches {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "- %s %s (%s) %s\n", entry.ID, entry.Latest().Version, entry.Repo, entry.Description)
+			}
+		}
+	}
+
 	remoteOutput, err := manager.SearchRegistry(ctx, query)
 	if err != nil {
 		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nRemote registry unavailable: %v\n", err)
@@ -229,6 +275,20 @@ func runSkillsInstall(cmd *cobra.Command, args []string) {
 	}
 
 	source := strings.TrimSpace(args[0])
+	if skillsInstallVersion != "" || isIndexSkillRef(source) {
+		installed, err := manager.InstallIndexed(context.Background(), source, skillsInstallVersion)
+		if err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Failed to install skill: %v\n", err)
+			return
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Installed %s %s from %s (ref %s)\n", installed.ID, installed.Version, installed.Repo, installed.Ref)
+		if requested := installed.Permissions.Requested(); len(requested) > 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Permissions: %s\n", strings.Join(requested, ", "))
+		} else {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Permissions: read-only tools only")
+		}
+		return
+	}
 	targetPath, err := manager.InstallSkill(context.Background(), source)
 	if err != nil {
 		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Failed to install skill: %v\n", err)
@@ -239,6 +299,109 @@ func runSkillsInstall(cmd *cobra.Command, args []string) {
 	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Target: %s\n", targetPath)
 }
 
+// isIndexSkillRef reports whether an install source names an index skill
+// rather than a URL or an existing path.
+func isIndexSkillRef(source string) bool {
+	if strings.Contains(source, "://") || strings.ContainsAny(source, `/\`) {
+		return false
+	}
+	_, err := os.Stat(source)
+	return os.IsNotExist(err)
+}
+
+func runSkillsUpgrade(cmd *cobra.Command, args []string) {
+	manager, err := loadSkillsManager()
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Failed to load skills manager: %v\n", err)
+		return
+	}
+	ctx := context.Background()
+	out := cmd.OutOrStdout()
+
+	updates, err := manager.CheckUpdates(ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Failed to check for updates: %v\n", err)
+		return
+	}
+	if len(args) > 0 {
+		wanted := make(map[string]bool, len(args))
+		for _, id := range args {
+			wanted[strings.TrimSpace(id)] = true
+		}
+		filtered := updates[:0]
+		for _, update := range updates {
+			if wanted[update.ID] {
+				filtered = append(filtered, update)
+			}
+		}
+		updates = filtered
+	}
+	if len(updates) == 0 {
+		_, _ = fmt.Fprintln(out, "All index skills are up to date.")
+		return
+	}
+
+	for _, update := range updates {
+		if skillsUpgradeCheck || update.Pinned {
+			note := ""
+			if update.Pinned {
+				note = " (pinned)"
+			}
+			_, _ = fmt.Fprintf(out, "%s %s -> %s%s\n", update.ID, update.Current, update.Latest, note)
+			writeChangelog(out, update.Changelog)
+			continue
+		}
+		installed, upgraded, err := manager.UpgradeSkill(ctx, update.ID)
+		switch {
+		case err != nil:
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Failed to upgrade %s: %v\n", update.ID, err)
+		case upgraded:
+			_, _ = fmt.Fprintf(out, "Upgraded %s %s -> %s\n", update.ID, update.Current, installed.Version)
+			writeChangelog(out, update.Changelog)
+		}
+	}
+}
+
+func writeChangelog(w io.Writer, changelog string) {
+	for _, line := range strings.Split(strings.TrimSpace(changelog), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			_, _ = fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+}
+
+func runSkillsPin(cmd *cobra.Command, args []string) {
+	manager, err := loadSkillsManager()
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Failed to load skills manager: %v\n", err)
+		return
+	}
+	version := ""
+	if len(args) > 1 {
+		version = args[1]
+	}
+	installed, err := manager.PinSkill(context.Background(), args[0], version)
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Failed to pin skill: %v\n", err)
+		return
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Pinned %s at %s\n", installed.ID, installed.Version)
+}
+
+func runSkillsUnpin(cmd *cobra.Command, args []string) {
+	manager, err := loadSkillsManager()
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Failed to load skills manager: %v\n", err)
+		return
+	}
+	installed, err := manager.UnpinSkill(args[0])
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Failed to unpin skill: %v\n", err)
+		return
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Unpinned %s (installed %s)\n", installed.ID, installed.Version)
+}
+
 func loadSkillsManager() (*skills.Manager, error) {
 	log, err := logger.New(&logger.Config{
 		Level:       logger.LevelError,
@@ -17,6 +17,9 @@ func TestSkillsCommand_RegistersValidateAndInstallDeps(t *testing.T) {
 		{"skills", "install-deps", "demo-skill"},
 		{"skills", "search", "git"},
 		{"skills", "install", "https://example.com/skills/repo.git"},
+		{"skills", "upgrade"},
+		{"skills", "pin", "demo-skill"},
+		{"skills", "unpin", "demo-skill"},
 	} {
 		cmd, _, err := rootCmd.Find(path)
 		if err != nil {
@@ -37,6 +40,32 @@ func TestSkillsSearchCommand_RequiresAtLeastOneArg(t *testing.T) {
 	}
 }
 
+func TestSkillsPinCommand_AcceptsOptionalVersion(t *testing.T) {
+	if err := skillsPinCmd.Args(skillsPinCmd, nil); err == nil {
+		t.Fatal("expected args validation error for empty args")
+	}
+	if err := skillsPinCmd.Args(skillsPinCmd, []string{"demo-skill", "v1.0.0"}); err != nil {
+		t.Fatalf("expected valid args, got error: %v", err)
+	}
+	if err := skillsPinCmd.Args(skillsPinCmd, []string{"a", "b", "c"}); err == nil {
+		t.Fatal("expected args validation error for extra args")
+	}
+}
+
+func TestIsIndexSkillRef(t *testing.T) {
+	dir := t.TempDir()
+	for source, want := range map[string]bool{
+		"pdf-tools":                           true,
+		"owner/repo":                          false,
+		"https://example.com/skills/repo.git": false,
+		dir:                                   false,
+	} {
+		if got := isIndexSkillRef(source); got != want {
+			t.Fatalf("isIndexSkillRef(%q) = %v, want %v", source, got, want)
+		}
+	}
+}
+
 func TestSkillsValidateCommand_RequiresExactlyOneArg(t *testing.T) {
 	if err := skillsValidateCmd.Args(skillsValidateCmd, nil); err == nil {
 		t.Fatal("expected args validation error for empty args")
@@ -2,14 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"go.uber.org/fx"
@@ -17,6 +21,7 @@ import (
 
 	"nekobot/pkg/accountbindings"
 	"nekobot/pkg/agent"
+	"nekobot/pkg/approval"
 	"nekobot/pkg/audit"
 	"nekobot/pkg/bus"
 	"nekobot/pkg/channelaccounts"
@@ -24,6 +29,7 @@ import (
 	"nekobot/pkg/config"
 	"nekobot/pkg/logger"
 	"nekobot/pkg/permissionrules"
+	"nekobot/pkg/plugins"
 	"nekobot/pkg/process"
 	"nekobot/pkg/prompts"
 	"nekobot/pkg/providers"
@@ -34,17 +40,34 @@ import (
 	"nekobot/pkg/skills"
 	"nekobot/pkg/state"
 	"nekobot/pkg/tools"
+	"nekobot/pkg/toolsessions"
 	"nekobot/pkg/watch"
 	"nekobot/pkg/workspace"
 )
 
+const (
+	// tuiTurnTimeout bounds one agent turn started from the TUI.
+	tuiTurnTimeout = 5 * time.Minute
+	// tuiSessionPrefix selects the sessions offered by the session switcher.
+	tuiSessionPrefix = "cli:"
+	// tuiNewSession is the switcher entry that starts a fresh session.
+	tuiNewSession = "+ new session"
+)
+
 var tuiSessionID string
 
 var tuiCmd = &cobra.Command{
 	Use:   "tui",
-	Short: "Start a minimal terminal UI chat",
-	Long:  "Start a minimal Bubble Tea based chat interface for the nekobot agent.",
-	Run:   runTUI,
+	Short: "Start a terminal UI chat",
+	Long: `Start a Bubble Tea based chat interface for the nekobot agent.
+
+Keys:
+  Enter        send the message
+  Esc          cancel the reply in progress
+  PgUp/PgDn    scroll the conversation
+  Ctrl+S       switch sessions
+  Ctrl+C       quit`,
+	Run: runTUI,
 }
 
 func init() {
@@ -52,74 +75,151 @@ func init() {
 	rootCmd.AddCommand(tuiCmd)
 }
 
-type aiResponseMsg struct {
+// tuiResponseMsg ends a turn.
+type tuiResponseMsg struct {
+	turn int
 	text string
 	err  error
 }
 
+// tuiStreamMsg carries the reply text of a turn so far.
+type tuiStreamMsg struct {
+	turn int
+	text string
+}
+
+// tuiToolMsg reports a tool call of a turn starting or finishing.
+type tuiToolMsg struct {
+	turn     int
+	progress agent.ToolProgress
+}
+
+// tuiEntry is one block of the scrollback panel.
+type tuiEntry struct {
+	speaker string
+	text    string
+}
+
 type tuiModel struct {
 	ctx      context.Context
 	agent    *agent.Agent
+	sessions *session.Manager
+	source   string
 	session  *session.Session
 	input    textinput.Model
-	messages []string
-	waiting  bool
+	viewport viewport.Model
+	entries  []tuiEntry
 	provider string
 	model    string
+
+	// events delivers stream and tool progress updates from the running
+	// turn; updates of older turns are ignored.
+	events    chan tea.Msg
+	turn      int
+	waiting   bool
+	cancel    context.CancelFunc
+	streaming string
+	tools     []agent.ToolProgress
+
+	switching   bool
+	sessionKeys []string
+	cursor      int
+	notice      string
 }
 
-func newTUIModel(ctx context.Context, ag *agent.Agent, sess *session.Session, provider, model string) tuiModel {
+func newTUIModel(
+	ctx context.Context,
+	ag *agent.Agent,
+	sm *session.Manager,
+	sess *session.Session,
+	source, provider, model string,
+) tuiModel {
 	input := textinput.New()
 	input.Placeholder = "Type a message and press Enter"
 	input.Focus()
 	input.Prompt = "> "
 
-	return tuiModel{
+	m := tuiModel{
 		ctx:      ctx,
 		agent:    ag,
+		sessions: sm,
+		source:   source,
 		session:  sess,
 		input:    input,
-		messages: []string{"Welcome to nekobot TUI. Press Ctrl+C to exit."},
+		viewport: viewport.New(80, 20),
 		provider: provider,
 		model:    model,
+		events:   make(chan tea.Msg, 64),
 	}
+	m.loadHistory()
+	m.refresh()
+	return m
 }
 
 func (m tuiModel) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, m.waitForEvent())
+}
+
+// waitForEvent delivers the next update of the running turn.
+func (m tuiModel) waitForEvent() tea.Cmd {
+	events := m.events
+	return func() tea.Msg {
+		return <-events
+	}
 }
 
 func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.viewport.Width = msg.Width
+		// Header, status line and input take four lines.
+		m.viewport.Height = max(msg.Height-4, 3)
+		m.input.Width = max(msg.Width-4, 10)
+		m.refresh()
+		return m, nil
 	case tea.KeyMsg:
+		if m.switching {
+			return m.updateSwitcher(msg)
+		}
 		switch msg.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case tea.KeyCtrlC:
+			m.cancelTurn()
 			return m, tea.Quit
-		case tea.KeyEnter:
+		case tea.KeyEsc:
 			if m.waiting {
-				return m, nil
-			}
-			prompt := strings.TrimSpace(m.input.Value())
-			if prompt == "" {
-				return m, nil
+				m.cancelTurn()
+				m.notice = "Cancelling…"
 			}
-			m.messages = append(m.messages, "You: "+prompt)
-			m.input.SetValue("")
-			m.waiting = true
-			return m, func() tea.Msg {
-				ctx, cancel := context.WithTimeout(m.ctx, 5*time.Minute)
-				defer cancel()
-				resp, err := m.agent.Chat(ctx, m.session, prompt)
-				return aiResponseMsg{text: resp, err: err}
+			return m, nil
+		case tea.KeyCtrlS:
+			if !m.waiting {
+				m.openSwitcher()
 			}
+			return m, nil
+		case tea.KeyPgUp, tea.KeyPgDown:
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		case tea.KeyEnter:
+			return m.send()
+		}
+	case tuiStreamMsg:
+		if msg.turn == m.turn && m.waiting {
+			m.streaming = msg.text
+			m.refresh()
+		}
+		return m, m.waitForEvent()
+	case tuiToolMsg:
+		if msg.turn == m.turn && m.waiting {
+			m.trackTool(msg.progress)
 		}
-	case aiResponseMsg:
-		m.waiting = false
-		if msg.err != nil {
-			m.messages = append(m.messages, "Bot: ❌ "+msg.err.Error())
-		} else {
-			m.messages = append(m.messages, "Bot: "+msg.text)
+		return m, m.waitForEvent()
+	case tuiResponseMsg:
+		if msg.turn != m.turn {
+			return m, nil
 		}
+		m.finishTurn(msg)
+		return m, nil
 	}
 
 	var cmd tea.Cmd
@@ -127,30 +227,270 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m tuiModel) View() string {
-	var b strings.Builder
-	_, _ = fmt.Fprintf(&b, "Nekobot TUI | Provider: %s | Model: %s\n", m.provider, m.model)
-	b.WriteString(strings.Repeat("-", 80))
-	b.WriteString("\n")
+// send starts a turn with the typed message.
+func (m tuiModel) send() (tea.Model, tea.Cmd) {
+	prompt := strings.TrimSpace(m.input.Value())
+	if m.waiting || prompt == "" {
+		return m, nil
+	}
+	m.input.SetValue("")
+	m.entries = append(m.entries, tuiEntry{speaker: "You", text: prompt})
+	m.turn++
+	m.waiting = true
+	m.streaming = ""
+	m.tools = nil
+	m.notice = ""
+	m.refresh()
+	m.viewport.GotoBottom()
+
+	turn := m.turn
+	ctx, cancel := context.WithTimeout(m.ctx, tuiTurnTimeout)
+	m.cancel = cancel
+	ag, sess, events := m.agent, m.session, m.events
+	return m, func() tea.Msg {
+		defer cancel()
+		resp, _, err := ag.ChatWithPromptContextDetailed(ctx, sess, prompt, agent.PromptContext{
+			SessionID: sess.GetID(),
+			Stream: func(text string) {
+				// Each update carries the full text, so a dropped one is
+				// replaced by the next.
+				select {
+				case events <- tuiStreamMsg{turn: turn, text: text}:
+				default:
+				}
+			},
+			ToolProgress: func(progress agent.ToolProgress) {
+				select {
+				case events <- tuiToolMsg{turn: turn, progress: progress}:
+				case <-ctx.Done():
+				}
+			},
+		})
+		if err == nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return tuiResponseMsg{turn: turn, text: resp, err: err}
+	}
+}
+
+func (m *tuiModel) cancelTurn() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+}
+
+func (m *tuiModel) finishTurn(msg tuiResponseMsg) {
+	m.waiting = false
+	m.cancel = nil
+	m.notice = ""
+	switch {
+	case errors.Is(msg.err, context.Canceled):
+		m.entries = append(m.entries, tuiEntry{speaker: "Bot", text: "⏹ Cancelled"})
+	case msg.err != nil:
+		m.entries = append(m.entries, tuiEntry{speaker: "Bot", text: "❌ " + msg.err.Error()})
+	default:
+		m.entries = append(m.entries, tuiEntry{speaker: "Bot", text: msg.text})
+	}
+	m.streaming = ""
+	m.tools = nil
+	m.refresh()
+	m.viewport.GotoBottom()
+}
+
+// trackTool records a tool call starting or finishing in the current turn.
+func (m *tuiModel) trackTool(progress agent.ToolProgress) {
+	for i := range m.tools {
+		if m.tools[i].ID == progress.ID {
+			m.tools[i] = progress
+			return
+		}
+	}
+	m.tools = append(m.tools, progress)
+}
+
+func (m *tuiModel) openSwitcher() {
+	keys, err := m.sessions.ListKeys()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		m.notice = "❌ Failed to list sessions: " + err.Error()
+		return
+	}
+	current := m.session.GetID()
+	m.sessionKeys = []string{tuiNewSession}
+	seen := map[string]bool{}
+	for _, key := range append(keys, current) {
+		if strings.HasPrefix(key, tuiSessionPrefix) && !seen[key] {
+			seen[key] = true
+			m.sessionKeys = append(m.sessionKeys, key)
+		}
+	}
+	sort.Strings(m.sessionKeys[1:])
+	m.cursor = 0
+	for i, key := range m.sessionKeys {
+		if key == current {
+			m.cursor = i
+		}
+	}
+	m.switching = true
+}
 
-	start := 0
-	if len(m.messages) > 20 {
-		start = len(m.messages) - 20
+func (m tuiModel) updateSwitcher(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc, tea.KeyCtrlS:
+		m.switching = false
+	case tea.KeyUp:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case tea.KeyDown:
+		if m.cursor < len(m.sessionKeys)-1 {
+			m.cursor++
+		}
+	case tea.KeyEnter:
+		m.switching = false
+		key := m.sessionKeys[m.cursor]
+		if key == tuiNewSession {
+			key = tuiSessionPrefix + time.Now().Format("20060102-150405")
+		}
+		sess, err := m.sessions.GetWithSource(key, m.source)
+		if err != nil {
+			m.notice = "❌ Failed to open session: " + err.Error()
+			return m, nil
+		}
+		m.session = sess
+		m.loadHistory()
+		m.refresh()
+		m.viewport.GotoBottom()
 	}
-	for _, line := range m.messages[start:] {
-		b.WriteString(line)
+	return m, nil
+}
+
+// loadHistory fills the scrollback with the session's conversation.
+func (m *tuiModel) loadHistory() {
+	m.entries = []tuiEntry{{text: fmt.Sprintf("Session %s. Esc cancels a reply, Ctrl+S switches sessions, Ctrl+C exits.", m.session.GetID())}}
+	for _, msg := range m.session.GetMessages() {
+		if strings.TrimSpace(msg.Content) == "" {
+			continue
+		}
+		switch msg.Role {
+		case "user":
+			m.entries = append(m.entries, tuiEntry{speaker: "You", text: msg.Content})
+		case "assistant":
+			m.entries = append(m.entries, tuiEntry{speaker: "Bot", text: msg.Content})
+		}
+	}
+}
+
+// refresh re-renders the scrollback, following new output only when the
+// user has not scrolled up.
+func (m *tuiModel) refresh() {
+	follow := m.viewport.AtBottom()
+	width := m.viewport.Width
+	var b strings.Builder
+	for _, entry := range m.entries {
+		b.WriteString(renderTUIEntry(entry, width))
 		b.WriteString("\n")
 	}
+	if m.waiting && m.streaming != "" {
+		b.WriteString(renderTUIEntry(tuiEntry{speaker: "Bot", text: m.streaming + " ▍"}, width))
+		b.WriteString("\n")
+	}
+	m.viewport.SetContent(b.String())
+	if follow {
+		m.viewport.GotoBottom()
+	}
+}
+
+func renderTUIEntry(entry tuiEntry, width int) string {
+	text := entry.text
+	if entry.speaker != "" {
+		text = entry.speaker + ": " + text
+	}
+	return wrapTUIText(text, width)
+}
 
-	if m.waiting {
-		b.WriteString("\nThinking...\n")
+// wrapTUIText hard-wraps lines longer than width runes.
+func wrapTUIText(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	wrapped := make([]string, 0, len(lines))
+	for _, line := range lines {
+		runes := []rune(line)
+		for len(runes) > width {
+			wrapped = append(wrapped, string(runes[:width]))
+			runes = runes[width:]
+		}
+		wrapped = append(wrapped, string(runes))
 	}
+	return strings.Join(wrapped, "\n")
+}
+
+// statusLine shows the tools of the running turn, or a notice.
+func (m tuiModel) statusLine() string {
+	if !m.waiting {
+		return m.notice
+	}
+	parts := make([]string, 0, len(m.tools)+1)
+	for _, tool := range m.tools {
+		switch {
+		case !tool.Done:
+			parts = append(parts, "⏳ "+tool.Name)
+		case tool.Error != "":
+			parts = append(parts, "✗ "+tool.Name)
+		default:
+			parts = append(parts, "✓ "+tool.Name)
+		}
+	}
+	status := "Thinking… (Esc to cancel)"
+	if m.notice != "" {
+		status = m.notice
+	}
+	return strings.Join(append(parts, status), "  ")
+}
+
+func (m tuiModel) View() string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "Nekobot TUI | Session: %s | Provider: %s | Model: %s\n", m.session.GetID(), m.provider, m.model)
+	if m.switching {
+		b.WriteString("Switch session (↑/↓, Enter to open, Esc to close)\n\n")
+		for i, key := range m.sessionKeys {
+			cursor := "  "
+			if i == m.cursor {
+				cursor = "> "
+			}
+			b.WriteString(cursor + key + "\n")
+		}
+		return b.String()
+	}
+	b.WriteString(m.viewport.View())
+	b.WriteString("\n")
+	b.WriteString(m.statusLine())
 	b.WriteString("\n")
 	b.WriteString(m.input.View())
 	b.WriteString("\n")
 	return b.String()
 }
 
+// runTUIProgram runs the chat UI for sess until the user quits.
+func runTUIProgram(
+	ctx context.Context,
+	ag *agent.Agent,
+	sm *session.Manager,
+	sess *session.Session,
+	source string,
+	cfg *config.Config,
+) error {
+	model := newTUIModel(ctx, ag, sm, sess, source, cfg.Agents.Defaults.Provider, cfg.Agents.Defaults.Model)
+	_, err := tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+	if errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
 func runTUI(cmd *cobra.Command, args []string) {
 	cfgPath, created, err := config.InitDefaultConfig()
 	if err != nil {
@@ -184,6 +524,8 @@ func runTUI(cmd *cobra.Command, args []string) {
 		state.Module,
 		process.Module,
 		watch.Module,
+		approval.Module,
+		toolsessions.Module,
 		prompts.Module,
 		providerstore.Module,
 		permissionrules.Module,
@@ -192,6 +534,7 @@ func runTUI(cmd *cobra.Command, args []string) {
 		accountbindings.Module,
 		runtimetopology.Module,
 		agent.Module,
+		plugins.Module,
 
 		fx.Invoke(func(lc fx.Lifecycle, log *logger.Logger, ag *agent.Agent, sm *session.Manager, cfg *config.Config) {
 			lc.Append(fx.Hook{
@@ -205,8 +548,7 @@ func runTUI(cmd *cobra.Command, args []string) {
 							return
 						}
 
-						model := newTUIModel(ctx, ag, sess, cfg.Agents.Defaults.Provider, cfg.Agents.Defaults.Model)
-						if _, err := tea.NewProgram(model).Run(); err != nil {
+						if err := runTUIProgram(ctx, ag, sm, sess, session.SourceTUI, cfg); err != nil {
 							log.Error("TUI terminated with error", zap.Error(err))
 						}
 					}()
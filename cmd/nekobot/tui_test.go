@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"nekobot/pkg/agent"
+	"nekobot/pkg/config"
+	"nekobot/pkg/session"
+)
+
+func newTestTUIModel(t *testing.T) (tuiModel, *session.Manager) {
+	t.Helper()
+	cfg := config.DefaultConfig().Sessions
+	cfg.Sources = config.SessionSourcesConfig{TUI: true}
+	sm := session.NewManager(t.TempDir(), cfg)
+	sess, err := sm.GetWithSource("cli:tui", session.SourceTUI)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+	return newTUIModel(context.Background(), nil, sm, sess, session.SourceTUI, "openai", "gpt-4o"), sm
+}
+
+func updateTUI(t *testing.T, m tuiModel, msg tea.Msg) tuiModel {
+	t.Helper()
+	next, _ := m.Update(msg)
+	return next.(tuiModel)
+}
+
+func TestTUIShowsStreamingAndToolProgressOfCurrentTurn(t *testing.T) {
+	m, _ := newTestTUIModel(t)
+	m.turn, m.waiting = 2, true
+
+	m = updateTUI(t, m, tuiToolMsg{turn: 2, progress: agent.ToolProgress{ID: "1", Name: "read_file"}})
+	m = updateTUI(t, m, tuiToolMsg{turn: 2, progress: agent.ToolProgress{ID: "2", Name: "exec"}})
+	m = updateTUI(t, m, tuiToolMsg{turn: 2, progress: agent.ToolProgress{ID: "1", Name: "read_file", Done: true}})
+	m = updateTUI(t, m, tuiStreamMsg{turn: 2, text: "Hello wor"})
+	m = updateTUI(t, m, tuiStreamMsg{turn: 1, text: "stale"})
+
+	view := m.View()
+	if !strings.Contains(view, "✓ read_file  ⏳ exec  Thinking…") {
+		t.Fatalf("expected tool progress in the status line, got:\n%s", view)
+	}
+	if !strings.Contains(view, "Bot: Hello wor ▍") || strings.Contains(view, "stale") {
+		t.Fatalf("expected only the current turn's streamed text, got:\n%s", view)
+	}
+
+	m = updateTUI(t, m, tuiResponseMsg{turn: 2, text: "Hello world"})
+	view = m.View()
+	if m.waiting || !strings.Contains(view, "Bot: Hello world") || strings.Contains(view, "exec") {
+		t.Fatalf("expected the final reply without progress, got:\n%s", view)
+	}
+}
+
+func TestTUIEscCancelsTheRunningTurn(t *testing.T) {
+	m, _ := newTestTUIModel(t)
+	cancelled := false
+	m.turn, m.waiting = 1, true
+	m.cancel = func() { cancelled = true }
+
+	m = updateTUI(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if !cancelled {
+		t.Fatal("expected Esc to cancel the running turn")
+	}
+	m = updateTUI(t, m, tuiResponseMsg{turn: 1, err: context.Canceled})
+	if m.waiting || !strings.Contains(m.View(), "Bot: ⏹ Cancelled") {
+		t.Fatalf("expected a cancelled turn, got:\n%s", m.View())
+	}
+}
+
+func TestTUISessionSwitcherLoadsHistory(t *testing.T) {
+	m, sm := newTestTUIModel(t)
+	other, err := sm.GetWithSource("cli:notes", session.SourceTUI)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+	other.AddMessage(session.Message{Role: "user", Content: "remember the milk"})
+	other.AddMessage(session.Message{Role: "assistant", Content: "Noted."})
+	if _, err := sm.GetWithSource("telegram:1", session.SourceTUI); err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+
+	m = updateTUI(t, m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if !m.switching {
+		t.Fatal("expected Ctrl+S to open the switcher")
+	}
+	if got := strings.Join(m.sessionKeys, ","); got != tuiNewSession+",cli:notes,cli:tui" {
+		t.Fatalf("unexpected switcher entries: %s", got)
+	}
+	if m.sessionKeys[m.cursor] != "cli:tui" {
+		t.Fatalf("expected the cursor on the current session, got %q", m.sessionKeys[m.cursor])
+	}
+
+	m = updateTUI(t, m, tea.KeyMsg{Type: tea.KeyUp})
+	m = updateTUI(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.switching || m.session.GetID() != "cli:notes" {
+		t.Fatalf("expected to switch to cli:notes, got %q", m.session.GetID())
+	}
+	view := m.View()
+	if !strings.Contains(view, "You: remember the milk") || !strings.Contains(view, "Bot: Noted.") {
+		t.Fatalf("expected the session history, got:\n%s", view)
+	}
+}
+
+func TestWrapTUIText(t *testing.T) {
+	if got := wrapTUIText("abcdefg\nhi", 3); got != "abc\ndef\ng\nhi" {
+		t.Fatalf("unexpected wrap: %q", got)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/accountbindings"
+	"nekobot/pkg/agent"
+	"nekobot/pkg/approval"
+	"nekobot/pkg/audit"
+	"nekobot/pkg/bus"
+	"nekobot/pkg/channelaccounts"
+	"nekobot/pkg/commands"
+	"nekobot/pkg/config"
+	"nekobot/pkg/inboundrouter"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/mcp"
+	"nekobot/pkg/permissionrules"
+	"nekobot/pkg/plugins"
+	"nekobot/pkg/process"
+	"nekobot/pkg/prompts"
+	"nekobot/pkg/providerstore"
+	"nekobot/pkg/runtimeagents"
+	"nekobot/pkg/runtimetopology"
+	"nekobot/pkg/session"
+	"nekobot/pkg/skills"
+	"nekobot/pkg/state"
+	"nekobot/pkg/toolsessions"
+	"nekobot/pkg/transcript"
+	"nekobot/pkg/userprefs"
+	"nekobot/pkg/watch"
+	"nekobot/pkg/workspace"
+)
+
+// workerChannelSyncInterval is how often a worker picks up channel
+// accounts added or disabled through the gateway.
+const workerChannelSyncInterval = 30 * time.Second
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Process inbound channel messages from the Redis stream bus",
+	Long: `Run an agent worker for a horizontally scaled deployment.
+
+With bus.type set to "redis_streams", gateways receive channel messages and
+publish them to a Redis stream. Workers consume the stream through a consumer
+group, so each message is handled by exactly one worker, and publish replies
+back to the gateway instance that received the message.
+
+Workers run no channels, WebUI or schedulers. They must share the gateway's
+runtime database (PostgreSQL or MySQL) and workspace directory, or use an
+object-storage workspace backend. Start as many workers as needed; set
+bus.process_inbound to false to keep gateways from processing messages
+themselves.
+
+Examples:
+  nekobot worker
+  NEKOBOT_BUS_INSTANCE_ID=worker-2 nekobot worker`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runWorker,
+}
+
+func init() {
+	rootCmd.AddCommand(workerCmd)
+}
+
+func runWorker(cmd *cobra.Command, args []string) error {
+	app := fx.New(
+		config.Module,
+		logger.Module,
+		commands.Module,
+		workspace.Module,
+		state.Module,
+		userprefs.Module,
+		session.Module,
+		transcript.Module,
+		approval.Module,
+		audit.Module,
+		skills.Module,
+		process.Module,
+		watch.Module,
+		toolsessions.Module,
+		prompts.Module,
+		providerstore.Module,
+		permissionrules.Module,
+		runtimeagents.Module,
+		channelaccounts.Module,
+		accountbindings.Module,
+		runtimetopology.Module,
+		inboundrouter.Module,
+		agent.Module,
+		plugins.Module,
+		bus.Module,
+		mcp.Module,
+		fx.Supply(bus.RoleWorker),
+
+		fx.Invoke(registerWorker),
+		fx.NopLogger,
+	)
+	if err := app.Err(); err != nil {
+		return err
+	}
+	app.Run()
+	return nil
+}
+
+func registerWorker(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *logger.Logger,
+	sessions *session.Manager,
+	router *inboundrouter.Router,
+) error {
+	if bus.BusType(cfg.Bus.Type) != bus.BusTypeRedisStreams {
+		return fmt.Errorf("nekobot worker needs bus.type %q, got %q", bus.BusTypeRedisStreams, cfg.Bus.Type)
+	}
+	// Other workers append to the same session files.
+	sessions.SetShared(true)
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			log.Info("Worker started",
+				zap.Int("workers", cfg.Bus.Workers),
+				zap.Int("pid", os.Getpid()))
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ticker := time.NewTicker(workerChannelSyncInterval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-loopCtx.Done():
+						return
+					case <-ticker.C:
+					}
+					if _, err := router.SyncChannels(loopCtx); err != nil && loopCtx.Err() == nil {
+						log.Warn("Failed to sync worker channels", zap.Error(err))
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			wg.Wait()
+			log.Info("Worker stopped")
+			return nil
+		},
+	})
+	return nil
+}
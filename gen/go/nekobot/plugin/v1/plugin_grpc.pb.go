@@ -0,0 +1,165 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.1
+// - protoc             (unknown)
+// source: nekobot/plugin/v1/plugin.proto
+
+package pluginv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ToolPluginService_Describe_FullMethodName = "/nekobot.plugin.v1.ToolPluginService/Describe"
+	ToolPluginService_Execute_FullMethodName  = "/nekobot.plugin.v1.ToolPluginService/Execute"
+)
+
+// ToolPluginServiceClient is the client API for ToolPluginService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ToolPluginService is served by a plugin binary on the socket nekobot passes
+// in NEKOBOT_PLUGIN_SOCKET.
+type ToolPluginServiceClient interface {
+	Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error)
+	Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error)
+}
+
+type toolPluginServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewToolPluginServiceClient(cc grpc.ClientConnInterface) ToolPluginServiceClient {
+	return &toolPluginServiceClient{cc}
+}
+
+func (c *toolPluginServiceClient) Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DescribeResponse)
+	err := c.cc.Invoke(ctx, ToolPluginService_Describe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toolPluginServiceClient) Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecuteResponse)
+	err := c.cc.Invoke(ctx, ToolPluginService_Execute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ToolPluginServiceServer is the server API for ToolPluginService service.
+// All implementations must embed UnimplementedToolPluginServiceServer
+// for forward compatibility.
+//
+// ToolPluginService is served by a plugin binary on the socket nekobot passes
+// in NEKOBOT_PLUGIN_SOCKET.
+type ToolPluginServiceServer interface {
+	Describe(context.Context, *DescribeRequest) (*DescribeResponse, error)
+	Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error)
+	mustEmbedUnimplementedToolPluginServiceServer()
+}
+
+// UnimplementedToolPluginServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedToolPluginServiceServer struct{}
+
+func (UnimplementedToolPluginServiceServer) Describe(context.Context, *DescribeRequest) (*DescribeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Describe not implemented")
+}
+func (UnimplementedToolPluginServiceServer) Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedToolPluginServiceServer) mustEmbedUnimplementedToolPluginServiceServer() {}
+func (UnimplementedToolPluginServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeToolPluginServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ToolPluginServiceServer will
+// result in compilation errors.
+type UnsafeToolPluginServiceServer interface {
+	mustEmbedUnimplementedToolPluginServiceServer()
+}
+
+func RegisterToolPluginServiceServer(s grpc.ServiceRegistrar, srv ToolPluginServiceServer) {
+	// If the following call panics, it indicates UnimplementedToolPluginServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ToolPluginService_ServiceDesc, srv)
+}
+
+func _ToolPluginService_Describe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToolPluginServiceServer).Describe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ToolPluginService_Describe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToolPluginServiceServer).Describe(ctx, req.(*DescribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToolPluginService_Execute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToolPluginServiceServer).Execute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ToolPluginService_Execute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToolPluginServiceServer).Execute(ctx, req.(*ExecuteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ToolPluginService_ServiceDesc is the grpc.ServiceDesc for ToolPluginService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ToolPluginService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nekobot.plugin.v1.ToolPluginService",
+	HandlerType: (*ToolPluginServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Describe",
+			Handler:    _ToolPluginService_Describe_Handler,
+		},
+		{
+			MethodName: "Execute",
+			Handler:    _ToolPluginService_Execute_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "nekobot/plugin/v1/plugin.proto",
+}
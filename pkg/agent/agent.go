@@ -11,16 +11,22 @@ import (
 
 	"go.uber.org/zap"
 	"nekobot/pkg/approval"
+	"nekobot/pkg/background"
+	"nekobot/pkg/bus"
 	"nekobot/pkg/config"
+	"nekobot/pkg/feedback"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/mcp"
 	"nekobot/pkg/memory"
 	promptmemory "nekobot/pkg/memory/prompt"
 	"nekobot/pkg/modelroute"
+	"nekobot/pkg/moderation"
 	"nekobot/pkg/permissionrules"
 	"nekobot/pkg/preprocess"
 	"nekobot/pkg/process"
 	"nekobot/pkg/prompts"
 	"nekobot/pkg/providers"
+	"nekobot/pkg/sandbox"
 	"nekobot/pkg/session"
 	"nekobot/pkg/skills"
 	"nekobot/pkg/state"
@@ -29,6 +35,8 @@ import (
 	"nekobot/pkg/tasks"
 	"nekobot/pkg/tools"
 	"nekobot/pkg/toolsessions"
+	"nekobot/pkg/turnlimit"
+	"nekobot/pkg/usage"
 )
 
 const (
@@ -46,6 +54,26 @@ type safeHistorySession interface {
 	GetHistorySafe(int) []Message
 }
 
+type pinnedContextSession interface {
+	GetPins() []string
+}
+
+// personaSession is implemented by sessions that carry a custom system prompt.
+type personaSession interface {
+	GetPersona() string
+}
+
+// orchestratorSession is implemented by sessions that carry an orchestrator override.
+type orchestratorSession interface {
+	GetOrchestrator() string
+}
+
+// modelOverrideSession is implemented by sessions that carry provider, model
+// and fallback overrides.
+type modelOverrideSession interface {
+	GetModelOverride() (string, string, []string)
+}
+
 // Agent represents an AI agent that can interact with users and use tools.
 type Agent struct {
 	config   *config.Config
@@ -68,15 +96,30 @@ type Agent struct {
 	acpRuntime  map[string]string
 	kvStore     state.KV
 
+	toolUsage *toolUsageTracker
+
 	failoverMu       sync.Mutex
 	failoverCooldown *providers.CooldownTracker
 	providerGroups   *providerGroupPlanner
 
+	// configMu guards the fields ApplyConfig refreshes after a config
+	// reload: maxIterations, definition and moderation.
+	configMu      sync.RWMutex
 	maxIterations int
 	entClient     *ent.Client
 	taskStore     *tasks.Store
 	taskService   *tasks.Service
 	subagents     *subagent.SubagentManager
+	background    *background.Manager
+	sandbox       *sandbox.Pool
+	filePolicy    *tools.FilePolicy
+	processMgr    *process.Manager
+	moderation    *moderation.Filter
+	turnLimits    *turnlimit.Limiter
+	usage         *usage.Manager
+	feedback      *feedback.Manager
+	events        bus.Bus
+	mcp           *mcp.Manager
 }
 
 type subagentAgentAdapter struct {
@@ -96,6 +139,15 @@ type ChatRouteResult struct {
 	ContextBudgetReasons  []string
 	CompactionRecommended bool
 	CompactionStrategy    string
+	Orchestrator          string
+	// AgentProfile names the agent profile that handled the turn, if any.
+	AgentProfile string
+	// Debug is set when the session runs in developer mode.
+	Debug *TurnDebug
+	// Sources lists the pages web tools fetched or searched during the turn.
+	Sources []Source
+	// Usage totals the tokens spent on the turn's LLM calls.
+	Usage TurnUsage
 }
 
 func markPreflightApplied(routeResult ChatRouteResult) ChatRouteResult {
@@ -137,7 +189,18 @@ type PromptContext struct {
 	RequestedModel    string
 	RequestedFallback []string
 	ExplicitPromptIDs []string
-	Custom            map[string]any
+	// Orchestrator overrides agents.defaults.orchestrator for this turn.
+	Orchestrator string
+	Custom       map[string]any
+	// Stream, when set, receives the reply text while it is generated.
+	// It is not called when output moderation is on, since the text could
+	// still be replaced.
+	Stream StreamFunc
+	// ToolProgress, when set, is told when each tool call starts and ends.
+	ToolProgress ToolProgressFunc
+	// Attachments are files sent with the message. Images are passed to the
+	// model with the current turn; they are not kept in the session.
+	Attachments []bus.Attachment
 }
 
 // New creates a new agent with the given configuration.
@@ -160,6 +223,9 @@ func New(
 		if err := toolRegistry.Register(tool); err != nil {
 			return fmt.Errorf("register tool %s: %w", tool.Name(), err)
 		}
+		if marked, ok := tool.(tools.ApprovalRequired); ok && marked.RequiresApproval() && approvalMgr != nil {
+			approvalMgr.RequireApproval(tool.Name())
+		}
 		return nil
 	}
 
@@ -179,20 +245,60 @@ func New(
 	if err := registerTool(tools.NewListDirTool(workspace, cfg.Agents.Defaults.RestrictToWorkspace)); err != nil {
 		return nil, err
 	}
-	if err := registerTool(tools.NewExecTool(workspace, cfg.Agents.Defaults.RestrictToWorkspace, tools.ExecConfig{
-		Timeout: time.Duration(cfg.Tools.Exec.TimeoutSeconds) * time.Second,
-		Sandbox: tools.DockerSandboxConfig{
-			Enabled:     cfg.Tools.Exec.Sandbox.Enabled,
-			Image:       cfg.Tools.Exec.Sandbox.Image,
-			NetworkMode: cfg.Tools.Exec.Sandbox.NetworkMode,
-			Mounts:      cfg.Tools.Exec.Sandbox.Mounts,
-			Timeout:     time.Duration(cfg.Tools.Exec.Sandbox.Timeout) * time.Second,
-			AutoCleanup: cfg.Tools.Exec.Sandbox.AutoCleanup,
-		},
-	}, processMgr)); err != nil {
+	filePolicy := tools.NewFilePolicy(workspace, filePolicyOptions(cfg.Tools.Files))
+	toolRegistry.AddGuard(filePolicy)
+	sandboxCfg := sandboxConfigFromConfig(cfg)
+	// Per-session sandbox containers, swept with idle tool sessions
+	var sandboxPool *sandbox.Pool
+	if sandboxCfg.Enabled && cfg.Tools.Exec.Sandbox.PerSession {
+		pool, err := tools.NewSandboxPool(log, workspace, sandboxCfg)
+		if err != nil {
+			return nil, fmt.Errorf("create sandbox pool: %w", err)
+		}
+		sandboxPool = pool
+		if toolSessionMgr != nil {
+			toolSessionMgr.AddSweeper("sandbox", sandboxPool.Sweep)
+		}
+		log.Info("Per-session sandbox containers enabled",
+			zap.Int("pool_size", sandboxCfg.PoolSize),
+			zap.Int("max_containers", sandboxCfg.MaxContainers))
+	}
+	if err := registerTool(newExecToolFromConfig(cfg, sandboxPool, processMgr)); err != nil {
 		return nil, err
 	}
 
+	// Git tools, confined to repositories inside the workspace
+	for _, tool := range tools.NewGitTools(workspace) {
+		if err := registerTool(tool); err != nil {
+			return nil, err
+		}
+	}
+
+	// SQL query tool (if databases are configured)
+	if sqlQuery := newSQLQueryToolFromConfig(cfg); sqlQuery != nil {
+		if err := registerTool(sqlQuery); err != nil {
+			return nil, err
+		}
+		log.Info("SQL query tool enabled", zap.Int("databases", len(cfg.Tools.Databases)))
+	}
+
+	// Kubernetes tools (if enabled)
+	if k8s := cfg.Tools.Kubernetes; k8s.Enabled {
+		for _, tool := range tools.NewKubernetesTools(tools.KubernetesOptions{
+			Kubeconfig: k8s.KubeconfigPath(),
+			Context:    k8s.Context,
+			Namespace:  k8s.Namespace,
+			Namespaces: k8s.Namespaces,
+			AllowApply: k8s.AllowApply,
+			Timeout:    time.Duration(k8s.TimeoutSeconds) * time.Second,
+		}) {
+			if err := registerTool(tool); err != nil {
+				return nil, err
+			}
+		}
+		log.Info("Kubernetes tools enabled", zap.Bool("apply", k8s.AllowApply))
+	}
+
 	// Register process tool
 	if err := registerTool(tools.NewProcessTool(processMgr)); err != nil {
 		return nil, err
@@ -208,12 +314,7 @@ func New(
 	}
 
 	// Register web search tool (Brave first, optional DuckDuckGo fallback)
-	if webSearch := tools.NewWebSearchTool(tools.WebSearchToolOptions{
-		BraveAPIKey:          cfg.Tools.Web.Search.GetBraveAPIKey(),
-		BraveMaxResults:      cfg.Tools.Web.Search.MaxResults,
-		DuckDuckGoEnabled:    cfg.Tools.Web.Search.DuckDuckGoEnabled,
-		DuckDuckGoMaxResults: cfg.Tools.Web.Search.DuckDuckGoMaxResults,
-	}); webSearch != nil {
+	if webSearch := newWebSearchToolFromConfig(cfg); webSearch != nil {
 		if err := registerTool(webSearch); err != nil {
 			return nil, err
 		}
@@ -227,7 +328,9 @@ func New(
 
 	// Browser tool (if Chrome is available)
 	outputDir := cfg.WorkspacePath() + "/screenshots"
-	if err := registerTool(tools.NewBrowserTool(log, true, 30, outputDir)); err != nil {
+	browser := tools.NewBrowserTool(log, true, 30, outputDir)
+	browser.SetWorkspace(workspace, cfg.Agents.Defaults.RestrictToWorkspace)
+	if err := registerTool(browser); err != nil {
 		return nil, err
 	}
 	log.Info("Browser tool enabled")
@@ -242,6 +345,9 @@ func New(
 	if err := registerTool(tools.NewWikiLintTool(workspace)); err != nil {
 		return nil, err
 	}
+	if err := registerTool(tools.NewListToolsTool(toolRegistry)); err != nil {
+		return nil, err
+	}
 
 	var semanticMemory memory.SearchManager
 	if cfg.Memory.Enabled && cfg.Memory.Semantic.Enabled {
@@ -299,6 +405,21 @@ func New(
 
 	// Set tool descriptions function
 	contextBuilder.SetToolDescriptionsFunc(toolRegistry.GetDescriptions)
+	toolUsage := newToolUsageTracker()
+	contextBuilder.SetToolPromptBudget(cfg.Tools.PromptBudget.MaxTokens, func() []string {
+		return rankToolsForPrompt(cfg.Tools.PromptBudget, toolUsage)
+	})
+
+	moderationFilter, err := moderation.New(cfg.Moderation, log)
+	if err != nil {
+		return nil, fmt.Errorf("create moderation filter: %w", err)
+	}
+	if moderationFilter.Enabled() {
+		log.Info("Content moderation enabled",
+			zap.String("provider", cfg.Moderation.Provider),
+			zap.String("action", cfg.Moderation.Action),
+		)
+	}
 
 	agent := &Agent{
 		config:           cfg,
@@ -311,6 +432,7 @@ func New(
 		semanticMemory:   semanticMemory,
 		promptManager:    promptMgr,
 		snapshotMgr:      snapshotMgr,
+		toolUsage:        toolUsage,
 		acpSessions:      make(map[string]*acpSessionState),
 		acpRuntime:       make(map[string]string),
 		kvStore:          kvStore,
@@ -319,6 +441,32 @@ func New(
 		maxIterations:    cfg.Agents.Defaults.MaxToolIterations,
 		entClient:        runtimeEntClient,
 		taskStore:        tasks.NewStore(),
+		moderation:       moderationFilter,
+		turnLimits:       turnlimit.New(cfg),
+		sandbox:          sandboxPool,
+		filePolicy:       filePolicy,
+		processMgr:       processMgr,
+	}
+	if spawnAgent := cfg.Tools.SpawnAgent; spawnAgent.Enabled {
+		if err := registerTool(tools.NewSpawnAgentTool(agent, tools.SpawnAgentOptions{
+			MaxDepth:      spawnAgent.MaxDepth,
+			MaxIterations: spawnAgent.MaxIterations,
+		})); err != nil {
+			return nil, err
+		}
+	}
+	if runtimeEntClient != nil {
+		usageMgr, err := usage.NewManager(cfg, runtimeEntClient)
+		if err != nil {
+			return nil, fmt.Errorf("create usage manager: %w", err)
+		}
+		agent.usage = usageMgr
+
+		feedbackMgr, err := feedback.NewManager(runtimeEntClient)
+		if err != nil {
+			return nil, fmt.Errorf("create feedback manager: %w", err)
+		}
+		agent.feedback = feedbackMgr
 	}
 	agent.taskService = tasks.NewService(agent.taskStore)
 	if processMgr != nil {
@@ -348,6 +496,23 @@ func preprocessConfigFromConfig(cfg *config.Config, workspace string) preprocess
 	return preprocessCfg
 }
 
+func filePolicyOptions(cfg config.FilePolicyConfig) tools.FilePolicyOptions {
+	opts := tools.FilePolicyOptions{
+		FilePathRules:        tools.FilePathRules{Allow: cfg.Allow, Deny: cfg.Deny},
+		MaxFileBytes:         cfg.MaxFileBytes,
+		MaxSessionWriteBytes: cfg.MaxSessionWriteBytes,
+		RedactSecrets:        cfg.RedactSecrets,
+		SecretPatterns:       cfg.SecretPatterns,
+	}
+	if len(cfg.Agents) > 0 {
+		opts.Agents = make(map[string]tools.FilePathRules, len(cfg.Agents))
+		for name, rules := range cfg.Agents {
+			opts.Agents[name] = tools.FilePathRules{Allow: rules.Allow, Deny: rules.Deny}
+		}
+	}
+	return opts
+}
+
 // RegisterSkillTool registers the skill tool with the agent.
 // This should be called after agent creation when skills manager is available.
 func (a *Agent) RegisterSkillTool(skillsManager *skills.Manager) {
@@ -431,6 +596,22 @@ func (a *Agent) TaskStore() *tasks.Store {
 	return a.taskStore
 }
 
+// BackgroundJobs exposes the run_background job manager, or nil before it is wired.
+func (a *Agent) BackgroundJobs() *background.Manager {
+	if a == nil {
+		return nil
+	}
+	return a.background
+}
+
+// Feedback exposes the reply rating store, or nil without a runtime database.
+func (a *Agent) Feedback() *feedback.Manager {
+	if a == nil {
+		return nil
+	}
+	return a.feedback
+}
+
 // ApprovalManager exposes the shared approval manager for higher-level control planes.
 func (a *Agent) ApprovalManager() *approval.Manager {
 	if a == nil {
@@ -468,6 +649,8 @@ func (a *Agent) Definition() AgentDefinition {
 	if a == nil {
 		return AgentDefinition{}
 	}
+	a.configMu.RLock()
+	defer a.configMu.RUnlock()
 	return a.definition
 }
 
@@ -619,12 +802,33 @@ func (a *Agent) chatWithProviderModelDetailed(
 ) (string, ChatRouteResult, error) {
 	ctx = context.WithValue(ctx, promptContextChannelKey, strings.TrimSpace(promptCtx.Channel))
 	ctx = context.WithValue(ctx, promptContextSessionKey, strings.TrimSpace(promptCtx.SessionID))
+	ctx = context.WithValue(ctx, promptContextUserKey, strings.TrimSpace(promptCtx.UserID))
 	if promptCtx.Custom != nil {
 		if runtimeID, ok := promptCtx.Custom["runtime_id"].(string); ok {
 			ctx = context.WithValue(ctx, promptContextRuntimeKey, strings.TrimSpace(runtimeID))
 		}
 	}
 
+	// Flagged input short-circuits with a refusal; the agent never sees it.
+	if verdict := a.moderationFilter().CheckInput(ctx, userMessage); verdict.Blocked {
+		return verdict.Message, ChatRouteResult{}, nil
+	}
+
+	// Cap concurrent turns per user; a refused turn answers with the busy
+	// notice instead of touching the session.
+	releaseTurn, err := a.turnLimits.Acquire(ctx, promptCtx.UserID)
+	if err != nil {
+		if errors.Is(err, turnlimit.ErrBusy) {
+			a.logger.Info("Refused concurrent turn",
+				zap.String("user_id", strings.TrimSpace(promptCtx.UserID)),
+				zap.String("channel", strings.TrimSpace(promptCtx.Channel)),
+			)
+			return a.turnLimits.BusyMessage(), ChatRouteResult{}, nil
+		}
+		return "", ChatRouteResult{}, err
+	}
+	defer releaseTurn()
+
 	sessionID := strings.TrimSpace(promptCtx.SessionID)
 	if sessionID == "" {
 		if identifiable, ok := sess.(interface{ GetID() string }); ok {
@@ -635,6 +839,25 @@ func (a *Agent) chatWithProviderModelDetailed(
 		a.RegisterUndoTool(sessionID)
 	}
 
+	profile, hasProfile := a.sessionAgentProfile(sess, promptCtx.Channel, sessionID)
+	if !hasProfile {
+		// Child agents keep the profile of the agent that spawned them.
+		profile, hasProfile = agentProfileFromContext(ctx)
+	}
+	requestedProvider := provider
+	if hasProfile {
+		provider, model = applyAgentProfileRoute(profile, provider, model)
+		ctx = withAgentProfile(ctx, profile)
+	}
+	// After the profile, so a profile workspace cannot move a tenant's turn
+	// out of the tenant's workspace.
+	ctx = a.withTenantWorkspace(ctx)
+	if strings.TrimSpace(requestedProvider) == "" {
+		// Session overrides beat defaults and the profile's route, but not a
+		// provider the caller asked for explicitly.
+		provider, model, fallback = applySessionModelOverride(sess, provider, model, fallback)
+	}
+
 	// Save snapshot before each turn (for undo functionality)
 	if a.snapshotMgr != nil && sess != nil {
 		store := a.snapshotMgr.GetStore(sessionID)
@@ -651,23 +874,70 @@ func (a *Agent) chatWithProviderModelDetailed(
 		}
 	}
 
-	orchestrator, err := a.resolveOrchestrator()
+	autoTitle := a.shouldAutoTitle(sess)
+
+	var trace *developerTrace
+	if sessionDeveloperMode(sess) {
+		trace = &developerTrace{}
+		ctx = context.WithValue(ctx, promptContextDeveloperTraceKey, trace)
+	}
+	sources := newSourceCollector()
+	ctx = context.WithValue(ctx, promptContextToolObserverKey, toolExecutionObserver(sources))
+	ctx, turnUsage := withTurnUsage(ctx)
+	ctx = a.withSkillScope(ctx)
+	if promptCtx.Stream != nil && !a.moderationFilter().ChecksOutput() {
+		ctx = withStream(ctx, promptCtx.Stream)
+	}
+	if promptCtx.ToolProgress != nil {
+		ctx = withToolProgress(ctx, promptCtx.ToolProgress)
+	}
+
+	override := strings.TrimSpace(promptCtx.Orchestrator)
+	if override == "" {
+		override = sessionOrchestrator(sess)
+	}
+	orchestrator, err := a.resolveOrchestratorOverride(override)
 	if err != nil {
 		return "", ChatRouteResult{}, err
 	}
 
 	a.logger.Debug("Dispatching chat orchestration",
 		zap.String("orchestrator", orchestrator),
+		zap.Bool("override", override != ""),
 	)
 
+	var (
+		response    string
+		routeResult ChatRouteResult
+	)
 	switch orchestrator {
 	case orchestratorBlades:
-		return a.chatWithBladesOrchestrator(ctx, sess, userMessage, provider, model, fallback, promptCtx)
+		response, routeResult, err = a.chatWithBladesOrchestrator(ctx, sess, userMessage, provider, model, fallback, promptCtx)
 	case orchestratorLegacy:
-		return a.chatWithLegacyOrchestrator(ctx, sess, userMessage, provider, model, fallback, promptCtx)
+		response, routeResult, err = a.chatWithLegacyOrchestrator(ctx, sess, userMessage, provider, model, fallback, promptCtx)
 	default:
 		return "", ChatRouteResult{}, fmt.Errorf("unsupported orchestrator: %s", orchestrator)
 	}
+	routeResult.Orchestrator = orchestrator
+	if hasProfile {
+		routeResult.AgentProfile = profile.Name
+	}
+	if trace != nil {
+		routeResult.Debug = trace.snapshot()
+	}
+	routeResult.Sources = sources.snapshot()
+	routeResult.Usage = turnUsage.snapshot()
+	if err == nil {
+		if verdict := a.moderationFilter().CheckOutput(ctx, userMessage, response); verdict.Blocked {
+			response = verdict.Message
+		} else if a.config.Tools.Web.CiteSources {
+			response = appendSources(response, routeResult.Sources)
+		}
+		if autoTitle {
+			a.autoTitleSession(ctx, sess, userMessage)
+		}
+	}
+	return response, routeResult, err
 }
 
 // convertToSnapshotMessages converts agent.Message slice to session.MessageSnapshot slice.
@@ -717,6 +987,53 @@ func (a *Agent) sessionHistory(sess SessionInterface) []Message {
 	return sess.GetMessages()
 }
 
+// sessionOrchestrator returns the session's orchestrator override, if any.
+func sessionOrchestrator(sess SessionInterface) string {
+	scoped, ok := sess.(orchestratorSession)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(scoped.GetOrchestrator())
+}
+
+// applySessionModelOverride replaces provider, model and fallback with the
+// session's overrides, where set.
+func applySessionModelOverride(sess SessionInterface, provider, model string, fallback []string) (string, string, []string) {
+	scoped, ok := sess.(modelOverrideSession)
+	if !ok {
+		return provider, model, fallback
+	}
+	overrideProvider, overrideModel, overrideFallback := scoped.GetModelOverride()
+	if value := strings.TrimSpace(overrideProvider); value != "" {
+		provider = value
+	}
+	if value := strings.TrimSpace(overrideModel); value != "" {
+		model = value
+	}
+	if len(overrideFallback) > 0 {
+		fallback = overrideFallback
+	}
+	return provider, model, fallback
+}
+
+// sessionPins returns the session's pinned context, if the session supports pins.
+func sessionPins(sess SessionInterface) []string {
+	pinned, ok := sess.(pinnedContextSession)
+	if !ok {
+		return nil
+	}
+	return pinned.GetPins()
+}
+
+// sessionPersona returns the session's custom system prompt, if any.
+func sessionPersona(sess SessionInterface) string {
+	scoped, ok := sess.(personaSession)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(scoped.GetPersona())
+}
+
 func newMemoryStoreFromConfig(cfg *config.Config, workspace string, kvStore state.KV, runtimeEntClient *ent.Client) *promptmemory.Store {
 	if cfg == nil || !cfg.Memory.Enabled {
 		return promptmemory.NewStoreWithBackend(workspace, promptmemory.NewNoopBackend())
@@ -763,11 +1080,26 @@ func newSemanticMemoryManagerFromConfig(log *logger.Logger, cfg *config.Config)
 }
 
 func (a *Agent) resolveOrchestrator() (string, error) {
-	orchestrator := strings.TrimSpace(strings.ToLower(a.config.Agents.Defaults.Orchestrator))
+	return a.resolveOrchestratorOverride("")
+}
+
+// resolveOrchestratorOverride prefers a per-request or per-session override
+// over the configured default. Both go through the same validation.
+func (a *Agent) resolveOrchestratorOverride(override string) (string, error) {
+	if strings.TrimSpace(override) != "" {
+		return NormalizeOrchestrator(override)
+	}
+	orchestrator := strings.TrimSpace(a.config.Agents.Defaults.Orchestrator)
 	if orchestrator == "" {
 		return orchestratorBlades, nil
 	}
+	return NormalizeOrchestrator(orchestrator)
+}
 
+// NormalizeOrchestrator validates an orchestrator name ("blades" or "legacy")
+// and returns it in canonical form.
+func NormalizeOrchestrator(name string) (string, error) {
+	orchestrator := strings.TrimSpace(strings.ToLower(name))
 	switch orchestrator {
 	case orchestratorLegacy, orchestratorBlades:
 		return orchestrator, nil
@@ -809,11 +1141,14 @@ func (a *Agent) chatWithLegacyOrchestrator(
 	if err != nil {
 		return "", routeResult, err
 	}
+	resolvedPrompts.Pinned = sessionPins(sess)
+	resolvedPrompts.Persona = sessionPersona(sess)
+	resolvedPrompts.AgentProfile = agentProfilePrompt(ctx)
 	routeResult = a.enrichChatRouteResultWithContextPreview(routeResult, resolvedPrompts, promptCtx, userMessage)
 	messages := a.context.BuildMessagesWithPromptSet(history, userMessage, resolvedPrompts)
 
 	// Convert to provider format
-	providerMessages := a.convertToProviderMessages(messages)
+	providerMessages := withImages(a.convertToProviderMessages(messages), imageParts(promptCtx.Attachments))
 	if routeResult.Preflight.Action == "compact_before_run" {
 		compressedMessages := forceCompressMessages(providerMessages)
 		if len(compressedMessages) != len(providerMessages) {
@@ -828,16 +1163,17 @@ func (a *Agent) chatWithLegacyOrchestrator(
 	}
 
 	// Tool definitions
-	toolDefs := a.convertToolDefinitions()
+	toolDefs := filterAllowedTools(ctx, a.convertToolDefinitions())
 
 	// Main agent loop
+	maxIterations := a.iterationLimit(ctx)
 	iteration := 0
-	for iteration < a.maxIterations {
+	for iteration < maxIterations {
 		iteration++
 
 		a.logger.Debug("Agent iteration",
 			zap.Int("iteration", iteration),
-			zap.Int("max", a.maxIterations),
+			zap.Int("max", maxIterations),
 		)
 
 		// Create request
@@ -847,14 +1183,7 @@ func (a *Agent) chatWithLegacyOrchestrator(
 			Tools:       toolDefs,
 			MaxTokens:   a.config.Agents.Defaults.MaxTokens,
 			Temperature: a.config.Agents.Defaults.Temperature,
-		}
-
-		// Pass extended thinking config via Extra
-		if a.config.Agents.Defaults.ExtendedThinking {
-			req.Extra = map[string]interface{}{
-				"extended_thinking": true,
-				"thinking_budget":   a.config.Agents.Defaults.ThinkingBudget,
-			}
+			Extra:       a.requestExtra(),
 		}
 
 		// Call LLM with provider fallback, with retry on context errors.
@@ -930,7 +1259,7 @@ func (a *Agent) chatWithLegacyOrchestrator(
 		// Execute tool calls
 		trackedSessionID := strings.TrimSpace(promptCtx.SessionID)
 		if a.taskStore != nil && trackedSessionID != "" {
-			a.taskStore.EnsureSessionToolRoundLimit(trackedSessionID, a.maxIterations)
+			a.taskStore.EnsureSessionToolRoundLimit(trackedSessionID, a.maxToolIterations())
 			if !a.taskStore.CanStartSessionToolRound(trackedSessionID) {
 				state, _ := a.taskStore.GetSessionState(trackedSessionID)
 				return "", routeResult, fmt.Errorf("max tool rounds (%d) reached for session %s", state.MaxToolRounds, trackedSessionID)
@@ -961,7 +1290,7 @@ func (a *Agent) chatWithLegacyOrchestrator(
 		}
 	}
 
-	return "", routeResult, fmt.Errorf("max iterations (%d) reached without final response", a.maxIterations)
+	return "", routeResult, fmt.Errorf("max iterations (%d) reached without final response", maxIterations)
 }
 
 func (a *Agent) enrichChatRouteResultWithContextPreview(
@@ -1057,20 +1386,33 @@ func (a *Agent) newClientForProvider(providerName, model string) (*providers.Cli
 	}
 
 	client, err := providers.NewClient(providerKind, &providers.RelayInfo{
-		ProviderName: providerName,
-		APIKey:       providerCfg.APIKey,
-		APIBase:      providerCfg.APIBase,
-		Model:        model,
-		Proxy:        providerCfg.Proxy,
-		Timeout:      providerCfg.GetTimeout(),
+		ProviderName:  providerName,
+		APIKey:        providerCfg.APIKey,
+		APIBase:       providerCfg.APIBase,
+		Model:         model,
+		Proxy:         providerCfg.Proxy,
+		Timeout:       providerCfg.GetTimeout(),
+		APIVersion:    providerCfg.APIVersion,
+		Deployments:   providerCfg.Deployments,
+		DisableStream: !providerCfg.StreamingEnabled() || providerCfg.Capabilities.StreamingUnsupported(model),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("create provider client for %s: %w", providerName, err)
 	}
+	client.Use(providerMiddleware(a.config, a.logger, providerName, providerCfg)...)
 
 	return client, nil
 }
 
+// providerMiddleware builds the request middleware configured on a
+// provider profile, pricing spend limits from the usage pricing table.
+func providerMiddleware(cfg *config.Config, log *logger.Logger, providerName string, providerCfg *config.ProviderProfile) []providers.Middleware {
+	return providers.ProfileMiddleware(providerCfg.Middleware, providers.MiddlewareOptions{
+		Logger:       log,
+		EstimateCost: usage.Estimator(cfg, providerName),
+	})
+}
+
 func (a *Agent) buildProviderOrder(provider string, fallback []string) ([]string, error) {
 	if a.providerGroups == nil {
 		a.providerGroups = newProviderGroupPlanner()
@@ -1139,16 +1481,33 @@ func (a *Agent) callLLMWithFallback(
 	requestedModel string,
 	clientCache map[string]*providers.Client,
 ) (*providers.UnifiedResponse, string, string, error) {
+	if err := checkDelegationBudget(ctx); err != nil {
+		return nil, "", "", err
+	}
 	tracker := a.getFailoverCooldown()
+	if a.latencyRouting() {
+		providerOrder = tracker.RankByLatency(providerOrder)
+	}
 	var lastErr error
 	var lastProviderUsed string
 	var lastModelUsed string
 	var attempts []providers.FallbackAttempt
+	maxAttempts := a.maxFallbackAttempts()
+	tried := 0
+	var untried []string
 
-	for _, providerName := range providerOrder {
+	for i, providerName := range providerOrder {
 		if ctxErr := ctx.Err(); ctxErr != nil {
 			return nil, "", "", ctxErr
 		}
+		if maxAttempts > 0 && tried >= maxAttempts {
+			untried = append(untried, providerOrder[i:]...)
+			a.logger.Warn("Provider fallback attempts exhausted",
+				zap.Int("max_fallback_attempts", maxAttempts),
+				zap.Strings("untried", untried),
+			)
+			break
+		}
 
 		if !tracker.IsAvailable(providerName) {
 			remaining := tracker.CooldownRemaining(providerName)
@@ -1182,7 +1541,7 @@ func (a *Agent) callLLMWithFallback(
 		client, err := a.getProviderClient(providerName, model, clientCache)
 		if err != nil {
 			lastErr = err
-			tracker.MarkFailure(providerName, providers.FailoverReasonUnknown)
+			a.markProviderFailure(ctx, tracker, providerName, providers.FailoverReasonUnknown)
 			if a.providerGroups != nil {
 				a.providerGroups.recordFailure(providerName, err)
 			}
@@ -1192,8 +1551,13 @@ func (a *Agent) callLLMWithFallback(
 
 		reqCopy := *req
 		reqCopy.Model = model
+		reqCopy.Messages = a.withProviderSystemPrefix(providerName, req.Messages)
+		a.applyProviderCapabilities(providerName, &reqCopy)
+		a.traceDeveloperRequest(ctx, providerName, &reqCopy)
 
-		resp, err := client.Chat(ctx, &reqCopy)
+		tried++
+		started := time.Now()
+		resp, err := a.requestLLM(ctx, client, &reqCopy)
 		if err != nil {
 			if ctxErr := ctx.Err(); ctxErr != nil {
 				return nil, "", "", ctxErr
@@ -1228,7 +1592,7 @@ func (a *Agent) callLLMWithFallback(
 				return nil, lastProviderUsed, lastModelUsed, loggedErr
 			}
 
-			tracker.MarkFailure(providerName, reason)
+			a.markProviderFailure(ctx, tracker, providerName, reason)
 			if a.providerGroups != nil {
 				a.providerGroups.recordFailure(providerName, loggedErr)
 			}
@@ -1236,9 +1600,13 @@ func (a *Agent) callLLMWithFallback(
 		}
 
 		tracker.MarkSuccess(providerName)
+		tracker.RecordLatency(providerName, time.Since(started))
 		if a.providerGroups != nil {
 			a.providerGroups.recordSuccess(providerName)
 		}
+		a.recordUsage(ctx, providerName, model, resp.Usage)
+		recordDelegationUsage(ctx, resp.Usage)
+		recordTurnUsage(ctx, resp.Usage)
 		return resp, providerName, model, nil
 	}
 
@@ -1246,11 +1614,129 @@ func (a *Agent) callLLMWithFallback(
 		lastErr = fmt.Errorf("no provider attempt made")
 	}
 	if len(attempts) > 0 {
-		lastErr = &providers.FallbackExhaustedError{Attempts: attempts}
+		lastErr = &providers.FallbackExhaustedError{
+			Attempts:    attempts,
+			MaxAttempts: maxAttempts,
+			Untried:     untried,
+		}
+		a.logger.Error("All provider fallbacks failed", zap.Error(lastErr))
 	}
 	return nil, lastProviderUsed, lastModelUsed, lastErr
 }
 
+// withProviderSystemPrefix prepends the provider's system_prefix to the system
+// prompt. It returns a copy so a fallback provider never inherits another
+// provider's prefix.
+func (a *Agent) withProviderSystemPrefix(providerName string, messages []providers.UnifiedMessage) []providers.UnifiedMessage {
+	providerCfg := a.config.GetProviderConfig(providerName)
+	if providerCfg == nil {
+		return messages
+	}
+	prefix := strings.TrimSpace(providerCfg.SystemPrefix)
+	if prefix == "" {
+		return messages
+	}
+
+	prefixed := make([]providers.UnifiedMessage, 0, len(messages)+1)
+	for i, msg := range messages {
+		if msg.Role != "system" {
+			continue
+		}
+		prefixed = append(prefixed, messages[:i]...)
+		if strings.TrimSpace(msg.Content) != "" {
+			msg.Content = prefix + "\n\n" + msg.Content
+		} else {
+			msg.Content = prefix
+		}
+		prefixed = append(prefixed, msg)
+		return append(prefixed, messages[i+1:]...)
+	}
+	prefixed = append(prefixed, providers.UnifiedMessage{Role: "system", Content: prefix})
+	return append(prefixed, messages...)
+}
+
+// applyProviderCapabilities drops request features a capability probe found
+// unsupported for the provider's model, so the provider is not sent params it
+// would reject with a format error.
+func (a *Agent) applyProviderCapabilities(providerName string, req *providers.UnifiedRequest) {
+	providerCfg := a.config.GetProviderConfig(providerName)
+	if providerCfg == nil {
+		return
+	}
+	if len(req.Tools) > 0 && providerCfg.Capabilities.ToolCallingUnsupported(req.Model) {
+		a.logger.Debug("Omitting tools for provider without tool calling support",
+			zap.String("provider", providerName),
+			zap.String("model", req.Model),
+		)
+		req.Tools = nil
+		req.ToolChoice = nil
+	}
+	if providerCfg.Capabilities.VisionUnsupported(req.Model) {
+		if messages, dropped := withoutImages(req.Messages); dropped {
+			a.logger.Debug("Omitting images for provider without vision support",
+				zap.String("provider", providerName),
+				zap.String("model", req.Model),
+			)
+			req.Messages = messages
+		}
+	}
+}
+
+// requestExtra returns the provider-specific request options from the agent
+// defaults: extended thinking and prompt caching.
+func (a *Agent) requestExtra() map[string]interface{} {
+	defaults := a.config.Agents.Defaults
+	if !defaults.ExtendedThinking && !defaults.PromptCaching {
+		return nil
+	}
+	extra := map[string]interface{}{}
+	if defaults.ExtendedThinking {
+		extra["extended_thinking"] = true
+		extra["thinking_budget"] = defaults.ThinkingBudget
+	}
+	if defaults.PromptCaching {
+		extra["prompt_caching"] = true
+	}
+	return extra
+}
+
+// recordUsage persists token usage for one successful provider call.
+func (a *Agent) recordUsage(ctx context.Context, providerName, model string, usageInfo *providers.UnifiedUsage) {
+	if usageInfo == nil || !a.usage.Enabled() {
+		return
+	}
+	err := a.usage.Record(context.WithoutCancel(ctx), usage.Record{
+		Provider:         providerName,
+		Model:            model,
+		UserID:           ctxStringValue(ctx, promptContextUserKey),
+		SessionID:        ctxStringValue(ctx, promptContextSessionKey),
+		Channel:          ctxStringValue(ctx, promptContextChannelKey),
+		PromptTokens:     usageInfo.PromptTokens,
+		CompletionTokens: usageInfo.CompletionTokens,
+		TotalTokens:      usageInfo.TotalTokens,
+		CacheReadTokens:  usageInfo.CacheReadTokens,
+		CacheWriteTokens: usageInfo.CacheWriteTokens,
+	})
+	if err != nil {
+		a.logger.Warn("Failed to record token usage", zap.String("provider", providerName), zap.Error(err))
+	}
+}
+
+// maxFallbackAttempts returns how many providers may be called per model turn.
+// Zero means the whole provider chain may be tried.
+func (a *Agent) maxFallbackAttempts() int {
+	if a == nil || a.config == nil || a.config.Agents.Defaults.MaxFallbackAttempts < 0 {
+		return 0
+	}
+	return a.config.Agents.Defaults.MaxFallbackAttempts
+}
+
+// latencyRouting reports whether the provider chain is ordered by latency.
+func (a *Agent) latencyRouting() bool {
+	return a != nil && a.config != nil &&
+		strings.EqualFold(strings.TrimSpace(a.config.Agents.Defaults.RoutingPolicy), "latency")
+}
+
 func (a *Agent) getFailoverCooldown() *providers.CooldownTracker {
 	a.failoverMu.Lock()
 	defer a.failoverMu.Unlock()
@@ -1258,6 +1744,10 @@ func (a *Agent) getFailoverCooldown() *providers.CooldownTracker {
 	if a.failoverCooldown == nil {
 		a.failoverCooldown = providers.NewCooldownTracker()
 	}
+	// Applied on every use so a config reload takes effect.
+	if a.config != nil {
+		a.failoverCooldown.SetFailureThreshold(a.config.Agents.Defaults.CircuitBreakerThreshold)
+	}
 
 	return a.failoverCooldown
 }
@@ -1388,13 +1878,41 @@ func (a *Agent) resolveModelFromRoutes(
 	return "", fmt.Errorf("resolve route for provider %s model %s: %w", providerName, logicalModelID, modelroute.ErrRouteNotFound)
 }
 
-// executeToolCall executes a single tool call with approval checking.
+// executeToolCall runs one tool call and reports its progress to the turn.
 func (a *Agent) executeToolCall(ctx context.Context, toolCall providers.UnifiedToolCall) (string, error) {
+	reportToolProgress(ctx, ToolProgress{ID: toolCall.ID, Name: toolCall.Name})
+	result, err := a.runToolCall(ctx, toolCall)
+	if err == nil && toolCall.Name == "skill" {
+		a.activateInvokedSkill(ctx, parseSkillToolCall(toolCall.Arguments))
+	}
+	done := ToolProgress{ID: toolCall.ID, Name: toolCall.Name, Done: true}
+	if err != nil {
+		done.Error = err.Error()
+		a.publishToolFailure(ctx, toolCall, err)
+	}
+	reportToolProgress(ctx, done)
+	return result, err
+}
+
+// runToolCall checks a tool call against the agent profile, session limits,
+// permission rules and approval policy, then executes it.
+func (a *Agent) runToolCall(ctx context.Context, toolCall providers.UnifiedToolCall) (string, error) {
 	a.logger.Info("Executing tool",
 		zap.String("tool", toolCall.Name),
 		zap.Any("args", toolCall.Arguments),
 	)
 
+	if !profileAllowsTool(ctx, toolCall.Name) {
+		profile, _ := agentProfileFromContext(ctx)
+		return "", fmt.Errorf("tool %s is not enabled for agent profile %s", toolCall.Name, profile.Name)
+	}
+	if !delegationAllowsTool(ctx, toolCall.Name) {
+		return "", fmt.Errorf("tool %s is not available to this child agent", toolCall.Name)
+	}
+	if skill := blockingSkill(ctx, toolCall.Name); skill != nil {
+		return "", fmt.Errorf("tool %s is not permitted by the permissions manifest of skill %s", toolCall.Name, skill.ID)
+	}
+
 	sessionID := ctxStringValue(ctx, promptContextSessionKey)
 	runtimeID := ctxStringValue(ctx, promptContextRuntimeKey)
 	skipApproval := false
@@ -1438,6 +1956,7 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall providers.UnifiedT
 				if a.taskStore != nil {
 					a.taskStore.SetSessionPendingAction(sessionID, toolCall.Name, requestID)
 				}
+				a.publishApprovalRequest(sessionID, requestID, toolCall)
 				return "Tool call pending approval", nil
 			case permissionrules.ActionAllow:
 				if a.taskStore != nil {
@@ -1452,6 +1971,7 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall providers.UnifiedT
 	// Check approval
 	if a.approval != nil && !skipApproval {
 		decision, requestID, err := a.approval.CheckApproval(
+			ctx,
 			toolCall.Name,
 			toolCall.Arguments,
 			sessionID,
@@ -1476,6 +1996,7 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall providers.UnifiedT
 					a.taskStore.SetSessionPermissionMode(sessionID, string(mode))
 				}
 			}
+			a.publishApprovalRequest(sessionID, requestID, toolCall)
 			return "Tool call pending approval", nil
 		case approval.Approved:
 			if a.taskStore != nil {
@@ -1489,6 +2010,7 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall providers.UnifiedT
 	if a.taskStore != nil && sessionID != "" {
 		a.taskStore.SetSessionLifecycleState(sessionID, tasks.SessionLifecycleProcessing, toolCall.Name)
 	}
+	a.toolUsage.record(toolCall.Name)
 
 	if toolCall.Name == "spawn" {
 		ctx = tools.WithSpawnContext(
@@ -1497,6 +2019,20 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall providers.UnifiedT
 			ctxStringValue(ctx, promptContextSessionKey),
 		)
 	}
+	if toolCall.Name == "send_file" {
+		ctx = tools.WithSendFileContext(
+			ctx,
+			ctxStringValue(ctx, promptContextChannelKey),
+			ctxStringValue(ctx, promptContextSessionKey),
+		)
+	}
+	if toolCall.Name == "run_background" {
+		ctx = tools.WithBackgroundContext(
+			ctx,
+			ctxStringValue(ctx, promptContextChannelKey),
+			ctxStringValue(ctx, promptContextSessionKey),
+		)
+	}
 
 	if sessionID := ctxStringValue(ctx, promptContextSessionKey); sessionID != "" {
 		ctx = context.WithValue(ctx, "session_id", sessionID)
@@ -1505,7 +2041,9 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall providers.UnifiedT
 		ctx = context.WithValue(ctx, "runtime_id", runtimeID)
 	}
 
-	result, err := a.tools.Execute(ctx, toolCall.Name, toolCall.Arguments)
+	result, err := runToolWithTimeout(ctx, toolCall.Name, a.toolTimeout(toolCall.Name, false), func(toolCtx context.Context) (string, error) {
+		return a.tools.Execute(toolCtx, toolCall.Name, toolCall.Arguments)
+	})
 	if err != nil {
 		if a.taskStore != nil {
 			a.taskStore.SetSessionLifecycleState(sessionID, tasks.SessionLifecycleIdle, "")
@@ -1515,6 +2053,7 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall providers.UnifiedT
 	if a.taskStore != nil {
 		a.taskStore.SetSessionLifecycleState(sessionID, tasks.SessionLifecycleIdle, "")
 	}
+	observeToolExecution(ctx, toolCall, result)
 
 	return result, nil
 }
@@ -1525,6 +2064,16 @@ const (
 	promptContextChannelKey promptContextKey = "prompt_channel"
 	promptContextSessionKey promptContextKey = "prompt_session_id"
 	promptContextRuntimeKey promptContextKey = "prompt_runtime_id"
+	promptContextUserKey    promptContextKey = "prompt_user_id"
+
+	promptContextDeveloperTraceKey promptContextKey = "developer_trace"
+	promptContextToolObserverKey   promptContextKey = "tool_observer"
+	promptContextStreamKey         promptContextKey = "stream"
+	promptContextToolProgressKey   promptContextKey = "tool_progress"
+	promptContextAgentProfileKey   promptContextKey = "agent_profile"
+	promptContextDelegationKey     promptContextKey = "delegation"
+	promptContextTurnUsageKey      promptContextKey = "turn_usage"
+	promptContextSkillScopeKey     promptContextKey = "skill_scope"
 )
 
 func ctxStringValue(ctx context.Context, key promptContextKey) string {
@@ -1541,6 +2090,7 @@ func (a *subagentAgentAdapter) Chat(ctx context.Context, message string) (string
 	}
 
 	sess := &subagentSession{messages: make([]Message, 0, 8)}
+	ctx = withToolProgress(withStream(ctx, nil), nil)
 	return a.agent.Chat(ctx, sess, message)
 }
 
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-kratos/blades"
 	bladestools "github.com/go-kratos/blades/tools"
@@ -833,6 +834,39 @@ func TestNewAgent_SeedsDefinitionSnapshot(t *testing.T) {
 	}
 }
 
+func TestNewAgent_K8sApplyNeedsApprovalInAutoMode(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Approval.Mode = "auto"
+	cfg.Tools.Kubernetes.Enabled = true
+	cfg.Tools.Kubernetes.AllowApply = true
+
+	logCfg := logger.DefaultConfig()
+	logCfg.OutputPath = ""
+	log, err := logger.New(logCfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	approvals := approval.NewManager(approval.Config{Mode: approval.ModeAuto, Allowlist: []string{"k8s_*"}})
+	if _, err := New(cfg, log, nil, nil, approvals, nil, nil, nil, nil); err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := context.Background()
+	args := map[string]interface{}{"manifest": "kind: Namespace"}
+	if decision, id, err := approvals.CheckApproval(ctx, "k8s_apply", args, "s1"); err != nil || decision != approval.Pending || id == "" {
+		t.Fatalf("expected k8s_apply to be queued in auto mode, got %s %q (err %v)", decision, id, err)
+	}
+	approvals.SetSessionMode("s2", approval.ModeAuto)
+	if decision, _, err := approvals.CheckApproval(ctx, "k8s_apply", args, "s2"); err != nil || decision != approval.Pending {
+		t.Fatalf("expected an auto session override to keep k8s_apply queued, got %s (err %v)", decision, err)
+	}
+	if decision, _, err := approvals.CheckApproval(ctx, "k8s_get", nil, "s1"); err != nil || decision != approval.Approved {
+		t.Fatalf("expected read-only tools to stay automatic, got %s (err %v)", decision, err)
+	}
+}
+
 func TestPreviewContextSources_IncludesKeySourceTypes(t *testing.T) {
 	workspace := t.TempDir()
 	if err := os.WriteFile(filepath.Join(workspace, "AGENTS.md"), []byte("project-rules"), 0644); err != nil {
@@ -1059,6 +1093,144 @@ func TestPreviewContextSources_ReportsWarningBudgetStatusForMemoryPressure(t *te
 	}
 }
 
+func TestChatWithPromptContextDetailed_AppliesModeration(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Orchestrator = orchestratorLegacy
+	cfg.Agents.Defaults.Provider = "test-primary"
+	cfg.Agents.Defaults.Model = "gpt-5.4"
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Providers = []config.ProviderProfile{
+		{
+			Name:         "test-primary",
+			ProviderKind: failoverTestProviderKind(t, "primary"),
+		},
+	}
+	cfg.Moderation.Enabled = true
+	cfg.Moderation.CheckOutput = true
+	cfg.Moderation.Keywords = []string{"forbidden"}
+	cfg.Moderation.RefusalMessage = "refused"
+	cfg.Moderation.ReplacementMessage = "replaced"
+
+	callCount := 0
+	registerFailoverTestProvider(t, cfg.Providers[0].ProviderKind, &callCount, "a forbidden answer", nil)
+
+	logCfg := logger.DefaultConfig()
+	logCfg.OutputPath = ""
+	logCfg.Development = true
+	log, err := logger.New(logCfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	ag, err := New(cfg, log, nil, nil, approval.NewManager(approval.Config{Mode: approval.ModeAuto}), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	promptCtx := PromptContext{SessionID: "webui-chat:tester", Channel: "webui"}
+
+	response, _, err := ag.ChatWithPromptContextDetailed(context.Background(), &testSession{}, "tell me something FORBIDDEN", promptCtx)
+	if err != nil {
+		t.Fatalf("ChatWithPromptContextDetailed failed: %v", err)
+	}
+	if response != "refused" {
+		t.Fatalf("expected refusal, got %q", response)
+	}
+	if callCount != 0 {
+		t.Fatalf("expected flagged input to skip the provider, got %d calls", callCount)
+	}
+
+	response, _, err = ag.ChatWithPromptContextDetailed(context.Background(), &testSession{}, "hello", promptCtx)
+	if err != nil {
+		t.Fatalf("ChatWithPromptContextDetailed failed: %v", err)
+	}
+	if response != "replaced" {
+		t.Fatalf("expected flagged output to be replaced, got %q", response)
+	}
+	if callCount != 1 {
+		t.Fatalf("expected one provider call, got %d", callCount)
+	}
+}
+
+func TestChatWithPromptContextDetailed_EnforcesPerUserTurnLimit(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Orchestrator = orchestratorLegacy
+	cfg.Agents.Defaults.Provider = "test-primary"
+	cfg.Agents.Defaults.Model = "gpt-5.4"
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Providers = []config.ProviderProfile{
+		{
+			Name:         "test-primary",
+			ProviderKind: failoverTestProviderKind(t, "primary"),
+		},
+	}
+	cfg.TurnLimits.Enabled = true
+	cfg.TurnLimits.MaxPerUser = 1
+	cfg.TurnLimits.Mode = "reject"
+	cfg.TurnLimits.BusyMessage = "one at a time"
+
+	callCount := 0
+	registerFailoverTestProvider(t, cfg.Providers[0].ProviderKind, &callCount, "answer", nil)
+
+	logCfg := logger.DefaultConfig()
+	logCfg.OutputPath = ""
+	logCfg.Development = true
+	log, err := logger.New(logCfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	ag, err := New(cfg, log, nil, nil, approval.NewManager(approval.Config{Mode: approval.ModeAuto}), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// Hold alice's only slot as if another of her turns were still running.
+	release, err := ag.turnLimits.Acquire(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("acquire turn: %v", err)
+	}
+
+	response, _, err := ag.ChatWithPromptContextDetailed(context.Background(), &testSession{}, "hello", PromptContext{
+		SessionID: "telegram:1",
+		Channel:   "telegram",
+		UserID:    "alice",
+	})
+	if err != nil {
+		t.Fatalf("ChatWithPromptContextDetailed failed: %v", err)
+	}
+	if response != "one at a time" || callCount != 0 {
+		t.Fatalf("expected busy notice without a provider call, got %q after %d calls", response, callCount)
+	}
+
+	response, _, err = ag.ChatWithPromptContextDetailed(context.Background(), &testSession{}, "hello", PromptContext{
+		SessionID: "discord:2",
+		Channel:   "discord",
+		UserID:    "bob",
+	})
+	if err != nil {
+		t.Fatalf("ChatWithPromptContextDetailed failed: %v", err)
+	}
+	if response != "answer" || callCount != 1 {
+		t.Fatalf("expected another user to be unaffected, got %q after %d calls", response, callCount)
+	}
+
+	release()
+	response, _, err = ag.ChatWithPromptContextDetailed(context.Background(), &testSession{}, "hello", PromptContext{
+		SessionID: "telegram:1",
+		Channel:   "telegram",
+		UserID:    "alice",
+	})
+	if err != nil {
+		t.Fatalf("ChatWithPromptContextDetailed failed: %v", err)
+	}
+	if response != "answer" {
+		t.Fatalf("expected alice to chat again after her turn finished, got %q", response)
+	}
+	if got := ag.turnLimits.InFlight("alice"); got != 0 {
+		t.Fatalf("expected finished turns to release their slot, got %d in flight", got)
+	}
+}
+
 func TestChatWithPromptContextDetailed_IncludesContextPressurePreview(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.Agents.Defaults.Orchestrator = orchestratorLegacy
@@ -1595,6 +1767,98 @@ func TestSessionHistoryUsesSafeWindowWhenAvailable(t *testing.T) {
 	}
 }
 
+func TestBuildMessagesWithPromptSetIncludesPinnedContext(t *testing.T) {
+	workspace := t.TempDir()
+	cb := NewContextBuilderWithMemory(workspace, promptmemory.NewStoreWithBackend(workspace, promptmemory.NewNoopBackend()))
+	cb.SetToolDescriptionsFunc(func() []string { return nil })
+
+	messages := cb.BuildMessagesWithPromptSet(nil, "hello", prompts.ResolvedPromptSet{
+		SystemText: "managed system prompt",
+		Pinned:     []string{"Always answer in English.", "  "},
+	})
+
+	system := messages[0].Content
+	if !strings.Contains(system, "# Pinned Context") || !strings.Contains(system, "- Always answer in English.") {
+		t.Fatalf("expected pinned context section in system prompt, got:\n%s", system)
+	}
+	if strings.Index(system, "# Managed Prompts") > strings.Index(system, "# Pinned Context") {
+		t.Fatalf("expected pinned context after managed prompts, got:\n%s", system)
+	}
+	if !strings.HasSuffix(system, "- Always answer in English.") {
+		t.Fatalf("expected blank pins to be skipped, got:\n%s", system)
+	}
+}
+
+func TestBuildMessagesWithPromptSetPlacesPersonaFirst(t *testing.T) {
+	workspace := t.TempDir()
+	cb := NewContextBuilderWithMemory(workspace, promptmemory.NewStoreWithBackend(workspace, promptmemory.NewNoopBackend()))
+	cb.SetToolDescriptionsFunc(func() []string { return nil })
+
+	messages := cb.BuildMessagesWithPromptSet(nil, "hello", prompts.ResolvedPromptSet{
+		Persona: "  You are Captain Hook. Speak like a pirate.  ",
+		Pinned:  []string{"Always answer in English."},
+	})
+
+	system := messages[0].Content
+	if !strings.HasPrefix(system, "# Persona") || !strings.Contains(system, "You are Captain Hook. Speak like a pirate.") {
+		t.Fatalf("expected persona section first in system prompt, got:\n%s", system)
+	}
+	if strings.Index(system, "# Persona") > strings.Index(system, "# Pinned Context") {
+		t.Fatalf("expected persona ahead of pinned context, got:\n%s", system)
+	}
+
+	messages = cb.BuildMessagesWithPromptSet(nil, "hello", prompts.ResolvedPromptSet{Persona: "   "})
+	if strings.Contains(messages[0].Content, "# Persona") {
+		t.Fatalf("expected blank persona to be skipped, got:\n%s", messages[0].Content)
+	}
+}
+
+func TestChatKeepsPinnedContextWhenHistoryIsTrimmed(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Orchestrator = orchestratorLegacy
+	cfg.Agents.Defaults.Provider = "test-primary"
+	cfg.Agents.Defaults.Model = "test-model"
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Memory.ShortTerm.Enabled = true
+	cfg.Memory.ShortTerm.RawHistoryLimit = 2
+	cfg.Providers = []config.ProviderProfile{{
+		Name:         "test-primary",
+		ProviderKind: failoverTestProviderKind(t, "pins"),
+	}}
+
+	callCount := 0
+	var captured *providers.UnifiedRequest
+	registerFailoverTestProviderWithCapture(t, cfg.Providers[0].ProviderKind, &callCount, "ok", nil, func(req *providers.UnifiedRequest) {
+		captured = req
+	})
+	ag := newFailoverTestAgent(t, cfg)
+
+	sess := &pinnedTestSession{
+		testSession: testSession{messages: []Message{
+			{Role: "user", Content: "the codename is kestrel"},
+			{Role: "assistant", Content: "noted"},
+			{Role: "user", Content: "third"},
+			{Role: "assistant", Content: "fourth"},
+		}},
+		pins: []string{"Project codename is kestrel."},
+	}
+
+	if _, err := ag.Chat(context.Background(), sess, "what is the codename?"); err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if captured == nil {
+		t.Fatal("expected captured provider request")
+	}
+	for _, msg := range captured.Messages[1:] {
+		if strings.Contains(msg.Content, "the codename is kestrel") {
+			t.Fatalf("expected oldest history message to be trimmed, got %#v", captured.Messages)
+		}
+	}
+	if !strings.Contains(captured.Messages[0].Content, "- Project codename is kestrel.") {
+		t.Fatalf("expected pinned context in system prompt, got:\n%s", captured.Messages[0].Content)
+	}
+}
+
 func TestContextBuilderPreprocessorCanBeDisabledViaConfig(t *testing.T) {
 	workspace := t.TempDir()
 	target := filepath.Join(workspace, "README.md")
@@ -1688,6 +1952,136 @@ func TestNewMemoryStoreFromConfig_NoopWhenMemoryDisabled(t *testing.T) {
 	}
 }
 
+func TestCallLLMWithFallback_AppliesSystemPrefixOfResolvedProvider(t *testing.T) {
+	primaryKind := failoverTestProviderKind(t, "primary")
+	fallbackKind := failoverTestProviderKind(t, "fallback")
+
+	var primarySeen, fallbackSeen []providers.UnifiedMessage
+	primaryCalls := 0
+	fallbackCalls := 0
+	registerFailoverTestProviderWithCapture(t, primaryKind, &primaryCalls, "", errors.New("status 429: too many requests"), func(req *providers.UnifiedRequest) {
+		primarySeen = req.Messages
+	})
+	registerFailoverTestProviderWithCapture(t, fallbackKind, &fallbackCalls, "fallback-response", nil, func(req *providers.UnifiedRequest) {
+		fallbackSeen = req.Messages
+	})
+
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Model = "primary-model"
+	cfg.Providers = []config.ProviderProfile{
+		{
+			Name:         "primary",
+			ProviderKind: primaryKind,
+			Models:       []string{"primary-model"},
+			DefaultModel: "primary-model",
+			SystemPrefix: "Respond concisely.",
+		},
+		{
+			Name:         "fallback",
+			ProviderKind: fallbackKind,
+			Models:       []string{"fallback-model"},
+			DefaultModel: "fallback-model",
+		},
+	}
+
+	ag := newFailoverTestAgent(t, cfg)
+	req := &providers.UnifiedRequest{
+		Model: "primary-model",
+		Messages: []providers.UnifiedMessage{
+			{Role: "system", Content: "base prompt"},
+			{Role: "user", Content: "hello"},
+		},
+	}
+	_, providerUsed, _, err := ag.callLLMWithFallback(
+		context.Background(),
+		req,
+		"primary",
+		[]string{"primary", "fallback"},
+		"primary-model",
+		map[string]*providers.Client{},
+	)
+	if err != nil {
+		t.Fatalf("callLLMWithFallback failed: %v", err)
+	}
+	if providerUsed != "fallback" {
+		t.Fatalf("expected fallback provider, got %q", providerUsed)
+	}
+	if len(primarySeen) != 2 || primarySeen[0].Content != "Respond concisely.\n\nbase prompt" {
+		t.Fatalf("expected primary request to carry its prefix, got %+v", primarySeen)
+	}
+	if len(fallbackSeen) != 2 || fallbackSeen[0].Content != "base prompt" {
+		t.Fatalf("expected fallback request without the primary prefix, got %+v", fallbackSeen)
+	}
+	if req.Messages[0].Content != "base prompt" {
+		t.Fatalf("expected shared request to stay unmodified, got %q", req.Messages[0].Content)
+	}
+
+	// A provider prefix is added as its own system message when the request has none.
+	cfg.Providers[1].SystemPrefix = "Answer in English."
+	_, _, _, err = ag.callLLMWithFallback(
+		context.Background(),
+		&providers.UnifiedRequest{Model: "fallback-model", Messages: []providers.UnifiedMessage{{Role: "user", Content: "hello"}}},
+		"fallback",
+		[]string{"fallback"},
+		"fallback-model",
+		map[string]*providers.Client{},
+	)
+	if err != nil {
+		t.Fatalf("callLLMWithFallback failed: %v", err)
+	}
+	if len(fallbackSeen) != 2 || fallbackSeen[0].Role != "system" || fallbackSeen[0].Content != "Answer in English." {
+		t.Fatalf("expected a leading system message with the prefix, got %+v", fallbackSeen)
+	}
+}
+
+func TestCallLLMWithFallback_OmitsToolsWhenProbeFoundNoToolCalling(t *testing.T) {
+	providerKind := failoverTestProviderKind(t, "no-tools")
+
+	var seen *providers.UnifiedRequest
+	calls := 0
+	registerFailoverTestProviderWithCapture(t, providerKind, &calls, "ok", nil, func(req *providers.UnifiedRequest) {
+		seen = req
+	})
+
+	unsupported := false
+	cfg := config.DefaultConfig()
+	cfg.Providers = []config.ProviderProfile{{
+		Name:         "local",
+		ProviderKind: providerKind,
+		Models:       []string{"small-model", "big-model"},
+		DefaultModel: "small-model",
+		Capabilities: &config.ProviderCapabilities{Model: "small-model", ToolCalling: &unsupported},
+	}}
+
+	ag := newFailoverTestAgent(t, cfg)
+	call := func(model string) {
+		t.Helper()
+		req := &providers.UnifiedRequest{
+			Model:      model,
+			Messages:   []providers.UnifiedMessage{{Role: "user", Content: "hello"}},
+			Tools:      []providers.UnifiedTool{{Type: "function", Name: "read_file"}},
+			ToolChoice: "auto",
+		}
+		if _, _, _, err := ag.callLLMWithFallback(context.Background(), req, "local", []string{"local"}, model, map[string]*providers.Client{}); err != nil {
+			t.Fatalf("callLLMWithFallback failed: %v", err)
+		}
+		if len(req.Tools) != 1 {
+			t.Fatalf("expected shared request to keep its tools, got %+v", req.Tools)
+		}
+	}
+
+	call("small-model")
+	if seen == nil || len(seen.Tools) != 0 || seen.ToolChoice != nil {
+		t.Fatalf("expected tools to be omitted for the probed model, got %+v", seen)
+	}
+
+	// The probe result only applies to the model it was taken for.
+	call("big-model")
+	if len(seen.Tools) != 1 {
+		t.Fatalf("expected tools for an unprobed model, got %+v", seen.Tools)
+	}
+}
+
 func TestCallLLMWithFallback_RetriableErrorFallsBackAndMarksCooldown(t *testing.T) {
 	primaryKind := failoverTestProviderKind(t, "primary")
 	fallbackKind := failoverTestProviderKind(t, "fallback")
@@ -1931,6 +2325,100 @@ func TestCallLLMWithFallback_AllProvidersInCooldownReturnsExhaustedError(t *test
 	}
 }
 
+func TestCallLLMWithFallback_StopsAtMaxFallbackAttempts(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Model = "shared-model"
+	cfg.Agents.Defaults.MaxFallbackAttempts = 2
+
+	names := []string{"first", "second", "third"}
+	calls := make([]int, len(names))
+	for i, name := range names {
+		kind := failoverTestProviderKind(t, name+"-capped")
+		registerFailoverTestProvider(t, kind, &calls[i], "", errors.New("status 503: service unavailable"))
+		cfg.Providers = append(cfg.Providers, config.ProviderProfile{
+			Name:         name,
+			ProviderKind: kind,
+			Models:       []string{"shared-model"},
+			DefaultModel: "shared-model",
+		})
+	}
+
+	ag := newFailoverTestAgent(t, cfg)
+	_, _, _, err := ag.callLLMWithFallback(
+		context.Background(),
+		&providers.UnifiedRequest{Model: "shared-model"},
+		"first",
+		names,
+		"shared-model",
+		map[string]*providers.Client{},
+	)
+	if err == nil {
+		t.Fatal("expected fallback exhausted error")
+	}
+	if calls[0] != 1 || calls[1] != 1 || calls[2] != 0 {
+		t.Fatalf("expected only the first two providers to be called, got %v", calls)
+	}
+
+	exhaustedErr, ok := errors.AsType[*providers.FallbackExhaustedError](err)
+	if !ok {
+		t.Fatalf("expected fallback exhausted error, got %T: %v", err, err)
+	}
+	if len(exhaustedErr.Attempts) != 2 || exhaustedErr.MaxAttempts != 2 {
+		t.Fatalf("expected two recorded attempts capped at 2, got %+v", exhaustedErr)
+	}
+	if len(exhaustedErr.Untried) != 1 || exhaustedErr.Untried[0] != "third" {
+		t.Fatalf("expected third provider to be reported as untried, got %v", exhaustedErr.Untried)
+	}
+	if !strings.Contains(err.Error(), "attempted chain: first -> second") || !strings.Contains(err.Error(), "not tried: third") {
+		t.Fatalf("expected error to surface the attempted chain, got %v", err)
+	}
+}
+
+func TestCallLLMWithFallback_LatencyRoutingPrefersFastestHealthyProvider(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Model = "shared-model"
+	cfg.Agents.Defaults.RoutingPolicy = "latency"
+	cfg.Agents.Defaults.CircuitBreakerThreshold = 3
+
+	names := []string{"slow", "fast"}
+	calls := make([]int, len(names))
+	for i, name := range names {
+		kind := failoverTestProviderKind(t, name+"-latency")
+		registerFailoverTestProvider(t, kind, &calls[i], name+"-response", nil)
+		cfg.Providers = append(cfg.Providers, config.ProviderProfile{
+			Name:         name,
+			ProviderKind: kind,
+			Models:       []string{"shared-model"},
+			DefaultModel: "shared-model",
+		})
+	}
+
+	ag := newFailoverTestAgent(t, cfg)
+	tracker := ag.getFailoverCooldown()
+	tracker.RecordLatency("slow", 3*time.Second)
+	tracker.RecordLatency("fast", 200*time.Millisecond)
+	// Below the threshold a failure does not open the circuit.
+	tracker.MarkFailure("fast", providers.FailoverReasonTimeout)
+	if !tracker.IsAvailable("fast") {
+		t.Fatalf("expected fast provider to stay available below the circuit threshold")
+	}
+
+	_, providerUsed, _, err := ag.callLLMWithFallback(
+		context.Background(),
+		&providers.UnifiedRequest{Model: "shared-model"},
+		"slow",
+		names,
+		"shared-model",
+		map[string]*providers.Client{},
+	)
+	if err != nil {
+		t.Fatalf("callLLMWithFallback failed: %v", err)
+	}
+	if providerUsed != "fast" || calls[0] != 0 || calls[1] != 1 {
+		t.Fatalf("expected the faster provider to serve the request, got %q with calls %v", providerUsed, calls)
+	}
+}
+
 func TestChatWithProviderModelDetailed_ReturnsActualRouteOnFailure(t *testing.T) {
 	primaryKind := failoverTestProviderKind(t, "primary")
 	registerFailoverTestProvider(t, primaryKind, new(int), "", errors.New("status 400: invalid request format"))
@@ -2815,9 +3303,10 @@ func (a *failoverTestAdaptor) SetupRequestHeader(req *http.Request, info *provid
 func (a *failoverTestAdaptor) ConvertRequest(unified *providers.UnifiedRequest, info *providers.RelayInfo) ([]byte, error) {
 	if a.onRequest != nil && unified != nil {
 		clone := &providers.UnifiedRequest{
-			Model:       unified.Model,
-			MaxTokens:   unified.MaxTokens,
-			Temperature: unified.Temperature,
+			Model:          unified.Model,
+			MaxTokens:      unified.MaxTokens,
+			Temperature:    unified.Temperature,
+			ResponseFormat: unified.ResponseFormat,
 		}
 		if len(unified.Messages) > 0 {
 			clone.Messages = append([]providers.UnifiedMessage(nil), unified.Messages...)
@@ -3159,6 +3648,15 @@ type testSession struct {
 	messages []Message
 }
 
+type pinnedTestSession struct {
+	testSession
+	pins []string
+}
+
+func (s *pinnedTestSession) GetPins() []string {
+	return s.pins
+}
+
 func (s *testSession) GetMessages() []Message {
 	return s.messages
 }
@@ -3232,3 +3730,66 @@ func TestLoadBootstrapFiles_IgnoresStaticAgentsBootstrap(t *testing.T) {
 		t.Fatalf("expected IDENTITY.md content, got %q", content)
 	}
 }
+
+func TestChatPromptContextOrchestratorOverrideRoutesThroughChosenPath(t *testing.T) {
+	// The configured value is invalid, so only the override can route the turn.
+	ag := newRoutingTestAgent(t, "unknown")
+
+	for _, orchestrator := range []string{orchestratorLegacy, orchestratorBlades} {
+		sess := &testSession{}
+		_, routeResult, err := ag.ChatWithPromptContextDetailed(context.Background(), sess, "hello", PromptContext{
+			Orchestrator: strings.ToUpper(orchestrator),
+		})
+		if err == nil {
+			t.Fatalf("expected chat error")
+		}
+		if !strings.Contains(err.Error(), "no providers configured") {
+			t.Fatalf("expected %s path no providers configured error, got %v", orchestrator, err)
+		}
+		if routeResult.Orchestrator != orchestrator {
+			t.Fatalf("expected route through %s, got %+v", orchestrator, routeResult)
+		}
+	}
+}
+
+func TestChatSessionOrchestratorOverrideRoutesThroughChosenPath(t *testing.T) {
+	ag := newRoutingTestAgent(t, orchestratorBlades)
+
+	sess := &orchestratorTestSession{orchestrator: orchestratorLegacy}
+	_, routeResult, err := ag.ChatWithPromptContextDetailed(context.Background(), sess, "hello", PromptContext{})
+	if err == nil || !strings.Contains(err.Error(), "no providers configured") {
+		t.Fatalf("expected legacy path no providers configured error, got %v", err)
+	}
+	if routeResult.Orchestrator != orchestratorLegacy {
+		t.Fatalf("expected session override to route through legacy, got %+v", routeResult)
+	}
+
+	// A request-level override wins over the session override.
+	_, routeResult, _ = ag.ChatWithPromptContextDetailed(context.Background(), sess, "hello", PromptContext{
+		Orchestrator: orchestratorBlades,
+	})
+	if routeResult.Orchestrator != orchestratorBlades {
+		t.Fatalf("expected request override to route through blades, got %+v", routeResult)
+	}
+}
+
+func TestChatRejectsUnsupportedOrchestratorOverride(t *testing.T) {
+	ag := newRoutingTestAgent(t, orchestratorBlades)
+
+	sess := &testSession{}
+	_, _, err := ag.ChatWithPromptContextDetailed(context.Background(), sess, "hello", PromptContext{
+		Orchestrator: "swarm",
+	})
+	if err == nil || !strings.Contains(err.Error(), "unsupported orchestrator: swarm") {
+		t.Fatalf("expected unsupported orchestrator error, got %v", err)
+	}
+}
+
+type orchestratorTestSession struct {
+	testSession
+	orchestrator string
+}
+
+func (s *orchestratorTestSession) GetOrchestrator() string {
+	return s.orchestrator
+}
@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/approval"
+	"nekobot/pkg/bus"
+)
+
+// defaultApprovalPromptTimeout bounds how long a chat turn waits for an
+// approval decision when approval.prompt_timeout_seconds is unset.
+const defaultApprovalPromptTimeout = 5 * time.Minute
+
+// promptApprovalInChat asks the chat the turn came from whether to run a
+// tool. The request is queued like a manual approval, so the WebUI can
+// decide it too, and the turn waits until someone does or the prompt times
+// out.
+func (a *Agent) promptApprovalInChat(ctx context.Context, req *approval.Request) (bool, error) {
+	channel := ctxStringValue(ctx, promptContextChannelKey)
+	chatID := ctxStringValue(ctx, promptContextSessionKey)
+	if a.events == nil || channel == "" || chatID == "" {
+		// Nobody to ask outside a chat conversation.
+		return true, nil
+	}
+
+	requestID, err := a.approval.EnqueuePrompt(req.ToolName, req.Arguments, req.SessionID, channel, chatID)
+	if err != nil {
+		return false, fmt.Errorf("enqueue approval request: %w", err)
+	}
+	queued, ok := a.approval.GetRequest(requestID)
+	if !ok {
+		return false, fmt.Errorf("approval request %s disappeared", requestID)
+	}
+	if err := a.events.SendOutbound(&bus.Message{
+		ID:        "approval:" + requestID,
+		ChannelID: channel,
+		SessionID: chatID,
+		Type:      bus.MessageTypeText,
+		Content:   approval.PromptText(queued),
+		Data:      map[string]interface{}{approval.DataKeyRequestID: requestID},
+		Timestamp: time.Now(),
+	}); err != nil {
+		_ = a.approval.Deny(requestID, "approval prompt could not be delivered")
+		return false, fmt.Errorf("send approval prompt: %w", err)
+	}
+	a.publishEvent(bus.EventApprovalRequested, req.SessionID,
+		fmt.Sprintf("Tool %s is waiting for approval (request %s).", req.ToolName, requestID),
+		map[string]string{"tool": req.ToolName, "request_id": requestID},
+	)
+
+	timeout := defaultApprovalPromptTimeout
+	if seconds := a.config.Approval.PromptTimeoutSeconds; seconds > 0 {
+		timeout = time.Duration(seconds) * time.Second
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	decision, err := a.approval.Wait(waitCtx, requestID)
+	if err != nil {
+		if denyErr := a.approval.Deny(requestID, "approval prompt timed out"); denyErr != nil {
+			a.logger.Debug("Failed to deny unanswered approval prompt", zap.String("request_id", requestID), zap.Error(denyErr))
+		}
+		return false, nil
+	}
+	return decision == approval.Approved, nil
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/go-kratos/blades"
 	bladesmcp "github.com/go-kratos/blades/contrib/mcp"
@@ -16,6 +15,7 @@ import (
 	"github.com/google/jsonschema-go/jsonschema"
 	"go.uber.org/zap"
 	"nekobot/pkg/config"
+	"nekobot/pkg/mcp"
 	"nekobot/pkg/memory"
 	"nekobot/pkg/prompts"
 	"nekobot/pkg/providers"
@@ -191,13 +191,7 @@ func (p *bladesModelProvider) toUnifiedRequest(req *blades.ModelRequest) (*provi
 		Tools:       tools,
 		MaxTokens:   p.agent.config.Agents.Defaults.MaxTokens,
 		Temperature: p.agent.config.Agents.Defaults.Temperature,
-	}
-
-	if p.agent.config.Agents.Defaults.ExtendedThinking {
-		unifiedReq.Extra = map[string]interface{}{
-			"extended_thinking": true,
-			"thinking_budget":   p.agent.config.Agents.Defaults.ThinkingBudget,
-		}
+		Extra:       p.agent.requestExtra(),
 	}
 
 	return unifiedReq, nil
@@ -242,6 +236,22 @@ func (p *bladesModelProvider) convertMessages(messages []*blades.Message) ([]pro
 				} else {
 					item.Content += "\n" + part.Text
 				}
+			case blades.DataPart:
+				if strings.HasPrefix(string(part.MIMEType), "image/") {
+					item.Parts = append(item.Parts, providers.ContentPart{
+						Type:     providers.ContentPartImage,
+						MIMEType: string(part.MIMEType),
+						Data:     part.Bytes,
+					})
+				}
+			case blades.FilePart:
+				if strings.HasPrefix(string(part.MIMEType), "image/") {
+					item.Parts = append(item.Parts, providers.ContentPart{
+						Type:     providers.ContentPartImage,
+						MIMEType: string(part.MIMEType),
+						URL:      part.URI,
+					})
+				}
 			case blades.ToolPart:
 				if item.Role != string(blades.RoleAssistant) {
 					continue
@@ -436,47 +446,6 @@ func (r *multiToolResolver) appendResolver(resolver bladestools.Resolver) {
 	r.resolvers = append(r.resolvers, resolver)
 }
 
-func parseMCPTransport(raw string) (bladesmcp.TransportType, error) {
-	transport := strings.TrimSpace(strings.ToLower(raw))
-	if transport == "" {
-		transport = string(bladesmcp.TransportStdio)
-	}
-	if transport == "sse" {
-		transport = string(bladesmcp.TransportHTTP)
-	}
-
-	switch bladesmcp.TransportType(transport) {
-	case bladesmcp.TransportStdio, bladesmcp.TransportHTTP, bladesmcp.TransportWebSocket:
-		return bladesmcp.TransportType(transport), nil
-	default:
-		return "", fmt.Errorf("unsupported transport: %s", raw)
-	}
-}
-
-func parseMCPTimeout(raw string) (time.Duration, error) {
-	trimmed := strings.TrimSpace(raw)
-	if trimmed == "" {
-		return 0, nil
-	}
-
-	d, err := time.ParseDuration(trimmed)
-	if err != nil {
-		return 0, fmt.Errorf("parse timeout duration: %w", err)
-	}
-	if d <= 0 {
-		return 0, fmt.Errorf("timeout duration must be greater than 0")
-	}
-	return d, nil
-}
-
-func mcpServerName(cfg config.MCPServerConfig, idx int) string {
-	name := strings.TrimSpace(cfg.Name)
-	if name != "" {
-		return name
-	}
-	return fmt.Sprintf("index-%d", idx)
-}
-
 func toMCPClientConfigs(serverConfigs []config.MCPServerConfig) ([]bladesmcp.ClientConfig, error) {
 	if len(serverConfigs) == 0 {
 		return nil, nil
@@ -484,33 +453,17 @@ func toMCPClientConfigs(serverConfigs []config.MCPServerConfig) ([]bladesmcp.Cli
 
 	res := make([]bladesmcp.ClientConfig, 0, len(serverConfigs))
 	for i, server := range serverConfigs {
-		transport, err := parseMCPTransport(server.Transport)
-		if err != nil {
-			return nil, fmt.Errorf("mcp server %s transport: %w", mcpServerName(server, i), err)
-		}
-
-		timeout, err := parseMCPTimeout(server.Timeout)
+		clientConfig, err := mcp.ClientConfig(server)
 		if err != nil {
-			return nil, fmt.Errorf("mcp server %s timeout: %w", mcpServerName(server, i), err)
+			return nil, fmt.Errorf("mcp server %s %w", mcp.ServerName(server, i), err)
 		}
-
-		res = append(res, bladesmcp.ClientConfig{
-			Name:      strings.TrimSpace(server.Name),
-			Transport: transport,
-			Command:   strings.TrimSpace(server.Command),
-			Args:      server.Args,
-			Env:       server.Env,
-			WorkDir:   strings.TrimSpace(server.WorkDir),
-			Endpoint:  strings.TrimSpace(server.Endpoint),
-			Headers:   server.Headers,
-			Timeout:   timeout,
-		})
+		res = append(res, clientConfig)
 	}
 
 	return res, nil
 }
 
-func (a *Agent) buildBladesToolsResolverWithMCP(serverConfigs []config.MCPServerConfig) (bladestools.Resolver, *bladesmcp.ToolsResolver, error) {
+func (a *Agent) buildBaseToolsResolver() (*multiToolResolver, error) {
 	resolver := newBladesToolsResolver()
 	if a.semanticMemory != nil && a.semanticMemory.IsEnabled() {
 		memoryTool, err := bladesmemory.NewMemoryTool(memory.NewBladesMemoryStoreAdapter(
@@ -522,31 +475,44 @@ func (a *Agent) buildBladesToolsResolverWithMCP(serverConfigs []config.MCPServer
 			},
 		))
 		if err != nil {
-			return nil, nil, fmt.Errorf("create blades memory tool: %w", err)
+			return nil, fmt.Errorf("create blades memory tool: %w", err)
 		}
 		resolver.appendResolver(&staticToolResolver{tools: []bladestools.Tool{memoryTool}})
 	}
 	resolver.appendResolver(newBladesToolResolver(a, a.tools))
+	return resolver, nil
+}
 
-	mcpConfigs, err := toMCPClientConfigs(serverConfigs)
+func (a *Agent) buildBladesToolsResolverWithMCP(serverConfigs []config.MCPServerConfig) (bladestools.Resolver, *mcpToolsResolver, error) {
+	resolver, err := a.buildBaseToolsResolver()
 	if err != nil {
 		return nil, nil, err
 	}
-	if len(mcpConfigs) == 0 {
-		return resolver, nil, nil
-	}
 
-	mcpResolver, err := bladesmcp.NewToolsResolver(mcpConfigs...)
+	mcpResolver, err := newMCPToolsResolver(serverConfigs, a.logger)
 	if err != nil {
-		return nil, nil, fmt.Errorf("create mcp tools resolver: %w", err)
+		return nil, nil, err
+	}
+	if mcpResolver == nil {
+		return resolver, nil, nil
 	}
 
-	resolver.appendResolver(mcpResolver)
+	resolver.appendResolver(&timeoutToolResolver{resolver: mcpResolver, agent: a, isMCP: true})
 	return resolver, mcpResolver, nil
 }
 
-func (a *Agent) buildBladesToolsResolver() (bladestools.Resolver, *bladesmcp.ToolsResolver, error) {
-	return a.buildBladesToolsResolverWithMCP(a.config.Agents.Defaults.MCPServers)
+// buildBladesToolsResolver serves the default MCP servers from the shared
+// manager connections when one is running, instead of connecting per chat.
+func (a *Agent) buildBladesToolsResolver() (bladestools.Resolver, *mcpToolsResolver, error) {
+	if a.mcp == nil {
+		return a.buildBladesToolsResolverWithMCP(a.config.Agents.Defaults.MCPServers)
+	}
+	resolver, err := a.buildBaseToolsResolver()
+	if err != nil {
+		return nil, nil, err
+	}
+	resolver.appendResolver(&timeoutToolResolver{resolver: a.mcp, agent: a, isMCP: true})
+	return resolver, nil, nil
 }
 
 func schemaToMap(s *jsonschema.Schema) map[string]interface{} {
@@ -610,6 +576,9 @@ func (a *Agent) chatWithBladesOrchestrator(
 	if err != nil {
 		return "", routeResult, err
 	}
+	resolvedPrompts.Pinned = sessionPins(sess)
+	resolvedPrompts.Persona = sessionPersona(sess)
+	resolvedPrompts.AgentProfile = agentProfilePrompt(ctx)
 	routeResult = a.enrichChatRouteResultWithContextPreview(routeResult, resolvedPrompts, promptCtx, userMessage)
 	modelProvider := newBladesModelProvider(
 		a,
@@ -625,9 +594,9 @@ func (a *Agent) chatWithBladesOrchestrator(
 	agentOpts := []blades.AgentOption{
 		blades.WithModel(modelProvider),
 		blades.WithInstruction(instruction),
-		blades.WithToolsResolver(toolResolver),
-		blades.WithMiddleware(bladesmiddleware.ConversationBuffered(a.maxIterations * 4)),
-		blades.WithMaxIterations(a.maxIterations),
+		blades.WithToolsResolver(&allowedToolResolver{resolver: toolResolver}),
+		blades.WithMiddleware(bladesmiddleware.ConversationBuffered(a.iterationLimit(ctx) * 4)),
+		blades.WithMaxIterations(a.iterationLimit(ctx)),
 	}
 
 	// Wire blade-native skills if skills manager is available.
@@ -662,9 +631,13 @@ func (a *Agent) chatWithBladesOrchestrator(
 	}
 
 	runner := blades.NewRunner(agentInstance)
+	userParts := append(
+		[]any{prompts.ComposeUserMessage(resolvedPrompts.UserText, userMessage)},
+		bladesImageParts(imageParts(promptCtx.Attachments))...,
+	)
 	output, err := runner.Run(
 		ctx,
-		blades.UserMessage(prompts.ComposeUserMessage(resolvedPrompts.UserText, userMessage)),
+		blades.UserMessage(userParts...),
 		blades.WithSession(bladesSession),
 	)
 	if err != nil {
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"unicode/utf8"
@@ -63,7 +64,7 @@ func forceCompressMessages(messages []providers.UnifiedMessage) []providers.Unif
 
 // isContextLimitError checks if an error is related to context window/token limits.
 func isContextLimitError(err error) bool {
-	if err == nil {
+	if err == nil || errors.Is(err, errDelegationBudgetExhausted) {
 		return false
 	}
 	msg := strings.ToLower(err.Error())
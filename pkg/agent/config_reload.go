@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/moderation"
+	"nekobot/pkg/process"
+	"nekobot/pkg/sandbox"
+	"nekobot/pkg/tools"
+)
+
+// ApplyConfig refreshes the state the agent derives from config once a
+// reload has copied new values into its *config.Config. sections are the
+// JSON names of the changed config sections. Settings read on every turn,
+// such as providers and turn limits, need no refresh.
+func (a *Agent) ApplyConfig(sections []string) {
+	if a == nil || a.config == nil {
+		return
+	}
+	if slices.Contains(sections, "agents") {
+		a.applyAgentsConfig()
+	}
+	if slices.Contains(sections, "moderation") {
+		a.applyModerationConfig()
+	}
+	if slices.Contains(sections, "tools") {
+		a.applyToolsConfig()
+	}
+}
+
+func (a *Agent) applyAgentsConfig() {
+	a.configMu.Lock()
+	a.maxIterations = a.config.Agents.Defaults.MaxToolIterations
+	a.definition = AgentDefinitionFromRuntimeConfig(a.config)
+	a.configMu.Unlock()
+
+	if mode, err := a.resolveOrchestrator(); err == nil && a.context != nil {
+		a.context.SetOrchestratorMode(mode)
+	}
+	if a.skillsManager != nil {
+		a.skillsManager.SetIndex(a.config.Agents.Defaults.SkillsIndexURL, a.config.Agents.Defaults.SkillsIndexOnly)
+	}
+	a.logger.Info("Applied agent config change",
+		zap.Int("max_tool_iterations", a.config.Agents.Defaults.MaxToolIterations))
+}
+
+func (a *Agent) applyModerationConfig() {
+	filter, err := moderation.New(a.config.Moderation, a.logger)
+	if err != nil {
+		a.logger.Warn("Keeping previous moderation settings", zap.Error(err))
+		return
+	}
+	a.configMu.Lock()
+	a.moderation = filter
+	a.configMu.Unlock()
+	a.logger.Info("Applied moderation config change", zap.Bool("enabled", filter.Enabled()))
+}
+
+// applyToolsConfig rebuilds the tools whose behavior is fixed at
+// construction. The per-session sandbox pool is kept as is; changing its
+// sizing still needs a restart.
+func (a *Agent) applyToolsConfig() {
+	if a.tools == nil {
+		return
+	}
+	if a.filePolicy != nil {
+		a.filePolicy.SetOptions(filePolicyOptions(a.config.Tools.Files))
+	}
+	a.tools.Replace(newExecToolFromConfig(a.config, a.sandbox, a.processMgr))
+
+	if sqlQuery := newSQLQueryToolFromConfig(a.config); sqlQuery != nil {
+		a.tools.Replace(sqlQuery)
+	} else {
+		a.tools.Unregister("sql_query")
+	}
+	if webSearch := newWebSearchToolFromConfig(a.config); webSearch != nil {
+		a.tools.Replace(webSearch)
+	} else {
+		a.tools.Unregister("web_search")
+	}
+	a.logger.Info("Applied tools config change")
+}
+
+func (a *Agent) maxToolIterations() int {
+	a.configMu.RLock()
+	defer a.configMu.RUnlock()
+	return a.maxIterations
+}
+
+func (a *Agent) moderationFilter() *moderation.Filter {
+	a.configMu.RLock()
+	defer a.configMu.RUnlock()
+	return a.moderation
+}
+
+func sandboxConfigFromConfig(cfg *config.Config) tools.DockerSandboxConfig {
+	sb := cfg.Tools.Exec.Sandbox
+	return tools.DockerSandboxConfig{
+		Enabled:     sb.Enabled,
+		Image:       sb.Image,
+		NetworkMode: sb.NetworkMode,
+		Mounts:      sb.Mounts,
+		Timeout:     time.Duration(sb.Timeout) * time.Second,
+		AutoCleanup: sb.AutoCleanup,
+		Runtime:     sb.Runtime,
+		OCIRuntime:  sb.OCIRuntime,
+		Limits: sandbox.Limits{
+			CPUs:     sb.CPUs,
+			MemoryMB: sb.MemoryMB,
+			Pids:     sb.PidsLimit,
+		},
+		PoolSize:      sb.PoolSize,
+		MaxContainers: sb.MaxContainers,
+		IdleTimeout:   time.Duration(sb.IdleTimeoutSeconds) * time.Second,
+	}
+}
+
+func newExecToolFromConfig(cfg *config.Config, pool *sandbox.Pool, processMgr *process.Manager) *tools.ExecTool {
+	return tools.NewExecTool(cfg.WorkspacePath(), cfg.Agents.Defaults.RestrictToWorkspace, tools.ExecConfig{
+		Timeout: time.Duration(cfg.Tools.Exec.TimeoutSeconds) * time.Second,
+		Sandbox: sandboxConfigFromConfig(cfg),
+		Pool:    pool,
+	}, processMgr)
+}
+
+// newSQLQueryToolFromConfig returns nil when no databases are configured.
+func newSQLQueryToolFromConfig(cfg *config.Config) *tools.SQLQueryTool {
+	if len(cfg.Tools.Databases) == 0 {
+		return nil
+	}
+	connections := make([]tools.SQLConnection, 0, len(cfg.Tools.Databases))
+	for _, db := range cfg.Tools.Databases {
+		connections = append(connections, tools.SQLConnection{
+			Name:     db.Name,
+			Type:     db.NormalizedType(),
+			DSN:      db.DSN,
+			ReadOnly: db.ReadOnly,
+			MaxRows:  db.MaxRows,
+			Timeout:  time.Duration(db.TimeoutSeconds) * time.Second,
+		})
+	}
+	return tools.NewSQLQueryTool(connections)
+}
+
+// newWebSearchToolFromConfig returns nil when no search provider is usable.
+func newWebSearchToolFromConfig(cfg *config.Config) *tools.WebSearchTool {
+	return tools.NewWebSearchTool(tools.WebSearchToolOptions{
+		BraveAPIKey:          cfg.Tools.Web.Search.GetBraveAPIKey(),
+		BraveMaxResults:      cfg.Tools.Web.Search.MaxResults,
+		DuckDuckGoEnabled:    cfg.Tools.Web.Search.DuckDuckGoEnabled,
+		DuckDuckGoMaxResults: cfg.Tools.Web.Search.DuckDuckGoMaxResults,
+	})
+}
@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+func TestAgentApplyConfigRefreshesLimitsAndTools(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Agents.Defaults.MaxToolIterations = 5
+
+	logCfg := logger.DefaultConfig()
+	logCfg.OutputPath = ""
+	logCfg.Development = true
+	log, err := logger.New(logCfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	ag, err := New(cfg, log, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, ok := ag.tools.Get("sql_query"); ok {
+		t.Fatal("expected no sql_query tool without databases")
+	}
+
+	cfg.Agents.Defaults.MaxToolIterations = 2
+	cfg.Tools.Databases = []config.DatabaseToolConfig{{Name: "local", Type: "sqlite", DSN: ":memory:"}}
+	ag.ApplyConfig([]string{"agents", "tools"})
+
+	if got := ag.maxToolIterations(); got != 2 {
+		t.Fatalf("expected max tool iterations 2 after reload, got %d", got)
+	}
+	if _, ok := ag.tools.Get("sql_query"); !ok {
+		t.Fatal("expected sql_query to be registered after databases were added")
+	}
+
+	cfg.Tools.Databases = nil
+	ag.ApplyConfig([]string{"tools"})
+	if _, ok := ag.tools.Get("sql_query"); ok {
+		t.Fatal("expected sql_query to be removed with its databases")
+	}
+}
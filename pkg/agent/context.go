@@ -39,11 +39,14 @@ type ContextBuilder struct {
 
 	// Tool registry reference (set after creation)
 	getToolDescriptions func() []string
+	toolBudget          toolPromptBudget
 
 	// Skills manager reference (set after creation)
 	skillsManager *skills.Manager
 
-	// Orchestrator mode affects how skills are rendered.
+	// Orchestrator mode affects how skills are rendered. It can change
+	// when the config is reloaded.
+	modeMu           sync.RWMutex
 	orchestratorMode string
 
 	// Preprocessor for @file and @dir mentions
@@ -104,7 +107,9 @@ func (cb *ContextBuilder) SetSkillsManager(sm *skills.Manager) {
 // SetOrchestratorMode sets the orchestrator mode for context building.
 // This affects how skills are rendered in the system prompt.
 func (cb *ContextBuilder) SetOrchestratorMode(mode string) {
+	cb.modeMu.Lock()
 	cb.orchestratorMode = mode
+	cb.modeMu.Unlock()
 }
 
 // SetPreprocessorConfig configures the preprocessor for @file and @dir mentions.
@@ -192,6 +197,7 @@ func (cb *ContextBuilder) buildToolsSection() string {
 		return ""
 	}
 	sort.Strings(descriptions)
+	descriptions, hidden := cb.selectToolDescriptions(descriptions)
 
 	var sb strings.Builder
 	sb.WriteString("## Available Tools\n\n")
@@ -202,6 +208,10 @@ func (cb *ContextBuilder) buildToolsSection() string {
 		sb.WriteString(desc)
 		sb.WriteString("\n\n")
 	}
+	if hidden > 0 {
+		sb.WriteString(hiddenToolsNote(hidden))
+		sb.WriteString("\n\n")
+	}
 
 	return sb.String()
 }
@@ -215,7 +225,8 @@ func (cb *ContextBuilder) currentToolSignature() string {
 		return ""
 	}
 	sort.Strings(descriptions)
-	return strings.Join(descriptions, "\n")
+	descriptions, hidden := cb.selectToolDescriptions(descriptions)
+	return strings.Join(descriptions, "\n") + fmt.Sprintf("\nhidden=%d", hidden)
 }
 
 // buildSkillsSection creates the skills section of the system prompt.
@@ -226,12 +237,15 @@ func (cb *ContextBuilder) buildSkillsSection() string {
 
 	// For blades orchestrator: only inject always-on skills inline.
 	// Regular skills are discovered through blade's list_skills meta-tool.
-	if cb.orchestratorMode == orchestratorBlades {
+	cb.modeMu.RLock()
+	mode := cb.orchestratorMode
+	cb.modeMu.RUnlock()
+	if mode == orchestratorBlades {
 		alwaysInstructions := cb.skillsManager.GetAlwaysInstructions()
 		if alwaysInstructions == "" {
 			return ""
 		}
-		return alwaysInstructions + "\n\nAdditional skills are available via the `list_skills` tool."
+		return alwaysInstructions + "\n\nAdditional skills are available via the `list_skills` tool; installed skills via the `skill` tool."
 	}
 
 	// Legacy orchestrator: full skills section.
@@ -357,16 +371,49 @@ func (cb *ContextBuilder) BuildSystemPrompt() string {
 }
 
 // BuildSystemPromptWithInjected appends resolved system prompts to the base system prompt.
+// A session persona and the agent profile prompt are rendered first so they
+// frame the default prompt, and pinned session context is rendered last so it
+// is never dropped with history.
 func (cb *ContextBuilder) BuildSystemPromptWithInjected(extra prompts.ResolvedPromptSet) string {
-	base := cb.BuildSystemPrompt()
-	injected := strings.TrimSpace(extra.SystemText)
-	if injected == "" {
-		return base
+	parts := make([]string, 0, 5)
+	if persona := strings.TrimSpace(extra.Persona); persona != "" {
+		parts = append(parts, "# Persona\n\nFor this conversation, take on the following persona. It overrides the default identity and tone below:\n\n"+persona)
+	}
+	if profile := strings.TrimSpace(extra.AgentProfile); profile != "" {
+		parts = append(parts, "# Agent Profile\n\n"+profile)
+	}
+	if base := cb.BuildSystemPrompt(); strings.TrimSpace(base) != "" {
+		parts = append(parts, base)
 	}
-	if strings.TrimSpace(base) == "" {
-		return injected
+	if injected := strings.TrimSpace(extra.SystemText); injected != "" {
+		if len(parts) == 0 {
+			parts = append(parts, injected)
+		} else {
+			parts = append(parts, "# Managed Prompts\n\n"+injected)
+		}
+	}
+	if pinned := buildPinnedContextSection(extra.Pinned); pinned != "" {
+		parts = append(parts, pinned)
+	}
+	return strings.Join(parts, "\n\n---\n\n")
+}
+
+// buildPinnedContextSection renders session pins as a dedicated prompt section.
+func buildPinnedContextSection(pins []string) string {
+	var sb strings.Builder
+	for _, pin := range pins {
+		pin = strings.TrimSpace(pin)
+		if pin == "" {
+			continue
+		}
+		sb.WriteString("- ")
+		sb.WriteString(pin)
+		sb.WriteString("\n")
+	}
+	if sb.Len() == 0 {
+		return ""
 	}
-	return base + "\n\n---\n\n# Managed Prompts\n\n" + injected
+	return "# Pinned Context\n\nThe user pinned these notes for this conversation. Treat them as standing instructions and facts:\n\n" + strings.TrimRight(sb.String(), "\n")
 }
 
 func (cb *ContextBuilder) buildStaticPromptBlock() string {
@@ -0,0 +1,140 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/providers"
+	"nekobot/pkg/tools"
+)
+
+// errDelegationBudgetExhausted stops a child agent that spent its token budget.
+var errDelegationBudgetExhausted = errors.New("child agent token budget exhausted")
+
+// delegationScope bounds a child agent started by spawn_agent. Scopes nest, so
+// a grandchild is limited by every ancestor's tools and token budget.
+type delegationScope struct {
+	parent        *delegationScope
+	depth         int
+	maxDepth      int
+	tools         []string
+	maxIterations int
+	maxTokens     int
+
+	mu    sync.Mutex
+	usage tools.DelegationResult
+}
+
+// Delegate runs a child agent for the spawn_agent tool. The child starts from
+// an empty conversation and stops at its iteration or token budget.
+func (a *Agent) Delegate(ctx context.Context, task tools.AgentDelegation) (tools.DelegationResult, error) {
+	for _, name := range task.Tools {
+		if _, ok := a.tools.Get(name); !ok {
+			return tools.DelegationResult{}, fmt.Errorf("unknown tool %q", name)
+		}
+	}
+	cfg := a.config.Tools.SpawnAgent
+	scope := &delegationScope{
+		parent:        delegationScopeFromContext(ctx),
+		depth:         task.Depth,
+		maxDepth:      cfg.MaxDepth,
+		tools:         task.Tools,
+		maxIterations: task.MaxIterations,
+		maxTokens:     cfg.MaxTokens,
+	}
+
+	childCtx := context.WithValue(ctx, promptContextDelegationKey, scope)
+	childCtx = withToolProgress(withStream(childCtx, nil), nil)
+	sess := &subagentSession{messages: make([]Message, 0, 8)}
+	answer, err := a.ChatWithPromptContext(childCtx, sess, task.Task, PromptContext{
+		UserID: ctxStringValue(ctx, promptContextUserKey),
+	})
+
+	result := scope.result()
+	result.Answer = answer
+	a.logger.Info("Child agent finished",
+		zap.Int("depth", task.Depth),
+		zap.Int("llm_calls", result.LLMCalls),
+		zap.Int("total_tokens", result.TotalTokens),
+		zap.Error(err),
+	)
+	return result, err
+}
+
+func delegationScopeFromContext(ctx context.Context) *delegationScope {
+	if ctx == nil {
+		return nil
+	}
+	scope, _ := ctx.Value(promptContextDelegationKey).(*delegationScope)
+	return scope
+}
+
+// delegationAllowsTool reports whether a child agent may call a tool. Children
+// never get the background spawn tool, which would escape their budgets, nor
+// spawn_agent once they reach the depth limit.
+func delegationAllowsTool(ctx context.Context, toolName string) bool {
+	scope := delegationScopeFromContext(ctx)
+	if scope == nil {
+		return true
+	}
+	name := strings.TrimSpace(toolName)
+	if name == "spawn" || (name == tools.SpawnAgentToolName && scope.depth >= scope.maxDepth) {
+		return false
+	}
+	for current := scope; current != nil; current = current.parent {
+		if len(current.tools) > 0 && !slices.Contains(current.tools, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// iterationLimit returns the tool iteration budget for a turn.
+func (a *Agent) iterationLimit(ctx context.Context) int {
+	limit := a.maxToolIterations()
+	if scope := delegationScopeFromContext(ctx); scope != nil && scope.maxIterations > 0 && scope.maxIterations < limit {
+		return scope.maxIterations
+	}
+	return limit
+}
+
+// checkDelegationBudget fails once a child agent, or any of its ancestors,
+// has spent its token budget.
+func checkDelegationBudget(ctx context.Context) error {
+	for scope := delegationScopeFromContext(ctx); scope != nil; scope = scope.parent {
+		scope.mu.Lock()
+		spent := scope.usage.TotalTokens
+		scope.mu.Unlock()
+		if scope.maxTokens > 0 && spent >= scope.maxTokens {
+			return fmt.Errorf("%w (%d of %d tokens)", errDelegationBudgetExhausted, spent, scope.maxTokens)
+		}
+	}
+	return nil
+}
+
+// recordDelegationUsage charges one LLM call to the child agent and every
+// ancestor scope.
+func recordDelegationUsage(ctx context.Context, usageInfo *providers.UnifiedUsage) {
+	for scope := delegationScopeFromContext(ctx); scope != nil; scope = scope.parent {
+		scope.mu.Lock()
+		scope.usage.LLMCalls++
+		if usageInfo != nil {
+			scope.usage.PromptTokens += usageInfo.PromptTokens
+			scope.usage.CompletionTokens += usageInfo.CompletionTokens
+			scope.usage.TotalTokens += usageInfo.TotalTokens
+		}
+		scope.mu.Unlock()
+	}
+}
+
+func (s *delegationScope) result() tools.DelegationResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.usage
+}
@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/providers"
+	"nekobot/pkg/tools"
+)
+
+func TestDelegateRunsChildWithToolSubsetAndAccountsTokens(t *testing.T) {
+	providerKind := failoverTestProviderKind(t, "child")
+	callCount := 0
+	var toolLists [][]string
+	registerFailoverTestProviderWithResponses(t, providerKind, &callCount, []*providers.UnifiedResponse{
+		{
+			ToolCalls:    []providers.UnifiedToolCall{{ID: "call-1", Name: "stub_tool", Arguments: map[string]interface{}{}}},
+			FinishReason: "tool_calls",
+			Usage:        &providers.UnifiedUsage{PromptTokens: 80, CompletionTokens: 20, TotalTokens: 100},
+		},
+		{
+			Content:      "child done",
+			FinishReason: "stop",
+			Usage:        &providers.UnifiedUsage{PromptTokens: 120, CompletionTokens: 30, TotalTokens: 150},
+		},
+	}, func(req *providers.UnifiedRequest) {
+		names := make([]string, 0, len(req.Tools))
+		for _, tool := range req.Tools {
+			names = append(names, tool.Name)
+		}
+		toolLists = append(toolLists, names)
+	})
+
+	ag := newDelegationTestAgent(t, providerKind)
+	ag.config.Tools.SpawnAgent.MaxDepth = 1
+	stubTool := &toolExecutionResultStubTool{name: "stub_tool", description: "stub tool"}
+	ag.tools.MustRegister(stubTool)
+	ag.tools.MustRegister(&toolExecutionResultStubTool{name: "other_tool", description: "other tool"})
+	ag.tools.MustRegister(tools.NewSpawnAgentTool(ag, tools.SpawnAgentOptions{MaxDepth: 1, MaxIterations: 3}))
+
+	ctx := tools.WithDelegationDepth(context.Background(), 1)
+	result, err := ag.Delegate(ctx, tools.AgentDelegation{
+		Task:          "inspect things",
+		Tools:         []string{"stub_tool", tools.SpawnAgentToolName},
+		MaxIterations: 3,
+		Depth:         1,
+	})
+	if err != nil {
+		t.Fatalf("Delegate failed: %v", err)
+	}
+	if result.Answer != "child done" || result.LLMCalls != 2 || result.TotalTokens != 250 || result.PromptTokens != 200 {
+		t.Fatalf("unexpected delegation result %+v", result)
+	}
+	if stubTool.callCount() != 1 {
+		t.Fatalf("expected the child to call stub_tool once, got %d", stubTool.callCount())
+	}
+	for _, names := range toolLists {
+		if len(names) != 1 || names[0] != "stub_tool" {
+			t.Fatalf("expected the child to see only stub_tool at the depth limit, got %v", names)
+		}
+	}
+
+	if _, err := ag.Delegate(ctx, tools.AgentDelegation{Task: "x", Tools: []string{"missing"}, Depth: 1}); err == nil {
+		t.Fatal("expected an unknown tool to be rejected")
+	}
+}
+
+func TestDelegateStopsAtTokenBudget(t *testing.T) {
+	providerKind := failoverTestProviderKind(t, "budget")
+	callCount := 0
+	registerFailoverTestProviderWithResponses(t, providerKind, &callCount, []*providers.UnifiedResponse{{
+		ToolCalls:    []providers.UnifiedToolCall{{ID: "call-1", Name: "stub_tool", Arguments: map[string]interface{}{}}},
+		FinishReason: "tool_calls",
+		Usage:        &providers.UnifiedUsage{TotalTokens: 600},
+	}}, nil)
+
+	ag := newDelegationTestAgent(t, providerKind)
+	ag.config.Tools.SpawnAgent.MaxTokens = 1000
+	ag.tools.MustRegister(&toolExecutionResultStubTool{name: "stub_tool", description: "stub tool"})
+
+	result, err := ag.Delegate(context.Background(), tools.AgentDelegation{Task: "loop forever", MaxIterations: 10, Depth: 1})
+	if err == nil || !strings.Contains(err.Error(), "token budget exhausted") {
+		t.Fatalf("expected token budget error, got %v", err)
+	}
+	if callCount != 2 || result.TotalTokens != 1200 {
+		t.Fatalf("expected the child to stop after exceeding its budget, got calls=%d result=%+v", callCount, result)
+	}
+}
+
+func newDelegationTestAgent(t *testing.T, providerKind string) *Agent {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Orchestrator = orchestratorLegacy
+	cfg.Agents.Defaults.Provider = "primary"
+	cfg.Agents.Defaults.Model = "test-model"
+	cfg.Providers = []config.ProviderProfile{{Name: "primary", ProviderKind: providerKind}}
+
+	ag := newFailoverTestAgent(t, cfg)
+	ag.maxIterations = 10
+	return ag
+}
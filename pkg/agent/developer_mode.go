@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+
+	"nekobot/pkg/providers"
+)
+
+const redactedSecret = "[REDACTED]"
+
+var developerSecretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)((?:api[_-]?key|apikey|secret|token|password|passwd)["']?\s*[:=]\s*["']?)[^\s"',]{8,}`),
+	regexp.MustCompile(`(?i)(bearer\s+)[a-z0-9._\-]{16,}`),
+	regexp.MustCompile(`()\bsk-[A-Za-z0-9_\-]{16,}`),
+}
+
+// developerModeSession is implemented by sessions that can opt into developer mode.
+type developerModeSession interface {
+	GetDeveloperMode() bool
+}
+
+// TurnDebug is the developer-mode payload of one turn.
+type TurnDebug struct {
+	// Requests lists every LLM request of the turn in send order, including
+	// failed fallback attempts and tool-loop iterations.
+	Requests []DebugRequest
+}
+
+// DebugRequest is one request as sent to a provider, with secrets redacted.
+type DebugRequest struct {
+	Provider string
+	Model    string
+	Request  providers.UnifiedRequest
+}
+
+// developerTrace collects the requests of a turn run in developer mode.
+type developerTrace struct {
+	mu       sync.Mutex
+	requests []DebugRequest
+}
+
+func (t *developerTrace) record(req DebugRequest) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests = append(t.requests, req)
+}
+
+func (t *developerTrace) snapshot() *TurnDebug {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &TurnDebug{Requests: append([]DebugRequest(nil), t.requests...)}
+}
+
+// sessionDeveloperMode reports whether sess has developer mode switched on.
+func sessionDeveloperMode(sess SessionInterface) bool {
+	scoped, ok := sess.(developerModeSession)
+	return ok && scoped.GetDeveloperMode()
+}
+
+// traceDeveloperRequest records req for the turn's developer trace, if any.
+func (a *Agent) traceDeveloperRequest(ctx context.Context, providerName string, req *providers.UnifiedRequest) {
+	trace, ok := ctx.Value(promptContextDeveloperTraceKey).(*developerTrace)
+	if !ok || trace == nil {
+		return
+	}
+
+	var secrets []string
+	if providerCfg := a.config.GetProviderConfig(providerName); providerCfg != nil {
+		if key := strings.TrimSpace(providerCfg.APIKey); key != "" {
+			secrets = append(secrets, key)
+		}
+	}
+
+	redacted := *req
+	redacted.Extra = nil
+	redacted.User = redactSecrets(req.User, secrets)
+	redacted.Messages = make([]providers.UnifiedMessage, len(req.Messages))
+	for i, msg := range req.Messages {
+		msg.Content = redactSecrets(msg.Content, secrets)
+		msg.Metadata = nil
+		if len(msg.ToolCalls) > 0 {
+			calls := make([]providers.UnifiedToolCall, len(msg.ToolCalls))
+			for j, call := range msg.ToolCalls {
+				call.Arguments, _ = redactValue(call.Arguments, secrets).(map[string]interface{})
+				calls[j] = call
+			}
+			msg.ToolCalls = calls
+		}
+		redacted.Messages[i] = msg
+	}
+
+	trace.record(DebugRequest{
+		Provider: providerName,
+		Model:    req.Model,
+		Request:  redacted,
+	})
+}
+
+// redactSecrets masks known secrets and common credential shapes in text.
+func redactSecrets(text string, secrets []string) string {
+	for _, secret := range secrets {
+		text = strings.ReplaceAll(text, secret, redactedSecret)
+	}
+	for _, pattern := range developerSecretPatterns {
+		text = pattern.ReplaceAllString(text, "${1}"+redactedSecret)
+	}
+	return text
+}
+
+func redactValue(value interface{}, secrets []string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return redactSecrets(v, secrets)
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = redactValue(item, secrets)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = redactValue(item, secrets)
+		}
+		return out
+	default:
+		return value
+	}
+}
@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+)
+
+type developerModeTestSession struct {
+	testSession
+	developerMode bool
+}
+
+func (s *developerModeTestSession) GetDeveloperMode() bool {
+	return s.developerMode
+}
+
+func newDeveloperModeTestAgent(t *testing.T) *Agent {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Orchestrator = orchestratorLegacy
+	cfg.Agents.Defaults.Provider = "test-primary"
+	cfg.Agents.Defaults.Model = "gpt-5.4"
+	cfg.Providers = []config.ProviderProfile{
+		{
+			Name:         "test-primary",
+			ProviderKind: failoverTestProviderKind(t, "primary"),
+			APIKey:       "provider-secret-value-123",
+		},
+	}
+	callCount := 0
+	registerFailoverTestProvider(t, cfg.Providers[0].ProviderKind, &callCount, "pong", nil)
+	return newFailoverTestAgent(t, cfg)
+}
+
+func TestChatDeveloperModeIsOffByDefault(t *testing.T) {
+	ag := newDeveloperModeTestAgent(t)
+
+	response, routeResult, err := ag.ChatWithPromptContextDetailed(context.Background(), &developerModeTestSession{}, "ping", PromptContext{
+		SessionID: "webui-dev-off",
+		Channel:   "webui",
+	})
+	if err != nil {
+		t.Fatalf("ChatWithPromptContextDetailed failed: %v", err)
+	}
+	if response != "pong" {
+		t.Fatalf("expected response pong, got %q", response)
+	}
+	if routeResult.Debug != nil {
+		t.Fatalf("expected no debug payload without developer mode, got %+v", routeResult.Debug)
+	}
+}
+
+func TestChatDeveloperModeReturnsRedactedRequests(t *testing.T) {
+	ag := newDeveloperModeTestAgent(t)
+
+	sess := &developerModeTestSession{developerMode: true}
+	message := "ping with provider-secret-value-123 and api_key=abcdefghijklmnop"
+	response, routeResult, err := ag.ChatWithPromptContextDetailed(context.Background(), sess, message, PromptContext{
+		SessionID: "webui-dev-on",
+		Channel:   "webui",
+	})
+	if err != nil {
+		t.Fatalf("ChatWithPromptContextDetailed failed: %v", err)
+	}
+	if response != "pong" {
+		t.Fatalf("expected response pong, got %q", response)
+	}
+	if routeResult.Debug == nil || len(routeResult.Debug.Requests) != 1 {
+		t.Fatalf("expected one debug request, got %+v", routeResult.Debug)
+	}
+
+	req := routeResult.Debug.Requests[0]
+	if req.Provider != "test-primary" || req.Model != "gpt-5.4" {
+		t.Fatalf("unexpected debug route %s/%s", req.Provider, req.Model)
+	}
+	if len(req.Request.Messages) < 2 || req.Request.Messages[0].Role != "system" {
+		t.Fatalf("expected assembled messages with a system prompt, got %+v", req.Request.Messages)
+	}
+	last := req.Request.Messages[len(req.Request.Messages)-1]
+	if !strings.HasPrefix(last.Content, "ping with") {
+		t.Fatalf("expected user message last, got %q", last.Content)
+	}
+	for _, msg := range req.Request.Messages {
+		if strings.Contains(msg.Content, "provider-secret-value-123") || strings.Contains(msg.Content, "abcdefghijklmnop") {
+			t.Fatalf("expected secrets to be redacted, got %q", msg.Content)
+		}
+	}
+}
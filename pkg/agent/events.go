@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/providers"
+)
+
+// publishEvent reports a runtime event on the message bus for subscribers
+// such as notification rules. It never fails the caller.
+func (a *Agent) publishEvent(eventType, sessionID, content string, fields map[string]string) {
+	if a.events == nil {
+		return
+	}
+	if err := bus.PublishEvent(a.events, eventType, sessionID, content, fields); err != nil {
+		a.logger.Debug("Failed to publish runtime event", zap.String("event", eventType), zap.Error(err))
+	}
+}
+
+func (a *Agent) publishToolFailure(ctx context.Context, toolCall providers.UnifiedToolCall, err error) {
+	a.publishEvent(bus.EventToolFailed, ctxStringValue(ctx, promptContextSessionKey),
+		fmt.Sprintf("Tool %s failed: %v", toolCall.Name, err),
+		map[string]string{"tool": toolCall.Name, "error": err.Error()},
+	)
+}
+
+func (a *Agent) publishApprovalRequest(sessionID, requestID string, toolCall providers.UnifiedToolCall) {
+	a.publishEvent(bus.EventApprovalRequested, sessionID,
+		fmt.Sprintf("Tool %s is waiting for approval (request %s).", toolCall.Name, requestID),
+		map[string]string{"tool": toolCall.Name, "request_id": requestID},
+	)
+}
+
+// markProviderFailure records a provider failure and publishes an event when
+// it moves the provider into cooldown.
+func (a *Agent) markProviderFailure(ctx context.Context, tracker *providers.CooldownTracker, providerName string, reason providers.FailoverReason) {
+	wasAvailable := tracker.IsAvailable(providerName)
+	tracker.MarkFailure(providerName, reason)
+	if !wasAvailable || tracker.IsAvailable(providerName) {
+		return
+	}
+	remaining := tracker.CooldownRemaining(providerName).Round(time.Second)
+	a.publishEvent(bus.EventProviderCooldown, ctxStringValue(ctx, promptContextSessionKey),
+		fmt.Sprintf("Provider %s is cooling down for %s after a %s failure.", providerName, remaining, reason),
+		map[string]string{"provider": providerName, "reason": string(reason), "cooldown": remaining.String()},
+	)
+}
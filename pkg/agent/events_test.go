@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/providers"
+)
+
+type failingStubTool struct{}
+
+func (failingStubTool) Name() string        { return "broken_tool" }
+func (failingStubTool) Description() string { return "always fails" }
+func (failingStubTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+}
+func (failingStubTool) Execute(context.Context, map[string]interface{}) (string, error) {
+	return "", errors.New("disk full")
+}
+
+type eventRecordingBus struct {
+	bus.Bus
+	mu     sync.Mutex
+	events []*bus.Message
+}
+
+func (b *eventRecordingBus) SendOutbound(msg *bus.Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, msg)
+	return nil
+}
+
+func TestExecuteToolCallPublishesToolFailure(t *testing.T) {
+	ag := newRoutingTestAgent(t, orchestratorLegacy)
+	events := &eventRecordingBus{}
+	ag.events = events
+	ag.tools.MustRegister(failingStubTool{})
+
+	ctx := context.WithValue(context.Background(), promptContextSessionKey, "telegram:42")
+	if _, err := ag.executeToolCall(ctx, providers.UnifiedToolCall{Name: "broken_tool"}); err == nil {
+		t.Fatal("expected tool error")
+	}
+
+	if len(events.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(events.events))
+	}
+	msg := events.events[0]
+	if msg.ChannelID != bus.EventChannelID || msg.Type != bus.MessageTypeEvent || msg.SessionID != "telegram:42" {
+		t.Fatalf("unexpected event envelope %+v", msg)
+	}
+	if msg.Data[bus.DataKeyEvent] != bus.EventToolFailed || msg.Data["tool"] != "broken_tool" || msg.Data["error"] != "disk full" {
+		t.Fatalf("unexpected event data %+v", msg.Data)
+	}
+}
+
+func TestMarkProviderFailurePublishesCooldownOnce(t *testing.T) {
+	ag := newRoutingTestAgent(t, orchestratorLegacy)
+	events := &eventRecordingBus{}
+	ag.events = events
+	tracker := providers.NewCooldownTracker()
+
+	ag.markProviderFailure(context.Background(), tracker, "primary", providers.FailoverReasonRateLimit)
+	ag.markProviderFailure(context.Background(), tracker, "primary", providers.FailoverReasonRateLimit)
+
+	if len(events.events) != 1 {
+		t.Fatalf("expected a single cooldown event, got %d", len(events.events))
+	}
+	if data := events.events[0].Data; data[bus.DataKeyEvent] != bus.EventProviderCooldown || data["provider"] != "primary" {
+		t.Fatalf("unexpected cooldown event %+v", data)
+	}
+}
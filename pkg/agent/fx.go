@@ -3,15 +3,19 @@ package agent
 import (
 	"context"
 	"strings"
+	"time"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"nekobot/pkg/approval"
 	"nekobot/pkg/audit"
+	"nekobot/pkg/background"
 	"nekobot/pkg/bus"
 	"nekobot/pkg/config"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/mcp"
 	"nekobot/pkg/permissionrules"
+	"nekobot/pkg/plugins"
 	"nekobot/pkg/process"
 	"nekobot/pkg/prompts"
 	"nekobot/pkg/providers"
@@ -45,6 +49,19 @@ func (s busNotificationSender) SendNotification(msg *subagent.Notification) erro
 	})
 }
 
+// SendFile delivers a workspace file to a channel conversation as an outbound
+// file message.
+func (s busNotificationSender) SendFile(ctx context.Context, channel, chatID, path, caption string) error {
+	return s.bus.SendOutbound(&bus.Message{
+		ChannelID:   channel,
+		SessionID:   chatID,
+		Type:        bus.MessageTypeFile,
+		Content:     caption,
+		Attachments: []bus.Attachment{bus.FileAttachment(path)},
+		Timestamp:   time.Now(),
+	})
+}
+
 // Module provides agent for fx dependency injection.
 var Module = fx.Module("agent",
 	fx.Provide(ProvideAgent),
@@ -67,6 +84,8 @@ type provideAgentDeps struct {
 	EntClient       *ent.Client              `optional:"true"`
 	PromptMgr       *prompts.Manager         `optional:"true"`
 	AuditLogger     *audit.Logger            `optional:"true"`
+	MCPMgr          *mcp.Manager             `optional:"true"`
+	PluginMgr       *plugins.Manager         `optional:"true"`
 }
 
 // ProvideAgent provides an agent instance.
@@ -115,12 +134,15 @@ func ProvideAgent(deps provideAgentDeps) (*Agent, error) {
 
 		var err error
 		client, err = providers.NewClient(providerKind, &providers.RelayInfo{
-			ProviderName: providerName,
-			APIKey:       providerCfg.APIKey,
-			APIBase:      providerCfg.APIBase,
-			Model:        cfg.Agents.Defaults.Model,
-			Proxy:        providerCfg.Proxy,
-			Timeout:      providerCfg.GetTimeout(),
+			ProviderName:  providerName,
+			APIKey:        providerCfg.APIKey,
+			APIBase:       providerCfg.APIBase,
+			Model:         cfg.Agents.Defaults.Model,
+			Proxy:         providerCfg.Proxy,
+			Timeout:       providerCfg.GetTimeout(),
+			APIVersion:    providerCfg.APIVersion,
+			Deployments:   providerCfg.Deployments,
+			DisableStream: !providerCfg.StreamingEnabled() || providerCfg.Capabilities.StreamingUnsupported(cfg.Agents.Defaults.Model),
 		})
 		if err != nil {
 			log.Warn("Default provider configuration is invalid; starting agent without a provider client",
@@ -129,6 +151,8 @@ func ProvideAgent(deps provideAgentDeps) (*Agent, error) {
 				zap.Error(err),
 			)
 			client = nil
+		} else {
+			client.Use(providerMiddleware(cfg, log, providerName, providerCfg)...)
 		}
 	}
 
@@ -138,6 +162,12 @@ func ProvideAgent(deps provideAgentDeps) (*Agent, error) {
 		return nil, err
 	}
 	agent.permissionRules = permissionRules
+	agent.events = deps.Bus
+	bus.SubscribeConfigChanges(deps.Bus, agent.ApplyConfig)
+	agent.mcp = deps.MCPMgr
+	if approvalMgr != nil && approvalMgr.PromptFunc == nil {
+		approvalMgr.PromptFunc = agent.promptApprovalInChat
+	}
 
 	// Set skills manager on context builder
 	agent.context.SetSkillsManager(skillsMgr)
@@ -147,11 +177,24 @@ func ProvideAgent(deps provideAgentDeps) (*Agent, error) {
 	if err := agent.GetTools().Register(tools.NewSkillManageTool(skillsMgr)); err != nil {
 		log.Warn("Failed to register skill-manage tool", zap.Error(err))
 	}
+	if err := agent.GetTools().Register(tools.NewSendFileTool(cfg.WorkspacePath(), busNotificationSender{bus: deps.Bus}.SendFile)); err != nil {
+		log.Warn("Failed to register send_file tool", zap.Error(err))
+	}
 	agent.EnableSubagents(func(task *subagent.SubagentTask) {
 		if err := subagent.SendTaskNotification(busNotificationSender{bus: deps.Bus}, task); err != nil {
 			log.Warn("Subagent notification failed", zap.Error(err))
 		}
 	})
+	agent.background = background.NewManager(log, deps.ProcessMgr, busNotificationSender{bus: deps.Bus}, background.Config{
+		MaxRunning:       cfg.Tools.Background.MaxRunning,
+		ProgressInterval: time.Duration(cfg.Tools.Background.ProgressIntervalSeconds) * time.Second,
+	})
+	if agent.subagents != nil {
+		agent.background.SetAgents(agent.subagents)
+	}
+	if err := agent.GetTools().Register(tools.NewRunBackgroundTool(cfg.WorkspacePath(), cfg.Agents.Defaults.RestrictToWorkspace, agent.background)); err != nil {
+		log.Warn("Failed to register run_background tool", zap.Error(err))
+	}
 
 	// Set up audit logging hook
 	if deps.AuditLogger != nil && cfg.Audit.Enabled {
@@ -176,10 +219,26 @@ func ProvideAgent(deps provideAgentDeps) (*Agent, error) {
 				zap.Int("skills_total", len(skillsMgr.ListEnabled())),
 				zap.Int("skills_eligible", len(skillsMgr.ListEligibleEnabled())),
 			)
+			// The plugin manager starts first: the agent depends on it.
+			if deps.PluginMgr != nil {
+				deps.PluginMgr.Attach(agent.GetTools())
+			}
+			if agent.sandbox != nil {
+				go func() {
+					if err := agent.sandbox.Warm(context.Background()); err != nil {
+						log.Warn("Failed to warm sandbox pool", zap.Error(err))
+					}
+				}()
+			}
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
 			agent.DisableSubagents()
+			if agent.sandbox != nil {
+				if err := agent.sandbox.Close(ctx); err != nil {
+					log.Warn("Failed to remove sandbox containers", zap.Error(err))
+				}
+			}
 			log.Info("Agent shutting down")
 			return nil
 		},
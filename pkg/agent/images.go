@@ -0,0 +1,95 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-kratos/blades"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/providers"
+)
+
+// visionUnsupportedNote replaces the images of a message for models known
+// not to accept them, so the model can tell the user instead of guessing.
+const visionUnsupportedNote = "[The user attached %d image(s), but the current model cannot view images.]"
+
+// imageParts returns the image attachments of a turn as content parts.
+// Attachments without a usable MIME type are sniffed from their content.
+func imageParts(attachments []bus.Attachment) []providers.ContentPart {
+	var parts []providers.ContentPart
+	for _, attachment := range attachments {
+		if !attachment.IsImage() || (len(attachment.Data) == 0 && strings.TrimSpace(attachment.URL) == "") {
+			continue
+		}
+		mimeType := strings.TrimSpace(attachment.MIMEType)
+		if !strings.HasPrefix(mimeType, "image/") && len(attachment.Data) > 0 {
+			mimeType = http.DetectContentType(attachment.Data)
+		}
+		parts = append(parts, providers.ContentPart{
+			Type:     providers.ContentPartImage,
+			MIMEType: mimeType,
+			Data:     attachment.Data,
+			URL:      strings.TrimSpace(attachment.URL),
+		})
+	}
+	return parts
+}
+
+// withImages attaches parts to the last user message, which carries the
+// current turn. messages is not modified.
+func withImages(messages []providers.UnifiedMessage, parts []providers.ContentPart) []providers.UnifiedMessage {
+	if len(parts) == 0 {
+		return messages
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role != "user" {
+			continue
+		}
+		result := append([]providers.UnifiedMessage(nil), messages...)
+		result[i].Parts = append(append([]providers.ContentPart(nil), result[i].Parts...), parts...)
+		return result
+	}
+	return messages
+}
+
+// bladesImageParts converts image parts to blades message parts.
+func bladesImageParts(parts []providers.ContentPart) []any {
+	result := make([]any, 0, len(parts))
+	for _, part := range parts {
+		if len(part.Data) > 0 {
+			result = append(result, blades.DataPart{Name: "image", Bytes: part.Data, MIMEType: blades.MIMEType(part.MIMEType)})
+			continue
+		}
+		result = append(result, blades.FilePart{Name: "image", URI: part.URL, MIMEType: blades.MIMEType(part.MIMEType)})
+	}
+	return result
+}
+
+// withoutImages drops the images of every message, noting in the text how
+// many were removed.
+func withoutImages(messages []providers.UnifiedMessage) ([]providers.UnifiedMessage, bool) {
+	var result []providers.UnifiedMessage
+	for i, msg := range messages {
+		count := 0
+		for _, part := range msg.Parts {
+			if part.Type == providers.ContentPartImage {
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		if result == nil {
+			result = append([]providers.UnifiedMessage(nil), messages...)
+		}
+		note := fmt.Sprintf(visionUnsupportedNote, count)
+		result[i].Parts = nil
+		result[i].Content = strings.TrimSpace(msg.Content + "\n\n" + note)
+	}
+	if result == nil {
+		return messages, false
+	}
+	return result, true
+}
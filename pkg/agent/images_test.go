@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/config"
+	"nekobot/pkg/providers"
+)
+
+// pngHeader is enough of a PNG file for content sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newVisionTestAgent(t *testing.T, vision *bool, onRequest func(*providers.UnifiedRequest)) *Agent {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Orchestrator = orchestratorLegacy
+	cfg.Agents.Defaults.Provider = "vision"
+	cfg.Agents.Defaults.Model = "vision-model"
+	kind := failoverTestProviderKind(t, "vision")
+	registerFailoverTestProviderWithResponses(t, kind, new(int), []*providers.UnifiedResponse{{Content: "a cat"}}, onRequest)
+	cfg.Providers = []config.ProviderProfile{{
+		Name:         "vision",
+		ProviderKind: kind,
+		Models:       []string{"vision-model"},
+		DefaultModel: "vision-model",
+		Capabilities: &config.ProviderCapabilities{Model: "vision-model", Vision: vision},
+	}}
+	return newFailoverTestAgent(t, cfg)
+}
+
+func TestChatSendsImageAttachmentsWithCurrentTurn(t *testing.T) {
+	var request *providers.UnifiedRequest
+	ag := newVisionTestAgent(t, nil, func(req *providers.UnifiedRequest) { request = req })
+
+	_, _, err := ag.ChatWithPromptContextDetailed(context.Background(), &testSession{}, "what is this?", PromptContext{
+		Attachments: []bus.Attachment{
+			{Type: bus.MessageTypeImage, Data: pngHeader},
+			{Type: bus.MessageTypeFile, Data: []byte("not an image")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ChatWithPromptContextDetailed failed: %v", err)
+	}
+
+	last := request.Messages[len(request.Messages)-1]
+	if last.Role != "user" || len(last.Parts) != 1 {
+		t.Fatalf("expected one image on the user turn, got %+v", last)
+	}
+	if part := last.Parts[0]; part.Type != providers.ContentPartImage || part.MIMEType != "image/png" {
+		t.Fatalf("expected a sniffed PNG image part, got %+v", part)
+	}
+}
+
+func TestChatDropsImagesForModelsWithoutVision(t *testing.T) {
+	var request *providers.UnifiedRequest
+	noVision := false
+	ag := newVisionTestAgent(t, &noVision, func(req *providers.UnifiedRequest) { request = req })
+
+	_, _, err := ag.ChatWithPromptContextDetailed(context.Background(), &testSession{}, "what is this?", PromptContext{
+		Attachments: []bus.Attachment{{Type: bus.MessageTypeImage, MIMEType: "image/png", Data: pngHeader}},
+	})
+	if err != nil {
+		t.Fatalf("ChatWithPromptContextDetailed failed: %v", err)
+	}
+
+	last := request.Messages[len(request.Messages)-1]
+	if len(last.Parts) != 0 || !strings.Contains(last.Content, "cannot view images") {
+		t.Fatalf("expected the image to be replaced by a note, got %+v", last)
+	}
+}
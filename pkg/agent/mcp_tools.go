@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	bladesmcp "github.com/go-kratos/blades/contrib/mcp"
+	bladestools "github.com/go-kratos/blades/tools"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/mcp"
+)
+
+// mcpServerResolver resolves one MCP server's tools and applies its
+// allowed_tools/blocked_tools filter.
+type mcpServerResolver struct {
+	name     string
+	server   config.MCPServerConfig
+	resolver *bladesmcp.ToolsResolver
+}
+
+// mcpToolsResolver resolves tools from several MCP servers. Like the blades
+// resolver it only fails when no server could be loaded, so one unreachable
+// server does not take the others down with it.
+type mcpToolsResolver struct {
+	servers []mcpServerResolver
+	logger  *logger.Logger
+}
+
+func newMCPToolsResolver(serverConfigs []config.MCPServerConfig, log *logger.Logger) (*mcpToolsResolver, error) {
+	clientConfigs, err := toMCPClientConfigs(serverConfigs)
+	if err != nil {
+		return nil, err
+	}
+	if len(clientConfigs) == 0 {
+		return nil, nil
+	}
+
+	res := &mcpToolsResolver{
+		servers: make([]mcpServerResolver, 0, len(clientConfigs)),
+		logger:  log,
+	}
+	for i, clientConfig := range clientConfigs {
+		resolver, err := bladesmcp.NewToolsResolver(clientConfig)
+		if err != nil {
+			_ = res.Close()
+			return nil, fmt.Errorf("create mcp tools resolver for %s: %w", mcp.ServerName(serverConfigs[i], i), err)
+		}
+		res.servers = append(res.servers, mcpServerResolver{
+			name:     mcp.ServerName(serverConfigs[i], i),
+			server:   serverConfigs[i],
+			resolver: resolver,
+		})
+	}
+	return res, nil
+}
+
+func (r *mcpToolsResolver) Resolve(ctx context.Context) ([]bladestools.Tool, error) {
+	var (
+		errs     []error
+		resolved []bladestools.Tool
+		loaded   int
+	)
+	for _, server := range r.servers {
+		serverTools, err := server.resolver.Resolve(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("mcp server %s: %w", server.name, err))
+			continue
+		}
+		loaded++
+		for _, tool := range serverTools {
+			if tool == nil || !server.server.AllowsTool(tool.Name()) {
+				continue
+			}
+			resolved = append(resolved, tool)
+		}
+	}
+	if len(errs) > 0 && loaded == 0 {
+		return nil, fmt.Errorf("failed to load any mcp tools: %w", errors.Join(errs...))
+	}
+	if len(errs) > 0 && r.logger != nil {
+		r.logger.Warn("Some MCP servers failed to load tools", zap.Error(errors.Join(errs...)))
+	}
+	return resolved, nil
+}
+
+// Close closes every MCP server connection.
+func (r *mcpToolsResolver) Close() error {
+	var errs []error
+	for _, server := range r.servers {
+		if err := server.resolver.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("mcp server %s: %w", server.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,134 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"nekobot/pkg/config"
+	mcpmanager "nekobot/pkg/mcp"
+)
+
+func newTestMCPServer(t *testing.T, toolNames ...string) string {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-mcp", Version: "v0.0.1"}, nil)
+	for _, name := range toolNames {
+		server.AddTool(&mcp.Tool{
+			Name:        name,
+			Description: "test tool " + name,
+			InputSchema: map[string]any{"type": "object"},
+		}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil
+		})
+	}
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(httpServer.Close)
+	return httpServer.URL
+}
+
+func TestBuildBladesToolsResolver_FiltersMCPToolsPerServer(t *testing.T) {
+	docsURL := newTestMCPServer(t, "read_doc", "read_secret", "write_doc", "delete_doc")
+	searchURL := newTestMCPServer(t, "search", "index")
+
+	ag := newRoutingTestAgent(t, orchestratorBlades)
+	ag.config.Agents.Defaults.MCPServers = []config.MCPServerConfig{
+		{
+			Name:         "docs",
+			Transport:    "http",
+			Endpoint:     docsURL,
+			AllowedTools: []string{"read_*", "write_doc"},
+			BlockedTools: []string{"read_secret"},
+		},
+		{
+			Name:      "search",
+			Transport: "http",
+			Endpoint:  searchURL,
+		},
+	}
+
+	resolver, mcpResolver, err := ag.buildBladesToolsResolver()
+	if err != nil {
+		t.Fatalf("buildBladesToolsResolver failed: %v", err)
+	}
+	if mcpResolver == nil {
+		t.Fatalf("expected mcp resolver")
+	}
+	t.Cleanup(func() { _ = mcpResolver.Close() })
+
+	resolvedTools, err := resolver.Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("resolve tools failed: %v", err)
+	}
+
+	var mcpTools []string
+	for _, tool := range resolvedTools {
+		if strings.Contains(tool.Description(), "test tool ") {
+			mcpTools = append(mcpTools, tool.Name())
+		}
+	}
+	sort.Strings(mcpTools)
+	want := []string{"index", "read_doc", "search", "write_doc"}
+	if strings.Join(mcpTools, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected mcp tools %v, got %v", want, mcpTools)
+	}
+}
+
+func TestMCPToolsResolverToleratesUnreachableServer(t *testing.T) {
+	searchURL := newTestMCPServer(t, "search")
+
+	resolver, err := newMCPToolsResolver([]config.MCPServerConfig{
+		{Name: "down", Transport: "http", Endpoint: "http://127.0.0.1:1/mcp"},
+		{Name: "search", Transport: "http", Endpoint: searchURL},
+	}, nil)
+	if err != nil {
+		t.Fatalf("newMCPToolsResolver failed: %v", err)
+	}
+	t.Cleanup(func() { _ = resolver.Close() })
+
+	resolvedTools, err := resolver.Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("expected partial resolve to succeed, got %v", err)
+	}
+	if len(resolvedTools) != 1 || resolvedTools[0].Name() != "search" {
+		t.Fatalf("expected only the reachable server's tool, got %d tools", len(resolvedTools))
+	}
+}
+
+func TestBuildBladesToolsResolver_UsesMCPManagerConnections(t *testing.T) {
+	searchURL := newTestMCPServer(t, "search")
+
+	ag := newRoutingTestAgent(t, orchestratorBlades)
+	ag.config.Agents.Defaults.MCPServers = []config.MCPServerConfig{
+		{Name: "search", Transport: "http", Endpoint: searchURL},
+	}
+	ag.mcp = mcpmanager.New(ag.logger, ag.config)
+	t.Cleanup(func() { _ = ag.mcp.Stop() })
+	ag.mcp.Reload(context.Background())
+
+	resolver, mcpResolver, err := ag.buildBladesToolsResolver()
+	if err != nil {
+		t.Fatalf("buildBladesToolsResolver failed: %v", err)
+	}
+	if mcpResolver != nil {
+		t.Fatalf("expected no per-chat mcp resolver when the manager is running")
+	}
+
+	resolvedTools, err := resolver.Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("resolve tools failed: %v", err)
+	}
+	found := false
+	for _, tool := range resolvedTools {
+		found = found || tool.Name() == "search"
+	}
+	if !found {
+		t.Fatalf("expected the manager's search tool to be resolved")
+	}
+}
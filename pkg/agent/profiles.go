@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	bladestools "github.com/go-kratos/blades/tools"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/providers"
+	"nekobot/pkg/tools"
+)
+
+// agentProfileSession is implemented by sessions that select an agent profile.
+type agentProfileSession interface {
+	GetAgentProfile() string
+}
+
+// ResolveAgentProfile picks the agent profile for a chat. The chat's own
+// selection wins over the profile serving its channel; ok is false when the
+// defaults apply. Selecting config.DefaultAgentProfile opts out of channel routing.
+func ResolveAgentProfile(cfg *config.Config, channel, selected string) (config.AgentProfileConfig, bool) {
+	if cfg == nil || strings.TrimSpace(selected) == config.DefaultAgentProfile {
+		return config.AgentProfileConfig{}, false
+	}
+	if profile, ok := cfg.Agents.Profile(selected); ok {
+		return profile, true
+	}
+	return cfg.Agents.ProfileForChannel(channel)
+}
+
+// sessionAgentProfile resolves the profile for a turn. Channels that do not
+// pass their name are recognized by the "<channel>:<chat>" session ID prefix.
+func (a *Agent) sessionAgentProfile(sess SessionInterface, channel, sessionID string) (config.AgentProfileConfig, bool) {
+	selected := ""
+	if scoped, ok := sess.(agentProfileSession); ok {
+		selected = scoped.GetAgentProfile()
+	}
+	channel = strings.TrimSpace(channel)
+	if channel == "" {
+		channel, _, _ = strings.Cut(sessionID, ":")
+	}
+	return ResolveAgentProfile(a.config, channel, selected)
+}
+
+// applyAgentProfileRoute lets the profile pick the provider and model unless
+// the caller already asked for a specific provider.
+func applyAgentProfileRoute(profile config.AgentProfileConfig, provider, model string) (string, string) {
+	if strings.TrimSpace(provider) != "" {
+		return provider, model
+	}
+	if value := strings.TrimSpace(profile.Provider); value != "" {
+		provider = value
+	}
+	if value := strings.TrimSpace(profile.Model); value != "" {
+		model = value
+	}
+	return provider, model
+}
+
+// withAgentProfile stores the active profile for tool filtering and points
+// file and exec tools at the profile's workspace.
+func withAgentProfile(ctx context.Context, profile config.AgentProfileConfig) context.Context {
+	ctx = context.WithValue(ctx, promptContextAgentProfileKey, profile)
+	ctx = tools.WithAgentName(ctx, profile.Name)
+	return tools.WithWorkspace(ctx, profile.Workspace)
+}
+
+type tenantWorkspaceKey struct{}
+
+// WithTenant binds the caller's tenant to the turn context, so
+// workspace-bound tools run in that tenant's workspace.
+func WithTenant(ctx context.Context, tenantSlug string) context.Context {
+	return context.WithValue(ctx, tenantWorkspaceKey{}, strings.TrimSpace(tenantSlug))
+}
+
+// withTenantWorkspace points workspace-bound tools at the workspace of the
+// tenant bound by WithTenant. Turns without a tenant, or for the default
+// tenant, keep the workspace they already have.
+func (a *Agent) withTenantWorkspace(ctx context.Context) context.Context {
+	if ctx == nil || a.config == nil {
+		return ctx
+	}
+	slug, _ := ctx.Value(tenantWorkspaceKey{}).(string)
+	if slug == "" {
+		return ctx
+	}
+	workspace := a.config.TenantWorkspacePath(slug)
+	if workspace == a.config.WorkspacePath() {
+		return ctx
+	}
+	if err := os.MkdirAll(workspace, 0o755); err != nil {
+		a.logger.Warn("Failed to create tenant workspace", zap.String("workspace", workspace), zap.Error(err))
+	}
+	return tools.WithWorkspace(ctx, workspace)
+}
+
+func agentProfileFromContext(ctx context.Context) (config.AgentProfileConfig, bool) {
+	if ctx == nil {
+		return config.AgentProfileConfig{}, false
+	}
+	profile, ok := ctx.Value(promptContextAgentProfileKey).(config.AgentProfileConfig)
+	return profile, ok
+}
+
+// agentProfilePrompt returns the active profile's system prompt, if any.
+func agentProfilePrompt(ctx context.Context) string {
+	profile, ok := agentProfileFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(profile.SystemPrompt)
+}
+
+// profileAllowsTool reports whether the active profile may call a tool.
+func profileAllowsTool(ctx context.Context, toolName string) bool {
+	profile, ok := agentProfileFromContext(ctx)
+	return !ok || profile.AllowsTool(toolName)
+}
+
+// toolAllowed reports whether the active profile, the skills the turn
+// follows and, inside a child agent, the delegation scope all allow a tool.
+func toolAllowed(ctx context.Context, toolName string) bool {
+	return profileAllowsTool(ctx, toolName) && delegationAllowsTool(ctx, toolName) && blockingSkill(ctx, toolName) == nil
+}
+
+// filterAllowedTools drops tool definitions the current turn may not call.
+func filterAllowedTools(ctx context.Context, defs []providers.UnifiedTool) []providers.UnifiedTool {
+	filtered := make([]providers.UnifiedTool, 0, len(defs))
+	for _, def := range defs {
+		if toolAllowed(ctx, def.Name) {
+			filtered = append(filtered, def)
+		}
+	}
+	return filtered
+}
+
+// allowedToolResolver hides blades tools the current turn may not call.
+type allowedToolResolver struct {
+	resolver bladestools.Resolver
+}
+
+func (r *allowedToolResolver) Resolve(ctx context.Context) ([]bladestools.Tool, error) {
+	resolved, err := r.resolver.Resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]bladestools.Tool, 0, len(resolved))
+	for _, tool := range resolved {
+		if toolAllowed(ctx, tool.Name()) {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,225 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/providers"
+	"nekobot/pkg/tools"
+)
+
+type agentProfileTestSession struct {
+	testSession
+	profile string
+}
+
+func (s *agentProfileTestSession) GetAgentProfile() string {
+	return s.profile
+}
+
+func TestChatAppliesChannelAgentProfile(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Orchestrator = orchestratorLegacy
+	cfg.Agents.Defaults.Provider = "primary"
+	cfg.Agents.Defaults.Model = "test-model"
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Providers = []config.ProviderProfile{
+		{Name: "primary", ProviderKind: failoverTestProviderKind(t, "profile-primary")},
+		{Name: "coder", ProviderKind: failoverTestProviderKind(t, "profile-coder")},
+	}
+	cfg.Agents.Profiles = []config.AgentProfileConfig{{
+		Name:         "coder",
+		Provider:     "coder",
+		Model:        "coder-model",
+		Tools:        []string{"read_*"},
+		SystemPrompt: "You are a terse coding assistant.",
+		Channels:     []string{"discord"},
+	}}
+
+	primaryCalls := 0
+	registerFailoverTestProviderWithCapture(t, cfg.Providers[0].ProviderKind, &primaryCalls, "from primary", nil, nil)
+	coderCalls := 0
+	var captured *providers.UnifiedRequest
+	registerFailoverTestProviderWithCapture(t, cfg.Providers[1].ProviderKind, &coderCalls, "from coder", nil, func(req *providers.UnifiedRequest) {
+		captured = req
+	})
+	ag := newFailoverTestAgent(t, cfg)
+	workspace := t.TempDir()
+	if err := ag.tools.Register(tools.NewReadFileTool(workspace, false)); err != nil {
+		t.Fatalf("register read_file: %v", err)
+	}
+	if err := ag.tools.Register(tools.NewListDirTool(workspace, false)); err != nil {
+		t.Fatalf("register list_dir: %v", err)
+	}
+
+	reply, route, err := ag.ChatWithPromptContextDetailed(context.Background(), &testSession{}, "hi", PromptContext{
+		Channel:   "discord",
+		SessionID: "discord:42",
+	})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if reply != "from coder" || primaryCalls != 0 || route.AgentProfile != "coder" {
+		t.Fatalf("expected the coder profile route, got reply=%q primary=%d route=%+v", reply, primaryCalls, route)
+	}
+	if captured == nil || captured.Model != "coder-model" {
+		t.Fatalf("expected coder model, got %+v", captured)
+	}
+	if len(captured.Tools) != 1 || captured.Tools[0].Name != "read_file" {
+		t.Fatalf("expected only allowlisted tools, got %+v", captured.Tools)
+	}
+	if !strings.Contains(captured.Messages[0].Content, "# Agent Profile\n\nYou are a terse coding assistant.") {
+		t.Fatalf("expected profile prompt in system prompt, got:\n%s", captured.Messages[0].Content)
+	}
+
+	reply, route, err = ag.ChatWithPromptContextDetailed(context.Background(), &agentProfileTestSession{profile: "missing"}, "hi", PromptContext{
+		Channel:   "telegram",
+		SessionID: "telegram:42",
+	})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if reply != "from primary" || route.AgentProfile != "" {
+		t.Fatalf("expected defaults for an unrouted channel, got reply=%q route=%+v", reply, route)
+	}
+}
+
+type modelOverrideTestSession struct {
+	testSession
+	provider string
+	model    string
+}
+
+func (s *modelOverrideTestSession) GetModelOverride() (string, string, []string) {
+	return s.provider, s.model, nil
+}
+
+func TestChatAppliesSessionModelOverride(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Orchestrator = orchestratorLegacy
+	cfg.Agents.Defaults.Provider = "primary"
+	cfg.Agents.Defaults.Model = "test-model"
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Providers = []config.ProviderProfile{
+		{Name: "primary", ProviderKind: failoverTestProviderKind(t, "override-primary")},
+		{Name: "alt", ProviderKind: failoverTestProviderKind(t, "override-alt")},
+	}
+	cfg.Agents.Profiles = []config.AgentProfileConfig{{
+		Name:     "coder",
+		Provider: "primary",
+		Model:    "coder-model",
+		Channels: []string{"discord"},
+	}}
+
+	primaryCalls := 0
+	registerFailoverTestProviderWithCapture(t, cfg.Providers[0].ProviderKind, &primaryCalls, "from primary", nil, nil)
+	altCalls := 0
+	var captured *providers.UnifiedRequest
+	registerFailoverTestProviderWithCapture(t, cfg.Providers[1].ProviderKind, &altCalls, "from alt", nil, func(req *providers.UnifiedRequest) {
+		captured = req
+	})
+	ag := newFailoverTestAgent(t, cfg)
+
+	sess := &modelOverrideTestSession{provider: "alt", model: "alt-model"}
+	reply, _, err := ag.ChatWithPromptContextDetailed(context.Background(), sess, "hi", PromptContext{
+		Channel:   "discord",
+		SessionID: "discord:42",
+	})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if reply != "from alt" || primaryCalls != 0 {
+		t.Fatalf("expected the session override to beat the profile route, got reply=%q primary=%d", reply, primaryCalls)
+	}
+	if captured == nil || captured.Model != "alt-model" {
+		t.Fatalf("expected the overridden model, got %+v", captured)
+	}
+
+	reply, _, err = ag.ChatWithPromptContextDetailed(context.Background(), sess, "hi", PromptContext{
+		SessionID:         "webui:42",
+		RequestedProvider: "primary",
+	})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if reply != "from primary" {
+		t.Fatalf("expected an explicitly requested provider to win, got %q", reply)
+	}
+}
+
+func TestResolveAgentProfilePrefersSessionSelection(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Profiles = []config.AgentProfileConfig{
+		{Name: "coder", Channels: []string{"discord"}},
+		{Name: "support"},
+	}
+
+	if profile, ok := ResolveAgentProfile(cfg, "discord", ""); !ok || profile.Name != "coder" {
+		t.Fatalf("expected channel profile, got %+v ok=%v", profile, ok)
+	}
+	if profile, ok := ResolveAgentProfile(cfg, "discord", "support"); !ok || profile.Name != "support" {
+		t.Fatalf("expected selected profile, got %+v ok=%v", profile, ok)
+	}
+	if _, ok := ResolveAgentProfile(cfg, "discord", config.DefaultAgentProfile); ok {
+		t.Fatal("expected the default selection to opt out of channel routing")
+	}
+	if _, ok := ResolveAgentProfile(cfg, "slack", ""); ok {
+		t.Fatal("expected defaults for a channel without a profile")
+	}
+
+	ag := &Agent{config: cfg}
+	if profile, ok := ag.sessionAgentProfile(&testSession{}, "", "discord:7"); !ok || profile.Name != "coder" {
+		t.Fatalf("expected channel derived from the session ID, got %+v ok=%v", profile, ok)
+	}
+}
+
+func TestRunToolCallRejectsToolsOutsideProfile(t *testing.T) {
+	cfg := config.DefaultConfig()
+	ag := newFailoverTestAgent(t, cfg)
+	ctx := withAgentProfile(context.Background(), config.AgentProfileConfig{Name: "support", Tools: []string{"web_*"}})
+
+	_, err := ag.runToolCall(ctx, providers.UnifiedToolCall{Name: "exec"})
+	if err == nil || !strings.Contains(err.Error(), "not enabled for agent profile support") {
+		t.Fatalf("expected profile allowlist error, got %v", err)
+	}
+}
+
+func TestTenantTurnsCannotReachOtherTenantWorkspaces(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	ag := newFailoverTestAgent(t, cfg)
+	if err := ag.tools.Register(tools.NewReadFileTool(cfg.WorkspacePath(), true)); err != nil {
+		t.Fatalf("register read_file: %v", err)
+	}
+
+	acme := cfg.TenantWorkspacePath("acme")
+	globex := cfg.TenantWorkspacePath("globex")
+	for dir, name := range map[string]string{acme: "note.txt", globex: "secret.txt"} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("create tenant workspace: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	// A profile workspace must not move the turn out of the tenant's workspace.
+	ctx := withAgentProfile(WithTenant(context.Background(), "acme"), config.AgentProfileConfig{Name: "coder", Workspace: globex})
+	ctx = ag.withTenantWorkspace(ctx)
+	read := func(path string) (string, error) {
+		return ag.runToolCall(ctx, providers.UnifiedToolCall{Name: "read_file", Arguments: map[string]interface{}{"path": path}})
+	}
+
+	if out, err := read("note.txt"); err != nil || !strings.Contains(out, "note.txt") {
+		t.Fatalf("expected the tenant's own file, got %q (%v)", out, err)
+	}
+	for _, path := range []string{"../globex/secret.txt", filepath.Join(globex, "secret.txt")} {
+		if out, err := read(path); err == nil && strings.Contains(out, "secret.txt") {
+			t.Fatalf("expected %s to stay out of reach, got %q", path, out)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/providers"
+)
+
+const (
+	defaultSessionTitleMaxLength = 60
+	sessionTitleTimeout          = 30 * time.Second
+	sessionTitleInputLimit       = 2000
+	sessionTitlePrompt           = "Write a concise title (at most 8 words) for a conversation that starts with the user message below. " +
+		"Reply with the title only: no quotes, no trailing punctuation, same language as the message."
+)
+
+// titledSession is implemented by sessions that carry a display title.
+type titledSession interface {
+	GetTitle() string
+	SetTitle(string)
+}
+
+// GenerateSessionTitle asks the configured title model for a short title
+// summarizing the first user message in sess. It does not store the result.
+func (a *Agent) GenerateSessionTitle(ctx context.Context, sess SessionInterface) (string, error) {
+	if sess == nil {
+		return "", fmt.Errorf("session is required")
+	}
+	first := ""
+	for _, msg := range sess.GetMessages() {
+		if msg.Role == "user" && strings.TrimSpace(msg.Content) != "" {
+			first = msg.Content
+			break
+		}
+	}
+	if first == "" {
+		return "", fmt.Errorf("session has no user message to title")
+	}
+	return a.generateSessionTitle(ctx, first)
+}
+
+func (a *Agent) generateSessionTitle(ctx context.Context, userMessage string) (string, error) {
+	cfg := a.config.Sessions.AutoTitle
+	providerOrder, err := a.buildProviderOrder(cfg.Provider, nil)
+	if err != nil {
+		return "", err
+	}
+	model := strings.TrimSpace(cfg.Model)
+	if model == "" {
+		model = a.config.Agents.Defaults.Model
+	}
+
+	input := strings.TrimSpace(userMessage)
+	if utf8.RuneCountInString(input) > sessionTitleInputLimit {
+		input = string([]rune(input)[:sessionTitleInputLimit])
+	}
+	req := &providers.UnifiedRequest{
+		Model: model,
+		Messages: []providers.UnifiedMessage{
+			{Role: "system", Content: sessionTitlePrompt},
+			{Role: "user", Content: input},
+		},
+		MaxTokens:   64,
+		Temperature: 0.2,
+	}
+	resp, _, _, err := a.callLLMWithFallback(withStream(ctx, nil), req, providerOrder[0], providerOrder, model, make(map[string]*providers.Client))
+	if err != nil {
+		return "", err
+	}
+
+	title := normalizeSessionTitle(resp.Content, cfg.MaxLength)
+	if title == "" {
+		return "", fmt.Errorf("title model returned an empty title")
+	}
+	return title, nil
+}
+
+// shouldAutoTitle reports whether the turn about to run is the first user
+// turn of an untitled session with auto titles enabled.
+func (a *Agent) shouldAutoTitle(sess SessionInterface) bool {
+	if !a.config.Sessions.AutoTitle.Enabled || sess == nil {
+		return false
+	}
+	titled, ok := sess.(titledSession)
+	if !ok || titled.GetTitle() != "" {
+		return false
+	}
+	for _, msg := range sess.GetMessages() {
+		if msg.Role == "user" {
+			return false
+		}
+	}
+	return true
+}
+
+// autoTitleSession generates and stores a title in the background so the
+// reply is not delayed. A title set meanwhile (e.g. manually) is kept.
+func (a *Agent) autoTitleSession(ctx context.Context, sess SessionInterface, userMessage string) {
+	titled, ok := sess.(titledSession)
+	if !ok {
+		return
+	}
+	go func() {
+		titleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sessionTitleTimeout)
+		defer cancel()
+
+		title, err := a.generateSessionTitle(titleCtx, userMessage)
+		if err != nil {
+			a.logger.Warn("Failed to generate session title", zap.Error(err))
+			return
+		}
+		if titled.GetTitle() != "" {
+			return
+		}
+		titled.SetTitle(title)
+	}()
+}
+
+// normalizeSessionTitle strips quoting and trailing punctuation from a model
+// reply and caps it at maxLength characters.
+func normalizeSessionTitle(raw string, maxLength int) string {
+	if maxLength <= 0 {
+		maxLength = defaultSessionTitleMaxLength
+	}
+	title := strings.TrimSpace(raw)
+	if idx := strings.IndexAny(title, "\r\n"); idx >= 0 {
+		title = title[:idx]
+	}
+	title = strings.TrimSpace(title)
+	title = strings.TrimPrefix(title, "Title:")
+	for range 2 {
+		title = strings.TrimRight(strings.TrimSpace(title), ".。!！?？:：;；,，")
+		title = strings.Trim(strings.TrimSpace(title), "\"'`*#“”「」")
+	}
+	title = strings.Join(strings.Fields(title), " ")
+	if utf8.RuneCountInString(title) > maxLength {
+		title = strings.TrimSpace(string([]rune(title)[:maxLength]))
+	}
+	return title
+}
@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"nekobot/pkg/approval"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+type titledTestSession struct {
+	testSession
+	mu    sync.Mutex
+	title string
+}
+
+func (s *titledTestSession) GetTitle() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.title
+}
+
+func (s *titledTestSession) SetTitle(title string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.title = title
+}
+
+func newSessionTitleTestAgent(t *testing.T, content string, callCount *int) *Agent {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Orchestrator = orchestratorLegacy
+	cfg.Agents.Defaults.Provider = "test-primary"
+	cfg.Agents.Defaults.Model = "gpt-5.4"
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Providers = []config.ProviderProfile{
+		{
+			Name:         "test-primary",
+			ProviderKind: failoverTestProviderKind(t, "primary"),
+		},
+	}
+	cfg.Sessions.AutoTitle.Enabled = true
+	cfg.Sessions.AutoTitle.MaxLength = 40
+	registerFailoverTestProvider(t, cfg.Providers[0].ProviderKind, callCount, content, nil)
+
+	logCfg := logger.DefaultConfig()
+	logCfg.OutputPath = ""
+	logCfg.Development = true
+	log, err := logger.New(logCfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	ag, err := New(cfg, log, nil, nil, approval.NewManager(approval.Config{Mode: approval.ModeAuto}), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return ag
+}
+
+func TestChatAutoTitlesSessionAfterFirstTurn(t *testing.T) {
+	callCount := 0
+	ag := newSessionTitleTestAgent(t, "\"Planning a trip to Kyoto.\"", &callCount)
+
+	sess := &titledTestSession{}
+	if _, _, err := ag.ChatWithPromptContextDetailed(context.Background(), sess, "help me plan a trip to Kyoto", PromptContext{
+		SessionID: "webui-title",
+		Channel:   "webui",
+	}); err != nil {
+		t.Fatalf("ChatWithPromptContextDetailed failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for sess.GetTitle() == "" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := sess.GetTitle(); got != "Planning a trip to Kyoto" {
+		t.Fatalf("expected generated title, got %q", got)
+	}
+}
+
+func TestChatAutoTitleKeepsManualTitle(t *testing.T) {
+	callCount := 0
+	ag := newSessionTitleTestAgent(t, "Generated title", &callCount)
+
+	sess := &titledTestSession{title: "My notes"}
+	if _, _, err := ag.ChatWithPromptContextDetailed(context.Background(), sess, "hello", PromptContext{
+		SessionID: "webui-manual",
+		Channel:   "webui",
+	}); err != nil {
+		t.Fatalf("ChatWithPromptContextDetailed failed: %v", err)
+	}
+
+	if callCount != 1 {
+		t.Fatalf("expected only the chat call for a titled session, got %d calls", callCount)
+	}
+	if got := sess.GetTitle(); got != "My notes" {
+		t.Fatalf("expected manual title to be kept, got %q", got)
+	}
+}
+
+func TestChatAutoTitleSkipsLaterTurns(t *testing.T) {
+	callCount := 0
+	ag := newSessionTitleTestAgent(t, "Generated title", &callCount)
+
+	sess := &titledTestSession{testSession: testSession{messages: []Message{
+		{Role: "user", Content: "earlier"},
+		{Role: "assistant", Content: "reply"},
+	}}}
+	if _, _, err := ag.ChatWithPromptContextDetailed(context.Background(), sess, "again", PromptContext{
+		SessionID: "webui-later",
+		Channel:   "webui",
+	}); err != nil {
+		t.Fatalf("ChatWithPromptContextDetailed failed: %v", err)
+	}
+
+	if callCount != 1 || sess.GetTitle() != "" {
+		t.Fatalf("expected no title generation after the first turn, got %d calls and title %q", callCount, sess.GetTitle())
+	}
+}
+
+func TestNormalizeSessionTitle(t *testing.T) {
+	cases := map[string]string{
+		"  \"Kyoto trip.\"  ":         "Kyoto trip",
+		"Title: Debugging   Go tests": "Debugging Go tests",
+		"\n\n「旅行计划」。\nextra":          "旅行计划",
+		"abcdefghijklmnopqrstuvwxyz":  "abcdefghijklmnopqrst",
+	}
+	for raw, want := range cases {
+		if got := normalizeSessionTitle(raw, 20); got != want {
+			t.Fatalf("normalizeSessionTitle(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/skills"
+)
+
+// skillScope tracks the installed skills whose instructions a turn follows:
+// untrusted always-on skills from the start, and skills the agent invokes
+// as it goes. Every one of them must allow a tool before the turn may call
+// it, so invoking a second skill never widens what the first one granted.
+type skillScope struct {
+	mu     sync.Mutex
+	skills []*skills.Skill
+}
+
+// withSkillScope starts the skill scope of a turn. Child agents keep the
+// scope of the turn that spawned them.
+func (a *Agent) withSkillScope(ctx context.Context) context.Context {
+	if skillScopeFromContext(ctx) != nil {
+		return ctx
+	}
+	scope := &skillScope{}
+	if a.skillsManager != nil {
+		for _, skill := range a.skillsManager.ListAlwaysEligible() {
+			scope.activate(skill)
+		}
+	}
+	return context.WithValue(ctx, promptContextSkillScopeKey, scope)
+}
+
+func skillScopeFromContext(ctx context.Context) *skillScope {
+	if ctx == nil {
+		return nil
+	}
+	scope, _ := ctx.Value(promptContextSkillScopeKey).(*skillScope)
+	return scope
+}
+
+func (s *skillScope) activate(skill *skills.Skill) {
+	if skill == nil || skill.Trusted() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, active := range s.skills {
+		if active.ID == skill.ID {
+			return
+		}
+	}
+	s.skills = append(s.skills, skill)
+}
+
+// blocking returns the first active skill whose manifest does not allow tool.
+func (s *skillScope) blocking(tool string) *skills.Skill {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, skill := range s.skills {
+		if !skill.AllowsTool(tool) {
+			return skill
+		}
+	}
+	return nil
+}
+
+// blockingSkill returns the active skill, if any, that forbids tool in the
+// current turn.
+func blockingSkill(ctx context.Context, tool string) *skills.Skill {
+	scope := skillScopeFromContext(ctx)
+	if scope == nil {
+		return nil
+	}
+	return scope.blocking(tool)
+}
+
+// activateInvokedSkill puts a skill loaded through `skill invoke` under the
+// turn's skill scope.
+func (a *Agent) activateInvokedSkill(ctx context.Context, toolCall skillToolCall) {
+	scope := skillScopeFromContext(ctx)
+	if scope == nil || a.skillsManager == nil || toolCall.action != "invoke" || toolCall.skillID == "" {
+		return
+	}
+	skill, err := a.skillsManager.Get(toolCall.skillID)
+	if err != nil {
+		return
+	}
+	if !skill.Trusted() {
+		a.logger.Info("Limiting turn to skill permissions",
+			zap.String("skill", skill.ID),
+			zap.Strings("permissions", skill.Permissions.Requested()),
+		)
+	}
+	scope.activate(skill)
+}
+
+type skillToolCall struct {
+	action  string
+	skillID string
+}
+
+func parseSkillToolCall(args map[string]interface{}) skillToolCall {
+	action, _ := args["action"].(string)
+	skillID, _ := args["skill_id"].(string)
+	return skillToolCall{action: strings.TrimSpace(action), skillID: strings.TrimSpace(skillID)}
+}
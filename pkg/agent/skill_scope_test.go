@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/providers"
+	"nekobot/pkg/skills"
+	"nekobot/pkg/tools"
+)
+
+func newSkillScopeTestAgent(t *testing.T, skillFiles map[string]string) *Agent {
+	t.Helper()
+	ag := newFailoverTestAgent(t, config.DefaultConfig())
+	skillsDir := filepath.Join(t.TempDir(), "skills")
+	for id, content := range skillFiles {
+		dir := filepath.Join(skillsDir, id)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("create skill dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0o644); err != nil {
+			t.Fatalf("write skill: %v", err)
+		}
+	}
+	mgr := skills.NewManager(ag.logger, skillsDir, false)
+	if err := mgr.Discover(); err != nil {
+		t.Fatalf("discover skills: %v", err)
+	}
+	ag.skillsManager = mgr
+	if err := ag.tools.Register(tools.NewSkillTool(ag.logger, mgr)); err != nil {
+		t.Fatalf("register skill tool: %v", err)
+	}
+	return ag
+}
+
+func TestInvokedSkillLimitsTurnToItsPermissions(t *testing.T) {
+	ag := newSkillScopeTestAgent(t, map[string]string{
+		"fetcher": "---\nid: fetcher\npermissions:\n  network: true\n---\n\nFetch pages.",
+	})
+	ctx := ag.withSkillScope(context.Background())
+
+	if !toolAllowed(ctx, "exec") {
+		t.Fatal("expected exec to be allowed before any skill is invoked")
+	}
+	if _, err := ag.executeToolCall(ctx, providers.UnifiedToolCall{
+		Name:      "skill",
+		Arguments: map[string]interface{}{"action": "invoke", "skill_id": "fetcher"},
+	}); err != nil {
+		t.Fatalf("invoke skill: %v", err)
+	}
+
+	_, err := ag.runToolCall(ctx, providers.UnifiedToolCall{Name: "exec"})
+	if err == nil || !strings.Contains(err.Error(), "permissions manifest of skill fetcher") {
+		t.Fatalf("expected exec to be refused by the skill manifest, got %v", err)
+	}
+	if !toolAllowed(ctx, "web_fetch") || !toolAllowed(ctx, "read_file") || toolAllowed(ctx, "write_file") {
+		t.Fatal("unexpected tool filtering under the fetcher skill")
+	}
+
+	if !toolAllowed(ag.withSkillScope(context.Background()), "exec") {
+		t.Fatal("expected a new turn to start without the invoked skill")
+	}
+}
+
+func TestAlwaysOnSkillWithoutManifestScopesEveryTurn(t *testing.T) {
+	ag := newSkillScopeTestAgent(t, map[string]string{
+		"house-style": "---\nid: house-style\nalways: true\n---\n\nAnswer politely.",
+	})
+	ctx := ag.withSkillScope(context.Background())
+	if toolAllowed(ctx, "web_fetch") || !toolAllowed(ctx, "list_dir") {
+		t.Fatal("expected an always-on skill without manifest to limit the turn to read-only tools")
+	}
+	if child := ag.withSkillScope(ctx); skillScopeFromContext(child) != skillScopeFromContext(ctx) {
+		t.Fatal("expected child turns to keep the parent's skill scope")
+	}
+}
@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"nekobot/pkg/providers"
+)
+
+// webSearchResultURL matches the "URL:" line of each web_search result.
+var webSearchResultURL = regexp.MustCompile(`(?m)^\s*\d+\.\s+(.*)\n\s+URL:\s+(\S+)`)
+
+// Source is one web page consulted while answering a turn.
+type Source struct {
+	Title string
+	URL   string
+}
+
+// toolExecutionObserver is notified after every successful tool call of a turn.
+type toolExecutionObserver interface {
+	ObserveToolExecution(toolCall providers.UnifiedToolCall, result string)
+}
+
+// observeToolExecution hands a finished tool call to the turn's observer, if any.
+func observeToolExecution(ctx context.Context, toolCall providers.UnifiedToolCall, result string) {
+	observer, ok := ctx.Value(promptContextToolObserverKey).(toolExecutionObserver)
+	if !ok || observer == nil {
+		return
+	}
+	observer.ObserveToolExecution(toolCall, result)
+}
+
+// sourceCollector records the URLs fetched or searched by web tools during a turn.
+type sourceCollector struct {
+	mu      sync.Mutex
+	sources []Source
+	seen    map[string]struct{}
+}
+
+func newSourceCollector() *sourceCollector {
+	return &sourceCollector{seen: make(map[string]struct{})}
+}
+
+// ObserveToolExecution implements toolExecutionObserver.
+func (c *sourceCollector) ObserveToolExecution(toolCall providers.UnifiedToolCall, result string) {
+	switch toolCall.Name {
+	case "web_fetch":
+		rawURL, _ := toolCall.Arguments["url"].(string)
+		c.add("", rawURL)
+	case "web_search":
+		for _, match := range webSearchResultURL.FindAllStringSubmatch(result, -1) {
+			c.add(match[1], match[2])
+		}
+	}
+}
+
+func (c *sourceCollector) add(title, rawURL string) {
+	rawURL = strings.TrimSpace(rawURL)
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.seen[rawURL]; ok {
+		// A later fetch of a searched URL keeps the search title.
+		return
+	}
+	c.seen[rawURL] = struct{}{}
+	c.sources = append(c.sources, Source{Title: strings.TrimSpace(title), URL: rawURL})
+}
+
+func (c *sourceCollector) snapshot() []Source {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Source(nil), c.sources...)
+}
+
+// formatSources renders sources as a numbered list. URLs stay bare so every
+// channel can turn them into links, including plain-text ones.
+func formatSources(sources []Source) string {
+	var b strings.Builder
+	b.WriteString("Sources:")
+	for i, source := range sources {
+		if source.Title != "" {
+			fmt.Fprintf(&b, "\n%d. %s - %s", i+1, source.Title, source.URL)
+		} else {
+			fmt.Fprintf(&b, "\n%d. %s", i+1, source.URL)
+		}
+	}
+	return b.String()
+}
+
+// appendSources adds the sources section to a response.
+func appendSources(response string, sources []Source) string {
+	if len(sources) == 0 {
+		return response
+	}
+	return strings.TrimRight(response, "\n") + "\n\n" + formatSources(sources)
+}
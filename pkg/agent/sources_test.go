@@ -0,0 +1,134 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/providers"
+)
+
+// webToolStub stands in for web_search/web_fetch with a canned result.
+type webToolStub struct {
+	name   string
+	result string
+}
+
+func (t *webToolStub) Name() string        { return t.name }
+func (t *webToolStub) Description() string { return "stub " + t.name }
+func (t *webToolStub) Parameters() map[string]interface{} {
+	return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+}
+func (t *webToolStub) Execute(context.Context, map[string]interface{}) (string, error) {
+	return t.result, nil
+}
+
+func newSourcesTestAgent(t *testing.T, responses []*providers.UnifiedResponse) *Agent {
+	t.Helper()
+
+	providerKind := failoverTestProviderKind(t, "sources")
+	registerFailoverTestProviderWithResponses(t, providerKind, new(int), responses, nil)
+
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Orchestrator = orchestratorLegacy
+	cfg.Agents.Defaults.Provider = "primary"
+	cfg.Agents.Defaults.Model = "test-model"
+	cfg.Providers = []config.ProviderProfile{{
+		Name:         "primary",
+		ProviderKind: providerKind,
+		Models:       []string{"test-model"},
+		DefaultModel: "test-model",
+	}}
+
+	ag := newFailoverTestAgent(t, cfg)
+	ag.maxIterations = 3
+	ag.tools.MustRegister(&webToolStub{
+		name:   "web_search",
+		result: "Search results for: go\n\n1. The Go Programming Language\n   URL: https://go.dev/\n   Build simple software.\n\n2. Go docs\n   URL: https://go.dev/doc/\n",
+	})
+	ag.tools.MustRegister(&webToolStub{name: "web_fetch", result: "URL: https://pkg.go.dev/context\nStatus: 200"})
+	return ag
+}
+
+func TestChatAppendsSourcesFromWebTools(t *testing.T) {
+	ag := newSourcesTestAgent(t, []*providers.UnifiedResponse{
+		{
+			ToolCalls: []providers.UnifiedToolCall{
+				{ID: "call-1", Name: "web_search", Arguments: map[string]interface{}{"query": "go"}},
+				{ID: "call-2", Name: "web_fetch", Arguments: map[string]interface{}{"url": "https://pkg.go.dev/context"}},
+			},
+			FinishReason: "tool_calls",
+		},
+		{Content: "Go is a programming language.", FinishReason: "stop"},
+	})
+
+	response, route, err := ag.ChatWithPromptContextDetailed(context.Background(), &testSession{}, "what is go", PromptContext{
+		RequestedProvider: "primary",
+		RequestedModel:    "test-model",
+	})
+	if err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+
+	want := []Source{
+		{Title: "The Go Programming Language", URL: "https://go.dev/"},
+		{Title: "Go docs", URL: "https://go.dev/doc/"},
+		{URL: "https://pkg.go.dev/context"},
+	}
+	if len(route.Sources) != len(want) {
+		t.Fatalf("expected %d sources, got %+v", len(want), route.Sources)
+	}
+	for i, source := range want {
+		if route.Sources[i] != source {
+			t.Fatalf("source %d: expected %+v, got %+v", i, source, route.Sources[i])
+		}
+	}
+	if !strings.HasPrefix(response, "Go is a programming language.\n\nSources:\n") {
+		t.Fatalf("expected sources section after the answer, got %q", response)
+	}
+	for _, source := range want {
+		if !strings.Contains(response, source.URL) {
+			t.Fatalf("expected response to cite %s, got %q", source.URL, response)
+		}
+	}
+
+	ag2 := newSourcesTestAgent(t, []*providers.UnifiedResponse{
+		{
+			ToolCalls:    []providers.UnifiedToolCall{{ID: "call-1", Name: "web_fetch", Arguments: map[string]interface{}{"url": "https://go.dev/"}}},
+			FinishReason: "tool_calls",
+		},
+		{Content: "plain answer", FinishReason: "stop"},
+	})
+	ag2.config.Tools.Web.CiteSources = false
+	response, _, err = ag2.ChatWithPromptContextDetailed(context.Background(), &testSession{}, "what is go", PromptContext{
+		RequestedProvider: "primary",
+		RequestedModel:    "test-model",
+	})
+	if err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+	if response != "plain answer" {
+		t.Fatalf("expected no sources section when cite_sources is off, got %q", response)
+	}
+}
+
+func TestChatOmitsSourcesWithoutWebTools(t *testing.T) {
+	ag := newSourcesTestAgent(t, []*providers.UnifiedResponse{
+		{Content: "Hello there.", FinishReason: "stop"},
+	})
+
+	response, route, err := ag.ChatWithPromptContextDetailed(context.Background(), &testSession{}, "hi", PromptContext{
+		RequestedProvider: "primary",
+		RequestedModel:    "test-model",
+	})
+	if err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+	if len(route.Sources) != 0 {
+		t.Fatalf("expected no sources, got %+v", route.Sources)
+	}
+	if response != "Hello there." {
+		t.Fatalf("expected response without sources section, got %q", response)
+	}
+}
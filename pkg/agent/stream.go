@@ -0,0 +1,216 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/providers"
+)
+
+// StreamFunc receives the reply text of the current LLM call as it grows.
+// Each call carries the full text so far, not just the new tokens; a new
+// LLM call within the same turn (after tool calls or a provider fallback)
+// starts over from its own first token.
+type StreamFunc func(text string)
+
+// withStream attaches fn to ctx so LLM calls of the turn stream through it.
+// A nil fn disables streaming for ctx, e.g. for side requests of a turn.
+func withStream(ctx context.Context, fn StreamFunc) context.Context {
+	return context.WithValue(ctx, promptContextStreamKey, fn)
+}
+
+func streamFromContext(ctx context.Context) StreamFunc {
+	fn, _ := ctx.Value(promptContextStreamKey).(StreamFunc)
+	return fn
+}
+
+// ToolProgress reports a tool call of the turn starting or finishing.
+type ToolProgress struct {
+	ID   string
+	Name string
+	Done bool
+	// Error is set when a finished call failed.
+	Error string
+}
+
+// ToolProgressFunc receives the tool calls of a turn as they run.
+type ToolProgressFunc func(ToolProgress)
+
+// withToolProgress attaches fn to ctx; a nil fn disables reporting.
+func withToolProgress(ctx context.Context, fn ToolProgressFunc) context.Context {
+	return context.WithValue(ctx, promptContextToolProgressKey, fn)
+}
+
+func reportToolProgress(ctx context.Context, progress ToolProgress) {
+	if fn, _ := ctx.Value(promptContextToolProgressKey).(ToolProgressFunc); fn != nil {
+		fn(progress)
+	}
+}
+
+// requestLLM sends one request to client. When the turn streams, the reply
+// is requested as a stream and its text forwarded as it arrives.
+func (a *Agent) requestLLM(ctx context.Context, client *providers.Client, req *providers.UnifiedRequest) (*providers.UnifiedResponse, error) {
+	stream := streamFromContext(ctx)
+	if stream == nil {
+		return client.Chat(ctx, req)
+	}
+
+	acc := newStreamAccumulator(stream)
+	streamReq := *req
+	if err := client.ChatStream(ctx, &streamReq, acc); err != nil {
+		return nil, err
+	}
+	resp, err := acc.response()
+	if err != nil {
+		// Some providers split tool call arguments in ways that cannot be
+		// put back together; ask again without streaming.
+		a.logger.Warn("Streamed response unusable, retrying without streaming", zap.Error(err))
+		return client.Chat(ctx, req)
+	}
+	return resp, nil
+}
+
+// streamAccumulator rebuilds a complete response from stream chunks.
+type streamAccumulator struct {
+	stream   StreamFunc
+	resp     providers.UnifiedResponse
+	content  strings.Builder
+	thinking strings.Builder
+	calls    []*streamedToolCall
+}
+
+// streamedToolCall collects one tool call spread over several chunks.
+// Argument fragments are raw JSON text; complete argument maps are kept
+// as-is unless fragments follow.
+type streamedToolCall struct {
+	call      providers.UnifiedToolCall
+	fragments strings.Builder
+}
+
+func newStreamAccumulator(stream StreamFunc) *streamAccumulator {
+	return &streamAccumulator{stream: stream}
+}
+
+// OnChunk implements providers.StreamHandler.
+func (s *streamAccumulator) OnChunk(chunk *providers.UnifiedStreamChunk) error {
+	if chunk == nil {
+		return nil
+	}
+	if chunk.ID != "" {
+		s.resp.ID = chunk.ID
+	}
+	if chunk.Model != "" {
+		s.resp.Model = chunk.Model
+	}
+	if chunk.FinishReason != "" {
+		s.resp.FinishReason = chunk.FinishReason
+	}
+	if chunk.Usage != nil {
+		s.resp.Usage = chunk.Usage
+	}
+	for key, value := range chunk.Extra {
+		if s.resp.Extra == nil {
+			s.resp.Extra = make(map[string]interface{}, len(chunk.Extra))
+		}
+		s.resp.Extra[key] = value
+	}
+	s.thinking.WriteString(chunk.Delta.Thinking)
+	for _, toolCall := range chunk.Delta.ToolCalls {
+		s.addToolCall(toolCall)
+	}
+	if chunk.Delta.Content != "" {
+		s.content.WriteString(chunk.Delta.Content)
+		s.stream(s.content.String())
+	}
+	return nil
+}
+
+// OnError implements providers.StreamHandler; ChatStream returns the error.
+func (s *streamAccumulator) OnError(error) {}
+
+// OnComplete implements providers.StreamHandler.
+func (s *streamAccumulator) OnComplete(usage *providers.UnifiedUsage) {
+	if usage != nil {
+		s.resp.Usage = usage
+	}
+}
+
+// addToolCall starts a new call when the delta names one and otherwise
+// appends the delta's arguments to the call in progress.
+func (s *streamAccumulator) addToolCall(delta providers.UnifiedToolCall) {
+	var current *streamedToolCall
+	if len(s.calls) > 0 {
+		current = s.calls[len(s.calls)-1]
+	}
+	startsCall := delta.ID != "" && (current == nil || current.call.ID != delta.ID)
+	if startsCall || current == nil || (delta.ID == "" && delta.Name != "") {
+		current = &streamedToolCall{call: providers.UnifiedToolCall{ID: delta.ID, Type: delta.Type}}
+		s.calls = append(s.calls, current)
+	}
+	if delta.Name != "" {
+		current.call.Name = delta.Name
+	}
+	if delta.Type != "" {
+		current.call.Type = delta.Type
+	}
+
+	if fragment, ok := argumentFragment(delta.Arguments); ok {
+		current.fragments.WriteString(fragment)
+		return
+	}
+	if len(delta.Arguments) == 0 {
+		return
+	}
+	if current.fragments.Len() > 0 {
+		encoded, _ := json.Marshal(delta.Arguments)
+		current.fragments.Write(encoded)
+		return
+	}
+	if current.call.Arguments == nil {
+		current.call.Arguments = make(map[string]interface{}, len(delta.Arguments))
+	}
+	for key, value := range delta.Arguments {
+		current.call.Arguments[key] = value
+	}
+}
+
+// argumentFragment extracts partial argument JSON that converters wrap as
+// {"raw": ...} (OpenAI) or {"partial_json": ...} (Claude).
+func argumentFragment(args map[string]interface{}) (string, bool) {
+	if len(args) != 1 {
+		return "", false
+	}
+	for _, key := range []string{"raw", "partial_json"} {
+		if fragment, ok := args[key].(string); ok {
+			return fragment, true
+		}
+	}
+	return "", false
+}
+
+// response returns the assembled response, or an error when tool call
+// arguments do not form valid JSON.
+func (s *streamAccumulator) response() (*providers.UnifiedResponse, error) {
+	resp := s.resp
+	resp.Content = s.content.String()
+	resp.Thinking = s.thinking.String()
+	for _, streamed := range s.calls {
+		call := streamed.call
+		if raw := strings.TrimSpace(streamed.fragments.String()); raw != "" {
+			var args map[string]interface{}
+			if err := json.Unmarshal([]byte(raw), &args); err != nil {
+				return nil, fmt.Errorf("tool call %s: invalid streamed arguments: %w", call.Name, err)
+			}
+			call.Arguments = args
+		}
+		if call.Arguments == nil {
+			call.Arguments = map[string]interface{}{}
+		}
+		resp.ToolCalls = append(resp.ToolCalls, call)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,130 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/providers"
+)
+
+func TestStreamAccumulatorRebuildsFragmentedResponse(t *testing.T) {
+	var streamed []string
+	acc := newStreamAccumulator(func(text string) { streamed = append(streamed, text) })
+
+	chunks := []*providers.UnifiedStreamChunk{
+		{ID: "resp-1", Model: "test-model", Delta: providers.UnifiedDelta{Content: "Let me "}},
+		{Delta: providers.UnifiedDelta{Content: "check."}},
+		// OpenAI style: the call is named once, then arguments arrive as raw text.
+		{Delta: providers.UnifiedDelta{ToolCalls: []providers.UnifiedToolCall{{ID: "call-1", Name: "web_search"}}}},
+		{Delta: providers.UnifiedDelta{ToolCalls: []providers.UnifiedToolCall{{Arguments: map[string]interface{}{"raw": `{"query":`}}}}},
+		{Delta: providers.UnifiedDelta{ToolCalls: []providers.UnifiedToolCall{{Arguments: map[string]interface{}{"raw": `"go"}`}}}}},
+		// Claude style: partial_json fragments for a second call.
+		{Delta: providers.UnifiedDelta{ToolCalls: []providers.UnifiedToolCall{{ID: "call-2", Name: "web_fetch"}}}},
+		{Delta: providers.UnifiedDelta{ToolCalls: []providers.UnifiedToolCall{{Arguments: map[string]interface{}{"partial_json": `{"url":"https://go.dev/"}`}}}}},
+		{FinishReason: "tool_calls", Usage: &providers.UnifiedUsage{TotalTokens: 12}},
+	}
+	for _, chunk := range chunks {
+		if err := acc.OnChunk(chunk); err != nil {
+			t.Fatalf("OnChunk failed: %v", err)
+		}
+	}
+	acc.OnComplete(nil)
+
+	resp, err := acc.response()
+	if err != nil {
+		t.Fatalf("response failed: %v", err)
+	}
+	if resp.Content != "Let me check." || resp.ID != "resp-1" || resp.FinishReason != "tool_calls" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if resp.Usage == nil || resp.Usage.TotalTokens != 12 {
+		t.Fatalf("expected usage from the stream, got %+v", resp.Usage)
+	}
+	if len(streamed) != 2 || streamed[1] != "Let me check." {
+		t.Fatalf("expected accumulated text per content chunk, got %q", streamed)
+	}
+	if len(resp.ToolCalls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %+v", resp.ToolCalls)
+	}
+	if call := resp.ToolCalls[0]; call.ID != "call-1" || call.Name != "web_search" || call.Arguments["query"] != "go" {
+		t.Fatalf("unexpected first tool call %+v", call)
+	}
+	if call := resp.ToolCalls[1]; call.ID != "call-2" || call.Name != "web_fetch" || call.Arguments["url"] != "https://go.dev/" {
+		t.Fatalf("unexpected second tool call %+v", call)
+	}
+}
+
+func TestStreamAccumulatorRejectsBrokenToolArguments(t *testing.T) {
+	acc := newStreamAccumulator(func(string) {})
+	_ = acc.OnChunk(&providers.UnifiedStreamChunk{Delta: providers.UnifiedDelta{ToolCalls: []providers.UnifiedToolCall{
+		{ID: "call-1", Name: "web_search", Arguments: map[string]interface{}{"raw": `{"query":`}},
+	}}})
+
+	if _, err := acc.response(); err == nil {
+		t.Fatal("expected an error for incomplete tool call arguments")
+	}
+}
+
+func TestChatStreamsReplyTextThroughPromptContext(t *testing.T) {
+	providerKind := failoverTestProviderKind(t, "stream")
+	registerFailoverTestProviderWithResponses(t, providerKind, new(int), []*providers.UnifiedResponse{
+		{
+			ToolCalls:    []providers.UnifiedToolCall{{ID: "call-1", Name: "web_fetch", Arguments: map[string]interface{}{"url": "https://go.dev/"}}},
+			FinishReason: "tool_calls",
+			Usage:        &providers.UnifiedUsage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12},
+		},
+		{
+			Content:      "Go is a programming language.",
+			FinishReason: "stop",
+			Usage:        &providers.UnifiedUsage{PromptTokens: 20, CompletionTokens: 6, TotalTokens: 26},
+		},
+	}, nil)
+
+	streamDisabled := false
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Orchestrator = orchestratorLegacy
+	cfg.Agents.Defaults.Provider = "primary"
+	cfg.Agents.Defaults.Model = "test-model"
+	cfg.Tools.Web.CiteSources = false
+	cfg.Providers = []config.ProviderProfile{{
+		Name:         "primary",
+		ProviderKind: providerKind,
+		Models:       []string{"test-model"},
+		DefaultModel: "test-model",
+		// Buffered replies are replayed through the stream handler.
+		Stream: &streamDisabled,
+	}}
+
+	ag := newFailoverTestAgent(t, cfg)
+	ag.maxIterations = 3
+	ag.tools.MustRegister(&webToolStub{name: "web_fetch", result: "URL: https://go.dev/\nStatus: 200"})
+
+	var streamed []string
+	var progress []ToolProgress
+	response, route, err := ag.ChatWithPromptContextDetailed(context.Background(), &testSession{}, "what is go", PromptContext{
+		RequestedProvider: "primary",
+		RequestedModel:    "test-model",
+		Stream:            func(text string) { streamed = append(streamed, text) },
+		ToolProgress:      func(p ToolProgress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+	if response != "Go is a programming language." {
+		t.Fatalf("unexpected response %q", response)
+	}
+	if len(streamed) != 1 || streamed[0] != response {
+		t.Fatalf("expected the final text to be streamed once, got %q", streamed)
+	}
+	want := []ToolProgress{
+		{ID: "call-1", Name: "web_fetch"},
+		{ID: "call-1", Name: "web_fetch", Done: true},
+	}
+	if len(progress) != len(want) || progress[0] != want[0] || progress[1] != want[1] {
+		t.Fatalf("expected start and finish of the tool call, got %+v", progress)
+	}
+	if want := (TurnUsage{LLMCalls: 2, PromptTokens: 30, CompletionTokens: 8, TotalTokens: 38}); route.Usage != want {
+		t.Fatalf("expected usage of both LLM calls, got %+v", route.Usage)
+	}
+}
@@ -0,0 +1,180 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/providers"
+)
+
+// structuredOutputAttempts bounds how often a structured request is sent
+// again after the model replied with invalid JSON.
+const structuredOutputAttempts = 3
+
+// ErrInvalidStructuredOutput is returned when the model keeps replying with
+// JSON that does not parse or does not match the requested schema.
+var ErrInvalidStructuredOutput = errors.New("model did not return valid structured output")
+
+// ChatStructured answers prompt with a JSON value matching schema, a JSON
+// Schema document. A nil schema accepts any JSON object. See
+// ChatWithResponseFormat.
+func (a *Agent) ChatStructured(
+	ctx context.Context,
+	sess SessionInterface,
+	prompt string,
+	schema map[string]interface{},
+) (json.RawMessage, error) {
+	format := &providers.ResponseFormat{Type: providers.ResponseFormatJSONObject}
+	if schema != nil {
+		format = &providers.ResponseFormat{Type: providers.ResponseFormatJSONSchema, Schema: schema}
+	}
+	return a.ChatWithResponseFormat(ctx, sess, prompt, format)
+}
+
+// ChatWithResponseFormat answers prompt with a JSON value in the requested
+// format. The session history gives context but tools are not offered, and
+// the turn is not stored in the session. The provider's native JSON mode is
+// used where one exists; a reply that does not parse or match the schema is
+// sent back to the model with the validation error, up to
+// structuredOutputAttempts times.
+func (a *Agent) ChatWithResponseFormat(
+	ctx context.Context,
+	sess SessionInterface,
+	prompt string,
+	format *providers.ResponseFormat,
+) (json.RawMessage, error) {
+	format, err := format.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	if format == nil {
+		return nil, fmt.Errorf("a JSON response format is required")
+	}
+	validator, err := compileResponseSchema(format.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if verdict := a.moderationFilter().CheckInput(ctx, prompt); verdict.Blocked {
+		return nil, fmt.Errorf("prompt blocked by moderation: %s", verdict.Message)
+	}
+
+	model := a.config.Agents.Defaults.Model
+	providerOrder, err := a.buildProviderOrder("", nil)
+	if err != nil {
+		return nil, err
+	}
+	resolvedPrompts, err := a.resolvePromptSet(ctx, "", model, nil, PromptContext{})
+	if err != nil {
+		return nil, err
+	}
+	resolvedPrompts.Pinned = sessionPins(sess)
+	resolvedPrompts.Persona = sessionPersona(sess)
+	messages := a.convertToProviderMessages(
+		a.context.BuildMessagesWithPromptSet(a.sessionHistory(sess), prompt, resolvedPrompts),
+	)
+	messages = withStructuredOutputInstruction(messages, format)
+
+	ctx = withStream(ctx, nil)
+	clientCache := make(map[string]*providers.Client)
+	var lastErr error
+	for attempt := 1; attempt <= structuredOutputAttempts; attempt++ {
+		req := &providers.UnifiedRequest{
+			Model:          model,
+			Messages:       messages,
+			MaxTokens:      a.config.Agents.Defaults.MaxTokens,
+			Temperature:    a.config.Agents.Defaults.Temperature,
+			ResponseFormat: format,
+		}
+		resp, providerUsed, _, err := a.callLLMWithFallback(ctx, req, providerOrder[0], providerOrder, model, clientCache)
+		if err != nil {
+			return nil, err
+		}
+
+		output := providers.StructuredContent(resp, format)
+		lastErr = validateStructuredOutput(output, validator)
+		if lastErr == nil {
+			return json.RawMessage(output), nil
+		}
+		a.logger.Warn("Model returned invalid structured output",
+			zap.String("provider", providerUsed),
+			zap.Int("attempt", attempt),
+			zap.Error(lastErr),
+		)
+		messages = append(messages,
+			providers.UnifiedMessage{Role: "assistant", Content: output},
+			providers.UnifiedMessage{Role: "user", Content: fmt.Sprintf(
+				"That reply is not valid: %v. Reply again with only the corrected JSON.", lastErr)},
+		)
+	}
+	return nil, fmt.Errorf("%w after %d attempts: %v", ErrInvalidStructuredOutput, structuredOutputAttempts, lastErr)
+}
+
+// withStructuredOutputInstruction tells the model what JSON to reply with,
+// after the leading system messages. Native JSON modes enforce the format
+// too, but providers without one rely on the instruction alone.
+func withStructuredOutputInstruction(messages []providers.UnifiedMessage, format *providers.ResponseFormat) []providers.UnifiedMessage {
+	instruction := "Reply with a single JSON object and nothing else: no prose, no Markdown."
+	if len(format.Schema) > 0 {
+		schema, _ := json.Marshal(format.Schema)
+		instruction = "Reply with a single JSON value matching this JSON Schema and nothing else " +
+			"(no prose, no Markdown):\n" + string(schema)
+	}
+	split := 0
+	for split < len(messages) && messages[split].Role == "system" {
+		split++
+	}
+	result := make([]providers.UnifiedMessage, 0, len(messages)+1)
+	result = append(result, messages[:split]...)
+	result = append(result, providers.UnifiedMessage{Role: "system", Content: instruction})
+	return append(result, messages[split:]...)
+}
+
+// compileResponseSchema resolves a JSON Schema document for validation. It
+// returns nil for an empty schema.
+func compileResponseSchema(schema map[string]interface{}) (*jsonschema.Resolved, error) {
+	if len(schema) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("encode response schema: %w", err)
+	}
+	var parsed jsonschema.Schema
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("parse response schema: %w", err)
+	}
+	resolved, err := parsed.Resolve(nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response schema: %w", err)
+	}
+	return resolved, nil
+}
+
+// validateStructuredOutput checks that output is JSON and, given a schema,
+// matches it. Without a schema the reply must be a JSON object.
+func validateStructuredOutput(output string, validator *jsonschema.Resolved) error {
+	if strings.TrimSpace(output) == "" {
+		return fmt.Errorf("empty reply")
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(output), &value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if validator == nil {
+		if _, ok := value.(map[string]interface{}); !ok {
+			return fmt.Errorf("expected a JSON object")
+		}
+		return nil
+	}
+	if err := validator.Validate(value); err != nil {
+		return fmt.Errorf("schema mismatch: %w", err)
+	}
+	return nil
+}
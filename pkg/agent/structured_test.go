@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/providers"
+)
+
+var placeSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"city": map[string]interface{}{"type": "string"},
+	},
+	"required": []interface{}{"city"},
+}
+
+func newStructuredTestAgent(t *testing.T, responses []*providers.UnifiedResponse, callCount *int, onRequest func(*providers.UnifiedRequest)) *Agent {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Provider = "structured"
+	cfg.Agents.Defaults.Model = "structured-model"
+	kind := failoverTestProviderKind(t, "structured")
+	registerFailoverTestProviderWithResponses(t, kind, callCount, responses, onRequest)
+	cfg.Providers = []config.ProviderProfile{{
+		Name:         "structured",
+		ProviderKind: kind,
+		Models:       []string{"structured-model"},
+		DefaultModel: "structured-model",
+	}}
+	return newFailoverTestAgent(t, cfg)
+}
+
+func TestChatStructuredRetriesUntilOutputMatchesSchema(t *testing.T) {
+	callCount := new(int)
+	var requests []*providers.UnifiedRequest
+	ag := newStructuredTestAgent(t, []*providers.UnifiedResponse{
+		{Content: "Sure, here it is: {"},
+		{Content: `{"city": 42}`},
+		{Content: "```json\n{\"city\": \"Paris\"}\n```"},
+	}, callCount, func(req *providers.UnifiedRequest) {
+		requests = append(requests, req)
+	})
+
+	output, err := ag.ChatStructured(context.Background(), &testSession{}, "Where is the Louvre?", placeSchema)
+	if err != nil {
+		t.Fatalf("ChatStructured failed: %v", err)
+	}
+	if string(output) != `{"city": "Paris"}` || *callCount != 3 {
+		t.Fatalf("expected valid output on the third attempt, got %s after %d calls", output, *callCount)
+	}
+
+	format := requests[0].ResponseFormat
+	if format == nil || format.Type != providers.ResponseFormatJSONSchema || format.Schema == nil {
+		t.Fatalf("expected the schema to be sent as response format, got %+v", format)
+	}
+	last := requests[2].Messages
+	if feedback := last[len(last)-1]; feedback.Role != "user" || !strings.Contains(feedback.Content, "schema mismatch") {
+		t.Fatalf("expected the validation error to be fed back, got %+v", feedback)
+	}
+}
+
+func TestChatStructuredReadsForcedToolCall(t *testing.T) {
+	ag := newStructuredTestAgent(t, []*providers.UnifiedResponse{{
+		ToolCalls: []providers.UnifiedToolCall{{
+			ID:        "toolu_1",
+			Name:      "structured_output",
+			Arguments: map[string]interface{}{"city": "Paris"},
+		}},
+		FinishReason: "tool_calls",
+	}}, new(int), nil)
+
+	output, err := ag.ChatStructured(context.Background(), &testSession{}, "Where is the Louvre?", placeSchema)
+	if err != nil {
+		t.Fatalf("ChatStructured failed: %v", err)
+	}
+	if string(output) != `{"city":"Paris"}` {
+		t.Fatalf("expected the tool input as output, got %s", output)
+	}
+}
+
+func TestChatStructuredGivesUpAfterRepeatedInvalidOutput(t *testing.T) {
+	callCount := new(int)
+	ag := newStructuredTestAgent(t, []*providers.UnifiedResponse{{Content: "[1, 2]"}}, callCount, nil)
+
+	_, err := ag.ChatStructured(context.Background(), &testSession{}, "List a city", nil)
+	if !errors.Is(err, ErrInvalidStructuredOutput) {
+		t.Fatalf("expected ErrInvalidStructuredOutput, got %v", err)
+	}
+	if *callCount != structuredOutputAttempts {
+		t.Fatalf("expected %d attempts, got %d", structuredOutputAttempts, *callCount)
+	}
+}
+
+func TestChatStructuredRejectsInvalidSchema(t *testing.T) {
+	callCount := new(int)
+	ag := newStructuredTestAgent(t, nil, callCount, nil)
+
+	_, err := ag.ChatStructured(context.Background(), &testSession{}, "hi", map[string]interface{}{"type": 7})
+	if err == nil || *callCount != 0 {
+		t.Fatalf("expected a bad schema to fail before calling the model, got %v after %d calls", err, *callCount)
+	}
+}
@@ -0,0 +1,182 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"nekobot/pkg/config"
+)
+
+const (
+	toolBudgetStrategyPriority = "priority"
+	toolBudgetStrategyRecent   = "recent"
+)
+
+// toolPromptBudget limits how many tool descriptions are rendered into the
+// system prompt. rank returns tool names in the order they should be kept.
+type toolPromptBudget struct {
+	maxTokens int
+	rank      func() []string
+}
+
+// toolUsageTracker remembers when each tool was last executed so the prompt
+// budget can favour recently used tools.
+type toolUsageTracker struct {
+	mu       sync.RWMutex
+	lastUsed map[string]time.Time
+}
+
+func newToolUsageTracker() *toolUsageTracker {
+	return &toolUsageTracker{lastUsed: make(map[string]time.Time)}
+}
+
+func (t *toolUsageTracker) record(toolName string) {
+	if t == nil {
+		return
+	}
+	name := strings.TrimSpace(toolName)
+	if name == "" {
+		return
+	}
+	t.mu.Lock()
+	t.lastUsed[name] = time.Now()
+	t.mu.Unlock()
+}
+
+// recent returns used tool names, most recent first.
+func (t *toolUsageTracker) recent() []string {
+	if t == nil {
+		return nil
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	names := make([]string, 0, len(t.lastUsed))
+	for name := range t.lastUsed {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		left, right := t.lastUsed[names[i]], t.lastUsed[names[j]]
+		if left.Equal(right) {
+			return names[i] < names[j]
+		}
+		return left.After(right)
+	})
+	return names
+}
+
+// rankToolsForPrompt orders tool names by configured priority, then by
+// recency of use when the strategy asks for it.
+func rankToolsForPrompt(cfg config.ToolPromptBudgetConfig, usage *toolUsageTracker) []string {
+	ranked := make([]string, 0, len(cfg.Priority))
+	seen := make(map[string]struct{}, len(cfg.Priority))
+	appendName := func(name string) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return
+		}
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		ranked = append(ranked, name)
+	}
+
+	for _, name := range cfg.Priority {
+		appendName(name)
+	}
+	strategy := strings.TrimSpace(strings.ToLower(cfg.Strategy))
+	if strategy == "" || strategy == toolBudgetStrategyRecent {
+		for _, name := range usage.recent() {
+			appendName(name)
+		}
+	}
+	return ranked
+}
+
+// SetToolPromptBudget bounds the estimated token size of the tools section.
+// A non-positive maxTokens disables trimming.
+func (cb *ContextBuilder) SetToolPromptBudget(maxTokens int, rank func() []string) {
+	cb.toolBudget = toolPromptBudget{maxTokens: maxTokens, rank: rank}
+}
+
+// selectToolDescriptions applies the prompt budget to sorted descriptions and
+// returns the kept descriptions (still sorted) plus the number hidden.
+func (cb *ContextBuilder) selectToolDescriptions(descriptions []string) ([]string, int) {
+	budget := cb.toolBudget
+	if budget.maxTokens <= 0 || len(descriptions) == 0 {
+		return descriptions, 0
+	}
+
+	total := 0
+	for _, desc := range descriptions {
+		total += estimateTextTokens(desc)
+	}
+	if total <= budget.maxTokens {
+		return descriptions, 0
+	}
+
+	byName := make(map[string]string, len(descriptions))
+	for _, desc := range descriptions {
+		byName[toolNameFromDescription(desc)] = desc
+	}
+
+	ordered := make([]string, 0, len(descriptions))
+	used := make(map[string]struct{}, len(descriptions))
+	if budget.rank != nil {
+		for _, name := range budget.rank() {
+			desc, ok := byName[name]
+			if !ok {
+				continue
+			}
+			if _, dup := used[desc]; dup {
+				continue
+			}
+			used[desc] = struct{}{}
+			ordered = append(ordered, desc)
+		}
+	}
+	for _, desc := range descriptions {
+		if _, ok := used[desc]; ok {
+			continue
+		}
+		ordered = append(ordered, desc)
+	}
+
+	kept := make([]string, 0, len(ordered))
+	spent := 0
+	for _, desc := range ordered {
+		cost := estimateTextTokens(desc)
+		if spent+cost > budget.maxTokens {
+			continue
+		}
+		spent += cost
+		kept = append(kept, desc)
+	}
+	sort.Strings(kept)
+	return kept, len(descriptions) - len(kept)
+}
+
+func hiddenToolsNote(hidden int) string {
+	return fmt.Sprintf("_%d tools hidden to save context. Call `list_tools` to see every available tool._", hidden)
+}
+
+// toolNameFromDescription extracts the tool name from a "**name**: description" entry.
+func toolNameFromDescription(desc string) string {
+	trimmed := strings.TrimSpace(desc)
+	if strings.HasPrefix(trimmed, "**") {
+		rest := trimmed[2:]
+		if end := strings.Index(rest, "**"); end > 0 {
+			return rest[:end]
+		}
+	}
+	return trimmed
+}
+
+// estimateTextTokens applies the estimateTokens heuristic to a single string.
+func estimateTextTokens(text string) int {
+	return utf8.RuneCountInString(text) * 2 / 5
+}
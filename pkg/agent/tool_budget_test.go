@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+	promptmemory "nekobot/pkg/memory/prompt"
+)
+
+func newToolBudgetTestBuilder(t *testing.T, descriptions []string) *ContextBuilder {
+	t.Helper()
+	workspace := t.TempDir()
+	cb := NewContextBuilderWithMemory(workspace, promptmemory.NewStoreWithBackend(workspace, promptmemory.NewNoopBackend()))
+	cb.SetToolDescriptionsFunc(func() []string {
+		return append([]string(nil), descriptions...)
+	})
+	return cb
+}
+
+func TestBuildToolsSection_BudgetKeepsPriorityToolsAndNotesHidden(t *testing.T) {
+	long := strings.Repeat("x", 200)
+	cb := newToolBudgetTestBuilder(t, []string{
+		"**alpha**: " + long,
+		"**beta**: " + long,
+		"**exec**: " + long,
+		"**read_file**: " + long,
+	})
+	cfg := config.ToolPromptBudgetConfig{
+		MaxTokens: 180,
+		Strategy:  toolBudgetStrategyPriority,
+		Priority:  []string{"read_file", "exec"},
+	}
+	cb.SetToolPromptBudget(cfg.MaxTokens, func() []string {
+		return rankToolsForPrompt(cfg, nil)
+	})
+
+	section := cb.buildToolsSection()
+	for _, name := range []string{"**read_file**", "**exec**"} {
+		if !strings.Contains(section, name) {
+			t.Fatalf("expected priority tool %s in section: %q", name, section)
+		}
+	}
+	for _, name := range []string{"**alpha**", "**beta**"} {
+		if strings.Contains(section, name) {
+			t.Fatalf("expected %s trimmed from section: %q", name, section)
+		}
+	}
+	if !strings.Contains(section, "2 tools hidden") || !strings.Contains(section, "list_tools") {
+		t.Fatalf("expected hidden-count note in section: %q", section)
+	}
+}
+
+func TestBuildToolsSection_NoTrimWithinBudget(t *testing.T) {
+	cb := newToolBudgetTestBuilder(t, []string{"**alpha**: short", "**beta**: short"})
+	cb.SetToolPromptBudget(1000, nil)
+
+	section := cb.buildToolsSection()
+	if !strings.Contains(section, "**alpha**") || !strings.Contains(section, "**beta**") {
+		t.Fatalf("expected all tools within budget: %q", section)
+	}
+	if strings.Contains(section, "hidden") {
+		t.Fatalf("did not expect hidden note within budget: %q", section)
+	}
+}
+
+func TestRankToolsForPrompt_RecentStrategyFavoursRecentlyUsedTools(t *testing.T) {
+	usage := newToolUsageTracker()
+	usage.record("web_fetch")
+	usage.record("exec")
+
+	ranked := rankToolsForPrompt(config.ToolPromptBudgetConfig{
+		Strategy: toolBudgetStrategyRecent,
+		Priority: []string{"read_file"},
+	}, usage)
+	if len(ranked) != 3 || ranked[0] != "read_file" {
+		t.Fatalf("expected priority tool first, got %v", ranked)
+	}
+	if ranked[1] != "exec" && ranked[2] != "exec" {
+		t.Fatalf("expected recently used tools after priority, got %v", ranked)
+	}
+
+	priorityOnly := rankToolsForPrompt(config.ToolPromptBudgetConfig{
+		Strategy: toolBudgetStrategyPriority,
+		Priority: []string{"read_file"},
+	}, usage)
+	if len(priorityOnly) != 1 {
+		t.Fatalf("expected priority strategy to ignore usage, got %v", priorityOnly)
+	}
+}
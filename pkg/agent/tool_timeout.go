@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	bladestools "github.com/go-kratos/blades/tools"
+)
+
+// ErrToolTimeout reports that a tool call exceeded its configured timeout.
+var ErrToolTimeout = errors.New("tool timed out")
+
+type toolCallResult struct {
+	output string
+	err    error
+}
+
+func (a *Agent) toolTimeout(toolName string, isMCP bool) time.Duration {
+	if a == nil || a.config == nil {
+		return 0
+	}
+	return a.config.Tools.ToolTimeout(toolName, isMCP)
+}
+
+// runToolWithTimeout executes fn under a derived deadline. Tools that ignore
+// context cancellation are abandoned once the deadline passes so a hung tool
+// cannot block the turn; their late result is discarded.
+func runToolWithTimeout(
+	ctx context.Context,
+	toolName string,
+	timeout time.Duration,
+	fn func(context.Context) (string, error),
+) (string, error) {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	toolCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan toolCallResult, 1)
+	go func() {
+		output, err := fn(toolCtx)
+		done <- toolCallResult{output: output, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		if result.err != nil && errors.Is(toolCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			return "", fmt.Errorf("%w: %s exceeded %s", ErrToolTimeout, toolName, timeout)
+		}
+		return result.output, result.err
+	case <-toolCtx.Done():
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", fmt.Errorf("%w: %s exceeded %s", ErrToolTimeout, toolName, timeout)
+	}
+}
+
+// timeoutToolResolver applies per-tool timeouts to tools resolved outside the
+// nekobot registry, such as MCP server tools.
+type timeoutToolResolver struct {
+	resolver bladestools.Resolver
+	agent    *Agent
+	isMCP    bool
+}
+
+func (r *timeoutToolResolver) Resolve(ctx context.Context) ([]bladestools.Tool, error) {
+	resolved, err := r.resolver.Resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := make([]bladestools.Tool, 0, len(resolved))
+	for _, tool := range resolved {
+		if tool == nil {
+			continue
+		}
+		timeout := r.agent.toolTimeout(tool.Name(), r.isMCP)
+		if timeout <= 0 {
+			wrapped = append(wrapped, tool)
+			continue
+		}
+		wrapped = append(wrapped, &timeoutTool{Tool: tool, timeout: timeout})
+	}
+	return wrapped, nil
+}
+
+type timeoutTool struct {
+	bladestools.Tool
+	timeout time.Duration
+}
+
+func (t *timeoutTool) Handle(ctx context.Context, input string) (string, error) {
+	output, err := runToolWithTimeout(ctx, t.Name(), t.timeout, func(toolCtx context.Context) (string, error) {
+		return t.Tool.Handle(toolCtx, input)
+	})
+	if errors.Is(err, ErrToolTimeout) {
+		return fmt.Sprintf("Error: %v", err), nil
+	}
+	return output, err
+}
@@ -0,0 +1,153 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/providers"
+)
+
+type slowStubTool struct {
+	name  string
+	delay time.Duration
+
+	mu        sync.Mutex
+	cancelled bool
+}
+
+func (t *slowStubTool) Name() string        { return t.name }
+func (t *slowStubTool) Description() string { return "sleeps before answering" }
+func (t *slowStubTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+}
+
+func (t *slowStubTool) Execute(ctx context.Context, _ map[string]interface{}) (string, error) {
+	select {
+	case <-time.After(t.delay):
+		return "finished", nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		t.cancelled = true
+		t.mu.Unlock()
+		return "", ctx.Err()
+	}
+}
+
+func (t *slowStubTool) wasCancelled() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cancelled
+}
+
+func TestToolsConfigToolTimeoutPrecedence(t *testing.T) {
+	cfg := config.ToolsConfig{
+		TimeoutSeconds: 60,
+		Timeouts: map[string]int{
+			"web_fetch": 5,
+			"mcp":       15,
+			"mcp_tool":  3,
+		},
+	}
+
+	if got := cfg.ToolTimeout("web_fetch", false); got != 5*time.Second {
+		t.Fatalf("expected explicit web_fetch timeout, got %s", got)
+	}
+	if got := cfg.ToolTimeout("mcp_tool", true); got != 3*time.Second {
+		t.Fatalf("expected explicit MCP tool timeout, got %s", got)
+	}
+	if got := cfg.ToolTimeout("other_mcp_tool", true); got != 15*time.Second {
+		t.Fatalf("expected mcp fallback timeout, got %s", got)
+	}
+	if got := cfg.ToolTimeout("read_file", false); got != 60*time.Second {
+		t.Fatalf("expected global default timeout, got %s", got)
+	}
+}
+
+func TestExecuteToolCallCancelsSlowToolAtConfiguredTimeout(t *testing.T) {
+	ag := newRoutingTestAgent(t, orchestratorLegacy)
+	ag.config.Tools.TimeoutSeconds = 0
+	ag.config.Tools.Timeouts = map[string]int{"slow_tool": 1}
+	slow := &slowStubTool{name: "slow_tool", delay: 10 * time.Second}
+	ag.tools.MustRegister(slow)
+
+	start := time.Now()
+	_, err := ag.executeToolCall(context.Background(), providers.UnifiedToolCall{Name: "slow_tool"})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrToolTimeout) {
+		t.Fatalf("expected tool timeout error, got %v", err)
+	}
+	if elapsed > 5*time.Second {
+		t.Fatalf("expected tool to be cancelled near 1s, took %s", elapsed)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !slow.wasCancelled() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected slow tool context to be cancelled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestChatContinuesAfterToolTimeout(t *testing.T) {
+	providerKind := failoverTestProviderKind(t, "tool-timeout")
+	callCount := new(int)
+	var (
+		mu           sync.Mutex
+		toolMessages []string
+	)
+	registerFailoverTestProviderWithResponses(t, providerKind, callCount, []*providers.UnifiedResponse{
+		{
+			ToolCalls: []providers.UnifiedToolCall{{
+				ID:        "call-1",
+				Name:      "slow_tool",
+				Arguments: map[string]interface{}{},
+			}},
+			FinishReason: "tool_calls",
+		},
+		{Content: "done after timeout", FinishReason: "stop"},
+	}, func(req *providers.UnifiedRequest) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, msg := range req.Messages {
+			if msg.Role == "tool" {
+				toolMessages = append(toolMessages, msg.Content)
+			}
+		}
+	})
+
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Orchestrator = orchestratorLegacy
+	cfg.Agents.Defaults.Provider = "primary"
+	cfg.Agents.Defaults.Model = "test-model"
+	cfg.Providers = []config.ProviderProfile{{
+		Name:         "primary",
+		ProviderKind: providerKind,
+		Models:       []string{"test-model"},
+		DefaultModel: "test-model",
+	}}
+	cfg.Tools.Timeouts = map[string]int{"slow_tool": 1}
+
+	ag := newFailoverTestAgent(t, cfg)
+	ag.maxIterations = 3
+	ag.tools.MustRegister(&slowStubTool{name: "slow_tool", delay: 10 * time.Second})
+
+	response, err := ag.ChatWithProviderModel(context.Background(), &testSession{}, "hello", "primary", "test-model")
+	if err != nil {
+		t.Fatalf("expected chat to continue after tool timeout, got %v", err)
+	}
+	if response != "done after timeout" {
+		t.Fatalf("unexpected response: %q", response)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(toolMessages) == 0 || !strings.Contains(toolMessages[len(toolMessages)-1], "tool timed out") {
+		t.Fatalf("expected timed out tool result passed back to model, got %v", toolMessages)
+	}
+}
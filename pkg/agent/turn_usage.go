@@ -0,0 +1,49 @@
+package agent
+
+import (
+	"context"
+	"sync"
+
+	"nekobot/pkg/providers"
+)
+
+// TurnUsage totals the tokens of the LLM calls made during one chat turn.
+type TurnUsage struct {
+	LLMCalls         int
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// turnUsageCollector sums the usage reported by each LLM call of a turn.
+type turnUsageCollector struct {
+	mu    sync.Mutex
+	usage TurnUsage
+}
+
+func withTurnUsage(ctx context.Context) (context.Context, *turnUsageCollector) {
+	collector := &turnUsageCollector{}
+	return context.WithValue(ctx, promptContextTurnUsageKey, collector), collector
+}
+
+// recordTurnUsage charges one LLM call to the turn in ctx, if any.
+func recordTurnUsage(ctx context.Context, usageInfo *providers.UnifiedUsage) {
+	collector, _ := ctx.Value(promptContextTurnUsageKey).(*turnUsageCollector)
+	if collector == nil {
+		return
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.usage.LLMCalls++
+	if usageInfo != nil {
+		collector.usage.PromptTokens += usageInfo.PromptTokens
+		collector.usage.CompletionTokens += usageInfo.CompletionTokens
+		collector.usage.TotalTokens += usageInfo.TotalTokens
+	}
+}
+
+func (c *turnUsageCollector) snapshot() TurnUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usage
+}
@@ -0,0 +1,290 @@
+// Package alerts notifies operators about critical runtime events through an
+// admin channel or webhook, with deduplication, rate limiting and escalation.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+// Severity ranks how urgent an alert is.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+const (
+	defaultDedupWindow = 10 * time.Minute
+	deliverTimeout     = 10 * time.Second
+)
+
+// ParseSeverity parses a severity name. Unknown values map to warning.
+func ParseSeverity(value string) Severity {
+	switch Severity(strings.TrimSpace(strings.ToLower(value))) {
+	case SeverityInfo:
+		return SeverityInfo
+	case SeverityCritical:
+		return SeverityCritical
+	default:
+		return SeverityWarning
+	}
+}
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityInfo:
+		return 0
+	case SeverityCritical:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func (s Severity) next() Severity {
+	switch s {
+	case SeverityInfo:
+		return SeverityWarning
+	default:
+		return SeverityCritical
+	}
+}
+
+// Alert is a single operator notification.
+type Alert struct {
+	// Key identifies the condition for deduplication, e.g. "providers.exhausted".
+	Key      string            `json:"key"`
+	Severity Severity          `json:"severity"`
+	Title    string            `json:"title"`
+	Message  string            `json:"message,omitempty"`
+	Source   string            `json:"source,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	Time     time.Time         `json:"time"`
+
+	// Escalated is set when the alert bypassed deduplication because its
+	// severity rose; Previous holds the severity it escalated from.
+	Escalated bool     `json:"escalated,omitempty"`
+	Previous  Severity `json:"previous,omitempty"`
+	// Suppressed counts duplicates dropped since the last delivery of Key.
+	Suppressed int `json:"suppressed,omitempty"`
+}
+
+// Sink delivers alerts to a destination.
+type Sink interface {
+	Send(ctx context.Context, alert Alert) error
+}
+
+type keyState struct {
+	lastSent   time.Time
+	severity   Severity
+	suppressed int
+}
+
+// Manager decides which alerts are delivered and fans them out to sinks.
+type Manager struct {
+	log   *logger.Logger
+	sinks []Sink
+
+	minSeverity   Severity
+	window        time.Duration
+	escalateAfter int
+	maxPerHour    int
+	enabled       bool
+
+	mu     sync.Mutex
+	state  map[string]*keyState
+	recent []time.Time
+	now    func() time.Time
+}
+
+// NewManager creates an alert manager. A disabled config or no sinks yields a
+// manager whose Raise is a no-op.
+func NewManager(cfg config.AlertsConfig, log *logger.Logger, sinks ...Sink) *Manager {
+	window := time.Duration(cfg.DedupWindowSeconds) * time.Second
+	if window <= 0 {
+		window = defaultDedupWindow
+	}
+	active := make([]Sink, 0, len(sinks))
+	for _, sink := range sinks {
+		if sink != nil {
+			active = append(active, sink)
+		}
+	}
+	return &Manager{
+		log:           log,
+		sinks:         active,
+		minSeverity:   ParseSeverity(cfg.MinSeverity),
+		window:        window,
+		escalateAfter: cfg.EscalateAfter,
+		maxPerHour:    cfg.MaxPerHour,
+		enabled:       cfg.Enabled && len(active) > 0,
+		state:         map[string]*keyState{},
+		now:           time.Now,
+	}
+}
+
+// Enabled reports whether alerts are delivered at all.
+func (m *Manager) Enabled() bool {
+	return m != nil && m.enabled
+}
+
+// Raise records an alert and delivers it unless it is below the minimum
+// severity, a duplicate inside the dedup window, or over the hourly limit.
+// It reports whether the alert was delivered.
+func (m *Manager) Raise(ctx context.Context, alert Alert) bool {
+	if !m.Enabled() {
+		return false
+	}
+	alert, ok := m.admit(alert)
+	if !ok {
+		return false
+	}
+	return m.deliver(ctx, alert)
+}
+
+// deliver sends an admitted alert to every sink and reports whether at least
+// one accepted it.
+func (m *Manager) deliver(ctx context.Context, alert Alert) bool {
+	deliverCtx, cancel := context.WithTimeout(ctx, deliverTimeout)
+	defer cancel()
+
+	delivered := false
+	for _, sink := range m.sinks {
+		if err := sink.Send(deliverCtx, alert); err != nil {
+			// Warn rather than Error so a failing sink cannot feed the log hook.
+			m.logWarn("Failed to deliver alert", zap.String("key", alert.Key), zap.Error(err))
+			continue
+		}
+		delivered = true
+	}
+	return delivered
+}
+
+// admit applies severity filtering, deduplication, escalation and the hourly
+// limit, returning the alert as it should be delivered.
+func (m *Manager) admit(alert Alert) (Alert, bool) {
+	alert.Key = strings.TrimSpace(alert.Key)
+	if alert.Key == "" {
+		alert.Key = alert.Title
+	}
+	alert.Severity = ParseSeverity(string(alert.Severity))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if alert.Time.IsZero() {
+		alert.Time = now
+	}
+
+	state := m.state[alert.Key]
+	if state != nil && now.Sub(state.lastSent) < m.window {
+		switch {
+		case alert.Severity.rank() > state.severity.rank():
+			alert.Escalated = true
+			alert.Previous = state.severity
+		case m.escalateAfter > 0 && state.suppressed+1 >= m.escalateAfter && state.severity != SeverityCritical:
+			alert.Escalated = true
+			alert.Previous = state.severity
+			alert.Severity = state.severity.next()
+		default:
+			state.suppressed++
+			return alert, false
+		}
+	}
+	if alert.Severity.rank() < m.minSeverity.rank() {
+		return alert, false
+	}
+
+	// Escalations skip the hourly limit; they are what the limit protects.
+	if !alert.Escalated && !m.allowRate(now) {
+		if state != nil {
+			state.suppressed++
+		}
+		return alert, false
+	}
+
+	if state != nil {
+		alert.Suppressed = state.suppressed
+	}
+	m.state[alert.Key] = &keyState{lastSent: now, severity: alert.Severity}
+	m.recent = append(m.recent, now)
+	m.prune(now)
+	return alert, true
+}
+
+func (m *Manager) allowRate(now time.Time) bool {
+	if m.maxPerHour <= 0 {
+		return true
+	}
+	cutoff := now.Add(-time.Hour)
+	kept := m.recent[:0]
+	for _, sent := range m.recent {
+		if sent.After(cutoff) {
+			kept = append(kept, sent)
+		}
+	}
+	m.recent = kept
+	return len(m.recent) < m.maxPerHour
+}
+
+// prune drops dedup state that can no longer suppress anything.
+func (m *Manager) prune(now time.Time) {
+	for key, state := range m.state {
+		if now.Sub(state.lastSent) >= m.window && state.suppressed == 0 {
+			delete(m.state, key)
+		}
+	}
+}
+
+func (m *Manager) logWarn(msg string, fields ...zap.Field) {
+	if m.log != nil {
+		m.log.Warn(msg, fields...)
+	}
+}
+
+// Format renders an alert as plain text for chat channels.
+func Format(alert Alert) string {
+	var sb strings.Builder
+	icon := "⚠️"
+	switch alert.Severity {
+	case SeverityCritical:
+		icon = "🚨"
+	case SeverityInfo:
+		icon = "ℹ️"
+	}
+	_, _ = fmt.Fprintf(&sb, "%s [%s] %s", icon, strings.ToUpper(string(alert.Severity)), alert.Title)
+	if alert.Escalated && alert.Previous != "" {
+		_, _ = fmt.Fprintf(&sb, " (escalated from %s)", alert.Previous)
+	}
+	if alert.Message != "" {
+		sb.WriteString("\n")
+		sb.WriteString(alert.Message)
+	}
+	if len(alert.Fields) > 0 {
+		keys := make([]string, 0, len(alert.Fields))
+		for key := range alert.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			_, _ = fmt.Fprintf(&sb, "\n%s: %s", key, alert.Fields[key])
+		}
+	}
+	if alert.Suppressed > 0 {
+		_, _ = fmt.Fprintf(&sb, "\n(%d similar alerts suppressed)", alert.Suppressed)
+	}
+	return sb.String()
+}
@@ -0,0 +1,168 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/providers"
+)
+
+type recordingSink struct {
+	alerts []Alert
+}
+
+func (s *recordingSink) Send(ctx context.Context, alert Alert) error {
+	s.alerts = append(s.alerts, alert)
+	return nil
+}
+
+func newTestManager(t *testing.T, cfg config.AlertsConfig) (*Manager, *recordingSink, *time.Time) {
+	t.Helper()
+	cfg.Enabled = true
+	sink := &recordingSink{}
+	m := NewManager(cfg, nil, sink)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	return m, sink, &now
+}
+
+func TestRaiseSuppressesDuplicatesWithinWindow(t *testing.T) {
+	m, sink, now := newTestManager(t, config.AlertsConfig{DedupWindowSeconds: 60})
+	alert := Alert{Key: "db.write", Severity: SeverityWarning, Title: "DB write failed"}
+
+	if !m.Raise(context.Background(), alert) {
+		t.Fatal("expected first alert to be delivered")
+	}
+	*now = now.Add(30 * time.Second)
+	if m.Raise(context.Background(), alert) {
+		t.Fatal("expected duplicate inside the window to be suppressed")
+	}
+	if m.Raise(context.Background(), alert) {
+		t.Fatal("expected second duplicate inside the window to be suppressed")
+	}
+
+	*now = now.Add(31 * time.Second)
+	if !m.Raise(context.Background(), alert) {
+		t.Fatal("expected alert after the window to be delivered")
+	}
+	if len(sink.alerts) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(sink.alerts))
+	}
+	if sink.alerts[1].Suppressed != 2 {
+		t.Fatalf("expected suppressed count 2 on redelivery, got %d", sink.alerts[1].Suppressed)
+	}
+	if !strings.Contains(Format(sink.alerts[1]), "2 similar alerts suppressed") {
+		t.Fatalf("expected suppressed note in %q", Format(sink.alerts[1]))
+	}
+}
+
+func TestRaiseEscalatesOnHigherSeverity(t *testing.T) {
+	m, sink, now := newTestManager(t, config.AlertsConfig{DedupWindowSeconds: 600})
+
+	m.Raise(context.Background(), Alert{Key: "providers.exhausted", Severity: SeverityWarning, Title: "Providers degraded"})
+	*now = now.Add(time.Minute)
+	if !m.Raise(context.Background(), Alert{Key: "providers.exhausted", Severity: SeverityCritical, Title: "All providers failed"}) {
+		t.Fatal("expected higher severity to bypass deduplication")
+	}
+	if m.Raise(context.Background(), Alert{Key: "providers.exhausted", Severity: SeverityCritical, Title: "All providers failed"}) {
+		t.Fatal("expected repeated critical alert to be suppressed")
+	}
+
+	if len(sink.alerts) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(sink.alerts))
+	}
+	escalated := sink.alerts[1]
+	if !escalated.Escalated || escalated.Previous != SeverityWarning || escalated.Severity != SeverityCritical {
+		t.Fatalf("expected escalation from warning to critical, got %+v", escalated)
+	}
+	if !strings.Contains(Format(escalated), "escalated from warning") {
+		t.Fatalf("expected escalation note in %q", Format(escalated))
+	}
+}
+
+func TestRaiseEscalatesAfterRepeatedDuplicates(t *testing.T) {
+	m, sink, _ := newTestManager(t, config.AlertsConfig{DedupWindowSeconds: 600, EscalateAfter: 3})
+	alert := Alert{Key: "providers.auth:openai", Severity: SeverityWarning, Title: "Provider openai rejected requests (auth)"}
+
+	for i := 0; i < 4; i++ {
+		m.Raise(context.Background(), alert)
+	}
+
+	if len(sink.alerts) != 2 {
+		t.Fatalf("expected initial alert and one escalation, got %d", len(sink.alerts))
+	}
+	if got := sink.alerts[1]; !got.Escalated || got.Severity != SeverityCritical {
+		t.Fatalf("expected repeat escalation to critical, got %+v", got)
+	}
+}
+
+func TestRaiseHonorsMinSeverityAndHourlyLimit(t *testing.T) {
+	m, sink, now := newTestManager(t, config.AlertsConfig{MinSeverity: "warning", MaxPerHour: 2})
+
+	if m.Raise(context.Background(), Alert{Key: "info", Severity: SeverityInfo, Title: "FYI"}) {
+		t.Fatal("expected info alert below min severity to be dropped")
+	}
+	m.Raise(context.Background(), Alert{Key: "a", Title: "A"})
+	m.Raise(context.Background(), Alert{Key: "b", Title: "B"})
+	if m.Raise(context.Background(), Alert{Key: "c", Title: "C"}) {
+		t.Fatal("expected third alert within the hour to be rate limited")
+	}
+	*now = now.Add(61 * time.Minute)
+	if !m.Raise(context.Background(), Alert{Key: "c", Title: "C"}) {
+		t.Fatal("expected alert to be delivered once the hour has passed")
+	}
+	if len(sink.alerts) != 3 {
+		t.Fatalf("expected 3 deliveries, got %d", len(sink.alerts))
+	}
+}
+
+func TestAlertFromLogEntryUsesProviderTaxonomy(t *testing.T) {
+	exhausted := &providers.FallbackExhaustedError{Attempts: []providers.FallbackAttempt{{Provider: "openai", Error: errors.New("boom")}}}
+	alert, ok := AlertFromLogEntry(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "All provider fallbacks failed"}, []zapcore.Field{zap.Error(exhausted)})
+	if !ok || alert.Key != "providers.exhausted" || alert.Severity != SeverityCritical {
+		t.Fatalf("expected critical providers.exhausted alert, got %+v ok=%v", alert, ok)
+	}
+
+	authErr := &providers.FailoverError{Reason: providers.FailoverReasonAuth, Provider: "openai", Wrapped: errors.New("401")}
+	alert, ok = AlertFromLogEntry(zapcore.Entry{Level: zapcore.WarnLevel, Message: "Provider request failed"}, []zapcore.Field{zap.Error(authErr)})
+	if !ok || alert.Key != "providers.auth:openai" || alert.Severity != SeverityWarning {
+		t.Fatalf("expected auth warning alert, got %+v ok=%v", alert, ok)
+	}
+
+	if _, ok := AlertFromLogEntry(zapcore.Entry{Level: zapcore.WarnLevel, Message: "slow"}, nil); ok {
+		t.Fatal("expected plain warnings to be ignored")
+	}
+
+	alert, ok = AlertFromLogEntry(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "Failed to save session"}, []zapcore.Field{zap.Error(errors.New("database is locked"))})
+	if !ok || alert.Key != "Failed to save session" || alert.Message != "database is locked" {
+		t.Fatalf("expected error log alert, got %+v ok=%v", alert, ok)
+	}
+}
+
+func TestWebhookSinkPostsJSON(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, server.Client())
+	if err := sink.Send(context.Background(), Alert{Key: "k", Severity: SeverityCritical, Title: "Down"}); err != nil {
+		t.Fatalf("send webhook: %v", err)
+	}
+	if payload["key"] != "k" || payload["severity"] != "critical" || !strings.Contains(payload["text"].(string), "Down") {
+		t.Fatalf("unexpected webhook payload: %v", payload)
+	}
+}
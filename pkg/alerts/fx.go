@@ -0,0 +1,61 @@
+package alerts
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+// Module provides the alert manager and hooks it into the logger.
+var Module = fx.Module("alerts",
+	fx.Provide(NewManagerFromConfig),
+	fx.Invoke(registerLogHook),
+)
+
+// Params holds dependencies for creating an alert manager.
+type Params struct {
+	fx.In
+
+	Config *config.Config
+	Log    *logger.Logger
+	Bus    bus.Bus `optional:"true"`
+}
+
+// NewManagerFromConfig creates an alert manager from the alerts config section.
+func NewManagerFromConfig(p Params) *Manager {
+	cfg := p.Config.Alerts
+
+	var sinks []Sink
+	if sink := NewChannelSink(p.Bus, cfg.Channel, cfg.SessionID); sink != nil {
+		sinks = append(sinks, sink)
+	}
+	if sink := NewWebhookSink(cfg.WebhookURL, nil); sink != nil {
+		sinks = append(sinks, sink)
+	}
+	return NewManager(cfg, p.Log, sinks...)
+}
+
+func registerLogHook(lc fx.Lifecycle, cfg *config.Config, log *logger.Logger, m *Manager) {
+	if !m.Enabled() || !cfg.Alerts.LogEvents {
+		return
+	}
+	var remove func()
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			remove = log.AddEntryHook(m.LogHook())
+			log.Info("Alerts enabled", zap.String("min_severity", string(m.minSeverity)))
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if remove != nil {
+				remove()
+			}
+			return nil
+		},
+	})
+}
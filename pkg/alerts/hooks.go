@@ -0,0 +1,105 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+
+	"nekobot/pkg/logger"
+	"nekobot/pkg/providers"
+)
+
+// LogHook returns a logger entry hook that raises alerts for error-level
+// entries and for classified provider failures. Deduplication runs inline;
+// delivery happens in the background so logging never blocks on a sink.
+func (m *Manager) LogHook() logger.EntryHook {
+	return func(entry zapcore.Entry, fields []zapcore.Field) {
+		if !m.Enabled() {
+			return
+		}
+		alert, ok := AlertFromLogEntry(entry, fields)
+		if !ok {
+			return
+		}
+		if alert, ok = m.admit(alert); ok {
+			go m.deliver(context.Background(), alert)
+		}
+	}
+}
+
+// AlertFromLogEntry maps a log entry onto an alert using the provider error
+// taxonomy first and the log level second:
+//   - FallbackExhaustedError (every provider failed) is critical;
+//   - auth and billing FailoverErrors are warnings keyed per provider, so
+//     repeated credential failures escalate through EscalateAfter;
+//   - other error-level entries are warnings (critical from DPanic up),
+//     keyed by their message.
+func AlertFromLogEntry(entry zapcore.Entry, fields []zapcore.Field) (Alert, bool) {
+	var logErr error
+	for _, field := range fields {
+		if field.Type != zapcore.ErrorType {
+			continue
+		}
+		err, ok := field.Interface.(error)
+		if !ok || err == nil {
+			continue
+		}
+		logErr = err
+
+		var exhausted *providers.FallbackExhaustedError
+		if errors.As(err, &exhausted) {
+			return Alert{
+				Key:      "providers.exhausted",
+				Severity: SeverityCritical,
+				Title:    "All providers failed",
+				Message:  err.Error(),
+				Source:   "providers",
+				Time:     entry.Time,
+			}, true
+		}
+
+		var failover *providers.FailoverError
+		if errors.As(err, &failover) {
+			switch failover.Reason {
+			case providers.FailoverReasonAuth, providers.FailoverReasonBilling:
+				return Alert{
+					Key:      fmt.Sprintf("providers.%s:%s", failover.Reason, failover.Provider),
+					Severity: SeverityWarning,
+					Title:    fmt.Sprintf("Provider %s rejected requests (%s)", failover.Provider, failover.Reason),
+					Message:  err.Error(),
+					Source:   "providers",
+					Fields: map[string]string{
+						"provider": failover.Provider,
+						"model":    failover.Model,
+					},
+					Time: entry.Time,
+				}, true
+			}
+		}
+	}
+
+	if entry.Level < zapcore.ErrorLevel {
+		return Alert{}, false
+	}
+	severity := SeverityWarning
+	if entry.Level >= zapcore.DPanicLevel {
+		severity = SeverityCritical
+	}
+	alert := Alert{
+		Key:      strings.TrimPrefix(entry.LoggerName+":"+entry.Message, ":"),
+		Severity: severity,
+		Title:    entry.Message,
+		Source:   entry.LoggerName,
+		Time:     entry.Time,
+	}
+	if logErr != nil {
+		alert.Message = logErr.Error()
+	}
+	if entry.Caller.Defined {
+		alert.Fields = map[string]string{"caller": entry.Caller.TrimmedPath()}
+	}
+	return alert, true
+}
@@ -0,0 +1,95 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"nekobot/pkg/bus"
+)
+
+// ChannelSink posts alerts into an admin chat through the message bus.
+type ChannelSink struct {
+	bus       bus.Bus
+	channelID string
+	sessionID string
+}
+
+// NewChannelSink returns a sink for the given channel and chat session, or nil
+// when either is missing.
+func NewChannelSink(messageBus bus.Bus, channelID, sessionID string) *ChannelSink {
+	channelID = strings.TrimSpace(channelID)
+	sessionID = strings.TrimSpace(sessionID)
+	if messageBus == nil || channelID == "" || sessionID == "" {
+		return nil
+	}
+	return &ChannelSink{bus: messageBus, channelID: channelID, sessionID: sessionID}
+}
+
+// Send implements Sink.
+func (s *ChannelSink) Send(ctx context.Context, alert Alert) error {
+	return s.bus.SendOutbound(&bus.Message{
+		ChannelID: s.channelID,
+		SessionID: s.sessionID,
+		Type:      bus.MessageTypeText,
+		Content:   Format(alert),
+		Timestamp: alert.Time,
+		Data: map[string]interface{}{
+			"alert_key":      alert.Key,
+			"alert_severity": string(alert.Severity),
+		},
+	})
+}
+
+// WebhookSink posts alerts as JSON to an HTTP endpoint.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink returns a webhook sink, or nil when url is empty.
+// A nil client uses http.DefaultClient; requests are bounded by the context.
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return nil
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookSink{url: url, client: client}
+}
+
+// webhookPayload adds a preformatted text field for chat-style webhooks.
+type webhookPayload struct {
+	Alert
+	Text string `json:"text"`
+}
+
+// Send implements Sink.
+func (s *WebhookSink) Send(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(webhookPayload{Alert: alert, Text: Format(alert)})
+	if err != nil {
+		return fmt.Errorf("encode alert: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post alert: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("post alert: status %d", resp.StatusCode)
+	}
+	return nil
+}
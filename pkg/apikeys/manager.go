@@ -0,0 +1,254 @@
+// Package apikeys stores long-lived API keys that let scripts call the WebUI
+// API and the gateway without a login token.
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"nekobot/pkg/storage/ent"
+	"nekobot/pkg/storage/ent/apikey"
+)
+
+// Prefix starts every API key, so callers can tell keys from login tokens.
+const Prefix = "nbk_"
+
+// Scopes limit what a key may do. A key without scopes may do everything its
+// user may.
+const (
+	ScopeRead    = "read"    // GET requests on /api
+	ScopeWrite   = "write"   // Every request on /api
+	ScopeGateway = "gateway" // Gateway WebSocket and REST endpoints
+)
+
+// lastUsedInterval throttles last-used updates to one write per key and
+// interval.
+const lastUsedInterval = time.Minute
+
+var (
+	// ErrKeyNotFound is returned when a key ID does not exist.
+	ErrKeyNotFound = errors.New("api key not found")
+	// ErrInvalidKey is returned for unknown, revoked or expired keys.
+	ErrInvalidKey = errors.New("invalid api key")
+	// ErrInvalidInput is returned when a key cannot be created as requested.
+	ErrInvalidInput = errors.New("invalid api key input")
+)
+
+// Key is an API key without its secret.
+type Key struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	UserID     string     `json:"user_id"`
+	TenantID   string     `json:"tenant_id,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Allows reports whether the key grants scope. Write implies read.
+func (k Key) Allows(scope string) bool {
+	if len(k.Scopes) == 0 || slices.Contains(k.Scopes, scope) {
+		return true
+	}
+	return scope == ScopeRead && slices.Contains(k.Scopes, ScopeWrite)
+}
+
+// CreateInput describes a new key.
+type CreateInput struct {
+	Name      string
+	UserID    string
+	TenantID  string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// Manager persists API keys.
+type Manager struct {
+	client *ent.Client
+	now    func() time.Time
+}
+
+// NewManager creates an API key manager on the shared Ent client.
+func NewManager(client *ent.Client) (*Manager, error) {
+	if client == nil {
+		return nil, fmt.Errorf("ent client is nil")
+	}
+	return &Manager{client: client, now: time.Now}, nil
+}
+
+// IsKey reports whether token looks like an API key rather than a JWT.
+func IsKey(token string) bool {
+	return strings.HasPrefix(strings.TrimSpace(token), Prefix)
+}
+
+// Create stores a new key and returns it with its secret. The secret is not
+// stored and cannot be shown again.
+func (m *Manager) Create(ctx context.Context, input CreateInput) (*Key, string, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	input.UserID = strings.TrimSpace(input.UserID)
+	if input.Name == "" {
+		return nil, "", fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if input.UserID == "" {
+		return nil, "", fmt.Errorf("%w: user is required", ErrInvalidInput)
+	}
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(m.now()) {
+		return nil, "", fmt.Errorf("%w: expiry must be in the future", ErrInvalidInput)
+	}
+	encodedScopes, err := json.Marshal(scopes)
+	if err != nil {
+		return nil, "", fmt.Errorf("encode scopes: %w", err)
+	}
+
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", fmt.Errorf("generate api key: %w", err)
+	}
+	secret := Prefix + hex.EncodeToString(buf)
+
+	rec, err := m.client.APIKey.Create().
+		SetName(input.Name).
+		SetPrefix(secret[:len(Prefix)+8]).
+		SetKeyHash(hashKey(secret)).
+		SetUserID(input.UserID).
+		SetTenantID(strings.TrimSpace(input.TenantID)).
+		SetScopesJSON(string(encodedScopes)).
+		SetNillableExpiresAt(input.ExpiresAt).
+		Save(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("create api key: %w", err)
+	}
+	key := toKey(rec)
+	return &key, secret, nil
+}
+
+// List returns keys newest first. An empty userID lists every user's keys.
+func (m *Manager) List(ctx context.Context, userID string) ([]Key, error) {
+	q := m.client.APIKey.Query().Order(ent.Desc(apikey.FieldCreatedAt))
+	if userID = strings.TrimSpace(userID); userID != "" {
+		q = q.Where(apikey.UserIDEQ(userID))
+	}
+	recs, err := q.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	out := make([]Key, 0, len(recs))
+	for _, rec := range recs {
+		out = append(out, toKey(rec))
+	}
+	return out, nil
+}
+
+// Get returns one key by ID.
+func (m *Manager) Get(ctx context.Context, id string) (*Key, error) {
+	rec, err := m.client.APIKey.Get(ctx, strings.TrimSpace(id))
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrKeyNotFound
+		}
+		return nil, fmt.Errorf("get api key: %w", err)
+	}
+	key := toKey(rec)
+	return &key, nil
+}
+
+// Revoke disables a key. Revoking a revoked key is a no-op.
+func (m *Manager) Revoke(ctx context.Context, id string) error {
+	rec, err := m.client.APIKey.Get(ctx, strings.TrimSpace(id))
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return ErrKeyNotFound
+		}
+		return fmt.Errorf("get api key: %w", err)
+	}
+	if rec.RevokedAt != nil {
+		return nil
+	}
+	if err := m.client.APIKey.UpdateOneID(rec.ID).SetRevokedAt(m.now()).Exec(ctx); err != nil {
+		return fmt.Errorf("revoke api key: %w", err)
+	}
+	return nil
+}
+
+// Authenticate resolves a secret to its key. Unknown, revoked and expired
+// keys return ErrInvalidKey.
+func (m *Manager) Authenticate(ctx context.Context, secret string) (*Key, error) {
+	secret = strings.TrimSpace(secret)
+	if !IsKey(secret) {
+		return nil, ErrInvalidKey
+	}
+	rec, err := m.client.APIKey.Query().Where(apikey.KeyHashEQ(hashKey(secret))).Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrInvalidKey
+		}
+		return nil, fmt.Errorf("load api key: %w", err)
+	}
+	now := m.now()
+	if rec.RevokedAt != nil || (rec.ExpiresAt != nil && !rec.ExpiresAt.After(now)) {
+		return nil, ErrInvalidKey
+	}
+	if rec.LastUsedAt == nil || now.Sub(*rec.LastUsedAt) >= lastUsedInterval {
+		if updated, err := m.client.APIKey.UpdateOneID(rec.ID).SetLastUsedAt(now).Save(ctx); err == nil {
+			rec = updated
+		}
+	}
+	key := toKey(rec)
+	return &key, nil
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	out := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		switch scope {
+		case "":
+			continue
+		case ScopeRead, ScopeWrite, ScopeGateway:
+		default:
+			return nil, fmt.Errorf("%w: unknown scope %q", ErrInvalidInput, scope)
+		}
+		if !slices.Contains(out, scope) {
+			out = append(out, scope)
+		}
+	}
+	slices.Sort(out)
+	return out, nil
+}
+
+func hashKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func toKey(rec *ent.APIKey) Key {
+	key := Key{
+		ID:         rec.ID,
+		Name:       rec.Name,
+		Prefix:     rec.Prefix,
+		UserID:     rec.UserID,
+		TenantID:   rec.TenantID,
+		Scopes:     []string{},
+		ExpiresAt:  rec.ExpiresAt,
+		LastUsedAt: rec.LastUsedAt,
+		RevokedAt:  rec.RevokedAt,
+		CreatedAt:  rec.CreatedAt,
+	}
+	_ = json.Unmarshal([]byte(rec.ScopesJSON), &key.Scopes)
+	return key
+}
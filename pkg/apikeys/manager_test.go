@@ -0,0 +1,115 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/storage/ent"
+)
+
+func TestManagerCreateAuthenticateRevoke(t *testing.T) {
+	ctx := context.Background()
+	mgr := newTestManager(t)
+
+	key, secret, err := mgr.Create(ctx, CreateInput{Name: "ci", UserID: "user-1", Scopes: []string{" Write ", "gateway", "write"}})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if !strings.HasPrefix(secret, Prefix) || !strings.HasPrefix(secret, key.Prefix) {
+		t.Fatalf("unexpected secret %q for prefix %q", secret, key.Prefix)
+	}
+	if got := strings.Join(key.Scopes, ","); got != "gateway,write" {
+		t.Fatalf("expected normalized scopes, got %q", got)
+	}
+
+	got, err := mgr.Authenticate(ctx, secret)
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if got.ID != key.ID || got.UserID != "user-1" || got.LastUsedAt == nil {
+		t.Fatalf("unexpected authenticated key: %+v", got)
+	}
+	if !got.Allows(ScopeRead) || !got.Allows(ScopeGateway) {
+		t.Fatalf("expected write key to allow read and gateway, got %+v", got.Scopes)
+	}
+	if _, err := mgr.Authenticate(ctx, secret+"x"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected ErrInvalidKey for unknown key, got %v", err)
+	}
+
+	if err := mgr.Revoke(ctx, key.ID); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if _, err := mgr.Authenticate(ctx, secret); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected revoked key to be rejected, got %v", err)
+	}
+	if err := mgr.Revoke(ctx, "missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestManagerRejectsExpiredKeysAndBadInput(t *testing.T) {
+	ctx := context.Background()
+	mgr := newTestManager(t)
+
+	expiresAt := time.Now().Add(time.Hour)
+	_, secret, err := mgr.Create(ctx, CreateInput{Name: "short", UserID: "user-1", Scopes: []string{ScopeRead}, ExpiresAt: &expiresAt})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	key, err := mgr.Authenticate(ctx, secret)
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if key.Allows(ScopeWrite) || key.Allows(ScopeGateway) {
+		t.Fatalf("expected read key to allow only reads, got %+v", key.Scopes)
+	}
+
+	mgr.now = func() time.Time { return expiresAt.Add(time.Second) }
+	if _, err := mgr.Authenticate(ctx, secret); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected expired key to be rejected, got %v", err)
+	}
+
+	if _, _, err := mgr.Create(ctx, CreateInput{Name: "bad", UserID: "user-1", Scopes: []string{"admin"}}); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected unknown scope to be rejected, got %v", err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if _, _, err := mgr.Create(ctx, CreateInput{Name: "old", UserID: "user-1", ExpiresAt: &past}); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected past expiry to be rejected, got %v", err)
+	}
+}
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Fatalf("close ent client: %v", err)
+		}
+	})
+
+	mgr, err := NewManager(client)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	return mgr
+}
+
+func newTestEntClient(t *testing.T, cfg *config.Config) *ent.Client {
+	t.Helper()
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		t.Fatalf("open runtime ent client: %v", err)
+	}
+	if err := config.EnsureRuntimeEntSchema(client); err != nil {
+		_ = client.Close()
+		t.Fatalf("ensure runtime schema: %v", err)
+	}
+	return client
+}
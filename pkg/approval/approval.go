@@ -6,10 +6,19 @@
 package approval
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+// storeTimeout bounds each write of a request to the store.
+const storeTimeout = 5 * time.Second
+
 // Mode defines the approval behavior.
 type Mode string
 
@@ -26,8 +35,18 @@ const (
 	Approved Decision = "approved"
 	Denied   Decision = "denied"
 	Pending  Decision = "pending"
+	// Expired marks requests nobody decided before their TTL ran out. They
+	// count as denied.
+	Expired Decision = "expired"
 )
 
+// ArgRequestedBy is the argument naming the person a queued request acts
+// for, as opposed to a tool call the agent made.
+const ArgRequestedBy = "requested_by"
+
+// ErrSelfApproval is returned when someone approves their own request.
+var ErrSelfApproval = errors.New("requests cannot be approved by their requester")
+
 // Request represents a pending approval request.
 type Request struct {
 	ID        string                 `json:"id"`
@@ -36,6 +55,21 @@ type Request struct {
 	SessionID string                 `json:"session_id"`
 	Decision  Decision               `json:"decision"`
 	Reason    string                 `json:"reason,omitempty"`
+	DecidedBy string                 `json:"decided_by,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	ExpiresAt *time.Time             `json:"expires_at,omitempty"`
+	DecidedAt *time.Time             `json:"decided_at,omitempty"`
+	// PromptChannel and PromptSession name the chat a prompt-mode request
+	// was asked in. They are not persisted: a prompt does not outlive the
+	// turn waiting on it.
+	PromptChannel string `json:"prompt_channel,omitempty"`
+	PromptSession string `json:"prompt_session,omitempty"`
+}
+
+// RequestedBy returns who the request acts for, or "" for tool calls.
+func (r *Request) RequestedBy() string {
+	requester, _ := r.Arguments[ArgRequestedBy].(string)
+	return requester
 }
 
 // Config configures the approval system.
@@ -43,49 +77,84 @@ type Config struct {
 	Mode      Mode     `json:"mode"`      // Approval mode
 	Allowlist []string `json:"allowlist"` // Tools that bypass approval (always auto-approved)
 	Denylist  []string `json:"denylist"`  // Tools that are always denied
+	// AlwaysPrompt lists tools that still ask in prompt mode even when the
+	// allowlist matches them.
+	AlwaysPrompt []string `json:"always_prompt"`
+	// TTL expires queued requests nobody decided in time. Zero disables.
+	TTL time.Duration `json:"ttl"`
+	// Rules pick an action by tool name and arguments. The first match
+	// wins, after the denylist and before the allowlist and mode.
+	Rules []Rule `json:"rules,omitempty"`
+	// Workspace is where rules with InsideWorkspace resolve paths.
+	Workspace string `json:"workspace,omitempty"`
 }
 
 // Manager handles tool execution approvals.
 type Manager struct {
 	config  Config
+	rules   []compiledRule
+	store   Store
 	pending map[string]*Request
 	session map[string]Mode
 	mu      sync.RWMutex
-	counter int
+	now     func() time.Time
 	// PromptFunc is called in prompt mode to ask the user.
 	// Returns true if approved. Nil means auto-approve.
-	PromptFunc func(req *Request) (bool, error)
+	PromptFunc func(ctx context.Context, req *Request) (bool, error)
+	// OnDecision, when set, is called with a copy of each request a person
+	// approves or denies, after the decision is stored.
+	OnDecision func(req Request)
+	// waiters are closed when the request with that ID is decided.
+	waiters map[string]chan struct{}
+	// handlers run the action behind a request once a person decides it,
+	// by tool name.
+	handlers map[string]func(req Request)
+	// subscribers receive every queued, decided and expired request.
+	subscribers map[chan Request]struct{}
+	// required are tools that are never approved automatically.
+	required map[string]struct{}
 }
 
 // NewManager creates a new approval manager.
 func NewManager(cfg Config) *Manager {
 	return &Manager{
-		config:  cfg,
-		pending: make(map[string]*Request),
-		session: make(map[string]Mode),
+		config:   cfg,
+		rules:    compileRules(cfg.Rules),
+		pending:  make(map[string]*Request),
+		session:  make(map[string]Mode),
+		waiters:  make(map[string]chan struct{}),
+		handlers: make(map[string]func(Request)),
+		now:      time.Now,
 	}
 }
 
-// CheckApproval determines whether a tool call should be approved.
-// Returns the decision and, for pending requests, the request ID.
-func (m *Manager) CheckApproval(toolName string, args map[string]interface{}, sessionID string) (Decision, string, error) {
-	// Check denylist first
-	if m.isInList(toolName, m.config.Denylist) {
-		return Denied, "", nil
+// NewManagerWithStore creates an approval manager that persists requests to
+// store and reloads the ones still pending, so they survive restarts.
+func NewManagerWithStore(ctx context.Context, cfg Config, store Store) (*Manager, error) {
+	m := NewManager(cfg)
+	if store == nil {
+		return m, nil
 	}
-
-	// Check allowlist (bypass approval)
-	if m.isInList(toolName, m.config.Allowlist) {
-		return Approved, "", nil
+	pending, err := store.Pending(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load pending approvals: %w", err)
 	}
+	m.store = store
+	for _, req := range pending {
+		m.pending[req.ID] = req
+	}
+	return m, nil
+}
 
-	mode := m.modeForSession(sessionID)
-
-	switch mode {
-	case ModeAuto:
-		return Approved, "", nil
+// CheckApproval determines whether a tool call should be approved.
+// Returns the decision and, for pending requests, the request ID. ctx is
+// passed to PromptFunc in prompt mode.
+func (m *Manager) CheckApproval(ctx context.Context, toolName string, args map[string]interface{}, sessionID string) (Decision, string, error) {
+	switch m.Evaluate(toolName, args, sessionID).Action {
+	case ActionDeny:
+		return Denied, "", nil
 
-	case ModePrompt:
+	case ActionPrompt:
 		if m.PromptFunc == nil {
 			return Approved, "", nil
 		}
@@ -94,7 +163,7 @@ func (m *Manager) CheckApproval(toolName string, args map[string]interface{}, se
 			Arguments: args,
 			SessionID: sessionID,
 		}
-		approved, err := m.PromptFunc(req)
+		approved, err := m.PromptFunc(ctx, req)
 		if err != nil {
 			return Denied, "", fmt.Errorf("prompt error: %w", err)
 		}
@@ -103,8 +172,11 @@ func (m *Manager) CheckApproval(toolName string, args map[string]interface{}, se
 		}
 		return Denied, "", nil
 
-	case ModeManual:
-		id := m.enqueue(toolName, args, sessionID)
+	case ActionManual:
+		id, err := m.enqueue(Request{ToolName: toolName, Arguments: args, SessionID: sessionID}, m.config.TTL)
+		if err != nil {
+			return Denied, "", err
+		}
 		return Pending, id, nil
 
 	default:
@@ -112,13 +184,155 @@ func (m *Manager) CheckApproval(toolName string, args map[string]interface{}, se
 	}
 }
 
+// RequireApproval marks toolName as never approved automatically. Calls
+// that the mode, a session override, the allowlist or a rule would run
+// unattended are queued for a manual decision instead.
+func (m *Manager) RequireApproval(toolName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.required == nil {
+		m.required = make(map[string]struct{})
+	}
+	m.required[toolName] = struct{}{}
+}
+
+// Evaluate reports which action CheckApproval would take for a tool call
+// and what decided it, without prompting or queueing anything.
+func (m *Manager) Evaluate(toolName string, args map[string]interface{}, sessionID string) Evaluation {
+	evaluation := m.evaluate(toolName, args, sessionID)
+	if evaluation.Action != ActionAuto {
+		return evaluation
+	}
+	m.mu.RLock()
+	_, required := m.required[toolName]
+	m.mu.RUnlock()
+	if required {
+		return Evaluation{Action: ActionManual, Source: "required", RuleIndex: -1}
+	}
+	return evaluation
+}
+
+func (m *Manager) evaluate(toolName string, args map[string]interface{}, sessionID string) Evaluation {
+	// Check denylist first
+	if m.isInList(toolName, m.config.Denylist) {
+		return Evaluation{Action: ActionDeny, Source: "denylist", RuleIndex: -1}
+	}
+
+	for i := range m.rules {
+		if rule := &m.rules[i]; rule.matches(toolName, args, m.config.Workspace) {
+			return Evaluation{Action: rule.Action, Source: "rule", Rule: rule.label(i), RuleIndex: i}
+		}
+	}
+
+	mode := m.config.Mode
+	source := "mode"
+	if override, ok := m.GetSessionMode(sessionID); ok {
+		mode, source = override, "session_mode"
+	}
+
+	// Check allowlist (bypass approval)
+	alwaysPrompt := mode == ModePrompt && m.isExactInList(toolName, m.config.AlwaysPrompt)
+	if !alwaysPrompt && m.isInList(toolName, m.config.Allowlist) {
+		return Evaluation{Action: ActionAuto, Source: "allowlist", RuleIndex: -1}
+	}
+
+	switch mode {
+	case ModePrompt:
+		return Evaluation{Action: ActionPrompt, Source: source, RuleIndex: -1}
+	case ModeManual:
+		return Evaluation{Action: ActionManual, Source: source, RuleIndex: -1}
+	default:
+		return Evaluation{Action: ActionAuto, Source: source, RuleIndex: -1}
+	}
+}
+
 // EnqueueRequest forces a tool call into the pending approval queue, bypassing mode checks.
 func (m *Manager) EnqueueRequest(toolName string, args map[string]interface{}, sessionID string) (string, error) {
+	return m.EnqueueRequestWithTTL(toolName, args, sessionID, m.config.TTL)
+}
+
+// EnqueueRequestWithTTL queues a request like EnqueueRequest but expires it
+// after ttl instead of the configured TTL. Zero never expires it.
+func (m *Manager) EnqueueRequestWithTTL(toolName string, args map[string]interface{}, sessionID string, ttl time.Duration) (string, error) {
 	trimmedTool := toolName
 	if trimmedTool == "" {
 		return "", fmt.Errorf("tool name is required")
 	}
-	return m.enqueue(trimmedTool, args, sessionID), nil
+	return m.enqueue(Request{ToolName: trimmedTool, Arguments: args, SessionID: sessionID}, ttl)
+}
+
+// EnqueuePrompt queues a request that is asked in a chat, identified by the
+// channel and its chat session. Chat commands only decide it from there;
+// see PromptedIn.
+func (m *Manager) EnqueuePrompt(toolName string, args map[string]interface{}, sessionID, channel, chatSession string) (string, error) {
+	if toolName == "" {
+		return "", fmt.Errorf("tool name is required")
+	}
+	return m.enqueue(Request{
+		ToolName:      toolName,
+		Arguments:     args,
+		SessionID:     sessionID,
+		PromptChannel: channel,
+		PromptSession: chatSession,
+	}, m.config.TTL)
+}
+
+// PromptedIn reports whether request id is a pending prompt asked in
+// chatSession of channel.
+func (m *Manager) PromptedIn(id, channel, chatSession string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expireLocked()
+	req, ok := m.pending[id]
+	if !ok || req.Decision != Pending || req.PromptChannel == "" {
+		return false
+	}
+	return req.PromptChannel == channel && req.PromptSession == chatSession
+}
+
+// HandleDecided registers fn to run after a person approves or denies a
+// request for toolName, whichever API or channel decided it. Queued
+// requests that stand for an action, rather than a tool call waiting on
+// the decision, carry it out here so every approval path launches it.
+func (m *Manager) HandleDecided(toolName string, fn func(req Request)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if fn == nil {
+		delete(m.handlers, toolName)
+		return
+	}
+	m.handlers[toolName] = fn
+}
+
+// Subscribe returns a channel receiving a copy of every request queued,
+// decided or expired from now on, and a function ending the subscription.
+func (m *Manager) Subscribe() (<-chan Request, func()) {
+	ch := make(chan Request, 16)
+	m.mu.Lock()
+	if m.subscribers == nil {
+		m.subscribers = make(map[chan Request]struct{})
+	}
+	m.subscribers[ch] = struct{}{}
+	m.mu.Unlock()
+
+	return ch, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if _, ok := m.subscribers[ch]; ok {
+			delete(m.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (m *Manager) publishLocked(req *Request) {
+	for ch := range m.subscribers {
+		select {
+		case ch <- copyRequest(req):
+		default:
+			// Slow subscribers miss updates rather than block decisions.
+		}
+	}
 }
 
 // SetSessionMode overrides approval mode for one session.
@@ -162,33 +376,124 @@ func (m *Manager) GetSessionMode(sessionID string) (Mode, bool) {
 
 // Approve approves a pending request by ID.
 func (m *Manager) Approve(id string) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	return m.ApproveAs(id, "")
+}
 
-	req, ok := m.pending[id]
-	if !ok {
-		return fmt.Errorf("request not found: %s", id)
-	}
-	req.Decision = Approved
-	return nil
+// ApproveAs approves a pending request by ID, recording who decided.
+func (m *Manager) ApproveAs(id, actor string) error {
+	return m.decide(id, Approved, actor, "")
 }
 
 // Deny denies a pending request by ID with an optional reason.
 func (m *Manager) Deny(id string, reason string) error {
+	return m.DenyAs(id, "", reason)
+}
+
+// DenyAs denies a pending request by ID, recording who decided and why.
+func (m *Manager) DenyAs(id, actor, reason string) error {
+	return m.decide(id, Denied, actor, reason)
+}
+
+func (m *Manager) decide(id string, decision Decision, actor, reason string) error {
+	decided, err := m.applyDecision(id, decision, actor, reason)
+	if err != nil {
+		return err
+	}
+	if m.OnDecision != nil {
+		m.OnDecision(decided)
+	}
+	m.mu.RLock()
+	handler := m.handlers[decided.ToolName]
+	m.mu.RUnlock()
+	if handler != nil {
+		handler(decided)
+	}
+	return nil
+}
+
+func (m *Manager) applyDecision(id string, decision Decision, actor, reason string) (Request, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.expireLocked()
 
 	req, ok := m.pending[id]
 	if !ok {
-		return fmt.Errorf("request not found: %s", id)
+		return Request{}, fmt.Errorf("request not found: %s", id)
+	}
+	if req.Decision != Pending {
+		return Request{}, fmt.Errorf("request %s is already %s", id, req.Decision)
+	}
+	if requester := req.RequestedBy(); decision == Approved && requester != "" && requester == actor {
+		return Request{}, ErrSelfApproval
 	}
-	req.Decision = Denied
+	previous := *req
+	now := m.now()
+	req.Decision = decision
 	req.Reason = reason
-	return nil
+	req.DecidedBy = actor
+	req.DecidedAt = &now
+	if err := m.saveLocked(req); err != nil {
+		*req = previous
+		return Request{}, err
+	}
+	m.notifyLocked(id)
+	m.publishLocked(req)
+	return copyRequest(req), nil
+}
+
+// Wait blocks until the request is decided or expires and returns the
+// decision. It returns ctx's error if ctx ends first.
+func (m *Manager) Wait(ctx context.Context, id string) (Decision, error) {
+	for {
+		m.mu.Lock()
+		m.expireLocked()
+		req, ok := m.pending[id]
+		if !ok {
+			m.mu.Unlock()
+			return "", fmt.Errorf("request not found: %s", id)
+		}
+		if req.Decision != Pending {
+			decision := req.Decision
+			m.mu.Unlock()
+			return decision, nil
+		}
+		waiter, ok := m.waiters[id]
+		if !ok {
+			waiter = make(chan struct{})
+			m.waiters[id] = waiter
+		}
+		var timer *time.Timer
+		var expiry <-chan time.Time
+		if req.ExpiresAt != nil {
+			timer = time.NewTimer(req.ExpiresAt.Sub(m.now()))
+			expiry = timer.C
+		}
+		m.mu.Unlock()
+
+		select {
+		case <-waiter:
+		case <-expiry:
+		case <-ctx.Done():
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err := ctx.Err(); err != nil {
+			return Pending, err
+		}
+	}
+}
+
+func (m *Manager) notifyLocked(id string) {
+	if waiter, ok := m.waiters[id]; ok {
+		close(waiter)
+		delete(m.waiters, id)
+	}
 }
 
 // GetPending returns all pending approval requests.
 func (m *Manager) GetPending() []*Request {
+	m.ExpireStale()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -203,6 +508,7 @@ func (m *Manager) GetPending() []*Request {
 
 // GetDecision returns the decision for a specific request.
 func (m *Manager) GetDecision(id string) (Decision, error) {
+	m.ExpireStale()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -219,6 +525,7 @@ func (m *Manager) GetRequest(id string) (*Request, bool) {
 		return nil, false
 	}
 
+	m.ExpireStale()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -227,6 +534,12 @@ func (m *Manager) GetRequest(id string) (*Request, bool) {
 		return nil, false
 	}
 
+	copyReq := copyRequest(req)
+	return &copyReq, true
+}
+
+// copyRequest copies req with its own top-level arguments map.
+func copyRequest(req *Request) Request {
 	copyReq := *req
 	if req.Arguments != nil {
 		copyReq.Arguments = make(map[string]interface{}, len(req.Arguments))
@@ -234,7 +547,7 @@ func (m *Manager) GetRequest(id string) (*Request, bool) {
 			copyReq.Arguments[key] = value
 		}
 	}
-	return &copyReq, true
+	return copyReq
 }
 
 // Cleanup removes resolved requests.
@@ -249,20 +562,90 @@ func (m *Manager) Cleanup() {
 	}
 }
 
-func (m *Manager) enqueue(toolName string, args map[string]interface{}, sessionID string) string {
+// ExpireStale marks pending requests past their TTL as expired and returns
+// how many it expired.
+func (m *Manager) ExpireStale() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.expireLocked()
+}
+
+func (m *Manager) expireLocked() int {
+	now := m.now()
+	expired := 0
+	for _, req := range m.pending {
+		if req.Decision != Pending || req.ExpiresAt == nil || now.Before(*req.ExpiresAt) {
+			continue
+		}
+		req.Decision = Expired
+		req.Reason = "approval timed out"
+		req.DecidedAt = &now
+		// A failed write is retried when the store reloads it as pending.
+		_ = m.saveLocked(req)
+		m.notifyLocked(req.ID)
+		m.publishLocked(req)
+		expired++
+	}
+	return expired
+}
+
+// History returns decided and expired requests, newest first. Without a
+// store only requests not yet removed by Cleanup are returned.
+func (m *Manager) History(ctx context.Context, q HistoryQuery) ([]*Request, error) {
+	m.ExpireStale()
+	if m.store != nil {
+		return m.store.History(ctx, q)
+	}
+
+	m.mu.RLock()
+	var requests []*Request
+	for _, req := range m.pending {
+		if req.Decision != Pending && q.matches(req) {
+			copyReq := *req
+			requests = append(requests, &copyReq)
+		}
+	}
+	m.mu.RUnlock()
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].DecidedAt.After(*requests[j].DecidedAt)
+	})
+	if limit := q.limit(); len(requests) > limit {
+		requests = requests[:limit]
+	}
+	return requests, nil
+}
+
+func (m *Manager) enqueue(template Request, ttl time.Duration) (string, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.counter++
-	id := fmt.Sprintf("approval-%d", m.counter)
-	m.pending[id] = &Request{
-		ID:        id,
-		ToolName:  toolName,
-		Arguments: args,
-		SessionID: sessionID,
-		Decision:  Pending,
+	id := "approval-" + uuid.NewString()[:8]
+	req := &template
+	req.ID = id
+	req.Decision = Pending
+	req.CreatedAt = m.now()
+	if ttl > 0 {
+		expiresAt := req.CreatedAt.Add(ttl)
+		req.ExpiresAt = &expiresAt
+	}
+	if err := m.saveLocked(req); err != nil {
+		return "", err
 	}
-	return id
+	m.pending[id] = req
+	m.publishLocked(req)
+	return id, nil
+}
+
+func (m *Manager) saveLocked(req *Request) error {
+	if m.store == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	if err := m.store.Save(ctx, req); err != nil {
+		return fmt.Errorf("persist approval %s: %w", req.ID, err)
+	}
+	return nil
 }
 
 func (m *Manager) isInList(name string, list []string) bool {
@@ -274,14 +657,12 @@ func (m *Manager) isInList(name string, list []string) bool {
 	return false
 }
 
-func (m *Manager) modeForSession(sessionID string) Mode {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	if sessionID != "" {
-		if mode, ok := m.session[sessionID]; ok {
-			return mode
+// isExactInList matches names only, so "*" cannot opt every tool in.
+func (m *Manager) isExactInList(name string, list []string) bool {
+	for _, item := range list {
+		if item == name {
+			return true
 		}
 	}
-	return m.config.Mode
+	return false
 }
@@ -1,14 +1,17 @@
 package approval
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestAutoMode(t *testing.T) {
 	mgr := NewManager(Config{Mode: ModeAuto})
 
-	decision, _, err := mgr.CheckApproval("exec", nil, "")
+	decision, _, err := mgr.CheckApproval(context.Background(), "exec", nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -23,7 +26,7 @@ func TestDenylistBlocksBeforeMode(t *testing.T) {
 		Denylist: []string{"exec"},
 	})
 
-	decision, _, err := mgr.CheckApproval("exec", nil, "")
+	decision, _, err := mgr.CheckApproval(context.Background(), "exec", nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -38,7 +41,7 @@ func TestAllowlistBypassesManualMode(t *testing.T) {
 		Allowlist: []string{"read_file"},
 	})
 
-	decision, _, err := mgr.CheckApproval("read_file", nil, "")
+	decision, _, err := mgr.CheckApproval(context.Background(), "read_file", nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -47,13 +50,70 @@ func TestAllowlistBypassesManualMode(t *testing.T) {
 	}
 }
 
+func TestAlwaysPromptOverridesAllowlistInPromptMode(t *testing.T) {
+	var prompted []string
+	mgr := NewManager(Config{
+		Mode:         ModePrompt,
+		Allowlist:    []string{"*"},
+		AlwaysPrompt: []string{"git_commit"},
+	})
+	mgr.PromptFunc = func(_ context.Context, req *Request) (bool, error) {
+		prompted = append(prompted, fmt.Sprintf("%s:%v", req.ToolName, req.Arguments["message"]))
+		return false, nil
+	}
+
+	decision, _, err := mgr.CheckApproval(context.Background(), "git_commit", map[string]interface{}{"message": "fix typo"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decision != Denied {
+		t.Fatalf("expected git_commit to be prompted and denied, got %s", decision)
+	}
+	decision, _, err = mgr.CheckApproval(context.Background(), "git_status", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decision != Approved {
+		t.Fatalf("expected allowlisted git_status to bypass the prompt, got %s", decision)
+	}
+	if len(prompted) != 1 || prompted[0] != "git_commit:fix typo" {
+		t.Fatalf("unexpected prompts %v", prompted)
+	}
+
+	mgr.SetSessionMode("s1", ModeAuto)
+	if decision, _, _ := mgr.CheckApproval(context.Background(), "git_commit", nil, "s1"); decision != Approved {
+		t.Fatalf("expected auto mode to approve git_commit, got %s", decision)
+	}
+}
+
+func TestRequiredToolsAreNeverAutoApproved(t *testing.T) {
+	mgr := NewManager(Config{
+		Mode:      ModeAuto,
+		Allowlist: []string{"k8s_apply"},
+		Rules:     []Rule{{Tool: "k8s_*", Action: ActionAuto}},
+	})
+	mgr.RequireApproval("k8s_apply")
+
+	evaluation := mgr.Evaluate("k8s_apply", nil, "s1")
+	if evaluation.Action != ActionManual || evaluation.Source != "required" {
+		t.Fatalf("expected a manual decision, got %+v", evaluation)
+	}
+	mgr.SetSessionMode("s1", ModePrompt)
+	if evaluation := mgr.Evaluate("k8s_apply", nil, "s1"); evaluation.Action != ActionManual {
+		t.Fatalf("expected rules that auto-approve to be overridden, got %+v", evaluation)
+	}
+	if evaluation := mgr.Evaluate("k8s_get", nil, "s1"); evaluation.Action != ActionAuto {
+		t.Fatalf("expected other tools to follow their rule, got %+v", evaluation)
+	}
+}
+
 func TestWildcardAllowlist(t *testing.T) {
 	mgr := NewManager(Config{
 		Mode:      ModeManual,
 		Allowlist: []string{"*"},
 	})
 
-	decision, _, err := mgr.CheckApproval("anything", nil, "")
+	decision, _, err := mgr.CheckApproval(context.Background(), "anything", nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -62,10 +122,91 @@ func TestWildcardAllowlist(t *testing.T) {
 	}
 }
 
+func TestOnDecisionReceivesDecidedRequests(t *testing.T) {
+	mgr := NewManager(Config{Mode: ModeManual})
+	var decided []Request
+	mgr.OnDecision = func(req Request) { decided = append(decided, req) }
+
+	_, id, err := mgr.CheckApproval(context.Background(), "exec", nil, "sess-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.DenyAs(id, "alice", "too risky"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.ApproveAs(id, "bob"); err == nil {
+		t.Fatal("expected deciding twice to fail")
+	}
+	if len(decided) != 1 {
+		t.Fatalf("expected one decision callback, got %d", len(decided))
+	}
+	if got := decided[0]; got.ID != id || got.Decision != Denied || got.DecidedBy != "alice" || got.Reason != "too risky" {
+		t.Fatalf("unexpected decided request: %+v", got)
+	}
+}
+
+func TestHandleDecidedRunsRequestedActions(t *testing.T) {
+	mgr := NewManager(Config{Mode: ModeAuto})
+	var handled []Request
+	mgr.HandleDecided("tool_session_spawn", func(req Request) { handled = append(handled, req) })
+
+	id, err := mgr.EnqueueRequest("tool_session_spawn", map[string]interface{}{ArgRequestedBy: "alice"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.ApproveAs(id, "alice"); !errors.Is(err, ErrSelfApproval) {
+		t.Fatalf("expected self-approval to fail, got %v", err)
+	}
+	if len(handled) != 0 {
+		t.Fatalf("expected no action before approval, got %+v", handled)
+	}
+	if err := mgr.ApproveAs(id, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if len(handled) != 1 || handled[0].ID != id || handled[0].Decision != Approved || handled[0].RequestedBy() != "alice" {
+		t.Fatalf("expected the approved request handled once, got %+v", handled)
+	}
+
+	if _, err := mgr.EnqueueRequest("exec", nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, req := range mgr.GetPending() {
+		if err := mgr.ApproveAs(req.ID, "admin"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(handled) != 1 {
+		t.Fatalf("expected other tools not to reach the handler, got %+v", handled)
+	}
+}
+
+func TestEnqueueRequestWithTTLExpiresAndNotifies(t *testing.T) {
+	mgr := NewManager(Config{Mode: ModeAuto})
+	now := time.Now()
+	mgr.now = func() time.Time { return now }
+	events, cancel := mgr.Subscribe()
+	defer cancel()
+
+	id, err := mgr.EnqueueRequestWithTTL("tool_session_spawn", nil, "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := <-events; got.ID != id || got.Decision != Pending {
+		t.Fatalf("expected the queued request, got %+v", got)
+	}
+	now = now.Add(2 * time.Minute)
+	if expired := mgr.ExpireStale(); expired != 1 {
+		t.Fatalf("expected one expired request, got %d", expired)
+	}
+	if got := <-events; got.ID != id || got.Decision != Expired {
+		t.Fatalf("expected the expiry, got %+v", got)
+	}
+}
+
 func TestManualModeQueuesPending(t *testing.T) {
 	mgr := NewManager(Config{Mode: ModeManual})
 
-	decision, id, err := mgr.CheckApproval("exec", map[string]interface{}{"cmd": "ls"}, "sess-1")
+	decision, id, err := mgr.CheckApproval(context.Background(), "exec", map[string]interface{}{"cmd": "ls"}, "sess-1")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -90,8 +231,8 @@ func TestApproveAndDeny(t *testing.T) {
 	mgr := NewManager(Config{Mode: ModeManual})
 
 	// Queue two requests
-	_, id1, _ := mgr.CheckApproval("exec", nil, "")
-	_, id2, _ := mgr.CheckApproval("write_file", nil, "")
+	_, id1, _ := mgr.CheckApproval(context.Background(), "exec", nil, "")
+	_, id2, _ := mgr.CheckApproval(context.Background(), "write_file", nil, "")
 
 	// Approve first
 	if err := mgr.Approve(id1); err != nil {
@@ -118,6 +259,44 @@ func TestApproveAndDeny(t *testing.T) {
 	}
 }
 
+func TestExpiredRequestsCannotBeDecided(t *testing.T) {
+	mgr := NewManager(Config{Mode: ModeManual, TTL: time.Minute})
+	now := time.Now()
+	mgr.now = func() time.Time { return now }
+
+	_, id, _ := mgr.CheckApproval(context.Background(), "exec", nil, "sess-1")
+	now = now.Add(2 * time.Minute)
+
+	if len(mgr.GetPending()) != 0 {
+		t.Fatal("expected the request to leave the pending list once expired")
+	}
+	if decision, _ := mgr.GetDecision(id); decision != Expired {
+		t.Fatalf("expected Expired, got %s", decision)
+	}
+	if err := mgr.Approve(id); err == nil {
+		t.Fatal("expected approving an expired request to fail")
+	}
+}
+
+func TestDecisionsRecordActorAndAreFinal(t *testing.T) {
+	mgr := NewManager(Config{Mode: ModeManual})
+	_, id, _ := mgr.CheckApproval(context.Background(), "exec", nil, "sess-1")
+
+	if err := mgr.DenyAs(id, "alice", "too risky"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.ApproveAs(id, "bob"); err == nil {
+		t.Fatal("expected a second decision to be rejected")
+	}
+	history, err := mgr.History(context.Background(), HistoryQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].DecidedBy != "alice" || history[0].Reason != "too risky" || history[0].DecidedAt == nil {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+}
+
 func TestApproveNonExistent(t *testing.T) {
 	mgr := NewManager(Config{Mode: ModeManual})
 
@@ -129,8 +308,8 @@ func TestApproveNonExistent(t *testing.T) {
 func TestCleanup(t *testing.T) {
 	mgr := NewManager(Config{Mode: ModeManual})
 
-	_, id1, _ := mgr.CheckApproval("exec", nil, "")
-	_, _, _ = mgr.CheckApproval("read_file", nil, "")
+	_, id1, _ := mgr.CheckApproval(context.Background(), "exec", nil, "")
+	_, _, _ = mgr.CheckApproval(context.Background(), "read_file", nil, "")
 
 	// Approve first, leave second pending
 	if err := mgr.Approve(id1); err != nil {
@@ -148,11 +327,11 @@ func TestCleanup(t *testing.T) {
 
 func TestPromptModeApproved(t *testing.T) {
 	mgr := NewManager(Config{Mode: ModePrompt})
-	mgr.PromptFunc = func(req *Request) (bool, error) {
+	mgr.PromptFunc = func(_ context.Context, req *Request) (bool, error) {
 		return true, nil
 	}
 
-	decision, _, err := mgr.CheckApproval("exec", nil, "")
+	decision, _, err := mgr.CheckApproval(context.Background(), "exec", nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -163,11 +342,11 @@ func TestPromptModeApproved(t *testing.T) {
 
 func TestPromptModeDenied(t *testing.T) {
 	mgr := NewManager(Config{Mode: ModePrompt})
-	mgr.PromptFunc = func(req *Request) (bool, error) {
+	mgr.PromptFunc = func(_ context.Context, req *Request) (bool, error) {
 		return false, nil
 	}
 
-	decision, _, err := mgr.CheckApproval("exec", nil, "")
+	decision, _, err := mgr.CheckApproval(context.Background(), "exec", nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -178,11 +357,11 @@ func TestPromptModeDenied(t *testing.T) {
 
 func TestPromptModeError(t *testing.T) {
 	mgr := NewManager(Config{Mode: ModePrompt})
-	mgr.PromptFunc = func(req *Request) (bool, error) {
+	mgr.PromptFunc = func(_ context.Context, req *Request) (bool, error) {
 		return false, fmt.Errorf("connection lost")
 	}
 
-	decision, _, err := mgr.CheckApproval("exec", nil, "")
+	decision, _, err := mgr.CheckApproval(context.Background(), "exec", nil, "")
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -195,7 +374,7 @@ func TestPromptModeNilFunc(t *testing.T) {
 	// When PromptFunc is nil, should auto-approve
 	mgr := NewManager(Config{Mode: ModePrompt})
 
-	decision, _, err := mgr.CheckApproval("exec", nil, "")
+	decision, _, err := mgr.CheckApproval(context.Background(), "exec", nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -206,14 +385,14 @@ func TestPromptModeNilFunc(t *testing.T) {
 
 func TestSessionModeOverrideTakesPrecedence(t *testing.T) {
 	mgr := NewManager(Config{Mode: ModePrompt})
-	mgr.PromptFunc = func(req *Request) (bool, error) {
+	mgr.PromptFunc = func(_ context.Context, req *Request) (bool, error) {
 		t.Fatalf("PromptFunc should not be called when session override applies")
 		return false, nil
 	}
 
 	mgr.SetSessionMode("sess-1", ModeAuto)
 
-	decision, _, err := mgr.CheckApproval("exec", nil, "sess-1")
+	decision, _, err := mgr.CheckApproval(context.Background(), "exec", nil, "sess-1")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -228,7 +407,7 @@ func TestSessionModeOverrideCanBeCleared(t *testing.T) {
 	mgr.SetSessionMode("sess-1", ModeAuto)
 	mgr.ClearSessionMode("sess-1")
 
-	decision, id, err := mgr.CheckApproval("exec", nil, "sess-1")
+	decision, id, err := mgr.CheckApproval(context.Background(), "exec", nil, "sess-1")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -255,7 +434,7 @@ func TestGetSessionModeReturnsOverride(t *testing.T) {
 
 func TestGetRequestReturnsCopy(t *testing.T) {
 	mgr := NewManager(Config{Mode: ModeManual})
-	_, id, err := mgr.CheckApproval("exec", map[string]interface{}{"cmd": "ls"}, "sess-1")
+	_, id, err := mgr.CheckApproval(context.Background(), "exec", map[string]interface{}{"cmd": "ls"}, "sess-1")
 	if err != nil {
 		t.Fatalf("CheckApproval failed: %v", err)
 	}
@@ -277,3 +456,39 @@ func TestGetRequestReturnsCopy(t *testing.T) {
 		t.Fatalf("expected original arguments to remain unchanged, got %+v", reqAgain.Arguments)
 	}
 }
+
+func TestWaitReturnsDecision(t *testing.T) {
+	mgr := NewManager(Config{Mode: ModeManual})
+	id, err := mgr.EnqueueRequest("exec", map[string]interface{}{"cmd": "ls"}, "sess-1")
+	if err != nil {
+		t.Fatalf("EnqueueRequest failed: %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = mgr.ApproveAs(id, "telegram:@alice")
+	}()
+	decision, err := mgr.Wait(context.Background(), id)
+	if err != nil || decision != Approved {
+		t.Fatalf("expected approved, got %s (%v)", decision, err)
+	}
+
+	other, _ := mgr.EnqueueRequest("exec", nil, "sess-1")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := mgr.Wait(ctx, other); err == nil {
+		t.Fatal("expected Wait to give up when the context ends")
+	}
+}
+
+func TestCallbackDataRoundTrip(t *testing.T) {
+	decision, id, ok := ParseCallback(CallbackData(Denied, "approval-1234"))
+	if !ok || decision != Denied || id != "approval-1234" {
+		t.Fatalf("unexpected parse: %s %s %v", decision, id, ok)
+	}
+	for _, data := range []string{"approval:expired:approval-1", "approval:approved:", "feedback:up"} {
+		if _, _, ok := ParseCallback(data); ok {
+			t.Fatalf("expected %q to be rejected", data)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package approval
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DataKeyRequestID marks an outbound chat message as the prompt for the
+// approval request with this ID. Channels with buttons attach approve and
+// deny actions to it; others show the text, which names the /approve and
+// /deny commands.
+const DataKeyRequestID = "approval_request_id"
+
+// CallbackPrefix starts the callback data of approval buttons.
+const CallbackPrefix = "approval:"
+
+// maxPromptArgsChars bounds the arguments shown in a chat prompt.
+const maxPromptArgsChars = 600
+
+// CallbackData encodes a button that applies decision to request id.
+func CallbackData(decision Decision, id string) string {
+	return CallbackPrefix + string(decision) + ":" + id
+}
+
+// ParseCallback decodes CallbackData. It only accepts Approved and Denied.
+func ParseCallback(data string) (Decision, string, bool) {
+	rest, ok := strings.CutPrefix(data, CallbackPrefix)
+	if !ok {
+		return "", "", false
+	}
+	decision, id, ok := strings.Cut(rest, ":")
+	if !ok || strings.TrimSpace(id) == "" {
+		return "", "", false
+	}
+	switch Decision(decision) {
+	case Approved, Denied:
+		return Decision(decision), id, true
+	default:
+		return "", "", false
+	}
+}
+
+// PromptText is the chat message asking whether to run req.
+func PromptText(req *Request) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔐 Tool %s is waiting for approval\n", req.ToolName)
+	if len(req.Arguments) > 0 {
+		if args, err := json.Marshal(req.Arguments); err == nil {
+			text := string(args)
+			if runes := []rune(text); len(runes) > maxPromptArgsChars {
+				text = string(runes[:maxPromptArgsChars]) + "…"
+			}
+			fmt.Fprintf(&b, "Arguments: %s\n", text)
+		}
+	}
+	fmt.Fprintf(&b, "Reply /approve %s or /deny %s [reason].", req.ID, req.ID)
+	return b.String()
+}
+
+// DecisionText reports the outcome of a request, for updating its prompt.
+func DecisionText(decision Decision, actor string) string {
+	var label string
+	switch decision {
+	case Approved:
+		label = "✅ Approved"
+	case Expired:
+		label = "⌛ Expired"
+	default:
+		label = "⛔ Denied"
+	}
+	if actor = strings.TrimSpace(actor); actor != "" {
+		return label + " by " + actor
+	}
+	return label
+}
@@ -1,20 +1,141 @@
 package approval
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/audit"
 	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/storage/ent"
 )
 
+// expireInterval is how often queued requests are checked against their TTL.
+const expireInterval = time.Minute
+
 // Module provides the approval manager for fx dependency injection.
 var Module = fx.Module("approval",
 	fx.Provide(ProvideManager),
 )
 
-// ProvideManager creates an approval manager from config.
-func ProvideManager(cfg *config.Config) *Manager {
-	return NewManager(Config{
-		Mode:      Mode(cfg.Approval.Mode),
-		Allowlist: cfg.Approval.Allowlist,
-		Denylist:  cfg.Approval.Denylist,
+// ManagerParams are the dependencies of ProvideManager.
+type ManagerParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Config    *config.Config
+	Logger    *logger.Logger `optional:"true"`
+	EntClient *ent.Client    `optional:"true"`
+}
+
+// ProvideManager creates an approval manager from config. With a runtime
+// database the queue and its decisions are persisted there.
+func ProvideManager(p ManagerParams) (*Manager, error) {
+	cfg := p.Config
+	managerCfg := Config{
+		Mode:         Mode(cfg.Approval.Mode),
+		Allowlist:    cfg.Approval.Allowlist,
+		Denylist:     cfg.Approval.Denylist,
+		AlwaysPrompt: cfg.Approval.AlwaysPrompt,
+		TTL:          time.Duration(cfg.Approval.TTLSeconds) * time.Second,
+		Rules:        rulesFromConfig(cfg.Approval.Rules),
+		Workspace:    cfg.WorkspacePath(),
+	}
+	if p.EntClient == nil {
+		mgr := NewManager(managerCfg)
+		registerExpiry(p.Lifecycle, mgr, p.Logger)
+		return mgr, nil
+	}
+	store, err := NewEntStore(p.EntClient)
+	if err != nil {
+		return nil, err
+	}
+	mgr, err := NewManagerWithStore(context.Background(), managerCfg, store)
+	if err != nil {
+		return nil, err
+	}
+	// Requests can carry their own TTL, so expiry runs even without a
+	// configured one.
+	registerExpiry(p.Lifecycle, mgr, p.Logger)
+	events, err := audit.NewEventStore(p.EntClient)
+	if err != nil {
+		return nil, err
+	}
+	mgr.OnDecision = auditDecision(events, p.Logger)
+	return mgr, nil
+}
+
+func rulesFromConfig(configured []config.ApprovalRuleConfig) []Rule {
+	rules := make([]Rule, 0, len(configured))
+	for _, rule := range configured {
+		rules = append(rules, Rule{
+			Name:            rule.Name,
+			Tool:            rule.Tool,
+			Args:            rule.Args,
+			InsideWorkspace: rule.InsideWorkspace,
+			Action:          Action(rule.Action),
+		})
+	}
+	return rules
+}
+
+// auditDecision records each decision in the audit log, whichever channel
+// it came from.
+func auditDecision(events *audit.EventStore, log *logger.Logger) func(Request) {
+	return func(req Request) {
+		summary := fmt.Sprintf("%s %s", req.Decision, req.ToolName)
+		if req.SessionID != "" {
+			summary += " in session " + req.SessionID
+		}
+		if req.Reason != "" {
+			summary += ": " + req.Reason
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+		defer cancel()
+		err := events.Record(ctx, audit.Event{
+			Action:  audit.ActionApprovalDecision,
+			Actor:   req.DecidedBy,
+			Target:  req.ID,
+			Success: true,
+			Summary: summary,
+		})
+		if err != nil && log != nil {
+			log.Warn("Failed to audit approval decision", zap.String("request_id", req.ID), zap.Error(err))
+		}
+	}
+}
+
+func registerExpiry(lc fx.Lifecycle, mgr *Manager, log *logger.Logger) {
+	var cancel context.CancelFunc
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			runCtx, c := context.WithCancel(context.Background())
+			cancel = c
+			go func() {
+				ticker := time.NewTicker(expireInterval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-runCtx.Done():
+						return
+					case <-ticker.C:
+						if expired := mgr.ExpireStale(); expired > 0 && log != nil {
+							log.Info("Expired pending approvals", zap.Int("count", expired))
+						}
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			if cancel != nil {
+				cancel()
+			}
+			return nil
+		},
 	})
 }
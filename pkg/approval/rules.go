@@ -0,0 +1,161 @@
+package approval
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Action is what a matching rule does with a tool call.
+type Action string
+
+const (
+	ActionAuto   Action = "auto"   // Run without asking
+	ActionPrompt Action = "prompt" // Ask in the chat the call came from
+	ActionManual Action = "manual" // Queue for explicit approval
+	ActionDeny   Action = "deny"   // Refuse the call
+)
+
+// RegexPrefix marks a rule pattern as a regular expression instead of a glob.
+const RegexPrefix = "re:"
+
+// Rule assigns an action to tool calls by tool name and arguments. Patterns
+// are globs where "*" matches any run of characters, or regular expressions
+// when prefixed with RegexPrefix. Globs match the whole value; regular
+// expressions match anywhere unless anchored.
+type Rule struct {
+	Name string `json:"name,omitempty"`
+	Tool string `json:"tool"` // Tool name pattern; empty matches every tool
+	// Args maps argument names to patterns. Every listed argument must be
+	// present and match. Non-string values are matched as JSON.
+	Args map[string]string `json:"args,omitempty"`
+	// InsideWorkspace requires the call's "path" argument to resolve inside
+	// the workspace.
+	InsideWorkspace bool   `json:"inside_workspace,omitempty"`
+	Action          Action `json:"action"`
+}
+
+// Evaluation explains how CheckApproval would treat a tool call.
+type Evaluation struct {
+	Action Action `json:"action"`
+	// Source is what decided the action: "denylist", "rule", "allowlist",
+	// "session_mode" or "mode".
+	Source    string `json:"source"`
+	Rule      string `json:"rule,omitempty"`
+	RuleIndex int    `json:"rule_index"` // -1 when no rule matched
+}
+
+type compiledRule struct {
+	Rule
+	tool *regexp.Regexp
+	args map[string]*regexp.Regexp
+}
+
+// CompilePattern compiles a rule pattern.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	if expr, ok := strings.CutPrefix(pattern, RegexPrefix); ok {
+		return regexp.Compile(expr)
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// compileRules compiles rules in order. A rule with an invalid pattern keeps
+// its slot, so indexes match the config, but never matches; config
+// validation reports the pattern.
+func compileRules(rules []Rule) []compiledRule {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		c := compiledRule{Rule: rule, args: make(map[string]*regexp.Regexp, len(rule.Args))}
+		var err error
+		if rule.Tool != "" {
+			c.tool, err = CompilePattern(rule.Tool)
+		}
+		for name, pattern := range rule.Args {
+			if err != nil {
+				break
+			}
+			c.args[name], err = CompilePattern(pattern)
+		}
+		if err != nil {
+			c.Action = ""
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled
+}
+
+func (r *compiledRule) matches(toolName string, args map[string]interface{}, workspace string) bool {
+	if r.Action == "" {
+		return false
+	}
+	if r.tool != nil && !r.tool.MatchString(toolName) {
+		return false
+	}
+	for name, re := range r.args {
+		value, ok := args[name]
+		if !ok || !re.MatchString(argString(value)) {
+			return false
+		}
+	}
+	if r.InsideWorkspace {
+		path, _ := args["path"].(string)
+		if !insideWorkspace(workspace, path) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *compiledRule) label(index int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("rule %d", index+1)
+}
+
+func argString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// insideWorkspace reports whether path stays inside workspace. Relative
+// paths resolve against it.
+func insideWorkspace(workspace, path string) bool {
+	path = strings.TrimSpace(path)
+	if path == "" || strings.HasPrefix(path, "~") {
+		return false
+	}
+	if !filepath.IsAbs(path) {
+		clean := filepath.Clean(path)
+		return clean != ".." && !strings.HasPrefix(clean, ".."+string(filepath.Separator))
+	}
+	if workspace == "" {
+		return false
+	}
+	rel, err := filepath.Rel(filepath.Clean(workspace), filepath.Clean(path))
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,78 @@
+package approval
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRulesPickActionByToolAndArguments(t *testing.T) {
+	mgr := NewManager(Config{
+		Mode:      ModeAuto,
+		Denylist:  []string{"shutdown"},
+		Workspace: "/srv/workspace",
+		Rules: []Rule{
+			{Name: "destructive exec", Tool: "exec", Args: map[string]string{"command": `re:rm\s+-(rf|fr)`}, Action: ActionManual},
+			{Name: "workspace writes", Tool: "write_file", InsideWorkspace: true, Action: ActionAuto},
+			{Tool: "write_*", Action: ActionPrompt},
+			{Tool: "*", Args: map[string]string{"timeout": "600"}, Action: ActionDeny},
+		},
+	})
+
+	tests := []struct {
+		tool      string
+		args      map[string]interface{}
+		action    Action
+		source    string
+		rule      string
+		ruleIndex int
+	}{
+		{"exec", map[string]interface{}{"command": "cd /tmp && rm -rf build"}, ActionManual, "rule", "destructive exec", 0},
+		{"exec", map[string]interface{}{"command": "ls -la"}, ActionAuto, "mode", "", -1},
+		{"write_file", map[string]interface{}{"path": "notes/todo.md"}, ActionAuto, "rule", "workspace writes", 1},
+		{"write_file", map[string]interface{}{"path": "/srv/workspace/a.txt"}, ActionAuto, "rule", "workspace writes", 1},
+		{"write_file", map[string]interface{}{"path": "../outside.txt"}, ActionPrompt, "rule", "rule 3", 2},
+		{"write_file", map[string]interface{}{"path": "/etc/passwd"}, ActionPrompt, "rule", "rule 3", 2},
+		{"exec", map[string]interface{}{"command": "sleep 1", "timeout": 600}, ActionDeny, "rule", "rule 4", 3},
+		{"shutdown", nil, ActionDeny, "denylist", "", -1},
+	}
+	for _, tt := range tests {
+		got := mgr.Evaluate(tt.tool, tt.args, "")
+		if got.Action != tt.action || got.Source != tt.source || got.Rule != tt.rule || got.RuleIndex != tt.ruleIndex {
+			t.Errorf("Evaluate(%s, %v) = %+v, want %s from %s %q (%d)", tt.tool, tt.args, got, tt.action, tt.source, tt.rule, tt.ruleIndex)
+		}
+	}
+
+	decision, id, err := mgr.CheckApproval(context.Background(), "exec", map[string]interface{}{"command": "rm -rf /"}, "sess-1")
+	if err != nil || decision != Pending || id == "" {
+		t.Fatalf("expected destructive exec to be queued, got %s %q %v", decision, id, err)
+	}
+}
+
+func TestRulesOverrideAllowlistAndSessionMode(t *testing.T) {
+	mgr := NewManager(Config{
+		Mode:      ModeManual,
+		Allowlist: []string{"exec"},
+		Rules:     []Rule{{Tool: "exec", Args: map[string]string{"command": "*sudo *"}, Action: ActionManual}},
+	})
+	mgr.SetSessionMode("sess-1", ModeAuto)
+
+	if got := mgr.Evaluate("exec", map[string]interface{}{"command": "sudo reboot"}, "sess-1"); got.Action != ActionManual {
+		t.Fatalf("expected the rule to win over allowlist and session mode, got %+v", got)
+	}
+	if got := mgr.Evaluate("exec", map[string]interface{}{"command": "uptime"}, "sess-1"); got.Action != ActionAuto || got.Source != "allowlist" {
+		t.Fatalf("expected the allowlist when no rule matches, got %+v", got)
+	}
+	if got := mgr.Evaluate("read_file", nil, "sess-1"); got.Source != "session_mode" || got.Action != ActionAuto {
+		t.Fatalf("expected the session mode override, got %+v", got)
+	}
+}
+
+func TestInvalidRulePatternNeverMatches(t *testing.T) {
+	mgr := NewManager(Config{
+		Mode:  ModeAuto,
+		Rules: []Rule{{Tool: "re:(", Action: ActionDeny}},
+	})
+	if got := mgr.Evaluate("exec", nil, ""); got.Action != ActionAuto {
+		t.Fatalf("expected an invalid rule to be skipped, got %+v", got)
+	}
+}
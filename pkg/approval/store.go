@@ -0,0 +1,149 @@
+package approval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"nekobot/pkg/storage/ent"
+	entapproval "nekobot/pkg/storage/ent/approvalrequest"
+)
+
+// DefaultHistoryLimit caps History when the query does not set a limit.
+const DefaultHistoryLimit = 200
+
+// Store persists approval requests and their decisions.
+type Store interface {
+	// Save inserts or updates a request.
+	Save(ctx context.Context, req *Request) error
+	// Pending returns every request still awaiting a decision.
+	Pending(ctx context.Context) ([]*Request, error)
+	// History returns decided and expired requests, newest decision first.
+	History(ctx context.Context, q HistoryQuery) ([]*Request, error)
+}
+
+// HistoryQuery filters History. Zero values match everything.
+type HistoryQuery struct {
+	SessionID string
+	ToolName  string
+	Decision  Decision
+	Limit     int
+}
+
+func (q HistoryQuery) matches(req *Request) bool {
+	return (q.SessionID == "" || req.SessionID == q.SessionID) &&
+		(q.ToolName == "" || req.ToolName == q.ToolName) &&
+		(q.Decision == "" || req.Decision == q.Decision)
+}
+
+func (q HistoryQuery) limit() int {
+	if q.Limit <= 0 {
+		return DefaultHistoryLimit
+	}
+	return q.Limit
+}
+
+// EntStore keeps approval requests in the runtime database.
+type EntStore struct {
+	client *ent.Client
+}
+
+// NewEntStore creates a store backed by the runtime database.
+func NewEntStore(client *ent.Client) (*EntStore, error) {
+	if client == nil {
+		return nil, fmt.Errorf("ent client is nil")
+	}
+	return &EntStore{client: client}, nil
+}
+
+// Save inserts or updates a request.
+func (s *EntStore) Save(ctx context.Context, req *Request) error {
+	exists, err := s.client.ApprovalRequest.Query().Where(entapproval.IDEQ(req.ID)).Exist(ctx)
+	if err != nil {
+		return fmt.Errorf("query approval: %w", err)
+	}
+	if exists {
+		update := s.client.ApprovalRequest.UpdateOneID(req.ID).
+			SetState(entapproval.State(req.Decision)).
+			SetReason(req.Reason).
+			SetDecidedBy(req.DecidedBy)
+		if req.DecidedAt != nil {
+			update.SetDecidedAt(*req.DecidedAt)
+		}
+		if err := update.Exec(ctx); err != nil {
+			return fmt.Errorf("update approval: %w", err)
+		}
+		return nil
+	}
+	create := s.client.ApprovalRequest.Create().
+		SetID(req.ID).
+		SetToolName(req.ToolName).
+		SetArguments(req.Arguments).
+		SetSessionID(req.SessionID).
+		SetState(entapproval.State(req.Decision)).
+		SetReason(req.Reason).
+		SetDecidedBy(req.DecidedBy).
+		SetNillableExpiresAt(req.ExpiresAt).
+		SetNillableDecidedAt(req.DecidedAt)
+	if !req.CreatedAt.IsZero() {
+		create.SetCreatedAt(req.CreatedAt)
+	}
+	if err := create.Exec(ctx); err != nil {
+		return fmt.Errorf("save approval: %w", err)
+	}
+	return nil
+}
+
+// Pending returns every request still awaiting a decision.
+func (s *EntStore) Pending(ctx context.Context) ([]*Request, error) {
+	rows, err := s.client.ApprovalRequest.Query().
+		Where(entapproval.StateEQ(entapproval.StatePending)).
+		Order(ent.Asc(entapproval.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list pending approvals: %w", err)
+	}
+	return fromEntRows(rows), nil
+}
+
+// History returns decided and expired requests, newest decision first.
+func (s *EntStore) History(ctx context.Context, q HistoryQuery) ([]*Request, error) {
+	query := s.client.ApprovalRequest.Query().
+		Where(entapproval.StateNEQ(entapproval.StatePending))
+	if sessionID := strings.TrimSpace(q.SessionID); sessionID != "" {
+		query = query.Where(entapproval.SessionIDEQ(sessionID))
+	}
+	if toolName := strings.TrimSpace(q.ToolName); toolName != "" {
+		query = query.Where(entapproval.ToolNameEQ(toolName))
+	}
+	if q.Decision != "" {
+		query = query.Where(entapproval.StateEQ(entapproval.State(q.Decision)))
+	}
+	rows, err := query.
+		Order(ent.Desc(entapproval.FieldDecidedAt), ent.Desc(entapproval.FieldCreatedAt)).
+		Limit(q.limit()).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list approval history: %w", err)
+	}
+	return fromEntRows(rows), nil
+}
+
+func fromEntRows(rows []*ent.ApprovalRequest) []*Request {
+	requests := make([]*Request, 0, len(rows))
+	for _, row := range rows {
+		requests = append(requests, &Request{
+			ID:        row.ID,
+			ToolName:  row.ToolName,
+			Arguments: row.Arguments,
+			SessionID: row.SessionID,
+			Decision:  Decision(row.State),
+			Reason:    row.Reason,
+			DecidedBy: row.DecidedBy,
+			CreatedAt: row.CreatedAt,
+			ExpiresAt: row.ExpiresAt,
+			DecidedAt: row.DecidedAt,
+		})
+	}
+	return requests
+}
@@ -0,0 +1,83 @@
+package approval
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"nekobot/pkg/config"
+)
+
+func newTestStore(t *testing.T) *EntStore {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		t.Fatalf("open runtime ent client: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	if err := config.EnsureRuntimeEntSchema(client); err != nil {
+		t.Fatalf("ensure runtime schema: %v", err)
+	}
+	store, err := NewEntStore(client)
+	if err != nil {
+		t.Fatalf("new approval store: %v", err)
+	}
+	return store
+}
+
+func TestPendingRequestsSurviveRestart(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	cfg := Config{Mode: ModeManual, TTL: time.Hour}
+
+	first, err := NewManagerWithStore(ctx, cfg, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, keepID, _ := first.CheckApproval(ctx, "exec", map[string]interface{}{"command": "ls"}, "sess-1")
+	_, denyID, _ := first.CheckApproval(ctx, "write_file", nil, "sess-2")
+	if err := first.DenyAs(denyID, "alice", "no"); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := NewManagerWithStore(ctx, cfg, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending := restarted.GetPending()
+	if len(pending) != 1 || pending[0].ID != keepID || pending[0].Arguments["command"] != "ls" || pending[0].ExpiresAt == nil {
+		t.Fatalf("expected the pending request to be reloaded, got %+v", pending)
+	}
+	if err := restarted.ApproveAs(keepID, "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := restarted.History(ctx, HistoryQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 history entries, got %d", len(history))
+	}
+	decidedBy := map[string]string{}
+	for _, req := range history {
+		decidedBy[req.ID] = string(req.Decision) + ":" + req.DecidedBy
+	}
+	if decidedBy[keepID] != "approved:bob" || decidedBy[denyID] != "denied:alice" {
+		t.Fatalf("unexpected audit trail: %v", decidedBy)
+	}
+
+	denied, err := restarted.History(ctx, HistoryQuery{Decision: Denied})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(denied) != 1 || denied[0].ID != denyID {
+		t.Fatalf("expected the decision filter to apply, got %+v", denied)
+	}
+}
@@ -0,0 +1,272 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"nekobot/pkg/secrets"
+	"nekobot/pkg/storage/ent"
+	"nekobot/pkg/storage/ent/auditlog"
+)
+
+// Event actions. Filters match an exact action or, without a dot, every
+// action in that group ("provider" matches "provider.create").
+const (
+	ActionLogin             = "auth.login"
+	ActionPasswordChange    = "auth.password_change"
+	ActionConfigUpdate      = "config.update"
+	ActionConfigImport      = "config.import"
+	ActionProviderCreate    = "provider.create"
+	ActionProviderUpdate    = "provider.update"
+	ActionProviderDelete    = "provider.delete"
+	ActionApprovalDecision  = "approval.decision"
+	ActionToolSessionAccess = "tool_session.access"
+	ActionPluginInstall     = "plugin.install"
+	ActionPluginUninstall   = "plugin.uninstall"
+	ActionSkillInstall      = "skill.install"
+)
+
+// MaxEventLimit is the most events one List call returns.
+const MaxEventLimit = 1000
+
+const (
+	defaultEventLimit = 100
+	// maxDiffChanges caps how many changed fields a diff summary lists.
+	maxDiffChanges = 20
+	// maxDiffValueLength truncates each value in a diff summary.
+	maxDiffValueLength = 80
+)
+
+// Event is one configuration or security event.
+type Event struct {
+	ID        string    `json:"id"`
+	Action    string    `json:"action"`
+	Actor     string    `json:"actor,omitempty"`
+	ActorID   string    `json:"actor_id,omitempty"`
+	TenantID  string    `json:"tenant_id,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	Target    string    `json:"target,omitempty"`
+	Success   bool      `json:"success"`
+	Summary   string    `json:"summary,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EventFilter narrows a List call. Zero fields do not filter.
+type EventFilter struct {
+	Action   string
+	Actor    string
+	TenantID string
+	Target   string
+	Since    time.Time
+	Until    time.Time
+	// Limit caps the result; zero means 100 and the maximum is 1000.
+	Limit int
+}
+
+// EventStore persists events in the runtime database.
+type EventStore struct {
+	client *ent.Client
+	now    func() time.Time
+}
+
+// NewEventStore creates an event store on the shared Ent client.
+func NewEventStore(client *ent.Client) (*EventStore, error) {
+	if client == nil {
+		return nil, fmt.Errorf("ent client is nil")
+	}
+	return &EventStore{client: client, now: time.Now}, nil
+}
+
+// Record stores an event. CreatedAt defaults to now.
+func (s *EventStore) Record(ctx context.Context, event Event) error {
+	event.Action = strings.TrimSpace(event.Action)
+	if event.Action == "" {
+		return fmt.Errorf("audit event action is required")
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = s.now()
+	}
+	err := s.client.AuditLog.Create().
+		SetAction(event.Action).
+		SetActor(strings.TrimSpace(event.Actor)).
+		SetActorID(strings.TrimSpace(event.ActorID)).
+		SetTenantID(strings.TrimSpace(event.TenantID)).
+		SetIP(strings.TrimSpace(event.IP)).
+		SetTarget(strings.TrimSpace(event.Target)).
+		SetSuccess(event.Success).
+		SetSummary(event.Summary).
+		SetCreatedAt(event.CreatedAt).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("record audit event: %w", err)
+	}
+	return nil
+}
+
+// List returns matching events, newest first.
+func (s *EventStore) List(ctx context.Context, filter EventFilter) ([]Event, error) {
+	q := s.client.AuditLog.Query()
+	if action := strings.TrimSpace(filter.Action); action != "" {
+		if strings.Contains(action, ".") {
+			q = q.Where(auditlog.ActionEQ(action))
+		} else {
+			q = q.Where(auditlog.ActionHasPrefix(action + "."))
+		}
+	}
+	if actor := strings.TrimSpace(filter.Actor); actor != "" {
+		q = q.Where(auditlog.ActorEQ(actor))
+	}
+	if tenantID := strings.TrimSpace(filter.TenantID); tenantID != "" {
+		q = q.Where(auditlog.TenantIDEQ(tenantID))
+	}
+	if target := strings.TrimSpace(filter.Target); target != "" {
+		q = q.Where(auditlog.TargetEQ(target))
+	}
+	if !filter.Since.IsZero() {
+		q = q.Where(auditlog.CreatedAtGTE(filter.Since))
+	}
+	if !filter.Until.IsZero() {
+		q = q.Where(auditlog.CreatedAtLT(filter.Until))
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultEventLimit
+	}
+	if limit > MaxEventLimit {
+		limit = MaxEventLimit
+	}
+	recs, err := q.Order(ent.Desc(auditlog.FieldCreatedAt)).Limit(limit).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list audit events: %w", err)
+	}
+	out := make([]Event, 0, len(recs))
+	for _, rec := range recs {
+		out = append(out, Event{
+			ID:        rec.ID,
+			Action:    rec.Action,
+			Actor:     rec.Actor,
+			ActorID:   rec.ActorID,
+			TenantID:  rec.TenantID,
+			IP:        rec.IP,
+			Target:    rec.Target,
+			Success:   rec.Success,
+			Summary:   rec.Summary,
+			CreatedAt: rec.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+// WriteJSONL writes events one JSON object per line.
+func WriteJSONL(w io.Writer, events []Event) error {
+	enc := json.NewEncoder(w)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return fmt.Errorf("write audit event: %w", err)
+		}
+	}
+	return nil
+}
+
+// Diff summarizes which fields differ between two JSON-encodable values as
+// "path: old → new" pairs. Values of secret-looking fields are not shown.
+func Diff(before, after any) string {
+	left := map[string]string{}
+	right := map[string]string{}
+	flattenJSON("", before, left)
+	flattenJSON("", after, right)
+
+	paths := make([]string, 0, len(left)+len(right))
+	for path, value := range left {
+		if other, ok := right[path]; !ok || other != value {
+			paths = append(paths, path)
+		}
+	}
+	for path := range right {
+		if _, ok := left[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	changes := make([]string, 0, min(len(paths), maxDiffChanges)+1)
+	for i, path := range paths {
+		if i == maxDiffChanges {
+			changes = append(changes, fmt.Sprintf("and %d more", len(paths)-maxDiffChanges))
+			break
+		}
+		if isSecretPath(path) {
+			changes = append(changes, path+": changed")
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s → %s", path, diffValue(left, path), diffValue(right, path)))
+	}
+	return strings.Join(changes, "; ")
+}
+
+// flattenJSON maps each leaf of value's JSON form to its dotted path.
+func flattenJSON(prefix string, value any, out map[string]string) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return
+	}
+	flattenDecoded(prefix, decoded, out)
+}
+
+func flattenDecoded(prefix string, value any, out map[string]string) {
+	if obj, ok := value.(map[string]any); ok && len(obj) > 0 {
+		for key, child := range obj {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flattenDecoded(path, child, out)
+		}
+		return
+	}
+	// Array elements get their own paths, so secrets inside a list of
+	// objects are hidden like any other field.
+	if items, ok := value.([]any); ok && len(items) > 0 && prefix != "" {
+		for i, child := range items {
+			flattenDecoded(fmt.Sprintf("%s[%d]", prefix, i), child, out)
+		}
+		return
+	}
+	if prefix == "" {
+		return
+	}
+	data, _ := json.Marshal(value)
+	out[prefix] = string(data)
+}
+
+func diffValue(values map[string]string, path string) string {
+	value, ok := values[path]
+	if !ok {
+		return "(unset)"
+	}
+	if runes := []rune(value); len(runes) > maxDiffValueLength {
+		return string(runes[:maxDiffValueLength]) + "…"
+	}
+	return value
+}
+
+// isSecretPath reports whether the field a path ends in is sensitive by the
+// rules of secrets.IsSensitiveField. Array indexes are skipped, so an
+// element of a secret list counts as secret too.
+func isSecretPath(path string) bool {
+	field := path[strings.LastIndex(path, ".")+1:]
+	if i := strings.Index(field, "["); i >= 0 {
+		field = field[:i]
+	}
+	lower := strings.ToLower(field)
+	return secrets.IsSensitiveField(lower) || strings.Contains(lower, "secret") || strings.Contains(lower, "password")
+}
@@ -0,0 +1,174 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/storage/ent"
+)
+
+func TestEventStoreRecordAndFilter(t *testing.T) {
+	ctx := context.Background()
+	store := newTestEventStore(t)
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	events := []Event{
+		{Action: ActionLogin, Actor: "alice", TenantID: "t1", IP: "10.0.0.1", Success: true, CreatedAt: base},
+		{Action: ActionProviderCreate, Actor: "alice", TenantID: "t1", Target: "openai", Success: true, CreatedAt: base.Add(time.Minute)},
+		{Action: ActionProviderDelete, Actor: "bob", TenantID: "t2", Target: "openai", Success: true, CreatedAt: base.Add(2 * time.Minute)},
+		{Action: ActionLogin, Actor: "mallory", Summary: "invalid credentials", CreatedAt: base.Add(3 * time.Minute)},
+	}
+	for _, event := range events {
+		if err := store.Record(ctx, event); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	if err := store.Record(ctx, Event{}); err == nil {
+		t.Fatal("expected an event without action to be rejected")
+	}
+
+	all, err := store.List(ctx, EventFilter{})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(all) != 4 || all[0].Actor != "mallory" || all[0].Success {
+		t.Fatalf("expected newest failed login first, got %+v", all)
+	}
+
+	cases := []struct {
+		name   string
+		filter EventFilter
+		want   []string
+	}{
+		{"action group", EventFilter{Action: "provider"}, []string{ActionProviderDelete, ActionProviderCreate}},
+		{"exact action", EventFilter{Action: ActionLogin, Actor: "alice"}, []string{ActionLogin}},
+		{"tenant", EventFilter{TenantID: "t1"}, []string{ActionProviderCreate, ActionLogin}},
+		{"time window", EventFilter{Since: base.Add(time.Minute), Until: base.Add(3 * time.Minute)}, []string{ActionProviderDelete, ActionProviderCreate}},
+		{"limit", EventFilter{Target: "openai", Limit: 1}, []string{ActionProviderDelete}},
+	}
+	for _, tc := range cases {
+		got, err := store.List(ctx, tc.filter)
+		if err != nil {
+			t.Fatalf("%s: List failed: %v", tc.name, err)
+		}
+		actions := make([]string, 0, len(got))
+		for _, event := range got {
+			actions = append(actions, event.Action)
+		}
+		if strings.Join(actions, ",") != strings.Join(tc.want, ",") {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, actions)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSONL(&buf, all); err != nil {
+		t.Fatalf("WriteJSONL failed: %v", err)
+	}
+	lines := 0
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("decode line %d: %v", lines, err)
+		}
+		lines++
+	}
+	if lines != len(all) {
+		t.Fatalf("expected %d lines, got %d", len(all), lines)
+	}
+}
+
+func TestDiffListsChangedFieldsAndHidesSecrets(t *testing.T) {
+	before := map[string]any{
+		"name":     "openai",
+		"api_key":  "sk-old",
+		"timeout":  30,
+		"settings": map[string]any{"max_tokens": 1000, "bot_token": "a"},
+	}
+	after := map[string]any{
+		"name":     "openai",
+		"api_key":  "sk-new",
+		"timeout":  60,
+		"proxy":    "http://proxy",
+		"settings": map[string]any{"max_tokens": 2000, "bot_token": "b"},
+	}
+
+	got := Diff(before, after)
+	want := `api_key: changed; proxy: changed; settings.bot_token: changed; settings.max_tokens: 1000 → 2000; timeout: 30 → 60`
+	if got != want {
+		t.Fatalf("unexpected diff:\n got: %s\nwant: %s", got, want)
+	}
+	if strings.Contains(got, "sk-") {
+		t.Fatalf("diff leaked a secret: %s", got)
+	}
+	if Diff(before, before) != "" {
+		t.Fatal("expected no changes for equal values")
+	}
+}
+
+func TestDiffHidesSecretsInsideArrays(t *testing.T) {
+	before := map[string]any{
+		"providers": []any{
+			map[string]any{"name": "openai", "api_key": "sk-old", "api_base": "https://a.example/v1?key=1"},
+		},
+		"storage":  map[string]any{"db_dsn": "postgres://u:old@db/neko"},
+		"fallback": []string{"openai"},
+	}
+	after := map[string]any{
+		"providers": []any{
+			map[string]any{"name": "openai", "api_key": "sk-new", "api_base": "https://a.example/v1?key=1"},
+			map[string]any{"name": "backup", "api_key": "sk-backup", "webhook_url": "https://hooks.example/t0ken"},
+		},
+		"storage":  map[string]any{"db_dsn": "postgres://u:new@db/neko"},
+		"fallback": []string{"openai", "backup"},
+	}
+
+	got := Diff(before, after)
+	want := `fallback[1]: (unset) → "backup"; providers[0].api_key: changed; providers[1].api_key: changed; providers[1].name: (unset) → "backup"; providers[1].webhook_url: changed; storage.db_dsn: changed`
+	if got != want {
+		t.Fatalf("unexpected diff:\n got: %s\nwant: %s", got, want)
+	}
+	for _, leaked := range []string{"sk-", "t0ken", "u:new"} {
+		if strings.Contains(got, leaked) {
+			t.Fatalf("diff leaked %q: %s", leaked, got)
+		}
+	}
+}
+
+func newTestEventStore(t *testing.T) *EventStore {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Fatalf("close ent client: %v", err)
+		}
+	})
+
+	store, err := NewEventStore(client)
+	if err != nil {
+		t.Fatalf("NewEventStore failed: %v", err)
+	}
+	return store
+}
+
+func newTestEntClient(t *testing.T, cfg *config.Config) *ent.Client {
+	t.Helper()
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		t.Fatalf("open runtime ent client: %v", err)
+	}
+	if err := config.EnsureRuntimeEntSchema(client); err != nil {
+		_ = client.Close()
+		t.Fatalf("ensure runtime schema: %v", err)
+	}
+	return client
+}
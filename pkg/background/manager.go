@@ -0,0 +1,462 @@
+// Package background runs long jobs for the agent and reports their progress
+// and completion to the conversation that started them.
+package background
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/execenv"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/process"
+	"nekobot/pkg/subagent"
+	"nekobot/pkg/tasks"
+)
+
+// Job kinds.
+const (
+	KindCommand = "command"
+	KindAgent   = "agent"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	defaultMaxRunning   = 5
+	// maxFinishedJobs bounds how many finished command jobs are remembered.
+	maxFinishedJobs = 50
+	// outputTailChars bounds the output excerpt sent with notifications.
+	outputTailChars = 1500
+)
+
+// ErrJobNotFound indicates the job does not exist or belongs to another chat.
+var ErrJobNotFound = errors.New("background job not found")
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07]*\x07`)
+
+// Job is the listing view of a background command or agent job.
+type Job struct {
+	ID          string      `json:"id"`
+	Kind        string      `json:"kind"`
+	Label       string      `json:"label"`
+	Command     string      `json:"command"` // Shell command, or the task of an agent job
+	Workdir     string      `json:"workdir,omitempty"`
+	Channel     string      `json:"channel,omitempty"`
+	ChatID      string      `json:"chat_id,omitempty"`
+	State       tasks.State `json:"state"`
+	ExitCode    int         `json:"exit_code"`
+	Output      string      `json:"output,omitempty"` // Output tail, or the result of an agent job
+	Error       string      `json:"error,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	CompletedAt time.Time   `json:"completed_at,omitempty"`
+}
+
+// CommandSpec describes a command job.
+type CommandSpec struct {
+	Command string
+	Workdir string
+	Label   string
+	Channel string
+	ChatID  string
+}
+
+// Config bounds command jobs.
+type Config struct {
+	MaxRunning       int
+	ProgressInterval time.Duration // 0 disables progress notifications
+}
+
+// Processes is the part of process.Manager that runs command jobs.
+type Processes interface {
+	StartWithSpec(ctx context.Context, spec execenv.StartSpec) error
+	GetStatus(sessionID string) (*process.SessionStatus, error)
+	GetOutput(sessionID string, offset, limit int) ([]string, int, error)
+	Kill(sessionID string) error
+}
+
+// Agents is the part of subagent.SubagentManager that runs agent jobs.
+type Agents interface {
+	Spawn(ctx context.Context, task, label, channel, chatID string) (string, error)
+	ListTaskSnapshots() []tasks.Task
+	CancelTask(taskID string) error
+}
+
+// Manager starts background jobs and tracks command jobs until they exit.
+// Command jobs run as process sessions named after the job ID, so their
+// output stays readable through the process tool.
+type Manager struct {
+	log          *logger.Logger
+	procs        Processes
+	notify       subagent.OutboundSender
+	cfg          Config
+	pollInterval time.Duration
+
+	mu     sync.RWMutex
+	agents Agents
+	jobs   map[string]*commandJob
+}
+
+type commandJob struct {
+	Job
+	cancelRequested bool
+}
+
+// NewManager creates a background job manager.
+func NewManager(log *logger.Logger, procs Processes, notify subagent.OutboundSender, cfg Config) *Manager {
+	if cfg.MaxRunning <= 0 {
+		cfg.MaxRunning = defaultMaxRunning
+	}
+	return &Manager{
+		log:          log,
+		procs:        procs,
+		notify:       notify,
+		cfg:          cfg,
+		pollInterval: defaultPollInterval,
+		jobs:         make(map[string]*commandJob),
+	}
+}
+
+// SetAgents enables agent jobs, which are delegated to the subagent manager.
+func (m *Manager) SetAgents(agents Agents) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.agents = agents
+}
+
+// StartCommand starts a command job and returns immediately.
+func (m *Manager) StartCommand(spec CommandSpec) (Job, error) {
+	command := strings.TrimSpace(spec.Command)
+	if command == "" {
+		return Job{}, fmt.Errorf("command is required")
+	}
+	if m.procs == nil {
+		return Job{}, fmt.Errorf("process manager not available")
+	}
+
+	m.mu.Lock()
+	running := 0
+	for _, job := range m.jobs {
+		if !tasks.IsFinal(job.State) {
+			running++
+		}
+	}
+	if running >= m.cfg.MaxRunning {
+		m.mu.Unlock()
+		return Job{}, fmt.Errorf("%d background jobs are already running, wait for one to finish or cancel it", running)
+	}
+	id := "bg-" + strings.ReplaceAll(uuid.NewString(), "-", "")[:8]
+	label := strings.TrimSpace(spec.Label)
+	if label == "" {
+		label = id
+	}
+	job := &commandJob{Job: Job{
+		ID:        id,
+		Kind:      KindCommand,
+		Label:     label,
+		Command:   command,
+		Workdir:   spec.Workdir,
+		Channel:   strings.TrimSpace(spec.Channel),
+		ChatID:    strings.TrimSpace(spec.ChatID),
+		State:     tasks.StateRunning,
+		CreatedAt: time.Now(),
+	}}
+	m.jobs[id] = job
+	snapshot := job.Job
+	m.mu.Unlock()
+
+	// The job outlives the tool call that started it, so it must not inherit
+	// the call's context.
+	err := m.procs.StartWithSpec(context.Background(), execenv.StartSpec{
+		SessionID: id,
+		Command:   command,
+		Workdir:   spec.Workdir,
+		TaskID:    id,
+		RuntimeID: "background",
+		Env:       os.Environ(),
+	})
+	if err != nil {
+		m.mu.Lock()
+		delete(m.jobs, id)
+		m.mu.Unlock()
+		return Job{}, fmt.Errorf("starting background job: %w", err)
+	}
+
+	m.log.Info("Background job started", zap.String("job_id", id), zap.String("command", command))
+	go m.watch(id)
+	return snapshot, nil
+}
+
+// StartAgent delegates an agent job to the subagent manager, which notifies
+// the chat when it finishes.
+func (m *Manager) StartAgent(ctx context.Context, task, label, channel, chatID string) (Job, error) {
+	m.mu.RLock()
+	agents := m.agents
+	m.mu.RUnlock()
+	if agents == nil {
+		return Job{}, fmt.Errorf("agent jobs are not available")
+	}
+	if strings.TrimSpace(task) == "" {
+		return Job{}, fmt.Errorf("task is required")
+	}
+	id, err := agents.Spawn(ctx, task, strings.TrimSpace(label), channel, chatID)
+	if err != nil {
+		return Job{}, fmt.Errorf("starting agent job: %w", err)
+	}
+	for _, snapshot := range agents.ListTaskSnapshots() {
+		if snapshot.ID == id {
+			return agentJob(snapshot), nil
+		}
+	}
+	return Job{ID: id, Kind: KindAgent, Label: label, Command: task, Channel: channel, ChatID: chatID, State: tasks.StatePending, CreatedAt: time.Now()}, nil
+}
+
+// List returns the jobs started from channel/chatID, newest first. An empty
+// channel lists every job.
+func (m *Manager) List(channel, chatID string) []Job {
+	m.mu.RLock()
+	result := make([]Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		if inScope(job.Job, channel, chatID) {
+			result = append(result, job.Job)
+		}
+	}
+	agents := m.agents
+	m.mu.RUnlock()
+
+	if agents != nil {
+		for _, snapshot := range agents.ListTaskSnapshots() {
+			if job := agentJob(snapshot); inScope(job, channel, chatID) {
+				result = append(result, job)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result
+}
+
+// Cancel stops a running job started from channel/chatID. Agent jobs may be
+// referred to by a unique ID prefix, as /tasks shows them shortened. An empty
+// channel allows cancelling any job.
+func (m *Manager) Cancel(channel, chatID, jobID string) error {
+	jobID = strings.TrimSpace(jobID)
+	if jobID == "" {
+		return fmt.Errorf("job id is required")
+	}
+
+	m.mu.Lock()
+	if job, ok := m.jobs[jobID]; ok && inScope(job.Job, channel, chatID) {
+		if tasks.IsFinal(job.State) {
+			m.mu.Unlock()
+			return fmt.Errorf("job %s already %s", jobID, job.State)
+		}
+		job.cancelRequested = true
+		m.mu.Unlock()
+		if err := m.procs.Kill(jobID); err != nil {
+			return fmt.Errorf("cancelling job %s: %w", jobID, err)
+		}
+		return nil
+	}
+	agents := m.agents
+	m.mu.Unlock()
+
+	if agents == nil {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, jobID)
+	}
+	var matches []Job
+	for _, snapshot := range agents.ListTaskSnapshots() {
+		job := agentJob(snapshot)
+		if strings.HasPrefix(job.ID, jobID) && inScope(job, channel, chatID) {
+			matches = append(matches, job)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("%w: %s", ErrJobNotFound, jobID)
+	case 1:
+		return agents.CancelTask(matches[0].ID)
+	default:
+		return fmt.Errorf("job id %s is ambiguous", jobID)
+	}
+}
+
+// watch polls a command job until its process exits, sending progress
+// updates while its output keeps changing.
+func (m *Manager) watch(id string) {
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+
+	lastProgress := time.Now()
+	reportedOutput := 0
+	for range ticker.C {
+		status, err := m.procs.GetStatus(id)
+		if err != nil {
+			m.finish(id, -1, "process session lost")
+			return
+		}
+		if !status.Running {
+			m.finish(id, status.ExitCode, "")
+			return
+		}
+		if m.cfg.ProgressInterval <= 0 || time.Since(lastProgress) < m.cfg.ProgressInterval || status.OutputSize == reportedOutput {
+			continue
+		}
+		lastProgress = time.Now()
+		reportedOutput = status.OutputSize
+
+		m.mu.Lock()
+		job, ok := m.jobs[id]
+		if !ok {
+			m.mu.Unlock()
+			return
+		}
+		job.Output = m.outputTail(id)
+		snapshot := job.Job
+		m.mu.Unlock()
+		m.send(snapshot, fmt.Sprintf("Background job [%s] is still running (%s). Latest output:\n%s",
+			snapshot.Label, time.Since(snapshot.CreatedAt).Round(time.Second), snapshot.Output), true)
+	}
+}
+
+func (m *Manager) finish(id string, exitCode int, lostReason string) {
+	output := m.outputTail(id)
+
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	job.ExitCode = exitCode
+	job.Output = output
+	job.CompletedAt = time.Now()
+	switch {
+	case job.cancelRequested:
+		job.State = tasks.StateCanceled
+	case lostReason != "":
+		job.State = tasks.StateFailed
+		job.Error = lostReason
+	case exitCode == 0:
+		job.State = tasks.StateCompleted
+	default:
+		job.State = tasks.StateFailed
+		job.Error = fmt.Sprintf("exit code %d", exitCode)
+	}
+	snapshot := job.Job
+	m.pruneLocked()
+	m.mu.Unlock()
+
+	m.log.Info("Background job finished",
+		zap.String("job_id", id),
+		zap.String("state", string(snapshot.State)),
+		zap.Int("exit_code", exitCode))
+	m.send(snapshot, formatFinished(snapshot), false)
+}
+
+// pruneLocked forgets the oldest finished command jobs beyond maxFinishedJobs.
+func (m *Manager) pruneLocked() {
+	var finished []*commandJob
+	for _, job := range m.jobs {
+		if tasks.IsFinal(job.State) {
+			finished = append(finished, job)
+		}
+	}
+	if len(finished) <= maxFinishedJobs {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].CompletedAt.Before(finished[j].CompletedAt)
+	})
+	for _, job := range finished[:len(finished)-maxFinishedJobs] {
+		delete(m.jobs, job.ID)
+	}
+}
+
+func (m *Manager) outputTail(id string) string {
+	chunks, _, err := m.procs.GetOutput(id, 0, 0)
+	if err != nil {
+		return ""
+	}
+	output := strings.TrimSpace(strings.ReplaceAll(ansiPattern.ReplaceAllString(strings.Join(chunks, ""), ""), "\r", ""))
+	if len(output) > outputTailChars {
+		output = "..." + output[len(output)-outputTailChars:]
+	}
+	return output
+}
+
+func (m *Manager) send(job Job, content string, progress bool) {
+	if m.notify == nil || job.Channel == "" || job.ChatID == "" {
+		return
+	}
+	id := "background:" + job.ID
+	if progress {
+		id += ":" + time.Now().Format("150405")
+	}
+	err := m.notify.SendNotification(&subagent.Notification{
+		ID:      id,
+		Channel: job.Channel,
+		ChatID:  job.ChatID,
+		Content: content,
+		Data: map[string]interface{}{
+			"task_id":   job.ID,
+			"status":    string(job.State),
+			"label":     job.Label,
+			"task_type": string(tasks.TypeRuntimeWorker),
+			"progress":  progress,
+		},
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		m.log.Warn("Background job notification failed", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
+
+func formatFinished(job Job) string {
+	duration := job.CompletedAt.Sub(job.CreatedAt).Round(time.Second)
+	var header string
+	switch job.State {
+	case tasks.StateCompleted:
+		header = fmt.Sprintf("Background job [%s] completed in %s.", job.Label, duration)
+	case tasks.StateCanceled:
+		return fmt.Sprintf("Background job [%s] was cancelled after %s.", job.Label, duration)
+	default:
+		header = fmt.Sprintf("Background job [%s] failed after %s: %s.", job.Label, duration, job.Error)
+	}
+	if job.Output == "" {
+		return header
+	}
+	return header + "\n" + job.Output
+}
+
+func agentJob(snapshot tasks.Task) Job {
+	job := Job{
+		ID:          snapshot.ID,
+		Kind:        KindAgent,
+		Command:     snapshot.Summary,
+		ChatID:      snapshot.SessionID,
+		State:       snapshot.State,
+		Error:       snapshot.LastError,
+		CreatedAt:   snapshot.CreatedAt,
+		CompletedAt: snapshot.CompletedAt,
+	}
+	job.Label, _ = snapshot.Metadata["label"].(string)
+	job.Channel, _ = snapshot.Metadata["channel"].(string)
+	return job
+}
+
+func inScope(job Job, channel, chatID string) bool {
+	if channel == "" {
+		return true
+	}
+	return job.Channel == channel && job.ChatID == chatID
+}
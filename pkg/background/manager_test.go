@@ -0,0 +1,181 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nekobot/pkg/logger"
+	"nekobot/pkg/process"
+	"nekobot/pkg/subagent"
+	"nekobot/pkg/tasks"
+)
+
+type recordingSender struct {
+	mu    sync.Mutex
+	sent  []*subagent.Notification
+	final chan *subagent.Notification
+}
+
+func newRecordingSender() *recordingSender {
+	return &recordingSender{final: make(chan *subagent.Notification, 4)}
+}
+
+func (s *recordingSender) SendNotification(msg *subagent.Notification) error {
+	s.mu.Lock()
+	s.sent = append(s.sent, msg)
+	s.mu.Unlock()
+	if progress, _ := msg.Data["progress"].(bool); !progress {
+		s.final <- msg
+	}
+	return nil
+}
+
+func (s *recordingSender) progressCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, msg := range s.sent {
+		if progress, _ := msg.Data["progress"].(bool); progress {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *recordingSender) waitFinal(t *testing.T) *subagent.Notification {
+	t.Helper()
+	select {
+	case msg := <-s.final:
+		return msg
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the job to finish")
+		return nil
+	}
+}
+
+func newTestManager(t *testing.T, cfg Config) (*Manager, *recordingSender) {
+	t.Helper()
+	log, err := logger.New(&logger.Config{Level: "error"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	sender := newRecordingSender()
+	mgr := NewManager(log, process.NewManager(log), sender, cfg)
+	mgr.pollInterval = 20 * time.Millisecond
+	return mgr, sender
+}
+
+func TestCommandJobReportsProgressAndCompletion(t *testing.T) {
+	mgr, sender := newTestManager(t, Config{ProgressInterval: 50 * time.Millisecond})
+
+	job, err := mgr.StartCommand(CommandSpec{
+		Command: "echo building; sleep 0.4; echo \"$((20 + 22)) done\"",
+		Workdir: t.TempDir(),
+		Label:   "build",
+		Channel: "telegram",
+		ChatID:  "42",
+	})
+	if err != nil {
+		t.Fatalf("StartCommand failed: %v", err)
+	}
+	if !strings.HasPrefix(job.ID, "bg-") || job.State != tasks.StateRunning {
+		t.Fatalf("unexpected job %+v", job)
+	}
+
+	msg := sender.waitFinal(t)
+	if msg.Channel != "telegram" || msg.ChatID != "42" || !strings.Contains(msg.Content, "Background job [build] completed") || !strings.Contains(msg.Content, "42 done") {
+		t.Fatalf("unexpected completion notification %+v", msg)
+	}
+	if sender.progressCount() == 0 {
+		t.Fatal("expected a progress notification while the job was running")
+	}
+
+	jobs := mgr.List("telegram", "42")
+	if len(jobs) != 1 || jobs[0].State != tasks.StateCompleted || jobs[0].ExitCode != 0 {
+		t.Fatalf("unexpected job list %+v", jobs)
+	}
+	if len(mgr.List("telegram", "other")) != 0 {
+		t.Fatal("expected jobs to be scoped to their chat")
+	}
+}
+
+func TestCommandJobCancelAndLimits(t *testing.T) {
+	mgr, sender := newTestManager(t, Config{MaxRunning: 1})
+
+	job, err := mgr.StartCommand(CommandSpec{Command: "sleep 30", Channel: "slack", ChatID: "C1"})
+	if err != nil {
+		t.Fatalf("StartCommand failed: %v", err)
+	}
+	if _, err := mgr.StartCommand(CommandSpec{Command: "sleep 30", Channel: "slack", ChatID: "C1"}); err == nil {
+		t.Fatal("expected max_running to reject a second job")
+	}
+	if err := mgr.Cancel("slack", "C2", job.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected another chat to be unable to cancel the job, got %v", err)
+	}
+	if err := mgr.Cancel("slack", "C1", job.ID); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+
+	msg := sender.waitFinal(t)
+	if !strings.Contains(msg.Content, "was cancelled") || msg.Data["status"] != string(tasks.StateCanceled) {
+		t.Fatalf("unexpected cancel notification %+v", msg)
+	}
+	if err := mgr.Cancel("", "", job.ID); err == nil {
+		t.Fatal("expected cancelling a finished job to fail")
+	}
+}
+
+type fakeAgents struct {
+	snapshots []tasks.Task
+	cancelled string
+}
+
+func (f *fakeAgents) Spawn(_ context.Context, task, label, channel, chatID string) (string, error) {
+	id := "7f3c2a10-0000-4000-8000-000000000001"
+	f.snapshots = append(f.snapshots, tasks.Task{
+		ID:        id,
+		Type:      tasks.TypeBackgroundAgent,
+		State:     tasks.StatePending,
+		Summary:   task,
+		SessionID: chatID,
+		CreatedAt: time.Now(),
+		Metadata:  map[string]any{"label": label, "channel": channel},
+	})
+	return id, nil
+}
+
+func (f *fakeAgents) ListTaskSnapshots() []tasks.Task {
+	return f.snapshots
+}
+
+func (f *fakeAgents) CancelTask(taskID string) error {
+	f.cancelled = taskID
+	return nil
+}
+
+func TestAgentJobsAreListedAndCancelledByPrefix(t *testing.T) {
+	mgr, _ := newTestManager(t, Config{})
+	if _, err := mgr.StartAgent(context.Background(), "summarize the logs", "", "discord", "D1"); err == nil {
+		t.Fatal("expected agent jobs to be unavailable without a subagent manager")
+	}
+
+	agents := &fakeAgents{}
+	mgr.SetAgents(agents)
+	job, err := mgr.StartAgent(context.Background(), "summarize the logs", "logs", "discord", "D1")
+	if err != nil {
+		t.Fatalf("StartAgent failed: %v", err)
+	}
+	if job.Kind != KindAgent || job.Label != "logs" || job.Channel != "discord" {
+		t.Fatalf("unexpected agent job %+v", job)
+	}
+	if jobs := mgr.List("discord", "D1"); len(jobs) != 1 || jobs[0].Command != "summarize the logs" {
+		t.Fatalf("unexpected job list %+v", jobs)
+	}
+	if err := mgr.Cancel("discord", "D1", job.ID[:8]); err != nil || agents.cancelled != job.ID {
+		t.Fatalf("expected prefix cancel to reach the subagent manager, got err=%v cancelled=%q", err, agents.cancelled)
+	}
+}
@@ -0,0 +1,359 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/fileutil"
+	"nekobot/pkg/version"
+)
+
+// Archive layout. Workspace files keep their path relative to the workspace.
+const (
+	manifestEntry  = "manifest.json"
+	databaseEntry  = "database/" + config.RuntimeDBName
+	configEntry    = "config.yaml"
+	workspaceEntry = "workspace/"
+
+	memoryFileName = "MEMORY.md"
+	memoryDirName  = "memory"
+)
+
+// Manifest describes the contents of an archive.
+type Manifest struct {
+	Version      string    `json:"version"`
+	CreatedAt    time.Time `json:"created_at"`
+	DatabaseType string    `json:"database_type"`
+	// Database is false for PostgreSQL/MySQL runtimes, which are backed up
+	// with their own tools; the config export still covers their settings.
+	Database    bool     `json:"database"`
+	Config      bool     `json:"config"`
+	MemoryFiles []string `json:"memory_files"`
+}
+
+// writeArchive writes a tar.gz backup to w.
+func (m *Manager) writeArchive(ctx context.Context, w io.Writer, createdAt time.Time) (Manifest, error) {
+	manifest := Manifest{
+		Version:      version.GetVersion(),
+		CreatedAt:    createdAt,
+		DatabaseType: m.cfg.DatabaseType(),
+		MemoryFiles:  []string{},
+	}
+
+	// Snapshot the database first so a failure leaves no partial archive.
+	dbPath, ok, err := config.RuntimeSQLiteFilePath(m.cfg)
+	if err != nil {
+		return manifest, err
+	}
+	var snapshot string
+	if ok {
+		snapshot, err = snapshotSQLite(ctx, dbPath)
+		if err != nil {
+			return manifest, err
+		}
+		defer os.RemoveAll(filepath.Dir(snapshot))
+		manifest.Database = true
+	}
+
+	var configData []byte
+	if m.exporter != nil {
+		doc, err := m.exporter.ExportDocument(ctx)
+		if err != nil {
+			return manifest, fmt.Errorf("export config: %w", err)
+		}
+		if configData, err = config.MarshalDocument(doc, config.DocumentFormatYAML); err != nil {
+			return manifest, fmt.Errorf("export config: %w", err)
+		}
+		manifest.Config = true
+	}
+
+	workspace := m.cfg.WorkspacePath()
+	memoryFiles, err := collectMemoryFiles(workspace)
+	if err != nil {
+		return manifest, err
+	}
+	manifest.MemoryFiles = memoryFiles
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifest, err
+	}
+	if err := writeTarBytes(tw, manifestEntry, manifestData, createdAt); err != nil {
+		return manifest, err
+	}
+	if snapshot != "" {
+		if err := writeTarFile(tw, databaseEntry, snapshot); err != nil {
+			return manifest, err
+		}
+	}
+	if configData != nil {
+		if err := writeTarBytes(tw, configEntry, configData, createdAt); err != nil {
+			return manifest, err
+		}
+	}
+	for _, rel := range memoryFiles {
+		if err := ctx.Err(); err != nil {
+			return manifest, err
+		}
+		if err := writeTarFile(tw, workspaceEntry+rel, filepath.Join(workspace, filepath.FromSlash(rel))); err != nil {
+			return manifest, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return manifest, fmt.Errorf("finish backup archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return manifest, fmt.Errorf("finish backup archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// snapshotSQLite copies a live SQLite database with VACUUM INTO, which
+// gives a consistent copy while the gateway keeps writing.
+func snapshotSQLite(ctx context.Context, dbPath string) (string, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return "", fmt.Errorf("runtime database: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "nekobot-db-snapshot-")
+	if err != nil {
+		return "", fmt.Errorf("create snapshot directory: %w", err)
+	}
+	snapshot := filepath.Join(dir, config.RuntimeDBName)
+
+	db, err := sql.Open("sqlite3", "file:"+dbPath+"?_pragma=busy_timeout(10000)")
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return "", fmt.Errorf("open runtime database: %w", err)
+	}
+	defer db.Close()
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", snapshot); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", fmt.Errorf("snapshot runtime database: %w", err)
+	}
+	return snapshot, nil
+}
+
+// collectMemoryFiles lists MEMORY.md and everything under memory/, as
+// slash-separated paths relative to the workspace.
+func collectMemoryFiles(workspace string) ([]string, error) {
+	files := []string{}
+	if info, err := os.Stat(filepath.Join(workspace, memoryFileName)); err == nil && info.Mode().IsRegular() {
+		files = append(files, memoryFileName)
+	}
+	root := filepath.Join(workspace, memoryDirName)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(workspace, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("collect memory files: %w", err)
+	}
+	return files, nil
+}
+
+func writeTarBytes(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: modTime}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+func writeTarFile(tw *tar.Writer, name, source string) error {
+	f, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", source, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", source, err)
+	}
+	header := &tar.Header{Name: name, Mode: 0o600, Size: info.Size(), ModTime: info.ModTime()}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := io.CopyN(tw, f, info.Size()); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// RestoreOptions selects what Restore puts back.
+type RestoreOptions struct {
+	Database bool
+	Memory   bool
+	Config   bool
+}
+
+// RestoreResult reports what Restore put back. Config is the parsed config
+// export; applying it is left to the caller, since the restored database
+// already holds the same sections.
+type RestoreResult struct {
+	Manifest    Manifest         `json:"manifest"`
+	Database    string           `json:"database,omitempty"` // Path of the restored database
+	MemoryFiles int              `json:"memory_files"`
+	Config      *config.Document `json:"-"`
+}
+
+// Restore unpacks an archive, named as in List or given as a local file
+// path. The database must not be in use: stop the gateway first. The
+// replaced database is kept next to it with a .pre-restore suffix.
+func (m *Manager) Restore(ctx context.Context, source string, opts RestoreOptions) (RestoreResult, error) {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+
+	reader, err := m.openArchive(ctx, source)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+	defer reader.Close()
+
+	gz, err := gzip.NewReader(reader)
+	if err != nil {
+		return RestoreResult{}, fmt.Errorf("open backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	var result RestoreResult
+	workspace := m.cfg.WorkspacePath()
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("read backup archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		switch name := path.Clean(header.Name); {
+		case name == manifestEntry:
+			if err := json.NewDecoder(tr).Decode(&result.Manifest); err != nil {
+				return result, fmt.Errorf("parse %s: %w", manifestEntry, err)
+			}
+		case name == databaseEntry && opts.Database:
+			if result.Database, err = m.restoreDatabase(tr); err != nil {
+				return result, err
+			}
+		case name == configEntry && opts.Config:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return result, fmt.Errorf("read %s: %w", configEntry, err)
+			}
+			if result.Config, err = config.ParseDocument(data, config.DocumentFormatYAML); err != nil {
+				return result, err
+			}
+		case strings.HasPrefix(name, workspaceEntry) && opts.Memory:
+			rel := strings.TrimPrefix(name, workspaceEntry)
+			if !isMemoryPath(rel) {
+				return result, fmt.Errorf("backup archive has an unexpected workspace file %q", header.Name)
+			}
+			if err := writeRestoredFile(filepath.Join(workspace, filepath.FromSlash(rel)), tr, 0o644); err != nil {
+				return result, err
+			}
+			result.MemoryFiles++
+		}
+	}
+	return result, nil
+}
+
+func (m *Manager) openArchive(ctx context.Context, source string) (io.ReadCloser, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, fmt.Errorf("backup archive is required")
+	}
+	if info, err := os.Stat(source); err == nil && info.Mode().IsRegular() {
+		return os.Open(source)
+	}
+	if _, ok := parseArchiveName(source); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, source)
+	}
+	target, err := m.target(m.settings())
+	if err != nil {
+		return nil, err
+	}
+	return target.Open(ctx, source)
+}
+
+// restoreDatabase replaces the SQLite runtime database with the archived
+// copy and returns its path.
+func (m *Manager) restoreDatabase(r io.Reader) (string, error) {
+	dbPath, ok, err := config.RuntimeSQLiteFilePath(m.cfg)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("the runtime database is %s, not a SQLite file; restore it with --only memory,config", m.cfg.DatabaseType())
+	}
+	if _, err := os.Stat(dbPath); err == nil {
+		if err := copyFile(dbPath, dbPath+".pre-restore"); err != nil {
+			return "", fmt.Errorf("keep current database: %w", err)
+		}
+	}
+	if err := writeRestoredFile(dbPath, r, 0o644); err != nil {
+		return "", err
+	}
+	return dbPath, nil
+}
+
+func isMemoryPath(rel string) bool {
+	if rel == "" || strings.HasPrefix(rel, "/") || strings.Contains(rel, "..") {
+		return false
+	}
+	return rel == memoryFileName || strings.HasPrefix(rel, memoryDirName+"/")
+}
+
+func writeRestoredFile(dst string, r io.Reader, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(dst), err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read %s from backup: %w", filepath.Base(dst), err)
+	}
+	if err := fileutil.WriteFileAtomic(dst, data, perm); err != nil {
+		return fmt.Errorf("restore %s: %w", dst, err)
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFileAtomic(dst, data, 0o600)
+}
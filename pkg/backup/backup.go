@@ -0,0 +1,368 @@
+// Package backup snapshots the runtime database, the workspace memory files
+// and a config export into tar.gz archives on a schedule, prunes them with a
+// retention policy and restores them.
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+const (
+	archivePrefix     = "nekobot-backup-"
+	archiveSuffix     = ".tar.gz"
+	archiveTimeLayout = "20060102T150405Z"
+
+	tickerInterval = time.Minute
+)
+
+// ErrNotFound is returned for an archive the target does not hold.
+var ErrNotFound = errors.New("backup archive not found")
+
+// Archive is one backup stored in the target.
+type Archive struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Status reports the backup schedule and the latest run.
+type Status struct {
+	Enabled       bool       `json:"enabled"`
+	Target        string     `json:"target"`
+	Location      string     `json:"location"`
+	IntervalHours int        `json:"interval_hours"`
+	Keep          int        `json:"keep"`
+	MaxAgeDays    int        `json:"max_age_days"`
+	Running       bool       `json:"running"`
+	LastBackup    *Archive   `json:"last_backup,omitempty"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextRunAt     *time.Time `json:"next_run_at,omitempty"`
+}
+
+// ConfigExporter exports the runtime config with its providers; the
+// provider store implements it.
+type ConfigExporter interface {
+	ExportDocument(ctx context.Context) (*config.Document, error)
+}
+
+// Manager runs scheduled and on-demand backups.
+type Manager struct {
+	cfg      *config.Config
+	log      *logger.Logger
+	exporter ConfigExporter
+
+	runMu sync.Mutex // serializes backups and restores
+
+	mu      sync.Mutex
+	running bool
+	seeded  bool
+	last    *Archive
+	lastRun time.Time
+	lastErr string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a backup manager. exporter may be nil, in which case archives
+// carry no config export.
+func New(cfg *config.Config, log *logger.Logger, exporter ConfigExporter) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		cfg:      cfg,
+		log:      log,
+		exporter: exporter,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start runs backups every backup.interval_hours while backup.enabled is set.
+// Both are read on every tick, so config reloads apply without a restart.
+func (m *Manager) Start() error {
+	m.log.Info("Starting backup scheduler")
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(tickerInterval)
+		defer ticker.Stop()
+		for {
+			m.runDue(m.ctx)
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the scheduler and waits for a backup in flight.
+func (m *Manager) Stop() error {
+	m.log.Info("Stopping backup scheduler")
+	m.cancel()
+	m.wg.Wait()
+	return nil
+}
+
+// runDue starts a backup when the newest archive is older than the interval.
+func (m *Manager) runDue(ctx context.Context) {
+	settings := m.settings()
+	if !settings.Enabled {
+		return
+	}
+	m.seed(ctx)
+	next := m.nextRun(settings)
+	if next != nil && time.Now().Before(*next) {
+		return
+	}
+	if _, err := m.Run(ctx); err != nil && ctx.Err() == nil {
+		m.log.Warn("Scheduled backup failed", zap.Error(err))
+	}
+}
+
+// seed loads the newest archive once, so a restart does not trigger a
+// backup right away.
+func (m *Manager) seed(ctx context.Context) {
+	m.mu.Lock()
+	seeded := m.seeded
+	m.mu.Unlock()
+	if seeded {
+		return
+	}
+	archives, err := m.List(ctx)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seeded = true
+	if err != nil {
+		m.lastErr = err.Error()
+		return
+	}
+	if len(archives) > 0 && m.last == nil {
+		latest := archives[0]
+		m.last = &latest
+	}
+}
+
+func (m *Manager) nextRun(settings config.BackupConfig) *time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !settings.Enabled || settings.IntervalHours < 1 {
+		return nil
+	}
+	interval := time.Duration(settings.IntervalHours) * time.Hour
+	var base time.Time
+	if m.last != nil {
+		base = m.last.CreatedAt
+	}
+	// A failed run waits a full interval too, instead of retrying every tick.
+	if m.lastRun.After(base) {
+		base = m.lastRun
+	}
+	if base.IsZero() {
+		return nil
+	}
+	next := base.Add(interval)
+	return &next
+}
+
+// Run writes a backup archive to the target now and applies retention.
+func (m *Manager) Run(ctx context.Context) (Archive, error) {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+
+	m.mu.Lock()
+	m.running = true
+	m.mu.Unlock()
+
+	archive, err := m.run(ctx)
+
+	m.mu.Lock()
+	m.running = false
+	m.seeded = true
+	m.lastRun = time.Now()
+	if err != nil {
+		m.lastErr = err.Error()
+	} else {
+		m.lastErr = ""
+		m.last = &archive
+	}
+	m.mu.Unlock()
+	return archive, err
+}
+
+func (m *Manager) run(ctx context.Context) (Archive, error) {
+	settings := m.settings()
+	target, err := m.target(settings)
+	if err != nil {
+		return Archive{}, err
+	}
+
+	tmp, err := os.CreateTemp("", archivePrefix+"*"+archiveSuffix)
+	if err != nil {
+		return Archive{}, fmt.Errorf("create backup file: %w", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	createdAt := time.Now().UTC().Truncate(time.Second)
+	manifest, err := m.writeArchive(ctx, tmp, createdAt)
+	if err != nil {
+		return Archive{}, err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return Archive{}, fmt.Errorf("size backup file: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return Archive{}, fmt.Errorf("rewind backup file: %w", err)
+	}
+
+	archive := Archive{Name: archiveName(createdAt), Size: size, CreatedAt: createdAt}
+	if err := target.Put(ctx, archive.Name, tmp, size); err != nil {
+		return Archive{}, fmt.Errorf("upload backup to %s: %w", target.Location(), err)
+	}
+	m.log.Info("Backup written",
+		zap.String("archive", archive.Name),
+		zap.String("location", target.Location()),
+		zap.Int64("bytes", size),
+		zap.Bool("database", manifest.Database),
+		zap.Int("memory_files", len(manifest.MemoryFiles)))
+
+	if err := m.prune(ctx, target, settings); err != nil {
+		m.log.Warn("Backup retention failed", zap.Error(err))
+	}
+	return archive, nil
+}
+
+// prune deletes archives beyond backup.keep or older than
+// backup.max_age_days, always sparing the newest one.
+func (m *Manager) prune(ctx context.Context, target Target, settings config.BackupConfig) error {
+	archives, err := listArchives(ctx, target)
+	if err != nil {
+		return err
+	}
+	cutoff := time.Time{}
+	if settings.MaxAgeDays > 0 {
+		cutoff = time.Now().Add(-time.Duration(settings.MaxAgeDays) * 24 * time.Hour)
+	}
+	var errs []error
+	for i, archive := range archives {
+		if i == 0 {
+			continue
+		}
+		expired := !cutoff.IsZero() && archive.CreatedAt.Before(cutoff)
+		if (settings.Keep <= 0 || i < settings.Keep) && !expired {
+			continue
+		}
+		if err := target.Delete(ctx, archive.Name); err != nil {
+			errs = append(errs, fmt.Errorf("delete %s: %w", archive.Name, err))
+			continue
+		}
+		m.log.Info("Pruned backup", zap.String("archive", archive.Name))
+	}
+	return errors.Join(errs...)
+}
+
+// List returns the archives in the target, newest first.
+func (m *Manager) List(ctx context.Context) ([]Archive, error) {
+	target, err := m.target(m.settings())
+	if err != nil {
+		return nil, err
+	}
+	return listArchives(ctx, target)
+}
+
+// Status reports the schedule and the latest backup.
+func (m *Manager) Status() Status {
+	settings := m.settings()
+	status := Status{
+		Enabled:       settings.Enabled,
+		Target:        targetKind(settings),
+		IntervalHours: settings.IntervalHours,
+		Keep:          settings.Keep,
+		MaxAgeDays:    settings.MaxAgeDays,
+	}
+	if target, err := m.target(settings); err == nil {
+		status.Location = target.Location()
+	}
+	status.NextRunAt = m.nextRun(settings)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status.Running = m.running
+	status.LastError = m.lastErr
+	if m.last != nil {
+		last := *m.last
+		status.LastBackup = &last
+	}
+	if !m.lastRun.IsZero() {
+		lastRun := m.lastRun
+		status.LastRunAt = &lastRun
+	}
+	return status
+}
+
+func (m *Manager) settings() config.BackupConfig {
+	return m.cfg.Backup
+}
+
+func (m *Manager) target(settings config.BackupConfig) (Target, error) {
+	if targetKind(settings) == "s3" {
+		return newS3Target(settings.S3)
+	}
+	return newLocalTarget(m.cfg.BackupDir()), nil
+}
+
+func targetKind(settings config.BackupConfig) string {
+	if strings.EqualFold(strings.TrimSpace(settings.Target), "s3") {
+		return "s3"
+	}
+	return "local"
+}
+
+func listArchives(ctx context.Context, target Target) ([]Archive, error) {
+	archives, err := target.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list backups in %s: %w", target.Location(), err)
+	}
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].CreatedAt.After(archives[j].CreatedAt)
+	})
+	return archives, nil
+}
+
+func archiveName(createdAt time.Time) string {
+	return archivePrefix + createdAt.UTC().Format(archiveTimeLayout) + archiveSuffix
+}
+
+// parseArchiveName returns the creation time encoded in an archive name.
+func parseArchiveName(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, archivePrefix) || !strings.HasSuffix(name, archiveSuffix) {
+		return time.Time{}, false
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, archivePrefix), archiveSuffix)
+	createdAt, err := time.Parse(archiveTimeLayout, stamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return createdAt, true
+}
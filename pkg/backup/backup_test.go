@@ -0,0 +1,202 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+type stubExporter struct{ cfg *config.Config }
+
+func (s stubExporter) ExportDocument(context.Context) (*config.Document, error) {
+	return config.ExportDocument(s.cfg, []config.ProviderProfile{{Name: "p1", ProviderKind: "openai"}})
+}
+
+func newTestManager(t *testing.T) (*Manager, *config.Config) {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Backup.Dir = filepath.Join(t.TempDir(), "backups")
+
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		t.Fatalf("open runtime db: %v", err)
+	}
+	if err := config.EnsureRuntimeEntSchema(client); err != nil {
+		t.Fatalf("ensure schema: %v", err)
+	}
+	if err := config.SaveDatabaseSections(cfg, "usage"); err != nil {
+		t.Fatalf("save section: %v", err)
+	}
+	_ = client.Close()
+
+	log, err := logger.New(&logger.Config{Level: logger.LevelError})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return New(cfg, log, stubExporter{cfg: cfg}), cfg
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestRunAndRestore(t *testing.T) {
+	mgr, cfg := newTestManager(t)
+	workspace := cfg.WorkspacePath()
+	writeFile(t, filepath.Join(workspace, "MEMORY.md"), "likes tea")
+	writeFile(t, filepath.Join(workspace, "memory", "2026-10-18.md"), "daily log")
+	writeFile(t, filepath.Join(workspace, "notes.md"), "not memory")
+
+	archive, err := mgr.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.HasPrefix(archive.Name, archivePrefix) || archive.Size == 0 {
+		t.Fatalf("unexpected archive %+v", archive)
+	}
+	status := mgr.Status()
+	if status.LastBackup == nil || status.LastBackup.Name != archive.Name || status.LastError != "" || status.Location != cfg.BackupDir() {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	writeFile(t, filepath.Join(workspace, "MEMORY.md"), "changed")
+	if err := os.Remove(filepath.Join(workspace, "memory", "2026-10-18.md")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	result, err := mgr.Restore(context.Background(), archive.Name, RestoreOptions{Database: true, Memory: true, Config: true})
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if result.MemoryFiles != 2 || result.Database == "" || !result.Manifest.Database || !result.Manifest.Config {
+		t.Fatalf("unexpected restore result %+v", result)
+	}
+	if data, _ := os.ReadFile(filepath.Join(workspace, "MEMORY.md")); string(data) != "likes tea" {
+		t.Fatalf("MEMORY.md not restored: %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(workspace, "memory", "2026-10-18.md")); string(data) != "daily log" {
+		t.Fatalf("daily memory not restored: %q", data)
+	}
+	if _, err := os.Stat(result.Database + ".pre-restore"); err != nil {
+		t.Fatalf("expected previous database to be kept: %v", err)
+	}
+	if result.Config == nil || len(result.Config.Providers) != 1 || result.Config.Sections["usage"] == nil {
+		t.Fatalf("unexpected config export %+v", result.Config)
+	}
+
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		t.Fatalf("open restored db: %v", err)
+	}
+	defer client.Close()
+	if n, err := client.ConfigSection.Query().Count(context.Background()); err != nil || n == 0 {
+		t.Fatalf("expected restored config sections, got %d (%v)", n, err)
+	}
+}
+
+func TestRestoreLocalFileWithoutDatabase(t *testing.T) {
+	mgr, cfg := newTestManager(t)
+	writeFile(t, filepath.Join(cfg.WorkspacePath(), "MEMORY.md"), "likes tea")
+	archive, err := mgr.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	result, err := mgr.Restore(context.Background(), filepath.Join(cfg.BackupDir(), archive.Name), RestoreOptions{Memory: true})
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if result.Database != "" || result.Config != nil || result.MemoryFiles != 1 {
+		t.Fatalf("expected only memory files restored, got %+v", result)
+	}
+
+	if _, err := mgr.Restore(context.Background(), "nekobot-backup-20000101T000000Z.tar.gz", RestoreOptions{Memory: true}); err == nil {
+		t.Fatal("expected a missing archive to fail")
+	}
+}
+
+func TestPruneKeepsNewestArchives(t *testing.T) {
+	mgr, cfg := newTestManager(t)
+	dir := cfg.BackupDir()
+	now := time.Now().UTC()
+	for _, age := range []time.Duration{1 * time.Hour, 30 * time.Hour, 60 * time.Hour, 400 * time.Hour} {
+		writeFile(t, filepath.Join(dir, archiveName(now.Add(-age))), "old")
+	}
+	writeFile(t, filepath.Join(dir, "unrelated.txt"), "keep")
+
+	cfg.Backup.Keep = 3
+	cfg.Backup.MaxAgeDays = 2
+	if err := mgr.prune(context.Background(), newLocalTarget(dir), cfg.Backup); err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+	archives, err := mgr.List(context.Background())
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	// 60h exceeds max_age_days and 400h exceeds keep as well.
+	if len(archives) != 2 || archives[0].Name != archiveName(now.Add(-time.Hour)) {
+		t.Fatalf("unexpected archives after prune: %+v", archives)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "unrelated.txt")); err != nil {
+		t.Fatalf("prune touched an unrelated file: %v", err)
+	}
+
+	// The newest archive survives any policy.
+	writeFile(t, filepath.Join(dir, archiveName(now.Add(-24*time.Hour*30))), "old")
+	if err := mgr.prune(context.Background(), newLocalTarget(dir), config.BackupConfig{MaxAgeDays: 1}); err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+	if archives, _ = mgr.List(context.Background()); len(archives) != 1 {
+		t.Fatalf("expected only the newest archive, got %+v", archives)
+	}
+}
+
+func TestNextRunWaitsForInterval(t *testing.T) {
+	mgr, cfg := newTestManager(t)
+	cfg.Backup.Enabled = true
+	cfg.Backup.IntervalHours = 6
+	if next := mgr.nextRun(cfg.Backup); next != nil {
+		t.Fatalf("expected an immediate first backup, got %v", next)
+	}
+	created := time.Now().Add(-2 * time.Hour)
+	mgr.last = &Archive{Name: archiveName(created), CreatedAt: created}
+	next := mgr.nextRun(cfg.Backup)
+	if next == nil || !next.Equal(created.Add(6*time.Hour)) {
+		t.Fatalf("unexpected next run %v", next)
+	}
+
+	data, err := json.Marshal(mgr.Status())
+	if err != nil {
+		t.Fatalf("marshal status: %v", err)
+	}
+	if !strings.Contains(string(data), `"next_run_at"`) || !strings.Contains(string(data), `"target":"local"`) {
+		t.Fatalf("unexpected status JSON %s", data)
+	}
+}
+
+func TestS3TargetRequiresBucket(t *testing.T) {
+	if _, err := newS3Target(config.S3Config{Endpoint: "s3.amazonaws.com"}); err == nil {
+		t.Fatal("expected a missing bucket to fail")
+	}
+	target, err := newS3Target(config.S3Config{Endpoint: "https://minio.local:9000/", Bucket: "backups", Prefix: "/nekobot"})
+	if err != nil {
+		t.Fatalf("newS3Target failed: %v", err)
+	}
+	if target.Location() != "s3://backups/nekobot/" {
+		t.Fatalf("unexpected location %q", target.Location())
+	}
+}
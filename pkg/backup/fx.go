@@ -0,0 +1,46 @@
+package backup
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/providerstore"
+)
+
+// Module is the fx module for scheduled backups.
+var Module = fx.Module("backup",
+	fx.Provide(NewManager),
+)
+
+type managerParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Config    *config.Config
+	Log       *logger.Logger
+	Providers *providerstore.Manager `optional:"true"`
+}
+
+// NewManager creates the backup manager for fx and starts its scheduler
+// with the app.
+func NewManager(p managerParams) *Manager {
+	var exporter ConfigExporter
+	if p.Providers != nil {
+		exporter = p.Providers
+	}
+	manager := New(p.Config, p.Log, exporter)
+
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			return manager.Start()
+		},
+		OnStop: func(context.Context) error {
+			return manager.Stop()
+		},
+	})
+
+	return manager
+}
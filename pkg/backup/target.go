@@ -0,0 +1,172 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"nekobot/pkg/config"
+)
+
+// Target stores backup archives.
+type Target interface {
+	// Location describes where archives go, for logs and status.
+	Location() string
+	Put(ctx context.Context, name string, r io.Reader, size int64) error
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// List returns the archives in any order.
+	List(ctx context.Context) ([]Archive, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// localTarget keeps archives in a directory.
+type localTarget struct {
+	dir string
+}
+
+func newLocalTarget(dir string) *localTarget {
+	return &localTarget{dir: dir}
+}
+
+func (t *localTarget) Location() string { return t.dir }
+
+func (t *localTarget) Put(_ context.Context, name string, r io.Reader, _ int64) error {
+	if err := os.MkdirAll(t.dir, 0o700); err != nil {
+		return fmt.Errorf("create backup directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(t.dir, ".tmp-"+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(t.dir, name))
+}
+
+func (t *localTarget) Open(_ context.Context, name string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(t.dir, filepath.Base(name)))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return f, err
+}
+
+func (t *localTarget) List(_ context.Context) ([]Archive, error) {
+	entries, err := os.ReadDir(t.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var archives []Archive
+	for _, entry := range entries {
+		createdAt, ok := parseArchiveName(entry.Name())
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		archives = append(archives, Archive{Name: entry.Name(), Size: info.Size(), CreatedAt: createdAt})
+	}
+	return archives, nil
+}
+
+func (t *localTarget) Delete(_ context.Context, name string) error {
+	return os.Remove(filepath.Join(t.dir, filepath.Base(name)))
+}
+
+// s3Target keeps archives in an S3-compatible bucket under a key prefix.
+type s3Target struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func newS3Target(cfg config.S3Config) (*s3Target, error) {
+	endpoint := strings.TrimSpace(cfg.Endpoint)
+	endpoint = strings.TrimPrefix(strings.TrimPrefix(endpoint, "https://"), "http://")
+	endpoint = strings.TrimRight(endpoint, "/")
+	bucket := strings.TrimSpace(cfg.Bucket)
+	if endpoint == "" || bucket == "" {
+		return nil, fmt.Errorf("backup.s3.endpoint and backup.s3.bucket are required for the s3 target")
+	}
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(strings.TrimSpace(cfg.AccessKey), strings.TrimSpace(cfg.SecretKey), ""),
+		Secure: !cfg.Insecure,
+		Region: strings.TrimSpace(cfg.Region),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create s3 client: %w", err)
+	}
+	prefix := strings.TrimLeft(strings.TrimSpace(cfg.Prefix), "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &s3Target{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+func (t *s3Target) Location() string {
+	return "s3://" + t.bucket + "/" + t.prefix
+}
+
+func (t *s3Target) Put(ctx context.Context, name string, r io.Reader, size int64) error {
+	_, err := t.client.PutObject(ctx, t.bucket, t.prefix+name, r, size, minio.PutObjectOptions{
+		ContentType: "application/gzip",
+	})
+	return err
+}
+
+func (t *s3Target) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	obj, err := t.client.GetObject(ctx, t.bucket, t.prefix+name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// GetObject is lazy; Stat surfaces a missing key before reading.
+	if _, err := obj.Stat(); err != nil {
+		_ = obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (t *s3Target) List(ctx context.Context) ([]Archive, error) {
+	var archives []Archive
+	for obj := range t.client.ListObjects(ctx, t.bucket, minio.ListObjectsOptions{Prefix: t.prefix + archivePrefix}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		name := strings.TrimPrefix(obj.Key, t.prefix)
+		createdAt, ok := parseArchiveName(name)
+		if !ok {
+			continue
+		}
+		archives = append(archives, Archive{Name: name, Size: obj.Size, CreatedAt: createdAt})
+	}
+	return archives, nil
+}
+
+func (t *s3Target) Delete(ctx context.Context, name string) error {
+	return t.client.RemoveObject(ctx, t.bucket, t.prefix+name, minio.RemoveObjectOptions{})
+}
@@ -2,6 +2,8 @@ package bus
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -191,3 +193,112 @@ func BenchmarkBusThroughput(b *testing.B) {
 		}
 	})
 }
+
+func TestWithOutboundFilterRewritesTextMessages(t *testing.T) {
+	log, err := logger.New(&logger.Config{
+		Level: "info",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	inner := NewLocalBus(log, 10)
+	if err := inner.Start(); err != nil {
+		t.Fatalf("Failed to start bus: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := inner.Stop(); err != nil {
+			t.Fatalf("Failed to stop bus: %v", err)
+		}
+	})
+
+	bus := WithOutboundFilter(inner, func(channelID, content string) string {
+		return "[" + channelID + "] " + content
+	})
+	received := make(chan *Message, 3)
+	bus.RegisterOutboundHandler("test", func(ctx context.Context, msg *Message) error {
+		received <- msg
+		return nil
+	})
+
+	original := &Message{ID: "out-1", ChannelID: "test", Type: MessageTypeText, Content: "hello"}
+	if err := bus.SendOutbound(original); err != nil {
+		t.Fatalf("Failed to send message: %v", err)
+	}
+	if err := bus.SendOutbound(&Message{ID: "out-2", ChannelID: "test", Type: MessageTypeImage, Content: "photo.png"}); err != nil {
+		t.Fatalf("Failed to send message: %v", err)
+	}
+	if err := bus.SendOutbound(&Message{ID: "out-3", ChannelID: "test", Type: MessageTypeStreamUpdate, Content: "hel"}); err != nil {
+		t.Fatalf("Failed to send message: %v", err)
+	}
+
+	for _, want := range []string{"[test] hello", "photo.png", "[test] hel"} {
+		select {
+		case msg := <-received:
+			if msg.Content != want {
+				t.Fatalf("Expected content %q, got %q", want, msg.Content)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timeout waiting for outbound message")
+		}
+	}
+	if original.Content != "hello" {
+		t.Fatalf("Expected caller's message to be left untouched, got %q", original.Content)
+	}
+}
+
+func TestFileAttachmentTypesByExtensionAndReadsLazily(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chart.PNG")
+	if err := os.WriteFile(path, []byte("png"), 0o644); err != nil {
+		t.Fatalf("write chart: %v", err)
+	}
+
+	attachment := FileAttachment(path)
+	if attachment.Type != MessageTypeImage || attachment.MIMEType != "image/png" || attachment.FileName() != "chart.PNG" {
+		t.Fatalf("expected a PNG image attachment, got %+v", attachment)
+	}
+	if len(attachment.Data) != 0 {
+		t.Fatal("expected the file not to be read up front")
+	}
+	data, err := attachment.ReadData()
+	if err != nil || string(data) != "png" {
+		t.Fatalf("expected file content, got %q / %v", data, err)
+	}
+
+	if doc := FileAttachment("/tmp/report.unknownext"); doc.Type != MessageTypeFile || doc.MIMEType != "application/octet-stream" {
+		t.Fatalf("expected a generic file attachment, got %+v", doc)
+	}
+}
+
+func TestSubscribeConfigChanges(t *testing.T) {
+	log, _ := logger.New(&logger.Config{Level: "error", OutputPath: ""})
+	bus := NewLocalBus(log, 10)
+	if err := bus.Start(); err != nil {
+		t.Fatalf("start bus: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := bus.Stop(); err != nil {
+			t.Fatalf("stop bus: %v", err)
+		}
+	})
+
+	received := make(chan []string, 2)
+	SubscribeConfigChanges(bus, func(sections []string) {
+		received <- sections
+	})
+	if err := PublishEvent(bus, EventToolFailed, "", "ignored", nil); err != nil {
+		t.Fatalf("publish tool event: %v", err)
+	}
+	if err := PublishConfigChanged(bus, []string{"channels", "tools"}); err != nil {
+		t.Fatalf("publish config change: %v", err)
+	}
+
+	select {
+	case sections := <-received:
+		if len(sections) != 2 || sections[0] != "channels" || sections[1] != "tools" {
+			t.Fatalf("unexpected sections: %v", sections)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for config change")
+	}
+}
@@ -0,0 +1,102 @@
+package bus
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventChannelID is the reserved channel carrying runtime events. Nothing
+// delivers it to users; subscribers such as the notification rules engine
+// register outbound handlers for it.
+const EventChannelID = "events"
+
+// MessageTypeEvent marks a runtime event published on EventChannelID.
+// Content holds a human-readable description of the event.
+const MessageTypeEvent MessageType = "event"
+
+// DataKeyEvent holds the event type of a MessageTypeEvent message.
+const DataKeyEvent = "event"
+
+// Runtime event types.
+const (
+	EventToolFailed        = "tool.failed"
+	EventApprovalRequested = "approval.requested"
+	EventHeartbeatResult   = "heartbeat.result"
+	EventProviderCooldown  = "provider.cooldown"
+	EventConfigChanged     = "config.changed"
+	EventToolSessionLimit  = "tool_session.limit_exceeded"
+	EventChannelStatus     = "channel.status"
+)
+
+// DataKeyConfigSections holds the comma-separated config sections changed by
+// an EventConfigChanged event.
+const DataKeyConfigSections = "sections"
+
+// EventTypes lists the runtime event types in a stable order.
+var EventTypes = []string{
+	EventToolFailed,
+	EventApprovalRequested,
+	EventHeartbeatResult,
+	EventProviderCooldown,
+	EventConfigChanged,
+	EventToolSessionLimit,
+	EventChannelStatus,
+}
+
+// PublishEvent sends a runtime event on EventChannelID. sessionID names the
+// conversation the event belongs to, if any; fields are copied into Data next
+// to DataKeyEvent. A nil bus drops the event.
+func PublishEvent(b Bus, eventType, sessionID, content string, fields map[string]string) error {
+	if b == nil {
+		return nil
+	}
+	data := make(map[string]interface{}, len(fields)+1)
+	for key, value := range fields {
+		data[key] = value
+	}
+	data[DataKeyEvent] = eventType
+	return b.SendOutbound(&Message{
+		ID:        "event:" + uuid.NewString(),
+		ChannelID: EventChannelID,
+		SessionID: sessionID,
+		Type:      MessageTypeEvent,
+		Content:   content,
+		Data:      data,
+		Timestamp: time.Now(),
+	})
+}
+
+// PublishConfigChanged announces that a config reload changed sections.
+func PublishConfigChanged(b Bus, sections []string) error {
+	if len(sections) == 0 {
+		return nil
+	}
+	return PublishEvent(b, EventConfigChanged, "",
+		"Configuration reloaded: "+strings.Join(sections, ", ")+" changed",
+		map[string]string{DataKeyConfigSections: strings.Join(sections, ",")})
+}
+
+// SubscribeConfigChanges calls fn with the changed sections of every
+// EventConfigChanged event published on b.
+func SubscribeConfigChanges(b Bus, fn func(sections []string)) {
+	if b == nil || fn == nil {
+		return
+	}
+	b.RegisterOutboundHandler(EventChannelID, func(_ context.Context, msg *Message) error {
+		if msg == nil || msg.Data == nil || msg.Data[DataKeyEvent] != EventConfigChanged {
+			return nil
+		}
+		raw, _ := msg.Data[DataKeyConfigSections].(string)
+		var sections []string
+		for _, section := range strings.Split(raw, ",") {
+			if section = strings.TrimSpace(section); section != "" {
+				sections = append(sections, section)
+			}
+		}
+		fn(sections)
+		return nil
+	})
+}
@@ -2,6 +2,7 @@ package bus
 
 import (
 	"fmt"
+	"time"
 
 	"nekobot/pkg/logger"
 )
@@ -12,6 +13,9 @@ type BusType string
 const (
 	BusTypeLocal BusType = "local"
 	BusTypeRedis BusType = "redis"
+	// BusTypeRedisStreams splits channel ingestion and agent turns across
+	// processes through Redis streams.
+	BusTypeRedisStreams BusType = "redis_streams"
 )
 
 // Config configures the bus.
@@ -24,6 +28,13 @@ type Config struct {
 	RedisPassword string
 	RedisDB       int
 	RedisPrefix   string
+
+	// Redis streams config
+	InstanceID     string
+	Role           Role
+	ConsumeInbound bool
+	Workers        int
+	ClaimIdle      time.Duration
 }
 
 // NewBus creates a new bus based on configuration.
@@ -45,6 +56,23 @@ func NewBus(log *logger.Logger, cfg *Config) (Bus, error) {
 			Prefix:   cfg.RedisPrefix,
 		})
 
+	case BusTypeRedisStreams:
+		if cfg.RedisAddr == "" {
+			return nil, fmt.Errorf("redis address is required for redis_streams bus")
+		}
+
+		return NewStreamBus(log, &StreamBusConfig{
+			Addr:           cfg.RedisAddr,
+			Password:       cfg.RedisPassword,
+			DB:             cfg.RedisDB,
+			Prefix:         cfg.RedisPrefix,
+			InstanceID:     cfg.InstanceID,
+			Role:           cfg.Role,
+			ConsumeInbound: cfg.ConsumeInbound,
+			Workers:        cfg.Workers,
+			ClaimIdle:      cfg.ClaimIdle,
+		})
+
 	default:
 		return nil, fmt.Errorf("unknown bus type: %s", cfg.Type)
 	}
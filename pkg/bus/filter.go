@@ -0,0 +1,35 @@
+package bus
+
+// OutboundFilter rewrites the text of a message headed to channelID.
+type OutboundFilter func(channelID, content string) string
+
+// filteredBus runs outbound text messages, including stream updates, through
+// a filter before handing them to the wrapped bus.
+type filteredBus struct {
+	Bus
+	filter OutboundFilter
+}
+
+// WithOutboundFilter wraps b so every outbound text message passes through
+// filter first. A nil filter returns b unchanged.
+func WithOutboundFilter(b Bus, filter OutboundFilter) Bus {
+	if b == nil || filter == nil {
+		return b
+	}
+	return &filteredBus{Bus: b, filter: filter}
+}
+
+// SendOutbound filters the message content and forwards a copy, leaving the
+// caller's message untouched.
+func (b *filteredBus) SendOutbound(msg *Message) error {
+	if msg == nil || msg.Content == "" || !filtersType(msg.Type) {
+		return b.Bus.SendOutbound(msg)
+	}
+	filtered := *msg
+	filtered.Content = b.filter(msg.ChannelID, msg.Content)
+	return b.Bus.SendOutbound(&filtered)
+}
+
+func filtersType(msgType MessageType) bool {
+	return msgType == "" || msgType == MessageTypeText || msgType == MessageTypeStreamUpdate
+}
@@ -2,11 +2,15 @@ package bus
 
 import (
 	"context"
+	"os"
+	"strings"
+	"time"
 
 	"go.uber.org/fx"
 
 	"nekobot/pkg/config"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/responsefilters"
 )
 
 // Module is the fx module for the message bus.
@@ -14,12 +18,20 @@ var Module = fx.Module("bus",
 	fx.Provide(NewMessageBus),
 )
 
+// MessageBusParams holds the dependencies of NewMessageBus. Role is
+// supplied by the gateway and worker commands; other commands only publish.
+type MessageBusParams struct {
+	fx.In
+
+	LC     fx.Lifecycle
+	Logger *logger.Logger
+	Config *config.Config
+	Role   Role `optional:"true"`
+}
+
 // NewMessageBus creates a new message bus for fx.
-func NewMessageBus(
-	lc fx.Lifecycle,
-	log *logger.Logger,
-	cfg *config.Config,
-) (Bus, error) {
+func NewMessageBus(p MessageBusParams) (Bus, error) {
+	lc, log, cfg := p.LC, p.Logger, p.Config
 	// Determine bus configuration
 	busConfig := &Config{
 		Type:       BusTypeLocal, // Default to local
@@ -40,6 +52,12 @@ func NewMessageBus(
 		}
 	}
 
+	busConfig.InstanceID = strings.TrimSpace(os.Getenv(config.BusInstanceIDEnv))
+	busConfig.Role = p.Role
+	busConfig.ConsumeInbound = p.Role == RoleWorker || (p.Role == RoleGateway && cfg.Bus.ProcessInbound)
+	busConfig.Workers = cfg.Bus.Workers
+	busConfig.ClaimIdle = time.Duration(cfg.Bus.ClaimIdleSeconds) * time.Second
+
 	// Default buffer size
 	if busConfig.BufferSize <= 0 {
 		busConfig.BufferSize = 100
@@ -49,6 +67,7 @@ func NewMessageBus(
 	if err != nil {
 		return nil, err
 	}
+	bus = WithOutboundFilter(bus, responsefilters.NewProcessor(cfg, log).Apply)
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
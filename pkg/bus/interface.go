@@ -3,6 +3,11 @@ package bus
 
 import (
 	"context"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -17,20 +22,97 @@ const (
 	MessageTypeFile     MessageType = "file"
 	MessageTypeLocation MessageType = "location"
 	MessageTypeCommand  MessageType = "command"
+	// MessageTypeStreamUpdate carries the partial text of a reply still being
+	// generated. Content holds the full text so far; the final reply follows
+	// as a regular text message.
+	MessageTypeStreamUpdate MessageType = "stream_update"
 )
 
+// DataKeyStreamReplies marks an inbound message whose channel can show
+// MessageTypeStreamUpdate messages for the reply.
+const DataKeyStreamReplies = "stream_replies"
+
+// DataKeyDirectMessage marks an inbound message from a one-to-one chat. Only
+// direct messages follow the sender's linked identity into a shared session.
+const DataKeyDirectMessage = "direct_message"
+
+// MaxImageBytes is the largest image channels download and attach to an
+// inbound message. Larger images are dropped.
+const MaxImageBytes = 10 << 20
+
+// Attachment is a file carried by a message: a photo the user sent, or an
+// image or document the agent replies with. Inbound files are downloaded
+// into Data; URL is only used when the content is publicly reachable and was
+// not downloaded. Outbound files generated in the workspace are referenced by
+// Path instead of being read into memory.
+type Attachment struct {
+	Type     MessageType `json:"type"`                // MessageTypeImage, MessageTypeFile, ...
+	Name     string      `json:"name,omitempty"`      // Original file name
+	MIMEType string      `json:"mime_type,omitempty"` // e.g. "image/jpeg"
+	Data     []byte      `json:"data,omitempty"`      // File content
+	URL      string      `json:"url,omitempty"`       // Remote location when Data is empty
+	Path     string      `json:"path,omitempty"`      // Local file when Data is empty
+}
+
+// FileAttachment describes the local file at path, typed as an image or a
+// file by its extension.
+func FileAttachment(path string) Attachment {
+	attachment := Attachment{
+		Type:     MessageTypeFile,
+		Name:     filepath.Base(path),
+		MIMEType: mime.TypeByExtension(strings.ToLower(filepath.Ext(path))),
+		Path:     path,
+	}
+	if attachment.MIMEType == "" {
+		attachment.MIMEType = "application/octet-stream"
+	}
+	if attachment.IsImage() {
+		attachment.Type = MessageTypeImage
+	}
+	return attachment
+}
+
+// IsImage reports whether the attachment is an image.
+func (a Attachment) IsImage() bool {
+	return a.Type == MessageTypeImage || strings.HasPrefix(a.MIMEType, "image/")
+}
+
+// FileName returns the name to show for the attachment.
+func (a Attachment) FileName() string {
+	if name := strings.TrimSpace(a.Name); name != "" {
+		return name
+	}
+	if a.Path != "" {
+		return filepath.Base(a.Path)
+	}
+	return "file"
+}
+
+// ReadData returns the attachment content, reading Path when Data is empty.
+func (a Attachment) ReadData() ([]byte, error) {
+	if len(a.Data) > 0 || a.Path == "" {
+		return a.Data, nil
+	}
+	data, err := os.ReadFile(a.Path)
+	if err != nil {
+		return nil, fmt.Errorf("read attachment %s: %w", a.FileName(), err)
+	}
+	return data, nil
+}
+
 // Message represents a message flowing through the bus.
 type Message struct {
-	ID        string                 `json:"id"`         // Unique message ID
-	ChannelID string                 `json:"channel_id"` // Source/target channel
-	SessionID string                 `json:"session_id"` // Session/conversation ID
-	UserID    string                 `json:"user_id"`    // User identifier
-	Username  string                 `json:"username"`   // User display name
-	Type      MessageType            `json:"type"`       // Message type
-	Content   string                 `json:"content"`    // Text content
-	Data      map[string]interface{} `json:"data"`       // Additional data
-	Timestamp time.Time              `json:"timestamp"`  // Message timestamp
-	ReplyTo   string                 `json:"reply_to"`   // ID of message being replied to
+	ID          string                 `json:"id"`                    // Unique message ID
+	ChannelID   string                 `json:"channel_id"`            // Source/target channel
+	SessionID   string                 `json:"session_id"`            // Session/conversation ID
+	UserID      string                 `json:"user_id"`               // User identifier
+	Username    string                 `json:"username"`              // User display name
+	Type        MessageType            `json:"type"`                  // Message type
+	Content     string                 `json:"content"`               // Text content
+	Attachments []Attachment           `json:"attachments,omitempty"` // Photos the user sent, files the agent replies with
+	Data        map[string]interface{} `json:"data"`                  // Additional data
+	Timestamp   time.Time              `json:"timestamp"`             // Message timestamp
+	ReplyTo     string                 `json:"reply_to"`              // ID of message being replied to
 }
 
 // Handler is a function that processes messages.
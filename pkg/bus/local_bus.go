@@ -204,6 +204,10 @@ func (b *LocalBus) handleMessage(msg *Message, direction string, handlerMap map[
 	b.mu.RUnlock()
 
 	if len(handlers) == 0 {
+		if msg.ChannelID == EventChannelID {
+			// Runtime events are dropped quietly when nobody subscribes.
+			return
+		}
 		b.log.Warn("No handlers registered for channel",
 			zap.String("channel", msg.ChannelID),
 			zap.String("direction", direction),
@@ -0,0 +1,570 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/logger"
+)
+
+// DataKeyBusOrigin holds the instance that received an inbound message on
+// the redis_streams bus. Replies copy the inbound data, so the key routes
+// them back to that instance.
+const DataKeyBusOrigin = "bus_origin"
+
+// Role is the part a process plays on the redis_streams bus.
+type Role string
+
+const (
+	// RoleGateway runs channels: it publishes inbound messages, delivers
+	// replies for the channels it registered and handles inbound messages
+	// itself unless bus.process_inbound is off.
+	RoleGateway Role = "gateway"
+	// RoleWorker handles inbound messages for gateways.
+	RoleWorker Role = "worker"
+)
+
+const (
+	streamInboundGroup  = "workers"
+	streamOutboundGroup = "delivery"
+	streamMaxLen        = 10000
+	// streamBlock bounds how long Stop waits for an idle reader.
+	streamBlock = time.Second
+)
+
+// StreamBusConfig configures the Redis streams bus.
+type StreamBusConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	Prefix   string
+
+	// InstanceID names this process; empty uses the hostname and role.
+	InstanceID string
+	Role       Role
+	// ConsumeInbound makes this process handle inbound messages with
+	// Workers concurrent handlers.
+	ConsumeInbound bool
+	Workers        int
+	// ClaimIdle hands inbound messages pending this long to this process.
+	ClaimIdle time.Duration
+}
+
+// StreamBus is a Redis-based message bus for running channels and agent
+// turns in separate processes. Inbound messages go to one stream read by a
+// consumer group, so each is handled by exactly one worker; replies go to
+// the stream of the instance that received the inbound message, or of the
+// instance that registered the channel.
+type StreamBus struct {
+	log            *logger.Logger
+	client         *redis.Client
+	prefix         string
+	instance       string
+	role           Role
+	consumeInbound bool
+	workers        int
+	claimIdle      time.Duration
+
+	inboundHandlers  map[string][]Handler
+	outboundHandlers map[string][]Handler
+	mu               sync.RWMutex
+
+	// inflight holds the inbound entries being handled, which stay pending
+	// and would otherwise be claimed again by claimPending.
+	inflight   map[string]struct{}
+	inflightMu sync.Mutex
+
+	// Lifecycle
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// Metrics
+	messagesIn  uint64
+	messagesOut uint64
+	errors      uint64
+	claimed     uint64
+	metricsLock sync.RWMutex
+}
+
+// NewStreamBus creates a Redis streams message bus.
+func NewStreamBus(log *logger.Logger, cfg *StreamBusConfig) (*StreamBus, error) {
+	if cfg.Prefix == "" {
+		cfg.Prefix = "nekobot:bus:"
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 4
+	}
+	if cfg.ClaimIdle <= 0 {
+		cfg.ClaimIdle = 10 * time.Minute
+	}
+	instance := strings.TrimSpace(cfg.InstanceID)
+	if instance == "" {
+		instance = defaultInstanceID(cfg.Role)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("connecting to Redis: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &StreamBus{
+		log:              log,
+		client:           client,
+		prefix:           cfg.Prefix,
+		instance:         instance,
+		role:             cfg.Role,
+		consumeInbound:   cfg.ConsumeInbound,
+		workers:          cfg.Workers,
+		claimIdle:        cfg.ClaimIdle,
+		inboundHandlers:  make(map[string][]Handler),
+		outboundHandlers: make(map[string][]Handler),
+		inflight:         make(map[string]struct{}),
+		ctx:              ctx,
+		cancel:           cancel,
+	}
+
+	log.Info("Redis streams bus initialized",
+		zap.String("addr", cfg.Addr),
+		zap.String("instance", instance),
+		zap.String("role", string(cfg.Role)),
+		zap.Bool("consume_inbound", cfg.ConsumeInbound))
+
+	return b, nil
+}
+
+// defaultInstanceID keeps a gateway and a worker on one host apart.
+func defaultInstanceID(role Role) string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "nekobot"
+	}
+	if role == "" {
+		return fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	return host + "-" + string(role)
+}
+
+// InstanceID returns the name replies to this process are routed by.
+func (b *StreamBus) InstanceID() string {
+	return b.instance
+}
+
+func (b *StreamBus) inboundStream() string {
+	return b.prefix + "stream:inbound"
+}
+
+func (b *StreamBus) outboundStream(instance string) string {
+	return b.prefix + "stream:outbound:" + instance
+}
+
+func (b *StreamBus) routesKey() string {
+	return b.prefix + "routes"
+}
+
+// Start creates the consumer groups and starts the readers. Processes
+// without a role only publish.
+func (b *StreamBus) Start() error {
+	b.log.Info("Starting Redis streams message bus", zap.String("instance", b.instance))
+	if b.role == "" {
+		return nil
+	}
+
+	if err := b.ensureGroup(b.outboundStream(b.instance), streamOutboundGroup); err != nil {
+		return err
+	}
+	b.wg.Add(1)
+	go b.readOutbound()
+
+	if b.consumeInbound {
+		if err := b.ensureGroup(b.inboundStream(), streamInboundGroup); err != nil {
+			return err
+		}
+		for i := 0; i < b.workers; i++ {
+			b.wg.Add(1)
+			go b.readInbound()
+		}
+		b.wg.Add(1)
+		go b.claimInbound()
+	}
+	return nil
+}
+
+func (b *StreamBus) ensureGroup(stream, group string) error {
+	err := b.client.XGroupCreateMkStream(b.ctx, stream, group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("create consumer group %s: %w", stream, err)
+	}
+	return nil
+}
+
+// Stop stops the readers, waiting for messages being handled, and removes
+// the channel routes this instance registered.
+func (b *StreamBus) Stop() error {
+	b.log.Info("Stopping Redis streams message bus")
+
+	b.cancel()
+	b.wg.Wait()
+
+	b.mu.RLock()
+	channels := make([]string, 0, len(b.outboundHandlers))
+	for channelID := range b.outboundHandlers {
+		channels = append(channels, channelID)
+	}
+	b.mu.RUnlock()
+	for _, channelID := range channels {
+		b.releaseRoute(channelID)
+	}
+
+	_ = b.client.Close()
+	b.log.Info("Redis streams message bus stopped")
+	return nil
+}
+
+// RegisterInboundHandler registers a handler for inbound messages.
+func (b *StreamBus) RegisterInboundHandler(channelID string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inboundHandlers[channelID] = append(b.inboundHandlers[channelID], handler)
+	b.log.Info("Registered inbound handler", zap.String("channel", channelID))
+}
+
+// UnregisterInboundHandlers removes all inbound handlers for a channel.
+func (b *StreamBus) UnregisterInboundHandlers(channelID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.inboundHandlers, channelID)
+	b.log.Info("Unregistered inbound handlers", zap.String("channel", channelID))
+}
+
+// RegisterOutboundHandler registers a handler for outbound messages and
+// routes replies without an origin for the channel to this instance.
+func (b *StreamBus) RegisterOutboundHandler(channelID string, handler Handler) {
+	b.mu.Lock()
+	b.outboundHandlers[channelID] = append(b.outboundHandlers[channelID], handler)
+	b.mu.Unlock()
+
+	if b.role == "" {
+		// Nothing reads this process's reply stream.
+		return
+	}
+	if err := b.client.HSet(b.ctx, b.routesKey(), channelID, b.instance).Err(); err != nil {
+		b.log.Warn("Failed to register channel route",
+			zap.String("channel", channelID), zap.Error(err))
+	}
+	b.log.Info("Registered outbound handler", zap.String("channel", channelID))
+}
+
+// UnregisterOutboundHandlers removes all outbound handlers for a channel.
+func (b *StreamBus) UnregisterOutboundHandlers(channelID string) {
+	b.mu.Lock()
+	delete(b.outboundHandlers, channelID)
+	b.mu.Unlock()
+
+	b.releaseRoute(channelID)
+	b.log.Info("Unregistered outbound handlers", zap.String("channel", channelID))
+}
+
+// releaseRoute drops the channel route if it still points here.
+func (b *StreamBus) releaseRoute(channelID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	owner, err := b.client.HGet(ctx, b.routesKey(), channelID).Result()
+	if err == nil && owner == b.instance {
+		_ = b.client.HDel(ctx, b.routesKey(), channelID).Err()
+	}
+}
+
+// RegisterHandler registers an outbound handler for backward compatibility.
+func (b *StreamBus) RegisterHandler(channelID string, handler Handler) {
+	b.RegisterOutboundHandler(channelID, handler)
+}
+
+// UnregisterHandlers removes all outbound handlers for backward compatibility.
+func (b *StreamBus) UnregisterHandlers(channelID string) {
+	b.UnregisterOutboundHandlers(channelID)
+}
+
+// SendInbound queues an inbound message (from channel to agent) for one
+// worker, stamped with this instance as the origin of replies.
+func (b *StreamBus) SendInbound(msg *Message) error {
+	stamped := *msg
+	stamped.Data = make(map[string]interface{}, len(msg.Data)+1)
+	for key, value := range msg.Data {
+		stamped.Data[key] = value
+	}
+	if origin, _ := stamped.Data[DataKeyBusOrigin].(string); origin == "" {
+		stamped.Data[DataKeyBusOrigin] = b.instance
+	}
+	if err := b.add(b.inboundStream(), &stamped); err != nil {
+		return err
+	}
+	b.incrementMessagesIn()
+	return nil
+}
+
+// SendOutbound sends an outbound message (from agent to channel) to the
+// instance the inbound message came from, or else to the instance that
+// registered the channel.
+func (b *StreamBus) SendOutbound(msg *Message) error {
+	target, _ := msg.Data[DataKeyBusOrigin].(string)
+	if target == "" {
+		owner, err := b.client.HGet(b.ctx, b.routesKey(), msg.ChannelID).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return fmt.Errorf("looking up channel route: %w", err)
+		}
+		target = owner
+	}
+	if target == "" {
+		b.incrementErrors()
+		return fmt.Errorf("no gateway instance delivers channel %s", msg.ChannelID)
+	}
+	if err := b.add(b.outboundStream(target), msg); err != nil {
+		return err
+	}
+	b.incrementMessagesOut()
+	return nil
+}
+
+func (b *StreamBus) add(stream string, msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshaling message: %w", err)
+	}
+	err = b.client.XAdd(b.ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: streamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"msg": data},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("adding to Redis stream: %w", err)
+	}
+	return nil
+}
+
+// GetMetrics returns current bus metrics.
+func (b *StreamBus) GetMetrics() map[string]uint64 {
+	b.metricsLock.RLock()
+	defer b.metricsLock.RUnlock()
+
+	return map[string]uint64{
+		"messages_in":  b.messagesIn,
+		"messages_out": b.messagesOut,
+		"errors":       b.errors,
+		"claimed":      b.claimed,
+	}
+}
+
+// readInbound is one worker of the pool: it handles one inbound message at
+// a time and acknowledges it once the handlers return. Entries a previous
+// run left pending are picked up by claimInbound.
+func (b *StreamBus) readInbound() {
+	defer b.wg.Done()
+	b.readGroup(b.inboundStream(), streamInboundGroup, "inbound", false)
+}
+
+// readOutbound delivers the replies routed to this instance, starting with
+// any left unacknowledged by a previous run.
+func (b *StreamBus) readOutbound() {
+	defer b.wg.Done()
+	b.readGroup(b.outboundStream(b.instance), streamOutboundGroup, "outbound", true)
+}
+
+func (b *StreamBus) readGroup(stream, group, direction string, replay bool) {
+	// "0" replays this consumer's pending entries; ">" reads new ones.
+	id := ">"
+	if replay {
+		id = "0"
+	}
+	for b.ctx.Err() == nil {
+		streams, err := b.client.XReadGroup(b.ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: b.instance,
+			Streams:  []string{stream, id},
+			Count:    1,
+			Block:    streamBlock,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || b.ctx.Err() != nil {
+				continue
+			}
+			b.incrementErrors()
+			b.log.Warn("Failed to read Redis stream", zap.String("stream", stream), zap.Error(err))
+			b.sleep(time.Second)
+			continue
+		}
+		entries := 0
+		for _, s := range streams {
+			for _, entry := range s.Messages {
+				entries++
+				b.handleEntry(stream, group, direction, entry)
+			}
+		}
+		if id == "0" && entries == 0 {
+			id = ">"
+		}
+	}
+}
+
+// claimInbound takes over inbound messages another consumer left pending
+// for longer than the claim idle time.
+func (b *StreamBus) claimInbound() {
+	defer b.wg.Done()
+	interval := b.claimIdle / 2
+	if interval > time.Minute || interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		b.claimPending()
+	}
+}
+
+func (b *StreamBus) claimPending() {
+	start := "0-0"
+	for b.ctx.Err() == nil {
+		entries, next, err := b.client.XAutoClaim(b.ctx, &redis.XAutoClaimArgs{
+			Stream:   b.inboundStream(),
+			Group:    streamInboundGroup,
+			Consumer: b.instance,
+			MinIdle:  b.claimIdle,
+			Start:    start,
+			Count:    10,
+		}).Result()
+		if err != nil {
+			if b.ctx.Err() == nil {
+				b.log.Warn("Failed to claim pending inbound messages", zap.Error(err))
+			}
+			return
+		}
+		for _, entry := range entries {
+			b.inflightMu.Lock()
+			_, busy := b.inflight[entry.ID]
+			b.inflightMu.Unlock()
+			if busy {
+				continue
+			}
+			b.incrementClaimed()
+			b.handleEntry(b.inboundStream(), streamInboundGroup, "inbound", entry)
+		}
+		if next == "0-0" || len(entries) == 0 {
+			return
+		}
+		start = next
+	}
+}
+
+func (b *StreamBus) handleEntry(stream, group, direction string, entry redis.XMessage) {
+	if direction == "inbound" {
+		b.inflightMu.Lock()
+		b.inflight[entry.ID] = struct{}{}
+		b.inflightMu.Unlock()
+		defer func() {
+			b.inflightMu.Lock()
+			delete(b.inflight, entry.ID)
+			b.inflightMu.Unlock()
+		}()
+	}
+	defer func() {
+		// Leave a message cut short by shutdown pending for redelivery.
+		if b.ctx.Err() != nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if err := b.client.XAck(ctx, stream, group, entry.ID).Err(); err != nil {
+			b.log.Warn("Failed to acknowledge stream entry",
+				zap.String("stream", stream), zap.String("id", entry.ID), zap.Error(err))
+		}
+	}()
+
+	payload, _ := entry.Values["msg"].(string)
+	var msg Message
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		b.log.Error("Failed to unmarshal message", zap.String("id", entry.ID), zap.Error(err))
+		b.incrementErrors()
+		return
+	}
+
+	b.mu.RLock()
+	var handlers []Handler
+	if direction == "inbound" {
+		handlers = append([]Handler(nil), b.inboundHandlers[msg.ChannelID]...)
+	} else {
+		handlers = append([]Handler(nil), b.outboundHandlers[msg.ChannelID]...)
+	}
+	b.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		b.incrementErrors()
+		b.log.Warn("No handlers registered for channel",
+			zap.String("channel", msg.ChannelID),
+			zap.String("direction", direction),
+			zap.String("message_id", msg.ID))
+		return
+	}
+
+	for _, handler := range handlers {
+		if err := handler(b.ctx, &msg); err != nil {
+			b.incrementErrors()
+			b.log.Error("Handler error",
+				zap.String("channel", msg.ChannelID),
+				zap.String("message_id", msg.ID),
+				zap.Error(err))
+		}
+	}
+}
+
+func (b *StreamBus) sleep(d time.Duration) {
+	select {
+	case <-b.ctx.Done():
+	case <-time.After(d):
+	}
+}
+
+func (b *StreamBus) incrementMessagesIn() {
+	b.metricsLock.Lock()
+	b.messagesIn++
+	b.metricsLock.Unlock()
+}
+
+func (b *StreamBus) incrementMessagesOut() {
+	b.metricsLock.Lock()
+	b.messagesOut++
+	b.metricsLock.Unlock()
+}
+
+func (b *StreamBus) incrementErrors() {
+	b.metricsLock.Lock()
+	b.errors++
+	b.metricsLock.Unlock()
+}
+
+func (b *StreamBus) incrementClaimed() {
+	b.metricsLock.Lock()
+	b.claimed++
+	b.metricsLock.Unlock()
+}
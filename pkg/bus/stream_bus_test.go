@@ -0,0 +1,216 @@
+package bus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"nekobot/pkg/logger"
+)
+
+func newTestStreamBus(t *testing.T, srv *miniredis.Miniredis, instance string, role Role, consume bool) *StreamBus {
+	t.Helper()
+	log, err := logger.New(&logger.Config{Level: "error", OutputPath: ""})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	b, err := NewStreamBus(log, &StreamBusConfig{
+		Addr:           srv.Addr(),
+		InstanceID:     instance,
+		Role:           role,
+		ConsumeInbound: consume,
+		Workers:        2,
+		ClaimIdle:      100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("new stream bus: %v", err)
+	}
+	return b
+}
+
+func startTestStreamBus(t *testing.T, b *StreamBus) {
+	t.Helper()
+	if err := b.Start(); err != nil {
+		t.Fatalf("start stream bus: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = b.Stop()
+	})
+}
+
+func TestStreamBusRoutesRepliesToOriginGateway(t *testing.T) {
+	srv := miniredis.RunT(t)
+
+	replies := map[string]chan *Message{
+		"gw-a": make(chan *Message, 8),
+		"gw-b": make(chan *Message, 8),
+	}
+	gateways := map[string]*StreamBus{}
+	for _, instance := range []string{"gw-a", "gw-b"} {
+		gw := newTestStreamBus(t, srv, instance, RoleGateway, false)
+		out := replies[instance]
+		gw.RegisterOutboundHandler("slack:team", func(ctx context.Context, msg *Message) error {
+			out <- msg
+			return nil
+		})
+		startTestStreamBus(t, gw)
+		gateways[instance] = gw
+	}
+
+	var mu sync.Mutex
+	handled := map[string][]string{}
+	for _, instance := range []string{"worker-1", "worker-2"} {
+		worker := newTestStreamBus(t, srv, instance, RoleWorker, true)
+		name := instance
+		worker.RegisterInboundHandler("slack:team", func(ctx context.Context, msg *Message) error {
+			mu.Lock()
+			handled[msg.ID] = append(handled[msg.ID], name)
+			mu.Unlock()
+			return worker.SendOutbound(&Message{
+				ID:        msg.ID + "-reply",
+				ChannelID: msg.ChannelID,
+				Content:   "re: " + msg.Content,
+				Data:      msg.Data,
+			})
+		})
+		startTestStreamBus(t, worker)
+	}
+
+	for _, id := range []string{"m1", "m2", "m3", "m4"} {
+		if err := gateways["gw-b"].SendInbound(&Message{ID: id, ChannelID: "slack:team", Content: id}); err != nil {
+			t.Fatalf("SendInbound failed: %v", err)
+		}
+	}
+	for i := 0; i < 4; i++ {
+		select {
+		case msg := <-replies["gw-b"]:
+			if msg.Data[DataKeyBusOrigin] != "gw-b" {
+				t.Fatalf("expected origin gw-b, got %v", msg.Data[DataKeyBusOrigin])
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for reply %d", i+1)
+		}
+	}
+	select {
+	case msg := <-replies["gw-a"]:
+		t.Fatalf("reply %s went to the wrong gateway", msg.ID)
+	default:
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 4 {
+		t.Fatalf("expected 4 handled messages, got %v", handled)
+	}
+	for id, workers := range handled {
+		if len(workers) != 1 {
+			t.Fatalf("message %s handled by %v", id, workers)
+		}
+	}
+}
+
+func TestStreamBusRoutesUnstampedRepliesToChannelOwner(t *testing.T) {
+	srv := miniredis.RunT(t)
+
+	gw := newTestStreamBus(t, srv, "gw-a", RoleGateway, false)
+	delivered := make(chan *Message, 1)
+	gw.RegisterOutboundHandler("telegram:bot", func(ctx context.Context, msg *Message) error {
+		delivered <- msg
+		return nil
+	})
+	startTestStreamBus(t, gw)
+
+	// A process without a role, like "nekobot agent", only publishes.
+	cli := newTestStreamBus(t, srv, "", "", false)
+	cli.RegisterOutboundHandler("events", func(ctx context.Context, msg *Message) error { return nil })
+	startTestStreamBus(t, cli)
+	if owner := srv.HGet("nekobot:bus:routes", "events"); owner != "" {
+		t.Fatalf("expected no route for a publish-only process, got %q", owner)
+	}
+
+	if err := cli.SendOutbound(&Message{ID: "cron-1", ChannelID: "telegram:bot", Content: "reminder"}); err != nil {
+		t.Fatalf("SendOutbound failed: %v", err)
+	}
+	select {
+	case msg := <-delivered:
+		if msg.Content != "reminder" {
+			t.Fatalf("unexpected message %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for delivery")
+	}
+	if err := cli.SendOutbound(&Message{ID: "cron-2", ChannelID: "discord:guild"}); err == nil {
+		t.Fatal("expected a channel without owner to fail")
+	}
+
+	if err := cli.SendInbound(&Message{ID: "in-1", ChannelID: "telegram:bot"}); err != nil {
+		t.Fatalf("SendInbound failed: %v", err)
+	}
+	if entries, err := srv.Stream("nekobot:bus:stream:inbound"); err != nil || len(entries) != 1 {
+		t.Fatalf("expected 1 queued inbound message, got %d (%v)", len(entries), err)
+	}
+}
+
+func TestStreamBusClaimsMessagesLeftByDeadWorker(t *testing.T) {
+	srv := miniredis.RunT(t)
+	ctx := context.Background()
+
+	gw := newTestStreamBus(t, srv, "gw-a", "", false)
+	startTestStreamBus(t, gw)
+	if err := gw.SendInbound(&Message{ID: "stuck", ChannelID: "slack:team", Content: "hello"}); err != nil {
+		t.Fatalf("SendInbound failed: %v", err)
+	}
+
+	// A worker reads the message and dies before acknowledging it.
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	stream := "nekobot:bus:stream:inbound"
+	if err := client.XGroupCreateMkStream(ctx, stream, streamInboundGroup, "0").Err(); err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	if err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group: streamInboundGroup, Consumer: "dead", Streams: []string{stream, ">"}, Count: 1,
+	}).Err(); err != nil {
+		t.Fatalf("read group: %v", err)
+	}
+
+	worker := newTestStreamBus(t, srv, "worker-1", RoleWorker, true)
+	handled := make(chan *Message, 1)
+	worker.RegisterInboundHandler("slack:team", func(ctx context.Context, msg *Message) error {
+		handled <- msg
+		return nil
+	})
+	startTestStreamBus(t, worker)
+
+	select {
+	case msg := <-handled:
+		if msg.ID != "stuck" {
+			t.Fatalf("unexpected message %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the claimed message")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		pending, err := client.XPending(ctx, stream, streamInboundGroup).Result()
+		if err != nil {
+			t.Fatalf("pending: %v", err)
+		}
+		if pending.Count == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the claimed message to be acknowledged, %d pending", pending.Count)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := worker.GetMetrics()["claimed"]; got != 1 {
+		t.Fatalf("expected 1 claimed message, got %d", got)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"nekobot/pkg/config"
 	"nekobot/pkg/logger"
 	"nekobot/pkg/ownership"
+	"nekobot/pkg/secrets"
 	"nekobot/pkg/storage/ent"
 	"nekobot/pkg/storage/ent/channelaccount"
 )
@@ -70,7 +71,7 @@ func (m *Manager) List(ctx context.Context) ([]ChannelAccount, error) {
 	}
 	result := make([]ChannelAccount, 0, len(recs))
 	for _, rec := range recs {
-		item, err := toAccount(rec)
+		item, err := m.toAccount(rec)
 		if err != nil {
 			return nil, err
 		}
@@ -92,7 +93,7 @@ func (m *Manager) Get(ctx context.Context, id string) (*ChannelAccount, error) {
 		}
 		return nil, fmt.Errorf("get channel account %s: %w", id, err)
 	}
-	item, err := toAccount(rec)
+	item, err := m.toAccount(rec)
 	if err != nil {
 		return nil, err
 	}
@@ -131,7 +132,7 @@ func (m *Manager) FindByChannelTypeAndAccountKey(
 		return nil, fmt.Errorf("find channel account %s/%s: %w", channelType, accountKey, err)
 	}
 
-	item, err := toAccount(rec)
+	item, err := m.toAccount(rec)
 	if err != nil {
 		return nil, err
 	}
@@ -214,7 +215,7 @@ func (m *Manager) Create(ctx context.Context, item ChannelAccount) (*ChannelAcco
 			normalized.OwnerUserID, normalized.TenantID, normalized.Visibility,
 		)
 	}
-	configJSON, err := marshalMap(normalized.Config)
+	configJSON, err := m.sealConfig(normalized.Config)
 	if err != nil {
 		return nil, err
 	}
@@ -240,7 +241,7 @@ func (m *Manager) Create(ctx context.Context, item ChannelAccount) (*ChannelAcco
 		}
 		return nil, fmt.Errorf("create channel account: %w", err)
 	}
-	out, err := toAccount(rec)
+	out, err := m.toAccount(rec)
 	if err != nil {
 		return nil, err
 	}
@@ -267,7 +268,7 @@ func (m *Manager) Update(ctx context.Context, id string, item ChannelAccount) (*
 			}
 			return nil, fmt.Errorf("get channel account for auth: %w", lookupErr)
 		}
-		if !ac.CanWrite(existing.OwnerUserID) {
+		if !ac.CanWrite(existing.OwnerUserID, existing.TenantID) {
 			return nil, ownership.ErrPermissionDenied
 		}
 		normalized.OwnerUserID, normalized.TenantID, normalized.Visibility = ac.ValidateUpdateOwnership(
@@ -275,7 +276,7 @@ func (m *Manager) Update(ctx context.Context, id string, item ChannelAccount) (*
 			normalized.OwnerUserID, normalized.TenantID, normalized.Visibility,
 		)
 	}
-	configJSON, err := marshalMap(normalized.Config)
+	configJSON, err := m.sealConfig(normalized.Config)
 	if err != nil {
 		return nil, err
 	}
@@ -304,7 +305,7 @@ func (m *Manager) Update(ctx context.Context, id string, item ChannelAccount) (*
 		}
 		return nil, fmt.Errorf("update channel account %s: %w", id, err)
 	}
-	out, err := toAccount(rec)
+	out, err := m.toAccount(rec)
 	if err != nil {
 		return nil, err
 	}
@@ -326,7 +327,7 @@ func (m *Manager) Delete(ctx context.Context, id string) error {
 			}
 			return fmt.Errorf("get channel account for auth: %w", lookupErr)
 		}
-		if !ac.CanWrite(existing.OwnerUserID) {
+		if !ac.CanWrite(existing.OwnerUserID, existing.TenantID) {
 			return ownership.ErrPermissionDenied
 		}
 	}
@@ -388,8 +389,85 @@ func unmarshalMap(raw string) (map[string]interface{}, error) {
 	return values, nil
 }
 
-func toAccount(rec *ent.ChannelAccount) (ChannelAccount, error) {
-	cfgMap, err := unmarshalMap(rec.ConfigJSON)
+// sealConfig encodes an account config, encrypting its secret fields when a
+// master key is configured.
+func (m *Manager) sealConfig(values map[string]interface{}) (string, error) {
+	configJSON, err := marshalMap(values)
+	if err != nil {
+		return "", err
+	}
+	box, err := m.cfg.SecretBox()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := box.EncryptJSON([]byte(configJSON))
+	if err != nil {
+		return "", fmt.Errorf("encrypt channel account config: %w", err)
+	}
+	return string(sealed), nil
+}
+
+// openConfig returns a stored account config with its secret fields
+// decrypted.
+func (m *Manager) openConfig(rec *ent.ChannelAccount) (string, error) {
+	if !strings.Contains(rec.ConfigJSON, secrets.Prefix) {
+		return rec.ConfigJSON, nil
+	}
+	box, err := m.cfg.SecretBox()
+	if err != nil {
+		return "", err
+	}
+	opened, err := box.DecryptJSON([]byte(rec.ConfigJSON))
+	if err != nil {
+		return "", fmt.Errorf("decrypt channel account config %s: %w", rec.ID, err)
+	}
+	return string(opened), nil
+}
+
+// EncryptSecrets re-saves account configs whose secret fields are still in
+// plaintext under the configured master key and returns how many accounts
+// changed.
+func (m *Manager) EncryptSecrets(ctx context.Context) (int, error) {
+	box, err := m.cfg.SecretBox()
+	if err != nil {
+		return 0, err
+	}
+	if box == nil {
+		return 0, fmt.Errorf("no master key configured; set %s", secrets.KeyEnv)
+	}
+
+	records, err := m.client.ChannelAccount.Query().All(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("query channel accounts: %w", err)
+	}
+	changed := 0
+	for _, rec := range records {
+		if strings.TrimSpace(rec.ConfigJSON) == "" {
+			continue
+		}
+		// Encrypted values are kept as they are, so only plaintext
+		// secrets change the document.
+		sealed, err := box.EncryptJSON([]byte(rec.ConfigJSON))
+		if err != nil {
+			return changed, fmt.Errorf("encrypt channel account %s: %w", rec.ID, err)
+		}
+		if string(sealed) == rec.ConfigJSON {
+			continue
+		}
+		if err := m.client.ChannelAccount.UpdateOneID(rec.ID).SetConfigJSON(string(sealed)).Exec(ctx); err != nil {
+			return changed, fmt.Errorf("encrypt channel account %s: %w", rec.ID, err)
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+func (m *Manager) toAccount(rec *ent.ChannelAccount) (ChannelAccount, error) {
+	configJSON, err := m.openConfig(rec)
+	if err != nil {
+		return ChannelAccount{}, err
+	}
+	cfgMap, err := unmarshalMap(configJSON)
 	if err != nil {
 		return ChannelAccount{}, fmt.Errorf("decode channel account config %s: %w", rec.ID, err)
 	}
@@ -3,11 +3,13 @@ package channelaccounts
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	channelwechat "nekobot/pkg/channels/wechat"
 	"nekobot/pkg/config"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/secrets"
 	wxtypes "nekobot/pkg/wechat/types"
 )
 
@@ -145,6 +147,72 @@ func TestResolveForChannelIDPrefersActiveWechatAccountForAlias(t *testing.T) {
 	}
 }
 
+func TestManagerEncryptsConfigSecretsAtRest(t *testing.T) {
+	ctx := context.Background()
+
+	// Stored before a master key existed.
+	t.Setenv(secrets.KeyEnv, "")
+	plainMgr := newTestManager(t)
+	legacy, err := plainMgr.Create(ctx, ChannelAccount{
+		ChannelType: "telegram",
+		AccountKey:  "legacy",
+		Config:      map[string]interface{}{"token": "123:legacy", "allow_from": []interface{}{"alice"}},
+	})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+
+	key, err := secrets.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(secrets.KeyEnv, key)
+	keyedCfg := config.DefaultConfig()
+	keyedCfg.Storage.DBDir = plainMgr.cfg.Storage.DBDir
+	mgr, err := NewManager(keyedCfg, plainMgr.log, plainMgr.client)
+	if err != nil {
+		t.Fatalf("new account manager: %v", err)
+	}
+	fresh, err := mgr.Create(ctx, ChannelAccount{
+		ChannelType: "slack",
+		AccountKey:  "fresh",
+		Config:      map[string]interface{}{"bot_token": "xoxb-fresh", "app_token": "xapp-fresh"},
+	})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if fresh.Config["bot_token"] != "xoxb-fresh" {
+		t.Fatalf("expected the created account to hold plaintext config, got %+v", fresh.Config)
+	}
+	changed, err := mgr.EncryptSecrets(ctx)
+	if err != nil || changed != 1 {
+		t.Fatalf("expected one legacy account to be encrypted, got %d, %v", changed, err)
+	}
+	if changed, err := mgr.EncryptSecrets(ctx); err != nil || changed != 0 {
+		t.Fatalf("expected encrypted accounts to stay unchanged, got %d, %v", changed, err)
+	}
+
+	records, err := mgr.client.ChannelAccount.Query().All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range records {
+		for _, secret := range []string{"123:legacy", "xoxb-fresh", "xapp-fresh"} {
+			if strings.Contains(rec.ConfigJSON, secret) {
+				t.Fatalf("expected %s config to be encrypted at rest, got %s", rec.AccountKey, rec.ConfigJSON)
+			}
+		}
+	}
+
+	got, err := mgr.Get(ctx, legacy.ID)
+	if err != nil {
+		t.Fatalf("get account: %v", err)
+	}
+	if got.Config["token"] != "123:legacy" || len(got.Config["allow_from"].([]interface{})) != 1 {
+		t.Fatalf("expected decrypted config, got %+v", got.Config)
+	}
+}
+
 func newTestManager(t *testing.T) *Manager {
 	t.Helper()
 	cfg := config.DefaultConfig()
@@ -278,6 +278,16 @@ func GetDefaultCapabilitiesForChannel(channelType string) ChannelCapabilities {
 			Media:          CapabilityScopeAll,
 			NativeCommands: CapabilityScopeOff,
 		}
+	case "matrix":
+		return ChannelCapabilities{
+			Reactions:      CapabilityScopeOff,
+			InlineButtons:  CapabilityScopeOff,
+			Threads:        CapabilityScopeOff,
+			Polls:          CapabilityScopeOff,
+			Streaming:      CapabilityScopeOff,
+			Media:          CapabilityScopeOff,
+			NativeCommands: CapabilityScopeAll,
+		}
 	default:
 		return DefaultCapabilities()
 	}
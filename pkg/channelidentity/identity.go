@@ -0,0 +1,111 @@
+// Package channelidentity caches the bot account a channel is connected as.
+package channelidentity
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTTL is how long a resolved identity is served before refreshing.
+	DefaultTTL = 10 * time.Minute
+	// DefaultRetryInterval is the minimum gap between failed lookups.
+	DefaultRetryInterval = 30 * time.Second
+)
+
+// Identity describes the bot account behind a channel.
+type Identity struct {
+	ID           string          `json:"id"`
+	Username     string          `json:"username,omitempty"`
+	DisplayName  string          `json:"display_name,omitempty"`
+	Capabilities map[string]bool `json:"capabilities,omitempty"`
+	FetchedAt    time.Time       `json:"fetched_at"`
+}
+
+// Fetcher resolves the identity from the upstream platform (e.g. Telegram getMe).
+type Fetcher func(ctx context.Context) (Identity, error)
+
+// Cache serves an identity until its TTL expires and rate-limits lookups:
+// concurrent callers share one fetch and failures are not retried before
+// the retry interval.
+type Cache struct {
+	fetch         Fetcher
+	ttl           time.Duration
+	retryInterval time.Duration
+	now           func() time.Time
+
+	mu          sync.Mutex
+	identity    Identity
+	cached      bool
+	lastErr     error
+	lastAttempt time.Time
+}
+
+// NewCache creates an identity cache; a ttl <= 0 uses DefaultTTL.
+func NewCache(fetch Fetcher, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Cache{
+		fetch:         fetch,
+		ttl:           ttl,
+		retryInterval: DefaultRetryInterval,
+		now:           time.Now,
+	}
+}
+
+// Get returns the cached identity, refreshing it once the TTL has passed.
+// A failed refresh keeps serving the previous identity when there is one.
+func (c *Cache) Get(ctx context.Context) (Identity, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if c.cached && now.Sub(c.identity.FetchedAt) < c.ttl {
+		return c.identity, nil
+	}
+	if c.lastErr != nil && now.Sub(c.lastAttempt) < c.retryInterval {
+		if c.cached {
+			return c.identity, nil
+		}
+		return Identity{}, c.lastErr
+	}
+
+	c.lastAttempt = now
+	identity, err := c.fetch(ctx)
+	if err != nil {
+		c.lastErr = err
+		if c.cached {
+			return c.identity, nil
+		}
+		return Identity{}, err
+	}
+	c.lastErr = nil
+	c.store(identity, now)
+	return c.identity, nil
+}
+
+// Set stores an identity obtained elsewhere, e.g. while connecting.
+func (c *Cache) Set(identity Identity) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastErr = nil
+	c.store(identity, c.now())
+}
+
+// Invalidate drops the cached identity so the next Get fetches again.
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.identity = Identity{}
+	c.cached = false
+	c.lastErr = nil
+	c.lastAttempt = time.Time{}
+}
+
+func (c *Cache) store(identity Identity, now time.Time) {
+	identity.FetchedAt = now
+	c.identity = identity
+	c.cached = true
+}
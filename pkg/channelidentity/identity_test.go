@@ -0,0 +1,114 @@
+package channelidentity
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestCache(ttl time.Duration, fetch Fetcher) (*Cache, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cache := NewCache(fetch, ttl)
+	cache.now = clock.Now
+	return cache, clock
+}
+
+func TestCacheServesIdentityUntilTTL(t *testing.T) {
+	calls := 0
+	cache, clock := newTestCache(time.Minute, func(context.Context) (Identity, error) {
+		calls++
+		return Identity{ID: "42", Username: "mybot"}, nil
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		identity, err := cache.Get(ctx)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if identity.Username != "mybot" {
+			t.Fatalf("unexpected identity %+v", identity)
+		}
+		clock.now = clock.now.Add(10 * time.Second)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single fetch within the TTL, got %d", calls)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	identity, err := cache.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get after TTL failed: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected a refresh after the TTL, got %d fetches", calls)
+	}
+	if !identity.FetchedAt.Equal(clock.now) {
+		t.Fatalf("expected refreshed timestamp %s, got %s", clock.now, identity.FetchedAt)
+	}
+}
+
+func TestCacheKeepsStaleIdentityAndRateLimitsFailures(t *testing.T) {
+	calls := 0
+	failing := false
+	cache, clock := newTestCache(time.Minute, func(context.Context) (Identity, error) {
+		calls++
+		if failing {
+			return Identity{}, errors.New("upstream down")
+		}
+		return Identity{ID: "42", Username: "mybot"}, nil
+	})
+	ctx := context.Background()
+
+	if _, err := cache.Get(ctx); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	failing = true
+	clock.now = clock.now.Add(2 * time.Minute)
+	identity, err := cache.Get(ctx)
+	if err != nil || identity.Username != "mybot" {
+		t.Fatalf("expected stale identity on refresh failure, got %+v (%v)", identity, err)
+	}
+	clock.now = clock.now.Add(time.Second)
+	if _, err := cache.Get(ctx); err != nil {
+		t.Fatalf("expected stale identity, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected failed refresh not to be retried immediately, got %d fetches", calls)
+	}
+
+	failing = false
+	clock.now = clock.now.Add(DefaultRetryInterval)
+	if _, err := cache.Get(ctx); err != nil {
+		t.Fatalf("Get after retry interval failed: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected a retry after the retry interval, got %d fetches", calls)
+	}
+}
+
+func TestCacheInvalidateForcesFetch(t *testing.T) {
+	calls := 0
+	cache, _ := newTestCache(time.Hour, func(context.Context) (Identity, error) {
+		calls++
+		return Identity{ID: "42"}, nil
+	})
+	cache.Set(Identity{ID: "41"})
+
+	identity, _ := cache.Get(context.Background())
+	if identity.ID != "41" || calls != 0 {
+		t.Fatalf("expected seeded identity without fetching, got %+v after %d fetches", identity, calls)
+	}
+
+	cache.Invalidate()
+	identity, _ = cache.Get(context.Background())
+	if identity.ID != "42" || calls != 1 {
+		t.Fatalf("expected fetch after invalidation, got %+v after %d fetches", identity, calls)
+	}
+}
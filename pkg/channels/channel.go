@@ -5,6 +5,7 @@ import (
 	"context"
 
 	"nekobot/pkg/bus"
+	"nekobot/pkg/channelidentity"
 )
 
 // Channel represents a communication channel (Telegram, Discord, etc).
@@ -40,6 +41,31 @@ type HealthChecker interface {
 	HealthCheck(ctx context.Context) error
 }
 
+// Identity describes the bot account a channel is connected as.
+type Identity = channelidentity.Identity
+
+// IdentityProvider optionally exposes the cached bot identity of a channel.
+type IdentityProvider interface {
+	// Identity returns the bot account, served from cache within its TTL.
+	Identity(ctx context.Context) (Identity, error)
+}
+
+// ReplyEditor optionally lets a channel rewrite the reply it already sent for
+// a user message, e.g. after the user edited that message.
+type ReplyEditor interface {
+	// EditReply replaces the bot reply to sourceMessageID with content.
+	EditReply(ctx context.Context, sessionID, sourceMessageID, content string) error
+}
+
+// AttachmentSender optionally lets a channel upload the attachments of an
+// outbound message, e.g. a chart or report the agent generated in its
+// workspace. Its SendMessage sends msg.Attachments the same way.
+type AttachmentSender interface {
+	// SendAttachments uploads attachments to the conversation identified by
+	// sessionID, with caption shown alongside the first one.
+	SendAttachments(ctx context.Context, sessionID, caption string, attachments []bus.Attachment) error
+}
+
 // ChannelConfig is the interface for channel-specific configuration.
 type ChannelConfig interface {
 	// IsEnabled returns whether the channel is enabled.
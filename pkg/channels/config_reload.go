@@ -0,0 +1,97 @@
+package channels
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/agent"
+	"nekobot/pkg/bus"
+	"nekobot/pkg/commands"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/process"
+	"nekobot/pkg/toolsessions"
+	"nekobot/pkg/userprefs"
+)
+
+// configReloader rebuilds config-driven channels whose settings changed in a
+// config reload. Channel types backed by channel accounts are left alone.
+type configReloader struct {
+	manager        *Manager
+	log            *logger.Logger
+	bus            bus.Bus
+	agent          *agent.Agent
+	cmdRegistry    *commands.Registry
+	cfg            *config.Config
+	prefsMgr       *userprefs.Manager
+	toolSessionMgr *toolsessions.Manager
+	processMgr     *process.Manager
+	skip           map[string]bool
+
+	mu       sync.Mutex
+	snapshot map[string]string
+}
+
+func (r *configReloader) channelConfigs() map[string]string {
+	out := make(map[string]string)
+	for name, value := range ListChannelConfigs(r.cfg) {
+		if r.skip[name] {
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		out[name] = string(data)
+	}
+	return out
+}
+
+// HandleConfigChanged applies a config.changed event to the channels.
+func (r *configReloader) HandleConfigChanged(sections []string) {
+	if !slices.Contains(sections, "channels") {
+		return
+	}
+	r.Apply()
+}
+
+// Apply restarts every channel whose config differs from the last snapshot
+// and stops channels that were disabled. It returns the channels it touched.
+func (r *configReloader) Apply() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := r.channelConfigs()
+	var touched []string
+	for _, name := range ChannelNames() {
+		current, ok := next[name]
+		if !ok || current == r.snapshot[name] {
+			continue
+		}
+		touched = append(touched, name)
+		if err := r.reloadChannel(name); err != nil {
+			r.log.Warn("Failed to apply channel config change",
+				zap.String("channel", name), zap.Error(err))
+		}
+	}
+	r.snapshot = next
+	return touched
+}
+
+func (r *configReloader) reloadChannel(name string) error {
+	enabled, err := IsChannelEnabled(name, r.cfg)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return r.manager.StopChannel(name)
+	}
+	channel, err := BuildChannel(name, r.log, r.bus, r.agent, r.cmdRegistry, r.prefsMgr, r.toolSessionMgr, r.processMgr, r.cfg)
+	if err != nil {
+		return err
+	}
+	return r.manager.ReloadChannel(channel)
+}
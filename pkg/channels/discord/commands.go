@@ -0,0 +1,296 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+
+	channelcapabilities "nekobot/pkg/channelcapabilities"
+	"nekobot/pkg/commands"
+)
+
+const (
+	// discordMaxApplicationCommands is the number of global slash commands
+	// an application may register.
+	discordMaxApplicationCommands = 100
+	// discordMaxChoices is the number of choices or completions Discord
+	// accepts per option.
+	discordMaxChoices = 25
+	// discordRawArgsOption carries the argument text of commands that do
+	// not declare parameters.
+	discordRawArgsOption = "args"
+)
+
+var discordCommandNamePattern = regexp.MustCompile(`^[-_a-z0-9]{1,32}$`)
+
+// syncApplicationCommands registers the command registry as Discord slash
+// commands, replacing any previously registered set.
+func (c *Channel) syncApplicationCommands() {
+	if c.session == nil || c.commands == nil || c.session.State == nil || c.session.State.User == nil {
+		return
+	}
+	if !c.supportsNativeCommands(channelcapabilities.CapabilityScopeDM) &&
+		!c.supportsNativeCommands(channelcapabilities.CapabilityScopeGroup) {
+		return
+	}
+	appCmds := applicationCommands(c.commands.List())
+	if len(appCmds) == 0 {
+		return
+	}
+	if _, err := c.session.ApplicationCommandBulkOverwrite(c.session.State.User.ID, "", appCmds); err != nil {
+		c.log.Warn("Failed to sync Discord slash commands", zap.Error(err))
+		return
+	}
+	c.log.Info("Synced Discord slash commands", zap.Int("count", len(appCmds)))
+}
+
+// applicationCommands renders registry commands as Discord slash commands.
+// Declared parameters become typed options; other commands take their
+// arguments as a single optional text option.
+func applicationCommands(cmds []*commands.Command) []*discordgo.ApplicationCommand {
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].Name < cmds[j].Name })
+	appCmds := make([]*discordgo.ApplicationCommand, 0, len(cmds))
+	for _, cmd := range cmds {
+		if !discordCommandNamePattern.MatchString(cmd.Name) {
+			continue
+		}
+		appCmd := &discordgo.ApplicationCommand{
+			Name:        cmd.Name,
+			Description: discordDescription(cmd.Description, cmd.Name),
+		}
+		if len(cmd.Params) == 0 {
+			appCmd.Options = []*discordgo.ApplicationCommandOption{{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        discordRawArgsOption,
+				Description: discordDescription(strings.TrimSpace(strings.TrimPrefix(cmd.UsageText(), "/"+cmd.Name)), "Arguments"),
+			}}
+		}
+		for _, p := range cmd.Params {
+			appCmd.Options = append(appCmd.Options, applicationCommandOption(p))
+		}
+		appCmds = append(appCmds, appCmd)
+		if len(appCmds) == discordMaxApplicationCommands {
+			break
+		}
+	}
+	return appCmds
+}
+
+func applicationCommandOption(p commands.Param) *discordgo.ApplicationCommandOption {
+	option := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        p.Name,
+		Description: discordDescription(p.Description, p.Name),
+		Required:    p.Required,
+	}
+	if p.Type == commands.ParamInt {
+		option.Type = discordgo.ApplicationCommandOptionInteger
+	}
+	switch {
+	case p.Type == commands.ParamEnum && len(p.Choices) <= discordMaxChoices:
+		for _, choice := range p.Choices {
+			option.Choices = append(option.Choices, &discordgo.ApplicationCommandOptionChoice{Name: choice, Value: choice})
+		}
+	case len(p.Choices) > 0:
+		// Free-form values, and enums with more values than Discord lists,
+		// are completed as the user types.
+		option.Autocomplete = true
+	}
+	return option
+}
+
+// discordDescription fits desc into Discord's 1-100 character limit.
+func discordDescription(desc, fallback string) string {
+	desc = strings.Join(strings.Fields(desc), " ")
+	if desc == "" {
+		desc = fallback
+	}
+	runes := []rune(desc)
+	if len(runes) > 100 {
+		desc = string(runes[:99]) + "…"
+	}
+	return desc
+}
+
+// handleApplicationCommand runs a slash command invoked from Discord's
+// command picker.
+func (c *Channel) handleApplicationCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if c.commands == nil {
+		return
+	}
+	data := i.ApplicationCommandData()
+	userID := c.interactionUserID(i)
+	if !c.isAllowed(userID) {
+		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "You are not allowed to use this bot.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+	cmd, exists := c.commands.Get(data.Name)
+	if !exists {
+		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: c.commands.UnknownCommandMessage(data.Name),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+
+	// Commands may take longer than the three seconds Discord waits for a
+	// response, so acknowledge first and fill in the reply afterwards.
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}); err != nil {
+		c.log.Error("Failed to acknowledge Discord slash command", zap.Error(err))
+		return
+	}
+
+	req := commands.CommandRequest{
+		Channel:  "discord",
+		ChatID:   i.ChannelID,
+		UserID:   userID,
+		Username: c.interactionUserName(i),
+		Command:  cmd.Name,
+		Metadata: map[string]string{
+			"interaction_id": i.ID,
+			"guild_id":       i.GuildID,
+			"session_id":     c.commandSessionID(context.Background(), i.ChannelID, i.GuildID, userID),
+		},
+	}
+	values := applicationCommandValues(data.Options)
+	if len(cmd.Params) > 0 {
+		// The registry validates the values and fills in Args.
+		req.Params = values
+	} else {
+		req.Args = strings.TrimSpace(values[discordRawArgsOption])
+	}
+
+	c.log.Info("Executing slash command",
+		zap.String("command", cmd.Name),
+		zap.String("user", req.Username))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := cmd.Handler(ctx, req)
+	content := resp.Content
+	if err != nil {
+		c.log.Error("Command execution failed", zap.String("command", cmd.Name), zap.Error(err))
+		content = "❌ Command failed: " + err.Error()
+	}
+	if strings.TrimSpace(content) == "" {
+		content = "✅ Done."
+	}
+
+	edit := &discordgo.WebhookEdit{}
+	var pending *pendingSkillInstall
+	if err == nil && resp.Interaction != nil && resp.Interaction.Type == commands.InteractionTypeSkillInstallConfirm {
+		if repo := strings.TrimSpace(resp.Interaction.Repo); repo != "" {
+			if message := strings.TrimSpace(resp.Interaction.Message); message != "" {
+				content = message
+			}
+			components := skillInstallComponents()
+			edit.Components = &components
+			commandName := cmd.Name
+			if custom := strings.TrimSpace(resp.Interaction.Command); custom != "" {
+				commandName = custom
+			}
+			pending = &pendingSkillInstall{
+				UserID:    userID,
+				ChannelID: i.ChannelID,
+				Command:   commandName,
+				Repo:      repo,
+				CreatedAt: time.Now(),
+			}
+		}
+	}
+	var pages []string
+	if pending != nil {
+		edit.Content = &content
+	} else {
+		pages = pagedEdit(edit, content)
+	}
+
+	msg, err := s.InteractionResponseEdit(i.Interaction, edit)
+	if err != nil {
+		c.log.Error("Failed to send slash command response", zap.Error(err))
+		return
+	}
+	if pending != nil && msg != nil {
+		c.setPendingSkillInstall(msg.ID, *pending)
+	}
+	if len(pages) > 0 && msg != nil {
+		c.trackPages(msg.ID, pages)
+	}
+}
+
+// applicationCommandValues flattens slash command options into values by
+// option name.
+func applicationCommandValues(options []*discordgo.ApplicationCommandInteractionDataOption) map[string]string {
+	values := make(map[string]string, len(options))
+	for _, option := range options {
+		switch option.Type {
+		case discordgo.ApplicationCommandOptionInteger:
+			values[option.Name] = strconv.FormatInt(option.IntValue(), 10)
+		case discordgo.ApplicationCommandOptionString:
+			values[option.Name] = option.StringValue()
+		default:
+			values[option.Name] = fmt.Sprint(option.Value)
+		}
+	}
+	return values
+}
+
+// handleAutocomplete suggests the declared choices of the focused option
+// that start with what the user typed so far.
+func (c *Channel) handleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if c.commands == nil {
+		return
+	}
+	data := i.ApplicationCommandData()
+	var focused *discordgo.ApplicationCommandInteractionDataOption
+	for _, option := range data.Options {
+		if option.Focused {
+			focused = option
+			break
+		}
+	}
+
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	if cmd, ok := c.commands.Get(data.Name); ok && focused != nil {
+		typed := strings.ToLower(strings.TrimSpace(fmt.Sprint(focused.Value)))
+		for _, p := range cmd.Params {
+			if p.Name != focused.Name {
+				continue
+			}
+			for _, choice := range p.Choices {
+				if strings.HasPrefix(strings.ToLower(choice), typed) {
+					choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: choice, Value: choice})
+				}
+				if len(choices) == discordMaxChoices {
+					break
+				}
+			}
+		}
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	}); err != nil {
+		c.log.Debug("Failed to send Discord autocomplete choices", zap.Error(err))
+	}
+}
@@ -2,6 +2,7 @@
 package discord
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -14,13 +15,16 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"go.uber.org/zap"
 
+	"nekobot/pkg/approval"
 	"nekobot/pkg/bus"
 	channelcapabilities "nekobot/pkg/channelcapabilities"
 	"nekobot/pkg/channeltrace"
 	"nekobot/pkg/commands"
 	"nekobot/pkg/config"
+	"nekobot/pkg/feedback"
 	"nekobot/pkg/logger"
 	"nekobot/pkg/transcription"
+	"nekobot/pkg/userprefs"
 )
 
 // Channel implements Discord channel.
@@ -39,8 +43,43 @@ type Channel struct {
 
 	pendingSkillMu       sync.Mutex
 	pendingSkillInstalls map[string]pendingSkillInstall
+
+	// feedback stores 👍/👎 reactions on replies; replies are only seeded
+	// with the reactions when it is set.
+	feedback   FeedbackRecorder
+	repliesMu  sync.Mutex
+	replies    map[string]trackedReply
+	replyOrder []string
+
+	// pages holds the pages of long replies for their pagination buttons.
+	pagesMu   sync.Mutex
+	pages     map[string][]string
+	pageOrder []string
+
+	// approvals decides tool approval prompts from their buttons.
+	approvals *approval.Manager
+
+	// links resolves the sessions of linked accounts for commands.
+	links *userprefs.Manager
 }
 
+// FeedbackRecorder stores reply ratings.
+type FeedbackRecorder interface {
+	Record(ctx context.Context, entry feedback.Entry) (feedback.Entry, error)
+}
+
+// trackedReply remembers an agent reply so reactions to it can be rated.
+type trackedReply struct {
+	SessionID string
+	Content   string
+}
+
+// discordMaxTrackedReplies bounds the reply index used for reaction feedback.
+const discordMaxTrackedReplies = 1024
+
+// feedbackReactions are seeded on every reply for one-click rating.
+var feedbackReactions = []string{"👍", "👎"}
+
 type pendingSkillInstall struct {
 	UserID    string
 	ChannelID string
@@ -91,9 +130,28 @@ func NewAccountChannel(
 		session:              session,
 		running:              false,
 		pendingSkillInstalls: map[string]pendingSkillInstall{},
+		replies:              map[string]trackedReply{},
+		pages:                map[string][]string{},
 	}, nil
 }
 
+// SetFeedback enables 👍/👎 reactions on replies, recorded through recorder.
+func (c *Channel) SetFeedback(recorder FeedbackRecorder) {
+	c.feedback = recorder
+}
+
+// SetApprovals enables approve and deny buttons on tool approval prompts,
+// decided through mgr.
+func (c *Channel) SetApprovals(mgr *approval.Manager) {
+	c.approvals = mgr
+}
+
+// SetIdentityLinks makes commands sent in DMs by a linked account apply to
+// the linked identity's session.
+func (c *Channel) SetIdentityLinks(links *userprefs.Manager) {
+	c.links = links
+}
+
 // ID returns the channel identifier.
 func (c *Channel) ID() string {
 	return c.id
@@ -121,11 +179,14 @@ func (c *Channel) Start(ctx context.Context) error {
 	// Register message handler
 	c.session.AddHandler(c.handleMessage)
 	c.session.AddHandler(c.handleInteraction)
+	c.session.AddHandler(c.handleReactionAdd)
 
 	// Set intents
 	c.session.Identify.Intents = discordgo.IntentsGuildMessages |
 		discordgo.IntentsDirectMessages |
-		discordgo.IntentsMessageContent
+		discordgo.IntentsMessageContent |
+		discordgo.IntentsGuildMessageReactions |
+		discordgo.IntentsDirectMessageReactions
 
 	// Open WebSocket connection
 	if err := c.session.Open(); err != nil {
@@ -143,6 +204,7 @@ func (c *Channel) Start(ctx context.Context) error {
 			zap.String("username", botUser.Username),
 			zap.String("user_id", botUser.ID))
 	}
+	c.syncApplicationCommands()
 
 	return nil
 }
@@ -187,28 +249,46 @@ func (c *Channel) handleMessage(s *discordgo.Session, m *discordgo.MessageCreate
 		c.handleCommand(s, m)
 		return
 	}
+	if m.GuildID != "" && c.mentionOnly() {
+		addressed, stripped := addressedToBot(s, m, content)
+		if !addressed {
+			c.log.Debug("Ignoring Discord guild message not addressed to the bot",
+				zap.String("channel_id", m.ChannelID))
+			return
+		}
+		content = stripped
+	}
 
-	if content == "" && c.transcriber != nil {
+	attachments := c.imageAttachments(m.Attachments)
+	if len(attachments) > 0 {
+		msgType = bus.MessageTypeImage
+	}
+	if content == "" && len(attachments) == 0 && c.transcriber != nil {
 		transcribed, ok := c.transcribeAttachmentAudio(m.Attachments)
 		if ok {
 			content = transcribed
 			msgType = bus.MessageTypeAudio
 		}
 	}
-	if content == "" {
+	if content == "" && len(attachments) == 0 {
 		return
 	}
 
 	// Create inbound message
 	msg := &bus.Message{
-		ID:        fmt.Sprintf("discord:%s", m.ID),
-		ChannelID: "discord",
-		SessionID: fmt.Sprintf("discord:%s", m.ChannelID),
-		UserID:    m.Author.ID,
-		Username:  m.Author.Username,
-		Type:      msgType,
-		Content:   content,
-		Timestamp: time.Now(),
+		ID:          fmt.Sprintf("discord:%s", m.ID),
+		ChannelID:   "discord",
+		SessionID:   c.messageSessionID(m),
+		UserID:      m.Author.ID,
+		Username:    m.Author.Username,
+		Type:        msgType,
+		Content:     content,
+		Attachments: attachments,
+		Data:        map[string]interface{}{"reply_to_message_id": m.ID},
+		Timestamp:   time.Now(),
+	}
+	if m.GuildID == "" {
+		msg.Data[bus.DataKeyDirectMessage] = true
 	}
 
 	// Send to bus
@@ -249,6 +329,9 @@ func (c *Channel) handleCommand(s *discordgo.Session, m *discordgo.MessageCreate
 		zap.String("command", cmdName),
 		zap.String("user", m.Author.Username))
 
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
 	// Create command request
 	req := commands.CommandRequest{
 		Channel:  "discord",
@@ -260,12 +343,11 @@ func (c *Channel) handleCommand(s *discordgo.Session, m *discordgo.MessageCreate
 		Metadata: map[string]string{
 			"message_id": m.ID,
 			"guild_id":   m.GuildID,
+			"session_id": c.commandSessionID(ctx, m.ChannelID, m.GuildID, m.Author.ID),
 		},
 	}
 
 	// Execute command
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
 
 	resp, err := cmd.Handler(ctx, req)
 	if err != nil {
@@ -288,16 +370,35 @@ func (c *Channel) handleCommand(s *discordgo.Session, m *discordgo.MessageCreate
 		return
 	}
 
-	if _, err := s.ChannelMessageSend(m.ChannelID, resp.Content); err != nil {
+	if _, err := c.sendText(m.ChannelID, resp.Content); err != nil {
 		c.log.Error("Failed to send command response", zap.Error(err))
 	}
 }
 
 func (c *Channel) handleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if i == nil || i.Type != discordgo.InteractionMessageComponent {
+	if i == nil {
+		return
+	}
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		c.handleApplicationCommand(s, i)
+		return
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		c.handleAutocomplete(s, i)
+		return
+	case discordgo.InteractionMessageComponent:
+	default:
 		return
 	}
 	data := i.MessageComponentData()
+	if strings.HasPrefix(data.CustomID, approval.CallbackPrefix) {
+		c.handleApprovalInteraction(s, i, data.CustomID)
+		return
+	}
+	if strings.HasPrefix(data.CustomID, discordPagePrefix) {
+		c.handlePageInteraction(s, i, data.CustomID)
+		return
+	}
 	if !strings.HasPrefix(data.CustomID, "skillinstall:") {
 		return
 	}
@@ -356,6 +457,80 @@ func (c *Channel) handleInteraction(s *discordgo.Session, i *discordgo.Interacti
 	}
 }
 
+// approvalComponents renders the approve and deny buttons of a tool
+// approval prompt.
+func approvalComponents(requestID string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Approve",
+					Style:    discordgo.SuccessButton,
+					CustomID: approval.CallbackData(approval.Approved, requestID),
+				},
+				discordgo.Button{
+					Label:    "Deny",
+					Style:    discordgo.DangerButton,
+					CustomID: approval.CallbackData(approval.Denied, requestID),
+				},
+			},
+		},
+	}
+}
+
+// handleApprovalInteraction applies an approve or deny press to the pending
+// tool approval and replaces the buttons with the outcome.
+func (c *Channel) handleApprovalInteraction(s *discordgo.Session, i *discordgo.InteractionCreate, customID string) {
+	ephemeral := func(content string) {
+		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: content,
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+	}
+	userID := c.interactionUserID(i)
+	if userID == "" || !c.isAllowed(userID) {
+		ephemeral("You are not allowed to decide approvals.")
+		return
+	}
+	decision, requestID, ok := approval.ParseCallback(customID)
+	if !ok || c.approvals == nil || i.Message == nil {
+		ephemeral("Approvals are unavailable.")
+		return
+	}
+	// Buttons only decide prompts asked in this chat; custom IDs are
+	// client-supplied, so a forged ID must not reach other requests.
+	sessionID := c.commandSessionID(context.Background(), i.ChannelID, i.GuildID, userID)
+	if !c.approvals.PromptedIn(requestID, "discord", sessionID) {
+		ephemeral("No approval prompt " + requestID + " is waiting in this chat.")
+		return
+	}
+
+	actor := c.ID() + ":" + userID
+	if name := c.interactionUserName(i); name != "" {
+		actor = c.ID() + ":" + name
+	}
+	var err error
+	if decision == approval.Approved {
+		err = c.approvals.ApproveAs(requestID, actor)
+	} else {
+		err = c.approvals.DenyAs(requestID, actor, "denied from Discord")
+	}
+	if err != nil {
+		ephemeral(err.Error())
+		return
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    i.Message.Content + "\n\n" + approval.DecisionText(decision, actor),
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
 func (c *Channel) sendSkillInstallConfirmation(s *discordgo.Session, m *discordgo.MessageCreate, cmdName string, resp commands.CommandResponse) error {
 	if resp.Interaction == nil {
 		return fmt.Errorf("missing interaction payload")
@@ -382,23 +557,8 @@ func (c *Channel) sendSkillInstallConfirmation(s *discordgo.Session, m *discordg
 	}
 
 	msg, err := s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
-		Content: content,
-		Components: []discordgo.MessageComponent{
-			discordgo.ActionsRow{
-				Components: []discordgo.MessageComponent{
-					discordgo.Button{
-						Label:    "Confirm Install",
-						Style:    discordgo.SuccessButton,
-						CustomID: "skillinstall:confirm",
-					},
-					discordgo.Button{
-						Label:    "Cancel",
-						Style:    discordgo.SecondaryButton,
-						CustomID: "skillinstall:cancel",
-					},
-				},
-			},
-		},
+		Content:    content,
+		Components: skillInstallComponents(),
 	})
 	if err != nil {
 		return err
@@ -414,6 +574,25 @@ func (c *Channel) sendSkillInstallConfirmation(s *discordgo.Session, m *discordg
 	return nil
 }
 
+func skillInstallComponents() []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Confirm Install",
+					Style:    discordgo.SuccessButton,
+					CustomID: "skillinstall:confirm",
+				},
+				discordgo.Button{
+					Label:    "Cancel",
+					Style:    discordgo.SecondaryButton,
+					CustomID: "skillinstall:cancel",
+				},
+			},
+		},
+	}
+}
+
 func (c *Channel) executeConfirmedSkillInstall(s *discordgo.Session, i *discordgo.InteractionCreate, pending pendingSkillInstall) string {
 	cmd, exists := c.commands.Get(pending.Command)
 	if !exists {
@@ -506,17 +685,36 @@ func (c *Channel) SendMessage(ctx context.Context, msg *bus.Message) error {
 		return fmt.Errorf("session not initialized")
 	}
 
-	// Extract channel ID from session ID (format: "discord:channel_id")
-	channelID := msg.SessionID
-	if len(channelID) > 8 && channelID[:8] == "discord:" {
-		channelID = channelID[8:]
+	// Extract channel ID from session ID (format: "discord:channel_id[:user_id]")
+	channelID := sessionChannelID(msg.SessionID)
+	if len(msg.Attachments) > 0 {
+		return c.SendAttachments(ctx, msg.SessionID, msg.Content, msg.Attachments)
+	}
+
+	if requestID, ok := msg.Data[approval.DataKeyRequestID].(string); ok && requestID != "" && c.approvals != nil {
+		if _, err := c.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Content:    msg.Content,
+			Components: approvalComponents(requestID),
+		}); err != nil {
+			return fmt.Errorf("sending discord approval prompt: %w", err)
+		}
+		return nil
 	}
 
 	// Send message
-	_, err := c.session.ChannelMessageSend(channelID, prependBusToolTrace(msg.Content, msg))
+	sent, err := c.sendText(channelID, prependBusToolTrace(msg.Content, msg))
 	if err != nil {
 		return fmt.Errorf("sending discord message: %w", err)
 	}
+	if _, isReply := msg.Data["reply_to_message_id"]; isReply && c.feedback != nil && sent != nil {
+		c.trackReply(sent.ID, trackedReply{SessionID: msg.SessionID, Content: msg.Content})
+		for _, emoji := range feedbackReactions {
+			if err := c.session.MessageReactionAdd(channelID, sent.ID, emoji); err != nil {
+				c.log.Debug("Failed to seed Discord feedback reaction", zap.Error(err))
+				break
+			}
+		}
+	}
 
 	c.log.Debug("Sent Discord message",
 		zap.String("channel_id", channelID),
@@ -525,6 +723,98 @@ func (c *Channel) SendMessage(ctx context.Context, msg *bus.Message) error {
 	return nil
 }
 
+// discordMaxFilesPerMessage is the number of files Discord accepts on one
+// message; more attachments are spread over several messages.
+const discordMaxFilesPerMessage = 10
+
+// SendAttachments uploads attachments to the channel, with caption as the
+// text of the first message.
+func (c *Channel) SendAttachments(ctx context.Context, sessionID, caption string, attachments []bus.Attachment) error {
+	if c.session == nil {
+		return fmt.Errorf("session not initialized")
+	}
+	channelID := sessionChannelID(sessionID)
+
+	for start := 0; start < len(attachments); start += discordMaxFilesPerMessage {
+		batch := attachments[start:min(start+discordMaxFilesPerMessage, len(attachments))]
+		send := &discordgo.MessageSend{Files: make([]*discordgo.File, 0, len(batch))}
+		if start == 0 {
+			send.Content = caption
+		}
+		for _, attachment := range batch {
+			data, err := attachment.ReadData()
+			if err != nil {
+				return err
+			}
+			if len(data) == 0 && attachment.URL != "" {
+				send.Content = strings.TrimSpace(send.Content + "\n" + attachment.URL)
+				continue
+			}
+			send.Files = append(send.Files, &discordgo.File{
+				Name:        attachment.FileName(),
+				ContentType: attachment.MIMEType,
+				Reader:      bytes.NewReader(data),
+			})
+		}
+		if _, err := c.session.ChannelMessageSendComplex(channelID, send, discordgo.WithContext(ctx)); err != nil {
+			return fmt.Errorf("sending discord attachments: %w", err)
+		}
+	}
+	return nil
+}
+
+// handleReactionAdd records 👍/👎 reactions on tracked replies as feedback.
+func (c *Channel) handleReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	if c.feedback == nil || r == nil || r.MessageReaction == nil {
+		return
+	}
+	if s != nil && s.State != nil && s.State.User != nil && r.UserID == s.State.User.ID {
+		return
+	}
+	rating, err := feedback.ParseRating(r.Emoji.Name)
+	if err != nil {
+		return
+	}
+	reply, ok := c.trackedReply(r.MessageID)
+	if !ok || !c.isAllowed(r.UserID) {
+		return
+	}
+	_, err = c.feedback.Record(context.Background(), feedback.Entry{
+		Channel:   c.ID(),
+		SessionID: reply.SessionID,
+		MessageID: "discord:" + r.MessageID,
+		UserID:    r.UserID,
+		Rating:    rating,
+		Response:  reply.Content,
+	})
+	if err != nil {
+		c.log.Warn("Failed to record Discord feedback", zap.Error(err))
+	}
+}
+
+func (c *Channel) trackReply(messageID string, reply trackedReply) {
+	c.repliesMu.Lock()
+	defer c.repliesMu.Unlock()
+	if c.replies == nil {
+		c.replies = map[string]trackedReply{}
+	}
+	if _, exists := c.replies[messageID]; !exists {
+		c.replyOrder = append(c.replyOrder, messageID)
+	}
+	c.replies[messageID] = reply
+	for len(c.replyOrder) > discordMaxTrackedReplies {
+		delete(c.replies, c.replyOrder[0])
+		c.replyOrder = c.replyOrder[1:]
+	}
+}
+
+func (c *Channel) trackedReply(messageID string) (trackedReply, bool) {
+	c.repliesMu.Lock()
+	defer c.repliesMu.Unlock()
+	reply, ok := c.replies[messageID]
+	return reply, ok
+}
+
 func prependBusToolTrace(content string, msg *bus.Message) string {
 	return channeltrace.PrependBusToolTrace(content, msg)
 }
@@ -544,6 +834,52 @@ func (c *Channel) isAllowed(userID string) bool {
 	return false
 }
 
+// discordMaxImages caps how many image attachments of one message are
+// passed to the agent.
+const discordMaxImages = 4
+
+// imageAttachments downloads the image attachments of a message, skipping
+// any over bus.MaxImageBytes.
+func (c *Channel) imageAttachments(attachments []*discordgo.MessageAttachment) []bus.Attachment {
+	var images []bus.Attachment
+	for _, att := range attachments {
+		if len(images) >= discordMaxImages {
+			break
+		}
+		if att == nil || att.URL == "" || att.Size > bus.MaxImageBytes ||
+			!strings.HasPrefix(strings.ToLower(att.ContentType), "image/") {
+			continue
+		}
+
+		req, err := http.NewRequest(http.MethodGet, att.URL, nil)
+		if err != nil {
+			continue
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			c.log.Warn("Failed to download Discord image", zap.Error(err))
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			_ = resp.Body.Close()
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, bus.MaxImageBytes+1))
+		_ = resp.Body.Close()
+		if err != nil || len(data) > bus.MaxImageBytes {
+			c.log.Warn("Failed reading Discord image", zap.String("file", att.Filename), zap.Error(err))
+			continue
+		}
+		images = append(images, bus.Attachment{
+			Type:     bus.MessageTypeImage,
+			Name:     att.Filename,
+			MIMEType: strings.ToLower(att.ContentType),
+			Data:     data,
+		})
+	}
+	return images
+}
+
 func (c *Channel) transcribeAttachmentAudio(attachments []*discordgo.MessageAttachment) (string, bool) {
 	for _, att := range attachments {
 		if att == nil || att.URL == "" {
@@ -569,7 +905,7 @@ func (c *Channel) transcribeAttachmentAudio(attachments []*discordgo.MessageAtta
 			_ = resp.Body.Close()
 			continue
 		}
-		data, err := io.ReadAll(io.LimitReader(resp.Body, 20*1024*1024))
+		data, err := io.ReadAll(io.LimitReader(resp.Body, transcription.MaxAudioBytes))
 		_ = resp.Body.Close()
 		if err != nil {
 			c.log.Warn("Failed reading Discord audio", zap.Error(err))
@@ -13,6 +13,9 @@ import (
 
 	"nekobot/pkg/bus"
 	channelcapabilities "nekobot/pkg/channelcapabilities"
+	"nekobot/pkg/commands"
+	"nekobot/pkg/config"
+	"nekobot/pkg/feedback"
 	"nekobot/pkg/logger"
 )
 
@@ -83,6 +86,89 @@ func TestSendMessagePrependsToolTraceFromBusMetadata(t *testing.T) {
 	}
 }
 
+type recordingFeedback struct {
+	entries []feedback.Entry
+}
+
+func (r *recordingFeedback) Record(ctx context.Context, entry feedback.Entry) (feedback.Entry, error) {
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+func TestReactionsOnRepliesAreRecordedAsFeedback(t *testing.T) {
+	var reactions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v9/channels/C123/messages":
+			_, _ = w.Write([]byte(`{"id":"m1","channel_id":"C123","content":"ok"}`))
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/v9/channels/C123/messages/m1/reactions/"):
+			reactions = append(reactions, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	discordgo.EndpointDiscord = server.URL + "/"
+	discordgo.EndpointAPI = discordgo.EndpointDiscord + "api/v" + discordgo.APIVersion + "/"
+	discordgo.EndpointChannels = discordgo.EndpointAPI + "channels/"
+
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("create discord session: %v", err)
+	}
+	session.Client = server.Client()
+
+	store := &recordingFeedback{}
+	channel := &Channel{
+		id:          "discord",
+		log:         newTestLogger(t),
+		channelType: "discord",
+		session:     session,
+	}
+	channel.SetFeedback(store)
+
+	// Notifications without a source message get no rating reactions.
+	if err := channel.SendMessage(context.Background(), &bus.Message{SessionID: "discord:C123", Content: "heads up"}); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if len(reactions) != 0 {
+		t.Fatalf("expected no reactions on a notification, got %v", reactions)
+	}
+
+	if err := channel.SendMessage(context.Background(), &bus.Message{
+		SessionID: "discord:C123",
+		Content:   "the answer",
+		Data:      map[string]interface{}{"reply_to_message_id": "u1"},
+	}); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if len(reactions) != 2 {
+		t.Fatalf("expected both rating reactions to be seeded, got %v", reactions)
+	}
+
+	channel.handleReactionAdd(session, &discordgo.MessageReactionAdd{MessageReaction: &discordgo.MessageReaction{
+		UserID: "U9", MessageID: "m1", ChannelID: "C123", Emoji: discordgo.Emoji{Name: "🎉"},
+	}})
+	channel.handleReactionAdd(session, &discordgo.MessageReactionAdd{MessageReaction: &discordgo.MessageReaction{
+		UserID: "U9", MessageID: "other", ChannelID: "C123", Emoji: discordgo.Emoji{Name: "👍"},
+	}})
+	channel.handleReactionAdd(session, &discordgo.MessageReactionAdd{MessageReaction: &discordgo.MessageReaction{
+		UserID: "U9", MessageID: "m1", ChannelID: "C123", Emoji: discordgo.Emoji{Name: "👍"},
+	}})
+
+	if len(store.entries) != 1 {
+		t.Fatalf("expected only the thumbs reaction on the tracked reply to count, got %+v", store.entries)
+	}
+	got := store.entries[0]
+	if got.MessageID != "discord:m1" || got.SessionID != "discord:C123" || got.UserID != "U9" ||
+		got.Rating != feedback.RatingUp || got.Response != "the answer" {
+		t.Fatalf("unexpected feedback entry: %+v", got)
+	}
+}
+
 func newTestLogger(t *testing.T) *logger.Logger {
 	t.Helper()
 	cfg := logger.DefaultConfig()
@@ -94,3 +180,380 @@ func newTestLogger(t *testing.T) *logger.Logger {
 	}
 	return log
 }
+
+func TestSendMessageUploadsAttachments(t *testing.T) {
+	var files []string
+	var content string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v9/channels/C123/messages" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("parse multipart form: %v", err)
+		}
+		var payload map[string]interface{}
+		if err := json.Unmarshal([]byte(r.FormValue("payload_json")), &payload); err != nil {
+			t.Fatalf("decode payload: %v", err)
+		}
+		content, _ = payload["content"].(string)
+		for _, headers := range r.MultipartForm.File {
+			for _, header := range headers {
+				files = append(files, header.Filename)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"m1","channel_id":"C123"}`))
+	}))
+	defer server.Close()
+
+	discordgo.EndpointDiscord = server.URL + "/"
+	discordgo.EndpointAPI = discordgo.EndpointDiscord + "api/v" + discordgo.APIVersion + "/"
+	discordgo.EndpointChannels = discordgo.EndpointAPI + "channels/"
+
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("create discord session: %v", err)
+	}
+	session.Client = server.Client()
+	channel := &Channel{log: newTestLogger(t), channelType: "discord", session: session}
+
+	err = channel.SendMessage(context.Background(), &bus.Message{
+		SessionID:   "discord:C123",
+		Content:     "your chart",
+		Attachments: []bus.Attachment{{Type: bus.MessageTypeImage, Name: "chart.png", MIMEType: "image/png", Data: []byte("png")}},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if content != "your chart" || len(files) != 1 || files[0] != "chart.png" {
+		t.Fatalf("expected the chart with its caption, got content=%q files=%v", content, files)
+	}
+}
+
+type recordingBus struct {
+	bus.Bus
+	inbound []*bus.Message
+}
+
+func (b *recordingBus) SendInbound(msg *bus.Message) error {
+	b.inbound = append(b.inbound, msg)
+	return nil
+}
+
+func TestGuildMentionModeAndPerMemberSessions(t *testing.T) {
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("create discord session: %v", err)
+	}
+	session.State.User = &discordgo.User{ID: "B1"}
+	inbound := &recordingBus{}
+	channel := &Channel{
+		log:         newTestLogger(t),
+		channelType: "discord",
+		config:      config.DiscordConfig{GroupMode: "mention", SessionScope: "user"},
+		bus:         inbound,
+		commands:    commands.NewRegistry(),
+	}
+
+	alice := &discordgo.User{ID: "U1", Username: "alice"}
+	channel.handleMessage(session, &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID: "m1", ChannelID: "C1", GuildID: "G1", Author: alice, Content: "lunch anyone?",
+	}})
+	channel.handleMessage(session, &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID: "m2", ChannelID: "C1", GuildID: "G1", Author: alice, Content: "<@B1> what time is it?",
+		Mentions: []*discordgo.User{{ID: "B1"}},
+	}})
+	channel.handleMessage(session, &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID: "m3", ChannelID: "T1", GuildID: "G1", Author: alice, Content: "and tomorrow?",
+		ReferencedMessage: &discordgo.Message{ID: "r1", Author: &discordgo.User{ID: "B1"}},
+	}})
+	channel.handleMessage(session, &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID: "m4", ChannelID: "D1", Author: alice, Content: "hello",
+	}})
+
+	if len(inbound.inbound) != 3 {
+		t.Fatalf("expected the mention, reply and DM to be routed, got %d messages", len(inbound.inbound))
+	}
+	for i, want := range []struct{ session, content string }{
+		{"discord:C1:U1", "what time is it?"},
+		{"discord:T1:U1", "and tomorrow?"},
+		{"discord:D1", "hello"},
+	} {
+		got := inbound.inbound[i]
+		if got.SessionID != want.session || got.Content != want.content {
+			t.Fatalf("message %d: expected %s %q, got %s %q", i, want.session, want.content, got.SessionID, got.Content)
+		}
+	}
+	if got := sessionChannelID("discord:C1:U1"); got != "C1" {
+		t.Fatalf("expected replies to go to C1, got %q", got)
+	}
+}
+
+func TestApplicationCommandsRenderParamsAndRunHandlers(t *testing.T) {
+	reg := commands.NewRegistry()
+	var got commands.CommandRequest
+	handler := func(ctx context.Context, req commands.CommandRequest) (commands.CommandResponse, error) {
+		got = req
+		return commands.CommandResponse{Content: "reminder set"}, nil
+	}
+	for _, cmd := range []*commands.Command{
+		{
+			Name:        "remind",
+			Description: "Set a reminder",
+			Params: []commands.Param{
+				{Name: "unit", Type: commands.ParamEnum, Required: true, Choices: []string{"min", "hour"}},
+				{Name: "count", Type: commands.ParamInt, Required: true},
+				{Name: "text", Choices: []string{"stretch", "water the plants"}},
+			},
+			Handler: handler,
+		},
+		{Name: "status", Description: "Show bot status", Usage: "/status [verbose]", Handler: handler},
+	} {
+		if err := reg.Register(cmd); err != nil {
+			t.Fatalf("register %s: %v", cmd.Name, err)
+		}
+	}
+
+	appCmds := applicationCommands(reg.List())
+	if len(appCmds) != 2 || appCmds[0].Name != "remind" || len(appCmds[0].Options) != 3 {
+		t.Fatalf("unexpected application commands: %+v", appCmds)
+	}
+	unit, count, text := appCmds[0].Options[0], appCmds[0].Options[1], appCmds[0].Options[2]
+	if !unit.Required || len(unit.Choices) != 2 || unit.Choices[1].Value != "hour" {
+		t.Fatalf("expected enum choices, got %+v", unit)
+	}
+	if count.Type != discordgo.ApplicationCommandOptionInteger || !count.Required {
+		t.Fatalf("expected required integer option, got %+v", count)
+	}
+	if text.Required || !text.Autocomplete || len(text.Choices) != 0 {
+		t.Fatalf("expected optional autocompleted text option, got %+v", text)
+	}
+	if raw := appCmds[1].Options; len(raw) != 1 || raw[0].Name != discordRawArgsOption || raw[0].Description != "[verbose]" {
+		t.Fatalf("expected raw args option for commands without params, got %+v", raw)
+	}
+
+	var callbacks []string
+	var edited map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v9/interactions/i1/tok/callback":
+			callbacks = append(callbacks, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v9/webhooks/A1/tok/messages/@original":
+			if err := json.Unmarshal(body, &edited); err != nil {
+				t.Errorf("decode edit: %v", err)
+			}
+			_, _ = w.Write([]byte(`{"id":"m1","channel_id":"C1"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	discordgo.EndpointDiscord = server.URL + "/"
+	discordgo.EndpointAPI = discordgo.EndpointDiscord + "api/v" + discordgo.APIVersion + "/"
+	discordgo.EndpointWebhooks = discordgo.EndpointAPI + "webhooks/"
+
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("create discord session: %v", err)
+	}
+	session.Client = server.Client()
+	channel := &Channel{log: newTestLogger(t), channelType: "discord", session: session, commands: reg}
+
+	channel.handleInteraction(session, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        "i1",
+		AppID:     "A1",
+		Token:     "tok",
+		Type:      discordgo.InteractionApplicationCommand,
+		ChannelID: "C1",
+		User:      &discordgo.User{ID: "U1", Username: "alice"},
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name: "remind",
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "unit", Type: discordgo.ApplicationCommandOptionString, Value: "HOUR"},
+				{Name: "count", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(2)},
+				{Name: "text", Type: discordgo.ApplicationCommandOptionString, Value: "water the plants"},
+			},
+		},
+	}})
+
+	if len(callbacks) != 1 || !strings.Contains(callbacks[0], `"type":5`) {
+		t.Fatalf("expected a deferred acknowledgement, got %v", callbacks)
+	}
+	if got.Params.String("unit") != "hour" || got.Params.Int("count") != 2 || got.Args != "hour 2 water the plants" || got.ChatID != "C1" {
+		t.Fatalf("unexpected command request: %+v", got)
+	}
+	if edited["content"] != "reminder set" {
+		t.Fatalf("expected reply to replace the acknowledgement, got %+v", edited)
+	}
+}
+
+func TestSplitDiscordTextPrefersLineBreaks(t *testing.T) {
+	text := strings.Repeat("é", 6) + "\n" + strings.Repeat("ü", 6)
+	chunks := splitDiscordText(text, 10)
+	if len(chunks) != 2 || chunks[0] != strings.Repeat("é", 6) || chunks[1] != strings.Repeat("ü", 6) {
+		t.Fatalf("expected the text to split at the line break, got %q", chunks)
+	}
+	if chunks := splitDiscordText(strings.Repeat("x", 25), 10); len(chunks) != 3 || len(chunks[2]) != 5 {
+		t.Fatalf("expected hard splits without line breaks, got %q", chunks)
+	}
+}
+
+func TestLongRepliesArePaginatedEmbeds(t *testing.T) {
+	var sent map[string]interface{}
+	var callbacks []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v9/channels/C123/messages":
+			if err := json.Unmarshal(body, &sent); err != nil {
+				t.Errorf("decode message: %v", err)
+			}
+			_, _ = w.Write([]byte(`{"id":"m1","channel_id":"C123"}`))
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/api/v9/interactions/"):
+			var callback map[string]interface{}
+			if err := json.Unmarshal(body, &callback); err != nil {
+				t.Errorf("decode callback: %v", err)
+			}
+			callbacks = append(callbacks, callback)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	discordgo.EndpointDiscord = server.URL + "/"
+	discordgo.EndpointAPI = discordgo.EndpointDiscord + "api/v" + discordgo.APIVersion + "/"
+	discordgo.EndpointChannels = discordgo.EndpointAPI + "channels/"
+
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("create discord session: %v", err)
+	}
+	session.Client = server.Client()
+	channel := &Channel{log: newTestLogger(t), channelType: "discord", session: session}
+
+	paragraph := strings.Repeat("word ", 700) + "\n"
+	answer := strings.Repeat(paragraph, 3)
+	if err := channel.SendMessage(context.Background(), &bus.Message{SessionID: "discord:C123", Content: answer}); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if content, _ := sent["content"].(string); content != "" {
+		t.Fatalf("expected the long reply in an embed, got content of %d characters", len(content))
+	}
+	embeds, _ := sent["embeds"].([]interface{})
+	if len(embeds) != 1 {
+		t.Fatalf("expected one embed, got %+v", sent["embeds"])
+	}
+	footer, _ := embeds[0].(map[string]interface{})["footer"].(map[string]interface{})
+	if footer["text"] != "Page 1/3" {
+		t.Fatalf("expected a page footer, got %+v", footer)
+	}
+	if components, _ := sent["components"].([]interface{}); len(components) != 1 {
+		t.Fatalf("expected a row of pagination buttons, got %+v", sent["components"])
+	}
+
+	press := func(customID string) map[string]interface{} {
+		callbacks = nil
+		channel.handleInteraction(session, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			ID:      "i1",
+			AppID:   "A1",
+			Token:   "tok",
+			Type:    discordgo.InteractionMessageComponent,
+			User:    &discordgo.User{ID: "U1"},
+			Message: &discordgo.Message{ID: "m1", ChannelID: "C123"},
+			Data:    discordgo.MessageComponentInteractionData{CustomID: customID},
+		}})
+		if len(callbacks) != 1 {
+			t.Fatalf("expected one interaction response, got %d", len(callbacks))
+		}
+		return callbacks[0]
+	}
+
+	update := press(discordPagePrefix + "2")
+	if update["type"] != float64(discordgo.InteractionResponseUpdateMessage) {
+		t.Fatalf("expected the message to be updated, got %+v", update)
+	}
+	data, _ := update["data"].(map[string]interface{})
+	embeds, _ = data["embeds"].([]interface{})
+	page, _ := embeds[0].(map[string]interface{})
+	if footer, _ := page["footer"].(map[string]interface{}); footer["text"] != "Page 3/3" {
+		t.Fatalf("expected the last page, got %+v", page)
+	}
+	if !strings.HasPrefix(page["description"].(string), "word") {
+		t.Fatalf("unexpected page content %q", page["description"])
+	}
+
+	if expired := press(discordPagePrefix + "7"); expired["type"] != float64(discordgo.InteractionResponseChannelMessageWithSource) {
+		t.Fatalf("expected an ephemeral notice for a missing page, got %+v", expired)
+	}
+}
+
+func TestPagedEditKeepsShortRepliesAsContent(t *testing.T) {
+	edit := &discordgo.WebhookEdit{}
+	if pages := pagedEdit(edit, "reminder set"); pages != nil || edit.Content == nil || *edit.Content != "reminder set" || edit.Embeds != nil {
+		t.Fatalf("expected short replies to stay plain content, got %+v", edit)
+	}
+
+	edit = &discordgo.WebhookEdit{}
+	pages := pagedEdit(edit, strings.Repeat("line\n", 1000))
+	if len(pages) != 2 || edit.Content != nil || edit.Embeds == nil || len(*edit.Embeds) != 1 || edit.Components == nil || len(*edit.Components) != 1 {
+		t.Fatalf("expected the first page as an embed with buttons, got %d pages and %+v", len(pages), edit)
+	}
+}
+
+func TestWhitespaceOnlyLongRepliesSendNothing(t *testing.T) {
+	content := strings.Repeat(" \n", 1500)
+	channel := &Channel{log: newTestLogger(t), channelType: "discord"}
+	if sent, err := channel.sendText("C123", content); sent != nil || err != nil {
+		t.Fatalf("expected nothing to be sent, got %+v, %v", sent, err)
+	}
+
+	edit := &discordgo.WebhookEdit{}
+	if pages := pagedEdit(edit, content); pages != nil || edit.Embeds != nil || edit.Content == nil || *edit.Content != "" {
+		t.Fatalf("expected an empty edit without embeds, got %d pages and %+v", len(pages), edit)
+	}
+}
+
+func TestSplitDiscordTextKeepsIndentationAtPageStarts(t *testing.T) {
+	text := "steps:\n" + strings.Repeat("    - run: make\n", 400)
+	pages := splitDiscordText(text, 1000)
+	if len(pages) < 2 {
+		t.Fatalf("expected several pages, got %d", len(pages))
+	}
+	for i, page := range pages[1:] {
+		if !strings.HasPrefix(page, "    - run") {
+			t.Fatalf("page %d lost its indentation: %q", i+2, page[:20])
+		}
+	}
+	if strings.Join(pages, "\n") != strings.TrimSpace(text) {
+		t.Fatal("expected the pages to add up to the reply")
+	}
+}
+
+func TestSplitDiscordTextReopensCodeBlocksAcrossPages(t *testing.T) {
+	text := "Here you go:\n```go\n" + strings.Repeat("\tx := 1\n", 400) + "```\nDone."
+	pages := splitDiscordText(text, 1000)
+	if len(pages) < 3 {
+		t.Fatalf("expected several pages, got %d", len(pages))
+	}
+	for i, page := range pages {
+		if strings.Count(page, "```")%2 != 0 {
+			t.Fatalf("page %d leaves a code block open: %q", i+1, page)
+		}
+		if i > 0 && !strings.HasPrefix(page, "```go\n\t") {
+			t.Fatalf("page %d does not reopen the code block: %q", i+1, page[:20])
+		}
+		if i < len(pages)-1 && !strings.HasSuffix(page, "\n```") {
+			t.Fatalf("page %d does not close the code block: %q", i+1, page[len(page)-20:])
+		}
+	}
+	if last := pages[len(pages)-1]; !strings.HasSuffix(last, "```\nDone.") {
+		t.Fatalf("unexpected last page ending %q", last[len(last)-20:])
+	}
+}
@@ -0,0 +1,74 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// messageSessionID returns the session of an inbound message. Threads have
+// their own channel IDs, so each thread already gets its own session; with
+// session_scope "user" guild sessions are further split per member as
+// "discord:<channel>:<user>".
+func (c *Channel) messageSessionID(m *discordgo.MessageCreate) string {
+	userID := ""
+	if m.Author != nil {
+		userID = m.Author.ID
+	}
+	return c.chatSessionID(m.ChannelID, m.GuildID, userID)
+}
+
+func (c *Channel) chatSessionID(channelID, guildID, userID string) string {
+	sessionID := fmt.Sprintf("discord:%s", channelID)
+	if guildID != "" && strings.TrimSpace(c.config.SessionScope) == "user" && userID != "" {
+		sessionID += ":" + userID
+	}
+	return sessionID
+}
+
+// commandSessionID returns the session a command applies to. DMs from a
+// linked account use the linked identity's session, as their messages do.
+func (c *Channel) commandSessionID(ctx context.Context, channelID, guildID, userID string) string {
+	sessionID := c.chatSessionID(channelID, guildID, userID)
+	if c.links == nil || guildID != "" {
+		return sessionID
+	}
+	return c.links.ResolveSessionID(ctx, "discord", userID, sessionID)
+}
+
+// sessionChannelID returns the channel a session replies to.
+func sessionChannelID(sessionID string) string {
+	channelID := strings.TrimPrefix(sessionID, "discord:")
+	if idx := strings.Index(channelID, ":"); idx >= 0 {
+		channelID = channelID[:idx]
+	}
+	return channelID
+}
+
+func (c *Channel) mentionOnly() bool {
+	return strings.TrimSpace(c.config.GroupMode) == "mention"
+}
+
+// addressedToBot reports whether a guild message @mentions the bot or
+// replies to one of its messages, and returns content without the mention.
+func addressedToBot(s *discordgo.Session, m *discordgo.MessageCreate, content string) (bool, string) {
+	if s == nil || s.State == nil || s.State.User == nil {
+		return false, content
+	}
+	botID := s.State.User.ID
+	mentioned := m.ReferencedMessage != nil && m.ReferencedMessage.Author != nil && m.ReferencedMessage.Author.ID == botID
+	for _, user := range m.Mentions {
+		if user != nil && user.ID == botID {
+			mentioned = true
+		}
+	}
+	if !mentioned {
+		return false, content
+	}
+	for _, mention := range []string{"<@" + botID + ">", "<@!" + botID + ">"} {
+		content = strings.ReplaceAll(content, mention, "")
+	}
+	return true, strings.TrimSpace(content)
+}
@@ -0,0 +1,238 @@
+package discord
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// discordMaxMessageLength is the number of characters Discord accepts in
+	// message content. Longer replies are sent as paginated embeds.
+	discordMaxMessageLength = 2000
+	// discordEmbedPageLength is the length of one page of a long reply; an
+	// embed description holds up to 4096 characters, which leaves room to
+	// close and reopen a code block split across pages.
+	discordEmbedPageLength = 4000
+	// discordMaxPagedReplies bounds the pages kept for pagination buttons.
+	discordMaxPagedReplies = 256
+	// discordPagePrefix starts the custom ID of pagination buttons, followed
+	// by the page they open.
+	discordPagePrefix = "page:"
+	// discordEmbedColor is Discord's blurple.
+	discordEmbedColor = 0x5865F2
+)
+
+// splitDiscordText breaks text into chunks of at most limit characters,
+// preferring to break at line ends. A code block split across chunks is
+// closed at the end of one and reopened at the start of the next.
+func splitDiscordText(text string, limit int) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return []string{text}
+	}
+
+	chunks := make([]string, 0, len(runes)/limit+1)
+	start := 0
+	for start < len(runes) {
+		end := start + limit
+		if end >= len(runes) {
+			if chunk := string(runes[start:]); strings.TrimSpace(chunk) != "" {
+				chunks = append(chunks, chunk)
+			}
+			break
+		}
+		splitAt, chunkEnd := end, end
+		for i := end; i > start+limit/2; i-- {
+			if runes[i-1] == '\n' {
+				// The line break itself is dropped with the split.
+				splitAt, chunkEnd = i, i-1
+				break
+			}
+		}
+		if chunk := string(runes[start:chunkEnd]); strings.TrimSpace(chunk) != "" {
+			chunks = append(chunks, chunk)
+		}
+		start = splitAt
+	}
+	return balanceCodeFences(chunks)
+}
+
+// balanceCodeFences closes a ``` block left open at the end of a chunk and
+// reopens it, with its language, at the start of the next chunk, so every
+// page renders on its own.
+func balanceCodeFences(chunks []string) []string {
+	open, lang := false, ""
+	for i, chunk := range chunks {
+		if open {
+			chunk = "```" + lang + "\n" + chunk
+		}
+		open = false
+		for _, line := range strings.Split(chunk, "\n") {
+			fence, ok := strings.CutPrefix(strings.TrimSpace(line), "```")
+			if !ok {
+				continue
+			}
+			if open {
+				open = false
+				continue
+			}
+			open, lang = true, strings.TrimSpace(fence)
+		}
+		if open {
+			if !strings.HasSuffix(chunk, "\n") {
+				chunk += "\n"
+			}
+			chunk += "```"
+		}
+		chunks[i] = chunk
+	}
+	return chunks
+}
+
+// pageEmbed renders one page of a long reply.
+func pageEmbed(pages []string, page int) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Description: pages[page],
+		Color:       discordEmbedColor,
+	}
+	if len(pages) > 1 {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Page %d/%d", page+1, len(pages))}
+	}
+	return embed
+}
+
+// pageComponents renders the buttons moving between the pages of a long
+// reply, or none for a single page.
+func pageComponents(total, page int) []discordgo.MessageComponent {
+	if total <= 1 {
+		return []discordgo.MessageComponent{}
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "◀ Previous",
+					Style:    discordgo.SecondaryButton,
+					CustomID: discordPagePrefix + strconv.Itoa(page-1),
+					Disabled: page == 0,
+				},
+				discordgo.Button{
+					Label:    "Next ▶",
+					Style:    discordgo.SecondaryButton,
+					CustomID: discordPagePrefix + strconv.Itoa(page+1),
+					Disabled: page == total-1,
+				},
+			},
+		},
+	}
+}
+
+// sendText sends content to a channel, as paginated embeds when it does
+// not fit in one message.
+func (c *Channel) sendText(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if utf8.RuneCountInString(content) <= discordMaxMessageLength {
+		return c.session.ChannelMessageSend(channelID, content, options...)
+	}
+	pages := splitDiscordText(content, discordEmbedPageLength)
+	if len(pages) == 0 {
+		return nil, nil
+	}
+	sent, err := c.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{pageEmbed(pages, 0)},
+		Components: pageComponents(len(pages), 0),
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) > 1 && sent != nil {
+		c.trackPages(sent.ID, pages)
+	}
+	return sent, nil
+}
+
+// pagedEdit fills edit with the first page of content when it does not fit
+// in one message, and returns the pages to track once the edit is sent.
+func pagedEdit(edit *discordgo.WebhookEdit, content string) []string {
+	if utf8.RuneCountInString(content) <= discordMaxMessageLength {
+		edit.Content = &content
+		return nil
+	}
+	pages := splitDiscordText(content, discordEmbedPageLength)
+	if len(pages) == 0 {
+		empty := ""
+		edit.Content = &empty
+		return nil
+	}
+	embeds := []*discordgo.MessageEmbed{pageEmbed(pages, 0)}
+	components := pageComponents(len(pages), 0)
+	edit.Embeds = &embeds
+	edit.Components = &components
+	if len(pages) == 1 {
+		return nil
+	}
+	return pages
+}
+
+// handlePageInteraction shows the page a pagination button points to.
+func (c *Channel) handlePageInteraction(s *discordgo.Session, i *discordgo.InteractionCreate, customID string) {
+	ephemeral := func(content string) {
+		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: content,
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+	}
+	if !c.isAllowed(c.interactionUserID(i)) {
+		ephemeral("You are not allowed to use this bot.")
+		return
+	}
+	if i.Message == nil {
+		return
+	}
+	pages, ok := c.trackedPages(i.Message.ID)
+	page, err := strconv.Atoi(strings.TrimPrefix(customID, discordPagePrefix))
+	if !ok || err != nil || page < 0 || page >= len(pages) {
+		ephemeral("These pages have expired.")
+		return
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{pageEmbed(pages, page)},
+			Components: pageComponents(len(pages), page),
+		},
+	})
+}
+
+func (c *Channel) trackPages(messageID string, pages []string) {
+	c.pagesMu.Lock()
+	defer c.pagesMu.Unlock()
+	if c.pages == nil {
+		c.pages = map[string][]string{}
+	}
+	if _, exists := c.pages[messageID]; !exists {
+		c.pageOrder = append(c.pageOrder, messageID)
+	}
+	c.pages[messageID] = pages
+	for len(c.pageOrder) > discordMaxPagedReplies {
+		delete(c.pages, c.pageOrder[0])
+		c.pageOrder = c.pageOrder[1:]
+	}
+}
+
+func (c *Channel) trackedPages(messageID string) ([]string, bool) {
+	c.pagesMu.Lock()
+	defer c.pagesMu.Unlock()
+	pages, ok := c.pages[messageID]
+	return pages, ok
+}
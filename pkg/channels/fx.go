@@ -121,5 +121,20 @@ func RegisterChannels(
 		}
 	}
 
+	reloader := &configReloader{
+		manager:        manager,
+		log:            log,
+		bus:            messageBus,
+		agent:          ag,
+		cmdRegistry:    cmdRegistry,
+		cfg:            cfg,
+		prefsMgr:       prefsMgr,
+		toolSessionMgr: toolSessionMgr,
+		processMgr:     processMgr,
+		skip:           accountedTypes,
+	}
+	reloader.snapshot = reloader.channelConfigs()
+	bus.SubscribeConfigChanges(messageBus, reloader.HandleConfigChanged)
+
 	return nil
 }
@@ -148,3 +148,44 @@ func newFXTestEntClient(t *testing.T, cfg *config.Config) *ent.Client {
 	}
 	return client
 }
+
+func TestConfigReloaderRestartsChangedChannels(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Channels.Gotify.Enabled = true
+	cfg.Channels.Gotify.ServerURL = "https://gotify.example.com"
+	cfg.Channels.Gotify.AppToken = "token-a"
+
+	log := newFXTestLogger(t)
+	manager := NewManager(log, nil)
+	if err := RegisterChannels(manager, log, nil, nil, nil, cfg, nil, nil, nil, nil); err != nil {
+		t.Fatalf("RegisterChannels failed: %v", err)
+	}
+	before, err := manager.GetChannel("gotify")
+	if err != nil {
+		t.Fatalf("GetChannel failed: %v", err)
+	}
+
+	reloader := &configReloader{manager: manager, log: log, cfg: cfg}
+	reloader.snapshot = reloader.channelConfigs()
+	if touched := reloader.Apply(); len(touched) != 0 {
+		t.Fatalf("expected no changes, got %v", touched)
+	}
+
+	cfg.Channels.Gotify.AppToken = "token-b"
+	if touched := reloader.Apply(); len(touched) != 1 || touched[0] != "gotify" {
+		t.Fatalf("expected gotify to be reloaded, got %v", touched)
+	}
+	after, err := manager.GetChannel("gotify")
+	if err != nil {
+		t.Fatalf("GetChannel after reload failed: %v", err)
+	}
+	if after == before {
+		t.Fatal("expected a rebuilt gotify channel")
+	}
+
+	cfg.Channels.Gotify.Enabled = false
+	reloader.Apply()
+	if _, err := manager.GetChannel("gotify"); err == nil {
+		t.Fatal("expected disabled gotify channel to be stopped")
+	}
+}
@@ -3,6 +3,7 @@ package channels
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -107,7 +108,7 @@ func (m *Manager) Start() error {
 		// Register message handler for this channel
 		if m.bus != nil {
 			m.bus.RegisterOutboundHandler(channel.ID(), func(ctx context.Context, msg *bus.Message) error {
-				return channel.SendMessage(ctx, msg)
+				return deliverOutbound(ctx, channel, msg)
 			})
 		}
 
@@ -256,7 +257,7 @@ func (m *Manager) ReloadChannel(channel Channel) error {
 
 	if m.bus != nil {
 		m.bus.RegisterOutboundHandler(channel.ID(), func(ctx context.Context, msg *bus.Message) error {
-			return channel.SendMessage(ctx, msg)
+			return deliverOutbound(ctx, channel, msg)
 		})
 	}
 
@@ -378,6 +379,39 @@ func (m *Manager) removeTypeIndexLocked(channelType, channelID string) {
 	}
 }
 
+// deliverOutbound hands an outbound bus message to channel. Attachments are
+// uploaded through AttachmentSender; channels without it get a text note
+// naming the files instead.
+func deliverOutbound(ctx context.Context, channel Channel, msg *bus.Message) error {
+	if msg == nil || len(msg.Attachments) == 0 {
+		if msg != nil && msg.Type == bus.MessageTypeFile {
+			return fmt.Errorf("file message for channel %s has no attachments", channel.ID())
+		}
+		return channel.SendMessage(ctx, msg)
+	}
+	if sender, ok := channel.(AttachmentSender); ok {
+		return sender.SendAttachments(ctx, msg.SessionID, msg.Content, msg.Attachments)
+	}
+
+	locations := make([]string, 0, len(msg.Attachments))
+	for _, attachment := range msg.Attachments {
+		switch {
+		case attachment.Path != "":
+			locations = append(locations, attachment.Path)
+		case attachment.URL != "":
+			locations = append(locations, attachment.URL)
+		default:
+			locations = append(locations, attachment.FileName())
+		}
+	}
+	note := *msg
+	note.Type = bus.MessageTypeText
+	note.Attachments = nil
+	note.Content = strings.TrimSpace(msg.Content + "\n\n" +
+		fmt.Sprintf("(This channel cannot receive attachments; the file is at %s)", strings.Join(locations, ", ")))
+	return channel.SendMessage(ctx, &note)
+}
+
 func channelTypeOf(channel Channel) string {
 	typed, ok := channel.(TypedChannel)
 	if ok {
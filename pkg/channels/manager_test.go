@@ -2,6 +2,7 @@ package channels
 
 import (
 	"context"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -354,3 +355,72 @@ func TestManagerStopChannelKeepsTypeAliasForRemainingInstance(t *testing.T) {
 		t.Fatalf("expected default alias to move to remaining instance")
 	}
 }
+
+type fileTestChannel struct {
+	testChannel
+	files []string
+}
+
+func (c *fileTestChannel) SendAttachments(ctx context.Context, sessionID, caption string, attachments []bus.Attachment) error {
+	for _, attachment := range attachments {
+		c.files = append(c.files, sessionID+"|"+attachment.Path+"|"+caption)
+	}
+	return nil
+}
+
+type recordingTestChannel struct {
+	testChannel
+	messages []*bus.Message
+}
+
+func (c *recordingTestChannel) SendMessage(ctx context.Context, msg *bus.Message) error {
+	c.messages = append(c.messages, msg)
+	return nil
+}
+
+func TestDeliverOutboundUploadsAttachmentsThroughAttachmentSender(t *testing.T) {
+	ch := &fileTestChannel{testChannel: testChannel{id: "files"}}
+
+	err := deliverOutbound(context.Background(), ch, &bus.Message{
+		ChannelID:   "files",
+		SessionID:   "files:1",
+		Type:        bus.MessageTypeFile,
+		Content:     "report",
+		Attachments: []bus.Attachment{bus.FileAttachment("/workspace/report.pdf")},
+	})
+	if err != nil {
+		t.Fatalf("deliverOutbound failed: %v", err)
+	}
+	if len(ch.files) != 1 || ch.files[0] != "files:1|/workspace/report.pdf|report" {
+		t.Fatalf("unexpected uploads: %#v", ch.files)
+	}
+	if got := ch.sendCount.Load(); got != 0 {
+		t.Fatalf("expected no text send for an uploaded file, got %d", got)
+	}
+}
+
+func TestDeliverOutboundFallsBackToTextWithoutAttachmentSender(t *testing.T) {
+	ch := &recordingTestChannel{testChannel: testChannel{id: "text"}}
+
+	err := deliverOutbound(context.Background(), ch, &bus.Message{
+		ChannelID:   "text",
+		SessionID:   "text:1",
+		Type:        bus.MessageTypeFile,
+		Content:     "report",
+		Attachments: []bus.Attachment{bus.FileAttachment("/workspace/report.pdf")},
+	})
+	if err != nil {
+		t.Fatalf("deliverOutbound failed: %v", err)
+	}
+	if len(ch.messages) != 1 {
+		t.Fatalf("expected one text fallback, got %d", len(ch.messages))
+	}
+	got := ch.messages[0]
+	if got.Type != bus.MessageTypeText || !strings.Contains(got.Content, "report") || !strings.Contains(got.Content, "/workspace/report.pdf") {
+		t.Fatalf("unexpected fallback message: %+v", got)
+	}
+
+	if err := deliverOutbound(context.Background(), ch, &bus.Message{Type: bus.MessageTypeFile}); err == nil {
+		t.Fatal("expected file message without attachments to fail")
+	}
+}
@@ -0,0 +1,503 @@
+// Package matrix provides Matrix channel implementation over the
+// Client-Server API.
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/bus"
+	channelcapabilities "nekobot/pkg/channelcapabilities"
+	"nekobot/pkg/channeltrace"
+	"nekobot/pkg/commands"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+const (
+	clientAPIPrefix = "/_matrix/client/v3"
+
+	// syncTimeout is how long the homeserver holds a sync request open
+	// when there are no new events.
+	syncTimeout = 30 * time.Second
+	// syncRetryDelay is the pause after a failed sync before retrying.
+	syncRetryDelay = 5 * time.Second
+
+	thinkingText = "🤔 Thinking..."
+)
+
+// syncResponse is the part of a /sync response the channel reads.
+type syncResponse struct {
+	NextBatch string `json:"next_batch"`
+	Rooms     struct {
+		Join map[string]struct {
+			Timeline struct {
+				Events []roomEvent `json:"events"`
+			} `json:"timeline"`
+		} `json:"join"`
+		Invite map[string]struct {
+			InviteState struct {
+				Events []roomEvent `json:"events"`
+			} `json:"invite_state"`
+		} `json:"invite"`
+	} `json:"rooms"`
+}
+
+type roomEvent struct {
+	Type           string       `json:"type"`
+	EventID        string       `json:"event_id"`
+	Sender         string       `json:"sender"`
+	StateKey       *string      `json:"state_key,omitempty"`
+	OriginServerTS int64        `json:"origin_server_ts"`
+	Content        eventContent `json:"content"`
+}
+
+type eventContent struct {
+	MsgType    string        `json:"msgtype,omitempty"`
+	Body       string        `json:"body,omitempty"`
+	Membership string        `json:"membership,omitempty"`
+	NewContent *eventContent `json:"m.new_content,omitempty"`
+	RelatesTo  *relation     `json:"m.relates_to,omitempty"`
+}
+
+type relation struct {
+	RelType string `json:"rel_type,omitempty"`
+	EventID string `json:"event_id,omitempty"`
+}
+
+// Channel implements the Matrix channel.
+type Channel struct {
+	log         *logger.Logger
+	config      config.MatrixConfig
+	bus         bus.Bus
+	commands    *commands.Registry
+	id          string
+	channelType string
+	name        string
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	running    bool
+	done       chan struct{}
+	httpClient *http.Client
+	homeserver string
+
+	txnSeq atomic.Int64
+}
+
+// NewChannel creates a new Matrix channel.
+func NewChannel(
+	log *logger.Logger,
+	cfg config.MatrixConfig,
+	b bus.Bus,
+	cmdRegistry *commands.Registry,
+) (*Channel, error) {
+	return NewAccountChannel(log, cfg, b, cmdRegistry, "matrix", "Matrix")
+}
+
+// NewAccountChannel creates an account-scoped Matrix channel instance.
+func NewAccountChannel(
+	log *logger.Logger,
+	cfg config.MatrixConfig,
+	b bus.Bus,
+	cmdRegistry *commands.Registry,
+	channelID string,
+	displayName string,
+) (*Channel, error) {
+	homeserver := strings.TrimRight(strings.TrimSpace(cfg.Homeserver), "/")
+	if homeserver == "" || cfg.UserID == "" || cfg.AccessToken == "" {
+		return nil, fmt.Errorf("matrix homeserver, user_id and access_token are required")
+	}
+
+	return &Channel{
+		log:         log,
+		config:      cfg,
+		bus:         b,
+		commands:    cmdRegistry,
+		id:          strings.TrimSpace(channelID),
+		channelType: "matrix",
+		name:        defaultMatrixName(displayName),
+		httpClient: &http.Client{
+			// Long enough to outlast a sync request held open by the server.
+			Timeout: syncTimeout + 30*time.Second,
+		},
+		homeserver: homeserver,
+	}, nil
+}
+
+// ID returns the channel identifier.
+func (c *Channel) ID() string {
+	return c.id
+}
+
+// Name returns the channel name.
+func (c *Channel) Name() string {
+	return c.name
+}
+
+// ChannelType returns the stable Matrix family key.
+func (c *Channel) ChannelType() string {
+	return c.channelType
+}
+
+// IsEnabled returns whether the channel is enabled.
+func (c *Channel) IsEnabled() bool {
+	return c.config.Enabled
+}
+
+// Start starts syncing with the homeserver.
+func (c *Channel) Start(ctx context.Context) error {
+	c.log.Info("Starting Matrix channel", zap.String("homeserver", c.homeserver))
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+
+	go c.syncLoop()
+
+	c.running = true
+	c.log.Info("Matrix channel started", zap.String("user_id", c.config.UserID))
+	return nil
+}
+
+// Stop stops the Matrix channel.
+func (c *Channel) Stop(ctx context.Context) error {
+	c.log.Info("Stopping Matrix channel")
+	c.running = false
+	if c.cancel != nil {
+		c.cancel()
+	}
+	if c.done != nil {
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// syncLoop long-polls /sync until the channel stops. Messages already in the
+// room history when the channel starts are skipped; pending invites are not.
+func (c *Channel) syncLoop() {
+	defer close(c.done)
+
+	since := ""
+	for c.ctx.Err() == nil {
+		resp, err := c.sync(c.ctx, since)
+		if err != nil {
+			if c.ctx.Err() != nil {
+				return
+			}
+			c.log.Warn("Matrix sync failed", zap.Error(err))
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-time.After(syncRetryDelay):
+			}
+			continue
+		}
+
+		c.handleInvites(resp)
+		if since != "" {
+			c.handleSync(resp)
+		}
+		since = resp.NextBatch
+	}
+}
+
+func (c *Channel) sync(ctx context.Context, since string) (*syncResponse, error) {
+	query := url.Values{}
+	if since == "" {
+		query.Set("timeout", "0")
+	} else {
+		query.Set("since", since)
+		query.Set("timeout", fmt.Sprintf("%d", syncTimeout.Milliseconds()))
+	}
+
+	var resp syncResponse
+	if err := c.do(ctx, http.MethodGet, "/sync?"+query.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// handleInvites joins rooms the bot was invited to by allowed users when
+// auto_join is enabled.
+func (c *Channel) handleInvites(resp *syncResponse) {
+	if !c.config.AutoJoin {
+		return
+	}
+	for roomID, room := range resp.Rooms.Invite {
+		inviter := ""
+		for _, ev := range room.InviteState.Events {
+			if ev.Type == "m.room.member" && ev.StateKey != nil && *ev.StateKey == c.config.UserID &&
+				ev.Content.Membership == "invite" {
+				inviter = ev.Sender
+			}
+		}
+		if inviter == "" || !c.isAllowed(inviter) {
+			c.log.Warn("Ignoring Matrix invite", zap.String("room_id", roomID), zap.String("inviter", inviter))
+			continue
+		}
+		if err := c.do(c.ctx, http.MethodPost, "/rooms/"+url.PathEscape(roomID)+"/join", struct{}{}, nil); err != nil {
+			c.log.Error("Failed to join Matrix room", zap.String("room_id", roomID), zap.Error(err))
+			continue
+		}
+		c.log.Info("Joined Matrix room", zap.String("room_id", roomID), zap.String("inviter", inviter))
+	}
+}
+
+// handleSync dispatches new text messages of joined rooms.
+func (c *Channel) handleSync(resp *syncResponse) {
+	for roomID, room := range resp.Rooms.Join {
+		for _, ev := range room.Timeline.Events {
+			c.handleEvent(roomID, ev)
+		}
+	}
+}
+
+func (c *Channel) handleEvent(roomID string, ev roomEvent) {
+	if ev.Type != "m.room.message" || ev.Sender == c.config.UserID {
+		return
+	}
+	// Notices are bot output by convention; edits are not re-run.
+	if ev.Content.MsgType != "m.text" {
+		return
+	}
+	if ev.Content.RelatesTo != nil && ev.Content.RelatesTo.RelType == "m.replace" {
+		return
+	}
+	content := strings.TrimSpace(ev.Content.Body)
+	if content == "" {
+		return
+	}
+	if !c.isAllowed(ev.Sender) {
+		c.log.Warn("Unauthorized Matrix sender", zap.String("user_id", ev.Sender))
+		return
+	}
+
+	if c.supportsNativeCommands() && c.commands.IsCommand(content) {
+		c.handleCommand(roomID, ev, content)
+		return
+	}
+
+	thinkingID, err := c.sendText(c.ctx, roomID, thinkingText)
+	if err != nil {
+		c.log.Debug("Failed to send Matrix thinking message", zap.Error(err))
+	}
+
+	timestamp := time.Now()
+	if ev.OriginServerTS > 0 {
+		timestamp = time.UnixMilli(ev.OriginServerTS)
+	}
+	msg := &bus.Message{
+		ID:        "matrix:" + ev.EventID,
+		ChannelID: c.ID(),
+		SessionID: "matrix:" + roomID,
+		UserID:    ev.Sender,
+		Username:  ev.Sender,
+		Type:      bus.MessageTypeText,
+		Content:   content,
+		Timestamp: timestamp,
+		Data: map[string]interface{}{
+			"room_id":           roomID,
+			"reply_to_event_id": ev.EventID,
+			"thinking_event_id": thinkingID,
+		},
+	}
+	if err := c.bus.SendInbound(msg); err != nil {
+		c.log.Error("Failed to dispatch Matrix message to bus", zap.Error(err))
+		c.finishThinkingMessage(c.ctx, roomID, thinkingID, "❌ Sorry, something went wrong handling your message.")
+	}
+}
+
+func (c *Channel) supportsNativeCommands() bool {
+	return channelcapabilities.IsCapabilityEnabled(
+		channelcapabilities.GetDefaultCapabilitiesForChannel(c.ChannelType()),
+		channelcapabilities.CapabilityNativeCommands,
+		channelcapabilities.CapabilityScopeGroup,
+		false,
+	)
+}
+
+func (c *Channel) handleCommand(roomID string, ev roomEvent, content string) {
+	cmdName, args := c.commands.Parse(content)
+	if cmdName == "" {
+		_, _ = c.sendText(c.ctx, roomID, commands.MalformedCommandMessage())
+		return
+	}
+
+	cmd, exists := c.commands.Get(cmdName)
+	if !exists {
+		_, _ = c.sendText(c.ctx, roomID, c.commands.UnknownCommandMessage(cmdName))
+		return
+	}
+
+	thinkingID, err := c.sendText(c.ctx, roomID, "🤔 Running command...")
+	if err != nil {
+		c.log.Debug("Failed to send Matrix thinking message", zap.Error(err))
+	}
+
+	ctx, cancel := context.WithTimeout(c.ctx, 30*time.Second)
+	defer cancel()
+
+	resp, err := cmd.Handler(ctx, commands.CommandRequest{
+		Channel:  c.ID(),
+		ChatID:   roomID,
+		UserID:   ev.Sender,
+		Username: ev.Sender,
+		Command:  cmdName,
+		Args:     args,
+		Metadata: map[string]string{
+			"room_id":  roomID,
+			"event_id": ev.EventID,
+		},
+	})
+	if err != nil {
+		c.log.Error("Matrix command failed", zap.String("command", cmdName), zap.Error(err))
+		c.finishThinkingMessage(ctx, roomID, thinkingID, "❌ Command failed: "+err.Error())
+		return
+	}
+
+	c.finishThinkingMessage(ctx, roomID, thinkingID, resp.Content)
+}
+
+// SendMessage sends a message to a Matrix room. Replies to a message that
+// showed a thinking notice replace the notice in place.
+func (c *Channel) SendMessage(ctx context.Context, msg *bus.Message) error {
+	if msg.Type == bus.MessageTypeStreamUpdate {
+		return nil
+	}
+
+	roomID, _ := msg.Data["room_id"].(string)
+	if roomID == "" {
+		roomID = strings.TrimPrefix(msg.SessionID, "matrix:")
+	}
+	if roomID == "" || !strings.HasPrefix(roomID, "!") {
+		return fmt.Errorf("invalid matrix room for session %s", msg.SessionID)
+	}
+
+	text := channeltrace.PrependBusToolTrace(msg.Content, msg)
+	thinkingID, _ := msg.Data["thinking_event_id"].(string)
+	if thinkingID != "" {
+		if err := c.editText(ctx, roomID, thinkingID, text); err == nil {
+			return nil
+		} else {
+			c.log.Warn("Failed to edit Matrix thinking message, sending a new one", zap.Error(err))
+		}
+	}
+
+	_, err := c.sendText(ctx, roomID, text)
+	return err
+}
+
+// finishThinkingMessage replaces the thinking notice with text, or sends text
+// as a new message when there is no notice to edit.
+func (c *Channel) finishThinkingMessage(ctx context.Context, roomID, thinkingID, text string) {
+	if thinkingID != "" {
+		if err := c.editText(ctx, roomID, thinkingID, text); err == nil {
+			return
+		}
+	}
+	if _, err := c.sendText(ctx, roomID, text); err != nil {
+		c.log.Error("Failed to send Matrix message", zap.Error(err))
+	}
+}
+
+// sendText posts a text message and returns its event ID.
+func (c *Channel) sendText(ctx context.Context, roomID, text string) (string, error) {
+	return c.sendMessageEvent(ctx, roomID, eventContent{MsgType: "m.text", Body: text})
+}
+
+// editText replaces the body of eventID with an m.replace edit.
+func (c *Channel) editText(ctx context.Context, roomID, eventID, text string) error {
+	_, err := c.sendMessageEvent(ctx, roomID, eventContent{
+		MsgType:    "m.text",
+		Body:       "* " + text,
+		NewContent: &eventContent{MsgType: "m.text", Body: text},
+		RelatesTo:  &relation{RelType: "m.replace", EventID: eventID},
+	})
+	return err
+}
+
+func (c *Channel) sendMessageEvent(ctx context.Context, roomID string, content eventContent) (string, error) {
+	txnID := fmt.Sprintf("nekobot-%d-%d", time.Now().UnixNano(), c.txnSeq.Add(1))
+	path := "/rooms/" + url.PathEscape(roomID) + "/send/m.room.message/" + url.PathEscape(txnID)
+
+	var resp struct {
+		EventID string `json:"event_id"`
+	}
+	if err := c.do(ctx, http.MethodPut, path, content, &resp); err != nil {
+		return "", err
+	}
+	return resp.EventID, nil
+}
+
+// do sends an authenticated Client-Server API request and decodes the JSON
+// response into out when it is not nil.
+func (c *Channel) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshaling matrix request: %w", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.homeserver+clientAPIPrefix+path, reader)
+	if err != nil {
+		return fmt.Errorf("creating matrix request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending matrix request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("matrix api status %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding matrix response: %w", err)
+	}
+	return nil
+}
+
+func (c *Channel) isAllowed(userID string) bool {
+	if len(c.config.AllowFrom) == 0 {
+		return true
+	}
+	for _, allowed := range c.config.AllowFrom {
+		if allowed == userID || allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func defaultMatrixName(displayName string) string {
+	name := strings.TrimSpace(displayName)
+	if name == "" {
+		return "Matrix"
+	}
+	return name
+}
@@ -0,0 +1,220 @@
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/commands"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+func TestHandleSyncDispatchesMessagesWithThinkingNotice(t *testing.T) {
+	hs := newFakeHomeserver(t)
+	b := &stubBus{}
+	channel := newTestChannel(t, hs, b, config.MatrixConfig{AllowFrom: []string{"@alice:example.org"}})
+
+	var resp syncResponse
+	decodeJSON(t, `{"next_batch":"s2","rooms":{"join":{"!room:example.org":{"timeline":{"events":[
+		{"type":"m.room.message","event_id":"$own","sender":"@bot:example.org","content":{"msgtype":"m.text","body":"echo"}},
+		{"type":"m.room.message","event_id":"$mallory","sender":"@mallory:example.org","content":{"msgtype":"m.text","body":"hi"}},
+		{"type":"m.room.message","event_id":"$notice","sender":"@alice:example.org","content":{"msgtype":"m.notice","body":"bot output"}},
+		{"type":"m.room.message","event_id":"$hello","sender":"@alice:example.org","origin_server_ts":1700000000000,"content":{"msgtype":"m.text","body":" hello "}}
+	]}}}}}`, &resp)
+	channel.handleSync(&resp)
+
+	if len(b.inbound) != 1 {
+		t.Fatalf("expected one inbound message, got %d", len(b.inbound))
+	}
+	msg := b.inbound[0]
+	if msg.ID != "matrix:$hello" || msg.SessionID != "matrix:!room:example.org" || msg.UserID != "@alice:example.org" {
+		t.Fatalf("unexpected inbound message %+v", msg)
+	}
+	if msg.Content != "hello" || msg.Timestamp.UnixMilli() != 1700000000000 {
+		t.Fatalf("unexpected inbound content %q at %v", msg.Content, msg.Timestamp)
+	}
+	if msg.Data["thinking_event_id"] != "$sent1" {
+		t.Fatalf("expected thinking notice event id, got %+v", msg.Data)
+	}
+
+	sent := hs.sentEvents()
+	if len(sent) != 1 || sent[0].room != "!room:example.org" || sent[0].content.Body != thinkingText {
+		t.Fatalf("expected one thinking notice, got %+v", sent)
+	}
+}
+
+func TestSendMessageEditsThinkingNotice(t *testing.T) {
+	hs := newFakeHomeserver(t)
+	channel := newTestChannel(t, hs, &stubBus{}, config.MatrixConfig{})
+
+	err := channel.SendMessage(context.Background(), &bus.Message{
+		SessionID: "matrix:!room:example.org",
+		Content:   "done",
+		Data: map[string]interface{}{
+			"room_id":           "!room:example.org",
+			"thinking_event_id": "$thinking",
+			"tool_call_trace":   "Tool call: read_file",
+		},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	sent := hs.sentEvents()
+	if len(sent) != 1 {
+		t.Fatalf("expected one edit, got %+v", sent)
+	}
+	content := sent[0].content
+	if content.RelatesTo == nil || content.RelatesTo.RelType != "m.replace" || content.RelatesTo.EventID != "$thinking" {
+		t.Fatalf("expected an m.replace edit of the thinking notice, got %+v", content)
+	}
+	if content.NewContent == nil || !strings.Contains(content.NewContent.Body, "Tool call: read_file") ||
+		!strings.HasSuffix(content.NewContent.Body, "done") {
+		t.Fatalf("unexpected edited body %+v", content.NewContent)
+	}
+	if hs.authHeader() != "Bearer secret-token" {
+		t.Fatalf("expected access token auth, got %q", hs.authHeader())
+	}
+}
+
+func TestHandleInvitesJoinsRoomsFromAllowedUsers(t *testing.T) {
+	hs := newFakeHomeserver(t)
+	channel := newTestChannel(t, hs, &stubBus{}, config.MatrixConfig{
+		AutoJoin:  true,
+		AllowFrom: []string{"@alice:example.org"},
+	})
+
+	var resp syncResponse
+	decodeJSON(t, `{"rooms":{"invite":{
+		"!alice:example.org":{"invite_state":{"events":[{"type":"m.room.member","sender":"@alice:example.org","state_key":"@bot:example.org","content":{"membership":"invite"}}]}},
+		"!mallory:example.org":{"invite_state":{"events":[{"type":"m.room.member","sender":"@mallory:example.org","state_key":"@bot:example.org","content":{"membership":"invite"}}]}}
+	}}}`, &resp)
+	channel.handleInvites(&resp)
+
+	joined := hs.joinedRooms()
+	if len(joined) != 1 || joined[0] != "!alice:example.org" {
+		t.Fatalf("expected to join only the allowed invite, got %v", joined)
+	}
+}
+
+type sentEvent struct {
+	room    string
+	content eventContent
+}
+
+type fakeHomeserver struct {
+	server *httptest.Server
+
+	mu     sync.Mutex
+	auth   string
+	sent   []sentEvent
+	joined []string
+}
+
+func newFakeHomeserver(t *testing.T) *fakeHomeserver {
+	t.Helper()
+	hs := &fakeHomeserver{}
+	hs.server = httptest.NewServer(http.HandlerFunc(hs.serve))
+	t.Cleanup(hs.server.Close)
+	return hs
+}
+
+func (hs *fakeHomeserver) serve(w http.ResponseWriter, r *http.Request) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	hs.auth = r.Header.Get("Authorization")
+
+	path := strings.TrimPrefix(r.URL.Path, clientAPIPrefix+"/rooms/")
+	switch {
+	case r.Method == http.MethodPut && strings.Contains(path, "/send/m.room.message/"):
+		var content eventContent
+		_ = json.NewDecoder(r.Body).Decode(&content)
+		hs.sent = append(hs.sent, sentEvent{room: strings.SplitN(path, "/", 2)[0], content: content})
+		_ = json.NewEncoder(w).Encode(map[string]string{"event_id": fmt.Sprintf("$sent%d", len(hs.sent))})
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/join"):
+		room := strings.TrimSuffix(path, "/join")
+		hs.joined = append(hs.joined, room)
+		_ = json.NewEncoder(w).Encode(map[string]string{"room_id": room})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (hs *fakeHomeserver) sentEvents() []sentEvent {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	return append([]sentEvent(nil), hs.sent...)
+}
+
+func (hs *fakeHomeserver) joinedRooms() []string {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	return append([]string(nil), hs.joined...)
+}
+
+func (hs *fakeHomeserver) authHeader() string {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	return hs.auth
+}
+
+func newTestChannel(t *testing.T, hs *fakeHomeserver, b bus.Bus, cfg config.MatrixConfig) *Channel {
+	t.Helper()
+	cfg.Enabled = true
+	cfg.Homeserver = hs.server.URL
+	cfg.UserID = "@bot:example.org"
+	cfg.AccessToken = "secret-token"
+
+	channel, err := NewChannel(newTestLogger(t), cfg, b, commands.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewChannel failed: %v", err)
+	}
+	channel.ctx = context.Background()
+	return channel
+}
+
+func decodeJSON(t *testing.T, raw string, out interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(raw), out); err != nil {
+		t.Fatalf("decode fixture: %v", err)
+	}
+}
+
+type stubBus struct {
+	inbound []*bus.Message
+}
+
+func (b *stubBus) Start() error                                                  { return nil }
+func (b *stubBus) Stop() error                                                   { return nil }
+func (b *stubBus) RegisterInboundHandler(channelID string, handler bus.Handler)  {}
+func (b *stubBus) UnregisterInboundHandlers(channelID string)                    {}
+func (b *stubBus) RegisterOutboundHandler(channelID string, handler bus.Handler) {}
+func (b *stubBus) UnregisterOutboundHandlers(channelID string)                   {}
+func (b *stubBus) RegisterHandler(channelID string, handler bus.Handler)         {}
+func (b *stubBus) UnregisterHandlers(channelID string)                           {}
+func (b *stubBus) SendInbound(msg *bus.Message) error {
+	b.inbound = append(b.inbound, msg)
+	return nil
+}
+func (b *stubBus) SendOutbound(msg *bus.Message) error { return nil }
+func (b *stubBus) GetMetrics() map[string]uint64       { return map[string]uint64{} }
+
+func newTestLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+
+	cfg := logger.DefaultConfig()
+	cfg.OutputPath = ""
+	cfg.Development = true
+	log, err := logger.New(cfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return log
+}
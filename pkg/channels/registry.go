@@ -15,8 +15,10 @@ import (
 	"nekobot/pkg/channels/gotify"
 	"nekobot/pkg/channels/infoflow"
 	"nekobot/pkg/channels/maixcam"
+	"nekobot/pkg/channels/matrix"
 	"nekobot/pkg/channels/qq"
 	"nekobot/pkg/channels/serverchan"
+	"nekobot/pkg/channels/signal"
 	"nekobot/pkg/channels/slack"
 	"nekobot/pkg/channels/teams"
 	"nekobot/pkg/channels/telegram"
@@ -30,9 +32,17 @@ import (
 	"nekobot/pkg/process"
 	"nekobot/pkg/toolsessions"
 	"nekobot/pkg/transcription"
+	"nekobot/pkg/tts"
 	"nekobot/pkg/userprefs"
 )
 
+var (
+	_ ReplyEditor      = (*telegram.Channel)(nil)
+	_ AttachmentSender = (*telegram.Channel)(nil)
+	_ AttachmentSender = (*discord.Channel)(nil)
+	_ AttachmentSender = (*slack.Channel)(nil)
+)
+
 type channelDescriptor struct {
 	name    string
 	get     func(*config.Config) interface{}
@@ -74,8 +84,13 @@ var channelDescriptors = []channelDescriptor{
 			if telegramCfg.TimeoutSeconds <= 0 {
 				telegramCfg.TimeoutSeconds = cfg.Channels.TimeoutSeconds
 			}
-			transcriber := transcription.NewFromConfig(log, cfg)
-			return telegram.New(log, messageBus, ag, cmdRegistry, &telegramCfg, transcriber, prefsMgr)
+			transcriber := transcription.NewForChannel(log, cfg, "telegram")
+			channel, err := telegram.New(log, messageBus, ag, cmdRegistry, &telegramCfg, transcriber, prefsMgr)
+			if err != nil {
+				return nil, err
+			}
+			channel.SetSynthesizer(tts.NewFromConfig(log, cfg))
+			return channel, nil
 		},
 		buildFromAccount: func(account channelaccounts.ChannelAccount, log *logger.Logger, messageBus bus.Bus, ag *agent.Agent, cmdRegistry *commands.Registry, prefsMgr *userprefs.Manager, toolSessionMgr *toolsessions.Manager, processMgr *process.Manager, cfg *config.Config) (Channel, error) {
 			telegramCfg := cfg.Channels.Telegram
@@ -85,8 +100,8 @@ var channelDescriptors = []channelDescriptor{
 			if telegramCfg.TimeoutSeconds <= 0 {
 				telegramCfg.TimeoutSeconds = cfg.Channels.TimeoutSeconds
 			}
-			transcriber := transcription.NewFromConfig(log, cfg)
-			return telegram.NewAccountChannel(
+			transcriber := transcription.NewForChannel(log, cfg, "telegram")
+			channel, err := telegram.NewAccountChannel(
 				log,
 				messageBus,
 				ag,
@@ -97,6 +112,11 @@ var channelDescriptors = []channelDescriptor{
 				channelInstanceID(account),
 				channelDisplayName(account, "Telegram"),
 			)
+			if err != nil {
+				return nil, err
+			}
+			channel.SetSynthesizer(tts.NewFromConfig(log, cfg))
+			return channel, nil
 		},
 	},
 	{
@@ -125,16 +145,22 @@ var channelDescriptors = []channelDescriptor{
 		},
 		enabled: func(cfg *config.Config) bool { return cfg.Channels.Discord.Enabled },
 		build: func(log *logger.Logger, messageBus bus.Bus, ag *agent.Agent, cmdRegistry *commands.Registry, prefsMgr *userprefs.Manager, toolSessionMgr *toolsessions.Manager, processMgr *process.Manager, cfg *config.Config) (Channel, error) {
-			transcriber := transcription.NewFromConfig(log, cfg)
-			return discord.NewChannel(log, cfg.Channels.Discord, messageBus, cmdRegistry, transcriber)
+			transcriber := transcription.NewForChannel(log, cfg, "discord")
+			channel, err := discord.NewChannel(log, cfg.Channels.Discord, messageBus, cmdRegistry, transcriber)
+			if err != nil {
+				return nil, err
+			}
+			attachDiscordFeedback(channel, ag)
+			channel.SetIdentityLinks(prefsMgr)
+			return channel, nil
 		},
 		buildFromAccount: func(account channelaccounts.ChannelAccount, log *logger.Logger, messageBus bus.Bus, ag *agent.Agent, cmdRegistry *commands.Registry, prefsMgr *userprefs.Manager, toolSessionMgr *toolsessions.Manager, processMgr *process.Manager, cfg *config.Config) (Channel, error) {
 			discordCfg := cfg.Channels.Discord
 			if err := decodeAccountConfig(account, &discordCfg); err != nil {
 				return nil, err
 			}
-			transcriber := transcription.NewFromConfig(log, cfg)
-			return discord.NewAccountChannel(
+			transcriber := transcription.NewForChannel(log, cfg, "discord")
+			channel, err := discord.NewAccountChannel(
 				log,
 				discordCfg,
 				messageBus,
@@ -143,6 +169,12 @@ var channelDescriptors = []channelDescriptor{
 				channelInstanceID(account),
 				channelDisplayName(account, "Discord"),
 			)
+			if err != nil {
+				return nil, err
+			}
+			attachDiscordFeedback(channel, ag)
+			channel.SetIdentityLinks(prefsMgr)
+			return channel, nil
 		},
 	},
 	{
@@ -151,16 +183,21 @@ var channelDescriptors = []channelDescriptor{
 		set:     func(cfg *config.Config, data json.RawMessage) error { return json.Unmarshal(data, &cfg.Channels.Slack) },
 		enabled: func(cfg *config.Config) bool { return cfg.Channels.Slack.Enabled },
 		build: func(log *logger.Logger, messageBus bus.Bus, ag *agent.Agent, cmdRegistry *commands.Registry, prefsMgr *userprefs.Manager, toolSessionMgr *toolsessions.Manager, processMgr *process.Manager, cfg *config.Config) (Channel, error) {
-			transcriber := transcription.NewFromConfig(log, cfg)
-			return slack.NewChannel(log, cfg.Channels.Slack, messageBus, cmdRegistry, transcriber)
+			transcriber := transcription.NewForChannel(log, cfg, "slack")
+			channel, err := slack.NewChannel(log, cfg.Channels.Slack, messageBus, cmdRegistry, transcriber)
+			if err != nil {
+				return nil, err
+			}
+			channel.SetApprovals(ag.ApprovalManager())
+			return channel, nil
 		},
 		buildFromAccount: func(account channelaccounts.ChannelAccount, log *logger.Logger, messageBus bus.Bus, ag *agent.Agent, cmdRegistry *commands.Registry, prefsMgr *userprefs.Manager, toolSessionMgr *toolsessions.Manager, processMgr *process.Manager, cfg *config.Config) (Channel, error) {
 			slackCfg := cfg.Channels.Slack
 			if err := decodeAccountConfig(account, &slackCfg); err != nil {
 				return nil, err
 			}
-			transcriber := transcription.NewFromConfig(log, cfg)
-			return slack.NewAccountChannel(
+			transcriber := transcription.NewForChannel(log, cfg, "slack")
+			channel, err := slack.NewAccountChannel(
 				log,
 				slackCfg,
 				messageBus,
@@ -169,6 +206,11 @@ var channelDescriptors = []channelDescriptor{
 				channelInstanceID(account),
 				channelDisplayName(account, "Slack"),
 			)
+			if err != nil {
+				return nil, err
+			}
+			channel.SetApprovals(ag.ApprovalManager())
+			return channel, nil
 		},
 	},
 	{
@@ -202,7 +244,7 @@ var channelDescriptors = []channelDescriptor{
 				return nil, err
 			}
 			authSvc := ilinkauth.NewService(store, nil)
-			transcriber := transcription.NewFromConfig(log, cfg)
+			transcriber := transcription.NewForChannel(log, cfg, "wechat")
 			return wechat.NewChannel(log, cfg.Channels.WeChat, messageBus, ag, cmdRegistry, authSvc, toolSessionMgr, processMgr, cfg, transcriber)
 		},
 		buildFromAccount: func(account channelaccounts.ChannelAccount, log *logger.Logger, messageBus bus.Bus, ag *agent.Agent, cmdRegistry *commands.Registry, prefsMgr *userprefs.Manager, toolSessionMgr *toolsessions.Manager, processMgr *process.Manager, cfg *config.Config) (Channel, error) {
@@ -219,7 +261,7 @@ var channelDescriptors = []channelDescriptor{
 				return nil, err
 			}
 			authSvc := ilinkauth.NewService(store, nil)
-			transcriber := transcription.NewFromConfig(log, cfg)
+			transcriber := transcription.NewForChannel(log, cfg, "wechat")
 			return wechat.NewAccountChannel(
 				log,
 				wechatCfg,
@@ -388,6 +430,44 @@ var channelDescriptors = []channelDescriptor{
 			return infoflow.NewAccountChannel(log, infoflowCfg, messageBus, cmdRegistry, channelInstanceID(account), channelDisplayName(account, "Infoflow"))
 		},
 	},
+	{
+		name: "matrix",
+		get:  func(cfg *config.Config) interface{} { return cfg.Channels.Matrix },
+		set: func(cfg *config.Config, data json.RawMessage) error {
+			return json.Unmarshal(data, &cfg.Channels.Matrix)
+		},
+		enabled: func(cfg *config.Config) bool { return cfg.Channels.Matrix.Enabled },
+		build: func(log *logger.Logger, messageBus bus.Bus, ag *agent.Agent, cmdRegistry *commands.Registry, prefsMgr *userprefs.Manager, toolSessionMgr *toolsessions.Manager, processMgr *process.Manager, cfg *config.Config) (Channel, error) {
+			return matrix.NewChannel(log, cfg.Channels.Matrix, messageBus, cmdRegistry)
+		},
+		buildFromAccount: func(account channelaccounts.ChannelAccount, log *logger.Logger, messageBus bus.Bus, ag *agent.Agent, cmdRegistry *commands.Registry, prefsMgr *userprefs.Manager, toolSessionMgr *toolsessions.Manager, processMgr *process.Manager, cfg *config.Config) (Channel, error) {
+			matrixCfg := cfg.Channels.Matrix
+			if err := decodeAccountConfig(account, &matrixCfg); err != nil {
+				return nil, err
+			}
+			return matrix.NewAccountChannel(log, matrixCfg, messageBus, cmdRegistry, channelInstanceID(account), channelDisplayName(account, "Matrix"))
+		},
+	},
+	{
+		name: "signal",
+		get:  func(cfg *config.Config) interface{} { return cfg.Channels.Signal },
+		set: func(cfg *config.Config, data json.RawMessage) error {
+			return json.Unmarshal(data, &cfg.Channels.Signal)
+		},
+		enabled: func(cfg *config.Config) bool { return cfg.Channels.Signal.Enabled },
+		build: func(log *logger.Logger, messageBus bus.Bus, ag *agent.Agent, cmdRegistry *commands.Registry, prefsMgr *userprefs.Manager, toolSessionMgr *toolsessions.Manager, processMgr *process.Manager, cfg *config.Config) (Channel, error) {
+			transcriber := transcription.NewForChannel(log, cfg, "signal")
+			return signal.NewChannel(log, cfg.Channels.Signal, messageBus, cmdRegistry, transcriber)
+		},
+		buildFromAccount: func(account channelaccounts.ChannelAccount, log *logger.Logger, messageBus bus.Bus, ag *agent.Agent, cmdRegistry *commands.Registry, prefsMgr *userprefs.Manager, toolSessionMgr *toolsessions.Manager, processMgr *process.Manager, cfg *config.Config) (Channel, error) {
+			signalCfg := cfg.Channels.Signal
+			if err := decodeAccountConfig(account, &signalCfg); err != nil {
+				return nil, err
+			}
+			transcriber := transcription.NewForChannel(log, cfg, "signal")
+			return signal.NewAccountChannel(log, signalCfg, messageBus, cmdRegistry, transcriber, channelInstanceID(account), channelDisplayName(account, "Signal"))
+		},
+	},
 }
 
 func getChannelDescriptor(name string) (*channelDescriptor, error) {
@@ -499,3 +579,12 @@ func channelDisplayName(account channelaccounts.ChannelAccount, fallback string)
 	}
 	return fallback
 }
+
+// attachDiscordFeedback enables reaction ratings when the agent has a
+// feedback store, and approval buttons backed by its approval queue.
+func attachDiscordFeedback(channel *discord.Channel, ag *agent.Agent) {
+	if store := ag.Feedback(); store != nil {
+		channel.SetFeedback(store)
+	}
+	channel.SetApprovals(ag.ApprovalManager())
+}
@@ -331,6 +331,66 @@ func TestBuildChannelFromAccount_Teams(t *testing.T) {
 	}
 }
 
+func TestBuildChannelFromAccount_Matrix(t *testing.T) {
+	cfg := config.DefaultConfig()
+	log := newRegistryTestLogger(t)
+
+	account := channelaccounts.ChannelAccount{
+		ChannelType: "matrix",
+		AccountKey:  "home",
+		DisplayName: "Home Matrix",
+		Config: map[string]interface{}{
+			"enabled":      true,
+			"homeserver":   "https://matrix.example.org",
+			"user_id":      "@nekobot:example.org",
+			"access_token": "matrix-token",
+		},
+	}
+
+	channel, err := BuildChannelFromAccount(account, log, nil, nil, nil, nil, nil, nil, cfg)
+	if err != nil {
+		t.Fatalf("BuildChannelFromAccount failed: %v", err)
+	}
+	if channel.ID() != "matrix:home" {
+		t.Fatalf("unexpected matrix account channel id: %s", channel.ID())
+	}
+	if typed, ok := channel.(TypedChannel); !ok || typed.ChannelType() != "matrix" {
+		t.Fatalf("expected typed matrix channel, got %T", channel)
+	}
+	if channel.Name() != "Home Matrix" {
+		t.Fatalf("unexpected matrix account channel name: %s", channel.Name())
+	}
+}
+
+func TestBuildChannelFromAccount_Signal(t *testing.T) {
+	cfg := config.DefaultConfig()
+	log := newRegistryTestLogger(t)
+
+	account := channelaccounts.ChannelAccount{
+		ChannelType: "signal",
+		AccountKey:  "personal",
+		DisplayName: "Signal Personal",
+		Config: map[string]interface{}{
+			"enabled": true,
+			"number":  "+15550000000",
+		},
+	}
+
+	channel, err := BuildChannelFromAccount(account, log, nil, nil, nil, nil, nil, nil, cfg)
+	if err != nil {
+		t.Fatalf("BuildChannelFromAccount failed: %v", err)
+	}
+	if channel.ID() != "signal:personal" {
+		t.Fatalf("unexpected signal account channel id: %s", channel.ID())
+	}
+	if typed, ok := channel.(TypedChannel); !ok || typed.ChannelType() != "signal" {
+		t.Fatalf("expected typed signal channel, got %T", channel)
+	}
+	if channel.Name() != "Signal Personal" {
+		t.Fatalf("unexpected signal account channel name: %s", channel.Name())
+	}
+}
+
 func TestBuildChannelFromAccount_WeWork(t *testing.T) {
 	cfg := config.DefaultConfig()
 	log := newRegistryTestLogger(t)
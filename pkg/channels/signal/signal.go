@@ -0,0 +1,446 @@
+// Package signal provides Signal channel implementation through a
+// signal-cli-rest-api endpoint.
+package signal
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/channeltrace"
+	"nekobot/pkg/commands"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/transcription"
+)
+
+const (
+	// receiveTimeout is how long signal-cli waits for new messages before
+	// answering a receive request.
+	receiveTimeout = 10 * time.Second
+	// receiveRetryDelay is the pause after a failed receive before retrying.
+	receiveRetryDelay = 5 * time.Second
+
+	groupRecipientPrefix = "group."
+)
+
+// envelope is one entry of a /v1/receive response.
+type envelope struct {
+	Envelope struct {
+		Source       string       `json:"source"`
+		SourceNumber string       `json:"sourceNumber"`
+		SourceUUID   string       `json:"sourceUuid"`
+		SourceName   string       `json:"sourceName"`
+		Timestamp    int64        `json:"timestamp"`
+		DataMessage  *dataMessage `json:"dataMessage"`
+	} `json:"envelope"`
+}
+
+type dataMessage struct {
+	Timestamp   int64        `json:"timestamp"`
+	Message     string       `json:"message"`
+	GroupInfo   *groupInfo   `json:"groupInfo"`
+	Attachments []attachment `json:"attachments"`
+}
+
+type groupInfo struct {
+	GroupID string `json:"groupId"`
+}
+
+type attachment struct {
+	ID          string `json:"id"`
+	ContentType string `json:"contentType"`
+	Filename    string `json:"filename"`
+}
+
+type sendRequest struct {
+	Message        string   `json:"message"`
+	Number         string   `json:"number"`
+	Recipients     []string `json:"recipients"`
+	QuoteTimestamp int64    `json:"quote_timestamp,omitempty"`
+	QuoteAuthor    string   `json:"quote_author,omitempty"`
+	QuoteMessage   string   `json:"quote_message,omitempty"`
+}
+
+// Channel implements the Signal channel.
+type Channel struct {
+	log         *logger.Logger
+	config      config.SignalConfig
+	bus         bus.Bus
+	commands    *commands.Registry
+	id          string
+	channelType string
+	name        string
+	transcriber transcription.Transcriber
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	running    bool
+	done       chan struct{}
+	httpClient *http.Client
+	apiURL     string
+}
+
+// NewChannel creates a new Signal channel.
+func NewChannel(
+	log *logger.Logger,
+	cfg config.SignalConfig,
+	b bus.Bus,
+	cmdRegistry *commands.Registry,
+	transcriber transcription.Transcriber,
+) (*Channel, error) {
+	return NewAccountChannel(log, cfg, b, cmdRegistry, transcriber, "signal", "Signal")
+}
+
+// NewAccountChannel creates an account-scoped Signal channel instance.
+func NewAccountChannel(
+	log *logger.Logger,
+	cfg config.SignalConfig,
+	b bus.Bus,
+	cmdRegistry *commands.Registry,
+	transcriber transcription.Transcriber,
+	channelID string,
+	displayName string,
+) (*Channel, error) {
+	apiURL := strings.TrimRight(strings.TrimSpace(cfg.APIURL), "/")
+	if apiURL == "" || strings.TrimSpace(cfg.Number) == "" {
+		return nil, fmt.Errorf("signal api_url and number are required")
+	}
+
+	return &Channel{
+		log:         log,
+		config:      cfg,
+		bus:         b,
+		commands:    cmdRegistry,
+		id:          strings.TrimSpace(channelID),
+		channelType: "signal",
+		name:        defaultSignalName(displayName),
+		transcriber: transcriber,
+		httpClient: &http.Client{
+			// Long enough to outlast a receive request held open by signal-cli.
+			Timeout: receiveTimeout + 80*time.Second,
+		},
+		apiURL: apiURL,
+	}, nil
+}
+
+// ID returns the channel identifier.
+func (c *Channel) ID() string {
+	return c.id
+}
+
+// Name returns the channel name.
+func (c *Channel) Name() string {
+	return c.name
+}
+
+// ChannelType returns the stable Signal family key.
+func (c *Channel) ChannelType() string {
+	return c.channelType
+}
+
+// IsEnabled returns whether the channel is enabled.
+func (c *Channel) IsEnabled() bool {
+	return c.config.Enabled
+}
+
+// Start starts polling signal-cli for messages.
+func (c *Channel) Start(ctx context.Context) error {
+	c.log.Info("Starting Signal channel", zap.String("api_url", c.apiURL))
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+
+	go c.receiveLoop()
+
+	c.running = true
+	c.log.Info("Signal channel started", zap.String("number", c.config.Number))
+	return nil
+}
+
+// Stop stops the Signal channel.
+func (c *Channel) Stop(ctx context.Context) error {
+	c.log.Info("Stopping Signal channel")
+	c.running = false
+	if c.cancel != nil {
+		c.cancel()
+	}
+	if c.done != nil {
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (c *Channel) receiveLoop() {
+	defer close(c.done)
+
+	for c.ctx.Err() == nil {
+		envelopes, err := c.receive(c.ctx)
+		if err != nil {
+			if c.ctx.Err() != nil {
+				return
+			}
+			c.log.Warn("Signal receive failed", zap.Error(err))
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-time.After(receiveRetryDelay):
+			}
+			continue
+		}
+		for _, env := range envelopes {
+			c.handleEnvelope(env)
+		}
+	}
+}
+
+func (c *Channel) receive(ctx context.Context) ([]envelope, error) {
+	query := url.Values{}
+	query.Set("timeout", fmt.Sprintf("%d", int(receiveTimeout.Seconds())))
+	endpoint := c.apiURL + "/v1/receive/" + url.PathEscape(c.config.Number) + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating signal receive request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending signal receive request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("signal api status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var envelopes []envelope
+	if err := json.NewDecoder(resp.Body).Decode(&envelopes); err != nil {
+		return nil, fmt.Errorf("decoding signal receive response: %w", err)
+	}
+	return envelopes, nil
+}
+
+func (c *Channel) handleEnvelope(env envelope) {
+	data := env.Envelope.DataMessage
+	if data == nil {
+		return
+	}
+	sender := firstNonEmpty(env.Envelope.SourceNumber, env.Envelope.Source, env.Envelope.SourceUUID)
+	if sender == "" || sender == c.config.Number {
+		return
+	}
+	if !c.isAllowed(env.Envelope.SourceNumber, env.Envelope.SourceUUID, env.Envelope.Source) {
+		c.log.Warn("Unauthorized Signal sender", zap.String("user_id", sender))
+		return
+	}
+
+	content := strings.TrimSpace(data.Message)
+	msgType := bus.MessageTypeText
+	if content == "" && c.transcriber != nil {
+		if transcribed, ok := c.transcribeVoiceNote(data.Attachments); ok {
+			content = transcribed
+			msgType = bus.MessageTypeAudio
+		}
+	}
+	if content == "" {
+		return
+	}
+
+	recipient := sender
+	if data.GroupInfo != nil && data.GroupInfo.GroupID != "" {
+		recipient = groupRecipient(data.GroupInfo.GroupID)
+	}
+	timestamp := firstNonZero(data.Timestamp, env.Envelope.Timestamp)
+
+	msg := &bus.Message{
+		ID:        fmt.Sprintf("signal:%d", timestamp),
+		ChannelID: c.ID(),
+		SessionID: "signal:" + recipient,
+		UserID:    sender,
+		Username:  firstNonEmpty(env.Envelope.SourceName, sender),
+		Type:      msgType,
+		Content:   content,
+		Timestamp: time.UnixMilli(timestamp),
+		Data: map[string]interface{}{
+			"recipient":       recipient,
+			"quote_timestamp": timestamp,
+			"quote_author":    sender,
+			"quote_message":   content,
+		},
+	}
+	if err := c.bus.SendInbound(msg); err != nil {
+		c.log.Error("Failed to dispatch Signal message to bus", zap.Error(err))
+	}
+}
+
+// transcribeVoiceNote transcribes the first audio attachment that yields text.
+func (c *Channel) transcribeVoiceNote(attachments []attachment) (string, bool) {
+	for _, att := range attachments {
+		if att.ID == "" || !strings.HasPrefix(strings.ToLower(att.ContentType), "audio/") {
+			continue
+		}
+
+		audio, err := c.downloadAttachment(c.ctx, att.ID)
+		if err != nil {
+			c.log.Warn("Failed to download Signal audio", zap.Error(err))
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(c.ctx, 2*time.Minute)
+		text, err := c.transcriber.Transcribe(ctx, audio, firstNonEmpty(att.Filename, att.ID))
+		cancel()
+		if err != nil {
+			c.log.Warn("Signal audio transcription failed", zap.Error(err))
+			continue
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (c *Channel) downloadAttachment(ctx context.Context, id string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+"/v1/attachments/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("signal attachment status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, transcription.MaxAudioBytes))
+}
+
+// SendMessage sends a message to a Signal contact or group. Replies quote
+// the message they answer.
+func (c *Channel) SendMessage(ctx context.Context, msg *bus.Message) error {
+	if msg.Type == bus.MessageTypeStreamUpdate {
+		return nil
+	}
+
+	recipient, _ := msg.Data["recipient"].(string)
+	if recipient == "" {
+		recipient = strings.TrimPrefix(msg.SessionID, "signal:")
+	}
+	if recipient == "" || recipient == msg.SessionID {
+		return fmt.Errorf("invalid signal recipient for session %s", msg.SessionID)
+	}
+
+	payload := sendRequest{
+		Message:    channeltrace.PrependBusToolTrace(msg.Content, msg),
+		Number:     c.config.Number,
+		Recipients: []string{recipient},
+	}
+	if quoteTimestamp := metadataInt64(msg.Data, "quote_timestamp"); quoteTimestamp > 0 {
+		payload.QuoteTimestamp = quoteTimestamp
+		payload.QuoteAuthor, _ = msg.Data["quote_author"].(string)
+		payload.QuoteMessage, _ = msg.Data["quote_message"].(string)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling signal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/v2/send", bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("creating signal request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending signal request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("signal api status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// groupRecipient converts the internal group ID of a received message into
+// the recipient form signal-cli-rest-api expects when sending.
+func groupRecipient(internalID string) string {
+	return groupRecipientPrefix + base64.StdEncoding.EncodeToString([]byte(internalID))
+}
+
+// isAllowed reports whether any of the sender's identifiers is allowed.
+func (c *Channel) isAllowed(ids ...string) bool {
+	if len(c.config.AllowFrom) == 0 {
+		return true
+	}
+	for _, allowed := range c.config.AllowFrom {
+		if allowed == "*" {
+			return true
+		}
+		for _, id := range ids {
+			if id != "" && allowed == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// metadataInt64 reads an integer from message data, which may have been
+// decoded from JSON by a remote bus.
+func metadataInt64(data map[string]interface{}, key string) int64 {
+	switch v := data[key].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	default:
+		return 0
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func firstNonZero(values ...int64) int64 {
+	for _, value := range values {
+		if value != 0 {
+			return value
+		}
+	}
+	return time.Now().UnixMilli()
+}
+
+func defaultSignalName(displayName string) string {
+	name := strings.TrimSpace(displayName)
+	if name == "" {
+		return "Signal"
+	}
+	return name
+}
@@ -0,0 +1,191 @@
+package signal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+func TestHandleEnvelopeDispatchesAllowedMessages(t *testing.T) {
+	b := &stubBus{}
+	channel := newTestChannel(t, b, nil, config.SignalConfig{AllowFrom: []string{"+15550001111"}})
+
+	var envelopes []envelope
+	decodeJSON(t, `[
+		{"envelope":{"sourceNumber":"+15559999999","timestamp":1,"dataMessage":{"timestamp":1,"message":"hi"}}},
+		{"envelope":{"sourceNumber":"+15550001111","sourceName":"Alice","timestamp":2,"dataMessage":{"timestamp":2}}},
+		{"envelope":{"sourceNumber":"+15550001111","sourceName":"Alice","timestamp":3,"dataMessage":{"timestamp":3,"message":" hello ","groupInfo":{"groupId":"grp"}}}}
+	]`, &envelopes)
+	for _, env := range envelopes {
+		channel.handleEnvelope(env)
+	}
+
+	if len(b.inbound) != 1 {
+		t.Fatalf("expected one inbound message, got %d", len(b.inbound))
+	}
+	msg := b.inbound[0]
+	wantRecipient := "group." + "Z3Jw"
+	if msg.SessionID != "signal:"+wantRecipient || msg.Data["recipient"] != wantRecipient {
+		t.Fatalf("expected group session, got %q %+v", msg.SessionID, msg.Data)
+	}
+	if msg.UserID != "+15550001111" || msg.Username != "Alice" || msg.Content != "hello" {
+		t.Fatalf("unexpected inbound message %+v", msg)
+	}
+	if msg.Data["quote_timestamp"] != int64(3) {
+		t.Fatalf("expected quote timestamp of the source message, got %+v", msg.Data)
+	}
+}
+
+func TestHandleEnvelopeTranscribesVoiceNotes(t *testing.T) {
+	b := &stubBus{}
+	transcriber := &stubTranscriber{text: "what time is it"}
+	channel := newTestChannel(t, b, transcriber, config.SignalConfig{})
+	channel.httpClient = &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/v1/attachments/voice.aac" {
+				t.Fatalf("unexpected request %s", req.URL.Path)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("audio-bytes")),
+				Header:     make(http.Header),
+			}, nil
+		}),
+	}
+
+	var env envelope
+	decodeJSON(t, `{"envelope":{"sourceNumber":"+15550001111","timestamp":5,"dataMessage":{"timestamp":5,
+		"attachments":[{"id":"voice.aac","contentType":"audio/aac"}]}}}`, &env)
+	channel.handleEnvelope(env)
+
+	if len(b.inbound) != 1 {
+		t.Fatalf("expected transcribed message, got %d", len(b.inbound))
+	}
+	if msg := b.inbound[0]; msg.Type != bus.MessageTypeAudio || msg.Content != "what time is it" || msg.SessionID != "signal:+15550001111" {
+		t.Fatalf("unexpected inbound message %+v", msg)
+	}
+	if transcriber.audio != "audio-bytes" || transcriber.filename != "voice.aac" {
+		t.Fatalf("unexpected transcription input %q %q", transcriber.audio, transcriber.filename)
+	}
+}
+
+func TestSendMessageQuotesSourceMessage(t *testing.T) {
+	var payload sendRequest
+	channel := newTestChannel(t, &stubBus{}, nil, config.SignalConfig{})
+	channel.httpClient = &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodPost || req.URL.Path != "/v2/send" {
+				t.Fatalf("unexpected request %s %s", req.Method, req.URL.Path)
+			}
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode payload: %v", err)
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(strings.NewReader(`{"timestamp":"6"}`)),
+				Header:     make(http.Header),
+			}, nil
+		}),
+	}
+
+	err := channel.SendMessage(context.Background(), &bus.Message{
+		SessionID: "signal:+15550001111",
+		Content:   "noon",
+		Data: map[string]interface{}{
+			// A remote bus decodes numbers as float64.
+			"quote_timestamp": float64(5),
+			"quote_author":    "+15550001111",
+			"quote_message":   "what time is it",
+		},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if payload.Number != "+15550000000" || len(payload.Recipients) != 1 || payload.Recipients[0] != "+15550001111" {
+		t.Fatalf("unexpected addressing %+v", payload)
+	}
+	if payload.Message != "noon" || payload.QuoteTimestamp != 5 || payload.QuoteAuthor != "+15550001111" {
+		t.Fatalf("expected a quoted reply, got %+v", payload)
+	}
+}
+
+func newTestChannel(t *testing.T, b bus.Bus, transcriber *stubTranscriber, cfg config.SignalConfig) *Channel {
+	t.Helper()
+	cfg.Enabled = true
+	cfg.APIURL = "http://signal.invalid"
+	cfg.Number = "+15550000000"
+
+	channel, err := NewChannel(newTestLogger(t), cfg, b, nil, nil)
+	if err != nil {
+		t.Fatalf("NewChannel failed: %v", err)
+	}
+	if transcriber != nil {
+		channel.transcriber = transcriber
+	}
+	channel.ctx = context.Background()
+	return channel
+}
+
+func decodeJSON(t *testing.T, raw string, out interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(raw), out); err != nil {
+		t.Fatalf("decode fixture: %v", err)
+	}
+}
+
+type stubTranscriber struct {
+	text     string
+	audio    string
+	filename string
+}
+
+func (s *stubTranscriber) Transcribe(ctx context.Context, audio []byte, filename string) (string, error) {
+	s.audio = string(audio)
+	s.filename = filename
+	return s.text, nil
+}
+
+type stubBus struct {
+	inbound []*bus.Message
+}
+
+func (b *stubBus) Start() error                                                  { return nil }
+func (b *stubBus) Stop() error                                                   { return nil }
+func (b *stubBus) RegisterInboundHandler(channelID string, handler bus.Handler)  {}
+func (b *stubBus) UnregisterInboundHandlers(channelID string)                    {}
+func (b *stubBus) RegisterOutboundHandler(channelID string, handler bus.Handler) {}
+func (b *stubBus) UnregisterOutboundHandlers(channelID string)                   {}
+func (b *stubBus) RegisterHandler(channelID string, handler bus.Handler)         {}
+func (b *stubBus) UnregisterHandlers(channelID string)                           {}
+func (b *stubBus) SendInbound(msg *bus.Message) error {
+	b.inbound = append(b.inbound, msg)
+	return nil
+}
+func (b *stubBus) SendOutbound(msg *bus.Message) error { return nil }
+func (b *stubBus) GetMetrics() map[string]uint64       { return map[string]uint64{} }
+
+func newTestLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+
+	cfg := logger.DefaultConfig()
+	cfg.OutputPath = ""
+	cfg.Development = true
+	log, err := logger.New(cfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return log
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
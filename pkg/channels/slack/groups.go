@@ -0,0 +1,96 @@
+package slack
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// engagedThreadTTL is how long a thread keeps getting replies in mention
+	// mode after the bot was last mentioned or answered in it.
+	engagedThreadTTL = 24 * time.Hour
+	// maxEngagedThreads bounds the engaged thread index.
+	maxEngagedThreads = 1024
+)
+
+// engagedThreads remembers the threads the bot takes part in, so thread
+// replies count as replies to the bot in mention mode.
+type engagedThreads struct {
+	mu      sync.Mutex
+	threads map[string]time.Time
+}
+
+func (e *engagedThreads) mark(channelID, threadTS string) {
+	if channelID == "" || threadTS == "" {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.threads == nil {
+		e.threads = make(map[string]time.Time)
+	}
+	now := time.Now()
+	e.threads[channelID+":"+threadTS] = now
+	if len(e.threads) <= maxEngagedThreads {
+		return
+	}
+	var oldestKey string
+	var oldest time.Time
+	for key, seen := range e.threads {
+		if now.Sub(seen) > engagedThreadTTL {
+			delete(e.threads, key)
+			continue
+		}
+		if oldestKey == "" || seen.Before(oldest) {
+			oldestKey, oldest = key, seen
+		}
+	}
+	if len(e.threads) > maxEngagedThreads {
+		delete(e.threads, oldestKey)
+	}
+}
+
+func (e *engagedThreads) engaged(channelID, threadTS string) bool {
+	if threadTS == "" {
+		return false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	seen, ok := e.threads[channelID+":"+threadTS]
+	return ok && time.Since(seen) <= engagedThreadTTL
+}
+
+// isGroupChannelType reports whether a message event comes from a channel,
+// private channel or multi-person DM rather than a direct message.
+func isGroupChannelType(channelType string) bool {
+	switch channelType {
+	case "channel", "group", "mpim":
+		return true
+	}
+	return false
+}
+
+// chatSessionID returns the session of a slash command or button press by
+// userID, the way messages in the same place are routed. Slash commands
+// are never threaded; direct message channel IDs start with "D".
+func (c *Channel) chatSessionID(channelID, threadTS, userID string) string {
+	if strings.HasPrefix(channelID, "D") {
+		return c.sessionThreadID(channelID, threadTS)
+	}
+	return c.groupSessionID(channelID, threadTS, userID)
+}
+
+func (c *Channel) mentionOnly() bool {
+	return strings.TrimSpace(c.config.GroupMode) == "mention"
+}
+
+// groupSessionID returns the session of a channel message: one per thread,
+// followed by ":<user>" when session_scope is "user".
+func (c *Channel) groupSessionID(channelID, threadTS, userID string) string {
+	sessionID := c.sessionThreadID(channelID, threadTS)
+	if strings.TrimSpace(c.config.SessionScope) == "user" && userID != "" {
+		sessionID += ":" + userID
+	}
+	return sessionID
+}
@@ -2,6 +2,7 @@
 package slack
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -16,6 +17,7 @@ import (
 	"github.com/slack-go/slack/socketmode"
 	"go.uber.org/zap"
 
+	"nekobot/pkg/approval"
 	"nekobot/pkg/bus"
 	"nekobot/pkg/channeltrace"
 	"nekobot/pkg/commands"
@@ -31,6 +33,7 @@ type slackAPI interface {
 	PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error)
 	OpenView(triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error)
 	UpdateMessage(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error)
 }
 
 // Channel implements Slack channel using Socket Mode.
@@ -52,6 +55,12 @@ type Channel struct {
 	name                 string
 	pendingSkillMu       sync.Mutex
 	pendingSkillInstalls map[string]pendingSkillInstall
+
+	// approvals decides tool approval prompts from their buttons.
+	approvals *approval.Manager
+
+	// threads tracks the threads the bot takes part in for mention mode.
+	threads engagedThreads
 }
 
 type pendingSkillInstall struct {
@@ -147,6 +156,12 @@ func NewAccountChannel(
 	}, nil
 }
 
+// SetApprovals enables approve and deny buttons on tool approval prompts,
+// decided through mgr.
+func (c *Channel) SetApprovals(mgr *approval.Manager) {
+	c.approvals = mgr
+}
+
 // Start starts the Slack bot.
 func (c *Channel) Start(ctx context.Context) error {
 	c.log.Info("Starting Slack channel (Socket Mode)")
@@ -287,6 +302,19 @@ func (c *Channel) handleMessageEvent(ev *slackevents.MessageEvent) {
 		return
 	}
 
+	group := isGroupChannelType(ev.ChannelType)
+	if group && c.mentionOnly() {
+		// Mentions arrive as app_mention events too and are handled there.
+		if c.botUserID != "" && strings.Contains(ev.Text, fmt.Sprintf("<@%s>", c.botUserID)) {
+			return
+		}
+		if !c.threads.engaged(ev.Channel, ev.ThreadTimeStamp) {
+			c.log.Debug("Ignoring Slack channel message not addressed to the bot",
+				zap.String("channel_id", ev.Channel))
+			return
+		}
+	}
+
 	content := strings.TrimSpace(ev.Text)
 	msgType := bus.MessageTypeText
 
@@ -301,9 +329,9 @@ func (c *Channel) handleMessageEvent(ev *slackevents.MessageEvent) {
 	}
 
 	// Determine chat ID (channel_id or channel_id:thread_ts)
-	sessionID := c.sessionID(ev.Channel)
-	if ev.ThreadTimeStamp != "" {
-		sessionID = c.sessionThreadID(ev.Channel, ev.ThreadTimeStamp)
+	sessionID := c.sessionThreadID(ev.Channel, ev.ThreadTimeStamp)
+	if group {
+		sessionID = c.groupSessionID(ev.Channel, ev.ThreadTimeStamp, ev.User)
 	}
 
 	// Create inbound message
@@ -333,12 +361,16 @@ func (c *Channel) handleAppMentionEvent(ev *slackevents.AppMentionEvent) {
 		return
 	}
 
-	// Determine session ID
-	sessionID := c.sessionID(ev.Channel)
+	// Replies in the thread of a mention count as addressed to the bot.
 	if ev.ThreadTimeStamp != "" {
-		sessionID = c.sessionThreadID(ev.Channel, ev.ThreadTimeStamp)
+		c.threads.mark(ev.Channel, ev.ThreadTimeStamp)
+	} else {
+		c.threads.mark(ev.Channel, ev.TimeStamp)
 	}
 
+	// Determine session ID
+	sessionID := c.groupSessionID(ev.Channel, ev.ThreadTimeStamp, ev.User)
+
 	// Remove bot mention from text
 	text := strings.TrimSpace(strings.Replace(ev.Text, fmt.Sprintf("<@%s>", c.botUserID), "", 1))
 
@@ -430,6 +462,7 @@ func (c *Channel) handleSlashCommand(evt socketmode.Event) {
 			"team_domain":  cmd.TeamDomain,
 			"trigger_id":   cmd.TriggerID,
 			"runtime_id":   c.ID(),
+			"session_id":   c.chatSessionID(cmd.ChannelID, "", cmd.UserID),
 		},
 	}
 
@@ -518,6 +551,8 @@ func (c *Channel) handleBlockActions(callback slack.InteractionCallback) {
 			c.handleSkillInstallConfirm(callback, repo)
 		case action.ActionID == "skill_install_cancel":
 			c.handleSkillInstallCancel(callback)
+		case strings.HasPrefix(action.ActionID, approval.CallbackPrefix):
+			c.handleApprovalAction(callback, action.ActionID)
 		default:
 			c.log.Debug("Unknown action", zap.String("action_id", action.ActionID))
 		}
@@ -579,6 +614,74 @@ func (c *Channel) handleSkillInstallCancel(callback slack.InteractionCallback) {
 	c.updateInteractionMessage(pending, "Installation cancelled.")
 }
 
+// approvalBlocks renders a tool approval prompt with approve and deny
+// buttons.
+func approvalBlocks(content, requestID string) []slack.Block {
+	approveButton := slack.NewButtonBlockElement(
+		approval.CallbackData(approval.Approved, requestID),
+		requestID,
+		slack.NewTextBlockObject(slack.PlainTextType, "Approve", false, false),
+	).WithStyle(slack.StylePrimary)
+	denyButton := slack.NewButtonBlockElement(
+		approval.CallbackData(approval.Denied, requestID),
+		requestID,
+		slack.NewTextBlockObject(slack.PlainTextType, "Deny", false, false),
+	).WithStyle(slack.StyleDanger)
+	return []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, content, false, false), nil, nil),
+		slack.NewActionBlock("approval_actions:"+requestID, approveButton, denyButton),
+	}
+}
+
+// handleApprovalAction applies an approve or deny press to the pending tool
+// approval and replaces the buttons with the outcome.
+func (c *Channel) handleApprovalAction(callback slack.InteractionCallback, actionID string) {
+	reply := func(text string) {
+		if _, err := c.api.PostEphemeral(callback.Channel.ID, callback.User.ID, slack.MsgOptionText(text, false)); err != nil {
+			c.log.Error("Failed to send Slack approval message", zap.Error(err))
+		}
+	}
+	if !c.isAllowed(callback.User.ID) {
+		reply("You are not allowed to decide approvals.")
+		return
+	}
+	decision, requestID, ok := approval.ParseCallback(actionID)
+	if !ok || c.approvals == nil {
+		reply("Approvals are unavailable.")
+		return
+	}
+	// Buttons only decide prompts asked in this chat; action IDs are
+	// client-supplied, so a forged ID must not reach other requests.
+	sessionID := c.chatSessionID(callback.Channel.ID, callback.Message.ThreadTimestamp, callback.User.ID)
+	if !c.approvals.PromptedIn(requestID, c.ID(), sessionID) {
+		reply("No approval prompt " + requestID + " is waiting in this chat.")
+		return
+	}
+
+	actor := c.ID() + ":" + callback.User.ID
+	if callback.User.Name != "" {
+		actor = c.ID() + ":" + callback.User.Name
+	}
+	var err error
+	if decision == approval.Approved {
+		err = c.approvals.ApproveAs(requestID, actor)
+	} else {
+		err = c.approvals.DenyAs(requestID, actor, "denied from Slack")
+	}
+	if err != nil {
+		reply(err.Error())
+		return
+	}
+
+	text := callback.Message.Text + "\n\n" + approval.DecisionText(decision, actor)
+	if _, _, _, err := c.api.UpdateMessage(callback.Channel.ID, c.interactionMessageTS(callback),
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionBlocks(slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil)),
+	); err != nil {
+		c.log.Warn("Failed to update Slack approval prompt", zap.Error(err))
+	}
+}
+
 func (c *Channel) handleShortcut(callback slack.InteractionCallback) {
 	c.log.Debug("Shortcut interaction received",
 		zap.String("callback_id", callback.CallbackID),
@@ -1519,21 +1622,32 @@ func (c *Channel) SendMessage(ctx context.Context, msg *bus.Message) error {
 	if channelID == "" {
 		return fmt.Errorf("invalid session ID: %s", msg.SessionID)
 	}
+	if len(msg.Attachments) > 0 {
+		return c.SendAttachments(ctx, msg.SessionID, msg.Content, msg.Attachments)
+	}
 
 	// Build message options
 	opts := []slack.MsgOption{
 		slack.MsgOptionText(prependBusToolTrace(msg.Content, msg), false),
 	}
+	if requestID, ok := msg.Data[approval.DataKeyRequestID].(string); ok && requestID != "" && c.approvals != nil {
+		opts = append(opts, slack.MsgOptionBlocks(approvalBlocks(msg.Content, requestID)...))
+	}
 
 	if threadTS != "" {
 		opts = append(opts, slack.MsgOptionTS(threadTS))
 	}
 
 	// Send message
-	_, _, err := c.api.PostMessageContext(ctx, channelID, opts...)
+	_, ts, err := c.api.PostMessageContext(ctx, channelID, opts...)
 	if err != nil {
 		return fmt.Errorf("sending slack message: %w", err)
 	}
+	if threadTS != "" {
+		c.threads.mark(channelID, threadTS)
+	} else {
+		c.threads.mark(channelID, ts)
+	}
 
 	c.log.Debug("Sent Slack message",
 		zap.String("channel_id", channelID),
@@ -1542,6 +1656,47 @@ func (c *Channel) SendMessage(ctx context.Context, msg *bus.Message) error {
 	return nil
 }
 
+// SendAttachments uploads attachments to the channel or thread, with caption
+// as the comment on the first file. Attachments only known by URL are posted
+// as links.
+func (c *Channel) SendAttachments(ctx context.Context, sessionID, caption string, attachments []bus.Attachment) error {
+	channelID, threadTS := c.parseSessionID(sessionID)
+	if channelID == "" {
+		return fmt.Errorf("invalid session ID: %s", sessionID)
+	}
+	for i, attachment := range attachments {
+		if i > 0 {
+			caption = ""
+		}
+		data, err := attachment.ReadData()
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			opts := []slack.MsgOption{slack.MsgOptionText(strings.TrimSpace(caption+"\n"+attachment.URL), false)}
+			if threadTS != "" {
+				opts = append(opts, slack.MsgOptionTS(threadTS))
+			}
+			if _, _, err := c.api.PostMessageContext(ctx, channelID, opts...); err != nil {
+				return fmt.Errorf("sending slack message: %w", err)
+			}
+			continue
+		}
+		if _, err := c.api.UploadFileV2Context(ctx, slack.UploadFileV2Parameters{
+			Reader:          bytes.NewReader(data),
+			FileSize:        len(data),
+			Filename:        attachment.FileName(),
+			Title:           attachment.FileName(),
+			InitialComment:  caption,
+			Channel:         channelID,
+			ThreadTimestamp: threadTS,
+		}); err != nil {
+			return fmt.Errorf("uploading slack file %s: %w", attachment.FileName(), err)
+		}
+	}
+	return nil
+}
+
 func prependBusToolTrace(content string, msg *bus.Message) string {
 	return channeltrace.PrependBusToolTrace(content, msg)
 }
@@ -1569,19 +1724,22 @@ func (c *Channel) parseSessionID(sessionID string) (string, string) {
 				}
 			}
 			if matches {
-				channelID := parts[len(instanceParts)]
-				if len(parts) > len(instanceParts)+1 {
-					return channelID, strings.Join(parts[len(instanceParts)+1:], ":")
-				}
-				return channelID, ""
+				return parts[len(instanceParts)], sessionThreadTS(parts[len(instanceParts)+1:])
 			}
 		}
 	}
 
-	if len(parts) > 2 {
-		return parts[1], strings.Join(parts[2:], ":")
+	return parts[1], sessionThreadTS(parts[2:])
+}
+
+// sessionThreadTS returns the thread timestamp among the session ID parts
+// after the channel. A trailing user ID from per-member sessions is skipped;
+// Slack timestamps always contain a dot.
+func sessionThreadTS(parts []string) string {
+	if len(parts) == 0 || !strings.Contains(parts[0], ".") {
+		return ""
 	}
-	return parts[1], ""
+	return parts[0]
 }
 
 func (c *Channel) sessionID(channelID string) string {
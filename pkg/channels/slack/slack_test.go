@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -14,6 +16,7 @@ import (
 	"github.com/slack-go/slack/slackevents"
 	"github.com/slack-go/slack/socketmode"
 
+	"nekobot/pkg/approval"
 	"nekobot/pkg/bus"
 	"nekobot/pkg/commands"
 	"nekobot/pkg/config"
@@ -39,6 +42,8 @@ type stubSlackAPI struct {
 	ephemeralUser    string
 	ephemeralOpts    []slackapi.MsgOption
 	ephemeralErr     error
+
+	uploads []slackapi.UploadFileV2Parameters
 }
 
 func (s *stubSlackAPI) AuthTest() (*slackapi.AuthTestResponse, error) {
@@ -82,6 +87,11 @@ func (s *stubSlackAPI) UpdateMessage(channelID, timestamp string, options ...sla
 	return channelID, timestamp, "", s.updateMessageErr
 }
 
+func (s *stubSlackAPI) UploadFileV2Context(ctx context.Context, params slackapi.UploadFileV2Parameters) (*slackapi.FileSummary, error) {
+	s.uploads = append(s.uploads, params)
+	return &slackapi.FileSummary{}, nil
+}
+
 type stubBus struct {
 	inbound []*bus.Message
 }
@@ -247,6 +257,77 @@ func TestHandleSkillInstallCancelUpdatesMessage(t *testing.T) {
 	}
 }
 
+func TestHandleBlockActionsDecidesApprovalPrompt(t *testing.T) {
+	ch := newTestChannel(t)
+	api := &stubSlackAPI{}
+	ch.api = api
+	mgr := approval.NewManager(approval.Config{Mode: approval.ModeManual})
+	ch.SetApprovals(mgr)
+
+	requestID, err := mgr.EnqueuePrompt("exec", map[string]interface{}{"command": "ls"}, "s1", "slack", "slack:C123")
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if err := ch.SendMessage(context.Background(), &bus.Message{
+		SessionID: "slack:C123",
+		Content:   "approve?",
+		Data:      map[string]interface{}{approval.DataKeyRequestID: requestID},
+	}); err != nil {
+		t.Fatalf("send prompt: %v", err)
+	}
+	if len(api.postMessageOpts) != 2 {
+		t.Fatalf("expected text and blocks options on the prompt, got %d", len(api.postMessageOpts))
+	}
+
+	callback := slackapi.InteractionCallback{
+		User: slackapi.User{ID: "U123", Name: "alice"},
+		Channel: slackapi.Channel{
+			GroupConversation: slackapi.GroupConversation{
+				Conversation: slackapi.Conversation{ID: "C123"},
+			},
+		},
+		Message: slackapi.Message{Msg: slackapi.Msg{Timestamp: "1710000000.000100", Text: "approve?"}},
+		ActionCallback: slackapi.ActionCallbacks{
+			BlockActions: []*slackapi.BlockAction{{ActionID: approval.CallbackData(approval.Approved, requestID)}},
+		},
+	}
+	// A forged button for a request queued elsewhere is refused.
+	webuiID, err := mgr.EnqueueRequest("tool_session_spawn", map[string]interface{}{"command": "bash"}, "")
+	if err != nil {
+		t.Fatalf("enqueue webui request: %v", err)
+	}
+	otherChatID, err := mgr.EnqueuePrompt("exec", map[string]interface{}{"command": "id"}, "s2", "slack", "slack:C999")
+	if err != nil {
+		t.Fatalf("enqueue other chat prompt: %v", err)
+	}
+	for _, id := range []string{webuiID, otherChatID} {
+		forged := callback
+		forged.ActionCallback = slackapi.ActionCallbacks{
+			BlockActions: []*slackapi.BlockAction{{ActionID: approval.CallbackData(approval.Approved, id)}},
+		}
+		api.ephemeralUser = ""
+		ch.handleBlockActions(forged)
+		if req, _ := mgr.GetRequest(id); req.Decision != approval.Pending || api.ephemeralUser != "U123" {
+			t.Fatalf("expected forged press on %s to be refused, got %+v", id, req)
+		}
+	}
+
+	ch.handleBlockActions(callback)
+
+	req, ok := mgr.GetRequest(requestID)
+	if !ok || req.Decision != approval.Approved || req.DecidedBy != "slack:alice" {
+		t.Fatalf("expected request approved by slack:alice, got %+v", req)
+	}
+	if api.updateMessageTS != "1710000000.000100" {
+		t.Fatalf("expected the prompt to be updated, got %q", api.updateMessageTS)
+	}
+
+	ch.handleBlockActions(callback)
+	if api.ephemeralUser != "U123" {
+		t.Fatal("expected a second press to be refused with an ephemeral message")
+	}
+}
+
 func TestPendingSkillInstallExpires(t *testing.T) {
 	ch := newTestChannel(t)
 	ch.setPendingSkillInstall("1710000000.000100", pendingSkillInstall{
@@ -449,6 +530,33 @@ func TestSendMessagePrependsToolTraceFromBusMetadata(t *testing.T) {
 	}
 }
 
+func TestSendMessageUploadsAttachmentsToThread(t *testing.T) {
+	ch := newTestChannel(t)
+	api := &stubSlackAPI{}
+	ch.api = api
+
+	path := filepath.Join(t.TempDir(), "report.pdf")
+	if err := os.WriteFile(path, []byte("%PDF"), 0o644); err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+	err := ch.SendMessage(context.Background(), &bus.Message{
+		SessionID:   "slack:C123:1710000000.000100",
+		Content:     "weekly report",
+		Attachments: []bus.Attachment{bus.FileAttachment(path)},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if len(api.uploads) != 1 {
+		t.Fatalf("expected one upload, got %d", len(api.uploads))
+	}
+	upload := api.uploads[0]
+	if upload.Channel != "C123" || upload.ThreadTimestamp != "1710000000.000100" ||
+		upload.Filename != "report.pdf" || upload.InitialComment != "weekly report" || upload.FileSize != 4 {
+		t.Fatalf("unexpected upload %+v", upload)
+	}
+}
+
 func TestHandleViewSubmissionExecutesFindSkillsCommand(t *testing.T) {
 	ch := newTestChannel(t)
 	api := &stubSlackAPI{}
@@ -1147,6 +1255,7 @@ func TestHandleSlashCommandRejectsAdminOnlyCommandForNonAdmin(t *testing.T) {
 
 type slackapieventsMessageEvent struct {
 	Channel         string
+	ChannelType     string
 	ThreadTimeStamp string
 	User            string
 	Text            string
@@ -1156,9 +1265,74 @@ type slackapieventsMessageEvent struct {
 func (e *slackapieventsMessageEvent) toSlack() *slackevents.MessageEvent {
 	return &slackevents.MessageEvent{
 		Channel:         e.Channel,
+		ChannelType:     e.ChannelType,
 		ThreadTimeStamp: e.ThreadTimeStamp,
 		User:            e.User,
 		Text:            e.Text,
 		TimeStamp:       e.TimeStamp,
 	}
 }
+
+func TestMentionModeFollowsEngagedThreadsWithPerMemberSessions(t *testing.T) {
+	ch := newTestChannel(t)
+	ch.config = config.SlackConfig{GroupMode: "mention", SessionScope: "user"}
+	ch.botUserID = "UBOT"
+	api := &stubSlackAPI{postMessageTS: "1710000000.000900"}
+	ch.api = api
+	b := &stubBus{}
+	ch.bus = b
+
+	// Channel chatter and the message copy of a mention are ignored.
+	ch.handleMessageEvent((&slackapieventsMessageEvent{
+		Channel: "C123", ChannelType: "channel", User: "U1", Text: "lunch anyone?", TimeStamp: "1710000000.000100",
+	}).toSlack())
+	ch.handleMessageEvent((&slackapieventsMessageEvent{
+		Channel: "C123", ChannelType: "channel", User: "U1", Text: "<@UBOT> status?", TimeStamp: "1710000000.000200",
+	}).toSlack())
+	if len(b.inbound) != 0 {
+		t.Fatalf("expected channel messages to be ignored, got %d", len(b.inbound))
+	}
+
+	ch.handleAppMentionEvent(&slackevents.AppMentionEvent{
+		Channel: "C123", User: "U1", Text: "<@UBOT> status?", TimeStamp: "1710000000.000200",
+	})
+	// A reply in the thread under the mention continues without a mention.
+	ch.handleMessageEvent((&slackapieventsMessageEvent{
+		Channel: "C123", ChannelType: "channel", ThreadTimeStamp: "1710000000.000200", User: "U2", Text: "and disk?", TimeStamp: "1710000000.000300",
+	}).toSlack())
+	// Direct messages are never filtered.
+	ch.handleMessageEvent((&slackapieventsMessageEvent{
+		Channel: "D123", ChannelType: "im", User: "U1", Text: "hello", TimeStamp: "1710000000.000400",
+	}).toSlack())
+
+	if len(b.inbound) != 3 {
+		t.Fatalf("expected mention, thread reply and DM, got %d messages", len(b.inbound))
+	}
+	for i, want := range []struct{ session, content string }{
+		{"slack:C123:U1", "status?"},
+		{"slack:C123:1710000000.000200:U2", "and disk?"},
+		{"slack:D123", "hello"},
+	} {
+		if got := b.inbound[i]; got.SessionID != want.session || got.Content != want.content {
+			t.Fatalf("message %d: expected %s %q, got %s %q", i, want.session, want.content, got.SessionID, got.Content)
+		}
+	}
+
+	if channelID, threadTS := ch.parseSessionID("slack:C123:1710000000.000200:U2"); channelID != "C123" || threadTS != "1710000000.000200" {
+		t.Fatalf("unexpected parsed thread session: channel=%q thread=%q", channelID, threadTS)
+	}
+	if channelID, threadTS := ch.parseSessionID("slack:C123:U1"); channelID != "C123" || threadTS != "" {
+		t.Fatalf("unexpected parsed member session: channel=%q thread=%q", channelID, threadTS)
+	}
+
+	// Threads under the bot's own replies are engaged too.
+	if err := ch.SendMessage(context.Background(), &bus.Message{SessionID: "slack:C123:U1", Content: "all good"}); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	ch.handleMessageEvent((&slackapieventsMessageEvent{
+		Channel: "C123", ChannelType: "channel", ThreadTimeStamp: "1710000000.000900", User: "U1", Text: "thanks", TimeStamp: "1710000000.001000",
+	}).toSlack())
+	if len(b.inbound) != 4 || b.inbound[3].SessionID != "slack:C123:1710000000.000900:U1" {
+		t.Fatalf("expected reply under the bot message to be routed, got %d messages", len(b.inbound))
+	}
+}
@@ -0,0 +1,199 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxTrackedTopics bounds the message-to-topic index; older entries are
+// dropped first.
+const maxTrackedTopics = 2048
+
+// topicIndex remembers the forum topic of recently received messages.
+// telegram-bot-api v5.5.1 predates forum topics and drops message_thread_id
+// when decoding updates, so topicTransport records it from the raw
+// getUpdates responses instead.
+type topicIndex struct {
+	mu     sync.Mutex
+	topics map[string]int
+	order  []string
+}
+
+func topicIndexKey(chatID int64, messageID int) string {
+	return fmt.Sprintf("%d:%d", chatID, messageID)
+}
+
+func (t *topicIndex) set(chatID int64, messageID, topicID int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.topics == nil {
+		t.topics = make(map[string]int)
+	}
+	key := topicIndexKey(chatID, messageID)
+	if _, ok := t.topics[key]; !ok {
+		t.order = append(t.order, key)
+	}
+	t.topics[key] = topicID
+	for len(t.order) > maxTrackedTopics {
+		delete(t.topics, t.order[0])
+		t.order = t.order[1:]
+	}
+}
+
+// get returns the topic of a message, or 0 outside forum topics.
+func (t *topicIndex) get(chatID int64, messageID int) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.topics[topicIndexKey(chatID, messageID)]
+}
+
+type rawTopicMessage struct {
+	MessageID       int  `json:"message_id"`
+	MessageThreadID int  `json:"message_thread_id"`
+	IsTopicMessage  bool `json:"is_topic_message"`
+	Chat            struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+}
+
+// record indexes the topic messages of a getUpdates response body.
+func (t *topicIndex) record(body []byte) {
+	var resp struct {
+		Result []struct {
+			Message       *rawTopicMessage `json:"message"`
+			EditedMessage *rawTopicMessage `json:"edited_message"`
+			CallbackQuery *struct {
+				Message *rawTopicMessage `json:"message"`
+			} `json:"callback_query"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return
+	}
+	for _, update := range resp.Result {
+		messages := []*rawTopicMessage{update.Message, update.EditedMessage}
+		if update.CallbackQuery != nil {
+			messages = append(messages, update.CallbackQuery.Message)
+		}
+		for _, msg := range messages {
+			if msg != nil && msg.IsTopicMessage && msg.MessageThreadID != 0 {
+				t.set(msg.Chat.ID, msg.MessageID, msg.MessageThreadID)
+			}
+		}
+	}
+}
+
+// topicTransport feeds getUpdates responses into a topicIndex.
+type topicTransport struct {
+	base   http.RoundTripper
+	topics *topicIndex
+}
+
+func (t *topicTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil || !strings.HasSuffix(req.URL.Path, "/getUpdates") {
+		return resp, err
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		return nil, readErr
+	}
+	t.topics.record(body)
+	return resp, nil
+}
+
+// messageSessionID returns the session of an inbound message. With
+// topic_sessions on, each forum topic has its own session.
+func (c *Channel) messageSessionID(message *tgbotapi.Message) string {
+	var userID int64
+	if message.From != nil {
+		userID = message.From.ID
+	}
+	return c.topicSessionID(message.Chat.ID, c.messageTopic(message.Chat.ID, message.MessageID), userID)
+}
+
+// commandSessionID returns the session a command in message applies to.
+// Private chats of a linked account use the linked identity's session, as
+// their messages do.
+func (c *Channel) commandSessionID(ctx context.Context, message *tgbotapi.Message) string {
+	sessionID := c.messageSessionID(message)
+	if c.prefs == nil || message.From == nil || !message.Chat.IsPrivate() {
+		return sessionID
+	}
+	return c.prefs.ResolveSessionID(ctx, c.ID(), fmt.Sprintf("%d", message.From.ID), sessionID)
+}
+
+func (c *Channel) messageTopic(chatID int64, messageID int) int {
+	if c.config == nil || !c.config.TopicSessions {
+		return 0
+	}
+	return c.topics.get(chatID, messageID)
+}
+
+// topicSessionID scopes sessionID to a forum topic: "<id>:<chat>:topic-<topic>",
+// followed by ":<user>" when group sessions are per member. The chat stays
+// the second segment so replies still go to <chat>.
+func (c *Channel) topicSessionID(chatID int64, topicID int, userID int64) string {
+	if topicID == 0 || chatTypeForChatID(chatID) != "group" {
+		return c.sessionID(chatID, userID)
+	}
+	sessionID := fmt.Sprintf("%s:%d:topic-%d", c.ID(), chatID, topicID)
+	if c.perUserGroupSessions() && userID != 0 {
+		sessionID += fmt.Sprintf(":%d", userID)
+	}
+	return sessionID
+}
+
+func (c *Channel) mentionOnly() bool {
+	return c.config != nil && strings.TrimSpace(c.config.GroupMode) == "mention"
+}
+
+// addressedToBot reports whether a group message @mentions the bot or
+// replies to one of its messages, and returns content without the mention.
+func (c *Channel) addressedToBot(message *tgbotapi.Message, content string) (bool, string) {
+	if c.bot == nil {
+		return false, content
+	}
+	self := c.bot.Self
+	if message.ReplyToMessage != nil && message.ReplyToMessage.From != nil && message.ReplyToMessage.From.ID == self.ID {
+		return true, content
+	}
+
+	text, entities := message.Text, message.Entities
+	if text == "" {
+		text, entities = message.Caption, message.CaptionEntities
+	}
+	encoded := utf16.Encode([]rune(text))
+	for _, entity := range entities {
+		switch entity.Type {
+		case "text_mention":
+			if entity.User != nil && entity.User.ID == self.ID {
+				return true, content
+			}
+		case "mention":
+			if entity.Offset < 0 || entity.Offset+entity.Length > len(encoded) {
+				continue
+			}
+			mention := string(utf16.Decode(encoded[entity.Offset : entity.Offset+entity.Length]))
+			if self.UserName != "" && strings.EqualFold(mention, "@"+self.UserName) {
+				return true, strings.TrimSpace(strings.Replace(content, mention, "", 1))
+			}
+		}
+	}
+	return false, content
+}
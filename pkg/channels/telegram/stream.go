@@ -0,0 +1,146 @@
+package telegram
+
+import (
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+)
+
+// telegramStreamEditInterval is the minimum gap between edits of a streamed
+// reply; Telegram rate-limits bots that edit one message more often.
+const telegramStreamEditInterval = time.Second
+
+// replyStream batches partial reply text into edits of the thinking message.
+// Only the latest text is kept, so updates arriving faster than the edit
+// interval collapse into one edit.
+type replyStream struct {
+	interval time.Duration
+	edit     func(text string) error
+
+	mu       sync.Mutex
+	pending  string
+	shown    string
+	lastEdit time.Time
+	timer    *time.Timer
+	done     bool
+
+	// sendMu serializes edits so finish can wait for one in flight.
+	sendMu sync.Mutex
+}
+
+func newReplyStream(interval time.Duration, edit func(text string) error) *replyStream {
+	return &replyStream{interval: interval, edit: edit}
+}
+
+// update records text and schedules an edit if none is pending.
+func (s *replyStream) update(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return
+	}
+	s.pending = truncateStreamText(text)
+	if s.timer != nil {
+		return
+	}
+	wait := s.interval - time.Since(s.lastEdit)
+	if wait < 0 {
+		wait = 0
+	}
+	s.timer = time.AfterFunc(wait, s.flush)
+}
+
+func (s *replyStream) flush() {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	s.mu.Lock()
+	s.timer = nil
+	if s.done || s.pending == s.shown {
+		s.mu.Unlock()
+		return
+	}
+	text := s.pending
+	s.mu.Unlock()
+
+	err := s.edit(text)
+
+	s.mu.Lock()
+	s.lastEdit = time.Now()
+	if err == nil {
+		s.shown = text
+	}
+	s.mu.Unlock()
+}
+
+// finish stops further edits, waits for one in flight and returns the text
+// currently shown.
+func (s *replyStream) finish() string {
+	s.mu.Lock()
+	s.done = true
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.mu.Unlock()
+
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.shown
+}
+
+// truncateStreamText keeps partial text within a single message; the final
+// reply is split normally once complete.
+func truncateStreamText(text string) string {
+	runes := []rune(text)
+	if len(runes) <= telegramMaxMessageChars {
+		return text
+	}
+	return string(runes[:telegramMaxMessageChars-1]) + "…"
+}
+
+// updateReplyStream forwards partial reply text to the stream editing
+// thinkingMsgID, starting one on the first update.
+func (c *Channel) updateReplyStream(chatID int64, thinkingMsgID int, text string) {
+	key := replyKey(chatID, thinkingMsgID)
+	c.streamsMu.Lock()
+	if c.streams == nil {
+		c.streams = map[string]*replyStream{}
+	}
+	stream, ok := c.streams[key]
+	if !ok {
+		interval := c.streamEditInterval
+		if interval <= 0 {
+			interval = telegramStreamEditInterval
+		}
+		stream = newReplyStream(interval, func(text string) error {
+			_, err := c.bot.Send(tgbotapi.NewEditMessageText(chatID, thinkingMsgID, text))
+			if err != nil {
+				c.log.Debug("Failed to edit streamed Telegram reply", zap.Error(err))
+			}
+			return err
+		})
+		c.streams[key] = stream
+	}
+	c.streamsMu.Unlock()
+
+	stream.update(text)
+}
+
+// finishReplyStream ends the stream for thinkingMsgID and returns the text it
+// last showed, or "" when nothing was streamed.
+func (c *Channel) finishReplyStream(chatID int64, thinkingMsgID int) string {
+	key := replyKey(chatID, thinkingMsgID)
+	c.streamsMu.Lock()
+	stream, ok := c.streams[key]
+	delete(c.streams, key)
+	c.streamsMu.Unlock()
+	if !ok {
+		return ""
+	}
+	return stream.finish()
+}
@@ -3,11 +3,14 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -16,13 +19,17 @@ import (
 	"go.uber.org/zap"
 
 	"nekobot/pkg/agent"
+	"nekobot/pkg/approval"
 	"nekobot/pkg/bus"
 	channelcapabilities "nekobot/pkg/channelcapabilities"
+	"nekobot/pkg/channelidentity"
 	"nekobot/pkg/channeltrace"
 	"nekobot/pkg/commands"
 	"nekobot/pkg/config"
+	"nekobot/pkg/feedback"
 	"nekobot/pkg/logger"
 	"nekobot/pkg/transcription"
+	"nekobot/pkg/tts"
 	"nekobot/pkg/userprefs"
 )
 
@@ -62,22 +69,59 @@ type Channel struct {
 	ctx      context.Context
 	cancel   context.CancelFunc
 
+	// identity caches getMe; it is reseeded from bot.Self on every connect.
+	identity *channelidentity.Cache
+
 	settingsMu    sync.Mutex
 	settingsInput map[string]string
 
 	pendingSkillMu       sync.Mutex
 	pendingSkillInstalls map[string]pendingSkillInstall
+
+	// replies maps "<chat>:<user message>" to the bot reply so edits and
+	// deletions of the user message can be propagated.
+	repliesMu  sync.Mutex
+	replies    map[string]int
+	replyOrder []string
+
+	// feedback stores 👍/👎 ratings; replies carry rating buttons when set.
+	feedback feedbackRecorder
+
+	// synthesizer voices replies to voice messages for users with
+	// voice_replies on; nil disables voice replies.
+	synthesizer tts.Synthesizer
+
+	// streams holds the replies being streamed into thinking messages,
+	// keyed like replies but by thinking message.
+	streamsMu          sync.Mutex
+	streams            map[string]*replyStream
+	streamEditInterval time.Duration
+
+	// topics maps received messages to their forum topic when
+	// topic_sessions is on.
+	topics topicIndex
+}
+
+type feedbackRecorder interface {
+	Record(ctx context.Context, entry feedback.Entry) (feedback.Entry, error)
 }
 
 type pendingSkillInstall struct {
 	UserID    int64
 	Command   string
 	Repo      string
+	Version   string
 	CreatedAt time.Time
 }
 
 const telegramMaxMessageChars = 3800
 
+// dataKeyVoiceReply marks a turn whose reply should also be sent as a voice note.
+const dataKeyVoiceReply = "voice_reply"
+
+// telegramMaxTrackedReplies bounds the reply index used for edit propagation.
+const telegramMaxTrackedReplies = 1024
+
 // New creates a new Telegram channel.
 func New(
 	log *logger.Logger,
@@ -109,7 +153,7 @@ func NewAccountChannel(
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &Channel{
+	channel := &Channel{
 		log:                  log,
 		bus:                  messageBus,
 		agent:                ag,
@@ -124,7 +168,18 @@ func NewAccountChannel(
 		cancel:               cancel,
 		settingsInput:        map[string]string{},
 		pendingSkillInstalls: map[string]pendingSkillInstall{},
-	}, nil
+		replies:              map[string]int{},
+	}
+	channel.identity = channelidentity.NewCache(channel.fetchIdentity, channelidentity.DefaultTTL)
+	if store := ag.Feedback(); store != nil {
+		channel.feedback = store
+	}
+	return channel, nil
+}
+
+// SetSynthesizer enables voice-note replies to voice messages.
+func (c *Channel) SetSynthesizer(synthesizer tts.Synthesizer) {
+	c.synthesizer = synthesizer
 }
 
 // ID returns the channel identifier.
@@ -163,6 +218,9 @@ func (c *Channel) Start(ctx context.Context) error {
 		}
 		c.log.Info("Telegram proxy enabled", zap.String("proxy", proxyURL.String()))
 	}
+	if c.config.TopicSessions {
+		httpClient.Transport = &topicTransport{base: httpClient.Transport, topics: &c.topics}
+	}
 
 	// Create bot
 	bot, err := tgbotapi.NewBotAPIWithClient(c.config.Token, tgbotapi.APIEndpoint, httpClient)
@@ -174,6 +232,10 @@ func (c *Channel) Start(ctx context.Context) error {
 	c.stopOnce = sync.Once{}
 	c.bot.Debug = false
 
+	// NewBotAPIWithClient already called getMe; reuse it instead of asking again.
+	c.identity.Invalidate()
+	c.identity.Set(telegramIdentity(bot.Self))
+
 	c.log.Info("Telegram bot connected",
 		zap.String("username", bot.Self.UserName))
 	c.syncSlashCommands()
@@ -212,6 +274,35 @@ func (c *Channel) Stop(ctx context.Context) error {
 	return nil
 }
 
+// Identity returns the bot account this channel is connected as.
+func (c *Channel) Identity(ctx context.Context) (channelidentity.Identity, error) {
+	return c.identity.Get(ctx)
+}
+
+func (c *Channel) fetchIdentity(context.Context) (channelidentity.Identity, error) {
+	if c.bot == nil {
+		return channelidentity.Identity{}, fmt.Errorf("telegram bot not initialized")
+	}
+	user, err := c.bot.GetMe()
+	if err != nil {
+		return channelidentity.Identity{}, fmt.Errorf("telegram getMe: %w", err)
+	}
+	return telegramIdentity(user), nil
+}
+
+func telegramIdentity(user tgbotapi.User) channelidentity.Identity {
+	return channelidentity.Identity{
+		ID:          strconv.FormatInt(user.ID, 10),
+		Username:    user.UserName,
+		DisplayName: strings.TrimSpace(user.FirstName + " " + user.LastName),
+		Capabilities: map[string]bool{
+			"can_join_groups":             user.CanJoinGroups,
+			"can_read_all_group_messages": user.CanReadAllGroupMessages,
+			"supports_inline_queries":     user.SupportsInlineQueries,
+		},
+	}
+}
+
 func (c *Channel) stopReceivingUpdates() {
 	if c.bot == nil {
 		return
@@ -239,10 +330,53 @@ func (c *Channel) SendMessage(ctx context.Context, msg *bus.Message) error {
 	if err != nil {
 		return fmt.Errorf("invalid session ID: %w", err)
 	}
+	if len(msg.Attachments) > 0 {
+		return c.SendAttachments(ctx, msg.SessionID, msg.Content, msg.Attachments)
+	}
+
+	thinkingMsgID := int(metadataInt64(msg.Data, "thinking_message_id"))
+	if msg.Type == bus.MessageTypeStreamUpdate {
+		if thinkingMsgID > 0 {
+			c.updateReplyStream(chatID, thinkingMsgID, msg.Content)
+		}
+		return nil
+	}
 
-	// Create message
 	replyText := prependBusToolTrace(msg.Content, msg)
+	sourceMsgID := int(metadataInt64(msg.Data, "reply_to_message_id"))
+	if voice, _ := msg.Data[dataKeyVoiceReply].(bool); voice && c.synthesizer != nil {
+		defer c.sendVoiceReply(ctx, chatID, sourceMsgID, msg.Content)
+	}
+
+	// A streamed reply ends in the thinking message it was streamed into.
+	if streamed, _ := msg.Data[bus.DataKeyStreamReplies].(bool); streamed && thinkingMsgID > 0 {
+		if c.finishStreamedReply(chatID, sourceMsgID, thinkingMsgID, replyText) {
+			return nil
+		}
+	}
+
+	// A re-run for an edited message rewrites the earlier reply in place.
+	if edited, _ := msg.Data["edited"].(bool); edited && sourceMsgID > 0 {
+		if replyID := c.trackedReply(chatID, sourceMsgID); replyID > 0 {
+			if err := c.editReplyText(chatID, replyID, replyText); err == nil {
+				return nil
+			} else {
+				c.log.Warn("Failed to edit Telegram reply, sending a new one", zap.Error(err))
+			}
+		}
+	}
+
+	// Create message
 	reply := tgbotapi.NewMessage(chatID, replyText)
+	if requestID, ok := msg.Data[approval.DataKeyRequestID].(string); ok && requestID != "" {
+		if kb := c.scopedInlineKeyboard(chatTypeForChatID(chatID), approvalKeyboard(requestID)); kb != nil {
+			reply.ReplyMarkup = kb
+		}
+	} else if sourceMsgID > 0 && c.feedback != nil {
+		if kb := c.scopedInlineKeyboard(chatTypeForChatID(chatID), feedbackKeyboard("")); kb != nil {
+			reply.ReplyMarkup = kb
+		}
+	}
 
 	// Handle reply
 	if msg.ReplyTo != "" {
@@ -253,10 +387,183 @@ func (c *Channel) SendMessage(ctx context.Context, msg *bus.Message) error {
 	}
 
 	// Send message
-	if _, err := c.bot.Send(reply); err != nil {
+	sent, err := c.bot.Send(reply)
+	if err != nil {
 		return fmt.Errorf("sending telegram message: %w", err)
 	}
+	if sourceMsgID > 0 {
+		c.trackReply(chatID, sourceMsgID, sent.MessageID)
+	}
+
+	return nil
+}
 
+// telegramMaxPhotoBytes is the Bot API size limit for sendPhoto uploads;
+// larger images are sent as documents.
+const telegramMaxPhotoBytes = 10 << 20
+
+// SendAttachments uploads each attachment to the chat, small images as
+// photos and everything else as documents. The caption goes with the first.
+func (c *Channel) SendAttachments(ctx context.Context, sessionID, caption string, attachments []bus.Attachment) error {
+	if c.bot == nil {
+		return fmt.Errorf("telegram bot not initialized")
+	}
+	chatID, err := c.extractChatID(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+	for i, attachment := range attachments {
+		if i > 0 {
+			caption = ""
+		}
+		if _, err := c.bot.Send(telegramFileMessage(chatID, attachment, caption)); err != nil {
+			return fmt.Errorf("sending telegram file %s: %w", attachment.FileName(), err)
+		}
+	}
+	return nil
+}
+
+// finishStreamedReply replaces the streamed text in the thinking message with
+// the final reply. It returns false when the reply must be sent as a new
+// message instead.
+func (c *Channel) finishStreamedReply(chatID int64, sourceMsgID, thinkingMsgID int, text string) bool {
+	shown := c.finishReplyStream(chatID, thinkingMsgID)
+
+	chunks := splitTelegramText(text, telegramMaxMessageChars)
+	if len(chunks) > 1 {
+		c.finishThinkingMessage(chatID, sourceMsgID, thinkingMsgID, text)
+		return true
+	}
+	if len(chunks) == 0 {
+		return false
+	}
+
+	var kb *tgbotapi.InlineKeyboardMarkup
+	if sourceMsgID > 0 && c.feedback != nil {
+		kb = c.scopedInlineKeyboard(chatTypeForChatID(chatID), feedbackKeyboard(""))
+	}
+	// Telegram rejects edits that change nothing.
+	if chunks[0] != shown || kb != nil {
+		edit := tgbotapi.NewEditMessageText(chatID, thinkingMsgID, chunks[0])
+		edit.ReplyMarkup = kb
+		if _, err := c.bot.Send(edit); err != nil {
+			c.log.Warn("Failed to finish streamed Telegram reply, sending a new one", zap.Error(err))
+			return false
+		}
+	}
+	if sourceMsgID > 0 {
+		c.trackReply(chatID, sourceMsgID, thinkingMsgID)
+	}
+	return true
+}
+
+// sendVoiceReply speaks text as a voice note answering the user's message.
+// Failures are logged; the text reply has already been delivered.
+func (c *Channel) sendVoiceReply(ctx context.Context, chatID int64, replyTo int, text string) {
+	audio, err := c.synthesizer.Synthesize(ctx, text)
+	if errors.Is(err, tts.ErrNotSpeakable) {
+		return
+	}
+	if err != nil {
+		c.log.Warn("Failed to synthesize Telegram voice reply", zap.Error(err))
+		return
+	}
+
+	name := "reply.mp3"
+	if audio.MIMEType == "audio/ogg" {
+		name = "reply.ogg"
+	}
+	voice := tgbotapi.NewVoice(chatID, tgbotapi.FileBytes{Name: name, Bytes: audio.Data})
+	if replyTo > 0 {
+		voice.ReplyToMessageID = replyTo
+	}
+	if _, err := c.bot.Send(voice); err != nil {
+		c.log.Warn("Failed to send Telegram voice reply", zap.Error(err))
+	}
+}
+
+// profileLanguage returns the language the user picked with /settings lang,
+// or "" when they have not set one.
+func (c *Channel) profileLanguage(ctx context.Context, userID string) string {
+	if c.prefs == nil {
+		return ""
+	}
+	profile, ok, err := c.prefs.Get(ctx, c.ID(), userID)
+	if err != nil || !ok || profile.Language == "" {
+		return ""
+	}
+	return userprefs.NormalizeLanguage(profile.Language)
+}
+
+// wantsVoiceReply reports whether a voice message from userID should be
+// answered with a voice note as well as text.
+func (c *Channel) wantsVoiceReply(ctx context.Context, userID string) bool {
+	if c.synthesizer == nil || c.prefs == nil {
+		return false
+	}
+	profile, ok, err := c.prefs.Get(ctx, c.ID(), userID)
+	return err == nil && ok && profile.VoiceReplies
+}
+
+func telegramFileMessage(chatID int64, attachment bus.Attachment, caption string) tgbotapi.Chattable {
+	var file tgbotapi.RequestFileData = tgbotapi.FileBytes{Name: attachment.FileName(), Bytes: attachment.Data}
+	size := int64(len(attachment.Data))
+	switch {
+	case len(attachment.Data) == 0 && attachment.Path != "":
+		file = tgbotapi.FilePath(attachment.Path)
+		size = -1
+		if info, err := os.Stat(attachment.Path); err == nil {
+			size = info.Size()
+		}
+	case len(attachment.Data) == 0 && attachment.URL != "":
+		file = tgbotapi.FileURL(attachment.URL)
+		size = 0
+	}
+	if attachment.IsImage() && size >= 0 && size <= telegramMaxPhotoBytes {
+		photo := tgbotapi.NewPhoto(chatID, file)
+		photo.Caption = caption
+		return photo
+	}
+	doc := tgbotapi.NewDocument(chatID, file)
+	doc.Caption = caption
+	return doc
+}
+
+// EditReply replaces the reply sent for sourceMessageID with content.
+func (c *Channel) EditReply(ctx context.Context, sessionID, sourceMessageID, content string) error {
+	if c.bot == nil {
+		return fmt.Errorf("telegram bot not initialized")
+	}
+	chatID, replyID, err := c.resolveReply(sessionID, sourceMessageID)
+	if err != nil {
+		return err
+	}
+	return c.editReplyText(chatID, replyID, content)
+}
+
+func (c *Channel) resolveReply(sessionID, sourceMessageID string) (int64, int, error) {
+	chatID, err := c.extractChatID(sessionID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid session ID: %w", err)
+	}
+	sourceID, err := c.extractMessageID(sourceMessageID)
+	if err != nil {
+		return 0, 0, err
+	}
+	replyID := c.trackedReply(chatID, sourceID)
+	if replyID == 0 {
+		return 0, 0, fmt.Errorf("no tracked reply for telegram message %d", sourceID)
+	}
+	return chatID, replyID, nil
+}
+
+func (c *Channel) editReplyText(chatID int64, replyID int, text string) error {
+	if len([]rune(text)) > telegramMaxMessageChars {
+		return fmt.Errorf("reply too long to edit in place")
+	}
+	if _, err := c.bot.Send(tgbotapi.NewEditMessageText(chatID, replyID, text)); err != nil {
+		return fmt.Errorf("editing telegram reply: %w", err)
+	}
 	return nil
 }
 
@@ -273,6 +580,12 @@ func (c *Channel) handleUpdate(update tgbotapi.Update) {
 		return
 	}
 
+	if update.EditedMessage != nil {
+		msg := *update.EditedMessage
+		go c.handleEditedMessage(&msg)
+		return
+	}
+
 	if update.CallbackQuery != nil {
 		cb := *update.CallbackQuery
 		go c.handleCallbackQuery(&cb)
@@ -308,9 +621,10 @@ func (c *Channel) syncSlashCommands() {
 		if desc == "" {
 			desc = "Command"
 		}
-		if len(desc) > 256 {
-			desc = desc[:256]
+		if hint := cmd.ParamHint(); hint != "" {
+			desc += " " + hint
 		}
+		desc = truncateTelegramCommandDescription(desc)
 
 		telegramCmds = append(telegramCmds, tgbotapi.BotCommand{
 			Command:     name,
@@ -327,6 +641,14 @@ func (c *Channel) syncSlashCommands() {
 		telegramCmds = telegramCmds[:100]
 	}
 
+	botID := strconv.FormatInt(c.bot.Self.ID, 10)
+	fingerprint := telegramCommandsFingerprint(telegramCmds)
+	if syncedTelegramCommands.unchanged(botID, fingerprint) {
+		c.log.Debug("Telegram slash commands unchanged, skipping sync",
+			zap.String("username", c.bot.Self.UserName))
+		return
+	}
+
 	scopes := []struct {
 		name   string
 		setter func() tgbotapi.Chattable
@@ -378,12 +700,57 @@ func (c *Channel) syncSlashCommands() {
 	if okScopes == 0 {
 		return
 	}
+	if okScopes == len(scopes) {
+		syncedTelegramCommands.record(botID, fingerprint)
+	}
 
 	c.log.Info("Synced Telegram slash commands",
 		zap.Int("count", len(telegramCmds)),
 		zap.Int("scopes", okScopes))
 }
 
+// truncateTelegramCommandDescription keeps desc within the 256 characters
+// Telegram allows without splitting a multi-byte character.
+func truncateTelegramCommandDescription(desc string) string {
+	runes := []rune(desc)
+	if len(runes) <= 256 {
+		return desc
+	}
+	return string(runes[:255]) + "…"
+}
+
+// syncedTelegramCommands remembers, per bot ID, the command list last pushed
+// to Telegram so reconnects and channel reloads skip an unchanged sync.
+var syncedTelegramCommands = &telegramCommandSyncState{synced: map[string]string{}}
+
+type telegramCommandSyncState struct {
+	mu     sync.Mutex
+	synced map[string]string
+}
+
+func (s *telegramCommandSyncState) unchanged(botID, fingerprint string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.synced[botID] == fingerprint
+}
+
+func (s *telegramCommandSyncState) record(botID, fingerprint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.synced[botID] = fingerprint
+}
+
+func telegramCommandsFingerprint(commands []tgbotapi.BotCommand) string {
+	var b strings.Builder
+	for _, cmd := range commands {
+		b.WriteString(cmd.Command)
+		b.WriteByte(0)
+		b.WriteString(cmd.Description)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
 func sortTelegramCommands(commands []tgbotapi.BotCommand) {
 	core := map[string]struct{}{
 		"start":    {},
@@ -455,15 +822,23 @@ func (c *Channel) handleMessage(message *tgbotapi.Message) {
 	content := strings.TrimSpace(message.Text)
 	msgType := bus.MessageTypeText
 
+	// Photos are passed to the agent as image attachments, captioned by
+	// the message text.
+	attachments := c.imageAttachments(message)
+	if len(attachments) > 0 {
+		content = strings.TrimSpace(message.Caption)
+		msgType = bus.MessageTypeImage
+	}
+
 	// Support voice/audio messages via Whisper transcription.
-	if content == "" && c.transcriber != nil {
+	if content == "" && len(attachments) == 0 && c.transcriber != nil {
 		transcribed, ok := c.tryTranscribeAudio(message)
 		if ok {
 			content = transcribed
 			msgType = bus.MessageTypeAudio
 		}
 	}
-	if content == "" {
+	if content == "" && len(attachments) == 0 {
 		return
 	}
 
@@ -477,7 +852,7 @@ func (c *Channel) handleMessage(message *tgbotapi.Message) {
 		zap.String("text", content))
 
 	// Check if it's a command
-	if c.supportsNativeCommands(chatTypeForChatID(message.Chat.ID)) && c.commands.IsCommand(content) {
+	if msgType != bus.MessageTypeImage && c.supportsNativeCommands(chatTypeForChatID(message.Chat.ID)) && c.commands.IsCommand(content) {
 		if msgType == bus.MessageTypeText {
 			c.handleCommand(message)
 			return
@@ -490,16 +865,31 @@ func (c *Channel) handleMessage(message *tgbotapi.Message) {
 		return
 	}
 
+	if !message.Chat.IsPrivate() && c.mentionOnly() {
+		addressed, stripped := c.addressedToBot(message, content)
+		if !addressed {
+			c.log.Debug("Ignoring Telegram group message not addressed to the bot",
+				zap.Int64("chat_id", message.Chat.ID),
+				zap.Int("message_id", message.MessageID))
+			return
+		}
+		content = stripped
+		if content == "" && len(attachments) == 0 {
+			return
+		}
+	}
+
 	// Create bus message
 	busMsg := &bus.Message{
-		ID:        fmt.Sprintf("telegram:%d", message.MessageID),
-		ChannelID: c.ID(),
-		SessionID: c.sessionID(message.Chat.ID),
-		UserID:    fmt.Sprintf("%d", message.From.ID),
-		Username:  message.From.UserName,
-		Type:      msgType,
-		Content:   content,
-		Timestamp: time.Unix(int64(message.Date), 0),
+		ID:          fmt.Sprintf("telegram:%d", message.MessageID),
+		ChannelID:   c.ID(),
+		SessionID:   c.messageSessionID(message),
+		UserID:      fmt.Sprintf("%d", message.From.ID),
+		Username:    message.From.UserName,
+		Type:        msgType,
+		Content:     content,
+		Attachments: attachments,
+		Timestamp:   time.Unix(int64(message.Date), 0),
 	}
 
 	if message.ReplyToMessage != nil {
@@ -512,6 +902,15 @@ func (c *Channel) handleMessage(message *tgbotapi.Message) {
 		"thinking_message_id": thinkingMsgID,
 		"reply_to_message_id": message.MessageID,
 	}
+	if c.config.StreamReplies && thinkingMsgID > 0 {
+		busMsg.Data[bus.DataKeyStreamReplies] = true
+	}
+	if message.Chat.IsPrivate() {
+		busMsg.Data[bus.DataKeyDirectMessage] = true
+	}
+	if msgType == bus.MessageTypeAudio && c.wantsVoiceReply(context.Background(), busMsg.UserID) {
+		busMsg.Data[dataKeyVoiceReply] = true
+	}
 
 	if err := c.bus.SendInbound(busMsg); err != nil {
 		c.log.Error("Failed to route Telegram inbound message", zap.Error(err))
@@ -519,6 +918,91 @@ func (c *Channel) handleMessage(message *tgbotapi.Message) {
 	}
 }
 
+// handleEditedMessage re-runs the turn for an edited text message when
+// rerun_edited_messages is enabled; the reply is then edited in place.
+func (c *Channel) handleEditedMessage(message *tgbotapi.Message) {
+	if !c.config.RerunEditedMessages || message.From == nil {
+		return
+	}
+	if !c.isUserAllowed(message.From.ID, message.Chat.ID, message.From.UserName) {
+		return
+	}
+
+	content := strings.TrimSpace(message.Text)
+	if content == "" || (c.commands != nil && c.commands.IsCommand(content)) {
+		return
+	}
+	if !message.Chat.IsPrivate() && c.mentionOnly() {
+		_, content = c.addressedToBot(message, content)
+	}
+	if c.trackedReply(message.Chat.ID, message.MessageID) == 0 {
+		c.log.Debug("Ignoring edit for Telegram message without a tracked reply",
+			zap.Int64("chat_id", message.Chat.ID),
+			zap.Int("message_id", message.MessageID))
+		return
+	}
+
+	c.log.Info("Re-running edited Telegram message",
+		zap.Int64("chat_id", message.Chat.ID),
+		zap.Int("message_id", message.MessageID))
+
+	busMsg := &bus.Message{
+		ID:        fmt.Sprintf("telegram:%d", message.MessageID),
+		ChannelID: c.ID(),
+		SessionID: c.messageSessionID(message),
+		UserID:    fmt.Sprintf("%d", message.From.ID),
+		Username:  message.From.UserName,
+		Type:      bus.MessageTypeText,
+		Content:   c.applyUserProfile(context.Background(), fmt.Sprintf("%d", message.From.ID), content),
+		Timestamp: time.Unix(int64(message.EditDate), 0),
+		Data: map[string]interface{}{
+			"reply_to_message_id":    message.MessageID,
+			"edited":                 true,
+			bus.DataKeyDirectMessage: message.Chat.IsPrivate(),
+		},
+	}
+	if message.ReplyToMessage != nil {
+		busMsg.ReplyTo = fmt.Sprintf("telegram:%d", message.ReplyToMessage.MessageID)
+	}
+
+	if err := c.bus.SendInbound(busMsg); err != nil {
+		c.log.Error("Failed to route edited Telegram message", zap.Error(err))
+	}
+}
+
+// imageAttachments downloads the photo of a message, or an image sent as a
+// document. Telegram lists each photo in several sizes; the largest one
+// within bus.MaxImageBytes is used.
+func (c *Channel) imageAttachments(message *tgbotapi.Message) []bus.Attachment {
+	fileID, name, mimeType := "", "photo.jpg", "image/jpeg"
+	switch {
+	case len(message.Photo) > 0:
+		for _, size := range message.Photo {
+			if size.FileSize <= bus.MaxImageBytes {
+				fileID = size.FileID
+			}
+		}
+	case message.Document != nil && strings.HasPrefix(message.Document.MimeType, "image/"):
+		if message.Document.FileSize > bus.MaxImageBytes {
+			break
+		}
+		fileID, mimeType = message.Document.FileID, message.Document.MimeType
+		if message.Document.FileName != "" {
+			name = message.Document.FileName
+		}
+	}
+	if fileID == "" {
+		return nil
+	}
+
+	data, err := c.downloadFile(fileID, bus.MaxImageBytes)
+	if err != nil {
+		c.log.Warn("Failed to download Telegram image", zap.Error(err))
+		return nil
+	}
+	return []bus.Attachment{{Type: bus.MessageTypeImage, Name: name, MIMEType: mimeType, Data: data}}
+}
+
 func (c *Channel) tryTranscribeAudio(message *tgbotapi.Message) (string, bool) {
 	fileID := ""
 	filename := "voice.ogg"
@@ -543,7 +1027,7 @@ func (c *Channel) tryTranscribeAudio(message *tgbotapi.Message) (string, bool) {
 		return "", false
 	}
 
-	audioBytes, err := c.downloadFile(fileID)
+	audioBytes, err := c.downloadFile(fileID, transcription.MaxAudioBytes)
 	if err != nil {
 		c.log.Warn("Failed to download Telegram audio for transcription", zap.Error(err))
 		return "", false
@@ -551,6 +1035,7 @@ func (c *Channel) tryTranscribeAudio(message *tgbotapi.Message) (string, bool) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout())
 	defer cancel()
+	ctx = transcription.WithLanguage(ctx, c.profileLanguage(ctx, fmt.Sprintf("%d", message.From.ID)))
 
 	text, err := c.transcriber.Transcribe(ctx, audioBytes, filename)
 	if err != nil {
@@ -564,7 +1049,9 @@ func (c *Channel) tryTranscribeAudio(message *tgbotapi.Message) (string, bool) {
 	return text, true
 }
 
-func (c *Channel) downloadFile(fileID string) ([]byte, error) {
+// downloadFile fetches a file from the Bot API. Files over limit bytes are
+// rejected.
+func (c *Channel) downloadFile(fileID string, limit int64) ([]byte, error) {
 	url, err := c.bot.GetFileDirectURL(fileID)
 	if err != nil {
 		return nil, fmt.Errorf("resolving file URL: %w", err)
@@ -580,10 +1067,13 @@ func (c *Channel) downloadFile(fileID string) ([]byte, error) {
 		return nil, fmt.Errorf("download failed with status %d", resp.StatusCode)
 	}
 
-	data, err := io.ReadAll(io.LimitReader(resp.Body, 20*1024*1024))
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
 	if err != nil {
 		return nil, fmt.Errorf("reading file body: %w", err)
 	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("file exceeds %d bytes", limit)
+	}
 	return data, nil
 }
 
@@ -611,6 +1101,9 @@ func (c *Channel) handleCommand(message *tgbotapi.Message) {
 		zap.String("command", cmdName),
 		zap.String("user", message.From.UserName))
 
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout())
+	defer cancel()
+
 	// Create command request
 	req := commands.CommandRequest{
 		Channel:  c.ID(),
@@ -622,12 +1115,10 @@ func (c *Channel) handleCommand(message *tgbotapi.Message) {
 		Metadata: map[string]string{
 			"message_id": fmt.Sprintf("%d", message.MessageID),
 			"chat_type":  message.Chat.Type,
+			"session_id": c.commandSessionID(ctx, message),
 		},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout())
-	defer cancel()
-
 	thinkingMsgID := c.sendThinkingMessage(message.Chat.ID, message.MessageID, "🤔 正在处理命令...")
 
 	resp, err := cmd.Handler(ctx, req)
@@ -642,9 +1133,12 @@ func (c *Channel) handleCommand(message *tgbotapi.Message) {
 
 	if resp.Interaction != nil && resp.Interaction.Type == commands.InteractionTypeSkillInstallConfirm {
 		proposal := commands.SkillInstallProposal{
-			Repo:    strings.TrimSpace(resp.Interaction.Repo),
-			Reason:  strings.TrimSpace(resp.Interaction.Reason),
-			Message: strings.TrimSpace(resp.Interaction.Message),
+			Repo:        strings.TrimSpace(resp.Interaction.Repo),
+			Reason:      strings.TrimSpace(resp.Interaction.Reason),
+			Message:     strings.TrimSpace(resp.Interaction.Message),
+			Version:     strings.TrimSpace(resp.Interaction.Version),
+			Changelog:   strings.TrimSpace(resp.Interaction.Changelog),
+			Permissions: resp.Interaction.Permissions,
 		}
 		if proposal.Repo == "" {
 			c.finishThinkingMessage(message.Chat.ID, message.MessageID, thinkingMsgID, resp.Content)
@@ -689,6 +1183,16 @@ func (c *Channel) handleCallbackQuery(cb *tgbotapi.CallbackQuery) {
 		return
 	}
 
+	if strings.HasPrefix(cb.Data, feedbackCallbackPrefix) {
+		c.handleFeedbackCallback(cb)
+		return
+	}
+
+	if strings.HasPrefix(cb.Data, approval.CallbackPrefix) {
+		c.handleApprovalCallback(cb)
+		return
+	}
+
 	if !strings.HasPrefix(cb.Data, "settings:") {
 		c.answerCallback(cb.ID, "ok", false)
 		return
@@ -810,6 +1314,135 @@ func (c *Channel) handleCallbackQuery(cb *tgbotapi.CallbackQuery) {
 	}
 }
 
+// feedbackCallbackPrefix marks the rating buttons attached to replies.
+const feedbackCallbackPrefix = "feedback:"
+
+// feedbackKeyboard renders the rating buttons, marking the chosen one.
+func feedbackKeyboard(selected string) tgbotapi.InlineKeyboardMarkup {
+	up, down := "👍", "👎"
+	switch selected {
+	case "up":
+		up = "👍 ✓"
+	case "down":
+		down = "👎 ✓"
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(up, feedbackCallbackPrefix+"up"),
+			tgbotapi.NewInlineKeyboardButtonData(down, feedbackCallbackPrefix+"down"),
+		),
+	)
+}
+
+// handleFeedbackCallback records a 👍/👎 press against the reply it is
+// attached to. Pressing again replaces the user's earlier rating.
+func (c *Channel) handleFeedbackCallback(cb *tgbotapi.CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+	if cb.From == nil || !c.isUserAllowed(cb.From.ID, chatID, cb.From.UserName) {
+		c.answerCallback(cb.ID, "你不在 allow_from 白名单中", true)
+		return
+	}
+	if c.feedback == nil {
+		c.answerCallback(cb.ID, "Feedback is unavailable", true)
+		return
+	}
+	rating, err := feedback.ParseRating(strings.TrimPrefix(cb.Data, feedbackCallbackPrefix))
+	if err != nil {
+		c.answerCallback(cb.ID, "ok", false)
+		return
+	}
+
+	ctx := context.Background()
+	profile, _, _ := c.getProfile(ctx, cb.From.ID)
+	lang := userprefs.NormalizeLanguage(profile.Language)
+	_, err = c.feedback.Record(ctx, feedback.Entry{
+		Channel:   c.ID(),
+		SessionID: c.topicSessionID(chatID, c.messageTopic(chatID, cb.Message.MessageID), feedbackAskerID(cb)),
+		MessageID: fmt.Sprintf("telegram:%d", cb.Message.MessageID),
+		UserID:    fmt.Sprintf("%d", cb.From.ID),
+		Rating:    rating,
+		Response:  cb.Message.Text,
+	})
+	if err != nil {
+		c.log.Warn("Failed to record Telegram feedback", zap.Error(err))
+		c.answerCallback(cb.ID, c.settingsText(lang, "保存失败", "Save failed", "保存に失敗しました"), true)
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, cb.Message.MessageID, feedbackKeyboard(feedback.RatingLabel(rating)))
+	if _, err := c.bot.Request(edit); err != nil {
+		c.log.Debug("Failed to update Telegram feedback buttons", zap.Error(err))
+	}
+	c.answerCallback(cb.ID, c.settingsText(lang, "感谢反馈！", "Thanks for the feedback!", "フィードバックありがとうございます！"), false)
+}
+
+// feedbackAskerID returns the user whose message the rated reply answered,
+// so feedback lands in that user's session under per-user group scope.
+func feedbackAskerID(cb *tgbotapi.CallbackQuery) int64 {
+	if cb.Message != nil && cb.Message.ReplyToMessage != nil && cb.Message.ReplyToMessage.From != nil {
+		return cb.Message.ReplyToMessage.From.ID
+	}
+	return cb.From.ID
+}
+
+// approvalKeyboard renders the approve and deny buttons of a tool approval
+// prompt.
+func approvalKeyboard(requestID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Approve", approval.CallbackData(approval.Approved, requestID)),
+			tgbotapi.NewInlineKeyboardButtonData("⛔ Deny", approval.CallbackData(approval.Denied, requestID)),
+		),
+	)
+}
+
+// handleApprovalCallback applies an approve or deny press to the pending
+// tool approval and replaces the buttons with the outcome.
+func (c *Channel) handleApprovalCallback(cb *tgbotapi.CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+	if cb.From == nil || !c.isUserAllowed(cb.From.ID, chatID, cb.From.UserName) {
+		c.answerCallback(cb.ID, "你不在 allow_from 白名单中", true)
+		return
+	}
+	decision, requestID, ok := approval.ParseCallback(cb.Data)
+	if !ok {
+		c.answerCallback(cb.ID, "ok", false)
+		return
+	}
+	mgr := c.agent.ApprovalManager()
+	if mgr == nil {
+		c.answerCallback(cb.ID, "Approvals are unavailable", true)
+		return
+	}
+	// Buttons only decide prompts asked in this chat; callback data is
+	// client-supplied, so a forged ID must not reach other requests.
+	pressed := &tgbotapi.Message{MessageID: cb.Message.MessageID, Chat: cb.Message.Chat, From: cb.From}
+	if !mgr.PromptedIn(requestID, c.ID(), c.commandSessionID(context.Background(), pressed)) {
+		c.answerCallback(cb.ID, "No approval prompt "+requestID+" is waiting in this chat", true)
+		return
+	}
+
+	actor := c.ID() + ":" + strconv.FormatInt(cb.From.ID, 10)
+	if cb.From.UserName != "" {
+		actor = c.ID() + ":@" + cb.From.UserName
+	}
+	var err error
+	if decision == approval.Approved {
+		err = mgr.ApproveAs(requestID, actor)
+	} else {
+		err = mgr.DenyAs(requestID, actor, "denied from Telegram")
+	}
+	if err != nil {
+		c.answerCallback(cb.ID, err.Error(), true)
+		if req, found := mgr.GetRequest(requestID); found && req.Decision != approval.Pending {
+			c.editSettingsMessage(chatID, cb.Message.MessageID, cb.Message.Text+"\n\n"+approval.DecisionText(req.Decision, req.DecidedBy), tgbotapi.NewInlineKeyboardMarkup())
+		}
+		return
+	}
+	c.editSettingsMessage(chatID, cb.Message.MessageID, cb.Message.Text+"\n\n"+approval.DecisionText(decision, actor), tgbotapi.NewInlineKeyboardMarkup())
+	c.answerCallback(cb.ID, approval.DecisionText(decision, ""), false)
+}
+
 func (c *Channel) handleSkillInstallCallback(cb *tgbotapi.CallbackQuery) {
 	if cb == nil || cb.Message == nil {
 		return
@@ -870,7 +1503,7 @@ func (c *Channel) executeConfirmedSkillInstall(cb *tgbotapi.CallbackQuery, pendi
 		UserID:   fmt.Sprintf("%d", cb.From.ID),
 		Username: cb.From.UserName,
 		Command:  pending.Command,
-		Args:     "__confirm_install__ " + pending.Repo,
+		Args:     commands.ConfirmInstallArgs(pending.Repo, pending.Version),
 		Metadata: map[string]string{
 			"message_id": fmt.Sprintf("%d", cb.Message.MessageID),
 			"chat_type":  cb.Message.Chat.Type,
@@ -1069,19 +1702,7 @@ func (c *Channel) sendSkillInstallConfirmation(chatID, userID int64, replyTo int
 		lang = userprefs.NormalizeLanguage(p.Language)
 	}
 
-	text := proposal.Message
-	if strings.TrimSpace(text) == "" {
-		text = c.settingsText(lang,
-			fmt.Sprintf("准备安装技能仓库：%s\n是否继续？", proposal.Repo),
-			fmt.Sprintf("Ready to install skill repo: %s\nContinue?", proposal.Repo),
-			fmt.Sprintf("スキルリポジトリ %s をインストールします。続行しますか？", proposal.Repo),
-		)
-	}
-	if strings.TrimSpace(proposal.Reason) != "" {
-		text += "\n\n" + c.settingsText(lang, "原因：", "Reason: ", "理由: ") + proposal.Reason
-	}
-
-	msg := tgbotapi.NewMessage(chatID, text)
+	msg := tgbotapi.NewMessage(chatID, c.skillInstallConfirmationText(lang, proposal))
 	if replyTo > 0 {
 		msg.ReplyToMessageID = replyTo
 	}
@@ -1103,10 +1724,51 @@ func (c *Channel) sendSkillInstallConfirmation(chatID, userID int64, replyTo int
 		UserID:    userID,
 		Command:   command,
 		Repo:      proposal.Repo,
+		Version:   proposal.Version,
 		CreatedAt: time.Now(),
 	})
 }
 
+// skillInstallConfirmationText describes the proposed install, including
+// the indexed version and its changelog when known.
+func (c *Channel) skillInstallConfirmationText(lang string, proposal commands.SkillInstallProposal) string {
+	text := proposal.Message
+	if strings.TrimSpace(text) == "" {
+		text = c.settingsText(lang,
+			fmt.Sprintf("准备安装技能仓库：%s\n是否继续？", proposal.Repo),
+			fmt.Sprintf("Ready to install skill repo: %s\nContinue?", proposal.Repo),
+			fmt.Sprintf("スキルリポジトリ %s をインストールします。続行しますか？", proposal.Repo),
+		)
+	}
+	if proposal.Version != "" {
+		text += "\n\n" + c.settingsText(lang, "版本：", "Version: ", "バージョン: ") + proposal.Version
+	}
+	if proposal.Changelog != "" {
+		text += "\n" + c.settingsText(lang, "更新说明：\n", "Changelog:\n", "変更履歴:\n") + proposal.Changelog
+	}
+	text += "\n\n" + c.skillPermissionsText(lang, proposal)
+	if strings.TrimSpace(proposal.Reason) != "" {
+		text += "\n\n" + c.settingsText(lang, "原因：", "Reason: ", "理由: ") + proposal.Reason
+	}
+	return text
+}
+
+// skillPermissionsText lists what an indexed skill may do once installed.
+// Repos outside the index are described by the manifest they ship.
+func (c *Channel) skillPermissionsText(lang string, proposal commands.SkillInstallProposal) string {
+	if proposal.Version == "" {
+		return c.settingsText(lang,
+			"权限：由技能的 SKILL.md 声明并强制执行；未声明时仅可使用只读工具",
+			"Permissions: declared by the skill's SKILL.md and enforced; without a manifest it only gets read-only tools",
+			"権限: スキルの SKILL.md で宣言され強制されます。宣言がない場合は読み取り専用ツールのみ",
+		)
+	}
+	if len(proposal.Permissions) == 0 {
+		return c.settingsText(lang, "权限：仅只读工具", "Permissions: read-only tools only", "権限: 読み取り専用ツールのみ")
+	}
+	return c.settingsText(lang, "请求的权限：", "Requested permissions: ", "要求する権限: ") + strings.Join(proposal.Permissions, ", ")
+}
+
 func (c *Channel) editSettingsMessage(chatID int64, messageID int, text string, kb tgbotapi.InlineKeyboardMarkup) {
 	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
 	if scoped := c.scopedInlineKeyboard(chatTypeForChatID(chatID), kb); scoped != nil {
@@ -1484,7 +2146,8 @@ func (c *Channel) isUserAllowed(userID, chatID int64, username string) bool {
 	return false
 }
 
-// extractChatID extracts the chat ID from a session ID.
+// extractChatID extracts the chat ID from a session ID of the form
+// <channel>:<chat> or <channel>:<chat>:<user>.
 func (c *Channel) extractChatID(sessionID string) (int64, error) {
 	parts := strings.Split(sessionID, ":")
 	if len(parts) < 2 {
@@ -1496,7 +2159,7 @@ func (c *Channel) extractChatID(sessionID string) (int64, error) {
 		return 0, fmt.Errorf("invalid telegram session ID format")
 	}
 
-	rawChatID := parts[len(parts)-1]
+	rawChatID := parts[1]
 
 	var chatID int64
 	if _, err := fmt.Sscanf(rawChatID, "%d", &chatID); err != nil {
@@ -1526,13 +2189,64 @@ func (c *Channel) extractMessageID(msgRef string) (int, error) {
 	return msgID, nil
 }
 
-func (c *Channel) sessionID(chatID int64) string {
-	if c.ID() == c.ChannelType() {
-		return fmt.Sprintf("%s:%d", c.ChannelType(), chatID)
+func replyKey(chatID int64, sourceMsgID int) string {
+	return fmt.Sprintf("%d:%d", chatID, sourceMsgID)
+}
+
+func (c *Channel) trackReply(chatID int64, sourceMsgID, replyMsgID int) {
+	c.repliesMu.Lock()
+	defer c.repliesMu.Unlock()
+
+	if c.replies == nil {
+		c.replies = map[string]int{}
+	}
+	key := replyKey(chatID, sourceMsgID)
+	if _, exists := c.replies[key]; !exists {
+		c.replyOrder = append(c.replyOrder, key)
+	}
+	c.replies[key] = replyMsgID
+
+	for len(c.replyOrder) > telegramMaxTrackedReplies {
+		delete(c.replies, c.replyOrder[0])
+		c.replyOrder = c.replyOrder[1:]
+	}
+}
+
+func (c *Channel) trackedReply(chatID int64, sourceMsgID int) int {
+	c.repliesMu.Lock()
+	defer c.repliesMu.Unlock()
+	return c.replies[replyKey(chatID, sourceMsgID)]
+}
+
+func metadataInt64(values map[string]interface{}, key string) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	switch v := values[key].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// sessionID derives the session for a message from userID in chatID. Group
+// chats are scoped per member when session_scope is "user"; the chat ID
+// always stays the second segment so replies resolve to the right chat.
+func (c *Channel) sessionID(chatID, userID int64) string {
+	if c.perUserGroupSessions() && chatTypeForChatID(chatID) == "group" && userID != 0 {
+		return fmt.Sprintf("%s:%d:%d", c.ID(), chatID, userID)
 	}
 	return fmt.Sprintf("%s:%d", c.ID(), chatID)
 }
 
+func (c *Channel) perUserGroupSessions() bool {
+	return c.config != nil && strings.TrimSpace(c.config.SessionScope) == "user"
+}
+
 func (c *Channel) profileKey(userID int64) string {
 	if c.ID() == c.ChannelType() {
 		return fmt.Sprintf("%d", userID)
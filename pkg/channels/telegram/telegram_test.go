@@ -2,15 +2,26 @@ package telegram
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"nekobot/pkg/bus"
+	"nekobot/pkg/commands"
+	"nekobot/pkg/config"
+	"nekobot/pkg/feedback"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/session"
+	"nekobot/pkg/tts"
 )
 
 func TestSupportsInlineButtonsRespectsDefaultCapabilityScope(t *testing.T) {
@@ -59,6 +70,35 @@ func TestSkillInstallPromptFallsBackToTextConfirmationWithoutInlineButtons(t *te
 	}
 }
 
+func TestSkillInstallConfirmationShowsVersionAndChangelog(t *testing.T) {
+	channel := newTestChannel(t)
+
+	text := channel.skillInstallConfirmationText("en", commands.SkillInstallProposal{
+		Repo:      "acme/pdf-skill",
+		Version:   "v1.1.0",
+		Changelog: "Adds merging",
+	})
+	for _, want := range []string{"acme/pdf-skill", "Version: v1.1.0", "Changelog:\nAdds merging", "Permissions: read-only tools only"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in confirmation, got %q", want, text)
+		}
+	}
+
+	text = channel.skillInstallConfirmationText("en", commands.SkillInstallProposal{
+		Repo:        "acme/deploy-skill",
+		Version:     "v2.0.0",
+		Permissions: []string{"network", "exec"},
+	})
+	if !strings.Contains(text, "Requested permissions: network, exec") {
+		t.Fatalf("expected requested permissions in confirmation, got %q", text)
+	}
+
+	text = channel.skillInstallConfirmationText("en", commands.SkillInstallProposal{Repo: "someone/else"})
+	if !strings.Contains(text, "declared by the skill's SKILL.md") {
+		t.Fatalf("expected manifest note for repos outside the index, got %q", text)
+	}
+}
+
 func TestSendThinkingMessageSkipsGroupsWhenStreamingUnsupported(t *testing.T) {
 	channel := newTestChannel(t)
 
@@ -178,3 +218,708 @@ func TestSendMessagePrependsToolTraceFromBusMetadata(t *testing.T) {
 		t.Fatalf("expected original reply after blank line, got %q", sentTexts[0])
 	}
 }
+
+func TestEditedMessageRerunsTurnAndEditsReply(t *testing.T) {
+	channel := newTestChannel(t)
+	channel.config = &config.TelegramConfig{RerunEditedMessages: true}
+
+	var sentTexts, editedTexts []string
+	var editedIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		switch r.URL.Path {
+		case "/bottest-token/getMe":
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"testbot"}}`))
+		case "/bottest-token/sendMessage":
+			sentTexts = append(sentTexts, r.Form.Get("text"))
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":77}}`))
+		case "/bottest-token/editMessageText":
+			editedTexts = append(editedTexts, r.Form.Get("text"))
+			editedIDs = append(editedIDs, r.Form.Get("message_id"))
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":77}}`))
+		default:
+			t.Fatalf("unexpected telegram API path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("test-token", server.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatalf("create bot api: %v", err)
+	}
+	channel.bot = bot
+
+	inbound := make(chan *bus.Message, 1)
+	channel.bus = &recordingBus{inbound: inbound}
+
+	// First answer to user message 10 is tracked as reply 77.
+	if err := channel.SendMessage(context.Background(), &bus.Message{
+		SessionID: "telegram:123",
+		Content:   "old answer",
+		Data:      map[string]interface{}{"reply_to_message_id": 10},
+	}); err != nil {
+		t.Fatalf("send first reply: %v", err)
+	}
+
+	channel.handleEditedMessage(&tgbotapi.Message{
+		MessageID: 10,
+		From:      &tgbotapi.User{ID: 5, UserName: "alice"},
+		Chat:      &tgbotapi.Chat{ID: 123},
+		Text:      "edited question",
+	})
+
+	var rerun *bus.Message
+	select {
+	case rerun = <-inbound:
+	default:
+		t.Fatal("expected edited message to be routed for a re-run")
+	}
+	if rerun.Content != "edited question" || rerun.SessionID != "telegram:123" {
+		t.Fatalf("unexpected re-run message: %+v", rerun)
+	}
+
+	// The agent answers the re-run with the inbound data echoed back.
+	if err := channel.SendMessage(context.Background(), &bus.Message{
+		SessionID: rerun.SessionID,
+		Content:   "new answer",
+		Data:      rerun.Data,
+	}); err != nil {
+		t.Fatalf("send re-run reply: %v", err)
+	}
+
+	if len(sentTexts) != 1 {
+		t.Fatalf("expected only the original reply to be sent, got %v", sentTexts)
+	}
+	if len(editedTexts) != 1 || editedTexts[0] != "new answer" || editedIDs[0] != "77" {
+		t.Fatalf("expected reply 77 to be edited with the new answer, got texts=%v ids=%v", editedTexts, editedIDs)
+	}
+}
+
+func TestEditedMessageIgnoredWhenRerunDisabled(t *testing.T) {
+	channel := newTestChannel(t)
+	channel.config = &config.TelegramConfig{}
+	inbound := make(chan *bus.Message, 1)
+	channel.bus = &recordingBus{inbound: inbound}
+	channel.trackReply(123, 10, 77)
+
+	channel.handleEditedMessage(&tgbotapi.Message{
+		MessageID: 10,
+		From:      &tgbotapi.User{ID: 5},
+		Chat:      &tgbotapi.Chat{ID: 123},
+		Text:      "edited question",
+	})
+
+	select {
+	case msg := <-inbound:
+		t.Fatalf("expected edit to be ignored, got %+v", msg)
+	default:
+	}
+}
+
+// recordingBus captures inbound messages and ignores everything else.
+type recordingBus struct {
+	bus.Bus
+	inbound chan *bus.Message
+}
+
+func (b *recordingBus) SendInbound(msg *bus.Message) error {
+	b.inbound <- msg
+	return nil
+}
+
+type recordingFeedback struct {
+	entries []feedback.Entry
+}
+
+func (r *recordingFeedback) Record(ctx context.Context, entry feedback.Entry) (feedback.Entry, error) {
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+func TestFeedbackButtonsRecordRatingForReply(t *testing.T) {
+	channel := newTestChannel(t)
+	channel.config = &config.TelegramConfig{}
+	store := &recordingFeedback{}
+	channel.feedback = store
+
+	var replyMarkup, editedMarkup, editedMessageID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		switch r.URL.Path {
+		case "/bottest-token/getMe":
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"testbot"}}`))
+		case "/bottest-token/sendMessage":
+			replyMarkup = r.Form.Get("reply_markup")
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":42}}`))
+		case "/bottest-token/editMessageReplyMarkup":
+			editedMarkup = r.Form.Get("reply_markup")
+			editedMessageID = r.Form.Get("message_id")
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":42}}`))
+		case "/bottest-token/answerCallbackQuery":
+			_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+		default:
+			t.Fatalf("unexpected telegram API path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("test-token", server.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatalf("create bot api: %v", err)
+	}
+	channel.bot = bot
+
+	if err := channel.SendMessage(context.Background(), &bus.Message{
+		SessionID: "telegram:123",
+		Content:   "the answer",
+		Data:      map[string]interface{}{"reply_to_message_id": 10},
+	}); err != nil {
+		t.Fatalf("send reply: %v", err)
+	}
+	if !strings.Contains(replyMarkup, "feedback:up") || !strings.Contains(replyMarkup, "feedback:down") {
+		t.Fatalf("expected rating buttons on the reply, got %q", replyMarkup)
+	}
+
+	channel.handleCallbackQuery(&tgbotapi.CallbackQuery{
+		ID:   "cb-1",
+		From: &tgbotapi.User{ID: 5, UserName: "alice"},
+		Data: "feedback:down",
+		Message: &tgbotapi.Message{
+			MessageID: 42,
+			Chat:      &tgbotapi.Chat{ID: 123},
+			Text:      "the answer",
+		},
+	})
+
+	if len(store.entries) != 1 {
+		t.Fatalf("expected one recorded rating, got %d", len(store.entries))
+	}
+	got := store.entries[0]
+	if got.MessageID != "telegram:42" || got.SessionID != "telegram:123" || got.UserID != "5" ||
+		got.Rating != feedback.RatingDown || got.Response != "the answer" {
+		t.Fatalf("unexpected feedback entry: %+v", got)
+	}
+	if editedMessageID != "42" || !strings.Contains(editedMarkup, "👎 ✓") {
+		t.Fatalf("expected the buttons to mark the choice, got id=%q markup=%q", editedMessageID, editedMarkup)
+	}
+}
+
+func TestSendAttachmentsUploadsImagesAsPhotosAndOthersAsDocuments(t *testing.T) {
+	channel := newTestChannel(t)
+
+	var uploads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bottest-token/getMe":
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"testbot"}}`))
+		case "/bottest-token/sendPhoto", "/bottest-token/sendDocument":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("parse multipart form: %v", err)
+			}
+			method := strings.TrimPrefix(r.URL.Path, "/bottest-token/")
+			uploads = append(uploads, method+"|"+r.FormValue("chat_id")+"|"+r.FormValue("caption"))
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":42}}`))
+		default:
+			t.Fatalf("unexpected telegram API path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("test-token", server.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatalf("create bot api: %v", err)
+	}
+	channel.bot = bot
+
+	dir := t.TempDir()
+	imagePath := filepath.Join(dir, "chart.png")
+	docPath := filepath.Join(dir, "report.pdf")
+	for _, path := range []string{imagePath, docPath} {
+		if err := os.WriteFile(path, []byte("data"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	if err := channel.SendAttachments(context.Background(), "telegram:123", "chart", []bus.Attachment{
+		bus.FileAttachment(imagePath),
+		bus.FileAttachment(docPath),
+	}); err != nil {
+		t.Fatalf("send attachments: %v", err)
+	}
+	err = channel.SendMessage(context.Background(), &bus.Message{
+		SessionID:   "telegram:123",
+		Content:     "generated",
+		Attachments: []bus.Attachment{{Type: bus.MessageTypeImage, Name: "plot.png", MIMEType: "image/png", Data: []byte("png")}},
+	})
+	if err != nil {
+		t.Fatalf("send message with attachment: %v", err)
+	}
+
+	want := []string{"sendPhoto|123|chart", "sendDocument|123|", "sendPhoto|123|generated"}
+	if strings.Join(uploads, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected uploads: got %v want %v", uploads, want)
+	}
+}
+
+type fakeSynthesizer struct {
+	texts []string
+}
+
+func (f *fakeSynthesizer) Synthesize(_ context.Context, text string) (tts.Audio, error) {
+	f.texts = append(f.texts, text)
+	return tts.Audio{Data: []byte("OggS"), MIMEType: "audio/ogg"}, nil
+}
+
+func TestSendMessageAddsVoiceNoteForVoiceReplies(t *testing.T) {
+	channel := newTestChannel(t)
+	channel.config = &config.TelegramConfig{}
+	synth := &fakeSynthesizer{}
+	channel.SetSynthesizer(synth)
+
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bottest-token/getMe":
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"testbot"}}`))
+		case "/bottest-token/sendMessage", "/bottest-token/sendVoice":
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					t.Fatalf("parse multipart form: %v", err)
+				}
+			}
+			method := strings.TrimPrefix(r.URL.Path, "/bottest-token/")
+			calls = append(calls, method+"|"+r.FormValue("reply_to_message_id"))
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":42}}`))
+		default:
+			t.Fatalf("unexpected telegram API path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("test-token", server.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatalf("create bot api: %v", err)
+	}
+	channel.bot = bot
+
+	if err := channel.SendMessage(context.Background(), &bus.Message{
+		SessionID: "telegram:123",
+		Content:   "**Sunny** today",
+		Data:      map[string]interface{}{"reply_to_message_id": 7, dataKeyVoiceReply: true},
+	}); err != nil {
+		t.Fatalf("send voice reply: %v", err)
+	}
+	if err := channel.SendMessage(context.Background(), &bus.Message{
+		SessionID: "telegram:123",
+		Content:   "text only",
+		Data:      map[string]interface{}{"reply_to_message_id": 8},
+	}); err != nil {
+		t.Fatalf("send text reply: %v", err)
+	}
+
+	want := []string{"sendMessage|", "sendVoice|7", "sendMessage|"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected calls: got %v want %v", calls, want)
+	}
+	if len(synth.texts) != 1 || synth.texts[0] != "**Sunny** today" {
+		t.Fatalf("unexpected synthesized texts %#v", synth.texts)
+	}
+}
+
+func TestSessionIDRespectsGroupSessionScope(t *testing.T) {
+	channel := newTestChannel(t)
+
+	cases := []struct {
+		scope  string
+		chatID int64
+		want   string
+	}{
+		{scope: "", chatID: -100123, want: "telegram:-100123"},
+		{scope: "chat", chatID: -100123, want: "telegram:-100123"},
+		{scope: "user", chatID: -100123, want: "telegram:-100123:5"},
+		{scope: "user", chatID: 5, want: "telegram:5"},
+	}
+	for _, tc := range cases {
+		channel.config = &config.TelegramConfig{SessionScope: tc.scope}
+		got := channel.sessionID(tc.chatID, 5)
+		if got != tc.want {
+			t.Fatalf("scope %q chat %d: got %q want %q", tc.scope, tc.chatID, got, tc.want)
+		}
+		chatID, err := channel.extractChatID(got)
+		if err != nil || chatID != tc.chatID {
+			t.Fatalf("extractChatID(%q) = %d, %v; want %d", got, chatID, err, tc.chatID)
+		}
+	}
+}
+
+func TestSendMessageRepliesToGroupChatForPerUserSession(t *testing.T) {
+	channel := newTestChannel(t)
+	channel.config = &config.TelegramConfig{SessionScope: "user"}
+
+	var chatIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bottest-token/getMe":
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"testbot"}}`))
+		case "/bottest-token/sendMessage":
+			if err := r.ParseForm(); err != nil {
+				t.Fatalf("parse form: %v", err)
+			}
+			chatIDs = append(chatIDs, r.Form.Get("chat_id"))
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":42}}`))
+		default:
+			t.Fatalf("unexpected telegram API path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("test-token", server.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatalf("create bot api: %v", err)
+	}
+	channel.bot = bot
+
+	if err := channel.SendMessage(context.Background(), &bus.Message{
+		SessionID: channel.sessionID(-100123, 5),
+		Content:   "hello",
+	}); err != nil {
+		t.Fatalf("send message: %v", err)
+	}
+	if len(chatIDs) != 1 || chatIDs[0] != "-100123" {
+		t.Fatalf("expected reply to group chat -100123, got %v", chatIDs)
+	}
+}
+
+func TestTelegramCommandSyncStateSkipsUnchangedCommands(t *testing.T) {
+	state := &telegramCommandSyncState{synced: map[string]string{}}
+	cmds := []tgbotapi.BotCommand{{Command: "help", Description: "Show help"}}
+	fingerprint := telegramCommandsFingerprint(cmds)
+
+	if state.unchanged("42", fingerprint) {
+		t.Fatalf("expected first sync to run")
+	}
+	state.record("42", fingerprint)
+	if !state.unchanged("42", fingerprint) {
+		t.Fatalf("expected unchanged commands to skip sync")
+	}
+	if state.unchanged("43", fingerprint) {
+		t.Fatalf("expected a different bot to sync")
+	}
+
+	cmds[0].Description = "Show all commands"
+	if state.unchanged("42", telegramCommandsFingerprint(cmds)) {
+		t.Fatalf("expected changed commands to sync")
+	}
+}
+
+func TestSendMessageStreamsReplyIntoThinkingMessage(t *testing.T) {
+	channel := newTestChannel(t)
+	channel.streamEditInterval = 50 * time.Millisecond
+
+	var mu sync.Mutex
+	var sentTexts, editedTexts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/bottest-token/getMe":
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"testbot"}}`))
+		case "/bottest-token/sendMessage":
+			sentTexts = append(sentTexts, r.Form.Get("text"))
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":90}}`))
+		case "/bottest-token/editMessageText":
+			if r.Form.Get("message_id") != "9" {
+				t.Errorf("expected edits of thinking message 9, got %s", r.Form.Get("message_id"))
+			}
+			editedTexts = append(editedTexts, r.Form.Get("text"))
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":9}}`))
+		default:
+			t.Errorf("unexpected telegram API path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("test-token", server.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatalf("create bot api: %v", err)
+	}
+	channel.bot = bot
+
+	data := map[string]interface{}{
+		"thinking_message_id":    9,
+		"reply_to_message_id":    10,
+		bus.DataKeyStreamReplies: true,
+	}
+	for _, partial := range []string{"Go", "Go is", "Go is a language"} {
+		if err := channel.SendMessage(context.Background(), &bus.Message{
+			SessionID: "telegram:123",
+			Type:      bus.MessageTypeStreamUpdate,
+			Content:   partial,
+			Data:      data,
+		}); err != nil {
+			t.Fatalf("send stream update: %v", err)
+		}
+	}
+	time.Sleep(4 * channel.streamEditInterval)
+
+	mu.Lock()
+	streamedEdits := append([]string(nil), editedTexts...)
+	mu.Unlock()
+	if len(streamedEdits) == 0 || len(streamedEdits) > 2 {
+		t.Fatalf("expected rapid updates to be batched into at most 2 edits, got %q", streamedEdits)
+	}
+	if streamedEdits[len(streamedEdits)-1] != "Go is a language" {
+		t.Fatalf("expected the latest partial text to be shown, got %q", streamedEdits)
+	}
+
+	if err := channel.SendMessage(context.Background(), &bus.Message{
+		SessionID: "telegram:123",
+		Content:   "Go is a language.",
+		Data:      data,
+	}); err != nil {
+		t.Fatalf("send final reply: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sentTexts) != 0 {
+		t.Fatalf("expected no new message for a streamed reply, got %q", sentTexts)
+	}
+	if last := editedTexts[len(editedTexts)-1]; last != "Go is a language." {
+		t.Fatalf("expected the final reply to replace the streamed text, got %q", last)
+	}
+	if channel.trackedReply(123, 10) != 9 {
+		t.Fatalf("expected the thinking message to be tracked as the reply")
+	}
+}
+
+func TestGroupMentionModeOnlyRoutesAddressedMessages(t *testing.T) {
+	channel := newTestChannel(t)
+	channel.config = &config.TelegramConfig{GroupMode: "mention"}
+	channel.commands = commands.NewRegistry()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bottest-token/getMe":
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"testbot"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+		}
+	}))
+	defer server.Close()
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("test-token", server.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatalf("create bot api: %v", err)
+	}
+	channel.bot = bot
+	inbound := make(chan *bus.Message, 4)
+	channel.bus = &recordingBus{inbound: inbound}
+
+	group := &tgbotapi.Chat{ID: -100123, Type: "supergroup"}
+	alice := &tgbotapi.User{ID: 5, UserName: "alice"}
+	cases := []struct {
+		name    string
+		message *tgbotapi.Message
+		want    string
+	}{
+		{
+			name:    "chatter",
+			message: &tgbotapi.Message{MessageID: 1, From: alice, Chat: group, Text: "lunch anyone?"},
+		},
+		{
+			name:    "other mention",
+			message: &tgbotapi.Message{MessageID: 2, From: alice, Chat: group, Text: "@testbot_fan hi", Entities: []tgbotapi.MessageEntity{{Type: "mention", Offset: 0, Length: 12}}},
+		},
+		{
+			name:    "mention",
+			message: &tgbotapi.Message{MessageID: 3, From: alice, Chat: group, Text: "🙂 @TestBot what time is it?", Entities: []tgbotapi.MessageEntity{{Type: "mention", Offset: 3, Length: 8}}},
+			want:    "🙂  what time is it?",
+		},
+		{
+			name:    "reply",
+			message: &tgbotapi.Message{MessageID: 4, From: alice, Chat: group, Text: "and tomorrow?", ReplyToMessage: &tgbotapi.Message{MessageID: 3, From: &tgbotapi.User{ID: 1}}},
+			want:    "and tomorrow?",
+		},
+		{
+			name:    "private",
+			message: &tgbotapi.Message{MessageID: 5, From: alice, Chat: &tgbotapi.Chat{ID: 5, Type: "private"}, Text: "hello"},
+			want:    "hello",
+		},
+	}
+	for _, tc := range cases {
+		channel.handleMessage(tc.message)
+		select {
+		case msg := <-inbound:
+			if tc.want == "" {
+				t.Fatalf("%s: expected message to be ignored, got %q", tc.name, msg.Content)
+			}
+			if msg.Content != tc.want {
+				t.Fatalf("%s: expected content %q, got %q", tc.name, tc.want, msg.Content)
+			}
+		default:
+			if tc.want != "" {
+				t.Fatalf("%s: expected message to be routed", tc.name)
+			}
+		}
+	}
+}
+
+func TestTopicSessionsFollowForumTopics(t *testing.T) {
+	channel := newTestChannel(t)
+	channel.config = &config.TelegramConfig{TopicSessions: true}
+
+	body := `{"ok":true,"result":[
+		{"update_id":1,"message":{"message_id":10,"message_thread_id":7,"is_topic_message":true,"chat":{"id":-100123,"type":"supergroup"},"text":"in topic"}},
+		{"update_id":2,"message":{"message_id":11,"message_thread_id":3,"chat":{"id":-100123,"type":"supergroup"},"text":"plain reply"}}
+	]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &topicTransport{topics: &channel.topics}}
+	resp, err := client.Get(server.URL + "/bottest-token/getUpdates")
+	if err != nil {
+		t.Fatalf("getUpdates: %v", err)
+	}
+	defer resp.Body.Close()
+	if data, _ := io.ReadAll(resp.Body); string(data) != body {
+		t.Fatalf("transport must pass the body through unchanged, got %q", data)
+	}
+
+	group := &tgbotapi.Chat{ID: -100123, Type: "supergroup"}
+	from := &tgbotapi.User{ID: 5}
+	if got := channel.messageSessionID(&tgbotapi.Message{MessageID: 10, Chat: group, From: from}); got != "telegram:-100123:topic-7" {
+		t.Fatalf("expected topic session, got %q", got)
+	}
+	if got := channel.messageSessionID(&tgbotapi.Message{MessageID: 11, Chat: group, From: from}); got != "telegram:-100123" {
+		t.Fatalf("expected replies outside topics to use the group session, got %q", got)
+	}
+
+	channel.config.SessionScope = "user"
+	got := channel.messageSessionID(&tgbotapi.Message{MessageID: 10, Chat: group, From: from})
+	if got != "telegram:-100123:topic-7:5" {
+		t.Fatalf("expected per-member topic session, got %q", got)
+	}
+	if chatID, err := channel.extractChatID(got); err != nil || chatID != -100123 {
+		t.Fatalf("expected replies to go to the group, got %d, %v", chatID, err)
+	}
+
+	channel.config.TopicSessions = false
+	if got := channel.messageSessionID(&tgbotapi.Message{MessageID: 10, Chat: group, From: from}); got != "telegram:-100123:5" {
+		t.Fatalf("expected topics to be ignored when disabled, got %q", got)
+	}
+}
+
+func TestPinCommandAppliesToForumTopicSession(t *testing.T) {
+	channel := newTestChannel(t)
+	channel.config = &config.TelegramConfig{TopicSessions: true}
+	sessionMgr := session.NewManager(t.TempDir(), config.DefaultConfig().Sessions)
+	channel.commands = commands.NewRegistry()
+	if err := commands.RegisterAdvancedCommands(channel.commands, commands.Dependencies{SessionManager: sessionMgr}); err != nil {
+		t.Fatalf("register commands: %v", err)
+	}
+
+	var replies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		switch r.URL.Path {
+		case "/bottest-token/getMe":
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"testbot"}}`))
+		default:
+			replies = append(replies, r.Form.Get("text"))
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":77}}`))
+		}
+	}))
+	defer server.Close()
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("test-token", server.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatalf("create bot api: %v", err)
+	}
+	channel.bot = bot
+
+	channel.topics.set(-100123, 10, 7)
+	channel.handleCommand(&tgbotapi.Message{
+		MessageID: 10,
+		From:      &tgbotapi.User{ID: 5, UserName: "alice"},
+		Chat:      &tgbotapi.Chat{ID: -100123, Type: "supergroup"},
+		Text:      "/pin Answer in English.",
+		Entities:  []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 4}},
+	})
+
+	if len(replies) == 0 || !strings.Contains(replies[len(replies)-1], "Pinned") {
+		t.Fatalf("expected pin confirmation, got %v", replies)
+	}
+	sess, err := sessionMgr.GetExisting("telegram:-100123:topic-7")
+	if err != nil {
+		t.Fatalf("expected pin in the topic session: %v", err)
+	}
+	if pins := sess.GetPins(); len(pins) != 1 || pins[0] != "Answer in English." {
+		t.Fatalf("unexpected topic pins: %#v", pins)
+	}
+	if _, err := sessionMgr.GetExisting("telegram:-100123"); err == nil {
+		t.Fatal("expected the group session to stay untouched")
+	}
+}
+
+func TestSyncSlashCommandsAddsParamHints(t *testing.T) {
+	channel := newTestChannel(t)
+	channel.config = &config.TelegramConfig{}
+	channel.commands = commands.NewRegistry()
+	if err := channel.commands.Register(&commands.Command{
+		Name:        "feedback",
+		Description: "Rate the latest reply",
+		Params: []commands.Param{
+			{Name: "rating", Required: true, Choices: []string{"up", "down"}},
+			{Name: "comment"},
+		},
+		Handler: func(ctx context.Context, req commands.CommandRequest) (commands.CommandResponse, error) {
+			return commands.CommandResponse{}, nil
+		},
+	}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	var synced []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bottest-token/getMe":
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"id":987654,"is_bot":true,"first_name":"Test","username":"testbot"}}`))
+		case "/bottest-token/setMyCommands":
+			if err := r.ParseForm(); err != nil {
+				t.Errorf("parse form: %v", err)
+			}
+			synced = append(synced, r.FormValue("commands"))
+			_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+		default:
+			t.Errorf("unexpected telegram API path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("test-token", server.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatalf("create bot api: %v", err)
+	}
+	channel.bot = bot
+
+	channel.syncSlashCommands()
+	if len(synced) == 0 {
+		t.Fatal("expected commands to be synced")
+	}
+	var cmds []tgbotapi.BotCommand
+	if err := json.Unmarshal([]byte(synced[0]), &cmds); err != nil {
+		t.Fatalf("decode commands: %v", err)
+	}
+	if len(cmds) != 1 || cmds[0].Description != "Rate the latest reply <up|down> [comment]" {
+		t.Fatalf("expected parameter hint in description, got %+v", cmds)
+	}
+}
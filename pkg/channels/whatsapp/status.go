@@ -0,0 +1,147 @@
+package whatsapp
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/bus"
+)
+
+// Bridge connection states reported by Status.
+const (
+	// StateDisconnected means the channel is stopped.
+	StateDisconnected = "disconnected"
+	// StateConnecting means the channel is dialing the bridge, or the bridge
+	// is connected but still linking to WhatsApp.
+	StateConnecting = "connecting"
+	// StatePairing means the bridge waits for its QR code to be scanned with
+	// the WhatsApp app.
+	StatePairing = "pairing"
+	// StateConnected means the bridge is connected and linked to WhatsApp.
+	StateConnected = "connected"
+	// StateReconnecting means the bridge connection failed and the channel
+	// waits before dialing again.
+	StateReconnecting = "reconnecting"
+)
+
+// Reconnect backoff: the delay doubles after every failed attempt, from
+// reconnectInitialDelay up to reconnectMaxDelay.
+var (
+	reconnectInitialDelay = time.Second
+	reconnectMaxDelay     = time.Minute
+)
+
+// Status is the bridge connection and pairing state of a channel.
+type Status struct {
+	ChannelID string `json:"channel_id"`
+	BridgeURL string `json:"bridge_url"`
+	State     string `json:"state"`
+	// QRCode is the pairing code to render as a QR image while pairing.
+	QRCode            string     `json:"qr_code,omitempty"`
+	LastError         string     `json:"last_error,omitempty"`
+	ReconnectAttempts int        `json:"reconnect_attempts"`
+	NextRetryAt       *time.Time `json:"next_retry_at,omitempty"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// reconnectDelay returns the delay before reconnect attempt n (from 1).
+func reconnectDelay(attempt int) time.Duration {
+	delay := reconnectInitialDelay
+	for i := 1; i < attempt && delay < reconnectMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, reconnectMaxDelay)
+}
+
+// Status returns the current bridge state.
+func (c *Channel) Status() Status {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+	return c.status
+}
+
+// Subscribe returns a channel receiving the status after every change,
+// starting with the current one, and a function ending the subscription.
+// The channel is closed when the subscription ends or the channel stops.
+func (c *Channel) Subscribe() (<-chan Status, func()) {
+	ch := make(chan Status, 8)
+	c.statusMu.Lock()
+	ch <- c.status
+	if c.subscribers == nil {
+		c.subscribers = map[chan Status]struct{}{}
+	}
+	c.subscribers[ch] = struct{}{}
+	c.statusMu.Unlock()
+
+	return ch, func() {
+		c.statusMu.Lock()
+		defer c.statusMu.Unlock()
+		if _, ok := c.subscribers[ch]; ok {
+			delete(c.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// closeSubscribers ends every subscription.
+func (c *Channel) closeSubscribers() {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+	for ch := range c.subscribers {
+		close(ch)
+	}
+	c.subscribers = nil
+}
+
+// updateStatus applies fn to the status, notifies subscribers and, when the
+// state changed, publishes a runtime event.
+func (c *Channel) updateStatus(fn func(*Status)) {
+	c.statusMu.Lock()
+	previous := c.status.State
+	fn(&c.status)
+	c.status.UpdatedAt = time.Now()
+	current := c.status
+	for ch := range c.subscribers {
+		select {
+		case ch <- current:
+		default:
+			// A slow subscriber misses intermediate states; the next
+			// update carries the whole status again.
+		}
+	}
+	c.statusMu.Unlock()
+
+	if current.State == previous {
+		return
+	}
+	c.log.Info("WhatsApp bridge state changed",
+		zap.String("channel", c.id),
+		zap.String("state", current.State))
+	if err := bus.PublishEvent(c.bus, bus.EventChannelStatus, "", statusEventContent(c.name, current), map[string]string{
+		"channel":      c.id,
+		"channel_type": c.channelType,
+		"state":        current.State,
+	}); err != nil {
+		c.log.Warn("Failed to publish WhatsApp status event", zap.Error(err))
+	}
+}
+
+func statusEventContent(name string, status Status) string {
+	switch status.State {
+	case StateReconnecting:
+		content := fmt.Sprintf("%s bridge connection lost, reconnect attempt %d", name, status.ReconnectAttempts)
+		if status.NextRetryAt != nil {
+			content += " at " + status.NextRetryAt.Format(time.RFC3339)
+		}
+		if status.LastError != "" {
+			content += ": " + status.LastError
+		}
+		return content
+	case StatePairing:
+		return name + " bridge is waiting for its QR code to be scanned"
+	default:
+		return fmt.Sprintf("%s bridge is %s", name, status.State)
+	}
+}
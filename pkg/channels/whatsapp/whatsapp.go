@@ -30,12 +30,16 @@ type Channel struct {
 	channelType string
 	name        string
 
-	conn      *websocket.Conn
-	mu        sync.Mutex
-	connected bool
-	running   bool
-	ctx       context.Context
-	cancel    context.CancelFunc
+	conn    *websocket.Conn
+	mu      sync.Mutex
+	running bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	statusMu    sync.Mutex
+	status      Status
+	subscribers map[chan Status]struct{}
 }
 
 // NewChannel creates a new WhatsApp channel.
@@ -61,16 +65,22 @@ func NewAccountChannel(
 		return nil, fmt.Errorf("whatsapp bridge_url is required")
 	}
 
+	id := strings.TrimSpace(channelID)
 	return &Channel{
 		log:         log,
 		config:      cfg,
 		bus:         b,
 		commands:    cmdRegistry,
-		id:          strings.TrimSpace(channelID),
+		id:          id,
 		channelType: "whatsapp",
 		name:        defaultWhatsAppName(displayName),
-		connected:   false,
 		running:     false,
+		status: Status{
+			ChannelID: id,
+			BridgeURL: cfg.BridgeURL,
+			State:     StateDisconnected,
+			UpdatedAt: time.Now(),
+		},
 	}, nil
 }
 
@@ -94,23 +104,19 @@ func (c *Channel) IsEnabled() bool {
 	return c.config.Enabled
 }
 
-// Start starts the WhatsApp channel and connects to the bridge.
+// Start starts the WhatsApp channel. The bridge is connected in the
+// background and reconnected with exponential backoff whenever it drops, so
+// the channel starts even while the bridge is down.
 func (c *Channel) Start(ctx context.Context) error {
 	c.log.Info("Starting WhatsApp channel", zap.String("bridge_url", c.config.BridgeURL))
 
 	c.ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+	c.running = true
 
-	// Connect to bridge
-	if err := c.connect(); err != nil {
-		return fmt.Errorf("connecting to WhatsApp bridge: %w", err)
-	}
-
-	// Start listening
-	go c.listen()
+	go c.run(c.ctx)
 
-	c.running = true
 	c.log.Info("WhatsApp channel started")
-
 	return nil
 }
 
@@ -124,94 +130,164 @@ func (c *Channel) Stop(ctx context.Context) error {
 		c.cancel()
 	}
 
-	// Close connection
+	// Close the connection to unblock the read loop.
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	if c.conn != nil {
 		if err := c.conn.Close(); err != nil {
 			c.log.Warn("Error closing WhatsApp connection", zap.Error(err))
 		}
 		c.conn = nil
 	}
+	c.mu.Unlock()
 
-	c.connected = false
+	if c.done != nil {
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+		}
+	}
+
+	c.updateStatus(func(s *Status) {
+		s.State = StateDisconnected
+		s.QRCode = ""
+		s.NextRetryAt = nil
+	})
+	c.closeSubscribers()
 	c.log.Info("WhatsApp channel stopped")
 
 	return nil
 }
 
+// run keeps the bridge connected until ctx ends.
+func (c *Channel) run(ctx context.Context) {
+	defer close(c.done)
+
+	attempt := 0
+	for {
+		c.updateStatus(func(s *Status) {
+			s.State = StateConnecting
+			s.NextRetryAt = nil
+		})
+		conn, err := c.connect(ctx)
+		if err == nil {
+			attempt = 0
+			err = c.listen(ctx, conn)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		attempt++
+		delay := reconnectDelay(attempt)
+		retryAt := time.Now().Add(delay)
+		c.log.Warn("WhatsApp bridge connection lost, reconnecting",
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+		c.updateStatus(func(s *Status) {
+			s.State = StateReconnecting
+			s.QRCode = ""
+			s.LastError = err.Error()
+			s.ReconnectAttempts = attempt
+			s.NextRetryAt = &retryAt
+		})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
 // connect establishes WebSocket connection to the bridge.
-func (c *Channel) connect() error {
-	dialer := websocket.DefaultDialer
+func (c *Channel) connect(ctx context.Context) (*websocket.Conn, error) {
+	dialer := *websocket.DefaultDialer
 	dialer.HandshakeTimeout = 10 * time.Second
 
-	conn, _, err := dialer.Dial(c.config.BridgeURL, nil)
+	conn, _, err := dialer.DialContext(ctx, c.config.BridgeURL, nil)
 	if err != nil {
-		return fmt.Errorf("dialing bridge: %w", err)
+		return nil, fmt.Errorf("dialing bridge: %w", err)
 	}
 
 	c.mu.Lock()
 	c.conn = conn
-	c.connected = true
 	c.mu.Unlock()
 
+	// Bridges that do not report their WhatsApp link are taken as linked.
+	c.updateStatus(func(s *Status) {
+		s.State = StateConnected
+		s.LastError = ""
+		s.ReconnectAttempts = 0
+	})
 	c.log.Info("Connected to WhatsApp bridge")
-	return nil
+	return conn, nil
 }
 
-// listen listens for messages from the bridge.
-func (c *Channel) listen() {
-	for {
-		select {
-		case <-c.ctx.Done():
-			return
-		default:
-			c.mu.Lock()
-			conn := c.conn
-			c.mu.Unlock()
-
-			if conn == nil {
-				if c.running {
-					// Try to reconnect
-					c.log.Warn("WhatsApp connection lost, reconnecting...")
-					time.Sleep(2 * time.Second)
-					if err := c.connect(); err != nil {
-						c.log.Error("Failed to reconnect", zap.Error(err))
-					}
-				}
-				continue
-			}
+// listen reads messages from the bridge until the connection fails.
+func (c *Channel) listen(ctx context.Context, conn *websocket.Conn) error {
+	defer func() {
+		c.mu.Lock()
+		if c.conn == conn {
+			c.conn = nil
+		}
+		c.mu.Unlock()
+		_ = conn.Close()
+	}()
 
-			// Read message
-			_, message, err := conn.ReadMessage()
-			if err != nil {
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() == nil {
 				c.log.Error("WhatsApp read error", zap.Error(err))
-				c.mu.Lock()
-				c.connected = false
-				c.conn = nil
-				c.mu.Unlock()
-				time.Sleep(2 * time.Second)
-				continue
 			}
+			return fmt.Errorf("reading from bridge: %w", err)
+		}
 
-			// Parse message
-			var msg map[string]interface{}
-			if err := json.Unmarshal(message, &msg); err != nil {
-				c.log.Warn("Failed to unmarshal message", zap.Error(err))
-				continue
-			}
+		var msg map[string]interface{}
+		if err := json.Unmarshal(message, &msg); err != nil {
+			c.log.Warn("Failed to unmarshal message", zap.Error(err))
+			continue
+		}
+		c.handleBridgeMessage(msg)
+	}
+}
 
-			// Handle message based on type
-			msgType, ok := msg["type"].(string)
-			if !ok {
-				continue
-			}
+// handleBridgeMessage dispatches a message from the bridge by its type.
+func (c *Channel) handleBridgeMessage(msg map[string]interface{}) {
+	msgType, ok := msg["type"].(string)
+	if !ok {
+		return
+	}
 
-			if msgType == "message" {
-				c.handleInbound(msg)
-			}
+	switch msgType {
+	case "message":
+		c.handleInbound(msg)
+	case "qr":
+		code := getStringOrDefault(msg, "qr", "")
+		if code == "" {
+			return
+		}
+		c.updateStatus(func(s *Status) {
+			s.State = StatePairing
+			s.QRCode = code
+		})
+	case "status":
+		state := StateConnecting
+		if getStringOrDefault(msg, "status", "") == "connected" {
+			state = StateConnected
 		}
+		c.updateStatus(func(s *Status) {
+			s.State = state
+			s.QRCode = ""
+		})
+	case "error":
+		c.log.Warn("WhatsApp bridge reported an error", zap.String("error", getStringOrDefault(msg, "error", "")))
+		c.updateStatus(func(s *Status) {
+			s.LastError = getStringOrDefault(msg, "error", "")
+		})
 	}
 }
 
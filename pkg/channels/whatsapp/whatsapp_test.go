@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 
@@ -170,8 +172,122 @@ func TestSendMessagePrependsToolTraceFromBusMetadata(t *testing.T) {
 	}
 }
 
+func TestReconnectDelayDoublesUpToMax(t *testing.T) {
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second, time.Minute, time.Minute}
+	for i, delay := range want {
+		if got := reconnectDelay(i + 1); got != delay {
+			t.Fatalf("attempt %d: expected %s, got %s", i+1, delay, got)
+		}
+	}
+}
+
+func TestChannelTracksPairingAndReconnectsToBridge(t *testing.T) {
+	originalInitial, originalMax := reconnectInitialDelay, reconnectMaxDelay
+	reconnectInitialDelay, reconnectMaxDelay = 10*time.Millisecond, 40*time.Millisecond
+	t.Cleanup(func() {
+		reconnectInitialDelay, reconnectMaxDelay = originalInitial, originalMax
+	})
+
+	// The first connection pairs and is then dropped by the bridge; the
+	// second reports an established WhatsApp link.
+	var connections int
+	var connMu sync.Mutex
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		connMu.Lock()
+		connections++
+		n := connections
+		connMu.Unlock()
+
+		if n == 1 {
+			_ = conn.WriteJSON(map[string]string{"type": "qr", "qr": "2@pairing-code"})
+			time.Sleep(50 * time.Millisecond)
+			return
+		}
+		_ = conn.WriteJSON(map[string]string{"type": "status", "status": "connected"})
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	messageBus := &stubBus{}
+	channel, err := NewChannel(newTestLogger(t), config.WhatsAppConfig{
+		Enabled:   true,
+		BridgeURL: "ws" + strings.TrimPrefix(server.URL, "http"),
+	}, messageBus, commands.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewChannel failed: %v", err)
+	}
+	updates, cancel := channel.Subscribe()
+	defer cancel()
+	if first := <-updates; first.State != StateDisconnected {
+		t.Fatalf("expected a stopped channel to be disconnected, got %q", first.State)
+	}
+
+	if err := channel.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer channel.Stop(context.Background())
+
+	var seen []string
+	sawQR := false
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case status := <-updates:
+			if len(seen) == 0 || seen[len(seen)-1] != status.State {
+				seen = append(seen, status.State)
+			}
+			if status.State == StatePairing && status.QRCode == "2@pairing-code" {
+				sawQR = true
+			}
+			if status.State == StateReconnecting && (status.ReconnectAttempts != 1 || status.NextRetryAt == nil || status.LastError == "") {
+				t.Fatalf("expected reconnect details, got %+v", status)
+			}
+		case <-deadline:
+			t.Fatalf("expected the channel to reconnect, saw states %v", seen)
+		}
+		if sawQR && seen[len(seen)-1] == StateConnected && containsState(seen, StateReconnecting) {
+			break
+		}
+	}
+	if current := channel.Status(); current.QRCode != "" || current.ReconnectAttempts != 0 {
+		t.Fatalf("expected the pairing code and attempts to clear once connected, got %+v", current)
+	}
+
+	if !messageBus.hasEvent(StateReconnecting) || !messageBus.hasEvent(StatePairing) {
+		t.Fatalf("expected state changes on the bus, got %d events", len(messageBus.events()))
+	}
+
+	if err := channel.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	for status := range updates {
+		if status.State == StateDisconnected {
+			return
+		}
+	}
+	t.Fatal("expected a disconnected status before the subscription closed")
+}
+
+func containsState(states []string, state string) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
 type stubBus struct {
 	inbound []*bus.Message
+
+	mu       sync.Mutex
+	outbound []*bus.Message
 }
 
 func (b *stubBus) Start() error                                                  { return nil }
@@ -186,8 +302,28 @@ func (b *stubBus) SendInbound(msg *bus.Message) error {
 	b.inbound = append(b.inbound, msg)
 	return nil
 }
-func (b *stubBus) SendOutbound(msg *bus.Message) error { return nil }
-func (b *stubBus) GetMetrics() map[string]uint64       { return map[string]uint64{} }
+func (b *stubBus) SendOutbound(msg *bus.Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.outbound = append(b.outbound, msg)
+	return nil
+}
+func (b *stubBus) GetMetrics() map[string]uint64 { return map[string]uint64{} }
+
+func (b *stubBus) events() []*bus.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*bus.Message(nil), b.outbound...)
+}
+
+func (b *stubBus) hasEvent(state string) bool {
+	for _, msg := range b.events() {
+		if msg.Data[bus.DataKeyEvent] == bus.EventChannelStatus && msg.Data["state"] == state && msg.Data["channel"] == "whatsapp" {
+			return true
+		}
+	}
+	return false
+}
 
 func newTestLogger(t *testing.T) *logger.Logger {
 	t.Helper()
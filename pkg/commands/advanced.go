@@ -7,8 +7,10 @@ import (
 	"sync"
 
 	"nekobot/pkg/agent"
+	"nekobot/pkg/approval"
 	"nekobot/pkg/config"
 	"nekobot/pkg/message"
+	"nekobot/pkg/session"
 	"nekobot/pkg/skills"
 	"nekobot/pkg/userprefs"
 )
@@ -38,6 +40,13 @@ type Dependencies struct {
 	ChannelManager    ChannelManager
 	UserPrefs         *userprefs.Manager
 	GatewayController GatewayController
+	SessionManager    *session.Manager
+	TaskScheduler     TaskScheduler
+	BackgroundJobs    BackgroundJobs
+	Feedback          FeedbackRecorder
+	Approvals         ApprovalDecider
+	Models            ModelCatalog
+	Plugins           PluginManager
 }
 
 // RegisterAdvancedCommands registers advanced commands that require dependencies.
@@ -45,9 +54,23 @@ func RegisterAdvancedCommands(registry *Registry, deps Dependencies) error {
 	advancedCmds := []*Command{
 		{
 			Name:        "model",
-			Description: "List or switch AI models",
-			Usage:       "/model [provider] or /model list",
-			Handler:     modelHandler(deps.Config),
+			Description: "Show, list or switch the model for this chat",
+			Usage:       "/model [list|reset|<model>]",
+			Params: []Param{{
+				Name:        "model",
+				Description: "Model to use in this chat, list, or reset; omit to show the current one",
+			}},
+			Handler: modelHandler(deps.Config, deps.Models, deps.SessionManager),
+		},
+		{
+			Name:        "provider",
+			Description: "Show, list or switch the provider and fallback chain for this chat",
+			Usage:       "/provider [list|reset|<name>|fallback [<a,b,...>|reset]]",
+			Params: []Param{
+				{Name: "provider", Description: "Provider to use in this chat, list, reset, or fallback"},
+				{Name: "chain", Description: "Fallback providers for /provider fallback, comma separated, or reset"},
+			},
+			Handler: providerHandler(deps.Config, deps.Models, deps.SessionManager),
 		},
 		{
 			Name:        "gateway",
@@ -56,17 +79,125 @@ func RegisterAdvancedCommands(registry *Registry, deps Dependencies) error {
 			Handler:     gatewayHandler(deps.ChannelManager, deps.GatewayController),
 			AdminOnly:   true,
 		},
+		{
+			Name:        "plugins",
+			Description: "List tool plugins, install or uninstall WASM tools",
+			Usage:       "/plugins [list|install <dir, .zip or URL>|uninstall <name>]",
+			Handler:     pluginsHandler(deps.Plugins),
+			AdminOnly:   true,
+		},
 		{
 			Name:        "settings",
 			Description: "Set per-channel language/name/preferences/skill install mode",
-			Usage:       "/settings [show|lang <zh|en|ja>|name <text>|prefs <text>|skillmode <legacy|npx>|clear]",
+			Usage:       "/settings [show|lang <zh|en|ja>|name <text>|prefs <text>|skillmode <legacy|npx>|voice <on|off>|clear]",
 			Handler:     settingsHandler(deps.UserPrefs),
 		},
+		{
+			Name:        "lang",
+			Description: "Switch your reply language",
+			Params: []Param{{
+				Name:        "language",
+				Description: "Language code; omit to show the current one",
+				Type:        ParamEnum,
+				Choices:     userprefs.SupportedLanguages,
+			}},
+			Handler: langHandler(deps.UserPrefs),
+		},
+		{
+			Name:        "link",
+			Description: "Link this account with your other channels to share one conversation",
+			Params: []Param{{
+				Name:        "code",
+				Description: "Code issued by /link on your other account",
+			}},
+			Handler: linkHandler(deps.UserPrefs),
+		},
+		{
+			Name:        "unlink",
+			Description: "Stop sharing this chat's conversation with linked accounts",
+			Usage:       "/unlink",
+			Handler:     unlinkHandler(deps.UserPrefs),
+		},
 		{
 			Name:        "agent",
-			Description: "Switch agent or show agent info",
-			Usage:       "/agent [name]",
-			Handler:     agentHandler(deps.Config),
+			Description: "Show or switch the agent profile for this chat",
+			Usage:       "/agent [info|list|use <name>]",
+			Handler:     agentHandler(deps.Config, deps.SessionManager),
+		},
+		{
+			Name:        "pin",
+			Description: "Pin context that stays in every prompt for this chat",
+			Params: []Param{{
+				Name:        "text",
+				Description: "Context to pin; omit to list pins",
+			}},
+			Handler: pinHandler(deps.SessionManager),
+		},
+		{
+			Name:        "unpin",
+			Description: "Remove pinned context",
+			Usage:       "/unpin <number|all>",
+			Params: []Param{{
+				Name:        "pin",
+				Description: "Pin number from /pins, or all",
+				Required:    true,
+				Choices:     []string{"all"},
+			}},
+			Handler: unpinHandler(deps.SessionManager),
+		},
+		{
+			Name:        "pins",
+			Description: "List pinned context for this chat",
+			Usage:       "/pins",
+			Handler:     pinsHandler(deps.SessionManager),
+		},
+		{
+			Name:        "orchestrator",
+			Description: "Show or switch the orchestrator for this chat",
+			Params: []Param{{
+				Name:        "orchestrator",
+				Description: "Orchestrator to use; omit to show the current one",
+				Choices:     []string{"blades", "legacy", "default"},
+			}},
+			Handler: orchestratorHandler(deps.Config, deps.SessionManager),
+		},
+		{
+			Name:        "persona",
+			Description: "Show or set a custom system prompt for this chat",
+			Usage:       "/persona [text|reset]",
+			Handler:     personaHandler(deps.SessionManager),
+		},
+		{
+			Name:        "tasks",
+			Description: "List or cancel your scheduled reminders and background jobs",
+			Usage:       "/tasks [cancel <id>]",
+			Handler:     tasksHandler(deps.TaskScheduler, deps.BackgroundJobs),
+		},
+		{
+			Name:        "feedback",
+			Description: "Rate the latest reply with thumbs up or down",
+			Params: []Param{
+				{Name: "rating", Description: "up or down", Required: true, Choices: []string{"up", "down"}},
+				{Name: "comment", Description: "What was good or wrong"},
+			},
+			Handler: feedbackHandler(deps.Feedback, deps.SessionManager),
+		},
+		{
+			Name:        "approve",
+			Description: "Approve a tool call waiting for approval",
+			Params: []Param{
+				{Name: "id", Description: "Approval request ID", Required: true},
+			},
+			Handler: approvalDecisionHandler(deps.Approvals, approval.Approved),
+		},
+		{
+			Name:        "deny",
+			Description: "Deny a tool call waiting for approval",
+			Params: []Param{
+				{Name: "id", Description: "Approval request ID", Required: true},
+				{Name: "reason", Description: "Why the call is denied"},
+			},
+			Handler: approvalDecisionHandler(deps.Approvals, approval.Denied),
 		},
 	}
 
@@ -86,99 +217,6 @@ func RegisterAdvancedCommands(registry *Registry, deps Dependencies) error {
 	return nil
 }
 
-// modelHandler handles the /model command.
-func modelHandler(cfg *config.Config) CommandHandler {
-	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
-		args := strings.TrimSpace(req.Args)
-		parts := strings.Fields(args)
-		if len(parts) > 1 {
-			return CommandResponse{
-				Content:     "❌ 用法: /model [list|<provider>]",
-				ReplyInline: true,
-			}, nil
-		}
-
-		// List models
-		if args == "" || args == "list" {
-			var sb strings.Builder
-			sb.WriteString("🤖 **Available Providers**\n\n")
-
-			// List all configured providers from profiles
-			hasProviders := len(cfg.Providers) > 0
-
-			for _, profile := range cfg.Providers {
-				_, _ = fmt.Fprintf(&sb, "**%s** (%s)\n", profile.Name, profile.ProviderKind)
-				if profile.APIBase != "" {
-					_, _ = fmt.Fprintf(&sb, "  Base: %s\n", profile.APIBase)
-				}
-				if len(profile.Models) > 0 {
-					_, _ = fmt.Fprintf(&sb, "  Models: %d configured\n", len(profile.Models))
-				}
-				if profile.DefaultModel != "" {
-					_, _ = fmt.Fprintf(&sb, "  Default: %s\n", profile.DefaultModel)
-				}
-				if profile.Timeout > 0 {
-					_, _ = fmt.Fprintf(&sb, "  Timeout: %ds\n", profile.Timeout)
-				}
-				sb.WriteString("\n")
-			}
-
-			if !hasProviders {
-				sb.WriteString("No providers configured.\n")
-			}
-
-			sb.WriteString("\nUse `/model <provider>` to get provider info.")
-
-			return CommandResponse{
-				Content:     sb.String(),
-				ReplyInline: true,
-			}, nil
-		}
-
-		// Show provider info
-		providerName := strings.ToLower(args)
-		var providerProfile *config.ProviderProfile
-		found := false
-
-		// Search for provider in providers
-		for i := range cfg.Providers {
-			if strings.ToLower(cfg.Providers[i].Name) == providerName {
-				providerProfile = &cfg.Providers[i]
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			return CommandResponse{
-				Content:     fmt.Sprintf("❌ Provider '%s' not found or not configured. Use `/model list` to see available providers.", providerName),
-				ReplyInline: true,
-			}, nil
-		}
-
-		var sb strings.Builder
-		_, _ = fmt.Fprintf(&sb, "🤖 **Provider: %s**\n\n", providerName)
-		_, _ = fmt.Fprintf(&sb, "Type: %s\n", providerProfile.ProviderKind)
-		if providerProfile.APIBase != "" {
-			_, _ = fmt.Fprintf(&sb, "Base URL: %s\n", providerProfile.APIBase)
-		}
-		if len(providerProfile.Models) > 0 {
-			_, _ = fmt.Fprintf(&sb, "Models: %d configured\n", len(providerProfile.Models))
-			if providerProfile.DefaultModel != "" {
-				_, _ = fmt.Fprintf(&sb, "Default Model: %s\n", providerProfile.DefaultModel)
-			}
-		}
-		if providerProfile.Timeout > 0 {
-			_, _ = fmt.Fprintf(&sb, "Timeout: %ds\n", providerProfile.Timeout)
-		}
-
-		return CommandResponse{
-			Content:     sb.String(),
-			ReplyInline: true,
-		}, nil
-	}
-}
-
 // gatewayHandler handles the /gateway command.
 func gatewayHandler(channelMgr ChannelManager, ctrl GatewayController) CommandHandler {
 	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
@@ -263,79 +301,6 @@ func gatewayHandler(channelMgr ChannelManager, ctrl GatewayController) CommandHa
 	}
 }
 
-// agentHandler handles the /agent command.
-func agentHandler(cfg *config.Config) CommandHandler {
-	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
-		args := strings.TrimSpace(req.Args)
-
-		// Show current agent info
-		if args == "" || args == "info" {
-			var sb strings.Builder
-			sb.WriteString("🤖 **Agent Information**\n\n")
-
-			// Show default provider
-			defaultProvider := cfg.Agents.Defaults.Provider
-			if defaultProvider == "" {
-				defaultProvider = "Not configured"
-			}
-
-			_, _ = fmt.Fprintf(&sb, "Default Provider: **%s**\n", defaultProvider)
-			_, _ = fmt.Fprintf(&sb, "Model: %s\n", cfg.Agents.Defaults.Model)
-			_, _ = fmt.Fprintf(&sb, "Max Tokens: %d\n", cfg.Agents.Defaults.MaxTokens)
-			_, _ = fmt.Fprintf(&sb, "Temperature: %.2f\n", cfg.Agents.Defaults.Temperature)
-
-			sb.WriteString("\nUse `/agent list` to see all available providers.")
-
-			return CommandResponse{
-				Content:     sb.String(),
-				ReplyInline: true,
-			}, nil
-		}
-
-		parts := strings.Fields(args)
-		if len(parts) > 1 {
-			return CommandResponse{
-				Content:     "❌ 用法: /agent [info|list]",
-				ReplyInline: true,
-			}, nil
-		}
-
-		// List available agents/providers
-		if args == "list" {
-			var sb strings.Builder
-			sb.WriteString("🤖 **Available Providers**\n\n")
-
-			providers := []string{
-				"anthropic", "openai", "openrouter", "groq",
-				"zhipu", "vllm", "gemini", "nvidia",
-				"moonshot", "deepseek",
-			}
-
-			currentProvider := cfg.Agents.Defaults.Provider
-			for _, p := range providers {
-				prefix := "  "
-				if p == currentProvider {
-					prefix = "→ " // Current
-				}
-				_, _ = fmt.Fprintf(&sb, "%s**%s**\n", prefix, p)
-			}
-
-			sb.WriteString("\nUse `/model <provider>` to see provider details.")
-
-			return CommandResponse{
-				Content:     sb.String(),
-				ReplyInline: true,
-			}, nil
-		}
-
-		// Show provider info
-		return CommandResponse{
-			Content:     fmt.Sprintf("❌ Unsupported agent action: %s\n\nAvailable: info, list", args),
-			ReplyInline: true,
-		}, nil
-	}
-}
-
 func settingsHandler(prefsMgr *userprefs.Manager) CommandHandler {
 	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
 		if prefsMgr == nil {
@@ -365,8 +330,8 @@ func settingsHandler(prefsMgr *userprefs.Manager) CommandHandler {
 		switch action {
 		case "lang", "language":
 			lang := strings.ToLower(strings.TrimSpace(value))
-			if lang != "zh" && lang != "en" && lang != "ja" {
-				return CommandResponse{Content: "❌ 仅支持: zh / en / ja", ReplyInline: true}, nil
+			if !userprefs.IsSupportedLanguage(lang) {
+				return CommandResponse{Content: "❌ 仅支持: " + strings.Join(userprefs.SupportedLanguages, " / "), ReplyInline: true}, nil
 			}
 			profile.Language = userprefs.NormalizeLanguage(lang)
 			if err := prefsMgr.Save(ctx, channel, userID, profile); err != nil {
@@ -414,6 +379,23 @@ func settingsHandler(prefsMgr *userprefs.Manager) CommandHandler {
 			}
 			return CommandResponse{Content: "✅ Skills 安装方式已更新为: 当前方式", ReplyInline: true}, nil
 
+		case "voice", "voice_replies":
+			switch strings.ToLower(strings.TrimSpace(value)) {
+			case "on", "true", "1":
+				profile.VoiceReplies = true
+			case "off", "false", "0":
+				profile.VoiceReplies = false
+			default:
+				return CommandResponse{Content: "❌ 用法: /settings voice <on|off>", ReplyInline: true}, nil
+			}
+			if err := prefsMgr.Save(ctx, channel, userID, profile); err != nil {
+				return CommandResponse{Content: "❌ 保存失败: " + err.Error(), ReplyInline: true}, nil
+			}
+			if profile.VoiceReplies {
+				return CommandResponse{Content: "✅ 语音回复已开启：发送语音消息时将收到语音回复", ReplyInline: true}, nil
+			}
+			return CommandResponse{Content: "✅ 语音回复已关闭", ReplyInline: true}, nil
+
 		case "clear", "reset":
 			if err := prefsMgr.Clear(ctx, channel, userID); err != nil {
 				return CommandResponse{Content: "❌ 清除失败: " + err.Error(), ReplyInline: true}, nil
@@ -421,7 +403,7 @@ func settingsHandler(prefsMgr *userprefs.Manager) CommandHandler {
 			return CommandResponse{Content: "✅ 设置已清除", ReplyInline: true}, nil
 
 		default:
-			return CommandResponse{Content: "ℹ️ 用法: /settings [show|lang <zh|en|ja>|name <text>|prefs <text>|skillmode <legacy|npx>|clear]", ReplyInline: true}, nil
+			return CommandResponse{Content: "ℹ️ 用法: /settings [show|lang <zh|en|ja>|name <text>|prefs <text>|skillmode <legacy|npx>|voice <on|off>|clear]", ReplyInline: true}, nil
 		}
 	}
 }
@@ -446,7 +428,12 @@ func formatSettings(p userprefs.Profile) string {
 		modeLabel = "npx 优先"
 	}
 
-	return fmt.Sprintf("⚙️ 当前设置\n\n语言: %s\n称呼: %s\n偏好: %s\nSkills安装: %s\n\n用法:\n/settings lang <zh|en|ja>\n/settings name <称呼>\n/settings prefs <偏好描述>\n/settings skillmode <legacy|npx>\n/settings clear", lang, name, prefs, modeLabel)
+	voice := "关闭"
+	if p.VoiceReplies {
+		voice = "开启"
+	}
+
+	return fmt.Sprintf("⚙️ 当前设置\n\n语言: %s\n称呼: %s\n偏好: %s\nSkills安装: %s\n语音回复: %s\n\n用法:\n/settings lang <zh|en|ja>\n/settings name <称呼>\n/settings prefs <偏好描述>\n/settings skillmode <legacy|npx>\n/settings voice <on|off>\n/settings clear", lang, name, prefs, modeLabel, voice)
 }
 
 // registerSkillCommands registers commands for all loaded skills.
@@ -561,10 +548,15 @@ func skillHandler(skillsMgr *skills.Manager, ag *agent.Agent, prefsMgr *userpref
 			installModeHint = "涉及技能安装时，优先尝试 `npx skills add <owner/repo>`，失败再回退当前方式。"
 		}
 
-		confirmedRepo := parseConfirmedInstallRepo(userTask)
+		confirmedRepo, confirmedVersion := parseConfirmedInstallRepo(userTask)
 		if fromMeta := strings.TrimSpace(req.Metadata["skill_install_confirmed_repo"]); fromMeta != "" {
 			confirmedRepo = fromMeta
 		}
+		if confirmedRepo != "" && skillName == "find-skills" {
+			if reply, handled := installConfirmedSkill(ctx, skillsMgr, confirmedRepo, confirmedVersion); handled {
+				return CommandResponse{Content: reply, ReplyInline: true}, nil
+			}
+		}
 		if skillName == "find-skills" && installMode == "npx_preferred" {
 			prompt := fmt.Sprintf(
 				"你正在处理 %s 渠道的 slash command /%s，对应技能 %q。\n"+
@@ -612,6 +604,9 @@ func skillHandler(skillsMgr *skills.Manager, ag *agent.Agent, prefsMgr *userpref
 			}
 
 			if proposal, ok := ParseSkillInstallProposal(reply); ok {
+				if refusal := resolveSkillProposal(ctx, skillsMgr, &proposal); refusal != "" {
+					return CommandResponse{Content: refusal, ReplyInline: true}, nil
+				}
 				msg := strings.TrimSpace(proposal.Message)
 				if msg == "" {
 					msg = fmt.Sprintf("已找到候选技能：%s\n请确认是否安装。", proposal.Repo)
@@ -620,11 +615,14 @@ func skillHandler(skillsMgr *skills.Manager, ag *agent.Agent, prefsMgr *userpref
 					Content:     msg,
 					ReplyInline: true,
 					Interaction: &CommandInteraction{
-						Type:    InteractionTypeSkillInstallConfirm,
-						Repo:    proposal.Repo,
-						Reason:  proposal.Reason,
-						Message: proposal.Message,
-						Command: req.Command,
+						Type:        InteractionTypeSkillInstallConfirm,
+						Repo:        proposal.Repo,
+						Version:     proposal.Version,
+						Changelog:   proposal.Changelog,
+						Permissions: proposal.Permissions,
+						Reason:      proposal.Reason,
+						Message:     proposal.Message,
+						Command:     req.Command,
 					},
 				}, nil
 			}
@@ -663,17 +661,19 @@ func skillHandler(skillsMgr *skills.Manager, ag *agent.Agent, prefsMgr *userpref
 	}
 }
 
-func parseConfirmedInstallRepo(task string) string {
-	const prefix = "__confirm_install__"
+// parseConfirmedInstallRepo reads the repo and optional version from
+// arguments built by ConfirmInstallArgs.
+func parseConfirmedInstallRepo(task string) (string, string) {
 	task = strings.TrimSpace(task)
-	if !strings.HasPrefix(task, prefix) {
-		return ""
+	if !strings.HasPrefix(task, confirmInstallPrefix) {
+		return "", ""
 	}
-	repo := strings.TrimSpace(strings.TrimPrefix(task, prefix))
+	target := strings.TrimSpace(strings.TrimPrefix(task, confirmInstallPrefix))
+	repo, version, _ := strings.Cut(target, "@")
 	if repo == "" || !strings.Contains(repo, "/") {
-		return ""
+		return "", ""
 	}
-	return repo
+	return repo, strings.TrimSpace(version)
 }
 
 type commandSession struct {
@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"nekobot/pkg/agent"
+	"nekobot/pkg/config"
+	"nekobot/pkg/session"
+)
+
+const agentUsage = "Usage: /agent [info|list|use <name>]"
+
+// agentHandler handles the /agent command.
+func agentHandler(cfg *config.Config, sessionMgr *session.Manager) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		parts := strings.Fields(req.Args)
+		action := ""
+		if len(parts) > 0 {
+			action = strings.ToLower(parts[0])
+		}
+
+		switch {
+		case action == "" || action == "info":
+			if len(parts) > 1 {
+				break
+			}
+			sess, _ := chatSession(sessionMgr, req)
+			return CommandResponse{Content: formatAgentInfo(cfg, req.Channel, sess), ReplyInline: true}, nil
+		case action == "list":
+			if len(parts) > 1 {
+				break
+			}
+			sess, _ := chatSession(sessionMgr, req)
+			return CommandResponse{Content: formatAgentList(cfg, req.Channel, sess), ReplyInline: true}, nil
+		case action == "use" && len(parts) == 2:
+			return useAgentProfile(cfg, sessionMgr, req, parts[1]), nil
+		case action == "reset" && len(parts) == 1:
+			return useAgentProfile(cfg, sessionMgr, req, ""), nil
+		case len(parts) == 1:
+			return useAgentProfile(cfg, sessionMgr, req, parts[0]), nil
+		}
+		return CommandResponse{Content: "❌ " + agentUsage, ReplyInline: true}, nil
+	}
+}
+
+// useAgentProfile stores the chat's profile selection. An empty name clears
+// it so the channel's profile applies again.
+func useAgentProfile(cfg *config.Config, sessionMgr *session.Manager, req CommandRequest, name string) CommandResponse {
+	sess, errMsg := chatSession(sessionMgr, req)
+	if errMsg != "" {
+		return CommandResponse{Content: errMsg, ReplyInline: true}
+	}
+	name = strings.TrimSpace(name)
+	if name != "" && name != config.DefaultAgentProfile {
+		if _, ok := cfg.Agents.Profile(name); !ok {
+			return CommandResponse{
+				Content:     fmt.Sprintf("❌ Unknown agent profile: %s\n\nUse `/agent list` to see the configured profiles.", name),
+				ReplyInline: true,
+			}
+		}
+	}
+	sess.SetAgentProfile(name)
+
+	switch name {
+	case "":
+		if profile, ok := agent.ResolveAgentProfile(cfg, req.Channel, ""); ok {
+			return CommandResponse{Content: fmt.Sprintf("✅ Selection cleared. This chat uses the channel profile **%s** again.", profile.Name), ReplyInline: true}
+		}
+		return CommandResponse{Content: "✅ Selection cleared. This chat uses the default agent again.", ReplyInline: true}
+	case config.DefaultAgentProfile:
+		return CommandResponse{Content: "✅ This chat now uses the default agent.", ReplyInline: true}
+	}
+	return CommandResponse{Content: fmt.Sprintf("🤖 This chat now uses agent profile **%s**.", name), ReplyInline: true}
+}
+
+func selectedAgentProfile(sess *session.Session) string {
+	if sess == nil {
+		return ""
+	}
+	return sess.GetAgentProfile()
+}
+
+func formatAgentInfo(cfg *config.Config, channel string, sess *session.Session) string {
+	defaults := cfg.Agents.Defaults
+	provider, model := defaults.Provider, defaults.Model
+	var sb strings.Builder
+	sb.WriteString("🤖 **Agent Information**\n\n")
+
+	profile, ok := agent.ResolveAgentProfile(cfg, channel, selectedAgentProfile(sess))
+	if ok {
+		_, _ = fmt.Fprintf(&sb, "Profile: **%s**\n", profile.Name)
+		if profile.Description != "" {
+			_, _ = fmt.Fprintf(&sb, "Description: %s\n", profile.Description)
+		}
+		if profile.Provider != "" {
+			provider = profile.Provider
+		}
+		if profile.Model != "" {
+			model = profile.Model
+		}
+	} else {
+		sb.WriteString("Profile: **default**\n")
+	}
+	if provider == "" {
+		provider = "Not configured"
+	}
+
+	_, _ = fmt.Fprintf(&sb, "Provider: **%s**\n", provider)
+	_, _ = fmt.Fprintf(&sb, "Model: %s\n", model)
+	if ok && len(profile.Tools) > 0 {
+		_, _ = fmt.Fprintf(&sb, "Tools: %s\n", strings.Join(profile.Tools, ", "))
+	}
+	if ok && profile.Workspace != "" {
+		_, _ = fmt.Fprintf(&sb, "Workspace: %s\n", profile.Workspace)
+	}
+	_, _ = fmt.Fprintf(&sb, "Max Tokens: %d\n", defaults.MaxTokens)
+	_, _ = fmt.Fprintf(&sb, "Temperature: %.2f\n", defaults.Temperature)
+
+	sb.WriteString("\nUse `/agent list` to see all agent profiles.")
+	return sb.String()
+}
+
+func formatAgentList(cfg *config.Config, channel string, sess *session.Session) string {
+	active := config.DefaultAgentProfile
+	if profile, ok := agent.ResolveAgentProfile(cfg, channel, selectedAgentProfile(sess)); ok {
+		active = profile.Name
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🤖 **Agent Profiles**\n\n")
+	writeAgentListEntry(&sb, active == config.DefaultAgentProfile, config.DefaultAgentProfile, "Agent defaults")
+	for _, profile := range cfg.Agents.Profiles {
+		description := profile.Description
+		if len(profile.Channels) > 0 {
+			if description != "" {
+				description += " "
+			}
+			description += "(channels: " + strings.Join(profile.Channels, ", ") + ")"
+		}
+		writeAgentListEntry(&sb, profile.Name == active, profile.Name, description)
+	}
+	sb.WriteString("\nUse `/agent use <name>` to switch this chat, `/agent reset` to follow the channel again.")
+	return sb.String()
+}
+
+func writeAgentListEntry(sb *strings.Builder, active bool, name, description string) {
+	prefix := "  "
+	if active {
+		prefix = "→ "
+	}
+	_, _ = fmt.Fprintf(sb, "%s**%s**", prefix, name)
+	if description != "" {
+		_, _ = fmt.Fprintf(sb, " — %s", description)
+	}
+	sb.WriteString("\n")
+}
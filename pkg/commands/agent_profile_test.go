@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/session"
+)
+
+func TestAgentCommandSwitchesChatProfile(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Profiles = []config.AgentProfileConfig{
+		{Name: "coder", Model: "coder-model", Channels: []string{"telegram"}},
+		{Name: "support", Description: "Customer support"},
+	}
+	sessionMgr := session.NewManager(t.TempDir(), cfg.Sessions)
+	handler := agentHandler(cfg, sessionMgr)
+	req := CommandRequest{Channel: "telegram", ChatID: "42"}
+	ctx := context.Background()
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		t.Fatalf("agent info failed: %v", err)
+	}
+	if !strings.Contains(resp.Content, "Profile: **coder**") || !strings.Contains(resp.Content, "coder-model") {
+		t.Fatalf("expected the channel profile, got %q", resp.Content)
+	}
+
+	req.Args = "use support"
+	if _, err := handler(ctx, req); err != nil {
+		t.Fatalf("agent use failed: %v", err)
+	}
+	sess, err := sessionMgr.GetExisting("telegram:42")
+	if err != nil {
+		t.Fatalf("expected chat session: %v", err)
+	}
+	if got := sess.GetAgentProfile(); got != "support" {
+		t.Fatalf("unexpected selected profile %q", got)
+	}
+
+	req.Args = "list"
+	resp, _ = handler(ctx, req)
+	if !strings.Contains(resp.Content, "→ **support**") {
+		t.Fatalf("expected support to be marked active, got %q", resp.Content)
+	}
+
+	req.Args = "use missing"
+	resp, _ = handler(ctx, req)
+	if !strings.Contains(resp.Content, "Unknown agent profile") || sess.GetAgentProfile() != "support" {
+		t.Fatalf("expected unknown profile to be rejected, got %q", resp.Content)
+	}
+
+	req.Args = "reset"
+	resp, _ = handler(ctx, req)
+	if sess.GetAgentProfile() != "" || !strings.Contains(resp.Content, "channel profile **coder**") {
+		t.Fatalf("expected selection to be cleared, got %q", resp.Content)
+	}
+}
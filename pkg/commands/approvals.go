@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"nekobot/pkg/approval"
+)
+
+// ApprovalDecider decides queued tool approval requests.
+type ApprovalDecider interface {
+	ApproveAs(id, actor string) error
+	DenyAs(id, actor, reason string) error
+	PromptedIn(id, channel, chatSession string) bool
+}
+
+// approvalDecisionHandler handles /approve and /deny, the text fallback for
+// channels that cannot render approval buttons. It only decides prompts
+// asked in the chat session the command comes from; anything else is
+// decided in the WebUI.
+func approvalDecisionHandler(decider ApprovalDecider, decision approval.Decision) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		if decider == nil {
+			return CommandResponse{Content: "❌ Approvals are unavailable", ReplyInline: true}, nil
+		}
+		id, reason := req.Params.String("id"), req.Params.String("reason")
+		if !decider.PromptedIn(id, req.Channel, commandSessionID(req)) {
+			return CommandResponse{Content: "❌ No approval prompt " + id + " is waiting in this chat", ReplyInline: true}, nil
+		}
+
+		actor := req.Channel + ":" + req.UserID
+		if name := strings.TrimSpace(req.Username); name != "" {
+			actor = req.Channel + ":" + name
+		}
+		var err error
+		if decision == approval.Approved {
+			err = decider.ApproveAs(id, actor)
+		} else {
+			reason = strings.TrimSpace(reason)
+			if reason == "" {
+				reason = "denied from chat"
+			}
+			err = decider.DenyAs(id, actor, reason)
+		}
+		if err != nil {
+			return CommandResponse{Content: "❌ " + err.Error(), ReplyInline: true}, nil
+		}
+		return CommandResponse{Content: approval.DecisionText(decision, "") + " " + id, ReplyInline: true}, nil
+	}
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/approval"
+)
+
+func TestApprovalCommandsDecideQueuedRequests(t *testing.T) {
+	mgr := approval.NewManager(approval.Config{Mode: approval.ModeManual})
+	first, err := mgr.EnqueuePrompt("exec", map[string]interface{}{"command": "ls"}, "s1", "telegram", "telegram:42")
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	second, err := mgr.EnqueuePrompt("exec", map[string]interface{}{"command": "rm -rf /"}, "s1", "telegram", "telegram:42")
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	deps := Dependencies{Approvals: mgr}
+	approve := registeredHandler(t, deps, "approve")
+	deny := registeredHandler(t, deps, "deny")
+	ctx := context.Background()
+	req := CommandRequest{Channel: "telegram", ChatID: "42", UserID: "1", Username: "alice"}
+
+	resp, _ := approve(ctx, req)
+	if !strings.Contains(resp.Content, "Usage") {
+		t.Fatalf("expected usage without an id, got %q", resp.Content)
+	}
+
+	req.Args = first
+	if resp, _ = approve(ctx, req); !strings.Contains(resp.Content, "Approved") {
+		t.Fatalf("unexpected approve response: %q", resp.Content)
+	}
+	if got, _ := mgr.GetRequest(first); got.Decision != approval.Approved || got.DecidedBy != "telegram:alice" {
+		t.Fatalf("expected approval by telegram:alice, got %+v", got)
+	}
+
+	req.Args = second + " too dangerous"
+	if resp, _ = deny(ctx, req); !strings.Contains(resp.Content, "Denied") {
+		t.Fatalf("unexpected deny response: %q", resp.Content)
+	}
+	if got, _ := mgr.GetRequest(second); got.Decision != approval.Denied || got.Reason != "too dangerous" {
+		t.Fatalf("expected denial with reason, got %+v", got)
+	}
+
+	req.Args = first
+	if resp, _ = deny(ctx, req); !strings.Contains(resp.Content, "No approval prompt") {
+		t.Fatalf("expected a decided request to stay final, got %q", resp.Content)
+	}
+}
+
+func TestApprovalCommandsOnlyDecidePromptsOfTheSameChat(t *testing.T) {
+	mgr := approval.NewManager(approval.Config{Mode: approval.ModeManual})
+	queued, err := mgr.EnqueueRequest("exec", map[string]interface{}{"command": "ls"}, "s1")
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	prompted, err := mgr.EnqueuePrompt("exec", map[string]interface{}{"command": "ls"}, "s2", "telegram", "telegram:42:topic-7")
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	approve := registeredHandler(t, Dependencies{Approvals: mgr}, "approve")
+	ctx := context.Background()
+
+	for name, req := range map[string]CommandRequest{
+		"webui request": {Channel: "telegram", ChatID: "42", UserID: "1", Args: queued},
+		"other chat":    {Channel: "telegram", ChatID: "7", UserID: "1", Args: prompted},
+		"other session": {Channel: "telegram", ChatID: "42", UserID: "1", Args: prompted},
+		"other channel": {Channel: "slack", ChatID: "42", UserID: "1", Args: prompted, Metadata: map[string]string{"session_id": "telegram:42:topic-7"}},
+	} {
+		if resp, _ := approve(ctx, req); !strings.Contains(resp.Content, "No approval prompt") {
+			t.Fatalf("%s: expected the command to be refused, got %q", name, resp.Content)
+		}
+	}
+	for _, id := range []string{queued, prompted} {
+		if got, _ := mgr.GetRequest(id); got.Decision != approval.Pending {
+			t.Fatalf("expected %s to stay pending, got %+v", id, got)
+		}
+	}
+
+	req := CommandRequest{Channel: "telegram", ChatID: "42", UserID: "1", Args: prompted, Metadata: map[string]string{"session_id": "telegram:42:topic-7"}}
+	if resp, _ := approve(ctx, req); !strings.Contains(resp.Content, "Approved") {
+		t.Fatalf("expected the prompt to be approved from its topic, got %q", resp.Content)
+	}
+}
@@ -19,8 +19,11 @@ func RegisterBuiltinCommands(registry *Registry) error {
 		{
 			Name:        "help",
 			Description: "Show available commands",
-			Usage:       "/help [command]",
-			Handler:     helpHandler(registry),
+			Params: []Param{{
+				Name:        "command",
+				Description: "Command to explain",
+			}},
+			Handler: helpHandler(registry),
 		},
 		{
 			Name:        "start",
@@ -49,23 +52,17 @@ func RegisterBuiltinCommands(registry *Registry) error {
 func helpHandler(registry *Registry) CommandHandler {
 	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
 		// If a specific command is requested, show detailed help
-		if req.Args != "" {
-			parts := strings.Fields(req.Args)
-			if len(parts) > 0 {
-				cmdName := strings.TrimPrefix(parts[0], "/")
-				if at := strings.Index(cmdName, "@"); at > 0 {
-					cmdName = cmdName[:at]
-				}
-				cmd, exists := registry.Get(cmdName)
-				if exists {
-					content := fmt.Sprintf("**/%s**\n\n%s\n\n**Usage:** %s",
-						cmd.Name, cmd.Description, cmd.Usage)
-
-					return CommandResponse{
-						Content:     content,
-						ReplyInline: true,
-					}, nil
-				}
+		if parts := strings.Fields(req.Params.String("command")); len(parts) > 0 {
+			cmdName := strings.TrimPrefix(parts[0], "/")
+			if at := strings.Index(cmdName, "@"); at > 0 {
+				cmdName = cmdName[:at]
+			}
+			cmd, exists := registry.Get(cmdName)
+			if exists {
+				return CommandResponse{
+					Content:     formatCommandHelp(cmd),
+					ReplyInline: true,
+				}, nil
 			}
 		}
 
@@ -113,6 +110,35 @@ func helpHandler(registry *Registry) CommandHandler {
 	}
 }
 
+// formatCommandHelp renders the detailed help of one command, including
+// its declared parameters.
+func formatCommandHelp(cmd *Command) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "**/%s**\n\n%s\n\n**Usage:** %s", cmd.Name, cmd.Description, cmd.UsageText())
+	if len(cmd.Params) == 0 {
+		return sb.String()
+	}
+	sb.WriteString("\n\n**Parameters:**")
+	for _, p := range cmd.Params {
+		_, _ = fmt.Fprintf(&sb, "\n• `%s` (%s", p.Name, p.valueType())
+		if !p.Required {
+			sb.WriteString(", optional")
+		}
+		sb.WriteString(")")
+		if desc := strings.TrimSpace(p.Description); desc != "" {
+			sb.WriteString(" - " + desc)
+		}
+		if len(p.Choices) > 0 {
+			label := "values"
+			if p.valueType() != ParamEnum {
+				label = "e.g."
+			}
+			_, _ = fmt.Fprintf(&sb, " [%s: %s]", label, strings.Join(p.Choices, ", "))
+		}
+	}
+	return sb.String()
+}
+
 func compactDescription(desc string, limit int) string {
 	desc = strings.Join(strings.Fields(strings.TrimSpace(desc)), " ")
 	if limit <= 0 {
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"nekobot/pkg/feedback"
+	"nekobot/pkg/session"
+)
+
+// FeedbackRecorder stores reply ratings.
+type FeedbackRecorder interface {
+	Record(ctx context.Context, entry feedback.Entry) (feedback.Entry, error)
+}
+
+const feedbackUsage = "Usage: /feedback <up|down> [comment]"
+
+// feedbackHandler handles the /feedback command, which rates the latest
+// assistant reply in the current chat.
+func feedbackHandler(recorder FeedbackRecorder, sessionMgr *session.Manager) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		if recorder == nil {
+			return CommandResponse{
+				Content:     "❌ Feedback is unavailable (runtime database not initialized)",
+				ReplyInline: true,
+			}, nil
+		}
+		comment := req.Params.String("comment")
+		rating, err := feedback.ParseRating(req.Params.String("rating"))
+		if err != nil {
+			return CommandResponse{Content: "❌ " + feedbackUsage, ReplyInline: true}, nil
+		}
+
+		sess, errMsg := chatSession(sessionMgr, req)
+		if errMsg != "" {
+			return CommandResponse{Content: errMsg, ReplyInline: true}, nil
+		}
+		messages := sess.GetMessages()
+		index := -1
+		for i := len(messages) - 1; i >= 0; i-- {
+			if messages[i].Role == "assistant" && strings.TrimSpace(messages[i].Content) != "" {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return CommandResponse{Content: "ℹ️ There is no reply to rate in this chat yet", ReplyInline: true}, nil
+		}
+
+		if _, err := recorder.Record(ctx, feedback.Entry{
+			Channel:   req.Channel,
+			SessionID: sess.GetID(),
+			MessageID: feedback.SessionMessageID(index),
+			UserID:    req.UserID,
+			Rating:    rating,
+			Comment:   comment,
+			Response:  messages[index].Content,
+		}); err != nil {
+			return CommandResponse{Content: "❌ Failed to save feedback: " + err.Error(), ReplyInline: true}, nil
+		}
+		if rating == feedback.RatingUp {
+			return CommandResponse{Content: "👍 Thanks for the feedback!", ReplyInline: true}, nil
+		}
+		return CommandResponse{Content: "👎 Thanks, noted. Tell us more with /feedback down <comment>.", ReplyInline: true}, nil
+	}
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/feedback"
+	"nekobot/pkg/session"
+)
+
+type recordingFeedback struct {
+	entries []feedback.Entry
+}
+
+func (r *recordingFeedback) Record(ctx context.Context, entry feedback.Entry) (feedback.Entry, error) {
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+func TestFeedbackCommandRatesLatestReply(t *testing.T) {
+	sessionMgr := session.NewManager(t.TempDir(), config.DefaultConfig().Sessions)
+	recorder := &recordingFeedback{}
+	handler := registeredHandler(t, Dependencies{Feedback: recorder, SessionManager: sessionMgr}, "feedback")
+	ctx := context.Background()
+	req := CommandRequest{Channel: "telegram", ChatID: "42", UserID: "7", Args: "down"}
+
+	resp, _ := handler(ctx, req)
+	if !strings.Contains(resp.Content, "no reply to rate") || len(recorder.entries) != 0 {
+		t.Fatalf("expected nothing to rate in an empty chat, got %q", resp.Content)
+	}
+
+	sess, err := sessionMgr.GetWithSource("telegram:42", session.SourceChannels)
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	sess.AddMessage(session.Message{Role: "user", Content: "hi"})
+	sess.AddMessage(session.Message{Role: "assistant", Content: "hello"})
+	sess.AddMessage(session.Message{Role: "user", Content: "explain"})
+	sess.AddMessage(session.Message{Role: "assistant", Content: "an explanation"})
+
+	req.Args = "down far too short"
+	resp, err = handler(ctx, req)
+	if err != nil {
+		t.Fatalf("feedback failed: %v", err)
+	}
+	if !strings.Contains(resp.Content, "👎") || len(recorder.entries) != 1 {
+		t.Fatalf("unexpected feedback response %q (%d entries)", resp.Content, len(recorder.entries))
+	}
+	got := recorder.entries[0]
+	if got.MessageID != feedback.SessionMessageID(3) || got.SessionID != "telegram:42" || got.UserID != "7" ||
+		got.Rating != feedback.RatingDown || got.Comment != "far too short" || got.Response != "an explanation" {
+		t.Fatalf("unexpected feedback entry: %+v", got)
+	}
+
+	req.Args = "sideways"
+	resp, _ = handler(ctx, req)
+	if !strings.Contains(resp.Content, feedbackUsage) {
+		t.Fatalf("expected usage for an unknown rating, got %q", resp.Content)
+	}
+}
@@ -6,8 +6,13 @@ import (
 
 	"nekobot/pkg/agent"
 	"nekobot/pkg/config"
+	"nekobot/pkg/cron"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/plugins"
+	"nekobot/pkg/providerstore"
+	"nekobot/pkg/session"
 	"nekobot/pkg/skills"
+	"nekobot/pkg/storage/ent"
 	"nekobot/pkg/userprefs"
 )
 
@@ -42,6 +47,11 @@ func registerAdvanced(
 		ChannelMgr    ChannelManager     `optional:"true"`
 		UserPrefs     *userprefs.Manager `optional:"true"`
 		GatewayCtrl   GatewayController  `optional:"true"`
+		SessionMgr    *session.Manager   `optional:"true"`
+		CronMgr       *cron.Manager      `optional:"true"`
+		Providers     *providerstore.Manager `optional:"true"`
+		EntClient     *ent.Client            `optional:"true"`
+		PluginMgr     *plugins.Manager       `optional:"true"`
 	},
 ) error {
 	deps := Dependencies{
@@ -51,6 +61,25 @@ func registerAdvanced(
 		ChannelManager:    p.ChannelMgr,
 		UserPrefs:         p.UserPrefs,
 		GatewayController: p.GatewayCtrl,
+		SessionManager:    p.SessionMgr,
+	}
+	if p.CronMgr != nil {
+		deps.TaskScheduler = p.CronMgr
+	}
+	if p.PluginMgr != nil {
+		deps.Plugins = p.PluginMgr
+	}
+	if p.Providers != nil {
+		deps.Models = NewModelCatalog(p.Config, p.Log, p.Providers, p.EntClient)
+	}
+	if jobs := p.Agent.BackgroundJobs(); jobs != nil {
+		deps.BackgroundJobs = jobs
+	}
+	if feedbackMgr := p.Agent.Feedback(); feedbackMgr != nil {
+		deps.Feedback = feedbackMgr
+	}
+	if approvalMgr := p.Agent.ApprovalManager(); approvalMgr != nil {
+		deps.Approvals = approvalMgr
 	}
 
 	if err := RegisterAdvancedCommands(p.Registry, deps); err != nil {
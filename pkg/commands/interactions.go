@@ -17,6 +17,11 @@ type SkillInstallProposal struct {
 	Repo    string
 	Reason  string
 	Message string
+	// Version, Changelog and Permissions describe the release to install
+	// when the repo is listed in the skills index.
+	Version     string
+	Changelog   string
+	Permissions []string
 }
 
 // ParseSkillInstallProposal parses standardized proposal lines from model output.
@@ -40,3 +45,15 @@ func ParseSkillInstallProposal(content string) (SkillInstallProposal, bool) {
 	}
 	return p, true
 }
+
+const confirmInstallPrefix = "__confirm_install__"
+
+// ConfirmInstallArgs builds the command arguments that re-run a skill
+// command after the user confirmed installing repo at version.
+func ConfirmInstallArgs(repo, version string) string {
+	target := strings.TrimSpace(repo)
+	if version = strings.TrimSpace(version); version != "" {
+		target += "@" + version
+	}
+	return confirmInstallPrefix + " " + target
+}
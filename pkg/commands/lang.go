@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"nekobot/pkg/userprefs"
+)
+
+// langConfirmations confirms a switch in the newly selected language.
+// Languages without an entry fall back to English.
+var langConfirmations = map[string]string{
+	"zh": "✅ 语言已切换为中文 (zh)",
+	"en": "✅ Language set to English (en)",
+	"ja": "✅ 言語を日本語 (ja) に設定しました",
+}
+
+// langHandler handles the /lang command, a shortcut for /settings lang.
+func langHandler(prefsMgr *userprefs.Manager) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		if prefsMgr == nil {
+			return CommandResponse{Content: "❌ settings 暂不可用（state 未初始化）", ReplyInline: true}, nil
+		}
+
+		channel := strings.TrimSpace(req.Channel)
+		userID := strings.TrimSpace(req.UserID)
+		profile, _, err := prefsMgr.Get(ctx, channel, userID)
+		if err != nil {
+			return CommandResponse{Content: "❌ 读取设置失败: " + err.Error(), ReplyInline: true}, nil
+		}
+
+		lang := req.Params.String("language")
+		if lang == "" {
+			return CommandResponse{
+				Content:     "🌐 Language: " + userprefs.NormalizeLanguage(profile.Language) + "\nSupported: " + strings.Join(userprefs.SupportedLanguages, ", ") + "\nUsage: /lang <code>",
+				ReplyInline: true,
+			}, nil
+		}
+
+		profile.Language = userprefs.NormalizeLanguage(lang)
+		if err := prefsMgr.Save(ctx, channel, userID, profile); err != nil {
+			return CommandResponse{Content: "❌ 保存失败: " + err.Error(), ReplyInline: true}, nil
+		}
+
+		confirmation, ok := langConfirmations[profile.Language]
+		if !ok {
+			confirmation = "✅ Language set to " + profile.Language
+		}
+		return CommandResponse{Content: confirmation, ReplyInline: true}, nil
+	}
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/logger"
+	"nekobot/pkg/state"
+	"nekobot/pkg/userprefs"
+)
+
+func newLangTestPrefs(t *testing.T) *userprefs.Manager {
+	t.Helper()
+
+	log, err := logger.New(&logger.Config{Level: "error"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	store, err := state.NewFileStore(log, &state.FileStoreConfig{FilePath: filepath.Join(t.TempDir(), "state.json")})
+	if err != nil {
+		t.Fatalf("create state store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return userprefs.New(store)
+}
+
+func TestLangCommandUpdatesProfileLanguage(t *testing.T) {
+	prefs := newLangTestPrefs(t)
+	handler := registeredHandler(t, Dependencies{UserPrefs: prefs}, "lang")
+	ctx := context.Background()
+	req := CommandRequest{Channel: "telegram", UserID: "7", Args: " EN "}
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		t.Fatalf("lang failed: %v", err)
+	}
+	if resp.Content != langConfirmations["en"] {
+		t.Fatalf("expected English confirmation, got %q", resp.Content)
+	}
+
+	profile, ok, err := prefs.Get(ctx, "telegram", "7")
+	if err != nil || !ok {
+		t.Fatalf("expected saved profile, got ok=%v err=%v", ok, err)
+	}
+	if profile.Language != "en" {
+		t.Fatalf("expected language en, got %q", profile.Language)
+	}
+
+	req.Args = "ja"
+	resp, _ = handler(ctx, req)
+	if resp.Content != langConfirmations["ja"] {
+		t.Fatalf("expected Japanese confirmation, got %q", resp.Content)
+	}
+}
+
+func TestLangCommandRejectsUnsupportedLanguage(t *testing.T) {
+	prefs := newLangTestPrefs(t)
+	handler := registeredHandler(t, Dependencies{UserPrefs: prefs}, "lang")
+	ctx := context.Background()
+
+	resp, err := handler(ctx, CommandRequest{Channel: "telegram", UserID: "7", Args: "fr"})
+	if err != nil {
+		t.Fatalf("lang failed: %v", err)
+	}
+	if !strings.Contains(resp.Content, "Unsupported language: fr") || !strings.Contains(resp.Content, "zh, en, ja") {
+		t.Fatalf("expected supported language list, got %q", resp.Content)
+	}
+	if _, ok, _ := prefs.Get(ctx, "telegram", "7"); ok {
+		t.Fatalf("expected no profile to be saved for an unsupported language")
+	}
+}
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"nekobot/pkg/userprefs"
+)
+
+// linkHandler handles the /link command. Without arguments it issues a
+// one-time code; /link <code> in another channel confirms it so both
+// accounts share one conversation.
+func linkHandler(prefsMgr *userprefs.Manager) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		if prefsMgr == nil {
+			return CommandResponse{Content: "❌ link 暂不可用（state 未初始化）", ReplyInline: true}, nil
+		}
+		channel := strings.TrimSpace(req.Channel)
+		userID := strings.TrimSpace(req.UserID)
+		if userID == "" {
+			return CommandResponse{Content: "❌ Cannot link: unknown user", ReplyInline: true}, nil
+		}
+
+		if code := req.Params.String("code"); code != "" {
+			identity, err := prefsMgr.RedeemLinkCode(ctx, code, channel, userID)
+			switch {
+			case errors.Is(err, userprefs.ErrInvalidLinkCode):
+				return CommandResponse{Content: "❌ Link code is invalid or expired. Run /link again to get a new one.", ReplyInline: true}, nil
+			case errors.Is(err, userprefs.ErrLinkSameAccount):
+				return CommandResponse{Content: "❌ Confirm the code from the other account, not the one that issued it.", ReplyInline: true}, nil
+			case err != nil:
+				return CommandResponse{Content: "❌ Link failed: " + err.Error(), ReplyInline: true}, nil
+			}
+			return CommandResponse{
+				Content:     strings.TrimRight("🔗 Accounts linked. Direct chats now share one conversation.\n"+formatLinkedAccounts(ctx, prefsMgr, identity), "\n"),
+				ReplyInline: true,
+			}, nil
+		}
+
+		code, expiresAt, err := prefsMgr.IssueLinkCode(ctx, channel, userID)
+		if err != nil {
+			return CommandResponse{Content: "❌ Link failed: " + err.Error(), ReplyInline: true}, nil
+		}
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "🔗 Link code: %s\n", code)
+		fmt.Fprintf(&sb, "Send /link %s from your other account (or enter it in the WebUI) before %s.\n",
+			code, expiresAt.Format("15:04 MST"))
+		if identity, err := prefsMgr.LinkedIdentity(ctx, channel, userID); err == nil && identity != "" {
+			sb.WriteString(formatLinkedAccounts(ctx, prefsMgr, identity))
+		}
+		return CommandResponse{Content: strings.TrimRight(sb.String(), "\n"), ReplyInline: true}, nil
+	}
+}
+
+// unlinkHandler handles the /unlink command.
+func unlinkHandler(prefsMgr *userprefs.Manager) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		if prefsMgr == nil {
+			return CommandResponse{Content: "❌ link 暂不可用（state 未初始化）", ReplyInline: true}, nil
+		}
+		unlinked, err := prefsMgr.Unlink(ctx, strings.TrimSpace(req.Channel), strings.TrimSpace(req.UserID))
+		if err != nil {
+			return CommandResponse{Content: "❌ Unlink failed: " + err.Error(), ReplyInline: true}, nil
+		}
+		if !unlinked {
+			return CommandResponse{Content: "ℹ️ This account is not linked.", ReplyInline: true}, nil
+		}
+		return CommandResponse{Content: "✅ Account unlinked. This chat has its own conversation again.", ReplyInline: true}, nil
+	}
+}
+
+func formatLinkedAccounts(ctx context.Context, prefsMgr *userprefs.Manager, identity string) string {
+	accounts, err := prefsMgr.LinkedAccounts(ctx, identity)
+	if err != nil || len(accounts) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("Linked accounts:\n")
+	for _, account := range accounts {
+		fmt.Fprintf(&sb, "• %s: %s\n", account.Channel, account.UserID)
+	}
+	return sb.String()
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/userprefs"
+)
+
+func TestLinkCommandIssuesAndConfirmsCode(t *testing.T) {
+	prefs := newLangTestPrefs(t)
+	link := registeredHandler(t, Dependencies{UserPrefs: prefs}, "link")
+	ctx := context.Background()
+
+	resp, err := link(ctx, CommandRequest{Channel: "telegram", UserID: "7"})
+	if err != nil {
+		t.Fatalf("link failed: %v", err)
+	}
+	fields := strings.Fields(strings.SplitN(resp.Content, "\n", 2)[0])
+	code := fields[len(fields)-1]
+	if !strings.Contains(resp.Content, "/link "+code) {
+		t.Fatalf("expected instructions with the code, got %q", resp.Content)
+	}
+
+	resp, _ = link(ctx, CommandRequest{Channel: "discord", UserID: "42", Args: "WRONG123"})
+	if !strings.Contains(resp.Content, "invalid or expired") {
+		t.Fatalf("expected invalid code error, got %q", resp.Content)
+	}
+
+	resp, _ = link(ctx, CommandRequest{Channel: "discord", UserID: "42", Args: strings.ToLower(code)})
+	if !strings.Contains(resp.Content, "Accounts linked") || !strings.Contains(resp.Content, "telegram: 7") {
+		t.Fatalf("expected linked confirmation, got %q", resp.Content)
+	}
+	identity, err := prefs.LinkedIdentity(ctx, "telegram", "7")
+	if err != nil || identity == "" {
+		t.Fatalf("expected telegram account linked, got %q, %v", identity, err)
+	}
+	if got := prefs.ResolveSessionID(ctx, "discord", "42", "discord:1"); got != userprefs.LinkedSessionID(identity) {
+		t.Fatalf("expected discord to share the linked session, got %q", got)
+	}
+
+	unlink := unlinkHandler(prefs)
+	resp, _ = unlink(ctx, CommandRequest{Channel: "discord", UserID: "42"})
+	if !strings.Contains(resp.Content, "unlinked") {
+		t.Fatalf("expected unlink confirmation, got %q", resp.Content)
+	}
+	resp, _ = unlink(ctx, CommandRequest{Channel: "discord", UserID: "42"})
+	if !strings.Contains(resp.Content, "not linked") {
+		t.Fatalf("expected not linked notice, got %q", resp.Content)
+	}
+}
@@ -0,0 +1,390 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/modelroute"
+	"nekobot/pkg/modelstore"
+	"nekobot/pkg/providerstore"
+	"nekobot/pkg/session"
+	"nekobot/pkg/storage/ent"
+)
+
+// maxListedModels bounds the models shown per provider in /model list.
+const maxListedModels = 20
+
+// ProviderModels is a provider a chat can switch to and the models it serves.
+type ProviderModels struct {
+	Name         string
+	Kind         string
+	DefaultModel string
+	Models       []string
+}
+
+// ModelCatalog lists the providers and models available to /model and
+// /provider.
+type ModelCatalog interface {
+	ListProviders(ctx context.Context) ([]ProviderModels, error)
+}
+
+// NewModelCatalog lists providers from the provider store and their models
+// from the model catalog and routes, when a runtime database is available.
+func NewModelCatalog(cfg *config.Config, log *logger.Logger, providers *providerstore.Manager, client *ent.Client) ModelCatalog {
+	return &storeModelCatalog{cfg: cfg, log: log, providers: providers, client: client}
+}
+
+type storeModelCatalog struct {
+	cfg       *config.Config
+	log       *logger.Logger
+	providers *providerstore.Manager
+	client    *ent.Client
+}
+
+func (c *storeModelCatalog) ListProviders(ctx context.Context) ([]ProviderModels, error) {
+	profiles, err := c.providers.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list providers: %w", err)
+	}
+	items := providerModelsFromProfiles(profiles)
+	if c.client == nil {
+		return items, nil
+	}
+
+	modelMgr, err := modelstore.NewManager(c.cfg, c.log, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("create model manager: %w", err)
+	}
+	routeMgr, err := modelroute.NewManager(c.cfg, c.log, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("create model route manager: %w", err)
+	}
+	models, err := modelMgr.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	modelIDs := make([]string, 0, len(models))
+	for _, model := range models {
+		if model.Enabled {
+			modelIDs = append(modelIDs, model.ModelID)
+		}
+	}
+	routes, err := routeMgr.ListByModels(ctx, modelIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*ProviderModels, len(items))
+	for i := range items {
+		byName[items[i].Name] = &items[i]
+	}
+	for _, modelID := range modelIDs {
+		for _, route := range routes[modelID] {
+			if item, ok := byName[route.ProviderName]; ok && route.Enabled {
+				item.Models = appendModel(item.Models, modelID)
+			}
+		}
+	}
+	return items, nil
+}
+
+func providerModelsFromProfiles(profiles []config.ProviderProfile) []ProviderModels {
+	items := make([]ProviderModels, 0, len(profiles))
+	for i := range profiles {
+		profile := &profiles[i]
+		item := ProviderModels{
+			Name:         profile.Name,
+			Kind:         profile.ProviderKind,
+			DefaultModel: profile.GetDefaultModel(),
+		}
+		for _, model := range profile.Models {
+			item.Models = appendModel(item.Models, model)
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items
+}
+
+func appendModel(models []string, model string) []string {
+	model = strings.TrimSpace(model)
+	if model == "" {
+		return models
+	}
+	for _, existing := range models {
+		if existing == model {
+			return models
+		}
+	}
+	return append(models, model)
+}
+
+// listProviders returns the catalog, or the configured providers when no
+// catalog is wired in.
+func listProviders(ctx context.Context, catalog ModelCatalog, cfg *config.Config) ([]ProviderModels, error) {
+	if catalog != nil {
+		return catalog.ListProviders(ctx)
+	}
+	if cfg == nil {
+		return nil, nil
+	}
+	return providerModelsFromProfiles(cfg.Providers), nil
+}
+
+func findProvider(items []ProviderModels, name string) (ProviderModels, bool) {
+	for _, item := range items {
+		if strings.EqualFold(item.Name, strings.TrimSpace(name)) {
+			return item, true
+		}
+	}
+	return ProviderModels{}, false
+}
+
+func (p ProviderModels) serves(model string) bool {
+	for _, candidate := range p.Models {
+		if candidate == model {
+			return true
+		}
+	}
+	return false
+}
+
+// chatRoute is the provider, model and fallback chain the next turn of a
+// chat uses, and whether each comes from a chat override.
+type chatRoute struct {
+	Provider         string
+	Model            string
+	Fallback         []string
+	ProviderOverride bool
+	ModelOverride    bool
+	FallbackOverride bool
+}
+
+func currentChatRoute(cfg *config.Config, sess *session.Session) chatRoute {
+	var route chatRoute
+	if cfg != nil {
+		route.Provider = cfg.Agents.Defaults.Provider
+		route.Model = cfg.Agents.Defaults.Model
+		route.Fallback = cfg.Agents.Defaults.Fallback
+	}
+	provider, model, fallback := sess.GetModelOverride()
+	if provider != "" {
+		route.Provider, route.ProviderOverride = provider, true
+	}
+	if model != "" {
+		route.Model, route.ModelOverride = model, true
+	}
+	if len(fallback) > 0 {
+		route.Fallback, route.FallbackOverride = fallback, true
+	}
+	return route
+}
+
+func formatChatRoute(route chatRoute) string {
+	var sb strings.Builder
+	sb.WriteString("🤖 **Model for this chat**\n\n")
+	_, _ = fmt.Fprintf(&sb, "Provider: %s%s\n", orNone(route.Provider), overrideLabel(route.ProviderOverride))
+	_, _ = fmt.Fprintf(&sb, "Model: %s%s\n", orNone(route.Model), overrideLabel(route.ModelOverride))
+	_, _ = fmt.Fprintf(&sb, "Fallback: %s%s\n", orNone(strings.Join(route.Fallback, ", ")), overrideLabel(route.FallbackOverride))
+	return sb.String()
+}
+
+func orNone(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "(none)"
+	}
+	return value
+}
+
+func overrideLabel(override bool) string {
+	if override {
+		return " (chat override)"
+	}
+	return " (configured default)"
+}
+
+func formatProviderList(items []ProviderModels, route chatRoute, withModels bool) string {
+	var sb strings.Builder
+	sb.WriteString("🤖 **Available Providers**\n\n")
+	if len(items) == 0 {
+		sb.WriteString("No providers configured.\n")
+		return sb.String()
+	}
+	for _, item := range items {
+		marker := ""
+		if strings.EqualFold(item.Name, route.Provider) {
+			marker = " ← current"
+		}
+		_, _ = fmt.Fprintf(&sb, "**%s** (%s)%s\n", item.Name, item.Kind, marker)
+		if item.DefaultModel != "" {
+			_, _ = fmt.Fprintf(&sb, "  Default: %s\n", item.DefaultModel)
+		}
+		if !withModels || len(item.Models) == 0 {
+			continue
+		}
+		shown := item.Models
+		if len(shown) > maxListedModels {
+			shown = shown[:maxListedModels]
+		}
+		_, _ = fmt.Fprintf(&sb, "  Models: %s", strings.Join(shown, ", "))
+		if more := len(item.Models) - len(shown); more > 0 {
+			_, _ = fmt.Fprintf(&sb, " … and %d more", more)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// modelHandler handles the /model command.
+func modelHandler(cfg *config.Config, catalog ModelCatalog, sessionMgr *session.Manager) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		arg := req.Params.String("model")
+		if strings.EqualFold(arg, "list") {
+			items, err := listProviders(ctx, catalog, cfg)
+			if err != nil {
+				return CommandResponse{Content: "❌ Failed to list models: " + err.Error(), ReplyInline: true}, nil
+			}
+			route := chatRoute{}
+			if cfg != nil {
+				route.Provider = cfg.Agents.Defaults.Provider
+			}
+			if sess, errMsg := chatSession(sessionMgr, req); errMsg == "" {
+				route = currentChatRoute(cfg, sess)
+			}
+			return CommandResponse{
+				Content:     formatProviderList(items, route, true) + "\nUse `/model <model>` to switch models for this chat.",
+				ReplyInline: true,
+			}, nil
+		}
+
+		sess, errMsg := chatSession(sessionMgr, req)
+		if errMsg != "" {
+			return CommandResponse{Content: errMsg, ReplyInline: true}, nil
+		}
+		provider, _, fallback := sess.GetModelOverride()
+		switch strings.ToLower(arg) {
+		case "":
+			return CommandResponse{
+				Content:     formatChatRoute(currentChatRoute(cfg, sess)) + "\nUse `/model list` to see available models.",
+				ReplyInline: true,
+			}, nil
+		case "default", "reset":
+			sess.SetModelOverride(provider, "", fallback)
+			return CommandResponse{Content: "✅ Model reset to the default for this chat", ReplyInline: true}, nil
+		}
+
+		items, err := listProviders(ctx, catalog, cfg)
+		if err != nil {
+			return CommandResponse{Content: "❌ Failed to list models: " + err.Error(), ReplyInline: true}, nil
+		}
+		// Keep the current provider when it serves the model or declares no
+		// models; otherwise switch to the first provider that serves it.
+		current, ok := findProvider(items, currentChatRoute(cfg, sess).Provider)
+		if !ok || (len(current.Models) > 0 && !current.serves(arg)) {
+			switched := false
+			for _, item := range items {
+				if item.serves(arg) {
+					provider, switched = item.Name, true
+					break
+				}
+			}
+			if !switched {
+				return CommandResponse{
+					Content:     fmt.Sprintf("❌ No provider serves model '%s'. Use `/model list` to see available models.", arg),
+					ReplyInline: true,
+				}, nil
+			}
+		}
+		sess.SetModelOverride(provider, arg, fallback)
+		content := "✅ This chat now uses model " + arg
+		if provider != "" {
+			content += " on " + provider
+		}
+		return CommandResponse{Content: content, ReplyInline: true}, nil
+	}
+}
+
+// providerHandler handles the /provider command.
+func providerHandler(cfg *config.Config, catalog ModelCatalog, sessionMgr *session.Manager) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		sess, errMsg := chatSession(sessionMgr, req)
+		if errMsg != "" {
+			return CommandResponse{Content: errMsg, ReplyInline: true}, nil
+		}
+		provider, model, fallback := sess.GetModelOverride()
+
+		arg := req.Params.String("provider")
+		switch strings.ToLower(arg) {
+		case "default", "reset":
+			sess.SetModelOverride("", "", nil)
+			return CommandResponse{
+				Content:     "✅ Provider, model and fallback reset to the configured defaults for this chat",
+				ReplyInline: true,
+			}, nil
+		}
+
+		items, err := listProviders(ctx, catalog, cfg)
+		if err != nil {
+			return CommandResponse{Content: "❌ Failed to list providers: " + err.Error(), ReplyInline: true}, nil
+		}
+		switch strings.ToLower(arg) {
+		case "", "list":
+			route := currentChatRoute(cfg, sess)
+			return CommandResponse{
+				Content: formatChatRoute(route) + "\n" + formatProviderList(items, route, false) +
+					"\nUse `/provider <name>` to switch providers or `/provider fallback <a,b>` to set the fallback chain for this chat.",
+				ReplyInline: true,
+			}, nil
+		case "fallback":
+			return providerFallback(sess, items, provider, model, req.Params.String("chain")), nil
+		}
+
+		item, ok := findProvider(items, arg)
+		if !ok {
+			return CommandResponse{
+				Content:     fmt.Sprintf("❌ Provider '%s' not found. Use `/provider list` to see available providers.", arg),
+				ReplyInline: true,
+			}, nil
+		}
+		sess.SetModelOverride(item.Name, item.DefaultModel, fallback)
+		content := "✅ This chat now uses provider " + item.Name
+		if item.DefaultModel != "" {
+			content += " with model " + item.DefaultModel
+		}
+		return CommandResponse{Content: content, ReplyInline: true}, nil
+	}
+}
+
+// providerFallback handles /provider fallback [<a,b,...>|reset].
+func providerFallback(sess *session.Session, items []ProviderModels, provider, model, chain string) CommandResponse {
+	switch strings.ToLower(chain) {
+	case "":
+		_, _, fallback := sess.GetModelOverride()
+		if len(fallback) == 0 {
+			return CommandResponse{Content: "🔁 This chat uses the configured fallback chain", ReplyInline: true}
+		}
+		return CommandResponse{Content: "🔁 Fallback for this chat: " + strings.Join(fallback, ", "), ReplyInline: true}
+	case "default", "reset":
+		sess.SetModelOverride(provider, model, nil)
+		return CommandResponse{Content: "✅ Fallback chain reset to the configured default for this chat", ReplyInline: true}
+	}
+
+	names := strings.FieldsFunc(chain, func(r rune) bool { return r == ',' || r == ' ' })
+	fallback := make([]string, 0, len(names))
+	for _, name := range names {
+		item, ok := findProvider(items, name)
+		if !ok {
+			return CommandResponse{
+				Content:     fmt.Sprintf("❌ Provider '%s' not found. Use `/provider list` to see available providers.", name),
+				ReplyInline: true,
+			}
+		}
+		fallback = append(fallback, item.Name)
+	}
+	sess.SetModelOverride(provider, model, fallback)
+	return CommandResponse{Content: "✅ Fallback for this chat: " + strings.Join(fallback, ", "), ReplyInline: true}
+}
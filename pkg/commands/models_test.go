@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/session"
+)
+
+type fakeModelCatalog []ProviderModels
+
+func (c fakeModelCatalog) ListProviders(context.Context) ([]ProviderModels, error) {
+	return c, nil
+}
+
+func TestModelAndProviderCommandsSetChatOverrides(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Provider = "openai"
+	cfg.Agents.Defaults.Model = "gpt-4o"
+	sessionMgr := session.NewManager(t.TempDir(), cfg.Sessions)
+	deps := Dependencies{
+		Config:         cfg,
+		SessionManager: sessionMgr,
+		Models: fakeModelCatalog{
+			{Name: "claude", Kind: "anthropic", DefaultModel: "claude-sonnet", Models: []string{"claude-sonnet", "claude-haiku"}},
+			{Name: "local", Kind: "ollama"},
+			{Name: "openai", Kind: "openai", DefaultModel: "gpt-4o", Models: []string{"gpt-4o", "gpt-4o-mini"}},
+		},
+	}
+	modelCmd := registeredHandler(t, deps, "model")
+	providerCmd := registeredHandler(t, deps, "provider")
+	req := CommandRequest{Channel: "telegram", ChatID: "42"}
+	ctx := context.Background()
+	run := func(handler CommandHandler, args string) string {
+		t.Helper()
+		req.Args = args
+		resp, err := handler(ctx, req)
+		if err != nil {
+			t.Fatalf("command %q failed: %v", args, err)
+		}
+		return resp.Content
+	}
+	sess, err := sessionMgr.GetWithSource("telegram:42", session.SourceChannels)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+	expect := func(provider, model string, fallback ...string) {
+		t.Helper()
+		gotProvider, gotModel, gotFallback := sess.GetModelOverride()
+		if gotProvider != provider || gotModel != model || strings.Join(gotFallback, ",") != strings.Join(fallback, ",") {
+			t.Fatalf("expected override %q %q %v, got %q %q %v", provider, model, fallback, gotProvider, gotModel, gotFallback)
+		}
+	}
+
+	if content := run(modelCmd, "list"); !strings.Contains(content, "**openai** (openai) ← current") ||
+		!strings.Contains(content, "Models: claude-sonnet, claude-haiku") {
+		t.Fatalf("unexpected model list: %q", content)
+	}
+
+	run(modelCmd, "gpt-4o-mini")
+	expect("", "gpt-4o-mini")
+
+	if content := run(modelCmd, "claude-haiku"); !strings.Contains(content, "claude-haiku on claude") {
+		t.Fatalf("expected a switch to the serving provider, got %q", content)
+	}
+	expect("claude", "claude-haiku")
+
+	if content := run(modelCmd, "unknown-model"); !strings.Contains(content, "No provider serves model 'unknown-model'") {
+		t.Fatalf("expected unknown model error, got %q", content)
+	}
+	expect("claude", "claude-haiku")
+
+	run(providerCmd, "fallback openai, LOCAL")
+	expect("claude", "claude-haiku", "openai", "local")
+
+	if content := run(providerCmd, "fallback missing"); !strings.Contains(content, "Provider 'missing' not found") {
+		t.Fatalf("expected unknown fallback provider error, got %q", content)
+	}
+
+	// A provider without a model list accepts any model.
+	run(providerCmd, "local")
+	expect("local", "", "openai", "local")
+	run(modelCmd, "llama3")
+	expect("local", "llama3", "openai", "local")
+
+	if content := run(modelCmd, ""); !strings.Contains(content, "Provider: local (chat override)") ||
+		!strings.Contains(content, "Model: llama3 (chat override)") {
+		t.Fatalf("unexpected current route: %q", content)
+	}
+
+	run(modelCmd, "reset")
+	expect("local", "", "openai", "local")
+	run(providerCmd, "reset")
+	expect("", "")
+
+	if content := run(providerCmd, ""); !strings.Contains(content, "Provider: openai (configured default)") {
+		t.Fatalf("unexpected provider status: %q", content)
+	}
+}
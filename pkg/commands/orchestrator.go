@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"nekobot/pkg/agent"
+	"nekobot/pkg/config"
+	"nekobot/pkg/session"
+)
+
+// orchestratorHandler handles the /orchestrator command.
+func orchestratorHandler(cfg *config.Config, sessionMgr *session.Manager) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		sess, errMsg := chatSession(sessionMgr, req)
+		if errMsg != "" {
+			return CommandResponse{Content: errMsg, ReplyInline: true}, nil
+		}
+
+		arg := req.Params.String("orchestrator")
+		switch strings.ToLower(arg) {
+		case "":
+			return CommandResponse{Content: formatOrchestrator(cfg, sess.GetOrchestrator()), ReplyInline: true}, nil
+		case "default", "reset":
+			sess.SetOrchestrator("")
+			return CommandResponse{
+				Content:     "✅ Orchestrator reset to the configured default (" + defaultOrchestrator(cfg) + ")",
+				ReplyInline: true,
+			}, nil
+		}
+
+		name, err := agent.NormalizeOrchestrator(arg)
+		if err != nil {
+			return CommandResponse{
+				Content:     "❌ " + err.Error() + "\nUsage: /orchestrator [blades|legacy|default]",
+				ReplyInline: true,
+			}, nil
+		}
+		sess.SetOrchestrator(name)
+		return CommandResponse{Content: "✅ This chat now uses the " + name + " orchestrator", ReplyInline: true}, nil
+	}
+}
+
+// defaultOrchestrator reports agents.defaults.orchestrator, which defaults to blades.
+func defaultOrchestrator(cfg *config.Config) string {
+	if cfg == nil || strings.TrimSpace(cfg.Agents.Defaults.Orchestrator) == "" {
+		return "blades"
+	}
+	if name, err := agent.NormalizeOrchestrator(cfg.Agents.Defaults.Orchestrator); err == nil {
+		return name
+	}
+	return cfg.Agents.Defaults.Orchestrator
+}
+
+func formatOrchestrator(cfg *config.Config, override string) string {
+	if override == "" {
+		return "🧭 Orchestrator: " + defaultOrchestrator(cfg) + " (configured default)\n\nUse `/orchestrator <blades|legacy>` to switch for this chat."
+	}
+	return "🧭 Orchestrator: " + override + " (chat override)\n\nUse `/orchestrator default` to restore " + defaultOrchestrator(cfg) + "."
+}
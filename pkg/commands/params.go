@@ -0,0 +1,241 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParamType is the value type of a command parameter.
+type ParamType string
+
+const (
+	// ParamString accepts any text. The last string parameter takes the
+	// rest of the arguments, spaces included.
+	ParamString ParamType = "string"
+	// ParamInt accepts a whole number.
+	ParamInt ParamType = "int"
+	// ParamEnum accepts one of Choices, matched case-insensitively.
+	ParamEnum ParamType = "enum"
+)
+
+// Param declares a positional command parameter.
+type Param struct {
+	// Name identifies the parameter; lowercase letters, digits, - and _.
+	Name string
+	// Description is shown in /help and as the Discord option description.
+	Description string
+	// Type is the value type; empty means ParamString.
+	Type ParamType
+	// Required parameters must come before optional ones.
+	Required bool
+	// Choices are the accepted values of an enum parameter. On string
+	// parameters they are only offered as completions.
+	Choices []string
+}
+
+// Args holds the validated parameter values of a command invocation, keyed
+// by parameter name. Unset optional parameters are absent.
+type Args map[string]string
+
+// String returns the value of a parameter, or "" when it is unset.
+func (a Args) String(name string) string {
+	return a[name]
+}
+
+// Int returns the value of an int parameter, or 0 when it is unset.
+func (a Args) Int(name string) int {
+	n, _ := strconv.Atoi(a[name])
+	return n
+}
+
+// Has reports whether a parameter was given.
+func (a Args) Has(name string) bool {
+	_, ok := a[name]
+	return ok
+}
+
+// ArgError describes an invalid command invocation.
+type ArgError struct {
+	Param   string
+	Message string
+}
+
+func (e *ArgError) Error() string {
+	return e.Message
+}
+
+var paramNamePattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+func (p Param) valueType() ParamType {
+	if p.Type == "" {
+		return ParamString
+	}
+	return p.Type
+}
+
+// validateParams checks a parameter schema when a command is registered.
+func validateParams(params []Param) error {
+	seen := make(map[string]struct{}, len(params))
+	optional := false
+	for _, p := range params {
+		if !paramNamePattern.MatchString(p.Name) {
+			return fmt.Errorf("invalid parameter name %q", p.Name)
+		}
+		if _, ok := seen[p.Name]; ok {
+			return fmt.Errorf("duplicate parameter %q", p.Name)
+		}
+		seen[p.Name] = struct{}{}
+		switch p.valueType() {
+		case ParamString, ParamInt:
+		case ParamEnum:
+			if len(p.Choices) == 0 {
+				return fmt.Errorf("enum parameter %q has no choices", p.Name)
+			}
+		default:
+			return fmt.Errorf("parameter %q has unknown type %q", p.Name, p.Type)
+		}
+		if p.Required && optional {
+			return fmt.Errorf("required parameter %q follows an optional one", p.Name)
+		}
+		optional = optional || !p.Required
+	}
+	return nil
+}
+
+// ParseArgs splits raw command text into the declared parameters and
+// validates them.
+func (c *Command) ParseArgs(raw string) (Args, error) {
+	values := make(map[string]string, len(c.Params))
+	rest := strings.TrimSpace(raw)
+	for i, p := range c.Params {
+		if rest == "" {
+			break
+		}
+		if i == len(c.Params)-1 && p.valueType() == ParamString {
+			values[p.Name] = rest
+			rest = ""
+			break
+		}
+		token := rest
+		rest = ""
+		if idx := strings.IndexFunc(token, unicode.IsSpace); idx >= 0 {
+			token, rest = token[:idx], strings.TrimSpace(token[idx:])
+		}
+		values[p.Name] = token
+	}
+	if rest != "" {
+		return nil, &ArgError{Message: "Too many arguments: " + rest}
+	}
+	return c.BindArgs(values)
+}
+
+// BindArgs validates parameter values given by name, as channels with
+// native command options provide them, and normalizes enum values to
+// their declared spelling.
+func (c *Command) BindArgs(values map[string]string) (Args, error) {
+	args := make(Args, len(values))
+	for _, p := range c.Params {
+		value := strings.TrimSpace(values[p.Name])
+		if value == "" {
+			if p.Required {
+				return nil, &ArgError{Param: p.Name, Message: "Missing " + p.Name}
+			}
+			continue
+		}
+		switch p.valueType() {
+		case ParamInt:
+			if _, err := strconv.Atoi(value); err != nil {
+				return nil, &ArgError{Param: p.Name, Message: fmt.Sprintf("%s must be a number: %s", p.Name, value)}
+			}
+		case ParamEnum:
+			matched := ""
+			for _, choice := range p.Choices {
+				if strings.EqualFold(choice, value) {
+					matched = choice
+					break
+				}
+			}
+			if matched == "" {
+				return nil, &ArgError{
+					Param:   p.Name,
+					Message: fmt.Sprintf("Unsupported %s: %s\nSupported: %s", p.Name, value, strings.Join(p.Choices, ", ")),
+				}
+			}
+			value = matched
+		}
+		args[p.Name] = value
+	}
+	return args, nil
+}
+
+// ArgsText renders args back into command text in parameter order.
+func (c *Command) ArgsText(args Args) string {
+	parts := make([]string, 0, len(c.Params))
+	for _, p := range c.Params {
+		if value, ok := args[p.Name]; ok {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParamHint renders the parameters as "<a|b> [name]", or "" for commands
+// without declared parameters.
+func (c *Command) ParamHint() string {
+	parts := make([]string, 0, len(c.Params))
+	for _, p := range c.Params {
+		label := p.Name
+		if len(p.Choices) > 0 {
+			label = strings.Join(p.Choices, "|")
+		}
+		if p.Required {
+			parts = append(parts, "<"+label+">")
+		} else {
+			parts = append(parts, "["+label+"]")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// UsageText returns Usage, or a usage line generated from Params.
+func (c *Command) UsageText() string {
+	if usage := strings.TrimSpace(c.Usage); usage != "" {
+		return usage
+	}
+	usage := "/" + c.Name
+	if hint := c.ParamHint(); hint != "" {
+		usage += " " + hint
+	}
+	return usage
+}
+
+// validatedHandler parses and validates the arguments of c before calling
+// handler, so handlers read req.Params instead of parsing req.Args.
+func (c *Command) validatedHandler(handler CommandHandler) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		var (
+			args Args
+			err  error
+		)
+		if req.Params != nil {
+			args, err = c.BindArgs(req.Params)
+		} else {
+			args, err = c.ParseArgs(req.Args)
+		}
+		if err != nil {
+			return CommandResponse{
+				Content:     "❌ " + err.Error() + "\nUsage: " + c.UsageText(),
+				ReplyInline: true,
+			}, nil
+		}
+		req.Params = args
+		if strings.TrimSpace(req.Args) == "" {
+			req.Args = c.ArgsText(args)
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// registeredHandler returns the handler of a command as registered, with
+// argument validation in front of it.
+func registeredHandler(t *testing.T, deps Dependencies, name string) CommandHandler {
+	t.Helper()
+	reg := NewRegistry()
+	if err := RegisterBuiltinCommands(reg); err != nil {
+		t.Fatalf("register builtin commands: %v", err)
+	}
+	if err := RegisterAdvancedCommands(reg, deps); err != nil {
+		t.Fatalf("register advanced commands: %v", err)
+	}
+	cmd, ok := reg.Get(name)
+	if !ok {
+		t.Fatalf("command %s not registered", name)
+	}
+	return cmd.Handler
+}
+
+func TestRegisteredCommandsValidateParams(t *testing.T) {
+	var got CommandRequest
+	reg := NewRegistry()
+	cmd := &Command{
+		Name: "remind",
+		Params: []Param{
+			{Name: "unit", Type: ParamEnum, Required: true, Choices: []string{"min", "hour"}},
+			{Name: "count", Type: ParamInt, Required: true},
+			{Name: "text", Description: "What to remind you of"},
+		},
+		Handler: func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+			got = req
+			return CommandResponse{Content: "ok"}, nil
+		},
+	}
+	if err := reg.Register(cmd); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if cmd.UsageText() != "/remind <min|hour> <count> [text]" {
+		t.Fatalf("unexpected generated usage %q", cmd.UsageText())
+	}
+
+	ctx := context.Background()
+	resp, _ := cmd.Handler(ctx, CommandRequest{Args: "HOUR 2  water the\tplants"})
+	if resp.Content != "ok" || got.Params.String("unit") != "hour" || got.Params.Int("count") != 2 ||
+		got.Params.String("text") != "water the\tplants" {
+		t.Fatalf("unexpected parsed request %+v (%q)", got.Params, resp.Content)
+	}
+
+	for args, want := range map[string]string{
+		"":          "Missing unit",
+		"week 2":    "Unsupported unit: week",
+		"min two x": "count must be a number: two",
+	} {
+		resp, _ := cmd.Handler(ctx, CommandRequest{Args: args})
+		if !strings.Contains(resp.Content, want) || !strings.Contains(resp.Content, "Usage: /remind") {
+			t.Fatalf("args %q: expected %q with usage, got %q", args, want, resp.Content)
+		}
+	}
+
+	// Native command options arrive by name and are validated the same way.
+	resp, _ = cmd.Handler(ctx, CommandRequest{Params: Args{"unit": "Min", "count": "5"}})
+	if resp.Content != "ok" || got.Params.String("unit") != "min" || got.Args != "min 5" {
+		t.Fatalf("unexpected bound request %+v args=%q", got.Params, got.Args)
+	}
+}
+
+func TestRegisterRejectsInvalidParamSchemas(t *testing.T) {
+	for name, params := range map[string][]Param{
+		"required after optional": {{Name: "a"}, {Name: "b", Required: true}},
+		"enum without choices":    {{Name: "a", Type: ParamEnum}},
+		"bad name":                {{Name: "Has Space"}},
+		"duplicate":               {{Name: "a"}, {Name: "a"}},
+	} {
+		if err := NewRegistry().Register(&Command{Name: "x", Params: params}); err == nil {
+			t.Fatalf("%s: expected registration to fail", name)
+		}
+	}
+}
+
+func TestHelpDocumentsCommandParams(t *testing.T) {
+	help := registeredHandler(t, Dependencies{}, "help")
+	resp, err := help(context.Background(), CommandRequest{Args: "/feedback"})
+	if err != nil {
+		t.Fatalf("help: %v", err)
+	}
+	for _, want := range []string{
+		"**Usage:** /feedback <up|down> [comment]",
+		"`rating` (string) - up or down [e.g.: up, down]",
+		"`comment` (string, optional)",
+	} {
+		if !strings.Contains(resp.Content, want) {
+			t.Fatalf("help missing %q:\n%s", want, resp.Content)
+		}
+	}
+}
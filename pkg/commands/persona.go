@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"nekobot/pkg/session"
+)
+
+const personaUsage = "Usage: /persona [text|reset]"
+
+// personaHandler handles the /persona command.
+func personaHandler(sessionMgr *session.Manager) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		sess, errMsg := chatSession(sessionMgr, req)
+		if errMsg != "" {
+			return CommandResponse{Content: errMsg, ReplyInline: true}, nil
+		}
+
+		text := strings.TrimSpace(req.Args)
+		switch strings.ToLower(text) {
+		case "":
+			return CommandResponse{Content: formatPersona(sess.GetPersona()), ReplyInline: true}, nil
+		case "reset", "clear", "default":
+			sess.SetPersona("")
+			return CommandResponse{Content: "✅ Persona cleared. This chat uses the default prompt again.", ReplyInline: true}, nil
+		}
+
+		sess.SetPersona(text)
+		return CommandResponse{Content: "🎭 Persona set for this chat. Use `/persona reset` to clear it.", ReplyInline: true}, nil
+	}
+}
+
+func formatPersona(persona string) string {
+	if persona == "" {
+		return "🎭 No persona set. This chat uses the default prompt.\n\n" + personaUsage
+	}
+	return "🎭 **Persona**\n\n" + persona + "\n\n" + personaUsage
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/session"
+)
+
+func TestPersonaCommandSetsAndClearsChatPersona(t *testing.T) {
+	sessionMgr := session.NewManager(t.TempDir(), config.DefaultConfig().Sessions)
+	req := CommandRequest{Channel: "telegram", ChatID: "42"}
+	ctx := context.Background()
+
+	resp, err := personaHandler(sessionMgr)(ctx, req)
+	if err != nil {
+		t.Fatalf("show persona failed: %v", err)
+	}
+	if !strings.Contains(resp.Content, "No persona set") {
+		t.Fatalf("unexpected empty persona response: %q", resp.Content)
+	}
+
+	req.Args = "You are Captain Hook. Speak like a pirate."
+	if _, err := personaHandler(sessionMgr)(ctx, req); err != nil {
+		t.Fatalf("set persona failed: %v", err)
+	}
+	sess, err := sessionMgr.GetExisting("telegram:42")
+	if err != nil {
+		t.Fatalf("expected chat session: %v", err)
+	}
+	if got := sess.GetPersona(); got != "You are Captain Hook. Speak like a pirate." {
+		t.Fatalf("unexpected persona %q", got)
+	}
+
+	req.Args = ""
+	resp, _ = personaHandler(sessionMgr)(ctx, req)
+	if !strings.Contains(resp.Content, "Captain Hook") {
+		t.Fatalf("expected persona to be shown, got %q", resp.Content)
+	}
+
+	req.Args = "reset"
+	if _, err := personaHandler(sessionMgr)(ctx, req); err != nil {
+		t.Fatalf("reset persona failed: %v", err)
+	}
+	if got := sess.GetPersona(); got != "" {
+		t.Fatalf("expected persona to be cleared, got %q", got)
+	}
+}
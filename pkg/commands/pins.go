@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"nekobot/pkg/session"
+)
+
+// pinSessionMetadataKey lets channels override the derived session ID.
+const pinSessionMetadataKey = "session_id"
+
+// commandSessionID maps a command invocation to the chat session it belongs to.
+// Channels key sessions as "<channel>:<chat>" unless they pass an explicit ID.
+func commandSessionID(req CommandRequest) string {
+	if sessionID := strings.TrimSpace(req.Metadata[pinSessionMetadataKey]); sessionID != "" {
+		return sessionID
+	}
+	channel := strings.TrimSpace(req.Channel)
+	chatID := strings.TrimSpace(req.ChatID)
+	if channel == "" || chatID == "" {
+		return ""
+	}
+	return channel + ":" + chatID
+}
+
+// chatSession loads the chat session a command applies to, or returns a
+// user-facing error message.
+func chatSession(sessionMgr *session.Manager, req CommandRequest) (*session.Session, string) {
+	if sessionMgr == nil {
+		return nil, "❌ Session state is unavailable (session manager not initialized)"
+	}
+	sessionID := commandSessionID(req)
+	if sessionID == "" {
+		return nil, "❌ Cannot determine the session for this chat"
+	}
+	sess, err := sessionMgr.GetWithSource(sessionID, session.SourceChannels)
+	if err != nil {
+		return nil, "❌ Failed to load session: " + err.Error()
+	}
+	return sess, ""
+}
+
+// pinHandler handles the /pin command.
+func pinHandler(sessionMgr *session.Manager) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		text := req.Params.String("text")
+		sess, errMsg := chatSession(sessionMgr, req)
+		if errMsg != "" {
+			return CommandResponse{Content: errMsg, ReplyInline: true}, nil
+		}
+		if text == "" {
+			return CommandResponse{Content: formatPins(sess.GetPins()), ReplyInline: true}, nil
+		}
+		if !sess.AddPin(text) {
+			return CommandResponse{Content: "ℹ️ Already pinned", ReplyInline: true}, nil
+		}
+		return CommandResponse{
+			Content:     fmt.Sprintf("📌 Pinned (#%d). It will stay in context until you /unpin it.", len(sess.GetPins())),
+			ReplyInline: true,
+		}, nil
+	}
+}
+
+// unpinHandler handles the /unpin command.
+func unpinHandler(sessionMgr *session.Manager) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		arg := req.Params.String("pin")
+		sess, errMsg := chatSession(sessionMgr, req)
+		if errMsg != "" {
+			return CommandResponse{Content: errMsg, ReplyInline: true}, nil
+		}
+		if strings.EqualFold(arg, "all") {
+			sess.ClearPins()
+			return CommandResponse{Content: "✅ All pins removed", ReplyInline: true}, nil
+		}
+		index, err := strconv.Atoi(arg)
+		if err != nil {
+			return CommandResponse{Content: "❌ Usage: /unpin <number|all>", ReplyInline: true}, nil
+		}
+		removed, ok := sess.RemovePin(index)
+		if !ok {
+			return CommandResponse{Content: fmt.Sprintf("❌ No pin #%d. Use /pins to list pins.", index), ReplyInline: true}, nil
+		}
+		return CommandResponse{Content: "✅ Unpinned: " + removed, ReplyInline: true}, nil
+	}
+}
+
+// pinsHandler handles the /pins command.
+func pinsHandler(sessionMgr *session.Manager) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		sess, errMsg := chatSession(sessionMgr, req)
+		if errMsg != "" {
+			return CommandResponse{Content: errMsg, ReplyInline: true}, nil
+		}
+		return CommandResponse{Content: formatPins(sess.GetPins()), ReplyInline: true}, nil
+	}
+}
+
+func formatPins(pins []string) string {
+	if len(pins) == 0 {
+		return "📌 No pinned context. Use `/pin <text>` to add one."
+	}
+	var sb strings.Builder
+	sb.WriteString("📌 **Pinned Context**\n\n")
+	for i, pin := range pins {
+		_, _ = fmt.Fprintf(&sb, "%d. %s\n", i+1, pin)
+	}
+	sb.WriteString("\nUse `/unpin <number>` to remove one.")
+	return sb.String()
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/session"
+)
+
+func TestPinCommandsManageChatSessionPins(t *testing.T) {
+	sessionMgr := session.NewManager(t.TempDir(), config.DefaultConfig().Sessions)
+	req := CommandRequest{Channel: "telegram", ChatID: "42"}
+	ctx := context.Background()
+
+	req.Args = "Always answer in English."
+	resp, err := registeredHandler(t, Dependencies{SessionManager: sessionMgr}, "pin")(ctx, req)
+	if err != nil {
+		t.Fatalf("pin failed: %v", err)
+	}
+	if !strings.Contains(resp.Content, "#1") {
+		t.Fatalf("unexpected pin response: %q", resp.Content)
+	}
+
+	sess, err := sessionMgr.GetExisting("telegram:42")
+	if err != nil {
+		t.Fatalf("expected pinned chat session: %v", err)
+	}
+	if pins := sess.GetPins(); len(pins) != 1 || pins[0] != "Always answer in English." {
+		t.Fatalf("unexpected pins: %#v", pins)
+	}
+
+	req.Args = ""
+	resp, _ = pinsHandler(sessionMgr)(ctx, req)
+	if !strings.Contains(resp.Content, "1. Always answer in English.") {
+		t.Fatalf("unexpected pins listing: %q", resp.Content)
+	}
+
+	req.Args = "2"
+	resp, _ = registeredHandler(t, Dependencies{SessionManager: sessionMgr}, "unpin")(ctx, req)
+	if !strings.Contains(resp.Content, "No pin #2") {
+		t.Fatalf("expected missing pin error, got %q", resp.Content)
+	}
+
+	req.Args = "1"
+	if _, err := registeredHandler(t, Dependencies{SessionManager: sessionMgr}, "unpin")(ctx, req); err != nil {
+		t.Fatalf("unpin failed: %v", err)
+	}
+	if pins := sess.GetPins(); len(pins) != 0 {
+		t.Fatalf("expected pins removed, got %#v", pins)
+	}
+}
+
+func TestCommandSessionIDPrefersMetadataOverride(t *testing.T) {
+	req := CommandRequest{Channel: "slack", ChatID: "C1", Metadata: map[string]string{"session_id": "slack-work:C1"}}
+	if got := commandSessionID(req); got != "slack-work:C1" {
+		t.Fatalf("expected metadata session id, got %q", got)
+	}
+	req.Metadata = nil
+	if got := commandSessionID(req); got != "slack:C1" {
+		t.Fatalf("expected derived session id, got %q", got)
+	}
+}
+
+func TestOrchestratorCommandSetsChatSessionOverride(t *testing.T) {
+	cfg := config.DefaultConfig()
+	sessionMgr := session.NewManager(t.TempDir(), cfg.Sessions)
+	req := CommandRequest{Channel: "telegram", ChatID: "42"}
+	ctx := context.Background()
+
+	req.Args = "LEGACY"
+	resp, err := registeredHandler(t, Dependencies{Config: cfg, SessionManager: sessionMgr}, "orchestrator")(ctx, req)
+	if err != nil {
+		t.Fatalf("orchestrator failed: %v", err)
+	}
+	if !strings.Contains(resp.Content, "legacy orchestrator") {
+		t.Fatalf("unexpected response: %q", resp.Content)
+	}
+	sess, err := sessionMgr.GetExisting("telegram:42")
+	if err != nil {
+		t.Fatalf("expected chat session: %v", err)
+	}
+	if got := sess.GetOrchestrator(); got != "legacy" {
+		t.Fatalf("expected legacy override, got %q", got)
+	}
+
+	req.Args = "swarm"
+	resp, _ = registeredHandler(t, Dependencies{Config: cfg, SessionManager: sessionMgr}, "orchestrator")(ctx, req)
+	if !strings.Contains(resp.Content, "unsupported orchestrator: swarm") {
+		t.Fatalf("expected unsupported orchestrator error, got %q", resp.Content)
+	}
+	if got := sess.GetOrchestrator(); got != "legacy" {
+		t.Fatalf("invalid value must not change override, got %q", got)
+	}
+
+	req.Args = "default"
+	if _, err := registeredHandler(t, Dependencies{Config: cfg, SessionManager: sessionMgr}, "orchestrator")(ctx, req); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	if got := sess.GetOrchestrator(); got != "" {
+		t.Fatalf("expected override cleared, got %q", got)
+	}
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"nekobot/pkg/plugins"
+)
+
+// PluginManager lists tool plugins and installs or removes WASM tools.
+type PluginManager interface {
+	Status() []plugins.Status
+	InstallWasm(ctx context.Context, source string) (plugins.Status, error)
+	UninstallWasm(ctx context.Context, name string) error
+}
+
+const pluginsUsage = "Usage: /plugins [list|install <dir, .zip or URL>|uninstall <name>]"
+
+// pluginsHandler handles the /plugins command.
+func pluginsHandler(manager PluginManager) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		if manager == nil {
+			return CommandResponse{
+				Content:     "❌ Plugins are unavailable (plugin manager not initialized)",
+				ReplyInline: true,
+			}, nil
+		}
+
+		fields := strings.Fields(req.Args)
+		if len(fields) == 0 || (len(fields) == 1 && strings.EqualFold(fields[0], "list")) {
+			return CommandResponse{Content: formatPlugins(manager.Status()), ReplyInline: true}, nil
+		}
+		if len(fields) != 2 {
+			return CommandResponse{Content: "❌ " + pluginsUsage, ReplyInline: true}, nil
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case "install":
+			status, err := manager.InstallWasm(ctx, fields[1])
+			if err != nil {
+				return CommandResponse{Content: "❌ Failed to install plugin: " + err.Error(), ReplyInline: true}, nil
+			}
+			return CommandResponse{
+				Content:     fmt.Sprintf("✅ Installed WASM tool `%s` (workspace: %s)", status.Name, status.Workspace),
+				ReplyInline: true,
+			}, nil
+		case "uninstall":
+			err := manager.UninstallWasm(ctx, fields[1])
+			if errors.Is(err, plugins.ErrNotFound) {
+				return CommandResponse{Content: "❌ No installed WASM tool named " + fields[1], ReplyInline: true}, nil
+			}
+			if err != nil {
+				return CommandResponse{Content: "❌ Failed to uninstall plugin: " + err.Error(), ReplyInline: true}, nil
+			}
+			return CommandResponse{Content: "✅ Uninstalled WASM tool `" + fields[1] + "`", ReplyInline: true}, nil
+		default:
+			return CommandResponse{Content: "❌ " + pluginsUsage, ReplyInline: true}, nil
+		}
+	}
+}
+
+func formatPlugins(statuses []plugins.Status) string {
+	if len(statuses) == 0 {
+		return "🧩 No plugins installed."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🧩 Plugins:\n")
+	for _, status := range statuses {
+		state := "running"
+		if !status.Running {
+			state = "stopped"
+		}
+		fmt.Fprintf(&sb, "\n• `%s` [%s] %s", status.Name, status.Kind, state)
+		if status.Workspace != "" {
+			fmt.Fprintf(&sb, ", workspace %s", status.Workspace)
+		}
+		if len(status.Tools) > 0 {
+			fmt.Fprintf(&sb, " — %s", strings.Join(status.Tools, ", "))
+		}
+		if status.LastError != "" {
+			fmt.Fprintf(&sb, "\n  ⚠️ %s", status.LastError)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/plugins"
+)
+
+type fakePluginManager struct {
+	statuses []plugins.Status
+}
+
+func (m *fakePluginManager) Status() []plugins.Status { return m.statuses }
+
+func (m *fakePluginManager) InstallWasm(_ context.Context, source string) (plugins.Status, error) {
+	status := plugins.Status{Name: strings.TrimSuffix(source, ".zip"), Kind: plugins.KindWasm, Running: true, Workspace: plugins.WasmWorkspaceReadOnly}
+	m.statuses = append(m.statuses, status)
+	return status, nil
+}
+
+func (m *fakePluginManager) UninstallWasm(_ context.Context, name string) error {
+	for i, status := range m.statuses {
+		if status.Name == name && status.Kind == plugins.KindWasm {
+			m.statuses = append(m.statuses[:i], m.statuses[i+1:]...)
+			return nil
+		}
+	}
+	return plugins.ErrNotFound
+}
+
+func TestPluginsCommandInstallsListsAndUninstalls(t *testing.T) {
+	manager := &fakePluginManager{statuses: []plugins.Status{
+		{Name: "search", Kind: plugins.KindGRPC, LastError: "exited before serving"},
+	}}
+	handler := pluginsHandler(manager)
+	ctx := context.Background()
+
+	resp, _ := handler(ctx, CommandRequest{Args: "install word_count.zip"})
+	if !strings.Contains(resp.Content, "Installed WASM tool `word_count`") {
+		t.Fatalf("unexpected install response: %q", resp.Content)
+	}
+
+	resp, _ = handler(ctx, CommandRequest{})
+	for _, want := range []string{"`search` [grpc] stopped", "exited before serving", "`word_count` [wasm] running, workspace read_only"} {
+		if !strings.Contains(resp.Content, want) {
+			t.Fatalf("expected %q in list, got %q", want, resp.Content)
+		}
+	}
+
+	resp, _ = handler(ctx, CommandRequest{Args: "uninstall word_count"})
+	if !strings.Contains(resp.Content, "Uninstalled WASM tool") || len(manager.statuses) != 1 {
+		t.Fatalf("unexpected uninstall response: %q", resp.Content)
+	}
+	resp, _ = handler(ctx, CommandRequest{Args: "uninstall search"})
+	if !strings.Contains(resp.Content, "No installed WASM tool named search") {
+		t.Fatalf("expected gRPC plugins to be left alone, got %q", resp.Content)
+	}
+
+	resp, _ = handler(ctx, CommandRequest{Args: "remove search"})
+	if !strings.Contains(resp.Content, pluginsUsage) {
+		t.Fatalf("expected usage, got %q", resp.Content)
+	}
+	resp, _ = pluginsHandler(nil)(ctx, CommandRequest{})
+	if !strings.Contains(resp.Content, "unavailable") {
+		t.Fatalf("expected unavailable message, got %q", resp.Content)
+	}
+}
@@ -33,6 +33,10 @@ func (r *Registry) Register(cmd *Command) error {
 	// Normalize command name (lowercase, no /)
 	cmd.Name = strings.ToLower(strings.TrimPrefix(cmd.Name, "/"))
 
+	if err := validateParams(cmd.Params); err != nil {
+		return fmt.Errorf("command %s: %w", cmd.Name, err)
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -40,6 +44,9 @@ func (r *Registry) Register(cmd *Command) error {
 		return fmt.Errorf("command %s already registered", cmd.Name)
 	}
 
+	if len(cmd.Params) > 0 && cmd.Handler != nil {
+		cmd.Handler = cmd.validatedHandler(cmd.Handler)
+	}
 	r.commands[cmd.Name] = cmd
 	return nil
 }
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"nekobot/pkg/skills"
+)
+
+// resolveSkillProposal checks a proposed repo against the curated skills
+// index and fills in the release to install. It returns a refusal message
+// when skills_index_only rejects the repo.
+func resolveSkillProposal(ctx context.Context, skillsMgr *skills.Manager, proposal *SkillInstallProposal) string {
+	entry, refusal, ok := lookupIndexedSkill(ctx, skillsMgr, proposal.Repo)
+	if !ok {
+		return refusal
+	}
+	release := entry.Latest()
+	proposal.Repo = entry.Repo
+	proposal.Version = release.Version
+	proposal.Changelog = release.ShortChangelog()
+	proposal.Permissions = entry.Permissions.Requested()
+	return ""
+}
+
+// installConfirmedSkill installs a confirmed repo from the curated skills
+// index. handled is false when the repo should go through the agent-driven
+// install flow instead.
+func installConfirmedSkill(ctx context.Context, skillsMgr *skills.Manager, repo, version string) (string, bool) {
+	entry, refusal, ok := lookupIndexedSkill(ctx, skillsMgr, repo)
+	if !ok {
+		return refusal, refusal != ""
+	}
+	installed, err := skillsMgr.InstallIndexed(ctx, entry.ID, version)
+	if err != nil {
+		return "❌ Failed to install skill: " + err.Error(), true
+	}
+	return fmt.Sprintf("✅ Installed skill %s %s from %s", installed.ID, installed.Version, installed.Repo), true
+}
+
+// lookupIndexedSkill finds repo in the skills index. ok is false when the
+// index does not list it; refusal is then set if skills_index_only forbids
+// installing it anyway.
+func lookupIndexedSkill(ctx context.Context, skillsMgr *skills.Manager, repo string) (skills.IndexEntry, string, bool) {
+	if !skillsMgr.HasIndex() {
+		return skills.IndexEntry{}, "", false
+	}
+	entry, err := skillsMgr.LookupIndex(ctx, repo)
+	switch {
+	case err == nil:
+		return entry, "", true
+	case !skillsMgr.IndexOnly():
+		return skills.IndexEntry{}, "", false
+	case errors.Is(err, skills.ErrSkillNotInIndex):
+		return skills.IndexEntry{}, fmt.Sprintf("❌ %s is not in the curated skills index, so it can't be installed from chat.", repo), false
+	default:
+		return skills.IndexEntry{}, "❌ Skills index unavailable: " + err.Error(), false
+	}
+}
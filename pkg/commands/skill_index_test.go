@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/logger"
+	"nekobot/pkg/skills"
+)
+
+func newIndexedSkillsManager(t *testing.T, indexOnly bool) *skills.Manager {
+	t.Helper()
+	log, err := logger.New(&logger.Config{Level: logger.LevelError})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	root := t.TempDir()
+	indexPath := filepath.Join(root, "index.json")
+	index := `{"skills":[{"id":"pdf-tools","repo":"acme/pdf-skill","permissions":{"file_write":true},"versions":[
+		{"version":"v1.0.0"},
+		{"version":"v1.1.0","changelog":"Adds merging"}
+	]}]}`
+	if err := os.WriteFile(indexPath, []byte(index), 0o644); err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+	mgr := skills.NewManager(log, filepath.Join(root, "skills"), false)
+	mgr.SetIndex(indexPath, indexOnly)
+	return mgr
+}
+
+func TestResolveSkillProposalFillsLatestIndexVersion(t *testing.T) {
+	mgr := newIndexedSkillsManager(t, false)
+	proposal := SkillInstallProposal{Repo: "ACME/pdf-skill"}
+	if refusal := resolveSkillProposal(context.Background(), mgr, &proposal); refusal != "" {
+		t.Fatalf("unexpected refusal: %s", refusal)
+	}
+	if proposal.Repo != "acme/pdf-skill" || proposal.Version != "v1.1.0" || proposal.Changelog != "Adds merging" ||
+		strings.Join(proposal.Permissions, ",") != "file_write" {
+		t.Fatalf("unexpected proposal: %+v", proposal)
+	}
+
+	unlisted := SkillInstallProposal{Repo: "someone/else"}
+	if refusal := resolveSkillProposal(context.Background(), mgr, &unlisted); refusal != "" || unlisted.Version != "" {
+		t.Fatalf("expected unlisted repos to pass through without index-only, got %q %+v", refusal, unlisted)
+	}
+}
+
+func TestResolveSkillProposalRefusesUnlistedReposWhenIndexOnly(t *testing.T) {
+	mgr := newIndexedSkillsManager(t, true)
+	proposal := SkillInstallProposal{Repo: "someone/else"}
+	refusal := resolveSkillProposal(context.Background(), mgr, &proposal)
+	if !strings.Contains(refusal, "not in the curated skills index") {
+		t.Fatalf("expected index-only refusal, got %q", refusal)
+	}
+	if reply, handled := installConfirmedSkill(context.Background(), mgr, "someone/else", ""); !handled || reply != refusal {
+		t.Fatalf("expected confirmed install to be refused too, got handled=%v %q", handled, reply)
+	}
+}
+
+func TestConfirmInstallArgsRoundTripsVersion(t *testing.T) {
+	repo, version := parseConfirmedInstallRepo(ConfirmInstallArgs("acme/pdf-skill", "v1.1.0"))
+	if repo != "acme/pdf-skill" || version != "v1.1.0" {
+		t.Fatalf("unexpected round trip: %q %q", repo, version)
+	}
+	repo, version = parseConfirmedInstallRepo(ConfirmInstallArgs("acme/pdf-skill", ""))
+	if repo != "acme/pdf-skill" || version != "" {
+		t.Fatalf("unexpected round trip without version: %q %q", repo, version)
+	}
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"nekobot/pkg/background"
+	"nekobot/pkg/cron"
+	"nekobot/pkg/tasks"
+)
+
+// TaskScheduler exposes the owner-scoped view of scheduled jobs.
+type TaskScheduler interface {
+	ListPendingJobs(ownerUserID string) []*cron.Job
+	CancelJob(ownerUserID, jobID string) error
+}
+
+// BackgroundJobs exposes the chat-scoped view of background jobs.
+type BackgroundJobs interface {
+	List(channel, chatID string) []background.Job
+	Cancel(channel, chatID, jobID string) error
+}
+
+const tasksUsage = "Usage: /tasks or /tasks cancel <id>"
+
+// tasksHandler handles the /tasks command. It lists the user's scheduled
+// reminders and the background jobs started in this chat.
+func tasksHandler(scheduler TaskScheduler, jobs BackgroundJobs) CommandHandler {
+	return func(ctx context.Context, req CommandRequest) (CommandResponse, error) {
+		if scheduler == nil && jobs == nil {
+			return CommandResponse{
+				Content:     "❌ Scheduled tasks are unavailable (scheduler not initialized)",
+				ReplyInline: true,
+			}, nil
+		}
+		owner := strings.TrimSpace(req.UserID)
+		if owner == "" {
+			return CommandResponse{Content: "❌ Unable to identify the requesting user", ReplyInline: true}, nil
+		}
+
+		fields := strings.Fields(req.Args)
+		if len(fields) == 0 {
+			var sections []string
+			if scheduler != nil {
+				sections = append(sections, formatPendingTasks(scheduler.ListPendingJobs(owner)))
+			}
+			if jobs != nil {
+				if list := jobs.List(req.Channel, req.ChatID); len(list) > 0 {
+					sections = append(sections, formatBackgroundJobs(list))
+				}
+			}
+			if len(sections) == 0 {
+				sections = append(sections, "🛠 No background jobs in this chat.")
+			}
+			return CommandResponse{Content: strings.Join(sections, "\n\n"), ReplyInline: true}, nil
+		}
+		if !strings.EqualFold(fields[0], "cancel") || len(fields) != 2 {
+			return CommandResponse{Content: "❌ " + tasksUsage, ReplyInline: true}, nil
+		}
+
+		if jobs != nil {
+			err := jobs.Cancel(req.Channel, req.ChatID, fields[1])
+			if err == nil {
+				return CommandResponse{Content: "✅ Cancelled background job " + fields[1], ReplyInline: true}, nil
+			}
+			if !errors.Is(err, background.ErrJobNotFound) || scheduler == nil {
+				return CommandResponse{Content: "❌ " + err.Error(), ReplyInline: true}, nil
+			}
+		}
+		if err := scheduler.CancelJob(owner, fields[1]); err != nil {
+			return CommandResponse{Content: "❌ " + err.Error(), ReplyInline: true}, nil
+		}
+		return CommandResponse{Content: "✅ Cancelled task " + fields[1], ReplyInline: true}, nil
+	}
+}
+
+func formatPendingTasks(jobs []*cron.Job) string {
+	if len(jobs) == 0 {
+		return "⏰ No pending scheduled tasks."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("⏰ Pending scheduled tasks:\n")
+	for _, job := range jobs {
+		fmt.Fprintf(&sb, "\n• `%s` %s — %s", job.ID, job.Name, job.NextRun.Local().Format(time.DateTime))
+	}
+	sb.WriteString("\n\nUse `/tasks cancel <id>` to cancel one.")
+	return sb.String()
+}
+
+func formatBackgroundJobs(jobs []background.Job) string {
+	var sb strings.Builder
+	sb.WriteString("🛠 Background jobs in this chat:\n")
+	for _, job := range jobs {
+		id := job.ID
+		if job.Kind == background.KindAgent && len(id) > 8 {
+			id = id[:8]
+		}
+		fmt.Fprintf(&sb, "\n• `%s` [%s] %s — %s", id, job.Kind, job.Label, job.State)
+		if tasks.IsFinal(job.State) {
+			fmt.Fprintf(&sb, " (%s)", job.CompletedAt.Local().Format(time.DateTime))
+		} else {
+			fmt.Fprintf(&sb, " for %s", time.Since(job.CreatedAt).Round(time.Second))
+		}
+	}
+	sb.WriteString("\n\nUse `/tasks cancel <id>` to stop a running job.")
+	return sb.String()
+}
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"nekobot/pkg/background"
+	"nekobot/pkg/cron"
+	"nekobot/pkg/tasks"
+)
+
+type fakeTaskScheduler struct {
+	jobs map[string][]*cron.Job
+}
+
+func (s *fakeTaskScheduler) ListPendingJobs(ownerUserID string) []*cron.Job {
+	return s.jobs[ownerUserID]
+}
+
+func (s *fakeTaskScheduler) CancelJob(ownerUserID, jobID string) error {
+	jobs := s.jobs[ownerUserID]
+	for i, job := range jobs {
+		if job.ID == jobID {
+			s.jobs[ownerUserID] = append(jobs[:i], jobs[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("job not found: %s", jobID)
+}
+
+func TestTasksCommandListsAndCancelsOwnReminders(t *testing.T) {
+	scheduler := &fakeTaskScheduler{jobs: map[string][]*cron.Job{
+		"alice": {{ID: "job-1", Name: "standup", NextRun: time.Now().Add(time.Hour)}},
+		"bob":   {{ID: "job-2", Name: "payday", NextRun: time.Now().Add(time.Hour)}},
+	}}
+	handler := tasksHandler(scheduler, nil)
+	ctx := context.Background()
+	req := CommandRequest{Channel: "telegram", ChatID: "42", UserID: "alice"}
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		t.Fatalf("tasks failed: %v", err)
+	}
+	if !strings.Contains(resp.Content, "job-1") || strings.Contains(resp.Content, "job-2") {
+		t.Fatalf("expected only alice's tasks, got %q", resp.Content)
+	}
+
+	req.Args = "cancel job-2"
+	resp, _ = handler(ctx, req)
+	if !strings.Contains(resp.Content, "job not found") || len(scheduler.jobs["bob"]) != 1 {
+		t.Fatalf("expected foreign cancel to be rejected, got %q", resp.Content)
+	}
+
+	req.Args = "cancel job-1"
+	resp, _ = handler(ctx, req)
+	if !strings.Contains(resp.Content, "Cancelled task job-1") {
+		t.Fatalf("unexpected cancel response: %q", resp.Content)
+	}
+
+	req.Args = ""
+	resp, _ = handler(ctx, req)
+	if !strings.Contains(resp.Content, "No pending scheduled tasks") {
+		t.Fatalf("expected empty list after cancel, got %q", resp.Content)
+	}
+}
+
+type fakeBackgroundJobs struct {
+	jobs      []background.Job
+	cancelled string
+}
+
+func (f *fakeBackgroundJobs) List(channel, chatID string) []background.Job {
+	var result []background.Job
+	for _, job := range f.jobs {
+		if job.Channel == channel && job.ChatID == chatID {
+			result = append(result, job)
+		}
+	}
+	return result
+}
+
+func (f *fakeBackgroundJobs) Cancel(channel, chatID, jobID string) error {
+	for _, job := range f.List(channel, chatID) {
+		if job.ID == jobID {
+			f.cancelled = jobID
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", background.ErrJobNotFound, jobID)
+}
+
+func TestTasksCommandIncludesBackgroundJobs(t *testing.T) {
+	scheduler := &fakeTaskScheduler{jobs: map[string][]*cron.Job{
+		"alice": {{ID: "job-1", Name: "standup", NextRun: time.Now().Add(time.Hour)}},
+	}}
+	jobs := &fakeBackgroundJobs{jobs: []background.Job{
+		{ID: "bg-1a2b3c4d", Kind: background.KindCommand, Label: "build", Channel: "telegram", ChatID: "42", State: tasks.StateRunning, CreatedAt: time.Now()},
+		{ID: "bg-99999999", Kind: background.KindCommand, Label: "other", Channel: "telegram", ChatID: "7", State: tasks.StateRunning, CreatedAt: time.Now()},
+	}}
+	handler := tasksHandler(scheduler, jobs)
+	ctx := context.Background()
+	req := CommandRequest{Channel: "telegram", ChatID: "42", UserID: "alice"}
+
+	resp, _ := handler(ctx, req)
+	if !strings.Contains(resp.Content, "job-1") || !strings.Contains(resp.Content, "bg-1a2b3c4d") || strings.Contains(resp.Content, "bg-99999999") {
+		t.Fatalf("expected reminders and this chat's background jobs, got %q", resp.Content)
+	}
+
+	req.Args = "cancel bg-1a2b3c4d"
+	resp, _ = handler(ctx, req)
+	if !strings.Contains(resp.Content, "Cancelled background job") || jobs.cancelled != "bg-1a2b3c4d" {
+		t.Fatalf("unexpected background cancel response: %q", resp.Content)
+	}
+
+	req.Args = "cancel job-1"
+	resp, _ = handler(ctx, req)
+	if !strings.Contains(resp.Content, "Cancelled task job-1") {
+		t.Fatalf("expected unknown background IDs to fall through to reminders, got %q", resp.Content)
+	}
+}
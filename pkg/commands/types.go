@@ -11,8 +11,11 @@ type Command struct {
 	Name string
 	// Description is a short description of what the command does
 	Description string
-	// Usage shows how to use the command
+	// Usage shows how to use the command; generated from Params when empty
 	Usage string
+	// Params declares the arguments. When set, the registry validates
+	// invocations and passes the values in CommandRequest.Params.
+	Params []Param
 	// Handler is the function that executes the command
 	Handler CommandHandler
 	// RequiresAuth indicates if the command requires authentication
@@ -38,6 +41,9 @@ type CommandRequest struct {
 	Command string
 	// Args are the command arguments (text after the command)
 	Args string
+	// Params are the validated arguments of commands that declare Params.
+	// Channels with native command options may set them instead of Args.
+	Params Args
 	// Metadata contains channel-specific metadata
 	Metadata map[string]string
 }
@@ -61,6 +67,11 @@ type CommandInteraction struct {
 	Type string
 	// Repo is used by skill-install confirmation flows.
 	Repo string
+	// Version, Changelog and Permissions describe the indexed release a
+	// skill-install confirmation would install.
+	Version     string
+	Changelog   string
+	Permissions []string
 	// Reason is optional explanation text.
 	Reason string
 	// Message is a user-facing prompt.
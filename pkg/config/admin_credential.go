@@ -11,13 +11,14 @@ import (
 
 	"golang.org/x/crypto/bcrypt"
 
+	"nekobot/pkg/ownership"
 	"nekobot/pkg/storage/ent"
 	"nekobot/pkg/storage/ent/user"
 )
 
 const (
 	adminCredSection  = "webui_auth"
-	defaultTenantSlug = "default"
+	defaultTenantSlug = ownership.DefaultTenantSlug
 )
 
 var (
@@ -65,6 +65,34 @@ func ensureDefaultTenantTx(ctx context.Context, tx *ent.Tx) (string, error) {
 	return rec.ID, nil
 }
 
+// ensureTenantTx returns the ID of the tenant with slug, creating it if
+// needed. An empty slug is the default tenant.
+func ensureTenantTx(ctx context.Context, tx *ent.Tx, slug string) (string, error) {
+	slug = strings.TrimSpace(slug)
+	if slug == "" || slug == defaultTenantSlug {
+		return ensureDefaultTenantTx(ctx, tx)
+	}
+	rec, err := tx.Tenant.Query().Where(tenant.SlugEQ(slug)).Only(ctx)
+	if err == nil {
+		if !rec.Enabled {
+			return "", fmt.Errorf("tenant %s is disabled", slug)
+		}
+		return rec.ID, nil
+	}
+	if !ent.IsNotFound(err) {
+		return "", fmt.Errorf("query tenant %s: %w", slug, err)
+	}
+	rec, err = tx.Tenant.Create().
+		SetSlug(slug).
+		SetName(slug).
+		SetEnabled(true).
+		Save(ctx)
+	if err != nil {
+		return "", fmt.Errorf("create tenant %s: %w", slug, err)
+	}
+	return rec.ID, nil
+}
+
 func upsertAdminUserTx(ctx context.Context, tx *ent.Tx, username, nickname, passwordHash string) (*ent.User, error) {
 	rec, err := tx.User.Query().Where(user.UsernameEQ(username)).Only(ctx)
 	if err == nil {
@@ -2,42 +2,59 @@
 // It uses Viper for flexible configuration loading with support for:
 // - Multiple formats (JSON, YAML, TOML)
 // - Environment variables
-// - Hot-reload
+// - Hot-reload (Loader.Watch)
 // - Default values
 package config
 
 import (
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
+
+	"nekobot/pkg/secrets"
 )
 
 // Config represents the complete nanobot configuration.
 type Config struct {
-	Logger        LoggerConfig        `mapstructure:"logger" json:"logger"`
-	Storage       StorageConfig       `mapstructure:"storage" json:"storage"`
-	Agents        AgentsConfig        `mapstructure:"agents" json:"agents"`
-	Channels      ChannelsConfig      `mapstructure:"channels" json:"channels"`
-	Providers     ProvidersConfig     `mapstructure:"providers" json:"providers"`
-	Transcription TranscriptionConfig `mapstructure:"transcription" json:"transcription"`
-	Gateway       GatewayConfig       `mapstructure:"gateway" json:"gateway"`
-	Tools         ToolsConfig         `mapstructure:"tools" json:"tools"`
-	Heartbeat     HeartbeatConfig     `mapstructure:"heartbeat" json:"heartbeat"`
-	Webhook       WebhookConfig       `mapstructure:"webhook" json:"webhook"`
-	Redis         RedisConfig         `mapstructure:"redis" json:"redis"`
-	State         StateConfig         `mapstructure:"state" json:"state"`
-	Bus           BusConfig           `mapstructure:"bus" json:"bus"`
-	Memory        MemoryConfig        `mapstructure:"memory" json:"memory"`
-	Sessions      SessionsConfig      `mapstructure:"sessions" json:"sessions"`
-	Approval      ApprovalConfig      `mapstructure:"approval" json:"approval"`
-	WebUI         WebUIConfig         `mapstructure:"webui" json:"webui"`
-	Audit         AuditConfig         `mapstructure:"audit" json:"audit"`
-	Undo          UndoConfig          `mapstructure:"undo" json:"undo"`
-	Preprocess    PreprocessConfig    `mapstructure:"preprocess" json:"preprocess"`
-	Learnings     LearningsConfig     `mapstructure:"learnings" json:"learnings"`
-	Watch         WatchConfig         `mapstructure:"watch" json:"watch"`
-	mu            sync.RWMutex
+	Logger          LoggerConfig          `mapstructure:"logger" json:"logger"`
+	Storage         StorageConfig         `mapstructure:"storage" json:"storage"`
+	Agents          AgentsConfig          `mapstructure:"agents" json:"agents"`
+	Channels        ChannelsConfig        `mapstructure:"channels" json:"channels"`
+	Providers       ProvidersConfig       `mapstructure:"providers" json:"providers"`
+	Transcription   TranscriptionConfig   `mapstructure:"transcription" json:"transcription"`
+	TTS             TTSConfig             `mapstructure:"tts" json:"tts"`
+	Gateway         GatewayConfig         `mapstructure:"gateway" json:"gateway"`
+	Tools           ToolsConfig           `mapstructure:"tools" json:"tools"`
+	Heartbeat       HeartbeatConfig       `mapstructure:"heartbeat" json:"heartbeat"`
+	Webhook         WebhookConfig         `mapstructure:"webhook" json:"webhook"`
+	Redis           RedisConfig           `mapstructure:"redis" json:"redis"`
+	State           StateConfig           `mapstructure:"state" json:"state"`
+	Bus             BusConfig             `mapstructure:"bus" json:"bus"`
+	Memory          MemoryConfig          `mapstructure:"memory" json:"memory"`
+	Sessions        SessionsConfig        `mapstructure:"sessions" json:"sessions"`
+	Approval        ApprovalConfig        `mapstructure:"approval" json:"approval"`
+	WebUI           WebUIConfig           `mapstructure:"webui" json:"webui"`
+	Audit           AuditConfig           `mapstructure:"audit" json:"audit"`
+	Undo            UndoConfig            `mapstructure:"undo" json:"undo"`
+	Preprocess      PreprocessConfig      `mapstructure:"preprocess" json:"preprocess"`
+	Learnings       LearningsConfig       `mapstructure:"learnings" json:"learnings"`
+	Watch           WatchConfig           `mapstructure:"watch" json:"watch"`
+	MOTD            MOTDConfig            `mapstructure:"motd" json:"motd"`
+	Alerts          AlertsConfig          `mapstructure:"alerts" json:"alerts"`
+	Moderation      ModerationConfig      `mapstructure:"moderation" json:"moderation"`
+	Usage           UsageConfig           `mapstructure:"usage" json:"usage"`
+	ResponseFilters ResponseFiltersConfig `mapstructure:"response_filters" json:"response_filters"`
+	TurnLimits      TurnLimitsConfig      `mapstructure:"turn_limits" json:"turn_limits"`
+	Backup          BackupConfig          `mapstructure:"backup" json:"backup"`
+	ToolSessions    ToolSessionsConfig    `mapstructure:"tool_sessions" json:"tool_sessions"`
+	mu              sync.RWMutex
+
+	secretsOnce sync.Once
+	secretBox   *secrets.Box
+	secretsErr  error
 }
 
 const (
@@ -49,6 +66,9 @@ const (
 	DBTypeEnv = "NEKOBOT_DB_TYPE"
 	// DBDSNEnv overrides runtime database DSN at runtime.
 	DBDSNEnv = "NEKOBOT_DB_DSN"
+	// BusInstanceIDEnv names this process on the redis_streams bus; the
+	// default combines the hostname and the process role.
+	BusInstanceIDEnv = "NEKOBOT_BUS_INSTANCE_ID"
 )
 
 // StorageConfig holds local runtime storage paths.
@@ -56,31 +76,99 @@ type StorageConfig struct {
 	DBDir  string `mapstructure:"db_dir" json:"db_dir"`
 	DBType string `mapstructure:"db_type" json:"db_type"`
 	DBDSN  string `mapstructure:"db_dsn" json:"db_dsn"`
+	// Connection pool limits of the runtime database; 0 keeps the
+	// database/sql default.
+	MaxOpenConns           int `mapstructure:"max_open_conns" json:"max_open_conns,omitempty"`
+	MaxIdleConns           int `mapstructure:"max_idle_conns" json:"max_idle_conns,omitempty"`
+	ConnMaxLifetimeSeconds int `mapstructure:"conn_max_lifetime_seconds" json:"conn_max_lifetime_seconds,omitempty"`
+	ConnMaxIdleTimeSeconds int `mapstructure:"conn_max_idle_time_seconds" json:"conn_max_idle_time_seconds,omitempty"`
+	// AutoMigrate applies schema changes when the database is opened. Turn
+	// it off when several replicas share one database and run
+	// "nekobot db migrate" once per upgrade instead.
+	AutoMigrate bool `mapstructure:"auto_migrate" json:"auto_migrate"`
+	// MasterKeyFile and MasterKeyCommand supply the key that encrypts
+	// secrets at rest when NEKOBOT_MASTER_KEY is unset. The command prints
+	// the key, so a KMS client can unwrap it.
+	MasterKeyFile    string `mapstructure:"master_key_file" json:"master_key_file,omitempty"`
+	MasterKeyCommand string `mapstructure:"master_key_command" json:"master_key_command,omitempty"`
 }
 
 // AgentsConfig contains agent-related configuration.
 type AgentsConfig struct {
 	Defaults AgentDefaults `mapstructure:"defaults" json:"defaults"`
+	// Profiles are named agents that override the defaults for the channels
+	// they serve or for chats that pick them with /agent use <name>.
+	Profiles []AgentProfileConfig `mapstructure:"profiles" json:"profiles"`
+	// TenantRouting keeps the chat routing picked by users of tenants other
+	// than the default one, keyed by tenant slug. The default tenant uses
+	// Defaults.
+	TenantRouting map[string]ChatRoutingConfig `mapstructure:"tenant_routing" json:"tenant_routing,omitempty"`
+}
+
+// ChatRoutingConfig is the provider, model and fallback chain of a tenant's
+// chats.
+type ChatRoutingConfig struct {
+	Provider string   `mapstructure:"provider" json:"provider"`
+	Model    string   `mapstructure:"model" json:"model"`
+	Fallback []string `mapstructure:"fallback" json:"fallback"`
+}
+
+// AgentProfileConfig defines a named agent. Empty fields inherit the defaults.
+type AgentProfileConfig struct {
+	Name        string `mapstructure:"name" json:"name"`
+	Description string `mapstructure:"description" json:"description"`
+	Provider    string `mapstructure:"provider" json:"provider"`
+	Model       string `mapstructure:"model" json:"model"`
+	// Tools limits which tools the profile may call. Entries are tool names or
+	// path.Match globs; empty allows every tool.
+	Tools []string `mapstructure:"tools" json:"tools"`
+	// SystemPrompt is placed ahead of the default system prompt.
+	SystemPrompt string `mapstructure:"system_prompt" json:"system_prompt"`
+	// Workspace replaces the default workspace for file and exec tools.
+	Workspace string `mapstructure:"workspace" json:"workspace"`
+	// Channels lists the channels (e.g. "telegram") that use this profile
+	// unless a chat picks another one.
+	Channels []string `mapstructure:"channels" json:"channels"`
 }
 
 // AgentDefaults defines default settings for agents.
 type AgentDefaults struct {
-	Workspace           string                `mapstructure:"workspace" json:"workspace"`
-	RestrictToWorkspace bool                  `mapstructure:"restrict_to_workspace" json:"restrict_to_workspace"`
-	Provider            string                `mapstructure:"provider" json:"provider"`
-	Fallback            []string              `mapstructure:"fallback" json:"fallback"`
-	ProviderGroups      []ProviderGroupConfig `mapstructure:"provider_groups" json:"provider_groups"`
-	Orchestrator        string                `mapstructure:"orchestrator" json:"orchestrator"`
-	Model               string                `mapstructure:"model" json:"model"`
-	MaxTokens           int                   `mapstructure:"max_tokens" json:"max_tokens"`
-	Temperature         float64               `mapstructure:"temperature" json:"temperature"`
-	MaxToolIterations   int                   `mapstructure:"max_tool_iterations" json:"max_tool_iterations"`
-	SkillsDir           string                `mapstructure:"skills_dir" json:"skills_dir"`
-	SkillsAutoReload    bool                  `mapstructure:"skills_auto_reload" json:"skills_auto_reload"`
-	SkillsProxy         string                `mapstructure:"skills_proxy" json:"skills_proxy"`
-	ExtendedThinking    bool                  `mapstructure:"extended_thinking" json:"extended_thinking"`
-	ThinkingBudget      int                   `mapstructure:"thinking_budget" json:"thinking_budget"`
-	MCPServers          []MCPServerConfig     `mapstructure:"mcp_servers" json:"mcp_servers"`
+	Workspace               string                 `mapstructure:"workspace" json:"workspace"`
+	RestrictToWorkspace     bool                   `mapstructure:"restrict_to_workspace" json:"restrict_to_workspace"`
+	Provider                string                 `mapstructure:"provider" json:"provider"`
+	Fallback                []string               `mapstructure:"fallback" json:"fallback"`
+	MaxFallbackAttempts     int                    `mapstructure:"max_fallback_attempts" json:"max_fallback_attempts"` // 0 tries the full chain
+	ProviderGroups          []ProviderGroupConfig  `mapstructure:"provider_groups" json:"provider_groups"`
+	RoutingPolicy           string                 `mapstructure:"routing_policy" json:"routing_policy"`                       // ordered (default) or latency: fastest healthy provider first
+	CircuitBreakerThreshold int                    `mapstructure:"circuit_breaker_threshold" json:"circuit_breaker_threshold"` // Consecutive failures that open a provider's circuit; 0 means 1
+	Orchestrator            string                 `mapstructure:"orchestrator" json:"orchestrator"`
+	Model                   string                 `mapstructure:"model" json:"model"`
+	MaxTokens               int                    `mapstructure:"max_tokens" json:"max_tokens"`
+	Temperature             float64                `mapstructure:"temperature" json:"temperature"`
+	MaxToolIterations       int                    `mapstructure:"max_tool_iterations" json:"max_tool_iterations"`
+	SkillsDir               string                 `mapstructure:"skills_dir" json:"skills_dir"`
+	SkillsAutoReload        bool                   `mapstructure:"skills_auto_reload" json:"skills_auto_reload"`
+	SkillsProxy             string                 `mapstructure:"skills_proxy" json:"skills_proxy"`
+	SkillsIndexURL          string                 `mapstructure:"skills_index_url" json:"skills_index_url"`   // Curated skills index feed (URL or file), empty disables it
+	SkillsIndexOnly         bool                   `mapstructure:"skills_index_only" json:"skills_index_only"` // Chat installs only accept skills listed in the index
+	ExtendedThinking        bool                   `mapstructure:"extended_thinking" json:"extended_thinking"`
+	ThinkingBudget          int                    `mapstructure:"thinking_budget" json:"thinking_budget"`
+	PromptCaching           bool                   `mapstructure:"prompt_caching" json:"prompt_caching"` // Cache the system prompt and tools on providers that support it
+	MCPServers              []MCPServerConfig      `mapstructure:"mcp_servers" json:"mcp_servers"`
+	WorkspaceBackend        WorkspaceBackendConfig `mapstructure:"workspace_backend" json:"workspace_backend"`
+}
+
+// WorkspaceBackendConfig keeps parts of the workspace in object storage so
+// stateless deployments keep memory across redeploys. The local workspace
+// acts as a cache: Paths are pulled from the backend on start and changes
+// are pushed back every SyncIntervalSeconds and on shutdown.
+type WorkspaceBackendConfig struct {
+	Type string   `mapstructure:"type" json:"type"` // local (default) or s3
+	S3   S3Config `mapstructure:"s3" json:"s3"`
+	// Paths are workspace-relative files or directories kept in the
+	// backend. Default: MEMORY.md and memory/, which holds session exports.
+	Paths               []string `mapstructure:"paths" json:"paths"`
+	SyncIntervalSeconds int      `mapstructure:"sync_interval_seconds" json:"sync_interval_seconds"`
 }
 
 // ProviderGroupConfig defines a logical provider pool with a selection strategy.
@@ -101,6 +189,11 @@ type MCPServerConfig struct {
 	Endpoint  string            `mapstructure:"endpoint" json:"endpoint"`
 	Headers   map[string]string `mapstructure:"headers" json:"headers"`
 	Timeout   string            `mapstructure:"timeout" json:"timeout"`
+	// AllowedTools limits which of the server's tools are exposed to the model.
+	// Entries are tool names or path.Match globs; empty exposes every tool.
+	AllowedTools []string `mapstructure:"allowed_tools" json:"allowed_tools,omitempty"`
+	// BlockedTools hides matching tools even when AllowedTools matches them.
+	BlockedTools []string `mapstructure:"blocked_tools" json:"blocked_tools,omitempty"`
 }
 
 // ChannelsConfig contains all channel configurations.
@@ -121,6 +214,8 @@ type ChannelsConfig struct {
 	GoogleChat     GoogleChatConfig `mapstructure:"googlechat" json:"googlechat"`
 	Teams          TeamsConfig      `mapstructure:"teams" json:"teams"`
 	Infoflow       InfoflowConfig   `mapstructure:"infoflow" json:"infoflow"`
+	Matrix         MatrixConfig     `mapstructure:"matrix" json:"matrix"`
+	Signal         SignalConfig     `mapstructure:"signal" json:"signal"`
 }
 
 // GotifyConfig for Gotify push channel.
@@ -152,6 +247,22 @@ type TelegramConfig struct {
 	Proxy          string   `mapstructure:"proxy" json:"proxy"`
 	TimeoutSeconds int      `mapstructure:"timeout_seconds" json:"timeout_seconds"`
 	AllowFrom      []string `mapstructure:"allow_from" json:"allow_from"`
+	// RerunEditedMessages re-runs the turn when a user edits a message and
+	// edits the earlier bot reply in place. Edits are ignored when false.
+	RerunEditedMessages bool `mapstructure:"rerun_edited_messages" json:"rerun_edited_messages"`
+	// SessionScope controls how group chats map to sessions: "chat" (default)
+	// shares one session per group, "user" gives each member their own.
+	// Private chats always use one session per chat.
+	SessionScope string `mapstructure:"session_scope" json:"session_scope"`
+	// GroupMode controls which group messages get a reply: "all" (default)
+	// answers every message, "mention" only messages that @mention the bot
+	// or reply to it. Commands are always handled.
+	GroupMode string `mapstructure:"group_mode" json:"group_mode"`
+	// TopicSessions gives each forum topic of a group its own session.
+	TopicSessions bool `mapstructure:"topic_sessions" json:"topic_sessions"`
+	// StreamReplies edits the "thinking" message with the reply while it is
+	// generated instead of sending the reply once it is complete.
+	StreamReplies bool `mapstructure:"stream_replies" json:"stream_replies"`
 }
 
 // FeishuConfig for Feishu (Lark) channel.
@@ -169,6 +280,13 @@ type DiscordConfig struct {
 	Enabled   bool     `mapstructure:"enabled" json:"enabled"`
 	Token     string   `mapstructure:"token" json:"token"`
 	AllowFrom []string `mapstructure:"allow_from" json:"allow_from"`
+	// SessionScope controls how guild channels and threads map to sessions:
+	// "chat" (default) shares one session per channel or thread, "user"
+	// gives each member their own.
+	SessionScope string `mapstructure:"session_scope" json:"session_scope"`
+	// GroupMode controls which guild messages get a reply: "all" (default)
+	// or "mention" for messages that @mention the bot or reply to it.
+	GroupMode string `mapstructure:"group_mode" json:"group_mode"`
 }
 
 // MaixCamConfig for MaixCAM channel.
@@ -201,6 +319,14 @@ type SlackConfig struct {
 	BotToken  string   `mapstructure:"bot_token" json:"bot_token"`
 	AppToken  string   `mapstructure:"app_token" json:"app_token"`
 	AllowFrom []string `mapstructure:"allow_from" json:"allow_from"`
+	// SessionScope controls how channels and threads map to sessions:
+	// "chat" (default) shares one session per channel or thread, "user"
+	// gives each member their own.
+	SessionScope string `mapstructure:"session_scope" json:"session_scope"`
+	// GroupMode controls which channel messages get a reply: "all"
+	// (default) or "mention" for messages that @mention the bot and
+	// replies in threads the bot takes part in.
+	GroupMode string `mapstructure:"group_mode" json:"group_mode"`
 }
 
 // ServerChanConfig for ServerChan Bot channel.
@@ -245,6 +371,25 @@ type InfoflowConfig struct {
 	AllowFrom  []string `mapstructure:"allow_from" json:"allow_from"`
 }
 
+// MatrixConfig for Matrix channel.
+type MatrixConfig struct {
+	Enabled     bool   `mapstructure:"enabled" json:"enabled"`
+	Homeserver  string `mapstructure:"homeserver" json:"homeserver"` // e.g. "https://matrix.example.org"
+	UserID      string `mapstructure:"user_id" json:"user_id"`       // Bot account, e.g. "@nekobot:example.org"
+	AccessToken string `mapstructure:"access_token" json:"access_token"`
+	// AutoJoin accepts room invites from users allowed by AllowFrom.
+	AutoJoin  bool     `mapstructure:"auto_join" json:"auto_join"`
+	AllowFrom []string `mapstructure:"allow_from" json:"allow_from"`
+}
+
+// SignalConfig for Signal channel via signal-cli-rest-api.
+type SignalConfig struct {
+	Enabled   bool     `mapstructure:"enabled" json:"enabled"`
+	APIURL    string   `mapstructure:"api_url" json:"api_url"` // signal-cli-rest-api base URL
+	Number    string   `mapstructure:"number" json:"number"`   // Registered bot number, e.g. "+15551234567"
+	AllowFrom []string `mapstructure:"allow_from" json:"allow_from"`
+}
+
 // HeartbeatConfig for periodic autonomous tasks.
 type HeartbeatConfig struct {
 	Enabled         bool `mapstructure:"enabled" json:"enabled"`
@@ -255,6 +400,18 @@ type HeartbeatConfig struct {
 type WebhookConfig struct {
 	Enabled bool   `mapstructure:"enabled" json:"enabled"`
 	Path    string `mapstructure:"path" json:"path"`
+	// Hooks are named ingestion endpoints served by the gateway at
+	// POST /hooks/<name>, independent of Enabled.
+	Hooks []WebhookHookConfig `mapstructure:"hooks" json:"hooks"`
+}
+
+// WebhookHookConfig maps the JSON payload of one named hook into an agent prompt.
+type WebhookHookConfig struct {
+	Name      string `mapstructure:"name" json:"name"`
+	Secret    string `mapstructure:"secret" json:"secret"`
+	Template  string `mapstructure:"template" json:"template"`     // Go text/template over the decoded payload.
+	ChannelID string `mapstructure:"channel_id" json:"channel_id"` // Channel delivering the reply, e.g. "telegram".
+	SessionID string `mapstructure:"session_id" json:"session_id"` // Conversation target; defaults to "webhook:<name>".
 }
 
 // RedisConfig is the shared Redis connection configuration.
@@ -274,8 +431,21 @@ type StateConfig struct {
 
 // BusConfig for message bus backend.
 type BusConfig struct {
-	Type   string `mapstructure:"type" json:"type"`     // "local" or "redis"
+	Type   string `mapstructure:"type" json:"type"`     // "local", "redis" or "redis_streams"
 	Prefix string `mapstructure:"prefix" json:"prefix"` // Redis key prefix (default "nekobot:bus:")
+
+	// The settings below apply to the redis_streams bus, where each inbound
+	// message goes to one worker of a consumer group and the reply returns
+	// to the gateway instance that received it.
+
+	// Workers is how many inbound messages one process handles at a time.
+	Workers int `mapstructure:"workers" json:"workers"`
+	// ProcessInbound lets a gateway run agent turns itself. Turn it off to
+	// leave them to "nekobot worker" processes.
+	ProcessInbound bool `mapstructure:"process_inbound" json:"process_inbound"`
+	// ClaimIdleSeconds hands an inbound message left unacknowledged this
+	// long, e.g. by a worker that died, to another worker.
+	ClaimIdleSeconds int `mapstructure:"claim_idle_seconds" json:"claim_idle_seconds"`
 }
 
 // ProvidersConfig contains provider configurations.
@@ -296,6 +466,44 @@ type ProviderProfile struct {
 	DefaultTestModel string   `mapstructure:"default_test_model" json:"default_test_model,omitempty"` // Default model for manual provider testing
 	APIFormat        string   `mapstructure:"api_format" json:"api_format,omitempty"`                 // Wire format: openai/chat_completions or openai/responses
 	Timeout          int      `mapstructure:"timeout" json:"timeout,omitempty"`                       // Timeout in seconds, default 30s
+	Stream           *bool    `mapstructure:"stream" json:"stream,omitempty"`                         // Allow streaming requests, default true
+	// SystemPrefix is prepended to the system prompt of every request this
+	// provider serves, e.g. "Respond concisely." for a small local model.
+	SystemPrefix string `mapstructure:"system_prefix" json:"system_prefix,omitempty"`
+	// APIVersion and Deployments configure Azure OpenAI: the api-version
+	// query parameter and the deployment serving each model alias.
+	APIVersion  string            `mapstructure:"api_version" json:"api_version,omitempty"`
+	Deployments map[string]string `mapstructure:"deployments" json:"deployments,omitempty"`
+	// Middleware configures retries, timeouts, spend limits and headers
+	// applied to every request this provider serves.
+	Middleware *ProviderMiddlewareConfig `mapstructure:"middleware" json:"middleware,omitempty"`
+	// Capabilities caches the result of the last capability probe.
+	Capabilities *ProviderCapabilities `mapstructure:"capabilities" json:"capabilities,omitempty"`
+	// TenantID is the tenant that owns the provider; empty is the default
+	// tenant.
+	TenantID string `mapstructure:"tenant_id" json:"tenant_id,omitempty"`
+}
+
+// ProviderMiddlewareConfig configures the request middleware of a provider.
+// Zero values leave the matching behavior off.
+type ProviderMiddlewareConfig struct {
+	MaxRetries     int               `mapstructure:"max_retries" json:"max_retries,omitempty"`           // Retries of transient failures
+	RetryBackoffMS int               `mapstructure:"retry_backoff_ms" json:"retry_backoff_ms,omitempty"` // Base retry delay in milliseconds, default 500
+	AttemptTimeout int               `mapstructure:"attempt_timeout" json:"attempt_timeout,omitempty"`   // Seconds per attempt (first chunk for streams)
+	MaxRequestCost float64           `mapstructure:"max_request_cost" json:"max_request_cost,omitempty"` // USD per request, priced from usage.pricing
+	Headers        map[string]string `mapstructure:"headers" json:"headers,omitempty"`                   // Extra HTTP headers
+	LogRequests    bool              `mapstructure:"log_requests" json:"log_requests,omitempty"`         // Log every attempt with latency and usage
+}
+
+// ProviderCapabilities records which request features a provider's model
+// accepted when probed. A nil feature is unknown and never restricts requests.
+type ProviderCapabilities struct {
+	Model       string `mapstructure:"model" json:"model"`
+	ToolCalling *bool  `mapstructure:"tool_calling" json:"tool_calling,omitempty"`
+	Streaming   *bool  `mapstructure:"streaming" json:"streaming,omitempty"`
+	Vision      *bool  `mapstructure:"vision" json:"vision,omitempty"`
+	JSONMode    *bool  `mapstructure:"json_mode" json:"json_mode,omitempty"`
+	ProbedAt    int64  `mapstructure:"probed_at" json:"probed_at"` // Unix seconds
 }
 
 // LoggerConfig contains logger configuration.
@@ -326,18 +534,140 @@ type TranscriptionConfig struct {
 	APIBase        string `mapstructure:"api_base" json:"api_base"`
 	Model          string `mapstructure:"model" json:"model"`
 	TimeoutSeconds int    `mapstructure:"timeout_seconds" json:"timeout_seconds"`
+	// ConvertOnFailure re-encodes the audio with ffmpeg and retries once when
+	// the provider rejects it; ignored when ffmpeg is not installed.
+	ConvertOnFailure bool   `mapstructure:"convert_on_failure" json:"convert_on_failure"`
+	ConvertFormat    string `mapstructure:"convert_format" json:"convert_format"` // "wav" or "mp3"
+	// Backend picks the speech-to-text service: "whisper" (default, any
+	// OpenAI-compatible endpoint), "whisper-local", "deepgram" or "google".
+	Backend string `mapstructure:"backend" json:"backend"`
+	// Language is the hint used when the user has no profile language;
+	// empty lets the backend detect it.
+	Language string `mapstructure:"language" json:"language"`
+	// Channels overrides the backend per channel type (e.g. "telegram").
+	Channels map[string]TranscriptionChannelConfig `mapstructure:"channels" json:"channels,omitempty"`
+}
+
+// TranscriptionChannelConfig overrides transcription settings for one channel
+// type. Switching backend drops the top-level api_key, api_base and model.
+type TranscriptionChannelConfig struct {
+	Backend  string `mapstructure:"backend" json:"backend,omitempty"`
+	APIKey   string `mapstructure:"api_key" json:"api_key,omitempty"`
+	APIBase  string `mapstructure:"api_base" json:"api_base,omitempty"`
+	Model    string `mapstructure:"model" json:"model,omitempty"`
+	Language string `mapstructure:"language" json:"language,omitempty"`
+}
+
+// TTSConfig controls text-to-speech voice replies.
+type TTSConfig struct {
+	Enabled        bool   `mapstructure:"enabled" json:"enabled"`
+	Backend        string `mapstructure:"backend" json:"backend"` // "openai", "elevenlabs" or "edge"
+	Provider       string `mapstructure:"provider" json:"provider"`
+	APIKey         string `mapstructure:"api_key" json:"api_key"`
+	APIBase        string `mapstructure:"api_base" json:"api_base"`
+	Model          string `mapstructure:"model" json:"model"` // Empty uses the backend default
+	Voice          string `mapstructure:"voice" json:"voice"` // Empty uses the backend default
+	TimeoutSeconds int    `mapstructure:"timeout_seconds" json:"timeout_seconds"`
+	// MaxChars skips the voice note for replies longer than this, 0 disables.
+	MaxChars int `mapstructure:"max_chars" json:"max_chars"`
 }
 
 // ToolsConfig contains tool-related configuration.
 type ToolsConfig struct {
-	Web  WebToolsConfig  `mapstructure:"web" json:"web"`
-	Exec ExecToolsConfig `mapstructure:"exec" json:"exec"`
+	Web            WebToolsConfig         `mapstructure:"web" json:"web"`
+	Exec           ExecToolsConfig        `mapstructure:"exec" json:"exec"`
+	TimeoutSeconds int                    `mapstructure:"timeout_seconds" json:"timeout_seconds"` // Default per-call timeout for every tool, 0 disables
+	Timeouts       map[string]int         `mapstructure:"timeouts" json:"timeouts"`               // Per-tool overrides keyed by tool name; "mcp" covers all MCP tools
+	PromptBudget   ToolPromptBudgetConfig `mapstructure:"prompt_budget" json:"prompt_budget"`
+	SpawnAgent     SpawnAgentToolConfig   `mapstructure:"spawn_agent" json:"spawn_agent"`
+	Databases      []DatabaseToolConfig   `mapstructure:"databases" json:"databases"`
+	Background     BackgroundToolConfig   `mapstructure:"background" json:"background"`
+	Kubernetes     KubernetesToolConfig   `mapstructure:"kubernetes" json:"kubernetes"`
+	Files          FilePolicyConfig       `mapstructure:"files" json:"files"`
+	PluginsDir     string                 `mapstructure:"plugins_dir" json:"plugins_dir"` // Directory of tool plugins: executables and WASM tool directories, empty disables plugins
+}
+
+// FilePolicyConfig is enforced on every read_file, write_file, edit_file,
+// append_file and list_dir call.
+type FilePolicyConfig struct {
+	Allow                []string                       `mapstructure:"allow" json:"allow"`                                     // Glob patterns paths must match, empty allows all
+	Deny                 []string                       `mapstructure:"deny" json:"deny"`                                       // Glob patterns that are always refused
+	Agents               map[string]FilePathRulesConfig `mapstructure:"agents" json:"agents"`                                   // Extra rules keyed by agent profile name
+	MaxFileBytes         int64                          `mapstructure:"max_file_bytes" json:"max_file_bytes"`                   // Largest file read or written, 0 disables
+	MaxSessionWriteBytes int64                          `mapstructure:"max_session_write_bytes" json:"max_session_write_bytes"` // Total bytes one session may write, 0 disables
+	RedactSecrets        bool                           `mapstructure:"redact_secrets" json:"redact_secrets"`                   // Redact .env values and key files on read
+	SecretPatterns       []string                       `mapstructure:"secret_patterns" json:"secret_patterns"`                 // File name patterns added to the built-in secret list
+}
+
+// FilePathRulesConfig adds file rules for one agent profile. Deny patterns
+// add to the global ones; allow patterns, when set, replace them.
+type FilePathRulesConfig struct {
+	Allow []string `mapstructure:"allow" json:"allow"`
+	Deny  []string `mapstructure:"deny" json:"deny"`
+}
+
+// KubernetesToolConfig enables the k8s_* tools against one cluster.
+type KubernetesToolConfig struct {
+	Enabled        bool     `mapstructure:"enabled" json:"enabled"`
+	Kubeconfig     string   `mapstructure:"kubeconfig" json:"kubeconfig"`           // Empty uses $KUBECONFIG, ~/.kube/config or the in-cluster account
+	Context        string   `mapstructure:"context" json:"context"`                 // Empty uses the kubeconfig's current context
+	Namespace      string   `mapstructure:"namespace" json:"namespace"`             // Default namespace, empty uses the context's
+	Namespaces     []string `mapstructure:"namespaces" json:"namespaces"`           // Namespaces the tools may access, empty allows all
+	AllowApply     bool     `mapstructure:"allow_apply" json:"allow_apply"`         // Register k8s_apply
+	TimeoutSeconds int      `mapstructure:"timeout_seconds" json:"timeout_seconds"` // Per-request timeout, 0 uses the default of 30
+}
+
+// KubeconfigPath returns the kubeconfig path with ~ expanded.
+func (k KubernetesToolConfig) KubeconfigPath() string {
+	return expandPath(strings.TrimSpace(k.Kubeconfig))
+}
+
+// BackgroundToolConfig bounds the run_background tool, which starts long
+// commands and reports back to the originating chat.
+type BackgroundToolConfig struct {
+	MaxRunning              int `mapstructure:"max_running" json:"max_running"`                             // Concurrent command jobs
+	ProgressIntervalSeconds int `mapstructure:"progress_interval_seconds" json:"progress_interval_seconds"` // Progress update interval while output changes, 0 disables
+}
+
+// DatabaseToolConfig is a named connection the sql_query tool can query.
+type DatabaseToolConfig struct {
+	Name           string `mapstructure:"name" json:"name"`
+	Type           string `mapstructure:"type" json:"type"` // "postgres", "mysql" or "sqlite"
+	DSN            string `mapstructure:"dsn" json:"dsn"`
+	ReadOnly       bool   `mapstructure:"read_only" json:"read_only"`             // Only allow single read statements in a read-only transaction
+	MaxRows        int    `mapstructure:"max_rows" json:"max_rows"`               // Rows returned per query, 0 uses the default of 200
+	TimeoutSeconds int    `mapstructure:"timeout_seconds" json:"timeout_seconds"` // Per-query timeout, 0 uses the default of 30
 }
 
+// NormalizedType returns the canonical database type (postgres, mysql or sqlite).
+func (d DatabaseToolConfig) NormalizedType() string {
+	return normalizeDatabaseType(d.Type)
+}
+
+// SpawnAgentToolConfig bounds the spawn_agent tool, which delegates a task to
+// a child agent and waits for its answer.
+type SpawnAgentToolConfig struct {
+	Enabled       bool `mapstructure:"enabled" json:"enabled"`
+	MaxDepth      int  `mapstructure:"max_depth" json:"max_depth"`           // Nesting limit; 1 lets the main agent delegate but not its children
+	MaxIterations int  `mapstructure:"max_iterations" json:"max_iterations"` // Default and upper bound for a child's tool iterations
+	MaxTokens     int  `mapstructure:"max_tokens" json:"max_tokens"`         // Token budget per child including its own children, 0 disables the cap
+}
+
+// ToolPromptBudgetConfig bounds the tool list rendered into the system prompt.
+type ToolPromptBudgetConfig struct {
+	MaxTokens int      `mapstructure:"max_tokens" json:"max_tokens"` // Estimated token budget for the tools section, 0 disables trimming
+	Strategy  string   `mapstructure:"strategy" json:"strategy"`     // "priority" or "recent"
+	Priority  []string `mapstructure:"priority" json:"priority"`     // Tool names kept first when trimming
+}
+
+// MCPToolTimeoutKey is the ToolsConfig.Timeouts key applied to MCP tools without an explicit entry.
+const MCPToolTimeoutKey = "mcp"
+
 // WebToolsConfig for web-related tools.
 type WebToolsConfig struct {
-	Search WebSearchConfig `mapstructure:"search" json:"search"`
-	Fetch  WebFetchConfig  `mapstructure:"fetch" json:"fetch"`
+	Search      WebSearchConfig `mapstructure:"search" json:"search"`
+	Fetch       WebFetchConfig  `mapstructure:"fetch" json:"fetch"`
+	CiteSources bool            `mapstructure:"cite_sources" json:"cite_sources"` // Append a "Sources" list of consulted URLs to replies
 }
 
 // WebSearchConfig for web search tool.
@@ -368,6 +698,19 @@ type DockerSandboxConfig struct {
 	Mounts      []string `mapstructure:"mounts" json:"mounts"`
 	Timeout     int      `mapstructure:"timeout" json:"timeout"`
 	AutoCleanup bool     `mapstructure:"auto_cleanup" json:"auto_cleanup"`
+	// Runtime selects the isolation backend: "docker" (default runc),
+	// "gvisor" (runsc) or "firecracker" (Kata Containers microVMs).
+	Runtime    string  `mapstructure:"runtime" json:"runtime"`
+	OCIRuntime string  `mapstructure:"oci_runtime" json:"oci_runtime"` // Overrides the runtime name registered with Docker
+	CPUs       float64 `mapstructure:"cpus" json:"cpus"`               // CPU limit per container, 0 leaves it unlimited
+	MemoryMB   int     `mapstructure:"memory_mb" json:"memory_mb"`     // Memory limit per container, 0 leaves it unlimited
+	PidsLimit  int64   `mapstructure:"pids_limit" json:"pids_limit"`   // Process limit per container, 0 leaves it unlimited
+	// PerSession keeps one container per session, so installed packages
+	// and files outside the workspace survive across exec calls.
+	PerSession         bool `mapstructure:"per_session" json:"per_session"`
+	PoolSize           int  `mapstructure:"pool_size" json:"pool_size"`                       // Warm containers kept ready for new sessions
+	MaxContainers      int  `mapstructure:"max_containers" json:"max_containers"`             // Warm and session containers together
+	IdleTimeoutSeconds int  `mapstructure:"idle_timeout_seconds" json:"idle_timeout_seconds"` // Session containers idle this long are removed
 }
 
 // DefaultConfig returns a new Config with default values.
@@ -385,9 +728,10 @@ func DefaultConfig() *Config {
 			Compress:   true,
 		},
 		Storage: StorageConfig{
-			DBDir:  "", // Empty means executable directory.
-			DBType: "sqlite",
-			DBDSN:  "",
+			DBDir:       "", // Empty means executable directory.
+			DBType:      "sqlite",
+			DBDSN:       "",
+			AutoMigrate: true,
 		},
 		Agents: AgentsConfig{
 			Defaults: AgentDefaults{
@@ -400,7 +744,13 @@ func DefaultConfig() *Config {
 				MaxTokens:           8192,
 				Temperature:         0.7,
 				MaxToolIterations:   20,
+				PromptCaching:       true,
 				MCPServers:          []MCPServerConfig{},
+				WorkspaceBackend: WorkspaceBackendConfig{
+					Type:                "local",
+					Paths:               []string{"MEMORY.md", "memory"},
+					SyncIntervalSeconds: 30,
+				},
 			},
 		},
 		Channels: ChannelsConfig{
@@ -419,6 +769,8 @@ func DefaultConfig() *Config {
 				Enabled:        false,
 				TimeoutSeconds: 60,
 				AllowFrom:      []string{},
+				SessionScope:   "chat",
+				GroupMode:      "all",
 			},
 			Gotify: GotifyConfig{
 				Enabled:   false,
@@ -430,8 +782,10 @@ func DefaultConfig() *Config {
 				AllowFrom: []string{},
 			},
 			Discord: DiscordConfig{
-				Enabled:   false,
-				AllowFrom: []string{},
+				Enabled:      false,
+				AllowFrom:    []string{},
+				SessionScope: "chat",
+				GroupMode:    "all",
 			},
 			MaixCam: MaixCamConfig{
 				Enabled:   false,
@@ -448,8 +802,10 @@ func DefaultConfig() *Config {
 				AllowFrom: []string{},
 			},
 			Slack: SlackConfig{
-				Enabled:   false,
-				AllowFrom: []string{},
+				Enabled:      false,
+				AllowFrom:    []string{},
+				SessionScope: "chat",
+				GroupMode:    "all",
 			},
 			ServerChan: ServerChanConfig{
 				Enabled:   false,
@@ -471,6 +827,16 @@ func DefaultConfig() *Config {
 				Enabled:   false,
 				AllowFrom: []string{},
 			},
+			Matrix: MatrixConfig{
+				Enabled:   false,
+				AutoJoin:  true,
+				AllowFrom: []string{},
+			},
+			Signal: SignalConfig{
+				Enabled:   false,
+				APIURL:    "http://localhost:8080",
+				AllowFrom: []string{},
+			},
 		},
 		Providers: []ProviderProfile{},
 		Transcription: TranscriptionConfig{
@@ -479,6 +845,15 @@ func DefaultConfig() *Config {
 			APIBase:        "https://api.groq.com/openai/v1",
 			Model:          "whisper-large-v3-turbo",
 			TimeoutSeconds: 90,
+			ConvertFormat:  "wav",
+			Backend:        "whisper",
+		},
+		TTS: TTSConfig{
+			Enabled:        false,
+			Backend:        "openai",
+			Provider:       "openai",
+			TimeoutSeconds: 60,
+			MaxChars:       2000,
 		},
 		Gateway: GatewayConfig{
 			Host:           "0.0.0.0",
@@ -487,6 +862,28 @@ func DefaultConfig() *Config {
 			AllowedOrigins: []string{},
 		},
 		Tools: ToolsConfig{
+			TimeoutSeconds: 120,
+			Timeouts:       map[string]int{},
+			PromptBudget: ToolPromptBudgetConfig{
+				MaxTokens: 0,
+				Strategy:  "recent",
+				Priority:  []string{},
+			},
+			SpawnAgent: SpawnAgentToolConfig{
+				Enabled:       true,
+				MaxDepth:      2,
+				MaxIterations: 10,
+				MaxTokens:     200000,
+			},
+			Background: BackgroundToolConfig{
+				MaxRunning:              5,
+				ProgressIntervalSeconds: 300,
+			},
+			Files: FilePolicyConfig{
+				MaxFileBytes:  10 << 20,
+				RedactSecrets: true,
+			},
+			PluginsDir: filepath.Join(homeDir, ".nekobot", "plugins"),
 			Web: WebToolsConfig{
 				Search: WebSearchConfig{
 					MaxResults:           5,
@@ -496,16 +893,21 @@ func DefaultConfig() *Config {
 				Fetch: WebFetchConfig{
 					MaxChars: 50000,
 				},
+				CiteSources: true,
 			},
 			Exec: ExecToolsConfig{
 				TimeoutSeconds: 30,
 				Sandbox: DockerSandboxConfig{
-					Enabled:     false,
-					Image:       "alpine:3.20",
-					NetworkMode: "none",
-					Mounts:      []string{},
-					Timeout:     60,
-					AutoCleanup: true,
+					Enabled:            false,
+					Image:              "alpine:3.20",
+					NetworkMode:        "none",
+					Mounts:             []string{},
+					Timeout:            60,
+					AutoCleanup:        true,
+					Runtime:            "docker",
+					PoolSize:           1,
+					MaxContainers:      10,
+					IdleTimeoutSeconds: 1800,
 				},
 			},
 		},
@@ -526,8 +928,11 @@ func DefaultConfig() *Config {
 			Prefix:   "nekobot:",
 		},
 		Bus: BusConfig{
-			Type:   "local",
-			Prefix: "nekobot:bus:",
+			Type:             "local",
+			Prefix:           "nekobot:bus:",
+			Workers:          4,
+			ProcessInbound:   true,
+			ClaimIdleSeconds: 600,
 		},
 		Memory: MemoryConfig{
 			Enabled:  true,
@@ -591,22 +996,40 @@ func DefaultConfig() *Config {
 				IntervalMinutes: 60,
 				MaxAgeDays:      30,
 			},
+			AutoTitle: SessionAutoTitleConfig{
+				Enabled:   false,
+				MaxLength: 60,
+			},
+			Export: SessionExportConfig{
+				Enabled:       false,
+				Format:        "jsonl",
+				RetentionDays: 90,
+			},
 		},
 		Approval: ApprovalConfig{
-			Mode:      "auto",
-			Allowlist: []string{},
-			Denylist:  []string{},
+			Mode:                 "auto",
+			Allowlist:            []string{},
+			Denylist:             []string{},
+			AlwaysPrompt:         []string{"git_commit", "k8s_apply"},
+			TTLSeconds:           3600,
+			PromptTimeoutSeconds: 300,
 		},
 		WebUI: WebUIConfig{
 			Enabled:                     true,
 			Port:                        0, // 0 means gateway port + 1
 			PublicBaseURL:               "",
 			ToolSessionRuntimeTransport: "tmux",
+			ToolSessionScrollbackBytes:  1 << 20,
 			ToolSessionOTPTTLSeconds:    180,
 			ToolSessionEvents: ToolSessionEventsConfig{
 				Enabled:       true,
 				RetentionDays: 14,
 			},
+			ToolSessionLimits: ToolSessionLimitsConfig{
+				CPUGraceSeconds: 60,
+			},
+			WebSocketCompression: true,
+			ChatMaxMessageBytes:  65536,
 			SkillSnapshots: SkillSnapshotsConfig{
 				AutoPrune: true,
 				MaxCount:  20,
@@ -647,6 +1070,48 @@ func DefaultConfig() *Config {
 			DebounceMs: 300,
 			Patterns:   []WatchPattern{},
 		},
+		MOTD: MOTDConfig{
+			Enabled: false,
+		},
+		Alerts: AlertsConfig{
+			Enabled:            false,
+			MinSeverity:        "warning",
+			DedupWindowSeconds: 600,
+			EscalateAfter:      5,
+			MaxPerHour:         30,
+			LogEvents:          true,
+		},
+		Moderation: ModerationConfig{
+			Enabled:    false,
+			Provider:   "keyword",
+			Action:     "block",
+			Categories: []string{},
+			Keywords:   []string{},
+			Patterns:   []string{},
+		},
+		Usage: UsageConfig{
+			Enabled: true,
+			Pricing: []ModelPricing{},
+		},
+		ResponseFilters: ResponseFiltersConfig{
+			Enabled: false,
+			Filters: []ResponseFilterConfig{},
+		},
+		TurnLimits: TurnLimitsConfig{
+			Enabled:             false,
+			MaxPerUser:          1,
+			Mode:                "reject",
+			QueueTimeoutSeconds: 120,
+		},
+		Backup: BackupConfig{
+			Enabled:       false,
+			IntervalHours: 24,
+			Target:        "local",
+			Keep:          7,
+		},
+		ToolSessions: ToolSessionsConfig{
+			ApprovalTimeoutSeconds: 300,
+		},
 	}
 }
 
@@ -660,6 +1125,27 @@ func (c *Config) WorkspacePath() string {
 	return expandPath(c.Agents.Defaults.Workspace)
 }
 
+// PluginsPath returns the expanded tool plugins directory, or "" when
+// plugins are disabled.
+func (c *Config) PluginsPath() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if strings.TrimSpace(c.Tools.PluginsDir) == "" {
+		return ""
+	}
+	return expandPath(strings.TrimSpace(c.Tools.PluginsDir))
+}
+
+// TenantWorkspacePath returns the workspace of a tenant. The default tenant
+// uses the workspace itself; other tenants get a directory under "tenants".
+func (c *Config) TenantWorkspacePath(slug string) string {
+	slug = strings.TrimSpace(slug)
+	if slug == "" || slug == defaultTenantSlug {
+		return c.WorkspacePath()
+	}
+	return filepath.Join(c.WorkspacePath(), "tenants", filepath.Base(slug))
+}
+
 // DatabaseDir returns the runtime SQLite directory.
 // Priority: NEKOBOT_DB_DIR > storage.db_dir > executable directory > current directory.
 func (c *Config) DatabaseDir() string {
@@ -684,6 +1170,30 @@ func (c *Config) DatabaseDir() string {
 	return "."
 }
 
+// BackupDir returns the directory of local backup archives.
+// Default: <db_dir>/backups.
+func (c *Config) BackupDir() string {
+	c.mu.RLock()
+	dir := strings.TrimSpace(c.Backup.Dir)
+	c.mu.RUnlock()
+	if dir != "" {
+		return expandPath(dir)
+	}
+	return filepath.Join(c.DatabaseDir(), "backups")
+}
+
+// SecretBox returns the box that encrypts secrets at rest, or nil when no
+// master key is configured. The key is loaded once per config.
+func (c *Config) SecretBox() (*secrets.Box, error) {
+	c.secretsOnce.Do(func() {
+		c.mu.RLock()
+		src := secrets.KeySource{File: c.Storage.MasterKeyFile, Command: c.Storage.MasterKeyCommand}
+		c.mu.RUnlock()
+		c.secretBox, c.secretsErr = secrets.Load(src)
+	})
+	return c.secretBox, c.secretsErr
+}
+
 // DatabaseType returns the runtime database type.
 // Priority: NEKOBOT_DB_TYPE > storage.db_type > sqlite.
 func (c *Config) DatabaseType() string {
@@ -765,6 +1275,105 @@ func (p *ProviderProfile) GetTimeout() int {
 	return 30 // Default 30 seconds
 }
 
+// StreamingEnabled reports whether streaming requests may be sent to this provider.
+func (p *ProviderProfile) StreamingEnabled() bool {
+	return p.Stream == nil || *p.Stream
+}
+
+// ToolCallingUnsupported reports whether a probe of model found that tool
+// definitions are rejected. Probes of other models are ignored.
+func (c *ProviderCapabilities) ToolCallingUnsupported(model string) bool {
+	return c.appliesTo(model) && c.ToolCalling != nil && !*c.ToolCalling
+}
+
+// StreamingUnsupported reports whether a probe of model found that streaming
+// requests are rejected. Probes of other models are ignored.
+func (c *ProviderCapabilities) StreamingUnsupported(model string) bool {
+	return c.appliesTo(model) && c.Streaming != nil && !*c.Streaming
+}
+
+// VisionUnsupported reports whether model is known not to accept images.
+// Probes of other models are ignored.
+func (c *ProviderCapabilities) VisionUnsupported(model string) bool {
+	return c.appliesTo(model) && c.Vision != nil && !*c.Vision
+}
+
+func (c *ProviderCapabilities) appliesTo(model string) bool {
+	return c != nil && strings.TrimSpace(c.Model) == strings.TrimSpace(model)
+}
+
+// ToolTimeout returns the execution timeout for one tool call.
+// Explicit per-tool entries win, MCP tools then fall back to the "mcp" entry,
+// and everything else uses the global default. Zero means no timeout.
+func (t ToolsConfig) ToolTimeout(toolName string, isMCP bool) time.Duration {
+	name := strings.TrimSpace(toolName)
+	if seconds, ok := t.Timeouts[name]; ok && name != "" {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	if isMCP {
+		if seconds, ok := t.Timeouts[MCPToolTimeoutKey]; ok {
+			return time.Duration(max(seconds, 0)) * time.Second
+		}
+	}
+	return time.Duration(max(t.TimeoutSeconds, 0)) * time.Second
+}
+
+// AllowsTool reports whether the MCP server's tool should be exposed to the
+// model. Blocked patterns win over allowed ones; invalid patterns never match.
+func (s MCPServerConfig) AllowsTool(toolName string) bool {
+	name := strings.TrimSpace(toolName)
+	if matchToolPattern(s.BlockedTools, name) {
+		return false
+	}
+	return len(s.AllowedTools) == 0 || matchToolPattern(s.AllowedTools, name)
+}
+
+// DefaultAgentProfile is the reserved profile name that selects the agent
+// defaults, even on a channel that another profile serves.
+const DefaultAgentProfile = "default"
+
+// Profile returns the agent profile with the given name.
+func (c AgentsConfig) Profile(name string) (AgentProfileConfig, bool) {
+	name = strings.TrimSpace(name)
+	for _, profile := range c.Profiles {
+		if strings.TrimSpace(profile.Name) == name && name != "" {
+			return profile, true
+		}
+	}
+	return AgentProfileConfig{}, false
+}
+
+// ProfileForChannel returns the profile that serves a channel by default.
+func (c AgentsConfig) ProfileForChannel(channel string) (AgentProfileConfig, bool) {
+	channel = strings.TrimSpace(channel)
+	if channel == "" {
+		return AgentProfileConfig{}, false
+	}
+	for _, profile := range c.Profiles {
+		for _, candidate := range profile.Channels {
+			if strings.TrimSpace(candidate) == channel {
+				return profile, true
+			}
+		}
+	}
+	return AgentProfileConfig{}, false
+}
+
+// AllowsTool reports whether the profile may call a tool. Invalid patterns
+// never match.
+func (p AgentProfileConfig) AllowsTool(toolName string) bool {
+	return len(p.Tools) == 0 || matchToolPattern(p.Tools, strings.TrimSpace(toolName))
+}
+
+func matchToolPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, err := path.Match(strings.TrimSpace(pattern), name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
 // GetBraveAPIKey returns the Brave search API key with backward compatibility.
 func (w WebSearchConfig) GetBraveAPIKey() string {
 	if strings.TrimSpace(w.BraveAPIKey) != "" {
@@ -871,6 +1480,10 @@ type SessionsConfig struct {
 	Sources SessionSourcesConfig `mapstructure:"sources" json:"sources"`
 	Content SessionContentConfig `mapstructure:"content" json:"content"`
 	Cleanup SessionCleanupConfig `mapstructure:"cleanup" json:"cleanup"`
+	// AutoTitle generates a short session title after the first user turn.
+	AutoTitle SessionAutoTitleConfig `mapstructure:"auto_title" json:"auto_title"`
+	// Export writes transcripts of closed sessions to disk or S3.
+	Export SessionExportConfig `mapstructure:"export" json:"export"`
 }
 
 // SessionSourcesConfig controls which session sources are persisted.
@@ -900,11 +1513,61 @@ type SessionCleanupConfig struct {
 	MaxAgeDays      int  `mapstructure:"max_age_days" json:"max_age_days"`
 }
 
+// SessionAutoTitleConfig controls automatic session title generation.
+// Provider and Model select a cheap model for the title call; empty values
+// fall back to the agent defaults.
+type SessionAutoTitleConfig struct {
+	Enabled   bool   `mapstructure:"enabled" json:"enabled"`
+	Provider  string `mapstructure:"provider" json:"provider"`
+	Model     string `mapstructure:"model" json:"model"`
+	MaxLength int    `mapstructure:"max_length" json:"max_length"` // Max title length in characters
+}
+
+// SessionExportConfig controls transcript exports. Exports go to S3 when a
+// bucket is set, otherwise to Dir.
+type SessionExportConfig struct {
+	Enabled       bool                  `mapstructure:"enabled" json:"enabled"`               // Export sessions when they are closed
+	Format        string                `mapstructure:"format" json:"format"`                 // "jsonl" or "markdown"
+	Dir           string                `mapstructure:"dir" json:"dir"`                       // Default: <workspace>/exports/transcripts
+	RetentionDays int                   `mapstructure:"retention_days" json:"retention_days"` // 0 keeps exports forever; S3 relies on bucket lifecycle rules
+	S3            SessionExportS3Config `mapstructure:"s3" json:"s3"`
+}
+
+// SessionExportS3Config addresses an S3-compatible bucket.
+type SessionExportS3Config struct {
+	Endpoint        string `mapstructure:"endpoint" json:"endpoint"` // Default: https://s3.<region>.amazonaws.com
+	Region          string `mapstructure:"region" json:"region"`
+	Bucket          string `mapstructure:"bucket" json:"bucket"`
+	Prefix          string `mapstructure:"prefix" json:"prefix"`
+	AccessKeyID     string `mapstructure:"access_key_id" json:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key" json:"secret_access_key"`
+	// PathStyle addresses objects as <endpoint>/<bucket>/<key>, as MinIO expects.
+	PathStyle bool `mapstructure:"path_style" json:"path_style"`
+}
+
 // ApprovalConfig for tool execution approval system.
 type ApprovalConfig struct {
 	Mode      string   `mapstructure:"mode" json:"mode"`           // "auto", "prompt", or "manual"
 	Allowlist []string `mapstructure:"allowlist" json:"allowlist"` // Tools that bypass approval
 	Denylist  []string `mapstructure:"denylist" json:"denylist"`   // Tools that are always denied
+	// AlwaysPrompt tools ask in "prompt" mode even when allowlisted.
+	AlwaysPrompt []string `mapstructure:"always_prompt" json:"always_prompt"`
+	// TTLSeconds auto-denies queued requests nobody decided in time; 0 keeps them until decided.
+	TTLSeconds int `mapstructure:"ttl_seconds" json:"ttl_seconds"`
+	// PromptTimeoutSeconds is how long "prompt" mode waits for an answer in chat before denying.
+	PromptTimeoutSeconds int `mapstructure:"prompt_timeout_seconds" json:"prompt_timeout_seconds"`
+	// Rules pick an action per tool call; the first match wins, after the denylist.
+	Rules []ApprovalRuleConfig `mapstructure:"rules" json:"rules"`
+}
+
+// ApprovalRuleConfig assigns an approval action to matching tool calls.
+// Patterns are globs, or regular expressions when prefixed with "re:".
+type ApprovalRuleConfig struct {
+	Name            string            `mapstructure:"name" json:"name"`
+	Tool            string            `mapstructure:"tool" json:"tool"`                         // Tool name pattern; empty matches every tool
+	Args            map[string]string `mapstructure:"args" json:"args"`                         // Argument name -> pattern; all must match
+	InsideWorkspace bool              `mapstructure:"inside_workspace" json:"inside_workspace"` // Require the path argument to stay inside the workspace
+	Action          string            `mapstructure:"action" json:"action"`                     // "auto", "prompt", "manual" or "deny"
 }
 
 // WebUIConfig for the web dashboard.
@@ -912,9 +1575,13 @@ type WebUIConfig struct {
 	Enabled                     bool                    `mapstructure:"enabled" json:"enabled"`                                               // Enable WebUI (default true in daemon mode)
 	Port                        int                     `mapstructure:"port" json:"port"`                                                     // WebUI port (default: gateway port + 1)
 	PublicBaseURL               string                  `mapstructure:"public_base_url" json:"public_base_url"`                               // Preferred external base URL for share links
-	ToolSessionRuntimeTransport string                  `mapstructure:"tool_session_runtime_transport" json:"tool_session_runtime_transport"` // Default runtime transport for tool sessions (tmux or zellij)
+	ToolSessionRuntimeTransport string                  `mapstructure:"tool_session_runtime_transport" json:"tool_session_runtime_transport"` // Default runtime transport for tool sessions (tmux, zellij or native)
+	ToolSessionScrollbackBytes  int                     `mapstructure:"tool_session_scrollback_bytes" json:"tool_session_scrollback_bytes"`   // Terminal output persisted per session (0 = memory only)
 	ToolSessionOTPTTLSeconds    int                     `mapstructure:"tool_session_otp_ttl_seconds" json:"tool_session_otp_ttl_seconds"`     // One-time password TTL for tool sessions (seconds)
 	ToolSessionEvents           ToolSessionEventsConfig `mapstructure:"tool_session_events" json:"tool_session_events"`
+	ToolSessionLimits           ToolSessionLimitsConfig `mapstructure:"tool_session_limits" json:"tool_session_limits"`
+	WebSocketCompression        bool                    `mapstructure:"websocket_compression" json:"websocket_compression"`   // Negotiate per-message deflate on chat and tool WebSockets
+	ChatMaxMessageBytes         int                     `mapstructure:"chat_max_message_bytes" json:"chat_max_message_bytes"` // Largest chat WebSocket message accepted (0 = 65536)
 	SkillSnapshots              SkillSnapshotsConfig    `mapstructure:"skill_snapshots" json:"skill_snapshots"`
 	SkillVersions               SkillVersionsConfig     `mapstructure:"skill_versions" json:"skill_versions"`
 }
@@ -925,6 +1592,16 @@ type ToolSessionEventsConfig struct {
 	RetentionDays int  `mapstructure:"retention_days" json:"retention_days"`
 }
 
+// ToolSessionLimitsConfig bounds the resources of every tool session. Zero
+// disables a limit; a session's own limits can only tighten these.
+type ToolSessionLimitsConfig struct {
+	MaxCPUPercent       int   `mapstructure:"max_cpu_percent" json:"max_cpu_percent"`               // Sustained CPU usage, 100 = one core
+	CPUGraceSeconds     int   `mapstructure:"cpu_grace_seconds" json:"cpu_grace_seconds"`           // How long CPU may stay above max_cpu_percent
+	MaxMemoryMB         int   `mapstructure:"max_memory_mb" json:"max_memory_mb"`                   // Resident memory of the process tree
+	MaxOutputBytes      int64 `mapstructure:"max_output_bytes" json:"max_output_bytes"`             // Terminal output since start
+	MaxWallClockSeconds int   `mapstructure:"max_wall_clock_seconds" json:"max_wall_clock_seconds"` // Absolute run time
+}
+
 // SkillSnapshotsConfig controls marketplace skill snapshot retention.
 type SkillSnapshotsConfig struct {
 	AutoPrune bool `mapstructure:"auto_prune" json:"auto_prune"`
@@ -981,6 +1658,153 @@ type WatchConfig struct {
 	Patterns   []WatchPattern `mapstructure:"patterns" json:"patterns"`
 }
 
+// MOTDConfig controls the banner shown in the CLI header and on the WebUI login page.
+type MOTDConfig struct {
+	Enabled bool   `mapstructure:"enabled" json:"enabled"`
+	Title   string `mapstructure:"title" json:"title"`     // Replaces the default CLI header line
+	Message string `mapstructure:"message" json:"message"` // Operator status message
+	// LatestVersion is compared with the running version to show an update notice.
+	LatestVersion string `mapstructure:"latest_version" json:"latest_version"`
+	// LatestVersionURL is fetched when LatestVersion is empty; it may return plain
+	// text or JSON with a "version" or "tag_name" field.
+	LatestVersionURL string `mapstructure:"latest_version_url" json:"latest_version_url"`
+	UpdateURL        string `mapstructure:"update_url" json:"update_url"` // Where users can get the update
+}
+
+// AlertsConfig controls operator alerts for critical runtime events.
+type AlertsConfig struct {
+	Enabled     bool   `mapstructure:"enabled" json:"enabled"`
+	MinSeverity string `mapstructure:"min_severity" json:"min_severity"` // info, warning or critical
+	// DedupWindowSeconds suppresses repeats of the same alert inside the window.
+	DedupWindowSeconds int `mapstructure:"dedup_window_seconds" json:"dedup_window_seconds"`
+	// EscalateAfter raises a suppressed alert one severity level once it repeats
+	// this many times inside the window. Zero disables repeat escalation.
+	EscalateAfter int    `mapstructure:"escalate_after" json:"escalate_after"`
+	MaxPerHour    int    `mapstructure:"max_per_hour" json:"max_per_hour"` // 0 = unlimited
+	Channel       string `mapstructure:"channel" json:"channel"`           // Admin channel ID, e.g. "telegram"
+	SessionID     string `mapstructure:"session_id" json:"session_id"`     // Admin chat, e.g. "telegram:123456"
+	WebhookURL    string `mapstructure:"webhook_url" json:"webhook_url"`
+	// LogEvents raises alerts for error-level log entries and classified
+	// provider failures.
+	LogEvents bool `mapstructure:"log_events" json:"log_events"`
+}
+
+// ModerationConfig controls the content filter applied to chat messages
+// before and after the agent runs.
+type ModerationConfig struct {
+	Enabled  bool   `mapstructure:"enabled" json:"enabled"`
+	Provider string `mapstructure:"provider" json:"provider"` // keyword or openai
+	Action   string `mapstructure:"action" json:"action"`     // block or log
+	// CheckOutput also runs agent responses through the moderator.
+	CheckOutput bool `mapstructure:"check_output" json:"check_output"`
+	// Categories limits which provider categories count as a hit. Empty means
+	// any flagged result counts. The keyword provider ignores it.
+	Categories []string               `mapstructure:"categories" json:"categories"`
+	Keywords   []string               `mapstructure:"keywords" json:"keywords"` // Case-insensitive substrings
+	Patterns   []string               `mapstructure:"patterns" json:"patterns"` // Go regular expressions
+	OpenAI     ModerationOpenAIConfig `mapstructure:"openai" json:"openai"`
+	// RefusalMessage and ReplacementMessage override the built-in localized
+	// replies for flagged input and output.
+	RefusalMessage     string `mapstructure:"refusal_message" json:"refusal_message"`
+	ReplacementMessage string `mapstructure:"replacement_message" json:"replacement_message"`
+}
+
+// ModerationOpenAIConfig configures the OpenAI moderation endpoint.
+type ModerationOpenAIConfig struct {
+	APIKey  string `mapstructure:"api_key" json:"api_key"`
+	APIBase string `mapstructure:"api_base" json:"api_base"` // Defaults to https://api.openai.com/v1
+	Model   string `mapstructure:"model" json:"model"`       // Defaults to omni-moderation-latest
+}
+
+// UsageConfig controls persisted per-call token usage records and the prices
+// used to estimate their cost.
+type UsageConfig struct {
+	Enabled bool           `mapstructure:"enabled" json:"enabled"`
+	Pricing []ModelPricing `mapstructure:"pricing" json:"pricing"`
+}
+
+// ModelPricing is the price of one model in USD per million tokens. An empty
+// Provider matches the model on every provider.
+type ModelPricing struct {
+	Provider         string  `mapstructure:"provider" json:"provider"`
+	Model            string  `mapstructure:"model" json:"model"`
+	InputPerMillion  float64 `mapstructure:"input_per_million" json:"input_per_million"`
+	OutputPerMillion float64 `mapstructure:"output_per_million" json:"output_per_million"`
+	// Prices for prompt tokens read from or written to the prompt cache;
+	// zero prices them as regular input.
+	CacheReadPerMillion  float64 `mapstructure:"cache_read_per_million" json:"cache_read_per_million,omitempty"`
+	CacheWritePerMillion float64 `mapstructure:"cache_write_per_million" json:"cache_write_per_million,omitempty"`
+}
+
+// ResponseFiltersConfig is an ordered chain of text transforms applied to
+// outgoing responses before any channel delivers them.
+type ResponseFiltersConfig struct {
+	Enabled bool                   `mapstructure:"enabled" json:"enabled"`
+	Filters []ResponseFilterConfig `mapstructure:"filters" json:"filters"`
+}
+
+// ResponseFilterConfig is one step of the response filter chain.
+type ResponseFilterConfig struct {
+	// Type is regex_replace, prepend, append or strip_between.
+	Type        string `mapstructure:"type" json:"type"`
+	Pattern     string `mapstructure:"pattern" json:"pattern"`         // regex_replace: Go regular expression
+	Replacement string `mapstructure:"replacement" json:"replacement"` // regex_replace: supports $1 expansion
+	Text        string `mapstructure:"text" json:"text"`               // prepend / append
+	Start       string `mapstructure:"start" json:"start"`             // strip_between: opening marker
+	End         string `mapstructure:"end" json:"end"`                 // strip_between: closing marker
+	// Channels limits the filter to these channel IDs. Empty means all.
+	Channels []string `mapstructure:"channels" json:"channels"`
+}
+
+// TurnLimitsConfig caps how many agent turns one user can have in flight at
+// once, across every channel.
+type TurnLimitsConfig struct {
+	Enabled    bool `mapstructure:"enabled" json:"enabled"`
+	MaxPerUser int  `mapstructure:"max_per_user" json:"max_per_user"`
+	// Mode is reject (answer with BusyMessage right away) or queue (wait for a
+	// free slot, up to QueueTimeoutSeconds).
+	Mode                string `mapstructure:"mode" json:"mode"`
+	QueueTimeoutSeconds int    `mapstructure:"queue_timeout_seconds" json:"queue_timeout_seconds"` // 0 waits until the request is canceled
+	// BusyMessage overrides the built-in "one at a time" notice.
+	BusyMessage string `mapstructure:"busy_message" json:"busy_message"`
+}
+
+// ToolSessionsConfig gates starting tool-session processes behind admin
+// approval.
+type ToolSessionsConfig struct {
+	// RequireApproval queues spawns, restarts and command changes as
+	// approval requests that an admin other than the requester must approve.
+	RequireApproval        bool `mapstructure:"require_approval" json:"require_approval"`
+	ApprovalTimeoutSeconds int  `mapstructure:"approval_timeout_seconds" json:"approval_timeout_seconds"` // Pending requests expire after this long
+}
+
+// BackupConfig schedules snapshots of the runtime database, the workspace
+// memory files and a config export into a tar.gz archive.
+type BackupConfig struct {
+	Enabled       bool `mapstructure:"enabled" json:"enabled"`
+	IntervalHours int  `mapstructure:"interval_hours" json:"interval_hours"`
+	// Target is local (archives under Dir) or s3.
+	Target string   `mapstructure:"target" json:"target"`
+	Dir    string   `mapstructure:"dir" json:"dir"` // Default <db_dir>/backups
+	S3     S3Config `mapstructure:"s3" json:"s3"`
+	// Keep is how many archives retention leaves, 0 for all; MaxAgeDays
+	// also drops archives older than that. The newest one is never pruned.
+	Keep       int `mapstructure:"keep" json:"keep"`
+	MaxAgeDays int `mapstructure:"max_age_days" json:"max_age_days"` // 0 disables age-based pruning
+}
+
+// S3Config locates an S3-compatible bucket (AWS S3, MinIO, R2, ...) for
+// backups and the workspace backend.
+type S3Config struct {
+	Endpoint  string `mapstructure:"endpoint" json:"endpoint"` // host[:port], e.g. s3.amazonaws.com
+	Region    string `mapstructure:"region" json:"region"`
+	Bucket    string `mapstructure:"bucket" json:"bucket"`
+	Prefix    string `mapstructure:"prefix" json:"prefix"` // Key prefix, e.g. nekobot/
+	AccessKey string `mapstructure:"access_key" json:"access_key"`
+	SecretKey string `mapstructure:"secret_key" json:"secret_key"`
+	Insecure  bool   `mapstructure:"insecure" json:"insecure"` // Plain HTTP instead of HTTPS
+}
+
 // WatchPattern defines a file pattern and command to run on changes.
 type WatchPattern struct {
 	FileGlob    string `mapstructure:"file_glob" json:"file_glob"`
@@ -999,6 +1823,7 @@ func (c *Config) ApplyFrom(other *Config) {
 	c.Channels = other.Channels
 	c.Providers = other.Providers
 	c.Transcription = other.Transcription
+	c.TTS = other.TTS
 	c.Gateway = other.Gateway
 	c.Tools = other.Tools
 	c.Heartbeat = other.Heartbeat
@@ -1012,4 +1837,12 @@ func (c *Config) ApplyFrom(other *Config) {
 	c.Preprocess = other.Preprocess
 	c.Learnings = other.Learnings
 	c.Watch = other.Watch
+	c.MOTD = other.MOTD
+	c.Alerts = other.Alerts
+	c.Moderation = other.Moderation
+	c.Usage = other.Usage
+	c.ResponseFilters = other.ResponseFilters
+	c.TurnLimits = other.TurnLimits
+	c.Backup = other.Backup
+	c.ToolSessions = other.ToolSessions
 }
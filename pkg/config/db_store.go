@@ -16,6 +16,7 @@ var runtimeConfigSections = []string{
 	"gateway",
 	"tools",
 	"transcription",
+	"tts",
 	"heartbeat",
 	"webhook",
 	"redis",
@@ -31,6 +32,14 @@ var runtimeConfigSections = []string{
 	"preprocess",
 	"learnings",
 	"watch",
+	"motd",
+	"alerts",
+	"moderation",
+	"usage",
+	"response_filters",
+	"turn_limits",
+	"backup",
+	"tool_sessions",
 }
 
 // ApplyDatabaseOverrides loads runtime-config sections from SQLite.
@@ -55,7 +64,7 @@ func ApplyDatabaseOverrides(cfg *Config) error {
 			return err
 		}
 		if !exists {
-			payload, err = marshalSection(cfg, section)
+			payload, err = encodeSection(cfg, section)
 			if err != nil {
 				return err
 			}
@@ -64,6 +73,10 @@ func ApplyDatabaseOverrides(cfg *Config) error {
 			}
 			continue
 		}
+		payload, err = decodeSection(cfg, section, payload)
+		if err != nil {
+			return err
+		}
 		if err := applySection(cfg, section, payload); err != nil {
 			return err
 		}
@@ -91,7 +104,7 @@ func SaveDatabaseSections(cfg *Config, sections ...string) error {
 
 	ctx := context.Background()
 	for _, section := range normalizeSections(sections) {
-		payload, err := marshalSection(cfg, section)
+		payload, err := encodeSection(cfg, section)
 		if err != nil {
 			return err
 		}
@@ -108,7 +121,7 @@ func openRuntimeConfigClient(cfg *Config) (*ent.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	if err := EnsureRuntimeEntSchema(client); err != nil {
+	if err := PrepareRuntimeEntSchema(cfg, client); err != nil {
 		_ = client.Close()
 		return nil, err
 	}
@@ -158,6 +171,37 @@ func upsertSectionPayload(ctx context.Context, client *ent.Client, section strin
 	return nil
 }
 
+// encodeSection marshals a section and, with a master key configured,
+// encrypts its secret fields.
+func encodeSection(cfg *Config, section string) ([]byte, error) {
+	payload, err := marshalSection(cfg, section)
+	if err != nil {
+		return nil, err
+	}
+	box, err := cfg.SecretBox()
+	if err != nil {
+		return nil, err
+	}
+	payload, err = box.EncryptJSON(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt %s config: %w", section, err)
+	}
+	return payload, nil
+}
+
+// decodeSection decrypts the secret fields of a stored section.
+func decodeSection(cfg *Config, section string, payload []byte) ([]byte, error) {
+	box, err := cfg.SecretBox()
+	if err != nil {
+		return nil, err
+	}
+	payload, err = box.DecryptJSON(payload)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s config: %w", section, err)
+	}
+	return payload, nil
+}
+
 func marshalSection(cfg *Config, section string) ([]byte, error) {
 	cfg.mu.RLock()
 	defer cfg.mu.RUnlock()
@@ -173,6 +217,8 @@ func marshalSection(cfg *Config, section string) ([]byte, error) {
 		return json.Marshal(cfg.Tools)
 	case "transcription":
 		return json.Marshal(cfg.Transcription)
+	case "tts":
+		return json.Marshal(cfg.TTS)
 	case "heartbeat":
 		return json.Marshal(cfg.Heartbeat)
 	case "webhook":
@@ -203,6 +249,22 @@ func marshalSection(cfg *Config, section string) ([]byte, error) {
 		return json.Marshal(cfg.Learnings)
 	case "watch":
 		return json.Marshal(cfg.Watch)
+	case "motd":
+		return json.Marshal(cfg.MOTD)
+	case "alerts":
+		return json.Marshal(cfg.Alerts)
+	case "moderation":
+		return json.Marshal(cfg.Moderation)
+	case "usage":
+		return json.Marshal(cfg.Usage)
+	case "response_filters":
+		return json.Marshal(cfg.ResponseFilters)
+	case "turn_limits":
+		return json.Marshal(cfg.TurnLimits)
+	case "backup":
+		return json.Marshal(cfg.Backup)
+	case "tool_sessions":
+		return json.Marshal(cfg.ToolSessions)
 	default:
 		return nil, fmt.Errorf("unknown runtime config section: %s", section)
 	}
@@ -243,6 +305,12 @@ func applySection(cfg *Config, section string, payload []byte) error {
 			return fmt.Errorf("decode transcription config: %w", err)
 		}
 		cfg.Transcription = v
+	case "tts":
+		v := cfg.TTS
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return fmt.Errorf("decode tts config: %w", err)
+		}
+		cfg.TTS = v
 	case "heartbeat":
 		var v HeartbeatConfig
 		if err := json.Unmarshal(payload, &v); err != nil {
@@ -298,7 +366,9 @@ func applySection(cfg *Config, section string, payload []byte) error {
 		}
 		cfg.Sessions = v
 	case "webui":
-		var v WebUIConfig
+		// Decode over current values so fields added after the section was
+		// stored keep their defaults instead of becoming zero.
+		v := cfg.WebUI
 		if err := json.Unmarshal(payload, &v); err != nil {
 			return fmt.Errorf("decode webui config: %w", err)
 		}
@@ -333,6 +403,56 @@ func applySection(cfg *Config, section string, payload []byte) error {
 			return fmt.Errorf("decode watch config: %w", err)
 		}
 		cfg.Watch = v
+	case "motd":
+		var v MOTDConfig
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return fmt.Errorf("decode motd config: %w", err)
+		}
+		cfg.MOTD = v
+	case "alerts":
+		// Decode over current values so log_events and the limits keep their
+		// defaults when a stored payload omits them.
+		v := cfg.Alerts
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return fmt.Errorf("decode alerts config: %w", err)
+		}
+		cfg.Alerts = v
+	case "moderation":
+		v := cfg.Moderation
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return fmt.Errorf("decode moderation config: %w", err)
+		}
+		cfg.Moderation = v
+	case "usage":
+		v := cfg.Usage
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return fmt.Errorf("decode usage config: %w", err)
+		}
+		cfg.Usage = v
+	case "response_filters":
+		v := cfg.ResponseFilters
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return fmt.Errorf("decode response_filters config: %w", err)
+		}
+		cfg.ResponseFilters = v
+	case "turn_limits":
+		v := cfg.TurnLimits
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return fmt.Errorf("decode turn_limits config: %w", err)
+		}
+		cfg.TurnLimits = v
+	case "backup":
+		v := cfg.Backup
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return fmt.Errorf("decode backup config: %w", err)
+		}
+		cfg.Backup = v
+	case "tool_sessions":
+		v := cfg.ToolSessions
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return fmt.Errorf("decode tool_sessions config: %w", err)
+		}
+		cfg.ToolSessions = v
 	default:
 		return fmt.Errorf("unknown runtime config section: %s", section)
 	}
@@ -1,11 +1,15 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 
+	"nekobot/pkg/secrets"
 	"nekobot/pkg/storage/ent"
 )
 
@@ -209,6 +213,19 @@ func TestApplyDatabaseOverridesLoadsGatewayLoggerAndWebUI(t *testing.T) {
 	}
 }
 
+func TestApplySectionWebUIKeepsDefaultsForMissingFields(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := applySection(cfg, "webui", []byte(`{"enabled":true,"port":19101}`)); err != nil {
+		t.Fatalf("applySection failed: %v", err)
+	}
+	if cfg.WebUI.Port != 19101 {
+		t.Fatalf("expected stored port to apply, got %d", cfg.WebUI.Port)
+	}
+	if !cfg.WebUI.WebSocketCompression {
+		t.Fatal("expected websocket compression default to survive an older stored section")
+	}
+}
+
 func TestSaveAdminCredentialMigratesToUserTenantMembership(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.Storage.DBDir = t.TempDir()
@@ -335,3 +352,95 @@ func TestGetJWTSecretWithLegacyPayload(t *testing.T) {
 		t.Fatalf("expected legacy-secret, got %q", secret)
 	}
 }
+
+func TestCreateUserMapsOperatorAndViewerRoles(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	client, err := openRuntimeConfigClient(cfg)
+	if err != nil {
+		t.Fatalf("open runtime client: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Fatalf("close runtime client: %v", err)
+		}
+	})
+
+	for _, role := range []string{RoleOperator, RoleViewer} {
+		if _, err := CreateUser(t.Context(), client, UserInput{
+			Username:     role + "-1",
+			PasswordHash: "$2a$10$examplehash",
+			Role:         role,
+			Enabled:      true,
+		}); err != nil {
+			t.Fatalf("CreateUser(%s) failed: %v", role, err)
+		}
+		profile, err := BuildAuthProfileByUsername(t.Context(), client, role+"-1")
+		if err != nil {
+			t.Fatalf("BuildAuthProfileByUsername(%s) failed: %v", role, err)
+		}
+		if profile.Role != role {
+			t.Fatalf("expected %s membership role, got %q", role, profile.Role)
+		}
+	}
+
+	if _, err := CreateUser(t.Context(), client, UserInput{
+		Username:     "superuser",
+		PasswordHash: "$2a$10$examplehash",
+		Role:         "superuser",
+		Enabled:      true,
+	}); err == nil {
+		t.Fatalf("expected unknown role to be rejected")
+	}
+}
+
+func TestDatabaseSectionsEncryptSecretsWithMasterKey(t *testing.T) {
+	key, err := secrets.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(secrets.KeyEnv, key)
+	dbDir := t.TempDir()
+
+	cfg := DefaultConfig()
+	cfg.Storage.DBDir = dbDir
+	cfg.Channels.Telegram.Token = "123:telegram-token"
+	if err := ApplyDatabaseOverrides(cfg); err != nil {
+		t.Fatalf("ApplyDatabaseOverrides failed: %v", err)
+	}
+	cfg.Channels.Telegram.Token = "456:rotated-token"
+	if err := SaveDatabaseSections(cfg, "channels"); err != nil {
+		t.Fatalf("SaveDatabaseSections failed: %v", err)
+	}
+
+	client, err := openRuntimeConfigClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, _, err := loadSectionPayload(context.Background(), client, "channels")
+	_ = client.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(payload), "rotated-token") || !strings.Contains(string(payload), secrets.Prefix) {
+		t.Fatalf("expected the token to be encrypted at rest: %s", payload)
+	}
+
+	reloaded := DefaultConfig()
+	reloaded.Storage.DBDir = dbDir
+	if err := ApplyDatabaseOverrides(reloaded); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if reloaded.Channels.Telegram.Token != "456:rotated-token" {
+		t.Fatalf("expected decrypted token, got %q", reloaded.Channels.Telegram.Token)
+	}
+
+	t.Setenv(secrets.KeyEnv, "")
+	withoutKey := DefaultConfig()
+	withoutKey.Storage.DBDir = dbDir
+	if err := ApplyDatabaseOverrides(withoutKey); !errors.Is(err, secrets.ErrNoKey) {
+		t.Fatalf("expected ErrNoKey without a master key, got %v", err)
+	}
+}
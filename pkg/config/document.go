@@ -0,0 +1,248 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Document formats accepted by MarshalDocument and ParseDocument.
+const (
+	DocumentFormatYAML = "yaml"
+	DocumentFormatJSON = "json"
+)
+
+// documentBootstrapSections live in the bootstrap file only. ParseDocument
+// accepts them so a full config file can be applied, but ApplyDocument
+// skips them: moving the runtime database needs a restart.
+var documentBootstrapSections = []string{"storage"}
+
+// Document is a portable snapshot of the runtime config: every database
+// backed section plus the provider profiles. It round-trips through YAML or
+// JSON so runtime config can be kept in version control.
+type Document struct {
+	// Sections holds the JSON payload of each section present, keyed by
+	// section name.
+	Sections map[string]json.RawMessage
+	// Providers is nil when the document has no providers key.
+	Providers []ProviderProfile
+	// Skipped lists recognized sections that are not applied.
+	Skipped []string
+}
+
+// DocumentFormatFromPath picks YAML for .yaml/.yml files and JSON otherwise.
+func DocumentFormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return DocumentFormatYAML
+	default:
+		return DocumentFormatJSON
+	}
+}
+
+// ExportDocument captures every runtime section of cfg along with providers.
+func ExportDocument(cfg *Config, providers []ProviderProfile) (*Document, error) {
+	doc := &Document{Sections: make(map[string]json.RawMessage, len(runtimeConfigSections))}
+	for _, section := range runtimeConfigSections {
+		payload, err := marshalSection(cfg, section)
+		if err != nil {
+			return nil, err
+		}
+		doc.Sections[section] = payload
+	}
+	doc.Providers = slices.Clone(providers)
+	if doc.Providers == nil {
+		doc.Providers = []ProviderProfile{}
+	}
+	return doc, nil
+}
+
+// MarshalDocument encodes doc with sorted keys, so exporting the same config
+// twice yields identical files.
+func MarshalDocument(doc *Document, format string) ([]byte, error) {
+	out := make(map[string]interface{}, len(doc.Sections)+1)
+	for name, payload := range doc.Sections {
+		out[name] = payload
+	}
+	if doc.Providers != nil {
+		out["providers"] = doc.Providers
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode config document: %w", err)
+	}
+	if format != DocumentFormatYAML {
+		return append(data, '\n'), nil
+	}
+	data, err = yaml.JSONToYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("encode config document as yaml: %w", err)
+	}
+	return data, nil
+}
+
+// ParseDocument decodes a YAML or JSON document. Unknown top-level keys are
+// rejected so a typo cannot silently leave a section unapplied.
+func ParseDocument(data []byte, format string) (*Document, error) {
+	if format == DocumentFormatYAML {
+		converted, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse yaml: %w", err)
+		}
+		data = converted
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse config document: %w", err)
+	}
+
+	doc := &Document{Sections: make(map[string]json.RawMessage, len(raw))}
+	var unknown []string
+	for name, payload := range raw {
+		switch {
+		case name == "providers":
+			if bytes.Equal(bytes.TrimSpace(payload), []byte("null")) {
+				continue
+			}
+			providers := []ProviderProfile{}
+			if err := json.Unmarshal(payload, &providers); err != nil {
+				return nil, fmt.Errorf("decode providers: %w", err)
+			}
+			doc.Providers = providers
+		case slices.Contains(runtimeConfigSections, name):
+			doc.Sections[name] = payload
+		case slices.Contains(documentBootstrapSections, name):
+			doc.Skipped = append(doc.Skipped, name)
+		default:
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown config sections: %s", strings.Join(unknown, ", "))
+	}
+	sort.Strings(doc.Skipped)
+	return doc, nil
+}
+
+// SectionNames returns the sections present in doc in sorted order.
+func (d *Document) SectionNames() []string {
+	names := make([]string, 0, len(d.Sections))
+	for name := range d.Sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyDocument merges doc into cfg and returns the sections whose values
+// changed, sorted by name. The merge is deterministic: a section present in
+// doc replaces the stored one, keys missing inside it take their default
+// values, and sections absent from doc keep their current values. Providers
+// are left to the caller. Nothing is changed when the result fails
+// validation.
+func ApplyDocument(cfg *Config, doc *Document) ([]string, error) {
+	next, changed, err := mergeDocument(cfg, doc)
+	if err != nil {
+		return nil, err
+	}
+	for _, section := range changed {
+		payload, err := marshalSection(next, section)
+		if err != nil {
+			return nil, err
+		}
+		if err := applySection(cfg, section, payload); err != nil {
+			return nil, err
+		}
+	}
+	return changed, nil
+}
+
+// PlanDocument reports the sections ApplyDocument would change without
+// touching cfg.
+func PlanDocument(cfg *Config, doc *Document) ([]string, error) {
+	_, changed, err := mergeDocument(cfg, doc)
+	return changed, err
+}
+
+func mergeDocument(cfg *Config, doc *Document) (*Config, []string, error) {
+	next := DefaultConfig()
+	cfg.mu.RLock()
+	next.Storage = cfg.Storage
+	next.Providers = slices.Clone(cfg.Providers)
+	cfg.mu.RUnlock()
+	for _, section := range runtimeConfigSections {
+		payload, err := marshalSection(cfg, section)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := applySection(next, section, payload); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	defaults := DefaultConfig()
+	for _, section := range doc.SectionNames() {
+		base, err := marshalSection(defaults, section)
+		if err != nil {
+			return nil, nil, err
+		}
+		payload, err := overlayJSON(base, doc.Sections[section])
+		if err != nil {
+			return nil, nil, fmt.Errorf("merge %s config: %w", section, err)
+		}
+		if err := applySection(next, section, payload); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := ValidateConfig(next); err != nil {
+		return nil, nil, fmt.Errorf("validating config: %w", err)
+	}
+
+	var changed []string
+	for _, section := range doc.SectionNames() {
+		before, err := marshalSection(cfg, section)
+		if err != nil {
+			return nil, nil, err
+		}
+		after, err := marshalSection(next, section)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !bytes.Equal(before, after) {
+			changed = append(changed, section)
+		}
+	}
+	return next, changed, nil
+}
+
+// overlayJSON deep-merges the overlay object onto base. Nested objects merge
+// key by key; arrays and scalars in overlay replace the base value.
+func overlayJSON(base, overlay []byte) ([]byte, error) {
+	var baseValue, overlayValue interface{}
+	if err := json.Unmarshal(base, &baseValue); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(overlay, &overlayValue); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergeJSONValue(baseValue, overlayValue))
+}
+
+func mergeJSONValue(base, overlay interface{}) interface{} {
+	baseMap, baseIsMap := base.(map[string]interface{})
+	overlayMap, overlayIsMap := overlay.(map[string]interface{})
+	if !baseIsMap || !overlayIsMap {
+		return overlay
+	}
+	for key, value := range overlayMap {
+		baseMap[key] = mergeJSONValue(baseMap[key], value)
+	}
+	return baseMap
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDocumentRoundTripIsStable(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.MaxToolIterations = 42
+	providers := []ProviderProfile{{Name: "p1", ProviderKind: "openai", APIKey: "k"}}
+
+	for _, format := range []string{DocumentFormatYAML, DocumentFormatJSON} {
+		doc, err := ExportDocument(cfg, providers)
+		if err != nil {
+			t.Fatalf("ExportDocument failed: %v", err)
+		}
+		data, err := MarshalDocument(doc, format)
+		if err != nil {
+			t.Fatalf("MarshalDocument(%s) failed: %v", format, err)
+		}
+		again, err := MarshalDocument(doc, format)
+		if err != nil || string(again) != string(data) {
+			t.Fatalf("expected %s export to be deterministic", format)
+		}
+
+		parsed, err := ParseDocument(data, format)
+		if err != nil {
+			t.Fatalf("ParseDocument(%s) failed: %v", format, err)
+		}
+		if len(parsed.Providers) != 1 || parsed.Providers[0].Name != "p1" {
+			t.Fatalf("providers not round-tripped: %+v", parsed.Providers)
+		}
+		changed, err := ApplyDocument(cfg, parsed)
+		if err != nil {
+			t.Fatalf("ApplyDocument(%s) failed: %v", format, err)
+		}
+		if len(changed) != 0 {
+			t.Fatalf("expected no changes applying an export, got %v", changed)
+		}
+	}
+}
+
+func TestApplyDocumentFillsDefaultsWithinSection(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.MaxToolIterations = 42
+	cfg.Usage.Enabled = false
+
+	doc, err := ParseDocument([]byte("agents:\n  defaults:\n    temperature: 0.3\n"), DocumentFormatYAML)
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+	if doc.Providers != nil {
+		t.Fatalf("expected nil providers without a providers key, got %+v", doc.Providers)
+	}
+	planned, err := PlanDocument(cfg, doc)
+	if err != nil {
+		t.Fatalf("PlanDocument failed: %v", err)
+	}
+	if cfg.Agents.Defaults.MaxToolIterations != 42 {
+		t.Fatal("PlanDocument changed the config")
+	}
+
+	changed, err := ApplyDocument(cfg, doc)
+	if err != nil {
+		t.Fatalf("ApplyDocument failed: %v", err)
+	}
+	if !slices.Equal(changed, []string{"agents"}) || !slices.Equal(planned, changed) {
+		t.Fatalf("unexpected changed sections: planned %v, applied %v", planned, changed)
+	}
+	if cfg.Agents.Defaults.Temperature != 0.3 {
+		t.Fatalf("expected temperature 0.3, got %v", cfg.Agents.Defaults.Temperature)
+	}
+	if want := DefaultConfig().Agents.Defaults.MaxToolIterations; cfg.Agents.Defaults.MaxToolIterations != want {
+		t.Fatalf("expected unset max_tool_iterations to take the default %d, got %d", want, cfg.Agents.Defaults.MaxToolIterations)
+	}
+	if cfg.Usage.Enabled {
+		t.Fatal("expected sections absent from the document to be left alone")
+	}
+}
+
+func TestParseDocumentRejectsUnknownAndSkipsBootstrapSections(t *testing.T) {
+	if _, err := ParseDocument([]byte(`{"agentz":{}}`), DocumentFormatJSON); err == nil || !strings.Contains(err.Error(), "agentz") {
+		t.Fatalf("expected unknown section error, got %v", err)
+	}
+
+	doc, err := ParseDocument([]byte(`{"storage":{"db_dir":"/elsewhere"},"usage":{"enabled":false}}`), DocumentFormatJSON)
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+	if !slices.Equal(doc.Skipped, []string{"storage"}) || !slices.Equal(doc.SectionNames(), []string{"usage"}) {
+		t.Fatalf("unexpected document: skipped %v, sections %v", doc.Skipped, doc.SectionNames())
+	}
+}
+
+func TestApplyDocumentLeavesConfigOnValidationError(t *testing.T) {
+	cfg := DefaultConfig()
+	doc, err := ParseDocument([]byte("agents:\n  defaults:\n    max_tool_iterations: -1\n"), DocumentFormatYAML)
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+	if _, err := ApplyDocument(cfg, doc); err == nil {
+		t.Fatal("expected validation error")
+	}
+	if cfg.Agents.Defaults.MaxToolIterations != DefaultConfig().Agents.Defaults.MaxToolIterations {
+		t.Fatal("expected config untouched after a failed apply")
+	}
+}
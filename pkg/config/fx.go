@@ -65,7 +65,7 @@ func ProvideRuntimeEntClient(cfg *Config, lc fx.Lifecycle) (*ent.Client, error)
 	if err != nil {
 		return nil, err
 	}
-	if err := EnsureRuntimeEntSchema(client); err != nil {
+	if err := PrepareRuntimeEntSchema(cfg, client); err != nil {
 		_ = client.Close()
 		return nil, err
 	}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/spf13/viper"
 )
@@ -12,6 +13,11 @@ import (
 // Loader handles configuration loading with Viper.
 type Loader struct {
 	viper *viper.Viper
+
+	// reloadMu serializes Reload; baseline is the file content the last
+	// reload (or Watch) saw, used to tell which sections were edited on disk.
+	reloadMu sync.Mutex
+	baseline *Config
 }
 
 const ConfigPathEnv = "NEKOBOT_CONFIG_FILE"
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce coalesces the burst of events editors emit for one save.
+const reloadDebounce = 300 * time.Millisecond
+
+// reloadableSections returns the sections ApplyFrom copies, keyed by their
+// JSON name.
+func reloadableSections(c *Config) map[string]interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return map[string]interface{}{
+		"agents":           c.Agents,
+		"channels":         c.Channels,
+		"providers":        c.Providers,
+		"transcription":    c.Transcription,
+		"tts":              c.TTS,
+		"gateway":          c.Gateway,
+		"tools":            c.Tools,
+		"heartbeat":        c.Heartbeat,
+		"logger":           c.Logger,
+		"memory":           c.Memory,
+		"sessions":         c.Sessions,
+		"approval":         c.Approval,
+		"webui":            c.WebUI,
+		"audit":            c.Audit,
+		"undo":             c.Undo,
+		"preprocess":       c.Preprocess,
+		"learnings":        c.Learnings,
+		"watch":            c.Watch,
+		"motd":             c.MOTD,
+		"alerts":           c.Alerts,
+		"moderation":       c.Moderation,
+		"usage":            c.Usage,
+		"response_filters": c.ResponseFilters,
+		"turn_limits":      c.TurnLimits,
+		"backup":           c.Backup,
+		"tool_sessions":    c.ToolSessions,
+	}
+}
+
+// ChangedSections lists the reloadable sections that differ between two
+// configs, sorted by name.
+func ChangedSections(before, after *Config) []string {
+	old, next := reloadableSections(before), reloadableSections(after)
+	changed := make([]string, 0)
+	for name, value := range next {
+		if !sameSection(old[name], value) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// sameSection compares sections by their JSON form, so nil and empty
+// slices count as equal.
+func sameSection(a, b interface{}) bool {
+	left, errA := json.Marshal(a)
+	right, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return string(left) == string(right)
+}
+
+// Reload re-reads the configuration file, applies database overrides and
+// validates the result. A valid config is copied into live and the changed
+// sections are returned; an invalid one leaves live untouched.
+//
+// Runtime sections normally come from the database, and providers from the
+// provider store. Sections edited in the file since the previous reload or
+// Watch win instead: they are applied and written back to the database, and
+// edited providers are merged into the live list by name.
+func (l *Loader) Reload(live *Config) ([]string, error) {
+	l.reloadMu.Lock()
+	defer l.reloadMu.Unlock()
+
+	file, err := l.Load("")
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	fresh, err := l.Load("")
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	if err := ApplyDatabaseOverrides(fresh); err != nil {
+		return nil, fmt.Errorf("applying database overrides: %w", err)
+	}
+
+	var edited []string
+	if l.baseline != nil {
+		edited = ChangedSections(l.baseline, file)
+	}
+	persist := make([]string, 0, len(edited))
+	for _, section := range edited {
+		if section == "providers" {
+			continue
+		}
+		payload, err := marshalSection(file, section)
+		if err != nil {
+			return nil, err
+		}
+		if err := applySection(fresh, section, payload); err != nil {
+			return nil, err
+		}
+		persist = append(persist, section)
+	}
+	live.mu.RLock()
+	providers := slices.Clone(live.Providers)
+	live.mu.RUnlock()
+	if slices.Contains(edited, "providers") {
+		providers = mergeProviders(providers, file.Providers)
+	}
+	fresh.Providers = providers
+
+	if err := ValidateConfig(fresh); err != nil {
+		return nil, fmt.Errorf("validating config: %w", err)
+	}
+	if len(persist) > 0 {
+		if err := SaveDatabaseSections(fresh, persist...); err != nil {
+			return nil, fmt.Errorf("saving edited sections: %w", err)
+		}
+	}
+	l.baseline = file
+
+	changed := ChangedSections(live, fresh)
+	if len(changed) > 0 {
+		live.ApplyFrom(fresh)
+	}
+	return changed, nil
+}
+
+// mergeProviders replaces providers of the same name and appends new ones.
+// Providers missing from the file are kept; remove them in the WebUI.
+func mergeProviders(current, edited []ProviderProfile) []ProviderProfile {
+	merged := slices.Clone(current)
+	for _, profile := range edited {
+		idx := slices.IndexFunc(merged, func(p ProviderProfile) bool { return p.Name == profile.Name })
+		if idx >= 0 {
+			merged[idx] = profile
+		} else {
+			merged = append(merged, profile)
+		}
+	}
+	return merged
+}
+
+// setBaseline records the file content edits are detected against.
+func (l *Loader) setBaseline() error {
+	l.reloadMu.Lock()
+	defer l.reloadMu.Unlock()
+	if l.baseline != nil {
+		return nil
+	}
+	file, err := l.Load("")
+	if err != nil {
+		return err
+	}
+	l.baseline = file
+	return nil
+}
+
+// Watch reloads live whenever the config file changes on disk and reports
+// each reload that changed a section, or failed, to onReload. It returns once
+// the watcher is running and stops when ctx is done.
+func (l *Loader) Watch(ctx context.Context, live *Config, onReload func(changed []string, err error)) error {
+	path := l.GetConfigPath()
+	if path == "" {
+		return fmt.Errorf("no config file to watch")
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolve config path: %w", err)
+	}
+	if err := l.setBaseline(); err != nil {
+		return fmt.Errorf("read config baseline: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create config watcher: %w", err)
+	}
+	// Watch the directory: editors replace the file on save, which drops a
+	// watch on the file itself.
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("watch %s: %w", filepath.Dir(path), err)
+	}
+
+	go func() {
+		defer func() { _ = watcher.Close() }()
+		var timer *time.Timer
+		reload := make(chan struct{}, 1)
+		for {
+			select {
+			case <-ctx.Done():
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != path || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.AfterFunc(reloadDebounce, func() {
+					select {
+					case reload <- struct{}{}:
+					default:
+					}
+				})
+			case <-reload:
+				changed, err := l.Reload(live)
+				if err != nil || len(changed) > 0 {
+					onReload(changed, err)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				onReload(nil, fmt.Errorf("config watcher: %w", err))
+			}
+		}
+	}()
+	return nil
+}
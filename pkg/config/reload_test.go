@@ -0,0 +1,136 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeReloadConfig writes sections to path the way an operator editing the
+// file would; SaveToFile only keeps the bootstrap sections.
+func writeReloadConfig(t *testing.T, path string, sections map[string]interface{}) {
+	t.Helper()
+	doc := map[string]interface{}{
+		"storage": map[string]interface{}{"db_dir": filepath.Dir(path)},
+	}
+	for name, value := range sections {
+		doc[name] = value
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+func TestLoaderReloadAppliesChangedSections(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	writeReloadConfig(t, cfgPath, nil)
+	t.Setenv(ConfigPathEnv, cfgPath)
+
+	loader := NewLoader()
+	live, err := loader.Load("")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if err := ApplyDatabaseOverrides(live); err != nil {
+		t.Fatalf("apply overrides: %v", err)
+	}
+
+	// Providers come from the provider store, not the file.
+	live.Providers = []ProviderProfile{{Name: "stored", ProviderKind: "openai", APIKey: "sk-stored"}}
+
+	changed, err := loader.Reload(live)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if len(changed) != 0 {
+		t.Fatalf("expected no changes for an untouched file, got %v", changed)
+	}
+
+	writeReloadConfig(t, cfgPath, map[string]interface{}{
+		"agents": map[string]interface{}{"defaults": map[string]interface{}{"max_tool_iterations": 7}},
+		"tools":  map[string]interface{}{"exec": map[string]interface{}{"timeout_seconds": 42}},
+	})
+	changed, err = loader.Reload(live)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if !slices.Equal(changed, []string{"agents", "tools"}) {
+		t.Fatalf("expected agents and tools to change, got %v", changed)
+	}
+	if live.Agents.Defaults.MaxToolIterations != 7 || live.Tools.Exec.TimeoutSeconds != 42 {
+		t.Fatalf("expected the new values to be applied, got %+v %+v", live.Agents.Defaults, live.Tools.Exec)
+	}
+	if len(live.Providers) != 1 || live.Providers[0].Name != "stored" {
+		t.Fatalf("expected stored providers to survive the reload, got %+v", live.Providers)
+	}
+
+	writeReloadConfig(t, cfgPath, map[string]interface{}{
+		"agents":  map[string]interface{}{"defaults": map[string]interface{}{"max_tool_iterations": 9}},
+		"gateway": map[string]interface{}{"port": -1},
+	})
+	if _, err := loader.Reload(live); err == nil {
+		t.Fatal("expected an invalid config to be rejected")
+	}
+	if live.Agents.Defaults.MaxToolIterations != 7 {
+		t.Fatalf("expected a rejected reload to leave the config alone, got %d", live.Agents.Defaults.MaxToolIterations)
+	}
+}
+
+func TestLoaderWatchReloadsOnWrite(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	writeReloadConfig(t, cfgPath, nil)
+	t.Setenv(ConfigPathEnv, cfgPath)
+
+	loader := NewLoader()
+	live, err := loader.Load("")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloads := make(chan []string, 4)
+	if err := loader.Watch(ctx, live, func(changed []string, err error) {
+		if err != nil {
+			t.Errorf("unexpected reload error: %v", err)
+			return
+		}
+		reloads <- changed
+	}); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	writeReloadConfig(t, cfgPath, map[string]interface{}{
+		"heartbeat": map[string]interface{}{"interval_minutes": 17},
+	})
+	select {
+	case changed := <-reloads:
+		if !slices.Equal(changed, []string{"heartbeat"}) {
+			t.Fatalf("expected heartbeat to change, got %v", changed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the config reload")
+	}
+}
+
+func TestMergeProvidersReplacesByName(t *testing.T) {
+	merged := mergeProviders(
+		[]ProviderProfile{{Name: "a", APIKey: "old"}, {Name: "b", APIKey: "keep"}},
+		[]ProviderProfile{{Name: "a", APIKey: "new"}, {Name: "c", APIKey: "added"}},
+	)
+	var got []string
+	for _, profile := range merged {
+		got = append(got, profile.Name+"="+profile.APIKey)
+	}
+	if !slices.Equal(got, []string{"a=new", "b=keep", "c=added"}) {
+		t.Fatalf("unexpected merge result: %v", got)
+	}
+}
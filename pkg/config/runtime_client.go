@@ -2,9 +2,13 @@ package config
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
+
+	entsql "entgo.io/ent/dialect/sql"
 
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/lib-x/entsqlite"
@@ -17,17 +21,42 @@ const runtimeSQLiteDSN = "file:%s?cache=shared&_pragma=foreign_keys(1)&_pragma=j
 
 var ensureRuntimeEntSchemaMu sync.Mutex
 
-// OpenRuntimeEntClient opens an Ent client for the unified runtime database.
+// OpenRuntimeEntClient opens an Ent client for the unified runtime database
+// with the storage connection pool limits applied.
 func OpenRuntimeEntClient(cfg *Config) (*ent.Client, error) {
+	drv, err := openRuntimeDriver(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return ent.NewClient(ent.Driver(drv)), nil
+}
+
+func openRuntimeDriver(cfg *Config) (*entsql.Driver, error) {
 	dbType, dsn, err := RuntimeDBOpenConfig(cfg)
 	if err != nil {
 		return nil, err
 	}
-	client, err := ent.Open(entDialectForDatabaseType(dbType), dsn)
+	drv, err := entsql.Open(entDialectForDatabaseType(dbType), dsn)
 	if err != nil {
 		return nil, fmt.Errorf("open runtime database: %w", err)
 	}
-	return client, nil
+	applyRuntimeDBPool(drv.DB(), cfg.Storage)
+	return drv, nil
+}
+
+func applyRuntimeDBPool(db *sql.DB, storage StorageConfig) {
+	if storage.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(storage.MaxOpenConns)
+	}
+	if storage.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(storage.MaxIdleConns)
+	}
+	if storage.ConnMaxLifetimeSeconds > 0 {
+		db.SetConnMaxLifetime(time.Duration(storage.ConnMaxLifetimeSeconds) * time.Second)
+	}
+	if storage.ConnMaxIdleTimeSeconds > 0 {
+		db.SetConnMaxIdleTime(time.Duration(storage.ConnMaxIdleTimeSeconds) * time.Second)
+	}
 }
 
 // RuntimeDBOpenConfig returns the normalized database type and Ent-compatible DSN.
@@ -82,6 +111,15 @@ func sqliteDSNFromConfiguredValue(value string) string {
 	return fmt.Sprintf(runtimeSQLiteDSN, trimmed)
 }
 
+// PrepareRuntimeEntSchema applies the runtime schema unless
+// storage.auto_migrate is off, in which case "nekobot db migrate" does it.
+func PrepareRuntimeEntSchema(cfg *Config, client *ent.Client) error {
+	if cfg != nil && !cfg.Storage.AutoMigrate {
+		return nil
+	}
+	return EnsureRuntimeEntSchema(client)
+}
+
 // EnsureRuntimeEntSchema creates/updates required Ent tables in runtime DB.
 func EnsureRuntimeEntSchema(client *ent.Client) error {
 	if client == nil {
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -100,3 +101,70 @@ func TestValidateConfigRejectsExternalDatabaseWithoutDSN(t *testing.T) {
 		t.Fatalf("expected storage.db_dsn validation error, got %v", err)
 	}
 }
+
+func TestCopyRuntimeDBMovesRowsIntoEmptyDatabase(t *testing.T) {
+	ctx := context.Background()
+	source := DefaultConfig()
+	source.Storage.DBDir = t.TempDir()
+	srcClient, err := OpenRuntimeEntClient(source)
+	if err != nil {
+		t.Fatalf("open source: %v", err)
+	}
+	if err := EnsureRuntimeEntSchema(srcClient); err != nil {
+		t.Fatalf("source schema: %v", err)
+	}
+	user := srcClient.User.Create().SetID("u1").SetUsername("neko").SetEnabled(false).SaveX(ctx)
+	tenant := srcClient.Tenant.Create().SetID("t1").SetSlug("home").SetName("Home").SaveX(ctx)
+	srcClient.Membership.Create().SetID("m1").SetUserID(user.ID).SetTenantID(tenant.ID).SaveX(ctx)
+	srcClient.ConfigSection.Create().SetSection("agents").SetPayloadJSON(`{"defaults":{}}`).SaveX(ctx)
+	_ = srcClient.Close()
+
+	target := DefaultConfig()
+	target.Storage.DBDir = t.TempDir()
+	target.Storage.MaxOpenConns = 2
+	srcPath := filepath.Join(source.Storage.DBDir, RuntimeDBName)
+	copied, err := CopyRuntimeDB(ctx, target, "sqlite", srcPath)
+	if err != nil {
+		t.Fatalf("CopyRuntimeDB failed: %v", err)
+	}
+	rows := map[string]int{}
+	for _, table := range copied {
+		rows[table.Table] = table.Rows
+	}
+	if rows["users"] != 1 || rows["tenants"] != 1 || rows["memberships"] != 1 || rows["config_sections"] != 1 {
+		t.Fatalf("unexpected copy counts %v", rows)
+	}
+
+	dstClient, err := OpenRuntimeEntClient(target)
+	if err != nil {
+		t.Fatalf("open target: %v", err)
+	}
+	defer dstClient.Close()
+	got := dstClient.User.GetX(ctx, "u1")
+	if got.Enabled || got.Username != "neko" || !got.CreatedAt.Equal(user.CreatedAt) {
+		t.Fatalf("unexpected copied user %+v", got)
+	}
+	// The copied auto-increment id must not collide with new rows.
+	dstClient.ConfigSection.Create().SetSection("tools").SetPayloadJSON(`{}`).SaveX(ctx)
+
+	if _, err := CopyRuntimeDB(ctx, target, "sqlite", srcPath); err == nil || !strings.Contains(err.Error(), "empty database") {
+		t.Fatalf("expected a non-empty target to be refused, got %v", err)
+	}
+}
+
+func TestPrepareRuntimeEntSchemaHonorsAutoMigrate(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Storage.AutoMigrate = false
+	client, err := OpenRuntimeEntClient(cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer client.Close()
+	if err := PrepareRuntimeEntSchema(cfg, client); err != nil {
+		t.Fatalf("PrepareRuntimeEntSchema failed: %v", err)
+	}
+	if _, err := client.ConfigSection.Query().Count(context.Background()); err == nil {
+		t.Fatal("expected no schema with auto_migrate off")
+	}
+}
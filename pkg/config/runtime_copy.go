@@ -0,0 +1,273 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect/sql/schema"
+	"entgo.io/ent/schema/field"
+
+	"nekobot/pkg/storage/ent"
+	"nekobot/pkg/storage/ent/migrate"
+)
+
+// RuntimeTableCopy reports the rows copied into one runtime table.
+type RuntimeTableCopy struct {
+	Table string
+	Rows  int
+}
+
+// CopyRuntimeDB copies every runtime table from the database fromType and
+// fromDSN describe into the configured runtime database, e.g. to move a
+// single-node SQLite database to a PostgreSQL or MySQL one shared by several
+// replicas. The target schema is applied first and must hold no rows; the
+// copy runs in one transaction. A SQLite fromDSN may be a plain file path.
+func CopyRuntimeDB(ctx context.Context, cfg *Config, fromType, fromDSN string) ([]RuntimeTableCopy, error) {
+	srcType := normalizeDatabaseType(fromType)
+	srcDSN := strings.TrimSpace(fromDSN)
+	switch srcType {
+	case "sqlite":
+		srcDSN = sqliteDSNFromConfiguredValue(expandPath(srcDSN))
+	case "postgres", "mysql":
+	default:
+		return nil, fmt.Errorf("unsupported source database type %q", fromType)
+	}
+	if srcDSN == "" {
+		return nil, fmt.Errorf("source database DSN is empty")
+	}
+	dstType, dstDSN, err := RuntimeDBOpenConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if srcType == dstType && srcDSN == dstDSN {
+		return nil, fmt.Errorf("source and target are the same database")
+	}
+
+	src, err := sql.Open(entDialectForDatabaseType(srcType), srcDSN)
+	if err != nil {
+		return nil, fmt.Errorf("open source database: %w", err)
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	drv, err := openRuntimeDriver(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client := ent.NewClient(ent.Driver(drv))
+	defer func() {
+		_ = client.Close()
+	}()
+	if err := EnsureRuntimeEntSchema(client); err != nil {
+		return nil, err
+	}
+	return copyRuntimeDB(ctx, src, srcType, drv.DB(), dstType)
+}
+
+// copyRuntimeDB copies the runtime tables of src into the empty dst.
+// Tables missing from an older src are skipped.
+func copyRuntimeDB(ctx context.Context, src *sql.DB, srcType string, dst *sql.DB, dstType string) ([]RuntimeTableCopy, error) {
+	if err := src.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("connect to source database: %w", err)
+	}
+	tables := orderedRuntimeTables()
+	for _, table := range tables {
+		var count int
+		query := "SELECT COUNT(*) FROM " + quoteRuntimeIdent(dstType, table.Name)
+		if err := dst.QueryRowContext(ctx, query).Scan(&count); err != nil {
+			return nil, fmt.Errorf("check target table %s: %w", table.Name, err)
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("target table %s already has %d rows; copy needs an empty database", table.Name, count)
+		}
+	}
+
+	tx, err := dst.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin target transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	copied := make([]RuntimeTableCopy, 0, len(tables))
+	for _, table := range tables {
+		n, err := copyRuntimeTable(ctx, src, srcType, tx, dstType, table)
+		if err != nil {
+			return nil, fmt.Errorf("copy %s: %w", table.Name, err)
+		}
+		copied = append(copied, RuntimeTableCopy{Table: table.Name, Rows: n})
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit target transaction: %w", err)
+	}
+	return copied, nil
+}
+
+func copyRuntimeTable(ctx context.Context, src *sql.DB, srcType string, tx *sql.Tx, dstType string, table *schema.Table) (int, error) {
+	// Older databases may lack tables or columns added since; copy what the
+	// source has and let the target defaults fill the rest.
+	probe, err := src.QueryContext(ctx, "SELECT * FROM "+quoteRuntimeIdent(srcType, table.Name)+" WHERE 1=0")
+	if err != nil {
+		return 0, nil
+	}
+	srcNames, err := probe.Columns()
+	_ = probe.Close()
+	if err != nil {
+		return 0, err
+	}
+	present := make(map[string]bool, len(srcNames))
+	for _, name := range srcNames {
+		present[name] = true
+	}
+	var columns []*schema.Column
+	for _, column := range table.Columns {
+		if present[column.Name] {
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) == 0 {
+		return 0, nil
+	}
+
+	srcCols := make([]string, len(columns))
+	dstCols := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, column := range columns {
+		srcCols[i] = quoteRuntimeIdent(srcType, column.Name)
+		dstCols[i] = quoteRuntimeIdent(dstType, column.Name)
+		placeholders[i] = "?"
+		if dstType == "postgres" {
+			placeholders[i] = "$" + strconv.Itoa(i+1)
+		}
+	}
+	insert, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		quoteRuntimeIdent(dstType, table.Name), strings.Join(dstCols, ", "), strings.Join(placeholders, ", ")))
+	if err != nil {
+		return 0, err
+	}
+	defer insert.Close()
+
+	rows, err := src.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s",
+		strings.Join(srcCols, ", "), quoteRuntimeIdent(srcType, table.Name)))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	n := 0
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return n, err
+		}
+		args := make([]any, len(columns))
+		for i, column := range columns {
+			if args[i], err = convertRuntimeValue(values[i], column); err != nil {
+				return n, fmt.Errorf("column %s: %w", column.Name, err)
+			}
+		}
+		if _, err := insert.ExecContext(ctx, args...); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, err
+	}
+
+	// Explicit ids do not advance PostgreSQL sequences.
+	if dstType == "postgres" {
+		for _, column := range table.PrimaryKey {
+			if !column.Increment || !present[column.Name] {
+				continue
+			}
+			col := quoteRuntimeIdent(dstType, column.Name)
+			query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', '%s'), COALESCE(MAX(%s), 1), MAX(%s) IS NOT NULL) FROM %s",
+				table.Name, column.Name, col, col, quoteRuntimeIdent(dstType, table.Name))
+			if _, err := tx.ExecContext(ctx, query); err != nil {
+				return n, fmt.Errorf("reset %s sequence: %w", column.Name, err)
+			}
+		}
+	}
+	return n, nil
+}
+
+// orderedRuntimeTables returns the runtime tables with every table after
+// the tables its foreign keys reference.
+func orderedRuntimeTables() []*schema.Table {
+	ordered := make([]*schema.Table, 0, len(migrate.Tables))
+	done := make(map[string]bool, len(migrate.Tables))
+	var visit func(table *schema.Table)
+	visit = func(table *schema.Table) {
+		if done[table.Name] {
+			return
+		}
+		done[table.Name] = true
+		for _, fk := range table.ForeignKeys {
+			if fk.RefTable != nil && fk.RefTable != table {
+				visit(fk.RefTable)
+			}
+		}
+		ordered = append(ordered, table)
+	}
+	for _, table := range migrate.Tables {
+		visit(table)
+	}
+	return ordered
+}
+
+func quoteRuntimeIdent(dbType, name string) string {
+	if dbType == "mysql" {
+		return "`" + name + "`"
+	}
+	return `"` + name + `"`
+}
+
+var runtimeTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+}
+
+// convertRuntimeValue maps a scanned source value to one every target driver
+// accepts: SQLite returns booleans as integers and text as bytes.
+func convertRuntimeValue(value any, column *schema.Column) (any, error) {
+	if raw, ok := value.([]byte); ok {
+		if column.Type == field.TypeBytes {
+			return raw, nil
+		}
+		value = string(raw)
+	}
+	if value == nil {
+		return nil, nil
+	}
+	switch column.Type {
+	case field.TypeBool:
+		switch v := value.(type) {
+		case int64:
+			return v != 0, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case field.TypeTime:
+		if v, ok := value.(string); ok {
+			for _, layout := range runtimeTimeLayouts {
+				if t, err := time.Parse(layout, v); err == nil {
+					return t, nil
+				}
+			}
+			return nil, fmt.Errorf("unrecognized time %q", v)
+		}
+	}
+	return value, nil
+}
@@ -34,6 +34,20 @@ func RuntimeDBPath(cfg *Config) (string, error) {
 	return filepath.Join(dbDir, RuntimeDBName), nil
 }
 
+// RuntimeSQLiteFilePath returns the file of a SQLite runtime database. ok is
+// false for PostgreSQL/MySQL and in-memory SQLite.
+func RuntimeSQLiteFilePath(cfg *Config) (string, bool, error) {
+	dbType, dsn, err := RuntimeDBOpenConfig(cfg)
+	if err != nil {
+		return "", false, err
+	}
+	if dbType != "sqlite" {
+		return "", false, nil
+	}
+	path, ok := sqliteFilePathFromDSN(dsn)
+	return path, ok, nil
+}
+
 // RuntimeDBDisplayName returns a redacted runtime database identifier for logs and status APIs.
 func RuntimeDBDisplayName(cfg *Config) (string, error) {
 	dbType, dsn, err := RuntimeDBOpenConfig(cfg)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -15,6 +16,21 @@ import (
 
 var ErrCannotDisableLastPrivilegedUser = errors.New("cannot disable the last privileged user")
 
+var tenantSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// User roles. Admins manage everything, including users; their tenant
+// membership role is owner. Operators run the bot day to day: they approve
+// tools and change providers but cannot manage users or the config file.
+// Viewers have read-only access. Members only reach tool sessions shared
+// with them.
+const (
+	RoleAdmin    = "admin"
+	RoleOwner    = "owner"
+	RoleOperator = "operator"
+	RoleViewer   = "viewer"
+	RoleMember   = "member"
+)
+
 type UserRecord struct {
 	ID         string     `json:"id"`
 	Username   string     `json:"username"`
@@ -34,6 +50,9 @@ type UserInput struct {
 	PasswordHash string
 	Role         string
 	Enabled      bool
+	// TenantSlug places the user in a tenant, creating it if needed. Empty
+	// means the default tenant on create and no change on update.
+	TenantSlug string
 }
 
 func ListUsers(ctx context.Context, client *ent.Client) ([]UserRecord, error) {
@@ -71,7 +90,7 @@ func CreateUser(ctx context.Context, client *ent.Client, input UserInput) (*User
 	}
 	var createdID string
 	err = withTx(ctx, client, func(tx *ent.Tx) error {
-		tenantID, err := ensureDefaultTenantTx(ctx, tx)
+		tenantID, err := ensureTenantTx(ctx, tx, normalized.TenantSlug)
 		if err != nil {
 			return err
 		}
@@ -120,7 +139,7 @@ func UpdateUser(ctx context.Context, client *ent.Client, id string, input UserIn
 	if err != nil {
 		return nil, err
 	}
-	if !normalized.Enabled {
+	if !normalized.Enabled || (normalized.TenantSlug != "" && normalized.TenantSlug != defaultTenantSlug) {
 		if err := ensureNotLastPrivilegedUser(ctx, client, id); err != nil {
 			return nil, err
 		}
@@ -147,6 +166,23 @@ func UpdateUser(ctx context.Context, client *ent.Client, id string, input UserIn
 		}
 		updatedID = rec.ID
 
+		if normalized.TenantSlug != "" {
+			tenantID, err := ensureTenantTx(ctx, tx, normalized.TenantSlug)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Membership.Delete().Where(membership.UserIDEQ(id), membership.TenantIDNEQ(tenantID)).Exec(ctx); err != nil {
+				return fmt.Errorf("delete memberships: %w", err)
+			}
+			if err := ensureMembershipTx(ctx, tx, id, tenantID, membershipRoleForUserRole(normalized.Role)); err != nil {
+				return err
+			}
+			if !normalized.Enabled {
+				_, err = tx.Membership.Update().Where(membership.UserIDEQ(id)).SetEnabled(false).Save(ctx)
+			}
+			return err
+		}
+
 		member, err := tx.Membership.Query().Where(membership.UserIDEQ(id), membership.EnabledEQ(true)).First(ctx)
 		if err == nil {
 			_, err = tx.Membership.UpdateOneID(member.ID).
@@ -211,7 +247,15 @@ func normalizeUserInput(input UserInput, requirePassword bool) (UserInput, error
 	input.Username = strings.TrimSpace(input.Username)
 	input.Nickname = strings.TrimSpace(input.Nickname)
 	input.PasswordHash = strings.TrimSpace(input.PasswordHash)
-	input.Role = normalizeUserRole(input.Role)
+	role, ok := normalizeUserRole(input.Role)
+	if !ok {
+		return UserInput{}, fmt.Errorf("unsupported role %q: use admin, operator, viewer or member", strings.TrimSpace(input.Role))
+	}
+	input.Role = role
+	input.TenantSlug = strings.ToLower(strings.TrimSpace(input.TenantSlug))
+	if input.TenantSlug != "" && !tenantSlugPattern.MatchString(input.TenantSlug) {
+		return UserInput{}, fmt.Errorf("invalid tenant %q: use lowercase letters, digits and dashes", input.TenantSlug)
+	}
 	if input.Username == "" {
 		return UserInput{}, fmt.Errorf("username is required")
 	}
@@ -221,25 +265,26 @@ func normalizeUserInput(input UserInput, requirePassword bool) (UserInput, error
 	return input, nil
 }
 
-func normalizeUserRole(role string) string {
-	switch strings.ToLower(strings.TrimSpace(role)) {
-	case "admin":
-		return "admin"
-	case "owner":
-		return "owner"
+// normalizeUserRole maps role to a known role; an empty role is member.
+func normalizeUserRole(role string) (string, bool) {
+	switch role = strings.ToLower(strings.TrimSpace(role)); role {
+	case RoleAdmin, RoleOwner, RoleOperator, RoleViewer, RoleMember:
+		return role, true
+	case "":
+		return RoleMember, true
 	default:
-		return "member"
+		return "", false
 	}
 }
 
 func membershipRoleForUserRole(role string) string {
-	switch normalizeUserRole(role) {
-	case "admin":
-		return "owner"
-	case "owner":
-		return "owner"
+	switch normalized, _ := normalizeUserRole(role); normalized {
+	case RoleAdmin, RoleOwner:
+		return RoleOwner
+	case RoleOperator, RoleViewer:
+		return normalized
 	default:
-		return "member"
+		return RoleMember
 	}
 }
 
@@ -298,7 +343,7 @@ func ensureNotLastPrivilegedUser(ctx context.Context, client *ent.Client, id str
 		}
 		return err
 	}
-	if profile.Role != "admin" && profile.Role != "owner" {
+	if (profile.Role != "admin" && profile.Role != "owner") || profile.TenantSlug != defaultTenantSlug {
 		return nil
 	}
 	users, err := ListUsers(ctx, client)
@@ -310,7 +355,7 @@ func ensureNotLastPrivilegedUser(ctx context.Context, client *ent.Client, id str
 		if !item.Enabled {
 			continue
 		}
-		if item.Role == "admin" || item.Role == "owner" {
+		if (item.Role == "admin" || item.Role == "owner") && item.TenantSlug == defaultTenantSlug {
 			activePrivileged++
 		}
 	}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"path"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -75,6 +77,7 @@ func (v *Validator) Validate(cfg *Config) error {
 
 	// Validate transcription configuration
 	v.validateTranscription(&cfg.Transcription)
+	v.validateTTS(&cfg.TTS)
 
 	// Validate heartbeat configuration
 	v.validateHeartbeat(&cfg.Heartbeat)
@@ -87,6 +90,17 @@ func (v *Validator) Validate(cfg *Config) error {
 
 	// Validate web UI configuration.
 	v.validateWebUI(&cfg.WebUI)
+	v.validateWebhook(&cfg.Webhook)
+	v.validateMOTD(&cfg.MOTD)
+	v.validateAlerts(&cfg.Alerts)
+	v.validateModeration(&cfg.Moderation)
+	v.validateUsage(&cfg.Usage)
+	v.validateResponseFilters(&cfg.ResponseFilters)
+	v.validateTurnLimits(&cfg.TurnLimits)
+	v.validateBackup(&cfg.Backup)
+	v.validateToolSessions(&cfg.ToolSessions)
+	v.validateBus(cfg)
+	v.validateApproval(&cfg.Approval)
 
 	// Validate harness-ported runtime features.
 	v.validateAudit(&cfg.Audit)
@@ -103,6 +117,23 @@ func (v *Validator) Validate(cfg *Config) error {
 }
 
 func (v *Validator) validateStorage(cfg *Config) {
+	for _, limit := range []struct {
+		name  string
+		value int
+	}{
+		{"max_open_conns", cfg.Storage.MaxOpenConns},
+		{"max_idle_conns", cfg.Storage.MaxIdleConns},
+		{"conn_max_lifetime_seconds", cfg.Storage.ConnMaxLifetimeSeconds},
+		{"conn_max_idle_time_seconds", cfg.Storage.ConnMaxIdleTimeSeconds},
+	} {
+		if limit.value < 0 {
+			v.addError("storage."+limit.name, limit.name+" must be non-negative")
+		}
+	}
+	if cfg.Storage.MaxOpenConns > 0 && cfg.Storage.MaxIdleConns > cfg.Storage.MaxOpenConns {
+		v.addError("storage.max_idle_conns", "max_idle_conns cannot exceed max_open_conns")
+	}
+
 	dbType := cfg.DatabaseType()
 	switch dbType {
 	case "sqlite":
@@ -134,6 +165,22 @@ func (v *Validator) validateAgents(cfg *AgentsConfig) {
 		v.addError("agents.defaults.max_tool_iterations", "max_tool_iterations must be at least 1")
 	}
 
+	if cfg.Defaults.MaxFallbackAttempts < 0 {
+		v.addError("agents.defaults.max_fallback_attempts", "max_fallback_attempts must be non-negative")
+	}
+
+	switch strings.TrimSpace(strings.ToLower(cfg.Defaults.RoutingPolicy)) {
+	case "", "ordered", "latency":
+	default:
+		v.addError("agents.defaults.routing_policy", "routing_policy must be one of: ordered, latency")
+	}
+
+	if cfg.Defaults.CircuitBreakerThreshold < 0 {
+		v.addError("agents.defaults.circuit_breaker_threshold", "circuit_breaker_threshold must be non-negative")
+	}
+
+	v.validateWorkspaceBackend(&cfg.Defaults.WorkspaceBackend)
+
 	orchestrator := strings.TrimSpace(strings.ToLower(cfg.Defaults.Orchestrator))
 	if orchestrator == "" {
 		v.addError("agents.defaults.orchestrator", "orchestrator is required")
@@ -150,6 +197,40 @@ func (v *Validator) validateAgents(cfg *AgentsConfig) {
 		prefix := fmt.Sprintf("agents.defaults.provider_groups[%d]", i)
 		v.validateProviderGroup(prefix, group)
 	}
+
+	v.validateAgentProfiles(cfg.Profiles)
+}
+
+func (v *Validator) validateAgentProfiles(profiles []AgentProfileConfig) {
+	names := make(map[string]struct{}, len(profiles))
+	channels := make(map[string]string)
+	for i, profile := range profiles {
+		prefix := fmt.Sprintf("agents.profiles[%d]", i)
+		name := strings.TrimSpace(profile.Name)
+		switch {
+		case !webhookNamePattern.MatchString(name):
+			v.addError(prefix+".name", "name must contain only letters, digits, '-' or '_'")
+		case strings.EqualFold(name, DefaultAgentProfile):
+			v.addError(prefix+".name", fmt.Sprintf("%q is reserved", DefaultAgentProfile))
+		default:
+			if _, ok := names[name]; ok {
+				v.addError(prefix+".name", fmt.Sprintf("duplicate profile name %q", name))
+			}
+			names[name] = struct{}{}
+		}
+		v.validateToolPatterns(prefix+".tools", profile.Tools)
+		for _, channel := range profile.Channels {
+			channel = strings.TrimSpace(channel)
+			if channel == "" {
+				continue
+			}
+			if owner, ok := channels[channel]; ok {
+				v.addError(prefix+".channels", fmt.Sprintf("channel %q is already served by profile %q", channel, owner))
+				continue
+			}
+			channels[channel] = name
+		}
+	}
 }
 
 func (v *Validator) validateMCPServer(prefix string, cfg MCPServerConfig) {
@@ -186,6 +267,68 @@ func (v *Validator) validateMCPServer(prefix string, cfg MCPServerConfig) {
 			v.addError(prefix+".timeout", err.Error())
 		}
 	}
+
+	v.validateToolPatterns(prefix+".allowed_tools", cfg.AllowedTools)
+	v.validateToolPatterns(prefix+".blocked_tools", cfg.BlockedTools)
+}
+
+func (v *Validator) validateToolPatterns(field string, patterns []string) {
+	for i, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			v.addError(fmt.Sprintf("%s[%d]", field, i), "tool pattern must not be empty")
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			v.addError(fmt.Sprintf("%s[%d]", field, i), fmt.Sprintf("invalid tool pattern %q: %v", pattern, err))
+		}
+	}
+}
+
+func (v *Validator) validateApproval(cfg *ApprovalConfig) {
+	if cfg.TTLSeconds < 0 {
+		v.addError("approval.ttl_seconds", "ttl_seconds must be non-negative")
+	}
+	if cfg.PromptTimeoutSeconds < 0 {
+		v.addError("approval.prompt_timeout_seconds", "prompt_timeout_seconds must be non-negative")
+	}
+	for i, rule := range cfg.Rules {
+		field := fmt.Sprintf("approval.rules[%d]", i)
+		switch rule.Action {
+		case "auto", "prompt", "manual", "deny":
+		default:
+			v.addError(field+".action", fmt.Sprintf("action must be auto, prompt, manual or deny, got %q", rule.Action))
+		}
+		v.validateRulePattern(field+".tool", rule.Tool)
+		for name, pattern := range rule.Args {
+			v.validateRulePattern(fmt.Sprintf("%s.args.%s", field, name), pattern)
+		}
+	}
+}
+
+// validateRulePattern checks the regular expression of a "re:" approval
+// rule pattern; globs are always valid.
+func (v *Validator) validateRulePattern(field, pattern string) {
+	expr, ok := strings.CutPrefix(pattern, "re:")
+	if !ok {
+		return
+	}
+	if _, err := regexp.Compile(expr); err != nil {
+		v.addError(field, fmt.Sprintf("invalid regular expression %q: %v", expr, err))
+	}
+}
+
+func (v *Validator) validatePathPatterns(field string, patterns []string) {
+	for i, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			v.addError(fmt.Sprintf("%s[%d]", field, i), "path pattern must not be empty")
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			v.addError(fmt.Sprintf("%s[%d]", field, i), fmt.Sprintf("invalid path pattern %q: %v", pattern, err))
+		}
+	}
 }
 
 func parseMCPTimeout(raw string) (int64, error) {
@@ -251,17 +394,49 @@ func (v *Validator) validateProviders(cfg *ProvidersConfig) {
 			}
 		}
 
+		if mw := profile.Middleware; mw != nil {
+			if mw.MaxRetries < 0 || mw.MaxRetries > 10 {
+				v.addError(prefix+".middleware.max_retries", "must be between 0 and 10")
+			}
+			if mw.RetryBackoffMS < 0 {
+				v.addError(prefix+".middleware.retry_backoff_ms", "must not be negative")
+			}
+			if mw.AttemptTimeout < 0 {
+				v.addError(prefix+".middleware.attempt_timeout", "must not be negative")
+			}
+			if mw.MaxRequestCost < 0 {
+				v.addError(prefix+".middleware.max_request_cost", "must not be negative")
+			}
+		}
+
 		// Models list is optional - some profiles might not specify models
 		// Default model validation is optional - if not specified, first model will be used
 	}
 }
 
+// validateGroupChat validates the group chat options shared by channels.
+func (v *Validator) validateGroupChat(prefix, sessionScope, groupMode string) {
+	switch strings.TrimSpace(sessionScope) {
+	case "", "chat", "user":
+	default:
+		v.addError(prefix+".session_scope", "must be one of: chat, user")
+	}
+	switch strings.TrimSpace(groupMode) {
+	case "", "all", "mention":
+	default:
+		v.addError(prefix+".group_mode", "must be one of: all, mention")
+	}
+}
+
 // validateChannels validates channel configuration.
 func (v *Validator) validateChannels(cfg *ChannelsConfig) {
 	// Validate Telegram
 	if cfg.Telegram.Enabled && cfg.Telegram.Token == "" {
 		v.addError("channels.telegram.token", "token is required when Telegram is enabled")
 	}
+	v.validateGroupChat("channels.telegram", cfg.Telegram.SessionScope, cfg.Telegram.GroupMode)
+	v.validateGroupChat("channels.discord", cfg.Discord.SessionScope, cfg.Discord.GroupMode)
+	v.validateGroupChat("channels.slack", cfg.Slack.SessionScope, cfg.Slack.GroupMode)
 
 	// Validate Gotify
 	if cfg.Gotify.Enabled {
@@ -354,6 +529,29 @@ func (v *Validator) validateChannels(cfg *ChannelsConfig) {
 	if cfg.Infoflow.Enabled && cfg.Infoflow.WebhookURL == "" {
 		v.addError("channels.infoflow.webhook_url", "webhook_url is required when Infoflow is enabled")
 	}
+
+	// Validate Matrix
+	if cfg.Matrix.Enabled {
+		if cfg.Matrix.Homeserver == "" {
+			v.addError("channels.matrix.homeserver", "homeserver is required when Matrix is enabled")
+		}
+		if cfg.Matrix.UserID == "" {
+			v.addError("channels.matrix.user_id", "user_id is required when Matrix is enabled")
+		}
+		if cfg.Matrix.AccessToken == "" {
+			v.addError("channels.matrix.access_token", "access_token is required when Matrix is enabled")
+		}
+	}
+
+	// Validate Signal
+	if cfg.Signal.Enabled {
+		if cfg.Signal.APIURL == "" {
+			v.addError("channels.signal.api_url", "api_url is required when Signal is enabled")
+		}
+		if cfg.Signal.Number == "" {
+			v.addError("channels.signal.number", "number is required when Signal is enabled")
+		}
+	}
 }
 
 // validateGateway validates gateway configuration.
@@ -412,6 +610,84 @@ func (v *Validator) validateTools(cfg *ToolsConfig) {
 	if cfg.Exec.TimeoutSeconds < 1 {
 		v.addError("tools.exec.timeout_seconds", "timeout_seconds must be at least 1")
 	}
+	if cfg.TimeoutSeconds < 0 {
+		v.addError("tools.timeout_seconds", "timeout_seconds must be non-negative")
+	}
+	for name, seconds := range cfg.Timeouts {
+		if strings.TrimSpace(name) == "" {
+			v.addError("tools.timeouts", "tool name must not be empty")
+			continue
+		}
+		if seconds < 0 {
+			v.addError(fmt.Sprintf("tools.timeouts.%s", name), "timeout must be non-negative")
+		}
+	}
+	if cfg.PromptBudget.MaxTokens < 0 {
+		v.addError("tools.prompt_budget.max_tokens", "max_tokens must be non-negative")
+	}
+	switch strings.TrimSpace(strings.ToLower(cfg.PromptBudget.Strategy)) {
+	case "", "priority", "recent":
+	default:
+		v.addError("tools.prompt_budget.strategy", "strategy must be one of: priority, recent")
+	}
+	if cfg.SpawnAgent.Enabled {
+		if cfg.SpawnAgent.MaxDepth < 1 {
+			v.addError("tools.spawn_agent.max_depth", "max_depth must be at least 1 when spawn_agent is enabled")
+		}
+		if cfg.SpawnAgent.MaxIterations < 1 {
+			v.addError("tools.spawn_agent.max_iterations", "max_iterations must be at least 1 when spawn_agent is enabled")
+		}
+	}
+	if cfg.SpawnAgent.MaxTokens < 0 {
+		v.addError("tools.spawn_agent.max_tokens", "max_tokens must be non-negative")
+	}
+	if cfg.Background.MaxRunning < 1 {
+		v.addError("tools.background.max_running", "max_running must be at least 1")
+	}
+	if cfg.Background.ProgressIntervalSeconds < 0 {
+		v.addError("tools.background.progress_interval_seconds", "progress_interval_seconds must be non-negative")
+	}
+	if cfg.Kubernetes.TimeoutSeconds < 0 {
+		v.addError("tools.kubernetes.timeout_seconds", "timeout_seconds must be non-negative")
+	}
+	if cfg.Files.MaxFileBytes < 0 {
+		v.addError("tools.files.max_file_bytes", "max_file_bytes must be non-negative")
+	}
+	if cfg.Files.MaxSessionWriteBytes < 0 {
+		v.addError("tools.files.max_session_write_bytes", "max_session_write_bytes must be non-negative")
+	}
+	v.validatePathPatterns("tools.files.allow", cfg.Files.Allow)
+	v.validatePathPatterns("tools.files.deny", cfg.Files.Deny)
+	v.validatePathPatterns("tools.files.secret_patterns", cfg.Files.SecretPatterns)
+	for name, rules := range cfg.Files.Agents {
+		v.validatePathPatterns(fmt.Sprintf("tools.files.agents.%s.allow", name), rules.Allow)
+		v.validatePathPatterns(fmt.Sprintf("tools.files.agents.%s.deny", name), rules.Deny)
+	}
+	seenDatabases := make(map[string]bool, len(cfg.Databases))
+	for i, db := range cfg.Databases {
+		field := fmt.Sprintf("tools.databases[%d]", i)
+		name := strings.TrimSpace(db.Name)
+		if name == "" {
+			v.addError(field+".name", "name is required")
+		} else if seenDatabases[name] {
+			v.addError(field+".name", fmt.Sprintf("duplicate database name %q", name))
+		}
+		seenDatabases[name] = true
+		switch db.NormalizedType() {
+		case "postgres", "mysql", "sqlite":
+		default:
+			v.addError(field+".type", "type must be one of: postgres, mysql, sqlite")
+		}
+		if strings.TrimSpace(db.DSN) == "" {
+			v.addError(field+".dsn", "dsn is required")
+		}
+		if db.MaxRows < 0 {
+			v.addError(field+".max_rows", "max_rows must be non-negative")
+		}
+		if db.TimeoutSeconds < 0 {
+			v.addError(field+".timeout_seconds", "timeout_seconds must be non-negative")
+		}
+	}
 	if cfg.Exec.Sandbox.Enabled {
 		if cfg.Exec.Sandbox.Image == "" {
 			v.addError("tools.exec.sandbox.image", "image is required when sandbox is enabled")
@@ -419,6 +695,33 @@ func (v *Validator) validateTools(cfg *ToolsConfig) {
 		if cfg.Exec.Sandbox.Timeout < 1 {
 			v.addError("tools.exec.sandbox.timeout", "timeout must be at least 1")
 		}
+		switch strings.ToLower(strings.TrimSpace(cfg.Exec.Sandbox.Runtime)) {
+		case "", "docker", "gvisor", "firecracker":
+		default:
+			v.addError("tools.exec.sandbox.runtime", "runtime must be one of: docker, gvisor, firecracker")
+		}
+		if cfg.Exec.Sandbox.CPUs < 0 {
+			v.addError("tools.exec.sandbox.cpus", "cpus must be non-negative")
+		}
+		if cfg.Exec.Sandbox.MemoryMB < 0 {
+			v.addError("tools.exec.sandbox.memory_mb", "memory_mb must be non-negative")
+		} else if cfg.Exec.Sandbox.MemoryMB > 0 && cfg.Exec.Sandbox.MemoryMB < 6 {
+			v.addError("tools.exec.sandbox.memory_mb", "memory_mb must be at least 6 (Docker's minimum)")
+		}
+		if cfg.Exec.Sandbox.PidsLimit < 0 {
+			v.addError("tools.exec.sandbox.pids_limit", "pids_limit must be non-negative")
+		}
+		if cfg.Exec.Sandbox.PerSession {
+			if cfg.Exec.Sandbox.MaxContainers < 1 {
+				v.addError("tools.exec.sandbox.max_containers", "max_containers must be at least 1 when per_session is enabled")
+			}
+			if cfg.Exec.Sandbox.PoolSize < 0 || cfg.Exec.Sandbox.PoolSize > cfg.Exec.Sandbox.MaxContainers {
+				v.addError("tools.exec.sandbox.pool_size", "pool_size must be between 0 and max_containers")
+			}
+			if cfg.Exec.Sandbox.IdleTimeoutSeconds < 60 {
+				v.addError("tools.exec.sandbox.idle_timeout_seconds", "idle_timeout_seconds must be at least 60 when per_session is enabled")
+			}
+		}
 	}
 }
 
@@ -427,12 +730,41 @@ func (v *Validator) validateTranscription(cfg *TranscriptionConfig) {
 	if !cfg.Enabled {
 		return
 	}
-	if cfg.Model == "" {
+	backend := strings.ToLower(strings.TrimSpace(cfg.Backend))
+	if cfg.Model == "" && (backend == "" || backend == "whisper") {
 		v.addError("transcription.model", "model is required when transcription is enabled")
 	}
 	if cfg.TimeoutSeconds < 1 {
 		v.addError("transcription.timeout_seconds", "timeout_seconds must be at least 1")
 	}
+	for channel := range cfg.Channels {
+		if strings.TrimSpace(channel) == "" {
+			v.addError("transcription.channels", "channel type cannot be empty")
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(cfg.ConvertFormat)) {
+	case "", "wav", "mp3":
+	default:
+		v.addError("transcription.convert_format", "convert_format must be wav or mp3")
+	}
+}
+
+// validateTTS validates text-to-speech configuration.
+func (v *Validator) validateTTS(cfg *TTSConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	switch strings.ToLower(strings.TrimSpace(cfg.Backend)) {
+	case "", "openai", "elevenlabs", "edge":
+	default:
+		v.addError("tts.backend", "backend must be openai, elevenlabs or edge")
+	}
+	if cfg.TimeoutSeconds < 1 {
+		v.addError("tts.timeout_seconds", "timeout_seconds must be at least 1")
+	}
+	if cfg.MaxChars < 0 {
+		v.addError("tts.max_chars", "max_chars cannot be negative")
+	}
 }
 
 // validateHeartbeat validates heartbeat configuration.
@@ -499,6 +831,21 @@ func (v *Validator) validateMemory(cfg *MemoryConfig) {
 
 // validateSessions validates session persistence configuration.
 func (v *Validator) validateSessions(cfg *SessionsConfig) {
+	if cfg.AutoTitle.MaxLength < 0 {
+		v.addError("sessions.auto_title.max_length", "max_length cannot be negative")
+	}
+	switch strings.ToLower(strings.TrimSpace(cfg.Export.Format)) {
+	case "", "jsonl", "markdown":
+	default:
+		v.addError("sessions.export.format", "format must be one of: jsonl, markdown")
+	}
+	if cfg.Export.RetentionDays < 0 {
+		v.addError("sessions.export.retention_days", "retention_days cannot be negative")
+	}
+	if cfg.Export.S3.Bucket != "" && cfg.Export.S3.Region == "" && cfg.Export.S3.Endpoint == "" {
+		v.addError("sessions.export.s3.region", "region or endpoint is required when bucket is set")
+	}
+
 	if !cfg.Enabled {
 		return
 	}
@@ -536,13 +883,24 @@ func (v *Validator) validateWebUI(cfg *WebUIConfig) {
 		v.addError("webui.tool_session_otp_ttl_seconds", "tool_session_otp_ttl_seconds cannot be negative")
 	}
 	switch strings.TrimSpace(strings.ToLower(cfg.ToolSessionRuntimeTransport)) {
-	case "", "tmux", "zellij":
+	case "", "tmux", "zellij", "native":
 	default:
-		v.addError("webui.tool_session_runtime_transport", "tool_session_runtime_transport must be empty, tmux, or zellij")
+		v.addError("webui.tool_session_runtime_transport", "tool_session_runtime_transport must be empty, tmux, zellij, or native")
+	}
+	if cfg.ToolSessionScrollbackBytes < 0 {
+		v.addError("webui.tool_session_scrollback_bytes", "tool_session_scrollback_bytes cannot be negative")
 	}
 	if cfg.ToolSessionEvents.Enabled && cfg.ToolSessionEvents.RetentionDays < 1 {
 		v.addError("webui.tool_session_events.retention_days", "retention_days must be at least 1 when tool session events are enabled")
 	}
+	limits := cfg.ToolSessionLimits
+	if limits.MaxCPUPercent < 0 || limits.CPUGraceSeconds < 0 || limits.MaxMemoryMB < 0 ||
+		limits.MaxOutputBytes < 0 || limits.MaxWallClockSeconds < 0 {
+		v.addError("webui.tool_session_limits", "tool_session_limits cannot be negative")
+	}
+	if cfg.ChatMaxMessageBytes < 0 {
+		v.addError("webui.chat_max_message_bytes", "chat_max_message_bytes cannot be negative")
+	}
 	if cfg.SkillSnapshots.AutoPrune && cfg.SkillSnapshots.MaxCount < 1 {
 		v.addError("webui.skill_snapshots.max_count", "max_count must be at least 1 when skill snapshot auto prune is enabled")
 	}
@@ -551,6 +909,241 @@ func (v *Validator) validateWebUI(cfg *WebUIConfig) {
 	}
 }
 
+func (v *Validator) validateMOTD(cfg *MOTDConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{name: "motd.latest_version_url", value: cfg.LatestVersionURL},
+		{name: "motd.update_url", value: cfg.UpdateURL},
+	} {
+		raw := strings.TrimSpace(field.value)
+		if raw == "" {
+			continue
+		}
+		parsed, err := url.Parse(raw)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			v.addError(field.name, "must be an absolute http(s) URL")
+		}
+	}
+}
+
+func (v *Validator) validateWebhook(cfg *WebhookConfig) {
+	seen := make(map[string]struct{}, len(cfg.Hooks))
+	for i, hook := range cfg.Hooks {
+		field := fmt.Sprintf("webhook.hooks[%d]", i)
+		name := strings.TrimSpace(hook.Name)
+		if !webhookNamePattern.MatchString(name) {
+			v.addError(field+".name", "name must contain only letters, digits, '-' or '_'")
+		} else if _, ok := seen[name]; ok {
+			v.addError(field+".name", fmt.Sprintf("duplicate hook name %q", name))
+		}
+		seen[name] = struct{}{}
+		if strings.TrimSpace(hook.Secret) == "" {
+			v.addError(field+".secret", "secret is required")
+		}
+		if strings.TrimSpace(hook.ChannelID) != "" && strings.TrimSpace(hook.SessionID) == "" {
+			v.addError(field+".session_id", "session_id is required when channel_id is set")
+		}
+	}
+}
+
+var webhookNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func (v *Validator) validateAlerts(cfg *AlertsConfig) {
+	if cfg.DedupWindowSeconds < 0 {
+		v.addError("alerts.dedup_window_seconds", "dedup_window_seconds cannot be negative")
+	}
+	if cfg.EscalateAfter < 0 {
+		v.addError("alerts.escalate_after", "escalate_after cannot be negative")
+	}
+	if cfg.MaxPerHour < 0 {
+		v.addError("alerts.max_per_hour", "max_per_hour cannot be negative")
+	}
+	switch strings.TrimSpace(strings.ToLower(cfg.MinSeverity)) {
+	case "", "info", "warning", "critical":
+	default:
+		v.addError("alerts.min_severity", "min_severity must be info, warning, or critical")
+	}
+	if !cfg.Enabled {
+		return
+	}
+	if strings.TrimSpace(cfg.WebhookURL) != "" {
+		parsed, err := url.Parse(strings.TrimSpace(cfg.WebhookURL))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			v.addError("alerts.webhook_url", "must be an absolute http(s) URL")
+		}
+	}
+	if strings.TrimSpace(cfg.Channel) != "" && strings.TrimSpace(cfg.SessionID) == "" {
+		v.addError("alerts.session_id", "session_id is required when an alert channel is set")
+	}
+	if strings.TrimSpace(cfg.WebhookURL) == "" && strings.TrimSpace(cfg.Channel) == "" {
+		v.addError("alerts", "enabled alerts need a channel or webhook_url")
+	}
+}
+
+func (v *Validator) validateModeration(cfg *ModerationConfig) {
+	provider := strings.TrimSpace(strings.ToLower(cfg.Provider))
+	switch provider {
+	case "", "keyword", "openai":
+	default:
+		v.addError("moderation.provider", "provider must be keyword or openai")
+	}
+	switch strings.TrimSpace(strings.ToLower(cfg.Action)) {
+	case "", "block", "log":
+	default:
+		v.addError("moderation.action", "action must be block or log")
+	}
+	for i, pattern := range cfg.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			v.addError(fmt.Sprintf("moderation.patterns[%d]", i), fmt.Sprintf("invalid regular expression: %v", err))
+		}
+	}
+	if !cfg.Enabled {
+		return
+	}
+	switch provider {
+	case "openai":
+		if strings.TrimSpace(cfg.OpenAI.APIKey) == "" {
+			v.addError("moderation.openai.api_key", "api_key is required for the openai provider")
+		}
+	case "", "keyword":
+		if len(cfg.Keywords) == 0 && len(cfg.Patterns) == 0 {
+			v.addError("moderation", "the keyword provider needs at least one keyword or pattern")
+		}
+	}
+}
+
+func (v *Validator) validateUsage(cfg *UsageConfig) {
+	for i, price := range cfg.Pricing {
+		field := fmt.Sprintf("usage.pricing[%d]", i)
+		if strings.TrimSpace(price.Model) == "" {
+			v.addError(field+".model", "model is required")
+		}
+		if price.InputPerMillion < 0 || price.OutputPerMillion < 0 {
+			v.addError(field, "prices cannot be negative")
+		}
+	}
+}
+
+func (v *Validator) validateResponseFilters(cfg *ResponseFiltersConfig) {
+	for i, filter := range cfg.Filters {
+		field := fmt.Sprintf("response_filters.filters[%d]", i)
+		switch strings.TrimSpace(strings.ToLower(filter.Type)) {
+		case "regex_replace":
+			if filter.Pattern == "" {
+				v.addError(field+".pattern", "pattern is required for regex_replace")
+			} else if _, err := regexp.Compile(filter.Pattern); err != nil {
+				v.addError(field+".pattern", fmt.Sprintf("invalid regular expression: %v", err))
+			}
+		case "prepend", "append":
+			if filter.Text == "" {
+				v.addError(field+".text", "text is required for prepend and append")
+			}
+		case "strip_between":
+			if filter.Start == "" || filter.End == "" {
+				v.addError(field, "start and end markers are required for strip_between")
+			}
+		default:
+			v.addError(field+".type", "type must be regex_replace, prepend, append or strip_between")
+		}
+	}
+}
+
+func (v *Validator) validateTurnLimits(cfg *TurnLimitsConfig) {
+	switch strings.TrimSpace(strings.ToLower(cfg.Mode)) {
+	case "", "reject", "queue":
+	default:
+		v.addError("turn_limits.mode", "mode must be reject or queue")
+	}
+	if cfg.QueueTimeoutSeconds < 0 {
+		v.addError("turn_limits.queue_timeout_seconds", "queue_timeout_seconds cannot be negative")
+	}
+	if cfg.Enabled && cfg.MaxPerUser < 1 {
+		v.addError("turn_limits.max_per_user", "max_per_user must be at least 1 when turn limits are enabled")
+	}
+}
+
+func (v *Validator) validateToolSessions(cfg *ToolSessionsConfig) {
+	if cfg.RequireApproval && cfg.ApprovalTimeoutSeconds < 1 {
+		v.addError("tool_sessions.approval_timeout_seconds", "approval_timeout_seconds must be at least 1 when tool session approval is required")
+	}
+}
+
+func (v *Validator) validateWorkspaceBackend(cfg *WorkspaceBackendConfig) {
+	const field = "agents.defaults.workspace_backend"
+	switch strings.TrimSpace(strings.ToLower(cfg.Type)) {
+	case "", "local":
+		return
+	case "s3":
+		if strings.TrimSpace(cfg.S3.Endpoint) == "" {
+			v.addError(field+".s3.endpoint", "endpoint is required for the s3 backend")
+		}
+		if strings.TrimSpace(cfg.S3.Bucket) == "" {
+			v.addError(field+".s3.bucket", "bucket is required for the s3 backend")
+		}
+	default:
+		v.addError(field+".type", "type must be local or s3")
+		return
+	}
+	if cfg.SyncIntervalSeconds < 0 {
+		v.addError(field+".sync_interval_seconds", "sync_interval_seconds cannot be negative")
+	}
+	for i, p := range cfg.Paths {
+		clean := path.Clean(strings.TrimSpace(p))
+		if clean == "." || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			v.addError(fmt.Sprintf("%s.paths[%d]", field, i), "paths must be relative to the workspace and stay inside it")
+		}
+	}
+}
+
+func (v *Validator) validateBus(cfg *Config) {
+	switch strings.TrimSpace(strings.ToLower(cfg.Bus.Type)) {
+	case "", "local":
+		return
+	case "redis", "redis_streams":
+		if strings.TrimSpace(cfg.Redis.Addr) == "" {
+			v.addError("redis.addr", "addr is required for the redis bus")
+		}
+	default:
+		v.addError("bus.type", "type must be local, redis or redis_streams")
+		return
+	}
+	if cfg.Bus.Workers < 0 {
+		v.addError("bus.workers", "workers must be non-negative")
+	}
+	if cfg.Bus.ClaimIdleSeconds < 0 {
+		v.addError("bus.claim_idle_seconds", "claim_idle_seconds must be non-negative")
+	}
+}
+
+func (v *Validator) validateBackup(cfg *BackupConfig) {
+	switch strings.TrimSpace(strings.ToLower(cfg.Target)) {
+	case "", "local":
+	case "s3":
+		if cfg.Enabled && strings.TrimSpace(cfg.S3.Endpoint) == "" {
+			v.addError("backup.s3.endpoint", "endpoint is required for the s3 target")
+		}
+		if cfg.Enabled && strings.TrimSpace(cfg.S3.Bucket) == "" {
+			v.addError("backup.s3.bucket", "bucket is required for the s3 target")
+		}
+	default:
+		v.addError("backup.target", "target must be local or s3")
+	}
+	if cfg.Enabled && cfg.IntervalHours < 1 {
+		v.addError("backup.interval_hours", "interval_hours must be at least 1 when backups are enabled")
+	}
+	if cfg.Keep < 0 {
+		v.addError("backup.keep", "keep cannot be negative")
+	}
+	if cfg.MaxAgeDays < 0 {
+		v.addError("backup.max_age_days", "max_age_days cannot be negative")
+	}
+}
+
 func (v *Validator) validateAudit(cfg *AuditConfig) {
 	if !cfg.Enabled {
 		return
@@ -104,6 +104,13 @@ func TestValidateConfigRejectsInvalidMCPServerConfig(t *testing.T) {
 			Name:      "bad-transport",
 			Transport: "udp",
 		},
+		{
+			Name:         "bad-tool-patterns",
+			Transport:    "stdio",
+			Command:      "npx",
+			AllowedTools: []string{"read_["},
+			BlockedTools: []string{"write_*", " "},
+		},
 	}
 
 	err := ValidateConfig(cfg)
@@ -117,11 +124,13 @@ func TestValidateConfigRejectsInvalidMCPServerConfig(t *testing.T) {
 	}
 
 	requiredFields := map[string]bool{
-		"agents.defaults.mcp_servers[0].name":      false,
-		"agents.defaults.mcp_servers[0].endpoint":  false,
-		"agents.defaults.mcp_servers[0].timeout":   false,
-		"agents.defaults.mcp_servers[1].command":   false,
-		"agents.defaults.mcp_servers[2].transport": false,
+		"agents.defaults.mcp_servers[0].name":             false,
+		"agents.defaults.mcp_servers[0].endpoint":         false,
+		"agents.defaults.mcp_servers[0].timeout":          false,
+		"agents.defaults.mcp_servers[1].command":          false,
+		"agents.defaults.mcp_servers[2].transport":        false,
+		"agents.defaults.mcp_servers[3].allowed_tools[0]": false,
+		"agents.defaults.mcp_servers[3].blocked_tools[1]": false,
 	}
 
 	for _, validationErr := range validationErrors {
@@ -322,6 +331,20 @@ func TestValidateConfigRejectsInvalidSessionCleanupConfig(t *testing.T) {
 	}
 }
 
+func TestValidateConfigRejectsNegativeSessionAutoTitleMaxLength(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Sessions.AutoTitle.MaxLength = -1
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected validation error for session auto title")
+	}
+	if !strings.Contains(err.Error(), "sessions.auto_title.max_length") {
+		t.Fatalf("expected sessions.auto_title.max_length validation error, got %v", err)
+	}
+}
+
 func TestValidateConfigRejectsInvalidWebUIRecordRetentionConfig(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.Agents.Defaults.Workspace = t.TempDir()
@@ -361,6 +384,17 @@ func TestValidateConfigRejectsInvalidToolSessionRuntimeTransport(t *testing.T) {
 	}
 }
 
+func TestValidateConfigRejectsNegativeToolSessionLimits(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.WebUI.ToolSessionLimits.MaxMemoryMB = -1
+
+	err := ValidateConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "webui.tool_session_limits") {
+		t.Fatalf("expected tool_session_limits validation error, got %v", err)
+	}
+}
+
 func TestValidateConfigRejectsInvalidHarnessFeatureConfig(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.Agents.Defaults.Workspace = t.TempDir()
@@ -410,3 +444,394 @@ func TestValidateConfigRejectsInvalidHarnessFeatureConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateConfigRejectsInvalidMOTDUpdateURL(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.MOTD.Enabled = true
+	cfg.MOTD.UpdateURL = "releases page"
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected validation error for motd update url")
+	}
+	if !strings.Contains(err.Error(), "motd.update_url") {
+		t.Fatalf("expected motd update url validation error, got %v", err)
+	}
+}
+
+func TestValidateConfigRequiresAlertTarget(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Alerts.Enabled = true
+
+	err := ValidateConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "alerts") {
+		t.Fatalf("expected alerts target validation error, got %v", err)
+	}
+
+	cfg.Alerts.Channel = "telegram"
+	cfg.Alerts.SessionID = "telegram:123456"
+	if err := ValidateConfig(cfg); err != nil {
+		t.Fatalf("expected valid alerts config, got %v", err)
+	}
+}
+
+func TestValidateConfigChecksWebhookHooks(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Webhook.Hooks = []WebhookHookConfig{
+		{Name: "alerts", Secret: "s3cret", ChannelID: "telegram"},
+		{Name: "alerts", Secret: "other"},
+		{Name: "bad name"},
+	}
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected webhook hook validation errors")
+	}
+	for _, want := range []string{
+		"webhook.hooks[0].session_id",
+		"webhook.hooks[1].name",
+		"webhook.hooks[2].name",
+		"webhook.hooks[2].secret",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %s validation error, got %v", want, err)
+		}
+	}
+
+	cfg.Webhook.Hooks = []WebhookHookConfig{
+		{Name: "alerts", Secret: "s3cret", ChannelID: "telegram", SessionID: "telegram:123456"},
+		{Name: "github", Secret: "other"},
+	}
+	if err := ValidateConfig(cfg); err != nil {
+		t.Fatalf("expected valid webhook hooks, got %v", err)
+	}
+}
+
+func TestValidateConfigChecksAgentProfiles(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Agents.Profiles = []AgentProfileConfig{
+		{Name: "coder", Tools: []string{"read_file", "["}, Channels: []string{"discord"}},
+		{Name: "coder"},
+		{Name: "default"},
+		{Name: "support", Channels: []string{"discord"}},
+	}
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected agent profile validation errors")
+	}
+	for _, want := range []string{
+		"agents.profiles[0].tools",
+		"agents.profiles[1].name",
+		"agents.profiles[2].name",
+		"agents.profiles[3].channels",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %s validation error, got %v", want, err)
+		}
+	}
+
+	cfg.Agents.Profiles = []AgentProfileConfig{
+		{Name: "coder", Tools: []string{"read_file", "exec*"}, Channels: []string{"discord"}},
+		{Name: "support", Channels: []string{"telegram"}},
+	}
+	if err := ValidateConfig(cfg); err != nil {
+		t.Fatalf("expected valid agent profiles, got %v", err)
+	}
+	if profile, ok := cfg.Agents.ProfileForChannel("telegram"); !ok || profile.Name != "support" {
+		t.Fatalf("expected telegram to use the support profile, got %+v", profile)
+	}
+	coder, _ := cfg.Agents.Profile("coder")
+	if !coder.AllowsTool("exec") || coder.AllowsTool("web_fetch") {
+		t.Fatalf("unexpected coder tool allowlist %+v", coder.Tools)
+	}
+}
+
+func TestValidateConfigChecksModerationSettings(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Moderation.Enabled = true
+
+	err := ValidateConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "keyword or pattern") {
+		t.Fatalf("expected empty keyword list validation error, got %v", err)
+	}
+
+	cfg.Moderation.Patterns = []string{"(unclosed"}
+	err = ValidateConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "moderation.patterns[0]") {
+		t.Fatalf("expected invalid pattern validation error, got %v", err)
+	}
+
+	cfg.Moderation.Patterns = []string{`\bspam\b`}
+	cfg.Moderation.Provider = "openai"
+	err = ValidateConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "moderation.openai.api_key") {
+		t.Fatalf("expected missing api key validation error, got %v", err)
+	}
+
+	cfg.Moderation.OpenAI.APIKey = "sk-test"
+	cfg.Moderation.Action = "log"
+	if err := ValidateConfig(cfg); err != nil {
+		t.Fatalf("expected valid moderation config, got %v", err)
+	}
+}
+
+func TestValidateConfigRejectsInvalidUsagePricing(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Usage.Pricing = []ModelPricing{{InputPerMillion: -1}}
+
+	err := ValidateConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "usage.pricing[0].model") || !strings.Contains(err.Error(), "cannot be negative") {
+		t.Fatalf("expected usage pricing validation errors, got %v", err)
+	}
+}
+
+func TestValidateConfigChecksResponseFilters(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.ResponseFilters.Filters = []ResponseFilterConfig{
+		{Type: "regex_replace", Pattern: "("},
+		{Type: "append"},
+		{Type: "strip_between", Start: "<think>"},
+		{Type: "uppercase"},
+		{Type: "prepend", Text: "ok"},
+	}
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected response filter validation errors")
+	}
+	for _, want := range []string{
+		"response_filters.filters[0].pattern",
+		"response_filters.filters[1].text",
+		"response_filters.filters[2]",
+		"response_filters.filters[3].type",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "response_filters.filters[4]") {
+		t.Fatalf("valid prepend filter was rejected: %v", err)
+	}
+}
+
+func TestValidateConfigChecksTurnLimits(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.TurnLimits.Enabled = true
+	cfg.TurnLimits.MaxPerUser = 0
+	cfg.TurnLimits.Mode = "drop"
+	cfg.TurnLimits.QueueTimeoutSeconds = -1
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected turn limit validation errors")
+	}
+	for _, want := range []string{
+		"turn_limits.max_per_user",
+		"turn_limits.mode",
+		"turn_limits.queue_timeout_seconds",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestValidateConfigChecksBackup(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Backup.Enabled = true
+	cfg.Backup.IntervalHours = 0
+	cfg.Backup.Target = "s3"
+	cfg.Backup.Keep = -1
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected backup validation errors")
+	}
+	for _, want := range []string{
+		"backup.interval_hours",
+		"backup.s3.endpoint",
+		"backup.s3.bucket",
+		"backup.keep",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+
+	cfg.Backup.Target = "ftp"
+	if err := ValidateConfig(cfg); err == nil || !strings.Contains(err.Error(), "backup.target") {
+		t.Fatalf("expected backup.target error, got %v", err)
+	}
+}
+
+func TestValidateConfigChecksWorkspaceBackend(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Agents.Defaults.WorkspaceBackend.Type = "s3"
+	cfg.Agents.Defaults.WorkspaceBackend.SyncIntervalSeconds = -1
+	cfg.Agents.Defaults.WorkspaceBackend.Paths = []string{"MEMORY.md", "../secrets"}
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected workspace backend validation errors")
+	}
+	for _, want := range []string{
+		"agents.defaults.workspace_backend.s3.endpoint",
+		"agents.defaults.workspace_backend.s3.bucket",
+		"agents.defaults.workspace_backend.sync_interval_seconds",
+		"agents.defaults.workspace_backend.paths[1]",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+
+	cfg.Agents.Defaults.WorkspaceBackend.Type = "ftp"
+	if err := ValidateConfig(cfg); err == nil || !strings.Contains(err.Error(), "workspace_backend.type") {
+		t.Fatalf("expected workspace_backend.type error, got %v", err)
+	}
+}
+
+func TestValidateConfigChecksBus(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Bus.Type = "redis_streams"
+	cfg.Redis.Addr = ""
+	cfg.Bus.Workers = -1
+	cfg.Bus.ClaimIdleSeconds = -1
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected bus validation errors")
+	}
+	for _, want := range []string{"redis.addr", "bus.workers", "bus.claim_idle_seconds"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+
+	cfg.Bus.Type = "kafka"
+	if err := ValidateConfig(cfg); err == nil || !strings.Contains(err.Error(), "bus.type") {
+		t.Fatalf("expected bus.type error, got %v", err)
+	}
+}
+
+func TestMCPServerConfigAllowsTool(t *testing.T) {
+	server := MCPServerConfig{
+		AllowedTools: []string{"read_*", "search"},
+		BlockedTools: []string{"read_secret*"},
+	}
+	cases := map[string]bool{
+		"read_file":        true,
+		"search":           true,
+		"read_secret_keys": false,
+		"write_file":       false,
+	}
+	for name, want := range cases {
+		if got := server.AllowsTool(name); got != want {
+			t.Fatalf("AllowsTool(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	if !(MCPServerConfig{}).AllowsTool("anything") {
+		t.Fatalf("expected empty lists to expose every tool")
+	}
+	if (MCPServerConfig{BlockedTools: []string{"*"}}).AllowsTool("anything") {
+		t.Fatalf("expected blocked wildcard to hide every tool")
+	}
+}
+
+func TestValidateConfigRejectsInvalidGroupMode(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Channels.Discord.GroupMode = "quiet"
+	cfg.Channels.Slack.SessionScope = "thread"
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected validation error for group options")
+	}
+	for _, field := range []string{"channels.discord.group_mode", "channels.slack.session_scope"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Fatalf("expected %s in %v", field, err)
+		}
+	}
+
+	cfg.Channels.Discord.GroupMode = "mention"
+	cfg.Channels.Slack.SessionScope = "user"
+	if err := ValidateConfig(cfg); err != nil {
+		t.Fatalf("expected mention mode and user scope to validate, got %v", err)
+	}
+}
+
+func TestValidateConfigRejectsInvalidTelegramSessionScope(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Channels.Telegram.SessionScope = "thread"
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected validation error for telegram session scope")
+	}
+	if !strings.Contains(err.Error(), "channels.telegram.session_scope") {
+		t.Fatalf("expected channels.telegram.session_scope in %v", err)
+	}
+
+	cfg.Channels.Telegram.SessionScope = "user"
+	if err := ValidateConfig(cfg); err != nil {
+		t.Fatalf("expected user session scope to validate, got %v", err)
+	}
+}
+
+func TestValidateConfigRejectsInvalidTranscriptionConvertFormat(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Transcription.ConvertOnFailure = true
+	cfg.Transcription.ConvertFormat = "flac"
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatalf("expected validation error for transcription convert format")
+	}
+	if !strings.Contains(err.Error(), "transcription.convert_format") {
+		t.Fatalf("expected transcription.convert_format in %v", err)
+	}
+
+	cfg.Transcription.ConvertFormat = "mp3"
+	if err := ValidateConfig(cfg); err != nil {
+		t.Fatalf("expected mp3 convert format to validate, got %v", err)
+	}
+}
+
+func TestValidateConfigChecksApprovalRules(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Approval.Rules = []ApprovalRuleConfig{
+		{Tool: "exec", Args: map[string]string{"command": "re:rm\\s+-rf"}, Action: "manual"},
+		{Tool: "re:(", Action: "manual"},
+		{Tool: "write_file", Action: "sometimes"},
+	}
+
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected validation errors for approval rules")
+	}
+	for _, field := range []string{"approval.rules[1].tool", "approval.rules[2].action"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Fatalf("expected %s in %v", field, err)
+		}
+	}
+	if strings.Contains(err.Error(), "approval.rules[0]") {
+		t.Fatalf("expected the first rule to validate, got %v", err)
+	}
+}
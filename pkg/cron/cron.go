@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -476,6 +477,56 @@ func (m *Manager) ListJobsFiltered(ctx context.Context) []*Job {
 	return jobs
 }
 
+// ListPendingJobs returns the enabled jobs owned by ownerUserID that still
+// have an upcoming run, ordered by fire time.
+func (m *Manager) ListPendingJobs(ownerUserID string) []*Job {
+	ownerUserID = strings.TrimSpace(ownerUserID)
+	if ownerUserID == "" {
+		return nil
+	}
+
+	m.mu.RLock()
+	jobs := make([]*Job, 0)
+	for _, job := range m.jobs {
+		if !job.Enabled || job.OwnerUserID != ownerUserID {
+			continue
+		}
+		jobCopy := *job
+		if entryID, ok := m.entries[job.ID]; ok {
+			if next := m.scheduler.Entry(entryID).Next; !next.IsZero() {
+				jobCopy.NextRun = next
+			}
+		}
+		if jobCopy.NextRun.IsZero() {
+			continue
+		}
+		jobs = append(jobs, &jobCopy)
+	}
+	m.mu.RUnlock()
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].NextRun.Before(jobs[j].NextRun)
+	})
+	return jobs
+}
+
+// CancelJob removes a job on behalf of ownerUserID. Jobs owned by anyone
+// else are reported as not found.
+func (m *Manager) CancelJob(ownerUserID, jobID string) error {
+	ownerUserID = strings.TrimSpace(ownerUserID)
+	jobID = strings.TrimSpace(jobID)
+
+	m.mu.RLock()
+	job, exists := m.jobs[jobID]
+	owned := exists && ownerUserID != "" && job.OwnerUserID == ownerUserID
+	m.mu.RUnlock()
+	if !owned {
+		return fmt.Errorf("job not found: %s", jobID)
+	}
+
+	return m.RemoveJob(jobID)
+}
+
 // GetJob returns a job by ID.
 func (m *Manager) GetJob(jobID string) (*Job, error) {
 	m.mu.RLock()
@@ -584,7 +635,7 @@ func (m *Manager) RemoveJobAuth(ctx context.Context, jobID string) error {
 		if err != nil {
 			return err
 		}
-		if !ac.CanWrite(job.OwnerUserID) {
+		if !ac.CanWrite(job.OwnerUserID, job.TenantID) {
 			return ownership.ErrPermissionDenied
 		}
 	}
@@ -598,7 +649,7 @@ func (m *Manager) EnableJobAuth(ctx context.Context, jobID string) error {
 		if err != nil {
 			return err
 		}
-		if !ac.CanWrite(job.OwnerUserID) {
+		if !ac.CanWrite(job.OwnerUserID, job.TenantID) {
 			return ownership.ErrPermissionDenied
 		}
 	}
@@ -612,7 +663,7 @@ func (m *Manager) DisableJobAuth(ctx context.Context, jobID string) error {
 		if err != nil {
 			return err
 		}
-		if !ac.CanWrite(job.OwnerUserID) {
+		if !ac.CanWrite(job.OwnerUserID, job.TenantID) {
 			return ownership.ErrPermissionDenied
 		}
 	}
@@ -626,7 +677,7 @@ func (m *Manager) RunJobAuth(ctx context.Context, jobID string) error {
 		if err != nil {
 			return err
 		}
-		if !ac.CanWrite(job.OwnerUserID) {
+		if !ac.CanWrite(job.OwnerUserID, job.TenantID) {
 			return ownership.ErrPermissionDenied
 		}
 	}
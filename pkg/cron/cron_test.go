@@ -2,7 +2,9 @@ package cron
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"nekobot/pkg/config"
 	"nekobot/pkg/execenv"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/ownership"
 	"nekobot/pkg/tasks"
 )
 
@@ -437,3 +440,110 @@ func (m *Manager) hasJob(jobID string) bool {
 	_, ok := m.jobs[jobID]
 	return ok
 }
+
+func TestManagerListPendingJobsScopesToOwner(t *testing.T) {
+	t.Parallel()
+
+	manager, cleanup := newTestManager(t)
+	defer cleanup()
+
+	later := time.Now().Add(2 * time.Hour)
+	sooner := time.Now().Add(time.Hour)
+	for _, job := range []*Job{
+		{ID: "later", Name: "later", ScheduleKind: ScheduleAt, AtTime: &later, NextRun: later, Prompt: "b", Enabled: true, OwnerUserID: "alice"},
+		{ID: "sooner", Name: "sooner", ScheduleKind: ScheduleAt, AtTime: &sooner, NextRun: sooner, Prompt: "a", Enabled: true, OwnerUserID: "alice"},
+		{ID: "other", Name: "other", ScheduleKind: ScheduleAt, AtTime: &sooner, NextRun: sooner, Prompt: "c", Enabled: true, OwnerUserID: "bob"},
+		{ID: "paused", Name: "paused", ScheduleKind: ScheduleAt, AtTime: &sooner, NextRun: sooner, Prompt: "d", Enabled: false, OwnerUserID: "alice"},
+	} {
+		if _, err := manager.addAndSchedule(job); err != nil {
+			t.Fatalf("add job %s: %v", job.ID, err)
+		}
+	}
+
+	jobs := manager.ListPendingJobs("alice")
+	if len(jobs) != 2 || jobs[0].ID != "sooner" || jobs[1].ID != "later" {
+		t.Fatalf("expected alice's pending jobs ordered by fire time, got %+v", jobs)
+	}
+	if got := manager.ListPendingJobs(""); len(got) != 0 {
+		t.Fatalf("expected no jobs for anonymous owner, got %+v", got)
+	}
+}
+
+func TestManagerCancelJobRequiresOwnerAndStopsFiring(t *testing.T) {
+	t.Parallel()
+
+	manager, cleanup := newTestManager(t)
+	defer cleanup()
+
+	var mu sync.Mutex
+	fired := 0
+	manager.agentChat = func(ctx context.Context, sess agent.SessionInterface, prompt, provider, model string, fallback []string) (string, error) {
+		mu.Lock()
+		fired++
+		mu.Unlock()
+		return "ok", nil
+	}
+
+	due := time.Now().Add(-time.Minute)
+	job := &Job{ID: "reminder", Name: "reminder", ScheduleKind: ScheduleAt, AtTime: &due, NextRun: due, Prompt: "ping", Enabled: true, OwnerUserID: "alice"}
+	if _, err := manager.addAndSchedule(job); err != nil {
+		t.Fatalf("add job: %v", err)
+	}
+
+	if err := manager.CancelJob("bob", "reminder"); err == nil {
+		t.Fatalf("expected cancel by another user to fail")
+	}
+	if !manager.hasJob("reminder") {
+		t.Fatalf("expected job to survive foreign cancel")
+	}
+
+	if err := manager.CancelJob("alice", "reminder"); err != nil {
+		t.Fatalf("cancel job: %v", err)
+	}
+	if manager.hasJob("reminder") {
+		t.Fatalf("expected job removed after cancel")
+	}
+	if _, err := manager.client.CronJob.Get(t.Context(), "reminder"); err == nil {
+		t.Fatalf("expected cancelled job removed from storage")
+	}
+
+	manager.checkTimerJobs()
+	mu.Lock()
+	defer mu.Unlock()
+	if fired != 0 {
+		t.Fatalf("expected cancelled job not to fire, fired %d times", fired)
+	}
+}
+
+func TestManagerScopesTenantOwnersToTheirTenant(t *testing.T) {
+	t.Parallel()
+
+	manager, cleanup := newTestManager(t)
+	defer cleanup()
+
+	for _, job := range []*Job{
+		{ID: "job-a", Name: "a", ScheduleKind: ScheduleCron, Schedule: "0 0 * * *", Prompt: "ping", TenantID: "tenant-a", OwnerUserID: "user-a", Visibility: "shared"},
+		{ID: "job-b", Name: "b", ScheduleKind: ScheduleCron, Schedule: "0 0 * * *", Prompt: "ping", TenantID: "tenant-b", OwnerUserID: "user-b", Visibility: "shared"},
+	} {
+		if _, err := manager.addAndSchedule(job); err != nil {
+			t.Fatalf("add job %s: %v", job.ID, err)
+		}
+	}
+
+	ownerA := ownership.WithAuthContext(t.Context(), ownership.AuthContext{UserID: "owner-a", TenantID: "tenant-a", TenantSlug: "team-a", Role: "owner"})
+	jobs := manager.ListJobsFiltered(ownerA)
+	if len(jobs) != 1 || jobs[0].ID != "job-a" {
+		t.Fatalf("expected tenant owner to see only job-a, got %+v", jobs)
+	}
+	if err := manager.RemoveJobAuth(ownerA, "job-b"); !errors.Is(err, ownership.ErrPermissionDenied) {
+		t.Fatalf("expected permission denied removing another tenant's job, got %v", err)
+	}
+
+	admin := ownership.WithAuthContext(t.Context(), ownership.AuthContext{UserID: "admin", TenantID: "tenant-default", TenantSlug: ownership.DefaultTenantSlug, Role: "owner"})
+	if jobs := manager.ListJobsFiltered(admin); len(jobs) != 2 {
+		t.Fatalf("expected default tenant admin to see every job, got %+v", jobs)
+	}
+	if err := manager.RemoveJobAuth(admin, "job-b"); err != nil {
+		t.Fatalf("admin remove job: %v", err)
+	}
+}
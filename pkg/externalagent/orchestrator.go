@@ -201,7 +201,7 @@ func (o *ResolveOrchestrator) HandleLaunchApproval(
 	if o == nil || o.approval == nil {
 		return ApprovalResult{}, false, nil
 	}
-	decision, requestID, err := o.approval.CheckApproval(toolName, map[string]any{
+	decision, requestID, err := o.approval.CheckApproval(context.Background(), toolName, map[string]any{
 		"tool_name":  toolName,
 		"session_id": sessionID,
 	}, sessionID)
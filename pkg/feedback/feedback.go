@@ -0,0 +1,220 @@
+// Package feedback persists thumbs up/down ratings users give agent replies
+// so they can be reviewed and exported as an evaluation dataset.
+package feedback
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"nekobot/pkg/storage/ent"
+	entfeedback "nekobot/pkg/storage/ent/feedback"
+)
+
+// Ratings stored in Entry.Rating.
+const (
+	RatingUp   = 1
+	RatingDown = -1
+)
+
+// maxResponseChars bounds the reply snapshot stored with each rating.
+const maxResponseChars = 8000
+
+// DefaultListLimit caps List when the query does not set a limit.
+const DefaultListLimit = 500
+
+// Entry is one user's rating of one reply.
+type Entry struct {
+	ID        string    `json:"id"`
+	Channel   string    `json:"channel"`
+	SessionID string    `json:"session_id"`
+	MessageID string    `json:"message_id"`
+	UserID    string    `json:"user_id"`
+	Rating    int       `json:"rating"`
+	Comment   string    `json:"comment"`
+	Response  string    `json:"response"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Query filters List. Zero values match everything.
+type Query struct {
+	Channel   string
+	SessionID string
+	Rating    int
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
+
+// Manager stores feedback in the runtime database.
+type Manager struct {
+	client *ent.Client
+}
+
+// NewManager creates a feedback manager backed by the runtime database.
+func NewManager(client *ent.Client) (*Manager, error) {
+	if client == nil {
+		return nil, fmt.Errorf("ent client is nil")
+	}
+	return &Manager{client: client}, nil
+}
+
+// Record saves a rating. Rating the same message again replaces the earlier
+// rating and comment; an empty comment keeps the previous one.
+func (m *Manager) Record(ctx context.Context, entry Entry) (Entry, error) {
+	entry.Channel = strings.TrimSpace(entry.Channel)
+	entry.SessionID = strings.TrimSpace(entry.SessionID)
+	entry.MessageID = strings.TrimSpace(entry.MessageID)
+	entry.UserID = strings.TrimSpace(entry.UserID)
+	entry.Comment = strings.TrimSpace(entry.Comment)
+	if entry.Channel == "" || entry.SessionID == "" || entry.MessageID == "" {
+		return Entry{}, fmt.Errorf("channel, session and message are required")
+	}
+	if entry.Rating != RatingUp && entry.Rating != RatingDown {
+		return Entry{}, fmt.Errorf("rating must be %d or %d", RatingUp, RatingDown)
+	}
+	if runes := []rune(entry.Response); len(runes) > maxResponseChars {
+		entry.Response = string(runes[:maxResponseChars])
+	}
+
+	existing, err := m.client.Feedback.Query().
+		Where(
+			entfeedback.ChannelEQ(entry.Channel),
+			entfeedback.SessionIDEQ(entry.SessionID),
+			entfeedback.MessageIDEQ(entry.MessageID),
+			entfeedback.UserIDEQ(entry.UserID),
+		).
+		Only(ctx)
+	switch {
+	case err == nil:
+		update := existing.Update().SetRating(entry.Rating)
+		if entry.Comment != "" {
+			update.SetComment(entry.Comment)
+		}
+		if entry.Response != "" {
+			update.SetResponse(entry.Response)
+		}
+		saved, err := update.Save(ctx)
+		if err != nil {
+			return Entry{}, fmt.Errorf("update feedback: %w", err)
+		}
+		return fromEnt(saved), nil
+	case ent.IsNotFound(err):
+		saved, err := m.client.Feedback.Create().
+			SetChannel(entry.Channel).
+			SetSessionID(entry.SessionID).
+			SetMessageID(entry.MessageID).
+			SetUserID(entry.UserID).
+			SetRating(entry.Rating).
+			SetComment(entry.Comment).
+			SetResponse(entry.Response).
+			Save(ctx)
+		if err != nil {
+			return Entry{}, fmt.Errorf("save feedback: %w", err)
+		}
+		return fromEnt(saved), nil
+	default:
+		return Entry{}, fmt.Errorf("query feedback: %w", err)
+	}
+}
+
+// List returns the newest feedback matching q.
+func (m *Manager) List(ctx context.Context, q Query) ([]Entry, error) {
+	query := m.client.Feedback.Query()
+	if channel := strings.TrimSpace(q.Channel); channel != "" {
+		query = query.Where(entfeedback.ChannelEQ(channel))
+	}
+	if sessionID := strings.TrimSpace(q.SessionID); sessionID != "" {
+		query = query.Where(entfeedback.SessionIDEQ(sessionID))
+	}
+	if q.Rating != 0 {
+		query = query.Where(entfeedback.RatingEQ(q.Rating))
+	}
+	if !q.From.IsZero() {
+		query = query.Where(entfeedback.CreatedAtGTE(q.From))
+	}
+	if !q.To.IsZero() {
+		query = query.Where(entfeedback.CreatedAtLT(q.To))
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	items, err := query.Order(ent.Desc(entfeedback.FieldCreatedAt)).Limit(limit).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list feedback: %w", err)
+	}
+	entries := make([]Entry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, fromEnt(item))
+	}
+	return entries, nil
+}
+
+// SessionMessageID is the message reference for the reply at index in a
+// session history. Channels with native message IDs use those instead.
+func SessionMessageID(index int) string {
+	return "msg-" + strconv.Itoa(index)
+}
+
+// ParseRating accepts up/down, +1/-1, like/dislike and the thumbs emoji.
+func ParseRating(value string) (int, error) {
+	switch strings.TrimSpace(strings.ToLower(value)) {
+	case "up", "+1", "1", "good", "like", "👍":
+		return RatingUp, nil
+	case "down", "-1", "bad", "dislike", "👎":
+		return RatingDown, nil
+	default:
+		return 0, fmt.Errorf("unknown rating %q: use up or down", value)
+	}
+}
+
+// RatingLabel renders a rating as "up" or "down".
+func RatingLabel(rating int) string {
+	if rating < 0 {
+		return "down"
+	}
+	return "up"
+}
+
+// WriteCSV writes entries with a header row.
+func WriteCSV(w io.Writer, entries []Entry) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{{"created_at", "channel", "session_id", "message_id", "user_id", "rating", "comment", "response"}}
+	for _, entry := range entries {
+		rows = append(rows, []string{
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			entry.Channel,
+			entry.SessionID,
+			entry.MessageID,
+			entry.UserID,
+			strconv.Itoa(entry.Rating),
+			entry.Comment,
+			entry.Response,
+		})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("write feedback csv: %w", err)
+	}
+	return nil
+}
+
+func fromEnt(item *ent.Feedback) Entry {
+	return Entry{
+		ID:        item.ID,
+		Channel:   item.Channel,
+		SessionID: item.SessionID,
+		MessageID: item.MessageID,
+		UserID:    item.UserID,
+		Rating:    item.Rating,
+		Comment:   item.Comment,
+		Response:  item.Response,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
@@ -0,0 +1,113 @@
+package feedback
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"nekobot/pkg/config"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		t.Fatalf("open runtime ent client: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	if err := config.EnsureRuntimeEntSchema(client); err != nil {
+		t.Fatalf("ensure runtime schema: %v", err)
+	}
+
+	mgr, err := NewManager(client)
+	if err != nil {
+		t.Fatalf("new feedback manager: %v", err)
+	}
+	return mgr
+}
+
+func TestRecordReplacesEarlierRatingPerUserAndMessage(t *testing.T) {
+	mgr := newTestManager(t)
+	ctx := context.Background()
+	base := Entry{Channel: "telegram", SessionID: "telegram:1", MessageID: "telegram:10", UserID: "5", Response: "answer"}
+
+	first := base
+	first.Rating = RatingDown
+	first.Comment = "wrong file"
+	if _, err := mgr.Record(ctx, first); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	second := base
+	second.Rating = RatingUp
+	if _, err := mgr.Record(ctx, second); err != nil {
+		t.Fatalf("re-record: %v", err)
+	}
+	other := base
+	other.UserID = "6"
+	other.Rating = RatingDown
+	if _, err := mgr.Record(ctx, other); err != nil {
+		t.Fatalf("record other user: %v", err)
+	}
+
+	entries, err := mgr.List(ctx, Query{SessionID: "telegram:1"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected one entry per user, got %+v", entries)
+	}
+	up, err := mgr.List(ctx, Query{Rating: RatingUp})
+	if err != nil {
+		t.Fatalf("list up: %v", err)
+	}
+	if len(up) != 1 || up[0].UserID != "5" || up[0].Comment != "wrong file" {
+		t.Fatalf("expected the re-rating to keep the comment, got %+v", up)
+	}
+
+	future, err := mgr.List(ctx, Query{From: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("list future: %v", err)
+	}
+	if len(future) != 0 {
+		t.Fatalf("expected the time filter to exclude entries, got %+v", future)
+	}
+}
+
+func TestRecordValidatesEntries(t *testing.T) {
+	mgr := newTestManager(t)
+	if _, err := mgr.Record(context.Background(), Entry{Channel: "telegram", SessionID: "s", MessageID: "m", Rating: 3}); err == nil {
+		t.Fatal("expected an invalid rating to be rejected")
+	}
+	if _, err := mgr.Record(context.Background(), Entry{Channel: "telegram", Rating: RatingUp}); err == nil {
+		t.Fatal("expected a missing message reference to be rejected")
+	}
+}
+
+func TestParseRatingAndCSV(t *testing.T) {
+	for value, want := range map[string]int{"up": RatingUp, "👍": RatingUp, "-1": RatingDown, "Down": RatingDown} {
+		got, err := ParseRating(value)
+		if err != nil || got != want {
+			t.Fatalf("ParseRating(%q) = %d, %v; want %d", value, got, err, want)
+		}
+	}
+	if _, err := ParseRating("meh"); err == nil {
+		t.Fatal("expected unknown rating to fail")
+	}
+
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, []Entry{{Channel: "websocket", SessionID: "s", MessageID: "msg-1", UserID: "alice", Rating: RatingDown, Comment: "a, b"}})
+	if err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	if !strings.Contains(buf.String(), `websocket,s,msg-1,alice,-1,"a, b",`) {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,515 @@
+// Package feeds polls RSS/Atom feeds and posts agent-written summaries of
+// new entries to a channel.
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/agent"
+	"nekobot/pkg/bus"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/message"
+	"nekobot/pkg/storage/ent"
+	"nekobot/pkg/storage/ent/feedentry"
+	"nekobot/pkg/storage/ent/feedsubscription"
+)
+
+const (
+	// DefaultIntervalMinutes is the poll interval of a subscription that does
+	// not set one.
+	DefaultIntervalMinutes = 60
+	// MinIntervalMinutes keeps subscriptions from hammering feed servers.
+	MinIntervalMinutes = 5
+
+	// maxEntriesPerSummary caps how many new entries one summary covers.
+	maxEntriesPerSummary = 10
+	maxSummaryChars      = 500
+	maxFeedBytes         = 5 << 20
+
+	tickerInterval = time.Minute
+)
+
+// DefaultPrompt asks for the summary when a subscription has no prompt.
+const DefaultPrompt = "Summarize the new entries of the feed below for a chat message. " +
+	"Use one short bullet per entry and keep each entry's link."
+
+// ErrNotFound is returned for an unknown subscription ID.
+var ErrNotFound = errors.New("feed subscription not found")
+
+// Subscription is a feed the agent summarizes into a channel session.
+type Subscription struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	URL             string     `json:"url"`
+	IntervalMinutes int        `json:"interval_minutes"`
+	Prompt          string     `json:"prompt"`     // Summarization prompt; DefaultPrompt when empty.
+	ChannelID       string     `json:"channel_id"` // Channel that delivers summaries, e.g. "telegram".
+	SessionID       string     `json:"session_id"` // Chat to post to, e.g. "telegram:123456789".
+	Enabled         bool       `json:"enabled"`
+	LastCheckedAt   *time.Time `json:"last_checked_at,omitempty"`
+	LastError       string     `json:"last_error"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// CheckResult reports one poll of a subscription.
+type CheckResult struct {
+	NewEntries int    `json:"new_entries"`
+	Summary    string `json:"summary,omitempty"`
+}
+
+// Manager stores subscriptions and polls the due ones.
+type Manager struct {
+	log        *logger.Logger
+	agent      *agent.Agent
+	bus        bus.Bus
+	client     *ent.Client
+	httpClient *http.Client
+
+	// summarize is replaced in tests.
+	summarize func(ctx context.Context, prompt string) (string, error)
+
+	checkMu  sync.Mutex
+	checking map[string]bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a feed manager.
+func New(log *logger.Logger, ag *agent.Agent, b bus.Bus, client *ent.Client) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		log:        log,
+		agent:      ag,
+		bus:        b,
+		client:     client,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		checking:   make(map[string]bool),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	m.summarize = m.chatAgent
+	return m
+}
+
+// Start starts polling subscriptions.
+func (m *Manager) Start() error {
+	if m.client == nil {
+		return fmt.Errorf("runtime ent client is nil")
+	}
+	m.log.Info("Starting feed watcher")
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(tickerInterval)
+		defer ticker.Stop()
+		for {
+			m.checkDue(m.ctx)
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops polling and waits for checks in flight.
+func (m *Manager) Stop() error {
+	m.log.Info("Stopping feed watcher")
+	m.cancel()
+	m.wg.Wait()
+	return nil
+}
+
+// checkDue polls every enabled subscription whose interval has passed.
+func (m *Manager) checkDue(ctx context.Context) {
+	items, err := m.client.FeedSubscription.Query().
+		Where(feedsubscription.EnabledEQ(true)).
+		All(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			m.log.Warn("Failed to list feed subscriptions", zap.Error(err))
+		}
+		return
+	}
+	now := time.Now()
+	for _, item := range items {
+		sub := fromEnt(item)
+		if sub.LastCheckedAt != nil && now.Sub(*sub.LastCheckedAt) < time.Duration(sub.IntervalMinutes)*time.Minute {
+			continue
+		}
+		if _, err := m.check(ctx, sub); err != nil && ctx.Err() == nil {
+			m.log.Warn("Feed check failed", zap.String("feed_id", sub.ID), zap.String("url", sub.URL), zap.Error(err))
+		}
+	}
+}
+
+// List returns all subscriptions, newest first.
+func (m *Manager) List(ctx context.Context) ([]Subscription, error) {
+	items, err := m.client.FeedSubscription.Query().
+		Order(ent.Desc(feedsubscription.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list feed subscriptions: %w", err)
+	}
+	subs := make([]Subscription, 0, len(items))
+	for _, item := range items {
+		subs = append(subs, fromEnt(item))
+	}
+	return subs, nil
+}
+
+// Get returns one subscription.
+func (m *Manager) Get(ctx context.Context, id string) (Subscription, error) {
+	item, err := m.client.FeedSubscription.Get(ctx, strings.TrimSpace(id))
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return Subscription{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return Subscription{}, fmt.Errorf("get feed subscription: %w", err)
+	}
+	return fromEnt(item), nil
+}
+
+// Create stores a new subscription. Entries already in the feed are marked
+// seen on the first check, so only later entries are summarized.
+func (m *Manager) Create(ctx context.Context, sub Subscription) (Subscription, error) {
+	sub, err := normalize(sub)
+	if err != nil {
+		return Subscription{}, err
+	}
+	item, err := m.client.FeedSubscription.Create().
+		SetName(sub.Name).
+		SetURL(sub.URL).
+		SetIntervalMinutes(sub.IntervalMinutes).
+		SetPrompt(sub.Prompt).
+		SetChannelID(sub.ChannelID).
+		SetSessionID(sub.SessionID).
+		SetEnabled(sub.Enabled).
+		Save(ctx)
+	if err != nil {
+		return Subscription{}, fmt.Errorf("create feed subscription: %w", err)
+	}
+	return fromEnt(item), nil
+}
+
+// Update replaces the editable fields of a subscription. Changing the URL
+// starts the feed over as if it were new.
+func (m *Manager) Update(ctx context.Context, id string, sub Subscription) (Subscription, error) {
+	existing, err := m.Get(ctx, id)
+	if err != nil {
+		return Subscription{}, err
+	}
+	sub, err = normalize(sub)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	update := m.client.FeedSubscription.UpdateOneID(existing.ID).
+		SetName(sub.Name).
+		SetURL(sub.URL).
+		SetIntervalMinutes(sub.IntervalMinutes).
+		SetPrompt(sub.Prompt).
+		SetChannelID(sub.ChannelID).
+		SetSessionID(sub.SessionID).
+		SetEnabled(sub.Enabled)
+	if sub.URL != existing.URL {
+		if _, err := m.client.FeedEntry.Delete().Where(feedentry.SubscriptionIDEQ(existing.ID)).Exec(ctx); err != nil {
+			return Subscription{}, fmt.Errorf("reset feed entries: %w", err)
+		}
+		update.ClearLastCheckedAt().SetLastError("")
+	}
+	item, err := update.Save(ctx)
+	if err != nil {
+		return Subscription{}, fmt.Errorf("update feed subscription: %w", err)
+	}
+	return fromEnt(item), nil
+}
+
+// Delete removes a subscription and its seen entries.
+func (m *Manager) Delete(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if _, err := m.client.FeedEntry.Delete().Where(feedentry.SubscriptionIDEQ(id)).Exec(ctx); err != nil {
+		return fmt.Errorf("delete feed entries: %w", err)
+	}
+	if err := m.client.FeedSubscription.DeleteOneID(id).Exec(ctx); err != nil {
+		if ent.IsNotFound(err) {
+			return fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return fmt.Errorf("delete feed subscription: %w", err)
+	}
+	return nil
+}
+
+// CheckNow polls a subscription immediately, regardless of its interval.
+func (m *Manager) CheckNow(ctx context.Context, id string) (CheckResult, error) {
+	sub, err := m.Get(ctx, id)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return m.check(ctx, sub)
+}
+
+// check fetches the feed, records unseen entries and, unless the feed was
+// never fetched before, posts a summary of them. The outcome is stored on the
+// subscription.
+func (m *Manager) check(ctx context.Context, sub Subscription) (CheckResult, error) {
+	if !m.beginCheck(sub.ID) {
+		return CheckResult{}, fmt.Errorf("feed %s is already being checked", sub.ID)
+	}
+	defer m.endCheck(sub.ID)
+
+	result, err := m.poll(ctx, sub)
+	errText := ""
+	if err != nil {
+		errText = err.Error()
+	}
+	if saveErr := m.client.FeedSubscription.UpdateOneID(sub.ID).
+		SetLastCheckedAt(time.Now()).
+		SetLastError(errText).
+		Exec(ctx); saveErr != nil && err == nil {
+		err = fmt.Errorf("save feed check: %w", saveErr)
+	}
+	return result, err
+}
+
+func (m *Manager) poll(ctx context.Context, sub Subscription) (CheckResult, error) {
+	items, err := m.fetch(ctx, sub.URL)
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	// The first successful fetch only marks what is already there as seen.
+	seeded, err := m.client.FeedEntry.Query().Where(feedentry.SubscriptionIDEQ(sub.ID)).Exist(ctx)
+	if err != nil {
+		return CheckResult{}, fmt.Errorf("query feed entries: %w", err)
+	}
+	fresh, err := m.recordNew(ctx, sub.ID, items)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	result := CheckResult{NewEntries: len(fresh)}
+	if len(fresh) == 0 || !seeded {
+		return result, nil
+	}
+
+	summary, err := m.summarize(ctx, buildPrompt(sub, fresh))
+	if err != nil {
+		return result, fmt.Errorf("summarizing feed: %w", err)
+	}
+	result.Summary = strings.TrimSpace(summary)
+	if result.Summary == "" {
+		return result, nil
+	}
+	if err := m.deliver(sub, result.Summary); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (m *Manager) fetch(ctx context.Context, feedURL string) ([]Item, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating feed request: %w", err)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+	req.Header.Set("User-Agent", "nekobot-feeds/1.0")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching feed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("feed returned status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes))
+	if err != nil {
+		return nil, fmt.Errorf("reading feed: %w", err)
+	}
+	return Parse(data)
+}
+
+// recordNew stores the entries not seen before and returns them, newest
+// first, capped at maxEntriesPerSummary.
+func (m *Manager) recordNew(ctx context.Context, subscriptionID string, items []Item) ([]Item, error) {
+	fresh := make([]Item, 0)
+	for _, item := range items {
+		exists, err := m.client.FeedEntry.Query().
+			Where(feedentry.SubscriptionIDEQ(subscriptionID), feedentry.EntryKeyEQ(item.Key)).
+			Exist(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("query feed entries: %w", err)
+		}
+		if exists {
+			continue
+		}
+		create := m.client.FeedEntry.Create().
+			SetSubscriptionID(subscriptionID).
+			SetEntryKey(item.Key).
+			SetTitle(item.Title).
+			SetLink(item.Link)
+		if !item.Published.IsZero() {
+			create.SetPublishedAt(item.Published)
+		}
+		if err := create.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("save feed entry: %w", err)
+		}
+		fresh = append(fresh, item)
+	}
+	if len(fresh) > maxEntriesPerSummary {
+		fresh = fresh[:maxEntriesPerSummary]
+	}
+	return fresh, nil
+}
+
+func (m *Manager) deliver(sub Subscription, summary string) error {
+	if m.bus == nil {
+		return fmt.Errorf("message bus is not available")
+	}
+	msg := &bus.Message{
+		ID:        "feed:" + uuid.NewString(),
+		ChannelID: sub.ChannelID,
+		SessionID: sub.SessionID,
+		Type:      bus.MessageTypeText,
+		Content:   summary,
+		Data: map[string]interface{}{
+			"source":    "feed",
+			"feed_id":   sub.ID,
+			"feed_name": sub.Name,
+		},
+		Timestamp: time.Now(),
+	}
+	if err := m.bus.SendOutbound(msg); err != nil {
+		return fmt.Errorf("delivering feed summary: %w", err)
+	}
+	return nil
+}
+
+func (m *Manager) chatAgent(ctx context.Context, prompt string) (response string, err error) {
+	if m.agent == nil {
+		return "", fmt.Errorf("agent is nil")
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("agent chat panic: %v", recovered)
+		}
+	}()
+	return m.agent.Chat(ctx, &simpleSession{}, prompt)
+}
+
+func (m *Manager) beginCheck(id string) bool {
+	m.checkMu.Lock()
+	defer m.checkMu.Unlock()
+	if m.checking[id] {
+		return false
+	}
+	m.checking[id] = true
+	return true
+}
+
+func (m *Manager) endCheck(id string) {
+	m.checkMu.Lock()
+	defer m.checkMu.Unlock()
+	delete(m.checking, id)
+}
+
+func buildPrompt(sub Subscription, items []Item) string {
+	var sb strings.Builder
+	sb.WriteString(firstNonEmpty(sub.Prompt, DefaultPrompt))
+	sb.WriteString("\n\nFeed: ")
+	sb.WriteString(sub.Name)
+	for i, item := range items {
+		fmt.Fprintf(&sb, "\n\n%d. %s", i+1, firstNonEmpty(item.Title, "(untitled)"))
+		if item.Link != "" {
+			sb.WriteString("\nLink: " + item.Link)
+		}
+		if !item.Published.IsZero() {
+			sb.WriteString("\nPublished: " + item.Published.Format(time.RFC3339))
+		}
+		if item.Summary != "" {
+			sb.WriteString("\n" + truncate(item.Summary, maxSummaryChars))
+		}
+	}
+	return sb.String()
+}
+
+func normalize(sub Subscription) (Subscription, error) {
+	sub.Name = strings.TrimSpace(sub.Name)
+	sub.URL = strings.TrimSpace(sub.URL)
+	sub.Prompt = strings.TrimSpace(sub.Prompt)
+	sub.ChannelID = strings.TrimSpace(sub.ChannelID)
+	sub.SessionID = strings.TrimSpace(sub.SessionID)
+
+	parsed, err := url.Parse(sub.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return Subscription{}, fmt.Errorf("url must be an http(s) feed address")
+	}
+	if sub.Name == "" {
+		sub.Name = parsed.Host
+	}
+	if sub.ChannelID == "" || sub.SessionID == "" {
+		return Subscription{}, fmt.Errorf("channel_id and session_id are required")
+	}
+	if sub.IntervalMinutes == 0 {
+		sub.IntervalMinutes = DefaultIntervalMinutes
+	}
+	if sub.IntervalMinutes < MinIntervalMinutes {
+		return Subscription{}, fmt.Errorf("interval_minutes must be at least %d", MinIntervalMinutes)
+	}
+	return sub, nil
+}
+
+func truncate(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit]) + "…"
+}
+
+func fromEnt(item *ent.FeedSubscription) Subscription {
+	return Subscription{
+		ID:              item.ID,
+		Name:            item.Name,
+		URL:             item.URL,
+		IntervalMinutes: item.IntervalMinutes,
+		Prompt:          item.Prompt,
+		ChannelID:       item.ChannelID,
+		SessionID:       item.SessionID,
+		Enabled:         item.Enabled,
+		LastCheckedAt:   item.LastCheckedAt,
+		LastError:       item.LastError,
+		CreatedAt:       item.CreatedAt,
+		UpdatedAt:       item.UpdatedAt,
+	}
+}
+
+// simpleSession holds the one-off conversation of a summary request.
+type simpleSession struct {
+	messages []message.Message
+}
+
+func (s *simpleSession) GetMessages() []message.Message {
+	return s.messages
+}
+
+func (s *simpleSession) AddMessage(msg message.Message) {
+	s.messages = append(s.messages, msg)
+}
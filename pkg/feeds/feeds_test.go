@@ -0,0 +1,229 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+const rssFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Example</title>
+%s
+</channel></rss>`
+
+const atomFixture = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><title>Example</title>
+<entry><id>tag:example.org,2026:2</id><title>Second &amp; last</title>
+<link rel="alternate" href="https://example.org/2"/><updated>2026-10-02T08:00:00Z</updated>
+<summary type="html">&lt;p&gt;Body &lt;b&gt;two&lt;/b&gt;&lt;/p&gt;</summary></entry>
+<entry><id>tag:example.org,2026:1</id><title>First</title>
+<link href="https://example.org/1"/><published>2026-10-01T08:00:00Z</published></entry>
+</feed>`
+
+func rssItem(guid, title string) string {
+	return fmt.Sprintf(`<item><guid>%s</guid><title>%s</title><link>https://example.org/%s</link>
+<description>&lt;p&gt;About %s&lt;/p&gt;</description><pubDate>Thu, 01 Oct 2026 08:00:00 +0000</pubDate></item>`,
+		guid, title, guid, title)
+}
+
+func TestParseReadsRSSAndAtom(t *testing.T) {
+	items, err := Parse([]byte(fmt.Sprintf(rssFixture, rssItem("a", "Alpha"))))
+	if err != nil {
+		t.Fatalf("parse rss: %v", err)
+	}
+	if len(items) != 1 || items[0].Key != "a" || items[0].Link != "https://example.org/a" ||
+		items[0].Summary != "About Alpha" || items[0].Published.IsZero() {
+		t.Fatalf("unexpected rss items %+v", items)
+	}
+
+	items, err = Parse([]byte(atomFixture))
+	if err != nil {
+		t.Fatalf("parse atom: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected two atom entries, got %+v", items)
+	}
+	if items[0].Title != "Second & last" || items[0].Link != "https://example.org/2" || items[0].Summary != "Body two" {
+		t.Fatalf("unexpected first atom entry %+v", items[0])
+	}
+	if items[1].Key != "tag:example.org,2026:1" || items[1].Published.IsZero() {
+		t.Fatalf("unexpected second atom entry %+v", items[1])
+	}
+
+	if _, err := Parse([]byte(`<html><body>nope</body></html>`)); err == nil {
+		t.Fatal("expected an error for a non-feed document")
+	}
+}
+
+func TestCheckSummarizesOnlyEntriesAddedAfterTheFirstFetch(t *testing.T) {
+	var mu sync.Mutex
+	body := fmt.Sprintf(rssFixture, rssItem("a", "Alpha"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	outbound := &stubBus{}
+	mgr := newTestManager(t, outbound)
+	var prompts []string
+	mgr.summarize = func(ctx context.Context, prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		return "- Beta: https://example.org/b", nil
+	}
+
+	ctx := context.Background()
+	sub, err := mgr.Create(ctx, Subscription{
+		URL:       server.URL,
+		ChannelID: "telegram",
+		SessionID: "telegram:42",
+		Enabled:   true,
+	})
+	if err != nil {
+		t.Fatalf("create subscription: %v", err)
+	}
+	if sub.IntervalMinutes != DefaultIntervalMinutes || sub.Name == "" {
+		t.Fatalf("expected defaults to be filled, got %+v", sub)
+	}
+
+	result, err := mgr.CheckNow(ctx, sub.ID)
+	if err != nil {
+		t.Fatalf("first check: %v", err)
+	}
+	if result.NewEntries != 1 || len(prompts) != 0 || len(outbound.outbound) != 0 {
+		t.Fatalf("expected the first fetch to only seed entries, got %+v prompts=%d", result, len(prompts))
+	}
+
+	mu.Lock()
+	body = fmt.Sprintf(rssFixture, rssItem("b", "Beta")+rssItem("a", "Alpha"))
+	mu.Unlock()
+
+	result, err = mgr.CheckNow(ctx, sub.ID)
+	if err != nil {
+		t.Fatalf("second check: %v", err)
+	}
+	if result.NewEntries != 1 || len(prompts) != 1 {
+		t.Fatalf("expected one new entry to be summarized, got %+v prompts=%d", result, len(prompts))
+	}
+	if !strings.Contains(prompts[0], "Beta") || strings.Contains(prompts[0], "Alpha") {
+		t.Fatalf("expected only the new entry in the prompt, got %q", prompts[0])
+	}
+	if len(outbound.outbound) != 1 {
+		t.Fatalf("expected one delivered summary, got %d", len(outbound.outbound))
+	}
+	msg := outbound.outbound[0]
+	if msg.ChannelID != "telegram" || msg.SessionID != "telegram:42" || msg.Content != "- Beta: https://example.org/b" {
+		t.Fatalf("unexpected delivered message %+v", msg)
+	}
+
+	if _, err := mgr.CheckNow(ctx, sub.ID); err != nil {
+		t.Fatalf("third check: %v", err)
+	}
+	if len(prompts) != 1 {
+		t.Fatalf("expected seen entries not to be summarized again, got %d prompts", len(prompts))
+	}
+
+	stored, err := mgr.Get(ctx, sub.ID)
+	if err != nil {
+		t.Fatalf("get subscription: %v", err)
+	}
+	if stored.LastCheckedAt == nil || stored.LastError != "" {
+		t.Fatalf("expected a recorded successful check, got %+v", stored)
+	}
+}
+
+func TestSubscriptionCRUDValidatesInput(t *testing.T) {
+	mgr := newTestManager(t, &stubBus{})
+	ctx := context.Background()
+
+	if _, err := mgr.Create(ctx, Subscription{URL: "ftp://example.org/feed", ChannelID: "telegram", SessionID: "telegram:1"}); err == nil {
+		t.Fatal("expected non-http url to be rejected")
+	}
+	if _, err := mgr.Create(ctx, Subscription{URL: "https://example.org/feed"}); err == nil {
+		t.Fatal("expected missing target to be rejected")
+	}
+	if _, err := mgr.Create(ctx, Subscription{URL: "https://example.org/feed", ChannelID: "telegram", SessionID: "telegram:1", IntervalMinutes: 1}); err == nil {
+		t.Fatal("expected too short interval to be rejected")
+	}
+
+	sub, err := mgr.Create(ctx, Subscription{Name: "News", URL: "https://example.org/feed", ChannelID: "telegram", SessionID: "telegram:1", Enabled: true})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	sub.IntervalMinutes = 30
+	sub.Enabled = false
+	updated, err := mgr.Update(ctx, sub.ID, sub)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.IntervalMinutes != 30 || updated.Enabled {
+		t.Fatalf("unexpected updated subscription %+v", updated)
+	}
+
+	subs, err := mgr.List(ctx)
+	if err != nil || len(subs) != 1 {
+		t.Fatalf("expected one subscription, got %+v err=%v", subs, err)
+	}
+	if err := mgr.Delete(ctx, sub.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := mgr.Get(ctx, sub.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func newTestManager(t *testing.T, b bus.Bus) *Manager {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		t.Fatalf("open runtime ent client: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	if err := config.EnsureRuntimeEntSchema(client); err != nil {
+		t.Fatalf("ensure runtime schema: %v", err)
+	}
+
+	logCfg := logger.DefaultConfig()
+	logCfg.OutputPath = ""
+	log, err := logger.New(logCfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return New(log, nil, b, client)
+}
+
+type stubBus struct {
+	outbound []*bus.Message
+}
+
+func (b *stubBus) Start() error                                                  { return nil }
+func (b *stubBus) Stop() error                                                   { return nil }
+func (b *stubBus) RegisterInboundHandler(channelID string, handler bus.Handler)  {}
+func (b *stubBus) UnregisterInboundHandlers(channelID string)                    {}
+func (b *stubBus) RegisterOutboundHandler(channelID string, handler bus.Handler) {}
+func (b *stubBus) UnregisterOutboundHandlers(channelID string)                   {}
+func (b *stubBus) RegisterHandler(channelID string, handler bus.Handler)         {}
+func (b *stubBus) UnregisterHandlers(channelID string)                           {}
+func (b *stubBus) SendInbound(msg *bus.Message) error                            { return nil }
+func (b *stubBus) SendOutbound(msg *bus.Message) error {
+	b.outbound = append(b.outbound, msg)
+	return nil
+}
+func (b *stubBus) GetMetrics() map[string]uint64 { return map[string]uint64{} }
@@ -0,0 +1,39 @@
+package feeds
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"nekobot/pkg/agent"
+	"nekobot/pkg/bus"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/storage/ent"
+)
+
+// Module is the fx module for feeds.
+var Module = fx.Module("feeds",
+	fx.Provide(NewManager),
+)
+
+// NewManager creates a new feed manager for fx.
+func NewManager(
+	lc fx.Lifecycle,
+	log *logger.Logger,
+	ag *agent.Agent,
+	b bus.Bus,
+	client *ent.Client,
+) *Manager {
+	manager := New(log, ag, b, client)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return manager.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			return manager.Stop()
+		},
+	})
+
+	return manager
+}
@@ -0,0 +1,157 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Item is one entry of a fetched feed.
+type Item struct {
+	Key       string
+	Title     string
+	Link      string
+	Summary   string
+	Published time.Time
+}
+
+type rssDocument struct {
+	Channel struct {
+		Items []struct {
+			GUID        string `xml:"guid"`
+			Title       string `xml:"title"`
+			Link        string `xml:"link"`
+			Description string `xml:"description"`
+			PubDate     string `xml:"pubDate"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+type atomDocument struct {
+	Entries []struct {
+		ID    string `xml:"id"`
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Summary   string `xml:"summary"`
+		Content   string `xml:"content"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// Parse reads an RSS 2.0 or Atom document. Items are returned in document
+// order, which for both formats is normally newest first.
+func Parse(data []byte) ([]Item, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("reading feed: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(start.Name.Local) {
+		case "rss":
+			var doc rssDocument
+			if err := decoder.DecodeElement(&doc, &start); err != nil {
+				return nil, fmt.Errorf("decoding rss feed: %w", err)
+			}
+			return rssItems(doc), nil
+		case "feed":
+			var doc atomDocument
+			if err := decoder.DecodeElement(&doc, &start); err != nil {
+				return nil, fmt.Errorf("decoding atom feed: %w", err)
+			}
+			return atomItems(doc), nil
+		default:
+			return nil, fmt.Errorf("unsupported feed format <%s>", start.Name.Local)
+		}
+	}
+}
+
+func rssItems(doc rssDocument) []Item {
+	items := make([]Item, 0, len(doc.Channel.Items))
+	for _, raw := range doc.Channel.Items {
+		item := Item{
+			Title:     cleanText(raw.Title),
+			Link:      strings.TrimSpace(raw.Link),
+			Summary:   cleanText(raw.Description),
+			Published: parseTime(raw.PubDate),
+		}
+		item.Key = firstNonEmpty(strings.TrimSpace(raw.GUID), item.Link, item.Title)
+		if item.Key != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func atomItems(doc atomDocument) []Item {
+	items := make([]Item, 0, len(doc.Entries))
+	for _, raw := range doc.Entries {
+		item := Item{
+			Title:     cleanText(raw.Title),
+			Summary:   cleanText(firstNonEmpty(raw.Summary, raw.Content)),
+			Published: parseTime(firstNonEmpty(raw.Published, raw.Updated)),
+		}
+		for _, link := range raw.Links {
+			if link.Rel == "" || link.Rel == "alternate" {
+				item.Link = strings.TrimSpace(link.Href)
+				break
+			}
+		}
+		item.Key = firstNonEmpty(strings.TrimSpace(raw.ID), item.Link, item.Title)
+		if item.Key != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// cleanText strips markup from HTML descriptions and collapses whitespace.
+func cleanText(value string) string {
+	value = html.UnescapeString(tagPattern.ReplaceAllString(value, " "))
+	return strings.Join(strings.Fields(value), " ")
+}
+
+var timeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2006-01-02T15:04:05Z0700",
+}
+
+func parseTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range timeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
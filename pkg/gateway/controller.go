@@ -9,6 +9,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"nekobot/pkg/bus"
 	"nekobot/pkg/commands"
 	"nekobot/pkg/config"
 	"nekobot/pkg/logger"
@@ -19,14 +20,17 @@ type Controller struct {
 	config *config.Config
 	loader *config.Loader
 	log    *logger.Logger
+	events bus.Bus
 }
 
-// NewController creates a new gateway controller.
-func NewController(cfg *config.Config, loader *config.Loader, log *logger.Logger) *Controller {
+// NewController creates a new gateway controller. Reloads that change the
+// config are announced on events when it is non-nil.
+func NewController(cfg *config.Config, loader *config.Loader, log *logger.Logger, events bus.Bus) *Controller {
 	return &Controller{
 		config: cfg,
 		loader: loader,
 		log:    log,
+		events: events,
 	}
 }
 
@@ -68,23 +72,15 @@ func (c *Controller) Restart() error {
 func (c *Controller) ReloadConfig() error {
 	c.log.Info("Configuration reload requested")
 
-	fresh, err := c.loader.Load("")
+	changed, err := c.loader.Reload(c.config)
 	if err != nil {
-		return fmt.Errorf("loading config: %w", err)
+		return err
 	}
-
-	if err := config.ApplyDatabaseOverrides(fresh); err != nil {
-		return fmt.Errorf("applying database overrides: %w", err)
-	}
-
-	if err := config.ValidateConfig(fresh); err != nil {
-		return fmt.Errorf("validating config: %w", err)
+	if err := bus.PublishConfigChanged(c.events, changed); err != nil {
+		c.log.Warn("Failed to publish config change", zap.Error(err))
 	}
 
-	// Apply the reloaded values into the live config.
-	c.config.ApplyFrom(fresh)
-
-	c.log.Info("Configuration reloaded successfully")
+	c.log.Info("Configuration reloaded successfully", zap.Strings("changed", changed))
 	return nil
 }
 
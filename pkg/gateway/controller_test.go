@@ -44,7 +44,7 @@ func TestControllerReloadConfigAppliesRuntimeOverrides(t *testing.T) {
 	live.Storage.DBDir = seed.Storage.DBDir
 	live.Agents.Defaults.Workspace = seed.Agents.Defaults.Workspace
 	log := newGatewayTestLogger(t)
-	ctrl := NewController(live, config.NewLoader(), log)
+	ctrl := NewController(live, config.NewLoader(), log, nil)
 	t.Setenv(config.ConfigPathEnv, cfgPath)
 
 	if err := ctrl.ReloadConfig(); err != nil {
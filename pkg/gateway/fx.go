@@ -8,6 +8,7 @@ import (
 	"go.uber.org/zap"
 
 	"nekobot/pkg/audit"
+	"nekobot/pkg/bus"
 	"nekobot/pkg/channels"
 	"nekobot/pkg/commands"
 	"nekobot/pkg/config"
@@ -23,10 +24,11 @@ var Module = fx.Module("gateway",
 	fx.Provide(provideGatewayController),
 	fx.Invoke(attachCollaborationManagers),
 	fx.Invoke(registerLifecycle),
+	fx.Invoke(registerConfigWatcher),
 )
 
-func provideGatewayController(cfg *config.Config, loader *config.Loader, log *logger.Logger) commands.GatewayController {
-	return NewController(cfg, loader, log)
+func provideGatewayController(cfg *config.Config, loader *config.Loader, log *logger.Logger, messageBus bus.Bus) commands.GatewayController {
+	return NewController(cfg, loader, log, messageBus)
 }
 
 func attachCollaborationManagers(
@@ -65,3 +67,31 @@ func registerLifecycle(lc fx.Lifecycle, s *Server, cfg *config.Config, log *logg
 		},
 	})
 }
+
+// registerConfigWatcher reloads the config file when it changes on disk and
+// announces the changed sections so subscribers can apply them live.
+func registerConfigWatcher(lc fx.Lifecycle, cfg *config.Config, loader *config.Loader, messageBus bus.Bus, log *logger.Logger) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			err := loader.Watch(ctx, cfg, func(changed []string, err error) {
+				if err != nil {
+					log.Warn("Config reload rejected, keeping the running config", zap.Error(err))
+					return
+				}
+				log.Info("Configuration reloaded from disk", zap.Strings("changed", changed))
+				if err := bus.PublishConfigChanged(messageBus, changed); err != nil {
+					log.Warn("Failed to publish config change", zap.Error(err))
+				}
+			})
+			if err != nil {
+				log.Warn("Config hot-reload disabled", zap.Error(err))
+			}
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
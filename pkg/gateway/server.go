@@ -27,6 +27,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"nekobot/pkg/agent"
+	"nekobot/pkg/apikeys"
 	"nekobot/pkg/approval"
 	"nekobot/pkg/audit"
 	"nekobot/pkg/bus"
@@ -38,7 +39,9 @@ import (
 	"nekobot/pkg/idempotency"
 	"nekobot/pkg/inboundrouter"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/ownership"
 	"nekobot/pkg/process"
+	"nekobot/pkg/providers"
 	"nekobot/pkg/runs"
 	"nekobot/pkg/runtimeagents"
 	"nekobot/pkg/session"
@@ -48,6 +51,7 @@ import (
 	"nekobot/pkg/tasks"
 	"nekobot/pkg/toolsessions"
 	"nekobot/pkg/version"
+	"nekobot/pkg/webhooks"
 
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
@@ -68,6 +72,9 @@ type WSMessage struct {
 	MessageID string `json:"message_id,omitempty"` // Unique message ID
 	Timestamp int64  `json:"timestamp,omitempty"`  // Unix timestamp
 	RuntimeID string `json:"runtime_id,omitempty"` // Explicit runtime selection
+
+	// ResponseFormat asks for a JSON reply, e.g. {"type":"json_schema","schema":{...}}.
+	ResponseFormat *providers.ResponseFormat `json:"response_format,omitempty"`
 }
 
 type websocketRouter interface {
@@ -85,6 +92,8 @@ type Client struct {
 	session            agent.SessionInterface
 	userID             string
 	username           string
+	tenantID           string
+	tenantSlug         string
 	connectedAt        time.Time
 	remoteAddr         string
 	sessionSource      string
@@ -141,9 +150,22 @@ type connectionStatus struct {
 }
 
 type authContext struct {
-	userID   string
-	username string
-	role     string
+	userID     string
+	username   string
+	role       string
+	tenantID   string
+	tenantSlug string
+}
+
+// inTenant reports whether a connection of tenantID is in the caller's
+// tenant, following ownership.AuthContext.
+func (a *authContext) inTenant(tenantID string) bool {
+	return ownership.AuthContext{
+		UserID:     a.userID,
+		TenantID:   a.tenantID,
+		TenantSlug: a.tenantSlug,
+		Role:       strings.ToLower(strings.TrimSpace(a.role)),
+	}.InTenant(tenantID)
 }
 
 type gatewayControlPlaneScope string
@@ -329,6 +351,9 @@ func (s *Server) setupRoutes() {
 	mux.HandleFunc("POST /api/v1/approvals/{id}/approve", s.handleApproveRequest)
 	mux.HandleFunc("POST /api/v1/approvals/{id}/deny", s.handleDenyRequest)
 
+	// Named webhook ingestion, authenticated by each hook's own secret.
+	mux.Handle("POST /hooks/{name}", webhooks.NewHandler(s.logger, s.config, s.bus, s.runWebhookPrompt))
+
 	// Health check
 	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -343,6 +368,18 @@ func (s *Server) setupRoutes() {
 	s.mux = mux
 }
 
+// runWebhookPrompt continues a hook's conversation with the agent.
+func (s *Server) runWebhookPrompt(ctx context.Context, sessionID, prompt string) (string, error) {
+	if s.agent == nil || s.sessionMgr == nil {
+		return "", fmt.Errorf("agent runtime not available")
+	}
+	sess, err := s.sessionMgr.GetWithSource(sessionID, session.SourceGateway)
+	if err != nil {
+		return "", fmt.Errorf("load webhook session: %w", err)
+	}
+	return s.agent.Chat(ctx, sess, prompt)
+}
+
 // Start starts the gateway server.
 func (s *Server) Start() error {
 	addr := fmt.Sprintf("%s:%d", s.config.Gateway.Host, s.config.Gateway.Port)
@@ -524,6 +561,8 @@ func (s *Server) handleWSChat(w http.ResponseWriter, r *http.Request) {
 		session:            sess,
 		userID:             authCtx.userID,
 		username:           authCtx.username,
+		tenantID:           authCtx.tenantID,
+		tenantSlug:         authCtx.tenantSlug,
 		connectedAt:        time.Now().UTC(),
 		remoteAddr:         r.RemoteAddr,
 		sessionSource:      classifyGatewaySessionSource(sess, requestedSessionID),
@@ -714,13 +753,30 @@ func (s *Server) processMessage(client *Client, wsMsg WSMessage) {
 		return
 	}
 
+	format, err := wsMsg.ResponseFormat.Normalize()
+	if err != nil {
+		s.sendError(client, err.Error())
+		return
+	}
+
+	// Turns run in the workspace of the client's tenant.
+	turnCtx := agent.WithTenant(context.Background(), client.tenantSlug)
 	response := ""
 	routerHandled := false
-	if s.router != nil {
+	if format != nil {
+		// Structured replies come from the built-in agent; runtime routes
+		// only speak free text.
+		output, err := s.agent.ChatWithResponseFormat(turnCtx, client.session, wsMsg.Content, format)
+		if err != nil {
+			s.sendError(client, fmt.Sprintf("agent error: %v", err))
+			return
+		}
+		response = string(output)
+	} else if s.router != nil {
 		routerHandled = true
 		var err error
 		response, _, err = s.router.ChatWebsocket(
-			context.Background(),
+			turnCtx,
 			client.userID,
 			client.username,
 			activeSessionID,
@@ -751,7 +807,7 @@ func (s *Server) processMessage(client *Client, wsMsg WSMessage) {
 			s.logger.Warn("Failed to publish inbound bus message", zap.Error(err))
 		}
 		var err error
-		response, err = s.agent.Chat(context.Background(), client.session, wsMsg.Content)
+		response, err = s.agent.Chat(turnCtx, client.session, wsMsg.Content)
 		if err != nil {
 			s.sendError(client, fmt.Sprintf("agent error: %v", err))
 			return
@@ -1142,7 +1198,8 @@ func (s *Server) handleGetApprovals(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleApproveRequest(w http.ResponseWriter, r *http.Request) {
-	if _, ok := s.requireAuthenticatedAPI(w, r, gatewayControlPlaneScopeManage); !ok {
+	authCtx, ok := s.requireAuthenticatedAPI(w, r, gatewayControlPlaneScopeManage)
+	if !ok {
 		return
 	}
 	if s.approval == nil {
@@ -1151,8 +1208,16 @@ func (s *Server) handleApproveRequest(w http.ResponseWriter, r *http.Request) {
 	}
 	id := strings.TrimSpace(r.PathValue("id"))
 	req, _ := s.approval.GetRequest(id)
-	if err := s.approval.Approve(id); err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusNotFound)
+	if status, msg := gatewayApprovalDeciderError(authCtx, req); status != 0 {
+		http.Error(w, fmt.Sprintf(`{"error":%q}`, msg), status)
+		return
+	}
+	if err := s.approval.ApproveAs(id, authCtx.username); err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, approval.ErrSelfApproval) {
+			status = http.StatusForbidden
+		}
+		http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), status)
 		return
 	}
 	if req != nil && isGatewayExternalAgentApprovalRequest(req) {
@@ -1199,7 +1264,8 @@ func (s *Server) handleApproveRequest(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleDenyRequest(w http.ResponseWriter, r *http.Request) {
-	if _, ok := s.requireAuthenticatedAPI(w, r, gatewayControlPlaneScopeManage); !ok {
+	authCtx, ok := s.requireAuthenticatedAPI(w, r, gatewayControlPlaneScopeManage)
+	if !ok {
 		return
 	}
 	if s.approval == nil {
@@ -1215,7 +1281,11 @@ func (s *Server) handleDenyRequest(w http.ResponseWriter, r *http.Request) {
 	}
 	id := strings.TrimSpace(r.PathValue("id"))
 	req, _ := s.approval.GetRequest(id)
-	if err := s.approval.Deny(id, body.Reason); err != nil {
+	if status, msg := gatewayApprovalDeciderError(authCtx, req); status != 0 {
+		http.Error(w, fmt.Sprintf(`{"error":%q}`, msg), status)
+		return
+	}
+	if err := s.approval.DenyAs(id, authCtx.username, body.Reason); err != nil {
 		http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusNotFound)
 		return
 	}
@@ -1236,6 +1306,27 @@ func (s *Server) handleDenyRequest(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// gatewayApprovalDeciderError returns the status and message refusing the
+// caller's decision on req, or 0 when they may decide it. Requests acting
+// for a person, such as tool session spawns, need an admin; the approval
+// manager runs them once approved.
+func gatewayApprovalDeciderError(authCtx *authContext, req *approval.Request) (int, string) {
+	if req == nil {
+		return 0, ""
+	}
+	if req.Decision != approval.Pending {
+		return http.StatusConflict, fmt.Sprintf("request %s is already %s", req.ID, req.Decision)
+	}
+	if req.RequestedBy() == "" {
+		return 0, ""
+	}
+	switch strings.ToLower(strings.TrimSpace(authCtx.role)) {
+	case "admin", "owner":
+		return 0, ""
+	}
+	return http.StatusForbidden, "deciding this request requires an admin"
+}
+
 func (s *Server) currentSessionRuntimeState(sessionID string) *tasks.SessionState {
 	if s == nil || s.taskStore == nil {
 		return nil
@@ -1284,7 +1375,7 @@ func (s *Server) authenticateDeleteConnections(r *http.Request) (*authContext, e
 		return nil, fmt.Errorf("unauthorized")
 	}
 	role := strings.ToLower(strings.TrimSpace(authCtx.role))
-	if role == "member" || role == "admin" || role == "owner" {
+	if role == "member" || role == "admin" || role == "owner" || role == "operator" {
 		return authCtx, nil
 	}
 	return nil, fmt.Errorf("forbidden")
@@ -1510,6 +1601,12 @@ func (s *Server) authenticateRequest(r *http.Request) (*authContext, error) {
 	if token == "" {
 		return nil, fmt.Errorf("no token provided")
 	}
+	if apikeys.IsKey(token) {
+		if r.URL.Query().Get("token") != "" {
+			return nil, fmt.Errorf("api keys must be sent in the Authorization header")
+		}
+		return s.authenticateAPIKey(r.Context(), token)
+	}
 
 	// Validate JWT using auth secret from database.
 	secret, secretErr := config.GetJWTSecret(s.entClient)
@@ -1550,18 +1647,50 @@ func (s *Server) authenticateRequest(r *http.Request) (*authContext, error) {
 		role = "admin"
 	}
 
+	tid, _ := claims["tid"].(string)
+	ts, _ := claims["ts"].(string)
+
 	return &authContext{
-		userID:   userID,
-		username: username,
-		role:     role,
+		userID:     userID,
+		username:   username,
+		role:       role,
+		tenantID:   strings.TrimSpace(tid),
+		tenantSlug: strings.TrimSpace(ts),
+	}, nil
+}
+
+// authenticateAPIKey resolves an API key with the gateway scope to its
+// user.
+func (s *Server) authenticateAPIKey(ctx context.Context, secret string) (*authContext, error) {
+	manager, err := apikeys.NewManager(s.entClient)
+	if err != nil {
+		return nil, fmt.Errorf("server not initialized")
+	}
+	key, err := manager.Authenticate(ctx, secret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	if !key.Allows(apikeys.ScopeGateway) {
+		return nil, fmt.Errorf("api key lacks the %s scope", apikeys.ScopeGateway)
+	}
+	profile, err := config.BuildAuthProfileByUserID(ctx, s.entClient, key.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	return &authContext{
+		userID:     profile.UserID,
+		username:   profile.Username,
+		role:       profile.Role,
+		tenantID:   profile.TenantID,
+		tenantSlug: profile.TenantSlug,
 	}, nil
 }
 
 func isGatewayControlPlaneRoleAllowed(role string, scope gatewayControlPlaneScope) bool {
 	switch strings.ToLower(strings.TrimSpace(role)) {
-	case "admin", "owner":
+	case "admin", "owner", "operator":
 		return true
-	case "member":
+	case "member", "viewer":
 		return scope == gatewayControlPlaneScopeRead
 	default:
 		return false
@@ -1569,11 +1698,11 @@ func isGatewayControlPlaneRoleAllowed(role string, scope gatewayControlPlaneScop
 }
 
 func gatewayControlPlaneCanReadConnection(authCtx *authContext, client *Client) bool {
-	if authCtx == nil || client == nil {
+	if authCtx == nil || client == nil || !authCtx.inTenant(client.tenantID) {
 		return false
 	}
 	switch strings.ToLower(strings.TrimSpace(authCtx.role)) {
-	case "admin", "owner":
+	case "admin", "owner", "operator", "viewer":
 		return true
 	case "member":
 		return strings.TrimSpace(authCtx.userID) != "" && strings.TrimSpace(authCtx.userID) == strings.TrimSpace(client.userID)
@@ -1583,11 +1712,11 @@ func gatewayControlPlaneCanReadConnection(authCtx *authContext, client *Client)
 }
 
 func gatewayControlPlaneCanManageConnection(authCtx *authContext, client *Client) bool {
-	if authCtx == nil || client == nil {
+	if authCtx == nil || client == nil || !authCtx.inTenant(client.tenantID) {
 		return false
 	}
 	switch strings.ToLower(strings.TrimSpace(authCtx.role)) {
-	case "admin", "owner":
+	case "admin", "owner", "operator":
 		return true
 	case "member":
 		return strings.TrimSpace(authCtx.userID) != "" && strings.TrimSpace(authCtx.userID) == strings.TrimSpace(client.userID)
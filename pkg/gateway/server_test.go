@@ -24,6 +24,7 @@ import (
 	daemonv1 "nekobot/gen/go/nekobot/daemon/v1"
 
 	"nekobot/pkg/agent"
+	"nekobot/pkg/apikeys"
 	"nekobot/pkg/approval"
 	"nekobot/pkg/bus"
 	"nekobot/pkg/config"
@@ -60,6 +61,7 @@ type stubGatewayRouter struct {
 	lastRuntimeID string
 	reply         string
 	err           error
+	calls         int
 }
 
 type stubGatewaySession struct {
@@ -87,6 +89,7 @@ func (s *stubGatewayRouter) ChatWebsocket(
 	userID, username, upstreamSessionID, content, runtimeID string,
 ) (string, map[string]any, error) {
 	s.lastRuntimeID = runtimeID
+	s.calls++
 	if s.err != nil {
 		return "", nil, s.err
 	}
@@ -1554,7 +1557,7 @@ func TestResolveExternalAgentSessionEndpointReturnsPendingApprovalForManualMode(
 func TestResolveExternalAgentSessionEndpointRejectsDeniedByApprovalMode(t *testing.T) {
 	s, token := newAuthedTestServer(t)
 	approvalMgr := approval.NewManager(approval.Config{Mode: approval.ModePrompt})
-	approvalMgr.PromptFunc = func(req *approval.Request) (bool, error) {
+	approvalMgr.PromptFunc = func(_ context.Context, req *approval.Request) (bool, error) {
 		return false, nil
 	}
 	s.approval = approvalMgr
@@ -2105,6 +2108,47 @@ func TestGatewayAuthenticateRequestAllowsMemberRoleForWebsocketPath(t *testing.T
 	}
 }
 
+func TestGatewayAuthenticateRequestAcceptsScopedAPIKeys(t *testing.T) {
+	s, _ := newAuthedTestServer(t)
+	profile, err := config.BuildAuthProfileByUsername(t.Context(), s.entClient, "admin")
+	if err != nil {
+		t.Fatalf("load admin profile: %v", err)
+	}
+	keys, err := apikeys.NewManager(s.entClient)
+	if err != nil {
+		t.Fatalf("new api key manager: %v", err)
+	}
+	_, gatewayKey, err := keys.Create(t.Context(), apikeys.CreateInput{Name: "ci", UserID: profile.UserID, Scopes: []string{apikeys.ScopeGateway}})
+	if err != nil {
+		t.Fatalf("create gateway key: %v", err)
+	}
+	_, readKey, err := keys.Create(t.Context(), apikeys.CreateInput{Name: "dashboard", UserID: profile.UserID, Scopes: []string{apikeys.ScopeRead}})
+	if err != nil {
+		t.Fatalf("create read key: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/ws/chat", nil)
+	req.Header.Set("Authorization", "Bearer "+gatewayKey)
+	authCtx, err := s.authenticateRequest(req)
+	if err != nil {
+		t.Fatalf("expected gateway key to authenticate, got %v", err)
+	}
+	if authCtx.userID != profile.UserID || authCtx.username != "admin" || authCtx.role != "owner" {
+		t.Fatalf("expected key to act as admin, got %+v", authCtx)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/ws/chat", nil)
+	req.Header.Set("Authorization", "Bearer "+readKey)
+	if _, err := s.authenticateRequest(req); err == nil {
+		t.Fatalf("expected key without gateway scope to be rejected")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/ws/chat?token="+gatewayKey, nil)
+	if _, err := s.authenticateRequest(req); err == nil {
+		t.Fatalf("expected api key in query string to be rejected")
+	}
+}
+
 func TestDeleteConnectionEndpointRemovesClient(t *testing.T) {
 	s, token := newAuthedTestServer(t)
 
@@ -3230,6 +3274,75 @@ func TestProcessMessageUsesPairedSessionIDForRouterAndResponse(t *testing.T) {
 	}
 }
 
+func TestProcessMessageAnswersResponseFormatWithStructuredOutput(t *testing.T) {
+	var sentFormat atomic.Value
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		sentFormat.Store(fmt.Sprint(payload["response_format"]))
+		_, _ = w.Write([]byte(`{"id":"c1","model":"gpt-test","choices":[{"index":0,"message":{"role":"assistant","content":"{\"city\":\"Paris\"}"},"finish_reason":"stop"}]}`))
+	}))
+	defer provider.Close()
+
+	s := newTestServer(t)
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Agents.Defaults.Provider = "structured"
+	cfg.Agents.Defaults.Model = "gpt-test"
+	cfg.Providers = []config.ProviderProfile{{
+		Name:         "structured",
+		ProviderKind: "openai",
+		APIKey:       "test-key",
+		APIBase:      provider.URL,
+		Models:       []string{"gpt-test"},
+		DefaultModel: "gpt-test",
+	}}
+	ag, err := agent.New(cfg, s.logger, nil, nil, approval.NewManager(approval.Config{Mode: approval.ModeAuto}), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("new agent: %v", err)
+	}
+	s.agent = ag
+	router := &stubGatewayRouter{reply: "router reply"}
+	s.router = router
+
+	sess, err := s.sessionMgr.GetWithSource("structured-session", session.SourceGateway)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+	client := &Client{id: "structured-session", send: make(chan []byte, 1), session: sess}
+
+	s.processMessage(client, WSMessage{
+		Type:    "message",
+		Content: "Where is the Louvre?",
+		ResponseFormat: &providers.ResponseFormat{
+			Type:   "json_schema",
+			Schema: map[string]interface{}{"type": "object", "required": []interface{}{"city"}},
+		},
+	})
+
+	var msg WSMessage
+	if err := json.Unmarshal(<-client.send, &msg); err != nil {
+		t.Fatalf("unmarshal ws message: %v", err)
+	}
+	if msg.Type != "message" || msg.Content != `{"city":"Paris"}` {
+		t.Fatalf("expected the structured reply, got %+v", msg)
+	}
+	if format, _ := sentFormat.Load().(string); !strings.Contains(format, "json_schema") {
+		t.Fatalf("expected response_format to reach the provider, got %q", format)
+	}
+	if router.calls != 0 {
+		t.Fatalf("expected the runtime router to be bypassed, got %d calls", router.calls)
+	}
+
+	s.processMessage(client, WSMessage{Type: "message", Content: "hi", ResponseFormat: &providers.ResponseFormat{Type: "xml"}})
+	if err := json.Unmarshal(<-client.send, &msg); err != nil {
+		t.Fatalf("unmarshal ws message: %v", err)
+	}
+	if msg.Type != "error" || !strings.Contains(msg.Content, "unsupported response_format") {
+		t.Fatalf("expected an unsupported format to be rejected, got %+v", msg)
+	}
+}
+
 func TestProcessMessageRejectsMismatchedInboundSessionID(t *testing.T) {
 	s := newTestServer(t)
 	router := &stubGatewayRouter{reply: "router reply"}
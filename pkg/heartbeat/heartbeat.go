@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -188,6 +190,8 @@ func (s *Service) executeHeartbeat(ctx context.Context) {
 	}
 
 	// Execute each task
+	results := make([]string, 0, len(tasks))
+	failed := 0
 	for i, task := range tasks {
 		s.log.Debug("Executing heartbeat task",
 			zap.Int("task_num", i+1),
@@ -195,15 +199,19 @@ func (s *Service) executeHeartbeat(ctx context.Context) {
 
 		// Execute task with timeout
 		taskCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
-		_, err := s.agent.Chat(taskCtx, sess, task.Prompt)
+		reply, err := s.agent.Chat(taskCtx, sess, task.Prompt)
 		cancel()
 
 		if err != nil {
 			s.log.Error("Heartbeat task failed",
 				zap.String("task_name", task.Name),
 				zap.Error(err))
+			failed++
+			results = append(results, fmt.Sprintf("%s failed: %v", task.Name, err))
 			// Continue with other tasks
+			continue
 		}
+		results = append(results, fmt.Sprintf("%s: %s", task.Name, strings.TrimSpace(reply)))
 	}
 
 	duration := time.Since(start)
@@ -212,6 +220,19 @@ func (s *Service) executeHeartbeat(ctx context.Context) {
 		zap.Int("tasks", len(tasks)))
 
 	s.updateState(start, nil)
+	s.publishResult(results, failed)
+}
+
+// publishResult reports the cycle's task replies as a heartbeat.result event.
+func (s *Service) publishResult(results []string, failed int) {
+	content := "Heartbeat completed.\n\n" + strings.Join(results, "\n\n")
+	fields := map[string]string{
+		"tasks":  strconv.Itoa(len(results)),
+		"failed": strconv.Itoa(failed),
+	}
+	if err := bus.PublishEvent(s.bus, bus.EventHeartbeatResult, sessionKey, content, fields); err != nil {
+		s.log.Debug("Failed to publish heartbeat result", zap.Error(err))
+	}
 }
 
 // loadTasks loads tasks from HEARTBEAT.md.
@@ -7,8 +7,8 @@ import (
 	"go.uber.org/zap"
 
 	"nekobot/pkg/agent"
-	"nekobot/pkg/channelaccounts"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/userprefs"
 )
 
 // Module provides the unified inbound router.
@@ -17,25 +17,32 @@ var Module = fx.Module("inboundrouter",
 		return ag
 	}),
 	fx.Provide(New),
+	fx.Invoke(registerIdentityLinks),
 	fx.Invoke(registerLifecycle),
 )
 
-func registerLifecycle(lc fx.Lifecycle, router *Router, accounts *channelaccounts.Manager, log *logger.Logger) {
+type identityLinksParams struct {
+	fx.In
+
+	Router *Router
+	Prefs  *userprefs.Manager `optional:"true"`
+}
+
+func registerIdentityLinks(p identityLinksParams) {
+	if p.Prefs != nil {
+		p.Router.SetIdentityLinks(p.Prefs)
+	}
+}
+
+func registerLifecycle(lc fx.Lifecycle, router *Router, log *logger.Logger) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			accountList, err := accounts.List(ctx)
+			count, err := router.SyncChannels(ctx)
 			if err != nil {
 				return err
 			}
-			for _, account := range accountList {
-				if !account.Enabled {
-					continue
-				}
-				router.RegisterChannel(account.ChannelType + ":" + account.AccountKey)
-			}
-			router.RegisterChannel("websocket")
 			log.Info("Inbound router registered",
-				zap.Int("channel_count", len(accountList)+1))
+				zap.Int("channel_count", count))
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
@@ -17,6 +17,7 @@ import (
 	"nekobot/pkg/logger"
 	"nekobot/pkg/runtimeagents"
 	"nekobot/pkg/session"
+	"nekobot/pkg/userprefs"
 )
 
 const (
@@ -33,6 +34,7 @@ type Router struct {
 	accounts    *channelaccounts.Manager
 	bindings    *accountbindings.Manager
 	runtimes    *runtimeagents.Manager
+	links       *userprefs.Manager
 	mu          sync.Mutex
 	channelKeys []string
 }
@@ -94,6 +96,12 @@ func New(
 	}, nil
 }
 
+// SetIdentityLinks makes direct messages from accounts linked with /link
+// share the session of their linked identity.
+func (r *Router) SetIdentityLinks(links *userprefs.Manager) {
+	r.links = links
+}
+
 // RegisterChannel registers one inbound channel identifier with the bus.
 func (r *Router) RegisterChannel(channelID string) {
 	channelID = strings.TrimSpace(channelID)
@@ -151,6 +159,43 @@ func (r *Router) UnregisterAll() {
 	r.channelKeys = nil
 }
 
+// SyncChannels registers the websocket channel and every enabled channel
+// account, and unregisters channels whose account was removed or disabled.
+// It returns the number of registered channels.
+func (r *Router) SyncChannels(ctx context.Context) (int, error) {
+	accountList, err := r.accounts.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	wanted := []string{"websocket"}
+	for _, account := range accountList {
+		if account.Enabled {
+			wanted = append(wanted, account.ChannelType+":"+account.AccountKey)
+		}
+	}
+
+	r.mu.Lock()
+	registered := make(map[string]bool, len(r.channelKeys))
+	for _, channelID := range r.channelKeys {
+		registered[channelID] = true
+	}
+	r.mu.Unlock()
+
+	keep := make(map[string]bool, len(wanted))
+	for _, channelID := range wanted {
+		keep[channelID] = true
+		if !registered[channelID] {
+			r.RegisterChannel(channelID)
+		}
+	}
+	for channelID := range registered {
+		if !keep[channelID] {
+			r.UnregisterChannel(channelID)
+		}
+	}
+	return len(keep), nil
+}
+
 // ChatWebsocket routes a gateway/websocket turn through runtime bindings when configured.
 // When no websocket runtime mapping exists yet, it preserves legacy default-agent behavior.
 func (r *Router) ChatWebsocket(
@@ -295,16 +340,19 @@ func (r *Router) hasAnyBindings(ctx context.Context, channelAccountID string) (b
 }
 
 func (r *Router) handleLegacyInbound(ctx context.Context, msg *bus.Message) error {
-	sess, err := r.sessionMgr.GetWithSource(msg.SessionID, session.SourceChannels)
+	sessionID := r.conversationSessionID(ctx, msg)
+	sess, err := r.sessionMgr.GetWithSource(sessionID, session.SourceChannels)
 	if err != nil {
-		return fmt.Errorf("get legacy channel session %s: %w", msg.SessionID, err)
+		return fmt.Errorf("get legacy channel session %s: %w", sessionID, err)
 	}
 
 	response, _, err := r.agent.ChatWithPromptContextDetailed(ctx, sess, msg.Content, agent.PromptContext{
-		Channel:   msg.ChannelID,
-		SessionID: msg.SessionID,
-		UserID:    msg.UserID,
-		Username:  msg.Username,
+		Channel:     msg.ChannelID,
+		SessionID:   sessionID,
+		UserID:      msg.UserID,
+		Username:    msg.Username,
+		Stream:      r.replyStream(msg, ""),
+		Attachments: msg.Attachments,
 	})
 	if err != nil {
 		return fmt.Errorf("legacy channel %s chat: %w", msg.ChannelID, err)
@@ -438,12 +486,22 @@ func (r *Router) chatWithRuntime(
 	runtimeItem runtimeagents.AgentRuntime,
 	source string,
 ) (string, map[string]any, error) {
-	sessionID := routedSessionID(runtimeItem.ID, msg.SessionID)
+	sessionID := routedSessionID(runtimeItem.ID, r.conversationSessionID(ctx, msg))
 	sess, err := r.sessionMgr.GetWithSource(sessionID, source)
 	if err != nil {
 		return "", nil, fmt.Errorf("get routed session %s: %w", sessionID, err)
 	}
 
+	// Several multi-agent replies cannot share one streamed message.
+	var stream agent.StreamFunc
+	if binding.BindingMode != accountbindings.ModeMultiAgent {
+		prefix := ""
+		if label := strings.TrimSpace(binding.ReplyLabel); label != "" {
+			prefix = fmt.Sprintf("[%s] ", binding.ReplyLabel)
+		}
+		stream = r.replyStream(msg, prefix)
+	}
+
 	response, _, err := r.agent.ChatWithPromptContextDetailed(ctx, sess, msg.Content, agent.PromptContext{
 		Channel:           msg.ChannelID,
 		SessionID:         sessionID,
@@ -461,6 +519,8 @@ func (r *Router) chatWithRuntime(
 			"binding_mode":       binding.BindingMode,
 			"reply_label":        binding.ReplyLabel,
 		},
+		Stream:      stream,
+		Attachments: msg.Attachments,
 	})
 	if err != nil {
 		return "", nil, fmt.Errorf("runtime %s chat: %w", runtimeItem.ID, err)
@@ -483,6 +543,44 @@ func (r *Router) chatWithRuntime(
 	}, nil
 }
 
+// replyStream publishes the partial reply as stream updates when the inbound
+// channel asked for them. prefix is prepended like on the final reply.
+func (r *Router) replyStream(msg *bus.Message, prefix string) agent.StreamFunc {
+	if enabled, _ := msg.Data[bus.DataKeyStreamReplies].(bool); !enabled {
+		return nil
+	}
+	return func(text string) {
+		update := &bus.Message{
+			ChannelID: msg.ChannelID,
+			SessionID: msg.SessionID,
+			UserID:    msg.UserID,
+			Username:  msg.Username,
+			Type:      bus.MessageTypeStreamUpdate,
+			Content:   prefix + text,
+			Data:      cloneMessageData(msg.Data),
+			ReplyTo:   msg.ReplyTo,
+		}
+		if err := r.bus.SendOutbound(update); err != nil {
+			r.log.Debug("Failed to publish reply stream update",
+				zap.String("channel_id", msg.ChannelID),
+				zap.Error(err))
+		}
+	}
+}
+
+// conversationSessionID returns the session a message continues. Direct
+// messages from a linked account continue the linked identity's session;
+// replies still go out on msg.SessionID.
+func (r *Router) conversationSessionID(ctx context.Context, msg *bus.Message) string {
+	if r.links == nil {
+		return msg.SessionID
+	}
+	if direct, _ := msg.Data[bus.DataKeyDirectMessage].(bool); !direct {
+		return msg.SessionID
+	}
+	return r.links.ResolveSessionID(ctx, msg.ChannelID, msg.UserID, msg.SessionID)
+}
+
 func routedSessionID(runtimeID, upstreamSessionID string) string {
 	runtimeID = strings.TrimSpace(runtimeID)
 	upstreamSessionID = strings.TrimSpace(upstreamSessionID)
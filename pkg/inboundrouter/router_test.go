@@ -3,6 +3,7 @@ package inboundrouter
 import (
 	"context"
 	"encoding/json"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -16,7 +17,9 @@ import (
 	"nekobot/pkg/logger"
 	"nekobot/pkg/runtimeagents"
 	"nekobot/pkg/session"
+	"nekobot/pkg/state"
 	"nekobot/pkg/storage/ent"
+	"nekobot/pkg/userprefs"
 	wxtypes "nekobot/pkg/wechat/types"
 )
 
@@ -24,6 +27,8 @@ type stubAgent struct {
 	response   string
 	lastPrompt agent.PromptContext
 	lastInput  string
+	// partials are streamed before the response when the turn streams.
+	partials []string
 }
 
 func (s *stubAgent) ChatWithPromptContextDetailed(
@@ -34,6 +39,11 @@ func (s *stubAgent) ChatWithPromptContextDetailed(
 ) (string, agent.ChatRouteResult, error) {
 	s.lastPrompt = promptCtx
 	s.lastInput = userMessage
+	if promptCtx.Stream != nil {
+		for _, partial := range s.partials {
+			promptCtx.Stream(partial)
+		}
+	}
 	sess.AddMessage(agent.Message{
 		Role: "assistant",
 		ToolCalls: []agent.ToolCall{{
@@ -981,6 +991,111 @@ func TestHandleInboundFallsBackForLegacyChannelWithoutTopology(t *testing.T) {
 	}
 }
 
+func TestHandleInboundPublishesStreamUpdatesWhenRequested(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	log, err := logger.New(&logger.Config{Level: "error", OutputPath: ""})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Fatalf("close ent client: %v", err)
+		}
+	})
+
+	accountMgr, err := channelaccounts.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new account manager: %v", err)
+	}
+	runtimeMgr, err := runtimeagents.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new runtime manager: %v", err)
+	}
+	bindingMgr, err := accountbindings.NewManager(cfg, log, client, runtimeMgr, accountMgr)
+	if err != nil {
+		t.Fatalf("new binding manager: %v", err)
+	}
+
+	messageBus := bus.NewLocalBus(log, 8)
+	if err := messageBus.Start(); err != nil {
+		t.Fatalf("start bus: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := messageBus.Stop(); err != nil {
+			t.Fatalf("stop bus: %v", err)
+		}
+	})
+
+	replyCh := make(chan *bus.Message, 4)
+	messageBus.RegisterOutboundHandler("telegram", func(ctx context.Context, msg *bus.Message) error {
+		replyCh <- msg
+		return nil
+	})
+
+	agentStub := &stubAgent{response: "hello there", partials: []string{"hel", "hello th"}}
+	router, err := New(
+		log,
+		messageBus,
+		agentStub,
+		session.NewManager(t.TempDir(), cfg.Sessions),
+		accountMgr,
+		bindingMgr,
+		runtimeMgr,
+	)
+	if err != nil {
+		t.Fatalf("new router: %v", err)
+	}
+
+	inbound := &bus.Message{
+		ChannelID: "telegram",
+		SessionID: "telegram:123",
+		UserID:    "u-1",
+		Type:      bus.MessageTypeText,
+		Content:   "hello",
+		Data: map[string]interface{}{
+			"thinking_message_id":    9,
+			bus.DataKeyStreamReplies: true,
+		},
+	}
+	if err := router.HandleInbound(context.Background(), inbound); err != nil {
+		t.Fatalf("handle inbound: %v", err)
+	}
+
+	want := []struct {
+		msgType bus.MessageType
+		content string
+	}{
+		{bus.MessageTypeStreamUpdate, "hel"},
+		{bus.MessageTypeStreamUpdate, "hello th"},
+		{bus.MessageTypeText, "hello there"},
+	}
+	for _, expected := range want {
+		select {
+		case msg := <-replyCh:
+			if msg.Type != expected.msgType || msg.Content != expected.content {
+				t.Fatalf("expected %s %q, got %s %q", expected.msgType, expected.content, msg.Type, msg.Content)
+			}
+			if got, _ := msg.Data["thinking_message_id"].(int); got != 9 {
+				t.Fatalf("expected thinking_message_id to be carried, got %#v", msg.Data["thinking_message_id"])
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected outbound %s message", expected.msgType)
+		}
+	}
+
+	delete(inbound.Data, bus.DataKeyStreamReplies)
+	if err := router.HandleInbound(context.Background(), inbound); err != nil {
+		t.Fatalf("handle inbound: %v", err)
+	}
+	if agentStub.lastPrompt.Stream != nil {
+		t.Fatal("expected no stream when the channel did not ask for one")
+	}
+}
+
 func newTestEntClient(t *testing.T, cfg *config.Config) *ent.Client {
 	t.Helper()
 	client, err := config.OpenRuntimeEntClient(cfg)
@@ -993,3 +1108,166 @@ func newTestEntClient(t *testing.T, cfg *config.Config) *ent.Client {
 	}
 	return client
 }
+
+func TestHandleInboundSharesSessionForLinkedDirectMessages(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	log, err := logger.New(&logger.Config{Level: "error", OutputPath: ""})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Fatalf("close ent client: %v", err)
+		}
+	})
+
+	accountMgr, err := channelaccounts.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new account manager: %v", err)
+	}
+	runtimeMgr, err := runtimeagents.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new runtime manager: %v", err)
+	}
+	bindingMgr, err := accountbindings.NewManager(cfg, log, client, runtimeMgr, accountMgr)
+	if err != nil {
+		t.Fatalf("new binding manager: %v", err)
+	}
+
+	messageBus := bus.NewLocalBus(log, 8)
+	if err := messageBus.Start(); err != nil {
+		t.Fatalf("start bus: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := messageBus.Stop(); err != nil {
+			t.Fatalf("stop bus: %v", err)
+		}
+	})
+	replyCh := make(chan *bus.Message, 4)
+	for _, channelID := range []string{"telegram", "discord"} {
+		messageBus.RegisterOutboundHandler(channelID, func(ctx context.Context, msg *bus.Message) error {
+			replyCh <- msg
+			return nil
+		})
+	}
+
+	store, err := state.NewFileStore(log, &state.FileStoreConfig{FilePath: filepath.Join(t.TempDir(), "prefs.json")})
+	if err != nil {
+		t.Fatalf("create state store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	prefs := userprefs.New(store)
+	ctx := context.Background()
+	code, _, err := prefs.IssueLinkCode(ctx, "telegram", "u-1")
+	if err != nil {
+		t.Fatalf("issue link code: %v", err)
+	}
+	identity, err := prefs.RedeemLinkCode(ctx, code, "discord", "d-1")
+	if err != nil {
+		t.Fatalf("redeem link code: %v", err)
+	}
+
+	agentStub := &stubAgent{response: "shared reply"}
+	router, err := New(log, messageBus, agentStub, session.NewManager(t.TempDir(), cfg.Sessions), accountMgr, bindingMgr, runtimeMgr)
+	if err != nil {
+		t.Fatalf("new router: %v", err)
+	}
+	router.SetIdentityLinks(prefs)
+
+	cases := []struct {
+		msg         *bus.Message
+		wantSession string
+	}{
+		{
+			msg:         &bus.Message{ChannelID: "telegram", SessionID: "telegram:123", UserID: "u-1", Content: "hi", Data: map[string]interface{}{bus.DataKeyDirectMessage: true}},
+			wantSession: userprefs.LinkedSessionID(identity),
+		},
+		{
+			msg:         &bus.Message{ChannelID: "discord", SessionID: "discord:dm-9", UserID: "d-1", Content: "hi", Data: map[string]interface{}{bus.DataKeyDirectMessage: true}},
+			wantSession: userprefs.LinkedSessionID(identity),
+		},
+		{
+			msg:         &bus.Message{ChannelID: "telegram", SessionID: "telegram:-100", UserID: "u-1", Content: "hi in group"},
+			wantSession: "telegram:-100",
+		},
+	}
+	for _, tc := range cases {
+		if err := router.HandleInbound(ctx, tc.msg); err != nil {
+			t.Fatalf("handle inbound: %v", err)
+		}
+		select {
+		case reply := <-replyCh:
+			if reply.SessionID != tc.msg.SessionID {
+				t.Fatalf("reply should keep the channel session %q, got %q", tc.msg.SessionID, reply.SessionID)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected outbound reply")
+		}
+		if agentStub.lastPrompt.SessionID != tc.wantSession {
+			t.Fatalf("expected session %q for %s, got %q", tc.wantSession, tc.msg.SessionID, agentStub.lastPrompt.SessionID)
+		}
+	}
+}
+
+func TestSyncChannelsFollowsEnabledAccounts(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+
+	log, err := logger.New(&logger.Config{Level: "error", OutputPath: ""})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	accountMgr, err := channelaccounts.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new account manager: %v", err)
+	}
+	runtimeMgr, err := runtimeagents.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new runtime manager: %v", err)
+	}
+	bindingMgr, err := accountbindings.NewManager(cfg, log, client, runtimeMgr, accountMgr)
+	if err != nil {
+		t.Fatalf("new binding manager: %v", err)
+	}
+	router, err := New(log, bus.NewLocalBus(log, 8), &stubAgent{}, session.NewManager(t.TempDir(), cfg.Sessions),
+		accountMgr, bindingMgr, runtimeMgr)
+	if err != nil {
+		t.Fatalf("new router: %v", err)
+	}
+
+	ctx := context.Background()
+	slack, err := accountMgr.Create(ctx, channelaccounts.ChannelAccount{ChannelType: "slack", AccountKey: "team-a", Enabled: true})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if _, err := accountMgr.Create(ctx, channelaccounts.ChannelAccount{ChannelType: "discord", AccountKey: "guild", Enabled: false}); err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if count, err := router.SyncChannels(ctx); err != nil || count != 2 {
+		t.Fatalf("expected 2 channels, got %d (%v)", count, err)
+	}
+	if got := strings.Join(router.channelKeys, ","); got != "websocket,slack:team-a" {
+		t.Fatalf("unexpected channels %q", got)
+	}
+
+	if err := accountMgr.Delete(ctx, slack.ID); err != nil {
+		t.Fatalf("delete account: %v", err)
+	}
+	if _, err := accountMgr.Create(ctx, channelaccounts.ChannelAccount{ChannelType: "telegram", AccountKey: "bot", Enabled: true}); err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if count, err := router.SyncChannels(ctx); err != nil || count != 2 {
+		t.Fatalf("expected 2 channels, got %d (%v)", count, err)
+	}
+	if got := strings.Join(router.channelKeys, ","); got != "websocket,telegram:bot" {
+		t.Fatalf("unexpected channels %q", got)
+	}
+}
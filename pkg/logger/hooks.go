@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// EntryHook receives log entries at or above warn level together with their
+// fields. Hooks run on the logging goroutine and must not block; they must
+// also not log at warn level or above themselves.
+type EntryHook func(entry zapcore.Entry, fields []zapcore.Field)
+
+// hookRegistry holds hooks shared by a logger and all of its children.
+type hookRegistry struct {
+	mu     sync.RWMutex
+	nextID uint64
+	hooks  map[uint64]EntryHook
+	count  atomic.Int32
+}
+
+func newHookRegistry() *hookRegistry {
+	return &hookRegistry{hooks: map[uint64]EntryHook{}}
+}
+
+func (r *hookRegistry) add(hook EntryHook) func() {
+	r.mu.Lock()
+	r.nextID++
+	id := r.nextID
+	r.hooks[id] = hook
+	r.count.Store(int32(len(r.hooks)))
+	r.mu.Unlock()
+
+	return func() {
+		r.mu.Lock()
+		delete(r.hooks, id)
+		r.count.Store(int32(len(r.hooks)))
+		r.mu.Unlock()
+	}
+}
+
+func (r *hookRegistry) fire(entry zapcore.Entry, fields []zapcore.Field) {
+	r.mu.RLock()
+	hooks := make([]EntryHook, 0, len(r.hooks))
+	for _, hook := range r.hooks {
+		hooks = append(hooks, hook)
+	}
+	r.mu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(entry, fields)
+	}
+}
+
+// hookCore forwards warn-and-above entries to registered hooks.
+type hookCore struct {
+	registry *hookRegistry
+	fields   []zapcore.Field
+}
+
+func (c *hookCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.WarnLevel && c.registry.count.Load() > 0
+}
+
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	combined := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	combined = append(combined, c.fields...)
+	combined = append(combined, fields...)
+	return &hookCore{registry: c.registry, fields: combined}
+}
+
+func (c *hookCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *hookCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	all := fields
+	if len(c.fields) > 0 {
+		all = make([]zapcore.Field, 0, len(c.fields)+len(fields))
+		all = append(all, c.fields...)
+		all = append(all, fields...)
+	}
+	c.registry.fire(entry, all)
+	return nil
+}
+
+func (c *hookCore) Sync() error {
+	return nil
+}
+
+// AddEntryHook registers a hook for warn-and-above entries logged through l
+// or any logger derived from it. The returned function removes the hook.
+func (l *Logger) AddEntryHook(hook EntryHook) func() {
+	if l == nil || l.hooks == nil || hook == nil {
+		return func() {}
+	}
+	return l.hooks.add(hook)
+}
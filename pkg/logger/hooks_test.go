@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAddEntryHookObservesWarnAndAboveIncludingChildFields(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.OutputPath = ""
+	cfg.Development = true
+	log, err := New(cfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	var entries []zapcore.Entry
+	var fieldKeys [][]string
+	remove := log.AddEntryHook(func(entry zapcore.Entry, fields []zapcore.Field) {
+		entries = append(entries, entry)
+		keys := make([]string, 0, len(fields))
+		for _, field := range fields {
+			keys = append(keys, field.Key)
+		}
+		fieldKeys = append(fieldKeys, keys)
+	})
+
+	child := log.WithFields(zap.String("component", "db"))
+	child.Info("ignored")
+	child.Error("write failed", zap.Error(errors.New("disk full")))
+
+	if len(entries) != 1 || entries[0].Message != "write failed" {
+		t.Fatalf("expected only the error entry, got %+v", entries)
+	}
+	if len(fieldKeys[0]) != 2 || fieldKeys[0][0] != "component" || fieldKeys[0][1] != "error" {
+		t.Fatalf("expected child and call fields, got %v", fieldKeys[0])
+	}
+
+	remove()
+	log.Error("after removal")
+	if len(entries) != 1 {
+		t.Fatalf("expected removed hook to stop firing, got %d entries", len(entries))
+	}
+}
@@ -81,6 +81,7 @@ type Logger struct {
 	*zap.Logger
 	config *Config
 	sugar  *zap.SugaredLogger
+	hooks  *hookRegistry
 }
 
 // New creates a new logger with the given configuration.
@@ -158,6 +159,10 @@ func New(cfg *Config) (*Logger, error) {
 		))
 	}
 
+	// Entry hooks (e.g. alerting) observe warn-and-above entries.
+	hooks := newHookRegistry()
+	cores = append(cores, &hookCore{registry: hooks})
+
 	// Combine cores
 	core := zapcore.NewTee(cores...)
 
@@ -181,6 +186,7 @@ func New(cfg *Config) (*Logger, error) {
 		Logger: zapLogger,
 		config: cfg,
 		sugar:  zapLogger.Sugar(),
+		hooks:  hooks,
 	}, nil
 }
 
@@ -196,6 +202,7 @@ func (l *Logger) WithFields(fields ...zap.Field) *Logger {
 		Logger: child,
 		config: l.config,
 		sugar:  child.Sugar(),
+		hooks:  l.hooks,
 	}
 }
 
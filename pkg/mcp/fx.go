@@ -0,0 +1,35 @@
+package mcp
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+// Module is the fx module for the MCP server manager.
+var Module = fx.Module("mcp",
+	fx.Provide(NewManager),
+)
+
+// NewManager creates a new MCP server manager for fx.
+func NewManager(
+	lc fx.Lifecycle,
+	log *logger.Logger,
+	cfg *config.Config,
+) *Manager {
+	manager := New(log, cfg)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return manager.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			return manager.Stop()
+		},
+	})
+
+	return manager
+}
@@ -0,0 +1,412 @@
+// Package mcp keeps persistent connections to the configured MCP servers,
+// checks their health periodically and serves their tools to the agent.
+// Servers are reconciled against the config on every check, so edits apply
+// without a restart.
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	bladesmcp "github.com/go-kratos/blades/contrib/mcp"
+	bladestools "github.com/go-kratos/blades/tools"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+const defaultHealthInterval = time.Minute
+
+// ErrNotFound is returned for an unknown server name.
+var ErrNotFound = errors.New("mcp server not found")
+
+// State describes a server connection.
+type State string
+
+const (
+	StateConnecting State = "connecting"
+	StateConnected  State = "connected"
+	StateError      State = "error"
+)
+
+// ServerStatus reports the health of one server.
+type ServerStatus struct {
+	Name          string     `json:"name"`
+	Transport     string     `json:"transport"`
+	State         State      `json:"state"`
+	Tools         []string   `json:"tools"` // Tools exposed to the model after allowed/blocked filtering
+	LastError     string     `json:"last_error,omitempty"`
+	ConnectedAt   *time.Time `json:"connected_at,omitempty"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+}
+
+type server struct {
+	cfg    config.MCPServerConfig
+	client *bladesmcp.Client
+	tools  []bladestools.Tool
+	status ServerStatus
+}
+
+// Manager owns the MCP server connections.
+type Manager struct {
+	log      *logger.Logger
+	config   *config.Config
+	interval time.Duration
+	connect  func(config.MCPServerConfig) (*bladesmcp.Client, error)
+
+	mu      sync.RWMutex
+	servers map[string]*server
+	order   []string
+
+	checkMu sync.Mutex
+	wake    chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// New creates an MCP manager for the servers in cfg.Agents.Defaults.MCPServers.
+func New(log *logger.Logger, cfg *config.Config) *Manager {
+	return &Manager{
+		log:      log,
+		config:   cfg,
+		interval: defaultHealthInterval,
+		connect:  newClient,
+		servers:  make(map[string]*server),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Start connects to the configured servers and begins periodic health checks.
+func (m *Manager) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.done = make(chan struct{})
+	go m.loop(ctx)
+	return nil
+}
+
+// Stop ends health checks and closes every connection.
+func (m *Manager) Stop() error {
+	if m.cancel != nil {
+		m.cancel()
+		<-m.done
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var errs []error
+	for _, name := range m.order {
+		if err := closeClient(m.servers[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	m.servers = make(map[string]*server)
+	m.order = nil
+	return errors.Join(errs...)
+}
+
+func (m *Manager) loop(ctx context.Context) {
+	defer close(m.done)
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.Reload(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-m.wake:
+		}
+	}
+}
+
+// Refresh applies config changes right away: removed servers disconnect
+// immediately and new or changed ones connect on the next background check,
+// which it triggers without waiting for the health interval.
+func (m *Manager) Refresh() {
+	m.sync(m.config.Agents.Defaults.MCPServers)
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Reload reconciles the servers with the config and checks each one.
+// Unchanged healthy servers keep their connection.
+func (m *Manager) Reload(ctx context.Context) {
+	m.sync(m.config.Agents.Defaults.MCPServers)
+	m.CheckAll(ctx)
+}
+
+// sync adds, replaces and removes servers to match the configured list.
+func (m *Manager) sync(configs []config.MCPServerConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	wanted := make(map[string]struct{}, len(configs))
+	order := make([]string, 0, len(configs))
+	for i, cfg := range configs {
+		name := ServerName(cfg, i)
+		if _, dup := wanted[name]; dup {
+			continue
+		}
+		wanted[name] = struct{}{}
+		order = append(order, name)
+
+		existing, ok := m.servers[name]
+		if ok && reflect.DeepEqual(existing.cfg, cfg) {
+			continue
+		}
+		if ok {
+			m.closeLogged(existing)
+		}
+		m.servers[name] = &server{
+			cfg: cfg,
+			status: ServerStatus{
+				Name:      name,
+				Transport: transportName(cfg.Transport),
+				State:     StateConnecting,
+				Tools:     []string{},
+			},
+		}
+	}
+	for name, existing := range m.servers {
+		if _, ok := wanted[name]; !ok {
+			m.closeLogged(existing)
+			delete(m.servers, name)
+		}
+	}
+	m.order = order
+}
+
+// CheckAll checks every server, reconnecting the ones that are down.
+func (m *Manager) CheckAll(ctx context.Context) {
+	m.checkMu.Lock()
+	defer m.checkMu.Unlock()
+
+	m.mu.RLock()
+	names := append([]string(nil), m.order...)
+	m.mu.RUnlock()
+	for _, name := range names {
+		if ctx.Err() != nil {
+			return
+		}
+		_, _ = m.check(ctx, name, false)
+	}
+}
+
+// Reconnect drops a server's connection and connects again.
+func (m *Manager) Reconnect(ctx context.Context, name string) (ServerStatus, error) {
+	m.sync(m.config.Agents.Defaults.MCPServers)
+	m.checkMu.Lock()
+	defer m.checkMu.Unlock()
+	return m.check(ctx, name, true)
+}
+
+// check lists a server's tools over its connection, opening a new one when
+// needed or forced. Must be called with checkMu held.
+func (m *Manager) check(ctx context.Context, name string, force bool) (ServerStatus, error) {
+	m.mu.RLock()
+	srv, ok := m.servers[name]
+	var client *bladesmcp.Client
+	var cfg config.MCPServerConfig
+	if ok {
+		client, cfg = srv.client, srv.cfg
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return ServerStatus{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	if force && client != nil {
+		m.mu.Lock()
+		if srv.client == client {
+			m.closeLogged(srv)
+		}
+		m.mu.Unlock()
+		client = nil
+	}
+
+	fresh := client == nil
+	var err error
+	if fresh {
+		client, err = m.connect(cfg)
+	}
+	var tools []bladestools.Tool
+	if err == nil {
+		tools, err = client.Resolve(ctx)
+	}
+
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.servers[name] != srv {
+		// Replaced or removed while the check ran.
+		if fresh && client != nil {
+			_ = client.Close()
+		}
+		return ServerStatus{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	srv.status.LastCheckedAt = &now
+	if err != nil {
+		if fresh && client != nil {
+			_ = client.Close()
+		}
+		m.closeLogged(srv)
+		srv.tools = nil
+		srv.status.State = StateError
+		srv.status.LastError = err.Error()
+		srv.status.Tools = []string{}
+		srv.status.ConnectedAt = nil
+		m.log.Warn("MCP server health check failed", zap.String("server", name), zap.Error(err))
+		return copyStatus(srv.status), err
+	}
+
+	if fresh {
+		srv.client = client
+		srv.status.ConnectedAt = &now
+		m.log.Info("MCP server connected", zap.String("server", name), zap.Int("tools", len(tools)))
+	}
+	srv.tools = srv.tools[:0]
+	srv.status.Tools = []string{}
+	for _, tool := range tools {
+		if tool == nil || !srv.cfg.AllowsTool(tool.Name()) {
+			continue
+		}
+		srv.tools = append(srv.tools, tool)
+		srv.status.Tools = append(srv.status.Tools, tool.Name())
+	}
+	srv.status.State = StateConnected
+	srv.status.LastError = ""
+	return copyStatus(srv.status), nil
+}
+
+// Resolve returns the tools of every connected server. It implements the
+// blades tools resolver so the agent can use the shared connections.
+func (m *Manager) Resolve(ctx context.Context) ([]bladestools.Tool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var resolved []bladestools.Tool
+	for _, name := range m.order {
+		resolved = append(resolved, m.servers[name].tools...)
+	}
+	return resolved, nil
+}
+
+// Status reports every server in config order.
+func (m *Manager) Status() []ServerStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	statuses := make([]ServerStatus, 0, len(m.order))
+	for _, name := range m.order {
+		statuses = append(statuses, copyStatus(m.servers[name].status))
+	}
+	return statuses
+}
+
+// closeLogged closes a server's client. Must be called with mu held.
+func (m *Manager) closeLogged(srv *server) {
+	if err := closeClient(srv); err != nil {
+		m.log.Warn("Failed to close MCP connection", zap.String("server", srv.status.Name), zap.Error(err))
+	}
+}
+
+func closeClient(srv *server) error {
+	if srv == nil || srv.client == nil {
+		return nil
+	}
+	client := srv.client
+	srv.client = nil
+	return client.Close()
+}
+
+func copyStatus(status ServerStatus) ServerStatus {
+	status.Tools = append([]string{}, status.Tools...)
+	return status
+}
+
+func newClient(cfg config.MCPServerConfig) (*bladesmcp.Client, error) {
+	clientConfig, err := ClientConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return bladesmcp.NewClient(clientConfig)
+}
+
+// ClientConfig converts a configured server into a blades MCP client config.
+func ClientConfig(server config.MCPServerConfig) (bladesmcp.ClientConfig, error) {
+	transport, err := parseTransport(server.Transport)
+	if err != nil {
+		return bladesmcp.ClientConfig{}, fmt.Errorf("transport: %w", err)
+	}
+	timeout, err := parseTimeout(server.Timeout)
+	if err != nil {
+		return bladesmcp.ClientConfig{}, fmt.Errorf("timeout: %w", err)
+	}
+	return bladesmcp.ClientConfig{
+		Name:      strings.TrimSpace(server.Name),
+		Transport: transport,
+		Command:   strings.TrimSpace(server.Command),
+		Args:      server.Args,
+		Env:       server.Env,
+		WorkDir:   strings.TrimSpace(server.WorkDir),
+		Endpoint:  strings.TrimSpace(server.Endpoint),
+		Headers:   server.Headers,
+		Timeout:   timeout,
+	}, nil
+}
+
+// ServerName returns the configured name, or a positional fallback.
+func ServerName(cfg config.MCPServerConfig, idx int) string {
+	name := strings.TrimSpace(cfg.Name)
+	if name != "" {
+		return name
+	}
+	return fmt.Sprintf("index-%d", idx)
+}
+
+func transportName(raw string) string {
+	transport, err := parseTransport(raw)
+	if err != nil {
+		return strings.TrimSpace(raw)
+	}
+	return string(transport)
+}
+
+func parseTransport(raw string) (bladesmcp.TransportType, error) {
+	transport := strings.TrimSpace(strings.ToLower(raw))
+	if transport == "" {
+		transport = string(bladesmcp.TransportStdio)
+	}
+	if transport == "sse" {
+		transport = string(bladesmcp.TransportHTTP)
+	}
+
+	switch bladesmcp.TransportType(transport) {
+	case bladesmcp.TransportStdio, bladesmcp.TransportHTTP, bladesmcp.TransportWebSocket:
+		return bladesmcp.TransportType(transport), nil
+	default:
+		return "", fmt.Errorf("unsupported transport: %s", raw)
+	}
+}
+
+func parseTimeout(raw string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(trimmed)
+	if err != nil {
+		return 0, fmt.Errorf("parse timeout duration: %w", err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("timeout duration must be greater than 0")
+	}
+	return d, nil
+}
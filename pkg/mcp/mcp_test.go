@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+func TestManagerReloadConnectsAndFiltersTools(t *testing.T) {
+	docs := newTestServer(t, "read_doc", "read_secret", "delete_doc")
+	m := newTestManager(t, config.MCPServerConfig{
+		Name:         "docs",
+		Transport:    "http",
+		Endpoint:     docs.URL,
+		AllowedTools: []string{"read_*"},
+		BlockedTools: []string{"read_secret"},
+	})
+	t.Cleanup(func() { _ = m.Stop() })
+
+	m.Reload(context.Background())
+
+	statuses := m.Status()
+	if len(statuses) != 1 {
+		t.Fatalf("expected one server, got %+v", statuses)
+	}
+	status := statuses[0]
+	if status.State != StateConnected || status.ConnectedAt == nil || !slices.Equal(status.Tools, []string{"read_doc"}) {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	tools, err := m.Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name() != "read_doc" {
+		t.Fatalf("expected only read_doc, got %d tools", len(tools))
+	}
+}
+
+func TestManagerReportsFailuresAndReconnects(t *testing.T) {
+	docs := newTestServer(t, "read_doc")
+	m := newTestManager(t,
+		config.MCPServerConfig{Name: "docs", Transport: "http", Endpoint: docs.URL},
+		config.MCPServerConfig{Name: "broken", Transport: "carrier-pigeon"},
+	)
+	t.Cleanup(func() { _ = m.Stop() })
+
+	m.Reload(context.Background())
+	statuses := m.Status()
+	if statuses[0].State != StateConnected {
+		t.Fatalf("expected docs to connect, got %+v", statuses[0])
+	}
+	if statuses[1].State != StateError || statuses[1].LastError == "" {
+		t.Fatalf("expected broken server to report an error, got %+v", statuses[1])
+	}
+
+	status, err := m.Reconnect(context.Background(), "docs")
+	if err != nil || status.State != StateConnected {
+		t.Fatalf("reconnect: %+v, %v", status, err)
+	}
+	if _, err := m.Reconnect(context.Background(), "missing"); err == nil {
+		t.Fatal("expected unknown server to fail")
+	}
+
+	docs.CloseClientConnections()
+	docs.Close()
+	m.CheckAll(context.Background())
+	if status := m.Status()[0]; status.State != StateError || len(status.Tools) != 0 {
+		t.Fatalf("expected stopped server to report an error, got %+v", status)
+	}
+	if tools, _ := m.Resolve(context.Background()); len(tools) != 0 {
+		t.Fatalf("expected no tools from unhealthy servers, got %d", len(tools))
+	}
+}
+
+func TestManagerRefreshAppliesConfigChanges(t *testing.T) {
+	docs := newTestServer(t, "read_doc")
+	m := newTestManager(t, config.MCPServerConfig{Name: "docs", Transport: "http", Endpoint: docs.URL})
+	t.Cleanup(func() { _ = m.Stop() })
+	m.Reload(context.Background())
+
+	search := newTestServer(t, "search")
+	m.config.Agents.Defaults.MCPServers = []config.MCPServerConfig{
+		{Name: "search", Transport: "http", Endpoint: search.URL},
+	}
+	m.Refresh()
+
+	statuses := m.Status()
+	if len(statuses) != 1 || statuses[0].Name != "search" || statuses[0].State != StateConnecting {
+		t.Fatalf("expected docs removed and search pending, got %+v", statuses)
+	}
+	m.CheckAll(context.Background())
+	if status := m.Status()[0]; status.State != StateConnected || !slices.Equal(status.Tools, []string{"search"}) {
+		t.Fatalf("expected search to connect, got %+v", status)
+	}
+}
+
+func newTestManager(t *testing.T, servers ...config.MCPServerConfig) *Manager {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.MCPServers = servers
+
+	logCfg := logger.DefaultConfig()
+	logCfg.OutputPath = ""
+	log, err := logger.New(logCfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return New(log, cfg)
+}
+
+func newTestServer(t *testing.T, toolNames ...string) *httptest.Server {
+	t.Helper()
+
+	server := sdkmcp.NewServer(&sdkmcp.Implementation{Name: "test-mcp", Version: "v0.0.1"}, nil)
+	for _, name := range toolNames {
+		server.AddTool(&sdkmcp.Tool{
+			Name:        name,
+			Description: "test tool " + name,
+			InputSchema: map[string]any{"type": "object"},
+		}, func(ctx context.Context, req *sdkmcp.CallToolRequest) (*sdkmcp.CallToolResult, error) {
+			return &sdkmcp.CallToolResult{Content: []sdkmcp.Content{&sdkmcp.TextContent{Text: "ok"}}}, nil
+		})
+	}
+	handler := sdkmcp.NewStreamableHTTPHandler(func(*http.Request) *sdkmcp.Server { return server }, nil)
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		httpServer.CloseClientConnections()
+		httpServer.Close()
+	})
+	return httpServer
+}
@@ -197,12 +197,8 @@ func (m *Manager) UpdateAll(ctx context.Context) error {
 }
 
 func (m *Manager) exportSessions(ctx context.Context) error {
-	if !m.config.Sessions.Enabled {
-		return nil
-	}
-
-	exportDir := strings.TrimSpace(os.ExpandEnv(expandHome(m.config.Sessions.ExportDir)))
-	if exportDir == "" {
+	exporter := m.sessionExporter()
+	if exporter == nil {
 		return nil
 	}
 
@@ -211,7 +207,6 @@ func (m *Manager) exportSessions(ctx context.Context) error {
 		return nil
 	}
 
-	exporter := NewSessionExporter(m.log, exportDir, m.config.Sessions.RetentionDays)
 	if err := exporter.ExportAllSessions(ctx, sessionsDir); err != nil {
 		return fmt.Errorf("export sessions: %w", err)
 	}
@@ -222,6 +217,44 @@ func (m *Manager) exportSessions(ctx context.Context) error {
 	return nil
 }
 
+// ArchiveSession exports a finished conversation, prunes exports past the
+// retention window and re-indexes the sessions collection. It is a no-op when
+// session export is disabled.
+func (m *Manager) ArchiveSession(ctx context.Context, sessionID string, messages []SessionMessage) error {
+	exporter := m.sessionExporter()
+	if exporter == nil {
+		return nil
+	}
+
+	if err := exporter.ExportMessages(ctx, sessionID, messages); err != nil {
+		return fmt.Errorf("export session: %w", err)
+	}
+	if err := exporter.CleanupOldExports(ctx); err != nil {
+		return fmt.Errorf("cleanup exported sessions: %w", err)
+	}
+	if !m.available {
+		return nil
+	}
+	if err := m.UpdateCollection(ctx, "sessions"); err != nil {
+		return fmt.Errorf("reindex sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Manager) sessionExporter() *SessionExporter {
+	if !m.config.Sessions.Enabled {
+		return nil
+	}
+
+	exportDir := strings.TrimSpace(os.ExpandEnv(expandHome(m.config.Sessions.ExportDir)))
+	if exportDir == "" {
+		return nil
+	}
+
+	return NewSessionExporter(m.log, exportDir, m.config.Sessions.RetentionDays)
+}
+
 // Search performs a semantic search across collections.
 func (m *Manager) Search(ctx context.Context, collectionName, query string, limit int) ([]SearchResult, error) {
 	if !m.available {
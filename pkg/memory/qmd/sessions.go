@@ -83,6 +83,11 @@ func (se *SessionExporter) ExportSession(ctx context.Context, sessionID string,
 		messages = append(messages, msg)
 	}
 
+	return se.ExportMessages(ctx, sessionID, messages)
+}
+
+// ExportMessages writes an already-parsed conversation to markdown.
+func (se *SessionExporter) ExportMessages(ctx context.Context, sessionID string, messages []SessionMessage) error {
 	if len(messages) == 0 {
 		return fmt.Errorf("no messages in session")
 	}
@@ -96,7 +101,7 @@ func (se *SessionExporter) ExportSession(ctx context.Context, sessionID string,
 	}
 
 	// Write markdown file
-	mdPath := filepath.Join(se.exportDir, sessionID+".md")
+	mdPath := filepath.Join(se.exportDir, exportFileName(sessionID))
 	if err := os.WriteFile(mdPath, []byte(markdown), 0644); err != nil {
 		return fmt.Errorf("writing markdown: %w", err)
 	}
@@ -108,6 +113,12 @@ func (se *SessionExporter) ExportSession(ctx context.Context, sessionID string,
 	return nil
 }
 
+// exportFileName keeps session IDs containing path separators inside exportDir.
+func exportFileName(sessionID string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_").Replace(sessionID)
+	return name + ".md"
+}
+
 // convertToMarkdown converts session messages to markdown format.
 func (se *SessionExporter) convertToMarkdown(sessionID string, messages []SessionMessage) string {
 	var sb strings.Builder
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"nekobot/pkg/logger"
 )
@@ -76,6 +77,84 @@ func TestManagerExportSessionsUsesConfiguredDirs(t *testing.T) {
 	}
 }
 
+func TestSessionExporterExportMessagesRendersMarkdown(t *testing.T) {
+	exportDir := t.TempDir()
+	exporter := NewSessionExporter(newTestLogger(t), exportDir, 0)
+
+	startedAt := time.Date(2026, 3, 24, 9, 30, 0, 0, time.UTC)
+	err := exporter.ExportMessages(context.Background(), "webui/alice", []SessionMessage{
+		{Role: "user", Content: "what is qmd?", Timestamp: startedAt},
+		{Role: "assistant", Content: "a local search engine"},
+	})
+	if err != nil {
+		t.Fatalf("ExportMessages failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(exportDir, "webui_alice.md"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	want := "# Session: webui/alice\n\n" +
+		"**Date**: 2026-03-24 09:30:00\n\n" +
+		"---\n\n" +
+		"## User\n\nwhat is qmd?\n\n" +
+		"---\n\n" +
+		"## Assistant\n\na local search engine\n\n"
+	if string(data) != want {
+		t.Fatalf("unexpected markdown:\n%s", string(data))
+	}
+}
+
+func TestManagerArchiveSessionPrunesExpiredExports(t *testing.T) {
+	exportDir := t.TempDir()
+	stalePath := filepath.Join(exportDir, "stale.md")
+	freshPath := filepath.Join(exportDir, "fresh.md")
+	for _, path := range []string{stalePath, freshPath} {
+		if err := os.WriteFile(path, []byte("# old"), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	staleTime := time.Now().AddDate(0, 0, -10)
+	if err := os.Chtimes(stalePath, staleTime, staleTime); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	manager := &Manager{
+		log:    newTestLogger(t),
+		config: Config{Sessions: SessionsConfig{Enabled: true, ExportDir: exportDir, RetentionDays: 7}},
+	}
+	err := manager.ArchiveSession(context.Background(), "chat:3", []SessionMessage{{Role: "user", Content: "bye"}})
+	if err != nil {
+		t.Fatalf("ArchiveSession failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(exportDir, "chat:3.md")); err != nil {
+		t.Fatalf("expected closed session exported: %v", err)
+	}
+	if _, err := os.Stat(freshPath); err != nil {
+		t.Fatalf("expected export within retention kept: %v", err)
+	}
+	if _, err := os.Stat(stalePath); !os.IsNotExist(err) {
+		t.Fatalf("expected expired export removed, got %v", err)
+	}
+}
+
+func TestManagerArchiveSessionSkipsWhenDisabled(t *testing.T) {
+	exportDir := filepath.Join(t.TempDir(), "exports")
+	manager := &Manager{
+		log:    newTestLogger(t),
+		config: Config{Sessions: SessionsConfig{Enabled: false, ExportDir: exportDir, RetentionDays: 7}},
+	}
+
+	err := manager.ArchiveSession(context.Background(), "chat:4", []SessionMessage{{Role: "user", Content: "hello"}})
+	if err != nil {
+		t.Fatalf("ArchiveSession failed: %v", err)
+	}
+	if _, err := os.Stat(exportDir); !os.IsNotExist(err) {
+		t.Fatalf("expected no export when disabled, got %v", err)
+	}
+}
+
 func newTestLogger(t *testing.T) *logger.Logger {
 	t.Helper()
 	cfg := logger.DefaultConfig()
@@ -0,0 +1,214 @@
+// Package moderation checks chat messages against a configured content filter
+// before the agent runs and, optionally, on the agent's responses.
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+// Result is a moderator's verdict for one piece of text.
+type Result struct {
+	Flagged bool
+	// Categories lists the provider categories that were hit. Moderators
+	// without categories leave it empty.
+	Categories []string
+}
+
+// Moderator classifies text.
+type Moderator interface {
+	Moderate(ctx context.Context, text string) (Result, error)
+}
+
+// Verdict is the outcome of a filter check.
+type Verdict struct {
+	// Blocked is set when the text must not be used; Message holds the
+	// localized reply to send instead.
+	Blocked    bool
+	Message    string
+	Categories []string
+}
+
+// Filter applies a moderator according to the moderation config.
+type Filter struct {
+	moderator   Moderator
+	log         *logger.Logger
+	enabled     bool
+	block       bool
+	checkOutput bool
+	categories  map[string]struct{}
+	refusal     string
+	replacement string
+}
+
+// New builds a filter from config. A disabled config yields a filter whose
+// checks always pass.
+func New(cfg config.ModerationConfig, log *logger.Logger) (*Filter, error) {
+	if !cfg.Enabled {
+		return NewWithModerator(cfg, nil, log), nil
+	}
+	var (
+		moderator Moderator
+		err       error
+	)
+	switch strings.TrimSpace(strings.ToLower(cfg.Provider)) {
+	case "", "keyword":
+		moderator, err = NewKeywordModerator(cfg.Keywords, cfg.Patterns)
+	case "openai":
+		moderator = NewOpenAIModerator(cfg.OpenAI, nil)
+	default:
+		err = fmt.Errorf("unsupported moderation provider: %s", cfg.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewWithModerator(cfg, moderator, log), nil
+}
+
+// NewWithModerator builds a filter around an existing moderator. A nil
+// moderator disables the filter.
+func NewWithModerator(cfg config.ModerationConfig, moderator Moderator, log *logger.Logger) *Filter {
+	categories := make(map[string]struct{}, len(cfg.Categories))
+	for _, category := range cfg.Categories {
+		if category = normalizeCategory(category); category != "" {
+			categories[category] = struct{}{}
+		}
+	}
+	return &Filter{
+		moderator:   moderator,
+		log:         log,
+		enabled:     cfg.Enabled && moderator != nil,
+		block:       !strings.EqualFold(strings.TrimSpace(cfg.Action), "log"),
+		checkOutput: cfg.CheckOutput,
+		categories:  categories,
+		refusal:     strings.TrimSpace(cfg.RefusalMessage),
+		replacement: strings.TrimSpace(cfg.ReplacementMessage),
+	}
+}
+
+// Enabled reports whether the filter checks anything.
+func (f *Filter) Enabled() bool {
+	return f != nil && f.enabled
+}
+
+// ChecksOutput reports whether agent responses are moderated too.
+func (f *Filter) ChecksOutput() bool {
+	return f.Enabled() && f.checkOutput
+}
+
+// CheckInput moderates an inbound user message. A blocked verdict carries the
+// refusal to send instead of running the agent.
+func (f *Filter) CheckInput(ctx context.Context, text string) Verdict {
+	if !f.Enabled() {
+		return Verdict{}
+	}
+	verdict := f.check(ctx, "input", text)
+	if verdict.Blocked {
+		verdict.Message = f.refusal
+		if verdict.Message == "" {
+			verdict.Message = refusalMessages[detectLanguage(text)]
+		}
+	}
+	return verdict
+}
+
+// CheckOutput moderates an agent response. A blocked verdict carries the safe
+// message that replaces it; the language follows the user's prompt.
+func (f *Filter) CheckOutput(ctx context.Context, prompt, text string) Verdict {
+	if !f.ChecksOutput() {
+		return Verdict{}
+	}
+	verdict := f.check(ctx, "output", text)
+	if verdict.Blocked {
+		verdict.Message = f.replacement
+		if verdict.Message == "" {
+			verdict.Message = replacementMessages[detectLanguage(prompt)]
+		}
+	}
+	return verdict
+}
+
+func (f *Filter) check(ctx context.Context, direction, text string) Verdict {
+	if strings.TrimSpace(text) == "" {
+		return Verdict{}
+	}
+	result, err := f.moderator.Moderate(ctx, text)
+	if err != nil {
+		// Fail open: an unreachable moderation service must not take the bot down.
+		f.logWarn("Moderation check failed", zap.String("direction", direction), zap.Error(err))
+		return Verdict{}
+	}
+	if !f.matches(result) {
+		return Verdict{}
+	}
+	f.logWarn("Moderation flagged message",
+		zap.String("direction", direction),
+		zap.Strings("categories", result.Categories),
+		zap.Bool("blocked", f.block),
+	)
+	return Verdict{Blocked: f.block, Categories: result.Categories}
+}
+
+// matches reports whether a flagged result hits a configured category. Results
+// without categories, and filters without a category list, always match.
+func (f *Filter) matches(result Result) bool {
+	if !result.Flagged {
+		return false
+	}
+	if len(f.categories) == 0 || len(result.Categories) == 0 {
+		return true
+	}
+	for _, category := range result.Categories {
+		if _, ok := f.categories[normalizeCategory(category)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter) logWarn(msg string, fields ...zap.Field) {
+	if f.log != nil {
+		f.log.Warn(msg, fields...)
+	}
+}
+
+func normalizeCategory(category string) string {
+	return strings.TrimSpace(strings.ToLower(category))
+}
+
+var refusalMessages = map[string]string{
+	"en": "Sorry, I can't help with that request.",
+	"zh": "抱歉，我无法处理这个请求。",
+	"ja": "申し訳ありませんが、そのリクエストにはお応えできません。",
+}
+
+var replacementMessages = map[string]string{
+	"en": "Sorry, I can't share that response.",
+	"zh": "抱歉，我无法提供这条回复。",
+	"ja": "申し訳ありませんが、その回答はお伝えできません。",
+}
+
+// detectLanguage guesses the reply language from the script of text: kana
+// means Japanese, other Han characters mean Chinese, anything else English.
+func detectLanguage(text string) string {
+	han := false
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			return "ja"
+		case unicode.Is(unicode.Han, r):
+			han = true
+		}
+	}
+	if han {
+		return "zh"
+	}
+	return "en"
+}
@@ -0,0 +1,178 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+)
+
+type stubModerator struct {
+	result Result
+	err    error
+	seen   []string
+}
+
+func (s *stubModerator) Moderate(ctx context.Context, text string) (Result, error) {
+	s.seen = append(s.seen, text)
+	return s.result, s.err
+}
+
+func enabledConfig() config.ModerationConfig {
+	return config.ModerationConfig{Enabled: true, Action: "block"}
+}
+
+func TestFilterBlocksFlaggedInputWithLocalizedRefusal(t *testing.T) {
+	stub := &stubModerator{result: Result{Flagged: true, Categories: []string{"violence"}}}
+	filter := NewWithModerator(enabledConfig(), stub, nil)
+
+	verdict := filter.CheckInput(context.Background(), "something bad")
+	if !verdict.Blocked {
+		t.Fatalf("expected flagged input to be blocked")
+	}
+	if verdict.Message != refusalMessages["en"] {
+		t.Fatalf("unexpected refusal: %q", verdict.Message)
+	}
+
+	verdict = filter.CheckInput(context.Background(), "不好的内容")
+	if verdict.Message != refusalMessages["zh"] {
+		t.Fatalf("expected chinese refusal, got %q", verdict.Message)
+	}
+	verdict = filter.CheckInput(context.Background(), "ひどい内容です")
+	if verdict.Message != refusalMessages["ja"] {
+		t.Fatalf("expected japanese refusal, got %q", verdict.Message)
+	}
+}
+
+func TestFilterPassesCleanInput(t *testing.T) {
+	stub := &stubModerator{}
+	filter := NewWithModerator(enabledConfig(), stub, nil)
+
+	if verdict := filter.CheckInput(context.Background(), "hello there"); verdict.Blocked {
+		t.Fatalf("expected clean input to pass, got %+v", verdict)
+	}
+	if len(stub.seen) != 1 {
+		t.Fatalf("expected moderator to be consulted once, got %d", len(stub.seen))
+	}
+}
+
+func TestFilterLogActionDoesNotBlock(t *testing.T) {
+	cfg := enabledConfig()
+	cfg.Action = "log"
+	filter := NewWithModerator(cfg, &stubModerator{result: Result{Flagged: true}}, nil)
+
+	verdict := filter.CheckInput(context.Background(), "something bad")
+	if verdict.Blocked || verdict.Message != "" {
+		t.Fatalf("expected log action to pass the message through, got %+v", verdict)
+	}
+}
+
+func TestFilterRestrictsToConfiguredCategories(t *testing.T) {
+	cfg := enabledConfig()
+	cfg.Categories = []string{"Hate"}
+	stub := &stubModerator{result: Result{Flagged: true, Categories: []string{"violence"}}}
+	filter := NewWithModerator(cfg, stub, nil)
+
+	if verdict := filter.CheckInput(context.Background(), "text"); verdict.Blocked {
+		t.Fatalf("expected unlisted category to pass")
+	}
+	stub.result.Categories = []string{"violence", "hate"}
+	if verdict := filter.CheckInput(context.Background(), "text"); !verdict.Blocked {
+		t.Fatalf("expected listed category to block")
+	}
+}
+
+func TestFilterFailsOpenOnModeratorError(t *testing.T) {
+	filter := NewWithModerator(enabledConfig(), &stubModerator{err: errors.New("unavailable")}, nil)
+	if verdict := filter.CheckInput(context.Background(), "text"); verdict.Blocked {
+		t.Fatalf("expected moderator errors to pass the message through")
+	}
+}
+
+func TestFilterCheckOutputReplacesFlaggedResponse(t *testing.T) {
+	cfg := enabledConfig()
+	stub := &stubModerator{result: Result{Flagged: true}}
+
+	filter := NewWithModerator(cfg, stub, nil)
+	if verdict := filter.CheckOutput(context.Background(), "prompt", "reply"); verdict.Blocked {
+		t.Fatalf("expected output checks to be off unless check_output is set")
+	}
+
+	cfg.CheckOutput = true
+	cfg.ReplacementMessage = "[removed]"
+	filter = NewWithModerator(cfg, stub, nil)
+	verdict := filter.CheckOutput(context.Background(), "prompt", "reply")
+	if !verdict.Blocked || verdict.Message != "[removed]" {
+		t.Fatalf("expected configured replacement, got %+v", verdict)
+	}
+}
+
+func TestNewDisabledFilterPassesEverything(t *testing.T) {
+	filter, err := New(config.ModerationConfig{Keywords: []string{"bad"}}, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if filter.Enabled() {
+		t.Fatalf("expected disabled filter")
+	}
+	if verdict := filter.CheckInput(context.Background(), "bad"); verdict.Blocked {
+		t.Fatalf("expected disabled filter to pass")
+	}
+}
+
+func TestKeywordModeratorMatchesKeywordsAndPatterns(t *testing.T) {
+	moderator, err := NewKeywordModerator([]string{"Forbidden"}, []string{`\bfree\s+money\b`})
+	if err != nil {
+		t.Fatalf("NewKeywordModerator failed: %v", err)
+	}
+	cases := map[string]bool{
+		"this is FORBIDDEN talk": true,
+		"get free   money now":   true,
+		"a perfectly fine note":  false,
+	}
+	for text, want := range cases {
+		result, err := moderator.Moderate(context.Background(), text)
+		if err != nil {
+			t.Fatalf("Moderate(%q) failed: %v", text, err)
+		}
+		if result.Flagged != want {
+			t.Fatalf("Moderate(%q) flagged = %v, want %v", text, result.Flagged, want)
+		}
+	}
+
+	if _, err := NewKeywordModerator(nil, []string{"(unclosed"}); err == nil {
+		t.Fatalf("expected invalid pattern to fail")
+	}
+}
+
+func TestOpenAIModeratorParsesCategories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/moderations" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk-test" {
+			t.Errorf("unexpected authorization %q", got)
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["model"] != defaultOpenAIModel || body["input"] != "text" {
+			t.Errorf("unexpected request body %#v", body)
+		}
+		_, _ = w.Write([]byte(`{"results":[{"flagged":true,"categories":{"violence":true,"hate":false,"harassment":true}}]}`))
+	}))
+	defer server.Close()
+
+	moderator := NewOpenAIModerator(config.ModerationOpenAIConfig{APIKey: "sk-test", APIBase: server.URL + "/v1/"}, server.Client())
+	result, err := moderator.Moderate(context.Background(), "text")
+	if err != nil {
+		t.Fatalf("Moderate failed: %v", err)
+	}
+	if !result.Flagged || strings.Join(result.Categories, ",") != "harassment,violence" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
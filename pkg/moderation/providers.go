@@ -0,0 +1,141 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"nekobot/pkg/config"
+)
+
+const (
+	defaultOpenAIBase  = "https://api.openai.com/v1"
+	defaultOpenAIModel = "omni-moderation-latest"
+)
+
+// KeywordModerator flags text containing a configured keyword or matching a
+// configured regular expression.
+type KeywordModerator struct {
+	keywords []string
+	patterns []*regexp.Regexp
+}
+
+// NewKeywordModerator compiles the keyword and pattern lists. Keywords match
+// case-insensitively as substrings.
+func NewKeywordModerator(keywords, patterns []string) (*KeywordModerator, error) {
+	m := &KeywordModerator{}
+	for _, keyword := range keywords {
+		if keyword = strings.TrimSpace(strings.ToLower(keyword)); keyword != "" {
+			m.keywords = append(m.keywords, keyword)
+		}
+	}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compile moderation pattern %q: %w", pattern, err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m, nil
+}
+
+// Moderate implements Moderator.
+func (m *KeywordModerator) Moderate(ctx context.Context, text string) (Result, error) {
+	lower := strings.ToLower(text)
+	for _, keyword := range m.keywords {
+		if strings.Contains(lower, keyword) {
+			return Result{Flagged: true}, nil
+		}
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(text) {
+			return Result{Flagged: true}, nil
+		}
+	}
+	return Result{}, nil
+}
+
+// OpenAIModerator classifies text with the OpenAI moderation endpoint.
+type OpenAIModerator struct {
+	apiKey string
+	url    string
+	model  string
+	client *http.Client
+}
+
+// NewOpenAIModerator returns a moderator for the configured endpoint. A nil
+// client uses http.DefaultClient; requests are bounded by the context.
+func NewOpenAIModerator(cfg config.ModerationOpenAIConfig, client *http.Client) *OpenAIModerator {
+	base := strings.TrimRight(strings.TrimSpace(cfg.APIBase), "/")
+	if base == "" {
+		base = defaultOpenAIBase
+	}
+	model := strings.TrimSpace(cfg.Model)
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &OpenAIModerator{
+		apiKey: strings.TrimSpace(cfg.APIKey),
+		url:    base + "/moderations",
+		model:  model,
+		client: client,
+	}
+}
+
+type openAIModerationResponse struct {
+	Results []struct {
+		Flagged    bool            `json:"flagged"`
+		Categories map[string]bool `json:"categories"`
+	} `json:"results"`
+}
+
+// Moderate implements Moderator.
+func (m *OpenAIModerator) Moderate(ctx context.Context, text string) (Result, error) {
+	body, err := json.Marshal(map[string]string{"model": m.model, "input": text})
+	if err != nil {
+		return Result{}, fmt.Errorf("encode moderation request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(body))
+	if err != nil {
+		return Result{}, fmt.Errorf("create moderation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("send moderation request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return Result{}, fmt.Errorf("moderation request failed: %s: %s", resp.Status, strings.TrimSpace(string(snippet)))
+	}
+
+	var decoded openAIModerationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return Result{}, fmt.Errorf("decode moderation response: %w", err)
+	}
+	if len(decoded.Results) == 0 {
+		return Result{}, fmt.Errorf("moderation response has no results")
+	}
+
+	first := decoded.Results[0]
+	result := Result{Flagged: first.Flagged}
+	for category, hit := range first.Categories {
+		if hit {
+			result.Categories = append(result.Categories, category)
+		}
+	}
+	sort.Strings(result.Categories)
+	return result, nil
+}
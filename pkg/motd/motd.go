@@ -0,0 +1,266 @@
+// Package motd builds the operator banner shown in the CLI header and on the
+// WebUI login page, including an optional update-available notice.
+package motd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nekobot/pkg/config"
+)
+
+// DefaultCacheTTL bounds how often the remote latest-version URL is fetched.
+const DefaultCacheTTL = time.Hour
+
+// Notice is the rendered message-of-the-day.
+type Notice struct {
+	Enabled         bool   `json:"enabled"`
+	Title           string `json:"title,omitempty"`
+	Message         string `json:"message,omitempty"`
+	CurrentVersion  string `json:"current_version,omitempty"`
+	LatestVersion   string `json:"latest_version,omitempty"`
+	UpdateAvailable bool   `json:"update_available"`
+	UpdateURL       string `json:"update_url,omitempty"`
+}
+
+// Checker resolves notices and caches remote latest-version lookups.
+type Checker struct {
+	client *http.Client
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	cachedURL string
+	cached    string
+	fetchedAt time.Time
+}
+
+// NewChecker creates a checker. A nil client uses a short-timeout default.
+func NewChecker(client *http.Client) *Checker {
+	if client == nil {
+		client = &http.Client{Timeout: 3 * time.Second}
+	}
+	return &Checker{client: client, ttl: DefaultCacheTTL}
+}
+
+// Notice builds the notice for cfg and the running version.
+// Remote lookup failures only drop the update notice.
+func (c *Checker) Notice(ctx context.Context, cfg config.MOTDConfig, currentVersion string) Notice {
+	if !cfg.Enabled {
+		return Notice{}
+	}
+
+	notice := Notice{
+		Enabled:        true,
+		Title:          strings.TrimSpace(cfg.Title),
+		Message:        strings.TrimSpace(cfg.Message),
+		CurrentVersion: strings.TrimSpace(currentVersion),
+		UpdateURL:      strings.TrimSpace(cfg.UpdateURL),
+	}
+	latest := strings.TrimSpace(cfg.LatestVersion)
+	if latest == "" && strings.TrimSpace(cfg.LatestVersionURL) != "" {
+		latest = c.remoteLatest(ctx, strings.TrimSpace(cfg.LatestVersionURL))
+	}
+	notice.LatestVersion = latest
+	notice.UpdateAvailable = UpdateAvailable(notice.CurrentVersion, latest)
+	return notice
+}
+
+func (c *Checker) remoteLatest(ctx context.Context, url string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cachedURL == url && time.Since(c.fetchedAt) < c.ttl {
+		return c.cached
+	}
+	latest, err := fetchLatestVersion(ctx, c.client, url)
+	if err != nil {
+		// Keep serving the last known value rather than hammering the endpoint.
+		if c.cachedURL == url {
+			c.fetchedAt = time.Now()
+			return c.cached
+		}
+		return ""
+	}
+	c.cachedURL = url
+	c.cached = latest
+	c.fetchedAt = time.Now()
+	return latest
+}
+
+func fetchLatestVersion(ctx context.Context, client *http.Client, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetch latest version: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetch latest version: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return "", fmt.Errorf("read latest version: %w", err)
+	}
+	return parseLatestVersion(data), nil
+}
+
+// parseLatestVersion accepts plain text or JSON with "version" or "tag_name".
+func parseLatestVersion(data []byte) string {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{") {
+		var payload struct {
+			Version string `json:"version"`
+			TagName string `json:"tag_name"`
+		}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return ""
+		}
+		if v := strings.TrimSpace(payload.Version); v != "" {
+			return v
+		}
+		return strings.TrimSpace(payload.TagName)
+	}
+	if line, _, _ := strings.Cut(trimmed, "\n"); line != "" {
+		return strings.TrimSpace(line)
+	}
+	return ""
+}
+
+// UpdateAvailable reports whether latest is a newer release than current.
+// Development builds and unparseable versions never report an update.
+func UpdateAvailable(current, latest string) bool {
+	cmp, ok := CompareVersions(latest, current)
+	return ok && cmp > 0
+}
+
+// CompareVersions compares two semantic versions such as "v1.2.3" or
+// "1.3.0-rc.1". It returns -1, 0 or 1, and ok=false when either side does
+// not parse. A pre-release sorts before its release.
+func CompareVersions(a, b string) (int, bool) {
+	left, ok := parseVersion(a)
+	if !ok {
+		return 0, false
+	}
+	right, ok := parseVersion(b)
+	if !ok {
+		return 0, false
+	}
+
+	for i := range left.core {
+		if left.core[i] != right.core[i] {
+			if left.core[i] < right.core[i] {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	switch {
+	case left.pre == right.pre:
+		return 0, true
+	case left.pre == "":
+		return 1, true
+	case right.pre == "":
+		return -1, true
+	default:
+		return comparePreRelease(left.pre, right.pre), true
+	}
+}
+
+type parsedVersion struct {
+	core [3]int
+	pre  string
+}
+
+func parseVersion(raw string) (parsedVersion, bool) {
+	v := strings.TrimPrefix(strings.TrimSpace(raw), "v")
+	v, _, _ = strings.Cut(v, "+")
+	v, pre, _ := strings.Cut(v, "-")
+	if v == "" {
+		return parsedVersion{}, false
+	}
+
+	parts := strings.Split(v, ".")
+	if len(parts) > 3 {
+		return parsedVersion{}, false
+	}
+	var parsed parsedVersion
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsedVersion{}, false
+		}
+		parsed.core[i] = n
+	}
+	parsed.pre = pre
+	return parsed, true
+}
+
+// comparePreRelease orders dot-separated identifiers per semver precedence.
+func comparePreRelease(a, b string) int {
+	left := strings.Split(a, ".")
+	right := strings.Split(b, ".")
+	for i := 0; i < len(left) && i < len(right); i++ {
+		ln, lerr := strconv.Atoi(left[i])
+		rn, rerr := strconv.Atoi(right[i])
+		switch {
+		case lerr == nil && rerr == nil:
+			if ln != rn {
+				if ln < rn {
+					return -1
+				}
+				return 1
+			}
+		case lerr == nil:
+			return -1
+		case rerr == nil:
+			return 1
+		default:
+			if cmp := strings.Compare(left[i], right[i]); cmp != 0 {
+				return cmp
+			}
+		}
+	}
+	switch {
+	case len(left) < len(right):
+		return -1
+	case len(left) > len(right):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Banner renders the notice as plain-text lines for the CLI header.
+// defaultTitle is used when the notice is disabled or has no title.
+func Banner(notice Notice, defaultTitle string) string {
+	if !notice.Enabled {
+		return defaultTitle
+	}
+	title := defaultTitle
+	if notice.Title != "" {
+		title = notice.Title
+	}
+	lines := []string{title}
+	if notice.Message != "" {
+		lines = append(lines, notice.Message)
+	}
+	if notice.UpdateAvailable {
+		update := fmt.Sprintf("Update available: %s (running %s)", notice.LatestVersion, notice.CurrentVersion)
+		if notice.UpdateURL != "" {
+			update += " - " + notice.UpdateURL
+		}
+		lines = append(lines, update)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,114 @@
+package motd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/config"
+)
+
+func TestUpdateAvailable(t *testing.T) {
+	tests := []struct {
+		current string
+		latest  string
+		want    bool
+	}{
+		{current: "v1.2.3", latest: "v1.2.4", want: true},
+		{current: "1.2.3", latest: "v1.3", want: true},
+		{current: "v1.10.0", latest: "v1.9.9", want: false},
+		{current: "v1.2.3", latest: "1.2.3", want: false},
+		{current: "v1.3.0-rc.1", latest: "v1.3.0", want: true},
+		{current: "v1.3.0", latest: "v1.3.0-rc.2", want: false},
+		{current: "v1.3.0-rc.2", latest: "v1.3.0-rc.10", want: true},
+		{current: "v1.3.0-alpha", latest: "v1.3.0-alpha.1", want: true},
+		{current: "v1.3.0-1", latest: "v1.3.0-beta", want: true},
+		{current: "v1.2.3+build.5", latest: "v1.2.3", want: false},
+		{current: "dev", latest: "v9.9.9", want: false},
+		{current: "v1.2.3", latest: "", want: false},
+		{current: "v1.2.3", latest: "latest", want: false},
+	}
+
+	for _, tc := range tests {
+		if got := UpdateAvailable(tc.current, tc.latest); got != tc.want {
+			t.Errorf("UpdateAvailable(%q, %q) = %v, want %v", tc.current, tc.latest, got, tc.want)
+		}
+	}
+}
+
+func TestCheckerNoticeDisabled(t *testing.T) {
+	notice := NewChecker(nil).Notice(context.Background(), config.MOTDConfig{Message: "hidden"}, "v1.0.0")
+	if notice.Enabled || notice.Message != "" {
+		t.Fatalf("expected empty notice when disabled, got %+v", notice)
+	}
+	if got := Banner(notice, "default header"); got != "default header" {
+		t.Fatalf("expected default banner, got %q", got)
+	}
+}
+
+func TestCheckerNoticeFetchesAndCachesRemoteLatest(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"tag_name":"v2.0.0"}`))
+	}))
+	defer server.Close()
+
+	checker := NewChecker(server.Client())
+	cfg := config.MOTDConfig{
+		Enabled:          true,
+		Title:            "Acme Bot",
+		Message:          "Maintenance Friday 18:00 UTC",
+		LatestVersionURL: server.URL,
+		UpdateURL:        "https://example.com/releases",
+	}
+
+	notice := checker.Notice(context.Background(), cfg, "v1.4.0")
+	if !notice.UpdateAvailable || notice.LatestVersion != "v2.0.0" {
+		t.Fatalf("expected remote update notice, got %+v", notice)
+	}
+	checker.Notice(context.Background(), cfg, "v1.4.0")
+	if calls != 1 {
+		t.Fatalf("expected remote latest version to be cached, got %d fetches", calls)
+	}
+
+	banner := Banner(notice, "default header")
+	for _, fragment := range []string{"Acme Bot", "Maintenance Friday", "Update available: v2.0.0 (running v1.4.0) - https://example.com/releases"} {
+		if !strings.Contains(banner, fragment) {
+			t.Fatalf("expected banner to contain %q, got:\n%s", fragment, banner)
+		}
+	}
+}
+
+func TestCheckerNoticePrefersConfiguredLatestVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("remote latest version should not be fetched when latest_version is set")
+	}))
+	defer server.Close()
+
+	notice := NewChecker(server.Client()).Notice(context.Background(), config.MOTDConfig{
+		Enabled:          true,
+		LatestVersion:    "1.0.0",
+		LatestVersionURL: server.URL,
+	}, "v1.0.0")
+	if notice.UpdateAvailable {
+		t.Fatalf("expected no update for matching versions, got %+v", notice)
+	}
+}
+
+func TestParseLatestVersion(t *testing.T) {
+	cases := map[string]string{
+		"v1.2.3\n":                   "v1.2.3",
+		`{"version":"1.4.0"}`:        "1.4.0",
+		`{"tag_name":" v1.5.0 "}`:    "v1.5.0",
+		`{"name":"missing version"}`: "",
+		"":                           "",
+	}
+	for input, want := range cases {
+		if got := parseLatestVersion([]byte(input)); got != want {
+			t.Errorf("parseLatestVersion(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -145,7 +145,7 @@ func (m *Manager) UpdateRoute(ctx context.Context, id string, item NotificationR
 			}
 			return nil, fmt.Errorf("get notification route for auth: %w", lookupErr)
 		}
-		if !ac.CanWrite(existing.OwnerUserID) {
+		if !ac.CanWrite(existing.OwnerUserID, existing.TenantID) {
 			return nil, ownership.ErrPermissionDenied
 		}
 		normalized.OwnerUserID, normalized.TenantID, normalized.Visibility = ac.ValidateUpdateOwnership(
@@ -194,7 +194,7 @@ func (m *Manager) DeleteRoute(ctx context.Context, id string) error {
 			}
 			return fmt.Errorf("get notification route for auth: %w", lookupErr)
 		}
-		if !ac.CanWrite(existing.OwnerUserID) {
+		if !ac.CanWrite(existing.OwnerUserID, existing.TenantID) {
 			return ownership.ErrPermissionDenied
 		}
 	}
@@ -341,7 +341,7 @@ func (m *Manager) UpdateBinding(ctx context.Context, id string, item Notificatio
 			}
 			return nil, fmt.Errorf("get notification binding for auth: %w", lookupErr)
 		}
-		if !ac.CanWrite(existing.OwnerUserID) {
+		if !ac.CanWrite(existing.OwnerUserID, existing.TenantID) {
 			return nil, ownership.ErrPermissionDenied
 		}
 		normalized.OwnerUserID, normalized.TenantID, normalized.Visibility = ac.ValidateUpdateOwnership(
@@ -390,7 +390,7 @@ func (m *Manager) DeleteBinding(ctx context.Context, id string) error {
 			}
 			return fmt.Errorf("get notification binding for auth: %w", lookupErr)
 		}
-		if !ac.CanWrite(existing.OwnerUserID) {
+		if !ac.CanWrite(existing.OwnerUserID, existing.TenantID) {
 			return ownership.ErrPermissionDenied
 		}
 	}
@@ -426,7 +426,7 @@ func (m *Manager) DeleteBindingsForTarget(ctx context.Context, scope, target str
 	}
 	if ac, ok := ownership.AuthContextFromContext(ctx); ok {
 		for _, rec := range recs {
-			if !ac.CanWrite(rec.OwnerUserID) {
+			if !ac.CanWrite(rec.OwnerUserID, rec.TenantID) {
 				return ownership.ErrPermissionDenied
 			}
 		}
@@ -518,7 +518,7 @@ func (m *Manager) deleteBindingsForRoute(ctx context.Context, routeID string) er
 	}
 	if ac, ok := ownership.AuthContextFromContext(ctx); ok {
 		for _, rec := range recs {
-			if !ac.CanWrite(rec.OwnerUserID) {
+			if !ac.CanWrite(rec.OwnerUserID, rec.TenantID) {
 				return ownership.ErrPermissionDenied
 			}
 		}
@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/storage/ent"
+)
+
+// Module is the fx module for notify rules.
+var Module = fx.Module("notify",
+	fx.Provide(NewManager),
+)
+
+// NewManager creates a new notify rules manager for fx.
+func NewManager(
+	lc fx.Lifecycle,
+	log *logger.Logger,
+	b bus.Bus,
+	client *ent.Client,
+) *Manager {
+	manager := New(log, b, client)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return manager.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			return manager.Stop()
+		},
+	})
+
+	return manager
+}
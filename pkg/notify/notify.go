@@ -0,0 +1,286 @@
+// Package notify forwards runtime events published on the message bus (tool
+// failures, approval requests, heartbeat results, provider cooldowns) to
+// channel sessions according to user-defined rules.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/storage/ent"
+	"nekobot/pkg/storage/ent/notifyrule"
+)
+
+// AnyEvent matches every event type.
+const AnyEvent = "*"
+
+const maxForwardChars = 3500
+
+// ErrNotFound is returned for an unknown rule ID.
+var ErrNotFound = errors.New("notify rule not found")
+
+// Rule forwards events of the listed types to a channel session.
+type Rule struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	EventTypes []string  `json:"event_types"` // Event types to forward; "*" matches all.
+	Match      string    `json:"match"`       // Optional case-insensitive text the event must contain.
+	ChannelID  string    `json:"channel_id"`  // Channel that delivers the event, e.g. "telegram".
+	SessionID  string    `json:"session_id"`  // Chat to post to, e.g. "telegram:123456789".
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Matches reports whether the rule forwards an event.
+func (r Rule) Matches(eventType, content string) bool {
+	if !r.Enabled {
+		return false
+	}
+	if !slices.Contains(r.EventTypes, AnyEvent) && !slices.Contains(r.EventTypes, eventType) {
+		return false
+	}
+	match := strings.TrimSpace(r.Match)
+	return match == "" || strings.Contains(strings.ToLower(content), strings.ToLower(match))
+}
+
+// Manager stores rules and forwards matching events.
+type Manager struct {
+	log    *logger.Logger
+	bus    bus.Bus
+	client *ent.Client
+}
+
+// New creates a notify rules manager.
+func New(log *logger.Logger, b bus.Bus, client *ent.Client) *Manager {
+	return &Manager{log: log, bus: b, client: client}
+}
+
+// Start subscribes to runtime events.
+func (m *Manager) Start() error {
+	if m.client == nil {
+		return fmt.Errorf("runtime ent client is nil")
+	}
+	if m.bus == nil {
+		return fmt.Errorf("message bus is nil")
+	}
+	m.bus.RegisterOutboundHandler(bus.EventChannelID, m.HandleEvent)
+	m.log.Info("Notify rules engine started")
+	return nil
+}
+
+// Stop unsubscribes from runtime events.
+func (m *Manager) Stop() error {
+	if m.bus != nil {
+		m.bus.UnregisterOutboundHandlers(bus.EventChannelID)
+	}
+	return nil
+}
+
+// HandleEvent forwards an event message to the session of every matching
+// rule. Delivery failures are logged so one bad rule does not block others.
+func (m *Manager) HandleEvent(ctx context.Context, msg *bus.Message) error {
+	if msg == nil || msg.Type != bus.MessageTypeEvent {
+		return nil
+	}
+	eventType, _ := msg.Data[bus.DataKeyEvent].(string)
+	if eventType == "" {
+		return nil
+	}
+	items, err := m.client.NotifyRule.Query().
+		Where(notifyrule.EnabledEQ(true)).
+		Order(ent.Asc(notifyrule.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return fmt.Errorf("list notify rules: %w", err)
+	}
+	for _, item := range items {
+		rule := fromEnt(item)
+		if !rule.Matches(eventType, msg.Content) {
+			continue
+		}
+		if err := m.forward(rule, eventType, msg); err != nil {
+			m.log.Warn("Failed to forward event",
+				zap.String("rule_id", rule.ID),
+				zap.String("event", eventType),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) forward(rule Rule, eventType string, event *bus.Message) error {
+	content := fmt.Sprintf("[%s] %s", eventType, strings.TrimSpace(event.Content))
+	if event.SessionID != "" && event.SessionID != rule.SessionID {
+		content += "\nSession: " + event.SessionID
+	}
+	if runes := []rune(content); len(runes) > maxForwardChars {
+		content = string(runes[:maxForwardChars]) + "…"
+	}
+	return m.bus.SendOutbound(&bus.Message{
+		ID:        "notify:" + uuid.NewString(),
+		ChannelID: rule.ChannelID,
+		SessionID: rule.SessionID,
+		Type:      bus.MessageTypeText,
+		Content:   content,
+		Data: map[string]interface{}{
+			"source":  "notify",
+			"rule_id": rule.ID,
+			"event":   eventType,
+		},
+		Timestamp: time.Now(),
+	})
+}
+
+// List returns all rules, newest first.
+func (m *Manager) List(ctx context.Context) ([]Rule, error) {
+	items, err := m.client.NotifyRule.Query().
+		Order(ent.Desc(notifyrule.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list notify rules: %w", err)
+	}
+	rules := make([]Rule, 0, len(items))
+	for _, item := range items {
+		rules = append(rules, fromEnt(item))
+	}
+	return rules, nil
+}
+
+// Get returns one rule.
+func (m *Manager) Get(ctx context.Context, id string) (Rule, error) {
+	item, err := m.client.NotifyRule.Get(ctx, strings.TrimSpace(id))
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return Rule{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return Rule{}, fmt.Errorf("get notify rule: %w", err)
+	}
+	return fromEnt(item), nil
+}
+
+// Create stores a new rule.
+func (m *Manager) Create(ctx context.Context, rule Rule) (Rule, error) {
+	rule, err := normalize(rule)
+	if err != nil {
+		return Rule{}, err
+	}
+	item, err := m.client.NotifyRule.Create().
+		SetName(rule.Name).
+		SetEventTypesJSON(encodeEventTypes(rule.EventTypes)).
+		SetMatch(rule.Match).
+		SetChannelID(rule.ChannelID).
+		SetSessionID(rule.SessionID).
+		SetEnabled(rule.Enabled).
+		Save(ctx)
+	if err != nil {
+		return Rule{}, fmt.Errorf("create notify rule: %w", err)
+	}
+	return fromEnt(item), nil
+}
+
+// Update replaces the editable fields of a rule.
+func (m *Manager) Update(ctx context.Context, id string, rule Rule) (Rule, error) {
+	existing, err := m.Get(ctx, id)
+	if err != nil {
+		return Rule{}, err
+	}
+	rule, err = normalize(rule)
+	if err != nil {
+		return Rule{}, err
+	}
+	item, err := m.client.NotifyRule.UpdateOneID(existing.ID).
+		SetName(rule.Name).
+		SetEventTypesJSON(encodeEventTypes(rule.EventTypes)).
+		SetMatch(rule.Match).
+		SetChannelID(rule.ChannelID).
+		SetSessionID(rule.SessionID).
+		SetEnabled(rule.Enabled).
+		Save(ctx)
+	if err != nil {
+		return Rule{}, fmt.Errorf("update notify rule: %w", err)
+	}
+	return fromEnt(item), nil
+}
+
+// Delete removes a rule.
+func (m *Manager) Delete(ctx context.Context, id string) error {
+	if err := m.client.NotifyRule.DeleteOneID(strings.TrimSpace(id)).Exec(ctx); err != nil {
+		if ent.IsNotFound(err) {
+			return fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return fmt.Errorf("delete notify rule: %w", err)
+	}
+	return nil
+}
+
+func normalize(rule Rule) (Rule, error) {
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.Match = strings.TrimSpace(rule.Match)
+	rule.ChannelID = strings.TrimSpace(rule.ChannelID)
+	rule.SessionID = strings.TrimSpace(rule.SessionID)
+
+	types := make([]string, 0, len(rule.EventTypes))
+	for _, eventType := range rule.EventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if eventType == "" || slices.Contains(types, eventType) {
+			continue
+		}
+		if eventType != AnyEvent && !slices.Contains(bus.EventTypes, eventType) {
+			return Rule{}, fmt.Errorf("unknown event type %q", eventType)
+		}
+		types = append(types, eventType)
+	}
+	if len(types) == 0 {
+		return Rule{}, fmt.Errorf("at least one event type is required")
+	}
+	rule.EventTypes = types
+
+	if rule.ChannelID == "" || rule.SessionID == "" {
+		return Rule{}, fmt.Errorf("channel_id and session_id are required")
+	}
+	if rule.ChannelID == bus.EventChannelID {
+		return Rule{}, fmt.Errorf("channel_id %q is reserved", bus.EventChannelID)
+	}
+	if rule.Name == "" {
+		rule.Name = strings.Join(types, ", ") + " → " + rule.SessionID
+	}
+	return rule, nil
+}
+
+func encodeEventTypes(types []string) string {
+	data, err := json.Marshal(types)
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
+
+func fromEnt(item *ent.NotifyRule) Rule {
+	var types []string
+	if err := json.Unmarshal([]byte(item.EventTypesJSON), &types); err != nil || types == nil {
+		types = []string{}
+	}
+	return Rule{
+		ID:         item.ID,
+		Name:       item.Name,
+		EventTypes: types,
+		Match:      item.Match,
+		ChannelID:  item.ChannelID,
+		SessionID:  item.SessionID,
+		Enabled:    item.Enabled,
+		CreatedAt:  item.CreatedAt,
+		UpdatedAt:  item.UpdatedAt,
+	}
+}
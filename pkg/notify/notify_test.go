@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+func TestHandleEventForwardsToMatchingRules(t *testing.T) {
+	outbound := &stubBus{}
+	mgr := newTestManager(t, outbound)
+	ctx := context.Background()
+
+	approvals, err := mgr.Create(ctx, Rule{
+		EventTypes: []string{bus.EventApprovalRequested},
+		ChannelID:  "telegram",
+		SessionID:  "telegram:1",
+		Enabled:    true,
+	})
+	if err != nil {
+		t.Fatalf("create approvals rule: %v", err)
+	}
+	if _, err := mgr.Create(ctx, Rule{
+		Name:       "shell failures",
+		EventTypes: []string{AnyEvent},
+		Match:      "EXEC",
+		ChannelID:  "slack",
+		SessionID:  "slack:ops",
+		Enabled:    true,
+	}); err != nil {
+		t.Fatalf("create match rule: %v", err)
+	}
+	if _, err := mgr.Create(ctx, Rule{
+		EventTypes: []string{bus.EventToolFailed},
+		ChannelID:  "discord",
+		SessionID:  "discord:1",
+	}); err != nil {
+		t.Fatalf("create disabled rule: %v", err)
+	}
+
+	publish(t, mgr, bus.EventApprovalRequested, "webui:alice", "Tool exec is waiting for approval (request 7).")
+	publish(t, mgr, bus.EventToolFailed, "", "Tool read_file failed: not found")
+
+	if len(outbound.outbound) != 2 {
+		t.Fatalf("expected two forwarded messages, got %d", len(outbound.outbound))
+	}
+	first := outbound.outbound[0]
+	if first.ChannelID != "telegram" || first.SessionID != "telegram:1" || first.Data["rule_id"] != approvals.ID {
+		t.Fatalf("unexpected first delivery %+v", first)
+	}
+	if !strings.HasPrefix(first.Content, "[approval.requested] Tool exec") || !strings.Contains(first.Content, "Session: webui:alice") {
+		t.Fatalf("unexpected forwarded content %q", first.Content)
+	}
+	if second := outbound.outbound[1]; second.ChannelID != "slack" || second.SessionID != "slack:ops" {
+		t.Fatalf("expected the case-insensitive match rule to fire, got %+v", second)
+	}
+}
+
+func TestRuleCRUDValidatesInput(t *testing.T) {
+	mgr := newTestManager(t, &stubBus{})
+	ctx := context.Background()
+
+	invalid := []Rule{
+		{ChannelID: "telegram", SessionID: "telegram:1"},
+		{EventTypes: []string{"disk.full"}, ChannelID: "telegram", SessionID: "telegram:1"},
+		{EventTypes: []string{bus.EventToolFailed}, ChannelID: "telegram"},
+		{EventTypes: []string{bus.EventToolFailed}, ChannelID: bus.EventChannelID, SessionID: "x"},
+	}
+	for i, rule := range invalid {
+		if _, err := mgr.Create(ctx, rule); err == nil {
+			t.Fatalf("expected rule %d to be rejected", i)
+		}
+	}
+
+	rule, err := mgr.Create(ctx, Rule{
+		EventTypes: []string{bus.EventToolFailed, " tool.failed "},
+		ChannelID:  "telegram",
+		SessionID:  "telegram:1",
+		Enabled:    true,
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(rule.EventTypes) != 1 || rule.Name == "" {
+		t.Fatalf("expected normalized rule, got %+v", rule)
+	}
+
+	rule.EventTypes = []string{bus.EventHeartbeatResult}
+	rule.Enabled = false
+	updated, err := mgr.Update(ctx, rule.ID, rule)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.Enabled || updated.EventTypes[0] != bus.EventHeartbeatResult {
+		t.Fatalf("unexpected updated rule %+v", updated)
+	}
+
+	if err := mgr.Delete(ctx, rule.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := mgr.Delete(ctx, rule.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func publish(t *testing.T, mgr *Manager, eventType, sessionID, content string) {
+	t.Helper()
+	recorder := &stubBus{}
+	if err := bus.PublishEvent(recorder, eventType, sessionID, content, nil); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if err := mgr.HandleEvent(context.Background(), recorder.outbound[0]); err != nil {
+		t.Fatalf("handle event: %v", err)
+	}
+}
+
+func newTestManager(t *testing.T, b bus.Bus) *Manager {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		t.Fatalf("open runtime ent client: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	if err := config.EnsureRuntimeEntSchema(client); err != nil {
+		t.Fatalf("ensure runtime schema: %v", err)
+	}
+
+	logCfg := logger.DefaultConfig()
+	logCfg.OutputPath = ""
+	log, err := logger.New(logCfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return New(log, b, client)
+}
+
+type stubBus struct {
+	outbound []*bus.Message
+}
+
+func (b *stubBus) Start() error                                                  { return nil }
+func (b *stubBus) Stop() error                                                   { return nil }
+func (b *stubBus) RegisterInboundHandler(channelID string, handler bus.Handler)  {}
+func (b *stubBus) UnregisterInboundHandlers(channelID string)                    {}
+func (b *stubBus) RegisterOutboundHandler(channelID string, handler bus.Handler) {}
+func (b *stubBus) UnregisterOutboundHandlers(channelID string)                   {}
+func (b *stubBus) RegisterHandler(channelID string, handler bus.Handler)         {}
+func (b *stubBus) UnregisterHandlers(channelID string)                           {}
+func (b *stubBus) SendInbound(msg *bus.Message) error                            { return nil }
+func (b *stubBus) SendOutbound(msg *bus.Message) error {
+	b.outbound = append(b.outbound, msg)
+	return nil
+}
+func (b *stubBus) GetMetrics() map[string]uint64 { return map[string]uint64{} }
@@ -2,6 +2,10 @@ package ownership
 
 import "strings"
 
+// DefaultTenantSlug is the tenant every instance starts with. Its admins
+// manage all tenants.
+const DefaultTenantSlug = "default"
+
 const (
 	VisibilityPrivate = "private"
 	VisibilityShared  = "shared"
@@ -3,6 +3,7 @@ package ownership
 import (
 	"context"
 	"errors"
+	"strings"
 )
 
 var (
@@ -13,9 +14,10 @@ var (
 
 // AuthContext carries the authenticated user's identity for access control.
 type AuthContext struct {
-	UserID   string
-	TenantID string
-	Role     string
+	UserID     string
+	TenantID   string
+	TenantSlug string
+	Role       string
 }
 
 type authContextKey struct{}
@@ -42,10 +44,37 @@ func (ac AuthContext) IsAdminOrOwner() bool {
 	return ac.Role == "admin" || ac.Role == "owner"
 }
 
+// SpansTenants returns true for admins of the default tenant, who manage the
+// whole instance. Owners of other tenants are confined to their tenant. An
+// empty TenantSlug (a context not built from a login token) is treated as the
+// default tenant.
+func (ac AuthContext) SpansTenants() bool {
+	return ac.IsAdminOrOwner() && ac.inDefaultTenant()
+}
+
+// InTenant returns true if a resource stored under tenantID belongs to the
+// caller's tenant. Resources without a tenant predate tenant scoping and
+// belong to the default tenant.
+func (ac AuthContext) InTenant(tenantID string) bool {
+	if ac.SpansTenants() {
+		return true
+	}
+	tenantID = strings.TrimSpace(tenantID)
+	if tenantID == "" {
+		return ac.inDefaultTenant()
+	}
+	return ac.TenantID != "" && ac.TenantID == tenantID
+}
+
+func (ac AuthContext) inDefaultTenant() bool {
+	slug := strings.TrimSpace(ac.TenantSlug)
+	return slug == "" || slug == DefaultTenantSlug
+}
+
 // CanRead returns true if this AuthContext is allowed to read a resource
 // with the given ownership fields.
 func (ac AuthContext) CanRead(ownerUserID, tenantID, visibility string) bool {
-	if ac.IsAdminOrOwner() {
+	if ac.IsAdminOrOwner() && ac.InTenant(tenantID) {
 		return true
 	}
 	switch NormalizeVisibility(visibility) {
@@ -59,10 +88,10 @@ func (ac AuthContext) CanRead(ownerUserID, tenantID, visibility string) bool {
 }
 
 // CanWrite returns true if this AuthContext is allowed to modify or delete
-// a resource with the given owner. Admins/owners can write anything; normal
-// users can only write their own resources.
-func (ac AuthContext) CanWrite(ownerUserID string) bool {
-	if ac.IsAdminOrOwner() {
+// a resource with the given owner and tenant. Admins/owners can write
+// anything in their tenant; normal users can only write their own resources.
+func (ac AuthContext) CanWrite(ownerUserID, tenantID string) bool {
+	if ac.IsAdminOrOwner() && ac.InTenant(tenantID) {
 		return true
 	}
 	if ac.UserID == "" {
@@ -72,11 +101,16 @@ func (ac AuthContext) CanWrite(ownerUserID string) bool {
 }
 
 // ValidateCreateOwnership enforces ownership rules for resource creation.
-// Admin/owner callers may set any owner/tenant/visibility (including system).
-// Normal users are forced to their own identity and may not set system visibility.
-// Returns the (ownerUserID, tenantID, visibility) that should be persisted.
+// Admins of the default tenant may set any owner/tenant/visibility (including
+// system); owners of other tenants may set any owner and visibility but stay in
+// their tenant. Normal users are forced to their own identity and may not set
+// system visibility. Returns the (ownerUserID, tenantID, visibility) that
+// should be persisted.
 func (ac AuthContext) ValidateCreateOwnership(ownerUserID, tenantID, visibility string) (string, string, string) {
 	if ac.IsAdminOrOwner() {
+		if !ac.SpansTenants() {
+			tenantID = ac.TenantID
+		}
 		if ownerUserID == "" {
 			ownerUserID = ac.UserID
 		}
@@ -97,6 +131,9 @@ func (ac AuthContext) ValidateUpdateOwnership(
 	if ac.IsAdminOrOwner() {
 		owner := incomingOwner
 		tenant := incomingTenant
+		if !ac.SpansTenants() {
+			tenant = existingTenant
+		}
 		if owner == "" {
 			owner = existingOwner
 		}
@@ -0,0 +1,35 @@
+package plugins
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+// Module is the fx module for the tool plugin manager.
+var Module = fx.Module("plugins",
+	fx.Provide(NewManager),
+)
+
+// NewManager creates a new tool plugin manager for fx.
+func NewManager(
+	lc fx.Lifecycle,
+	log *logger.Logger,
+	cfg *config.Config,
+) *Manager {
+	manager := New(log, cfg)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return manager.Start(ctx)
+		},
+		OnStop: func(ctx context.Context) error {
+			return manager.Stop()
+		},
+	})
+
+	return manager
+}
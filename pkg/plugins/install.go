@@ -0,0 +1,299 @@
+package plugins
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// MaxWasmArchiveBytes caps the size of a WASM tool archive.
+const MaxWasmArchiveBytes = 64 << 20
+
+const downloadTimeout = 2 * time.Minute
+
+// ErrNotFound is returned for an unknown plugin name.
+var ErrNotFound = errors.New("plugin not found")
+
+// InstallWasm installs a WASM tool from a directory holding manifest.json
+// and the module, from a .zip of such a directory, or from an http(s) URL of
+// a .zip. An installed tool of the same name is replaced.
+func (m *Manager) InstallWasm(ctx context.Context, source string) (Status, error) {
+	source = strings.TrimSpace(source)
+	switch {
+	case source == "":
+		return Status{}, errors.New("source is required")
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		data, err := download(ctx, source)
+		if err != nil {
+			return Status{}, err
+		}
+		return m.InstallWasmArchive(ctx, data)
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return Status{}, err
+	}
+	if !info.IsDir() {
+		data, err := readLimited(source)
+		if err != nil {
+			return Status{}, err
+		}
+		return m.InstallWasmArchive(ctx, data)
+	}
+	return m.install(ctx, func(staging string) (string, error) {
+		manifest, err := ReadWasmManifest(source)
+		if err != nil {
+			return "", err
+		}
+		for _, name := range []string{WasmManifestFile, manifest.Module} {
+			if err := copyFile(filepath.Join(source, name), filepath.Join(staging, name)); err != nil {
+				return "", err
+			}
+		}
+		return staging, nil
+	})
+}
+
+// InstallWasmArchive installs a WASM tool from a .zip archive. The manifest
+// may sit at the root of the archive or in its single top-level directory.
+func (m *Manager) InstallWasmArchive(ctx context.Context, data []byte) (Status, error) {
+	return m.install(ctx, func(staging string) (string, error) {
+		return extractZip(data, staging)
+	})
+}
+
+// install stages the files written by fill in a hidden directory of the
+// plugins dir, loads the tool from there and moves it into place.
+func (m *Manager) install(ctx context.Context, fill func(staging string) (string, error)) (Status, error) {
+	pluginsDir := m.config.PluginsPath()
+	if pluginsDir == "" {
+		return Status{}, errors.New("tools.plugins_dir is not set")
+	}
+	m.installMu.Lock()
+	defer m.installMu.Unlock()
+
+	if err := os.MkdirAll(pluginsDir, 0o755); err != nil {
+		return Status{}, fmt.Errorf("create plugins dir: %w", err)
+	}
+	staging, err := os.MkdirTemp(pluginsDir, ".install-")
+	if err != nil {
+		return Status{}, fmt.Errorf("create staging dir: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	root, err := fill(staging)
+	if err != nil {
+		return Status{}, err
+	}
+	tool, err := loadWasmTool(ctx, root, m.config.WorkspacePath)
+	if err != nil {
+		return Status{}, err
+	}
+	name := tool.Name()
+	if err := m.checkToolName(name); err != nil {
+		tool.close(ctx)
+		return Status{}, err
+	}
+
+	target := filepath.Join(pluginsDir, name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	previous := m.removeWasmLocked(name)
+	if err := os.RemoveAll(target); err != nil {
+		tool.close(ctx)
+		return Status{}, fmt.Errorf("remove previous version: %w", err)
+	}
+	if err := os.Rename(root, target); err != nil {
+		tool.close(ctx)
+		return Status{}, fmt.Errorf("install %s: %w", name, err)
+	}
+	if previous != nil && previous.tool != nil {
+		previous.tool.close(ctx)
+	}
+	tool.dir = target
+	entry := &wasmEntry{name: name, dir: target, tool: tool}
+	m.wasm = append(m.wasm, entry)
+	slices.SortFunc(m.wasm, func(a, b *wasmEntry) int { return strings.Compare(a.name, b.name) })
+	if m.registry != nil {
+		m.registry.Replace(tool)
+	}
+	m.log.Info("Installed WASM tool", zap.String("tool", name), zap.String("path", target))
+	return entry.status(), nil
+}
+
+// checkToolName refuses names taken by built-in or gRPC plugin tools.
+// Installed WASM tools may be replaced.
+func (m *Manager) checkToolName(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.registry == nil {
+		return nil
+	}
+	existing, ok := m.registry.Get(name)
+	if !ok {
+		return nil
+	}
+	if _, isWasm := existing.(*wasmTool); isWasm {
+		return nil
+	}
+	return fmt.Errorf("tool %s already exists", name)
+}
+
+// UninstallWasm removes an installed WASM tool.
+func (m *Manager) UninstallWasm(ctx context.Context, name string) error {
+	m.installMu.Lock()
+	defer m.installMu.Unlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := m.removeWasmLocked(strings.TrimSpace(name))
+	if entry == nil {
+		return ErrNotFound
+	}
+	if entry.tool != nil {
+		entry.tool.close(ctx)
+	}
+	if err := os.RemoveAll(entry.dir); err != nil {
+		return fmt.Errorf("remove %s: %w", entry.dir, err)
+	}
+	m.log.Info("Uninstalled WASM tool", zap.String("tool", entry.name))
+	return nil
+}
+
+// removeWasmLocked drops the WASM entry named name and unregisters its
+// tool. Callers hold m.mu.
+func (m *Manager) removeWasmLocked(name string) *wasmEntry {
+	idx := slices.IndexFunc(m.wasm, func(e *wasmEntry) bool { return e.name == name })
+	if idx < 0 {
+		return nil
+	}
+	entry := m.wasm[idx]
+	m.wasm = slices.Delete(m.wasm, idx, idx+1)
+	if m.registry != nil && entry.tool != nil {
+		if registered, ok := m.registry.Get(entry.tool.Name()); ok && registered == entry.tool {
+			m.registry.Unregister(entry.tool.Name())
+		}
+	}
+	return entry
+}
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: %s", url, resp.Status)
+	}
+	return readAllLimited(resp.Body)
+}
+
+func readLimited(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readAllLimited(f)
+}
+
+func readAllLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxWasmArchiveBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxWasmArchiveBytes {
+		return nil, fmt.Errorf("archive exceeds %d MB", MaxWasmArchiveBytes>>20)
+	}
+	return data, nil
+}
+
+// extractZip writes the regular files of the archive under dir and returns
+// the directory holding the manifest.
+func extractZip(data []byte, dir string) (string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("open archive: %w", err)
+	}
+	var total uint64
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if !file.Mode().IsRegular() {
+			return "", fmt.Errorf("archive entry %s is not a regular file", file.Name)
+		}
+		name := filepath.FromSlash(file.Name)
+		if !filepath.IsLocal(name) {
+			return "", fmt.Errorf("archive entry %s escapes the plugin directory", file.Name)
+		}
+		if total += file.UncompressedSize64; total > MaxWasmArchiveBytes {
+			return "", fmt.Errorf("archive contents exceed %d MB", MaxWasmArchiveBytes>>20)
+		}
+		if err := extractZipFile(file, filepath.Join(dir, name)); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, WasmManifestFile)); err == nil {
+		return dir, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		root := filepath.Join(dir, entries[0].Name())
+		if _, err := os.Stat(filepath.Join(root, WasmManifestFile)); err == nil {
+			return root, nil
+		}
+	}
+	return "", fmt.Errorf("archive has no %s", WasmManifestFile)
+}
+
+func extractZipFile(file *zip.File, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, io.LimitReader(src, MaxWasmArchiveBytes)); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+func copyFile(src, dst string) error {
+	data, err := readLimited(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0o644)
+}
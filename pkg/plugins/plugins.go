@@ -0,0 +1,456 @@
+// Package plugins runs tool plugins from tools.plugins_dir. Executables
+// serve tools over gRPC (see package sdk); each runs in its own process and
+// is restarted on the next call after it exits, so a crashing plugin cannot
+// take the agent down with it. Subdirectories with a manifest.json hold WASM
+// tools, run in-process by wazero with a WASI filesystem scoped to the
+// workspace.
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pluginv1 "nekobot/gen/go/nekobot/plugin/v1"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/plugins/sdk"
+	"nekobot/pkg/tools"
+)
+
+const (
+	defaultStartTimeout = 10 * time.Second
+	stopGracePeriod     = 5 * time.Second
+)
+
+// Plugin kinds reported in Status.
+const (
+	KindGRPC = "grpc"
+	KindWasm = "wasm"
+)
+
+// Status reports one plugin.
+type Status struct {
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"`
+	Path      string   `json:"path"`
+	Running   bool     `json:"running"`
+	Tools     []string `json:"tools"`
+	Workspace string   `json:"workspace,omitempty"` // WASM tools only
+	LastError string   `json:"last_error,omitempty"`
+}
+
+// Manager discovers, starts and stops the tool plugins.
+type Manager struct {
+	log          *logger.Logger
+	config       *config.Config
+	startTimeout time.Duration
+
+	mu        sync.Mutex
+	plugins   []*plugin
+	wasm      []*wasmEntry
+	socketDir string
+	registry  *tools.Registry
+
+	installMu sync.Mutex
+}
+
+// wasmEntry is one WASM tool directory; tool is nil when it failed to load.
+type wasmEntry struct {
+	name string
+	dir  string
+	tool *wasmTool
+	err  error
+}
+
+// New creates a plugin manager for cfg.Tools.PluginsDir.
+func New(log *logger.Logger, cfg *config.Config) *Manager {
+	return &Manager{
+		log:          log,
+		config:       cfg,
+		startTimeout: defaultStartTimeout,
+	}
+}
+
+// Start launches every executable in the plugins directory. A plugin that
+// fails to start is logged and serves no tools; Status reports its error.
+func (m *Manager) Start(ctx context.Context) error {
+	dir := m.config.PluginsPath()
+	if dir == "" {
+		return nil
+	}
+	paths, wasmDirs, err := discover(dir)
+	if err != nil {
+		return err
+	}
+	for _, wasmDir := range wasmDirs {
+		entry := &wasmEntry{name: filepath.Base(wasmDir), dir: wasmDir}
+		entry.tool, entry.err = loadWasmTool(ctx, wasmDir, m.config.WorkspacePath)
+		if entry.err != nil {
+			m.log.Warn("Failed to load WASM tool", zap.String("plugin", entry.name), zap.Error(entry.err))
+		} else {
+			m.log.Info("Loaded WASM tool", zap.String("plugin", entry.name), zap.String("tool", entry.tool.Name()))
+		}
+		m.mu.Lock()
+		m.wasm = append(m.wasm, entry)
+		m.mu.Unlock()
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	socketDir, err := os.MkdirTemp("", "nekobot-plugins-")
+	if err != nil {
+		return fmt.Errorf("create plugin socket dir: %w", err)
+	}
+	m.mu.Lock()
+	m.socketDir = socketDir
+	m.mu.Unlock()
+
+	for _, path := range paths {
+		p := &plugin{
+			manager: m,
+			name:    strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+			path:    path,
+		}
+		if err := p.start(ctx); err != nil {
+			m.log.Warn("Failed to start tool plugin", zap.String("plugin", p.name), zap.Error(err))
+		} else {
+			m.log.Info("Started tool plugin", zap.String("plugin", p.name), zap.Strings("tools", p.toolNames()))
+		}
+		m.mu.Lock()
+		m.plugins = append(m.plugins, p)
+		m.mu.Unlock()
+	}
+	return nil
+}
+
+// Stop terminates every plugin process and releases the WASM runtimes.
+func (m *Manager) Stop() error {
+	m.mu.Lock()
+	plugins := m.plugins
+	wasm := m.wasm
+	socketDir := m.socketDir
+	m.plugins = nil
+	m.wasm = nil
+	m.socketDir = ""
+	m.registry = nil
+	m.mu.Unlock()
+
+	for _, entry := range wasm {
+		if entry.tool != nil {
+			entry.tool.close(context.Background())
+		}
+	}
+	for _, p := range plugins {
+		p.mu.Lock()
+		p.stop()
+		p.mu.Unlock()
+	}
+	if socketDir != "" {
+		return os.RemoveAll(socketDir)
+	}
+	return nil
+}
+
+// Tools returns the tools served by the running plugins.
+func (m *Manager) Tools() []tools.Tool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.toolsLocked()
+}
+
+func (m *Manager) toolsLocked() []tools.Tool {
+	var out []tools.Tool
+	for _, p := range m.plugins {
+		p.mu.Lock()
+		for _, spec := range p.specs {
+			out = append(out, newPluginTool(p, spec))
+		}
+		p.mu.Unlock()
+	}
+	for _, entry := range m.wasm {
+		if entry.tool != nil {
+			out = append(out, entry.tool)
+		}
+	}
+	return out
+}
+
+// Attach registers the plugin tools in registry and keeps it in sync as
+// WASM tools are installed and uninstalled. A plugin tool never replaces a
+// tool registered under the same name.
+func (m *Manager) Attach(registry *tools.Registry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.registry = registry
+	for _, tool := range m.toolsLocked() {
+		if err := registry.Register(tool); err != nil {
+			m.log.Warn("Skipping plugin tool", zap.String("tool", tool.Name()), zap.Error(err))
+			continue
+		}
+		m.log.Info("Plugin tool registered", zap.String("tool", tool.Name()))
+	}
+}
+
+// Status reports every discovered plugin.
+func (m *Manager) Status() []Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Status, 0, len(m.plugins))
+	for _, p := range m.plugins {
+		p.mu.Lock()
+		status := Status{Name: p.name, Kind: KindGRPC, Path: p.path, Running: p.running(), Tools: p.toolNames()}
+		if p.lastErr != nil {
+			status.LastError = p.lastErr.Error()
+		}
+		p.mu.Unlock()
+		out = append(out, status)
+	}
+	for _, entry := range m.wasm {
+		out = append(out, entry.status())
+	}
+	return out
+}
+
+func (e *wasmEntry) status() Status {
+	status := Status{Name: e.name, Kind: KindWasm, Path: e.dir, Tools: []string{}}
+	if e.tool != nil {
+		status.Running = true
+		status.Tools = []string{e.tool.Name()}
+		status.Workspace = e.tool.manifest.Workspace
+	}
+	if e.err != nil {
+		status.LastError = e.err.Error()
+	}
+	return status
+}
+
+// discover lists the executables and the WASM tool directories in dir,
+// sorted by name. Hidden entries are skipped.
+func discover(dir string) (paths, wasmDirs []string, err error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("read plugins dir: %w", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if entry.IsDir() {
+			if _, err := os.Stat(filepath.Join(dir, entry.Name(), WasmManifestFile)); err == nil {
+				wasmDirs = append(wasmDirs, filepath.Join(dir, entry.Name()))
+			}
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || !isExecutable(entry.Name(), info.Mode()) {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(paths)
+	sort.Strings(wasmDirs)
+	return paths, wasmDirs, nil
+}
+
+func isExecutable(name string, mode os.FileMode) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(name), ".exe")
+	}
+	return mode.Perm()&0o111 != 0
+}
+
+// plugin is one plugin process and its gRPC connection.
+type plugin struct {
+	manager *Manager
+	name    string
+	path    string
+
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	exited  chan struct{}
+	conn    *grpc.ClientConn
+	client  pluginv1.ToolPluginServiceClient
+	specs   []*pluginv1.ToolSpec
+	lastErr error
+}
+
+// start launches the process and waits until it serves. Callers hold p.mu,
+// except before p is shared.
+func (p *plugin) start(ctx context.Context) error {
+	m := p.manager
+	socket := filepath.Join(m.socketDir, p.name+".sock")
+	_ = os.Remove(socket)
+
+	cmd := exec.Command(p.path)
+	cmd.Dir = filepath.Dir(p.path)
+	cmd.Env = append(os.Environ(), sdk.EnvSocket+"="+socket)
+	output := &logWriter{log: m.log, plugin: p.name}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Start(); err != nil {
+		p.lastErr = err
+		return err
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	p.cmd = cmd
+	p.exited = exited
+
+	conn, err := grpc.NewClient("unix://"+socket,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  50 * time.Millisecond,
+				Multiplier: 1.6,
+				MaxDelay:   time.Second,
+			},
+		}),
+	)
+	if err != nil {
+		p.stop()
+		p.lastErr = err
+		return err
+	}
+	p.conn = conn
+	p.client = pluginv1.NewToolPluginServiceClient(conn)
+
+	describeCtx, cancel := context.WithTimeout(ctx, m.startTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-exited:
+			cancel()
+		case <-describeCtx.Done():
+		}
+	}()
+	resp, err := p.client.Describe(describeCtx, &pluginv1.DescribeRequest{ProtocolVersion: sdk.ProtocolVersion}, grpc.WaitForReady(true))
+	if err == nil && resp.GetProtocolVersion() != sdk.ProtocolVersion {
+		err = fmt.Errorf("unsupported plugin protocol version %d", resp.GetProtocolVersion())
+	}
+	if err != nil {
+		select {
+		case <-exited:
+			err = fmt.Errorf("plugin exited before serving: %w", err)
+		default:
+		}
+		p.stop()
+		p.lastErr = err
+		return err
+	}
+	p.specs = resp.GetTools()
+	p.lastErr = nil
+	return nil
+}
+
+// stop closes the connection and terminates the process, killing it if it
+// does not exit within stopGracePeriod. Callers hold p.mu.
+func (p *plugin) stop() {
+	if p.conn != nil {
+		_ = p.conn.Close()
+		p.conn = nil
+		p.client = nil
+	}
+	if p.cmd == nil || p.cmd.Process == nil {
+		return
+	}
+	if p.running() {
+		if err := p.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+			_ = p.cmd.Process.Kill()
+		}
+		select {
+		case <-p.exited:
+		case <-time.After(stopGracePeriod):
+			_ = p.cmd.Process.Kill()
+			<-p.exited
+		}
+	}
+	p.cmd = nil
+}
+
+// running reports whether the process is alive. Callers hold p.mu.
+func (p *plugin) running() bool {
+	if p.cmd == nil {
+		return false
+	}
+	select {
+	case <-p.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+func (p *plugin) toolNames() []string {
+	names := make([]string, 0, len(p.specs))
+	for _, spec := range p.specs {
+		names = append(names, spec.GetName())
+	}
+	return names
+}
+
+// execute calls a tool, restarting the plugin first if its process exited.
+func (p *plugin) execute(ctx context.Context, tool string, args map[string]interface{}) (string, error) {
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("encode arguments: %w", err)
+	}
+
+	p.mu.Lock()
+	if !p.running() {
+		p.manager.log.Warn("Restarting exited tool plugin", zap.String("plugin", p.name))
+		p.stop()
+		if err := p.start(ctx); err != nil {
+			p.mu.Unlock()
+			return "", fmt.Errorf("restart plugin %s: %w", p.name, err)
+		}
+	}
+	client := p.client
+	p.mu.Unlock()
+
+	resp, err := client.Execute(ctx, &pluginv1.ExecuteRequest{Tool: tool, ArgumentsJson: string(payload)})
+	if err != nil {
+		return "", fmt.Errorf("plugin %s: %w", p.name, err)
+	}
+	if resp.GetError() != "" {
+		return resp.GetOutput(), errors.New(resp.GetError())
+	}
+	return resp.GetOutput(), nil
+}
+
+// logWriter forwards plugin stdout and stderr to the log line by line.
+type logWriter struct {
+	log    *logger.Logger
+	plugin string
+}
+
+func (w *logWriter) Write(data []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			w.log.Info("Tool plugin output", zap.String("plugin", w.plugin), zap.String("line", line))
+		}
+	}
+	return len(data), nil
+}
@@ -0,0 +1,214 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/plugins/sdk"
+)
+
+// TestMain doubles as the plugin binary: the tests copy the test executable
+// into a plugins directory, and the manager starts it with EnvSocket set.
+func TestMain(m *testing.M) {
+	if os.Getenv(sdk.EnvSocket) != "" {
+		if err := sdk.Serve(echoTool{}, crashTool{}); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+type echoTool struct{}
+
+func (echoTool) Name() string        { return "plugin_echo" }
+func (echoTool) Description() string { return "Echoes text back." }
+func (echoTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"text": map[string]interface{}{"type": "string"}},
+		"required":   []string{"text"},
+	}
+}
+func (echoTool) Execute(_ context.Context, args map[string]interface{}) (string, error) {
+	text, _ := args["text"].(string)
+	if text == "" {
+		return "", errors.New("text is required")
+	}
+	return "echo: " + text, nil
+}
+
+type crashTool struct{}
+
+func (crashTool) Name() string                       { return "plugin_crash" }
+func (crashTool) Description() string                { return "Exits the plugin process." }
+func (crashTool) Parameters() map[string]interface{} { return nil }
+func (crashTool) Execute(context.Context, map[string]interface{}) (string, error) {
+	os.Exit(3)
+	return "", nil
+}
+
+func TestManagerServesPluginTools(t *testing.T) {
+	m := newTestManager(t, installTestPlugin(t))
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Stop() })
+
+	statuses := m.Status()
+	if len(statuses) != 1 || !statuses[0].Running || statuses[0].Name != "echo" {
+		t.Fatalf("unexpected status %+v", statuses)
+	}
+	if !slices.Equal(statuses[0].Tools, []string{"plugin_echo", "plugin_crash"}) {
+		t.Fatalf("unexpected tools %v", statuses[0].Tools)
+	}
+
+	echo := findTool(t, m, "plugin_echo")
+	if echo.Description() != "Echoes text back." || echo.Parameters()["required"] == nil {
+		t.Fatalf("schema not forwarded: %q %+v", echo.Description(), echo.Parameters())
+	}
+	out, err := echo.Execute(context.Background(), map[string]interface{}{"text": "hi"})
+	if err != nil || out != "echo: hi" {
+		t.Fatalf("unexpected result %q (%v)", out, err)
+	}
+	if _, err := echo.Execute(context.Background(), map[string]interface{}{}); err == nil || err.Error() != "text is required" {
+		t.Fatalf("expected the tool error to be forwarded, got %v", err)
+	}
+	if params := findTool(t, m, "plugin_crash").Parameters(); params["type"] != "object" {
+		t.Fatalf("expected a default object schema, got %+v", params)
+	}
+}
+
+func TestManagerRestartsExitedPlugin(t *testing.T) {
+	m := newTestManager(t, installTestPlugin(t))
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Stop() })
+
+	if _, err := findTool(t, m, "plugin_crash").Execute(context.Background(), nil); err == nil {
+		t.Fatal("expected the crash call to fail")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for m.Status()[0].Running && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	out, err := findTool(t, m, "plugin_echo").Execute(context.Background(), map[string]interface{}{"text": "back"})
+	if err != nil || out != "echo: back" {
+		t.Fatalf("expected the plugin to be restarted, got %q (%v)", out, err)
+	}
+}
+
+func TestManagerSkipsNonExecutables(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bits are not used on windows")
+	}
+	dir := t.TempDir()
+	for name, mode := range map[string]os.FileMode{"README.md": 0o644, ".hidden": 0o755} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	paths, wasmDirs, err := discover(dir)
+	if err != nil || len(paths) != 0 || len(wasmDirs) != 0 {
+		t.Fatalf("expected no plugins, got %v %v (%v)", paths, wasmDirs, err)
+	}
+	if paths, _, err := discover(filepath.Join(dir, "missing")); err != nil || paths != nil {
+		t.Fatalf("expected a missing dir to disable plugins, got %v (%v)", paths, err)
+	}
+}
+
+func TestManagerReportsPluginThatFailsToServe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script plugin")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken"), []byte("#!/bin/sh\nexit 2\n"), 0o755); err != nil {
+		t.Fatalf("write plugin: %v", err)
+	}
+	m := newTestManager(t, dir)
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Stop() })
+
+	statuses := m.Status()
+	if len(statuses) != 1 || statuses[0].Running || !strings.Contains(statuses[0].LastError, "exited before serving") {
+		t.Fatalf("unexpected status %+v", statuses)
+	}
+	if len(m.Tools()) != 0 {
+		t.Fatalf("expected no tools from a broken plugin, got %d", len(m.Tools()))
+	}
+}
+
+func newTestManager(t *testing.T, pluginsDir string) *Manager {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Tools.PluginsDir = pluginsDir
+
+	logCfg := logger.DefaultConfig()
+	logCfg.OutputPath = ""
+	log, err := logger.New(logCfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return New(log, cfg)
+}
+
+// installTestPlugin copies the test binary into a fresh plugins directory.
+func installTestPlugin(t *testing.T) string {
+	t.Helper()
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatalf("find test binary: %v", err)
+	}
+	src, err := os.Open(self)
+	if err != nil {
+		t.Fatalf("open test binary: %v", err)
+	}
+	defer src.Close()
+
+	dir := t.TempDir()
+	name := "echo"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	dst, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY, 0o755)
+	if err != nil {
+		t.Fatalf("create plugin: %v", err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		t.Fatalf("copy plugin: %v", err)
+	}
+	if err := dst.Close(); err != nil {
+		t.Fatalf("close plugin: %v", err)
+	}
+	return dir
+}
+
+func findTool(t *testing.T, m *Manager, name string) interface {
+	Name() string
+	Description() string
+	Parameters() map[string]interface{}
+	Execute(context.Context, map[string]interface{}) (string, error)
+} {
+	t.Helper()
+	for _, tool := range m.Tools() {
+		if tool.Name() == name {
+			return tool
+		}
+	}
+	t.Fatalf("tool %s not found", name)
+	return nil
+}
@@ -0,0 +1,135 @@
+// Package sdk serves tools from a nekobot plugin binary. A plugin is an
+// executable placed in tools.plugins_dir; nekobot starts it with the socket
+// to listen on in NEKOBOT_PLUGIN_SOCKET and calls its tools over gRPC:
+//
+//	func main() {
+//		if err := sdk.Serve(&weatherTool{}); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//
+// Tools implement the same methods as nekobot's built-in tools.
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+
+	pluginv1 "nekobot/gen/go/nekobot/plugin/v1"
+)
+
+const (
+	// EnvSocket names the environment variable holding the unix socket path
+	// the plugin must listen on.
+	EnvSocket = "NEKOBOT_PLUGIN_SOCKET"
+	// ProtocolVersion is the plugin protocol spoken by this package.
+	ProtocolVersion = 1
+)
+
+// Tool is a tool served by a plugin.
+type Tool interface {
+	Name() string
+	Description() string
+	// Parameters returns the JSON schema of the tool arguments.
+	Parameters() map[string]interface{}
+	Execute(ctx context.Context, args map[string]interface{}) (string, error)
+}
+
+// Serve listens on the socket nekobot passed in EnvSocket and serves tools
+// until nekobot stops the plugin.
+func Serve(tools ...Tool) error {
+	path := os.Getenv(EnvSocket)
+	if path == "" {
+		return fmt.Errorf("%s is not set; plugins are started by nekobot", EnvSocket)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", path, err)
+	}
+
+	server := grpc.NewServer()
+	service, err := NewService(tools...)
+	if err != nil {
+		return err
+	}
+	pluginv1.RegisterToolPluginServiceServer(server, service)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-stop
+		server.GracefulStop()
+	}()
+	if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// Service implements the plugin gRPC service for a set of tools. Serve uses
+// it; it is exported for plugins that manage their own gRPC server.
+type Service struct {
+	pluginv1.UnimplementedToolPluginServiceServer
+
+	tools map[string]Tool
+	specs []*pluginv1.ToolSpec
+}
+
+// NewService builds a Service. Tool names must be unique.
+func NewService(tools ...Tool) (*Service, error) {
+	s := &Service{tools: make(map[string]Tool, len(tools))}
+	for _, tool := range tools {
+		name := tool.Name()
+		if name == "" {
+			return nil, errors.New("tool name is required")
+		}
+		if _, exists := s.tools[name]; exists {
+			return nil, fmt.Errorf("duplicate tool %q", name)
+		}
+		schema, err := json.Marshal(tool.Parameters())
+		if err != nil {
+			return nil, fmt.Errorf("encode %s parameters: %w", name, err)
+		}
+		s.tools[name] = tool
+		s.specs = append(s.specs, &pluginv1.ToolSpec{
+			Name:           name,
+			Description:    tool.Description(),
+			ParametersJson: string(schema),
+		})
+	}
+	return s, nil
+}
+
+// Describe lists the served tools.
+func (s *Service) Describe(context.Context, *pluginv1.DescribeRequest) (*pluginv1.DescribeResponse, error) {
+	return &pluginv1.DescribeResponse{ProtocolVersion: ProtocolVersion, Tools: s.specs}, nil
+}
+
+// Execute runs one tool call. Tool errors are returned in the response, not
+// as gRPC errors, so partial output reaches the agent.
+func (s *Service) Execute(ctx context.Context, req *pluginv1.ExecuteRequest) (*pluginv1.ExecuteResponse, error) {
+	tool, ok := s.tools[req.GetTool()]
+	if !ok {
+		return &pluginv1.ExecuteResponse{Error: fmt.Sprintf("unknown tool %q", req.GetTool())}, nil
+	}
+	args := map[string]interface{}{}
+	if raw := req.GetArgumentsJson(); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &args); err != nil {
+			return &pluginv1.ExecuteResponse{Error: fmt.Sprintf("decode arguments: %v", err)}, nil
+		}
+	}
+	output, err := tool.Execute(ctx, args)
+	resp := &pluginv1.ExecuteResponse{Output: output}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	return resp, nil
+}
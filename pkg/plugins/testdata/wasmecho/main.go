@@ -0,0 +1,43 @@
+// Command wasmecho is the WASM tool used by the plugins tests. Build it with
+// GOOS=wasip1 GOARCH=wasm.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	var args struct {
+		Text  string `json:"text"`
+		Read  string `json:"read"`
+		Write string `json:"write"`
+		Fail  string `json:"fail"`
+	}
+	if err := json.NewDecoder(os.Stdin).Decode(&args); err != nil {
+		fmt.Fprintln(os.Stderr, "decode arguments:", err)
+		os.Exit(2)
+	}
+	switch {
+	case args.Fail != "":
+		fmt.Fprintln(os.Stderr, args.Fail)
+		os.Exit(1)
+	case args.Read != "":
+		data, err := os.ReadFile(filepath.Join("/workspace", args.Read))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Print(string(data))
+	case args.Write != "":
+		if err := os.WriteFile(filepath.Join("/workspace", args.Write), []byte(args.Text), 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Print("written")
+	default:
+		fmt.Print("echo: " + args.Text)
+	}
+}
@@ -0,0 +1,39 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+
+	pluginv1 "nekobot/gen/go/nekobot/plugin/v1"
+)
+
+// pluginTool adapts one plugin tool to tools.Tool.
+type pluginTool struct {
+	plugin      *plugin
+	name        string
+	description string
+	parameters  map[string]interface{}
+}
+
+func newPluginTool(p *plugin, spec *pluginv1.ToolSpec) *pluginTool {
+	params := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(spec.GetParametersJson()), &params); err != nil || len(params) == 0 {
+		params = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+	return &pluginTool{
+		plugin:      p,
+		name:        spec.GetName(),
+		description: spec.GetDescription(),
+		parameters:  params,
+	}
+}
+
+func (t *pluginTool) Name() string { return t.name }
+
+func (t *pluginTool) Description() string { return t.description }
+
+func (t *pluginTool) Parameters() map[string]interface{} { return t.parameters }
+
+func (t *pluginTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	return t.plugin.execute(ctx, t.name, args)
+}
@@ -0,0 +1,233 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+
+	"nekobot/pkg/tools"
+)
+
+const (
+	// WasmManifestFile names the manifest in a WASM tool directory.
+	WasmManifestFile = "manifest.json"
+
+	// WorkspaceMount is where a WASM tool sees the workspace.
+	WorkspaceMount = "/workspace"
+
+	defaultWasmTimeout  = 30 * time.Second
+	defaultWasmMemoryMB = 128
+	maxWasmOutputBytes  = 1 << 20
+)
+
+// Workspace access a WASM tool can request in its manifest.
+const (
+	WasmWorkspaceNone      = "none"
+	WasmWorkspaceReadOnly  = "read_only"
+	WasmWorkspaceReadWrite = "read_write"
+)
+
+var wasmToolNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// WasmManifest describes a tool implemented as a WASI command module. The
+// module reads the JSON arguments on stdin and writes its result to stdout;
+// a non-zero exit code fails the call with stderr as the error.
+type WasmManifest struct {
+	Name           string                 `json:"name"`
+	Description    string                 `json:"description"`
+	Parameters     map[string]interface{} `json:"parameters"`      // JSON schema of the arguments
+	Module         string                 `json:"module"`          // .wasm file next to the manifest, default <name>.wasm
+	Workspace      string                 `json:"workspace"`       // none, read_only (default) or read_write
+	TimeoutSeconds int                    `json:"timeout_seconds"` // Per-call limit, 0 uses 30
+	MemoryLimitMB  int                    `json:"memory_limit_mb"` // Linear memory limit, 0 uses 128
+}
+
+// ReadWasmManifest reads and validates the manifest in dir.
+func ReadWasmManifest(dir string) (WasmManifest, error) {
+	var manifest WasmManifest
+	data, err := os.ReadFile(filepath.Join(dir, WasmManifestFile))
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("parse %s: %w", WasmManifestFile, err)
+	}
+	return manifest, manifest.normalize()
+}
+
+func (m *WasmManifest) normalize() error {
+	m.Name = strings.TrimSpace(m.Name)
+	if !wasmToolNamePattern.MatchString(m.Name) {
+		return fmt.Errorf("invalid tool name %q: use lowercase letters, digits and underscores", m.Name)
+	}
+	m.Module = strings.TrimSpace(m.Module)
+	if m.Module == "" {
+		m.Module = m.Name + ".wasm"
+	}
+	if filepath.IsAbs(m.Module) || filepath.Base(m.Module) != m.Module {
+		return fmt.Errorf("module %q must be a file next to the manifest", m.Module)
+	}
+	switch m.Workspace = strings.TrimSpace(m.Workspace); m.Workspace {
+	case "":
+		m.Workspace = WasmWorkspaceReadOnly
+	case WasmWorkspaceNone, WasmWorkspaceReadOnly, WasmWorkspaceReadWrite:
+	default:
+		return fmt.Errorf("invalid workspace access %q", m.Workspace)
+	}
+	if m.TimeoutSeconds < 0 || m.MemoryLimitMB < 0 {
+		return errors.New("timeout_seconds and memory_limit_mb must not be negative")
+	}
+	if len(m.Parameters) == 0 {
+		m.Parameters = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+	return nil
+}
+
+// wasmTool runs a compiled WASM module, one fresh instance per call.
+type wasmTool struct {
+	dir       string
+	manifest  WasmManifest
+	workspace func() string
+	runtime   wazero.Runtime
+	compiled  wazero.CompiledModule
+}
+
+// loadWasmTool compiles the module described by the manifest in dir.
+func loadWasmTool(ctx context.Context, dir string, workspace func() string) (*wasmTool, error) {
+	manifest, err := ReadWasmManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	code, err := os.ReadFile(filepath.Join(dir, manifest.Module))
+	if err != nil {
+		return nil, fmt.Errorf("read module: %w", err)
+	}
+
+	memoryMB := manifest.MemoryLimitMB
+	if memoryMB == 0 {
+		memoryMB = defaultWasmMemoryMB
+	}
+	rt := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(uint32(memoryMB)*16)) // 64KiB pages
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, rt); err != nil {
+		_ = rt.Close(ctx)
+		return nil, fmt.Errorf("instantiate wasi: %w", err)
+	}
+	compiled, err := rt.CompileModule(ctx, code)
+	if err != nil {
+		_ = rt.Close(ctx)
+		return nil, fmt.Errorf("compile module: %w", err)
+	}
+	return &wasmTool{
+		dir:       dir,
+		manifest:  manifest,
+		workspace: workspace,
+		runtime:   rt,
+		compiled:  compiled,
+	}, nil
+}
+
+func (t *wasmTool) Name() string { return t.manifest.Name }
+
+func (t *wasmTool) Description() string { return t.manifest.Description }
+
+func (t *wasmTool) Parameters() map[string]interface{} { return t.manifest.Parameters }
+
+func (t *wasmTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	input, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("encode arguments: %w", err)
+	}
+	timeout := defaultWasmTimeout
+	if t.manifest.TimeoutSeconds > 0 {
+		timeout = time.Duration(t.manifest.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stdout := &limitedBuffer{limit: maxWasmOutputBytes}
+	stderr := &limitedBuffer{limit: maxWasmOutputBytes}
+	moduleConfig := wazero.NewModuleConfig().
+		WithName("").
+		WithArgs(t.manifest.Name).
+		WithEnv("NEKOBOT_TOOL", t.manifest.Name).
+		WithStdin(bytes.NewReader(input)).
+		WithStdout(stdout).
+		WithStderr(stderr).
+		WithSysWalltime().
+		WithSysNanotime().
+		WithRandSource(rand.Reader)
+	if workspace := tools.WorkspaceFor(ctx, t.workspace()); workspace != "" && t.manifest.Workspace != WasmWorkspaceNone {
+		fsConfig := wazero.NewFSConfig()
+		if t.manifest.Workspace == WasmWorkspaceReadWrite {
+			fsConfig = fsConfig.WithDirMount(workspace, WorkspaceMount)
+		} else {
+			fsConfig = fsConfig.WithReadOnlyDirMount(workspace, WorkspaceMount)
+		}
+		moduleConfig = moduleConfig.WithFSConfig(fsConfig).WithEnv("PWD", WorkspaceMount)
+	}
+
+	module, err := t.runtime.InstantiateModule(ctx, t.compiled, moduleConfig)
+	if module != nil {
+		_ = module.Close(context.Background())
+	}
+	output := stdout.String()
+	var exitErr *sys.ExitError
+	switch {
+	case err == nil:
+		return output, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 0:
+		return output, nil
+	case ctx.Err() == context.DeadlineExceeded:
+		return output, fmt.Errorf("%s timed out after %s", t.manifest.Name, timeout)
+	case errors.As(err, &exitErr):
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return output, errors.New(msg)
+		}
+		return output, fmt.Errorf("%s exited with code %d", t.manifest.Name, exitErr.ExitCode())
+	default:
+		return output, fmt.Errorf("run %s: %w", t.manifest.Name, err)
+	}
+}
+
+func (t *wasmTool) close(ctx context.Context) {
+	_ = t.runtime.Close(ctx)
+}
+
+// limitedBuffer keeps the first limit bytes written and drops the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.Buffer.String() + "\n[output truncated]"
+	}
+	return b.Buffer.String()
+}
@@ -0,0 +1,218 @@
+package plugins
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"nekobot/pkg/tools"
+)
+
+var (
+	wasmEchoOnce sync.Once
+	wasmEchoPath string
+	wasmEchoErr  error
+)
+
+// buildWasmEcho compiles testdata/wasmecho for wasip1 once per test run.
+func buildWasmEcho(t *testing.T) []byte {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("builds a wasip1 module")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	wasmEchoOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "nekobot-wasmecho-")
+		if err != nil {
+			wasmEchoErr = err
+			return
+		}
+		wasmEchoPath = filepath.Join(dir, "wasmecho.wasm")
+		cmd := exec.Command(goBin, "build", "-o", wasmEchoPath, ".")
+		cmd.Dir = filepath.Join("testdata", "wasmecho")
+		cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			wasmEchoErr = errors.New(string(out))
+		}
+	})
+	if wasmEchoErr != nil {
+		t.Fatalf("build wasm module: %v", wasmEchoErr)
+	}
+	data, err := os.ReadFile(wasmEchoPath)
+	if err != nil {
+		t.Fatalf("read wasm module: %v", err)
+	}
+	return data
+}
+
+func writeWasmTool(t *testing.T, dir string, manifest WasmManifest, module []byte) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("create tool dir: %v", err)
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("encode manifest: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, WasmManifestFile), data, 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "wasmecho.wasm"), module, 0o644); err != nil {
+		t.Fatalf("write module: %v", err)
+	}
+}
+
+func TestWasmToolRunsWithScopedWorkspace(t *testing.T) {
+	module := buildWasmEcho(t)
+	pluginsDir := t.TempDir()
+	writeWasmTool(t, filepath.Join(pluginsDir, "wasm_echo"), WasmManifest{
+		Name:        "wasm_echo",
+		Description: "Echoes text from WASM.",
+		Module:      "wasmecho.wasm",
+	}, module)
+
+	m := newTestManager(t, pluginsDir)
+	workspace := t.TempDir()
+	m.config.Agents.Defaults.Workspace = workspace
+	if err := os.WriteFile(filepath.Join(workspace, "note.txt"), []byte("from the workspace"), 0o644); err != nil {
+		t.Fatalf("write note: %v", err)
+	}
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Stop() })
+
+	statuses := m.Status()
+	if len(statuses) != 1 || statuses[0].Kind != KindWasm || !statuses[0].Running || statuses[0].Workspace != WasmWorkspaceReadOnly {
+		t.Fatalf("unexpected status %+v", statuses)
+	}
+	tool := findTool(t, m, "wasm_echo")
+	ctx := context.Background()
+
+	if out, err := tool.Execute(ctx, map[string]interface{}{"text": "hi"}); err != nil || out != "echo: hi" {
+		t.Fatalf("unexpected echo %q (%v)", out, err)
+	}
+	if out, err := tool.Execute(ctx, map[string]interface{}{"read": "note.txt"}); err != nil || out != "from the workspace" {
+		t.Fatalf("expected the workspace to be readable, got %q (%v)", out, err)
+	}
+	if _, err := tool.Execute(ctx, map[string]interface{}{"read": "../../etc/passwd"}); err == nil {
+		t.Fatal("expected paths outside the workspace to be unreachable")
+	}
+	if _, err := tool.Execute(ctx, map[string]interface{}{"write": "out.txt", "text": "x"}); err == nil {
+		t.Fatal("expected the read-only workspace to refuse writes")
+	}
+	if _, err := tool.Execute(ctx, map[string]interface{}{"fail": "bad input"}); err == nil || err.Error() != "bad input" {
+		t.Fatalf("expected stderr as the error, got %v", err)
+	}
+
+	profileWorkspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(profileWorkspace, "note.txt"), []byte("from the profile"), 0o644); err != nil {
+		t.Fatalf("write profile note: %v", err)
+	}
+	profileCtx := tools.WithWorkspace(ctx, profileWorkspace)
+	if out, err := tool.Execute(profileCtx, map[string]interface{}{"read": "note.txt"}); err != nil || out != "from the profile" {
+		t.Fatalf("expected the call's workspace to be mounted, got %q (%v)", out, err)
+	}
+}
+
+func TestManagerInstallsAndUninstallsWasmArchive(t *testing.T) {
+	module := buildWasmEcho(t)
+	m := newTestManager(t, t.TempDir())
+	m.config.Agents.Defaults.Workspace = t.TempDir()
+	t.Cleanup(func() { _ = m.Stop() })
+	registry := tools.NewRegistry()
+	m.Attach(registry)
+
+	manifest, err := json.Marshal(WasmManifest{Name: "wasm_echo", Module: "wasmecho.wasm", Workspace: WasmWorkspaceReadWrite})
+	if err != nil {
+		t.Fatalf("encode manifest: %v", err)
+	}
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for name, data := range map[string][]byte{"wasm_echo/manifest.json": manifest, "wasm_echo/wasmecho.wasm": module} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("create zip entry: %v", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatalf("write zip entry: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+
+	status, err := m.InstallWasmArchive(context.Background(), archive.Bytes())
+	if err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if status.Name != "wasm_echo" || status.Workspace != WasmWorkspaceReadWrite {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	out, err := registry.Execute(context.Background(), "wasm_echo", map[string]interface{}{"write": "out.txt", "text": "saved"})
+	if err != nil || out != "written" {
+		t.Fatalf("expected the installed tool to be registered, got %q (%v)", out, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(m.config.WorkspacePath(), "out.txt")); err != nil || string(data) != "saved" {
+		t.Fatalf("expected a read-write workspace, got %q (%v)", data, err)
+	}
+
+	if err := m.UninstallWasm(context.Background(), "wasm_echo"); err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+	if _, ok := registry.Get("wasm_echo"); ok {
+		t.Fatal("expected the tool to be unregistered")
+	}
+	if _, err := os.Stat(status.Path); !os.IsNotExist(err) {
+		t.Fatalf("expected the tool dir to be removed, got %v", err)
+	}
+	if err := m.UninstallWasm(context.Background(), "wasm_echo"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestWasmManifestValidation(t *testing.T) {
+	for _, tc := range []struct {
+		manifest WasmManifest
+		want     string
+	}{
+		{WasmManifest{Name: "Bad-Name"}, "invalid tool name"},
+		{WasmManifest{Name: "ok", Module: "../evil.wasm"}, "next to the manifest"},
+		{WasmManifest{Name: "ok", Workspace: "everything"}, "invalid workspace access"},
+	} {
+		if err := tc.manifest.normalize(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("manifest %+v: expected %q, got %v", tc.manifest, tc.want, err)
+		}
+	}
+
+	m := WasmManifest{Name: "ok"}
+	if err := m.normalize(); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if m.Module != "ok.wasm" || m.Workspace != WasmWorkspaceReadOnly || m.Parameters["type"] != "object" {
+		t.Fatalf("unexpected defaults %+v", m)
+	}
+}
+
+func TestExtractZipRejectsEscapingPaths(t *testing.T) {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	w, _ := zw.Create("../escape.txt")
+	_, _ = w.Write([]byte("x"))
+	_ = zw.Close()
+
+	if _, err := extractZip(archive.Bytes(), t.TempDir()); err == nil || !strings.Contains(err.Error(), "escapes") {
+		t.Fatalf("expected an escaping path to be rejected, got %v", err)
+	}
+}
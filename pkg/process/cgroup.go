@@ -0,0 +1,208 @@
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cgroupRoot is where the unified (v2) cgroup hierarchy is mounted.
+var cgroupRoot = "/sys/fs/cgroup"
+
+// procRoot is the proc filesystem used to sum usage without a cgroup.
+var procRoot = "/proc"
+
+// clockTicks is USER_HZ, the unit of CPU times in /proc/<pid>/stat. It is
+// 100 on every mainstream Linux architecture.
+const clockTicks = 100
+
+// cgroup is a child cgroup v2 of nekobot's own cgroup holding one session.
+type cgroup struct {
+	dir string
+}
+
+// newCgroup creates a cgroup for a session below nekobot's own cgroup and
+// caps its memory when the memory controller is enabled there. It fails
+// unless the host delegates that part of the hierarchy to nekobot, as
+// systemd does for units with Delegate=yes.
+func newCgroup(sessionID string, limits ResourceLimits) (*cgroup, error) {
+	if runtime.GOOS != "linux" {
+		return nil, errors.ErrUnsupported
+	}
+	self, err := os.ReadFile(filepath.Join(procRoot, "self", "cgroup"))
+	if err != nil {
+		return nil, err
+	}
+	parent := ""
+	for _, line := range strings.Split(string(self), "\n") {
+		if rest, ok := strings.CutPrefix(line, "0::"); ok {
+			parent = strings.TrimSpace(rest)
+			break
+		}
+	}
+	if parent == "" {
+		return nil, errors.New("no cgroup v2 hierarchy")
+	}
+	dir := filepath.Join(cgroupRoot, parent, "nekobot-"+sanitizeCgroupName(sessionID))
+	if err := os.Mkdir(dir, 0o755); err != nil && !os.IsExist(err) {
+		return nil, err
+	}
+	cg := &cgroup{dir: dir}
+	if limits.MaxMemoryBytes > 0 {
+		err := cg.write("memory.max", strconv.FormatInt(limits.MaxMemoryBytes, 10))
+		if err != nil && !os.IsNotExist(err) {
+			cg.remove()
+			return nil, fmt.Errorf("set memory.max: %w", err)
+		}
+	}
+	return cg, nil
+}
+
+func sanitizeCgroupName(sessionID string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.TrimSpace(sessionID))
+}
+
+func (c *cgroup) write(name, value string) error {
+	return os.WriteFile(filepath.Join(c.dir, name), []byte(value), 0o644)
+}
+
+func (c *cgroup) attach(pid int) error {
+	return c.write("cgroup.procs", strconv.Itoa(pid))
+}
+
+// kill kills every process in the cgroup; it needs Linux 5.14 or later.
+func (c *cgroup) kill() error {
+	return c.write("cgroup.kill", "1")
+}
+
+// oomKilled reports whether the kernel killed a process of the cgroup for
+// exceeding memory.max.
+func (c *cgroup) oomKilled() bool {
+	value, ok := readKeyedFile(filepath.Join(c.dir, "memory.events"), "oom_kill")
+	return ok && value > 0
+}
+
+// remove deletes the cgroup. It waits briefly for the kernel to reap the
+// killed processes, since only empty cgroups can be removed.
+func (c *cgroup) remove() {
+	for i := 0; i < 10; i++ {
+		if err := os.Remove(c.dir); err == nil || os.IsNotExist(err) {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// cgroupSampler reads usage from the cgroup, and memory from /proc when the
+// memory controller is not enabled for it.
+type cgroupSampler struct {
+	cg       *cgroup
+	fallback usageSampler
+}
+
+func (s cgroupSampler) sample() (usage, error) {
+	usec, ok := readKeyedFile(filepath.Join(s.cg.dir, "cpu.stat"), "usage_usec")
+	if !ok {
+		return s.fallback.sample()
+	}
+	raw, err := os.ReadFile(filepath.Join(s.cg.dir, "memory.current"))
+	if err != nil {
+		fallback, err := s.fallback.sample()
+		if err != nil {
+			return usage{}, err
+		}
+		return usage{cpu: time.Duration(usec) * time.Microsecond, memory: fallback.memory}, nil
+	}
+	memory, err := strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return usage{}, err
+	}
+	return usage{cpu: time.Duration(usec) * time.Microsecond, memory: memory}, nil
+}
+
+// procSampler sums the usage of a session's process tree from /proc. PTY
+// sessions run in their own session, so every process whose session ID is
+// the leader's PID belongs to it.
+type procSampler struct {
+	pid int
+}
+
+func (s procSampler) sample() (usage, error) {
+	if runtime.GOOS != "linux" {
+		return usage{}, errors.ErrUnsupported
+	}
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return usage{}, err
+	}
+	pageSize := int64(os.Getpagesize())
+	var total usage
+	found := false
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		stat, err := os.ReadFile(filepath.Join(procRoot, entry.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+		// Fields after the command name, which may contain spaces, start
+		// with the state (field 3 of proc(5)).
+		end := bytes.LastIndexByte(stat, ')')
+		if end < 0 {
+			continue
+		}
+		fields := strings.Fields(string(stat[end+1:]))
+		if len(fields) < 22 {
+			continue
+		}
+		sid, _ := strconv.Atoi(fields[3])
+		if pid != s.pid && sid != s.pid {
+			continue
+		}
+		utime, _ := strconv.ParseInt(fields[11], 10, 64)
+		stime, _ := strconv.ParseInt(fields[12], 10, 64)
+		rss, _ := strconv.ParseInt(fields[21], 10, 64)
+		total.cpu += time.Duration(utime+stime) * time.Second / clockTicks
+		total.memory += rss * pageSize
+		found = true
+	}
+	if !found {
+		return usage{}, os.ErrNotExist
+	}
+	return total, nil
+}
+
+// readKeyedFile reads one "key value" line of a cgroup file.
+func readKeyedFile(path, key string) (int64, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), " ")
+		if !ok || name != key {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
@@ -1,10 +1,24 @@
 package process
 
 import (
+	"path/filepath"
+
 	"go.uber.org/fx"
+
+	"nekobot/pkg/config"
 )
 
 // Module provides process manager for fx.
 var Module = fx.Module("process",
 	fx.Provide(NewManager),
+	fx.Invoke(configureScrollback),
 )
+
+// configureScrollback persists tool-session output under the runtime
+// database directory; a zero limit keeps output in memory only.
+func configureScrollback(cfg *config.Config, m *Manager) {
+	if cfg.WebUI.ToolSessionScrollbackBytes <= 0 {
+		return
+	}
+	m.SetScrollback(filepath.Join(cfg.DatabaseDir(), "scrollback"), cfg.WebUI.ToolSessionScrollbackBytes)
+}
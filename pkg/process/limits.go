@@ -0,0 +1,276 @@
+package process
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Limit names reported in a LimitViolation.
+const (
+	LimitCPU       = "cpu"
+	LimitMemory    = "memory"
+	LimitOutput    = "output"
+	LimitWallClock = "wall_clock"
+)
+
+// limitPollInterval is how often CPU and memory usage are sampled.
+var limitPollInterval = time.Second
+
+// ResourceLimits bounds what one session may consume. A zero value disables
+// the limit.
+type ResourceLimits struct {
+	MaxCPUPercent  float64       // Sustained CPU usage; 100 is one full core
+	CPUGrace       time.Duration // How long CPU may stay above MaxCPUPercent
+	MaxMemoryBytes int64         // Resident memory of the whole process tree
+	MaxOutputBytes int64         // Terminal output produced since start
+	MaxWallClock   time.Duration // Absolute run time
+}
+
+// IsZero reports whether no limit is set.
+func (l ResourceLimits) IsZero() bool {
+	return l.MaxCPUPercent <= 0 && l.MaxMemoryBytes <= 0 && l.MaxOutputBytes <= 0 && l.MaxWallClock <= 0
+}
+
+func (l ResourceLimits) sampled() bool {
+	return l.MaxCPUPercent > 0 || l.MaxMemoryBytes > 0
+}
+
+// LimitViolation describes a session terminated for exceeding a limit.
+type LimitViolation struct {
+	SessionID string    `json:"session_id"`
+	Limit     string    `json:"limit"`
+	Detail    string    `json:"detail"`
+	At        time.Time `json:"at"`
+}
+
+// LimitResolver returns the limits for a session about to start.
+type LimitResolver func(sessionID string) ResourceLimits
+
+// LimitHandler is called once a session killed for exceeding a limit has
+// exited.
+type LimitHandler func(LimitViolation)
+
+// SetLimitResolver sets how limits are looked up for new sessions. Sessions
+// started without a resolver are unlimited.
+func (m *Manager) SetLimitResolver(fn LimitResolver) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limitResolver = fn
+}
+
+// SetLimitHandler sets the callback for sessions terminated by a limit.
+func (m *Manager) SetLimitHandler(fn LimitHandler) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limitHandler = fn
+}
+
+func (m *Manager) resolveLimits(sessionID string) ResourceLimits {
+	m.mu.RLock()
+	resolver := m.limitResolver
+	m.mu.RUnlock()
+	if resolver == nil {
+		return ResourceLimits{}
+	}
+	return resolver(sessionID)
+}
+
+// usage is one sample of the resources used by a session.
+type usage struct {
+	cpu    time.Duration // cumulative CPU time
+	memory int64         // resident bytes
+}
+
+type usageSampler interface {
+	sample() (usage, error)
+}
+
+// startLimits sets up accounting for a started session. Linux sessions are
+// moved into their own cgroup when the host delegates one to nekobot, so
+// memory is capped by the kernel and the whole tree can be killed; otherwise
+// usage is summed from /proc.
+func (m *Manager) startLimits(session *Session) {
+	limits := session.limits
+	if limits.IsZero() || session.Process == nil {
+		return
+	}
+	if cg, err := newCgroup(session.ID, limits); err == nil {
+		if err := cg.attach(session.Process.Pid); err != nil {
+			cg.remove()
+		} else {
+			session.cgroup = cg
+		}
+	}
+	var sampler usageSampler
+	if limits.sampled() {
+		sampler = newUsageSampler(session)
+	}
+	go m.watchLimits(session, sampler)
+}
+
+var newUsageSampler = func(session *Session) usageSampler {
+	procs := procSampler{pid: session.Process.Pid}
+	if session.cgroup != nil {
+		return cgroupSampler{cg: session.cgroup, fallback: procs}
+	}
+	return procs
+}
+
+// watchLimits enforces the wall clock, CPU and memory limits of a session
+// until it exits.
+func (m *Manager) watchLimits(session *Session, sampler usageSampler) {
+	limits := session.limits
+
+	var wallClock <-chan time.Time
+	if limits.MaxWallClock > 0 {
+		timer := time.NewTimer(limits.MaxWallClock - time.Since(session.StartedAt))
+		defer timer.Stop()
+		wallClock = timer.C
+	}
+	var poll <-chan time.Time
+	if sampler != nil {
+		ticker := time.NewTicker(limitPollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	var (
+		lastCPU   time.Duration
+		lastAt    = time.Now()
+		overSince time.Time
+	)
+	for {
+		select {
+		case <-session.exited:
+			return
+		case <-wallClock:
+			m.enforceLimit(session, LimitWallClock,
+				fmt.Sprintf("ran longer than %s", limits.MaxWallClock))
+			return
+		case now := <-poll:
+			current, err := sampler.sample()
+			if err != nil {
+				continue
+			}
+			if limits.MaxMemoryBytes > 0 && current.memory > limits.MaxMemoryBytes {
+				m.enforceLimit(session, LimitMemory,
+					fmt.Sprintf("used %s of memory, limit is %s", formatBytes(current.memory), formatBytes(limits.MaxMemoryBytes)))
+				return
+			}
+			if limits.MaxCPUPercent > 0 && now.After(lastAt) {
+				percent := float64(current.cpu-lastCPU) / float64(now.Sub(lastAt)) * 100
+				switch {
+				case percent <= limits.MaxCPUPercent:
+					overSince = time.Time{}
+				case overSince.IsZero():
+					overSince = lastAt
+				}
+				if !overSince.IsZero() && now.Sub(overSince) >= limits.CPUGrace {
+					m.enforceLimit(session, LimitCPU,
+						fmt.Sprintf("used %.0f%% CPU for %s, limit is %.0f%%", percent, now.Sub(overSince).Round(time.Second), limits.MaxCPUPercent))
+					return
+				}
+			}
+			lastCPU, lastAt = current.cpu, now
+		}
+	}
+}
+
+// countOutput enforces the output limit for a chunk read from the terminal.
+func (m *Manager) countOutput(session *Session, n int) {
+	limit := session.limits.MaxOutputBytes
+	if limit <= 0 {
+		return
+	}
+	if total := atomic.AddInt64(&session.outputBytes, int64(n)); total > limit {
+		m.enforceLimit(session, LimitOutput,
+			fmt.Sprintf("printed more than %s of output", formatBytes(limit)))
+	}
+}
+
+// enforceLimit records the first violation of a session and kills it.
+func (m *Manager) enforceLimit(session *Session, limit, detail string) {
+	if !session.recordViolation(limit, detail) {
+		return
+	}
+	m.log.Warn("PTY session exceeded resource limit",
+		zap.String("session_id", session.ID),
+		zap.String("limit", limit),
+		zap.String("detail", detail))
+	if session.cgroup != nil && session.cgroup.kill() == nil {
+		return
+	}
+	if err := killProcess(session.Process); err != nil {
+		m.log.Warn("Failed to kill PTY session over limit",
+			zap.String("session_id", session.ID),
+			zap.Error(err))
+	}
+}
+
+// finishLimits runs once the process has exited and reports a violation, if
+// any, to the limit handler.
+func (m *Manager) finishLimits(session *Session) *LimitViolation {
+	if session.cgroup != nil {
+		if session.cgroup.oomKilled() {
+			session.recordViolation(LimitMemory,
+				fmt.Sprintf("was killed for using more than %s of memory", formatBytes(session.limits.MaxMemoryBytes)))
+		}
+		session.cgroup.remove()
+	}
+	violation := session.limitViolation()
+	if violation == nil {
+		return nil
+	}
+	m.mu.RLock()
+	handler := m.limitHandler
+	m.mu.RUnlock()
+	if handler != nil {
+		handler(*violation)
+	}
+	return violation
+}
+
+// recordViolation keeps the first violation of a session and reports
+// whether this was it.
+func (s *Session) recordViolation(limit, detail string) bool {
+	s.limitMu.Lock()
+	defer s.limitMu.Unlock()
+	if s.violation != nil {
+		return false
+	}
+	s.violation = &LimitViolation{
+		SessionID: s.ID,
+		Limit:     limit,
+		Detail:    detail,
+		At:        time.Now(),
+	}
+	return true
+}
+
+func (s *Session) limitViolation() *LimitViolation {
+	s.limitMu.Lock()
+	defer s.limitMu.Unlock()
+	return s.violation
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,171 @@
+package process
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nekobot/pkg/execenv"
+)
+
+type fakeSampler struct {
+	mu      sync.Mutex
+	current usage
+	step    usage
+}
+
+func (f *fakeSampler) sample() (usage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.current.cpu += f.step.cpu
+	f.current.memory = f.step.memory
+	return f.current, nil
+}
+
+// startLimited starts a session under limits and returns the violations
+// reported for it.
+func startLimited(t *testing.T, limits ResourceLimits, command string) (*Manager, <-chan LimitViolation) {
+	t.Helper()
+	originalRoot := cgroupRoot
+	cgroupRoot = filepath.Join(t.TempDir(), "missing")
+	t.Cleanup(func() {
+		cgroupRoot = originalRoot
+	})
+
+	mgr := NewManager(newTestLogger(t))
+	violations := make(chan LimitViolation, 1)
+	mgr.SetLimitResolver(func(string) ResourceLimits { return limits })
+	mgr.SetLimitHandler(func(v LimitViolation) { violations <- v })
+	if err := mgr.StartWithSpec(context.Background(), execenv.StartSpec{
+		SessionID: "sess-limited",
+		Command:   command,
+		Workdir:   t.TempDir(),
+	}); err != nil {
+		t.Fatalf("StartWithSpec failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = mgr.Reset("sess-limited")
+	})
+	return mgr, violations
+}
+
+func waitForViolation(t *testing.T, mgr *Manager, violations <-chan LimitViolation, limit string) LimitViolation {
+	t.Helper()
+	var violation LimitViolation
+	select {
+	case violation = <-violations:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the %s limit to terminate the session", limit)
+	}
+	if violation.Limit != limit || violation.SessionID != "sess-limited" || violation.Detail == "" {
+		t.Fatalf("unexpected violation %+v", violation)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		status, err := mgr.GetStatus("sess-limited")
+		if err != nil {
+			t.Fatalf("GetStatus failed: %v", err)
+		}
+		if !status.Running {
+			if status.LimitExceeded != violation.Detail {
+				t.Fatalf("expected status to explain the limit, got %q", status.LimitExceeded)
+			}
+			return violation
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the session to stop after exceeding its limit")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestManagerEnforcesOutputLimit(t *testing.T) {
+	mgr, violations := startLimited(t, ResourceLimits{MaxOutputBytes: 1024}, "yes")
+	violation := waitForViolation(t, mgr, violations, LimitOutput)
+	if !strings.Contains(violation.Detail, "1.0 KiB") {
+		t.Fatalf("expected the output limit in the detail, got %q", violation.Detail)
+	}
+}
+
+func TestManagerEnforcesWallClockLimit(t *testing.T) {
+	mgr, violations := startLimited(t, ResourceLimits{MaxWallClock: 200 * time.Millisecond}, "sleep 30")
+	waitForViolation(t, mgr, violations, LimitWallClock)
+}
+
+func TestManagerEnforcesMemoryAndSustainedCPULimits(t *testing.T) {
+	originalInterval, originalSampler := limitPollInterval, newUsageSampler
+	t.Cleanup(func() {
+		limitPollInterval, newUsageSampler = originalInterval, originalSampler
+	})
+	limitPollInterval = 10 * time.Millisecond
+
+	// Two cores busy per poll interval is roughly 200% CPU.
+	newUsageSampler = func(*Session) usageSampler {
+		return &fakeSampler{step: usage{cpu: 20 * time.Millisecond, memory: 64 << 20}}
+	}
+	mgr, violations := startLimited(t, ResourceLimits{MaxCPUPercent: 150, CPUGrace: 100 * time.Millisecond}, "sleep 30")
+	waitForViolation(t, mgr, violations, LimitCPU)
+
+	newUsageSampler = func(*Session) usageSampler {
+		return &fakeSampler{step: usage{memory: 64 << 20}}
+	}
+	mgr, violations = startLimited(t, ResourceLimits{MaxMemoryBytes: 32 << 20, MaxCPUPercent: 150}, "sleep 30")
+	violation := waitForViolation(t, mgr, violations, LimitMemory)
+	if !strings.Contains(violation.Detail, "64.0 MiB") {
+		t.Fatalf("expected the memory usage in the detail, got %q", violation.Detail)
+	}
+}
+
+func TestManagerLeavesSessionsWithoutLimitsAlone(t *testing.T) {
+	mgr, violations := startLimited(t, ResourceLimits{}, "printf done")
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		status, err := mgr.GetStatus("sess-limited")
+		if err != nil {
+			t.Fatalf("GetStatus failed: %v", err)
+		}
+		if !status.Running {
+			if status.LimitExceeded != "" || status.ExitCode != 0 {
+				t.Fatalf("expected a clean exit, got %+v", status)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the session to exit")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	select {
+	case v := <-violations:
+		t.Fatalf("did not expect a violation, got %+v", v)
+	default:
+	}
+}
+
+func TestProcSamplerSumsSessionTree(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("/proc sampling is Linux only")
+	}
+	mgr, _ := startLimited(t, ResourceLimits{}, "sleep 30 & sleep 30; wait")
+	mgr.mu.RLock()
+	session := mgr.sessions["sess-limited"]
+	mgr.mu.RUnlock()
+
+	sampler := procSampler{pid: session.Process.Pid}
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		current, err := sampler.sample()
+		if err == nil && current.memory > 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected usage of the session tree, got %+v (%v)", current, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/creack/pty"
 	"go.uber.org/zap"
 
 	"nekobot/pkg/execenv"
@@ -29,7 +28,7 @@ type Session struct {
 	ExitedAt    time.Time
 	Running     bool
 	ExitCode    int
-	PTY         *os.File
+	PTY         io.ReadWriteCloser // *os.File, or pipes where PTYs are unsupported
 	Process     *os.Process
 	Output      []string
 	OutputMutex sync.RWMutex
@@ -39,6 +38,14 @@ type Session struct {
 	TaskID      string
 	RuntimeID   string
 	taskDone    sync.Once
+	outputDone  chan struct{}
+	exited      chan struct{}
+
+	limits      ResourceLimits
+	outputBytes int64
+	cgroup      *cgroup
+	limitMu     sync.Mutex
+	violation   *LimitViolation
 
 	cancelMu        sync.RWMutex
 	cancelRequested bool
@@ -82,6 +89,9 @@ func DefaultAutoResponseConfig() AutoResponseConfig {
 const (
 	defaultPTYRows = 40
 	defaultPTYCols = 120
+	// outputDrainTimeout bounds how long an exited session waits for its
+	// remaining output; background children can keep the terminal open.
+	outputDrainTimeout = time.Second
 )
 
 var killProcess = func(proc *os.Process) error {
@@ -95,6 +105,11 @@ type Manager struct {
 	mu       sync.RWMutex
 	preparer execenv.Preparer
 	taskSvc  taskLifecycle
+
+	scrollback *scrollbackStore
+
+	limitResolver LimitResolver
+	limitHandler  LimitHandler
 }
 
 type taskLifecycle interface {
@@ -135,6 +150,34 @@ func (m *Manager) SetTaskService(svc taskLifecycle) {
 	m.taskSvc = svc
 }
 
+// SetScrollback persists terminal output of every session under dir, keeping
+// up to maxBytes per session. A session started again with the same ID, for
+// example after a restart, begins with the persisted history.
+func (m *Manager) SetScrollback(dir string, maxBytes int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if strings.TrimSpace(dir) == "" {
+		m.scrollback = nil
+		return
+	}
+	m.scrollback = newScrollbackStore(dir, maxBytes, m.log)
+}
+
+// Scrollback returns the persisted output of a session, including sessions
+// whose process is gone.
+func (m *Manager) Scrollback(sessionID string) (string, error) {
+	m.mu.RLock()
+	store := m.scrollback
+	m.mu.RUnlock()
+	if store == nil {
+		return "", os.ErrNotExist
+	}
+	return store.load(sessionID)
+}
+
 // Start starts a new PTY session with the default start spec.
 func (m *Manager) Start(ctx context.Context, sessionID, command, workdir string) error {
 	return m.StartWithSpec(ctx, execenv.StartSpec{
@@ -147,6 +190,8 @@ func (m *Manager) Start(ctx context.Context, sessionID, command, workdir string)
 
 // StartWithSpec starts a new PTY session using an execution-environment preparation contract.
 func (m *Manager) StartWithSpec(ctx context.Context, spec execenv.StartSpec) error {
+	limits := m.resolveLimits(spec.SessionID)
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -172,17 +217,13 @@ func (m *Manager) StartWithSpec(ctx context.Context, spec execenv.StartSpec) err
 		return fmt.Errorf("prepare execenv: %w", err)
 	}
 
-	shellPath := resolveShellPath()
-	cmd := exec.CommandContext(ctx, shellPath, "-c", spec.Command)
+	cmd, shellPath := shellCommand(ctx, spec.Command)
 	cmd.Env = append([]string{}, prepared.Env...)
 	if prepared.Workdir != "" {
 		cmd.Dir = prepared.Workdir
 	}
 
-	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{
-		Rows: defaultPTYRows,
-		Cols: defaultPTYCols,
-	})
+	ptmx, err := startTerminal(cmd)
 	if err != nil {
 		failManagedTask(taskSvc, spec, fmt.Errorf("starting PTY: %w", err), m.log)
 		runCleanup(prepared.Cleanup, m.log, spec.SessionID)
@@ -197,22 +238,26 @@ func (m *Manager) StartWithSpec(ctx context.Context, spec execenv.StartSpec) err
 	}
 
 	session := &Session{
-		ID:        spec.SessionID,
-		Command:   spec.Command,
-		Workdir:   prepared.Workdir,
-		StartedAt: time.Now(),
-		Running:   true,
-		PTY:       ptmx,
-		Process:   cmd.Process,
-		Output:    make([]string, 0),
-		MaxOutput: 10000,
-		Cleanup:   prepared.Cleanup,
-		TaskID:    strings.TrimSpace(spec.TaskID),
-		RuntimeID: strings.TrimSpace(spec.RuntimeID),
+		ID:         spec.SessionID,
+		Command:    spec.Command,
+		Workdir:    prepared.Workdir,
+		StartedAt:  time.Now(),
+		Running:    true,
+		PTY:        ptmx,
+		Process:    cmd.Process,
+		Output:     m.restoredOutput(spec.SessionID),
+		MaxOutput:  10000,
+		outputDone: make(chan struct{}),
+		exited:     make(chan struct{}),
+		limits:     limits,
+		Cleanup:    prepared.Cleanup,
+		TaskID:     strings.TrimSpace(spec.TaskID),
+		RuntimeID:  strings.TrimSpace(spec.RuntimeID),
 	}
 
 	m.sessions[spec.SessionID] = session
 
+	m.startLimits(session)
 	go m.captureOutput(session)
 	go m.waitForExit(session, cmd)
 
@@ -249,16 +294,36 @@ func (m *Manager) Reset(sessionID string) error {
 	session.cleanupOnce.Do(func() {
 		runCleanup(session.Cleanup, m.log, sessionID)
 	})
+	if store := m.scrollbackStore(); store != nil {
+		store.remove(sessionID)
+	}
 	return nil
 }
 
+// restoredOutput seeds a new session with its persisted history.
+func (m *Manager) restoredOutput(sessionID string) []string {
+	if m.scrollback == nil {
+		return make([]string, 0)
+	}
+	history, err := m.scrollback.load(sessionID)
+	if err != nil || history == "" {
+		return make([]string, 0)
+	}
+	return []string{history}
+}
+
 // captureOutput captures raw output chunks from PTY.
 func (m *Manager) captureOutput(session *Session) {
+	defer close(session.outputDone)
 	buf := make([]byte, 4096)
 	for {
 		n, err := session.PTY.Read(buf)
 		if n > 0 {
 			chunk := string(buf[:n])
+			m.countOutput(session, n)
+			if store := m.scrollbackStore(); store != nil {
+				store.append(session.ID, chunk)
+			}
 			session.OutputMutex.Lock()
 			session.Output = append(session.Output, chunk)
 
@@ -274,14 +339,27 @@ func (m *Manager) captureOutput(session *Session) {
 					zap.String("session_id", session.ID),
 					zap.Error(err))
 			}
+			if store := m.scrollbackStore(); store != nil {
+				store.close(session.ID)
+			}
 			return
 		}
 	}
 }
 
+func (m *Manager) scrollbackStore() *scrollbackStore {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.scrollback
+}
+
 // waitForExit waits for the process to exit.
 func (m *Manager) waitForExit(session *Session, cmd *exec.Cmd) {
 	err := cmd.Wait()
+	close(session.exited)
+	// Report a limit before the session shows as exited, so owners see why
+	// it was killed rather than just an exit code.
+	violation := m.finishLimits(session)
 
 	session.OutputMutex.Lock()
 	session.Running = false
@@ -298,7 +376,10 @@ func (m *Manager) waitForExit(session *Session, cmd *exec.Cmd) {
 	}
 	session.OutputMutex.Unlock()
 
-	// Close PTY
+	select {
+	case <-session.outputDone:
+	case <-time.After(outputDrainTimeout):
+	}
 	_ = session.PTY.Close()
 	session.cleanupOnce.Do(func() {
 		runCleanup(session.Cleanup, m.log, session.ID)
@@ -306,6 +387,14 @@ func (m *Manager) waitForExit(session *Session, cmd *exec.Cmd) {
 	switch {
 	case session.cancelRequestedState():
 		cancelManagedTask(m.taskSvc, session, m.log)
+	case violation != nil:
+		failManagedTask(m.taskSvc, execenv.StartSpec{
+			SessionID: session.ID,
+			Command:   session.Command,
+			Workdir:   session.Workdir,
+			RuntimeID: session.RuntimeID,
+			TaskID:    session.TaskID,
+		}, fmt.Errorf("resource limit exceeded: %s", violation.Detail), m.log)
 	case session.ExitCode == 0:
 		completeManagedTask(m.taskSvc, session, m.log)
 	default:
@@ -468,10 +557,7 @@ func (m *Manager) Resize(sessionID string, cols, rows int) error {
 	if cols <= 0 || rows <= 0 {
 		return fmt.Errorf("invalid resize values: cols=%d rows=%d", cols, rows)
 	}
-	if err := pty.Setsize(session.PTY, &pty.Winsize{
-		Cols: uint16(cols),
-		Rows: uint16(rows),
-	}); err != nil {
+	if err := resizeTerminal(session.PTY, cols, rows); err != nil {
 		return fmt.Errorf("resize PTY: %w", err)
 	}
 	return nil
@@ -524,6 +610,9 @@ func (m *Manager) GetStatus(sessionID string) (*SessionStatus, error) {
 		OutputSize:  len(session.Output),
 		Observation: classifyObservation(session.Output),
 	}
+	if violation := session.limitViolation(); violation != nil {
+		status.LimitExceeded = violation.Detail
+	}
 
 	if session.Running {
 		status.Duration = time.Since(session.StartedAt)
@@ -554,6 +643,9 @@ func (m *Manager) List() []*SessionStatus {
 			OutputSize:  len(session.Output),
 			Observation: classifyObservation(session.Output),
 		}
+		if violation := session.limitViolation(); violation != nil {
+			status.LimitExceeded = violation.Detail
+		}
 
 		if session.Running {
 			status.Duration = time.Since(session.StartedAt)
@@ -579,6 +671,9 @@ func (m *Manager) Cleanup(maxAge time.Duration) int {
 	for id, session := range m.sessions {
 		if !session.Running && now.Sub(session.ExitedAt) > maxAge {
 			delete(m.sessions, id)
+			if m.scrollback != nil {
+				m.scrollback.remove(id)
+			}
 			count++
 			m.log.Debug("Cleaned up session", zap.String("session_id", id))
 		}
@@ -770,6 +865,9 @@ type SessionStatus struct {
 	Duration    time.Duration `json:"duration"`
 	OutputSize  int           `json:"output_size"`
 	Observation Observation   `json:"observation,omitempty"`
+	// LimitExceeded explains why the session was killed for exceeding a
+	// resource limit.
+	LimitExceeded string `json:"limit_exceeded,omitempty"`
 }
 
 func classifyObservation(chunks []string) Observation {
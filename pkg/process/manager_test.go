@@ -2,12 +2,17 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/creack/pty"
+
 	"nekobot/pkg/execenv"
 	"nekobot/pkg/logger"
 	"nekobot/pkg/tasks"
@@ -197,3 +202,126 @@ func newTestLogger(t *testing.T) *logger.Logger {
 	}
 	return log
 }
+
+func TestManagerPersistsScrollbackAcrossRestarts(t *testing.T) {
+	log := newTestLogger(t)
+	dir := t.TempDir()
+	mgr := NewManager(log)
+	mgr.SetScrollback(dir, 1024)
+
+	if err := mgr.StartWithSpec(context.Background(), execenv.StartSpec{
+		SessionID: "sess-scrollback",
+		Command:   "printf 'before restart'",
+		Workdir:   t.TempDir(),
+	}); err != nil {
+		t.Fatalf("StartWithSpec failed: %v", err)
+	}
+	waitForScrollback(t, mgr, "sess-scrollback", "before restart")
+
+	// A new manager, as after restarting nekobot, still has the history
+	// and seeds a restarted session with it.
+	restarted := NewManager(log)
+	restarted.SetScrollback(dir, 1024)
+	if history, err := restarted.Scrollback("sess-scrollback"); err != nil || !strings.Contains(history, "before restart") {
+		t.Fatalf("expected persisted scrollback, got %q (%v)", history, err)
+	}
+	if err := restarted.StartWithSpec(context.Background(), execenv.StartSpec{
+		SessionID: "sess-scrollback",
+		Command:   "sleep 30",
+		Workdir:   t.TempDir(),
+	}); err != nil {
+		t.Fatalf("StartWithSpec failed: %v", err)
+	}
+	chunks, _, err := restarted.GetOutput("sess-scrollback", 0, 0)
+	if err != nil || len(chunks) == 0 || !strings.Contains(chunks[0], "before restart") {
+		t.Fatalf("expected restarted session to begin with its history, got %q (%v)", chunks, err)
+	}
+
+	if err := restarted.Reset("sess-scrollback"); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if _, err := restarted.Scrollback("sess-scrollback"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected Reset to discard the scrollback, got %v", err)
+	}
+}
+
+func TestScrollbackStoreCompactsToLimit(t *testing.T) {
+	store := newScrollbackStore(t.TempDir(), 16, newTestLogger(t))
+	for i := 0; i < 10; i++ {
+		store.append("sess", fmt.Sprintf("line-%02d\n", i))
+	}
+	store.close("sess")
+
+	history, err := store.load("sess")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if history != "line-08\nline-09\n" {
+		t.Fatalf("expected the last 16 bytes, got %q", history)
+	}
+	info, err := os.Stat(store.path("sess"))
+	if err != nil {
+		t.Fatalf("stat failed: %v", err)
+	}
+	if info.Size() > 32 {
+		t.Fatalf("expected the file to be compacted, got %d bytes", info.Size())
+	}
+}
+
+func TestManagerFallsBackToPipesWithoutPTYSupport(t *testing.T) {
+	originalStart := startPTY
+	t.Cleanup(func() {
+		startPTY = originalStart
+	})
+	startPTY = func(*exec.Cmd, *pty.Winsize) (*os.File, error) {
+		return nil, pty.ErrUnsupported
+	}
+
+	mgr := NewManager(newTestLogger(t))
+	if err := mgr.StartWithSpec(context.Background(), execenv.StartSpec{
+		SessionID: "sess-pipes",
+		Command:   "read line; echo \"got $line\"",
+		Workdir:   t.TempDir(),
+	}); err != nil {
+		t.Fatalf("StartWithSpec failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = mgr.Reset("sess-pipes")
+	})
+	if err := mgr.Resize("sess-pipes", 80, 24); err != nil {
+		t.Fatalf("expected resize to be a no-op for pipes, got %v", err)
+	}
+	if err := mgr.Write("sess-pipes", "hello\n"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		chunks, _, err := mgr.GetOutput("sess-pipes", 0, 0)
+		if err != nil {
+			t.Fatalf("GetOutput failed: %v", err)
+		}
+		if strings.Contains(strings.Join(chunks, ""), "got hello") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected piped output, got %q", chunks)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func waitForScrollback(t *testing.T, mgr *Manager, sessionID, want string) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		history, _ := mgr.Scrollback(sessionID)
+		if strings.Contains(history, want) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected scrollback to contain %q, got %q", want, history)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
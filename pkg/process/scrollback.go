@@ -0,0 +1,149 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/logger"
+)
+
+// DefaultScrollbackBytes is the amount of terminal output kept on disk per
+// session when no limit is configured.
+const DefaultScrollbackBytes = 1 << 20
+
+// scrollbackStore persists raw terminal output per session, so the history
+// of a session is still available after the process or nekobot restarts.
+type scrollbackStore struct {
+	dir      string
+	maxBytes int64
+	log      *logger.Logger
+
+	mu    sync.Mutex
+	files map[string]*scrollbackFile
+}
+
+type scrollbackFile struct {
+	f    *os.File
+	size int64
+}
+
+func newScrollbackStore(dir string, maxBytes int, log *logger.Logger) *scrollbackStore {
+	if maxBytes <= 0 {
+		maxBytes = DefaultScrollbackBytes
+	}
+	return &scrollbackStore{
+		dir:      dir,
+		maxBytes: int64(maxBytes),
+		log:      log,
+		files:    make(map[string]*scrollbackFile),
+	}
+}
+
+func (s *scrollbackStore) path(sessionID string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(sessionID) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return filepath.Join(s.dir, b.String()+".log")
+}
+
+// load returns the persisted tail of a session's output.
+func (s *scrollbackStore) load(sessionID string) (string, error) {
+	data, err := os.ReadFile(s.path(sessionID))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > s.maxBytes {
+		data = data[int64(len(data))-s.maxBytes:]
+	}
+	return string(data), nil
+}
+
+// append writes a chunk of output. The file is compacted to the last
+// maxBytes once it grows past twice that size.
+func (s *scrollbackStore) append(sessionID, chunk string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.open(sessionID)
+	if err != nil {
+		s.log.Warn("Failed to open scrollback file", zap.String("session_id", sessionID), zap.Error(err))
+		return
+	}
+	n, err := file.f.WriteString(chunk)
+	file.size += int64(n)
+	if err != nil {
+		s.log.Warn("Failed to persist scrollback", zap.String("session_id", sessionID), zap.Error(err))
+		return
+	}
+	if file.size > 2*s.maxBytes {
+		if err := s.compact(sessionID, file); err != nil {
+			s.log.Warn("Failed to compact scrollback", zap.String("session_id", sessionID), zap.Error(err))
+		}
+	}
+}
+
+func (s *scrollbackStore) open(sessionID string) (*scrollbackFile, error) {
+	if file, ok := s.files[sessionID]; ok {
+		return file, nil
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(s.path(sessionID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	file := &scrollbackFile{f: f, size: info.Size()}
+	s.files[sessionID] = file
+	return file, nil
+}
+
+func (s *scrollbackStore) compact(sessionID string, file *scrollbackFile) error {
+	tail, err := s.load(sessionID)
+	if err != nil {
+		return err
+	}
+	_ = file.f.Close()
+	delete(s.files, sessionID)
+
+	path := s.path(sessionID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(tail), 0o600); err != nil {
+		return fmt.Errorf("write compacted scrollback: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// close releases the file handle of a session and keeps its history.
+func (s *scrollbackStore) close(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if file, ok := s.files[sessionID]; ok {
+		_ = file.f.Close()
+		delete(s.files, sessionID)
+	}
+}
+
+// remove deletes the history of a session.
+func (s *scrollbackStore) remove(sessionID string) {
+	s.close(sessionID)
+	if err := os.Remove(s.path(sessionID)); err != nil && !os.IsNotExist(err) {
+		s.log.Warn("Failed to remove scrollback", zap.String("session_id", sessionID), zap.Error(err))
+	}
+}
@@ -0,0 +1,88 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/creack/pty"
+)
+
+// pipeTerminal stands in for a PTY on platforms without one, such as
+// Windows. Input goes to stdin and stdout and stderr are merged. Programs
+// see no terminal, so full-screen TUIs will not render, but line-based
+// tools work and resizing is a no-op.
+type pipeTerminal struct {
+	stdin io.WriteCloser
+	out   *io.PipeReader
+	outW  *io.PipeWriter
+}
+
+func (p *pipeTerminal) Read(b []byte) (int, error)  { return p.out.Read(b) }
+func (p *pipeTerminal) Write(b []byte) (int, error) { return p.stdin.Write(b) }
+
+func (p *pipeTerminal) Close() error {
+	_ = p.stdin.Close()
+	_ = p.outW.Close()
+	return p.out.Close()
+}
+
+var startPTY = pty.StartWithSize
+
+// startTerminal starts cmd attached to a new PTY, falling back to pipes
+// where the platform has no PTY support.
+func startTerminal(cmd *exec.Cmd) (io.ReadWriteCloser, error) {
+	ptmx, err := startPTY(cmd, &pty.Winsize{
+		Rows: defaultPTYRows,
+		Cols: defaultPTYCols,
+	})
+	if err == nil {
+		return ptmx, nil
+	}
+	if !errors.Is(err, pty.ErrUnsupported) {
+		return nil, err
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, outW := io.Pipe()
+	cmd.Stdout = outW
+	cmd.Stderr = outW
+	if err := cmd.Start(); err != nil {
+		_ = stdin.Close()
+		_ = outW.Close()
+		return nil, err
+	}
+	return &pipeTerminal{stdin: stdin, out: out, outW: outW}, nil
+}
+
+// resizeTerminal resizes a PTY; pipe terminals have no size.
+func resizeTerminal(term io.ReadWriteCloser, cols, rows int) error {
+	f, ok := term.(*os.File)
+	if !ok {
+		return nil
+	}
+	return pty.Setsize(f, &pty.Winsize{
+		Cols: uint16(cols),
+		Rows: uint16(rows),
+	})
+}
+
+// shellCommand runs command through the platform shell.
+func shellCommand(ctx context.Context, command string) (*exec.Cmd, string) {
+	if runtime.GOOS == "windows" {
+		shell := strings.TrimSpace(os.Getenv("ComSpec"))
+		if shell == "" {
+			shell = "cmd.exe"
+		}
+		return exec.CommandContext(ctx, shell, "/C", command), shell
+	}
+	shell := resolveShellPath()
+	return exec.CommandContext(ctx, shell, "-c", command), shell
+}
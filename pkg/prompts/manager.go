@@ -138,7 +138,7 @@ func (m *Manager) UpdatePrompt(ctx context.Context, id string, item Prompt) (*Pr
 			}
 			return nil, fmt.Errorf("get prompt for auth: %w", lookupErr)
 		}
-		if !ac.CanWrite(existing.OwnerUserID) {
+		if !ac.CanWrite(existing.OwnerUserID, existing.TenantID) {
 			return nil, ownership.ErrPermissionDenied
 		}
 		normalized.OwnerUserID, normalized.TenantID, normalized.Visibility = ac.ValidateUpdateOwnership(
@@ -190,7 +190,7 @@ func (m *Manager) DeletePrompt(ctx context.Context, id string) error {
 			}
 			return fmt.Errorf("get prompt for auth: %w", lookupErr)
 		}
-		if !ac.CanWrite(existing.OwnerUserID) {
+		if !ac.CanWrite(existing.OwnerUserID, existing.TenantID) {
 			return ownership.ErrPermissionDenied
 		}
 	}
@@ -72,6 +72,12 @@ type ResolvedPromptSet struct {
 	SystemText string          `json:"system_text"`
 	UserText   string          `json:"user_text"`
 	Applied    []AppliedPrompt `json:"applied"`
+	// Pinned holds session-pinned context that must survive history trimming.
+	Pinned []string `json:"pinned,omitempty"`
+	// Persona is the session's custom system prompt, placed ahead of the default prompt.
+	Persona string `json:"persona,omitempty"`
+	// AgentProfile is the active agent profile's system prompt, placed after the persona.
+	AgentProfile string `json:"agent_profile,omitempty"`
 }
 
 // SessionBindingSet is the chat-friendly shape for session bindings.
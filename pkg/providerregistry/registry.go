@@ -46,15 +46,29 @@ var builtins = []Type{
 		AuthFields:        []Field{{Key: "api_key", Label: "API Key", Type: "password", Required: true, Secret: true}},
 		AdvancedFields:    []Field{{Key: "api_base", Label: "API Base", Type: "text", Placeholder: "https://api.anthropic.com"}},
 	},
+	{
+		ID:                "azure-openai",
+		DisplayName:       "Azure OpenAI",
+		Icon:              "azure",
+		Description:       "Azure OpenAI resource with per-model deployments.",
+		SupportsDiscovery: true,
+		Capabilities:      []string{"chat", "discovery"},
+		AuthFields: []Field{
+			{Key: "api_key", Label: "API Key", Type: "password", Required: true, Secret: true},
+			{Key: "api_base", Label: "Endpoint", Type: "text", Placeholder: "https://my-resource.openai.azure.com", Required: true},
+		},
+		AdvancedFields: []Field{{Key: "api_version", Label: "API Version", Type: "text", Placeholder: "2024-10-21"}},
+	},
 	{
 		ID:                "gemini",
 		DisplayName:       "Gemini",
 		Icon:              "gemini",
 		Description:       "Google Gemini and compatible endpoints.",
-		DefaultAPIBase:    "https://generativelanguage.googleapis.com",
+		DefaultAPIBase:    "https://generativelanguage.googleapis.com/v1beta",
 		SupportsDiscovery: true,
 		Capabilities:      []string{"chat", "discovery"},
 		AuthFields:        []Field{{Key: "api_key", Label: "API Key", Type: "password", Required: true, Secret: true}},
+		AdvancedFields:    []Field{{Key: "api_base", Label: "API Base", Type: "text", Placeholder: "https://generativelanguage.googleapis.com/v1beta"}},
 	},
 	{
 		ID:                "openrouter",
@@ -0,0 +1,308 @@
+// Package azure provides the Azure OpenAI adaptor implementation.
+// Azure serves OpenAI models from per-resource endpoints where each model is
+// reached through a named deployment and an api-version query parameter.
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"nekobot/pkg/providers"
+	"nekobot/pkg/providers/converter"
+	"nekobot/pkg/providers/streaming"
+)
+
+const (
+	// defaultAPIVersion is the GA data-plane API version used when a
+	// profile does not set one.
+	defaultAPIVersion = "2024-10-21"
+	// deploymentsAPIVersion is the last API version that lists deployments,
+	// used for model discovery when no mapping is configured.
+	deploymentsAPIVersion = "2022-12-01"
+)
+
+// Adaptor implements the providers.Adaptor interface for Azure OpenAI.
+type Adaptor struct {
+	converter  *converter.OpenAIConverter
+	httpClient *http.Client
+	info       *providers.RelayInfo
+}
+
+// New creates a new Azure OpenAI adaptor instance.
+func New() *Adaptor {
+	return &Adaptor{
+		converter: converter.NewOpenAIConverter(),
+		httpClient: &http.Client{
+			Timeout: 0, // No timeout, we handle it per-request
+		},
+	}
+}
+
+// Init initializes the adaptor with the given RelayInfo.
+func (a *Adaptor) Init(info *providers.RelayInfo) error {
+	if info.APIKey == "" {
+		return fmt.Errorf("API key is required for Azure OpenAI")
+	}
+	if strings.TrimSpace(info.APIBase) == "" {
+		return fmt.Errorf("endpoint (api_base) is required for Azure OpenAI")
+	}
+	if strings.TrimSpace(info.APIVersion) == "" {
+		info.APIVersion = defaultAPIVersion
+	}
+
+	// Setup HTTP client with proxy if provided
+	client, err := providers.NewHTTPClientWithProxy(info.Proxy)
+	if err != nil {
+		return fmt.Errorf("setting up proxy: %w", err)
+	}
+	a.httpClient = client
+	a.info = info
+
+	return nil
+}
+
+// GetRequestURL returns the chat completions URL of the deployment serving
+// the requested model.
+func (a *Adaptor) GetRequestURL(info *providers.RelayInfo) (string, error) {
+	deployment := Deployment(info.Deployments, info.Model)
+	if deployment == "" {
+		return "", fmt.Errorf("model or deployment is required for Azure OpenAI")
+	}
+	apiVersion := strings.TrimSpace(info.APIVersion)
+	if apiVersion == "" {
+		apiVersion = defaultAPIVersion
+	}
+
+	return fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		endpoint(info.APIBase), url.PathEscape(deployment), url.QueryEscape(apiVersion)), nil
+}
+
+// Deployment resolves the deployment serving model. Models without a
+// mapping are assumed to be deployed under their own name.
+func Deployment(deployments map[string]string, model string) string {
+	model = strings.TrimSpace(model)
+	if deployment := strings.TrimSpace(deployments[model]); deployment != "" {
+		return deployment
+	}
+	return model
+}
+
+// endpoint normalizes a resource endpoint such as
+// https://my-resource.openai.azure.com/ or one ending in /openai.
+func endpoint(base string) string {
+	base = strings.TrimRight(strings.TrimSpace(base), "/")
+	return strings.TrimSuffix(base, "/openai")
+}
+
+// SetupRequestHeader sets up HTTP headers for the request.
+func (a *Adaptor) SetupRequestHeader(req *http.Request, info *providers.RelayInfo) error {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("api-key", info.APIKey)
+
+	// Add custom headers if provided, but do not let callers override adaptor-owned headers.
+	for key, value := range info.Headers {
+		switch http.CanonicalHeaderKey(key) {
+		case "Api-Key", "Content-Type":
+			continue
+		default:
+			req.Header.Set(key, value)
+		}
+	}
+
+	return nil
+}
+
+// ConvertRequest converts a UnifiedRequest to provider-specific format.
+func (a *Adaptor) ConvertRequest(unified *providers.UnifiedRequest, info *providers.RelayInfo) ([]byte, error) {
+	// Azure accepts the OpenAI chat completions format
+	providerReq, err := a.converter.ToProviderRequest(unified)
+	if err != nil {
+		return nil, fmt.Errorf("converting request: %w", err)
+	}
+
+	// Marshal to JSON
+	data, err := json.Marshal(providerReq)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	return data, nil
+}
+
+// DoRequest performs the HTTP request and returns the raw response body.
+func (a *Adaptor) DoRequest(ctx context.Context, req *http.Request) ([]byte, error) {
+	resp, err := a.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp.StatusCode, body)
+	}
+
+	return body, nil
+}
+
+// DoResponse parses the provider-specific response into UnifiedResponse.
+func (a *Adaptor) DoResponse(body []byte, info *providers.RelayInfo) (*providers.UnifiedResponse, error) {
+	var providerResp interface{}
+	if err := providers.UnmarshalJSONResponse(body, &providerResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	// Use converter to transform to unified format
+	unified, err := a.converter.FromProviderResponse(providerResp)
+	if err != nil {
+		return nil, fmt.Errorf("converting response: %w", err)
+	}
+
+	return unified, nil
+}
+
+// DoStreamResponse handles streaming responses.
+func (a *Adaptor) DoStreamResponse(ctx context.Context, reader io.Reader, handler providers.StreamHandler, info *providers.RelayInfo) error {
+	// Create stream processor for SSE format
+	processor := streaming.NewStreamProcessor(ctx, reader, streaming.FormatSSE)
+
+	// Set timeout if provided
+	if info.Timeout > 0 {
+		processor.SetTimeout(time.Duration(info.Timeout) * time.Second)
+	}
+
+	// Process each chunk
+	err := processor.ProcessStream(func(chunk []byte) error {
+		// Convert chunk to unified format
+		unified, err := a.converter.FromProviderStreamChunk(chunk)
+		if err != nil {
+			// Some chunks might not be valid (e.g., content filter results), skip them
+			return nil
+		}
+
+		if unified == nil {
+			// Stream termination marker
+			return nil
+		}
+
+		// Call handler
+		if err := handler.OnChunk(unified); err != nil {
+			return fmt.Errorf("handler error: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		handler.OnError(err)
+		return err
+	}
+
+	handler.OnComplete(nil)
+	return nil
+}
+
+// GetModelList returns the configured model aliases. Without a mapping it
+// lists the resource's deployments, each usable as a model name.
+func (a *Adaptor) GetModelList() ([]string, error) {
+	if a.info == nil {
+		return nil, fmt.Errorf("adaptor is not initialized")
+	}
+	if len(a.info.Deployments) > 0 {
+		models := make([]string, 0, len(a.info.Deployments))
+		for alias := range a.info.Deployments {
+			models = append(models, alias)
+		}
+		slices.Sort(models)
+		return models, nil
+	}
+
+	client := *a.httpClient
+	if client.Timeout == 0 {
+		client.Timeout = 20 * time.Second
+		if a.info.Timeout > 0 {
+			client.Timeout = time.Duration(a.info.Timeout) * time.Second
+		}
+	}
+	req, err := http.NewRequest(http.MethodGet, endpoint(a.info.APIBase)+"/openai/deployments?api-version="+deploymentsAPIVersion, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("api-key", a.info.APIKey)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("listing deployments: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp.StatusCode, body)
+	}
+
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := providers.UnmarshalJSONResponse(body, &list); err != nil {
+		return nil, fmt.Errorf("decode deployments: %w", err)
+	}
+	models := make([]string, 0, len(list.Data))
+	for _, deployment := range list.Data {
+		if id := strings.TrimSpace(deployment.ID); id != "" {
+			models = append(models, id)
+		}
+	}
+	return models, nil
+}
+
+// parseError parses an Azure OpenAI error response.
+func parseError(statusCode int, body []byte) error {
+	var errResp struct {
+		Error struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+			Code    string `json:"code"`
+		} `json:"error"`
+	}
+
+	if err := providers.UnmarshalJSONResponse(body, &errResp); err != nil {
+		return &providers.ErrorResponse{
+			StatusCode: statusCode,
+			Message:    err.Error(),
+		}
+	}
+
+	return &providers.ErrorResponse{
+		StatusCode: statusCode,
+		Message:    errResp.Error.Message,
+		Type:       errResp.Error.Type,
+		Code:       errResp.Error.Code,
+	}
+}
+
+// init registers the Azure OpenAI adaptor with the global registry.
+func init() {
+	providers.Register("azure-openai", func() providers.Adaptor {
+		return New()
+	})
+	// Also register under "azure" alias
+	providers.Register("azure", func() providers.Adaptor {
+		return New()
+	})
+}
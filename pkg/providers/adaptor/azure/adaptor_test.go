@@ -0,0 +1,104 @@
+package azure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/providers"
+)
+
+func TestGetRequestURLMapsDeployment(t *testing.T) {
+	t.Parallel()
+
+	adaptor := New()
+	info := &providers.RelayInfo{
+		APIKey:      "key",
+		APIBase:     "https://res.openai.azure.com/openai/",
+		Model:       "gpt-4o",
+		Deployments: map[string]string{"gpt-4o": "prod-4o"},
+	}
+	if err := adaptor.Init(info); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	url, err := adaptor.GetRequestURL(info)
+	if err != nil {
+		t.Fatalf("get request url: %v", err)
+	}
+	if url != "https://res.openai.azure.com/openai/deployments/prod-4o/chat/completions?api-version="+defaultAPIVersion {
+		t.Fatalf("unexpected url %q", url)
+	}
+
+	info.Model = "gpt-4o-mini"
+	info.APIVersion = "2025-01-01-preview"
+	url, err = adaptor.GetRequestURL(info)
+	if err != nil {
+		t.Fatalf("get request url: %v", err)
+	}
+	if url != "https://res.openai.azure.com/openai/deployments/gpt-4o-mini/chat/completions?api-version=2025-01-01-preview" {
+		t.Fatalf("expected unmapped model to be used as deployment, got %q", url)
+	}
+}
+
+func TestInitRequiresEndpoint(t *testing.T) {
+	t.Parallel()
+
+	if err := New().Init(&providers.RelayInfo{APIKey: "key"}); err == nil {
+		t.Fatalf("expected missing endpoint to fail")
+	}
+}
+
+func TestSetupRequestHeaderUsesAPIKeyHeader(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequest(http.MethodPost, "https://res.openai.azure.com", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	info := &providers.RelayInfo{APIKey: "key", Headers: map[string]string{"api-key": "other", "X-Custom": "v"}}
+	if err := New().SetupRequestHeader(req, info); err != nil {
+		t.Fatalf("setup request header: %v", err)
+	}
+	if req.Header.Get("api-key") != "key" || req.Header.Get("Authorization") != "" || req.Header.Get("X-Custom") != "v" {
+		t.Fatalf("unexpected headers %v", req.Header)
+	}
+}
+
+func TestGetModelList(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments" || r.Header.Get("api-key") != "key" {
+			http.Error(w, `{"error":{"code":"401","message":"denied"}}`, http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"prod-4o","model":"gpt-4o"},{"id":"embed","model":"text-embedding-3-small"}]}`))
+	}))
+	defer server.Close()
+
+	listed := New()
+	if err := listed.Init(&providers.RelayInfo{APIKey: "key", APIBase: server.URL}); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	models, err := listed.GetModelList()
+	if err != nil {
+		t.Fatalf("get model list: %v", err)
+	}
+	if strings.Join(models, ",") != "prod-4o,embed" {
+		t.Fatalf("unexpected deployments %v", models)
+	}
+
+	mapped := New()
+	if err := mapped.Init(&providers.RelayInfo{APIKey: "key", APIBase: server.URL, Deployments: map[string]string{"gpt-4o": "prod-4o", "gpt-4.1": "prod-41"}}); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	models, err = mapped.GetModelList()
+	if err != nil {
+		t.Fatalf("get model list: %v", err)
+	}
+	if strings.Join(models, ",") != "gpt-4.1,gpt-4o" {
+		t.Fatalf("expected configured aliases, got %v", models)
+	}
+}
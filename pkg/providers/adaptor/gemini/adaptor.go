@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,10 +17,14 @@ import (
 	"nekobot/pkg/providers/streaming"
 )
 
+// defaultAPIBase is the Gemini API endpoint including its version.
+const defaultAPIBase = "https://generativelanguage.googleapis.com/v1beta"
+
 // Adaptor implements the providers.Adaptor interface for Gemini API.
 type Adaptor struct {
 	converter  *converter.GeminiConverter
 	httpClient *http.Client
+	info       *providers.RelayInfo
 }
 
 // New creates a new Gemini adaptor instance.
@@ -37,9 +43,7 @@ func (a *Adaptor) Init(info *providers.RelayInfo) error {
 		return fmt.Errorf("API key is required for Gemini")
 	}
 
-	if info.APIBase == "" {
-		info.APIBase = "https://generativelanguage.googleapis.com/v1beta"
-	}
+	info.APIBase = apiBase(info.APIBase)
 
 	// Setup HTTP client with proxy if provided
 	client, err := providers.NewHTTPClientWithProxy(info.Proxy)
@@ -47,16 +51,27 @@ func (a *Adaptor) Init(info *providers.RelayInfo) error {
 		return fmt.Errorf("setting up proxy: %w", err)
 	}
 	a.httpClient = client
+	a.info = info
 
 	return nil
 }
 
+// apiBase normalizes a configured API base. A bare host gets the v1beta
+// version path, which the generateContent and models endpoints live under.
+func apiBase(base string) string {
+	base = strings.TrimRight(strings.TrimSpace(base), "/")
+	if base == "" {
+		return defaultAPIBase
+	}
+	if !strings.Contains(base, "/v1") {
+		base += "/v1beta"
+	}
+	return base
+}
+
 // GetRequestURL returns the full URL for the API request.
 func (a *Adaptor) GetRequestURL(info *providers.RelayInfo) (string, error) {
-	baseURL := info.APIBase
-	if baseURL == "" {
-		baseURL = "https://generativelanguage.googleapis.com/v1beta"
-	}
+	baseURL := apiBase(info.APIBase)
 
 	model := info.Model
 	if model == "" {
@@ -68,19 +83,19 @@ func (a *Adaptor) GetRequestURL(info *providers.RelayInfo) (string, error) {
 		model = model[idx+1:]
 	}
 
-	// Construct URL based on streaming or not
+	// The API key travels in the x-goog-api-key header so it never shows
+	// up in logged URLs. Streaming asks for SSE instead of a JSON array.
 	if info.Stream {
-		return fmt.Sprintf("%s/models/%s:streamGenerateContent?key=%s", baseURL, model, info.APIKey), nil
+		return fmt.Sprintf("%s/models/%s:streamGenerateContent?alt=sse", baseURL, model), nil
 	}
 
-	return fmt.Sprintf("%s/models/%s:generateContent?key=%s", baseURL, model, info.APIKey), nil
+	return fmt.Sprintf("%s/models/%s:generateContent", baseURL, model), nil
 }
 
 // SetupRequestHeader sets up HTTP headers for the request.
 func (a *Adaptor) SetupRequestHeader(req *http.Request, info *providers.RelayInfo) error {
 	req.Header.Set("Content-Type", "application/json")
 
-	// Gemini uses API key in URL, but we also set it in header for consistency
 	req.Header.Set("x-goog-api-key", info.APIKey)
 
 	// Add custom headers if provided
@@ -110,29 +125,6 @@ func (a *Adaptor) ConvertRequest(unified *providers.UnifiedRequest, info *provid
 
 // DoRequest performs the HTTP request and returns the raw response body.
 func (a *Adaptor) DoRequest(ctx context.Context, req *http.Request) ([]byte, error) {
-	// Create a new context with timeout if specified
-	if deadline, ok := ctx.Deadline(); ok {
-		timeout := time.Until(deadline)
-		client := &http.Client{Timeout: timeout}
-		resp, err := client.Do(req.WithContext(ctx))
-		if err != nil {
-			return nil, fmt.Errorf("executing request: %w", err)
-		}
-		defer func() { _ = resp.Body.Close() }()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("reading response body: %w", err)
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			return nil, parseError(resp.StatusCode, body)
-		}
-
-		return body, nil
-	}
-
-	// No timeout specified, use default client
 	resp, err := a.httpClient.Do(req.WithContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("executing request: %w", err)
@@ -169,8 +161,8 @@ func (a *Adaptor) DoResponse(body []byte, info *providers.RelayInfo) (*providers
 
 // DoStreamResponse handles streaming responses.
 func (a *Adaptor) DoStreamResponse(ctx context.Context, reader io.Reader, handler providers.StreamHandler, info *providers.RelayInfo) error {
-	// Create stream processor for JSON-lines format (Gemini uses JSON-lines)
-	processor := streaming.NewStreamProcessor(ctx, reader, streaming.FormatJSONLines)
+	// Create stream processor for SSE format (requested with alt=sse)
+	processor := streaming.NewStreamProcessor(ctx, reader, streaming.FormatSSE)
 
 	// Set timeout if provided
 	if info.Timeout > 0 {
@@ -194,14 +186,9 @@ func (a *Adaptor) DoStreamResponse(ctx context.Context, reader io.Reader, handle
 			return nil
 		}
 
-		// Accumulate usage
+		// Usage metadata is cumulative, so the latest report wins.
 		if unified.Usage != nil {
-			if totalUsage == nil {
-				totalUsage = unified.Usage
-			} else {
-				// Update with latest usage info
-				totalUsage = unified.Usage
-			}
+			totalUsage = unified.Usage
 		}
 
 		// Call handler
@@ -221,19 +208,69 @@ func (a *Adaptor) DoStreamResponse(ctx context.Context, reader io.Reader, handle
 	return nil
 }
 
-// GetModelList returns a list of available models for this provider.
+// GetModelList lists the models that support generateContent from the
+// models endpoint, following pagination.
 func (a *Adaptor) GetModelList() ([]string, error) {
-	// Return a static list of Gemini models
-	return []string{
-		"gemini-2.0-flash-exp",
-		"gemini-2.0-flash-001",
-		"gemini-1.5-pro-latest",
-		"gemini-1.5-pro-001",
-		"gemini-1.5-flash-latest",
-		"gemini-1.5-flash-001",
-		"gemini-1.0-pro",
-		"gemini-1.0-pro-vision",
-	}, nil
+	if a.info == nil {
+		return nil, fmt.Errorf("adaptor is not initialized")
+	}
+
+	client := *a.httpClient
+	if client.Timeout == 0 {
+		client.Timeout = 20 * time.Second
+		if a.info.Timeout > 0 {
+			client.Timeout = time.Duration(a.info.Timeout) * time.Second
+		}
+	}
+
+	var models []string
+	pageToken := ""
+	for {
+		query := url.Values{"pageSize": {"1000"}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		req, err := http.NewRequest(http.MethodGet, apiBase(a.info.APIBase)+"/models?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		req.Header.Set("x-goog-api-key", a.info.APIKey)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("listing models: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading response body: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, parseError(resp.StatusCode, body)
+		}
+
+		var page struct {
+			Models []struct {
+				Name                       string   `json:"name"`
+				SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+			} `json:"models"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := providers.UnmarshalJSONResponse(body, &page); err != nil {
+			return nil, fmt.Errorf("decode models: %w", err)
+		}
+		for _, model := range page.Models {
+			if !slices.Contains(model.SupportedGenerationMethods, "generateContent") {
+				continue
+			}
+			models = append(models, strings.TrimPrefix(model.Name, "models/"))
+		}
+
+		if page.NextPageToken == "" {
+			return models, nil
+		}
+		pageToken = page.NextPageToken
+	}
 }
 
 // parseError parses a Gemini API error response.
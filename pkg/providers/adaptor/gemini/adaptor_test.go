@@ -0,0 +1,112 @@
+package gemini
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/providers"
+)
+
+func TestGetRequestURL(t *testing.T) {
+	t.Parallel()
+
+	adaptor := New()
+	info := &providers.RelayInfo{APIBase: "https://generativelanguage.googleapis.com/", APIKey: "secret", Model: "google/gemini-2.5-flash"}
+
+	url, err := adaptor.GetRequestURL(info)
+	if err != nil {
+		t.Fatalf("get request url: %v", err)
+	}
+	if url != "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.5-flash:generateContent" {
+		t.Fatalf("unexpected url %q", url)
+	}
+
+	info.Stream = true
+	url, err = adaptor.GetRequestURL(info)
+	if err != nil {
+		t.Fatalf("get stream url: %v", err)
+	}
+	if url != "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.5-flash:streamGenerateContent?alt=sse" {
+		t.Fatalf("unexpected stream url %q", url)
+	}
+	if strings.Contains(url, "secret") {
+		t.Fatalf("expected API key to stay out of the url, got %q", url)
+	}
+}
+
+func TestGetModelListPaginatesAndFilters(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1beta/models" || r.Header.Get("x-goog-api-key") != "key" {
+			http.Error(w, `{"error":{"code":403,"message":"denied","status":"PERMISSION_DENIED"}}`, http.StatusForbidden)
+			return
+		}
+		if r.URL.Query().Get("pageToken") == "" {
+			_, _ = w.Write([]byte(`{"models":[{"name":"models/gemini-2.5-pro","supportedGenerationMethods":["generateContent","countTokens"]},{"name":"models/text-embedding-004","supportedGenerationMethods":["embedContent"]}],"nextPageToken":"next"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"models":[{"name":"models/gemini-2.5-flash","supportedGenerationMethods":["generateContent"]}]}`))
+	}))
+	defer server.Close()
+
+	adaptor := New()
+	if err := adaptor.Init(&providers.RelayInfo{APIKey: "key", APIBase: server.URL}); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	models, err := adaptor.GetModelList()
+	if err != nil {
+		t.Fatalf("get model list: %v", err)
+	}
+	if strings.Join(models, ",") != "gemini-2.5-pro,gemini-2.5-flash" {
+		t.Fatalf("unexpected models %v", models)
+	}
+
+	denied := New()
+	if err := denied.Init(&providers.RelayInfo{APIKey: "wrong", APIBase: server.URL}); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if _, err := denied.GetModelList(); err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Fatalf("expected API error, got %v", err)
+	}
+}
+
+type recordingHandler struct {
+	chunks []*providers.UnifiedStreamChunk
+	usage  *providers.UnifiedUsage
+}
+
+func (h *recordingHandler) OnChunk(chunk *providers.UnifiedStreamChunk) error {
+	h.chunks = append(h.chunks, chunk)
+	return nil
+}
+
+func (h *recordingHandler) OnError(error) {}
+
+func (h *recordingHandler) OnComplete(usage *providers.UnifiedUsage) { h.usage = usage }
+
+func TestDoStreamResponseParsesSSE(t *testing.T) {
+	t.Parallel()
+
+	stream := "data: {\"candidates\":[{\"content\":{\"role\":\"model\",\"parts\":[{\"text\":\"Hel\"}]}}],\"usageMetadata\":{\"promptTokenCount\":4,\"totalTokenCount\":4}}\r\n\r\n" +
+		"data: {\"candidates\":[{\"content\":{\"role\":\"model\",\"parts\":[{\"text\":\"lo\"}]},\"finishReason\":\"STOP\"}],\"usageMetadata\":{\"promptTokenCount\":4,\"candidatesTokenCount\":2,\"totalTokenCount\":6}}\r\n\r\n"
+
+	handler := &recordingHandler{}
+	if err := New().DoStreamResponse(context.Background(), strings.NewReader(stream), handler, &providers.RelayInfo{}); err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+
+	var content strings.Builder
+	for _, chunk := range handler.chunks {
+		content.WriteString(chunk.Delta.Content)
+	}
+	if content.String() != "Hello" {
+		t.Fatalf("expected streamed content Hello, got %q", content.String())
+	}
+	if handler.usage == nil || handler.usage.TotalTokens != 6 || handler.usage.CompletionTokens != 2 {
+		t.Fatalf("expected final usage, got %+v", handler.usage)
+	}
+}
@@ -102,29 +102,6 @@ func (a *Adaptor) ConvertRequest(unified *providers.UnifiedRequest, info *provid
 
 // DoRequest performs the HTTP request and returns the raw response body.
 func (a *Adaptor) DoRequest(ctx context.Context, req *http.Request) ([]byte, error) {
-	// Create a new context with timeout if specified
-	if deadline, ok := ctx.Deadline(); ok {
-		timeout := time.Until(deadline)
-		client := &http.Client{Timeout: timeout}
-		resp, err := client.Do(req.WithContext(ctx))
-		if err != nil {
-			return nil, fmt.Errorf("executing request: %w", err)
-		}
-		defer func() { _ = resp.Body.Close() }()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("reading response body: %w", err)
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			return nil, parseError(resp.StatusCode, body)
-		}
-
-		return body, nil
-	}
-
-	// No timeout specified, use default client
 	resp, err := a.httpClient.Do(req.WithContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("executing request: %w", err)
@@ -0,0 +1,91 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+)
+
+// ProbeResult reports which request features a model accepted during a
+// capability probe. A nil field means the probe could not tell.
+type ProbeResult struct {
+	ToolCalling *bool
+	Streaming   *bool
+}
+
+// ProbeCapabilities sends minimal requests for model through client to find
+// out whether tool definitions and streaming are accepted. A plain request is
+// sent first; if it fails the provider is unreachable and the probe returns
+// its error. Afterwards a rejected feature request marks that feature
+// unsupported, unless the failure is transient (auth, rate limit, timeout),
+// which aborts the probe instead of caching a wrong answer.
+func ProbeCapabilities(ctx context.Context, client *Client, model string) (ProbeResult, error) {
+	var result ProbeResult
+	if client == nil {
+		return result, fmt.Errorf("client is nil")
+	}
+
+	base := func() *UnifiedRequest {
+		return &UnifiedRequest{
+			Model:     model,
+			Messages:  []UnifiedMessage{{Role: "user", Content: "Reply with: ok"}},
+			MaxTokens: 16,
+		}
+	}
+
+	if _, err := client.Chat(ctx, base()); err != nil {
+		return result, fmt.Errorf("baseline request failed: %w", err)
+	}
+
+	toolReq := base()
+	toolReq.Tools = []UnifiedTool{{
+		Type:        "function",
+		Name:        "probe",
+		Description: "Capability probe; do not call.",
+		Parameters:  map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
+	}}
+	toolCalling, err := probeFeature(ctx, model, func() error {
+		_, err := client.Chat(ctx, toolReq)
+		return err
+	})
+	if err != nil {
+		return result, fmt.Errorf("tool calling probe: %w", err)
+	}
+	result.ToolCalling = &toolCalling
+
+	streaming, err := probeFeature(ctx, model, func() error {
+		return client.ChatStream(ctx, base(), probeStreamHandler{})
+	})
+	if err != nil {
+		return result, fmt.Errorf("streaming probe: %w", err)
+	}
+	result.Streaming = &streaming
+
+	return result, nil
+}
+
+// probeFeature runs one feature request. It reports false for a rejected
+// request and returns an error when the failure says nothing about the
+// feature itself.
+func probeFeature(ctx context.Context, model string, run func() error) (bool, error) {
+	err := run()
+	if err == nil {
+		return true, nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return false, ctxErr
+	}
+	if classified := ClassifyError(err, "", model); classified != nil {
+		switch classified.Reason {
+		case FailoverReasonAuth, FailoverReasonRateLimit, FailoverReasonBilling,
+			FailoverReasonTimeout, FailoverReasonOverloaded:
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+type probeStreamHandler struct{}
+
+func (probeStreamHandler) OnChunk(*UnifiedStreamChunk) error { return nil }
+func (probeStreamHandler) OnError(error)                     {}
+func (probeStreamHandler) OnComplete(*UnifiedUsage)          {}
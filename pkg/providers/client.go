@@ -5,13 +5,16 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
+	"time"
 )
 
 // Client provides a high-level interface for making LLM API calls.
 type Client struct {
-	adaptor Adaptor
-	info    *RelayInfo
+	adaptor    Adaptor
+	info       *RelayInfo
+	middleware []Middleware
 }
 
 // NewClient creates a new provider client.
@@ -31,16 +34,60 @@ func NewClient(providerName string, info *RelayInfo) (*Client, error) {
 	}, nil
 }
 
+// Use appends middleware to the client. The first middleware added is the
+// outermost and sees each call before the others.
+func (c *Client) Use(middleware ...Middleware) {
+	for _, mw := range middleware {
+		if mw != nil {
+			c.middleware = append(c.middleware, mw)
+		}
+	}
+}
+
 // Chat performs a non-streaming chat completion request.
 func (c *Client) Chat(ctx context.Context, req *UnifiedRequest) (*UnifiedResponse, error) {
+	return c.run(ctx, c.newCall(req, nil), c.chat)
+}
+
+// ChatStream performs a streaming chat completion request.
+// Providers with streaming disabled are served by a buffered request instead.
+func (c *Client) ChatStream(ctx context.Context, req *UnifiedRequest, handler StreamHandler) error {
+	call := c.newCall(req, handler)
+	call.Stream = true
+	_, err := c.run(ctx, call, c.stream)
+	return err
+}
+
+// newCall gives each call its own copy of the relay info, so middleware
+// can adjust headers without affecting other calls on the client.
+func (c *Client) newCall(req *UnifiedRequest, handler StreamHandler) *Call {
+	info := *c.info
+	info.Headers = maps.Clone(c.info.Headers)
+	return &Call{Request: req, Info: &info, Handler: handler}
+}
+
+// run passes call through the middleware chain to terminal.
+func (c *Client) run(ctx context.Context, call *Call, terminal Next) (*UnifiedResponse, error) {
+	next := terminal
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		next = c.middleware[i](next)
+	}
+	return next(ctx, call)
+}
+
+// chat sends one non-streaming request.
+func (c *Client) chat(ctx context.Context, call *Call) (*UnifiedResponse, error) {
+	req, info := call.Request, call.Info
+	info.Stream = false
+
 	// Convert request
-	reqBody, err := c.adaptor.ConvertRequest(req, c.info)
+	reqBody, err := c.adaptor.ConvertRequest(req, info)
 	if err != nil {
 		return nil, fmt.Errorf("converting request: %w", err)
 	}
 
 	// Get request URL
-	url, err := c.adaptor.GetRequestURL(c.info)
+	url, err := c.adaptor.GetRequestURL(info)
 	if err != nil {
 		return nil, fmt.Errorf("getting request URL: %w", err)
 	}
@@ -52,18 +99,18 @@ func (c *Client) Chat(ctx context.Context, req *UnifiedRequest) (*UnifiedRespons
 	}
 
 	// Setup headers
-	if err := c.adaptor.SetupRequestHeader(httpReq, c.info); err != nil {
+	if err := c.adaptor.SetupRequestHeader(httpReq, info); err != nil {
 		return nil, fmt.Errorf("setting up request headers: %w", err)
 	}
 
 	// Execute request
-	respBody, err := c.adaptor.DoRequest(ctx, httpReq)
+	respBody, err := c.doRequest(ctx, httpReq, timeoutOf(info))
 	if err != nil {
 		return nil, fmt.Errorf("executing request: %w", err)
 	}
 
 	// Parse response
-	resp, err := c.adaptor.DoResponse(respBody, c.info)
+	resp, err := c.adaptor.DoResponse(respBody, info)
 	if err != nil {
 		return nil, fmt.Errorf("parsing response: %w", err)
 	}
@@ -71,53 +118,186 @@ func (c *Client) Chat(ctx context.Context, req *UnifiedRequest) (*UnifiedRespons
 	return resp, nil
 }
 
-// ChatStream performs a streaming chat completion request.
-func (c *Client) ChatStream(ctx context.Context, req *UnifiedRequest, handler StreamHandler) error {
+// stream sends one streaming request. The returned response carries only
+// the usage reported when the stream completed.
+func (c *Client) stream(ctx context.Context, call *Call) (*UnifiedResponse, error) {
+	req, info := call.Request, call.Info
+	if info.DisableStream {
+		return c.chatBuffered(ctx, call)
+	}
+
 	// Enable streaming in request
 	req.Stream = true
 
 	// Convert request
-	reqBody, err := c.adaptor.ConvertRequest(req, c.info)
+	reqBody, err := c.adaptor.ConvertRequest(req, info)
 	if err != nil {
-		return fmt.Errorf("converting request: %w", err)
+		return nil, fmt.Errorf("converting request: %w", err)
 	}
 
 	// Update RelayInfo to indicate streaming
-	c.info.Stream = true
+	info.Stream = true
 
 	// Get request URL
-	url, err := c.adaptor.GetRequestURL(c.info)
+	url, err := c.adaptor.GetRequestURL(info)
 	if err != nil {
-		return fmt.Errorf("getting request URL: %w", err)
+		return nil, fmt.Errorf("getting request URL: %w", err)
+	}
+
+	// The profile timeout bounds the wait between chunks rather than the
+	// whole stream, so long answers are not cut off while they still flow.
+	handler := &usageRecorder{StreamHandler: call.Handler}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	var streamHandler StreamHandler = handler
+	if timeout := timeoutOf(info); timeout > 0 {
+		watchdog := time.AfterFunc(timeout, func() { cancel(requestTimeoutError(timeout)) })
+		defer watchdog.Stop()
+		streamHandler = &idleResetHandler{StreamHandler: handler, reset: func() { watchdog.Reset(timeout) }}
 	}
 
 	// Create HTTP request
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
 	if err != nil {
-		return fmt.Errorf("creating HTTP request: %w", err)
+		return nil, fmt.Errorf("creating HTTP request: %w", err)
 	}
 
 	// Setup headers
-	if err := c.adaptor.SetupRequestHeader(httpReq, c.info); err != nil {
-		return fmt.Errorf("setting up request headers: %w", err)
+	if err := c.adaptor.SetupRequestHeader(httpReq, info); err != nil {
+		return nil, fmt.Errorf("setting up request headers: %w", err)
 	}
 
 	// Execute streaming request
-	client := &http.Client{Timeout: 0}
+	client, err := NewHTTPClientWithProxy(info.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("setting up proxy: %w", err)
+	}
 	resp, err := client.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("executing request: %w", err)
+		return nil, fmt.Errorf("executing request: %w", causeOf(ctx, err))
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP error: %d", resp.StatusCode)
+		return nil, &ErrorResponse{StatusCode: resp.StatusCode, Message: fmt.Sprintf("HTTP error: %d", resp.StatusCode)}
 	}
 
 	// Process stream
-	return c.adaptor.DoStreamResponse(ctx, resp.Body, handler, c.info)
+	if err := c.adaptor.DoStreamResponse(ctx, resp.Body, streamHandler, info); err != nil {
+		return nil, causeOf(ctx, err)
+	}
+	return &UnifiedResponse{Usage: handler.usage}, nil
+}
+
+// doRequest runs the adaptor request under the profile timeout. Adaptors
+// send the request with ctx on their proxy-aware client, so cancellation
+// and the timeout abort it; the result is still abandoned once the deadline
+// passes, so an adaptor that does not honor ctx cannot hold the turn past
+// the timeout. Errors name the timeout rather than a bare cancel.
+func (c *Client) doRequest(ctx context.Context, httpReq *http.Request, timeout time.Duration) ([]byte, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, requestTimeoutError(timeout))
+		defer cancel()
+		httpReq = httpReq.WithContext(ctx)
+	}
+
+	type result struct {
+		body []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		body, err := c.adaptor.DoRequest(ctx, httpReq)
+		done <- result{body: body, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return nil, causeOf(ctx, r.err)
+		}
+		return r.body, nil
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+}
+
+func timeoutOf(info *RelayInfo) time.Duration {
+	if info == nil || info.Timeout <= 0 {
+		return 0
+	}
+	return time.Duration(info.Timeout) * time.Second
+}
+
+// requestTimeoutError wraps context.DeadlineExceeded so failover treats a
+// request cut off by the profile timeout as a timeout.
+func requestTimeoutError(timeout time.Duration) error {
+	return fmt.Errorf("provider request timed out after %s: %w", timeout, context.DeadlineExceeded)
+}
+
+// causeOf replaces err with the timeout cause when ctx was cut off by the
+// profile timeout, so the error names the timeout instead of a bare cancel.
+func causeOf(ctx context.Context, err error) error {
+	if ctx.Err() == nil {
+		return err
+	}
+	if cause := context.Cause(ctx); cause != nil && cause != ctx.Err() {
+		return cause
+	}
+	return err
+}
+
+// idleResetHandler restarts the stream idle timer on every chunk.
+type idleResetHandler struct {
+	StreamHandler
+	reset func()
+}
+
+func (h *idleResetHandler) OnChunk(chunk *UnifiedStreamChunk) error {
+	h.reset()
+	return h.StreamHandler.OnChunk(chunk)
+}
+
+// usageRecorder keeps the usage a stream reports on completion.
+type usageRecorder struct {
+	StreamHandler
+	usage *UnifiedUsage
+}
+
+func (h *usageRecorder) OnComplete(usage *UnifiedUsage) {
+	h.usage = usage
+	h.StreamHandler.OnComplete(usage)
+}
+
+// chatBuffered replays a non-streaming response through a stream handler.
+func (c *Client) chatBuffered(ctx context.Context, call *Call) (*UnifiedResponse, error) {
+	call.Request.Stream = false
+	resp, err := c.chat(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	chunk := &UnifiedStreamChunk{
+		ID:    resp.ID,
+		Model: resp.Model,
+		Delta: UnifiedDelta{
+			Role:      "assistant",
+			Content:   resp.Content,
+			Thinking:  resp.Thinking,
+			ToolCalls: resp.ToolCalls,
+		},
+		FinishReason: resp.FinishReason,
+		Usage:        resp.Usage,
+		Extra:        resp.Extra,
+	}
+	if err := call.Handler.OnChunk(chunk); err != nil {
+		return nil, err
+	}
+	call.Handler.OnComplete(resp.Usage)
+	return resp, nil
 }
 
 // GetModelList returns a list of available models for this provider.
@@ -0,0 +1,136 @@
+package providers_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"nekobot/pkg/providers"
+	_ "nekobot/pkg/providers/init"
+)
+
+// newSlowServer answers only after the request is abandoned, or after a
+// generous cap so a broken client cannot hang the test binary.
+func newSlowServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Drain the body so the server notices when the client hangs up.
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func assertTimeoutError(t *testing.T, err error, started time.Time) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected timeout error")
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("expected request to be cut off near the 1s timeout, took %s", elapsed)
+	}
+	classified := providers.ClassifyError(err, "slow", "gpt-test")
+	if classified == nil || classified.Reason != providers.FailoverReasonTimeout {
+		t.Fatalf("expected timeout failover reason, got %+v (err: %v)", classified, err)
+	}
+}
+
+func TestClientChatCancelsSlowProviderAtTimeout(t *testing.T) {
+	server := newSlowServer(t)
+
+	client, err := providers.NewClient("openai", &providers.RelayInfo{
+		ProviderName: "slow",
+		APIKey:       "test-key",
+		APIBase:      server.URL,
+		Model:        "gpt-test",
+		Timeout:      1,
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	started := time.Now()
+	_, err = client.Chat(context.Background(), &providers.UnifiedRequest{
+		Model:    "gpt-test",
+		Messages: []providers.UnifiedMessage{{Role: "user", Content: "hi"}},
+	})
+	assertTimeoutError(t, err, started)
+}
+
+func TestClientChatStreamCancelsStalledStreamAtTimeout(t *testing.T) {
+	server := newSlowServer(t)
+
+	client, err := providers.NewClient("openai", &providers.RelayInfo{
+		ProviderName: "slow",
+		APIKey:       "test-key",
+		APIBase:      server.URL,
+		Model:        "gpt-test",
+		Timeout:      1,
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	started := time.Now()
+	err = client.ChatStream(context.Background(), &providers.UnifiedRequest{
+		Model:    "gpt-test",
+		Messages: []providers.UnifiedMessage{{Role: "user", Content: "hi"}},
+	}, &providers.SimpleStreamHandler{})
+	assertTimeoutError(t, err, started)
+}
+
+// ctxIgnoringAdaptor blocks in DoRequest without looking at ctx, like a
+// misbehaving third-party adaptor.
+type ctxIgnoringAdaptor struct {
+	release chan struct{}
+}
+
+func (a *ctxIgnoringAdaptor) Init(*providers.RelayInfo) error { return nil }
+func (a *ctxIgnoringAdaptor) GetRequestURL(*providers.RelayInfo) (string, error) {
+	return "http://127.0.0.1/unused", nil
+}
+func (a *ctxIgnoringAdaptor) SetupRequestHeader(*http.Request, *providers.RelayInfo) error {
+	return nil
+}
+func (a *ctxIgnoringAdaptor) ConvertRequest(*providers.UnifiedRequest, *providers.RelayInfo) ([]byte, error) {
+	return []byte("{}"), nil
+}
+func (a *ctxIgnoringAdaptor) DoRequest(context.Context, *http.Request) ([]byte, error) {
+	<-a.release
+	return []byte("{}"), nil
+}
+func (a *ctxIgnoringAdaptor) DoResponse([]byte, *providers.RelayInfo) (*providers.UnifiedResponse, error) {
+	return &providers.UnifiedResponse{}, nil
+}
+func (a *ctxIgnoringAdaptor) DoStreamResponse(context.Context, io.Reader, providers.StreamHandler, *providers.RelayInfo) error {
+	return nil
+}
+func (a *ctxIgnoringAdaptor) GetModelList() ([]string, error) { return nil, nil }
+
+func TestClientChatTimesOutAdaptorIgnoringContext(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	providers.Register("ctx-ignoring-test", func() providers.Adaptor {
+		return &ctxIgnoringAdaptor{release: release}
+	})
+
+	client, err := providers.NewClient("ctx-ignoring-test", &providers.RelayInfo{
+		ProviderName: "slow",
+		Model:        "gpt-test",
+		Timeout:      1,
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	started := time.Now()
+	_, err = client.Chat(context.Background(), &providers.UnifiedRequest{Model: "gpt-test"})
+	assertTimeoutError(t, err, started)
+}
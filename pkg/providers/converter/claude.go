@@ -1,6 +1,7 @@
 package converter
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -28,7 +29,7 @@ type claudeThinkingConfig struct {
 type claudeRequest struct {
 	Model       string                   `json:"model"`
 	Messages    []claudeMessage          `json:"messages"`
-	System      string                   `json:"system,omitempty"`
+	System      interface{}              `json:"system,omitempty"` // string, or text blocks when caching
 	MaxTokens   int                      `json:"max_tokens"`
 	Temperature float64                  `json:"temperature,omitempty"`
 	TopP        float64                  `json:"top_p,omitempty"`
@@ -53,12 +54,32 @@ type claudeResponse struct {
 	Model        string                   `json:"model"`
 	StopReason   string                   `json:"stop_reason"`
 	StopSequence string                   `json:"stop_sequence,omitempty"`
-	Usage        struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-	} `json:"usage"`
+	Usage        claudeUsage              `json:"usage"`
+}
+
+// claudeUsage reports token usage. InputTokens excludes the tokens read from
+// or written to the prompt cache.
+type claudeUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+}
+
+func (u claudeUsage) toUnified() *providers.UnifiedUsage {
+	prompt := u.InputTokens + u.CacheReadInputTokens + u.CacheCreationInputTokens
+	return &providers.UnifiedUsage{
+		PromptTokens:     prompt,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      prompt + u.OutputTokens,
+		CacheReadTokens:  u.CacheReadInputTokens,
+		CacheWriteTokens: u.CacheCreationInputTokens,
+	}
 }
 
+// claudeCacheControl marks the end of a cacheable prompt prefix.
+var claudeCacheControl = map[string]interface{}{"type": "ephemeral"}
+
 // claudeStreamChunk represents a streaming event in Claude format.
 type claudeStreamChunk struct {
 	Type  string                 `json:"type"`
@@ -83,9 +104,10 @@ func (c *ClaudeConverter) ToProviderRequest(unified *providers.UnifiedRequest) (
 	systemMsgs, conversationMsgs := c.ExtractSystemMessages(unified.Messages)
 	systemText := c.MergeSystemMessages(systemMsgs)
 
+	promptCaching, _ := unified.Extra["prompt_caching"].(bool)
+
 	req := claudeRequest{
 		Model:       unified.Model,
-		System:      systemText,
 		MaxTokens:   unified.MaxTokens,
 		Temperature: unified.Temperature,
 		TopP:        unified.TopP,
@@ -93,6 +115,21 @@ func (c *ClaudeConverter) ToProviderRequest(unified *providers.UnifiedRequest) (
 		ToolChoice:  unified.ToolChoice,
 	}
 
+	// With prompt caching the system prompt is sent as a text block marked
+	// as a cache breakpoint, so the tools and system prompt are reused
+	// between turns.
+	if systemText != "" {
+		if promptCaching {
+			req.System = []map[string]interface{}{{
+				"type":          "text",
+				"text":          systemText,
+				"cache_control": claudeCacheControl,
+			}}
+		} else {
+			req.System = systemText
+		}
+	}
+
 	// Default max_tokens if not set (required by Claude)
 	if req.MaxTokens == 0 {
 		req.MaxTokens = 4096
@@ -132,6 +169,13 @@ func (c *ClaudeConverter) ToProviderRequest(unified *providers.UnifiedRequest) (
 			}
 		}
 
+		// Add images
+		for _, part := range msg.Parts {
+			if block := claudeImageBlock(part); block != nil {
+				claudeMsg.Content = append(claudeMsg.Content, block)
+			}
+		}
+
 		// Add tool calls
 		for _, tc := range msg.ToolCalls {
 			claudeMsg.Content = append(claudeMsg.Content, map[string]interface{}{
@@ -155,10 +199,27 @@ func (c *ClaudeConverter) ToProviderRequest(unified *providers.UnifiedRequest) (
 				"input_schema": tool.Parameters,
 			}
 		}
+		if promptCaching {
+			req.Tools[len(req.Tools)-1]["cache_control"] = claudeCacheControl
+		}
+	}
+
+	// Claude has no JSON mode, so a response format becomes a tool whose
+	// input schema is the format schema, and the model is forced to call it.
+	// The reply is then read from the tool input, see
+	// providers.StructuredContent.
+	if format := unified.ResponseFormat; format != nil {
+		req.Tools = append(req.Tools, map[string]interface{}{
+			"name":         format.Name,
+			"description":  "Return the final answer as structured data.",
+			"input_schema": format.ToolSchema(),
+		})
+		req.ToolChoice = map[string]interface{}{"type": "tool", "name": format.Name}
 	}
 
-	// Apply extended thinking if configured via Extra
-	if unified.Extra != nil {
+	// Apply extended thinking if configured via Extra. Thinking cannot be
+	// combined with a forced tool call, so structured requests skip it.
+	if unified.Extra != nil && unified.ResponseFormat == nil {
 		if enabled, ok := unified.Extra["extended_thinking"].(bool); ok && enabled {
 			budget := 10000 // default budget
 			if b, ok := unified.Extra["thinking_budget"].(int); ok && b > 0 {
@@ -177,6 +238,23 @@ func (c *ClaudeConverter) ToProviderRequest(unified *providers.UnifiedRequest) (
 	return req, nil
 }
 
+// claudeImageBlock encodes an image part as an image content block with a
+// base64 or URL source. It returns nil for other parts.
+func claudeImageBlock(part providers.ContentPart) map[string]interface{} {
+	if part.Type != providers.ContentPartImage {
+		return nil
+	}
+	source := map[string]interface{}{"type": "url", "url": part.URL}
+	if len(part.Data) > 0 {
+		source = map[string]interface{}{
+			"type":       "base64",
+			"media_type": part.MIMEType,
+			"data":       base64.StdEncoding.EncodeToString(part.Data),
+		}
+	}
+	return map[string]interface{}{"type": "image", "source": source}
+}
+
 // FromProviderResponse converts a Claude response to UnifiedResponse.
 func (c *ClaudeConverter) FromProviderResponse(providerResp interface{}) (*providers.UnifiedResponse, error) {
 	// Re-marshal and unmarshal to convert to claudeResponse type
@@ -193,11 +271,7 @@ func (c *ClaudeConverter) FromProviderResponse(providerResp interface{}) (*provi
 	unified := &providers.UnifiedResponse{
 		ID:    resp.ID,
 		Model: resp.Model,
-		Usage: &providers.UnifiedUsage{
-			PromptTokens:     resp.Usage.InputTokens,
-			CompletionTokens: resp.Usage.OutputTokens,
-			TotalTokens:      resp.Usage.InputTokens + resp.Usage.OutputTokens,
-		},
+		Usage: resp.Usage.toUnified(),
 	}
 
 	// Convert stop_reason to finish_reason
@@ -269,6 +343,7 @@ func (c *ClaudeConverter) FromProviderStreamChunk(rawChunk []byte) (*providers.U
 		if chunk.Message != nil {
 			unified.ID = chunk.Message.ID
 			unified.Model = chunk.Message.Model
+			unified.Usage = chunk.Message.Usage.toUnified()
 		}
 
 	case "content_block_start":
@@ -365,3 +365,154 @@ func TestToProviderRequest_DefaultMaxTokens(t *testing.T) {
 		t.Fatal("expected default max_tokens of 4096")
 	}
 }
+
+func TestToProviderRequest_PromptCaching(t *testing.T) {
+	c := NewClaudeConverter()
+
+	req := &providers.UnifiedRequest{
+		Model: "claude-sonnet-4-5-20250929",
+		Messages: []providers.UnifiedMessage{
+			{Role: "system", Content: "You are a helpful assistant."},
+			{Role: "user", Content: "Hi"},
+		},
+		Tools: []providers.UnifiedTool{
+			{Type: "function", Name: "read_file", Parameters: map[string]interface{}{"type": "object"}},
+			{Type: "function", Name: "exec", Parameters: map[string]interface{}{"type": "object"}},
+		},
+		Extra: map[string]interface{}{"prompt_caching": true},
+	}
+
+	result, err := c.ToProviderRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(result)
+	var raw struct {
+		System []map[string]interface{} `json:"system"`
+		Tools  []map[string]interface{} `json:"tools"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("expected system as text blocks: %v", err)
+	}
+	if len(raw.System) != 1 || raw.System[0]["text"] != "You are a helpful assistant." || raw.System[0]["cache_control"] == nil {
+		t.Fatalf("expected a cached system block, got %v", raw.System)
+	}
+	if raw.Tools[0]["cache_control"] != nil || raw.Tools[1]["cache_control"] == nil {
+		t.Fatalf("expected only the last tool to be a cache breakpoint, got %v", raw.Tools)
+	}
+}
+
+func TestFromProviderResponse_CacheUsage(t *testing.T) {
+	c := NewClaudeConverter()
+
+	resp := map[string]interface{}{
+		"id":          "msg_1",
+		"model":       "claude-sonnet-4-5-20250929",
+		"stop_reason": "end_turn",
+		"content":     []interface{}{map[string]interface{}{"type": "text", "text": "Hi"}},
+		"usage": map[string]interface{}{
+			"input_tokens":                20,
+			"output_tokens":               10,
+			"cache_read_input_tokens":     3000,
+			"cache_creation_input_tokens": 500,
+		},
+	}
+
+	unified, err := c.FromProviderResponse(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usage := unified.Usage
+	if usage.PromptTokens != 3520 || usage.CacheReadTokens != 3000 || usage.CacheWriteTokens != 500 || usage.TotalTokens != 3530 {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+}
+
+func TestFromProviderStreamChunk_MessageStartUsage(t *testing.T) {
+	c := NewClaudeConverter()
+
+	chunk := `{"type":"message_start","message":{"id":"msg_1","model":"claude","usage":{"input_tokens":5,"output_tokens":1,"cache_read_input_tokens":2000}}}`
+
+	unified, err := c.FromProviderStreamChunk([]byte(chunk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unified.Usage == nil || unified.Usage.PromptTokens != 2005 || unified.Usage.CacheReadTokens != 2000 {
+		t.Fatalf("expected prompt usage from message_start, got %+v", unified.Usage)
+	}
+}
+
+func TestToProviderRequest_ResponseFormatForcesTool(t *testing.T) {
+	c := NewClaudeConverter()
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"city": map[string]interface{}{"type": "string"}},
+		"required":   []interface{}{"city"},
+	}
+	req := &providers.UnifiedRequest{
+		Model:          "claude-sonnet-4-5-20250929",
+		Messages:       []providers.UnifiedMessage{{Role: "user", Content: "Where is the Louvre?"}},
+		ResponseFormat: &providers.ResponseFormat{Type: providers.ResponseFormatJSONSchema, Name: "place", Schema: schema},
+		Extra:          map[string]interface{}{"extended_thinking": true},
+	}
+
+	result, err := c.ToProviderRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(result)
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("unmarshal structured request: %v", err)
+	}
+
+	tools, _ := raw["tools"].([]interface{})
+	if len(tools) != 1 {
+		t.Fatalf("expected the format tool, got %v", raw["tools"])
+	}
+	tool, _ := tools[0].(map[string]interface{})
+	if tool["name"] != "place" || tool["input_schema"] == nil {
+		t.Fatalf("expected a tool named after the format with its schema, got %v", tool)
+	}
+	choice, _ := raw["tool_choice"].(map[string]interface{})
+	if choice["type"] != "tool" || choice["name"] != "place" {
+		t.Fatalf("expected the format tool to be forced, got %v", raw["tool_choice"])
+	}
+	if _, hasThinking := raw["thinking"]; hasThinking {
+		t.Fatal("thinking should be skipped for a forced tool call")
+	}
+}
+
+func TestToProviderRequest_ImagePartsBecomeImageBlocks(t *testing.T) {
+	c := NewClaudeConverter()
+
+	req := &providers.UnifiedRequest{
+		Model: "claude-sonnet-4-5-20250929",
+		Messages: []providers.UnifiedMessage{{
+			Role:    "user",
+			Content: "What is this?",
+			Parts: []providers.ContentPart{
+				{Type: providers.ContentPartImage, MIMEType: "image/png", Data: []byte("png")},
+				{Type: providers.ContentPartImage, MIMEType: "image/jpeg", URL: "https://example.com/cat.jpg"},
+			},
+		}},
+	}
+
+	result, err := c.ToProviderRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := result.(claudeRequest).Messages[0].Content
+	if len(content) != 3 || content[0]["type"] != "text" {
+		t.Fatalf("expected text followed by two images, got %v", content)
+	}
+	inline, _ := content[1]["source"].(map[string]interface{})
+	if content[1]["type"] != "image" || inline["type"] != "base64" || inline["media_type"] != "image/png" || inline["data"] != "cG5n" {
+		t.Fatalf("expected a base64 image block, got %v", content[1])
+	}
+	linked, _ := content[2]["source"].(map[string]interface{})
+	if linked["type"] != "url" || linked["url"] != "https://example.com/cat.jpg" {
+		t.Fatalf("expected a URL image block, got %v", content[2])
+	}
+}
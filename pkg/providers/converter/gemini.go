@@ -1,10 +1,13 @@
 package converter
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/google/uuid"
+
 	"nekobot/pkg/providers"
 )
 
@@ -23,6 +26,7 @@ type geminiRequest struct {
 	Contents          []geminiContent         `json:"contents"`
 	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
 	Tools             []geminiTool            `json:"tools,omitempty"`
+	ToolConfig        *geminiToolConfig       `json:"toolConfig,omitempty"`
 	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
 }
 
@@ -41,17 +45,29 @@ type geminiTool struct {
 }
 
 // geminiFunctionDeclaration represents a function declaration in Gemini format.
+// Parameters are sent as plain JSON Schema, which Gemini accepts without the
+// OpenAPI subset restrictions of the legacy parameters field.
 type geminiFunctionDeclaration struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	Parameters  map[string]interface{} `json:"parameters"`
+	Name                 string                 `json:"name"`
+	Description          string                 `json:"description"`
+	ParametersJSONSchema map[string]interface{} `json:"parametersJsonSchema,omitempty"`
+}
+
+// geminiToolConfig controls whether and how the model calls functions.
+type geminiToolConfig struct {
+	FunctionCallingConfig struct {
+		Mode                 string   `json:"mode"`
+		AllowedFunctionNames []string `json:"allowedFunctionNames,omitempty"`
+	} `json:"functionCallingConfig"`
 }
 
 // geminiGenerationConfig represents generation configuration.
 type geminiGenerationConfig struct {
-	Temperature     float64 `json:"temperature,omitempty"`
-	TopP            float64 `json:"topP,omitempty"`
-	MaxOutputTokens int     `json:"maxOutputTokens,omitempty"`
+	Temperature        float64                `json:"temperature,omitempty"`
+	TopP               float64                `json:"topP,omitempty"`
+	MaxOutputTokens    int                    `json:"maxOutputTokens,omitempty"`
+	ResponseMimeType   string                 `json:"responseMimeType,omitempty"`
+	ResponseJSONSchema map[string]interface{} `json:"responseJsonSchema,omitempty"`
 }
 
 // geminiResponse represents the Gemini API response format.
@@ -68,11 +84,31 @@ type geminiResponse struct {
 			Probability string `json:"probability"`
 		} `json:"safetyRatings"`
 	} `json:"candidates"`
-	UsageMetadata struct {
-		PromptTokenCount     int `json:"promptTokenCount"`
-		CandidatesTokenCount int `json:"candidatesTokenCount"`
-		TotalTokenCount      int `json:"totalTokenCount"`
-	} `json:"usageMetadata"`
+	UsageMetadata geminiUsage `json:"usageMetadata"`
+}
+
+// geminiUsage reports token usage. PromptTokenCount includes the cached
+// tokens; thinking tokens are reported apart from the candidates.
+type geminiUsage struct {
+	PromptTokenCount        int `json:"promptTokenCount"`
+	CandidatesTokenCount    int `json:"candidatesTokenCount"`
+	ThoughtsTokenCount      int `json:"thoughtsTokenCount"`
+	CachedContentTokenCount int `json:"cachedContentTokenCount"`
+	TotalTokenCount         int `json:"totalTokenCount"`
+}
+
+func (u geminiUsage) toUnified() *providers.UnifiedUsage {
+	completion := u.CandidatesTokenCount + u.ThoughtsTokenCount
+	total := u.TotalTokenCount
+	if total == 0 {
+		total = u.PromptTokenCount + completion
+	}
+	return &providers.UnifiedUsage{
+		PromptTokens:     u.PromptTokenCount,
+		CompletionTokens: completion,
+		TotalTokens:      total,
+		CacheReadTokens:  u.CachedContentTokenCount,
+	}
 }
 
 // geminiStreamChunk represents a streaming chunk in Gemini format.
@@ -85,11 +121,7 @@ type geminiStreamChunk struct {
 		FinishReason string `json:"finishReason,omitempty"`
 		Index        int    `json:"index"`
 	} `json:"candidates"`
-	UsageMetadata *struct {
-		PromptTokenCount     int `json:"promptTokenCount"`
-		CandidatesTokenCount int `json:"candidatesTokenCount"`
-		TotalTokenCount      int `json:"totalTokenCount"`
-	} `json:"usageMetadata,omitempty"`
+	UsageMetadata *geminiUsage `json:"usageMetadata,omitempty"`
 }
 
 // ToProviderRequest converts a UnifiedRequest to Gemini format.
@@ -110,16 +142,46 @@ func (c *GeminiConverter) ToProviderRequest(unified *providers.UnifiedRequest) (
 		}
 	}
 
+	// Tool results only carry the call ID, while Gemini matches function
+	// responses by name, so remember the name of every earlier call.
+	toolNames := make(map[string]string)
+	for _, msg := range conversationMsgs {
+		for _, tc := range msg.ToolCalls {
+			toolNames[tc.ID] = tc.Name
+		}
+	}
+
 	// Convert messages
 	req.Contents = make([]geminiContent, 0, len(conversationMsgs))
-	for _, msg := range conversationMsgs {
-		// Convert role: "assistant" -> "model", "tool" -> "user"
+	for i, msg := range conversationMsgs {
+		if msg.Role == "tool" {
+			name := msg.Name
+			if name == "" {
+				name = toolNames[msg.ToolCallID]
+			}
+			part := geminiPart{
+				"functionResponse": map[string]interface{}{
+					"id":   msg.ToolCallID,
+					"name": name,
+					"response": map[string]interface{}{
+						"content": msg.Content,
+					},
+				},
+			}
+			// All responses to one model turn go back in a single content.
+			if i > 0 && conversationMsgs[i-1].Role == "tool" && len(req.Contents) > 0 {
+				last := &req.Contents[len(req.Contents)-1]
+				last.Parts = append(last.Parts, part)
+			} else {
+				req.Contents = append(req.Contents, geminiContent{Role: "user", Parts: []geminiPart{part}})
+			}
+			continue
+		}
+
+		// Convert role: "assistant" -> "model"
 		role := msg.Role
-		switch role {
-		case "assistant":
+		if role == "assistant" {
 			role = "model"
-		case "tool":
-			role = "user"
 		}
 
 		content := geminiContent{
@@ -127,36 +189,32 @@ func (c *GeminiConverter) ToProviderRequest(unified *providers.UnifiedRequest) (
 			Parts: make([]geminiPart, 0),
 		}
 
-		// Add text content
 		if msg.Content != "" {
-			if msg.ToolCallID != "" {
-				// Function response
-				content.Parts = append(content.Parts, geminiPart{
-					"functionResponse": map[string]interface{}{
-						"name": msg.Name,
-						"response": map[string]interface{}{
-							"content": msg.Content,
-						},
-					},
-				})
-			} else {
-				// Regular text
-				content.Parts = append(content.Parts, geminiPart{
-					"text": msg.Content,
-				})
+			content.Parts = append(content.Parts, geminiPart{
+				"text": msg.Content,
+			})
+		}
+		for _, part := range msg.Parts {
+			if image := geminiImagePart(part); image != nil {
+				content.Parts = append(content.Parts, image)
 			}
 		}
 
 		// Add function calls
 		for _, tc := range msg.ToolCalls {
-			content.Parts = append(content.Parts, geminiPart{
-				"functionCall": map[string]interface{}{
-					"name": tc.Name,
-					"args": tc.Arguments,
-				},
-			})
+			call := map[string]interface{}{
+				"id":   tc.ID,
+				"name": tc.Name,
+			}
+			if tc.Arguments != nil {
+				call["args"] = tc.Arguments
+			}
+			content.Parts = append(content.Parts, geminiPart{"functionCall": call})
 		}
 
+		if len(content.Parts) == 0 {
+			continue
+		}
 		req.Contents = append(req.Contents, content)
 	}
 
@@ -165,18 +223,19 @@ func (c *GeminiConverter) ToProviderRequest(unified *providers.UnifiedRequest) (
 		functionDecls := make([]geminiFunctionDeclaration, len(unified.Tools))
 		for i, tool := range unified.Tools {
 			functionDecls[i] = geminiFunctionDeclaration{
-				Name:        tool.Name,
-				Description: tool.Description,
-				Parameters:  tool.Parameters,
+				Name:                 tool.Name,
+				Description:          tool.Description,
+				ParametersJSONSchema: tool.Parameters,
 			}
 		}
 		req.Tools = []geminiTool{
 			{FunctionDeclarations: functionDecls},
 		}
+		req.ToolConfig = geminiToolChoice(unified.ToolChoice)
 	}
 
 	// Generation config
-	if unified.Temperature > 0 || unified.TopP > 0 || unified.MaxTokens > 0 {
+	if unified.Temperature > 0 || unified.TopP > 0 || unified.MaxTokens > 0 || unified.ResponseFormat != nil {
 		req.GenerationConfig = &geminiGenerationConfig{
 			Temperature:     unified.Temperature,
 			TopP:            unified.TopP,
@@ -184,6 +243,12 @@ func (c *GeminiConverter) ToProviderRequest(unified *providers.UnifiedRequest) (
 		}
 	}
 
+	// JSON mode, constrained by a plain JSON Schema when one is given.
+	if format := unified.ResponseFormat; format != nil {
+		req.GenerationConfig.ResponseMimeType = "application/json"
+		req.GenerationConfig.ResponseJSONSchema = format.Schema
+	}
+
 	return req, nil
 }
 
@@ -201,11 +266,7 @@ func (c *GeminiConverter) FromProviderResponse(providerResp interface{}) (*provi
 	}
 
 	unified := &providers.UnifiedResponse{
-		Usage: &providers.UnifiedUsage{
-			PromptTokens:     resp.UsageMetadata.PromptTokenCount,
-			CompletionTokens: resp.UsageMetadata.CandidatesTokenCount,
-			TotalTokens:      resp.UsageMetadata.TotalTokenCount,
-		},
+		Usage: resp.UsageMetadata.toUnified(),
 	}
 
 	if len(resp.Candidates) == 0 {
@@ -214,37 +275,18 @@ func (c *GeminiConverter) FromProviderResponse(providerResp interface{}) (*provi
 	}
 
 	candidate := resp.Candidates[0]
-
-	// Convert finish_reason
-	switch candidate.FinishReason {
-	case "STOP":
-		unified.FinishReason = "stop"
-	case "MAX_TOKENS":
-		unified.FinishReason = "length"
-	case "SAFETY":
-		unified.FinishReason = "content_filter"
-	case "RECITATION":
-		unified.FinishReason = "content_filter"
-	default:
-		unified.FinishReason = strings.ToLower(candidate.FinishReason)
-	}
+	unified.FinishReason = geminiFinishReason(candidate.FinishReason)
 
 	// Parse parts
 	for _, part := range candidate.Content.Parts {
-		if text, ok := part["text"].(string); ok {
+		text, thought, call := parseGeminiPart(part)
+		if thought {
+			unified.Thinking += text
+		} else {
 			unified.Content += text
 		}
-
-		if functionCall, ok := part["functionCall"].(map[string]interface{}); ok {
-			name, _ := functionCall["name"].(string)
-			args, _ := functionCall["args"].(map[string]interface{})
-
-			unified.ToolCalls = append(unified.ToolCalls, providers.UnifiedToolCall{
-				ID:        name, // Gemini doesn't provide IDs, use name as ID
-				Type:      "function",
-				Name:      name,
-				Arguments: args,
-			})
+		if call != nil {
+			unified.ToolCalls = append(unified.ToolCalls, *call)
 		}
 	}
 
@@ -274,11 +316,7 @@ func (c *GeminiConverter) FromProviderStreamChunk(rawChunk []byte) (*providers.U
 	unified := &providers.UnifiedStreamChunk{}
 
 	if chunk.UsageMetadata != nil {
-		unified.Usage = &providers.UnifiedUsage{
-			PromptTokens:     chunk.UsageMetadata.PromptTokenCount,
-			CompletionTokens: chunk.UsageMetadata.CandidatesTokenCount,
-			TotalTokens:      chunk.UsageMetadata.TotalTokenCount,
-		}
+		unified.Usage = chunk.UsageMetadata.toUnified()
 	}
 
 	if len(chunk.Candidates) == 0 {
@@ -286,46 +324,110 @@ func (c *GeminiConverter) FromProviderStreamChunk(rawChunk []byte) (*providers.U
 	}
 
 	candidate := chunk.Candidates[0]
-
-	// Convert finish_reason
 	if candidate.FinishReason != "" {
-		switch candidate.FinishReason {
-		case "STOP":
-			unified.FinishReason = "stop"
-		case "MAX_TOKENS":
-			unified.FinishReason = "length"
-		case "SAFETY":
-			unified.FinishReason = "content_filter"
-		case "RECITATION":
-			unified.FinishReason = "content_filter"
-		default:
-			unified.FinishReason = strings.ToLower(candidate.FinishReason)
-		}
+		unified.FinishReason = geminiFinishReason(candidate.FinishReason)
 	}
 
-	// Parse parts for delta
+	// Parse parts for delta. Gemini streams each function call whole.
 	for _, part := range candidate.Content.Parts {
-		if text, ok := part["text"].(string); ok {
+		text, thought, call := parseGeminiPart(part)
+		if thought {
+			unified.Delta.Thinking += text
+		} else {
 			unified.Delta.Content += text
 		}
-
-		if functionCall, ok := part["functionCall"].(map[string]interface{}); ok {
-			name, _ := functionCall["name"].(string)
-			args, _ := functionCall["args"].(map[string]interface{})
-
-			unified.Delta.ToolCalls = append(unified.Delta.ToolCalls, providers.UnifiedToolCall{
-				ID:        name,
-				Type:      "function",
-				Name:      name,
-				Arguments: args,
-			})
+		if call != nil {
+			unified.Delta.ToolCalls = append(unified.Delta.ToolCalls, *call)
 		}
 	}
 
 	// If we have function calls, set finish_reason to tool_calls
-	if len(unified.Delta.ToolCalls) > 0 && unified.FinishReason == "" {
+	if len(unified.Delta.ToolCalls) > 0 {
 		unified.FinishReason = "tool_calls"
 	}
 
 	return unified, nil
 }
+
+// parseGeminiPart returns the text of a part, whether that text is the
+// model's thinking, and the function call it carries, if any.
+func parseGeminiPart(part geminiPart) (string, bool, *providers.UnifiedToolCall) {
+	text, _ := part["text"].(string)
+	thought, _ := part["thought"].(bool)
+
+	functionCall, ok := part["functionCall"].(map[string]interface{})
+	if !ok {
+		return text, thought, nil
+	}
+	name, _ := functionCall["name"].(string)
+	args, _ := functionCall["args"].(map[string]interface{})
+	// Older models do not return call IDs; generate one so repeated calls
+	// of the same function stay distinct.
+	id, _ := functionCall["id"].(string)
+	if id == "" {
+		id = "call_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	}
+	return text, thought, &providers.UnifiedToolCall{
+		ID:        id,
+		Type:      "function",
+		Name:      name,
+		Arguments: args,
+	}
+}
+
+// geminiFinishReason maps a Gemini finish reason to the unified one.
+func geminiFinishReason(reason string) string {
+	switch reason {
+	case "STOP":
+		return "stop"
+	case "MAX_TOKENS":
+		return "length"
+	case "SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII":
+		return "content_filter"
+	default:
+		return strings.ToLower(reason)
+	}
+}
+
+// geminiImagePart encodes an image part as inline data, or as file data
+// when it only has a URL. It returns nil for other parts.
+func geminiImagePart(part providers.ContentPart) geminiPart {
+	if part.Type != providers.ContentPartImage {
+		return nil
+	}
+	if len(part.Data) == 0 {
+		return geminiPart{"fileData": map[string]interface{}{"mimeType": part.MIMEType, "fileUri": part.URL}}
+	}
+	return geminiPart{"inlineData": map[string]interface{}{
+		"mimeType": part.MIMEType,
+		"data":     base64.StdEncoding.EncodeToString(part.Data),
+	}}
+}
+
+// geminiToolChoice maps an OpenAI-style tool_choice to a Gemini tool config.
+// Unset or unknown choices leave the model to decide.
+func geminiToolChoice(choice interface{}) *geminiToolConfig {
+	cfg := &geminiToolConfig{}
+	switch value := choice.(type) {
+	case string:
+		switch value {
+		case "none":
+			cfg.FunctionCallingConfig.Mode = "NONE"
+		case "required", "any":
+			cfg.FunctionCallingConfig.Mode = "ANY"
+		default:
+			return nil
+		}
+	case map[string]interface{}:
+		function, _ := value["function"].(map[string]interface{})
+		name, _ := function["name"].(string)
+		if name == "" {
+			return nil
+		}
+		cfg.FunctionCallingConfig.Mode = "ANY"
+		cfg.FunctionCallingConfig.AllowedFunctionNames = []string{name}
+	default:
+		return nil
+	}
+	return cfg
+}
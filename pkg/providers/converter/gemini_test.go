@@ -0,0 +1,179 @@
+package converter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"nekobot/pkg/providers"
+)
+
+func TestGeminiToProviderRequest_FunctionCalling(t *testing.T) {
+	c := NewGeminiConverter()
+
+	req := &providers.UnifiedRequest{
+		Model: "gemini-2.5-flash",
+		Messages: []providers.UnifiedMessage{
+			{Role: "system", Content: "Be brief."},
+			{Role: "user", Content: "Weather in Paris and Rome?"},
+			{Role: "assistant", ToolCalls: []providers.UnifiedToolCall{
+				{ID: "call_1", Name: "weather", Arguments: map[string]interface{}{"city": "Paris"}},
+				{ID: "call_2", Name: "weather", Arguments: map[string]interface{}{"city": "Rome"}},
+			}},
+			{Role: "tool", ToolCallID: "call_1", Content: "sunny"},
+			{Role: "tool", ToolCallID: "call_2", Content: ""},
+		},
+		Tools: []providers.UnifiedTool{{
+			Type:        "function",
+			Name:        "weather",
+			Description: "Look up the weather",
+			Parameters:  map[string]interface{}{"type": "object", "additionalProperties": false},
+		}},
+		ToolChoice: "required",
+	}
+
+	result, err := c.ToProviderRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(result)
+	var geminiReq geminiRequest
+	if err := json.Unmarshal(data, &geminiReq); err != nil {
+		t.Fatalf("unmarshal gemini request: %v", err)
+	}
+
+	if geminiReq.SystemInstruction == nil || geminiReq.SystemInstruction.Parts[0]["text"] != "Be brief." {
+		t.Fatalf("expected system instruction, got %+v", geminiReq.SystemInstruction)
+	}
+	if len(geminiReq.Contents) != 3 {
+		t.Fatalf("expected user, model and merged tool contents, got %+v", geminiReq.Contents)
+	}
+	model := geminiReq.Contents[1]
+	if model.Role != "model" || len(model.Parts) != 2 {
+		t.Fatalf("expected model turn with two function calls, got %+v", model)
+	}
+	responses := geminiReq.Contents[2]
+	if responses.Role != "user" || len(responses.Parts) != 2 {
+		t.Fatalf("expected both function responses in one user turn, got %+v", responses)
+	}
+	for i, part := range responses.Parts {
+		response, _ := part["functionResponse"].(map[string]interface{})
+		if response["name"] != "weather" {
+			t.Fatalf("expected response %d to be named after its call, got %+v", i, response)
+		}
+	}
+	if geminiReq.Tools[0].FunctionDeclarations[0].ParametersJSONSchema["additionalProperties"] != false {
+		t.Fatalf("expected JSON schema to pass through, got %+v", geminiReq.Tools)
+	}
+	if geminiReq.ToolConfig == nil || geminiReq.ToolConfig.FunctionCallingConfig.Mode != "ANY" {
+		t.Fatalf("expected required tool choice to map to ANY, got %+v", geminiReq.ToolConfig)
+	}
+}
+
+func TestGeminiFromProviderResponse_ToolCalls(t *testing.T) {
+	c := NewGeminiConverter()
+
+	raw := map[string]interface{}{
+		"candidates": []interface{}{map[string]interface{}{
+			"content": map[string]interface{}{
+				"role": "model",
+				"parts": []interface{}{
+					map[string]interface{}{"text": "Checking.", "thought": true},
+					map[string]interface{}{"functionCall": map[string]interface{}{"name": "weather", "args": map[string]interface{}{"city": "Paris"}}},
+					map[string]interface{}{"functionCall": map[string]interface{}{"name": "weather", "args": map[string]interface{}{"city": "Rome"}}},
+				},
+			},
+			"finishReason": "STOP",
+		}},
+		"usageMetadata": map[string]interface{}{
+			"promptTokenCount":        100,
+			"candidatesTokenCount":    20,
+			"thoughtsTokenCount":      10,
+			"cachedContentTokenCount": 40,
+			"totalTokenCount":         130,
+		},
+	}
+
+	resp, err := c.FromProviderResponse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.FinishReason != "tool_calls" || resp.Thinking != "Checking." || resp.Content != "" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if len(resp.ToolCalls) != 2 || resp.ToolCalls[0].ID == "" || resp.ToolCalls[0].ID == resp.ToolCalls[1].ID {
+		t.Fatalf("expected two calls with distinct IDs, got %+v", resp.ToolCalls)
+	}
+	if resp.ToolCalls[1].Arguments["city"] != "Rome" {
+		t.Fatalf("expected call arguments, got %+v", resp.ToolCalls[1])
+	}
+	usage := resp.Usage
+	if usage.PromptTokens != 100 || usage.CompletionTokens != 30 || usage.TotalTokens != 130 || usage.CacheReadTokens != 40 {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+}
+
+func TestGeminiFromProviderStreamChunk(t *testing.T) {
+	c := NewGeminiConverter()
+
+	chunk, err := c.FromProviderStreamChunk([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"id":"fc_1","name":"weather","args":{"city":"Paris"}}}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":5,"candidatesTokenCount":3,"totalTokenCount":8}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunk.FinishReason != "tool_calls" || len(chunk.Delta.ToolCalls) != 1 || chunk.Delta.ToolCalls[0].ID != "fc_1" {
+		t.Fatalf("unexpected chunk %+v", chunk)
+	}
+	if chunk.Usage == nil || chunk.Usage.TotalTokens != 8 {
+		t.Fatalf("expected usage, got %+v", chunk.Usage)
+	}
+}
+
+func TestGeminiToProviderRequest_ResponseFormat(t *testing.T) {
+	c := NewGeminiConverter()
+
+	schema := map[string]interface{}{"type": "object", "required": []interface{}{"city"}}
+	req := &providers.UnifiedRequest{
+		Model:          "gemini-2.5-flash",
+		Messages:       []providers.UnifiedMessage{{Role: "user", Content: "Where is the Louvre?"}},
+		ResponseFormat: &providers.ResponseFormat{Type: providers.ResponseFormatJSONSchema, Name: "place", Schema: schema},
+	}
+
+	result, err := c.ToProviderRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(result)
+	var geminiReq geminiRequest
+	if err := json.Unmarshal(data, &geminiReq); err != nil {
+		t.Fatalf("unmarshal gemini request: %v", err)
+	}
+	config := geminiReq.GenerationConfig
+	if config == nil || config.ResponseMimeType != "application/json" || config.ResponseJSONSchema["type"] != "object" {
+		t.Fatalf("expected JSON mode with the schema, got %+v", config)
+	}
+}
+
+func TestGeminiToProviderRequest_ImageParts(t *testing.T) {
+	c := NewGeminiConverter()
+
+	req := &providers.UnifiedRequest{
+		Model: "gemini-2.5-flash",
+		Messages: []providers.UnifiedMessage{{
+			Role:    "user",
+			Content: "What is this?",
+			Parts:   []providers.ContentPart{{Type: providers.ContentPartImage, MIMEType: "image/png", Data: []byte("png")}},
+		}},
+	}
+
+	result, err := c.ToProviderRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := result.(geminiRequest).Contents[0].Parts
+	if len(parts) != 2 {
+		t.Fatalf("expected text and image parts, got %v", parts)
+	}
+	inline, _ := parts[1]["inlineData"].(map[string]interface{})
+	if inline["mimeType"] != "image/png" || inline["data"] != "cG5n" {
+		t.Fatalf("expected inline image data, got %v", parts[1])
+	}
+}
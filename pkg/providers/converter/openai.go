@@ -20,21 +20,22 @@ func NewOpenAIConverter() *OpenAIConverter {
 
 // openAIRequest represents the OpenAI API request format.
 type openAIRequest struct {
-	Model       string                   `json:"model"`
-	Messages    []openAIMessage          `json:"messages"`
-	MaxTokens   int                      `json:"max_tokens,omitempty"`
-	Temperature float64                  `json:"temperature,omitempty"`
-	TopP        float64                  `json:"top_p,omitempty"`
-	Stream      bool                     `json:"stream,omitempty"`
-	Tools       []map[string]interface{} `json:"tools,omitempty"`
-	ToolChoice  interface{}              `json:"tool_choice,omitempty"`
-	User        string                   `json:"user,omitempty"`
+	Model          string                   `json:"model"`
+	Messages       []openAIMessage          `json:"messages"`
+	MaxTokens      int                      `json:"max_tokens,omitempty"`
+	Temperature    float64                  `json:"temperature,omitempty"`
+	TopP           float64                  `json:"top_p,omitempty"`
+	Stream         bool                     `json:"stream,omitempty"`
+	Tools          []map[string]interface{} `json:"tools,omitempty"`
+	ToolChoice     interface{}              `json:"tool_choice,omitempty"`
+	User           string                   `json:"user,omitempty"`
+	ResponseFormat map[string]interface{}   `json:"response_format,omitempty"`
 }
 
 // openAIMessage represents a single message in OpenAI format.
 type openAIMessage struct {
 	Role       string           `json:"role"`
-	Content    string           `json:"content,omitempty"`
+	Content    interface{}      `json:"content,omitempty"` // string, or content parts with images
 	Name       string           `json:"name,omitempty"`
 	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
 	ToolCallID string           `json:"tool_call_id,omitempty"`
@@ -92,13 +93,14 @@ type openAIStreamChunk struct {
 // ToProviderRequest converts a UnifiedRequest to OpenAI format.
 func (c *OpenAIConverter) ToProviderRequest(unified *providers.UnifiedRequest) (interface{}, error) {
 	req := openAIRequest{
-		Model:       unified.Model,
-		MaxTokens:   unified.MaxTokens,
-		Temperature: unified.Temperature,
-		TopP:        unified.TopP,
-		Stream:      unified.Stream,
-		ToolChoice:  unified.ToolChoice,
-		User:        unified.User,
+		Model:          unified.Model,
+		MaxTokens:      unified.MaxTokens,
+		Temperature:    unified.Temperature,
+		TopP:           unified.TopP,
+		Stream:         unified.Stream,
+		ToolChoice:     unified.ToolChoice,
+		User:           unified.User,
+		ResponseFormat: openAIResponseFormat(unified.ResponseFormat),
 	}
 
 	// Convert messages
@@ -106,7 +108,7 @@ func (c *OpenAIConverter) ToProviderRequest(unified *providers.UnifiedRequest) (
 	for i, msg := range unified.Messages {
 		oaiMsg := openAIMessage{
 			Role:       msg.Role,
-			Content:    msg.Content,
+			Content:    openAIContent(msg),
 			Name:       msg.Name,
 			ToolCallID: msg.ToolCallID,
 		}
@@ -263,3 +265,50 @@ func (c *OpenAIConverter) FromProviderStreamChunk(rawChunk []byte) (*providers.U
 
 	return unified, nil
 }
+
+// openAIResponseFormat maps a unified response format to the OpenAI
+// response_format parameter.
+func openAIResponseFormat(format *providers.ResponseFormat) map[string]interface{} {
+	if format == nil {
+		return nil
+	}
+	if format.Type != providers.ResponseFormatJSONSchema {
+		return map[string]interface{}{"type": format.Type}
+	}
+	jsonSchema := map[string]interface{}{
+		"name":   format.Name,
+		"schema": format.Schema,
+	}
+	if format.Strict {
+		jsonSchema["strict"] = true
+	}
+	return map[string]interface{}{
+		"type":        providers.ResponseFormatJSONSchema,
+		"json_schema": jsonSchema,
+	}
+}
+
+// openAIContent returns the message text, or content parts when the message
+// carries images. Empty text is left out.
+func openAIContent(msg providers.UnifiedMessage) interface{} {
+	if len(msg.Parts) == 0 {
+		if msg.Content == "" {
+			return nil
+		}
+		return msg.Content
+	}
+	parts := make([]map[string]interface{}, 0, len(msg.Parts)+1)
+	if msg.Content != "" {
+		parts = append(parts, map[string]interface{}{"type": "text", "text": msg.Content})
+	}
+	for _, part := range msg.Parts {
+		if part.Type != providers.ContentPartImage {
+			continue
+		}
+		parts = append(parts, map[string]interface{}{
+			"type":      "image_url",
+			"image_url": map[string]interface{}{"url": part.DataURL()},
+		})
+	}
+	return parts
+}
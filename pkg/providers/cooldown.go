@@ -1,22 +1,46 @@
 package providers
 
 import (
+	"cmp"
 	"math"
+	"slices"
 	"sync"
 	"time"
 )
 
 const (
 	defaultFailureWindow = 24 * time.Hour
+
+	// healthSmoothing weighs the newest sample in the rolling latency and
+	// error rate averages.
+	healthSmoothing = 0.3
+	// unhealthyErrorRate is the rolling error rate above which latency
+	// routing stops preferring a provider.
+	unhealthyErrorRate = 0.5
+)
+
+// CircuitState is the circuit breaker state of a provider.
+type CircuitState string
+
+const (
+	// CircuitClosed providers serve requests normally.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen providers are skipped until their cooldown ends.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen providers have served their cooldown and are on
+	// trial: one success closes the circuit, one failure reopens it.
+	CircuitHalfOpen CircuitState = "half_open"
 )
 
-// CooldownTracker manages per-provider cooldown state with exponential backoff.
+// CooldownTracker manages per-provider health: a circuit breaker with
+// exponential backoff plus rolling latency and error rate.
 // Thread-safe via sync.RWMutex. In-memory only (resets on restart).
 type CooldownTracker struct {
-	mu            sync.RWMutex
-	entries       map[string]*cooldownEntry
-	failureWindow time.Duration
-	nowFunc       func() time.Time // for testing
+	mu               sync.RWMutex
+	entries          map[string]*cooldownEntry
+	failureWindow    time.Duration
+	failureThreshold int
+	nowFunc          func() time.Time // for testing
 }
 
 type cooldownEntry struct {
@@ -26,6 +50,9 @@ type cooldownEntry struct {
 	DisabledUntil  time.Time      // billing-specific disable expiry
 	DisabledReason FailoverReason // reason for disable (billing)
 	LastFailure    time.Time
+	Latency        time.Duration // rolling average of successful requests
+	LatencySamples int
+	ErrorRate      float64 // rolling share of failed requests
 }
 
 // CooldownSnapshot captures the current runtime state of a provider cooldown entry.
@@ -39,17 +66,31 @@ type CooldownSnapshot struct {
 	DisabledUntil     time.Time
 	DisabledReason    FailoverReason
 	LastFailure       time.Time
+	CircuitState      CircuitState
+	Latency           time.Duration
+	LatencySamples    int
+	ErrorRate         float64
 }
 
-// NewCooldownTracker creates a tracker with default 24h failure window.
+// NewCooldownTracker creates a tracker with default 24h failure window that
+// opens a provider's circuit on its first failure.
 func NewCooldownTracker() *CooldownTracker {
 	return &CooldownTracker{
-		entries:       make(map[string]*cooldownEntry),
-		failureWindow: defaultFailureWindow,
-		nowFunc:       time.Now,
+		entries:          make(map[string]*cooldownEntry),
+		failureWindow:    defaultFailureWindow,
+		failureThreshold: 1,
+		nowFunc:          time.Now,
 	}
 }
 
+// SetFailureThreshold sets how many consecutive failures open a provider's
+// circuit. Values below 1 mean 1.
+func (ct *CooldownTracker) SetFailureThreshold(threshold int) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	ct.failureThreshold = max(1, threshold)
+}
+
 // MarkFailure records a failure for a provider and sets appropriate cooldown.
 // Resets error counts if last failure was more than failureWindow ago.
 func (ct *CooldownTracker) MarkFailure(provider string, reason FailoverReason) {
@@ -68,26 +109,27 @@ func (ct *CooldownTracker) MarkFailure(provider string, reason FailoverReason) {
 	entry.ErrorCount++
 	entry.FailureCounts[reason]++
 	entry.LastFailure = now
+	entry.ErrorRate += healthSmoothing * (1 - entry.ErrorRate)
 
 	if reason == FailoverReasonBilling {
 		billingCount := entry.FailureCounts[FailoverReasonBilling]
 		entry.DisabledUntil = now.Add(calculateBillingCooldown(billingCount))
 		entry.DisabledReason = FailoverReasonBilling
-	} else {
-		entry.CooldownEnd = now.Add(calculateStandardCooldown(entry.ErrorCount))
+	} else if opened := entry.ErrorCount - ct.threshold() + 1; opened > 0 {
+		// The circuit opens at the threshold; each further failure, such as
+		// a failed half-open trial, lengthens the cooldown.
+		entry.CooldownEnd = now.Add(calculateStandardCooldown(opened))
 	}
 }
 
-// MarkSuccess resets all counters and cooldowns for a provider.
+// MarkSuccess closes the provider's circuit, resetting all counters and
+// cooldowns.
 func (ct *CooldownTracker) MarkSuccess(provider string) {
 	ct.mu.Lock()
 	defer ct.mu.Unlock()
 
-	entry := ct.entries[provider]
-	if entry == nil {
-		return
-	}
-
+	entry := ct.getOrCreate(provider)
+	entry.ErrorRate -= healthSmoothing * entry.ErrorRate
 	entry.ErrorCount = 0
 	entry.FailureCounts = make(map[FailoverReason]int)
 	entry.CooldownEnd = time.Time{}
@@ -95,6 +137,54 @@ func (ct *CooldownTracker) MarkSuccess(provider string) {
 	entry.DisabledReason = ""
 }
 
+// RecordLatency adds the duration of a successful request to the
+// provider's rolling latency.
+func (ct *CooldownTracker) RecordLatency(provider string, latency time.Duration) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	entry := ct.getOrCreate(provider)
+	if entry.LatencySamples == 0 {
+		entry.Latency = latency
+	} else {
+		entry.Latency += time.Duration(healthSmoothing * float64(latency-entry.Latency))
+	}
+	entry.LatencySamples++
+}
+
+// RankByLatency reorders providers for latency routing. Healthy providers
+// come first: those without latency samples in their given order, so each
+// gets measured, then the rest from fastest to slowest. Providers that are
+// cooling down or fail more often than not keep their order at the end.
+func (ct *CooldownTracker) RankByLatency(providers []string) []string {
+	ct.mu.RLock()
+	defer ct.mu.RUnlock()
+
+	now := ct.nowFunc()
+	var unmeasured, measured, unhealthy []string
+	for _, provider := range providers {
+		entry := ct.entries[provider]
+		switch {
+		case entry == nil:
+			unmeasured = append(unmeasured, provider)
+		case !entry.available(now) || entry.ErrorRate > unhealthyErrorRate:
+			unhealthy = append(unhealthy, provider)
+		case entry.LatencySamples == 0:
+			unmeasured = append(unmeasured, provider)
+		default:
+			measured = append(measured, provider)
+		}
+	}
+	slices.SortStableFunc(measured, func(a, b string) int {
+		return cmp.Compare(ct.entries[a].Latency, ct.entries[b].Latency)
+	})
+
+	ranked := make([]string, 0, len(providers))
+	ranked = append(ranked, unmeasured...)
+	ranked = append(ranked, measured...)
+	return append(ranked, unhealthy...)
+}
+
 // ClearProvider resets all counters and cooldown state for one provider.
 func (ct *CooldownTracker) ClearProvider(provider string) {
 	ct.mu.Lock()
@@ -111,16 +201,17 @@ func (ct *CooldownTracker) IsAvailable(provider string) bool {
 	if entry == nil {
 		return true
 	}
+	return entry.available(ct.nowFunc())
+}
 
-	now := ct.nowFunc()
-
+func (e *cooldownEntry) available(now time.Time) bool {
 	// Billing disable takes precedence (longer cooldown).
-	if !entry.DisabledUntil.IsZero() && now.Before(entry.DisabledUntil) {
+	if !e.DisabledUntil.IsZero() && now.Before(e.DisabledUntil) {
 		return false
 	}
 
 	// Standard cooldown.
-	if !entry.CooldownEnd.IsZero() && now.Before(entry.CooldownEnd) {
+	if !e.CooldownEnd.IsZero() && now.Before(e.CooldownEnd) {
 		return false
 	}
 
@@ -193,6 +284,7 @@ func (ct *CooldownTracker) Snapshot(provider string) CooldownSnapshot {
 		return CooldownSnapshot{
 			Available:     true,
 			FailureCounts: map[FailoverReason]int{},
+			CircuitState:  CircuitClosed,
 		}
 	}
 
@@ -212,6 +304,14 @@ func (ct *CooldownTracker) Snapshot(provider string) CooldownSnapshot {
 		}
 	}
 
+	circuit := CircuitClosed
+	switch {
+	case remaining > 0:
+		circuit = CircuitOpen
+	case entry.ErrorCount >= ct.threshold():
+		circuit = CircuitHalfOpen
+	}
+
 	return CooldownSnapshot{
 		Available:         remaining <= 0,
 		InCooldown:        remaining > 0,
@@ -222,9 +322,17 @@ func (ct *CooldownTracker) Snapshot(provider string) CooldownSnapshot {
 		DisabledUntil:     entry.DisabledUntil,
 		DisabledReason:    entry.DisabledReason,
 		LastFailure:       entry.LastFailure,
+		CircuitState:      circuit,
+		Latency:           entry.Latency,
+		LatencySamples:    entry.LatencySamples,
+		ErrorRate:         entry.ErrorRate,
 	}
 }
 
+func (ct *CooldownTracker) threshold() int {
+	return max(1, ct.failureThreshold)
+}
+
 func (ct *CooldownTracker) getOrCreate(provider string) *cooldownEntry {
 	entry := ct.entries[provider]
 	if entry == nil {
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -117,3 +118,70 @@ func TestCooldownTrackerClearProvider(t *testing.T) {
 		t.Fatalf("expected failure counts reset, got %+v", snapshot.FailureCounts)
 	}
 }
+
+func TestCooldownTrackerOpensCircuitAtThreshold(t *testing.T) {
+	tracker := NewCooldownTracker()
+	tracker.SetFailureThreshold(3)
+	now := time.Date(2026, 3, 25, 10, 0, 0, 0, time.UTC)
+	tracker.nowFunc = func() time.Time { return now }
+
+	tracker.MarkFailure("primary", FailoverReasonTimeout)
+	tracker.MarkFailure("primary", FailoverReasonTimeout)
+	if snapshot := tracker.Snapshot("primary"); !snapshot.Available || snapshot.CircuitState != CircuitClosed {
+		t.Fatalf("expected circuit to stay closed below the threshold, got %+v", snapshot)
+	}
+
+	tracker.MarkFailure("primary", FailoverReasonTimeout)
+	snapshot := tracker.Snapshot("primary")
+	if snapshot.Available || snapshot.CircuitState != CircuitOpen || snapshot.CooldownRemaining != 5*time.Second {
+		t.Fatalf("expected circuit to open for 5s at the threshold, got %+v", snapshot)
+	}
+
+	now = now.Add(6 * time.Second)
+	if snapshot := tracker.Snapshot("primary"); !snapshot.Available || snapshot.CircuitState != CircuitHalfOpen {
+		t.Fatalf("expected half-open circuit after the cooldown, got %+v", snapshot)
+	}
+
+	// A failed trial reopens the circuit for longer.
+	tracker.MarkFailure("primary", FailoverReasonTimeout)
+	if snapshot := tracker.Snapshot("primary"); snapshot.CircuitState != CircuitOpen || snapshot.CooldownRemaining != 30*time.Second {
+		t.Fatalf("expected failed trial to reopen the circuit for 30s, got %+v", snapshot)
+	}
+
+	now = now.Add(time.Minute)
+	tracker.MarkSuccess("primary")
+	snapshot = tracker.Snapshot("primary")
+	if snapshot.CircuitState != CircuitClosed || snapshot.ErrorCount != 0 {
+		t.Fatalf("expected success to close the circuit, got %+v", snapshot)
+	}
+	if snapshot.ErrorRate <= 0 || snapshot.ErrorRate >= 1 {
+		t.Fatalf("expected a rolling error rate between 0 and 1, got %v", snapshot.ErrorRate)
+	}
+}
+
+func TestCooldownTrackerRankByLatency(t *testing.T) {
+	tracker := NewCooldownTracker()
+	tracker.RecordLatency("slow", 2*time.Second)
+	tracker.RecordLatency("fast", 300*time.Millisecond)
+	tracker.RecordLatency("flaky", 100*time.Millisecond)
+	tracker.MarkFailure("flaky", FailoverReasonRateLimit)
+	tracker.RecordLatency("erratic", 50*time.Millisecond)
+	tracker.SetFailureThreshold(5)
+	for range 3 {
+		tracker.MarkFailure("erratic", FailoverReasonTimeout)
+	}
+
+	got := tracker.RankByLatency([]string{"slow", "flaky", "new", "erratic", "fast"})
+	want := []string{"new", "fast", "slow", "flaky", "erratic"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected ranking %v, got %v", want, got)
+	}
+
+	// Later samples move the rolling average.
+	for range 10 {
+		tracker.RecordLatency("slow", 100*time.Millisecond)
+	}
+	if got := tracker.RankByLatency([]string{"fast", "slow"}); got[0] != "slow" {
+		t.Fatalf("expected recovered provider to rank first, got %v", got)
+	}
+}
@@ -4,6 +4,7 @@ package init
 
 import (
 	// Import adaptors to trigger their init() functions
+	_ "nekobot/pkg/providers/adaptor/azure"
 	_ "nekobot/pkg/providers/adaptor/claude"
 	_ "nekobot/pkg/providers/adaptor/gemini"
 	_ "nekobot/pkg/providers/adaptor/generic"
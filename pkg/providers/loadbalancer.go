@@ -238,8 +238,11 @@ type FallbackAttempt struct {
 }
 
 // FallbackExhaustedError indicates all fallback candidates were tried and failed.
+// When MaxAttempts stopped the chain early, Untried lists the providers left out.
 type FallbackExhaustedError struct {
-	Attempts []FallbackAttempt
+	Attempts    []FallbackAttempt
+	MaxAttempts int
+	Untried     []string
 }
 
 func (e *FallbackExhaustedError) Error() string {
@@ -268,6 +271,14 @@ func (e *FallbackExhaustedError) Error() string {
 				i+1, a.Provider, a.Error, a.Reason, a.Duration.Round(time.Millisecond))
 		}
 	}
+	if len(e.Untried) > 0 {
+		chain := make([]string, 0, len(e.Attempts))
+		for _, a := range e.Attempts {
+			chain = append(chain, a.Provider)
+		}
+		_, _ = fmt.Fprintf(&sb, "\n  stopped after %d attempts (max_fallback_attempts); attempted chain: %s; not tried: %s",
+			e.MaxAttempts, strings.Join(chain, " -> "), strings.Join(e.Untried, ", "))
+	}
 	return sb.String()
 }
 
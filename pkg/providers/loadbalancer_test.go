@@ -278,3 +278,57 @@ func TestCooldownTrackerResetsAfterFailureWindow(t *testing.T) {
 		t.Fatalf("expected timeout counter to be 1 after reset, got %d", got)
 	}
 }
+
+type recordingStreamHandler struct {
+	chunks    []*UnifiedStreamChunk
+	completed bool
+}
+
+func (h *recordingStreamHandler) OnChunk(chunk *UnifiedStreamChunk) error {
+	h.chunks = append(h.chunks, chunk)
+	return nil
+}
+
+func (h *recordingStreamHandler) OnError(err error) {
+	_ = err
+}
+
+func (h *recordingStreamHandler) OnComplete(usage *UnifiedUsage) {
+	_ = usage
+	h.completed = true
+}
+
+func TestLoadBalancerChatStreamUsesBufferedRequestWhenStreamingDisabled(t *testing.T) {
+	lb := NewLoadBalancer()
+
+	calls := 0
+	client := newFakeClient(func(ctx context.Context, req *http.Request) ([]byte, error) {
+		_ = ctx
+		_ = req
+		calls++
+		return []byte("buffered reply"), nil
+	})
+	client.info.DisableStream = true
+	if err := lb.RegisterProvider("self-hosted", client); err != nil {
+		t.Fatalf("register provider: %v", err)
+	}
+
+	req := &UnifiedRequest{Model: "local-model"}
+	handler := &recordingStreamHandler{}
+	if err := lb.ChatStream(context.Background(), req, handler, []string{"self-hosted"}); err != nil {
+		t.Fatalf("chat stream failed: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected one buffered request, got %d", calls)
+	}
+	if req.Stream || client.info.Stream {
+		t.Fatalf("expected streaming to stay off, got request=%v info=%v", req.Stream, client.info.Stream)
+	}
+	if len(handler.chunks) != 1 || handler.chunks[0].Delta.Content != "buffered reply" {
+		t.Fatalf("expected buffered reply as a single chunk, got %#v", handler.chunks)
+	}
+	if handler.chunks[0].FinishReason != "stop" || !handler.completed {
+		t.Fatalf("expected completed stream, got %#v completed=%v", handler.chunks[0], handler.completed)
+	}
+}
@@ -0,0 +1,343 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+const (
+	defaultRetryDelay = 500 * time.Millisecond
+	maxRetryDelay     = 30 * time.Second
+)
+
+// ErrBudgetExceeded is returned when a request cannot fit the per-request
+// spend limit of its provider.
+var ErrBudgetExceeded = errors.New("request exceeds the provider spend limit")
+
+// Call is one Chat or ChatStream call travelling through the middleware
+// chain. Info is a per-call copy of the client's relay info.
+type Call struct {
+	Request *UnifiedRequest
+	Info    *RelayInfo
+	Handler StreamHandler // Set for streaming calls only
+	Stream  bool
+	Attempt int // Zero-based attempt number, advanced by Retry
+}
+
+// Next sends a call on to the next middleware or to the provider. For
+// streaming calls the response carries only the final usage.
+type Next func(ctx context.Context, call *Call) (*UnifiedResponse, error)
+
+// Middleware wraps provider calls with cross-cutting behavior.
+type Middleware func(next Next) Next
+
+// CostEstimator prices a call to model in US dollars. It returns 0 for
+// models without known pricing.
+type CostEstimator func(model string, promptTokens, completionTokens int) float64
+
+// MiddlewareOptions supplies the runtime dependencies of profile middleware.
+type MiddlewareOptions struct {
+	Logger       *logger.Logger
+	EstimateCost CostEstimator
+}
+
+// ProfileMiddleware builds the middleware configured on a provider
+// profile, outermost first: budget, retry, logging, attempt timeout and
+// header injection. It returns nil when cfg is nil.
+func ProfileMiddleware(cfg *config.ProviderMiddlewareConfig, opts MiddlewareOptions) []Middleware {
+	if cfg == nil {
+		return nil
+	}
+	var middleware []Middleware
+	if cfg.MaxRequestCost > 0 && opts.EstimateCost != nil {
+		middleware = append(middleware, Budget(cfg.MaxRequestCost, opts.EstimateCost))
+	}
+	if cfg.MaxRetries > 0 {
+		middleware = append(middleware, Retry(cfg.MaxRetries, time.Duration(cfg.RetryBackoffMS)*time.Millisecond))
+	}
+	if cfg.LogRequests && opts.Logger != nil {
+		middleware = append(middleware, Logging(opts.Logger))
+	}
+	if cfg.AttemptTimeout > 0 {
+		middleware = append(middleware, AttemptTimeout(time.Duration(cfg.AttemptTimeout)*time.Second))
+	}
+	if len(cfg.Headers) > 0 {
+		middleware = append(middleware, Headers(cfg.Headers))
+	}
+	return middleware
+}
+
+// Retry retries attempts that fail with a transient error (rate limits,
+// timeouts, 5xx responses and network errors) up to maxRetries times,
+// waiting an exponential backoff from baseDelay with jitter in between.
+// Streams are only retried until their first chunk reaches the handler, so
+// callers never see output twice.
+func Retry(maxRetries int, baseDelay time.Duration) Middleware {
+	if baseDelay <= 0 {
+		baseDelay = defaultRetryDelay
+	}
+	return func(next Next) Next {
+		return func(ctx context.Context, call *Call) (*UnifiedResponse, error) {
+			var guard *retryHandler
+			if call.Handler != nil {
+				guard = &retryHandler{StreamHandler: call.Handler}
+				call.Handler = guard
+				defer func() { call.Handler = guard.StreamHandler }()
+			}
+			for attempt := 0; ; attempt++ {
+				call.Attempt = attempt
+				if guard != nil {
+					guard.err = nil
+				}
+				resp, err := next(ctx, call)
+				if err == nil || attempt >= maxRetries || !retryable(err) ||
+					(guard != nil && guard.delivered) || ctx.Err() != nil {
+					if err != nil && guard != nil && guard.err != nil {
+						guard.StreamHandler.OnError(guard.err)
+					}
+					return resp, err
+				}
+
+				timer := time.NewTimer(retryDelay(baseDelay, attempt))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, fmt.Errorf("waiting to retry: %w", context.Cause(ctx))
+				case <-timer.C:
+				}
+			}
+		}
+	}
+}
+
+// retryDelay doubles baseDelay per attempt up to maxRetryDelay and picks a
+// random delay in the upper half, so concurrent callers spread out.
+func retryDelay(baseDelay time.Duration, attempt int) time.Duration {
+	delay := baseDelay << min(attempt, 16)
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// retryable reports whether err is worth another attempt on the same
+// provider.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrBudgetExceeded) {
+		return false
+	}
+	var apiErr *ErrorResponse
+	if errors.As(err, &apiErr) && apiErr.StatusCode > 0 {
+		return apiErr.StatusCode == http.StatusRequestTimeout ||
+			apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	classified := ClassifyError(err, "", "")
+	return classified != nil &&
+		(classified.Reason == FailoverReasonRateLimit || classified.Reason == FailoverReasonTimeout)
+}
+
+// retryHandler tracks whether a stream attempt delivered output and holds
+// back its error until Retry knows the attempt is final.
+type retryHandler struct {
+	StreamHandler
+	delivered bool
+	err       error
+}
+
+func (h *retryHandler) OnChunk(chunk *UnifiedStreamChunk) error {
+	h.delivered = true
+	return h.StreamHandler.OnChunk(chunk)
+}
+
+func (h *retryHandler) OnError(err error) {
+	h.err = err
+}
+
+// AttemptTimeout bounds each attempt: the whole of a non-streaming call,
+// or the wait for the first chunk of a stream. Once a stream flows, the
+// profile timeout bounds the gaps between its chunks.
+func AttemptTimeout(timeout time.Duration) Middleware {
+	return func(next Next) Next {
+		return func(ctx context.Context, call *Call) (*UnifiedResponse, error) {
+			if timeout <= 0 {
+				return next(ctx, call)
+			}
+			if !call.Stream {
+				ctx, cancel := context.WithTimeoutCause(ctx, timeout, requestTimeoutError(timeout))
+				defer cancel()
+				resp, err := next(ctx, call)
+				if err != nil {
+					return nil, causeOf(ctx, err)
+				}
+				return resp, nil
+			}
+
+			ctx, cancel := context.WithCancelCause(ctx)
+			defer cancel(nil)
+			timer := time.AfterFunc(timeout, func() { cancel(requestTimeoutError(timeout)) })
+			defer timer.Stop()
+
+			handler := call.Handler
+			call.Handler = &firstChunkHandler{StreamHandler: handler, first: func() { timer.Stop() }}
+			defer func() { call.Handler = handler }()
+
+			resp, err := next(ctx, call)
+			if err != nil {
+				return nil, causeOf(ctx, err)
+			}
+			return resp, nil
+		}
+	}
+}
+
+// firstChunkHandler runs first when a stream delivers its first chunk.
+type firstChunkHandler struct {
+	StreamHandler
+	first   func()
+	started bool
+}
+
+func (h *firstChunkHandler) OnChunk(chunk *UnifiedStreamChunk) error {
+	if !h.started {
+		h.started = true
+		h.first()
+	}
+	return h.StreamHandler.OnChunk(chunk)
+}
+
+// Budget caps the spend of each request at maxCost. The prompt size is
+// estimated before the call; a request whose prompt alone exceeds the
+// limit fails with ErrBudgetExceeded, otherwise max_tokens is lowered to
+// what the rest of the budget pays for. Models without pricing pass.
+func Budget(maxCost float64, estimate CostEstimator) Middleware {
+	return func(next Next) Next {
+		return func(ctx context.Context, call *Call) (*UnifiedResponse, error) {
+			if maxCost <= 0 || estimate == nil {
+				return next(ctx, call)
+			}
+			model := call.Request.Model
+			if model == "" {
+				model = call.Info.Model
+			}
+			promptCost := estimate(model, estimatePromptTokens(call.Request), 0)
+			outputCost := estimate(model, 0, 1_000_000) / 1_000_000
+			if promptCost == 0 && outputCost == 0 {
+				return next(ctx, call)
+			}
+			if promptCost > maxCost {
+				return nil, fmt.Errorf("%w: estimated prompt cost $%.4f is over $%.4f", ErrBudgetExceeded, promptCost, maxCost)
+			}
+			if outputCost > 0 {
+				affordable := int((maxCost - promptCost) / outputCost)
+				if affordable < 1 {
+					return nil, fmt.Errorf("%w: no output tokens fit in $%.4f", ErrBudgetExceeded, maxCost)
+				}
+				if call.Request.MaxTokens == 0 || call.Request.MaxTokens > affordable {
+					clamped := *call.Request
+					clamped.MaxTokens = affordable
+					call.Request = &clamped
+				}
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+// estimatePromptTokens approximates the prompt size at four characters
+// per token, counting messages, tool calls and tool definitions.
+func estimatePromptTokens(req *UnifiedRequest) int {
+	chars := 0
+	for _, msg := range req.Messages {
+		chars += len(msg.Content)
+		for _, tc := range msg.ToolCalls {
+			args, _ := json.Marshal(tc.Arguments)
+			chars += len(tc.Name) + len(args)
+		}
+	}
+	if len(req.Tools) > 0 {
+		tools, _ := json.Marshal(req.Tools)
+		chars += len(tools)
+	}
+	return chars / 4
+}
+
+// Headers adds headers to every request. Headers the adaptor owns, such as
+// authentication, cannot be overridden.
+func Headers(headers map[string]string) Middleware {
+	return func(next Next) Next {
+		return func(ctx context.Context, call *Call) (*UnifiedResponse, error) {
+			if len(headers) > 0 && call.Info.Headers == nil {
+				call.Info.Headers = make(map[string]string, len(headers))
+			}
+			for key, value := range headers {
+				call.Info.Headers[key] = value
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+// Hooks observe each attempt that reaches the provider.
+type Hooks struct {
+	OnRequest  func(ctx context.Context, call *Call)
+	OnResponse func(ctx context.Context, call *Call, resp *UnifiedResponse, err error, elapsed time.Duration)
+}
+
+// WithHooks runs hooks around each attempt.
+func WithHooks(hooks Hooks) Middleware {
+	return func(next Next) Next {
+		return func(ctx context.Context, call *Call) (*UnifiedResponse, error) {
+			if hooks.OnRequest != nil {
+				hooks.OnRequest(ctx, call)
+			}
+			started := time.Now()
+			resp, err := next(ctx, call)
+			if hooks.OnResponse != nil {
+				hooks.OnResponse(ctx, call, resp, err, time.Since(started))
+			}
+			return resp, err
+		}
+	}
+}
+
+// Logging logs each attempt with its latency and token usage.
+func Logging(log *logger.Logger) Middleware {
+	return WithHooks(Hooks{
+		OnResponse: func(_ context.Context, call *Call, resp *UnifiedResponse, err error, elapsed time.Duration) {
+			fields := []zap.Field{
+				zap.String("provider", call.Info.ProviderName),
+				zap.String("model", call.Request.Model),
+				zap.Bool("stream", call.Stream),
+				zap.Int("attempt", call.Attempt),
+				zap.Duration("elapsed", elapsed),
+			}
+			if err != nil {
+				log.Warn("Provider request failed", append(fields, zap.Error(err))...)
+				return
+			}
+			if resp != nil && resp.Usage != nil {
+				fields = append(fields,
+					zap.Int("prompt_tokens", resp.Usage.PromptTokens),
+					zap.Int("completion_tokens", resp.Usage.CompletionTokens))
+			}
+			log.Info("Provider request", fields...)
+		},
+	})
+}
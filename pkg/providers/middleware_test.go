@@ -0,0 +1,226 @@
+package providers_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"nekobot/pkg/providers"
+)
+
+const okCompletion = `{"id":"c1","model":"gpt-test","choices":[{"index":0,"message":{"role":"assistant","content":"pong"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`
+
+func newTestClient(t *testing.T, serverURL string) *providers.Client {
+	t.Helper()
+	client, err := providers.NewClient("openai", &providers.RelayInfo{
+		ProviderName: "test",
+		APIKey:       "test-key",
+		APIBase:      serverURL,
+		Model:        "gpt-test",
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	return client
+}
+
+func pingRequest() *providers.UnifiedRequest {
+	return &providers.UnifiedRequest{
+		Model:    "gpt-test",
+		Messages: []providers.UnifiedMessage{{Role: "user", Content: "ping"}},
+	}
+}
+
+func TestRetryRecoversFromTransientErrors(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		if hits.Add(1) < 3 {
+			http.Error(w, `{"error":{"message":"busy","type":"server_error"}}`, http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(okCompletion))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	client.Use(providers.Retry(2, time.Millisecond))
+
+	resp, err := client.Chat(context.Background(), pingRequest())
+	if err != nil {
+		t.Fatalf("expected retries to recover, got %v", err)
+	}
+	if resp.Content != "pong" || hits.Load() != 3 {
+		t.Fatalf("expected success on the third attempt, got %q after %d attempts", resp.Content, hits.Load())
+	}
+}
+
+func TestRetrySkipsPermanentErrors(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		hits.Add(1)
+		http.Error(w, `{"error":{"message":"bad key","type":"invalid_request_error"}}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	client.Use(providers.Retry(3, time.Millisecond))
+
+	if _, err := client.Chat(context.Background(), pingRequest()); err == nil {
+		t.Fatalf("expected auth error")
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("expected no retries of an auth error, got %d attempts", hits.Load())
+	}
+}
+
+func TestRetryDoesNotRepeatDeliveredStream(t *testing.T) {
+	attempts := 0
+	next := func(_ context.Context, call *providers.Call) (*providers.UnifiedResponse, error) {
+		attempts++
+		err := &providers.ErrorResponse{StatusCode: http.StatusServiceUnavailable, Message: "stream dropped"}
+		if attempts == 1 {
+			// The first attempt fails before any output and may be retried.
+			call.Handler.OnError(err)
+			return nil, err
+		}
+		if err := call.Handler.OnChunk(&providers.UnifiedStreamChunk{Delta: providers.UnifiedDelta{Content: "par"}}); err != nil {
+			return nil, err
+		}
+		call.Handler.OnError(err)
+		return nil, err
+	}
+
+	var content strings.Builder
+	var reported []error
+	_, err := providers.Retry(3, time.Millisecond)(next)(context.Background(), &providers.Call{
+		Request: pingRequest(),
+		Info:    &providers.RelayInfo{},
+		Stream:  true,
+		Handler: &providers.SimpleStreamHandler{
+			OnChunkFunc: func(chunk *providers.UnifiedStreamChunk) error {
+				content.WriteString(chunk.Delta.Content)
+				return nil
+			},
+			OnErrorFunc: func(err error) { reported = append(reported, err) },
+		},
+	})
+	if err == nil {
+		t.Fatalf("expected the broken stream to fail")
+	}
+	if attempts != 2 || content.String() != "par" {
+		t.Fatalf("expected no retry after output was delivered, got %d attempts and %q", attempts, content.String())
+	}
+	if len(reported) != 1 {
+		t.Fatalf("expected only the final attempt's error to reach the handler, got %v", reported)
+	}
+}
+
+func TestAttemptTimeoutIsRetried(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		if hits.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+			return
+		}
+		_, _ = w.Write([]byte(okCompletion))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	client.Use(providers.Retry(1, time.Millisecond), providers.AttemptTimeout(200*time.Millisecond))
+
+	started := time.Now()
+	resp, err := client.Chat(context.Background(), pingRequest())
+	if err != nil {
+		t.Fatalf("expected the second attempt to succeed, got %v", err)
+	}
+	if resp.Content != "pong" || time.Since(started) > 5*time.Second {
+		t.Fatalf("expected the stalled attempt to be cut off, got %q after %s", resp.Content, time.Since(started))
+	}
+}
+
+func TestHeadersAreInjectedPerCall(t *testing.T) {
+	var team, auth atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		team.Store(r.Header.Get("X-Team"))
+		auth.Store(r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(okCompletion))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	client.Use(providers.Headers(map[string]string{"X-Team": "ops", "Authorization": "Bearer stolen"}))
+
+	if _, err := client.Chat(context.Background(), pingRequest()); err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if team.Load() != "ops" {
+		t.Fatalf("expected injected header, got %v", team.Load())
+	}
+	if auth.Load() != "Bearer test-key" {
+		t.Fatalf("expected adaptor-owned auth header to win, got %v", auth.Load())
+	}
+}
+
+func TestBudgetClampsAndRejects(t *testing.T) {
+	// $1 per million prompt tokens and $10 per million completion tokens.
+	estimate := func(model string, prompt, completion int) float64 {
+		if model != "gpt-test" {
+			return 0
+		}
+		return (float64(prompt)*1 + float64(completion)*10) / 1_000_000
+	}
+	var seen *providers.UnifiedRequest
+	next := func(_ context.Context, call *providers.Call) (*providers.UnifiedResponse, error) {
+		seen = call.Request
+		return &providers.UnifiedResponse{}, nil
+	}
+	call := func(budget float64, req *providers.UnifiedRequest) error {
+		seen = nil
+		_, err := providers.Budget(budget, estimate)(next)(context.Background(), &providers.Call{
+			Request: req,
+			Info:    &providers.RelayInfo{Model: "gpt-test"},
+		})
+		return err
+	}
+
+	req := pingRequest()
+	req.MaxTokens = 100_000
+	if err := call(0.01, req); err != nil {
+		t.Fatalf("expected request to fit the budget, got %v", err)
+	}
+	if seen == nil || seen.MaxTokens >= 1000 || seen.MaxTokens < 990 {
+		t.Fatalf("expected max_tokens clamped to about 1000, got %+v", seen)
+	}
+	if req.MaxTokens != 100_000 {
+		t.Fatalf("expected the caller's request to stay untouched, got %d", req.MaxTokens)
+	}
+
+	long := pingRequest()
+	long.Messages[0].Content = strings.Repeat("x", 400_000)
+	if err := call(0.05, long); !errors.Is(err, providers.ErrBudgetExceeded) {
+		t.Fatalf("expected an oversized prompt to exceed the budget, got %v", err)
+	}
+	if seen != nil {
+		t.Fatalf("expected a rejected request not to be sent")
+	}
+
+	unpriced := pingRequest()
+	unpriced.Model = "unknown"
+	if err := call(0.0001, unpriced); err != nil || seen.MaxTokens != 0 {
+		t.Fatalf("expected unpriced models to pass untouched, got %v / %+v", err, seen)
+	}
+}
@@ -0,0 +1,101 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Response format types, named after the OpenAI response_format parameter.
+const (
+	ResponseFormatText       = "text"
+	ResponseFormatJSONObject = "json_object"
+	ResponseFormatJSONSchema = "json_schema"
+)
+
+// defaultStructuredOutputName names the schema when the caller gives none.
+const defaultStructuredOutputName = "structured_output"
+
+// ResponseFormat asks the model for a JSON reply. A json_object format wants
+// any JSON object; a json_schema format wants a reply matching Schema.
+// Providers without a native JSON mode receive the schema as a forced tool
+// call instead, see StructuredContent.
+type ResponseFormat struct {
+	Type   string                 `json:"type"`
+	Name   string                 `json:"name,omitempty"`
+	Schema map[string]interface{} `json:"schema,omitempty"`
+	Strict bool                   `json:"strict,omitempty"`
+}
+
+// Normalize checks the format and fills in defaults. It returns nil for a nil
+// or plain text format, which needs no special handling.
+func (f *ResponseFormat) Normalize() (*ResponseFormat, error) {
+	if f == nil {
+		return nil, nil
+	}
+	normalized := *f
+	normalized.Type = strings.ToLower(strings.TrimSpace(f.Type))
+	normalized.Name = strings.TrimSpace(f.Name)
+	switch normalized.Type {
+	case "", ResponseFormatText:
+		return nil, nil
+	case ResponseFormatJSONObject:
+		normalized.Schema = nil
+		normalized.Strict = false
+	case ResponseFormatJSONSchema:
+		if len(normalized.Schema) == 0 {
+			return nil, fmt.Errorf("response_format json_schema requires a schema")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported response_format type: %s", f.Type)
+	}
+	if normalized.Name == "" {
+		normalized.Name = defaultStructuredOutputName
+	}
+	return &normalized, nil
+}
+
+// ToolSchema returns the input schema of the tool that stands in for the
+// format on providers that only support structured tool input.
+func (f *ResponseFormat) ToolSchema() map[string]interface{} {
+	if len(f.Schema) > 0 {
+		return f.Schema
+	}
+	return map[string]interface{}{"type": "object", "additionalProperties": true}
+}
+
+// StructuredContent returns the JSON text of a reply to a request with
+// format: the arguments of the stand-in tool call when the provider answered
+// with one, otherwise the reply text with any Markdown code fence removed.
+func StructuredContent(resp *UnifiedResponse, format *ResponseFormat) string {
+	if resp == nil {
+		return ""
+	}
+	if format != nil {
+		for _, call := range resp.ToolCalls {
+			if call.Name != format.Name {
+				continue
+			}
+			data, err := json.Marshal(call.Arguments)
+			if err == nil {
+				return string(data)
+			}
+		}
+	}
+	return stripCodeFence(resp.Content)
+}
+
+// stripCodeFence removes a Markdown code fence wrapped around text, which
+// models in prompt-only JSON mode often add.
+func stripCodeFence(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "```") || !strings.HasSuffix(trimmed, "```") || len(trimmed) < 6 {
+		return trimmed
+	}
+	body := strings.TrimSuffix(strings.TrimPrefix(trimmed, "```"), "```")
+	if newline := strings.IndexByte(body, '\n'); newline >= 0 {
+		// Drop the info string, e.g. "json".
+		body = body[newline+1:]
+	}
+	return strings.TrimSpace(body)
+}
@@ -0,0 +1,113 @@
+package providers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"nekobot/pkg/providers"
+)
+
+func TestResponseFormatNormalize(t *testing.T) {
+	if format, err := (&providers.ResponseFormat{Type: "text"}).Normalize(); err != nil || format != nil {
+		t.Fatalf("expected text format to need no handling, got %+v / %v", format, err)
+	}
+	if _, err := (&providers.ResponseFormat{Type: "json_schema"}).Normalize(); err == nil {
+		t.Fatalf("expected json_schema without a schema to be rejected")
+	}
+	if _, err := (&providers.ResponseFormat{Type: "xml"}).Normalize(); err == nil {
+		t.Fatalf("expected unknown format type to be rejected")
+	}
+
+	format, err := (&providers.ResponseFormat{Type: " JSON_Object ", Strict: true}).Normalize()
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	if format.Type != providers.ResponseFormatJSONObject || format.Name == "" || format.Strict {
+		t.Fatalf("expected normalized json_object format with a default name, got %+v", format)
+	}
+}
+
+func TestStructuredContentPrefersFormatToolCall(t *testing.T) {
+	format := &providers.ResponseFormat{Type: providers.ResponseFormatJSONSchema, Name: "place"}
+
+	resp := &providers.UnifiedResponse{
+		Content: "Here you go.",
+		ToolCalls: []providers.UnifiedToolCall{
+			{Name: "other", Arguments: map[string]interface{}{"x": 1}},
+			{Name: "place", Arguments: map[string]interface{}{"city": "Paris"}},
+		},
+	}
+	if got := providers.StructuredContent(resp, format); got != `{"city":"Paris"}` {
+		t.Fatalf("expected the format tool input, got %q", got)
+	}
+
+	fenced := &providers.UnifiedResponse{Content: "```json\n{\"city\":\"Rome\"}\n```"}
+	if got := providers.StructuredContent(fenced, format); got != `{"city":"Rome"}` {
+		t.Fatalf("expected the code fence to be stripped, got %q", got)
+	}
+}
+
+func TestOpenAIRequestCarriesResponseFormat(t *testing.T) {
+	var body atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		body.Store(payload)
+		_, _ = w.Write([]byte(okCompletion))
+	}))
+	defer server.Close()
+
+	req := pingRequest()
+	req.ResponseFormat = &providers.ResponseFormat{
+		Type:   providers.ResponseFormatJSONSchema,
+		Name:   "reply",
+		Schema: map[string]interface{}{"type": "object"},
+		Strict: true,
+	}
+	if _, err := newTestClient(t, server.URL).Chat(context.Background(), req); err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	payload, _ := body.Load().(map[string]interface{})
+	format, _ := payload["response_format"].(map[string]interface{})
+	schema, _ := format["json_schema"].(map[string]interface{})
+	if format["type"] != "json_schema" || schema["name"] != "reply" || schema["strict"] != true || schema["schema"] == nil {
+		t.Fatalf("expected an OpenAI json_schema response_format, got %v", payload["response_format"])
+	}
+}
+
+func TestOpenAIRequestSendsImagePartsAsDataURLs(t *testing.T) {
+	var body atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		body.Store(payload)
+		_, _ = w.Write([]byte(okCompletion))
+	}))
+	defer server.Close()
+
+	req := pingRequest()
+	req.Messages[len(req.Messages)-1].Parts = []providers.ContentPart{
+		{Type: providers.ContentPartImage, MIMEType: "image/png", Data: []byte("png")},
+	}
+	if _, err := newTestClient(t, server.URL).Chat(context.Background(), req); err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	payload, _ := body.Load().(map[string]interface{})
+	messages, _ := payload["messages"].([]interface{})
+	last, _ := messages[len(messages)-1].(map[string]interface{})
+	parts, _ := last["content"].([]interface{})
+	if len(parts) != 2 {
+		t.Fatalf("expected text and image content parts, got %v", last["content"])
+	}
+	image, _ := parts[1].(map[string]interface{})
+	url, _ := image["image_url"].(map[string]interface{})
+	if image["type"] != "image_url" || url["url"] != "data:image/png;base64,cG5n" {
+		t.Fatalf("expected a data URL image part, got %v", image)
+	}
+}
@@ -5,6 +5,7 @@ package providers
 
 import (
 	"context"
+	"encoding/base64"
 	"io"
 	"net/http"
 )
@@ -12,28 +13,51 @@ import (
 // UnifiedRequest represents a provider-agnostic request structure.
 // All provider-specific formats are converted to/from this unified format.
 type UnifiedRequest struct {
-	Model       string                 `json:"model"`
-	Messages    []UnifiedMessage       `json:"messages"`
-	MaxTokens   int                    `json:"max_tokens,omitempty"`
-	Temperature float64                `json:"temperature,omitempty"`
-	TopP        float64                `json:"top_p,omitempty"`
-	Stream      bool                   `json:"stream,omitempty"`
-	Tools       []UnifiedTool          `json:"tools,omitempty"`
-	ToolChoice  interface{}            `json:"tool_choice,omitempty"`
-	User        string                 `json:"user,omitempty"`
-	Extra       map[string]interface{} `json:"-"` // Provider-specific extras
+	Model          string                 `json:"model"`
+	Messages       []UnifiedMessage       `json:"messages"`
+	MaxTokens      int                    `json:"max_tokens,omitempty"`
+	Temperature    float64                `json:"temperature,omitempty"`
+	TopP           float64                `json:"top_p,omitempty"`
+	Stream         bool                   `json:"stream,omitempty"`
+	Tools          []UnifiedTool          `json:"tools,omitempty"`
+	ToolChoice     interface{}            `json:"tool_choice,omitempty"`
+	User           string                 `json:"user,omitempty"`
+	ResponseFormat *ResponseFormat        `json:"response_format,omitempty"` // JSON reply request; nil means free text
+	Extra          map[string]interface{} `json:"-"`                         // Provider-specific extras
 }
 
 // UnifiedMessage represents a single message in the conversation.
 type UnifiedMessage struct {
 	Role       string                 `json:"role"` // "system", "user", "assistant", "tool"
 	Content    string                 `json:"content,omitempty"`
+	Parts      []ContentPart          `json:"parts,omitempty"` // Non-text content sent after Content
 	Name       string                 `json:"name,omitempty"`
 	ToolCalls  []UnifiedToolCall      `json:"tool_calls,omitempty"`
 	ToolCallID string                 `json:"tool_call_id,omitempty"`
 	Metadata   map[string]interface{} `json:"-"` // Provider-specific metadata
 }
 
+// ContentPartImage is the type of an image content part.
+const ContentPartImage = "image"
+
+// ContentPart is non-text content of a message, such as an image. Inline
+// Data is preferred; URL is sent when Data is empty.
+type ContentPart struct {
+	Type     string `json:"type"` // ContentPartImage
+	MIMEType string `json:"mime_type,omitempty"`
+	Data     []byte `json:"data,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+// DataURL returns the part as a data: URL, or its URL when it has no inline
+// data.
+func (p ContentPart) DataURL() string {
+	if len(p.Data) == 0 {
+		return p.URL
+	}
+	return "data:" + p.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(p.Data)
+}
+
 // UnifiedToolCall represents a tool invocation by the LLM.
 type UnifiedToolCall struct {
 	ID        string                 `json:"id"`
@@ -81,10 +105,14 @@ type UnifiedDelta struct {
 }
 
 // UnifiedUsage represents token usage information.
+// PromptTokens includes the cache tokens, which break down how much of the
+// prompt was read from or written to the provider's prompt cache.
 type UnifiedUsage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+	CacheReadTokens  int `json:"cache_read_tokens,omitempty"`
+	CacheWriteTokens int `json:"cache_write_tokens,omitempty"`
 }
 
 // RelayInfo contains metadata about the current request being processed.
@@ -96,10 +124,13 @@ type RelayInfo struct {
 	APIBase       string                 // Base URL for API endpoints
 	Model         string                 // Model identifier
 	Stream        bool                   // Whether streaming is enabled
+	DisableStream bool                   // Force buffered requests even when streaming is requested
 	MaxRetries    int                    // Maximum retry attempts
 	Timeout       int                    // Timeout in seconds
 	Proxy         string                 // HTTP proxy URL
 	Headers       map[string]string      // Additional HTTP headers
+	APIVersion    string                 // API version for providers that require one (Azure OpenAI)
+	Deployments   map[string]string      // Model alias to deployment name (Azure OpenAI)
 	Metadata      map[string]interface{} // Additional metadata
 }
 
@@ -0,0 +1,55 @@
+package providerstore
+
+import (
+	"context"
+	"fmt"
+
+	"nekobot/pkg/config"
+)
+
+// DocumentResult summarizes an applied, or planned, config document.
+type DocumentResult struct {
+	Sections  []string        `json:"sections"`
+	Skipped   []string        `json:"skipped,omitempty"`
+	Providers ProviderChanges `json:"providers"`
+}
+
+// Empty reports whether the document changed nothing.
+func (r DocumentResult) Empty() bool {
+	return len(r.Sections) == 0 && r.Providers.Empty()
+}
+
+// ExportDocument snapshots the runtime config sections and stored providers.
+func (m *Manager) ExportDocument(ctx context.Context) (*config.Document, error) {
+	profiles, err := m.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return config.ExportDocument(m.cfg, profiles)
+}
+
+// ApplyDocument merges doc into the runtime config, saves the changed
+// sections to the database and applies the document's providers. See
+// config.ApplyDocument for the merge rules. With opts.DryRun nothing is
+// written.
+func (m *Manager) ApplyDocument(ctx context.Context, doc *config.Document, opts ApplyOptions) (DocumentResult, error) {
+	result := DocumentResult{Skipped: doc.Skipped}
+	var err error
+	if opts.DryRun {
+		result.Sections, err = config.PlanDocument(m.cfg, doc)
+	} else {
+		result.Sections, err = config.ApplyDocument(m.cfg, doc)
+	}
+	if err != nil {
+		return result, err
+	}
+	if !opts.DryRun && len(result.Sections) > 0 {
+		if err := config.SaveDatabaseSections(m.cfg, result.Sections...); err != nil {
+			return result, fmt.Errorf("save runtime config: %w", err)
+		}
+	}
+	if doc.Providers != nil {
+		result.Providers, err = m.ApplyProviders(ctx, doc.Providers, opts)
+	}
+	return result, err
+}
@@ -2,9 +2,12 @@ package providerstore
 
 import (
 	"context"
+	"slices"
 
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 
+	"nekobot/pkg/bus"
 	"nekobot/pkg/logger"
 )
 
@@ -12,6 +15,7 @@ import (
 var Module = fx.Module("providerstore",
 	fx.Provide(NewManager),
 	fx.Invoke(registerLifecycle),
+	fx.Invoke(subscribeConfigChanges),
 )
 
 func registerLifecycle(lc fx.Lifecycle, mgr *Manager, log *logger.Logger) {
@@ -22,3 +26,20 @@ func registerLifecycle(lc fx.Lifecycle, mgr *Manager, log *logger.Logger) {
 		},
 	})
 }
+
+// subscribeConfigChanges stores providers edited in the config file once a
+// reload has applied them.
+func subscribeConfigChanges(mgr *Manager, messageBus bus.Bus, log *logger.Logger) {
+	bus.SubscribeConfigChanges(messageBus, func(sections []string) {
+		if !slices.Contains(sections, "providers") {
+			return
+		}
+		written, err := mgr.ImportConfigProviders(context.Background())
+		if err != nil {
+			log.Warn("Failed to store reloaded providers", zap.Error(err))
+		}
+		if written > 0 {
+			log.Info("Stored providers from reloaded config", zap.Int("providers", written))
+		}
+	})
+}
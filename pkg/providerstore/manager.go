@@ -2,8 +2,10 @@ package providerstore
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"strings"
 	"sync"
 
@@ -11,7 +13,9 @@ import (
 
 	"nekobot/pkg/config"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/ownership"
 	"nekobot/pkg/providerregistry"
+	"nekobot/pkg/secrets"
 	"nekobot/pkg/storage/ent"
 	"nekobot/pkg/storage/ent/provider"
 )
@@ -58,7 +62,8 @@ func (m *Manager) Close() error {
 	return nil
 }
 
-// List returns all providers sorted by name.
+// List returns all providers sorted by name. When an ownership.AuthContext
+// is stored in ctx, only providers of the caller's tenant are returned.
 func (m *Manager) List(ctx context.Context) ([]config.ProviderProfile, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -66,6 +71,15 @@ func (m *Manager) List(ctx context.Context) ([]config.ProviderProfile, error) {
 	if err != nil {
 		return nil, err
 	}
+	if ac, ok := ownership.AuthContextFromContext(ctx); ok {
+		visible := providers[:0]
+		for _, profile := range providers {
+			if ac.InTenant(profile.TenantID) {
+				visible = append(visible, profile)
+			}
+		}
+		providers = visible
+	}
 	return cloneProviders(providers), nil
 }
 
@@ -107,6 +121,9 @@ func (m *Manager) Create(ctx context.Context, profile config.ProviderProfile) (*
 	if exists {
 		return nil, ErrProviderExists
 	}
+	if ac, ok := ownership.AuthContextFromContext(ctx); ok {
+		_, normalized.TenantID, _ = ac.ValidateCreateOwnership("", normalized.TenantID, "")
+	}
 
 	if err := m.insertLocked(ctx, normalized); err != nil {
 		return nil, err
@@ -146,6 +163,18 @@ func (m *Manager) Update(ctx context.Context, name string, profile config.Provid
 		DefaultTestModel: strings.TrimSpace(profile.DefaultTestModel),
 		APIFormat:        strings.TrimSpace(profile.APIFormat),
 		Timeout:          profile.Timeout,
+		Stream:           profile.Stream,
+		// The prefix is always replaced so that an empty value clears it.
+		SystemPrefix: profile.SystemPrefix,
+		APIVersion:   strings.TrimSpace(profile.APIVersion),
+		Deployments:  profile.Deployments,
+		Middleware:   profile.Middleware,
+		TenantID:     current.TenantID,
+	}
+	// Capabilities are only written by SetCapabilities.
+	merged.Capabilities, err = decodeCapabilities(current.CapabilitiesJSON)
+	if err != nil {
+		return nil, err
 	}
 	if merged.Name == "" {
 		merged.Name = current.Name
@@ -174,12 +203,41 @@ func (m *Manager) Update(ctx context.Context, name string, profile config.Provid
 	if merged.Timeout == 0 {
 		merged.Timeout = current.Timeout
 	}
+	if merged.Stream == nil {
+		merged.Stream = &current.Stream
+	}
+	if merged.APIVersion == "" {
+		merged.APIVersion = current.APIVersion
+	}
+	// A nil mapping keeps the current one; an empty mapping clears it.
+	if merged.Deployments == nil {
+		merged.Deployments, err = decodeDeployments(current.DeploymentsJSON)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Likewise for middleware: nil keeps it and an empty value clears it.
+	if merged.Middleware == nil {
+		merged.Middleware, err = decodeMiddleware(current.MiddlewareJSON)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	normalized, err := normalizeProvider(merged)
 	if err != nil {
 		return nil, err
 	}
 
+	deployments, err := encodeDeployments(normalized.Deployments)
+	if err != nil {
+		return nil, err
+	}
+	middleware, err := encodeMiddleware(normalized.Middleware)
+	if err != nil {
+		return nil, err
+	}
+
 	if normalized.Name != name {
 		exists, err := m.existsLocked(ctx, normalized.Name)
 		if err != nil {
@@ -190,17 +248,26 @@ func (m *Manager) Update(ctx context.Context, name string, profile config.Provid
 		}
 	}
 
+	apiKey, proxy, err := m.sealCredentials(normalized.APIKey, normalized.Proxy)
+	if err != nil {
+		return nil, err
+	}
 	_, err = m.client.Provider.UpdateOneID(current.ID).
 		SetName(normalized.Name).
 		SetProviderKind(normalized.ProviderKind).
-		SetAPIKey(normalized.APIKey).
+		SetAPIKey(apiKey).
 		SetAPIBase(normalized.APIBase).
-		SetProxy(normalized.Proxy).
+		SetProxy(proxy).
 		SetDefaultWeight(normalized.DefaultWeight).
 		SetEnabled(normalized.Enabled).
 		SetDefaultTestModel(normalized.DefaultTestModel).
 		SetAPIFormat(normalized.APIFormat).
 		SetTimeout(normalized.Timeout).
+		SetStream(normalized.StreamingEnabled()).
+		SetSystemPrefix(normalized.SystemPrefix).
+		SetAPIVersion(normalized.APIVersion).
+		SetDeploymentsJSON(deployments).
+		SetMiddlewareJSON(middleware).
 		Save(ctx)
 	if err != nil {
 		if ent.IsConstraintError(err) {
@@ -220,6 +287,49 @@ func (m *Manager) Update(ctx context.Context, name string, profile config.Provid
 	return &updated, nil
 }
 
+// SetCapabilities stores the probed capabilities of a provider. A nil value
+// clears them.
+func (m *Manager) SetCapabilities(ctx context.Context, name string, caps *config.ProviderCapabilities) (*config.ProviderProfile, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("provider name is required")
+	}
+	encoded, err := encodeCapabilities(caps)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, err := m.getRecordLocked(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := m.client.Provider.UpdateOneID(current.ID).
+		SetCapabilitiesJSON(encoded).
+		Save(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrProviderNotFound
+		}
+		return nil, fmt.Errorf("update provider capabilities: %w", err)
+	}
+	if err := m.openRecord(rec); err != nil {
+		return nil, err
+	}
+
+	if err := m.syncConfigLocked(ctx); err != nil {
+		return nil, err
+	}
+
+	profile, err := toConfigProvider(rec)
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
 // Delete removes a provider by name.
 func (m *Manager) Delete(ctx context.Context, name string) error {
 	name = strings.TrimSpace(name)
@@ -230,17 +340,122 @@ func (m *Manager) Delete(ctx context.Context, name string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	affected, err := m.client.Provider.Delete().Where(provider.NameEQ(name)).Exec(ctx)
+	current, err := m.getRecordLocked(ctx, name)
 	if err != nil {
-		return fmt.Errorf("delete provider: %w", err)
+		return err
 	}
-	if affected == 0 {
-		return ErrProviderNotFound
+	if err := m.client.Provider.DeleteOneID(current.ID).Exec(ctx); err != nil {
+		if ent.IsNotFound(err) {
+			return ErrProviderNotFound
+		}
+		return fmt.Errorf("delete provider: %w", err)
 	}
 
 	return m.syncConfigLocked(ctx)
 }
 
+// ImportConfigProviders stores providers from the runtime config that are
+// missing from, or differ from, the database, e.g. after a config file edit
+// was hot-reloaded. Providers only in the database are kept. It returns how
+// many providers were written.
+func (m *Manager) ImportConfigProviders(ctx context.Context) (int, error) {
+	changes, err := m.ApplyProviders(ctx, cloneProviders(m.cfg.Providers), ApplyOptions{})
+	return len(changes.Created) + len(changes.Updated), err
+}
+
+// ApplyOptions controls ApplyProviders.
+type ApplyOptions struct {
+	// Prune deletes stored providers missing from the applied list.
+	Prune bool
+	// DryRun reports the changes without writing them.
+	DryRun bool
+}
+
+// ProviderChanges names the providers ApplyProviders created, updated and
+// deleted.
+type ProviderChanges struct {
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Deleted []string `json:"deleted"`
+}
+
+// Empty reports whether no provider changed.
+func (c ProviderChanges) Empty() bool {
+	return len(c.Created) == 0 && len(c.Updated) == 0 && len(c.Deleted) == 0
+}
+
+// ApplyProviders makes the stored providers match profiles: new names are
+// created and differing ones updated. Unchanged providers are not written.
+// Errors for single providers are collected and the rest still applied.
+func (m *Manager) ApplyProviders(ctx context.Context, profiles []config.ProviderProfile, opts ApplyOptions) (ProviderChanges, error) {
+	var changes ProviderChanges
+	stored, err := m.List(ctx)
+	if err != nil {
+		return changes, err
+	}
+	byName := make(map[string]config.ProviderProfile, len(stored))
+	for _, profile := range stored {
+		byName[profile.Name] = profile
+	}
+
+	var errs []error
+	applied := make(map[string]bool, len(profiles))
+	for _, profile := range profiles {
+		name := strings.TrimSpace(profile.Name)
+		applied[name] = true
+		current, exists := byName[name]
+		if exists && sameProvider(current, profile) {
+			continue
+		}
+		if !opts.DryRun {
+			if exists {
+				_, err = m.Update(ctx, current.Name, profile)
+			} else {
+				_, err = m.Create(ctx, profile)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("apply provider %s: %w", name, err))
+				continue
+			}
+		}
+		if exists {
+			changes.Updated = append(changes.Updated, name)
+		} else {
+			changes.Created = append(changes.Created, name)
+		}
+	}
+	if opts.Prune {
+		for _, profile := range stored {
+			if applied[profile.Name] {
+				continue
+			}
+			if !opts.DryRun {
+				if err := m.Delete(ctx, profile.Name); err != nil {
+					errs = append(errs, fmt.Errorf("delete provider %s: %w", profile.Name, err))
+					continue
+				}
+			}
+			changes.Deleted = append(changes.Deleted, profile.Name)
+		}
+	}
+	return changes, errors.Join(errs...)
+}
+
+// sameProvider reports whether applying profile would leave stored as is.
+func sameProvider(stored, profile config.ProviderProfile) bool {
+	normalized, err := normalizeProvider(profile)
+	if err != nil {
+		return false
+	}
+	if normalized.TenantID == "" {
+		normalized.TenantID = stored.TenantID
+	}
+	a, b := stored, normalized
+	left, errA := json.Marshal(a)
+	right, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(left) == string(right)
+}
+
 func (m *Manager) syncConfig(ctx context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -264,6 +479,9 @@ func (m *Manager) listLocked(ctx context.Context) ([]config.ProviderProfile, err
 
 	providers := make([]config.ProviderProfile, 0, len(records))
 	for _, rec := range records {
+		if err := m.openRecord(rec); err != nil {
+			return nil, err
+		}
 		profile, err := toConfigProvider(rec)
 		if err != nil {
 			return nil, err
@@ -273,6 +491,8 @@ func (m *Manager) listLocked(ctx context.Context) ([]config.ProviderProfile, err
 	return providers, nil
 }
 
+// getRecordLocked loads a provider by name. Providers of another tenant than
+// the ownership.AuthContext stored in ctx are reported as not found.
 func (m *Manager) getRecordLocked(ctx context.Context, name string) (*ent.Provider, error) {
 	rec, err := m.client.Provider.Query().Where(provider.NameEQ(name)).Only(ctx)
 	if err != nil {
@@ -281,9 +501,85 @@ func (m *Manager) getRecordLocked(ctx context.Context, name string) (*ent.Provid
 		}
 		return nil, fmt.Errorf("get provider: %w", err)
 	}
+	if ac, ok := ownership.AuthContextFromContext(ctx); ok && !ac.InTenant(rec.TenantID) {
+		return nil, ErrProviderNotFound
+	}
+	if err := m.openRecord(rec); err != nil {
+		return nil, err
+	}
 	return rec, nil
 }
 
+// EncryptSecrets re-saves stored API keys and proxies that are still in
+// plaintext under the configured master key and returns how many providers
+// changed.
+func (m *Manager) EncryptSecrets(ctx context.Context) (int, error) {
+	box, err := m.cfg.SecretBox()
+	if err != nil {
+		return 0, err
+	}
+	if box == nil {
+		return 0, fmt.Errorf("no master key configured; set %s", secrets.KeyEnv)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	records, err := m.client.Provider.Query().All(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("query providers: %w", err)
+	}
+	changed := 0
+	for _, rec := range records {
+		apiKey, proxy, err := m.sealCredentials(rec.APIKey, rec.Proxy)
+		if err != nil {
+			return changed, err
+		}
+		if apiKey == rec.APIKey && proxy == rec.Proxy {
+			continue
+		}
+		if err := m.client.Provider.UpdateOneID(rec.ID).SetAPIKey(apiKey).SetProxy(proxy).Exec(ctx); err != nil {
+			return changed, fmt.Errorf("encrypt provider %s: %w", rec.Name, err)
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// sealCredentials encrypts an API key and proxy when a master key is
+// configured.
+func (m *Manager) sealCredentials(apiKey, proxy string) (string, string, error) {
+	box, err := m.cfg.SecretBox()
+	if err != nil {
+		return "", "", err
+	}
+	if apiKey, err = box.Encrypt(apiKey); err != nil {
+		return "", "", fmt.Errorf("encrypt provider api key: %w", err)
+	}
+	if proxy, err = box.Encrypt(proxy); err != nil {
+		return "", "", fmt.Errorf("encrypt provider proxy: %w", err)
+	}
+	return apiKey, proxy, nil
+}
+
+// openRecord decrypts a loaded record's credentials in place.
+func (m *Manager) openRecord(rec *ent.Provider) error {
+	if !secrets.IsEncrypted(rec.APIKey) && !secrets.IsEncrypted(rec.Proxy) {
+		return nil
+	}
+	box, err := m.cfg.SecretBox()
+	if err != nil {
+		return err
+	}
+	if rec.APIKey, err = box.Decrypt(rec.APIKey); err != nil {
+		return fmt.Errorf("provider %s api key: %w", rec.Name, err)
+	}
+	if rec.Proxy, err = box.Decrypt(rec.Proxy); err != nil {
+		return fmt.Errorf("provider %s proxy: %w", rec.Name, err)
+	}
+	return nil
+}
+
 func (m *Manager) existsLocked(ctx context.Context, name string) (bool, error) {
 	exists, err := m.client.Provider.Query().Where(provider.NameEQ(name)).Exist(ctx)
 	if err != nil {
@@ -293,17 +589,40 @@ func (m *Manager) existsLocked(ctx context.Context, name string) (bool, error) {
 }
 
 func (m *Manager) insertLocked(ctx context.Context, profile config.ProviderProfile) error {
-	_, err := m.client.Provider.Create().
+	caps, err := encodeCapabilities(profile.Capabilities)
+	if err != nil {
+		return err
+	}
+	deployments, err := encodeDeployments(profile.Deployments)
+	if err != nil {
+		return err
+	}
+	middleware, err := encodeMiddleware(profile.Middleware)
+	if err != nil {
+		return err
+	}
+	apiKey, proxy, err := m.sealCredentials(profile.APIKey, profile.Proxy)
+	if err != nil {
+		return err
+	}
+	_, err = m.client.Provider.Create().
 		SetName(profile.Name).
 		SetProviderKind(profile.ProviderKind).
-		SetAPIKey(profile.APIKey).
+		SetAPIKey(apiKey).
 		SetAPIBase(profile.APIBase).
-		SetProxy(profile.Proxy).
+		SetProxy(proxy).
 		SetDefaultWeight(profile.DefaultWeight).
 		SetEnabled(profile.Enabled).
 		SetDefaultTestModel(profile.DefaultTestModel).
 		SetAPIFormat(profile.APIFormat).
 		SetTimeout(profile.Timeout).
+		SetStream(profile.StreamingEnabled()).
+		SetSystemPrefix(profile.SystemPrefix).
+		SetAPIVersion(profile.APIVersion).
+		SetDeploymentsJSON(deployments).
+		SetMiddlewareJSON(middleware).
+		SetCapabilitiesJSON(caps).
+		SetTenantID(profile.TenantID).
 		Save(ctx)
 	if err != nil {
 		if ent.IsConstraintError(err) {
@@ -318,6 +637,18 @@ func toConfigProvider(rec *ent.Provider) (config.ProviderProfile, error) {
 	if rec == nil {
 		return config.ProviderProfile{}, fmt.Errorf("provider record is nil")
 	}
+	caps, err := decodeCapabilities(rec.CapabilitiesJSON)
+	if err != nil {
+		return config.ProviderProfile{}, err
+	}
+	deployments, err := decodeDeployments(rec.DeploymentsJSON)
+	if err != nil {
+		return config.ProviderProfile{}, err
+	}
+	middleware, err := decodeMiddleware(rec.MiddlewareJSON)
+	if err != nil {
+		return config.ProviderProfile{}, err
+	}
 	return config.ProviderProfile{
 		Name:             rec.Name,
 		ProviderKind:     rec.ProviderKind,
@@ -329,9 +660,110 @@ func toConfigProvider(rec *ent.Provider) (config.ProviderProfile, error) {
 		DefaultTestModel: rec.DefaultTestModel,
 		APIFormat:        rec.APIFormat,
 		Timeout:          rec.Timeout,
+		Stream:           &rec.Stream,
+		SystemPrefix:     rec.SystemPrefix,
+		APIVersion:       rec.APIVersion,
+		Deployments:      deployments,
+		Middleware:       middleware,
+		Capabilities:     caps,
+		TenantID:         rec.TenantID,
 	}, nil
 }
 
+func encodeDeployments(deployments map[string]string) (string, error) {
+	if len(deployments) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(deployments)
+	if err != nil {
+		return "", fmt.Errorf("encode provider deployments: %w", err)
+	}
+	return string(data), nil
+}
+
+func decodeDeployments(raw string) (map[string]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var deployments map[string]string
+	if err := json.Unmarshal([]byte(raw), &deployments); err != nil {
+		return nil, fmt.Errorf("decode provider deployments: %w", err)
+	}
+	return deployments, nil
+}
+
+func encodeMiddleware(mw *config.ProviderMiddlewareConfig) (string, error) {
+	if mw == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(mw)
+	if err != nil {
+		return "", fmt.Errorf("encode provider middleware: %w", err)
+	}
+	return string(data), nil
+}
+
+func decodeMiddleware(raw string) (*config.ProviderMiddlewareConfig, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var mw config.ProviderMiddlewareConfig
+	if err := json.Unmarshal([]byte(raw), &mw); err != nil {
+		return nil, fmt.Errorf("decode provider middleware: %w", err)
+	}
+	return &mw, nil
+}
+
+// normalizeMiddleware trims header names and values and returns nil when
+// no middleware behavior is enabled.
+func normalizeMiddleware(mw *config.ProviderMiddlewareConfig) (*config.ProviderMiddlewareConfig, error) {
+	if mw == nil {
+		return nil, nil
+	}
+	if mw.MaxRetries < 0 || mw.RetryBackoffMS < 0 || mw.AttemptTimeout < 0 || mw.MaxRequestCost < 0 {
+		return nil, fmt.Errorf("%w: middleware values must not be negative", ErrInvalidProvider)
+	}
+	normalized := *mw
+	normalized.Headers = nil
+	for key, value := range mw.Headers {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if normalized.Headers == nil {
+			normalized.Headers = make(map[string]string, len(mw.Headers))
+		}
+		normalized.Headers[key] = strings.TrimSpace(value)
+	}
+	if normalized.MaxRetries == 0 && normalized.AttemptTimeout == 0 && normalized.MaxRequestCost == 0 &&
+		len(normalized.Headers) == 0 && !normalized.LogRequests {
+		return nil, nil
+	}
+	return &normalized, nil
+}
+
+func encodeCapabilities(caps *config.ProviderCapabilities) (string, error) {
+	if caps == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(caps)
+	if err != nil {
+		return "", fmt.Errorf("encode provider capabilities: %w", err)
+	}
+	return string(data), nil
+}
+
+func decodeCapabilities(raw string) (*config.ProviderCapabilities, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var caps config.ProviderCapabilities
+	if err := json.Unmarshal([]byte(raw), &caps); err != nil {
+		return nil, fmt.Errorf("decode provider capabilities: %w", err)
+	}
+	return &caps, nil
+}
+
 func normalizeProvider(profile config.ProviderProfile) (config.ProviderProfile, error) {
 	profile.Name = strings.TrimSpace(profile.Name)
 	if profile.Name == "" {
@@ -348,6 +780,25 @@ func normalizeProvider(profile config.ProviderProfile) (config.ProviderProfile,
 	profile.Proxy = strings.TrimSpace(profile.Proxy)
 	profile.DefaultTestModel = strings.TrimSpace(profile.DefaultTestModel)
 	profile.APIFormat = strings.TrimSpace(profile.APIFormat)
+	profile.SystemPrefix = strings.TrimSpace(profile.SystemPrefix)
+	profile.APIVersion = strings.TrimSpace(profile.APIVersion)
+	deployments := make(map[string]string, len(profile.Deployments))
+	for alias, deployment := range profile.Deployments {
+		alias, deployment = strings.TrimSpace(alias), strings.TrimSpace(deployment)
+		if alias == "" || deployment == "" {
+			continue
+		}
+		deployments[alias] = deployment
+	}
+	profile.Deployments = nil
+	if len(deployments) > 0 {
+		profile.Deployments = deployments
+	}
+	middleware, err := normalizeMiddleware(profile.Middleware)
+	if err != nil {
+		return config.ProviderProfile{}, err
+	}
+	profile.Middleware = middleware
 	profile.Models = []string{}
 	profile.DefaultModel = ""
 	if profile.DefaultWeight <= 0 {
@@ -363,6 +814,8 @@ func normalizeProvider(profile config.ProviderProfile) (config.ProviderProfile,
 	if profile.Timeout <= 0 {
 		profile.Timeout = 60
 	}
+	stream := profile.StreamingEnabled()
+	profile.Stream = &stream
 
 	if meta, ok := providerregistry.Get(profile.ProviderKind); ok {
 		for _, field := range meta.AuthFields {
@@ -374,6 +827,10 @@ func normalizeProvider(profile config.ProviderProfile) (config.ProviderProfile,
 				if profile.APIKey == "" {
 					return config.ProviderProfile{}, fmt.Errorf("%w: api key is required for %s", ErrInvalidProvider, profile.ProviderKind)
 				}
+			case "api_base":
+				if profile.APIBase == "" {
+					return config.ProviderProfile{}, fmt.Errorf("%w: api base is required for %s", ErrInvalidProvider, profile.ProviderKind)
+				}
 			}
 		}
 	}
@@ -391,6 +848,12 @@ func cloneProviders(src []config.ProviderProfile) []config.ProviderProfile {
 		dst[i].DefaultModel = ""
 		dst[i].DefaultTestModel = src[i].DefaultTestModel
 		dst[i].APIFormat = src[i].APIFormat
+		dst[i].Deployments = maps.Clone(src[i].Deployments)
+		if src[i].Middleware != nil {
+			middleware := *src[i].Middleware
+			middleware.Headers = maps.Clone(src[i].Middleware.Headers)
+			dst[i].Middleware = &middleware
+		}
 	}
 	return dst
 }
@@ -3,10 +3,13 @@ package providerstore
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 
 	"nekobot/pkg/config"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/ownership"
+	"nekobot/pkg/secrets"
 	"nekobot/pkg/storage/ent"
 )
 
@@ -216,6 +219,365 @@ func TestManagerRejectsProviderMissingRequiredAPIKey(t *testing.T) {
 	}
 }
 
+func TestManagerPersistsStreamToggle(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Fatalf("close ent client: %v", err)
+		}
+	})
+
+	mgr, err := NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	created, err := mgr.Create(ctx, config.ProviderProfile{Name: "local", ProviderKind: "openai", APIKey: "k"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if !created.StreamingEnabled() {
+		t.Fatalf("expected streaming enabled by default, got %+v", created)
+	}
+
+	disabled := false
+	if _, err := mgr.Update(ctx, "local", config.ProviderProfile{Stream: &disabled}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := mgr.Update(ctx, "local", config.ProviderProfile{DefaultWeight: 2}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	got, err := mgr.Get(ctx, "local")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.StreamingEnabled() {
+		t.Fatalf("expected streaming to stay disabled across updates, got %+v", got)
+	}
+	if cfg.Providers[0].StreamingEnabled() {
+		t.Fatalf("expected synced config to carry stream=false, got %+v", cfg.Providers[0])
+	}
+}
+
+func TestManagerPersistsSystemPrefix(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Fatalf("close ent client: %v", err)
+		}
+	})
+
+	mgr, err := NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	if _, err := mgr.Create(ctx, config.ProviderProfile{
+		Name:         "local",
+		ProviderKind: "openai",
+		APIKey:       "k",
+		SystemPrefix: "  Respond concisely.  ",
+	}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	got, err := mgr.Get(ctx, "local")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.SystemPrefix != "Respond concisely." || cfg.Providers[0].SystemPrefix != "Respond concisely." {
+		t.Fatalf("expected trimmed prefix to be stored and synced, got %+v / %+v", got, cfg.Providers[0])
+	}
+
+	if _, err := mgr.Update(ctx, "local", config.ProviderProfile{DefaultWeight: 2}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got, err = mgr.Get(ctx, "local")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.SystemPrefix != "" {
+		t.Fatalf("expected an update without a prefix to clear it, got %q", got.SystemPrefix)
+	}
+}
+
+func TestManagerPersistsAzureDeployments(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Fatalf("close ent client: %v", err)
+		}
+	})
+
+	mgr, err := NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	if _, err := mgr.Create(ctx, config.ProviderProfile{
+		Name:         "azure",
+		ProviderKind: "azure-openai",
+		APIKey:       "k",
+	}); err == nil {
+		t.Fatalf("expected Azure provider without an endpoint to be rejected")
+	}
+
+	if _, err := mgr.Create(ctx, config.ProviderProfile{
+		Name:         "azure",
+		ProviderKind: "azure-openai",
+		APIKey:       "k",
+		APIBase:      "https://res.openai.azure.com",
+		APIVersion:   " 2024-10-21 ",
+		Deployments:  map[string]string{" gpt-4o ": " prod-4o ", "empty": " "},
+	}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	got, err := mgr.Get(ctx, "azure")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.APIVersion != "2024-10-21" || len(got.Deployments) != 1 || got.Deployments["gpt-4o"] != "prod-4o" {
+		t.Fatalf("expected normalized Azure settings, got %+v", got)
+	}
+	if cfg.Providers[0].Deployments["gpt-4o"] != "prod-4o" {
+		t.Fatalf("expected deployments to sync into config, got %+v", cfg.Providers[0])
+	}
+
+	if _, err := mgr.Update(ctx, "azure", config.ProviderProfile{DefaultWeight: 2}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got, err = mgr.Get(ctx, "azure")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.APIVersion != "2024-10-21" || got.Deployments["gpt-4o"] != "prod-4o" {
+		t.Fatalf("expected an update without Azure settings to keep them, got %+v", got)
+	}
+
+	if _, err := mgr.Update(ctx, "azure", config.ProviderProfile{Deployments: map[string]string{}}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got, err = mgr.Get(ctx, "azure")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(got.Deployments) != 0 {
+		t.Fatalf("expected an empty mapping to clear deployments, got %+v", got.Deployments)
+	}
+}
+
+func TestManagerPersistsMiddleware(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Fatalf("close ent client: %v", err)
+		}
+	})
+
+	mgr, err := NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	if _, err := mgr.Create(ctx, config.ProviderProfile{
+		Name:         "gateway",
+		ProviderKind: "openai",
+		APIKey:       "k",
+		Middleware: &config.ProviderMiddlewareConfig{
+			MaxRetries:     2,
+			MaxRequestCost: 0.5,
+			Headers:        map[string]string{" X-Team ": " ops ", " ": "dropped"},
+		},
+	}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	got, err := mgr.Get(ctx, "gateway")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Middleware == nil || got.Middleware.MaxRetries != 2 || got.Middleware.MaxRequestCost != 0.5 ||
+		len(got.Middleware.Headers) != 1 || got.Middleware.Headers["X-Team"] != "ops" {
+		t.Fatalf("expected normalized middleware, got %+v", got.Middleware)
+	}
+
+	if _, err := mgr.Update(ctx, "gateway", config.ProviderProfile{DefaultWeight: 2}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got, err = mgr.Get(ctx, "gateway")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Middleware == nil || got.Middleware.MaxRetries != 2 {
+		t.Fatalf("expected an update without middleware to keep it, got %+v", got.Middleware)
+	}
+
+	if _, err := mgr.Update(ctx, "gateway", config.ProviderProfile{Middleware: &config.ProviderMiddlewareConfig{}}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got, err = mgr.Get(ctx, "gateway")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Middleware != nil {
+		t.Fatalf("expected empty middleware to clear it, got %+v", got.Middleware)
+	}
+
+	if _, err := mgr.Update(ctx, "gateway", config.ProviderProfile{Middleware: &config.ProviderMiddlewareConfig{MaxRetries: -1}}); err == nil {
+		t.Fatalf("expected negative middleware values to be rejected")
+	}
+}
+
+func TestManagerStoresProbedCapabilities(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Fatalf("close ent client: %v", err)
+		}
+	})
+
+	mgr, err := NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if _, err := mgr.Create(ctx, config.ProviderProfile{Name: "local", ProviderKind: "openai", APIKey: "k"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	supported, unsupported := true, false
+	if _, err := mgr.SetCapabilities(ctx, "local", &config.ProviderCapabilities{
+		Model:       "small-model",
+		ToolCalling: &unsupported,
+		Streaming:   &supported,
+		ProbedAt:    1700000000,
+	}); err != nil {
+		t.Fatalf("SetCapabilities failed: %v", err)
+	}
+
+	// A regular profile update keeps the cached probe result.
+	if _, err := mgr.Update(ctx, "local", config.ProviderProfile{DefaultWeight: 2}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got, err := mgr.Get(ctx, "local")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	caps := got.Capabilities
+	if caps == nil || caps.Model != "small-model" || caps.ToolCalling == nil || *caps.ToolCalling ||
+		caps.Streaming == nil || !*caps.Streaming || caps.Vision != nil || caps.ProbedAt != 1700000000 {
+		t.Fatalf("unexpected stored capabilities: %+v", caps)
+	}
+	if !cfg.Providers[0].Capabilities.ToolCallingUnsupported("small-model") {
+		t.Fatalf("expected capabilities to be synced to runtime config, got %+v", cfg.Providers[0].Capabilities)
+	}
+
+	if _, err := mgr.SetCapabilities(ctx, "local", nil); err != nil {
+		t.Fatalf("SetCapabilities(nil) failed: %v", err)
+	}
+	if cfg.Providers[0].Capabilities != nil {
+		t.Fatalf("expected capabilities to be cleared, got %+v", cfg.Providers[0].Capabilities)
+	}
+	if _, err := mgr.SetCapabilities(ctx, "missing", nil); !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("expected ErrProviderNotFound, got %v", err)
+	}
+}
+
+func TestManagerScopesProvidersToTenant(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Fatalf("close ent client: %v", err)
+		}
+	})
+
+	mgr, err := NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	admin := ownership.WithAuthContext(ctx, ownership.AuthContext{
+		UserID: "u-admin", TenantID: "t-default", TenantSlug: ownership.DefaultTenantSlug, Role: "owner",
+	})
+	teamA := ownership.WithAuthContext(ctx, ownership.AuthContext{
+		UserID: "u-a", TenantID: "t-a", TenantSlug: "team-a", Role: "owner",
+	})
+	teamB := ownership.WithAuthContext(ctx, ownership.AuthContext{
+		UserID: "u-b", TenantID: "t-b", TenantSlug: "team-b", Role: "operator",
+	})
+
+	if _, err := mgr.Create(admin, config.ProviderProfile{Name: "shared", ProviderKind: "openai", APIKey: "k"}); err != nil {
+		t.Fatalf("Create shared failed: %v", err)
+	}
+	// Tenant users cannot place providers in another tenant.
+	created, err := mgr.Create(teamA, config.ProviderProfile{Name: "team-a", ProviderKind: "openai", APIKey: "k", TenantID: "t-b"})
+	if err != nil {
+		t.Fatalf("Create team-a failed: %v", err)
+	}
+	if created.TenantID != "t-a" {
+		t.Fatalf("expected provider in tenant t-a, got %q", created.TenantID)
+	}
+
+	names := func(ctx context.Context) []string {
+		t.Helper()
+		providers, err := mgr.List(ctx)
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		out := make([]string, 0, len(providers))
+		for _, p := range providers {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+	if got := names(admin); len(got) != 2 {
+		t.Fatalf("expected admin to see every provider, got %v", got)
+	}
+	if got := names(teamA); len(got) != 1 || got[0] != "team-a" {
+		t.Fatalf("expected team-a to see only its provider, got %v", got)
+	}
+	if got := names(teamB); len(got) != 0 {
+		t.Fatalf("expected team-b to see no providers, got %v", got)
+	}
+	if _, err := mgr.Get(teamB, "team-a"); !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("expected other tenant provider to be hidden, got %v", err)
+	}
+	if err := mgr.Delete(teamB, "team-a"); !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("expected delete of other tenant provider to fail, got %v", err)
+	}
+	// The runtime config still carries every provider.
+	if len(cfg.Providers) != 2 {
+		t.Fatalf("expected runtime config to keep all providers, got %+v", cfg.Providers)
+	}
+}
+
 func newTestLogger(t *testing.T) *logger.Logger {
 	t.Helper()
 	cfg := logger.DefaultConfig()
@@ -240,3 +602,164 @@ func newTestEntClient(t *testing.T, cfg *config.Config) *ent.Client {
 	}
 	return client
 }
+
+func TestManagerEncryptsCredentialsAtRest(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Fatalf("close ent client: %v", err)
+		}
+	})
+
+	// Stored before a master key existed.
+	t.Setenv(secrets.KeyEnv, "")
+	plainMgr, err := NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if _, err := plainMgr.Create(ctx, config.ProviderProfile{Name: "legacy", ProviderKind: "openai", APIKey: "sk-legacy", Proxy: "http://u:p@proxy"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	key, err := secrets.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(secrets.KeyEnv, key)
+	keyedCfg := config.DefaultConfig()
+	keyedCfg.Storage.DBDir = cfg.Storage.DBDir
+	mgr, err := NewManager(keyedCfg, log, client)
+	if err != nil {
+		t.Fatalf("NewManager with key failed: %v", err)
+	}
+	if _, err := mgr.Create(ctx, config.ProviderProfile{Name: "fresh", ProviderKind: "openai", APIKey: "sk-fresh"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	changed, err := mgr.EncryptSecrets(ctx)
+	if err != nil || changed != 1 {
+		t.Fatalf("expected one legacy provider to be encrypted, got %d, %v", changed, err)
+	}
+
+	records, err := client.Provider.Query().All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range records {
+		if !secrets.IsEncrypted(rec.APIKey) {
+			t.Fatalf("expected %s api key to be encrypted at rest, got %q", rec.Name, rec.APIKey)
+		}
+	}
+
+	legacy, err := mgr.Get(ctx, "legacy")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if legacy.APIKey != "sk-legacy" || legacy.Proxy != "http://u:p@proxy" {
+		t.Fatalf("expected decrypted credentials, got %+v", legacy)
+	}
+	updated, err := mgr.Update(ctx, "legacy", config.ProviderProfile{APIBase: "https://example.test/v1"})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if updated.APIKey != "sk-legacy" {
+		t.Fatalf("expected update to keep the decrypted key, got %q", updated.APIKey)
+	}
+	for _, p := range keyedCfg.Providers {
+		if secrets.IsEncrypted(p.APIKey) {
+			t.Fatalf("expected runtime config to hold plaintext keys, got %+v", p)
+		}
+	}
+}
+
+func TestManagerImportsReloadedConfigProviders(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() { _ = client.Close() })
+	mgr, err := NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if _, err := mgr.Create(ctx, config.ProviderProfile{Name: "stored", ProviderKind: "openai", APIKey: "k1", Enabled: true}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	cfg.Providers = []config.ProviderProfile{
+		{Name: "stored", ProviderKind: "openai", APIKey: "k1-rotated", Enabled: true},
+		{Name: "added", ProviderKind: "anthropic", APIKey: "k2", Enabled: true},
+	}
+	written, err := mgr.ImportConfigProviders(ctx)
+	if err != nil {
+		t.Fatalf("ImportConfigProviders failed: %v", err)
+	}
+	if written != 2 {
+		t.Fatalf("expected 2 providers written, got %d", written)
+	}
+
+	stored, err := mgr.Get(ctx, "stored")
+	if err != nil || stored.APIKey != "k1-rotated" {
+		t.Fatalf("expected the rotated key to be stored, got %+v (%v)", stored, err)
+	}
+	if _, err := mgr.Get(ctx, "added"); err != nil {
+		t.Fatalf("expected the added provider to be stored: %v", err)
+	}
+	if written, err := mgr.ImportConfigProviders(ctx); err != nil || written != 0 {
+		t.Fatalf("expected a second import to be a no-op, got %d (%v)", written, err)
+	}
+}
+
+func TestManagerApplyProvidersPrunesAndDryRuns(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() { _ = client.Close() })
+	mgr, err := NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	for _, name := range []string{"keep", "stale"} {
+		if _, err := mgr.Create(ctx, config.ProviderProfile{Name: name, ProviderKind: "openai", APIKey: "k", Enabled: true}); err != nil {
+			t.Fatalf("Create %s failed: %v", name, err)
+		}
+	}
+
+	profiles := []config.ProviderProfile{
+		{Name: "keep", ProviderKind: "openai", APIKey: "k2", Enabled: true},
+		{Name: "new", ProviderKind: "anthropic", APIKey: "k3", Enabled: true},
+	}
+	planned, err := mgr.ApplyProviders(ctx, profiles, ApplyOptions{Prune: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	want := ProviderChanges{Created: []string{"new"}, Updated: []string{"keep"}, Deleted: []string{"stale"}}
+	if !reflect.DeepEqual(planned, want) {
+		t.Fatalf("unexpected plan: %+v", planned)
+	}
+	if _, err := mgr.Get(ctx, "stale"); err != nil {
+		t.Fatalf("dry run deleted a provider: %v", err)
+	}
+
+	applied, err := mgr.ApplyProviders(ctx, profiles, ApplyOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	if !reflect.DeepEqual(applied, want) {
+		t.Fatalf("apply differs from plan: %+v", applied)
+	}
+	if _, err := mgr.Get(ctx, "stale"); err == nil {
+		t.Fatal("expected stale provider to be pruned")
+	}
+	if again, err := mgr.ApplyProviders(ctx, profiles, ApplyOptions{Prune: true}); err != nil || !again.Empty() {
+		t.Fatalf("expected re-apply to be a no-op, got %+v (%v)", again, err)
+	}
+}
@@ -0,0 +1,197 @@
+// Package responsefilters applies the operator-configured chain of text
+// transforms to agent responses before channels deliver them.
+package responsefilters
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+// Filter types accepted in config.ResponseFilterConfig.Type.
+const (
+	TypeRegexReplace = "regex_replace"
+	TypePrepend      = "prepend"
+	TypeAppend       = "append"
+	TypeStripBetween = "strip_between"
+)
+
+type step struct {
+	kind        string
+	pattern     *regexp.Regexp
+	replacement string
+	text        string
+	start       string
+	end         string
+	channels    map[string]struct{}
+}
+
+// Chain is a compiled, ordered list of filters.
+type Chain struct {
+	steps []step
+}
+
+// NewChain compiles filter definitions in order.
+func NewChain(filters []config.ResponseFilterConfig) (*Chain, error) {
+	chain := &Chain{steps: make([]step, 0, len(filters))}
+	for i, filter := range filters {
+		s := step{
+			kind:        strings.TrimSpace(strings.ToLower(filter.Type)),
+			replacement: filter.Replacement,
+			text:        filter.Text,
+			start:       filter.Start,
+			end:         filter.End,
+		}
+		switch s.kind {
+		case TypeRegexReplace:
+			re, err := regexp.Compile(filter.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("filter %d: compile pattern: %w", i, err)
+			}
+			s.pattern = re
+		case TypePrepend, TypeAppend:
+		case TypeStripBetween:
+			if s.start == "" || s.end == "" {
+				return nil, fmt.Errorf("filter %d: strip_between needs start and end markers", i)
+			}
+		default:
+			return nil, fmt.Errorf("filter %d: unknown type %q", i, filter.Type)
+		}
+		if len(filter.Channels) > 0 {
+			s.channels = make(map[string]struct{}, len(filter.Channels))
+			for _, channel := range filter.Channels {
+				if channel = strings.TrimSpace(channel); channel != "" {
+					s.channels[channel] = struct{}{}
+				}
+			}
+		}
+		chain.steps = append(chain.steps, s)
+	}
+	return chain, nil
+}
+
+// Apply runs every filter that matches channel over text, in order.
+func (c *Chain) Apply(channel, text string) string {
+	if c == nil {
+		return text
+	}
+	for _, s := range c.steps {
+		if !s.matches(channel) {
+			continue
+		}
+		switch s.kind {
+		case TypeRegexReplace:
+			text = s.pattern.ReplaceAllString(text, s.replacement)
+		case TypePrepend:
+			text = s.text + text
+		case TypeAppend:
+			text = text + s.text
+		case TypeStripBetween:
+			text = stripBetween(text, s.start, s.end)
+		}
+	}
+	return text
+}
+
+func (s step) matches(channel string) bool {
+	if len(s.channels) == 0 {
+		return true
+	}
+	if _, ok := s.channels[channel]; ok {
+		return true
+	}
+	// Channel instances are registered as "<type>" or "<type>:<account>".
+	if idx := strings.Index(channel, ":"); idx > 0 {
+		_, ok := s.channels[channel[:idx]]
+		return ok
+	}
+	return false
+}
+
+// stripBetween removes every start...end span, markers included. An opening
+// marker without a closing one is left untouched so a truncated reply is
+// never swallowed whole.
+func stripBetween(text, start, end string) string {
+	var b strings.Builder
+	rest := text
+	stripped := false
+	for {
+		i := strings.Index(rest, start)
+		if i < 0 {
+			break
+		}
+		j := strings.Index(rest[i+len(start):], end)
+		if j < 0 {
+			break
+		}
+		b.WriteString(rest[:i])
+		rest = rest[i+len(start)+j+len(end):]
+		stripped = true
+	}
+	if !stripped {
+		return text
+	}
+	b.WriteString(rest)
+	return strings.TrimSpace(b.String())
+}
+
+// Processor applies the chain from the live config, recompiling it whenever
+// the response_filters section changes.
+type Processor struct {
+	cfg *config.Config
+	log *logger.Logger
+
+	mu       sync.Mutex
+	snapshot config.ResponseFiltersConfig
+	chain    *Chain
+	compiled bool
+}
+
+// NewProcessor creates a processor bound to cfg.
+func NewProcessor(cfg *config.Config, log *logger.Logger) *Processor {
+	return &Processor{cfg: cfg, log: log}
+}
+
+// Apply filters text destined for channel. It returns text unchanged when
+// filtering is disabled or the configuration fails to compile.
+func (p *Processor) Apply(channel, text string) string {
+	if p == nil || p.cfg == nil || text == "" {
+		return text
+	}
+	chain := p.current()
+	if chain == nil {
+		return text
+	}
+	return chain.Apply(channel, text)
+}
+
+func (p *Processor) current() *Chain {
+	current := p.cfg.ResponseFilters
+	if !current.Enabled || len(current.Filters) == 0 {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.compiled && reflect.DeepEqual(p.snapshot, current) {
+		return p.chain
+	}
+	chain, err := NewChain(current.Filters)
+	if err != nil && p.log != nil {
+		p.log.Warn("Response filters disabled: invalid configuration", zap.Error(err))
+	}
+	p.snapshot = config.ResponseFiltersConfig{
+		Enabled: current.Enabled,
+		Filters: append([]config.ResponseFilterConfig(nil), current.Filters...),
+	}
+	p.chain = chain
+	p.compiled = true
+	return chain
+}
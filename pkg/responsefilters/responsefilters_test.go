@@ -0,0 +1,135 @@
+package responsefilters
+
+import (
+	"testing"
+
+	"nekobot/pkg/config"
+)
+
+func TestChainAppliesEachFilterType(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter config.ResponseFilterConfig
+		input  string
+		want   string
+	}{
+		{
+			name:   "regex replace with group expansion",
+			filter: config.ResponseFilterConfig{Type: "regex_replace", Pattern: `https://internal\.example/(\S+)`, Replacement: "https://docs.example/$1"},
+			input:  "see https://internal.example/guide and https://internal.example/faq",
+			want:   "see https://docs.example/guide and https://docs.example/faq",
+		},
+		{
+			name:   "prepend",
+			filter: config.ResponseFilterConfig{Type: "prepend", Text: "Bot: "},
+			input:  "hi",
+			want:   "Bot: hi",
+		},
+		{
+			name:   "append",
+			filter: config.ResponseFilterConfig{Type: "append", Text: "\n\n-- AI generated"},
+			input:  "hi",
+			want:   "hi\n\n-- AI generated",
+		},
+		{
+			name:   "strip between removes every span",
+			filter: config.ResponseFilterConfig{Type: "strip_between", Start: "<think>", End: "</think>"},
+			input:  "<think>plan</think>\n\nAnswer <think>more</think>done",
+			want:   "Answer done",
+		},
+		{
+			name:   "strip between keeps unterminated marker",
+			filter: config.ResponseFilterConfig{Type: "strip_between", Start: "<think>", End: "</think>"},
+			input:  "<think>cut off",
+			want:   "<think>cut off",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := NewChain([]config.ResponseFilterConfig{tt.filter})
+			if err != nil {
+				t.Fatalf("NewChain failed: %v", err)
+			}
+			if got := chain.Apply("telegram", tt.input); got != tt.want {
+				t.Fatalf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChainAppliesFiltersInOrder(t *testing.T) {
+	filters := []config.ResponseFilterConfig{
+		{Type: "strip_between", Start: "<think>", End: "</think>"},
+		{Type: "append", Text: " [end]"},
+		{Type: "regex_replace", Pattern: `\[end\]`, Replacement: "[fin]"},
+		{Type: "prepend", Text: "> "},
+	}
+	chain, err := NewChain(filters)
+	if err != nil {
+		t.Fatalf("NewChain failed: %v", err)
+	}
+	if got := chain.Apply("slack", "<think>x</think>answer"); got != "> answer [fin]" {
+		t.Fatalf("unexpected ordered result %q", got)
+	}
+
+	reversed := []config.ResponseFilterConfig{filters[3], filters[2], filters[1], filters[0]}
+	chain, err = NewChain(reversed)
+	if err != nil {
+		t.Fatalf("NewChain failed: %v", err)
+	}
+	if got := chain.Apply("slack", "<think>x</think>answer"); got != "> answer [end]" {
+		t.Fatalf("unexpected reversed result %q", got)
+	}
+}
+
+func TestChainScopesFiltersToChannels(t *testing.T) {
+	chain, err := NewChain([]config.ResponseFilterConfig{
+		{Type: "append", Text: " (tg)", Channels: []string{"telegram"}},
+	})
+	if err != nil {
+		t.Fatalf("NewChain failed: %v", err)
+	}
+	if got := chain.Apply("telegram:support", "hi"); got != "hi (tg)" {
+		t.Fatalf("expected account-scoped channel to match its type, got %q", got)
+	}
+	if got := chain.Apply("discord", "hi"); got != "hi" {
+		t.Fatalf("expected other channels to be skipped, got %q", got)
+	}
+}
+
+func TestNewChainRejectsInvalidFilters(t *testing.T) {
+	for _, filter := range []config.ResponseFilterConfig{
+		{Type: "regex_replace", Pattern: "("},
+		{Type: "strip_between", Start: "<a>"},
+		{Type: "shout"},
+	} {
+		if _, err := NewChain([]config.ResponseFilterConfig{filter}); err == nil {
+			t.Fatalf("expected %+v to be rejected", filter)
+		}
+	}
+}
+
+func TestProcessorFollowsConfigChanges(t *testing.T) {
+	cfg := config.DefaultConfig()
+	p := NewProcessor(cfg, nil)
+	if got := p.Apply("telegram", "hi"); got != "hi" {
+		t.Fatalf("expected disabled filters to pass through, got %q", got)
+	}
+
+	cfg.ResponseFilters.Enabled = true
+	cfg.ResponseFilters.Filters = []config.ResponseFilterConfig{{Type: "append", Text: "!"}}
+	if got := p.Apply("telegram", "hi"); got != "hi!" {
+		t.Fatalf("expected append filter, got %q", got)
+	}
+
+	cfg.ResponseFilters.Filters = []config.ResponseFilterConfig{{Type: "prepend", Text: "> "}}
+	if got := p.Apply("telegram", "hi"); got != "> hi" {
+		t.Fatalf("expected recompiled chain, got %q", got)
+	}
+
+	cfg.ResponseFilters.Filters = []config.ResponseFilterConfig{{Type: "regex_replace", Pattern: "("}}
+	if got := p.Apply("telegram", "hi"); got != "hi" {
+		t.Fatalf("expected invalid config to pass through, got %q", got)
+	}
+}
@@ -16,6 +16,7 @@ var (
 	reMarkdownStrike  = regexp.MustCompile(`~~(.+?)~~`)
 	reMarkdownQuote   = regexp.MustCompile(`(?m)^>\s?`)
 	reMarkdownList    = regexp.MustCompile(`(?m)^(\s*)[-*+]\s+`)
+	reMarkdownFence   = regexp.MustCompile("(?s)```([^\\n`]*)\\n(.*?)```")
 )
 
 const longMarkdownThreshold = 400
@@ -61,6 +62,57 @@ func MarkdownToPlainText(text string) string {
 	return strings.TrimSpace(result)
 }
 
+// CodeBlock is a fenced code block extracted from markdown.
+type CodeBlock struct {
+	Language string
+	// Filename is set when the fence info names a file, as in "```go main.go",
+	// "```go:main.go" or "```go title=\"main.go\"".
+	Filename string
+	Content  string
+}
+
+// ExtractCodeBlocks returns the fenced code blocks in text, in order.
+func ExtractCodeBlocks(text string) []CodeBlock {
+	matches := reMarkdownFence.FindAllStringSubmatch(text, -1)
+	blocks := make([]CodeBlock, 0, len(matches))
+	for _, match := range matches {
+		language, filename := parseFenceInfo(match[1])
+		blocks = append(blocks, CodeBlock{
+			Language: language,
+			Filename: filename,
+			Content:  strings.TrimSuffix(match[2], "\n"),
+		})
+	}
+	return blocks
+}
+
+func parseFenceInfo(info string) (language, filename string) {
+	fields := strings.Fields(info)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	language = fields[0]
+	if lang, name, ok := strings.Cut(language, ":"); ok {
+		language, filename = lang, name
+	}
+	for _, field := range fields[1:] {
+		if filename != "" {
+			break
+		}
+		if key, value, ok := strings.Cut(field, "="); ok {
+			switch strings.ToLower(key) {
+			case "title", "file", "filename", "name":
+				filename = strings.Trim(value, `"'`)
+			}
+			continue
+		}
+		if strings.ContainsAny(field, "./") {
+			filename = field
+		}
+	}
+	return strings.ToLower(language), strings.TrimSpace(filename)
+}
+
 // SplitPlainText breaks text into messaging-friendly chunks.
 func SplitPlainText(text string, maxLen int) []string {
 	trimmed := strings.TrimSpace(text)
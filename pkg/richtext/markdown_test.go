@@ -36,3 +36,22 @@ func TestBuildMarkdownHTML(t *testing.T) {
 		t.Fatalf("expected markdown content in html, got %q", html)
 	}
 }
+
+func TestExtractCodeBlocks(t *testing.T) {
+	input := "Intro\n```go main.go\npackage main\n```\ntext\n```python:scripts/run.py\nprint('hi')\n```\n```sh title=\"setup.sh\"\necho ok\n```\n```\nplain\n```"
+	blocks := ExtractCodeBlocks(input)
+	want := []CodeBlock{
+		{Language: "go", Filename: "main.go", Content: "package main"},
+		{Language: "python", Filename: "scripts/run.py", Content: "print('hi')"},
+		{Language: "sh", Filename: "setup.sh", Content: "echo ok"},
+		{Content: "plain"},
+	}
+	if len(blocks) != len(want) {
+		t.Fatalf("expected %d blocks, got %#v", len(want), blocks)
+	}
+	for i := range want {
+		if blocks[i] != want[i] {
+			t.Fatalf("block %d = %#v, want %#v", i, blocks[i], want[i])
+		}
+	}
+}
@@ -170,7 +170,7 @@ func (m *Manager) Update(ctx context.Context, id string, item AgentRuntime) (*Ag
 			}
 			return nil, fmt.Errorf("get agent runtime for auth: %w", lookupErr)
 		}
-		if !ac.CanWrite(existing.OwnerUserID) {
+		if !ac.CanWrite(existing.OwnerUserID, existing.TenantID) {
 			return nil, ownership.ErrPermissionDenied
 		}
 		normalized.OwnerUserID, normalized.TenantID, normalized.Visibility = ac.ValidateUpdateOwnership(
@@ -236,7 +236,7 @@ func (m *Manager) Delete(ctx context.Context, id string) error {
 			}
 			return fmt.Errorf("get agent runtime for auth: %w", lookupErr)
 		}
-		if !ac.CanWrite(existing.OwnerUserID) {
+		if !ac.CanWrite(existing.OwnerUserID, existing.TenantID) {
 			return ownership.ErrPermissionDenied
 		}
 	}
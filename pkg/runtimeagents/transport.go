@@ -17,6 +17,10 @@ const (
 
 	TransportTmux   = "tmux"
 	TransportZellij = "zellij"
+	// TransportNative runs the command directly in nekobot's PTY process
+	// manager. Sessions survive WebSocket reconnects but not a restart of
+	// nekobot; their scrollback does.
+	TransportNative = "native"
 )
 
 type LaunchInfo struct {
@@ -41,13 +45,18 @@ type RuntimeTransport interface {
 
 type tmuxTransport struct{}
 type zellijTransport struct{}
+type nativeTransport struct{}
 
+// DefaultTransport returns tmux when it is installed and the native PTY
+// transport otherwise.
 func DefaultTransport() RuntimeTransport {
-	return tmuxTransport{}
+	return ResolveTransport(TransportTmux)
 }
 
 func TransportByName(name string) RuntimeTransport {
 	switch strings.TrimSpace(strings.ToLower(name)) {
+	case TransportNative:
+		return nativeTransport{}
 	case TransportZellij:
 		return zellijTransport{}
 	case TransportTmux:
@@ -57,6 +66,16 @@ func TransportByName(name string) RuntimeTransport {
 	}
 }
 
+// ResolveTransport returns the named transport, or the native transport
+// when that multiplexer is not installed.
+func ResolveTransport(name string) RuntimeTransport {
+	transport := TransportByName(name)
+	if !transport.Available() {
+		return nativeTransport{}
+	}
+	return transport
+}
+
 func (tmuxTransport) Name() string {
 	return TransportTmux
 }
@@ -155,6 +174,31 @@ func (t zellijTransport) KillSession(sessionID string) {
 	}
 }
 
+func (nativeTransport) Name() string {
+	return TransportNative
+}
+
+func (nativeTransport) Available() bool {
+	return true
+}
+
+func (t nativeTransport) WrapStart(command, sessionID string) LaunchInfo {
+	return LaunchInfo{
+		TransportName: t.Name(),
+		SessionName:   strings.TrimSpace(sessionID),
+		LaunchCommand: command,
+	}
+}
+
+// BuildReattach never succeeds: a native session lives only as long as the
+// process manager that started it, which reattaches on its own.
+func (nativeTransport) BuildReattach(sessionID string) (ReattachInfo, bool) {
+	return ReattachInfo{}, false
+}
+
+// KillSession is a no-op; the process manager owns native sessions.
+func (nativeTransport) KillSession(sessionID string) {}
+
 func ApplyLaunchMetadata(metadata map[string]interface{}, info LaunchInfo) map[string]interface{} {
 	if metadata == nil {
 		metadata = map[string]interface{}{}
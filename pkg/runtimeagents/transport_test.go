@@ -141,3 +141,27 @@ func TestZellijKillSessionDeletesResurrectableSession(t *testing.T) {
 		t.Fatalf("expected zellij session %q to be removed after KillSession", name)
 	}
 }
+
+func TestResolveTransportFallsBackToNative(t *testing.T) {
+	native := ResolveTransport(TransportNative)
+	if native.Name() != TransportNative || !native.Available() {
+		t.Fatalf("expected an available native transport, got %q", native.Name())
+	}
+	info := native.WrapStart("codex", "sess-1")
+	if info.TransportName != TransportNative || info.LaunchCommand != "codex" || info.SessionName != "sess-1" {
+		t.Fatalf("expected native launch to run the command unwrapped, got %+v", info)
+	}
+	if _, ok := native.BuildReattach("sess-1"); ok {
+		t.Fatal("did not expect a native session to be reattached through a launch command")
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	for _, name := range []string{TransportTmux, TransportZellij, ""} {
+		if got := ResolveTransport(name).Name(); got != TransportNative {
+			t.Fatalf("expected %q without multiplexers to fall back to native, got %q", name, got)
+		}
+	}
+	if got := DefaultTransport().Name(); got != TransportNative {
+		t.Fatalf("expected default transport to fall back to native, got %q", got)
+	}
+}
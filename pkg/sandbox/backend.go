@@ -0,0 +1,61 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Backend names accepted by NewBackend.
+const (
+	BackendDocker      = "docker"
+	BackendGVisor      = "gvisor"
+	BackendFirecracker = "firecracker"
+)
+
+// Default OCI runtimes the isolating backends ask Docker for.
+const (
+	defaultGVisorRuntime      = "runsc"
+	defaultFirecrackerRuntime = "kata-fc"
+)
+
+// Limits bound the resources of one sandbox container. Zero values leave the
+// engine's defaults in place.
+type Limits struct {
+	CPUs     float64
+	MemoryMB int
+	Pids     int64
+}
+
+// Backend is the isolation technology sandbox containers run on.
+type Backend interface {
+	Containers
+	// Name identifies the backend in tool output.
+	Name() string
+	// Run executes command in a fresh container and waits for it to exit.
+	Run(ctx context.Context, spec Spec, command []string, workdir string) (ExecResult, error)
+}
+
+// NewBackend returns the backend called name. The gVisor backend runs
+// containers under runsc, and the Firecracker backend runs each container in
+// a microVM through the Kata Containers runtime; ociRuntime overrides the
+// runtime name registered with Docker.
+func NewBackend(name, ociRuntime string) (Backend, error) {
+	ociRuntime = strings.TrimSpace(ociRuntime)
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", BackendDocker:
+		return NewDockerRuntime(BackendDocker, ociRuntime), nil
+	case BackendGVisor:
+		if ociRuntime == "" {
+			ociRuntime = defaultGVisorRuntime
+		}
+		return NewDockerRuntime(BackendGVisor, ociRuntime), nil
+	case BackendFirecracker:
+		if ociRuntime == "" {
+			ociRuntime = defaultFirecrackerRuntime
+		}
+		return NewDockerRuntime(BackendFirecracker, ociRuntime), nil
+	default:
+		return nil, fmt.Errorf("unknown sandbox runtime %q", name)
+	}
+}
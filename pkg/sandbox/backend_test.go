@@ -0,0 +1,36 @@
+package sandbox
+
+import "testing"
+
+func TestNewBackendSelectsRuntimeAndAppliesLimits(t *testing.T) {
+	cases := map[string]string{
+		"":            "docker",
+		"docker":      "docker",
+		"gvisor":      "gvisor (runsc)",
+		"firecracker": "firecracker (kata-fc)",
+	}
+	for name, want := range cases {
+		backend, err := NewBackend(name, "")
+		if err != nil {
+			t.Fatalf("NewBackend(%q): %v", name, err)
+		}
+		if backend.Name() != want {
+			t.Fatalf("NewBackend(%q).Name() = %q, want %q", name, backend.Name(), want)
+		}
+	}
+	if _, err := NewBackend("lxc", ""); err == nil {
+		t.Fatal("expected unknown runtime to be rejected")
+	}
+
+	backend, _ := NewBackend("gvisor", "runsc-kvm")
+	host := backend.(*Docker).hostConfig(Spec{Workspace: "/ws", Limits: Limits{CPUs: 1.5, MemoryMB: 256, Pids: 64}})
+	if host.Runtime != "runsc-kvm" {
+		t.Fatalf("expected the OCI runtime override, got %q", host.Runtime)
+	}
+	if host.NanoCPUs != 1_500_000_000 || host.Memory != 256<<20 || host.MemorySwap != host.Memory || host.PidsLimit == nil || *host.PidsLimit != 64 {
+		t.Fatalf("unexpected resource limits: %+v", host.Resources)
+	}
+	if len(host.Mounts) != 1 || host.Mounts[0].Source != "/ws" || host.Mounts[0].Target != WorkspaceMount {
+		t.Fatalf("expected the workspace mount, got %+v", host.Mounts)
+	}
+}
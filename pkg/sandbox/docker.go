@@ -0,0 +1,261 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// containerLabel marks containers owned by a sandbox pool.
+const containerLabel = "nekobot.sandbox"
+
+// keepAlive keeps an otherwise idle container running until it is removed.
+var keepAlive = []string{"sh", "-c", "trap 'exit 0' TERM INT; while :; do sleep 3600 & wait $!; done"}
+
+// Docker runs sandbox containers on the local Docker daemon, optionally
+// under a non-default OCI runtime such as gVisor's runsc.
+type Docker struct {
+	name    string
+	runtime string
+
+	mu  sync.Mutex
+	cli *client.Client
+}
+
+// NewDocker creates a backend using Docker's default runtime. The daemon is
+// contacted on first use.
+func NewDocker() *Docker {
+	return &Docker{name: BackendDocker}
+}
+
+// NewDockerRuntime creates a backend named name that starts containers with
+// the given OCI runtime, which must be registered with the Docker daemon.
+func NewDockerRuntime(name, runtime string) *Docker {
+	return &Docker{name: name, runtime: runtime}
+}
+
+// Name returns the backend name.
+func (d *Docker) Name() string {
+	if d.runtime == "" {
+		return d.name
+	}
+	return d.name + " (" + d.runtime + ")"
+}
+
+func (d *Docker) client(ctx context.Context) (*client.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cli != nil {
+		return d.cli, nil
+	}
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("%w: initializing docker client: %v", ErrUnavailable, err)
+	}
+	if _, err := cli.Ping(ctx); err != nil {
+		_ = cli.Close()
+		return nil, fmt.Errorf("%w: docker daemon unavailable: %v", ErrUnavailable, err)
+	}
+	if d.runtime != "" {
+		info, err := cli.Info(ctx)
+		if err != nil {
+			_ = cli.Close()
+			return nil, fmt.Errorf("%w: reading docker runtimes: %v", ErrUnavailable, err)
+		}
+		if _, ok := info.Runtimes[d.runtime]; !ok {
+			_ = cli.Close()
+			return nil, fmt.Errorf("%w: docker runtime %q is not installed", ErrUnavailable, d.runtime)
+		}
+	}
+	d.cli = cli
+	return cli, nil
+}
+
+func (d *Docker) hostConfig(spec Spec) *container.HostConfig {
+	mounts := append([]mount.Mount{{
+		Type:   mount.TypeBind,
+		Source: spec.Workspace,
+		Target: WorkspaceMount,
+	}}, spec.Mounts...)
+	host := &container.HostConfig{
+		NetworkMode: container.NetworkMode(spec.NetworkMode),
+		Mounts:      mounts,
+		Runtime:     d.runtime,
+	}
+	if spec.Limits.CPUs > 0 {
+		host.NanoCPUs = int64(spec.Limits.CPUs * 1e9)
+	}
+	if spec.Limits.MemoryMB > 0 {
+		host.Memory = int64(spec.Limits.MemoryMB) << 20
+		// Equal to Memory, so the container cannot swap past its limit.
+		host.MemorySwap = host.Memory
+	}
+	if spec.Limits.Pids > 0 {
+		pids := spec.Limits.Pids
+		host.PidsLimit = &pids
+	}
+	return host
+}
+
+func (d *Docker) create(ctx context.Context, cli *client.Client, spec Spec, cmd []string, workdir string, labels map[string]string) (string, error) {
+	// Pull only when the image is missing; pulls can be rate-limited.
+	if _, err := cli.ImageInspect(ctx, spec.Image); err != nil {
+		if reader, err := cli.ImagePull(ctx, spec.Image, image.PullOptions{}); err == nil {
+			_, _ = io.Copy(io.Discard, reader)
+			_ = reader.Close()
+		}
+	}
+	resp, err := cli.ContainerCreate(ctx,
+		&container.Config{
+			Image:      spec.Image,
+			Cmd:        cmd,
+			WorkingDir: workdir,
+			Labels:     labels,
+		},
+		d.hostConfig(spec),
+		nil, nil, "")
+	if err != nil {
+		return "", fmt.Errorf("creating container: %w", err)
+	}
+	if err := cli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		_ = cli.ContainerRemove(context.Background(), resp.ID, container.RemoveOptions{Force: true})
+		return "", fmt.Errorf("starting container: %w", err)
+	}
+	return resp.ID, nil
+}
+
+// Create starts a long-lived container for spec.
+func (d *Docker) Create(ctx context.Context, spec Spec) (string, error) {
+	cli, err := d.client(ctx)
+	if err != nil {
+		return "", err
+	}
+	return d.create(ctx, cli, spec, keepAlive, WorkspaceMount, map[string]string{containerLabel: "1"})
+}
+
+// Run executes command in a fresh container and waits for it to exit. When
+// ctx ends first the container is killed.
+func (d *Docker) Run(ctx context.Context, spec Spec, command []string, workdir string) (ExecResult, error) {
+	cli, err := d.client(ctx)
+	if err != nil {
+		return ExecResult{}, err
+	}
+	id, err := d.create(ctx, cli, spec, command, workdir, nil)
+	if err != nil {
+		return ExecResult{}, err
+	}
+	defer func() {
+		removeCtx, cancel := context.WithTimeout(context.Background(), removeTimeout)
+		defer cancel()
+		if spec.AutoCleanup {
+			_ = cli.ContainerRemove(removeCtx, id, container.RemoveOptions{Force: true, RemoveVolumes: true})
+		} else if ctx.Err() != nil {
+			_ = cli.ContainerKill(removeCtx, id, "KILL")
+		}
+	}()
+
+	waitCh, errCh := cli.ContainerWait(ctx, id, container.WaitConditionNotRunning)
+	var waitResp container.WaitResponse
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return ExecResult{}, fmt.Errorf("waiting for container: %w", err)
+		}
+	case waitResp = <-waitCh:
+	case <-ctx.Done():
+		return ExecResult{ExitCode: -1}, ctx.Err()
+	}
+
+	var stdout, stderr bytes.Buffer
+	if logs, err := cli.ContainerLogs(ctx, id, container.LogsOptions{ShowStdout: true, ShowStderr: true}); err == nil {
+		_, _ = stdcopy.StdCopy(&stdout, &stderr, logs)
+		_ = logs.Close()
+	}
+	if waitResp.Error != nil && waitResp.Error.Message != "" {
+		stderr.WriteString(waitResp.Error.Message)
+	}
+	return ExecResult{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: int(waitResp.StatusCode)}, nil
+}
+
+// Exec runs command in a running container and waits for it to exit.
+func (d *Docker) Exec(ctx context.Context, id string, command []string, workdir string) (ExecResult, error) {
+	cli, err := d.client(ctx)
+	if err != nil {
+		return ExecResult{}, err
+	}
+	created, err := cli.ContainerExecCreate(ctx, id, container.ExecOptions{
+		Cmd:          command,
+		WorkingDir:   workdir,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return ExecResult{}, fmt.Errorf("creating exec: %w", err)
+	}
+	attached, err := cli.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return ExecResult{}, fmt.Errorf("attaching exec: %w", err)
+	}
+	defer attached.Close()
+
+	var stdout, stderr bytes.Buffer
+	copied := make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(&stdout, &stderr, attached.Reader)
+		copied <- err
+	}()
+	select {
+	case err := <-copied:
+		if err != nil {
+			return ExecResult{}, fmt.Errorf("reading exec output: %w", err)
+		}
+	case <-ctx.Done():
+		attached.Close()
+		<-copied
+		return ExecResult{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: -1}, ctx.Err()
+	}
+
+	inspect, err := cli.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return ExecResult{}, fmt.Errorf("inspecting exec: %w", err)
+	}
+	return ExecResult{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: inspect.ExitCode}, nil
+}
+
+// Remove force-removes a container.
+func (d *Docker) Remove(ctx context.Context, id string) error {
+	cli, err := d.client(ctx)
+	if err != nil {
+		return err
+	}
+	return cli.ContainerRemove(ctx, id, container.RemoveOptions{Force: true, RemoveVolumes: true})
+}
+
+// List returns every container carrying the sandbox label.
+func (d *Docker) List(ctx context.Context) ([]string, error) {
+	cli, err := d.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", containerLabel)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing sandbox containers: %w", err)
+	}
+	ids := make([]string, 0, len(summaries))
+	for _, summary := range summaries {
+		ids = append(ids, summary.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,352 @@
+// Package sandbox runs exec sandbox containers on pluggable isolation
+// backends and keeps long-lived ones: a warm pool of idle containers and one
+// persistent container per session, so state such as installed packages
+// survives across tool calls.
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/mount"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/logger"
+)
+
+const (
+	defaultMaxContainers = 10
+	defaultIdleTimeout   = 30 * time.Minute
+	// WorkspaceMount is where the workspace is mounted inside containers.
+	WorkspaceMount = "/workspace"
+	removeTimeout  = 10 * time.Second
+)
+
+// ErrUnavailable wraps errors caused by the container engine being unreachable.
+var ErrUnavailable = errors.New("sandbox engine unavailable")
+
+// Spec describes the containers a backend creates.
+type Spec struct {
+	Image       string
+	NetworkMode string
+	Workspace   string // Mounted at WorkspaceMount
+	Mounts      []mount.Mount
+	Limits      Limits
+	// AutoCleanup removes containers started by Backend.Run once they exit.
+	AutoCleanup bool
+}
+
+// ExecResult is the outcome of a command run in a container.
+type ExecResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Containers creates and drives sandbox containers. Docker implements it.
+type Containers interface {
+	Create(ctx context.Context, spec Spec) (string, error)
+	Exec(ctx context.Context, id string, command []string, workdir string) (ExecResult, error)
+	Remove(ctx context.Context, id string) error
+	// List returns the IDs of sandbox containers left behind by any process.
+	List(ctx context.Context) ([]string, error)
+}
+
+// Config sizes a Pool.
+type Config struct {
+	Spec
+	PoolSize      int           // Warm containers kept ready for new sessions
+	MaxContainers int           // Upper bound on warm and session containers together
+	IdleTimeout   time.Duration // Session containers unused this long are removed by Sweep
+}
+
+type pooledContainer struct {
+	id        string
+	workspace string
+	lastUsed  time.Time
+	active    int
+}
+
+// Pool assigns one persistent container to each session and keeps warm
+// containers ready so a new session does not wait for a container to start.
+type Pool struct {
+	log        *logger.Logger
+	containers Containers
+	cfg        Config
+
+	mu       sync.Mutex
+	idle     []*pooledContainer
+	sessions map[string]*pooledContainer
+	creating int
+	closed   bool
+}
+
+// NewPool creates a pool. No containers are started until Warm or Exec.
+func NewPool(log *logger.Logger, containers Containers, cfg Config) *Pool {
+	if cfg.MaxContainers <= 0 {
+		cfg.MaxContainers = defaultMaxContainers
+	}
+	if cfg.PoolSize < 0 {
+		cfg.PoolSize = 0
+	}
+	if cfg.PoolSize > cfg.MaxContainers {
+		cfg.PoolSize = cfg.MaxContainers
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = defaultIdleTimeout
+	}
+	return &Pool{
+		log:        log,
+		containers: containers,
+		cfg:        cfg,
+		sessions:   make(map[string]*pooledContainer),
+	}
+}
+
+// Image returns the image pooled containers run.
+func (p *Pool) Image() string {
+	return p.cfg.Image
+}
+
+// Warm removes containers left behind by a previous run and fills the pool.
+func (p *Pool) Warm(ctx context.Context) error {
+	stale, err := p.containers.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range stale {
+		if err := p.containers.Remove(ctx, id); err != nil {
+			p.log.Warn("Failed to remove stale sandbox container", zap.String("container", id), zap.Error(err))
+		}
+	}
+	p.fill(ctx)
+	return nil
+}
+
+// Exec runs command in the session's container, assigning one first if the
+// session has none. workspace is the host directory mounted at /workspace;
+// a session whose workspace changes gets a fresh container.
+func (p *Pool) Exec(ctx context.Context, sessionID, workspace string, command []string, workdir string) (ExecResult, error) {
+	c, err := p.acquire(ctx, sessionID, workspace)
+	if err != nil {
+		return ExecResult{}, err
+	}
+	result, err := p.containers.Exec(ctx, c.id, command, workdir)
+
+	p.mu.Lock()
+	c.active--
+	c.lastUsed = time.Now()
+	// A command cut off by its deadline keeps running inside the container,
+	// so the session starts over with a clean one.
+	reset := ctx.Err() != nil && p.sessions[sessionID] == c
+	if reset {
+		delete(p.sessions, sessionID)
+	}
+	p.mu.Unlock()
+
+	if reset {
+		p.remove(c.id)
+		return result, fmt.Errorf("sandbox command did not finish, the session container was reset: %w", ctx.Err())
+	}
+	return result, err
+}
+
+// Release removes the session's container, if any.
+func (p *Pool) Release(sessionID string) {
+	p.mu.Lock()
+	c, ok := p.sessions[sessionID]
+	if ok {
+		delete(p.sessions, sessionID)
+	}
+	p.mu.Unlock()
+	if ok {
+		p.remove(c.id)
+	}
+}
+
+// Sweep removes session containers idle for longer than the idle timeout and
+// refills the warm pool. It returns how many containers were removed.
+func (p *Pool) Sweep(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-p.cfg.IdleTimeout)
+	var expired []string
+
+	p.mu.Lock()
+	for sessionID, c := range p.sessions {
+		if c.active == 0 && c.lastUsed.Before(cutoff) {
+			expired = append(expired, c.id)
+			delete(p.sessions, sessionID)
+		}
+	}
+	p.mu.Unlock()
+
+	var errs []error
+	for _, id := range expired {
+		if err := p.containers.Remove(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("removing sandbox container %s: %w", id, err))
+		}
+	}
+	p.fill(ctx)
+	return len(expired), errors.Join(errs...)
+}
+
+// Close removes every pooled and session container.
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	ids := make([]string, 0, len(p.idle)+len(p.sessions))
+	for _, c := range p.idle {
+		ids = append(ids, c.id)
+	}
+	for _, c := range p.sessions {
+		ids = append(ids, c.id)
+	}
+	p.idle = nil
+	p.sessions = make(map[string]*pooledContainer)
+	p.mu.Unlock()
+
+	var errs []error
+	for _, id := range ids {
+		if err := p.containers.Remove(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("removing sandbox container %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Pool) acquire(ctx context.Context, sessionID, workspace string) (*pooledContainer, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("sandbox pool is closed")
+	}
+	var stale string
+	if c, ok := p.sessions[sessionID]; ok {
+		if c.workspace == workspace {
+			c.active++
+			c.lastUsed = time.Now()
+			p.mu.Unlock()
+			return c, nil
+		}
+		delete(p.sessions, sessionID)
+		stale = c.id
+	}
+	for i, c := range p.idle {
+		if c.workspace == workspace {
+			p.idle = append(p.idle[:i], p.idle[i+1:]...)
+			c.active = 1
+			c.lastUsed = time.Now()
+			p.sessions[sessionID] = c
+			p.mu.Unlock()
+			if stale != "" {
+				p.remove(stale)
+			}
+			go p.fill(context.Background())
+			return c, nil
+		}
+	}
+	evicted, err := p.reserveLocked()
+	p.mu.Unlock()
+	for _, id := range append(evicted, stale) {
+		if id != "" {
+			p.remove(id)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	spec := p.cfg.Spec
+	spec.Workspace = workspace
+	id, err := p.containers.Create(ctx, spec)
+
+	p.mu.Lock()
+	p.creating--
+	if err != nil {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("creating sandbox container: %w", err)
+	}
+	c := &pooledContainer{id: id, workspace: workspace, lastUsed: time.Now(), active: 1}
+	p.sessions[sessionID] = c
+	p.mu.Unlock()
+
+	p.log.Info("Sandbox container assigned", zap.String("session_id", sessionID), zap.String("container", id))
+	return c, nil
+}
+
+// reserveLocked makes room for one more container, evicting the least
+// recently used idle session containers or warm containers when the pool is
+// full. It returns the container IDs to remove.
+func (p *Pool) reserveLocked() ([]string, error) {
+	var evicted []string
+	for p.totalLocked() >= p.cfg.MaxContainers {
+		if len(p.idle) > 0 {
+			evicted = append(evicted, p.idle[0].id)
+			p.idle = p.idle[1:]
+			continue
+		}
+		var candidates []string
+		for sessionID, c := range p.sessions {
+			if c.active == 0 {
+				candidates = append(candidates, sessionID)
+			}
+		}
+		if len(candidates) == 0 {
+			return evicted, fmt.Errorf("all %d sandbox containers are busy", p.cfg.MaxContainers)
+		}
+		sort.Slice(candidates, func(i, j int) bool {
+			return p.sessions[candidates[i]].lastUsed.Before(p.sessions[candidates[j]].lastUsed)
+		})
+		evicted = append(evicted, p.sessions[candidates[0]].id)
+		delete(p.sessions, candidates[0])
+	}
+	p.creating++
+	return evicted, nil
+}
+
+func (p *Pool) totalLocked() int {
+	return len(p.idle) + len(p.sessions) + p.creating
+}
+
+// fill starts warm containers until the pool holds PoolSize of them.
+func (p *Pool) fill(ctx context.Context) {
+	for {
+		p.mu.Lock()
+		if p.closed || len(p.idle)+p.creating >= p.cfg.PoolSize || p.totalLocked() >= p.cfg.MaxContainers {
+			p.mu.Unlock()
+			return
+		}
+		p.creating++
+		p.mu.Unlock()
+
+		id, err := p.containers.Create(ctx, p.cfg.Spec)
+
+		p.mu.Lock()
+		p.creating--
+		if err == nil && !p.closed {
+			p.idle = append(p.idle, &pooledContainer{id: id, workspace: p.cfg.Workspace, lastUsed: time.Now()})
+		}
+		closed := p.closed
+		p.mu.Unlock()
+
+		if err != nil {
+			p.log.Warn("Failed to start warm sandbox container", zap.Error(err))
+			return
+		}
+		if closed {
+			p.remove(id)
+			return
+		}
+	}
+}
+
+func (p *Pool) remove(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), removeTimeout)
+	defer cancel()
+	if err := p.containers.Remove(ctx, id); err != nil {
+		p.log.Warn("Failed to remove sandbox container", zap.String("container", id), zap.Error(err))
+	}
+}
@@ -0,0 +1,131 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nekobot/pkg/logger"
+)
+
+type fakeContainers struct {
+	mu      sync.Mutex
+	next    int
+	running map[string]string // id -> workspace
+	execs   map[string]int
+	stale   []string
+}
+
+func newFakeContainers() *fakeContainers {
+	return &fakeContainers{running: map[string]string{}, execs: map[string]int{}}
+}
+
+func (f *fakeContainers) Create(_ context.Context, spec Spec) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.next++
+	id := fmt.Sprintf("c%d", f.next)
+	f.running[id] = spec.Workspace
+	return id, nil
+}
+
+func (f *fakeContainers) Exec(_ context.Context, id string, command []string, _ string) (ExecResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.running[id]; !ok {
+		return ExecResult{}, fmt.Errorf("no such container %s", id)
+	}
+	f.execs[id]++
+	return ExecResult{Stdout: id + ":" + strings.Join(command, " ")}, nil
+}
+
+func (f *fakeContainers) Remove(_ context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.running, id)
+	return nil
+}
+
+func (f *fakeContainers) List(context.Context) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.stale...), nil
+}
+
+func (f *fakeContainers) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.running)
+}
+
+func newTestPool(t *testing.T, containers Containers, cfg Config) *Pool {
+	t.Helper()
+	log, err := logger.New(&logger.Config{Level: "error"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return NewPool(log, containers, cfg)
+}
+
+func TestPoolKeepsOneContainerPerSession(t *testing.T) {
+	containers := newFakeContainers()
+	containers.stale = []string{"old"}
+	containers.running["old"] = "/ws"
+	pool := newTestPool(t, containers, Config{Spec: Spec{Workspace: "/ws"}, PoolSize: 1, MaxContainers: 4})
+	ctx := context.Background()
+
+	if err := pool.Warm(ctx); err != nil {
+		t.Fatalf("warm: %v", err)
+	}
+	if _, ok := containers.running["old"]; ok || containers.count() != 1 {
+		t.Fatalf("expected stale container removed and one warm container, got %v", containers.running)
+	}
+
+	first, err := pool.Exec(ctx, "s1", "/ws", []string{"pip", "install", "x"}, WorkspaceMount)
+	if err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	second, err := pool.Exec(ctx, "s1", "/ws", []string{"python"}, WorkspaceMount)
+	if err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	if !strings.HasPrefix(first.Stdout, "c1:") || !strings.HasPrefix(second.Stdout, "c1:") {
+		t.Fatalf("expected the session to reuse the warm container, got %q and %q", first.Stdout, second.Stdout)
+	}
+
+	other, err := pool.Exec(ctx, "s2", "/ws", []string{"true"}, WorkspaceMount)
+	if err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	if strings.HasPrefix(other.Stdout, "c1:") {
+		t.Fatalf("expected another session to get its own container, got %q", other.Stdout)
+	}
+}
+
+func TestPoolSweepRemovesIdleSessionsAndEvictsWhenFull(t *testing.T) {
+	containers := newFakeContainers()
+	pool := newTestPool(t, containers, Config{Spec: Spec{Workspace: "/ws"}, MaxContainers: 2, IdleTimeout: time.Minute})
+	ctx := context.Background()
+
+	for _, session := range []string{"s1", "s2", "s3"} {
+		if _, err := pool.Exec(ctx, session, "/ws", []string{"true"}, WorkspaceMount); err != nil {
+			t.Fatalf("exec %s: %v", session, err)
+		}
+	}
+	if containers.count() != 2 {
+		t.Fatalf("expected the least recently used session to be evicted at the limit, got %d containers", containers.count())
+	}
+
+	pool.mu.Lock()
+	for _, c := range pool.sessions {
+		c.lastUsed = time.Now().Add(-time.Hour)
+	}
+	pool.mu.Unlock()
+	removed, err := pool.Sweep(ctx)
+	if err != nil || removed != 2 || containers.count() != 0 {
+		t.Fatalf("expected idle session containers to be swept, removed %d (err %v), %d left", removed, err, containers.count())
+	}
+}
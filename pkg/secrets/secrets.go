@@ -0,0 +1,276 @@
+// Package secrets encrypts credentials at rest with AES-256-GCM under a
+// master key supplied from the environment, a file or a command such as a
+// KMS client.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Prefix marks an encrypted value. Values without it are plaintext.
+const Prefix = "enc:v1:"
+
+// KeyEnv names the environment variable holding the master key.
+const KeyEnv = "NEKOBOT_MASTER_KEY"
+
+// KeySize is the master key length in bytes.
+const KeySize = 32
+
+// keyCommandTimeout bounds a master key command.
+const keyCommandTimeout = 30 * time.Second
+
+// ErrNoKey is returned when encrypted data is found but no master key is
+// configured.
+var ErrNoKey = errors.New("encrypted secrets found but no master key is configured; set " + KeyEnv)
+
+// KeySource says where to find the master key. The environment variable wins
+// over the file, and the file over the command.
+type KeySource struct {
+	File    string
+	Command string
+}
+
+// Box encrypts and decrypts values under one master key.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New creates a box for a 32-byte key.
+func New(key []byte) (*Box, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm: %w", err)
+	}
+	return &Box{aead: aead}, nil
+}
+
+// Load creates a box from the configured key source. It returns nil without
+// an error when no key is configured, leaving secrets in plaintext.
+func Load(src KeySource) (*Box, error) {
+	raw, err := readKey(src)
+	if err != nil || raw == "" {
+		return nil, err
+	}
+	key, err := ParseKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	return New(key)
+}
+
+func readKey(src KeySource) (string, error) {
+	if value := strings.TrimSpace(os.Getenv(KeyEnv)); value != "" {
+		return value, nil
+	}
+	if path := strings.TrimSpace(src.File); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("read master key file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	if command := strings.TrimSpace(src.Command); command != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), keyCommandTimeout)
+		defer cancel()
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+		} else {
+			cmd = exec.CommandContext(ctx, "sh", "-c", command)
+		}
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("run master key command: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+	return "", nil
+}
+
+// ParseKey decodes a master key written as base64 or hex.
+func ParseKey(raw string) ([]byte, error) {
+	raw = strings.TrimSpace(raw)
+	if key, err := hex.DecodeString(raw); err == nil && len(key) == KeySize {
+		return key, nil
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(raw); err == nil && len(key) == KeySize {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("master key must be %d bytes encoded as base64 or hex", KeySize)
+}
+
+// GenerateKey returns a new random master key encoded as base64.
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generate master key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// IsEncrypted reports whether value carries the encryption prefix.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Encrypt seals value. Empty and already encrypted values are returned as
+// is, and so is everything when b is nil.
+func (b *Box) Encrypt(value string) (string, error) {
+	if b == nil || value == "" || IsEncrypted(value) {
+		return value, nil
+	}
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(value), nil)
+	return Prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens an encrypted value. Plaintext values are returned as is. A
+// nil box fails with ErrNoKey on encrypted values.
+func (b *Box) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if b == nil {
+		return "", ErrNoKey
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, Prefix))
+	if err != nil {
+		return "", fmt.Errorf("decode encrypted secret: %w", err)
+	}
+	size := b.aead.NonceSize()
+	if len(sealed) < size {
+		return "", fmt.Errorf("encrypted secret is truncated")
+	}
+	plain, err := b.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt secret: wrong master key or corrupted value")
+	}
+	return string(plain), nil
+}
+
+// IsSecretField reports whether a JSON field holds a credential: API keys,
+// tokens, secrets, passwords, and proxy URLs and database DSNs, which may
+// embed credentials.
+func IsSecretField(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, suffix := range []string{"key", "token", "secret", "password", "proxy", "dsn"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSensitiveField reports whether a JSON field must not be shown to readers
+// without access to credentials: secret fields, and URLs, which may carry
+// tokens in their path or query.
+func IsSensitiveField(name string) bool {
+	return IsSecretField(name) || strings.HasSuffix(strings.ToLower(strings.TrimSpace(name)), "url")
+}
+
+// Redacted replaces sensitive values in redacted documents.
+const Redacted = "[redacted]"
+
+// RedactJSON replaces the non-empty string values of sensitive fields
+// anywhere in a JSON document with Redacted.
+func RedactJSON(payload []byte) ([]byte, error) {
+	return transformJSON(payload, func(field, value string) (string, error) {
+		if value == "" || !IsSensitiveField(field) {
+			return value, nil
+		}
+		return Redacted, nil
+	})
+}
+
+// EncryptJSON encrypts the string values of secret fields anywhere in a JSON
+// document. A nil box returns payload unchanged.
+func (b *Box) EncryptJSON(payload []byte) ([]byte, error) {
+	if b == nil {
+		return payload, nil
+	}
+	return transformJSON(payload, func(field, value string) (string, error) {
+		if !IsSecretField(field) {
+			return value, nil
+		}
+		return b.Encrypt(value)
+	})
+}
+
+// DecryptJSON decrypts every encrypted string in a JSON document.
+func (b *Box) DecryptJSON(payload []byte) ([]byte, error) {
+	if !bytes.Contains(payload, []byte(Prefix)) {
+		return payload, nil
+	}
+	return transformJSON(payload, func(_, value string) (string, error) {
+		return b.Decrypt(value)
+	})
+}
+
+func transformJSON(payload []byte, fn func(field, value string) (string, error)) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode json: %w", err)
+	}
+	doc, err := transformValue("", doc, fn)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+func transformValue(field string, value any, fn func(field, value string) (string, error)) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			next, err := transformValue(key, child, fn)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			v[key] = next
+		}
+		return v, nil
+	case []any:
+		for i, child := range v {
+			next, err := transformValue(field, child, fn)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = next
+		}
+		return v, nil
+	case string:
+		return fn(field, v)
+	default:
+		return value, nil
+	}
+}
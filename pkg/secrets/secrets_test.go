@@ -0,0 +1,141 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBoxEncryptDecrypt(t *testing.T) {
+	box := newTestBox(t)
+
+	sealed, err := box.Encrypt("sk-live")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !IsEncrypted(sealed) || strings.Contains(sealed, "sk-live") {
+		t.Fatalf("expected an encrypted value, got %q", sealed)
+	}
+	again, err := box.Encrypt(sealed)
+	if err != nil || again != sealed {
+		t.Fatalf("expected encrypting twice to be a no-op, got %q, %v", again, err)
+	}
+	plain, err := box.Decrypt(sealed)
+	if err != nil || plain != "sk-live" {
+		t.Fatalf("expected round trip, got %q, %v", plain, err)
+	}
+	if plain, err := box.Decrypt("legacy"); err != nil || plain != "legacy" {
+		t.Fatalf("expected plaintext to pass through, got %q, %v", plain, err)
+	}
+
+	var missing *Box
+	if _, err := missing.Decrypt(sealed); !errors.Is(err, ErrNoKey) {
+		t.Fatalf("expected ErrNoKey without a key, got %v", err)
+	}
+	if _, err := newTestBox(t).Decrypt(sealed); err == nil {
+		t.Fatal("expected a different key to fail")
+	}
+}
+
+func TestBoxJSONEncryptsOnlySecretFields(t *testing.T) {
+	box := newTestBox(t)
+	payload := []byte(`{"telegram":{"token":"123:abc","enabled":true,"allow_from":["alice"]},"max_tokens":4096,"proxy":"http://u:p@proxy","name":"bot","big":9007199254740993}`)
+
+	sealed, err := box.EncryptJSON(payload)
+	if err != nil {
+		t.Fatalf("EncryptJSON failed: %v", err)
+	}
+	for _, secret := range []string{"123:abc", "u:p@proxy"} {
+		if strings.Contains(string(sealed), secret) {
+			t.Fatalf("expected %q to be encrypted: %s", secret, sealed)
+		}
+	}
+	for _, kept := range []string{`"name":"bot"`, `"allow_from":["alice"]`, `"max_tokens":4096`, `"big":9007199254740993`} {
+		if !strings.Contains(string(sealed), kept) {
+			t.Fatalf("expected %s to stay in plaintext: %s", kept, sealed)
+		}
+	}
+
+	opened, err := box.DecryptJSON(sealed)
+	if err != nil {
+		t.Fatalf("DecryptJSON failed: %v", err)
+	}
+	var got, want map[string]any
+	if err := json.Unmarshal(opened, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(payload, &want); err != nil {
+		t.Fatal(err)
+	}
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Fatalf("round trip mismatch:\n got: %s\nwant: %s", gotJSON, wantJSON)
+	}
+}
+
+func TestRedactJSONHidesSensitiveFields(t *testing.T) {
+	payload := []byte(`{"storage":{"db_dsn":"postgres://u:p@db/neko","db_type":"postgres"},"tools":{"databases":[{"name":"main","dsn":"mysql://u:p@db"}]},"webhook":{"hooks":[{"secret":"s3cr3t","empty_token":""}]},"alerts":{"webhook_url":"https://hooks.example/T0/B0/x"}}`)
+	redacted, err := RedactJSON(payload)
+	if err != nil {
+		t.Fatalf("RedactJSON failed: %v", err)
+	}
+	for _, secret := range []string{"u:p@db", "s3cr3t", "hooks.example"} {
+		if strings.Contains(string(redacted), secret) {
+			t.Fatalf("expected %q to be redacted: %s", secret, redacted)
+		}
+	}
+	for _, kept := range []string{`"db_type":"postgres"`, `"name":"main"`, `"empty_token":""`} {
+		if !strings.Contains(string(redacted), kept) {
+			t.Fatalf("expected %s to be kept: %s", kept, redacted)
+		}
+	}
+}
+
+func TestLoadKeySources(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(KeyEnv, "")
+	if box, err := Load(KeySource{}); err != nil || box != nil {
+		t.Fatalf("expected no box without a key, got %v, %v", box, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "master.key")
+	if err := os.WriteFile(path, []byte(key+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if box, err := Load(KeySource{File: path}); err != nil || box == nil {
+		t.Fatalf("expected box from key file, got %v, %v", box, err)
+	}
+	if box, err := Load(KeySource{Command: "echo " + key}); err != nil || box == nil {
+		t.Fatalf("expected box from key command, got %v, %v", box, err)
+	}
+
+	t.Setenv(KeyEnv, "too-short")
+	if _, err := Load(KeySource{File: path}); err == nil {
+		t.Fatal("expected the environment key to win and be rejected")
+	}
+}
+
+func newTestBox(t *testing.T) *Box {
+	t.Helper()
+	raw, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ParseKey(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	box, err := New(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return box
+}
@@ -0,0 +1,165 @@
+package servicecontrol
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kardianos/service"
+)
+
+// DefaultRestartSec is how long the service manager waits before restarting
+// a crashed daemon.
+const DefaultRestartSec = 5
+
+// DaemonOptions controls the systemd unit or launchd plist generated for the
+// gateway+WebUI daemon.
+type DaemonOptions struct {
+	// LogDir receives <name>.log and <name>.err.log. Empty uses
+	// DefaultLogDir.
+	LogDir string
+	// UserService installs a per-user unit (systemctl --user or a
+	// LaunchAgent) instead of a system-wide one.
+	UserService bool
+	// RestartSec is the delay before a crashed daemon is restarted.
+	RestartSec int
+}
+
+// DefaultLogDir returns the log directory used when none is configured:
+// /var/log/nekobot for system services and ~/.nekobot/logs for user services.
+func DefaultLogDir(userService bool) string {
+	if !userService {
+		return "/var/log/nekobot"
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "nekobot", "logs")
+	}
+	return filepath.Join(home, ".nekobot", "logs")
+}
+
+func (o DaemonOptions) normalized() DaemonOptions {
+	if strings.TrimSpace(o.LogDir) == "" {
+		o.LogDir = DefaultLogDir(o.UserService)
+	}
+	if abs, err := filepath.Abs(o.LogDir); err == nil {
+		o.LogDir = abs
+	}
+	if o.RestartSec <= 0 {
+		o.RestartSec = DefaultRestartSec
+	}
+	return o
+}
+
+// LogPaths returns the stdout and stderr log files of the daemon.
+func (o DaemonOptions) LogPaths() (string, string) {
+	o = o.normalized()
+	name := GatewayServiceSpec.Name
+	return filepath.Join(o.LogDir, name+".log"), filepath.Join(o.LogDir, name+".err.log")
+}
+
+// GatewayDaemonSpec returns the gateway service spec with a unit or plist
+// that appends output to log files and restarts the daemon when it exits.
+func GatewayDaemonSpec(opts DaemonOptions) ServiceSpec {
+	opts = opts.normalized()
+	outPath, errPath := opts.LogPaths()
+	spec := GatewayServiceSpec
+	spec.Options = service.KeyValue{
+		"UserService":   opts.UserService,
+		"SystemdScript": systemdUnit(opts, outPath, errPath),
+		"LaunchdConfig": launchdPlist(opts, outPath, errPath),
+		"KeepAlive":     true,
+		"RunAtLoad":     true,
+	}
+	return spec
+}
+
+// InstallGatewayDaemon creates the log directory and installs the service.
+func InstallGatewayDaemon(configPath string, opts DaemonOptions) error {
+	opts = opts.normalized()
+	if err := os.MkdirAll(opts.LogDir, 0o755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+	return ControlService(configPath, GatewayDaemonSpec(opts), "install")
+}
+
+// ControlService runs one of service.ControlAction against spec.
+func ControlService(configPath string, spec ServiceSpec, action string) error {
+	svc, err := buildService(configPath, spec)
+	if err != nil {
+		return err
+	}
+	if err := service.Control(svc, action); err != nil {
+		return fmt.Errorf("%s service: %w", action, err)
+	}
+	return nil
+}
+
+// systemdUnit restarts the daemon on failure after opts.RestartSec and gives
+// up after five crashes within a minute. User units are wanted by
+// default.target, since multi-user.target never starts for user managers.
+func systemdUnit(opts DaemonOptions, outPath, errPath string) string {
+	wantedBy := "multi-user.target"
+	if opts.UserService {
+		wantedBy = "default.target"
+	}
+	return fmt.Sprintf(`[Unit]
+Description={{.Description}}
+ConditionFileIsExecutable={{.Path|cmdEscape}}
+After=network-online.target
+Wants=network-online.target
+StartLimitIntervalSec=60
+StartLimitBurst=5
+
+[Service]
+ExecStart={{.Path|cmdEscape}}{{range .Arguments}} {{.|cmd}}{{end}}
+{{if .WorkingDirectory}}WorkingDirectory={{.WorkingDirectory|cmdEscape}}{{end}}
+{{if .UserName}}User={{.UserName}}{{end}}
+StandardOutput=append:%s
+StandardError=append:%s
+Restart=on-failure
+RestartSec=%d
+EnvironmentFile=-/etc/sysconfig/{{.Name}}
+{{range $k, $v := .EnvVars -}}
+Environment={{$k}}={{$v}}
+{{end}}
+[Install]
+WantedBy=%s
+`, outPath, errPath, opts.RestartSec, wantedBy)
+}
+
+// launchdPlist keeps the daemon alive and loads it at boot or login.
+func launchdPlist(opts DaemonOptions, outPath, errPath string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{html .Name}}</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>{{html .Path}}</string>
+		{{- range .Config.Arguments}}
+		<string>{{html .}}</string>
+		{{- end}}
+	</array>
+	{{- if .WorkingDirectory}}
+	<key>WorkingDirectory</key>
+	<string>{{html .WorkingDirectory}}</string>
+	{{- end}}
+	<key>KeepAlive</key>
+	<{{bool .KeepAlive}}/>
+	<key>RunAtLoad</key>
+	<{{bool .RunAtLoad}}/>
+	<key>ThrottleInterval</key>
+	<integer>%d</integer>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, opts.RestartSec, html.EscapeString(outPath), html.EscapeString(errPath))
+}
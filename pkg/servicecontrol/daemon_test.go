@@ -0,0 +1,106 @@
+package servicecontrol
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/kardianos/service"
+)
+
+func renderServiceTemplate(t *testing.T, text string) string {
+	t.Helper()
+	identity := func(s string) string { return s }
+	tmpl, err := template.New("").Funcs(template.FuncMap{
+		"cmd":       identity,
+		"cmdEscape": identity,
+		"html":      identity,
+		"bool": func(v bool) string {
+			if v {
+				return "true"
+			}
+			return "false"
+		},
+	}).Parse(text)
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	var out strings.Builder
+	data := struct {
+		*service.Config
+		Path                 string
+		KeepAlive, RunAtLoad bool
+	}{
+		Config:    &service.Config{Name: "nekobot-gateway", Description: "Nekobot", Arguments: []string{"gateway", "run"}},
+		Path:      "/usr/local/bin/nekobot",
+		KeepAlive: true,
+		RunAtLoad: true,
+	}
+	if err := tmpl.Execute(&out, data); err != nil {
+		t.Fatalf("execute template: %v", err)
+	}
+	return out.String()
+}
+
+func TestGatewayDaemonSpecWritesLogsAndRestarts(t *testing.T) {
+	logDir := t.TempDir()
+	spec := GatewayDaemonSpec(DaemonOptions{LogDir: logDir, UserService: true})
+	if spec.Name != GatewayServiceSpec.Name || spec.Options["UserService"] != true {
+		t.Fatalf("unexpected spec: %+v", spec)
+	}
+
+	unit := renderServiceTemplate(t, spec.Options["SystemdScript"].(string))
+	for _, want := range []string{
+		"ExecStart=/usr/local/bin/nekobot gateway run",
+		"StandardOutput=append:" + filepath.Join(logDir, "nekobot-gateway.log"),
+		"StandardError=append:" + filepath.Join(logDir, "nekobot-gateway.err.log"),
+		"Restart=on-failure",
+		"RestartSec=5",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Fatalf("expected %q in systemd unit:\n%s", want, unit)
+		}
+	}
+
+	plist := renderServiceTemplate(t, spec.Options["LaunchdConfig"].(string))
+	for _, want := range []string{
+		"<string>gateway</string>",
+		"<key>KeepAlive</key>\n\t<true/>",
+		"<string>" + filepath.Join(logDir, "nekobot-gateway.err.log") + "</string>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Fatalf("expected %q in launchd plist:\n%s", want, plist)
+		}
+	}
+
+	system := GatewayDaemonSpec(DaemonOptions{RestartSec: 30})
+	unit = renderServiceTemplate(t, system.Options["SystemdScript"].(string))
+	if !strings.Contains(unit, "RestartSec=30") || !strings.Contains(unit, "WantedBy=multi-user.target") ||
+		!strings.Contains(unit, "append:/var/log/nekobot/nekobot-gateway.log") {
+		t.Fatalf("unexpected system unit:\n%s", unit)
+	}
+}
+
+func TestInstallGatewayDaemonCreatesLogDir(t *testing.T) {
+	original := newService
+	t.Cleanup(func() { newService = original })
+	var got *service.Config
+	newService = func(_ service.Interface, cfg *service.Config) (service.Service, error) {
+		got = cfg
+		return &stubService{}, nil
+	}
+
+	logDir := filepath.Join(t.TempDir(), "logs")
+	if err := InstallGatewayDaemon("", DaemonOptions{LogDir: logDir, UserService: true}); err != nil {
+		t.Fatalf("InstallGatewayDaemon failed: %v", err)
+	}
+	if info, err := os.Stat(logDir); err != nil || !info.IsDir() {
+		t.Fatalf("expected the log directory to be created: %v", err)
+	}
+	if got == nil || got.Option["SystemdScript"] == nil || got.Option["UserService"] != true {
+		t.Fatalf("expected the daemon options on the service config, got %+v", got)
+	}
+}
@@ -3,6 +3,7 @@ package servicecontrol
 import (
 	"cmp"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
@@ -28,6 +29,9 @@ type ServiceSpec struct {
 	Description string
 	RunArgs     []string
 	ExtraArgs   []string
+	// Options are passed to the service manager as platform options, e.g.
+	// a custom systemd unit or launchd plist template.
+	Options service.KeyValue
 }
 
 var (
@@ -68,6 +72,7 @@ func ServiceConfig(configPath string, spec ServiceSpec) *service.Config {
 		DisplayName: spec.DisplayName,
 		Description: spec.Description,
 		Arguments:   args,
+		Option:      maps.Clone(spec.Options),
 	}
 }
 
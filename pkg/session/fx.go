@@ -9,6 +9,7 @@ import (
 
 	"nekobot/pkg/config"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/memory/qmd"
 )
 
 // Module provides session management for fx.
@@ -17,8 +18,52 @@ var Module = fx.Module("session",
 		return NewManager(cfg.WorkspacePath()+"/sessions", cfg.Sessions)
 	}),
 	fx.Invoke(registerCleanupLifecycle),
+	fx.Invoke(registerQMDExportHook),
 )
 
+// registerQMDExportHook exports closed sessions to QMD when
+// memory.qmd.sessions is enabled.
+func registerQMDExportHook(cfg *config.Config, log *logger.Logger, manager *Manager) {
+	if !cfg.Memory.QMD.Enabled || !cfg.Memory.QMD.Sessions.Enabled {
+		return
+	}
+
+	qmdCfg := qmd.ConfigFromConfigWithWorkspace(cfg.Memory.QMD, cfg.WorkspacePath())
+	qmdMgr := qmd.NewManager(log, qmdCfg)
+	timeout := 5 * time.Minute
+	if d, err := time.ParseDuration(qmdCfg.Update.UpdateTimeout); err == nil && d > 0 {
+		timeout = d
+	}
+
+	manager.OnClose(func(closed *SessionJSONL) {
+		messages := qmdSessionMessages(closed)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			if err := qmdMgr.ArchiveSession(ctx, closed.Key, messages); err != nil {
+				log.Warn("Failed to export closed session to QMD",
+					zap.String("session", closed.Key),
+					zap.Error(err))
+			}
+		}()
+	})
+}
+
+func qmdSessionMessages(closed *SessionJSONL) []qmd.SessionMessage {
+	messages := make([]qmd.SessionMessage, 0, len(closed.Messages))
+	for _, msg := range closed.Messages {
+		if stringsTrimmed(msg.Content) == "" {
+			continue
+		}
+		messages = append(messages, qmd.SessionMessage{
+			Role:      msg.Role,
+			Content:   msg.Content,
+			Timestamp: closed.CreatedAt,
+		})
+	}
+	return messages
+}
+
 func registerCleanupLifecycle(
 	lc fx.Lifecycle,
 	cfg *config.Config,
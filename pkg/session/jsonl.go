@@ -2,6 +2,7 @@
 package session
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -70,6 +71,7 @@ func (m *Manager) SaveJSONL(key string, messages []Message, metadata map[string]
 	if err := os.Rename(tmpPath, path); err != nil {
 		return fmt.Errorf("renaming temp file: %w", err)
 	}
+	m.recordStamp(key)
 
 	return nil
 }
@@ -105,6 +107,11 @@ func (m *Manager) LoadJSONL(key string) (*SessionJSONL, error) {
 
 		// Check if metadata line
 		if msgType, ok := raw["_type"].(string); ok && msgType == "metadata" {
+			// Storage keys replace characters such as ':', so prefer the
+			// original key when a listed file is loaded by its storage key.
+			if original, ok := raw["key"].(string); ok && original != "" && StorageKey(original) == StorageKey(key) {
+				session.Key = original
+			}
 			if createdAt, ok := raw["created_at"].(string); ok {
 				session.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 			}
@@ -129,15 +136,18 @@ func (m *Manager) LoadJSONL(key string) (*SessionJSONL, error) {
 
 // getJSONLPath returns the path for a JSONL session file.
 func (m *Manager) getJSONLPath(key string) string {
-	// Sanitize key
-	safeKey := strings.Map(func(r rune) rune {
+	return m.baseDir + "/" + StorageKey(key) + ".jsonl"
+}
+
+// StorageKey returns the file-safe form of a session key. List reports
+// sessions by this form.
+func StorageKey(key string) string {
+	return strings.Map(func(r rune) rune {
 		if r == '/' || r == '\\' || r == ':' || r == '*' || r == '?' || r == '"' || r == '<' || r == '>' || r == '|' {
 			return '_'
 		}
 		return r
 	}, key)
-
-	return m.baseDir + "/" + safeKey + ".jsonl"
 }
 
 // ListJSONL lists all JSONL session files.
@@ -158,8 +168,48 @@ func (m *Manager) ListJSONL() ([]string, error) {
 	return keys, nil
 }
 
-// DeleteJSONL deletes a JSONL session file.
+// ListKeys lists persisted sessions by their original keys, such as
+// "telegram:123", where ListJSONL reports the file-safe form.
+func (m *Manager) ListKeys() ([]string, error) {
+	storageKeys, err := m.ListJSONL()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(storageKeys))
+	for _, storageKey := range storageKeys {
+		keys = append(keys, m.originalKey(storageKey))
+	}
+	return keys, nil
+}
+
+// originalKey reads the key from a session file's metadata line, falling back
+// to the storage key.
+func (m *Manager) originalKey(storageKey string) string {
+	file, err := os.Open(m.getJSONLPath(storageKey))
+	if err != nil {
+		return storageKey
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	line, err := bufio.NewReader(file).ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return storageKey
+	}
+	var meta struct {
+		Type string `json:"_type"`
+		Key  string `json:"key"`
+	}
+	if json.Unmarshal(line, &meta) != nil || meta.Type != "metadata" || StorageKey(meta.Key) != storageKey {
+		return storageKey
+	}
+	return meta.Key
+}
+
+// DeleteJSONL deletes a JSONL session file after notifying close hooks.
 func (m *Manager) DeleteJSONL(key string) error {
+	m.notifyClose(key)
 	path := m.getJSONLPath(key)
 	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
 		return err
@@ -213,6 +263,7 @@ func (m *Manager) AppendMessageJSONL(key string, msg Message, metadata map[strin
 	if err := encoder.Encode(msg); err != nil {
 		return fmt.Errorf("encoding message: %w", err)
 	}
+	m.recordStamp(key)
 
 	return nil
 }
@@ -28,9 +28,26 @@ type Session struct {
 	UpdatedAt time.Time `json:"updated_at"`
 	Messages  []Message `json:"messages"`
 	Summary   string    `json:"summary,omitempty"`
-	Source    string    `json:"source,omitempty"`
-	mu        sync.RWMutex
-	manager   *Manager
+	Title     string    `json:"title,omitempty"`
+	Pins      []string  `json:"pins,omitempty"`
+	// Orchestrator overrides agents.defaults.orchestrator for this session.
+	Orchestrator string `json:"orchestrator,omitempty"`
+	// DeveloperMode attaches the assembled LLM requests to each turn result.
+	DeveloperMode bool `json:"developer_mode,omitempty"`
+	// Persona is a custom system prompt placed ahead of the default prompt.
+	Persona string `json:"persona,omitempty"`
+	// AgentProfile selects a named agent profile for this session.
+	AgentProfile string `json:"agent_profile,omitempty"`
+	// Provider, Model and Fallback override the provider, model and
+	// fallback chain of later turns.
+	Provider string   `json:"provider,omitempty"`
+	Model    string   `json:"model,omitempty"`
+	Fallback []string `json:"fallback,omitempty"`
+	// Archived hides the session from default listings without deleting it.
+	Archived bool   `json:"archived,omitempty"`
+	Source   string `json:"source,omitempty"`
+	mu       sync.RWMutex
+	manager  *Manager
 }
 
 const (
@@ -49,8 +66,18 @@ type Manager struct {
 	config   config.SessionsConfig
 	sessions map[string]*Session
 	mu       sync.RWMutex
+
+	closeHooks []CloseHook
+
+	stampMu sync.Mutex
+	shared  bool
+	stamps  map[string]fileStamp
 }
 
+// CloseHook receives the final transcript of a session right before its
+// persisted history is removed.
+type CloseHook func(closed *SessionJSONL)
+
 // NewManager creates a new session manager.
 func NewManager(baseDir string, cfg config.SessionsConfig) *Manager {
 	_ = os.MkdirAll(baseDir, 0755)
@@ -72,7 +99,7 @@ func (m *Manager) GetWithSource(sessionID, source string) (*Session, error) {
 	defer m.mu.Unlock()
 
 	// Check if already in memory
-	if session, exists := m.sessions[sessionID]; exists {
+	if session, exists := m.sessions[sessionID]; exists && !m.changedOnDisk(sessionID) {
 		if stringsTrimmed(source) != "" && stringsTrimmed(session.Source) == "" {
 			session.Source = stringsTrimmed(source)
 		}
@@ -109,7 +136,7 @@ func (m *Manager) GetExisting(sessionID string) (*Session, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if session, exists := m.sessions[sessionID]; exists {
+	if session, exists := m.sessions[sessionID]; exists && !m.changedOnDisk(sessionID) {
 		return session, nil
 	}
 
@@ -143,8 +170,18 @@ func (m *Manager) Save(session *Session) error {
 	filteredMessages := m.filterMessages(snapshot.Messages, snapshot.Source)
 
 	if err := m.SaveJSONL(snapshot.ID, filteredMessages, map[string]interface{}{
-		"summary": snapshot.Summary,
-		"source":  snapshot.Source,
+		"summary":        snapshot.Summary,
+		"title":          snapshot.Title,
+		"pins":           snapshot.Pins,
+		"orchestrator":   snapshot.Orchestrator,
+		"developer_mode": snapshot.DeveloperMode,
+		"persona":        snapshot.Persona,
+		"agent_profile":  snapshot.AgentProfile,
+		"provider":       snapshot.Provider,
+		"model":          snapshot.Model,
+		"fallback":       snapshot.Fallback,
+		"archived":       snapshot.Archived,
+		"source":         snapshot.Source,
 	}); err != nil {
 		return fmt.Errorf("writing session jsonl: %w", err)
 	}
@@ -154,6 +191,8 @@ func (m *Manager) Save(session *Session) error {
 
 // load loads a session from disk.
 func (m *Manager) load(sessionID string) (*Session, error) {
+	// Stamp first: a write racing the read only causes another reload.
+	m.recordStamp(sessionID)
 	jsonlSession, err := m.LoadJSONL(sessionID)
 	if err != nil {
 		return nil, err
@@ -169,6 +208,32 @@ func (m *Manager) load(sessionID string) (*Session, error) {
 	if summary, ok := jsonlSession.Metadata["summary"].(string); ok {
 		session.Summary = summary
 	}
+	if title, ok := jsonlSession.Metadata["title"].(string); ok {
+		session.Title = title
+	}
+	session.Pins = pinsFromMetadata(jsonlSession.Metadata["pins"])
+	if orchestrator, ok := jsonlSession.Metadata["orchestrator"].(string); ok {
+		session.Orchestrator = orchestrator
+	}
+	if developerMode, ok := jsonlSession.Metadata["developer_mode"].(bool); ok {
+		session.DeveloperMode = developerMode
+	}
+	if persona, ok := jsonlSession.Metadata["persona"].(string); ok {
+		session.Persona = persona
+	}
+	if agentProfile, ok := jsonlSession.Metadata["agent_profile"].(string); ok {
+		session.AgentProfile = agentProfile
+	}
+	if provider, ok := jsonlSession.Metadata["provider"].(string); ok {
+		session.Provider = provider
+	}
+	if model, ok := jsonlSession.Metadata["model"].(string); ok {
+		session.Model = model
+	}
+	session.Fallback = pinsFromMetadata(jsonlSession.Metadata["fallback"])
+	if archived, ok := jsonlSession.Metadata["archived"].(bool); ok {
+		session.Archived = archived
+	}
 	if source, ok := jsonlSession.Metadata["source"].(string); ok {
 		session.Source = source
 	}
@@ -189,6 +254,34 @@ func (m *Manager) Delete(sessionID string) error {
 	return m.DeleteJSONL(sessionID)
 }
 
+// OnClose registers a hook that runs whenever a session is deleted, either
+// explicitly or by the pruner.
+func (m *Manager) OnClose(hook CloseHook) {
+	if hook == nil {
+		return
+	}
+	m.mu.Lock()
+	m.closeHooks = append(m.closeHooks, hook)
+	m.mu.Unlock()
+}
+
+func (m *Manager) notifyClose(key string) {
+	m.mu.RLock()
+	hooks := append([]CloseHook(nil), m.closeHooks...)
+	m.mu.RUnlock()
+	if len(hooks) == 0 {
+		return
+	}
+
+	closed, err := m.LoadJSONL(key)
+	if err != nil || len(closed.Messages) == 0 {
+		return
+	}
+	for _, hook := range hooks {
+		hook(closed)
+	}
+}
+
 // AddMessage adds a message to the session.
 func (s *Session) AddMessage(message Message) {
 	s.mu.Lock()
@@ -303,6 +396,232 @@ func (s *Session) GetSummary() string {
 	return s.Summary
 }
 
+// SetTitle sets the human-readable title shown in session lists.
+func (s *Session) SetTitle(title string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Title = strings.TrimSpace(title)
+	s.UpdatedAt = time.Now()
+	if s.manager != nil {
+		_ = s.manager.saveSnapshot(s.snapshotLocked())
+	}
+}
+
+// GetTitle returns the session title.
+func (s *Session) GetTitle() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.Title
+}
+
+// AddPin pins text so it stays in the prompt regardless of history trimming.
+// It returns false when the text is empty or already pinned.
+func (s *Session) AddPin(text string) bool {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, pin := range s.Pins {
+		if pin == text {
+			return false
+		}
+	}
+	s.Pins = append(s.Pins, text)
+	s.UpdatedAt = time.Now()
+	if s.manager != nil {
+		_ = s.manager.saveSnapshot(s.snapshotLocked())
+	}
+	return true
+}
+
+// RemovePin removes the pin at the given 1-based index and returns its text.
+func (s *Session) RemovePin(index int) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if index < 1 || index > len(s.Pins) {
+		return "", false
+	}
+	removed := s.Pins[index-1]
+	next := make([]string, 0, len(s.Pins)-1)
+	next = append(next, s.Pins[:index-1]...)
+	next = append(next, s.Pins[index:]...)
+	s.Pins = next
+	s.UpdatedAt = time.Now()
+	if s.manager != nil {
+		_ = s.manager.saveSnapshot(s.snapshotLocked())
+	}
+	return removed, true
+}
+
+// ClearPins removes every pinned item.
+func (s *Session) ClearPins() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Pins = nil
+	s.UpdatedAt = time.Now()
+	if s.manager != nil {
+		_ = s.manager.saveSnapshot(s.snapshotLocked())
+	}
+}
+
+// GetPins returns a copy of the pinned items in pin order.
+func (s *Session) GetPins() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pins := make([]string, len(s.Pins))
+	copy(pins, s.Pins)
+	return pins
+}
+
+// SetOrchestrator sets the orchestrator override for later turns.
+// An empty name restores the configured default.
+func (s *Session) SetOrchestrator(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Orchestrator = strings.TrimSpace(name)
+	s.UpdatedAt = time.Now()
+	if s.manager != nil {
+		_ = s.manager.saveSnapshot(s.snapshotLocked())
+	}
+}
+
+// GetOrchestrator returns the session's orchestrator override, if any.
+func (s *Session) GetOrchestrator() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.Orchestrator
+}
+
+// SetModelOverride sets the provider, model and fallback chain of later
+// turns. Empty values restore the configured defaults.
+func (s *Session) SetModelOverride(provider, model string, fallback []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Provider = strings.TrimSpace(provider)
+	s.Model = strings.TrimSpace(model)
+	s.Fallback = nil
+	for _, name := range fallback {
+		if name = strings.TrimSpace(name); name != "" {
+			s.Fallback = append(s.Fallback, name)
+		}
+	}
+	s.UpdatedAt = time.Now()
+	if s.manager != nil {
+		_ = s.manager.saveSnapshot(s.snapshotLocked())
+	}
+}
+
+// GetModelOverride returns the session's provider, model and fallback
+// overrides, if any.
+func (s *Session) GetModelOverride() (string, string, []string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.Provider, s.Model, append([]string(nil), s.Fallback...)
+}
+
+// SetDeveloperMode toggles developer mode for later turns.
+func (s *Session) SetDeveloperMode(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.DeveloperMode = enabled
+	s.UpdatedAt = time.Now()
+	if s.manager != nil {
+		_ = s.manager.saveSnapshot(s.snapshotLocked())
+	}
+}
+
+// GetDeveloperMode reports whether developer mode is on for the session.
+func (s *Session) GetDeveloperMode() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.DeveloperMode
+}
+
+// SetPersona sets the custom system prompt for later turns.
+// An empty persona restores the default prompt.
+func (s *Session) SetPersona(persona string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Persona = strings.TrimSpace(persona)
+	s.UpdatedAt = time.Now()
+	if s.manager != nil {
+		_ = s.manager.saveSnapshot(s.snapshotLocked())
+	}
+}
+
+// GetPersona returns the session's custom system prompt, if any.
+func (s *Session) GetPersona() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.Persona
+}
+
+// SetAgentProfile selects the agent profile for later turns.
+// An empty name falls back to the channel's profile or the defaults.
+func (s *Session) SetAgentProfile(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.AgentProfile = strings.TrimSpace(name)
+	s.UpdatedAt = time.Now()
+	if s.manager != nil {
+		_ = s.manager.saveSnapshot(s.snapshotLocked())
+	}
+}
+
+// GetAgentProfile returns the session's agent profile override, if any.
+func (s *Session) GetAgentProfile() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.AgentProfile
+}
+
+// SetArchived archives or restores the session. Archived sessions keep their
+// history but are left out of default listings.
+func (s *Session) SetArchived(archived bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Archived = archived
+	if s.manager != nil {
+		_ = s.manager.saveSnapshot(s.snapshotLocked())
+	}
+}
+
+// IsArchived reports whether the session is archived.
+func (s *Session) IsArchived() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.Archived
+}
+
+// GetSource returns where the session was started, such as "channels".
+func (s *Session) GetSource() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.Source
+}
+
 // GetID returns the session ID.
 func (s *Session) GetID() string {
 	s.mu.RLock()
@@ -328,42 +647,82 @@ func (s *Session) GetUpdatedAt() time.Time {
 }
 
 type sessionSnapshot struct {
-	ID        string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Messages  []Message
-	Summary   string
-	Source    string
+	ID            string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Messages      []Message
+	Summary       string
+	Title         string
+	Pins          []string
+	Orchestrator  string
+	DeveloperMode bool
+	Persona       string
+	AgentProfile  string
+	Provider      string
+	Model         string
+	Fallback      []string
+	Archived      bool
+	Source        string
 }
 
 type sessionAppendSnapshot struct {
-	ID           string
-	CreatedAt    time.Time
-	Summary      string
-	Source       string
-	MessageCount int
+	ID            string
+	CreatedAt     time.Time
+	Summary       string
+	Title         string
+	Pins          []string
+	Orchestrator  string
+	DeveloperMode bool
+	Persona       string
+	AgentProfile  string
+	Provider      string
+	Model         string
+	Fallback      []string
+	Archived      bool
+	Source        string
+	MessageCount  int
 }
 
 func (s *Session) snapshotLocked() sessionSnapshot {
 	messages := make([]Message, len(s.Messages))
 	copy(messages, s.Messages)
 	return sessionSnapshot{
-		ID:        s.ID,
-		CreatedAt: s.CreatedAt,
-		UpdatedAt: s.UpdatedAt,
-		Messages:  messages,
-		Summary:   s.Summary,
-		Source:    s.Source,
+		ID:            s.ID,
+		CreatedAt:     s.CreatedAt,
+		UpdatedAt:     s.UpdatedAt,
+		Messages:      messages,
+		Summary:       s.Summary,
+		Title:         s.Title,
+		Pins:          append([]string(nil), s.Pins...),
+		Orchestrator:  s.Orchestrator,
+		DeveloperMode: s.DeveloperMode,
+		Persona:       s.Persona,
+		AgentProfile:  s.AgentProfile,
+		Provider:      s.Provider,
+		Model:         s.Model,
+		Fallback:      append([]string(nil), s.Fallback...),
+		Archived:      s.Archived,
+		Source:        s.Source,
 	}
 }
 
 func (s *Session) appendSnapshotLocked() sessionAppendSnapshot {
 	return sessionAppendSnapshot{
-		ID:           s.ID,
-		CreatedAt:    s.CreatedAt,
-		Summary:      s.Summary,
-		Source:       s.Source,
-		MessageCount: len(s.Messages),
+		ID:            s.ID,
+		CreatedAt:     s.CreatedAt,
+		Summary:       s.Summary,
+		Title:         s.Title,
+		Pins:          append([]string(nil), s.Pins...),
+		Orchestrator:  s.Orchestrator,
+		DeveloperMode: s.DeveloperMode,
+		Persona:       s.Persona,
+		AgentProfile:  s.AgentProfile,
+		Provider:      s.Provider,
+		Model:         s.Model,
+		Fallback:      append([]string(nil), s.Fallback...),
+		Archived:      s.Archived,
+		Source:        s.Source,
+		MessageCount:  len(s.Messages),
 	}
 }
 
@@ -374,9 +733,19 @@ func (m *Manager) saveSnapshot(snapshot sessionSnapshot) error {
 
 	filtered := m.filterMessages(snapshot.Messages, snapshot.Source)
 	return m.SaveJSONL(snapshot.ID, filtered, map[string]interface{}{
-		"summary":    snapshot.Summary,
-		"source":     snapshot.Source,
-		"created_at": snapshot.CreatedAt.Format(time.RFC3339Nano),
+		"summary":        snapshot.Summary,
+		"title":          snapshot.Title,
+		"pins":           snapshot.Pins,
+		"orchestrator":   snapshot.Orchestrator,
+		"developer_mode": snapshot.DeveloperMode,
+		"persona":        snapshot.Persona,
+		"agent_profile":  snapshot.AgentProfile,
+		"provider":       snapshot.Provider,
+		"model":          snapshot.Model,
+		"fallback":       snapshot.Fallback,
+		"archived":       snapshot.Archived,
+		"source":         snapshot.Source,
+		"created_at":     snapshot.CreatedAt.Format(time.RFC3339Nano),
 	})
 }
 
@@ -400,9 +769,19 @@ func (m *Manager) appendSnapshotMessage(snapshot sessionAppendSnapshot, msg Mess
 	}
 
 	return m.AppendMessageJSONL(snapshot.ID, filtered, map[string]interface{}{
-		"summary":    snapshot.Summary,
-		"source":     snapshot.Source,
-		"created_at": snapshot.CreatedAt.Format(time.RFC3339Nano),
+		"summary":        snapshot.Summary,
+		"title":          snapshot.Title,
+		"pins":           snapshot.Pins,
+		"orchestrator":   snapshot.Orchestrator,
+		"developer_mode": snapshot.DeveloperMode,
+		"persona":        snapshot.Persona,
+		"agent_profile":  snapshot.AgentProfile,
+		"provider":       snapshot.Provider,
+		"model":          snapshot.Model,
+		"fallback":       snapshot.Fallback,
+		"archived":       snapshot.Archived,
+		"source":         snapshot.Source,
+		"created_at":     snapshot.CreatedAt.Format(time.RFC3339Nano),
 	}, snapshot.CreatedAt)
 }
 
@@ -479,6 +858,24 @@ func (m *Manager) filterMessage(msg Message) (bool, Message) {
 	}
 }
 
+// pinsFromMetadata decodes the "pins" metadata entry written by saveSnapshot.
+func pinsFromMetadata(raw interface{}) []string {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+	pins := make([]string, 0, len(items))
+	for _, item := range items {
+		if text, ok := item.(string); ok && strings.TrimSpace(text) != "" {
+			pins = append(pins, text)
+		}
+	}
+	if len(pins) == 0 {
+		return nil
+	}
+	return pins
+}
+
 func stringsTrimmed(v string) string {
 	return strings.TrimSpace(strings.ToLower(v))
 }
@@ -240,3 +240,260 @@ func TestSnapshotStoreLoadSnapshots(t *testing.T) {
 		t.Fatalf("expected snapshot file %s to exist: %v", path, err)
 	}
 }
+
+func TestSessionPinsPersistAndSurviveHistoryTrimming(t *testing.T) {
+	cfg := config.DefaultConfig().Sessions
+	cfg.Sources = config.SessionSourcesConfig{WebUI: true}
+
+	manager := NewManager(t.TempDir(), cfg)
+	sess, err := manager.GetWithSource("webui-pins", SourceWebUI)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+
+	if !sess.AddPin("  Always answer in English.  ") {
+		t.Fatal("expected first pin to be added")
+	}
+	if sess.AddPin("Always answer in English.") {
+		t.Fatal("expected duplicate pin to be rejected")
+	}
+	if sess.AddPin("   ") {
+		t.Fatal("expected empty pin to be rejected")
+	}
+	sess.AddPin("Project codename is kestrel.")
+	sess.AddMessage(Message{Role: "user", Content: "hello"})
+	sess.ReplaceMessages(nil)
+
+	reloaded := NewManager(manager.baseDir, cfg)
+	loaded, err := reloaded.GetExisting("webui-pins")
+	if err != nil {
+		t.Fatalf("GetExisting failed: %v", err)
+	}
+	pins := loaded.GetPins()
+	if len(pins) != 2 || pins[0] != "Always answer in English." || pins[1] != "Project codename is kestrel." {
+		t.Fatalf("unexpected persisted pins: %#v", pins)
+	}
+
+	removed, ok := loaded.RemovePin(1)
+	if !ok || removed != "Always answer in English." {
+		t.Fatalf("unexpected RemovePin result: %q %v", removed, ok)
+	}
+	if _, ok := loaded.RemovePin(5); ok {
+		t.Fatal("expected out-of-range RemovePin to fail")
+	}
+	loaded.ClearPins()
+	if pins := loaded.GetPins(); len(pins) != 0 {
+		t.Fatalf("expected pins cleared, got %#v", pins)
+	}
+}
+
+func TestSessionOrchestratorOverridePersists(t *testing.T) {
+	cfg := config.DefaultConfig().Sessions
+	cfg.Sources = config.SessionSourcesConfig{WebUI: true}
+
+	manager := NewManager(t.TempDir(), cfg)
+	sess, err := manager.GetWithSource("webui-orchestrator", SourceWebUI)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+	sess.SetOrchestrator(" legacy ")
+	sess.AddMessage(Message{Role: "user", Content: "hello"})
+
+	reloaded := NewManager(manager.baseDir, cfg)
+	loaded, err := reloaded.GetExisting("webui-orchestrator")
+	if err != nil {
+		t.Fatalf("GetExisting failed: %v", err)
+	}
+	if got := loaded.GetOrchestrator(); got != "legacy" {
+		t.Fatalf("expected persisted orchestrator override, got %q", got)
+	}
+}
+
+func TestSessionModelOverridePersists(t *testing.T) {
+	cfg := config.DefaultConfig().Sessions
+	cfg.Sources = config.SessionSourcesConfig{WebUI: true}
+
+	manager := NewManager(t.TempDir(), cfg)
+	sess, err := manager.GetWithSource("webui-model", SourceWebUI)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+	sess.SetModelOverride(" claude ", " claude-sonnet ", []string{"openai", " ", "local"})
+	sess.AddMessage(Message{Role: "user", Content: "hello"})
+
+	reloaded := NewManager(manager.baseDir, cfg)
+	loaded, err := reloaded.GetExisting("webui-model")
+	if err != nil {
+		t.Fatalf("GetExisting failed: %v", err)
+	}
+	provider, model, fallback := loaded.GetModelOverride()
+	if provider != "claude" || model != "claude-sonnet" {
+		t.Fatalf("expected persisted provider and model overrides, got %q %q", provider, model)
+	}
+	if len(fallback) != 2 || fallback[0] != "openai" || fallback[1] != "local" {
+		t.Fatalf("expected persisted fallback override, got %#v", fallback)
+	}
+
+	loaded.SetModelOverride("", "", nil)
+	if provider, model, fallback := loaded.GetModelOverride(); provider != "" || model != "" || len(fallback) != 0 {
+		t.Fatalf("expected overrides cleared, got %q %q %#v", provider, model, fallback)
+	}
+}
+
+func TestSessionPersonaPersists(t *testing.T) {
+	cfg := config.DefaultConfig().Sessions
+	cfg.Sources = config.SessionSourcesConfig{WebUI: true}
+
+	manager := NewManager(t.TempDir(), cfg)
+	sess, err := manager.GetWithSource("webui-persona", SourceWebUI)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+	sess.SetPersona("  You are a grumpy pirate.  ")
+	sess.AddMessage(Message{Role: "user", Content: "hello"})
+
+	reloaded := NewManager(manager.baseDir, cfg)
+	loaded, err := reloaded.GetExisting("webui-persona")
+	if err != nil {
+		t.Fatalf("GetExisting failed: %v", err)
+	}
+	if got := loaded.GetPersona(); got != "You are a grumpy pirate." {
+		t.Fatalf("expected persisted persona, got %q", got)
+	}
+
+	loaded.SetPersona("")
+	if got := loaded.GetPersona(); got != "" {
+		t.Fatalf("expected persona to be cleared, got %q", got)
+	}
+}
+
+func TestSessionTitlePersists(t *testing.T) {
+	cfg := config.DefaultConfig().Sessions
+	cfg.Sources = config.SessionSourcesConfig{WebUI: true}
+
+	manager := NewManager(t.TempDir(), cfg)
+	sess, err := manager.GetWithSource("webui-title", SourceWebUI)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+	sess.AddMessage(Message{Role: "user", Content: "hello"})
+	sess.SetTitle("  Greeting chat  ")
+
+	reloaded := NewManager(manager.baseDir, cfg)
+	loaded, err := reloaded.GetExisting("webui-title")
+	if err != nil {
+		t.Fatalf("GetExisting failed: %v", err)
+	}
+	if got := loaded.GetTitle(); got != "Greeting chat" {
+		t.Fatalf("expected persisted title, got %q", got)
+	}
+}
+
+func TestSessionArchivePersistsAndListKeysKeepsOriginalIDs(t *testing.T) {
+	cfg := config.DefaultConfig().Sessions
+	cfg.Sources = config.SessionSourcesConfig{Channels: true}
+
+	manager := NewManager(t.TempDir(), cfg)
+	sess, err := manager.GetWithSource("telegram:42", SourceChannels)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+	sess.AddMessage(Message{Role: "user", Content: "hello"})
+	sess.SetArchived(true)
+
+	reloaded := NewManager(manager.baseDir, cfg)
+	keys, err := reloaded.ListKeys()
+	if err != nil {
+		t.Fatalf("ListKeys failed: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "telegram:42" {
+		t.Fatalf("expected original key telegram:42, got %v", keys)
+	}
+	loaded, err := reloaded.GetExisting(keys[0])
+	if err != nil {
+		t.Fatalf("GetExisting failed: %v", err)
+	}
+	if !loaded.IsArchived() || loaded.GetSource() != SourceChannels {
+		t.Fatalf("expected archived channel session, got archived=%v source=%q", loaded.IsArchived(), loaded.GetSource())
+	}
+}
+
+func TestSessionDeleteNotifiesCloseHooks(t *testing.T) {
+	cfg := config.DefaultConfig().Sessions
+	cfg.Sources = config.SessionSourcesConfig{WebUI: true}
+
+	manager := NewManager(t.TempDir(), cfg)
+	var closed []*SessionJSONL
+	manager.OnClose(func(s *SessionJSONL) {
+		closed = append(closed, s)
+	})
+
+	sess, err := manager.GetWithSource("webui:close", SourceWebUI)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+	sess.AddMessage(Message{Role: "user", Content: "hello"})
+	sess.AddMessage(Message{Role: "assistant", Content: "hi there"})
+
+	if err := manager.Delete("webui:close"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if len(closed) != 1 {
+		t.Fatalf("expected one close notification, got %d", len(closed))
+	}
+	if closed[0].Key != "webui:close" || len(closed[0].Messages) != 2 {
+		t.Fatalf("unexpected closed session: %+v", closed[0])
+	}
+	if _, err := os.Stat(manager.getJSONLPath("webui:close")); !os.IsNotExist(err) {
+		t.Fatalf("expected session file removed, got %v", err)
+	}
+
+	if err := manager.Delete("webui:close"); err != nil {
+		t.Fatalf("second Delete failed: %v", err)
+	}
+	if len(closed) != 1 {
+		t.Fatalf("expected no notification for missing session, got %d", len(closed))
+	}
+}
+
+func TestSharedManagerReloadsSessionChangedByAnotherProcess(t *testing.T) {
+	cfg := config.DefaultConfig().Sessions
+	cfg.Sources = config.SessionSourcesConfig{Channels: true}
+	dir := t.TempDir()
+
+	first := NewManager(dir, cfg)
+	first.SetShared(true)
+	second := NewManager(dir, cfg)
+	second.SetShared(true)
+
+	sess, err := first.GetWithSource("telegram:42", SourceChannels)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+	sess.AddMessage(Message{Role: "user", Content: "hello"})
+
+	other, err := second.GetWithSource("telegram:42", SourceChannels)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+	other.AddMessage(Message{Role: "assistant", Content: "hi there"})
+	other.AddMessage(Message{Role: "user", Content: "how are you?"})
+
+	reloaded, err := first.GetWithSource("telegram:42", SourceChannels)
+	if err != nil {
+		t.Fatalf("GetWithSource failed: %v", err)
+	}
+	if got := len(reloaded.GetMessages()); got != 3 {
+		t.Fatalf("expected the shared manager to reload 3 messages, got %d", got)
+	}
+	if again, _ := first.GetWithSource("telegram:42", SourceChannels); again != reloaded {
+		t.Fatal("expected an unchanged session to stay cached")
+	}
+
+	private := NewManager(dir, cfg)
+	cached, _ := private.GetWithSource("telegram:42", SourceChannels)
+	sess.AddMessage(Message{Role: "assistant", Content: "fine"})
+	if again, _ := private.GetWithSource("telegram:42", SourceChannels); again != cached || len(again.GetMessages()) != 3 {
+		t.Fatal("expected a private manager to keep its cached session")
+	}
+}
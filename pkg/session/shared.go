@@ -0,0 +1,58 @@
+package session
+
+import (
+	"os"
+	"time"
+)
+
+// fileStamp identifies the version of a session file this process last
+// read or wrote.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// SetShared marks the sessions directory as shared with other processes,
+// such as several "nekobot worker" replicas. A shared manager reloads a
+// cached session when its file changed since this process last read or
+// wrote it, so a conversation handled by another replica is not
+// overwritten with stale history.
+func (m *Manager) SetShared(shared bool) {
+	m.stampMu.Lock()
+	defer m.stampMu.Unlock()
+	m.shared = shared
+	if m.stamps == nil {
+		m.stamps = make(map[string]fileStamp)
+	}
+}
+
+// recordStamp remembers the current version of the session file.
+func (m *Manager) recordStamp(key string) {
+	m.stampMu.Lock()
+	defer m.stampMu.Unlock()
+	if !m.shared {
+		return
+	}
+	info, err := os.Stat(m.getJSONLPath(key))
+	if err != nil {
+		delete(m.stamps, key)
+		return
+	}
+	m.stamps[key] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// changedOnDisk reports whether a shared session file differs from the
+// version this process last read or wrote.
+func (m *Manager) changedOnDisk(key string) bool {
+	m.stampMu.Lock()
+	defer m.stampMu.Unlock()
+	if !m.shared {
+		return false
+	}
+	info, err := os.Stat(m.getJSONLPath(key))
+	if err != nil {
+		return false
+	}
+	stamp, ok := m.stamps[key]
+	return !ok || !stamp.modTime.Equal(info.ModTime()) || stamp.size != info.Size()
+}
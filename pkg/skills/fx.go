@@ -55,6 +55,7 @@ func ProvideManager(log *logger.Logger, cfg *config.Config) (*Manager, error) {
 			MaxCount: cfg.WebUI.SkillVersions.MaxCount,
 		},
 	)
+	manager.SetIndex(cfg.Agents.Defaults.SkillsIndexURL, cfg.Agents.Defaults.SkillsIndexOnly)
 	manager.eligibilityCheck.SetConfigPathExists(func(path string) bool {
 		return hasConfigPath(cfg, path)
 	})
@@ -105,6 +106,10 @@ func hasConfigPath(cfg *config.Config, path string) bool {
 		return cfg.Channels.Infoflow.Enabled
 	case "channels.gotify":
 		return cfg.Channels.Gotify.Enabled
+	case "channels.matrix":
+		return cfg.Channels.Matrix.Enabled
+	case "channels.signal":
+		return cfg.Channels.Signal.Enabled
 	default:
 		return false
 	}
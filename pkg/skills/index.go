@@ -0,0 +1,273 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nekobot/pkg/motd"
+)
+
+const (
+	indexCacheTTL      = 10 * time.Minute
+	indexFetchTimeout  = 30 * time.Second
+	maxIndexBytes      = 8 << 20
+	maxChangelogLength = 800
+)
+
+var (
+	indexSkillIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	shortRepoPattern    = regexp.MustCompile(`^[A-Za-z0-9._-]+/[A-Za-z0-9._-]+$`)
+)
+
+// Index is the curated skills index: a JSON feed listing vetted skills and
+// their released versions.
+type Index struct {
+	Skills []IndexEntry `json:"skills"`
+}
+
+// IndexEntry is one skill in the index.
+type IndexEntry struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Repo        string            `json:"repo"`           // owner/repo on GitHub, or a git URL
+	Path        string            `json:"path,omitempty"` // Directory of the skill inside the repo, default the root
+	Tags        []string          `json:"tags,omitempty"`
+	Permissions *SkillPermissions `json:"permissions,omitempty"` // What the skill's manifest may request
+	Versions    []IndexVersion    `json:"versions"`
+}
+
+// IndexVersion is one released version of an indexed skill.
+type IndexVersion struct {
+	Version     string    `json:"version"`
+	Ref         string    `json:"ref,omitempty"` // Git tag or branch, default the version
+	Changelog   string    `json:"changelog,omitempty"`
+	PublishedAt time.Time `json:"published_at,omitempty"`
+}
+
+// ParseIndex decodes and validates an index. Versions of each entry are
+// sorted newest first.
+func ParseIndex(data []byte) (*Index, error) {
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("parse skills index: %w", err)
+	}
+	seen := make(map[string]struct{}, len(index.Skills))
+	for i := range index.Skills {
+		entry := &index.Skills[i]
+		entry.ID = strings.TrimSpace(entry.ID)
+		entry.Repo = strings.TrimSpace(entry.Repo)
+		entry.Path = strings.Trim(strings.TrimSpace(entry.Path), "/")
+		if !indexSkillIDPattern.MatchString(entry.ID) {
+			return nil, fmt.Errorf("skills index: invalid skill id %q", entry.ID)
+		}
+		if _, dup := seen[entry.ID]; dup {
+			return nil, fmt.Errorf("skills index: duplicate skill id %q", entry.ID)
+		}
+		seen[entry.ID] = struct{}{}
+		if entry.Repo == "" {
+			return nil, fmt.Errorf("skills index: %s has no repo", entry.ID)
+		}
+		if strings.Contains(entry.Path, "..") {
+			return nil, fmt.Errorf("skills index: %s has an invalid path", entry.ID)
+		}
+		if entry.Name == "" {
+			entry.Name = entry.ID
+		}
+		for j := range entry.Versions {
+			version := &entry.Versions[j]
+			version.Version = strings.TrimSpace(version.Version)
+			if version.Version == "" {
+				return nil, fmt.Errorf("skills index: %s has a version without a number", entry.ID)
+			}
+			if version.Ref = strings.TrimSpace(version.Ref); version.Ref == "" {
+				version.Ref = version.Version
+			}
+		}
+		if len(entry.Versions) == 0 {
+			return nil, fmt.Errorf("skills index: %s has no versions", entry.ID)
+		}
+		sort.SliceStable(entry.Versions, func(a, b int) bool {
+			return compareSkillVersions(entry.Versions[a].Version, entry.Versions[b].Version) > 0
+		})
+	}
+	return &index, nil
+}
+
+// Lookup finds an entry by id or repo.
+func (idx *Index) Lookup(ref string) (IndexEntry, bool) {
+	ref = strings.TrimSpace(ref)
+	if idx == nil || ref == "" {
+		return IndexEntry{}, false
+	}
+	for _, entry := range idx.Skills {
+		if entry.ID == ref || strings.EqualFold(entry.Repo, ref) || strings.EqualFold(entry.RepoURL(), ref) {
+			return entry, true
+		}
+	}
+	return IndexEntry{}, false
+}
+
+// Search returns entries whose id, name, description or tags contain query.
+func (idx *Index) Search(query string) []IndexEntry {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if idx == nil || query == "" {
+		return nil
+	}
+	var matches []IndexEntry
+	for _, entry := range idx.Skills {
+		haystack := strings.ToLower(strings.Join(append([]string{entry.ID, entry.Name, entry.Description, entry.Repo}, entry.Tags...), " "))
+		if strings.Contains(haystack, query) {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
+
+// Latest returns the newest version of the entry.
+func (e IndexEntry) Latest() IndexVersion {
+	if len(e.Versions) == 0 {
+		return IndexVersion{}
+	}
+	return e.Versions[0]
+}
+
+// Version returns the named version, or the latest one for an empty name.
+func (e IndexEntry) Version(version string) (IndexVersion, bool) {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return e.Latest(), len(e.Versions) > 0
+	}
+	for _, v := range e.Versions {
+		if v.Version == version || strings.TrimPrefix(v.Version, "v") == strings.TrimPrefix(version, "v") {
+			return v, true
+		}
+	}
+	return IndexVersion{}, false
+}
+
+// RepoURL returns the git URL of the entry's repository.
+func (e IndexEntry) RepoURL() string {
+	if shortRepoPattern.MatchString(e.Repo) {
+		return "https://github.com/" + e.Repo + ".git"
+	}
+	return e.Repo
+}
+
+// ShortChangelog returns the changelog trimmed for chat messages.
+func (v IndexVersion) ShortChangelog() string {
+	changelog := strings.TrimSpace(v.Changelog)
+	if len(changelog) <= maxChangelogLength {
+		return changelog
+	}
+	cut := maxChangelogLength
+	for cut > 0 && !isRuneStart(changelog[cut]) {
+		cut--
+	}
+	return strings.TrimSpace(changelog[:cut]) + "…"
+}
+
+func isRuneStart(b byte) bool { return b&0xC0 != 0x80 }
+
+// compareSkillVersions orders semantic versions, falling back to a plain
+// string comparison for versions that do not parse.
+func compareSkillVersions(a, b string) int {
+	if cmp, ok := motd.CompareVersions(a, b); ok {
+		return cmp
+	}
+	return strings.Compare(a, b)
+}
+
+// IndexClient fetches the skills index from an http(s) URL or a local file
+// and caches it briefly.
+type IndexClient struct {
+	source string
+	client *http.Client
+
+	mu        sync.Mutex
+	cached    *Index
+	fetchedAt time.Time
+}
+
+// NewIndexClient creates an index client. client may be nil.
+func NewIndexClient(source string, client *http.Client) *IndexClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &IndexClient{source: strings.TrimSpace(source), client: client}
+}
+
+// Source returns the configured index location.
+func (c *IndexClient) Source() string {
+	if c == nil {
+		return ""
+	}
+	return c.source
+}
+
+// Fetch returns the index, reusing a copy fetched in the last few minutes.
+func (c *IndexClient) Fetch(ctx context.Context) (*Index, error) {
+	if c == nil || c.source == "" {
+		return nil, fmt.Errorf("skills index is not configured (agents.defaults.skills_index_url)")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cached != nil && time.Since(c.fetchedAt) < indexCacheTTL {
+		return c.cached, nil
+	}
+
+	data, err := c.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index, err := ParseIndex(data)
+	if err != nil {
+		return nil, err
+	}
+	c.cached = index
+	c.fetchedAt = time.Now()
+	return index, nil
+}
+
+func (c *IndexClient) read(ctx context.Context) ([]byte, error) {
+	if !isRemoteSkillSource(c.source) {
+		data, err := os.ReadFile(c.source)
+		if err != nil {
+			return nil, fmt.Errorf("read skills index: %w", err)
+		}
+		return data, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, indexFetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch skills index: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch skills index: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxIndexBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read skills index: %w", err)
+	}
+	if len(data) > maxIndexBytes {
+		return nil, fmt.Errorf("skills index exceeds %d MB", maxIndexBytes>>20)
+	}
+	return data, nil
+}
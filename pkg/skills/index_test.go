@@ -0,0 +1,73 @@
+package skills
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseIndexSortsVersionsAndDefaultsRefs(t *testing.T) {
+	index, err := ParseIndex([]byte(`{"skills":[{
+		"id": "pdf-tools",
+		"repo": "acme/pdf-skill",
+		"path": "/skills/pdf/",
+		"tags": ["documents"],
+		"versions": [
+			{"version": "v1.2.0", "changelog": "Adds merging"},
+			{"version": "v1.10.0", "ref": "release-1.10"},
+			{"version": "v1.9.1"}
+		]
+	}]}`))
+	if err != nil {
+		t.Fatalf("parse index: %v", err)
+	}
+	entry, ok := index.Lookup("https://github.com/acme/pdf-skill.git")
+	if !ok {
+		t.Fatal("expected lookup by repo URL to match")
+	}
+	if entry.Name != "pdf-tools" || entry.Path != "skills/pdf" {
+		t.Fatalf("unexpected normalized entry: %+v", entry)
+	}
+	var order []string
+	for _, v := range entry.Versions {
+		order = append(order, v.Version)
+	}
+	if strings.Join(order, ",") != "v1.10.0,v1.9.1,v1.2.0" {
+		t.Fatalf("unexpected version order: %v", order)
+	}
+	if latest := entry.Latest(); latest.Ref != "release-1.10" {
+		t.Fatalf("expected explicit ref to be kept, got %q", latest.Ref)
+	}
+	release, ok := entry.Version("1.2.0")
+	if !ok || release.Ref != "v1.2.0" || release.Changelog != "Adds merging" {
+		t.Fatalf("unexpected version lookup: %+v ok=%v", release, ok)
+	}
+	if matches := index.Search("DOCUMENTS"); len(matches) != 1 {
+		t.Fatalf("expected tag search to match, got %d", len(matches))
+	}
+}
+
+func TestParseIndexRejectsInvalidEntries(t *testing.T) {
+	cases := map[string]string{
+		"invalid skill id":    `{"skills":[{"id":"Bad_ID","repo":"a/b","versions":[{"version":"1.0.0"}]}]}`,
+		"duplicate skill":     `{"skills":[{"id":"a","repo":"a/b","versions":[{"version":"1"}]},{"id":"a","repo":"a/c","versions":[{"version":"1"}]}]}`,
+		"has no repo":         `{"skills":[{"id":"a","versions":[{"version":"1.0.0"}]}]}`,
+		"has no versions":     `{"skills":[{"id":"a","repo":"a/b"}]}`,
+		"has an invalid path": `{"skills":[{"id":"a","repo":"a/b","path":"../etc","versions":[{"version":"1"}]}]}`,
+	}
+	for want, data := range cases {
+		if _, err := ParseIndex([]byte(data)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
+
+func TestShortChangelogTruncatesOnRuneBoundary(t *testing.T) {
+	release := IndexVersion{Changelog: strings.Repeat("日", maxChangelogLength)}
+	short := release.ShortChangelog()
+	if !strings.HasSuffix(short, "…") || len(short) > maxChangelogLength+len("…") {
+		t.Fatalf("unexpected truncation length %d", len(short))
+	}
+	if !strings.HasPrefix(short, "日") || strings.ContainsRune(short, '�') {
+		t.Fatalf("truncation split a rune: %q", short[:12])
+	}
+}
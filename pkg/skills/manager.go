@@ -39,6 +39,9 @@ type Skill struct {
 
 	// Installation requirements
 	Requirements *SkillRequirements `yaml:"requirements" json:"requirements,omitempty"`
+
+	// Permission manifest; nil limits the skill to read-only tools
+	Permissions *SkillPermissions `yaml:"permissions" json:"permissions,omitempty"`
 }
 
 // SkillRequirements defines what a skill needs to run.
@@ -72,6 +75,10 @@ type Manager struct {
 	snapshotCfg      SnapshotRetentionConfig
 	versionMgr       *VersionManager
 	versionCfg       VersionRetentionConfig
+	indexMu          sync.Mutex
+	index            *IndexClient
+	indexOnly        bool
+	installMu        sync.Mutex // Serializes index installs and the lock file
 }
 
 // SnapshotRetentionConfig controls automatic cleanup of skill snapshots.
@@ -514,11 +521,16 @@ func (m *Manager) ListAlwaysEligible() []*Skill {
 	return skills
 }
 
-// ToBladesSkills wraps all eligible enabled skills as blade-compatible Skill implementations.
+// ToBladesSkills wraps eligible enabled builtin skills as blade-compatible
+// Skill implementations. Installed skills stay behind the skill tool, which
+// holds the turn to their permission manifest.
 func (m *Manager) ToBladesSkills() []bladeskills.Skill {
 	eligible := m.ListEligibleEnabled()
 	result := make([]bladeskills.Skill, 0, len(eligible))
 	for _, skill := range eligible {
+		if !skill.Trusted() {
+			continue
+		}
 		result = append(result, NewBladesSkillAdapter(skill))
 	}
 	return result
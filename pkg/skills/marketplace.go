@@ -0,0 +1,348 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/fileutil"
+)
+
+// lockFileName records the skills installed from the index, next to them.
+const lockFileName = "skills-lock.json"
+
+// ErrSkillNotInIndex is returned for skills the curated index does not list.
+var ErrSkillNotInIndex = errors.New("skill is not in the skills index")
+
+// InstalledSkill is the lock entry of a skill installed from the index.
+type InstalledSkill struct {
+	ID          string            `json:"id"`
+	Repo        string            `json:"repo"`
+	Path        string            `json:"path,omitempty"`
+	Version     string            `json:"version"`
+	Ref         string            `json:"ref"`
+	Pinned      bool              `json:"pinned,omitempty"`
+	Permissions *SkillPermissions `json:"permissions,omitempty"`
+	InstalledAt time.Time         `json:"installed_at"`
+}
+
+// SkillUpdate reports a newer index version of an installed skill.
+type SkillUpdate struct {
+	ID        string `json:"id"`
+	Current   string `json:"current"`
+	Latest    string `json:"latest"`
+	Pinned    bool   `json:"pinned"`
+	Changelog string `json:"changelog,omitempty"`
+}
+
+type skillsLock struct {
+	Skills map[string]InstalledSkill `json:"skills"`
+}
+
+// SetIndex configures the curated skills index by URL or file path. An
+// empty source disables index features. When indexOnly is set, chat
+// installs are limited to indexed skills.
+func (m *Manager) SetIndex(source string, indexOnly bool) {
+	var client *IndexClient
+	if source = strings.TrimSpace(source); source != "" {
+		var httpClient *http.Client
+		if registry, ok := m.registry.(*RegistryClient); ok {
+			httpClient = registry.client
+		}
+		client = NewIndexClient(source, httpClient)
+	}
+	m.indexMu.Lock()
+	m.index = client
+	m.indexOnly = indexOnly
+	m.indexMu.Unlock()
+}
+
+// HasIndex reports whether a skills index is configured.
+func (m *Manager) HasIndex() bool {
+	if m == nil {
+		return false
+	}
+	m.indexMu.Lock()
+	defer m.indexMu.Unlock()
+	return m.index != nil
+}
+
+// IndexOnly reports whether chat installs are limited to indexed skills.
+func (m *Manager) IndexOnly() bool {
+	m.indexMu.Lock()
+	defer m.indexMu.Unlock()
+	return m.indexOnly
+}
+
+// FetchIndex returns the curated skills index.
+func (m *Manager) FetchIndex(ctx context.Context) (*Index, error) {
+	m.indexMu.Lock()
+	client := m.index
+	m.indexMu.Unlock()
+	return client.Fetch(ctx)
+}
+
+// LookupIndex finds a skill in the index by id or repo.
+func (m *Manager) LookupIndex(ctx context.Context, ref string) (IndexEntry, error) {
+	index, err := m.FetchIndex(ctx)
+	if err != nil {
+		return IndexEntry{}, err
+	}
+	entry, ok := index.Lookup(ref)
+	if !ok {
+		return IndexEntry{}, fmt.Errorf("%w: %s", ErrSkillNotInIndex, ref)
+	}
+	return entry, nil
+}
+
+// InstalledSkills returns the lock entries of skills installed from the index.
+func (m *Manager) InstalledSkills() ([]InstalledSkill, error) {
+	m.installMu.Lock()
+	defer m.installMu.Unlock()
+	lock, err := m.readLock()
+	if err != nil {
+		return nil, err
+	}
+	installed := make([]InstalledSkill, 0, len(lock.Skills))
+	for _, entry := range lock.Skills {
+		installed = append(installed, entry)
+	}
+	sort.Slice(installed, func(i, j int) bool { return installed[i].ID < installed[j].ID })
+	return installed, nil
+}
+
+// InstallIndexed installs a version of an indexed skill, the latest when
+// version is empty. A pinned skill keeps its pin.
+func (m *Manager) InstallIndexed(ctx context.Context, ref, version string) (InstalledSkill, error) {
+	entry, err := m.LookupIndex(ctx, ref)
+	if err != nil {
+		return InstalledSkill{}, err
+	}
+	release, ok := entry.Version(version)
+	if !ok {
+		return InstalledSkill{}, fmt.Errorf("skill %s has no version %s in the index", entry.ID, version)
+	}
+
+	m.installMu.Lock()
+	defer m.installMu.Unlock()
+	lock, err := m.readLock()
+	if err != nil {
+		return InstalledSkill{}, err
+	}
+	installed, err := m.installRelease(ctx, entry, release)
+	if err != nil {
+		return InstalledSkill{}, err
+	}
+	installed.Pinned = lock.Skills[entry.ID].Pinned
+	lock.Skills[entry.ID] = installed
+	if err := m.writeLock(lock); err != nil {
+		return installed, err
+	}
+	if err := m.Discover(); err != nil {
+		return installed, fmt.Errorf("rediscover installed skills: %w", err)
+	}
+	return installed, nil
+}
+
+// CheckUpdates lists installed index skills with a newer index version,
+// including pinned ones.
+func (m *Manager) CheckUpdates(ctx context.Context) ([]SkillUpdate, error) {
+	index, err := m.FetchIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	installed, err := m.InstalledSkills()
+	if err != nil {
+		return nil, err
+	}
+	var updates []SkillUpdate
+	for _, skill := range installed {
+		entry, ok := index.Lookup(skill.ID)
+		if !ok {
+			continue
+		}
+		latest := entry.Latest()
+		if compareSkillVersions(latest.Version, skill.Version) <= 0 {
+			continue
+		}
+		updates = append(updates, SkillUpdate{
+			ID:        skill.ID,
+			Current:   skill.Version,
+			Latest:    latest.Version,
+			Pinned:    skill.Pinned,
+			Changelog: latest.ShortChangelog(),
+		})
+	}
+	return updates, nil
+}
+
+// UpgradeSkill installs the latest index version of an installed skill.
+// Pinned skills are left alone and reported with upgraded=false.
+func (m *Manager) UpgradeSkill(ctx context.Context, id string) (InstalledSkill, bool, error) {
+	current, err := m.installedSkill(id)
+	if err != nil {
+		return InstalledSkill{}, false, err
+	}
+	if current.Pinned {
+		return current, false, nil
+	}
+	entry, err := m.LookupIndex(ctx, current.ID)
+	if err != nil {
+		return current, false, err
+	}
+	if compareSkillVersions(entry.Latest().Version, current.Version) <= 0 {
+		return current, false, nil
+	}
+	installed, err := m.InstallIndexed(ctx, current.ID, "")
+	return installed, err == nil, err
+}
+
+// PinSkill holds an installed skill at a version, installing that version
+// first when it differs. An empty version pins the installed one.
+func (m *Manager) PinSkill(ctx context.Context, id, version string) (InstalledSkill, error) {
+	current, err := m.installedSkill(id)
+	if err != nil {
+		return InstalledSkill{}, err
+	}
+	if version = strings.TrimSpace(version); version != "" && version != current.Version {
+		if current, err = m.InstallIndexed(ctx, current.ID, version); err != nil {
+			return InstalledSkill{}, err
+		}
+	}
+	return m.setPinned(current.ID, true)
+}
+
+// UnpinSkill lets upgrades move an installed skill again.
+func (m *Manager) UnpinSkill(id string) (InstalledSkill, error) {
+	return m.setPinned(strings.TrimSpace(id), false)
+}
+
+func (m *Manager) setPinned(id string, pinned bool) (InstalledSkill, error) {
+	m.installMu.Lock()
+	defer m.installMu.Unlock()
+	lock, err := m.readLock()
+	if err != nil {
+		return InstalledSkill{}, err
+	}
+	installed, ok := lock.Skills[id]
+	if !ok {
+		return InstalledSkill{}, fmt.Errorf("skill %s was not installed from the skills index", id)
+	}
+	installed.Pinned = pinned
+	lock.Skills[id] = installed
+	return installed, m.writeLock(lock)
+}
+
+func (m *Manager) installedSkill(id string) (InstalledSkill, error) {
+	m.installMu.Lock()
+	defer m.installMu.Unlock()
+	lock, err := m.readLock()
+	if err != nil {
+		return InstalledSkill{}, err
+	}
+	installed, ok := lock.Skills[strings.TrimSpace(id)]
+	if !ok {
+		return InstalledSkill{}, fmt.Errorf("skill %s was not installed from the skills index", id)
+	}
+	return installed, nil
+}
+
+// installRelease clones the release ref into a staging directory inside the
+// skills dir and moves the skill directory into place. Callers hold
+// m.installMu.
+func (m *Manager) installRelease(ctx context.Context, entry IndexEntry, release IndexVersion) (InstalledSkill, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return InstalledSkill{}, fmt.Errorf("git not installed: %w", err)
+	}
+	if err := os.MkdirAll(m.skillsDir, 0o755); err != nil {
+		return InstalledSkill{}, fmt.Errorf("create skills directory: %w", err)
+	}
+	staging, err := os.MkdirTemp(m.skillsDir, ".install-")
+	if err != nil {
+		return InstalledSkill{}, fmt.Errorf("create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	clone := filepath.Join(staging, "repo")
+	cmd := exec.CommandContext(ctx, "git", "clone", "--quiet", "--depth", "1", "--branch", release.Ref, entry.RepoURL(), clone)
+	cmd.Env = skillsProxyEnv(os.Environ(), m.skillsProxy)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return InstalledSkill{}, fmt.Errorf("clone %s at %s: %w: %s", entry.Repo, release.Ref, err, strings.TrimSpace(string(output)))
+	}
+
+	source := filepath.Join(clone, filepath.FromSlash(entry.Path))
+	manifest, err := os.ReadFile(filepath.Join(source, skillFileName))
+	if err != nil {
+		return InstalledSkill{}, fmt.Errorf("%s at %s has no %s in %q", entry.Repo, release.Ref, skillFileName, entry.Path)
+	}
+	skill, err := parseSkillContent(string(manifest), filepath.Join(source, skillFileName))
+	if err != nil {
+		return InstalledSkill{}, fmt.Errorf("parse %s of %s: %w", skillFileName, entry.ID, err)
+	}
+	if entry.Permissions != nil {
+		if extra := skill.Permissions.Exceeding(entry.Permissions); len(extra) > 0 {
+			return InstalledSkill{}, fmt.Errorf("%s %s requests permissions the skills index does not list: %s", entry.ID, release.Version, strings.Join(extra, ", "))
+		}
+	}
+	_ = os.RemoveAll(filepath.Join(source, ".git"))
+
+	target := filepath.Join(m.skillsDir, entry.ID)
+	if err := os.RemoveAll(target); err != nil {
+		return InstalledSkill{}, fmt.Errorf("remove previous version: %w", err)
+	}
+	if err := os.Rename(source, target); err != nil {
+		return InstalledSkill{}, fmt.Errorf("install %s: %w", entry.ID, err)
+	}
+	m.log.Info("Installed skill from index",
+		zap.String("skill", entry.ID),
+		zap.String("version", release.Version),
+		zap.String("ref", release.Ref))
+	return InstalledSkill{
+		ID:          entry.ID,
+		Repo:        entry.Repo,
+		Path:        entry.Path,
+		Version:     release.Version,
+		Ref:         release.Ref,
+		Permissions: skill.Permissions,
+		InstalledAt: time.Now().UTC(),
+	}, nil
+}
+
+func (m *Manager) readLock() (skillsLock, error) {
+	lock := skillsLock{Skills: map[string]InstalledSkill{}}
+	data, err := os.ReadFile(filepath.Join(m.skillsDir, lockFileName))
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return lock, fmt.Errorf("read %s: %w", lockFileName, err)
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return lock, fmt.Errorf("parse %s: %w", lockFileName, err)
+	}
+	if lock.Skills == nil {
+		lock.Skills = map[string]InstalledSkill{}
+	}
+	return lock, nil
+}
+
+func (m *Manager) writeLock(lock skillsLock) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := fileutil.WriteFileAtomic(filepath.Join(m.skillsDir, lockFileName), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", lockFileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package skills
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nekobot/pkg/logger"
+)
+
+// newIndexedSkillRepo creates a git repo whose tags v1.0.0 and v1.1.0 each
+// hold a different SKILL.md under skills/greeter. frontmatter is added to
+// both versions.
+func newIndexedSkillRepo(t *testing.T, frontmatter string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, output)
+		}
+	}
+	git("init", "--quiet")
+	skillDir := filepath.Join(repo, "skills", "greeter")
+	if err := os.MkdirAll(skillDir, 0o755); err != nil {
+		t.Fatalf("create skill dir: %v", err)
+	}
+	for _, version := range []string{"v1.0.0", "v1.1.0"} {
+		content := fmt.Sprintf("---\nid: greeter\nname: Greeter\n%s---\n\nGreet the user (%s).\n", frontmatter, version)
+		if err := os.WriteFile(filepath.Join(skillDir, skillFileName), []byte(content), 0o644); err != nil {
+			t.Fatalf("write skill: %v", err)
+		}
+		git("add", "-A")
+		git("commit", "--quiet", "-m", version)
+		git("tag", version)
+	}
+	return repo
+}
+
+func newIndexedSkillsManager(t *testing.T, repo, permissions string) *Manager {
+	t.Helper()
+	log, err := logger.New(&logger.Config{Level: logger.LevelError})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	root := t.TempDir()
+	if permissions == "" {
+		permissions = "null"
+	}
+	index := fmt.Sprintf(`{"skills":[{"id":"greeter","repo":%q,"path":"skills/greeter","permissions":%s,"versions":[
+		{"version":"v1.0.0","changelog":"First release"},
+		{"version":"v1.1.0","changelog":"Friendlier greetings"}
+	]}]}`, repo, permissions)
+	indexPath := filepath.Join(root, "index.json")
+	if err := os.WriteFile(indexPath, []byte(index), 0o644); err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+	mgr := NewManager(log, filepath.Join(root, "skills"), false)
+	mgr.SetIndex(indexPath, true)
+	return mgr
+}
+
+func readInstalledGreeter(t *testing.T, mgr *Manager) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(mgr.skillsDir, "greeter", skillFileName))
+	if err != nil {
+		t.Fatalf("read installed skill: %v", err)
+	}
+	return string(data)
+}
+
+func TestInstallIndexedPinAndUpgrade(t *testing.T) {
+	mgr := newIndexedSkillsManager(t, newIndexedSkillRepo(t, ""), "")
+	ctx := context.Background()
+
+	installed, err := mgr.InstallIndexed(ctx, "greeter", "v1.0.0")
+	if err != nil {
+		t.Fatalf("install v1.0.0: %v", err)
+	}
+	if installed.Version != "v1.0.0" || !strings.Contains(readInstalledGreeter(t, mgr), "(v1.0.0)") {
+		t.Fatalf("unexpected install: %+v", installed)
+	}
+	if _, err := mgr.Get("greeter"); err != nil {
+		t.Fatalf("expected installed skill to be discovered: %v", err)
+	}
+
+	updates, err := mgr.CheckUpdates(ctx)
+	if err != nil {
+		t.Fatalf("check updates: %v", err)
+	}
+	if len(updates) != 1 || updates[0].Latest != "v1.1.0" || updates[0].Changelog != "Friendlier greetings" {
+		t.Fatalf("unexpected updates: %+v", updates)
+	}
+
+	if _, err := mgr.PinSkill(ctx, "greeter", ""); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	if _, upgraded, err := mgr.UpgradeSkill(ctx, "greeter"); err != nil || upgraded {
+		t.Fatalf("expected pinned skill to be held, upgraded=%v err=%v", upgraded, err)
+	}
+	if !strings.Contains(readInstalledGreeter(t, mgr), "(v1.0.0)") {
+		t.Fatal("pinned skill changed on upgrade")
+	}
+
+	if _, err := mgr.UnpinSkill("greeter"); err != nil {
+		t.Fatalf("unpin: %v", err)
+	}
+	installed, upgraded, err := mgr.UpgradeSkill(ctx, "greeter")
+	if err != nil || !upgraded || installed.Version != "v1.1.0" {
+		t.Fatalf("expected upgrade to v1.1.0, got %+v upgraded=%v err=%v", installed, upgraded, err)
+	}
+	if !strings.Contains(readInstalledGreeter(t, mgr), "(v1.1.0)") {
+		t.Fatal("upgrade did not replace the skill files")
+	}
+	if updates, err := mgr.CheckUpdates(ctx); err != nil || len(updates) != 0 {
+		t.Fatalf("expected no updates after upgrade, got %+v err=%v", updates, err)
+	}
+
+	pinned, err := mgr.PinSkill(ctx, "greeter", "1.0.0")
+	if err != nil || !pinned.Pinned || pinned.Version != "v1.0.0" {
+		t.Fatalf("expected pin to downgrade to v1.0.0, got %+v err=%v", pinned, err)
+	}
+	entries, err := os.ReadDir(mgr.skillsDir)
+	if err != nil {
+		t.Fatalf("read skills dir: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".install-") {
+			t.Fatalf("staging directory left behind: %s", entry.Name())
+		}
+	}
+}
+
+func TestInstallIndexedRejectsUnknownSkillsAndVersions(t *testing.T) {
+	mgr := newIndexedSkillsManager(t, newIndexedSkillRepo(t, ""), "")
+	ctx := context.Background()
+
+	if _, err := mgr.InstallIndexed(ctx, "someone/else", ""); !errors.Is(err, ErrSkillNotInIndex) {
+		t.Fatalf("expected ErrSkillNotInIndex, got %v", err)
+	}
+	if _, err := mgr.InstallIndexed(ctx, "greeter", "v9.9.9"); err == nil || !strings.Contains(err.Error(), "no version v9.9.9") {
+		t.Fatalf("expected unknown version error, got %v", err)
+	}
+	if _, err := mgr.PinSkill(ctx, "greeter", ""); err == nil {
+		t.Fatal("expected pinning a skill not installed from the index to fail")
+	}
+}
+
+func TestInstallIndexedEnforcesIndexPermissions(t *testing.T) {
+	repo := newIndexedSkillRepo(t, "permissions:\n  network: true\n  exec: true\n")
+	ctx := context.Background()
+
+	mgr := newIndexedSkillsManager(t, repo, `{"network":true}`)
+	if _, err := mgr.InstallIndexed(ctx, "greeter", ""); err == nil || !strings.Contains(err.Error(), "does not list: exec") {
+		t.Fatalf("expected install to be refused for unlisted exec, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(mgr.skillsDir, "greeter")); !os.IsNotExist(err) {
+		t.Fatalf("expected refused skill not to be installed, stat err=%v", err)
+	}
+
+	mgr = newIndexedSkillsManager(t, repo, `{"network":true,"exec":true}`)
+	installed, err := mgr.InstallIndexed(ctx, "greeter", "")
+	if err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if got := installed.Permissions.Requested(); strings.Join(got, ",") != "network,exec" {
+		t.Fatalf("unexpected recorded permissions: %v", got)
+	}
+	skill, err := mgr.Get("greeter")
+	if err != nil {
+		t.Fatalf("get installed skill: %v", err)
+	}
+	if skill.Trusted() || !skill.AllowsTool("exec") || skill.AllowsTool("write_file") {
+		t.Fatalf("unexpected runtime permissions for installed skill: %+v", skill.Permissions)
+	}
+}
@@ -0,0 +1,162 @@
+package skills
+
+import (
+	"slices"
+	"strings"
+)
+
+// Permission is a capability a skill must declare before the agent may use
+// it while following the skill's instructions.
+type Permission string
+
+const (
+	PermissionNetwork   Permission = "network"
+	PermissionExec      Permission = "exec"
+	PermissionFileWrite Permission = "file_write"
+)
+
+// SkillPermissions is the permission manifest a skill declares in its
+// frontmatter:
+//
+//	permissions:
+//	  network: true
+//	  exec: false
+//	  file_write: true
+//	  tools: [sql_query]
+//
+// Tools outside the network, exec and file write groups, such as MCP and
+// plugin tools, must be listed by name.
+type SkillPermissions struct {
+	Network   bool     `yaml:"network" json:"network"`
+	Exec      bool     `yaml:"exec" json:"exec"`
+	FileWrite bool     `yaml:"file_write" json:"file_write"`
+	Tools     []string `yaml:"tools" json:"tools,omitempty"`
+}
+
+// readOnlyTools never need a permission: they only read the workspace or
+// talk to the user.
+var readOnlyTools = map[string]struct{}{
+	"read_file":  {},
+	"list_dir":   {},
+	"git_status": {},
+	"git_diff":   {},
+	"git_log":    {},
+	"skill":      {},
+	"list_tools": {},
+	"message":    {},
+	"send_file":  {},
+	"memory":     {},
+	"learning":   {},
+	"wiki_query": {},
+	"wiki_lint":  {},
+	"undo":       {},
+}
+
+// toolPermissions maps the built-in tools that need a permission.
+var toolPermissions = map[string]Permission{
+	"web_fetch":      PermissionNetwork,
+	"web_search":     PermissionNetwork,
+	"smart_search":   PermissionNetwork,
+	"browser":        PermissionNetwork,
+	"exec":           PermissionExec,
+	"run_background": PermissionExec,
+	"process":        PermissionExec,
+	"tool_session":   PermissionExec,
+	"write_file":     PermissionFileWrite,
+	"edit_file":      PermissionFileWrite,
+	"append_file":    PermissionFileWrite,
+	"git_branch":     PermissionFileWrite,
+	"git_commit":     PermissionFileWrite,
+	"skill_manage":   PermissionFileWrite,
+}
+
+// ToolPermission returns the permission a tool needs. ok is false for
+// read-only tools and for tools a manifest must list by name.
+func ToolPermission(tool string) (Permission, bool) {
+	perm, ok := toolPermissions[strings.TrimSpace(tool)]
+	return perm, ok
+}
+
+// Has reports whether the manifest grants perm.
+func (p *SkillPermissions) Has(perm Permission) bool {
+	if p == nil {
+		return false
+	}
+	switch perm {
+	case PermissionNetwork:
+		return p.Network
+	case PermissionExec:
+		return p.Exec
+	case PermissionFileWrite:
+		return p.FileWrite
+	}
+	return false
+}
+
+// AllowsTool reports whether the manifest lets the agent call tool.
+func (p *SkillPermissions) AllowsTool(tool string) bool {
+	tool = strings.TrimSpace(tool)
+	if _, ok := readOnlyTools[tool]; ok {
+		return true
+	}
+	if p == nil {
+		return false
+	}
+	if slices.Contains(p.Tools, tool) {
+		return true
+	}
+	perm, ok := toolPermissions[tool]
+	return ok && p.Has(perm)
+}
+
+// Requested lists what the manifest asks for, permissions first and then
+// named tools.
+func (p *SkillPermissions) Requested() []string {
+	if p == nil {
+		return nil
+	}
+	var requested []string
+	for _, perm := range []Permission{PermissionNetwork, PermissionExec, PermissionFileWrite} {
+		if p.Has(perm) {
+			requested = append(requested, string(perm))
+		}
+	}
+	for _, tool := range p.Tools {
+		if tool = strings.TrimSpace(tool); tool != "" {
+			requested = append(requested, "tool:"+tool)
+		}
+	}
+	return requested
+}
+
+// Exceeding lists what the manifest requests beyond limit.
+func (p *SkillPermissions) Exceeding(limit *SkillPermissions) []string {
+	var extra []string
+	for _, requested := range p.Requested() {
+		if tool, ok := strings.CutPrefix(requested, "tool:"); ok {
+			if limit == nil || !slices.Contains(limit.Tools, tool) {
+				extra = append(extra, requested)
+			}
+			continue
+		}
+		if !limit.Has(Permission(requested)) {
+			extra = append(extra, requested)
+		}
+	}
+	return extra
+}
+
+// Trusted reports whether the skill ships with nekobot. Builtin skills are
+// reviewed with the code and run without a manifest.
+func (s *Skill) Trusted() bool {
+	return s != nil && strings.HasPrefix(s.FilePath, "builtin://")
+}
+
+// AllowsTool reports whether the agent may call tool while following the
+// skill. Skills without a manifest only get read-only tools.
+func (s *Skill) AllowsTool(tool string) bool {
+	if s == nil || s.Trusted() {
+		return true
+	}
+	return s.Permissions.AllowsTool(tool)
+}
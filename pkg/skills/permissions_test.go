@@ -0,0 +1,82 @@
+package skills
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSkillPermissionsAllowTools(t *testing.T) {
+	skill, err := parseSkillContent(`---
+id: deployer
+permissions:
+  exec: true
+  tools: [k8s_apply]
+---
+
+Deploy things.`, "/skills/deployer/SKILL.md")
+	if err != nil {
+		t.Fatalf("parse skill: %v", err)
+	}
+	for tool, want := range map[string]bool{
+		"read_file":      true,
+		"skill":          true,
+		"exec":           true,
+		"run_background": true,
+		"k8s_apply":      true,
+		"write_file":     false,
+		"web_fetch":      false,
+		"mcp_github":     false,
+	} {
+		if got := skill.AllowsTool(tool); got != want {
+			t.Fatalf("AllowsTool(%q) = %v, want %v", tool, got, want)
+		}
+	}
+	if got := skill.Permissions.Requested(); !reflect.DeepEqual(got, []string{"exec", "tool:k8s_apply"}) {
+		t.Fatalf("unexpected requested permissions: %v", got)
+	}
+}
+
+func TestSkillWithoutManifestIsReadOnly(t *testing.T) {
+	skill := &Skill{ID: "notes", FilePath: "/skills/notes/SKILL.md"}
+	if !skill.AllowsTool("list_dir") || skill.AllowsTool("exec") || skill.AllowsTool("edit_file") {
+		t.Fatal("expected a skill without manifest to get read-only tools only")
+	}
+	builtin := &Skill{ID: "github", FilePath: "builtin://github"}
+	if !builtin.Trusted() || !builtin.AllowsTool("exec") {
+		t.Fatal("expected builtin skills to be trusted")
+	}
+}
+
+func TestSkillPermissionsExceeding(t *testing.T) {
+	requested := &SkillPermissions{Network: true, FileWrite: true, Tools: []string{"sql_query", "k8s_get"}}
+	limit := &SkillPermissions{Network: true, Tools: []string{"k8s_get"}}
+	if got := requested.Exceeding(limit); !reflect.DeepEqual(got, []string{"file_write", "tool:sql_query"}) {
+		t.Fatalf("unexpected excess: %v", got)
+	}
+	if got := requested.Exceeding(nil); len(got) != 4 {
+		t.Fatalf("expected everything to exceed a missing limit, got %v", got)
+	}
+	var none *SkillPermissions
+	if got := none.Exceeding(nil); len(got) != 0 {
+		t.Fatalf("expected a read-only skill to fit any limit, got %v", got)
+	}
+}
+
+func TestValidatePermissionsWarnsWithoutManifest(t *testing.T) {
+	v := NewValidator()
+	diagnostics := v.ValidatePermissions(&Skill{ID: "helper", FilePath: "/skills/helper/SKILL.md", Always: true})
+	if len(diagnostics) != 1 || diagnostics[0].Severity != DiagnosticWarning || diagnostics[0].Field != "permissions" {
+		t.Fatalf("unexpected diagnostics: %+v", diagnostics)
+	}
+	if diagnostics := v.ValidatePermissions(&Skill{ID: "github", FilePath: "builtin://github"}); len(diagnostics) != 0 {
+		t.Fatalf("expected builtin skills to need no manifest, got %+v", diagnostics)
+	}
+	diagnostics = v.ValidatePermissions(&Skill{
+		ID:          "fetcher",
+		FilePath:    "/skills/fetcher/SKILL.md",
+		Permissions: &SkillPermissions{Tools: []string{"web_fetch", " "}},
+	})
+	if len(diagnostics) != 2 || diagnostics[0].Severity != DiagnosticInfo || diagnostics[1].Severity != DiagnosticError {
+		t.Fatalf("unexpected diagnostics: %+v", diagnostics)
+	}
+}
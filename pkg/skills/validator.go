@@ -45,6 +45,9 @@ func (v *Validator) Validate(skill *Skill) []Diagnostic {
 	// Validate always flag with enabled setting.
 	diagnostics = append(diagnostics, v.ValidateAlways(skill)...)
 
+	// Validate permission manifest
+	diagnostics = append(diagnostics, v.ValidatePermissions(skill)...)
+
 	return diagnostics
 }
 
@@ -253,6 +256,51 @@ func (v *Validator) ValidateAlways(skill *Skill) []Diagnostic {
 	return diagnostics
 }
 
+// ValidatePermissions validates the skill's permission manifest.
+func (v *Validator) ValidatePermissions(skill *Skill) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	if skill.Trusted() {
+		return diagnostics
+	}
+
+	if skill.Permissions == nil {
+		message := "Skill has no permissions manifest; it is limited to read-only tools"
+		if skill.Always {
+			message = "Always-on skill has no permissions manifest; every turn is limited to read-only tools"
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: DiagnosticWarning,
+			Message:  message,
+			Field:    "permissions",
+			Fixable:  true,
+		})
+		return diagnostics
+	}
+
+	for _, tool := range skill.Permissions.Tools {
+		if strings.TrimSpace(tool) == "" {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: DiagnosticError,
+				Message:  "Permission tool names cannot be empty",
+				Field:    "permissions.tools",
+				Fixable:  true,
+			})
+			continue
+		}
+		if perm, ok := ToolPermission(tool); ok {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: DiagnosticInfo,
+				Message:  fmt.Sprintf("Tool %s is covered by the %s permission", tool, perm),
+				Field:    "permissions.tools",
+				Fixable:  true,
+			})
+		}
+	}
+
+	return diagnostics
+}
+
 // ValidateFile validates a skill file before loading.
 func (v *Validator) ValidateFile(path string) []Diagnostic {
 	var diagnostics []Diagnostic
@@ -0,0 +1,387 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nekobot/pkg/storage/ent/approvalrequest"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// ApprovalRequestCreate is the builder for creating a ApprovalRequest entity.
+type ApprovalRequestCreate struct {
+	config
+	mutation *ApprovalRequestMutation
+	hooks    []Hook
+}
+
+// SetToolName sets the "tool_name" field.
+func (_c *ApprovalRequestCreate) SetToolName(v string) *ApprovalRequestCreate {
+	_c.mutation.SetToolName(v)
+	return _c
+}
+
+// SetArguments sets the "arguments" field.
+func (_c *ApprovalRequestCreate) SetArguments(v map[string]interface{}) *ApprovalRequestCreate {
+	_c.mutation.SetArguments(v)
+	return _c
+}
+
+// SetSessionID sets the "session_id" field.
+func (_c *ApprovalRequestCreate) SetSessionID(v string) *ApprovalRequestCreate {
+	_c.mutation.SetSessionID(v)
+	return _c
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (_c *ApprovalRequestCreate) SetNillableSessionID(v *string) *ApprovalRequestCreate {
+	if v != nil {
+		_c.SetSessionID(*v)
+	}
+	return _c
+}
+
+// SetState sets the "state" field.
+func (_c *ApprovalRequestCreate) SetState(v approvalrequest.State) *ApprovalRequestCreate {
+	_c.mutation.SetState(v)
+	return _c
+}
+
+// SetNillableState sets the "state" field if the given value is not nil.
+func (_c *ApprovalRequestCreate) SetNillableState(v *approvalrequest.State) *ApprovalRequestCreate {
+	if v != nil {
+		_c.SetState(*v)
+	}
+	return _c
+}
+
+// SetReason sets the "reason" field.
+func (_c *ApprovalRequestCreate) SetReason(v string) *ApprovalRequestCreate {
+	_c.mutation.SetReason(v)
+	return _c
+}
+
+// SetNillableReason sets the "reason" field if the given value is not nil.
+func (_c *ApprovalRequestCreate) SetNillableReason(v *string) *ApprovalRequestCreate {
+	if v != nil {
+		_c.SetReason(*v)
+	}
+	return _c
+}
+
+// SetDecidedBy sets the "decided_by" field.
+func (_c *ApprovalRequestCreate) SetDecidedBy(v string) *ApprovalRequestCreate {
+	_c.mutation.SetDecidedBy(v)
+	return _c
+}
+
+// SetNillableDecidedBy sets the "decided_by" field if the given value is not nil.
+func (_c *ApprovalRequestCreate) SetNillableDecidedBy(v *string) *ApprovalRequestCreate {
+	if v != nil {
+		_c.SetDecidedBy(*v)
+	}
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *ApprovalRequestCreate) SetCreatedAt(v time.Time) *ApprovalRequestCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *ApprovalRequestCreate) SetNillableCreatedAt(v *time.Time) *ApprovalRequestCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_c *ApprovalRequestCreate) SetExpiresAt(v time.Time) *ApprovalRequestCreate {
+	_c.mutation.SetExpiresAt(v)
+	return _c
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_c *ApprovalRequestCreate) SetNillableExpiresAt(v *time.Time) *ApprovalRequestCreate {
+	if v != nil {
+		_c.SetExpiresAt(*v)
+	}
+	return _c
+}
+
+// SetDecidedAt sets the "decided_at" field.
+func (_c *ApprovalRequestCreate) SetDecidedAt(v time.Time) *ApprovalRequestCreate {
+	_c.mutation.SetDecidedAt(v)
+	return _c
+}
+
+// SetNillableDecidedAt sets the "decided_at" field if the given value is not nil.
+func (_c *ApprovalRequestCreate) SetNillableDecidedAt(v *time.Time) *ApprovalRequestCreate {
+	if v != nil {
+		_c.SetDecidedAt(*v)
+	}
+	return _c
+}
+
+// SetID sets the "id" field.
+func (_c *ApprovalRequestCreate) SetID(v string) *ApprovalRequestCreate {
+	_c.mutation.SetID(v)
+	return _c
+}
+
+// Mutation returns the ApprovalRequestMutation object of the builder.
+func (_c *ApprovalRequestCreate) Mutation() *ApprovalRequestMutation {
+	return _c.mutation
+}
+
+// Save creates the ApprovalRequest in the database.
+func (_c *ApprovalRequestCreate) Save(ctx context.Context) (*ApprovalRequest, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *ApprovalRequestCreate) SaveX(ctx context.Context) *ApprovalRequest {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ApprovalRequestCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ApprovalRequestCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *ApprovalRequestCreate) defaults() {
+	if _, ok := _c.mutation.SessionID(); !ok {
+		v := approvalrequest.DefaultSessionID
+		_c.mutation.SetSessionID(v)
+	}
+	if _, ok := _c.mutation.State(); !ok {
+		v := approvalrequest.DefaultState
+		_c.mutation.SetState(v)
+	}
+	if _, ok := _c.mutation.Reason(); !ok {
+		v := approvalrequest.DefaultReason
+		_c.mutation.SetReason(v)
+	}
+	if _, ok := _c.mutation.DecidedBy(); !ok {
+		v := approvalrequest.DefaultDecidedBy
+		_c.mutation.SetDecidedBy(v)
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := approvalrequest.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *ApprovalRequestCreate) check() error {
+	if _, ok := _c.mutation.ToolName(); !ok {
+		return &ValidationError{Name: "tool_name", err: errors.New(`ent: missing required field "ApprovalRequest.tool_name"`)}
+	}
+	if v, ok := _c.mutation.ToolName(); ok {
+		if err := approvalrequest.ToolNameValidator(v); err != nil {
+			return &ValidationError{Name: "tool_name", err: fmt.Errorf(`ent: validator failed for field "ApprovalRequest.tool_name": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.SessionID(); !ok {
+		return &ValidationError{Name: "session_id", err: errors.New(`ent: missing required field "ApprovalRequest.session_id"`)}
+	}
+	if _, ok := _c.mutation.State(); !ok {
+		return &ValidationError{Name: "state", err: errors.New(`ent: missing required field "ApprovalRequest.state"`)}
+	}
+	if v, ok := _c.mutation.State(); ok {
+		if err := approvalrequest.StateValidator(v); err != nil {
+			return &ValidationError{Name: "state", err: fmt.Errorf(`ent: validator failed for field "ApprovalRequest.state": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Reason(); !ok {
+		return &ValidationError{Name: "reason", err: errors.New(`ent: missing required field "ApprovalRequest.reason"`)}
+	}
+	if _, ok := _c.mutation.DecidedBy(); !ok {
+		return &ValidationError{Name: "decided_by", err: errors.New(`ent: missing required field "ApprovalRequest.decided_by"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "ApprovalRequest.created_at"`)}
+	}
+	if v, ok := _c.mutation.ID(); ok {
+		if err := approvalrequest.IDValidator(v); err != nil {
+			return &ValidationError{Name: "id", err: fmt.Errorf(`ent: validator failed for field "ApprovalRequest.id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_c *ApprovalRequestCreate) sqlSave(ctx context.Context) (*ApprovalRequest, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(string); ok {
+			_node.ID = id
+		} else {
+			return nil, fmt.Errorf("unexpected ApprovalRequest.ID type: %T", _spec.ID.Value)
+		}
+	}
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *ApprovalRequestCreate) createSpec() (*ApprovalRequest, *sqlgraph.CreateSpec) {
+	var (
+		_node = &ApprovalRequest{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(approvalrequest.Table, sqlgraph.NewFieldSpec(approvalrequest.FieldID, field.TypeString))
+	)
+	if id, ok := _c.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := _c.mutation.ToolName(); ok {
+		_spec.SetField(approvalrequest.FieldToolName, field.TypeString, value)
+		_node.ToolName = value
+	}
+	if value, ok := _c.mutation.Arguments(); ok {
+		_spec.SetField(approvalrequest.FieldArguments, field.TypeJSON, value)
+		_node.Arguments = value
+	}
+	if value, ok := _c.mutation.SessionID(); ok {
+		_spec.SetField(approvalrequest.FieldSessionID, field.TypeString, value)
+		_node.SessionID = value
+	}
+	if value, ok := _c.mutation.State(); ok {
+		_spec.SetField(approvalrequest.FieldState, field.TypeEnum, value)
+		_node.State = value
+	}
+	if value, ok := _c.mutation.Reason(); ok {
+		_spec.SetField(approvalrequest.FieldReason, field.TypeString, value)
+		_node.Reason = value
+	}
+	if value, ok := _c.mutation.DecidedBy(); ok {
+		_spec.SetField(approvalrequest.FieldDecidedBy, field.TypeString, value)
+		_node.DecidedBy = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(approvalrequest.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.ExpiresAt(); ok {
+		_spec.SetField(approvalrequest.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = &value
+	}
+	if value, ok := _c.mutation.DecidedAt(); ok {
+		_spec.SetField(approvalrequest.FieldDecidedAt, field.TypeTime, value)
+		_node.DecidedAt = &value
+	}
+	return _node, _spec
+}
+
+// ApprovalRequestCreateBulk is the builder for creating many ApprovalRequest entities in bulk.
+type ApprovalRequestCreateBulk struct {
+	config
+	err      error
+	builders []*ApprovalRequestCreate
+}
+
+// Save creates the ApprovalRequest entities in the database.
+func (_c *ApprovalRequestCreateBulk) Save(ctx context.Context) ([]*ApprovalRequest, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*ApprovalRequest, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*ApprovalRequestMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *ApprovalRequestCreateBulk) SaveX(ctx context.Context) []*ApprovalRequest {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ApprovalRequestCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ApprovalRequestCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
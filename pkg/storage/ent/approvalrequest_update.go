@@ -0,0 +1,522 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nekobot/pkg/storage/ent/approvalrequest"
+	"nekobot/pkg/storage/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// ApprovalRequestUpdate is the builder for updating ApprovalRequest entities.
+type ApprovalRequestUpdate struct {
+	config
+	hooks    []Hook
+	mutation *ApprovalRequestMutation
+}
+
+// Where appends a list predicates to the ApprovalRequestUpdate builder.
+func (_u *ApprovalRequestUpdate) Where(ps ...predicate.ApprovalRequest) *ApprovalRequestUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetToolName sets the "tool_name" field.
+func (_u *ApprovalRequestUpdate) SetToolName(v string) *ApprovalRequestUpdate {
+	_u.mutation.SetToolName(v)
+	return _u
+}
+
+// SetNillableToolName sets the "tool_name" field if the given value is not nil.
+func (_u *ApprovalRequestUpdate) SetNillableToolName(v *string) *ApprovalRequestUpdate {
+	if v != nil {
+		_u.SetToolName(*v)
+	}
+	return _u
+}
+
+// SetArguments sets the "arguments" field.
+func (_u *ApprovalRequestUpdate) SetArguments(v map[string]interface{}) *ApprovalRequestUpdate {
+	_u.mutation.SetArguments(v)
+	return _u
+}
+
+// ClearArguments clears the value of the "arguments" field.
+func (_u *ApprovalRequestUpdate) ClearArguments() *ApprovalRequestUpdate {
+	_u.mutation.ClearArguments()
+	return _u
+}
+
+// SetSessionID sets the "session_id" field.
+func (_u *ApprovalRequestUpdate) SetSessionID(v string) *ApprovalRequestUpdate {
+	_u.mutation.SetSessionID(v)
+	return _u
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (_u *ApprovalRequestUpdate) SetNillableSessionID(v *string) *ApprovalRequestUpdate {
+	if v != nil {
+		_u.SetSessionID(*v)
+	}
+	return _u
+}
+
+// SetState sets the "state" field.
+func (_u *ApprovalRequestUpdate) SetState(v approvalrequest.State) *ApprovalRequestUpdate {
+	_u.mutation.SetState(v)
+	return _u
+}
+
+// SetNillableState sets the "state" field if the given value is not nil.
+func (_u *ApprovalRequestUpdate) SetNillableState(v *approvalrequest.State) *ApprovalRequestUpdate {
+	if v != nil {
+		_u.SetState(*v)
+	}
+	return _u
+}
+
+// SetReason sets the "reason" field.
+func (_u *ApprovalRequestUpdate) SetReason(v string) *ApprovalRequestUpdate {
+	_u.mutation.SetReason(v)
+	return _u
+}
+
+// SetNillableReason sets the "reason" field if the given value is not nil.
+func (_u *ApprovalRequestUpdate) SetNillableReason(v *string) *ApprovalRequestUpdate {
+	if v != nil {
+		_u.SetReason(*v)
+	}
+	return _u
+}
+
+// SetDecidedBy sets the "decided_by" field.
+func (_u *ApprovalRequestUpdate) SetDecidedBy(v string) *ApprovalRequestUpdate {
+	_u.mutation.SetDecidedBy(v)
+	return _u
+}
+
+// SetNillableDecidedBy sets the "decided_by" field if the given value is not nil.
+func (_u *ApprovalRequestUpdate) SetNillableDecidedBy(v *string) *ApprovalRequestUpdate {
+	if v != nil {
+		_u.SetDecidedBy(*v)
+	}
+	return _u
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *ApprovalRequestUpdate) SetExpiresAt(v time.Time) *ApprovalRequestUpdate {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *ApprovalRequestUpdate) SetNillableExpiresAt(v *time.Time) *ApprovalRequestUpdate {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (_u *ApprovalRequestUpdate) ClearExpiresAt() *ApprovalRequestUpdate {
+	_u.mutation.ClearExpiresAt()
+	return _u
+}
+
+// SetDecidedAt sets the "decided_at" field.
+func (_u *ApprovalRequestUpdate) SetDecidedAt(v time.Time) *ApprovalRequestUpdate {
+	_u.mutation.SetDecidedAt(v)
+	return _u
+}
+
+// SetNillableDecidedAt sets the "decided_at" field if the given value is not nil.
+func (_u *ApprovalRequestUpdate) SetNillableDecidedAt(v *time.Time) *ApprovalRequestUpdate {
+	if v != nil {
+		_u.SetDecidedAt(*v)
+	}
+	return _u
+}
+
+// ClearDecidedAt clears the value of the "decided_at" field.
+func (_u *ApprovalRequestUpdate) ClearDecidedAt() *ApprovalRequestUpdate {
+	_u.mutation.ClearDecidedAt()
+	return _u
+}
+
+// Mutation returns the ApprovalRequestMutation object of the builder.
+func (_u *ApprovalRequestUpdate) Mutation() *ApprovalRequestMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *ApprovalRequestUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ApprovalRequestUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *ApprovalRequestUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ApprovalRequestUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *ApprovalRequestUpdate) check() error {
+	if v, ok := _u.mutation.ToolName(); ok {
+		if err := approvalrequest.ToolNameValidator(v); err != nil {
+			return &ValidationError{Name: "tool_name", err: fmt.Errorf(`ent: validator failed for field "ApprovalRequest.tool_name": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.State(); ok {
+		if err := approvalrequest.StateValidator(v); err != nil {
+			return &ValidationError{Name: "state", err: fmt.Errorf(`ent: validator failed for field "ApprovalRequest.state": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *ApprovalRequestUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(approvalrequest.Table, approvalrequest.Columns, sqlgraph.NewFieldSpec(approvalrequest.FieldID, field.TypeString))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.ToolName(); ok {
+		_spec.SetField(approvalrequest.FieldToolName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Arguments(); ok {
+		_spec.SetField(approvalrequest.FieldArguments, field.TypeJSON, value)
+	}
+	if _u.mutation.ArgumentsCleared() {
+		_spec.ClearField(approvalrequest.FieldArguments, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.SessionID(); ok {
+		_spec.SetField(approvalrequest.FieldSessionID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.State(); ok {
+		_spec.SetField(approvalrequest.FieldState, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.Reason(); ok {
+		_spec.SetField(approvalrequest.FieldReason, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.DecidedBy(); ok {
+		_spec.SetField(approvalrequest.FieldDecidedBy, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(approvalrequest.FieldExpiresAt, field.TypeTime, value)
+	}
+	if _u.mutation.ExpiresAtCleared() {
+		_spec.ClearField(approvalrequest.FieldExpiresAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.DecidedAt(); ok {
+		_spec.SetField(approvalrequest.FieldDecidedAt, field.TypeTime, value)
+	}
+	if _u.mutation.DecidedAtCleared() {
+		_spec.ClearField(approvalrequest.FieldDecidedAt, field.TypeTime)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{approvalrequest.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// ApprovalRequestUpdateOne is the builder for updating a single ApprovalRequest entity.
+type ApprovalRequestUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *ApprovalRequestMutation
+}
+
+// SetToolName sets the "tool_name" field.
+func (_u *ApprovalRequestUpdateOne) SetToolName(v string) *ApprovalRequestUpdateOne {
+	_u.mutation.SetToolName(v)
+	return _u
+}
+
+// SetNillableToolName sets the "tool_name" field if the given value is not nil.
+func (_u *ApprovalRequestUpdateOne) SetNillableToolName(v *string) *ApprovalRequestUpdateOne {
+	if v != nil {
+		_u.SetToolName(*v)
+	}
+	return _u
+}
+
+// SetArguments sets the "arguments" field.
+func (_u *ApprovalRequestUpdateOne) SetArguments(v map[string]interface{}) *ApprovalRequestUpdateOne {
+	_u.mutation.SetArguments(v)
+	return _u
+}
+
+// ClearArguments clears the value of the "arguments" field.
+func (_u *ApprovalRequestUpdateOne) ClearArguments() *ApprovalRequestUpdateOne {
+	_u.mutation.ClearArguments()
+	return _u
+}
+
+// SetSessionID sets the "session_id" field.
+func (_u *ApprovalRequestUpdateOne) SetSessionID(v string) *ApprovalRequestUpdateOne {
+	_u.mutation.SetSessionID(v)
+	return _u
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (_u *ApprovalRequestUpdateOne) SetNillableSessionID(v *string) *ApprovalRequestUpdateOne {
+	if v != nil {
+		_u.SetSessionID(*v)
+	}
+	return _u
+}
+
+// SetState sets the "state" field.
+func (_u *ApprovalRequestUpdateOne) SetState(v approvalrequest.State) *ApprovalRequestUpdateOne {
+	_u.mutation.SetState(v)
+	return _u
+}
+
+// SetNillableState sets the "state" field if the given value is not nil.
+func (_u *ApprovalRequestUpdateOne) SetNillableState(v *approvalrequest.State) *ApprovalRequestUpdateOne {
+	if v != nil {
+		_u.SetState(*v)
+	}
+	return _u
+}
+
+// SetReason sets the "reason" field.
+func (_u *ApprovalRequestUpdateOne) SetReason(v string) *ApprovalRequestUpdateOne {
+	_u.mutation.SetReason(v)
+	return _u
+}
+
+// SetNillableReason sets the "reason" field if the given value is not nil.
+func (_u *ApprovalRequestUpdateOne) SetNillableReason(v *string) *ApprovalRequestUpdateOne {
+	if v != nil {
+		_u.SetReason(*v)
+	}
+	return _u
+}
+
+// SetDecidedBy sets the "decided_by" field.
+func (_u *ApprovalRequestUpdateOne) SetDecidedBy(v string) *ApprovalRequestUpdateOne {
+	_u.mutation.SetDecidedBy(v)
+	return _u
+}
+
+// SetNillableDecidedBy sets the "decided_by" field if the given value is not nil.
+func (_u *ApprovalRequestUpdateOne) SetNillableDecidedBy(v *string) *ApprovalRequestUpdateOne {
+	if v != nil {
+		_u.SetDecidedBy(*v)
+	}
+	return _u
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *ApprovalRequestUpdateOne) SetExpiresAt(v time.Time) *ApprovalRequestUpdateOne {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *ApprovalRequestUpdateOne) SetNillableExpiresAt(v *time.Time) *ApprovalRequestUpdateOne {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (_u *ApprovalRequestUpdateOne) ClearExpiresAt() *ApprovalRequestUpdateOne {
+	_u.mutation.ClearExpiresAt()
+	return _u
+}
+
+// SetDecidedAt sets the "decided_at" field.
+func (_u *ApprovalRequestUpdateOne) SetDecidedAt(v time.Time) *ApprovalRequestUpdateOne {
+	_u.mutation.SetDecidedAt(v)
+	return _u
+}
+
+// SetNillableDecidedAt sets the "decided_at" field if the given value is not nil.
+func (_u *ApprovalRequestUpdateOne) SetNillableDecidedAt(v *time.Time) *ApprovalRequestUpdateOne {
+	if v != nil {
+		_u.SetDecidedAt(*v)
+	}
+	return _u
+}
+
+// ClearDecidedAt clears the value of the "decided_at" field.
+func (_u *ApprovalRequestUpdateOne) ClearDecidedAt() *ApprovalRequestUpdateOne {
+	_u.mutation.ClearDecidedAt()
+	return _u
+}
+
+// Mutation returns the ApprovalRequestMutation object of the builder.
+func (_u *ApprovalRequestUpdateOne) Mutation() *ApprovalRequestMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the ApprovalRequestUpdate builder.
+func (_u *ApprovalRequestUpdateOne) Where(ps ...predicate.ApprovalRequest) *ApprovalRequestUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *ApprovalRequestUpdateOne) Select(field string, fields ...string) *ApprovalRequestUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated ApprovalRequest entity.
+func (_u *ApprovalRequestUpdateOne) Save(ctx context.Context) (*ApprovalRequest, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ApprovalRequestUpdateOne) SaveX(ctx context.Context) *ApprovalRequest {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *ApprovalRequestUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ApprovalRequestUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *ApprovalRequestUpdateOne) check() error {
+	if v, ok := _u.mutation.ToolName(); ok {
+		if err := approvalrequest.ToolNameValidator(v); err != nil {
+			return &ValidationError{Name: "tool_name", err: fmt.Errorf(`ent: validator failed for field "ApprovalRequest.tool_name": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.State(); ok {
+		if err := approvalrequest.StateValidator(v); err != nil {
+			return &ValidationError{Name: "state", err: fmt.Errorf(`ent: validator failed for field "ApprovalRequest.state": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *ApprovalRequestUpdateOne) sqlSave(ctx context.Context) (_node *ApprovalRequest, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(approvalrequest.Table, approvalrequest.Columns, sqlgraph.NewFieldSpec(approvalrequest.FieldID, field.TypeString))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "ApprovalRequest.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, approvalrequest.FieldID)
+		for _, f := range fields {
+			if !approvalrequest.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != approvalrequest.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.ToolName(); ok {
+		_spec.SetField(approvalrequest.FieldToolName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Arguments(); ok {
+		_spec.SetField(approvalrequest.FieldArguments, field.TypeJSON, value)
+	}
+	if _u.mutation.ArgumentsCleared() {
+		_spec.ClearField(approvalrequest.FieldArguments, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.SessionID(); ok {
+		_spec.SetField(approvalrequest.FieldSessionID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.State(); ok {
+		_spec.SetField(approvalrequest.FieldState, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.Reason(); ok {
+		_spec.SetField(approvalrequest.FieldReason, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.DecidedBy(); ok {
+		_spec.SetField(approvalrequest.FieldDecidedBy, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(approvalrequest.FieldExpiresAt, field.TypeTime, value)
+	}
+	if _u.mutation.ExpiresAtCleared() {
+		_spec.ClearField(approvalrequest.FieldExpiresAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.DecidedAt(); ok {
+		_spec.SetField(approvalrequest.FieldDecidedAt, field.TypeTime, value)
+	}
+	if _u.mutation.DecidedAtCleared() {
+		_spec.ClearField(approvalrequest.FieldDecidedAt, field.TypeTime)
+	}
+	_node = &ApprovalRequest{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{approvalrequest.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
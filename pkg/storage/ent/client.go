@@ -13,17 +13,24 @@ import (
 
 	"nekobot/pkg/storage/ent/accountbinding"
 	"nekobot/pkg/storage/ent/agentruntime"
+	"nekobot/pkg/storage/ent/apikey"
+	"nekobot/pkg/storage/ent/approvalrequest"
 	"nekobot/pkg/storage/ent/attachtoken"
+	"nekobot/pkg/storage/ent/auditlog"
 	"nekobot/pkg/storage/ent/channelaccount"
 	"nekobot/pkg/storage/ent/collaborationevent"
 	"nekobot/pkg/storage/ent/configsection"
 	"nekobot/pkg/storage/ent/cronjob"
+	"nekobot/pkg/storage/ent/feedback"
+	"nekobot/pkg/storage/ent/feedentry"
+	"nekobot/pkg/storage/ent/feedsubscription"
 	"nekobot/pkg/storage/ent/idempotencyrecord"
 	"nekobot/pkg/storage/ent/membership"
 	"nekobot/pkg/storage/ent/modelcatalog"
 	"nekobot/pkg/storage/ent/modelroute"
 	"nekobot/pkg/storage/ent/notificationbinding"
 	"nekobot/pkg/storage/ent/notificationroute"
+	"nekobot/pkg/storage/ent/notifyrule"
 	"nekobot/pkg/storage/ent/permissionrule"
 	"nekobot/pkg/storage/ent/prompt"
 	"nekobot/pkg/storage/ent/promptbinding"
@@ -33,6 +40,7 @@ import (
 	"nekobot/pkg/storage/ent/tenant"
 	"nekobot/pkg/storage/ent/toolevent"
 	"nekobot/pkg/storage/ent/toolsession"
+	"nekobot/pkg/storage/ent/usagerecord"
 	"nekobot/pkg/storage/ent/user"
 
 	"entgo.io/ent"
@@ -46,12 +54,18 @@ type Client struct {
 	config
 	// Schema is the client for creating, migrating and dropping schema.
 	Schema *migrate.Schema
+	// APIKey is the client for interacting with the APIKey builders.
+	APIKey *APIKeyClient
 	// AccountBinding is the client for interacting with the AccountBinding builders.
 	AccountBinding *AccountBindingClient
 	// AgentRuntime is the client for interacting with the AgentRuntime builders.
 	AgentRuntime *AgentRuntimeClient
+	// ApprovalRequest is the client for interacting with the ApprovalRequest builders.
+	ApprovalRequest *ApprovalRequestClient
 	// AttachToken is the client for interacting with the AttachToken builders.
 	AttachToken *AttachTokenClient
+	// AuditLog is the client for interacting with the AuditLog builders.
+	AuditLog *AuditLogClient
 	// ChannelAccount is the client for interacting with the ChannelAccount builders.
 	ChannelAccount *ChannelAccountClient
 	// CollaborationEvent is the client for interacting with the CollaborationEvent builders.
@@ -60,6 +74,12 @@ type Client struct {
 	ConfigSection *ConfigSectionClient
 	// CronJob is the client for interacting with the CronJob builders.
 	CronJob *CronJobClient
+	// FeedEntry is the client for interacting with the FeedEntry builders.
+	FeedEntry *FeedEntryClient
+	// FeedSubscription is the client for interacting with the FeedSubscription builders.
+	FeedSubscription *FeedSubscriptionClient
+	// Feedback is the client for interacting with the Feedback builders.
+	Feedback *FeedbackClient
 	// IdempotencyRecord is the client for interacting with the IdempotencyRecord builders.
 	IdempotencyRecord *IdempotencyRecordClient
 	// Membership is the client for interacting with the Membership builders.
@@ -72,6 +92,8 @@ type Client struct {
 	NotificationBinding *NotificationBindingClient
 	// NotificationRoute is the client for interacting with the NotificationRoute builders.
 	NotificationRoute *NotificationRouteClient
+	// NotifyRule is the client for interacting with the NotifyRule builders.
+	NotifyRule *NotifyRuleClient
 	// PermissionRule is the client for interacting with the PermissionRule builders.
 	PermissionRule *PermissionRuleClient
 	// Prompt is the client for interacting with the Prompt builders.
@@ -90,6 +112,8 @@ type Client struct {
 	ToolEvent *ToolEventClient
 	// ToolSession is the client for interacting with the ToolSession builders.
 	ToolSession *ToolSessionClient
+	// UsageRecord is the client for interacting with the UsageRecord builders.
+	UsageRecord *UsageRecordClient
 	// User is the client for interacting with the User builders.
 	User *UserClient
 }
@@ -103,19 +127,26 @@ func NewClient(opts ...Option) *Client {
 
 func (c *Client) init() {
 	c.Schema = migrate.NewSchema(c.driver)
+	c.APIKey = NewAPIKeyClient(c.config)
 	c.AccountBinding = NewAccountBindingClient(c.config)
 	c.AgentRuntime = NewAgentRuntimeClient(c.config)
+	c.ApprovalRequest = NewApprovalRequestClient(c.config)
 	c.AttachToken = NewAttachTokenClient(c.config)
+	c.AuditLog = NewAuditLogClient(c.config)
 	c.ChannelAccount = NewChannelAccountClient(c.config)
 	c.CollaborationEvent = NewCollaborationEventClient(c.config)
 	c.ConfigSection = NewConfigSectionClient(c.config)
 	c.CronJob = NewCronJobClient(c.config)
+	c.FeedEntry = NewFeedEntryClient(c.config)
+	c.FeedSubscription = NewFeedSubscriptionClient(c.config)
+	c.Feedback = NewFeedbackClient(c.config)
 	c.IdempotencyRecord = NewIdempotencyRecordClient(c.config)
 	c.Membership = NewMembershipClient(c.config)
 	c.ModelCatalog = NewModelCatalogClient(c.config)
 	c.ModelRoute = NewModelRouteClient(c.config)
 	c.NotificationBinding = NewNotificationBindingClient(c.config)
 	c.NotificationRoute = NewNotificationRouteClient(c.config)
+	c.NotifyRule = NewNotifyRuleClient(c.config)
 	c.PermissionRule = NewPermissionRuleClient(c.config)
 	c.Prompt = NewPromptClient(c.config)
 	c.PromptBinding = NewPromptBindingClient(c.config)
@@ -125,6 +156,7 @@ func (c *Client) init() {
 	c.Tenant = NewTenantClient(c.config)
 	c.ToolEvent = NewToolEventClient(c.config)
 	c.ToolSession = NewToolSessionClient(c.config)
+	c.UsageRecord = NewUsageRecordClient(c.config)
 	c.User = NewUserClient(c.config)
 }
 
@@ -218,19 +250,26 @@ func (c *Client) Tx(ctx context.Context) (*Tx, error) {
 	return &Tx{
 		ctx:                 ctx,
 		config:              cfg,
+		APIKey:              NewAPIKeyClient(cfg),
 		AccountBinding:      NewAccountBindingClient(cfg),
 		AgentRuntime:        NewAgentRuntimeClient(cfg),
+		ApprovalRequest:     NewApprovalRequestClient(cfg),
 		AttachToken:         NewAttachTokenClient(cfg),
+		AuditLog:            NewAuditLogClient(cfg),
 		ChannelAccount:      NewChannelAccountClient(cfg),
 		CollaborationEvent:  NewCollaborationEventClient(cfg),
 		ConfigSection:       NewConfigSectionClient(cfg),
 		CronJob:             NewCronJobClient(cfg),
+		FeedEntry:           NewFeedEntryClient(cfg),
+		FeedSubscription:    NewFeedSubscriptionClient(cfg),
+		Feedback:            NewFeedbackClient(cfg),
 		IdempotencyRecord:   NewIdempotencyRecordClient(cfg),
 		Membership:          NewMembershipClient(cfg),
 		ModelCatalog:        NewModelCatalogClient(cfg),
 		ModelRoute:          NewModelRouteClient(cfg),
 		NotificationBinding: NewNotificationBindingClient(cfg),
 		NotificationRoute:   NewNotificationRouteClient(cfg),
+		NotifyRule:          NewNotifyRuleClient(cfg),
 		PermissionRule:      NewPermissionRuleClient(cfg),
 		Prompt:              NewPromptClient(cfg),
 		PromptBinding:       NewPromptBindingClient(cfg),
@@ -240,6 +279,7 @@ func (c *Client) Tx(ctx context.Context) (*Tx, error) {
 		Tenant:              NewTenantClient(cfg),
 		ToolEvent:           NewToolEventClient(cfg),
 		ToolSession:         NewToolSessionClient(cfg),
+		UsageRecord:         NewUsageRecordClient(cfg),
 		User:                NewUserClient(cfg),
 	}, nil
 }
@@ -260,19 +300,26 @@ func (c *Client) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error)
 	return &Tx{
 		ctx:                 ctx,
 		config:              cfg,
+		APIKey:              NewAPIKeyClient(cfg),
 		AccountBinding:      NewAccountBindingClient(cfg),
 		AgentRuntime:        NewAgentRuntimeClient(cfg),
+		ApprovalRequest:     NewApprovalRequestClient(cfg),
 		AttachToken:         NewAttachTokenClient(cfg),
+		AuditLog:            NewAuditLogClient(cfg),
 		ChannelAccount:      NewChannelAccountClient(cfg),
 		CollaborationEvent:  NewCollaborationEventClient(cfg),
 		ConfigSection:       NewConfigSectionClient(cfg),
 		CronJob:             NewCronJobClient(cfg),
+		FeedEntry:           NewFeedEntryClient(cfg),
+		FeedSubscription:    NewFeedSubscriptionClient(cfg),
+		Feedback:            NewFeedbackClient(cfg),
 		IdempotencyRecord:   NewIdempotencyRecordClient(cfg),
 		Membership:          NewMembershipClient(cfg),
 		ModelCatalog:        NewModelCatalogClient(cfg),
 		ModelRoute:          NewModelRouteClient(cfg),
 		NotificationBinding: NewNotificationBindingClient(cfg),
 		NotificationRoute:   NewNotificationRouteClient(cfg),
+		NotifyRule:          NewNotifyRuleClient(cfg),
 		PermissionRule:      NewPermissionRuleClient(cfg),
 		Prompt:              NewPromptClient(cfg),
 		PromptBinding:       NewPromptBindingClient(cfg),
@@ -282,6 +329,7 @@ func (c *Client) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error)
 		Tenant:              NewTenantClient(cfg),
 		ToolEvent:           NewToolEventClient(cfg),
 		ToolSession:         NewToolSessionClient(cfg),
+		UsageRecord:         NewUsageRecordClient(cfg),
 		User:                NewUserClient(cfg),
 	}, nil
 }
@@ -289,7 +337,7 @@ func (c *Client) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error)
 // Debug returns a new debug-client. It's used to get verbose logging on specific operations.
 //
 //	client.Debug().
-//		AccountBinding.
+//		APIKey.
 //		Query().
 //		Count(ctx)
 func (c *Client) Debug() *Client {
@@ -312,11 +360,12 @@ func (c *Client) Close() error {
 // In order to add hooks to a specific client, call: `client.Node.Use(...)`.
 func (c *Client) Use(hooks ...Hook) {
 	for _, n := range []interface{ Use(...Hook) }{
-		c.AccountBinding, c.AgentRuntime, c.AttachToken, c.ChannelAccount,
-		c.CollaborationEvent, c.ConfigSection, c.CronJob, c.IdempotencyRecord, c.Membership, c.ModelCatalog,
-		c.ModelRoute, c.NotificationBinding, c.NotificationRoute, c.PermissionRule,
-		c.Prompt, c.PromptBinding, c.Provider, c.Run, c.RunStep, c.Tenant, c.ToolEvent,
-		c.ToolSession, c.User,
+		c.APIKey, c.AccountBinding, c.AgentRuntime, c.ApprovalRequest, c.AttachToken,
+		c.AuditLog, c.ChannelAccount, c.CollaborationEvent, c.ConfigSection, c.CronJob,
+		c.FeedEntry, c.FeedSubscription, c.Feedback, c.IdempotencyRecord, c.Membership,
+		c.ModelCatalog, c.ModelRoute, c.NotificationBinding, c.NotificationRoute,
+		c.NotifyRule, c.PermissionRule, c.Prompt, c.PromptBinding, c.Provider, c.Run,
+		c.RunStep, c.Tenant, c.ToolEvent, c.ToolSession, c.UsageRecord, c.User,
 	} {
 		n.Use(hooks...)
 	}
@@ -326,11 +375,12 @@ func (c *Client) Use(hooks ...Hook) {
 // In order to add interceptors to a specific client, call: `client.Node.Intercept(...)`.
 func (c *Client) Intercept(interceptors ...Interceptor) {
 	for _, n := range []interface{ Intercept(...Interceptor) }{
-		c.AccountBinding, c.AgentRuntime, c.AttachToken, c.ChannelAccount,
-		c.CollaborationEvent, c.ConfigSection, c.CronJob, c.IdempotencyRecord, c.Membership, c.ModelCatalog,
-		c.ModelRoute, c.NotificationBinding, c.NotificationRoute, c.PermissionRule,
-		c.Prompt, c.PromptBinding, c.Provider, c.Run, c.RunStep, c.Tenant, c.ToolEvent,
-		c.ToolSession, c.User,
+		c.APIKey, c.AccountBinding, c.AgentRuntime, c.ApprovalRequest, c.AttachToken,
+		c.AuditLog, c.ChannelAccount, c.CollaborationEvent, c.ConfigSection, c.CronJob,
+		c.FeedEntry, c.FeedSubscription, c.Feedback, c.IdempotencyRecord, c.Membership,
+		c.ModelCatalog, c.ModelRoute, c.NotificationBinding, c.NotificationRoute,
+		c.NotifyRule, c.PermissionRule, c.Prompt, c.PromptBinding, c.Provider, c.Run,
+		c.RunStep, c.Tenant, c.ToolEvent, c.ToolSession, c.UsageRecord, c.User,
 	} {
 		n.Intercept(interceptors...)
 	}
@@ -339,12 +389,18 @@ func (c *Client) Intercept(interceptors ...Interceptor) {
 // Mutate implements the ent.Mutator interface.
 func (c *Client) Mutate(ctx context.Context, m Mutation) (Value, error) {
 	switch m := m.(type) {
+	case *APIKeyMutation:
+		return c.APIKey.mutate(ctx, m)
 	case *AccountBindingMutation:
 		return c.AccountBinding.mutate(ctx, m)
 	case *AgentRuntimeMutation:
 		return c.AgentRuntime.mutate(ctx, m)
+	case *ApprovalRequestMutation:
+		return c.ApprovalRequest.mutate(ctx, m)
 	case *AttachTokenMutation:
 		return c.AttachToken.mutate(ctx, m)
+	case *AuditLogMutation:
+		return c.AuditLog.mutate(ctx, m)
 	case *ChannelAccountMutation:
 		return c.ChannelAccount.mutate(ctx, m)
 	case *CollaborationEventMutation:
@@ -353,6 +409,12 @@ func (c *Client) Mutate(ctx context.Context, m Mutation) (Value, error) {
 		return c.ConfigSection.mutate(ctx, m)
 	case *CronJobMutation:
 		return c.CronJob.mutate(ctx, m)
+	case *FeedEntryMutation:
+		return c.FeedEntry.mutate(ctx, m)
+	case *FeedSubscriptionMutation:
+		return c.FeedSubscription.mutate(ctx, m)
+	case *FeedbackMutation:
+		return c.Feedback.mutate(ctx, m)
 	case *IdempotencyRecordMutation:
 		return c.IdempotencyRecord.mutate(ctx, m)
 	case *MembershipMutation:
@@ -365,6 +427,8 @@ func (c *Client) Mutate(ctx context.Context, m Mutation) (Value, error) {
 		return c.NotificationBinding.mutate(ctx, m)
 	case *NotificationRouteMutation:
 		return c.NotificationRoute.mutate(ctx, m)
+	case *NotifyRuleMutation:
+		return c.NotifyRule.mutate(ctx, m)
 	case *PermissionRuleMutation:
 		return c.PermissionRule.mutate(ctx, m)
 	case *PromptMutation:
@@ -383,6 +447,8 @@ func (c *Client) Mutate(ctx context.Context, m Mutation) (Value, error) {
 		return c.ToolEvent.mutate(ctx, m)
 	case *ToolSessionMutation:
 		return c.ToolSession.mutate(ctx, m)
+	case *UsageRecordMutation:
+		return c.UsageRecord.mutate(ctx, m)
 	case *UserMutation:
 		return c.User.mutate(ctx, m)
 	default:
@@ -390,6 +456,139 @@ func (c *Client) Mutate(ctx context.Context, m Mutation) (Value, error) {
 	}
 }
 
+// APIKeyClient is a client for the APIKey schema.
+type APIKeyClient struct {
+	config
+}
+
+// NewAPIKeyClient returns a client for the APIKey from the given config.
+func NewAPIKeyClient(c config) *APIKeyClient {
+	return &APIKeyClient{config: c}
+}
+
+// Use adds a list of mutation hooks to the hooks stack.
+// A call to `Use(f, g, h)` equals to `apikey.Hooks(f(g(h())))`.
+func (c *APIKeyClient) Use(hooks ...Hook) {
+	c.hooks.APIKey = append(c.hooks.APIKey, hooks...)
+}
+
+// Intercept adds a list of query interceptors to the interceptors stack.
+// A call to `Intercept(f, g, h)` equals to `apikey.Intercept(f(g(h())))`.
+func (c *APIKeyClient) Intercept(interceptors ...Interceptor) {
+	c.inters.APIKey = append(c.inters.APIKey, interceptors...)
+}
+
+// Create returns a builder for creating a APIKey entity.
+func (c *APIKeyClient) Create() *APIKeyCreate {
+	mutation := newAPIKeyMutation(c.config, OpCreate)
+	return &APIKeyCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// CreateBulk returns a builder for creating a bulk of APIKey entities.
+func (c *APIKeyClient) CreateBulk(builders ...*APIKeyCreate) *APIKeyCreateBulk {
+	return &APIKeyCreateBulk{config: c.config, builders: builders}
+}
+
+// MapCreateBulk creates a bulk creation builder from the given slice. For each item in the slice, the function creates
+// a builder and applies setFunc on it.
+func (c *APIKeyClient) MapCreateBulk(slice any, setFunc func(*APIKeyCreate, int)) *APIKeyCreateBulk {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return &APIKeyCreateBulk{err: fmt.Errorf("calling to APIKeyClient.MapCreateBulk with wrong type %T, need slice", slice)}
+	}
+	builders := make([]*APIKeyCreate, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		builders[i] = c.Create()
+		setFunc(builders[i], i)
+	}
+	return &APIKeyCreateBulk{config: c.config, builders: builders}
+}
+
+// Update returns an update builder for APIKey.
+func (c *APIKeyClient) Update() *APIKeyUpdate {
+	mutation := newAPIKeyMutation(c.config, OpUpdate)
+	return &APIKeyUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOne returns an update builder for the given entity.
+func (c *APIKeyClient) UpdateOne(_m *APIKey) *APIKeyUpdateOne {
+	mutation := newAPIKeyMutation(c.config, OpUpdateOne, withAPIKey(_m))
+	return &APIKeyUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOneID returns an update builder for the given id.
+func (c *APIKeyClient) UpdateOneID(id string) *APIKeyUpdateOne {
+	mutation := newAPIKeyMutation(c.config, OpUpdateOne, withAPIKeyID(id))
+	return &APIKeyUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// Delete returns a delete builder for APIKey.
+func (c *APIKeyClient) Delete() *APIKeyDelete {
+	mutation := newAPIKeyMutation(c.config, OpDelete)
+	return &APIKeyDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// DeleteOne returns a builder for deleting the given entity.
+func (c *APIKeyClient) DeleteOne(_m *APIKey) *APIKeyDeleteOne {
+	return c.DeleteOneID(_m.ID)
+}
+
+// DeleteOneID returns a builder for deleting the given entity by its id.
+func (c *APIKeyClient) DeleteOneID(id string) *APIKeyDeleteOne {
+	builder := c.Delete().Where(apikey.ID(id))
+	builder.mutation.id = &id
+	builder.mutation.op = OpDeleteOne
+	return &APIKeyDeleteOne{builder}
+}
+
+// Query returns a query builder for APIKey.
+func (c *APIKeyClient) Query() *APIKeyQuery {
+	return &APIKeyQuery{
+		config: c.config,
+		ctx:    &QueryContext{Type: TypeAPIKey},
+		inters: c.Interceptors(),
+	}
+}
+
+// Get returns a APIKey entity by its id.
+func (c *APIKeyClient) Get(ctx context.Context, id string) (*APIKey, error) {
+	return c.Query().Where(apikey.ID(id)).Only(ctx)
+}
+
+// GetX is like Get, but panics if an error occurs.
+func (c *APIKeyClient) GetX(ctx context.Context, id string) *APIKey {
+	obj, err := c.Get(ctx, id)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// Hooks returns the client hooks.
+func (c *APIKeyClient) Hooks() []Hook {
+	return c.hooks.APIKey
+}
+
+// Interceptors returns the client interceptors.
+func (c *APIKeyClient) Interceptors() []Interceptor {
+	return c.inters.APIKey
+}
+
+func (c *APIKeyClient) mutate(ctx context.Context, m *APIKeyMutation) (Value, error) {
+	switch m.Op() {
+	case OpCreate:
+		return (&APIKeyCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdate:
+		return (&APIKeyUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdateOne:
+		return (&APIKeyUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpDelete, OpDeleteOne:
+		return (&APIKeyDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
+	default:
+		return nil, fmt.Errorf("ent: unknown APIKey mutation op: %q", m.Op())
+	}
+}
+
 // AccountBindingClient is a client for the AccountBinding schema.
 type AccountBindingClient struct {
 	config
@@ -656,6 +855,139 @@ func (c *AgentRuntimeClient) mutate(ctx context.Context, m *AgentRuntimeMutation
 	}
 }
 
+// ApprovalRequestClient is a client for the ApprovalRequest schema.
+type ApprovalRequestClient struct {
+	config
+}
+
+// NewApprovalRequestClient returns a client for the ApprovalRequest from the given config.
+func NewApprovalRequestClient(c config) *ApprovalRequestClient {
+	return &ApprovalRequestClient{config: c}
+}
+
+// Use adds a list of mutation hooks to the hooks stack.
+// A call to `Use(f, g, h)` equals to `approvalrequest.Hooks(f(g(h())))`.
+func (c *ApprovalRequestClient) Use(hooks ...Hook) {
+	c.hooks.ApprovalRequest = append(c.hooks.ApprovalRequest, hooks...)
+}
+
+// Intercept adds a list of query interceptors to the interceptors stack.
+// A call to `Intercept(f, g, h)` equals to `approvalrequest.Intercept(f(g(h())))`.
+func (c *ApprovalRequestClient) Intercept(interceptors ...Interceptor) {
+	c.inters.ApprovalRequest = append(c.inters.ApprovalRequest, interceptors...)
+}
+
+// Create returns a builder for creating a ApprovalRequest entity.
+func (c *ApprovalRequestClient) Create() *ApprovalRequestCreate {
+	mutation := newApprovalRequestMutation(c.config, OpCreate)
+	return &ApprovalRequestCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// CreateBulk returns a builder for creating a bulk of ApprovalRequest entities.
+func (c *ApprovalRequestClient) CreateBulk(builders ...*ApprovalRequestCreate) *ApprovalRequestCreateBulk {
+	return &ApprovalRequestCreateBulk{config: c.config, builders: builders}
+}
+
+// MapCreateBulk creates a bulk creation builder from the given slice. For each item in the slice, the function creates
+// a builder and applies setFunc on it.
+func (c *ApprovalRequestClient) MapCreateBulk(slice any, setFunc func(*ApprovalRequestCreate, int)) *ApprovalRequestCreateBulk {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return &ApprovalRequestCreateBulk{err: fmt.Errorf("calling to ApprovalRequestClient.MapCreateBulk with wrong type %T, need slice", slice)}
+	}
+	builders := make([]*ApprovalRequestCreate, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		builders[i] = c.Create()
+		setFunc(builders[i], i)
+	}
+	return &ApprovalRequestCreateBulk{config: c.config, builders: builders}
+}
+
+// Update returns an update builder for ApprovalRequest.
+func (c *ApprovalRequestClient) Update() *ApprovalRequestUpdate {
+	mutation := newApprovalRequestMutation(c.config, OpUpdate)
+	return &ApprovalRequestUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOne returns an update builder for the given entity.
+func (c *ApprovalRequestClient) UpdateOne(_m *ApprovalRequest) *ApprovalRequestUpdateOne {
+	mutation := newApprovalRequestMutation(c.config, OpUpdateOne, withApprovalRequest(_m))
+	return &ApprovalRequestUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOneID returns an update builder for the given id.
+func (c *ApprovalRequestClient) UpdateOneID(id string) *ApprovalRequestUpdateOne {
+	mutation := newApprovalRequestMutation(c.config, OpUpdateOne, withApprovalRequestID(id))
+	return &ApprovalRequestUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// Delete returns a delete builder for ApprovalRequest.
+func (c *ApprovalRequestClient) Delete() *ApprovalRequestDelete {
+	mutation := newApprovalRequestMutation(c.config, OpDelete)
+	return &ApprovalRequestDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// DeleteOne returns a builder for deleting the given entity.
+func (c *ApprovalRequestClient) DeleteOne(_m *ApprovalRequest) *ApprovalRequestDeleteOne {
+	return c.DeleteOneID(_m.ID)
+}
+
+// DeleteOneID returns a builder for deleting the given entity by its id.
+func (c *ApprovalRequestClient) DeleteOneID(id string) *ApprovalRequestDeleteOne {
+	builder := c.Delete().Where(approvalrequest.ID(id))
+	builder.mutation.id = &id
+	builder.mutation.op = OpDeleteOne
+	return &ApprovalRequestDeleteOne{builder}
+}
+
+// Query returns a query builder for ApprovalRequest.
+func (c *ApprovalRequestClient) Query() *ApprovalRequestQuery {
+	return &ApprovalRequestQuery{
+		config: c.config,
+		ctx:    &QueryContext{Type: TypeApprovalRequest},
+		inters: c.Interceptors(),
+	}
+}
+
+// Get returns a ApprovalRequest entity by its id.
+func (c *ApprovalRequestClient) Get(ctx context.Context, id string) (*ApprovalRequest, error) {
+	return c.Query().Where(approvalrequest.ID(id)).Only(ctx)
+}
+
+// GetX is like Get, but panics if an error occurs.
+func (c *ApprovalRequestClient) GetX(ctx context.Context, id string) *ApprovalRequest {
+	obj, err := c.Get(ctx, id)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// Hooks returns the client hooks.
+func (c *ApprovalRequestClient) Hooks() []Hook {
+	return c.hooks.ApprovalRequest
+}
+
+// Interceptors returns the client interceptors.
+func (c *ApprovalRequestClient) Interceptors() []Interceptor {
+	return c.inters.ApprovalRequest
+}
+
+func (c *ApprovalRequestClient) mutate(ctx context.Context, m *ApprovalRequestMutation) (Value, error) {
+	switch m.Op() {
+	case OpCreate:
+		return (&ApprovalRequestCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdate:
+		return (&ApprovalRequestUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdateOne:
+		return (&ApprovalRequestUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpDelete, OpDeleteOne:
+		return (&ApprovalRequestDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
+	default:
+		return nil, fmt.Errorf("ent: unknown ApprovalRequest mutation op: %q", m.Op())
+	}
+}
+
 // AttachTokenClient is a client for the AttachToken schema.
 type AttachTokenClient struct {
 	config
@@ -789,6 +1121,139 @@ func (c *AttachTokenClient) mutate(ctx context.Context, m *AttachTokenMutation)
 	}
 }
 
+// AuditLogClient is a client for the AuditLog schema.
+type AuditLogClient struct {
+	config
+}
+
+// NewAuditLogClient returns a client for the AuditLog from the given config.
+func NewAuditLogClient(c config) *AuditLogClient {
+	return &AuditLogClient{config: c}
+}
+
+// Use adds a list of mutation hooks to the hooks stack.
+// A call to `Use(f, g, h)` equals to `auditlog.Hooks(f(g(h())))`.
+func (c *AuditLogClient) Use(hooks ...Hook) {
+	c.hooks.AuditLog = append(c.hooks.AuditLog, hooks...)
+}
+
+// Intercept adds a list of query interceptors to the interceptors stack.
+// A call to `Intercept(f, g, h)` equals to `auditlog.Intercept(f(g(h())))`.
+func (c *AuditLogClient) Intercept(interceptors ...Interceptor) {
+	c.inters.AuditLog = append(c.inters.AuditLog, interceptors...)
+}
+
+// Create returns a builder for creating a AuditLog entity.
+func (c *AuditLogClient) Create() *AuditLogCreate {
+	mutation := newAuditLogMutation(c.config, OpCreate)
+	return &AuditLogCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// CreateBulk returns a builder for creating a bulk of AuditLog entities.
+func (c *AuditLogClient) CreateBulk(builders ...*AuditLogCreate) *AuditLogCreateBulk {
+	return &AuditLogCreateBulk{config: c.config, builders: builders}
+}
+
+// MapCreateBulk creates a bulk creation builder from the given slice. For each item in the slice, the function creates
+// a builder and applies setFunc on it.
+func (c *AuditLogClient) MapCreateBulk(slice any, setFunc func(*AuditLogCreate, int)) *AuditLogCreateBulk {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return &AuditLogCreateBulk{err: fmt.Errorf("calling to AuditLogClient.MapCreateBulk with wrong type %T, need slice", slice)}
+	}
+	builders := make([]*AuditLogCreate, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		builders[i] = c.Create()
+		setFunc(builders[i], i)
+	}
+	return &AuditLogCreateBulk{config: c.config, builders: builders}
+}
+
+// Update returns an update builder for AuditLog.
+func (c *AuditLogClient) Update() *AuditLogUpdate {
+	mutation := newAuditLogMutation(c.config, OpUpdate)
+	return &AuditLogUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOne returns an update builder for the given entity.
+func (c *AuditLogClient) UpdateOne(_m *AuditLog) *AuditLogUpdateOne {
+	mutation := newAuditLogMutation(c.config, OpUpdateOne, withAuditLog(_m))
+	return &AuditLogUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOneID returns an update builder for the given id.
+func (c *AuditLogClient) UpdateOneID(id string) *AuditLogUpdateOne {
+	mutation := newAuditLogMutation(c.config, OpUpdateOne, withAuditLogID(id))
+	return &AuditLogUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// Delete returns a delete builder for AuditLog.
+func (c *AuditLogClient) Delete() *AuditLogDelete {
+	mutation := newAuditLogMutation(c.config, OpDelete)
+	return &AuditLogDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// DeleteOne returns a builder for deleting the given entity.
+func (c *AuditLogClient) DeleteOne(_m *AuditLog) *AuditLogDeleteOne {
+	return c.DeleteOneID(_m.ID)
+}
+
+// DeleteOneID returns a builder for deleting the given entity by its id.
+func (c *AuditLogClient) DeleteOneID(id string) *AuditLogDeleteOne {
+	builder := c.Delete().Where(auditlog.ID(id))
+	builder.mutation.id = &id
+	builder.mutation.op = OpDeleteOne
+	return &AuditLogDeleteOne{builder}
+}
+
+// Query returns a query builder for AuditLog.
+func (c *AuditLogClient) Query() *AuditLogQuery {
+	return &AuditLogQuery{
+		config: c.config,
+		ctx:    &QueryContext{Type: TypeAuditLog},
+		inters: c.Interceptors(),
+	}
+}
+
+// Get returns a AuditLog entity by its id.
+func (c *AuditLogClient) Get(ctx context.Context, id string) (*AuditLog, error) {
+	return c.Query().Where(auditlog.ID(id)).Only(ctx)
+}
+
+// GetX is like Get, but panics if an error occurs.
+func (c *AuditLogClient) GetX(ctx context.Context, id string) *AuditLog {
+	obj, err := c.Get(ctx, id)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// Hooks returns the client hooks.
+func (c *AuditLogClient) Hooks() []Hook {
+	return c.hooks.AuditLog
+}
+
+// Interceptors returns the client interceptors.
+func (c *AuditLogClient) Interceptors() []Interceptor {
+	return c.inters.AuditLog
+}
+
+func (c *AuditLogClient) mutate(ctx context.Context, m *AuditLogMutation) (Value, error) {
+	switch m.Op() {
+	case OpCreate:
+		return (&AuditLogCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdate:
+		return (&AuditLogUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdateOne:
+		return (&AuditLogUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpDelete, OpDeleteOne:
+		return (&AuditLogDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
+	default:
+		return nil, fmt.Errorf("ent: unknown AuditLog mutation op: %q", m.Op())
+	}
+}
+
 // ChannelAccountClient is a client for the ChannelAccount schema.
 type ChannelAccountClient struct {
 	config
@@ -1077,85 +1542,484 @@ func (c *ConfigSectionClient) Intercept(interceptors ...Interceptor) {
 	c.inters.ConfigSection = append(c.inters.ConfigSection, interceptors...)
 }
 
-// Create returns a builder for creating a ConfigSection entity.
-func (c *ConfigSectionClient) Create() *ConfigSectionCreate {
-	mutation := newConfigSectionMutation(c.config, OpCreate)
-	return &ConfigSectionCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+// Create returns a builder for creating a ConfigSection entity.
+func (c *ConfigSectionClient) Create() *ConfigSectionCreate {
+	mutation := newConfigSectionMutation(c.config, OpCreate)
+	return &ConfigSectionCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// CreateBulk returns a builder for creating a bulk of ConfigSection entities.
+func (c *ConfigSectionClient) CreateBulk(builders ...*ConfigSectionCreate) *ConfigSectionCreateBulk {
+	return &ConfigSectionCreateBulk{config: c.config, builders: builders}
+}
+
+// MapCreateBulk creates a bulk creation builder from the given slice. For each item in the slice, the function creates
+// a builder and applies setFunc on it.
+func (c *ConfigSectionClient) MapCreateBulk(slice any, setFunc func(*ConfigSectionCreate, int)) *ConfigSectionCreateBulk {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return &ConfigSectionCreateBulk{err: fmt.Errorf("calling to ConfigSectionClient.MapCreateBulk with wrong type %T, need slice", slice)}
+	}
+	builders := make([]*ConfigSectionCreate, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		builders[i] = c.Create()
+		setFunc(builders[i], i)
+	}
+	return &ConfigSectionCreateBulk{config: c.config, builders: builders}
+}
+
+// Update returns an update builder for ConfigSection.
+func (c *ConfigSectionClient) Update() *ConfigSectionUpdate {
+	mutation := newConfigSectionMutation(c.config, OpUpdate)
+	return &ConfigSectionUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOne returns an update builder for the given entity.
+func (c *ConfigSectionClient) UpdateOne(_m *ConfigSection) *ConfigSectionUpdateOne {
+	mutation := newConfigSectionMutation(c.config, OpUpdateOne, withConfigSection(_m))
+	return &ConfigSectionUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOneID returns an update builder for the given id.
+func (c *ConfigSectionClient) UpdateOneID(id int) *ConfigSectionUpdateOne {
+	mutation := newConfigSectionMutation(c.config, OpUpdateOne, withConfigSectionID(id))
+	return &ConfigSectionUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// Delete returns a delete builder for ConfigSection.
+func (c *ConfigSectionClient) Delete() *ConfigSectionDelete {
+	mutation := newConfigSectionMutation(c.config, OpDelete)
+	return &ConfigSectionDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// DeleteOne returns a builder for deleting the given entity.
+func (c *ConfigSectionClient) DeleteOne(_m *ConfigSection) *ConfigSectionDeleteOne {
+	return c.DeleteOneID(_m.ID)
+}
+
+// DeleteOneID returns a builder for deleting the given entity by its id.
+func (c *ConfigSectionClient) DeleteOneID(id int) *ConfigSectionDeleteOne {
+	builder := c.Delete().Where(configsection.ID(id))
+	builder.mutation.id = &id
+	builder.mutation.op = OpDeleteOne
+	return &ConfigSectionDeleteOne{builder}
+}
+
+// Query returns a query builder for ConfigSection.
+func (c *ConfigSectionClient) Query() *ConfigSectionQuery {
+	return &ConfigSectionQuery{
+		config: c.config,
+		ctx:    &QueryContext{Type: TypeConfigSection},
+		inters: c.Interceptors(),
+	}
+}
+
+// Get returns a ConfigSection entity by its id.
+func (c *ConfigSectionClient) Get(ctx context.Context, id int) (*ConfigSection, error) {
+	return c.Query().Where(configsection.ID(id)).Only(ctx)
+}
+
+// GetX is like Get, but panics if an error occurs.
+func (c *ConfigSectionClient) GetX(ctx context.Context, id int) *ConfigSection {
+	obj, err := c.Get(ctx, id)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// Hooks returns the client hooks.
+func (c *ConfigSectionClient) Hooks() []Hook {
+	return c.hooks.ConfigSection
+}
+
+// Interceptors returns the client interceptors.
+func (c *ConfigSectionClient) Interceptors() []Interceptor {
+	return c.inters.ConfigSection
+}
+
+func (c *ConfigSectionClient) mutate(ctx context.Context, m *ConfigSectionMutation) (Value, error) {
+	switch m.Op() {
+	case OpCreate:
+		return (&ConfigSectionCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdate:
+		return (&ConfigSectionUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdateOne:
+		return (&ConfigSectionUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpDelete, OpDeleteOne:
+		return (&ConfigSectionDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
+	default:
+		return nil, fmt.Errorf("ent: unknown ConfigSection mutation op: %q", m.Op())
+	}
+}
+
+// CronJobClient is a client for the CronJob schema.
+type CronJobClient struct {
+	config
+}
+
+// NewCronJobClient returns a client for the CronJob from the given config.
+func NewCronJobClient(c config) *CronJobClient {
+	return &CronJobClient{config: c}
+}
+
+// Use adds a list of mutation hooks to the hooks stack.
+// A call to `Use(f, g, h)` equals to `cronjob.Hooks(f(g(h())))`.
+func (c *CronJobClient) Use(hooks ...Hook) {
+	c.hooks.CronJob = append(c.hooks.CronJob, hooks...)
+}
+
+// Intercept adds a list of query interceptors to the interceptors stack.
+// A call to `Intercept(f, g, h)` equals to `cronjob.Intercept(f(g(h())))`.
+func (c *CronJobClient) Intercept(interceptors ...Interceptor) {
+	c.inters.CronJob = append(c.inters.CronJob, interceptors...)
+}
+
+// Create returns a builder for creating a CronJob entity.
+func (c *CronJobClient) Create() *CronJobCreate {
+	mutation := newCronJobMutation(c.config, OpCreate)
+	return &CronJobCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// CreateBulk returns a builder for creating a bulk of CronJob entities.
+func (c *CronJobClient) CreateBulk(builders ...*CronJobCreate) *CronJobCreateBulk {
+	return &CronJobCreateBulk{config: c.config, builders: builders}
+}
+
+// MapCreateBulk creates a bulk creation builder from the given slice. For each item in the slice, the function creates
+// a builder and applies setFunc on it.
+func (c *CronJobClient) MapCreateBulk(slice any, setFunc func(*CronJobCreate, int)) *CronJobCreateBulk {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return &CronJobCreateBulk{err: fmt.Errorf("calling to CronJobClient.MapCreateBulk with wrong type %T, need slice", slice)}
+	}
+	builders := make([]*CronJobCreate, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		builders[i] = c.Create()
+		setFunc(builders[i], i)
+	}
+	return &CronJobCreateBulk{config: c.config, builders: builders}
+}
+
+// Update returns an update builder for CronJob.
+func (c *CronJobClient) Update() *CronJobUpdate {
+	mutation := newCronJobMutation(c.config, OpUpdate)
+	return &CronJobUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOne returns an update builder for the given entity.
+func (c *CronJobClient) UpdateOne(_m *CronJob) *CronJobUpdateOne {
+	mutation := newCronJobMutation(c.config, OpUpdateOne, withCronJob(_m))
+	return &CronJobUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOneID returns an update builder for the given id.
+func (c *CronJobClient) UpdateOneID(id string) *CronJobUpdateOne {
+	mutation := newCronJobMutation(c.config, OpUpdateOne, withCronJobID(id))
+	return &CronJobUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// Delete returns a delete builder for CronJob.
+func (c *CronJobClient) Delete() *CronJobDelete {
+	mutation := newCronJobMutation(c.config, OpDelete)
+	return &CronJobDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// DeleteOne returns a builder for deleting the given entity.
+func (c *CronJobClient) DeleteOne(_m *CronJob) *CronJobDeleteOne {
+	return c.DeleteOneID(_m.ID)
+}
+
+// DeleteOneID returns a builder for deleting the given entity by its id.
+func (c *CronJobClient) DeleteOneID(id string) *CronJobDeleteOne {
+	builder := c.Delete().Where(cronjob.ID(id))
+	builder.mutation.id = &id
+	builder.mutation.op = OpDeleteOne
+	return &CronJobDeleteOne{builder}
+}
+
+// Query returns a query builder for CronJob.
+func (c *CronJobClient) Query() *CronJobQuery {
+	return &CronJobQuery{
+		config: c.config,
+		ctx:    &QueryContext{Type: TypeCronJob},
+		inters: c.Interceptors(),
+	}
+}
+
+// Get returns a CronJob entity by its id.
+func (c *CronJobClient) Get(ctx context.Context, id string) (*CronJob, error) {
+	return c.Query().Where(cronjob.ID(id)).Only(ctx)
+}
+
+// GetX is like Get, but panics if an error occurs.
+func (c *CronJobClient) GetX(ctx context.Context, id string) *CronJob {
+	obj, err := c.Get(ctx, id)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// Hooks returns the client hooks.
+func (c *CronJobClient) Hooks() []Hook {
+	return c.hooks.CronJob
+}
+
+// Interceptors returns the client interceptors.
+func (c *CronJobClient) Interceptors() []Interceptor {
+	return c.inters.CronJob
+}
+
+func (c *CronJobClient) mutate(ctx context.Context, m *CronJobMutation) (Value, error) {
+	switch m.Op() {
+	case OpCreate:
+		return (&CronJobCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdate:
+		return (&CronJobUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdateOne:
+		return (&CronJobUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpDelete, OpDeleteOne:
+		return (&CronJobDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
+	default:
+		return nil, fmt.Errorf("ent: unknown CronJob mutation op: %q", m.Op())
+	}
+}
+
+// FeedEntryClient is a client for the FeedEntry schema.
+type FeedEntryClient struct {
+	config
+}
+
+// NewFeedEntryClient returns a client for the FeedEntry from the given config.
+func NewFeedEntryClient(c config) *FeedEntryClient {
+	return &FeedEntryClient{config: c}
+}
+
+// Use adds a list of mutation hooks to the hooks stack.
+// A call to `Use(f, g, h)` equals to `feedentry.Hooks(f(g(h())))`.
+func (c *FeedEntryClient) Use(hooks ...Hook) {
+	c.hooks.FeedEntry = append(c.hooks.FeedEntry, hooks...)
+}
+
+// Intercept adds a list of query interceptors to the interceptors stack.
+// A call to `Intercept(f, g, h)` equals to `feedentry.Intercept(f(g(h())))`.
+func (c *FeedEntryClient) Intercept(interceptors ...Interceptor) {
+	c.inters.FeedEntry = append(c.inters.FeedEntry, interceptors...)
+}
+
+// Create returns a builder for creating a FeedEntry entity.
+func (c *FeedEntryClient) Create() *FeedEntryCreate {
+	mutation := newFeedEntryMutation(c.config, OpCreate)
+	return &FeedEntryCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// CreateBulk returns a builder for creating a bulk of FeedEntry entities.
+func (c *FeedEntryClient) CreateBulk(builders ...*FeedEntryCreate) *FeedEntryCreateBulk {
+	return &FeedEntryCreateBulk{config: c.config, builders: builders}
+}
+
+// MapCreateBulk creates a bulk creation builder from the given slice. For each item in the slice, the function creates
+// a builder and applies setFunc on it.
+func (c *FeedEntryClient) MapCreateBulk(slice any, setFunc func(*FeedEntryCreate, int)) *FeedEntryCreateBulk {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return &FeedEntryCreateBulk{err: fmt.Errorf("calling to FeedEntryClient.MapCreateBulk with wrong type %T, need slice", slice)}
+	}
+	builders := make([]*FeedEntryCreate, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		builders[i] = c.Create()
+		setFunc(builders[i], i)
+	}
+	return &FeedEntryCreateBulk{config: c.config, builders: builders}
+}
+
+// Update returns an update builder for FeedEntry.
+func (c *FeedEntryClient) Update() *FeedEntryUpdate {
+	mutation := newFeedEntryMutation(c.config, OpUpdate)
+	return &FeedEntryUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOne returns an update builder for the given entity.
+func (c *FeedEntryClient) UpdateOne(_m *FeedEntry) *FeedEntryUpdateOne {
+	mutation := newFeedEntryMutation(c.config, OpUpdateOne, withFeedEntry(_m))
+	return &FeedEntryUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOneID returns an update builder for the given id.
+func (c *FeedEntryClient) UpdateOneID(id string) *FeedEntryUpdateOne {
+	mutation := newFeedEntryMutation(c.config, OpUpdateOne, withFeedEntryID(id))
+	return &FeedEntryUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// Delete returns a delete builder for FeedEntry.
+func (c *FeedEntryClient) Delete() *FeedEntryDelete {
+	mutation := newFeedEntryMutation(c.config, OpDelete)
+	return &FeedEntryDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// DeleteOne returns a builder for deleting the given entity.
+func (c *FeedEntryClient) DeleteOne(_m *FeedEntry) *FeedEntryDeleteOne {
+	return c.DeleteOneID(_m.ID)
+}
+
+// DeleteOneID returns a builder for deleting the given entity by its id.
+func (c *FeedEntryClient) DeleteOneID(id string) *FeedEntryDeleteOne {
+	builder := c.Delete().Where(feedentry.ID(id))
+	builder.mutation.id = &id
+	builder.mutation.op = OpDeleteOne
+	return &FeedEntryDeleteOne{builder}
+}
+
+// Query returns a query builder for FeedEntry.
+func (c *FeedEntryClient) Query() *FeedEntryQuery {
+	return &FeedEntryQuery{
+		config: c.config,
+		ctx:    &QueryContext{Type: TypeFeedEntry},
+		inters: c.Interceptors(),
+	}
+}
+
+// Get returns a FeedEntry entity by its id.
+func (c *FeedEntryClient) Get(ctx context.Context, id string) (*FeedEntry, error) {
+	return c.Query().Where(feedentry.ID(id)).Only(ctx)
+}
+
+// GetX is like Get, but panics if an error occurs.
+func (c *FeedEntryClient) GetX(ctx context.Context, id string) *FeedEntry {
+	obj, err := c.Get(ctx, id)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// Hooks returns the client hooks.
+func (c *FeedEntryClient) Hooks() []Hook {
+	return c.hooks.FeedEntry
+}
+
+// Interceptors returns the client interceptors.
+func (c *FeedEntryClient) Interceptors() []Interceptor {
+	return c.inters.FeedEntry
+}
+
+func (c *FeedEntryClient) mutate(ctx context.Context, m *FeedEntryMutation) (Value, error) {
+	switch m.Op() {
+	case OpCreate:
+		return (&FeedEntryCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdate:
+		return (&FeedEntryUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdateOne:
+		return (&FeedEntryUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpDelete, OpDeleteOne:
+		return (&FeedEntryDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
+	default:
+		return nil, fmt.Errorf("ent: unknown FeedEntry mutation op: %q", m.Op())
+	}
+}
+
+// FeedSubscriptionClient is a client for the FeedSubscription schema.
+type FeedSubscriptionClient struct {
+	config
+}
+
+// NewFeedSubscriptionClient returns a client for the FeedSubscription from the given config.
+func NewFeedSubscriptionClient(c config) *FeedSubscriptionClient {
+	return &FeedSubscriptionClient{config: c}
+}
+
+// Use adds a list of mutation hooks to the hooks stack.
+// A call to `Use(f, g, h)` equals to `feedsubscription.Hooks(f(g(h())))`.
+func (c *FeedSubscriptionClient) Use(hooks ...Hook) {
+	c.hooks.FeedSubscription = append(c.hooks.FeedSubscription, hooks...)
+}
+
+// Intercept adds a list of query interceptors to the interceptors stack.
+// A call to `Intercept(f, g, h)` equals to `feedsubscription.Intercept(f(g(h())))`.
+func (c *FeedSubscriptionClient) Intercept(interceptors ...Interceptor) {
+	c.inters.FeedSubscription = append(c.inters.FeedSubscription, interceptors...)
+}
+
+// Create returns a builder for creating a FeedSubscription entity.
+func (c *FeedSubscriptionClient) Create() *FeedSubscriptionCreate {
+	mutation := newFeedSubscriptionMutation(c.config, OpCreate)
+	return &FeedSubscriptionCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
 }
 
-// CreateBulk returns a builder for creating a bulk of ConfigSection entities.
-func (c *ConfigSectionClient) CreateBulk(builders ...*ConfigSectionCreate) *ConfigSectionCreateBulk {
-	return &ConfigSectionCreateBulk{config: c.config, builders: builders}
+// CreateBulk returns a builder for creating a bulk of FeedSubscription entities.
+func (c *FeedSubscriptionClient) CreateBulk(builders ...*FeedSubscriptionCreate) *FeedSubscriptionCreateBulk {
+	return &FeedSubscriptionCreateBulk{config: c.config, builders: builders}
 }
 
 // MapCreateBulk creates a bulk creation builder from the given slice. For each item in the slice, the function creates
 // a builder and applies setFunc on it.
-func (c *ConfigSectionClient) MapCreateBulk(slice any, setFunc func(*ConfigSectionCreate, int)) *ConfigSectionCreateBulk {
+func (c *FeedSubscriptionClient) MapCreateBulk(slice any, setFunc func(*FeedSubscriptionCreate, int)) *FeedSubscriptionCreateBulk {
 	rv := reflect.ValueOf(slice)
 	if rv.Kind() != reflect.Slice {
-		return &ConfigSectionCreateBulk{err: fmt.Errorf("calling to ConfigSectionClient.MapCreateBulk with wrong type %T, need slice", slice)}
+		return &FeedSubscriptionCreateBulk{err: fmt.Errorf("calling to FeedSubscriptionClient.MapCreateBulk with wrong type %T, need slice", slice)}
 	}
-	builders := make([]*ConfigSectionCreate, rv.Len())
+	builders := make([]*FeedSubscriptionCreate, rv.Len())
 	for i := 0; i < rv.Len(); i++ {
 		builders[i] = c.Create()
 		setFunc(builders[i], i)
 	}
-	return &ConfigSectionCreateBulk{config: c.config, builders: builders}
+	return &FeedSubscriptionCreateBulk{config: c.config, builders: builders}
 }
 
-// Update returns an update builder for ConfigSection.
-func (c *ConfigSectionClient) Update() *ConfigSectionUpdate {
-	mutation := newConfigSectionMutation(c.config, OpUpdate)
-	return &ConfigSectionUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+// Update returns an update builder for FeedSubscription.
+func (c *FeedSubscriptionClient) Update() *FeedSubscriptionUpdate {
+	mutation := newFeedSubscriptionMutation(c.config, OpUpdate)
+	return &FeedSubscriptionUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
 }
 
 // UpdateOne returns an update builder for the given entity.
-func (c *ConfigSectionClient) UpdateOne(_m *ConfigSection) *ConfigSectionUpdateOne {
-	mutation := newConfigSectionMutation(c.config, OpUpdateOne, withConfigSection(_m))
-	return &ConfigSectionUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+func (c *FeedSubscriptionClient) UpdateOne(_m *FeedSubscription) *FeedSubscriptionUpdateOne {
+	mutation := newFeedSubscriptionMutation(c.config, OpUpdateOne, withFeedSubscription(_m))
+	return &FeedSubscriptionUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
 }
 
 // UpdateOneID returns an update builder for the given id.
-func (c *ConfigSectionClient) UpdateOneID(id int) *ConfigSectionUpdateOne {
-	mutation := newConfigSectionMutation(c.config, OpUpdateOne, withConfigSectionID(id))
-	return &ConfigSectionUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+func (c *FeedSubscriptionClient) UpdateOneID(id string) *FeedSubscriptionUpdateOne {
+	mutation := newFeedSubscriptionMutation(c.config, OpUpdateOne, withFeedSubscriptionID(id))
+	return &FeedSubscriptionUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
 }
 
-// Delete returns a delete builder for ConfigSection.
-func (c *ConfigSectionClient) Delete() *ConfigSectionDelete {
-	mutation := newConfigSectionMutation(c.config, OpDelete)
-	return &ConfigSectionDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
+// Delete returns a delete builder for FeedSubscription.
+func (c *FeedSubscriptionClient) Delete() *FeedSubscriptionDelete {
+	mutation := newFeedSubscriptionMutation(c.config, OpDelete)
+	return &FeedSubscriptionDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
 }
 
 // DeleteOne returns a builder for deleting the given entity.
-func (c *ConfigSectionClient) DeleteOne(_m *ConfigSection) *ConfigSectionDeleteOne {
+func (c *FeedSubscriptionClient) DeleteOne(_m *FeedSubscription) *FeedSubscriptionDeleteOne {
 	return c.DeleteOneID(_m.ID)
 }
 
 // DeleteOneID returns a builder for deleting the given entity by its id.
-func (c *ConfigSectionClient) DeleteOneID(id int) *ConfigSectionDeleteOne {
-	builder := c.Delete().Where(configsection.ID(id))
+func (c *FeedSubscriptionClient) DeleteOneID(id string) *FeedSubscriptionDeleteOne {
+	builder := c.Delete().Where(feedsubscription.ID(id))
 	builder.mutation.id = &id
 	builder.mutation.op = OpDeleteOne
-	return &ConfigSectionDeleteOne{builder}
+	return &FeedSubscriptionDeleteOne{builder}
 }
 
-// Query returns a query builder for ConfigSection.
-func (c *ConfigSectionClient) Query() *ConfigSectionQuery {
-	return &ConfigSectionQuery{
+// Query returns a query builder for FeedSubscription.
+func (c *FeedSubscriptionClient) Query() *FeedSubscriptionQuery {
+	return &FeedSubscriptionQuery{
 		config: c.config,
-		ctx:    &QueryContext{Type: TypeConfigSection},
+		ctx:    &QueryContext{Type: TypeFeedSubscription},
 		inters: c.Interceptors(),
 	}
 }
 
-// Get returns a ConfigSection entity by its id.
-func (c *ConfigSectionClient) Get(ctx context.Context, id int) (*ConfigSection, error) {
-	return c.Query().Where(configsection.ID(id)).Only(ctx)
+// Get returns a FeedSubscription entity by its id.
+func (c *FeedSubscriptionClient) Get(ctx context.Context, id string) (*FeedSubscription, error) {
+	return c.Query().Where(feedsubscription.ID(id)).Only(ctx)
 }
 
 // GetX is like Get, but panics if an error occurs.
-func (c *ConfigSectionClient) GetX(ctx context.Context, id int) *ConfigSection {
+func (c *FeedSubscriptionClient) GetX(ctx context.Context, id string) *FeedSubscription {
 	obj, err := c.Get(ctx, id)
 	if err != nil {
 		panic(err)
@@ -1164,131 +2028,131 @@ func (c *ConfigSectionClient) GetX(ctx context.Context, id int) *ConfigSection {
 }
 
 // Hooks returns the client hooks.
-func (c *ConfigSectionClient) Hooks() []Hook {
-	return c.hooks.ConfigSection
+func (c *FeedSubscriptionClient) Hooks() []Hook {
+	return c.hooks.FeedSubscription
 }
 
 // Interceptors returns the client interceptors.
-func (c *ConfigSectionClient) Interceptors() []Interceptor {
-	return c.inters.ConfigSection
+func (c *FeedSubscriptionClient) Interceptors() []Interceptor {
+	return c.inters.FeedSubscription
 }
 
-func (c *ConfigSectionClient) mutate(ctx context.Context, m *ConfigSectionMutation) (Value, error) {
+func (c *FeedSubscriptionClient) mutate(ctx context.Context, m *FeedSubscriptionMutation) (Value, error) {
 	switch m.Op() {
 	case OpCreate:
-		return (&ConfigSectionCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+		return (&FeedSubscriptionCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
 	case OpUpdate:
-		return (&ConfigSectionUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+		return (&FeedSubscriptionUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
 	case OpUpdateOne:
-		return (&ConfigSectionUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+		return (&FeedSubscriptionUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
 	case OpDelete, OpDeleteOne:
-		return (&ConfigSectionDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
+		return (&FeedSubscriptionDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
 	default:
-		return nil, fmt.Errorf("ent: unknown ConfigSection mutation op: %q", m.Op())
+		return nil, fmt.Errorf("ent: unknown FeedSubscription mutation op: %q", m.Op())
 	}
 }
 
-// CronJobClient is a client for the CronJob schema.
-type CronJobClient struct {
+// FeedbackClient is a client for the Feedback schema.
+type FeedbackClient struct {
 	config
 }
 
-// NewCronJobClient returns a client for the CronJob from the given config.
-func NewCronJobClient(c config) *CronJobClient {
-	return &CronJobClient{config: c}
+// NewFeedbackClient returns a client for the Feedback from the given config.
+func NewFeedbackClient(c config) *FeedbackClient {
+	return &FeedbackClient{config: c}
 }
 
 // Use adds a list of mutation hooks to the hooks stack.
-// A call to `Use(f, g, h)` equals to `cronjob.Hooks(f(g(h())))`.
-func (c *CronJobClient) Use(hooks ...Hook) {
-	c.hooks.CronJob = append(c.hooks.CronJob, hooks...)
+// A call to `Use(f, g, h)` equals to `feedback.Hooks(f(g(h())))`.
+func (c *FeedbackClient) Use(hooks ...Hook) {
+	c.hooks.Feedback = append(c.hooks.Feedback, hooks...)
 }
 
 // Intercept adds a list of query interceptors to the interceptors stack.
-// A call to `Intercept(f, g, h)` equals to `cronjob.Intercept(f(g(h())))`.
-func (c *CronJobClient) Intercept(interceptors ...Interceptor) {
-	c.inters.CronJob = append(c.inters.CronJob, interceptors...)
+// A call to `Intercept(f, g, h)` equals to `feedback.Intercept(f(g(h())))`.
+func (c *FeedbackClient) Intercept(interceptors ...Interceptor) {
+	c.inters.Feedback = append(c.inters.Feedback, interceptors...)
 }
 
-// Create returns a builder for creating a CronJob entity.
-func (c *CronJobClient) Create() *CronJobCreate {
-	mutation := newCronJobMutation(c.config, OpCreate)
-	return &CronJobCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+// Create returns a builder for creating a Feedback entity.
+func (c *FeedbackClient) Create() *FeedbackCreate {
+	mutation := newFeedbackMutation(c.config, OpCreate)
+	return &FeedbackCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
 }
 
-// CreateBulk returns a builder for creating a bulk of CronJob entities.
-func (c *CronJobClient) CreateBulk(builders ...*CronJobCreate) *CronJobCreateBulk {
-	return &CronJobCreateBulk{config: c.config, builders: builders}
+// CreateBulk returns a builder for creating a bulk of Feedback entities.
+func (c *FeedbackClient) CreateBulk(builders ...*FeedbackCreate) *FeedbackCreateBulk {
+	return &FeedbackCreateBulk{config: c.config, builders: builders}
 }
 
 // MapCreateBulk creates a bulk creation builder from the given slice. For each item in the slice, the function creates
 // a builder and applies setFunc on it.
-func (c *CronJobClient) MapCreateBulk(slice any, setFunc func(*CronJobCreate, int)) *CronJobCreateBulk {
+func (c *FeedbackClient) MapCreateBulk(slice any, setFunc func(*FeedbackCreate, int)) *FeedbackCreateBulk {
 	rv := reflect.ValueOf(slice)
 	if rv.Kind() != reflect.Slice {
-		return &CronJobCreateBulk{err: fmt.Errorf("calling to CronJobClient.MapCreateBulk with wrong type %T, need slice", slice)}
+		return &FeedbackCreateBulk{err: fmt.Errorf("calling to FeedbackClient.MapCreateBulk with wrong type %T, need slice", slice)}
 	}
-	builders := make([]*CronJobCreate, rv.Len())
+	builders := make([]*FeedbackCreate, rv.Len())
 	for i := 0; i < rv.Len(); i++ {
 		builders[i] = c.Create()
 		setFunc(builders[i], i)
 	}
-	return &CronJobCreateBulk{config: c.config, builders: builders}
+	return &FeedbackCreateBulk{config: c.config, builders: builders}
 }
 
-// Update returns an update builder for CronJob.
-func (c *CronJobClient) Update() *CronJobUpdate {
-	mutation := newCronJobMutation(c.config, OpUpdate)
-	return &CronJobUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+// Update returns an update builder for Feedback.
+func (c *FeedbackClient) Update() *FeedbackUpdate {
+	mutation := newFeedbackMutation(c.config, OpUpdate)
+	return &FeedbackUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
 }
 
 // UpdateOne returns an update builder for the given entity.
-func (c *CronJobClient) UpdateOne(_m *CronJob) *CronJobUpdateOne {
-	mutation := newCronJobMutation(c.config, OpUpdateOne, withCronJob(_m))
-	return &CronJobUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+func (c *FeedbackClient) UpdateOne(_m *Feedback) *FeedbackUpdateOne {
+	mutation := newFeedbackMutation(c.config, OpUpdateOne, withFeedback(_m))
+	return &FeedbackUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
 }
 
 // UpdateOneID returns an update builder for the given id.
-func (c *CronJobClient) UpdateOneID(id string) *CronJobUpdateOne {
-	mutation := newCronJobMutation(c.config, OpUpdateOne, withCronJobID(id))
-	return &CronJobUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+func (c *FeedbackClient) UpdateOneID(id string) *FeedbackUpdateOne {
+	mutation := newFeedbackMutation(c.config, OpUpdateOne, withFeedbackID(id))
+	return &FeedbackUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
 }
 
-// Delete returns a delete builder for CronJob.
-func (c *CronJobClient) Delete() *CronJobDelete {
-	mutation := newCronJobMutation(c.config, OpDelete)
-	return &CronJobDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
+// Delete returns a delete builder for Feedback.
+func (c *FeedbackClient) Delete() *FeedbackDelete {
+	mutation := newFeedbackMutation(c.config, OpDelete)
+	return &FeedbackDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
 }
 
 // DeleteOne returns a builder for deleting the given entity.
-func (c *CronJobClient) DeleteOne(_m *CronJob) *CronJobDeleteOne {
+func (c *FeedbackClient) DeleteOne(_m *Feedback) *FeedbackDeleteOne {
 	return c.DeleteOneID(_m.ID)
 }
 
 // DeleteOneID returns a builder for deleting the given entity by its id.
-func (c *CronJobClient) DeleteOneID(id string) *CronJobDeleteOne {
-	builder := c.Delete().Where(cronjob.ID(id))
+func (c *FeedbackClient) DeleteOneID(id string) *FeedbackDeleteOne {
+	builder := c.Delete().Where(feedback.ID(id))
 	builder.mutation.id = &id
 	builder.mutation.op = OpDeleteOne
-	return &CronJobDeleteOne{builder}
+	return &FeedbackDeleteOne{builder}
 }
 
-// Query returns a query builder for CronJob.
-func (c *CronJobClient) Query() *CronJobQuery {
-	return &CronJobQuery{
+// Query returns a query builder for Feedback.
+func (c *FeedbackClient) Query() *FeedbackQuery {
+	return &FeedbackQuery{
 		config: c.config,
-		ctx:    &QueryContext{Type: TypeCronJob},
+		ctx:    &QueryContext{Type: TypeFeedback},
 		inters: c.Interceptors(),
 	}
 }
 
-// Get returns a CronJob entity by its id.
-func (c *CronJobClient) Get(ctx context.Context, id string) (*CronJob, error) {
-	return c.Query().Where(cronjob.ID(id)).Only(ctx)
+// Get returns a Feedback entity by its id.
+func (c *FeedbackClient) Get(ctx context.Context, id string) (*Feedback, error) {
+	return c.Query().Where(feedback.ID(id)).Only(ctx)
 }
 
 // GetX is like Get, but panics if an error occurs.
-func (c *CronJobClient) GetX(ctx context.Context, id string) *CronJob {
+func (c *FeedbackClient) GetX(ctx context.Context, id string) *Feedback {
 	obj, err := c.Get(ctx, id)
 	if err != nil {
 		panic(err)
@@ -1297,27 +2161,27 @@ func (c *CronJobClient) GetX(ctx context.Context, id string) *CronJob {
 }
 
 // Hooks returns the client hooks.
-func (c *CronJobClient) Hooks() []Hook {
-	return c.hooks.CronJob
+func (c *FeedbackClient) Hooks() []Hook {
+	return c.hooks.Feedback
 }
 
 // Interceptors returns the client interceptors.
-func (c *CronJobClient) Interceptors() []Interceptor {
-	return c.inters.CronJob
+func (c *FeedbackClient) Interceptors() []Interceptor {
+	return c.inters.Feedback
 }
 
-func (c *CronJobClient) mutate(ctx context.Context, m *CronJobMutation) (Value, error) {
+func (c *FeedbackClient) mutate(ctx context.Context, m *FeedbackMutation) (Value, error) {
 	switch m.Op() {
 	case OpCreate:
-		return (&CronJobCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+		return (&FeedbackCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
 	case OpUpdate:
-		return (&CronJobUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+		return (&FeedbackUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
 	case OpUpdateOne:
-		return (&CronJobUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+		return (&FeedbackUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
 	case OpDelete, OpDeleteOne:
-		return (&CronJobDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
+		return (&FeedbackDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
 	default:
-		return nil, fmt.Errorf("ent: unknown CronJob mutation op: %q", m.Op())
+		return nil, fmt.Errorf("ent: unknown Feedback mutation op: %q", m.Op())
 	}
 }
 
@@ -2151,6 +3015,139 @@ func (c *NotificationRouteClient) mutate(ctx context.Context, m *NotificationRou
 	}
 }
 
+// NotifyRuleClient is a client for the NotifyRule schema.
+type NotifyRuleClient struct {
+	config
+}
+
+// NewNotifyRuleClient returns a client for the NotifyRule from the given config.
+func NewNotifyRuleClient(c config) *NotifyRuleClient {
+	return &NotifyRuleClient{config: c}
+}
+
+// Use adds a list of mutation hooks to the hooks stack.
+// A call to `Use(f, g, h)` equals to `notifyrule.Hooks(f(g(h())))`.
+func (c *NotifyRuleClient) Use(hooks ...Hook) {
+	c.hooks.NotifyRule = append(c.hooks.NotifyRule, hooks...)
+}
+
+// Intercept adds a list of query interceptors to the interceptors stack.
+// A call to `Intercept(f, g, h)` equals to `notifyrule.Intercept(f(g(h())))`.
+func (c *NotifyRuleClient) Intercept(interceptors ...Interceptor) {
+	c.inters.NotifyRule = append(c.inters.NotifyRule, interceptors...)
+}
+
+// Create returns a builder for creating a NotifyRule entity.
+func (c *NotifyRuleClient) Create() *NotifyRuleCreate {
+	mutation := newNotifyRuleMutation(c.config, OpCreate)
+	return &NotifyRuleCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// CreateBulk returns a builder for creating a bulk of NotifyRule entities.
+func (c *NotifyRuleClient) CreateBulk(builders ...*NotifyRuleCreate) *NotifyRuleCreateBulk {
+	return &NotifyRuleCreateBulk{config: c.config, builders: builders}
+}
+
+// MapCreateBulk creates a bulk creation builder from the given slice. For each item in the slice, the function creates
+// a builder and applies setFunc on it.
+func (c *NotifyRuleClient) MapCreateBulk(slice any, setFunc func(*NotifyRuleCreate, int)) *NotifyRuleCreateBulk {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return &NotifyRuleCreateBulk{err: fmt.Errorf("calling to NotifyRuleClient.MapCreateBulk with wrong type %T, need slice", slice)}
+	}
+	builders := make([]*NotifyRuleCreate, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		builders[i] = c.Create()
+		setFunc(builders[i], i)
+	}
+	return &NotifyRuleCreateBulk{config: c.config, builders: builders}
+}
+
+// Update returns an update builder for NotifyRule.
+func (c *NotifyRuleClient) Update() *NotifyRuleUpdate {
+	mutation := newNotifyRuleMutation(c.config, OpUpdate)
+	return &NotifyRuleUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOne returns an update builder for the given entity.
+func (c *NotifyRuleClient) UpdateOne(_m *NotifyRule) *NotifyRuleUpdateOne {
+	mutation := newNotifyRuleMutation(c.config, OpUpdateOne, withNotifyRule(_m))
+	return &NotifyRuleUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOneID returns an update builder for the given id.
+func (c *NotifyRuleClient) UpdateOneID(id string) *NotifyRuleUpdateOne {
+	mutation := newNotifyRuleMutation(c.config, OpUpdateOne, withNotifyRuleID(id))
+	return &NotifyRuleUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// Delete returns a delete builder for NotifyRule.
+func (c *NotifyRuleClient) Delete() *NotifyRuleDelete {
+	mutation := newNotifyRuleMutation(c.config, OpDelete)
+	return &NotifyRuleDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// DeleteOne returns a builder for deleting the given entity.
+func (c *NotifyRuleClient) DeleteOne(_m *NotifyRule) *NotifyRuleDeleteOne {
+	return c.DeleteOneID(_m.ID)
+}
+
+// DeleteOneID returns a builder for deleting the given entity by its id.
+func (c *NotifyRuleClient) DeleteOneID(id string) *NotifyRuleDeleteOne {
+	builder := c.Delete().Where(notifyrule.ID(id))
+	builder.mutation.id = &id
+	builder.mutation.op = OpDeleteOne
+	return &NotifyRuleDeleteOne{builder}
+}
+
+// Query returns a query builder for NotifyRule.
+func (c *NotifyRuleClient) Query() *NotifyRuleQuery {
+	return &NotifyRuleQuery{
+		config: c.config,
+		ctx:    &QueryContext{Type: TypeNotifyRule},
+		inters: c.Interceptors(),
+	}
+}
+
+// Get returns a NotifyRule entity by its id.
+func (c *NotifyRuleClient) Get(ctx context.Context, id string) (*NotifyRule, error) {
+	return c.Query().Where(notifyrule.ID(id)).Only(ctx)
+}
+
+// GetX is like Get, but panics if an error occurs.
+func (c *NotifyRuleClient) GetX(ctx context.Context, id string) *NotifyRule {
+	obj, err := c.Get(ctx, id)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// Hooks returns the client hooks.
+func (c *NotifyRuleClient) Hooks() []Hook {
+	return c.hooks.NotifyRule
+}
+
+// Interceptors returns the client interceptors.
+func (c *NotifyRuleClient) Interceptors() []Interceptor {
+	return c.inters.NotifyRule
+}
+
+func (c *NotifyRuleClient) mutate(ctx context.Context, m *NotifyRuleMutation) (Value, error) {
+	switch m.Op() {
+	case OpCreate:
+		return (&NotifyRuleCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdate:
+		return (&NotifyRuleUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdateOne:
+		return (&NotifyRuleUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpDelete, OpDeleteOne:
+		return (&NotifyRuleDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
+	default:
+		return nil, fmt.Errorf("ent: unknown NotifyRule mutation op: %q", m.Op())
+	}
+}
+
 // PermissionRuleClient is a client for the PermissionRule schema.
 type PermissionRuleClient struct {
 	config
@@ -3364,6 +4361,139 @@ func (c *ToolSessionClient) mutate(ctx context.Context, m *ToolSessionMutation)
 	}
 }
 
+// UsageRecordClient is a client for the UsageRecord schema.
+type UsageRecordClient struct {
+	config
+}
+
+// NewUsageRecordClient returns a client for the UsageRecord from the given config.
+func NewUsageRecordClient(c config) *UsageRecordClient {
+	return &UsageRecordClient{config: c}
+}
+
+// Use adds a list of mutation hooks to the hooks stack.
+// A call to `Use(f, g, h)` equals to `usagerecord.Hooks(f(g(h())))`.
+func (c *UsageRecordClient) Use(hooks ...Hook) {
+	c.hooks.UsageRecord = append(c.hooks.UsageRecord, hooks...)
+}
+
+// Intercept adds a list of query interceptors to the interceptors stack.
+// A call to `Intercept(f, g, h)` equals to `usagerecord.Intercept(f(g(h())))`.
+func (c *UsageRecordClient) Intercept(interceptors ...Interceptor) {
+	c.inters.UsageRecord = append(c.inters.UsageRecord, interceptors...)
+}
+
+// Create returns a builder for creating a UsageRecord entity.
+func (c *UsageRecordClient) Create() *UsageRecordCreate {
+	mutation := newUsageRecordMutation(c.config, OpCreate)
+	return &UsageRecordCreate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// CreateBulk returns a builder for creating a bulk of UsageRecord entities.
+func (c *UsageRecordClient) CreateBulk(builders ...*UsageRecordCreate) *UsageRecordCreateBulk {
+	return &UsageRecordCreateBulk{config: c.config, builders: builders}
+}
+
+// MapCreateBulk creates a bulk creation builder from the given slice. For each item in the slice, the function creates
+// a builder and applies setFunc on it.
+func (c *UsageRecordClient) MapCreateBulk(slice any, setFunc func(*UsageRecordCreate, int)) *UsageRecordCreateBulk {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice {
+		return &UsageRecordCreateBulk{err: fmt.Errorf("calling to UsageRecordClient.MapCreateBulk with wrong type %T, need slice", slice)}
+	}
+	builders := make([]*UsageRecordCreate, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		builders[i] = c.Create()
+		setFunc(builders[i], i)
+	}
+	return &UsageRecordCreateBulk{config: c.config, builders: builders}
+}
+
+// Update returns an update builder for UsageRecord.
+func (c *UsageRecordClient) Update() *UsageRecordUpdate {
+	mutation := newUsageRecordMutation(c.config, OpUpdate)
+	return &UsageRecordUpdate{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOne returns an update builder for the given entity.
+func (c *UsageRecordClient) UpdateOne(_m *UsageRecord) *UsageRecordUpdateOne {
+	mutation := newUsageRecordMutation(c.config, OpUpdateOne, withUsageRecord(_m))
+	return &UsageRecordUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// UpdateOneID returns an update builder for the given id.
+func (c *UsageRecordClient) UpdateOneID(id string) *UsageRecordUpdateOne {
+	mutation := newUsageRecordMutation(c.config, OpUpdateOne, withUsageRecordID(id))
+	return &UsageRecordUpdateOne{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// Delete returns a delete builder for UsageRecord.
+func (c *UsageRecordClient) Delete() *UsageRecordDelete {
+	mutation := newUsageRecordMutation(c.config, OpDelete)
+	return &UsageRecordDelete{config: c.config, hooks: c.Hooks(), mutation: mutation}
+}
+
+// DeleteOne returns a builder for deleting the given entity.
+func (c *UsageRecordClient) DeleteOne(_m *UsageRecord) *UsageRecordDeleteOne {
+	return c.DeleteOneID(_m.ID)
+}
+
+// DeleteOneID returns a builder for deleting the given entity by its id.
+func (c *UsageRecordClient) DeleteOneID(id string) *UsageRecordDeleteOne {
+	builder := c.Delete().Where(usagerecord.ID(id))
+	builder.mutation.id = &id
+	builder.mutation.op = OpDeleteOne
+	return &UsageRecordDeleteOne{builder}
+}
+
+// Query returns a query builder for UsageRecord.
+func (c *UsageRecordClient) Query() *UsageRecordQuery {
+	return &UsageRecordQuery{
+		config: c.config,
+		ctx:    &QueryContext{Type: TypeUsageRecord},
+		inters: c.Interceptors(),
+	}
+}
+
+// Get returns a UsageRecord entity by its id.
+func (c *UsageRecordClient) Get(ctx context.Context, id string) (*UsageRecord, error) {
+	return c.Query().Where(usagerecord.ID(id)).Only(ctx)
+}
+
+// GetX is like Get, but panics if an error occurs.
+func (c *UsageRecordClient) GetX(ctx context.Context, id string) *UsageRecord {
+	obj, err := c.Get(ctx, id)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// Hooks returns the client hooks.
+func (c *UsageRecordClient) Hooks() []Hook {
+	return c.hooks.UsageRecord
+}
+
+// Interceptors returns the client interceptors.
+func (c *UsageRecordClient) Interceptors() []Interceptor {
+	return c.inters.UsageRecord
+}
+
+func (c *UsageRecordClient) mutate(ctx context.Context, m *UsageRecordMutation) (Value, error) {
+	switch m.Op() {
+	case OpCreate:
+		return (&UsageRecordCreate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdate:
+		return (&UsageRecordUpdate{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpUpdateOne:
+		return (&UsageRecordUpdateOne{config: c.config, hooks: c.Hooks(), mutation: m}).Save(ctx)
+	case OpDelete, OpDeleteOne:
+		return (&UsageRecordDelete{config: c.config, hooks: c.Hooks(), mutation: m}).Exec(ctx)
+	default:
+		return nil, fmt.Errorf("ent: unknown UsageRecord mutation op: %q", m.Op())
+	}
+}
+
 // UserClient is a client for the User schema.
 type UserClient struct {
 	config
@@ -3516,15 +4646,19 @@ func (c *UserClient) mutate(ctx context.Context, m *UserMutation) (Value, error)
 // hooks and interceptors per client, for fast access.
 type (
 	hooks struct {
-		AccountBinding, AgentRuntime, AttachToken, ChannelAccount, CollaborationEvent,
-		ConfigSection, CronJob, IdempotencyRecord, Membership, ModelCatalog, ModelRoute,
-		NotificationBinding, NotificationRoute, PermissionRule, Prompt, PromptBinding,
-		Provider, Run, RunStep, Tenant, ToolEvent, ToolSession, User []ent.Hook
+		APIKey, AccountBinding, AgentRuntime, ApprovalRequest, AttachToken, AuditLog,
+		ChannelAccount, CollaborationEvent, ConfigSection, CronJob, FeedEntry,
+		FeedSubscription, Feedback, IdempotencyRecord, Membership, ModelCatalog,
+		ModelRoute, NotificationBinding, NotificationRoute, NotifyRule, PermissionRule,
+		Prompt, PromptBinding, Provider, Run, RunStep, Tenant, ToolEvent, ToolSession,
+		UsageRecord, User []ent.Hook
 	}
 	inters struct {
-		AccountBinding, AgentRuntime, AttachToken, ChannelAccount, CollaborationEvent,
-		ConfigSection, CronJob, IdempotencyRecord, Membership, ModelCatalog, ModelRoute,
-		NotificationBinding, NotificationRoute, PermissionRule, Prompt, PromptBinding,
-		Provider, Run, RunStep, Tenant, ToolEvent, ToolSession, User []ent.Interceptor
+		APIKey, AccountBinding, AgentRuntime, ApprovalRequest, AttachToken, AuditLog,
+		ChannelAccount, CollaborationEvent, ConfigSection, CronJob, FeedEntry,
+		FeedSubscription, Feedback, IdempotencyRecord, Membership, ModelCatalog,
+		ModelRoute, NotificationBinding, NotificationRoute, NotifyRule, PermissionRule,
+		Prompt, PromptBinding, Provider, Run, RunStep, Tenant, ToolEvent, ToolSession,
+		UsageRecord, User []ent.Interceptor
 	}
 )
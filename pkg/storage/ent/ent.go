@@ -8,17 +8,24 @@ import (
 	"fmt"
 	"nekobot/pkg/storage/ent/accountbinding"
 	"nekobot/pkg/storage/ent/agentruntime"
+	"nekobot/pkg/storage/ent/apikey"
+	"nekobot/pkg/storage/ent/approvalrequest"
 	"nekobot/pkg/storage/ent/attachtoken"
+	"nekobot/pkg/storage/ent/auditlog"
 	"nekobot/pkg/storage/ent/channelaccount"
 	"nekobot/pkg/storage/ent/collaborationevent"
 	"nekobot/pkg/storage/ent/configsection"
 	"nekobot/pkg/storage/ent/cronjob"
+	"nekobot/pkg/storage/ent/feedback"
+	"nekobot/pkg/storage/ent/feedentry"
+	"nekobot/pkg/storage/ent/feedsubscription"
 	"nekobot/pkg/storage/ent/idempotencyrecord"
 	"nekobot/pkg/storage/ent/membership"
 	"nekobot/pkg/storage/ent/modelcatalog"
 	"nekobot/pkg/storage/ent/modelroute"
 	"nekobot/pkg/storage/ent/notificationbinding"
 	"nekobot/pkg/storage/ent/notificationroute"
+	"nekobot/pkg/storage/ent/notifyrule"
 	"nekobot/pkg/storage/ent/permissionrule"
 	"nekobot/pkg/storage/ent/prompt"
 	"nekobot/pkg/storage/ent/promptbinding"
@@ -28,6 +35,7 @@ import (
 	"nekobot/pkg/storage/ent/tenant"
 	"nekobot/pkg/storage/ent/toolevent"
 	"nekobot/pkg/storage/ent/toolsession"
+	"nekobot/pkg/storage/ent/usagerecord"
 	"nekobot/pkg/storage/ent/user"
 	"reflect"
 	"sync"
@@ -95,19 +103,26 @@ var (
 func checkColumn(t, c string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
+			apikey.Table:              apikey.ValidColumn,
 			accountbinding.Table:      accountbinding.ValidColumn,
 			agentruntime.Table:        agentruntime.ValidColumn,
+			approvalrequest.Table:     approvalrequest.ValidColumn,
 			attachtoken.Table:         attachtoken.ValidColumn,
+			auditlog.Table:            auditlog.ValidColumn,
 			channelaccount.Table:      channelaccount.ValidColumn,
 			collaborationevent.Table:  collaborationevent.ValidColumn,
 			configsection.Table:       configsection.ValidColumn,
 			cronjob.Table:             cronjob.ValidColumn,
+			feedentry.Table:           feedentry.ValidColumn,
+			feedsubscription.Table:    feedsubscription.ValidColumn,
+			feedback.Table:            feedback.ValidColumn,
 			idempotencyrecord.Table:   idempotencyrecord.ValidColumn,
 			membership.Table:          membership.ValidColumn,
 			modelcatalog.Table:        modelcatalog.ValidColumn,
 			modelroute.Table:          modelroute.ValidColumn,
 			notificationbinding.Table: notificationbinding.ValidColumn,
 			notificationroute.Table:   notificationroute.ValidColumn,
+			notifyrule.Table:          notifyrule.ValidColumn,
 			permissionrule.Table:      permissionrule.ValidColumn,
 			prompt.Table:              prompt.ValidColumn,
 			promptbinding.Table:       promptbinding.ValidColumn,
@@ -117,6 +132,7 @@ func checkColumn(t, c string) error {
 			tenant.Table:              tenant.ValidColumn,
 			toolevent.Table:           toolevent.ValidColumn,
 			toolsession.Table:         toolsession.ValidColumn,
+			usagerecord.Table:         usagerecord.ValidColumn,
 			user.Table:                user.ValidColumn,
 		})
 	})
@@ -0,0 +1,392 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nekobot/pkg/storage/ent/feedback"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// FeedbackCreate is the builder for creating a Feedback entity.
+type FeedbackCreate struct {
+	config
+	mutation *FeedbackMutation
+	hooks    []Hook
+}
+
+// SetChannel sets the "channel" field.
+func (_c *FeedbackCreate) SetChannel(v string) *FeedbackCreate {
+	_c.mutation.SetChannel(v)
+	return _c
+}
+
+// SetSessionID sets the "session_id" field.
+func (_c *FeedbackCreate) SetSessionID(v string) *FeedbackCreate {
+	_c.mutation.SetSessionID(v)
+	return _c
+}
+
+// SetMessageID sets the "message_id" field.
+func (_c *FeedbackCreate) SetMessageID(v string) *FeedbackCreate {
+	_c.mutation.SetMessageID(v)
+	return _c
+}
+
+// SetUserID sets the "user_id" field.
+func (_c *FeedbackCreate) SetUserID(v string) *FeedbackCreate {
+	_c.mutation.SetUserID(v)
+	return _c
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_c *FeedbackCreate) SetNillableUserID(v *string) *FeedbackCreate {
+	if v != nil {
+		_c.SetUserID(*v)
+	}
+	return _c
+}
+
+// SetRating sets the "rating" field.
+func (_c *FeedbackCreate) SetRating(v int) *FeedbackCreate {
+	_c.mutation.SetRating(v)
+	return _c
+}
+
+// SetComment sets the "comment" field.
+func (_c *FeedbackCreate) SetComment(v string) *FeedbackCreate {
+	_c.mutation.SetComment(v)
+	return _c
+}
+
+// SetNillableComment sets the "comment" field if the given value is not nil.
+func (_c *FeedbackCreate) SetNillableComment(v *string) *FeedbackCreate {
+	if v != nil {
+		_c.SetComment(*v)
+	}
+	return _c
+}
+
+// SetResponse sets the "response" field.
+func (_c *FeedbackCreate) SetResponse(v string) *FeedbackCreate {
+	_c.mutation.SetResponse(v)
+	return _c
+}
+
+// SetNillableResponse sets the "response" field if the given value is not nil.
+func (_c *FeedbackCreate) SetNillableResponse(v *string) *FeedbackCreate {
+	if v != nil {
+		_c.SetResponse(*v)
+	}
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *FeedbackCreate) SetCreatedAt(v time.Time) *FeedbackCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *FeedbackCreate) SetNillableCreatedAt(v *time.Time) *FeedbackCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_c *FeedbackCreate) SetUpdatedAt(v time.Time) *FeedbackCreate {
+	_c.mutation.SetUpdatedAt(v)
+	return _c
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (_c *FeedbackCreate) SetNillableUpdatedAt(v *time.Time) *FeedbackCreate {
+	if v != nil {
+		_c.SetUpdatedAt(*v)
+	}
+	return _c
+}
+
+// SetID sets the "id" field.
+func (_c *FeedbackCreate) SetID(v string) *FeedbackCreate {
+	_c.mutation.SetID(v)
+	return _c
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (_c *FeedbackCreate) SetNillableID(v *string) *FeedbackCreate {
+	if v != nil {
+		_c.SetID(*v)
+	}
+	return _c
+}
+
+// Mutation returns the FeedbackMutation object of the builder.
+func (_c *FeedbackCreate) Mutation() *FeedbackMutation {
+	return _c.mutation
+}
+
+// Save creates the Feedback in the database.
+func (_c *FeedbackCreate) Save(ctx context.Context) (*Feedback, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *FeedbackCreate) SaveX(ctx context.Context) *Feedback {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *FeedbackCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *FeedbackCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *FeedbackCreate) defaults() {
+	if _, ok := _c.mutation.UserID(); !ok {
+		v := feedback.DefaultUserID
+		_c.mutation.SetUserID(v)
+	}
+	if _, ok := _c.mutation.Comment(); !ok {
+		v := feedback.DefaultComment
+		_c.mutation.SetComment(v)
+	}
+	if _, ok := _c.mutation.Response(); !ok {
+		v := feedback.DefaultResponse
+		_c.mutation.SetResponse(v)
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := feedback.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		v := feedback.DefaultUpdatedAt()
+		_c.mutation.SetUpdatedAt(v)
+	}
+	if _, ok := _c.mutation.ID(); !ok {
+		v := feedback.DefaultID()
+		_c.mutation.SetID(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *FeedbackCreate) check() error {
+	if _, ok := _c.mutation.Channel(); !ok {
+		return &ValidationError{Name: "channel", err: errors.New(`ent: missing required field "Feedback.channel"`)}
+	}
+	if v, ok := _c.mutation.Channel(); ok {
+		if err := feedback.ChannelValidator(v); err != nil {
+			return &ValidationError{Name: "channel", err: fmt.Errorf(`ent: validator failed for field "Feedback.channel": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.SessionID(); !ok {
+		return &ValidationError{Name: "session_id", err: errors.New(`ent: missing required field "Feedback.session_id"`)}
+	}
+	if v, ok := _c.mutation.SessionID(); ok {
+		if err := feedback.SessionIDValidator(v); err != nil {
+			return &ValidationError{Name: "session_id", err: fmt.Errorf(`ent: validator failed for field "Feedback.session_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.MessageID(); !ok {
+		return &ValidationError{Name: "message_id", err: errors.New(`ent: missing required field "Feedback.message_id"`)}
+	}
+	if v, ok := _c.mutation.MessageID(); ok {
+		if err := feedback.MessageIDValidator(v); err != nil {
+			return &ValidationError{Name: "message_id", err: fmt.Errorf(`ent: validator failed for field "Feedback.message_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "Feedback.user_id"`)}
+	}
+	if _, ok := _c.mutation.Rating(); !ok {
+		return &ValidationError{Name: "rating", err: errors.New(`ent: missing required field "Feedback.rating"`)}
+	}
+	if _, ok := _c.mutation.Comment(); !ok {
+		return &ValidationError{Name: "comment", err: errors.New(`ent: missing required field "Feedback.comment"`)}
+	}
+	if _, ok := _c.mutation.Response(); !ok {
+		return &ValidationError{Name: "response", err: errors.New(`ent: missing required field "Feedback.response"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Feedback.created_at"`)}
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "Feedback.updated_at"`)}
+	}
+	return nil
+}
+
+func (_c *FeedbackCreate) sqlSave(ctx context.Context) (*Feedback, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(string); ok {
+			_node.ID = id
+		} else {
+			return nil, fmt.Errorf("unexpected Feedback.ID type: %T", _spec.ID.Value)
+		}
+	}
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *FeedbackCreate) createSpec() (*Feedback, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Feedback{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(feedback.Table, sqlgraph.NewFieldSpec(feedback.FieldID, field.TypeString))
+	)
+	if id, ok := _c.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := _c.mutation.Channel(); ok {
+		_spec.SetField(feedback.FieldChannel, field.TypeString, value)
+		_node.Channel = value
+	}
+	if value, ok := _c.mutation.SessionID(); ok {
+		_spec.SetField(feedback.FieldSessionID, field.TypeString, value)
+		_node.SessionID = value
+	}
+	if value, ok := _c.mutation.MessageID(); ok {
+		_spec.SetField(feedback.FieldMessageID, field.TypeString, value)
+		_node.MessageID = value
+	}
+	if value, ok := _c.mutation.UserID(); ok {
+		_spec.SetField(feedback.FieldUserID, field.TypeString, value)
+		_node.UserID = value
+	}
+	if value, ok := _c.mutation.Rating(); ok {
+		_spec.SetField(feedback.FieldRating, field.TypeInt, value)
+		_node.Rating = value
+	}
+	if value, ok := _c.mutation.Comment(); ok {
+		_spec.SetField(feedback.FieldComment, field.TypeString, value)
+		_node.Comment = value
+	}
+	if value, ok := _c.mutation.Response(); ok {
+		_spec.SetField(feedback.FieldResponse, field.TypeString, value)
+		_node.Response = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(feedback.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.UpdatedAt(); ok {
+		_spec.SetField(feedback.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	return _node, _spec
+}
+
+// FeedbackCreateBulk is the builder for creating many Feedback entities in bulk.
+type FeedbackCreateBulk struct {
+	config
+	err      error
+	builders []*FeedbackCreate
+}
+
+// Save creates the Feedback entities in the database.
+func (_c *FeedbackCreateBulk) Save(ctx context.Context) ([]*Feedback, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*Feedback, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*FeedbackMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *FeedbackCreateBulk) SaveX(ctx context.Context) []*Feedback {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *FeedbackCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *FeedbackCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,516 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nekobot/pkg/storage/ent/feedback"
+	"nekobot/pkg/storage/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// FeedbackUpdate is the builder for updating Feedback entities.
+type FeedbackUpdate struct {
+	config
+	hooks    []Hook
+	mutation *FeedbackMutation
+}
+
+// Where appends a list predicates to the FeedbackUpdate builder.
+func (_u *FeedbackUpdate) Where(ps ...predicate.Feedback) *FeedbackUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetChannel sets the "channel" field.
+func (_u *FeedbackUpdate) SetChannel(v string) *FeedbackUpdate {
+	_u.mutation.SetChannel(v)
+	return _u
+}
+
+// SetNillableChannel sets the "channel" field if the given value is not nil.
+func (_u *FeedbackUpdate) SetNillableChannel(v *string) *FeedbackUpdate {
+	if v != nil {
+		_u.SetChannel(*v)
+	}
+	return _u
+}
+
+// SetSessionID sets the "session_id" field.
+func (_u *FeedbackUpdate) SetSessionID(v string) *FeedbackUpdate {
+	_u.mutation.SetSessionID(v)
+	return _u
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (_u *FeedbackUpdate) SetNillableSessionID(v *string) *FeedbackUpdate {
+	if v != nil {
+		_u.SetSessionID(*v)
+	}
+	return _u
+}
+
+// SetMessageID sets the "message_id" field.
+func (_u *FeedbackUpdate) SetMessageID(v string) *FeedbackUpdate {
+	_u.mutation.SetMessageID(v)
+	return _u
+}
+
+// SetNillableMessageID sets the "message_id" field if the given value is not nil.
+func (_u *FeedbackUpdate) SetNillableMessageID(v *string) *FeedbackUpdate {
+	if v != nil {
+		_u.SetMessageID(*v)
+	}
+	return _u
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *FeedbackUpdate) SetUserID(v string) *FeedbackUpdate {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *FeedbackUpdate) SetNillableUserID(v *string) *FeedbackUpdate {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// SetRating sets the "rating" field.
+func (_u *FeedbackUpdate) SetRating(v int) *FeedbackUpdate {
+	_u.mutation.ResetRating()
+	_u.mutation.SetRating(v)
+	return _u
+}
+
+// SetNillableRating sets the "rating" field if the given value is not nil.
+func (_u *FeedbackUpdate) SetNillableRating(v *int) *FeedbackUpdate {
+	if v != nil {
+		_u.SetRating(*v)
+	}
+	return _u
+}
+
+// AddRating adds value to the "rating" field.
+func (_u *FeedbackUpdate) AddRating(v int) *FeedbackUpdate {
+	_u.mutation.AddRating(v)
+	return _u
+}
+
+// SetComment sets the "comment" field.
+func (_u *FeedbackUpdate) SetComment(v string) *FeedbackUpdate {
+	_u.mutation.SetComment(v)
+	return _u
+}
+
+// SetNillableComment sets the "comment" field if the given value is not nil.
+func (_u *FeedbackUpdate) SetNillableComment(v *string) *FeedbackUpdate {
+	if v != nil {
+		_u.SetComment(*v)
+	}
+	return _u
+}
+
+// SetResponse sets the "response" field.
+func (_u *FeedbackUpdate) SetResponse(v string) *FeedbackUpdate {
+	_u.mutation.SetResponse(v)
+	return _u
+}
+
+// SetNillableResponse sets the "response" field if the given value is not nil.
+func (_u *FeedbackUpdate) SetNillableResponse(v *string) *FeedbackUpdate {
+	if v != nil {
+		_u.SetResponse(*v)
+	}
+	return _u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *FeedbackUpdate) SetUpdatedAt(v time.Time) *FeedbackUpdate {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// Mutation returns the FeedbackMutation object of the builder.
+func (_u *FeedbackUpdate) Mutation() *FeedbackMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *FeedbackUpdate) Save(ctx context.Context) (int, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *FeedbackUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *FeedbackUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *FeedbackUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_u *FeedbackUpdate) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
+		v := feedback.UpdateDefaultUpdatedAt()
+		_u.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *FeedbackUpdate) check() error {
+	if v, ok := _u.mutation.Channel(); ok {
+		if err := feedback.ChannelValidator(v); err != nil {
+			return &ValidationError{Name: "channel", err: fmt.Errorf(`ent: validator failed for field "Feedback.channel": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.SessionID(); ok {
+		if err := feedback.SessionIDValidator(v); err != nil {
+			return &ValidationError{Name: "session_id", err: fmt.Errorf(`ent: validator failed for field "Feedback.session_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.MessageID(); ok {
+		if err := feedback.MessageIDValidator(v); err != nil {
+			return &ValidationError{Name: "message_id", err: fmt.Errorf(`ent: validator failed for field "Feedback.message_id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *FeedbackUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(feedback.Table, feedback.Columns, sqlgraph.NewFieldSpec(feedback.FieldID, field.TypeString))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Channel(); ok {
+		_spec.SetField(feedback.FieldChannel, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.SessionID(); ok {
+		_spec.SetField(feedback.FieldSessionID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.MessageID(); ok {
+		_spec.SetField(feedback.FieldMessageID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.UserID(); ok {
+		_spec.SetField(feedback.FieldUserID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Rating(); ok {
+		_spec.SetField(feedback.FieldRating, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedRating(); ok {
+		_spec.AddField(feedback.FieldRating, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Comment(); ok {
+		_spec.SetField(feedback.FieldComment, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Response(); ok {
+		_spec.SetField(feedback.FieldResponse, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(feedback.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{feedback.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// FeedbackUpdateOne is the builder for updating a single Feedback entity.
+type FeedbackUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *FeedbackMutation
+}
+
+// SetChannel sets the "channel" field.
+func (_u *FeedbackUpdateOne) SetChannel(v string) *FeedbackUpdateOne {
+	_u.mutation.SetChannel(v)
+	return _u
+}
+
+// SetNillableChannel sets the "channel" field if the given value is not nil.
+func (_u *FeedbackUpdateOne) SetNillableChannel(v *string) *FeedbackUpdateOne {
+	if v != nil {
+		_u.SetChannel(*v)
+	}
+	return _u
+}
+
+// SetSessionID sets the "session_id" field.
+func (_u *FeedbackUpdateOne) SetSessionID(v string) *FeedbackUpdateOne {
+	_u.mutation.SetSessionID(v)
+	return _u
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (_u *FeedbackUpdateOne) SetNillableSessionID(v *string) *FeedbackUpdateOne {
+	if v != nil {
+		_u.SetSessionID(*v)
+	}
+	return _u
+}
+
+// SetMessageID sets the "message_id" field.
+func (_u *FeedbackUpdateOne) SetMessageID(v string) *FeedbackUpdateOne {
+	_u.mutation.SetMessageID(v)
+	return _u
+}
+
+// SetNillableMessageID sets the "message_id" field if the given value is not nil.
+func (_u *FeedbackUpdateOne) SetNillableMessageID(v *string) *FeedbackUpdateOne {
+	if v != nil {
+		_u.SetMessageID(*v)
+	}
+	return _u
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *FeedbackUpdateOne) SetUserID(v string) *FeedbackUpdateOne {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *FeedbackUpdateOne) SetNillableUserID(v *string) *FeedbackUpdateOne {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// SetRating sets the "rating" field.
+func (_u *FeedbackUpdateOne) SetRating(v int) *FeedbackUpdateOne {
+	_u.mutation.ResetRating()
+	_u.mutation.SetRating(v)
+	return _u
+}
+
+// SetNillableRating sets the "rating" field if the given value is not nil.
+func (_u *FeedbackUpdateOne) SetNillableRating(v *int) *FeedbackUpdateOne {
+	if v != nil {
+		_u.SetRating(*v)
+	}
+	return _u
+}
+
+// AddRating adds value to the "rating" field.
+func (_u *FeedbackUpdateOne) AddRating(v int) *FeedbackUpdateOne {
+	_u.mutation.AddRating(v)
+	return _u
+}
+
+// SetComment sets the "comment" field.
+func (_u *FeedbackUpdateOne) SetComment(v string) *FeedbackUpdateOne {
+	_u.mutation.SetComment(v)
+	return _u
+}
+
+// SetNillableComment sets the "comment" field if the given value is not nil.
+func (_u *FeedbackUpdateOne) SetNillableComment(v *string) *FeedbackUpdateOne {
+	if v != nil {
+		_u.SetComment(*v)
+	}
+	return _u
+}
+
+// SetResponse sets the "response" field.
+func (_u *FeedbackUpdateOne) SetResponse(v string) *FeedbackUpdateOne {
+	_u.mutation.SetResponse(v)
+	return _u
+}
+
+// SetNillableResponse sets the "response" field if the given value is not nil.
+func (_u *FeedbackUpdateOne) SetNillableResponse(v *string) *FeedbackUpdateOne {
+	if v != nil {
+		_u.SetResponse(*v)
+	}
+	return _u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *FeedbackUpdateOne) SetUpdatedAt(v time.Time) *FeedbackUpdateOne {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// Mutation returns the FeedbackMutation object of the builder.
+func (_u *FeedbackUpdateOne) Mutation() *FeedbackMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the FeedbackUpdate builder.
+func (_u *FeedbackUpdateOne) Where(ps ...predicate.Feedback) *FeedbackUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *FeedbackUpdateOne) Select(field string, fields ...string) *FeedbackUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated Feedback entity.
+func (_u *FeedbackUpdateOne) Save(ctx context.Context) (*Feedback, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *FeedbackUpdateOne) SaveX(ctx context.Context) *Feedback {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *FeedbackUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *FeedbackUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_u *FeedbackUpdateOne) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
+		v := feedback.UpdateDefaultUpdatedAt()
+		_u.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *FeedbackUpdateOne) check() error {
+	if v, ok := _u.mutation.Channel(); ok {
+		if err := feedback.ChannelValidator(v); err != nil {
+			return &ValidationError{Name: "channel", err: fmt.Errorf(`ent: validator failed for field "Feedback.channel": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.SessionID(); ok {
+		if err := feedback.SessionIDValidator(v); err != nil {
+			return &ValidationError{Name: "session_id", err: fmt.Errorf(`ent: validator failed for field "Feedback.session_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.MessageID(); ok {
+		if err := feedback.MessageIDValidator(v); err != nil {
+			return &ValidationError{Name: "message_id", err: fmt.Errorf(`ent: validator failed for field "Feedback.message_id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *FeedbackUpdateOne) sqlSave(ctx context.Context) (_node *Feedback, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(feedback.Table, feedback.Columns, sqlgraph.NewFieldSpec(feedback.FieldID, field.TypeString))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Feedback.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, feedback.FieldID)
+		for _, f := range fields {
+			if !feedback.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != feedback.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Channel(); ok {
+		_spec.SetField(feedback.FieldChannel, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.SessionID(); ok {
+		_spec.SetField(feedback.FieldSessionID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.MessageID(); ok {
+		_spec.SetField(feedback.FieldMessageID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.UserID(); ok {
+		_spec.SetField(feedback.FieldUserID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Rating(); ok {
+		_spec.SetField(feedback.FieldRating, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedRating(); ok {
+		_spec.AddField(feedback.FieldRating, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Comment(); ok {
+		_spec.SetField(feedback.FieldComment, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Response(); ok {
+		_spec.SetField(feedback.FieldResponse, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(feedback.FieldUpdatedAt, field.TypeTime, value)
+	}
+	_node = &Feedback{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{feedback.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,460 @@
+// Code generated by ent, DO NOT EDIT.
+
+package feedentry
+
+import (
+	"nekobot/pkg/storage/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLTE(FieldID, id))
+}
+
+// IDEqualFold applies the EqualFold predicate on the ID field.
+func IDEqualFold(id string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEqualFold(FieldID, id))
+}
+
+// IDContainsFold applies the ContainsFold predicate on the ID field.
+func IDContainsFold(id string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldContainsFold(FieldID, id))
+}
+
+// SubscriptionID applies equality check predicate on the "subscription_id" field. It's identical to SubscriptionIDEQ.
+func SubscriptionID(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldSubscriptionID, v))
+}
+
+// EntryKey applies equality check predicate on the "entry_key" field. It's identical to EntryKeyEQ.
+func EntryKey(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldEntryKey, v))
+}
+
+// Title applies equality check predicate on the "title" field. It's identical to TitleEQ.
+func Title(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldTitle, v))
+}
+
+// Link applies equality check predicate on the "link" field. It's identical to LinkEQ.
+func Link(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldLink, v))
+}
+
+// PublishedAt applies equality check predicate on the "published_at" field. It's identical to PublishedAtEQ.
+func PublishedAt(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldPublishedAt, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// SubscriptionIDEQ applies the EQ predicate on the "subscription_id" field.
+func SubscriptionIDEQ(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldSubscriptionID, v))
+}
+
+// SubscriptionIDNEQ applies the NEQ predicate on the "subscription_id" field.
+func SubscriptionIDNEQ(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNEQ(FieldSubscriptionID, v))
+}
+
+// SubscriptionIDIn applies the In predicate on the "subscription_id" field.
+func SubscriptionIDIn(vs ...string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldIn(FieldSubscriptionID, vs...))
+}
+
+// SubscriptionIDNotIn applies the NotIn predicate on the "subscription_id" field.
+func SubscriptionIDNotIn(vs ...string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNotIn(FieldSubscriptionID, vs...))
+}
+
+// SubscriptionIDGT applies the GT predicate on the "subscription_id" field.
+func SubscriptionIDGT(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGT(FieldSubscriptionID, v))
+}
+
+// SubscriptionIDGTE applies the GTE predicate on the "subscription_id" field.
+func SubscriptionIDGTE(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGTE(FieldSubscriptionID, v))
+}
+
+// SubscriptionIDLT applies the LT predicate on the "subscription_id" field.
+func SubscriptionIDLT(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLT(FieldSubscriptionID, v))
+}
+
+// SubscriptionIDLTE applies the LTE predicate on the "subscription_id" field.
+func SubscriptionIDLTE(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLTE(FieldSubscriptionID, v))
+}
+
+// SubscriptionIDContains applies the Contains predicate on the "subscription_id" field.
+func SubscriptionIDContains(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldContains(FieldSubscriptionID, v))
+}
+
+// SubscriptionIDHasPrefix applies the HasPrefix predicate on the "subscription_id" field.
+func SubscriptionIDHasPrefix(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldHasPrefix(FieldSubscriptionID, v))
+}
+
+// SubscriptionIDHasSuffix applies the HasSuffix predicate on the "subscription_id" field.
+func SubscriptionIDHasSuffix(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldHasSuffix(FieldSubscriptionID, v))
+}
+
+// SubscriptionIDEqualFold applies the EqualFold predicate on the "subscription_id" field.
+func SubscriptionIDEqualFold(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEqualFold(FieldSubscriptionID, v))
+}
+
+// SubscriptionIDContainsFold applies the ContainsFold predicate on the "subscription_id" field.
+func SubscriptionIDContainsFold(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldContainsFold(FieldSubscriptionID, v))
+}
+
+// EntryKeyEQ applies the EQ predicate on the "entry_key" field.
+func EntryKeyEQ(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldEntryKey, v))
+}
+
+// EntryKeyNEQ applies the NEQ predicate on the "entry_key" field.
+func EntryKeyNEQ(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNEQ(FieldEntryKey, v))
+}
+
+// EntryKeyIn applies the In predicate on the "entry_key" field.
+func EntryKeyIn(vs ...string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldIn(FieldEntryKey, vs...))
+}
+
+// EntryKeyNotIn applies the NotIn predicate on the "entry_key" field.
+func EntryKeyNotIn(vs ...string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNotIn(FieldEntryKey, vs...))
+}
+
+// EntryKeyGT applies the GT predicate on the "entry_key" field.
+func EntryKeyGT(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGT(FieldEntryKey, v))
+}
+
+// EntryKeyGTE applies the GTE predicate on the "entry_key" field.
+func EntryKeyGTE(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGTE(FieldEntryKey, v))
+}
+
+// EntryKeyLT applies the LT predicate on the "entry_key" field.
+func EntryKeyLT(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLT(FieldEntryKey, v))
+}
+
+// EntryKeyLTE applies the LTE predicate on the "entry_key" field.
+func EntryKeyLTE(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLTE(FieldEntryKey, v))
+}
+
+// EntryKeyContains applies the Contains predicate on the "entry_key" field.
+func EntryKeyContains(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldContains(FieldEntryKey, v))
+}
+
+// EntryKeyHasPrefix applies the HasPrefix predicate on the "entry_key" field.
+func EntryKeyHasPrefix(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldHasPrefix(FieldEntryKey, v))
+}
+
+// EntryKeyHasSuffix applies the HasSuffix predicate on the "entry_key" field.
+func EntryKeyHasSuffix(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldHasSuffix(FieldEntryKey, v))
+}
+
+// EntryKeyEqualFold applies the EqualFold predicate on the "entry_key" field.
+func EntryKeyEqualFold(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEqualFold(FieldEntryKey, v))
+}
+
+// EntryKeyContainsFold applies the ContainsFold predicate on the "entry_key" field.
+func EntryKeyContainsFold(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldContainsFold(FieldEntryKey, v))
+}
+
+// TitleEQ applies the EQ predicate on the "title" field.
+func TitleEQ(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldTitle, v))
+}
+
+// TitleNEQ applies the NEQ predicate on the "title" field.
+func TitleNEQ(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNEQ(FieldTitle, v))
+}
+
+// TitleIn applies the In predicate on the "title" field.
+func TitleIn(vs ...string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldIn(FieldTitle, vs...))
+}
+
+// TitleNotIn applies the NotIn predicate on the "title" field.
+func TitleNotIn(vs ...string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNotIn(FieldTitle, vs...))
+}
+
+// TitleGT applies the GT predicate on the "title" field.
+func TitleGT(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGT(FieldTitle, v))
+}
+
+// TitleGTE applies the GTE predicate on the "title" field.
+func TitleGTE(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGTE(FieldTitle, v))
+}
+
+// TitleLT applies the LT predicate on the "title" field.
+func TitleLT(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLT(FieldTitle, v))
+}
+
+// TitleLTE applies the LTE predicate on the "title" field.
+func TitleLTE(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLTE(FieldTitle, v))
+}
+
+// TitleContains applies the Contains predicate on the "title" field.
+func TitleContains(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldContains(FieldTitle, v))
+}
+
+// TitleHasPrefix applies the HasPrefix predicate on the "title" field.
+func TitleHasPrefix(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldHasPrefix(FieldTitle, v))
+}
+
+// TitleHasSuffix applies the HasSuffix predicate on the "title" field.
+func TitleHasSuffix(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldHasSuffix(FieldTitle, v))
+}
+
+// TitleEqualFold applies the EqualFold predicate on the "title" field.
+func TitleEqualFold(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEqualFold(FieldTitle, v))
+}
+
+// TitleContainsFold applies the ContainsFold predicate on the "title" field.
+func TitleContainsFold(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldContainsFold(FieldTitle, v))
+}
+
+// LinkEQ applies the EQ predicate on the "link" field.
+func LinkEQ(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldLink, v))
+}
+
+// LinkNEQ applies the NEQ predicate on the "link" field.
+func LinkNEQ(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNEQ(FieldLink, v))
+}
+
+// LinkIn applies the In predicate on the "link" field.
+func LinkIn(vs ...string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldIn(FieldLink, vs...))
+}
+
+// LinkNotIn applies the NotIn predicate on the "link" field.
+func LinkNotIn(vs ...string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNotIn(FieldLink, vs...))
+}
+
+// LinkGT applies the GT predicate on the "link" field.
+func LinkGT(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGT(FieldLink, v))
+}
+
+// LinkGTE applies the GTE predicate on the "link" field.
+func LinkGTE(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGTE(FieldLink, v))
+}
+
+// LinkLT applies the LT predicate on the "link" field.
+func LinkLT(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLT(FieldLink, v))
+}
+
+// LinkLTE applies the LTE predicate on the "link" field.
+func LinkLTE(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLTE(FieldLink, v))
+}
+
+// LinkContains applies the Contains predicate on the "link" field.
+func LinkContains(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldContains(FieldLink, v))
+}
+
+// LinkHasPrefix applies the HasPrefix predicate on the "link" field.
+func LinkHasPrefix(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldHasPrefix(FieldLink, v))
+}
+
+// LinkHasSuffix applies the HasSuffix predicate on the "link" field.
+func LinkHasSuffix(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldHasSuffix(FieldLink, v))
+}
+
+// LinkEqualFold applies the EqualFold predicate on the "link" field.
+func LinkEqualFold(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEqualFold(FieldLink, v))
+}
+
+// LinkContainsFold applies the ContainsFold predicate on the "link" field.
+func LinkContainsFold(v string) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldContainsFold(FieldLink, v))
+}
+
+// PublishedAtEQ applies the EQ predicate on the "published_at" field.
+func PublishedAtEQ(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldPublishedAt, v))
+}
+
+// PublishedAtNEQ applies the NEQ predicate on the "published_at" field.
+func PublishedAtNEQ(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNEQ(FieldPublishedAt, v))
+}
+
+// PublishedAtIn applies the In predicate on the "published_at" field.
+func PublishedAtIn(vs ...time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldIn(FieldPublishedAt, vs...))
+}
+
+// PublishedAtNotIn applies the NotIn predicate on the "published_at" field.
+func PublishedAtNotIn(vs ...time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNotIn(FieldPublishedAt, vs...))
+}
+
+// PublishedAtGT applies the GT predicate on the "published_at" field.
+func PublishedAtGT(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGT(FieldPublishedAt, v))
+}
+
+// PublishedAtGTE applies the GTE predicate on the "published_at" field.
+func PublishedAtGTE(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGTE(FieldPublishedAt, v))
+}
+
+// PublishedAtLT applies the LT predicate on the "published_at" field.
+func PublishedAtLT(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLT(FieldPublishedAt, v))
+}
+
+// PublishedAtLTE applies the LTE predicate on the "published_at" field.
+func PublishedAtLTE(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLTE(FieldPublishedAt, v))
+}
+
+// PublishedAtIsNil applies the IsNil predicate on the "published_at" field.
+func PublishedAtIsNil() predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldIsNull(FieldPublishedAt))
+}
+
+// PublishedAtNotNil applies the NotNil predicate on the "published_at" field.
+func PublishedAtNotNil() predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNotNull(FieldPublishedAt))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.FeedEntry) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.FeedEntry) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.FeedEntry) predicate.FeedEntry {
+	return predicate.FeedEntry(sql.NotPredicates(p))
+}
@@ -0,0 +1,329 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nekobot/pkg/storage/ent/feedentry"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// FeedEntryCreate is the builder for creating a FeedEntry entity.
+type FeedEntryCreate struct {
+	config
+	mutation *FeedEntryMutation
+	hooks    []Hook
+}
+
+// SetSubscriptionID sets the "subscription_id" field.
+func (_c *FeedEntryCreate) SetSubscriptionID(v string) *FeedEntryCreate {
+	_c.mutation.SetSubscriptionID(v)
+	return _c
+}
+
+// SetEntryKey sets the "entry_key" field.
+func (_c *FeedEntryCreate) SetEntryKey(v string) *FeedEntryCreate {
+	_c.mutation.SetEntryKey(v)
+	return _c
+}
+
+// SetTitle sets the "title" field.
+func (_c *FeedEntryCreate) SetTitle(v string) *FeedEntryCreate {
+	_c.mutation.SetTitle(v)
+	return _c
+}
+
+// SetNillableTitle sets the "title" field if the given value is not nil.
+func (_c *FeedEntryCreate) SetNillableTitle(v *string) *FeedEntryCreate {
+	if v != nil {
+		_c.SetTitle(*v)
+	}
+	return _c
+}
+
+// SetLink sets the "link" field.
+func (_c *FeedEntryCreate) SetLink(v string) *FeedEntryCreate {
+	_c.mutation.SetLink(v)
+	return _c
+}
+
+// SetNillableLink sets the "link" field if the given value is not nil.
+func (_c *FeedEntryCreate) SetNillableLink(v *string) *FeedEntryCreate {
+	if v != nil {
+		_c.SetLink(*v)
+	}
+	return _c
+}
+
+// SetPublishedAt sets the "published_at" field.
+func (_c *FeedEntryCreate) SetPublishedAt(v time.Time) *FeedEntryCreate {
+	_c.mutation.SetPublishedAt(v)
+	return _c
+}
+
+// SetNillablePublishedAt sets the "published_at" field if the given value is not nil.
+func (_c *FeedEntryCreate) SetNillablePublishedAt(v *time.Time) *FeedEntryCreate {
+	if v != nil {
+		_c.SetPublishedAt(*v)
+	}
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *FeedEntryCreate) SetCreatedAt(v time.Time) *FeedEntryCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *FeedEntryCreate) SetNillableCreatedAt(v *time.Time) *FeedEntryCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetID sets the "id" field.
+func (_c *FeedEntryCreate) SetID(v string) *FeedEntryCreate {
+	_c.mutation.SetID(v)
+	return _c
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (_c *FeedEntryCreate) SetNillableID(v *string) *FeedEntryCreate {
+	if v != nil {
+		_c.SetID(*v)
+	}
+	return _c
+}
+
+// Mutation returns the FeedEntryMutation object of the builder.
+func (_c *FeedEntryCreate) Mutation() *FeedEntryMutation {
+	return _c.mutation
+}
+
+// Save creates the FeedEntry in the database.
+func (_c *FeedEntryCreate) Save(ctx context.Context) (*FeedEntry, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *FeedEntryCreate) SaveX(ctx context.Context) *FeedEntry {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *FeedEntryCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *FeedEntryCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *FeedEntryCreate) defaults() {
+	if _, ok := _c.mutation.Title(); !ok {
+		v := feedentry.DefaultTitle
+		_c.mutation.SetTitle(v)
+	}
+	if _, ok := _c.mutation.Link(); !ok {
+		v := feedentry.DefaultLink
+		_c.mutation.SetLink(v)
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := feedentry.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+	if _, ok := _c.mutation.ID(); !ok {
+		v := feedentry.DefaultID()
+		_c.mutation.SetID(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *FeedEntryCreate) check() error {
+	if _, ok := _c.mutation.SubscriptionID(); !ok {
+		return &ValidationError{Name: "subscription_id", err: errors.New(`ent: missing required field "FeedEntry.subscription_id"`)}
+	}
+	if v, ok := _c.mutation.SubscriptionID(); ok {
+		if err := feedentry.SubscriptionIDValidator(v); err != nil {
+			return &ValidationError{Name: "subscription_id", err: fmt.Errorf(`ent: validator failed for field "FeedEntry.subscription_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.EntryKey(); !ok {
+		return &ValidationError{Name: "entry_key", err: errors.New(`ent: missing required field "FeedEntry.entry_key"`)}
+	}
+	if v, ok := _c.mutation.EntryKey(); ok {
+		if err := feedentry.EntryKeyValidator(v); err != nil {
+			return &ValidationError{Name: "entry_key", err: fmt.Errorf(`ent: validator failed for field "FeedEntry.entry_key": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Title(); !ok {
+		return &ValidationError{Name: "title", err: errors.New(`ent: missing required field "FeedEntry.title"`)}
+	}
+	if _, ok := _c.mutation.Link(); !ok {
+		return &ValidationError{Name: "link", err: errors.New(`ent: missing required field "FeedEntry.link"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "FeedEntry.created_at"`)}
+	}
+	return nil
+}
+
+func (_c *FeedEntryCreate) sqlSave(ctx context.Context) (*FeedEntry, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(string); ok {
+			_node.ID = id
+		} else {
+			return nil, fmt.Errorf("unexpected FeedEntry.ID type: %T", _spec.ID.Value)
+		}
+	}
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *FeedEntryCreate) createSpec() (*FeedEntry, *sqlgraph.CreateSpec) {
+	var (
+		_node = &FeedEntry{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(feedentry.Table, sqlgraph.NewFieldSpec(feedentry.FieldID, field.TypeString))
+	)
+	if id, ok := _c.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := _c.mutation.SubscriptionID(); ok {
+		_spec.SetField(feedentry.FieldSubscriptionID, field.TypeString, value)
+		_node.SubscriptionID = value
+	}
+	if value, ok := _c.mutation.EntryKey(); ok {
+		_spec.SetField(feedentry.FieldEntryKey, field.TypeString, value)
+		_node.EntryKey = value
+	}
+	if value, ok := _c.mutation.Title(); ok {
+		_spec.SetField(feedentry.FieldTitle, field.TypeString, value)
+		_node.Title = value
+	}
+	if value, ok := _c.mutation.Link(); ok {
+		_spec.SetField(feedentry.FieldLink, field.TypeString, value)
+		_node.Link = value
+	}
+	if value, ok := _c.mutation.PublishedAt(); ok {
+		_spec.SetField(feedentry.FieldPublishedAt, field.TypeTime, value)
+		_node.PublishedAt = &value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(feedentry.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	return _node, _spec
+}
+
+// FeedEntryCreateBulk is the builder for creating many FeedEntry entities in bulk.
+type FeedEntryCreateBulk struct {
+	config
+	err      error
+	builders []*FeedEntryCreate
+}
+
+// Save creates the FeedEntry entities in the database.
+func (_c *FeedEntryCreateBulk) Save(ctx context.Context) ([]*FeedEntry, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*FeedEntry, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*FeedEntryMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *FeedEntryCreateBulk) SaveX(ctx context.Context) []*FeedEntry {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *FeedEntryCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *FeedEntryCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
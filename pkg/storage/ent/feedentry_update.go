@@ -0,0 +1,400 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nekobot/pkg/storage/ent/feedentry"
+	"nekobot/pkg/storage/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// FeedEntryUpdate is the builder for updating FeedEntry entities.
+type FeedEntryUpdate struct {
+	config
+	hooks    []Hook
+	mutation *FeedEntryMutation
+}
+
+// Where appends a list predicates to the FeedEntryUpdate builder.
+func (_u *FeedEntryUpdate) Where(ps ...predicate.FeedEntry) *FeedEntryUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetSubscriptionID sets the "subscription_id" field.
+func (_u *FeedEntryUpdate) SetSubscriptionID(v string) *FeedEntryUpdate {
+	_u.mutation.SetSubscriptionID(v)
+	return _u
+}
+
+// SetNillableSubscriptionID sets the "subscription_id" field if the given value is not nil.
+func (_u *FeedEntryUpdate) SetNillableSubscriptionID(v *string) *FeedEntryUpdate {
+	if v != nil {
+		_u.SetSubscriptionID(*v)
+	}
+	return _u
+}
+
+// SetEntryKey sets the "entry_key" field.
+func (_u *FeedEntryUpdate) SetEntryKey(v string) *FeedEntryUpdate {
+	_u.mutation.SetEntryKey(v)
+	return _u
+}
+
+// SetNillableEntryKey sets the "entry_key" field if the given value is not nil.
+func (_u *FeedEntryUpdate) SetNillableEntryKey(v *string) *FeedEntryUpdate {
+	if v != nil {
+		_u.SetEntryKey(*v)
+	}
+	return _u
+}
+
+// SetTitle sets the "title" field.
+func (_u *FeedEntryUpdate) SetTitle(v string) *FeedEntryUpdate {
+	_u.mutation.SetTitle(v)
+	return _u
+}
+
+// SetNillableTitle sets the "title" field if the given value is not nil.
+func (_u *FeedEntryUpdate) SetNillableTitle(v *string) *FeedEntryUpdate {
+	if v != nil {
+		_u.SetTitle(*v)
+	}
+	return _u
+}
+
+// SetLink sets the "link" field.
+func (_u *FeedEntryUpdate) SetLink(v string) *FeedEntryUpdate {
+	_u.mutation.SetLink(v)
+	return _u
+}
+
+// SetNillableLink sets the "link" field if the given value is not nil.
+func (_u *FeedEntryUpdate) SetNillableLink(v *string) *FeedEntryUpdate {
+	if v != nil {
+		_u.SetLink(*v)
+	}
+	return _u
+}
+
+// SetPublishedAt sets the "published_at" field.
+func (_u *FeedEntryUpdate) SetPublishedAt(v time.Time) *FeedEntryUpdate {
+	_u.mutation.SetPublishedAt(v)
+	return _u
+}
+
+// SetNillablePublishedAt sets the "published_at" field if the given value is not nil.
+func (_u *FeedEntryUpdate) SetNillablePublishedAt(v *time.Time) *FeedEntryUpdate {
+	if v != nil {
+		_u.SetPublishedAt(*v)
+	}
+	return _u
+}
+
+// ClearPublishedAt clears the value of the "published_at" field.
+func (_u *FeedEntryUpdate) ClearPublishedAt() *FeedEntryUpdate {
+	_u.mutation.ClearPublishedAt()
+	return _u
+}
+
+// Mutation returns the FeedEntryMutation object of the builder.
+func (_u *FeedEntryUpdate) Mutation() *FeedEntryMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *FeedEntryUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *FeedEntryUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *FeedEntryUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *FeedEntryUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *FeedEntryUpdate) check() error {
+	if v, ok := _u.mutation.SubscriptionID(); ok {
+		if err := feedentry.SubscriptionIDValidator(v); err != nil {
+			return &ValidationError{Name: "subscription_id", err: fmt.Errorf(`ent: validator failed for field "FeedEntry.subscription_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.EntryKey(); ok {
+		if err := feedentry.EntryKeyValidator(v); err != nil {
+			return &ValidationError{Name: "entry_key", err: fmt.Errorf(`ent: validator failed for field "FeedEntry.entry_key": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *FeedEntryUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(feedentry.Table, feedentry.Columns, sqlgraph.NewFieldSpec(feedentry.FieldID, field.TypeString))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.SubscriptionID(); ok {
+		_spec.SetField(feedentry.FieldSubscriptionID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.EntryKey(); ok {
+		_spec.SetField(feedentry.FieldEntryKey, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Title(); ok {
+		_spec.SetField(feedentry.FieldTitle, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Link(); ok {
+		_spec.SetField(feedentry.FieldLink, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.PublishedAt(); ok {
+		_spec.SetField(feedentry.FieldPublishedAt, field.TypeTime, value)
+	}
+	if _u.mutation.PublishedAtCleared() {
+		_spec.ClearField(feedentry.FieldPublishedAt, field.TypeTime)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{feedentry.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// FeedEntryUpdateOne is the builder for updating a single FeedEntry entity.
+type FeedEntryUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *FeedEntryMutation
+}
+
+// SetSubscriptionID sets the "subscription_id" field.
+func (_u *FeedEntryUpdateOne) SetSubscriptionID(v string) *FeedEntryUpdateOne {
+	_u.mutation.SetSubscriptionID(v)
+	return _u
+}
+
+// SetNillableSubscriptionID sets the "subscription_id" field if the given value is not nil.
+func (_u *FeedEntryUpdateOne) SetNillableSubscriptionID(v *string) *FeedEntryUpdateOne {
+	if v != nil {
+		_u.SetSubscriptionID(*v)
+	}
+	return _u
+}
+
+// SetEntryKey sets the "entry_key" field.
+func (_u *FeedEntryUpdateOne) SetEntryKey(v string) *FeedEntryUpdateOne {
+	_u.mutation.SetEntryKey(v)
+	return _u
+}
+
+// SetNillableEntryKey sets the "entry_key" field if the given value is not nil.
+func (_u *FeedEntryUpdateOne) SetNillableEntryKey(v *string) *FeedEntryUpdateOne {
+	if v != nil {
+		_u.SetEntryKey(*v)
+	}
+	return _u
+}
+
+// SetTitle sets the "title" field.
+func (_u *FeedEntryUpdateOne) SetTitle(v string) *FeedEntryUpdateOne {
+	_u.mutation.SetTitle(v)
+	return _u
+}
+
+// SetNillableTitle sets the "title" field if the given value is not nil.
+func (_u *FeedEntryUpdateOne) SetNillableTitle(v *string) *FeedEntryUpdateOne {
+	if v != nil {
+		_u.SetTitle(*v)
+	}
+	return _u
+}
+
+// SetLink sets the "link" field.
+func (_u *FeedEntryUpdateOne) SetLink(v string) *FeedEntryUpdateOne {
+	_u.mutation.SetLink(v)
+	return _u
+}
+
+// SetNillableLink sets the "link" field if the given value is not nil.
+func (_u *FeedEntryUpdateOne) SetNillableLink(v *string) *FeedEntryUpdateOne {
+	if v != nil {
+		_u.SetLink(*v)
+	}
+	return _u
+}
+
+// SetPublishedAt sets the "published_at" field.
+func (_u *FeedEntryUpdateOne) SetPublishedAt(v time.Time) *FeedEntryUpdateOne {
+	_u.mutation.SetPublishedAt(v)
+	return _u
+}
+
+// SetNillablePublishedAt sets the "published_at" field if the given value is not nil.
+func (_u *FeedEntryUpdateOne) SetNillablePublishedAt(v *time.Time) *FeedEntryUpdateOne {
+	if v != nil {
+		_u.SetPublishedAt(*v)
+	}
+	return _u
+}
+
+// ClearPublishedAt clears the value of the "published_at" field.
+func (_u *FeedEntryUpdateOne) ClearPublishedAt() *FeedEntryUpdateOne {
+	_u.mutation.ClearPublishedAt()
+	return _u
+}
+
+// Mutation returns the FeedEntryMutation object of the builder.
+func (_u *FeedEntryUpdateOne) Mutation() *FeedEntryMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the FeedEntryUpdate builder.
+func (_u *FeedEntryUpdateOne) Where(ps ...predicate.FeedEntry) *FeedEntryUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *FeedEntryUpdateOne) Select(field string, fields ...string) *FeedEntryUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated FeedEntry entity.
+func (_u *FeedEntryUpdateOne) Save(ctx context.Context) (*FeedEntry, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *FeedEntryUpdateOne) SaveX(ctx context.Context) *FeedEntry {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *FeedEntryUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *FeedEntryUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *FeedEntryUpdateOne) check() error {
+	if v, ok := _u.mutation.SubscriptionID(); ok {
+		if err := feedentry.SubscriptionIDValidator(v); err != nil {
+			return &ValidationError{Name: "subscription_id", err: fmt.Errorf(`ent: validator failed for field "FeedEntry.subscription_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.EntryKey(); ok {
+		if err := feedentry.EntryKeyValidator(v); err != nil {
+			return &ValidationError{Name: "entry_key", err: fmt.Errorf(`ent: validator failed for field "FeedEntry.entry_key": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *FeedEntryUpdateOne) sqlSave(ctx context.Context) (_node *FeedEntry, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(feedentry.Table, feedentry.Columns, sqlgraph.NewFieldSpec(feedentry.FieldID, field.TypeString))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "FeedEntry.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, feedentry.FieldID)
+		for _, f := range fields {
+			if !feedentry.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != feedentry.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.SubscriptionID(); ok {
+		_spec.SetField(feedentry.FieldSubscriptionID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.EntryKey(); ok {
+		_spec.SetField(feedentry.FieldEntryKey, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Title(); ok {
+		_spec.SetField(feedentry.FieldTitle, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Link(); ok {
+		_spec.SetField(feedentry.FieldLink, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.PublishedAt(); ok {
+		_spec.SetField(feedentry.FieldPublishedAt, field.TypeTime, value)
+	}
+	if _u.mutation.PublishedAtCleared() {
+		_spec.ClearField(feedentry.FieldPublishedAt, field.TypeTime)
+	}
+	_node = &FeedEntry{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{feedentry.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,440 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nekobot/pkg/storage/ent/feedsubscription"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// FeedSubscriptionCreate is the builder for creating a FeedSubscription entity.
+type FeedSubscriptionCreate struct {
+	config
+	mutation *FeedSubscriptionMutation
+	hooks    []Hook
+}
+
+// SetName sets the "name" field.
+func (_c *FeedSubscriptionCreate) SetName(v string) *FeedSubscriptionCreate {
+	_c.mutation.SetName(v)
+	return _c
+}
+
+// SetURL sets the "url" field.
+func (_c *FeedSubscriptionCreate) SetURL(v string) *FeedSubscriptionCreate {
+	_c.mutation.SetURL(v)
+	return _c
+}
+
+// SetIntervalMinutes sets the "interval_minutes" field.
+func (_c *FeedSubscriptionCreate) SetIntervalMinutes(v int) *FeedSubscriptionCreate {
+	_c.mutation.SetIntervalMinutes(v)
+	return _c
+}
+
+// SetNillableIntervalMinutes sets the "interval_minutes" field if the given value is not nil.
+func (_c *FeedSubscriptionCreate) SetNillableIntervalMinutes(v *int) *FeedSubscriptionCreate {
+	if v != nil {
+		_c.SetIntervalMinutes(*v)
+	}
+	return _c
+}
+
+// SetPrompt sets the "prompt" field.
+func (_c *FeedSubscriptionCreate) SetPrompt(v string) *FeedSubscriptionCreate {
+	_c.mutation.SetPrompt(v)
+	return _c
+}
+
+// SetNillablePrompt sets the "prompt" field if the given value is not nil.
+func (_c *FeedSubscriptionCreate) SetNillablePrompt(v *string) *FeedSubscriptionCreate {
+	if v != nil {
+		_c.SetPrompt(*v)
+	}
+	return _c
+}
+
+// SetChannelID sets the "channel_id" field.
+func (_c *FeedSubscriptionCreate) SetChannelID(v string) *FeedSubscriptionCreate {
+	_c.mutation.SetChannelID(v)
+	return _c
+}
+
+// SetSessionID sets the "session_id" field.
+func (_c *FeedSubscriptionCreate) SetSessionID(v string) *FeedSubscriptionCreate {
+	_c.mutation.SetSessionID(v)
+	return _c
+}
+
+// SetEnabled sets the "enabled" field.
+func (_c *FeedSubscriptionCreate) SetEnabled(v bool) *FeedSubscriptionCreate {
+	_c.mutation.SetEnabled(v)
+	return _c
+}
+
+// SetNillableEnabled sets the "enabled" field if the given value is not nil.
+func (_c *FeedSubscriptionCreate) SetNillableEnabled(v *bool) *FeedSubscriptionCreate {
+	if v != nil {
+		_c.SetEnabled(*v)
+	}
+	return _c
+}
+
+// SetLastCheckedAt sets the "last_checked_at" field.
+func (_c *FeedSubscriptionCreate) SetLastCheckedAt(v time.Time) *FeedSubscriptionCreate {
+	_c.mutation.SetLastCheckedAt(v)
+	return _c
+}
+
+// SetNillableLastCheckedAt sets the "last_checked_at" field if the given value is not nil.
+func (_c *FeedSubscriptionCreate) SetNillableLastCheckedAt(v *time.Time) *FeedSubscriptionCreate {
+	if v != nil {
+		_c.SetLastCheckedAt(*v)
+	}
+	return _c
+}
+
+// SetLastError sets the "last_error" field.
+func (_c *FeedSubscriptionCreate) SetLastError(v string) *FeedSubscriptionCreate {
+	_c.mutation.SetLastError(v)
+	return _c
+}
+
+// SetNillableLastError sets the "last_error" field if the given value is not nil.
+func (_c *FeedSubscriptionCreate) SetNillableLastError(v *string) *FeedSubscriptionCreate {
+	if v != nil {
+		_c.SetLastError(*v)
+	}
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *FeedSubscriptionCreate) SetCreatedAt(v time.Time) *FeedSubscriptionCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *FeedSubscriptionCreate) SetNillableCreatedAt(v *time.Time) *FeedSubscriptionCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_c *FeedSubscriptionCreate) SetUpdatedAt(v time.Time) *FeedSubscriptionCreate {
+	_c.mutation.SetUpdatedAt(v)
+	return _c
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (_c *FeedSubscriptionCreate) SetNillableUpdatedAt(v *time.Time) *FeedSubscriptionCreate {
+	if v != nil {
+		_c.SetUpdatedAt(*v)
+	}
+	return _c
+}
+
+// SetID sets the "id" field.
+func (_c *FeedSubscriptionCreate) SetID(v string) *FeedSubscriptionCreate {
+	_c.mutation.SetID(v)
+	return _c
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (_c *FeedSubscriptionCreate) SetNillableID(v *string) *FeedSubscriptionCreate {
+	if v != nil {
+		_c.SetID(*v)
+	}
+	return _c
+}
+
+// Mutation returns the FeedSubscriptionMutation object of the builder.
+func (_c *FeedSubscriptionCreate) Mutation() *FeedSubscriptionMutation {
+	return _c.mutation
+}
+
+// Save creates the FeedSubscription in the database.
+func (_c *FeedSubscriptionCreate) Save(ctx context.Context) (*FeedSubscription, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *FeedSubscriptionCreate) SaveX(ctx context.Context) *FeedSubscription {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *FeedSubscriptionCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *FeedSubscriptionCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *FeedSubscriptionCreate) defaults() {
+	if _, ok := _c.mutation.IntervalMinutes(); !ok {
+		v := feedsubscription.DefaultIntervalMinutes
+		_c.mutation.SetIntervalMinutes(v)
+	}
+	if _, ok := _c.mutation.Prompt(); !ok {
+		v := feedsubscription.DefaultPrompt
+		_c.mutation.SetPrompt(v)
+	}
+	if _, ok := _c.mutation.Enabled(); !ok {
+		v := feedsubscription.DefaultEnabled
+		_c.mutation.SetEnabled(v)
+	}
+	if _, ok := _c.mutation.LastError(); !ok {
+		v := feedsubscription.DefaultLastError
+		_c.mutation.SetLastError(v)
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := feedsubscription.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		v := feedsubscription.DefaultUpdatedAt()
+		_c.mutation.SetUpdatedAt(v)
+	}
+	if _, ok := _c.mutation.ID(); !ok {
+		v := feedsubscription.DefaultID()
+		_c.mutation.SetID(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *FeedSubscriptionCreate) check() error {
+	if _, ok := _c.mutation.Name(); !ok {
+		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "FeedSubscription.name"`)}
+	}
+	if v, ok := _c.mutation.Name(); ok {
+		if err := feedsubscription.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "FeedSubscription.name": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.URL(); !ok {
+		return &ValidationError{Name: "url", err: errors.New(`ent: missing required field "FeedSubscription.url"`)}
+	}
+	if v, ok := _c.mutation.URL(); ok {
+		if err := feedsubscription.URLValidator(v); err != nil {
+			return &ValidationError{Name: "url", err: fmt.Errorf(`ent: validator failed for field "FeedSubscription.url": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.IntervalMinutes(); !ok {
+		return &ValidationError{Name: "interval_minutes", err: errors.New(`ent: missing required field "FeedSubscription.interval_minutes"`)}
+	}
+	if _, ok := _c.mutation.Prompt(); !ok {
+		return &ValidationError{Name: "prompt", err: errors.New(`ent: missing required field "FeedSubscription.prompt"`)}
+	}
+	if _, ok := _c.mutation.ChannelID(); !ok {
+		return &ValidationError{Name: "channel_id", err: errors.New(`ent: missing required field "FeedSubscription.channel_id"`)}
+	}
+	if v, ok := _c.mutation.ChannelID(); ok {
+		if err := feedsubscription.ChannelIDValidator(v); err != nil {
+			return &ValidationError{Name: "channel_id", err: fmt.Errorf(`ent: validator failed for field "FeedSubscription.channel_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.SessionID(); !ok {
+		return &ValidationError{Name: "session_id", err: errors.New(`ent: missing required field "FeedSubscription.session_id"`)}
+	}
+	if v, ok := _c.mutation.SessionID(); ok {
+		if err := feedsubscription.SessionIDValidator(v); err != nil {
+			return &ValidationError{Name: "session_id", err: fmt.Errorf(`ent: validator failed for field "FeedSubscription.session_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Enabled(); !ok {
+		return &ValidationError{Name: "enabled", err: errors.New(`ent: missing required field "FeedSubscription.enabled"`)}
+	}
+	if _, ok := _c.mutation.LastError(); !ok {
+		return &ValidationError{Name: "last_error", err: errors.New(`ent: missing required field "FeedSubscription.last_error"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "FeedSubscription.created_at"`)}
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "FeedSubscription.updated_at"`)}
+	}
+	return nil
+}
+
+func (_c *FeedSubscriptionCreate) sqlSave(ctx context.Context) (*FeedSubscription, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(string); ok {
+			_node.ID = id
+		} else {
+			return nil, fmt.Errorf("unexpected FeedSubscription.ID type: %T", _spec.ID.Value)
+		}
+	}
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *FeedSubscriptionCreate) createSpec() (*FeedSubscription, *sqlgraph.CreateSpec) {
+	var (
+		_node = &FeedSubscription{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(feedsubscription.Table, sqlgraph.NewFieldSpec(feedsubscription.FieldID, field.TypeString))
+	)
+	if id, ok := _c.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := _c.mutation.Name(); ok {
+		_spec.SetField(feedsubscription.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := _c.mutation.URL(); ok {
+		_spec.SetField(feedsubscription.FieldURL, field.TypeString, value)
+		_node.URL = value
+	}
+	if value, ok := _c.mutation.IntervalMinutes(); ok {
+		_spec.SetField(feedsubscription.FieldIntervalMinutes, field.TypeInt, value)
+		_node.IntervalMinutes = value
+	}
+	if value, ok := _c.mutation.Prompt(); ok {
+		_spec.SetField(feedsubscription.FieldPrompt, field.TypeString, value)
+		_node.Prompt = value
+	}
+	if value, ok := _c.mutation.ChannelID(); ok {
+		_spec.SetField(feedsubscription.FieldChannelID, field.TypeString, value)
+		_node.ChannelID = value
+	}
+	if value, ok := _c.mutation.SessionID(); ok {
+		_spec.SetField(feedsubscription.FieldSessionID, field.TypeString, value)
+		_node.SessionID = value
+	}
+	if value, ok := _c.mutation.Enabled(); ok {
+		_spec.SetField(feedsubscription.FieldEnabled, field.TypeBool, value)
+		_node.Enabled = value
+	}
+	if value, ok := _c.mutation.LastCheckedAt(); ok {
+		_spec.SetField(feedsubscription.FieldLastCheckedAt, field.TypeTime, value)
+		_node.LastCheckedAt = &value
+	}
+	if value, ok := _c.mutation.LastError(); ok {
+		_spec.SetField(feedsubscription.FieldLastError, field.TypeString, value)
+		_node.LastError = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(feedsubscription.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.UpdatedAt(); ok {
+		_spec.SetField(feedsubscription.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	return _node, _spec
+}
+
+// FeedSubscriptionCreateBulk is the builder for creating many FeedSubscription entities in bulk.
+type FeedSubscriptionCreateBulk struct {
+	config
+	err      error
+	builders []*FeedSubscriptionCreate
+}
+
+// Save creates the FeedSubscription entities in the database.
+func (_c *FeedSubscriptionCreateBulk) Save(ctx context.Context) ([]*FeedSubscription, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*FeedSubscription, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*FeedSubscriptionMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *FeedSubscriptionCreateBulk) SaveX(ctx context.Context) []*FeedSubscription {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *FeedSubscriptionCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *FeedSubscriptionCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,612 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nekobot/pkg/storage/ent/feedsubscription"
+	"nekobot/pkg/storage/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// FeedSubscriptionUpdate is the builder for updating FeedSubscription entities.
+type FeedSubscriptionUpdate struct {
+	config
+	hooks    []Hook
+	mutation *FeedSubscriptionMutation
+}
+
+// Where appends a list predicates to the FeedSubscriptionUpdate builder.
+func (_u *FeedSubscriptionUpdate) Where(ps ...predicate.FeedSubscription) *FeedSubscriptionUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetName sets the "name" field.
+func (_u *FeedSubscriptionUpdate) SetName(v string) *FeedSubscriptionUpdate {
+	_u.mutation.SetName(v)
+	return _u
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdate) SetNillableName(v *string) *FeedSubscriptionUpdate {
+	if v != nil {
+		_u.SetName(*v)
+	}
+	return _u
+}
+
+// SetURL sets the "url" field.
+func (_u *FeedSubscriptionUpdate) SetURL(v string) *FeedSubscriptionUpdate {
+	_u.mutation.SetURL(v)
+	return _u
+}
+
+// SetNillableURL sets the "url" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdate) SetNillableURL(v *string) *FeedSubscriptionUpdate {
+	if v != nil {
+		_u.SetURL(*v)
+	}
+	return _u
+}
+
+// SetIntervalMinutes sets the "interval_minutes" field.
+func (_u *FeedSubscriptionUpdate) SetIntervalMinutes(v int) *FeedSubscriptionUpdate {
+	_u.mutation.ResetIntervalMinutes()
+	_u.mutation.SetIntervalMinutes(v)
+	return _u
+}
+
+// SetNillableIntervalMinutes sets the "interval_minutes" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdate) SetNillableIntervalMinutes(v *int) *FeedSubscriptionUpdate {
+	if v != nil {
+		_u.SetIntervalMinutes(*v)
+	}
+	return _u
+}
+
+// AddIntervalMinutes adds value to the "interval_minutes" field.
+func (_u *FeedSubscriptionUpdate) AddIntervalMinutes(v int) *FeedSubscriptionUpdate {
+	_u.mutation.AddIntervalMinutes(v)
+	return _u
+}
+
+// SetPrompt sets the "prompt" field.
+func (_u *FeedSubscriptionUpdate) SetPrompt(v string) *FeedSubscriptionUpdate {
+	_u.mutation.SetPrompt(v)
+	return _u
+}
+
+// SetNillablePrompt sets the "prompt" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdate) SetNillablePrompt(v *string) *FeedSubscriptionUpdate {
+	if v != nil {
+		_u.SetPrompt(*v)
+	}
+	return _u
+}
+
+// SetChannelID sets the "channel_id" field.
+func (_u *FeedSubscriptionUpdate) SetChannelID(v string) *FeedSubscriptionUpdate {
+	_u.mutation.SetChannelID(v)
+	return _u
+}
+
+// SetNillableChannelID sets the "channel_id" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdate) SetNillableChannelID(v *string) *FeedSubscriptionUpdate {
+	if v != nil {
+		_u.SetChannelID(*v)
+	}
+	return _u
+}
+
+// SetSessionID sets the "session_id" field.
+func (_u *FeedSubscriptionUpdate) SetSessionID(v string) *FeedSubscriptionUpdate {
+	_u.mutation.SetSessionID(v)
+	return _u
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdate) SetNillableSessionID(v *string) *FeedSubscriptionUpdate {
+	if v != nil {
+		_u.SetSessionID(*v)
+	}
+	return _u
+}
+
+// SetEnabled sets the "enabled" field.
+func (_u *FeedSubscriptionUpdate) SetEnabled(v bool) *FeedSubscriptionUpdate {
+	_u.mutation.SetEnabled(v)
+	return _u
+}
+
+// SetNillableEnabled sets the "enabled" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdate) SetNillableEnabled(v *bool) *FeedSubscriptionUpdate {
+	if v != nil {
+		_u.SetEnabled(*v)
+	}
+	return _u
+}
+
+// SetLastCheckedAt sets the "last_checked_at" field.
+func (_u *FeedSubscriptionUpdate) SetLastCheckedAt(v time.Time) *FeedSubscriptionUpdate {
+	_u.mutation.SetLastCheckedAt(v)
+	return _u
+}
+
+// SetNillableLastCheckedAt sets the "last_checked_at" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdate) SetNillableLastCheckedAt(v *time.Time) *FeedSubscriptionUpdate {
+	if v != nil {
+		_u.SetLastCheckedAt(*v)
+	}
+	return _u
+}
+
+// ClearLastCheckedAt clears the value of the "last_checked_at" field.
+func (_u *FeedSubscriptionUpdate) ClearLastCheckedAt() *FeedSubscriptionUpdate {
+	_u.mutation.ClearLastCheckedAt()
+	return _u
+}
+
+// SetLastError sets the "last_error" field.
+func (_u *FeedSubscriptionUpdate) SetLastError(v string) *FeedSubscriptionUpdate {
+	_u.mutation.SetLastError(v)
+	return _u
+}
+
+// SetNillableLastError sets the "last_error" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdate) SetNillableLastError(v *string) *FeedSubscriptionUpdate {
+	if v != nil {
+		_u.SetLastError(*v)
+	}
+	return _u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *FeedSubscriptionUpdate) SetUpdatedAt(v time.Time) *FeedSubscriptionUpdate {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// Mutation returns the FeedSubscriptionMutation object of the builder.
+func (_u *FeedSubscriptionUpdate) Mutation() *FeedSubscriptionMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *FeedSubscriptionUpdate) Save(ctx context.Context) (int, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *FeedSubscriptionUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *FeedSubscriptionUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *FeedSubscriptionUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_u *FeedSubscriptionUpdate) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
+		v := feedsubscription.UpdateDefaultUpdatedAt()
+		_u.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *FeedSubscriptionUpdate) check() error {
+	if v, ok := _u.mutation.Name(); ok {
+		if err := feedsubscription.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "FeedSubscription.name": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.URL(); ok {
+		if err := feedsubscription.URLValidator(v); err != nil {
+			return &ValidationError{Name: "url", err: fmt.Errorf(`ent: validator failed for field "FeedSubscription.url": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.ChannelID(); ok {
+		if err := feedsubscription.ChannelIDValidator(v); err != nil {
+			return &ValidationError{Name: "channel_id", err: fmt.Errorf(`ent: validator failed for field "FeedSubscription.channel_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.SessionID(); ok {
+		if err := feedsubscription.SessionIDValidator(v); err != nil {
+			return &ValidationError{Name: "session_id", err: fmt.Errorf(`ent: validator failed for field "FeedSubscription.session_id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *FeedSubscriptionUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(feedsubscription.Table, feedsubscription.Columns, sqlgraph.NewFieldSpec(feedsubscription.FieldID, field.TypeString))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Name(); ok {
+		_spec.SetField(feedsubscription.FieldName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.URL(); ok {
+		_spec.SetField(feedsubscription.FieldURL, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.IntervalMinutes(); ok {
+		_spec.SetField(feedsubscription.FieldIntervalMinutes, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedIntervalMinutes(); ok {
+		_spec.AddField(feedsubscription.FieldIntervalMinutes, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Prompt(); ok {
+		_spec.SetField(feedsubscription.FieldPrompt, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.ChannelID(); ok {
+		_spec.SetField(feedsubscription.FieldChannelID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.SessionID(); ok {
+		_spec.SetField(feedsubscription.FieldSessionID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Enabled(); ok {
+		_spec.SetField(feedsubscription.FieldEnabled, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.LastCheckedAt(); ok {
+		_spec.SetField(feedsubscription.FieldLastCheckedAt, field.TypeTime, value)
+	}
+	if _u.mutation.LastCheckedAtCleared() {
+		_spec.ClearField(feedsubscription.FieldLastCheckedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.LastError(); ok {
+		_spec.SetField(feedsubscription.FieldLastError, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(feedsubscription.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{feedsubscription.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// FeedSubscriptionUpdateOne is the builder for updating a single FeedSubscription entity.
+type FeedSubscriptionUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *FeedSubscriptionMutation
+}
+
+// SetName sets the "name" field.
+func (_u *FeedSubscriptionUpdateOne) SetName(v string) *FeedSubscriptionUpdateOne {
+	_u.mutation.SetName(v)
+	return _u
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdateOne) SetNillableName(v *string) *FeedSubscriptionUpdateOne {
+	if v != nil {
+		_u.SetName(*v)
+	}
+	return _u
+}
+
+// SetURL sets the "url" field.
+func (_u *FeedSubscriptionUpdateOne) SetURL(v string) *FeedSubscriptionUpdateOne {
+	_u.mutation.SetURL(v)
+	return _u
+}
+
+// SetNillableURL sets the "url" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdateOne) SetNillableURL(v *string) *FeedSubscriptionUpdateOne {
+	if v != nil {
+		_u.SetURL(*v)
+	}
+	return _u
+}
+
+// SetIntervalMinutes sets the "interval_minutes" field.
+func (_u *FeedSubscriptionUpdateOne) SetIntervalMinutes(v int) *FeedSubscriptionUpdateOne {
+	_u.mutation.ResetIntervalMinutes()
+	_u.mutation.SetIntervalMinutes(v)
+	return _u
+}
+
+// SetNillableIntervalMinutes sets the "interval_minutes" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdateOne) SetNillableIntervalMinutes(v *int) *FeedSubscriptionUpdateOne {
+	if v != nil {
+		_u.SetIntervalMinutes(*v)
+	}
+	return _u
+}
+
+// AddIntervalMinutes adds value to the "interval_minutes" field.
+func (_u *FeedSubscriptionUpdateOne) AddIntervalMinutes(v int) *FeedSubscriptionUpdateOne {
+	_u.mutation.AddIntervalMinutes(v)
+	return _u
+}
+
+// SetPrompt sets the "prompt" field.
+func (_u *FeedSubscriptionUpdateOne) SetPrompt(v string) *FeedSubscriptionUpdateOne {
+	_u.mutation.SetPrompt(v)
+	return _u
+}
+
+// SetNillablePrompt sets the "prompt" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdateOne) SetNillablePrompt(v *string) *FeedSubscriptionUpdateOne {
+	if v != nil {
+		_u.SetPrompt(*v)
+	}
+	return _u
+}
+
+// SetChannelID sets the "channel_id" field.
+func (_u *FeedSubscriptionUpdateOne) SetChannelID(v string) *FeedSubscriptionUpdateOne {
+	_u.mutation.SetChannelID(v)
+	return _u
+}
+
+// SetNillableChannelID sets the "channel_id" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdateOne) SetNillableChannelID(v *string) *FeedSubscriptionUpdateOne {
+	if v != nil {
+		_u.SetChannelID(*v)
+	}
+	return _u
+}
+
+// SetSessionID sets the "session_id" field.
+func (_u *FeedSubscriptionUpdateOne) SetSessionID(v string) *FeedSubscriptionUpdateOne {
+	_u.mutation.SetSessionID(v)
+	return _u
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdateOne) SetNillableSessionID(v *string) *FeedSubscriptionUpdateOne {
+	if v != nil {
+		_u.SetSessionID(*v)
+	}
+	return _u
+}
+
+// SetEnabled sets the "enabled" field.
+func (_u *FeedSubscriptionUpdateOne) SetEnabled(v bool) *FeedSubscriptionUpdateOne {
+	_u.mutation.SetEnabled(v)
+	return _u
+}
+
+// SetNillableEnabled sets the "enabled" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdateOne) SetNillableEnabled(v *bool) *FeedSubscriptionUpdateOne {
+	if v != nil {
+		_u.SetEnabled(*v)
+	}
+	return _u
+}
+
+// SetLastCheckedAt sets the "last_checked_at" field.
+func (_u *FeedSubscriptionUpdateOne) SetLastCheckedAt(v time.Time) *FeedSubscriptionUpdateOne {
+	_u.mutation.SetLastCheckedAt(v)
+	return _u
+}
+
+// SetNillableLastCheckedAt sets the "last_checked_at" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdateOne) SetNillableLastCheckedAt(v *time.Time) *FeedSubscriptionUpdateOne {
+	if v != nil {
+		_u.SetLastCheckedAt(*v)
+	}
+	return _u
+}
+
+// ClearLastCheckedAt clears the value of the "last_checked_at" field.
+func (_u *FeedSubscriptionUpdateOne) ClearLastCheckedAt() *FeedSubscriptionUpdateOne {
+	_u.mutation.ClearLastCheckedAt()
+	return _u
+}
+
+// SetLastError sets the "last_error" field.
+func (_u *FeedSubscriptionUpdateOne) SetLastError(v string) *FeedSubscriptionUpdateOne {
+	_u.mutation.SetLastError(v)
+	return _u
+}
+
+// SetNillableLastError sets the "last_error" field if the given value is not nil.
+func (_u *FeedSubscriptionUpdateOne) SetNillableLastError(v *string) *FeedSubscriptionUpdateOne {
+	if v != nil {
+		_u.SetLastError(*v)
+	}
+	return _u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *FeedSubscriptionUpdateOne) SetUpdatedAt(v time.Time) *FeedSubscriptionUpdateOne {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// Mutation returns the FeedSubscriptionMutation object of the builder.
+func (_u *FeedSubscriptionUpdateOne) Mutation() *FeedSubscriptionMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the FeedSubscriptionUpdate builder.
+func (_u *FeedSubscriptionUpdateOne) Where(ps ...predicate.FeedSubscription) *FeedSubscriptionUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *FeedSubscriptionUpdateOne) Select(field string, fields ...string) *FeedSubscriptionUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated FeedSubscription entity.
+func (_u *FeedSubscriptionUpdateOne) Save(ctx context.Context) (*FeedSubscription, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *FeedSubscriptionUpdateOne) SaveX(ctx context.Context) *FeedSubscription {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *FeedSubscriptionUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *FeedSubscriptionUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_u *FeedSubscriptionUpdateOne) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
+		v := feedsubscription.UpdateDefaultUpdatedAt()
+		_u.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *FeedSubscriptionUpdateOne) check() error {
+	if v, ok := _u.mutation.Name(); ok {
+		if err := feedsubscription.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "FeedSubscription.name": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.URL(); ok {
+		if err := feedsubscription.URLValidator(v); err != nil {
+			return &ValidationError{Name: "url", err: fmt.Errorf(`ent: validator failed for field "FeedSubscription.url": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.ChannelID(); ok {
+		if err := feedsubscription.ChannelIDValidator(v); err != nil {
+			return &ValidationError{Name: "channel_id", err: fmt.Errorf(`ent: validator failed for field "FeedSubscription.channel_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.SessionID(); ok {
+		if err := feedsubscription.SessionIDValidator(v); err != nil {
+			return &ValidationError{Name: "session_id", err: fmt.Errorf(`ent: validator failed for field "FeedSubscription.session_id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *FeedSubscriptionUpdateOne) sqlSave(ctx context.Context) (_node *FeedSubscription, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(feedsubscription.Table, feedsubscription.Columns, sqlgraph.NewFieldSpec(feedsubscription.FieldID, field.TypeString))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "FeedSubscription.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, feedsubscription.FieldID)
+		for _, f := range fields {
+			if !feedsubscription.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != feedsubscription.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Name(); ok {
+		_spec.SetField(feedsubscription.FieldName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.URL(); ok {
+		_spec.SetField(feedsubscription.FieldURL, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.IntervalMinutes(); ok {
+		_spec.SetField(feedsubscription.FieldIntervalMinutes, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedIntervalMinutes(); ok {
+		_spec.AddField(feedsubscription.FieldIntervalMinutes, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Prompt(); ok {
+		_spec.SetField(feedsubscription.FieldPrompt, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.ChannelID(); ok {
+		_spec.SetField(feedsubscription.FieldChannelID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.SessionID(); ok {
+		_spec.SetField(feedsubscription.FieldSessionID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Enabled(); ok {
+		_spec.SetField(feedsubscription.FieldEnabled, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.LastCheckedAt(); ok {
+		_spec.SetField(feedsubscription.FieldLastCheckedAt, field.TypeTime, value)
+	}
+	if _u.mutation.LastCheckedAtCleared() {
+		_spec.ClearField(feedsubscription.FieldLastCheckedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.LastError(); ok {
+		_spec.SetField(feedsubscription.FieldLastError, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(feedsubscription.FieldUpdatedAt, field.TypeTime, value)
+	}
+	_node = &FeedSubscription{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{feedsubscription.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
@@ -8,6 +8,43 @@ import (
 )
 
 var (
+	// APIKeysColumns holds the columns for the "api_keys" table.
+	APIKeysColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeString},
+		{Name: "name", Type: field.TypeString},
+		{Name: "prefix", Type: field.TypeString, Default: ""},
+		{Name: "key_hash", Type: field.TypeString, Unique: true},
+		{Name: "user_id", Type: field.TypeString},
+		{Name: "tenant_id", Type: field.TypeString, Default: ""},
+		{Name: "scopes_json", Type: field.TypeString, Default: "[]"},
+		{Name: "expires_at", Type: field.TypeTime, Nullable: true},
+		{Name: "last_used_at", Type: field.TypeTime, Nullable: true},
+		{Name: "revoked_at", Type: field.TypeTime, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime},
+	}
+	// APIKeysTable holds the schema information for the "api_keys" table.
+	APIKeysTable = &schema.Table{
+		Name:       "api_keys",
+		Columns:    APIKeysColumns,
+		PrimaryKey: []*schema.Column{APIKeysColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "apikey_key_hash",
+				Unique:  true,
+				Columns: []*schema.Column{APIKeysColumns[3]},
+			},
+			{
+				Name:    "apikey_user_id",
+				Unique:  false,
+				Columns: []*schema.Column{APIKeysColumns[4]},
+			},
+			{
+				Name:    "apikey_tenant_id",
+				Unique:  false,
+				Columns: []*schema.Column{APIKeysColumns[5]},
+			},
+		},
+	}
 	// AccountBindingsColumns holds the columns for the "account_bindings" table.
 	AccountBindingsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeString},
@@ -107,6 +144,42 @@ var (
 			},
 		},
 	}
+	// ApprovalRequestsColumns holds the columns for the "approval_requests" table.
+	ApprovalRequestsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeString},
+		{Name: "tool_name", Type: field.TypeString},
+		{Name: "arguments", Type: field.TypeJSON, Nullable: true},
+		{Name: "session_id", Type: field.TypeString, Default: ""},
+		{Name: "state", Type: field.TypeEnum, Enums: []string{"pending", "approved", "denied", "expired"}, Default: "pending"},
+		{Name: "reason", Type: field.TypeString, Size: 2147483647, Default: ""},
+		{Name: "decided_by", Type: field.TypeString, Default: ""},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "expires_at", Type: field.TypeTime, Nullable: true},
+		{Name: "decided_at", Type: field.TypeTime, Nullable: true},
+	}
+	// ApprovalRequestsTable holds the schema information for the "approval_requests" table.
+	ApprovalRequestsTable = &schema.Table{
+		Name:       "approval_requests",
+		Columns:    ApprovalRequestsColumns,
+		PrimaryKey: []*schema.Column{ApprovalRequestsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "approvalrequest_state",
+				Unique:  false,
+				Columns: []*schema.Column{ApprovalRequestsColumns[4]},
+			},
+			{
+				Name:    "approvalrequest_session_id",
+				Unique:  false,
+				Columns: []*schema.Column{ApprovalRequestsColumns[3]},
+			},
+			{
+				Name:    "approvalrequest_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{ApprovalRequestsColumns[7]},
+			},
+		},
+	}
 	// AttachTokensColumns holds the columns for the "attach_tokens" table.
 	AttachTokensColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeString},
@@ -145,6 +218,47 @@ var (
 			},
 		},
 	}
+	// AuditLogsColumns holds the columns for the "audit_logs" table.
+	AuditLogsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeString},
+		{Name: "action", Type: field.TypeString},
+		{Name: "actor", Type: field.TypeString, Default: ""},
+		{Name: "actor_id", Type: field.TypeString, Default: ""},
+		{Name: "tenant_id", Type: field.TypeString, Default: ""},
+		{Name: "ip", Type: field.TypeString, Default: ""},
+		{Name: "target", Type: field.TypeString, Default: ""},
+		{Name: "success", Type: field.TypeBool, Default: true},
+		{Name: "summary", Type: field.TypeString, Size: 2147483647, Default: ""},
+		{Name: "created_at", Type: field.TypeTime},
+	}
+	// AuditLogsTable holds the schema information for the "audit_logs" table.
+	AuditLogsTable = &schema.Table{
+		Name:       "audit_logs",
+		Columns:    AuditLogsColumns,
+		PrimaryKey: []*schema.Column{AuditLogsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "auditlog_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{AuditLogsColumns[9]},
+			},
+			{
+				Name:    "auditlog_action_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{AuditLogsColumns[1], AuditLogsColumns[9]},
+			},
+			{
+				Name:    "auditlog_actor_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{AuditLogsColumns[2], AuditLogsColumns[9]},
+			},
+			{
+				Name:    "auditlog_tenant_id_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{AuditLogsColumns[4], AuditLogsColumns[9]},
+			},
+		},
+	}
 	// ChannelAccountsColumns holds the columns for the "channel_accounts" table.
 	ChannelAccountsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeString},
@@ -356,6 +470,98 @@ var (
 			},
 		},
 	}
+	// FeedEntriesColumns holds the columns for the "feed_entries" table.
+	FeedEntriesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeString},
+		{Name: "subscription_id", Type: field.TypeString},
+		{Name: "entry_key", Type: field.TypeString},
+		{Name: "title", Type: field.TypeString, Default: ""},
+		{Name: "link", Type: field.TypeString, Default: ""},
+		{Name: "published_at", Type: field.TypeTime, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime},
+	}
+	// FeedEntriesTable holds the schema information for the "feed_entries" table.
+	FeedEntriesTable = &schema.Table{
+		Name:       "feed_entries",
+		Columns:    FeedEntriesColumns,
+		PrimaryKey: []*schema.Column{FeedEntriesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "feedentry_subscription_id_entry_key",
+				Unique:  true,
+				Columns: []*schema.Column{FeedEntriesColumns[1], FeedEntriesColumns[2]},
+			},
+			{
+				Name:    "feedentry_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{FeedEntriesColumns[6]},
+			},
+		},
+	}
+	// FeedSubscriptionsColumns holds the columns for the "feed_subscriptions" table.
+	FeedSubscriptionsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeString},
+		{Name: "name", Type: field.TypeString},
+		{Name: "url", Type: field.TypeString},
+		{Name: "interval_minutes", Type: field.TypeInt, Default: 60},
+		{Name: "prompt", Type: field.TypeString, Size: 2147483647, Default: ""},
+		{Name: "channel_id", Type: field.TypeString},
+		{Name: "session_id", Type: field.TypeString},
+		{Name: "enabled", Type: field.TypeBool, Default: true},
+		{Name: "last_checked_at", Type: field.TypeTime, Nullable: true},
+		{Name: "last_error", Type: field.TypeString, Default: ""},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "updated_at", Type: field.TypeTime},
+	}
+	// FeedSubscriptionsTable holds the schema information for the "feed_subscriptions" table.
+	FeedSubscriptionsTable = &schema.Table{
+		Name:       "feed_subscriptions",
+		Columns:    FeedSubscriptionsColumns,
+		PrimaryKey: []*schema.Column{FeedSubscriptionsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "feedsubscription_enabled",
+				Unique:  false,
+				Columns: []*schema.Column{FeedSubscriptionsColumns[7]},
+			},
+			{
+				Name:    "feedsubscription_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{FeedSubscriptionsColumns[10]},
+			},
+		},
+	}
+	// FeedbacksColumns holds the columns for the "feedbacks" table.
+	FeedbacksColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeString},
+		{Name: "channel", Type: field.TypeString},
+		{Name: "session_id", Type: field.TypeString},
+		{Name: "message_id", Type: field.TypeString},
+		{Name: "user_id", Type: field.TypeString, Default: ""},
+		{Name: "rating", Type: field.TypeInt},
+		{Name: "comment", Type: field.TypeString, Size: 2147483647, Default: ""},
+		{Name: "response", Type: field.TypeString, Size: 2147483647, Default: ""},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "updated_at", Type: field.TypeTime},
+	}
+	// FeedbacksTable holds the schema information for the "feedbacks" table.
+	FeedbacksTable = &schema.Table{
+		Name:       "feedbacks",
+		Columns:    FeedbacksColumns,
+		PrimaryKey: []*schema.Column{FeedbacksColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "feedback_channel_session_id_message_id_user_id",
+				Unique:  true,
+				Columns: []*schema.Column{FeedbacksColumns[1], FeedbacksColumns[2], FeedbacksColumns[3], FeedbacksColumns[4]},
+			},
+			{
+				Name:    "feedback_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{FeedbacksColumns[8]},
+			},
+		},
+	}
 	// IdempotencyRecordsColumns holds the columns for the "idempotency_records" table.
 	IdempotencyRecordsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeString},
@@ -635,6 +841,36 @@ var (
 			},
 		},
 	}
+	// NotifyRulesColumns holds the columns for the "notify_rules" table.
+	NotifyRulesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeString},
+		{Name: "name", Type: field.TypeString},
+		{Name: "event_types_json", Type: field.TypeString, Size: 2147483647, Default: "[]"},
+		{Name: "match", Type: field.TypeString, Default: ""},
+		{Name: "channel_id", Type: field.TypeString},
+		{Name: "session_id", Type: field.TypeString},
+		{Name: "enabled", Type: field.TypeBool, Default: true},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "updated_at", Type: field.TypeTime},
+	}
+	// NotifyRulesTable holds the schema information for the "notify_rules" table.
+	NotifyRulesTable = &schema.Table{
+		Name:       "notify_rules",
+		Columns:    NotifyRulesColumns,
+		PrimaryKey: []*schema.Column{NotifyRulesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "notifyrule_enabled",
+				Unique:  false,
+				Columns: []*schema.Column{NotifyRulesColumns[6]},
+			},
+			{
+				Name:    "notifyrule_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{NotifyRulesColumns[7]},
+			},
+		},
+	}
 	// PermissionRulesColumns holds the columns for the "permission_rules" table.
 	PermissionRulesColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeString},
@@ -782,6 +1018,13 @@ var (
 		{Name: "default_test_model", Type: field.TypeString, Default: ""},
 		{Name: "api_format", Type: field.TypeString, Default: "openai/chat_completions"},
 		{Name: "timeout", Type: field.TypeInt, Default: 60},
+		{Name: "stream", Type: field.TypeBool, Default: true},
+		{Name: "system_prefix", Type: field.TypeString, Size: 2147483647, Default: ""},
+		{Name: "api_version", Type: field.TypeString, Default: ""},
+		{Name: "deployments_json", Type: field.TypeString, Size: 2147483647, Default: ""},
+		{Name: "middleware_json", Type: field.TypeString, Size: 2147483647, Default: ""},
+		{Name: "capabilities_json", Type: field.TypeString, Size: 2147483647, Default: ""},
+		{Name: "tenant_id", Type: field.TypeString, Default: ""},
 		{Name: "created_at", Type: field.TypeTime},
 		{Name: "updated_at", Type: field.TypeTime},
 	}
@@ -801,6 +1044,11 @@ var (
 				Unique:  false,
 				Columns: []*schema.Column{ProvidersColumns[2]},
 			},
+			{
+				Name:    "provider_tenant_id",
+				Unique:  false,
+				Columns: []*schema.Column{ProvidersColumns[17]},
+			},
 		},
 	}
 	// RunsColumns holds the columns for the "runs" table.
@@ -989,6 +1237,7 @@ var (
 		{Name: "access_mode", Type: field.TypeString, Default: "none"},
 		{Name: "access_secret_hash", Type: field.TypeString, Nullable: true, Default: ""},
 		{Name: "access_once_used_at", Type: field.TypeTime, Nullable: true},
+		{Name: "spectator_secret_hash", Type: field.TypeString, Nullable: true, Default: ""},
 		{Name: "pinned", Type: field.TypeBool, Default: false},
 		{Name: "last_active_at", Type: field.TypeTime},
 		{Name: "detached_at", Type: field.TypeTime, Nullable: true},
@@ -1022,17 +1271,56 @@ var (
 			{
 				Name:    "toolsession_last_active_at",
 				Unique:  false,
-				Columns: []*schema.Column{ToolSessionsColumns[14]},
+				Columns: []*schema.Column{ToolSessionsColumns[15]},
 			},
 			{
 				Name:    "toolsession_created_at",
 				Unique:  false,
-				Columns: []*schema.Column{ToolSessionsColumns[19]},
+				Columns: []*schema.Column{ToolSessionsColumns[20]},
 			},
 			{
 				Name:    "toolsession_updated_at",
 				Unique:  false,
-				Columns: []*schema.Column{ToolSessionsColumns[20]},
+				Columns: []*schema.Column{ToolSessionsColumns[21]},
+			},
+		},
+	}
+	// UsageRecordsColumns holds the columns for the "usage_records" table.
+	UsageRecordsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeString},
+		{Name: "provider", Type: field.TypeString, Default: ""},
+		{Name: "model", Type: field.TypeString, Default: ""},
+		{Name: "user_id", Type: field.TypeString, Default: ""},
+		{Name: "session_id", Type: field.TypeString, Default: ""},
+		{Name: "channel", Type: field.TypeString, Default: ""},
+		{Name: "prompt_tokens", Type: field.TypeInt, Default: 0},
+		{Name: "completion_tokens", Type: field.TypeInt, Default: 0},
+		{Name: "total_tokens", Type: field.TypeInt, Default: 0},
+		{Name: "cache_read_tokens", Type: field.TypeInt, Default: 0},
+		{Name: "cache_write_tokens", Type: field.TypeInt, Default: 0},
+		{Name: "cost", Type: field.TypeFloat64, Default: 0},
+		{Name: "created_at", Type: field.TypeTime},
+	}
+	// UsageRecordsTable holds the schema information for the "usage_records" table.
+	UsageRecordsTable = &schema.Table{
+		Name:       "usage_records",
+		Columns:    UsageRecordsColumns,
+		PrimaryKey: []*schema.Column{UsageRecordsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "usagerecord_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{UsageRecordsColumns[12]},
+			},
+			{
+				Name:    "usagerecord_provider_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{UsageRecordsColumns[1], UsageRecordsColumns[12]},
+			},
+			{
+				Name:    "usagerecord_user_id_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{UsageRecordsColumns[3], UsageRecordsColumns[12]},
 			},
 		},
 	}
@@ -1068,19 +1356,26 @@ var (
 	}
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
+		APIKeysTable,
 		AccountBindingsTable,
 		AgentRuntimesTable,
+		ApprovalRequestsTable,
 		AttachTokensTable,
+		AuditLogsTable,
 		ChannelAccountsTable,
 		CollaborationEventsTable,
 		ConfigSectionsTable,
 		CronJobsTable,
+		FeedEntriesTable,
+		FeedSubscriptionsTable,
+		FeedbacksTable,
 		IdempotencyRecordsTable,
 		MembershipsTable,
 		ModelCatalogsTable,
 		ModelRoutesTable,
 		NotificationBindingsTable,
 		NotificationRoutesTable,
+		NotifyRulesTable,
 		PermissionRulesTable,
 		PromptsTable,
 		PromptBindingsTable,
@@ -1090,6 +1385,7 @@ var (
 		TenantsTable,
 		ToolEventsTable,
 		ToolSessionsTable,
+		UsageRecordsTable,
 		UsersTable,
 	}
 )
@@ -0,0 +1,535 @@
+// Code generated by ent, DO NOT EDIT.
+
+package notifyrule
+
+import (
+	"nekobot/pkg/storage/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLTE(FieldID, id))
+}
+
+// IDEqualFold applies the EqualFold predicate on the ID field.
+func IDEqualFold(id string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEqualFold(FieldID, id))
+}
+
+// IDContainsFold applies the ContainsFold predicate on the ID field.
+func IDContainsFold(id string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldContainsFold(FieldID, id))
+}
+
+// Name applies equality check predicate on the "name" field. It's identical to NameEQ.
+func Name(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldName, v))
+}
+
+// EventTypesJSON applies equality check predicate on the "event_types_json" field. It's identical to EventTypesJSONEQ.
+func EventTypesJSON(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldEventTypesJSON, v))
+}
+
+// Match applies equality check predicate on the "match" field. It's identical to MatchEQ.
+func Match(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldMatch, v))
+}
+
+// ChannelID applies equality check predicate on the "channel_id" field. It's identical to ChannelIDEQ.
+func ChannelID(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldChannelID, v))
+}
+
+// SessionID applies equality check predicate on the "session_id" field. It's identical to SessionIDEQ.
+func SessionID(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldSessionID, v))
+}
+
+// Enabled applies equality check predicate on the "enabled" field. It's identical to EnabledEQ.
+func Enabled(v bool) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldEnabled, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// UpdatedAt applies equality check predicate on the "updated_at" field. It's identical to UpdatedAtEQ.
+func UpdatedAt(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// NameEQ applies the EQ predicate on the "name" field.
+func NameEQ(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldName, v))
+}
+
+// NameNEQ applies the NEQ predicate on the "name" field.
+func NameNEQ(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNEQ(FieldName, v))
+}
+
+// NameIn applies the In predicate on the "name" field.
+func NameIn(vs ...string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldIn(FieldName, vs...))
+}
+
+// NameNotIn applies the NotIn predicate on the "name" field.
+func NameNotIn(vs ...string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNotIn(FieldName, vs...))
+}
+
+// NameGT applies the GT predicate on the "name" field.
+func NameGT(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGT(FieldName, v))
+}
+
+// NameGTE applies the GTE predicate on the "name" field.
+func NameGTE(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGTE(FieldName, v))
+}
+
+// NameLT applies the LT predicate on the "name" field.
+func NameLT(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLT(FieldName, v))
+}
+
+// NameLTE applies the LTE predicate on the "name" field.
+func NameLTE(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLTE(FieldName, v))
+}
+
+// NameContains applies the Contains predicate on the "name" field.
+func NameContains(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldContains(FieldName, v))
+}
+
+// NameHasPrefix applies the HasPrefix predicate on the "name" field.
+func NameHasPrefix(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldHasPrefix(FieldName, v))
+}
+
+// NameHasSuffix applies the HasSuffix predicate on the "name" field.
+func NameHasSuffix(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldHasSuffix(FieldName, v))
+}
+
+// NameEqualFold applies the EqualFold predicate on the "name" field.
+func NameEqualFold(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEqualFold(FieldName, v))
+}
+
+// NameContainsFold applies the ContainsFold predicate on the "name" field.
+func NameContainsFold(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldContainsFold(FieldName, v))
+}
+
+// EventTypesJSONEQ applies the EQ predicate on the "event_types_json" field.
+func EventTypesJSONEQ(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldEventTypesJSON, v))
+}
+
+// EventTypesJSONNEQ applies the NEQ predicate on the "event_types_json" field.
+func EventTypesJSONNEQ(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNEQ(FieldEventTypesJSON, v))
+}
+
+// EventTypesJSONIn applies the In predicate on the "event_types_json" field.
+func EventTypesJSONIn(vs ...string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldIn(FieldEventTypesJSON, vs...))
+}
+
+// EventTypesJSONNotIn applies the NotIn predicate on the "event_types_json" field.
+func EventTypesJSONNotIn(vs ...string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNotIn(FieldEventTypesJSON, vs...))
+}
+
+// EventTypesJSONGT applies the GT predicate on the "event_types_json" field.
+func EventTypesJSONGT(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGT(FieldEventTypesJSON, v))
+}
+
+// EventTypesJSONGTE applies the GTE predicate on the "event_types_json" field.
+func EventTypesJSONGTE(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGTE(FieldEventTypesJSON, v))
+}
+
+// EventTypesJSONLT applies the LT predicate on the "event_types_json" field.
+func EventTypesJSONLT(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLT(FieldEventTypesJSON, v))
+}
+
+// EventTypesJSONLTE applies the LTE predicate on the "event_types_json" field.
+func EventTypesJSONLTE(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLTE(FieldEventTypesJSON, v))
+}
+
+// EventTypesJSONContains applies the Contains predicate on the "event_types_json" field.
+func EventTypesJSONContains(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldContains(FieldEventTypesJSON, v))
+}
+
+// EventTypesJSONHasPrefix applies the HasPrefix predicate on the "event_types_json" field.
+func EventTypesJSONHasPrefix(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldHasPrefix(FieldEventTypesJSON, v))
+}
+
+// EventTypesJSONHasSuffix applies the HasSuffix predicate on the "event_types_json" field.
+func EventTypesJSONHasSuffix(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldHasSuffix(FieldEventTypesJSON, v))
+}
+
+// EventTypesJSONEqualFold applies the EqualFold predicate on the "event_types_json" field.
+func EventTypesJSONEqualFold(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEqualFold(FieldEventTypesJSON, v))
+}
+
+// EventTypesJSONContainsFold applies the ContainsFold predicate on the "event_types_json" field.
+func EventTypesJSONContainsFold(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldContainsFold(FieldEventTypesJSON, v))
+}
+
+// MatchEQ applies the EQ predicate on the "match" field.
+func MatchEQ(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldMatch, v))
+}
+
+// MatchNEQ applies the NEQ predicate on the "match" field.
+func MatchNEQ(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNEQ(FieldMatch, v))
+}
+
+// MatchIn applies the In predicate on the "match" field.
+func MatchIn(vs ...string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldIn(FieldMatch, vs...))
+}
+
+// MatchNotIn applies the NotIn predicate on the "match" field.
+func MatchNotIn(vs ...string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNotIn(FieldMatch, vs...))
+}
+
+// MatchGT applies the GT predicate on the "match" field.
+func MatchGT(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGT(FieldMatch, v))
+}
+
+// MatchGTE applies the GTE predicate on the "match" field.
+func MatchGTE(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGTE(FieldMatch, v))
+}
+
+// MatchLT applies the LT predicate on the "match" field.
+func MatchLT(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLT(FieldMatch, v))
+}
+
+// MatchLTE applies the LTE predicate on the "match" field.
+func MatchLTE(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLTE(FieldMatch, v))
+}
+
+// MatchContains applies the Contains predicate on the "match" field.
+func MatchContains(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldContains(FieldMatch, v))
+}
+
+// MatchHasPrefix applies the HasPrefix predicate on the "match" field.
+func MatchHasPrefix(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldHasPrefix(FieldMatch, v))
+}
+
+// MatchHasSuffix applies the HasSuffix predicate on the "match" field.
+func MatchHasSuffix(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldHasSuffix(FieldMatch, v))
+}
+
+// MatchEqualFold applies the EqualFold predicate on the "match" field.
+func MatchEqualFold(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEqualFold(FieldMatch, v))
+}
+
+// MatchContainsFold applies the ContainsFold predicate on the "match" field.
+func MatchContainsFold(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldContainsFold(FieldMatch, v))
+}
+
+// ChannelIDEQ applies the EQ predicate on the "channel_id" field.
+func ChannelIDEQ(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldChannelID, v))
+}
+
+// ChannelIDNEQ applies the NEQ predicate on the "channel_id" field.
+func ChannelIDNEQ(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNEQ(FieldChannelID, v))
+}
+
+// ChannelIDIn applies the In predicate on the "channel_id" field.
+func ChannelIDIn(vs ...string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldIn(FieldChannelID, vs...))
+}
+
+// ChannelIDNotIn applies the NotIn predicate on the "channel_id" field.
+func ChannelIDNotIn(vs ...string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNotIn(FieldChannelID, vs...))
+}
+
+// ChannelIDGT applies the GT predicate on the "channel_id" field.
+func ChannelIDGT(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGT(FieldChannelID, v))
+}
+
+// ChannelIDGTE applies the GTE predicate on the "channel_id" field.
+func ChannelIDGTE(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGTE(FieldChannelID, v))
+}
+
+// ChannelIDLT applies the LT predicate on the "channel_id" field.
+func ChannelIDLT(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLT(FieldChannelID, v))
+}
+
+// ChannelIDLTE applies the LTE predicate on the "channel_id" field.
+func ChannelIDLTE(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLTE(FieldChannelID, v))
+}
+
+// ChannelIDContains applies the Contains predicate on the "channel_id" field.
+func ChannelIDContains(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldContains(FieldChannelID, v))
+}
+
+// ChannelIDHasPrefix applies the HasPrefix predicate on the "channel_id" field.
+func ChannelIDHasPrefix(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldHasPrefix(FieldChannelID, v))
+}
+
+// ChannelIDHasSuffix applies the HasSuffix predicate on the "channel_id" field.
+func ChannelIDHasSuffix(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldHasSuffix(FieldChannelID, v))
+}
+
+// ChannelIDEqualFold applies the EqualFold predicate on the "channel_id" field.
+func ChannelIDEqualFold(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEqualFold(FieldChannelID, v))
+}
+
+// ChannelIDContainsFold applies the ContainsFold predicate on the "channel_id" field.
+func ChannelIDContainsFold(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldContainsFold(FieldChannelID, v))
+}
+
+// SessionIDEQ applies the EQ predicate on the "session_id" field.
+func SessionIDEQ(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldSessionID, v))
+}
+
+// SessionIDNEQ applies the NEQ predicate on the "session_id" field.
+func SessionIDNEQ(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNEQ(FieldSessionID, v))
+}
+
+// SessionIDIn applies the In predicate on the "session_id" field.
+func SessionIDIn(vs ...string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldIn(FieldSessionID, vs...))
+}
+
+// SessionIDNotIn applies the NotIn predicate on the "session_id" field.
+func SessionIDNotIn(vs ...string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNotIn(FieldSessionID, vs...))
+}
+
+// SessionIDGT applies the GT predicate on the "session_id" field.
+func SessionIDGT(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGT(FieldSessionID, v))
+}
+
+// SessionIDGTE applies the GTE predicate on the "session_id" field.
+func SessionIDGTE(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGTE(FieldSessionID, v))
+}
+
+// SessionIDLT applies the LT predicate on the "session_id" field.
+func SessionIDLT(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLT(FieldSessionID, v))
+}
+
+// SessionIDLTE applies the LTE predicate on the "session_id" field.
+func SessionIDLTE(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLTE(FieldSessionID, v))
+}
+
+// SessionIDContains applies the Contains predicate on the "session_id" field.
+func SessionIDContains(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldContains(FieldSessionID, v))
+}
+
+// SessionIDHasPrefix applies the HasPrefix predicate on the "session_id" field.
+func SessionIDHasPrefix(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldHasPrefix(FieldSessionID, v))
+}
+
+// SessionIDHasSuffix applies the HasSuffix predicate on the "session_id" field.
+func SessionIDHasSuffix(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldHasSuffix(FieldSessionID, v))
+}
+
+// SessionIDEqualFold applies the EqualFold predicate on the "session_id" field.
+func SessionIDEqualFold(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEqualFold(FieldSessionID, v))
+}
+
+// SessionIDContainsFold applies the ContainsFold predicate on the "session_id" field.
+func SessionIDContainsFold(v string) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldContainsFold(FieldSessionID, v))
+}
+
+// EnabledEQ applies the EQ predicate on the "enabled" field.
+func EnabledEQ(v bool) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldEnabled, v))
+}
+
+// EnabledNEQ applies the NEQ predicate on the "enabled" field.
+func EnabledNEQ(v bool) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNEQ(FieldEnabled, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// UpdatedAtEQ applies the EQ predicate on the "updated_at" field.
+func UpdatedAtEQ(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtNEQ applies the NEQ predicate on the "updated_at" field.
+func UpdatedAtNEQ(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtIn applies the In predicate on the "updated_at" field.
+func UpdatedAtIn(vs ...time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtNotIn applies the NotIn predicate on the "updated_at" field.
+func UpdatedAtNotIn(vs ...time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldNotIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtGT applies the GT predicate on the "updated_at" field.
+func UpdatedAtGT(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtGTE applies the GTE predicate on the "updated_at" field.
+func UpdatedAtGTE(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldGTE(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLT applies the LT predicate on the "updated_at" field.
+func UpdatedAtLT(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLTE applies the LTE predicate on the "updated_at" field.
+func UpdatedAtLTE(v time.Time) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.FieldLTE(FieldUpdatedAt, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.NotifyRule) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.NotifyRule) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.NotifyRule) predicate.NotifyRule {
+	return predicate.NotifyRule(sql.NotPredicates(p))
+}
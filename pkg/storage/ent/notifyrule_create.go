@@ -0,0 +1,379 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nekobot/pkg/storage/ent/notifyrule"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// NotifyRuleCreate is the builder for creating a NotifyRule entity.
+type NotifyRuleCreate struct {
+	config
+	mutation *NotifyRuleMutation
+	hooks    []Hook
+}
+
+// SetName sets the "name" field.
+func (_c *NotifyRuleCreate) SetName(v string) *NotifyRuleCreate {
+	_c.mutation.SetName(v)
+	return _c
+}
+
+// SetEventTypesJSON sets the "event_types_json" field.
+func (_c *NotifyRuleCreate) SetEventTypesJSON(v string) *NotifyRuleCreate {
+	_c.mutation.SetEventTypesJSON(v)
+	return _c
+}
+
+// SetNillableEventTypesJSON sets the "event_types_json" field if the given value is not nil.
+func (_c *NotifyRuleCreate) SetNillableEventTypesJSON(v *string) *NotifyRuleCreate {
+	if v != nil {
+		_c.SetEventTypesJSON(*v)
+	}
+	return _c
+}
+
+// SetMatch sets the "match" field.
+func (_c *NotifyRuleCreate) SetMatch(v string) *NotifyRuleCreate {
+	_c.mutation.SetMatch(v)
+	return _c
+}
+
+// SetNillableMatch sets the "match" field if the given value is not nil.
+func (_c *NotifyRuleCreate) SetNillableMatch(v *string) *NotifyRuleCreate {
+	if v != nil {
+		_c.SetMatch(*v)
+	}
+	return _c
+}
+
+// SetChannelID sets the "channel_id" field.
+func (_c *NotifyRuleCreate) SetChannelID(v string) *NotifyRuleCreate {
+	_c.mutation.SetChannelID(v)
+	return _c
+}
+
+// SetSessionID sets the "session_id" field.
+func (_c *NotifyRuleCreate) SetSessionID(v string) *NotifyRuleCreate {
+	_c.mutation.SetSessionID(v)
+	return _c
+}
+
+// SetEnabled sets the "enabled" field.
+func (_c *NotifyRuleCreate) SetEnabled(v bool) *NotifyRuleCreate {
+	_c.mutation.SetEnabled(v)
+	return _c
+}
+
+// SetNillableEnabled sets the "enabled" field if the given value is not nil.
+func (_c *NotifyRuleCreate) SetNillableEnabled(v *bool) *NotifyRuleCreate {
+	if v != nil {
+		_c.SetEnabled(*v)
+	}
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *NotifyRuleCreate) SetCreatedAt(v time.Time) *NotifyRuleCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *NotifyRuleCreate) SetNillableCreatedAt(v *time.Time) *NotifyRuleCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_c *NotifyRuleCreate) SetUpdatedAt(v time.Time) *NotifyRuleCreate {
+	_c.mutation.SetUpdatedAt(v)
+	return _c
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (_c *NotifyRuleCreate) SetNillableUpdatedAt(v *time.Time) *NotifyRuleCreate {
+	if v != nil {
+		_c.SetUpdatedAt(*v)
+	}
+	return _c
+}
+
+// SetID sets the "id" field.
+func (_c *NotifyRuleCreate) SetID(v string) *NotifyRuleCreate {
+	_c.mutation.SetID(v)
+	return _c
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (_c *NotifyRuleCreate) SetNillableID(v *string) *NotifyRuleCreate {
+	if v != nil {
+		_c.SetID(*v)
+	}
+	return _c
+}
+
+// Mutation returns the NotifyRuleMutation object of the builder.
+func (_c *NotifyRuleCreate) Mutation() *NotifyRuleMutation {
+	return _c.mutation
+}
+
+// Save creates the NotifyRule in the database.
+func (_c *NotifyRuleCreate) Save(ctx context.Context) (*NotifyRule, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *NotifyRuleCreate) SaveX(ctx context.Context) *NotifyRule {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *NotifyRuleCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *NotifyRuleCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *NotifyRuleCreate) defaults() {
+	if _, ok := _c.mutation.EventTypesJSON(); !ok {
+		v := notifyrule.DefaultEventTypesJSON
+		_c.mutation.SetEventTypesJSON(v)
+	}
+	if _, ok := _c.mutation.Match(); !ok {
+		v := notifyrule.DefaultMatch
+		_c.mutation.SetMatch(v)
+	}
+	if _, ok := _c.mutation.Enabled(); !ok {
+		v := notifyrule.DefaultEnabled
+		_c.mutation.SetEnabled(v)
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := notifyrule.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		v := notifyrule.DefaultUpdatedAt()
+		_c.mutation.SetUpdatedAt(v)
+	}
+	if _, ok := _c.mutation.ID(); !ok {
+		v := notifyrule.DefaultID()
+		_c.mutation.SetID(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *NotifyRuleCreate) check() error {
+	if _, ok := _c.mutation.Name(); !ok {
+		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "NotifyRule.name"`)}
+	}
+	if v, ok := _c.mutation.Name(); ok {
+		if err := notifyrule.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "NotifyRule.name": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.EventTypesJSON(); !ok {
+		return &ValidationError{Name: "event_types_json", err: errors.New(`ent: missing required field "NotifyRule.event_types_json"`)}
+	}
+	if _, ok := _c.mutation.Match(); !ok {
+		return &ValidationError{Name: "match", err: errors.New(`ent: missing required field "NotifyRule.match"`)}
+	}
+	if _, ok := _c.mutation.ChannelID(); !ok {
+		return &ValidationError{Name: "channel_id", err: errors.New(`ent: missing required field "NotifyRule.channel_id"`)}
+	}
+	if v, ok := _c.mutation.ChannelID(); ok {
+		if err := notifyrule.ChannelIDValidator(v); err != nil {
+			return &ValidationError{Name: "channel_id", err: fmt.Errorf(`ent: validator failed for field "NotifyRule.channel_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.SessionID(); !ok {
+		return &ValidationError{Name: "session_id", err: errors.New(`ent: missing required field "NotifyRule.session_id"`)}
+	}
+	if v, ok := _c.mutation.SessionID(); ok {
+		if err := notifyrule.SessionIDValidator(v); err != nil {
+			return &ValidationError{Name: "session_id", err: fmt.Errorf(`ent: validator failed for field "NotifyRule.session_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Enabled(); !ok {
+		return &ValidationError{Name: "enabled", err: errors.New(`ent: missing required field "NotifyRule.enabled"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "NotifyRule.created_at"`)}
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "NotifyRule.updated_at"`)}
+	}
+	return nil
+}
+
+func (_c *NotifyRuleCreate) sqlSave(ctx context.Context) (*NotifyRule, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(string); ok {
+			_node.ID = id
+		} else {
+			return nil, fmt.Errorf("unexpected NotifyRule.ID type: %T", _spec.ID.Value)
+		}
+	}
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *NotifyRuleCreate) createSpec() (*NotifyRule, *sqlgraph.CreateSpec) {
+	var (
+		_node = &NotifyRule{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(notifyrule.Table, sqlgraph.NewFieldSpec(notifyrule.FieldID, field.TypeString))
+	)
+	if id, ok := _c.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := _c.mutation.Name(); ok {
+		_spec.SetField(notifyrule.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := _c.mutation.EventTypesJSON(); ok {
+		_spec.SetField(notifyrule.FieldEventTypesJSON, field.TypeString, value)
+		_node.EventTypesJSON = value
+	}
+	if value, ok := _c.mutation.Match(); ok {
+		_spec.SetField(notifyrule.FieldMatch, field.TypeString, value)
+		_node.Match = value
+	}
+	if value, ok := _c.mutation.ChannelID(); ok {
+		_spec.SetField(notifyrule.FieldChannelID, field.TypeString, value)
+		_node.ChannelID = value
+	}
+	if value, ok := _c.mutation.SessionID(); ok {
+		_spec.SetField(notifyrule.FieldSessionID, field.TypeString, value)
+		_node.SessionID = value
+	}
+	if value, ok := _c.mutation.Enabled(); ok {
+		_spec.SetField(notifyrule.FieldEnabled, field.TypeBool, value)
+		_node.Enabled = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(notifyrule.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.UpdatedAt(); ok {
+		_spec.SetField(notifyrule.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	return _node, _spec
+}
+
+// NotifyRuleCreateBulk is the builder for creating many NotifyRule entities in bulk.
+type NotifyRuleCreateBulk struct {
+	config
+	err      error
+	builders []*NotifyRuleCreate
+}
+
+// Save creates the NotifyRule entities in the database.
+func (_c *NotifyRuleCreateBulk) Save(ctx context.Context) ([]*NotifyRule, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*NotifyRule, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*NotifyRuleMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *NotifyRuleCreateBulk) SaveX(ctx context.Context) []*NotifyRule {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *NotifyRuleCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *NotifyRuleCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
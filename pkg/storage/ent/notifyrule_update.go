@@ -0,0 +1,462 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nekobot/pkg/storage/ent/notifyrule"
+	"nekobot/pkg/storage/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// NotifyRuleUpdate is the builder for updating NotifyRule entities.
+type NotifyRuleUpdate struct {
+	config
+	hooks    []Hook
+	mutation *NotifyRuleMutation
+}
+
+// Where appends a list predicates to the NotifyRuleUpdate builder.
+func (_u *NotifyRuleUpdate) Where(ps ...predicate.NotifyRule) *NotifyRuleUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetName sets the "name" field.
+func (_u *NotifyRuleUpdate) SetName(v string) *NotifyRuleUpdate {
+	_u.mutation.SetName(v)
+	return _u
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (_u *NotifyRuleUpdate) SetNillableName(v *string) *NotifyRuleUpdate {
+	if v != nil {
+		_u.SetName(*v)
+	}
+	return _u
+}
+
+// SetEventTypesJSON sets the "event_types_json" field.
+func (_u *NotifyRuleUpdate) SetEventTypesJSON(v string) *NotifyRuleUpdate {
+	_u.mutation.SetEventTypesJSON(v)
+	return _u
+}
+
+// SetNillableEventTypesJSON sets the "event_types_json" field if the given value is not nil.
+func (_u *NotifyRuleUpdate) SetNillableEventTypesJSON(v *string) *NotifyRuleUpdate {
+	if v != nil {
+		_u.SetEventTypesJSON(*v)
+	}
+	return _u
+}
+
+// SetMatch sets the "match" field.
+func (_u *NotifyRuleUpdate) SetMatch(v string) *NotifyRuleUpdate {
+	_u.mutation.SetMatch(v)
+	return _u
+}
+
+// SetNillableMatch sets the "match" field if the given value is not nil.
+func (_u *NotifyRuleUpdate) SetNillableMatch(v *string) *NotifyRuleUpdate {
+	if v != nil {
+		_u.SetMatch(*v)
+	}
+	return _u
+}
+
+// SetChannelID sets the "channel_id" field.
+func (_u *NotifyRuleUpdate) SetChannelID(v string) *NotifyRuleUpdate {
+	_u.mutation.SetChannelID(v)
+	return _u
+}
+
+// SetNillableChannelID sets the "channel_id" field if the given value is not nil.
+func (_u *NotifyRuleUpdate) SetNillableChannelID(v *string) *NotifyRuleUpdate {
+	if v != nil {
+		_u.SetChannelID(*v)
+	}
+	return _u
+}
+
+// SetSessionID sets the "session_id" field.
+func (_u *NotifyRuleUpdate) SetSessionID(v string) *NotifyRuleUpdate {
+	_u.mutation.SetSessionID(v)
+	return _u
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (_u *NotifyRuleUpdate) SetNillableSessionID(v *string) *NotifyRuleUpdate {
+	if v != nil {
+		_u.SetSessionID(*v)
+	}
+	return _u
+}
+
+// SetEnabled sets the "enabled" field.
+func (_u *NotifyRuleUpdate) SetEnabled(v bool) *NotifyRuleUpdate {
+	_u.mutation.SetEnabled(v)
+	return _u
+}
+
+// SetNillableEnabled sets the "enabled" field if the given value is not nil.
+func (_u *NotifyRuleUpdate) SetNillableEnabled(v *bool) *NotifyRuleUpdate {
+	if v != nil {
+		_u.SetEnabled(*v)
+	}
+	return _u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *NotifyRuleUpdate) SetUpdatedAt(v time.Time) *NotifyRuleUpdate {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// Mutation returns the NotifyRuleMutation object of the builder.
+func (_u *NotifyRuleUpdate) Mutation() *NotifyRuleMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *NotifyRuleUpdate) Save(ctx context.Context) (int, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *NotifyRuleUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *NotifyRuleUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *NotifyRuleUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_u *NotifyRuleUpdate) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
+		v := notifyrule.UpdateDefaultUpdatedAt()
+		_u.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *NotifyRuleUpdate) check() error {
+	if v, ok := _u.mutation.Name(); ok {
+		if err := notifyrule.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "NotifyRule.name": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.ChannelID(); ok {
+		if err := notifyrule.ChannelIDValidator(v); err != nil {
+			return &ValidationError{Name: "channel_id", err: fmt.Errorf(`ent: validator failed for field "NotifyRule.channel_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.SessionID(); ok {
+		if err := notifyrule.SessionIDValidator(v); err != nil {
+			return &ValidationError{Name: "session_id", err: fmt.Errorf(`ent: validator failed for field "NotifyRule.session_id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *NotifyRuleUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(notifyrule.Table, notifyrule.Columns, sqlgraph.NewFieldSpec(notifyrule.FieldID, field.TypeString))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Name(); ok {
+		_spec.SetField(notifyrule.FieldName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.EventTypesJSON(); ok {
+		_spec.SetField(notifyrule.FieldEventTypesJSON, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Match(); ok {
+		_spec.SetField(notifyrule.FieldMatch, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.ChannelID(); ok {
+		_spec.SetField(notifyrule.FieldChannelID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.SessionID(); ok {
+		_spec.SetField(notifyrule.FieldSessionID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Enabled(); ok {
+		_spec.SetField(notifyrule.FieldEnabled, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(notifyrule.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{notifyrule.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// NotifyRuleUpdateOne is the builder for updating a single NotifyRule entity.
+type NotifyRuleUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *NotifyRuleMutation
+}
+
+// SetName sets the "name" field.
+func (_u *NotifyRuleUpdateOne) SetName(v string) *NotifyRuleUpdateOne {
+	_u.mutation.SetName(v)
+	return _u
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (_u *NotifyRuleUpdateOne) SetNillableName(v *string) *NotifyRuleUpdateOne {
+	if v != nil {
+		_u.SetName(*v)
+	}
+	return _u
+}
+
+// SetEventTypesJSON sets the "event_types_json" field.
+func (_u *NotifyRuleUpdateOne) SetEventTypesJSON(v string) *NotifyRuleUpdateOne {
+	_u.mutation.SetEventTypesJSON(v)
+	return _u
+}
+
+// SetNillableEventTypesJSON sets the "event_types_json" field if the given value is not nil.
+func (_u *NotifyRuleUpdateOne) SetNillableEventTypesJSON(v *string) *NotifyRuleUpdateOne {
+	if v != nil {
+		_u.SetEventTypesJSON(*v)
+	}
+	return _u
+}
+
+// SetMatch sets the "match" field.
+func (_u *NotifyRuleUpdateOne) SetMatch(v string) *NotifyRuleUpdateOne {
+	_u.mutation.SetMatch(v)
+	return _u
+}
+
+// SetNillableMatch sets the "match" field if the given value is not nil.
+func (_u *NotifyRuleUpdateOne) SetNillableMatch(v *string) *NotifyRuleUpdateOne {
+	if v != nil {
+		_u.SetMatch(*v)
+	}
+	return _u
+}
+
+// SetChannelID sets the "channel_id" field.
+func (_u *NotifyRuleUpdateOne) SetChannelID(v string) *NotifyRuleUpdateOne {
+	_u.mutation.SetChannelID(v)
+	return _u
+}
+
+// SetNillableChannelID sets the "channel_id" field if the given value is not nil.
+func (_u *NotifyRuleUpdateOne) SetNillableChannelID(v *string) *NotifyRuleUpdateOne {
+	if v != nil {
+		_u.SetChannelID(*v)
+	}
+	return _u
+}
+
+// SetSessionID sets the "session_id" field.
+func (_u *NotifyRuleUpdateOne) SetSessionID(v string) *NotifyRuleUpdateOne {
+	_u.mutation.SetSessionID(v)
+	return _u
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (_u *NotifyRuleUpdateOne) SetNillableSessionID(v *string) *NotifyRuleUpdateOne {
+	if v != nil {
+		_u.SetSessionID(*v)
+	}
+	return _u
+}
+
+// SetEnabled sets the "enabled" field.
+func (_u *NotifyRuleUpdateOne) SetEnabled(v bool) *NotifyRuleUpdateOne {
+	_u.mutation.SetEnabled(v)
+	return _u
+}
+
+// SetNillableEnabled sets the "enabled" field if the given value is not nil.
+func (_u *NotifyRuleUpdateOne) SetNillableEnabled(v *bool) *NotifyRuleUpdateOne {
+	if v != nil {
+		_u.SetEnabled(*v)
+	}
+	return _u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *NotifyRuleUpdateOne) SetUpdatedAt(v time.Time) *NotifyRuleUpdateOne {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// Mutation returns the NotifyRuleMutation object of the builder.
+func (_u *NotifyRuleUpdateOne) Mutation() *NotifyRuleMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the NotifyRuleUpdate builder.
+func (_u *NotifyRuleUpdateOne) Where(ps ...predicate.NotifyRule) *NotifyRuleUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *NotifyRuleUpdateOne) Select(field string, fields ...string) *NotifyRuleUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated NotifyRule entity.
+func (_u *NotifyRuleUpdateOne) Save(ctx context.Context) (*NotifyRule, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *NotifyRuleUpdateOne) SaveX(ctx context.Context) *NotifyRule {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *NotifyRuleUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *NotifyRuleUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_u *NotifyRuleUpdateOne) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
+		v := notifyrule.UpdateDefaultUpdatedAt()
+		_u.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *NotifyRuleUpdateOne) check() error {
+	if v, ok := _u.mutation.Name(); ok {
+		if err := notifyrule.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "NotifyRule.name": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.ChannelID(); ok {
+		if err := notifyrule.ChannelIDValidator(v); err != nil {
+			return &ValidationError{Name: "channel_id", err: fmt.Errorf(`ent: validator failed for field "NotifyRule.channel_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.SessionID(); ok {
+		if err := notifyrule.SessionIDValidator(v); err != nil {
+			return &ValidationError{Name: "session_id", err: fmt.Errorf(`ent: validator failed for field "NotifyRule.session_id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *NotifyRuleUpdateOne) sqlSave(ctx context.Context) (_node *NotifyRule, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(notifyrule.Table, notifyrule.Columns, sqlgraph.NewFieldSpec(notifyrule.FieldID, field.TypeString))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "NotifyRule.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, notifyrule.FieldID)
+		for _, f := range fields {
+			if !notifyrule.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != notifyrule.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Name(); ok {
+		_spec.SetField(notifyrule.FieldName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.EventTypesJSON(); ok {
+		_spec.SetField(notifyrule.FieldEventTypesJSON, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Match(); ok {
+		_spec.SetField(notifyrule.FieldMatch, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.ChannelID(); ok {
+		_spec.SetField(notifyrule.FieldChannelID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.SessionID(); ok {
+		_spec.SetField(notifyrule.FieldSessionID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Enabled(); ok {
+		_spec.SetField(notifyrule.FieldEnabled, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(notifyrule.FieldUpdatedAt, field.TypeTime, value)
+	}
+	_node = &NotifyRule{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{notifyrule.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
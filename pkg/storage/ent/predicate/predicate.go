@@ -6,15 +6,24 @@ import (
 	"entgo.io/ent/dialect/sql"
 )
 
+// APIKey is the predicate function for apikey builders.
+type APIKey func(*sql.Selector)
+
 // AccountBinding is the predicate function for accountbinding builders.
 type AccountBinding func(*sql.Selector)
 
 // AgentRuntime is the predicate function for agentruntime builders.
 type AgentRuntime func(*sql.Selector)
 
+// ApprovalRequest is the predicate function for approvalrequest builders.
+type ApprovalRequest func(*sql.Selector)
+
 // AttachToken is the predicate function for attachtoken builders.
 type AttachToken func(*sql.Selector)
 
+// AuditLog is the predicate function for auditlog builders.
+type AuditLog func(*sql.Selector)
+
 // ChannelAccount is the predicate function for channelaccount builders.
 type ChannelAccount func(*sql.Selector)
 
@@ -27,6 +36,15 @@ type ConfigSection func(*sql.Selector)
 // CronJob is the predicate function for cronjob builders.
 type CronJob func(*sql.Selector)
 
+// FeedEntry is the predicate function for feedentry builders.
+type FeedEntry func(*sql.Selector)
+
+// FeedSubscription is the predicate function for feedsubscription builders.
+type FeedSubscription func(*sql.Selector)
+
+// Feedback is the predicate function for feedback builders.
+type Feedback func(*sql.Selector)
+
 // IdempotencyRecord is the predicate function for idempotencyrecord builders.
 type IdempotencyRecord func(*sql.Selector)
 
@@ -45,6 +63,9 @@ type NotificationBinding func(*sql.Selector)
 // NotificationRoute is the predicate function for notificationroute builders.
 type NotificationRoute func(*sql.Selector)
 
+// NotifyRule is the predicate function for notifyrule builders.
+type NotifyRule func(*sql.Selector)
+
 // PermissionRule is the predicate function for permissionrule builders.
 type PermissionRule func(*sql.Selector)
 
@@ -72,5 +93,8 @@ type ToolEvent func(*sql.Selector)
 // ToolSession is the predicate function for toolsession builders.
 type ToolSession func(*sql.Selector)
 
+// UsageRecord is the predicate function for usagerecord builders.
+type UsageRecord func(*sql.Selector)
+
 // User is the predicate function for user builders.
 type User func(*sql.Selector)
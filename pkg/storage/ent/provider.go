@@ -37,6 +37,20 @@ type Provider struct {
 	APIFormat string `json:"api_format,omitempty"`
 	// Timeout holds the value of the "timeout" field.
 	Timeout int `json:"timeout,omitempty"`
+	// Stream holds the value of the "stream" field.
+	Stream bool `json:"stream,omitempty"`
+	// SystemPrefix holds the value of the "system_prefix" field.
+	SystemPrefix string `json:"system_prefix,omitempty"`
+	// APIVersion holds the value of the "api_version" field.
+	APIVersion string `json:"api_version,omitempty"`
+	// DeploymentsJSON holds the value of the "deployments_json" field.
+	DeploymentsJSON string `json:"deployments_json,omitempty"`
+	// MiddlewareJSON holds the value of the "middleware_json" field.
+	MiddlewareJSON string `json:"middleware_json,omitempty"`
+	// CapabilitiesJSON holds the value of the "capabilities_json" field.
+	CapabilitiesJSON string `json:"capabilities_json,omitempty"`
+	// TenantID holds the value of the "tenant_id" field.
+	TenantID string `json:"tenant_id,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	// UpdatedAt holds the value of the "updated_at" field.
@@ -49,11 +63,11 @@ func (*Provider) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case provider.FieldEnabled:
+		case provider.FieldEnabled, provider.FieldStream:
 			values[i] = new(sql.NullBool)
 		case provider.FieldDefaultWeight, provider.FieldTimeout:
 			values[i] = new(sql.NullInt64)
-		case provider.FieldID, provider.FieldName, provider.FieldProviderKind, provider.FieldAPIKey, provider.FieldAPIBase, provider.FieldProxy, provider.FieldDefaultTestModel, provider.FieldAPIFormat:
+		case provider.FieldID, provider.FieldName, provider.FieldProviderKind, provider.FieldAPIKey, provider.FieldAPIBase, provider.FieldProxy, provider.FieldDefaultTestModel, provider.FieldAPIFormat, provider.FieldSystemPrefix, provider.FieldAPIVersion, provider.FieldDeploymentsJSON, provider.FieldMiddlewareJSON, provider.FieldCapabilitiesJSON, provider.FieldTenantID:
 			values[i] = new(sql.NullString)
 		case provider.FieldCreatedAt, provider.FieldUpdatedAt:
 			values[i] = new(sql.NullTime)
@@ -138,6 +152,48 @@ func (_m *Provider) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				_m.Timeout = int(value.Int64)
 			}
+		case provider.FieldStream:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field stream", values[i])
+			} else if value.Valid {
+				_m.Stream = value.Bool
+			}
+		case provider.FieldSystemPrefix:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field system_prefix", values[i])
+			} else if value.Valid {
+				_m.SystemPrefix = value.String
+			}
+		case provider.FieldAPIVersion:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field api_version", values[i])
+			} else if value.Valid {
+				_m.APIVersion = value.String
+			}
+		case provider.FieldDeploymentsJSON:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field deployments_json", values[i])
+			} else if value.Valid {
+				_m.DeploymentsJSON = value.String
+			}
+		case provider.FieldMiddlewareJSON:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field middleware_json", values[i])
+			} else if value.Valid {
+				_m.MiddlewareJSON = value.String
+			}
+		case provider.FieldCapabilitiesJSON:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field capabilities_json", values[i])
+			} else if value.Valid {
+				_m.CapabilitiesJSON = value.String
+			}
+		case provider.FieldTenantID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field tenant_id", values[i])
+			} else if value.Valid {
+				_m.TenantID = value.String
+			}
 		case provider.FieldCreatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field created_at", values[i])
@@ -216,6 +272,27 @@ func (_m *Provider) String() string {
 	builder.WriteString("timeout=")
 	builder.WriteString(fmt.Sprintf("%v", _m.Timeout))
 	builder.WriteString(", ")
+	builder.WriteString("stream=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Stream))
+	builder.WriteString(", ")
+	builder.WriteString("system_prefix=")
+	builder.WriteString(_m.SystemPrefix)
+	builder.WriteString(", ")
+	builder.WriteString("api_version=")
+	builder.WriteString(_m.APIVersion)
+	builder.WriteString(", ")
+	builder.WriteString("deployments_json=")
+	builder.WriteString(_m.DeploymentsJSON)
+	builder.WriteString(", ")
+	builder.WriteString("middleware_json=")
+	builder.WriteString(_m.MiddlewareJSON)
+	builder.WriteString(", ")
+	builder.WriteString("capabilities_json=")
+	builder.WriteString(_m.CapabilitiesJSON)
+	builder.WriteString(", ")
+	builder.WriteString("tenant_id=")
+	builder.WriteString(_m.TenantID)
+	builder.WriteString(", ")
 	builder.WriteString("created_at=")
 	builder.WriteString(_m.CreatedAt.Format(time.ANSIC))
 	builder.WriteString(", ")
@@ -33,6 +33,20 @@ const (
 	FieldAPIFormat = "api_format"
 	// FieldTimeout holds the string denoting the timeout field in the database.
 	FieldTimeout = "timeout"
+	// FieldStream holds the string denoting the stream field in the database.
+	FieldStream = "stream"
+	// FieldSystemPrefix holds the string denoting the system_prefix field in the database.
+	FieldSystemPrefix = "system_prefix"
+	// FieldAPIVersion holds the string denoting the api_version field in the database.
+	FieldAPIVersion = "api_version"
+	// FieldDeploymentsJSON holds the string denoting the deployments_json field in the database.
+	FieldDeploymentsJSON = "deployments_json"
+	// FieldMiddlewareJSON holds the string denoting the middleware_json field in the database.
+	FieldMiddlewareJSON = "middleware_json"
+	// FieldCapabilitiesJSON holds the string denoting the capabilities_json field in the database.
+	FieldCapabilitiesJSON = "capabilities_json"
+	// FieldTenantID holds the string denoting the tenant_id field in the database.
+	FieldTenantID = "tenant_id"
 	// FieldCreatedAt holds the string denoting the created_at field in the database.
 	FieldCreatedAt = "created_at"
 	// FieldUpdatedAt holds the string denoting the updated_at field in the database.
@@ -54,6 +68,13 @@ var Columns = []string{
 	FieldDefaultTestModel,
 	FieldAPIFormat,
 	FieldTimeout,
+	FieldStream,
+	FieldSystemPrefix,
+	FieldAPIVersion,
+	FieldDeploymentsJSON,
+	FieldMiddlewareJSON,
+	FieldCapabilitiesJSON,
+	FieldTenantID,
 	FieldCreatedAt,
 	FieldUpdatedAt,
 }
@@ -89,6 +110,20 @@ var (
 	DefaultAPIFormat string
 	// DefaultTimeout holds the default value on creation for the "timeout" field.
 	DefaultTimeout int
+	// DefaultStream holds the default value on creation for the "stream" field.
+	DefaultStream bool
+	// DefaultSystemPrefix holds the default value on creation for the "system_prefix" field.
+	DefaultSystemPrefix string
+	// DefaultAPIVersion holds the default value on creation for the "api_version" field.
+	DefaultAPIVersion string
+	// DefaultDeploymentsJSON holds the default value on creation for the "deployments_json" field.
+	DefaultDeploymentsJSON string
+	// DefaultMiddlewareJSON holds the default value on creation for the "middleware_json" field.
+	DefaultMiddlewareJSON string
+	// DefaultCapabilitiesJSON holds the default value on creation for the "capabilities_json" field.
+	DefaultCapabilitiesJSON string
+	// DefaultTenantID holds the default value on creation for the "tenant_id" field.
+	DefaultTenantID string
 	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
 	DefaultCreatedAt func() time.Time
 	// DefaultUpdatedAt holds the default value on creation for the "updated_at" field.
@@ -157,6 +192,41 @@ func ByTimeout(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldTimeout, opts...).ToFunc()
 }
 
+// ByStream orders the results by the stream field.
+func ByStream(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldStream, opts...).ToFunc()
+}
+
+// BySystemPrefix orders the results by the system_prefix field.
+func BySystemPrefix(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldSystemPrefix, opts...).ToFunc()
+}
+
+// ByAPIVersion orders the results by the api_version field.
+func ByAPIVersion(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAPIVersion, opts...).ToFunc()
+}
+
+// ByDeploymentsJSON orders the results by the deployments_json field.
+func ByDeploymentsJSON(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDeploymentsJSON, opts...).ToFunc()
+}
+
+// ByMiddlewareJSON orders the results by the middleware_json field.
+func ByMiddlewareJSON(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMiddlewareJSON, opts...).ToFunc()
+}
+
+// ByCapabilitiesJSON orders the results by the capabilities_json field.
+func ByCapabilitiesJSON(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCapabilitiesJSON, opts...).ToFunc()
+}
+
+// ByTenantID orders the results by the tenant_id field.
+func ByTenantID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTenantID, opts...).ToFunc()
+}
+
 // ByCreatedAt orders the results by the created_at field.
 func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
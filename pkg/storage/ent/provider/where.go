@@ -114,6 +114,41 @@ func Timeout(v int) predicate.Provider {
 	return predicate.Provider(sql.FieldEQ(FieldTimeout, v))
 }
 
+// Stream applies equality check predicate on the "stream" field. It's identical to StreamEQ.
+func Stream(v bool) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldStream, v))
+}
+
+// SystemPrefix applies equality check predicate on the "system_prefix" field. It's identical to SystemPrefixEQ.
+func SystemPrefix(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldSystemPrefix, v))
+}
+
+// APIVersion applies equality check predicate on the "api_version" field. It's identical to APIVersionEQ.
+func APIVersion(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldAPIVersion, v))
+}
+
+// DeploymentsJSON applies equality check predicate on the "deployments_json" field. It's identical to DeploymentsJSONEQ.
+func DeploymentsJSON(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldDeploymentsJSON, v))
+}
+
+// MiddlewareJSON applies equality check predicate on the "middleware_json" field. It's identical to MiddlewareJSONEQ.
+func MiddlewareJSON(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldMiddlewareJSON, v))
+}
+
+// CapabilitiesJSON applies equality check predicate on the "capabilities_json" field. It's identical to CapabilitiesJSONEQ.
+func CapabilitiesJSON(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldCapabilitiesJSON, v))
+}
+
+// TenantID applies equality check predicate on the "tenant_id" field. It's identical to TenantIDEQ.
+func TenantID(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldTenantID, v))
+}
+
 // CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
 func CreatedAt(v time.Time) predicate.Provider {
 	return predicate.Provider(sql.FieldEQ(FieldCreatedAt, v))
@@ -669,6 +704,406 @@ func TimeoutLTE(v int) predicate.Provider {
 	return predicate.Provider(sql.FieldLTE(FieldTimeout, v))
 }
 
+// StreamEQ applies the EQ predicate on the "stream" field.
+func StreamEQ(v bool) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldStream, v))
+}
+
+// StreamNEQ applies the NEQ predicate on the "stream" field.
+func StreamNEQ(v bool) predicate.Provider {
+	return predicate.Provider(sql.FieldNEQ(FieldStream, v))
+}
+
+// SystemPrefixEQ applies the EQ predicate on the "system_prefix" field.
+func SystemPrefixEQ(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldSystemPrefix, v))
+}
+
+// SystemPrefixNEQ applies the NEQ predicate on the "system_prefix" field.
+func SystemPrefixNEQ(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldNEQ(FieldSystemPrefix, v))
+}
+
+// SystemPrefixIn applies the In predicate on the "system_prefix" field.
+func SystemPrefixIn(vs ...string) predicate.Provider {
+	return predicate.Provider(sql.FieldIn(FieldSystemPrefix, vs...))
+}
+
+// SystemPrefixNotIn applies the NotIn predicate on the "system_prefix" field.
+func SystemPrefixNotIn(vs ...string) predicate.Provider {
+	return predicate.Provider(sql.FieldNotIn(FieldSystemPrefix, vs...))
+}
+
+// SystemPrefixGT applies the GT predicate on the "system_prefix" field.
+func SystemPrefixGT(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldGT(FieldSystemPrefix, v))
+}
+
+// SystemPrefixGTE applies the GTE predicate on the "system_prefix" field.
+func SystemPrefixGTE(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldGTE(FieldSystemPrefix, v))
+}
+
+// SystemPrefixLT applies the LT predicate on the "system_prefix" field.
+func SystemPrefixLT(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldLT(FieldSystemPrefix, v))
+}
+
+// SystemPrefixLTE applies the LTE predicate on the "system_prefix" field.
+func SystemPrefixLTE(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldLTE(FieldSystemPrefix, v))
+}
+
+// SystemPrefixContains applies the Contains predicate on the "system_prefix" field.
+func SystemPrefixContains(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldContains(FieldSystemPrefix, v))
+}
+
+// SystemPrefixHasPrefix applies the HasPrefix predicate on the "system_prefix" field.
+func SystemPrefixHasPrefix(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldHasPrefix(FieldSystemPrefix, v))
+}
+
+// SystemPrefixHasSuffix applies the HasSuffix predicate on the "system_prefix" field.
+func SystemPrefixHasSuffix(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldHasSuffix(FieldSystemPrefix, v))
+}
+
+// SystemPrefixEqualFold applies the EqualFold predicate on the "system_prefix" field.
+func SystemPrefixEqualFold(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEqualFold(FieldSystemPrefix, v))
+}
+
+// SystemPrefixContainsFold applies the ContainsFold predicate on the "system_prefix" field.
+func SystemPrefixContainsFold(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldContainsFold(FieldSystemPrefix, v))
+}
+
+// APIVersionEQ applies the EQ predicate on the "api_version" field.
+func APIVersionEQ(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldAPIVersion, v))
+}
+
+// APIVersionNEQ applies the NEQ predicate on the "api_version" field.
+func APIVersionNEQ(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldNEQ(FieldAPIVersion, v))
+}
+
+// APIVersionIn applies the In predicate on the "api_version" field.
+func APIVersionIn(vs ...string) predicate.Provider {
+	return predicate.Provider(sql.FieldIn(FieldAPIVersion, vs...))
+}
+
+// APIVersionNotIn applies the NotIn predicate on the "api_version" field.
+func APIVersionNotIn(vs ...string) predicate.Provider {
+	return predicate.Provider(sql.FieldNotIn(FieldAPIVersion, vs...))
+}
+
+// APIVersionGT applies the GT predicate on the "api_version" field.
+func APIVersionGT(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldGT(FieldAPIVersion, v))
+}
+
+// APIVersionGTE applies the GTE predicate on the "api_version" field.
+func APIVersionGTE(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldGTE(FieldAPIVersion, v))
+}
+
+// APIVersionLT applies the LT predicate on the "api_version" field.
+func APIVersionLT(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldLT(FieldAPIVersion, v))
+}
+
+// APIVersionLTE applies the LTE predicate on the "api_version" field.
+func APIVersionLTE(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldLTE(FieldAPIVersion, v))
+}
+
+// APIVersionContains applies the Contains predicate on the "api_version" field.
+func APIVersionContains(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldContains(FieldAPIVersion, v))
+}
+
+// APIVersionHasPrefix applies the HasPrefix predicate on the "api_version" field.
+func APIVersionHasPrefix(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldHasPrefix(FieldAPIVersion, v))
+}
+
+// APIVersionHasSuffix applies the HasSuffix predicate on the "api_version" field.
+func APIVersionHasSuffix(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldHasSuffix(FieldAPIVersion, v))
+}
+
+// APIVersionEqualFold applies the EqualFold predicate on the "api_version" field.
+func APIVersionEqualFold(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEqualFold(FieldAPIVersion, v))
+}
+
+// APIVersionContainsFold applies the ContainsFold predicate on the "api_version" field.
+func APIVersionContainsFold(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldContainsFold(FieldAPIVersion, v))
+}
+
+// DeploymentsJSONEQ applies the EQ predicate on the "deployments_json" field.
+func DeploymentsJSONEQ(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldDeploymentsJSON, v))
+}
+
+// DeploymentsJSONNEQ applies the NEQ predicate on the "deployments_json" field.
+func DeploymentsJSONNEQ(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldNEQ(FieldDeploymentsJSON, v))
+}
+
+// DeploymentsJSONIn applies the In predicate on the "deployments_json" field.
+func DeploymentsJSONIn(vs ...string) predicate.Provider {
+	return predicate.Provider(sql.FieldIn(FieldDeploymentsJSON, vs...))
+}
+
+// DeploymentsJSONNotIn applies the NotIn predicate on the "deployments_json" field.
+func DeploymentsJSONNotIn(vs ...string) predicate.Provider {
+	return predicate.Provider(sql.FieldNotIn(FieldDeploymentsJSON, vs...))
+}
+
+// DeploymentsJSONGT applies the GT predicate on the "deployments_json" field.
+func DeploymentsJSONGT(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldGT(FieldDeploymentsJSON, v))
+}
+
+// DeploymentsJSONGTE applies the GTE predicate on the "deployments_json" field.
+func DeploymentsJSONGTE(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldGTE(FieldDeploymentsJSON, v))
+}
+
+// DeploymentsJSONLT applies the LT predicate on the "deployments_json" field.
+func DeploymentsJSONLT(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldLT(FieldDeploymentsJSON, v))
+}
+
+// DeploymentsJSONLTE applies the LTE predicate on the "deployments_json" field.
+func DeploymentsJSONLTE(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldLTE(FieldDeploymentsJSON, v))
+}
+
+// DeploymentsJSONContains applies the Contains predicate on the "deployments_json" field.
+func DeploymentsJSONContains(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldContains(FieldDeploymentsJSON, v))
+}
+
+// DeploymentsJSONHasPrefix applies the HasPrefix predicate on the "deployments_json" field.
+func DeploymentsJSONHasPrefix(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldHasPrefix(FieldDeploymentsJSON, v))
+}
+
+// DeploymentsJSONHasSuffix applies the HasSuffix predicate on the "deployments_json" field.
+func DeploymentsJSONHasSuffix(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldHasSuffix(FieldDeploymentsJSON, v))
+}
+
+// DeploymentsJSONEqualFold applies the EqualFold predicate on the "deployments_json" field.
+func DeploymentsJSONEqualFold(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEqualFold(FieldDeploymentsJSON, v))
+}
+
+// DeploymentsJSONContainsFold applies the ContainsFold predicate on the "deployments_json" field.
+func DeploymentsJSONContainsFold(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldContainsFold(FieldDeploymentsJSON, v))
+}
+
+// MiddlewareJSONEQ applies the EQ predicate on the "middleware_json" field.
+func MiddlewareJSONEQ(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldMiddlewareJSON, v))
+}
+
+// MiddlewareJSONNEQ applies the NEQ predicate on the "middleware_json" field.
+func MiddlewareJSONNEQ(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldNEQ(FieldMiddlewareJSON, v))
+}
+
+// MiddlewareJSONIn applies the In predicate on the "middleware_json" field.
+func MiddlewareJSONIn(vs ...string) predicate.Provider {
+	return predicate.Provider(sql.FieldIn(FieldMiddlewareJSON, vs...))
+}
+
+// MiddlewareJSONNotIn applies the NotIn predicate on the "middleware_json" field.
+func MiddlewareJSONNotIn(vs ...string) predicate.Provider {
+	return predicate.Provider(sql.FieldNotIn(FieldMiddlewareJSON, vs...))
+}
+
+// MiddlewareJSONGT applies the GT predicate on the "middleware_json" field.
+func MiddlewareJSONGT(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldGT(FieldMiddlewareJSON, v))
+}
+
+// MiddlewareJSONGTE applies the GTE predicate on the "middleware_json" field.
+func MiddlewareJSONGTE(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldGTE(FieldMiddlewareJSON, v))
+}
+
+// MiddlewareJSONLT applies the LT predicate on the "middleware_json" field.
+func MiddlewareJSONLT(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldLT(FieldMiddlewareJSON, v))
+}
+
+// MiddlewareJSONLTE applies the LTE predicate on the "middleware_json" field.
+func MiddlewareJSONLTE(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldLTE(FieldMiddlewareJSON, v))
+}
+
+// MiddlewareJSONContains applies the Contains predicate on the "middleware_json" field.
+func MiddlewareJSONContains(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldContains(FieldMiddlewareJSON, v))
+}
+
+// MiddlewareJSONHasPrefix applies the HasPrefix predicate on the "middleware_json" field.
+func MiddlewareJSONHasPrefix(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldHasPrefix(FieldMiddlewareJSON, v))
+}
+
+// MiddlewareJSONHasSuffix applies the HasSuffix predicate on the "middleware_json" field.
+func MiddlewareJSONHasSuffix(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldHasSuffix(FieldMiddlewareJSON, v))
+}
+
+// MiddlewareJSONEqualFold applies the EqualFold predicate on the "middleware_json" field.
+func MiddlewareJSONEqualFold(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEqualFold(FieldMiddlewareJSON, v))
+}
+
+// MiddlewareJSONContainsFold applies the ContainsFold predicate on the "middleware_json" field.
+func MiddlewareJSONContainsFold(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldContainsFold(FieldMiddlewareJSON, v))
+}
+
+// CapabilitiesJSONEQ applies the EQ predicate on the "capabilities_json" field.
+func CapabilitiesJSONEQ(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldCapabilitiesJSON, v))
+}
+
+// CapabilitiesJSONNEQ applies the NEQ predicate on the "capabilities_json" field.
+func CapabilitiesJSONNEQ(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldNEQ(FieldCapabilitiesJSON, v))
+}
+
+// CapabilitiesJSONIn applies the In predicate on the "capabilities_json" field.
+func CapabilitiesJSONIn(vs ...string) predicate.Provider {
+	return predicate.Provider(sql.FieldIn(FieldCapabilitiesJSON, vs...))
+}
+
+// CapabilitiesJSONNotIn applies the NotIn predicate on the "capabilities_json" field.
+func CapabilitiesJSONNotIn(vs ...string) predicate.Provider {
+	return predicate.Provider(sql.FieldNotIn(FieldCapabilitiesJSON, vs...))
+}
+
+// CapabilitiesJSONGT applies the GT predicate on the "capabilities_json" field.
+func CapabilitiesJSONGT(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldGT(FieldCapabilitiesJSON, v))
+}
+
+// CapabilitiesJSONGTE applies the GTE predicate on the "capabilities_json" field.
+func CapabilitiesJSONGTE(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldGTE(FieldCapabilitiesJSON, v))
+}
+
+// CapabilitiesJSONLT applies the LT predicate on the "capabilities_json" field.
+func CapabilitiesJSONLT(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldLT(FieldCapabilitiesJSON, v))
+}
+
+// CapabilitiesJSONLTE applies the LTE predicate on the "capabilities_json" field.
+func CapabilitiesJSONLTE(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldLTE(FieldCapabilitiesJSON, v))
+}
+
+// CapabilitiesJSONContains applies the Contains predicate on the "capabilities_json" field.
+func CapabilitiesJSONContains(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldContains(FieldCapabilitiesJSON, v))
+}
+
+// CapabilitiesJSONHasPrefix applies the HasPrefix predicate on the "capabilities_json" field.
+func CapabilitiesJSONHasPrefix(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldHasPrefix(FieldCapabilitiesJSON, v))
+}
+
+// CapabilitiesJSONHasSuffix applies the HasSuffix predicate on the "capabilities_json" field.
+func CapabilitiesJSONHasSuffix(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldHasSuffix(FieldCapabilitiesJSON, v))
+}
+
+// CapabilitiesJSONEqualFold applies the EqualFold predicate on the "capabilities_json" field.
+func CapabilitiesJSONEqualFold(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEqualFold(FieldCapabilitiesJSON, v))
+}
+
+// CapabilitiesJSONContainsFold applies the ContainsFold predicate on the "capabilities_json" field.
+func CapabilitiesJSONContainsFold(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldContainsFold(FieldCapabilitiesJSON, v))
+}
+
+// TenantIDEQ applies the EQ predicate on the "tenant_id" field.
+func TenantIDEQ(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEQ(FieldTenantID, v))
+}
+
+// TenantIDNEQ applies the NEQ predicate on the "tenant_id" field.
+func TenantIDNEQ(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldNEQ(FieldTenantID, v))
+}
+
+// TenantIDIn applies the In predicate on the "tenant_id" field.
+func TenantIDIn(vs ...string) predicate.Provider {
+	return predicate.Provider(sql.FieldIn(FieldTenantID, vs...))
+}
+
+// TenantIDNotIn applies the NotIn predicate on the "tenant_id" field.
+func TenantIDNotIn(vs ...string) predicate.Provider {
+	return predicate.Provider(sql.FieldNotIn(FieldTenantID, vs...))
+}
+
+// TenantIDGT applies the GT predicate on the "tenant_id" field.
+func TenantIDGT(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldGT(FieldTenantID, v))
+}
+
+// TenantIDGTE applies the GTE predicate on the "tenant_id" field.
+func TenantIDGTE(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldGTE(FieldTenantID, v))
+}
+
+// TenantIDLT applies the LT predicate on the "tenant_id" field.
+func TenantIDLT(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldLT(FieldTenantID, v))
+}
+
+// TenantIDLTE applies the LTE predicate on the "tenant_id" field.
+func TenantIDLTE(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldLTE(FieldTenantID, v))
+}
+
+// TenantIDContains applies the Contains predicate on the "tenant_id" field.
+func TenantIDContains(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldContains(FieldTenantID, v))
+}
+
+// TenantIDHasPrefix applies the HasPrefix predicate on the "tenant_id" field.
+func TenantIDHasPrefix(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldHasPrefix(FieldTenantID, v))
+}
+
+// TenantIDHasSuffix applies the HasSuffix predicate on the "tenant_id" field.
+func TenantIDHasSuffix(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldHasSuffix(FieldTenantID, v))
+}
+
+// TenantIDEqualFold applies the EqualFold predicate on the "tenant_id" field.
+func TenantIDEqualFold(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldEqualFold(FieldTenantID, v))
+}
+
+// TenantIDContainsFold applies the ContainsFold predicate on the "tenant_id" field.
+func TenantIDContainsFold(v string) predicate.Provider {
+	return predicate.Provider(sql.FieldContainsFold(FieldTenantID, v))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.Provider {
 	return predicate.Provider(sql.FieldEQ(FieldCreatedAt, v))
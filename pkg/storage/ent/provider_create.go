@@ -144,6 +144,104 @@ func (_c *ProviderCreate) SetNillableTimeout(v *int) *ProviderCreate {
 	return _c
 }
 
+// SetStream sets the "stream" field.
+func (_c *ProviderCreate) SetStream(v bool) *ProviderCreate {
+	_c.mutation.SetStream(v)
+	return _c
+}
+
+// SetNillableStream sets the "stream" field if the given value is not nil.
+func (_c *ProviderCreate) SetNillableStream(v *bool) *ProviderCreate {
+	if v != nil {
+		_c.SetStream(*v)
+	}
+	return _c
+}
+
+// SetSystemPrefix sets the "system_prefix" field.
+func (_c *ProviderCreate) SetSystemPrefix(v string) *ProviderCreate {
+	_c.mutation.SetSystemPrefix(v)
+	return _c
+}
+
+// SetNillableSystemPrefix sets the "system_prefix" field if the given value is not nil.
+func (_c *ProviderCreate) SetNillableSystemPrefix(v *string) *ProviderCreate {
+	if v != nil {
+		_c.SetSystemPrefix(*v)
+	}
+	return _c
+}
+
+// SetAPIVersion sets the "api_version" field.
+func (_c *ProviderCreate) SetAPIVersion(v string) *ProviderCreate {
+	_c.mutation.SetAPIVersion(v)
+	return _c
+}
+
+// SetNillableAPIVersion sets the "api_version" field if the given value is not nil.
+func (_c *ProviderCreate) SetNillableAPIVersion(v *string) *ProviderCreate {
+	if v != nil {
+		_c.SetAPIVersion(*v)
+	}
+	return _c
+}
+
+// SetDeploymentsJSON sets the "deployments_json" field.
+func (_c *ProviderCreate) SetDeploymentsJSON(v string) *ProviderCreate {
+	_c.mutation.SetDeploymentsJSON(v)
+	return _c
+}
+
+// SetNillableDeploymentsJSON sets the "deployments_json" field if the given value is not nil.
+func (_c *ProviderCreate) SetNillableDeploymentsJSON(v *string) *ProviderCreate {
+	if v != nil {
+		_c.SetDeploymentsJSON(*v)
+	}
+	return _c
+}
+
+// SetMiddlewareJSON sets the "middleware_json" field.
+func (_c *ProviderCreate) SetMiddlewareJSON(v string) *ProviderCreate {
+	_c.mutation.SetMiddlewareJSON(v)
+	return _c
+}
+
+// SetNillableMiddlewareJSON sets the "middleware_json" field if the given value is not nil.
+func (_c *ProviderCreate) SetNillableMiddlewareJSON(v *string) *ProviderCreate {
+	if v != nil {
+		_c.SetMiddlewareJSON(*v)
+	}
+	return _c
+}
+
+// SetCapabilitiesJSON sets the "capabilities_json" field.
+func (_c *ProviderCreate) SetCapabilitiesJSON(v string) *ProviderCreate {
+	_c.mutation.SetCapabilitiesJSON(v)
+	return _c
+}
+
+// SetNillableCapabilitiesJSON sets the "capabilities_json" field if the given value is not nil.
+func (_c *ProviderCreate) SetNillableCapabilitiesJSON(v *string) *ProviderCreate {
+	if v != nil {
+		_c.SetCapabilitiesJSON(*v)
+	}
+	return _c
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (_c *ProviderCreate) SetTenantID(v string) *ProviderCreate {
+	_c.mutation.SetTenantID(v)
+	return _c
+}
+
+// SetNillableTenantID sets the "tenant_id" field if the given value is not nil.
+func (_c *ProviderCreate) SetNillableTenantID(v *string) *ProviderCreate {
+	if v != nil {
+		_c.SetTenantID(*v)
+	}
+	return _c
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (_c *ProviderCreate) SetCreatedAt(v time.Time) *ProviderCreate {
 	_c.mutation.SetCreatedAt(v)
@@ -253,6 +351,34 @@ func (_c *ProviderCreate) defaults() {
 		v := provider.DefaultTimeout
 		_c.mutation.SetTimeout(v)
 	}
+	if _, ok := _c.mutation.Stream(); !ok {
+		v := provider.DefaultStream
+		_c.mutation.SetStream(v)
+	}
+	if _, ok := _c.mutation.SystemPrefix(); !ok {
+		v := provider.DefaultSystemPrefix
+		_c.mutation.SetSystemPrefix(v)
+	}
+	if _, ok := _c.mutation.APIVersion(); !ok {
+		v := provider.DefaultAPIVersion
+		_c.mutation.SetAPIVersion(v)
+	}
+	if _, ok := _c.mutation.DeploymentsJSON(); !ok {
+		v := provider.DefaultDeploymentsJSON
+		_c.mutation.SetDeploymentsJSON(v)
+	}
+	if _, ok := _c.mutation.MiddlewareJSON(); !ok {
+		v := provider.DefaultMiddlewareJSON
+		_c.mutation.SetMiddlewareJSON(v)
+	}
+	if _, ok := _c.mutation.CapabilitiesJSON(); !ok {
+		v := provider.DefaultCapabilitiesJSON
+		_c.mutation.SetCapabilitiesJSON(v)
+	}
+	if _, ok := _c.mutation.TenantID(); !ok {
+		v := provider.DefaultTenantID
+		_c.mutation.SetTenantID(v)
+	}
 	if _, ok := _c.mutation.CreatedAt(); !ok {
 		v := provider.DefaultCreatedAt()
 		_c.mutation.SetCreatedAt(v)
@@ -309,6 +435,27 @@ func (_c *ProviderCreate) check() error {
 	if _, ok := _c.mutation.Timeout(); !ok {
 		return &ValidationError{Name: "timeout", err: errors.New(`ent: missing required field "Provider.timeout"`)}
 	}
+	if _, ok := _c.mutation.Stream(); !ok {
+		return &ValidationError{Name: "stream", err: errors.New(`ent: missing required field "Provider.stream"`)}
+	}
+	if _, ok := _c.mutation.SystemPrefix(); !ok {
+		return &ValidationError{Name: "system_prefix", err: errors.New(`ent: missing required field "Provider.system_prefix"`)}
+	}
+	if _, ok := _c.mutation.APIVersion(); !ok {
+		return &ValidationError{Name: "api_version", err: errors.New(`ent: missing required field "Provider.api_version"`)}
+	}
+	if _, ok := _c.mutation.DeploymentsJSON(); !ok {
+		return &ValidationError{Name: "deployments_json", err: errors.New(`ent: missing required field "Provider.deployments_json"`)}
+	}
+	if _, ok := _c.mutation.MiddlewareJSON(); !ok {
+		return &ValidationError{Name: "middleware_json", err: errors.New(`ent: missing required field "Provider.middleware_json"`)}
+	}
+	if _, ok := _c.mutation.CapabilitiesJSON(); !ok {
+		return &ValidationError{Name: "capabilities_json", err: errors.New(`ent: missing required field "Provider.capabilities_json"`)}
+	}
+	if _, ok := _c.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant_id", err: errors.New(`ent: missing required field "Provider.tenant_id"`)}
+	}
 	if _, ok := _c.mutation.CreatedAt(); !ok {
 		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Provider.created_at"`)}
 	}
@@ -390,6 +537,34 @@ func (_c *ProviderCreate) createSpec() (*Provider, *sqlgraph.CreateSpec) {
 		_spec.SetField(provider.FieldTimeout, field.TypeInt, value)
 		_node.Timeout = value
 	}
+	if value, ok := _c.mutation.Stream(); ok {
+		_spec.SetField(provider.FieldStream, field.TypeBool, value)
+		_node.Stream = value
+	}
+	if value, ok := _c.mutation.SystemPrefix(); ok {
+		_spec.SetField(provider.FieldSystemPrefix, field.TypeString, value)
+		_node.SystemPrefix = value
+	}
+	if value, ok := _c.mutation.APIVersion(); ok {
+		_spec.SetField(provider.FieldAPIVersion, field.TypeString, value)
+		_node.APIVersion = value
+	}
+	if value, ok := _c.mutation.DeploymentsJSON(); ok {
+		_spec.SetField(provider.FieldDeploymentsJSON, field.TypeString, value)
+		_node.DeploymentsJSON = value
+	}
+	if value, ok := _c.mutation.MiddlewareJSON(); ok {
+		_spec.SetField(provider.FieldMiddlewareJSON, field.TypeString, value)
+		_node.MiddlewareJSON = value
+	}
+	if value, ok := _c.mutation.CapabilitiesJSON(); ok {
+		_spec.SetField(provider.FieldCapabilitiesJSON, field.TypeString, value)
+		_node.CapabilitiesJSON = value
+	}
+	if value, ok := _c.mutation.TenantID(); ok {
+		_spec.SetField(provider.FieldTenantID, field.TypeString, value)
+		_node.TenantID = value
+	}
 	if value, ok := _c.mutation.CreatedAt(); ok {
 		_spec.SetField(provider.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
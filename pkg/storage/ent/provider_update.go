@@ -182,6 +182,104 @@ func (_u *ProviderUpdate) AddTimeout(v int) *ProviderUpdate {
 	return _u
 }
 
+// SetStream sets the "stream" field.
+func (_u *ProviderUpdate) SetStream(v bool) *ProviderUpdate {
+	_u.mutation.SetStream(v)
+	return _u
+}
+
+// SetNillableStream sets the "stream" field if the given value is not nil.
+func (_u *ProviderUpdate) SetNillableStream(v *bool) *ProviderUpdate {
+	if v != nil {
+		_u.SetStream(*v)
+	}
+	return _u
+}
+
+// SetSystemPrefix sets the "system_prefix" field.
+func (_u *ProviderUpdate) SetSystemPrefix(v string) *ProviderUpdate {
+	_u.mutation.SetSystemPrefix(v)
+	return _u
+}
+
+// SetNillableSystemPrefix sets the "system_prefix" field if the given value is not nil.
+func (_u *ProviderUpdate) SetNillableSystemPrefix(v *string) *ProviderUpdate {
+	if v != nil {
+		_u.SetSystemPrefix(*v)
+	}
+	return _u
+}
+
+// SetAPIVersion sets the "api_version" field.
+func (_u *ProviderUpdate) SetAPIVersion(v string) *ProviderUpdate {
+	_u.mutation.SetAPIVersion(v)
+	return _u
+}
+
+// SetNillableAPIVersion sets the "api_version" field if the given value is not nil.
+func (_u *ProviderUpdate) SetNillableAPIVersion(v *string) *ProviderUpdate {
+	if v != nil {
+		_u.SetAPIVersion(*v)
+	}
+	return _u
+}
+
+// SetDeploymentsJSON sets the "deployments_json" field.
+func (_u *ProviderUpdate) SetDeploymentsJSON(v string) *ProviderUpdate {
+	_u.mutation.SetDeploymentsJSON(v)
+	return _u
+}
+
+// SetNillableDeploymentsJSON sets the "deployments_json" field if the given value is not nil.
+func (_u *ProviderUpdate) SetNillableDeploymentsJSON(v *string) *ProviderUpdate {
+	if v != nil {
+		_u.SetDeploymentsJSON(*v)
+	}
+	return _u
+}
+
+// SetMiddlewareJSON sets the "middleware_json" field.
+func (_u *ProviderUpdate) SetMiddlewareJSON(v string) *ProviderUpdate {
+	_u.mutation.SetMiddlewareJSON(v)
+	return _u
+}
+
+// SetNillableMiddlewareJSON sets the "middleware_json" field if the given value is not nil.
+func (_u *ProviderUpdate) SetNillableMiddlewareJSON(v *string) *ProviderUpdate {
+	if v != nil {
+		_u.SetMiddlewareJSON(*v)
+	}
+	return _u
+}
+
+// SetCapabilitiesJSON sets the "capabilities_json" field.
+func (_u *ProviderUpdate) SetCapabilitiesJSON(v string) *ProviderUpdate {
+	_u.mutation.SetCapabilitiesJSON(v)
+	return _u
+}
+
+// SetNillableCapabilitiesJSON sets the "capabilities_json" field if the given value is not nil.
+func (_u *ProviderUpdate) SetNillableCapabilitiesJSON(v *string) *ProviderUpdate {
+	if v != nil {
+		_u.SetCapabilitiesJSON(*v)
+	}
+	return _u
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (_u *ProviderUpdate) SetTenantID(v string) *ProviderUpdate {
+	_u.mutation.SetTenantID(v)
+	return _u
+}
+
+// SetNillableTenantID sets the "tenant_id" field if the given value is not nil.
+func (_u *ProviderUpdate) SetNillableTenantID(v *string) *ProviderUpdate {
+	if v != nil {
+		_u.SetTenantID(*v)
+	}
+	return _u
+}
+
 // SetUpdatedAt sets the "updated_at" field.
 func (_u *ProviderUpdate) SetUpdatedAt(v time.Time) *ProviderUpdate {
 	_u.mutation.SetUpdatedAt(v)
@@ -292,6 +390,27 @@ func (_u *ProviderUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if value, ok := _u.mutation.AddedTimeout(); ok {
 		_spec.AddField(provider.FieldTimeout, field.TypeInt, value)
 	}
+	if value, ok := _u.mutation.Stream(); ok {
+		_spec.SetField(provider.FieldStream, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.SystemPrefix(); ok {
+		_spec.SetField(provider.FieldSystemPrefix, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.APIVersion(); ok {
+		_spec.SetField(provider.FieldAPIVersion, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.DeploymentsJSON(); ok {
+		_spec.SetField(provider.FieldDeploymentsJSON, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.MiddlewareJSON(); ok {
+		_spec.SetField(provider.FieldMiddlewareJSON, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.CapabilitiesJSON(); ok {
+		_spec.SetField(provider.FieldCapabilitiesJSON, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.TenantID(); ok {
+		_spec.SetField(provider.FieldTenantID, field.TypeString, value)
+	}
 	if value, ok := _u.mutation.UpdatedAt(); ok {
 		_spec.SetField(provider.FieldUpdatedAt, field.TypeTime, value)
 	}
@@ -469,6 +588,104 @@ func (_u *ProviderUpdateOne) AddTimeout(v int) *ProviderUpdateOne {
 	return _u
 }
 
+// SetStream sets the "stream" field.
+func (_u *ProviderUpdateOne) SetStream(v bool) *ProviderUpdateOne {
+	_u.mutation.SetStream(v)
+	return _u
+}
+
+// SetNillableStream sets the "stream" field if the given value is not nil.
+func (_u *ProviderUpdateOne) SetNillableStream(v *bool) *ProviderUpdateOne {
+	if v != nil {
+		_u.SetStream(*v)
+	}
+	return _u
+}
+
+// SetSystemPrefix sets the "system_prefix" field.
+func (_u *ProviderUpdateOne) SetSystemPrefix(v string) *ProviderUpdateOne {
+	_u.mutation.SetSystemPrefix(v)
+	return _u
+}
+
+// SetNillableSystemPrefix sets the "system_prefix" field if the given value is not nil.
+func (_u *ProviderUpdateOne) SetNillableSystemPrefix(v *string) *ProviderUpdateOne {
+	if v != nil {
+		_u.SetSystemPrefix(*v)
+	}
+	return _u
+}
+
+// SetAPIVersion sets the "api_version" field.
+func (_u *ProviderUpdateOne) SetAPIVersion(v string) *ProviderUpdateOne {
+	_u.mutation.SetAPIVersion(v)
+	return _u
+}
+
+// SetNillableAPIVersion sets the "api_version" field if the given value is not nil.
+func (_u *ProviderUpdateOne) SetNillableAPIVersion(v *string) *ProviderUpdateOne {
+	if v != nil {
+		_u.SetAPIVersion(*v)
+	}
+	return _u
+}
+
+// SetDeploymentsJSON sets the "deployments_json" field.
+func (_u *ProviderUpdateOne) SetDeploymentsJSON(v string) *ProviderUpdateOne {
+	_u.mutation.SetDeploymentsJSON(v)
+	return _u
+}
+
+// SetNillableDeploymentsJSON sets the "deployments_json" field if the given value is not nil.
+func (_u *ProviderUpdateOne) SetNillableDeploymentsJSON(v *string) *ProviderUpdateOne {
+	if v != nil {
+		_u.SetDeploymentsJSON(*v)
+	}
+	return _u
+}
+
+// SetMiddlewareJSON sets the "middleware_json" field.
+func (_u *ProviderUpdateOne) SetMiddlewareJSON(v string) *ProviderUpdateOne {
+	_u.mutation.SetMiddlewareJSON(v)
+	return _u
+}
+
+// SetNillableMiddlewareJSON sets the "middleware_json" field if the given value is not nil.
+func (_u *ProviderUpdateOne) SetNillableMiddlewareJSON(v *string) *ProviderUpdateOne {
+	if v != nil {
+		_u.SetMiddlewareJSON(*v)
+	}
+	return _u
+}
+
+// SetCapabilitiesJSON sets the "capabilities_json" field.
+func (_u *ProviderUpdateOne) SetCapabilitiesJSON(v string) *ProviderUpdateOne {
+	_u.mutation.SetCapabilitiesJSON(v)
+	return _u
+}
+
+// SetNillableCapabilitiesJSON sets the "capabilities_json" field if the given value is not nil.
+func (_u *ProviderUpdateOne) SetNillableCapabilitiesJSON(v *string) *ProviderUpdateOne {
+	if v != nil {
+		_u.SetCapabilitiesJSON(*v)
+	}
+	return _u
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (_u *ProviderUpdateOne) SetTenantID(v string) *ProviderUpdateOne {
+	_u.mutation.SetTenantID(v)
+	return _u
+}
+
+// SetNillableTenantID sets the "tenant_id" field if the given value is not nil.
+func (_u *ProviderUpdateOne) SetNillableTenantID(v *string) *ProviderUpdateOne {
+	if v != nil {
+		_u.SetTenantID(*v)
+	}
+	return _u
+}
+
 // SetUpdatedAt sets the "updated_at" field.
 func (_u *ProviderUpdateOne) SetUpdatedAt(v time.Time) *ProviderUpdateOne {
 	_u.mutation.SetUpdatedAt(v)
@@ -609,6 +826,27 @@ func (_u *ProviderUpdateOne) sqlSave(ctx context.Context) (_node *Provider, err
 	if value, ok := _u.mutation.AddedTimeout(); ok {
 		_spec.AddField(provider.FieldTimeout, field.TypeInt, value)
 	}
+	if value, ok := _u.mutation.Stream(); ok {
+		_spec.SetField(provider.FieldStream, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.SystemPrefix(); ok {
+		_spec.SetField(provider.FieldSystemPrefix, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.APIVersion(); ok {
+		_spec.SetField(provider.FieldAPIVersion, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.DeploymentsJSON(); ok {
+		_spec.SetField(provider.FieldDeploymentsJSON, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.MiddlewareJSON(); ok {
+		_spec.SetField(provider.FieldMiddlewareJSON, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.CapabilitiesJSON(); ok {
+		_spec.SetField(provider.FieldCapabilitiesJSON, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.TenantID(); ok {
+		_spec.SetField(provider.FieldTenantID, field.TypeString, value)
+	}
 	if value, ok := _u.mutation.UpdatedAt(); ok {
 		_spec.SetField(provider.FieldUpdatedAt, field.TypeTime, value)
 	}
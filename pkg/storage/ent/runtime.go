@@ -5,17 +5,24 @@ package ent
 import (
 	"nekobot/pkg/storage/ent/accountbinding"
 	"nekobot/pkg/storage/ent/agentruntime"
+	"nekobot/pkg/storage/ent/apikey"
+	"nekobot/pkg/storage/ent/approvalrequest"
 	"nekobot/pkg/storage/ent/attachtoken"
+	"nekobot/pkg/storage/ent/auditlog"
 	"nekobot/pkg/storage/ent/channelaccount"
 	"nekobot/pkg/storage/ent/collaborationevent"
 	"nekobot/pkg/storage/ent/configsection"
 	"nekobot/pkg/storage/ent/cronjob"
+	"nekobot/pkg/storage/ent/feedback"
+	"nekobot/pkg/storage/ent/feedentry"
+	"nekobot/pkg/storage/ent/feedsubscription"
 	"nekobot/pkg/storage/ent/idempotencyrecord"
 	"nekobot/pkg/storage/ent/membership"
 	"nekobot/pkg/storage/ent/modelcatalog"
 	"nekobot/pkg/storage/ent/modelroute"
 	"nekobot/pkg/storage/ent/notificationbinding"
 	"nekobot/pkg/storage/ent/notificationroute"
+	"nekobot/pkg/storage/ent/notifyrule"
 	"nekobot/pkg/storage/ent/permissionrule"
 	"nekobot/pkg/storage/ent/prompt"
 	"nekobot/pkg/storage/ent/promptbinding"
@@ -26,6 +33,7 @@ import (
 	"nekobot/pkg/storage/ent/tenant"
 	"nekobot/pkg/storage/ent/toolevent"
 	"nekobot/pkg/storage/ent/toolsession"
+	"nekobot/pkg/storage/ent/usagerecord"
 	"nekobot/pkg/storage/ent/user"
 	"time"
 )
@@ -34,6 +42,32 @@ import (
 // (default values, validators, hooks and policies) and stitches it
 // to their package variables.
 func init() {
+	apikeyFields := schema.APIKey{}.Fields()
+	_ = apikeyFields
+	// apikeyDescName is the schema descriptor for name field.
+	apikeyDescName := apikeyFields[1].Descriptor()
+	// apikey.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	apikey.NameValidator = apikeyDescName.Validators[0].(func(string) error)
+	// apikeyDescPrefix is the schema descriptor for prefix field.
+	apikeyDescPrefix := apikeyFields[2].Descriptor()
+	// apikey.DefaultPrefix holds the default value on creation for the prefix field.
+	apikey.DefaultPrefix = apikeyDescPrefix.Default.(string)
+	// apikeyDescTenantID is the schema descriptor for tenant_id field.
+	apikeyDescTenantID := apikeyFields[5].Descriptor()
+	// apikey.DefaultTenantID holds the default value on creation for the tenant_id field.
+	apikey.DefaultTenantID = apikeyDescTenantID.Default.(string)
+	// apikeyDescScopesJSON is the schema descriptor for scopes_json field.
+	apikeyDescScopesJSON := apikeyFields[6].Descriptor()
+	// apikey.DefaultScopesJSON holds the default value on creation for the scopes_json field.
+	apikey.DefaultScopesJSON = apikeyDescScopesJSON.Default.(string)
+	// apikeyDescCreatedAt is the schema descriptor for created_at field.
+	apikeyDescCreatedAt := apikeyFields[10].Descriptor()
+	// apikey.DefaultCreatedAt holds the default value on creation for the created_at field.
+	apikey.DefaultCreatedAt = apikeyDescCreatedAt.Default.(func() time.Time)
+	// apikeyDescID is the schema descriptor for id field.
+	apikeyDescID := apikeyFields[0].Descriptor()
+	// apikey.DefaultID holds the default value on creation for the id field.
+	apikey.DefaultID = apikeyDescID.Default.(func() string)
 	accountbindingFields := schema.AccountBinding{}.Fields()
 	_ = accountbindingFields
 	// accountbindingDescChannelAccountID is the schema descriptor for channel_account_id field.
@@ -142,6 +176,32 @@ func init() {
 	agentruntimeDescID := agentruntimeFields[0].Descriptor()
 	// agentruntime.DefaultID holds the default value on creation for the id field.
 	agentruntime.DefaultID = agentruntimeDescID.Default.(func() string)
+	approvalrequestFields := schema.ApprovalRequest{}.Fields()
+	_ = approvalrequestFields
+	// approvalrequestDescToolName is the schema descriptor for tool_name field.
+	approvalrequestDescToolName := approvalrequestFields[1].Descriptor()
+	// approvalrequest.ToolNameValidator is a validator for the "tool_name" field. It is called by the builders before save.
+	approvalrequest.ToolNameValidator = approvalrequestDescToolName.Validators[0].(func(string) error)
+	// approvalrequestDescSessionID is the schema descriptor for session_id field.
+	approvalrequestDescSessionID := approvalrequestFields[3].Descriptor()
+	// approvalrequest.DefaultSessionID holds the default value on creation for the session_id field.
+	approvalrequest.DefaultSessionID = approvalrequestDescSessionID.Default.(string)
+	// approvalrequestDescReason is the schema descriptor for reason field.
+	approvalrequestDescReason := approvalrequestFields[5].Descriptor()
+	// approvalrequest.DefaultReason holds the default value on creation for the reason field.
+	approvalrequest.DefaultReason = approvalrequestDescReason.Default.(string)
+	// approvalrequestDescDecidedBy is the schema descriptor for decided_by field.
+	approvalrequestDescDecidedBy := approvalrequestFields[6].Descriptor()
+	// approvalrequest.DefaultDecidedBy holds the default value on creation for the decided_by field.
+	approvalrequest.DefaultDecidedBy = approvalrequestDescDecidedBy.Default.(string)
+	// approvalrequestDescCreatedAt is the schema descriptor for created_at field.
+	approvalrequestDescCreatedAt := approvalrequestFields[7].Descriptor()
+	// approvalrequest.DefaultCreatedAt holds the default value on creation for the created_at field.
+	approvalrequest.DefaultCreatedAt = approvalrequestDescCreatedAt.Default.(func() time.Time)
+	// approvalrequestDescID is the schema descriptor for id field.
+	approvalrequestDescID := approvalrequestFields[0].Descriptor()
+	// approvalrequest.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	approvalrequest.IDValidator = approvalrequestDescID.Validators[0].(func(string) error)
 	attachtokenFields := schema.AttachToken{}.Fields()
 	_ = attachtokenFields
 	// attachtokenDescOwner is the schema descriptor for owner field.
@@ -156,6 +216,48 @@ func init() {
 	attachtokenDescID := attachtokenFields[0].Descriptor()
 	// attachtoken.DefaultID holds the default value on creation for the id field.
 	attachtoken.DefaultID = attachtokenDescID.Default.(func() string)
+	auditlogFields := schema.AuditLog{}.Fields()
+	_ = auditlogFields
+	// auditlogDescAction is the schema descriptor for action field.
+	auditlogDescAction := auditlogFields[1].Descriptor()
+	// auditlog.ActionValidator is a validator for the "action" field. It is called by the builders before save.
+	auditlog.ActionValidator = auditlogDescAction.Validators[0].(func(string) error)
+	// auditlogDescActor is the schema descriptor for actor field.
+	auditlogDescActor := auditlogFields[2].Descriptor()
+	// auditlog.DefaultActor holds the default value on creation for the actor field.
+	auditlog.DefaultActor = auditlogDescActor.Default.(string)
+	// auditlogDescActorID is the schema descriptor for actor_id field.
+	auditlogDescActorID := auditlogFields[3].Descriptor()
+	// auditlog.DefaultActorID holds the default value on creation for the actor_id field.
+	auditlog.DefaultActorID = auditlogDescActorID.Default.(string)
+	// auditlogDescTenantID is the schema descriptor for tenant_id field.
+	auditlogDescTenantID := auditlogFields[4].Descriptor()
+	// auditlog.DefaultTenantID holds the default value on creation for the tenant_id field.
+	auditlog.DefaultTenantID = auditlogDescTenantID.Default.(string)
+	// auditlogDescIP is the schema descriptor for ip field.
+	auditlogDescIP := auditlogFields[5].Descriptor()
+	// auditlog.DefaultIP holds the default value on creation for the ip field.
+	auditlog.DefaultIP = auditlogDescIP.Default.(string)
+	// auditlogDescTarget is the schema descriptor for target field.
+	auditlogDescTarget := auditlogFields[6].Descriptor()
+	// auditlog.DefaultTarget holds the default value on creation for the target field.
+	auditlog.DefaultTarget = auditlogDescTarget.Default.(string)
+	// auditlogDescSuccess is the schema descriptor for success field.
+	auditlogDescSuccess := auditlogFields[7].Descriptor()
+	// auditlog.DefaultSuccess holds the default value on creation for the success field.
+	auditlog.DefaultSuccess = auditlogDescSuccess.Default.(bool)
+	// auditlogDescSummary is the schema descriptor for summary field.
+	auditlogDescSummary := auditlogFields[8].Descriptor()
+	// auditlog.DefaultSummary holds the default value on creation for the summary field.
+	auditlog.DefaultSummary = auditlogDescSummary.Default.(string)
+	// auditlogDescCreatedAt is the schema descriptor for created_at field.
+	auditlogDescCreatedAt := auditlogFields[9].Descriptor()
+	// auditlog.DefaultCreatedAt holds the default value on creation for the created_at field.
+	auditlog.DefaultCreatedAt = auditlogDescCreatedAt.Default.(func() time.Time)
+	// auditlogDescID is the schema descriptor for id field.
+	auditlogDescID := auditlogFields[0].Descriptor()
+	// auditlog.DefaultID holds the default value on creation for the id field.
+	auditlog.DefaultID = auditlogDescID.Default.(func() string)
 	channelaccountFields := schema.ChannelAccount{}.Fields()
 	_ = channelaccountFields
 	// channelaccountDescChannelType is the schema descriptor for channel_type field.
@@ -392,6 +494,120 @@ func init() {
 	cronjobDescID := cronjobFields[0].Descriptor()
 	// cronjob.DefaultID holds the default value on creation for the id field.
 	cronjob.DefaultID = cronjobDescID.Default.(func() string)
+	feedentryFields := schema.FeedEntry{}.Fields()
+	_ = feedentryFields
+	// feedentryDescSubscriptionID is the schema descriptor for subscription_id field.
+	feedentryDescSubscriptionID := feedentryFields[1].Descriptor()
+	// feedentry.SubscriptionIDValidator is a validator for the "subscription_id" field. It is called by the builders before save.
+	feedentry.SubscriptionIDValidator = feedentryDescSubscriptionID.Validators[0].(func(string) error)
+	// feedentryDescEntryKey is the schema descriptor for entry_key field.
+	feedentryDescEntryKey := feedentryFields[2].Descriptor()
+	// feedentry.EntryKeyValidator is a validator for the "entry_key" field. It is called by the builders before save.
+	feedentry.EntryKeyValidator = feedentryDescEntryKey.Validators[0].(func(string) error)
+	// feedentryDescTitle is the schema descriptor for title field.
+	feedentryDescTitle := feedentryFields[3].Descriptor()
+	// feedentry.DefaultTitle holds the default value on creation for the title field.
+	feedentry.DefaultTitle = feedentryDescTitle.Default.(string)
+	// feedentryDescLink is the schema descriptor for link field.
+	feedentryDescLink := feedentryFields[4].Descriptor()
+	// feedentry.DefaultLink holds the default value on creation for the link field.
+	feedentry.DefaultLink = feedentryDescLink.Default.(string)
+	// feedentryDescCreatedAt is the schema descriptor for created_at field.
+	feedentryDescCreatedAt := feedentryFields[6].Descriptor()
+	// feedentry.DefaultCreatedAt holds the default value on creation for the created_at field.
+	feedentry.DefaultCreatedAt = feedentryDescCreatedAt.Default.(func() time.Time)
+	// feedentryDescID is the schema descriptor for id field.
+	feedentryDescID := feedentryFields[0].Descriptor()
+	// feedentry.DefaultID holds the default value on creation for the id field.
+	feedentry.DefaultID = feedentryDescID.Default.(func() string)
+	feedsubscriptionFields := schema.FeedSubscription{}.Fields()
+	_ = feedsubscriptionFields
+	// feedsubscriptionDescName is the schema descriptor for name field.
+	feedsubscriptionDescName := feedsubscriptionFields[1].Descriptor()
+	// feedsubscription.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	feedsubscription.NameValidator = feedsubscriptionDescName.Validators[0].(func(string) error)
+	// feedsubscriptionDescURL is the schema descriptor for url field.
+	feedsubscriptionDescURL := feedsubscriptionFields[2].Descriptor()
+	// feedsubscription.URLValidator is a validator for the "url" field. It is called by the builders before save.
+	feedsubscription.URLValidator = feedsubscriptionDescURL.Validators[0].(func(string) error)
+	// feedsubscriptionDescIntervalMinutes is the schema descriptor for interval_minutes field.
+	feedsubscriptionDescIntervalMinutes := feedsubscriptionFields[3].Descriptor()
+	// feedsubscription.DefaultIntervalMinutes holds the default value on creation for the interval_minutes field.
+	feedsubscription.DefaultIntervalMinutes = feedsubscriptionDescIntervalMinutes.Default.(int)
+	// feedsubscriptionDescPrompt is the schema descriptor for prompt field.
+	feedsubscriptionDescPrompt := feedsubscriptionFields[4].Descriptor()
+	// feedsubscription.DefaultPrompt holds the default value on creation for the prompt field.
+	feedsubscription.DefaultPrompt = feedsubscriptionDescPrompt.Default.(string)
+	// feedsubscriptionDescChannelID is the schema descriptor for channel_id field.
+	feedsubscriptionDescChannelID := feedsubscriptionFields[5].Descriptor()
+	// feedsubscription.ChannelIDValidator is a validator for the "channel_id" field. It is called by the builders before save.
+	feedsubscription.ChannelIDValidator = feedsubscriptionDescChannelID.Validators[0].(func(string) error)
+	// feedsubscriptionDescSessionID is the schema descriptor for session_id field.
+	feedsubscriptionDescSessionID := feedsubscriptionFields[6].Descriptor()
+	// feedsubscription.SessionIDValidator is a validator for the "session_id" field. It is called by the builders before save.
+	feedsubscription.SessionIDValidator = feedsubscriptionDescSessionID.Validators[0].(func(string) error)
+	// feedsubscriptionDescEnabled is the schema descriptor for enabled field.
+	feedsubscriptionDescEnabled := feedsubscriptionFields[7].Descriptor()
+	// feedsubscription.DefaultEnabled holds the default value on creation for the enabled field.
+	feedsubscription.DefaultEnabled = feedsubscriptionDescEnabled.Default.(bool)
+	// feedsubscriptionDescLastError is the schema descriptor for last_error field.
+	feedsubscriptionDescLastError := feedsubscriptionFields[9].Descriptor()
+	// feedsubscription.DefaultLastError holds the default value on creation for the last_error field.
+	feedsubscription.DefaultLastError = feedsubscriptionDescLastError.Default.(string)
+	// feedsubscriptionDescCreatedAt is the schema descriptor for created_at field.
+	feedsubscriptionDescCreatedAt := feedsubscriptionFields[10].Descriptor()
+	// feedsubscription.DefaultCreatedAt holds the default value on creation for the created_at field.
+	feedsubscription.DefaultCreatedAt = feedsubscriptionDescCreatedAt.Default.(func() time.Time)
+	// feedsubscriptionDescUpdatedAt is the schema descriptor for updated_at field.
+	feedsubscriptionDescUpdatedAt := feedsubscriptionFields[11].Descriptor()
+	// feedsubscription.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	feedsubscription.DefaultUpdatedAt = feedsubscriptionDescUpdatedAt.Default.(func() time.Time)
+	// feedsubscription.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	feedsubscription.UpdateDefaultUpdatedAt = feedsubscriptionDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// feedsubscriptionDescID is the schema descriptor for id field.
+	feedsubscriptionDescID := feedsubscriptionFields[0].Descriptor()
+	// feedsubscription.DefaultID holds the default value on creation for the id field.
+	feedsubscription.DefaultID = feedsubscriptionDescID.Default.(func() string)
+	feedbackFields := schema.Feedback{}.Fields()
+	_ = feedbackFields
+	// feedbackDescChannel is the schema descriptor for channel field.
+	feedbackDescChannel := feedbackFields[1].Descriptor()
+	// feedback.ChannelValidator is a validator for the "channel" field. It is called by the builders before save.
+	feedback.ChannelValidator = feedbackDescChannel.Validators[0].(func(string) error)
+	// feedbackDescSessionID is the schema descriptor for session_id field.
+	feedbackDescSessionID := feedbackFields[2].Descriptor()
+	// feedback.SessionIDValidator is a validator for the "session_id" field. It is called by the builders before save.
+	feedback.SessionIDValidator = feedbackDescSessionID.Validators[0].(func(string) error)
+	// feedbackDescMessageID is the schema descriptor for message_id field.
+	feedbackDescMessageID := feedbackFields[3].Descriptor()
+	// feedback.MessageIDValidator is a validator for the "message_id" field. It is called by the builders before save.
+	feedback.MessageIDValidator = feedbackDescMessageID.Validators[0].(func(string) error)
+	// feedbackDescUserID is the schema descriptor for user_id field.
+	feedbackDescUserID := feedbackFields[4].Descriptor()
+	// feedback.DefaultUserID holds the default value on creation for the user_id field.
+	feedback.DefaultUserID = feedbackDescUserID.Default.(string)
+	// feedbackDescComment is the schema descriptor for comment field.
+	feedbackDescComment := feedbackFields[6].Descriptor()
+	// feedback.DefaultComment holds the default value on creation for the comment field.
+	feedback.DefaultComment = feedbackDescComment.Default.(string)
+	// feedbackDescResponse is the schema descriptor for response field.
+	feedbackDescResponse := feedbackFields[7].Descriptor()
+	// feedback.DefaultResponse holds the default value on creation for the response field.
+	feedback.DefaultResponse = feedbackDescResponse.Default.(string)
+	// feedbackDescCreatedAt is the schema descriptor for created_at field.
+	feedbackDescCreatedAt := feedbackFields[8].Descriptor()
+	// feedback.DefaultCreatedAt holds the default value on creation for the created_at field.
+	feedback.DefaultCreatedAt = feedbackDescCreatedAt.Default.(func() time.Time)
+	// feedbackDescUpdatedAt is the schema descriptor for updated_at field.
+	feedbackDescUpdatedAt := feedbackFields[9].Descriptor()
+	// feedback.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	feedback.DefaultUpdatedAt = feedbackDescUpdatedAt.Default.(func() time.Time)
+	// feedback.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	feedback.UpdateDefaultUpdatedAt = feedbackDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// feedbackDescID is the schema descriptor for id field.
+	feedbackDescID := feedbackFields[0].Descriptor()
+	// feedback.DefaultID holds the default value on creation for the id field.
+	feedback.DefaultID = feedbackDescID.Default.(func() string)
 	idempotencyrecordFields := schema.IdempotencyRecord{}.Fields()
 	_ = idempotencyrecordFields
 	// idempotencyrecordDescTenantID is the schema descriptor for tenant_id field.
@@ -684,6 +900,46 @@ func init() {
 	notificationrouteDescID := notificationrouteFields[0].Descriptor()
 	// notificationroute.DefaultID holds the default value on creation for the id field.
 	notificationroute.DefaultID = notificationrouteDescID.Default.(func() string)
+	notifyruleFields := schema.NotifyRule{}.Fields()
+	_ = notifyruleFields
+	// notifyruleDescName is the schema descriptor for name field.
+	notifyruleDescName := notifyruleFields[1].Descriptor()
+	// notifyrule.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	notifyrule.NameValidator = notifyruleDescName.Validators[0].(func(string) error)
+	// notifyruleDescEventTypesJSON is the schema descriptor for event_types_json field.
+	notifyruleDescEventTypesJSON := notifyruleFields[2].Descriptor()
+	// notifyrule.DefaultEventTypesJSON holds the default value on creation for the event_types_json field.
+	notifyrule.DefaultEventTypesJSON = notifyruleDescEventTypesJSON.Default.(string)
+	// notifyruleDescMatch is the schema descriptor for match field.
+	notifyruleDescMatch := notifyruleFields[3].Descriptor()
+	// notifyrule.DefaultMatch holds the default value on creation for the match field.
+	notifyrule.DefaultMatch = notifyruleDescMatch.Default.(string)
+	// notifyruleDescChannelID is the schema descriptor for channel_id field.
+	notifyruleDescChannelID := notifyruleFields[4].Descriptor()
+	// notifyrule.ChannelIDValidator is a validator for the "channel_id" field. It is called by the builders before save.
+	notifyrule.ChannelIDValidator = notifyruleDescChannelID.Validators[0].(func(string) error)
+	// notifyruleDescSessionID is the schema descriptor for session_id field.
+	notifyruleDescSessionID := notifyruleFields[5].Descriptor()
+	// notifyrule.SessionIDValidator is a validator for the "session_id" field. It is called by the builders before save.
+	notifyrule.SessionIDValidator = notifyruleDescSessionID.Validators[0].(func(string) error)
+	// notifyruleDescEnabled is the schema descriptor for enabled field.
+	notifyruleDescEnabled := notifyruleFields[6].Descriptor()
+	// notifyrule.DefaultEnabled holds the default value on creation for the enabled field.
+	notifyrule.DefaultEnabled = notifyruleDescEnabled.Default.(bool)
+	// notifyruleDescCreatedAt is the schema descriptor for created_at field.
+	notifyruleDescCreatedAt := notifyruleFields[7].Descriptor()
+	// notifyrule.DefaultCreatedAt holds the default value on creation for the created_at field.
+	notifyrule.DefaultCreatedAt = notifyruleDescCreatedAt.Default.(func() time.Time)
+	// notifyruleDescUpdatedAt is the schema descriptor for updated_at field.
+	notifyruleDescUpdatedAt := notifyruleFields[8].Descriptor()
+	// notifyrule.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	notifyrule.DefaultUpdatedAt = notifyruleDescUpdatedAt.Default.(func() time.Time)
+	// notifyrule.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	notifyrule.UpdateDefaultUpdatedAt = notifyruleDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// notifyruleDescID is the schema descriptor for id field.
+	notifyruleDescID := notifyruleFields[0].Descriptor()
+	// notifyrule.DefaultID holds the default value on creation for the id field.
+	notifyrule.DefaultID = notifyruleDescID.Default.(func() string)
 	permissionruleFields := schema.PermissionRule{}.Fields()
 	_ = permissionruleFields
 	// permissionruleDescEnabled is the schema descriptor for enabled field.
@@ -850,12 +1106,40 @@ func init() {
 	providerDescTimeout := providerFields[10].Descriptor()
 	// provider.DefaultTimeout holds the default value on creation for the timeout field.
 	provider.DefaultTimeout = providerDescTimeout.Default.(int)
+	// providerDescStream is the schema descriptor for stream field.
+	providerDescStream := providerFields[11].Descriptor()
+	// provider.DefaultStream holds the default value on creation for the stream field.
+	provider.DefaultStream = providerDescStream.Default.(bool)
+	// providerDescSystemPrefix is the schema descriptor for system_prefix field.
+	providerDescSystemPrefix := providerFields[12].Descriptor()
+	// provider.DefaultSystemPrefix holds the default value on creation for the system_prefix field.
+	provider.DefaultSystemPrefix = providerDescSystemPrefix.Default.(string)
+	// providerDescAPIVersion is the schema descriptor for api_version field.
+	providerDescAPIVersion := providerFields[13].Descriptor()
+	// provider.DefaultAPIVersion holds the default value on creation for the api_version field.
+	provider.DefaultAPIVersion = providerDescAPIVersion.Default.(string)
+	// providerDescDeploymentsJSON is the schema descriptor for deployments_json field.
+	providerDescDeploymentsJSON := providerFields[14].Descriptor()
+	// provider.DefaultDeploymentsJSON holds the default value on creation for the deployments_json field.
+	provider.DefaultDeploymentsJSON = providerDescDeploymentsJSON.Default.(string)
+	// providerDescMiddlewareJSON is the schema descriptor for middleware_json field.
+	providerDescMiddlewareJSON := providerFields[15].Descriptor()
+	// provider.DefaultMiddlewareJSON holds the default value on creation for the middleware_json field.
+	provider.DefaultMiddlewareJSON = providerDescMiddlewareJSON.Default.(string)
+	// providerDescCapabilitiesJSON is the schema descriptor for capabilities_json field.
+	providerDescCapabilitiesJSON := providerFields[16].Descriptor()
+	// provider.DefaultCapabilitiesJSON holds the default value on creation for the capabilities_json field.
+	provider.DefaultCapabilitiesJSON = providerDescCapabilitiesJSON.Default.(string)
+	// providerDescTenantID is the schema descriptor for tenant_id field.
+	providerDescTenantID := providerFields[17].Descriptor()
+	// provider.DefaultTenantID holds the default value on creation for the tenant_id field.
+	provider.DefaultTenantID = providerDescTenantID.Default.(string)
 	// providerDescCreatedAt is the schema descriptor for created_at field.
-	providerDescCreatedAt := providerFields[11].Descriptor()
+	providerDescCreatedAt := providerFields[18].Descriptor()
 	// provider.DefaultCreatedAt holds the default value on creation for the created_at field.
 	provider.DefaultCreatedAt = providerDescCreatedAt.Default.(func() time.Time)
 	// providerDescUpdatedAt is the schema descriptor for updated_at field.
-	providerDescUpdatedAt := providerFields[12].Descriptor()
+	providerDescUpdatedAt := providerFields[19].Descriptor()
 	// provider.DefaultUpdatedAt holds the default value on creation for the updated_at field.
 	provider.DefaultUpdatedAt = providerDescUpdatedAt.Default.(func() time.Time)
 	// provider.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
@@ -1070,24 +1354,28 @@ func init() {
 	toolsessionDescAccessSecretHash := toolsessionFields[11].Descriptor()
 	// toolsession.DefaultAccessSecretHash holds the default value on creation for the access_secret_hash field.
 	toolsession.DefaultAccessSecretHash = toolsessionDescAccessSecretHash.Default.(string)
+	// toolsessionDescSpectatorSecretHash is the schema descriptor for spectator_secret_hash field.
+	toolsessionDescSpectatorSecretHash := toolsessionFields[13].Descriptor()
+	// toolsession.DefaultSpectatorSecretHash holds the default value on creation for the spectator_secret_hash field.
+	toolsession.DefaultSpectatorSecretHash = toolsessionDescSpectatorSecretHash.Default.(string)
 	// toolsessionDescPinned is the schema descriptor for pinned field.
-	toolsessionDescPinned := toolsessionFields[13].Descriptor()
+	toolsessionDescPinned := toolsessionFields[14].Descriptor()
 	// toolsession.DefaultPinned holds the default value on creation for the pinned field.
 	toolsession.DefaultPinned = toolsessionDescPinned.Default.(bool)
 	// toolsessionDescLastActiveAt is the schema descriptor for last_active_at field.
-	toolsessionDescLastActiveAt := toolsessionFields[14].Descriptor()
+	toolsessionDescLastActiveAt := toolsessionFields[15].Descriptor()
 	// toolsession.DefaultLastActiveAt holds the default value on creation for the last_active_at field.
 	toolsession.DefaultLastActiveAt = toolsessionDescLastActiveAt.Default.(func() time.Time)
 	// toolsessionDescMetadataJSON is the schema descriptor for metadata_json field.
-	toolsessionDescMetadataJSON := toolsessionFields[18].Descriptor()
+	toolsessionDescMetadataJSON := toolsessionFields[19].Descriptor()
 	// toolsession.DefaultMetadataJSON holds the default value on creation for the metadata_json field.
 	toolsession.DefaultMetadataJSON = toolsessionDescMetadataJSON.Default.(string)
 	// toolsessionDescCreatedAt is the schema descriptor for created_at field.
-	toolsessionDescCreatedAt := toolsessionFields[19].Descriptor()
+	toolsessionDescCreatedAt := toolsessionFields[20].Descriptor()
 	// toolsession.DefaultCreatedAt holds the default value on creation for the created_at field.
 	toolsession.DefaultCreatedAt = toolsessionDescCreatedAt.Default.(func() time.Time)
 	// toolsessionDescUpdatedAt is the schema descriptor for updated_at field.
-	toolsessionDescUpdatedAt := toolsessionFields[20].Descriptor()
+	toolsessionDescUpdatedAt := toolsessionFields[21].Descriptor()
 	// toolsession.DefaultUpdatedAt holds the default value on creation for the updated_at field.
 	toolsession.DefaultUpdatedAt = toolsessionDescUpdatedAt.Default.(func() time.Time)
 	// toolsession.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
@@ -1096,6 +1384,60 @@ func init() {
 	toolsessionDescID := toolsessionFields[0].Descriptor()
 	// toolsession.DefaultID holds the default value on creation for the id field.
 	toolsession.DefaultID = toolsessionDescID.Default.(func() string)
+	usagerecordFields := schema.UsageRecord{}.Fields()
+	_ = usagerecordFields
+	// usagerecordDescProvider is the schema descriptor for provider field.
+	usagerecordDescProvider := usagerecordFields[1].Descriptor()
+	// usagerecord.DefaultProvider holds the default value on creation for the provider field.
+	usagerecord.DefaultProvider = usagerecordDescProvider.Default.(string)
+	// usagerecordDescModel is the schema descriptor for model field.
+	usagerecordDescModel := usagerecordFields[2].Descriptor()
+	// usagerecord.DefaultModel holds the default value on creation for the model field.
+	usagerecord.DefaultModel = usagerecordDescModel.Default.(string)
+	// usagerecordDescUserID is the schema descriptor for user_id field.
+	usagerecordDescUserID := usagerecordFields[3].Descriptor()
+	// usagerecord.DefaultUserID holds the default value on creation for the user_id field.
+	usagerecord.DefaultUserID = usagerecordDescUserID.Default.(string)
+	// usagerecordDescSessionID is the schema descriptor for session_id field.
+	usagerecordDescSessionID := usagerecordFields[4].Descriptor()
+	// usagerecord.DefaultSessionID holds the default value on creation for the session_id field.
+	usagerecord.DefaultSessionID = usagerecordDescSessionID.Default.(string)
+	// usagerecordDescChannel is the schema descriptor for channel field.
+	usagerecordDescChannel := usagerecordFields[5].Descriptor()
+	// usagerecord.DefaultChannel holds the default value on creation for the channel field.
+	usagerecord.DefaultChannel = usagerecordDescChannel.Default.(string)
+	// usagerecordDescPromptTokens is the schema descriptor for prompt_tokens field.
+	usagerecordDescPromptTokens := usagerecordFields[6].Descriptor()
+	// usagerecord.DefaultPromptTokens holds the default value on creation for the prompt_tokens field.
+	usagerecord.DefaultPromptTokens = usagerecordDescPromptTokens.Default.(int)
+	// usagerecordDescCompletionTokens is the schema descriptor for completion_tokens field.
+	usagerecordDescCompletionTokens := usagerecordFields[7].Descriptor()
+	// usagerecord.DefaultCompletionTokens holds the default value on creation for the completion_tokens field.
+	usagerecord.DefaultCompletionTokens = usagerecordDescCompletionTokens.Default.(int)
+	// usagerecordDescTotalTokens is the schema descriptor for total_tokens field.
+	usagerecordDescTotalTokens := usagerecordFields[8].Descriptor()
+	// usagerecord.DefaultTotalTokens holds the default value on creation for the total_tokens field.
+	usagerecord.DefaultTotalTokens = usagerecordDescTotalTokens.Default.(int)
+	// usagerecordDescCacheReadTokens is the schema descriptor for cache_read_tokens field.
+	usagerecordDescCacheReadTokens := usagerecordFields[9].Descriptor()
+	// usagerecord.DefaultCacheReadTokens holds the default value on creation for the cache_read_tokens field.
+	usagerecord.DefaultCacheReadTokens = usagerecordDescCacheReadTokens.Default.(int)
+	// usagerecordDescCacheWriteTokens is the schema descriptor for cache_write_tokens field.
+	usagerecordDescCacheWriteTokens := usagerecordFields[10].Descriptor()
+	// usagerecord.DefaultCacheWriteTokens holds the default value on creation for the cache_write_tokens field.
+	usagerecord.DefaultCacheWriteTokens = usagerecordDescCacheWriteTokens.Default.(int)
+	// usagerecordDescCost is the schema descriptor for cost field.
+	usagerecordDescCost := usagerecordFields[11].Descriptor()
+	// usagerecord.DefaultCost holds the default value on creation for the cost field.
+	usagerecord.DefaultCost = usagerecordDescCost.Default.(float64)
+	// usagerecordDescCreatedAt is the schema descriptor for created_at field.
+	usagerecordDescCreatedAt := usagerecordFields[12].Descriptor()
+	// usagerecord.DefaultCreatedAt holds the default value on creation for the created_at field.
+	usagerecord.DefaultCreatedAt = usagerecordDescCreatedAt.Default.(func() time.Time)
+	// usagerecordDescID is the schema descriptor for id field.
+	usagerecordDescID := usagerecordFields[0].Descriptor()
+	// usagerecord.DefaultID holds the default value on creation for the id field.
+	usagerecord.DefaultID = usagerecordDescID.Default.(func() string)
 	userFields := schema.User{}.Fields()
 	_ = userFields
 	// userDescUsername is the schema descriptor for username field.
@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// APIKey stores hashed long-lived keys for programmatic API access.
+type APIKey struct {
+	ent.Schema
+}
+
+// Fields of the APIKey.
+func (APIKey) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			DefaultFunc(func() string { return uuid.NewString() }).
+			Immutable(),
+		field.String("name").NotEmpty(),
+		field.String("prefix").Default(""),
+		field.String("key_hash").Unique().Sensitive(),
+		field.String("user_id"),
+		field.String("tenant_id").Default(""),
+		field.String("scopes_json").Default("[]"),
+		field.Time("expires_at").Optional().Nillable(),
+		field.Time("last_used_at").Optional().Nillable(),
+		field.Time("revoked_at").Optional().Nillable(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+// Edges of the APIKey.
+func (APIKey) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the APIKey.
+func (APIKey) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("key_hash").Unique(),
+		index.Fields("user_id"),
+		index.Fields("tenant_id"),
+	}
+}
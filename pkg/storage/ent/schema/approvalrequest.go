@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// ApprovalRequest stores one tool call awaiting or past an approval decision.
+type ApprovalRequest struct {
+	ent.Schema
+}
+
+// Fields of the ApprovalRequest.
+func (ApprovalRequest) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").NotEmpty().Immutable(),
+		field.String("tool_name").NotEmpty(),
+		field.JSON("arguments", map[string]interface{}{}).Optional(),
+		field.String("session_id").Default(""),
+		field.Enum("state").Values("pending", "approved", "denied", "expired").Default("pending"),
+		field.Text("reason").Default(""),
+		field.String("decided_by").Default(""),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("expires_at").Optional().Nillable(),
+		field.Time("decided_at").Optional().Nillable(),
+	}
+}
+
+// Edges of the ApprovalRequest.
+func (ApprovalRequest) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the ApprovalRequest.
+func (ApprovalRequest) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("state"),
+		index.Fields("session_id"),
+		index.Fields("created_at"),
+	}
+}
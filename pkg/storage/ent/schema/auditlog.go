@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// AuditLog records one configuration or security event: who did what, from
+// where, and what changed.
+type AuditLog struct {
+	ent.Schema
+}
+
+// Fields of the AuditLog.
+func (AuditLog) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			DefaultFunc(func() string { return uuid.NewString() }).
+			Immutable(),
+		field.String("action").NotEmpty().Immutable(),
+		field.String("actor").Default("").Immutable(),
+		field.String("actor_id").Default("").Immutable(),
+		field.String("tenant_id").Default("").Immutable(),
+		field.String("ip").Default("").Immutable(),
+		field.String("target").Default("").Immutable(),
+		field.Bool("success").Default(true).Immutable(),
+		field.Text("summary").Default("").Immutable(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+// Edges of the AuditLog.
+func (AuditLog) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the AuditLog.
+func (AuditLog) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("created_at"),
+		index.Fields("action", "created_at"),
+		index.Fields("actor", "created_at"),
+		index.Fields("tenant_id", "created_at"),
+	}
+}
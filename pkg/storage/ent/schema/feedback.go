@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// Feedback stores one user's thumbs up/down rating of an agent reply.
+type Feedback struct {
+	ent.Schema
+}
+
+// Fields of the Feedback.
+func (Feedback) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			DefaultFunc(func() string { return uuid.NewString() }).
+			Immutable(),
+		field.String("channel").NotEmpty(),
+		field.String("session_id").NotEmpty(),
+		field.String("message_id").NotEmpty(),
+		field.String("user_id").Default(""),
+		field.Int("rating"),
+		field.Text("comment").Default(""),
+		field.Text("response").Default(""),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
+	}
+}
+
+// Edges of the Feedback.
+func (Feedback) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the Feedback.
+func (Feedback) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("channel", "session_id", "message_id", "user_id").Unique(),
+		index.Fields("created_at"),
+	}
+}
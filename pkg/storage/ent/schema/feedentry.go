@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// FeedEntry records a feed item already seen so it is summarized only once.
+type FeedEntry struct {
+	ent.Schema
+}
+
+// Fields of the FeedEntry.
+func (FeedEntry) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			DefaultFunc(func() string { return uuid.NewString() }).
+			Immutable(),
+		field.String("subscription_id").NotEmpty(),
+		field.String("entry_key").NotEmpty(),
+		field.String("title").Default(""),
+		field.String("link").Default(""),
+		field.Time("published_at").Optional().Nillable(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+// Edges of the FeedEntry.
+func (FeedEntry) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the FeedEntry.
+func (FeedEntry) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("subscription_id", "entry_key").Unique(),
+		index.Fields("created_at"),
+	}
+}
@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// FeedSubscription stores an RSS/Atom feed the agent summarizes into a channel.
+type FeedSubscription struct {
+	ent.Schema
+}
+
+// Fields of the FeedSubscription.
+func (FeedSubscription) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			DefaultFunc(func() string { return uuid.NewString() }).
+			Immutable(),
+		field.String("name").NotEmpty(),
+		field.String("url").NotEmpty(),
+		field.Int("interval_minutes").Default(60),
+		field.Text("prompt").Default(""),
+		field.String("channel_id").NotEmpty(),
+		field.String("session_id").NotEmpty(),
+		field.Bool("enabled").Default(true),
+		field.Time("last_checked_at").Optional().Nillable(),
+		field.String("last_error").Default(""),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
+	}
+}
+
+// Edges of the FeedSubscription.
+func (FeedSubscription) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the FeedSubscription.
+func (FeedSubscription) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("enabled"),
+		index.Fields("created_at"),
+	}
+}
@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// NotifyRule forwards matching runtime events to a channel session.
+type NotifyRule struct {
+	ent.Schema
+}
+
+// Fields of the NotifyRule.
+func (NotifyRule) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			DefaultFunc(func() string { return uuid.NewString() }).
+			Immutable(),
+		field.String("name").NotEmpty(),
+		field.Text("event_types_json").Default("[]"),
+		field.String("match").Default(""),
+		field.String("channel_id").NotEmpty(),
+		field.String("session_id").NotEmpty(),
+		field.Bool("enabled").Default(true),
+		field.Time("created_at").Default(time.Now).Immutable(),
+		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
+	}
+}
+
+// Edges of the NotifyRule.
+func (NotifyRule) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the NotifyRule.
+func (NotifyRule) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("enabled"),
+		index.Fields("created_at"),
+	}
+}
@@ -30,6 +30,13 @@ func (Provider) Fields() []ent.Field {
 		field.String("default_test_model").Default(""),
 		field.String("api_format").Default("openai/chat_completions"),
 		field.Int("timeout").Default(60),
+		field.Bool("stream").Default(true),
+		field.Text("system_prefix").Default(""),
+		field.String("api_version").Default(""),
+		field.Text("deployments_json").Default(""),
+		field.Text("middleware_json").Default(""),
+		field.Text("capabilities_json").Default(""),
+		field.String("tenant_id").Default(""),
 		field.Time("created_at").Default(time.Now).Immutable(),
 		field.Time("updated_at").Default(time.Now).UpdateDefault(time.Now),
 	}
@@ -45,5 +52,6 @@ func (Provider) Indexes() []ent.Index {
 	return []ent.Index{
 		index.Fields("name").Unique(),
 		index.Fields("provider_kind"),
+		index.Fields("tenant_id"),
 	}
 }
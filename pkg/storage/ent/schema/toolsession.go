@@ -33,6 +33,7 @@ func (ToolSession) Fields() []ent.Field {
 		field.String("access_mode").Default("none"),
 		field.String("access_secret_hash").Optional().Default(""),
 		field.Time("access_once_used_at").Optional().Nillable(),
+		field.String("spectator_secret_hash").Optional().Default(""),
 		field.Bool("pinned").Default(false),
 		field.Time("last_active_at").Default(time.Now),
 		field.Time("detached_at").Optional().Nillable(),
@@ -0,0 +1,50 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// UsageRecord stores token usage and cost for one provider call.
+type UsageRecord struct {
+	ent.Schema
+}
+
+// Fields of the UsageRecord.
+func (UsageRecord) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			DefaultFunc(func() string { return uuid.NewString() }).
+			Immutable(),
+		field.String("provider").Default(""),
+		field.String("model").Default(""),
+		field.String("user_id").Default(""),
+		field.String("session_id").Default(""),
+		field.String("channel").Default(""),
+		field.Int("prompt_tokens").Default(0),
+		field.Int("completion_tokens").Default(0),
+		field.Int("total_tokens").Default(0),
+		field.Int("cache_read_tokens").Default(0),
+		field.Int("cache_write_tokens").Default(0),
+		field.Float("cost").Default(0),
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+// Edges of the UsageRecord.
+func (UsageRecord) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the UsageRecord.
+func (UsageRecord) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("created_at"),
+		index.Fields("provider", "created_at"),
+		index.Fields("user_id", "created_at"),
+	}
+}
@@ -41,6 +41,8 @@ type ToolSession struct {
 	AccessSecretHash string `json:"access_secret_hash,omitempty"`
 	// AccessOnceUsedAt holds the value of the "access_once_used_at" field.
 	AccessOnceUsedAt *time.Time `json:"access_once_used_at,omitempty"`
+	// SpectatorSecretHash holds the value of the "spectator_secret_hash" field.
+	SpectatorSecretHash string `json:"spectator_secret_hash,omitempty"`
 	// Pinned holds the value of the "pinned" field.
 	Pinned bool `json:"pinned,omitempty"`
 	// LastActiveAt holds the value of the "last_active_at" field.
@@ -67,7 +69,7 @@ func (*ToolSession) scanValues(columns []string) ([]any, error) {
 		switch columns[i] {
 		case toolsession.FieldPinned:
 			values[i] = new(sql.NullBool)
-		case toolsession.FieldID, toolsession.FieldOwner, toolsession.FieldSource, toolsession.FieldChannel, toolsession.FieldConversationKey, toolsession.FieldTool, toolsession.FieldTitle, toolsession.FieldCommand, toolsession.FieldWorkdir, toolsession.FieldState, toolsession.FieldAccessMode, toolsession.FieldAccessSecretHash, toolsession.FieldMetadataJSON:
+		case toolsession.FieldID, toolsession.FieldOwner, toolsession.FieldSource, toolsession.FieldChannel, toolsession.FieldConversationKey, toolsession.FieldTool, toolsession.FieldTitle, toolsession.FieldCommand, toolsession.FieldWorkdir, toolsession.FieldState, toolsession.FieldAccessMode, toolsession.FieldAccessSecretHash, toolsession.FieldSpectatorSecretHash, toolsession.FieldMetadataJSON:
 			values[i] = new(sql.NullString)
 		case toolsession.FieldAccessOnceUsedAt, toolsession.FieldLastActiveAt, toolsession.FieldDetachedAt, toolsession.FieldTerminatedAt, toolsession.FieldExpiresAt, toolsession.FieldCreatedAt, toolsession.FieldUpdatedAt:
 			values[i] = new(sql.NullTime)
@@ -165,6 +167,12 @@ func (_m *ToolSession) assignValues(columns []string, values []any) error {
 				_m.AccessOnceUsedAt = new(time.Time)
 				*_m.AccessOnceUsedAt = value.Time
 			}
+		case toolsession.FieldSpectatorSecretHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field spectator_secret_hash", values[i])
+			} else if value.Valid {
+				_m.SpectatorSecretHash = value.String
+			}
 		case toolsession.FieldPinned:
 			if value, ok := values[i].(*sql.NullBool); !ok {
 				return fmt.Errorf("unexpected type %T for field pinned", values[i])
@@ -290,6 +298,9 @@ func (_m *ToolSession) String() string {
 		builder.WriteString(v.Format(time.ANSIC))
 	}
 	builder.WriteString(", ")
+	builder.WriteString("spectator_secret_hash=")
+	builder.WriteString(_m.SpectatorSecretHash)
+	builder.WriteString(", ")
 	builder.WriteString("pinned=")
 	builder.WriteString(fmt.Sprintf("%v", _m.Pinned))
 	builder.WriteString(", ")
@@ -37,6 +37,8 @@ const (
 	FieldAccessSecretHash = "access_secret_hash"
 	// FieldAccessOnceUsedAt holds the string denoting the access_once_used_at field in the database.
 	FieldAccessOnceUsedAt = "access_once_used_at"
+	// FieldSpectatorSecretHash holds the string denoting the spectator_secret_hash field in the database.
+	FieldSpectatorSecretHash = "spectator_secret_hash"
 	// FieldPinned holds the string denoting the pinned field in the database.
 	FieldPinned = "pinned"
 	// FieldLastActiveAt holds the string denoting the last_active_at field in the database.
@@ -72,6 +74,7 @@ var Columns = []string{
 	FieldAccessMode,
 	FieldAccessSecretHash,
 	FieldAccessOnceUsedAt,
+	FieldSpectatorSecretHash,
 	FieldPinned,
 	FieldLastActiveAt,
 	FieldDetachedAt,
@@ -115,6 +118,8 @@ var (
 	DefaultAccessMode string
 	// DefaultAccessSecretHash holds the default value on creation for the "access_secret_hash" field.
 	DefaultAccessSecretHash string
+	// DefaultSpectatorSecretHash holds the default value on creation for the "spectator_secret_hash" field.
+	DefaultSpectatorSecretHash string
 	// DefaultPinned holds the default value on creation for the "pinned" field.
 	DefaultPinned bool
 	// DefaultLastActiveAt holds the default value on creation for the "last_active_at" field.
@@ -199,6 +204,11 @@ func ByAccessOnceUsedAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldAccessOnceUsedAt, opts...).ToFunc()
 }
 
+// BySpectatorSecretHash orders the results by the spectator_secret_hash field.
+func BySpectatorSecretHash(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldSpectatorSecretHash, opts...).ToFunc()
+}
+
 // ByPinned orders the results by the pinned field.
 func ByPinned(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldPinned, opts...).ToFunc()
@@ -124,6 +124,11 @@ func AccessOnceUsedAt(v time.Time) predicate.ToolSession {
 	return predicate.ToolSession(sql.FieldEQ(FieldAccessOnceUsedAt, v))
 }
 
+// SpectatorSecretHash applies equality check predicate on the "spectator_secret_hash" field. It's identical to SpectatorSecretHashEQ.
+func SpectatorSecretHash(v string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldEQ(FieldSpectatorSecretHash, v))
+}
+
 // Pinned applies equality check predicate on the "pinned" field. It's identical to PinnedEQ.
 func Pinned(v bool) predicate.ToolSession {
 	return predicate.ToolSession(sql.FieldEQ(FieldPinned, v))
@@ -989,6 +994,81 @@ func AccessOnceUsedAtNotNil() predicate.ToolSession {
 	return predicate.ToolSession(sql.FieldNotNull(FieldAccessOnceUsedAt))
 }
 
+// SpectatorSecretHashEQ applies the EQ predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashEQ(v string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldEQ(FieldSpectatorSecretHash, v))
+}
+
+// SpectatorSecretHashNEQ applies the NEQ predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashNEQ(v string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldNEQ(FieldSpectatorSecretHash, v))
+}
+
+// SpectatorSecretHashIn applies the In predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashIn(vs ...string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldIn(FieldSpectatorSecretHash, vs...))
+}
+
+// SpectatorSecretHashNotIn applies the NotIn predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashNotIn(vs ...string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldNotIn(FieldSpectatorSecretHash, vs...))
+}
+
+// SpectatorSecretHashGT applies the GT predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashGT(v string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldGT(FieldSpectatorSecretHash, v))
+}
+
+// SpectatorSecretHashGTE applies the GTE predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashGTE(v string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldGTE(FieldSpectatorSecretHash, v))
+}
+
+// SpectatorSecretHashLT applies the LT predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashLT(v string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldLT(FieldSpectatorSecretHash, v))
+}
+
+// SpectatorSecretHashLTE applies the LTE predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashLTE(v string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldLTE(FieldSpectatorSecretHash, v))
+}
+
+// SpectatorSecretHashContains applies the Contains predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashContains(v string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldContains(FieldSpectatorSecretHash, v))
+}
+
+// SpectatorSecretHashHasPrefix applies the HasPrefix predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashHasPrefix(v string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldHasPrefix(FieldSpectatorSecretHash, v))
+}
+
+// SpectatorSecretHashHasSuffix applies the HasSuffix predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashHasSuffix(v string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldHasSuffix(FieldSpectatorSecretHash, v))
+}
+
+// SpectatorSecretHashIsNil applies the IsNil predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashIsNil() predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldIsNull(FieldSpectatorSecretHash))
+}
+
+// SpectatorSecretHashNotNil applies the NotNil predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashNotNil() predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldNotNull(FieldSpectatorSecretHash))
+}
+
+// SpectatorSecretHashEqualFold applies the EqualFold predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashEqualFold(v string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldEqualFold(FieldSpectatorSecretHash, v))
+}
+
+// SpectatorSecretHashContainsFold applies the ContainsFold predicate on the "spectator_secret_hash" field.
+func SpectatorSecretHashContainsFold(v string) predicate.ToolSession {
+	return predicate.ToolSession(sql.FieldContainsFold(FieldSpectatorSecretHash, v))
+}
+
 // PinnedEQ applies the EQ predicate on the "pinned" field.
 func PinnedEQ(v bool) predicate.ToolSession {
 	return predicate.ToolSession(sql.FieldEQ(FieldPinned, v))
@@ -188,6 +188,20 @@ func (_c *ToolSessionCreate) SetNillableAccessOnceUsedAt(v *time.Time) *ToolSess
 	return _c
 }
 
+// SetSpectatorSecretHash sets the "spectator_secret_hash" field.
+func (_c *ToolSessionCreate) SetSpectatorSecretHash(v string) *ToolSessionCreate {
+	_c.mutation.SetSpectatorSecretHash(v)
+	return _c
+}
+
+// SetNillableSpectatorSecretHash sets the "spectator_secret_hash" field if the given value is not nil.
+func (_c *ToolSessionCreate) SetNillableSpectatorSecretHash(v *string) *ToolSessionCreate {
+	if v != nil {
+		_c.SetSpectatorSecretHash(*v)
+	}
+	return _c
+}
+
 // SetPinned sets the "pinned" field.
 func (_c *ToolSessionCreate) SetPinned(v bool) *ToolSessionCreate {
 	_c.mutation.SetPinned(v)
@@ -393,6 +407,10 @@ func (_c *ToolSessionCreate) defaults() {
 		v := toolsession.DefaultAccessSecretHash
 		_c.mutation.SetAccessSecretHash(v)
 	}
+	if _, ok := _c.mutation.SpectatorSecretHash(); !ok {
+		v := toolsession.DefaultSpectatorSecretHash
+		_c.mutation.SetSpectatorSecretHash(v)
+	}
 	if _, ok := _c.mutation.Pinned(); !ok {
 		v := toolsession.DefaultPinned
 		_c.mutation.SetPinned(v)
@@ -531,6 +549,10 @@ func (_c *ToolSessionCreate) createSpec() (*ToolSession, *sqlgraph.CreateSpec) {
 		_spec.SetField(toolsession.FieldAccessOnceUsedAt, field.TypeTime, value)
 		_node.AccessOnceUsedAt = &value
 	}
+	if value, ok := _c.mutation.SpectatorSecretHash(); ok {
+		_spec.SetField(toolsession.FieldSpectatorSecretHash, field.TypeString, value)
+		_node.SpectatorSecretHash = value
+	}
 	if value, ok := _c.mutation.Pinned(); ok {
 		_spec.SetField(toolsession.FieldPinned, field.TypeBool, value)
 		_node.Pinned = value
@@ -238,6 +238,26 @@ func (_u *ToolSessionUpdate) ClearAccessOnceUsedAt() *ToolSessionUpdate {
 	return _u
 }
 
+// SetSpectatorSecretHash sets the "spectator_secret_hash" field.
+func (_u *ToolSessionUpdate) SetSpectatorSecretHash(v string) *ToolSessionUpdate {
+	_u.mutation.SetSpectatorSecretHash(v)
+	return _u
+}
+
+// SetNillableSpectatorSecretHash sets the "spectator_secret_hash" field if the given value is not nil.
+func (_u *ToolSessionUpdate) SetNillableSpectatorSecretHash(v *string) *ToolSessionUpdate {
+	if v != nil {
+		_u.SetSpectatorSecretHash(*v)
+	}
+	return _u
+}
+
+// ClearSpectatorSecretHash clears the value of the "spectator_secret_hash" field.
+func (_u *ToolSessionUpdate) ClearSpectatorSecretHash() *ToolSessionUpdate {
+	_u.mutation.ClearSpectatorSecretHash()
+	return _u
+}
+
 // SetPinned sets the "pinned" field.
 func (_u *ToolSessionUpdate) SetPinned(v bool) *ToolSessionUpdate {
 	_u.mutation.SetPinned(v)
@@ -459,6 +479,12 @@ func (_u *ToolSessionUpdate) sqlSave(ctx context.Context) (_node int, err error)
 	if _u.mutation.AccessOnceUsedAtCleared() {
 		_spec.ClearField(toolsession.FieldAccessOnceUsedAt, field.TypeTime)
 	}
+	if value, ok := _u.mutation.SpectatorSecretHash(); ok {
+		_spec.SetField(toolsession.FieldSpectatorSecretHash, field.TypeString, value)
+	}
+	if _u.mutation.SpectatorSecretHashCleared() {
+		_spec.ClearField(toolsession.FieldSpectatorSecretHash, field.TypeString)
+	}
 	if value, ok := _u.mutation.Pinned(); ok {
 		_spec.SetField(toolsession.FieldPinned, field.TypeBool, value)
 	}
@@ -722,6 +748,26 @@ func (_u *ToolSessionUpdateOne) ClearAccessOnceUsedAt() *ToolSessionUpdateOne {
 	return _u
 }
 
+// SetSpectatorSecretHash sets the "spectator_secret_hash" field.
+func (_u *ToolSessionUpdateOne) SetSpectatorSecretHash(v string) *ToolSessionUpdateOne {
+	_u.mutation.SetSpectatorSecretHash(v)
+	return _u
+}
+
+// SetNillableSpectatorSecretHash sets the "spectator_secret_hash" field if the given value is not nil.
+func (_u *ToolSessionUpdateOne) SetNillableSpectatorSecretHash(v *string) *ToolSessionUpdateOne {
+	if v != nil {
+		_u.SetSpectatorSecretHash(*v)
+	}
+	return _u
+}
+
+// ClearSpectatorSecretHash clears the value of the "spectator_secret_hash" field.
+func (_u *ToolSessionUpdateOne) ClearSpectatorSecretHash() *ToolSessionUpdateOne {
+	_u.mutation.ClearSpectatorSecretHash()
+	return _u
+}
+
 // SetPinned sets the "pinned" field.
 func (_u *ToolSessionUpdateOne) SetPinned(v bool) *ToolSessionUpdateOne {
 	_u.mutation.SetPinned(v)
@@ -973,6 +1019,12 @@ func (_u *ToolSessionUpdateOne) sqlSave(ctx context.Context) (_node *ToolSession
 	if _u.mutation.AccessOnceUsedAtCleared() {
 		_spec.ClearField(toolsession.FieldAccessOnceUsedAt, field.TypeTime)
 	}
+	if value, ok := _u.mutation.SpectatorSecretHash(); ok {
+		_spec.SetField(toolsession.FieldSpectatorSecretHash, field.TypeString, value)
+	}
+	if _u.mutation.SpectatorSecretHashCleared() {
+		_spec.ClearField(toolsession.FieldSpectatorSecretHash, field.TypeString)
+	}
 	if value, ok := _u.mutation.Pinned(); ok {
 		_spec.SetField(toolsession.FieldPinned, field.TypeBool, value)
 	}
@@ -12,12 +12,18 @@ import (
 // Tx is a transactional client that is created by calling Client.Tx().
 type Tx struct {
 	config
+	// APIKey is the client for interacting with the APIKey builders.
+	APIKey *APIKeyClient
 	// AccountBinding is the client for interacting with the AccountBinding builders.
 	AccountBinding *AccountBindingClient
 	// AgentRuntime is the client for interacting with the AgentRuntime builders.
 	AgentRuntime *AgentRuntimeClient
+	// ApprovalRequest is the client for interacting with the ApprovalRequest builders.
+	ApprovalRequest *ApprovalRequestClient
 	// AttachToken is the client for interacting with the AttachToken builders.
 	AttachToken *AttachTokenClient
+	// AuditLog is the client for interacting with the AuditLog builders.
+	AuditLog *AuditLogClient
 	// ChannelAccount is the client for interacting with the ChannelAccount builders.
 	ChannelAccount *ChannelAccountClient
 	// CollaborationEvent is the client for interacting with the CollaborationEvent builders.
@@ -26,6 +32,12 @@ type Tx struct {
 	ConfigSection *ConfigSectionClient
 	// CronJob is the client for interacting with the CronJob builders.
 	CronJob *CronJobClient
+	// FeedEntry is the client for interacting with the FeedEntry builders.
+	FeedEntry *FeedEntryClient
+	// FeedSubscription is the client for interacting with the FeedSubscription builders.
+	FeedSubscription *FeedSubscriptionClient
+	// Feedback is the client for interacting with the Feedback builders.
+	Feedback *FeedbackClient
 	// IdempotencyRecord is the client for interacting with the IdempotencyRecord builders.
 	IdempotencyRecord *IdempotencyRecordClient
 	// Membership is the client for interacting with the Membership builders.
@@ -38,6 +50,8 @@ type Tx struct {
 	NotificationBinding *NotificationBindingClient
 	// NotificationRoute is the client for interacting with the NotificationRoute builders.
 	NotificationRoute *NotificationRouteClient
+	// NotifyRule is the client for interacting with the NotifyRule builders.
+	NotifyRule *NotifyRuleClient
 	// PermissionRule is the client for interacting with the PermissionRule builders.
 	PermissionRule *PermissionRuleClient
 	// Prompt is the client for interacting with the Prompt builders.
@@ -56,6 +70,8 @@ type Tx struct {
 	ToolEvent *ToolEventClient
 	// ToolSession is the client for interacting with the ToolSession builders.
 	ToolSession *ToolSessionClient
+	// UsageRecord is the client for interacting with the UsageRecord builders.
+	UsageRecord *UsageRecordClient
 	// User is the client for interacting with the User builders.
 	User *UserClient
 
@@ -189,19 +205,26 @@ func (tx *Tx) Client() *Client {
 }
 
 func (tx *Tx) init() {
+	tx.APIKey = NewAPIKeyClient(tx.config)
 	tx.AccountBinding = NewAccountBindingClient(tx.config)
 	tx.AgentRuntime = NewAgentRuntimeClient(tx.config)
+	tx.ApprovalRequest = NewApprovalRequestClient(tx.config)
 	tx.AttachToken = NewAttachTokenClient(tx.config)
+	tx.AuditLog = NewAuditLogClient(tx.config)
 	tx.ChannelAccount = NewChannelAccountClient(tx.config)
 	tx.CollaborationEvent = NewCollaborationEventClient(tx.config)
 	tx.ConfigSection = NewConfigSectionClient(tx.config)
 	tx.CronJob = NewCronJobClient(tx.config)
+	tx.FeedEntry = NewFeedEntryClient(tx.config)
+	tx.FeedSubscription = NewFeedSubscriptionClient(tx.config)
+	tx.Feedback = NewFeedbackClient(tx.config)
 	tx.IdempotencyRecord = NewIdempotencyRecordClient(tx.config)
 	tx.Membership = NewMembershipClient(tx.config)
 	tx.ModelCatalog = NewModelCatalogClient(tx.config)
 	tx.ModelRoute = NewModelRouteClient(tx.config)
 	tx.NotificationBinding = NewNotificationBindingClient(tx.config)
 	tx.NotificationRoute = NewNotificationRouteClient(tx.config)
+	tx.NotifyRule = NewNotifyRuleClient(tx.config)
 	tx.PermissionRule = NewPermissionRuleClient(tx.config)
 	tx.Prompt = NewPromptClient(tx.config)
 	tx.PromptBinding = NewPromptBindingClient(tx.config)
@@ -211,6 +234,7 @@ func (tx *Tx) init() {
 	tx.Tenant = NewTenantClient(tx.config)
 	tx.ToolEvent = NewToolEventClient(tx.config)
 	tx.ToolSession = NewToolSessionClient(tx.config)
+	tx.UsageRecord = NewUsageRecordClient(tx.config)
 	tx.User = NewUserClient(tx.config)
 }
 
@@ -221,7 +245,7 @@ func (tx *Tx) init() {
 // of them in order to commit or rollback the transaction.
 //
 // If a closed transaction is embedded in one of the generated entities, and the entity
-// applies a query, for example: AccountBinding.QueryXXX(), the query will be executed
+// applies a query, for example: APIKey.QueryXXX(), the query will be executed
 // through the driver which created this transaction.
 //
 // Note that txDriver is not goroutine safe.
@@ -0,0 +1,669 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nekobot/pkg/storage/ent/predicate"
+	"nekobot/pkg/storage/ent/usagerecord"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// UsageRecordUpdate is the builder for updating UsageRecord entities.
+type UsageRecordUpdate struct {
+	config
+	hooks    []Hook
+	mutation *UsageRecordMutation
+}
+
+// Where appends a list predicates to the UsageRecordUpdate builder.
+func (_u *UsageRecordUpdate) Where(ps ...predicate.UsageRecord) *UsageRecordUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetProvider sets the "provider" field.
+func (_u *UsageRecordUpdate) SetProvider(v string) *UsageRecordUpdate {
+	_u.mutation.SetProvider(v)
+	return _u
+}
+
+// SetNillableProvider sets the "provider" field if the given value is not nil.
+func (_u *UsageRecordUpdate) SetNillableProvider(v *string) *UsageRecordUpdate {
+	if v != nil {
+		_u.SetProvider(*v)
+	}
+	return _u
+}
+
+// SetModel sets the "model" field.
+func (_u *UsageRecordUpdate) SetModel(v string) *UsageRecordUpdate {
+	_u.mutation.SetModel(v)
+	return _u
+}
+
+// SetNillableModel sets the "model" field if the given value is not nil.
+func (_u *UsageRecordUpdate) SetNillableModel(v *string) *UsageRecordUpdate {
+	if v != nil {
+		_u.SetModel(*v)
+	}
+	return _u
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *UsageRecordUpdate) SetUserID(v string) *UsageRecordUpdate {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *UsageRecordUpdate) SetNillableUserID(v *string) *UsageRecordUpdate {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// SetSessionID sets the "session_id" field.
+func (_u *UsageRecordUpdate) SetSessionID(v string) *UsageRecordUpdate {
+	_u.mutation.SetSessionID(v)
+	return _u
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (_u *UsageRecordUpdate) SetNillableSessionID(v *string) *UsageRecordUpdate {
+	if v != nil {
+		_u.SetSessionID(*v)
+	}
+	return _u
+}
+
+// SetChannel sets the "channel" field.
+func (_u *UsageRecordUpdate) SetChannel(v string) *UsageRecordUpdate {
+	_u.mutation.SetChannel(v)
+	return _u
+}
+
+// SetNillableChannel sets the "channel" field if the given value is not nil.
+func (_u *UsageRecordUpdate) SetNillableChannel(v *string) *UsageRecordUpdate {
+	if v != nil {
+		_u.SetChannel(*v)
+	}
+	return _u
+}
+
+// SetPromptTokens sets the "prompt_tokens" field.
+func (_u *UsageRecordUpdate) SetPromptTokens(v int) *UsageRecordUpdate {
+	_u.mutation.ResetPromptTokens()
+	_u.mutation.SetPromptTokens(v)
+	return _u
+}
+
+// SetNillablePromptTokens sets the "prompt_tokens" field if the given value is not nil.
+func (_u *UsageRecordUpdate) SetNillablePromptTokens(v *int) *UsageRecordUpdate {
+	if v != nil {
+		_u.SetPromptTokens(*v)
+	}
+	return _u
+}
+
+// AddPromptTokens adds value to the "prompt_tokens" field.
+func (_u *UsageRecordUpdate) AddPromptTokens(v int) *UsageRecordUpdate {
+	_u.mutation.AddPromptTokens(v)
+	return _u
+}
+
+// SetCompletionTokens sets the "completion_tokens" field.
+func (_u *UsageRecordUpdate) SetCompletionTokens(v int) *UsageRecordUpdate {
+	_u.mutation.ResetCompletionTokens()
+	_u.mutation.SetCompletionTokens(v)
+	return _u
+}
+
+// SetNillableCompletionTokens sets the "completion_tokens" field if the given value is not nil.
+func (_u *UsageRecordUpdate) SetNillableCompletionTokens(v *int) *UsageRecordUpdate {
+	if v != nil {
+		_u.SetCompletionTokens(*v)
+	}
+	return _u
+}
+
+// AddCompletionTokens adds value to the "completion_tokens" field.
+func (_u *UsageRecordUpdate) AddCompletionTokens(v int) *UsageRecordUpdate {
+	_u.mutation.AddCompletionTokens(v)
+	return _u
+}
+
+// SetTotalTokens sets the "total_tokens" field.
+func (_u *UsageRecordUpdate) SetTotalTokens(v int) *UsageRecordUpdate {
+	_u.mutation.ResetTotalTokens()
+	_u.mutation.SetTotalTokens(v)
+	return _u
+}
+
+// SetNillableTotalTokens sets the "total_tokens" field if the given value is not nil.
+func (_u *UsageRecordUpdate) SetNillableTotalTokens(v *int) *UsageRecordUpdate {
+	if v != nil {
+		_u.SetTotalTokens(*v)
+	}
+	return _u
+}
+
+// AddTotalTokens adds value to the "total_tokens" field.
+func (_u *UsageRecordUpdate) AddTotalTokens(v int) *UsageRecordUpdate {
+	_u.mutation.AddTotalTokens(v)
+	return _u
+}
+
+// SetCacheReadTokens sets the "cache_read_tokens" field.
+func (_u *UsageRecordUpdate) SetCacheReadTokens(v int) *UsageRecordUpdate {
+	_u.mutation.ResetCacheReadTokens()
+	_u.mutation.SetCacheReadTokens(v)
+	return _u
+}
+
+// SetNillableCacheReadTokens sets the "cache_read_tokens" field if the given value is not nil.
+func (_u *UsageRecordUpdate) SetNillableCacheReadTokens(v *int) *UsageRecordUpdate {
+	if v != nil {
+		_u.SetCacheReadTokens(*v)
+	}
+	return _u
+}
+
+// AddCacheReadTokens adds value to the "cache_read_tokens" field.
+func (_u *UsageRecordUpdate) AddCacheReadTokens(v int) *UsageRecordUpdate {
+	_u.mutation.AddCacheReadTokens(v)
+	return _u
+}
+
+// SetCacheWriteTokens sets the "cache_write_tokens" field.
+func (_u *UsageRecordUpdate) SetCacheWriteTokens(v int) *UsageRecordUpdate {
+	_u.mutation.ResetCacheWriteTokens()
+	_u.mutation.SetCacheWriteTokens(v)
+	return _u
+}
+
+// SetNillableCacheWriteTokens sets the "cache_write_tokens" field if the given value is not nil.
+func (_u *UsageRecordUpdate) SetNillableCacheWriteTokens(v *int) *UsageRecordUpdate {
+	if v != nil {
+		_u.SetCacheWriteTokens(*v)
+	}
+	return _u
+}
+
+// AddCacheWriteTokens adds value to the "cache_write_tokens" field.
+func (_u *UsageRecordUpdate) AddCacheWriteTokens(v int) *UsageRecordUpdate {
+	_u.mutation.AddCacheWriteTokens(v)
+	return _u
+}
+
+// SetCost sets the "cost" field.
+func (_u *UsageRecordUpdate) SetCost(v float64) *UsageRecordUpdate {
+	_u.mutation.ResetCost()
+	_u.mutation.SetCost(v)
+	return _u
+}
+
+// SetNillableCost sets the "cost" field if the given value is not nil.
+func (_u *UsageRecordUpdate) SetNillableCost(v *float64) *UsageRecordUpdate {
+	if v != nil {
+		_u.SetCost(*v)
+	}
+	return _u
+}
+
+// AddCost adds value to the "cost" field.
+func (_u *UsageRecordUpdate) AddCost(v float64) *UsageRecordUpdate {
+	_u.mutation.AddCost(v)
+	return _u
+}
+
+// Mutation returns the UsageRecordMutation object of the builder.
+func (_u *UsageRecordUpdate) Mutation() *UsageRecordMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *UsageRecordUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *UsageRecordUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *UsageRecordUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *UsageRecordUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (_u *UsageRecordUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(usagerecord.Table, usagerecord.Columns, sqlgraph.NewFieldSpec(usagerecord.FieldID, field.TypeString))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Provider(); ok {
+		_spec.SetField(usagerecord.FieldProvider, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Model(); ok {
+		_spec.SetField(usagerecord.FieldModel, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.UserID(); ok {
+		_spec.SetField(usagerecord.FieldUserID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.SessionID(); ok {
+		_spec.SetField(usagerecord.FieldSessionID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Channel(); ok {
+		_spec.SetField(usagerecord.FieldChannel, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.PromptTokens(); ok {
+		_spec.SetField(usagerecord.FieldPromptTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedPromptTokens(); ok {
+		_spec.AddField(usagerecord.FieldPromptTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.CompletionTokens(); ok {
+		_spec.SetField(usagerecord.FieldCompletionTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedCompletionTokens(); ok {
+		_spec.AddField(usagerecord.FieldCompletionTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.TotalTokens(); ok {
+		_spec.SetField(usagerecord.FieldTotalTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedTotalTokens(); ok {
+		_spec.AddField(usagerecord.FieldTotalTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.CacheReadTokens(); ok {
+		_spec.SetField(usagerecord.FieldCacheReadTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedCacheReadTokens(); ok {
+		_spec.AddField(usagerecord.FieldCacheReadTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.CacheWriteTokens(); ok {
+		_spec.SetField(usagerecord.FieldCacheWriteTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedCacheWriteTokens(); ok {
+		_spec.AddField(usagerecord.FieldCacheWriteTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Cost(); ok {
+		_spec.SetField(usagerecord.FieldCost, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedCost(); ok {
+		_spec.AddField(usagerecord.FieldCost, field.TypeFloat64, value)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{usagerecord.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// UsageRecordUpdateOne is the builder for updating a single UsageRecord entity.
+type UsageRecordUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *UsageRecordMutation
+}
+
+// SetProvider sets the "provider" field.
+func (_u *UsageRecordUpdateOne) SetProvider(v string) *UsageRecordUpdateOne {
+	_u.mutation.SetProvider(v)
+	return _u
+}
+
+// SetNillableProvider sets the "provider" field if the given value is not nil.
+func (_u *UsageRecordUpdateOne) SetNillableProvider(v *string) *UsageRecordUpdateOne {
+	if v != nil {
+		_u.SetProvider(*v)
+	}
+	return _u
+}
+
+// SetModel sets the "model" field.
+func (_u *UsageRecordUpdateOne) SetModel(v string) *UsageRecordUpdateOne {
+	_u.mutation.SetModel(v)
+	return _u
+}
+
+// SetNillableModel sets the "model" field if the given value is not nil.
+func (_u *UsageRecordUpdateOne) SetNillableModel(v *string) *UsageRecordUpdateOne {
+	if v != nil {
+		_u.SetModel(*v)
+	}
+	return _u
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *UsageRecordUpdateOne) SetUserID(v string) *UsageRecordUpdateOne {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *UsageRecordUpdateOne) SetNillableUserID(v *string) *UsageRecordUpdateOne {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// SetSessionID sets the "session_id" field.
+func (_u *UsageRecordUpdateOne) SetSessionID(v string) *UsageRecordUpdateOne {
+	_u.mutation.SetSessionID(v)
+	return _u
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (_u *UsageRecordUpdateOne) SetNillableSessionID(v *string) *UsageRecordUpdateOne {
+	if v != nil {
+		_u.SetSessionID(*v)
+	}
+	return _u
+}
+
+// SetChannel sets the "channel" field.
+func (_u *UsageRecordUpdateOne) SetChannel(v string) *UsageRecordUpdateOne {
+	_u.mutation.SetChannel(v)
+	return _u
+}
+
+// SetNillableChannel sets the "channel" field if the given value is not nil.
+func (_u *UsageRecordUpdateOne) SetNillableChannel(v *string) *UsageRecordUpdateOne {
+	if v != nil {
+		_u.SetChannel(*v)
+	}
+	return _u
+}
+
+// SetPromptTokens sets the "prompt_tokens" field.
+func (_u *UsageRecordUpdateOne) SetPromptTokens(v int) *UsageRecordUpdateOne {
+	_u.mutation.ResetPromptTokens()
+	_u.mutation.SetPromptTokens(v)
+	return _u
+}
+
+// SetNillablePromptTokens sets the "prompt_tokens" field if the given value is not nil.
+func (_u *UsageRecordUpdateOne) SetNillablePromptTokens(v *int) *UsageRecordUpdateOne {
+	if v != nil {
+		_u.SetPromptTokens(*v)
+	}
+	return _u
+}
+
+// AddPromptTokens adds value to the "prompt_tokens" field.
+func (_u *UsageRecordUpdateOne) AddPromptTokens(v int) *UsageRecordUpdateOne {
+	_u.mutation.AddPromptTokens(v)
+	return _u
+}
+
+// SetCompletionTokens sets the "completion_tokens" field.
+func (_u *UsageRecordUpdateOne) SetCompletionTokens(v int) *UsageRecordUpdateOne {
+	_u.mutation.ResetCompletionTokens()
+	_u.mutation.SetCompletionTokens(v)
+	return _u
+}
+
+// SetNillableCompletionTokens sets the "completion_tokens" field if the given value is not nil.
+func (_u *UsageRecordUpdateOne) SetNillableCompletionTokens(v *int) *UsageRecordUpdateOne {
+	if v != nil {
+		_u.SetCompletionTokens(*v)
+	}
+	return _u
+}
+
+// AddCompletionTokens adds value to the "completion_tokens" field.
+func (_u *UsageRecordUpdateOne) AddCompletionTokens(v int) *UsageRecordUpdateOne {
+	_u.mutation.AddCompletionTokens(v)
+	return _u
+}
+
+// SetTotalTokens sets the "total_tokens" field.
+func (_u *UsageRecordUpdateOne) SetTotalTokens(v int) *UsageRecordUpdateOne {
+	_u.mutation.ResetTotalTokens()
+	_u.mutation.SetTotalTokens(v)
+	return _u
+}
+
+// SetNillableTotalTokens sets the "total_tokens" field if the given value is not nil.
+func (_u *UsageRecordUpdateOne) SetNillableTotalTokens(v *int) *UsageRecordUpdateOne {
+	if v != nil {
+		_u.SetTotalTokens(*v)
+	}
+	return _u
+}
+
+// AddTotalTokens adds value to the "total_tokens" field.
+func (_u *UsageRecordUpdateOne) AddTotalTokens(v int) *UsageRecordUpdateOne {
+	_u.mutation.AddTotalTokens(v)
+	return _u
+}
+
+// SetCacheReadTokens sets the "cache_read_tokens" field.
+func (_u *UsageRecordUpdateOne) SetCacheReadTokens(v int) *UsageRecordUpdateOne {
+	_u.mutation.ResetCacheReadTokens()
+	_u.mutation.SetCacheReadTokens(v)
+	return _u
+}
+
+// SetNillableCacheReadTokens sets the "cache_read_tokens" field if the given value is not nil.
+func (_u *UsageRecordUpdateOne) SetNillableCacheReadTokens(v *int) *UsageRecordUpdateOne {
+	if v != nil {
+		_u.SetCacheReadTokens(*v)
+	}
+	return _u
+}
+
+// AddCacheReadTokens adds value to the "cache_read_tokens" field.
+func (_u *UsageRecordUpdateOne) AddCacheReadTokens(v int) *UsageRecordUpdateOne {
+	_u.mutation.AddCacheReadTokens(v)
+	return _u
+}
+
+// SetCacheWriteTokens sets the "cache_write_tokens" field.
+func (_u *UsageRecordUpdateOne) SetCacheWriteTokens(v int) *UsageRecordUpdateOne {
+	_u.mutation.ResetCacheWriteTokens()
+	_u.mutation.SetCacheWriteTokens(v)
+	return _u
+}
+
+// SetNillableCacheWriteTokens sets the "cache_write_tokens" field if the given value is not nil.
+func (_u *UsageRecordUpdateOne) SetNillableCacheWriteTokens(v *int) *UsageRecordUpdateOne {
+	if v != nil {
+		_u.SetCacheWriteTokens(*v)
+	}
+	return _u
+}
+
+// AddCacheWriteTokens adds value to the "cache_write_tokens" field.
+func (_u *UsageRecordUpdateOne) AddCacheWriteTokens(v int) *UsageRecordUpdateOne {
+	_u.mutation.AddCacheWriteTokens(v)
+	return _u
+}
+
+// SetCost sets the "cost" field.
+func (_u *UsageRecordUpdateOne) SetCost(v float64) *UsageRecordUpdateOne {
+	_u.mutation.ResetCost()
+	_u.mutation.SetCost(v)
+	return _u
+}
+
+// SetNillableCost sets the "cost" field if the given value is not nil.
+func (_u *UsageRecordUpdateOne) SetNillableCost(v *float64) *UsageRecordUpdateOne {
+	if v != nil {
+		_u.SetCost(*v)
+	}
+	return _u
+}
+
+// AddCost adds value to the "cost" field.
+func (_u *UsageRecordUpdateOne) AddCost(v float64) *UsageRecordUpdateOne {
+	_u.mutation.AddCost(v)
+	return _u
+}
+
+// Mutation returns the UsageRecordMutation object of the builder.
+func (_u *UsageRecordUpdateOne) Mutation() *UsageRecordMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the UsageRecordUpdate builder.
+func (_u *UsageRecordUpdateOne) Where(ps ...predicate.UsageRecord) *UsageRecordUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *UsageRecordUpdateOne) Select(field string, fields ...string) *UsageRecordUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated UsageRecord entity.
+func (_u *UsageRecordUpdateOne) Save(ctx context.Context) (*UsageRecord, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *UsageRecordUpdateOne) SaveX(ctx context.Context) *UsageRecord {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *UsageRecordUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *UsageRecordUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (_u *UsageRecordUpdateOne) sqlSave(ctx context.Context) (_node *UsageRecord, err error) {
+	_spec := sqlgraph.NewUpdateSpec(usagerecord.Table, usagerecord.Columns, sqlgraph.NewFieldSpec(usagerecord.FieldID, field.TypeString))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "UsageRecord.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, usagerecord.FieldID)
+		for _, f := range fields {
+			if !usagerecord.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != usagerecord.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Provider(); ok {
+		_spec.SetField(usagerecord.FieldProvider, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Model(); ok {
+		_spec.SetField(usagerecord.FieldModel, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.UserID(); ok {
+		_spec.SetField(usagerecord.FieldUserID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.SessionID(); ok {
+		_spec.SetField(usagerecord.FieldSessionID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Channel(); ok {
+		_spec.SetField(usagerecord.FieldChannel, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.PromptTokens(); ok {
+		_spec.SetField(usagerecord.FieldPromptTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedPromptTokens(); ok {
+		_spec.AddField(usagerecord.FieldPromptTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.CompletionTokens(); ok {
+		_spec.SetField(usagerecord.FieldCompletionTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedCompletionTokens(); ok {
+		_spec.AddField(usagerecord.FieldCompletionTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.TotalTokens(); ok {
+		_spec.SetField(usagerecord.FieldTotalTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedTotalTokens(); ok {
+		_spec.AddField(usagerecord.FieldTotalTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.CacheReadTokens(); ok {
+		_spec.SetField(usagerecord.FieldCacheReadTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedCacheReadTokens(); ok {
+		_spec.AddField(usagerecord.FieldCacheReadTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.CacheWriteTokens(); ok {
+		_spec.SetField(usagerecord.FieldCacheWriteTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedCacheWriteTokens(); ok {
+		_spec.AddField(usagerecord.FieldCacheWriteTokens, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Cost(); ok {
+		_spec.SetField(usagerecord.FieldCost, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedCost(); ok {
+		_spec.AddField(usagerecord.FieldCost, field.TypeFloat64, value)
+	}
+	_node = &UsageRecord{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{usagerecord.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
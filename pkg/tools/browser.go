@@ -32,6 +32,10 @@ type BrowserTool struct {
 	headless  bool
 	timeout   time.Duration
 	outputDir string
+	// workspace and restrict limit file:// pages to the workspace, like the
+	// file tools; other non-http(s) schemes are refused when restricted.
+	workspace string
+	restrict  bool
 }
 
 // NewBrowserTool creates a new browser tool.
@@ -64,6 +68,13 @@ func NewBrowserTool(log *logger.Logger, headless bool, timeout int, outputDir st
 	}
 }
 
+// SetWorkspace applies agents.defaults.restrict_to_workspace to the pages
+// the browser may open.
+func (b *BrowserTool) SetWorkspace(workspace string, restrict bool) {
+	b.workspace = workspace
+	b.restrict = restrict
+}
+
 // Name returns the tool name.
 func (b *BrowserTool) Name() string {
 	return "browser"
@@ -383,8 +394,8 @@ func (b *BrowserTool) navigate(ctx context.Context, params map[string]interface{
 	if err != nil {
 		return "", fmt.Errorf("invalid URL: %w", err)
 	}
-	if !parsedURL.IsAbs() || strings.TrimSpace(parsedURL.Scheme) == "" || strings.TrimSpace(parsedURL.Host) == "" {
-		return "", fmt.Errorf("absolute URL is required")
+	if err := b.checkURLAllowed(ctx, parsedURL); err != nil {
+		return "", err
 	}
 
 	b.log.Info("Browser navigating",
@@ -490,6 +501,41 @@ func (b *BrowserTool) startOptions(params map[string]interface{}) (BrowserStartO
 	}, nil
 }
 
+// checkURLAllowed accepts absolute http(s) URLs, and file:// URLs inside the
+// workspace. Without the workspace restriction any absolute URL with a host
+// is allowed.
+func (b *BrowserTool) checkURLAllowed(ctx context.Context, u *url.URL) error {
+	scheme := strings.ToLower(strings.TrimSpace(u.Scheme))
+	if scheme == "file" && b.restrict {
+		return b.checkFileURLInWorkspace(ctx, u)
+	}
+	if !u.IsAbs() || scheme == "" || strings.TrimSpace(u.Host) == "" {
+		return fmt.Errorf("absolute URL is required")
+	}
+	if b.restrict && scheme != "http" && scheme != "https" {
+		return fmt.Errorf("access denied: only http(s) and workspace file URLs are allowed")
+	}
+	return nil
+}
+
+func (b *BrowserTool) checkFileURLInWorkspace(ctx context.Context, u *url.URL) error {
+	if host := strings.ToLower(u.Host); host != "" && host != "localhost" {
+		return fmt.Errorf("access denied: remote file URLs are not allowed")
+	}
+	absPath, err := filepath.Abs(filepath.FromSlash(u.Path))
+	if err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+	absWorkspace, err := filepath.Abs(WorkspaceFor(ctx, b.workspace))
+	if err != nil {
+		return fmt.Errorf("invalid workspace: %w", err)
+	}
+	if absPath != absWorkspace && !strings.HasPrefix(absPath, absWorkspace+string(filepath.Separator)) {
+		return fmt.Errorf("access denied: path outside workspace")
+	}
+	return nil
+}
+
 func (b *BrowserTool) navigationParams(params map[string]interface{}, urlStr string) map[string]interface{} {
 	navigateParams := map[string]interface{}{
 		"url": urlStr,
@@ -1908,6 +1954,16 @@ func (b *BrowserTool) newPage(ctx context.Context, params map[string]interface{}
 		return "", err
 	}
 
+	if rawURL := stringParam(params, "url"); rawURL != "" {
+		parsedURL, err := url.Parse(rawURL)
+		if err != nil {
+			return "", fmt.Errorf("invalid URL: %w", err)
+		}
+		if err := b.checkURLAllowed(ctx, parsedURL); err != nil {
+			return "", err
+		}
+	}
+
 	target, err := b.createPageTarget(ctx, devtools, stringParam(params, "url"))
 	if err != nil {
 		return "", err
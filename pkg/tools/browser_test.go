@@ -3,7 +3,9 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"net/url"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -788,6 +790,43 @@ func TestBrowserToolStartOptionsRejectsInvalidDebugPort(t *testing.T) {
 	}
 }
 
+func TestBrowserToolRestrictsURLsToWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	tool := NewBrowserTool(newToolsTestLogger(t), true, 30, t.TempDir())
+	tool.SetWorkspace(workspace, true)
+
+	allowed := []string{
+		"https://example.com/app",
+		"file://" + filepath.ToSlash(filepath.Join(workspace, "report.html")),
+	}
+	for _, raw := range allowed {
+		u, _ := url.Parse(raw)
+		if err := tool.checkURLAllowed(context.Background(), u); err != nil {
+			t.Fatalf("expected %s to be allowed, got %v", raw, err)
+		}
+	}
+
+	denied := []string{
+		"file:///etc/passwd",
+		"file://localhost/etc/passwd",
+		"file://" + filepath.ToSlash(workspace) + "-other/x.html",
+		"chrome://settings/x",
+		"ftp://example.com/file",
+	}
+	for _, raw := range denied {
+		u, _ := url.Parse(raw)
+		if err := tool.checkURLAllowed(context.Background(), u); err == nil {
+			t.Fatalf("expected %s to be denied", raw)
+		}
+	}
+
+	tool.SetWorkspace(workspace, false)
+	u, _ := url.Parse("ftp://example.com/file")
+	if err := tool.checkURLAllowed(context.Background(), u); err != nil {
+		t.Fatalf("expected unrestricted browser to allow %s, got %v", u, err)
+	}
+}
+
 func TestBrowserToolExecuteRejectsRelativeNavigateURL(t *testing.T) {
 	tool := NewBrowserTool(newToolsTestLogger(t), true, 30, t.TempDir())
 
@@ -49,11 +49,11 @@ func (t *ListDirTool) Execute(ctx context.Context, args map[string]interface{})
 	}
 
 	// Resolve path
-	path := t.resolvePath(pathArg)
+	path := t.resolvePath(ctx, pathArg)
 
 	// Security check
 	if t.restrict {
-		if err := t.checkPathInWorkspace(path); err != nil {
+		if err := t.checkPathInWorkspace(ctx, path); err != nil {
 			return "", err
 		}
 	}
@@ -91,20 +91,20 @@ func (t *ListDirTool) Execute(ctx context.Context, args map[string]interface{})
 	return output, nil
 }
 
-func (t *ListDirTool) resolvePath(path string) string {
+func (t *ListDirTool) resolvePath(ctx context.Context, path string) string {
 	if filepath.IsAbs(path) {
 		return path
 	}
-	return filepath.Join(t.workspace, path)
+	return filepath.Join(WorkspaceFor(ctx, t.workspace), path)
 }
 
-func (t *ListDirTool) checkPathInWorkspace(path string) error {
+func (t *ListDirTool) checkPathInWorkspace(ctx context.Context, path string) error {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return fmt.Errorf("invalid path: %w", err)
 	}
 
-	absWorkspace, err := filepath.Abs(t.workspace)
+	absWorkspace, err := filepath.Abs(WorkspaceFor(ctx, t.workspace))
 	if err != nil {
 		return fmt.Errorf("invalid workspace: %w", err)
 	}
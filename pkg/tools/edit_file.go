@@ -71,11 +71,11 @@ func (t *EditFileTool) Execute(ctx context.Context, args map[string]interface{})
 	}
 
 	// Resolve path
-	path := t.resolvePath(pathArg)
+	path := t.resolvePath(ctx, pathArg)
 
 	// Security check
 	if t.restrict {
-		if err := t.checkPathInWorkspace(path); err != nil {
+		if err := t.checkPathInWorkspace(ctx, path); err != nil {
 			return "", err
 		}
 	}
@@ -108,20 +108,20 @@ func (t *EditFileTool) Execute(ctx context.Context, args map[string]interface{})
 	return fmt.Sprintf("Successfully edited %s", filepath.Base(path)), nil
 }
 
-func (t *EditFileTool) resolvePath(path string) string {
+func (t *EditFileTool) resolvePath(ctx context.Context, path string) string {
 	if filepath.IsAbs(path) {
 		return path
 	}
-	return filepath.Join(t.workspace, path)
+	return filepath.Join(WorkspaceFor(ctx, t.workspace), path)
 }
 
-func (t *EditFileTool) checkPathInWorkspace(path string) error {
+func (t *EditFileTool) checkPathInWorkspace(ctx context.Context, path string) error {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return fmt.Errorf("invalid path: %w", err)
 	}
 
-	absWorkspace, err := filepath.Abs(t.workspace)
+	absWorkspace, err := filepath.Abs(WorkspaceFor(ctx, t.workspace))
 	if err != nil {
 		return fmt.Errorf("invalid workspace: %w", err)
 	}
@@ -184,11 +184,11 @@ func (t *AppendFileTool) Execute(ctx context.Context, args map[string]interface{
 	}
 
 	// Resolve path
-	path := t.resolvePath(pathArg)
+	path := t.resolvePath(ctx, pathArg)
 
 	// Security check
 	if t.restrict {
-		if err := t.checkPathInWorkspace(path); err != nil {
+		if err := t.checkPathInWorkspace(ctx, path); err != nil {
 			return "", err
 		}
 	}
@@ -216,20 +216,20 @@ func (t *AppendFileTool) Execute(ctx context.Context, args map[string]interface{
 	return fmt.Sprintf("Successfully appended %d bytes to %s", n, filepath.Base(path)), nil
 }
 
-func (t *AppendFileTool) resolvePath(path string) string {
+func (t *AppendFileTool) resolvePath(ctx context.Context, path string) string {
 	if filepath.IsAbs(path) {
 		return path
 	}
-	return filepath.Join(t.workspace, path)
+	return filepath.Join(WorkspaceFor(ctx, t.workspace), path)
 }
 
-func (t *AppendFileTool) checkPathInWorkspace(path string) error {
+func (t *AppendFileTool) checkPathInWorkspace(ctx context.Context, path string) error {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return fmt.Errorf("invalid path: %w", err)
 	}
 
-	absWorkspace, err := filepath.Abs(t.workspace)
+	absWorkspace, err := filepath.Abs(WorkspaceFor(ctx, t.workspace))
 	if err != nil {
 		return fmt.Errorf("invalid workspace: %w", err)
 	}
@@ -14,21 +14,22 @@ import (
 	"time"
 
 	"github.com/creack/pty"
-	"github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/mount"
-	"github.com/docker/docker/client"
-	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/google/uuid"
 
 	"nekobot/pkg/execenv"
+	"nekobot/pkg/logger"
 	"nekobot/pkg/process"
+	"nekobot/pkg/sandbox"
 )
 
 // ExecConfig controls ExecTool behavior.
 type ExecConfig struct {
 	Timeout time.Duration
 	Sandbox DockerSandboxConfig
+	// Pool, when set, runs sandboxed commands in a persistent container per
+	// session instead of a fresh container per call.
+	Pool *sandbox.Pool
 }
 
 // DockerSandboxConfig controls containerized execution.
@@ -39,6 +40,44 @@ type DockerSandboxConfig struct {
 	Mounts      []string
 	Timeout     time.Duration
 	AutoCleanup bool
+	Runtime     string // Backend name, see sandbox.NewBackend
+	OCIRuntime  string // Overrides the backend's default OCI runtime
+	Limits      sandbox.Limits
+	// Pool sizing, used by NewSandboxPool.
+	PoolSize      int
+	MaxContainers int
+	IdleTimeout   time.Duration
+}
+
+// NewSandboxPool creates a Docker container pool for cfg, resolving relative
+// mount sources against workspace.
+func NewSandboxPool(log *logger.Logger, workspace string, cfg DockerSandboxConfig) (*sandbox.Pool, error) {
+	mounts, err := parseMountSpecs(workspace, cfg.Mounts)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Image == "" {
+		cfg.Image = "alpine:3.20"
+	}
+	if cfg.NetworkMode == "" {
+		cfg.NetworkMode = "none"
+	}
+	backend, err := sandbox.NewBackend(cfg.Runtime, cfg.OCIRuntime)
+	if err != nil {
+		return nil, err
+	}
+	return sandbox.NewPool(log, backend, sandbox.Config{
+		Spec: sandbox.Spec{
+			Image:       cfg.Image,
+			NetworkMode: cfg.NetworkMode,
+			Workspace:   workspace,
+			Mounts:      mounts,
+			Limits:      cfg.Limits,
+		},
+		PoolSize:      cfg.PoolSize,
+		MaxContainers: cfg.MaxContainers,
+		IdleTimeout:   cfg.IdleTimeout,
+	}), nil
 }
 
 // ExecTool allows the agent to execute shell commands.
@@ -47,6 +86,7 @@ type ExecTool struct {
 	restrict       bool
 	config         ExecConfig
 	processManager *process.Manager
+	backend        sandbox.Backend
 	mu             sync.RWMutex
 	sandboxOff     bool
 	sandboxReason  string
@@ -67,12 +107,20 @@ func NewExecTool(workspace string, restrict bool, cfg ExecConfig, pm *process.Ma
 		cfg.Sandbox.Timeout = 60 * time.Second
 	}
 
-	return &ExecTool{
+	t := &ExecTool{
 		workspace:      workspace,
 		restrict:       restrict,
 		config:         cfg,
 		processManager: pm,
 	}
+	if cfg.Sandbox.Enabled {
+		backend, err := sandbox.NewBackend(cfg.Sandbox.Runtime, cfg.Sandbox.OCIRuntime)
+		if err != nil {
+			t.disableSandbox(err.Error())
+		}
+		t.backend = backend
+	}
+	return t
 }
 
 func (t *ExecTool) Name() string {
@@ -139,19 +187,17 @@ func (t *ExecTool) Execute(ctx context.Context, args map[string]interface{}) (st
 	}
 
 	// Resolve workdir
+	workspace := WorkspaceFor(ctx, t.workspace)
 	if workdir == "" {
-		workdir = t.workspace
+		workdir = workspace
 	} else if !strings.HasPrefix(workdir, "/") {
-		workdir = filepath.Join(t.workspace, workdir)
+		workdir = filepath.Join(workspace, workdir)
 	}
 
 	// Basic security: prevent dangerous commands
 	if t.restrict {
-		dangerous := []string{"rm -rf /", "dd if=", "mkfs", "> /dev/", ":(){ :|:& };:", "curl | sh", "wget | sh"}
-		for _, d := range dangerous {
-			if strings.Contains(command, d) {
-				return "", fmt.Errorf("potentially dangerous command blocked: contains '%s'", d)
-			}
+		if err := checkDangerousCommand(command); err != nil {
+			return "", err
 		}
 	}
 
@@ -189,7 +235,13 @@ func (t *ExecTool) Execute(ctx context.Context, args map[string]interface{}) (st
 			return streamingFallbackNotice + "Docker sandbox is disabled for this process, fallback to " + fallbackMode + ".\nReason: " + reason + "\n\n" + fallback, fallbackErr
 		}
 
-		result, err := t.executeInDocker(ctx, command, workdir, execTimeout)
+		var result string
+		var err error
+		if sessionID, _ := ctx.Value("session_id").(string); t.config.Pool != nil && sessionID != "" {
+			result, err = t.executeInSessionContainer(ctx, sessionID, command, workdir, execTimeout)
+		} else {
+			result, err = t.executeInDocker(ctx, command, workdir, execTimeout)
+		}
 		if err == nil {
 			return result, nil
 		}
@@ -231,123 +283,81 @@ func (t *ExecTool) executeInDocker(ctx context.Context, command, workdir string,
 	execCtx, cancel := context.WithTimeout(ctx, dockerTimeout)
 	defer cancel()
 
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
-	if err != nil {
-		return "", &sandboxUnavailableError{cause: fmt.Errorf("initializing docker client: %w", err)}
-	}
-	defer func() {
-		_ = cli.Close()
-	}()
-
-	if _, err := cli.Ping(execCtx); err != nil {
-		return "", &sandboxUnavailableError{cause: fmt.Errorf("docker daemon unavailable: %w", err)}
-	}
-
-	// Try to pull image (best effort, errors ignored because image may already exist and pull can be rate-limited).
-	if reader, err := cli.ImagePull(execCtx, t.config.Sandbox.Image, image.PullOptions{}); err == nil {
-		_, _ = io.Copy(io.Discard, reader)
-		_ = reader.Close()
-	}
-
-	containerWorkdir := "/workspace"
-	if rel, err := filepath.Rel(t.workspace, workdir); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
-		containerWorkdir = filepath.ToSlash(filepath.Join("/workspace", rel))
-	}
-
-	mounts := []mount.Mount{
-		{
-			Type:   mount.TypeBind,
-			Source: t.workspace,
-			Target: "/workspace",
-		},
-	}
-
-	extraMounts, err := parseMountSpecs(t.workspace, t.config.Sandbox.Mounts)
+	workspace := WorkspaceFor(ctx, t.workspace)
+	extraMounts, err := parseMountSpecs(workspace, t.config.Sandbox.Mounts)
 	if err != nil {
 		return "", err
 	}
-	mounts = append(mounts, extraMounts...)
-
-	resp, err := cli.ContainerCreate(
-		execCtx,
-		&container.Config{
-			Image:        t.config.Sandbox.Image,
-			Cmd:          []string{"sh", "-c", command},
-			WorkingDir:   containerWorkdir,
-			AttachStdout: true,
-			AttachStderr: true,
-			Tty:          false,
-		},
-		&container.HostConfig{
-			NetworkMode: container.NetworkMode(t.config.Sandbox.NetworkMode),
-			Mounts:      mounts,
-		},
-		nil,
-		nil,
-		"",
-	)
-	if err != nil {
-		return "", fmt.Errorf("creating sandbox container: %w", err)
-	}
-
-	containerID := resp.ID
-	if t.config.Sandbox.AutoCleanup {
-		defer func() {
-			cleanupCtx, cleanupCancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cleanupCancel()
-			_ = cli.ContainerRemove(cleanupCtx, containerID, container.RemoveOptions{Force: true, RemoveVolumes: true})
-		}()
-	}
-
-	if err := cli.ContainerStart(execCtx, containerID, container.StartOptions{}); err != nil {
-		return "", fmt.Errorf("starting sandbox container: %w", err)
+	spec := sandbox.Spec{
+		Image:       t.config.Sandbox.Image,
+		NetworkMode: t.config.Sandbox.NetworkMode,
+		Workspace:   workspace,
+		Mounts:      extraMounts,
+		Limits:      t.config.Sandbox.Limits,
+		AutoCleanup: t.config.Sandbox.AutoCleanup,
 	}
 
-	waitCh, errCh := cli.ContainerWait(execCtx, containerID, container.WaitConditionNotRunning)
-	var waitResp container.WaitResponse
-
-	select {
-	case err := <-errCh:
-		if err != nil {
-			return "", fmt.Errorf("waiting for sandbox container: %w", err)
+	res, err := t.backend.Run(execCtx, spec, []string{"sh", "-c", command}, sandboxWorkdir(workspace, workdir))
+	if err != nil {
+		if errors.Is(err, sandbox.ErrUnavailable) {
+			return "", &sandboxUnavailableError{cause: err}
 		}
-	case waitResp = <-waitCh:
-	case <-execCtx.Done():
-		return "", fmt.Errorf("sandbox command timed out after %v", dockerTimeout)
+		if execCtx.Err() != nil {
+			return "", fmt.Errorf("sandbox command timed out after %v", dockerTimeout)
+		}
+		return "", err
 	}
+	return formatSandboxResult(command, workdir, fmt.Sprintf("Sandbox, %s (%s)", t.backend.Name(), t.config.Sandbox.Image), res), nil
+}
 
-	var stdoutBuf, stderrBuf bytes.Buffer
-	logsReader, err := cli.ContainerLogs(execCtx, containerID, container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-	})
-	if err == nil {
-		defer func() {
-			_ = logsReader.Close()
-		}()
-		_, _ = stdcopy.StdCopy(&stdoutBuf, &stderrBuf, logsReader)
+// sandboxWorkdir maps a host workdir inside the workspace to its path in the container.
+func sandboxWorkdir(workspace, workdir string) string {
+	if rel, err := filepath.Rel(workspace, workdir); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(filepath.Join(sandbox.WorkspaceMount, rel))
 	}
+	return sandbox.WorkspaceMount
+}
 
+func formatSandboxResult(command, workdir, mode string, res sandbox.ExecResult) string {
 	var result strings.Builder
 	_, _ = fmt.Fprintf(&result, "Command: %s\n", command)
 	_, _ = fmt.Fprintf(&result, "Working Directory: %s\n", workdir)
-	_, _ = fmt.Fprintf(&result, "Mode: Docker Sandbox (%s)\n\n", t.config.Sandbox.Image)
-
-	if stdoutBuf.Len() > 0 {
+	_, _ = fmt.Fprintf(&result, "Mode: %s\n\n", mode)
+	if res.Stdout != "" {
 		result.WriteString("STDOUT:\n")
-		result.WriteString(stdoutBuf.String())
+		result.WriteString(res.Stdout)
 		result.WriteString("\n")
 	}
-	if stderrBuf.Len() > 0 {
+	if res.Stderr != "" {
 		result.WriteString("STDERR:\n")
-		result.WriteString(stderrBuf.String())
+		result.WriteString(res.Stderr)
 		result.WriteString("\n")
 	}
-	if waitResp.Error != nil && waitResp.Error.Message != "" {
-		_, _ = fmt.Fprintf(&result, "Error: %s\n", waitResp.Error.Message)
+	_, _ = fmt.Fprintf(&result, "\nExit Code: %d\n", res.ExitCode)
+	return result.String()
+}
+
+// executeInSessionContainer runs command in the session's pooled container.
+func (t *ExecTool) executeInSessionContainer(ctx context.Context, sessionID, command, workdir string, timeout time.Duration) (string, error) {
+	dockerTimeout := timeout
+	if t.config.Sandbox.Timeout > dockerTimeout {
+		dockerTimeout = t.config.Sandbox.Timeout
 	}
-	_, _ = fmt.Fprintf(&result, "\nExit Code: %d\n", waitResp.StatusCode)
-	return result.String(), nil
+	execCtx, cancel := context.WithTimeout(ctx, dockerTimeout)
+	defer cancel()
+
+	workspace := WorkspaceFor(ctx, t.workspace)
+	res, err := t.config.Pool.Exec(execCtx, sessionID, workspace, []string{"sh", "-c", command}, sandboxWorkdir(workspace, workdir))
+	if err != nil {
+		if errors.Is(err, sandbox.ErrUnavailable) {
+			return "", &sandboxUnavailableError{cause: err}
+		}
+		if execCtx.Err() != nil {
+			return "", fmt.Errorf("sandbox command timed out after %v: %w", dockerTimeout, err)
+		}
+		return "", err
+	}
+	return formatSandboxResult(command, workdir, fmt.Sprintf("Sandbox, session container on %s (%s)", t.backend.Name(), t.config.Pool.Image()), res), nil
 }
 
 // executeStandard executes command in standard mode.
@@ -583,6 +593,17 @@ func (t *ExecTool) isSandboxDisabled() (bool, string) {
 }
 
 // Helper functions
+// checkDangerousCommand blocks a few obviously destructive shell patterns.
+func checkDangerousCommand(command string) error {
+	dangerous := []string{"rm -rf /", "dd if=", "mkfs", "> /dev/", ":(){ :|:& };:", "curl | sh", "wget | sh"}
+	for _, d := range dangerous {
+		if strings.Contains(command, d) {
+			return fmt.Errorf("potentially dangerous command blocked: contains '%s'", d)
+		}
+	}
+	return nil
+}
+
 func getBoolArg(args map[string]interface{}, key string, defaultVal bool) bool {
 	if val, ok := args[key].(bool); ok {
 		return val
@@ -50,11 +50,11 @@ func (t *ReadFileTool) Execute(ctx context.Context, args map[string]interface{})
 	}
 
 	// Resolve path
-	path := t.resolvePath(pathArg)
+	path := t.resolvePath(ctx, pathArg)
 
 	// Security check
 	if t.restrict {
-		if err := t.checkPathInWorkspace(path); err != nil {
+		if err := t.checkPathInWorkspace(ctx, path); err != nil {
 			return "", err
 		}
 	}
@@ -69,21 +69,21 @@ func (t *ReadFileTool) Execute(ctx context.Context, args map[string]interface{})
 }
 
 // resolvePath resolves a path relative to workspace if it's not absolute.
-func (t *ReadFileTool) resolvePath(path string) string {
+func (t *ReadFileTool) resolvePath(ctx context.Context, path string) string {
 	if filepath.IsAbs(path) {
 		return path
 	}
-	return filepath.Join(t.workspace, path)
+	return filepath.Join(WorkspaceFor(ctx, t.workspace), path)
 }
 
 // checkPathInWorkspace ensures the path is within the workspace.
-func (t *ReadFileTool) checkPathInWorkspace(path string) error {
+func (t *ReadFileTool) checkPathInWorkspace(ctx context.Context, path string) error {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return fmt.Errorf("invalid path: %w", err)
 	}
 
-	absWorkspace, err := filepath.Abs(t.workspace)
+	absWorkspace, err := filepath.Abs(WorkspaceFor(ctx, t.workspace))
 	if err != nil {
 		return fmt.Errorf("invalid workspace: %w", err)
 	}
@@ -146,11 +146,11 @@ func (t *WriteFileTool) Execute(ctx context.Context, args map[string]interface{}
 	}
 
 	// Resolve path
-	path := t.resolvePath(pathArg)
+	path := t.resolvePath(ctx, pathArg)
 
 	// Security check
 	if t.restrict {
-		if err := t.checkPathInWorkspace(path); err != nil {
+		if err := t.checkPathInWorkspace(ctx, path); err != nil {
 			return "", err
 		}
 	}
@@ -169,20 +169,20 @@ func (t *WriteFileTool) Execute(ctx context.Context, args map[string]interface{}
 	return fmt.Sprintf("Successfully wrote %d bytes to %s", len(content), path), nil
 }
 
-func (t *WriteFileTool) resolvePath(path string) string {
+func (t *WriteFileTool) resolvePath(ctx context.Context, path string) string {
 	if filepath.IsAbs(path) {
 		return path
 	}
-	return filepath.Join(t.workspace, path)
+	return filepath.Join(WorkspaceFor(ctx, t.workspace), path)
 }
 
-func (t *WriteFileTool) checkPathInWorkspace(path string) error {
+func (t *WriteFileTool) checkPathInWorkspace(ctx context.Context, path string) error {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return fmt.Errorf("invalid path: %w", err)
 	}
 
-	absWorkspace, err := filepath.Abs(t.workspace)
+	absWorkspace, err := filepath.Abs(WorkspaceFor(ctx, t.workspace))
 	if err != nil {
 		return fmt.Errorf("invalid workspace: %w", err)
 	}
@@ -0,0 +1,343 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// DefaultSecretFilePatterns name files whose contents read_file redacts.
+var DefaultSecretFilePatterns = []string{
+	".env", ".env.*", "*.pem", "*.key", "*.p12", "*.pfx",
+	"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519", ".netrc", ".pgpass",
+}
+
+// FilePathRules are allow and deny glob patterns for file tool paths.
+type FilePathRules struct {
+	Allow []string
+	Deny  []string
+}
+
+// FilePolicyOptions configures a FilePolicy.
+type FilePolicyOptions struct {
+	FilePathRules
+	// Agents adds rules for calls made under an agent profile. Their deny
+	// patterns add to the global ones; their allow patterns, when set,
+	// replace the global ones.
+	Agents               map[string]FilePathRules
+	MaxFileBytes         int64 // Largest file read or written, 0 disables
+	MaxSessionWriteBytes int64 // Total bytes one session may write, 0 disables
+	RedactSecrets        bool
+	SecretPatterns       []string // Added to DefaultSecretFilePatterns
+}
+
+// FilePolicy is a registry Guard enforcing path rules, size limits, write
+// quotas and secret redaction for read_file, write_file, edit_file,
+// append_file and list_dir.
+type FilePolicy struct {
+	workspace string
+
+	optsMu  sync.RWMutex
+	opts    FilePolicyOptions
+	secrets []string
+
+	mu      sync.Mutex
+	written map[string]int64 // session ID -> bytes written
+}
+
+type agentNameContextKey struct{}
+
+// WithAgentName records the agent profile a tool call runs under, selecting
+// its per-agent file rules.
+func WithAgentName(ctx context.Context, name string) context.Context {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, agentNameContextKey{}, name)
+}
+
+func agentNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(agentNameContextKey{}).(string)
+	return name
+}
+
+// NewFilePolicy creates a file policy. Relative paths resolve against
+// workspace unless the call's context overrides it.
+func NewFilePolicy(workspace string, opts FilePolicyOptions) *FilePolicy {
+	p := &FilePolicy{
+		workspace: workspace,
+		written:   make(map[string]int64),
+	}
+	p.SetOptions(opts)
+	return p
+}
+
+// SetOptions replaces the rules and limits of a live policy. Bytes already
+// written keep counting against the session quotas.
+func (p *FilePolicy) SetOptions(opts FilePolicyOptions) {
+	secrets := append([]string(nil), DefaultSecretFilePatterns...)
+	secrets = append(secrets, opts.SecretPatterns...)
+	p.optsMu.Lock()
+	p.opts = opts
+	p.secrets = secrets
+	p.optsMu.Unlock()
+}
+
+func (p *FilePolicy) options() (FilePolicyOptions, []string) {
+	p.optsMu.RLock()
+	defer p.optsMu.RUnlock()
+	return p.opts, p.secrets
+}
+
+// fileToolWrites reports, for each guarded tool, whether it writes.
+var fileToolWrites = map[string]bool{
+	"read_file":   false,
+	"list_dir":    false,
+	"write_file":  true,
+	"edit_file":   true,
+	"append_file": true,
+}
+
+// Check refuses calls to paths the rules deny or that exceed a size limit.
+func (p *FilePolicy) Check(ctx context.Context, toolName string, args map[string]interface{}) error {
+	writes, ok := fileToolWrites[toolName]
+	if !ok {
+		return nil
+	}
+	pathArg, _ := args["path"].(string)
+	if pathArg == "" {
+		return nil // Let the tool report the missing argument
+	}
+	opts, _ := p.options()
+	workspace := WorkspaceFor(ctx, p.workspace)
+	path := resolvePolicyPath(workspace, pathArg)
+	if err := checkPolicyPath(ctx, opts, workspace, path); err != nil {
+		return err
+	}
+	// Check the link target too, so a symlink cannot step around the rules.
+	if real, err := filepath.EvalSymlinks(path); err == nil && real != path {
+		realWorkspace, err := filepath.EvalSymlinks(workspace)
+		if err != nil {
+			realWorkspace = workspace
+		}
+		if err := checkPolicyPath(ctx, opts, realWorkspace, real); err != nil {
+			return err
+		}
+	}
+
+	var size int64
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		size = info.Size()
+	}
+	if !writes {
+		if toolName == "read_file" && opts.MaxFileBytes > 0 && size > opts.MaxFileBytes {
+			return fmt.Errorf("access denied: %s is %d bytes, over the %d byte file limit", pathArg, size, opts.MaxFileBytes)
+		}
+		return nil
+	}
+
+	added := writtenBytes(toolName, args)
+	newSize := added
+	switch toolName {
+	case "append_file":
+		newSize = size + added
+	case "edit_file":
+		oldString, _ := args["old_string"].(string)
+		newSize = size - int64(len(oldString)) + added
+	}
+	if opts.MaxFileBytes > 0 && newSize > opts.MaxFileBytes {
+		return fmt.Errorf("access denied: writing %s would make it %d bytes, over the %d byte file limit", pathArg, newSize, opts.MaxFileBytes)
+	}
+	if sessionID := policySessionID(ctx); sessionID != "" && opts.MaxSessionWriteBytes > 0 {
+		p.mu.Lock()
+		used := p.written[sessionID]
+		p.mu.Unlock()
+		if used+added > opts.MaxSessionWriteBytes {
+			return fmt.Errorf("access denied: session write quota exceeded (%d of %d bytes used)", used, opts.MaxSessionWriteBytes)
+		}
+	}
+	return nil
+}
+
+// Filter counts bytes written against the session quota and redacts secret
+// files returned by read_file.
+func (p *FilePolicy) Filter(ctx context.Context, toolName string, args map[string]interface{}, result string) string {
+	writes, ok := fileToolWrites[toolName]
+	if !ok {
+		return result
+	}
+	if writes {
+		if sessionID := policySessionID(ctx); sessionID != "" {
+			p.mu.Lock()
+			p.written[sessionID] += writtenBytes(toolName, args)
+			p.mu.Unlock()
+		}
+		return result
+	}
+	opts, secrets := p.options()
+	if toolName != "read_file" || !opts.RedactSecrets {
+		return result
+	}
+	pathArg, _ := args["path"].(string)
+	path := resolvePolicyPath(WorkspaceFor(ctx, p.workspace), pathArg)
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		path = real
+	}
+	name := filepath.Base(path)
+	for _, pattern := range secrets {
+		if matched, _ := filepath.Match(pattern, name); !matched {
+			continue
+		}
+		if name == ".env" || strings.HasPrefix(name, ".env.") {
+			return redactEnv(result)
+		}
+		return fmt.Sprintf("[REDACTED: %s matches secret file pattern %q]", name, pattern)
+	}
+	return result
+}
+
+func checkPolicyPath(ctx context.Context, opts FilePolicyOptions, workspace, path string) error {
+	rules := opts.FilePathRules
+	if agent, ok := opts.Agents[agentNameFromContext(ctx)]; ok {
+		rules.Deny = append(append([]string(nil), rules.Deny...), agent.Deny...)
+		if len(agent.Allow) > 0 {
+			rules.Allow = agent.Allow
+		}
+	}
+	for _, pattern := range rules.Deny {
+		if matchPathPattern(pattern, workspace, path) {
+			return fmt.Errorf("access denied: path matches deny pattern %q", pattern)
+		}
+	}
+	if len(rules.Allow) == 0 {
+		return nil
+	}
+	for _, pattern := range rules.Allow {
+		if matchPathPattern(pattern, workspace, path) {
+			return nil
+		}
+	}
+	return fmt.Errorf("access denied: path matches no allow pattern")
+}
+
+func resolvePolicyPath(workspace, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workspace, path)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// matchPathPattern matches a glob against path or any of its parent
+// directories, so a pattern naming a directory covers its contents. "**"
+// spans directories. Absolute patterns match the absolute path, patterns
+// containing "/" match the workspace-relative path, and bare names match
+// any single path element.
+func matchPathPattern(pattern, workspace, path string) bool {
+	pattern = filepath.ToSlash(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return false
+	}
+	target := filepath.ToSlash(path)
+	if !strings.HasPrefix(pattern, "/") {
+		if absWorkspace, err := filepath.Abs(workspace); err == nil {
+			if rel, err := filepath.Rel(absWorkspace, path); err == nil && rel != ".." && !strings.HasPrefix(rel, "../") {
+				target = filepath.ToSlash(rel)
+			} else if strings.Contains(pattern, "/") {
+				return false // Relative patterns only apply inside the workspace
+			}
+		}
+	}
+	elements := strings.Split(strings.Trim(target, "/"), "/")
+
+	if !strings.Contains(pattern, "/") {
+		for _, element := range elements {
+			if matched, _ := filepath.Match(pattern, element); matched {
+				return true
+			}
+		}
+		return false
+	}
+
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return false
+	}
+	prefix := ""
+	if strings.HasPrefix(target, "/") {
+		prefix = "/"
+	}
+	for i := range elements {
+		candidate := prefix + strings.Join(elements[:i+1], "/")
+		if re.MatchString(candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+func writtenBytes(toolName string, args map[string]interface{}) int64 {
+	key := "content"
+	if toolName == "edit_file" {
+		key = "new_string"
+	}
+	content, _ := args[key].(string)
+	return int64(len(content))
+}
+
+func policySessionID(ctx context.Context) string {
+	sessionID, _ := ctx.Value("session_id").(string)
+	return sessionID
+}
+
+// redactEnv keeps the variable names of a dotenv file and hides the values.
+func redactEnv(content string) string {
+	var b strings.Builder
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if key, _, ok := strings.Cut(line, "="); ok && trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			line = key + "=[REDACTED]"
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,512 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	gitCommandTimeout = 30 * time.Second
+	// gitMaxOutputChars bounds diff and log output returned to the model.
+	gitMaxOutputChars  = 50000
+	gitDefaultLogCount = 20
+	gitMaxLogCount     = 200
+)
+
+// gitWorkspace runs git in a repository inside the workspace. The git tools
+// are always confined to the workspace, independent of restrict_to_workspace,
+// so chat channels cannot reach other repositories on the host.
+type gitWorkspace struct {
+	workspace string
+}
+
+// NewGitTools creates the git_status, git_diff, git_log, git_branch and
+// git_commit tools for repositories inside workspace.
+func NewGitTools(workspace string) []Tool {
+	repo := gitWorkspace{workspace: workspace}
+	return []Tool{
+		&GitStatusTool{repo: repo},
+		&GitDiffTool{repo: repo},
+		&GitLogTool{repo: repo},
+		&GitBranchTool{repo: repo},
+		&GitCommitTool{repo: repo},
+	}
+}
+
+func gitRepoParam() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": "Repository directory relative to the workspace (default: the workspace itself)",
+	}
+}
+
+// repoDir resolves the repo argument and checks that the repository it
+// belongs to lives inside the workspace.
+func (g gitWorkspace) repoDir(ctx context.Context, args map[string]interface{}) (string, error) {
+	workspace, err := filepath.Abs(WorkspaceFor(ctx, g.workspace))
+	if err != nil {
+		return "", fmt.Errorf("invalid workspace: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(workspace); err == nil {
+		workspace = resolved
+	}
+
+	dir := workspace
+	if repo := strings.TrimSpace(getStringArg(args, "repo", "")); repo != "" {
+		if filepath.IsAbs(repo) {
+			dir = filepath.Clean(repo)
+		} else {
+			dir = filepath.Join(workspace, repo)
+		}
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	} else if os.IsNotExist(err) {
+		return "", fmt.Errorf("repository directory not found: %s", getStringArg(args, "repo", ""))
+	}
+	if !pathWithin(workspace, dir) {
+		return "", fmt.Errorf("access denied: repository outside workspace")
+	}
+
+	top, err := g.run(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %w", err)
+	}
+	top = strings.TrimSpace(top)
+	if resolved, err := filepath.EvalSymlinks(top); err == nil {
+		top = resolved
+	}
+	if !pathWithin(workspace, top) {
+		return "", fmt.Errorf("access denied: repository root %s is outside the workspace", top)
+	}
+	return top, nil
+}
+
+// pathspecs turns the paths argument into pathspecs relative to dir,
+// rejecting anything outside it.
+func (g gitWorkspace) pathspecs(dir string, args map[string]interface{}) ([]string, error) {
+	raw, _ := args["paths"].([]interface{})
+	specs := make([]string, 0, len(raw))
+	for _, item := range raw {
+		path, ok := item.(string)
+		if !ok || strings.TrimSpace(path) == "" {
+			continue
+		}
+		path = strings.TrimSpace(path)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		path = filepath.Clean(path)
+		if !pathWithin(dir, path) {
+			return nil, fmt.Errorf("access denied: path outside repository: %s", item)
+		}
+		rel, _ := filepath.Rel(dir, path)
+		specs = append(specs, filepath.ToSlash(rel))
+	}
+	return specs, nil
+}
+
+func (g gitWorkspace) run(ctx context.Context, dir string, args ...string) (string, error) {
+	return g.runWithInput(ctx, dir, "", args...)
+}
+
+// runWithInput runs git in dir, feeding input on stdin when non-empty.
+func (g gitWorkspace) runWithInput(ctx context.Context, dir, input string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", append([]string{"--no-pager", "-c", "color.ui=false"}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_OPTIONAL_LOCKS=0")
+	if input != "" {
+		cmd.Stdin = strings.NewReader(input)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		return "", fmt.Errorf("git %s: %w: %s", gitSubcommand(args), err, msg)
+	}
+	return stdout.String(), nil
+}
+
+// gitSubcommand returns the subcommand of args, skipping "-c key=value".
+func gitSubcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-c" {
+			i++
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
+
+func pathWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// checkRevision rejects revisions that git would parse as options.
+func checkRevision(rev string) error {
+	if strings.HasPrefix(rev, "-") {
+		return fmt.Errorf("invalid revision: %s", rev)
+	}
+	return nil
+}
+
+// GitStatusTool shows the working tree status.
+type GitStatusTool struct {
+	repo gitWorkspace
+}
+
+func (t *GitStatusTool) Name() string {
+	return "git_status"
+}
+
+func (t *GitStatusTool) Description() string {
+	return "Show the branch and changed files of a git repository inside the workspace."
+}
+
+func (t *GitStatusTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"repo": gitRepoParam(),
+		},
+	}
+}
+
+func (t *GitStatusTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	dir, err := t.repo.repoDir(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	out, err := t.repo.run(ctx, dir, "status", "--short", "--branch")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+// GitDiffTool shows unstaged, staged or revision diffs.
+type GitDiffTool struct {
+	repo gitWorkspace
+}
+
+func (t *GitDiffTool) Name() string {
+	return "git_diff"
+}
+
+func (t *GitDiffTool) Description() string {
+	return "Show changes in a git repository inside the workspace: unstaged by default, staged with staged=true, or against a revision."
+}
+
+func (t *GitDiffTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"repo": gitRepoParam(),
+			"staged": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Show staged changes instead of unstaged ones",
+			},
+			"revision": map[string]interface{}{
+				"type":        "string",
+				"description": "Optional revision or range to diff against (e.g. HEAD~1, main..feature)",
+			},
+			"paths": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "Limit the diff to these paths",
+			},
+		},
+	}
+}
+
+func (t *GitDiffTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	dir, err := t.repo.repoDir(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	specs, err := t.repo.pathspecs(dir, args)
+	if err != nil {
+		return "", err
+	}
+
+	gitArgs := []string{"diff", "--stat", "--patch"}
+	if getBoolArg(args, "staged", false) {
+		gitArgs = append(gitArgs, "--cached")
+	}
+	if rev := strings.TrimSpace(getStringArg(args, "revision", "")); rev != "" {
+		if err := checkRevision(rev); err != nil {
+			return "", err
+		}
+		gitArgs = append(gitArgs, rev)
+	}
+	gitArgs = append(gitArgs, "--")
+	gitArgs = append(gitArgs, specs...)
+
+	out, err := t.repo.run(ctx, dir, gitArgs...)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(out) == "" {
+		return "No changes", nil
+	}
+	return truncateOutput(out, gitMaxOutputChars), nil
+}
+
+// GitLogTool lists recent commits.
+type GitLogTool struct {
+	repo gitWorkspace
+}
+
+func (t *GitLogTool) Name() string {
+	return "git_log"
+}
+
+func (t *GitLogTool) Description() string {
+	return "List recent commits of a git repository inside the workspace."
+}
+
+func (t *GitLogTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"repo": gitRepoParam(),
+			"max_count": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Number of commits to show (default %d, max %d)", gitDefaultLogCount, gitMaxLogCount),
+			},
+			"revision": map[string]interface{}{
+				"type":        "string",
+				"description": "Optional branch, tag or range to list",
+			},
+			"paths": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "Only show commits touching these paths",
+			},
+		},
+	}
+}
+
+func (t *GitLogTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	dir, err := t.repo.repoDir(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	specs, err := t.repo.pathspecs(dir, args)
+	if err != nil {
+		return "", err
+	}
+
+	count := getIntArg(args, "max_count", gitDefaultLogCount)
+	if count <= 0 {
+		count = gitDefaultLogCount
+	}
+	if count > gitMaxLogCount {
+		count = gitMaxLogCount
+	}
+	gitArgs := []string{"log", fmt.Sprintf("--max-count=%d", count), "--date=short", "--format=%h %ad %an%d %s"}
+	if rev := strings.TrimSpace(getStringArg(args, "revision", "")); rev != "" {
+		if err := checkRevision(rev); err != nil {
+			return "", err
+		}
+		gitArgs = append(gitArgs, rev)
+	}
+	gitArgs = append(gitArgs, "--")
+	gitArgs = append(gitArgs, specs...)
+
+	out, err := t.repo.run(ctx, dir, gitArgs...)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(out) == "" {
+		return "No commits", nil
+	}
+	return truncateOutput(strings.TrimRight(out, "\n"), gitMaxOutputChars), nil
+}
+
+// GitBranchTool lists, creates and switches branches.
+type GitBranchTool struct {
+	repo gitWorkspace
+}
+
+func (t *GitBranchTool) Name() string {
+	return "git_branch"
+}
+
+func (t *GitBranchTool) Description() string {
+	return "List, create or switch branches of a git repository inside the workspace."
+}
+
+func (t *GitBranchTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"repo": gitRepoParam(),
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"list", "create", "switch"},
+				"description": "list (default), create a branch, or switch to one",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Branch name (for create and switch)",
+			},
+			"start_point": map[string]interface{}{
+				"type":        "string",
+				"description": "Revision the new branch starts from (create only, default HEAD)",
+			},
+		},
+	}
+}
+
+func (t *GitBranchTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	dir, err := t.repo.repoDir(ctx, args)
+	if err != nil {
+		return "", err
+	}
+
+	action := strings.ToLower(strings.TrimSpace(getStringArg(args, "action", "list")))
+	if action == "list" || action == "" {
+		out, err := t.repo.run(ctx, dir, "branch", "--list", "--verbose")
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(out) == "" {
+			return "No branches yet", nil
+		}
+		return strings.TrimRight(out, "\n"), nil
+	}
+
+	name := strings.TrimSpace(getStringArg(args, "name", ""))
+	if name == "" {
+		return "", fmt.Errorf("name is required for %s", action)
+	}
+	if _, err := t.repo.run(ctx, dir, "check-ref-format", "--branch", name); err != nil || strings.HasPrefix(name, "-") {
+		return "", fmt.Errorf("invalid branch name: %s", name)
+	}
+
+	switch action {
+	case "create":
+		gitArgs := []string{"branch", name}
+		if start := strings.TrimSpace(getStringArg(args, "start_point", "")); start != "" {
+			if err := checkRevision(start); err != nil {
+				return "", err
+			}
+			gitArgs = append(gitArgs, start)
+		}
+		if _, err := t.repo.run(ctx, dir, gitArgs...); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Created branch %s", name), nil
+	case "switch":
+		if _, err := t.repo.run(ctx, dir, "switch", name); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Switched to branch %s", name), nil
+	default:
+		return "", fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+// GitCommitTool stages files and records a commit. It is listed in
+// approval.always_prompt by default, so "prompt" mode shows the message
+// for confirmation even when the tool is allowlisted.
+type GitCommitTool struct {
+	repo gitWorkspace
+}
+
+func (t *GitCommitTool) Name() string {
+	return "git_commit"
+}
+
+func (t *GitCommitTool) Description() string {
+	return "Commit changes in a git repository inside the workspace. Stages the given paths (or all changes with all=true) first; " +
+		"without either, commits what is already staged."
+}
+
+func (t *GitCommitTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"repo": gitRepoParam(),
+			"message": map[string]interface{}{
+				"type":        "string",
+				"description": "Commit message",
+			},
+			"paths": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "Paths to stage before committing",
+			},
+			"all": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Stage all changes, including new and deleted files",
+			},
+		},
+		"required": []string{"message"},
+	}
+}
+
+func (t *GitCommitTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	message := strings.TrimSpace(getStringArg(args, "message", ""))
+	if message == "" {
+		return "", fmt.Errorf("message is required")
+	}
+	dir, err := t.repo.repoDir(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	specs, err := t.repo.pathspecs(dir, args)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case getBoolArg(args, "all", false):
+		if _, err := t.repo.run(ctx, dir, "add", "--all"); err != nil {
+			return "", err
+		}
+	case len(specs) > 0:
+		if _, err := t.repo.run(ctx, dir, append([]string{"add", "--all", "--"}, specs...)...); err != nil {
+			return "", err
+		}
+	}
+
+	staged, err := t.repo.run(ctx, dir, "diff", "--cached", "--name-only")
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(staged) == "" {
+		return "", fmt.Errorf("nothing staged to commit")
+	}
+
+	gitArgs := []string{}
+	if email, _ := t.repo.run(ctx, dir, "config", "user.email"); strings.TrimSpace(email) == "" {
+		gitArgs = append(gitArgs, "-c", "user.name=nekobot", "-c", "user.email=nekobot@localhost")
+	}
+	gitArgs = append(gitArgs, "commit", "--quiet", "--file=-")
+	if _, err := t.repo.runWithInput(ctx, dir, message+"\n", gitArgs...); err != nil {
+		return "", err
+	}
+
+	out, err := t.repo.run(ctx, dir, "log", "-1", "--format=%h %s", "--stat")
+	if err != nil {
+		return "Committed", nil
+	}
+	return "Committed " + strings.TrimRight(out, "\n"), nil
+}
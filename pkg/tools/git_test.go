@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func initGitTestRepo(t *testing.T, dir string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch=main"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+}
+
+func gitTestTool(t *testing.T, workspace, name string) Tool {
+	t.Helper()
+	for _, tool := range NewGitTools(workspace) {
+		if tool.Name() == name {
+			return tool
+		}
+	}
+	t.Fatalf("git tool %s not found", name)
+	return nil
+}
+
+func TestGitToolsCommitAndInspectWorkspaceRepo(t *testing.T) {
+	workspace := t.TempDir()
+	initGitTestRepo(t, workspace)
+	if err := os.WriteFile(filepath.Join(workspace, "notes.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	ctx := context.Background()
+
+	status, err := gitTestTool(t, workspace, "git_status").Execute(ctx, map[string]interface{}{})
+	if err != nil || !strings.Contains(status, "?? notes.md") {
+		t.Fatalf("unexpected status %q (err %v)", status, err)
+	}
+
+	commit := gitTestTool(t, workspace, "git_commit")
+	if _, err := commit.Execute(ctx, map[string]interface{}{"message": "Add notes"}); err == nil {
+		t.Fatal("expected error when nothing is staged")
+	}
+	out, err := commit.Execute(ctx, map[string]interface{}{
+		"message": "Add notes",
+		"paths":   []interface{}{"notes.md"},
+	})
+	if err != nil || !strings.Contains(out, "Add notes") {
+		t.Fatalf("unexpected commit result %q (err %v)", out, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(workspace, "notes.md"), []byte("hello\nworld\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	diff, err := gitTestTool(t, workspace, "git_diff").Execute(ctx, map[string]interface{}{})
+	if err != nil || !strings.Contains(diff, "+world") {
+		t.Fatalf("unexpected diff %q (err %v)", diff, err)
+	}
+
+	branch := gitTestTool(t, workspace, "git_branch")
+	if _, err := branch.Execute(ctx, map[string]interface{}{"action": "create", "name": "--force"}); err == nil {
+		t.Fatal("expected option-like branch name to be rejected")
+	}
+	if _, err := branch.Execute(ctx, map[string]interface{}{"action": "switch", "name": "feature"}); err == nil {
+		t.Fatal("expected switching to a missing branch to fail")
+	}
+	if _, err := branch.Execute(ctx, map[string]interface{}{"action": "create", "name": "feature"}); err != nil {
+		t.Fatalf("create branch: %v", err)
+	}
+	list, err := branch.Execute(ctx, map[string]interface{}{})
+	if err != nil || !strings.Contains(list, "feature") {
+		t.Fatalf("unexpected branch list %q (err %v)", list, err)
+	}
+
+	log, err := gitTestTool(t, workspace, "git_log").Execute(ctx, map[string]interface{}{"max_count": float64(5)})
+	if err != nil || !strings.Contains(log, "Add notes") {
+		t.Fatalf("unexpected log %q (err %v)", log, err)
+	}
+}
+
+func TestGitToolsStayInsideWorkspace(t *testing.T) {
+	outer := t.TempDir()
+	initGitTestRepo(t, outer)
+	workspace := filepath.Join(outer, "workspace")
+	if err := os.MkdirAll(filepath.Join(workspace, "project"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	ctx := context.Background()
+	status := gitTestTool(t, workspace, "git_status")
+
+	// The workspace itself sits inside a repository rooted outside it.
+	if _, err := status.Execute(ctx, map[string]interface{}{}); err == nil || !strings.Contains(err.Error(), "outside the workspace") {
+		t.Fatalf("expected outer repository to be refused, got %v", err)
+	}
+	if _, err := status.Execute(ctx, map[string]interface{}{"repo": ".."}); err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Fatalf("expected repo outside workspace to be refused, got %v", err)
+	}
+
+	initGitTestRepo(t, filepath.Join(workspace, "project"))
+	if _, err := status.Execute(ctx, map[string]interface{}{"repo": "project"}); err != nil {
+		t.Fatalf("expected nested workspace repo to be usable: %v", err)
+	}
+	_, err := gitTestTool(t, workspace, "git_diff").Execute(ctx, map[string]interface{}{
+		"repo":  "project",
+		"paths": []interface{}{"../../secret"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "outside repository") {
+		t.Fatalf("expected path outside repository to be refused, got %v", err)
+	}
+	_, err = gitTestTool(t, workspace, "git_log").Execute(ctx, map[string]interface{}{
+		"repo":     "project",
+		"revision": "--output=/tmp/x",
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid revision") {
+		t.Fatalf("expected option-like revision to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,765 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	k8sDefaultTimeout   = 30 * time.Second
+	k8sDefaultTailLines = 200
+	k8sMaxTailLines     = 2000
+	k8sMaxListItems     = 500
+	// k8sMaxOutputChars bounds object, listing and log output returned to the model.
+	k8sMaxOutputChars = 50000
+	k8sFieldManager   = "nekobot"
+)
+
+// KubernetesOptions configures the k8s_* tools.
+type KubernetesOptions struct {
+	Kubeconfig string // Empty uses the default loading rules, then the in-cluster account
+	Context    string
+	Namespace  string   // Default namespace, empty uses the context's
+	Namespaces []string // Namespaces the tools may access, empty allows all
+	AllowApply bool
+	Timeout    time.Duration
+}
+
+// kubeClients are the API clients the k8s_* tools share.
+type kubeClients struct {
+	core      kubernetes.Interface
+	dynamic   dynamic.Interface
+	mapper    meta.RESTMapper
+	namespace string // Namespace of the kubeconfig context
+}
+
+// kubeResource is a resource argument resolved against the cluster's API.
+type kubeResource struct {
+	gvr        schema.GroupVersionResource
+	kind       string
+	namespaced bool
+}
+
+// kubeCluster connects on first use, so a missing kubeconfig only fails the
+// tool calls instead of startup. Failed connections are retried.
+type kubeCluster struct {
+	opts    KubernetesOptions
+	connect func() (*kubeClients, error)
+
+	mu      sync.Mutex
+	clients *kubeClients
+}
+
+// NewKubernetesTools creates the k8s_get, k8s_describe and k8s_logs tools,
+// plus k8s_apply when opts.AllowApply is set.
+func NewKubernetesTools(opts KubernetesOptions) []Tool {
+	cluster := &kubeCluster{opts: opts}
+	cluster.connect = cluster.dial
+	return newKubernetesTools(cluster)
+}
+
+func newKubernetesTools(cluster *kubeCluster) []Tool {
+	if cluster.opts.Timeout <= 0 {
+		cluster.opts.Timeout = k8sDefaultTimeout
+	}
+	result := []Tool{
+		&K8sGetTool{cluster: cluster},
+		&K8sDescribeTool{cluster: cluster},
+		&K8sLogsTool{cluster: cluster},
+	}
+	if cluster.opts.AllowApply {
+		result = append(result, &K8sApplyTool{cluster: cluster})
+	}
+	return result
+}
+
+func (c *kubeCluster) dial() (*kubeClients, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if c.opts.Kubeconfig != "" {
+		rules.ExplicitPath = c.opts.Kubeconfig
+	}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{
+		CurrentContext: c.opts.Context,
+	})
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	restConfig.UserAgent = "nekobot"
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		namespace = ""
+	}
+
+	core, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating kubernetes client: %w", err)
+	}
+	dyn, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating dynamic client: %w", err)
+	}
+	discovery := memory.NewMemCacheClient(core.Discovery())
+	mapper := restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(discovery), discovery, nil)
+	return &kubeClients{core: core, dynamic: dyn, mapper: mapper, namespace: namespace}, nil
+}
+
+func (c *kubeCluster) get() (*kubeClients, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clients != nil {
+		return c.clients, nil
+	}
+	clients, err := c.connect()
+	if err != nil {
+		return nil, err
+	}
+	c.clients = clients
+	return clients, nil
+}
+
+func (c *kubeCluster) restricted() bool {
+	return len(c.opts.Namespaces) > 0
+}
+
+func (c *kubeCluster) namespaceAllowed(namespace string) bool {
+	if !c.restricted() {
+		return true
+	}
+	for _, allowed := range c.opts.Namespaces {
+		if strings.TrimSpace(allowed) == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// namespace resolves the namespace argument, falling back to the configured
+// and then the context's default namespace.
+func (c *kubeCluster) namespace(clients *kubeClients, requested string) (string, error) {
+	namespace := strings.TrimSpace(requested)
+	if namespace == "" {
+		namespace = strings.TrimSpace(c.opts.Namespace)
+	}
+	if namespace == "" {
+		namespace = clients.namespace
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	if !c.namespaceAllowed(namespace) {
+		return "", fmt.Errorf("access denied: namespace %s is not allowed", namespace)
+	}
+	return namespace, nil
+}
+
+// resolve maps a kubectl-style resource argument ("pods", "deploy",
+// "deployments.apps") to its API resource.
+func (c *kubeCluster) resolve(clients *kubeClients, resource string) (kubeResource, error) {
+	resource = strings.ToLower(strings.TrimSpace(resource))
+	if resource == "" {
+		return kubeResource{}, fmt.Errorf("resource is required")
+	}
+
+	var gvr schema.GroupVersionResource
+	fullySpecified, groupResource := schema.ParseResourceArg(resource)
+	if fullySpecified != nil {
+		if found, err := clients.mapper.ResourceFor(*fullySpecified); err == nil {
+			gvr = found
+		}
+	}
+	if gvr.Empty() {
+		found, err := clients.mapper.ResourceFor(groupResource.WithVersion(""))
+		if err != nil {
+			return kubeResource{}, fmt.Errorf("unknown resource %q: %w", resource, err)
+		}
+		gvr = found
+	}
+	gvk, err := clients.mapper.KindFor(gvr)
+	if err != nil {
+		return kubeResource{}, fmt.Errorf("unknown resource %q: %w", resource, err)
+	}
+	return c.mapping(clients, gvk)
+}
+
+func (c *kubeCluster) mapping(clients *kubeClients, gvk schema.GroupVersionKind) (kubeResource, error) {
+	mapping, err := clients.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return kubeResource{}, fmt.Errorf("unknown kind %s: %w", gvk.Kind, err)
+	}
+	res := kubeResource{
+		gvr:        mapping.Resource,
+		kind:       gvk.Kind,
+		namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+	}
+	// Cluster-scoped objects belong to no namespace, so a namespace
+	// allowlist keeps them out of reach.
+	if !res.namespaced && c.restricted() {
+		return kubeResource{}, fmt.Errorf("access denied: %s is cluster-scoped and tools are limited to namespaces %s",
+			res.gvr.Resource, strings.Join(c.opts.Namespaces, ", "))
+	}
+	return res, nil
+}
+
+func (c *kubeClients) resource(res kubeResource, namespace string) dynamic.ResourceInterface {
+	if res.namespaced {
+		return c.dynamic.Resource(res.gvr).Namespace(namespace)
+	}
+	return c.dynamic.Resource(res.gvr)
+}
+
+// sanitizeKubeObject drops noise and secret values before an object is shown
+// to the model.
+func sanitizeKubeObject(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+	obj.SetManagedFields(nil)
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		// The last applied configuration repeats the object, Secret data included.
+		delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+		obj.SetAnnotations(annotations)
+	}
+	if obj.GetKind() == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			values, ok := obj.Object[field].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for key := range values {
+				values[key] = "<redacted>"
+			}
+		}
+	}
+	return obj
+}
+
+func kubeObjectYAML(obj *unstructured.Unstructured) (string, error) {
+	out, err := yaml.Marshal(sanitizeKubeObject(obj).Object)
+	if err != nil {
+		return "", fmt.Errorf("encoding %s: %w", obj.GetName(), err)
+	}
+	return string(out), nil
+}
+
+// kubeStatus summarizes the status of common kinds for listings.
+func kubeStatus(kind string, obj *unstructured.Unstructured) string {
+	nestedInt := func(path ...string) int64 {
+		value, _, _ := unstructured.NestedInt64(obj.Object, path...)
+		return value
+	}
+	switch kind {
+	case "Pod":
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		statuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "containerStatuses")
+		ready, restarts := 0, int64(0)
+		for _, item := range statuses {
+			status, _ := item.(map[string]interface{})
+			if isReady, _ := status["ready"].(bool); isReady {
+				ready++
+			}
+			restarts += toInt64(status["restartCount"])
+		}
+		return fmt.Sprintf("%s ready:%d/%d restarts:%d", phase, ready, len(statuses), restarts)
+	case "Deployment", "StatefulSet", "ReplicaSet":
+		return fmt.Sprintf("ready:%d/%d", nestedInt("status", "readyReplicas"), nestedInt("spec", "replicas"))
+	case "DaemonSet":
+		return fmt.Sprintf("ready:%d/%d", nestedInt("status", "numberReady"), nestedInt("status", "desiredNumberScheduled"))
+	case "Job":
+		return fmt.Sprintf("succeeded:%d failed:%d", nestedInt("status", "succeeded"), nestedInt("status", "failed"))
+	case "Node":
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		for _, item := range conditions {
+			condition, _ := item.(map[string]interface{})
+			if condition["type"] == "Ready" {
+				if condition["status"] == "True" {
+					return "Ready"
+				}
+				return "NotReady"
+			}
+		}
+		return "Unknown"
+	}
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	return phase
+}
+
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+func kubeAge(ts metav1.Time) string {
+	if ts.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(ts.Time))
+}
+
+func kubeNamespaceParam() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": "Namespace (default: the configured namespace)",
+	}
+}
+
+// K8sGetTool lists or fetches Kubernetes objects.
+type K8sGetTool struct {
+	cluster *kubeCluster
+}
+
+func (t *K8sGetTool) Name() string {
+	return "k8s_get"
+}
+
+func (t *K8sGetTool) Description() string {
+	return "List Kubernetes objects of a resource type with their status, or get one object as YAML when name is set (like kubectl get). Secret values are redacted."
+}
+
+func (t *K8sGetTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"resource": map[string]interface{}{
+				"type":        "string",
+				"description": "Resource type, e.g. pods, deploy, services, nodes, deployments.apps",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Object name; omit to list",
+			},
+			"namespace": kubeNamespaceParam(),
+			"all_namespaces": map[string]interface{}{
+				"type":        "boolean",
+				"description": "List across all namespaces (only when namespaces are not restricted)",
+			},
+			"label_selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Label selector, e.g. app=web,tier!=cache",
+			},
+		},
+		"required": []string{"resource"},
+	}
+}
+
+func (t *K8sGetTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	clients, err := t.cluster.get()
+	if err != nil {
+		return "", err
+	}
+	res, err := t.cluster.resolve(clients, getStringArg(args, "resource", ""))
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, t.cluster.opts.Timeout)
+	defer cancel()
+
+	allNamespaces := getBoolArg(args, "all_namespaces", false) && res.namespaced
+	namespace := metav1.NamespaceAll
+	if allNamespaces {
+		if t.cluster.restricted() {
+			return "", fmt.Errorf("access denied: all_namespaces is not allowed when namespaces are restricted")
+		}
+	} else if res.namespaced {
+		if namespace, err = t.cluster.namespace(clients, getStringArg(args, "namespace", "")); err != nil {
+			return "", err
+		}
+	}
+
+	if name := strings.TrimSpace(getStringArg(args, "name", "")); name != "" {
+		obj, err := clients.resource(res, namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("getting %s %s: %w", res.gvr.Resource, name, err)
+		}
+		out, err := kubeObjectYAML(obj)
+		if err != nil {
+			return "", err
+		}
+		return truncateOutput(out, k8sMaxOutputChars), nil
+	}
+
+	list, err := clients.resource(res, namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: strings.TrimSpace(getStringArg(args, "label_selector", "")),
+		Limit:         k8sMaxListItems,
+	})
+	if err != nil {
+		return "", fmt.Errorf("listing %s: %w", res.gvr.Resource, err)
+	}
+	if len(list.Items) == 0 {
+		if res.namespaced && !allNamespaces {
+			return fmt.Sprintf("No %s found in namespace %s.", res.gvr.Resource, namespace), nil
+		}
+		return fmt.Sprintf("No %s found.", res.gvr.Resource), nil
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		if list.Items[i].GetNamespace() != list.Items[j].GetNamespace() {
+			return list.Items[i].GetNamespace() < list.Items[j].GetNamespace()
+		}
+		return list.Items[i].GetName() < list.Items[j].GetName()
+	})
+
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	if allNamespaces {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tSTATUS\tAGE")
+	for i := range list.Items {
+		item := &list.Items[i]
+		if allNamespaces {
+			fmt.Fprintf(w, "%s\t", item.GetNamespace())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", item.GetName(), kubeStatus(res.kind, item), kubeAge(item.GetCreationTimestamp()))
+	}
+	_ = w.Flush()
+	if list.GetContinue() != "" {
+		fmt.Fprintf(&sb, "... (only the first %d shown, narrow with label_selector)\n", k8sMaxListItems)
+	}
+	return truncateOutput(sb.String(), k8sMaxOutputChars), nil
+}
+
+// K8sDescribeTool shows one object with its recent events.
+type K8sDescribeTool struct {
+	cluster *kubeCluster
+}
+
+func (t *K8sDescribeTool) Name() string {
+	return "k8s_describe"
+}
+
+func (t *K8sDescribeTool) Description() string {
+	return "Describe a Kubernetes object: its spec and status as YAML followed by its recent events (like kubectl describe). Use it to find why a pod or rollout is failing."
+}
+
+func (t *K8sDescribeTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"resource": map[string]interface{}{
+				"type":        "string",
+				"description": "Resource type, e.g. pod, deploy, node",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Object name",
+			},
+			"namespace": kubeNamespaceParam(),
+		},
+		"required": []string{"resource", "name"},
+	}
+}
+
+func (t *K8sDescribeTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	name := strings.TrimSpace(getStringArg(args, "name", ""))
+	if name == "" {
+		return "", fmt.Errorf("name is required")
+	}
+	clients, err := t.cluster.get()
+	if err != nil {
+		return "", err
+	}
+	res, err := t.cluster.resolve(clients, getStringArg(args, "resource", ""))
+	if err != nil {
+		return "", err
+	}
+	namespace := metav1.NamespaceAll
+	if res.namespaced {
+		if namespace, err = t.cluster.namespace(clients, getStringArg(args, "namespace", "")); err != nil {
+			return "", err
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, t.cluster.opts.Timeout)
+	defer cancel()
+
+	obj, err := clients.resource(res, namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("getting %s %s: %w", res.gvr.Resource, name, err)
+	}
+	out, err := kubeObjectYAML(obj)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s", res.kind, name)
+	if res.namespaced {
+		fmt.Fprintf(&sb, " (namespace %s)", namespace)
+	}
+	sb.WriteString("\n\n")
+	sb.WriteString(out)
+	sb.WriteString("\nEvents:\n")
+
+	events, err := clients.core.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.name", name).String(),
+	})
+	if err != nil {
+		fmt.Fprintf(&sb, "  (unavailable: %v)\n", err)
+		return truncateOutput(sb.String(), k8sMaxOutputChars), nil
+	}
+	var related []corev1.Event
+	for _, event := range events.Items {
+		if event.InvolvedObject.Name == name && event.InvolvedObject.Kind == res.kind {
+			related = append(related, event)
+		}
+	}
+	if len(related) == 0 {
+		sb.WriteString("  <none>\n")
+		return truncateOutput(sb.String(), k8sMaxOutputChars), nil
+	}
+	sort.Slice(related, func(i, j int) bool {
+		return related[i].LastTimestamp.Before(&related[j].LastTimestamp)
+	})
+	w := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "  TYPE\tREASON\tAGE\tCOUNT\tMESSAGE")
+	for _, event := range related {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%d\t%s\n", event.Type, event.Reason, kubeAge(event.LastTimestamp), event.Count, strings.TrimSpace(event.Message))
+	}
+	_ = w.Flush()
+	return truncateOutput(sb.String(), k8sMaxOutputChars), nil
+}
+
+// K8sLogsTool reads container logs of a pod.
+type K8sLogsTool struct {
+	cluster *kubeCluster
+}
+
+func (t *K8sLogsTool) Name() string {
+	return "k8s_logs"
+}
+
+func (t *K8sLogsTool) Description() string {
+	return "Read the most recent log lines of a pod's container (like kubectl logs). Set previous to read the logs of the last crashed container."
+}
+
+func (t *K8sLogsTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"pod": map[string]interface{}{
+				"type":        "string",
+				"description": "Pod name",
+			},
+			"container": map[string]interface{}{
+				"type":        "string",
+				"description": "Container name (required for pods with several containers)",
+			},
+			"namespace": kubeNamespaceParam(),
+			"tail_lines": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Number of lines from the end (default %d, max %d)", k8sDefaultTailLines, k8sMaxTailLines),
+			},
+			"since_seconds": map[string]interface{}{
+				"type":        "integer",
+				"description": "Only return logs newer than this many seconds",
+			},
+			"previous": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Read the previous terminated container's logs",
+			},
+		},
+		"required": []string{"pod"},
+	}
+}
+
+func (t *K8sLogsTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	pod := strings.TrimSpace(getStringArg(args, "pod", ""))
+	if pod == "" {
+		return "", fmt.Errorf("pod is required")
+	}
+	clients, err := t.cluster.get()
+	if err != nil {
+		return "", err
+	}
+	namespace, err := t.cluster.namespace(clients, getStringArg(args, "namespace", ""))
+	if err != nil {
+		return "", err
+	}
+
+	tail := int64(getIntArg(args, "tail_lines", k8sDefaultTailLines))
+	if tail <= 0 {
+		tail = k8sDefaultTailLines
+	}
+	if tail > k8sMaxTailLines {
+		tail = k8sMaxTailLines
+	}
+	opts := &corev1.PodLogOptions{
+		Container: strings.TrimSpace(getStringArg(args, "container", "")),
+		TailLines: &tail,
+		Previous:  getBoolArg(args, "previous", false),
+	}
+	if since := int64(getIntArg(args, "since_seconds", 0)); since > 0 {
+		opts.SinceSeconds = &since
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.cluster.opts.Timeout)
+	defer cancel()
+	data, err := clients.core.CoreV1().Pods(namespace).GetLogs(pod, opts).DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("reading logs of %s: %w", pod, err)
+	}
+	out := string(data)
+	if strings.TrimSpace(out) == "" {
+		return fmt.Sprintf("No log output from pod %s.", pod), nil
+	}
+	// Keep the end of the logs, which is where failures show up.
+	if len(out) > k8sMaxOutputChars {
+		out = fmt.Sprintf("... (truncated, %d earlier bytes)\n", len(out)-k8sMaxOutputChars) + out[len(out)-k8sMaxOutputChars:]
+	}
+	return out, nil
+}
+
+// K8sApplyTool server-side applies manifests. It is registered only when
+// tools.kubernetes.allow_apply is set and needs an approval in every mode.
+type K8sApplyTool struct {
+	cluster *kubeCluster
+}
+
+func (t *K8sApplyTool) Name() string {
+	return "k8s_apply"
+}
+
+// RequiresApproval keeps changes to the cluster from running unattended,
+// even in auto mode.
+func (t *K8sApplyTool) RequiresApproval() bool {
+	return true
+}
+
+func (t *K8sApplyTool) Description() string {
+	return "Create or update Kubernetes objects from a YAML or JSON manifest using server-side apply (like kubectl apply --server-side). " +
+		"Multiple documents separated by --- are applied in order. Use dry_run first to validate changes."
+}
+
+func (t *K8sApplyTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"manifest": map[string]interface{}{
+				"type":        "string",
+				"description": "YAML or JSON manifest, may contain several documents",
+			},
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Namespace for objects that do not set one (default: the configured namespace)",
+			},
+			"dry_run": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Validate on the server without persisting changes",
+			},
+		},
+		"required": []string{"manifest"},
+	}
+}
+
+func (t *K8sApplyTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	manifest := getStringArg(args, "manifest", "")
+	if strings.TrimSpace(manifest) == "" {
+		return "", fmt.Errorf("manifest is required")
+	}
+	clients, err := t.cluster.get()
+	if err != nil {
+		return "", err
+	}
+
+	type applyTarget struct {
+		obj *unstructured.Unstructured
+		res kubeResource
+	}
+	var targets []applyTarget
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+	for {
+		var doc map[string]interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return "", fmt.Errorf("parsing manifest: %w", err)
+		}
+		if len(doc) == 0 {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: doc}
+		gvk := obj.GroupVersionKind()
+		if gvk.Kind == "" || gvk.Version == "" {
+			return "", fmt.Errorf("manifest document %d is missing apiVersion or kind", len(targets)+1)
+		}
+		if obj.GetName() == "" {
+			return "", fmt.Errorf("%s in manifest document %d has no metadata.name", gvk.Kind, len(targets)+1)
+		}
+		res, err := t.cluster.mapping(clients, gvk)
+		if err != nil {
+			return "", err
+		}
+		if res.namespaced {
+			requested := obj.GetNamespace()
+			if requested == "" {
+				requested = getStringArg(args, "namespace", "")
+			}
+			namespace, err := t.cluster.namespace(clients, requested)
+			if err != nil {
+				return "", fmt.Errorf("%s/%s: %w", gvk.Kind, obj.GetName(), err)
+			}
+			obj.SetNamespace(namespace)
+		}
+		targets = append(targets, applyTarget{obj: obj, res: res})
+	}
+	if len(targets) == 0 {
+		return "", fmt.Errorf("manifest contains no objects")
+	}
+
+	opts := metav1.PatchOptions{FieldManager: k8sFieldManager}
+	dryRun := getBoolArg(args, "dry_run", false)
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	ctx, cancel := context.WithTimeout(ctx, t.cluster.opts.Timeout)
+	defer cancel()
+
+	var sb strings.Builder
+	for i, target := range targets {
+		data, err := target.obj.MarshalJSON()
+		if err != nil {
+			return "", fmt.Errorf("encoding %s/%s: %w", target.obj.GetKind(), target.obj.GetName(), err)
+		}
+		ref := strings.ToLower(target.obj.GetKind()) + "/" + target.obj.GetName()
+		if target.res.namespaced {
+			ref += " (namespace " + target.obj.GetNamespace() + ")"
+		}
+		if _, err := clients.resource(target.res, target.obj.GetNamespace()).Patch(ctx, target.obj.GetName(), types.ApplyPatchType, data, opts); err != nil {
+			return "", fmt.Errorf("applying %s: %w (%d of %d objects applied before it)", ref, err, i, len(targets))
+		}
+		if dryRun {
+			fmt.Fprintf(&sb, "%s: validated (server dry run)\n", ref)
+		} else {
+			fmt.Fprintf(&sb, "%s: applied\n", ref)
+		}
+	}
+	return sb.String(), nil
+}
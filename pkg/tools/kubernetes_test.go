@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestKubeTools(t *testing.T, opts KubernetesOptions) map[string]Tool {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "prod"},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "web", Ready: true, RestartCount: 3}},
+		},
+	}
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "prod"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web-1.1", Namespace: "prod"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-1", Namespace: "prod"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		Count:          4,
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Pod"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Node"), meta.RESTScopeRoot)
+	clients := &kubeClients{
+		core:    kubefake.NewClientset(pod, event),
+		dynamic: dynamicfake.NewSimpleDynamicClient(scheme, pod, secret),
+		mapper:  mapper,
+	}
+
+	cluster := &kubeCluster{opts: opts, connect: func() (*kubeClients, error) { return clients, nil }}
+	byName := make(map[string]Tool)
+	for _, tool := range newKubernetesTools(cluster) {
+		byName[tool.Name()] = tool
+	}
+	return byName
+}
+
+func TestKubernetesToolsReadClusterState(t *testing.T) {
+	tools := newTestKubeTools(t, KubernetesOptions{Namespace: "prod"})
+	ctx := context.Background()
+
+	list, err := tools["k8s_get"].Execute(ctx, map[string]interface{}{"resource": "pods"})
+	if err != nil || !strings.Contains(list, "web-1") || !strings.Contains(list, "Running ready:1/1 restarts:3") {
+		t.Fatalf("unexpected pod listing %q (err %v)", list, err)
+	}
+
+	secret, err := tools["k8s_get"].Execute(ctx, map[string]interface{}{"resource": "secret", "name": "db"})
+	if err != nil || !strings.Contains(secret, "password: <redacted>") || strings.Contains(secret, "aHVudGVyMg") {
+		t.Fatalf("expected secret data to be redacted, got %q (err %v)", secret, err)
+	}
+
+	described, err := tools["k8s_describe"].Execute(ctx, map[string]interface{}{"resource": "pod", "name": "web-1"})
+	if err != nil || !strings.Contains(described, "Back-off restarting failed container") {
+		t.Fatalf("expected pod events in description, got %q (err %v)", described, err)
+	}
+
+	logs, err := tools["k8s_logs"].Execute(ctx, map[string]interface{}{"pod": "web-1"})
+	if err != nil || !strings.Contains(logs, "fake logs") {
+		t.Fatalf("unexpected logs %q (err %v)", logs, err)
+	}
+
+	if _, ok := tools["k8s_apply"]; ok {
+		t.Fatal("k8s_apply should only be registered when allow_apply is set")
+	}
+}
+
+func TestKubernetesToolsEnforceNamespaceAllowlist(t *testing.T) {
+	tools := newTestKubeTools(t, KubernetesOptions{Namespace: "prod", Namespaces: []string{"prod"}, AllowApply: true})
+	ctx := context.Background()
+
+	if _, err := tools["k8s_get"].Execute(ctx, map[string]interface{}{"resource": "pods", "namespace": "kube-system"}); err == nil {
+		t.Fatal("expected namespace outside the allowlist to be denied")
+	}
+	if _, err := tools["k8s_get"].Execute(ctx, map[string]interface{}{"resource": "pods", "all_namespaces": true}); err == nil {
+		t.Fatal("expected all_namespaces to be denied with an allowlist")
+	}
+	if _, err := tools["k8s_get"].Execute(ctx, map[string]interface{}{"resource": "nodes"}); err == nil {
+		t.Fatal("expected cluster-scoped resources to be denied with an allowlist")
+	}
+
+	manifest := "apiVersion: v1\nkind: Secret\nmetadata:\n  name: leaked\n  namespace: kube-system\n"
+	_, err := tools["k8s_apply"].Execute(ctx, map[string]interface{}{"manifest": manifest})
+	if err == nil || !strings.Contains(err.Error(), "namespace kube-system is not allowed") {
+		t.Fatalf("expected apply outside the allowlist to be denied, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ListToolsTool lets the agent discover tools omitted from the system prompt.
+type ListToolsTool struct {
+	registry *Registry
+}
+
+// NewListToolsTool creates a list_tools tool backed by the given registry.
+func NewListToolsTool(registry *Registry) *ListToolsTool {
+	return &ListToolsTool{registry: registry}
+}
+
+// Name returns the tool name.
+func (t *ListToolsTool) Name() string {
+	return "list_tools"
+}
+
+// Description returns the tool description.
+func (t *ListToolsTool) Description() string {
+	return "List every available tool with its description. Use this when the system prompt notes hidden tools or you need a capability that is not listed."
+}
+
+// Parameters returns the tool parameter schema.
+func (t *ListToolsTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query": map[string]interface{}{
+				"type":        "string",
+				"description": "Optional case-insensitive filter matched against tool names and descriptions.",
+			},
+		},
+	}
+}
+
+// Execute lists registered tools, optionally filtered by query.
+func (t *ListToolsTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	_ = ctx
+	if t == nil || t.registry == nil {
+		return "", fmt.Errorf("list_tools tool not initialized")
+	}
+
+	query := ""
+	if raw, ok := args["query"].(string); ok {
+		query = strings.ToLower(strings.TrimSpace(raw))
+	}
+
+	var sb strings.Builder
+	matched := 0
+	for _, desc := range t.registry.GetDescriptions() {
+		if query != "" && !strings.Contains(strings.ToLower(desc), query) {
+			continue
+		}
+		matched++
+		sb.WriteString("- ")
+		sb.WriteString(desc)
+		sb.WriteString("\n")
+	}
+	if matched == 0 {
+		if query != "" {
+			return fmt.Sprintf("No tools match %q.", query), nil
+		}
+		return "No tools available.", nil
+	}
+	return fmt.Sprintf("Available tools (%d):\n%s", matched, sb.String()), nil
+}
@@ -0,0 +1,29 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestListToolsToolListsAndFiltersRegistry(t *testing.T) {
+	registry := NewRegistry()
+	registry.MustRegister(NewListToolsTool(registry))
+	registry.MustRegister(NewWikiQueryTool(t.TempDir()))
+
+	out, err := registry.Execute(context.Background(), "list_tools", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("list_tools failed: %v", err)
+	}
+	if !strings.Contains(out, "Available tools (2)") || !strings.Contains(out, "**wiki_query**") {
+		t.Fatalf("unexpected list_tools output: %q", out)
+	}
+
+	out, err = registry.Execute(context.Background(), "list_tools", map[string]interface{}{"query": "WIKI"})
+	if err != nil {
+		t.Fatalf("list_tools with query failed: %v", err)
+	}
+	if !strings.Contains(out, "Available tools (1)") || strings.Contains(out, "**list_tools**") {
+		t.Fatalf("unexpected filtered output: %q", out)
+	}
+}
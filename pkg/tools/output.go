@@ -0,0 +1,20 @@
+package tools
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// truncateOutput cuts tool output to at most limit bytes, noting how much was
+// left out. The cut backs off to a rune boundary, so multi-byte text is not
+// split mid-character.
+func truncateOutput(out string, limit int) string {
+	if len(out) <= limit {
+		return out
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(out[cut]) {
+		cut--
+	}
+	return out[:cut] + fmt.Sprintf("\n... (truncated, %d more bytes)", len(out)-cut)
+}
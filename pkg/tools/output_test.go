@@ -0,0 +1,25 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateOutputKeepsRunesWhole(t *testing.T) {
+	if got := truncateOutput("short", 10); got != "short" {
+		t.Fatalf("expected output under the limit unchanged, got %q", got)
+	}
+
+	// Each "界" is three bytes, so a limit of 4 falls inside the second one.
+	got := truncateOutput("世界世界", 4)
+	if !utf8.ValidString(got) {
+		t.Fatalf("expected valid UTF-8, got %q", got)
+	}
+	if !strings.HasPrefix(got, "世\n") {
+		t.Fatalf("expected the cut before the split rune, got %q", got)
+	}
+	if !strings.HasSuffix(got, "(truncated, 9 more bytes)") {
+		t.Fatalf("expected the omitted byte count, got %q", got)
+	}
+}
@@ -13,6 +13,13 @@ import (
 type ExecutionHook func(ctx context.Context, toolName string, args map[string]interface{}, result string, duration time.Duration, err error)
 type BeforeExecutionHook func(ctx context.Context, toolName string, args map[string]interface{})
 
+// Guard vets tool calls centrally. Check runs before the tool and can refuse
+// the call; Filter runs after a successful call and can rewrite its result.
+type Guard interface {
+	Check(ctx context.Context, toolName string, args map[string]interface{}) error
+	Filter(ctx context.Context, toolName string, args map[string]interface{}, result string) string
+}
+
 // Tool represents an action that the agent can perform.
 type Tool interface {
 	// Name returns the unique name of the tool.
@@ -28,12 +35,19 @@ type Tool interface {
 	Execute(ctx context.Context, args map[string]interface{}) (string, error)
 }
 
+// ApprovalRequired is implemented by tools that must not run without an
+// approval, whatever the approval mode.
+type ApprovalRequired interface {
+	RequiresApproval() bool
+}
+
 // Registry manages available tools for the agent.
 type Registry struct {
 	mu         sync.RWMutex
 	tools      map[string]Tool
 	beforeHook BeforeExecutionHook
 	hook       ExecutionHook // Optional execution hook for auditing/logging
+	guards     []Guard
 }
 
 // NewRegistry creates a new tool registry.
@@ -73,6 +87,13 @@ func (r *Registry) Replace(tool Tool) {
 	r.tools[tool.Name()] = tool
 }
 
+// Unregister removes a tool. Removing an unknown tool is a no-op.
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tools, name)
+}
+
 // RegisterOrReplace registers a tool, replacing an existing one with the same name.
 func (r *Registry) RegisterOrReplace(tool Tool) error {
 	r.mu.Lock()
@@ -158,7 +179,25 @@ func (r *Registry) Execute(ctx context.Context, name string, args map[string]int
 	if r.beforeHook != nil {
 		r.beforeHook(ctx, name, args)
 	}
-	result, err := tool.Execute(ctx, args)
+	r.mu.RLock()
+	guards := r.guards
+	r.mu.RUnlock()
+
+	var result string
+	var err error
+	for _, guard := range guards {
+		if err = guard.Check(ctx, name, args); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		result, err = tool.Execute(ctx, args)
+	}
+	if err == nil {
+		for _, guard := range guards {
+			result = guard.Filter(ctx, name, args, result)
+		}
+	}
 	duration := time.Since(start)
 
 	// Call hook if registered
@@ -183,3 +222,10 @@ func (r *Registry) SetBeforeHook(hook BeforeExecutionHook) {
 	defer r.mu.Unlock()
 	r.beforeHook = hook
 }
+
+// AddGuard adds a guard that every tool execution passes through.
+func (r *Registry) AddGuard(guard Guard) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.guards = append(r.guards, guard)
+}
@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"nekobot/pkg/background"
+)
+
+// RunBackgroundTool starts long commands or agent jobs that report back to
+// the conversation when they finish.
+type RunBackgroundTool struct {
+	workspace string
+	restrict  bool
+	jobs      *background.Manager
+}
+
+type backgroundContextKey string
+
+const (
+	backgroundContextChannelKey backgroundContextKey = "channel"
+	backgroundContextChatKey    backgroundContextKey = "chat"
+)
+
+// NewRunBackgroundTool creates a new run_background tool.
+func NewRunBackgroundTool(workspace string, restrict bool, jobs *background.Manager) *RunBackgroundTool {
+	return &RunBackgroundTool{
+		workspace: workspace,
+		restrict:  restrict,
+		jobs:      jobs,
+	}
+}
+
+// WithBackgroundContext stores the conversation route job notifications go to.
+func WithBackgroundContext(ctx context.Context, channel, chatID string) context.Context {
+	ctx = context.WithValue(ctx, backgroundContextChannelKey, strings.TrimSpace(channel))
+	return context.WithValue(ctx, backgroundContextChatKey, strings.TrimSpace(chatID))
+}
+
+func (t *RunBackgroundTool) Name() string {
+	return "run_background"
+}
+
+func (t *RunBackgroundTool) Description() string {
+	return "Start a long-running shell command or agent task in the background and return a job ID immediately. " +
+		"The user is notified in this chat with progress and when the job finishes, so do not poll it. " +
+		"Use it for builds, test suites, downloads or research that would take more than a minute. The user can list and cancel jobs with /tasks."
+}
+
+func (t *RunBackgroundTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"command": map[string]interface{}{
+				"type":        "string",
+				"description": "Shell command to run (set either command or task)",
+			},
+			"task": map[string]interface{}{
+				"type":        "string",
+				"description": "Task for a background agent (set either command or task)",
+			},
+			"label": map[string]interface{}{
+				"type":        "string",
+				"description": "Short name shown in notifications and /tasks",
+			},
+			"workdir": map[string]interface{}{
+				"type":        "string",
+				"description": "Working directory for command jobs (relative to workspace). Default: workspace root",
+			},
+		},
+	}
+}
+
+func (t *RunBackgroundTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	if t.jobs == nil {
+		return "", fmt.Errorf("background jobs are not available")
+	}
+	command := strings.TrimSpace(getStringArg(args, "command", ""))
+	task := strings.TrimSpace(getStringArg(args, "task", ""))
+	label := strings.TrimSpace(getStringArg(args, "label", ""))
+	if (command == "") == (task == "") {
+		return "", fmt.Errorf("exactly one of command or task is required")
+	}
+	channel, chatID := backgroundRouteFromContext(ctx)
+
+	if task != "" {
+		job, err := t.jobs.StartAgent(ctx, task, label, channel, chatID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Background agent job started\nJob ID: %s\nLabel: %s\n\nThe result will be posted to this chat when it finishes.", job.ID, job.Label), nil
+	}
+
+	if t.restrict {
+		if err := checkDangerousCommand(command); err != nil {
+			return "", err
+		}
+	}
+	workspace := WorkspaceFor(ctx, t.workspace)
+	workdir := strings.TrimSpace(getStringArg(args, "workdir", ""))
+	if workdir == "" {
+		workdir = workspace
+	} else if !filepath.IsAbs(workdir) {
+		workdir = filepath.Join(workspace, workdir)
+	}
+	if t.restrict && !pathWithin(workspace, filepath.Clean(workdir)) {
+		return "", fmt.Errorf("access denied: workdir outside workspace")
+	}
+
+	job, err := t.jobs.StartCommand(background.CommandSpec{
+		Command: command,
+		Workdir: workdir,
+		Label:   label,
+		Channel: channel,
+		ChatID:  chatID,
+	})
+	if err != nil {
+		return "", err
+	}
+	notice := "Progress and the result will be posted to this chat."
+	if channel == "" || chatID == "" {
+		notice = "No chat is attached, so check on it with the process tool."
+	}
+	return fmt.Sprintf("Background job started\nJob ID: %s\nLabel: %s\nWorkdir: %s\n\n%s Its output can also be read with process action:log sessionId:%s",
+		job.ID, job.Label, workdir, notice, job.ID), nil
+}
+
+func backgroundRouteFromContext(ctx context.Context) (string, string) {
+	if ctx == nil {
+		return "", ""
+	}
+	channel, _ := ctx.Value(backgroundContextChannelKey).(string)
+	chatID, _ := ctx.Value(backgroundContextChatKey).(string)
+	return channel, chatID
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SendFileFunc delivers a local file to the conversation chatID on channel.
+type SendFileFunc func(ctx context.Context, channel, chatID, path, caption string) error
+
+// SendFileTool lets the agent send a workspace file to the user as an
+// attachment on the channel the current conversation came from.
+type SendFileTool struct {
+	workspace string
+	send      SendFileFunc
+}
+
+type sendFileContextKey string
+
+const (
+	sendFileContextChannelKey sendFileContextKey = "channel"
+	sendFileContextChatKey    sendFileContextKey = "chat"
+)
+
+// NewSendFileTool creates a new send_file tool.
+func NewSendFileTool(workspace string, send SendFileFunc) *SendFileTool {
+	return &SendFileTool{
+		workspace: workspace,
+		send:      send,
+	}
+}
+
+// WithSendFileContext stores the conversation route files are delivered to.
+func WithSendFileContext(ctx context.Context, channel, chatID string) context.Context {
+	ctx = context.WithValue(ctx, sendFileContextChannelKey, strings.TrimSpace(channel))
+	return context.WithValue(ctx, sendFileContextChatKey, strings.TrimSpace(chatID))
+}
+
+func (t *SendFileTool) Name() string {
+	return "send_file"
+}
+
+func (t *SendFileTool) Description() string {
+	return "Send a file from the workspace to the user as an attachment (images are shown inline where the channel supports it). " +
+		"Use this to deliver generated reports, exports, or images. Only files inside the workspace can be sent."
+}
+
+func (t *SendFileTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Path to the file (absolute or relative to workspace)",
+			},
+			"caption": map[string]interface{}{
+				"type":        "string",
+				"description": "Optional short text sent with the file",
+			},
+		},
+		"required": []string{"path"},
+	}
+}
+
+func (t *SendFileTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	pathArg, _ := args["path"].(string)
+	if strings.TrimSpace(pathArg) == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	caption, _ := args["caption"].(string)
+
+	path, err := t.resolveWorkspaceFile(ctx, pathArg)
+	if err != nil {
+		return "", err
+	}
+
+	channel, chatID := sendFileRouteFromContext(ctx)
+	if channel == "" || chatID == "" {
+		return "", fmt.Errorf("no active channel conversation to send the file to")
+	}
+	if t.send == nil {
+		return "", fmt.Errorf("file sending is not available")
+	}
+	if err := t.send(ctx, channel, chatID, path, strings.TrimSpace(caption)); err != nil {
+		return "", fmt.Errorf("failed to send file: %w", err)
+	}
+
+	return fmt.Sprintf("Sent %s to the user", filepath.Base(path)), nil
+}
+
+// resolveWorkspaceFile returns the absolute, symlink-free path of a regular
+// file inside the workspace of ctx. Sending is always confined to the
+// workspace, independent of restrict_to_workspace, since the file leaves
+// the host.
+func (t *SendFileTool) resolveWorkspaceFile(ctx context.Context, pathArg string) (string, error) {
+	workspace, err := filepath.Abs(WorkspaceFor(ctx, t.workspace))
+	if err != nil {
+		return "", fmt.Errorf("invalid workspace: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(workspace); err == nil {
+		workspace = resolved
+	}
+
+	path := strings.TrimSpace(pathArg)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workspace, path)
+	}
+	path, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("file not found: %s", pathArg)
+		}
+		return "", fmt.Errorf("invalid path: %w", err)
+	}
+
+	rel, err := filepath.Rel(workspace, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("access denied: path outside workspace")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat file: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("not a regular file: %s", pathArg)
+	}
+	return path, nil
+}
+
+func sendFileRouteFromContext(ctx context.Context) (string, string) {
+	if ctx == nil {
+		return "", ""
+	}
+	channel, _ := ctx.Value(sendFileContextChannelKey).(string)
+	chatID, _ := ctx.Value(sendFileContextChatKey).(string)
+	return channel, chatID
+}
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type sentFile struct {
+	channel, chatID, path, caption string
+}
+
+func newSendFileTestTool(t *testing.T) (*SendFileTool, string, *[]sentFile) {
+	t.Helper()
+
+	workspace := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(workspace, "reports"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workspace, "reports", "weekly.pdf"), []byte("%PDF"), 0o644); err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+
+	var sent []sentFile
+	tool := NewSendFileTool(workspace, func(ctx context.Context, channel, chatID, path, caption string) error {
+		sent = append(sent, sentFile{channel: channel, chatID: chatID, path: path, caption: caption})
+		return nil
+	})
+	return tool, workspace, &sent
+}
+
+func TestSendFileToolSendsWorkspaceFileToContextRoute(t *testing.T) {
+	tool, workspace, sent := newSendFileTestTool(t)
+	ctx := WithSendFileContext(context.Background(), "telegram", "telegram:42")
+
+	result, err := tool.Execute(ctx, map[string]interface{}{
+		"path":    "reports/weekly.pdf",
+		"caption": " Weekly report ",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !strings.Contains(result, "weekly.pdf") {
+		t.Fatalf("unexpected result: %q", result)
+	}
+
+	wantPath, err := filepath.EvalSymlinks(filepath.Join(workspace, "reports", "weekly.pdf"))
+	if err != nil {
+		t.Fatalf("eval symlinks: %v", err)
+	}
+	if len(*sent) != 1 {
+		t.Fatalf("expected one upload, got %d", len(*sent))
+	}
+	got := (*sent)[0]
+	if got.channel != "telegram" || got.chatID != "telegram:42" || got.path != wantPath || got.caption != "Weekly report" {
+		t.Fatalf("unexpected upload: %+v", got)
+	}
+}
+
+func TestSendFileToolRejectsPathsOutsideWorkspace(t *testing.T) {
+	tool, workspace, sent := newSendFileTestTool(t)
+	ctx := WithSendFileContext(context.Background(), "telegram", "telegram:42")
+
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0o644); err != nil {
+		t.Fatalf("write outside file: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(workspace, "link.txt")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	for _, path := range []string{outside, "../" + filepath.Base(filepath.Dir(outside)) + "/secret.txt", "link.txt"} {
+		_, err := tool.Execute(ctx, map[string]interface{}{"path": path})
+		if err == nil {
+			t.Fatalf("expected %q to be rejected", path)
+		}
+	}
+	if _, err := tool.Execute(ctx, map[string]interface{}{"path": "reports"}); err == nil || !strings.Contains(err.Error(), "not a regular file") {
+		t.Fatalf("expected directory to be rejected, got %v", err)
+	}
+	if len(*sent) != 0 {
+		t.Fatalf("expected no uploads, got %+v", *sent)
+	}
+}
+
+func TestSendFileToolRequiresConversationRoute(t *testing.T) {
+	tool, _, sent := newSendFileTestTool(t)
+
+	_, err := tool.Execute(context.Background(), map[string]interface{}{"path": "reports/weekly.pdf"})
+	if err == nil || !strings.Contains(err.Error(), "no active channel") {
+		t.Fatalf("expected missing route error, got %v", err)
+	}
+	if len(*sent) != 0 {
+		t.Fatalf("expected no uploads, got %+v", *sent)
+	}
+}
+
+func TestSendFileToolUsesContextWorkspace(t *testing.T) {
+	tool, workspace, sent := newSendFileTestTool(t)
+	profileWorkspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(profileWorkspace, "notes.md"), []byte("# notes"), 0o644); err != nil {
+		t.Fatalf("write profile file: %v", err)
+	}
+	ctx := WithWorkspace(WithSendFileContext(context.Background(), "telegram", "telegram:42"), profileWorkspace)
+
+	if _, err := tool.Execute(ctx, map[string]interface{}{"path": "notes.md"}); err != nil {
+		t.Fatalf("expected the profile file to be sent: %v", err)
+	}
+	for _, path := range []string{"reports/weekly.pdf", filepath.Join(workspace, "reports", "weekly.pdf")} {
+		if _, err := tool.Execute(ctx, map[string]interface{}{"path": path}); err == nil {
+			t.Fatalf("expected %q from the default workspace to be rejected", path)
+		}
+	}
+	if len(*sent) != 1 || filepath.Base((*sent)[0].path) != "notes.md" {
+		t.Fatalf("expected only the profile file to be sent, got %+v", *sent)
+	}
+}
@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SpawnAgentToolName is the name of the synchronous delegation tool.
+const SpawnAgentToolName = "spawn_agent"
+
+// AgentDelegation describes a task handed to a child agent.
+type AgentDelegation struct {
+	Task          string
+	Tools         []string // Tools the child may call; empty means all tools the parent may call
+	MaxIterations int
+	Depth         int // Nesting level of the child, starting at 1
+}
+
+// DelegationResult is a child agent's final answer and what it consumed.
+type DelegationResult struct {
+	Answer           string
+	LLMCalls         int
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// AgentDelegator runs a child agent until it produces a final answer.
+type AgentDelegator interface {
+	Delegate(ctx context.Context, task AgentDelegation) (DelegationResult, error)
+}
+
+// SpawnAgentOptions bounds what a child agent may do.
+type SpawnAgentOptions struct {
+	MaxDepth      int // Nesting limit; 1 lets the main agent delegate but not its children
+	MaxIterations int // Default and upper bound for a child's tool iterations
+}
+
+// SpawnAgentTool delegates a task to a child agent and waits for its answer.
+type SpawnAgentTool struct {
+	delegator AgentDelegator
+	opts      SpawnAgentOptions
+}
+
+type delegationContextKey struct{}
+
+// NewSpawnAgentTool creates a new spawn_agent tool.
+func NewSpawnAgentTool(delegator AgentDelegator, opts SpawnAgentOptions) *SpawnAgentTool {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 1
+	}
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = 10
+	}
+	return &SpawnAgentTool{delegator: delegator, opts: opts}
+}
+
+// WithDelegationDepth marks ctx as running inside a child agent at depth.
+func WithDelegationDepth(ctx context.Context, depth int) context.Context {
+	return context.WithValue(ctx, delegationContextKey{}, depth)
+}
+
+// DelegationDepth returns how deeply ctx is nested in child agents; the main
+// agent runs at depth 0.
+func DelegationDepth(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+	depth, _ := ctx.Value(delegationContextKey{}).(int)
+	return depth
+}
+
+// Name returns the tool name.
+func (t *SpawnAgentTool) Name() string {
+	return SpawnAgentToolName
+}
+
+// Description returns the tool description.
+func (t *SpawnAgentTool) Description() string {
+	return `Delegate a self-contained task to a child agent and wait for its final answer. Use this to:
+- Research or explore something without filling this conversation with intermediate steps
+- Hand a focused subtask to an agent limited to the tools it needs
+
+The child does not see this conversation, so include every detail it needs in the task. It returns only its final answer.`
+}
+
+// Parameters returns the tool parameters schema.
+func (t *SpawnAgentTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"task": map[string]interface{}{
+				"type":        "string",
+				"description": "Complete instructions for the child agent",
+			},
+			"tools": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "Names of the tools the child may use. Omit to allow every tool you can use.",
+			},
+			"max_iterations": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Tool iterations the child may run (default and maximum %d)", t.opts.MaxIterations),
+			},
+		},
+		"required": []string{"task"},
+	}
+}
+
+// Execute runs the child agent and returns its final answer.
+func (t *SpawnAgentTool) Execute(ctx context.Context, params map[string]interface{}) (string, error) {
+	if t.delegator == nil {
+		return "", fmt.Errorf("agent delegation not available")
+	}
+	task, _ := params["task"].(string)
+	task = strings.TrimSpace(task)
+	if task == "" {
+		return "", fmt.Errorf("task parameter is required")
+	}
+
+	depth := DelegationDepth(ctx) + 1
+	if depth > t.opts.MaxDepth {
+		return "", fmt.Errorf("delegation depth limit (%d) reached; finish this task yourself", t.opts.MaxDepth)
+	}
+
+	iterations := t.opts.MaxIterations
+	if value, ok := params["max_iterations"].(float64); ok && value >= 1 && int(value) < iterations {
+		iterations = int(value)
+	}
+
+	var toolNames []string
+	if values, ok := params["tools"].([]interface{}); ok {
+		for _, value := range values {
+			if name, ok := value.(string); ok && strings.TrimSpace(name) != "" {
+				toolNames = append(toolNames, strings.TrimSpace(name))
+			}
+		}
+	}
+
+	result, err := t.delegator.Delegate(WithDelegationDepth(ctx, depth), AgentDelegation{
+		Task:          task,
+		Tools:         toolNames,
+		MaxIterations: iterations,
+		Depth:         depth,
+	})
+	if err != nil {
+		return "", fmt.Errorf("child agent failed: %w", err)
+	}
+
+	answer := strings.TrimSpace(result.Answer)
+	if answer == "" {
+		answer = "(the child agent returned no answer)"
+	}
+	return fmt.Sprintf("%s\n\n[child agent: %d LLM calls, %d tokens (%d prompt, %d completion)]",
+		answer, result.LLMCalls, result.TotalTokens, result.PromptTokens, result.CompletionTokens), nil
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type delegateRecorder struct {
+	task  AgentDelegation
+	depth int
+}
+
+func (d *delegateRecorder) Delegate(ctx context.Context, task AgentDelegation) (DelegationResult, error) {
+	d.task = task
+	d.depth = DelegationDepth(ctx)
+	return DelegationResult{Answer: "found it", LLMCalls: 2, PromptTokens: 90, CompletionTokens: 10, TotalTokens: 100}, nil
+}
+
+func TestSpawnAgentToolDelegatesWithinLimits(t *testing.T) {
+	delegator := &delegateRecorder{}
+	tool := NewSpawnAgentTool(delegator, SpawnAgentOptions{MaxDepth: 2, MaxIterations: 5})
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"task":           "find the config loader",
+		"tools":          []interface{}{"read_file", " list_dir "},
+		"max_iterations": float64(50),
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !strings.HasPrefix(result, "found it") || !strings.Contains(result, "2 LLM calls, 100 tokens") {
+		t.Fatalf("unexpected result %q", result)
+	}
+	if delegator.depth != 1 || delegator.task.Depth != 1 || delegator.task.MaxIterations != 5 {
+		t.Fatalf("expected depth 1 and capped iterations, got depth=%d task=%+v", delegator.depth, delegator.task)
+	}
+	if len(delegator.task.Tools) != 2 || delegator.task.Tools[1] != "list_dir" {
+		t.Fatalf("unexpected tool subset %v", delegator.task.Tools)
+	}
+
+	if _, err := tool.Execute(WithDelegationDepth(context.Background(), 2), map[string]interface{}{"task": "recurse"}); err == nil ||
+		!strings.Contains(err.Error(), "depth limit (2)") {
+		t.Fatalf("expected depth limit error, got %v", err)
+	}
+	if _, err := tool.Execute(context.Background(), map[string]interface{}{}); err == nil {
+		t.Fatal("expected missing task to be rejected")
+	}
+}
@@ -0,0 +1,587 @@
+package tools
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib-x/entsqlite"
+	_ "github.com/lib/pq"
+)
+
+const (
+	sqlDefaultMaxRows = 200
+	sqlDefaultTimeout = 30 * time.Second
+	// sqlSchemaTimeout bounds introspection done while rendering the
+	// description, so an unreachable database cannot stall prompt building.
+	sqlSchemaTimeout = 5 * time.Second
+	sqlSchemaTTL     = 10 * time.Minute
+	sqlSchemaRetry   = time.Minute
+	// sqlMaxSchemaChars bounds the schema summary in the tool description;
+	// the schema action returns the full listing.
+	sqlMaxSchemaChars = 4000
+	sqlMaxCellChars   = 2000
+	// sqlMaxOutputChars bounds the full schema listing returned to the model.
+	sqlMaxOutputChars = 50000
+)
+
+// SQLConnection is a database the sql_query tool can reach.
+type SQLConnection struct {
+	Name     string
+	Type     string // "postgres", "mysql" or "sqlite"
+	DSN      string
+	ReadOnly bool
+	MaxRows  int
+	Timeout  time.Duration
+}
+
+// sqlReadStatements are the leading keywords allowed on read-only connections.
+var sqlReadStatements = map[string]bool{
+	"select":   true,
+	"with":     true,
+	"show":     true,
+	"explain":  true,
+	"describe": true,
+	"desc":     true,
+	"values":   true,
+	"table":    true,
+}
+
+// sqlWriteKeywords are rejected anywhere in a read-only statement. This
+// catches data-modifying CTEs and SELECT ... INTO before the database's own
+// read-only transaction has to.
+var sqlWriteKeywords = map[string]bool{
+	"insert":   true,
+	"update":   true,
+	"delete":   true,
+	"merge":    true,
+	"upsert":   true,
+	"create":   true,
+	"alter":    true,
+	"drop":     true,
+	"truncate": true,
+	"grant":    true,
+	"revoke":   true,
+	"copy":     true,
+	"call":     true,
+	"execute":  true,
+	"attach":   true,
+	"detach":   true,
+	"vacuum":   true,
+	"reindex":  true,
+	"into":     true,
+	"lock":     true,
+}
+
+var sqlWordPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_$]*`)
+
+type sqlDatabase struct {
+	conn SQLConnection
+
+	openOnce sync.Once
+	db       *sql.DB
+	openErr  error
+
+	schemaMu  sync.Mutex
+	schema    string
+	schemaErr error
+	schemaAt  time.Time
+}
+
+// SQLQueryTool runs parameterized queries against configured databases and
+// offers their schema to the model in its description.
+type SQLQueryTool struct {
+	databases []*sqlDatabase
+	byName    map[string]*sqlDatabase
+}
+
+// NewSQLQueryTool creates a sql_query tool for connections. Connections are
+// opened lazily on first use.
+func NewSQLQueryTool(connections []SQLConnection) *SQLQueryTool {
+	t := &SQLQueryTool{byName: make(map[string]*sqlDatabase, len(connections))}
+	for _, conn := range connections {
+		conn.Name = strings.TrimSpace(conn.Name)
+		conn.Type = strings.ToLower(strings.TrimSpace(conn.Type))
+		if conn.MaxRows <= 0 {
+			conn.MaxRows = sqlDefaultMaxRows
+		}
+		if conn.Timeout <= 0 {
+			conn.Timeout = sqlDefaultTimeout
+		}
+		d := &sqlDatabase{conn: conn}
+		t.databases = append(t.databases, d)
+		t.byName[conn.Name] = d
+	}
+	return t
+}
+
+func (t *SQLQueryTool) Name() string {
+	return "sql_query"
+}
+
+func (t *SQLQueryTool) Description() string {
+	var b strings.Builder
+	b.WriteString("Run a parameterized SQL query against a configured database. ")
+	b.WriteString("Pass values through args instead of formatting them into the query. ")
+	b.WriteString("Use action=schema to list all tables and columns. Databases:")
+	remaining := sqlMaxSchemaChars
+	for _, d := range t.databases {
+		mode := "read-write"
+		if d.conn.ReadOnly {
+			mode = "read-only"
+		}
+		fmt.Fprintf(&b, "\n- %s (%s, %s, placeholders %s, max %d rows)", d.conn.Name, d.conn.Type, mode, d.placeholderHint(), d.conn.MaxRows)
+
+		ctx, cancel := context.WithTimeout(context.Background(), sqlSchemaTimeout)
+		schema, err := d.loadSchema(ctx, false)
+		cancel()
+		switch {
+		case err != nil:
+			b.WriteString("\n  schema unavailable")
+		case schema == "":
+			b.WriteString("\n  no tables")
+		case remaining <= 0:
+			b.WriteString("\n  schema omitted, use action=schema")
+		default:
+			if len(schema) > remaining {
+				schema = schema[:remaining] + "\n... (truncated, use action=schema)"
+			}
+			remaining -= len(schema)
+			b.WriteString("\n  " + strings.ReplaceAll(schema, "\n", "\n  "))
+		}
+	}
+	return b.String()
+}
+
+func (t *SQLQueryTool) Parameters() map[string]interface{} {
+	names := make([]interface{}, 0, len(t.databases))
+	for _, d := range t.databases {
+		names = append(names, d.conn.Name)
+	}
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"query", "schema"},
+				"description": "query runs a statement; schema lists tables and columns (default: query)",
+			},
+			"database": map[string]interface{}{
+				"type":        "string",
+				"enum":        names,
+				"description": "Database name, optional when only one is configured",
+			},
+			"query": map[string]interface{}{
+				"type":        "string",
+				"description": "A single SQL statement using the database's placeholders",
+			},
+			"args": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{},
+				"description": "Values bound to the query placeholders, in order",
+			},
+			"max_rows": map[string]interface{}{
+				"type":        "integer",
+				"description": "Maximum rows to return, capped by the database's limit",
+			},
+		},
+	}
+}
+
+func (t *SQLQueryTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	d, err := t.database(getStringArg(args, "database", ""))
+	if err != nil {
+		return "", err
+	}
+
+	switch action := strings.TrimSpace(getStringArg(args, "action", "query")); action {
+	case "schema":
+		ctx, cancel := context.WithTimeout(ctx, d.conn.Timeout)
+		defer cancel()
+		schema, err := d.loadSchema(ctx, true)
+		if err != nil {
+			return "", err
+		}
+		if schema == "" {
+			return fmt.Sprintf("Database %s has no tables.", d.conn.Name), nil
+		}
+		return truncateOutput(schema, sqlMaxOutputChars), nil
+	case "", "query":
+		query := strings.TrimSpace(getStringArg(args, "query", ""))
+		if query == "" {
+			return "", fmt.Errorf("query is required")
+		}
+		params, _ := args["args"].([]interface{})
+		maxRows := getIntArg(args, "max_rows", d.conn.MaxRows)
+		if maxRows <= 0 || maxRows > d.conn.MaxRows {
+			maxRows = d.conn.MaxRows
+		}
+		return d.query(ctx, query, params, maxRows)
+	default:
+		return "", fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+func (t *SQLQueryTool) database(name string) (*sqlDatabase, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		if len(t.databases) == 1 {
+			return t.databases[0], nil
+		}
+		return nil, fmt.Errorf("database is required when several are configured")
+	}
+	d, ok := t.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown database: %s", name)
+	}
+	return d, nil
+}
+
+func (d *sqlDatabase) open() (*sql.DB, error) {
+	d.openOnce.Do(func() {
+		var driver string
+		switch d.conn.Type {
+		case "postgres":
+			driver = "postgres"
+		case "mysql":
+			driver = "mysql"
+		case "sqlite":
+			driver = "sqlite3"
+		default:
+			d.openErr = fmt.Errorf("database %s: unsupported type %q", d.conn.Name, d.conn.Type)
+			return
+		}
+		d.db, d.openErr = sql.Open(driver, d.conn.DSN)
+		if d.openErr != nil {
+			d.openErr = fmt.Errorf("open database %s: %w", d.conn.Name, d.openErr)
+		}
+	})
+	return d.db, d.openErr
+}
+
+func (d *sqlDatabase) placeholderHint() string {
+	if d.conn.Type == "postgres" {
+		return "$1, $2"
+	}
+	return "?"
+}
+
+// query runs statement and renders its result. Read-only connections accept a
+// single read statement, executed in a read-only transaction that is always
+// rolled back; sqlite, which ignores that flag, also gets query_only.
+func (d *sqlDatabase) query(ctx context.Context, statement string, params []interface{}, maxRows int) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.conn.Timeout)
+	defer cancel()
+
+	db, err := d.open()
+	if err != nil {
+		return "", err
+	}
+
+	if !d.conn.ReadOnly {
+		if !sqlReturnsRows(d.conn.Type, statement) {
+			result, err := db.ExecContext(ctx, statement, params...)
+			if err != nil {
+				return "", fmt.Errorf("exec on %s: %w", d.conn.Name, err)
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return "Statement executed.", nil
+			}
+			return fmt.Sprintf("Statement executed, %d rows affected.", affected), nil
+		}
+		rows, err := db.QueryContext(ctx, statement, params...)
+		if err != nil {
+			return "", fmt.Errorf("query on %s: %w", d.conn.Name, err)
+		}
+		return formatSQLRows(rows, maxRows)
+	}
+
+	if err := checkReadOnlySQL(d.conn.Type, statement); err != nil {
+		return "", fmt.Errorf("database %s is read-only: %w", d.conn.Name, err)
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return "", fmt.Errorf("connect to %s: %w", d.conn.Name, err)
+	}
+	defer conn.Close()
+	if d.conn.Type == "sqlite" {
+		if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+			return "", fmt.Errorf("enable query_only on %s: %w", d.conn.Name, err)
+		}
+	}
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return "", fmt.Errorf("begin read-only transaction on %s: %w", d.conn.Name, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	rows, err := tx.QueryContext(ctx, statement, params...)
+	if err != nil {
+		return "", fmt.Errorf("query on %s: %w", d.conn.Name, err)
+	}
+	return formatSQLRows(rows, maxRows)
+}
+
+// formatSQLRows renders up to maxRows rows as JSON and closes rows.
+func formatSQLRows(rows *sql.Rows, maxRows int) (string, error) {
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", fmt.Errorf("read columns: %w", err)
+	}
+
+	result := struct {
+		Columns   []string        `json:"columns"`
+		Rows      [][]interface{} `json:"rows"`
+		Truncated bool            `json:"truncated,omitempty"`
+	}{Columns: columns, Rows: [][]interface{}{}}
+	for rows.Next() {
+		if len(result.Rows) == maxRows {
+			result.Truncated = true
+			break
+		}
+		values := make([]interface{}, len(columns))
+		scan := make([]interface{}, len(columns))
+		for i := range values {
+			scan[i] = &values[i]
+		}
+		if err := rows.Scan(scan...); err != nil {
+			return "", fmt.Errorf("scan row: %w", err)
+		}
+		for i, value := range values {
+			values[i] = sqlCellValue(value)
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("read rows: %w", err)
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("encode rows: %w", err)
+	}
+	return string(out), nil
+}
+
+func sqlCellValue(value interface{}) interface{} {
+	var text string
+	switch v := value.(type) {
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return value
+	}
+	if len(text) > sqlMaxCellChars {
+		return text[:sqlMaxCellChars] + "...(truncated)"
+	}
+	return text
+}
+
+// loadSchema returns one "table(column type, ...)" line per table, cached for
+// sqlSchemaTTL unless refresh is set. Failures are retried after sqlSchemaRetry.
+func (d *sqlDatabase) loadSchema(ctx context.Context, refresh bool) (string, error) {
+	d.schemaMu.Lock()
+	defer d.schemaMu.Unlock()
+
+	ttl := sqlSchemaTTL
+	if d.schemaErr != nil {
+		ttl = sqlSchemaRetry
+	}
+	if !refresh && !d.schemaAt.IsZero() && time.Since(d.schemaAt) < ttl {
+		return d.schema, d.schemaErr
+	}
+	d.schema, d.schemaErr = d.introspect(ctx)
+	d.schemaAt = time.Now()
+	return d.schema, d.schemaErr
+}
+
+func (d *sqlDatabase) introspect(ctx context.Context) (string, error) {
+	db, err := d.open()
+	if err != nil {
+		return "", err
+	}
+
+	var query string
+	switch d.conn.Type {
+	case "postgres":
+		query = `SELECT CASE WHEN table_schema = 'public' THEN table_name ELSE table_schema || '.' || table_name END,
+       column_name, data_type
+  FROM information_schema.columns
+ WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+ ORDER BY table_schema, table_name, ordinal_position`
+	case "mysql":
+		query = `SELECT table_name, column_name, column_type
+  FROM information_schema.columns
+ WHERE table_schema = DATABASE()
+ ORDER BY table_name, ordinal_position`
+	default:
+		query = `SELECT m.name, p.name, p.type
+  FROM sqlite_master m JOIN pragma_table_info(m.name) p
+ WHERE m.type IN ('table', 'view') AND m.name NOT LIKE 'sqlite_%'
+ ORDER BY m.name, p.cid`
+	}
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("introspect %s: %w", d.conn.Name, err)
+	}
+	defer rows.Close()
+
+	var lines []string
+	var table string
+	var columns []string
+	flush := func() {
+		if table != "" {
+			lines = append(lines, fmt.Sprintf("%s(%s)", table, strings.Join(columns, ", ")))
+		}
+	}
+	for rows.Next() {
+		var tableName, column, dataType string
+		if err := rows.Scan(&tableName, &column, &dataType); err != nil {
+			return "", fmt.Errorf("introspect %s: %w", d.conn.Name, err)
+		}
+		if tableName != table {
+			flush()
+			table, columns = tableName, nil
+		}
+		columns = append(columns, strings.TrimSpace(column+" "+strings.ToLower(dataType)))
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("introspect %s: %w", d.conn.Name, err)
+	}
+	flush()
+	return strings.Join(lines, "\n"), nil
+}
+
+// checkReadOnlySQL accepts a single statement starting with a read keyword
+// and containing no write keywords outside literals and comments.
+func checkReadOnlySQL(dbType, statement string) error {
+	stripped := stripSQLLiterals(dbType, statement)
+	body := strings.TrimRight(strings.TrimSpace(stripped), "; \t\r\n")
+	if strings.Contains(body, ";") {
+		return fmt.Errorf("only a single statement is allowed")
+	}
+	words := sqlWordPattern.FindAllString(body, -1)
+	if len(words) == 0 {
+		return fmt.Errorf("empty statement")
+	}
+	if first := strings.ToLower(words[0]); !sqlReadStatements[first] {
+		return fmt.Errorf("%s statements are not allowed, use SELECT, WITH, SHOW, EXPLAIN or DESCRIBE", strings.ToUpper(first))
+	}
+	for _, word := range words[1:] {
+		if sqlWriteKeywords[strings.ToLower(word)] {
+			return fmt.Errorf("%s is not allowed", strings.ToUpper(word))
+		}
+	}
+	return nil
+}
+
+// sqlReturnsRows reports whether statement should be run as a query rather
+// than an exec on a writable connection.
+func sqlReturnsRows(dbType, statement string) bool {
+	words := sqlWordPattern.FindAllString(stripSQLLiterals(dbType, statement), -1)
+	if len(words) == 0 {
+		return false
+	}
+	if sqlReadStatements[strings.ToLower(words[0])] || strings.EqualFold(words[0], "pragma") {
+		return true
+	}
+	for _, word := range words {
+		if strings.EqualFold(word, "returning") {
+			return true
+		}
+	}
+	return false
+}
+
+// stripSQLLiterals blanks out string literals, quoted identifiers, dollar
+// quoted bodies and comments so keyword checks only see SQL syntax. Backslash
+// escapes and # comments are MySQL-only; elsewhere they would let a literal
+// hide a following statement.
+func stripSQLLiterals(dbType, statement string) string {
+	mysql := dbType == "mysql"
+	var b strings.Builder
+	b.Grow(len(statement))
+	for i := 0; i < len(statement); {
+		c := statement[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for end < len(statement) {
+				if statement[end] == c {
+					if end+1 < len(statement) && statement[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				if mysql && statement[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			b.WriteString(" ")
+			i = end + 1
+		case c == '-' && strings.HasPrefix(statement[i:], "--"), mysql && c == '#':
+			end := strings.IndexByte(statement[i:], '\n')
+			if end < 0 {
+				i = len(statement)
+			} else {
+				i += end
+			}
+			b.WriteString(" ")
+		case c == '/' && strings.HasPrefix(statement[i:], "/*"):
+			end := strings.Index(statement[i+2:], "*/")
+			if end < 0 {
+				i = len(statement)
+			} else {
+				i += end + 4
+			}
+			b.WriteString(" ")
+		case c == '$' && dbType == "postgres":
+			tag := sqlDollarTag(statement[i:])
+			if tag == "" {
+				b.WriteByte(c)
+				i++
+				continue
+			}
+			end := strings.Index(statement[i+len(tag):], tag)
+			if end < 0 {
+				i = len(statement)
+			} else {
+				i += len(tag) + end + len(tag)
+			}
+			b.WriteString(" ")
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// sqlDollarTag returns the opening $tag$ of a postgres dollar-quoted string
+// at the start of s, or "" when s starts with a placeholder such as $1.
+func sqlDollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1]
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		case c >= '0' && c <= '9' && i > 1:
+		default:
+			return ""
+		}
+	}
+	return ""
+}
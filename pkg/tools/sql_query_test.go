@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newSQLTestDatabase(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "shop.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+	for _, stmt := range []string{
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, customer TEXT NOT NULL, total REAL)",
+		"INSERT INTO orders (customer, total) VALUES ('alice', 12.5), ('bob', 30), ('carol', 7)",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("seed %q: %v", stmt, err)
+		}
+	}
+	return path
+}
+
+func TestSQLQueryToolReadOnlyConnection(t *testing.T) {
+	tool := NewSQLQueryTool([]SQLConnection{{
+		Name:     "shop",
+		Type:     "sqlite",
+		DSN:      newSQLTestDatabase(t),
+		ReadOnly: true,
+		MaxRows:  2,
+	}})
+	ctx := context.Background()
+
+	if desc := tool.Description(); !strings.Contains(desc, "orders(id integer, customer text, total real)") {
+		t.Fatalf("expected schema in description, got %q", desc)
+	}
+
+	out, err := tool.Execute(ctx, map[string]interface{}{
+		"query": "SELECT customer FROM orders WHERE total > ? ORDER BY id",
+		"args":  []interface{}{float64(10)},
+	})
+	if err != nil || out != `{"columns":["customer"],"rows":[["alice"],["bob"]]}` {
+		t.Fatalf("unexpected result %q (err %v)", out, err)
+	}
+	out, err = tool.Execute(ctx, map[string]interface{}{"query": "SELECT id FROM orders", "max_rows": float64(50)})
+	if err != nil || !strings.Contains(out, `"truncated":true`) {
+		t.Fatalf("expected rows to be capped at max_rows, got %q (err %v)", out, err)
+	}
+
+	for _, query := range []string{
+		"DELETE FROM orders",
+		"SELECT 1; DROP TABLE orders",
+		"WITH gone AS (DELETE FROM orders RETURNING id) SELECT * FROM gone",
+		"SELECT * INTO backup FROM orders",
+	} {
+		if _, err := tool.Execute(ctx, map[string]interface{}{"query": query}); err == nil || !strings.Contains(err.Error(), "read-only") {
+			t.Fatalf("expected %q to be refused, got %v", query, err)
+		}
+	}
+	if _, err := tool.Execute(ctx, map[string]interface{}{"query": "SELECT 'drop; delete' AS note -- update"}); err != nil {
+		t.Fatalf("expected keywords in literals and comments to be ignored: %v", err)
+	}
+}
+
+func TestSQLQueryToolWritableConnection(t *testing.T) {
+	tool := NewSQLQueryTool([]SQLConnection{{Name: "shop", Type: "sqlite", DSN: newSQLTestDatabase(t)}})
+	ctx := context.Background()
+
+	out, err := tool.Execute(ctx, map[string]interface{}{
+		"database": "shop",
+		"query":    "UPDATE orders SET total = total + ? WHERE customer = ?",
+		"args":     []interface{}{float64(1), "carol"},
+	})
+	if err != nil || !strings.Contains(out, "1 rows affected") {
+		t.Fatalf("unexpected exec result %q (err %v)", out, err)
+	}
+	schema, err := tool.Execute(ctx, map[string]interface{}{"action": "schema"})
+	if err != nil || !strings.HasPrefix(schema, "orders(") {
+		t.Fatalf("unexpected schema %q (err %v)", schema, err)
+	}
+	if _, err := tool.Execute(ctx, map[string]interface{}{"database": "other", "query": "SELECT 1"}); err == nil {
+		t.Fatal("expected unknown database to be rejected")
+	}
+}
+
+func TestStripSQLLiteralsDialects(t *testing.T) {
+	// In postgres a backslash does not escape a quote, so the DROP is real.
+	if err := checkReadOnlySQL("postgres", `SELECT 'a\'; DROP TABLE t; --'`); err == nil {
+		t.Fatal("expected postgres statement with trailing DROP to be refused")
+	}
+	if err := checkReadOnlySQL("mysql", `SELECT 'a\'; DROP TABLE t; --'`); err != nil {
+		t.Fatalf("expected mysql escaped literal to be accepted: %v", err)
+	}
+	if err := checkReadOnlySQL("postgres", "SELECT $1::text, $body$ delete $body$"); err != nil {
+		t.Fatalf("expected dollar-quoted literal to be ignored: %v", err)
+	}
+}
@@ -110,7 +110,7 @@ func (t *ToolSessionTool) handleSpawn(ctx context.Context, args map[string]inter
 	command, _ := args["command"].(string)
 	workdir, _ := args["workdir"].(string)
 	title, _ := args["title"].(string)
-	resolved, err := t.resolveSpawnSpec(toolName, strings.TrimSpace(command), strings.TrimSpace(workdir), strings.TrimSpace(title))
+	resolved, err := t.resolveSpawnSpec(ctx, toolName, strings.TrimSpace(command), strings.TrimSpace(workdir), strings.TrimSpace(title))
 	if err != nil {
 		return "", err
 	}
@@ -159,7 +159,7 @@ func (t *ToolSessionTool) handleSpawn(ctx context.Context, args map[string]inter
 	if t.isSupportedExternalAgentKind(toolName) {
 		if err := externalagent.EnsureProcess(
 			ctx,
-			t.workspace(),
+			t.workspace(ctx),
 			t.processProbe,
 			t.processStarter,
 			t.sessionUpdater,
@@ -259,7 +259,10 @@ func (t *ToolSessionTool) handleTerminate(ctx context.Context, args map[string]i
 	return fmt.Sprintf("Session %s terminated.", sessionID), nil
 }
 
-func (t *ToolSessionTool) resolveSpawnSpec(toolName, command, workdir, title string) (externalagent.SessionSpec, error) {
+func (t *ToolSessionTool) resolveSpawnSpec(ctx context.Context, toolName, command, workdir, title string) (externalagent.SessionSpec, error) {
+	if strings.TrimSpace(workdir) == "" {
+		workdir = t.workspace(ctx)
+	}
 	spec := externalagent.SessionSpec{
 		Owner:     "agent",
 		AgentKind: toolName,
@@ -276,9 +279,6 @@ func (t *ToolSessionTool) resolveSpawnSpec(toolName, command, workdir, title str
 	if command == "" {
 		return externalagent.SessionSpec{}, fmt.Errorf("could not resolve command for tool: %s", toolName)
 	}
-	if strings.TrimSpace(workdir) == "" {
-		workdir = t.workspace()
-	}
 	if strings.TrimSpace(workdir) == "" {
 		return externalagent.SessionSpec{}, fmt.Errorf("workdir is required")
 	}
@@ -292,16 +292,16 @@ func (t *ToolSessionTool) isSupportedExternalAgentKind(toolName string) bool {
 	_, err := externalagent.NormalizeLaunchSpec(t.cfg, externalagent.SessionSpec{
 		Owner:     "agent",
 		AgentKind: strings.TrimSpace(toolName),
-		Workspace: t.workspace(),
+		Workspace: t.workspace(context.Background()),
 	})
 	return err == nil
 }
 
-func (t *ToolSessionTool) workspace() string {
+func (t *ToolSessionTool) workspace(ctx context.Context) string {
 	if t == nil || t.cfg == nil {
 		return ""
 	}
-	return strings.TrimSpace(t.cfg.WorkspacePath())
+	return strings.TrimSpace(WorkspaceFor(ctx, t.cfg.WorkspacePath()))
 }
 
 // resolveCommand maps a tool name to its default command, or uses the provided command.
@@ -331,7 +331,7 @@ func buildRuntimeCommand(command, sessionID string) (string, string) {
 }
 
 func isTmuxAvailable() bool {
-	return runtimeagents.DefaultTransport().Available()
+	return runtimeagents.TransportByName(runtimeagents.TransportTmux).Available()
 }
 
 func buildTmuxSessionName(sessionID string) string {
@@ -0,0 +1,29 @@
+package tools
+
+import (
+	"context"
+	"strings"
+)
+
+type workspaceContextKey struct{}
+
+// WithWorkspace overrides the workspace that file, exec, send_file and
+// tool session tools resolve relative paths against for calls made with ctx. Agent profiles use it to
+// give each profile its own working directory.
+func WithWorkspace(ctx context.Context, workspace string) context.Context {
+	workspace = strings.TrimSpace(workspace)
+	if workspace == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, workspaceContextKey{}, workspace)
+}
+
+// WorkspaceFor returns the workspace override stored in ctx, or fallback.
+func WorkspaceFor(ctx context.Context, fallback string) string {
+	if ctx != nil {
+		if workspace, ok := ctx.Value(workspaceContextKey{}).(string); ok && workspace != "" {
+			return workspace
+		}
+	}
+	return fallback
+}
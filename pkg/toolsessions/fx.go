@@ -7,15 +7,38 @@ import (
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
+	"nekobot/pkg/bus"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/process"
 )
 
 // Module wires tool session persistence and lifecycle cleanup.
 var Module = fx.Module("toolsessions",
 	fx.Provide(NewManager),
 	fx.Invoke(registerLifecycle),
+	fx.Invoke(bindProcessLimits),
 )
 
+type bindProcessLimitsDeps struct {
+	fx.In
+
+	Manager *Manager
+	Process *process.Manager `optional:"true"`
+	Bus     bus.Bus          `optional:"true"`
+}
+
+// bindProcessLimits lets the process manager enforce tool-session limits and
+// report the sessions it kills.
+func bindProcessLimits(deps bindProcessLimitsDeps) {
+	if deps.Process == nil {
+		return
+	}
+	deps.Process.SetLimitResolver(deps.Manager.resolveProcessLimits)
+	deps.Process.SetLimitHandler(func(violation process.LimitViolation) {
+		deps.Manager.handleLimitViolation(deps.Bus, violation)
+	})
+}
+
 func registerLifecycle(lc fx.Lifecycle, mgr *Manager, log *logger.Logger) {
 	var cancel context.CancelFunc
 
@@ -62,6 +85,7 @@ func (m *Manager) cleanupLoop(ctx context.Context, interval time.Duration) {
 					zap.Int("archived_old", result.ArchivedOld),
 				)
 			}
+			m.runSweepers(context.Background())
 
 			eventsDeleted, eventsErr := m.CleanupEvents(context.Background())
 			if eventsErr != nil {
@@ -0,0 +1,135 @@
+package toolsessions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/config"
+	"nekobot/pkg/process"
+	"nekobot/pkg/runtimeagents"
+)
+
+// MetadataResourceLimits holds limits a session sets for itself, in the
+// shape of config.ToolSessionLimitsConfig.
+const MetadataResourceLimits = "resource_limits"
+
+// EventLimitExceeded is the timeline event of a session terminated for
+// exceeding a resource limit.
+const EventLimitExceeded = "limit_exceeded"
+
+// SetResourceLimits replaces the limits applied to every tool session.
+func (m *Manager) SetResourceLimits(cfg config.ToolSessionLimitsConfig) {
+	m.limitsMu.Lock()
+	defer m.limitsMu.Unlock()
+	m.limits = cfg
+}
+
+// ResourceLimits returns the limits of a session: the configured limits,
+// tightened by any set in its metadata.
+func (m *Manager) ResourceLimits(sess *Session) config.ToolSessionLimitsConfig {
+	m.limitsMu.RLock()
+	limits := m.limits
+	m.limitsMu.RUnlock()
+	if sess == nil {
+		return limits
+	}
+	own, ok := sess.Metadata[MetadataResourceLimits]
+	if !ok {
+		return limits
+	}
+	raw, err := json.Marshal(own)
+	if err != nil {
+		return limits
+	}
+	var override config.ToolSessionLimitsConfig
+	if err := json.Unmarshal(raw, &override); err != nil {
+		return limits
+	}
+	return tightenLimits(limits, override)
+}
+
+// tightenLimits returns the stricter value of each limit; zero means unset.
+func tightenLimits(base, override config.ToolSessionLimitsConfig) config.ToolSessionLimitsConfig {
+	return config.ToolSessionLimitsConfig{
+		MaxCPUPercent:       stricter(base.MaxCPUPercent, override.MaxCPUPercent),
+		CPUGraceSeconds:     stricter(base.CPUGraceSeconds, override.CPUGraceSeconds),
+		MaxMemoryMB:         stricter(base.MaxMemoryMB, override.MaxMemoryMB),
+		MaxOutputBytes:      stricter(base.MaxOutputBytes, override.MaxOutputBytes),
+		MaxWallClockSeconds: stricter(base.MaxWallClockSeconds, override.MaxWallClockSeconds),
+	}
+}
+
+func stricter[T int | int64](a, b T) T {
+	switch {
+	case a <= 0:
+		return max(b, 0)
+	case b <= 0:
+		return a
+	default:
+		return min(a, b)
+	}
+}
+
+// processLimits converts configured limits for the process manager.
+func processLimits(cfg config.ToolSessionLimitsConfig) process.ResourceLimits {
+	return process.ResourceLimits{
+		MaxCPUPercent:  float64(cfg.MaxCPUPercent),
+		CPUGrace:       time.Duration(cfg.CPUGraceSeconds) * time.Second,
+		MaxMemoryBytes: int64(cfg.MaxMemoryMB) << 20,
+		MaxOutputBytes: cfg.MaxOutputBytes,
+		MaxWallClock:   time.Duration(cfg.MaxWallClockSeconds) * time.Second,
+	}
+}
+
+// resolveProcessLimits returns the limits for a process about to start.
+// Processes that do not belong to a tool session are not limited.
+func (m *Manager) resolveProcessLimits(sessionID string) process.ResourceLimits {
+	sess, err := m.GetSession(context.Background(), sessionID)
+	if err != nil {
+		return process.ResourceLimits{}
+	}
+	return processLimits(m.ResourceLimits(sess))
+}
+
+// handleLimitViolation records a session the process manager killed for
+// exceeding a limit on its timeline, terminates it and tells its owner
+// through a runtime event.
+func (m *Manager) handleLimitViolation(b bus.Bus, violation process.LimitViolation) {
+	ctx := context.Background()
+	sess, err := m.GetSession(ctx, violation.SessionID)
+	if err != nil {
+		return
+	}
+	if err := m.appendEvent(ctx, sess.ID, EventLimitExceeded, map[string]interface{}{
+		"limit":  violation.Limit,
+		"detail": violation.Detail,
+	}); err != nil {
+		m.log.Warn("Failed to record tool session event", zap.String("session_id", sess.ID), zap.Error(err))
+	}
+	if err := m.TerminateSession(ctx, sess.ID, "resource limit exceeded: "+violation.Detail); err != nil {
+		m.log.Warn("Failed to terminate tool session over limit", zap.String("session_id", sess.ID), zap.Error(err))
+	}
+	// A tool running inside tmux or zellij outlives its killed client.
+	if runtimeagents.MetadataString(sess.Metadata, runtimeagents.MetadataRuntimeSession) != "" {
+		transport := runtimeagents.MetadataString(sess.Metadata, runtimeagents.MetadataRuntimeTransport)
+		runtimeagents.TransportByName(transport).KillSession(sess.ID)
+	}
+
+	name := sess.Title
+	if name == "" {
+		name = sess.Tool
+	}
+	content := fmt.Sprintf("Tool session %q of %s was terminated: it %s", name, sess.Owner, violation.Detail)
+	if err := bus.PublishEvent(b, bus.EventToolSessionLimit, "", content, map[string]string{
+		"owner":           sess.Owner,
+		"tool_session_id": sess.ID,
+		"limit":           violation.Limit,
+	}); err != nil {
+		m.log.Warn("Failed to publish tool session limit event", zap.String("session_id", sess.ID), zap.Error(err))
+	}
+}
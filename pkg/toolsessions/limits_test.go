@@ -0,0 +1,140 @@
+package toolsessions
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/config"
+	"nekobot/pkg/process"
+)
+
+type eventRecordingBus struct {
+	bus.Bus
+	events []*bus.Message
+}
+
+func (b *eventRecordingBus) SendOutbound(msg *bus.Message) error {
+	b.events = append(b.events, msg)
+	return nil
+}
+
+func newLimitsTestManager(t *testing.T) *Manager {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.WebUI.ToolSessionLimits = config.ToolSessionLimitsConfig{
+		MaxCPUPercent:       200,
+		CPUGraceSeconds:     60,
+		MaxWallClockSeconds: 3600,
+	}
+
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	mgr, err := NewManager(cfg, newTestLogger(t), client)
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	return mgr
+}
+
+func TestResourceLimitsOnlyTightenConfiguredLimits(t *testing.T) {
+	mgr := newLimitsTestManager(t)
+	ctx := context.Background()
+	sess, err := mgr.CreateSession(ctx, CreateSessionInput{
+		Owner:   "alice",
+		Source:  SourceWebUI,
+		Tool:    "codex",
+		Command: "codex",
+		State:   StateRunning,
+		Metadata: map[string]interface{}{
+			MetadataResourceLimits: map[string]interface{}{
+				"max_cpu_percent":        400,
+				"max_memory_mb":          512,
+				"max_wall_clock_seconds": 60,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	limits := mgr.ResourceLimits(sess)
+	want := config.ToolSessionLimitsConfig{
+		MaxCPUPercent:       200,
+		CPUGraceSeconds:     60,
+		MaxMemoryMB:         512,
+		MaxWallClockSeconds: 60,
+	}
+	if limits != want {
+		t.Fatalf("expected %+v, got %+v", want, limits)
+	}
+
+	resolved := mgr.resolveProcessLimits(sess.ID)
+	if resolved.MaxMemoryBytes != 512<<20 || resolved.MaxWallClock != time.Minute || resolved.CPUGrace != time.Minute {
+		t.Fatalf("unexpected process limits %+v", resolved)
+	}
+	if got := mgr.resolveProcessLimits("not-a-tool-session"); !got.IsZero() {
+		t.Fatalf("expected other processes to be unlimited, got %+v", got)
+	}
+}
+
+func TestHandleLimitViolationTerminatesAndNotifiesOwner(t *testing.T) {
+	mgr := newLimitsTestManager(t)
+	ctx := context.Background()
+	sess, err := mgr.CreateSession(ctx, CreateSessionInput{
+		Owner:   "alice",
+		Source:  SourceWebUI,
+		Tool:    "codex",
+		Title:   "refactor",
+		Command: "codex",
+		State:   StateRunning,
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	events := &eventRecordingBus{}
+	mgr.handleLimitViolation(events, process.LimitViolation{
+		SessionID: sess.ID,
+		Limit:     process.LimitWallClock,
+		Detail:    "ran longer than 1h0m0s",
+	})
+
+	updated, err := mgr.GetSession(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("get session: %v", err)
+	}
+	if updated.State != StateTerminated {
+		t.Fatalf("expected the session to be terminated, got %q", updated.State)
+	}
+	timeline, err := mgr.ListEvents(ctx, sess.ID, 20)
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	var limitEvent *Event
+	for _, event := range timeline {
+		if event.Type == EventLimitExceeded {
+			limitEvent = event
+		}
+	}
+	if limitEvent == nil || limitEvent.Payload["limit"] != process.LimitWallClock {
+		t.Fatalf("expected a limit_exceeded timeline event, got %+v", timeline)
+	}
+
+	if len(events.events) != 1 {
+		t.Fatalf("expected one runtime event, got %d", len(events.events))
+	}
+	msg := events.events[0]
+	if msg.Data[bus.DataKeyEvent] != bus.EventToolSessionLimit || msg.Data["owner"] != "alice" || msg.Data["tool_session_id"] != sess.ID {
+		t.Fatalf("unexpected event data %+v", msg.Data)
+	}
+	if !strings.Contains(msg.Content, `"refactor"`) || !strings.Contains(msg.Content, "ran longer than") {
+		t.Fatalf("unexpected event content %q", msg.Content)
+	}
+}
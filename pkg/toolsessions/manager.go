@@ -40,8 +40,18 @@ type Manager struct {
 	otpTTL    time.Duration
 	otpMu     sync.Mutex
 	otpCodes  map[string]sessionOTP
+	viewerMu  sync.Mutex
+	viewers   map[string]int
+	sweepMu   sync.Mutex
+	sweepers  map[string]SweepFunc
+	limitsMu  sync.RWMutex
+	limits    config.ToolSessionLimitsConfig
 }
 
+// SweepFunc releases resources tied to idle sessions and reports how many
+// it removed. Sweepers run with every lifecycle cleanup pass.
+type SweepFunc func(ctx context.Context) (int, error)
+
 type sessionOTP struct {
 	hash      string
 	expiresAt time.Time
@@ -59,6 +69,8 @@ func NewManager(cfg *config.Config, log *logger.Logger, client *ent.Client) (*Ma
 		eventCfg:  cfg.WebUI.ToolSessionEvents,
 		otpTTL:    normalizeOTPTTLSeconds(cfg.WebUI.ToolSessionOTPTTLSeconds),
 		otpCodes:  map[string]sessionOTP{},
+		viewers:   map[string]int{},
+		limits:    cfg.WebUI.ToolSessionLimits,
 	}
 	dbPath, _ := config.RuntimeDBDisplayName(cfg)
 
@@ -77,6 +89,36 @@ func (m *Manager) Close() error {
 	return nil
 }
 
+// AddSweeper registers fn to run after each lifecycle cleanup pass.
+func (m *Manager) AddSweeper(name string, fn SweepFunc) {
+	m.sweepMu.Lock()
+	defer m.sweepMu.Unlock()
+	if m.sweepers == nil {
+		m.sweepers = make(map[string]SweepFunc)
+	}
+	m.sweepers[name] = fn
+}
+
+// runSweepers runs the registered sweepers, logging their results.
+func (m *Manager) runSweepers(ctx context.Context) {
+	m.sweepMu.Lock()
+	sweepers := make(map[string]SweepFunc, len(m.sweepers))
+	for name, fn := range m.sweepers {
+		sweepers[name] = fn
+	}
+	m.sweepMu.Unlock()
+
+	for name, fn := range sweepers {
+		removed, err := fn(ctx)
+		if err != nil {
+			m.log.Warn("Tool session sweeper failed", zap.String("sweeper", name), zap.Error(err))
+		}
+		if removed > 0 {
+			m.log.Info("Tool session sweeper applied", zap.String("sweeper", name), zap.Int("removed", removed))
+		}
+	}
+}
+
 // Lifecycle returns the current lifecycle config.
 func (m *Manager) Lifecycle() LifecycleConfig {
 	return m.lifecycle
@@ -177,7 +219,7 @@ func (m *Manager) GetSession(ctx context.Context, id string) (*Session, error) {
 		}
 		return nil, fmt.Errorf("get session: %w", err)
 	}
-	return toSession(rec), nil
+	return m.withViewers(toSession(rec)), nil
 }
 
 // ListSessions returns sessions in reverse creation order.
@@ -204,7 +246,7 @@ func (m *Manager) ListSessions(ctx context.Context, input ListSessionsInput) ([]
 	}
 	out := make([]*Session, 0, len(recs))
 	for _, rec := range recs {
-		out = append(out, toSession(rec))
+		out = append(out, m.withViewers(toSession(rec)))
 	}
 	return out, nil
 }
@@ -549,6 +591,89 @@ func (m *Manager) ConfigureSessionAccess(ctx context.Context, id, mode, password
 	return secret, nil
 }
 
+// ConfigureSpectatorAccess enables or disables the read-only spectator link
+// of a session. Enabling issues a new token, revoking earlier links, and
+// returns it; spectators already streaming keep watching until they leave.
+func (m *Manager) ConfigureSpectatorAccess(ctx context.Context, id string, enabled bool) (string, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", errors.New("session id is required")
+	}
+	token := ""
+	hash := ""
+	if enabled {
+		var err error
+		token, err = randomToken(24)
+		if err != nil {
+			return "", fmt.Errorf("generate spectator token: %w", err)
+		}
+		hash = hashAccessSecret(token)
+	}
+	if err := m.client.ToolSession.UpdateOneID(id).SetSpectatorSecretHash(hash).Exec(ctx); err != nil {
+		if ent.IsNotFound(err) {
+			return "", os.ErrNotExist
+		}
+		return "", fmt.Errorf("configure spectator access: %w", err)
+	}
+	if err := m.appendEvent(ctx, id, "spectator_updated", map[string]interface{}{"enabled": enabled}); err != nil {
+		m.log.Warn("Failed to record tool session event", zap.String("session_id", id), zap.Error(err))
+	}
+	return token, nil
+}
+
+// VerifySpectatorAccess checks a spectator token. It returns os.ErrPermission
+// when the token is wrong or the spectator link is disabled.
+func (m *Manager) VerifySpectatorAccess(ctx context.Context, id, token string) (*Session, error) {
+	id = strings.TrimSpace(id)
+	token = strings.TrimSpace(token)
+	if id == "" || token == "" {
+		return nil, os.ErrPermission
+	}
+	rec, err := m.client.ToolSession.Get(ctx, id)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, os.ErrNotExist
+		}
+		return nil, fmt.Errorf("get session for spectator verification: %w", err)
+	}
+	if strings.TrimSpace(rec.SpectatorSecretHash) == "" || !compareAccessSecret(rec.SpectatorSecretHash, token) {
+		return nil, os.ErrPermission
+	}
+	return m.withViewers(toSession(rec)), nil
+}
+
+// AddViewer counts a spectator of the session until release is called.
+func (m *Manager) AddViewer(id string) (release func()) {
+	id = strings.TrimSpace(id)
+	m.viewerMu.Lock()
+	m.viewers[id]++
+	m.viewerMu.Unlock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.viewerMu.Lock()
+			if m.viewers[id]--; m.viewers[id] <= 0 {
+				delete(m.viewers, id)
+			}
+			m.viewerMu.Unlock()
+		})
+	}
+}
+
+// ViewerCount returns the number of spectators streaming the session.
+func (m *Manager) ViewerCount(id string) int {
+	m.viewerMu.Lock()
+	defer m.viewerMu.Unlock()
+	return m.viewers[strings.TrimSpace(id)]
+}
+
+func (m *Manager) withViewers(sess *Session) *Session {
+	if sess != nil {
+		sess.Viewers = m.ViewerCount(sess.ID)
+	}
+	return sess
+}
+
 // GenerateSessionOTP creates a short-lived one-time 6-digit code for access-login.
 func (m *Manager) GenerateSessionOTP(ctx context.Context, id string, ttl time.Duration) (string, time.Time, error) {
 	id = strings.TrimSpace(id)
@@ -907,6 +1032,7 @@ func toSession(rec *ent.ToolSession) *Session {
 		State:            rec.State,
 		AccessMode:       rec.AccessMode,
 		AccessOnceUsedAt: rec.AccessOnceUsedAt,
+		SpectatorEnabled: strings.TrimSpace(rec.SpectatorSecretHash) != "",
 		Pinned:           rec.Pinned,
 		LastActiveAt:     rec.LastActiveAt,
 		DetachedAt:       rec.DetachedAt,
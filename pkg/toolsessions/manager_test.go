@@ -2,6 +2,8 @@ package toolsessions
 
 import (
 	"context"
+	"errors"
+	"os"
 	"testing"
 
 	"nekobot/pkg/config"
@@ -135,3 +137,67 @@ func newTestEntClient(t *testing.T, cfg *config.Config) *ent.Client {
 	}
 	return client
 }
+
+func TestSpectatorAccessIssuesRevocableReadOnlyToken(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	mgr, err := NewManager(cfg, newTestLogger(t), client)
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+
+	ctx := context.Background()
+	session, err := mgr.CreateSession(ctx, CreateSessionInput{Owner: "tester", Tool: "codex", State: StateRunning})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if _, err := mgr.VerifySpectatorAccess(ctx, session.ID, "guess"); !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("expected a disabled spectator link to be refused, got %v", err)
+	}
+
+	first, err := mgr.ConfigureSpectatorAccess(ctx, session.ID, true)
+	if err != nil || first == "" {
+		t.Fatalf("enable spectator access: %q (%v)", first, err)
+	}
+	second, err := mgr.ConfigureSpectatorAccess(ctx, session.ID, true)
+	if err != nil {
+		t.Fatalf("rotate spectator access: %v", err)
+	}
+	if _, err := mgr.VerifySpectatorAccess(ctx, session.ID, first); !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("expected the rotated token to be refused, got %v", err)
+	}
+	verified, err := mgr.VerifySpectatorAccess(ctx, session.ID, second)
+	if err != nil || !verified.SpectatorEnabled {
+		t.Fatalf("verify spectator token: %+v (%v)", verified, err)
+	}
+	// The spectator token does not open password access.
+	if _, err := mgr.VerifySessionAccess(ctx, session.ID, second); !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("expected the spectator token to be refused for access login, got %v", err)
+	}
+
+	releaseA := mgr.AddViewer(session.ID)
+	releaseB := mgr.AddViewer(session.ID)
+	listed, err := mgr.ListSessions(ctx, ListSessionsInput{Owner: "tester"})
+	if err != nil || len(listed) != 1 || listed[0].Viewers != 2 {
+		t.Fatalf("expected 2 viewers in the list, got %+v (%v)", listed, err)
+	}
+	releaseA()
+	releaseA()
+	releaseB()
+	if got := mgr.ViewerCount(session.ID); got != 0 {
+		t.Fatalf("expected no viewers after release, got %d", got)
+	}
+
+	if _, err := mgr.ConfigureSpectatorAccess(ctx, session.ID, false); err != nil {
+		t.Fatalf("disable spectator access: %v", err)
+	}
+	if _, err := mgr.VerifySpectatorAccess(ctx, session.ID, second); !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("expected a disabled spectator link to be refused, got %v", err)
+	}
+}
@@ -35,6 +35,8 @@ type Session struct {
 	State            string                 `json:"state"`
 	AccessMode       string                 `json:"access_mode"`
 	AccessOnceUsedAt *time.Time             `json:"access_once_used_at,omitempty"`
+	SpectatorEnabled bool                   `json:"spectator_enabled"`
+	Viewers          int                    `json:"viewers"`
 	Pinned           bool                   `json:"pinned"`
 	LastActiveAt     time.Time              `json:"last_active_at"`
 	DetachedAt       *time.Time             `json:"detached_at,omitempty"`
@@ -0,0 +1,168 @@
+package transcript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/session"
+	"nekobot/pkg/usage"
+)
+
+// UsageSource looks up the provider calls of a session.
+type UsageSource interface {
+	SessionRecords(ctx context.Context, sessionID string) ([]usage.Record, error)
+}
+
+// Exporter writes session transcripts to the configured sink.
+type Exporter struct {
+	format    string
+	retention time.Duration
+	sink      Sink
+	sessions  *session.Manager
+	usage     UsageSource
+	now       func() time.Time
+}
+
+// NewExporter creates an exporter from sessions.export. usageSource may be
+// nil, in which case transcripts carry no token usage.
+func NewExporter(cfg *config.Config, sessions *session.Manager, usageSource UsageSource) (*Exporter, error) {
+	if sessions == nil {
+		return nil, fmt.Errorf("session manager is nil")
+	}
+	exportCfg := cfg.Sessions.Export
+	format, err := NormalizeFormat(exportCfg.Format)
+	if err != nil {
+		return nil, err
+	}
+	var sink Sink
+	if strings.TrimSpace(exportCfg.S3.Bucket) != "" {
+		s3Sink, err := NewS3Sink(exportCfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		sink = s3Sink
+	} else {
+		dir := strings.TrimSpace(exportCfg.Dir)
+		if dir == "" {
+			dir = filepath.Join(cfg.WorkspacePath(), "exports", "transcripts")
+		}
+		sink = &DirSink{Dir: dir}
+	}
+	return &Exporter{
+		format:    format,
+		retention: time.Duration(exportCfg.RetentionDays) * 24 * time.Hour,
+		sink:      sink,
+		sessions:  sessions,
+		usage:     usageSource,
+		now:       time.Now,
+	}, nil
+}
+
+// Format returns the configured export format.
+func (e *Exporter) Format() string {
+	return e.format
+}
+
+// Build assembles the transcript of a persisted session.
+func (e *Exporter) Build(ctx context.Context, sess *session.SessionJSONL) (*Transcript, error) {
+	var records []usage.Record
+	if e.usage != nil {
+		var err error
+		records, err = e.usage.SessionRecords(ctx, sess.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return New(sess, records, e.now()), nil
+}
+
+// Load builds the transcript of the session with the given ID. It returns
+// os.ErrNotExist when the session is not persisted.
+func (e *Exporter) Load(ctx context.Context, sessionID string) (*Transcript, error) {
+	sess, err := e.sessions.LoadJSONL(sessionID)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, os.ErrNotExist
+		}
+		return nil, fmt.Errorf("load session %q: %w", sessionID, err)
+	}
+	return e.Build(ctx, sess)
+}
+
+// Export writes one session in format, or the configured format when empty,
+// and returns where it was stored. Re-exporting a session replaces the
+// earlier file.
+func (e *Exporter) Export(ctx context.Context, sess *session.SessionJSONL, format string) (string, error) {
+	if strings.TrimSpace(format) == "" {
+		format = e.format
+	}
+	format, err := NormalizeFormat(format)
+	if err != nil {
+		return "", err
+	}
+	t, err := e.Build(ctx, sess)
+	if err != nil {
+		return "", err
+	}
+	data, err := Render(t, format)
+	if err != nil {
+		return "", err
+	}
+	name := FileName(sess.Key, format)
+	if err := e.sink.Put(ctx, name, ContentType(format), data); err != nil {
+		return "", err
+	}
+	return e.sink.Location(name), nil
+}
+
+// ExportSince exports every persisted session with messages updated at or
+// after since, and returns where each was stored.
+func (e *Exporter) ExportSince(ctx context.Context, since time.Time, format string) ([]string, error) {
+	keys, err := e.sessions.List()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	locations := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return locations, err
+		}
+		sess, err := e.sessions.LoadJSONL(key)
+		if err != nil {
+			return locations, fmt.Errorf("load session %q: %w", key, err)
+		}
+		if len(sess.Messages) == 0 || sess.UpdatedAt.Before(since) {
+			continue
+		}
+		location, err := e.Export(ctx, sess, format)
+		if err != nil {
+			return locations, fmt.Errorf("export session %q: %w", sess.Key, err)
+		}
+		locations = append(locations, location)
+	}
+	return locations, nil
+}
+
+// Prune removes local exports older than retention_days. Exports in S3 are
+// left to the bucket's lifecycle rules.
+func (e *Exporter) Prune() (int, error) {
+	dir, ok := e.sink.(*DirSink)
+	if !ok || e.retention <= 0 {
+		return 0, nil
+	}
+	return dir.Prune(e.now().Add(-e.retention))
+}
+
+// FileName returns the export file name of a session.
+func FileName(sessionID, format string) string {
+	return session.StorageKey(sessionID) + Extension(format)
+}
@@ -0,0 +1,78 @@
+package transcript
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/session"
+	"nekobot/pkg/storage/ent"
+	"nekobot/pkg/usage"
+)
+
+// exportTimeout bounds one export of a closed session.
+const exportTimeout = 2 * time.Minute
+
+// Module exports closed sessions when sessions.export.enabled is set.
+var Module = fx.Module("transcript",
+	fx.Invoke(registerCloseExport),
+)
+
+// CloseExportParams are the dependencies of registerCloseExport.
+type CloseExportParams struct {
+	fx.In
+
+	Config    *config.Config
+	Logger    *logger.Logger
+	Sessions  *session.Manager
+	EntClient *ent.Client `optional:"true"`
+}
+
+// NewUsageSource returns the usage records of the runtime database, or nil
+// without one.
+func NewUsageSource(cfg *config.Config, client *ent.Client) (UsageSource, error) {
+	if client == nil {
+		return nil, nil
+	}
+	return usage.NewManager(cfg, client)
+}
+
+func registerCloseExport(p CloseExportParams) error {
+	if !p.Config.Sessions.Export.Enabled {
+		return nil
+	}
+	usageSource, err := NewUsageSource(p.Config, p.EntClient)
+	if err != nil {
+		return err
+	}
+	exporter, err := NewExporter(p.Config, p.Sessions, usageSource)
+	if err != nil {
+		return err
+	}
+	log := p.Logger
+
+	p.Sessions.OnClose(func(closed *session.SessionJSONL) {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+			defer cancel()
+			location, err := exporter.Export(ctx, closed, "")
+			if err != nil {
+				log.Warn("Failed to export closed session transcript",
+					zap.String("session", closed.Key),
+					zap.Error(err))
+				return
+			}
+			log.Debug("Exported session transcript",
+				zap.String("session", closed.Key),
+				zap.String("location", location))
+			if _, err := exporter.Prune(); err != nil {
+				log.Warn("Failed to prune transcript exports", zap.Error(err))
+			}
+		}()
+	})
+	return nil
+}
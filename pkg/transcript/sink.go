@@ -0,0 +1,223 @@
+package transcript
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nekobot/pkg/config"
+)
+
+// Sink stores exported transcript files.
+type Sink interface {
+	// Put writes data under name, replacing an earlier export of the same name.
+	Put(ctx context.Context, name, contentType string, data []byte) error
+	// Location describes where name is stored, for logs and CLI output.
+	Location(name string) string
+}
+
+// DirSink stores transcripts in a local directory.
+type DirSink struct {
+	Dir string
+}
+
+// Put writes the file atomically.
+func (s *DirSink) Put(_ context.Context, name, _ string, data []byte) error {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("create export dir: %w", err)
+	}
+	path := s.Location(name)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("write transcript: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("rename transcript: %w", err)
+	}
+	return nil
+}
+
+// Location returns the file path of name.
+func (s *DirSink) Location(name string) string {
+	return filepath.Join(s.Dir, name)
+}
+
+// Prune removes exports last written before cutoff and returns how many were
+// removed.
+func (s *DirSink) Prune(cutoff time.Time) (int, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("read export dir: %w", err)
+	}
+	removed := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || (!strings.HasSuffix(name, ".jsonl") && !strings.HasSuffix(name, ".md")) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.Dir, name)); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("remove expired transcript: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// S3Sink uploads transcripts to an S3-compatible bucket with SigV4-signed
+// PUT requests.
+type S3Sink struct {
+	cfg    config.SessionExportS3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Sink creates a sink for the configured bucket.
+func NewS3Sink(cfg config.SessionExportS3Config) (*S3Sink, error) {
+	cfg.Bucket = strings.TrimSpace(cfg.Bucket)
+	cfg.Region = strings.TrimSpace(cfg.Region)
+	cfg.Endpoint = strings.TrimRight(strings.TrimSpace(cfg.Endpoint), "/")
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+		if cfg.Endpoint == "" {
+			return nil, fmt.Errorf("s3 region or endpoint is required")
+		}
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3 access_key_id and secret_access_key are required")
+	}
+	return &S3Sink{cfg: cfg, client: &http.Client{Timeout: time.Minute}, now: time.Now}, nil
+}
+
+// Put uploads data as one object.
+func (s *S3Sink) Put(ctx context.Context, name, contentType string, data []byte) error {
+	target, err := url.Parse(s.objectURL(name))
+	if err != nil {
+		return fmt.Errorf("build s3 url: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create s3 request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, data)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload transcript: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upload transcript: s3 returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Location returns the s3:// URI of name.
+func (s *S3Sink) Location(name string) string {
+	return "s3://" + s.cfg.Bucket + "/" + s.objectKey(name)
+}
+
+func (s *S3Sink) objectKey(name string) string {
+	prefix := strings.Trim(strings.TrimSpace(s.cfg.Prefix), "/")
+	if prefix == "" {
+		return name
+	}
+	return prefix + "/" + name
+}
+
+func (s *S3Sink) objectURL(name string) string {
+	key := escapePath(s.objectKey(name))
+	if s.cfg.PathStyle {
+		return s.cfg.Endpoint + "/" + s.cfg.Bucket + "/" + key
+	}
+	scheme, host, ok := strings.Cut(s.cfg.Endpoint, "://")
+	if !ok {
+		return s.cfg.Endpoint + "/" + s.cfg.Bucket + "/" + key
+	}
+	return scheme + "://" + s.cfg.Bucket + "." + host + "/" + key
+}
+
+// sign adds AWS Signature Version 4 headers to req.
+func (s *S3Sink) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "content-type:" + req.Header.Get("Content-Type") + "\n" +
+		"host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), day)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// escapePath escapes each segment of an object key as SigV4 expects.
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,274 @@
+// Package transcript exports finished conversations, with their tool calls,
+// tool results and token usage, as JSONL or Markdown files in the workspace
+// or an S3 bucket.
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"nekobot/pkg/message"
+	"nekobot/pkg/session"
+	"nekobot/pkg/usage"
+)
+
+// Export formats.
+const (
+	FormatJSONL    = "jsonl"
+	FormatMarkdown = "markdown"
+)
+
+// Usage totals the provider calls of a conversation.
+type Usage struct {
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	CacheReadTokens  int     `json:"cache_read_tokens"`
+	CacheWriteTokens int     `json:"cache_write_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// Transcript is an exportable conversation.
+type Transcript struct {
+	SessionID  string            `json:"session_id"`
+	Title      string            `json:"title,omitempty"`
+	Source     string            `json:"source,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Providers  []string          `json:"providers,omitempty"`
+	Models     []string          `json:"models,omitempty"`
+	Usage      Usage             `json:"usage"`
+	Calls      []usage.Record    `json:"calls,omitempty"`
+	Messages   []message.Message `json:"messages"`
+}
+
+// New builds a transcript from a persisted session and its usage records.
+func New(sess *session.SessionJSONL, records []usage.Record, exportedAt time.Time) *Transcript {
+	title, _ := sess.Metadata["title"].(string)
+	source, _ := sess.Metadata["source"].(string)
+	t := &Transcript{
+		SessionID:  sess.Key,
+		Title:      title,
+		Source:     source,
+		CreatedAt:  sess.CreatedAt,
+		UpdatedAt:  sess.UpdatedAt,
+		ExportedAt: exportedAt,
+		Calls:      records,
+		Messages:   sess.Messages,
+	}
+	if t.Messages == nil {
+		t.Messages = []message.Message{}
+	}
+	providers := map[string]bool{}
+	models := map[string]bool{}
+	for _, rec := range records {
+		if rec.Provider != "" {
+			providers[rec.Provider] = true
+		}
+		if rec.Model != "" {
+			models[rec.Model] = true
+		}
+		t.Usage.Requests++
+		t.Usage.PromptTokens += rec.PromptTokens
+		t.Usage.CompletionTokens += rec.CompletionTokens
+		t.Usage.TotalTokens += rec.TotalTokens
+		t.Usage.CacheReadTokens += rec.CacheReadTokens
+		t.Usage.CacheWriteTokens += rec.CacheWriteTokens
+		t.Usage.Cost += rec.Cost
+	}
+	t.Providers = sortedKeys(providers)
+	t.Models = sortedKeys(models)
+	return t
+}
+
+// NormalizeFormat validates a format name; empty means JSONL and "md" is
+// accepted for Markdown.
+func NormalizeFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatJSONL:
+		return FormatJSONL, nil
+	case FormatMarkdown, "md":
+		return FormatMarkdown, nil
+	default:
+		return "", fmt.Errorf("unsupported transcript format %q: use jsonl or markdown", format)
+	}
+}
+
+// Extension returns the file extension of a normalized format.
+func Extension(format string) string {
+	if format == FormatMarkdown {
+		return ".md"
+	}
+	return ".jsonl"
+}
+
+// ContentType returns the MIME type of a normalized format.
+func ContentType(format string) string {
+	if format == FormatMarkdown {
+		return "text/markdown; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// Render encodes a transcript in the given format.
+func Render(t *Transcript, format string) ([]byte, error) {
+	format, err := NormalizeFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if format == FormatMarkdown {
+		err = WriteMarkdown(&buf, t)
+	} else {
+		err = WriteJSONL(&buf, t)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteJSONL writes a header line with the session and usage totals, one line
+// per message, and one line per provider call. Each line has a "type" of
+// "transcript", "message", "tool_result" or "usage".
+func WriteJSONL(w io.Writer, t *Transcript) error {
+	enc := json.NewEncoder(w)
+	header := struct {
+		Type string `json:"type"`
+		*Transcript
+		Calls    []usage.Record    `json:"calls,omitempty"`
+		Messages []message.Message `json:"messages,omitempty"`
+	}{Type: "transcript", Transcript: t}
+	if err := enc.Encode(header); err != nil {
+		return fmt.Errorf("write transcript header: %w", err)
+	}
+	for i, msg := range t.Messages {
+		kind := "message"
+		if msg.Role == "tool" {
+			kind = "tool_result"
+		}
+		line := struct {
+			Type  string `json:"type"`
+			Index int    `json:"index"`
+			message.Message
+		}{Type: kind, Index: i, Message: msg}
+		if err := enc.Encode(line); err != nil {
+			return fmt.Errorf("write transcript message: %w", err)
+		}
+	}
+	for _, rec := range t.Calls {
+		line := struct {
+			Type string `json:"type"`
+			usage.Record
+		}{Type: "usage", Record: rec}
+		if err := enc.Encode(line); err != nil {
+			return fmt.Errorf("write transcript usage: %w", err)
+		}
+	}
+	return nil
+}
+
+// WriteMarkdown writes a human-readable transcript.
+func WriteMarkdown(w io.Writer, t *Transcript) error {
+	var b strings.Builder
+	title := t.Title
+	if title == "" {
+		title = t.SessionID
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "- Session: `%s`\n", t.SessionID)
+	if t.Source != "" {
+		fmt.Fprintf(&b, "- Source: %s\n", t.Source)
+	}
+	fmt.Fprintf(&b, "- Created: %s\n", formatTime(t.CreatedAt))
+	fmt.Fprintf(&b, "- Updated: %s\n", formatTime(t.UpdatedAt))
+	if len(t.Providers) > 0 {
+		fmt.Fprintf(&b, "- Providers: %s\n", strings.Join(t.Providers, ", "))
+	}
+	if len(t.Models) > 0 {
+		fmt.Fprintf(&b, "- Models: %s\n", strings.Join(t.Models, ", "))
+	}
+	if t.Usage.Requests > 0 {
+		fmt.Fprintf(&b, "- Tokens: %d prompt, %d completion, %d total over %d requests\n",
+			t.Usage.PromptTokens, t.Usage.CompletionTokens, t.Usage.TotalTokens, t.Usage.Requests)
+		if t.Usage.Cost > 0 {
+			fmt.Fprintf(&b, "- Cost: $%.4f\n", t.Usage.Cost)
+		}
+	}
+
+	for _, msg := range t.Messages {
+		if msg.Role == "tool" {
+			fmt.Fprintf(&b, "\n### Tool result `%s`\n\n", msg.ToolCallID)
+			writeFence(&b, "", msg.Content)
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n", roleTitle(msg.Role))
+		if strings.TrimSpace(msg.Content) != "" {
+			fmt.Fprintf(&b, "\n%s\n", strings.TrimRight(msg.Content, "\n"))
+		}
+		for _, call := range msg.ToolCalls {
+			args, err := json.MarshalIndent(call.Arguments, "", "  ")
+			if err != nil {
+				args = []byte(fmt.Sprint(call.Arguments))
+			}
+			fmt.Fprintf(&b, "\n### Tool call `%s` (%s)\n\n", call.Name, call.ID)
+			writeFence(&b, "json", string(args))
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("write transcript: %w", err)
+	}
+	return nil
+}
+
+// writeFence writes content in a code fence longer than any backtick run it
+// contains.
+func writeFence(b *strings.Builder, lang, content string) {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(b, "%s%s\n%s\n%s\n", fence, lang, strings.TrimRight(content, "\n"), fence)
+}
+
+func roleTitle(role string) string {
+	switch role {
+	case "user":
+		return "User"
+	case "assistant":
+		return "Assistant"
+	case "system":
+		return "System"
+	case "":
+		return "Message"
+	default:
+		return role
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
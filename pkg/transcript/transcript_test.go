@@ -0,0 +1,219 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/session"
+	"nekobot/pkg/usage"
+)
+
+type fakeUsage map[string][]usage.Record
+
+func (f fakeUsage) SessionRecords(_ context.Context, sessionID string) ([]usage.Record, error) {
+	return f[sessionID], nil
+}
+
+func testSession() *session.SessionJSONL {
+	return &session.SessionJSONL{
+		Key:       "telegram:42",
+		CreatedAt: time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2026, 5, 1, 9, 5, 0, 0, time.UTC),
+		Metadata:  map[string]interface{}{"title": "Disk usage", "source": "channels"},
+		Messages: []session.Message{
+			{Role: "user", Content: "How full is the disk?"},
+			{Role: "assistant", ToolCalls: []session.ToolCall{{ID: "call-1", Name: "exec", Arguments: map[string]interface{}{"command": "df -h"}}}},
+			{Role: "tool", ToolCallID: "call-1", Content: "/dev/sda1  40G  31G  9G  78% /"},
+			{Role: "assistant", Content: "The root disk is 78% full."},
+		},
+	}
+}
+
+func testRecords() []usage.Record {
+	return []usage.Record{
+		{Provider: "openai", Model: "gpt-5", PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120, Cost: 0.01},
+		{Provider: "anthropic", Model: "sonnet", PromptTokens: 200, CompletionTokens: 30, TotalTokens: 230, Cost: 0.02},
+	}
+}
+
+func TestWriteJSONLIncludesMessagesToolResultsAndUsage(t *testing.T) {
+	tr := New(testSession(), testRecords(), time.Now())
+
+	var buf bytes.Buffer
+	if err := WriteJSONL(&buf, tr); err != nil {
+		t.Fatalf("WriteJSONL: %v", err)
+	}
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 1+4+2 {
+		t.Fatalf("expected 7 lines, got %d", len(lines))
+	}
+
+	header := lines[0]
+	if header["type"] != "transcript" || header["session_id"] != "telegram:42" || header["title"] != "Disk usage" {
+		t.Fatalf("unexpected header: %#v", header)
+	}
+	if _, ok := header["messages"]; ok {
+		t.Fatalf("header should not repeat messages: %#v", header)
+	}
+	totals := header["usage"].(map[string]any)
+	if totals["total_tokens"] != float64(350) || totals["requests"] != float64(2) {
+		t.Fatalf("unexpected usage totals: %#v", totals)
+	}
+	if providers := header["providers"].([]any); len(providers) != 2 || providers[0] != "anthropic" {
+		t.Fatalf("unexpected providers: %#v", providers)
+	}
+
+	if lines[2]["type"] != "message" || lines[2]["tool_calls"] == nil {
+		t.Fatalf("expected assistant tool call line, got %#v", lines[2])
+	}
+	if lines[3]["type"] != "tool_result" || lines[3]["tool_call_id"] != "call-1" {
+		t.Fatalf("expected tool result line, got %#v", lines[3])
+	}
+	if lines[5]["type"] != "usage" || lines[5]["provider"] != "openai" {
+		t.Fatalf("expected usage line, got %#v", lines[5])
+	}
+}
+
+func TestWriteMarkdownRendersToolCallsAndTotals(t *testing.T) {
+	data, err := Render(New(testSession(), testRecords(), time.Now()), "md")
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"# Disk usage",
+		"- Providers: anthropic, openai",
+		"- Tokens: 300 prompt, 50 completion, 350 total over 2 requests",
+		"## User\n\nHow full is the disk?",
+		"### Tool call `exec` (call-1)",
+		"\"command\": \"df -h\"",
+		"### Tool result `call-1`",
+		"The root disk is 78% full.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestExporterExportsRecentSessionsAndPrunesOldFiles(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Sessions.Export.RetentionDays = 30
+
+	sessions := session.NewManager(filepath.Join(t.TempDir(), "sessions"), cfg.Sessions)
+	if err := sessions.SaveJSONL("telegram:42", testSession().Messages, map[string]interface{}{"title": "Disk usage"}); err != nil {
+		t.Fatalf("save session: %v", err)
+	}
+	if err := sessions.SaveJSONL("empty", nil, nil); err != nil {
+		t.Fatalf("save empty session: %v", err)
+	}
+
+	exporter, err := NewExporter(cfg, sessions, fakeUsage{"telegram:42": testRecords()})
+	if err != nil {
+		t.Fatalf("NewExporter: %v", err)
+	}
+	dir := filepath.Join(cfg.WorkspacePath(), "exports", "transcripts")
+	stale := filepath.Join(dir, "stale.jsonl")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := exporter.ExportSince(context.Background(), time.Now().Add(time.Hour), ""); err != nil || len(got) != 0 {
+		t.Fatalf("expected no sessions updated in the future, got %v, %v", got, err)
+	}
+	locations, err := exporter.ExportSince(context.Background(), time.Now().Add(-time.Hour), "")
+	if err != nil {
+		t.Fatalf("ExportSince: %v", err)
+	}
+	want := filepath.Join(dir, "telegram_42.jsonl")
+	if len(locations) != 1 || locations[0] != want {
+		t.Fatalf("expected only %s, got %v", want, locations)
+	}
+	data, err := os.ReadFile(want)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if !strings.Contains(string(data), `"session_id":"telegram:42"`) || !strings.Contains(string(data), `"total_tokens":350`) {
+		t.Fatalf("export lacks session id or usage:\n%s", data)
+	}
+
+	removed, err := exporter.Prune()
+	if err != nil || removed != 1 {
+		t.Fatalf("expected one pruned export, got %d, %v", removed, err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("expected stale export to be removed, got %v", err)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Fatalf("expected fresh export to remain: %v", err)
+	}
+}
+
+func TestS3SinkSignsPutRequests(t *testing.T) {
+	var gotPath, gotAuth, gotBody, gotType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		gotType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	sink, err := NewS3Sink(config.SessionExportS3Config{
+		Endpoint:        srv.URL,
+		Region:          "eu-west-1",
+		Bucket:          "archive",
+		Prefix:          "/transcripts/",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		PathStyle:       true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Sink: %v", err)
+	}
+	if err := sink.Put(context.Background(), "telegram_42.jsonl", ContentType(FormatJSONL), []byte("{}\n")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	if gotPath != "/archive/transcripts/telegram_42.jsonl" || gotBody != "{}\n" || gotType != "application/x-ndjson" {
+		t.Fatalf("unexpected upload: path=%q body=%q type=%q", gotPath, gotBody, gotType)
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+		!strings.Contains(gotAuth, "/eu-west-1/s3/aws4_request") ||
+		!strings.Contains(gotAuth, "Signature=") {
+		t.Fatalf("unexpected authorization header: %q", gotAuth)
+	}
+	if got := sink.Location("telegram_42.jsonl"); got != "s3://archive/transcripts/telegram_42.jsonl" {
+		t.Fatalf("unexpected location %q", got)
+	}
+}
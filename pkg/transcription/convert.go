@@ -0,0 +1,119 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/logger"
+)
+
+// MaxAudioBytes is the largest audio payload sent for transcription, before
+// and after format conversion.
+const MaxAudioBytes = 20 * 1024 * 1024
+
+// DefaultConvertFormat is the target format when none is configured.
+const DefaultConvertFormat = "wav"
+
+// lookPath is swapped in tests to simulate a missing ffmpeg.
+var lookPath = exec.LookPath
+
+// AudioConverter re-encodes audio into another container/codec.
+type AudioConverter interface {
+	Convert(ctx context.Context, audio []byte, format string) ([]byte, error)
+}
+
+// FFmpegConverter converts audio by piping it through the ffmpeg binary.
+type FFmpegConverter struct {
+	path string
+}
+
+// NewFFmpegConverter returns a converter backed by ffmpeg on PATH, or an
+// error when ffmpeg is not installed.
+func NewFFmpegConverter() (*FFmpegConverter, error) {
+	path, err := lookPath("ffmpeg")
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg not found: %w", err)
+	}
+	return &FFmpegConverter{path: path}, nil
+}
+
+// Convert re-encodes audio to 16kHz mono in the given format ("wav" or "mp3").
+func (c *FFmpegConverter) Convert(ctx context.Context, audio []byte, format string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, c.path,
+		"-hide_banner", "-loglevel", "error",
+		"-i", "pipe:0",
+		"-ac", "1", "-ar", "16000",
+		"-f", format, "pipe:1",
+	)
+	cmd.Stdin = bytes.NewReader(audio)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg convert to %s: %w: %s", format, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// ConvertingTranscriber retries a failed transcription once after converting
+// the audio to a format providers generally accept.
+type ConvertingTranscriber struct {
+	log       *logger.Logger
+	next      Transcriber
+	converter AudioConverter
+	format    string
+}
+
+// NewConvertingTranscriber wraps next with a convert-and-retry step.
+func NewConvertingTranscriber(log *logger.Logger, next Transcriber, converter AudioConverter, format string) *ConvertingTranscriber {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = DefaultConvertFormat
+	}
+	return &ConvertingTranscriber{
+		log:       log,
+		next:      next,
+		converter: converter,
+		format:    format,
+	}
+}
+
+// Transcribe implements Transcriber.
+func (t *ConvertingTranscriber) Transcribe(ctx context.Context, audio []byte, filename string) (string, error) {
+	text, err := t.next.Transcribe(ctx, audio, filename)
+	if err == nil {
+		return text, nil
+	}
+	if strings.EqualFold(strings.TrimPrefix(filepath.Ext(filename), "."), t.format) {
+		// Already in the target format; converting again would not help.
+		return "", err
+	}
+
+	converted, convErr := t.converter.Convert(ctx, audio, t.format)
+	if convErr != nil {
+		t.log.Warn("Audio conversion after failed transcription failed", zap.Error(convErr))
+		return "", err
+	}
+	if len(converted) == 0 || len(converted) > MaxAudioBytes {
+		t.log.Warn("Converted audio unusable for transcription", zap.Int("bytes", len(converted)))
+		return "", err
+	}
+
+	base := filepath.Base(filename)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	if base == "" || base == "." {
+		base = "audio"
+	}
+	convertedName := base + "." + t.format
+	t.log.Info("Retrying transcription with converted audio",
+		zap.String("format", t.format),
+		zap.NamedError("first_error", err),
+	)
+	return t.next.Transcribe(ctx, converted, convertedName)
+}
@@ -0,0 +1,130 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+type recordedCall struct {
+	audio    []byte
+	filename string
+}
+
+// scriptedTranscriber fails for every filename listed in reject.
+type scriptedTranscriber struct {
+	reject map[string]bool
+	calls  []recordedCall
+}
+
+func (s *scriptedTranscriber) Transcribe(_ context.Context, audio []byte, filename string) (string, error) {
+	s.calls = append(s.calls, recordedCall{audio: audio, filename: filename})
+	if s.reject[filename] {
+		return "", errors.New("unsupported audio format")
+	}
+	return "hello world", nil
+}
+
+type fakeConverter struct {
+	output  []byte
+	err     error
+	formats []string
+}
+
+func (c *fakeConverter) Convert(_ context.Context, _ []byte, format string) ([]byte, error) {
+	c.formats = append(c.formats, format)
+	return c.output, c.err
+}
+
+func newTestLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+	log, err := logger.New(&logger.Config{Level: "error"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return log
+}
+
+func TestConvertingTranscriberRetriesWithConvertedAudio(t *testing.T) {
+	next := &scriptedTranscriber{reject: map[string]bool{"voice.ogg": true}}
+	converter := &fakeConverter{output: []byte("RIFF-wav")}
+	transcriber := NewConvertingTranscriber(newTestLogger(t), next, converter, "wav")
+
+	text, err := transcriber.Transcribe(context.Background(), []byte("OggS-opus"), "voice.ogg")
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if text != "hello world" {
+		t.Fatalf("unexpected text %q", text)
+	}
+	if len(converter.formats) != 1 || converter.formats[0] != "wav" {
+		t.Fatalf("expected one conversion to wav, got %v", converter.formats)
+	}
+	if len(next.calls) != 2 {
+		t.Fatalf("expected original attempt plus one retry, got %d calls", len(next.calls))
+	}
+	retry := next.calls[1]
+	if retry.filename != "voice.wav" || !bytes.Equal(retry.audio, []byte("RIFF-wav")) {
+		t.Fatalf("expected retry with converted audio, got %q (%q)", retry.filename, retry.audio)
+	}
+}
+
+func TestConvertingTranscriberSkipsConversionOnSuccess(t *testing.T) {
+	next := &scriptedTranscriber{}
+	converter := &fakeConverter{output: []byte("RIFF-wav")}
+	transcriber := NewConvertingTranscriber(newTestLogger(t), next, converter, "wav")
+
+	if _, err := transcriber.Transcribe(context.Background(), []byte("OggS"), "voice.ogg"); err != nil {
+		t.Fatalf("transcribe failed: %v", err)
+	}
+	if len(converter.formats) != 0 || len(next.calls) != 1 {
+		t.Fatalf("expected a single attempt without conversion, got %d calls, %v", len(next.calls), converter.formats)
+	}
+}
+
+func TestConvertingTranscriberReturnsOriginalErrorWhenRetryImpossible(t *testing.T) {
+	tests := []struct {
+		name      string
+		converter *fakeConverter
+	}{
+		{name: "conversion fails", converter: &fakeConverter{err: errors.New("ffmpeg crashed")}},
+		{name: "converted audio too large", converter: &fakeConverter{output: make([]byte, MaxAudioBytes+1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &scriptedTranscriber{reject: map[string]bool{"voice.ogg": true}}
+			transcriber := NewConvertingTranscriber(newTestLogger(t), next, tt.converter, "mp3")
+
+			_, err := transcriber.Transcribe(context.Background(), []byte("OggS"), "voice.ogg")
+			if err == nil || err.Error() != "unsupported audio format" {
+				t.Fatalf("expected original transcription error, got %v", err)
+			}
+			if len(next.calls) != 1 {
+				t.Fatalf("expected no retry, got %d calls", len(next.calls))
+			}
+		})
+	}
+}
+
+func TestNewFromConfigSkipsConversionWithoutFFmpeg(t *testing.T) {
+	original := lookPath
+	t.Cleanup(func() { lookPath = original })
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+
+	cfg := config.DefaultConfig()
+	cfg.Transcription.APIKey = "test-key"
+	cfg.Transcription.ConvertOnFailure = true
+
+	if _, ok := NewFromConfig(newTestLogger(t), cfg).(*WhisperClient); !ok {
+		t.Fatalf("expected plain whisper client when ffmpeg is missing")
+	}
+
+	lookPath = func(string) (string, error) { return "/usr/bin/ffmpeg", nil }
+	if _, ok := NewFromConfig(newTestLogger(t), cfg).(*ConvertingTranscriber); !ok {
+		t.Fatalf("expected converting transcriber when ffmpeg is available")
+	}
+}
@@ -0,0 +1,118 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+const (
+	defaultDeepgramBase  = "https://api.deepgram.com/v1"
+	defaultDeepgramModel = "nova-3"
+)
+
+func init() {
+	Register("deepgram", newDeepgramBackend)
+}
+
+// DeepgramClient is a Deepgram pre-recorded audio (/listen) client.
+type DeepgramClient struct {
+	apiKey     string
+	apiBase    string
+	model      string
+	language   string
+	httpClient *http.Client
+}
+
+// NewDeepgramClient creates a Deepgram client.
+func NewDeepgramClient(apiKey, apiBase, model, language string, timeout time.Duration) *DeepgramClient {
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	return &DeepgramClient{
+		apiKey:     strings.TrimSpace(apiKey),
+		apiBase:    strings.TrimRight(firstNonEmpty(apiBase, defaultDeepgramBase), "/"),
+		model:      firstNonEmpty(model, defaultDeepgramModel),
+		language:   strings.TrimSpace(language),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func newDeepgramBackend(_ *logger.Logger, cfg *config.Config, settings config.TranscriptionConfig) (Transcriber, error) {
+	apiKey := firstNonEmpty(settings.APIKey, providerAPIKey(cfg, settings.Provider, "deepgram"))
+	if err := requireAPIKey("deepgram", apiKey); err != nil {
+		return nil, err
+	}
+	timeout := time.Duration(settings.TimeoutSeconds) * time.Second
+	return NewDeepgramClient(apiKey, settings.APIBase, settings.Model, settings.Language, timeout), nil
+}
+
+// Transcribe posts the raw audio to Deepgram. Without a language hint
+// Deepgram detects the language.
+func (c *DeepgramClient) Transcribe(ctx context.Context, audio []byte, filename string) (string, error) {
+	if len(audio) == 0 {
+		return "", fmt.Errorf("audio is empty")
+	}
+
+	query := url.Values{}
+	query.Set("model", c.model)
+	query.Set("smart_format", "true")
+	if language := LanguageFromContext(ctx, c.language); language != "" {
+		query.Set("language", language)
+	} else {
+		query.Set("detect_language", "true")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiBase+"/listen?"+query.Encode(), bytes.NewReader(audio))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+c.apiKey)
+	req.Header.Set("Content-Type", audioContentType(filename))
+
+	rawResp, err := doTranscriptionRequest(c.httpClient, req, "deepgram")
+	if err != nil {
+		return "", err
+	}
+	var payload struct {
+		Results struct {
+			Channels []struct {
+				Alternatives []struct {
+					Transcript string `json:"transcript"`
+				} `json:"alternatives"`
+			} `json:"channels"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(rawResp, &payload); err != nil {
+		return "", fmt.Errorf("decoding deepgram response: %w", err)
+	}
+	if len(payload.Results.Channels) == 0 || len(payload.Results.Channels[0].Alternatives) == 0 {
+		return "", nil
+	}
+	return strings.TrimSpace(payload.Results.Channels[0].Alternatives[0].Transcript), nil
+}
+
+// audioContentType guesses the MIME type of an audio file from its name.
+func audioContentType(filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	switch ext {
+	case ".ogg", ".oga", ".opus":
+		return "audio/ogg"
+	case ".m4a":
+		return "audio/mp4"
+	}
+	if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+		return mimeType
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,142 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+const (
+	// v1p1beta1 is the REST version that accepts MP3.
+	defaultGoogleBase     = "https://speech.googleapis.com/v1p1beta1"
+	defaultGoogleLanguage = "en-US"
+)
+
+func init() {
+	Register("google", newGoogleBackend)
+}
+
+// googleLanguageCodes maps profile languages to Speech-to-Text BCP-47 codes.
+var googleLanguageCodes = map[string]string{
+	"zh": "cmn-Hans-CN",
+	"en": "en-US",
+	"ja": "ja-JP",
+}
+
+// GoogleClient is a Google Cloud Speech-to-Text client using an API key and
+// synchronous recognition, so audio is limited to about one minute.
+type GoogleClient struct {
+	apiKey     string
+	apiBase    string
+	model      string
+	language   string
+	httpClient *http.Client
+}
+
+// NewGoogleClient creates a Google Speech-to-Text client.
+func NewGoogleClient(apiKey, apiBase, model, language string, timeout time.Duration) *GoogleClient {
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	return &GoogleClient{
+		apiKey:     strings.TrimSpace(apiKey),
+		apiBase:    strings.TrimRight(firstNonEmpty(apiBase, defaultGoogleBase), "/"),
+		model:      strings.TrimSpace(model),
+		language:   strings.TrimSpace(language),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func newGoogleBackend(_ *logger.Logger, cfg *config.Config, settings config.TranscriptionConfig) (Transcriber, error) {
+	apiKey := firstNonEmpty(settings.APIKey, providerAPIKey(cfg, settings.Provider, "google"))
+	if err := requireAPIKey("google", apiKey); err != nil {
+		return nil, err
+	}
+	timeout := time.Duration(settings.TimeoutSeconds) * time.Second
+	return NewGoogleClient(apiKey, settings.APIBase, settings.Model, settings.Language, timeout), nil
+}
+
+// Transcribe sends audio to speech:recognize. Google needs a language, so
+// en-US is used when there is no hint.
+func (c *GoogleClient) Transcribe(ctx context.Context, audio []byte, filename string) (string, error) {
+	if len(audio) == 0 {
+		return "", fmt.Errorf("audio is empty")
+	}
+
+	recognition := map[string]any{
+		"languageCode":               googleLanguageCode(LanguageFromContext(ctx, c.language)),
+		"enableAutomaticPunctuation": true,
+	}
+	if c.model != "" {
+		recognition["model"] = c.model
+	}
+	// WAV and FLAC carry their encoding in the header.
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ogg", ".oga", ".opus":
+		recognition["encoding"] = "OGG_OPUS"
+		recognition["sampleRateHertz"] = 48000
+	case ".webm":
+		recognition["encoding"] = "WEBM_OPUS"
+		recognition["sampleRateHertz"] = 48000
+	case ".mp3":
+		recognition["encoding"] = "MP3"
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"config": recognition,
+		"audio":  map[string]string{"content": base64.StdEncoding.EncodeToString(audio)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("encoding recognize request: %w", err)
+	}
+
+	endpoint := c.apiBase + "/speech:recognize?key=" + url.QueryEscape(c.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rawResp, err := doTranscriptionRequest(c.httpClient, req, "google speech")
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Results []struct {
+			Alternatives []struct {
+				Transcript string `json:"transcript"`
+			} `json:"alternatives"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(rawResp, &result); err != nil {
+		return "", fmt.Errorf("decoding google speech response: %w", err)
+	}
+	var parts []string
+	for _, r := range result.Results {
+		if len(r.Alternatives) > 0 {
+			parts = append(parts, strings.TrimSpace(r.Alternatives[0].Transcript))
+		}
+	}
+	return strings.TrimSpace(strings.Join(parts, " ")), nil
+}
+
+func googleLanguageCode(language string) string {
+	language = strings.TrimSpace(language)
+	if language == "" {
+		return defaultGoogleLanguage
+	}
+	if code, ok := googleLanguageCodes[strings.ToLower(language)]; ok {
+		return code
+	}
+	return language
+}
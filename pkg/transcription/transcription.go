@@ -0,0 +1,204 @@
+// Package transcription provides speech-to-text integrations.
+package transcription
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+// DefaultBackend is used when transcription.backend is empty.
+const DefaultBackend = "whisper"
+
+// Transcriber is the shared interface used by channels.
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio []byte, filename string) (string, error)
+}
+
+// BackendFactory builds a transcriber. settings is the effective
+// transcription config for the channel; cfg gives access to provider
+// profiles for API key fallbacks.
+type BackendFactory func(log *logger.Logger, cfg *config.Config, settings config.TranscriptionConfig) (Transcriber, error)
+
+// Registry maintains a thread-safe registry of transcription backends.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]BackendFactory
+}
+
+// globalRegistry is the default global backend registry.
+var globalRegistry = NewRegistry()
+
+// NewRegistry creates a new backend registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: make(map[string]BackendFactory),
+	}
+}
+
+// Register registers a backend factory with the given name.
+func (r *Registry) Register(name string, factory BackendFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[normalizeBackend(name)] = factory
+}
+
+// Get retrieves the factory for a registered backend.
+func (r *Registry) Get(name string) (BackendFactory, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	factory, exists := r.factories[normalizeBackend(name)]
+	return factory, exists
+}
+
+// List returns the registered backend names, sorted.
+func (r *Registry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Register registers a backend with the global registry.
+func Register(name string, factory BackendFactory) {
+	globalRegistry.Register(name, factory)
+}
+
+// List returns the backends registered with the global registry.
+func List() []string {
+	return globalRegistry.List()
+}
+
+// NewFromConfig creates a transcriber from global config.
+// Returns nil when transcription is disabled or the backend cannot be set up.
+func NewFromConfig(log *logger.Logger, cfg *config.Config) Transcriber {
+	return NewForChannel(log, cfg, "")
+}
+
+// NewForChannel creates a transcriber for a channel type, applying its
+// transcription.channels override. Returns nil when transcription is
+// disabled or the backend cannot be set up.
+func NewForChannel(log *logger.Logger, cfg *config.Config, channelType string) Transcriber {
+	if cfg == nil {
+		return nil
+	}
+	if !cfg.Transcription.Enabled {
+		return nil
+	}
+
+	settings := SettingsForChannel(cfg.Transcription, channelType)
+	backend := normalizeBackend(settings.Backend)
+	factory, ok := globalRegistry.Get(backend)
+	if !ok {
+		log.Warn("Transcription disabled: unknown backend",
+			zap.String("backend", backend),
+			zap.Strings("available", List()))
+		return nil
+	}
+	transcriber, err := factory(log, cfg, settings)
+	if err != nil {
+		log.Warn("Transcription disabled", zap.String("backend", backend), zap.Error(err))
+		return nil
+	}
+	if !settings.ConvertOnFailure {
+		return transcriber
+	}
+	converter, err := NewFFmpegConverter()
+	if err != nil {
+		log.Warn("Transcription format conversion disabled", zap.Error(err))
+		return transcriber
+	}
+	return NewConvertingTranscriber(log, transcriber, converter, settings.ConvertFormat)
+}
+
+// SettingsForChannel merges the transcription.channels override for
+// channelType into the top-level settings.
+func SettingsForChannel(base config.TranscriptionConfig, channelType string) config.TranscriptionConfig {
+	settings := base
+	settings.Channels = nil
+	override, ok := base.Channels[strings.ToLower(strings.TrimSpace(channelType))]
+	if !ok {
+		return settings
+	}
+	if override.Backend != "" && normalizeBackend(override.Backend) != normalizeBackend(base.Backend) {
+		settings.Backend = override.Backend
+		settings.Provider = ""
+		settings.APIKey = ""
+		settings.APIBase = ""
+		settings.Model = ""
+	}
+	if override.APIKey != "" {
+		settings.APIKey = override.APIKey
+	}
+	if override.APIBase != "" {
+		settings.APIBase = override.APIBase
+	}
+	if override.Model != "" {
+		settings.Model = override.Model
+	}
+	if override.Language != "" {
+		settings.Language = override.Language
+	}
+	return settings
+}
+
+type languageKey struct{}
+
+// WithLanguage attaches a language hint (e.g. the user's profile language)
+// for backends that accept one.
+func WithLanguage(ctx context.Context, language string) context.Context {
+	language = strings.TrimSpace(language)
+	if language == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, languageKey{}, language)
+}
+
+// LanguageFromContext returns the hint set by WithLanguage, or fallback.
+func LanguageFromContext(ctx context.Context, fallback string) string {
+	if language, ok := ctx.Value(languageKey{}).(string); ok && language != "" {
+		return language
+	}
+	return strings.TrimSpace(fallback)
+}
+
+// providerAPIKey returns the API key of the first named provider profile
+// that has one.
+func providerAPIKey(cfg *config.Config, names ...string) string {
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		if p := cfg.GetProviderConfig(name); p != nil && p.APIKey != "" {
+			return p.APIKey
+		}
+	}
+	return ""
+}
+
+func normalizeBackend(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return DefaultBackend
+	}
+	return name
+}
+
+func requireAPIKey(backend, apiKey string) error {
+	if strings.TrimSpace(apiKey) == "" {
+		return fmt.Errorf("no API key found for %s (set transcription.api_key)", backend)
+	}
+	return nil
+}
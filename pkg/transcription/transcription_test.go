@@ -0,0 +1,122 @@
+package transcription
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+func TestBuiltinBackendsAreRegistered(t *testing.T) {
+	for _, name := range []string{"whisper", "whisper-local", "deepgram", "google"} {
+		if _, ok := globalRegistry.Get(name); !ok {
+			t.Fatalf("expected backend %q to be registered, have %v", name, List())
+		}
+	}
+}
+
+func TestNewForChannelAppliesChannelOverride(t *testing.T) {
+	log := newTestLogger(t)
+	cfg := config.DefaultConfig()
+	cfg.Transcription.APIKey = "groq-key"
+	cfg.Transcription.Channels = map[string]config.TranscriptionChannelConfig{
+		"discord": {Backend: "deepgram", APIKey: "dg-key", Language: "en"},
+		"slack":   {Backend: "whisper-local", APIBase: "http://whisper:8080"},
+	}
+
+	if _, ok := NewForChannel(log, cfg, "telegram").(*WhisperClient); !ok {
+		t.Fatal("expected telegram to use the default whisper backend")
+	}
+	deepgram, ok := NewForChannel(log, cfg, "discord").(*DeepgramClient)
+	if !ok {
+		t.Fatal("expected discord to use deepgram")
+	}
+	if deepgram.apiKey != "dg-key" || deepgram.model != defaultDeepgramModel || deepgram.language != "en" {
+		t.Fatalf("unexpected deepgram client %+v", deepgram)
+	}
+	local, ok := NewForChannel(log, cfg, "slack").(*WhisperLocalClient)
+	if !ok || local.apiBase != "http://whisper:8080" || local.apiKey != "" {
+		t.Fatalf("expected slack whisper-local client without the groq key, got %+v", local)
+	}
+
+	cfg.Transcription.Channels["discord"] = config.TranscriptionChannelConfig{Backend: "deepgram"}
+	if NewForChannel(log, cfg, "discord") != nil {
+		t.Fatal("expected nil transcriber when the override backend has no API key")
+	}
+	cfg.Transcription.Backend = "unknown"
+	if NewFromConfig(log, cfg) != nil {
+		t.Fatal("expected nil transcriber for an unknown backend")
+	}
+}
+
+func TestRegisterCustomBackend(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(" Custom ", func(*logger.Logger, *config.Config, config.TranscriptionConfig) (Transcriber, error) {
+		return &scriptedTranscriber{}, nil
+	})
+	if _, ok := registry.Get("custom"); !ok {
+		t.Fatalf("expected normalized backend name, have %v", registry.List())
+	}
+}
+
+func TestLanguageHintReachesBackends(t *testing.T) {
+	var deepgramQuery, googleLanguage, whisperLanguage string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/listen":
+			deepgramQuery = r.URL.RawQuery
+			_, _ = w.Write([]byte(`{"results":{"channels":[{"alternatives":[{"transcript":"ni hao"}]}]}}`))
+		case "/speech:recognize":
+			var body struct {
+				Config struct {
+					LanguageCode string `json:"languageCode"`
+					Encoding     string `json:"encoding"`
+				} `json:"config"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			googleLanguage = body.Config.LanguageCode + "|" + body.Config.Encoding
+			_, _ = w.Write([]byte(`{"results":[{"alternatives":[{"transcript":"ni"}]},{"alternatives":[{"transcript":"hao"}]}]}`))
+		case "/audio/transcriptions":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("parse multipart form: %v", err)
+			}
+			whisperLanguage = r.FormValue("language")
+			_, _ = w.Write([]byte(`{"text":"ni hao"}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	ctx := WithLanguage(context.Background(), "zh")
+	audio := []byte("OggS")
+
+	text, err := NewDeepgramClient("key", server.URL, "", "", 0).Transcribe(ctx, audio, "voice.ogg")
+	if err != nil || text != "ni hao" {
+		t.Fatalf("deepgram: text=%q err=%v", text, err)
+	}
+	if deepgramQuery != "language=zh&model=nova-3&smart_format=true" {
+		t.Fatalf("unexpected deepgram query %q", deepgramQuery)
+	}
+
+	text, err = NewGoogleClient("key", server.URL, "", "", 0).Transcribe(ctx, audio, "voice.ogg")
+	if err != nil || text != "ni hao" {
+		t.Fatalf("google: text=%q err=%v", text, err)
+	}
+	if googleLanguage != "cmn-Hans-CN|OGG_OPUS" {
+		t.Fatalf("unexpected google config %q", googleLanguage)
+	}
+
+	whisper := NewWhisperClient(newTestLogger(t), "key", server.URL, "", 0)
+	whisper.language = "en"
+	if _, err := whisper.Transcribe(ctx, audio, "voice.ogg"); err != nil {
+		t.Fatalf("whisper: %v", err)
+	}
+	if whisperLanguage != "zh" {
+		t.Fatalf("expected context hint to win over the configured language, got %q", whisperLanguage)
+	}
+}
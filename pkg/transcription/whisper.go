@@ -1,4 +1,3 @@
-// Package transcription provides speech-to-text integrations.
 package transcription
 
 import (
@@ -24,20 +23,20 @@ const (
 	defaultGroqModel = "whisper-large-v3-turbo"
 )
 
-// Transcriber is the shared interface used by channels.
-type Transcriber interface {
-	Transcribe(ctx context.Context, audio []byte, filename string) (string, error)
-}
-
 // WhisperClient is a Groq Whisper API client.
 type WhisperClient struct {
 	log        *logger.Logger
 	apiKey     string
 	apiBase    string
 	model      string
+	language   string
 	httpClient *http.Client
 }
 
+func init() {
+	Register(DefaultBackend, newWhisperBackend)
+}
+
 // NewWhisperClient creates a Groq Whisper client.
 func NewWhisperClient(log *logger.Logger, apiKey, apiBase, model string, timeout time.Duration) *WhisperClient {
 	if timeout <= 0 {
@@ -61,28 +60,13 @@ func NewWhisperClient(log *logger.Logger, apiKey, apiBase, model string, timeout
 	}
 }
 
-// NewFromConfig creates a Whisper transcriber from global config.
-// Returns nil when transcription is disabled or no API key can be resolved.
-func NewFromConfig(log *logger.Logger, cfg *config.Config) Transcriber {
-	if cfg == nil {
-		return nil
-	}
-	if !cfg.Transcription.Enabled {
-		return nil
-	}
-
-	apiKey := strings.TrimSpace(cfg.Transcription.APIKey)
+// newWhisperBackend resolves the API key from transcription.api_key, the
+// configured provider profile, then any groq profile.
+func newWhisperBackend(log *logger.Logger, cfg *config.Config, settings config.TranscriptionConfig) (Transcriber, error) {
+	apiKey := strings.TrimSpace(settings.APIKey)
 	if apiKey == "" {
 		// Reuse provider API key, prefer explicit provider first.
-		if p := cfg.GetProviderConfig(cfg.Transcription.Provider); p != nil && p.APIKey != "" {
-			apiKey = p.APIKey
-		}
-	}
-	if apiKey == "" {
-		// Fallback to provider profile named "groq".
-		if p := cfg.GetProviderConfig("groq"); p != nil && p.APIKey != "" {
-			apiKey = p.APIKey
-		}
+		apiKey = providerAPIKey(cfg, settings.Provider, "groq")
 	}
 	if apiKey == "" {
 		for i := range cfg.Providers {
@@ -94,13 +78,12 @@ func NewFromConfig(log *logger.Logger, cfg *config.Config) Transcriber {
 		}
 	}
 	if apiKey == "" {
-		log.Warn("Transcription enabled but no API key found (set transcription.api_key or groq provider api_key)")
-		return nil
+		return nil, fmt.Errorf("no API key found (set transcription.api_key or groq provider api_key)")
 	}
 
-	apiBase := cfg.Transcription.APIBase
+	apiBase := settings.APIBase
 	if apiBase == "" {
-		if p := cfg.GetProviderConfig(cfg.Transcription.Provider); p != nil && p.APIBase != "" {
+		if p := cfg.GetProviderConfig(settings.Provider); p != nil && p.APIBase != "" {
 			apiBase = p.APIBase
 		}
 	}
@@ -109,8 +92,10 @@ func NewFromConfig(log *logger.Logger, cfg *config.Config) Transcriber {
 			apiBase = p.APIBase
 		}
 	}
-	timeout := time.Duration(cfg.Transcription.TimeoutSeconds) * time.Second
-	return NewWhisperClient(log, apiKey, apiBase, cfg.Transcription.Model, timeout)
+	timeout := time.Duration(settings.TimeoutSeconds) * time.Second
+	client := NewWhisperClient(log, apiKey, apiBase, settings.Model, timeout)
+	client.language = strings.TrimSpace(settings.Language)
+	return client, nil
 }
 
 // Transcribe sends audio bytes to Groq Whisper and returns transcribed text.
@@ -131,6 +116,11 @@ func (c *WhisperClient) Transcribe(ctx context.Context, audio []byte, filename s
 	if err := writer.WriteField("model", c.model); err != nil {
 		return "", fmt.Errorf("writing model field: %w", err)
 	}
+	if language := LanguageFromContext(ctx, c.language); language != "" {
+		if err := writer.WriteField("language", language); err != nil {
+			return "", fmt.Errorf("writing language field: %w", err)
+		}
+	}
 
 	part, err := writer.CreateFormFile("file", filepath.Base(filename))
 	if err != nil {
@@ -0,0 +1,134 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+const defaultWhisperLocalBase = "http://127.0.0.1:8080"
+
+func init() {
+	Register("whisper-local", newWhisperLocalBackend)
+}
+
+// WhisperLocalClient talks to a self-hosted whisper.cpp server
+// (examples/server), which serves POST /inference.
+type WhisperLocalClient struct {
+	apiKey     string
+	apiBase    string
+	language   string
+	httpClient *http.Client
+}
+
+// NewWhisperLocalClient creates a whisper.cpp server client. apiKey is
+// optional and only sent when the server sits behind an authenticating proxy.
+func NewWhisperLocalClient(apiKey, apiBase, language string, timeout time.Duration) *WhisperLocalClient {
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	if strings.TrimSpace(apiBase) == "" {
+		apiBase = defaultWhisperLocalBase
+	}
+	return &WhisperLocalClient{
+		apiKey:     strings.TrimSpace(apiKey),
+		apiBase:    strings.TrimRight(strings.TrimSpace(apiBase), "/"),
+		language:   strings.TrimSpace(language),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func newWhisperLocalBackend(_ *logger.Logger, _ *config.Config, settings config.TranscriptionConfig) (Transcriber, error) {
+	timeout := time.Duration(settings.TimeoutSeconds) * time.Second
+	return NewWhisperLocalClient(settings.APIKey, settings.APIBase, settings.Language, timeout), nil
+}
+
+// Transcribe uploads audio to the whisper.cpp server.
+func (c *WhisperLocalClient) Transcribe(ctx context.Context, audio []byte, filename string) (string, error) {
+	if len(audio) == 0 {
+		return "", fmt.Errorf("audio is empty")
+	}
+	if strings.TrimSpace(filename) == "" {
+		filename = "audio.wav"
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	fields := map[string]string{
+		"response_format": "json",
+		"temperature":     "0.0",
+		"language":        firstNonEmpty(LanguageFromContext(ctx, c.language), "auto"),
+	}
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return "", fmt.Errorf("writing %s field: %w", name, err)
+		}
+	}
+	part, err := writer.CreateFormFile("file", filepath.Base(filename))
+	if err != nil {
+		return "", fmt.Errorf("creating file part: %w", err)
+	}
+	if _, err := part.Write(audio); err != nil {
+		return "", fmt.Errorf("writing audio payload: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("closing multipart writer: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiBase+"/inference", &body)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	rawResp, err := doTranscriptionRequest(c.httpClient, req, "whisper.cpp")
+	if err != nil {
+		return "", err
+	}
+	var payload struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(rawResp, &payload); err != nil {
+		return "", fmt.Errorf("decoding whisper.cpp response: %w", err)
+	}
+	return strings.TrimSpace(payload.Text), nil
+}
+
+// doTranscriptionRequest sends req and returns the body of a 2xx response.
+func doTranscriptionRequest(client *http.Client, req *http.Request, name string) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling %s api: %w", name, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	rawResp, _ := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s api status %d: %s", name, resp.StatusCode, strings.TrimSpace(string(rawResp)))
+	}
+	return rawResp, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
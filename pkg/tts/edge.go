@@ -0,0 +1,75 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const defaultEdgeVoice = "en-US-AriaNeural"
+
+// lookPath is swapped in tests to simulate a missing edge-tts.
+var lookPath = exec.LookPath
+
+// EdgeSynthesizer produces speech with the edge-tts CLI
+// (https://github.com/rany2/edge-tts), which needs no API key.
+type EdgeSynthesizer struct {
+	path    string
+	voice   string
+	timeout time.Duration
+}
+
+// NewEdgeSynthesizer returns a synthesizer backed by edge-tts on PATH, or an
+// error when it is not installed.
+func NewEdgeSynthesizer(voice string, timeout time.Duration) (*EdgeSynthesizer, error) {
+	path, err := lookPath("edge-tts")
+	if err != nil {
+		return nil, fmt.Errorf("edge-tts not found: %w", err)
+	}
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	return &EdgeSynthesizer{
+		path:    path,
+		voice:   firstNonEmpty(voice, defaultEdgeVoice),
+		timeout: timeout,
+	}, nil
+}
+
+// Synthesize returns MP3 speech.
+func (s *EdgeSynthesizer) Synthesize(ctx context.Context, text string) (Audio, error) {
+	if strings.TrimSpace(text) == "" {
+		return Audio{}, fmt.Errorf("text is empty")
+	}
+	dir, err := os.MkdirTemp("", "nekobot-tts-*")
+	if err != nil {
+		return Audio{}, fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	out := filepath.Join(dir, "speech.mp3")
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, s.path, "--voice", s.voice, "--text", text, "--write-media", out)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return Audio{}, fmt.Errorf("edge-tts: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		return Audio{}, fmt.Errorf("reading edge-tts output: %w", err)
+	}
+	if len(data) == 0 {
+		return Audio{}, fmt.Errorf("edge-tts produced no audio")
+	}
+	return Audio{Data: data, MIMEType: "audio/mpeg"}, nil
+}
@@ -0,0 +1,71 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultElevenLabsBase  = "https://api.elevenlabs.io/v1"
+	defaultElevenLabsModel = "eleven_multilingual_v2"
+	// defaultElevenLabsVoice is the "Rachel" premade voice.
+	defaultElevenLabsVoice = "21m00Tcm4TlvDq8ikWAM"
+)
+
+// ElevenLabsClient is an ElevenLabs text-to-speech client.
+type ElevenLabsClient struct {
+	apiKey     string
+	apiBase    string
+	model      string
+	voice      string
+	httpClient *http.Client
+}
+
+// NewElevenLabsClient creates an ElevenLabs client. voice is a voice ID.
+func NewElevenLabsClient(apiKey, apiBase, model, voice string, timeout time.Duration) *ElevenLabsClient {
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	return &ElevenLabsClient{
+		apiKey:     strings.TrimSpace(apiKey),
+		apiBase:    strings.TrimRight(firstNonEmpty(apiBase, defaultElevenLabsBase), "/"),
+		model:      firstNonEmpty(model, defaultElevenLabsModel),
+		voice:      firstNonEmpty(voice, defaultElevenLabsVoice),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Synthesize returns MP3 speech.
+func (c *ElevenLabsClient) Synthesize(ctx context.Context, text string) (Audio, error) {
+	if strings.TrimSpace(text) == "" {
+		return Audio{}, fmt.Errorf("text is empty")
+	}
+	payload, err := json.Marshal(map[string]string{
+		"text":     text,
+		"model_id": c.model,
+	})
+	if err != nil {
+		return Audio{}, fmt.Errorf("encoding speech request: %w", err)
+	}
+
+	endpoint := c.apiBase + "/text-to-speech/" + url.PathEscape(c.voice)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return Audio{}, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("xi-api-key", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "audio/mpeg")
+
+	data, err := doAudioRequest(c.httpClient, req, "elevenlabs")
+	if err != nil {
+		return Audio{}, err
+	}
+	return Audio{Data: data, MIMEType: "audio/mpeg"}, nil
+}
@@ -0,0 +1,96 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOpenAIBase  = "https://api.openai.com/v1"
+	defaultOpenAIModel = "gpt-4o-mini-tts"
+	defaultOpenAIVoice = "alloy"
+
+	// maxAudioBytes caps the synthesized audio read from a backend.
+	maxAudioBytes = 20 * 1024 * 1024
+)
+
+// OpenAIClient is an OpenAI-compatible /audio/speech client.
+type OpenAIClient struct {
+	apiKey     string
+	apiBase    string
+	model      string
+	voice      string
+	httpClient *http.Client
+}
+
+// NewOpenAIClient creates an OpenAI speech client.
+func NewOpenAIClient(apiKey, apiBase, model, voice string, timeout time.Duration) *OpenAIClient {
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	return &OpenAIClient{
+		apiKey:     strings.TrimSpace(apiKey),
+		apiBase:    strings.TrimRight(firstNonEmpty(apiBase, defaultOpenAIBase), "/"),
+		model:      firstNonEmpty(model, defaultOpenAIModel),
+		voice:      firstNonEmpty(voice, defaultOpenAIVoice),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Synthesize returns Ogg/Opus speech, the format Telegram plays as a voice note.
+func (c *OpenAIClient) Synthesize(ctx context.Context, text string) (Audio, error) {
+	if strings.TrimSpace(text) == "" {
+		return Audio{}, fmt.Errorf("text is empty")
+	}
+	payload, err := json.Marshal(map[string]string{
+		"model":           c.model,
+		"voice":           c.voice,
+		"input":           text,
+		"response_format": "opus",
+	})
+	if err != nil {
+		return Audio{}, fmt.Errorf("encoding speech request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiBase+"/audio/speech", bytes.NewReader(payload))
+	if err != nil {
+		return Audio{}, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	data, err := doAudioRequest(c.httpClient, req, "openai speech")
+	if err != nil {
+		return Audio{}, err
+	}
+	return Audio{Data: data, MIMEType: "audio/ogg"}, nil
+}
+
+// doAudioRequest sends req and returns the audio body of a 2xx response.
+func doAudioRequest(client *http.Client, req *http.Request, name string) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling %s api: %w", name, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAudioBytes))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s response: %w", name, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s api status %d: %s", name, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%s api returned no audio", name)
+	}
+	return data, nil
+}
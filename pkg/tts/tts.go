@@ -0,0 +1,144 @@
+// Package tts provides text-to-speech integrations for voice replies.
+package tts
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+const (
+	// BackendOpenAI uses the OpenAI-compatible /audio/speech endpoint.
+	BackendOpenAI = "openai"
+	// BackendElevenLabs uses the ElevenLabs text-to-speech API.
+	BackendElevenLabs = "elevenlabs"
+	// BackendEdge shells out to the edge-tts CLI; no API key is needed.
+	BackendEdge = "edge"
+)
+
+// ErrNotSpeakable is returned for replies that are empty once markdown and
+// code are stripped, or longer than tts.max_chars.
+var ErrNotSpeakable = errors.New("text is not suitable for speech")
+
+// Audio is synthesized speech ready to upload.
+type Audio struct {
+	Data     []byte
+	MIMEType string
+}
+
+// Synthesizer is the shared interface used by channels.
+type Synthesizer interface {
+	Synthesize(ctx context.Context, text string) (Audio, error)
+}
+
+// NewFromConfig creates a synthesizer from global config. The result speaks
+// SpeakableText of its input and enforces tts.max_chars.
+// Returns nil when TTS is disabled or the backend cannot be set up.
+func NewFromConfig(log *logger.Logger, cfg *config.Config) Synthesizer {
+	if cfg == nil || !cfg.TTS.Enabled {
+		return nil
+	}
+	backend := newBackend(log, cfg)
+	if backend == nil {
+		return nil
+	}
+	return &speechFilter{next: backend, maxChars: cfg.TTS.MaxChars}
+}
+
+func newBackend(log *logger.Logger, cfg *config.Config) Synthesizer {
+
+	ttsCfg := cfg.TTS
+	timeout := time.Duration(ttsCfg.TimeoutSeconds) * time.Second
+	backend := strings.ToLower(strings.TrimSpace(ttsCfg.Backend))
+
+	switch backend {
+	case BackendEdge:
+		synth, err := NewEdgeSynthesizer(ttsCfg.Voice, timeout)
+		if err != nil {
+			log.Warn("TTS enabled but edge-tts is unavailable", zap.Error(err))
+			return nil
+		}
+		return synth
+	case BackendElevenLabs:
+		apiKey := strings.TrimSpace(ttsCfg.APIKey)
+		if apiKey == "" {
+			if p := cfg.GetProviderConfig(firstNonEmpty(ttsCfg.Provider, BackendElevenLabs)); p != nil {
+				apiKey = p.APIKey
+			}
+		}
+		if apiKey == "" {
+			log.Warn("TTS enabled but no ElevenLabs API key found (set tts.api_key)")
+			return nil
+		}
+		return NewElevenLabsClient(apiKey, ttsCfg.APIBase, ttsCfg.Model, ttsCfg.Voice, timeout)
+	case "", BackendOpenAI:
+		apiKey := strings.TrimSpace(ttsCfg.APIKey)
+		apiBase := strings.TrimSpace(ttsCfg.APIBase)
+		if p := cfg.GetProviderConfig(firstNonEmpty(ttsCfg.Provider, BackendOpenAI)); p != nil {
+			if apiKey == "" {
+				apiKey = p.APIKey
+			}
+			if apiBase == "" {
+				apiBase = p.APIBase
+			}
+		}
+		if apiKey == "" {
+			log.Warn("TTS enabled but no API key found (set tts.api_key or openai provider api_key)")
+			return nil
+		}
+		return NewOpenAIClient(apiKey, apiBase, ttsCfg.Model, ttsCfg.Voice, timeout)
+	default:
+		log.Warn("TTS disabled: unknown backend", zap.String("backend", ttsCfg.Backend))
+		return nil
+	}
+}
+
+// speechFilter cleans reply text before handing it to a backend.
+type speechFilter struct {
+	next     Synthesizer
+	maxChars int
+}
+
+func (f *speechFilter) Synthesize(ctx context.Context, text string) (Audio, error) {
+	text = SpeakableText(text)
+	if text == "" || (f.maxChars > 0 && utf8.RuneCountInString(text) > f.maxChars) {
+		return Audio{}, ErrNotSpeakable
+	}
+	return f.next.Synthesize(ctx, text)
+}
+
+var (
+	codeBlockPattern  = regexp.MustCompile("(?s)```.*?```")
+	inlineCodePattern = regexp.MustCompile("`([^`]*)`")
+	linkPattern       = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markupPattern     = regexp.MustCompile(`(?m)^\s*(#{1,6}|>|[-*+])\s+|\*{1,3}|~~|__`)
+	blankLinePattern  = regexp.MustCompile(`\n{2,}`)
+)
+
+// SpeakableText strips markdown and drops fenced code so a reply reads
+// naturally aloud. It returns "" when nothing speakable remains.
+func SpeakableText(text string) string {
+	text = codeBlockPattern.ReplaceAllString(text, "")
+	text = inlineCodePattern.ReplaceAllString(text, "$1")
+	text = linkPattern.ReplaceAllString(text, "$1")
+	text = markupPattern.ReplaceAllString(text, "")
+	text = blankLinePattern.ReplaceAllString(text, "\n")
+	return strings.TrimSpace(text)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
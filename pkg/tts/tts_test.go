@@ -0,0 +1,134 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+func newTestLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+	log, err := logger.New(&logger.Config{Level: "error"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return log
+}
+
+func TestOpenAIClientRequestsOpusSpeech(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/speech" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer sk-test" {
+			t.Errorf("unexpected auth header %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		_, _ = w.Write([]byte("OggS-audio"))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient("sk-test", server.URL, "", "nova", 0)
+	audio, err := client.Synthesize(context.Background(), "hello there")
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if string(audio.Data) != "OggS-audio" || audio.MIMEType != "audio/ogg" {
+		t.Fatalf("unexpected audio %q (%s)", audio.Data, audio.MIMEType)
+	}
+	if got["model"] != defaultOpenAIModel || got["voice"] != "nova" || got["input"] != "hello there" || got["response_format"] != "opus" {
+		t.Fatalf("unexpected request body %#v", got)
+	}
+}
+
+func TestElevenLabsClientPostsToVoicePath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/text-to-speech/voice-1" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if key := r.Header.Get("xi-api-key"); key != "el-key" {
+			t.Errorf("unexpected api key %q", key)
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"detail":"bad key"}`))
+	}))
+	defer server.Close()
+
+	client := NewElevenLabsClient("el-key", server.URL, "", "voice-1", 0)
+	if _, err := client.Synthesize(context.Background(), "hi"); err == nil {
+		t.Fatal("expected error for non-2xx response")
+	}
+}
+
+func TestNewFromConfigResolvesBackends(t *testing.T) {
+	log := newTestLogger(t)
+	cfg := config.DefaultConfig()
+	if NewFromConfig(log, cfg) != nil {
+		t.Fatal("expected nil synthesizer when tts is disabled")
+	}
+
+	cfg.TTS.Enabled = true
+	if NewFromConfig(log, cfg) != nil {
+		t.Fatal("expected nil synthesizer without an API key")
+	}
+	cfg.Providers = []config.ProviderProfile{{Name: "openai", APIKey: "sk-provider"}}
+	if _, ok := newBackend(log, cfg).(*OpenAIClient); !ok {
+		t.Fatal("expected openai client reusing the provider API key")
+	}
+
+	original := lookPath
+	t.Cleanup(func() { lookPath = original })
+	cfg.TTS.Backend = BackendEdge
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	if NewFromConfig(log, cfg) != nil {
+		t.Fatal("expected nil synthesizer when edge-tts is missing")
+	}
+	lookPath = func(string) (string, error) { return "/usr/bin/edge-tts", nil }
+	if _, ok := newBackend(log, cfg).(*EdgeSynthesizer); !ok {
+		t.Fatal("expected edge synthesizer when edge-tts is installed")
+	}
+}
+
+func TestSpeakableTextStripsMarkdownAndCode(t *testing.T) {
+	input := "# Result\n\nUse **bold** and `snake_case` from [the docs](https://x.dev).\n\n```go\nfmt.Println()\n```\n- done"
+	want := "Result\nUse bold and snake_case from the docs.\ndone"
+	if got := SpeakableText(input); got != want {
+		t.Fatalf("SpeakableText() = %q, want %q", got, want)
+	}
+}
+
+type recordingSynthesizer struct {
+	texts []string
+}
+
+func (r *recordingSynthesizer) Synthesize(_ context.Context, text string) (Audio, error) {
+	r.texts = append(r.texts, text)
+	return Audio{Data: []byte("audio"), MIMEType: "audio/ogg"}, nil
+}
+
+func TestSpeechFilterSkipsUnspeakableAndLongReplies(t *testing.T) {
+	backend := &recordingSynthesizer{}
+	filter := &speechFilter{next: backend, maxChars: 10}
+
+	if _, err := filter.Synthesize(context.Background(), "```sh\nls\n```"); !errors.Is(err, ErrNotSpeakable) {
+		t.Fatalf("expected ErrNotSpeakable for code-only reply, got %v", err)
+	}
+	if _, err := filter.Synthesize(context.Background(), "this reply is far too long"); !errors.Is(err, ErrNotSpeakable) {
+		t.Fatalf("expected ErrNotSpeakable for long reply, got %v", err)
+	}
+	if _, err := filter.Synthesize(context.Background(), "**hi**"); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if len(backend.texts) != 1 || backend.texts[0] != "hi" {
+		t.Fatalf("unexpected backend calls %#v", backend.texts)
+	}
+}
@@ -0,0 +1,129 @@
+// Package turnlimit caps how many agent turns a single user can run at the
+// same time, so one user cannot monopolize the agent across channels.
+package turnlimit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"nekobot/pkg/config"
+)
+
+// ErrBusy is returned when a user already has the maximum number of turns in
+// flight and the configured mode does not wait for a free slot.
+var ErrBusy = errors.New("too many concurrent turns for this user")
+
+// DefaultBusyMessage is sent when a turn is refused and no custom message is configured.
+const DefaultBusyMessage = "I'm still working on your previous message. Please wait for it to finish and try again."
+
+const (
+	ModeReject = "reject"
+	ModeQueue  = "queue"
+)
+
+// Limiter tracks in-flight turns per user ID. It reads cfg.TurnLimits on every
+// call, so config reloads take effect for the next turn.
+type Limiter struct {
+	cfg *config.Config
+
+	mu       sync.Mutex
+	inFlight map[string]int
+	// released is closed and replaced whenever a user's slot frees up, waking
+	// queued turns for that user.
+	released map[string]chan struct{}
+}
+
+// New creates a limiter bound to cfg.
+func New(cfg *config.Config) *Limiter {
+	return &Limiter{
+		cfg:      cfg,
+		inFlight: make(map[string]int),
+		released: make(map[string]chan struct{}),
+	}
+}
+
+// Acquire reserves a turn slot for userID. The returned release func must be
+// called when the turn ends; it is safe to call more than once. Turns without
+// a user ID, and all turns while limits are disabled, are never limited.
+func (l *Limiter) Acquire(ctx context.Context, userID string) (func(), error) {
+	userID = strings.TrimSpace(userID)
+	if l == nil || l.cfg == nil || userID == "" {
+		return func() {}, nil
+	}
+	limits := l.cfg.TurnLimits
+	if !limits.Enabled || limits.MaxPerUser < 1 {
+		return func() {}, nil
+	}
+
+	var timeout <-chan time.Time
+	if limits.QueueTimeoutSeconds > 0 {
+		timer := time.NewTimer(time.Duration(limits.QueueTimeoutSeconds) * time.Second)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+		l.mu.Lock()
+		if l.inFlight[userID] < limits.MaxPerUser {
+			l.inFlight[userID]++
+			l.mu.Unlock()
+			var once sync.Once
+			return func() { once.Do(func() { l.release(userID) }) }, nil
+		}
+		if !strings.EqualFold(strings.TrimSpace(limits.Mode), ModeQueue) {
+			l.mu.Unlock()
+			return nil, ErrBusy
+		}
+		wait, ok := l.released[userID]
+		if !ok {
+			wait = make(chan struct{})
+			l.released[userID] = wait
+		}
+		l.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-timeout:
+			return nil, ErrBusy
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// InFlight reports how many turns userID currently has running.
+func (l *Limiter) InFlight(userID string) int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight[strings.TrimSpace(userID)]
+}
+
+// BusyMessage returns the notice to send when a turn is refused.
+func (l *Limiter) BusyMessage() string {
+	if l != nil && l.cfg != nil {
+		if msg := strings.TrimSpace(l.cfg.TurnLimits.BusyMessage); msg != "" {
+			return msg
+		}
+	}
+	return DefaultBusyMessage
+}
+
+func (l *Limiter) release(userID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight[userID] <= 1 {
+		delete(l.inFlight, userID)
+	} else {
+		l.inFlight[userID]--
+	}
+	if wait, ok := l.released[userID]; ok {
+		close(wait)
+		delete(l.released, userID)
+	}
+}
@@ -0,0 +1,135 @@
+package turnlimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"nekobot/pkg/config"
+)
+
+func newLimitedConfig(mode string) *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.TurnLimits.Enabled = true
+	cfg.TurnLimits.MaxPerUser = 1
+	cfg.TurnLimits.Mode = mode
+	return cfg
+}
+
+func TestAcquireRejectsSecondTurnFromSameUser(t *testing.T) {
+	limiter := New(newLimitedConfig(ModeReject))
+	ctx := context.Background()
+
+	release, err := limiter.Acquire(ctx, "alice")
+	if err != nil {
+		t.Fatalf("first turn: %v", err)
+	}
+	if _, err := limiter.Acquire(ctx, "alice"); !errors.Is(err, ErrBusy) {
+		t.Fatalf("expected second concurrent turn to be rejected, got %v", err)
+	}
+
+	other, err := limiter.Acquire(ctx, "bob")
+	if err != nil {
+		t.Fatalf("expected a different user to be unaffected, got %v", err)
+	}
+	other()
+
+	release()
+	release() // idempotent
+	if got := limiter.InFlight("alice"); got != 0 {
+		t.Fatalf("expected no in-flight turns after release, got %d", got)
+	}
+	next, err := limiter.Acquire(ctx, "alice")
+	if err != nil {
+		t.Fatalf("expected a turn after release, got %v", err)
+	}
+	next()
+}
+
+func TestAcquireQueuesSecondTurnUntilRelease(t *testing.T) {
+	limiter := New(newLimitedConfig(ModeQueue))
+	ctx := context.Background()
+
+	release, err := limiter.Acquire(ctx, "alice")
+	if err != nil {
+		t.Fatalf("first turn: %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		next, err := limiter.Acquire(ctx, "alice")
+		if err == nil {
+			next()
+		}
+		acquired <- err
+	}()
+
+	select {
+	case err := <-acquired:
+		t.Fatalf("expected second turn to wait, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	other, err := limiter.Acquire(ctx, "bob")
+	if err != nil {
+		t.Fatalf("expected a different user to be unaffected, got %v", err)
+	}
+	other()
+
+	release()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("queued turn: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("queued turn did not start after release")
+	}
+}
+
+func TestAcquireQueueHonorsContext(t *testing.T) {
+	limiter := New(newLimitedConfig(ModeQueue))
+	release, err := limiter.Acquire(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("first turn: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Acquire(ctx, "alice"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected canceled wait, got %v", err)
+	}
+}
+
+func TestAcquireUnlimitedWhenDisabledOrAnonymous(t *testing.T) {
+	cfg := newLimitedConfig(ModeReject)
+	limiter := New(cfg)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := limiter.Acquire(ctx, ""); err != nil {
+			t.Fatalf("expected turns without a user ID to be unlimited, got %v", err)
+		}
+	}
+
+	cfg.TurnLimits.Enabled = false
+	for i := 0; i < 3; i++ {
+		if _, err := limiter.Acquire(ctx, "alice"); err != nil {
+			t.Fatalf("expected disabled limits to allow every turn, got %v", err)
+		}
+	}
+}
+
+func TestBusyMessage(t *testing.T) {
+	cfg := newLimitedConfig(ModeReject)
+	limiter := New(cfg)
+	if got := limiter.BusyMessage(); got != DefaultBusyMessage {
+		t.Fatalf("expected default notice, got %q", got)
+	}
+	cfg.TurnLimits.BusyMessage = "one at a time please"
+	if got := limiter.BusyMessage(); got != "one at a time please" {
+		t.Fatalf("expected configured notice, got %q", got)
+	}
+}
@@ -0,0 +1,389 @@
+// Package usage persists per-call token usage and aggregates it into
+// provider, user and model spend reports.
+package usage
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/storage/ent"
+	"nekobot/pkg/storage/ent/usagerecord"
+)
+
+// Grouping keys accepted by Report.
+const (
+	GroupByProvider = "provider"
+	GroupByUser     = "user"
+	GroupByModel    = "model"
+	GroupByChannel  = "channel"
+	GroupBySession  = "session"
+	// GroupByDay and GroupByWeek bucket records by local calendar day and by
+	// the Monday starting their week; row keys are YYYY-MM-DD dates.
+	GroupByDay  = "day"
+	GroupByWeek = "week"
+)
+
+// Record is the usage of one provider call.
+type Record struct {
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	UserID           string    `json:"user_id"`
+	SessionID        string    `json:"session_id"`
+	Channel          string    `json:"channel"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	TotalTokens      int       `json:"total_tokens"`
+	CacheReadTokens  int       `json:"cache_read_tokens"`  // Prompt tokens served from the prompt cache
+	CacheWriteTokens int       `json:"cache_write_tokens"` // Prompt tokens written to the prompt cache
+	Cost             float64   `json:"cost"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// ReportRow aggregates the records sharing one group key.
+type ReportRow struct {
+	Key              string  `json:"key"`
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	CacheReadTokens  int     `json:"cache_read_tokens"`
+	CacheWriteTokens int     `json:"cache_write_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// Report is an aggregated usage report for [From, To).
+type Report struct {
+	From    time.Time   `json:"from"`
+	To      time.Time   `json:"to"`
+	GroupBy string      `json:"group_by"`
+	Rows    []ReportRow `json:"rows"`
+	Total   ReportRow   `json:"total"`
+}
+
+// Manager stores usage records in the runtime database.
+type Manager struct {
+	cfg    *config.Config
+	client *ent.Client
+	now    func() time.Time
+}
+
+// NewManager creates a usage manager backed by the runtime database.
+func NewManager(cfg *config.Config, client *ent.Client) (*Manager, error) {
+	if client == nil {
+		return nil, fmt.Errorf("ent client is nil")
+	}
+	return &Manager{cfg: cfg, client: client, now: time.Now}, nil
+}
+
+// Enabled reports whether usage records are persisted.
+func (m *Manager) Enabled() bool {
+	return m != nil && m.cfg != nil && m.cfg.Usage.Enabled
+}
+
+// Record persists one usage record. The total is derived from the token
+// counts when missing, and the cost is estimated from the configured pricing
+// when not set. It is a no-op when usage tracking is disabled.
+func (m *Manager) Record(ctx context.Context, rec Record) error {
+	if !m.Enabled() {
+		return nil
+	}
+	if rec.TotalTokens == 0 {
+		rec.TotalTokens = rec.PromptTokens + rec.CompletionTokens
+	}
+	if rec.Cost == 0 {
+		rec.Cost = EstimateCost(m.cfg.Usage.Pricing, rec)
+	}
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = m.now()
+	}
+	_, err := m.client.UsageRecord.Create().
+		SetProvider(strings.TrimSpace(rec.Provider)).
+		SetModel(strings.TrimSpace(rec.Model)).
+		SetUserID(strings.TrimSpace(rec.UserID)).
+		SetSessionID(strings.TrimSpace(rec.SessionID)).
+		SetChannel(strings.TrimSpace(rec.Channel)).
+		SetPromptTokens(rec.PromptTokens).
+		SetCompletionTokens(rec.CompletionTokens).
+		SetTotalTokens(rec.TotalTokens).
+		SetCacheReadTokens(rec.CacheReadTokens).
+		SetCacheWriteTokens(rec.CacheWriteTokens).
+		SetCost(rec.Cost).
+		SetCreatedAt(rec.CreatedAt).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("save usage record: %w", err)
+	}
+	return nil
+}
+
+// Report aggregates the records created in [from, to) by groupBy.
+func (m *Manager) Report(ctx context.Context, from, to time.Time, groupBy string) (*Report, error) {
+	groupBy, err := NormalizeGroupBy(groupBy)
+	if err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("report range end must be after its start")
+	}
+	recs, err := m.client.UsageRecord.Query().
+		Where(
+			usagerecord.CreatedAtGTE(from),
+			usagerecord.CreatedAtLT(to),
+		).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query usage records: %w", err)
+	}
+
+	rows := map[string]*ReportRow{}
+	report := &Report{From: from, To: to, GroupBy: groupBy, Rows: []ReportRow{}}
+	for _, rec := range recs {
+		key := groupKey(rec, groupBy)
+		row := rows[key]
+		if row == nil {
+			row = &ReportRow{Key: key}
+			rows[key] = row
+		}
+		addToRow(row, rec)
+		addToRow(&report.Total, rec)
+	}
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if isTimeGrouping(groupBy) {
+			return a.Key < b.Key
+		}
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		if a.TotalTokens != b.TotalTokens {
+			return a.TotalTokens > b.TotalTokens
+		}
+		return a.Key < b.Key
+	})
+	report.Total.Key = "total"
+	return report, nil
+}
+
+// SessionRecords returns the usage records of one session, oldest first.
+func (m *Manager) SessionRecords(ctx context.Context, sessionID string) ([]Record, error) {
+	recs, err := m.client.UsageRecord.Query().
+		Where(usagerecord.SessionIDEQ(strings.TrimSpace(sessionID))).
+		Order(ent.Asc(usagerecord.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query session usage: %w", err)
+	}
+	out := make([]Record, 0, len(recs))
+	for _, rec := range recs {
+		out = append(out, Record{
+			Provider:         rec.Provider,
+			Model:            rec.Model,
+			UserID:           rec.UserID,
+			SessionID:        rec.SessionID,
+			Channel:          rec.Channel,
+			PromptTokens:     rec.PromptTokens,
+			CompletionTokens: rec.CompletionTokens,
+			TotalTokens:      rec.TotalTokens,
+			CacheReadTokens:  rec.CacheReadTokens,
+			CacheWriteTokens: rec.CacheWriteTokens,
+			Cost:             rec.Cost,
+			CreatedAt:        rec.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+// NormalizeGroupBy validates a grouping key; empty means provider.
+func NormalizeGroupBy(groupBy string) (string, error) {
+	switch strings.TrimSpace(strings.ToLower(groupBy)) {
+	case "", GroupByProvider:
+		return GroupByProvider, nil
+	case GroupByUser:
+		return GroupByUser, nil
+	case GroupByModel:
+		return GroupByModel, nil
+	case GroupByChannel:
+		return GroupByChannel, nil
+	case GroupBySession:
+		return GroupBySession, nil
+	case GroupByDay:
+		return GroupByDay, nil
+	case GroupByWeek:
+		return GroupByWeek, nil
+	default:
+		return "", fmt.Errorf("unsupported groupBy %q: use provider, user, model, channel, session, day or week", groupBy)
+	}
+}
+
+// isTimeGrouping reports whether rows of groupBy are time buckets, which
+// are listed in chronological order instead of by cost.
+func isTimeGrouping(groupBy string) bool {
+	return groupBy == GroupByDay || groupBy == GroupByWeek
+}
+
+// DefaultReportWindow is the range covered when a report omits its start.
+const DefaultReportWindow = 30 * 24 * time.Hour
+
+// ParseRange parses report bounds given as RFC3339 timestamps or YYYY-MM-DD
+// dates. A date end bound covers the whole day. A missing end defaults to now
+// and a missing start to DefaultReportWindow before the end.
+func ParseRange(fromValue, toValue string, now time.Time) (time.Time, time.Time, error) {
+	to := now
+	if strings.TrimSpace(toValue) != "" {
+		parsed, dateOnly, err := parseBound(toValue)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to: %w", err)
+		}
+		to = parsed
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+	from := to.Add(-DefaultReportWindow)
+	if strings.TrimSpace(fromValue) != "" {
+		parsed, _, err := parseBound(fromValue)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from: %w", err)
+		}
+		from = parsed
+	}
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("to must be after from")
+	}
+	return from, to, nil
+}
+
+func parseBound(value string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%q is not an RFC3339 timestamp or YYYY-MM-DD date", value)
+	}
+	return t, true, nil
+}
+
+// EstimateCost prices a call from the pricing table. A provider-specific entry
+// wins over a model-only entry; unknown models cost nothing. Cache tokens use
+// the cache prices when set and the input price otherwise.
+func EstimateCost(pricing []config.ModelPricing, rec Record) float64 {
+	provider := strings.TrimSpace(rec.Provider)
+	model := strings.TrimSpace(rec.Model)
+	var match *config.ModelPricing
+	for i := range pricing {
+		price := &pricing[i]
+		if !strings.EqualFold(strings.TrimSpace(price.Model), model) {
+			continue
+		}
+		priceProvider := strings.TrimSpace(price.Provider)
+		if strings.EqualFold(priceProvider, provider) && priceProvider != "" {
+			match = price
+			break
+		}
+		if priceProvider == "" && match == nil {
+			match = price
+		}
+	}
+	if match == nil {
+		return 0
+	}
+	cacheReadPrice, cacheWritePrice := match.CacheReadPerMillion, match.CacheWritePerMillion
+	if cacheReadPrice == 0 {
+		cacheReadPrice = match.InputPerMillion
+	}
+	if cacheWritePrice == 0 {
+		cacheWritePrice = match.InputPerMillion
+	}
+	uncached := max(rec.PromptTokens-rec.CacheReadTokens-rec.CacheWriteTokens, 0)
+	return (float64(uncached)*match.InputPerMillion +
+		float64(rec.CacheReadTokens)*cacheReadPrice +
+		float64(rec.CacheWriteTokens)*cacheWritePrice +
+		float64(rec.CompletionTokens)*match.OutputPerMillion) / 1_000_000
+}
+
+// Estimator returns a cost estimator for calls to provider. It reads the
+// pricing table of cfg on every call, so reloaded pricing applies at once.
+func Estimator(cfg *config.Config, provider string) func(model string, promptTokens, completionTokens int) float64 {
+	return func(model string, promptTokens, completionTokens int) float64 {
+		if cfg == nil {
+			return 0
+		}
+		return EstimateCost(cfg.Usage.Pricing, Record{
+			Provider:         provider,
+			Model:            model,
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+		})
+	}
+}
+
+// WriteCSV writes the report rows followed by a total row.
+func WriteCSV(w io.Writer, report *Report) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{{report.GroupBy, "requests", "prompt_tokens", "completion_tokens", "total_tokens", "cache_read_tokens", "cache_write_tokens", "cost"}}
+	for _, row := range append(append([]ReportRow{}, report.Rows...), report.Total) {
+		rows = append(rows, []string{
+			row.Key,
+			strconv.Itoa(row.Requests),
+			strconv.Itoa(row.PromptTokens),
+			strconv.Itoa(row.CompletionTokens),
+			strconv.Itoa(row.TotalTokens),
+			strconv.Itoa(row.CacheReadTokens),
+			strconv.Itoa(row.CacheWriteTokens),
+			strconv.FormatFloat(row.Cost, 'f', 6, 64),
+		})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("write usage csv: %w", err)
+	}
+	return nil
+}
+
+func groupKey(rec *ent.UsageRecord, groupBy string) string {
+	var key string
+	switch groupBy {
+	case GroupByUser:
+		key = rec.UserID
+	case GroupByModel:
+		key = rec.Model
+	case GroupByChannel:
+		key = rec.Channel
+	case GroupBySession:
+		key = rec.SessionID
+	case GroupByDay:
+		key = rec.CreatedAt.In(time.Local).Format("2006-01-02")
+	case GroupByWeek:
+		day := rec.CreatedAt.In(time.Local)
+		// Weeks start on Monday.
+		offset := (int(day.Weekday()) + 6) % 7
+		key = day.AddDate(0, 0, -offset).Format("2006-01-02")
+	default:
+		key = rec.Provider
+	}
+	if strings.TrimSpace(key) == "" {
+		return "unknown"
+	}
+	return key
+}
+
+func addToRow(row *ReportRow, rec *ent.UsageRecord) {
+	row.Requests++
+	row.PromptTokens += rec.PromptTokens
+	row.CompletionTokens += rec.CompletionTokens
+	row.TotalTokens += rec.TotalTokens
+	row.CacheReadTokens += rec.CacheReadTokens
+	row.CacheWriteTokens += rec.CacheWriteTokens
+	row.Cost += rec.Cost
+}
@@ -0,0 +1,296 @@
+package usage
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"nekobot/pkg/config"
+)
+
+func newTestManager(t *testing.T, cfg *config.Config) *Manager {
+	t.Helper()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	client, err := config.OpenRuntimeEntClient(cfg)
+	if err != nil {
+		t.Fatalf("open runtime ent client: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	if err := config.EnsureRuntimeEntSchema(client); err != nil {
+		t.Fatalf("ensure runtime schema: %v", err)
+	}
+
+	mgr, err := NewManager(cfg, client)
+	if err != nil {
+		t.Fatalf("new usage manager: %v", err)
+	}
+	return mgr
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestReportGroupsRecords(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Usage.Pricing = []config.ModelPricing{
+		{Model: "gpt-5", InputPerMillion: 2, OutputPerMillion: 8},
+	}
+	mgr := newTestManager(t, cfg)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	records := []Record{
+		{Provider: "openai", Model: "gpt-5", UserID: "alice", PromptTokens: 1000, CompletionTokens: 500, CacheReadTokens: 400, CreatedAt: base},
+		{Provider: "openai", Model: "gpt-5", UserID: "bob", PromptTokens: 2000, CompletionTokens: 1000, CreatedAt: base.Add(time.Hour)},
+		{Provider: "claude", Model: "sonnet", UserID: "alice", PromptTokens: 300, CompletionTokens: 100, Cost: 0.5, CreatedAt: base.Add(2 * time.Hour)},
+	}
+	for _, rec := range records {
+		if err := mgr.Record(ctx, rec); err != nil {
+			t.Fatalf("record usage: %v", err)
+		}
+	}
+
+	from, to := base.Add(-time.Hour), base.Add(24*time.Hour)
+	report, err := mgr.Report(ctx, from, to, GroupByProvider)
+	if err != nil {
+		t.Fatalf("provider report: %v", err)
+	}
+	if len(report.Rows) != 2 {
+		t.Fatalf("expected two provider rows, got %+v", report.Rows)
+	}
+	openai := report.Rows[1]
+	if report.Rows[0].Key != "claude" || openai.Key != "openai" {
+		t.Fatalf("expected rows ordered by cost, got %+v", report.Rows)
+	}
+	if openai.Requests != 2 || openai.PromptTokens != 3000 || openai.CompletionTokens != 1500 || openai.TotalTokens != 4500 || openai.CacheReadTokens != 400 {
+		t.Fatalf("unexpected openai totals: %+v", openai)
+	}
+	// (3000*2 + 1500*8) / 1e6
+	if !almostEqual(openai.Cost, 0.018) {
+		t.Fatalf("expected estimated openai cost 0.018, got %v", openai.Cost)
+	}
+	if report.Total.Requests != 3 || report.Total.TotalTokens != 4900 || !almostEqual(report.Total.Cost, 0.518) {
+		t.Fatalf("unexpected report total: %+v", report.Total)
+	}
+
+	report, err = mgr.Report(ctx, from, to, GroupByUser)
+	if err != nil {
+		t.Fatalf("user report: %v", err)
+	}
+	byUser := map[string]ReportRow{}
+	for _, row := range report.Rows {
+		byUser[row.Key] = row
+	}
+	if byUser["alice"].Requests != 2 || byUser["bob"].Requests != 1 {
+		t.Fatalf("unexpected user grouping: %+v", report.Rows)
+	}
+
+	report, err = mgr.Report(ctx, from, to, GroupByModel)
+	if err != nil {
+		t.Fatalf("model report: %v", err)
+	}
+	if len(report.Rows) != 2 || report.GroupBy != GroupByModel {
+		t.Fatalf("unexpected model grouping: %+v", report)
+	}
+
+	if _, err := mgr.Report(ctx, from, to, "tenant"); err == nil {
+		t.Fatalf("expected unsupported groupBy to fail")
+	}
+}
+
+func TestReportFiltersByDateRange(t *testing.T) {
+	mgr := newTestManager(t, config.DefaultConfig())
+	ctx := context.Background()
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	for _, createdAt := range []time.Time{day.Add(-time.Minute), day, day.Add(23 * time.Hour), day.Add(24 * time.Hour)} {
+		if err := mgr.Record(ctx, Record{Provider: "openai", PromptTokens: 10, CreatedAt: createdAt}); err != nil {
+			t.Fatalf("record usage: %v", err)
+		}
+	}
+
+	report, err := mgr.Report(ctx, day, day.Add(24*time.Hour), GroupByProvider)
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if report.Total.Requests != 2 {
+		t.Fatalf("expected only records inside [from, to), got %d", report.Total.Requests)
+	}
+	if report.Total.TotalTokens != 20 {
+		t.Fatalf("expected total tokens derived from prompt tokens, got %d", report.Total.TotalTokens)
+	}
+}
+
+func TestReportGroupsByDayAndWeek(t *testing.T) {
+	mgr := newTestManager(t, config.DefaultConfig())
+	ctx := context.Background()
+	// 2026-03-10 is a Tuesday; its week starts on Monday 2026-03-09.
+	tuesday := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	for _, createdAt := range []time.Time{tuesday.AddDate(0, 0, 6), tuesday, tuesday.Add(time.Hour), tuesday.AddDate(0, 0, 5)} {
+		if err := mgr.Record(ctx, Record{Provider: "openai", Channel: "telegram", PromptTokens: 10, CreatedAt: createdAt}); err != nil {
+			t.Fatalf("record usage: %v", err)
+		}
+	}
+	from, to := tuesday.AddDate(0, 0, -7), tuesday.AddDate(0, 0, 7)
+
+	report, err := mgr.Report(ctx, from, to, GroupByDay)
+	if err != nil {
+		t.Fatalf("day report: %v", err)
+	}
+	var keys []string
+	for _, row := range report.Rows {
+		keys = append(keys, row.Key)
+	}
+	if strings.Join(keys, ",") != "2026-03-10,2026-03-15,2026-03-16" || report.Rows[0].Requests != 2 {
+		t.Fatalf("expected chronological day rows, got %+v", report.Rows)
+	}
+
+	report, err = mgr.Report(ctx, from, to, GroupByWeek)
+	if err != nil {
+		t.Fatalf("week report: %v", err)
+	}
+	if len(report.Rows) != 2 || report.Rows[0].Key != "2026-03-09" || report.Rows[0].Requests != 3 || report.Rows[1].Key != "2026-03-16" {
+		t.Fatalf("expected Monday-based week rows, got %+v", report.Rows)
+	}
+
+	report, err = mgr.Report(ctx, from, to, GroupByChannel)
+	if err != nil {
+		t.Fatalf("channel report: %v", err)
+	}
+	if len(report.Rows) != 1 || report.Rows[0].Key != "telegram" {
+		t.Fatalf("unexpected channel grouping: %+v", report.Rows)
+	}
+}
+
+func TestRecordSkipsWhenDisabled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Usage.Enabled = false
+	mgr := newTestManager(t, cfg)
+	ctx := context.Background()
+
+	if err := mgr.Record(ctx, Record{Provider: "openai", PromptTokens: 10}); err != nil {
+		t.Fatalf("record usage: %v", err)
+	}
+	count, err := mgr.client.UsageRecord.Query().Count(ctx)
+	if err != nil {
+		t.Fatalf("count records: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected no records when disabled, got %d", count)
+	}
+}
+
+func TestEstimateCostPrefersProviderSpecificPrice(t *testing.T) {
+	pricing := []config.ModelPricing{
+		{Model: "gpt-5", InputPerMillion: 1, OutputPerMillion: 1},
+		{Provider: "azure", Model: "GPT-5", InputPerMillion: 3, OutputPerMillion: 3},
+	}
+	if got := EstimateCost(pricing, Record{Provider: "azure", Model: "gpt-5", PromptTokens: 1_000_000}); !almostEqual(got, 3) {
+		t.Fatalf("expected provider-specific price, got %v", got)
+	}
+	if got := EstimateCost(pricing, Record{Provider: "openai", Model: "gpt-5", PromptTokens: 1_000_000}); !almostEqual(got, 1) {
+		t.Fatalf("expected model-wide price, got %v", got)
+	}
+	if got := EstimateCost(pricing, Record{Provider: "openai", Model: "unknown", PromptTokens: 1_000_000}); got != 0 {
+		t.Fatalf("expected unknown model to cost nothing, got %v", got)
+	}
+}
+
+func TestEstimateCostPricesCacheTokens(t *testing.T) {
+	pricing := []config.ModelPricing{
+		{Model: "claude", InputPerMillion: 3, OutputPerMillion: 15, CacheReadPerMillion: 0.3, CacheWritePerMillion: 3.75},
+		{Model: "plain", InputPerMillion: 2, OutputPerMillion: 4},
+	}
+	rec := Record{Model: "claude", PromptTokens: 3_000_000, CacheReadTokens: 1_000_000, CacheWriteTokens: 1_000_000, CompletionTokens: 1_000_000}
+	if got := EstimateCost(pricing, rec); !almostEqual(got, 3+0.3+3.75+15) {
+		t.Fatalf("expected cache prices to apply, got %v", got)
+	}
+	rec.Model = "plain"
+	if got := EstimateCost(pricing, rec); !almostEqual(got, 3*2+4) {
+		t.Fatalf("expected cache tokens to fall back to the input price, got %v", got)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	now := time.Date(2026, 3, 15, 8, 0, 0, 0, time.UTC)
+
+	from, to, err := ParseRange("", "", now)
+	if err != nil {
+		t.Fatalf("default range: %v", err)
+	}
+	if !to.Equal(now) || !from.Equal(now.Add(-DefaultReportWindow)) {
+		t.Fatalf("unexpected default range %v - %v", from, to)
+	}
+
+	from, to, err = ParseRange("2026-03-01T00:00:00Z", "2026-03-01T00:00:00Z", now)
+	if err == nil {
+		t.Fatalf("expected empty range to fail, got %v - %v", from, to)
+	}
+
+	from, to, err = ParseRange("2026-03-01", "2026-03-01", now)
+	if err != nil {
+		t.Fatalf("date range: %v", err)
+	}
+	if to.Sub(from) != 24*time.Hour {
+		t.Fatalf("expected a date end bound to cover the whole day, got %v - %v", from, to)
+	}
+
+	if _, _, err := ParseRange("yesterday", "", now); err == nil {
+		t.Fatalf("expected invalid bound to fail")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	report := &Report{
+		GroupBy: GroupByModel,
+		Rows:    []ReportRow{{Key: "gpt-5", Requests: 2, PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15, Cost: 0.25}},
+		Total:   ReportRow{Key: "total", Requests: 2, PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15, Cost: 0.25},
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, report); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	want := "model,requests,prompt_tokens,completion_tokens,total_tokens,cache_read_tokens,cache_write_tokens,cost\n" +
+		"gpt-5,2,10,5,15,0,0,0.250000\n" +
+		"total,2,10,5,15,0,0,0.250000\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected csv:\n%s", got)
+	}
+}
+
+func TestSessionRecordsReturnsOneSessionOldestFirst(t *testing.T) {
+	cfg := config.DefaultConfig()
+	mgr := newTestManager(t, cfg)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, rec := range []Record{
+		{Provider: "claude", SessionID: "s1", PromptTokens: 20, CreatedAt: base.Add(time.Minute)},
+		{Provider: "openai", SessionID: "s1", PromptTokens: 10, CreatedAt: base},
+		{Provider: "openai", SessionID: "s2", PromptTokens: 30, CreatedAt: base},
+	} {
+		if err := mgr.Record(ctx, rec); err != nil {
+			t.Fatalf("record usage: %v", err)
+		}
+	}
+
+	records, err := mgr.SessionRecords(ctx, "s1")
+	if err != nil {
+		t.Fatalf("SessionRecords: %v", err)
+	}
+	if len(records) != 2 || records[0].Provider != "openai" || records[1].Provider != "claude" {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+	if records[0].TotalTokens != 10 {
+		t.Fatalf("expected derived total of 10, got %d", records[0].TotalTokens)
+	}
+}
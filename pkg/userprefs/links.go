@@ -0,0 +1,319 @@
+package userprefs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LinkCodeTTL is how long a code issued by /link can be redeemed.
+const LinkCodeTTL = 10 * time.Minute
+
+// linkCodeAlphabet leaves out characters that are easy to misread.
+const linkCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+const linkCodeLength = 8
+
+// LinkedSessionPrefix prefixes the session shared by linked accounts.
+const LinkedSessionPrefix = "linked"
+
+var (
+	// ErrInvalidLinkCode is returned for unknown or expired link codes.
+	ErrInvalidLinkCode = errors.New("link code is invalid or expired")
+	// ErrLinkSameAccount is returned when a code is redeemed by the account
+	// that issued it.
+	ErrLinkSameAccount = errors.New("link code must be confirmed from another account")
+)
+
+// LinkedAccount is one channel account sharing a linked identity.
+type LinkedAccount struct {
+	Channel string `json:"channel"`
+	UserID  string `json:"user_id"`
+}
+
+type linkCode struct {
+	Channel   string    `json:"channel"`
+	UserID    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type identityRecord struct {
+	Accounts []LinkedAccount `json:"accounts"`
+}
+
+// IssueLinkCode creates a one-time code that links another account to
+// (channel, userID) when redeemed before it expires.
+func (m *Manager) IssueLinkCode(ctx context.Context, channel, userID string) (string, time.Time, error) {
+	if m == nil || m.store == nil {
+		return "", time.Time{}, fmt.Errorf("preferences store not available")
+	}
+	if err := m.pruneLinkCodes(ctx); err != nil {
+		return "", time.Time{}, err
+	}
+
+	code, err := newLinkCode()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := time.Now().Add(LinkCodeTTL)
+	entry := linkCode{
+		Channel:   strings.TrimSpace(channel),
+		UserID:    strings.TrimSpace(userID),
+		ExpiresAt: expiresAt,
+	}
+	if err := m.store.Set(ctx, linkCodeKey(code), entry); err != nil {
+		return "", time.Time{}, fmt.Errorf("save link code: %w", err)
+	}
+	return code, expiresAt, nil
+}
+
+// RedeemLinkCode links (channel, userID) with the account that issued code
+// and returns the shared identity. The issuer keeps its identity when it
+// already has one; otherwise the redeemer's is reused or a new one created.
+func (m *Manager) RedeemLinkCode(ctx context.Context, code, channel, userID string) (string, error) {
+	if m == nil || m.store == nil {
+		return "", fmt.Errorf("preferences store not available")
+	}
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return "", ErrInvalidLinkCode
+	}
+
+	v, ok, err := m.store.Get(ctx, linkCodeKey(code))
+	if err != nil {
+		return "", fmt.Errorf("read link code: %w", err)
+	}
+	if !ok {
+		return "", ErrInvalidLinkCode
+	}
+	var issued linkCode
+	if err := decodeValue(v, &issued); err != nil {
+		return "", err
+	}
+	if time.Now().After(issued.ExpiresAt) {
+		_ = m.store.Delete(ctx, linkCodeKey(code))
+		return "", ErrInvalidLinkCode
+	}
+	if key(issued.Channel, issued.UserID) == key(channel, userID) {
+		return "", ErrLinkSameAccount
+	}
+
+	issuer, _, err := m.Get(ctx, issued.Channel, issued.UserID)
+	if err != nil {
+		return "", err
+	}
+	redeemer, _, err := m.Get(ctx, channel, userID)
+	if err != nil {
+		return "", err
+	}
+	identity := issuer.LinkedIdentity
+	if identity == "" {
+		identity = redeemer.LinkedIdentity
+	}
+	if identity == "" {
+		identity, err = newIdentity()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	for _, account := range []struct {
+		channel, userID string
+		profile         Profile
+	}{
+		{issued.Channel, issued.UserID, issuer},
+		{channel, userID, redeemer},
+	} {
+		account.profile.LinkedIdentity = identity
+		if err := m.Save(ctx, account.channel, account.userID, account.profile); err != nil {
+			return "", err
+		}
+		if err := m.addIdentityAccount(ctx, identity, account.channel, account.userID); err != nil {
+			return "", err
+		}
+	}
+	if err := m.store.Delete(ctx, linkCodeKey(code)); err != nil {
+		return "", fmt.Errorf("delete link code: %w", err)
+	}
+	return identity, nil
+}
+
+// LinkedIdentity returns the identity (channel, userID) is linked to, or ""
+// when the account is not linked.
+func (m *Manager) LinkedIdentity(ctx context.Context, channel, userID string) (string, error) {
+	p, _, err := m.Get(ctx, channel, userID)
+	if err != nil {
+		return "", err
+	}
+	return p.LinkedIdentity, nil
+}
+
+// LinkedAccounts lists the accounts currently sharing identity.
+func (m *Manager) LinkedAccounts(ctx context.Context, identity string) ([]LinkedAccount, error) {
+	if m == nil || m.store == nil || identity == "" {
+		return nil, nil
+	}
+	record, err := m.identityRecord(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]LinkedAccount, 0, len(record.Accounts))
+	for _, account := range record.Accounts {
+		linked, err := m.LinkedIdentity(ctx, account.Channel, account.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if linked == identity {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts, nil
+}
+
+// Unlink detaches (channel, userID) from its linked identity. It reports
+// whether the account was linked.
+func (m *Manager) Unlink(ctx context.Context, channel, userID string) (bool, error) {
+	p, ok, err := m.Get(ctx, channel, userID)
+	if err != nil || !ok || p.LinkedIdentity == "" {
+		return false, err
+	}
+	identity := p.LinkedIdentity
+	p.LinkedIdentity = ""
+	if err := m.Save(ctx, channel, userID, p); err != nil {
+		return false, err
+	}
+
+	record, err := m.identityRecord(ctx, identity)
+	if err != nil {
+		return true, err
+	}
+	accountKey := key(channel, userID)
+	kept := record.Accounts[:0]
+	for _, account := range record.Accounts {
+		if key(account.Channel, account.UserID) != accountKey {
+			kept = append(kept, account)
+		}
+	}
+	record.Accounts = kept
+	if len(kept) == 0 {
+		return true, m.store.Delete(ctx, identityKey(identity))
+	}
+	return true, m.store.Set(ctx, identityKey(identity), record)
+}
+
+// ResolveSessionID returns the linked session of (channel, userID), or
+// sessionID when the account is not linked.
+func (m *Manager) ResolveSessionID(ctx context.Context, channel, userID, sessionID string) string {
+	identity, err := m.LinkedIdentity(ctx, channel, userID)
+	if err != nil || identity == "" {
+		return sessionID
+	}
+	return LinkedSessionID(identity)
+}
+
+// LinkedSessionID returns the session shared by the accounts of identity.
+func LinkedSessionID(identity string) string {
+	return LinkedSessionPrefix + ":" + identity
+}
+
+func (m *Manager) addIdentityAccount(ctx context.Context, identity, channel, userID string) error {
+	record, err := m.identityRecord(ctx, identity)
+	if err != nil {
+		return err
+	}
+	accountKey := key(channel, userID)
+	for _, account := range record.Accounts {
+		if key(account.Channel, account.UserID) == accountKey {
+			return nil
+		}
+	}
+	record.Accounts = append(record.Accounts, LinkedAccount{
+		Channel: strings.TrimSpace(channel),
+		UserID:  strings.TrimSpace(userID),
+	})
+	if err := m.store.Set(ctx, identityKey(identity), record); err != nil {
+		return fmt.Errorf("save linked identity: %w", err)
+	}
+	return nil
+}
+
+func (m *Manager) identityRecord(ctx context.Context, identity string) (identityRecord, error) {
+	var record identityRecord
+	v, ok, err := m.store.Get(ctx, identityKey(identity))
+	if err != nil {
+		return record, fmt.Errorf("read linked identity: %w", err)
+	}
+	if !ok {
+		return record, nil
+	}
+	err = decodeValue(v, &record)
+	return record, err
+}
+
+// pruneLinkCodes drops expired codes so abandoned /link attempts do not pile
+// up in the store.
+func (m *Manager) pruneLinkCodes(ctx context.Context) error {
+	keys, err := m.store.Keys(ctx)
+	if err != nil {
+		return fmt.Errorf("list link codes: %w", err)
+	}
+	now := time.Now()
+	for _, k := range keys {
+		if !strings.HasPrefix(k, linkCodeKey("")) {
+			continue
+		}
+		v, ok, err := m.store.Get(ctx, k)
+		if err != nil || !ok {
+			continue
+		}
+		var entry linkCode
+		if err := decodeValue(v, &entry); err != nil || now.After(entry.ExpiresAt) {
+			if err := m.store.Delete(ctx, k); err != nil {
+				return fmt.Errorf("delete expired link code: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func newLinkCode() (string, error) {
+	buf := make([]byte, linkCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate link code: %w", err)
+	}
+	for i, b := range buf {
+		buf[i] = linkCodeAlphabet[int(b)%len(linkCodeAlphabet)]
+	}
+	return string(buf), nil
+}
+
+func newIdentity() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate linked identity: %w", err)
+	}
+	return fmt.Sprintf("%x", buf), nil
+}
+
+func linkCodeKey(code string) string {
+	return keyPrefix + ".linkcode:" + code
+}
+
+func identityKey(identity string) string {
+	return keyPrefix + ".identity:" + identity
+}
+
+func decodeValue(v interface{}, out interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal stored value: %w", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("unmarshal stored value: %w", err)
+	}
+	return nil
+}
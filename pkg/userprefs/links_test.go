@@ -0,0 +1,109 @@
+package userprefs
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nekobot/pkg/logger"
+	"nekobot/pkg/state"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+
+	log, err := logger.New(&logger.Config{Level: "error"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	store, err := state.NewFileStore(log, &state.FileStoreConfig{FilePath: filepath.Join(t.TempDir(), "state.json")})
+	if err != nil {
+		t.Fatalf("create state store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return New(store)
+}
+
+func TestLinkCodeLinksAccountsAcrossChannels(t *testing.T) {
+	m := newTestManager(t)
+	ctx := context.Background()
+	if err := m.Save(ctx, "telegram", "7", Profile{Language: "en"}); err != nil {
+		t.Fatalf("save profile: %v", err)
+	}
+
+	code, expiresAt, err := m.IssueLinkCode(ctx, "telegram", "7")
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if len(code) != linkCodeLength || time.Until(expiresAt) <= 0 {
+		t.Fatalf("unexpected code %q expiring %v", code, expiresAt)
+	}
+	if _, err := m.RedeemLinkCode(ctx, code, "telegram", "7"); !errors.Is(err, ErrLinkSameAccount) {
+		t.Fatalf("expected ErrLinkSameAccount, got %v", err)
+	}
+
+	identity, err := m.RedeemLinkCode(ctx, " "+code+" ", "discord", "42")
+	if err != nil {
+		t.Fatalf("redeem: %v", err)
+	}
+	if _, err := m.RedeemLinkCode(ctx, code, "webui", "alice"); !errors.Is(err, ErrInvalidLinkCode) {
+		t.Fatalf("expected a used code to be rejected, got %v", err)
+	}
+	profile, _, err := m.Get(ctx, "telegram", "7")
+	if err != nil || profile.LinkedIdentity != identity || profile.Language != "en" {
+		t.Fatalf("issuer profile not linked or lost settings: %+v, %v", profile, err)
+	}
+
+	// A third account joins the existing identity.
+	code, _, err = m.IssueLinkCode(ctx, "discord", "42")
+	if err != nil {
+		t.Fatalf("issue second code: %v", err)
+	}
+	if got, err := m.RedeemLinkCode(ctx, code, "webui", "alice"); err != nil || got != identity {
+		t.Fatalf("expected webui to join %q, got %q, %v", identity, got, err)
+	}
+	accounts, err := m.LinkedAccounts(ctx, identity)
+	if err != nil || len(accounts) != 3 {
+		t.Fatalf("expected 3 linked accounts, got %v, %v", accounts, err)
+	}
+	want := LinkedSessionID(identity)
+	for _, account := range accounts {
+		if got := m.ResolveSessionID(ctx, account.Channel, account.UserID, "own"); got != want {
+			t.Fatalf("expected %s:%s to resolve to %q, got %q", account.Channel, account.UserID, want, got)
+		}
+	}
+
+	if unlinked, err := m.Unlink(ctx, "discord", "42"); err != nil || !unlinked {
+		t.Fatalf("unlink: %v, %v", unlinked, err)
+	}
+	if got := m.ResolveSessionID(ctx, "discord", "42", "discord:dm"); got != "discord:dm" {
+		t.Fatalf("expected unlinked account to keep its session, got %q", got)
+	}
+	if accounts, _ := m.LinkedAccounts(ctx, identity); len(accounts) != 2 {
+		t.Fatalf("expected 2 linked accounts after unlink, got %v", accounts)
+	}
+}
+
+func TestExpiredLinkCodeIsRejectedAndPruned(t *testing.T) {
+	m := newTestManager(t)
+	ctx := context.Background()
+	expired := linkCode{Channel: "telegram", UserID: "7", ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := m.store.Set(ctx, linkCodeKey("OLDCODE1"), expired); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.store.Set(ctx, linkCodeKey("OLDCODE2"), expired); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.RedeemLinkCode(ctx, "oldcode1", "discord", "42"); !errors.Is(err, ErrInvalidLinkCode) {
+		t.Fatalf("expected ErrInvalidLinkCode, got %v", err)
+	}
+	if _, _, err := m.IssueLinkCode(ctx, "telegram", "7"); err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if ok, _ := m.store.Exists(ctx, linkCodeKey("OLDCODE2")); ok {
+		t.Fatal("expected expired code to be pruned")
+	}
+}
@@ -18,6 +18,8 @@ type Profile struct {
 	PreferredName    string    `json:"preferred_name,omitempty"`
 	Preferences      string    `json:"preferences,omitempty"`
 	SkillInstallMode string    `json:"skill_install_mode,omitempty"`
+	VoiceReplies     bool      `json:"voice_replies,omitempty"`   // Answer voice messages with a voice note
+	LinkedIdentity   string    `json:"linked_identity,omitempty"` // Shared by accounts linked with /link
 	UpdatedAt        time.Time `json:"updated_at,omitempty"`
 }
 
@@ -84,15 +86,28 @@ func (m *Manager) Clear(ctx context.Context, channel, userID string) error {
 	return m.store.Delete(ctx, key(channel, userID))
 }
 
+// SupportedLanguages lists the language codes a profile may use; the first
+// entry is the default.
+var SupportedLanguages = []string{"zh", "en", "ja"}
+
+// IsSupportedLanguage reports whether lang is one of SupportedLanguages.
+func IsSupportedLanguage(lang string) bool {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	for _, supported := range SupportedLanguages {
+		if lang == supported {
+			return true
+		}
+	}
+	return false
+}
+
 // NormalizeLanguage returns normalized language code with default zh.
 func NormalizeLanguage(lang string) string {
 	lang = strings.ToLower(strings.TrimSpace(lang))
-	switch lang {
-	case "zh", "en", "ja":
+	if IsSupportedLanguage(lang) {
 		return lang
-	default:
-		return "zh"
 	}
+	return SupportedLanguages[0]
 }
 
 // PromptContext returns a compact profile context for LLM input.
@@ -0,0 +1,226 @@
+// Package webhooks turns JSON payloads posted to named hooks (GitHub,
+// Grafana, Alertmanager, ...) into agent conversations. Each hook renders
+// its payload through a Go template and can forward the agent's reply to a
+// channel session.
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+const (
+	maxPayloadBytes = 1 << 20
+	runTimeout      = 10 * time.Minute
+	maxPromptChars  = 16000
+)
+
+// DefaultTemplate is used when a hook has no template of its own.
+const DefaultTemplate = "Webhook \"{{hook}}\" received the following payload. " +
+	"Summarize what happened and point out anything that needs attention.\n\n{{json .}}"
+
+// Runner sends a prompt to the agent within the given session and returns its reply.
+type Runner func(ctx context.Context, sessionID, prompt string) (string, error)
+
+// Handler serves POST /hooks/{name}.
+type Handler struct {
+	log    *logger.Logger
+	config *config.Config
+	bus    bus.Bus
+	run    Runner
+}
+
+// NewHandler creates a hook handler. Hooks are looked up in cfg on every
+// request so configuration reloads apply without a restart.
+func NewHandler(log *logger.Logger, cfg *config.Config, b bus.Bus, run Runner) *Handler {
+	return &Handler{log: log, config: cfg, bus: b, run: run}
+}
+
+// ServeHTTP authenticates the request, renders the prompt and hands it to the
+// agent in the background. The caller gets 202 as soon as the prompt renders,
+// since senders like GitHub give up after a few seconds.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSpace(r.PathValue("name"))
+	hook, ok := h.lookup(name)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown hook"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadBytes+1))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "failed to read body"})
+		return
+	}
+	if len(body) > maxPayloadBytes {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "payload too large"})
+		return
+	}
+	if !verify(hook.Secret, r, body) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid secret"})
+		return
+	}
+
+	var payload interface{}
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "payload must be JSON"})
+			return
+		}
+	}
+
+	prompt, err := Render(hook, payload, r.Header)
+	if err != nil {
+		h.log.Warn("Failed to render webhook prompt", zap.String("hook", hook.Name), zap.Error(err))
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		return
+	}
+	if h.run == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "agent runtime not available"})
+		return
+	}
+
+	sessionID := SessionID(hook)
+	go h.dispatch(context.WithoutCancel(r.Context()), hook, sessionID, prompt)
+	writeJSON(w, http.StatusAccepted, map[string]string{
+		"status":     "accepted",
+		"session_id": sessionID,
+	})
+}
+
+func (h *Handler) lookup(name string) (config.WebhookHookConfig, bool) {
+	if h.config == nil || name == "" {
+		return config.WebhookHookConfig{}, false
+	}
+	for _, hook := range h.config.Webhook.Hooks {
+		if strings.TrimSpace(hook.Name) == name && strings.TrimSpace(hook.Secret) != "" {
+			return hook, true
+		}
+	}
+	return config.WebhookHookConfig{}, false
+}
+
+func (h *Handler) dispatch(ctx context.Context, hook config.WebhookHookConfig, sessionID, prompt string) {
+	ctx, cancel := context.WithTimeout(ctx, runTimeout)
+	defer cancel()
+
+	reply, err := h.run(ctx, sessionID, prompt)
+	if err != nil {
+		h.log.Warn("Webhook agent run failed",
+			zap.String("hook", hook.Name),
+			zap.String("session_id", sessionID),
+			zap.Error(err),
+		)
+		return
+	}
+	channelID := strings.TrimSpace(hook.ChannelID)
+	reply = strings.TrimSpace(reply)
+	if channelID == "" || reply == "" {
+		return
+	}
+	if h.bus == nil {
+		h.log.Warn("Webhook reply dropped: message bus is not available", zap.String("hook", hook.Name))
+		return
+	}
+	msg := &bus.Message{
+		ID:        "webhook:" + uuid.NewString(),
+		ChannelID: channelID,
+		SessionID: sessionID,
+		Type:      bus.MessageTypeText,
+		Content:   reply,
+		Data: map[string]interface{}{
+			"source": "webhook",
+			"hook":   hook.Name,
+		},
+		Timestamp: time.Now(),
+	}
+	if err := h.bus.SendOutbound(msg); err != nil {
+		h.log.Warn("Failed to deliver webhook reply", zap.String("hook", hook.Name), zap.Error(err))
+	}
+}
+
+// SessionID returns the session a hook's conversation lives in.
+func SessionID(hook config.WebhookHookConfig) string {
+	if sessionID := strings.TrimSpace(hook.SessionID); sessionID != "" {
+		return sessionID
+	}
+	return "webhook:" + strings.TrimSpace(hook.Name)
+}
+
+// Render executes the hook template against the decoded payload. Besides the
+// standard template functions it provides json, header and hook.
+func Render(hook config.WebhookHookConfig, payload interface{}, headers http.Header) (string, error) {
+	source := hook.Template
+	if strings.TrimSpace(source) == "" {
+		source = DefaultTemplate
+	}
+	tmpl, err := template.New(hook.Name).Funcs(template.FuncMap{
+		"json": func(value interface{}) (string, error) {
+			data, err := json.MarshalIndent(value, "", "  ")
+			return string(data), err
+		},
+		"header": headers.Get,
+		"hook":   func() string { return hook.Name },
+	}).Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, payload); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	prompt := strings.TrimSpace(sb.String())
+	if prompt == "" {
+		return "", fmt.Errorf("template rendered an empty prompt")
+	}
+	if runes := []rune(prompt); len(runes) > maxPromptChars {
+		prompt = string(runes[:maxPromptChars]) + "\n[truncated]"
+	}
+	return prompt, nil
+}
+
+// verify accepts the secret as a bearer token, an X-Webhook-Secret header or
+// a GitHub-style X-Hub-Signature-256 HMAC of the body.
+func verify(secret string, r *http.Request, body []byte) bool {
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return false
+	}
+	if signature := strings.TrimSpace(r.Header.Get("X-Hub-Signature-256")); signature != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		return hmac.Equal([]byte(signature), []byte(expected))
+	}
+	provided := strings.TrimSpace(r.Header.Get("X-Webhook-Secret"))
+	if provided == "" {
+		auth := strings.TrimSpace(r.Header.Get("Authorization"))
+		if len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+			provided = strings.TrimSpace(auth[7:])
+		}
+	}
+	return provided != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(secret)) == 1
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,176 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nekobot/pkg/bus"
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+const alertPayload = `{"status":"firing","alerts":[{"labels":{"alertname":"HighCPU"}}]}`
+
+func TestHandlerRunsAgentAndDeliversReply(t *testing.T) {
+	outbound := make(chan *bus.Message, 1)
+	type call struct{ sessionID, prompt string }
+	calls := make(chan call, 1)
+	mux := newTestMux(t, &stubBus{outbound: outbound}, func(ctx context.Context, sessionID, prompt string) (string, error) {
+		calls <- call{sessionID, prompt}
+		return "CPU is high on web-1.", nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/hooks/alerts", strings.NewReader(alertPayload))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+
+	select {
+	case got := <-calls:
+		if got.sessionID != "telegram:42" || got.prompt != "Alert HighCPU is firing" {
+			t.Fatalf("unexpected agent call %+v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("agent was not invoked")
+	}
+	select {
+	case msg := <-outbound:
+		if msg.ChannelID != "telegram" || msg.SessionID != "telegram:42" || msg.Content != "CPU is high on web-1." {
+			t.Fatalf("unexpected delivered message %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("reply was not delivered")
+	}
+}
+
+func TestHandlerAuthenticatesRequests(t *testing.T) {
+	calls := make(chan string, 4)
+	mux := newTestMux(t, &stubBus{}, func(ctx context.Context, sessionID, prompt string) (string, error) {
+		calls <- sessionID
+		return "", nil
+	})
+
+	mac := hmac.New(sha256.New, []byte("gh-secret"))
+	mac.Write([]byte(`{"action":"opened"}`))
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		header map[string]string
+		want   int
+	}{
+		{name: "unknown hook", path: "/hooks/missing", body: `{}`, header: map[string]string{"X-Webhook-Secret": "s3cret"}, want: http.StatusNotFound},
+		{name: "missing secret", path: "/hooks/alerts", body: alertPayload, want: http.StatusUnauthorized},
+		{name: "wrong secret", path: "/hooks/alerts", body: alertPayload, header: map[string]string{"X-Webhook-Secret": "nope"}, want: http.StatusUnauthorized},
+		{name: "invalid json", path: "/hooks/alerts", body: `not json`, header: map[string]string{"X-Webhook-Secret": "s3cret"}, want: http.StatusBadRequest},
+		{name: "bad signature", path: "/hooks/github", body: `{"action":"closed"}`, header: map[string]string{"X-Hub-Signature-256": signature}, want: http.StatusUnauthorized},
+		{name: "github signature", path: "/hooks/github", body: `{"action":"opened"}`, header: map[string]string{"X-Hub-Signature-256": signature, "X-GitHub-Event": "issues"}, want: http.StatusAccepted},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			for key, value := range tc.header {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tc.want {
+				t.Fatalf("expected status %d, got %d: %s", tc.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	select {
+	case sessionID := <-calls:
+		if sessionID != "webhook:github" {
+			t.Fatalf("expected default hook session, got %q", sessionID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("agent was not invoked for the signed request")
+	}
+}
+
+func TestRenderUsesHeadersAndDefaultTemplate(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("X-GitHub-Event", "push")
+	payload := map[string]interface{}{"ref": "refs/heads/main"}
+
+	prompt, err := Render(config.WebhookHookConfig{Name: "gh", Template: `{{header "X-GitHub-Event"}} to {{.ref}}`}, payload, headers)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if prompt != "push to refs/heads/main" {
+		t.Fatalf("unexpected prompt %q", prompt)
+	}
+
+	prompt, err = Render(config.WebhookHookConfig{Name: "gh"}, payload, headers)
+	if err != nil {
+		t.Fatalf("render default: %v", err)
+	}
+	if !strings.Contains(prompt, `Webhook "gh"`) || !strings.Contains(prompt, `"ref": "refs/heads/main"`) {
+		t.Fatalf("unexpected default prompt %q", prompt)
+	}
+
+	if _, err := Render(config.WebhookHookConfig{Name: "gh", Template: `{{.ref`}, payload, headers); err == nil {
+		t.Fatal("expected a template parse error")
+	}
+}
+
+func newTestMux(t *testing.T, b bus.Bus, run Runner) *http.ServeMux {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Webhook.Hooks = []config.WebhookHookConfig{
+		{
+			Name:      "alerts",
+			Secret:    "s3cret",
+			Template:  `Alert {{(index .alerts 0).labels.alertname}} is {{.status}}`,
+			ChannelID: "telegram",
+			SessionID: "telegram:42",
+		},
+		{Name: "github", Secret: "gh-secret"},
+	}
+
+	logCfg := logger.DefaultConfig()
+	logCfg.OutputPath = ""
+	log, err := logger.New(logCfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /hooks/{name}", NewHandler(log, cfg, b, run))
+	return mux
+}
+
+type stubBus struct {
+	outbound chan *bus.Message
+}
+
+func (b *stubBus) Start() error                                                  { return nil }
+func (b *stubBus) Stop() error                                                   { return nil }
+func (b *stubBus) RegisterInboundHandler(channelID string, handler bus.Handler)  {}
+func (b *stubBus) UnregisterInboundHandlers(channelID string)                    {}
+func (b *stubBus) RegisterOutboundHandler(channelID string, handler bus.Handler) {}
+func (b *stubBus) UnregisterOutboundHandlers(channelID string)                   {}
+func (b *stubBus) RegisterHandler(channelID string, handler bus.Handler)         {}
+func (b *stubBus) UnregisterHandlers(channelID string)                           {}
+func (b *stubBus) SendInbound(msg *bus.Message) error                            { return nil }
+func (b *stubBus) SendOutbound(msg *bus.Message) error {
+	if b.outbound != nil {
+		b.outbound <- msg
+	}
+	return nil
+}
+func (b *stubBus) GetMetrics() map[string]uint64 { return map[string]uint64{} }
@@ -11,6 +11,7 @@ const ModelsPage = lazy(() => import('./pages/ModelsPage'));
 const ChannelsPage = lazy(() => import('./pages/ChannelsPage'));
 const DaemonPage = lazy(() => import('./pages/DaemonPage'));
 const SystemPage = lazy(() => import('./pages/SystemPage'));
+const SpectatePage = lazy(() => import('./pages/SpectatePage'));
 
 function Loading() {
   return (
@@ -26,6 +27,7 @@ export default function App() {
       <Routes>
         <Route path="/login" element={<LoginPage />} />
         <Route path="/init" element={<InitPage />} />
+        <Route path="/spectate" element={<SpectatePage />} />
         <Route element={<AppLayout />}>
           <Route path="/chat" element={<ChatPage />} />
           <Route path="/providers" element={<ProvidersPage />} />
@@ -28,7 +28,8 @@ export async function apiFetch<T>(
 ): Promise<T> {
   const token = getToken();
   const headers: Record<string, string> = {
-    'Content-Type': 'application/json',
+    // Multipart bodies set their own content type with the boundary.
+    ...(options.body instanceof FormData ? {} : { 'Content-Type': 'application/json' }),
     ...(options.headers as Record<string, string>),
   };
   if (token) {
@@ -72,6 +73,9 @@ export const api = {
 
   delete: <T>(path: string) =>
     apiFetch<T>(path, { method: 'DELETE' }),
+
+  upload: <T>(path: string, form: FormData) =>
+    apiFetch<T>(path, { method: 'POST', body: form }),
 };
 
 
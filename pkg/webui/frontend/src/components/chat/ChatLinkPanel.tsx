@@ -0,0 +1,79 @@
+import { useState } from 'react';
+import { Link2 } from 'lucide-react';
+
+import { Button } from '@/components/ui/button';
+import { Input } from '@/components/ui/input';
+import { useChatLink, useLinkChat, useUnlinkChat } from '@/hooks/useChatLink';
+import { t } from '@/lib/i18n';
+
+/** Links the WebUI user with Telegram or Discord accounts via /link codes. */
+export function ChatLinkPanel() {
+  const { data: link } = useChatLink();
+  const linkChat = useLinkChat();
+  const unlinkChat = useUnlinkChat();
+  const [code, setCode] = useState('');
+
+  const issued = linkChat.data?.code ? linkChat.data : null;
+
+  return (
+    <div className="space-y-3">
+      <label className="eyebrow-label flex items-center gap-2 text-muted-foreground">
+        <Link2 className="h-3.5 w-3.5" />
+        {t('chatLink')}
+      </label>
+      {link?.linked ? (
+        <ul className="space-y-1 text-xs text-muted-foreground">
+          {link.accounts.map((account) => (
+            <li key={`${account.channel}:${account.user_id}`}>
+              <span className="font-medium text-foreground">{account.channel}</span> · {account.user_id}
+            </li>
+          ))}
+        </ul>
+      ) : null}
+      <div className="flex gap-2">
+        <Input
+          value={code}
+          onChange={(event) => setCode(event.target.value)}
+          placeholder={t('chatLinkCodePlaceholder')}
+          className="h-8 rounded-full text-xs"
+        />
+        <Button
+          type="button"
+          className="h-8 rounded-full px-3 text-xs"
+          disabled={!code.trim() || linkChat.isPending}
+          onClick={() => linkChat.mutate(code.trim(), { onSuccess: () => setCode('') })}
+        >
+          {t('chatLinkConfirm')}
+        </Button>
+      </div>
+      <div className="flex flex-wrap gap-2">
+        <Button
+          type="button"
+          variant="outline"
+          className="h-8 rounded-full px-3 text-xs"
+          disabled={linkChat.isPending}
+          onClick={() => linkChat.mutate('')}
+        >
+          {t('chatLinkIssue')}
+        </Button>
+        {link?.linked ? (
+          <Button
+            type="button"
+            variant="ghost"
+            className="h-8 rounded-full px-3 text-xs"
+            disabled={unlinkChat.isPending}
+            onClick={() => unlinkChat.mutate()}
+          >
+            {t('chatLinkUnlink')}
+          </Button>
+        ) : null}
+      </div>
+      {issued ? (
+        <p className="text-xs leading-5 text-foreground">
+          {t('chatLinkIssued', issued.code ?? '')}
+        </p>
+      ) : null}
+      <p className="text-xs leading-5 text-muted-foreground">{t('chatLinkHint')}</p>
+    </div>
+  );
+}
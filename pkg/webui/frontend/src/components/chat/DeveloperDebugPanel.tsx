@@ -0,0 +1,69 @@
+import { useState } from 'react';
+import { Bug, Eye, EyeOff } from 'lucide-react';
+import { Button } from '@/components/ui/button';
+import type { ChatTurnDebug } from '@/hooks/useChat';
+import { t } from '@/lib/i18n';
+
+interface DeveloperDebugPanelProps {
+  debug: ChatTurnDebug;
+}
+
+export function DeveloperDebugPanel({ debug }: DeveloperDebugPanelProps) {
+  const [showRaw, setShowRaw] = useState(false);
+  const requests = debug.requests ?? [];
+
+  return (
+    <div className="mt-3 rounded-2xl border border-border/70 bg-card/80 p-3">
+      <div className="flex items-center justify-between gap-2">
+        <div className="flex items-center gap-2 text-[11px] uppercase tracking-[0.16em] text-muted-foreground">
+          <Bug className="h-3.5 w-3.5" />
+          {t('chatDebugTitle', String(requests.length))}
+        </div>
+        <Button
+          type="button"
+          variant="ghost"
+          className="h-8 rounded-full px-3 text-xs"
+          onClick={() => setShowRaw((value) => !value)}
+        >
+          {showRaw ? <EyeOff className="mr-2 h-3.5 w-3.5" /> : <Eye className="mr-2 h-3.5 w-3.5" />}
+          {showRaw ? t('chatDebugHideRaw') : t('chatDebugShowRaw')}
+        </Button>
+      </div>
+      <div className="mt-2 space-y-3">
+        {requests.map((item, index) => (
+          <div key={`${item.provider}-${index}`} className="space-y-2">
+            <div className="flex flex-wrap gap-2 text-xs">
+              <span className="rounded-full bg-[hsl(var(--gray-900))] px-2.5 py-1 font-medium text-white dark:bg-[hsl(var(--gray-100))] dark:text-[hsl(var(--gray-800))]">
+                #{index + 1} {item.provider}
+              </span>
+              <span className="rounded-full border border-border/70 bg-card px-2.5 py-1 text-muted-foreground">
+                {item.model}
+              </span>
+              <span className="rounded-full border border-border/70 bg-card px-2.5 py-1 text-muted-foreground">
+                {t('chatDebugMessageCount', String(item.request.messages?.length ?? 0))}
+              </span>
+            </div>
+            {showRaw ? (
+              <pre className="max-h-80 overflow-auto whitespace-pre-wrap break-all rounded-xl bg-[hsl(var(--gray-100))] p-2 text-[11px] leading-4 text-foreground dark:bg-[hsl(var(--gray-200))]">
+                {JSON.stringify(item.request, null, 2)}
+              </pre>
+            ) : (
+              <div className="space-y-1">
+                {(item.request.messages ?? []).map((message, messageIndex) => (
+                  <div key={messageIndex} className="text-xs leading-5 text-muted-foreground">
+                    <span className="font-medium text-foreground">{message.role}</span>
+                    {': '}
+                    <span className="line-clamp-3 whitespace-pre-wrap break-all">
+                      {message.content || (message.tool_calls?.length ? t('chatDebugToolCalls', String(message.tool_calls.length)) : '')}
+                    </span>
+                  </div>
+                ))}
+              </div>
+            )}
+          </div>
+        ))}
+      </div>
+      <p className="mt-2 text-[11px] leading-4 text-muted-foreground">{t('chatDebugRedactedHint')}</p>
+    </div>
+  );
+}
@@ -1,6 +1,9 @@
+import { useEffect, useState } from 'react';
+import { FileDown, ThumbsDown, ThumbsUp } from 'lucide-react';
 import { cn } from '@/lib/utils';
 import { t } from '@/lib/i18n';
-import type { ChatMessage } from '@/hooks/useChat';
+import { getToken } from '@/api/client';
+import type { ChatAttachment, ChatMessage } from '@/hooks/useChat';
 
 function formatTime(timestamp: number): string {
   return new Date(timestamp).toLocaleTimeString(undefined, {
@@ -9,11 +12,143 @@ function formatTime(timestamp: number): string {
   });
 }
 
+const URL_PATTERN = /(https?:\/\/[^\s<>"')\]]+)/g;
+
+// linkify turns bare URLs (such as the "Sources" list of web-tool answers) into links.
+function linkify(content: string) {
+  return content.split(URL_PATTERN).map((part, index) =>
+    index % 2 === 1 ? (
+      <a
+        key={index}
+        href={part}
+        target="_blank"
+        rel="noopener noreferrer"
+        className="text-[hsl(var(--brand-700))] underline underline-offset-2 break-all"
+      >
+        {part}
+      </a>
+    ) : (
+      part
+    ),
+  );
+}
+
+// useAttachmentURL returns a URL the browser can load. Files the agent sent
+// are served by the authenticated API, so they are fetched into blob URLs.
+function useAttachmentURL(attachment: ChatAttachment): string | null {
+  const url = attachment.url ?? '';
+  const authenticated = url.startsWith('/api/');
+  const [blobURL, setBlobURL] = useState<string | null>(null);
+
+  useEffect(() => {
+    if (!authenticated) return;
+    let objectURL: string | null = null;
+    let cancelled = false;
+    const token = getToken();
+    fetch(url, { headers: token ? { Authorization: `Bearer ${token}` } : {} })
+      .then((resp) => (resp.ok ? resp.blob() : Promise.reject(new Error(resp.statusText))))
+      .then((blob) => {
+        if (cancelled) return;
+        objectURL = URL.createObjectURL(blob);
+        setBlobURL(objectURL);
+      })
+      .catch(() => setBlobURL(null));
+    return () => {
+      cancelled = true;
+      if (objectURL) URL.revokeObjectURL(objectURL);
+    };
+  }, [authenticated, url]);
+
+  if (!url) return null;
+  return authenticated ? blobURL : url;
+}
+
+function AttachmentPreview({ attachment }: { attachment: ChatAttachment }) {
+  const url = useAttachmentURL(attachment);
+  if (attachment.mime_type.startsWith('image/') && url) {
+    return (
+      <a href={url} target="_blank" rel="noopener noreferrer" download={attachment.name}>
+        <img src={url} alt={attachment.name} className="max-h-80 max-w-full rounded-xl border border-[hsl(var(--brand-200))]" />
+      </a>
+    );
+  }
+  return (
+    <a
+      href={url ?? undefined}
+      download={attachment.name}
+      target="_blank"
+      rel="noopener noreferrer"
+      className={cn(
+        'inline-flex items-center gap-2 rounded-full border border-[hsl(var(--brand-200))] bg-white/90 px-3 py-1.5 text-xs text-foreground',
+        !url && 'pointer-events-none opacity-60',
+      )}
+      title={url ? attachment.name : t('chatAttachmentUnavailable')}
+    >
+      <FileDown className="h-3.5 w-3.5" />
+      {attachment.name}
+    </a>
+  );
+}
+
+type Rating = 'up' | 'down';
+
 interface MessageBubbleProps {
   message: ChatMessage;
+  onRate?: (message: ChatMessage, rating: Rating) => Promise<void>;
 }
 
-export function MessageBubble({ message }: MessageBubbleProps) {
+function FeedbackButtons({ message, onRate }: { message: ChatMessage; onRate: NonNullable<MessageBubbleProps['onRate']> }) {
+  const [rating, setRating] = useState<Rating | null>(null);
+  const [pending, setPending] = useState(false);
+
+  async function rate(next: Rating) {
+    if (pending || rating === next) return;
+    setPending(true);
+    try {
+      await onRate(message, next);
+      setRating(next);
+    } catch {
+      // the caller reports the failure
+    } finally {
+      setPending(false);
+    }
+  }
+
+  const buttonClass = (value: Rating) =>
+    cn(
+      'rounded-full p-1 transition-colors hover:bg-[hsl(var(--gray-100))] disabled:opacity-50',
+      rating === value ? 'text-[hsl(var(--brand-700))]' : 'text-muted-foreground/70',
+    );
+
+  return (
+    <span className="inline-flex items-center gap-0.5">
+      <button
+        type="button"
+        className={buttonClass('up')}
+        title={t('chatFeedbackUp')}
+        aria-label={t('chatFeedbackUp')}
+        aria-pressed={rating === 'up'}
+        disabled={pending}
+        onClick={() => void rate('up')}
+      >
+        <ThumbsUp className="h-3.5 w-3.5" />
+      </button>
+      <button
+        type="button"
+        className={buttonClass('down')}
+        title={t('chatFeedbackDown')}
+        aria-label={t('chatFeedbackDown')}
+        aria-pressed={rating === 'down'}
+        disabled={pending}
+        onClick={() => void rate('down')}
+      >
+        <ThumbsDown className="h-3.5 w-3.5" />
+      </button>
+    </span>
+  );
+}
+
+export function MessageBubble({ message, onRate }: MessageBubbleProps) {
   if (message.role === 'user') {
     return (
       <div className="flex justify-end">
@@ -33,11 +168,17 @@ export function MessageBubble({ message }: MessageBubbleProps) {
     return (
       <div className="flex justify-start">
         <div className="max-w-[88%] space-y-2">
-          <div className="rounded-[1.4rem] rounded-bl-md border border-[hsl(var(--brand-200))] bg-white/90 px-4 py-3 text-sm leading-6 text-foreground shadow-[0_18px_42px_-30px_rgba(120,55,75,0.35)] backdrop-blur whitespace-pre-wrap break-words">
-            {message.content}
-          </div>
-          <div className="eyebrow-label mono-data text-muted-foreground/80">
+          {message.content.trim() !== '' && (
+            <div className="rounded-[1.4rem] rounded-bl-md border border-[hsl(var(--brand-200))] bg-white/90 px-4 py-3 text-sm leading-6 text-foreground shadow-[0_18px_42px_-30px_rgba(120,55,75,0.35)] backdrop-blur whitespace-pre-wrap break-words">
+              {linkify(message.content)}
+            </div>
+          )}
+          {message.attachments?.map((attachment, index) => (
+            <AttachmentPreview key={`${attachment.url ?? attachment.name}-${index}`} attachment={attachment} />
+          ))}
+          <div className="eyebrow-label mono-data flex items-center gap-2 text-muted-foreground/80">
             {formatTime(message.timestamp)}
+            {onRate && message.messageIndex !== undefined && <FeedbackButtons message={message} onRate={onRate} />}
           </div>
         </div>
       </div>
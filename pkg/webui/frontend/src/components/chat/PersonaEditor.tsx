@@ -0,0 +1,60 @@
+import { useEffect, useState } from 'react';
+import { VenetianMask } from 'lucide-react';
+
+import { Button } from '@/components/ui/button';
+import { Textarea } from '@/components/ui/textarea';
+import { useChatPersona, useSetChatPersona } from '@/hooks/useSessions';
+import { t } from '@/lib/i18n';
+
+interface PersonaEditorProps {
+  sessionID: string;
+}
+
+export function PersonaEditor({ sessionID }: PersonaEditorProps) {
+  const { data: persona = '' } = useChatPersona(sessionID);
+  const setPersona = useSetChatPersona();
+  const [draft, setDraft] = useState(persona);
+
+  useEffect(() => {
+    setDraft(persona);
+  }, [persona]);
+
+  const dirty = draft.trim() !== persona.trim();
+
+  return (
+    <div className="space-y-3">
+      <label className="eyebrow-label flex items-center gap-2 text-muted-foreground">
+        <VenetianMask className="h-3.5 w-3.5" />
+        {t('chatPersona')}
+      </label>
+      <Textarea
+        value={draft}
+        onChange={(event) => setDraft(event.target.value)}
+        placeholder={t('chatPersonaPlaceholder')}
+        className="min-h-[96px] rounded-2xl border-border/70 bg-card/90 text-sm"
+      />
+      <div className="flex flex-wrap gap-2">
+        <Button
+          type="button"
+          className="h-8 rounded-full px-3 text-xs"
+          disabled={!dirty || setPersona.isPending}
+          onClick={() => setPersona.mutate({ id: sessionID, persona: draft })}
+        >
+          {t('save')}
+        </Button>
+        {persona ? (
+          <Button
+            type="button"
+            variant="ghost"
+            className="h-8 rounded-full px-3 text-xs"
+            disabled={setPersona.isPending}
+            onClick={() => setPersona.mutate({ id: sessionID, persona: '' })}
+          >
+            {t('chatPersonaClear')}
+          </Button>
+        ) : null}
+      </div>
+      <p className="text-xs leading-5 text-muted-foreground">{t('chatPersonaHint')}</p>
+    </div>
+  );
+}
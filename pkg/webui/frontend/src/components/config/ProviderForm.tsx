@@ -12,6 +12,7 @@ import {
 import { Button } from '@/components/ui/button';
 import { Input } from '@/components/ui/input';
 import { Label } from '@/components/ui/label';
+import { Textarea } from '@/components/ui/textarea';
 import {
   Select,
   SelectContent,
@@ -58,6 +59,10 @@ interface ProviderFormData {
   default_test_model: string;
   api_format: string;
   enabled: boolean;
+  stream: boolean;
+  system_prefix: string;
+  api_version: string;
+  deployments: string;
 }
 
 interface ProviderFormProps {
@@ -76,8 +81,34 @@ const PROVIDER_KINDS_REQUIRING_API_KEY = new Set([
   'anthropic',
   'gemini',
   'openrouter',
+  'azure-openai',
 ]);
 
+const AZURE_PROVIDER_KIND = 'azure-openai';
+
+// Deployments are edited as one "alias=deployment" pair per line.
+function formatDeployments(deployments?: Record<string, string> | null): string {
+  return Object.entries(deployments ?? {})
+    .map(([alias, deployment]) => `${alias}=${deployment}`)
+    .join('\n');
+}
+
+function parseDeployments(value: string): Record<string, string> {
+  const deployments: Record<string, string> = {};
+  for (const line of value.split('\n')) {
+    const index = line.indexOf('=');
+    if (index <= 0) {
+      continue;
+    }
+    const alias = line.slice(0, index).trim();
+    const deployment = line.slice(index + 1).trim();
+    if (alias && deployment) {
+      deployments[alias] = deployment;
+    }
+  }
+  return deployments;
+}
+
 function toFormData(provider: Provider | null): ProviderFormData {
   return {
     name: provider?.name ?? '',
@@ -90,6 +121,10 @@ function toFormData(provider: Provider | null): ProviderFormData {
     default_test_model: provider?.default_test_model ?? '',
     api_format: provider?.api_format || 'openai/chat_completions',
     enabled: provider?.enabled ?? true,
+    stream: provider?.stream ?? true,
+    system_prefix: provider?.system_prefix ?? '',
+    api_version: provider?.api_version ?? '',
+    deployments: formatDeployments(provider?.deployments),
   };
 }
 
@@ -132,6 +167,7 @@ export function ProviderForm({ open, onOpenChange, provider }: ProviderFormProps
   const draftName = watch('name');
   const draftAPIKey = watch('api_key');
   const selectedAPIFormat = watch('api_format');
+  const isAzure = selectedKind === AZURE_PROVIDER_KIND;
   const selectedType = useMemo(
     () => providerTypes.find((item) => item.id === selectedKind) ?? providerTypes[0] ?? null,
     [providerTypes, selectedKind],
@@ -190,6 +226,8 @@ export function ProviderForm({ open, onOpenChange, provider }: ProviderFormProps
         api_base: values.api_base || undefined,
         proxy: values.proxy || undefined,
         timeout: values.timeout ? Number(values.timeout) : undefined,
+        api_version: isAzure ? values.api_version.trim() || undefined : undefined,
+        deployments: isAzure ? parseDeployments(values.deployments) : undefined,
       },
       {
         onSuccess: (result) => {
@@ -238,6 +276,10 @@ export function ProviderForm({ open, onOpenChange, provider }: ProviderFormProps
       default_test_model: data.default_test_model.trim() || undefined,
       api_format: data.api_format.trim() || 'openai/chat_completions',
       enabled: data.enabled,
+      stream: data.stream,
+      system_prefix: data.system_prefix.trim(),
+      api_version: isAzure ? data.api_version.trim() : undefined,
+      deployments: isAzure ? parseDeployments(data.deployments) : undefined,
     };
 
     if (isEdit) {
@@ -404,6 +446,32 @@ export function ProviderForm({ open, onOpenChange, provider }: ProviderFormProps
                     />
                   </div>
 
+                  {isAzure && (
+                    <>
+                      <div className="space-y-2">
+                        <Label htmlFor="pf-api-version">{t('providerAzureAPIVersion')}</Label>
+                        <Input
+                          id="pf-api-version"
+                          placeholder="2024-10-21"
+                          {...register('api_version')}
+                          className="h-11 rounded-2xl bg-card/90"
+                        />
+                      </div>
+
+                      <div className="space-y-2">
+                        <Label htmlFor="pf-deployments">{t('providerAzureDeployments')}</Label>
+                        <Textarea
+                          id="pf-deployments"
+                          rows={3}
+                          placeholder={t('providerAzureDeploymentsPlaceholder')}
+                          {...register('deployments')}
+                          className="rounded-2xl bg-card/90 font-mono text-xs"
+                        />
+                        <p className="text-xs leading-5 text-muted-foreground">{t('providerAzureDeploymentsHint')}</p>
+                      </div>
+                    </>
+                  )}
+
                   <div className="space-y-2">
                     <Label htmlFor="pf-proxy">{t('proxyAddress')}</Label>
                     <Input
@@ -485,6 +553,18 @@ export function ProviderForm({ open, onOpenChange, provider }: ProviderFormProps
                       />
                     )}
                   </div>
+
+                  <div className="space-y-2">
+                    <Label htmlFor="pf-system-prefix">{t('providerSystemPrefix')}</Label>
+                    <Textarea
+                      id="pf-system-prefix"
+                      rows={3}
+                      placeholder={t('providerSystemPrefixPlaceholder')}
+                      {...register('system_prefix')}
+                      className="rounded-2xl bg-card/90"
+                    />
+                    <p className="text-xs leading-5 text-muted-foreground">{t('providerSystemPrefixHint')}</p>
+                  </div>
                 </section>
 
                 <section className="rounded-[24px] border border-border/70 bg-card/70 p-4">
@@ -507,6 +587,25 @@ export function ProviderForm({ open, onOpenChange, provider }: ProviderFormProps
                     />
                   </div>
 
+                  <div className="mt-4 flex flex-col gap-4 sm:flex-row sm:items-center sm:justify-between">
+                    <div className="space-y-1">
+                      <div className="text-sm font-semibold text-foreground">{t('providerStreamTitle')}</div>
+                      <p className="text-sm leading-6 text-muted-foreground">
+                        {t('providerStreamDescription')}
+                      </p>
+                    </div>
+                    <Controller
+                      name="stream"
+                      control={control}
+                      render={({ field }) => (
+                        <div className="flex items-center gap-3 rounded-full border border-border/70 bg-background px-3 py-2">
+                          <span className="text-sm text-muted-foreground">{t('enabled')}</span>
+                          <Switch checked={field.value} onCheckedChange={field.onChange} />
+                        </div>
+                      )}
+                    />
+                  </div>
+
                   {selectedType?.supports_discovery && (
                     <div className="mt-4 flex flex-col gap-3 rounded-2xl border border-dashed border-border/70 bg-background/80 p-4 sm:flex-row sm:items-center sm:justify-between">
                       <div className="space-y-1">
@@ -8,6 +8,8 @@ import '@xterm/xterm/css/xterm.css';
 interface TerminalPanelProps {
   sessionId: string;
   active: boolean;
+  /** Token from a spectator link; the terminal then only streams output. */
+  spectatorToken?: string;
 }
 
 interface WsMessage {
@@ -22,7 +24,7 @@ interface WsMessage {
   rows?: number;
 }
 
-export default function TerminalPanel({ sessionId, active }: TerminalPanelProps) {
+export default function TerminalPanel({ sessionId, active, spectatorToken }: TerminalPanelProps) {
   const containerRef = useRef<HTMLDivElement>(null);
   const termRef = useRef<Terminal | null>(null);
   const fitAddonRef = useRef<FitAddon | null>(null);
@@ -30,21 +32,22 @@ export default function TerminalPanel({ sessionId, active }: TerminalPanelProps)
   const sessionRef = useRef(sessionId);
   const inputQueueRef = useRef('');
   const sendingRef = useRef(false);
+  const readOnly = !!spectatorToken;
 
   /* ---- send resize over WS ---- */
   const sendResize = useCallback((cols: number, rows: number) => {
     const ws = wsRef.current;
-    if (!ws || ws.readyState !== WebSocket.OPEN) return;
+    if (readOnly || !ws || ws.readyState !== WebSocket.OPEN) return;
     if (cols <= 0 || rows <= 0) return;
     ws.send(JSON.stringify({ type: 'resize', cols, rows }));
-  }, []);
+  }, [readOnly]);
 
   /* ---- send input over WS (with queue) ---- */
   const sendInput = useCallback(async (data: string) => {
     const ws = wsRef.current;
-    if (!ws || ws.readyState !== WebSocket.OPEN) return;
+    if (readOnly || !ws || ws.readyState !== WebSocket.OPEN) return;
     ws.send(JSON.stringify({ type: 'input', data }));
-  }, []);
+  }, [readOnly]);
 
   const flushQueue = useCallback(async () => {
     if (sendingRef.current) return;
@@ -79,7 +82,8 @@ export default function TerminalPanel({ sessionId, active }: TerminalPanelProps)
     const isDark = document.documentElement.classList.contains('dark');
     const term = new Terminal({
       convertEol: true,
-      cursorBlink: true,
+      cursorBlink: !readOnly,
+      disableStdin: readOnly,
       fontFamily: "'SF Mono', 'Fira Code', 'Cascadia Code', Consolas, monospace",
       fontSize: 13,
       scrollback: 10000,
@@ -155,14 +159,19 @@ export default function TerminalPanel({ sessionId, active }: TerminalPanelProps)
 
     /* Connect WebSocket */
     let cancelled = false;
-    getStreamToken('tool_session_ws', sessionId)
-      .then((token) => {
+    const streamQuery = spectatorToken
+      ? Promise.resolve('spectator_token=' + encodeURIComponent(spectatorToken))
+      : getStreamToken('tool_session_ws', sessionId).then(
+          (token) => 'token=' + encodeURIComponent(token),
+        );
+    streamQuery
+      .then((query) => {
         if (cancelled) return;
         const proto = window.location.protocol === 'https:' ? 'wss' : 'ws';
         let ws: WebSocket;
         try {
           ws = new WebSocket(
-            proto + '://' + window.location.host + '/api/tool-sessions/ws?token=' + encodeURIComponent(token) + '&session_id=' + encodeURIComponent(sessionId),
+            proto + '://' + window.location.host + '/api/tool-sessions/ws?' + query + '&session_id=' + encodeURIComponent(sessionId),
           );
         } catch {
           return;
@@ -191,7 +200,10 @@ export default function TerminalPanel({ sessionId, active }: TerminalPanelProps)
       }
 
       if (msg.type === 'status') {
-        /* We don't need to do much here; the session list polling handles state */
+        /* The session list polling handles state; only explain limit kills */
+        if (!msg.running && msg.message) {
+          term.write('\r\n\x1b[31m[Resource limit exceeded] ' + msg.message + '\x1b[0m\r\n');
+        }
         return;
       }
 
@@ -257,7 +269,7 @@ export default function TerminalPanel({ sessionId, active }: TerminalPanelProps)
       termRef.current = null;
       fitAddonRef.current = null;
     };
-  }, [sessionId, queueInput, sendResize]);
+  }, [sessionId, spectatorToken, readOnly, queueInput, sendResize]);
 
   /* ---- Refit when becoming active ---- */
   useEffect(() => {
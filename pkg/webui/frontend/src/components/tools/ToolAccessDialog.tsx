@@ -15,9 +15,10 @@ import { toast } from '@/lib/notify';
 import {
   useUpdateAccess,
   useGenerateOTP,
+  useUpdateSpectator,
   type ToolSession,
 } from '@/hooks/useToolSessions';
-import { Copy, RefreshCw } from 'lucide-react';
+import { Copy, Eye, EyeOff, RefreshCw } from 'lucide-react';
 
 const ACCESS_RECORDS_KEY = 'nekobot_tool_access_records';
 
@@ -88,9 +89,12 @@ export default function ToolAccessDialog({
   const [otpCountdown, setOtpCountdown] = useState('');
   const [otpProgress, setOtpProgress] = useState(0);
   const otpTimerRef = useRef<ReturnType<typeof setInterval> | null>(null);
+  const [spectatorUrl, setSpectatorUrl] = useState('');
+  const [spectatorEnabled, setSpectatorEnabled] = useState(false);
 
   const accessMutation = useUpdateAccess();
   const otpMutation = useGenerateOTP();
+  const spectatorMutation = useUpdateSpectator();
 
   /* ---- Load or fetch access credentials ---- */
   const loadAccess = useCallback(
@@ -162,6 +166,24 @@ export default function ToolAccessDialog({
     }
   }, [session, otpMutation]);
 
+  /* ---- Spectator link ---- */
+  const updateSpectator = useCallback(
+    async (enabled: boolean) => {
+      if (!session) return;
+      try {
+        const result = await spectatorMutation.mutateAsync({
+          id: session.id,
+          enabled,
+        });
+        setSpectatorEnabled(result.spectator_enabled);
+        setSpectatorUrl(result.spectator_url || '');
+      } catch {
+        /* error handled by mutation */
+      }
+    },
+    [session, spectatorMutation],
+  );
+
   /* ---- OTP countdown timer ---- */
   useEffect(() => {
     if (otpTimerRef.current) {
@@ -203,8 +225,10 @@ export default function ToolAccessDialog({
     if (!open) {
       setOtpCode('');
       setOtpExpiresAt(0);
+      setSpectatorUrl('');
       return;
     }
+    setSpectatorEnabled(!!session?.spectator_enabled);
     loadAccess(false);
     if (session) refreshOtp();
   }, [open]); // eslint-disable-line react-hooks/exhaustive-deps
@@ -339,6 +363,52 @@ export default function ToolAccessDialog({
               <span>{otpCountdown}</span>
             </div>
           </div>
+
+          {/* Spectator link */}
+          <div className="space-y-2 pt-2 border-t">
+            <Label>{t('spectatorLink')}</Label>
+            <p className="text-xs text-muted-foreground">
+              {spectatorEnabled && !spectatorUrl
+                ? t('spectatorLinkActiveHint')
+                : t('spectatorLinkHint')}
+            </p>
+            {spectatorUrl && (
+              <div className="flex gap-2">
+                <Input value={spectatorUrl} readOnly className="font-mono text-xs" />
+                <Button
+                  variant="outline"
+                  size="icon"
+                  onClick={() => copyToClipboard(spectatorUrl, t('spectatorLink'))}
+                  title={t('copyUrl')}
+                  aria-label={t('copyUrl')}
+                >
+                  <Copy className="h-4 w-4" />
+                </Button>
+              </div>
+            )}
+            <div className="flex gap-2">
+              <Button
+                variant="outline"
+                size="sm"
+                onClick={() => updateSpectator(true)}
+                disabled={spectatorMutation.isPending}
+              >
+                <Eye className="h-3.5 w-3.5 mr-1.5" />
+                {spectatorEnabled ? t('rotateSpectatorLink') : t('enableSpectatorLink')}
+              </Button>
+              {spectatorEnabled && (
+                <Button
+                  variant="outline"
+                  size="sm"
+                  onClick={() => updateSpectator(false)}
+                  disabled={spectatorMutation.isPending}
+                >
+                  <EyeOff className="h-3.5 w-3.5 mr-1.5" />
+                  {t('disableSpectatorLink')}
+                </Button>
+              )}
+            </div>
+          </div>
         </div>
 
         <DialogFooter>
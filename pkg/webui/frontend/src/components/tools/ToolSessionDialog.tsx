@@ -49,6 +49,7 @@ const ACCESS_MODES = [
 const RUNTIME_TRANSPORTS = [
   { value: 'tmux', labelKey: 'runtimeTransportTmux' },
   { value: 'zellij', labelKey: 'runtimeTransportZellij' },
+  { value: 'native', labelKey: 'runtimeTransportNative' },
 ];
 
 const DRAFT_KEY = 'nekobot_tool_session_draft';
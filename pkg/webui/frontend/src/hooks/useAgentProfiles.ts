@@ -0,0 +1,63 @@
+import { api } from '@/api/client';
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
+import { toast } from '@/lib/notify';
+import { t } from '@/lib/i18n';
+
+export interface AgentProfile {
+  name: string;
+  description: string;
+  provider: string;
+  model: string;
+  tools: string[];
+  system_prompt: string;
+  workspace: string;
+  channels: string[];
+}
+
+const AGENT_PROFILES_KEY = ['agents', 'profiles'] as const;
+
+export function useAgentProfiles() {
+  return useQuery<AgentProfile[]>({
+    queryKey: [...AGENT_PROFILES_KEY],
+    queryFn: () => api.get('/api/agents'),
+    staleTime: 10_000,
+  });
+}
+
+export function useCreateAgentProfile() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (input: AgentProfile) =>
+      api.post<{ status: string; profile: AgentProfile }>('/api/agents', input),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...AGENT_PROFILES_KEY] });
+      toast.success(t('saved'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
+
+export function useUpdateAgentProfile() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: ({ name, input }: { name: string; input: AgentProfile }) =>
+      api.put<{ status: string; profile: AgentProfile }>(`/api/agents/${encodeURIComponent(name)}`, input),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...AGENT_PROFILES_KEY] });
+      toast.success(t('saved'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
+
+export function useDeleteAgentProfile() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (name: string) => api.delete(`/api/agents/${encodeURIComponent(name)}`),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...AGENT_PROFILES_KEY] });
+      toast.success(t('deleted'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
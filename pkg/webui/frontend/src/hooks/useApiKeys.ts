@@ -0,0 +1,61 @@
+import { api } from '@/api/client';
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
+import { toast } from '@/lib/notify';
+import { t } from '@/lib/i18n';
+
+export interface ApiKey {
+  id: string;
+  name: string;
+  prefix: string;
+  user_id: string;
+  tenant_id?: string;
+  scopes: string[];
+  expires_at?: string;
+  last_used_at?: string;
+  revoked_at?: string;
+  created_at: string;
+}
+
+export interface ApiKeyInput {
+  name: string;
+  scopes: string[];
+  expires_in_days?: number;
+}
+
+export interface CreatedApiKey {
+  api_key: ApiKey;
+  key: string;
+}
+
+const API_KEYS_KEY = ['api-keys'] as const;
+
+export function useApiKeys() {
+  return useQuery<ApiKey[]>({
+    queryKey: [...API_KEYS_KEY],
+    queryFn: () => api.get('/api/api-keys'),
+    staleTime: 10_000,
+  });
+}
+
+export function useCreateApiKey() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (input: ApiKeyInput) => api.post<CreatedApiKey>('/api/api-keys', input),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...API_KEYS_KEY] });
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
+
+export function useRevokeApiKey() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (id: string) => api.delete(`/api/api-keys/${encodeURIComponent(id)}`),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...API_KEYS_KEY] });
+      toast.success(t('systemApiKeysRevoked'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
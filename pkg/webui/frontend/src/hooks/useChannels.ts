@@ -1,4 +1,5 @@
-import { api } from '@/api/client';
+import { useEffect } from 'react';
+import { api, getStreamToken } from '@/api/client';
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import { toast } from '@/lib/notify';
 import { t } from '@/lib/i18n';
@@ -45,6 +46,20 @@ export interface WechatBindingStatus {
   };
 }
 
+export type WhatsAppState = 'disconnected' | 'connecting' | 'pairing' | 'connected' | 'reconnecting';
+
+export interface WhatsAppStatus {
+  channel_id: string;
+  bridge_url: string;
+  state: WhatsAppState;
+  qr_code?: string;
+  qr_png_data_url?: string;
+  last_error?: string;
+  reconnect_attempts: number;
+  next_retry_at?: string;
+  updated_at: string;
+}
+
 export interface TestChannelResult {
   channel: string;
   id: string;
@@ -54,6 +69,75 @@ export interface TestChannelResult {
   error?: string;
 }
 
+export interface ChannelIdentity {
+  id: string;
+  username?: string;
+  display_name?: string;
+  capabilities?: Record<string, boolean>;
+  fetched_at: string;
+}
+
+export interface ChannelIdentityResult {
+  channel: string;
+  id: string;
+  supported: boolean;
+  identity?: ChannelIdentity;
+  error?: string;
+}
+
+export function useChannelIdentity(name: string, enabled: boolean) {
+  return useQuery<ChannelIdentityResult>({
+    queryKey: ['channels', name, 'identity'],
+    queryFn: () => api.get(`/api/channels/${encodeURIComponent(name)}/identity`),
+    enabled,
+    staleTime: 60_000,
+    retry: false,
+  });
+}
+
+/** Follows the WhatsApp bridge state; each new pairing QR code is streamed in. */
+export function useWhatsAppStatus(enabled: boolean) {
+  const qc = useQueryClient();
+  const query = useQuery<WhatsAppStatus>({
+    queryKey: ['channels', 'whatsapp', 'status'],
+    queryFn: () => api.get('/api/channels/whatsapp/status'),
+    enabled,
+    retry: false,
+  });
+
+  useEffect(() => {
+    if (!enabled) return;
+    let cancelled = false;
+    let source: EventSource | null = null;
+    getStreamToken('whatsapp_pairing')
+      .then((token) => {
+        if (cancelled) return;
+        source = new EventSource(`/api/channels/whatsapp/pairing/stream?token=${encodeURIComponent(token)}`);
+        source.onmessage = (ev) => {
+          try {
+            qc.setQueryData(['channels', 'whatsapp', 'status'], JSON.parse(ev.data) as WhatsAppStatus);
+          } catch {
+            // Ignore malformed events; the next one carries the whole status.
+          }
+        };
+        source.onerror = () => {
+          // The browser retries dropped streams itself; once it gives up,
+          // fall back to the last polled status.
+          if (source?.readyState === EventSource.CLOSED) {
+            qc.invalidateQueries({ queryKey: ['channels', 'whatsapp', 'status'] });
+          }
+        };
+      })
+      .catch(() => {});
+    return () => {
+      cancelled = true;
+      source?.close();
+    };
+  }, [enabled, qc]);
+
+  return query;
+}
+
 export function useChannels() {
   return useQuery<ChannelsResponse>({
     queryKey: ['channels'],
@@ -5,6 +5,20 @@ export interface ChatMessage {
   role: 'user' | 'assistant' | 'system' | 'error';
   content: string;
   timestamp: number;
+  /** Position in the server-side session history; used to rate replies. */
+  messageIndex?: number;
+  /** Set while the reply is still being generated. */
+  streaming?: boolean;
+  /** Files the agent sent, e.g. a generated chart. */
+  attachments?: ChatAttachment[];
+}
+
+export interface ChatAttachment {
+  name: string;
+  mime_type: string;
+  size: number;
+  /** Authenticated API path, or a public URL. */
+  url?: string;
 }
 
 export interface FileMentionFeedback {
@@ -45,6 +59,22 @@ export interface ChatRouteResult {
   compaction_recommended?: boolean;
   compaction_strategy?: string;
   runtime_id?: string;
+  /** Present only while developer mode is on for the session. */
+  debug?: ChatTurnDebug;
+}
+
+export interface ChatDebugRequest {
+  provider: string;
+  model: string;
+  request: {
+    model: string;
+    messages: { role: string; content?: string; tool_calls?: unknown[]; tool_call_id?: string }[];
+    [key: string]: unknown;
+  };
+}
+
+export interface ChatTurnDebug {
+  requests: ChatDebugRequest[];
 }
 
 interface SendOptions {
@@ -55,6 +85,37 @@ interface SendOptions {
   systemPromptIDs?: string[];
   userPromptIDs?: string[];
   runtimeID?: string;
+  /** Images uploaded via /api/chat/attachments; only sent over the socket. */
+  attachmentIDs?: string[];
+}
+
+interface ChatServerEvent {
+  type?: string;
+  content?: string;
+  session_id?: string;
+  route?: ChatRouteResult;
+  debug?: ChatTurnDebug;
+  meta?: {
+    kind?: string;
+    data?: FileMentionFeedback;
+    message_index?: number;
+    reset?: boolean;
+    name?: string;
+    done?: boolean;
+  };
+}
+
+/** Drops the streaming flag from the partial reply, keeping its text. */
+function settleStreaming(messages: ChatMessage[]): ChatMessage[] {
+  const last = messages[messages.length - 1];
+  if (!last?.streaming) return messages;
+  return [...messages.slice(0, -1), { ...last, streaming: false }];
+}
+
+/** Removes the partial reply so the final message can take its place. */
+function withoutStreaming(messages: ChatMessage[]): ChatMessage[] {
+  const last = messages[messages.length - 1];
+  return last?.streaming ? messages.slice(0, -1) : messages;
 }
 
 interface UseChatReturn {
@@ -72,6 +133,8 @@ interface UseChatReturn {
   daemonEventStreamStatus: ConnectionStatus;
   fileMentionFeedback: FileMentionFeedback | null;
   clearFileMentionFeedback: () => void;
+  /** Name of the tool the pending reply is running, if any. */
+  toolActivity: string;
 }
 
 export function useChat(): UseChatReturn {
@@ -88,7 +151,9 @@ export function useChat(): UseChatReturn {
   const [routeResultsBySession, setRouteResultsBySession] = useState<Record<string, ChatRouteResult | null>>({});
   const [awaitingReplyBySession, setAwaitingReplyBySession] = useState<Record<string, boolean>>({});
   const [fileMentionFeedbackBySession, setFileMentionFeedbackBySession] = useState<Record<string, FileMentionFeedback | null>>({});
+  const [toolActivityBySession, setToolActivityBySession] = useState<Record<string, string>>({});
   const wsRef = useRef<WebSocket | null>(null);
+  const replyStreamRef = useRef<EventSource | null>(null);
   const eventSourceRef = useRef<EventSource | null>(null);
   const reconnectTimerRef = useRef<ReturnType<typeof setTimeout> | null>(null);
   const pendingSessionKeyRef = useRef<string | null>(null);
@@ -115,6 +180,10 @@ export function useChat(): UseChatReturn {
       }
       eventSourceRef.current = null;
     }
+    if (replyStreamRef.current) {
+      replyStreamRef.current.close();
+      replyStreamRef.current = null;
+    }
   }, []);
 
   useEffect(() => {
@@ -123,7 +192,7 @@ export function useChat(): UseChatReturn {
 
   useEffect(() => {
     const runtimeSessionKey = activeSessionKey.trim();
-    if (!runtimeSessionKey || runtimeSessionKey === 'webui-chat') {
+    if (!runtimeSessionKey) {
       if (eventSourceRef.current) {
         eventSourceRef.current.close();
         eventSourceRef.current = null;
@@ -157,20 +226,28 @@ export function useChat(): UseChatReturn {
           role?: ChatMessage['role'];
           content?: string;
           timestamp?: number;
+          attachments?: ChatAttachment[];
         };
         const targetSessionKey = msg.session_id?.trim() || runtimeSessionKey;
+        const hasAttachments = (msg.attachments?.length ?? 0) > 0;
+        // Files arrive from tools while the reply is still pending, so they
+        // are filed under the subscribed session.
+        const messageSessionKey = hasAttachments ? runtimeSessionKey : targetSessionKey;
         setMessagesBySession((prev) => ({
           ...prev,
-          [targetSessionKey]: [
-            ...(prev[targetSessionKey] ?? []),
+          [messageSessionKey]: [
+            ...(prev[messageSessionKey] ?? []),
             {
               role: (msg.role as ChatMessage['role']) || 'system',
               content: msg.content || '',
               timestamp: msg.timestamp ? msg.timestamp * 1000 : Date.now(),
+              attachments: msg.attachments,
             },
           ],
         }));
-        setAwaitingReplyBySession((prev) => ({ ...prev, [targetSessionKey]: false }));
+        if (!hasAttachments) {
+          setAwaitingReplyBySession((prev) => ({ ...prev, [targetSessionKey]: false }));
+        }
       } catch {
         // ignore malformed event payloads
       }
@@ -200,6 +277,126 @@ export function useChat(): UseChatReturn {
     };
   }, [activeSessionKey]);
 
+  const handleServerEvent = useCallback((msg: ChatServerEvent) => {
+    const now = Date.now();
+    const explicitSessionKey = msg.session_id?.trim() || '';
+    const targetSessionKey = explicitSessionKey || pendingSessionKeyRef.current || activeSessionKeyRef.current;
+
+    if (msg.type === 'routing') {
+      try {
+        const parsed = JSON.parse(msg.content || '{}') as Partial<ChatRouteSettings>;
+        setRouteSettings({
+          provider: parsed.provider?.trim() || '',
+          model: parsed.model?.trim() || '',
+          fallback: Array.isArray(parsed.fallback)
+            ? parsed.fallback.map((item) => String(item).trim()).filter(Boolean)
+            : [],
+        });
+      } catch {
+        // ignore malformed routing snapshots
+      }
+      return;
+    }
+
+    if (msg.type === 'delta') {
+      const text = msg.content || '';
+      setMessagesBySession((prev) => {
+        const current = prev[targetSessionKey] ?? [];
+        const last = current[current.length - 1];
+        if (last?.streaming) {
+          const content = msg.meta?.reset ? text : last.content + text;
+          return { ...prev, [targetSessionKey]: [...current.slice(0, -1), { ...last, content }] };
+        }
+        return {
+          ...prev,
+          [targetSessionKey]: [...current, { role: 'assistant', content: text, timestamp: now, streaming: true }],
+        };
+      });
+      return;
+    }
+
+    if (msg.type === 'tool') {
+      const name = msg.meta?.done ? '' : msg.meta?.name || msg.content || '';
+      setToolActivityBySession((prev) => ({ ...prev, [targetSessionKey]: name }));
+      return;
+    }
+
+    if (msg.type === 'message') {
+      if (pendingSessionKeyRef.current === targetSessionKey) {
+        pendingSessionKeyRef.current = null;
+      }
+      setAwaitingReplyBySession((prev) => ({ ...prev, [targetSessionKey]: false }));
+      setToolActivityBySession((prev) => ({ ...prev, [targetSessionKey]: '' }));
+      setMessagesBySession((prev) => ({
+        ...prev,
+        [targetSessionKey]: [
+          ...withoutStreaming(prev[targetSessionKey] ?? []),
+          {
+            role: 'assistant',
+            content: msg.content || '',
+            timestamp: now,
+            messageIndex: typeof msg.meta?.message_index === 'number' ? msg.meta.message_index : undefined,
+          },
+        ],
+      }));
+    } else if (msg.type === 'error') {
+      if (pendingSessionKeyRef.current === targetSessionKey) {
+        pendingSessionKeyRef.current = null;
+      }
+      setAwaitingReplyBySession((prev) => ({ ...prev, [targetSessionKey]: false }));
+      setToolActivityBySession((prev) => ({ ...prev, [targetSessionKey]: '' }));
+      setMessagesBySession((prev) => ({
+        ...prev,
+        [targetSessionKey]: [
+          ...settleStreaming(prev[targetSessionKey] ?? []),
+          { role: 'error', content: msg.content || 'Unknown error', timestamp: now },
+        ],
+      }));
+    } else if (msg.type === 'route_result' && msg.route) {
+      if (pendingSessionKeyRef.current === targetSessionKey) {
+        pendingSessionKeyRef.current = null;
+      }
+      const route = msg.route ? { ...msg.route, debug: msg.debug } : null;
+      setRouteResultsBySession((prev) => ({ ...prev, [targetSessionKey]: route }));
+    } else if (msg.type === 'system' && msg.meta?.kind === 'file_mentions' && msg.meta.data) {
+      const feedback = msg.meta.data;
+      setFileMentionFeedbackBySession((prev) => ({
+        ...prev,
+        [targetSessionKey]: {
+          count: Number(feedback.count || 0),
+          paths: Array.isArray(feedback.paths) ? feedback.paths : [],
+          warnings: Array.isArray(feedback.warnings) ? feedback.warnings : [],
+        },
+      }));
+      setMessagesBySession((prev) => ({
+        ...prev,
+        [targetSessionKey]: [
+          ...(prev[targetSessionKey] ?? []),
+          {
+            role: 'system',
+            content: msg.content || 'file mention feedback',
+            timestamp: now,
+          },
+        ],
+      }));
+    } else {
+      if (msg.type === 'system' && msg.content === 'Session cleared' && pendingSessionKeyRef.current === targetSessionKey) {
+        pendingSessionKeyRef.current = null;
+      }
+      setMessagesBySession((prev) => ({
+        ...prev,
+        [targetSessionKey]: [
+          ...(prev[targetSessionKey] ?? []),
+          {
+            role: 'system',
+            content: msg.content || msg.type || 'event',
+            timestamp: now,
+          },
+        ],
+      }));
+    }
+  }, []);
+
   const connect = useCallback(() => {
     cleanup();
     setConnectionStatus('connecting');
@@ -240,111 +437,19 @@ export function useChat(): UseChatReturn {
     };
 
         ws.onmessage = (ev: MessageEvent) => {
-      let msg: {
-        type?: string;
-        content?: string;
-        session_id?: string;
-        route?: ChatRouteResult;
-        meta?: { kind?: string; data?: FileMentionFeedback };
-      };
-      try {
-        msg = JSON.parse(ev.data);
-      } catch {
-        return;
-      }
-
-      const now = Date.now();
-      const explicitSessionKey = msg.session_id?.trim() || '';
-      const targetSessionKey = explicitSessionKey || pendingSessionKeyRef.current || activeSessionKeyRef.current;
-
-      if (msg.type === 'routing') {
-        try {
-          const parsed = JSON.parse(msg.content || '{}') as Partial<ChatRouteSettings>;
-          setRouteSettings({
-            provider: parsed.provider?.trim() || '',
-            model: parsed.model?.trim() || '',
-            fallback: Array.isArray(parsed.fallback)
-              ? parsed.fallback.map((item) => String(item).trim()).filter(Boolean)
-              : [],
-          });
-        } catch {
-          // ignore malformed routing snapshots
-        }
-        return;
-      }
-
-      if (msg.type === 'message') {
-        if (pendingSessionKeyRef.current === targetSessionKey) {
-          pendingSessionKeyRef.current = null;
-        }
-        setAwaitingReplyBySession((prev) => ({ ...prev, [targetSessionKey]: false }));
-        setMessagesBySession((prev) => ({
-          ...prev,
-          [targetSessionKey]: [
-            ...(prev[targetSessionKey] ?? []),
-            { role: 'assistant', content: msg.content || '', timestamp: now },
-          ],
-        }));
-      } else if (msg.type === 'error') {
-        if (pendingSessionKeyRef.current === targetSessionKey) {
-          pendingSessionKeyRef.current = null;
-        }
-        setAwaitingReplyBySession((prev) => ({ ...prev, [targetSessionKey]: false }));
-        setMessagesBySession((prev) => ({
-          ...prev,
-          [targetSessionKey]: [
-            ...(prev[targetSessionKey] ?? []),
-            { role: 'error', content: msg.content || 'Unknown error', timestamp: now },
-          ],
-        }));
-      } else if (msg.type === 'route_result' && msg.route) {
-        if (pendingSessionKeyRef.current === targetSessionKey) {
-          pendingSessionKeyRef.current = null;
-        }
-        setRouteResultsBySession((prev) => ({ ...prev, [targetSessionKey]: msg.route ?? null }));
-      } else if (msg.type === 'system' && msg.meta?.kind === 'file_mentions' && msg.meta.data) {
-        const feedback = msg.meta.data;
-        setFileMentionFeedbackBySession((prev) => ({
-          ...prev,
-          [targetSessionKey]: {
-            count: Number(feedback.count || 0),
-            paths: Array.isArray(feedback.paths) ? feedback.paths : [],
-            warnings: Array.isArray(feedback.warnings) ? feedback.warnings : [],
-          },
-        }));
-        setMessagesBySession((prev) => ({
-          ...prev,
-          [targetSessionKey]: [
-            ...(prev[targetSessionKey] ?? []),
-            {
-              role: 'system',
-              content: msg.content || 'file mention feedback',
-              timestamp: now,
-            },
-          ],
-        }));
-      } else {
-        if (msg.type === 'system' && msg.content === 'Session cleared' && pendingSessionKeyRef.current === targetSessionKey) {
-          pendingSessionKeyRef.current = null;
-        }
-        setMessagesBySession((prev) => ({
-          ...prev,
-          [targetSessionKey]: [
-            ...(prev[targetSessionKey] ?? []),
-            {
-              role: 'system',
-              content: msg.content || msg.type || 'event',
-              timestamp: now,
-            },
-          ],
-        }));
-      }
-    };
+          let msg: ChatServerEvent;
+          try {
+            msg = JSON.parse(ev.data);
+          } catch {
+            return;
+          }
+          handleServerEvent(msg);
+        };
       })
       .catch(() => {
         setConnectionStatus('disconnected');
       });
-  }, [cleanup]);
+  }, [cleanup, handleServerEvent]);
 
   const reconnect = useCallback(() => {
     connect();
@@ -376,22 +481,74 @@ export function useChat(): UseChatReturn {
   }, []);
 
   const sendMessage = useCallback((text: string, options: SendOptions) => {
+    const attachmentIDs = options.attachmentIDs ?? [];
+    if (!text.trim() && attachmentIDs.length === 0) return;
+
     const ws = wsRef.current;
-    if (!ws || ws.readyState !== WebSocket.OPEN) return;
-    if (!text.trim()) return;
-
-    ws.send(
-      JSON.stringify({
-        type: 'message',
-        content: text,
-        model: options.model,
-        provider: options.provider,
-        fallback: options.fallbackProviders,
-        system_prompt_ids: options.systemPromptIDs ?? [],
-        user_prompt_ids: options.userPromptIDs ?? [],
-        runtime_id: options.runtimeID ?? '',
-      }),
-    );
+    if (ws && ws.readyState === WebSocket.OPEN) {
+      ws.send(
+        JSON.stringify({
+          type: 'message',
+          content: text,
+          model: options.model,
+          provider: options.provider,
+          fallback: options.fallbackProviders,
+          system_prompt_ids: options.systemPromptIDs ?? [],
+          user_prompt_ids: options.userPromptIDs ?? [],
+          runtime_id: options.runtimeID ?? '',
+          attachment_ids: attachmentIDs,
+          stream: true,
+        }),
+      );
+    } else {
+      // Without a socket the reply is streamed over SSE instead.
+      replyStreamRef.current?.close();
+      replyStreamRef.current = null;
+      getStreamToken('chat_stream')
+        .then((token) => {
+          const params = new URLSearchParams({
+            token,
+            message: text,
+            provider: options.provider,
+            model: options.model,
+            fallback: options.fallbackProviders.join(','),
+            runtime_id: options.runtimeID ?? '',
+          });
+          const es = new EventSource(`/api/chat/stream?${params.toString()}`);
+          replyStreamRef.current = es;
+          es.onmessage = (ev: MessageEvent) => {
+            try {
+              handleServerEvent(JSON.parse(ev.data) as ChatServerEvent);
+            } catch {
+              // ignore malformed events
+            }
+          };
+          // The server ends the stream after the reply; close so the
+          // browser does not reconnect and send the message again.
+          es.onerror = () => {
+            es.close();
+            if (replyStreamRef.current === es) {
+              replyStreamRef.current = null;
+            }
+            setAwaitingReplyBySession((prev) => ({ ...prev, [options.sessionKey]: false }));
+            setToolActivityBySession((prev) => ({ ...prev, [options.sessionKey]: '' }));
+            setMessagesBySession((prev) => ({
+              ...prev,
+              [options.sessionKey]: settleStreaming(prev[options.sessionKey] ?? []),
+            }));
+          };
+        })
+        .catch(() => {
+          setAwaitingReplyBySession((prev) => ({ ...prev, [options.sessionKey]: false }));
+          setMessagesBySession((prev) => ({
+            ...prev,
+            [options.sessionKey]: [
+              ...(prev[options.sessionKey] ?? []),
+              { role: 'error', content: 'Failed to start reply stream', timestamp: Date.now() },
+            ],
+          }));
+        });
+    }
     setRouteSettings({
       provider: options.provider,
       model: options.model,
@@ -407,10 +564,14 @@ export function useChat(): UseChatReturn {
       ...prev,
       [options.sessionKey]: [
         ...(prev[options.sessionKey] ?? []),
-        { role: 'user', content: text, timestamp: Date.now() },
+        {
+          role: 'user',
+          content: attachmentIDs.length > 0 ? `${text}\n\n[${attachmentIDs.length} image(s)]`.trim() : text,
+          timestamp: Date.now(),
+        },
       ],
     }));
-  }, []);
+  }, [handleServerEvent]);
 
   const clearMessages = useCallback((sessionKey: string, runtimeID?: string) => {
     const ws = wsRef.current;
@@ -445,6 +606,7 @@ export function useChat(): UseChatReturn {
   const routeResult = routeResultsBySession[activeSessionKey] ?? null;
   const isAwaitingReply = awaitingReplyBySession[activeSessionKey] ?? false;
   const fileMentionFeedback = fileMentionFeedbackBySession[activeSessionKey] ?? null;
+  const toolActivity = toolActivityBySession[activeSessionKey] ?? '';
 
   return {
     messages,
@@ -461,5 +623,6 @@ export function useChat(): UseChatReturn {
     daemonEventStreamStatus,
     fileMentionFeedback,
     clearFileMentionFeedback,
+    toolActivity,
   };
 }
@@ -0,0 +1,61 @@
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
+
+import { api } from '@/api/client';
+import { toast } from '@/lib/notify';
+import { t } from '@/lib/i18n';
+import { sessionKeys } from '@/hooks/useSessions';
+
+/** A channel account sharing the current user's conversation. */
+export interface LinkedAccount {
+  channel: string;
+  user_id: string;
+}
+
+export interface ChatLink {
+  linked: boolean;
+  session_id?: string;
+  accounts: LinkedAccount[];
+  /** Set when a code was just issued for confirmation in another channel. */
+  code?: string;
+  expires_at?: string;
+}
+
+const chatLinkKey = ['chat-link'] as const;
+
+export function useChatLink() {
+  return useQuery<ChatLink>({
+    queryKey: chatLinkKey,
+    queryFn: () => api.get<ChatLink>('/api/chat/link'),
+  });
+}
+
+/** Confirms a code from /link, or issues one when code is empty. */
+export function useLinkChat() {
+  const qc = useQueryClient();
+
+  return useMutation<ChatLink, Error, string>({
+    mutationFn: (code) => api.post<ChatLink>('/api/chat/link', { code }),
+    onSuccess: (data, code) => {
+      qc.setQueryData(chatLinkKey, data);
+      if (code) {
+        qc.invalidateQueries({ queryKey: sessionKeys.chatList() });
+        toast.success(t('chatLinkLinked'));
+      }
+    },
+    onError: (err) => toast.error(err.message || t('chatLinkFailed')),
+  });
+}
+
+export function useUnlinkChat() {
+  const qc = useQueryClient();
+
+  return useMutation<ChatLink, Error, void>({
+    mutationFn: () => api.delete<ChatLink>('/api/chat/link'),
+    onSuccess: (data) => {
+      qc.setQueryData(chatLinkKey, data);
+      qc.invalidateQueries({ queryKey: sessionKeys.chatList() });
+      toast.success(t('chatLinkUnlinked'));
+    },
+    onError: (err) => toast.error(err.message || t('chatLinkFailed')),
+  });
+}
@@ -9,10 +9,25 @@ export interface ConfigData {
   [section: string]: Record<string, unknown>;
 }
 
+export interface ConfigImportSectionResult {
+  section: string;
+  status: "saved" | "valid" | "invalid";
+  errors?: string[];
+}
+
+export interface ConfigImportProviderResult {
+  name: string;
+  status: "created" | "updated" | "failed";
+  error?: string;
+}
+
 export interface ConfigMutationResult {
   status?: string;
   sections_saved?: number;
+  sections?: ConfigImportSectionResult[];
   providers_imported?: number;
+  providers_failed?: number;
+  providers?: ConfigImportProviderResult[];
   restart_required?: boolean;
   restart_sections?: string[];
 }
@@ -242,6 +257,30 @@ export interface SystemServiceBundleData {
   nekoclientd: ServiceStatusData;
 }
 
+export interface BackupStatus {
+  enabled: boolean;
+  target: 'local' | 's3';
+  location: string;
+  interval_hours: number;
+  keep: number;
+  max_age_days: number;
+  running: boolean;
+  last_backup?: { name: string; size: number; created_at: string };
+  last_run_at?: string;
+  last_error?: string;
+  next_run_at?: string;
+}
+
+export interface WorkspaceBackendStatus {
+  backend: 'local' | 's3';
+  location: string;
+  paths: string[];
+  files: number;
+  last_pull_at?: string;
+  last_push_at?: string;
+  last_error?: string;
+}
+
 export interface StatusData {
   version: string;
   commit: string;
@@ -286,6 +325,8 @@ export interface StatusData {
   daemon_machines: DaemonMachineStatus[];
   session_runtime_states: SessionRuntimeState[];
   agent_definition?: AgentDefinitionStatus | null;
+  backup?: BackupStatus | null;
+  workspace_backend?: WorkspaceBackendStatus | null;
   gateway_host: string;
   gateway_port: number;
   gateway: {
@@ -366,13 +407,27 @@ export function useImportConfig() {
             String(result.providers_imported ?? 0),
           ),
         );
+        const failedProviders = (result.providers ?? []).filter(
+          (item) => item.status === "failed",
+        );
+        if (failedProviders.length > 0) {
+          toast.warning(
+            t(
+              "importProvidersFailed",
+              String(failedProviders.length),
+              failedProviders
+                .map((item) => `${item.name || "?"}: ${item.error ?? ""}`)
+                .join("; "),
+            ),
+          );
+        }
         const restartNotice = formatRestartNotice(result);
         if (restartNotice) {
           toast.info(restartNotice);
         }
       }
     },
-    onError: () => toast.error(t("importFailed")),
+    onError: (err: Error) => toast.error(err.message || t("importFailed")),
   });
 }
 
@@ -0,0 +1,95 @@
+import { api } from '@/api/client';
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
+import { toast } from '@/lib/notify';
+import { t } from '@/lib/i18n';
+
+export interface FeedSubscription {
+  id: string;
+  name: string;
+  url: string;
+  interval_minutes: number;
+  prompt: string;
+  channel_id: string;
+  session_id: string;
+  enabled: boolean;
+  last_checked_at?: string;
+  last_error: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface FeedSubscriptionInput {
+  name: string;
+  url: string;
+  interval_minutes: number;
+  prompt: string;
+  channel_id: string;
+  session_id: string;
+  enabled: boolean;
+}
+
+export interface FeedCheckResult {
+  new_entries: number;
+  summary: string;
+}
+
+const FEEDS_KEY = ['feeds'] as const;
+
+export function useFeeds() {
+  return useQuery<FeedSubscription[]>({
+    queryKey: [...FEEDS_KEY],
+    queryFn: () => api.get('/api/feeds'),
+    staleTime: 10_000,
+  });
+}
+
+export function useCreateFeed() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (input: FeedSubscriptionInput) =>
+      api.post<{ status: string; feed: FeedSubscription }>('/api/feeds', input),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...FEEDS_KEY] });
+      toast.success(t('saved'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
+
+export function useUpdateFeed() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: ({ id, input }: { id: string; input: FeedSubscriptionInput }) =>
+      api.put<FeedSubscription>(`/api/feeds/${encodeURIComponent(id)}`, input),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...FEEDS_KEY] });
+      toast.success(t('saved'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
+
+export function useDeleteFeed() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (id: string) => api.delete(`/api/feeds/${encodeURIComponent(id)}`),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...FEEDS_KEY] });
+      toast.success(t('deleted'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
+
+export function useCheckFeed() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (id: string) =>
+      api.post<FeedCheckResult>(`/api/feeds/${encodeURIComponent(id)}/check`),
+    onSuccess: (result) => {
+      qc.invalidateQueries({ queryKey: [...FEEDS_KEY] });
+      toast.success(t('systemFeedsChecked', result.new_entries));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
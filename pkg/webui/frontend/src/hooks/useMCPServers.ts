@@ -0,0 +1,100 @@
+import { api } from '@/api/client';
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
+import { toast } from '@/lib/notify';
+import { t } from '@/lib/i18n';
+
+export interface MCPServerConfig {
+  name: string;
+  transport: string;
+  command: string;
+  args: string[];
+  env?: Record<string, string>;
+  work_dir: string;
+  endpoint: string;
+  headers?: Record<string, string>;
+  timeout: string;
+  allowed_tools?: string[];
+  blocked_tools?: string[];
+}
+
+export type MCPServerState = 'connecting' | 'connected' | 'error';
+
+export interface MCPServerStatus {
+  name: string;
+  transport: string;
+  state: MCPServerState;
+  tools: string[];
+  last_error?: string;
+  connected_at?: string;
+  last_checked_at?: string;
+}
+
+export interface MCPServer {
+  config: MCPServerConfig;
+  status: MCPServerStatus;
+}
+
+const MCP_SERVERS_KEY = ['mcp', 'servers'] as const;
+
+export function useMCPServers() {
+  return useQuery<MCPServer[]>({
+    queryKey: [...MCP_SERVERS_KEY],
+    queryFn: () => api.get('/api/mcp/servers'),
+    refetchInterval: 15_000,
+  });
+}
+
+export function useCreateMCPServer() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (input: MCPServerConfig) =>
+      api.post<{ status: string; server: MCPServerConfig }>('/api/mcp/servers', input),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...MCP_SERVERS_KEY] });
+      toast.success(t('saved'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
+
+export function useUpdateMCPServer() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: ({ name, input }: { name: string; input: MCPServerConfig }) =>
+      api.put<{ status: string; server: MCPServerConfig }>(`/api/mcp/servers/${encodeURIComponent(name)}`, input),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...MCP_SERVERS_KEY] });
+      toast.success(t('saved'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
+
+export function useDeleteMCPServer() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (name: string) => api.delete(`/api/mcp/servers/${encodeURIComponent(name)}`),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...MCP_SERVERS_KEY] });
+      toast.success(t('deleted'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
+
+export function useReconnectMCPServer() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (name: string) =>
+      api.post<MCPServerStatus>(`/api/mcp/servers/${encodeURIComponent(name)}/reconnect`),
+    onSuccess: (status) => {
+      qc.invalidateQueries({ queryKey: [...MCP_SERVERS_KEY] });
+      if (status.state === 'connected') {
+        toast.success(t('systemMCPReconnected'));
+      } else {
+        toast.error(status.last_error || t('systemMCPReconnectFailed'));
+      }
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
@@ -0,0 +1,81 @@
+import { api } from '@/api/client';
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
+import { toast } from '@/lib/notify';
+import { t } from '@/lib/i18n';
+
+export interface NotifyRule {
+  id: string;
+  name: string;
+  event_types: string[];
+  match: string;
+  channel_id: string;
+  session_id: string;
+  enabled: boolean;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface NotifyRuleInput {
+  name: string;
+  event_types: string[];
+  match: string;
+  channel_id: string;
+  session_id: string;
+  enabled: boolean;
+}
+
+const NOTIFY_RULES_KEY = ['notify', 'rules'] as const;
+
+export function useNotifyRules() {
+  return useQuery<NotifyRule[]>({
+    queryKey: [...NOTIFY_RULES_KEY],
+    queryFn: () => api.get('/api/notify/rules'),
+    staleTime: 10_000,
+  });
+}
+
+export function useNotifyEventTypes() {
+  return useQuery<string[]>({
+    queryKey: ['notify', 'event-types'],
+    queryFn: () => api.get('/api/notify/event-types'),
+    staleTime: Infinity,
+  });
+}
+
+export function useCreateNotifyRule() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (input: NotifyRuleInput) =>
+      api.post<{ status: string; rule: NotifyRule }>('/api/notify/rules', input),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...NOTIFY_RULES_KEY] });
+      toast.success(t('saved'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
+
+export function useUpdateNotifyRule() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: ({ id, input }: { id: string; input: NotifyRuleInput }) =>
+      api.put<NotifyRule>(`/api/notify/rules/${encodeURIComponent(id)}`, input),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...NOTIFY_RULES_KEY] });
+      toast.success(t('saved'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
+
+export function useDeleteNotifyRule() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (id: string) => api.delete(`/api/notify/rules/${encodeURIComponent(id)}`),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...NOTIFY_RULES_KEY] });
+      toast.success(t('deleted'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
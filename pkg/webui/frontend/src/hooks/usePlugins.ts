@@ -0,0 +1,64 @@
+import { api } from '@/api/client';
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
+import { toast } from '@/lib/notify';
+import { t } from '@/lib/i18n';
+
+export type PluginKind = 'grpc' | 'wasm';
+
+export interface PluginStatus {
+  name: string;
+  kind: PluginKind;
+  path: string;
+  running: boolean;
+  tools: string[];
+  workspace?: 'none' | 'read_only' | 'read_write';
+  last_error?: string;
+}
+
+export interface PluginsResponse {
+  plugins_dir: string;
+  plugins: PluginStatus[];
+}
+
+const PLUGINS_KEY = ['plugins'] as const;
+
+export function usePlugins() {
+  return useQuery<PluginsResponse>({
+    queryKey: [...PLUGINS_KEY],
+    queryFn: () => api.get('/api/plugins'),
+    refetchInterval: 15_000,
+  });
+}
+
+// Installs a WASM tool from an uploaded .zip or from a directory, .zip or
+// URL on the server.
+export function useInstallWasmPlugin() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (input: File | string) => {
+      if (typeof input === 'string') {
+        return api.post<{ status: string; plugin: PluginStatus }>('/api/plugins/wasm', { source: input });
+      }
+      const form = new FormData();
+      form.append('file', input);
+      return api.upload<{ status: string; plugin: PluginStatus }>('/api/plugins/wasm', form);
+    },
+    onSuccess: (result) => {
+      qc.invalidateQueries({ queryKey: [...PLUGINS_KEY] });
+      toast.success(t('systemPluginsInstalled', result.plugin.name));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
+
+export function useUninstallWasmPlugin() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (name: string) => api.delete(`/api/plugins/wasm/${encodeURIComponent(name)}`),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: [...PLUGINS_KEY] });
+      toast.success(t('deleted'));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
@@ -17,6 +17,30 @@ export interface Provider {
   supports_discovery: boolean;
   summary: string;
   timeout: number;
+  stream: boolean;
+  system_prefix?: string;
+  api_version?: string;
+  deployments?: Record<string, string> | null;
+  middleware?: ProviderMiddleware | null;
+  capabilities?: ProviderCapabilities | null;
+}
+
+export interface ProviderMiddleware {
+  max_retries?: number;
+  retry_backoff_ms?: number;
+  attempt_timeout?: number;
+  max_request_cost?: number;
+  headers?: Record<string, string>;
+  log_requests?: boolean;
+}
+
+export interface ProviderCapabilities {
+  model: string;
+  tool_calling?: boolean;
+  streaming?: boolean;
+  vision?: boolean;
+  json_mode?: boolean;
+  probed_at: number;
 }
 
 export interface ProviderRuntime {
@@ -30,6 +54,10 @@ export interface ProviderRuntime {
   last_failure_unix: number;
   cooldown_end_unix: number;
   disabled_until_unix: number;
+  circuit_state: 'closed' | 'open' | 'half_open';
+  latency_ms: number;
+  latency_samples: number;
+  error_rate: number;
 }
 
 export interface CreateProviderInput {
@@ -43,6 +71,11 @@ export interface CreateProviderInput {
   enabled?: boolean;
   default_test_model?: string;
   api_format?: string;
+  stream?: boolean;
+  system_prefix?: string;
+  api_version?: string;
+  deployments?: Record<string, string>;
+  middleware?: ProviderMiddleware;
 }
 
 export interface UpdateProviderInput {
@@ -55,6 +88,11 @@ export interface UpdateProviderInput {
   enabled?: boolean;
   default_test_model?: string;
   api_format?: string;
+  stream?: boolean;
+  system_prefix?: string;
+  api_version?: string;
+  deployments?: Record<string, string>;
+  middleware?: ProviderMiddleware;
 }
 
 export interface DiscoverModelsInput {
@@ -64,6 +102,8 @@ export interface DiscoverModelsInput {
   api_base?: string;
   proxy?: string;
   timeout?: number;
+  api_version?: string;
+  deployments?: Record<string, string>;
 }
 
 export interface DiscoverModelsResponse {
@@ -88,6 +128,11 @@ export interface TestProviderResponse {
   preview: string;
 }
 
+export interface ProbeProviderCapabilitiesResponse {
+  provider: string;
+  capabilities: ProviderCapabilities;
+}
+
 export const PROVIDERS_KEY = ['providers'] as const;
 export const PROVIDER_RUNTIME_KEY = ['providers', 'runtime'] as const;
 
@@ -193,3 +238,16 @@ export function useTestProvider() {
     onError: (err: Error) => toast.error(err.message),
   });
 }
+
+export function useProbeProviderCapabilities() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (name: string) =>
+      api.post<ProbeProviderCapabilitiesResponse>(`/api/providers/${encodeURIComponent(name)}/probe-capabilities`, {}),
+    onSuccess: (result) => {
+      qc.invalidateQueries({ queryKey: [...PROVIDERS_KEY] });
+      toast.success(t('providerCapabilitiesProbed', result.provider, result.capabilities.model));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
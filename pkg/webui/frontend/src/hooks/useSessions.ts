@@ -1,12 +1,17 @@
-import { api } from '@/api/client';
+import { api, getToken } from '@/api/client';
 import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
 import { toast } from '@/lib/notify';
 import { t } from '@/lib/i18n';
 
 export interface SessionSummary {
   id: string;
+  /** Channel prefix of the ID (e.g. "telegram"), or the session source. */
+  channel: string;
+  source: string;
+  archived: boolean;
   created_at: string;
   updated_at: string;
+  title: string;
   summary: string;
   message_count: number;
   runtime_id: string;
@@ -19,7 +24,18 @@ export interface SessionMessage {
   tool_call_id?: string;
 }
 
+/** A WebUI playground session of the current user. */
+export interface ChatSessionSummary {
+  id: string;
+  runtime_id: string;
+  title: string;
+  message_count: number;
+  created_at: string;
+  updated_at: string;
+}
+
 export interface SessionDetail extends SessionSummary {
+  pins: string[];
   messages: SessionMessage[];
 }
 
@@ -27,13 +43,18 @@ export const sessionKeys = {
   all: ['sessions'] as const,
   list: () => [...sessionKeys.all, 'list'] as const,
   detail: (id: string) => [...sessionKeys.all, 'detail', id] as const,
+  developerMode: (id: string) => [...sessionKeys.all, 'developer-mode', id] as const,
+  persona: (id: string) => [...sessionKeys.all, 'persona', id] as const,
+  chatList: () => [...sessionKeys.all, 'chat-list'] as const,
+  chatMessages: (id: string) => [...sessionKeys.all, 'chat-messages', id] as const,
 };
 
-export function useSessions() {
+export function useSessions(options?: { archived?: boolean }) {
+  const archived = options?.archived ? 'true' : 'false';
   return useQuery<SessionSummary[]>({
-    queryKey: sessionKeys.list(),
+    queryKey: [...sessionKeys.list(), archived],
     queryFn: async () => {
-      const data = await api.get<SessionSummary[]>('/api/sessions');
+      const data = await api.get<SessionSummary[]>(`/api/sessions?archived=${archived}`);
       return Array.isArray(data) ? data : [];
     },
     staleTime: 10_000,
@@ -76,6 +97,172 @@ export function useUpdateSessionSummary() {
   });
 }
 
+export function useUpdateSessionTitle() {
+  const qc = useQueryClient();
+
+  return useMutation<unknown, Error, { id: string; title: string }>({
+    mutationFn: ({ id, title }) =>
+      api.put(`/api/sessions/${encodeURIComponent(id)}/title`, { title }),
+    onSuccess: (_, vars) => {
+      qc.invalidateQueries({ queryKey: sessionKeys.list() });
+      qc.invalidateQueries({ queryKey: sessionKeys.detail(vars.id) });
+      toast.success(t('sessionTitleSaved'));
+    },
+    onError: (err) => toast.error(err.message || t('sessionTitleSaveFailed')),
+  });
+}
+
+export function useArchiveSession() {
+  const qc = useQueryClient();
+
+  return useMutation<unknown, Error, { id: string; archived: boolean }>({
+    mutationFn: ({ id, archived }) =>
+      api.put(`/api/sessions/${encodeURIComponent(id)}/archive`, { archived }),
+    onSuccess: (_, vars) => {
+      qc.invalidateQueries({ queryKey: sessionKeys.list() });
+      qc.invalidateQueries({ queryKey: sessionKeys.detail(vars.id) });
+      toast.success(t(vars.archived ? 'sessionArchived' : 'sessionUnarchived'));
+    },
+    onError: (err) => toast.error(err.message || t('sessionArchiveFailed')),
+  });
+}
+
+export function useRetitleSession() {
+  const qc = useQueryClient();
+
+  return useMutation<{ title: string }, Error, string>({
+    mutationFn: (id) =>
+      api.post<{ title: string }>(`/api/sessions/${encodeURIComponent(id)}/retitle`),
+    onSuccess: (_, id) => {
+      qc.invalidateQueries({ queryKey: sessionKeys.list() });
+      qc.invalidateQueries({ queryKey: sessionKeys.detail(id) });
+      toast.success(t('sessionTitleSaved'));
+    },
+    onError: (err) => toast.error(err.message || t('sessionRetitleFailed')),
+  });
+}
+
+export function useChatDeveloperMode(chatSessionID: string) {
+  return useQuery<boolean>({
+    queryKey: sessionKeys.developerMode(chatSessionID),
+    queryFn: async () => {
+      const data = await api.get<{ developer_mode: boolean }>(
+        `/api/chat/session/${encodeURIComponent(chatSessionID)}/developer-mode`,
+      );
+      return Boolean(data?.developer_mode);
+    },
+    enabled: !!chatSessionID,
+  });
+}
+
+export function useSetChatDeveloperMode() {
+  const qc = useQueryClient();
+
+  return useMutation<{ developer_mode: boolean }, Error, { id: string; enabled: boolean }>({
+    mutationFn: ({ id, enabled }) =>
+      api.put<{ developer_mode: boolean }>(`/api/chat/session/${encodeURIComponent(id)}/developer-mode`, { enabled }),
+    onSuccess: (data, vars) => {
+      qc.setQueryData(sessionKeys.developerMode(vars.id), Boolean(data?.developer_mode));
+      toast.success(data?.developer_mode ? t('chatDeveloperModeOn') : t('chatDeveloperModeOff'));
+    },
+    onError: (err) => toast.error(err.message || t('chatDeveloperModeFailed')),
+  });
+}
+
+export function useChatPersona(chatSessionID: string) {
+  return useQuery<string>({
+    queryKey: sessionKeys.persona(chatSessionID),
+    queryFn: async () => {
+      const data = await api.get<{ persona: string }>(
+        `/api/chat/session/${encodeURIComponent(chatSessionID)}/persona`,
+      );
+      return data?.persona ?? '';
+    },
+    enabled: !!chatSessionID,
+  });
+}
+
+export function useSetChatPersona() {
+  const qc = useQueryClient();
+
+  return useMutation<{ persona: string }, Error, { id: string; persona: string }>({
+    mutationFn: ({ id, persona }) =>
+      api.put<{ persona: string }>(`/api/chat/session/${encodeURIComponent(id)}/persona`, { persona }),
+    onSuccess: (data, vars) => {
+      qc.setQueryData(sessionKeys.persona(vars.id), data?.persona ?? '');
+      toast.success(data?.persona ? t('chatPersonaSaved') : t('chatPersonaCleared'));
+    },
+    onError: (err) => toast.error(err.message || t('chatPersonaFailed')),
+  });
+}
+
+export function useChatSessions() {
+  return useQuery<ChatSessionSummary[]>({
+    queryKey: sessionKeys.chatList(),
+    queryFn: async () => {
+      const data = await api.get<ChatSessionSummary[]>('/api/chat/sessions');
+      return Array.isArray(data) ? data : [];
+    },
+    staleTime: 10_000,
+  });
+}
+
+export function useChatSessionMessages(chatSessionID?: string | null) {
+  return useQuery<SessionMessage[]>({
+    queryKey: sessionKeys.chatMessages(chatSessionID ?? ''),
+    queryFn: async () => {
+      const data = await api.get<{ messages: SessionMessage[] }>(
+        `/api/chat/sessions/${encodeURIComponent(chatSessionID!)}/messages`,
+      );
+      return Array.isArray(data?.messages) ? data.messages : [];
+    },
+    enabled: !!chatSessionID,
+    staleTime: Infinity,
+  });
+}
+
+export function useDeleteChatSession() {
+  const qc = useQueryClient();
+
+  return useMutation<unknown, Error, string>({
+    mutationFn: (id) => api.delete(`/api/chat/sessions/${encodeURIComponent(id)}`),
+    onSuccess: (_, id) => {
+      qc.invalidateQueries({ queryKey: sessionKeys.chatList() });
+      qc.removeQueries({ queryKey: sessionKeys.chatMessages(id) });
+      toast.success(t('chatHistoryDeleted'));
+    },
+    onError: (err) => toast.error(err.message || t('chatHistoryDeleteFailed')),
+  });
+}
+
+export function useAddSessionPin() {
+  const qc = useQueryClient();
+
+  return useMutation<unknown, Error, { id: string; text: string }>({
+    mutationFn: ({ id, text }) =>
+      api.post(`/api/sessions/${encodeURIComponent(id)}/pins`, { text }),
+    onSuccess: (_, vars) => {
+      qc.invalidateQueries({ queryKey: sessionKeys.detail(vars.id) });
+      toast.success(t('sessionPinAdded'));
+    },
+    onError: (err) => toast.error(err.message || t('sessionPinSaveFailed')),
+  });
+}
+
+export function useRemoveSessionPin() {
+  const qc = useQueryClient();
+
+  return useMutation<unknown, Error, { id: string; index: number }>({
+    mutationFn: ({ id, index }) =>
+      api.delete(`/api/sessions/${encodeURIComponent(id)}/pins/${index}`),
+    onSuccess: (_, vars) => {
+      qc.invalidateQueries({ queryKey: sessionKeys.detail(vars.id) });
+      toast.success(t('sessionPinRemoved'));
+    },
+    onError: (err) => toast.error(err.message || t('sessionPinSaveFailed')),
+  });
+}
+
 export function useDeleteSession() {
   const qc = useQueryClient();
 
@@ -90,6 +277,30 @@ export function useDeleteSession() {
   });
 }
 
+/** Downloads a session transcript with its token usage. */
+export function useExportSession() {
+  return useMutation<void, Error, { id: string; format: 'jsonl' | 'markdown' }>({
+    mutationFn: async ({ id, format }) => {
+      const token = getToken();
+      const resp = await fetch(
+        `/api/sessions/${encodeURIComponent(id)}/export?format=${format}`,
+        { headers: token ? { Authorization: `Bearer ${token}` } : undefined },
+      );
+      if (!resp.ok) {
+        throw new Error((await resp.text()) || resp.statusText);
+      }
+      const blob = await resp.blob();
+      const url = URL.createObjectURL(blob);
+      const a = document.createElement('a');
+      a.href = url;
+      a.download = `${id.replace(/[\\/:*?"<>|]/g, '_')}.${format === 'markdown' ? 'md' : 'jsonl'}`;
+      a.click();
+      URL.revokeObjectURL(url);
+    },
+    onError: (err) => toast.error(err.message || t('sessionExportFailed')),
+  });
+}
+
 export function useUpdateSessionRuntime() {
   const qc = useQueryClient();
 
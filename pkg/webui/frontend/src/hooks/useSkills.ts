@@ -18,6 +18,9 @@ export interface SkillItem {
   missing_requirements: SkillMissingRequirements;
   install_specs: SkillInstallSpec[];
   is_installed: boolean;
+  trusted: boolean;
+  permissions_declared: boolean;
+  permissions: string[];
 }
 
 export interface SkillContent {
@@ -45,6 +48,30 @@ export interface SkillInstallSpec {
   options?: Record<string, unknown>;
 }
 
+export interface SkillsIndexVersion {
+  version: string;
+  changelog: string;
+  published_at?: string;
+}
+
+export interface SkillsIndexEntry {
+  id: string;
+  name: string;
+  description: string;
+  repo: string;
+  tags: string[];
+  permissions: string[];
+  versions: SkillsIndexVersion[];
+  installed_version: string;
+  pinned: boolean;
+}
+
+export interface SkillsIndexResponse {
+  configured: boolean;
+  index_only?: boolean;
+  skills: SkillsIndexEntry[];
+}
+
 interface SkillToggleResponse {
   status: 'enabled' | 'disabled';
 }
@@ -90,6 +117,9 @@ function normalizeSkillItem(input: Partial<SkillItem> | null | undefined): Skill
       ? input.install_specs.map((spec) => normalizeInstallSpec(spec))
       : [],
     is_installed: Boolean(input?.is_installed),
+    trusted: Boolean(input?.trusted),
+    permissions_declared: Boolean(input?.permissions_declared),
+    permissions: Array.isArray(input?.permissions) ? input.permissions : [],
   };
 }
 
@@ -98,6 +128,7 @@ const skillKeys = {
   list: () => [...skillKeys.all, 'list'] as const,
   item: (skillID: string) => [...skillKeys.all, 'item', skillID] as const,
   content: (skillID: string) => [...skillKeys.all, 'content', skillID] as const,
+  index: () => [...skillKeys.all, 'index'] as const,
 };
 
 export function useSkills() {
@@ -166,3 +197,26 @@ export function useDisableSkill() {
     onError: (err) => toast.error(err.message),
   });
 }
+
+export function useSkillsIndex() {
+  return useQuery<SkillsIndexResponse>({
+    queryKey: skillKeys.index(),
+    queryFn: () => api.get<SkillsIndexResponse>('/api/skills-index'),
+    staleTime: 60_000,
+  });
+}
+
+// Installs an indexed skill version the user confirmed together with the
+// permissions it requests.
+export function useInstallIndexedSkill() {
+  const qc = useQueryClient();
+  return useMutation({
+    mutationFn: (input: { id: string; version: string }) =>
+      api.post<{ status: string; skill: { id: string; version: string } }>('/api/skills-index/install', input),
+    onSuccess: (result) => {
+      qc.invalidateQueries({ queryKey: skillKeys.all });
+      toast.success(t('skillsIndexInstalled', result.skill.id, result.skill.version));
+    },
+    onError: (err: Error) => toast.error(err.message),
+  });
+}
@@ -15,6 +15,8 @@ export interface ToolSession {
   access_mode: string; // "none" | "one_time" | "permanent"
   source?: string; // "agent" | "channel" | ""
   runtime_transport?: string;
+  spectator_enabled?: boolean;
+  viewers?: number;
   metadata?: Record<string, unknown>;
   created_at?: string;
   updated_at?: string;
@@ -40,6 +42,9 @@ export interface CreateSessionResponse {
   access_url?: string;
   access_password?: string;
   access_mode?: string;
+  /** "pending" when the spawn is waiting for admin approval; no session yet. */
+  status?: string;
+  request_id?: string;
 }
 
 export interface AccessResponse {
@@ -54,6 +59,13 @@ export interface OTPResponse {
   ttl_seconds: number;
 }
 
+export interface SpectatorResponse {
+  session: ToolSession;
+  spectator_enabled: boolean;
+  /** Only returned when a new link is issued. */
+  spectator_url?: string;
+}
+
 export interface ProcessStatus {
   running: boolean;
   exit_code?: number;
@@ -120,7 +132,11 @@ export function useCreateToolSession() {
   const qc = useQueryClient();
   return useMutation<CreateSessionResponse, Error, CreateToolSessionPayload>({
     mutationFn: (payload) => api.post('/api/tool-sessions/spawn', payload),
-    onSuccess: () => {
+    onSuccess: (data) => {
+      if (data?.status === 'pending') {
+        toast.info(t('toolSessionAwaitingApproval'));
+        return;
+      }
       qc.invalidateQueries({ queryKey: toolSessionKeys.list() });
     },
     onError: (err) => toast.error(err.message || t('createSessionFailed')),
@@ -225,3 +241,21 @@ export function useGenerateOTP() {
     onError: (err) => toast.error(err.message || t('otpUnavailable')),
   });
 }
+
+export function useUpdateSpectator() {
+  const qc = useQueryClient();
+  return useMutation<
+    SpectatorResponse,
+    Error,
+    { id: string; enabled: boolean }
+  >({
+    mutationFn: ({ id, enabled }) =>
+      api.post(`/api/tool-sessions/${encodeURIComponent(id)}/spectator`, {
+        enabled,
+      }),
+    onSuccess: () => {
+      qc.invalidateQueries({ queryKey: toolSessionKeys.list() });
+    },
+    onError: (err) => toast.error(err.message),
+  });
+}
@@ -0,0 +1,36 @@
+import { api } from '@/api/client';
+import { useQuery } from '@tanstack/react-query';
+
+export type UsageGroupBy = 'day' | 'week' | 'provider' | 'user' | 'model' | 'channel' | 'session';
+
+export interface UsageReportRow {
+  key: string;
+  requests: number;
+  prompt_tokens: number;
+  completion_tokens: number;
+  total_tokens: number;
+  cache_read_tokens: number;
+  cache_write_tokens: number;
+  cost: number;
+}
+
+export interface UsageReport {
+  from: string;
+  to: string;
+  group_by: UsageGroupBy;
+  rows: UsageReportRow[];
+  total: UsageReportRow;
+}
+
+export const usageKeys = {
+  all: ['usage'] as const,
+  report: (groupBy: UsageGroupBy) => [...usageKeys.all, 'report', groupBy] as const,
+};
+
+export function useUsageReport(groupBy: UsageGroupBy) {
+  return useQuery<UsageReport>({
+    queryKey: usageKeys.report(groupBy),
+    queryFn: () => api.get<UsageReport>(`/api/usage?groupBy=${encodeURIComponent(groupBy)}`),
+    staleTime: 60_000,
+  });
+}
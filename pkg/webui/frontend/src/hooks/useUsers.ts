@@ -34,6 +34,7 @@ export interface UserInput {
   password?: string;
   role: string;
   enabled: boolean;
+  tenant_slug?: string;
 }
 
 export class UserLimitError extends Error {
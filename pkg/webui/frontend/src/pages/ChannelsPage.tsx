@@ -14,7 +14,9 @@ import { Tabs, TabsList, TabsTrigger } from '@/components/ui/tabs';
 import { Skeleton } from '@/components/ui/skeleton';
 import {
   type ChannelConfig,
+  type WhatsAppState,
   useActivateWechatBinding,
+  useChannelIdentity,
   useChannels,
   useDeleteWechatBindingAccount,
   useDeleteWechatBinding,
@@ -22,6 +24,7 @@ import {
   useStartWechatBinding,
   useTestChannel,
   useWechatBindingStatus,
+  useWhatsAppStatus,
 } from '@/hooks/useChannels';
 import { useNotificationBindings, useNotificationRoutes, useSetNotificationBindingForTarget } from '@/hooks/useNotificationRoutes';
 import { ChannelForm } from '@/components/config/ChannelForm';
@@ -56,6 +59,8 @@ const channelColors: Record<string, string> = {
   maixcam: 'bg-orange-500',
   teams: 'bg-violet-600',
   infoflow: 'bg-rose-500',
+  matrix: 'bg-neutral-700',
+  signal: 'bg-blue-700',
   wechat: 'bg-emerald-700',
   email: 'bg-gray-500',
 };
@@ -267,6 +272,7 @@ export default function ChannelsPage() {
                       <div className="mt-3 text-xs text-muted-foreground">
                         {t('channelInstancesTypeLabel')}: <span className="font-medium text-foreground">{instance.type}</span>
                       </div>
+                      <ChannelIdentityLine id={instance.id} enabled={instance.enabled} />
                     </div>
                   ))}
                 </div>
@@ -292,6 +298,8 @@ export default function ChannelsPage() {
             />
           )}
 
+          {channelConfigs.whatsapp?.enabled && <WhatsAppStatusCard />}
+
           <div className="grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-3 xl:grid-cols-4 gap-4">
           {filteredChannels.map(([name, config]) => {
             if (name === 'wechat') return null;
@@ -411,6 +419,84 @@ export default function ChannelsPage() {
   );
 }
 
+function ChannelIdentityLine({ id, enabled }: { id: string; enabled: boolean }) {
+  const { data } = useChannelIdentity(id, enabled);
+  const identity = data?.identity;
+  if (!identity) return null;
+
+  const label = identity.username ? `@${identity.username}` : identity.display_name || identity.id;
+  return (
+    <div className="mt-1 text-xs text-muted-foreground">
+      {t('channelInstancesIdentityLabel')}:{' '}
+      <span className="font-medium text-foreground" title={identity.display_name || undefined}>
+        {label}
+      </span>
+    </div>
+  );
+}
+
+const whatsappStateStyles: Record<WhatsAppState, string> = {
+  connected: 'bg-emerald-50 text-emerald-700 dark:bg-emerald-950 dark:text-emerald-300',
+  pairing: 'bg-amber-50 text-amber-700 dark:bg-amber-950 dark:text-amber-300',
+  connecting: 'bg-sky-50 text-sky-700 dark:bg-sky-950 dark:text-sky-300',
+  reconnecting: 'bg-red-50 text-red-700 dark:bg-red-950 dark:text-red-300',
+  disconnected: 'bg-gray-100 text-gray-500 dark:bg-gray-800 dark:text-gray-400',
+};
+
+function WhatsAppStatusCard() {
+  const { data: status, error } = useWhatsAppStatus(true);
+  const state = status?.state ?? 'disconnected';
+
+  return (
+    <Card className="border-green-500/20">
+      <CardHeader className="pb-3">
+        <div className="flex items-start justify-between gap-3">
+          <div>
+            <CardTitle className="text-base">{t('whatsappBridgeTitle')}</CardTitle>
+            <CardDescription>{t('whatsappBridgeDescription')}</CardDescription>
+          </div>
+          <span className={cn('rounded-full px-2.5 py-0.5 text-xs font-medium', whatsappStateStyles[state])}>
+            {t(`whatsappState_${state}`)}
+          </span>
+        </div>
+      </CardHeader>
+      <CardContent className="grid gap-4 lg:grid-cols-[220px_1fr]">
+        <div className="rounded-xl border bg-card p-4 flex items-center justify-center min-h-[220px]">
+          {state === 'pairing' && status?.qr_png_data_url ? (
+            <img src={status.qr_png_data_url} alt={t('whatsappQrAlt')} className="w-full max-w-[180px] rounded-lg" />
+          ) : (
+            <div className="text-center text-sm text-muted-foreground">
+              {state === 'connected' ? t('whatsappPaired') : t('whatsappNoQr')}
+            </div>
+          )}
+        </div>
+        <div className="grid content-start gap-2 text-sm">
+          {error ? (
+            <div className="text-destructive">{t('whatsappStatusUnavailable')}</div>
+          ) : null}
+          {status?.bridge_url && (
+            <div>
+              <span className="text-muted-foreground">{t('whatsappBridgeUrlLabel')}</span>{' '}
+              <span className="font-mono text-xs">{status.bridge_url}</span>
+            </div>
+          )}
+          {state === 'pairing' && <div>{t('whatsappPairingHint')}</div>}
+          {state === 'reconnecting' && status && (
+            <div>
+              {t(
+                'whatsappReconnecting',
+                String(status.reconnect_attempts),
+                status.next_retry_at ? new Date(status.next_retry_at).toLocaleTimeString() : '-',
+              )}
+            </div>
+          )}
+          {status?.last_error && <div className="text-destructive">{status.last_error}</div>}
+        </div>
+      </CardContent>
+    </Card>
+  );
+}
+
 interface WechatBindingCardProps {
   enabled: boolean;
   binding?: {
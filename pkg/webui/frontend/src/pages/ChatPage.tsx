@@ -1,7 +1,7 @@
 import { useEffect, useMemo, useRef, useState } from 'react';
 import { useQuery } from '@tanstack/react-query';
 import { Link, useSearchParams } from 'react-router-dom';
-import { Send, Sparkles, RefreshCw, Trash2, Radio, Wand2, AlertCircle, ArrowRight, RotateCcw, Eye, EyeOff } from 'lucide-react';
+import { Send, ImagePlus, X, Sparkles, RefreshCw, Trash2, Radio, Wand2, AlertCircle, ArrowRight, RotateCcw, Eye, EyeOff, Bug, History } from 'lucide-react';
 import { toast } from '@/lib/notify';
 
 import { api } from '@/api/client';
@@ -21,7 +21,16 @@ import { useChat, type ChatMessage } from '@/hooks/useChat';
 import { useWatchStatus } from '@/hooks/useConfig';
 import { useModels, useModelRoutesForModels, buildModelOptions } from '@/hooks/useModels';
 import { useProviders } from '@/hooks/useProviders';
-import { useSessionDetail, useSessionDetailWithOptions, useUpdateSessionRuntime } from '@/hooks/useSessions';
+import {
+  useChatDeveloperMode,
+  useChatSessionMessages,
+  useChatSessions,
+  useDeleteChatSession,
+  useSessionDetail,
+  useSessionDetailWithOptions,
+  useSetChatDeveloperMode,
+  useUpdateSessionRuntime,
+} from '@/hooks/useSessions';
 import { useAccountBindings, useChannelAccounts, useRuntimeAgents } from '@/hooks/useTopology';
 import { useThreadDetail } from '@/hooks/useThreads';
 import { t } from '@/lib/i18n';
@@ -29,6 +38,9 @@ import { cn } from '@/lib/utils';
 import { MessageBubble } from '@/components/chat/MessageBubble';
 import { StatusPill } from '@/components/chat/StatusPill';
 import { ChatLoadErrorState } from '@/components/chat/ChatLoadErrorState';
+import { DeveloperDebugPanel } from '@/components/chat/DeveloperDebugPanel';
+import { PersonaEditor } from '@/components/chat/PersonaEditor';
+import { ChatLinkPanel } from '@/components/chat/ChatLinkPanel';
 
 interface ProviderGroupInfo {
   name: string;
@@ -47,6 +59,13 @@ interface ConfigData {
   };
 }
 
+interface ChatUpload {
+  id: string;
+  name: string;
+  mime_type: string;
+  size: number;
+}
+
 interface RouteTarget {
   name: string;
   type: 'provider' | 'group';
@@ -194,6 +213,7 @@ export default function ChatPage() {
     daemonEventStreamStatus,
     fileMentionFeedback,
     clearFileMentionFeedback,
+    toolActivity,
   } = useChat();
   const { data: watchStatus } = useWatchStatus();
   const providers = providersQuery.data ?? [];
@@ -219,6 +239,9 @@ export default function ChatPage() {
   const [selectedRuntimeID, setSelectedRuntimeID] = useState('');
   const [selectedFallbackTargets, setSelectedFallbackTargets] = useState<string[]>([]);
   const [chatInput, setChatInput] = useState('');
+  const [pendingImages, setPendingImages] = useState<ChatUpload[]>([]);
+  const [uploadingImage, setUploadingImage] = useState(false);
+  const imageInputRef = useRef<HTMLInputElement>(null);
   const [showFileMentionDetails, setShowFileMentionDetails] = useState(false);
   const scrollEndRef = useRef<HTMLDivElement>(null);
   const routeTargetMap = useMemo(
@@ -309,6 +332,12 @@ export default function ChatPage() {
     enabled: !!activeRuntimeID,
   });
   const updateSessionRuntime = useUpdateSessionRuntime();
+  const { data: chatSessions = [], refetch: refetchChatSessions } = useChatSessions();
+  const deleteChatSession = useDeleteChatSession();
+  const { data: storedChatMessages } = useChatSessionMessages(activeRuntimeID ? null : baseChatSessionID);
+  const restoredChatSessionsRef = useRef(new Set<string>());
+  const { data: developerMode = false } = useChatDeveloperMode(activeSessionBindingID);
+  const setDeveloperMode = useSetChatDeveloperMode();
   const activeFallback = selectedFallbackTargets.filter((target) => target.trim().length > 0);
   const actualProvider = routeResult?.actual_provider?.trim() || '';
   const actualModel = routeResult?.actual_model?.trim() || '';
@@ -437,12 +466,42 @@ export default function ChatPage() {
       role: message.role as ChatMessage['role'],
       content: message.content,
       timestamp: Date.now() + index,
+      messageIndex: index,
     }));
     if (nextMessages.length > 0) {
       replaceMessages(activeSessionBindingID, nextMessages);
     }
   }, [activeRuntimeID, activeSessionBindingID, activeSessionDetail, replaceMessages]);
 
+  useEffect(() => {
+    if (!isAwaitingReply) {
+      void refetchChatSessions();
+    }
+  }, [isAwaitingReply, refetchChatSessions]);
+
+  // Resume the stored playground history once per page load; runtime
+  // sessions are restored from their session detail above.
+  useEffect(() => {
+    if (activeRuntimeID || !storedChatMessages || restoredChatSessionsRef.current.has(baseChatSessionID)) {
+      return;
+    }
+    restoredChatSessionsRef.current.add(baseChatSessionID);
+    if (messages.length > 0) {
+      return;
+    }
+    const nextMessages = storedChatMessages
+      .map((message, index) => ({
+        role: message.role as ChatMessage['role'],
+        content: message.content,
+        timestamp: Date.now() + index,
+        messageIndex: index,
+      }))
+      .filter((message) => (message.role === 'user' || message.role === 'assistant') && message.content.trim() !== '');
+    if (nextMessages.length > 0) {
+      replaceMessages(baseChatSessionID, nextMessages);
+    }
+  }, [activeRuntimeID, storedChatMessages, messages.length, replaceMessages]);
+
   function handleProviderChange(value: string) {
     const provider = fromSelectValue(value);
     setSelectedProvider(provider);
@@ -474,9 +533,41 @@ export default function ChatPage() {
     updateSessionRuntime.mutate({ id: baseChatSessionID, runtime_id: nextRuntimeID });
   }
 
+  function handleDeleteChatSession(chatSessionID: string) {
+    deleteChatSession.mutate(chatSessionID, {
+      onSuccess: () => {
+        if (chatSessionID === activeSessionBindingID) {
+          replaceMessages(chatSessionID, []);
+        }
+      },
+    });
+  }
+
+  async function handleAttachImages(files: FileList | null) {
+    if (!files || files.length === 0) {
+      return;
+    }
+    setUploadingImage(true);
+    try {
+      for (const file of Array.from(files)) {
+        const form = new FormData();
+        form.append('file', file);
+        const upload = await api.upload<ChatUpload>('/api/chat/attachments', form);
+        setPendingImages((prev) => [...prev, upload]);
+      }
+    } catch (error) {
+      toast.error(t('chatAttachImageFailed', error instanceof Error ? error.message : String(error)));
+    } finally {
+      setUploadingImage(false);
+      if (imageInputRef.current) {
+        imageInputRef.current.value = '';
+      }
+    }
+  }
+
   function handleSend() {
     const content = chatInput.trim();
-    if (!content || composerDisabled) {
+    if ((!content && pendingImages.length === 0) || composerDisabled || uploadingImage) {
       return;
     }
 
@@ -488,8 +579,10 @@ export default function ChatPage() {
       systemPromptIDs: [],
       userPromptIDs: [],
       runtimeID: activeRuntimeID,
+      attachmentIDs: pendingImages.map((image) => image.id),
     });
     setChatInput('');
+    setPendingImages([]);
   }
 
   function handleInputKeyDown(event: React.KeyboardEvent<HTMLTextAreaElement>) {
@@ -516,6 +609,7 @@ export default function ChatPage() {
         role: message.role as ChatMessage['role'],
         content: message.content,
         timestamp: Date.now() + index,
+        messageIndex: index,
       })));
       clearFileMentionFeedback();
       toast.success(t('chatUndoSuccess', String(result.undone_steps ?? 0)));
@@ -525,6 +619,24 @@ export default function ChatPage() {
     }
   }
 
+  async function handleRateMessage(message: ChatMessage, rating: 'up' | 'down') {
+    if (message.messageIndex === undefined) {
+      return;
+    }
+    try {
+      await api.post('/api/feedback', {
+        runtime_id: activeRuntimeID,
+        message_index: message.messageIndex,
+        rating,
+      });
+      toast.success(t('chatFeedbackSaved'));
+    } catch (error) {
+      const errorMessage = error instanceof Error ? error.message : t('chatFeedbackFailed');
+      toast.error(errorMessage);
+      throw error;
+    }
+  }
+
   return (
     <div className="chat-page flex min-h-0 flex-1 flex-col overflow-hidden">
       <Header title={t('tabChat')} className="mb-3 md:mb-4" />
@@ -680,9 +792,22 @@ export default function ChatPage() {
             </div>
 
             <div className="rounded-[1.5rem] border border-[hsl(var(--brand-200))] bg-[linear-gradient(180deg,rgba(255,252,250,0.92),rgba(252,241,245,0.8))] p-4 dark:bg-card/90">
-              <div className="eyebrow-label mb-3 flex items-center gap-2 text-[hsl(var(--brand-700))]">
-                <Radio className="h-3.5 w-3.5" />
-                {t('chatActualRoute')}
+              <div className="mb-3 flex items-center justify-between gap-2">
+                <div className="eyebrow-label flex items-center gap-2 text-[hsl(var(--brand-700))]">
+                  <Radio className="h-3.5 w-3.5" />
+                  {t('chatActualRoute')}
+                </div>
+                <Button
+                  type="button"
+                  variant={developerMode ? 'default' : 'ghost'}
+                  className="h-8 rounded-full px-3 text-xs"
+                  title={t('chatDeveloperModeHint')}
+                  disabled={setDeveloperMode.isPending}
+                  onClick={() => setDeveloperMode.mutate({ id: activeSessionBindingID, enabled: !developerMode })}
+                >
+                  <Bug className="mr-1.5 h-3.5 w-3.5" />
+                  {t('chatDeveloperMode')}
+                </Button>
               </div>
               <div className="flex flex-wrap gap-2">
                 {actualProvider || actualModel ? (
@@ -757,11 +882,16 @@ export default function ChatPage() {
                   )}
                 </>
               ) : null}
+              {routeResult?.debug && <DeveloperDebugPanel debug={routeResult.debug} />}
               <p className="mt-3 text-sm leading-6 text-muted-foreground">
                 {t('chatActualRouteHint')}
               </p>
             </div>
 
+            <PersonaEditor sessionID={activeSessionBindingID} />
+
+            <ChatLinkPanel />
+
             <div className="space-y-3">
               <label className="eyebrow-label text-muted-foreground">
                 {t('chatRuntimeTarget')}
@@ -898,6 +1028,58 @@ export default function ChatPage() {
               />
             </div>
 
+            {chatSessions.length > 0 && (
+              <div className="space-y-3">
+                <label className="eyebrow-label flex items-center gap-2 text-muted-foreground">
+                  <History className="h-3.5 w-3.5" />
+                  {t('chatHistoryTitle')}
+                </label>
+                <div className="space-y-2">
+                  {chatSessions.map((item) => {
+                    const runtime = chatRuntimes.find((entry) => entry.id === item.runtime_id);
+                    const label =
+                      item.title ||
+                      (item.runtime_id
+                        ? runtime?.display_name || runtime?.name || item.runtime_id
+                        : t('chatHistoryDefault'));
+                    return (
+                      <div
+                        key={item.id}
+                        className={cn(
+                          'flex items-center gap-2 rounded-2xl border px-3 py-2',
+                          item.id === activeSessionBindingID
+                            ? 'border-[hsl(var(--brand-300))] bg-[hsl(var(--brand-50))]/60 dark:bg-[hsl(var(--brand-950))]/20'
+                            : 'border-border/70 bg-card/90',
+                        )}
+                      >
+                        <button
+                          type="button"
+                          className="min-w-0 flex-1 text-left"
+                          onClick={() => handleRuntimeBindingChange(toSelectValue(item.runtime_id))}
+                        >
+                          <div className="truncate text-sm font-medium text-foreground">{label}</div>
+                          <div className="text-xs text-muted-foreground">
+                            {t('chatHistoryMeta', String(item.message_count), new Date(item.updated_at).toLocaleString())}
+                          </div>
+                        </button>
+                        <Button
+                          variant="ghost"
+                          size="icon"
+                          className="h-8 w-8 rounded-full"
+                          title={t('chatHistoryDelete')}
+                          aria-label={t('chatHistoryDelete')}
+                          disabled={deleteChatSession.isPending}
+                          onClick={() => handleDeleteChatSession(item.id)}
+                        >
+                          <Trash2 className="h-3.5 w-3.5" />
+                        </Button>
+                      </div>
+                    );
+                  })}
+                </div>
+              </div>
+            )}
+
             <div className="flex flex-col gap-2 pt-2 sm:flex-row sm:flex-wrap">
               {connectionStatus !== 'connected' && (
                 <Button variant="outline" className="h-11 rounded-full sm:min-w-[140px]" onClick={reconnect}>
@@ -1028,12 +1210,12 @@ export default function ChatPage() {
                     </div>
                   )}
                   {messages.map((message, index) => (
-                    <MessageBubble key={`${message.timestamp}-${index}`} message={message} />
+                    <MessageBubble key={`${message.timestamp}-${index}`} message={message} onRate={handleRateMessage} />
                   ))}
-                  {isAwaitingReply && (
+                  {isAwaitingReply && (toolActivity || !messages[messages.length - 1]?.streaming) && (
                     <div className="flex justify-start">
                       <div className="rounded-full border border-[hsl(var(--brand-200))] bg-card/92 px-4 py-2 text-sm text-muted-foreground shadow-sm">
-                        {t('chatWaitingReply')}
+                        {toolActivity ? t('chatRunningTool', toolActivity) : t('chatWaitingReply')}
                       </div>
                     </div>
                   )}
@@ -1054,6 +1236,27 @@ export default function ChatPage() {
                   onKeyDown={handleInputKeyDown}
                   disabled={composerDisabled}
                 />
+                {pendingImages.length > 0 && (
+                  <div className="flex flex-wrap gap-2 px-2 pt-2">
+                    {pendingImages.map((image) => (
+                      <span
+                        key={image.id}
+                        className="inline-flex items-center gap-1 rounded-full border border-[hsl(var(--gray-200))] bg-card px-3 py-1 text-xs text-muted-foreground"
+                      >
+                        <ImagePlus className="h-3.5 w-3.5" />
+                        {image.name || image.mime_type}
+                        <button
+                          type="button"
+                          className="ml-1 rounded-full hover:text-foreground"
+                          aria-label={t('chatRemoveImage')}
+                          onClick={() => setPendingImages((prev) => prev.filter((item) => item.id !== image.id))}
+                        >
+                          <X className="h-3 w-3" />
+                        </button>
+                      </span>
+                    ))}
+                  </div>
+                )}
                 <div className="mt-3 flex flex-col gap-3 border-t border-[hsl(var(--gray-200))]/80 px-2 pt-3 sm:flex-row sm:items-center sm:justify-between">
                   <div className="space-y-1 text-xs text-muted-foreground">
                     <div>{t('chatComposerHint')}</div>
@@ -1067,6 +1270,24 @@ export default function ChatPage() {
                     )}
                   </div>
                   <div className="flex w-full flex-col gap-2 sm:w-auto sm:flex-row sm:self-end">
+                    <input
+                      ref={imageInputRef}
+                      type="file"
+                      accept="image/*"
+                      multiple
+                      className="hidden"
+                      onChange={(event) => void handleAttachImages(event.target.files)}
+                    />
+                    <Button
+                      type="button"
+                      variant="outline"
+                      className="h-11 rounded-full px-5"
+                      onClick={() => imageInputRef.current?.click()}
+                      disabled={composerDisabled || uploadingImage}
+                    >
+                      <ImagePlus className="mr-2 h-4 w-4" />
+                      {uploadingImage ? t('chatAttachingImage') : t('chatAttachImage')}
+                    </Button>
                     <Button
                       type="button"
                       variant="outline"
@@ -1079,7 +1300,7 @@ export default function ChatPage() {
                     <Button
                       className="h-11 rounded-full px-5"
                       onClick={handleSend}
-                      disabled={composerDisabled || !chatInput.trim()}
+                      disabled={composerDisabled || uploadingImage || (!chatInput.trim() && pendingImages.length === 0)}
                     >
                       <Send className="mr-2 h-4 w-4" />
                       {t('send')}
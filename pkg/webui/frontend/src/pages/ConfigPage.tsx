@@ -55,6 +55,7 @@ const CONFIG_SECTIONS = [
   'gateway',
   'tools',
   'transcription',
+  'tts',
   'memory',
   'sessions',
   'heartbeat',
@@ -69,6 +70,14 @@ const CONFIG_SECTIONS = [
   'preprocess',
   'learnings',
   'watch',
+  'motd',
+  'alerts',
+  'moderation',
+  'usage',
+  'response_filters',
+  'turn_limits',
+  'backup',
+  'tool_sessions',
 ] as const;
 
 type ConfigSection = (typeof CONFIG_SECTIONS)[number];
@@ -107,6 +116,7 @@ const SECTION_META: Record<ConfigSection, SectionMeta> = {
   gateway: { labelKey: 'configSectionGateway', descriptionKey: 'configSectionDescGateway' },
   tools: { labelKey: 'configSectionTools', descriptionKey: 'configSectionDescTools' },
   transcription: { labelKey: 'configSectionTranscription', descriptionKey: 'configSectionDescTranscription' },
+  tts: { labelKey: 'configSectionTTS', descriptionKey: 'configSectionDescTTS' },
   memory: { labelKey: 'configSectionMemory', descriptionKey: 'configSectionDescMemory' },
   sessions: { labelKey: 'configSectionSessions', descriptionKey: 'configSectionDescSessions' },
   heartbeat: { labelKey: 'configSectionHeartbeat', descriptionKey: 'configSectionDescHeartbeat' },
@@ -121,6 +131,14 @@ const SECTION_META: Record<ConfigSection, SectionMeta> = {
   preprocess: { labelKey: 'configSectionPreprocess', descriptionKey: 'configSectionDescPreprocess' },
   learnings: { labelKey: 'configSectionLearnings', descriptionKey: 'configSectionDescLearnings' },
   watch: { labelKey: 'configSectionWatch', descriptionKey: 'configSectionDescWatch' },
+  motd: { labelKey: 'configSectionMotd', descriptionKey: 'configSectionDescMotd' },
+  alerts: { labelKey: 'configSectionAlerts', descriptionKey: 'configSectionDescAlerts' },
+  moderation: { labelKey: 'configSectionModeration', descriptionKey: 'configSectionDescModeration' },
+  usage: { labelKey: 'configSectionUsage', descriptionKey: 'configSectionDescUsage' },
+  response_filters: { labelKey: 'configSectionResponseFilters', descriptionKey: 'configSectionDescResponseFilters' },
+  turn_limits: { labelKey: 'configSectionTurnLimits', descriptionKey: 'configSectionDescTurnLimits' },
+  backup: { labelKey: 'configSectionBackup', descriptionKey: 'configSectionDescBackup' },
+  tool_sessions: { labelKey: 'configSectionToolSessions', descriptionKey: 'configSectionDescToolSessions' },
 };
 
 function sectionLabel(section: ConfigSection): string {
@@ -714,6 +732,13 @@ function AgentsSectionForm({
               onChange={(event) => onChange('defaults.skills_proxy', event.target.value)}
             />
           </MemoryField>
+          <MemoryField label={t('agentsSkillsIndexURL')}>
+            <Input
+              className="h-11 rounded-xl bg-background"
+              value={readString('defaults.skills_index_url')}
+              onChange={(event) => onChange('defaults.skills_index_url', event.target.value)}
+            />
+          </MemoryField>
           <div className="flex items-center justify-between rounded-2xl border border-border/70 bg-card/92 p-4">
             <div>
               <Label className="text-sm font-semibold text-foreground">{t('agentsRestrictWorkspace')}</Label>
@@ -726,6 +751,12 @@ function AgentsSectionForm({
             </div>
             <Switch checked={readBool('defaults.skills_auto_reload')} onCheckedChange={(next) => onChange('defaults.skills_auto_reload', next)} />
           </div>
+          <div className="flex items-center justify-between rounded-2xl border border-border/70 bg-card/92 p-4">
+            <div>
+              <Label className="text-sm font-semibold text-foreground">{t('agentsSkillsIndexOnly')}</Label>
+            </div>
+            <Switch checked={readBool('defaults.skills_index_only')} onCheckedChange={(next) => onChange('defaults.skills_index_only', next)} />
+          </div>
         </CardContent>
       </Card>
     </div>
@@ -1716,6 +1747,16 @@ function WebUISectionForm({
                 onChange={(event) => onChange('tool_session_otp_ttl_seconds', Number(event.target.value || 0))}
               />
             </div>
+            <div className="rounded-2xl border border-[hsl(var(--gray-200))] bg-white/82 p-4">
+              <Label className="text-sm font-semibold text-foreground">{t('webuiToolSessionScrollbackBytes')}</Label>
+              <div className="mt-1 mb-3 text-xs text-muted-foreground">{t('webuiToolSessionScrollbackBytesDesc')}</div>
+              <Input
+                type="number"
+                min={0}
+                value={String(readNumber('tool_session_scrollback_bytes'))}
+                onChange={(event) => onChange('tool_session_scrollback_bytes', Number(event.target.value || 0))}
+              />
+            </div>
           </div>
 
           <div className="flex items-center justify-between rounded-2xl border border-border/70 bg-card/92 p-4">
@@ -1739,6 +1780,7 @@ function WebUISectionForm({
               <SelectContent>
                 <SelectItem value="tmux">{t('runtimeTransportTmux')}</SelectItem>
                 <SelectItem value="zellij">{t('runtimeTransportZellij')}</SelectItem>
+                <SelectItem value="native">{t('runtimeTransportNative')}</SelectItem>
               </SelectContent>
             </Select>
             {runtimeTransports.length > 0 ? (
@@ -1809,6 +1851,33 @@ function WebUISectionForm({
             </CardContent>
           </Card>
 
+          <Card className="border-border/70 bg-card/92 shadow-none">
+            <CardHeader className="pb-3">
+              <CardTitle className="text-base">{t('webuiToolSessionLimitsTitle')}</CardTitle>
+              <CardDescription>{t('webuiToolSessionLimitsDesc')}</CardDescription>
+            </CardHeader>
+            <CardContent className="grid gap-3 md:grid-cols-2">
+              {[
+                ['max_cpu_percent', 'webuiToolSessionLimitsMaxCPUPercent'],
+                ['cpu_grace_seconds', 'webuiToolSessionLimitsCPUGraceSeconds'],
+                ['max_memory_mb', 'webuiToolSessionLimitsMaxMemoryMB'],
+                ['max_output_bytes', 'webuiToolSessionLimitsMaxOutputBytes'],
+                ['max_wall_clock_seconds', 'webuiToolSessionLimitsMaxWallClockSeconds'],
+              ].map(([key, labelKey]) => (
+                <div key={key} className="rounded-2xl border border-[hsl(var(--gray-200))] bg-white/82 p-4">
+                  <Label className="text-sm font-semibold text-foreground">{t(labelKey)}</Label>
+                  <Input
+                    className="mt-3"
+                    type="number"
+                    min={0}
+                    value={String(readNumber(`tool_session_limits.${key}`))}
+                    onChange={(event) => onChange(`tool_session_limits.${key}`, Number(event.target.value || 0))}
+                  />
+                </div>
+              ))}
+            </CardContent>
+          </Card>
+
           <Card className="border-border/70 bg-card/92 shadow-none">
             <CardHeader className="pb-3">
               <CardTitle className="text-base">{t('webuiSkillSnapshotsTitle')}</CardTitle>
@@ -16,11 +16,46 @@ interface InitStatusResponse {
   initialized: boolean;
 }
 
+interface MOTDResponse {
+  enabled: boolean;
+  title?: string;
+  message?: string;
+  current_version?: string;
+  latest_version?: string;
+  update_available: boolean;
+  update_url?: string;
+}
+
 export default function LoginPage() {
   const navigate = useNavigate();
   const [username, setUsername] = useState('');
   const [password, setPassword] = useState('');
   const [loading, setLoading] = useState(false);
+  const [motd, setMotd] = useState<MOTDResponse | null>(null);
+
+  useEffect(() => {
+    let cancelled = false;
+
+    fetch('/api/motd')
+      .then(async (resp) => {
+        if (!resp.ok) {
+          throw new Error(`motd failed: ${resp.status}`);
+        }
+        return (await resp.json()) as MOTDResponse;
+      })
+      .then((data) => {
+        if (!cancelled && data.enabled) {
+          setMotd(data);
+        }
+      })
+      .catch(() => {
+        // The banner is informational; never block login on it.
+      });
+
+    return () => {
+      cancelled = true;
+    };
+  }, []);
 
   useEffect(() => {
     let cancelled = false;
@@ -128,6 +163,33 @@ export default function LoginPage() {
                 </div>
               </div>
 
+              {motd && (motd.title || motd.message || motd.update_available) && (
+                <div className="mb-6 space-y-1 rounded-xl border border-border/70 bg-muted/40 px-4 py-3 text-sm">
+                  {motd.title && <p className="font-medium text-foreground">{motd.title}</p>}
+                  {motd.message && (
+                    <p className="whitespace-pre-line text-muted-foreground">{motd.message}</p>
+                  )}
+                  {motd.update_available && (
+                    <p className="text-foreground">
+                      {t('loginUpdateAvailable', motd.latest_version ?? '', motd.current_version ?? '')}
+                      {motd.update_url && (
+                        <>
+                          {' '}
+                          <a
+                            href={motd.update_url}
+                            target="_blank"
+                            rel="noreferrer"
+                            className="underline underline-offset-2"
+                          >
+                            {t('loginUpdateLink')}
+                          </a>
+                        </>
+                      )}
+                    </p>
+                  )}
+                </div>
+              )}
+
               <form onSubmit={handleSubmit} className="space-y-4">
                 <div>
                   <label
@@ -16,10 +16,12 @@ import {
 import { useConfig, useSaveConfig } from '@/hooks/useConfig';
 import {
   useClearProviderCooldown,
+  useProbeProviderCapabilities,
   useProviderRuntime,
   useProviders,
   useTestProvider,
   type Provider,
+  type ProviderCapabilities,
   type ProviderRuntime,
 } from '@/hooks/useProviders';
 import { useProviderTypes } from '@/hooks/useProviderTypes';
@@ -106,6 +108,7 @@ export default function ProvidersPage() {
   const saveConfig = useSaveConfig();
   const clearProviderCooldown = useClearProviderCooldown();
   const testProvider = useTestProvider();
+  const probeCapabilities = useProbeProviderCapabilities();
 
   const [formOpen, setFormOpen] = useState(false);
   const [editingProvider, setEditingProvider] = useState<Provider | null>(null);
@@ -400,6 +403,8 @@ export default function ProvidersPage() {
               onShowRuntimeDetails={(runtime) => setRuntimeDetailProvider(runtime)}
               onTestProvider={(providerName) => testProvider.mutate(providerName)}
               testingProvider={testProvider.isPending}
+              onProbeCapabilities={(providerName) => probeCapabilities.mutate(providerName)}
+              probingCapabilities={probeCapabilities.isPending}
               clearingCooldown={clearProviderCooldown.isPending}
             />
           ))}
@@ -495,7 +500,7 @@ function MetricCard({ label, value, muted }: { label: string; value: string; mut
   );
 }
 
-function ProviderPanel({ provider, runtime, typeMeta, onClick, onClearCooldown, onShowRuntimeDetails, onTestProvider, testingProvider, clearingCooldown }: { provider: Provider; runtime?: ProviderRuntime; typeMeta?: { display_name: string; description: string; default_api_base?: string; icon: string }; onClick: () => void; onClearCooldown: (providerName: string) => void; onShowRuntimeDetails: (runtime: ProviderRuntime) => void; onTestProvider: (providerName: string) => void; testingProvider: boolean; clearingCooldown: boolean; }) {
+function ProviderPanel({ provider, runtime, typeMeta, onClick, onClearCooldown, onShowRuntimeDetails, onTestProvider, testingProvider, onProbeCapabilities, probingCapabilities, clearingCooldown }: { provider: Provider; runtime?: ProviderRuntime; typeMeta?: { display_name: string; description: string; default_api_base?: string; icon: string }; onClick: () => void; onClearCooldown: (providerName: string) => void; onShowRuntimeDetails: (runtime: ProviderRuntime) => void; onTestProvider: (providerName: string) => void; testingProvider: boolean; onProbeCapabilities: (providerName: string) => void; probingCapabilities: boolean; clearingCooldown: boolean; }) {
   const state = getConnectionState(provider, runtime);
   const logo = getProviderLogo(typeMeta?.icon || provider.provider_kind);
   const tint = getKindTint(provider.provider_kind);
@@ -559,14 +564,20 @@ function ProviderPanel({ provider, runtime, typeMeta, onClick, onClearCooldown,
               {runtime?.in_cooldown ? t('providerRuntimeCooldown') : runtime?.available === false ? t('providerRuntimeUnavailable') : t('providerRuntimeAvailable')}
             </span>
             <span className="rounded-full border border-slate-200 bg-card px-3 py-1.5 text-xs text-muted-foreground">{t('providerRuntimeErrors', String(runtime?.error_count ?? 0))}</span>
+            {runtime && runtime.latency_samples > 0 && <span className="rounded-full border border-slate-200 bg-card px-3 py-1.5 text-xs text-muted-foreground">{t('providerRuntimeLatency', String(runtime.latency_ms), String(Math.round(runtime.error_rate * 100)))}</span>}
+            {runtime?.circuit_state === 'half_open' && <span className="rounded-full border border-amber-200 bg-card px-3 py-1.5 text-xs text-amber-800">{t('providerRuntimeHalfOpen')}</span>}
             {runtime?.in_cooldown && <span className="rounded-full border border-amber-200 bg-card px-3 py-1.5 text-xs text-amber-800">{t('providerRuntimeRemaining', formatDuration(runtime.cooldown_remaining_seconds))}</span>}
             {runtime?.disabled_reason && <span className="rounded-full border border-rose-200 bg-card px-3 py-1.5 text-xs text-rose-700">{t('providerRuntimeReason', runtime.disabled_reason)}</span>}
           </div>
           <p className="mt-3 text-sm leading-6 text-muted-foreground">{formatFailureSummary(runtime)}</p>
+          <ProviderCapabilityChips capabilities={provider.capabilities} />
           <div className="mt-3 flex flex-wrap gap-2">
             <Button type="button" variant="outline" className="rounded-full" onClick={(event) => { event.stopPropagation(); onTestProvider(provider.name); }} disabled={testingProvider}>
               <Play className="mr-2 h-4 w-4" />Test provider
             </Button>
+            <Button type="button" variant="outline" className="rounded-full" onClick={(event) => { event.stopPropagation(); onProbeCapabilities(provider.name); }} disabled={probingCapabilities}>
+              <Sparkles className="mr-2 h-4 w-4" />{t('providerProbeCapabilities')}
+            </Button>
             {runtime && runtime.error_count > 0 ? <Button type="button" variant="outline" className="rounded-full" onClick={(event) => { event.stopPropagation(); onShowRuntimeDetails(runtime); }}>{t('providerRuntimeViewErrors', String(runtime.error_count))}</Button> : null}
             {runtime?.in_cooldown ? <Button type="button" variant="outline" className="rounded-full" onClick={(event) => { event.stopPropagation(); onClearCooldown(provider.name); }} disabled={clearingCooldown}><TimerReset className="mr-2 h-4 w-4" />{t('providerCooldownClear')}</Button> : null}
           </div>
@@ -576,6 +587,34 @@ function ProviderPanel({ provider, runtime, typeMeta, onClick, onClearCooldown,
   )
 }
 
+function ProviderCapabilityChips({ capabilities }: { capabilities?: ProviderCapabilities | null }) {
+  if (!capabilities) {
+    return <p className="mt-2 text-xs text-muted-foreground">{t('providerCapabilitiesNotProbed')}</p>;
+  }
+  const features: Array<[string, boolean | undefined]> = [
+    [t('providerCapabilityToolCalling'), capabilities.tool_calling],
+    [t('providerCapabilityStreaming'), capabilities.streaming],
+    [t('providerCapabilityVision'), capabilities.vision],
+    [t('providerCapabilityJSONMode'), capabilities.json_mode],
+  ];
+  return (
+    <div className="mt-2 flex flex-wrap items-center gap-2 text-xs">
+      <span className="text-muted-foreground">{t('providerCapabilitiesFor', capabilities.model)}</span>
+      {features.map(([label, supported]) => (
+        <span
+          key={label}
+          className={cn(
+            'rounded-full border px-2.5 py-1',
+            supported === undefined ? 'border-slate-200 text-muted-foreground' : supported ? 'border-emerald-200 text-emerald-700' : 'border-rose-200 text-rose-700',
+          )}
+        >
+          {label}: {supported === undefined ? '?' : supported ? '✓' : '✗'}
+        </span>
+      ))}
+    </div>
+  );
+}
+
 function formatDuration(totalSeconds: number): string {
   if (!totalSeconds || totalSeconds <= 0) return t('durationSecondsCompact', '0');
   const minutes = Math.floor(totalSeconds / 60)
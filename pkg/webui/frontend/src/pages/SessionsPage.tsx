@@ -13,14 +13,20 @@ import { ScrollArea } from '@/components/ui/scroll-area';
 import { t } from '@/lib/i18n';
 import { cn } from '@/lib/utils';
 import {
+  useAddSessionPin,
+  useArchiveSession,
   useDeleteSession,
+  useExportSession,
+  useRemoveSessionPin,
+  useRetitleSession,
   useSessionDetail,
   useSessions,
   useUpdateSessionThread,
   useUpdateSessionRuntime,
   useUpdateSessionSummary,
+  useUpdateSessionTitle,
 } from '@/hooks/useSessions';
-import { Save, Trash2, Loader2, MessageSquare } from 'lucide-react';
+import { Archive, ArchiveRestore, Download, Save, Trash2, Loader2, MessageSquare, Pin, Sparkles, X } from 'lucide-react';
 import { useNavigate } from 'react-router-dom';
 import { useRuntimeAgents } from '@/hooks/useTopology';
 import {
@@ -42,28 +48,51 @@ function formatDateTime(value: string): string {
 
 export default function SessionsPage() {
   const navigate = useNavigate();
-  const { data: sessions = [], isLoading } = useSessions();
+  const [showArchived, setShowArchived] = useState(false);
+  const [channelFilter, setChannelFilter] = useState('');
+  const [searchDraft, setSearchDraft] = useState('');
+  const { data: sessions = [], isLoading } = useSessions({ archived: showArchived });
   const [selectedId, setSelectedId] = useState('');
+  const [titleDraft, setTitleDraft] = useState('');
   const [summaryDraft, setSummaryDraft] = useState('');
   const [runtimeDraft, setRuntimeDraft] = useState('');
   const [topicDraft, setTopicDraft] = useState('');
+  const [pinDraft, setPinDraft] = useState('');
 
+  const updateTitle = useUpdateSessionTitle();
+  const retitle = useRetitleSession();
   const updateSummary = useUpdateSessionSummary();
   const updateRuntime = useUpdateSessionRuntime();
   const updateThread = useUpdateSessionThread();
   const deleteSession = useDeleteSession();
+  const exportSession = useExportSession();
+  const archiveSession = useArchiveSession();
+  const addPin = useAddSessionPin();
+  const removePin = useRemoveSessionPin();
   const [showDeleteConfirm, setShowDeleteConfirm] = useState(false);
   const { data: runtimes = [] } = useRuntimeAgents();
 
-  const sortedSessions = useMemo(
-    () =>
-      [...sessions].sort(
-        (a, b) =>
-          new Date(b.updated_at).getTime() - new Date(a.updated_at).getTime(),
-      ),
+  const channels = useMemo(
+    () => Array.from(new Set(sessions.map((item) => item.channel).filter(Boolean))).sort(),
     [sessions],
   );
 
+  const sortedSessions = useMemo(() => {
+    const query = searchDraft.trim().toLowerCase();
+    return sessions
+      .filter((item) => !channelFilter || item.channel === channelFilter)
+      .filter(
+        (item) =>
+          !query ||
+          item.id.toLowerCase().includes(query) ||
+          (item.title || '').toLowerCase().includes(query),
+      )
+      .sort(
+        (a, b) =>
+          new Date(b.updated_at).getTime() - new Date(a.updated_at).getTime(),
+      );
+  }, [sessions, channelFilter, searchDraft]);
+
   const selectedExists = sortedSessions.some((item) => item.id === selectedId);
 
   useEffect(() => {
@@ -87,12 +116,23 @@ export default function SessionsPage() {
 
   useEffect(() => {
     if (detail) {
+      setTitleDraft(detail.title || '');
       setSummaryDraft(detail.summary || '');
       setRuntimeDraft(detail.runtime_id || '');
       setTopicDraft(detail.topic || '');
     }
   }, [detail]);
 
+  const handleSaveTitle = () => {
+    if (!detail) return;
+    updateTitle.mutate({ id: detail.id, title: titleDraft });
+  };
+
+  const handleRetitle = () => {
+    if (!detail) return;
+    retitle.mutate(detail.id);
+  };
+
   const handleSaveSummary = () => {
     if (!detail) return;
     updateSummary.mutate({ id: detail.id, summary: summaryDraft });
@@ -108,6 +148,14 @@ export default function SessionsPage() {
     updateThread.mutate({ id: detail.id, topic: topicDraft });
   };
 
+  const handleAddPin = () => {
+    if (!detail || !pinDraft.trim()) return;
+    addPin.mutate(
+      { id: detail.id, text: pinDraft.trim() },
+      { onSuccess: () => setPinDraft('') },
+    );
+  };
+
   const handleDeleteSession = () => {
     if (!detail) return;
     setShowDeleteConfirm(true);
@@ -139,6 +187,39 @@ export default function SessionsPage() {
             <CardDescription>
               {t('sessionListCount', String(sortedSessions.length))}
             </CardDescription>
+            <div className="flex flex-col gap-2 pt-2">
+              <Input
+                value={searchDraft}
+                onChange={(e) => setSearchDraft(e.target.value)}
+                placeholder={t('sessionSearchPlaceholder')}
+              />
+              <div className="flex gap-2">
+                <select
+                  className="h-9 flex-1 rounded-md border border-input bg-background px-3 text-sm"
+                  value={channelFilter}
+                  onChange={(e) => setChannelFilter(e.target.value)}
+                >
+                  <option value="">{t('sessionChannelAll')}</option>
+                  {channels.map((channel) => (
+                    <option key={channel} value={channel}>
+                      {channel}
+                    </option>
+                  ))}
+                </select>
+                <Button
+                  variant={showArchived ? 'secondary' : 'outline'}
+                  size="sm"
+                  className="h-9"
+                  onClick={() => {
+                    setShowArchived((current) => !current);
+                    setSelectedId('');
+                  }}
+                >
+                  <Archive className="h-3.5 w-3.5 mr-1.5" />
+                  {t('sessionShowArchived')}
+                </Button>
+              </div>
+            </div>
           </CardHeader>
           <CardContent className="flex-1 min-h-0 p-0">
             <ScrollArea className="h-full">
@@ -166,7 +247,7 @@ export default function SessionsPage() {
                 )}
 
                 {sortedSessions.map((item) => {
-                  const displaySummary = item.summary?.trim() || item.id;
+                  const displaySummary = item.title?.trim() || item.summary?.trim() || item.id;
                   const isActive = item.id === selectedId;
 
                   return (
@@ -181,8 +262,15 @@ export default function SessionsPage() {
                           : 'hover:bg-muted/40 border-border',
                       )}
                     >
-                      <div className="text-xs text-muted-foreground truncate mb-1" title={item.id}>
-                        {item.id}
+                      <div className="flex items-center gap-2 mb-1">
+                        {item.channel && (
+                          <span className="shrink-0 rounded bg-muted px-1.5 py-0.5 text-[10px] font-medium uppercase text-muted-foreground">
+                            {item.channel}
+                          </span>
+                        )}
+                        <span className="text-xs text-muted-foreground truncate" title={item.id}>
+                          {item.id}
+                        </span>
                       </div>
                       <div className="text-sm font-medium truncate mb-1" title={displaySummary}>
                         {displaySummary}
@@ -273,6 +361,46 @@ export default function SessionsPage() {
                   </div>
                 </div>
 
+                <div className="flex flex-col gap-3 sm:flex-row sm:items-end">
+                  <div className="flex-1">
+                    <label className="text-xs text-muted-foreground mb-1 block">
+                      {t('sessionTitleLabel')}
+                    </label>
+                    <Input
+                      className="h-11"
+                      value={titleDraft}
+                      onChange={(e) => setTitleDraft(e.target.value)}
+                      placeholder={t('sessionTitlePlaceholder')}
+                    />
+                  </div>
+                  <div className="flex gap-2 sm:shrink-0">
+                    <Button
+                      variant="outline"
+                      onClick={handleSaveTitle}
+                      disabled={
+                        updateTitle.isPending || titleDraft === (detail.title || '')
+                      }
+                      className="h-11 flex-1 sm:flex-initial"
+                    >
+                      <Save className="h-4 w-4 mr-1.5" />
+                      {t('sessionTitleSave')}
+                    </Button>
+                    <Button
+                      variant="outline"
+                      onClick={handleRetitle}
+                      disabled={retitle.isPending || detail.message_count === 0}
+                      className="h-11 flex-1 sm:flex-initial"
+                    >
+                      {retitle.isPending ? (
+                        <Loader2 className="h-4 w-4 mr-1.5 animate-spin" />
+                      ) : (
+                        <Sparkles className="h-4 w-4 mr-1.5" />
+                      )}
+                      {t('sessionRetitle')}
+                    </Button>
+                  </div>
+                </div>
+
                 <div className="flex flex-col gap-3 sm:flex-row sm:items-end">
                   <div className="flex-1">
                     <label className="text-xs text-muted-foreground mb-1 block">
@@ -318,6 +446,37 @@ export default function SessionsPage() {
                       <Save className="h-4 w-4 mr-1.5" />
                       {t('sessionThreadSave')}
                     </Button>
+                    <Button
+                      variant="outline"
+                      onClick={() => archiveSession.mutate({ id: detail.id, archived: !detail.archived })}
+                      disabled={archiveSession.isPending}
+                      className="h-11 flex-1 sm:flex-initial"
+                    >
+                      {detail.archived ? (
+                        <ArchiveRestore className="h-4 w-4 mr-1.5" />
+                      ) : (
+                        <Archive className="h-4 w-4 mr-1.5" />
+                      )}
+                      {t(detail.archived ? 'sessionUnarchive' : 'sessionArchive')}
+                    </Button>
+                    <Button
+                      variant="outline"
+                      onClick={() => exportSession.mutate({ id: detail.id, format: 'markdown' })}
+                      disabled={exportSession.isPending}
+                      className="h-11 flex-1 sm:flex-initial"
+                    >
+                      <Download className="h-4 w-4 mr-1.5" />
+                      {t('sessionExportMarkdown')}
+                    </Button>
+                    <Button
+                      variant="outline"
+                      onClick={() => exportSession.mutate({ id: detail.id, format: 'jsonl' })}
+                      disabled={exportSession.isPending}
+                      className="h-11 flex-1 sm:flex-initial"
+                    >
+                      <Download className="h-4 w-4 mr-1.5" />
+                      {t('sessionExportJsonl')}
+                    </Button>
                     <Button
                       variant="destructive"
                       onClick={handleDeleteSession}
@@ -368,6 +527,57 @@ export default function SessionsPage() {
                   </p>
                 </div>
 
+                <div className="flex flex-col gap-2">
+                  <label className="text-xs text-muted-foreground mb-1 block">
+                    {t('sessionPinsLabel')}
+                  </label>
+                  {(detail.pins ?? []).length > 0 && (
+                    <ul className="space-y-1">
+                      {(detail.pins ?? []).map((pin, index) => (
+                        <li
+                          key={`${index}-${pin}`}
+                          className="flex items-start gap-2 rounded-md border px-3 py-2 text-sm"
+                        >
+                          <Pin className="h-3.5 w-3.5 mt-0.5 shrink-0 text-muted-foreground" />
+                          <span className="flex-1 break-words">{pin}</span>
+                          <button
+                            type="button"
+                            className="text-muted-foreground hover:text-destructive"
+                            onClick={() => removePin.mutate({ id: detail.id, index: index + 1 })}
+                            disabled={removePin.isPending}
+                            aria-label={t('sessionPinRemove')}
+                          >
+                            <X className="h-3.5 w-3.5" />
+                          </button>
+                        </li>
+                      ))}
+                    </ul>
+                  )}
+                  <div className="flex gap-2">
+                    <Input
+                      className="h-11"
+                      value={pinDraft}
+                      onChange={(e) => setPinDraft(e.target.value)}
+                      onKeyDown={(e) => {
+                        if (e.key === 'Enter') handleAddPin();
+                      }}
+                      placeholder={t('sessionPinPlaceholder')}
+                    />
+                    <Button
+                      variant="outline"
+                      onClick={handleAddPin}
+                      disabled={addPin.isPending || !pinDraft.trim()}
+                      className="h-11 shrink-0"
+                    >
+                      <Pin className="h-4 w-4 mr-1.5" />
+                      {t('sessionPinAdd')}
+                    </Button>
+                  </div>
+                  <p className="text-xs text-muted-foreground">
+                    {t('sessionPinsDescription')}
+                  </p>
+                </div>
+
                 <div className="flex-1 min-h-0 rounded-md border">
                   <div className="px-3 py-2 border-b text-sm font-medium">
                     {t('sessionMessagesTitle')}
@@ -2,16 +2,34 @@ import { useMemo, useState } from 'react';
 import Header from '@/components/layout/Header';
 import { Button } from '@/components/ui/button';
 import { Card } from '@/components/ui/card';
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog';
 import { Input } from '@/components/ui/input';
 import { ScrollArea } from '@/components/ui/scroll-area';
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui/select';
 import { Skeleton } from '@/components/ui/skeleton';
 import {
   useDisableSkill,
   useEnableSkill,
+  useInstallIndexedSkill,
   useSkillContent,
   useSkillItem,
   useSkills,
+  useSkillsIndex,
   type SkillItem,
+  type SkillsIndexEntry,
 } from '@/hooks/useSkills';
 import { t } from '@/lib/i18n';
 import { cn } from '@/lib/utils';
@@ -20,6 +38,8 @@ import {
   BadgeCheck,
   FileCode2,
   FileText,
+  Library,
+  Lock,
   Pin,
   Search,
   ShieldAlert,
@@ -242,6 +262,8 @@ export default function SkillsPage() {
                         </p>
                       </section>
 
+                      {selectedSkill && <SkillPermissionsSection skill={selectedSkill} />}
+
                       <section
                         className={cn(
                           'rounded-[24px] border p-4',
@@ -329,6 +351,8 @@ export default function SkillsPage() {
           </div>
         </>
       )}
+
+      <SkillsIndexCard />
     </div>
   );
 }
@@ -505,3 +529,180 @@ function SkillInfoCard({
     </div>
   );
 }
+
+function SkillPermissionsSection({ skill }: { skill: SkillItem }) {
+  let description = t('skillPermissionsReadOnly');
+  if (skill.trusted) {
+    description = t('skillPermissionsTrusted');
+  } else if (!skill.permissions_declared) {
+    description = t('skillPermissionsUndeclared');
+  }
+  return (
+    <section className="rounded-[24px] border border-border/70 bg-card p-4">
+      <div className="flex items-center gap-2 text-xs font-medium uppercase tracking-[0.18em] text-muted-foreground">
+        <Lock className="h-4 w-4" />
+        {t('skillPermissionsTitle')}
+      </div>
+      {!skill.trusted && skill.permissions.length > 0 ? (
+        <PermissionChips permissions={skill.permissions} />
+      ) : (
+        <p className="mt-2 text-sm leading-6 text-foreground">{description}</p>
+      )}
+    </section>
+  );
+}
+
+function PermissionChips({ permissions }: { permissions: string[] }) {
+  return (
+    <div className="mt-2 flex flex-wrap gap-2">
+      {permissions.map((permission) => (
+        <span
+          key={permission}
+          className="rounded-full border border-amber-200 bg-amber-50 px-2.5 py-1 text-xs font-medium text-amber-800"
+        >
+          {permissionLabel(permission)}
+        </span>
+      ))}
+    </div>
+  );
+}
+
+function permissionLabel(permission: string): string {
+  switch (permission) {
+    case 'network':
+      return t('skillPermissionNetwork');
+    case 'exec':
+      return t('skillPermissionExec');
+    case 'file_write':
+      return t('skillPermissionFileWrite');
+    default:
+      return permission.startsWith('tool:') ? t('skillPermissionTool', permission.slice(5)) : permission;
+  }
+}
+
+function SkillsIndexCard() {
+  const { data } = useSkillsIndex();
+  const [pending, setPending] = useState<SkillsIndexEntry | null>(null);
+
+  if (!data?.configured) {
+    return null;
+  }
+
+  return (
+    <Card className="rounded-[28px] border-border/70 bg-card/92 p-5 shadow-sm">
+      <div className="flex items-center gap-2">
+        <Library className="h-4 w-4 text-muted-foreground" />
+        <h3 className="text-sm font-semibold text-foreground">{t('skillsIndexTitle')}</h3>
+      </div>
+      <p className="mt-1 text-xs text-muted-foreground">
+        {data.index_only ? t('skillsIndexOnlyDescription') : t('skillsIndexDescription')}
+      </p>
+      <div className="mt-4 grid grid-cols-1 gap-3 lg:grid-cols-2">
+        {data.skills.map((entry) => (
+          <div key={entry.id} className="rounded-[22px] border border-border/70 bg-card p-4">
+            <div className="flex items-start justify-between gap-3">
+              <div className="min-w-0">
+                <h4 className="truncate text-sm font-semibold text-foreground">{entry.name || entry.id}</h4>
+                <p className="mt-1 truncate text-xs text-muted-foreground">{entry.repo}</p>
+              </div>
+              <Button size="sm" variant="outline" className="rounded-xl" onClick={() => setPending(entry)}>
+                {entry.installed_version ? t('skillsIndexChangeVersion') : t('skillsIndexInstall')}
+              </Button>
+            </div>
+            <p className="mt-3 line-clamp-2 text-sm leading-6 text-muted-foreground">
+              {entry.description || t('marketplaceNoDescription')}
+            </p>
+            <div className="mt-3 flex flex-wrap gap-2 text-[11px]">
+              <span className="rounded-full bg-muted px-2 py-0.5 text-muted-foreground">
+                {t('skillsIndexLatest', entry.versions[0]?.version ?? '-')}
+              </span>
+              {entry.installed_version && (
+                <span className="rounded-full bg-emerald-100 px-2 py-0.5 text-emerald-700">
+                  {t('skillsIndexInstalledVersion', entry.installed_version)}
+                </span>
+              )}
+              {entry.pinned && (
+                <span className="rounded-full bg-sky-100 px-2 py-0.5 text-sky-700">{t('skillsIndexPinned')}</span>
+              )}
+            </div>
+          </div>
+        ))}
+      </div>
+      <SkillInstallDialog entry={pending} onClose={() => setPending(null)} />
+    </Card>
+  );
+}
+
+function SkillInstallDialog({ entry, onClose }: { entry: SkillsIndexEntry | null; onClose: () => void }) {
+  const installSkill = useInstallIndexedSkill();
+  const [version, setVersion] = useState('');
+  const selected = entry?.versions.find((v) => v.version === version) ?? entry?.versions[0];
+
+  const handleOpenChange = (open: boolean) => {
+    if (!open) {
+      setVersion('');
+      onClose();
+    }
+  };
+
+  const handleConfirm = () => {
+    if (!entry || !selected) return;
+    installSkill.mutate(
+      { id: entry.id, version: selected.version },
+      { onSuccess: () => handleOpenChange(false) },
+    );
+  };
+
+  return (
+    <Dialog open={entry !== null} onOpenChange={handleOpenChange}>
+      <DialogContent className="sm:max-w-lg">
+        <DialogHeader>
+          <DialogTitle>{t('skillsIndexConfirmTitle', entry?.name || entry?.id || '')}</DialogTitle>
+          <DialogDescription>{entry?.repo}</DialogDescription>
+        </DialogHeader>
+        {entry && (
+          <div className="space-y-4">
+            <Select value={selected?.version ?? ''} onValueChange={setVersion}>
+              <SelectTrigger className="h-11 rounded-xl bg-background">
+                <SelectValue />
+              </SelectTrigger>
+              <SelectContent>
+                {entry.versions.map((v) => (
+                  <SelectItem key={v.version} value={v.version}>
+                    {v.version}
+                  </SelectItem>
+                ))}
+              </SelectContent>
+            </Select>
+            <div>
+              <div className="text-xs font-medium uppercase tracking-[0.18em] text-muted-foreground">
+                {t('skillsIndexChangelog')}
+              </div>
+              <p className="mt-2 whitespace-pre-wrap text-sm leading-6 text-foreground">
+                {selected?.changelog || t('skillsIndexNoChangelog')}
+              </p>
+            </div>
+            <div>
+              <div className="text-xs font-medium uppercase tracking-[0.18em] text-muted-foreground">
+                {t('skillPermissionsTitle')}
+              </div>
+              {entry.permissions.length > 0 ? (
+                <PermissionChips permissions={entry.permissions} />
+              ) : (
+                <p className="mt-2 text-sm leading-6 text-foreground">{t('skillPermissionsReadOnly')}</p>
+              )}
+            </div>
+          </div>
+        )}
+        <DialogFooter>
+          <Button variant="outline" onClick={() => handleOpenChange(false)}>
+            {t('cancel')}
+          </Button>
+          <Button disabled={installSkill.isPending || !selected} onClick={handleConfirm}>
+            {installSkill.isPending ? t('skillsIndexInstalling') : t('skillsIndexConfirmInstall')}
+          </Button>
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  );
+}
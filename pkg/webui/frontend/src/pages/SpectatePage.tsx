@@ -0,0 +1,33 @@
+import { useSearchParams } from 'react-router-dom';
+import { Eye } from 'lucide-react';
+import TerminalPanel from '@/components/tools/TerminalPanel';
+import { t } from '@/lib/i18n';
+
+/* Read-only view of a tool session opened from a spectator link. No login
+ * is needed; the token in the link authorizes the stream. */
+export default function SpectatePage() {
+  const [params] = useSearchParams();
+  const sessionId = (params.get('session') || '').trim();
+  const token = (params.get('token') || '').trim();
+
+  if (!sessionId || !token) {
+    return (
+      <div className="flex h-screen items-center justify-center text-sm text-muted-foreground">
+        {t('spectatorLinkInvalid')}
+      </div>
+    );
+  }
+
+  return (
+    <div className="flex h-screen flex-col bg-background">
+      <div className="flex items-center gap-2 border-b px-4 py-2 text-xs text-muted-foreground">
+        <Eye className="h-3.5 w-3.5" />
+        <span>{t('spectatorReadOnly')}</span>
+        <span className="font-mono">{sessionId.slice(0, 8)}</span>
+      </div>
+      <div className="min-h-0 flex-1 p-2">
+        <TerminalPanel sessionId={sessionId} active spectatorToken={token} />
+      </div>
+    </div>
+  );
+}
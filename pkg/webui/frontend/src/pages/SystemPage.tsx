@@ -1,6 +1,8 @@
 import { type FormEvent, useEffect, useMemo, useState } from "react";
 import { t } from "@/lib/i18n";
 import {
+  type BackupStatus,
+  type WorkspaceBackendStatus,
   SessionRuntimeState,
   StatusTask,
   useDaemonBootstrap,
@@ -15,6 +17,53 @@ import {
 } from "@/hooks/useConfig";
 import { useInstallQMD, useQMDStatus, useUpdateQMD } from "@/hooks/useQMD";
 import type { CronJob } from "@/hooks/useCron";
+import { type UsageGroupBy, useUsageReport } from "@/hooks/useUsage";
+import {
+  type FeedSubscription,
+  type FeedSubscriptionInput,
+  useCheckFeed,
+  useCreateFeed,
+  useDeleteFeed,
+  useFeeds,
+  useUpdateFeed,
+} from "@/hooks/useFeeds";
+import {
+  type AgentProfile,
+  useAgentProfiles,
+  useCreateAgentProfile,
+  useDeleteAgentProfile,
+  useUpdateAgentProfile,
+} from "@/hooks/useAgentProfiles";
+import {
+  type MCPServer,
+  type MCPServerConfig,
+  useCreateMCPServer,
+  useDeleteMCPServer,
+  useMCPServers,
+  useReconnectMCPServer,
+  useUpdateMCPServer,
+} from "@/hooks/useMCPServers";
+import {
+  type PluginStatus,
+  useInstallWasmPlugin,
+  usePlugins,
+  useUninstallWasmPlugin,
+} from "@/hooks/usePlugins";
+import {
+  type NotifyRule,
+  type NotifyRuleInput,
+  useCreateNotifyRule,
+  useDeleteNotifyRule,
+  useNotifyEventTypes,
+  useNotifyRules,
+  useUpdateNotifyRule,
+} from "@/hooks/useNotifyRules";
+import {
+  type ApiKeyInput,
+  useApiKeys,
+  useCreateApiKey,
+  useRevokeApiKey,
+} from "@/hooks/useApiKeys";
 import {
   type LicenseStatus,
   type UserRecord,
@@ -40,6 +89,7 @@ import {
 } from "@/components/ui/dialog";
 import { Input } from "@/components/ui/input";
 import { Textarea } from "@/components/ui/textarea";
+import { Switch } from "@/components/ui/switch";
 import { cn } from "@/lib/utils";
 import {
   AlertCircle,
@@ -47,11 +97,13 @@ import {
   Copy,
   DatabaseZap,
   Edit3,
+  Play,
   KeyRound,
   Plus,
   RefreshCw,
   Save,
   Trash2,
+  Upload,
   Users,
 } from "lucide-react";
 import { toast } from '@/lib/notify';
@@ -235,10 +287,20 @@ export default function SystemPage() {
                   label={t("systemRuntimeDatabase")}
                   value={status?.runtime_db_path || "-"}
                 />
+                <StatusMetric
+                  label={t("systemBackupLast")}
+                  value={formatBackupStatus(status?.backup)}
+                />
                 <StatusMetric
                   label={t("agentsWorkspace")}
                   value={status?.workspace_path || "-"}
                 />
+                {status?.workspace_backend ? (
+                  <StatusMetric
+                    label={t("systemWorkspaceBackend")}
+                    value={formatWorkspaceBackendStatus(status.workspace_backend)}
+                  />
+                ) : null}
               </div>
               {status?.workspace_contract ? (
                 <div className="mt-4 rounded-2xl border border-border/70 bg-muted/35 p-4">
@@ -285,6 +347,20 @@ export default function SystemPage() {
 
           <UserManagementCard />
 
+          <ApiKeysCard />
+
+          <UsageCard />
+
+          <FeedsCard />
+
+          <NotifyRulesCard />
+
+          <AgentProfilesCard />
+
+          <MCPServersCard />
+
+          <PluginsCard />
+
           <Card className="rounded-[24px] border-border/70 bg-card/92 p-5 shadow-sm">
             <div>
               <div className="text-xs font-medium uppercase tracking-[0.18em] text-muted-foreground">
@@ -1235,12 +1311,1167 @@ export default function SystemPage() {
   );
 }
 
+function UsageCard() {
+  const [groupBy, setGroupBy] = useState<UsageGroupBy>("day");
+  const { data: report, isLoading, error } = useUsageReport(groupBy);
+  const rows = report?.rows ?? [];
+  const maxTokens = Math.max(1, ...rows.map((row) => row.total_tokens));
+  const promptTokens = report?.total.prompt_tokens ?? 0;
+  const cacheHitRate = promptTokens > 0 ? ((report?.total.cache_read_tokens ?? 0) / promptTokens) * 100 : 0;
+
+  return (
+    <Card className="rounded-[24px] border-border/70 bg-card/92 p-5 shadow-sm">
+      <div className="flex flex-col gap-3 sm:flex-row sm:items-start sm:justify-between">
+        <div>
+          <div className="text-xs font-medium uppercase tracking-[0.18em] text-muted-foreground">
+            {t("systemUsageTitle")}
+          </div>
+          <h3 className="mt-2 text-lg font-semibold text-foreground">
+            {t("systemUsageHeadline")}
+          </h3>
+        </div>
+        <div className="flex gap-2">
+          {(["day", "week"] as const).map((value) => (
+            <Button
+              key={value}
+              size="sm"
+              variant={groupBy === value ? "default" : "outline"}
+              onClick={() => setGroupBy(value)}
+            >
+              {value === "day" ? t("systemUsageDaily") : t("systemUsageWeekly")}
+            </Button>
+          ))}
+        </div>
+      </div>
+      {isLoading ? (
+        <div className="mt-4 text-sm text-muted-foreground animate-pulse">
+          {t("systemLoading")}
+        </div>
+      ) : error ? (
+        <div className="mt-4 text-sm text-muted-foreground">{errorMessage(error)}</div>
+      ) : (
+        <>
+          <div className="mt-4 grid gap-3 md:grid-cols-4">
+            <StatusMetric
+              label={t("systemUsageRequests")}
+              value={String(report?.total.requests ?? 0)}
+            />
+            <StatusMetric
+              label={t("systemUsageTokens")}
+              value={(report?.total.total_tokens ?? 0).toLocaleString()}
+            />
+            <StatusMetric
+              label={t("systemUsageCost")}
+              value={`$${(report?.total.cost ?? 0).toFixed(4)}`}
+            />
+            <StatusMetric
+              label={t("systemUsageCacheHit")}
+              value={`${cacheHitRate.toFixed(1)}%`}
+            />
+          </div>
+          {rows.length === 0 ? (
+            <div className="mt-4 text-sm text-muted-foreground">{t("systemUsageEmpty")}</div>
+          ) : (
+            <div className="mt-4 space-y-2">
+              {rows.map((row) => (
+                <div key={row.key} className="grid grid-cols-[96px_minmax(0,1fr)_auto] items-center gap-3 text-xs">
+                  <span className="font-mono text-muted-foreground">{row.key}</span>
+                  <div className="h-2 rounded-full bg-muted/60">
+                    <div
+                      className="h-2 rounded-full bg-[hsl(var(--brand-500))]"
+                      style={{ width: `${(row.total_tokens / maxTokens) * 100}%` }}
+                    />
+                  </div>
+                  <span className="text-right text-foreground">
+                    {row.total_tokens.toLocaleString()} · ${row.cost.toFixed(4)}
+                  </span>
+                </div>
+              ))}
+            </div>
+          )}
+        </>
+      )}
+    </Card>
+  );
+}
+
+const emptyFeedForm: FeedSubscriptionInput = {
+  name: "",
+  url: "",
+  interval_minutes: 60,
+  prompt: "",
+  channel_id: "",
+  session_id: "",
+  enabled: true,
+};
+
+const apiKeyScopes = ["read", "write", "gateway"];
+
+const emptyApiKeyForm: ApiKeyInput = { name: "", scopes: ["read"], expires_in_days: 90 };
+
+function ApiKeysCard() {
+  const { data: keys = [], isLoading, error } = useApiKeys();
+  const createKey = useCreateApiKey();
+  const revokeKey = useRevokeApiKey();
+  const [formOpen, setFormOpen] = useState(false);
+  const [form, setForm] = useState<ApiKeyInput>(emptyApiKeyForm);
+  const [secret, setSecret] = useState("");
+
+  const toggleScope = (scope: string, enabled: boolean) =>
+    setForm((current) => ({
+      ...current,
+      scopes: enabled
+        ? [...current.scopes.filter((item) => item !== scope), scope]
+        : current.scopes.filter((item) => item !== scope),
+    }));
+
+  const handleSubmit = (event: FormEvent) => {
+    event.preventDefault();
+    createKey.mutate(
+      { ...form, name: form.name.trim(), expires_in_days: form.expires_in_days || undefined },
+      {
+        onSuccess: (result) => {
+          setSecret(result.key);
+          setForm(emptyApiKeyForm);
+          setFormOpen(false);
+        },
+      },
+    );
+  };
+
+  async function copySecret() {
+    try {
+      await navigator.clipboard.writeText(secret);
+      toast.success(t("copied"));
+    } catch (err) {
+      toast.error(errorMessage(err));
+    }
+  }
+
+  return (
+    <Card className="rounded-[24px] border-border/70 bg-card/92 p-5 shadow-sm">
+      <div className="flex flex-col gap-3 sm:flex-row sm:items-start sm:justify-between">
+        <div>
+          <div className="flex items-center gap-2 text-xs font-medium uppercase tracking-[0.18em] text-muted-foreground">
+            <KeyRound className="h-4 w-4" />
+            {t("systemApiKeysTitle")}
+          </div>
+          <h3 className="mt-2 text-lg font-semibold text-foreground">
+            {t("systemApiKeysHeadline")}
+          </h3>
+        </div>
+        <Button size="sm" variant="outline" onClick={() => setFormOpen(true)}>
+          <Plus className="mr-1 h-4 w-4" />
+          {t("systemApiKeysAdd")}
+        </Button>
+      </div>
+
+      {secret ? (
+        <div className="mt-4 rounded-2xl border border-amber-500/30 bg-amber-500/10 p-3">
+          <div className="text-xs text-muted-foreground">{t("systemApiKeysSecretOnce")}</div>
+          <div className="mt-2 flex items-center gap-2">
+            <code className="min-w-0 flex-1 truncate font-mono text-xs text-foreground">{secret}</code>
+            <Button size="sm" variant="ghost" onClick={copySecret}>
+              <Copy className="h-4 w-4" />
+            </Button>
+            <Button size="sm" variant="ghost" onClick={() => setSecret("")}>
+              {t("close")}
+            </Button>
+          </div>
+        </div>
+      ) : null}
+
+      {formOpen ? (
+        <form className="mt-4 grid gap-3 md:grid-cols-2" onSubmit={handleSubmit}>
+          <Input
+            placeholder={t("systemApiKeysName")}
+            value={form.name}
+            onChange={(e) => setForm((current) => ({ ...current, name: e.target.value }))}
+            required
+          />
+          <Input
+            type="number"
+            min={0}
+            placeholder={t("systemApiKeysExpiresInDays")}
+            value={form.expires_in_days ? String(form.expires_in_days) : ""}
+            onChange={(e) =>
+              setForm((current) => ({ ...current, expires_in_days: Number(e.target.value) || 0 }))
+            }
+          />
+          <div className="flex flex-wrap gap-4 md:col-span-2">
+            {apiKeyScopes.map((scope) => (
+              <label key={scope} className="flex items-center gap-2 text-sm text-muted-foreground">
+                <Switch
+                  checked={form.scopes.includes(scope)}
+                  onCheckedChange={(value) => toggleScope(scope, value)}
+                />
+                <span className="font-mono">{scope}</span>
+              </label>
+            ))}
+          </div>
+          <div className="flex gap-2 md:col-span-2">
+            <Button type="submit" size="sm" disabled={createKey.isPending}>
+              <Save className="mr-1 h-4 w-4" />
+              {t("save")}
+            </Button>
+            <Button type="button" size="sm" variant="ghost" onClick={() => setFormOpen(false)}>
+              {t("cancel")}
+            </Button>
+          </div>
+        </form>
+      ) : null}
+
+      {isLoading ? (
+        <div className="mt-4 text-sm text-muted-foreground animate-pulse">
+          {t("systemLoading")}
+        </div>
+      ) : error ? (
+        <div className="mt-4 text-sm text-muted-foreground">{errorMessage(error)}</div>
+      ) : keys.length === 0 ? (
+        <div className="mt-4 text-sm text-muted-foreground">{t("systemApiKeysEmpty")}</div>
+      ) : (
+        <div className="mt-4 space-y-2">
+          {keys.map((key) => (
+            <div
+              key={key.id}
+              className="flex flex-col gap-2 rounded-2xl border border-border/70 p-3 sm:flex-row sm:items-center sm:justify-between"
+            >
+              <div className="min-w-0">
+                <div className="flex items-center gap-2 text-sm font-medium text-foreground">
+                  <span className="truncate">{key.name}</span>
+                  {key.revoked_at ? (
+                    <span className="text-xs text-muted-foreground">{t("systemApiKeysRevokedBadge")}</span>
+                  ) : null}
+                </div>
+                <div className="truncate font-mono text-xs text-muted-foreground">
+                  {key.prefix}… · {key.scopes.length > 0 ? key.scopes.join(", ") : t("systemApiKeysAllScopes")}
+                </div>
+                <div className="text-xs text-muted-foreground">
+                  {key.expires_at
+                    ? t("systemApiKeysExpires", new Date(key.expires_at).toLocaleString())
+                    : t("systemApiKeysNoExpiry")}
+                  {key.last_used_at
+                    ? ` · ${t("systemApiKeysLastUsed", new Date(key.last_used_at).toLocaleString())}`
+                    : ""}
+                </div>
+              </div>
+              {!key.revoked_at ? (
+                <Button
+                  size="sm"
+                  variant="ghost"
+                  title={t("systemApiKeysRevoke")}
+                  disabled={revokeKey.isPending}
+                  onClick={() => revokeKey.mutate(key.id)}
+                >
+                  <Trash2 className="h-4 w-4" />
+                </Button>
+              ) : null}
+            </div>
+          ))}
+        </div>
+      )}
+    </Card>
+  );
+}
+
+function FeedsCard() {
+  const { data: feeds = [], isLoading, error } = useFeeds();
+  const createFeed = useCreateFeed();
+  const updateFeed = useUpdateFeed();
+  const deleteFeed = useDeleteFeed();
+  const checkFeed = useCheckFeed();
+  const [editingId, setEditingId] = useState<string | null>(null);
+  const [formOpen, setFormOpen] = useState(false);
+  const [form, setForm] = useState<FeedSubscriptionInput>(emptyFeedForm);
+  const saving = createFeed.isPending || updateFeed.isPending;
+
+  const openCreate = () => {
+    setEditingId(null);
+    setForm(emptyFeedForm);
+    setFormOpen(true);
+  };
+
+  const openEdit = (feed: FeedSubscription) => {
+    setEditingId(feed.id);
+    setForm({
+      name: feed.name,
+      url: feed.url,
+      interval_minutes: feed.interval_minutes,
+      prompt: feed.prompt,
+      channel_id: feed.channel_id,
+      session_id: feed.session_id,
+      enabled: feed.enabled,
+    });
+    setFormOpen(true);
+  };
+
+  const handleSubmit = (event: FormEvent) => {
+    event.preventDefault();
+    const onSuccess = () => setFormOpen(false);
+    if (editingId) {
+      updateFeed.mutate({ id: editingId, input: form }, { onSuccess });
+    } else {
+      createFeed.mutate(form, { onSuccess });
+    }
+  };
+
+  const setField = <K extends keyof FeedSubscriptionInput>(key: K, value: FeedSubscriptionInput[K]) =>
+    setForm((current) => ({ ...current, [key]: value }));
+
+  return (
+    <Card className="rounded-[24px] border-border/70 bg-card/92 p-5 shadow-sm">
+      <div className="flex flex-col gap-3 sm:flex-row sm:items-start sm:justify-between">
+        <div>
+          <div className="text-xs font-medium uppercase tracking-[0.18em] text-muted-foreground">
+            {t("systemFeedsTitle")}
+          </div>
+          <h3 className="mt-2 text-lg font-semibold text-foreground">
+            {t("systemFeedsHeadline")}
+          </h3>
+        </div>
+        <Button size="sm" variant="outline" onClick={openCreate}>
+          <Plus className="mr-1 h-4 w-4" />
+          {t("systemFeedsAdd")}
+        </Button>
+      </div>
+
+      {formOpen ? (
+        <form className="mt-4 grid gap-3 md:grid-cols-2" onSubmit={handleSubmit}>
+          <Input
+            placeholder={t("systemFeedsName")}
+            value={form.name}
+            onChange={(e) => setField("name", e.target.value)}
+          />
+          <Input
+            placeholder="https://example.org/feed.xml"
+            value={form.url}
+            onChange={(e) => setField("url", e.target.value)}
+            required
+          />
+          <Input
+            placeholder={t("systemFeedsChannel")}
+            value={form.channel_id}
+            onChange={(e) => setField("channel_id", e.target.value)}
+            required
+          />
+          <Input
+            placeholder={t("systemFeedsSession")}
+            value={form.session_id}
+            onChange={(e) => setField("session_id", e.target.value)}
+            required
+          />
+          <Input
+            type="number"
+            min={5}
+            placeholder={t("systemFeedsInterval")}
+            value={String(form.interval_minutes)}
+            onChange={(e) => setField("interval_minutes", Number(e.target.value) || 0)}
+          />
+          <label className="flex items-center gap-2 text-sm text-muted-foreground">
+            <Switch checked={form.enabled} onCheckedChange={(value) => setField("enabled", value)} />
+            {t("enabled")}
+          </label>
+          <Textarea
+            className="md:col-span-2"
+            rows={3}
+            placeholder={t("systemFeedsPrompt")}
+            value={form.prompt}
+            onChange={(e) => setField("prompt", e.target.value)}
+          />
+          <div className="flex gap-2 md:col-span-2">
+            <Button type="submit" size="sm" disabled={saving}>
+              <Save className="mr-1 h-4 w-4" />
+              {t("save")}
+            </Button>
+            <Button type="button" size="sm" variant="ghost" onClick={() => setFormOpen(false)}>
+              {t("cancel")}
+            </Button>
+          </div>
+        </form>
+      ) : null}
+
+      {isLoading ? (
+        <div className="mt-4 text-sm text-muted-foreground animate-pulse">
+          {t("systemLoading")}
+        </div>
+      ) : error ? (
+        <div className="mt-4 text-sm text-muted-foreground">{errorMessage(error)}</div>
+      ) : feeds.length === 0 ? (
+        <div className="mt-4 text-sm text-muted-foreground">{t("systemFeedsEmpty")}</div>
+      ) : (
+        <div className="mt-4 space-y-2">
+          {feeds.map((feed) => (
+            <div
+              key={feed.id}
+              className="flex flex-col gap-2 rounded-2xl border border-border/70 p-3 sm:flex-row sm:items-center sm:justify-between"
+            >
+              <div className="min-w-0">
+                <div className="flex items-center gap-2 text-sm font-medium text-foreground">
+                  <span className="truncate">{feed.name}</span>
+                  {!feed.enabled ? (
+                    <span className="text-xs text-muted-foreground">{t("systemFeedsPaused")}</span>
+                  ) : null}
+                </div>
+                <div className="truncate font-mono text-xs text-muted-foreground">{feed.url}</div>
+                <div className="text-xs text-muted-foreground">
+                  {feed.session_id} · {t("systemFeedsEvery", feed.interval_minutes)}
+                  {feed.last_checked_at
+                    ? ` · ${t("systemFeedsLastChecked", new Date(feed.last_checked_at).toLocaleString())}`
+                    : ""}
+                </div>
+                {feed.last_error ? (
+                  <div className="flex items-center gap-1 text-xs text-destructive">
+                    <AlertCircle className="h-3 w-3" />
+                    <span className="truncate">{feed.last_error}</span>
+                  </div>
+                ) : null}
+              </div>
+              <div className="flex shrink-0 gap-1">
+                <Button
+                  size="sm"
+                  variant="ghost"
+                  title={t("systemFeedsCheckNow")}
+                  disabled={checkFeed.isPending}
+                  onClick={() => checkFeed.mutate(feed.id)}
+                >
+                  <Play className="h-4 w-4" />
+                </Button>
+                <Button size="sm" variant="ghost" title={t("edit")} onClick={() => openEdit(feed)}>
+                  <Edit3 className="h-4 w-4" />
+                </Button>
+                <Button
+                  size="sm"
+                  variant="ghost"
+                  title={t("delete")}
+                  disabled={deleteFeed.isPending}
+                  onClick={() => deleteFeed.mutate(feed.id)}
+                >
+                  <Trash2 className="h-4 w-4" />
+                </Button>
+              </div>
+            </div>
+          ))}
+        </div>
+      )}
+    </Card>
+  );
+}
+
+const emptyNotifyRuleForm: NotifyRuleInput = {
+  name: "",
+  event_types: [],
+  match: "",
+  channel_id: "",
+  session_id: "",
+  enabled: true,
+};
+
+function NotifyRulesCard() {
+  const { data: rules = [], isLoading, error } = useNotifyRules();
+  const { data: eventTypes = [] } = useNotifyEventTypes();
+  const createRule = useCreateNotifyRule();
+  const updateRule = useUpdateNotifyRule();
+  const deleteRule = useDeleteNotifyRule();
+  const [editingId, setEditingId] = useState<string | null>(null);
+  const [formOpen, setFormOpen] = useState(false);
+  const [form, setForm] = useState<NotifyRuleInput>(emptyNotifyRuleForm);
+  const saving = createRule.isPending || updateRule.isPending;
+
+  const openCreate = () => {
+    setEditingId(null);
+    setForm(emptyNotifyRuleForm);
+    setFormOpen(true);
+  };
+
+  const openEdit = (rule: NotifyRule) => {
+    setEditingId(rule.id);
+    setForm({
+      name: rule.name,
+      event_types: rule.event_types,
+      match: rule.match,
+      channel_id: rule.channel_id,
+      session_id: rule.session_id,
+      enabled: rule.enabled,
+    });
+    setFormOpen(true);
+  };
+
+  const toggleEventType = (eventType: string) =>
+    setForm((current) => ({
+      ...current,
+      event_types: current.event_types.includes(eventType)
+        ? current.event_types.filter((value) => value !== eventType)
+        : [...current.event_types, eventType],
+    }));
+
+  const handleSubmit = (event: FormEvent) => {
+    event.preventDefault();
+    const onSuccess = () => setFormOpen(false);
+    if (editingId) {
+      updateRule.mutate({ id: editingId, input: form }, { onSuccess });
+    } else {
+      createRule.mutate(form, { onSuccess });
+    }
+  };
+
+  const setField = <K extends keyof NotifyRuleInput>(key: K, value: NotifyRuleInput[K]) =>
+    setForm((current) => ({ ...current, [key]: value }));
+
+  return (
+    <Card className="rounded-[24px] border-border/70 bg-card/92 p-5 shadow-sm">
+      <div className="flex flex-col gap-3 sm:flex-row sm:items-start sm:justify-between">
+        <div>
+          <div className="text-xs font-medium uppercase tracking-[0.18em] text-muted-foreground">
+            {t("systemNotifyTitle")}
+          </div>
+          <h3 className="mt-2 text-lg font-semibold text-foreground">
+            {t("systemNotifyHeadline")}
+          </h3>
+        </div>
+        <Button size="sm" variant="outline" onClick={openCreate}>
+          <Plus className="mr-1 h-4 w-4" />
+          {t("systemNotifyAdd")}
+        </Button>
+      </div>
+
+      {formOpen ? (
+        <form className="mt-4 grid gap-3 md:grid-cols-2" onSubmit={handleSubmit}>
+          <div className="flex flex-wrap gap-2 md:col-span-2">
+            {[...eventTypes, "*"].map((eventType) => (
+              <Button
+                key={eventType}
+                type="button"
+                size="sm"
+                variant={form.event_types.includes(eventType) ? "default" : "outline"}
+                onClick={() => toggleEventType(eventType)}
+              >
+                {eventType === "*" ? t("systemNotifyAllEvents") : eventType}
+              </Button>
+            ))}
+          </div>
+          <Input
+            placeholder={t("systemNotifyName")}
+            value={form.name}
+            onChange={(e) => setField("name", e.target.value)}
+          />
+          <Input
+            placeholder={t("systemNotifyMatch")}
+            value={form.match}
+            onChange={(e) => setField("match", e.target.value)}
+          />
+          <Input
+            placeholder={t("systemFeedsChannel")}
+            value={form.channel_id}
+            onChange={(e) => setField("channel_id", e.target.value)}
+            required
+          />
+          <Input
+            placeholder={t("systemFeedsSession")}
+            value={form.session_id}
+            onChange={(e) => setField("session_id", e.target.value)}
+            required
+          />
+          <label className="flex items-center gap-2 text-sm text-muted-foreground">
+            <Switch checked={form.enabled} onCheckedChange={(value) => setField("enabled", value)} />
+            {t("enabled")}
+          </label>
+          <div className="flex gap-2 md:col-span-2">
+            <Button type="submit" size="sm" disabled={saving || form.event_types.length === 0}>
+              <Save className="mr-1 h-4 w-4" />
+              {t("save")}
+            </Button>
+            <Button type="button" size="sm" variant="ghost" onClick={() => setFormOpen(false)}>
+              {t("cancel")}
+            </Button>
+          </div>
+        </form>
+      ) : null}
+
+      {isLoading ? (
+        <div className="mt-4 text-sm text-muted-foreground animate-pulse">
+          {t("systemLoading")}
+        </div>
+      ) : error ? (
+        <div className="mt-4 text-sm text-muted-foreground">{errorMessage(error)}</div>
+      ) : rules.length === 0 ? (
+        <div className="mt-4 text-sm text-muted-foreground">{t("systemNotifyEmpty")}</div>
+      ) : (
+        <div className="mt-4 space-y-2">
+          {rules.map((rule) => (
+            <div
+              key={rule.id}
+              className="flex flex-col gap-2 rounded-2xl border border-border/70 p-3 sm:flex-row sm:items-center sm:justify-between"
+            >
+              <div className="min-w-0">
+                <div className="flex items-center gap-2 text-sm font-medium text-foreground">
+                  <span className="truncate">{rule.name}</span>
+                  {!rule.enabled ? (
+                    <span className="text-xs text-muted-foreground">{t("systemFeedsPaused")}</span>
+                  ) : null}
+                </div>
+                <div className="truncate font-mono text-xs text-muted-foreground">
+                  {rule.event_types.join(", ")}
+                  {rule.match ? ` · "${rule.match}"` : ""}
+                </div>
+                <div className="text-xs text-muted-foreground">
+                  {rule.channel_id} · {rule.session_id}
+                </div>
+              </div>
+              <div className="flex shrink-0 gap-1">
+                <Button size="sm" variant="ghost" title={t("edit")} onClick={() => openEdit(rule)}>
+                  <Edit3 className="h-4 w-4" />
+                </Button>
+                <Button
+                  size="sm"
+                  variant="ghost"
+                  title={t("delete")}
+                  disabled={deleteRule.isPending}
+                  onClick={() => deleteRule.mutate(rule.id)}
+                >
+                  <Trash2 className="h-4 w-4" />
+                </Button>
+              </div>
+            </div>
+          ))}
+        </div>
+      )}
+    </Card>
+  );
+}
+
+const emptyAgentProfileForm: AgentProfile = {
+  name: "",
+  description: "",
+  provider: "",
+  model: "",
+  tools: [],
+  system_prompt: "",
+  workspace: "",
+  channels: [],
+};
+
+function splitList(value: string): string[] {
+  return value
+    .split(",")
+    .map((item) => item.trim())
+    .filter(Boolean);
+}
+
+function AgentProfilesCard() {
+  const { data: profiles = [], isLoading, error } = useAgentProfiles();
+  const createProfile = useCreateAgentProfile();
+  const updateProfile = useUpdateAgentProfile();
+  const deleteProfile = useDeleteAgentProfile();
+  const [editingName, setEditingName] = useState<string | null>(null);
+  const [formOpen, setFormOpen] = useState(false);
+  const [form, setForm] = useState<AgentProfile>(emptyAgentProfileForm);
+  const [tools, setTools] = useState("");
+  const [channels, setChannels] = useState("");
+  const saving = createProfile.isPending || updateProfile.isPending;
+
+  const openForm = (profile: AgentProfile | null) => {
+    const value = profile ?? emptyAgentProfileForm;
+    setEditingName(profile?.name ?? null);
+    setForm(value);
+    setTools((value.tools ?? []).join(", "));
+    setChannels((value.channels ?? []).join(", "));
+    setFormOpen(true);
+  };
+
+  const handleSubmit = (event: FormEvent) => {
+    event.preventDefault();
+    const input = { ...form, tools: splitList(tools), channels: splitList(channels) };
+    const onSuccess = () => setFormOpen(false);
+    if (editingName) {
+      updateProfile.mutate({ name: editingName, input }, { onSuccess });
+    } else {
+      createProfile.mutate(input, { onSuccess });
+    }
+  };
+
+  const setField = <K extends keyof AgentProfile>(key: K, value: AgentProfile[K]) =>
+    setForm((current) => ({ ...current, [key]: value }));
+
+  return (
+    <Card className="rounded-[24px] border-border/70 bg-card/92 p-5 shadow-sm">
+      <div className="flex flex-col gap-3 sm:flex-row sm:items-start sm:justify-between">
+        <div>
+          <div className="text-xs font-medium uppercase tracking-[0.18em] text-muted-foreground">
+            {t("systemAgentProfilesTitle")}
+          </div>
+          <h3 className="mt-2 text-lg font-semibold text-foreground">
+            {t("systemAgentProfilesHeadline")}
+          </h3>
+        </div>
+        <Button size="sm" variant="outline" onClick={() => openForm(null)}>
+          <Plus className="mr-1 h-4 w-4" />
+          {t("systemAgentProfilesAdd")}
+        </Button>
+      </div>
+
+      {formOpen ? (
+        <form className="mt-4 grid gap-3 md:grid-cols-2" onSubmit={handleSubmit}>
+          <Input
+            placeholder={t("systemAgentProfilesName")}
+            value={form.name}
+            onChange={(e) => setField("name", e.target.value)}
+            required
+          />
+          <Input
+            placeholder={t("systemAgentProfilesDescription")}
+            value={form.description}
+            onChange={(e) => setField("description", e.target.value)}
+          />
+          <Input
+            placeholder={t("systemAgentProfilesProvider")}
+            value={form.provider}
+            onChange={(e) => setField("provider", e.target.value)}
+          />
+          <Input
+            placeholder={t("systemAgentProfilesModel")}
+            value={form.model}
+            onChange={(e) => setField("model", e.target.value)}
+          />
+          <Input
+            placeholder={t("systemAgentProfilesTools")}
+            value={tools}
+            onChange={(e) => setTools(e.target.value)}
+          />
+          <Input
+            placeholder={t("systemAgentProfilesChannels")}
+            value={channels}
+            onChange={(e) => setChannels(e.target.value)}
+          />
+          <Input
+            className="md:col-span-2"
+            placeholder={t("systemAgentProfilesWorkspace")}
+            value={form.workspace}
+            onChange={(e) => setField("workspace", e.target.value)}
+          />
+          <Textarea
+            className="md:col-span-2"
+            rows={4}
+            placeholder={t("systemAgentProfilesPrompt")}
+            value={form.system_prompt}
+            onChange={(e) => setField("system_prompt", e.target.value)}
+          />
+          <div className="flex gap-2 md:col-span-2">
+            <Button type="submit" size="sm" disabled={saving}>
+              <Save className="mr-1 h-4 w-4" />
+              {t("save")}
+            </Button>
+            <Button type="button" size="sm" variant="ghost" onClick={() => setFormOpen(false)}>
+              {t("cancel")}
+            </Button>
+          </div>
+        </form>
+      ) : null}
+
+      {isLoading ? (
+        <div className="mt-4 text-sm text-muted-foreground animate-pulse">
+          {t("systemLoading")}
+        </div>
+      ) : error ? (
+        <div className="mt-4 text-sm text-muted-foreground">{errorMessage(error)}</div>
+      ) : profiles.length === 0 ? (
+        <div className="mt-4 text-sm text-muted-foreground">{t("systemAgentProfilesEmpty")}</div>
+      ) : (
+        <div className="mt-4 space-y-2">
+          {profiles.map((profile) => (
+            <div
+              key={profile.name}
+              className="flex flex-col gap-2 rounded-2xl border border-border/70 p-3 sm:flex-row sm:items-center sm:justify-between"
+            >
+              <div className="min-w-0">
+                <div className="flex items-center gap-2 text-sm font-medium text-foreground">
+                  <span className="truncate">{profile.name}</span>
+                  {profile.description ? (
+                    <span className="truncate text-xs text-muted-foreground">{profile.description}</span>
+                  ) : null}
+                </div>
+                <div className="truncate font-mono text-xs text-muted-foreground">
+                  {[profile.provider, profile.model].filter(Boolean).join(" / ") || t("systemAgentProfilesDefaults")}
+                  {profile.tools?.length ? ` · ${profile.tools.join(", ")}` : ""}
+                </div>
+                {profile.channels?.length ? (
+                  <div className="text-xs text-muted-foreground">{profile.channels.join(", ")}</div>
+                ) : null}
+              </div>
+              <div className="flex shrink-0 gap-1">
+                <Button size="sm" variant="ghost" title={t("edit")} onClick={() => openForm(profile)}>
+                  <Edit3 className="h-4 w-4" />
+                </Button>
+                <Button
+                  size="sm"
+                  variant="ghost"
+                  title={t("delete")}
+                  disabled={deleteProfile.isPending}
+                  onClick={() => deleteProfile.mutate(profile.name)}
+                >
+                  <Trash2 className="h-4 w-4" />
+                </Button>
+              </div>
+            </div>
+          ))}
+        </div>
+      )}
+    </Card>
+  );
+}
+
+const emptyMCPServerForm: MCPServerConfig = {
+  name: "",
+  transport: "stdio",
+  command: "",
+  args: [],
+  work_dir: "",
+  endpoint: "",
+  timeout: "",
+};
+
+const mcpStateClassName: Record<string, string> = {
+  connected: "bg-emerald-500",
+  connecting: "bg-amber-400",
+  error: "bg-destructive",
+};
+
+function MCPServersCard() {
+  const { data: servers = [], isLoading, error } = useMCPServers();
+  const createServer = useCreateMCPServer();
+  const updateServer = useUpdateMCPServer();
+  const deleteServer = useDeleteMCPServer();
+  const reconnectServer = useReconnectMCPServer();
+  const [editingName, setEditingName] = useState<string | null>(null);
+  const [formOpen, setFormOpen] = useState(false);
+  const [form, setForm] = useState<MCPServerConfig>(emptyMCPServerForm);
+  const [args, setArgs] = useState("");
+  const [allowedTools, setAllowedTools] = useState("");
+  const [blockedTools, setBlockedTools] = useState("");
+  const saving = createServer.isPending || updateServer.isPending;
+  const remote = form.transport !== "stdio";
+
+  const openForm = (server: MCPServer | null) => {
+    const value = server?.config ?? emptyMCPServerForm;
+    setEditingName(server?.status.name ?? null);
+    setForm({ ...value, transport: value.transport || "stdio" });
+    setArgs((value.args ?? []).join(", "));
+    setAllowedTools((value.allowed_tools ?? []).join(", "));
+    setBlockedTools((value.blocked_tools ?? []).join(", "));
+    setFormOpen(true);
+  };
+
+  const handleSubmit = (event: FormEvent) => {
+    event.preventDefault();
+    const input = {
+      ...form,
+      args: splitList(args),
+      allowed_tools: splitList(allowedTools),
+      blocked_tools: splitList(blockedTools),
+    };
+    const onSuccess = () => setFormOpen(false);
+    if (editingName) {
+      updateServer.mutate({ name: editingName, input }, { onSuccess });
+    } else {
+      createServer.mutate(input, { onSuccess });
+    }
+  };
+
+  const setField = <K extends keyof MCPServerConfig>(key: K, value: MCPServerConfig[K]) =>
+    setForm((current) => ({ ...current, [key]: value }));
+
+  return (
+    <Card className="rounded-[24px] border-border/70 bg-card/92 p-5 shadow-sm">
+      <div className="flex flex-col gap-3 sm:flex-row sm:items-start sm:justify-between">
+        <div>
+          <div className="text-xs font-medium uppercase tracking-[0.18em] text-muted-foreground">
+            {t("systemMCPTitle")}
+          </div>
+          <h3 className="mt-2 text-lg font-semibold text-foreground">
+            {t("systemMCPHeadline")}
+          </h3>
+        </div>
+        <Button size="sm" variant="outline" onClick={() => openForm(null)}>
+          <Plus className="mr-1 h-4 w-4" />
+          {t("systemMCPAdd")}
+        </Button>
+      </div>
+
+      {formOpen ? (
+        <form className="mt-4 grid gap-3 md:grid-cols-2" onSubmit={handleSubmit}>
+          <Input
+            placeholder={t("systemMCPName")}
+            value={form.name}
+            onChange={(e) => setField("name", e.target.value)}
+            required
+          />
+          <div className="flex flex-wrap gap-2">
+            {["stdio", "http", "websocket"].map((transport) => (
+              <Button
+                key={transport}
+                type="button"
+                size="sm"
+                variant={form.transport === transport ? "default" : "outline"}
+                onClick={() => setField("transport", transport)}
+              >
+                {transport}
+              </Button>
+            ))}
+          </div>
+          {remote ? (
+            <Input
+              className="md:col-span-2"
+              placeholder={t("systemMCPEndpoint")}
+              value={form.endpoint}
+              onChange={(e) => setField("endpoint", e.target.value)}
+              required
+            />
+          ) : (
+            <>
+              <Input
+                placeholder={t("systemMCPCommand")}
+                value={form.command}
+                onChange={(e) => setField("command", e.target.value)}
+                required
+              />
+              <Input
+                placeholder={t("systemMCPArgs")}
+                value={args}
+                onChange={(e) => setArgs(e.target.value)}
+              />
+            </>
+          )}
+          <Input
+            placeholder={t("systemMCPAllowedTools")}
+            value={allowedTools}
+            onChange={(e) => setAllowedTools(e.target.value)}
+          />
+          <Input
+            placeholder={t("systemMCPBlockedTools")}
+            value={blockedTools}
+            onChange={(e) => setBlockedTools(e.target.value)}
+          />
+          <Input
+            placeholder={t("systemMCPTimeout")}
+            value={form.timeout}
+            onChange={(e) => setField("timeout", e.target.value)}
+          />
+          <div className="flex gap-2 md:col-span-2">
+            <Button type="submit" size="sm" disabled={saving}>
+              <Save className="mr-1 h-4 w-4" />
+              {t("save")}
+            </Button>
+            <Button type="button" size="sm" variant="ghost" onClick={() => setFormOpen(false)}>
+              {t("cancel")}
+            </Button>
+          </div>
+        </form>
+      ) : null}
+
+      {isLoading ? (
+        <div className="mt-4 text-sm text-muted-foreground animate-pulse">
+          {t("systemLoading")}
+        </div>
+      ) : error ? (
+        <div className="mt-4 text-sm text-muted-foreground">{errorMessage(error)}</div>
+      ) : servers.length === 0 ? (
+        <div className="mt-4 text-sm text-muted-foreground">{t("systemMCPEmpty")}</div>
+      ) : (
+        <div className="mt-4 space-y-2">
+          {servers.map((server) => (
+            <div
+              key={server.status.name}
+              className="flex flex-col gap-2 rounded-2xl border border-border/70 p-3 sm:flex-row sm:items-center sm:justify-between"
+            >
+              <div className="min-w-0">
+                <div className="flex items-center gap-2 text-sm font-medium text-foreground">
+                  <span
+                    className={cn("h-2 w-2 shrink-0 rounded-full", mcpStateClassName[server.status.state])}
+                    title={server.status.state}
+                  />
+                  <span className="truncate">{server.status.name}</span>
+                  <span className="text-xs text-muted-foreground">{server.status.transport}</span>
+                </div>
+                <div className="truncate font-mono text-xs text-muted-foreground">
+                  {server.config.endpoint || [server.config.command, ...(server.config.args ?? [])].join(" ")}
+                </div>
+                {server.status.state === "error" && server.status.last_error ? (
+                  <div className="truncate text-xs text-destructive">{server.status.last_error}</div>
+                ) : (
+                  <div className="truncate text-xs text-muted-foreground">
+                    {server.status.state === "connected"
+                      ? t("systemMCPTools", String(server.status.tools.length))
+                      : t("systemMCPConnecting")}
+                    {server.status.tools.length ? ` · ${server.status.tools.join(", ")}` : ""}
+                  </div>
+                )}
+                <div className="text-xs text-muted-foreground">
+                  {t("systemMCPLastChecked")} {formatTaskTimestamp(server.status.last_checked_at)}
+                </div>
+              </div>
+              <div className="flex shrink-0 gap-1">
+                <Button
+                  size="sm"
+                  variant="ghost"
+                  title={t("systemMCPReconnect")}
+                  disabled={reconnectServer.isPending}
+                  onClick={() => reconnectServer.mutate(server.status.name)}
+                >
+                  <RefreshCw className={cn("h-4 w-4", reconnectServer.isPending && "animate-spin")} />
+                </Button>
+                <Button size="sm" variant="ghost" title={t("edit")} onClick={() => openForm(server)}>
+                  <Edit3 className="h-4 w-4" />
+                </Button>
+                <Button
+                  size="sm"
+                  variant="ghost"
+                  title={t("delete")}
+                  disabled={deleteServer.isPending}
+                  onClick={() => deleteServer.mutate(server.status.name)}
+                >
+                  <Trash2 className="h-4 w-4" />
+                </Button>
+              </div>
+            </div>
+          ))}
+        </div>
+      )}
+    </Card>
+  );
+}
+
+function PluginsCard() {
+  const { data, isLoading, error } = usePlugins();
+  const installPlugin = useInstallWasmPlugin();
+  const uninstallPlugin = useUninstallWasmPlugin();
+  const [source, setSource] = useState("");
+  const plugins = data?.plugins ?? [];
+
+  const handleSubmit = (event: FormEvent) => {
+    event.preventDefault();
+    if (!source.trim()) return;
+    installPlugin.mutate(source.trim(), { onSuccess: () => setSource("") });
+  };
+
+  const pluginDetail = (plugin: PluginStatus) => {
+    const parts = [plugin.kind === "wasm" ? "WASM" : "gRPC"];
+    if (plugin.workspace) {
+      parts.push(t("systemPluginsWorkspace", t(pluginWorkspaceLabel[plugin.workspace])));
+    }
+    if (plugin.tools.length) {
+      parts.push(plugin.tools.join(", "));
+    }
+    return parts.join(" · ");
+  };
+
+  return (
+    <Card className="rounded-[24px] border-border/70 bg-card/92 p-5 shadow-sm">
+      <div className="flex flex-col gap-3 sm:flex-row sm:items-start sm:justify-between">
+        <div>
+          <div className="text-xs font-medium uppercase tracking-[0.18em] text-muted-foreground">
+            {t("systemPluginsTitle")}
+          </div>
+          <h3 className="mt-2 text-lg font-semibold text-foreground">
+            {t("systemPluginsHeadline")}
+          </h3>
+          {data?.plugins_dir ? (
+            <div className="mt-1 font-mono text-xs text-muted-foreground">{data.plugins_dir}</div>
+          ) : null}
+        </div>
+        <Button size="sm" variant="outline" asChild>
+          <label className="cursor-pointer">
+            <Upload className="mr-1 h-4 w-4" />
+            {t("systemPluginsUpload")}
+            <input
+              type="file"
+              accept=".zip,application/zip"
+              className="hidden"
+              onChange={(e) => {
+                const file = e.target.files?.[0];
+                if (file) installPlugin.mutate(file);
+                e.target.value = "";
+              }}
+            />
+          </label>
+        </Button>
+      </div>
+
+      <form className="mt-4 flex gap-2" onSubmit={handleSubmit}>
+        <Input
+          placeholder={t("systemPluginsSource")}
+          value={source}
+          onChange={(e) => setSource(e.target.value)}
+        />
+        <Button type="submit" size="sm" disabled={installPlugin.isPending || !source.trim()}>
+          <Plus className="mr-1 h-4 w-4" />
+          {t("systemPluginsInstall")}
+        </Button>
+      </form>
+
+      {isLoading ? (
+        <div className="mt-4 text-sm text-muted-foreground animate-pulse">
+          {t("systemLoading")}
+        </div>
+      ) : error ? (
+        <div className="mt-4 text-sm text-muted-foreground">{errorMessage(error)}</div>
+      ) : plugins.length === 0 ? (
+        <div className="mt-4 text-sm text-muted-foreground">{t("systemPluginsEmpty")}</div>
+      ) : (
+        <div className="mt-4 space-y-2">
+          {plugins.map((plugin) => (
+            <div
+              key={`${plugin.kind}:${plugin.name}`}
+              className="flex flex-col gap-2 rounded-2xl border border-border/70 p-3 sm:flex-row sm:items-center sm:justify-between"
+            >
+              <div className="min-w-0">
+                <div className="flex items-center gap-2 text-sm font-medium text-foreground">
+                  <span
+                    className={cn(
+                      "h-2 w-2 shrink-0 rounded-full",
+                      plugin.running ? "bg-emerald-500" : plugin.last_error ? "bg-destructive" : "bg-muted-foreground",
+                    )}
+                    title={plugin.running ? t("systemPluginsRunning") : t("systemPluginsStopped")}
+                  />
+                  <span className="truncate">{plugin.name}</span>
+                </div>
+                <div className="truncate text-xs text-muted-foreground">{pluginDetail(plugin)}</div>
+                {plugin.last_error ? (
+                  <div className="truncate text-xs text-destructive">{plugin.last_error}</div>
+                ) : null}
+              </div>
+              {plugin.kind === "wasm" ? (
+                <div className="flex shrink-0 gap-1">
+                  <Button
+                    size="sm"
+                    variant="ghost"
+                    title={t("systemPluginsUninstall")}
+                    disabled={uninstallPlugin.isPending}
+                    onClick={() => uninstallPlugin.mutate(plugin.name)}
+                  >
+                    <Trash2 className="h-4 w-4" />
+                  </Button>
+                </div>
+              ) : null}
+            </div>
+          ))}
+        </div>
+      )}
+    </Card>
+  );
+}
+
+const pluginWorkspaceLabel: Record<NonNullable<PluginStatus["workspace"]>, string> = {
+  none: "systemPluginsWorkspaceNone",
+  read_only: "systemPluginsWorkspaceReadOnly",
+  read_write: "systemPluginsWorkspaceReadWrite",
+};
+
 type UserFormState = {
   username: string;
   nickname: string;
   password: string;
   role: string;
   enabled: boolean;
+  tenantSlug: string;
 };
 
 const emptyUserForm: UserFormState = {
@@ -1249,6 +2480,7 @@ const emptyUserForm: UserFormState = {
   password: "",
   role: "member",
   enabled: true,
+  tenantSlug: "",
 };
 
 function UserManagementCard() {
@@ -1283,6 +2515,7 @@ function UserManagementCard() {
       password: "",
       role: normalizeUserRoleForForm(user.role),
       enabled: user.enabled,
+      tenantSlug: user.tenant_slug || "",
     });
     setFormOpen(true);
   }
@@ -1306,6 +2539,7 @@ function UserManagementCard() {
         password: form.password,
         role: form.role,
         enabled: form.enabled,
+        tenant_slug: form.tenantSlug.trim(),
       };
       if (editingUser) {
         await updateUser.mutateAsync({ id: editingUser.id, input });
@@ -1479,6 +2713,15 @@ function UserManagementCard() {
                   onChange={(event) => setForm((current) => ({ ...current, nickname: event.target.value }))}
                 />
               </label>
+              <label className="text-sm text-muted-foreground">
+                {t("systemUsersTenant")}
+                <Input
+                  className="mt-1"
+                  value={form.tenantSlug}
+                  placeholder="default"
+                  onChange={(event) => setForm((current) => ({ ...current, tenantSlug: event.target.value }))}
+                />
+              </label>
               <label className="text-sm text-muted-foreground">
                 {editingUser ? t("systemUsersNewPassword") : t("password")}
                 <Input
@@ -1500,6 +2743,8 @@ function UserManagementCard() {
                   <option value="member">{t("systemUsersRoleMember")}</option>
                   <option value="owner">{t("systemUsersRoleOwner")}</option>
                   <option value="admin">{t("systemUsersRoleAdmin")}</option>
+                  <option value="operator">{t("systemUsersRoleOperator")}</option>
+                  <option value="viewer">{t("systemUsersRoleViewer")}</option>
                 </select>
               </label>
               <label className="flex items-center gap-3 rounded-2xl border border-border/70 bg-muted/35 px-4 py-3 text-sm text-foreground md:col-span-2">
@@ -1597,6 +2842,8 @@ function normalizeUserRoleForForm(role: string) {
   switch (role) {
     case "admin":
     case "owner":
+    case "operator":
+    case "viewer":
       return role;
     default:
       return "member";
@@ -1609,6 +2856,10 @@ function formatUserRole(role: string) {
       return t("systemUsersRoleAdmin");
     case "owner":
       return t("systemUsersRoleOwner");
+    case "operator":
+      return t("systemUsersRoleOperator");
+    case "viewer":
+      return t("systemUsersRoleViewer");
     default:
       return t("systemUsersRoleMember");
   }
@@ -1634,6 +2885,34 @@ function formatServiceStatus(status: string) {
   }
 }
 
+function formatBackupStatus(backup?: BackupStatus | null) {
+  if (!backup) {
+    return "-";
+  }
+  if (backup.running) {
+    return t("systemBackupRunning");
+  }
+  if (backup.last_error) {
+    return t("systemBackupFailed", backup.last_error);
+  }
+  if (backup.last_backup) {
+    const when = formatTaskTimestamp(backup.last_backup.created_at);
+    return `${when} · ${backup.location}`;
+  }
+  return backup.enabled ? t("systemBackupNone") : t("systemBackupDisabled");
+}
+
+function formatWorkspaceBackendStatus(backend: WorkspaceBackendStatus) {
+  if (backend.last_error) {
+    return t("systemBackupFailed", backend.last_error);
+  }
+  const synced = backend.last_push_at || backend.last_pull_at;
+  if (!synced) {
+    return t("systemWorkspaceBackendPending", backend.location);
+  }
+  return `${formatTaskTimestamp(synced)} · ${backend.location}`;
+}
+
 function StatusMetric({ label, value }: { label: string; value: string }) {
   return (
     <div className="rounded-2xl border border-border/70 bg-muted/35 p-4">
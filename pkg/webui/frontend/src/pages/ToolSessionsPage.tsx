@@ -16,6 +16,7 @@ import TerminalPanel from '@/components/tools/TerminalPanel';
 import ToolSessionDialog from '@/components/tools/ToolSessionDialog';
 import {
   Columns2,
+  Eye,
   Key,
   Loader2,
   PanelLeftClose,
@@ -337,6 +338,12 @@ export default function ToolSessionsPage() {
                           <div className="flex flex-wrap gap-1 text-[10px] uppercase tracking-[0.12em] text-muted-foreground">
                             {item.source === 'agent' && <span>{t('sourceAgent')}</span>}
                             {item.source === 'channel' && <span>{t('sourceChannel')}</span>}
+                            {item.spectator_enabled && (
+                              <span className="inline-flex items-center gap-1" title={t('spectatorViewers')}>
+                                <Eye className="h-3 w-3" />
+                                {item.viewers || 0}
+                              </span>
+                            )}
                             <span>{formatUpdatedAt(item.updated_at)}</span>
                           </div>
                         </button>
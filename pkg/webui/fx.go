@@ -7,10 +7,16 @@ import (
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
+	"nekobot/pkg/backup"
 	"nekobot/pkg/config"
+	"nekobot/pkg/feeds"
 	"nekobot/pkg/goaldriven"
 	"nekobot/pkg/inboundrouter"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/mcp"
+	"nekobot/pkg/notify"
+	"nekobot/pkg/plugins"
+	"nekobot/pkg/workspace"
 )
 
 // Module provides the WebUI server for fx dependency injection.
@@ -18,6 +24,12 @@ var Module = fx.Module("webui",
 	fx.Provide(NewServer),
 	fx.Invoke(bindGoalDrivenService),
 	fx.Invoke(bindInboundRouter),
+	fx.Invoke(bindFeeds),
+	fx.Invoke(bindNotifyRules),
+	fx.Invoke(bindMCP),
+	fx.Invoke(bindPlugins),
+	fx.Invoke(bindBackup),
+	fx.Invoke(bindWorkspaceSync),
 	fx.Invoke(registerLifecycle),
 )
 
@@ -52,6 +64,90 @@ func bindInboundRouter(deps bindInboundRouterDeps) {
 	deps.Server.inboundRouter = deps.Router
 }
 
+type bindFeedsDeps struct {
+	fx.In
+
+	Server *Server
+	Feeds  *feeds.Manager `optional:"true"`
+}
+
+func bindFeeds(deps bindFeedsDeps) {
+	if deps.Server == nil || deps.Feeds == nil {
+		return
+	}
+	deps.Server.feedMgr = deps.Feeds
+}
+
+type bindNotifyRulesDeps struct {
+	fx.In
+
+	Server *Server
+	Notify *notify.Manager `optional:"true"`
+}
+
+func bindNotifyRules(deps bindNotifyRulesDeps) {
+	if deps.Server == nil || deps.Notify == nil {
+		return
+	}
+	deps.Server.notifyMgr = deps.Notify
+}
+
+type bindMCPDeps struct {
+	fx.In
+
+	Server *Server
+	MCP    *mcp.Manager `optional:"true"`
+}
+
+func bindMCP(deps bindMCPDeps) {
+	if deps.Server == nil || deps.MCP == nil {
+		return
+	}
+	deps.Server.mcpMgr = deps.MCP
+}
+
+type bindPluginsDeps struct {
+	fx.In
+
+	Server  *Server
+	Plugins *plugins.Manager `optional:"true"`
+}
+
+func bindPlugins(deps bindPluginsDeps) {
+	if deps.Server == nil || deps.Plugins == nil {
+		return
+	}
+	deps.Server.pluginMgr = deps.Plugins
+}
+
+type bindBackupDeps struct {
+	fx.In
+
+	Server *Server
+	Backup *backup.Manager `optional:"true"`
+}
+
+func bindBackup(deps bindBackupDeps) {
+	if deps.Server == nil || deps.Backup == nil {
+		return
+	}
+	deps.Server.backupMgr = deps.Backup
+}
+
+type bindWorkspaceSyncDeps struct {
+	fx.In
+
+	Server *Server
+	Syncer *workspace.Syncer `optional:"true"`
+}
+
+func bindWorkspaceSync(deps bindWorkspaceSyncDeps) {
+	if deps.Server == nil || deps.Syncer == nil {
+		return
+	}
+	deps.Server.workspaceSync = deps.Syncer
+}
+
 func registerLifecycle(lc fx.Lifecycle, s *Server, cfg *config.Config, log *logger.Logger) {
 	if !cfg.WebUI.Enabled {
 		log.Info("WebUI disabled in config")
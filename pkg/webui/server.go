@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
@@ -20,6 +21,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -38,12 +40,16 @@ import (
 	daemonv1 "nekobot/gen/go/nekobot/daemon/v1"
 	"nekobot/pkg/accountbindings"
 	"nekobot/pkg/agent"
+	"nekobot/pkg/apikeys"
 	"nekobot/pkg/approval"
 	"nekobot/pkg/audit"
+	"nekobot/pkg/background"
+	"nekobot/pkg/backup"
 	"nekobot/pkg/bus"
 	"nekobot/pkg/channelaccounts"
 	"nekobot/pkg/channels"
 	channelwechat "nekobot/pkg/channels/wechat"
+	channelwhatsapp "nekobot/pkg/channels/whatsapp"
 	"nekobot/pkg/commands"
 	"nekobot/pkg/config"
 	"nekobot/pkg/cron"
@@ -51,6 +57,8 @@ import (
 	eventlog "nekobot/pkg/events"
 	"nekobot/pkg/execenv"
 	"nekobot/pkg/externalagent"
+	"nekobot/pkg/feedback"
+	"nekobot/pkg/feeds"
 	"nekobot/pkg/gateway"
 	"nekobot/pkg/goaldriven"
 	goalcriteria "nekobot/pkg/goaldriven/criteria"
@@ -59,21 +67,28 @@ import (
 	"nekobot/pkg/inboundrouter"
 	"nekobot/pkg/licensing"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/mcp"
 	memoryqmd "nekobot/pkg/memory/qmd"
 	"nekobot/pkg/message"
 	"nekobot/pkg/modelroute"
 	"nekobot/pkg/modelstore"
+	"nekobot/pkg/motd"
 	"nekobot/pkg/notificationroutes"
+	"nekobot/pkg/notify"
 	"nekobot/pkg/ownership"
 	"nekobot/pkg/permissionrules"
+	"nekobot/pkg/plugins"
 	"nekobot/pkg/policy"
 	"nekobot/pkg/process"
 	"nekobot/pkg/prompts"
 	"nekobot/pkg/providerregistry"
 	"nekobot/pkg/providers"
 	"nekobot/pkg/providerstore"
+	"nekobot/pkg/responsefilters"
+	"nekobot/pkg/richtext"
 	"nekobot/pkg/runtimeagents"
 	"nekobot/pkg/runtimetopology"
+	"nekobot/pkg/secrets"
 	"nekobot/pkg/servicecontrol"
 	"nekobot/pkg/session"
 	"nekobot/pkg/skills"
@@ -82,6 +97,8 @@ import (
 	"nekobot/pkg/tasks"
 	"nekobot/pkg/threads"
 	"nekobot/pkg/toolsessions"
+	"nekobot/pkg/transcript"
+	"nekobot/pkg/usage"
 	"nekobot/pkg/userprefs"
 	"nekobot/pkg/version"
 	"nekobot/pkg/watch"
@@ -116,9 +133,18 @@ type Server struct {
 	bindingMgr           *accountbindings.Manager
 	notificationMgr      *notificationroutes.Manager
 	notificationDispatch *notificationroutes.Dispatcher
+	usageMgr             *usage.Manager
+	feedbackMgr          *feedback.Manager
+	responseFilters      *responsefilters.Processor
 	inboundRouter        *inboundrouter.Router
 	topologySvc          *runtimetopology.Service
 	cronMgr              *cron.Manager
+	feedMgr              *feeds.Manager
+	notifyMgr            *notify.Manager
+	mcpMgr               *mcp.Manager
+	pluginMgr            *plugins.Manager
+	backupMgr            *backup.Manager
+	workspaceSync        *workspace.Syncer
 	skillsMgr            *skills.Manager
 	workspace            *workspace.Manager
 	entClient            *ent.Client
@@ -133,7 +159,15 @@ type Server struct {
 	chatEventMu          sync.RWMutex
 	chatEventSubs        map[string]map[chan chatEvent]struct{}
 	userMutationMu       sync.Mutex
+	toolSpawnMu          sync.Mutex
+	toolSpawnResults     map[string]toolSessionSpawnResult
+	toolSpawnOrder       []string
+	toolSpawnSubs        map[string]map[chan toolSessionSpawnResult]struct{}
+	chatUploadMu         sync.Mutex
+	chatUploads          map[string]chatUpload
+	chatFiles            map[string]chatUpload
 	watcher              *watch.Watcher
+	motd                 *motd.Checker
 	jwtFallbackSecret    string
 	daemonFallbackToken  string
 	webhookTestHandler   func(ctx context.Context, username, message string) (string, error)
@@ -142,10 +176,11 @@ type Server struct {
 }
 
 type chatEvent struct {
-	SessionID string `json:"session_id"`
-	Role      string `json:"role"`
-	Content   string `json:"content"`
-	Timestamp int64  `json:"timestamp"`
+	SessionID   string               `json:"session_id"`
+	Role        string               `json:"role"`
+	Content     string               `json:"content"`
+	Timestamp   int64                `json:"timestamp"`
+	Attachments []chatUploadResponse `json:"attachments,omitempty"` // Files sent by the agent, see /api/chat/files/:id
 }
 
 type serviceController interface {
@@ -159,6 +194,7 @@ type gatewayServiceController struct {
 	cfg        *config.Config
 	loader     *config.Loader
 	log        *logger.Logger
+	bus        bus.Bus
 	configPath string
 }
 
@@ -185,7 +221,7 @@ func (c *gatewayServiceController) Reload() error {
 	if c.cfg == nil || c.loader == nil || c.log == nil {
 		return fmt.Errorf("gateway reload is not available")
 	}
-	return gateway.NewController(c.cfg, c.loader, c.log).ReloadConfig()
+	return gateway.NewController(c.cfg, c.loader, c.log, c.bus).ReloadConfig()
 }
 
 func (c *gatewayServiceController) NekoClientdStatus() (map[string]interface{}, error) {
@@ -282,6 +318,7 @@ func NewServer(
 			cfg:    cfg,
 			loader: loader,
 			log:    log,
+			bus:    messageBus,
 			configPath: func() string {
 				if loader == nil {
 					return ""
@@ -289,12 +326,15 @@ func NewServer(
 				return loader.GetConfigPath()
 			}(),
 		},
-		port:      port,
-		startedAt: time.Now(),
+		motd:            motd.NewChecker(nil),
+		responseFilters: responsefilters.NewProcessor(cfg, log),
+		port:            port,
+		startedAt:       time.Now(),
 	}
 	if ag != nil {
 		s.taskStore = ag.TaskStore()
 	}
+	s.registerApprovalHandlers()
 
 	if entClient != nil {
 		runtimeMgr, err := runtimeagents.NewManager(cfg, log, entClient)
@@ -335,6 +375,23 @@ func NewServer(
 		} else {
 			s.notificationMgr = notificationMgr
 		}
+
+		usageMgr, err := usage.NewManager(cfg, entClient)
+		if err != nil {
+			log.Warn("Failed to initialize usage manager", zap.Error(err))
+		} else {
+			s.usageMgr = usageMgr
+		}
+
+		feedbackMgr, err := feedback.NewManager(entClient)
+		if err != nil {
+			log.Warn("Failed to initialize feedback manager", zap.Error(err))
+		} else {
+			s.feedbackMgr = feedbackMgr
+		}
+	}
+	if s.bus != nil {
+		s.bus.RegisterOutboundHandler(session.SourceWebUI, s.deliverWebUIOutbound)
 	}
 	if s.notificationMgr != nil && s.accountMgr != nil && s.bus != nil {
 		dispatcher := notificationroutes.NewDispatcher(log, s.notificationMgr, s.accountMgr, s.bus)
@@ -368,6 +425,7 @@ func (s *Server) setup() {
 	// Public routes
 	e.POST("/api/auth/login", s.handleLogin)
 	e.GET("/api/auth/init-status", s.handleInitStatus)
+	e.GET("/api/motd", s.handleGetMOTD)
 	e.POST("/api/auth/init", s.handleInitPassword)
 	e.POST("/api/auth/init/repair-workspace", s.handleInitRepairWorkspace)
 	e.POST("/api/daemon/register", s.handleRegisterDaemon)
@@ -379,12 +437,20 @@ func (s *Server) setup() {
 	// Chat WebSocket (auth handled inside via token query param)
 	e.GET("/api/chat/ws", s.handleChatWS)
 	e.GET("/api/chat/events", s.handleChatEvents)
+	e.GET("/api/chat/stream", s.handleChatStream)
+	e.GET("/api/channels/whatsapp/pairing/stream", s.handleWhatsAppPairingStream)
 	e.GET("/api/tool-sessions/ws", s.handleToolSessionWS)
+	e.GET("/api/approvals/ws", s.handleApprovalsWS)
 	e.POST("/api/tool-sessions/access-login", s.handleToolSessionAccessLogin)
 
 	// Protected API routes
 	api := e.Group("/api")
+	api.Use(s.authenticateAPIKey())
 	api.Use(echojwt.WithConfig(echojwt.Config{
+		// Requests authenticated by an API key carry no login token.
+		Skipper: func(c *echo.Context) bool {
+			return c.Get(apiKeyContextKey) != nil
+		},
 		SigningKey: nil, // Use KeyFunc instead for dynamic secret.
 		KeyFunc: func(t *jwt.Token) (interface{}, error) {
 			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -402,6 +468,7 @@ func (s *Server) setup() {
 	api.POST("/providers", s.handleCreateProvider)
 	api.POST("/providers/discover-models", s.handleDiscoverProviderModels)
 	api.POST("/providers/:name/test", s.handleTestProvider)
+	api.POST("/providers/:name/probe-capabilities", s.handleProbeProviderCapabilities)
 	api.POST("/providers/:name/clear-cooldown", s.handleClearProviderCooldown)
 	api.POST("/providers/apply-discovered-models", s.handleApplyDiscoveredProviderModels)
 	api.PUT("/providers/:name", s.handleUpdateProvider)
@@ -414,6 +481,9 @@ func (s *Server) setup() {
 	api.POST("/permission-rules", s.handleCreatePermissionRule)
 	api.PUT("/permission-rules/:id", s.handleUpdatePermissionRule)
 	api.DELETE("/permission-rules/:id", s.handleDeletePermissionRule)
+	api.GET("/api-keys", s.handleListAPIKeys)
+	api.POST("/api-keys", s.handleCreateAPIKey)
+	api.DELETE("/api-keys/:id", s.handleRevokeAPIKey)
 	api.GET("/policy/presets", s.handleGetPolicyPresets)
 	api.POST("/policy/evaluate", s.handleEvaluatePolicy)
 
@@ -421,6 +491,8 @@ func (s *Server) setup() {
 	api.GET("/channels", s.handleGetChannels)
 	api.PUT("/channels/:name", s.handleUpdateChannel)
 	api.POST("/channels/:name/test", s.handleTestChannel)
+	api.GET("/channels/:name/identity", s.handleGetChannelIdentity)
+	api.GET("/channels/whatsapp/status", s.handleGetWhatsAppStatus)
 	api.GET("/channels/wechat/binding", s.handleGetWechatBindingStatus)
 	api.POST("/channels/wechat/binding/start", s.handleStartWechatBinding)
 	api.POST("/channels/wechat/binding/poll", s.handlePollWechatBinding)
@@ -433,6 +505,8 @@ func (s *Server) setup() {
 	api.PUT("/config", s.handleSaveConfig)
 	api.GET("/config/export", s.handleExportConfig)
 	api.POST("/config/import", s.handleImportConfig)
+	api.GET("/config/document", s.handleGetConfigDocument)
+	api.POST("/config/apply", s.handleApplyConfigDocument)
 	api.GET("/memory/qmd/status", s.handleGetQMDStatus)
 	api.POST("/memory/qmd/install", s.handleInstallQMD)
 	api.POST("/memory/qmd/update", s.handleUpdateQMD)
@@ -453,6 +527,34 @@ func (s *Server) setup() {
 	api.PUT("/chat/prompts/session/:id", s.handlePutChatSessionPrompts)
 	api.DELETE("/chat/prompts/session/:id", s.handleDeleteChatSessionPrompts)
 	api.POST("/chat/session/:id/undo", s.handleUndoChatSession)
+	api.GET("/chat/session/:id/developer-mode", s.handleGetChatDeveloperMode)
+	api.PUT("/chat/session/:id/developer-mode", s.handleUpdateChatDeveloperMode)
+	api.GET("/chat/session/:id/persona", s.handleGetChatPersona)
+	api.PUT("/chat/session/:id/persona", s.handleUpdateChatPersona)
+	api.GET("/chat/sessions", s.handleListChatSessions)
+	api.GET("/chat/sessions/:id/messages", s.handleGetChatSessionMessages)
+	api.DELETE("/chat/sessions/:id", s.handleDeleteChatSession)
+	api.GET("/chat/link", s.handleGetChatLink)
+	api.POST("/chat/link", s.handleChatLink)
+	api.DELETE("/chat/link", s.handleUnlinkChat)
+	api.POST("/chat/attachments", s.handleUploadChatAttachment)
+	api.GET("/chat/files/:id", s.handleGetChatFile)
+
+	// Agent profile routes.
+	api.GET("/agents", s.handleListAgentProfiles)
+	api.POST("/agents", s.handleCreateAgentProfile)
+	api.PUT("/agents/:name", s.handleUpdateAgentProfile)
+	api.DELETE("/agents/:name", s.handleDeleteAgentProfile)
+
+	// MCP server routes.
+	api.GET("/mcp/servers", s.handleListMCPServers)
+	api.POST("/mcp/servers", s.handleCreateMCPServer)
+	api.PUT("/mcp/servers/:name", s.handleUpdateMCPServer)
+	api.DELETE("/mcp/servers/:name", s.handleDeleteMCPServer)
+	api.POST("/mcp/servers/:name/reconnect", s.handleReconnectMCPServer)
+	api.GET("/plugins", s.handleListPlugins)
+	api.POST("/plugins/wasm", s.handleInstallWasmPlugin)
+	api.DELETE("/plugins/wasm/:name", s.handleUninstallWasmPlugin)
 
 	// Multi-runtime foundation routes.
 	api.GET("/runtime-agents", s.handleListRuntimeAgents)
@@ -489,9 +591,19 @@ func (s *Server) setup() {
 	api.POST("/service/reload", s.handleServiceReload)
 	api.GET("/harness/watch", s.handleGetWatchStatus)
 	api.POST("/harness/watch", s.handleUpdateWatchStatus)
+	api.GET("/audit", s.handleListAuditEvents)
+	api.GET("/audit/export", s.handleExportAuditEvents)
 	api.GET("/harness/audit", s.handleGetHarnessAudit)
 	api.POST("/harness/audit/clear", s.handleClearHarnessAudit)
 
+	// Reports
+	api.GET("/reports/usage", s.handleGetUsageReport)
+	api.GET("/usage", s.handleGetUsageReport)
+
+	// Reply feedback
+	api.GET("/feedback", s.handleListFeedback)
+	api.POST("/feedback", s.handleRecordFeedback)
+
 	// Cron routes
 	api.GET("/cron/jobs", s.handleListCronJobs)
 	api.POST("/cron/jobs", s.handleCreateCronJob)
@@ -499,11 +611,37 @@ func (s *Server) setup() {
 	api.POST("/cron/jobs/:id/enable", s.handleEnableCronJob)
 	api.POST("/cron/jobs/:id/disable", s.handleDisableCronJob)
 	api.POST("/cron/jobs/:id/run", s.handleRunCronJob)
+	api.GET("/tasks", s.handleListScheduledTasks)
+	api.DELETE("/tasks/:id", s.handleCancelScheduledTask)
+	api.GET("/tasks/background", s.handleListBackgroundJobs)
+	api.DELETE("/tasks/background/:id", s.handleCancelBackgroundJob)
+
+	// Feed subscriptions
+	api.GET("/feeds", s.handleListFeeds)
+	api.POST("/feeds", s.handleCreateFeed)
+	api.PUT("/feeds/:id", s.handleUpdateFeed)
+	api.DELETE("/feeds/:id", s.handleDeleteFeed)
+	api.POST("/feeds/:id/check", s.handleCheckFeed)
+
+	// Notify rules
+	api.GET("/notify/event-types", s.handleListNotifyEventTypes)
+	api.GET("/notify/rules", s.handleListNotifyRules)
+	api.POST("/notify/rules", s.handleCreateNotifyRule)
+	api.PUT("/notify/rules/:id", s.handleUpdateNotifyRule)
+	api.DELETE("/notify/rules/:id", s.handleDeleteNotifyRule)
 
 	// Session routes
 	api.GET("/sessions", s.handleListSessions)
 	api.GET("/sessions/:id", s.handleGetSession)
+	api.GET("/sessions/:id/export", s.handleExportSession)
 	api.PUT("/sessions/:id/summary", s.handleUpdateSessionSummary)
+	api.PUT("/sessions/:id/title", s.handleUpdateSessionTitle)
+	api.PUT("/sessions/:id/archive", s.handleArchiveSession)
+	api.POST("/sessions/:id/retitle", s.handleRetitleSession)
+	api.GET("/sessions/:id/pins", s.handleListSessionPins)
+	api.POST("/sessions/:id/pins", s.handleAddSessionPin)
+	api.DELETE("/sessions/:id/pins", s.handleClearSessionPins)
+	api.DELETE("/sessions/:id/pins/:index", s.handleDeleteSessionPin)
 	api.PUT("/sessions/:id/runtime", s.handleUpdateSessionRuntime)
 	api.PUT("/sessions/:id/thread", s.handleUpdateSessionThread)
 	api.DELETE("/sessions/:id", s.handleDeleteSession)
@@ -518,6 +656,8 @@ func (s *Server) setup() {
 	api.GET("/skills/:id/content", s.handleGetSkillContent)
 	api.POST("/skills/:id/enable", s.handleEnableSkill)
 	api.POST("/skills/:id/disable", s.handleDisableSkill)
+	api.GET("/skills-index", s.handleListSkillsIndex)
+	api.POST("/skills-index/install", s.handleInstallIndexedSkill)
 	api.GET("/workspace/status", s.handleGetWorkspaceStatus)
 	api.POST("/workspace/repair", s.handleRepairWorkspace)
 	api.POST("/webhooks/test", s.handleTestWebhook)
@@ -544,10 +684,13 @@ func (s *Server) setup() {
 	api.PUT("/tool-sessions/:id", s.handleUpdateToolSession)
 	api.POST("/tool-sessions/:id/access", s.handleUpdateToolSessionAccess)
 	api.POST("/tool-sessions/:id/otp", s.handleGenerateToolSessionOTP)
+	api.POST("/tool-sessions/:id/spectator", s.handleUpdateToolSessionSpectator)
 	api.POST("/tool-sessions/:id/restart", s.handleRestartToolSession)
 	api.POST("/tool-sessions/:id/attach-token", s.handleCreateToolSessionAttachToken)
 	api.POST("/tool-sessions/consume-token", s.handleConsumeToolSessionAttachToken)
 	api.POST("/tool-sessions/spawn", s.handleSpawnToolSession)
+	api.POST("/tool-sessions/from-chat", s.handleSpawnToolSessionFromChat)
+	api.GET("/tool-sessions/spawn-requests/:id", s.handleGetToolSessionSpawnRequest)
 	api.GET("/tool-sessions/runtime-transports", s.handleListToolSessionRuntimeTransports)
 	api.POST("/external-agents/resolve-session", s.handleResolveExternalAgentSession)
 	api.GET("/external-agents/catalog", s.handleGetExternalAgentCatalog)
@@ -572,6 +715,7 @@ func (s *Server) setup() {
 	api.POST("/goal-runs/:id/confirm-manual", s.handleConfirmGoalRunManualCriterion)
 	api.GET("/tool-sessions/:id/process/status", s.handleToolSessionProcessStatus)
 	api.GET("/tool-sessions/:id/process/output", s.handleToolSessionProcessOutput)
+	api.GET("/tool-sessions/:id/stream", s.handleToolSessionStream)
 	api.POST("/tool-sessions/:id/process/input", s.handleToolSessionProcessInput)
 	api.POST("/tool-sessions/:id/process/kill", s.handleToolSessionProcessKill)
 	api.POST("/tool-sessions/cleanup-terminated", s.handleCleanupTerminatedToolSessions)
@@ -579,6 +723,8 @@ func (s *Server) setup() {
 
 	// Approval routes
 	api.GET("/approvals", s.handleGetApprovals)
+	api.GET("/approvals/history", s.handleGetApprovalHistory)
+	api.POST("/approvals/dry-run", s.handleApprovalDryRun)
 	api.POST("/approvals/:id/approve", s.handleApproveRequest)
 	api.POST("/approvals/:id/deny", s.handleDenyRequest)
 
@@ -662,6 +808,19 @@ func (s *Server) Stop(ctx context.Context) error {
 
 // --- Auth Handlers ---
 
+// handleGetMOTD returns the operator banner shown on the login page.
+func (s *Server) handleGetMOTD(c *echo.Context) error {
+	if s.config == nil {
+		return c.JSON(http.StatusOK, motd.Notice{})
+	}
+	checker := s.motd
+	if checker == nil {
+		checker = motd.NewChecker(nil)
+	}
+	notice := checker.Notice(c.Request().Context(), s.config.MOTD, version.GetVersion())
+	return c.JSON(http.StatusOK, notice)
+}
+
 func (s *Server) handleInitStatus(c *echo.Context) error {
 	cred, err := config.LoadAdminCredential(s.entClient)
 	if err != nil {
@@ -814,6 +973,11 @@ func (s *Server) handleLogin(c *echo.Context) error {
 	loginUser, err := config.AuthenticateUser(c.Request().Context(), s.entClient, body.Username, body.Password)
 	if err != nil {
 		if errors.Is(err, config.ErrAdminNotInitialized) {
+			s.recordAudit(c, audit.Event{
+				Action:  audit.ActionLogin,
+				Actor:   strings.TrimSpace(body.Username),
+				Summary: "invalid credentials",
+			})
 			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid credentials"})
 		}
 		s.logger.Error("Failed to authenticate login", zap.Error(err))
@@ -828,6 +992,13 @@ func (s *Server) handleLogin(c *echo.Context) error {
 	if err := config.RecordUserLogin(c.Request().Context(), s.entClient, profile.UserID); err != nil {
 		s.logger.Warn("Failed to record login timestamp", zap.Error(err))
 	}
+	s.recordAudit(c, audit.Event{
+		Action:   audit.ActionLogin,
+		Actor:    profile.Username,
+		ActorID:  profile.UserID,
+		TenantID: profile.TenantID,
+		Success:  true,
+	})
 
 	token, err := s.generateToken(profile)
 	if err != nil {
@@ -865,6 +1036,11 @@ func (s *Server) handleChangePassword(c *echo.Context) error {
 	loginUser, err := config.AuthenticateUser(c.Request().Context(), s.entClient, profile.Username, body.OldPassword)
 	if err != nil {
 		if errors.Is(err, config.ErrAdminNotInitialized) {
+			s.recordAudit(c, audit.Event{
+				Action:  audit.ActionPasswordChange,
+				Target:  profile.Username,
+				Summary: "old password is incorrect",
+			})
 			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "old password is incorrect"})
 		}
 		s.logger.Error("Failed to verify old password", zap.Error(err))
@@ -880,6 +1056,11 @@ func (s *Server) handleChangePassword(c *echo.Context) error {
 		s.logger.Error("Failed to update password", zap.Error(err))
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save credentials"})
 	}
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionPasswordChange,
+		Target:  profile.Username,
+		Success: true,
+	})
 
 	newSecret := config.GenerateJWTSecret()
 	if err := config.RotateJWTSecret(s.entClient, newSecret); err != nil {
@@ -1035,6 +1216,7 @@ func (s *Server) handleCreateUser(c *echo.Context) error {
 		Password string `json:"password"`
 		Role     string `json:"role"`
 		Enabled  *bool  `json:"enabled"`
+		Tenant   string `json:"tenant_slug"`
 	}
 	if err := c.Bind(&body); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
@@ -1067,6 +1249,7 @@ func (s *Server) handleCreateUser(c *echo.Context) error {
 		PasswordHash: hash,
 		Role:         body.Role,
 		Enabled:      enabled,
+		TenantSlug:   body.Tenant,
 	})
 	if err != nil {
 		if errors.Is(err, config.ErrUsernameAlreadyUsed) {
@@ -1088,6 +1271,7 @@ func (s *Server) handleUpdateUser(c *echo.Context) error {
 		Password string `json:"password"`
 		Role     string `json:"role"`
 		Enabled  bool   `json:"enabled"`
+		Tenant   string `json:"tenant_slug"`
 	}
 	if err := c.Bind(&body); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
@@ -1127,6 +1311,7 @@ func (s *Server) handleUpdateUser(c *echo.Context) error {
 		PasswordHash: hash,
 		Role:         body.Role,
 		Enabled:      body.Enabled,
+		TenantSlug:   body.Tenant,
 	})
 	if err != nil {
 		switch {
@@ -1196,7 +1381,7 @@ func (s *Server) userEnabled(ctx context.Context, id string) (bool, error) {
 // --- Provider Handlers ---
 
 func (s *Server) handleGetProviders(c *echo.Context) error {
-	loaded, err := s.providers.List(c.Request().Context())
+	loaded, err := s.providers.List(ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c)))
 	if err != nil {
 		s.logger.Error("Failed to load providers from database", zap.Error(err))
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load providers"})
@@ -1214,7 +1399,7 @@ func (s *Server) handleGetProviderRuntime(c *echo.Context) error {
 		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "agent not available"})
 	}
 
-	loaded, err := s.providers.List(c.Request().Context())
+	loaded, err := s.providers.List(ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c)))
 	if err != nil {
 		s.logger.Error("Failed to load providers from database", zap.Error(err))
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load providers"})
@@ -1250,6 +1435,10 @@ func (s *Server) handleGetProviderRuntime(c *echo.Context) error {
 			"last_failure_unix":          int64(0),
 			"cooldown_end_unix":          int64(0),
 			"disabled_until_unix":        int64(0),
+			"circuit_state":              string(snapshot.CircuitState),
+			"latency_ms":                 snapshot.Latency.Milliseconds(),
+			"latency_samples":            snapshot.LatencySamples,
+			"error_rate":                 snapshot.ErrorRate,
 		}
 		if !snapshot.LastFailure.IsZero() {
 			item["last_failure_unix"] = snapshot.LastFailure.Unix()
@@ -1279,7 +1468,7 @@ func (s *Server) handleTestProvider(c *echo.Context) error {
 	if s.providers == nil {
 		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "provider store unavailable"})
 	}
-	profile, err := s.providers.Get(c.Request().Context(), name)
+	profile, err := s.providers.Get(ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c)), name)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
@@ -1317,10 +1506,16 @@ func (s *Server) handleTestProvider(c *echo.Context) error {
 		Proxy:        profile.Proxy,
 		Model:        profile.DefaultTestModel,
 		Timeout:      profile.GetTimeout(),
+		APIVersion:   profile.APIVersion,
+		Deployments:  profile.Deployments,
 	})
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("init provider client failed: %v", err)})
 	}
+	client.Use(providers.ProfileMiddleware(profile.Middleware, providers.MiddlewareOptions{
+		Logger:       s.logger,
+		EstimateCost: usage.Estimator(s.config, profile.Name),
+	})...)
 	resp, err := client.Chat(c.Request().Context(), &providers.UnifiedRequest{
 		Model: profile.DefaultTestModel,
 		Messages: []providers.UnifiedMessage{{
@@ -1345,6 +1540,103 @@ func (s *Server) handleTestProvider(c *echo.Context) error {
 	})
 }
 
+// handleProbeProviderCapabilities probes which request features a provider's
+// model accepts and caches the result on the provider profile. The model
+// defaults to the provider's default_test_model.
+func (s *Server) handleProbeProviderCapabilities(c *echo.Context) error {
+	name := strings.TrimSpace(c.Param("name"))
+	if name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "provider name is required"})
+	}
+	if s.providers == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "provider store unavailable"})
+	}
+	var body struct {
+		Model string `json:"model"`
+	}
+	if c.Request().ContentLength > 0 {
+		if err := c.Bind(&body); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		}
+	}
+
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	profile, err := s.providers.Get(ctx, name)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	model := strings.TrimSpace(body.Model)
+	if model == "" {
+		model = strings.TrimSpace(profile.DefaultTestModel)
+	}
+	if model == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "model or default_test_model is required to probe this provider"})
+	}
+	apiFormat := strings.TrimSpace(profile.APIFormat)
+	if apiFormat != "" && apiFormat != "openai/chat_completions" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("capability probe for api_format %s is not supported yet", apiFormat)})
+	}
+	kind := strings.TrimSpace(profile.ProviderKind)
+	if kind == "" {
+		kind = name
+	}
+	client, err := providers.NewClient(kind, &providers.RelayInfo{
+		ProviderName: kind,
+		APIKey:       profile.APIKey,
+		APIBase:      profile.APIBase,
+		Proxy:        profile.Proxy,
+		Model:        model,
+		Timeout:      profile.GetTimeout(),
+		APIVersion:   profile.APIVersion,
+		Deployments:  profile.Deployments,
+	})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("init provider client failed: %v", err)})
+	}
+	client.Use(providers.ProfileMiddleware(profile.Middleware, providers.MiddlewareOptions{
+		Logger:       s.logger,
+		EstimateCost: usage.Estimator(s.config, profile.Name),
+	})...)
+
+	probed, err := providers.ProbeCapabilities(ctx, client, model)
+	if err != nil {
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": fmt.Sprintf("capability probe failed: %v", err)})
+	}
+	caps := &config.ProviderCapabilities{
+		Model:       model,
+		ToolCalling: probed.ToolCalling,
+		Streaming:   probed.Streaming,
+		ProbedAt:    time.Now().Unix(),
+	}
+	s.applyModelCatalogCapabilities(ctx, caps)
+
+	updated, err := s.providers.SetCapabilities(ctx, name, caps)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"provider":     updated.Name,
+		"capabilities": updated.Capabilities,
+	})
+}
+
+// applyModelCatalogCapabilities fills the features a live probe cannot test
+// from the model catalog entry, when one lists capabilities.
+func (s *Server) applyModelCatalogCapabilities(ctx context.Context, caps *config.ProviderCapabilities) {
+	manager, err := modelstore.NewManager(s.config, s.logger, s.entClient)
+	if err != nil {
+		return
+	}
+	item, err := manager.Get(ctx, caps.Model)
+	if err != nil || len(item.Capabilities) == 0 {
+		return
+	}
+	vision := slices.Contains(item.Capabilities, "vision")
+	jsonMode := slices.Contains(item.Capabilities, "json_mode")
+	caps.Vision = &vision
+	caps.JSONMode = &jsonMode
+}
+
 func (s *Server) testOpenAIResponsesProvider(ctx context.Context, kind string, profile *config.ProviderProfile) (string, error) {
 	base := strings.TrimRight(strings.TrimSpace(profile.APIBase), "/")
 	if base == "" {
@@ -1443,13 +1735,19 @@ func (s *Server) handleCreateProvider(c *echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
 
-	created, err := s.providers.Create(c.Request().Context(), profile)
+	created, err := s.providers.Create(ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c)), profile)
 	if err != nil {
 		return s.handleProviderStoreError(c, err)
 	}
 	if err := s.ensureRoutingProvidersValid(); err != nil {
 		s.logger.Warn("Failed to persist routing config after provider create", zap.Error(err))
 	}
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionProviderCreate,
+		Target:  created.Name,
+		Success: true,
+		Summary: audit.Diff(nil, providerProfileToMap(*created)),
+	})
 	return c.JSON(http.StatusCreated, map[string]interface{}{
 		"status":   "created",
 		"provider": s.providerProfileToView(*created),
@@ -1463,13 +1761,24 @@ func (s *Server) handleUpdateProvider(c *echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
 
-	updated, err := s.providers.Update(c.Request().Context(), name, profile)
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	var before map[string]interface{}
+	if previous, err := s.providers.Get(ctx, name); err == nil {
+		before = providerProfileToMap(*previous)
+	}
+	updated, err := s.providers.Update(ctx, name, profile)
 	if err != nil {
 		return s.handleProviderStoreError(c, err)
 	}
 	if err := s.ensureRoutingProvidersValid(); err != nil {
 		s.logger.Warn("Failed to persist routing config after provider update", zap.Error(err))
 	}
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionProviderUpdate,
+		Target:  name,
+		Success: true,
+		Summary: audit.Diff(before, providerProfileToMap(*updated)),
+	})
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"status":   "updated",
 		"provider": s.providerProfileToView(*updated),
@@ -1479,12 +1788,17 @@ func (s *Server) handleUpdateProvider(c *echo.Context) error {
 func (s *Server) handleDeleteProvider(c *echo.Context) error {
 	name := c.Param("name")
 
-	if err := s.providers.Delete(c.Request().Context(), name); err != nil {
+	if err := s.providers.Delete(ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c)), name); err != nil {
 		return s.handleProviderStoreError(c, err)
 	}
 	if err := s.ensureRoutingProvidersValid(); err != nil {
 		s.logger.Warn("Failed to persist routing config after provider delete", zap.Error(err))
 	}
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionProviderDelete,
+		Target:  name,
+		Success: true,
+	})
 	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
 }
 
@@ -1500,6 +1814,12 @@ func providerProfileToMap(p config.ProviderProfile) map[string]interface{} {
 		"default_test_model": strings.TrimSpace(p.DefaultTestModel),
 		"api_format":         strings.TrimSpace(p.APIFormat),
 		"timeout":            p.Timeout,
+		"stream":             p.StreamingEnabled(),
+		"system_prefix":      p.SystemPrefix,
+		"api_version":        strings.TrimSpace(p.APIVersion),
+		"deployments":        p.Deployments,
+		"middleware":         p.Middleware,
+		"capabilities":       p.Capabilities,
 	}
 }
 
@@ -1520,6 +1840,12 @@ func (s *Server) providerProfileToView(p config.ProviderProfile) map[string]inte
 		"supports_discovery": providerKindSupportsDiscovery(p.ProviderKind),
 		"summary":            summarizeProviderProfile(p),
 		"timeout":            p.Timeout,
+		"stream":             p.StreamingEnabled(),
+		"system_prefix":      p.SystemPrefix,
+		"api_version":        strings.TrimSpace(p.APIVersion),
+		"deployments":        p.Deployments,
+		"middleware":         p.Middleware,
+		"capabilities":       p.Capabilities,
 	}
 }
 
@@ -1628,7 +1954,7 @@ func (s *Server) handleDiscoverProviderModels(c *echo.Context) error {
 
 	name := strings.TrimSpace(profile.Name)
 	if name != "" {
-		if existing, err := s.providers.Get(c.Request().Context(), name); err == nil {
+		if existing, err := s.providers.Get(ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c)), name); err == nil {
 			if strings.TrimSpace(profile.APIKey) == "" {
 				profile.APIKey = existing.APIKey
 			}
@@ -1644,6 +1970,12 @@ func (s *Server) handleDiscoverProviderModels(c *echo.Context) error {
 			if strings.TrimSpace(profile.ProviderKind) == "" {
 				profile.ProviderKind = existing.ProviderKind
 			}
+			if strings.TrimSpace(profile.APIVersion) == "" {
+				profile.APIVersion = existing.APIVersion
+			}
+			if profile.Deployments == nil {
+				profile.Deployments = existing.Deployments
+			}
 		}
 	}
 
@@ -2015,2122 +2347,2579 @@ func (s *Server) handleDeleteCronJob(c *echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
 }
 
-func (s *Server) attachCronNotificationRoutes(ctx context.Context, jobs []*cron.Job) {
-	if s == nil || s.notificationMgr == nil {
-		return
+// handleListScheduledTasks lists the caller's pending scheduled reminders.
+func (s *Server) handleListScheduledTasks(c *echo.Context) error {
+	if s.cronMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "cron manager unavailable"})
 	}
-	for _, job := range jobs {
-		if job == nil || strings.TrimSpace(job.ID) == "" {
-			continue
-		}
-		binding, err := s.notificationMgr.FindBindingForTarget(ctx, notificationroutes.ScopeCronJob, job.ID)
-		if err != nil {
-			if s.logger != nil {
-				s.logger.Warn("Failed to load cron notification binding", zap.String("job_id", job.ID), zap.Error(err))
-			}
-			continue
-		}
-		if binding != nil && binding.Enabled {
-			job.NotificationRouteID = binding.RouteID
-		}
+	jobs := s.cronMgr.ListPendingJobs(s.currentUserID(c))
+	if jobs == nil {
+		jobs = []*cron.Job{}
 	}
+	return c.JSON(http.StatusOK, jobs)
 }
 
-func (s *Server) bindCronJobNotificationRoute(ctx context.Context, job *cron.Job, routeID string) error {
-	if s == nil || s.notificationMgr == nil {
-		return fmt.Errorf("notification routes not available")
-	}
-	if job == nil || strings.TrimSpace(job.ID) == "" {
-		return fmt.Errorf("cron job id is required")
-	}
-	if err := s.notificationMgr.DeleteBindingsForTarget(ctx, notificationroutes.ScopeCronJob, job.ID); err != nil {
-		return err
+func (s *Server) handleListFeeds(c *echo.Context) error {
+	if s.feedMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "feed manager unavailable"})
 	}
-	if strings.TrimSpace(routeID) == "" {
-		return nil
+	subs, err := s.feedMgr.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	_, err := s.notificationMgr.CreateBinding(ctx, notificationroutes.NotificationBinding{
-		Scope:          notificationroutes.ScopeCronJob,
-		Target:         job.ID,
-		RouteID:        routeID,
-		EventTypesJSON: `["` + notificationroutes.EventCronSucceeded + `","` + notificationroutes.EventCronFailed + `"]`,
-		Enabled:        true,
-		TenantID:       job.TenantID,
-		OwnerUserID:    job.OwnerUserID,
-		Visibility:     job.Visibility,
-	})
-	return err
+	return c.JSON(http.StatusOK, subs)
 }
 
-func (s *Server) handleEnableCronJob(c *echo.Context) error {
-	if s.cronMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "cron manager unavailable"})
+func (s *Server) handleCreateFeed(c *echo.Context) error {
+	if s.feedMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "feed manager unavailable"})
 	}
-	jobID := strings.TrimSpace(c.Param("id"))
-	if jobID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "job id is required"})
+	var body feeds.Subscription
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	if err := s.cronMgr.EnableJobAuth(ctx, jobID); err != nil {
-		if strings.Contains(err.Error(), "job not found") {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
-		}
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
-		}
-		s.logger.Error("Failed to enable cron job", zap.String("job_id", jobID), zap.Error(err))
+	sub, err := s.feedMgr.Create(c.Request().Context(), body)
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "enabled"})
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"status": "created",
+		"feed":   sub,
+	})
 }
 
-func (s *Server) handleDisableCronJob(c *echo.Context) error {
-	if s.cronMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "cron manager unavailable"})
+func (s *Server) handleUpdateFeed(c *echo.Context) error {
+	if s.feedMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "feed manager unavailable"})
 	}
-	jobID := strings.TrimSpace(c.Param("id"))
-	if jobID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "job id is required"})
+	var body feeds.Subscription
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	if err := s.cronMgr.DisableJobAuth(ctx, jobID); err != nil {
-		if strings.Contains(err.Error(), "job not found") {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
-		}
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
-		}
-		s.logger.Error("Failed to disable cron job", zap.String("job_id", jobID), zap.Error(err))
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	sub, err := s.feedMgr.Update(c.Request().Context(), c.Param("id"), body)
+	if err != nil {
+		return c.JSON(feedErrorStatus(err), map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "disabled"})
+	return c.JSON(http.StatusOK, sub)
 }
 
-func (s *Server) handleRunCronJob(c *echo.Context) error {
-	if s.cronMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "cron manager unavailable"})
-	}
-	jobID := strings.TrimSpace(c.Param("id"))
-	if jobID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "job id is required"})
+func (s *Server) handleDeleteFeed(c *echo.Context) error {
+	if s.feedMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "feed manager unavailable"})
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	if err := s.cronMgr.RunJobAuth(ctx, jobID); err != nil {
-		if strings.Contains(err.Error(), "job not found") {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
-		}
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
-		}
-		s.logger.Error("Failed to run cron job", zap.String("job_id", jobID), zap.Error(err))
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	if err := s.feedMgr.Delete(c.Request().Context(), c.Param("id")); err != nil {
+		return c.JSON(feedErrorStatus(err), map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "started"})
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
 }
 
-func (s *Server) discoverModels(kind string, profile *config.ProviderProfile) ([]string, error) {
-	kind = strings.ToLower(strings.TrimSpace(kind))
+// handleCheckFeed polls a feed right away, summarizing any new entries.
+func (s *Server) handleCheckFeed(c *echo.Context) error {
+	if s.feedMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "feed manager unavailable"})
+	}
+	result, err := s.feedMgr.CheckNow(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return c.JSON(feedErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
 
-	if kind == "openai" || kind == "generic" || kind == "openrouter" || kind == "groq" || kind == "vllm" || kind == "deepseek" || kind == "moonshot" || kind == "zhipu" || kind == "nvidia" {
-		if models, err := discoverOpenAICompatibleModelsFunc(profile.APIBase, profile.APIKey, profile.Proxy, profile.Timeout); err == nil && len(models) > 0 {
-			return models, nil
-		}
+func feedErrorStatus(err error) int {
+	if errors.Is(err, feeds.ErrNotFound) {
+		return http.StatusNotFound
 	}
+	return http.StatusBadRequest
+}
 
-	client, err := providers.NewClient(kind, &providers.RelayInfo{
-		ProviderName: kind,
-		APIKey:       profile.APIKey,
-		APIBase:      profile.APIBase,
-		Proxy:        profile.Proxy,
-		Timeout:      profile.GetTimeout(),
-	})
+func (s *Server) handleListNotifyRules(c *echo.Context) error {
+	if s.notifyMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notify rules unavailable"})
+	}
+	rules, err := s.notifyMgr.List(c.Request().Context())
 	if err != nil {
-		return nil, fmt.Errorf("init provider client failed: %w", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, rules)
+}
 
-	models, err := client.GetModelList()
+// handleListNotifyEventTypes lists the event types rules can match.
+func (s *Server) handleListNotifyEventTypes(c *echo.Context) error {
+	return c.JSON(http.StatusOK, bus.EventTypes)
+}
+
+func (s *Server) handleCreateNotifyRule(c *echo.Context) error {
+	if s.notifyMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notify rules unavailable"})
+	}
+	var body notify.Rule
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	rule, err := s.notifyMgr.Create(c.Request().Context(), body)
 	if err != nil {
-		return nil, fmt.Errorf("discover models failed: %w", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	sort.Strings(models)
-	return dedupeStrings(models), nil
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"status": "created",
+		"rule":   rule,
+	})
 }
 
-func discoverOpenAICompatibleModels(apiBase, apiKey, proxy string, timeout int) ([]string, error) {
-	base := strings.TrimRight(strings.TrimSpace(apiBase), "/")
-	if base == "" {
-		return nil, fmt.Errorf("api_base is required for OpenAI-compatible model discovery")
+func (s *Server) handleUpdateNotifyRule(c *echo.Context) error {
+	if s.notifyMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notify rules unavailable"})
 	}
-
-	client, err := providers.NewHTTPClientWithProxy(proxy)
-	if err != nil {
-		return nil, fmt.Errorf("setup proxy failed: %w", err)
+	var body notify.Rule
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	if timeout <= 0 {
-		timeout = 20
+	rule, err := s.notifyMgr.Update(c.Request().Context(), c.Param("id"), body)
+	if err != nil {
+		return c.JSON(notifyErrorStatus(err), map[string]string{"error": err.Error()})
 	}
-	client.Timeout = time.Duration(timeout) * time.Second
+	return c.JSON(http.StatusOK, rule)
+}
 
-	url := base + "/models"
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("build request failed: %w", err)
+func (s *Server) handleDeleteNotifyRule(c *echo.Context) error {
+	if s.notifyMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notify rules unavailable"})
 	}
-	if strings.TrimSpace(apiKey) != "" {
-		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(apiKey))
+	if err := s.notifyMgr.Delete(c.Request().Context(), c.Param("id")); err != nil {
+		return c.JSON(notifyErrorStatus(err), map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request /models failed: %w", err)
+func notifyErrorStatus(err error) int {
+	if errors.Is(err, notify.ErrNotFound) {
+		return http.StatusNotFound
 	}
-	defer func() { _ = resp.Body.Close() }()
+	return http.StatusBadRequest
+}
 
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("request /models failed: HTTP %d", resp.StatusCode)
+// handleGetUsageReport aggregates token usage and cost over a time range.
+// format=csv returns the report as a CSV attachment.
+func (s *Server) handleGetUsageReport(c *echo.Context) error {
+	if s.usageMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "usage reporting unavailable"})
 	}
-
-	var payload map[string]interface{}
-	if err := json.Unmarshal(body, &payload); err != nil {
-		return nil, fmt.Errorf("parse /models response failed: %w", err)
+	from, to, err := usage.ParseRange(c.QueryParam("from"), c.QueryParam("to"), time.Now())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-
-	models := make([]string, 0)
-	if data, ok := payload["data"].([]interface{}); ok {
-		for _, item := range data {
-			if m, ok := item.(map[string]interface{}); ok {
-				if id, ok := m["id"].(string); ok && strings.TrimSpace(id) != "" {
-					models = append(models, strings.TrimSpace(id))
-				}
-			}
-		}
+	groupBy, err := usage.NormalizeGroupBy(c.QueryParam("groupBy"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-
-	if data, ok := payload["models"].([]interface{}); ok {
-		for _, item := range data {
-			if m, ok := item.(map[string]interface{}); ok {
-				if id, ok := m["id"].(string); ok && strings.TrimSpace(id) != "" {
-					models = append(models, strings.TrimSpace(id))
-					continue
-				}
-				if name, ok := m["name"].(string); ok && strings.TrimSpace(name) != "" {
-					models = append(models, strings.TrimSpace(name))
-				}
-			}
-		}
+	report, err := s.usageMgr.Report(c.Request().Context(), from, to, groupBy)
+	if err != nil {
+		s.logger.Error("Failed to build usage report", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	if len(models) == 0 {
-		return nil, fmt.Errorf("no models found in /models response")
+	switch strings.TrimSpace(strings.ToLower(c.QueryParam("format"))) {
+	case "", "json":
+		return c.JSON(http.StatusOK, report)
+	case "csv":
+		var buf bytes.Buffer
+		if err := usage.WriteCSV(&buf, report); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		filename := fmt.Sprintf("nekobot-usage-%s-%s.csv", report.GroupBy, to.Format("20060102"))
+		c.Response().Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "format must be json or csv"})
 	}
-
-	sort.Strings(models)
-	return dedupeStrings(models), nil
 }
 
-var discoverOpenAICompatibleModelsFunc = discoverOpenAICompatibleModels
-
-func dedupeStrings(items []string) []string {
-	if len(items) == 0 {
-		return items
+// handleRecordFeedback rates an assistant reply in the caller's own chat.
+func (s *Server) handleRecordFeedback(c *echo.Context) error {
+	if s.feedbackMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "feedback unavailable"})
 	}
-	out := make([]string, 0, len(items))
-	seen := make(map[string]struct{}, len(items))
-	for _, it := range items {
-		v := strings.TrimSpace(it)
-		if v == "" {
-			continue
-		}
-		if _, ok := seen[v]; ok {
-			continue
-		}
-		seen[v] = struct{}{}
-		out = append(out, v)
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
 	}
-	return out
-}
-
-// --- Tool Session Handlers ---
-
-func (s *Server) handleListToolSessions(c *echo.Context) error {
-	if s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
+	var body struct {
+		RuntimeID    string `json:"runtime_id"`
+		MessageIndex *int   `json:"message_index"`
+		Rating       string `json:"rating"`
+		Comment      string `json:"comment"`
 	}
-
-	if sid := s.currentToolSessionID(c); sid != "" {
-		sess, err := s.toolSess.GetSession(c.Request().Context(), sid)
-		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				return c.JSON(http.StatusOK, []*toolsessions.Session{})
-			}
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-		}
-		return c.JSON(http.StatusOK, []*toolsessions.Session{sess})
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	rating, err := feedback.ParseRating(body.Rating)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if body.MessageIndex == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message_index is required"})
 	}
 
-	owner := s.currentUsername(c)
-	limit := 100
-	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
-		var parsed int
-		if _, err := fmt.Sscanf(raw, "%d", &parsed); err == nil && parsed > 0 && parsed <= 500 {
-			limit = parsed
-		}
+	username := s.currentUsername(c)
+	sessionID := s.chatSessionID(c.Request().Context(), username, strings.TrimSpace(body.RuntimeID))
+	sess, err := s.sessionMgr.GetExisting(sessionID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "chat session not found"})
+	}
+	messages := sess.GetMessages()
+	index := *body.MessageIndex
+	if index < 0 || index >= len(messages) || messages[index].Role != "assistant" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message_index does not point to an assistant reply"})
 	}
 
-	sessions, err := s.toolSess.ListSessions(c.Request().Context(), toolsessions.ListSessionsInput{
-		Owner:  owner,
-		Source: strings.TrimSpace(c.QueryParam("source")),
-		State:  strings.TrimSpace(c.QueryParam("state")),
-		Limit:  limit,
+	entry, err := s.feedbackMgr.Record(c.Request().Context(), feedback.Entry{
+		Channel:   "websocket",
+		SessionID: sessionID,
+		MessageID: feedback.SessionMessageID(index),
+		UserID:    username,
+		Rating:    rating,
+		Comment:   body.Comment,
+		Response:  messages[index].Content,
 	})
 	if err != nil {
+		s.logger.Error("Failed to record feedback", zap.Error(err))
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, sessions)
+	return c.JSON(http.StatusOK, entry)
 }
 
-func (s *Server) handleCreateToolSession(c *echo.Context) error {
-	if s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
+// handleListFeedback lists recorded reply ratings for review. Filters:
+// channel, session_id, rating (up|down), from/to and limit. format=csv
+// returns the entries as a CSV attachment.
+func (s *Server) handleListFeedback(c *echo.Context) error {
+	if s.feedbackMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "feedback unavailable"})
 	}
-
-	var body toolsessions.CreateSessionInput
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	query := feedback.Query{
+		Channel:   c.QueryParam("channel"),
+		SessionID: c.QueryParam("session_id"),
 	}
-	body.Owner = s.currentUsername(c)
-	if strings.TrimSpace(body.Tool) == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "tool is required"})
+	if value := strings.TrimSpace(c.QueryParam("rating")); value != "" {
+		rating, err := feedback.ParseRating(value)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		query.Rating = rating
 	}
-	if strings.TrimSpace(body.Source) == "" {
-		body.Source = toolsessions.SourceWebUI
+	if c.QueryParam("from") != "" || c.QueryParam("to") != "" {
+		from, to, err := usage.ParseRange(c.QueryParam("from"), c.QueryParam("to"), time.Now())
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		query.From, query.To = from, to
 	}
-
-	sess, err := s.toolSess.CreateSession(c.Request().Context(), body)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	if value := strings.TrimSpace(c.QueryParam("limit")); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "limit must be a positive integer"})
+		}
+		query.Limit = limit
 	}
-	return c.JSON(http.StatusCreated, sess)
-}
 
-func (s *Server) handleDetachToolSession(c *echo.Context) error {
-	if s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
-	}
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
-	}
-	if err := s.ensureSessionOwner(c, id); err != nil {
-		return err
+	entries, err := s.feedbackMgr.List(c.Request().Context(), query)
+	if err != nil {
+		s.logger.Error("Failed to list feedback", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if err := s.toolSess.DetachSession(c.Request().Context(), id); err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+	switch strings.TrimSpace(strings.ToLower(c.QueryParam("format"))) {
+	case "", "json":
+		return c.JSON(http.StatusOK, entries)
+	case "csv":
+		var buf bytes.Buffer
+		if err := feedback.WriteCSV(&buf, entries); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		filename := fmt.Sprintf("nekobot-feedback-%s.csv", time.Now().Format("20060102"))
+		c.Response().Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "format must be json or csv"})
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "detached"})
 }
 
-func (s *Server) handleTerminateToolSession(c *echo.Context) error {
-	if s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
-	}
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
-	}
-	if err := s.ensureSessionOwner(c, id); err != nil {
-		return err
-	}
-	var body struct {
-		Reason string `json:"reason"`
+// handleCancelScheduledTask cancels one of the caller's scheduled reminders.
+func (s *Server) handleCancelScheduledTask(c *echo.Context) error {
+	if s.cronMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "cron manager unavailable"})
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	jobID := strings.TrimSpace(c.Param("id"))
+	if jobID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "task id is required"})
 	}
-	if s.processMgr != nil {
-		if err := s.processMgr.Kill(id); err != nil && !isProcessSessionNotFound(err) && !strings.Contains(strings.ToLower(err.Error()), "not running") {
-			s.logger.Warn("Failed to kill tool session process during terminate",
-				zap.String("session_id", id),
-				zap.Error(err),
-			)
+	if err := s.cronMgr.CancelJob(s.currentUserID(c), jobID); err != nil {
+		if strings.Contains(err.Error(), "job not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 		}
+		s.logger.Error("Failed to cancel scheduled task", zap.String("job_id", jobID), zap.Error(err))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	s.tryKillRuntimeSession(c.Request().Context(), id)
-	if err := s.toolSess.TerminateSession(c.Request().Context(), id, body.Reason); err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+	if s.notificationMgr != nil {
+		ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+		if err := s.notificationMgr.DeleteBindingsForTarget(ctx, notificationroutes.ScopeCronJob, jobID); err != nil {
+			s.logger.Warn("Failed to delete notification bindings for cancelled task", zap.String("job_id", jobID), zap.Error(err))
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "terminated"})
+	return c.JSON(http.StatusOK, map[string]string{"status": "cancelled"})
 }
 
-func (s *Server) handleCleanupTerminatedToolSessions(c *echo.Context) error {
-	if s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
-	}
-	count, err := s.toolSess.ArchiveTerminatedSessions(c.Request().Context(), s.currentUsername(c))
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+func (s *Server) backgroundJobs() *background.Manager {
+	if s.agent == nil {
+		return nil
 	}
-	return c.JSON(http.StatusOK, map[string]int{"archived": count})
+	return s.agent.BackgroundJobs()
 }
 
-func (s *Server) handleCleanupToolSessionEvents(c *echo.Context) error {
-	if s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
-	}
-	count, err := s.toolSess.DeleteAllEvents(c.Request().Context())
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+func (s *Server) handleListBackgroundJobs(c *echo.Context) error {
+	jobs := s.backgroundJobs()
+	if jobs == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "background jobs unavailable"})
 	}
-	return c.JSON(http.StatusOK, map[string]int{"deleted": count})
+	return c.JSON(http.StatusOK, jobs.List("", ""))
 }
 
-func (s *Server) handleCreateToolSessionAttachToken(c *echo.Context) error {
-	if s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
-	}
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+func (s *Server) handleCancelBackgroundJob(c *echo.Context) error {
+	jobs := s.backgroundJobs()
+	if jobs == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "background jobs unavailable"})
 	}
-	if err := s.ensureSessionOwner(c, id); err != nil {
-		return err
-	}
-	var body struct {
-		TTLSeconds int `json:"ttl_seconds"`
+	jobID := strings.TrimSpace(c.Param("id"))
+	if err := jobs.Cancel("", "", jobID); err != nil {
+		if errors.Is(err, background.ErrJobNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	_ = c.Bind(&body)
-	ttl := time.Minute
-	if body.TTLSeconds > 0 && body.TTLSeconds <= 600 {
-		ttl = time.Duration(body.TTLSeconds) * time.Second
+	return c.JSON(http.StatusOK, map[string]string{"status": "cancelled"})
+}
+
+func (s *Server) attachCronNotificationRoutes(ctx context.Context, jobs []*cron.Job) {
+	if s == nil || s.notificationMgr == nil {
+		return
 	}
-	token, err := s.toolSess.CreateAttachToken(c.Request().Context(), id, s.currentUsername(c), ttl)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+	for _, job := range jobs {
+		if job == nil || strings.TrimSpace(job.ID) == "" {
+			continue
+		}
+		binding, err := s.notificationMgr.FindBindingForTarget(ctx, notificationroutes.ScopeCronJob, job.ID)
+		if err != nil {
+			if s.logger != nil {
+				s.logger.Warn("Failed to load cron notification binding", zap.String("job_id", job.ID), zap.Error(err))
+			}
+			continue
+		}
+		if binding != nil && binding.Enabled {
+			job.NotificationRouteID = binding.RouteID
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, map[string]string{"token": token})
 }
 
-func (s *Server) handleConsumeToolSessionAttachToken(c *echo.Context) error {
-	if s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
+func (s *Server) bindCronJobNotificationRoute(ctx context.Context, job *cron.Job, routeID string) error {
+	if s == nil || s.notificationMgr == nil {
+		return fmt.Errorf("notification routes not available")
 	}
-	var body struct {
-		Token string `json:"token"`
+	if job == nil || strings.TrimSpace(job.ID) == "" {
+		return fmt.Errorf("cron job id is required")
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	if err := s.notificationMgr.DeleteBindingsForTarget(ctx, notificationroutes.ScopeCronJob, job.ID); err != nil {
+		return err
 	}
-	sess, err := s.toolSess.ConsumeAttachToken(c.Request().Context(), body.Token, s.currentUsername(c))
-	if err != nil {
-		switch {
-		case errors.Is(err, os.ErrNotExist):
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "token not found"})
-		case errors.Is(err, os.ErrPermission):
-			return c.JSON(http.StatusForbidden, map[string]string{"error": "token is not valid for this user"})
-		case errors.Is(err, os.ErrDeadlineExceeded):
-			return c.JSON(http.StatusGone, map[string]string{"error": "token expired"})
-		default:
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-		}
+	if strings.TrimSpace(routeID) == "" {
+		return nil
 	}
-	return c.JSON(http.StatusOK, sess)
+	_, err := s.notificationMgr.CreateBinding(ctx, notificationroutes.NotificationBinding{
+		Scope:          notificationroutes.ScopeCronJob,
+		Target:         job.ID,
+		RouteID:        routeID,
+		EventTypesJSON: `["` + notificationroutes.EventCronSucceeded + `","` + notificationroutes.EventCronFailed + `"]`,
+		Enabled:        true,
+		TenantID:       job.TenantID,
+		OwnerUserID:    job.OwnerUserID,
+		Visibility:     job.Visibility,
+	})
+	return err
 }
 
-func (s *Server) handleSpawnToolSession(c *echo.Context) error {
-	if s.toolSess == nil || s.processMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
+func (s *Server) handleEnableCronJob(c *echo.Context) error {
+	if s.cronMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "cron manager unavailable"})
 	}
-
-	var body struct {
-		Tool             string                 `json:"tool"`
-		Title            string                 `json:"title"`
-		Command          string                 `json:"command"`
-		CommandArgs      string                 `json:"command_args"`
-		RuntimeTransport string                 `json:"runtime_transport"`
-		Workdir          string                 `json:"workdir"`
-		Metadata         map[string]interface{} `json:"metadata"`
-		AccessMode       string                 `json:"access_mode"`
-		AccessPassword   string                 `json:"access_password"`
-		ProxyMode        string                 `json:"proxy_mode"`
-		ProxyURL         string                 `json:"proxy_url"`
-		PublicBaseURL    string                 `json:"public_base_url"`
+	jobID := strings.TrimSpace(c.Param("id"))
+	if jobID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "job id is required"})
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	if err := s.cronMgr.EnableJobAuth(ctx, jobID); err != nil {
+		if strings.Contains(err.Error(), "job not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		s.logger.Error("Failed to enable cron job", zap.String("job_id", jobID), zap.Error(err))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "enabled"})
+}
 
-	toolName := strings.TrimSpace(body.Tool)
-	if toolName == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "tool is required"})
-	}
-	command := resolveToolCommandWithArgs(toolName, body.Command, body.CommandArgs)
-	if command == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "command is required"})
+func (s *Server) handleDisableCronJob(c *echo.Context) error {
+	if s.cronMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "cron manager unavailable"})
 	}
-	workdir := strings.TrimSpace(body.Workdir)
-	if workdir == "" {
-		workdir = s.config.WorkspacePath()
+	jobID := strings.TrimSpace(c.Param("id"))
+	if jobID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "job id is required"})
 	}
-	proxyMode, proxyURL, err := resolveToolProxyConfig("", "", body.ProxyMode, body.ProxyURL)
-	if err != nil {
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	if err := s.cronMgr.DisableJobAuth(ctx, jobID); err != nil {
+		if strings.Contains(err.Error(), "job not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		s.logger.Error("Failed to disable cron job", zap.String("job_id", jobID), zap.Error(err))
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	metadata := cloneMap(body.Metadata)
-	metadata = withToolProxyMetadata(metadata, proxyMode, proxyURL)
-	metadata["user_command"] = command
-	metadata["user_args"] = strings.TrimSpace(body.CommandArgs)
-	transport := s.resolveSessionRuntimeTransport(metadata, body.RuntimeTransport)
+	return c.JSON(http.StatusOK, map[string]string{"status": "disabled"})
+}
 
-	sess, err := s.toolSess.CreateSession(c.Request().Context(), toolsessions.CreateSessionInput{
-		Owner:    s.currentUsername(c),
-		Source:   toolsessions.SourceWebUI,
-		Tool:     toolName,
-		Title:    strings.TrimSpace(body.Title),
-		Command:  command,
-		Workdir:  workdir,
-		State:    toolsessions.StateRunning,
-		Metadata: metadata,
-	})
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+func (s *Server) handleRunCronJob(c *echo.Context) error {
+	if s.cronMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "cron manager unavailable"})
 	}
-
-	launchCommand := applyToolProxyToCommand(command, proxyMode, proxyURL)
-	runtimeSession := ""
-	if wrapped, sessionName := buildToolRuntimeLaunchWithTransport(transport, launchCommand, sess.ID); sessionName != "" {
-		launchCommand = wrapped
-		runtimeSession = sessionName
-		metadata = runtimeagents.ApplyLaunchMetadata(metadata, runtimeagents.LaunchInfo{
-			TransportName: transport.Name(),
-			SessionName:   runtimeSession,
-			LaunchCommand: launchCommand,
-		})
+	jobID := strings.TrimSpace(c.Param("id"))
+	if jobID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "job id is required"})
 	}
-	metadata[runtimeagents.MetadataLaunchCommand] = launchCommand
-	if err := s.toolSess.UpdateSessionMetadata(c.Request().Context(), sess.ID, metadata); err != nil {
-		if terminateErr := s.toolSess.TerminateSession(context.Background(), sess.ID, "failed to persist launch metadata: "+err.Error()); terminateErr != nil {
-			s.logger.Warn("Failed to terminate tool session after launch metadata error",
-				zap.String("session_id", sess.ID),
-				zap.Error(terminateErr),
-			)
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	if err := s.cronMgr.RunJobAuth(ctx, jobID); err != nil {
+		if strings.Contains(err.Error(), "job not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to persist tool session metadata: " + err.Error()})
-	}
-	sess, err = s.toolSess.GetSession(c.Request().Context(), sess.ID)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to reload tool session: " + err.Error()})
-	}
-
-	spec := execenv.StartSpecFromContext(c.Request().Context(), sess.ID, launchCommand, workdir, metadata)
-	if err := s.processMgr.StartWithSpec(context.Background(), spec); err != nil {
-		if terminateErr := s.toolSess.TerminateSession(context.Background(), sess.ID, "failed to start process: "+err.Error()); terminateErr != nil {
-			s.logger.Warn("Failed to terminate tool session after start error",
-				zap.String("session_id", sess.ID),
-				zap.Error(terminateErr),
-			)
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
 		}
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to start tool process: " + err.Error()})
+		s.logger.Error("Failed to run cron job", zap.String("job_id", jobID), zap.Error(err))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	accessMode := strings.TrimSpace(body.AccessMode)
-	accessPassword := ""
-	if accessMode != "" && accessMode != toolsessions.AccessModeNone {
-		accessPassword, err = s.toolSess.ConfigureSessionAccess(c.Request().Context(), sess.ID, accessMode, body.AccessPassword)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to configure session access: " + err.Error()})
-		}
-		sess, err = s.toolSess.GetSession(c.Request().Context(), sess.ID)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to reload tool session: " + err.Error()})
+	return c.JSON(http.StatusOK, map[string]string{"status": "started"})
+}
+
+func (s *Server) discoverModels(kind string, profile *config.ProviderProfile) ([]string, error) {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+
+	if kind == "openai" || kind == "generic" || kind == "openrouter" || kind == "groq" || kind == "vllm" || kind == "deepseek" || kind == "moonshot" || kind == "zhipu" || kind == "nvidia" {
+		if models, err := discoverOpenAICompatibleModelsFunc(profile.APIBase, profile.APIKey, profile.Proxy, profile.Timeout); err == nil && len(models) > 0 {
+			return models, nil
 		}
 	}
-	accessURL := ""
-	if strings.TrimSpace(sess.AccessMode) != "" && sess.AccessMode != toolsessions.AccessModeNone {
-		accessURL = s.buildToolSessionAccessURL(c, sess.ID, strings.TrimSpace(body.PublicBaseURL))
-	}
-	sess, err = s.toolSess.GetSession(c.Request().Context(), sess.ID)
+
+	client, err := providers.NewClient(kind, &providers.RelayInfo{
+		ProviderName: kind,
+		APIKey:       profile.APIKey,
+		APIBase:      profile.APIBase,
+		Proxy:        profile.Proxy,
+		Timeout:      profile.GetTimeout(),
+		APIVersion:   profile.APIVersion,
+		Deployments:  profile.Deployments,
+	})
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to reload tool session: " + err.Error()})
+		return nil, fmt.Errorf("init provider client failed: %w", err)
 	}
 
-	eventPayload := runtimeagents.ApplyRuntimeSessionPayload(map[string]interface{}{
-		"command":    command,
-		"launch_cmd": launchCommand,
-		"workdir":    workdir,
-		"proxy_mode": proxyMode,
-	}, transport.Name(), runtimeSession)
-	eventPayload[runtimeagents.MetadataRuntimeTransport] = strings.TrimSpace(transport.Name())
-	if err := s.toolSess.AppendEvent(context.Background(), sess.ID, "process_started", eventPayload); err != nil {
-		s.logger.Warn("Failed to append tool session start event",
-			zap.String("session_id", sess.ID),
-			zap.Error(err),
-		)
+	models, err := client.GetModelList()
+	if err != nil {
+		return nil, fmt.Errorf("discover models failed: %w", err)
 	}
-	return c.JSON(http.StatusCreated, map[string]interface{}{
-		"session":         sess,
-		"access_mode":     sess.AccessMode,
-		"access_url":      accessURL,
-		"access_password": accessPassword,
-	})
+	sort.Strings(models)
+	return dedupeStrings(models), nil
 }
 
-func (s *Server) handleResolveExternalAgentSession(c *echo.Context) error {
-	if s.externalAgent == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "external agent manager not available"})
+func discoverOpenAICompatibleModels(apiBase, apiKey, proxy string, timeout int) ([]string, error) {
+	base := strings.TrimRight(strings.TrimSpace(apiBase), "/")
+	if base == "" {
+		return nil, fmt.Errorf("api_base is required for OpenAI-compatible model discovery")
 	}
 
-	var body struct {
-		AgentKind string `json:"agent_kind"`
-		Workspace string `json:"workspace"`
-		Tool      string `json:"tool"`
-		Title     string `json:"title"`
-		Command   string `json:"command"`
+	client, err := providers.NewHTTPClientWithProxy(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("setup proxy failed: %w", err)
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	if timeout <= 0 {
+		timeout = 20
 	}
+	client.Timeout = time.Duration(timeout) * time.Second
 
-	var probe externalAgentProcessProbe
-	var starter externalagent.ProcessStarter
-	if s.processMgr != nil {
-		probe.mgr = s.processMgr
-		starter = s.processMgr
+	url := base + "/models"
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request failed: %w", err)
 	}
-	result, err := externalagent.ExecuteResolveFlow(
-		c.Request().Context(),
-		s.externalAgent,
-		externalagent.NewResolveOrchestrator(s.config, s.logger, s.entClient, s.approval, s.taskStore),
-		s.config.WorkspacePath(),
-		probe,
-		starter,
-		s.toolSess,
-		runtimeagents.DefaultTransport(),
-		externalagent.SessionSpec{
-			Owner:     s.currentUsername(c),
-			AgentKind: body.AgentKind,
-			Workspace: body.Workspace,
-			Tool:      body.Tool,
-			Title:     body.Title,
-			Command:   body.Command,
-		},
-	)
+	if strings.TrimSpace(apiKey) != "" {
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(apiKey))
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return nil, fmt.Errorf("request /models failed: %w", err)
 	}
-	return c.JSON(result.HTTPStatus(), result.ResponseBody())
-}
+	defer func() { _ = resp.Body.Close() }()
 
-func (s *Server) handleGetExternalAgentCatalog(c *echo.Context) error {
-	registry := externalagent.NewRegistry()
-	type adapterPayload struct {
-		Kind            string   `json:"kind"`
-		Tool            string   `json:"tool"`
-		Command         string   `json:"command"`
-		SupportsInstall bool     `json:"supports_install"`
-		InstallHint     []string `json:"install_hint,omitempty"`
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("request /models failed: HTTP %d", resp.StatusCode)
 	}
-	items := make([]adapterPayload, 0, len(registry.List()))
-	for _, adapter := range registry.List() {
-		payload := adapterPayload{
-			Kind:            adapter.Kind(),
-			Tool:            adapter.Tool(),
-			Command:         adapter.Command(),
-			SupportsInstall: adapter.SupportsAutoInstall(),
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("parse /models response failed: %w", err)
+	}
+
+	models := make([]string, 0)
+	if data, ok := payload["data"].([]interface{}); ok {
+		for _, item := range data {
+			if m, ok := item.(map[string]interface{}); ok {
+				if id, ok := m["id"].(string); ok && strings.TrimSpace(id) != "" {
+					models = append(models, strings.TrimSpace(id))
+				}
+			}
 		}
-		if adapter.SupportsAutoInstall() {
-			payload.InstallHint = adapter.InstallCommand(runtime.GOOS)
+	}
+
+	if data, ok := payload["models"].([]interface{}); ok {
+		for _, item := range data {
+			if m, ok := item.(map[string]interface{}); ok {
+				if id, ok := m["id"].(string); ok && strings.TrimSpace(id) != "" {
+					models = append(models, strings.TrimSpace(id))
+					continue
+				}
+				if name, ok := m["name"].(string); ok && strings.TrimSpace(name) != "" {
+					models = append(models, strings.TrimSpace(name))
+				}
+			}
 		}
-		items = append(items, payload)
 	}
-	installed, err := externalagent.DetectInstalled("")
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+
+	if len(models) == 0 {
+		return nil, fmt.Errorf("no models found in /models response")
 	}
-	return c.JSON(http.StatusOK, map[string]any{
-		"adapters":  items,
-		"installed": installed,
-	})
+
+	sort.Strings(models)
+	return dedupeStrings(models), nil
 }
 
-func (s *Server) handleGetDaemonRegistry(c *echo.Context) error {
-	if s.kvStore == nil {
-		return c.JSON(http.StatusOK, map[string]any{
-			"machines": []daemonhost.MachineStatus{},
-		})
+var discoverOpenAICompatibleModelsFunc = discoverOpenAICompatibleModels
+
+func dedupeStrings(items []string) []string {
+	if len(items) == 0 {
+		return items
 	}
-	snapshot, err := daemonhost.NewRegistry(s.kvStore).Snapshot(c.Request().Context())
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	out := make([]string, 0, len(items))
+	seen := make(map[string]struct{}, len(items))
+	for _, it := range items {
+		v := strings.TrimSpace(it)
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
 	}
-	return c.JSON(http.StatusOK, map[string]any{
-		"machines": daemonhost.MachineStatuses(snapshot),
-	})
+	return out
 }
 
-// handleGetDaemonInventory returns a two-level computer→agent inventory view
-// that merges daemon registry data (machines + per-runtime details) with agent
-// profile definitions (provider, model, skills, env).
-func (s *Server) handleGetDaemonInventory(c *echo.Context) error {
-	ctx := c.Request().Context()
+// --- Tool Session Handlers ---
 
-	type runtimeDetail struct {
-		RuntimeID        string   `json:"runtime_id"`
-		MachineID        string   `json:"computer_id"`
-		WorkspaceID      string   `json:"workspace_id"`
-		Kind             string   `json:"kind"`
-		DisplayName      string   `json:"display_name"`
-		Tool             string   `json:"tool"`
-		Command          string   `json:"command"`
-		Installed        bool     `json:"installed"`
-		Healthy          bool     `json:"healthy"`
-		CurrentTaskCount uint32   `json:"current_task_count"`
-		PendingTaskCount uint32   `json:"pending_task_count"`
-		Provider         string   `json:"provider,omitempty"`
-		Model            string   `json:"model,omitempty"`
-		Enabled          bool     `json:"enabled"`
-		SkillNames       []string `json:"skill_names,omitempty"`
-		EnvCount         int      `json:"env_count"`
+func (s *Server) handleListToolSessions(c *echo.Context) error {
+	if s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
 	}
 
-	type machineInventory struct {
-		Info                  *daemonv1.ComputerInfo `json:"info"`
-		Status                string                 `json:"status"`
-		WorkspaceCount        int                    `json:"workspace_count"`
-		RuntimeCount          int                    `json:"runtime_count"`
-		InstalledRuntimeCount int                    `json:"installed_runtime_count"`
-		HealthyRuntimeCount   int                    `json:"healthy_runtime_count"`
-		GoalRunRunnable       bool                   `json:"goal_run_runnable"`
-		Runtimes              []runtimeDetail        `json:"runtimes"`
+	if sid := s.currentToolSessionID(c); sid != "" {
+		sess, err := s.toolSess.GetSession(c.Request().Context(), sid)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return c.JSON(http.StatusOK, []*toolsessions.Session{})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusOK, []*toolsessions.Session{sess})
 	}
 
-	emptyResponse := map[string]any{"machines": []machineInventory{}}
-	if s.kvStore == nil {
-		return c.JSON(http.StatusOK, emptyResponse)
+	owner := s.currentUsername(c)
+	limit := 100
+	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
+		var parsed int
+		if _, err := fmt.Sscanf(raw, "%d", &parsed); err == nil && parsed > 0 && parsed <= 500 {
+			limit = parsed
+		}
 	}
 
-	snapshot, err := daemonhost.NewRegistry(s.kvStore).Snapshot(ctx)
+	sessions, err := s.toolSess.ListSessions(c.Request().Context(), toolsessions.ListSessionsInput{
+		Owner:  owner,
+		Source: strings.TrimSpace(c.QueryParam("source")),
+		State:  strings.TrimSpace(c.QueryParam("state")),
+		Limit:  limit,
+	})
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, sessions)
+}
 
-	// Build agent profile lookup by runtime_id.
-	type profileInfo struct {
-		Provider   string
-		Model      string
-		Enabled    bool
-		SkillNames []string
-		EnvCount   int
+func (s *Server) handleCreateToolSession(c *echo.Context) error {
+	if s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
 	}
-	profileByRuntime := map[string]profileInfo{}
-	if s.runtimeMgr != nil {
-		if items, listErr := s.runtimeMgr.List(ctx); listErr == nil {
-			envByRuntime := map[string]int{}
-			if s.kvStore != nil {
-				if raw, ok, _ := s.kvStore.Get(ctx, "daemon.collaboration.agent_env.v1"); ok {
-					if envMap, castOK := raw.(map[string]interface{}); castOK {
-						for rtID, varsRaw := range envMap {
-							if varsSlice, sliceOK := varsRaw.([]interface{}); sliceOK {
-								envByRuntime[rtID] = len(varsSlice)
-							}
-						}
-					}
-				}
-			}
-			for _, item := range items {
-				skillNames := make([]string, 0, len(item.Skills))
-				skillNames = append(skillNames, item.Skills...)
-				profileByRuntime[item.ID] = profileInfo{
-					Provider:   item.Provider,
-					Model:      item.Model,
-					Enabled:    item.Enabled,
-					SkillNames: skillNames,
-					EnvCount:   envByRuntime[item.ID],
-				}
-			}
-		}
+
+	var body toolsessions.CreateSessionInput
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	body.Owner = s.currentUsername(c)
+	if strings.TrimSpace(body.Tool) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "tool is required"})
+	}
+	if strings.TrimSpace(body.Source) == "" {
+		body.Source = toolsessions.SourceWebUI
+	}
+	workdir, err := s.tenantWorkdir(c, body.Workdir)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
+	body.Workdir = workdir
 
-	// Build per-machine inventory with runtime details.
-	machineIDs := make([]string, 0, len(snapshot.Machines))
-	for id := range snapshot.Machines {
-		machineIDs = append(machineIDs, id)
+	sess, err := s.toolSess.CreateSession(c.Request().Context(), body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	sort.Strings(machineIDs)
-	now := time.Now()
-	machines := make([]machineInventory, 0, len(machineIDs))
-	for _, id := range machineIDs {
-		info := snapshot.Machines[id]
-		if info == nil {
-			continue
-		}
-		cloned := proto.Clone(info).(*daemonv1.ComputerInfo)
-		cloned.Status = daemonhost.DeriveMachineStatus(info, now)
-		mi := machineInventory{
-			Info:   cloned,
-			Status: cloned.Status,
+	return c.JSON(http.StatusCreated, sess)
+}
+
+func (s *Server) handleDetachToolSession(c *echo.Context) error {
+	if s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+	if ok, err := s.ensureSessionOwner(c, id); !ok {
+		return err
+	}
+	if err := s.toolSess.DetachSession(c.Request().Context(), id); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
 		}
-		if inv, ok := snapshot.Inventories[id]; ok && inv != nil {
-			mi.WorkspaceCount = len(inv.Workspaces)
-			mi.RuntimeCount = len(inv.Runtimes)
-			mi.Runtimes = make([]runtimeDetail, 0, len(inv.Runtimes))
-			for _, rt := range inv.Runtimes {
-				if rt == nil {
-					continue
-				}
-				if rt.Installed {
-					mi.InstalledRuntimeCount++
-				}
-				if rt.Installed && rt.Healthy {
-					mi.HealthyRuntimeCount++
-				}
-				rd := runtimeDetail{
-					RuntimeID:        rt.RuntimeId,
-					MachineID:        rt.ComputerId,
-					WorkspaceID:      rt.WorkspaceId,
-					Kind:             rt.Kind,
-					DisplayName:      rt.DisplayName,
-					Tool:             rt.Tool,
-					Command:          rt.Command,
-					Installed:        rt.Installed,
-					Healthy:          rt.Healthy,
-					CurrentTaskCount: rt.CurrentTaskCount,
-					PendingTaskCount: rt.PendingTaskCount,
-				}
-				if p, ok := profileByRuntime[rt.RuntimeId]; ok {
-					rd.Provider = p.Provider
-					rd.Model = p.Model
-					rd.Enabled = p.Enabled
-					rd.SkillNames = p.SkillNames
-					rd.EnvCount = p.EnvCount
-				}
-				mi.Runtimes = append(mi.Runtimes, rd)
-			}
-			_, _, mi.GoalRunRunnable = daemonhost.SelectRunnableWorkspaceRuntime(inv)
-		}
-		machines = append(machines, mi)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, map[string]any{"machines": machines})
+	return c.JSON(http.StatusOK, map[string]string{"status": "detached"})
 }
 
-func (s *Server) daemonClientForMachine(ctx context.Context, machineID string) (*daemonhost.Client, *daemonv1.ComputerInventory, error) {
-	if s == nil || s.kvStore == nil {
-		return nil, nil, fmt.Errorf("daemon registry unavailable")
+func (s *Server) handleTerminateToolSession(c *echo.Context) error {
+	if s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
 	}
-	snapshot, err := daemonhost.NewRegistry(s.kvStore).Snapshot(ctx)
-	if err != nil {
-		return nil, nil, err
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
 	}
-	machineID = strings.TrimSpace(machineID)
-	if machineID == "" {
-		return nil, nil, fmt.Errorf("computer_id is required")
+	if ok, err := s.ensureSessionOwner(c, id); !ok {
+		return err
 	}
-	info := snapshot.Machines[machineID]
-	if info == nil {
-		return nil, nil, fmt.Errorf("daemon machine not found")
+	var body struct {
+		Reason string `json:"reason"`
 	}
-	baseURL := strings.TrimSpace(info.DaemonUrl)
-	if baseURL == "" {
-		return nil, nil, fmt.Errorf("daemon machine has no reachable daemon_url")
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	inv := snapshot.Inventories[machineID]
-	if inv == nil {
-		inv = &daemonv1.ComputerInventory{}
+	if s.processMgr != nil {
+		if err := s.processMgr.Kill(id); err != nil && !isProcessSessionNotFound(err) && !strings.Contains(strings.ToLower(err.Error()), "not running") {
+			s.logger.Warn("Failed to kill tool session process during terminate",
+				zap.String("session_id", id),
+				zap.Error(err),
+			)
+		}
 	}
-	return daemonhost.NewClient(baseURL), inv, nil
+	s.tryKillRuntimeSession(c.Request().Context(), id)
+	if err := s.toolSess.TerminateSession(c.Request().Context(), id, body.Reason); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "terminated"})
 }
 
-func (s *Server) handleDaemonExplorerWorkspaces(c *echo.Context) error {
-	machineID := strings.TrimSpace(c.QueryParam("computer_id"))
-	_, inv, err := s.daemonClientForMachine(c.Request().Context(), machineID)
+func (s *Server) handleCleanupTerminatedToolSessions(c *echo.Context) error {
+	if s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
+	}
+	count, err := s.toolSess.ArchiveTerminatedSessions(c.Request().Context(), s.currentUsername(c))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	workspaces := make([]*daemonv1.Workspace, 0, len(inv.Workspaces))
-	for _, ws := range inv.Workspaces {
-		if ws == nil {
-			continue
-		}
-		workspaces = append(workspaces, ws)
+	return c.JSON(http.StatusOK, map[string]int{"archived": count})
+}
+
+func (s *Server) handleCleanupToolSessionEvents(c *echo.Context) error {
+	if s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
 	}
-	return c.JSON(http.StatusOK, map[string]any{"workspaces": workspaces})
+	count, err := s.toolSess.DeleteAllEvents(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]int{"deleted": count})
 }
 
-func (s *Server) handleDaemonExplorerTree(c *echo.Context) error {
+func (s *Server) handleCreateToolSessionAttachToken(c *echo.Context) error {
+	if s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+	if ok, err := s.ensureSessionOwner(c, id); !ok {
+		return err
+	}
 	var body struct {
-		MachineID   string `json:"computer_id"`
-		WorkspaceID string `json:"workspace_id"`
-		Path        string `json:"path"`
+		TTLSeconds int `json:"ttl_seconds"`
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	_ = c.Bind(&body)
+	ttl := time.Minute
+	if body.TTLSeconds > 0 && body.TTLSeconds <= 600 {
+		ttl = time.Duration(body.TTLSeconds) * time.Second
 	}
-	client, inv, err := s.daemonClientForMachine(c.Request().Context(), body.MachineID)
+	token, err := s.toolSess.CreateAttachToken(c.Request().Context(), id, s.currentUsername(c), ttl)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
-	}
-	workspaceID := strings.TrimSpace(body.WorkspaceID)
-	if workspaceID == "" {
-		for _, ws := range inv.Workspaces {
-			if ws != nil && ws.IsDefault {
-				workspaceID = strings.TrimSpace(ws.WorkspaceId)
-				break
-			}
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
 		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	resp, err := client.ListWorkspaceTree(&daemonv1.ListWorkspaceTreeRequest{
-		WorkspaceId: workspaceID,
-		Path:        strings.TrimSpace(body.Path),
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionToolSessionAccess,
+		Target:  id,
+		Success: true,
+		Summary: "attach token issued for " + ttl.String(),
 	})
+	return c.JSON(http.StatusOK, map[string]string{"token": token})
+}
+
+func (s *Server) handleConsumeToolSessionAttachToken(c *echo.Context) error {
+	if s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
+	}
+	var body struct {
+		Token string `json:"token"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	sess, err := s.toolSess.ConsumeAttachToken(c.Request().Context(), body.Token, s.currentUsername(c))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "token not found"})
+		case errors.Is(err, os.ErrPermission):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "token is not valid for this user"})
+		case errors.Is(err, os.ErrDeadlineExceeded):
+			return c.JSON(http.StatusGone, map[string]string{"error": "token expired"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
 	}
-	return c.JSON(http.StatusOK, resp)
+	return c.JSON(http.StatusOK, sess)
 }
 
-func (s *Server) handleDaemonExplorerFile(c *echo.Context) error {
+func (s *Server) handleSpawnToolSession(c *echo.Context) error {
+	if s.toolSess == nil || s.processMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
+	}
+
 	var body struct {
-		MachineID   string `json:"computer_id"`
-		WorkspaceID string `json:"workspace_id"`
-		Path        string `json:"path"`
+		Tool             string                 `json:"tool"`
+		Title            string                 `json:"title"`
+		Command          string                 `json:"command"`
+		CommandArgs      string                 `json:"command_args"`
+		RuntimeTransport string                 `json:"runtime_transport"`
+		Workdir          string                 `json:"workdir"`
+		Metadata         map[string]interface{} `json:"metadata"`
+		AccessMode       string                 `json:"access_mode"`
+		AccessPassword   string                 `json:"access_password"`
+		ProxyMode        string                 `json:"proxy_mode"`
+		ProxyURL         string                 `json:"proxy_url"`
+		PublicBaseURL    string                 `json:"public_base_url"`
 	}
 	if err := c.Bind(&body); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	client, _, err := s.daemonClientForMachine(c.Request().Context(), body.MachineID)
+
+	toolName := strings.TrimSpace(body.Tool)
+	if toolName == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "tool is required"})
+	}
+	command := resolveToolCommandWithArgs(toolName, body.Command, body.CommandArgs)
+	if command == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "command is required"})
+	}
+	workdir, err := s.tenantWorkdir(c, body.Workdir)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	resp, err := client.ReadWorkspaceFile(&daemonv1.ReadWorkspaceFileRequest{
-		WorkspaceId: strings.TrimSpace(body.WorkspaceID),
-		Path:        strings.TrimSpace(body.Path),
-	})
+	proxyMode, proxyURL, err := resolveToolProxyConfig("", "", body.ProxyMode, body.ProxyURL)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, resp)
+	metadata := cloneMap(body.Metadata)
+	metadata = withToolProxyMetadata(metadata, proxyMode, proxyURL)
+	metadata["user_command"] = command
+	metadata["user_args"] = strings.TrimSpace(body.CommandArgs)
+
+	spawn := toolSessionSpawn{
+		Owner:            s.currentUsername(c),
+		Tool:             toolName,
+		Title:            strings.TrimSpace(body.Title),
+		Command:          command,
+		Workdir:          workdir,
+		Metadata:         metadata,
+		RuntimeTransport: body.RuntimeTransport,
+		ProxyMode:        proxyMode,
+		ProxyURL:         proxyURL,
+		AccessMode:       strings.TrimSpace(body.AccessMode),
+		AccessPassword:   body.AccessPassword,
+		AccessBaseURL:    s.toolSessionAccessBase(c, body.PublicBaseURL),
+	}
+	if s.config.ToolSessions.RequireApproval {
+		return s.requestToolSessionSpawnApproval(c, spawn)
+	}
+	status, payload := s.launchToolSession(s.spawnRequestContext(c), spawn)
+	return c.JSON(status, payload)
 }
 
-func (s *Server) handleSendAgentDirectMessage(c *echo.Context) error {
-	agentID := strings.TrimSpace(c.Param("agent_id"))
-	if agentID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "agent_id is required"})
+// maxChatSeedBytes caps the total size of files seeded from a chat.
+const maxChatSeedBytes = 5 << 20
+
+// chatSeedFile is one file written into a chat-seeded tool session workdir.
+type chatSeedFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// handleSpawnToolSessionFromChat writes code blocks from the caller's chat and
+// any explicitly selected files into a fresh workdir, then spawns a tool
+// session there.
+func (s *Server) handleSpawnToolSessionFromChat(c *echo.Context) error {
+	if s.toolSess == nil || s.processMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
 	}
 
 	var body struct {
-		Content          string   `json:"content"`
-		AttachmentIds    []string `json:"attachment_ids"`
-		ReplyToMessageId string   `json:"reply_to_message_id"`
-		RequestId        string   `json:"request_id"`
+		Tool             string         `json:"tool"`
+		Title            string         `json:"title"`
+		Command          string         `json:"command"`
+		CommandArgs      string         `json:"command_args"`
+		RuntimeTransport string         `json:"runtime_transport"`
+		RuntimeID        string         `json:"runtime_id"`
+		MessageIndexes   []int          `json:"message_indexes"`
+		Files            []chatSeedFile `json:"files"`
+		AccessMode       string         `json:"access_mode"`
+		AccessPassword   string         `json:"access_password"`
+		ProxyMode        string         `json:"proxy_mode"`
+		ProxyURL         string         `json:"proxy_url"`
+		PublicBaseURL    string         `json:"public_base_url"`
 	}
 	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
 
-	if strings.TrimSpace(body.Content) == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message content cannot be empty"})
+	toolName := strings.TrimSpace(body.Tool)
+	if toolName == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "tool is required"})
 	}
-
-	if strings.TrimSpace(body.RequestId) == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "request_id is required"})
+	command := resolveToolCommandWithArgs(toolName, body.Command, body.CommandArgs)
+	if command == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "command is required"})
+	}
+	proxyMode, proxyURL, err := resolveToolProxyConfig("", "", body.ProxyMode, body.ProxyURL)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	// Get daemon inventory to find which machine has this agent
-	snapshot, err := daemonhost.NewRegistry(s.kvStore).Snapshot(c.Request().Context())
+	owner := s.currentUsername(c)
+	chatSessionID := s.chatSessionID(c.Request().Context(), owner, body.RuntimeID)
+	files, err := s.collectChatSeedFiles(chatSessionID, body.MessageIndexes, body.Files)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get daemon inventory"})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if len(files) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "no code blocks or files selected"})
 	}
 
-	// Find the machine that has this agent
-	// First try to find in agents list (for future compatibility)
-	var targetMachineID string
-	for machineID, inventory := range snapshot.Inventories {
-		if inventory == nil || inventory.Agents == nil {
-			continue
+	workdir := filepath.Join(s.config.TenantWorkspacePath(s.currentTenantSlug(c)), "tool-workdirs", "chat-"+time.Now().Format("20060102-150405")+"-"+uuid.NewString()[:8])
+	if _, err := chatSeedPaths(files); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	metadata := withToolProxyMetadata(map[string]interface{}{}, proxyMode, proxyURL)
+	metadata["user_command"] = command
+	metadata["user_args"] = strings.TrimSpace(body.CommandArgs)
+	metadata["seeded_from_chat"] = chatSessionID
+
+	spawn := toolSessionSpawn{
+		Owner:            owner,
+		Tool:             toolName,
+		Title:            strings.TrimSpace(body.Title),
+		Command:          command,
+		Workdir:          workdir,
+		Seed:             files,
+		Metadata:         metadata,
+		RuntimeTransport: body.RuntimeTransport,
+		ProxyMode:        proxyMode,
+		ProxyURL:         proxyURL,
+		AccessMode:       strings.TrimSpace(body.AccessMode),
+		AccessPassword:   body.AccessPassword,
+		AccessBaseURL:    s.toolSessionAccessBase(c, body.PublicBaseURL),
+	}
+	if s.config.ToolSessions.RequireApproval {
+		return s.requestToolSessionSpawnApproval(c, spawn)
+	}
+	status, payload := s.launchToolSession(s.spawnRequestContext(c), spawn)
+	return c.JSON(status, payload)
+}
+
+// collectChatSeedFiles gathers the code blocks of the selected chat messages
+// followed by the explicit files. Unnamed blocks become snippet-N files; a
+// later file with the same path replaces an earlier one.
+func (s *Server) collectChatSeedFiles(chatSessionID string, indexes []int, explicit []chatSeedFile) ([]chatSeedFile, error) {
+	var files []chatSeedFile
+	if len(indexes) > 0 {
+		if s.sessionMgr == nil {
+			return nil, fmt.Errorf("session manager not available")
+		}
+		sess, err := s.sessionMgr.GetExisting(chatSessionID)
+		if err != nil {
+			return nil, fmt.Errorf("chat session not found")
 		}
-		for _, agent := range inventory.Agents {
-			if agent != nil && agent.AgentId == agentID {
-				targetMachineID = machineID
-				break
-			}
-		}
-		if targetMachineID != "" {
-			break
-		}
-	}
-
-	// Fallback: check if agent_id matches a runtime ID (current system uses runtime IDs as agent IDs)
-	if targetMachineID == "" {
-		for machineID, inventory := range snapshot.Inventories {
-			if inventory == nil || inventory.Runtimes == nil {
-				continue
+		messages := sess.GetMessages()
+		snippet := 0
+		for _, index := range indexes {
+			if index < 0 || index >= len(messages) {
+				return nil, fmt.Errorf("message index %d out of range", index)
 			}
-			for _, runtime := range inventory.Runtimes {
-				if runtime != nil && runtime.RuntimeId == agentID {
-					targetMachineID = machineID
-					break
+			for _, block := range richtext.ExtractCodeBlocks(messages[index].Content) {
+				name := block.Filename
+				if name == "" {
+					snippet++
+					name = fmt.Sprintf("snippet-%d%s", snippet, codeBlockExtension(block.Language))
 				}
-			}
-			if targetMachineID != "" {
-				break
+				files = append(files, chatSeedFile{Path: name, Content: block.Content})
 			}
 		}
 	}
+	return append(files, explicit...), nil
+}
 
-	if targetMachineID == "" {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": "agent not found in any daemon"})
+// chatSeedPaths checks files and returns their relative paths in order,
+// without duplicates. Paths must stay inside the workdir.
+func chatSeedPaths(files []chatSeedFile) ([]string, error) {
+	total := 0
+	seen := make(map[string]bool, len(files))
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		rel := filepath.Clean(filepath.FromSlash(strings.TrimSpace(file.Path)))
+		if rel == "." || filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("invalid file path %q", file.Path)
+		}
+		total += len(file.Content)
+		if total > maxChatSeedBytes {
+			return nil, fmt.Errorf("selected files exceed %d bytes", maxChatSeedBytes)
+		}
+		if slashed := filepath.ToSlash(rel); !seen[slashed] {
+			seen[slashed] = true
+			paths = append(paths, slashed)
+		}
 	}
+	return paths, nil
+}
 
-	// Get daemon client for the target machine
-	client, _, err := s.daemonClientForMachine(c.Request().Context(), targetMachineID)
+// writeChatSeedFiles writes files below root and returns their relative paths
+// in order. Paths must stay inside root.
+func writeChatSeedFiles(root string, files []chatSeedFile) ([]string, error) {
+	paths, err := chatSeedPaths(files)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return nil, err
 	}
-
-	// Call SendMessage RPC with dm:@agent_id target
-	resp, err := client.SendMessageRemote(&daemonv1.SendMessageRequest{
-		Target:           fmt.Sprintf("dm:@%s", agentID),
-		Content:          strings.TrimSpace(body.Content),
-		AttachmentIds:    body.AttachmentIds,
-		ReplyToMessageId: strings.TrimSpace(body.ReplyToMessageId),
-		RequestId:        strings.TrimSpace(body.RequestId),
-	})
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	for _, file := range files {
+		rel := filepath.Clean(filepath.FromSlash(strings.TrimSpace(file.Path)))
+		target := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return nil, fmt.Errorf("create directory for %s: %w", rel, err)
+		}
+		if err := os.WriteFile(target, []byte(file.Content), 0o644); err != nil {
+			return nil, fmt.Errorf("write %s: %w", rel, err)
+		}
+	}
+	return paths, nil
+}
+
+// codeBlockExtension maps a fence language to a file extension.
+func codeBlockExtension(language string) string {
+	switch language {
+	case "go", "golang":
+		return ".go"
+	case "python", "py":
+		return ".py"
+	case "javascript", "js":
+		return ".js"
+	case "typescript", "ts":
+		return ".ts"
+	case "tsx":
+		return ".tsx"
+	case "jsx":
+		return ".jsx"
+	case "rust", "rs":
+		return ".rs"
+	case "java":
+		return ".java"
+	case "c":
+		return ".c"
+	case "cpp", "c++":
+		return ".cpp"
+	case "ruby", "rb":
+		return ".rb"
+	case "sh", "bash", "shell", "zsh":
+		return ".sh"
+	case "json":
+		return ".json"
+	case "yaml", "yml":
+		return ".yaml"
+	case "toml":
+		return ".toml"
+	case "sql":
+		return ".sql"
+	case "html":
+		return ".html"
+	case "css":
+		return ".css"
+	case "markdown", "md":
+		return ".md"
+	case "diff", "patch":
+		return ".diff"
+	default:
+		return ".txt"
 	}
-
-	// Map response to frontend shape
-	return c.JSON(http.StatusOK, map[string]any{
-		"message_id": resp.Message.MessageId,
-		"agent_id":   agentID,
-		"content":    body.Content,
-		"created_at": time.Now().Format(time.RFC3339),
-		"target":     fmt.Sprintf("dm:@%s", agentID),
-	})
 }
 
-func (s *Server) handleGetDaemonAttachment(c *echo.Context) error {
-	attachmentID := strings.TrimSpace(c.Param("attachment_id"))
-	if attachmentID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "attachment_id is required"})
-	}
-	target := strings.TrimSpace(c.QueryParam("target"))
-	if target == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "target is required"})
+// Kinds of toolSessionSpawn other than starting a new session.
+const (
+	toolSessionSpawnRestart = "restart"
+	toolSessionSpawnUpdate  = "update"
+)
+
+// toolSessionSpawn is a validated request to start a tool-session process:
+// a new session, or a restart or command change of an existing one. Gated
+// spawns are stored with their approval request and run once approved.
+type toolSessionSpawn struct {
+	// Kind is empty for a new session, or toolSessionSpawnRestart or
+	// toolSessionSpawnUpdate of SessionID.
+	Kind             string                 `json:"kind,omitempty"`
+	SessionID        string                 `json:"session_id,omitempty"`
+	Owner            string                 `json:"owner"`
+	Tool             string                 `json:"tool"`
+	Title            string                 `json:"title,omitempty"`
+	Command          string                 `json:"command"`
+	Workdir          string                 `json:"workdir"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	RuntimeTransport string                 `json:"runtime_transport,omitempty"`
+	ProxyMode        string                 `json:"proxy_mode,omitempty"`
+	ProxyURL         string                 `json:"proxy_url,omitempty"`
+	AccessMode       string                 `json:"access_mode,omitempty"`
+	// AccessPassword is never stored with an approval request; approved
+	// spawns get a generated password.
+	AccessPassword string `json:"-"`
+	// AccessBaseURL is where access links point, resolved from the request
+	// that asked for the spawn.
+	AccessBaseURL string `json:"access_base_url,omitempty"`
+	// Seed holds chat files written into Workdir when the spawn runs, so
+	// a denied or expired spawn leaves nothing behind.
+	Seed []chatSeedFile `json:"seed,omitempty"`
+	// Requester is who asked for the spawn; approved spawns run as them.
+	Requester ownership.AuthContext `json:"requester"`
+}
+
+// launchToolSession writes the spawn's seed files, then creates the tool
+// session and starts its process. It returns the HTTP status and response
+// body for the spawn; a failed launch removes the seeded workdir.
+func (s *Server) launchToolSession(ctx context.Context, spawn toolSessionSpawn) (int, map[string]interface{}) {
+	if len(spawn.Seed) == 0 {
+		return s.startToolSession(ctx, spawn)
+	}
+	paths, err := writeChatSeedFiles(spawn.Workdir, spawn.Seed)
+	if err != nil {
+		_ = os.RemoveAll(spawn.Workdir)
+		return http.StatusBadRequest, map[string]interface{}{"error": err.Error()}
+	}
+	spawn.Metadata = cloneMap(spawn.Metadata)
+	spawn.Metadata["seeded_files"] = paths
+	status, payload := s.startToolSession(ctx, spawn)
+	if status >= http.StatusBadRequest {
+		_ = os.RemoveAll(spawn.Workdir)
+	} else {
+		payload["seeded_files"] = paths
 	}
-	resp, err := s.collaborationFacade().GetAttachment(c.Request().Context(), &daemonv1.GetAttachmentRequest{
-		AttachmentId: attachmentID,
-		Target:       target,
+	return status, payload
+}
+
+// startToolSession creates the tool session and starts its process.
+func (s *Server) startToolSession(ctx context.Context, spawn toolSessionSpawn) (int, map[string]interface{}) {
+	metadata := spawn.Metadata
+	command := spawn.Command
+	workdir := spawn.Workdir
+	proxyMode := spawn.ProxyMode
+	transport := s.resolveSessionRuntimeTransport(metadata, spawn.RuntimeTransport)
+
+	sess, err := s.toolSess.CreateSession(ctx, toolsessions.CreateSessionInput{
+		Owner:    spawn.Owner,
+		Source:   toolsessions.SourceWebUI,
+		Tool:     spawn.Tool,
+		Title:    spawn.Title,
+		Command:  command,
+		Workdir:  workdir,
+		State:    toolsessions.StateRunning,
+		Metadata: metadata,
 	})
 	if err != nil {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		return http.StatusBadRequest, map[string]interface{}{"error": err.Error()}
 	}
-	record := resp.GetAttachment()
-	content := resp.GetContent()
-	mimeType := strings.TrimSpace(record.GetMimeType())
-	if mimeType == "" {
-		mimeType = "application/octet-stream"
+
+	launchCommand := applyToolProxyToCommand(command, proxyMode, spawn.ProxyURL)
+	runtimeSession := ""
+	if wrapped, sessionName := buildToolRuntimeLaunchWithTransport(transport, launchCommand, sess.ID); sessionName != "" {
+		launchCommand = wrapped
+		runtimeSession = sessionName
+		metadata = runtimeagents.ApplyLaunchMetadata(metadata, runtimeagents.LaunchInfo{
+			TransportName: transport.Name(),
+			SessionName:   runtimeSession,
+			LaunchCommand: launchCommand,
+		})
 	}
-	filename := strings.TrimSpace(record.GetFilename())
-	if filename != "" {
-		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", filename))
+	metadata[runtimeagents.MetadataLaunchCommand] = launchCommand
+	if err := s.toolSess.UpdateSessionMetadata(ctx, sess.ID, metadata); err != nil {
+		if terminateErr := s.toolSess.TerminateSession(context.Background(), sess.ID, "failed to persist launch metadata: "+err.Error()); terminateErr != nil {
+			s.logger.Warn("Failed to terminate tool session after launch metadata error",
+				zap.String("session_id", sess.ID),
+				zap.Error(terminateErr),
+			)
+		}
+		return http.StatusInternalServerError, map[string]interface{}{"error": "failed to persist tool session metadata: " + err.Error()}
+	}
+	sess, err = s.toolSess.GetSession(ctx, sess.ID)
+	if err != nil {
+		return http.StatusInternalServerError, map[string]interface{}{"error": "failed to reload tool session: " + err.Error()}
 	}
-	return c.Blob(http.StatusOK, mimeType, content)
-}
 
-func (s *Server) collaborationFacade() *gateway.Server {
-	var eventMgr *eventlog.Manager
-	var idemStore *idempotency.Store
-	if s.entClient != nil {
-		if mgr, err := eventlog.NewManager(s.entClient); err == nil {
-			eventMgr = mgr
+	spec := execenv.StartSpecFromContext(ctx, sess.ID, launchCommand, workdir, metadata)
+	if err := s.processMgr.StartWithSpec(context.Background(), spec); err != nil {
+		if terminateErr := s.toolSess.TerminateSession(context.Background(), sess.ID, "failed to start process: "+err.Error()); terminateErr != nil {
+			s.logger.Warn("Failed to terminate tool session after start error",
+				zap.String("session_id", sess.ID),
+				zap.Error(terminateErr),
+			)
 		}
-		idemStore = idempotency.NewStore(s.entClient)
+		return http.StatusBadRequest, map[string]interface{}{"error": "failed to start tool process: " + err.Error()}
 	}
-	return gateway.NewCollaborationFacade(s.sessionMgr, s.kvStore, eventMgr, idemStore)
-}
-
-func (s *Server) handleSaveDaemonMessage(c *echo.Context) error {
-	messageID := strings.TrimSpace(c.Param("message_id"))
-	if messageID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message_id is required"})
+	accessMode := spawn.AccessMode
+	accessPassword := ""
+	if accessMode != "" && accessMode != toolsessions.AccessModeNone {
+		accessPassword, err = s.toolSess.ConfigureSessionAccess(ctx, sess.ID, accessMode, spawn.AccessPassword)
+		if err != nil {
+			return http.StatusBadRequest, map[string]interface{}{"error": "failed to configure session access: " + err.Error()}
+		}
+		sess, err = s.toolSess.GetSession(ctx, sess.ID)
+		if err != nil {
+			return http.StatusInternalServerError, map[string]interface{}{"error": "failed to reload tool session: " + err.Error()}
+		}
 	}
-	var body struct {
-		Target    string `json:"target"`
-		RequestID string `json:"request_id"`
+	accessURL := ""
+	if strings.TrimSpace(sess.AccessMode) != "" && sess.AccessMode != toolsessions.AccessModeNone {
+		accessURL = toolSessionAccessURL(spawn.AccessBaseURL, sess.ID)
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	sess, err = s.toolSess.GetSession(ctx, sess.ID)
+	if err != nil {
+		return http.StatusInternalServerError, map[string]interface{}{"error": "failed to reload tool session: " + err.Error()}
 	}
-	userID := firstNonEmptyString(s.currentUserID(c), s.currentUsername(c))
-	if strings.TrimSpace(userID) == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "user identity is required"})
+
+	eventPayload := runtimeagents.ApplyRuntimeSessionPayload(map[string]interface{}{
+		"command":    command,
+		"launch_cmd": launchCommand,
+		"workdir":    workdir,
+		"proxy_mode": proxyMode,
+	}, transport.Name(), runtimeSession)
+	eventPayload[runtimeagents.MetadataRuntimeTransport] = strings.TrimSpace(transport.Name())
+	if err := s.toolSess.AppendEvent(context.Background(), sess.ID, "process_started", eventPayload); err != nil {
+		s.logger.Warn("Failed to append tool session start event",
+			zap.String("session_id", sess.ID),
+			zap.Error(err),
+		)
 	}
-	resp, err := s.collaborationFacade().SaveMessage(c.Request().Context(), &daemonv1.SaveMessageRequest{
-		Target:        strings.TrimSpace(body.Target),
-		MessageId:     messageID,
-		SavedByUserId: userID,
-		RequestId:     strings.TrimSpace(body.RequestID),
-	})
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	return http.StatusCreated, map[string]interface{}{
+		"session":         sess,
+		"access_mode":     sess.AccessMode,
+		"access_url":      accessURL,
+		"access_password": accessPassword,
 	}
-	return c.JSON(http.StatusOK, resp)
 }
 
-func (s *Server) handleUnsaveDaemonMessage(c *echo.Context) error {
-	messageID := strings.TrimSpace(c.Param("message_id"))
-	if messageID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message_id is required"})
+// spawnRequestContext is the context an ungated spawn runs with: the
+// request's, carrying the caller's identity as approved spawns do.
+func (s *Server) spawnRequestContext(c *echo.Context) context.Context {
+	return ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+}
+
+// toolSessionSpawnApprovalTool is the approval request tool name for gated spawns.
+const toolSessionSpawnApprovalTool = "tool_session_spawn"
+
+// maxToolSessionSpawnResults bounds the launch results kept for requesters.
+const maxToolSessionSpawnResults = 256
+
+// toolSessionSpawnResult is the outcome of a decided spawn request. Only
+// the requester receives it, since it carries the session's access
+// password.
+type toolSessionSpawnResult struct {
+	RequestID      string                `json:"request_id"`
+	Kind           string                `json:"kind,omitempty"`
+	Status         approval.Decision     `json:"status"`
+	DecidedBy      string                `json:"decided_by,omitempty"`
+	Reason         string                `json:"reason,omitempty"`
+	Session        *toolsessions.Session `json:"session,omitempty"`
+	AccessMode     string                `json:"access_mode,omitempty"`
+	AccessURL      string                `json:"access_url,omitempty"`
+	AccessPassword string                `json:"access_password,omitempty"`
+	Error          string                `json:"error,omitempty"`
+	owner          string
+}
+
+// requestToolSessionSpawnApproval queues the spawn for admin approval instead
+// of starting it. Nothing is created or executed until the request is
+// approved; the spawn is stored with the request so it survives restarts.
+func (s *Server) requestToolSessionSpawnApproval(c *echo.Context, spawn toolSessionSpawn) error {
+	if s.approval == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "approval manager not available"})
 	}
-	var body struct {
-		Target    string `json:"target"`
-		RequestID string `json:"request_id"`
+	requester := s.currentUsername(c)
+	spawn.Requester = s.authContextFromEcho(c)
+	stored, err := toolSessionSpawnArgument(spawn)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	args := map[string]interface{}{
+		"tool":                  spawn.Tool,
+		"command":               spawn.Command,
+		"workdir":               spawn.Workdir,
+		approval.ArgRequestedBy: requester,
+		"spawn":                 stored,
 	}
-	userID := firstNonEmptyString(s.currentUserID(c), s.currentUsername(c))
-	if strings.TrimSpace(userID) == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "user identity is required"})
+	if spawn.Kind != "" {
+		args["kind"] = spawn.Kind
+		args["session_id"] = spawn.SessionID
 	}
-	resp, err := s.collaborationFacade().UnsaveMessage(c.Request().Context(), &daemonv1.UnsaveMessageRequest{
-		Target:        strings.TrimSpace(body.Target),
-		MessageId:     messageID,
-		SavedByUserId: userID,
-		RequestId:     strings.TrimSpace(body.RequestID),
-	})
+	timeout := time.Duration(s.config.ToolSessions.ApprovalTimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	requestID, err := s.approval.EnqueueRequestWithTTL(toolSessionSpawnApprovalTool, args, "", timeout)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, resp)
-}
 
-func (s *Server) handleListDaemonSavedMessages(c *echo.Context) error {
-	userID := firstNonEmptyString(s.currentUserID(c), s.currentUsername(c))
-	if strings.TrimSpace(userID) == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "user identity is required"})
+	s.logger.Info("Tool session spawn awaiting approval",
+		zap.String("request_id", requestID),
+		zap.String("requested_by", requester),
+		zap.String("kind", spawn.Kind),
+		zap.String("tool", spawn.Tool),
+		zap.String("command", spawn.Command),
+	)
+	body := map[string]interface{}{
+		"status":     "pending",
+		"request_id": requestID,
 	}
-	resp, err := s.collaborationFacade().ListSavedMessages(c.Request().Context(), &daemonv1.ListSavedMessagesRequest{
-		Target:        strings.TrimSpace(c.QueryParam("target")),
-		SavedByUserId: userID,
-		Limit:         200,
-	})
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	if req, ok := s.approval.GetRequest(requestID); ok && req.ExpiresAt != nil {
+		body["expires_at"] = *req.ExpiresAt
 	}
-	return c.JSON(http.StatusOK, resp)
+	return c.JSON(http.StatusAccepted, body)
 }
 
-func (s *Server) handleListGoalRuns(c *echo.Context) error {
-	if s.goalSvc == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
+// registerApprovalHandlers lets approvals decided anywhere, through this
+// server, the gateway API or a chat channel, carry out spawn requests.
+func (s *Server) registerApprovalHandlers() {
+	if s.approval != nil {
+		s.approval.HandleDecided(toolSessionSpawnApprovalTool, s.handleToolSessionSpawnDecision)
 	}
-	items, err := s.goalSvc.ListGoalRuns(c.Request().Context())
+}
+
+// toolSessionSpawnArgument encodes spawn as a JSON object for the approval
+// request, in the form it takes after a round trip through the store.
+func toolSessionSpawnArgument(spawn toolSessionSpawn) (map[string]interface{}, error) {
+	raw, err := json.Marshal(spawn)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return nil, fmt.Errorf("encode tool session spawn: %w", err)
 	}
-	return c.JSON(http.StatusOK, map[string]any{"items": items})
+	var stored map[string]interface{}
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return nil, fmt.Errorf("encode tool session spawn: %w", err)
+	}
+	return stored, nil
 }
 
-func (s *Server) handleCreateGoalRun(c *echo.Context) error {
-	if s.goalSvc == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
-	}
-	var body struct {
-		Name                    string                     `json:"name"`
-		Goal                    string                     `json:"goal"`
-		NaturalLanguageCriteria string                     `json:"natural_language_criteria"`
-		RiskLevel               goaldriven.RiskLevel       `json:"risk_level"`
-		AllowAutoScope          bool                       `json:"allow_auto_scope"`
-		SelectedScope           *goaldriven.ExecutionScope `json:"selected_scope"`
+// toolSessionSpawnFromRequest decodes the spawn stored with req.
+func toolSessionSpawnFromRequest(req approval.Request) (toolSessionSpawn, error) {
+	var spawn toolSessionSpawn
+	stored, ok := req.Arguments["spawn"]
+	if !ok {
+		return spawn, fmt.Errorf("approval request %s has no tool session spawn", req.ID)
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	raw, err := json.Marshal(stored)
+	if err == nil {
+		err = json.Unmarshal(raw, &spawn)
 	}
-	result, err := s.goalSvc.CreateGoalRun(c.Request().Context(), goaldriven.CreateGoalRunInput{
-		Name:                    body.Name,
-		Goal:                    body.Goal,
-		NaturalLanguageCriteria: body.NaturalLanguageCriteria,
-		RiskLevel:               body.RiskLevel,
-		AllowAutoScope:          body.AllowAutoScope,
-		SelectedScope:           body.SelectedScope,
-		CreatedBy:               s.currentUsername(c),
-	})
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return spawn, fmt.Errorf("decode tool session spawn: %w", err)
 	}
-	return c.JSON(http.StatusOK, result)
+	return spawn, nil
 }
 
-func (s *Server) handleGetGoalRun(c *echo.Context) error {
-	if s.goalSvc == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
+// handleToolSessionSpawnDecision runs an approved spawn, or records a
+// denied one, whichever server or channel decided it. The result goes to
+// the requester, not to the admin who decided.
+func (s *Server) handleToolSessionSpawnDecision(req approval.Request) {
+	requester := req.RequestedBy()
+	result := toolSessionSpawnResult{
+		RequestID: req.ID,
+		Status:    req.Decision,
+		DecidedBy: req.DecidedBy,
+		Reason:    req.Reason,
+		owner:     requester,
 	}
-	detail, ok, err := s.goalSvc.GetGoalRunDetail(c.Request().Context(), strings.TrimSpace(c.Param("id")))
+	spawn, err := toolSessionSpawnFromRequest(req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		result.Error = err.Error()
+		s.auditToolSessionSpawn(req.ID, requester, spawn, req.DecidedBy, req.Decision, "", result.Error)
+		s.deliverToolSessionSpawnResult(result)
+		return
 	}
-	if !ok {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": "goal run not found"})
+	result.Kind = spawn.Kind
+	if req.Decision != approval.Approved {
+		s.auditToolSessionSpawn(req.ID, requester, spawn, req.DecidedBy, req.Decision, "", req.Reason)
+		s.deliverToolSessionSpawnResult(result)
+		return
 	}
-	return c.JSON(http.StatusOK, detail)
-}
 
-func (s *Server) handleConfirmGoalRunCriteria(c *echo.Context) error {
-	if s.goalSvc == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
+	spawn.Metadata = cloneMap(spawn.Metadata)
+	spawn.Metadata["spawn_approval"] = map[string]interface{}{
+		"request_id":   req.ID,
+		"requested_by": requester,
+		"approved_by":  req.DecidedBy,
+		"requested_at": req.CreatedAt.UTC().Format(time.RFC3339),
 	}
-	var body struct {
-		Criteria      goalcriteria.Set           `json:"criteria"`
-		SelectedScope *goaldriven.ExecutionScope `json:"selected_scope"`
+	// The spawn runs as the requester, not as whoever approved it.
+	ctx := ownership.WithAuthContext(context.Background(), spawn.Requester)
+	var payload map[string]interface{}
+	switch {
+	case s.toolSess == nil || s.processMgr == nil:
+		payload = map[string]interface{}{"error": "tool runtime not available"}
+	case spawn.Kind == toolSessionSpawnRestart:
+		_, payload = s.restartToolSession(ctx, spawn)
+	case spawn.Kind == toolSessionSpawnUpdate:
+		_, payload = s.updateToolSession(ctx, spawn)
+	default:
+		_, payload = s.launchToolSession(ctx, spawn)
+	}
+	result.Error, _ = payload["error"].(string)
+	result.Session, _ = payload["session"].(*toolsessions.Session)
+	result.AccessMode, _ = payload["access_mode"].(string)
+	result.AccessURL, _ = payload["access_url"].(string)
+	result.AccessPassword, _ = payload["access_password"].(string)
+	sessionID := spawn.SessionID
+	if result.Session != nil {
+		sessionID = result.Session.ID
+	}
+	s.auditToolSessionSpawn(req.ID, requester, spawn, req.DecidedBy, req.Decision, sessionID, result.Error)
+	s.deliverToolSessionSpawnResult(result)
+}
+
+// deliverToolSessionSpawnResult pushes result to the requester's approval
+// WebSockets and keeps it for them to fetch. The access password is handed
+// out once.
+func (s *Server) deliverToolSessionSpawnResult(result toolSessionSpawnResult) {
+	s.toolSpawnMu.Lock()
+	defer s.toolSpawnMu.Unlock()
+
+	delivered := false
+	for ch := range s.toolSpawnSubs[result.owner] {
+		select {
+		case ch <- result:
+			delivered = true
+		default:
+		}
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	if delivered {
+		result.AccessPassword = ""
 	}
-	run, err := s.goalSvc.ConfirmCriteria(c.Request().Context(), strings.TrimSpace(c.Param("id")), body.Criteria, body.SelectedScope)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	if s.toolSpawnResults == nil {
+		s.toolSpawnResults = make(map[string]toolSessionSpawnResult)
 	}
-	return c.JSON(http.StatusOK, map[string]any{"goal_run": run})
-}
-
-func (s *Server) handleStartGoalRun(c *echo.Context) error {
-	if s.goalSvc == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
+	if _, exists := s.toolSpawnResults[result.RequestID]; !exists {
+		s.toolSpawnOrder = append(s.toolSpawnOrder, result.RequestID)
 	}
-	run, err := s.goalSvc.StartGoalRun(c.Request().Context(), strings.TrimSpace(c.Param("id")))
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	s.toolSpawnResults[result.RequestID] = result
+	for len(s.toolSpawnOrder) > maxToolSessionSpawnResults {
+		delete(s.toolSpawnResults, s.toolSpawnOrder[0])
+		s.toolSpawnOrder = s.toolSpawnOrder[1:]
 	}
-	return c.JSON(http.StatusOK, map[string]any{"goal_run": run})
 }
 
-func (s *Server) handleStopGoalRun(c *echo.Context) error {
-	if s.goalSvc == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
-	}
-	run, err := s.goalSvc.StopGoalRun(c.Request().Context(), strings.TrimSpace(c.Param("id")))
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+// takeToolSessionSpawnResult returns the result of a decided spawn request
+// of owner, clearing its access password after the first read.
+func (s *Server) takeToolSessionSpawnResult(requestID, owner string) (toolSessionSpawnResult, bool) {
+	s.toolSpawnMu.Lock()
+	defer s.toolSpawnMu.Unlock()
+
+	result, ok := s.toolSpawnResults[requestID]
+	if !ok || result.owner != owner {
+		return toolSessionSpawnResult{}, false
 	}
-	return c.JSON(http.StatusOK, map[string]any{"goal_run": run})
+	cleared := result
+	cleared.AccessPassword = ""
+	s.toolSpawnResults[requestID] = cleared
+	return result, true
 }
 
-func (s *Server) handleCancelGoalRun(c *echo.Context) error {
-	if s.goalSvc == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
-	}
-	run, err := s.goalSvc.CancelGoalRun(c.Request().Context(), strings.TrimSpace(c.Param("id")))
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
-	}
-	return c.JSON(http.StatusOK, map[string]any{"goal_run": run})
+// toolSpawnResult returns the result of a decided spawn request as kept,
+// without handing out its access password.
+func (s *Server) toolSpawnResult(requestID string) (toolSessionSpawnResult, bool) {
+	s.toolSpawnMu.Lock()
+	defer s.toolSpawnMu.Unlock()
+	result, ok := s.toolSpawnResults[requestID]
+	return result, ok
 }
 
-func (s *Server) handleConfirmGoalRunManualCriterion(c *echo.Context) error {
-	if s.goalSvc == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
-	}
-	var body struct {
-		CriterionID string `json:"criterion_id"`
-		Approved    bool   `json:"approved"`
-		Note        string `json:"note"`
-	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+// subscribeToolSessionSpawnResults streams the spawn results of owner.
+func (s *Server) subscribeToolSessionSpawnResults(owner string) (<-chan toolSessionSpawnResult, func()) {
+	ch := make(chan toolSessionSpawnResult, 8)
+	s.toolSpawnMu.Lock()
+	if s.toolSpawnSubs == nil {
+		s.toolSpawnSubs = make(map[string]map[chan toolSessionSpawnResult]struct{})
 	}
-	run, err := s.goalSvc.ConfirmManualCriterion(
-		c.Request().Context(),
-		strings.TrimSpace(c.Param("id")),
-		body.CriterionID,
-		body.Note,
-		body.Approved,
-	)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	if s.toolSpawnSubs[owner] == nil {
+		s.toolSpawnSubs[owner] = make(map[chan toolSessionSpawnResult]struct{})
 	}
-	return c.JSON(http.StatusOK, map[string]any{"goal_run": run})
-}
+	s.toolSpawnSubs[owner][ch] = struct{}{}
+	s.toolSpawnMu.Unlock()
 
-func (s *Server) releaseVersion() string {
-	versionValue := strings.TrimSpace(version.GetVersion())
-	if versionValue == "" || versionValue == "dev" {
-		return "latest"
+	return ch, func() {
+		s.toolSpawnMu.Lock()
+		defer s.toolSpawnMu.Unlock()
+		if _, ok := s.toolSpawnSubs[owner][ch]; !ok {
+			return
+		}
+		delete(s.toolSpawnSubs[owner], ch)
+		if len(s.toolSpawnSubs[owner]) == 0 {
+			delete(s.toolSpawnSubs, owner)
+		}
+		close(ch)
 	}
-	return versionValue
-}
-
-func (s *Server) releaseBaseURL() string {
-	return "https://github.com/czyt/nekobot/releases/download/" + s.releaseVersion()
 }
 
-func normalizePlatformTarget(goos, goarch string) (string, string) {
-	allowedOS := map[string]struct{}{"linux": {}, "darwin": {}, "windows": {}}
-	allowedArch := map[string]struct{}{"amd64": {}, "arm64": {}}
-	if _, ok := allowedOS[goos]; !ok {
-		goos = runtime.GOOS
+// handleGetToolSessionSpawnRequest reports a spawn request to its
+// requester, with the launched session's access details once approved.
+func (s *Server) handleGetToolSessionSpawnRequest(c *echo.Context) error {
+	if s.approval == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "approval manager not available"})
 	}
-	if _, ok := allowedArch[goarch]; !ok {
-		goarch = runtime.GOARCH
-	}
-	if _, ok := allowedOS[goos]; !ok {
-		goos = "linux"
-	}
-	if _, ok := allowedArch[goarch]; !ok {
-		goarch = "amd64"
+	id := strings.TrimSpace(c.Param("id"))
+	username := s.currentUsername(c)
+	if result, ok := s.takeToolSessionSpawnResult(id, username); ok {
+		return c.JSON(http.StatusOK, result)
 	}
-	return goos, goarch
+	req, ok := s.approval.GetRequest(id)
+	if !ok || req.ToolName != toolSessionSpawnApprovalTool || req.RequestedBy() != username {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "spawn request not found"})
+	}
+	kind, _ := req.Arguments["kind"].(string)
+	return c.JSON(http.StatusOK, toolSessionSpawnResult{
+		RequestID: req.ID,
+		Kind:      kind,
+		Status:    req.Decision,
+		DecidedBy: req.DecidedBy,
+		Reason:    req.Reason,
+	})
 }
 
-func parseBootstrapTarget(c *echo.Context) (string, string) {
-	return normalizePlatformTarget(strings.TrimSpace(c.QueryParam("os")), strings.TrimSpace(c.QueryParam("arch")))
+// auditToolSessionSpawn records who requested which command and how the
+// request was decided.
+func (s *Server) auditToolSessionSpawn(
+	requestID string,
+	requester string,
+	spawn toolSessionSpawn,
+	decidedBy string,
+	decision approval.Decision,
+	sessionID string,
+	errMsg string,
+) {
+	s.logger.Info("Tool session spawn decided",
+		zap.String("request_id", requestID),
+		zap.String("decision", string(decision)),
+		zap.String("kind", spawn.Kind),
+		zap.String("requested_by", requester),
+		zap.String("decided_by", decidedBy),
+		zap.String("command", spawn.Command),
+		zap.String("session_id", sessionID),
+		zap.String("error", errMsg),
+	)
+	if s.auditLogger == nil {
+		return
+	}
+	s.auditLogger.Log(&audit.Entry{
+		Timestamp: time.Now(),
+		ToolName:  toolSessionSpawnApprovalTool,
+		Arguments: map[string]interface{}{
+			"request_id":   requestID,
+			"kind":         spawn.Kind,
+			"tool":         spawn.Tool,
+			"command":      spawn.Command,
+			"workdir":      spawn.Workdir,
+			"requested_by": requester,
+			"decided_by":   decidedBy,
+			"decision":     string(decision),
+		},
+		Success:   decision == approval.Approved && errMsg == "",
+		Error:     errMsg,
+		SessionID: sessionID,
+		Workspace: spawn.Workdir,
+	})
 }
 
-func detectArchiveName(goos, goarch, binary string) string {
-	platform := goos + "-" + goarch
-	if goos == "windows" {
-		return binary + "-" + platform + ".zip"
+func (s *Server) handleResolveExternalAgentSession(c *echo.Context) error {
+	if s.externalAgent == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "external agent manager not available"})
 	}
-	return binary + "-" + platform + ".tar.gz"
-}
 
-func (s *Server) handleGetDaemonBootstrap(c *echo.Context) error {
-	serverURL := strings.TrimSpace(s.config.WebUI.PublicBaseURL)
-	if serverURL == "" {
-		scheme := "http"
-		if c.Scheme() != "" {
-			scheme = c.Scheme()
-		}
-		serverURL = fmt.Sprintf("%s://%s", scheme, c.Request().Host)
+	var body struct {
+		AgentKind string `json:"agent_kind"`
+		Workspace string `json:"workspace"`
+		Tool      string `json:"tool"`
+		Title     string `json:"title"`
+		Command   string `json:"command"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	token := s.getDaemonToken()
-	machineName := strings.TrimSpace(s.currentUsername(c))
-	command := fmt.Sprintf("nekobot daemon run --server-url %s --token %s --machine-name %s", strconv.Quote(serverURL), strconv.Quote(token), strconv.Quote(machineName))
-	return c.JSON(http.StatusOK, map[string]any{
-		"server_url":   serverURL,
-		"daemon_token": token,
-		"command":      command,
-	})
-}
 
-func (s *Server) handleGetNekoClientdBootstrap(c *echo.Context) error {
-	serverURL := strings.TrimSpace(s.config.WebUI.PublicBaseURL)
-	if serverURL == "" {
-		scheme := "http"
-		if c.Scheme() != "" {
-			scheme = c.Scheme()
-		}
-		serverURL = fmt.Sprintf("%s://%s", scheme, c.Request().Host)
+	var probe externalAgentProcessProbe
+	var starter externalagent.ProcessStarter
+	if s.processMgr != nil {
+		probe.mgr = s.processMgr
+		starter = s.processMgr
 	}
-	token := s.getDaemonToken()
-	grpcTarget := strings.TrimPrefix(strings.TrimPrefix(serverURL, "https://"), "http://")
-	targetOS, targetArch := parseBootstrapTarget(c)
-	archiveName := detectArchiveName(targetOS, targetArch, "nekoclientd")
-	downloadBaseURL := s.releaseBaseURL()
-	downloadURL := strings.TrimRight(downloadBaseURL, "/") + "/" + archiveName
-	installCommand := fmt.Sprintf("curl -L %s -o /tmp/%s && mkdir -p /tmp/nekoclientd-extract && tar -xzf /tmp/%s -C /tmp/nekoclientd-extract && sudo install -m 0755 /tmp/nekoclientd-extract/nekoclientd /usr/local/bin/nekoclientd", strconv.Quote(downloadURL), archiveName, archiveName)
-	serviceInstallCommand := fmt.Sprintf("nekoclientd --server %s --token %s --machine-name %s install", strconv.Quote(grpcTarget), strconv.Quote(token), strconv.Quote(strings.TrimSpace(s.currentUsername(c))))
-	startCommand := fmt.Sprintf("nekoclientd --server %s --token %s --machine-name %s start", strconv.Quote(grpcTarget), strconv.Quote(token), strconv.Quote(strings.TrimSpace(s.currentUsername(c))))
-	return c.JSON(http.StatusOK, map[string]any{"server_url": serverURL, "daemon_token": token, "grpc_target": grpcTarget, "target_os": targetOS, "target_arch": targetArch, "binary_name": "nekoclientd", "version": s.releaseVersion(), "download_base_url": downloadBaseURL, "download_url": downloadURL, "archive_name": archiveName, "install_command": installCommand, "service_install_command": serviceInstallCommand, "start_command": startCommand})
+	result, err := externalagent.ExecuteResolveFlow(
+		c.Request().Context(),
+		s.externalAgent,
+		externalagent.NewResolveOrchestrator(s.config, s.logger, s.entClient, s.approval, s.taskStore),
+		s.config.WorkspacePath(),
+		probe,
+		starter,
+		s.toolSess,
+		runtimeagents.DefaultTransport(),
+		externalagent.SessionSpec{
+			Owner:     s.currentUsername(c),
+			AgentKind: body.AgentKind,
+			Workspace: body.Workspace,
+			Tool:      body.Tool,
+			Title:     body.Title,
+			Command:   body.Command,
+		},
+	)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(result.HTTPStatus(), result.ResponseBody())
 }
 
-func (s *Server) handleRegisterDaemon(c *echo.Context) error {
-	if !s.authorizeDaemonRequest(c) {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid daemon token"})
-	}
-	if s.kvStore == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "daemon registry unavailable"})
+func (s *Server) handleGetExternalAgentCatalog(c *echo.Context) error {
+	registry := externalagent.NewRegistry()
+	type adapterPayload struct {
+		Kind            string   `json:"kind"`
+		Tool            string   `json:"tool"`
+		Command         string   `json:"command"`
+		SupportsInstall bool     `json:"supports_install"`
+		InstallHint     []string `json:"install_hint,omitempty"`
 	}
-	var req daemonv1.RegisterComputerRequest
-	if err := daemonhost.DecodeProtoJSON(c.Request(), &req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	items := make([]adapterPayload, 0, len(registry.List()))
+	for _, adapter := range registry.List() {
+		payload := adapterPayload{
+			Kind:            adapter.Kind(),
+			Tool:            adapter.Tool(),
+			Command:         adapter.Command(),
+			SupportsInstall: adapter.SupportsAutoInstall(),
+		}
+		if adapter.SupportsAutoInstall() {
+			payload.InstallHint = adapter.InstallCommand(runtime.GOOS)
+		}
+		items = append(items, payload)
 	}
-	resp, err := daemonhost.NewRegistry(s.kvStore).Register(c.Request().Context(), &req)
+	installed, err := externalagent.DetectInstalled("")
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, resp)
+	return c.JSON(http.StatusOK, map[string]any{
+		"adapters":  items,
+		"installed": installed,
+	})
 }
 
-func (s *Server) handleHeartbeatDaemon(c *echo.Context) error {
-	if !s.authorizeDaemonRequest(c) {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid daemon token"})
-	}
+func (s *Server) handleGetDaemonRegistry(c *echo.Context) error {
 	if s.kvStore == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "daemon registry unavailable"})
-	}
-	var req daemonv1.HeartbeatComputerRequest
-	if err := daemonhost.DecodeProtoJSON(c.Request(), &req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return c.JSON(http.StatusOK, map[string]any{
+			"machines": []daemonhost.MachineStatus{},
+		})
 	}
-	resp, err := daemonhost.NewRegistry(s.kvStore).Heartbeat(c.Request().Context(), &req)
+	snapshot, err := daemonhost.NewRegistry(s.kvStore).Snapshot(c.Request().Context())
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, resp)
+	return c.JSON(http.StatusOK, map[string]any{
+		"machines": daemonhost.MachineStatuses(snapshot),
+	})
 }
 
-func (s *Server) handleFetchDaemonRuns(c *echo.Context) error {
-	if !s.authorizeDaemonRequest(c) {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid daemon token"})
-	}
-	if s.agent == nil || s.agent.TaskService() == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "task service unavailable"})
-	}
-	var req daemonv1.FetchAssignedRunsRequest
-	if err := daemonhost.DecodeProtoJSON(c.Request(), &req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
-	}
-	resp := daemonhost.BuildAssignedRuns(s.agent.TaskService(), req.ComputerId, req.AgentIds, int(req.Limit))
-	return c.JSON(http.StatusOK, resp)
-}
+// handleGetDaemonInventory returns a two-level computer→agent inventory view
+// that merges daemon registry data (machines + per-runtime details) with agent
+// profile definitions (provider, model, skills, env).
+func (s *Server) handleGetDaemonInventory(c *echo.Context) error {
+	ctx := c.Request().Context()
 
-func (s *Server) handleUpdateDaemonRunStatus(c *echo.Context) error {
-	if !s.authorizeDaemonRequest(c) {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid daemon token"})
+	type runtimeDetail struct {
+		RuntimeID        string   `json:"runtime_id"`
+		MachineID        string   `json:"computer_id"`
+		WorkspaceID      string   `json:"workspace_id"`
+		Kind             string   `json:"kind"`
+		DisplayName      string   `json:"display_name"`
+		Tool             string   `json:"tool"`
+		Command          string   `json:"command"`
+		Installed        bool     `json:"installed"`
+		Healthy          bool     `json:"healthy"`
+		CurrentTaskCount uint32   `json:"current_task_count"`
+		PendingTaskCount uint32   `json:"pending_task_count"`
+		Provider         string   `json:"provider,omitempty"`
+		Model            string   `json:"model,omitempty"`
+		Enabled          bool     `json:"enabled"`
+		SkillNames       []string `json:"skill_names,omitempty"`
+		EnvCount         int      `json:"env_count"`
 	}
-	if s.agent == nil || s.agent.TaskService() == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "task service unavailable"})
+
+	type machineInventory struct {
+		Info                  *daemonv1.ComputerInfo `json:"info"`
+		Status                string                 `json:"status"`
+		WorkspaceCount        int                    `json:"workspace_count"`
+		RuntimeCount          int                    `json:"runtime_count"`
+		InstalledRuntimeCount int                    `json:"installed_runtime_count"`
+		HealthyRuntimeCount   int                    `json:"healthy_runtime_count"`
+		GoalRunRunnable       bool                   `json:"goal_run_runnable"`
+		Runtimes              []runtimeDetail        `json:"runtimes"`
 	}
-	var req daemonv1.UpdateRunStatusRequest
-	if err := daemonhost.DecodeProtoJSON(c.Request(), &req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+
+	emptyResponse := map[string]any{"machines": []machineInventory{}}
+	if s.kvStore == nil {
+		return c.JSON(http.StatusOK, emptyResponse)
 	}
-	resp, task, err := daemonhost.ApplyRunStatusUpdate(s.agent.TaskService(), &req)
+
+	snapshot, err := daemonhost.NewRegistry(s.kvStore).Snapshot(ctx)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
-	}
-	if err := s.appendDaemonTaskSessionUpdate(c.Request().Context(), task, &req); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, resp)
-}
 
-func (s *Server) appendDaemonTaskSessionUpdate(ctx context.Context, task tasks.Task, req *daemonv1.UpdateRunStatusRequest) error {
-	if s == nil || s.sessionMgr == nil || req == nil {
-		return nil
+	// Build agent profile lookup by runtime_id.
+	type profileInfo struct {
+		Provider   string
+		Model      string
+		Enabled    bool
+		SkillNames []string
+		EnvCount   int
 	}
-	sessionID := strings.TrimSpace(task.SessionID)
-	if sessionID == "" {
-		return nil
+	profileByRuntime := map[string]profileInfo{}
+	if s.runtimeMgr != nil {
+		if items, listErr := s.runtimeMgr.List(ctx); listErr == nil {
+			envByRuntime := map[string]int{}
+			if s.kvStore != nil {
+				if raw, ok, _ := s.kvStore.Get(ctx, "daemon.collaboration.agent_env.v1"); ok {
+					if envMap, castOK := raw.(map[string]interface{}); castOK {
+						for rtID, varsRaw := range envMap {
+							if varsSlice, sliceOK := varsRaw.([]interface{}); sliceOK {
+								envByRuntime[rtID] = len(varsSlice)
+							}
+						}
+					}
+				}
+			}
+			for _, item := range items {
+				skillNames := make([]string, 0, len(item.Skills))
+				skillNames = append(skillNames, item.Skills...)
+				profileByRuntime[item.ID] = profileInfo{
+					Provider:   item.Provider,
+					Model:      item.Model,
+					Enabled:    item.Enabled,
+					SkillNames: skillNames,
+					EnvCount:   envByRuntime[item.ID],
+				}
+			}
+		}
 	}
 
-	state := strings.TrimSpace(req.State)
-	messageRole := "system"
-	messageContent := ""
-	switch state {
-	case string(tasks.StateClaimed):
-		messageContent = fmt.Sprintf("Daemon task claimed.\nTask ID: %s", strings.TrimSpace(task.ID))
-	case string(tasks.StateRunning):
-		messageContent = fmt.Sprintf("Daemon task started.\nTask ID: %s", strings.TrimSpace(task.ID))
-	case string(tasks.StateRequiresAction):
-		reason := strings.TrimSpace(req.BlockedReason)
-		if reason == "" {
-			reason = strings.TrimSpace(task.PendingAction)
-		}
-		if reason == "" {
-			reason = "Daemon task requires action."
-		}
-		messageContent = reason
-	case string(tasks.StateCompleted):
-		messageRole = "assistant"
-		messageContent = strings.TrimSpace(req.ResultMessage)
-		if messageContent == "" {
-			messageContent = fmt.Sprintf("Daemon task completed.\nTask ID: %s", strings.TrimSpace(task.ID))
-		}
-	case string(tasks.StateFailed):
-		reason := strings.TrimSpace(req.Error)
-		if reason == "" {
-			reason = strings.TrimSpace(task.LastError)
+	// Build per-machine inventory with runtime details.
+	machineIDs := make([]string, 0, len(snapshot.Machines))
+	for id := range snapshot.Machines {
+		machineIDs = append(machineIDs, id)
+	}
+	sort.Strings(machineIDs)
+	now := time.Now()
+	machines := make([]machineInventory, 0, len(machineIDs))
+	for _, id := range machineIDs {
+		info := snapshot.Machines[id]
+		if info == nil {
+			continue
 		}
-		messageContent = strings.TrimSpace(req.ResultMessage)
-		if messageContent == "" {
-			messageContent = "Daemon task failed."
+		cloned := proto.Clone(info).(*daemonv1.ComputerInfo)
+		cloned.Status = daemonhost.DeriveMachineStatus(info, now)
+		mi := machineInventory{
+			Info:   cloned,
+			Status: cloned.Status,
 		}
-		if reason != "" {
-			messageContent = fmt.Sprintf("%s\nError: %s", messageContent, reason)
+		if inv, ok := snapshot.Inventories[id]; ok && inv != nil {
+			mi.WorkspaceCount = len(inv.Workspaces)
+			mi.RuntimeCount = len(inv.Runtimes)
+			mi.Runtimes = make([]runtimeDetail, 0, len(inv.Runtimes))
+			for _, rt := range inv.Runtimes {
+				if rt == nil {
+					continue
+				}
+				if rt.Installed {
+					mi.InstalledRuntimeCount++
+				}
+				if rt.Installed && rt.Healthy {
+					mi.HealthyRuntimeCount++
+				}
+				rd := runtimeDetail{
+					RuntimeID:        rt.RuntimeId,
+					MachineID:        rt.ComputerId,
+					WorkspaceID:      rt.WorkspaceId,
+					Kind:             rt.Kind,
+					DisplayName:      rt.DisplayName,
+					Tool:             rt.Tool,
+					Command:          rt.Command,
+					Installed:        rt.Installed,
+					Healthy:          rt.Healthy,
+					CurrentTaskCount: rt.CurrentTaskCount,
+					PendingTaskCount: rt.PendingTaskCount,
+				}
+				if p, ok := profileByRuntime[rt.RuntimeId]; ok {
+					rd.Provider = p.Provider
+					rd.Model = p.Model
+					rd.Enabled = p.Enabled
+					rd.SkillNames = p.SkillNames
+					rd.EnvCount = p.EnvCount
+				}
+				mi.Runtimes = append(mi.Runtimes, rd)
+			}
+			_, _, mi.GoalRunRunnable = daemonhost.SelectRunnableWorkspaceRuntime(inv)
 		}
-	case string(tasks.StateCanceled):
-		messageContent = fmt.Sprintf("Daemon task canceled.\nTask ID: %s", strings.TrimSpace(task.ID))
-	default:
-		return nil
-	}
-	if strings.TrimSpace(messageContent) == "" {
-		return nil
+		machines = append(machines, mi)
 	}
+	return c.JSON(http.StatusOK, map[string]any{"machines": machines})
+}
 
-	sess, err := s.sessionMgr.GetWithSource(sessionID, session.SourceWebUI)
+func (s *Server) daemonClientForMachine(ctx context.Context, machineID string) (*daemonhost.Client, *daemonv1.ComputerInventory, error) {
+	if s == nil || s.kvStore == nil {
+		return nil, nil, fmt.Errorf("daemon registry unavailable")
+	}
+	snapshot, err := daemonhost.NewRegistry(s.kvStore).Snapshot(ctx)
 	if err != nil {
-		return fmt.Errorf("load daemon task session %s: %w", sessionID, err)
+		return nil, nil, err
 	}
-	sess.AddMessage(agent.Message{
-		Role:    messageRole,
-		Content: messageContent,
-	})
-	if err := s.sessionMgr.Save(sess); err != nil {
-		return fmt.Errorf("save daemon task session %s: %w", sessionID, err)
+	machineID = strings.TrimSpace(machineID)
+	if machineID == "" {
+		return nil, nil, fmt.Errorf("computer_id is required")
 	}
-	s.publishChatEvent(chatEvent{
-		SessionID: sessionID,
-		Role:      messageRole,
-		Content:   messageContent,
-		Timestamp: time.Now().Unix(),
-	})
-	return nil
-}
-
-func (s *Server) handleChatEvents(c *echo.Context) error {
-	tokenStr := strings.TrimSpace(c.QueryParam("token"))
-	if tokenStr == "" {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "token required"})
+	info := snapshot.Machines[machineID]
+	if info == nil {
+		return nil, nil, fmt.Errorf("daemon machine not found")
 	}
-	username, _, _, err := s.parseScopedStreamToken(tokenStr, streamTokenPurposeChatEvents)
-	if err != nil || strings.TrimSpace(username) == "" {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+	baseURL := strings.TrimSpace(info.DaemonUrl)
+	if baseURL == "" {
+		return nil, nil, fmt.Errorf("daemon machine has no reachable daemon_url")
 	}
-	sessionID := strings.TrimSpace(c.QueryParam("session_id"))
-	if sessionID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session_id required"})
+	inv := snapshot.Inventories[machineID]
+	if inv == nil {
+		inv = &daemonv1.ComputerInventory{}
 	}
-	if runtimeID, isAlias := resolveWebUIChatRuntimeAlias(sessionID); isAlias {
-		sessionID = webUIRuntimeChatSessionID(username, runtimeID)
+	return daemonhost.NewClient(baseURL), inv, nil
+}
+
+func (s *Server) handleDaemonExplorerWorkspaces(c *echo.Context) error {
+	machineID := strings.TrimSpace(c.QueryParam("computer_id"))
+	_, inv, err := s.daemonClientForMachine(c.Request().Context(), machineID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	workspaces := make([]*daemonv1.Workspace, 0, len(inv.Workspaces))
+	for _, ws := range inv.Workspaces {
+		if ws == nil {
+			continue
+		}
+		workspaces = append(workspaces, ws)
 	}
+	return c.JSON(http.StatusOK, map[string]any{"workspaces": workspaces})
+}
 
-	res := c.Response()
-	req := c.Request()
-	res.Header().Set("Content-Type", "text/event-stream")
-	res.Header().Set("Cache-Control", "no-cache")
-	res.Header().Set("Connection", "keep-alive")
-
-	ch := make(chan chatEvent, 16)
-	s.registerChatEventSubscriber(sessionID, ch)
-	defer s.unregisterChatEventSubscriber(sessionID, ch)
-
-	flusher, ok := res.(http.Flusher)
-	if !ok {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "streaming unsupported"})
-	}
-
-	_, _ = res.Write([]byte(": connected\n\n"))
-	flusher.Flush()
-
-	for {
-		select {
-		case <-req.Context().Done():
-			return nil
-		case event := <-ch:
-			payload, _ := json.Marshal(event)
-			_, _ = res.Write([]byte("data: "))
-			_, _ = res.Write(payload)
-			_, _ = res.Write([]byte("\n\n"))
-			flusher.Flush()
-		}
+func (s *Server) handleDaemonExplorerTree(c *echo.Context) error {
+	var body struct {
+		MachineID   string `json:"computer_id"`
+		WorkspaceID string `json:"workspace_id"`
+		Path        string `json:"path"`
 	}
-}
-
-func (s *Server) registerChatEventSubscriber(sessionID string, ch chan chatEvent) {
-	if s == nil || ch == nil {
-		return
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	sessionID = strings.TrimSpace(sessionID)
-	if sessionID == "" {
-		return
+	client, inv, err := s.daemonClientForMachine(c.Request().Context(), body.MachineID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	s.chatEventMu.Lock()
-	defer s.chatEventMu.Unlock()
-	if s.chatEventSubs == nil {
-		s.chatEventSubs = map[string]map[chan chatEvent]struct{}{}
+	workspaceID := strings.TrimSpace(body.WorkspaceID)
+	if workspaceID == "" {
+		for _, ws := range inv.Workspaces {
+			if ws != nil && ws.IsDefault {
+				workspaceID = strings.TrimSpace(ws.WorkspaceId)
+				break
+			}
+		}
 	}
-	if s.chatEventSubs[sessionID] == nil {
-		s.chatEventSubs[sessionID] = map[chan chatEvent]struct{}{}
+	resp, err := client.ListWorkspaceTree(&daemonv1.ListWorkspaceTreeRequest{
+		WorkspaceId: workspaceID,
+		Path:        strings.TrimSpace(body.Path),
+	})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	s.chatEventSubs[sessionID][ch] = struct{}{}
+	return c.JSON(http.StatusOK, resp)
 }
 
-func (s *Server) unregisterChatEventSubscriber(sessionID string, ch chan chatEvent) {
-	if s == nil || ch == nil {
-		return
-	}
-	sessionID = strings.TrimSpace(sessionID)
-	if sessionID == "" {
-		return
-	}
-	s.chatEventMu.Lock()
-	defer s.chatEventMu.Unlock()
-	if s.chatEventSubs == nil || s.chatEventSubs[sessionID] == nil {
-		return
-	}
-	delete(s.chatEventSubs[sessionID], ch)
-	if len(s.chatEventSubs[sessionID]) == 0 {
-		delete(s.chatEventSubs, sessionID)
+func (s *Server) handleDaemonExplorerFile(c *echo.Context) error {
+	var body struct {
+		MachineID   string `json:"computer_id"`
+		WorkspaceID string `json:"workspace_id"`
+		Path        string `json:"path"`
 	}
-	close(ch)
-}
-
-func (s *Server) publishChatEvent(event chatEvent) {
-	if s == nil {
-		return
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	sessionID := strings.TrimSpace(event.SessionID)
-	if sessionID == "" {
-		return
+	client, _, err := s.daemonClientForMachine(c.Request().Context(), body.MachineID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	s.chatEventMu.RLock()
-	defer s.chatEventMu.RUnlock()
-	for ch := range s.chatEventSubs[sessionID] {
-		select {
-		case ch <- event:
-		default:
-		}
+	resp, err := client.ReadWorkspaceFile(&daemonv1.ReadWorkspaceFileRequest{
+		WorkspaceId: strings.TrimSpace(body.WorkspaceID),
+		Path:        strings.TrimSpace(body.Path),
+	})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, resp)
 }
 
-func (s *Server) handleUpdateToolSession(c *echo.Context) error {
-	if s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
-	}
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
-	}
-	if err := s.ensureSessionOwner(c, id); err != nil {
-		return err
-	}
-	current, err := s.toolSess.GetSession(c.Request().Context(), id)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+func (s *Server) handleSendAgentDirectMessage(c *echo.Context) error {
+	agentID := strings.TrimSpace(c.Param("agent_id"))
+	if agentID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "agent_id is required"})
 	}
 
 	var body struct {
-		Tool             string `json:"tool"`
-		Title            string `json:"title"`
-		Command          string `json:"command"`
-		CommandArgs      string `json:"command_args"`
-		RuntimeTransport string `json:"runtime_transport"`
-		Workdir          string `json:"workdir"`
-		AccessMode       string `json:"access_mode"`
-		AccessPassword   string `json:"access_password"`
-		ProxyMode        string `json:"proxy_mode"`
-		ProxyURL         string `json:"proxy_url"`
-		PublicBaseURL    string `json:"public_base_url"`
+		Content          string   `json:"content"`
+		AttachmentIds    []string `json:"attachment_ids"`
+		ReplyToMessageId string   `json:"reply_to_message_id"`
+		RequestId        string   `json:"request_id"`
 	}
 	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
 	}
 
-	toolName := strings.TrimSpace(body.Tool)
-	if toolName == "" {
-		toolName = strings.TrimSpace(current.Tool)
+	if strings.TrimSpace(body.Content) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message content cannot be empty"})
 	}
-	if toolName == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "tool is required"})
+
+	if strings.TrimSpace(body.RequestId) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "request_id is required"})
 	}
 
-	command := resolveToolCommandWithArgs(toolName, body.Command, body.CommandArgs)
-	if command == "" {
-		command = strings.TrimSpace(current.Command)
+	// Get daemon inventory to find which machine has this agent
+	snapshot, err := daemonhost.NewRegistry(s.kvStore).Snapshot(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get daemon inventory"})
 	}
-	if command == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "command is required"})
+
+	// Find the machine that has this agent
+	// First try to find in agents list (for future compatibility)
+	var targetMachineID string
+	for machineID, inventory := range snapshot.Inventories {
+		if inventory == nil || inventory.Agents == nil {
+			continue
+		}
+		for _, agent := range inventory.Agents {
+			if agent != nil && agent.AgentId == agentID {
+				targetMachineID = machineID
+				break
+			}
+		}
+		if targetMachineID != "" {
+			break
+		}
 	}
 
-	workdir := strings.TrimSpace(body.Workdir)
-	if workdir == "" {
-		workdir = strings.TrimSpace(current.Workdir)
+	// Fallback: check if agent_id matches a runtime ID (current system uses runtime IDs as agent IDs)
+	if targetMachineID == "" {
+		for machineID, inventory := range snapshot.Inventories {
+			if inventory == nil || inventory.Runtimes == nil {
+				continue
+			}
+			for _, runtime := range inventory.Runtimes {
+				if runtime != nil && runtime.RuntimeId == agentID {
+					targetMachineID = machineID
+					break
+				}
+			}
+			if targetMachineID != "" {
+				break
+			}
+		}
 	}
-	if workdir == "" {
-		workdir = s.config.WorkspacePath()
+
+	if targetMachineID == "" {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "agent not found in any daemon"})
 	}
 
-	_, err = s.toolSess.UpdateSessionConfig(c.Request().Context(), id, toolName, strings.TrimSpace(body.Title), command, workdir)
+	// Get daemon client for the target machine
+	client, _, err := s.daemonClientForMachine(c.Request().Context(), targetMachineID)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
-		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	existingProxyMode, existingProxyURL := toolProxyFromMetadata(current.Metadata)
-	proxyMode, proxyURL, err := resolveToolProxyConfig(existingProxyMode, existingProxyURL, body.ProxyMode, body.ProxyURL)
+	// Call SendMessage RPC with dm:@agent_id target
+	resp, err := client.SendMessageRemote(&daemonv1.SendMessageRequest{
+		Target:           fmt.Sprintf("dm:@%s", agentID),
+		Content:          strings.TrimSpace(body.Content),
+		AttachmentIds:    body.AttachmentIds,
+		ReplyToMessageId: strings.TrimSpace(body.ReplyToMessageId),
+		RequestId:        strings.TrimSpace(body.RequestId),
+	})
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
-	}
-	nextMetadata := withToolProxyMetadata(cloneMap(current.Metadata), proxyMode, proxyURL)
-	nextMetadata["user_command"] = command
-	nextMetadata["user_args"] = strings.TrimSpace(body.CommandArgs)
-	if err := s.toolSess.UpdateSessionMetadata(c.Request().Context(), id, nextMetadata); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	updatedSession, _ := s.toolSess.GetSession(c.Request().Context(), id)
 
-	accessPassword := ""
-	modeChanged := strings.TrimSpace(body.AccessMode) != "" || strings.TrimSpace(body.AccessPassword) != ""
-	if modeChanged {
-		mode := strings.TrimSpace(body.AccessMode)
-		if mode == "" {
-			mode = strings.TrimSpace(updatedSession.AccessMode)
-		}
-		accessPassword, err = s.toolSess.ConfigureSessionAccess(c.Request().Context(), id, mode, body.AccessPassword)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to configure session access: " + err.Error()})
-		}
-		updatedSession, err = s.toolSess.GetSession(c.Request().Context(), id)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to reload tool session: " + err.Error()})
-		}
-	}
+	// Map response to frontend shape
+	return c.JSON(http.StatusOK, map[string]any{
+		"message_id": resp.Message.MessageId,
+		"agent_id":   agentID,
+		"content":    body.Content,
+		"created_at": time.Now().Format(time.RFC3339),
+		"target":     fmt.Sprintf("dm:@%s", agentID),
+	})
+}
 
-	accessURL := ""
-	if strings.TrimSpace(updatedSession.AccessMode) != "" && updatedSession.AccessMode != toolsessions.AccessModeNone {
-		accessURL = s.buildToolSessionAccessURL(c, id, strings.TrimSpace(body.PublicBaseURL))
+func (s *Server) handleGetDaemonAttachment(c *echo.Context) error {
+	attachmentID := strings.TrimSpace(c.Param("attachment_id"))
+	if attachmentID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "attachment_id is required"})
 	}
-	if err := s.toolSess.AppendEvent(context.Background(), id, "session_updated", map[string]interface{}{
-		"command":    command,
-		"workdir":    workdir,
-		"proxy_mode": proxyMode,
-	}); err != nil {
-		s.logger.Warn("Failed to append tool session update event",
-			zap.String("session_id", id),
-			zap.Error(err),
-		)
+	target := strings.TrimSpace(c.QueryParam("target"))
+	if target == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "target is required"})
 	}
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"session":         updatedSession,
-		"access_mode":     updatedSession.AccessMode,
-		"access_url":      accessURL,
-		"access_password": accessPassword,
+	resp, err := s.collaborationFacade().GetAttachment(c.Request().Context(), &daemonv1.GetAttachmentRequest{
+		AttachmentId: attachmentID,
+		Target:       target,
 	})
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	record := resp.GetAttachment()
+	content := resp.GetContent()
+	mimeType := strings.TrimSpace(record.GetMimeType())
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	filename := strings.TrimSpace(record.GetFilename())
+	if filename != "" {
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", filename))
+	}
+	return c.Blob(http.StatusOK, mimeType, content)
 }
 
-func (s *Server) handleRestartToolSession(c *echo.Context) error {
-	if s.toolSess == nil || s.processMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
+func (s *Server) collaborationFacade() *gateway.Server {
+	var eventMgr *eventlog.Manager
+	var idemStore *idempotency.Store
+	if s.entClient != nil {
+		if mgr, err := eventlog.NewManager(s.entClient); err == nil {
+			eventMgr = mgr
+		}
+		idemStore = idempotency.NewStore(s.entClient)
 	}
+	return gateway.NewCollaborationFacade(s.sessionMgr, s.kvStore, eventMgr, idemStore)
+}
 
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+func (s *Server) handleSaveDaemonMessage(c *echo.Context) error {
+	messageID := strings.TrimSpace(c.Param("message_id"))
+	if messageID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message_id is required"})
 	}
-	if err := s.ensureSessionOwner(c, id); err != nil {
-		return err
+	var body struct {
+		Target    string `json:"target"`
+		RequestID string `json:"request_id"`
 	}
-	current, err := s.toolSess.GetSession(c.Request().Context(), id)
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	userID := firstNonEmptyString(s.currentUserID(c), s.currentUsername(c))
+	if strings.TrimSpace(userID) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "user identity is required"})
+	}
+	resp, err := s.collaborationFacade().SaveMessage(c.Request().Context(), &daemonv1.SaveMessageRequest{
+		Target:        strings.TrimSpace(body.Target),
+		MessageId:     messageID,
+		SavedByUserId: userID,
+		RequestId:     strings.TrimSpace(body.RequestID),
+	})
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, resp)
+}
 
+func (s *Server) handleUnsaveDaemonMessage(c *echo.Context) error {
+	messageID := strings.TrimSpace(c.Param("message_id"))
+	if messageID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message_id is required"})
+	}
 	var body struct {
-		Tool             string `json:"tool"`
-		Title            string `json:"title"`
-		Command          string `json:"command"`
-		CommandArgs      string `json:"command_args"`
-		RuntimeTransport string `json:"runtime_transport"`
-		Workdir          string `json:"workdir"`
-		AccessMode       string `json:"access_mode"`
-		AccessPassword   string `json:"access_password"`
-		ProxyMode        string `json:"proxy_mode"`
-		ProxyURL         string `json:"proxy_url"`
-		PublicBaseURL    string `json:"public_base_url"`
+		Target    string `json:"target"`
+		RequestID string `json:"request_id"`
 	}
 	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
 	}
+	userID := firstNonEmptyString(s.currentUserID(c), s.currentUsername(c))
+	if strings.TrimSpace(userID) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "user identity is required"})
+	}
+	resp, err := s.collaborationFacade().UnsaveMessage(c.Request().Context(), &daemonv1.UnsaveMessageRequest{
+		Target:        strings.TrimSpace(body.Target),
+		MessageId:     messageID,
+		SavedByUserId: userID,
+		RequestId:     strings.TrimSpace(body.RequestID),
+	})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
 
-	toolName := strings.TrimSpace(body.Tool)
-	if toolName == "" {
-		toolName = strings.TrimSpace(current.Tool)
+func (s *Server) handleListDaemonSavedMessages(c *echo.Context) error {
+	userID := firstNonEmptyString(s.currentUserID(c), s.currentUsername(c))
+	if strings.TrimSpace(userID) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "user identity is required"})
 	}
-	if toolName == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "tool is required"})
+	resp, err := s.collaborationFacade().ListSavedMessages(c.Request().Context(), &daemonv1.ListSavedMessagesRequest{
+		Target:        strings.TrimSpace(c.QueryParam("target")),
+		SavedByUserId: userID,
+		Limit:         200,
+	})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, resp)
+}
 
-	command := resolveToolCommandWithArgs(toolName, body.Command, body.CommandArgs)
-	if command == "" {
-		command = strings.TrimSpace(current.Command)
+func (s *Server) handleListGoalRuns(c *echo.Context) error {
+	if s.goalSvc == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
 	}
-	if command == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "command is required"})
+	items, err := s.goalSvc.ListGoalRuns(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, map[string]any{"items": items})
+}
 
-	workdir := strings.TrimSpace(body.Workdir)
-	if workdir == "" {
-		workdir = strings.TrimSpace(current.Workdir)
+func (s *Server) handleCreateGoalRun(c *echo.Context) error {
+	if s.goalSvc == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
 	}
-	if workdir == "" {
-		workdir = s.config.WorkspacePath()
+	var body struct {
+		Name                    string                     `json:"name"`
+		Goal                    string                     `json:"goal"`
+		NaturalLanguageCriteria string                     `json:"natural_language_criteria"`
+		RiskLevel               goaldriven.RiskLevel       `json:"risk_level"`
+		AllowAutoScope          bool                       `json:"allow_auto_scope"`
+		SelectedScope           *goaldriven.ExecutionScope `json:"selected_scope"`
 	}
-	existingProxyMode, existingProxyURL := toolProxyFromMetadata(current.Metadata)
-	proxyMode, proxyURL, err := resolveToolProxyConfig(existingProxyMode, existingProxyURL, body.ProxyMode, body.ProxyURL)
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	result, err := s.goalSvc.CreateGoalRun(c.Request().Context(), goaldriven.CreateGoalRunInput{
+		Name:                    body.Name,
+		Goal:                    body.Goal,
+		NaturalLanguageCriteria: body.NaturalLanguageCriteria,
+		RiskLevel:               body.RiskLevel,
+		AllowAutoScope:          body.AllowAutoScope,
+		SelectedScope:           body.SelectedScope,
+		CreatedBy:               s.currentUsername(c),
+	})
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, result)
+}
 
-	nextMetadata := withToolProxyMetadata(cloneMap(current.Metadata), proxyMode, proxyURL)
-	nextMetadata["user_command"] = command
-	nextMetadata["user_args"] = strings.TrimSpace(body.CommandArgs)
-	transport := s.resolveSessionRuntimeTransport(nextMetadata, body.RuntimeTransport)
-
-	launchCommand := applyToolProxyToCommand(command, proxyMode, proxyURL)
-	runtimeSession := ""
-	if wrapped, sessionName := buildToolRuntimeLaunchWithTransport(transport, launchCommand, id); sessionName != "" {
-		launchCommand = wrapped
-		runtimeSession = sessionName
-		nextMetadata = runtimeagents.ApplyLaunchMetadata(nextMetadata, runtimeagents.LaunchInfo{
-			TransportName: transport.Name(),
-			SessionName:   runtimeSession,
-			LaunchCommand: launchCommand,
-		})
-	} else {
-		delete(nextMetadata, runtimeagents.MetadataRuntimeTransport)
-		delete(nextMetadata, runtimeagents.MetadataRuntimeSession)
-		delete(nextMetadata, runtimeagents.MetadataTmuxSession)
+func (s *Server) handleGetGoalRun(c *echo.Context) error {
+	if s.goalSvc == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
 	}
-	nextMetadata[runtimeagents.MetadataLaunchCommand] = launchCommand
-
-	if err := s.processMgr.Reset(id); err != nil {
-		s.logger.Warn("Failed to reset tool process before restart",
-			zap.String("session_id", id),
-			zap.Error(err),
-		)
+	detail, ok, err := s.goalSvc.GetGoalRunDetail(c.Request().Context(), strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	s.tryKillRuntimeSession(c.Request().Context(), id)
-	spec := execenv.StartSpecFromContext(c.Request().Context(), id, launchCommand, workdir, nextMetadata)
-	if err := s.processMgr.StartWithSpec(context.Background(), spec); err != nil {
-		if terminateErr := s.toolSess.TerminateSession(context.Background(), id, "failed to restart process: "+err.Error()); terminateErr != nil {
-			s.logger.Warn("Failed to terminate tool session after restart error",
-				zap.String("session_id", id),
-				zap.Error(terminateErr),
-			)
-		}
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to restart tool process: " + err.Error()})
+	if !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "goal run not found"})
 	}
+	return c.JSON(http.StatusOK, detail)
+}
 
-	_, err = s.toolSess.UpdateSessionLaunch(c.Request().Context(), id, toolName, strings.TrimSpace(body.Title), command, workdir)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+func (s *Server) handleConfirmGoalRunCriteria(c *echo.Context) error {
+	if s.goalSvc == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
 	}
-	if err := s.toolSess.UpdateSessionMetadata(c.Request().Context(), id, nextMetadata); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	var body struct {
+		Criteria      goalcriteria.Set           `json:"criteria"`
+		SelectedScope *goaldriven.ExecutionScope `json:"selected_scope"`
 	}
-	updatedSession, err := s.toolSess.GetSession(c.Request().Context(), id)
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	run, err := s.goalSvc.ConfirmCriteria(c.Request().Context(), strings.TrimSpace(c.Param("id")), body.Criteria, body.SelectedScope)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to reload tool session: " + err.Error()})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, map[string]any{"goal_run": run})
+}
 
-	accessPassword := ""
-	modeChanged := strings.TrimSpace(body.AccessMode) != "" || strings.TrimSpace(body.AccessPassword) != ""
-	if modeChanged {
-		mode := strings.TrimSpace(body.AccessMode)
-		if mode == "" {
-			mode = strings.TrimSpace(updatedSession.AccessMode)
-		}
-		accessPassword, err = s.toolSess.ConfigureSessionAccess(c.Request().Context(), id, mode, body.AccessPassword)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to configure session access: " + err.Error()})
-		}
-		updatedSession, err = s.toolSess.GetSession(c.Request().Context(), id)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to reload tool session: " + err.Error()})
-		}
+func (s *Server) handleStartGoalRun(c *echo.Context) error {
+	if s.goalSvc == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
+	}
+	run, err := s.goalSvc.StartGoalRun(c.Request().Context(), strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, map[string]any{"goal_run": run})
+}
 
-	accessURL := ""
-	if strings.TrimSpace(updatedSession.AccessMode) != "" && updatedSession.AccessMode != toolsessions.AccessModeNone {
-		accessURL = s.buildToolSessionAccessURL(c, id, strings.TrimSpace(body.PublicBaseURL))
+func (s *Server) handleStopGoalRun(c *echo.Context) error {
+	if s.goalSvc == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
 	}
-	updatedSession, err = s.toolSess.GetSession(c.Request().Context(), id)
+	run, err := s.goalSvc.StopGoalRun(c.Request().Context(), strings.TrimSpace(c.Param("id")))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to reload tool session: " + err.Error()})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, map[string]any{"goal_run": run})
+}
 
-	eventPayload := runtimeagents.ApplyRuntimeSessionPayload(map[string]interface{}{
-		"command":    command,
-		"launch_cmd": launchCommand,
-		"workdir":    workdir,
-		"proxy_mode": proxyMode,
-	}, transport.Name(), runtimeSession)
-	eventPayload[runtimeagents.MetadataRuntimeTransport] = strings.TrimSpace(transport.Name())
-	if err := s.toolSess.AppendEvent(context.Background(), id, "process_restarted", eventPayload); err != nil {
-		s.logger.Warn("Failed to append tool session restart event",
-			zap.String("session_id", id),
-			zap.Error(err),
-		)
+func (s *Server) handleCancelGoalRun(c *echo.Context) error {
+	if s.goalSvc == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
 	}
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"session":         updatedSession,
-		"access_mode":     updatedSession.AccessMode,
-		"access_url":      accessURL,
-		"access_password": accessPassword,
-	})
+	run, err := s.goalSvc.CancelGoalRun(c.Request().Context(), strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]any{"goal_run": run})
 }
 
-func (s *Server) handleToolSessionProcessStatus(c *echo.Context) error {
-	if s.processMgr == nil || s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
+func (s *Server) handleConfirmGoalRunManualCriterion(c *echo.Context) error {
+	if s.goalSvc == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "goal-driven service unavailable"})
 	}
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	var body struct {
+		CriterionID string `json:"criterion_id"`
+		Approved    bool   `json:"approved"`
+		Note        string `json:"note"`
 	}
-	if err := s.ensureSessionOwner(c, id); err != nil {
-		return err
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	s.tryRestoreToolSessionRuntime(c.Request().Context(), id)
-	sess, sessErr := s.toolSess.GetSession(c.Request().Context(), id)
-	if sessErr != nil {
-		if errors.Is(sessErr, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": sessErr.Error()})
+	run, err := s.goalSvc.ConfirmManualCriterion(
+		c.Request().Context(),
+		strings.TrimSpace(c.Param("id")),
+		body.CriterionID,
+		body.Note,
+		body.Approved,
+	)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, map[string]any{"goal_run": run})
+}
 
-	status, err := s.processMgr.GetStatus(id)
-	if err != nil {
-		if isProcessSessionNotFound(err) {
-			return c.JSON(http.StatusOK, map[string]interface{}{
-				"id":                id,
-				"running":           false,
-				"missing":           true,
-				"runtime_transport": metadataString(sess.Metadata, runtimeagents.MetadataRuntimeTransport),
-				"runtime_session":   metadataString(sess.Metadata, runtimeagents.MetadataRuntimeSession),
-				"tmux_session":      metadataString(sess.Metadata, runtimeagents.MetadataTmuxSession),
-				"launch_cmd":        metadataString(sess.Metadata, runtimeagents.MetadataLaunchCommand),
-			})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+func (s *Server) releaseVersion() string {
+	versionValue := strings.TrimSpace(version.GetVersion())
+	if versionValue == "" || versionValue == "dev" {
+		return "latest"
 	}
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"id":                status.ID,
-		"running":           status.Running,
-		"exit_code":         status.ExitCode,
-		"runtime_transport": metadataString(sess.Metadata, runtimeagents.MetadataRuntimeTransport),
-		"runtime_session":   metadataString(sess.Metadata, runtimeagents.MetadataRuntimeSession),
-		"tmux_session":      metadataString(sess.Metadata, runtimeagents.MetadataTmuxSession),
-		"launch_cmd":        metadataString(sess.Metadata, runtimeagents.MetadataLaunchCommand),
-		"observation":       status.Observation,
-	})
+	return versionValue
 }
 
-func (s *Server) handleToolSessionProcessOutput(c *echo.Context) error {
-	if s.processMgr == nil || s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
+func (s *Server) releaseBaseURL() string {
+	return "https://github.com/czyt/nekobot/releases/download/" + s.releaseVersion()
+}
+
+func normalizePlatformTarget(goos, goarch string) (string, string) {
+	allowedOS := map[string]struct{}{"linux": {}, "darwin": {}, "windows": {}}
+	allowedArch := map[string]struct{}{"amd64": {}, "arm64": {}}
+	if _, ok := allowedOS[goos]; !ok {
+		goos = runtime.GOOS
 	}
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	if _, ok := allowedArch[goarch]; !ok {
+		goarch = runtime.GOARCH
 	}
-	if err := s.ensureSessionOwner(c, id); err != nil {
-		return err
+	if _, ok := allowedOS[goos]; !ok {
+		goos = "linux"
 	}
-	s.tryRestoreToolSessionRuntime(c.Request().Context(), id)
-
-	offset := 0
-	limit := 300
-	if raw := strings.TrimSpace(c.QueryParam("offset")); raw != "" {
-		if v, err := strconv.Atoi(raw); err == nil && v >= 0 {
-			offset = v
-		}
+	if _, ok := allowedArch[goarch]; !ok {
+		goarch = "amd64"
 	}
-	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
-		if v, err := strconv.Atoi(raw); err == nil && v > 0 && v <= 2000 {
-			limit = v
-		}
+	return goos, goarch
+}
+
+func parseBootstrapTarget(c *echo.Context) (string, string) {
+	return normalizePlatformTarget(strings.TrimSpace(c.QueryParam("os")), strings.TrimSpace(c.QueryParam("arch")))
+}
+
+func detectArchiveName(goos, goarch, binary string) string {
+	platform := goos + "-" + goarch
+	if goos == "windows" {
+		return binary + "-" + platform + ".zip"
 	}
+	return binary + "-" + platform + ".tar.gz"
+}
 
-	lines, total, err := s.processMgr.GetOutput(id, offset, limit)
-	if err != nil {
-		if isProcessSessionNotFound(err) {
-			return c.JSON(http.StatusOK, map[string]interface{}{
-				"lines":   []string{},
-				"total":   0,
-				"running": false,
-				"missing": true,
-			})
+func (s *Server) handleGetDaemonBootstrap(c *echo.Context) error {
+	serverURL := strings.TrimSpace(s.config.WebUI.PublicBaseURL)
+	if serverURL == "" {
+		scheme := "http"
+		if c.Scheme() != "" {
+			scheme = c.Scheme()
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		serverURL = fmt.Sprintf("%s://%s", scheme, c.Request().Host)
 	}
+	token := s.getDaemonToken()
+	machineName := strings.TrimSpace(s.currentUsername(c))
+	command := fmt.Sprintf("nekobot daemon run --server-url %s --token %s --machine-name %s", strconv.Quote(serverURL), strconv.Quote(token), strconv.Quote(machineName))
+	return c.JSON(http.StatusOK, map[string]any{
+		"server_url":   serverURL,
+		"daemon_token": token,
+		"command":      command,
+	})
+}
 
-	status, statusErr := s.processMgr.GetStatus(id)
-	if statusErr == nil && status.Running {
-		if err := s.toolSess.TouchSession(c.Request().Context(), id, toolsessions.StateRunning); err != nil {
-			s.logger.Warn("Failed to touch running tool session",
-				zap.String("session_id", id),
-				zap.Error(err),
-			)
-		}
-	} else if statusErr == nil && !status.Running {
-		if sess, err := s.toolSess.GetSession(c.Request().Context(), id); err == nil &&
-			sess.State != toolsessions.StateTerminated &&
-			sess.State != toolsessions.StateArchived {
-			if err := s.toolSess.TerminateSession(c.Request().Context(), id, fmt.Sprintf("process exited with code %d", status.ExitCode)); err != nil {
-				s.logger.Warn("Failed to terminate stopped tool session",
-					zap.String("session_id", id),
-					zap.Int("exit_code", status.ExitCode),
-					zap.Error(err),
-				)
-			}
+func (s *Server) handleGetNekoClientdBootstrap(c *echo.Context) error {
+	serverURL := strings.TrimSpace(s.config.WebUI.PublicBaseURL)
+	if serverURL == "" {
+		scheme := "http"
+		if c.Scheme() != "" {
+			scheme = c.Scheme()
 		}
+		serverURL = fmt.Sprintf("%s://%s", scheme, c.Request().Host)
 	}
-	running := statusErr == nil && status.Running
-	exitCode := 0
-	if statusErr == nil {
-		exitCode = status.ExitCode
-	}
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"lines":     lines,
-		"total":     total,
-		"running":   running,
-		"exit_code": exitCode,
-	})
+	token := s.getDaemonToken()
+	grpcTarget := strings.TrimPrefix(strings.TrimPrefix(serverURL, "https://"), "http://")
+	targetOS, targetArch := parseBootstrapTarget(c)
+	archiveName := detectArchiveName(targetOS, targetArch, "nekoclientd")
+	downloadBaseURL := s.releaseBaseURL()
+	downloadURL := strings.TrimRight(downloadBaseURL, "/") + "/" + archiveName
+	installCommand := fmt.Sprintf("curl -L %s -o /tmp/%s && mkdir -p /tmp/nekoclientd-extract && tar -xzf /tmp/%s -C /tmp/nekoclientd-extract && sudo install -m 0755 /tmp/nekoclientd-extract/nekoclientd /usr/local/bin/nekoclientd", strconv.Quote(downloadURL), archiveName, archiveName)
+	serviceInstallCommand := fmt.Sprintf("nekoclientd --server %s --token %s --machine-name %s install", strconv.Quote(grpcTarget), strconv.Quote(token), strconv.Quote(strings.TrimSpace(s.currentUsername(c))))
+	startCommand := fmt.Sprintf("nekoclientd --server %s --token %s --machine-name %s start", strconv.Quote(grpcTarget), strconv.Quote(token), strconv.Quote(strings.TrimSpace(s.currentUsername(c))))
+	return c.JSON(http.StatusOK, map[string]any{"server_url": serverURL, "daemon_token": token, "grpc_target": grpcTarget, "target_os": targetOS, "target_arch": targetArch, "binary_name": "nekoclientd", "version": s.releaseVersion(), "download_base_url": downloadBaseURL, "download_url": downloadURL, "archive_name": archiveName, "install_command": installCommand, "service_install_command": serviceInstallCommand, "start_command": startCommand})
 }
 
-func (s *Server) handleToolSessionProcessInput(c *echo.Context) error {
-	if s.processMgr == nil || s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
+func (s *Server) handleRegisterDaemon(c *echo.Context) error {
+	if !s.authorizeDaemonRequest(c) {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid daemon token"})
 	}
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	if s.kvStore == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "daemon registry unavailable"})
 	}
-	if err := s.ensureSessionOwner(c, id); err != nil {
-		return err
+	var req daemonv1.RegisterComputerRequest
+	if err := daemonhost.DecodeProtoJSON(c.Request(), &req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	s.tryRestoreToolSessionRuntime(c.Request().Context(), id)
+	resp, err := daemonhost.NewRegistry(s.kvStore).Register(c.Request().Context(), &req)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
 
-	var body struct {
-		Data string `json:"data"`
+func (s *Server) handleHeartbeatDaemon(c *echo.Context) error {
+	if !s.authorizeDaemonRequest(c) {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid daemon token"})
 	}
-	if err := c.Bind(&body); err != nil {
+	if s.kvStore == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "daemon registry unavailable"})
+	}
+	var req daemonv1.HeartbeatComputerRequest
+	if err := daemonhost.DecodeProtoJSON(c.Request(), &req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	if body.Data == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "input data is required"})
+	resp, err := daemonhost.NewRegistry(s.kvStore).Heartbeat(c.Request().Context(), &req)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, resp)
+}
 
-	if err := s.processMgr.Write(id, body.Data); err != nil {
-		if isProcessSessionNotFound(err) {
-			if s.tryRestoreToolSessionRuntime(c.Request().Context(), id) {
-				retryErr := s.processMgr.Write(id, body.Data)
-				if retryErr == nil {
-					if touchErr := s.toolSess.TouchSession(c.Request().Context(), id, toolsessions.StateRunning); touchErr != nil {
-						s.logger.Warn("Failed to touch restored tool session after input retry",
-							zap.String("session_id", id),
-							zap.Error(touchErr),
-						)
-					}
-					return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
-				}
-				err = retryErr
-			}
+func (s *Server) handleFetchDaemonRuns(c *echo.Context) error {
+	if !s.authorizeDaemonRequest(c) {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid daemon token"})
+	}
+	if s.agent == nil || s.agent.TaskService() == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "task service unavailable"})
+	}
+	var req daemonv1.FetchAssignedRunsRequest
+	if err := daemonhost.DecodeProtoJSON(c.Request(), &req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	resp := daemonhost.BuildAssignedRuns(s.agent.TaskService(), req.ComputerId, req.AgentIds, int(req.Limit))
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) handleUpdateDaemonRunStatus(c *echo.Context) error {
+	if !s.authorizeDaemonRequest(c) {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid daemon token"})
+	}
+	if s.agent == nil || s.agent.TaskService() == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "task service unavailable"})
+	}
+	var req daemonv1.UpdateRunStatusRequest
+	if err := daemonhost.DecodeProtoJSON(c.Request(), &req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	resp, task, err := daemonhost.ApplyRunStatusUpdate(s.agent.TaskService(), &req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := s.appendDaemonTaskSessionUpdate(c.Request().Context(), task, &req); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) appendDaemonTaskSessionUpdate(ctx context.Context, task tasks.Task, req *daemonv1.UpdateRunStatusRequest) error {
+	if s == nil || s.sessionMgr == nil || req == nil {
+		return nil
+	}
+	sessionID := strings.TrimSpace(task.SessionID)
+	if sessionID == "" {
+		return nil
+	}
+
+	state := strings.TrimSpace(req.State)
+	messageRole := "system"
+	messageContent := ""
+	switch state {
+	case string(tasks.StateClaimed):
+		messageContent = fmt.Sprintf("Daemon task claimed.\nTask ID: %s", strings.TrimSpace(task.ID))
+	case string(tasks.StateRunning):
+		messageContent = fmt.Sprintf("Daemon task started.\nTask ID: %s", strings.TrimSpace(task.ID))
+	case string(tasks.StateRequiresAction):
+		reason := strings.TrimSpace(req.BlockedReason)
+		if reason == "" {
+			reason = strings.TrimSpace(task.PendingAction)
 		}
-		if isProcessSessionNotFound(err) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "tool process not found"})
+		if reason == "" {
+			reason = "Daemon task requires action."
 		}
-		if strings.Contains(strings.ToLower(err.Error()), "not running") {
-			return c.JSON(http.StatusConflict, map[string]string{"error": "tool process is not running"})
+		messageContent = reason
+	case string(tasks.StateCompleted):
+		messageRole = "assistant"
+		messageContent = strings.TrimSpace(req.ResultMessage)
+		if messageContent == "" {
+			messageContent = fmt.Sprintf("Daemon task completed.\nTask ID: %s", strings.TrimSpace(task.ID))
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	case string(tasks.StateFailed):
+		reason := strings.TrimSpace(req.Error)
+		if reason == "" {
+			reason = strings.TrimSpace(task.LastError)
+		}
+		messageContent = strings.TrimSpace(req.ResultMessage)
+		if messageContent == "" {
+			messageContent = "Daemon task failed."
+		}
+		if reason != "" {
+			messageContent = fmt.Sprintf("%s\nError: %s", messageContent, reason)
+		}
+	case string(tasks.StateCanceled):
+		messageContent = fmt.Sprintf("Daemon task canceled.\nTask ID: %s", strings.TrimSpace(task.ID))
+	default:
+		return nil
 	}
-	if err := s.toolSess.TouchSession(c.Request().Context(), id, toolsessions.StateRunning); err != nil {
-		s.logger.Warn("Failed to touch tool session after process input",
-			zap.String("session_id", id),
-			zap.Error(err),
-		)
+	if strings.TrimSpace(messageContent) == "" {
+		return nil
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+
+	sess, err := s.sessionMgr.GetWithSource(sessionID, session.SourceWebUI)
+	if err != nil {
+		return fmt.Errorf("load daemon task session %s: %w", sessionID, err)
+	}
+	sess.AddMessage(agent.Message{
+		Role:    messageRole,
+		Content: messageContent,
+	})
+	if err := s.sessionMgr.Save(sess); err != nil {
+		return fmt.Errorf("save daemon task session %s: %w", sessionID, err)
+	}
+	s.publishChatEvent(chatEvent{
+		SessionID: sessionID,
+		Role:      messageRole,
+		Content:   messageContent,
+		Timestamp: time.Now().Unix(),
+	})
+	return nil
 }
 
-func (s *Server) handleToolSessionProcessKill(c *echo.Context) error {
-	if s.processMgr == nil || s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
+func (s *Server) handleChatEvents(c *echo.Context) error {
+	tokenStr := strings.TrimSpace(c.QueryParam("token"))
+	if tokenStr == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "token required"})
 	}
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	username, _, _, err := s.parseScopedStreamToken(tokenStr, streamTokenPurposeChatEvents)
+	if err != nil || strings.TrimSpace(username) == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid token"})
 	}
-	if err := s.ensureSessionOwner(c, id); err != nil {
-		return err
+	sessionID := strings.TrimSpace(c.QueryParam("session_id"))
+	if sessionID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session_id required"})
+	}
+	if runtimeID, isAlias := resolveWebUIChatRuntimeAlias(sessionID); isAlias {
+		sessionID = s.chatSessionID(c.Request().Context(), username, runtimeID)
 	}
 
-	err := s.processMgr.Kill(id)
-	if err != nil && !isProcessSessionNotFound(err) && !strings.Contains(strings.ToLower(err.Error()), "not running") {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	res := c.Response()
+	req := c.Request()
+	res.Header().Set("Content-Type", "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+
+	ch := make(chan chatEvent, 16)
+	s.registerChatEventSubscriber(sessionID, ch)
+	defer s.unregisterChatEventSubscriber(sessionID, ch)
+
+	flusher, ok := res.(http.Flusher)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "streaming unsupported"})
 	}
-	s.tryKillRuntimeSession(c.Request().Context(), id)
-	if err := s.toolSess.TerminateSession(c.Request().Context(), id, "killed from webui"); err != nil {
-		s.logger.Warn("Failed to terminate tool session after kill",
-			zap.String("session_id", id),
-			zap.Error(err),
-		)
+
+	_, _ = res.Write([]byte(": connected\n\n"))
+	flusher.Flush()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return nil
+		case event := <-ch:
+			payload, _ := json.Marshal(event)
+			_, _ = res.Write([]byte("data: "))
+			_, _ = res.Write(payload)
+			_, _ = res.Write([]byte("\n\n"))
+			flusher.Flush()
+		}
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "killed"})
 }
 
-func (s *Server) handleUpdateToolSessionAccess(c *echo.Context) error {
-	if s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
+func (s *Server) registerChatEventSubscriber(sessionID string, ch chan chatEvent) {
+	if s == nil || ch == nil {
+		return
 	}
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	sessionID = strings.TrimSpace(sessionID)
+	if sessionID == "" {
+		return
 	}
-	if err := s.ensureSessionOwner(c, id); err != nil {
-		return err
+	s.chatEventMu.Lock()
+	defer s.chatEventMu.Unlock()
+	if s.chatEventSubs == nil {
+		s.chatEventSubs = map[string]map[chan chatEvent]struct{}{}
 	}
-	var body struct {
-		Mode          string `json:"mode"`
-		Password      string `json:"password"`
-		PublicBaseURL string `json:"public_base_url"`
+	if s.chatEventSubs[sessionID] == nil {
+		s.chatEventSubs[sessionID] = map[chan chatEvent]struct{}{}
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	s.chatEventSubs[sessionID][ch] = struct{}{}
+}
+
+func (s *Server) unregisterChatEventSubscriber(sessionID string, ch chan chatEvent) {
+	if s == nil || ch == nil {
+		return
 	}
-	mode := strings.TrimSpace(body.Mode)
-	if mode == "" {
-		mode = toolsessions.AccessModeNone
+	sessionID = strings.TrimSpace(sessionID)
+	if sessionID == "" {
+		return
 	}
-	password, err := s.toolSess.ConfigureSessionAccess(c.Request().Context(), id, mode, body.Password)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
-		}
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	s.chatEventMu.Lock()
+	defer s.chatEventMu.Unlock()
+	if s.chatEventSubs == nil || s.chatEventSubs[sessionID] == nil {
+		return
 	}
-	sess, err := s.toolSess.GetSession(c.Request().Context(), id)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	delete(s.chatEventSubs[sessionID], ch)
+	if len(s.chatEventSubs[sessionID]) == 0 {
+		delete(s.chatEventSubs, sessionID)
 	}
-	accessURL := ""
-	if sess.AccessMode != toolsessions.AccessModeNone {
-		accessURL = s.buildToolSessionAccessURL(c, id, body.PublicBaseURL)
+	close(ch)
+}
+
+func (s *Server) publishChatEvent(event chatEvent) {
+	if s == nil {
+		return
+	}
+	sessionID := strings.TrimSpace(event.SessionID)
+	if sessionID == "" {
+		return
+	}
+	s.chatEventMu.RLock()
+	defer s.chatEventMu.RUnlock()
+	for ch := range s.chatEventSubs[sessionID] {
+		select {
+		case ch <- event:
+		default:
+		}
 	}
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"session":         sess,
-		"access_mode":     sess.AccessMode,
-		"access_url":      accessURL,
-		"access_password": password,
-	})
 }
 
-func (s *Server) handleGenerateToolSessionOTP(c *echo.Context) error {
+func (s *Server) handleUpdateToolSession(c *echo.Context) error {
 	if s.toolSess == nil {
 		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
 	}
@@ -4138,4253 +4927,6381 @@ func (s *Server) handleGenerateToolSessionOTP(c *echo.Context) error {
 	if id == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
 	}
-	if err := s.ensureSessionOwner(c, id); err != nil {
+	if ok, err := s.ensureSessionOwner(c, id); !ok {
 		return err
 	}
-
-	var body struct {
-		TTLSeconds int `json:"ttl_seconds"`
-	}
-	_ = c.Bind(&body)
-	var ttl time.Duration
-	if body.TTLSeconds > 0 {
-		ttl = time.Duration(body.TTLSeconds) * time.Second
-	}
-
-	code, expiresAt, err := s.toolSess.GenerateSessionOTP(c.Request().Context(), id, ttl)
+	current, err := s.toolSess.GetSession(c.Request().Context(), id)
 	if err != nil {
-		switch {
-		case errors.Is(err, os.ErrNotExist):
+		if errors.Is(err, os.ErrNotExist) {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
-		case errors.Is(err, os.ErrPermission):
-			return c.JSON(http.StatusConflict, map[string]string{"error": "external access is disabled for this session"})
-		default:
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"session_id":   id,
-		"otp_code":     code,
-		"expires_at":   expiresAt.Unix(),
-		"ttl_seconds":  int(time.Until(expiresAt).Seconds()),
-		"generated_at": time.Now().Unix(),
-	})
-}
 
-func (s *Server) handleToolSessionAccessLogin(c *echo.Context) error {
-	if s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
+	spawn, err := s.bindToolSessionRelaunch(c, current, toolSessionSpawnUpdate)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	commandChanged := spawn.Command != strings.TrimSpace(current.Command) || spawn.Workdir != strings.TrimSpace(current.Workdir)
+	if commandChanged && s.config.ToolSessions.RequireApproval {
+		return s.requestToolSessionSpawnApproval(c, spawn)
 	}
+	status, payload := s.updateToolSession(s.spawnRequestContext(c), spawn)
+	return c.JSON(status, payload)
+}
+
+// bindToolSessionRelaunch reads a restart or update of current from the
+// request body. Omitted fields keep the session's values.
+func (s *Server) bindToolSessionRelaunch(c *echo.Context, current *toolsessions.Session, kind string) (toolSessionSpawn, error) {
 	var body struct {
-		SessionID string `json:"session_id"`
-		Password  string `json:"password"`
+		Tool             string `json:"tool"`
+		Title            string `json:"title"`
+		Command          string `json:"command"`
+		CommandArgs      string `json:"command_args"`
+		RuntimeTransport string `json:"runtime_transport"`
+		Workdir          string `json:"workdir"`
+		AccessMode       string `json:"access_mode"`
+		AccessPassword   string `json:"access_password"`
+		ProxyMode        string `json:"proxy_mode"`
+		ProxyURL         string `json:"proxy_url"`
+		PublicBaseURL    string `json:"public_base_url"`
 	}
 	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return toolSessionSpawn{}, fmt.Errorf("invalid request")
 	}
-	sess, err := s.toolSess.VerifySessionAccess(c.Request().Context(), body.SessionID, body.Password)
-	if err != nil {
-		switch {
-		case errors.Is(err, os.ErrNotExist):
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
-		case errors.Is(err, os.ErrPermission):
-			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or expired password"})
-		default:
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
-		}
+
+	toolName := strings.TrimSpace(body.Tool)
+	if toolName == "" {
+		toolName = strings.TrimSpace(current.Tool)
 	}
-	username := "tool:" + strings.TrimSpace(sess.ID)
-	now := time.Now()
-	claims := jwt.MapClaims{
-		"sub":  username,
-		"uid":  sess.Owner,
-		"role": "member",
-		"sid":  strings.TrimSpace(sess.ID),
-		"exp":  now.Add(24 * time.Hour).Unix(),
-		"iat":  now.Unix(),
+	if toolName == "" {
+		return toolSessionSpawn{}, fmt.Errorf("tool is required")
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(s.getJWTSecret()))
+
+	// Without a command the session keeps its own rather than the tool's
+	// default.
+	command := strings.TrimSpace(current.Command)
+	if strings.TrimSpace(body.Command) != "" || strings.TrimSpace(body.CommandArgs) != "" || command == "" {
+		command = resolveToolCommandWithArgs(toolName, body.Command, body.CommandArgs)
+	}
+	if command == "" {
+		return toolSessionSpawn{}, fmt.Errorf("command is required")
+	}
+
+	workdir := strings.TrimSpace(body.Workdir)
+	if workdir == "" {
+		workdir = strings.TrimSpace(current.Workdir)
+	}
+	workdir, err := s.tenantWorkdir(c, workdir)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "token generation failed"})
+		return toolSessionSpawn{}, err
 	}
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"token":      signedToken,
-		"session_id": sess.ID,
-	})
+	existingProxyMode, existingProxyURL := toolProxyFromMetadata(current.Metadata)
+	proxyMode, proxyURL, err := resolveToolProxyConfig(existingProxyMode, existingProxyURL, body.ProxyMode, body.ProxyURL)
+	if err != nil {
+		return toolSessionSpawn{}, err
+	}
+	metadata := withToolProxyMetadata(cloneMap(current.Metadata), proxyMode, proxyURL)
+	metadata["user_command"] = command
+	metadata["user_args"] = strings.TrimSpace(body.CommandArgs)
+
+	return toolSessionSpawn{
+		Kind:             kind,
+		SessionID:        current.ID,
+		Owner:            strings.TrimSpace(current.Owner),
+		Tool:             toolName,
+		Title:            strings.TrimSpace(body.Title),
+		Command:          command,
+		Workdir:          workdir,
+		Metadata:         metadata,
+		RuntimeTransport: body.RuntimeTransport,
+		ProxyMode:        proxyMode,
+		ProxyURL:         proxyURL,
+		AccessMode:       strings.TrimSpace(body.AccessMode),
+		AccessPassword:   body.AccessPassword,
+		AccessBaseURL:    s.toolSessionAccessBase(c, body.PublicBaseURL),
+	}, nil
 }
 
-func (s *Server) ensureSessionOwner(c *echo.Context, sessionID string) error {
-	sess, err := s.toolSess.GetSession(c.Request().Context(), sessionID)
+// updateToolSession applies a new configuration to an existing session
+// without restarting its process.
+func (s *Server) updateToolSession(ctx context.Context, spawn toolSessionSpawn) (int, map[string]interface{}) {
+	id := spawn.SessionID
+	_, err := s.toolSess.UpdateSessionConfig(ctx, id, spawn.Tool, spawn.Title, spawn.Command, spawn.Workdir)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+			return http.StatusNotFound, map[string]interface{}{"error": "session not found"}
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return http.StatusInternalServerError, map[string]interface{}{"error": err.Error()}
 	}
-	if sid := s.currentToolSessionID(c); sid != "" {
-		if sid != strings.TrimSpace(sessionID) {
-			return c.JSON(http.StatusForbidden, map[string]string{"error": "session does not belong to current token"})
-		}
-		return nil
+	if err := s.toolSess.UpdateSessionMetadata(ctx, id, spawn.Metadata); err != nil {
+		return http.StatusInternalServerError, map[string]interface{}{"error": err.Error()}
 	}
-	owner := s.currentUsername(c)
-	if strings.TrimSpace(sess.Owner) != "" && sess.Owner != owner {
-		return c.JSON(http.StatusForbidden, map[string]string{"error": "session does not belong to current user"})
+	updatedSession, err := s.toolSess.GetSession(ctx, id)
+	if err != nil {
+		return http.StatusInternalServerError, map[string]interface{}{"error": "failed to reload tool session: " + err.Error()}
 	}
-	return nil
+
+	status, payload := s.applyToolSessionAccess(ctx, updatedSession, spawn)
+	if status != http.StatusOK {
+		return status, payload
+	}
+	if err := s.toolSess.AppendEvent(context.Background(), id, "session_updated", map[string]interface{}{
+		"command":    spawn.Command,
+		"workdir":    spawn.Workdir,
+		"proxy_mode": spawn.ProxyMode,
+	}); err != nil {
+		s.logger.Warn("Failed to append tool session update event",
+			zap.String("session_id", id),
+			zap.Error(err),
+		)
+	}
+	return http.StatusOK, payload
 }
 
-func (s *Server) authProfileFromContext(c *echo.Context) (*config.AuthProfile, error) {
-	userID := s.currentUserID(c)
-	if strings.TrimSpace(userID) != "" {
-		profile, err := config.BuildAuthProfileByUserID(c.Request().Context(), s.entClient, userID)
-		if err == nil {
-			return profile, nil
+// applyToolSessionAccess applies the access mode or password a restart or
+// update asked for and returns the session's access details.
+func (s *Server) applyToolSessionAccess(ctx context.Context, sess *toolsessions.Session, spawn toolSessionSpawn) (int, map[string]interface{}) {
+	id := sess.ID
+	accessPassword := ""
+	modeChanged := spawn.AccessMode != "" || strings.TrimSpace(spawn.AccessPassword) != ""
+	if modeChanged {
+		mode := spawn.AccessMode
+		if mode == "" {
+			mode = strings.TrimSpace(sess.AccessMode)
 		}
-		if !errors.Is(err, config.ErrAdminNotInitialized) {
-			return nil, err
+		var err error
+		accessPassword, err = s.toolSess.ConfigureSessionAccess(ctx, id, mode, spawn.AccessPassword)
+		if err != nil {
+			return http.StatusBadRequest, map[string]interface{}{"error": "failed to configure session access: " + err.Error()}
+		}
+		sess, err = s.toolSess.GetSession(ctx, id)
+		if err != nil {
+			return http.StatusInternalServerError, map[string]interface{}{"error": "failed to reload tool session: " + err.Error()}
 		}
 	}
 
-	username := s.currentUsername(c)
-	if strings.TrimSpace(username) == "" {
-		return nil, config.ErrAdminNotInitialized
+	accessURL := ""
+	if strings.TrimSpace(sess.AccessMode) != "" && sess.AccessMode != toolsessions.AccessModeNone {
+		accessURL = toolSessionAccessURL(spawn.AccessBaseURL, id)
+	}
+	return http.StatusOK, map[string]interface{}{
+		"session":         sess,
+		"access_mode":     sess.AccessMode,
+		"access_url":      accessURL,
+		"access_password": accessPassword,
 	}
-	return config.BuildAuthProfileByUsername(c.Request().Context(), s.entClient, username)
 }
 
-func (s *Server) authProfileResponse(profile *config.AuthProfile) map[string]interface{} {
-	if profile == nil {
-		return map[string]interface{}{}
-	}
-	return map[string]interface{}{
-		"id":          profile.UserID,
-		"username":    profile.Username,
-		"nickname":    profile.Nickname,
-		"role":        profile.Role,
-		"tenant_id":   profile.TenantID,
-		"tenant_slug": profile.TenantSlug,
+func (s *Server) handleRestartToolSession(c *echo.Context) error {
+	if s.toolSess == nil || s.processMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
 	}
-}
 
-func (s *Server) currentUserID(c *echo.Context) string {
-	user := c.Get("user")
-	token, ok := user.(*jwt.Token)
-	if !ok || token == nil {
-		return ""
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
 	}
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return ""
+	if ok, err := s.ensureSessionOwner(c, id); !ok {
+		return err
+	}
+	current, err := s.toolSess.GetSession(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	uid, _ := claims["uid"].(string)
-	return strings.TrimSpace(uid)
-}
 
-func (s *Server) currentUsername(c *echo.Context) string {
-	user := c.Get("user")
-	token, ok := user.(*jwt.Token)
-	if !ok || token == nil {
-		return ""
+	spawn, err := s.bindToolSessionRelaunch(c, current, toolSessionSpawnRestart)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return ""
+	if s.config.ToolSessions.RequireApproval {
+		return s.requestToolSessionSpawnApproval(c, spawn)
 	}
-	sub, _ := claims["sub"].(string)
-	return strings.TrimSpace(sub)
+	status, payload := s.restartToolSession(s.spawnRequestContext(c), spawn)
+	return c.JSON(status, payload)
 }
 
-func (s *Server) currentUserRole(c *echo.Context) string {
-	user := c.Get("user")
-	token, ok := user.(*jwt.Token)
-	if !ok || token == nil {
-		return ""
+// restartToolSession stops the session's process and starts the spawn's
+// command in its place.
+func (s *Server) restartToolSession(ctx context.Context, spawn toolSessionSpawn) (int, map[string]interface{}) {
+	id := spawn.SessionID
+	command := spawn.Command
+	workdir := spawn.Workdir
+	proxyMode := spawn.ProxyMode
+	nextMetadata := cloneMap(spawn.Metadata)
+	transport := s.resolveSessionRuntimeTransport(nextMetadata, spawn.RuntimeTransport)
+
+	launchCommand := applyToolProxyToCommand(command, proxyMode, spawn.ProxyURL)
+	runtimeSession := ""
+	if wrapped, sessionName := buildToolRuntimeLaunchWithTransport(transport, launchCommand, id); sessionName != "" {
+		launchCommand = wrapped
+		runtimeSession = sessionName
+		nextMetadata = runtimeagents.ApplyLaunchMetadata(nextMetadata, runtimeagents.LaunchInfo{
+			TransportName: transport.Name(),
+			SessionName:   runtimeSession,
+			LaunchCommand: launchCommand,
+		})
+	} else {
+		delete(nextMetadata, runtimeagents.MetadataRuntimeTransport)
+		delete(nextMetadata, runtimeagents.MetadataRuntimeSession)
+		delete(nextMetadata, runtimeagents.MetadataTmuxSession)
 	}
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return ""
+	nextMetadata[runtimeagents.MetadataLaunchCommand] = launchCommand
+
+	if err := s.processMgr.Reset(id); err != nil {
+		s.logger.Warn("Failed to reset tool process before restart",
+			zap.String("session_id", id),
+			zap.Error(err),
+		)
+	}
+	s.tryKillRuntimeSession(ctx, id)
+	spec := execenv.StartSpecFromContext(ctx, id, launchCommand, workdir, nextMetadata)
+	if err := s.processMgr.StartWithSpec(context.Background(), spec); err != nil {
+		if terminateErr := s.toolSess.TerminateSession(context.Background(), id, "failed to restart process: "+err.Error()); terminateErr != nil {
+			s.logger.Warn("Failed to terminate tool session after restart error",
+				zap.String("session_id", id),
+				zap.Error(terminateErr),
+			)
+		}
+		return http.StatusBadRequest, map[string]interface{}{"error": "failed to restart tool process: " + err.Error()}
 	}
-	role, _ := claims["role"].(string)
-	return strings.TrimSpace(role)
-}
 
-func (s *Server) currentTenantID(c *echo.Context) string {
-	user := c.Get("user")
-	token, ok := user.(*jwt.Token)
-	if !ok || token == nil {
-		return ""
+	_, err := s.toolSess.UpdateSessionLaunch(ctx, id, spawn.Tool, spawn.Title, command, workdir)
+	if err != nil {
+		return http.StatusInternalServerError, map[string]interface{}{"error": err.Error()}
 	}
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return ""
+	if err := s.toolSess.UpdateSessionMetadata(ctx, id, nextMetadata); err != nil {
+		return http.StatusInternalServerError, map[string]interface{}{"error": err.Error()}
+	}
+	updatedSession, err := s.toolSess.GetSession(ctx, id)
+	if err != nil {
+		return http.StatusInternalServerError, map[string]interface{}{"error": "failed to reload tool session: " + err.Error()}
 	}
-	tid, _ := claims["tid"].(string)
-	return strings.TrimSpace(tid)
-}
 
-func (s *Server) authContextFromEcho(c *echo.Context) ownership.AuthContext {
-	return ownership.AuthContext{
-		UserID:   s.currentUserID(c),
-		TenantID: s.currentTenantID(c),
-		Role:     s.currentUserRole(c),
+	status, payload := s.applyToolSessionAccess(ctx, updatedSession, spawn)
+	if status != http.StatusOK {
+		return status, payload
 	}
-}
 
-func (s *Server) currentToolSessionID(c *echo.Context) string {
-	user := c.Get("user")
-	token, ok := user.(*jwt.Token)
-	if !ok || token == nil {
-		return ""
-	}
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return ""
+	eventPayload := runtimeagents.ApplyRuntimeSessionPayload(map[string]interface{}{
+		"command":    command,
+		"launch_cmd": launchCommand,
+		"workdir":    workdir,
+		"proxy_mode": proxyMode,
+	}, transport.Name(), runtimeSession)
+	eventPayload[runtimeagents.MetadataRuntimeTransport] = strings.TrimSpace(transport.Name())
+	if err := s.toolSess.AppendEvent(context.Background(), id, "process_restarted", eventPayload); err != nil {
+		s.logger.Warn("Failed to append tool session restart event",
+			zap.String("session_id", id),
+			zap.Error(err),
+		)
 	}
-	sid, _ := claims["sid"].(string)
-	return strings.TrimSpace(sid)
+	return http.StatusOK, payload
 }
 
-func (s *Server) allowMemberAPI(c *echo.Context) bool {
-	if strings.TrimSpace(s.currentToolSessionID(c)) == "" {
-		return false
-	}
-	path := strings.TrimSpace(c.Request().URL.Path)
-	method := c.Request().Method
-	if method == http.MethodGet && path == "/api/tool-sessions" {
-		return true
+func (s *Server) handleToolSessionProcessStatus(c *echo.Context) error {
+	if s.processMgr == nil || s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
 	}
-	if method == http.MethodGet && path == "/api/tool-sessions/runtime-transports" {
-		return true
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
 	}
-	if method == http.MethodPost && path == "/api/auth/stream-token" {
-		return true
+	if ok, err := s.ensureSessionOwner(c, id); !ok {
+		return err
 	}
-	if strings.HasPrefix(path, "/api/tool-sessions/") {
-		switch {
-		case strings.HasSuffix(path, "/process/status"):
-			return method == http.MethodGet
-		case strings.HasSuffix(path, "/process/output"):
-			return method == http.MethodGet
-		case strings.HasSuffix(path, "/process/input"):
-			return method == http.MethodPost
-		case strings.HasSuffix(path, "/process/kill"):
-			return method == http.MethodPost
+	s.tryRestoreToolSessionRuntime(c.Request().Context(), id)
+	sess, sessErr := s.toolSess.GetSession(c.Request().Context(), id)
+	if sessErr != nil {
+		if errors.Is(sessErr, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
 		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": sessErr.Error()})
 	}
-	return false
-}
 
-func (s *Server) requirePrivilegedAPIUser() echo.MiddlewareFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c *echo.Context) error {
-			role := s.currentUserRole(c)
-			switch role {
-			case "admin", "owner":
-				return next(c)
-			case "member":
-				if s.allowMemberAPI(c) {
-					return next(c)
-				}
-				return c.JSON(http.StatusForbidden, map[string]string{"error": "insufficient role for privileged api"})
-			default:
-				return c.JSON(http.StatusForbidden, map[string]string{"error": "insufficient role for privileged api"})
-			}
+	status, err := s.processMgr.GetStatus(id)
+	if err != nil {
+		if isProcessSessionNotFound(err) {
+			return c.JSON(http.StatusOK, map[string]interface{}{
+				"id":                id,
+				"running":           false,
+				"missing":           true,
+				"runtime_transport": metadataString(sess.Metadata, runtimeagents.MetadataRuntimeTransport),
+				"runtime_session":   metadataString(sess.Metadata, runtimeagents.MetadataRuntimeSession),
+				"tmux_session":      metadataString(sess.Metadata, runtimeagents.MetadataTmuxSession),
+				"launch_cmd":        metadataString(sess.Metadata, runtimeagents.MetadataLaunchCommand),
+			})
 		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"id":                status.ID,
+		"running":           status.Running,
+		"exit_code":         status.ExitCode,
+		"runtime_transport": metadataString(sess.Metadata, runtimeagents.MetadataRuntimeTransport),
+		"runtime_session":   metadataString(sess.Metadata, runtimeagents.MetadataRuntimeSession),
+		"tmux_session":      metadataString(sess.Metadata, runtimeagents.MetadataTmuxSession),
+		"launch_cmd":        metadataString(sess.Metadata, runtimeagents.MetadataLaunchCommand),
+		"observation":       status.Observation,
+	})
 }
 
-func resolveToolCommand(toolName, command string) string {
-	cmd := strings.TrimSpace(command)
-	if cmd != "" {
-		return cmd
+func (s *Server) handleToolSessionProcessOutput(c *echo.Context) error {
+	if s.processMgr == nil || s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
 	}
-	tool := strings.TrimSpace(strings.ToLower(toolName))
-	switch tool {
-	case "codex":
-		return "codex"
-	case "claude":
-		return "claude"
-	case "opencode":
-		return "opencode"
-	case "aider":
-		return "aider"
-	default:
-		return strings.TrimSpace(toolName)
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
 	}
-}
-
-func resolveToolCommandWithArgs(toolName, command, commandArgs string) string {
-	args := strings.TrimSpace(commandArgs)
-	if args == "" {
-		return resolveToolCommand(toolName, command)
+	if ok, err := s.ensureSessionOwner(c, id); !ok {
+		return err
 	}
-	base := resolveToolCommand(toolName, "")
-	if base == "" {
-		base = strings.TrimSpace(toolName)
+	s.tryRestoreToolSessionRuntime(c.Request().Context(), id)
+
+	offset := 0
+	limit := 300
+	if raw := strings.TrimSpace(c.QueryParam("offset")); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v >= 0 {
+			offset = v
+		}
 	}
-	if base == "" {
-		return ""
+	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v > 0 && v <= 2000 {
+			limit = v
+		}
 	}
-	return strings.TrimSpace(base + " " + args)
-}
-
-const (
-	toolProxyModeInherit = "inherit"
-	toolProxyModeClear   = "clear"
-	toolProxyModeCustom  = "custom"
-)
 
-func normalizeToolProxyMode(mode string) string {
-	switch strings.TrimSpace(strings.ToLower(mode)) {
-	case toolProxyModeClear:
-		return toolProxyModeClear
-	case toolProxyModeCustom:
-		return toolProxyModeCustom
-	default:
-		return toolProxyModeInherit
+	lines, total, err := s.processMgr.GetOutput(id, offset, limit)
+	if err != nil {
+		if isProcessSessionNotFound(err) {
+			return c.JSON(http.StatusOK, map[string]interface{}{
+				"lines":   []string{},
+				"total":   0,
+				"running": false,
+				"missing": true,
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-}
 
-func cloneMap(src map[string]interface{}) map[string]interface{} {
-	if len(src) == 0 {
-		return map[string]interface{}{}
+	status, statusErr := s.processMgr.GetStatus(id)
+	if statusErr == nil && status.Running {
+		if err := s.toolSess.TouchSession(c.Request().Context(), id, toolsessions.StateRunning); err != nil {
+			s.logger.Warn("Failed to touch running tool session",
+				zap.String("session_id", id),
+				zap.Error(err),
+			)
+		}
+	} else if statusErr == nil && !status.Running {
+		if sess, err := s.toolSess.GetSession(c.Request().Context(), id); err == nil &&
+			sess.State != toolsessions.StateTerminated &&
+			sess.State != toolsessions.StateArchived {
+			if err := s.toolSess.TerminateSession(c.Request().Context(), id, fmt.Sprintf("process exited with code %d", status.ExitCode)); err != nil {
+				s.logger.Warn("Failed to terminate stopped tool session",
+					zap.String("session_id", id),
+					zap.Int("exit_code", status.ExitCode),
+					zap.Error(err),
+				)
+			}
+		}
 	}
-	dst := make(map[string]interface{}, len(src))
-	for k, v := range src {
-		dst[k] = v
+	running := statusErr == nil && status.Running
+	exitCode := 0
+	if statusErr == nil {
+		exitCode = status.ExitCode
 	}
-	return dst
-}
 
-func toolProxyFromMetadata(metadata map[string]interface{}) (string, string) {
-	if len(metadata) == 0 {
-		return toolProxyModeInherit, ""
-	}
-	rawMode, _ := metadata["proxy_mode"].(string)
-	mode := normalizeToolProxyMode(rawMode)
-	rawURL, _ := metadata["proxy_url"].(string)
-	if mode != toolProxyModeCustom {
-		return mode, ""
-	}
-	return mode, strings.TrimSpace(rawURL)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"lines":     lines,
+		"total":     total,
+		"running":   running,
+		"exit_code": exitCode,
+	})
 }
 
-func withToolProxyMetadata(metadata map[string]interface{}, mode, proxyURL string) map[string]interface{} {
-	if metadata == nil {
-		metadata = map[string]interface{}{}
-	}
-	mode = normalizeToolProxyMode(mode)
-	metadata["proxy_mode"] = mode
-	if mode == toolProxyModeCustom && strings.TrimSpace(proxyURL) != "" {
-		metadata["proxy_url"] = strings.TrimSpace(proxyURL)
-	} else {
-		delete(metadata, "proxy_url")
+// handleToolSessionStream is a view-only SSE mirror of the tool session WS
+// output loop for embedders that cannot speak the WS protocol. Each "output"
+// event carries the output cursor as its id, so reconnecting clients resume
+// through Last-Event-ID. encoding=base64 (default) keeps terminal bytes
+// intact for xterm.js; encoding=raw sends plain text lines and drops
+// carriage returns, which SSE cannot carry.
+func (s *Server) handleToolSessionStream(c *echo.Context) error {
+	if s.processMgr == nil || s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
 	}
-	return metadata
-}
-
-func resolveToolProxyConfig(existingMode, existingURL, inputMode, inputURL string) (string, string, error) {
-	mode := normalizeToolProxyMode(existingMode)
-	urlValue := strings.TrimSpace(existingURL)
-
-	if strings.TrimSpace(inputMode) != "" {
-		mode = normalizeToolProxyMode(inputMode)
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
 	}
-	if strings.TrimSpace(inputURL) != "" {
-		urlValue = strings.TrimSpace(inputURL)
+	if ok, err := s.ensureSessionOwner(c, id); !ok {
+		return err
 	}
 
-	if mode != toolProxyModeCustom {
-		return mode, "", nil
+	offset := 0
+	rawOffset := strings.TrimSpace(c.QueryParam("offset"))
+	if rawOffset == "" {
+		rawOffset = strings.TrimSpace(c.Request().Header.Get("Last-Event-ID"))
+	}
+	if rawOffset != "" {
+		v, err := strconv.Atoi(rawOffset)
+		if err != nil || v < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "offset must be a non-negative integer"})
+		}
+		offset = v
+	}
+	encoding := strings.ToLower(strings.TrimSpace(c.QueryParam("encoding")))
+	switch encoding {
+	case "":
+		encoding = "base64"
+	case "base64", "raw":
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "encoding must be base64 or raw"})
 	}
-	if urlValue == "" {
-		return "", "", fmt.Errorf("proxy url is required when proxy mode is custom")
+	s.tryRestoreToolSessionRuntime(c.Request().Context(), id)
+
+	res := c.Response()
+	req := c.Request()
+	flusher, ok := res.(http.Flusher)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "streaming unsupported"})
 	}
-	parsed, err := url.Parse(urlValue)
-	if err != nil || strings.TrimSpace(parsed.Scheme) == "" {
-		return "", "", fmt.Errorf("invalid proxy url")
+	res.Header().Set("Content-Type", "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	_, _ = res.Write([]byte(": connected\n\n"))
+	flusher.Flush()
+
+	writeEvent := func(event, eventID string, lines ...string) {
+		var b strings.Builder
+		if eventID != "" {
+			b.WriteString("id: " + eventID + "\n")
+		}
+		b.WriteString("event: " + event + "\n")
+		for _, line := range lines {
+			b.WriteString("data: " + line + "\n")
+		}
+		b.WriteString("\n")
+		_, _ = res.Write([]byte(b.String()))
+		flusher.Flush()
+	}
+	flushOutput := func() {
+		chunks, total, err := s.processMgr.GetOutput(id, offset, 500)
+		if err != nil {
+			return
+		}
+		offset = total
+		if len(chunks) == 0 {
+			return
+		}
+		data := strings.Join(chunks, "")
+		if encoding == "raw" {
+			writeEvent("output", strconv.Itoa(total), strings.Split(strings.ReplaceAll(data, "\r", ""), "\n")...)
+			return
+		}
+		writeEvent("output", strconv.Itoa(total), base64.StdEncoding.EncodeToString([]byte(data)))
+	}
+	writeExit := func(payload map[string]interface{}) {
+		encoded, _ := json.Marshal(payload)
+		writeEvent("exit", "", string(encoded))
 	}
-	return mode, urlValue, nil
-}
 
-func applyToolProxyToCommand(command, proxyMode, proxyURL string) string {
-	cmd := strings.TrimSpace(command)
-	mode := normalizeToolProxyMode(proxyMode)
-	switch mode {
-	case toolProxyModeClear:
-		return "env -u HTTP_PROXY -u HTTPS_PROXY -u ALL_PROXY -u NO_PROXY -u http_proxy -u https_proxy -u all_proxy -u no_proxy " + cmd
-	case toolProxyModeCustom:
-		value := strconv.Quote(strings.TrimSpace(proxyURL))
-		return fmt.Sprintf("env HTTP_PROXY=%s HTTPS_PROXY=%s ALL_PROXY=%s http_proxy=%s https_proxy=%s all_proxy=%s %s",
-			value, value, value, value, value, value, cmd)
-	default:
-		return cmd
+	ticker := time.NewTicker(220 * time.Millisecond)
+	defer ticker.Stop()
+	pingTicker := time.NewTicker(30 * time.Second)
+	defer pingTicker.Stop()
+
+	for {
+		flushOutput()
+
+		status, err := s.processMgr.GetStatus(id)
+		if err != nil {
+			writeExit(map[string]interface{}{"missing": isProcessSessionNotFound(err)})
+			return nil
+		}
+		if !status.Running {
+			// Output written between the read above and the exit is sent before ending.
+			flushOutput()
+			if rec, err := s.toolSess.GetSession(context.Background(), id); err == nil &&
+				rec.State != toolsessions.StateTerminated &&
+				rec.State != toolsessions.StateArchived {
+				if err := s.toolSess.TerminateSession(context.Background(), id, fmt.Sprintf("process exited with code %d", status.ExitCode)); err != nil {
+					s.logger.Warn("Failed to terminate streamed tool session after exit",
+						zap.String("session_id", id),
+						zap.Int("exit_code", status.ExitCode),
+						zap.Error(err),
+					)
+				}
+			}
+			writeExit(map[string]interface{}{"exit_code": status.ExitCode})
+			return nil
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil
+		case <-pingTicker.C:
+			_, _ = res.Write([]byte(": ping\n\n"))
+			flusher.Flush()
+		case <-ticker.C:
+		}
 	}
 }
 
-func (s *Server) tryRestoreToolSessionRuntime(ctx context.Context, sessionID string) bool {
+func (s *Server) handleToolSessionProcessInput(c *echo.Context) error {
 	if s.processMgr == nil || s.toolSess == nil {
-		return false
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
 	}
-	id := strings.TrimSpace(sessionID)
+	id := strings.TrimSpace(c.Param("id"))
 	if id == "" {
-		return false
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
 	}
-	if _, err := s.processMgr.GetStatus(id); err == nil {
-		return true
-	} else if !isProcessSessionNotFound(err) {
-		return false
+	if ok, err := s.ensureSessionOwner(c, id); !ok {
+		return err
 	}
+	s.tryRestoreToolSessionRuntime(c.Request().Context(), id)
 
-	sess, err := s.toolSess.GetSession(ctx, id)
-	if err != nil {
-		return false
-	}
-	if sess.State == toolsessions.StateTerminated || sess.State == toolsessions.StateArchived {
-		return false
+	var body struct {
+		Data string `json:"data"`
 	}
-
-	transport := s.resolveSessionRuntimeTransport(sess.Metadata, "")
-	attachCmd, runtimeSession, ok := buildToolReattachLaunchWithTransport(transport, id)
-	if !ok {
-		return false
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	workdir := strings.TrimSpace(sess.Workdir)
-	if workdir == "" {
-		workdir = s.config.WorkspacePath()
+	if body.Data == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "input data is required"})
 	}
-	spec := execenv.StartSpecFromContext(ctx, id, attachCmd, workdir, sess.Metadata)
-	if err := s.processMgr.StartWithSpec(context.Background(), spec); err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "session already exists") {
-			return true
+
+	if err := s.processMgr.Write(id, body.Data); err != nil {
+		if isProcessSessionNotFound(err) {
+			if s.tryRestoreToolSessionRuntime(c.Request().Context(), id) {
+				retryErr := s.processMgr.Write(id, body.Data)
+				if retryErr == nil {
+					if touchErr := s.toolSess.TouchSession(c.Request().Context(), id, toolsessions.StateRunning); touchErr != nil {
+						s.logger.Warn("Failed to touch restored tool session after input retry",
+							zap.String("session_id", id),
+							zap.Error(touchErr),
+						)
+					}
+					return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+				}
+				err = retryErr
+			}
 		}
-		s.logger.Warn("Failed to restore tool session runtime",
+		if isProcessSessionNotFound(err) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "tool process not found"})
+		}
+		if strings.Contains(strings.ToLower(err.Error()), "not running") {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "tool process is not running"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if err := s.toolSess.TouchSession(c.Request().Context(), id, toolsessions.StateRunning); err != nil {
+		s.logger.Warn("Failed to touch tool session after process input",
 			zap.String("session_id", id),
-			zap.String("runtime_session", runtimeSession),
 			zap.Error(err),
 		)
-		return false
 	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
 
-	metadata := cloneMap(sess.Metadata)
-	metadata = runtimeagents.ApplyLaunchMetadata(metadata, runtimeagents.LaunchInfo{
-		TransportName: transport.Name(),
-		SessionName:   runtimeSession,
-		LaunchCommand: attachCmd,
-	})
-	if err := s.toolSess.UpdateSessionMetadata(context.Background(), id, metadata); err != nil {
-		s.logger.Warn("Failed to persist restored tool session metadata",
-			zap.String("session_id", id),
-			zap.String("runtime_session", runtimeSession),
-			zap.Error(err),
-		)
+func (s *Server) handleToolSessionProcessKill(c *echo.Context) error {
+	if s.processMgr == nil || s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+	if ok, err := s.ensureSessionOwner(c, id); !ok {
+		return err
 	}
 
-	eventPayload := runtimeagents.ApplyRuntimeSessionPayload(map[string]interface{}{
-		"launch_cmd": attachCmd,
-		"workdir":    workdir,
-	}, transport.Name(), runtimeSession)
-	eventPayload[runtimeagents.MetadataRuntimeTransport] = strings.TrimSpace(transport.Name())
-	if err := s.toolSess.AppendEvent(context.Background(), id, "process_restored", eventPayload); err != nil {
-		s.logger.Warn("Failed to append tool session restore event",
-			zap.String("session_id", id),
-			zap.Error(err),
-		)
+	err := s.processMgr.Kill(id)
+	if err != nil && !isProcessSessionNotFound(err) && !strings.Contains(strings.ToLower(err.Error()), "not running") {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if err := s.toolSess.TouchSession(context.Background(), id, toolsessions.StateRunning); err != nil {
-		s.logger.Warn("Failed to touch restored tool session",
+	s.tryKillRuntimeSession(c.Request().Context(), id)
+	if err := s.toolSess.TerminateSession(c.Request().Context(), id, "killed from webui"); err != nil {
+		s.logger.Warn("Failed to terminate tool session after kill",
 			zap.String("session_id", id),
 			zap.Error(err),
 		)
 	}
-	s.logger.Info("Restored tool session runtime",
-		zap.String("session_id", id),
-		zap.String("runtime_session", runtimeSession),
-	)
-	return true
-}
-
-func runtimeTransportAvailable() bool {
-	return runtimeagents.DefaultTransport().Available()
-}
-
-func buildToolRuntimeLaunch(command, sessionID string) (string, string) {
-	launchInfo := runtimeagents.DefaultTransport().WrapStart(command, sessionID)
-	return launchInfo.LaunchCommand, launchInfo.SessionName
-}
-
-func buildToolReattachLaunch(sessionID string) (string, string, bool) {
-	reattachInfo, ok := runtimeagents.DefaultTransport().BuildReattach(sessionID)
-	return reattachInfo.LaunchCommand, reattachInfo.SessionName, ok
-}
-
-func buildToolRuntimeLaunchWithTransport(transport runtimeagents.RuntimeTransport, command, sessionID string) (string, string) {
-	if transport == nil {
-		transport = runtimeagents.DefaultTransport()
-	}
-	launchInfo := transport.WrapStart(command, sessionID)
-	return launchInfo.LaunchCommand, launchInfo.SessionName
+	return c.JSON(http.StatusOK, map[string]string{"status": "killed"})
 }
 
-func buildToolReattachLaunchWithTransport(transport runtimeagents.RuntimeTransport, sessionID string) (string, string, bool) {
-	if transport == nil {
-		transport = runtimeagents.DefaultTransport()
+func (s *Server) handleUpdateToolSessionAccess(c *echo.Context) error {
+	if s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
 	}
-	reattachInfo, ok := transport.BuildReattach(sessionID)
-	return reattachInfo.LaunchCommand, reattachInfo.SessionName, ok
-}
-
-func metadataString(values map[string]interface{}, key string) string {
-	if len(values) == 0 {
-		return ""
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
 	}
-	value, _ := values[key].(string)
-	return strings.TrimSpace(value)
-}
-
-func runtimeTransportFromName(name string) runtimeagents.RuntimeTransport {
-	return runtimeagents.TransportByName(name)
-}
-
-func (s *Server) handleListToolSessionRuntimeTransports(c *echo.Context) error {
-	defaultName := s.defaultRuntimeTransport().Name()
-	items := []map[string]interface{}{
-		{
-			"name":       runtimeagents.TransportTmux,
-			"available":  runtimeagents.TransportByName(runtimeagents.TransportTmux).Available(),
-			"is_default": defaultName == runtimeagents.TransportTmux,
-		},
-		{
-			"name":       runtimeagents.TransportZellij,
-			"available":  runtimeagents.TransportByName(runtimeagents.TransportZellij).Available(),
-			"is_default": defaultName == runtimeagents.TransportZellij,
-		},
+	if ok, err := s.ensureSessionOwner(c, id); !ok {
+		return err
 	}
-	return c.JSON(http.StatusOK, map[string]interface{}{"items": items})
-}
-
-func (s *Server) defaultRuntimeTransport() runtimeagents.RuntimeTransport {
-	if s == nil || s.config == nil {
-		return runtimeagents.DefaultTransport()
+	var body struct {
+		Mode          string `json:"mode"`
+		Password      string `json:"password"`
+		PublicBaseURL string `json:"public_base_url"`
 	}
-	name := strings.TrimSpace(s.config.WebUI.ToolSessionRuntimeTransport)
-	if name == "" {
-		return runtimeagents.DefaultTransport()
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	return runtimeTransportFromName(name)
-}
-
-func (s *Server) resolveSessionRuntimeTransport(metadata map[string]interface{}, requested string) runtimeagents.RuntimeTransport {
-	requested = strings.TrimSpace(requested)
-	if requested != "" {
-		return runtimeTransportFromName(requested)
+	mode := strings.TrimSpace(body.Mode)
+	if mode == "" {
+		mode = toolsessions.AccessModeNone
 	}
-	stored := runtimeagents.MetadataString(metadata, runtimeagents.MetadataRuntimeTransport)
-	if stored != "" {
-		return runtimeTransportFromName(stored)
-	}
-	return s.defaultRuntimeTransport()
-}
-
-func (s *Server) tryKillRuntimeSession(ctx context.Context, sessionID string) {
-	transport := s.defaultRuntimeTransport()
-	if s != nil && s.toolSess != nil {
-		if sess, err := s.toolSess.GetSession(ctx, sessionID); err == nil && sess != nil {
-			transport = s.resolveSessionRuntimeTransport(sess.Metadata, "")
+	password, err := s.toolSess.ConfigureSessionAccess(c.Request().Context(), id, mode, body.Password)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
 		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	transport.KillSession(sessionID)
-}
-
-func isProcessSessionNotFound(err error) bool {
-	if err == nil {
-		return false
+	sess, err := s.toolSess.GetSession(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return strings.Contains(strings.ToLower(err.Error()), "session not found")
+	accessURL := ""
+	if sess.AccessMode != toolsessions.AccessModeNone {
+		accessURL = s.buildToolSessionAccessURL(c, id, body.PublicBaseURL)
+	}
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionToolSessionAccess,
+		Target:  id,
+		Success: true,
+		Summary: "access mode set to " + sess.AccessMode,
+	})
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"session":         sess,
+		"access_mode":     sess.AccessMode,
+		"access_url":      accessURL,
+		"access_password": password,
+	})
 }
 
-func (s *Server) buildToolSessionAccessURL(c *echo.Context, sessionID, overrideBase string) string {
-	base := strings.TrimSpace(overrideBase)
-	if base == "" {
-		base = strings.TrimSpace(s.config.WebUI.PublicBaseURL)
-	}
-	if base == "" {
-		base = requestScheme(c) + "://" + c.Request().Host
+func (s *Server) handleUpdateToolSessionSpectator(c *echo.Context) error {
+	if s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
 	}
-	if !strings.Contains(base, "://") {
-		base = requestScheme(c) + "://" + strings.TrimPrefix(base, "/")
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
 	}
-	base = strings.TrimRight(base, "/")
-	values := url.Values{}
-	values.Set("tab", "tools")
-	values.Set("tool_session", strings.TrimSpace(sessionID))
-	return base + "/?" + values.Encode()
-}
-
-func requestScheme(c *echo.Context) string {
-	scheme := "http"
-	if c.Request().TLS != nil {
-		scheme = "https"
+	if ok, err := s.ensureSessionOwner(c, id); !ok {
+		return err
 	}
-	if forwardedProto := strings.TrimSpace(c.Request().Header.Get("X-Forwarded-Proto")); forwardedProto != "" {
-		scheme = forwardedProto
+	var body struct {
+		Enabled       bool   `json:"enabled"`
+		PublicBaseURL string `json:"public_base_url"`
 	}
-	return scheme
-}
-
-func (s *Server) resolveSkill(id string) (*skills.Skill, bool) {
-	trimmedID := strings.TrimSpace(id)
-	if trimmedID == "" {
-		return nil, false
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-
-	for _, skill := range s.skillsMgr.List() {
-		if strings.TrimSpace(skill.ID) == trimmedID {
-			return skill, true
+	token, err := s.toolSess.ConfigureSpectatorAccess(c.Request().Context(), id, body.Enabled)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
 		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-
-	return nil, false
+	sess, err := s.toolSess.GetSession(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	spectatorURL := ""
+	summary := "spectator link disabled"
+	if token != "" {
+		spectatorURL = s.buildToolSessionSpectatorURL(c, id, token, body.PublicBaseURL)
+		summary = "spectator link issued"
+	}
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionToolSessionAccess,
+		Target:  id,
+		Success: true,
+		Summary: summary,
+	})
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"session":           sess,
+		"spectator_enabled": sess.SpectatorEnabled,
+		"spectator_url":     spectatorURL,
+	})
 }
 
-// --- Skills Handlers ---
+func (s *Server) handleGenerateToolSessionOTP(c *echo.Context) error {
+	if s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+	if ok, err := s.ensureSessionOwner(c, id); !ok {
+		return err
+	}
 
-func (s *Server) handleListSkills(c *echo.Context) error {
-	if s.skillsMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "skills manager not available"})
+	var body struct {
+		TTLSeconds int `json:"ttl_seconds"`
+	}
+	_ = c.Bind(&body)
+	var ttl time.Duration
+	if body.TTLSeconds > 0 {
+		ttl = time.Duration(body.TTLSeconds) * time.Second
 	}
 
-	skillsList := s.skillsMgr.List()
-	sort.Slice(skillsList, func(i, j int) bool {
-		left := strings.TrimSpace(skillsList[i].ID)
-		right := strings.TrimSpace(skillsList[j].ID)
-		if left == right {
-			return strings.TrimSpace(skillsList[i].Name) < strings.TrimSpace(skillsList[j].Name)
+	code, expiresAt, err := s.toolSess.GenerateSessionOTP(c.Request().Context(), id, ttl)
+	if err != nil {
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		case errors.Is(err, os.ErrPermission):
+			return c.JSON(http.StatusConflict, map[string]string{"error": "external access is disabled for this session"})
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 		}
-		return left < right
-	})
-
-	items := make([]map[string]interface{}, 0, len(skillsList))
-	for _, skill := range skillsList {
-		items = append(items, s.skillItem(skill))
 	}
-
-	return c.JSON(http.StatusOK, items)
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionToolSessionAccess,
+		Target:  id,
+		Success: true,
+		Summary: "one-time access code issued until " + expiresAt.UTC().Format(time.RFC3339),
+	})
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"session_id":   id,
+		"otp_code":     code,
+		"expires_at":   expiresAt.Unix(),
+		"ttl_seconds":  int(time.Until(expiresAt).Seconds()),
+		"generated_at": time.Now().Unix(),
+	})
 }
 
-func (s *Server) handleGetSkill(c *echo.Context) error {
-	if s.skillsMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "skills manager not available"})
+func (s *Server) handleToolSessionAccessLogin(c *echo.Context) error {
+	if s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool session manager not available"})
 	}
-
-	skillID := strings.TrimSpace(c.Param("id"))
-	skill, ok := s.resolveSkill(skillID)
-	if !ok {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": "skill not found"})
+	var body struct {
+		SessionID string `json:"session_id"`
+		Password  string `json:"password"`
 	}
-
-	return c.JSON(http.StatusOK, s.skillItem(skill))
-}
-
-func (s *Server) handleGetSkillContent(c *echo.Context) error {
-	if s.skillsMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "skills manager not available"})
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-
-	skillID := strings.TrimSpace(c.Param("id"))
-	skill, ok := s.resolveSkill(skillID)
-	if !ok {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": "skill not found"})
+	sess, err := s.toolSess.VerifySessionAccess(c.Request().Context(), body.SessionID, body.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		case errors.Is(err, os.ErrPermission):
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or expired password"})
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
 	}
-
-	raw, bodyRaw, err := readSkillContent(skill)
+	username := "tool:" + strings.TrimSpace(sess.ID)
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub":  username,
+		"uid":  sess.Owner,
+		"role": "member",
+		"sid":  strings.TrimSpace(sess.ID),
+		"exp":  now.Add(24 * time.Hour).Unix(),
+		"iat":  now.Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signedToken, err := token.SignedString([]byte(s.getJWTSecret()))
 	if err != nil {
-		s.logger.Error("Failed to read skill content",
-			zap.String("skill_id", skillID),
-			zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to read skill content"})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "token generation failed"})
 	}
-
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"id":        strings.TrimSpace(skill.ID),
-		"name":      strings.TrimSpace(skill.Name),
-		"file_path": strings.TrimSpace(skill.FilePath),
-		"raw":       raw,
-		"body_raw":  bodyRaw,
+		"token":      signedToken,
+		"session_id": sess.ID,
 	})
 }
 
-func (s *Server) skillSnapshotPolicy() config.SkillSnapshotsConfig {
-	if s.config == nil {
-		return config.DefaultConfig().WebUI.SkillSnapshots
+// tenantWorkdir resolves the working directory of a tool session. An empty
+// workdir is the caller's tenant workspace; users outside the default tenant
+// must stay inside it.
+func (s *Server) tenantWorkdir(c *echo.Context, workdir string) (string, error) {
+	root := s.config.TenantWorkspacePath(s.currentTenantSlug(c))
+	workdir = strings.TrimSpace(workdir)
+	if workdir == "" {
+		if err := os.MkdirAll(root, 0o755); err != nil {
+			return "", fmt.Errorf("create tenant workspace: %w", err)
+		}
+		return root, nil
 	}
-	return s.config.WebUI.SkillSnapshots
+	if s.authContextFromEcho(c).InTenant("") {
+		return workdir, nil
+	}
+	if !filepath.IsAbs(workdir) {
+		workdir = filepath.Join(root, workdir)
+	}
+	workdir = filepath.Clean(workdir)
+	// Compare resolved paths, so a symlink inside the tenant workspace
+	// cannot lead outside it.
+	rel, err := filepath.Rel(resolveSymlinks(root), resolveSymlinks(workdir))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("workdir must stay inside the tenant workspace")
+	}
+	return workdir, nil
 }
 
-func (s *Server) skillVersionPolicy() config.SkillVersionsConfig {
-	if s.config == nil {
-		return config.DefaultConfig().WebUI.SkillVersions
+// resolveSymlinks resolves the symlinks in the longest existing prefix of
+// path. The part that does not exist yet is appended unchanged.
+func resolveSymlinks(path string) string {
+	rest := ""
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return path
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
 	}
-	return s.config.WebUI.SkillVersions
 }
 
-func (s *Server) skillItem(skill *skills.Skill) map[string]interface{} {
-	var report *skills.SkillEntry
-	if s != nil && s.skillsMgr != nil && skill != nil {
-		report, _ = s.skillsMgr.CheckRequirementsReport(skill.ID)
-	}
-	if report == nil {
-		report = &skills.SkillEntry{
-			Skill:     skill,
-			Eligible:  true,
-			Installed: skill != nil && !strings.HasPrefix(strings.TrimSpace(skill.FilePath), "builtin://"),
+// ensureSessionOwner reports whether the caller may act on a tool session.
+// When it may not, the error response has already been written and callers
+// return err as is.
+func (s *Server) ensureSessionOwner(c *echo.Context, sessionID string) (bool, error) {
+	sess, err := s.toolSess.GetSession(c.Request().Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
 		}
-		if skill != nil && skill.Requirements != nil {
-			eligible, reasons := skills.CheckEligibility(skill)
-			report.Eligible = eligible
-			report.Reasons = append([]string(nil), reasons...)
+		return false, c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if sid := s.currentToolSessionID(c); sid != "" {
+		if sid != strings.TrimSpace(sessionID) {
+			return false, c.JSON(http.StatusForbidden, map[string]string{"error": "session does not belong to current token"})
 		}
+		return true, nil
+	}
+	owner := s.currentUsername(c)
+	if strings.TrimSpace(sess.Owner) != "" && sess.Owner != owner {
+		return false, c.JSON(http.StatusForbidden, map[string]string{"error": "session does not belong to current user"})
 	}
+	return true, nil
+}
 
-	tags := append([]string(nil), skill.Tags...)
-	return map[string]interface{}{
-		"id":                    strings.TrimSpace(skill.ID),
-		"name":                  strings.TrimSpace(skill.Name),
-		"description":           strings.TrimSpace(skill.Description),
-		"version":               strings.TrimSpace(skill.Version),
-		"author":                strings.TrimSpace(skill.Author),
-		"enabled":               skill.Enabled,
-		"always":                skill.Always,
-		"file_path":             strings.TrimSpace(skill.FilePath),
-		"tags":                  tags,
-		"eligible":              report.Eligible,
-		"ineligibility_reasons": report.Reasons,
-		"missing_requirements": map[string]interface{}{
-			"binaries":        nonNilStrings(report.MissingBinaries),
-			"any_binaries":    nonNilStrings(report.MissingAnyBinaries),
-			"env":             nonNilStrings(report.MissingEnvVars),
-			"config_paths":    nonNilStrings(report.MissingPaths),
-			"python_packages": nonNilStrings(report.MissingPythonPackages),
-			"node_packages":   nonNilStrings(report.MissingNodePackages),
-		},
-		"install_specs": installSpecs(skill),
-		"is_installed":  report.Installed,
+func (s *Server) authProfileFromContext(c *echo.Context) (*config.AuthProfile, error) {
+	userID := s.currentUserID(c)
+	if strings.TrimSpace(userID) != "" {
+		profile, err := config.BuildAuthProfileByUserID(c.Request().Context(), s.entClient, userID)
+		if err == nil {
+			return profile, nil
+		}
+		if !errors.Is(err, config.ErrAdminNotInitialized) {
+			return nil, err
+		}
 	}
-}
 
-func (s *Server) handleGetWorkspaceStatus(c *echo.Context) error {
-	if s.workspace == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "workspace manager not available"})
+	username := s.currentUsername(c)
+	if strings.TrimSpace(username) == "" {
+		return nil, config.ErrAdminNotInitialized
 	}
+	return config.BuildAuthProfileByUsername(c.Request().Context(), s.entClient, username)
+}
 
-	status, err := s.workspace.Inspect()
-	if err != nil {
-		s.logger.Error("Failed to inspect workspace", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to inspect workspace"})
+func (s *Server) authProfileResponse(profile *config.AuthProfile) map[string]interface{} {
+	if profile == nil {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"id":          profile.UserID,
+		"username":    profile.Username,
+		"nickname":    profile.Nickname,
+		"role":        profile.Role,
+		"tenant_id":   profile.TenantID,
+		"tenant_slug": profile.TenantSlug,
 	}
-
-	return c.JSON(http.StatusOK, status)
 }
 
-func (s *Server) handleRepairWorkspace(c *echo.Context) error {
-	if s.workspace == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "workspace manager not available"})
+func (s *Server) currentUserID(c *echo.Context) string {
+	user := c.Get("user")
+	token, ok := user.(*jwt.Token)
+	if !ok || token == nil {
+		return ""
 	}
-
-	if err := s.workspace.Ensure(); err != nil {
-		s.logger.Error("Failed to repair workspace", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to repair workspace"})
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
 	}
+	uid, _ := claims["uid"].(string)
+	return strings.TrimSpace(uid)
+}
 
-	status, err := s.workspace.Inspect()
-	if err != nil {
-		s.logger.Error("Failed to inspect workspace after repair", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to inspect workspace"})
+func (s *Server) currentUsername(c *echo.Context) string {
+	user := c.Get("user")
+	token, ok := user.(*jwt.Token)
+	if !ok || token == nil {
+		return ""
 	}
-
-	return c.JSON(http.StatusOK, status)
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+	sub, _ := claims["sub"].(string)
+	return strings.TrimSpace(sub)
 }
 
-func (s *Server) handleGetQMDStatus(c *echo.Context) error {
-	qmdMgr, resolvedCommand, commandSource := s.newQMDManager()
-	status := qmdMgr.GetStatus()
-	exportDir := s.qmdResolvedExportDir()
-	exportCount, _ := s.countQMDExportFiles(exportDir)
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"enabled":                   s.config.Memory.QMD.Enabled,
-		"command":                   strings.TrimSpace(s.config.Memory.QMD.Command),
-		"resolved_command":          resolvedCommand,
-		"command_source":            commandSource,
-		"persistent_command":        s.persistentQMDBinary(),
-		"include_default":           s.config.Memory.QMD.IncludeDefault,
-		"available":                 status.Available,
-		"version":                   status.Version,
-		"error":                     status.Error,
-		"last_update":               status.LastUpdate.Format(time.RFC3339),
-		"collections":               status.Collections,
-		"sessions_enabled":          s.config.Memory.QMD.Sessions.Enabled,
-		"session_export_dir":        exportDir,
-		"session_retention_days":    s.config.Memory.QMD.Sessions.RetentionDays,
-		"session_export_file_count": exportCount,
-	})
+func (s *Server) currentUserRole(c *echo.Context) string {
+	user := c.Get("user")
+	token, ok := user.(*jwt.Token)
+	if !ok || token == nil {
+		return ""
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+	role, _ := claims["role"].(string)
+	return strings.TrimSpace(role)
 }
 
-func (s *Server) handleInstallQMD(c *echo.Context) error {
-	prefix := s.persistentQMDPrefix()
-	if err := os.MkdirAll(prefix, 0o755); err != nil {
-		s.logger.Error("Failed to create persistent qmd directory", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to prepare qmd install directory"})
+func (s *Server) currentTenantID(c *echo.Context) string {
+	user := c.Get("user")
+	token, ok := user.(*jwt.Token)
+	if !ok || token == nil {
+		return ""
 	}
-
-	cmd := exec.CommandContext(
-		c.Request().Context(),
-		defaultNPMCommand,
-		"install",
-		"-g",
-		"--prefix",
-		prefix,
-		defaultQMDNPMPackage,
-	)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		s.logger.Error("Failed to install qmd",
-			zap.String("prefix", prefix),
-			zap.ByteString("output", output),
-			zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
-			"error":  "failed to install qmd",
-			"output": strings.TrimSpace(string(output)),
-		})
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
 	}
+	tid, _ := claims["tid"].(string)
+	return strings.TrimSpace(tid)
+}
 
-	qmdMgr, resolvedCommand, commandSource := s.newQMDManager()
-	status := qmdMgr.GetStatus()
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"installed":          true,
-		"package":            defaultQMDNPMPackage,
-		"prefix":             prefix,
-		"binary":             s.persistentQMDBinary(),
-		"command":            strings.TrimSpace(s.config.Memory.QMD.Command),
-		"resolved_command":   resolvedCommand,
-		"command_source":     commandSource,
-		"persistent_command": s.persistentQMDBinary(),
-		"available":          status.Available,
-		"version":            status.Version,
-		"error":              status.Error,
-		"output":             strings.TrimSpace(string(output)),
-	})
+func (s *Server) currentTenantSlug(c *echo.Context) string {
+	user := c.Get("user")
+	token, ok := user.(*jwt.Token)
+	if !ok || token == nil {
+		return ""
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+	slug, _ := claims["ts"].(string)
+	return strings.TrimSpace(slug)
 }
 
-func (s *Server) handleUpdateQMD(c *echo.Context) error {
-	qmdMgr, resolvedCommand, commandSource := s.newQMDManager()
-	if !qmdMgr.IsAvailable() {
-		status := qmdMgr.GetStatus()
-		return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
-			"error":            "qmd not available",
-			"available":        false,
-			"detail":           status.Error,
-			"resolved_command": resolvedCommand,
-			"command_source":   commandSource,
-		})
+func (s *Server) authContextFromEcho(c *echo.Context) ownership.AuthContext {
+	return ownership.AuthContext{
+		UserID:     s.currentUserID(c),
+		TenantID:   s.currentTenantID(c),
+		TenantSlug: s.currentTenantSlug(c),
+		Role:       s.currentUserRole(c),
 	}
+}
 
-	ctx := c.Request().Context()
-	if err := qmdMgr.Initialize(ctx, s.config.WorkspacePath()); err != nil {
-		s.logger.Error("Failed to initialize qmd", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to initialize qmd"})
+func (s *Server) currentToolSessionID(c *echo.Context) string {
+	user := c.Get("user")
+	token, ok := user.(*jwt.Token)
+	if !ok || token == nil {
+		return ""
 	}
-	if err := qmdMgr.UpdateAll(ctx); err != nil {
-		s.logger.Error("Failed to update qmd", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update qmd"})
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
 	}
-
-	status := qmdMgr.GetStatus()
-	exportDir := s.qmdResolvedExportDir()
-	exportCount, _ := s.countQMDExportFiles(exportDir)
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"enabled":                   s.config.Memory.QMD.Enabled,
-		"command":                   strings.TrimSpace(s.config.Memory.QMD.Command),
-		"resolved_command":          resolvedCommand,
-		"command_source":            commandSource,
-		"persistent_command":        s.persistentQMDBinary(),
-		"include_default":           s.config.Memory.QMD.IncludeDefault,
-		"available":                 status.Available,
-		"version":                   status.Version,
-		"error":                     status.Error,
-		"last_update":               status.LastUpdate.Format(time.RFC3339),
-		"collections":               status.Collections,
-		"sessions_enabled":          s.config.Memory.QMD.Sessions.Enabled,
-		"session_export_dir":        exportDir,
-		"session_retention_days":    s.config.Memory.QMD.Sessions.RetentionDays,
-		"session_export_file_count": exportCount,
-	})
+	sid, _ := claims["sid"].(string)
+	return strings.TrimSpace(sid)
 }
 
-func (s *Server) handleCleanupQMDSessionExports(c *echo.Context) error {
-	if !s.config.Memory.QMD.Sessions.Enabled {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "qmd session export is disabled"})
+func (s *Server) allowMemberAPI(c *echo.Context) bool {
+	if strings.TrimSpace(s.currentToolSessionID(c)) == "" {
+		return false
 	}
-	if s.config.Memory.QMD.Sessions.RetentionDays < 1 {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "qmd session export retention_days must be at least 1"})
+	path := strings.TrimSpace(c.Request().URL.Path)
+	method := c.Request().Method
+	if method == http.MethodGet && path == "/api/tool-sessions" {
+		return true
 	}
-
-	exportDir := s.qmdResolvedExportDir()
-	exporter := memoryqmd.NewSessionExporter(s.logger, exportDir, s.config.Memory.QMD.Sessions.RetentionDays)
-	deleted, err := exporter.CleanupOldExportsCount(c.Request().Context())
-	if err != nil {
-		s.logger.Error("Failed to cleanup qmd session exports", zap.String("export_dir", exportDir), zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to cleanup qmd session exports"})
+	if method == http.MethodGet && path == "/api/tool-sessions/runtime-transports" {
+		return true
 	}
-
-	remaining, countErr := s.countQMDExportFiles(exportDir)
-	if countErr != nil {
-		s.logger.Warn("Failed to count qmd session exports after cleanup", zap.String("export_dir", exportDir), zap.Error(countErr))
+	if method == http.MethodPost && path == "/api/auth/stream-token" {
+		return true
 	}
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"deleted":                deleted,
-		"remaining":              remaining,
-		"session_export_dir":     exportDir,
-		"session_retention_days": s.config.Memory.QMD.Sessions.RetentionDays,
-	})
+	if strings.HasPrefix(path, "/api/tool-sessions/") {
+		switch {
+		case strings.HasSuffix(path, "/process/status"):
+			return method == http.MethodGet
+		case strings.HasSuffix(path, "/process/output"):
+			return method == http.MethodGet
+		case strings.HasSuffix(path, "/stream"):
+			return method == http.MethodGet
+		case strings.HasSuffix(path, "/process/input"):
+			return method == http.MethodPost
+		case strings.HasSuffix(path, "/process/kill"):
+			return method == http.MethodPost
+		}
+	}
+	return false
 }
 
-func (s *Server) newQMDManager() (*memoryqmd.Manager, string, string) {
-	qmdCfg := memoryqmd.ConfigFromConfigWithWorkspace(s.config.Memory.QMD, s.config.WorkspacePath())
-	resolvedCommand, commandSource := s.resolveQMDCommand()
-	qmdCfg.Command = resolvedCommand
-	return memoryqmd.NewManager(s.logger, qmdCfg), resolvedCommand, commandSource
+// isAdminOnlyAPI reports routes only admins may call: user management,
+// license import and reading or replacing the whole config.
+func isAdminOnlyAPI(c *echo.Context) bool {
+	path := strings.TrimSpace(c.Request().URL.Path)
+	method := c.Request().Method
+	switch {
+	case path == "/api/users" || strings.HasPrefix(path, "/api/users/"):
+		return true
+	case path == "/api/license/import":
+		return true
+	case path == "/api/config/export" || path == "/api/config/import",
+		path == "/api/config/document" || path == "/api/config/apply":
+		return true
+	case path == "/api/config":
+		return method != http.MethodGet
+	case path == "/api/audit" || strings.HasPrefix(path, "/api/audit/"):
+		return true
+	case path == "/api/plugins" || strings.HasPrefix(path, "/api/plugins/"):
+		return true
+	case path == "/api/skills-index/install":
+		return true
+	}
+	return false
 }
 
-func (s *Server) persistentQMDPrefix() string {
-	return filepath.Join(s.config.WorkspacePath(), ".nekobot", "runtime", "qmd")
+// instanceAPIPrefixes are routes that configure or expose the whole
+// instance rather than one tenant. Only users of the default tenant reach
+// them.
+var instanceAPIPrefixes = []string{
+	"/api/users",
+	"/api/license",
+	"/api/config",
+	"/api/channels",
+	"/api/approvals",
+	"/api/permission-rules",
+	"/api/mcp",
+	"/api/plugins",
+	"/api/skills-index",
+	"/api/service",
+	"/api/daemon",
+	"/api/harness",
+	"/api/workspace",
+	"/api/sessions",
+}
+
+func isInstanceAPI(c *echo.Context) bool {
+	path := strings.TrimSpace(c.Request().URL.Path)
+	for _, prefix := range instanceAPIPrefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
 }
 
-func (s *Server) qmdResolvedExportDir() string {
-	return memoryqmd.ConfigFromConfigWithWorkspace(s.config.Memory.QMD, s.config.WorkspacePath()).Sessions.ExportDir
-}
+// viewerHiddenReads are reads that return channel credentials. The config
+// is readable; handleGetConfig redacts its secrets for non-admins.
+var viewerHiddenReads = []string{"/api/channels", "/api/channel-accounts"}
 
-func (s *Server) countQMDExportFiles(exportDir string) (int, error) {
-	if strings.TrimSpace(exportDir) == "" {
-		return 0, nil
+// allowViewerAPI reports whether a read-only viewer may call the route:
+// reads without credentials, plus managing their own account and dry-run
+// evaluations.
+func allowViewerAPI(c *echo.Context) bool {
+	if isAdminOnlyAPI(c) {
+		return false
 	}
-
-	entries, err := os.ReadDir(exportDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return 0, nil
+	method := c.Request().Method
+	path := strings.TrimSpace(c.Request().URL.Path)
+	if method == http.MethodGet || method == http.MethodHead {
+		for _, hidden := range viewerHiddenReads {
+			if path == hidden || strings.HasPrefix(path, hidden+"/") {
+				return false
+			}
 		}
-		return 0, err
+		return true
 	}
-
-	count := 0
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(strings.ToLower(entry.Name()), ".md") {
-			continue
-		}
-		count++
+	switch {
+	case method == http.MethodPost && path == "/api/auth/change-password":
+		return true
+	case method == http.MethodPut && path == "/api/auth/profile":
+		return true
+	case method == http.MethodPost && (path == "/api/approvals/dry-run" || path == "/api/policy/evaluate"):
+		return true
+	case method == http.MethodPost && path == "/api/api-keys":
+		return true
+	case method == http.MethodDelete && strings.HasPrefix(path, "/api/api-keys/"):
+		return true
 	}
-	return count, nil
+	return false
 }
 
-func (s *Server) persistentQMDBinary() string {
-	return filepath.Join(s.persistentQMDPrefix(), "bin", "qmd")
-}
+// apiKeyContextKey holds the API key that authenticated a request.
+const apiKeyContextKey = "api_key"
 
-func (s *Server) resolveQMDCommand() (string, string) {
-	configured := strings.TrimSpace(s.config.Memory.QMD.Command)
-	if configured != "" && configured != "qmd" {
-		return configured, "config"
+// authenticateAPIKey accepts API keys as bearer tokens. A valid key stands
+// in for its user's login token, limited by the key's scopes.
+func (s *Server) authenticateAPIKey() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			secret, ok := strings.CutPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+			if !ok || !apikeys.IsKey(secret) {
+				return next(c)
+			}
+			manager, err := apikeys.NewManager(s.entClient)
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			}
+			ctx := c.Request().Context()
+			key, err := manager.Authenticate(ctx, secret)
+			if err != nil {
+				if errors.Is(err, apikeys.ErrInvalidKey) {
+					return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or expired api key"})
+				}
+				s.logger.Error("Failed to authenticate api key", zap.Error(err))
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to authenticate api key"})
+			}
+			scope := apikeys.ScopeWrite
+			if method := c.Request().Method; method == http.MethodGet || method == http.MethodHead {
+				scope = apikeys.ScopeRead
+			}
+			if !key.Allows(scope) {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "api key lacks the " + scope + " scope"})
+			}
+			profile, err := config.BuildAuthProfileByUserID(ctx, s.entClient, key.UserID)
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "api key user is unavailable"})
+			}
+			c.Set(apiKeyContextKey, key)
+			c.Set("user", &jwt.Token{
+				Method: jwt.SigningMethodHS256,
+				Valid:  true,
+				Claims: jwt.MapClaims{
+					"sub":  profile.Username,
+					"uid":  profile.UserID,
+					"role": profile.Role,
+					"tid":  profile.TenantID,
+					"ts":   profile.TenantSlug,
+					"kid":  key.ID,
+				},
+			})
+			return next(c)
+		}
 	}
+}
 
-	persistentBinary := s.persistentQMDBinary()
-	if info, err := os.Stat(persistentBinary); err == nil && !info.IsDir() {
-		return persistentBinary, "workspace"
+func (s *Server) requirePrivilegedAPIUser() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			if isInstanceAPI(c) && !s.authContextFromEcho(c).InTenant("") {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "not available to tenant users"})
+			}
+			role := s.currentUserRole(c)
+			switch role {
+			case config.RoleAdmin, config.RoleOwner:
+				return next(c)
+			case config.RoleOperator:
+				if isAdminOnlyAPI(c) {
+					return c.JSON(http.StatusForbidden, map[string]string{"error": "admin role required"})
+				}
+				return next(c)
+			case config.RoleViewer:
+				if allowViewerAPI(c) {
+					return next(c)
+				}
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "viewer role is read-only"})
+			case config.RoleMember:
+				if s.allowMemberAPI(c) {
+					return next(c)
+				}
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "insufficient role for privileged api"})
+			default:
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "insufficient role for privileged api"})
+			}
+		}
 	}
+}
 
-	if configured != "" {
-		return configured, "config"
+func resolveToolCommand(toolName, command string) string {
+	cmd := strings.TrimSpace(command)
+	if cmd != "" {
+		return cmd
+	}
+	tool := strings.TrimSpace(strings.ToLower(toolName))
+	switch tool {
+	case "codex":
+		return "codex"
+	case "claude":
+		return "claude"
+	case "opencode":
+		return "opencode"
+	case "aider":
+		return "aider"
+	default:
+		return strings.TrimSpace(toolName)
 	}
-
-	return "qmd", "path"
 }
 
-func installSpecs(skill *skills.Skill) []map[string]interface{} {
-	if skill == nil || skill.Requirements == nil {
-		return nil
+func resolveToolCommandWithArgs(toolName, command, commandArgs string) string {
+	args := strings.TrimSpace(commandArgs)
+	if args == "" {
+		return resolveToolCommand(toolName, command)
 	}
-
-	specs := skills.ParseRequirementsToSpecs(skill.Requirements)
-	if len(specs) == 0 {
-		return nil
+	base := resolveToolCommand(toolName, "")
+	if base == "" {
+		base = strings.TrimSpace(toolName)
 	}
-
-	out := make([]map[string]interface{}, 0, len(specs))
-	for _, spec := range specs {
-		out = append(out, map[string]interface{}{
-			"method":    strings.TrimSpace(spec.Method),
-			"package":   strings.TrimSpace(spec.Package),
-			"version":   strings.TrimSpace(spec.Version),
-			"post_hook": strings.TrimSpace(spec.PostHook),
-			"options":   spec.Options,
-		})
+	if base == "" {
+		return ""
 	}
-	return out
+	return strings.TrimSpace(base + " " + args)
 }
 
-func readSkillContent(skill *skills.Skill) (string, string, error) {
-	filePath := strings.TrimSpace(skill.FilePath)
-	var raw string
-	if strings.HasPrefix(filePath, "builtin://") {
-		builtinRaw, err := skills.ReadBuiltinSkillContent(filePath)
-		if err != nil {
-			return "", "", err
-		}
-		raw = builtinRaw
-	} else {
-		rawBytes, err := os.ReadFile(filePath)
-		if err != nil {
-			return "", "", fmt.Errorf("read skill file %s: %w", filePath, err)
-		}
-		raw = string(rawBytes)
-	}
+const (
+	toolProxyModeInherit = "inherit"
+	toolProxyModeClear   = "clear"
+	toolProxyModeCustom  = "custom"
+)
 
-	bodyRaw := strings.TrimSpace(raw)
-	if strings.HasPrefix(raw, "---\n") {
-		parts := strings.SplitN(raw[4:], "\n---\n", 2)
-		if len(parts) == 2 {
-			bodyRaw = strings.TrimSpace(parts[1])
-		}
+func normalizeToolProxyMode(mode string) string {
+	switch strings.TrimSpace(strings.ToLower(mode)) {
+	case toolProxyModeClear:
+		return toolProxyModeClear
+	case toolProxyModeCustom:
+		return toolProxyModeCustom
+	default:
+		return toolProxyModeInherit
 	}
-
-	return raw, bodyRaw, nil
 }
 
-func (s *Server) handleEnableSkill(c *echo.Context) error {
-	if s.skillsMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "skills manager not available"})
-	}
-
-	skillID := strings.TrimSpace(c.Param("id"))
-	if _, ok := s.resolveSkill(skillID); !ok {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": "skill not found"})
+func cloneMap(src map[string]interface{}) map[string]interface{} {
+	if len(src) == 0 {
+		return map[string]interface{}{}
 	}
-	if err := s.skillsMgr.Enable(skillID); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to enable skill"})
+	dst := make(map[string]interface{}, len(src))
+	for k, v := range src {
+		dst[k] = v
 	}
-
-	return c.JSON(http.StatusOK, map[string]string{"status": "enabled"})
+	return dst
 }
 
-func (s *Server) handleDisableSkill(c *echo.Context) error {
-	if s.skillsMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "skills manager not available"})
-	}
-
-	skillID := strings.TrimSpace(c.Param("id"))
-	if _, ok := s.resolveSkill(skillID); !ok {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": "skill not found"})
+func toolProxyFromMetadata(metadata map[string]interface{}) (string, string) {
+	if len(metadata) == 0 {
+		return toolProxyModeInherit, ""
 	}
-	if err := s.skillsMgr.Disable(skillID); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to disable skill"})
+	rawMode, _ := metadata["proxy_mode"].(string)
+	mode := normalizeToolProxyMode(rawMode)
+	rawURL, _ := metadata["proxy_url"].(string)
+	if mode != toolProxyModeCustom {
+		return mode, ""
 	}
-
-	return c.JSON(http.StatusOK, map[string]string{"status": "disabled"})
+	return mode, strings.TrimSpace(rawURL)
 }
 
-func errorString(err error) string {
-	if err == nil {
-		return ""
+func withToolProxyMetadata(metadata map[string]interface{}, mode, proxyURL string) map[string]interface{} {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
 	}
-	return err.Error()
-}
-
-func nonNilStrings(values []string) []string {
-	if values == nil {
-		return []string{}
+	mode = normalizeToolProxyMode(mode)
+	metadata["proxy_mode"] = mode
+	if mode == toolProxyModeCustom && strings.TrimSpace(proxyURL) != "" {
+		metadata["proxy_url"] = strings.TrimSpace(proxyURL)
+	} else {
+		delete(metadata, "proxy_url")
 	}
-	return values
+	return metadata
 }
 
-// --- Channel Handlers ---
+func resolveToolProxyConfig(existingMode, existingURL, inputMode, inputURL string) (string, string, error) {
+	mode := normalizeToolProxyMode(existingMode)
+	urlValue := strings.TrimSpace(existingURL)
 
-func (s *Server) handleGetChannels(c *echo.Context) error {
-	payload := map[string]interface{}{}
-	for name, cfg := range channels.ListChannelConfigs(s.config) {
-		payload[name] = cfg
+	if strings.TrimSpace(inputMode) != "" {
+		mode = normalizeToolProxyMode(inputMode)
 	}
-	payload["_instances"] = s.listChannelInstances()
-	return c.JSON(http.StatusOK, payload)
-}
-
-func (s *Server) handleUpdateChannel(c *echo.Context) error {
-	name := c.Param("name")
-
-	var body map[string]interface{}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	if strings.TrimSpace(inputURL) != "" {
+		urlValue = strings.TrimSpace(inputURL)
 	}
 
-	// Marshal the body to JSON, then unmarshal into the appropriate channel config.
-	data, err := json.Marshal(body)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	if mode != toolProxyModeCustom {
+		return mode, "", nil
 	}
+	if urlValue == "" {
+		return "", "", fmt.Errorf("proxy url is required when proxy mode is custom")
+	}
+	parsed, err := url.Parse(urlValue)
+	if err != nil || strings.TrimSpace(parsed.Scheme) == "" {
+		return "", "", fmt.Errorf("invalid proxy url")
+	}
+	return mode, urlValue, nil
+}
 
-	nextConfig, err := cloneConfigSnapshot(s.config)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+func applyToolProxyToCommand(command, proxyMode, proxyURL string) string {
+	cmd := strings.TrimSpace(command)
+	mode := normalizeToolProxyMode(proxyMode)
+	switch mode {
+	case toolProxyModeClear:
+		return "env -u HTTP_PROXY -u HTTPS_PROXY -u ALL_PROXY -u NO_PROXY -u http_proxy -u https_proxy -u all_proxy -u no_proxy " + cmd
+	case toolProxyModeCustom:
+		value := strconv.Quote(strings.TrimSpace(proxyURL))
+		return fmt.Sprintf("env HTTP_PROXY=%s HTTPS_PROXY=%s ALL_PROXY=%s http_proxy=%s https_proxy=%s all_proxy=%s %s",
+			value, value, value, value, value, value, cmd)
+	default:
+		return cmd
 	}
+}
 
-	if err := channels.ApplyChannelConfig(nextConfig, name, data); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+func (s *Server) tryRestoreToolSessionRuntime(ctx context.Context, sessionID string) bool {
+	if s.processMgr == nil || s.toolSess == nil {
+		return false
 	}
-	if err := config.ValidateConfig(nextConfig); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	id := strings.TrimSpace(sessionID)
+	if id == "" {
+		return false
+	}
+	if _, err := s.processMgr.GetStatus(id); err == nil {
+		return true
+	} else if !isProcessSessionNotFound(err) {
+		return false
 	}
 
-	enabled, err := channels.IsChannelEnabled(name, nextConfig)
+	sess, err := s.toolSess.GetSession(ctx, id)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return false
 	}
-
-	var rebuilt channels.Channel
-	if enabled {
-		rebuilt, err = channels.BuildChannel(
-			name,
-			s.logger,
-			s.bus,
-			s.agent,
-			s.commands,
-			s.prefs,
-			s.toolSess,
-			s.processMgr,
-			nextConfig,
-		)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
-		}
+	if sess.State == toolsessions.StateTerminated || sess.State == toolsessions.StateArchived {
+		return false
 	}
 
-	// Persist runtime channel config to database.
-	if err := config.SaveDatabaseSections(nextConfig, "channels"); err != nil {
-		s.logger.Error("Failed to persist channel config", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save config"})
+	transport := s.resolveSessionRuntimeTransport(sess.Metadata, "")
+	attachCmd, runtimeSession, ok := buildToolReattachLaunchWithTransport(transport, id)
+	if !ok {
+		return false
 	}
-
-	if enabled {
-		if err := s.channels.ReloadChannel(rebuilt); err != nil {
-			s.logger.Error("Failed to reload channel", zap.String("channel", name), zap.Error(err))
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "channel config saved but reload failed: " + err.Error(),
-			})
-		}
-	} else {
-		if err := s.channels.StopChannel(name); err != nil {
-			s.logger.Error("Failed to stop channel", zap.String("channel", name), zap.Error(err))
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "channel config saved but reload failed: " + err.Error(),
-			})
+	workdir := strings.TrimSpace(sess.Workdir)
+	if workdir == "" {
+		workdir = s.config.WorkspacePath()
+	}
+	spec := execenv.StartSpecFromContext(ctx, id, attachCmd, workdir, sess.Metadata)
+	if err := s.processMgr.StartWithSpec(context.Background(), spec); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "session already exists") {
+			return true
 		}
+		s.logger.Warn("Failed to restore tool session runtime",
+			zap.String("session_id", id),
+			zap.String("runtime_session", runtimeSession),
+			zap.Error(err),
+		)
+		return false
 	}
-	s.config.Channels = nextConfig.Channels
 
-	return c.JSON(http.StatusOK, map[string]string{"status": "updated", "channel": name, "reload": "ok"})
-}
-
-func (s *Server) listChannelInstances() []map[string]interface{} {
-	if s.channels == nil {
-		return []map[string]interface{}{}
+	metadata := cloneMap(sess.Metadata)
+	metadata = runtimeagents.ApplyLaunchMetadata(metadata, runtimeagents.LaunchInfo{
+		TransportName: transport.Name(),
+		SessionName:   runtimeSession,
+		LaunchCommand: attachCmd,
+	})
+	if err := s.toolSess.UpdateSessionMetadata(context.Background(), id, metadata); err != nil {
+		s.logger.Warn("Failed to persist restored tool session metadata",
+			zap.String("session_id", id),
+			zap.String("runtime_session", runtimeSession),
+			zap.Error(err),
+		)
 	}
 
-	items := s.channels.ListChannels()
-	result := make([]map[string]interface{}, 0, len(items))
-	for _, item := range items {
-		entry := map[string]interface{}{
-			"id":      item.ID(),
-			"name":    item.Name(),
-			"enabled": item.IsEnabled(),
-			"type":    item.ID(),
-		}
-		if typed, ok := item.(channels.TypedChannel); ok {
-			entry["type"] = typed.ChannelType()
-		}
-		result = append(result, entry)
+	eventPayload := runtimeagents.ApplyRuntimeSessionPayload(map[string]interface{}{
+		"launch_cmd": attachCmd,
+		"workdir":    workdir,
+	}, transport.Name(), runtimeSession)
+	eventPayload[runtimeagents.MetadataRuntimeTransport] = strings.TrimSpace(transport.Name())
+	if err := s.toolSess.AppendEvent(context.Background(), id, "process_restored", eventPayload); err != nil {
+		s.logger.Warn("Failed to append tool session restore event",
+			zap.String("session_id", id),
+			zap.Error(err),
+		)
 	}
-	return result
+	if err := s.toolSess.TouchSession(context.Background(), id, toolsessions.StateRunning); err != nil {
+		s.logger.Warn("Failed to touch restored tool session",
+			zap.String("session_id", id),
+			zap.Error(err),
+		)
+	}
+	s.logger.Info("Restored tool session runtime",
+		zap.String("session_id", id),
+		zap.String("runtime_session", runtimeSession),
+	)
+	return true
 }
 
-func (s *Server) reloadChannel(name string) error {
-	enabled, err := channels.IsChannelEnabled(name, s.config)
-	if err != nil {
-		return err
-	}
+func runtimeTransportAvailable() bool {
+	return runtimeagents.DefaultTransport().Available()
+}
 
-	if !enabled {
-		return s.channels.StopChannel(name)
-	}
+func buildToolRuntimeLaunch(command, sessionID string) (string, string) {
+	launchInfo := runtimeagents.DefaultTransport().WrapStart(command, sessionID)
+	return launchInfo.LaunchCommand, launchInfo.SessionName
+}
 
-	ch, err := channels.BuildChannel(name, s.logger, s.bus, s.agent, s.commands, s.prefs, s.toolSess, s.processMgr, s.config)
-	if err != nil {
-		return err
-	}
+func buildToolReattachLaunch(sessionID string) (string, string, bool) {
+	reattachInfo, ok := runtimeagents.DefaultTransport().BuildReattach(sessionID)
+	return reattachInfo.LaunchCommand, reattachInfo.SessionName, ok
+}
 
-	return s.channels.ReloadChannel(ch)
+func buildToolRuntimeLaunchWithTransport(transport runtimeagents.RuntimeTransport, command, sessionID string) (string, string) {
+	if transport == nil {
+		transport = runtimeagents.DefaultTransport()
+	}
+	launchInfo := transport.WrapStart(command, sessionID)
+	return launchInfo.LaunchCommand, launchInfo.SessionName
 }
 
-func (s *Server) reloadChannelsByType(channelType string) error {
-	if s == nil || s.channels == nil {
-		return fmt.Errorf("channel manager is unavailable")
+func buildToolReattachLaunchWithTransport(transport runtimeagents.RuntimeTransport, sessionID string) (string, string, bool) {
+	if transport == nil {
+		transport = runtimeagents.DefaultTransport()
 	}
+	reattachInfo, ok := transport.BuildReattach(sessionID)
+	return reattachInfo.LaunchCommand, reattachInfo.SessionName, ok
+}
 
-	channelType = strings.TrimSpace(channelType)
-	if channelType == "" {
-		return fmt.Errorf("channel type is required")
+func metadataString(values map[string]interface{}, key string) string {
+	if len(values) == 0 {
+		return ""
 	}
+	value, _ := values[key].(string)
+	return strings.TrimSpace(value)
+}
 
-	if s.accountMgr != nil {
-		accounts, err := s.accountMgr.List(context.Background())
-		if err != nil {
-			return fmt.Errorf("list channel accounts for %s: %w", channelType, err)
-		}
+// runtimeTransportFromName falls back to the native PTY transport when the
+// named multiplexer is not installed.
+func runtimeTransportFromName(name string) runtimeagents.RuntimeTransport {
+	return runtimeagents.ResolveTransport(name)
+}
 
-		desiredIDs := make(map[string]struct{})
-		desiredAccounts := make([]channelaccounts.ChannelAccount, 0)
-		for _, account := range accounts {
-			if account.ChannelType != channelType || !account.Enabled {
-				continue
-			}
-			runtimeID := strings.TrimSpace(account.ChannelType) + ":" + strings.TrimSpace(account.AccountKey)
-			desiredIDs[runtimeID] = struct{}{}
-			desiredAccounts = append(desiredAccounts, account)
-		}
-		if channelType == "wechat" {
-			desiredAccounts = s.prioritizeActiveWechatAccount(desiredAccounts)
-		}
-
-		existingChannels := s.channels.ListChannelsByType(channelType)
-		if len(desiredAccounts) > 0 {
-			for _, existing := range existingChannels {
-				if _, ok := desiredIDs[existing.ID()]; ok {
-					continue
-				}
-				if err := s.channels.StopChannel(existing.ID()); err != nil {
-					return fmt.Errorf("stop stale %s runtime %s: %w", channelType, existing.ID(), err)
-				}
-				s.unregisterInboundRouteForChannelID(existing.ID())
-			}
-
-			for _, account := range desiredAccounts {
-				ch, err := channels.BuildChannelFromAccount(
-					account,
-					s.logger,
-					s.bus,
-					s.agent,
-					s.commands,
-					s.prefs,
-					s.toolSess,
-					s.processMgr,
-					s.config,
-				)
-				if err != nil {
-					return fmt.Errorf("build %s account runtime %s: %w", channelType, account.AccountKey, err)
-				}
-				if err := s.channels.ReloadChannel(ch); err != nil {
-					return fmt.Errorf("reload %s account runtime %s: %w", channelType, ch.ID(), err)
-				}
-				s.registerInboundRouteForChannelID(ch.ID())
-			}
-			return nil
-		}
-
-		for _, existing := range existingChannels {
-			if existing.ID() == channelType {
-				continue
-			}
-			if err := s.channels.StopChannel(existing.ID()); err != nil {
-				return fmt.Errorf("stop stale %s runtime %s: %w", channelType, existing.ID(), err)
-			}
-			s.unregisterInboundRouteForChannelID(existing.ID())
-		}
+func (s *Server) handleListToolSessionRuntimeTransports(c *echo.Context) error {
+	defaultName := s.defaultRuntimeTransport().Name()
+	items := []map[string]interface{}{
+		{
+			"name":       runtimeagents.TransportTmux,
+			"available":  runtimeagents.TransportByName(runtimeagents.TransportTmux).Available(),
+			"is_default": defaultName == runtimeagents.TransportTmux,
+		},
+		{
+			"name":       runtimeagents.TransportZellij,
+			"available":  runtimeagents.TransportByName(runtimeagents.TransportZellij).Available(),
+			"is_default": defaultName == runtimeagents.TransportZellij,
+		},
+		{
+			"name":       runtimeagents.TransportNative,
+			"available":  true,
+			"is_default": defaultName == runtimeagents.TransportNative,
+		},
 	}
-
-	return s.reloadChannel(channelType)
+	return c.JSON(http.StatusOK, map[string]interface{}{"items": items})
 }
 
-func (s *Server) registerInboundRouteForChannelID(channelID string) {
-	if s == nil || s.inboundRouter == nil {
-		return
+func (s *Server) defaultRuntimeTransport() runtimeagents.RuntimeTransport {
+	if s == nil || s.config == nil {
+		return runtimeagents.DefaultTransport()
 	}
-	s.inboundRouter.RegisterChannel(channelID)
+	name := strings.TrimSpace(s.config.WebUI.ToolSessionRuntimeTransport)
+	if name == "" {
+		return runtimeagents.DefaultTransport()
+	}
+	return runtimeTransportFromName(name)
 }
 
-func (s *Server) unregisterInboundRouteForChannelID(channelID string) {
-	if s == nil || s.inboundRouter == nil {
-		return
+func (s *Server) resolveSessionRuntimeTransport(metadata map[string]interface{}, requested string) runtimeagents.RuntimeTransport {
+	requested = strings.TrimSpace(requested)
+	if requested != "" {
+		return runtimeTransportFromName(requested)
 	}
-	s.inboundRouter.UnregisterChannel(channelID)
+	stored := runtimeagents.MetadataString(metadata, runtimeagents.MetadataRuntimeTransport)
+	if stored != "" {
+		return runtimeTransportFromName(stored)
+	}
+	return s.defaultRuntimeTransport()
 }
 
-func (s *Server) prioritizeActiveWechatAccount(
-	accounts []channelaccounts.ChannelAccount,
-) []channelaccounts.ChannelAccount {
-	if len(accounts) < 2 || s == nil || s.config == nil {
-		return accounts
+func (s *Server) tryKillRuntimeSession(ctx context.Context, sessionID string) {
+	transport := s.defaultRuntimeTransport()
+	if s != nil && s.toolSess != nil {
+		if sess, err := s.toolSess.GetSession(ctx, sessionID); err == nil && sess != nil {
+			transport = s.resolveSessionRuntimeTransport(sess.Metadata, "")
+		}
 	}
+	transport.KillSession(sessionID)
+}
 
-	store, err := channelwechat.NewCredentialStore(s.config)
-	if err != nil {
-		return accounts
-	}
-	creds, err := store.LoadCredentials()
-	if err != nil || creds == nil {
-		return accounts
+func isProcessSessionNotFound(err error) bool {
+	if err == nil {
+		return false
 	}
+	return strings.Contains(strings.ToLower(err.Error()), "session not found")
+}
 
-	activeBotID := strings.TrimSpace(creds.ILinkBotID)
-	if activeBotID == "" {
-		return accounts
-	}
+func (s *Server) buildToolSessionAccessURL(c *echo.Context, sessionID, overrideBase string) string {
+	return toolSessionAccessURL(s.toolSessionAccessBase(c, overrideBase), sessionID)
+}
 
-	activeIndex := -1
-	for i, account := range accounts {
-		if strings.TrimSpace(account.AccountKey) == activeBotID {
-			activeIndex = i
-			break
-		}
+// toolSessionAccessBase resolves the base URL of access links: the override,
+// the configured public URL, or the host the request came in on.
+func (s *Server) toolSessionAccessBase(c *echo.Context, overrideBase string) string {
+	base := strings.TrimSpace(overrideBase)
+	if base == "" {
+		base = strings.TrimSpace(s.config.WebUI.PublicBaseURL)
 	}
-	if activeIndex <= 0 {
-		return accounts
+	if base == "" {
+		base = requestScheme(c) + "://" + c.Request().Host
+	}
+	if !strings.Contains(base, "://") {
+		base = requestScheme(c) + "://" + strings.TrimPrefix(base, "/")
 	}
+	return strings.TrimRight(base, "/")
+}
 
-	prioritized := make([]channelaccounts.ChannelAccount, 0, len(accounts))
-	prioritized = append(prioritized, accounts[activeIndex])
-	prioritized = append(prioritized, accounts[:activeIndex]...)
-	prioritized = append(prioritized, accounts[activeIndex+1:]...)
-	return prioritized
+func toolSessionAccessURL(base, sessionID string) string {
+	values := url.Values{}
+	values.Set("tab", "tools")
+	values.Set("tool_session", strings.TrimSpace(sessionID))
+	return base + "/?" + values.Encode()
 }
 
-func (s *Server) reloadChannelForAccount(channelType, accountID string) error {
-	channelType = strings.TrimSpace(channelType)
-	accountID = strings.TrimSpace(accountID)
-	if channelType == "" || accountID == "" || s.accountMgr == nil {
-		return s.reloadChannel(channelType)
+// buildToolSessionSpectatorURL links the read-only terminal view. The token
+// in the link is the only credential a spectator needs.
+func (s *Server) buildToolSessionSpectatorURL(c *echo.Context, sessionID, token, overrideBase string) string {
+	base := strings.TrimSpace(overrideBase)
+	if base == "" {
+		base = strings.TrimSpace(s.config.WebUI.PublicBaseURL)
+	}
+	if base == "" {
+		base = requestScheme(c) + "://" + c.Request().Host
+	}
+	if !strings.Contains(base, "://") {
+		base = requestScheme(c) + "://" + strings.TrimPrefix(base, "/")
 	}
+	base = strings.TrimRight(base, "/")
+	values := url.Values{}
+	values.Set("session", strings.TrimSpace(sessionID))
+	values.Set("token", token)
+	return base + "/spectate?" + values.Encode()
+}
 
-	account, err := s.accountMgr.Get(context.Background(), accountID)
-	if err != nil {
-		return s.reloadChannel(channelType)
+func requestScheme(c *echo.Context) string {
+	scheme := "http"
+	if c.Request().TLS != nil {
+		scheme = "https"
 	}
-	if account == nil || strings.TrimSpace(account.ChannelType) != channelType {
-		return s.reloadChannel(channelType)
+	if forwardedProto := strings.TrimSpace(c.Request().Header.Get("X-Forwarded-Proto")); forwardedProto != "" {
+		scheme = forwardedProto
 	}
+	return scheme
+}
 
-	runtimeID := strings.TrimSpace(account.ChannelType) + ":" + strings.TrimSpace(account.AccountKey)
-	if !account.Enabled {
-		return s.channels.StopChannel(runtimeID)
+func (s *Server) resolveSkill(id string) (*skills.Skill, bool) {
+	trimmedID := strings.TrimSpace(id)
+	if trimmedID == "" {
+		return nil, false
 	}
 
-	ch, err := channels.BuildChannelFromAccount(*account, s.logger, s.bus, s.agent, s.commands, s.prefs, s.toolSess, s.processMgr, s.config)
-	if err != nil {
-		return err
+	for _, skill := range s.skillsMgr.List() {
+		if strings.TrimSpace(skill.ID) == trimmedID {
+			return skill, true
+		}
 	}
 
-	return s.channels.ReloadChannel(ch)
+	return nil, false
 }
 
-func (s *Server) handleTestChannel(c *echo.Context) error {
-	name := c.Param("name")
+// --- Skills Handlers ---
 
-	ch, err := s.channels.GetChannel(name)
-	if err != nil {
-		return c.JSON(http.StatusNotFound, map[string]interface{}{
-			"channel": name,
-			"status":  "not_found",
-			"error":   err.Error(),
-		})
+func (s *Server) handleListSkills(c *echo.Context) error {
+	if s.skillsMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "skills manager not available"})
 	}
 
-	result := map[string]interface{}{
-		"channel":   ch.Name(),
-		"id":        ch.ID(),
-		"enabled":   ch.IsEnabled(),
-		"reachable": false,
+	skillsList := s.skillsMgr.List()
+	sort.Slice(skillsList, func(i, j int) bool {
+		left := strings.TrimSpace(skillsList[i].ID)
+		right := strings.TrimSpace(skillsList[j].ID)
+		if left == right {
+			return strings.TrimSpace(skillsList[i].Name) < strings.TrimSpace(skillsList[j].Name)
+		}
+		return left < right
+	})
+
+	items := make([]map[string]interface{}, 0, len(skillsList))
+	for _, skill := range skillsList {
+		items = append(items, s.skillItem(skill))
 	}
 
-	if !ch.IsEnabled() {
-		result["status"] = "disabled"
-		return c.JSON(http.StatusOK, result)
+	return c.JSON(http.StatusOK, items)
+}
+
+func (s *Server) handleGetSkill(c *echo.Context) error {
+	if s.skillsMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "skills manager not available"})
 	}
 
-	healthChecker, ok := ch.(channels.HealthChecker)
+	skillID := strings.TrimSpace(c.Param("id"))
+	skill, ok := s.resolveSkill(skillID)
 	if !ok {
-		result["status"] = "configured"
-		return c.JSON(http.StatusOK, result)
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "skill not found"})
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
-	defer cancel()
+	return c.JSON(http.StatusOK, s.skillItem(skill))
+}
 
-	if err := healthChecker.HealthCheck(ctx); err != nil {
-		result["status"] = "unreachable"
-		result["error"] = err.Error()
-		return c.JSON(http.StatusOK, result)
+func (s *Server) handleGetSkillContent(c *echo.Context) error {
+	if s.skillsMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "skills manager not available"})
 	}
 
-	result["reachable"] = true
-	result["status"] = "ok"
-	return c.JSON(http.StatusOK, result)
-}
-
-type webWechatLoginClient struct{}
-
-func (webWechatLoginClient) FetchQRCode(ctx context.Context) (*wxtypes.QRCodeResponse, error) {
-	return wxauth.FetchQRCode(ctx)
-}
-
-func (webWechatLoginClient) CheckQRStatus(ctx context.Context, qrcode string) (*wxtypes.QRStatusResponse, error) {
-	return wxauth.CheckQRStatus(ctx, qrcode)
-}
-
-func (s *Server) ilinkAuthService() (*ilinkauth.Service, error) {
-	if s.ilinkAuth != nil {
-		return s.ilinkAuth, nil
+	skillID := strings.TrimSpace(c.Param("id"))
+	skill, ok := s.resolveSkill(skillID)
+	if !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "skill not found"})
 	}
 
-	store, err := ilinkauth.NewStore(s.config)
+	raw, bodyRaw, err := readSkillContent(skill)
 	if err != nil {
-		return nil, fmt.Errorf("create ilink auth store: %w", err)
-	}
-	s.ilinkAuth = ilinkauth.NewService(store, webWechatLoginClient{})
-	return s.ilinkAuth, nil
-}
-
-func (s *Server) currentAuthUser(c *echo.Context) (*config.AuthProfile, error) {
-	profile, err := s.authProfileFromContext(c)
-	if err == nil {
-		return profile, nil
-	}
-
-	userID := strings.TrimSpace(s.currentUserID(c))
-	username := strings.TrimSpace(s.currentUsername(c))
-	if userID == "" && username == "" {
-		return nil, err
+		s.logger.Error("Failed to read skill content",
+			zap.String("skill_id", skillID),
+			zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to read skill content"})
 	}
 
-	return &config.AuthProfile{
-		UserID:   userID,
-		Username: username,
-		Role:     firstNonEmptyString(s.currentUserRole(c), "member"),
-		TenantID: s.currentTenantID(c),
-	}, nil
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"id":        strings.TrimSpace(skill.ID),
+		"name":      strings.TrimSpace(skill.Name),
+		"file_path": strings.TrimSpace(skill.FilePath),
+		"raw":       raw,
+		"body_raw":  bodyRaw,
+	})
 }
 
-func (s *Server) handleGetWechatBindingStatus(c *echo.Context) error {
-	authSvc, err := s.ilinkAuthService()
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+// handleListSkillsIndex lists the curated skills index with the installed
+// version and requested permissions of each skill.
+func (s *Server) handleListSkillsIndex(c *echo.Context) error {
+	if s.skillsMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "skills manager not available"})
 	}
-	profile, err := s.currentAuthUser(c)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	if !s.skillsMgr.HasIndex() {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"configured": false,
+			"skills":     []interface{}{},
+		})
 	}
 
-	payload, err := s.buildWechatBindingPayload(authSvc, profile.UserID)
+	index, err := s.skillsMgr.FetchIndex(c.Request().Context())
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, payload)
-}
-
-func (s *Server) handleStartWechatBinding(c *echo.Context) error {
-	authSvc, err := s.ilinkAuthService()
+	installed, err := s.skillsMgr.InstalledSkills()
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	profile, err := s.currentAuthUser(c)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	installedByID := make(map[string]skills.InstalledSkill, len(installed))
+	for _, skill := range installed {
+		installedByID[skill.ID] = skill
 	}
 
-	if _, err := authSvc.StartBinding(c.Request().Context(), profile.UserID); err != nil {
-		return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+	items := make([]map[string]interface{}, 0, len(index.Skills))
+	for _, entry := range index.Skills {
+		versions := make([]map[string]interface{}, 0, len(entry.Versions))
+		for _, version := range entry.Versions {
+			versions = append(versions, map[string]interface{}{
+				"version":      version.Version,
+				"changelog":    version.ShortChangelog(),
+				"published_at": version.PublishedAt,
+			})
+		}
+		current := installedByID[entry.ID]
+		items = append(items, map[string]interface{}{
+			"id":                entry.ID,
+			"name":              entry.Name,
+			"description":       entry.Description,
+			"repo":              entry.Repo,
+			"tags":              nonNilStrings(entry.Tags),
+			"permissions":       nonNilStrings(entry.Permissions.Requested()),
+			"versions":          versions,
+			"installed_version": current.Version,
+			"pinned":            current.Pinned,
+		})
 	}
 
-	payload, err := s.buildWechatBindingPayload(authSvc, profile.UserID)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-	}
-	return c.JSON(http.StatusOK, payload)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"configured": true,
+		"index_only": s.skillsMgr.IndexOnly(),
+		"skills":     items,
+	})
 }
 
-func (s *Server) handlePollWechatBinding(c *echo.Context) error {
-	authSvc, err := s.ilinkAuthService()
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-	}
-	profile, err := s.currentAuthUser(c)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+// handleInstallIndexedSkill installs a version of an indexed skill after the
+// user confirmed its changelog and permissions.
+func (s *Server) handleInstallIndexedSkill(c *echo.Context) error {
+	if s.skillsMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "skills manager not available"})
 	}
-
-	if _, err := authSvc.PollBinding(c.Request().Context(), profile.UserID); err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "no active ilink binding") {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "no active wechat binding"})
-		}
-		return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+	var body struct {
+		ID      string `json:"id"`
+		Version string `json:"version"`
 	}
-
-	if err := s.syncWechatBindingToAccounts(c.Request().Context(), authSvc, profile); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
 	}
-
-	if err := s.reloadWechatChannelsIfAvailable(); err != nil {
-		s.logger.Error("Failed to reload WeChat after binding", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if body.ID = strings.TrimSpace(body.ID); body.ID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
 	}
 
-	payload, err := s.buildWechatBindingPayload(authSvc, profile.UserID)
+	installed, err := s.skillsMgr.InstallIndexed(c.Request().Context(), body.ID, body.Version)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		s.recordAudit(c, audit.Event{Action: audit.ActionSkillInstall, Target: body.ID, Summary: err.Error()})
+		status := http.StatusBadRequest
+		if errors.Is(err, skills.ErrSkillNotInIndex) {
+			status = http.StatusNotFound
+		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, payload)
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionSkillInstall,
+		Target:  installed.ID,
+		Success: true,
+		Summary: fmt.Sprintf("version=%s permissions=%s", installed.Version, strings.Join(installed.Permissions.Requested(), ",")),
+	})
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"status": "installed",
+		"skill":  installed,
+	})
 }
 
-func (s *Server) handleDeleteWechatBinding(c *echo.Context) error {
-	authSvc, err := s.ilinkAuthService()
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+func (s *Server) skillSnapshotPolicy() config.SkillSnapshotsConfig {
+	if s.config == nil {
+		return config.DefaultConfig().WebUI.SkillSnapshots
 	}
-	profile, err := s.currentAuthUser(c)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	return s.config.WebUI.SkillSnapshots
+}
+
+func (s *Server) skillVersionPolicy() config.SkillVersionsConfig {
+	if s.config == nil {
+		return config.DefaultConfig().WebUI.SkillVersions
 	}
+	return s.config.WebUI.SkillVersions
+}
 
-	if _, err := authSvc.GetBinding(profile.UserID); err != nil {
-		if errors.Is(err, ilinkauth.ErrBindingNotFound) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "no active wechat binding"})
+func (s *Server) skillItem(skill *skills.Skill) map[string]interface{} {
+	var report *skills.SkillEntry
+	if s != nil && s.skillsMgr != nil && skill != nil {
+		report, _ = s.skillsMgr.CheckRequirementsReport(skill.ID)
+	}
+	if report == nil {
+		report = &skills.SkillEntry{
+			Skill:     skill,
+			Eligible:  true,
+			Installed: skill != nil && !strings.HasPrefix(strings.TrimSpace(skill.FilePath), "builtin://"),
+		}
+		if skill != nil && skill.Requirements != nil {
+			eligible, reasons := skills.CheckEligibility(skill)
+			report.Eligible = eligible
+			report.Reasons = append([]string(nil), reasons...)
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	if err := authSvc.DeleteBinding(profile.UserID); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	tags := append([]string(nil), skill.Tags...)
+	return map[string]interface{}{
+		"id":                    strings.TrimSpace(skill.ID),
+		"name":                  strings.TrimSpace(skill.Name),
+		"description":           strings.TrimSpace(skill.Description),
+		"version":               strings.TrimSpace(skill.Version),
+		"author":                strings.TrimSpace(skill.Author),
+		"enabled":               skill.Enabled,
+		"always":                skill.Always,
+		"file_path":             strings.TrimSpace(skill.FilePath),
+		"tags":                  tags,
+		"eligible":              report.Eligible,
+		"ineligibility_reasons": report.Reasons,
+		"missing_requirements": map[string]interface{}{
+			"binaries":        nonNilStrings(report.MissingBinaries),
+			"any_binaries":    nonNilStrings(report.MissingAnyBinaries),
+			"env":             nonNilStrings(report.MissingEnvVars),
+			"config_paths":    nonNilStrings(report.MissingPaths),
+			"python_packages": nonNilStrings(report.MissingPythonPackages),
+			"node_packages":   nonNilStrings(report.MissingNodePackages),
+		},
+		"install_specs":        installSpecs(skill),
+		"is_installed":         report.Installed,
+		"trusted":              skill.Trusted(),
+		"permissions_declared": skill.Permissions != nil,
+		"permissions":          nonNilStrings(skill.Permissions.Requested()),
 	}
+}
 
-	if err := s.deleteWechatChannelAccount(c.Request().Context(), profile.UserID); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+func (s *Server) handleGetWorkspaceStatus(c *echo.Context) error {
+	if s.workspace == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "workspace manager not available"})
 	}
 
-	if err := s.reloadWechatChannelsIfAvailable(); err != nil {
-		s.logger.Error("Failed to reload WeChat after unbind", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	status, err := s.workspace.Inspect()
+	if err != nil {
+		s.logger.Error("Failed to inspect workspace", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to inspect workspace"})
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+	return c.JSON(http.StatusOK, status)
 }
 
-func (s *Server) handleActivateWechatBinding(c *echo.Context) error {
-	if s.accountMgr == nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "channel account manager is unavailable"})
+func (s *Server) handleRepairWorkspace(c *echo.Context) error {
+	if s.workspace == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "workspace manager not available"})
 	}
 
-	accountID := strings.TrimSpace(c.FormValue("account_id"))
-	if accountID == "" {
-		var body struct {
-			AccountID string `json:"account_id"`
-		}
-		if err := c.Bind(&body); err == nil {
-			accountID = strings.TrimSpace(body.AccountID)
-		}
-	}
-	if accountID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "account_id is required"})
+	if err := s.workspace.Ensure(); err != nil {
+		s.logger.Error("Failed to repair workspace", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to repair workspace"})
 	}
 
-	profile, err := s.currentAuthUser(c)
+	status, err := s.workspace.Inspect()
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
-	}
-
-	if err := s.activateWechatChannelAccount(c.Request().Context(), profile.UserID, accountID); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
-	}
-	if err := s.reloadWechatChannelsIfAvailable(); err != nil {
-		s.logger.Error("Failed to reload WeChat after activation", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		s.logger.Error("Failed to inspect workspace after repair", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to inspect workspace"})
 	}
 
-	authSvc, err := s.ilinkAuthService()
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-	}
-	payload, err := s.buildWechatBindingPayload(authSvc, profile.UserID)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-	}
-	return c.JSON(http.StatusOK, payload)
+	return c.JSON(http.StatusOK, status)
 }
 
-func (s *Server) handleDeleteWechatBindingAccount(c *echo.Context) error {
-	if s.accountMgr == nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "channel account manager is unavailable"})
-	}
-
-	accountID := strings.TrimSpace(c.Param("accountId"))
-	if accountID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "accountId is required"})
-	}
-	profile, err := s.currentAuthUser(c)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
-	}
-	if err := s.removeWechatChannelAccount(c.Request().Context(), profile.UserID, accountID); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
-	}
-	if err := s.reloadWechatChannelsIfAvailable(); err != nil {
-		s.logger.Error("Failed to reload WeChat after account delete", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-	}
+func (s *Server) handleGetQMDStatus(c *echo.Context) error {
+	qmdMgr, resolvedCommand, commandSource := s.newQMDManager()
+	status := qmdMgr.GetStatus()
+	exportDir := s.qmdResolvedExportDir()
+	exportCount, _ := s.countQMDExportFiles(exportDir)
 
-	authSvc, err := s.ilinkAuthService()
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"enabled":                   s.config.Memory.QMD.Enabled,
+		"command":                   strings.TrimSpace(s.config.Memory.QMD.Command),
+		"resolved_command":          resolvedCommand,
+		"command_source":            commandSource,
+		"persistent_command":        s.persistentQMDBinary(),
+		"include_default":           s.config.Memory.QMD.IncludeDefault,
+		"available":                 status.Available,
+		"version":                   status.Version,
+		"error":                     status.Error,
+		"last_update":               status.LastUpdate.Format(time.RFC3339),
+		"collections":               status.Collections,
+		"sessions_enabled":          s.config.Memory.QMD.Sessions.Enabled,
+		"session_export_dir":        exportDir,
+		"session_retention_days":    s.config.Memory.QMD.Sessions.RetentionDays,
+		"session_export_file_count": exportCount,
+	})
+}
+
+func (s *Server) handleInstallQMD(c *echo.Context) error {
+	prefix := s.persistentQMDPrefix()
+	if err := os.MkdirAll(prefix, 0o755); err != nil {
+		s.logger.Error("Failed to create persistent qmd directory", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to prepare qmd install directory"})
 	}
-	payload, err := s.buildWechatBindingPayload(authSvc, profile.UserID)
+
+	cmd := exec.CommandContext(
+		c.Request().Context(),
+		defaultNPMCommand,
+		"install",
+		"-g",
+		"--prefix",
+		prefix,
+		defaultQMDNPMPackage,
+	)
+	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		s.logger.Error("Failed to install qmd",
+			zap.String("prefix", prefix),
+			zap.ByteString("output", output),
+			zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error":  "failed to install qmd",
+			"output": strings.TrimSpace(string(output)),
+		})
 	}
-	return c.JSON(http.StatusOK, payload)
+
+	qmdMgr, resolvedCommand, commandSource := s.newQMDManager()
+	status := qmdMgr.GetStatus()
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"installed":          true,
+		"package":            defaultQMDNPMPackage,
+		"prefix":             prefix,
+		"binary":             s.persistentQMDBinary(),
+		"command":            strings.TrimSpace(s.config.Memory.QMD.Command),
+		"resolved_command":   resolvedCommand,
+		"command_source":     commandSource,
+		"persistent_command": s.persistentQMDBinary(),
+		"available":          status.Available,
+		"version":            status.Version,
+		"error":              status.Error,
+		"output":             strings.TrimSpace(string(output)),
+	})
 }
 
-func (s *Server) buildWechatBindingPayload(authSvc *ilinkauth.Service, userID string) (map[string]interface{}, error) {
-	payload := map[string]interface{}{
-		"bound": false,
+func (s *Server) handleUpdateQMD(c *echo.Context) error {
+	qmdMgr, resolvedCommand, commandSource := s.newQMDManager()
+	if !qmdMgr.IsAvailable() {
+		status := qmdMgr.GetStatus()
+		return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+			"error":            "qmd not available",
+			"available":        false,
+			"detail":           status.Error,
+			"resolved_command": resolvedCommand,
+			"command_source":   commandSource,
+		})
 	}
 
-	binding, err := authSvc.GetBinding(userID)
-	if err != nil && !errors.Is(err, ilinkauth.ErrBindingNotFound) {
-		return nil, fmt.Errorf("load ilink binding: %w", err)
+	ctx := c.Request().Context()
+	if err := qmdMgr.Initialize(ctx, s.config.WorkspacePath()); err != nil {
+		s.logger.Error("Failed to initialize qmd", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to initialize qmd"})
 	}
-	activeAccountBotID := ""
-	if binding != nil {
-		payload["bound"] = true
-		activeAccountBotID = strings.TrimSpace(binding.Credentials.ILinkBotID)
-		payload["account"] = map[string]interface{}{
-			"bot_id":  binding.Credentials.ILinkBotID,
-			"user_id": binding.Credentials.ILinkUserID,
-		}
+	if err := qmdMgr.UpdateAll(ctx); err != nil {
+		s.logger.Error("Failed to update qmd", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update qmd"})
 	}
 
-	if s.accountMgr != nil {
-		accounts, err := s.accountMgr.List(context.Background())
-		if err != nil {
-			return nil, fmt.Errorf("list wechat channel accounts: %w", err)
-		}
-		items := make([]map[string]interface{}, 0, len(accounts))
-		for _, account := range accounts {
-			if account.ChannelType != "wechat" {
-				continue
-			}
-			if !wechatAccountBelongsToUser(account, userID) {
-				continue
-			}
-			botID, _ := account.Config["ilink_bot_id"].(string)
-			ilinkUserID, _ := account.Config["ilink_user_id"].(string)
-			items = append(items, map[string]interface{}{
-				"account_id": account.ID,
-				"bot_id":     botID,
-				"user_id":    ilinkUserID,
-				"active":     activeAccountBotID != "" && strings.TrimSpace(botID) == activeAccountBotID,
-			})
-			if activeAccountBotID != "" && strings.TrimSpace(botID) == activeAccountBotID {
-				payload["active_account_id"] = account.ID
-			}
-		}
-		payload["accounts"] = items
+	status := qmdMgr.GetStatus()
+	exportDir := s.qmdResolvedExportDir()
+	exportCount, _ := s.countQMDExportFiles(exportDir)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"enabled":                   s.config.Memory.QMD.Enabled,
+		"command":                   strings.TrimSpace(s.config.Memory.QMD.Command),
+		"resolved_command":          resolvedCommand,
+		"command_source":            commandSource,
+		"persistent_command":        s.persistentQMDBinary(),
+		"include_default":           s.config.Memory.QMD.IncludeDefault,
+		"available":                 status.Available,
+		"version":                   status.Version,
+		"error":                     status.Error,
+		"last_update":               status.LastUpdate.Format(time.RFC3339),
+		"collections":               status.Collections,
+		"sessions_enabled":          s.config.Memory.QMD.Sessions.Enabled,
+		"session_export_dir":        exportDir,
+		"session_retention_days":    s.config.Memory.QMD.Sessions.RetentionDays,
+		"session_export_file_count": exportCount,
+	})
+}
+
+func (s *Server) handleCleanupQMDSessionExports(c *echo.Context) error {
+	if !s.config.Memory.QMD.Sessions.Enabled {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "qmd session export is disabled"})
+	}
+	if s.config.Memory.QMD.Sessions.RetentionDays < 1 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "qmd session export retention_days must be at least 1"})
 	}
 
-	state, err := authSvc.LoadBindSession(userID)
+	exportDir := s.qmdResolvedExportDir()
+	exporter := memoryqmd.NewSessionExporter(s.logger, exportDir, s.config.Memory.QMD.Sessions.RetentionDays)
+	deleted, err := exporter.CleanupOldExportsCount(c.Request().Context())
 	if err != nil {
-		return nil, fmt.Errorf("load ilink bind session: %w", err)
+		s.logger.Error("Failed to cleanup qmd session exports", zap.String("export_dir", exportDir), zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to cleanup qmd session exports"})
 	}
-	if state != nil {
-		bind := map[string]interface{}{
-			"status":         state.Status,
-			"qrcode_content": state.QRCodeContent,
-			"updated_at":     state.UpdatedAt,
-			"bot_id":         state.BotID,
-			"user_id":        state.ILinkUserID,
-			"error":          state.Error,
-		}
-		if strings.TrimSpace(state.QRCodeContent) != "" {
-			if dataURL, err := encodeQRCodeDataURL(state.QRCodeContent); err == nil {
-				bind["qr_png_data_url"] = dataURL
-			}
-		}
-		payload["binding"] = bind
+
+	remaining, countErr := s.countQMDExportFiles(exportDir)
+	if countErr != nil {
+		s.logger.Warn("Failed to count qmd session exports after cleanup", zap.String("export_dir", exportDir), zap.Error(countErr))
 	}
 
-	return payload, nil
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"deleted":                deleted,
+		"remaining":              remaining,
+		"session_export_dir":     exportDir,
+		"session_retention_days": s.config.Memory.QMD.Sessions.RetentionDays,
+	})
 }
 
-func (s *Server) syncWechatBindingToAccounts(ctx context.Context, authSvc *ilinkauth.Service, profile *config.AuthProfile) error {
-	if s == nil || s.accountMgr == nil {
-		return nil
-	}
-	if profile == nil {
-		return fmt.Errorf("auth profile is required")
-	}
-	userID := strings.TrimSpace(profile.UserID)
-	if userID == "" {
-		return fmt.Errorf("user id is required")
+func (s *Server) newQMDManager() (*memoryqmd.Manager, string, string) {
+	qmdCfg := memoryqmd.ConfigFromConfigWithWorkspace(s.config.Memory.QMD, s.config.WorkspacePath())
+	resolvedCommand, commandSource := s.resolveQMDCommand()
+	qmdCfg.Command = resolvedCommand
+	return memoryqmd.NewManager(s.logger, qmdCfg), resolvedCommand, commandSource
+}
+
+func (s *Server) persistentQMDPrefix() string {
+	return filepath.Join(s.config.WorkspacePath(), ".nekobot", "runtime", "qmd")
+}
+
+func (s *Server) qmdResolvedExportDir() string {
+	return memoryqmd.ConfigFromConfigWithWorkspace(s.config.Memory.QMD, s.config.WorkspacePath()).Sessions.ExportDir
+}
+
+func (s *Server) countQMDExportFiles(exportDir string) (int, error) {
+	if strings.TrimSpace(exportDir) == "" {
+		return 0, nil
 	}
 
-	binding, err := authSvc.GetBinding(userID)
+	entries, err := os.ReadDir(exportDir)
 	if err != nil {
-		if errors.Is(err, ilinkauth.ErrBindingNotFound) {
-			return nil
+		if os.IsNotExist(err) {
+			return 0, nil
 		}
-		return fmt.Errorf("get ilink binding: %w", err)
-	}
-	if binding == nil {
-		return nil
+		return 0, err
 	}
 
-	store, err := channelwechat.NewCredentialStore(s.config)
-	if err != nil {
-		return fmt.Errorf("create wechat credential store: %w", err)
-	}
-	if err := store.SaveCredentials((*channelwechat.Credentials)(&binding.Credentials), true); err != nil {
-		return fmt.Errorf("save wechat credentials: %w", err)
-	}
-
-	accountKey := strings.TrimSpace(binding.Credentials.ILinkBotID)
-	if accountKey == "" {
-		return fmt.Errorf("ilink bot id is empty")
+	count := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(strings.ToLower(entry.Name()), ".md") {
+			continue
+		}
+		count++
 	}
+	return count, nil
+}
 
-	accounts, err := s.accountMgr.List(ctx)
-	if err != nil {
-		return fmt.Errorf("list channel accounts: %w", err)
-	}
+func (s *Server) persistentQMDBinary() string {
+	return filepath.Join(s.persistentQMDPrefix(), "bin", "qmd")
+}
 
-	payload := channelaccounts.ChannelAccount{
-		ChannelType: "wechat",
-		AccountKey:  accountKey,
-		DisplayName: accountKey,
-		Enabled:     true,
-		TenantID:    strings.TrimSpace(profile.TenantID),
-		OwnerUserID: userID,
-		Visibility:  ownership.VisibilityPrivate,
-		Config: map[string]interface{}{
-			"enabled":       true,
-			"ilink_bot_id":  binding.Credentials.ILinkBotID,
-			"ilink_user_id": binding.Credentials.ILinkUserID,
-			"base_url":      binding.Credentials.BaseURL,
-			"bot_token":     binding.Credentials.BotToken,
-		},
-		Metadata: map[string]interface{}{
-			"owner_user_id": strings.TrimSpace(userID),
-			"source":        "ilinkauth",
-		},
+func (s *Server) resolveQMDCommand() (string, string) {
+	configured := strings.TrimSpace(s.config.Memory.QMD.Command)
+	if configured != "" && configured != "qmd" {
+		return configured, "config"
 	}
 
-	for _, item := range accounts {
-		if item.ChannelType != "wechat" || item.AccountKey != accountKey {
-			continue
-		}
-		_, err := s.accountMgr.Update(ctx, item.ID, payload)
-		if err != nil {
-			return fmt.Errorf("update wechat channel account %s: %w", item.ID, err)
-		}
-		return nil
+	persistentBinary := s.persistentQMDBinary()
+	if info, err := os.Stat(persistentBinary); err == nil && !info.IsDir() {
+		return persistentBinary, "workspace"
 	}
 
-	if _, err := s.accountMgr.Create(ctx, payload); err != nil {
-		return fmt.Errorf("create wechat channel account: %w", err)
+	if configured != "" {
+		return configured, "config"
 	}
-	return nil
+
+	return "qmd", "path"
 }
 
-func (s *Server) reloadWechatChannelsIfAvailable() error {
-	if s == nil || s.channels == nil {
+func installSpecs(skill *skills.Skill) []map[string]interface{} {
+	if skill == nil || skill.Requirements == nil {
 		return nil
 	}
-	return s.reloadChannelsByType("wechat")
-}
 
-func wechatAccountBelongsToUser(account channelaccounts.ChannelAccount, userID string) bool {
-	userID = strings.TrimSpace(userID)
-	if userID == "" {
-		return false
+	specs := skills.ParseRequirementsToSpecs(skill.Requirements)
+	if len(specs) == 0 {
+		return nil
 	}
-	if strings.TrimSpace(account.OwnerUserID) == userID {
-		return true
+
+	out := make([]map[string]interface{}, 0, len(specs))
+	for _, spec := range specs {
+		out = append(out, map[string]interface{}{
+			"method":    strings.TrimSpace(spec.Method),
+			"package":   strings.TrimSpace(spec.Package),
+			"version":   strings.TrimSpace(spec.Version),
+			"post_hook": strings.TrimSpace(spec.PostHook),
+			"options":   spec.Options,
+		})
 	}
-	owner, _ := account.Metadata["owner_user_id"].(string)
-	return strings.TrimSpace(owner) == userID
+	return out
 }
 
-func (s *Server) deleteWechatChannelAccount(ctx context.Context, userID string) error {
-	if s == nil || s.accountMgr == nil {
-		return nil
-	}
-	accounts, err := s.accountMgr.List(ctx)
-	if err != nil {
-		return fmt.Errorf("list wechat channel accounts: %w", err)
-	}
-	for _, item := range accounts {
-		if item.ChannelType != "wechat" {
-			continue
-		}
-		if !wechatAccountBelongsToUser(item, userID) {
-			continue
+func readSkillContent(skill *skills.Skill) (string, string, error) {
+	filePath := strings.TrimSpace(skill.FilePath)
+	var raw string
+	if strings.HasPrefix(filePath, "builtin://") {
+		builtinRaw, err := skills.ReadBuiltinSkillContent(filePath)
+		if err != nil {
+			return "", "", err
 		}
-		if s.bindingMgr != nil {
-			if err := s.bindingMgr.DeleteByChannelAccountID(ctx, item.ID); err != nil {
-				return fmt.Errorf("delete wechat bindings for channel account %s: %w", item.ID, err)
-			}
+		raw = builtinRaw
+	} else {
+		rawBytes, err := os.ReadFile(filePath)
+		if err != nil {
+			return "", "", fmt.Errorf("read skill file %s: %w", filePath, err)
 		}
-		if err := s.accountMgr.Delete(ctx, item.ID); err != nil {
-			return fmt.Errorf("delete wechat channel account %s: %w", item.ID, err)
+		raw = string(rawBytes)
+	}
+
+	bodyRaw := strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "---\n") {
+		parts := strings.SplitN(raw[4:], "\n---\n", 2)
+		if len(parts) == 2 {
+			bodyRaw = strings.TrimSpace(parts[1])
 		}
 	}
-	return nil
+
+	return raw, bodyRaw, nil
 }
 
-func (s *Server) activateWechatChannelAccount(ctx context.Context, userID, accountID string) error {
-	authSvc, err := s.ilinkAuthService()
-	if err != nil {
-		return err
-	}
-	account, err := s.accountMgr.Get(ctx, accountID)
-	if err != nil {
-		return err
+func (s *Server) handleEnableSkill(c *echo.Context) error {
+	if s.skillsMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "skills manager not available"})
 	}
-	if account == nil || account.ChannelType != "wechat" {
-		return fmt.Errorf("wechat account not found")
+
+	skillID := strings.TrimSpace(c.Param("id"))
+	if _, ok := s.resolveSkill(skillID); !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "skill not found"})
 	}
-	if !wechatAccountBelongsToUser(*account, userID) {
-		return fmt.Errorf("wechat account does not belong to current user")
+	if err := s.skillsMgr.Enable(skillID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to enable skill"})
 	}
 
-	botID, _ := account.Config["ilink_bot_id"].(string)
-	botToken, _ := account.Config["bot_token"].(string)
-	ilinkUserID, _ := account.Config["ilink_user_id"].(string)
-	baseURL, _ := account.Config["base_url"].(string)
-	if strings.TrimSpace(botID) == "" || strings.TrimSpace(botToken) == "" {
-		return fmt.Errorf("wechat account credentials are incomplete")
-	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "enabled"})
+}
 
-	store, err := channelwechat.NewCredentialStore(s.config)
-	if err != nil {
-		return fmt.Errorf("create wechat credential store: %w", err)
-	}
-	if err := store.SetActiveAccount(botID); err != nil {
-		return fmt.Errorf("set active wechat account: %w", err)
+func (s *Server) handleDisableSkill(c *echo.Context) error {
+	if s.skillsMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "skills manager not available"})
 	}
 
-	if err := authSvc.DeleteBinding(userID); err != nil {
-		return err
+	skillID := strings.TrimSpace(c.Param("id"))
+	if _, ok := s.resolveSkill(skillID); !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "skill not found"})
+	}
+	if err := s.skillsMgr.Disable(skillID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to disable skill"})
 	}
 
-	return authSvc.SaveBinding(&ilinkauth.Binding{
-		UserID: strings.TrimSpace(userID),
-		Credentials: wxtypes.Credentials{
-			BotToken:    botToken,
-			ILinkBotID:  botID,
-			BaseURL:     baseURL,
-			ILinkUserID: ilinkUserID,
-		},
-	})
+	return c.JSON(http.StatusOK, map[string]string{"status": "disabled"})
 }
 
-func (s *Server) removeWechatChannelAccount(ctx context.Context, userID, accountID string) error {
-	authSvc, err := s.ilinkAuthService()
-	if err != nil {
-		return err
-	}
-	account, err := s.accountMgr.Get(ctx, accountID)
-	if err != nil {
-		return err
+func errorString(err error) string {
+	if err == nil {
+		return ""
 	}
-	if account == nil || account.ChannelType != "wechat" {
-		return fmt.Errorf("wechat account not found")
+	return err.Error()
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
 	}
-	if !wechatAccountBelongsToUser(*account, userID) {
-		return fmt.Errorf("wechat account does not belong to current user")
+	return values
+}
+
+// --- Channel Handlers ---
+
+func (s *Server) handleGetChannels(c *echo.Context) error {
+	payload := map[string]interface{}{}
+	for name, cfg := range channels.ListChannelConfigs(s.config) {
+		payload[name] = cfg
 	}
-	if s.bindingMgr != nil {
-		if err := s.bindingMgr.DeleteByChannelAccountID(ctx, account.ID); err != nil {
-			return err
-		}
+	payload["_instances"] = s.listChannelInstances()
+	return c.JSON(http.StatusOK, payload)
+}
+
+func (s *Server) handleUpdateChannel(c *echo.Context) error {
+	name := c.Param("name")
+	before, _ := json.Marshal(channels.ListChannelConfigs(s.config)[name])
+
+	var body map[string]interface{}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	if err := s.accountMgr.Delete(ctx, account.ID); err != nil {
-		return err
+
+	// Marshal the body to JSON, then unmarshal into the appropriate channel config.
+	data, err := json.Marshal(body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
 	}
 
-	store, err := channelwechat.NewCredentialStore(s.config)
+	nextConfig, err := cloneConfigSnapshot(s.config)
 	if err != nil {
-		return fmt.Errorf("create wechat credential store: %w", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if err := store.DeleteCredentials(account.AccountKey); err != nil {
-		return fmt.Errorf("delete wechat local credentials: %w", err)
+
+	if err := channels.ApplyChannelConfig(nextConfig, name, data); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := config.ValidateConfig(nextConfig); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	active, err := authSvc.GetBinding(userID)
-	if err != nil && !errors.Is(err, ilinkauth.ErrBindingNotFound) {
-		return err
+	enabled, err := channels.IsChannelEnabled(name, nextConfig)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	if active != nil && strings.TrimSpace(active.Credentials.ILinkBotID) == strings.TrimSpace(account.AccountKey) {
-		if err := authSvc.DeleteBinding(userID); err != nil {
-			return err
-		}
 
-		remaining, listErr := s.accountMgr.List(ctx)
-		if listErr != nil {
-			return listErr
-		}
-		for _, item := range remaining {
-			if item.ChannelType != "wechat" {
-				continue
-			}
-			if !wechatAccountBelongsToUser(item, userID) {
-				continue
-			}
-			return s.activateWechatChannelAccount(ctx, userID, item.ID)
+	var rebuilt channels.Channel
+	if enabled {
+		rebuilt, err = channels.BuildChannel(
+			name,
+			s.logger,
+			s.bus,
+			s.agent,
+			s.commands,
+			s.prefs,
+			s.toolSess,
+			s.processMgr,
+			nextConfig,
+		)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 		}
 	}
-	return nil
-}
 
-func encodeQRCodeDataURL(content string) (string, error) {
-	code, err := rscqr.Encode(content, rscqr.M)
-	if err != nil {
-		return "", fmt.Errorf("encode QR code: %w", err)
+	// Persist runtime channel config to database.
+	if err := config.SaveDatabaseSections(nextConfig, "channels"); err != nil {
+		s.logger.Error("Failed to persist channel config", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save config"})
 	}
-	code.Scale = 8
-	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(code.PNG()), nil
-}
 
-// --- Config Handlers ---
+	if enabled {
+		if err := s.channels.ReloadChannel(rebuilt); err != nil {
+			s.logger.Error("Failed to reload channel", zap.String("channel", name), zap.Error(err))
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "channel config saved but reload failed: " + err.Error(),
+			})
+		}
+	} else {
+		if err := s.channels.StopChannel(name); err != nil {
+			s.logger.Error("Failed to stop channel", zap.String("channel", name), zap.Error(err))
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "channel config saved but reload failed: " + err.Error(),
+			})
+		}
+	}
+	s.config.Channels = nextConfig.Channels
 
-func (s *Server) handleGetConfig(c *echo.Context) error {
-	// Return sanitized config (no secrets)
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"storage":       s.config.Storage,
-		"agents":        s.config.Agents,
-		"gateway":       s.config.Gateway,
-		"tools":         s.config.Tools,
-		"heartbeat":     s.config.Heartbeat,
-		"webhook":       s.config.Webhook,
-		"redis":         s.config.Redis,
-		"state":         s.config.State,
-		"bus":           s.config.Bus,
-		"approval":      s.config.Approval,
-		"logger":        s.config.Logger,
-		"memory":        s.config.Memory,
-		"sessions":      s.config.Sessions,
-		"webui":         s.config.WebUI,
-		"transcription": s.config.Transcription,
-		"audit":         s.config.Audit,
-		"undo":          s.config.Undo,
-		"preprocess":    s.config.Preprocess,
-		"learnings":     s.config.Learnings,
-		"watch":         s.config.Watch,
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionConfigUpdate,
+		Target:  "channels." + name,
+		Success: true,
+		Summary: audit.Diff(json.RawMessage(before), channels.ListChannelConfigs(s.config)[name]),
 	})
+
+	return c.JSON(http.StatusOK, map[string]string{"status": "updated", "channel": name, "reload": "ok"})
 }
 
-func (s *Server) handleSaveConfig(c *echo.Context) error {
-	previousStorage := s.config.Storage
-	oldRuntimeDBPath := ""
-	oldRuntimeDBIsSQLite := s.config.DatabaseType() == "sqlite"
-	if oldRuntimeDBIsSQLite {
-		currentPath, err := config.RuntimeDBPath(s.config)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-		}
-		oldRuntimeDBPath = currentPath
+func (s *Server) listChannelInstances() []map[string]interface{} {
+	if s.channels == nil {
+		return []map[string]interface{}{}
 	}
 
-	var body struct {
-		Storage       *config.StorageConfig       `json:"storage"`
-		Agents        *config.AgentsConfig        `json:"agents"`
-		Gateway       *config.GatewayConfig       `json:"gateway"`
-		Tools         *config.ToolsConfig         `json:"tools"`
-		Heartbeat     *config.HeartbeatConfig     `json:"heartbeat"`
-		Webhook       *config.WebhookConfig       `json:"webhook"`
-		Redis         *config.RedisConfig         `json:"redis"`
-		State         *config.StateConfig         `json:"state"`
-		Bus           *config.BusConfig           `json:"bus"`
-		Approval      *config.ApprovalConfig      `json:"approval"`
-		Logger        *config.LoggerConfig        `json:"logger"`
-		Memory        *config.MemoryConfig        `json:"memory"`
-		Sessions      *config.SessionsConfig      `json:"sessions"`
-		WebUI         *config.WebUIConfig         `json:"webui"`
-		Transcription *config.TranscriptionConfig `json:"transcription"`
-		Audit         *config.AuditConfig         `json:"audit"`
-		Undo          *config.UndoConfig          `json:"undo"`
-		Preprocess    *config.PreprocessConfig    `json:"preprocess"`
-		Learnings     *config.LearningsConfig     `json:"learnings"`
-		Watch         *config.WatchConfig         `json:"watch"`
+	items := s.channels.ListChannels()
+	result := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		entry := map[string]interface{}{
+			"id":      item.ID(),
+			"name":    item.Name(),
+			"enabled": item.IsEnabled(),
+			"type":    item.ID(),
+		}
+		if typed, ok := item.(channels.TypedChannel); ok {
+			entry["type"] = typed.ChannelType()
+		}
+		result = append(result, entry)
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	return result
+}
+
+func (s *Server) reloadChannel(name string) error {
+	enabled, err := channels.IsChannelEnabled(name, s.config)
+	if err != nil {
+		return err
 	}
 
-	// Apply partial updates
-	if body.Storage != nil {
-		s.config.Storage = *body.Storage
+	if !enabled {
+		return s.channels.StopChannel(name)
 	}
-	if body.Agents != nil {
-		s.config.Agents = *body.Agents
+
+	ch, err := channels.BuildChannel(name, s.logger, s.bus, s.agent, s.commands, s.prefs, s.toolSess, s.processMgr, s.config)
+	if err != nil {
+		return err
 	}
-	if body.Gateway != nil {
-		s.config.Gateway = *body.Gateway
+
+	return s.channels.ReloadChannel(ch)
+}
+
+func (s *Server) reloadChannelsByType(channelType string) error {
+	if s == nil || s.channels == nil {
+		return fmt.Errorf("channel manager is unavailable")
 	}
-	if body.Tools != nil {
-		s.config.Tools = *body.Tools
+
+	channelType = strings.TrimSpace(channelType)
+	if channelType == "" {
+		return fmt.Errorf("channel type is required")
 	}
-	if body.Heartbeat != nil {
-		s.config.Heartbeat = *body.Heartbeat
+
+	if s.accountMgr != nil {
+		accounts, err := s.accountMgr.List(context.Background())
+		if err != nil {
+			return fmt.Errorf("list channel accounts for %s: %w", channelType, err)
+		}
+
+		desiredIDs := make(map[string]struct{})
+		desiredAccounts := make([]channelaccounts.ChannelAccount, 0)
+		for _, account := range accounts {
+			if account.ChannelType != channelType || !account.Enabled {
+				continue
+			}
+			runtimeID := strings.TrimSpace(account.ChannelType) + ":" + strings.TrimSpace(account.AccountKey)
+			desiredIDs[runtimeID] = struct{}{}
+			desiredAccounts = append(desiredAccounts, account)
+		}
+		if channelType == "wechat" {
+			desiredAccounts = s.prioritizeActiveWechatAccount(desiredAccounts)
+		}
+
+		existingChannels := s.channels.ListChannelsByType(channelType)
+		if len(desiredAccounts) > 0 {
+			for _, existing := range existingChannels {
+				if _, ok := desiredIDs[existing.ID()]; ok {
+					continue
+				}
+				if err := s.channels.StopChannel(existing.ID()); err != nil {
+					return fmt.Errorf("stop stale %s runtime %s: %w", channelType, existing.ID(), err)
+				}
+				s.unregisterInboundRouteForChannelID(existing.ID())
+			}
+
+			for _, account := range desiredAccounts {
+				ch, err := channels.BuildChannelFromAccount(
+					account,
+					s.logger,
+					s.bus,
+					s.agent,
+					s.commands,
+					s.prefs,
+					s.toolSess,
+					s.processMgr,
+					s.config,
+				)
+				if err != nil {
+					return fmt.Errorf("build %s account runtime %s: %w", channelType, account.AccountKey, err)
+				}
+				if err := s.channels.ReloadChannel(ch); err != nil {
+					return fmt.Errorf("reload %s account runtime %s: %w", channelType, ch.ID(), err)
+				}
+				s.registerInboundRouteForChannelID(ch.ID())
+			}
+			return nil
+		}
+
+		for _, existing := range existingChannels {
+			if existing.ID() == channelType {
+				continue
+			}
+			if err := s.channels.StopChannel(existing.ID()); err != nil {
+				return fmt.Errorf("stop stale %s runtime %s: %w", channelType, existing.ID(), err)
+			}
+			s.unregisterInboundRouteForChannelID(existing.ID())
+		}
 	}
-	if body.Webhook != nil {
-		s.config.Webhook = *body.Webhook
+
+	return s.reloadChannel(channelType)
+}
+
+func (s *Server) registerInboundRouteForChannelID(channelID string) {
+	if s == nil || s.inboundRouter == nil {
+		return
 	}
-	if body.Redis != nil {
-		s.config.Redis = *body.Redis
+	s.inboundRouter.RegisterChannel(channelID)
+}
+
+func (s *Server) unregisterInboundRouteForChannelID(channelID string) {
+	if s == nil || s.inboundRouter == nil {
+		return
 	}
-	if body.State != nil {
-		s.config.State = *body.State
+	s.inboundRouter.UnregisterChannel(channelID)
+}
+
+func (s *Server) prioritizeActiveWechatAccount(
+	accounts []channelaccounts.ChannelAccount,
+) []channelaccounts.ChannelAccount {
+	if len(accounts) < 2 || s == nil || s.config == nil {
+		return accounts
 	}
-	if body.Bus != nil {
-		s.config.Bus = *body.Bus
+
+	store, err := channelwechat.NewCredentialStore(s.config)
+	if err != nil {
+		return accounts
 	}
-	if body.Approval != nil {
-		s.config.Approval = *body.Approval
+	creds, err := store.LoadCredentials()
+	if err != nil || creds == nil {
+		return accounts
 	}
-	if body.Logger != nil {
-		s.config.Logger = *body.Logger
+
+	activeBotID := strings.TrimSpace(creds.ILinkBotID)
+	if activeBotID == "" {
+		return accounts
 	}
-	if body.Memory != nil {
-		s.config.Memory = *body.Memory
+
+	activeIndex := -1
+	for i, account := range accounts {
+		if strings.TrimSpace(account.AccountKey) == activeBotID {
+			activeIndex = i
+			break
+		}
 	}
-	if body.Sessions != nil {
-		s.config.Sessions = *body.Sessions
+	if activeIndex <= 0 {
+		return accounts
 	}
-	if body.WebUI != nil {
-		s.config.WebUI = *body.WebUI
-		if s.toolSess != nil {
-			s.toolSess.SetEventConfig(s.config.WebUI.ToolSessionEvents)
-		}
-		if s.skillsMgr != nil {
-			s.skillsMgr.SetSnapshotRetention(skills.SnapshotRetentionConfig{
-				AutoPrune: s.config.WebUI.SkillSnapshots.AutoPrune,
-				MaxCount:  s.config.WebUI.SkillSnapshots.MaxCount,
-			})
-			s.skillsMgr.SetVersionRetention(skills.VersionRetentionConfig{
-				Enabled:  s.config.WebUI.SkillVersions.Enabled,
-				MaxCount: s.config.WebUI.SkillVersions.MaxCount,
-			})
-		}
-	}
-	if body.Transcription != nil {
-		s.config.Transcription = *body.Transcription
-	}
-	if body.Audit != nil {
-		s.config.Audit = *body.Audit
-	}
-	if body.Undo != nil {
-		s.config.Undo = *body.Undo
-	}
-	if body.Preprocess != nil {
-		s.config.Preprocess = *body.Preprocess
+
+	prioritized := make([]channelaccounts.ChannelAccount, 0, len(accounts))
+	prioritized = append(prioritized, accounts[activeIndex])
+	prioritized = append(prioritized, accounts[:activeIndex]...)
+	prioritized = append(prioritized, accounts[activeIndex+1:]...)
+	return prioritized
+}
+
+func (s *Server) reloadChannelForAccount(channelType, accountID string) error {
+	channelType = strings.TrimSpace(channelType)
+	accountID = strings.TrimSpace(accountID)
+	if channelType == "" || accountID == "" || s.accountMgr == nil {
+		return s.reloadChannel(channelType)
 	}
-	if body.Learnings != nil {
-		s.config.Learnings = *body.Learnings
+
+	account, err := s.accountMgr.Get(context.Background(), accountID)
+	if err != nil {
+		return s.reloadChannel(channelType)
 	}
-	if body.Watch != nil {
-		s.config.Watch = *body.Watch
+	if account == nil || strings.TrimSpace(account.ChannelType) != channelType {
+		return s.reloadChannel(channelType)
 	}
 
-	// Validate
-	if err := config.ValidateConfig(s.config); err != nil {
-		s.config.Storage = previousStorage
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	runtimeID := strings.TrimSpace(account.ChannelType) + ":" + strings.TrimSpace(account.AccountKey)
+	if !account.Enabled {
+		return s.channels.StopChannel(runtimeID)
 	}
 
-	restartSections := make([]string, 0, 5)
-	if body.Storage != nil || body.Logger != nil || body.Gateway != nil || body.WebUI != nil || body.Webhook != nil {
-		if body.Storage != nil && oldRuntimeDBIsSQLite && s.config.DatabaseType() == "sqlite" {
-			newRuntimeDBPath, err := config.RuntimeDBPath(s.config)
-			if err != nil {
-				s.config.Storage = previousStorage
-				return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-			}
-			if err := config.MigrateRuntimeDB(oldRuntimeDBPath, newRuntimeDBPath); err != nil {
-				s.config.Storage = previousStorage
-				return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-			}
-		}
-		if err := s.saveBootstrapConfig(); err != nil {
-			s.config.Storage = previousStorage
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-		}
-		if body.Storage != nil {
-			restartSections = append(restartSections, "storage")
-		}
-		if body.Logger != nil {
-			restartSections = append(restartSections, "logger")
-		}
-		if body.Gateway != nil {
-			restartSections = append(restartSections, "gateway")
-		}
-		if body.WebUI != nil {
-			restartSections = append(restartSections, "webui")
-		}
-		if body.Webhook != nil {
-			restartSections = append(restartSections, "webhook")
-		}
+	ch, err := channels.BuildChannelFromAccount(*account, s.logger, s.bus, s.agent, s.commands, s.prefs, s.toolSess, s.processMgr, s.config)
+	if err != nil {
+		return err
 	}
 
-	sections := make([]string, 0, 19)
-	if body.Agents != nil {
-		sections = append(sections, "agents")
-	}
-	if body.Gateway != nil {
-		sections = append(sections, "gateway")
-	}
-	if body.Tools != nil {
-		sections = append(sections, "tools")
-	}
-	if body.Heartbeat != nil {
-		sections = append(sections, "heartbeat")
+	return s.channels.ReloadChannel(ch)
+}
+
+func (s *Server) handleTestChannel(c *echo.Context) error {
+	name := c.Param("name")
+
+	ch, err := s.channels.GetChannel(name)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]interface{}{
+			"channel": name,
+			"status":  "not_found",
+			"error":   err.Error(),
+		})
 	}
-	if body.Webhook != nil {
-		sections = append(sections, "webhook")
+
+	result := map[string]interface{}{
+		"channel":   ch.Name(),
+		"id":        ch.ID(),
+		"enabled":   ch.IsEnabled(),
+		"reachable": false,
 	}
-	if body.Redis != nil {
-		sections = append(sections, "redis")
+
+	if !ch.IsEnabled() {
+		result["status"] = "disabled"
+		return c.JSON(http.StatusOK, result)
 	}
-	if body.State != nil {
-		sections = append(sections, "state")
+
+	healthChecker, ok := ch.(channels.HealthChecker)
+	if !ok {
+		result["status"] = "configured"
+		return c.JSON(http.StatusOK, result)
 	}
-	if body.Bus != nil {
-		sections = append(sections, "bus")
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+
+	if err := healthChecker.HealthCheck(ctx); err != nil {
+		result["status"] = "unreachable"
+		result["error"] = err.Error()
+		return c.JSON(http.StatusOK, result)
 	}
-	if body.Approval != nil {
-		sections = append(sections, "approval")
+
+	result["reachable"] = true
+	result["status"] = "ok"
+	return c.JSON(http.StatusOK, result)
+}
+
+// canManageChannels reports whether the user may see channel credentials
+// and pairing codes, as the /api/channels routes require.
+func (s *Server) canManageChannels(c *echo.Context) bool {
+	if !s.authContextFromEcho(c).InTenant("") {
+		return false
 	}
-	if body.Logger != nil {
-		sections = append(sections, "logger")
+	switch s.currentUserRole(c) {
+	case config.RoleAdmin, config.RoleOwner, config.RoleOperator:
+		return true
+	default:
+		return false
 	}
-	if body.Memory != nil {
-		sections = append(sections, "memory")
+}
+
+// whatsappChannel returns the running WhatsApp channel named by the
+// channel_id query parameter, by default the "whatsapp" channel.
+func (s *Server) whatsappChannel(c *echo.Context) (*channelwhatsapp.Channel, error) {
+	if s.channels == nil {
+		return nil, fmt.Errorf("channel manager not available")
 	}
-	if body.Sessions != nil {
-		sections = append(sections, "sessions")
+	id := strings.TrimSpace(c.QueryParam("channel_id"))
+	if id == "" {
+		id = "whatsapp"
 	}
-	if body.WebUI != nil {
-		sections = append(sections, "webui")
+	ch, err := s.channels.GetChannel(id)
+	if err != nil {
+		return nil, err
 	}
-	if body.Transcription != nil {
-		sections = append(sections, "transcription")
+	wa, ok := ch.(*channelwhatsapp.Channel)
+	if !ok {
+		return nil, fmt.Errorf("channel %s is not a WhatsApp channel", id)
 	}
-	if body.Audit != nil {
-		sections = append(sections, "audit")
+	return wa, nil
+}
+
+// whatsappStatusPayload is a WhatsApp channel status with its pairing code
+// rendered as a QR image.
+type whatsappStatusPayload struct {
+	channelwhatsapp.Status
+	QRPNGDataURL string `json:"qr_png_data_url,omitempty"`
+}
+
+func newWhatsAppStatusPayload(status channelwhatsapp.Status) whatsappStatusPayload {
+	payload := whatsappStatusPayload{Status: status}
+	if strings.TrimSpace(status.QRCode) != "" {
+		if dataURL, err := encodeQRCodeDataURL(status.QRCode); err == nil {
+			payload.QRPNGDataURL = dataURL
+		}
 	}
-	if body.Undo != nil {
-		sections = append(sections, "undo")
+	return payload
+}
+
+func (s *Server) handleGetWhatsAppStatus(c *echo.Context) error {
+	wa, err := s.whatsappChannel(c)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
-	if body.Preprocess != nil {
-		sections = append(sections, "preprocess")
+	return c.JSON(http.StatusOK, newWhatsAppStatusPayload(wa.Status()))
+}
+
+// handleWhatsAppPairingStream streams the status of a WhatsApp channel,
+// including each new pairing QR code, as server-sent events. The stream
+// ends when the channel stops or reloads.
+func (s *Server) handleWhatsAppPairingStream(c *echo.Context) error {
+	tokenStr := strings.TrimSpace(c.QueryParam("token"))
+	if tokenStr == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "token required"})
 	}
-	if body.Learnings != nil {
-		sections = append(sections, "learnings")
+	username, _, _, err := s.parseScopedStreamToken(tokenStr, streamTokenPurposeWhatsAppPairing)
+	if err != nil || strings.TrimSpace(username) == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid token"})
 	}
-	if body.Watch != nil {
-		sections = append(sections, "watch")
+	wa, err := s.whatsappChannel(c)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
 
-	// Persist runtime config sections to database.
-	if len(sections) > 0 {
-		if err := config.SaveDatabaseSections(s.config, sections...); err != nil {
-			s.logger.Error("Failed to persist config sections", zap.Error(err), zap.Strings("sections", sections))
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save config"})
-		}
+	res := c.Response()
+	req := c.Request()
+	flusher, ok := res.(http.Flusher)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "streaming unsupported"})
 	}
-	if body.Watch != nil {
-		if err := s.syncWatchRuntime(); err != nil {
-			if s.logger != nil {
-				s.logger.Error("Failed to sync watch runtime after config save", zap.Error(err))
+	res.Header().Set("Content-Type", "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+
+	updates, cancel := wa.Subscribe()
+	defer cancel()
+
+	_, _ = res.Write([]byte(": connected\n\n"))
+	flusher.Flush()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return nil
+		case status, ok := <-updates:
+			if !ok {
+				return nil
 			}
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to sync watch runtime"})
+			payload, _ := json.Marshal(newWhatsAppStatusPayload(status))
+			_, _ = res.Write([]byte("data: "))
+			_, _ = res.Write(payload)
+			_, _ = res.Write([]byte("\n\n"))
+			flusher.Flush()
 		}
 	}
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"status":           "saved",
-		"sections_saved":   len(sections),
-		"restart_required": len(restartSections) > 0,
-		"restart_sections": restartSections,
-	})
 }
 
-func (s *Server) handleExportConfig(c *echo.Context) error {
-	// Collect providers from the store
-	providerProfiles, err := s.providers.List(c.Request().Context())
+func (s *Server) handleGetChannelIdentity(c *echo.Context) error {
+	name := c.Param("name")
+
+	ch, err := s.channels.GetChannel(name)
 	if err != nil {
-		s.logger.Error("Failed to export providers", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load providers"})
-	}
-	providerList := make([]map[string]interface{}, len(providerProfiles))
-	for i, p := range providerProfiles {
-		providerList[i] = providerProfileToMap(p)
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
 
-	export := map[string]interface{}{
-		"storage":       s.config.Storage,
-		"agents":        s.config.Agents,
-		"gateway":       s.config.Gateway,
-		"tools":         s.config.Tools,
-		"heartbeat":     s.config.Heartbeat,
-		"redis":         s.config.Redis,
-		"state":         s.config.State,
-		"bus":           s.config.Bus,
-		"approval":      s.config.Approval,
-		"logger":        s.config.Logger,
-		"memory":        s.config.Memory,
-		"sessions":      s.config.Sessions,
-		"webui":         s.config.WebUI,
-		"transcription": s.config.Transcription,
-		"audit":         s.config.Audit,
-		"undo":          s.config.Undo,
-		"preprocess":    s.config.Preprocess,
-		"learnings":     s.config.Learnings,
-		"watch":         s.config.Watch,
-		"providers":     providerList,
+	result := map[string]interface{}{
+		"channel":   ch.Name(),
+		"id":        ch.ID(),
+		"supported": false,
 	}
-
-	c.Response().Header().Set("Content-Disposition", `attachment; filename="nekobot-config-export.json"`)
-	return c.JSON(http.StatusOK, export)
+	provider, ok := ch.(channels.IdentityProvider)
+	if !ok {
+		return c.JSON(http.StatusOK, result)
+	}
+	result["supported"] = true
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+
+	identity, err := provider.Identity(ctx)
+	if err != nil {
+		result["error"] = err.Error()
+		return c.JSON(http.StatusBadGateway, result)
+	}
+	result["identity"] = identity
+	return c.JSON(http.StatusOK, result)
 }
 
-func (s *Server) handleImportConfig(c *echo.Context) error {
-	previousStorage := s.config.Storage
-	oldRuntimeDBPath := ""
-	oldRuntimeDBIsSQLite := s.config.DatabaseType() == "sqlite"
-	if oldRuntimeDBIsSQLite {
-		currentPath, err := config.RuntimeDBPath(s.config)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-		}
-		oldRuntimeDBPath = currentPath
+type webWechatLoginClient struct{}
+
+func (webWechatLoginClient) FetchQRCode(ctx context.Context) (*wxtypes.QRCodeResponse, error) {
+	return wxauth.FetchQRCode(ctx)
+}
+
+func (webWechatLoginClient) CheckQRStatus(ctx context.Context, qrcode string) (*wxtypes.QRStatusResponse, error) {
+	return wxauth.CheckQRStatus(ctx, qrcode)
+}
+
+func (s *Server) ilinkAuthService() (*ilinkauth.Service, error) {
+	if s.ilinkAuth != nil {
+		return s.ilinkAuth, nil
 	}
 
-	var body struct {
-		Storage       *config.StorageConfig       `json:"storage"`
-		Agents        *config.AgentsConfig        `json:"agents"`
-		Gateway       *config.GatewayConfig       `json:"gateway"`
-		Tools         *config.ToolsConfig         `json:"tools"`
-		Heartbeat     *config.HeartbeatConfig     `json:"heartbeat"`
-		Webhook       *config.WebhookConfig       `json:"webhook"`
-		Redis         *config.RedisConfig         `json:"redis"`
-		State         *config.StateConfig         `json:"state"`
-		Bus           *config.BusConfig           `json:"bus"`
-		Approval      *config.ApprovalConfig      `json:"approval"`
-		Logger        *config.LoggerConfig        `json:"logger"`
-		Memory        *config.MemoryConfig        `json:"memory"`
-		Sessions      *config.SessionsConfig      `json:"sessions"`
-		WebUI         *config.WebUIConfig         `json:"webui"`
-		Transcription *config.TranscriptionConfig `json:"transcription"`
-		Audit         *config.AuditConfig         `json:"audit"`
-		Undo          *config.UndoConfig          `json:"undo"`
-		Preprocess    *config.PreprocessConfig    `json:"preprocess"`
-		Learnings     *config.LearningsConfig     `json:"learnings"`
-		Watch         *config.WatchConfig         `json:"watch"`
-		Providers     []config.ProviderProfile    `json:"providers"`
+	store, err := ilinkauth.NewStore(s.config)
+	if err != nil {
+		return nil, fmt.Errorf("create ilink auth store: %w", err)
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON payload"})
+	s.ilinkAuth = ilinkauth.NewService(store, webWechatLoginClient{})
+	return s.ilinkAuth, nil
+}
+
+func (s *Server) currentAuthUser(c *echo.Context) (*config.AuthProfile, error) {
+	profile, err := s.authProfileFromContext(c)
+	if err == nil {
+		return profile, nil
 	}
 
-	// Apply config sections
-	if body.Storage != nil {
-		s.config.Storage = *body.Storage
+	userID := strings.TrimSpace(s.currentUserID(c))
+	username := strings.TrimSpace(s.currentUsername(c))
+	if userID == "" && username == "" {
+		return nil, err
 	}
-	if body.Agents != nil {
-		s.config.Agents = *body.Agents
+
+	return &config.AuthProfile{
+		UserID:   userID,
+		Username: username,
+		Role:     firstNonEmptyString(s.currentUserRole(c), "member"),
+		TenantID: s.currentTenantID(c),
+	}, nil
+}
+
+func (s *Server) handleGetWechatBindingStatus(c *echo.Context) error {
+	authSvc, err := s.ilinkAuthService()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Gateway != nil {
-		s.config.Gateway = *body.Gateway
+	profile, err := s.currentAuthUser(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
 	}
-	if body.Tools != nil {
-		s.config.Tools = *body.Tools
+
+	payload, err := s.buildWechatBindingPayload(authSvc, profile.UserID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Heartbeat != nil {
-		s.config.Heartbeat = *body.Heartbeat
+	return c.JSON(http.StatusOK, payload)
+}
+
+func (s *Server) handleStartWechatBinding(c *echo.Context) error {
+	authSvc, err := s.ilinkAuthService()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Webhook != nil {
-		s.config.Webhook = *body.Webhook
+	profile, err := s.currentAuthUser(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
 	}
-	if body.Redis != nil {
-		s.config.Redis = *body.Redis
+
+	if _, err := authSvc.StartBinding(c.Request().Context(), profile.UserID); err != nil {
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
 	}
-	if body.State != nil {
-		s.config.State = *body.State
+
+	payload, err := s.buildWechatBindingPayload(authSvc, profile.UserID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Bus != nil {
-		s.config.Bus = *body.Bus
+	return c.JSON(http.StatusOK, payload)
+}
+
+func (s *Server) handlePollWechatBinding(c *echo.Context) error {
+	authSvc, err := s.ilinkAuthService()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Approval != nil {
-		s.config.Approval = *body.Approval
+	profile, err := s.currentAuthUser(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
 	}
-	if body.Logger != nil {
-		s.config.Logger = *body.Logger
+
+	if _, err := authSvc.PollBinding(c.Request().Context(), profile.UserID); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no active ilink binding") {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "no active wechat binding"})
+		}
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
 	}
-	if body.Memory != nil {
-		s.config.Memory = *body.Memory
+
+	if err := s.syncWechatBindingToAccounts(c.Request().Context(), authSvc, profile); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Sessions != nil {
-		s.config.Sessions = *body.Sessions
+
+	if err := s.reloadWechatChannelsIfAvailable(); err != nil {
+		s.logger.Error("Failed to reload WeChat after binding", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.WebUI != nil {
-		s.config.WebUI = *body.WebUI
-		if s.toolSess != nil {
-			s.toolSess.SetEventConfig(s.config.WebUI.ToolSessionEvents)
-		}
-		if s.skillsMgr != nil {
-			s.skillsMgr.SetSnapshotRetention(skills.SnapshotRetentionConfig{
-				AutoPrune: s.config.WebUI.SkillSnapshots.AutoPrune,
-				MaxCount:  s.config.WebUI.SkillSnapshots.MaxCount,
-			})
-			s.skillsMgr.SetVersionRetention(skills.VersionRetentionConfig{
-				Enabled:  s.config.WebUI.SkillVersions.Enabled,
-				MaxCount: s.config.WebUI.SkillVersions.MaxCount,
-			})
-		}
+
+	payload, err := s.buildWechatBindingPayload(authSvc, profile.UserID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Transcription != nil {
-		s.config.Transcription = *body.Transcription
+	return c.JSON(http.StatusOK, payload)
+}
+
+func (s *Server) handleDeleteWechatBinding(c *echo.Context) error {
+	authSvc, err := s.ilinkAuthService()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Audit != nil {
-		s.config.Audit = *body.Audit
+	profile, err := s.currentAuthUser(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
 	}
-	if body.Undo != nil {
-		s.config.Undo = *body.Undo
+
+	if _, err := authSvc.GetBinding(profile.UserID); err != nil {
+		if errors.Is(err, ilinkauth.ErrBindingNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "no active wechat binding"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Preprocess != nil {
-		s.config.Preprocess = *body.Preprocess
+
+	if err := authSvc.DeleteBinding(profile.UserID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Learnings != nil {
-		s.config.Learnings = *body.Learnings
+
+	if err := s.deleteWechatChannelAccount(c.Request().Context(), profile.UserID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Watch != nil {
-		s.config.Watch = *body.Watch
+
+	if err := s.reloadWechatChannelsIfAvailable(); err != nil {
+		s.logger.Error("Failed to reload WeChat after unbind", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	// Validate
-	if err := config.ValidateConfig(s.config); err != nil {
-		s.config.Storage = previousStorage
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func (s *Server) handleActivateWechatBinding(c *echo.Context) error {
+	if s.accountMgr == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "channel account manager is unavailable"})
 	}
 
-	restartSections := make([]string, 0, 5)
-	if body.Storage != nil || body.Logger != nil || body.Gateway != nil || body.WebUI != nil || body.Webhook != nil {
-		if body.Storage != nil && oldRuntimeDBIsSQLite && s.config.DatabaseType() == "sqlite" {
-			newRuntimeDBPath, err := config.RuntimeDBPath(s.config)
-			if err != nil {
-				s.config.Storage = previousStorage
-				return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-			}
-			if err := config.MigrateRuntimeDB(oldRuntimeDBPath, newRuntimeDBPath); err != nil {
-				s.config.Storage = previousStorage
-				return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-			}
-		}
-		if err := s.saveBootstrapConfig(); err != nil {
-			s.config.Storage = previousStorage
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-		}
-		if body.Storage != nil {
-			restartSections = append(restartSections, "storage")
-		}
-		if body.Logger != nil {
-			restartSections = append(restartSections, "logger")
-		}
-		if body.Gateway != nil {
-			restartSections = append(restartSections, "gateway")
-		}
-		if body.WebUI != nil {
-			restartSections = append(restartSections, "webui")
+	accountID := strings.TrimSpace(c.FormValue("account_id"))
+	if accountID == "" {
+		var body struct {
+			AccountID string `json:"account_id"`
 		}
-		if body.Webhook != nil {
-			restartSections = append(restartSections, "webhook")
+		if err := c.Bind(&body); err == nil {
+			accountID = strings.TrimSpace(body.AccountID)
 		}
 	}
-
-	// Persist runtime sections to database
-	sections := make([]string, 0, 19)
-	if body.Agents != nil {
-		sections = append(sections, "agents")
+	if accountID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "account_id is required"})
 	}
-	if body.Gateway != nil {
-		sections = append(sections, "gateway")
+
+	profile, err := s.currentAuthUser(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
 	}
-	if body.Tools != nil {
-		sections = append(sections, "tools")
-	}
-	if body.Heartbeat != nil {
-		sections = append(sections, "heartbeat")
-	}
-	if body.Webhook != nil {
-		sections = append(sections, "webhook")
-	}
-	if body.Redis != nil {
-		sections = append(sections, "redis")
-	}
-	if body.State != nil {
-		sections = append(sections, "state")
-	}
-	if body.Bus != nil {
-		sections = append(sections, "bus")
+
+	if err := s.activateWechatChannelAccount(c.Request().Context(), profile.UserID, accountID); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	if body.Approval != nil {
-		sections = append(sections, "approval")
+	if err := s.reloadWechatChannelsIfAvailable(); err != nil {
+		s.logger.Error("Failed to reload WeChat after activation", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Logger != nil {
-		sections = append(sections, "logger")
+
+	authSvc, err := s.ilinkAuthService()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Memory != nil {
-		sections = append(sections, "memory")
+	payload, err := s.buildWechatBindingPayload(authSvc, profile.UserID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Sessions != nil {
-		sections = append(sections, "sessions")
+	return c.JSON(http.StatusOK, payload)
+}
+
+func (s *Server) handleDeleteWechatBindingAccount(c *echo.Context) error {
+	if s.accountMgr == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "channel account manager is unavailable"})
 	}
-	if body.WebUI != nil {
-		sections = append(sections, "webui")
+
+	accountID := strings.TrimSpace(c.Param("accountId"))
+	if accountID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "accountId is required"})
 	}
-	if body.Transcription != nil {
-		sections = append(sections, "transcription")
+	profile, err := s.currentAuthUser(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
 	}
-	if body.Audit != nil {
-		sections = append(sections, "audit")
+	if err := s.removeWechatChannelAccount(c.Request().Context(), profile.UserID, accountID); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	if body.Undo != nil {
-		sections = append(sections, "undo")
+	if err := s.reloadWechatChannelsIfAvailable(); err != nil {
+		s.logger.Error("Failed to reload WeChat after account delete", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Preprocess != nil {
-		sections = append(sections, "preprocess")
+
+	authSvc, err := s.ilinkAuthService()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Learnings != nil {
-		sections = append(sections, "learnings")
+	payload, err := s.buildWechatBindingPayload(authSvc, profile.UserID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if body.Watch != nil {
-		sections = append(sections, "watch")
+	return c.JSON(http.StatusOK, payload)
+}
+
+func (s *Server) buildWechatBindingPayload(authSvc *ilinkauth.Service, userID string) (map[string]interface{}, error) {
+	payload := map[string]interface{}{
+		"bound": false,
 	}
-	if len(sections) > 0 {
-		if err := config.SaveDatabaseSections(s.config, sections...); err != nil {
-			s.logger.Error("Failed to persist imported config sections", zap.Error(err), zap.Strings("sections", sections))
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save config sections"})
-		}
+
+	binding, err := authSvc.GetBinding(userID)
+	if err != nil && !errors.Is(err, ilinkauth.ErrBindingNotFound) {
+		return nil, fmt.Errorf("load ilink binding: %w", err)
 	}
-	if body.Watch != nil {
-		if err := s.syncWatchRuntime(); err != nil {
-			if s.logger != nil {
-				s.logger.Error("Failed to sync watch runtime after config import", zap.Error(err))
-			}
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to sync watch runtime"})
+	activeAccountBotID := ""
+	if binding != nil {
+		payload["bound"] = true
+		activeAccountBotID = strings.TrimSpace(binding.Credentials.ILinkBotID)
+		payload["account"] = map[string]interface{}{
+			"bot_id":  binding.Credentials.ILinkBotID,
+			"user_id": binding.Credentials.ILinkUserID,
 		}
 	}
 
-	// Import providers
-	importedProviders := 0
-	if len(body.Providers) > 0 {
-		ctx := c.Request().Context()
-		for _, profile := range body.Providers {
-			if profile.Name == "" {
+	if s.accountMgr != nil {
+		accounts, err := s.accountMgr.List(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("list wechat channel accounts: %w", err)
+		}
+		items := make([]map[string]interface{}, 0, len(accounts))
+		for _, account := range accounts {
+			if account.ChannelType != "wechat" {
 				continue
 			}
-			// Try update first, then create
-			_, err := s.providers.Update(ctx, profile.Name, profile)
-			if err != nil {
-				_, createErr := s.providers.Create(ctx, profile)
-				if createErr != nil {
-					s.logger.Warn("Failed to import provider", zap.String("name", profile.Name), zap.Error(createErr))
-					continue
-				}
+			if !wechatAccountBelongsToUser(account, userID) {
+				continue
+			}
+			botID, _ := account.Config["ilink_bot_id"].(string)
+			ilinkUserID, _ := account.Config["ilink_user_id"].(string)
+			items = append(items, map[string]interface{}{
+				"account_id": account.ID,
+				"bot_id":     botID,
+				"user_id":    ilinkUserID,
+				"active":     activeAccountBotID != "" && strings.TrimSpace(botID) == activeAccountBotID,
+			})
+			if activeAccountBotID != "" && strings.TrimSpace(botID) == activeAccountBotID {
+				payload["active_account_id"] = account.ID
 			}
-			importedProviders++
 		}
+		payload["accounts"] = items
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"status":             "imported",
-		"sections_saved":     len(sections),
-		"providers_imported": importedProviders,
-		"restart_required":   len(restartSections) > 0,
-		"restart_sections":   restartSections,
-	})
-}
-
-func (s *Server) handleTestWebhook(c *echo.Context) error {
-	if !s.config.Webhook.Enabled {
-		return c.JSON(http.StatusConflict, map[string]string{"error": "webhook trigger is disabled"})
+	state, err := authSvc.LoadBindSession(userID)
+	if err != nil {
+		return nil, fmt.Errorf("load ilink bind session: %w", err)
 	}
-	if s.agent == nil && s.webhookTestHandler == nil || s.sessionMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "agent runtime not available"})
+	if state != nil {
+		bind := map[string]interface{}{
+			"status":         state.Status,
+			"qrcode_content": state.QRCodeContent,
+			"updated_at":     state.UpdatedAt,
+			"bot_id":         state.BotID,
+			"user_id":        state.ILinkUserID,
+			"error":          state.Error,
+		}
+		if strings.TrimSpace(state.QRCodeContent) != "" {
+			if dataURL, err := encodeQRCodeDataURL(state.QRCodeContent); err == nil {
+				bind["qr_png_data_url"] = dataURL
+			}
+		}
+		payload["binding"] = bind
 	}
 
-	var body struct {
-		Message string `json:"message"`
-	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
-	}
-	messageText := strings.TrimSpace(body.Message)
-	if messageText == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message is required"})
-	}
+	return payload, nil
+}
 
-	username := strings.TrimSpace(s.currentUsername(c))
-	if username == "" {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+func (s *Server) syncWechatBindingToAccounts(ctx context.Context, authSvc *ilinkauth.Service, profile *config.AuthProfile) error {
+	if s == nil || s.accountMgr == nil {
+		return nil
 	}
-	sess, err := s.sessionMgr.GetWithSource("webhook:"+username, session.SourceWebUI)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if profile == nil {
+		return fmt.Errorf("auth profile is required")
 	}
-	var reply string
-	if s.webhookTestHandler != nil {
-		reply, err = s.webhookTestHandler(c.Request().Context(), username, messageText)
-	} else {
-		reply, err = s.agent.Chat(c.Request().Context(), sess, messageText)
+	userID := strings.TrimSpace(profile.UserID)
+	if userID == "" {
+		return fmt.Errorf("user id is required")
 	}
+
+	binding, err := authSvc.GetBinding(userID)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "no providers configured") {
-			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		if errors.Is(err, ilinkauth.ErrBindingNotFound) {
+			return nil
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return fmt.Errorf("get ilink binding: %w", err)
 	}
-	return c.JSON(http.StatusOK, map[string]any{
-		"status":     "ok",
-		"reply":      reply,
-		"session_id": sess.ID,
-	})
-}
-
-// ---------------------------------------------------------------------------
-// Notification route handlers
-// ---------------------------------------------------------------------------
-
-func (s *Server) handleListNotificationRoutes(c *echo.Context) error {
-	if s.notificationMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification routes not available"})
+	if binding == nil {
+		return nil
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	routes, err := s.notificationMgr.ListRoutes(ctx)
+
+	store, err := channelwechat.NewCredentialStore(s.config)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return fmt.Errorf("create wechat credential store: %w", err)
 	}
-	if routes == nil {
-		routes = []notificationroutes.NotificationRoute{}
+	if err := store.SaveCredentials((*channelwechat.Credentials)(&binding.Credentials), true); err != nil {
+		return fmt.Errorf("save wechat credentials: %w", err)
 	}
-	return c.JSON(http.StatusOK, routes)
-}
 
-func (s *Server) handleCreateNotificationRoute(c *echo.Context) error {
-	if s.notificationMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification routes not available"})
-	}
-	var item notificationroutes.NotificationRoute
-	if err := c.Bind(&item); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	accountKey := strings.TrimSpace(binding.Credentials.ILinkBotID)
+	if accountKey == "" {
+		return fmt.Errorf("ilink bot id is empty")
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	route, err := s.notificationMgr.CreateRoute(ctx, item)
+
+	accounts, err := s.accountMgr.List(ctx)
 	if err != nil {
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return fmt.Errorf("list channel accounts: %w", err)
 	}
-	return c.JSON(http.StatusCreated, route)
-}
 
-func (s *Server) handleUpdateNotificationRoute(c *echo.Context) error {
-	if s.notificationMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification routes not available"})
-	}
-	id := c.Param("id")
-	var item notificationroutes.NotificationRoute
-	if err := c.Bind(&item); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	payload := channelaccounts.ChannelAccount{
+		ChannelType: "wechat",
+		AccountKey:  accountKey,
+		DisplayName: accountKey,
+		Enabled:     true,
+		TenantID:    strings.TrimSpace(profile.TenantID),
+		OwnerUserID: userID,
+		Visibility:  ownership.VisibilityPrivate,
+		Config: map[string]interface{}{
+			"enabled":       true,
+			"ilink_bot_id":  binding.Credentials.ILinkBotID,
+			"ilink_user_id": binding.Credentials.ILinkUserID,
+			"base_url":      binding.Credentials.BaseURL,
+			"bot_token":     binding.Credentials.BotToken,
+		},
+		Metadata: map[string]interface{}{
+			"owner_user_id": strings.TrimSpace(userID),
+			"source":        "ilinkauth",
+		},
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	route, err := s.notificationMgr.UpdateRoute(ctx, id, item)
-	if err != nil {
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+
+	for _, item := range accounts {
+		if item.ChannelType != "wechat" || item.AccountKey != accountKey {
+			continue
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		_, err := s.accountMgr.Update(ctx, item.ID, payload)
+		if err != nil {
+			return fmt.Errorf("update wechat channel account %s: %w", item.ID, err)
+		}
+		return nil
 	}
-	return c.JSON(http.StatusOK, route)
-}
 
-func (s *Server) handleDeleteNotificationRoute(c *echo.Context) error {
-	if s.notificationMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification routes not available"})
-	}
-	id := c.Param("id")
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	if err := s.notificationMgr.DeleteRoute(ctx, id); err != nil {
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if _, err := s.accountMgr.Create(ctx, payload); err != nil {
+		return fmt.Errorf("create wechat channel account: %w", err)
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+	return nil
 }
 
-// ---------------------------------------------------------------------------
-// Notification binding handlers
-// ---------------------------------------------------------------------------
-
-func (s *Server) handleListNotificationBindings(c *echo.Context) error {
-	if s.notificationMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
+func (s *Server) reloadWechatChannelsIfAvailable() error {
+	if s == nil || s.channels == nil {
+		return nil
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	bindings, err := s.notificationMgr.ListBindings(ctx)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	return s.reloadChannelsByType("wechat")
+}
+
+func wechatAccountBelongsToUser(account channelaccounts.ChannelAccount, userID string) bool {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return false
 	}
-	if bindings == nil {
-		bindings = []notificationroutes.NotificationBinding{}
+	if strings.TrimSpace(account.OwnerUserID) == userID {
+		return true
 	}
-	return c.JSON(http.StatusOK, bindings)
+	owner, _ := account.Metadata["owner_user_id"].(string)
+	return strings.TrimSpace(owner) == userID
 }
 
-func (s *Server) handleCreateNotificationBinding(c *echo.Context) error {
-	if s.notificationMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
-	}
-	var item notificationroutes.NotificationBinding
-	if err := c.Bind(&item); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+func (s *Server) deleteWechatChannelAccount(ctx context.Context, userID string) error {
+	if s == nil || s.accountMgr == nil {
+		return nil
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	binding, err := s.notificationMgr.CreateBinding(ctx, item)
+	accounts, err := s.accountMgr.List(ctx)
 	if err != nil {
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		return fmt.Errorf("list wechat channel accounts: %w", err)
+	}
+	for _, item := range accounts {
+		if item.ChannelType != "wechat" {
+			continue
+		}
+		if !wechatAccountBelongsToUser(item, userID) {
+			continue
+		}
+		if s.bindingMgr != nil {
+			if err := s.bindingMgr.DeleteByChannelAccountID(ctx, item.ID); err != nil {
+				return fmt.Errorf("delete wechat bindings for channel account %s: %w", item.ID, err)
+			}
+		}
+		if err := s.accountMgr.Delete(ctx, item.ID); err != nil {
+			return fmt.Errorf("delete wechat channel account %s: %w", item.ID, err)
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusCreated, binding)
+	return nil
 }
 
-func (s *Server) handleUpdateNotificationBinding(c *echo.Context) error {
-	if s.notificationMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
-	}
-	id := c.Param("id")
-	var item notificationroutes.NotificationBinding
-	if err := c.Bind(&item); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+func (s *Server) activateWechatChannelAccount(ctx context.Context, userID, accountID string) error {
+	authSvc, err := s.ilinkAuthService()
+	if err != nil {
+		return err
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	binding, err := s.notificationMgr.UpdateBinding(ctx, id, item)
+	account, err := s.accountMgr.Get(ctx, accountID)
 	if err != nil {
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
-	return c.JSON(http.StatusOK, binding)
-}
-
-func (s *Server) handleDeleteNotificationBinding(c *echo.Context) error {
-	if s.notificationMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
+	if account == nil || account.ChannelType != "wechat" {
+		return fmt.Errorf("wechat account not found")
 	}
-	id := c.Param("id")
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	if err := s.notificationMgr.DeleteBinding(ctx, id); err != nil {
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if !wechatAccountBelongsToUser(*account, userID) {
+		return fmt.Errorf("wechat account does not belong to current user")
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
-}
 
-func (s *Server) handleListNotificationBindingsByRoute(c *echo.Context) error {
-	if s.notificationMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
+	botID, _ := account.Config["ilink_bot_id"].(string)
+	botToken, _ := account.Config["bot_token"].(string)
+	ilinkUserID, _ := account.Config["ilink_user_id"].(string)
+	baseURL, _ := account.Config["base_url"].(string)
+	if strings.TrimSpace(botID) == "" || strings.TrimSpace(botToken) == "" {
+		return fmt.Errorf("wechat account credentials are incomplete")
 	}
-	routeID := c.Param("routeId")
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	bindings, err := s.notificationMgr.ListBindingsByRoute(ctx, routeID)
+
+	store, err := channelwechat.NewCredentialStore(s.config)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return fmt.Errorf("create wechat credential store: %w", err)
 	}
-	if bindings == nil {
-		bindings = []notificationroutes.NotificationBinding{}
+	if err := store.SetActiveAccount(botID); err != nil {
+		return fmt.Errorf("set active wechat account: %w", err)
 	}
-	return c.JSON(http.StatusOK, bindings)
-}
 
-type notificationBindingTargetRequest struct {
-	Scope      string   `json:"scope"`
-	Target     string   `json:"target"`
-	RouteID    string   `json:"route_id"`
-	Enabled    *bool    `json:"enabled,omitempty"`
-	EventTypes []string `json:"event_types,omitempty"`
+	if err := authSvc.DeleteBinding(userID); err != nil {
+		return err
+	}
+
+	return authSvc.SaveBinding(&ilinkauth.Binding{
+		UserID: strings.TrimSpace(userID),
+		Credentials: wxtypes.Credentials{
+			BotToken:    botToken,
+			ILinkBotID:  botID,
+			BaseURL:     baseURL,
+			ILinkUserID: ilinkUserID,
+		},
+	})
 }
 
-func (s *Server) handleGetNotificationBindingByTarget(c *echo.Context) error {
-	if s.notificationMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
-	}
-	scope := strings.TrimSpace(c.QueryParam("scope"))
-	target := strings.TrimSpace(c.QueryParam("target"))
-	if scope == "" || target == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "scope and target are required"})
+func (s *Server) removeWechatChannelAccount(ctx context.Context, userID, accountID string) error {
+	authSvc, err := s.ilinkAuthService()
+	if err != nil {
+		return err
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	binding, err := s.notificationMgr.FindBindingForTarget(ctx, scope, target)
+	account, err := s.accountMgr.Get(ctx, accountID)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
-	if binding == nil {
-		return c.JSON(http.StatusOK, map[string]interface{}{"binding": nil})
+	if account == nil || account.ChannelType != "wechat" {
+		return fmt.Errorf("wechat account not found")
 	}
-	return c.JSON(http.StatusOK, map[string]interface{}{"binding": binding})
-}
-
-func (s *Server) handleSetNotificationBindingByTarget(c *echo.Context) error {
-	if s.notificationMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
+	if !wechatAccountBelongsToUser(*account, userID) {
+		return fmt.Errorf("wechat account does not belong to current user")
 	}
-	var body notificationBindingTargetRequest
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	if s.bindingMgr != nil {
+		if err := s.bindingMgr.DeleteByChannelAccountID(ctx, account.ID); err != nil {
+			return err
+		}
 	}
-	scope := strings.TrimSpace(body.Scope)
-	target := strings.TrimSpace(body.Target)
-	if scope == "" || target == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "scope and target are required"})
+	if err := s.accountMgr.Delete(ctx, account.ID); err != nil {
+		return err
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	binding, err := s.bindDefaultNotificationRoute(ctx, scope, target, strings.TrimSpace(body.RouteID), body.EventTypes, body.Enabled)
+
+	store, err := channelwechat.NewCredentialStore(s.config)
 	if err != nil {
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
-		}
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return fmt.Errorf("create wechat credential store: %w", err)
+	}
+	if err := store.DeleteCredentials(account.AccountKey); err != nil {
+		return fmt.Errorf("delete wechat local credentials: %w", err)
 	}
-	return c.JSON(http.StatusOK, map[string]interface{}{"binding": binding})
-}
 
-func (s *Server) bindDefaultNotificationRoute(
-	ctx context.Context,
-	scope string,
-	target string,
-	routeID string,
-	eventTypes []string,
-	enabled *bool,
-) (*notificationroutes.NotificationBinding, error) {
-	if s == nil || s.notificationMgr == nil {
-		return nil, fmt.Errorf("notification routes not available")
-	}
-	scope = strings.TrimSpace(scope)
-	target = strings.TrimSpace(target)
-	routeID = strings.TrimSpace(routeID)
-	if scope == "" || target == "" {
-		return nil, fmt.Errorf("scope and target are required")
-	}
-	normalizedEvents := normalizeNotificationEventTypes(eventTypes)
-	if len(normalizedEvents) == 0 {
-		normalizedEvents = []string{notificationroutes.EventWebMessage}
-	}
-	rawEvents, err := json.Marshal(normalizedEvents)
-	if err != nil {
-		return nil, err
-	}
-	isEnabled := true
-	if enabled != nil {
-		isEnabled = *enabled
+	active, err := authSvc.GetBinding(userID)
+	if err != nil && !errors.Is(err, ilinkauth.ErrBindingNotFound) {
+		return err
 	}
-	var route *notificationroutes.NotificationRoute
-	if routeID != "" {
-		var err error
-		route, err = s.notificationMgr.GetRoute(ctx, routeID)
-		if err != nil {
-			return nil, err
+	if active != nil && strings.TrimSpace(active.Credentials.ILinkBotID) == strings.TrimSpace(account.AccountKey) {
+		if err := authSvc.DeleteBinding(userID); err != nil {
+			return err
 		}
-	}
-	if err := s.notificationMgr.DeleteBindingsForTarget(ctx, scope, target); err != nil {
-		return nil, err
-	}
-	if route == nil {
-		return nil, nil
-	}
-	return s.notificationMgr.CreateBinding(ctx, notificationroutes.NotificationBinding{
-		Scope:          scope,
-		Target:         target,
-		RouteID:        routeID,
-		EventTypesJSON: string(rawEvents),
-		Enabled:        isEnabled,
-		TenantID:       route.TenantID,
-		OwnerUserID:    route.OwnerUserID,
-		Visibility:     route.Visibility,
-	})
-}
 
-func normalizeNotificationEventTypes(events []string) []string {
-	out := make([]string, 0, len(events))
-	seen := map[string]struct{}{}
-	for _, event := range events {
-		event = strings.TrimSpace(event)
-		if event == "" {
-			continue
+		remaining, listErr := s.accountMgr.List(ctx)
+		if listErr != nil {
+			return listErr
 		}
-		if _, ok := seen[event]; ok {
-			continue
+		for _, item := range remaining {
+			if item.ChannelType != "wechat" {
+				continue
+			}
+			if !wechatAccountBelongsToUser(item, userID) {
+				continue
+			}
+			return s.activateWechatChannelAccount(ctx, userID, item.ID)
 		}
-		seen[event] = struct{}{}
-		out = append(out, event)
 	}
-	return out
+	return nil
 }
 
-func (s *Server) saveBootstrapConfig() error {
-	if s == nil || s.config == nil {
-		return fmt.Errorf("config is unavailable")
-	}
-
-	configPath, err := s.resolveBootstrapConfigPath()
+func encodeQRCodeDataURL(content string) (string, error) {
+	code, err := rscqr.Encode(content, rscqr.M)
 	if err != nil {
-		return err
-	}
-
-	if err := config.SaveToFile(s.config, configPath); err != nil {
-		return fmt.Errorf("save bootstrap config: %w", err)
+		return "", fmt.Errorf("encode QR code: %w", err)
 	}
-	return nil
+	code.Scale = 8
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(code.PNG()), nil
 }
 
-func (s *Server) syncWatchRuntime() error {
-	if s == nil || s.config == nil || s.watcher == nil {
-		return nil
+// --- Config Handlers ---
+
+func (s *Server) handleGetConfig(c *echo.Context) error {
+	sections := map[string]interface{}{
+		"storage":          s.config.Storage,
+		"agents":           s.config.Agents,
+		"gateway":          s.config.Gateway,
+		"tools":            s.config.Tools,
+		"heartbeat":        s.config.Heartbeat,
+		"webhook":          s.config.Webhook,
+		"redis":            s.config.Redis,
+		"state":            s.config.State,
+		"bus":              s.config.Bus,
+		"approval":         s.config.Approval,
+		"logger":           s.config.Logger,
+		"memory":           s.config.Memory,
+		"sessions":         s.config.Sessions,
+		"webui":            s.config.WebUI,
+		"transcription":    s.config.Transcription,
+		"tts":              s.config.TTS,
+		"audit":            s.config.Audit,
+		"undo":             s.config.Undo,
+		"preprocess":       s.config.Preprocess,
+		"learnings":        s.config.Learnings,
+		"watch":            s.config.Watch,
+		"motd":             s.config.MOTD,
+		"alerts":           s.config.Alerts,
+		"moderation":       s.config.Moderation,
+		"usage":            s.config.Usage,
+		"response_filters": s.config.ResponseFilters,
+		"turn_limits":      s.config.TurnLimits,
+		"backup":           s.config.Backup,
+		"tool_sessions":    s.config.ToolSessions,
+	}
+	switch s.currentUserRole(c) {
+	case config.RoleAdmin, config.RoleOwner:
+		return c.JSON(http.StatusOK, sections)
+	}
+	// Only admins may change the config, so others never need its
+	// credentials.
+	raw, err := json.Marshal(sections)
+	if err == nil {
+		raw, err = secrets.RedactJSON(raw)
 	}
-	if err := s.watcher.ApplyConfig(s.config.Watch); err != nil {
-		return fmt.Errorf("sync watch runtime: %w", err)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to encode config"})
 	}
-	return nil
+	return c.JSONBlob(http.StatusOK, raw)
 }
 
-func cloneConfigSnapshot(cfg *config.Config) (*config.Config, error) {
-	if cfg == nil {
-		return nil, fmt.Errorf("config is unavailable")
+func (s *Server) handleSaveConfig(c *echo.Context) error {
+	before, _ := json.Marshal(s.configSections())
+	previousStorage := s.config.Storage
+	oldRuntimeDBPath := ""
+	oldRuntimeDBIsSQLite := s.config.DatabaseType() == "sqlite"
+	if oldRuntimeDBIsSQLite {
+		currentPath, err := config.RuntimeDBPath(s.config)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		oldRuntimeDBPath = currentPath
 	}
 
-	data, err := json.Marshal(cfg)
-	if err != nil {
-		return nil, fmt.Errorf("marshal config snapshot: %w", err)
+	var body struct {
+		Storage         *config.StorageConfig         `json:"storage"`
+		Agents          *config.AgentsConfig          `json:"agents"`
+		Gateway         *config.GatewayConfig         `json:"gateway"`
+		Tools           *config.ToolsConfig           `json:"tools"`
+		Heartbeat       *config.HeartbeatConfig       `json:"heartbeat"`
+		Webhook         *config.WebhookConfig         `json:"webhook"`
+		Redis           *config.RedisConfig           `json:"redis"`
+		State           *config.StateConfig           `json:"state"`
+		Bus             *config.BusConfig             `json:"bus"`
+		Approval        *config.ApprovalConfig        `json:"approval"`
+		Logger          *config.LoggerConfig          `json:"logger"`
+		Memory          *config.MemoryConfig          `json:"memory"`
+		Sessions        *config.SessionsConfig        `json:"sessions"`
+		WebUI           *config.WebUIConfig           `json:"webui"`
+		Transcription   *config.TranscriptionConfig   `json:"transcription"`
+		TTS             *config.TTSConfig             `json:"tts"`
+		Audit           *config.AuditConfig           `json:"audit"`
+		Undo            *config.UndoConfig            `json:"undo"`
+		Preprocess      *config.PreprocessConfig      `json:"preprocess"`
+		Learnings       *config.LearningsConfig       `json:"learnings"`
+		Watch           *config.WatchConfig           `json:"watch"`
+		MOTD            *config.MOTDConfig            `json:"motd"`
+		Alerts          *config.AlertsConfig          `json:"alerts"`
+		Moderation      *config.ModerationConfig      `json:"moderation"`
+		Usage           *config.UsageConfig           `json:"usage"`
+		ResponseFilters *config.ResponseFiltersConfig `json:"response_filters"`
+		TurnLimits      *config.TurnLimitsConfig      `json:"turn_limits"`
+		Backup          *config.BackupConfig          `json:"backup"`
+		ToolSessions    *config.ToolSessionsConfig    `json:"tool_sessions"`
 	}
-
-	var cloned config.Config
-	if err := json.Unmarshal(data, &cloned); err != nil {
-		return nil, fmt.Errorf("unmarshal config snapshot: %w", err)
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
 
-	return &cloned, nil
-}
-
-func (s *Server) clearChatSession(sessionID string) error {
-	if s == nil || s.sessionMgr == nil {
-		return fmt.Errorf("session manager not available")
+	// Apply partial updates
+	if body.Storage != nil {
+		s.config.Storage = *body.Storage
 	}
-
-	if err := s.sessionMgr.Delete(sessionID); err != nil && !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf("delete session %q: %w", sessionID, err)
+	if body.Agents != nil {
+		s.config.Agents = *body.Agents
 	}
-	if s.snapshotMgr != nil {
-		if err := s.snapshotMgr.RemoveStore(sessionID); err != nil {
-			return fmt.Errorf("clear undo snapshots for %q: %w", sessionID, err)
-		}
+	if body.Gateway != nil {
+		s.config.Gateway = *body.Gateway
 	}
-	if _, err := s.getOrCreateChatSession(sessionID); err != nil {
-		return fmt.Errorf("recreate session %q: %w", sessionID, err)
+	if body.Tools != nil {
+		s.config.Tools = *body.Tools
 	}
-
-	return nil
-}
-
-func (s *Server) resolveBootstrapConfigPath() (string, error) {
-	configPath := ""
-	if s != nil && s.loader != nil {
-		configPath = strings.TrimSpace(s.loader.GetConfigPath())
+	if body.Heartbeat != nil {
+		s.config.Heartbeat = *body.Heartbeat
 	}
-	if configPath == "" {
-		configPath = strings.TrimSpace(os.Getenv(config.ConfigPathEnv))
+	if body.Webhook != nil {
+		s.config.Webhook = *body.Webhook
 	}
-	if configPath == "" {
-		home, err := config.GetConfigHome()
-		if err != nil {
-			return "", fmt.Errorf("resolve config home: %w", err)
+	if body.Redis != nil {
+		s.config.Redis = *body.Redis
+	}
+	if body.State != nil {
+		s.config.State = *body.State
+	}
+	if body.Bus != nil {
+		s.config.Bus = *body.Bus
+	}
+	if body.Approval != nil {
+		s.config.Approval = *body.Approval
+	}
+	if body.Logger != nil {
+		s.config.Logger = *body.Logger
+	}
+	if body.Memory != nil {
+		s.config.Memory = *body.Memory
+	}
+	if body.Sessions != nil {
+		s.config.Sessions = *body.Sessions
+	}
+	if body.WebUI != nil {
+		s.config.WebUI = *body.WebUI
+		if s.toolSess != nil {
+			s.toolSess.SetEventConfig(s.config.WebUI.ToolSessionEvents)
+			s.toolSess.SetResourceLimits(s.config.WebUI.ToolSessionLimits)
+		}
+		if s.skillsMgr != nil {
+			s.skillsMgr.SetSnapshotRetention(skills.SnapshotRetentionConfig{
+				AutoPrune: s.config.WebUI.SkillSnapshots.AutoPrune,
+				MaxCount:  s.config.WebUI.SkillSnapshots.MaxCount,
+			})
+			s.skillsMgr.SetVersionRetention(skills.VersionRetentionConfig{
+				Enabled:  s.config.WebUI.SkillVersions.Enabled,
+				MaxCount: s.config.WebUI.SkillVersions.MaxCount,
+			})
 		}
-		configPath = filepath.Join(home, "config.json")
 	}
-	return configPath, nil
-}
-
-// --- Session Handlers ---
+	if body.Transcription != nil {
+		s.config.Transcription = *body.Transcription
+	}
+	if body.TTS != nil {
+		s.config.TTS = *body.TTS
+	}
+	if body.Audit != nil {
+		s.config.Audit = *body.Audit
+	}
+	if body.Undo != nil {
+		s.config.Undo = *body.Undo
+	}
+	if body.Preprocess != nil {
+		s.config.Preprocess = *body.Preprocess
+	}
+	if body.Learnings != nil {
+		s.config.Learnings = *body.Learnings
+	}
+	if body.Watch != nil {
+		s.config.Watch = *body.Watch
+	}
+	if body.MOTD != nil {
+		s.config.MOTD = *body.MOTD
+	}
+	if body.Alerts != nil {
+		s.config.Alerts = *body.Alerts
+	}
+	if body.Moderation != nil {
+		s.config.Moderation = *body.Moderation
+	}
+	if body.Usage != nil {
+		s.config.Usage = *body.Usage
+	}
+	if body.ResponseFilters != nil {
+		s.config.ResponseFilters = *body.ResponseFilters
+	}
+	if body.TurnLimits != nil {
+		s.config.TurnLimits = *body.TurnLimits
+	}
+	if body.Backup != nil {
+		s.config.Backup = *body.Backup
+	}
+	if body.ToolSessions != nil {
+		s.config.ToolSessions = *body.ToolSessions
+	}
 
-type sessionSummaryResponse struct {
-	ID           string    `json:"id"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	Summary      string    `json:"summary"`
-	MessageCount int       `json:"message_count"`
-	RuntimeID    string    `json:"runtime_id"`
-	Topic        string    `json:"topic"`
-}
+	// Validate
+	if err := config.ValidateConfig(s.config); err != nil {
+		s.config.Storage = previousStorage
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
 
-type sessionMessageResponse struct {
-	ID          string                      `json:"id,omitempty"`
-	Role        string                      `json:"role"`
-	Content     string                      `json:"content"`
-	ToolCallID  string                      `json:"tool_call_id"`
-	Attachments []sessionAttachmentResponse `json:"attachments,omitempty"`
-}
+	restartSections := make([]string, 0, 5)
+	if body.Storage != nil || body.Logger != nil || body.Gateway != nil || body.WebUI != nil || body.Webhook != nil {
+		if body.Storage != nil && oldRuntimeDBIsSQLite && s.config.DatabaseType() == "sqlite" {
+			newRuntimeDBPath, err := config.RuntimeDBPath(s.config)
+			if err != nil {
+				s.config.Storage = previousStorage
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			}
+			if err := config.MigrateRuntimeDB(oldRuntimeDBPath, newRuntimeDBPath); err != nil {
+				s.config.Storage = previousStorage
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			}
+		}
+		if err := s.saveBootstrapConfig(); err != nil {
+			s.config.Storage = previousStorage
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		if body.Storage != nil {
+			restartSections = append(restartSections, "storage")
+		}
+		if body.Logger != nil {
+			restartSections = append(restartSections, "logger")
+		}
+		if body.Gateway != nil {
+			restartSections = append(restartSections, "gateway")
+		}
+		if body.WebUI != nil {
+			restartSections = append(restartSections, "webui")
+		}
+		if body.Webhook != nil {
+			restartSections = append(restartSections, "webhook")
+		}
+	}
 
-type sessionAttachmentResponse struct {
-	AttachmentID    string `json:"attachment_id"`
-	Target          string `json:"target"`
-	OwnerID         string `json:"owner_id,omitempty"`
-	Filename        string `json:"filename"`
-	MimeType        string `json:"mime_type,omitempty"`
-	SizeBytes       int64  `json:"size_bytes"`
-	StorageRef      string `json:"storage_ref,omitempty"`
-	CreatedTimeUnix int64  `json:"created_time_unix,omitempty"`
-}
+	sections := make([]string, 0, 19)
+	if body.Agents != nil {
+		sections = append(sections, "agents")
+	}
+	if body.Gateway != nil {
+		sections = append(sections, "gateway")
+	}
+	if body.Tools != nil {
+		sections = append(sections, "tools")
+	}
+	if body.Heartbeat != nil {
+		sections = append(sections, "heartbeat")
+	}
+	if body.Webhook != nil {
+		sections = append(sections, "webhook")
+	}
+	if body.Redis != nil {
+		sections = append(sections, "redis")
+	}
+	if body.State != nil {
+		sections = append(sections, "state")
+	}
+	if body.Bus != nil {
+		sections = append(sections, "bus")
+	}
+	if body.Approval != nil {
+		sections = append(sections, "approval")
+	}
+	if body.Logger != nil {
+		sections = append(sections, "logger")
+	}
+	if body.Memory != nil {
+		sections = append(sections, "memory")
+	}
+	if body.Sessions != nil {
+		sections = append(sections, "sessions")
+	}
+	if body.WebUI != nil {
+		sections = append(sections, "webui")
+	}
+	if body.Transcription != nil {
+		sections = append(sections, "transcription")
+	}
+	if body.TTS != nil {
+		sections = append(sections, "tts")
+	}
+	if body.Audit != nil {
+		sections = append(sections, "audit")
+	}
+	if body.Undo != nil {
+		sections = append(sections, "undo")
+	}
+	if body.Preprocess != nil {
+		sections = append(sections, "preprocess")
+	}
+	if body.Learnings != nil {
+		sections = append(sections, "learnings")
+	}
+	if body.Watch != nil {
+		sections = append(sections, "watch")
+	}
+	if body.MOTD != nil {
+		sections = append(sections, "motd")
+	}
+	if body.Alerts != nil {
+		sections = append(sections, "alerts")
+	}
+	if body.Moderation != nil {
+		sections = append(sections, "moderation")
+	}
+	if body.Usage != nil {
+		sections = append(sections, "usage")
+	}
+	if body.ResponseFilters != nil {
+		sections = append(sections, "response_filters")
+	}
+	if body.TurnLimits != nil {
+		sections = append(sections, "turn_limits")
+	}
+	if body.Backup != nil {
+		sections = append(sections, "backup")
+	}
+	if body.ToolSessions != nil {
+		sections = append(sections, "tool_sessions")
+	}
 
-type sessionDetailResponse struct {
-	ID           string                   `json:"id"`
-	CreatedAt    time.Time                `json:"created_at"`
-	UpdatedAt    time.Time                `json:"updated_at"`
-	Summary      string                   `json:"summary"`
-	MessageCount int                      `json:"message_count"`
-	RuntimeID    string                   `json:"runtime_id"`
-	Topic        string                   `json:"topic"`
-	Messages     []sessionMessageResponse `json:"messages"`
-}
+	// Persist runtime config sections to database.
+	if len(sections) > 0 {
+		if err := config.SaveDatabaseSections(s.config, sections...); err != nil {
+			s.logger.Error("Failed to persist config sections", zap.Error(err), zap.Strings("sections", sections))
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save config"})
+		}
+	}
+	if body.Agents != nil {
+		s.refreshMCPServers()
+	}
+	if body.Watch != nil {
+		if err := s.syncWatchRuntime(); err != nil {
+			if s.logger != nil {
+				s.logger.Error("Failed to sync watch runtime after config save", zap.Error(err))
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to sync watch runtime"})
+		}
+	}
 
-type threadSummaryResponse struct {
-	ID                  string    `json:"id"`
-	Target              string    `json:"target"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
-	Summary             string    `json:"summary"`
-	MessageCount        int       `json:"message_count"`
-	RuntimeID           string    `json:"runtime_id"`
-	Topic               string    `json:"topic"`
-	NotificationRouteID string    `json:"notification_route_id,omitempty"`
-}
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionConfigUpdate,
+		Success: true,
+		Summary: audit.Diff(json.RawMessage(before), s.configSections()),
+	})
 
-type threadDetailResponse struct {
-	ID                  string                   `json:"id"`
-	Target              string                   `json:"target"`
-	CreatedAt           time.Time                `json:"created_at"`
-	UpdatedAt           time.Time                `json:"updated_at"`
-	Summary             string                   `json:"summary"`
-	MessageCount        int                      `json:"message_count"`
-	RuntimeID           string                   `json:"runtime_id"`
-	Topic               string                   `json:"topic"`
-	NotificationRouteID string                   `json:"notification_route_id,omitempty"`
-	Messages            []sessionMessageResponse `json:"messages"`
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":           "saved",
+		"sections_saved":   len(sections),
+		"restart_required": len(restartSections) > 0,
+		"restart_sections": restartSections,
+	})
 }
 
-func buildSessionMessageResponses(messages []session.Message) []sessionMessageResponse {
-	respMessages := make([]sessionMessageResponse, len(messages))
-	for i, msg := range messages {
-		respMessages[i] = sessionMessageResponse{
-			ID:          msg.ID,
-			Role:        msg.Role,
-			Content:     msg.Content,
-			ToolCallID:  msg.ToolCallID,
-			Attachments: buildSessionAttachmentResponses(msg.Attachments),
-		}
+// configSections returns the exportable config sections by name.
+func (s *Server) configSections() map[string]interface{} {
+	return map[string]interface{}{
+		"storage":          s.config.Storage,
+		"agents":           s.config.Agents,
+		"gateway":          s.config.Gateway,
+		"tools":            s.config.Tools,
+		"heartbeat":        s.config.Heartbeat,
+		"redis":            s.config.Redis,
+		"state":            s.config.State,
+		"bus":              s.config.Bus,
+		"approval":         s.config.Approval,
+		"logger":           s.config.Logger,
+		"memory":           s.config.Memory,
+		"sessions":         s.config.Sessions,
+		"webui":            s.config.WebUI,
+		"transcription":    s.config.Transcription,
+		"tts":              s.config.TTS,
+		"audit":            s.config.Audit,
+		"undo":             s.config.Undo,
+		"preprocess":       s.config.Preprocess,
+		"learnings":        s.config.Learnings,
+		"watch":            s.config.Watch,
+		"motd":             s.config.MOTD,
+		"alerts":           s.config.Alerts,
+		"moderation":       s.config.Moderation,
+		"usage":            s.config.Usage,
+		"response_filters": s.config.ResponseFilters,
+		"turn_limits":      s.config.TurnLimits,
+		"backup":           s.config.Backup,
+		"tool_sessions":    s.config.ToolSessions,
 	}
-	return respMessages
 }
 
-func buildSessionAttachmentResponses(items []message.Attachment) []sessionAttachmentResponse {
-	if len(items) == 0 {
-		return nil
+func (s *Server) handleExportConfig(c *echo.Context) error {
+	// Collect providers from the store
+	providerProfiles, err := s.providers.List(c.Request().Context())
+	if err != nil {
+		s.logger.Error("Failed to export providers", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load providers"})
 	}
-	out := make([]sessionAttachmentResponse, 0, len(items))
-	for _, item := range items {
-		if strings.TrimSpace(item.AttachmentID) == "" {
-			continue
-		}
-		out = append(out, sessionAttachmentResponse{
-			AttachmentID:    item.AttachmentID,
-			Target:          item.Target,
-			OwnerID:         item.OwnerID,
-			Filename:        item.Filename,
-			MimeType:        item.MimeType,
-			SizeBytes:       item.SizeBytes,
-			StorageRef:      item.StorageRef,
-			CreatedTimeUnix: item.CreatedTimeUnix,
-		})
+	providerList := make([]map[string]interface{}, len(providerProfiles))
+	for i, p := range providerProfiles {
+		providerList[i] = providerProfileToMap(p)
 	}
-	return out
+
+	export := s.configSections()
+	export["providers"] = providerList
+
+	c.Response().Header().Set("Content-Disposition", `attachment; filename="nekobot-config-export.json"`)
+	return c.JSON(http.StatusOK, export)
 }
 
-func firstNonEmptyString(values ...string) string {
-	for _, value := range values {
-		if trimmed := strings.TrimSpace(value); trimmed != "" {
-			return trimmed
-		}
+// maxConfigDocumentBytes bounds a config document posted to
+// /api/config/apply.
+const maxConfigDocumentBytes = 4 << 20
+
+// handleGetConfigDocument exports the runtime config as the YAML or JSON
+// document read by "nekobot config apply" and POST /api/config/apply.
+func (s *Server) handleGetConfigDocument(c *echo.Context) error {
+	format := strings.ToLower(strings.TrimSpace(c.QueryParam("format")))
+	if format == "" {
+		format = config.DocumentFormatYAML
+	}
+	contentType := "application/yaml"
+	switch format {
+	case config.DocumentFormatYAML:
+	case config.DocumentFormatJSON:
+		contentType = echo.MIMEApplicationJSON
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "format must be yaml or json"})
 	}
-	return ""
-}
 
-func sessionTarget(sessionID string) string {
-	sessionID = strings.TrimSpace(sessionID)
-	if strings.HasPrefix(sessionID, "dm_@") {
-		return "dm:" + strings.TrimPrefix(sessionID, "dm_")
+	doc, err := s.providers.ExportDocument(c.Request().Context())
+	if err != nil {
+		s.logger.Error("Failed to export config document", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to export config"})
 	}
-	if sessionID == "" {
-		return ""
+	data, err := config.MarshalDocument(doc, format)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return "#websocket:" + sessionID
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="nekobot-config.%s"`, format))
+	return c.Blob(http.StatusOK, contentType, data)
 }
 
-func (s *Server) handleListSessions(c *echo.Context) error {
-	if s.sessionMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+// handleApplyConfigDocument merges a YAML or JSON config document into the
+// runtime config. JSON bodies parse as YAML too, so the body format needs no
+// declaring. Applied sections are announced on the bus so running
+// components pick them up.
+func (s *Server) handleApplyConfigDocument(c *echo.Context) error {
+	data, err := io.ReadAll(io.LimitReader(c.Request().Body, maxConfigDocumentBytes+1))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to read body"})
 	}
-
-	ids, err := s.sessionMgr.List()
+	if len(data) > maxConfigDocumentBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "config document too large"})
+	}
+	doc, err := config.ParseDocument(data, config.DocumentFormatYAML)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to list sessions: %v", err)})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	opts := providerstore.ApplyOptions{
+		Prune:  c.QueryParam("prune_providers") == "true",
+		DryRun: c.QueryParam("dry_run") == "true",
 	}
 
-	summaries := make([]sessionSummaryResponse, 0, len(ids))
-	for _, id := range ids {
-		sess, err := s.sessionMgr.GetExisting(id)
-		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				continue
-			}
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session %q: %v", id, err)})
+	before, _ := json.Marshal(s.configSections())
+	result, err := s.providers.ApplyDocument(c.Request().Context(), doc, opts)
+	if err != nil {
+		if opts.DryRun || len(result.Sections) == 0 {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": err.Error(), "result": result})
 		}
-		messages := sess.GetMessages()
-		summaries = append(summaries, sessionSummaryResponse{
-			ID:           sess.GetID(),
-			CreatedAt:    sess.GetCreatedAt(),
-			UpdatedAt:    sess.GetUpdatedAt(),
-			Summary:      sess.GetSummary(),
-			MessageCount: len(messages),
-			RuntimeID:    s.getThreadRuntimeBinding(id),
-			Topic:        s.getThreadTopic(id),
-		})
+		s.logger.Error("Failed to apply config document", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": err.Error(), "result": result})
 	}
-
-	sort.SliceStable(summaries, func(i, j int) bool {
-		return summaries[i].UpdatedAt.After(summaries[j].UpdatedAt)
-	})
-
-	return c.JSON(http.StatusOK, summaries)
-}
-
-func (s *Server) handleGetSession(c *echo.Context) error {
-	if s.sessionMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	if opts.DryRun || result.Empty() {
+		return c.JSON(http.StatusOK, map[string]interface{}{"dry_run": opts.DryRun, "result": result})
 	}
 
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	if slices.Contains(result.Sections, "agents") {
+		s.refreshMCPServers()
 	}
-
-	sess, err := s.sessionMgr.GetExisting(id)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+	if slices.Contains(result.Sections, "watch") {
+		if err := s.syncWatchRuntime(); err != nil {
+			s.logger.Error("Failed to sync watch runtime after config apply", zap.Error(err))
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
 	}
-
-	messages := sess.GetMessages()
-
-	resp := sessionDetailResponse{
-		ID:           sess.GetID(),
-		CreatedAt:    sess.GetCreatedAt(),
-		UpdatedAt:    sess.GetUpdatedAt(),
-		Summary:      sess.GetSummary(),
-		MessageCount: len(messages),
-		RuntimeID:    s.getThreadRuntimeBinding(id),
-		Topic:        s.getThreadTopic(id),
-		Messages:     buildSessionMessageResponses(messages),
+	changed := slices.Clone(result.Sections)
+	if !result.Providers.Empty() {
+		changed = append(changed, "providers")
+	}
+	if err := bus.PublishConfigChanged(s.bus, changed); err != nil {
+		s.logger.Warn("Failed to publish config change", zap.Error(err))
 	}
-	return c.JSON(http.StatusOK, resp)
-}
 
-func (s *Server) handleUpdateSessionSummary(c *echo.Context) error {
-	if s.sessionMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	restartSections := make([]string, 0)
+	for _, section := range result.Sections {
+		if slices.Contains([]string{"logger", "gateway", "webui", "webhook"}, section) {
+			restartSections = append(restartSections, section)
+		}
+	}
+	summary := audit.Diff(json.RawMessage(before), s.configSections())
+	if !result.Providers.Empty() {
+		summary = strings.TrimPrefix(fmt.Sprintf("%s; providers: %d created, %d updated, %d deleted", summary,
+			len(result.Providers.Created), len(result.Providers.Updated), len(result.Providers.Deleted)), "; ")
 	}
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionConfigImport,
+		Success: true,
+		Summary: summary,
+	})
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"dry_run":          false,
+		"result":           result,
+		"restart_required": len(restartSections) > 0,
+		"restart_sections": restartSections,
+	})
+}
 
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+func (s *Server) handleImportConfig(c *echo.Context) error {
+	before, _ := json.Marshal(s.configSections())
+	previousStorage := s.config.Storage
+	oldRuntimeDBPath := ""
+	oldRuntimeDBIsSQLite := s.config.DatabaseType() == "sqlite"
+	if oldRuntimeDBIsSQLite {
+		currentPath, err := config.RuntimeDBPath(s.config)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		oldRuntimeDBPath = currentPath
 	}
 
 	var body struct {
-		Summary string `json:"summary"`
+		Storage         *config.StorageConfig         `json:"storage"`
+		Agents          *config.AgentsConfig          `json:"agents"`
+		Gateway         *config.GatewayConfig         `json:"gateway"`
+		Tools           *config.ToolsConfig           `json:"tools"`
+		Heartbeat       *config.HeartbeatConfig       `json:"heartbeat"`
+		Webhook         *config.WebhookConfig         `json:"webhook"`
+		Redis           *config.RedisConfig           `json:"redis"`
+		State           *config.StateConfig           `json:"state"`
+		Bus             *config.BusConfig             `json:"bus"`
+		Approval        *config.ApprovalConfig        `json:"approval"`
+		Logger          *config.LoggerConfig          `json:"logger"`
+		Memory          *config.MemoryConfig          `json:"memory"`
+		Sessions        *config.SessionsConfig        `json:"sessions"`
+		WebUI           *config.WebUIConfig           `json:"webui"`
+		Transcription   *config.TranscriptionConfig   `json:"transcription"`
+		TTS             *config.TTSConfig             `json:"tts"`
+		Audit           *config.AuditConfig           `json:"audit"`
+		Undo            *config.UndoConfig            `json:"undo"`
+		Preprocess      *config.PreprocessConfig      `json:"preprocess"`
+		Learnings       *config.LearningsConfig       `json:"learnings"`
+		Watch           *config.WatchConfig           `json:"watch"`
+		MOTD            *config.MOTDConfig            `json:"motd"`
+		Alerts          *config.AlertsConfig          `json:"alerts"`
+		Moderation      *config.ModerationConfig      `json:"moderation"`
+		Usage           *config.UsageConfig           `json:"usage"`
+		ResponseFilters *config.ResponseFiltersConfig `json:"response_filters"`
+		TurnLimits      *config.TurnLimitsConfig      `json:"turn_limits"`
+		Backup          *config.BackupConfig          `json:"backup"`
+		ToolSessions    *config.ToolSessionsConfig    `json:"tool_sessions"`
+		Providers       []config.ProviderProfile      `json:"providers"`
 	}
 	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid JSON payload"})
 	}
 
-	sess, err := s.sessionMgr.GetExisting(id)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	// Apply config sections
+	if body.Storage != nil {
+		s.config.Storage = *body.Storage
 	}
-
-	sess.SetSummary(body.Summary)
-	if err := s.sessionMgr.Save(sess); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to save session summary: %v", err)})
+	if body.Agents != nil {
+		s.config.Agents = *body.Agents
 	}
-
-	return c.JSON(http.StatusOK, map[string]string{"status": "updated"})
-}
-
-func (s *Server) handleUpdateSessionRuntime(c *echo.Context) error {
-	if s.sessionMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	if body.Gateway != nil {
+		s.config.Gateway = *body.Gateway
 	}
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	if body.Tools != nil {
+		s.config.Tools = *body.Tools
 	}
-	if _, err := s.sessionMgr.GetExisting(id); err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	if body.Heartbeat != nil {
+		s.config.Heartbeat = *body.Heartbeat
 	}
-	var body struct {
-		RuntimeID string `json:"runtime_id"`
+	if body.Webhook != nil {
+		s.config.Webhook = *body.Webhook
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	if body.Redis != nil {
+		s.config.Redis = *body.Redis
 	}
-	if err := s.setThreadRuntimeBinding(id, body.RuntimeID); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if body.State != nil {
+		s.config.State = *body.State
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "updated"})
-}
-
-func (s *Server) handleUpdateSessionThread(c *echo.Context) error {
-	if s.sessionMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	if body.Bus != nil {
+		s.config.Bus = *body.Bus
 	}
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	if body.Approval != nil {
+		s.config.Approval = *body.Approval
 	}
-	if _, err := s.sessionMgr.GetExisting(id); err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+	if body.Logger != nil {
+		s.config.Logger = *body.Logger
+	}
+	if body.Memory != nil {
+		s.config.Memory = *body.Memory
+	}
+	if body.Sessions != nil {
+		s.config.Sessions = *body.Sessions
+	}
+	if body.WebUI != nil {
+		s.config.WebUI = *body.WebUI
+		if s.toolSess != nil {
+			s.toolSess.SetEventConfig(s.config.WebUI.ToolSessionEvents)
+			s.toolSess.SetResourceLimits(s.config.WebUI.ToolSessionLimits)
+		}
+		if s.skillsMgr != nil {
+			s.skillsMgr.SetSnapshotRetention(skills.SnapshotRetentionConfig{
+				AutoPrune: s.config.WebUI.SkillSnapshots.AutoPrune,
+				MaxCount:  s.config.WebUI.SkillSnapshots.MaxCount,
+			})
+			s.skillsMgr.SetVersionRetention(skills.VersionRetentionConfig{
+				Enabled:  s.config.WebUI.SkillVersions.Enabled,
+				MaxCount: s.config.WebUI.SkillVersions.MaxCount,
+			})
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
 	}
-	var body struct {
-		Topic string `json:"topic"`
+	if body.Transcription != nil {
+		s.config.Transcription = *body.Transcription
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	if body.TTS != nil {
+		s.config.TTS = *body.TTS
 	}
-	if err := s.setThreadTopic(id, body.Topic); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if body.Audit != nil {
+		s.config.Audit = *body.Audit
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "updated"})
-}
-
-func (s *Server) handleDeleteSession(c *echo.Context) error {
-	if s.sessionMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	if body.Undo != nil {
+		s.config.Undo = *body.Undo
 	}
-
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	if body.Preprocess != nil {
+		s.config.Preprocess = *body.Preprocess
 	}
-
-	if _, err := s.sessionMgr.GetExisting(id); err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	if body.Learnings != nil {
+		s.config.Learnings = *body.Learnings
 	}
-	if err := s.sessionMgr.Delete(id); err != nil && !errors.Is(err, os.ErrNotExist) {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to delete session: %v", err)})
+	if body.Watch != nil {
+		s.config.Watch = *body.Watch
 	}
-	_ = s.deleteThread(id)
-	if s.prompts != nil {
-		if err := s.prompts.ClearSessionBindings(c.Request().Context(), id); err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to delete session prompts: %v", err)})
-		}
+	if body.MOTD != nil {
+		s.config.MOTD = *body.MOTD
 	}
-
-	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
-}
-
-func (s *Server) handleCleanupSessions(c *echo.Context) error {
-	if s.sessionMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	if body.Alerts != nil {
+		s.config.Alerts = *body.Alerts
 	}
-	if err := session.CleanupPersistedSessions(s.config, s.sessionMgr); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if body.Moderation != nil {
+		s.config.Moderation = *body.Moderation
 	}
-	if s.prompts != nil {
-		listed, err := s.sessionMgr.List()
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("list sessions after cleanup: %v", err)})
-		}
-		active := make(map[string]struct{}, len(listed))
-		for _, item := range listed {
-			active[item] = struct{}{}
-		}
-		bindings, err := s.prompts.ListBindings(c.Request().Context(), prompts.ScopeSession, "")
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("list session prompt bindings: %v", err)})
-		}
-		for _, binding := range bindings {
-			if _, ok := active[binding.Target]; ok {
-				continue
-			}
-			if err := s.prompts.ClearSessionBindings(c.Request().Context(), binding.Target); err != nil {
-				return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("cleanup session prompts: %v", err)})
-			}
-		}
+	if body.Usage != nil {
+		s.config.Usage = *body.Usage
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "cleaned"})
-}
-
-func (s *Server) handleListThreads(c *echo.Context) error {
-	if s.sessionMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	if body.ResponseFilters != nil {
+		s.config.ResponseFilters = *body.ResponseFilters
 	}
-
-	ids, err := s.sessionMgr.List()
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to list threads: %v", err)})
+	if body.TurnLimits != nil {
+		s.config.TurnLimits = *body.TurnLimits
+	}
+	if body.Backup != nil {
+		s.config.Backup = *body.Backup
+	}
+	if body.ToolSessions != nil {
+		s.config.ToolSessions = *body.ToolSessions
 	}
 
-	items := make([]threadSummaryResponse, 0, len(ids))
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	for _, id := range ids {
-		sess, err := s.sessionMgr.GetExisting(id)
-		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				continue
-			}
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load thread %q: %v", id, err)})
-		}
-		messages := sess.GetMessages()
-		target := sessionTarget(sess.GetID())
-		items = append(items, threadSummaryResponse{
-			ID:                  sess.GetID(),
-			Target:              target,
-			CreatedAt:           sess.GetCreatedAt(),
-			UpdatedAt:           sess.GetUpdatedAt(),
-			Summary:             sess.GetSummary(),
-			MessageCount:        len(messages),
-			RuntimeID:           s.getThreadRuntimeBinding(id),
-			Topic:               s.getThreadTopic(id),
-			NotificationRouteID: s.getDefaultNotificationRouteID(ctx, notificationroutes.ScopeThread, target),
-		})
+	// Runtime sections persisted to the database after validation.
+	sections := make([]string, 0, 19)
+	if body.Agents != nil {
+		sections = append(sections, "agents")
 	}
-
-	sort.SliceStable(items, func(i, j int) bool {
-		return items[i].UpdatedAt.After(items[j].UpdatedAt)
-	})
-
-	return c.JSON(http.StatusOK, items)
-}
-
-func (s *Server) handleGetThread(c *echo.Context) error {
-	if s.sessionMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	if body.Gateway != nil {
+		sections = append(sections, "gateway")
 	}
-
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "thread id is required"})
+	if body.Tools != nil {
+		sections = append(sections, "tools")
 	}
-
-	sess, err := s.sessionMgr.GetExisting(id)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "thread not found"})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load thread: %v", err)})
+	if body.Heartbeat != nil {
+		sections = append(sections, "heartbeat")
 	}
-
-	messages := sess.GetMessages()
-	target := sessionTarget(sess.GetID())
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	return c.JSON(http.StatusOK, threadDetailResponse{
-		ID:                  sess.GetID(),
-		Target:              target,
-		CreatedAt:           sess.GetCreatedAt(),
-		UpdatedAt:           sess.GetUpdatedAt(),
-		Summary:             sess.GetSummary(),
-		MessageCount:        len(messages),
-		RuntimeID:           s.getThreadRuntimeBinding(id),
-		Topic:               s.getThreadTopic(id),
-		NotificationRouteID: s.getDefaultNotificationRouteID(ctx, notificationroutes.ScopeThread, target),
-		Messages:            buildSessionMessageResponses(messages),
-	})
-}
-
-func (s *Server) handleUpdateThread(c *echo.Context) error {
-	if s.sessionMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	if body.Webhook != nil {
+		sections = append(sections, "webhook")
 	}
-	id := strings.TrimSpace(c.Param("id"))
-	if id == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "thread id is required"})
+	if body.Redis != nil {
+		sections = append(sections, "redis")
 	}
-	sess, err := s.sessionMgr.GetExisting(id)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "thread not found"})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load thread: %v", err)})
+	if body.State != nil {
+		sections = append(sections, "state")
 	}
-	var body struct {
-		Summary             string  `json:"summary"`
-		RuntimeID           string  `json:"runtime_id"`
-		Topic               string  `json:"topic"`
-		NotificationRouteID *string `json:"notification_route_id"`
+	if body.Bus != nil {
+		sections = append(sections, "bus")
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	if body.Approval != nil {
+		sections = append(sections, "approval")
 	}
-	if strings.TrimSpace(body.Summary) != "" || body.Summary == "" {
-		sess.SetSummary(body.Summary)
-		if err := s.sessionMgr.Save(sess); err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to save thread summary: %v", err)})
-		}
+	if body.Logger != nil {
+		sections = append(sections, "logger")
 	}
-	if err := s.setThreadRuntimeBinding(id, body.RuntimeID); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if body.Memory != nil {
+		sections = append(sections, "memory")
 	}
-	if err := s.setThreadTopic(id, body.Topic); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if body.Sessions != nil {
+		sections = append(sections, "sessions")
 	}
-	if s.notificationMgr != nil && body.NotificationRouteID != nil {
-		ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-		if _, err := s.bindDefaultNotificationRoute(ctx, notificationroutes.ScopeThread, sessionTarget(id), *body.NotificationRouteID, nil, nil); err != nil {
-			if errors.Is(err, ownership.ErrPermissionDenied) {
-				return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
-			}
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
-		}
+	if body.WebUI != nil {
+		sections = append(sections, "webui")
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "updated"})
-}
-
-func (s *Server) getDefaultNotificationRouteID(ctx context.Context, scope, target string) string {
-	if s == nil || s.notificationMgr == nil {
-		return ""
+	if body.Transcription != nil {
+		sections = append(sections, "transcription")
 	}
-	binding, err := s.notificationMgr.FindBindingForTarget(ctx, scope, target)
-	if err != nil || binding == nil || !binding.Enabled {
-		return ""
+	if body.TTS != nil {
+		sections = append(sections, "tts")
 	}
-	return binding.RouteID
-}
-
-// --- Status Handler ---
-
-func (s *Server) handleStatus(c *echo.Context) error {
-	var mem runtime.MemStats
-	runtime.ReadMemStats(&mem)
-
-	uptime := time.Since(s.startedAt)
-	configPath, err := s.resolveBootstrapConfigPath()
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if body.Audit != nil {
+		sections = append(sections, "audit")
 	}
-	runtimeDBPath, err := config.RuntimeDBDisplayName(s.config)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if body.Undo != nil {
+		sections = append(sections, "undo")
 	}
-	workspaceStatus, err := workspace.NewManager(s.config.WorkspacePath(), s.logger).Inspect()
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if body.Preprocess != nil {
+		sections = append(sections, "preprocess")
 	}
-	taskSnapshots := s.listTaskSnapshots()
-	recentTasks, stateCounts := summarizeTasks(taskSnapshots, 5)
-	recentCronJobs := s.listRecentCronJobs(5)
-	runtimeStates, err := s.deriveRuntimeStatuses(c.Request().Context())
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if body.Learnings != nil {
+		sections = append(sections, "learnings")
 	}
-
-	sessionStates := s.listSessionStates()
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"version":                      version.GetVersion(),
-		"commit":                       version.GitCommit,
-		"build_time":                   version.BuildTime,
-		"os":                           runtime.GOOS,
-		"arch":                         runtime.GOARCH,
-		"go_version":                   runtime.Version(),
-		"pid":                          os.Getpid(),
-		"uptime":                       uptime.Round(time.Second).String(),
-		"uptime_seconds":               int64(uptime.Seconds()),
-		"memory_alloc_bytes":           mem.Alloc,
-		"memory_sys_bytes":             mem.Sys,
-		"provider_count":               len(s.config.Providers),
-		"config_path":                  configPath,
-		"runtime_db_type":              s.config.DatabaseType(),
-		"database_dir":                 s.config.Storage.DBDir,
-		"runtime_db_path":              runtimeDBPath,
-		"workspace_path":               s.config.Agents.Defaults.Workspace,
-		"workspace_contract":           workspaceStatus.Contract,
-		"workspace_validation_summary": workspaceStatus.ValidationSummary,
-		"task_count":                   len(taskSnapshots),
-		"task_state_counts":            stateCounts,
-		"recent_tasks":                 recentTasks,
-		"recent_cron_jobs":             recentCronJobs,
-		"runtime_states":               runtimeStates,
-		"daemon_machines": func() interface{} {
-			if s.kvStore == nil {
-				return []daemonhost.MachineStatus{}
-			}
-			snapshot, err := daemonhost.NewRegistry(s.kvStore).Snapshot(c.Request().Context())
-			if err != nil || snapshot == nil {
-				return []daemonhost.MachineStatus{}
-			}
-			return daemonhost.MachineStatuses(snapshot)
-		}(),
-		"session_runtime_states": sessionStates,
-		"agent_definition": func() interface{} {
-			if s.agent == nil {
-				return nil
-			}
-			return s.agent.Definition()
-		}(),
-		"gateway_host": s.config.Gateway.Host,
-		"gateway_port": s.config.Gateway.Port,
-		"gateway": map[string]interface{}{
-			"host": s.config.Gateway.Host,
-			"port": s.config.Gateway.Port,
-		},
-	})
-}
-
-func (s *Server) listTaskSnapshots() []tasks.Task {
-	if s == nil || s.taskStore == nil {
-		return nil
+	if body.Watch != nil {
+		sections = append(sections, "watch")
 	}
-	return s.taskStore.List()
-}
-
-func (s *Server) listRecentCronJobs(limit int) []cron.Job {
-	if s == nil || s.cronMgr == nil {
-		return []cron.Job{}
+	if body.MOTD != nil {
+		sections = append(sections, "motd")
 	}
-
-	jobs := s.cronMgr.ListJobs()
-	if len(jobs) == 0 {
-		return []cron.Job{}
+	if body.Alerts != nil {
+		sections = append(sections, "alerts")
 	}
-
-	filtered := make([]cron.Job, 0, len(jobs))
-	for _, job := range jobs {
-		if job == nil || job.LastRun.IsZero() {
-			continue
-		}
-		filtered = append(filtered, *job)
+	if body.Moderation != nil {
+		sections = append(sections, "moderation")
 	}
-	if len(filtered) == 0 {
-		return []cron.Job{}
+	if body.Usage != nil {
+		sections = append(sections, "usage")
 	}
-
-	sort.SliceStable(filtered, func(i, j int) bool {
-		return filtered[i].LastRun.After(filtered[j].LastRun)
-	})
-	if limit > 0 && len(filtered) > limit {
-		filtered = filtered[:limit]
+	if body.ResponseFilters != nil {
+		sections = append(sections, "response_filters")
 	}
-	return filtered
-}
-
-func (s *Server) listSessionStates() []tasks.SessionState {
-	if s == nil || s.taskStore == nil {
-		return []tasks.SessionState{}
+	if body.TurnLimits != nil {
+		sections = append(sections, "turn_limits")
 	}
-	return s.taskStore.ListSessionStates()
-}
-
-func (s *Server) deriveRuntimeStatuses(ctx context.Context) ([]runtimeagents.AgentRuntime, error) {
-	if s == nil || s.runtimeMgr == nil {
-		return []runtimeagents.AgentRuntime{}, nil
+	if body.Backup != nil {
+		sections = append(sections, "backup")
 	}
-
-	items, err := s.runtimeMgr.List(ctx)
-	if err != nil {
-		return nil, err
+	if body.ToolSessions != nil {
+		sections = append(sections, "tool_sessions")
+	}
+	importedSections := sections
+	if body.Storage != nil {
+		importedSections = append([]string{"storage"}, sections...)
 	}
-	return s.attachRuntimeStatuses(ctx, items)
-}
 
-func (s *Server) attachRuntimeStatuses(ctx context.Context, items []runtimeagents.AgentRuntime) ([]runtimeagents.AgentRuntime, error) {
-	if len(items) == 0 {
-		return []runtimeagents.AgentRuntime{}, nil
+	// Validate
+	if err := config.ValidateConfig(s.config); err != nil {
+		s.config.Storage = previousStorage
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error":    err.Error(),
+			"sections": configImportSectionResults(importedSections, err),
+		})
 	}
 
-	bindingsByRuntimeID := make(map[string]int, len(items))
-	enabledBindingsByRuntimeID := make(map[string]int, len(items))
-	accountEnabledByID := map[string]bool{}
-	if s.accountMgr != nil {
-		accounts, err := s.accountMgr.List(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("list channel accounts for runtime status: %w", err)
+	restartSections := make([]string, 0, 5)
+	if body.Storage != nil || body.Logger != nil || body.Gateway != nil || body.WebUI != nil || body.Webhook != nil {
+		if body.Storage != nil && oldRuntimeDBIsSQLite && s.config.DatabaseType() == "sqlite" {
+			newRuntimeDBPath, err := config.RuntimeDBPath(s.config)
+			if err != nil {
+				s.config.Storage = previousStorage
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			}
+			if err := config.MigrateRuntimeDB(oldRuntimeDBPath, newRuntimeDBPath); err != nil {
+				s.config.Storage = previousStorage
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			}
 		}
-		for _, account := range accounts {
-			accountEnabledByID[account.ID] = account.Enabled
+		if err := s.saveBootstrapConfig(); err != nil {
+			s.config.Storage = previousStorage
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		}
-	}
-	if s.bindingMgr != nil {
-		bindings, err := s.bindingMgr.List(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("list account bindings for runtime status: %w", err)
+		if body.Storage != nil {
+			restartSections = append(restartSections, "storage")
 		}
-		for _, binding := range bindings {
-			bindingsByRuntimeID[binding.AgentRuntimeID]++
-			if binding.Enabled && accountEnabledByID[binding.ChannelAccountID] {
-				enabledBindingsByRuntimeID[binding.AgentRuntimeID]++
-			}
+		if body.Logger != nil {
+			restartSections = append(restartSections, "logger")
 		}
-	}
-
-	tasksByRuntimeID := make(map[string]int, len(items))
-	lastSeenByRuntimeID := make(map[string]time.Time, len(items))
-	for _, task := range s.listTaskSnapshots() {
-		runtimeID := strings.TrimSpace(task.RuntimeID)
-		if runtimeID == "" {
-			continue
+		if body.Gateway != nil {
+			restartSections = append(restartSections, "gateway")
 		}
-		if !tasks.IsFinal(task.State) {
-			tasksByRuntimeID[runtimeID]++
+		if body.WebUI != nil {
+			restartSections = append(restartSections, "webui")
 		}
-		taskTime := taskSortTime(task)
-		if taskTime.After(lastSeenByRuntimeID[runtimeID]) {
-			lastSeenByRuntimeID[runtimeID] = taskTime
+		if body.Webhook != nil {
+			restartSections = append(restartSections, "webhook")
 		}
 	}
 
-	result := make([]runtimeagents.AgentRuntime, 0, len(items))
-	for _, item := range items {
-		status := &runtimeagents.RuntimeDerivedStatus{
-			EffectiveAvailable:  item.Enabled && enabledBindingsByRuntimeID[item.ID] > 0,
-			BoundAccountCount:   bindingsByRuntimeID[item.ID],
-			EnabledBindingCount: enabledBindingsByRuntimeID[item.ID],
-			CurrentTaskCount:    tasksByRuntimeID[item.ID],
-			LastSeenAt:          runtimeagents.NormalizeTimestamp(lastSeenByRuntimeID[item.ID]),
+	// Persist runtime sections to database
+	if len(sections) > 0 {
+		if err := config.SaveDatabaseSections(s.config, sections...); err != nil {
+			s.logger.Error("Failed to persist imported config sections", zap.Error(err), zap.Strings("sections", sections))
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save config sections"})
 		}
-		switch {
-		case !item.Enabled:
-			status.AvailabilityReason = "runtime_disabled"
-		case enabledBindingsByRuntimeID[item.ID] == 0:
-			if bindingsByRuntimeID[item.ID] == 0 {
-				status.AvailabilityReason = "unbound"
-			} else {
-				status.AvailabilityReason = "no_enabled_bindings"
+	}
+	if body.Agents != nil {
+		s.refreshMCPServers()
+	}
+	if body.Watch != nil {
+		if err := s.syncWatchRuntime(); err != nil {
+			if s.logger != nil {
+				s.logger.Error("Failed to sync watch runtime after config import", zap.Error(err))
 			}
-		default:
-			status.AvailabilityReason = "available"
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to sync watch runtime"})
 		}
-		item.Status = status
-		result = append(result, item)
 	}
-	return result, nil
-}
 
-func summarizeTasks(all []tasks.Task, limit int) ([]tasks.Task, map[string]int) {
-	stateCounts := make(map[string]int)
-	if len(all) == 0 {
-		return []tasks.Task{}, stateCounts
+	// Import providers best-effort; each entry reports its own outcome.
+	providerResults := make([]configImportProviderResult, 0, len(body.Providers))
+	importedProviders := 0
+	failedProviders := 0
+	ctx := c.Request().Context()
+	for _, profile := range body.Providers {
+		result := s.importProvider(ctx, profile)
+		if result.Status == configImportFailed {
+			s.logger.Warn("Failed to import provider", zap.String("name", result.Name), zap.String("error", result.Error))
+			failedProviders++
+		} else {
+			importedProviders++
+		}
+		providerResults = append(providerResults, result)
 	}
 
-	snapshots := append([]tasks.Task(nil), all...)
-	for _, task := range snapshots {
-		stateCounts[string(task.State)]++
+	summary := audit.Diff(json.RawMessage(before), s.configSections())
+	if len(body.Providers) > 0 {
+		summary = strings.TrimPrefix(fmt.Sprintf("%s; providers: %d imported, %d failed", summary, importedProviders, failedProviders), "; ")
 	}
-	sort.SliceStable(snapshots, func(i, j int) bool {
-		return taskSortTime(snapshots[i]).After(taskSortTime(snapshots[j]))
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionConfigImport,
+		Success: true,
+		Summary: summary,
 	})
-	if limit > 0 && len(snapshots) > limit {
-		snapshots = snapshots[:limit]
-	}
-	return snapshots, stateCounts
-}
 
-func taskSortTime(task tasks.Task) time.Time {
-	switch {
-	case !task.CompletedAt.IsZero():
-		return task.CompletedAt
-	case !task.StartedAt.IsZero():
-		return task.StartedAt
-	default:
-		return task.CreatedAt
-	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":             "imported",
+		"sections_saved":     len(sections),
+		"sections":           configImportSectionResults(importedSections, nil),
+		"providers_imported": importedProviders,
+		"providers_failed":   failedProviders,
+		"providers":          providerResults,
+		"restart_required":   len(restartSections) > 0,
+		"restart_sections":   restartSections,
+	})
 }
 
-func (s *Server) handleServiceStatus(c *echo.Context) error {
-	if s.serviceCtrl == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "service control not available"})
-	}
-	gatewayStatus, err := s.serviceCtrl.Status()
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-	}
-	clientdStatus, err := s.serviceCtrl.NekoClientdStatus()
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-	}
-	return c.JSON(http.StatusOK, map[string]any{"gateway": gatewayStatus, "nekoclientd": clientdStatus})
-}
+const (
+	configImportSaved   = "saved"
+	configImportValid   = "valid"
+	configImportInvalid = "invalid"
+	configImportCreated = "created"
+	configImportUpdated = "updated"
+	configImportFailed  = "failed"
+)
 
-func (s *Server) handleServiceRestart(c *echo.Context) error {
-	if s.serviceCtrl == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "service control not available"})
-	}
+// configImportSectionResult is the validation outcome of one imported section.
+type configImportSectionResult struct {
+	Section string   `json:"section"`
+	Status  string   `json:"status"`
+	Errors  []string `json:"errors,omitempty"`
+}
 
-	if err := s.serviceCtrl.Restart(); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "restarting"})
+// configImportProviderResult is the outcome of importing one provider.
+type configImportProviderResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
 }
 
-func (s *Server) handleServiceReload(c *echo.Context) error {
-	if s.serviceCtrl == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "service control not available"})
+// configImportSectionResults groups validation errors by top-level section.
+// Without errors every imported section is reported as saved; with errors the
+// import is rejected, so clean sections are only reported as valid. Errors in
+// sections that were not part of the import are listed too, since they still
+// block it.
+func configImportSectionResults(sections []string, validationErr error) []configImportSectionResult {
+	results := make([]configImportSectionResult, 0, len(sections))
+	index := make(map[string]int, len(sections))
+	for _, section := range sections {
+		status := configImportSaved
+		if validationErr != nil {
+			status = configImportValid
+		}
+		index[section] = len(results)
+		results = append(results, configImportSectionResult{Section: section, Status: status})
 	}
 
-	if err := s.serviceCtrl.Reload(); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	var validationErrs config.ValidationErrors
+	if !errors.As(validationErr, &validationErrs) {
+		return results
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "reloaded"})
+	for _, item := range validationErrs {
+		section, _, _ := strings.Cut(item.Field, ".")
+		i, ok := index[section]
+		if !ok {
+			index[section] = len(results)
+			i = len(results)
+			results = append(results, configImportSectionResult{Section: section})
+		}
+		results[i].Status = configImportInvalid
+		results[i].Errors = append(results[i].Errors, item.Error())
+	}
+	return results
 }
 
-func (s *Server) handleGetWatchStatus(c *echo.Context) error {
-	if s.config == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "config unavailable"})
+// importProvider updates an existing provider or creates it when missing.
+func (s *Server) importProvider(ctx context.Context, profile config.ProviderProfile) configImportProviderResult {
+	result := configImportProviderResult{Name: strings.TrimSpace(profile.Name)}
+	if result.Name == "" {
+		result.Status = configImportFailed
+		result.Error = "provider name is required"
+		return result
 	}
 
-	if s.watcher == nil {
-		return c.JSON(http.StatusOK, watch.StatusSnapshot{
-			Enabled:    s.config.Watch.Enabled,
-			Running:    false,
-			DebounceMs: s.config.Watch.DebounceMs,
-			Patterns:   append([]config.WatchPattern(nil), s.config.Watch.Patterns...),
-		})
+	_, err := s.providers.Update(ctx, result.Name, profile)
+	switch {
+	case err == nil:
+		result.Status = configImportUpdated
+	case errors.Is(err, providerstore.ErrProviderNotFound):
+		if _, createErr := s.providers.Create(ctx, profile); createErr != nil {
+			result.Status = configImportFailed
+			result.Error = createErr.Error()
+		} else {
+			result.Status = configImportCreated
+		}
+	default:
+		result.Status = configImportFailed
+		result.Error = err.Error()
 	}
-
-	return c.JSON(http.StatusOK, s.watcher.Status())
+	return result
 }
 
-func (s *Server) handleUpdateWatchStatus(c *echo.Context) error {
-	if s.config == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "config unavailable"})
+func (s *Server) handleTestWebhook(c *echo.Context) error {
+	if !s.config.Webhook.Enabled {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "webhook trigger is disabled"})
+	}
+	if s.agent == nil && s.webhookTestHandler == nil || s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "agent runtime not available"})
 	}
 
 	var body struct {
-		Enabled    *bool                 `json:"enabled"`
-		DebounceMs *int                  `json:"debounce_ms"`
-		Patterns   []config.WatchPattern `json:"patterns"`
+		Message string `json:"message"`
 	}
 	if err := c.Bind(&body); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-
-	if body.Enabled != nil {
-		s.config.Watch.Enabled = *body.Enabled
+	messageText := strings.TrimSpace(body.Message)
+	if messageText == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message is required"})
 	}
-	if body.DebounceMs != nil {
-		s.config.Watch.DebounceMs = *body.DebounceMs
+
+	username := strings.TrimSpace(s.currentUsername(c))
+	if username == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
 	}
-	if body.Patterns != nil {
-		s.config.Watch.Patterns = append([]config.WatchPattern(nil), body.Patterns...)
+	sess, err := s.sessionMgr.GetWithSource("webhook:"+username, session.SourceWebUI)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-
-	if err := config.SaveDatabaseSections(s.config, "watch"); err != nil {
-		if s.logger != nil {
-			s.logger.Error("Failed to persist watch config", zap.Error(err))
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save watch config"})
+	var reply string
+	if s.webhookTestHandler != nil {
+		reply, err = s.webhookTestHandler(c.Request().Context(), username, messageText)
+	} else {
+		reply, err = s.agent.Chat(agent.WithTenant(c.Request().Context(), s.currentTenantSlug(c)), sess, messageText)
 	}
-	if err := s.syncWatchRuntime(); err != nil {
-		if s.logger != nil {
-			s.logger.Error("Failed to sync watch runtime", zap.Error(err))
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no providers configured") {
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to sync watch runtime"})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"status":     "ok",
+		"reply":      reply,
+		"session_id": sess.ID,
+	})
+}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"status":           "saved",
-		"restart_required": true,
-		"watch": func() watch.StatusSnapshot {
-			if s.watcher != nil {
-				return s.watcher.Status()
-			}
-			return watch.StatusSnapshot{
-				Enabled:    s.config.Watch.Enabled,
-				Running:    false,
-				DebounceMs: s.config.Watch.DebounceMs,
-				Patterns:   append([]config.WatchPattern(nil), s.config.Watch.Patterns...),
-			}
-		}(),
-	})
-}
+// ---------------------------------------------------------------------------
+// Notification route handlers
+// ---------------------------------------------------------------------------
 
-func (s *Server) handleGetHarnessAudit(c *echo.Context) error {
-	if s.auditLogger == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "audit log unavailable"})
+func (s *Server) handleListNotificationRoutes(c *echo.Context) error {
+	if s.notificationMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification routes not available"})
 	}
-
-	limit := 100
-	if rawLimit := strings.TrimSpace(c.QueryParam("limit")); rawLimit != "" {
-		parsed, err := strconv.Atoi(rawLimit)
-		if err != nil || parsed <= 0 {
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid limit"})
-		}
-		if parsed > 500 {
-			parsed = 500
-		}
-		limit = parsed
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	routes, err := s.notificationMgr.ListRoutes(ctx)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if routes == nil {
+		routes = []notificationroutes.NotificationRoute{}
 	}
+	return c.JSON(http.StatusOK, routes)
+}
 
-	entries, err := s.auditLogger.ReadLast(limit)
+func (s *Server) handleCreateNotificationRoute(c *echo.Context) error {
+	if s.notificationMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification routes not available"})
+	}
+	var item notificationroutes.NotificationRoute
+	if err := c.Bind(&item); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	route, err := s.notificationMgr.CreateRoute(ctx, item)
 	if err != nil {
-		if s.logger != nil {
-			s.logger.Error("Failed to read audit log", zap.Error(err))
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to read audit log"})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusCreated, route)
+}
 
-	stats, err := s.auditLogger.Stats()
+func (s *Server) handleUpdateNotificationRoute(c *echo.Context) error {
+	if s.notificationMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification routes not available"})
+	}
+	id := c.Param("id")
+	var item notificationroutes.NotificationRoute
+	if err := c.Bind(&item); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	route, err := s.notificationMgr.UpdateRoute(ctx, id, item)
 	if err != nil {
-		if s.logger != nil {
-			s.logger.Error("Failed to read audit stats", zap.Error(err))
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to read audit stats"})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"entries": entries,
-		"stats":   stats,
-		"limit":   limit,
-	})
+	return c.JSON(http.StatusOK, route)
 }
 
-func (s *Server) handleClearHarnessAudit(c *echo.Context) error {
-	if s.auditLogger == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "audit log unavailable"})
+func (s *Server) handleDeleteNotificationRoute(c *echo.Context) error {
+	if s.notificationMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification routes not available"})
 	}
-
-	if err := s.auditLogger.Clear(); err != nil && !os.IsNotExist(err) {
-		if s.logger != nil {
-			s.logger.Error("Failed to clear audit log", zap.Error(err))
+	id := c.Param("id")
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	if err := s.notificationMgr.DeleteRoute(ctx, id); err != nil {
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to clear audit log"})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
 
-	stats, err := s.auditLogger.Stats()
+// ---------------------------------------------------------------------------
+// Notification binding handlers
+// ---------------------------------------------------------------------------
+
+func (s *Server) handleListNotificationBindings(c *echo.Context) error {
+	if s.notificationMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
+	}
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	bindings, err := s.notificationMgr.ListBindings(ctx)
 	if err != nil {
-		if s.logger != nil {
-			s.logger.Error("Failed to refresh audit stats after clear", zap.Error(err))
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to read audit stats"})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"status": "cleared",
-		"stats":  stats,
-	})
+	if bindings == nil {
+		bindings = []notificationroutes.NotificationBinding{}
+	}
+	return c.JSON(http.StatusOK, bindings)
 }
 
-// --- Chat WebSocket Playground ---
-
-var wsUpgrader = websocket.Upgrader{
-	ReadBufferSize:  4096,
-	WriteBufferSize: 4096,
-	CheckOrigin:     func(r *http.Request) bool { return true },
+func (s *Server) handleCreateNotificationBinding(c *echo.Context) error {
+	if s.notificationMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
+	}
+	var item notificationroutes.NotificationBinding
+	if err := c.Bind(&item); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	binding, err := s.notificationMgr.CreateBinding(ctx, item)
+	if err != nil {
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, binding)
 }
 
-type chatWSMessage struct {
-	Type            string   `json:"type"`                        // "message", "ping", "clear"
-	Content         string   `json:"content"`                     // User message text
-	Model           string   `json:"model"`                       // Optional model override
-	Provider        string   `json:"provider,omitempty"`          // Optional provider override
-	Fallback        []string `json:"fallback,omitempty"`          // Optional fallback provider order
-	SystemPromptIDs []string `json:"system_prompt_ids,omitempty"` // Optional session prompt overlays
-	UserPromptIDs   []string `json:"user_prompt_ids,omitempty"`   // Optional session prompt overlays
-	RuntimeID       string   `json:"runtime_id,omitempty"`        // Optional explicit runtime selection
+func (s *Server) handleUpdateNotificationBinding(c *echo.Context) error {
+	if s.notificationMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
+	}
+	id := c.Param("id")
+	var item notificationroutes.NotificationBinding
+	if err := c.Bind(&item); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	binding, err := s.notificationMgr.UpdateBinding(ctx, id, item)
+	if err != nil {
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, binding)
 }
 
-type chatWSResponse struct {
-	Type      string          `json:"type"`                 // "message", "thinking", "error", "system", "pong", "route_result"
-	Content   string          `json:"content"`              // Response text
-	Thinking  string          `json:"thinking,omitempty"`   // Model's thinking (if extended thinking enabled)
-	Timestamp int64           `json:"timestamp,omitempty"`  // Unix timestamp
-	SessionID string          `json:"session_id,omitempty"` // Routed chat session
-	Route     *chatRouteState `json:"route,omitempty"`
-	Meta      interface{}     `json:"meta,omitempty"`
+func (s *Server) handleDeleteNotificationBinding(c *echo.Context) error {
+	if s.notificationMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
+	}
+	id := c.Param("id")
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	if err := s.notificationMgr.DeleteBinding(ctx, id); err != nil {
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
 }
 
-type fileMentionFeedback struct {
-	Count    int      `json:"count"`
-	Paths    []string `json:"paths,omitempty"`
-	Warnings []string `json:"warnings,omitempty"`
+func (s *Server) handleListNotificationBindingsByRoute(c *echo.Context) error {
+	if s.notificationMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
+	}
+	routeID := c.Param("routeId")
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	bindings, err := s.notificationMgr.ListBindingsByRoute(ctx, routeID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if bindings == nil {
+		bindings = []notificationroutes.NotificationBinding{}
+	}
+	return c.JSON(http.StatusOK, bindings)
 }
 
-type chatRouteSettings struct {
-	Provider string   `json:"provider"`
-	Model    string   `json:"model"`
-	Fallback []string `json:"fallback"`
+type notificationBindingTargetRequest struct {
+	Scope      string   `json:"scope"`
+	Target     string   `json:"target"`
+	RouteID    string   `json:"route_id"`
+	Enabled    *bool    `json:"enabled,omitempty"`
+	EventTypes []string `json:"event_types,omitempty"`
 }
 
-type chatRouteState struct {
-	RequestedProvider     string                   `json:"requested_provider"`
-	RequestedModel        string                   `json:"requested_model"`
-	RequestedFallback     []string                 `json:"requested_fallback"`
-	ResolvedOrder         []string                 `json:"resolved_order"`
-	ActualProvider        string                   `json:"actual_provider"`
-	ActualModel           string                   `json:"actual_model"`
-	Preflight             *chatRoutePreflightState `json:"preflight,omitempty"`
-	ContextBudgetStatus   string                   `json:"context_budget_status,omitempty"`
-	ContextBudgetReasons  []string                 `json:"context_budget_reasons,omitempty"`
-	CompactionRecommended bool                     `json:"compaction_recommended,omitempty"`
-	CompactionStrategy    string                   `json:"compaction_strategy,omitempty"`
-	RuntimeID             string                   `json:"runtime_id,omitempty"`
+func (s *Server) handleGetNotificationBindingByTarget(c *echo.Context) error {
+	if s.notificationMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
+	}
+	scope := strings.TrimSpace(c.QueryParam("scope"))
+	target := strings.TrimSpace(c.QueryParam("target"))
+	if scope == "" || target == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "scope and target are required"})
+	}
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	binding, err := s.notificationMgr.FindBindingForTarget(ctx, scope, target)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if binding == nil {
+		return c.JSON(http.StatusOK, map[string]interface{}{"binding": nil})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"binding": binding})
 }
 
-type chatRoutePreflightState struct {
-	Action        string                   `json:"action,omitempty"`
-	Applied       bool                     `json:"applied"`
-	BudgetStatus  string                   `json:"budget_status,omitempty"`
-	BudgetReasons []string                 `json:"budget_reasons,omitempty"`
-	Compaction    chatRouteCompactionState `json:"compaction"`
-}
-
-type chatRouteCompactionState struct {
-	Recommended         bool     `json:"recommended"`
-	Strategy            string   `json:"strategy,omitempty"`
-	Reasons             []string `json:"reasons,omitempty"`
-	EstimatedCharsSaved int      `json:"estimated_chars_saved,omitempty"`
-}
-
-func buildChatRouteWSResponse(sessionID, runtimeID string, routeResult agent.ChatRouteResult) chatWSResponse {
-	return chatWSResponse{
-		Type:      "route_result",
-		Timestamp: time.Now().Unix(),
-		SessionID: strings.TrimSpace(sessionID),
-		Route: &chatRouteState{
-			RequestedProvider: routeResult.RequestedProvider,
-			RequestedModel:    routeResult.RequestedModel,
-			RequestedFallback: append([]string(nil), routeResult.RequestedFallback...),
-			ResolvedOrder:     append([]string(nil), routeResult.ResolvedOrder...),
-			ActualProvider:    routeResult.ActualProvider,
-			ActualModel:       routeResult.ActualModel,
-			Preflight: &chatRoutePreflightState{
-				Action:        routeResult.Preflight.Action,
-				Applied:       routeResult.Preflight.Applied,
-				BudgetStatus:  routeResult.Preflight.BudgetStatus,
-				BudgetReasons: append([]string(nil), routeResult.Preflight.BudgetReasons...),
-				Compaction: chatRouteCompactionState{
-					Recommended:         routeResult.Preflight.Compaction.Recommended,
-					Strategy:            routeResult.Preflight.Compaction.Strategy,
-					Reasons:             append([]string(nil), routeResult.Preflight.Compaction.Reasons...),
-					EstimatedCharsSaved: routeResult.Preflight.Compaction.EstimatedCharsSaved,
-				},
-			},
-			ContextBudgetStatus:   routeResult.ContextBudgetStatus,
-			ContextBudgetReasons:  append([]string(nil), routeResult.ContextBudgetReasons...),
-			CompactionRecommended: routeResult.CompactionRecommended,
-			CompactionStrategy:    routeResult.CompactionStrategy,
-			RuntimeID:             runtimeID,
-		},
-	}
-}
-
-type toolWSMessage struct {
-	Type string `json:"type"` // "input", "ping", "kill", "resize"
-	Data string `json:"data,omitempty"`
-	Cols int    `json:"cols,omitempty"`
-	Rows int    `json:"rows,omitempty"`
-}
-
-type toolWSResponse struct {
-	Type      string `json:"type"`                 // "ready", "output", "status", "error", "pong"
-	SessionID string `json:"session_id,omitempty"` // for ready
-	Data      string `json:"data,omitempty"`       // terminal output
-	Total     int    `json:"total,omitempty"`      // output chunk cursor
-	Running   bool   `json:"running,omitempty"`
-	ExitCode  int    `json:"exit_code,omitempty"`
-	Missing   bool   `json:"missing,omitempty"`
-	Message   string `json:"message,omitempty"`
-}
-
-func webUIChatSessionID(username string) string {
-	username = strings.TrimSpace(username)
-	if username == "" {
-		return "webui-chat"
-	}
-	return "webui-chat:" + username
-}
-
-func webUIRuntimeChatSessionID(username, runtimeID string) string {
-	username = strings.TrimSpace(username)
-	runtimeID = strings.TrimSpace(runtimeID)
-	baseSessionID := webUIChatSessionID(username)
-	if runtimeID == "" {
-		return baseSessionID
+func (s *Server) handleSetNotificationBindingByTarget(c *echo.Context) error {
+	if s.notificationMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "notification bindings not available"})
 	}
-	return inboundrouter.SessionPrefix + ":" + runtimeID + ":" + baseSessionID
-}
-
-func webUIClientChatSessionID(runtimeID string) string {
-	runtimeID = strings.TrimSpace(runtimeID)
-	if runtimeID == "" {
-		return "webui-chat"
+	var body notificationBindingTargetRequest
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	return inboundrouter.SessionPrefix + ":" + runtimeID + ":webui-chat"
-}
-
-func buildWebUIChatPromptContext(
-	sessionID string,
-	username string,
-	provider string,
-	model string,
-	fallback []string,
-	explicitPromptIDs []string,
-	runtimeID string,
-) agent.PromptContext {
-	promptCtx := agent.PromptContext{
-		Channel:           session.SourceWebUI,
-		SessionID:         strings.TrimSpace(sessionID),
-		UserID:            strings.TrimSpace(username),
-		Username:          strings.TrimSpace(username),
-		RequestedProvider: strings.TrimSpace(provider),
-		RequestedModel:    strings.TrimSpace(model),
-		RequestedFallback: append([]string(nil), fallback...),
-		ExplicitPromptIDs: append([]string(nil), explicitPromptIDs...),
+	scope := strings.TrimSpace(body.Scope)
+	target := strings.TrimSpace(body.Target)
+	if scope == "" || target == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "scope and target are required"})
 	}
-	if strings.TrimSpace(runtimeID) != "" {
-		promptCtx.Custom = map[string]any{
-			"runtime_id": strings.TrimSpace(runtimeID),
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	binding, err := s.bindDefaultNotificationRoute(ctx, scope, target, strings.TrimSpace(body.RouteID), body.EventTypes, body.Enabled)
+	if err != nil {
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
 		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	return promptCtx
+	return c.JSON(http.StatusOK, map[string]interface{}{"binding": binding})
 }
 
-func (s *Server) resolveWebUIRuntimeSelection(
+func (s *Server) bindDefaultNotificationRoute(
 	ctx context.Context,
-	runtimeID string,
-	provider string,
-	model string,
-	fallback []string,
-) (string, string, []string, []string, error) {
-	runtimeID = strings.TrimSpace(runtimeID)
-	if runtimeID == "" {
-		return strings.TrimSpace(provider), strings.TrimSpace(model), append([]string(nil), fallback...), nil, nil
+	scope string,
+	target string,
+	routeID string,
+	eventTypes []string,
+	enabled *bool,
+) (*notificationroutes.NotificationBinding, error) {
+	if s == nil || s.notificationMgr == nil {
+		return nil, fmt.Errorf("notification routes not available")
 	}
-	if s == nil || s.runtimeMgr == nil {
-		return "", "", nil, nil, fmt.Errorf("runtime manager not available")
+	scope = strings.TrimSpace(scope)
+	target = strings.TrimSpace(target)
+	routeID = strings.TrimSpace(routeID)
+	if scope == "" || target == "" {
+		return nil, fmt.Errorf("scope and target are required")
 	}
-	if s.accountMgr == nil || s.bindingMgr == nil {
-		return "", "", nil, nil, fmt.Errorf("chat runtime topology is not available")
+	normalizedEvents := normalizeNotificationEventTypes(eventTypes)
+	if len(normalizedEvents) == 0 {
+		normalizedEvents = []string{notificationroutes.EventWebMessage}
 	}
-
-	runtimeItem, err := s.runtimeMgr.Get(ctx, runtimeID)
+	rawEvents, err := json.Marshal(normalizedEvents)
 	if err != nil {
-		return "", "", nil, nil, fmt.Errorf("get runtime %s: %w", runtimeID, err)
+		return nil, err
 	}
-	if runtimeItem == nil || !runtimeItem.Enabled {
-		return "", "", nil, nil, fmt.Errorf("runtime %s is not available", runtimeID)
+	isEnabled := true
+	if enabled != nil {
+		isEnabled = *enabled
 	}
-	websocketAccount, err := s.accountMgr.FindByChannelTypeAndAccountKey(ctx, "websocket", "default")
-	if err != nil {
-		return "", "", nil, nil, fmt.Errorf("runtime %s is not available for websocket chat", runtimeID)
+	var route *notificationroutes.NotificationRoute
+	if routeID != "" {
+		var err error
+		route, err = s.notificationMgr.GetRoute(ctx, routeID)
+		if err != nil {
+			return nil, err
+		}
 	}
-	if websocketAccount == nil || !websocketAccount.Enabled {
-		return "", "", nil, nil, fmt.Errorf("runtime %s is not available for websocket chat", runtimeID)
+	if err := s.notificationMgr.DeleteBindingsForTarget(ctx, scope, target); err != nil {
+		return nil, err
 	}
-	bindings, err := s.bindingMgr.ListEnabledByChannelAccountID(ctx, websocketAccount.ID)
-	if err != nil {
-		return "", "", nil, nil, fmt.Errorf("list websocket bindings: %w", err)
+	if route == nil {
+		return nil, nil
 	}
-	bound := false
-	for _, binding := range bindings {
-		if strings.TrimSpace(binding.AgentRuntimeID) == runtimeID {
-			bound = true
-			break
+	return s.notificationMgr.CreateBinding(ctx, notificationroutes.NotificationBinding{
+		Scope:          scope,
+		Target:         target,
+		RouteID:        routeID,
+		EventTypesJSON: string(rawEvents),
+		Enabled:        isEnabled,
+		TenantID:       route.TenantID,
+		OwnerUserID:    route.OwnerUserID,
+		Visibility:     route.Visibility,
+	})
+}
+
+func normalizeNotificationEventTypes(events []string) []string {
+	out := make([]string, 0, len(events))
+	seen := map[string]struct{}{}
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if event == "" {
+			continue
 		}
+		if _, ok := seen[event]; ok {
+			continue
+		}
+		seen[event] = struct{}{}
+		out = append(out, event)
 	}
-	if !bound {
-		return "", "", nil, nil, fmt.Errorf("runtime %s is not available for websocket chat", runtimeID)
-	}
-
-	return strings.TrimSpace(runtimeItem.Provider),
-		strings.TrimSpace(runtimeItem.Model),
-		nil,
-		runtimePromptIDs(runtimeItem.PromptID),
-		nil
+	return out
 }
 
-func (s *Server) handleChatWS(c *echo.Context) error {
-	// Authenticate via token query param (since WebSocket can't use Authorization header easily)
-	tokenStr := c.QueryParam("token")
-	if tokenStr == "" {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "token required"})
+func (s *Server) saveBootstrapConfig() error {
+	if s == nil || s.config == nil {
+		return fmt.Errorf("config is unavailable")
 	}
-	username, _, role, userID, tenantID, err := s.parseScopedStreamTokenClaims(tokenStr, streamTokenPurposeChatWS)
+
+	configPath, err := s.resolveBootstrapConfigPath()
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+		return err
 	}
-	authCtx := ownership.AuthContext{UserID: userID, TenantID: tenantID, Role: role}
 
-	// Upgrade to WebSocket
-	conn, err := wsUpgrader.Upgrade(c.Response(), c.Request(), nil)
-	if err != nil {
-		s.logger.Error("WebUI chat WS upgrade failed", zap.Error(err))
-		return nil
+	if err := config.SaveToFile(s.config, configPath); err != nil {
+		return fmt.Errorf("save bootstrap config: %w", err)
 	}
-	defer func() {
-		_ = conn.Close()
-	}()
+	return nil
+}
 
-	baseSessionID := webUIChatSessionID(username)
-	baseClientSessionID := webUIClientChatSessionID("")
-	sess, err := s.getOrCreateChatSession(baseSessionID)
-	if err != nil {
-		sendWSError(conn, fmt.Sprintf("session error: %v", err), baseClientSessionID)
+func (s *Server) syncWatchRuntime() error {
+	if s == nil || s.config == nil || s.watcher == nil {
 		return nil
 	}
+	if err := s.watcher.ApplyConfig(s.config.Watch); err != nil {
+		return fmt.Errorf("sync watch runtime: %w", err)
+	}
+	return nil
+}
 
-	// Send welcome
-	welcome := chatWSResponse{
-		Type:      "system",
-		Content:   "Connected to chat playground",
-		Timestamp: time.Now().Unix(),
-		SessionID: baseClientSessionID,
+func cloneConfigSnapshot(cfg *config.Config) (*config.Config, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config is unavailable")
 	}
-	if data, err := json.Marshal(welcome); err == nil {
-		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-			s.logger.Warn("Failed to send chat welcome", zap.Error(err))
-		}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("marshal config snapshot: %w", err)
 	}
-	routing := chatRouteSettings{
-		Provider: strings.TrimSpace(s.config.Agents.Defaults.Provider),
-		Model:    strings.TrimSpace(s.config.Agents.Defaults.Model),
-		Fallback: append([]string(nil), s.config.Agents.Defaults.Fallback...),
+
+	var cloned config.Config
+	if err := json.Unmarshal(data, &cloned); err != nil {
+		return nil, fmt.Errorf("unmarshal config snapshot: %w", err)
 	}
-	if data, err := json.Marshal(chatWSResponse{
-		Type:      "routing",
-		Content:   mustMarshalChatRouting(routing),
-		Timestamp: time.Now().Unix(),
-		SessionID: baseClientSessionID,
-	}); err == nil {
-		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-			s.logger.Warn("Failed to send chat routing", zap.Error(err))
-		}
+
+	return &cloned, nil
+}
+
+func (s *Server) clearChatSession(sessionID string) error {
+	if s == nil || s.sessionMgr == nil {
+		return fmt.Errorf("session manager not available")
 	}
 
-	// Read loop
-	conn.SetReadLimit(65536)
-	if err := conn.SetReadDeadline(time.Now().Add(120 * time.Second)); err != nil {
-		s.logger.Warn("Failed to set chat read deadline", zap.Error(err))
+	if err := s.sessionMgr.Delete(sessionID); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete session %q: %w", sessionID, err)
 	}
-	conn.SetPongHandler(func(string) error {
-		if err := conn.SetReadDeadline(time.Now().Add(120 * time.Second)); err != nil {
-			s.logger.Warn("Failed to refresh chat read deadline", zap.Error(err))
+	if s.snapshotMgr != nil {
+		if err := s.snapshotMgr.RemoveStore(sessionID); err != nil {
+			return fmt.Errorf("clear undo snapshots for %q: %w", sessionID, err)
 		}
-		return nil
-	})
+	}
+	if _, err := s.getOrCreateChatSession(sessionID); err != nil {
+		return fmt.Errorf("recreate session %q: %w", sessionID, err)
+	}
 
-	// Ping ticker
-	pingDone := make(chan struct{})
-	go func() {
-		ticker := time.NewTicker(30 * time.Second)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ticker.C:
-				if err := conn.SetWriteDeadline(time.Now().Add(10 * time.Second)); err != nil {
-					s.logger.Warn("Failed to set chat ping deadline", zap.Error(err))
-				}
-				if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-					return
-				}
-			case <-pingDone:
-				return
-			}
-		}
-	}()
-	defer close(pingDone)
+	return nil
+}
 
-	for {
-		_, message, err := conn.ReadMessage()
+func (s *Server) resolveBootstrapConfigPath() (string, error) {
+	configPath := ""
+	if s != nil && s.loader != nil {
+		configPath = strings.TrimSpace(s.loader.GetConfigPath())
+	}
+	if configPath == "" {
+		configPath = strings.TrimSpace(os.Getenv(config.ConfigPathEnv))
+	}
+	if configPath == "" {
+		home, err := config.GetConfigHome()
 		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
-				s.logger.Warn("WebUI chat WS read error", zap.Error(err))
-			}
-			return nil
+			return "", fmt.Errorf("resolve config home: %w", err)
 		}
+		configPath = filepath.Join(home, "config.json")
+	}
+	return configPath, nil
+}
 
-		var msg chatWSMessage
-		if err := json.Unmarshal(message, &msg); err != nil {
-			sendWSError(conn, "invalid message format", baseClientSessionID)
-			continue
-		}
+// --- Session Handlers ---
 
-		switch msg.Type {
-		case "ping":
-			resp := chatWSResponse{
-				Type:      "pong",
-				Timestamp: time.Now().Unix(),
-				SessionID: baseClientSessionID,
-			}
-			if data, err := json.Marshal(resp); err == nil {
-				if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-					s.logger.Warn("Failed to send chat pong", zap.Error(err))
-				}
-			}
+type sessionSummaryResponse struct {
+	ID           string    `json:"id"`
+	Channel      string    `json:"channel"`
+	Source       string    `json:"source"`
+	Archived     bool      `json:"archived"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Title        string    `json:"title"`
+	Summary      string    `json:"summary"`
+	MessageCount int       `json:"message_count"`
+	RuntimeID    string    `json:"runtime_id"`
+	Topic        string    `json:"topic"`
+}
 
-		case "clear":
-			clearSessionID := webUIRuntimeChatSessionID(username, msg.RuntimeID)
-			clearClientSessionID := webUIClientChatSessionID(msg.RuntimeID)
-			if err := s.clearChatSession(clearSessionID); err != nil {
-				sendWSError(conn, fmt.Sprintf("session reset failed: %v", err), clearClientSessionID)
-				continue
-			}
-			sess, err = s.sessionMgr.GetExisting(clearSessionID)
-			if err != nil {
-				sendWSError(conn, fmt.Sprintf("session error: %v", err), clearClientSessionID)
-				continue
-			}
-			resp := chatWSResponse{
-				Type:      "system",
-				Content:   "Session cleared",
-				Timestamp: time.Now().Unix(),
-				SessionID: clearClientSessionID,
-			}
-			if data, err := json.Marshal(resp); err == nil {
-				if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-					s.logger.Warn("Failed to send chat cleared event", zap.Error(err))
-				}
-			}
+type sessionMessageResponse struct {
+	ID          string                      `json:"id,omitempty"`
+	Role        string                      `json:"role"`
+	Content     string                      `json:"content"`
+	ToolCallID  string                      `json:"tool_call_id"`
+	Attachments []sessionAttachmentResponse `json:"attachments,omitempty"`
+}
 
-		case "message":
-			content := strings.TrimSpace(msg.Content)
-			if content == "" {
-				continue
-			}
-			runtimeID := strings.TrimSpace(msg.RuntimeID)
-			if runtimeID == "" {
-				runtimeID = s.getThreadRuntimeBinding(webUIChatSessionID(username))
-			}
-			sessionID := webUIRuntimeChatSessionID(username, runtimeID)
-			clientSessionID := webUIClientChatSessionID(runtimeID)
-			requestedModel := strings.TrimSpace(msg.Model)
-			requestedProvider := strings.TrimSpace(msg.Provider)
-			requestedFallback := normalizeProviderNames(msg.Fallback)
+type sessionAttachmentResponse struct {
+	AttachmentID    string `json:"attachment_id"`
+	Target          string `json:"target"`
+	OwnerID         string `json:"owner_id,omitempty"`
+	Filename        string `json:"filename"`
+	MimeType        string `json:"mime_type,omitempty"`
+	SizeBytes       int64  `json:"size_bytes"`
+	StorageRef      string `json:"storage_ref,omitempty"`
+	CreatedTimeUnix int64  `json:"created_time_unix,omitempty"`
+}
 
-			if runtimeID == "" {
-				// Keep provider/fallback choices in sync with the saved config so restarts preserve them.
-				if err := s.persistChatRouting(requestedProvider, requestedModel, requestedFallback); err != nil {
-					sendWSError(conn, fmt.Sprintf("persist chat routing failed: %v", err), clientSessionID)
-					continue
-				}
-			} else {
-				_ = s.setThreadRuntimeBinding(webUIChatSessionID(username), runtimeID)
-			}
+type sessionDetailResponse struct {
+	ID           string                   `json:"id"`
+	Channel      string                   `json:"channel"`
+	Source       string                   `json:"source"`
+	Archived     bool                     `json:"archived"`
+	CreatedAt    time.Time                `json:"created_at"`
+	UpdatedAt    time.Time                `json:"updated_at"`
+	Title        string                   `json:"title"`
+	Summary      string                   `json:"summary"`
+	MessageCount int                      `json:"message_count"`
+	RuntimeID    string                   `json:"runtime_id"`
+	Topic        string                   `json:"topic"`
+	Pins         []string                 `json:"pins"`
+	Messages     []sessionMessageResponse `json:"messages"`
+}
 
-			provider, model, fallback, explicitPromptIDs, err := s.resolveWebUIRuntimeSelection(
-				context.Background(),
-				runtimeID,
-				requestedProvider,
-				requestedModel,
-				requestedFallback,
-			)
-			if err != nil {
-				sendWSError(conn, fmt.Sprintf("runtime selection failed: %v", err), clientSessionID)
-				continue
-			}
-			if s.prompts != nil {
-				if _, err := s.prompts.ReplaceSessionBindings(
-					context.Background(),
-					sessionID,
-					msg.SystemPromptIDs,
-					msg.UserPromptIDs,
-				); err != nil {
-					sendWSError(conn, fmt.Sprintf("save session prompts failed: %v", err), clientSessionID)
-					continue
-				}
-			}
-			sess, err = s.getOrCreateChatSession(sessionID)
-			if err != nil {
-				sendWSError(conn, fmt.Sprintf("session error: %v", err), clientSessionID)
-				continue
-			}
+type threadSummaryResponse struct {
+	ID                  string    `json:"id"`
+	Target              string    `json:"target"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+	Summary             string    `json:"summary"`
+	MessageCount        int       `json:"message_count"`
+	RuntimeID           string    `json:"runtime_id"`
+	Topic               string    `json:"topic"`
+	NotificationRouteID string    `json:"notification_route_id,omitempty"`
+}
 
-			if s.agent != nil {
-				preview, err := s.agent.PreviewPreprocessedInput(content)
-				if err == nil && preview != nil && (len(preview.Mentions) > 0 || len(preview.Warnings) > 0) {
-					paths := make([]string, 0, len(preview.Mentions))
-					for _, mention := range preview.Mentions {
-						path := strings.TrimSpace(mention.Path)
-						if path == "" {
-							continue
-						}
-						if mention.StartLine > 0 && mention.EndLine >= mention.StartLine {
-							path = fmt.Sprintf("%s:%d-%d", path, mention.StartLine, mention.EndLine)
-						}
-						paths = append(paths, path)
-					}
-					feedback := fileMentionFeedback{
-						Count:    len(preview.Mentions),
-						Paths:    paths,
-						Warnings: append([]string(nil), preview.Warnings...),
-					}
-					systemText := fmt.Sprintf("Inlined %d file reference(s)", feedback.Count)
-					if len(feedback.Warnings) > 0 {
-						systemText = fmt.Sprintf("%s (%d warning(s))", systemText, len(feedback.Warnings))
-					}
-					if data, marshalErr := json.Marshal(chatWSResponse{
-						Type:      "system",
+type threadDetailResponse struct {
+	ID                  string                   `json:"id"`
+	Target              string                   `json:"target"`
+	CreatedAt           time.Time                `json:"created_at"`
+	UpdatedAt           time.Time                `json:"updated_at"`
+	Summary             string                   `json:"summary"`
+	MessageCount        int                      `json:"message_count"`
+	RuntimeID           string                   `json:"runtime_id"`
+	Topic               string                   `json:"topic"`
+	NotificationRouteID string                   `json:"notification_route_id,omitempty"`
+	Messages            []sessionMessageResponse `json:"messages"`
+}
+
+func buildSessionMessageResponses(messages []session.Message) []sessionMessageResponse {
+	respMessages := make([]sessionMessageResponse, len(messages))
+	for i, msg := range messages {
+		respMessages[i] = sessionMessageResponse{
+			ID:          msg.ID,
+			Role:        msg.Role,
+			Content:     msg.Content,
+			ToolCallID:  msg.ToolCallID,
+			Attachments: buildSessionAttachmentResponses(msg.Attachments),
+		}
+	}
+	return respMessages
+}
+
+func buildSessionAttachmentResponses(items []message.Attachment) []sessionAttachmentResponse {
+	if len(items) == 0 {
+		return nil
+	}
+	out := make([]sessionAttachmentResponse, 0, len(items))
+	for _, item := range items {
+		if strings.TrimSpace(item.AttachmentID) == "" {
+			continue
+		}
+		out = append(out, sessionAttachmentResponse{
+			AttachmentID:    item.AttachmentID,
+			Target:          item.Target,
+			OwnerID:         item.OwnerID,
+			Filename:        item.Filename,
+			MimeType:        item.MimeType,
+			SizeBytes:       item.SizeBytes,
+			StorageRef:      item.StorageRef,
+			CreatedTimeUnix: item.CreatedTimeUnix,
+		})
+	}
+	return out
+}
+
+func firstNonEmptyString(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+func sessionTarget(sessionID string) string {
+	sessionID = strings.TrimSpace(sessionID)
+	if strings.HasPrefix(sessionID, "dm_@") {
+		return "dm:" + strings.TrimPrefix(sessionID, "dm_")
+	}
+	if sessionID == "" {
+		return ""
+	}
+	return "#websocket:" + sessionID
+}
+
+// handleListSessions lists persisted sessions across channels, newest first.
+// Query parameters: channel (e.g. "telegram"), q (matches ID or title) and
+// archived ("true" lists only archived sessions, "all" lists both).
+func (s *Server) handleListSessions(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+
+	channel := strings.ToLower(strings.TrimSpace(c.QueryParam("channel")))
+	query := strings.ToLower(strings.TrimSpace(c.QueryParam("q")))
+	archivedFilter := strings.ToLower(strings.TrimSpace(c.QueryParam("archived")))
+	switch archivedFilter {
+	case "", "false", "true", "all":
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "archived must be true, false or all"})
+	}
+
+	ids, err := s.sessionMgr.ListKeys()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to list sessions: %v", err)})
+	}
+
+	summaries := make([]sessionSummaryResponse, 0, len(ids))
+	for _, id := range ids {
+		sess, err := s.sessionMgr.GetExisting(id)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session %q: %v", id, err)})
+		}
+		archived := sess.IsArchived()
+		if (archivedFilter == "true" && !archived) || ((archivedFilter == "" || archivedFilter == "false") && archived) {
+			continue
+		}
+		sessionChannel := sessionChannelName(sess.GetID(), sess.GetSource())
+		if channel != "" && sessionChannel != channel {
+			continue
+		}
+		title := sess.GetTitle()
+		if query != "" && !strings.Contains(strings.ToLower(sess.GetID()), query) && !strings.Contains(strings.ToLower(title), query) {
+			continue
+		}
+		messages := sess.GetMessages()
+		summaries = append(summaries, sessionSummaryResponse{
+			ID:           sess.GetID(),
+			Channel:      sessionChannel,
+			Source:       sess.GetSource(),
+			Archived:     archived,
+			CreatedAt:    sess.GetCreatedAt(),
+			UpdatedAt:    sess.GetUpdatedAt(),
+			Title:        title,
+			Summary:      sess.GetSummary(),
+			MessageCount: len(messages),
+			RuntimeID:    s.getThreadRuntimeBinding(id),
+			Topic:        s.getThreadTopic(id),
+		})
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].UpdatedAt.After(summaries[j].UpdatedAt)
+	})
+
+	return c.JSON(http.StatusOK, summaries)
+}
+
+// sessionChannelName returns the channel a session belongs to: the prefix of
+// IDs such as "telegram:123", otherwise the session source.
+func sessionChannelName(id, source string) string {
+	if prefix, _, ok := strings.Cut(id, ":"); ok && prefix != "" {
+		return strings.ToLower(prefix)
+	}
+	return strings.ToLower(strings.TrimSpace(source))
+}
+
+func (s *Server) handleGetSession(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+
+	sess, err := s.sessionMgr.GetExisting(id)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+
+	messages := sess.GetMessages()
+
+	resp := sessionDetailResponse{
+		ID:           sess.GetID(),
+		Channel:      sessionChannelName(sess.GetID(), sess.GetSource()),
+		Source:       sess.GetSource(),
+		Archived:     sess.IsArchived(),
+		CreatedAt:    sess.GetCreatedAt(),
+		UpdatedAt:    sess.GetUpdatedAt(),
+		Title:        sess.GetTitle(),
+		Summary:      sess.GetSummary(),
+		MessageCount: len(messages),
+		RuntimeID:    s.getThreadRuntimeBinding(id),
+		Topic:        s.getThreadTopic(id),
+		Pins:         sess.GetPins(),
+		Messages:     buildSessionMessageResponses(messages),
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// handleExportSession downloads a session transcript as JSONL or Markdown,
+// with the token usage recorded for it.
+func (s *Server) handleExportSession(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+	format, err := transcript.NormalizeFormat(c.QueryParam("format"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	sess, err := s.sessionMgr.LoadJSONL(id)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+	var records []usage.Record
+	if s.usageMgr != nil {
+		records, err = s.usageMgr.SessionRecords(c.Request().Context(), sess.Key)
+		if err != nil {
+			s.logger.Error("Failed to load session usage", zap.String("session", id), zap.Error(err))
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load session usage"})
+		}
+	}
+	data, err := transcript.Render(transcript.New(sess, records, time.Now()), format)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	c.Response().Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": transcript.FileName(id, format),
+	}))
+	return c.Blob(http.StatusOK, transcript.ContentType(format), data)
+}
+
+func (s *Server) handleUpdateSessionSummary(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+
+	var body struct {
+		Summary string `json:"summary"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	sess, err := s.sessionMgr.GetExisting(id)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+
+	sess.SetSummary(body.Summary)
+	if err := s.sessionMgr.Save(sess); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to save session summary: %v", err)})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"status": "updated"})
+}
+
+func (s *Server) handleUpdateSessionTitle(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+
+	var body struct {
+		Title string `json:"title"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	sess, err := s.sessionMgr.GetExisting(id)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+
+	// A manual title, including clearing it, always wins over auto titles.
+	sess.SetTitle(body.Title)
+	return c.JSON(http.StatusOK, map[string]string{"title": sess.GetTitle()})
+}
+
+// handleArchiveSession archives or restores a session.
+func (s *Server) handleArchiveSession(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+
+	var body struct {
+		Archived bool `json:"archived"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	sess, err := s.sessionMgr.GetExisting(id)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+
+	sess.SetArchived(body.Archived)
+	return c.JSON(http.StatusOK, map[string]bool{"archived": sess.IsArchived()})
+}
+
+// handleRetitleSession regenerates a session title with the auto-title model.
+// It works even when automatic titling is disabled.
+func (s *Server) handleRetitleSession(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+	if s.agent == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "agent not available"})
+	}
+
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+
+	sess, err := s.sessionMgr.GetExisting(id)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+
+	title, err := s.agent.GenerateSessionTitle(c.Request().Context(), sess)
+	if err != nil {
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": fmt.Sprintf("failed to generate title: %v", err)})
+	}
+	sess.SetTitle(title)
+	return c.JSON(http.StatusOK, map[string]string{"title": sess.GetTitle()})
+}
+
+func (s *Server) handleListSessionPins(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+	sess, err := s.sessionMgr.GetExisting(id)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusOK, map[string]interface{}{"pins": []string{}})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"pins": sess.GetPins()})
+}
+
+func (s *Server) handleAddSessionPin(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+	var body struct {
+		Text string `json:"text"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	if strings.TrimSpace(body.Text) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "text is required"})
+	}
+
+	// Pins may be added before the first chat turn, so create the session on demand.
+	sess, err := s.sessionMgr.GetWithSource(id, session.SourceWebUI)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+	added := sess.AddPin(body.Text)
+	return c.JSON(http.StatusOK, map[string]interface{}{"added": added, "pins": sess.GetPins()})
+}
+
+func (s *Server) handleDeleteSessionPin(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+	index, err := strconv.Atoi(strings.TrimSpace(c.Param("index")))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "pin index must be a number"})
+	}
+	sess, err := s.sessionMgr.GetExisting(id)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+	if _, ok := sess.RemovePin(index); !ok {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "pin not found"})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"pins": sess.GetPins()})
+}
+
+func (s *Server) handleClearSessionPins(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+	sess, err := s.sessionMgr.GetExisting(id)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+	sess.ClearPins()
+	return c.JSON(http.StatusOK, map[string]interface{}{"pins": []string{}})
+}
+
+func (s *Server) handleUpdateSessionRuntime(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+	if _, err := s.sessionMgr.GetExisting(id); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+	var body struct {
+		RuntimeID string `json:"runtime_id"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	if err := s.setThreadRuntimeBinding(id, body.RuntimeID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "updated"})
+}
+
+func (s *Server) handleUpdateSessionThread(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+	if _, err := s.sessionMgr.GetExisting(id); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+	var body struct {
+		Topic string `json:"topic"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	if err := s.setThreadTopic(id, body.Topic); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "updated"})
+}
+
+func (s *Server) handleDeleteSession(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session id is required"})
+	}
+
+	if _, err := s.sessionMgr.GetExisting(id); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+	if err := s.sessionMgr.Delete(id); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to delete session: %v", err)})
+	}
+	_ = s.deleteThread(id)
+	if s.prompts != nil {
+		if err := s.prompts.ClearSessionBindings(c.Request().Context(), id); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to delete session prompts: %v", err)})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func (s *Server) handleCleanupSessions(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+	if err := session.CleanupPersistedSessions(s.config, s.sessionMgr); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if s.prompts != nil {
+		listed, err := s.sessionMgr.List()
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("list sessions after cleanup: %v", err)})
+		}
+		active := make(map[string]struct{}, len(listed))
+		for _, item := range listed {
+			active[item] = struct{}{}
+		}
+		bindings, err := s.prompts.ListBindings(c.Request().Context(), prompts.ScopeSession, "")
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("list session prompt bindings: %v", err)})
+		}
+		for _, binding := range bindings {
+			if _, ok := active[binding.Target]; ok {
+				continue
+			}
+			if err := s.prompts.ClearSessionBindings(c.Request().Context(), binding.Target); err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("cleanup session prompts: %v", err)})
+			}
+		}
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "cleaned"})
+}
+
+func (s *Server) handleListThreads(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+
+	ids, err := s.sessionMgr.List()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to list threads: %v", err)})
+	}
+
+	items := make([]threadSummaryResponse, 0, len(ids))
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	for _, id := range ids {
+		sess, err := s.sessionMgr.GetExisting(id)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load thread %q: %v", id, err)})
+		}
+		messages := sess.GetMessages()
+		target := sessionTarget(sess.GetID())
+		items = append(items, threadSummaryResponse{
+			ID:                  sess.GetID(),
+			Target:              target,
+			CreatedAt:           sess.GetCreatedAt(),
+			UpdatedAt:           sess.GetUpdatedAt(),
+			Summary:             sess.GetSummary(),
+			MessageCount:        len(messages),
+			RuntimeID:           s.getThreadRuntimeBinding(id),
+			Topic:               s.getThreadTopic(id),
+			NotificationRouteID: s.getDefaultNotificationRouteID(ctx, notificationroutes.ScopeThread, target),
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].UpdatedAt.After(items[j].UpdatedAt)
+	})
+
+	return c.JSON(http.StatusOK, items)
+}
+
+func (s *Server) handleGetThread(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "thread id is required"})
+	}
+
+	sess, err := s.sessionMgr.GetExisting(id)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "thread not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load thread: %v", err)})
+	}
+
+	messages := sess.GetMessages()
+	target := sessionTarget(sess.GetID())
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	return c.JSON(http.StatusOK, threadDetailResponse{
+		ID:                  sess.GetID(),
+		Target:              target,
+		CreatedAt:           sess.GetCreatedAt(),
+		UpdatedAt:           sess.GetUpdatedAt(),
+		Summary:             sess.GetSummary(),
+		MessageCount:        len(messages),
+		RuntimeID:           s.getThreadRuntimeBinding(id),
+		Topic:               s.getThreadTopic(id),
+		NotificationRouteID: s.getDefaultNotificationRouteID(ctx, notificationroutes.ScopeThread, target),
+		Messages:            buildSessionMessageResponses(messages),
+	})
+}
+
+func (s *Server) handleUpdateThread(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "thread id is required"})
+	}
+	sess, err := s.sessionMgr.GetExisting(id)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "thread not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load thread: %v", err)})
+	}
+	var body struct {
+		Summary             string  `json:"summary"`
+		RuntimeID           string  `json:"runtime_id"`
+		Topic               string  `json:"topic"`
+		NotificationRouteID *string `json:"notification_route_id"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	if strings.TrimSpace(body.Summary) != "" || body.Summary == "" {
+		sess.SetSummary(body.Summary)
+		if err := s.sessionMgr.Save(sess); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to save thread summary: %v", err)})
+		}
+	}
+	if err := s.setThreadRuntimeBinding(id, body.RuntimeID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if err := s.setThreadTopic(id, body.Topic); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if s.notificationMgr != nil && body.NotificationRouteID != nil {
+		ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+		if _, err := s.bindDefaultNotificationRoute(ctx, notificationroutes.ScopeThread, sessionTarget(id), *body.NotificationRouteID, nil, nil); err != nil {
+			if errors.Is(err, ownership.ErrPermissionDenied) {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+			}
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "updated"})
+}
+
+func (s *Server) getDefaultNotificationRouteID(ctx context.Context, scope, target string) string {
+	if s == nil || s.notificationMgr == nil {
+		return ""
+	}
+	binding, err := s.notificationMgr.FindBindingForTarget(ctx, scope, target)
+	if err != nil || binding == nil || !binding.Enabled {
+		return ""
+	}
+	return binding.RouteID
+}
+
+// --- Status Handler ---
+
+func (s *Server) handleStatus(c *echo.Context) error {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	uptime := time.Since(s.startedAt)
+	configPath, err := s.resolveBootstrapConfigPath()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	runtimeDBPath, err := config.RuntimeDBDisplayName(s.config)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	workspaceStatus, err := workspace.NewManager(s.config.WorkspacePath(), s.logger).Inspect()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	taskSnapshots := s.listTaskSnapshots()
+	recentTasks, stateCounts := summarizeTasks(taskSnapshots, 5)
+	recentCronJobs := s.listRecentCronJobs(5)
+	runtimeStates, err := s.deriveRuntimeStatuses(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	sessionStates := s.listSessionStates()
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"version":                      version.GetVersion(),
+		"commit":                       version.GitCommit,
+		"build_time":                   version.BuildTime,
+		"os":                           runtime.GOOS,
+		"arch":                         runtime.GOARCH,
+		"go_version":                   runtime.Version(),
+		"pid":                          os.Getpid(),
+		"uptime":                       uptime.Round(time.Second).String(),
+		"uptime_seconds":               int64(uptime.Seconds()),
+		"memory_alloc_bytes":           mem.Alloc,
+		"memory_sys_bytes":             mem.Sys,
+		"provider_count":               len(s.config.Providers),
+		"config_path":                  configPath,
+		"runtime_db_type":              s.config.DatabaseType(),
+		"database_dir":                 s.config.Storage.DBDir,
+		"runtime_db_path":              runtimeDBPath,
+		"workspace_path":               s.config.Agents.Defaults.Workspace,
+		"workspace_contract":           workspaceStatus.Contract,
+		"workspace_validation_summary": workspaceStatus.ValidationSummary,
+		"task_count":                   len(taskSnapshots),
+		"task_state_counts":            stateCounts,
+		"recent_tasks":                 recentTasks,
+		"recent_cron_jobs":             recentCronJobs,
+		"runtime_states":               runtimeStates,
+		"daemon_machines": func() interface{} {
+			if s.kvStore == nil {
+				return []daemonhost.MachineStatus{}
+			}
+			snapshot, err := daemonhost.NewRegistry(s.kvStore).Snapshot(c.Request().Context())
+			if err != nil || snapshot == nil {
+				return []daemonhost.MachineStatus{}
+			}
+			return daemonhost.MachineStatuses(snapshot)
+		}(),
+		"session_runtime_states": sessionStates,
+		"agent_definition": func() interface{} {
+			if s.agent == nil {
+				return nil
+			}
+			return s.agent.Definition()
+		}(),
+		"backup": func() interface{} {
+			if s.backupMgr == nil {
+				return nil
+			}
+			return s.backupMgr.Status()
+		}(),
+		"workspace_backend": func() interface{} {
+			if s.workspaceSync == nil {
+				return nil
+			}
+			return s.workspaceSync.Status()
+		}(),
+		"gateway_host": s.config.Gateway.Host,
+		"gateway_port": s.config.Gateway.Port,
+		"gateway": map[string]interface{}{
+			"host": s.config.Gateway.Host,
+			"port": s.config.Gateway.Port,
+		},
+	})
+}
+
+func (s *Server) listTaskSnapshots() []tasks.Task {
+	if s == nil || s.taskStore == nil {
+		return nil
+	}
+	return s.taskStore.List()
+}
+
+func (s *Server) listRecentCronJobs(limit int) []cron.Job {
+	if s == nil || s.cronMgr == nil {
+		return []cron.Job{}
+	}
+
+	jobs := s.cronMgr.ListJobs()
+	if len(jobs) == 0 {
+		return []cron.Job{}
+	}
+
+	filtered := make([]cron.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if job == nil || job.LastRun.IsZero() {
+			continue
+		}
+		filtered = append(filtered, *job)
+	}
+	if len(filtered) == 0 {
+		return []cron.Job{}
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].LastRun.After(filtered[j].LastRun)
+	})
+	if limit > 0 && len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+	return filtered
+}
+
+func (s *Server) listSessionStates() []tasks.SessionState {
+	if s == nil || s.taskStore == nil {
+		return []tasks.SessionState{}
+	}
+	return s.taskStore.ListSessionStates()
+}
+
+func (s *Server) deriveRuntimeStatuses(ctx context.Context) ([]runtimeagents.AgentRuntime, error) {
+	if s == nil || s.runtimeMgr == nil {
+		return []runtimeagents.AgentRuntime{}, nil
+	}
+
+	items, err := s.runtimeMgr.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.attachRuntimeStatuses(ctx, items)
+}
+
+func (s *Server) attachRuntimeStatuses(ctx context.Context, items []runtimeagents.AgentRuntime) ([]runtimeagents.AgentRuntime, error) {
+	if len(items) == 0 {
+		return []runtimeagents.AgentRuntime{}, nil
+	}
+
+	bindingsByRuntimeID := make(map[string]int, len(items))
+	enabledBindingsByRuntimeID := make(map[string]int, len(items))
+	accountEnabledByID := map[string]bool{}
+	if s.accountMgr != nil {
+		accounts, err := s.accountMgr.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list channel accounts for runtime status: %w", err)
+		}
+		for _, account := range accounts {
+			accountEnabledByID[account.ID] = account.Enabled
+		}
+	}
+	if s.bindingMgr != nil {
+		bindings, err := s.bindingMgr.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list account bindings for runtime status: %w", err)
+		}
+		for _, binding := range bindings {
+			bindingsByRuntimeID[binding.AgentRuntimeID]++
+			if binding.Enabled && accountEnabledByID[binding.ChannelAccountID] {
+				enabledBindingsByRuntimeID[binding.AgentRuntimeID]++
+			}
+		}
+	}
+
+	tasksByRuntimeID := make(map[string]int, len(items))
+	lastSeenByRuntimeID := make(map[string]time.Time, len(items))
+	for _, task := range s.listTaskSnapshots() {
+		runtimeID := strings.TrimSpace(task.RuntimeID)
+		if runtimeID == "" {
+			continue
+		}
+		if !tasks.IsFinal(task.State) {
+			tasksByRuntimeID[runtimeID]++
+		}
+		taskTime := taskSortTime(task)
+		if taskTime.After(lastSeenByRuntimeID[runtimeID]) {
+			lastSeenByRuntimeID[runtimeID] = taskTime
+		}
+	}
+
+	result := make([]runtimeagents.AgentRuntime, 0, len(items))
+	for _, item := range items {
+		status := &runtimeagents.RuntimeDerivedStatus{
+			EffectiveAvailable:  item.Enabled && enabledBindingsByRuntimeID[item.ID] > 0,
+			BoundAccountCount:   bindingsByRuntimeID[item.ID],
+			EnabledBindingCount: enabledBindingsByRuntimeID[item.ID],
+			CurrentTaskCount:    tasksByRuntimeID[item.ID],
+			LastSeenAt:          runtimeagents.NormalizeTimestamp(lastSeenByRuntimeID[item.ID]),
+		}
+		switch {
+		case !item.Enabled:
+			status.AvailabilityReason = "runtime_disabled"
+		case enabledBindingsByRuntimeID[item.ID] == 0:
+			if bindingsByRuntimeID[item.ID] == 0 {
+				status.AvailabilityReason = "unbound"
+			} else {
+				status.AvailabilityReason = "no_enabled_bindings"
+			}
+		default:
+			status.AvailabilityReason = "available"
+		}
+		item.Status = status
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+func summarizeTasks(all []tasks.Task, limit int) ([]tasks.Task, map[string]int) {
+	stateCounts := make(map[string]int)
+	if len(all) == 0 {
+		return []tasks.Task{}, stateCounts
+	}
+
+	snapshots := append([]tasks.Task(nil), all...)
+	for _, task := range snapshots {
+		stateCounts[string(task.State)]++
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return taskSortTime(snapshots[i]).After(taskSortTime(snapshots[j]))
+	})
+	if limit > 0 && len(snapshots) > limit {
+		snapshots = snapshots[:limit]
+	}
+	return snapshots, stateCounts
+}
+
+func taskSortTime(task tasks.Task) time.Time {
+	switch {
+	case !task.CompletedAt.IsZero():
+		return task.CompletedAt
+	case !task.StartedAt.IsZero():
+		return task.StartedAt
+	default:
+		return task.CreatedAt
+	}
+}
+
+func (s *Server) handleServiceStatus(c *echo.Context) error {
+	if s.serviceCtrl == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "service control not available"})
+	}
+	gatewayStatus, err := s.serviceCtrl.Status()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	clientdStatus, err := s.serviceCtrl.NekoClientdStatus()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]any{"gateway": gatewayStatus, "nekoclientd": clientdStatus})
+}
+
+func (s *Server) handleServiceRestart(c *echo.Context) error {
+	if s.serviceCtrl == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "service control not available"})
+	}
+
+	if err := s.serviceCtrl.Restart(); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "restarting"})
+}
+
+func (s *Server) handleServiceReload(c *echo.Context) error {
+	if s.serviceCtrl == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "service control not available"})
+	}
+
+	if err := s.serviceCtrl.Reload(); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "reloaded"})
+}
+
+func (s *Server) handleGetWatchStatus(c *echo.Context) error {
+	if s.config == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "config unavailable"})
+	}
+
+	if s.watcher == nil {
+		return c.JSON(http.StatusOK, watch.StatusSnapshot{
+			Enabled:    s.config.Watch.Enabled,
+			Running:    false,
+			DebounceMs: s.config.Watch.DebounceMs,
+			Patterns:   append([]config.WatchPattern(nil), s.config.Watch.Patterns...),
+		})
+	}
+
+	return c.JSON(http.StatusOK, s.watcher.Status())
+}
+
+func (s *Server) handleUpdateWatchStatus(c *echo.Context) error {
+	if s.config == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "config unavailable"})
+	}
+
+	var body struct {
+		Enabled    *bool                 `json:"enabled"`
+		DebounceMs *int                  `json:"debounce_ms"`
+		Patterns   []config.WatchPattern `json:"patterns"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	before, _ := json.Marshal(s.config.Watch)
+	if body.Enabled != nil {
+		s.config.Watch.Enabled = *body.Enabled
+	}
+	if body.DebounceMs != nil {
+		s.config.Watch.DebounceMs = *body.DebounceMs
+	}
+	if body.Patterns != nil {
+		s.config.Watch.Patterns = append([]config.WatchPattern(nil), body.Patterns...)
+	}
+
+	if err := config.SaveDatabaseSections(s.config, "watch"); err != nil {
+		if s.logger != nil {
+			s.logger.Error("Failed to persist watch config", zap.Error(err))
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save watch config"})
+	}
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionConfigUpdate,
+		Target:  "watch",
+		Success: true,
+		Summary: audit.Diff(json.RawMessage(before), s.config.Watch),
+	})
+	if err := s.syncWatchRuntime(); err != nil {
+		if s.logger != nil {
+			s.logger.Error("Failed to sync watch runtime", zap.Error(err))
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to sync watch runtime"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":           "saved",
+		"restart_required": true,
+		"watch": func() watch.StatusSnapshot {
+			if s.watcher != nil {
+				return s.watcher.Status()
+			}
+			return watch.StatusSnapshot{
+				Enabled:    s.config.Watch.Enabled,
+				Running:    false,
+				DebounceMs: s.config.Watch.DebounceMs,
+				Patterns:   append([]config.WatchPattern(nil), s.config.Watch.Patterns...),
+			}
+		}(),
+	})
+}
+
+// recordAudit stores a configuration or security event on behalf of the
+// request's user. Failures are logged and never fail the request.
+func (s *Server) recordAudit(c *echo.Context, event audit.Event) {
+	if s.entClient == nil {
+		return
+	}
+	events, err := audit.NewEventStore(s.entClient)
+	if err != nil {
+		return
+	}
+	authCtx := s.authContextFromEcho(c)
+	if event.Actor == "" {
+		event.Actor = s.currentUsername(c)
+	}
+	if event.ActorID == "" {
+		event.ActorID = authCtx.UserID
+	}
+	if event.TenantID == "" {
+		event.TenantID = authCtx.TenantID
+	}
+	event.IP = c.RealIP()
+	if err := events.Record(c.Request().Context(), event); err != nil && s.logger != nil {
+		s.logger.Warn("Failed to record audit event", zap.String("action", event.Action), zap.Error(err))
+	}
+}
+
+// auditEventFilter reads /api/audit query parameters. Users outside the
+// default tenant only see their own tenant's events.
+func (s *Server) auditEventFilter(c *echo.Context) (audit.EventFilter, error) {
+	filter := audit.EventFilter{
+		Action:   c.QueryParam("action"),
+		Actor:    c.QueryParam("actor"),
+		Target:   c.QueryParam("target"),
+		TenantID: c.QueryParam("tenant_id"),
+	}
+	if authCtx := s.authContextFromEcho(c); !authCtx.SpansTenants() {
+		filter.TenantID = authCtx.TenantID
+	}
+	for name, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		raw := strings.TrimSpace(c.QueryParam(name))
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return audit.EventFilter{}, fmt.Errorf("invalid %s: must be RFC3339", name)
+		}
+		*dst = parsed
+	}
+	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			return audit.EventFilter{}, fmt.Errorf("invalid limit")
+		}
+		filter.Limit = parsed
+	}
+	return filter, nil
+}
+
+func (s *Server) listAuditEvents(c *echo.Context, filter audit.EventFilter) ([]audit.Event, error) {
+	events, err := audit.NewEventStore(s.entClient)
+	if err != nil {
+		return nil, err
+	}
+	return events.List(c.Request().Context(), filter)
+}
+
+func (s *Server) handleListAuditEvents(c *echo.Context) error {
+	filter, err := s.auditEventFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	events, err := s.listAuditEvents(c, filter)
+	if err != nil {
+		s.logger.Error("Failed to list audit events", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list audit events"})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"events": events,
+		"count":  len(events),
+	})
+}
+
+func (s *Server) handleExportAuditEvents(c *echo.Context) error {
+	filter, err := s.auditEventFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if filter.Limit == 0 {
+		filter.Limit = audit.MaxEventLimit
+	}
+	events, err := s.listAuditEvents(c, filter)
+	if err != nil {
+		s.logger.Error("Failed to export audit events", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to export audit events"})
+	}
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+	res.Header().Set("Content-Disposition", `attachment; filename="nekobot-audit.jsonl"`)
+	res.WriteHeader(http.StatusOK)
+	return audit.WriteJSONL(res, events)
+}
+
+func (s *Server) handleGetHarnessAudit(c *echo.Context) error {
+	if s.auditLogger == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "audit log unavailable"})
+	}
+
+	limit := 100
+	if rawLimit := strings.TrimSpace(c.QueryParam("limit")); rawLimit != "" {
+		parsed, err := strconv.Atoi(rawLimit)
+		if err != nil || parsed <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid limit"})
+		}
+		if parsed > 500 {
+			parsed = 500
+		}
+		limit = parsed
+	}
+
+	entries, err := s.auditLogger.ReadLast(limit)
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Error("Failed to read audit log", zap.Error(err))
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to read audit log"})
+	}
+
+	stats, err := s.auditLogger.Stats()
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Error("Failed to read audit stats", zap.Error(err))
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to read audit stats"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"entries": entries,
+		"stats":   stats,
+		"limit":   limit,
+	})
+}
+
+func (s *Server) handleClearHarnessAudit(c *echo.Context) error {
+	if s.auditLogger == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "audit log unavailable"})
+	}
+
+	if err := s.auditLogger.Clear(); err != nil && !os.IsNotExist(err) {
+		if s.logger != nil {
+			s.logger.Error("Failed to clear audit log", zap.Error(err))
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to clear audit log"})
+	}
+
+	stats, err := s.auditLogger.Stats()
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Error("Failed to refresh audit stats after clear", zap.Error(err))
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to read audit stats"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "cleared",
+		"stats":  stats,
+	})
+}
+
+// --- Chat WebSocket Playground ---
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// wsUpgraderWithCompression negotiates per-message deflate when the client offers it.
+var wsUpgraderWithCompression = websocket.Upgrader{
+	ReadBufferSize:    4096,
+	WriteBufferSize:   4096,
+	CheckOrigin:       func(r *http.Request) bool { return true },
+	EnableCompression: true,
+}
+
+// upgradeWebSocket upgrades chat and tool WebSockets, enabling compression
+// unless webui.websocket_compression is turned off.
+func (s *Server) upgradeWebSocket(c *echo.Context) (*websocket.Conn, error) {
+	upgrader := &wsUpgrader
+	if s.config == nil || s.config.WebUI.WebSocketCompression {
+		upgrader = &wsUpgraderWithCompression
+	}
+	return upgrader.Upgrade(c.Response(), c.Request(), nil)
+}
+
+const (
+	defaultChatWSMaxMessageBytes = 65536
+	// chatWSHardLimitFactor bounds how much of an oversized message is drained
+	// before the connection is dropped instead of answered with an error frame.
+	chatWSHardLimitFactor = 16
+)
+
+// chatWSMaxMessageBytes returns the largest chat message the playground accepts.
+func (s *Server) chatWSMaxMessageBytes() int64 {
+	if s.config == nil || s.config.WebUI.ChatMaxMessageBytes <= 0 {
+		return defaultChatWSMaxMessageBytes
+	}
+	return int64(s.config.WebUI.ChatMaxMessageBytes)
+}
+
+// readChatWSMessage reads one message of at most limit bytes. A larger message
+// is drained and reported as tooLarge so the caller can answer with an error
+// frame and keep the connection open.
+func readChatWSMessage(conn *websocket.Conn, limit int64) (data []byte, tooLarge bool, err error) {
+	_, reader, err := conn.NextReader()
+	if err != nil {
+		return nil, false, err
+	}
+	data, err = io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(data)) <= limit {
+		return data, false, nil
+	}
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		return nil, true, err
+	}
+	return nil, true, nil
+}
+
+type chatWSMessage struct {
+	Type            string   `json:"type"`                        // "message", "ping", "clear"
+	Content         string   `json:"content"`                     // User message text
+	Model           string   `json:"model"`                       // Optional model override
+	Provider        string   `json:"provider,omitempty"`          // Optional provider override
+	Fallback        []string `json:"fallback,omitempty"`          // Optional fallback provider order
+	SystemPromptIDs []string `json:"system_prompt_ids,omitempty"` // Optional session prompt overlays
+	UserPromptIDs   []string `json:"user_prompt_ids,omitempty"`   // Optional session prompt overlays
+	RuntimeID       string   `json:"runtime_id,omitempty"`        // Optional explicit runtime selection
+	Orchestrator    string   `json:"orchestrator,omitempty"`      // Optional per-turn orchestrator override
+	Stream          bool     `json:"stream,omitempty"`            // Send "delta" and "tool" events while the reply is generated
+	AttachmentIDs   []string `json:"attachment_ids,omitempty"`    // Images uploaded via /api/chat/attachments
+}
+
+type chatWSResponse struct {
+	Type      string          `json:"type"`                 // "message", "delta", "tool", "thinking", "error", "system", "pong", "route_result"
+	Content   string          `json:"content"`              // Response text
+	Thinking  string          `json:"thinking,omitempty"`   // Model's thinking (if extended thinking enabled)
+	Timestamp int64           `json:"timestamp,omitempty"`  // Unix timestamp
+	SessionID string          `json:"session_id,omitempty"` // Routed chat session
+	Route     *chatRouteState `json:"route,omitempty"`
+	Debug     *chatDebugState `json:"debug,omitempty"` // Developer mode payload on route_result
+	Meta      interface{}     `json:"meta,omitempty"`
+}
+
+type chatDebugState struct {
+	Requests []chatDebugRequest `json:"requests"`
+}
+
+type chatDebugRequest struct {
+	Provider string                   `json:"provider"`
+	Model    string                   `json:"model"`
+	Request  providers.UnifiedRequest `json:"request"`
+}
+
+func buildChatDebugState(debug *agent.TurnDebug) *chatDebugState {
+	if debug == nil {
+		return nil
+	}
+	state := &chatDebugState{Requests: make([]chatDebugRequest, 0, len(debug.Requests))}
+	for _, req := range debug.Requests {
+		state.Requests = append(state.Requests, chatDebugRequest{
+			Provider: req.Provider,
+			Model:    req.Model,
+			Request:  req.Request,
+		})
+	}
+	return state
+}
+
+type fileMentionFeedback struct {
+	Count    int      `json:"count"`
+	Paths    []string `json:"paths,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type chatRouteSettings struct {
+	Provider string   `json:"provider"`
+	Model    string   `json:"model"`
+	Fallback []string `json:"fallback"`
+}
+
+type chatRouteState struct {
+	RequestedProvider     string                   `json:"requested_provider"`
+	RequestedModel        string                   `json:"requested_model"`
+	RequestedFallback     []string                 `json:"requested_fallback"`
+	ResolvedOrder         []string                 `json:"resolved_order"`
+	ActualProvider        string                   `json:"actual_provider"`
+	ActualModel           string                   `json:"actual_model"`
+	Preflight             *chatRoutePreflightState `json:"preflight,omitempty"`
+	ContextBudgetStatus   string                   `json:"context_budget_status,omitempty"`
+	ContextBudgetReasons  []string                 `json:"context_budget_reasons,omitempty"`
+	CompactionRecommended bool                     `json:"compaction_recommended,omitempty"`
+	CompactionStrategy    string                   `json:"compaction_strategy,omitempty"`
+	RuntimeID             string                   `json:"runtime_id,omitempty"`
+	Orchestrator          string                   `json:"orchestrator,omitempty"`
+}
+
+type chatRoutePreflightState struct {
+	Action        string                   `json:"action,omitempty"`
+	Applied       bool                     `json:"applied"`
+	BudgetStatus  string                   `json:"budget_status,omitempty"`
+	BudgetReasons []string                 `json:"budget_reasons,omitempty"`
+	Compaction    chatRouteCompactionState `json:"compaction"`
+}
+
+type chatRouteCompactionState struct {
+	Recommended         bool     `json:"recommended"`
+	Strategy            string   `json:"strategy,omitempty"`
+	Reasons             []string `json:"reasons,omitempty"`
+	EstimatedCharsSaved int      `json:"estimated_chars_saved,omitempty"`
+}
+
+func buildChatRouteWSResponse(sessionID, runtimeID string, routeResult agent.ChatRouteResult) chatWSResponse {
+	return chatWSResponse{
+		Type:      "route_result",
+		Timestamp: time.Now().Unix(),
+		SessionID: strings.TrimSpace(sessionID),
+		Route: &chatRouteState{
+			RequestedProvider: routeResult.RequestedProvider,
+			RequestedModel:    routeResult.RequestedModel,
+			RequestedFallback: append([]string(nil), routeResult.RequestedFallback...),
+			ResolvedOrder:     append([]string(nil), routeResult.ResolvedOrder...),
+			ActualProvider:    routeResult.ActualProvider,
+			ActualModel:       routeResult.ActualModel,
+			Preflight: &chatRoutePreflightState{
+				Action:        routeResult.Preflight.Action,
+				Applied:       routeResult.Preflight.Applied,
+				BudgetStatus:  routeResult.Preflight.BudgetStatus,
+				BudgetReasons: append([]string(nil), routeResult.Preflight.BudgetReasons...),
+				Compaction: chatRouteCompactionState{
+					Recommended:         routeResult.Preflight.Compaction.Recommended,
+					Strategy:            routeResult.Preflight.Compaction.Strategy,
+					Reasons:             append([]string(nil), routeResult.Preflight.Compaction.Reasons...),
+					EstimatedCharsSaved: routeResult.Preflight.Compaction.EstimatedCharsSaved,
+				},
+			},
+			ContextBudgetStatus:   routeResult.ContextBudgetStatus,
+			ContextBudgetReasons:  append([]string(nil), routeResult.ContextBudgetReasons...),
+			CompactionRecommended: routeResult.CompactionRecommended,
+			CompactionStrategy:    routeResult.CompactionStrategy,
+			RuntimeID:             runtimeID,
+			Orchestrator:          routeResult.Orchestrator,
+		},
+		Debug: buildChatDebugState(routeResult.Debug),
+	}
+}
+
+type toolWSMessage struct {
+	Type string `json:"type"` // "input", "ping", "kill", "resize"
+	Data string `json:"data,omitempty"`
+	Cols int    `json:"cols,omitempty"`
+	Rows int    `json:"rows,omitempty"`
+}
+
+type toolWSResponse struct {
+	Type      string `json:"type"`                 // "ready", "output", "status", "error", "pong"
+	SessionID string `json:"session_id,omitempty"` // for ready
+	Data      string `json:"data,omitempty"`       // terminal output
+	Total     int    `json:"total,omitempty"`      // output chunk cursor
+	Running   bool   `json:"running,omitempty"`
+	ExitCode  int    `json:"exit_code,omitempty"`
+	Missing   bool   `json:"missing,omitempty"`
+	ReadOnly  bool   `json:"read_only,omitempty"` // spectator connection
+	Message   string `json:"message,omitempty"`
+}
+
+func webUIChatSessionID(username string) string {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return "webui-chat"
+	}
+	return "webui-chat:" + username
+}
+
+func webUIRuntimeChatSessionID(username, runtimeID string) string {
+	return runtimeChatSessionID(webUIChatSessionID(username), runtimeID)
+}
+
+func runtimeChatSessionID(baseSessionID, runtimeID string) string {
+	runtimeID = strings.TrimSpace(runtimeID)
+	if runtimeID == "" {
+		return baseSessionID
+	}
+	return inboundrouter.SessionPrefix + ":" + runtimeID + ":" + baseSessionID
+}
+
+// chatSessionID returns the stored playground session of username for
+// runtimeID. Users linked with /link continue their linked identity's
+// session instead of their own.
+func (s *Server) chatSessionID(ctx context.Context, username, runtimeID string) string {
+	baseSessionID := webUIChatSessionID(username)
+	if username = strings.TrimSpace(username); s.prefs != nil && username != "" {
+		baseSessionID = s.prefs.ResolveSessionID(ctx, webUIChatLinkChannel, username, baseSessionID)
+	}
+	return runtimeChatSessionID(baseSessionID, runtimeID)
+}
+
+func webUIClientChatSessionID(runtimeID string) string {
+	runtimeID = strings.TrimSpace(runtimeID)
+	if runtimeID == "" {
+		return "webui-chat"
+	}
+	return inboundrouter.SessionPrefix + ":" + runtimeID + ":webui-chat"
+}
+
+func buildWebUIChatPromptContext(
+	sessionID string,
+	username string,
+	provider string,
+	model string,
+	fallback []string,
+	explicitPromptIDs []string,
+	runtimeID string,
+) agent.PromptContext {
+	promptCtx := agent.PromptContext{
+		Channel:           session.SourceWebUI,
+		SessionID:         strings.TrimSpace(sessionID),
+		UserID:            strings.TrimSpace(username),
+		Username:          strings.TrimSpace(username),
+		RequestedProvider: strings.TrimSpace(provider),
+		RequestedModel:    strings.TrimSpace(model),
+		RequestedFallback: append([]string(nil), fallback...),
+		ExplicitPromptIDs: append([]string(nil), explicitPromptIDs...),
+	}
+	if strings.TrimSpace(runtimeID) != "" {
+		promptCtx.Custom = map[string]any{
+			"runtime_id": strings.TrimSpace(runtimeID),
+		}
+	}
+	return promptCtx
+}
+
+func (s *Server) resolveWebUIRuntimeSelection(
+	ctx context.Context,
+	runtimeID string,
+	provider string,
+	model string,
+	fallback []string,
+) (string, string, []string, []string, error) {
+	runtimeID = strings.TrimSpace(runtimeID)
+	if runtimeID == "" {
+		provider = strings.TrimSpace(provider)
+		if ac, ok := ownership.AuthContextFromContext(ctx); ok {
+			if err := s.validateChatRouting(ac, provider, fallback); err != nil {
+				return "", "", nil, nil, err
+			}
+		}
+		return provider, strings.TrimSpace(model), append([]string(nil), fallback...), nil, nil
+	}
+	if s == nil || s.runtimeMgr == nil {
+		return "", "", nil, nil, fmt.Errorf("runtime manager not available")
+	}
+	if s.accountMgr == nil || s.bindingMgr == nil {
+		return "", "", nil, nil, fmt.Errorf("chat runtime topology is not available")
+	}
+
+	runtimeItem, err := s.runtimeMgr.Get(ctx, runtimeID)
+	if err != nil {
+		return "", "", nil, nil, fmt.Errorf("get runtime %s: %w", runtimeID, err)
+	}
+	if runtimeItem == nil || !runtimeItem.Enabled {
+		return "", "", nil, nil, fmt.Errorf("runtime %s is not available", runtimeID)
+	}
+	websocketAccount, err := s.accountMgr.FindByChannelTypeAndAccountKey(ctx, "websocket", "default")
+	if err != nil {
+		return "", "", nil, nil, fmt.Errorf("runtime %s is not available for websocket chat", runtimeID)
+	}
+	if websocketAccount == nil || !websocketAccount.Enabled {
+		return "", "", nil, nil, fmt.Errorf("runtime %s is not available for websocket chat", runtimeID)
+	}
+	bindings, err := s.bindingMgr.ListEnabledByChannelAccountID(ctx, websocketAccount.ID)
+	if err != nil {
+		return "", "", nil, nil, fmt.Errorf("list websocket bindings: %w", err)
+	}
+	bound := false
+	for _, binding := range bindings {
+		if strings.TrimSpace(binding.AgentRuntimeID) == runtimeID {
+			bound = true
+			break
+		}
+	}
+	if !bound {
+		return "", "", nil, nil, fmt.Errorf("runtime %s is not available for websocket chat", runtimeID)
+	}
+
+	return strings.TrimSpace(runtimeItem.Provider),
+		strings.TrimSpace(runtimeItem.Model),
+		nil,
+		runtimePromptIDs(runtimeItem.PromptID),
+		nil
+}
+
+func (s *Server) handleChatWS(c *echo.Context) error {
+	// Authenticate via token query param (since WebSocket can't use Authorization header easily)
+	tokenStr := c.QueryParam("token")
+	if tokenStr == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "token required"})
+	}
+	claims, err := s.parseScopedStreamTokenClaims(tokenStr, streamTokenPurposeChatWS)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+	}
+	username := claims.Subject
+	authCtx := claims.authContext()
+	authCtxBG := ownership.WithAuthContext(context.Background(), authCtx)
+
+	// Upgrade to WebSocket
+	conn, err := s.upgradeWebSocket(c)
+	if err != nil {
+		s.logger.Error("WebUI chat WS upgrade failed", zap.Error(err))
+		return nil
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	baseSessionID := s.chatSessionID(context.Background(), username, "")
+	baseClientSessionID := webUIClientChatSessionID("")
+	sess, err := s.getOrCreateChatSession(baseSessionID)
+	if err != nil {
+		sendWSError(conn, fmt.Sprintf("session error: %v", err), baseClientSessionID)
+		return nil
+	}
+
+	// Send welcome
+	welcome := chatWSResponse{
+		Type:      "system",
+		Content:   "Connected to chat playground",
+		Timestamp: time.Now().Unix(),
+		SessionID: baseClientSessionID,
+	}
+	if data, err := json.Marshal(welcome); err == nil {
+		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			s.logger.Warn("Failed to send chat welcome", zap.Error(err))
+		}
+	}
+	routing := s.chatRouting(authCtx)
+	if data, err := json.Marshal(chatWSResponse{
+		Type:      "routing",
+		Content:   mustMarshalChatRouting(routing),
+		Timestamp: time.Now().Unix(),
+		SessionID: baseClientSessionID,
+	}); err == nil {
+		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			s.logger.Warn("Failed to send chat routing", zap.Error(err))
+		}
+	}
+
+	// Read loop
+	maxMessageBytes := s.chatWSMaxMessageBytes()
+	conn.SetReadLimit(maxMessageBytes * chatWSHardLimitFactor)
+	if err := conn.SetReadDeadline(time.Now().Add(120 * time.Second)); err != nil {
+		s.logger.Warn("Failed to set chat read deadline", zap.Error(err))
+	}
+	conn.SetPongHandler(func(string) error {
+		if err := conn.SetReadDeadline(time.Now().Add(120 * time.Second)); err != nil {
+			s.logger.Warn("Failed to refresh chat read deadline", zap.Error(err))
+		}
+		return nil
+	})
+
+	// Ping ticker
+	pingDone := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := conn.SetWriteDeadline(time.Now().Add(10 * time.Second)); err != nil {
+					s.logger.Warn("Failed to set chat ping deadline", zap.Error(err))
+				}
+				if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+					return
+				}
+			case <-pingDone:
+				return
+			}
+		}
+	}()
+	defer close(pingDone)
+
+	for {
+		message, tooLarge, err := readChatWSMessage(conn, maxMessageBytes)
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				s.logger.Warn("WebUI chat WS read error", zap.Error(err))
+			}
+			return nil
+		}
+		if tooLarge {
+			sendWSError(conn, fmt.Sprintf("message too large: the limit is %d bytes", maxMessageBytes), baseClientSessionID)
+			continue
+		}
+
+		var msg chatWSMessage
+		if err := json.Unmarshal(message, &msg); err != nil {
+			sendWSError(conn, "invalid message format", baseClientSessionID)
+			continue
+		}
+
+		switch msg.Type {
+		case "ping":
+			resp := chatWSResponse{
+				Type:      "pong",
+				Timestamp: time.Now().Unix(),
+				SessionID: baseClientSessionID,
+			}
+			if data, err := json.Marshal(resp); err == nil {
+				if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+					s.logger.Warn("Failed to send chat pong", zap.Error(err))
+				}
+			}
+
+		case "clear":
+			clearSessionID := s.chatSessionID(context.Background(), username, msg.RuntimeID)
+			clearClientSessionID := webUIClientChatSessionID(msg.RuntimeID)
+			if err := s.clearChatSession(clearSessionID); err != nil {
+				sendWSError(conn, fmt.Sprintf("session reset failed: %v", err), clearClientSessionID)
+				continue
+			}
+			sess, err = s.sessionMgr.GetExisting(clearSessionID)
+			if err != nil {
+				sendWSError(conn, fmt.Sprintf("session error: %v", err), clearClientSessionID)
+				continue
+			}
+			resp := chatWSResponse{
+				Type:      "system",
+				Content:   "Session cleared",
+				Timestamp: time.Now().Unix(),
+				SessionID: clearClientSessionID,
+			}
+			if data, err := json.Marshal(resp); err == nil {
+				if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+					s.logger.Warn("Failed to send chat cleared event", zap.Error(err))
+				}
+			}
+
+		case "message":
+			content := strings.TrimSpace(msg.Content)
+			if content == "" && len(msg.AttachmentIDs) == 0 {
+				continue
+			}
+			attachments, err := s.takeChatUploads(username, msg.AttachmentIDs)
+			if err != nil {
+				sendWSError(conn, err.Error(), webUIClientChatSessionID(strings.TrimSpace(msg.RuntimeID)))
+				continue
+			}
+			runtimeID := strings.TrimSpace(msg.RuntimeID)
+			if runtimeID == "" {
+				runtimeID = s.getThreadRuntimeBinding(webUIChatSessionID(username))
+			}
+			sessionID := s.chatSessionID(context.Background(), username, runtimeID)
+			clientSessionID := webUIClientChatSessionID(runtimeID)
+			requestedModel := strings.TrimSpace(msg.Model)
+			requestedProvider := strings.TrimSpace(msg.Provider)
+			requestedFallback := normalizeProviderNames(msg.Fallback)
+			requestedOrchestrator := ""
+			if strings.TrimSpace(msg.Orchestrator) != "" {
+				requestedOrchestrator, err = agent.NormalizeOrchestrator(msg.Orchestrator)
+				if err != nil {
+					sendWSError(conn, err.Error(), clientSessionID)
+					continue
+				}
+			}
+
+			if runtimeID == "" {
+				// Keep provider/fallback choices in sync with the saved config so restarts preserve them.
+				if err := s.persistChatRouting(authCtx, requestedProvider, requestedModel, requestedFallback); err != nil {
+					sendWSError(conn, fmt.Sprintf("persist chat routing failed: %v", err), clientSessionID)
+					continue
+				}
+			} else {
+				_ = s.setThreadRuntimeBinding(webUIChatSessionID(username), runtimeID)
+			}
+
+			provider, model, fallback, explicitPromptIDs, err := s.resolveWebUIRuntimeSelection(
+				authCtxBG,
+				runtimeID,
+				requestedProvider,
+				requestedModel,
+				requestedFallback,
+			)
+			if err != nil {
+				sendWSError(conn, fmt.Sprintf("runtime selection failed: %v", err), clientSessionID)
+				continue
+			}
+			if s.prompts != nil {
+				if _, err := s.prompts.ReplaceSessionBindings(
+					context.Background(),
+					sessionID,
+					msg.SystemPromptIDs,
+					msg.UserPromptIDs,
+				); err != nil {
+					sendWSError(conn, fmt.Sprintf("save session prompts failed: %v", err), clientSessionID)
+					continue
+				}
+			}
+			sess, err = s.getOrCreateChatSession(sessionID)
+			if err != nil {
+				sendWSError(conn, fmt.Sprintf("session error: %v", err), clientSessionID)
+				continue
+			}
+
+			if s.agent != nil {
+				preview, err := s.agent.PreviewPreprocessedInput(content)
+				if err == nil && preview != nil && (len(preview.Mentions) > 0 || len(preview.Warnings) > 0) {
+					paths := make([]string, 0, len(preview.Mentions))
+					for _, mention := range preview.Mentions {
+						path := strings.TrimSpace(mention.Path)
+						if path == "" {
+							continue
+						}
+						if mention.StartLine > 0 && mention.EndLine >= mention.StartLine {
+							path = fmt.Sprintf("%s:%d-%d", path, mention.StartLine, mention.EndLine)
+						}
+						paths = append(paths, path)
+					}
+					feedback := fileMentionFeedback{
+						Count:    len(preview.Mentions),
+						Paths:    paths,
+						Warnings: append([]string(nil), preview.Warnings...),
+					}
+					systemText := fmt.Sprintf("Inlined %d file reference(s)", feedback.Count)
+					if len(feedback.Warnings) > 0 {
+						systemText = fmt.Sprintf("%s (%d warning(s))", systemText, len(feedback.Warnings))
+					}
+					if data, marshalErr := json.Marshal(chatWSResponse{
+						Type:      "system",
 						Content:   systemText,
 						Timestamp: time.Now().Unix(),
 						SessionID: clientSessionID,
@@ -8400,1248 +11317,2562 @@ func (s *Server) handleChatWS(c *echo.Context) error {
 				}
 			}
 
-			// Add user message to session
-			sess.AddMessage(agent.Message{
-				Role:    "user",
-				Content: content,
-			})
-			s.dispatchWebChatNotification(context.Background(), authCtx, username, runtimeID, sessionID, "user", content)
+			// Add user message to session
+			sess.AddMessage(agent.Message{
+				Role:    "user",
+				Content: content,
+			})
+			s.dispatchWebChatNotification(context.Background(), authCtx, username, runtimeID, sessionID, "user", content)
+
+			if daemonHandled, daemonReply, daemonErr := s.handleDaemonRuntimeChatMessage(
+				context.Background(),
+				username,
+				runtimeID,
+				sessionID,
+				content,
+			); daemonHandled {
+				if daemonErr != nil {
+					sendWSError(conn, fmt.Sprintf("daemon task error: %v", daemonErr), clientSessionID)
+					continue
+				}
+				sess.AddMessage(agent.Message{
+					Role:    "assistant",
+					Content: daemonReply,
+				})
+				s.dispatchWebChatNotification(context.Background(), authCtx, username, runtimeID, sessionID, "assistant", daemonReply)
+				resp := chatWSResponse{
+					Type:      "message",
+					Content:   daemonReply,
+					Timestamp: time.Now().Unix(),
+					SessionID: clientSessionID,
+				}
+				if data, err := json.Marshal(resp); err == nil {
+					if err := conn.SetWriteDeadline(time.Now().Add(30 * time.Second)); err != nil {
+						s.logger.Warn("Failed to set daemon chat response deadline", zap.Error(err))
+					}
+					if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+						s.logger.Warn("Failed to send daemon chat response", zap.Error(err))
+					}
+				}
+				continue
+			}
+
+			// Process with agent.
+			promptCtx := buildWebUIChatPromptContext(sessionID, username, provider, model, fallback, explicitPromptIDs, runtimeID)
+			promptCtx.Orchestrator = requestedOrchestrator
+			promptCtx.Attachments = attachments
+			if msg.Stream {
+				promptCtx.Stream, promptCtx.ToolProgress = s.chatTurnStreamCallbacks(clientSessionID, func(resp chatWSResponse) {
+					writeChatWSEvent(s.logger, conn, resp)
+				})
+			}
+			response, routeResult, err := s.agent.ChatWithPromptContextDetailed(
+				agent.WithTenant(context.Background(), authCtx.TenantSlug),
+				sess,
+				content,
+				promptCtx,
+			)
+			if err != nil {
+				routeResp := buildChatRouteWSResponse(clientSessionID, runtimeID, routeResult)
+				if data, marshalErr := json.Marshal(routeResp); marshalErr == nil {
+					if err := conn.SetWriteDeadline(time.Now().Add(30 * time.Second)); err != nil {
+						s.logger.Warn("Failed to set chat route deadline", zap.Error(err))
+					}
+					if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+						s.logger.Warn("Failed to send chat route result", zap.Error(err))
+					}
+				}
+				sendWSError(conn, fmt.Sprintf("agent error: %v", err), clientSessionID)
+				continue
+			}
+
+			response = s.responseFilters.Apply("websocket", response)
+
+			// Add assistant response to session
+			sess.AddMessage(agent.Message{
+				Role:    "assistant",
+				Content: response,
+			})
+			s.dispatchWebChatNotification(context.Background(), authCtx, username, runtimeID, sessionID, "assistant", response)
+
+			resp := chatWSResponse{
+				Type:      "message",
+				Content:   response,
+				Timestamp: time.Now().Unix(),
+				SessionID: clientSessionID,
+				Meta:      map[string]interface{}{"message_index": len(sess.GetMessages()) - 1},
+			}
+			if data, err := json.Marshal(resp); err == nil {
+				if err := conn.SetWriteDeadline(time.Now().Add(30 * time.Second)); err != nil {
+					s.logger.Warn("Failed to set chat response deadline", zap.Error(err))
+				}
+				if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+					s.logger.Warn("Failed to send chat response", zap.Error(err))
+				}
+			}
+
+			routeResp := buildChatRouteWSResponse(clientSessionID, runtimeID, routeResult)
+			if data, err := json.Marshal(routeResp); err == nil {
+				if err := conn.SetWriteDeadline(time.Now().Add(30 * time.Second)); err != nil {
+					s.logger.Warn("Failed to set chat route deadline", zap.Error(err))
+				}
+				if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+					s.logger.Warn("Failed to send chat route result", zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+// handleChatStream runs one chat turn and streams it as server-sent events,
+// for clients that cannot use the chat WebSocket. Every event carries the
+// chatWSResponse JSON: "delta" and "tool" while the reply is generated,
+// then "message" and "route_result", or "error".
+func (s *Server) handleChatStream(c *echo.Context) error {
+	tokenStr := strings.TrimSpace(c.QueryParam("token"))
+	if tokenStr == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "token required"})
+	}
+	claims, err := s.parseScopedStreamTokenClaims(tokenStr, streamTokenPurposeChatStream)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+	}
+	username := claims.Subject
+	authCtx := claims.authContext()
+
+	content := strings.TrimSpace(c.QueryParam("message"))
+	if content == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "message required"})
+	}
+	if s.agent == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "agent not available"})
+	}
+	requestedOrchestrator := ""
+	if raw := strings.TrimSpace(c.QueryParam("orchestrator")); raw != "" {
+		requestedOrchestrator, err = agent.NormalizeOrchestrator(raw)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+
+	ctx := ownership.WithAuthContext(c.Request().Context(), authCtx)
+	runtimeID := strings.TrimSpace(c.QueryParam("runtime_id"))
+	provider, model, fallback, explicitPromptIDs, err := s.resolveWebUIRuntimeSelection(
+		ctx,
+		runtimeID,
+		c.QueryParam("provider"),
+		c.QueryParam("model"),
+		normalizeProviderNames(strings.Split(c.QueryParam("fallback"), ",")),
+	)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("runtime selection failed: %v", err)})
+	}
+	sessionID := s.chatSessionID(c.Request().Context(), username, runtimeID)
+	clientSessionID := webUIClientChatSessionID(runtimeID)
+	sess, err := s.getOrCreateChatSession(sessionID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("session error: %v", err)})
+	}
+
+	res := c.Response()
+	flusher, ok := res.(http.Flusher)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "streaming unsupported"})
+	}
+	res.Header().Set("Content-Type", "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	emit := func(resp chatWSResponse) {
+		payload, err := json.Marshal(resp)
+		if err != nil {
+			return
+		}
+		_, _ = res.Write([]byte("data: "))
+		_, _ = res.Write(payload)
+		_, _ = res.Write([]byte("\n\n"))
+		flusher.Flush()
+	}
+	sendError := func(message string) {
+		emit(chatWSResponse{Type: "error", Content: message, Timestamp: time.Now().Unix(), SessionID: clientSessionID})
+	}
+
+	sess.AddMessage(agent.Message{Role: "user", Content: content})
+	s.dispatchWebChatNotification(ctx, authCtx, username, runtimeID, sessionID, "user", content)
+
+	if daemonHandled, daemonReply, daemonErr := s.handleDaemonRuntimeChatMessage(ctx, username, runtimeID, sessionID, content); daemonHandled {
+		if daemonErr != nil {
+			sendError(fmt.Sprintf("daemon task error: %v", daemonErr))
+			return nil
+		}
+		sess.AddMessage(agent.Message{Role: "assistant", Content: daemonReply})
+		s.dispatchWebChatNotification(ctx, authCtx, username, runtimeID, sessionID, "assistant", daemonReply)
+		emit(chatWSResponse{Type: "message", Content: daemonReply, Timestamp: time.Now().Unix(), SessionID: clientSessionID})
+		return nil
+	}
+
+	promptCtx := buildWebUIChatPromptContext(sessionID, username, provider, model, fallback, explicitPromptIDs, runtimeID)
+	promptCtx.Orchestrator = requestedOrchestrator
+	promptCtx.Stream, promptCtx.ToolProgress = s.chatTurnStreamCallbacks(clientSessionID, emit)
+	response, routeResult, err := s.agent.ChatWithPromptContextDetailed(agent.WithTenant(ctx, authCtx.TenantSlug), sess, content, promptCtx)
+	if err != nil {
+		emit(buildChatRouteWSResponse(clientSessionID, runtimeID, routeResult))
+		sendError(fmt.Sprintf("agent error: %v", err))
+		return nil
+	}
+
+	response = s.responseFilters.Apply("websocket", response)
+	sess.AddMessage(agent.Message{Role: "assistant", Content: response})
+	s.dispatchWebChatNotification(ctx, authCtx, username, runtimeID, sessionID, "assistant", response)
+	emit(chatWSResponse{
+		Type:      "message",
+		Content:   response,
+		Timestamp: time.Now().Unix(),
+		SessionID: clientSessionID,
+		Meta:      map[string]interface{}{"message_index": len(sess.GetMessages()) - 1},
+	})
+	emit(buildChatRouteWSResponse(clientSessionID, runtimeID, routeResult))
+	return nil
+}
+
+// chatTurnStreamCallbacks turns the live output of a chat turn into events.
+// "delta" carries reply text added since the previous delta; meta.reset
+// means the text starts over, as happens after tool calls or when a
+// response filter rewrites earlier text. "tool" reports a tool call starting
+// or finishing. The final "message" replaces the deltas.
+func (s *Server) chatTurnStreamCallbacks(clientSessionID string, emit func(chatWSResponse)) (agent.StreamFunc, agent.ToolProgressFunc) {
+	var mu sync.Mutex
+	sent := ""
+	restart := false
+
+	stream := func(text string) {
+		text = s.responseFilters.Apply("websocket", text)
+		mu.Lock()
+		defer mu.Unlock()
+		resp := chatWSResponse{Type: "delta", Timestamp: time.Now().Unix(), SessionID: clientSessionID}
+		if restart || !strings.HasPrefix(text, sent) {
+			resp.Content = text
+			resp.Meta = map[string]interface{}{"reset": true}
+			restart = false
+		} else {
+			resp.Content = text[len(sent):]
+			if resp.Content == "" {
+				return
+			}
+		}
+		sent = text
+		emit(resp)
+	}
+	progress := func(p agent.ToolProgress) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !p.Done && sent != "" {
+			restart = true
+		}
+		meta := map[string]interface{}{"id": p.ID, "name": p.Name, "done": p.Done}
+		if p.Error != "" {
+			meta["error"] = p.Error
+		}
+		emit(chatWSResponse{
+			Type:      "tool",
+			Content:   p.Name,
+			Timestamp: time.Now().Unix(),
+			SessionID: clientSessionID,
+			Meta:      meta,
+		})
+	}
+	return stream, progress
+}
+
+func writeChatWSEvent(log *logger.Logger, conn *websocket.Conn, resp chatWSResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	if err := conn.SetWriteDeadline(time.Now().Add(30 * time.Second)); err != nil {
+		log.Warn("Failed to set chat stream deadline", zap.Error(err))
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		log.Debug("Failed to send chat stream event", zap.Error(err))
+	}
+}
+
+func (s *Server) dispatchWebChatNotification(
+	ctx context.Context,
+	authCtx ownership.AuthContext,
+	username string,
+	runtimeID string,
+	sessionID string,
+	role string,
+	content string,
+) {
+	if s == nil || s.notificationDispatch == nil || strings.TrimSpace(content) == "" {
+		return
+	}
+	if authCtx.UserID != "" {
+		ctx = ownership.WithAuthContext(ctx, authCtx)
+	}
+	threadTarget := sessionTarget(sessionID)
+	scope := notificationroutes.ScopeChannel
+	target := "#websocket"
+	if s.getDefaultNotificationRouteID(ctx, notificationroutes.ScopeThread, threadTarget) != "" {
+		scope = notificationroutes.ScopeThread
+		target = threadTarget
+	}
+	s.notificationDispatch.HandleWebMessageEvent(ctx, notificationroutes.WebMessageEvent{
+		Scope:     scope,
+		Target:    target,
+		EventType: notificationroutes.EventWebMessage,
+		Role:      role,
+		Content:   content,
+		SessionID: sessionID,
+		ThreadID:  threadTarget,
+		Username:  username,
+		RuntimeID: runtimeID,
+		Topic:     s.getThreadTopic(sessionID),
+		CreatedAt: time.Now(),
+	})
+}
+
+func (s *Server) handleDaemonRuntimeChatMessage(
+	ctx context.Context,
+	username, runtimeID, sessionID, content string,
+) (bool, string, error) {
+	if s == nil || s.runtimeMgr == nil || s.agent == nil || s.agent.TaskService() == nil {
+		return false, "", nil
+	}
+	runtimeID = strings.TrimSpace(runtimeID)
+	if runtimeID == "" {
+		return false, "", nil
+	}
+	runtimeItem, err := s.runtimeMgr.Get(ctx, runtimeID)
+	if err != nil || runtimeItem == nil {
+		return false, "", nil
+	}
+	if !runtimeIsDaemonBacked(runtimeItem) {
+		return false, "", nil
+	}
+	machineID, _ := runtimeItem.Policy["daemon_computer_id"].(string)
+	workspaceID, _ := runtimeItem.Policy["daemon_workspace_id"].(string)
+	if strings.TrimSpace(machineID) == "" {
+		return false, "", fmt.Errorf("daemon-backed runtime %s is missing daemon_computer_id policy", runtimeID)
+	}
+	taskID := "daemon-task-" + uuid.NewString()
+	_, err = s.agent.TaskService().Enqueue(tasks.Task{
+		ID:        taskID,
+		Type:      tasks.TypeRemoteAgent,
+		Summary:   content,
+		SessionID: sessionID,
+		RuntimeID: runtimeID,
+		Metadata: map[string]any{
+			"computer_id":        strings.TrimSpace(machineID),
+			"workspace_id":       strings.TrimSpace(workspaceID),
+			"created_by_user_id": strings.TrimSpace(username),
+			"delivery":           "daemon",
+		},
+	})
+	if err != nil {
+		return true, "", err
+	}
+	return true, fmt.Sprintf("Daemon task queued.\nTask ID: %s\nRuntime: %s\nMachine: %s", taskID, runtimeID, machineID), nil
+}
+
+func runtimeIsDaemonBacked(item *runtimeagents.AgentRuntime) bool {
+	if item == nil {
+		return false
+	}
+	if item.Policy == nil {
+		return false
+	}
+	value, _ := item.Policy["daemon_enabled"].(bool)
+	return value
+}
+
+func (s *Server) getOrCreateChatSession(sessionID string) (agent.SessionInterface, error) {
+	if s.sessionMgr == nil {
+		return nil, fmt.Errorf("session manager not available")
+	}
+	return s.sessionMgr.GetWithSource(sessionID, session.SourceWebUI)
+}
+
+func (s *Server) handleToolSessionWS(c *echo.Context) error {
+	if s.processMgr == nil || s.toolSess == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
+	}
+
+	sessionID := strings.TrimSpace(c.QueryParam("session_id"))
+	if sessionID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session_id required"})
+	}
+
+	// Spectators stream the output with the token of their link; they
+	// cannot type, resize or kill the session.
+	spectatorToken := strings.TrimSpace(c.QueryParam("spectator_token"))
+	readOnly := spectatorToken != ""
+	if readOnly {
+		if _, err := s.toolSess.VerifySpectatorAccess(c.Request().Context(), sessionID, spectatorToken); err != nil {
+			switch {
+			case errors.Is(err, os.ErrNotExist):
+				return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+			case errors.Is(err, os.ErrPermission):
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or revoked spectator link"})
+			default:
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			}
+		}
+	} else {
+		tokenStr := strings.TrimSpace(c.QueryParam("token"))
+		if tokenStr == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "token required"})
+		}
+		username, scopedSessionID, _, err := s.parseScopedStreamToken(tokenStr, streamTokenPurposeToolSessionWS)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+		}
+		sess, err := s.toolSess.GetSession(c.Request().Context(), sessionID)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		if scopedSessionID != "" && scopedSessionID != sessionID {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "session does not belong to current token"})
+		}
+		if strings.TrimSpace(sess.Owner) != "" && sess.Owner != username {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "session does not belong to current user"})
+		}
+	}
+	s.tryRestoreToolSessionRuntime(c.Request().Context(), sessionID)
+
+	conn, err := s.upgradeWebSocket(c)
+	if err != nil {
+		s.logger.Error("Tool session WS upgrade failed", zap.Error(err))
+		return nil
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	if readOnly {
+		defer s.toolSess.AddViewer(sessionID)()
+	}
+
+	var writeMu sync.Mutex
+	writeJSON := func(v interface{}) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if err := conn.SetWriteDeadline(time.Now().Add(10 * time.Second)); err != nil {
+			s.logger.Warn("Failed to set tool session WS write deadline", zap.Error(err))
+		}
+		return conn.WriteJSON(v)
+	}
+
+	if err := writeJSON(toolWSResponse{
+		Type:      "ready",
+		SessionID: sessionID,
+		Running:   true,
+		ReadOnly:  readOnly,
+	}); err != nil {
+		s.logger.Warn("Failed to write tool session websocket ready event",
+			zap.String("session_id", sessionID),
+			zap.Error(err),
+		)
+	}
+
+	conn.SetReadLimit(65536)
+	if err := conn.SetReadDeadline(time.Now().Add(120 * time.Second)); err != nil {
+		s.logger.Warn("Failed to set tool session WS read deadline", zap.Error(err))
+	}
+	conn.SetPongHandler(func(string) error {
+		if err := conn.SetReadDeadline(time.Now().Add(120 * time.Second)); err != nil {
+			s.logger.Warn("Failed to refresh tool session WS read deadline", zap.Error(err))
+		}
+		return nil
+	})
+
+	done := make(chan struct{})
+	var doneOnce sync.Once
+	markDone := func() {
+		doneOnce.Do(func() { close(done) })
+	}
+	defer markDone()
+
+	go func() {
+		defer markDone()
+		for {
+			_, payload, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			var msg toolWSMessage
+			if err := json.Unmarshal(payload, &msg); err != nil {
+				if writeErr := writeJSON(toolWSResponse{Type: "error", Message: "invalid message format"}); writeErr != nil {
+					s.logger.Warn("Failed to write invalid-format tool websocket error",
+						zap.String("session_id", sessionID),
+						zap.Error(writeErr),
+					)
+				}
+				continue
+			}
+			if readOnly && msg.Type != "ping" {
+				if err := writeJSON(toolWSResponse{Type: "error", Message: "spectators have read-only access"}); err != nil {
+					s.logger.Warn("Failed to write read-only tool websocket error",
+						zap.String("session_id", sessionID),
+						zap.Error(err),
+					)
+				}
+				continue
+			}
+			switch msg.Type {
+			case "ping":
+				if err := writeJSON(toolWSResponse{Type: "pong"}); err != nil {
+					s.logger.Warn("Failed to write tool websocket pong",
+						zap.String("session_id", sessionID),
+						zap.Error(err),
+					)
+				}
+			case "kill":
+				if s.processMgr != nil {
+					if err := s.processMgr.Kill(sessionID); err != nil && !isProcessSessionNotFound(err) && !strings.Contains(strings.ToLower(err.Error()), "not running") {
+						s.logger.Warn("Failed to kill tool websocket session process",
+							zap.String("session_id", sessionID),
+							zap.Error(err),
+						)
+					}
+				}
+				s.tryKillRuntimeSession(context.Background(), sessionID)
+				if err := s.toolSess.TerminateSession(context.Background(), sessionID, "killed from tool ws"); err != nil {
+					s.logger.Warn("Failed to terminate tool websocket session",
+						zap.String("session_id", sessionID),
+						zap.Error(err),
+					)
+				}
+			case "resize":
+				if msg.Cols <= 0 || msg.Rows <= 0 {
+					continue
+				}
+				if err := s.processMgr.Resize(sessionID, msg.Cols, msg.Rows); err != nil && isProcessSessionNotFound(err) {
+					if s.tryRestoreToolSessionRuntime(context.Background(), sessionID) {
+						if retryErr := s.processMgr.Resize(sessionID, msg.Cols, msg.Rows); retryErr != nil {
+							s.logger.Warn("Failed to resize restored tool websocket session",
+								zap.String("session_id", sessionID),
+								zap.Int("cols", msg.Cols),
+								zap.Int("rows", msg.Rows),
+								zap.Error(retryErr),
+							)
+						}
+					}
+				}
+			case "input":
+				if msg.Data == "" {
+					continue
+				}
+				if err := s.processMgr.Write(sessionID, msg.Data); err != nil {
+					if isProcessSessionNotFound(err) && s.tryRestoreToolSessionRuntime(context.Background(), sessionID) {
+						if retryErr := s.processMgr.Write(sessionID, msg.Data); retryErr == nil {
+							if touchErr := s.toolSess.TouchSession(context.Background(), sessionID, toolsessions.StateRunning); touchErr != nil {
+								s.logger.Warn("Failed to touch restored websocket tool session",
+									zap.String("session_id", sessionID),
+									zap.Error(touchErr),
+								)
+							}
+							continue
+						} else {
+							err = retryErr
+						}
+					}
+					if writeErr := writeJSON(toolWSResponse{Type: "error", Message: err.Error()}); writeErr != nil {
+						s.logger.Warn("Failed to write tool websocket error response",
+							zap.String("session_id", sessionID),
+							zap.Error(writeErr),
+						)
+					}
+					continue
+				}
+				if err := s.toolSess.TouchSession(context.Background(), sessionID, toolsessions.StateRunning); err != nil {
+					s.logger.Warn("Failed to touch websocket tool session after input",
+						zap.String("session_id", sessionID),
+						zap.Error(err),
+					)
+				}
+			}
+		}
+	}()
+
+	offset := 0
+	lastRunning := true
+	lastExit := 0
+	lastMissing := false
+	statusInit := false
+
+	ticker := time.NewTicker(220 * time.Millisecond)
+	defer ticker.Stop()
+
+	pingTicker := time.NewTicker(30 * time.Second)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-pingTicker.C:
+			writeMu.Lock()
+			if err := conn.SetWriteDeadline(time.Now().Add(10 * time.Second)); err != nil {
+				writeMu.Unlock()
+				return nil
+			}
+			err := conn.WriteMessage(websocket.PingMessage, nil)
+			writeMu.Unlock()
+			if err != nil {
+				return nil
+			}
+		case <-ticker.C:
+			chunks, total, outErr := s.processMgr.GetOutput(sessionID, offset, 500)
+			if outErr == nil {
+				offset = total
+				if len(chunks) > 0 {
+					if err := writeJSON(toolWSResponse{
+						Type:  "output",
+						Data:  strings.Join(chunks, ""),
+						Total: total,
+					}); err != nil {
+						s.logger.Warn("Failed to write tool websocket output",
+							zap.String("session_id", sessionID),
+							zap.Error(err),
+						)
+					}
+				}
+			}
+
+			status, statusErr := s.processMgr.GetStatus(sessionID)
+			if statusErr != nil {
+				missing := isProcessSessionNotFound(statusErr)
+				// The process is gone, e.g. after a restart with the native
+				// transport; show what it printed before.
+				if missing && !statusInit {
+					if history, err := s.processMgr.Scrollback(sessionID); err == nil && history != "" {
+						if err := writeJSON(toolWSResponse{Type: "output", Data: history}); err != nil {
+							s.logger.Warn("Failed to write tool websocket scrollback",
+								zap.String("session_id", sessionID),
+								zap.Error(err),
+							)
+						}
+					}
+				}
+				if !statusInit || missing != lastMissing {
+					if err := writeJSON(toolWSResponse{
+						Type:    "status",
+						Running: false,
+						Missing: missing,
+					}); err != nil {
+						s.logger.Warn("Failed to write missing tool websocket status",
+							zap.String("session_id", sessionID),
+							zap.Error(err),
+						)
+					}
+					statusInit = true
+					lastMissing = missing
+				}
+				continue
+			}
+
+			if status.Running {
+				if err := s.toolSess.TouchSession(context.Background(), sessionID, toolsessions.StateRunning); err != nil {
+					s.logger.Warn("Failed to touch websocket tool session from status poll",
+						zap.String("session_id", sessionID),
+						zap.Error(err),
+					)
+				}
+			} else {
+				if rec, err := s.toolSess.GetSession(context.Background(), sessionID); err == nil &&
+					rec.State != toolsessions.StateTerminated &&
+					rec.State != toolsessions.StateArchived {
+					if err := s.toolSess.TerminateSession(context.Background(), sessionID, fmt.Sprintf("process exited with code %d", status.ExitCode)); err != nil {
+						s.logger.Warn("Failed to terminate websocket tool session after exit",
+							zap.String("session_id", sessionID),
+							zap.Int("exit_code", status.ExitCode),
+							zap.Error(err),
+						)
+					}
+				}
+			}
+
+			if !statusInit || status.Running != lastRunning || status.ExitCode != lastExit || lastMissing {
+				if err := writeJSON(toolWSResponse{
+					Type:     "status",
+					Running:  status.Running,
+					ExitCode: status.ExitCode,
+					Missing:  false,
+					Message:  status.LimitExceeded,
+				}); err != nil {
+					s.logger.Warn("Failed to write websocket tool status",
+						zap.String("session_id", sessionID),
+						zap.Error(err),
+					)
+				}
+				statusInit = true
+				lastRunning = status.Running
+				lastExit = status.ExitCode
+				lastMissing = false
+			}
+		}
+	}
+}
+
+func sendWSError(conn *websocket.Conn, errMsg string, sessionID ...string) {
+	targetSessionID := ""
+	if len(sessionID) > 0 {
+		targetSessionID = strings.TrimSpace(sessionID[0])
+	}
+	resp := chatWSResponse{
+		Type:      "error",
+		Content:   errMsg,
+		Timestamp: time.Now().Unix(),
+		SessionID: targetSessionID,
+	}
+	if data, err := json.Marshal(resp); err == nil {
+		_ = conn.WriteMessage(websocket.TextMessage, data)
+	}
+}
 
-			if daemonHandled, daemonReply, daemonErr := s.handleDaemonRuntimeChatMessage(
-				context.Background(),
-				username,
-				runtimeID,
-				sessionID,
-				content,
-			); daemonHandled {
-				if daemonErr != nil {
-					sendWSError(conn, fmt.Sprintf("daemon task error: %v", daemonErr), clientSessionID)
-					continue
-				}
-				sess.AddMessage(agent.Message{
-					Role:    "assistant",
-					Content: daemonReply,
-				})
-				s.dispatchWebChatNotification(context.Background(), authCtx, username, runtimeID, sessionID, "assistant", daemonReply)
-				resp := chatWSResponse{
-					Type:      "message",
-					Content:   daemonReply,
-					Timestamp: time.Now().Unix(),
-					SessionID: clientSessionID,
-				}
-				if data, err := json.Marshal(resp); err == nil {
-					if err := conn.SetWriteDeadline(time.Now().Add(30 * time.Second)); err != nil {
-						s.logger.Warn("Failed to set daemon chat response deadline", zap.Error(err))
-					}
-					if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-						s.logger.Warn("Failed to send daemon chat response", zap.Error(err))
-					}
-				}
-				continue
+func normalizeProviderNames(names []string) []string {
+	if len(names) == 0 {
+		return []string{}
+	}
+	seen := make(map[string]struct{}, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		trimmed := strings.TrimSpace(name)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		result = append(result, trimmed)
+	}
+	return result
+}
+
+func (s *Server) hasProvider(name string) bool {
+	for _, p := range s.config.Providers {
+		if strings.TrimSpace(p.Name) == name && providerHasRequiredAuthFields(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func providerHasRequiredAuthFields(profile config.ProviderProfile) bool {
+	meta, ok := providerregistry.Get(strings.ToLower(strings.TrimSpace(profile.ProviderKind)))
+	if !ok {
+		return true
+	}
+	for _, field := range meta.AuthFields {
+		if !field.Required {
+			continue
+		}
+		switch field.Key {
+		case "api_key":
+			if strings.TrimSpace(profile.APIKey) == "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *Server) hasProviderGroup(name string) bool {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return false
+	}
+	for _, group := range s.config.Agents.Defaults.ProviderGroups {
+		if strings.TrimSpace(group.Name) == trimmed {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) hasRoutingTarget(name string) bool {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return false
+	}
+	return s.hasProvider(trimmed) || s.hasProviderGroup(trimmed)
+}
+
+// hasTenantRoutingTarget is hasRoutingTarget limited to providers of the
+// caller's tenant. Outside the default tenant a provider group qualifies only
+// when all of its members belong to the tenant.
+func (s *Server) hasTenantRoutingTarget(ac ownership.AuthContext, name string) bool {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return false
+	}
+	if s.hasTenantProvider(ac, trimmed) {
+		return true
+	}
+	if ac.InTenant("") {
+		return s.hasProviderGroup(trimmed)
+	}
+	for _, group := range s.config.Agents.Defaults.ProviderGroups {
+		if strings.TrimSpace(group.Name) != trimmed {
+			continue
+		}
+		for _, member := range group.Members {
+			if !s.hasTenantProvider(ac, strings.TrimSpace(member)) {
+				return false
+			}
+		}
+		return len(group.Members) > 0
+	}
+	return false
+}
+
+func (s *Server) hasTenantProvider(ac ownership.AuthContext, name string) bool {
+	for _, p := range s.config.Providers {
+		if strings.TrimSpace(p.Name) == name && ac.InTenant(p.TenantID) && providerHasRequiredAuthFields(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// chatRouting returns the saved chat routing of the caller's tenant.
+func (s *Server) chatRouting(ac ownership.AuthContext) chatRouteSettings {
+	if ac.InTenant("") {
+		return chatRouteSettings{
+			Provider: strings.TrimSpace(s.config.Agents.Defaults.Provider),
+			Model:    strings.TrimSpace(s.config.Agents.Defaults.Model),
+			Fallback: append([]string(nil), s.config.Agents.Defaults.Fallback...),
+		}
+	}
+	routing := s.config.Agents.TenantRouting[strings.TrimSpace(ac.TenantSlug)]
+	return chatRouteSettings{
+		Provider: strings.TrimSpace(routing.Provider),
+		Model:    strings.TrimSpace(routing.Model),
+		Fallback: append([]string(nil), routing.Fallback...),
+	}
+}
+
+// validateChatRouting checks that the provider and fallback targets exist in
+// the caller's tenant.
+func (s *Server) validateChatRouting(ac ownership.AuthContext, provider string, fallback []string) error {
+	if provider != "" && !s.hasTenantRoutingTarget(ac, provider) {
+		return fmt.Errorf("routing target not found: %s", provider)
+	}
+	for _, name := range fallback {
+		if !s.hasTenantRoutingTarget(ac, name) {
+			return fmt.Errorf("fallback routing target not found: %s", name)
+		}
+	}
+	return nil
+}
+
+// persistChatRouting saves the chat routing of the caller's tenant. The
+// default tenant keeps it in agents.defaults, other tenants in
+// agents.tenant_routing.
+func (s *Server) persistChatRouting(ac ownership.AuthContext, provider, model string, fallback []string) error {
+	if err := s.validateChatRouting(ac, provider, fallback); err != nil {
+		return err
+	}
+	model = strings.TrimSpace(model)
+
+	changed := false
+	if ac.InTenant("") {
+		if strings.TrimSpace(s.config.Agents.Defaults.Provider) != provider {
+			s.config.Agents.Defaults.Provider = provider
+			changed = true
+		}
+		if s.config.Agents.Defaults.Model != model {
+			s.config.Agents.Defaults.Model = model
+			changed = true
+		}
+		if !reflect.DeepEqual(s.config.Agents.Defaults.Fallback, fallback) {
+			s.config.Agents.Defaults.Fallback = fallback
+			changed = true
+		}
+	} else {
+		slug := strings.TrimSpace(ac.TenantSlug)
+		next := config.ChatRoutingConfig{Provider: provider, Model: model, Fallback: fallback}
+		if current, ok := s.config.Agents.TenantRouting[slug]; !ok || !reflect.DeepEqual(current, next) {
+			if s.config.Agents.TenantRouting == nil {
+				s.config.Agents.TenantRouting = make(map[string]config.ChatRoutingConfig)
 			}
+			s.config.Agents.TenantRouting[slug] = next
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if err := config.ValidateConfig(s.config); err != nil {
+		return err
+	}
+
+	return config.SaveDatabaseSections(s.config, "agents")
+}
+
+func mustMarshalChatRouting(routing chatRouteSettings) string {
+	data, err := json.Marshal(routing)
+	if err != nil {
+		return `{"provider":"","model":"","fallback":[]}`
+	}
+	return string(data)
+}
+
+func (s *Server) handleListPrompts(c *echo.Context) error {
+	if s.prompts == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+	}
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	items, err := s.prompts.ListPrompts(ctx)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+func (s *Server) handleCreatePrompt(c *echo.Context) error {
+	if s.prompts == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+	}
+	var body prompts.Prompt
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	item, err := s.prompts.CreatePrompt(ctx, body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+func (s *Server) handleUpdatePrompt(c *echo.Context) error {
+	if s.prompts == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+	}
+	var body prompts.Prompt
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	item, err := s.prompts.UpdatePrompt(ctx, c.Param("id"), body)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, prompts.ErrPromptNotFound) {
+			status = http.StatusNotFound
+		}
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			status = http.StatusForbidden
+		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, item)
+}
 
-			// Process with agent.
-			response, routeResult, err := s.agent.ChatWithPromptContextDetailed(
-				context.Background(),
-				sess,
-				content,
-				buildWebUIChatPromptContext(sessionID, username, provider, model, fallback, explicitPromptIDs, runtimeID),
-			)
-			if err != nil {
-				routeResp := buildChatRouteWSResponse(clientSessionID, runtimeID, routeResult)
-				if data, marshalErr := json.Marshal(routeResp); marshalErr == nil {
-					if err := conn.SetWriteDeadline(time.Now().Add(30 * time.Second)); err != nil {
-						s.logger.Warn("Failed to set chat route deadline", zap.Error(err))
-					}
-					if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-						s.logger.Warn("Failed to send chat route result", zap.Error(err))
-					}
-				}
-				sendWSError(conn, fmt.Sprintf("agent error: %v", err), clientSessionID)
-				continue
-			}
+func (s *Server) handleDeletePrompt(c *echo.Context) error {
+	if s.prompts == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+	}
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	err := s.prompts.DeletePrompt(ctx, c.Param("id"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, prompts.ErrPromptNotFound) {
+			status = http.StatusNotFound
+		}
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			status = http.StatusForbidden
+		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
 
-			// Add assistant response to session
-			sess.AddMessage(agent.Message{
-				Role:    "assistant",
-				Content: response,
-			})
-			s.dispatchWebChatNotification(context.Background(), authCtx, username, runtimeID, sessionID, "assistant", response)
+func (s *Server) handleListPromptBindings(c *echo.Context) error {
+	if s.prompts == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+	}
+	items, err := s.prompts.ListBindings(
+		c.Request().Context(),
+		c.QueryParam("scope"),
+		c.QueryParam("target"),
+	)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, items)
+}
 
-			resp := chatWSResponse{
-				Type:      "message",
-				Content:   response,
-				Timestamp: time.Now().Unix(),
-				SessionID: clientSessionID,
-			}
-			if data, err := json.Marshal(resp); err == nil {
-				if err := conn.SetWriteDeadline(time.Now().Add(30 * time.Second)); err != nil {
-					s.logger.Warn("Failed to set chat response deadline", zap.Error(err))
-				}
-				if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-					s.logger.Warn("Failed to send chat response", zap.Error(err))
-				}
-			}
+func (s *Server) handleCreatePromptBinding(c *echo.Context) error {
+	if s.prompts == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+	}
+	var body prompts.Binding
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	item, err := s.prompts.CreateBinding(c.Request().Context(), body)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, prompts.ErrPromptNotFound) {
+			status = http.StatusNotFound
+		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, item)
+}
 
-			routeResp := buildChatRouteWSResponse(clientSessionID, runtimeID, routeResult)
-			if data, err := json.Marshal(routeResp); err == nil {
-				if err := conn.SetWriteDeadline(time.Now().Add(30 * time.Second)); err != nil {
-					s.logger.Warn("Failed to set chat route deadline", zap.Error(err))
-				}
-				if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-					s.logger.Warn("Failed to send chat route result", zap.Error(err))
-				}
-			}
+func (s *Server) handleUpdatePromptBinding(c *echo.Context) error {
+	if s.prompts == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+	}
+	var body prompts.Binding
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	item, err := s.prompts.UpdateBinding(c.Request().Context(), c.Param("id"), body)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, prompts.ErrPromptNotFound) || errors.Is(err, prompts.ErrBindingNotFound) {
+			status = http.StatusNotFound
 		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, item)
 }
 
-func (s *Server) dispatchWebChatNotification(
-	ctx context.Context,
-	authCtx ownership.AuthContext,
-	username string,
-	runtimeID string,
-	sessionID string,
-	role string,
-	content string,
-) {
-	if s == nil || s.notificationDispatch == nil || strings.TrimSpace(content) == "" {
-		return
+func (s *Server) handleDeletePromptBinding(c *echo.Context) error {
+	if s.prompts == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
 	}
-	if authCtx.UserID != "" {
-		ctx = ownership.WithAuthContext(ctx, authCtx)
+	err := s.prompts.DeleteBinding(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, prompts.ErrBindingNotFound) {
+			status = http.StatusNotFound
+		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
 	}
-	threadTarget := sessionTarget(sessionID)
-	scope := notificationroutes.ScopeChannel
-	target := "#websocket"
-	if s.getDefaultNotificationRouteID(ctx, notificationroutes.ScopeThread, threadTarget) != "" {
-		scope = notificationroutes.ScopeThread
-		target = threadTarget
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func (s *Server) handleResolvePrompts(c *echo.Context) error {
+	if s.prompts == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
 	}
-	s.notificationDispatch.HandleWebMessageEvent(ctx, notificationroutes.WebMessageEvent{
-		Scope:     scope,
-		Target:    target,
-		EventType: notificationroutes.EventWebMessage,
-		Role:      role,
-		Content:   content,
-		SessionID: sessionID,
-		ThreadID:  threadTarget,
-		Username:  username,
-		RuntimeID: runtimeID,
-		Topic:     s.getThreadTopic(sessionID),
-		CreatedAt: time.Now(),
-	})
+	var body prompts.ResolveInput
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	if strings.TrimSpace(body.Workspace) == "" && s.config != nil {
+		body.Workspace = s.config.WorkspacePath()
+	}
+	resolved, err := s.prompts.Resolve(c.Request().Context(), body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, resolved)
 }
 
-func (s *Server) handleDaemonRuntimeChatMessage(
-	ctx context.Context,
-	username, runtimeID, sessionID, content string,
-) (bool, string, error) {
-	if s == nil || s.runtimeMgr == nil || s.agent == nil || s.agent.TaskService() == nil {
-		return false, "", nil
+func (s *Server) handlePreviewContextSources(c *echo.Context) error {
+	if s.agent == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "agent not available"})
 	}
-	runtimeID = strings.TrimSpace(runtimeID)
-	if runtimeID == "" {
-		return false, "", nil
+	var body struct {
+		prompts.ResolveInput
+		UserMessage string `json:"user_message"`
 	}
-	runtimeItem, err := s.runtimeMgr.Get(ctx, runtimeID)
-	if err != nil || runtimeItem == nil {
-		return false, "", nil
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	if !runtimeIsDaemonBacked(runtimeItem) {
-		return false, "", nil
+	preview, err := s.agent.PreviewContextSources(c.Request().Context(), agent.PromptContext{
+		Channel:           body.Channel,
+		SessionID:         body.SessionID,
+		UserID:            body.UserID,
+		Username:          body.Username,
+		RequestedProvider: body.RequestedProvider,
+		RequestedModel:    body.RequestedModel,
+		RequestedFallback: body.RequestedFallback,
+		ExplicitPromptIDs: body.ExplicitPromptIDs,
+		Custom:            body.Custom,
+	}, body.UserMessage)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	machineID, _ := runtimeItem.Policy["daemon_computer_id"].(string)
-	workspaceID, _ := runtimeItem.Policy["daemon_workspace_id"].(string)
-	if strings.TrimSpace(machineID) == "" {
-		return false, "", fmt.Errorf("daemon-backed runtime %s is missing daemon_computer_id policy", runtimeID)
+	return c.JSON(http.StatusOK, preview)
+}
+
+func (s *Server) handleListRuntimeAgents(c *echo.Context) error {
+	if s.runtimeMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "runtime agent manager not available"})
 	}
-	taskID := "daemon-task-" + uuid.NewString()
-	_, err = s.agent.TaskService().Enqueue(tasks.Task{
-		ID:        taskID,
-		Type:      tasks.TypeRemoteAgent,
-		Summary:   content,
-		SessionID: sessionID,
-		RuntimeID: runtimeID,
-		Metadata: map[string]any{
-			"computer_id":        strings.TrimSpace(machineID),
-			"workspace_id":       strings.TrimSpace(workspaceID),
-			"created_by_user_id": strings.TrimSpace(username),
-			"delivery":           "daemon",
-		},
-	})
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	items, err := s.deriveRuntimeStatuses(ctx)
 	if err != nil {
-		return true, "", err
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return true, fmt.Sprintf("Daemon task queued.\nTask ID: %s\nRuntime: %s\nMachine: %s", taskID, runtimeID, machineID), nil
+	return c.JSON(http.StatusOK, items)
 }
 
-func runtimeIsDaemonBacked(item *runtimeagents.AgentRuntime) bool {
-	if item == nil {
-		return false
+func (s *Server) handleCreateRuntimeAgent(c *echo.Context) error {
+	if s.runtimeMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "runtime agent manager not available"})
 	}
-	if item.Policy == nil {
-		return false
+	var body runtimeagents.AgentRuntime
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	item, err := s.runtimeMgr.Create(ctx, body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	value, _ := item.Policy["daemon_enabled"].(bool)
-	return value
+	return c.JSON(http.StatusOK, item)
 }
 
-func (s *Server) getOrCreateChatSession(sessionID string) (agent.SessionInterface, error) {
-	if s.sessionMgr == nil {
-		return nil, fmt.Errorf("session manager not available")
+func (s *Server) handleUpdateRuntimeAgent(c *echo.Context) error {
+	if s.runtimeMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "runtime agent manager not available"})
 	}
-	return s.sessionMgr.GetWithSource(sessionID, session.SourceWebUI)
+	var body runtimeagents.AgentRuntime
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	item, err := s.runtimeMgr.Update(ctx, c.Param("id"), body)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, runtimeagents.ErrRuntimeNotFound) {
+			status = http.StatusNotFound
+		}
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			status = http.StatusForbidden
+		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, item)
 }
 
-func (s *Server) handleToolSessionWS(c *echo.Context) error {
-	if s.processMgr == nil || s.toolSess == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "tool runtime not available"})
+func (s *Server) handleDeleteRuntimeAgent(c *echo.Context) error {
+	if s.runtimeMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "runtime agent manager not available"})
 	}
-
-	tokenStr := strings.TrimSpace(c.QueryParam("token"))
-	if tokenStr == "" {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "token required"})
+	runtimeID := strings.TrimSpace(c.Param("id"))
+	if runtimeID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "runtime id is required"})
 	}
-	username, scopedSessionID, _, err := s.parseScopedStreamToken(tokenStr, streamTokenPurposeToolSessionWS)
+	authCtx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	if s.bindingMgr != nil {
+		if err := s.bindingMgr.DeleteByRuntimeID(authCtx, runtimeID); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+	err := s.runtimeMgr.Delete(authCtx, runtimeID)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+		status := http.StatusBadRequest
+		if errors.Is(err, runtimeagents.ErrRuntimeNotFound) {
+			status = http.StatusNotFound
+		}
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			status = http.StatusForbidden
+		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
 
-	sessionID := strings.TrimSpace(c.QueryParam("session_id"))
-	if sessionID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "session_id required"})
+func (s *Server) handleListChannelAccounts(c *echo.Context) error {
+	if s.accountMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "channel account manager not available"})
 	}
-	sess, err := s.toolSess.GetSession(c.Request().Context(), sessionID)
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	items, err := s.accountMgr.List(ctx)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
-		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	if scopedSessionID != "" && scopedSessionID != sessionID {
-		return c.JSON(http.StatusForbidden, map[string]string{"error": "session does not belong to current token"})
+	return c.JSON(http.StatusOK, items)
+}
+
+func (s *Server) handleCreateChannelAccount(c *echo.Context) error {
+	if s.accountMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "channel account manager not available"})
 	}
-	if strings.TrimSpace(sess.Owner) != "" && sess.Owner != username {
-		return c.JSON(http.StatusForbidden, map[string]string{"error": "session does not belong to current user"})
+	var body channelaccounts.ChannelAccount
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	s.tryRestoreToolSessionRuntime(c.Request().Context(), sessionID)
-
-	conn, err := wsUpgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err := validateChannelAccountInput(body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	item, err := s.accountMgr.Create(ctx, body)
 	if err != nil {
-		s.logger.Error("Tool session WS upgrade failed", zap.Error(err))
-		return nil
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	defer func() {
-		_ = conn.Close()
-	}()
-
-	var writeMu sync.Mutex
-	writeJSON := func(v interface{}) error {
-		writeMu.Lock()
-		defer writeMu.Unlock()
-		if err := conn.SetWriteDeadline(time.Now().Add(10 * time.Second)); err != nil {
-			s.logger.Warn("Failed to set tool session WS write deadline", zap.Error(err))
+	if s.channels != nil {
+		if err := s.reloadChannelsByType(item.ChannelType); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "channel account created but runtime reload failed: " + err.Error(),
+			})
 		}
-		return conn.WriteJSON(v)
 	}
+	return c.JSON(http.StatusOK, item)
+}
 
-	if err := writeJSON(toolWSResponse{
-		Type:      "ready",
-		SessionID: sessionID,
-		Running:   true,
-	}); err != nil {
-		s.logger.Warn("Failed to write tool session websocket ready event",
-			zap.String("session_id", sessionID),
-			zap.Error(err),
-		)
+func (s *Server) handleUpdateChannelAccount(c *echo.Context) error {
+	if s.accountMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "channel account manager not available"})
 	}
-
-	conn.SetReadLimit(65536)
-	if err := conn.SetReadDeadline(time.Now().Add(120 * time.Second)); err != nil {
-		s.logger.Warn("Failed to set tool session WS read deadline", zap.Error(err))
+	id := c.Param("id")
+	authCtx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	existing, err := s.accountMgr.Get(authCtx, id)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, channelaccounts.ErrAccountNotFound) {
+			status = http.StatusNotFound
+		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
 	}
-	conn.SetPongHandler(func(string) error {
-		if err := conn.SetReadDeadline(time.Now().Add(120 * time.Second)); err != nil {
-			s.logger.Warn("Failed to refresh tool session WS read deadline", zap.Error(err))
+	if existing == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "channel account not found"})
+	}
+	var body channelaccounts.ChannelAccount
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	if err := validateChannelAccountInput(body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	item, err := s.accountMgr.Update(authCtx, id, body)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, channelaccounts.ErrAccountNotFound) {
+			status = http.StatusNotFound
 		}
-		return nil
-	})
-
-	done := make(chan struct{})
-	var doneOnce sync.Once
-	markDone := func() {
-		doneOnce.Do(func() { close(done) })
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			status = http.StatusForbidden
+		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
 	}
-	defer markDone()
-
-	go func() {
-		defer markDone()
-		for {
-			_, payload, err := conn.ReadMessage()
-			if err != nil {
-				return
-			}
-
-			var msg toolWSMessage
-			if err := json.Unmarshal(payload, &msg); err != nil {
-				if writeErr := writeJSON(toolWSResponse{Type: "error", Message: "invalid message format"}); writeErr != nil {
-					s.logger.Warn("Failed to write invalid-format tool websocket error",
-						zap.String("session_id", sessionID),
-						zap.Error(writeErr),
-					)
-				}
-				continue
-			}
-			switch msg.Type {
-			case "ping":
-				if err := writeJSON(toolWSResponse{Type: "pong"}); err != nil {
-					s.logger.Warn("Failed to write tool websocket pong",
-						zap.String("session_id", sessionID),
-						zap.Error(err),
-					)
-				}
-			case "kill":
-				if s.processMgr != nil {
-					if err := s.processMgr.Kill(sessionID); err != nil && !isProcessSessionNotFound(err) && !strings.Contains(strings.ToLower(err.Error()), "not running") {
-						s.logger.Warn("Failed to kill tool websocket session process",
-							zap.String("session_id", sessionID),
-							zap.Error(err),
-						)
-					}
-				}
-				s.tryKillRuntimeSession(context.Background(), sessionID)
-				if err := s.toolSess.TerminateSession(context.Background(), sessionID, "killed from tool ws"); err != nil {
-					s.logger.Warn("Failed to terminate tool websocket session",
-						zap.String("session_id", sessionID),
-						zap.Error(err),
-					)
-				}
-			case "resize":
-				if msg.Cols <= 0 || msg.Rows <= 0 {
-					continue
-				}
-				if err := s.processMgr.Resize(sessionID, msg.Cols, msg.Rows); err != nil && isProcessSessionNotFound(err) {
-					if s.tryRestoreToolSessionRuntime(context.Background(), sessionID) {
-						if retryErr := s.processMgr.Resize(sessionID, msg.Cols, msg.Rows); retryErr != nil {
-							s.logger.Warn("Failed to resize restored tool websocket session",
-								zap.String("session_id", sessionID),
-								zap.Int("cols", msg.Cols),
-								zap.Int("rows", msg.Rows),
-								zap.Error(retryErr),
-							)
-						}
-					}
-				}
-			case "input":
-				if msg.Data == "" {
-					continue
-				}
-				if err := s.processMgr.Write(sessionID, msg.Data); err != nil {
-					if isProcessSessionNotFound(err) && s.tryRestoreToolSessionRuntime(context.Background(), sessionID) {
-						if retryErr := s.processMgr.Write(sessionID, msg.Data); retryErr == nil {
-							if touchErr := s.toolSess.TouchSession(context.Background(), sessionID, toolsessions.StateRunning); touchErr != nil {
-								s.logger.Warn("Failed to touch restored websocket tool session",
-									zap.String("session_id", sessionID),
-									zap.Error(touchErr),
-								)
-							}
-							continue
-						} else {
-							err = retryErr
-						}
-					}
-					if writeErr := writeJSON(toolWSResponse{Type: "error", Message: err.Error()}); writeErr != nil {
-						s.logger.Warn("Failed to write tool websocket error response",
-							zap.String("session_id", sessionID),
-							zap.Error(writeErr),
-						)
-					}
-					continue
-				}
-				if err := s.toolSess.TouchSession(context.Background(), sessionID, toolsessions.StateRunning); err != nil {
-					s.logger.Warn("Failed to touch websocket tool session after input",
-						zap.String("session_id", sessionID),
-						zap.Error(err),
-					)
-				}
+	previousType := strings.TrimSpace(existing.ChannelType)
+	currentType := strings.TrimSpace(item.ChannelType)
+	if s.channels != nil {
+		if previousType != "" && previousType != currentType {
+			if err := s.reloadChannelsByType(previousType); err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "channel account updated but previous runtime reload failed: " + err.Error(),
+				})
 			}
 		}
-	}()
-
-	offset := 0
-	lastRunning := true
-	lastExit := 0
-	lastMissing := false
-	statusInit := false
+		if err := s.reloadChannelsByType(currentType); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "channel account updated but runtime reload failed: " + err.Error(),
+			})
+		}
+	}
+	return c.JSON(http.StatusOK, item)
+}
 
-	ticker := time.NewTicker(220 * time.Millisecond)
-	defer ticker.Stop()
+func (s *Server) handleDeleteChannelAccount(c *echo.Context) error {
+	if s.accountMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "channel account manager not available"})
+	}
+	id := c.Param("id")
+	authCtx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
+	existing, err := s.accountMgr.Get(authCtx, id)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, channelaccounts.ErrAccountNotFound) {
+			status = http.StatusNotFound
+		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
+	}
+	if existing == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "channel account not found"})
+	}
+	if s.bindingMgr != nil {
+		if err := s.bindingMgr.DeleteByChannelAccountID(authCtx, existing.ID); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+	err = s.accountMgr.Delete(authCtx, id)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, channelaccounts.ErrAccountNotFound) {
+			status = http.StatusNotFound
+		}
+		if errors.Is(err, ownership.ErrPermissionDenied) {
+			status = http.StatusForbidden
+		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
+	}
+	if s.channels != nil {
+		if err := s.reloadChannelsByType(existing.ChannelType); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "channel account deleted but runtime reload failed: " + err.Error(),
+			})
+		}
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
 
-	pingTicker := time.NewTicker(30 * time.Second)
-	defer pingTicker.Stop()
+func validateChannelAccountInput(item channelaccounts.ChannelAccount) error {
+	if !item.Enabled {
+		return nil
+	}
 
-	for {
-		select {
-		case <-done:
-			return nil
-		case <-pingTicker.C:
-			writeMu.Lock()
-			if err := conn.SetWriteDeadline(time.Now().Add(10 * time.Second)); err != nil {
-				writeMu.Unlock()
-				return nil
-			}
-			err := conn.WriteMessage(websocket.PingMessage, nil)
-			writeMu.Unlock()
-			if err != nil {
-				return nil
-			}
-		case <-ticker.C:
-			chunks, total, outErr := s.processMgr.GetOutput(sessionID, offset, 500)
-			if outErr == nil {
-				offset = total
-				if len(chunks) > 0 {
-					if err := writeJSON(toolWSResponse{
-						Type:  "output",
-						Data:  strings.Join(chunks, ""),
-						Total: total,
-					}); err != nil {
-						s.logger.Warn("Failed to write tool websocket output",
-							zap.String("session_id", sessionID),
-							zap.Error(err),
-						)
-					}
-				}
-			}
+	switch strings.TrimSpace(strings.ToLower(item.ChannelType)) {
+	case "wechat":
+		botToken, _ := item.Config["bot_token"].(string)
+		botID, _ := item.Config["ilink_bot_id"].(string)
+		if strings.TrimSpace(botToken) == "" || strings.TrimSpace(botID) == "" {
+			return fmt.Errorf("enabled wechat account requires config.bot_token and config.ilink_bot_id")
+		}
+	case "gotify":
+		serverURL, _ := item.Config["server_url"].(string)
+		appToken, _ := item.Config["app_token"].(string)
+		if strings.TrimSpace(serverURL) == "" || strings.TrimSpace(appToken) == "" {
+			return fmt.Errorf("enabled gotify account requires config.server_url and config.app_token")
+		}
+	case "matrix":
+		homeserver, _ := item.Config["homeserver"].(string)
+		userID, _ := item.Config["user_id"].(string)
+		accessToken, _ := item.Config["access_token"].(string)
+		if strings.TrimSpace(homeserver) == "" || strings.TrimSpace(userID) == "" || strings.TrimSpace(accessToken) == "" {
+			return fmt.Errorf("enabled matrix account requires config.homeserver, config.user_id and config.access_token")
+		}
+	case "signal":
+		apiURL, _ := item.Config["api_url"].(string)
+		number, _ := item.Config["number"].(string)
+		if strings.TrimSpace(apiURL) == "" || strings.TrimSpace(number) == "" {
+			return fmt.Errorf("enabled signal account requires config.api_url and config.number")
+		}
+	}
 
-			status, statusErr := s.processMgr.GetStatus(sessionID)
-			if statusErr != nil {
-				missing := isProcessSessionNotFound(statusErr)
-				if !statusInit || missing != lastMissing {
-					if err := writeJSON(toolWSResponse{
-						Type:    "status",
-						Running: false,
-						Missing: missing,
-					}); err != nil {
-						s.logger.Warn("Failed to write missing tool websocket status",
-							zap.String("session_id", sessionID),
-							zap.Error(err),
-						)
-					}
-					statusInit = true
-					lastMissing = missing
-				}
-				continue
-			}
+	return nil
+}
 
-			if status.Running {
-				if err := s.toolSess.TouchSession(context.Background(), sessionID, toolsessions.StateRunning); err != nil {
-					s.logger.Warn("Failed to touch websocket tool session from status poll",
-						zap.String("session_id", sessionID),
-						zap.Error(err),
-					)
-				}
-			} else {
-				if rec, err := s.toolSess.GetSession(context.Background(), sessionID); err == nil &&
-					rec.State != toolsessions.StateTerminated &&
-					rec.State != toolsessions.StateArchived {
-					if err := s.toolSess.TerminateSession(context.Background(), sessionID, fmt.Sprintf("process exited with code %d", status.ExitCode)); err != nil {
-						s.logger.Warn("Failed to terminate websocket tool session after exit",
-							zap.String("session_id", sessionID),
-							zap.Int("exit_code", status.ExitCode),
-							zap.Error(err),
-						)
-					}
-				}
-			}
+func (s *Server) handleListAccountBindings(c *echo.Context) error {
+	if s.bindingMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "account binding manager not available"})
+	}
+	items, err := s.bindingMgr.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, items)
+}
 
-			if !statusInit || status.Running != lastRunning || status.ExitCode != lastExit || lastMissing {
-				if err := writeJSON(toolWSResponse{
-					Type:     "status",
-					Running:  status.Running,
-					ExitCode: status.ExitCode,
-					Missing:  false,
-				}); err != nil {
-					s.logger.Warn("Failed to write websocket tool status",
-						zap.String("session_id", sessionID),
-						zap.Error(err),
-					)
-				}
-				statusInit = true
-				lastRunning = status.Running
-				lastExit = status.ExitCode
-				lastMissing = false
-			}
+func (s *Server) handleCreateAccountBinding(c *echo.Context) error {
+	if s.bindingMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "account binding manager not available"})
+	}
+	var body accountbindings.AccountBinding
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	item, err := s.bindingMgr.Create(c.Request().Context(), body)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, accountbindings.ErrBindingNotFound) {
+			status = http.StatusNotFound
 		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, item)
 }
 
-func sendWSError(conn *websocket.Conn, errMsg string, sessionID ...string) {
-	targetSessionID := ""
-	if len(sessionID) > 0 {
-		targetSessionID = strings.TrimSpace(sessionID[0])
+func (s *Server) handleUpdateAccountBinding(c *echo.Context) error {
+	if s.bindingMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "account binding manager not available"})
 	}
-	resp := chatWSResponse{
-		Type:      "error",
-		Content:   errMsg,
-		Timestamp: time.Now().Unix(),
-		SessionID: targetSessionID,
+	var body accountbindings.AccountBinding
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	if data, err := json.Marshal(resp); err == nil {
-		_ = conn.WriteMessage(websocket.TextMessage, data)
+	item, err := s.bindingMgr.Update(c.Request().Context(), c.Param("id"), body)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, accountbindings.ErrBindingNotFound) {
+			status = http.StatusNotFound
+		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, item)
 }
 
-func normalizeProviderNames(names []string) []string {
-	if len(names) == 0 {
-		return []string{}
+func (s *Server) handleDeleteAccountBinding(c *echo.Context) error {
+	if s.bindingMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "account binding manager not available"})
 	}
-	seen := make(map[string]struct{}, len(names))
-	result := make([]string, 0, len(names))
-	for _, name := range names {
-		trimmed := strings.TrimSpace(name)
-		if trimmed == "" {
-			continue
-		}
-		if _, ok := seen[trimmed]; ok {
-			continue
+	err := s.bindingMgr.Delete(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, accountbindings.ErrBindingNotFound) {
+			status = http.StatusNotFound
 		}
-		seen[trimmed] = struct{}{}
-		result = append(result, trimmed)
+		return c.JSON(status, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func (s *Server) handleGetRuntimeTopology(c *echo.Context) error {
+	if s.topologySvc == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "runtime topology service not available"})
 	}
-	return result
+	snapshot, err := s.topologySvc.Snapshot(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, snapshot)
 }
 
-func (s *Server) hasProvider(name string) bool {
-	for _, p := range s.config.Providers {
-		if strings.TrimSpace(p.Name) == name && providerHasRequiredAuthFields(p) {
-			return true
-		}
+func (s *Server) handleGetChatSessionPrompts(c *echo.Context) error {
+	if s.prompts == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
 	}
-	return false
+	sessionID, err := s.resolveWebUIChatSessionAlias(c, c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	}
+	result, err := s.prompts.GetSessionBindingSet(c.Request().Context(), sessionID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
 }
 
-func providerHasRequiredAuthFields(profile config.ProviderProfile) bool {
-	meta, ok := providerregistry.Get(strings.ToLower(strings.TrimSpace(profile.ProviderKind)))
-	if !ok {
-		return true
+func (s *Server) handlePutChatSessionPrompts(c *echo.Context) error {
+	if s.prompts == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
 	}
-	for _, field := range meta.AuthFields {
-		if !field.Required {
-			continue
-		}
-		switch field.Key {
-		case "api_key":
-			if strings.TrimSpace(profile.APIKey) == "" {
-				return false
-			}
+	var body struct {
+		SystemPromptIDs []string `json:"system_prompt_ids"`
+		UserPromptIDs   []string `json:"user_prompt_ids"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	sessionID, err := s.resolveWebUIChatSessionAlias(c, c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	}
+	result, err := s.prompts.ReplaceSessionBindings(c.Request().Context(), sessionID, body.SystemPromptIDs, body.UserPromptIDs)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, prompts.ErrPromptNotFound) {
+			status = http.StatusNotFound
 		}
+		return c.JSON(status, map[string]string{"error": err.Error()})
 	}
-	return true
+	return c.JSON(http.StatusOK, result)
 }
 
-func (s *Server) hasProviderGroup(name string) bool {
-	trimmed := strings.TrimSpace(name)
-	if trimmed == "" {
-		return false
+func (s *Server) handleDeleteChatSessionPrompts(c *echo.Context) error {
+	if s.prompts == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
 	}
-	for _, group := range s.config.Agents.Defaults.ProviderGroups {
-		if strings.TrimSpace(group.Name) == trimmed {
-			return true
-		}
+	sessionID, err := s.resolveWebUIChatSessionAlias(c, c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
 	}
-	return false
+	if err := s.prompts.ClearSessionBindings(c.Request().Context(), sessionID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
 }
 
-func (s *Server) hasRoutingTarget(name string) bool {
-	trimmed := strings.TrimSpace(name)
-	if trimmed == "" {
-		return false
+func (s *Server) handleUndoChatSession(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+	if s.snapshotMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "undo is not available"})
 	}
-	return s.hasProvider(trimmed) || s.hasProviderGroup(trimmed)
-}
 
-func (s *Server) persistChatRouting(provider, model string, fallback []string) error {
-	changed := false
+	sessionID, err := s.resolveWebUIChatSessionAlias(c, c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	}
 
-	if provider != "" {
-		if !s.hasRoutingTarget(provider) {
-			return fmt.Errorf("routing target not found: %s", provider)
-		}
+	var body struct {
+		Steps int `json:"steps"`
 	}
-	if strings.TrimSpace(s.config.Agents.Defaults.Provider) != provider {
-		s.config.Agents.Defaults.Provider = provider
-		changed = true
+	if err := c.Bind(&body); err != nil && !errors.Is(err, io.EOF) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	if trimmedModel := strings.TrimSpace(model); s.config.Agents.Defaults.Model != trimmedModel {
-		s.config.Agents.Defaults.Model = trimmedModel
-		changed = true
+	steps := body.Steps
+	if steps <= 0 {
+		steps = 1
 	}
 
-	for _, name := range fallback {
-		if !s.hasRoutingTarget(name) {
-			return fmt.Errorf("fallback routing target not found: %s", name)
+	sess, err := s.sessionMgr.GetExisting(sessionID)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
 		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
 	}
-	if !reflect.DeepEqual(s.config.Agents.Defaults.Fallback, fallback) {
-		s.config.Agents.Defaults.Fallback = fallback
-		changed = true
+
+	store := s.snapshotMgr.GetStore(sessionID)
+	if err := store.LoadSnapshots(); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load undo snapshots: %v", err)})
 	}
 
-	if !changed {
-		return nil
+	undone := 0
+	var reverted []session.MessageSnapshot
+	for undone < steps && store.CanUndo() {
+		reverted, err = store.Undo()
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("undo failed: %v", err)})
+		}
+		undone++
 	}
 
-	if err := config.ValidateConfig(s.config); err != nil {
-		return err
+	if undone == 0 {
+		current := sess.GetMessages()
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"undone_steps":    0,
+			"remaining_turns": store.GetTurnCount(),
+			"message_count":   len(current),
+			"messages":        buildSessionMessageResponses(current),
+		})
 	}
 
-	return config.SaveDatabaseSections(s.config, "agents")
+	messages := session.MessageSnapshotsToMessages(reverted)
+	sess.ReplaceMessages(messages)
+	if err := s.sessionMgr.Save(sess); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to save reverted session: %v", err)})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"undone_steps":    undone,
+		"remaining_turns": store.GetTurnCount(),
+		"message_count":   len(messages),
+		"messages":        buildSessionMessageResponses(messages),
+	})
 }
 
-func mustMarshalChatRouting(routing chatRouteSettings) string {
-	data, err := json.Marshal(routing)
+func (s *Server) handleGetChatDeveloperMode(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+	sessionID, err := s.resolveWebUIChatSessionAlias(c, c.Param("id"))
 	if err != nil {
-		return `{"provider":"","model":"","fallback":[]}`
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
 	}
-	return string(data)
+
+	sess, err := s.sessionMgr.GetExisting(sessionID)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusOK, map[string]bool{"developer_mode": false})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+	return c.JSON(http.StatusOK, map[string]bool{"developer_mode": sess.GetDeveloperMode()})
 }
 
-func (s *Server) handleListPrompts(c *echo.Context) error {
-	if s.prompts == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+// handleUpdateChatDeveloperMode toggles developer mode for a chat session.
+// While on, every route_result frame carries the redacted LLM requests of
+// the turn.
+func (s *Server) handleUpdateChatDeveloperMode(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	items, err := s.prompts.ListPrompts(ctx)
+	sessionID, err := s.resolveWebUIChatSessionAlias(c, c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, items)
-}
 
-func (s *Server) handleCreatePrompt(c *echo.Context) error {
-	if s.prompts == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+	var body struct {
+		Enabled bool `json:"enabled"`
 	}
-	var body prompts.Prompt
 	if err := c.Bind(&body); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	item, err := s.prompts.CreatePrompt(ctx, body)
+
+	sess, err := s.sessionMgr.GetWithSource(sessionID, session.SourceWebUI)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
 	}
-	return c.JSON(http.StatusOK, item)
+	sess.SetDeveloperMode(body.Enabled)
+	return c.JSON(http.StatusOK, map[string]bool{"developer_mode": sess.GetDeveloperMode()})
 }
 
-func (s *Server) handleUpdatePrompt(c *echo.Context) error {
-	if s.prompts == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+func (s *Server) handleGetChatPersona(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
 	}
-	var body prompts.Prompt
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	sessionID, err := s.resolveWebUIChatSessionAlias(c, c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	item, err := s.prompts.UpdatePrompt(ctx, c.Param("id"), body)
+
+	sess, err := s.sessionMgr.GetExisting(sessionID)
 	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, prompts.ErrPromptNotFound) {
-			status = http.StatusNotFound
-		}
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			status = http.StatusForbidden
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusOK, map[string]string{"persona": ""})
 		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
 	}
-	return c.JSON(http.StatusOK, item)
+	return c.JSON(http.StatusOK, map[string]string{"persona": sess.GetPersona()})
 }
 
-func (s *Server) handleDeletePrompt(c *echo.Context) error {
-	if s.prompts == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+// handleUpdateChatPersona sets the custom system prompt of a chat session.
+// An empty persona restores the default prompt.
+func (s *Server) handleUpdateChatPersona(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	err := s.prompts.DeletePrompt(ctx, c.Param("id"))
+	sessionID, err := s.resolveWebUIChatSessionAlias(c, c.Param("id"))
 	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, prompts.ErrPromptNotFound) {
-			status = http.StatusNotFound
-		}
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			status = http.StatusForbidden
-		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
-}
 
-func (s *Server) handleListPromptBindings(c *echo.Context) error {
-	if s.prompts == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+	var body struct {
+		Persona string `json:"persona"`
 	}
-	items, err := s.prompts.ListBindings(
-		c.Request().Context(),
-		c.QueryParam("scope"),
-		c.QueryParam("target"),
-	)
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+
+	sess, err := s.sessionMgr.GetWithSource(sessionID, session.SourceWebUI)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	}
+	sess.SetPersona(body.Persona)
+	return c.JSON(http.StatusOK, map[string]string{"persona": sess.GetPersona()})
+}
+
+func (s *Server) handleListAgentProfiles(c *echo.Context) error {
+	profiles := s.config.Agents.Profiles
+	if profiles == nil {
+		profiles = []config.AgentProfileConfig{}
+	}
+	return c.JSON(http.StatusOK, profiles)
+}
+
+func (s *Server) handleCreateAgentProfile(c *echo.Context) error {
+	var profile config.AgentProfileConfig
+	if err := c.Bind(&profile); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	return c.JSON(http.StatusOK, items)
+	profile = normalizeAgentProfile(profile)
+	if _, exists := s.config.Agents.Profile(profile.Name); exists {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "agent profile already exists"})
+	}
+
+	profiles := append(slices.Clone(s.config.Agents.Profiles), profile)
+	if err := s.saveAgentProfiles(profiles); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"status":  "created",
+		"profile": profile,
+	})
 }
 
-func (s *Server) handleCreatePromptBinding(c *echo.Context) error {
-	if s.prompts == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+func (s *Server) handleUpdateAgentProfile(c *echo.Context) error {
+	name := strings.TrimSpace(c.Param("name"))
+	index := s.agentProfileIndex(name)
+	if index < 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "agent profile not found"})
 	}
-	var body prompts.Binding
-	if err := c.Bind(&body); err != nil {
+
+	var profile config.AgentProfileConfig
+	if err := c.Bind(&profile); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	item, err := s.prompts.CreateBinding(c.Request().Context(), body)
-	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, prompts.ErrPromptNotFound) {
-			status = http.StatusNotFound
-		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
+	profile = normalizeAgentProfile(profile)
+	if profile.Name == "" {
+		profile.Name = name
 	}
-	return c.JSON(http.StatusOK, item)
+
+	profiles := slices.Clone(s.config.Agents.Profiles)
+	profiles[index] = profile
+	if err := s.saveAgentProfiles(profiles); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":  "updated",
+		"profile": profile,
+	})
 }
 
-func (s *Server) handleUpdatePromptBinding(c *echo.Context) error {
-	if s.prompts == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+func (s *Server) handleDeleteAgentProfile(c *echo.Context) error {
+	index := s.agentProfileIndex(strings.TrimSpace(c.Param("name")))
+	if index < 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "agent profile not found"})
 	}
-	var body prompts.Binding
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+
+	profiles := slices.Delete(slices.Clone(s.config.Agents.Profiles), index, index+1)
+	if err := s.saveAgentProfiles(profiles); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	item, err := s.prompts.UpdateBinding(c.Request().Context(), c.Param("id"), body)
-	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, prompts.ErrPromptNotFound) || errors.Is(err, prompts.ErrBindingNotFound) {
-			status = http.StatusNotFound
-		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func (s *Server) agentProfileIndex(name string) int {
+	return slices.IndexFunc(s.config.Agents.Profiles, func(profile config.AgentProfileConfig) bool {
+		return name != "" && strings.TrimSpace(profile.Name) == name
+	})
+}
+
+// saveAgentProfiles validates and persists a new profile list, keeping the
+// previous list when validation or saving fails.
+func (s *Server) saveAgentProfiles(profiles []config.AgentProfileConfig) error {
+	previous := s.config.Agents.Profiles
+	s.config.Agents.Profiles = profiles
+	if err := config.ValidateConfig(s.config); err != nil {
+		s.config.Agents.Profiles = previous
+		return err
 	}
-	return c.JSON(http.StatusOK, item)
+	if err := config.SaveDatabaseSections(s.config, "agents"); err != nil {
+		s.config.Agents.Profiles = previous
+		return fmt.Errorf("failed to save agent profiles: %w", err)
+	}
+	return nil
 }
 
-func (s *Server) handleDeletePromptBinding(c *echo.Context) error {
-	if s.prompts == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+func normalizeAgentProfile(profile config.AgentProfileConfig) config.AgentProfileConfig {
+	profile.Name = strings.TrimSpace(profile.Name)
+	profile.Description = strings.TrimSpace(profile.Description)
+	profile.Provider = strings.TrimSpace(profile.Provider)
+	profile.Model = strings.TrimSpace(profile.Model)
+	profile.SystemPrompt = strings.TrimSpace(profile.SystemPrompt)
+	profile.Workspace = strings.TrimSpace(profile.Workspace)
+	profile.Tools = normalizeProviderNames(profile.Tools)
+	profile.Channels = normalizeProviderNames(profile.Channels)
+	return profile
+}
+
+// mcpServerResponse pairs a configured MCP server with its live status.
+type mcpServerResponse struct {
+	Config config.MCPServerConfig `json:"config"`
+	Status mcp.ServerStatus       `json:"status"`
+}
+
+func (s *Server) handleListMCPServers(c *echo.Context) error {
+	if s.mcpMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "mcp manager unavailable"})
 	}
-	err := s.prompts.DeleteBinding(c.Request().Context(), c.Param("id"))
-	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, prompts.ErrBindingNotFound) {
-			status = http.StatusNotFound
+	statuses := make(map[string]mcp.ServerStatus)
+	for _, status := range s.mcpMgr.Status() {
+		statuses[status.Name] = status
+	}
+	servers := make([]mcpServerResponse, 0, len(s.config.Agents.Defaults.MCPServers))
+	for i, server := range s.config.Agents.Defaults.MCPServers {
+		name := mcp.ServerName(server, i)
+		status, ok := statuses[name]
+		if !ok {
+			status = mcp.ServerStatus{Name: name, State: mcp.StateConnecting, Tools: []string{}}
 		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
+		servers = append(servers, mcpServerResponse{Config: server, Status: status})
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+	return c.JSON(http.StatusOK, servers)
 }
 
-func (s *Server) handleResolvePrompts(c *echo.Context) error {
-	if s.prompts == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+func (s *Server) handleCreateMCPServer(c *echo.Context) error {
+	if s.mcpMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "mcp manager unavailable"})
 	}
-	var body prompts.ResolveInput
-	if err := c.Bind(&body); err != nil {
+	var server config.MCPServerConfig
+	if err := c.Bind(&server); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	if strings.TrimSpace(body.Workspace) == "" && s.config != nil {
-		body.Workspace = s.config.WorkspacePath()
+	server = normalizeMCPServer(server)
+	if server.Name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "name is required"})
 	}
-	resolved, err := s.prompts.Resolve(c.Request().Context(), body)
-	if err != nil {
+	if s.mcpServerIndex(server.Name) >= 0 {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "mcp server already exists"})
+	}
+
+	servers := append(slices.Clone(s.config.Agents.Defaults.MCPServers), server)
+	if err := s.saveMCPServers(servers); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, resolved)
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"status": "created",
+		"server": server,
+	})
 }
 
-func (s *Server) handlePreviewContextSources(c *echo.Context) error {
-	if s.agent == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "agent not available"})
+func (s *Server) handleUpdateMCPServer(c *echo.Context) error {
+	if s.mcpMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "mcp manager unavailable"})
 	}
-	var body struct {
-		prompts.ResolveInput
-		UserMessage string `json:"user_message"`
+	name := strings.TrimSpace(c.Param("name"))
+	index := s.mcpServerIndex(name)
+	if index < 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "mcp server not found"})
 	}
-	if err := c.Bind(&body); err != nil {
+
+	var server config.MCPServerConfig
+	if err := c.Bind(&server); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	preview, err := s.agent.PreviewContextSources(c.Request().Context(), agent.PromptContext{
-		Channel:           body.Channel,
-		SessionID:         body.SessionID,
-		UserID:            body.UserID,
-		Username:          body.Username,
-		RequestedProvider: body.RequestedProvider,
-		RequestedModel:    body.RequestedModel,
-		RequestedFallback: body.RequestedFallback,
-		ExplicitPromptIDs: body.ExplicitPromptIDs,
-		Custom:            body.Custom,
-	}, body.UserMessage)
-	if err != nil {
+	server = normalizeMCPServer(server)
+	if server.Name == "" {
+		server.Name = name
+	}
+	if server.Name != name && s.mcpServerIndex(server.Name) >= 0 {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "mcp server already exists"})
+	}
+
+	servers := slices.Clone(s.config.Agents.Defaults.MCPServers)
+	servers[index] = server
+	if err := s.saveMCPServers(servers); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, preview)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "updated",
+		"server": server,
+	})
 }
 
-func (s *Server) handleListRuntimeAgents(c *echo.Context) error {
-	if s.runtimeMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "runtime agent manager not available"})
+func (s *Server) handleDeleteMCPServer(c *echo.Context) error {
+	if s.mcpMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "mcp manager unavailable"})
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	items, err := s.deriveRuntimeStatuses(ctx)
-	if err != nil {
+	index := s.mcpServerIndex(strings.TrimSpace(c.Param("name")))
+	if index < 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "mcp server not found"})
+	}
+
+	servers := slices.Delete(slices.Clone(s.config.Agents.Defaults.MCPServers), index, index+1)
+	if err := s.saveMCPServers(servers); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, items)
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
 }
 
-func (s *Server) handleCreateRuntimeAgent(c *echo.Context) error {
-	if s.runtimeMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "runtime agent manager not available"})
+// handleReconnectMCPServer drops a server's connection and connects again,
+// returning the resulting status.
+func (s *Server) handleReconnectMCPServer(c *echo.Context) error {
+	if s.mcpMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "mcp manager unavailable"})
 	}
-	var body runtimeagents.AgentRuntime
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
-	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	item, err := s.runtimeMgr.Create(ctx, body)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	status, err := s.mcpMgr.Reconnect(c.Request().Context(), strings.TrimSpace(c.Param("name")))
+	if errors.Is(err, mcp.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, item)
+	// A failed connection is reported through the status, not as a request error.
+	return c.JSON(http.StatusOK, status)
 }
 
-func (s *Server) handleUpdateRuntimeAgent(c *echo.Context) error {
-	if s.runtimeMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "runtime agent manager not available"})
-	}
-	var body runtimeagents.AgentRuntime
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
-	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	item, err := s.runtimeMgr.Update(ctx, c.Param("id"), body)
-	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, runtimeagents.ErrRuntimeNotFound) {
-			status = http.StatusNotFound
-		}
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			status = http.StatusForbidden
-		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
+// handleListPlugins reports gRPC plugins and WASM tools.
+func (s *Server) handleListPlugins(c *echo.Context) error {
+	if s.pluginMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "plugin manager unavailable"})
 	}
-	return c.JSON(http.StatusOK, item)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"plugins_dir": s.config.PluginsPath(),
+		"plugins":     s.pluginMgr.Status(),
+	})
 }
 
-func (s *Server) handleDeleteRuntimeAgent(c *echo.Context) error {
-	if s.runtimeMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "runtime agent manager not available"})
-	}
-	runtimeID := strings.TrimSpace(c.Param("id"))
-	if runtimeID == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "runtime id is required"})
+// handleInstallWasmPlugin installs a WASM tool from an uploaded .zip
+// (multipart field "file") or from a JSON {"source": ...} naming a
+// directory, .zip or URL on the server.
+func (s *Server) handleInstallWasmPlugin(c *echo.Context) error {
+	if s.pluginMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "plugin manager unavailable"})
 	}
-	authCtx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	if s.bindingMgr != nil {
-		if err := s.bindingMgr.DeleteByRuntimeID(authCtx, runtimeID); err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	ctx := c.Request().Context()
+	var (
+		status plugins.Status
+		source string
+		err    error
+	)
+	if header, formErr := c.FormFile("file"); formErr == nil {
+		source = header.Filename
+		if header.Size > plugins.MaxWasmArchiveBytes {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+				"error": fmt.Sprintf("archive exceeds %d MB", plugins.MaxWasmArchiveBytes>>20),
+			})
 		}
-	}
-	err := s.runtimeMgr.Delete(authCtx, runtimeID)
-	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, runtimeagents.ErrRuntimeNotFound) {
-			status = http.StatusNotFound
+		file, openErr := header.Open()
+		if openErr != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": openErr.Error()})
 		}
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			status = http.StatusForbidden
+		data, readErr := io.ReadAll(io.LimitReader(file, plugins.MaxWasmArchiveBytes))
+		_ = file.Close()
+		if readErr != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": readErr.Error()})
 		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
+		status, err = s.pluginMgr.InstallWasmArchive(ctx, data)
+	} else {
+		var body struct {
+			Source string `json:"source"`
+		}
+		if bindErr := c.Bind(&body); bindErr != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		}
+		source = strings.TrimSpace(body.Source)
+		if source == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "file or source is required"})
+		}
+		status, err = s.pluginMgr.InstallWasm(ctx, source)
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+	if err != nil {
+		s.recordAudit(c, audit.Event{Action: audit.ActionPluginInstall, Target: source, Summary: err.Error()})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	s.recordAudit(c, audit.Event{
+		Action:  audit.ActionPluginInstall,
+		Target:  status.Name,
+		Success: true,
+		Summary: fmt.Sprintf("source=%s workspace=%s", source, status.Workspace),
+	})
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"status": "installed",
+		"plugin": status,
+	})
 }
 
-func (s *Server) handleListChannelAccounts(c *echo.Context) error {
-	if s.accountMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "channel account manager not available"})
+func (s *Server) handleUninstallWasmPlugin(c *echo.Context) error {
+	if s.pluginMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "plugin manager unavailable"})
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	err := s.pluginMgr.UninstallWasm(c.Request().Context(), name)
+	if errors.Is(err, plugins.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	items, err := s.accountMgr.List(ctx)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, items)
+	s.recordAudit(c, audit.Event{Action: audit.ActionPluginUninstall, Target: name, Success: true})
+	return c.JSON(http.StatusOK, map[string]string{"status": "uninstalled"})
 }
 
-func (s *Server) handleCreateChannelAccount(c *echo.Context) error {
-	if s.accountMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "channel account manager not available"})
-	}
-	var body channelaccounts.ChannelAccount
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+// mcpServerIndex finds a server by its effective name, which falls back to
+// the position for unnamed servers.
+func (s *Server) mcpServerIndex(name string) int {
+	for i, server := range s.config.Agents.Defaults.MCPServers {
+		if name != "" && mcp.ServerName(server, i) == name {
+			return i
+		}
 	}
-	if err := validateChannelAccountInput(body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	return -1
+}
+
+// saveMCPServers validates and persists a new server list, keeping the
+// previous list when validation or saving fails, then lets the MCP manager
+// pick up the change.
+func (s *Server) saveMCPServers(servers []config.MCPServerConfig) error {
+	previous := s.config.Agents.Defaults.MCPServers
+	s.config.Agents.Defaults.MCPServers = servers
+	if err := config.ValidateConfig(s.config); err != nil {
+		s.config.Agents.Defaults.MCPServers = previous
+		return err
 	}
-	ctx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	item, err := s.accountMgr.Create(ctx, body)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	if err := config.SaveDatabaseSections(s.config, "agents"); err != nil {
+		s.config.Agents.Defaults.MCPServers = previous
+		return fmt.Errorf("failed to save mcp servers: %w", err)
 	}
-	if s.channels != nil {
-		if err := s.reloadChannelsByType(item.ChannelType); err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "channel account created but runtime reload failed: " + err.Error(),
-			})
-		}
+	s.refreshMCPServers()
+	return nil
+}
+
+func (s *Server) refreshMCPServers() {
+	if s.mcpMgr != nil {
+		s.mcpMgr.Refresh()
 	}
-	return c.JSON(http.StatusOK, item)
 }
 
-func (s *Server) handleUpdateChannelAccount(c *echo.Context) error {
-	if s.accountMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "channel account manager not available"})
+func normalizeMCPServer(server config.MCPServerConfig) config.MCPServerConfig {
+	server.Name = strings.TrimSpace(server.Name)
+	server.Transport = strings.TrimSpace(strings.ToLower(server.Transport))
+	server.Command = strings.TrimSpace(server.Command)
+	server.WorkDir = strings.TrimSpace(server.WorkDir)
+	server.Endpoint = strings.TrimSpace(server.Endpoint)
+	server.Timeout = strings.TrimSpace(server.Timeout)
+	server.AllowedTools = normalizeProviderNames(server.AllowedTools)
+	server.BlockedTools = normalizeProviderNames(server.BlockedTools)
+	return server
+}
+
+// chatSessionSummaryResponse describes one playground session of the current
+// user. ID is the client-facing session ID the chat socket reports.
+type chatSessionSummaryResponse struct {
+	ID           string    `json:"id"`
+	RuntimeID    string    `json:"runtime_id"`
+	Title        string    `json:"title"`
+	MessageCount int       `json:"message_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// webUIChatSessionRuntime reports whether storedKey, a session key as listed
+// by the session manager, is a playground session with the given base
+// session and, if so, the runtime it is bound to.
+func webUIChatSessionRuntime(baseSessionID, storedKey string) (string, bool) {
+	baseKey := session.StorageKey(baseSessionID)
+	if storedKey == baseKey {
+		return "", true
 	}
-	id := c.Param("id")
-	authCtx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	existing, err := s.accountMgr.Get(authCtx, id)
-	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, channelaccounts.ErrAccountNotFound) {
-			status = http.StatusNotFound
-		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
+	rest, ok := strings.CutPrefix(storedKey, session.StorageKey(inboundrouter.SessionPrefix+":"))
+	if !ok {
+		return "", false
 	}
-	if existing == nil {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": "channel account not found"})
+	runtimeID, ok := strings.CutSuffix(rest, session.StorageKey(":"+baseKey))
+	if !ok || strings.TrimSpace(runtimeID) == "" {
+		return "", false
 	}
-	var body channelaccounts.ChannelAccount
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	return runtimeID, true
+}
+
+// resolveOwnedChatSessionID maps a client-facing playground session ID to the
+// stored session of the current user.
+func (s *Server) resolveOwnedChatSessionID(c *echo.Context) (string, int, error) {
+	username := s.currentUsername(c)
+	if username == "" {
+		return "", http.StatusUnauthorized, fmt.Errorf("authorization required")
 	}
-	if err := validateChannelAccountInput(body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	runtimeID, ok := resolveWebUIChatRuntimeAlias(c.Param("id"))
+	if !ok {
+		return "", http.StatusNotFound, fmt.Errorf("chat session not found")
 	}
-	item, err := s.accountMgr.Update(authCtx, id, body)
+	return s.chatSessionID(c.Request().Context(), username, runtimeID), http.StatusOK, nil
+}
+
+// handleListChatSessions lists the playground sessions of the current user,
+// most recently used first.
+func (s *Server) handleListChatSessions(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+	username := s.currentUsername(c)
+	if username == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "authorization required"})
+	}
+
+	ids, err := s.sessionMgr.List()
 	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, channelaccounts.ErrAccountNotFound) {
-			status = http.StatusNotFound
-		}
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			status = http.StatusForbidden
-		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to list sessions: %v", err)})
 	}
-	previousType := strings.TrimSpace(existing.ChannelType)
-	currentType := strings.TrimSpace(item.ChannelType)
-	if s.channels != nil {
-		if previousType != "" && previousType != currentType {
-			if err := s.reloadChannelsByType(previousType); err != nil {
-				return c.JSON(http.StatusInternalServerError, map[string]string{
-					"error": "channel account updated but previous runtime reload failed: " + err.Error(),
-				})
-			}
+	ctx := c.Request().Context()
+	baseSessionID := s.chatSessionID(ctx, username, "")
+	summaries := make([]chatSessionSummaryResponse, 0)
+	for _, id := range ids {
+		runtimeID, ok := webUIChatSessionRuntime(baseSessionID, id)
+		if !ok {
+			continue
 		}
-		if err := s.reloadChannelsByType(currentType); err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "channel account updated but runtime reload failed: " + err.Error(),
-			})
+		sess, err := s.sessionMgr.GetExisting(s.chatSessionID(ctx, username, runtimeID))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session %q: %v", id, err)})
 		}
+		summaries = append(summaries, chatSessionSummaryResponse{
+			ID:           webUIClientChatSessionID(runtimeID),
+			RuntimeID:    runtimeID,
+			Title:        sess.GetTitle(),
+			MessageCount: len(sess.GetMessages()),
+			CreatedAt:    sess.GetCreatedAt(),
+			UpdatedAt:    sess.GetUpdatedAt(),
+		})
 	}
-	return c.JSON(http.StatusOK, item)
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].UpdatedAt.After(summaries[j].UpdatedAt)
+	})
+	return c.JSON(http.StatusOK, summaries)
 }
 
-func (s *Server) handleDeleteChannelAccount(c *echo.Context) error {
-	if s.accountMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "channel account manager not available"})
+// handleGetChatSessionMessages returns the stored history of a playground
+// session so the chat page can resume it. A session without history yields
+// an empty list.
+func (s *Server) handleGetChatSessionMessages(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
 	}
-	id := c.Param("id")
-	authCtx := ownership.WithAuthContext(c.Request().Context(), s.authContextFromEcho(c))
-	existing, err := s.accountMgr.Get(authCtx, id)
+	sessionID, status, err := s.resolveOwnedChatSessionID(c)
 	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, channelaccounts.ErrAccountNotFound) {
-			status = http.StatusNotFound
-		}
 		return c.JSON(status, map[string]string{"error": err.Error()})
 	}
-	if existing == nil {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": "channel account not found"})
+
+	messages := []sessionMessageResponse{}
+	sess, err := s.sessionMgr.GetExisting(sessionID)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
 	}
-	if s.bindingMgr != nil {
-		if err := s.bindingMgr.DeleteByChannelAccountID(authCtx, existing.ID); err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-		}
+	if sess != nil {
+		messages = buildSessionMessageResponses(sess.GetMessages())
 	}
-	err = s.accountMgr.Delete(authCtx, id)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"id":       strings.TrimSpace(c.Param("id")),
+		"messages": messages,
+	})
+}
+
+// handleDeleteChatSession removes a playground session of the current user
+// together with its undo snapshots and prompt bindings.
+func (s *Server) handleDeleteChatSession(c *echo.Context) error {
+	if s.sessionMgr == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+	}
+	sessionID, status, err := s.resolveOwnedChatSessionID(c)
 	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, channelaccounts.ErrAccountNotFound) {
-			status = http.StatusNotFound
-		}
-		if errors.Is(err, ownership.ErrPermissionDenied) {
-			status = http.StatusForbidden
-		}
 		return c.JSON(status, map[string]string{"error": err.Error()})
 	}
-	if s.channels != nil {
-		if err := s.reloadChannelsByType(existing.ChannelType); err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "channel account deleted but runtime reload failed: " + err.Error(),
-			})
+
+	if _, err := s.sessionMgr.GetExisting(sessionID); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "chat session not found"})
 		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
-}
-
-func validateChannelAccountInput(item channelaccounts.ChannelAccount) error {
-	if !item.Enabled {
-		return nil
+	if err := s.sessionMgr.Delete(sessionID); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to delete session: %v", err)})
 	}
-
-	switch strings.TrimSpace(strings.ToLower(item.ChannelType)) {
-	case "wechat":
-		botToken, _ := item.Config["bot_token"].(string)
-		botID, _ := item.Config["ilink_bot_id"].(string)
-		if strings.TrimSpace(botToken) == "" || strings.TrimSpace(botID) == "" {
-			return fmt.Errorf("enabled wechat account requires config.bot_token and config.ilink_bot_id")
+	if s.snapshotMgr != nil {
+		if err := s.snapshotMgr.RemoveStore(sessionID); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to clear undo snapshots: %v", err)})
 		}
-	case "gotify":
-		serverURL, _ := item.Config["server_url"].(string)
-		appToken, _ := item.Config["app_token"].(string)
-		if strings.TrimSpace(serverURL) == "" || strings.TrimSpace(appToken) == "" {
-			return fmt.Errorf("enabled gotify account requires config.server_url and config.app_token")
+	}
+	if s.prompts != nil {
+		if err := s.prompts.ClearSessionBindings(c.Request().Context(), sessionID); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to delete session prompts: %v", err)})
 		}
 	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
 
-	return nil
+// webUIChatLinkChannel is the channel WebUI users link under with /link.
+const webUIChatLinkChannel = "webui"
+
+// chatLinkResponse describes the identity link of the current user.
+type chatLinkResponse struct {
+	Linked    bool                      `json:"linked"`
+	SessionID string                    `json:"session_id,omitempty"`
+	Accounts  []userprefs.LinkedAccount `json:"accounts"`
+	Code      string                    `json:"code,omitempty"`
+	ExpiresAt *time.Time                `json:"expires_at,omitempty"`
 }
 
-func (s *Server) handleListAccountBindings(c *echo.Context) error {
-	if s.bindingMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "account binding manager not available"})
+func (s *Server) chatLinkStatus(ctx context.Context, username string) (chatLinkResponse, error) {
+	resp := chatLinkResponse{Accounts: []userprefs.LinkedAccount{}}
+	identity, err := s.prefs.LinkedIdentity(ctx, webUIChatLinkChannel, username)
+	if err != nil || identity == "" {
+		return resp, err
 	}
-	items, err := s.bindingMgr.List(c.Request().Context())
+	accounts, err := s.prefs.LinkedAccounts(ctx, identity)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return resp, err
 	}
-	return c.JSON(http.StatusOK, items)
+	resp.Linked = true
+	resp.SessionID = userprefs.LinkedSessionID(identity)
+	resp.Accounts = append(resp.Accounts, accounts...)
+	return resp, nil
 }
 
-func (s *Server) handleCreateAccountBinding(c *echo.Context) error {
-	if s.bindingMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "account binding manager not available"})
+// handleGetChatLink reports which channel accounts share the current user's
+// conversation.
+func (s *Server) handleGetChatLink(c *echo.Context) error {
+	if s.prefs == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "user preferences not available"})
 	}
-	var body accountbindings.AccountBinding
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	username := s.currentUsername(c)
+	if username == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "authorization required"})
 	}
-	item, err := s.bindingMgr.Create(c.Request().Context(), body)
+	resp, err := s.chatLinkStatus(c.Request().Context(), username)
 	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, accountbindings.ErrBindingNotFound) {
-			status = http.StatusNotFound
-		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load link: %v", err)})
 	}
-	return c.JSON(http.StatusOK, item)
+	return c.JSON(http.StatusOK, resp)
 }
 
-func (s *Server) handleUpdateAccountBinding(c *echo.Context) error {
-	if s.bindingMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "account binding manager not available"})
+// handleChatLink confirms a code issued by /link in another channel, or
+// issues a code to confirm there when the body has none.
+func (s *Server) handleChatLink(c *echo.Context) error {
+	if s.prefs == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "user preferences not available"})
+	}
+	username := s.currentUsername(c)
+	if username == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "authorization required"})
+	}
+	var body struct {
+		Code string `json:"code"`
 	}
-	var body accountbindings.AccountBinding
 	if err := c.Bind(&body); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
-	item, err := s.bindingMgr.Update(c.Request().Context(), c.Param("id"), body)
-	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, accountbindings.ErrBindingNotFound) {
-			status = http.StatusNotFound
+
+	ctx := c.Request().Context()
+	var (
+		code      string
+		expiresAt time.Time
+	)
+	if strings.TrimSpace(body.Code) != "" {
+		_, err := s.prefs.RedeemLinkCode(ctx, body.Code, webUIChatLinkChannel, username)
+		if errors.Is(err, userprefs.ErrInvalidLinkCode) || errors.Is(err, userprefs.ErrLinkSameAccount) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to link accounts: %v", err)})
+		}
+	} else {
+		var err error
+		code, expiresAt, err = s.prefs.IssueLinkCode(ctx, webUIChatLinkChannel, username)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to issue link code: %v", err)})
 		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, item)
-}
 
-func (s *Server) handleDeleteAccountBinding(c *echo.Context) error {
-	if s.bindingMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "account binding manager not available"})
-	}
-	err := s.bindingMgr.Delete(c.Request().Context(), c.Param("id"))
+	resp, err := s.chatLinkStatus(ctx, username)
 	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, accountbindings.ErrBindingNotFound) {
-			status = http.StatusNotFound
-		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load link: %v", err)})
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+	if code != "" {
+		resp.Code = code
+		resp.ExpiresAt = &expiresAt
+	}
+	return c.JSON(http.StatusOK, resp)
 }
 
-func (s *Server) handleGetRuntimeTopology(c *echo.Context) error {
-	if s.topologySvc == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "runtime topology service not available"})
+// handleUnlinkChat detaches the current user from their linked identity.
+func (s *Server) handleUnlinkChat(c *echo.Context) error {
+	if s.prefs == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "user preferences not available"})
 	}
-	snapshot, err := s.topologySvc.Snapshot(c.Request().Context())
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	username := s.currentUsername(c)
+	if username == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "authorization required"})
 	}
-	return c.JSON(http.StatusOK, snapshot)
+	if _, err := s.prefs.Unlink(c.Request().Context(), webUIChatLinkChannel, username); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to unlink: %v", err)})
+	}
+	return c.JSON(http.StatusOK, chatLinkResponse{Accounts: []userprefs.LinkedAccount{}})
 }
 
-func (s *Server) handleGetChatSessionPrompts(c *echo.Context) error {
-	if s.prompts == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+// chatUploadTTL bounds how long an uploaded chat image waits for the
+// message that references it.
+const chatUploadTTL = 10 * time.Minute
+
+// chatUpload is an image uploaded for a chat message that has not been sent
+// yet. Images are too large for the chat WebSocket, so messages refer to
+// them by ID.
+type chatUpload struct {
+	owner      string
+	attachment bus.Attachment
+	expiresAt  time.Time
+}
+
+type chatUploadResponse struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	MIMEType string `json:"mime_type"`
+	Size     int    `json:"size"`
+	URL      string `json:"url,omitempty"` // Where to fetch a file sent by the agent
+}
+
+func (s *Server) handleUploadChatAttachment(c *echo.Context) error {
+	username := s.currentUsername(c)
+	if username == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
 	}
-	sessionID, err := s.resolveWebUIChatSessionAlias(c, c.Param("id"))
+	header, err := c.FormFile("file")
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "file is required"})
 	}
-	result, err := s.prompts.GetSessionBindingSet(c.Request().Context(), sessionID)
+	if header.Size > bus.MaxImageBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": fmt.Sprintf("image exceeds %d MB", bus.MaxImageBytes>>20),
+		})
+	}
+	file, err := header.Open()
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	return c.JSON(http.StatusOK, result)
-}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, bus.MaxImageBytes+1))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if len(data) > bus.MaxImageBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": fmt.Sprintf("image exceeds %d MB", bus.MaxImageBytes>>20),
+		})
+	}
+	mimeType := http.DetectContentType(data)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": "only images can be attached"})
+	}
 
-func (s *Server) handlePutChatSessionPrompts(c *echo.Context) error {
-	if s.prompts == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+	upload := chatUpload{
+		owner: username,
+		attachment: bus.Attachment{
+			Type:     bus.MessageTypeImage,
+			Name:     filepath.Base(header.Filename),
+			MIMEType: mimeType,
+			Data:     data,
+		},
+		expiresAt: time.Now().Add(chatUploadTTL),
 	}
-	var body struct {
-		SystemPromptIDs []string `json:"system_prompt_ids"`
-		UserPromptIDs   []string `json:"user_prompt_ids"`
+	id := uuid.NewString()
+	s.chatUploadMu.Lock()
+	if s.chatUploads == nil {
+		s.chatUploads = make(map[string]chatUpload)
 	}
-	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	for key, pending := range s.chatUploads {
+		if time.Now().After(pending.expiresAt) {
+			delete(s.chatUploads, key)
+		}
 	}
-	sessionID, err := s.resolveWebUIChatSessionAlias(c, c.Param("id"))
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	s.chatUploads[id] = upload
+	s.chatUploadMu.Unlock()
+
+	return c.JSON(http.StatusCreated, chatUploadResponse{
+		ID:       id,
+		Name:     upload.attachment.Name,
+		MIMEType: mimeType,
+		Size:     len(data),
+	})
+}
+
+// chatFileTTL bounds how long files the agent sent to a WebUI chat can be
+// downloaded. They are kept in memory only.
+const chatFileTTL = time.Hour
+
+// deliverWebUIOutbound shows an outbound bus message for the WebUI, such as
+// a file sent by the send_file tool, in the chat of the session's user.
+func (s *Server) deliverWebUIOutbound(ctx context.Context, msg *bus.Message) error {
+	owner := webUIChatSessionOwner(msg.SessionID)
+	if owner == "" {
+		return fmt.Errorf("webui message for unknown chat session %q", msg.SessionID)
+	}
+
+	event := chatEvent{
+		SessionID: msg.SessionID,
+		Role:      "assistant",
+		Content:   msg.Content,
+		Timestamp: time.Now().Unix(),
 	}
-	result, err := s.prompts.ReplaceSessionBindings(c.Request().Context(), sessionID, body.SystemPromptIDs, body.UserPromptIDs)
-	if err != nil {
-		status := http.StatusBadRequest
-		if errors.Is(err, prompts.ErrPromptNotFound) {
-			status = http.StatusNotFound
+	for _, attachment := range msg.Attachments {
+		data, err := attachment.ReadData()
+		if err != nil {
+			return err
 		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
+		file := chatUploadResponse{
+			Name:     attachment.FileName(),
+			MIMEType: attachment.MIMEType,
+			Size:     len(data),
+			URL:      attachment.URL,
+		}
+		if len(data) > 0 {
+			if file.MIMEType == "" {
+				file.MIMEType = http.DetectContentType(data)
+			}
+			attachment.Data = data
+			attachment.MIMEType = file.MIMEType
+			file.ID = s.storeChatFile(owner, attachment)
+			file.URL = "/api/chat/files/" + file.ID
+		}
+		event.Attachments = append(event.Attachments, file)
 	}
-	return c.JSON(http.StatusOK, result)
+	s.publishChatEvent(event)
+	return nil
 }
 
-func (s *Server) handleDeleteChatSessionPrompts(c *echo.Context) error {
-	if s.prompts == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "prompt manager not available"})
+func (s *Server) storeChatFile(owner string, attachment bus.Attachment) string {
+	id := uuid.NewString()
+	now := time.Now()
+	s.chatUploadMu.Lock()
+	defer s.chatUploadMu.Unlock()
+	if s.chatFiles == nil {
+		s.chatFiles = make(map[string]chatUpload)
 	}
-	sessionID, err := s.resolveWebUIChatSessionAlias(c, c.Param("id"))
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	for key, file := range s.chatFiles {
+		if now.After(file.expiresAt) {
+			delete(s.chatFiles, key)
+		}
 	}
-	if err := s.prompts.ClearSessionBindings(c.Request().Context(), sessionID); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	s.chatFiles[id] = chatUpload{owner: owner, attachment: attachment, expiresAt: now.Add(chatFileTTL)}
+	return id
+}
+
+func (s *Server) handleGetChatFile(c *echo.Context) error {
+	id := strings.TrimSpace(c.Param("id"))
+	s.chatUploadMu.Lock()
+	file, ok := s.chatFiles[id]
+	s.chatUploadMu.Unlock()
+	if !ok || file.owner != s.currentUsername(c) || time.Now().After(file.expiresAt) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "file not found or expired"})
+	}
+	c.Response().Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{
+		"filename": file.attachment.FileName(),
+	}))
+	return c.Blob(http.StatusOK, file.attachment.MIMEType, file.attachment.Data)
+}
+
+// webUIChatSessionOwner returns the user of a WebUI chat session ID, with or
+// without a runtime prefix.
+func webUIChatSessionOwner(sessionID string) string {
+	index := strings.LastIndex(sessionID, "webui-chat:")
+	if index < 0 {
+		return ""
 	}
-	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+	return strings.TrimSpace(sessionID[index+len("webui-chat:"):])
 }
 
-func (s *Server) handleUndoChatSession(c *echo.Context) error {
-	if s.sessionMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "session manager not available"})
+// takeChatUploads removes and returns the uploads a message refers to. Each
+// upload is used by one message only, and only by the user who uploaded it.
+func (s *Server) takeChatUploads(username string, ids []string) ([]bus.Attachment, error) {
+	if len(ids) == 0 {
+		return nil, nil
 	}
-	if s.snapshotMgr == nil {
-		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "undo is not available"})
+	s.chatUploadMu.Lock()
+	defer s.chatUploadMu.Unlock()
+
+	now := time.Now()
+	attachments := make([]bus.Attachment, 0, len(ids))
+	for _, id := range ids {
+		upload, ok := s.chatUploads[strings.TrimSpace(id)]
+		if !ok || upload.owner != username || now.After(upload.expiresAt) {
+			return nil, fmt.Errorf("attachment %s not found or expired, upload it again", id)
+		}
+		attachments = append(attachments, upload.attachment)
+	}
+	for _, id := range ids {
+		delete(s.chatUploads, strings.TrimSpace(id))
 	}
+	return attachments, nil
+}
 
-	sessionID, err := s.resolveWebUIChatSessionAlias(c, c.Param("id"))
+// --- Approval Handlers ---
+
+func (s *Server) handleGetApprovals(c *echo.Context) error {
+	pending := s.approval.GetPending()
+	return c.JSON(http.StatusOK, pending)
+}
+
+// handleGetApprovalHistory lists decided and expired approvals, newest
+// first, for auditing who approved or denied what.
+func (s *Server) handleGetApprovalHistory(c *echo.Context) error {
+	query := approval.HistoryQuery{
+		SessionID: strings.TrimSpace(c.QueryParam("session_id")),
+		ToolName:  strings.TrimSpace(c.QueryParam("tool_name")),
+		Decision:  approval.Decision(strings.TrimSpace(c.QueryParam("decision"))),
+	}
+	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
+		var parsed int
+		if _, err := fmt.Sscanf(raw, "%d", &parsed); err == nil && parsed > 0 && parsed <= 1000 {
+			query.Limit = parsed
+		}
+	}
+	history, err := s.approval.History(c.Request().Context(), query)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, history)
+}
 
+// handleApprovalDryRun reports how a hypothetical tool call would be
+// treated: the matching permission rule, which takes precedence, and the
+// approval rule or mode behind it. Nothing is queued or run.
+func (s *Server) handleApprovalDryRun(c *echo.Context) error {
 	var body struct {
-		Steps int `json:"steps"`
+		ToolName  string                 `json:"tool_name"`
+		Arguments map[string]interface{} `json:"arguments"`
+		SessionID string                 `json:"session_id"`
+		RuntimeID string                 `json:"runtime_id"`
 	}
-	if err := c.Bind(&body); err != nil && !errors.Is(err, io.EOF) {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
 	}
-	steps := body.Steps
-	if steps <= 0 {
-		steps = 1
+	body.ToolName = strings.TrimSpace(body.ToolName)
+	if body.ToolName == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "tool_name is required"})
 	}
-
-	sess, err := s.sessionMgr.GetExisting(sessionID)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load session: %v", err)})
+	if s.approval == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "approval manager is unavailable"})
 	}
 
-	store := s.snapshotMgr.GetStore(sessionID)
-	if err := store.LoadSnapshots(); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to load undo snapshots: %v", err)})
+	response := map[string]any{
+		"approval": s.approval.Evaluate(body.ToolName, body.Arguments, strings.TrimSpace(body.SessionID)),
 	}
-
-	undone := 0
-	var reverted []session.MessageSnapshot
-	for undone < steps && store.CanUndo() {
-		reverted, err = store.Undo()
+	if s.entClient != nil {
+		manager, err := permissionrules.NewManager(s.config, s.logger, s.entClient)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("undo failed: %v", err)})
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		}
-		undone++
-	}
-
-	if undone == 0 {
-		current := sess.GetMessages()
-		return c.JSON(http.StatusOK, map[string]interface{}{
-			"undone_steps":    0,
-			"remaining_turns": store.GetTurnCount(),
-			"message_count":   len(current),
-			"messages":        buildSessionMessageResponses(current),
+		result, err := manager.Evaluate(c.Request().Context(), permissionrules.Input{
+			ToolName:  body.ToolName,
+			SessionID: body.SessionID,
+			RuntimeID: body.RuntimeID,
 		})
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		if result.Matched {
+			response["permission_rule"] = result
+		}
 	}
-
-	messages := session.MessageSnapshotsToMessages(reverted)
-	sess.ReplaceMessages(messages)
-	if err := s.sessionMgr.Save(sess); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to save reverted session: %v", err)})
-	}
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"undone_steps":    undone,
-		"remaining_turns": store.GetTurnCount(),
-		"message_count":   len(messages),
-		"messages":        buildSessionMessageResponses(messages),
-	})
+	return c.JSON(http.StatusOK, response)
 }
 
-// --- Approval Handlers ---
-
-func (s *Server) handleGetApprovals(c *echo.Context) error {
-	pending := s.approval.GetPending()
-	return c.JSON(http.StatusOK, pending)
+// approvalDeciderError returns the status and message refusing the
+// caller's decision on req, or 0 when they may decide it. Requests no longer
+// pending conflict, and requests acting for a person need an admin.
+func (s *Server) approvalDeciderError(c *echo.Context, req *approval.Request) (int, string) {
+	if req == nil {
+		return 0, ""
+	}
+	if req.Decision != approval.Pending {
+		return http.StatusConflict, fmt.Sprintf("request %s is already %s", req.ID, req.Decision)
+	}
+	if req.RequestedBy() == "" {
+		return 0, ""
+	}
+	switch s.currentUserRole(c) {
+	case config.RoleAdmin, config.RoleOwner:
+		return 0, ""
+	}
+	return http.StatusForbidden, "deciding this request requires an admin"
 }
 
 func (s *Server) handleApproveRequest(c *echo.Context) error {
 	id := c.Param("id")
 	req, _ := s.approval.GetRequest(id)
-	if err := s.approval.Approve(id); err != nil {
+	if status, msg := s.approvalDeciderError(c, req); status != 0 {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+	if err := s.approval.ApproveAs(id, s.currentUsername(c)); err != nil {
+		if errors.Is(err, approval.ErrSelfApproval) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
+	if req != nil && req.ToolName == toolSessionSpawnApprovalTool {
+		// The requester receives the launched session and its access
+		// details; the approver only learns whether it started.
+		body := map[string]any{"status": "approved", "id": id}
+		if result, ok := s.toolSpawnResult(id); ok {
+			if result.Session != nil {
+				body["session_id"] = result.Session.ID
+			}
+			if result.Error != "" {
+				body["error"] = "failed to launch approved tool session: " + result.Error
+				return c.JSON(http.StatusBadRequest, body)
+			}
+		}
+		return c.JSON(http.StatusOK, body)
+	}
 	if req != nil && s.approval != nil && isExternalAgentApprovalRequest(req) {
 		s.approval.SetSessionMode(req.SessionID, approval.ModeAuto)
 		if s.toolSess != nil {
@@ -9688,7 +13919,10 @@ func (s *Server) handleDenyRequest(c *echo.Context) error {
 	_ = c.Bind(&body) // reason is optional
 
 	req, _ := s.approval.GetRequest(id)
-	if err := s.approval.Deny(id, body.Reason); err != nil {
+	if status, msg := s.approvalDeciderError(c, req); status != 0 {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+	if err := s.approval.DenyAs(id, s.currentUsername(c), body.Reason); err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
 	approval.ClearPendingToolCall(id)
@@ -9705,6 +13939,117 @@ func (s *Server) handleDenyRequest(c *echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+// approvalWSEvent is one message of the approvals WebSocket: a request
+// that was queued, decided or expired, or the outcome of the caller's own
+// tool session spawn request.
+type approvalWSEvent struct {
+	Type        string                  `json:"type"`
+	Request     *approval.Request       `json:"request,omitempty"`
+	SpawnResult *toolSessionSpawnResult `json:"spawn_result,omitempty"`
+}
+
+// handleApprovalsWS streams approval requests as they are queued and
+// decided. Admins see every request; everyone else sees their own, plus
+// the launched session of their approved spawn requests.
+func (s *Server) handleApprovalsWS(c *echo.Context) error {
+	tokenStr := strings.TrimSpace(c.QueryParam("token"))
+	if tokenStr == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "token required"})
+	}
+	username, _, role, err := s.parseScopedStreamToken(tokenStr, streamTokenPurposeApprovalsWS)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+	}
+	if s.approval == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "approval manager not available"})
+	}
+	admin := role == config.RoleAdmin || role == config.RoleOwner
+
+	conn, err := s.upgradeWebSocket(c)
+	if err != nil {
+		s.logger.Error("WebUI approvals WS upgrade failed", zap.Error(err))
+		return nil
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	requests, cancelRequests := s.approval.Subscribe()
+	defer cancelRequests()
+	results, cancelResults := s.subscribeToolSessionSpawnResults(username)
+	defer cancelResults()
+
+	// The client only answers pings; reading detects when it goes away.
+	closed := make(chan struct{})
+	if err := conn.SetReadDeadline(time.Now().Add(120 * time.Second)); err != nil {
+		s.logger.Warn("Failed to set approvals read deadline", zap.Error(err))
+	}
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(120 * time.Second))
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	write := func(messageType int, data []byte) bool {
+		if err := conn.SetWriteDeadline(time.Now().Add(10 * time.Second)); err != nil {
+			return false
+		}
+		return conn.WriteMessage(messageType, data) == nil
+	}
+	send := func(event approvalWSEvent) bool {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return true
+		}
+		return write(websocket.TextMessage, data)
+	}
+
+	for _, req := range s.approval.GetPending() {
+		if admin || req.RequestedBy() == username {
+			pending := *req
+			if !send(approvalWSEvent{Type: "approval", Request: &pending}) {
+				return nil
+			}
+		}
+	}
+
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return nil
+		case <-ticker.C:
+			if !write(websocket.PingMessage, nil) {
+				return nil
+			}
+		case req, ok := <-requests:
+			if !ok {
+				return nil
+			}
+			if !admin && req.RequestedBy() != username {
+				continue
+			}
+			if !send(approvalWSEvent{Type: "approval", Request: &req}) {
+				return nil
+			}
+		case result, ok := <-results:
+			if !ok {
+				return nil
+			}
+			if !send(approvalWSEvent{Type: "tool_session_spawn", SpawnResult: &result}) {
+				return nil
+			}
+		}
+	}
+}
+
 func (s *Server) currentSessionRuntimeState(sessionID string) *tasks.SessionState {
 	if s == nil || s.taskStore == nil {
 		return nil
@@ -9818,6 +14163,87 @@ func (s *Server) handleDeletePermissionRule(c *echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"status": "deleted", "id": id})
 }
 
+func (s *Server) handleListAPIKeys(c *echo.Context) error {
+	manager, err := apikeys.NewManager(s.entClient)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	userID := s.currentUserID(c)
+	if s.authContextFromEcho(c).SpansTenants() {
+		userID = ""
+	}
+	items, err := manager.List(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+func (s *Server) handleCreateAPIKey(c *echo.Context) error {
+	if c.Get(apiKeyContextKey) != nil {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "api keys cannot manage api keys"})
+	}
+	var body struct {
+		Name          string   `json:"name"`
+		Scopes        []string `json:"scopes"`
+		ExpiresInDays int      `json:"expires_in_days"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	if body.ExpiresInDays < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "expires_in_days must not be negative"})
+	}
+	input := apikeys.CreateInput{
+		Name:     body.Name,
+		UserID:   s.currentUserID(c),
+		TenantID: s.currentTenantID(c),
+		Scopes:   body.Scopes,
+	}
+	if body.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, body.ExpiresInDays)
+		input.ExpiresAt = &expiresAt
+	}
+	manager, err := apikeys.NewManager(s.entClient)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	key, secret, err := manager.Create(c.Request().Context(), input)
+	if err != nil {
+		if errors.Is(err, apikeys.ErrInvalidInput) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, map[string]interface{}{"api_key": key, "key": secret})
+}
+
+func (s *Server) handleRevokeAPIKey(c *echo.Context) error {
+	if c.Get(apiKeyContextKey) != nil {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "api keys cannot manage api keys"})
+	}
+	manager, err := apikeys.NewManager(s.entClient)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	ctx := c.Request().Context()
+	id := strings.TrimSpace(c.Param("id"))
+	key, err := manager.Get(ctx, id)
+	if err == nil && key.UserID != s.currentUserID(c) && !s.authContextFromEcho(c).SpansTenants() {
+		err = apikeys.ErrKeyNotFound
+	}
+	if err == nil {
+		err = manager.Revoke(ctx, id)
+	}
+	if err != nil {
+		if errors.Is(err, apikeys.ErrKeyNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "api key not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "revoked", "id": id})
+}
+
 func (s *Server) handleGetPolicyPresets(c *echo.Context) error {
 	return c.JSON(http.StatusOK, policy.Presets())
 }
@@ -9841,6 +14267,11 @@ const (
 	streamTokenPurposeChatEvents    streamTokenPurpose = "chat_events"
 	streamTokenPurposeChatWS        streamTokenPurpose = "chat_ws"
 	streamTokenPurposeToolSessionWS streamTokenPurpose = "tool_session_ws"
+	streamTokenPurposeChatStream    streamTokenPurpose = "chat_stream"
+	// streamTokenPurposeWhatsAppPairing streams the WhatsApp pairing QR code.
+	streamTokenPurposeWhatsAppPairing streamTokenPurpose = "whatsapp_pairing"
+	// streamTokenPurposeApprovalsWS streams approval requests and decisions.
+	streamTokenPurposeApprovalsWS streamTokenPurpose = "approvals_ws"
 )
 
 func normalizeStreamTokenPurpose(raw string) streamTokenPurpose {
@@ -9851,6 +14282,12 @@ func normalizeStreamTokenPurpose(raw string) streamTokenPurpose {
 		return streamTokenPurposeChatWS
 	case string(streamTokenPurposeToolSessionWS):
 		return streamTokenPurposeToolSessionWS
+	case string(streamTokenPurposeChatStream):
+		return streamTokenPurposeChatStream
+	case string(streamTokenPurposeWhatsAppPairing):
+		return streamTokenPurposeWhatsAppPairing
+	case string(streamTokenPurposeApprovalsWS):
+		return streamTokenPurposeApprovalsWS
 	default:
 		return ""
 	}
@@ -9873,15 +14310,19 @@ func (s *Server) handleCreateStreamToken(c *echo.Context) error {
 		if sessionID == "" {
 			return c.JSON(http.StatusBadRequest, map[string]string{"error": "session_id required"})
 		}
-		if err := s.ensureSessionOwner(c, sessionID); err != nil {
+		if ok, err := s.ensureSessionOwner(c, sessionID); !ok {
 			return err
 		}
 	}
+	if purpose == streamTokenPurposeWhatsAppPairing && !s.canManageChannels(c) {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "channel management requires an operator role"})
+	}
 	now := time.Now()
 	claims := jwt.MapClaims{
 		"sub":  s.currentUsername(c),
 		"uid":  s.currentUserID(c),
 		"tid":  s.currentTenantID(c),
+		"ts":   s.currentTenantSlug(c),
 		"role": s.currentUserRole(c),
 		"pur":  string(purpose),
 		"exp":  now.Add(5 * time.Minute).Unix(),
@@ -9899,11 +14340,30 @@ func (s *Server) handleCreateStreamToken(c *echo.Context) error {
 }
 
 func (s *Server) parseScopedStreamToken(tokenStr string, purpose streamTokenPurpose) (string, string, string, error) {
-	sub, sid, role, _, _, err := s.parseScopedStreamTokenClaims(tokenStr, purpose)
-	return sub, sid, role, err
+	claims, err := s.parseScopedStreamTokenClaims(tokenStr, purpose)
+	return claims.Subject, claims.SessionID, claims.Role, err
 }
 
-func (s *Server) parseScopedStreamTokenClaims(tokenStr string, purpose streamTokenPurpose) (string, string, string, string, string, error) {
+// streamTokenClaims are the identity claims of a scoped stream token.
+type streamTokenClaims struct {
+	Subject    string
+	SessionID  string
+	Role       string
+	UserID     string
+	TenantID   string
+	TenantSlug string
+}
+
+func (c streamTokenClaims) authContext() ownership.AuthContext {
+	return ownership.AuthContext{
+		UserID:     c.UserID,
+		TenantID:   c.TenantID,
+		TenantSlug: c.TenantSlug,
+		Role:       c.Role,
+	}
+}
+
+func (s *Server) parseScopedStreamTokenClaims(tokenStr string, purpose streamTokenPurpose) (streamTokenClaims, error) {
 	parsed, err := jwt.Parse(strings.TrimSpace(tokenStr), func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method")
@@ -9911,25 +14371,33 @@ func (s *Server) parseScopedStreamTokenClaims(tokenStr string, purpose streamTok
 		return []byte(s.getJWTSecret()), nil
 	})
 	if err != nil || parsed == nil || !parsed.Valid {
-		return "", "", "", "", "", fmt.Errorf("invalid token")
+		return streamTokenClaims{}, fmt.Errorf("invalid token")
 	}
 	claims, ok := parsed.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", "", "", "", "", fmt.Errorf("invalid token claims")
+		return streamTokenClaims{}, fmt.Errorf("invalid token claims")
 	}
 	claimedPurpose, _ := claims["pur"].(string)
 	if normalizeStreamTokenPurpose(claimedPurpose) != purpose {
-		return "", "", "", "", "", fmt.Errorf("invalid token purpose")
+		return streamTokenClaims{}, fmt.Errorf("invalid token purpose")
 	}
 	sub, _ := claims["sub"].(string)
 	sid, _ := claims["sid"].(string)
 	role, _ := claims["role"].(string)
 	uid, _ := claims["uid"].(string)
 	tid, _ := claims["tid"].(string)
+	ts, _ := claims["ts"].(string)
 	if strings.TrimSpace(sub) == "" {
-		return "", "", "", "", "", fmt.Errorf("subject is empty")
-	}
-	return strings.TrimSpace(sub), strings.TrimSpace(sid), strings.TrimSpace(role), strings.TrimSpace(uid), strings.TrimSpace(tid), nil
+		return streamTokenClaims{}, fmt.Errorf("subject is empty")
+	}
+	return streamTokenClaims{
+		Subject:    strings.TrimSpace(sub),
+		SessionID:  strings.TrimSpace(sid),
+		Role:       strings.TrimSpace(role),
+		UserID:     strings.TrimSpace(uid),
+		TenantID:   strings.TrimSpace(tid),
+		TenantSlug: strings.TrimSpace(ts),
+	}, nil
 }
 
 func (s *Server) parseJWTSubject(tokenStr string) (string, error) {
@@ -9972,7 +14440,7 @@ func (s *Server) resolveWebUIChatSessionAlias(c *echo.Context, rawID string) (st
 	if err != nil {
 		return "", fmt.Errorf("invalid token")
 	}
-	return webUIRuntimeChatSessionID(username, runtimeID), nil
+	return s.chatSessionID(c.Request().Context(), username, runtimeID), nil
 }
 
 func resolveWebUIChatRuntimeAlias(sessionID string) (string, bool) {
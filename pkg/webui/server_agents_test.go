@@ -0,0 +1,77 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+
+	"nekobot/pkg/config"
+)
+
+func TestAgentProfileCRUD(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	s := &Server{config: cfg}
+	e := echo.New()
+
+	call := func(method, target, name, body string, handler func(*echo.Context) error) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		if name != "" {
+			ctx.SetPath("/api/agents/:name")
+			ctx.SetPathValues(echo.PathValues{{Name: "name", Value: name}})
+		}
+		if err := handler(ctx); err != nil {
+			t.Fatalf("%s %s failed: %v", method, target, err)
+		}
+		return rec
+	}
+
+	rec := call(http.MethodPost, "/api/agents", "", `{"name":" coder ","model":"coder-model","tools":["read_*","read_*"],"channels":["discord"]}`, s.handleCreateAgentProfile)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	profile, ok := cfg.Agents.Profile("coder")
+	if !ok || profile.Model != "coder-model" || len(profile.Tools) != 1 {
+		t.Fatalf("expected normalized profile in config, got %+v", cfg.Agents.Profiles)
+	}
+
+	rec = call(http.MethodPost, "/api/agents", "", `{"name":"coder"}`, s.handleCreateAgentProfile)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected duplicate to conflict, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = call(http.MethodPost, "/api/agents", "", `{"name":"support","channels":["discord"]}`, s.handleCreateAgentProfile)
+	if rec.Code != http.StatusBadRequest || len(cfg.Agents.Profiles) != 1 {
+		t.Fatalf("expected channel clash to be rejected, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = call(http.MethodPut, "/api/agents/coder", "coder", `{"system_prompt":"You write Go.","channels":["slack"]}`, s.handleUpdateAgentProfile)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	rec = call(http.MethodGet, "/api/agents", "", "", s.handleListAgentProfiles)
+	var listed []config.AgentProfileConfig
+	if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("unmarshal list: %v", err)
+	}
+	if len(listed) != 1 || listed[0].Name != "coder" || listed[0].SystemPrompt != "You write Go." || listed[0].Channels[0] != "slack" {
+		t.Fatalf("unexpected listed profiles %+v", listed)
+	}
+
+	rec = call(http.MethodDelete, "/api/agents/coder", "coder", "", s.handleDeleteAgentProfile)
+	if rec.Code != http.StatusOK || len(cfg.Agents.Profiles) != 0 {
+		t.Fatalf("expected profile to be deleted, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = call(http.MethodDelete, "/api/agents/coder", "coder", "", s.handleDeleteAgentProfile)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected missing profile to 404, got %d", rec.Code)
+	}
+}
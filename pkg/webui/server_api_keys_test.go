@@ -0,0 +1,75 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v5"
+
+	"nekobot/pkg/config"
+)
+
+func TestAPIKeysAuthenticateAsTheirUserWithinScopes(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Errorf("close ent client: %v", err)
+		}
+	})
+	s := &Server{config: cfg, logger: newTestLogger(t), entClient: client}
+	createTestUser(t, client, "ci-bot", config.RoleOperator, true)
+	profile, err := config.BuildAuthProfileByUsername(t.Context(), client, "ci-bot")
+	if err != nil {
+		t.Fatalf("load profile: %v", err)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/api-keys", strings.NewReader(`{"name":"ci","scopes":["read"],"expires_in_days":30}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"uid": profile.UserID, "role": profile.Role}))
+	if err := s.handleCreateAPIKey(c); err != nil {
+		t.Fatalf("handleCreateAPIKey failed: %v", err)
+	}
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode create response: %v", err)
+	}
+
+	handler := s.authenticateAPIKey()(s.requirePrivilegedAPIUser()(func(c *echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"uid": s.currentUserID(c), "role": s.currentUserRole(c)})
+	}))
+	call := func(method, path, secret string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+secret)
+		rec := httptest.NewRecorder()
+		if err := handler(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("%s %s: handler failed: %v", method, path, err)
+		}
+		return rec
+	}
+
+	rec = call(http.MethodGet, "/api/providers", created.Key)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), profile.UserID) || !strings.Contains(rec.Body.String(), config.RoleOperator) {
+		t.Fatalf("expected read key to act as its user, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec = call(http.MethodPut, "/api/providers/openai", created.Key); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected read key to be refused writes, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec = call(http.MethodGet, "/api/providers", created.Key+"0"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unknown key to be rejected, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
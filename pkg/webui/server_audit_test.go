@@ -4,12 +4,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v5"
 
 	"nekobot/pkg/audit"
+	"nekobot/pkg/config"
 )
 
 func TestHandleGetHarnessAudit(t *testing.T) {
@@ -116,3 +119,81 @@ func TestHandleClearHarnessAudit(t *testing.T) {
 		t.Fatalf("expected removed audit file stats, got %+v", payload.Stats)
 	}
 }
+
+func TestLoginEventsAreAuditedAndListedPerTenant(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Errorf("close ent client: %v", err)
+		}
+	})
+	s := &Server{config: cfg, logger: newTestLogger(t), entClient: client}
+	createTestUser(t, client, "alice", config.RoleOwner, true)
+
+	e := echo.New()
+	login := func(password string) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{"username":"alice","password":"`+password+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "192.0.2.7:5000"
+		rec := httptest.NewRecorder()
+		if err := s.handleLogin(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("handleLogin failed: %v", err)
+		}
+		return rec.Code
+	}
+	if code := login("wrong"); code != http.StatusUnauthorized {
+		t.Fatalf("expected failed login, got %d", code)
+	}
+	if code := login("secret-123"); code != http.StatusOK {
+		t.Fatalf("expected successful login, got %d", code)
+	}
+
+	list := func(tenantSlug, tenantID, query string) []audit.Event {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/audit?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"role": config.RoleOwner, "tid": tenantID, "ts": tenantSlug}))
+		if err := s.handleListAuditEvents(c); err != nil {
+			t.Fatalf("handleListAuditEvents failed: %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		var payload struct {
+			Events []audit.Event `json:"events"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return payload.Events
+	}
+
+	events := list("default", "", "action=auth")
+	if len(events) != 2 {
+		t.Fatalf("expected two login events, got %+v", events)
+	}
+	if !events[0].Success || events[0].ActorID == "" || events[0].IP != "192.0.2.7" {
+		t.Fatalf("expected successful login with actor and ip first, got %+v", events[0])
+	}
+	if events[1].Success || events[1].Actor != "alice" || events[1].Summary != "invalid credentials" {
+		t.Fatalf("expected failed login second, got %+v", events[1])
+	}
+	if got := list("team-b", "tenant-b", ""); len(got) != 0 {
+		t.Fatalf("expected other tenants to see no events, got %+v", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/audit/export?actor=alice", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"role": config.RoleOwner, "ts": "default"}))
+	if err := s.handleExportAuditEvents(c); err != nil {
+		t.Fatalf("handleExportAuditEvents failed: %v", err)
+	}
+	if lines := strings.Count(strings.TrimSpace(rec.Body.String()), "\n") + 1; rec.Code != http.StatusOK || lines != 2 {
+		t.Fatalf("expected two exported lines, got %d (%d): %s", lines, rec.Code, rec.Body.String())
+	}
+}
@@ -8,13 +8,17 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v5"
 
 	"nekobot/pkg/bus"
 	"nekobot/pkg/channelaccounts"
+	"nekobot/pkg/channelidentity"
 	"nekobot/pkg/channels"
 	"nekobot/pkg/channels/slack"
+	channelwhatsapp "nekobot/pkg/channels/whatsapp"
 	"nekobot/pkg/config"
 	"nekobot/pkg/ilinkauth"
 	wxtypes "nekobot/pkg/wechat/types"
@@ -529,3 +533,150 @@ func TestHandleTestChannelUsesHealthCheckSuccess(t *testing.T) {
 		t.Fatalf("expected reachable=true, got %#v", payload["reachable"])
 	}
 }
+
+type testIdentityChannel struct {
+	testConfiguredChannel
+	identity *channelidentity.Cache
+}
+
+func (c *testIdentityChannel) Identity(ctx context.Context) (channels.Identity, error) {
+	return c.identity.Get(ctx)
+}
+
+func TestHandleGetChannelIdentityServesCachedIdentity(t *testing.T) {
+	log := newTestLogger(t)
+	manager := channels.NewManager(log, bus.NewLocalBus(log, 8))
+	fetches := 0
+	ch := &testIdentityChannel{
+		testConfiguredChannel: testConfiguredChannel{id: "telegram", name: "Telegram", enabled: true},
+		identity: channelidentity.NewCache(func(context.Context) (channelidentity.Identity, error) {
+			fetches++
+			return channelidentity.Identity{ID: "42", Username: "mybot"}, nil
+		}, channelidentity.DefaultTTL),
+	}
+	if err := manager.Register(ch); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	s := &Server{logger: log, channels: manager}
+
+	for i := 0; i < 2; i++ {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/channels/telegram/identity", nil), rec)
+		c.SetPath("/api/channels/:name/identity")
+		c.SetPathValues(echo.PathValues{{Name: "name", Value: "telegram"}})
+
+		if err := s.handleGetChannelIdentity(c); err != nil {
+			t.Fatalf("handleGetChannelIdentity failed: %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		var payload struct {
+			Supported bool                     `json:"supported"`
+			Identity  channelidentity.Identity `json:"identity"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal response failed: %v", err)
+		}
+		if !payload.Supported || payload.Identity.Username != "mybot" {
+			t.Fatalf("unexpected identity payload: %s", rec.Body.String())
+		}
+	}
+	if fetches != 1 {
+		t.Fatalf("expected identity to be fetched once, got %d", fetches)
+	}
+}
+
+func TestHandleGetChannelIdentityReportsUnsupportedChannel(t *testing.T) {
+	log := newTestLogger(t)
+	manager := channels.NewManager(log, bus.NewLocalBus(log, 8))
+	if err := manager.Register(&testConfiguredChannel{id: "stub", name: "Stub", enabled: true}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	s := &Server{logger: log, channels: manager}
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/channels/stub/identity", nil), rec)
+	c.SetPath("/api/channels/:name/identity")
+	c.SetPathValues(echo.PathValues{{Name: "name", Value: "stub"}})
+
+	if err := s.handleGetChannelIdentity(c); err != nil {
+		t.Fatalf("handleGetChannelIdentity failed: %v", err)
+	}
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"supported":false`) {
+		t.Fatalf("expected unsupported identity response, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleGetWhatsAppStatusRendersPairingQRCode(t *testing.T) {
+	log := newTestLogger(t)
+	upgrader := websocket.Upgrader{}
+	bridge := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.WriteJSON(map[string]string{"type": "qr", "qr": "2@pairing-code"})
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer bridge.Close()
+
+	messageBus := bus.NewLocalBus(log, 8)
+	ch, err := channelwhatsapp.NewChannel(log, config.WhatsAppConfig{
+		Enabled:   true,
+		BridgeURL: "ws" + strings.TrimPrefix(bridge.URL, "http"),
+	}, messageBus, nil)
+	if err != nil {
+		t.Fatalf("NewChannel failed: %v", err)
+	}
+	manager := channels.NewManager(log, messageBus)
+	if err := manager.Register(ch); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := ch.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer ch.Stop(context.Background())
+	s := &Server{logger: log, channels: manager}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for ch.Status().State != channelwhatsapp.StatePairing {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the channel to wait for pairing, got %+v", ch.Status())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/channels/whatsapp/status", nil), rec)
+	if err := s.handleGetWhatsAppStatus(c); err != nil {
+		t.Fatalf("handleGetWhatsAppStatus failed: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		State        string `json:"state"`
+		QRCode       string `json:"qr_code"`
+		QRPNGDataURL string `json:"qr_png_data_url"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal response failed: %v", err)
+	}
+	if payload.State != channelwhatsapp.StatePairing || payload.QRCode != "2@pairing-code" || !strings.HasPrefix(payload.QRPNGDataURL, "data:image/png;base64,") {
+		t.Fatalf("unexpected status payload: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/api/channels/whatsapp/status?channel_id=whatsapp:missing", nil), rec)
+	if err := s.handleGetWhatsAppStatus(c); err != nil {
+		t.Fatalf("handleGetWhatsAppStatus failed: %v", err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d for a missing channel, got %d", http.StatusNotFound, rec.Code)
+	}
+}
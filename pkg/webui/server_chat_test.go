@@ -1,24 +1,36 @@
 package webui
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v5"
 
 	"nekobot/pkg/accountbindings"
 	"nekobot/pkg/agent"
 	"nekobot/pkg/approval"
+	"nekobot/pkg/bus"
 	"nekobot/pkg/channelaccounts"
 	"nekobot/pkg/config"
+	"nekobot/pkg/ownership"
+	"nekobot/pkg/providers"
 	"nekobot/pkg/runtimeagents"
 	"nekobot/pkg/session"
+	"nekobot/pkg/state"
 	"nekobot/pkg/tasks"
+	"nekobot/pkg/userprefs"
 )
 
 func TestChatRouteStateJSONIncludesContextPressureFields(t *testing.T) {
@@ -107,7 +119,7 @@ func TestPersistChatRoutingPersistsModel(t *testing.T) {
 	}
 
 	s := &Server{config: cfg}
-	if err := s.persistChatRouting("anthropic", "new-model", []string{"openai"}); err != nil {
+	if err := s.persistChatRouting(ownership.AuthContext{}, "anthropic", "new-model", []string{"openai"}); err != nil {
 		t.Fatalf("persistChatRouting failed: %v", err)
 	}
 
@@ -237,6 +249,99 @@ func TestClearChatSessionRemovesUndoSnapshots(t *testing.T) {
 	}
 }
 
+func TestHandleUpdateChatDeveloperModeTogglesSession(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Sessions.Sources.WebUI = true
+	sessionMgr := session.NewManager(t.TempDir(), cfg.Sessions)
+	s := &Server{config: cfg, sessionMgr: sessionMgr}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPut, "/api/chat/session/webui-chat%3Atester/developer-mode", strings.NewReader(`{"enabled":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetPath("/api/chat/session/:id/developer-mode")
+	ctx.SetPathValues(echo.PathValues{{Name: "id", Value: "webui-chat:tester"}})
+
+	if err := s.handleUpdateChatDeveloperMode(ctx); err != nil {
+		t.Fatalf("handleUpdateChatDeveloperMode failed: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	sess, err := sessionMgr.GetExisting("webui-chat:tester")
+	if err != nil {
+		t.Fatalf("GetExisting failed: %v", err)
+	}
+	if !sess.GetDeveloperMode() {
+		t.Fatalf("expected developer mode to be enabled")
+	}
+}
+
+func TestHandleUpdateChatPersonaStoresSessionPersona(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Sessions.Sources.WebUI = true
+	sessionMgr := session.NewManager(t.TempDir(), cfg.Sessions)
+	s := &Server{config: cfg, sessionMgr: sessionMgr}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPut, "/api/chat/session/webui-chat%3Atester/persona", strings.NewReader(`{"persona":"  You are a pirate.  "}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetPath("/api/chat/session/:id/persona")
+	ctx.SetPathValues(echo.PathValues{{Name: "id", Value: "webui-chat:tester"}})
+
+	if err := s.handleUpdateChatPersona(ctx); err != nil {
+		t.Fatalf("handleUpdateChatPersona failed: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"persona":"You are a pirate."`) {
+		t.Fatalf("unexpected response body: %s", rec.Body.String())
+	}
+
+	sess, err := sessionMgr.GetExisting("webui-chat:tester")
+	if err != nil {
+		t.Fatalf("GetExisting failed: %v", err)
+	}
+	if got := sess.GetPersona(); got != "You are a pirate." {
+		t.Fatalf("expected persona to be stored, got %q", got)
+	}
+}
+
+func TestBuildChatRouteWSResponseCarriesDeveloperModeDebug(t *testing.T) {
+	plain := buildChatRouteWSResponse("webui-chat", "", agent.ChatRouteResult{ActualProvider: "openai"})
+	payload, err := json.Marshal(plain)
+	if err != nil {
+		t.Fatalf("marshal route response failed: %v", err)
+	}
+	if strings.Contains(string(payload), `"debug"`) {
+		t.Fatalf("expected no debug payload without developer mode, got %s", payload)
+	}
+
+	withDebug := buildChatRouteWSResponse("webui-chat", "", agent.ChatRouteResult{
+		ActualProvider: "openai",
+		Debug: &agent.TurnDebug{Requests: []agent.DebugRequest{{
+			Provider: "openai",
+			Model:    "gpt-5.4",
+			Request: providers.UnifiedRequest{
+				Model:    "gpt-5.4",
+				Messages: []providers.UnifiedMessage{{Role: "system", Content: "be brief"}, {Role: "user", Content: "hi"}},
+			},
+		}}},
+	})
+	if withDebug.Debug == nil || len(withDebug.Debug.Requests) != 1 {
+		t.Fatalf("expected one debug request, got %+v", withDebug.Debug)
+	}
+	got := withDebug.Debug.Requests[0]
+	if got.Provider != "openai" || got.Model != "gpt-5.4" || len(got.Request.Messages) != 2 {
+		t.Fatalf("unexpected debug request: %+v", got)
+	}
+}
+
 func TestBuildWebUIChatPromptContextIncludesExplicitRuntimeID(t *testing.T) {
 	ctx := buildWebUIChatPromptContext(
 		"webui-chat:alice",
@@ -482,3 +587,465 @@ func TestHandleChatWSQueuesDaemonTaskForDaemonBackedRuntime(t *testing.T) {
 		t.Fatalf("expected machine-a metadata, got %+v", items[0].Metadata)
 	}
 }
+
+func newWebSocketEchoServer(t *testing.T, s *Server) string {
+	t.Helper()
+	e := echo.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := s.upgradeWebSocket(e.NewContext(r, w))
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(messageType, data); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestUpgradeWebSocketNegotiatesCompression(t *testing.T) {
+	cfg := config.DefaultConfig()
+	url := newWebSocketEchoServer(t, &Server{config: cfg})
+
+	dialer := websocket.Dialer{EnableCompression: true}
+	conn, resp, err := dialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if ext := resp.Header.Get("Sec-WebSocket-Extensions"); !strings.Contains(ext, "permessage-deflate") {
+		t.Fatalf("expected permessage-deflate to be negotiated, got %q", ext)
+	}
+
+	payload := map[string]string{"type": "output", "data": strings.Repeat("\x1b[32mok\x1b[0m ", 200)}
+	if err := conn.WriteJSON(payload); err != nil {
+		t.Fatalf("write json failed: %v", err)
+	}
+	var echoed map[string]string
+	if err := conn.ReadJSON(&echoed); err != nil {
+		t.Fatalf("read json failed: %v", err)
+	}
+	if !reflect.DeepEqual(echoed, payload) {
+		t.Fatalf("expected compressed JSON frame to round-trip, got %#v", echoed)
+	}
+	if err := conn.WriteControl(websocket.PingMessage, []byte("hb"), time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("ping failed: %v", err)
+	}
+}
+
+func TestChatWSOversizedMessageYieldsErrorFrame(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.WebUI.ChatMaxMessageBytes = 64
+	s := &Server{config: cfg}
+	e := echo.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := s.upgradeWebSocket(e.NewContext(r, w))
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		limit := s.chatWSMaxMessageBytes()
+		conn.SetReadLimit(limit * chatWSHardLimitFactor)
+		for {
+			data, tooLarge, err := readChatWSMessage(conn, limit)
+			if err != nil {
+				return
+			}
+			if tooLarge {
+				sendWSError(conn, fmt.Sprintf("message too large: the limit is %d bytes", limit))
+				continue
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(strings.Repeat("x", 200))); err != nil {
+		t.Fatalf("write oversized message failed: %v", err)
+	}
+	var resp chatWSResponse
+	if err := conn.ReadJSON(&resp); err != nil {
+		t.Fatalf("expected an error frame, got read error: %v", err)
+	}
+	if resp.Type != "error" || !strings.Contains(resp.Content, "limit is 64 bytes") {
+		t.Fatalf("unexpected response to oversized message: %+v", resp)
+	}
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("small")); err != nil {
+		t.Fatalf("write after oversized message failed: %v", err)
+	}
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("expected connection to stay open: %v", err)
+	}
+	if string(data) != "small" {
+		t.Fatalf("unexpected echo %q", data)
+	}
+}
+
+func TestUpgradeWebSocketRespectsCompressionToggle(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.WebUI.WebSocketCompression = false
+	url := newWebSocketEchoServer(t, &Server{config: cfg})
+
+	dialer := websocket.Dialer{EnableCompression: true}
+	conn, resp, err := dialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if ext := resp.Header.Get("Sec-WebSocket-Extensions"); ext != "" {
+		t.Fatalf("expected no extensions when compression is disabled, got %q", ext)
+	}
+}
+
+func TestChatTurnStreamCallbacksEmitDeltasAndToolEvents(t *testing.T) {
+	s := &Server{}
+	var events []chatWSResponse
+	stream, progress := s.chatTurnStreamCallbacks("webui-chat", func(resp chatWSResponse) {
+		events = append(events, resp)
+	})
+
+	stream("Let me")
+	stream("Let me check")
+	stream("Let me check")
+	progress(agent.ToolProgress{ID: "call-1", Name: "web_search"})
+	progress(agent.ToolProgress{ID: "call-1", Name: "web_search", Done: true, Error: "timeout"})
+	stream("Let me check again")
+
+	want := []struct {
+		typ     string
+		content string
+		reset   bool
+	}{
+		{"delta", "Let me", false},
+		{"delta", " check", false},
+		{"tool", "web_search", false},
+		{"tool", "web_search", false},
+		{"delta", "Let me check again", true},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, expected := range want {
+		event := events[i]
+		if event.Type != expected.typ || event.Content != expected.content || event.SessionID != "webui-chat" {
+			t.Fatalf("event %d: expected %s %q, got %+v", i, expected.typ, expected.content, event)
+		}
+		meta, _ := event.Meta.(map[string]interface{})
+		if reset, _ := meta["reset"].(bool); reset != expected.reset {
+			t.Fatalf("event %d: expected reset=%v, got %+v", i, expected.reset, event.Meta)
+		}
+	}
+	finished, _ := events[3].Meta.(map[string]interface{})
+	if finished["done"] != true || finished["error"] != "timeout" || finished["id"] != "call-1" {
+		t.Fatalf("unexpected finished tool event meta: %+v", finished)
+	}
+}
+
+func TestHandleChatStreamRequiresScopedTokenAndMessage(t *testing.T) {
+	s := &Server{config: config.DefaultConfig(), logger: newTestLogger(t)}
+	signToken := func(purpose string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": "alice",
+			"pur": purpose,
+			"exp": time.Now().Add(time.Minute).Unix(),
+		})
+		signed, err := token.SignedString([]byte(s.getJWTSecret()))
+		if err != nil {
+			t.Fatalf("sign token: %v", err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{name: "missing token", query: "message=hi", status: http.StatusUnauthorized},
+		{name: "token for another purpose", query: "message=hi&token=" + signToken("chat_ws"), status: http.StatusUnauthorized},
+		{name: "missing message", query: "token=" + signToken("chat_stream"), status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/chat/stream?"+tt.query, nil), rec)
+			if err := s.handleChatStream(c); err != nil {
+				t.Fatalf("handleChatStream returned error: %v", err)
+			}
+			if rec.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestChatSessionHandlersScopeToCurrentUser(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Sessions.Sources.WebUI = true
+	sessionMgr := session.NewManager(t.TempDir(), cfg.Sessions)
+	s := &Server{config: cfg, sessionMgr: sessionMgr}
+
+	for _, id := range []string{
+		webUIRuntimeChatSessionID("alice", ""),
+		webUIRuntimeChatSessionID("alice", "rt-1"),
+		webUIRuntimeChatSessionID("bob", ""),
+	} {
+		sess, err := s.getOrCreateChatSession(id)
+		if err != nil {
+			t.Fatalf("getOrCreateChatSession(%q) failed: %v", id, err)
+		}
+		sess.AddMessage(agent.Message{Role: "user", Content: "hello from " + id})
+	}
+
+	e := echo.New()
+	call := func(handler func(*echo.Context) error, method, target, id string) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(httptest.NewRequest(method, target, nil), rec)
+		ctx.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "alice"}))
+		if id != "" {
+			ctx.SetPathValues(echo.PathValues{{Name: "id", Value: id}})
+		}
+		if err := handler(ctx); err != nil {
+			t.Fatalf("%s %s failed: %v", method, target, err)
+		}
+		return rec
+	}
+
+	rec := call(s.handleListChatSessions, http.MethodGet, "/api/chat/sessions", "")
+	var summaries []chatSessionSummaryResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &summaries); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	ids := make([]string, 0, len(summaries))
+	for _, item := range summaries {
+		ids = append(ids, item.ID)
+	}
+	if len(ids) != 2 || !strings.Contains(strings.Join(ids, ","), "route:rt-1:webui-chat") || !strings.Contains(strings.Join(ids, ","), "webui-chat") {
+		t.Fatalf("expected alice's two playground sessions, got %v", ids)
+	}
+
+	rec = call(s.handleGetChatSessionMessages, http.MethodGet, "/api/chat/sessions/webui-chat/messages", "webui-chat")
+	var detail struct {
+		Messages []sessionMessageResponse `json:"messages"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &detail); err != nil {
+		t.Fatalf("decode messages: %v", err)
+	}
+	if len(detail.Messages) != 1 || detail.Messages[0].Content != "hello from webui-chat:alice" {
+		t.Fatalf("unexpected messages %+v", detail.Messages)
+	}
+
+	rec = call(s.handleGetChatSessionMessages, http.MethodGet, "/api/chat/sessions/webui-chat%3Abob/messages", "webui-chat:bob")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected other users' sessions to be unreachable, got %d", rec.Code)
+	}
+
+	rec = call(s.handleDeleteChatSession, http.MethodDelete, "/api/chat/sessions/route:rt-1:webui-chat", "route:rt-1:webui-chat")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected delete to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if _, err := sessionMgr.GetExisting(webUIRuntimeChatSessionID("alice", "rt-1")); err == nil {
+		t.Fatal("expected the runtime session to be deleted")
+	}
+	if _, err := sessionMgr.GetExisting(webUIRuntimeChatSessionID("bob", "")); err != nil {
+		t.Fatalf("expected bob's session to remain: %v", err)
+	}
+}
+
+func TestChatAttachmentUploadIsTakenOnceByItsOwner(t *testing.T) {
+	s := &Server{config: config.DefaultConfig()}
+	e := echo.New()
+	upload := func(data []byte) *httptest.ResponseRecorder {
+		t.Helper()
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("file", "cat.png")
+		_, _ = part.Write(data)
+		_ = form.Close()
+		req := httptest.NewRequest(http.MethodPost, "/api/chat/attachments", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "alice"}))
+		if err := s.handleUploadChatAttachment(ctx); err != nil {
+			t.Fatalf("handleUploadChatAttachment failed: %v", err)
+		}
+		return rec
+	}
+
+	if rec := upload([]byte("plain text")); rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected non-images to be rejected, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec := upload([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected upload to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var uploaded chatUploadResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &uploaded); err != nil {
+		t.Fatalf("decode upload: %v", err)
+	}
+	if uploaded.MIMEType != "image/png" || uploaded.Name != "cat.png" {
+		t.Fatalf("unexpected upload response %+v", uploaded)
+	}
+
+	if _, err := s.takeChatUploads("bob", []string{uploaded.ID}); err == nil {
+		t.Fatal("expected another user's upload to be unavailable")
+	}
+	attachments, err := s.takeChatUploads("alice", []string{uploaded.ID})
+	if err != nil || len(attachments) != 1 || attachments[0].MIMEType != "image/png" {
+		t.Fatalf("expected alice's image, got %+v / %v", attachments, err)
+	}
+	if _, err := s.takeChatUploads("alice", []string{uploaded.ID}); err == nil {
+		t.Fatal("expected an upload to be usable once")
+	}
+}
+
+func TestDeliverWebUIOutboundPublishesDownloadableFiles(t *testing.T) {
+	s := &Server{config: config.DefaultConfig()}
+	sessionID := webUIRuntimeChatSessionID("alice", "rt-1")
+	events := make(chan chatEvent, 1)
+	s.registerChatEventSubscriber(sessionID, events)
+	defer s.unregisterChatEventSubscriber(sessionID, events)
+
+	err := s.deliverWebUIOutbound(context.Background(), &bus.Message{
+		SessionID:   sessionID,
+		Content:     "your chart",
+		Attachments: []bus.Attachment{{Type: bus.MessageTypeImage, Name: "chart.png", MIMEType: "image/png", Data: []byte("png")}},
+	})
+	if err != nil {
+		t.Fatalf("deliverWebUIOutbound failed: %v", err)
+	}
+	event := <-events
+	if event.Content != "your chart" || len(event.Attachments) != 1 {
+		t.Fatalf("unexpected chat event %+v", event)
+	}
+	file := event.Attachments[0]
+	if file.Name != "chart.png" || file.URL != "/api/chat/files/"+file.ID {
+		t.Fatalf("unexpected file %+v", file)
+	}
+
+	e := echo.New()
+	get := func(user string) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(httptest.NewRequest(http.MethodGet, file.URL, nil), rec)
+		ctx.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": user}))
+		ctx.SetPathValues(echo.PathValues{{Name: "id", Value: file.ID}})
+		if err := s.handleGetChatFile(ctx); err != nil {
+			t.Fatalf("handleGetChatFile failed: %v", err)
+		}
+		return rec
+	}
+	if rec := get("bob"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected other users to be refused, got %d", rec.Code)
+	}
+	rec := get("alice")
+	if rec.Code != http.StatusOK || rec.Body.String() != "png" || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("unexpected download %d %q %q", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+
+	if err := s.deliverWebUIOutbound(context.Background(), &bus.Message{SessionID: "webui-chat"}); err == nil {
+		t.Fatal("expected a session without a user to be rejected")
+	}
+}
+
+func TestChatLinkHandlersShareSessionWithLinkedChannel(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Sessions.Sources.WebUI = true
+	log := newTestLogger(t)
+	store, err := state.NewFileStore(log, &state.FileStoreConfig{FilePath: filepath.Join(t.TempDir(), "prefs.json")})
+	if err != nil {
+		t.Fatalf("create state store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	prefs := userprefs.New(store)
+	s := &Server{config: cfg, sessionMgr: session.NewManager(t.TempDir(), cfg.Sessions), prefs: prefs, logger: log}
+
+	e := echo.New()
+	call := func(handler func(*echo.Context) error, method, body string) chatLinkResponse {
+		t.Helper()
+		req := httptest.NewRequest(method, "/api/chat/link", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "alice"}))
+		if err := handler(ctx); err != nil {
+			t.Fatalf("%s failed: %v", method, err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", method, rec.Code, rec.Body.String())
+		}
+		var resp chatLinkResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp
+	}
+
+	if resp := call(s.handleChatLink, http.MethodPost, `{}`); resp.Code == "" || resp.ExpiresAt == nil || resp.Linked {
+		t.Fatalf("expected a fresh code for an unlinked user, got %+v", resp)
+	}
+
+	ctx := context.Background()
+	code, _, err := prefs.IssueLinkCode(ctx, "telegram", "7")
+	if err != nil {
+		t.Fatalf("issue telegram code: %v", err)
+	}
+	resp := call(s.handleChatLink, http.MethodPost, `{"code":"`+code+`"}`)
+	if !resp.Linked || len(resp.Accounts) != 2 || !strings.HasPrefix(resp.SessionID, userprefs.LinkedSessionPrefix+":") {
+		t.Fatalf("expected alice linked with telegram, got %+v", resp)
+	}
+	if got := s.chatSessionID(ctx, "alice", "rt-1"); got != "route:rt-1:"+resp.SessionID {
+		t.Fatalf("expected runtime chat to use the linked session, got %q", got)
+	}
+	if got := prefs.ResolveSessionID(ctx, "telegram", "7", "telegram:7"); got != resp.SessionID {
+		t.Fatalf("expected telegram to share %q, got %q", resp.SessionID, got)
+	}
+
+	linked, err := s.getOrCreateChatSession(s.chatSessionID(ctx, "alice", ""))
+	if err != nil {
+		t.Fatalf("create linked chat: %v", err)
+	}
+	linked.AddMessage(agent.Message{Role: "user", Content: "from telegram"})
+	rec := httptest.NewRecorder()
+	listCtx := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/chat/sessions", nil), rec)
+	listCtx.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "alice"}))
+	if err := s.handleListChatSessions(listCtx); err != nil {
+		t.Fatalf("list chat sessions: %v", err)
+	}
+	var summaries []chatSessionSummaryResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &summaries); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].ID != "webui-chat" || summaries[0].MessageCount != 1 {
+		t.Fatalf("expected the linked session as alice's playground chat, got %+v", summaries)
+	}
+
+	if resp := call(s.handleUnlinkChat, http.MethodDelete, ""); resp.Linked {
+		t.Fatalf("expected unlink, got %+v", resp)
+	}
+	if got := s.chatSessionID(ctx, "alice", ""); got != webUIChatSessionID("alice") {
+		t.Fatalf("expected own session after unlink, got %q", got)
+	}
+}
@@ -10,17 +10,65 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v5"
 
 	"nekobot/pkg/agent"
 	"nekobot/pkg/config"
 	"nekobot/pkg/logger"
+	"nekobot/pkg/ownership"
 	"nekobot/pkg/providerstore"
 	"nekobot/pkg/skills"
 	"nekobot/pkg/storage/ent"
 	"nekobot/pkg/watch"
 )
 
+func TestHandleGetConfigRedactsSecretsForViewers(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Redis.Password = "redis-pass"
+	cfg.Storage.DBDSN = "postgres://neko:db-pass@db/neko"
+	cfg.Webhook.Hooks = []config.WebhookHookConfig{{Name: "ci", Secret: "hook-secret"}}
+	cfg.Transcription.APIKey = "transcription-key"
+	cfg.TTS.APIKey = "tts-key"
+	cfg.Tools.Web.Search.BraveAPIKey = "brave-key"
+	cfg.Backup.S3.SecretKey = "s3-secret"
+	cfg.Tools.Databases = []config.DatabaseToolConfig{{Name: "main", DSN: "mysql://neko:sql-pass@db/neko"}}
+	secrets := []string{"redis-pass", "db-pass", "hook-secret", "transcription-key", "tts-key", "brave-key", "s3-secret", "sql-pass"}
+	s := &Server{config: cfg}
+
+	get := func(role string) string {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/config", nil), rec)
+		c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"role": role}))
+		if err := s.handleGetConfig(c); err != nil {
+			t.Fatalf("handleGetConfig failed: %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		return rec.Body.String()
+	}
+
+	for _, role := range []string{config.RoleViewer, config.RoleOperator} {
+		body := get(role)
+		for _, secret := range secrets {
+			if strings.Contains(body, secret) {
+				t.Fatalf("expected %q to be redacted for %s: %s", secret, role, body)
+			}
+		}
+		if !strings.Contains(body, `"addr":"`+cfg.Redis.Addr+`"`) {
+			t.Fatalf("expected non-secret fields to stay visible for %s", role)
+		}
+	}
+	body := get(config.RoleAdmin)
+	for _, secret := range secrets {
+		if !strings.Contains(body, secret) {
+			t.Fatalf("expected admins to see %q", secret)
+		}
+	}
+}
+
 func TestHandleGetConfigIncludesMemorySection(t *testing.T) {
 	s := &Server{
 		config: config.DefaultConfig(),
@@ -33,6 +81,7 @@ func TestHandleGetConfigIncludesMemorySection(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
+	c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"role": config.RoleAdmin}))
 
 	if err := s.handleGetConfig(c); err != nil {
 		t.Fatalf("handleGetConfig failed: %v", err)
@@ -667,6 +716,122 @@ func TestHandleImportConfigPersistsHarnessSections(t *testing.T) {
 	}
 }
 
+func newImportConfigTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Fatalf("close ent client: %v", err)
+		}
+	})
+	providers, err := providerstore.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new provider manager: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := providers.Close(); err != nil {
+			t.Errorf("close provider manager: %v", err)
+		}
+	})
+
+	return &Server{
+		config:    cfg,
+		logger:    log,
+		providers: providers,
+	}
+}
+
+type importConfigTestResponse struct {
+	Error             string                       `json:"error"`
+	ProvidersImported int                          `json:"providers_imported"`
+	ProvidersFailed   int                          `json:"providers_failed"`
+	Providers         []configImportProviderResult `json:"providers"`
+	Sections          []configImportSectionResult  `json:"sections"`
+}
+
+func callImportConfig(t *testing.T, s *Server, body string) (int, importConfigTestResponse) {
+	t.Helper()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/config/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	if err := s.handleImportConfig(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("handleImportConfig failed: %v", err)
+	}
+	var payload importConfigTestResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal response failed: %v", err)
+	}
+	return rec.Code, payload
+}
+
+func TestHandleImportConfigReportsPerProviderResults(t *testing.T) {
+	s := newImportConfigTestServer(t)
+
+	body := `{"usage":{"enabled":true},"providers":[{"name":"p1","provider_kind":"openai","api_key":"k"},{"name":"broken"},{"name":""}]}`
+	code, payload := callImportConfig(t, s, body)
+	if code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %+v", http.StatusOK, code, payload)
+	}
+	if payload.ProvidersImported != 1 || payload.ProvidersFailed != 2 {
+		t.Fatalf("unexpected provider counts: %+v", payload)
+	}
+	if len(payload.Providers) != 3 {
+		t.Fatalf("expected one result per provider, got %+v", payload.Providers)
+	}
+	if got := payload.Providers[0]; got.Name != "p1" || got.Status != "created" || got.Error != "" {
+		t.Fatalf("unexpected result for p1: %+v", got)
+	}
+	if got := payload.Providers[1]; got.Name != "broken" || got.Status != "failed" || !strings.Contains(got.Error, "provider_kind is required") {
+		t.Fatalf("unexpected result for broken provider: %+v", got)
+	}
+	if got := payload.Providers[2]; got.Status != "failed" || got.Error != "provider name is required" {
+		t.Fatalf("unexpected result for unnamed provider: %+v", got)
+	}
+	if len(payload.Sections) != 1 || payload.Sections[0].Section != "usage" || payload.Sections[0].Status != "saved" {
+		t.Fatalf("unexpected section results: %+v", payload.Sections)
+	}
+
+	_, payload = callImportConfig(t, s, `{"providers":[{"name":"p1","provider_kind":"openai","api_key":"k2"}]}`)
+	if len(payload.Providers) != 1 || payload.Providers[0].Status != "updated" {
+		t.Fatalf("expected existing provider to be updated, got %+v", payload.Providers)
+	}
+}
+
+func TestHandleImportConfigReportsPerSectionValidation(t *testing.T) {
+	s := newImportConfigTestServer(t)
+
+	body := `{"usage":{"enabled":true},"turn_limits":{"enabled":true,"max_per_user":0,"mode":"reject"},"providers":[{"name":"p1","provider_kind":"openai"}]}`
+	code, payload := callImportConfig(t, s, body)
+	if code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d: %+v", http.StatusBadRequest, code, payload)
+	}
+	if !strings.Contains(payload.Error, "turn_limits.max_per_user") {
+		t.Fatalf("expected turn_limits error, got %q", payload.Error)
+	}
+
+	results := make(map[string]configImportSectionResult, len(payload.Sections))
+	for _, item := range payload.Sections {
+		results[item.Section] = item
+	}
+	if got := results["usage"]; got.Status != "valid" || len(got.Errors) != 0 {
+		t.Fatalf("expected usage to be reported valid, got %+v", got)
+	}
+	if got := results["turn_limits"]; got.Status != "invalid" || len(got.Errors) == 0 || !strings.Contains(got.Errors[0], "max_per_user") {
+		t.Fatalf("expected turn_limits to be reported invalid, got %+v", got)
+	}
+	if len(payload.Providers) != 0 {
+		t.Fatalf("expected no providers imported on rejected import, got %+v", payload.Providers)
+	}
+}
+
 func TestHandleImportConfigSyncsWatcherRuntime(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.Storage.DBDir = t.TempDir()
@@ -1157,7 +1322,7 @@ func TestPersistChatRoutingClearsProviderAndModel(t *testing.T) {
 		config: cfg,
 	}
 
-	if err := s.persistChatRouting("", "", nil); err != nil {
+	if err := s.persistChatRouting(ownership.AuthContext{}, "", "", nil); err != nil {
 		t.Fatalf("persistChatRouting failed: %v", err)
 	}
 
@@ -1182,7 +1347,7 @@ func TestPersistChatRoutingRejectsUnknownProvider(t *testing.T) {
 		config: cfg,
 	}
 
-	if err := s.persistChatRouting("missing", "gpt-4o", nil); err == nil {
+	if err := s.persistChatRouting(ownership.AuthContext{}, "missing", "gpt-4o", nil); err == nil {
 		t.Fatal("expected error for unknown provider")
 	}
 	if cfg.Agents.Defaults.Provider != "" {
@@ -1208,7 +1373,7 @@ func TestPersistChatRoutingAcceptsProviderGroupTargets(t *testing.T) {
 		config: cfg,
 	}
 
-	if err := s.persistChatRouting("pool-a", "gpt-4.1", []string{"backup", "pool-a"}); err != nil {
+	if err := s.persistChatRouting(ownership.AuthContext{}, "pool-a", "gpt-4.1", []string{"backup", "pool-a"}); err != nil {
 		t.Fatalf("persistChatRouting failed for provider group: %v", err)
 	}
 
@@ -1231,7 +1396,7 @@ func TestPersistChatRoutingUpdatesRouteFields(t *testing.T) {
 		config: cfg,
 	}
 
-	if err := s.persistChatRouting("primary", "gpt-4.1", []string{"backup"}); err != nil {
+	if err := s.persistChatRouting(ownership.AuthContext{}, "primary", "gpt-4.1", []string{"backup"}); err != nil {
 		t.Fatalf("persistChatRouting failed: %v", err)
 	}
 
@@ -1246,6 +1411,46 @@ func TestPersistChatRoutingUpdatesRouteFields(t *testing.T) {
 	}
 }
 
+func TestPersistChatRoutingStaysInTenant(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Provider = "primary"
+	cfg.Agents.Defaults.Model = "gpt-4.1"
+	cfg.Providers = []config.ProviderProfile{
+		{Name: "primary", ProviderKind: "openai", APIKey: "primary-key"},
+		{Name: "team-a-main", ProviderKind: "openai", APIKey: "team-key", TenantID: "tenant-a"},
+	}
+	cfg.Agents.Defaults.ProviderGroups = []config.ProviderGroupConfig{
+		{Name: "mixed", Strategy: "round_robin", Members: []string{"primary", "team-a-main"}},
+	}
+
+	s := &Server{config: cfg}
+	teamA := ownership.AuthContext{UserID: "u-a", TenantID: "tenant-a", TenantSlug: "team-a", Role: config.RoleMember}
+
+	if err := s.persistChatRouting(teamA, "primary", "gpt-4o", nil); err == nil {
+		t.Fatal("expected another tenant's provider to be rejected")
+	}
+	if err := s.persistChatRouting(teamA, "team-a-main", "gpt-4o", []string{"mixed"}); err == nil {
+		t.Fatal("expected a group with another tenant's members to be rejected")
+	}
+	if _, _, _, _, err := s.resolveWebUIRuntimeSelection(ownership.WithAuthContext(context.Background(), teamA), "", "primary", "", nil); err == nil {
+		t.Fatal("expected runtime selection to reject another tenant's provider")
+	}
+	if err := s.persistChatRouting(teamA, "team-a-main", "gpt-4o", nil); err != nil {
+		t.Fatalf("persistChatRouting failed: %v", err)
+	}
+
+	if cfg.Agents.Defaults.Provider != "primary" || cfg.Agents.Defaults.Model != "gpt-4.1" {
+		t.Fatalf("expected instance routing untouched, got %q/%q", cfg.Agents.Defaults.Provider, cfg.Agents.Defaults.Model)
+	}
+	if got := s.chatRouting(teamA); got.Provider != "team-a-main" || got.Model != "gpt-4o" {
+		t.Fatalf("expected tenant routing to be saved, got %+v", got)
+	}
+	if got := s.chatRouting(ownership.AuthContext{}); got.Provider != "primary" {
+		t.Fatalf("expected default tenant routing, got %+v", got)
+	}
+}
+
 func TestHandleGetProvidersReturnsProjectedView(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.Storage.DBDir = t.TempDir()
@@ -1652,3 +1857,64 @@ func newTestEntClient(t *testing.T, cfg *config.Config) *ent.Client {
 	}
 	return client
 }
+
+func TestHandleConfigDocumentRoundTrip(t *testing.T) {
+	s := newImportConfigTestServer(t)
+	e := echo.New()
+
+	body := "response_filters:\n  enabled: true\nproviders:\n  - name: p1\n    provider_kind: openai\n    api_key: k\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/config/apply?dry_run=true", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	if err := s.handleApplyConfigDocument(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"sections":["response_filters"]`) {
+		t.Fatalf("unexpected dry run response %d: %s", rec.Code, rec.Body.String())
+	}
+	if s.config.ResponseFilters.Enabled {
+		t.Fatal("dry run changed the runtime config")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/config/apply", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	if err := s.handleApplyConfigDocument(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	if rec.Code != http.StatusOK || !s.config.ResponseFilters.Enabled {
+		t.Fatalf("apply not reflected (%d): %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"created":["p1"]`) {
+		t.Fatalf("expected provider p1 to be created: %s", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/config/document?format=yaml", nil)
+	rec = httptest.NewRecorder()
+	if err := s.handleGetConfigDocument(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected export status %d: %s", rec.Code, rec.Body.String())
+	}
+	exported := rec.Body.String()
+
+	req = httptest.NewRequest(http.MethodPost, "/api/config/apply", strings.NewReader(exported))
+	rec = httptest.NewRecorder()
+	if err := s.handleApplyConfigDocument(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("re-apply failed: %v", err)
+	}
+	if !strings.Contains(rec.Body.String(), `"sections":null`) || strings.Contains(rec.Body.String(), `"updated":["p1"]`) {
+		t.Fatalf("re-applying an export should change nothing: %s", rec.Body.String())
+	}
+}
+
+func TestHandleApplyConfigDocumentRejectsUnknownSection(t *testing.T) {
+	s := newImportConfigTestServer(t)
+	req := httptest.NewRequest(http.MethodPost, "/api/config/apply", strings.NewReader("agentz: {}\n"))
+	rec := httptest.NewRecorder()
+	if err := s.handleApplyConfigDocument(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "agentz") {
+		t.Fatalf("expected unknown section to be rejected, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
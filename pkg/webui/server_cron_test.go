@@ -6,12 +6,15 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v5"
 
 	"nekobot/pkg/config"
 	"nekobot/pkg/cron"
 	"nekobot/pkg/notificationroutes"
+	"nekobot/pkg/ownership"
 )
 
 func TestCronHandlers_RequireCronManager(t *testing.T) {
@@ -67,6 +70,20 @@ func TestCronHandlers_RequireCronManager(t *testing.T) {
 			target: "/api/cron/jobs/job-1/run",
 			call:   (*Server).handleRunCronJob,
 		},
+		{
+			name:   "list tasks",
+			method: http.MethodGet,
+			path:   "/api/tasks",
+			target: "/api/tasks",
+			call:   (*Server).handleListScheduledTasks,
+		},
+		{
+			name:   "cancel task",
+			method: http.MethodDelete,
+			path:   "/api/tasks/:id",
+			target: "/api/tasks/job-1",
+			call:   (*Server).handleCancelScheduledTask,
+		},
 	}
 
 	for _, tc := range tests {
@@ -496,3 +513,77 @@ func TestHandleRunCronJob_DisabledJobDoesNotExecute(t *testing.T) {
 		t.Fatalf("expected disabled run_count to remain 0, got %d", updated.RunCount)
 	}
 }
+
+func TestScheduledTaskHandlers_ScopeToCurrentUser(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Errorf("close ent client: %v", err)
+		}
+	})
+
+	manager := cron.New(log, nil, client)
+	s := &Server{config: cfg, logger: log, cronMgr: manager}
+	e := echo.New()
+
+	fireAt := time.Now().Add(time.Hour)
+	createFor := func(userID string) *cron.Job {
+		ctx := ownership.WithAuthContext(t.Context(), ownership.AuthContext{UserID: userID, Role: "member"})
+		job, err := manager.AddAtJobWithAuth(ctx, "remind "+userID, fireAt, "ping", true, cron.RouteOptions{})
+		if err != nil {
+			t.Fatalf("add job for %s: %v", userID, err)
+		}
+		return job
+	}
+	aliceJob := createFor("alice")
+	bobJob := createFor("bob")
+
+	newCtx := func(method, target, id string) (*echo.Context, *httptest.ResponseRecorder) {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(method, target, nil), rec)
+		if id != "" {
+			c.SetPathValues(echo.PathValues{{Name: "id", Value: id}})
+		}
+		c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"uid": "alice", "role": "member"}))
+		return c, rec
+	}
+
+	listCtx, listRec := newCtx(http.MethodGet, "/api/tasks", "")
+	if err := s.handleListScheduledTasks(listCtx); err != nil {
+		t.Fatalf("handleListScheduledTasks failed: %v", err)
+	}
+	var listed []*cron.Job
+	if err := json.Unmarshal(listRec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("unmarshal tasks: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != aliceJob.ID {
+		t.Fatalf("expected only alice's task, got %s", listRec.Body.String())
+	}
+
+	foreignCtx, foreignRec := newCtx(http.MethodDelete, "/api/tasks/"+bobJob.ID, bobJob.ID)
+	if err := s.handleCancelScheduledTask(foreignCtx); err != nil {
+		t.Fatalf("handleCancelScheduledTask failed: %v", err)
+	}
+	if foreignRec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 cancelling another user's task, got %d", foreignRec.Code)
+	}
+
+	cancelCtx, cancelRec := newCtx(http.MethodDelete, "/api/tasks/"+aliceJob.ID, aliceJob.ID)
+	if err := s.handleCancelScheduledTask(cancelCtx); err != nil {
+		t.Fatalf("handleCancelScheduledTask failed: %v", err)
+	}
+	if cancelRec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", cancelRec.Code, cancelRec.Body.String())
+	}
+	if _, err := manager.GetJob(aliceJob.ID); err == nil {
+		t.Fatalf("expected alice's task removed")
+	}
+	if _, err := manager.GetJob(bobJob.ID); err != nil {
+		t.Fatalf("expected bob's task kept: %v", err)
+	}
+}
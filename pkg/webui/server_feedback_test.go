@@ -0,0 +1,115 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/feedback"
+	"nekobot/pkg/session"
+)
+
+func TestFeedbackIsRecordedAgainstTheRatedReply(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Errorf("close ent client: %v", err)
+		}
+	})
+	feedbackMgr, err := feedback.NewManager(client)
+	if err != nil {
+		t.Fatalf("new feedback manager: %v", err)
+	}
+	sessionMgr := session.NewManager(t.TempDir(), cfg.Sessions)
+	chat, err := sessionMgr.GetWithSource(webUIChatSessionID("alice"), session.SourceWebUI)
+	if err != nil {
+		t.Fatalf("create chat session: %v", err)
+	}
+	chat.AddMessage(session.Message{Role: "user", Content: "first question"})
+	chat.AddMessage(session.Message{Role: "assistant", Content: "first answer"})
+	chat.AddMessage(session.Message{Role: "user", Content: "second question"})
+	chat.AddMessage(session.Message{Role: "assistant", Content: "second answer"})
+
+	s := &Server{config: cfg, logger: newTestLogger(t), sessionMgr: sessionMgr, feedbackMgr: feedbackMgr}
+	e := echo.New()
+	rate := func(username, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/feedback", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		if err := s.handleRecordFeedback(newAuthedContext(e, req, rec, username)); err != nil {
+			t.Fatalf("handleRecordFeedback failed: %v", err)
+		}
+		return rec
+	}
+	list := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		if err := s.handleListFeedback(e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)); err != nil {
+			t.Fatalf("handleListFeedback(%s) failed: %v", target, err)
+		}
+		return rec
+	}
+
+	if rec := rate("alice", `{"message_index":1,"rating":"down","comment":"too vague"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := rate("alice", `{"message_index":3,"rating":"up"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	// Re-rating replaces the earlier rating but keeps the comment.
+	if rec := rate("alice", `{"message_index":1,"rating":"up"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := rate("alice", `{"message_index":0,"rating":"up"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected user messages to be rejected, got %d", rec.Code)
+	}
+	if rec := rate("alice", `{"message_index":1,"rating":"meh"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected invalid rating to be rejected, got %d", rec.Code)
+	}
+	if rec := rate("bob", `{"message_index":1,"rating":"down"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected another user's chat to be out of reach, got %d", rec.Code)
+	}
+
+	rec := list("/api/feedback")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var entries []feedback.Entry
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("unmarshal feedback: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected one entry per rated reply, got %+v", entries)
+	}
+	byMessage := map[string]feedback.Entry{}
+	for _, entry := range entries {
+		byMessage[entry.MessageID] = entry
+	}
+	first := byMessage[feedback.SessionMessageID(1)]
+	if first.Rating != feedback.RatingUp || first.Comment != "too vague" || first.Response != "first answer" ||
+		first.SessionID != webUIChatSessionID("alice") || first.UserID != "alice" || first.Channel != "websocket" {
+		t.Fatalf("unexpected entry for the first reply: %+v", first)
+	}
+	if second := byMessage[feedback.SessionMessageID(3)]; second.Response != "second answer" {
+		t.Fatalf("unexpected entry for the second reply: %+v", second)
+	}
+
+	rec = list("/api/feedback?rating=up&format=csv")
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), "text/csv") {
+		t.Fatalf("expected csv response, got %d %q", rec.Code, rec.Header().Get(echo.HeaderContentType))
+	}
+	if lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n"); len(lines) != 3 {
+		t.Fatalf("expected header and two rows, got:\n%s", rec.Body.String())
+	}
+	if rec := list("/api/feedback?rating=sideways"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid rating filter, got %d", rec.Code)
+	}
+}
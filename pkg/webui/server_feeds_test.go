@@ -0,0 +1,135 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/feeds"
+)
+
+func TestFeedHandlers_RequireFeedManager(t *testing.T) {
+	s := &Server{}
+	e := echo.New()
+
+	handlers := map[string]func(*Server, *echo.Context) error{
+		"list":   (*Server).handleListFeeds,
+		"create": (*Server).handleCreateFeed,
+		"update": (*Server).handleUpdateFeed,
+		"delete": (*Server).handleDeleteFeed,
+		"check":  (*Server).handleCheckFeed,
+	}
+	for name, call := range handlers {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/feeds", nil), rec)
+			if err := call(s, c); err != nil {
+				t.Fatalf("handler returned error: %v", err)
+			}
+			if rec.Code != http.StatusServiceUnavailable {
+				t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+			}
+		})
+	}
+}
+
+func TestFeedHandlers_Flow(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Errorf("close ent client: %v", err)
+		}
+	})
+
+	s := &Server{
+		config:  cfg,
+		logger:  log,
+		feedMgr: feeds.New(log, nil, nil, client),
+	}
+	e := echo.New()
+
+	invalidReq := httptest.NewRequest(http.MethodPost, "/api/feeds", strings.NewReader(`{"url":"https://example.org/feed"}`))
+	invalidReq.Header.Set("Content-Type", "application/json")
+	invalidRec := httptest.NewRecorder()
+	if err := s.handleCreateFeed(e.NewContext(invalidReq, invalidRec)); err != nil {
+		t.Fatalf("handleCreateFeed failed: %v", err)
+	}
+	if invalidRec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d for a missing target, got %d", http.StatusBadRequest, invalidRec.Code)
+	}
+
+	createBody := `{"url":"https://example.org/feed","channel_id":"telegram","session_id":"telegram:42","enabled":true}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/feeds", strings.NewReader(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createRec := httptest.NewRecorder()
+	if err := s.handleCreateFeed(e.NewContext(createReq, createRec)); err != nil {
+		t.Fatalf("handleCreateFeed failed: %v", err)
+	}
+	if createRec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, createRec.Code, createRec.Body.String())
+	}
+	var created struct {
+		Status string              `json:"status"`
+		Feed   *feeds.Subscription `json:"feed"`
+	}
+	if err := json.Unmarshal(createRec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("unmarshal create response: %v", err)
+	}
+	if created.Feed == nil || created.Feed.ID == "" || created.Feed.Name != "example.org" {
+		t.Fatalf("unexpected created feed: %s", createRec.Body.String())
+	}
+	feedID := created.Feed.ID
+
+	updateBody := `{"name":"Example","url":"https://example.org/feed","channel_id":"telegram","session_id":"telegram:42","interval_minutes":15}`
+	updateReq := httptest.NewRequest(http.MethodPut, "/api/feeds/"+feedID, strings.NewReader(updateBody))
+	updateReq.Header.Set("Content-Type", "application/json")
+	updateRec := httptest.NewRecorder()
+	updateCtx := e.NewContext(updateReq, updateRec)
+	updateCtx.SetPath("/api/feeds/:id")
+	updateCtx.SetPathValues(echo.PathValues{{Name: "id", Value: feedID}})
+	if err := s.handleUpdateFeed(updateCtx); err != nil {
+		t.Fatalf("handleUpdateFeed failed: %v", err)
+	}
+	if updateRec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, updateRec.Code, updateRec.Body.String())
+	}
+
+	listRec := httptest.NewRecorder()
+	if err := s.handleListFeeds(e.NewContext(httptest.NewRequest(http.MethodGet, "/api/feeds", nil), listRec)); err != nil {
+		t.Fatalf("handleListFeeds failed: %v", err)
+	}
+	var subs []feeds.Subscription
+	if err := json.Unmarshal(listRec.Body.Bytes(), &subs); err != nil {
+		t.Fatalf("unmarshal list response: %v", err)
+	}
+	if len(subs) != 1 || subs[0].Name != "Example" || subs[0].IntervalMinutes != 15 || subs[0].Enabled {
+		t.Fatalf("unexpected listed feeds %+v", subs)
+	}
+
+	for i := 0; i < 2; i++ {
+		deleteRec := httptest.NewRecorder()
+		deleteCtx := e.NewContext(httptest.NewRequest(http.MethodDelete, "/api/feeds/"+feedID, nil), deleteRec)
+		deleteCtx.SetPath("/api/feeds/:id")
+		deleteCtx.SetPathValues(echo.PathValues{{Name: "id", Value: feedID}})
+		if err := s.handleDeleteFeed(deleteCtx); err != nil {
+			t.Fatalf("handleDeleteFeed failed: %v", err)
+		}
+		want := http.StatusOK
+		if i == 1 {
+			want = http.StatusNotFound
+		}
+		if deleteRec.Code != want {
+			t.Fatalf("delete %d: expected status %d, got %d", i, want, deleteRec.Code)
+		}
+	}
+}
@@ -291,3 +291,42 @@ func containsString(items []string, target string) bool {
 	}
 	return false
 }
+
+func TestHandleGetMOTDReturnsConfiguredNotice(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.MOTD = config.MOTDConfig{
+		Enabled:       true,
+		Title:         "Acme Bot",
+		Message:       "Maintenance window on Friday",
+		LatestVersion: "v999.0.0",
+		UpdateURL:     "https://example.com/releases",
+	}
+	s := &Server{config: cfg, logger: newTestLogger(t)}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/motd", nil)
+	rec := httptest.NewRecorder()
+	if err := s.handleGetMOTD(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("handleGetMOTD failed: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var payload struct {
+		Enabled       bool   `json:"enabled"`
+		Title         string `json:"title"`
+		Message       string `json:"message"`
+		LatestVersion string `json:"latest_version"`
+		UpdateURL     string `json:"update_url"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if !payload.Enabled || payload.Title != "Acme Bot" || payload.Message != "Maintenance window on Friday" {
+		t.Fatalf("unexpected motd payload: %+v", payload)
+	}
+	if payload.LatestVersion != "v999.0.0" || payload.UpdateURL != "https://example.com/releases" {
+		t.Fatalf("unexpected update fields: %+v", payload)
+	}
+}
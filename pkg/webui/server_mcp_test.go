@@ -0,0 +1,96 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/mcp"
+)
+
+func TestMCPServerCRUD(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	logCfg := logger.DefaultConfig()
+	logCfg.OutputPath = ""
+	log, err := logger.New(logCfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	mgr := mcp.New(log, cfg)
+	t.Cleanup(func() { _ = mgr.Stop() })
+	s := &Server{config: cfg, mcpMgr: mgr}
+	e := echo.New()
+
+	call := func(method, target, name, body string, handler func(*echo.Context) error) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		if name != "" {
+			ctx.SetPath("/api/mcp/servers/:name")
+			ctx.SetPathValues(echo.PathValues{{Name: "name", Value: name}})
+		}
+		if err := handler(ctx); err != nil {
+			t.Fatalf("%s %s failed: %v", method, target, err)
+		}
+		return rec
+	}
+
+	rec := call(http.MethodPost, "/api/mcp/servers", "", `{"name":" docs ","transport":"HTTP","endpoint":"http://127.0.0.1:1/mcp"}`, s.handleCreateMCPServer)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	if servers := cfg.Agents.Defaults.MCPServers; len(servers) != 1 || servers[0].Name != "docs" || servers[0].Transport != "http" {
+		t.Fatalf("expected normalized server in config, got %+v", servers)
+	}
+	rec = call(http.MethodPost, "/api/mcp/servers", "", `{"name":"docs","transport":"http","endpoint":"http://127.0.0.1:2/mcp"}`, s.handleCreateMCPServer)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected duplicate to conflict, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = call(http.MethodPost, "/api/mcp/servers", "", `{"name":"bad","transport":"http"}`, s.handleCreateMCPServer)
+	if rec.Code != http.StatusBadRequest || len(cfg.Agents.Defaults.MCPServers) != 1 {
+		t.Fatalf("expected invalid server to be rejected, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = call(http.MethodGet, "/api/mcp/servers", "", "", s.handleListMCPServers)
+	var listed []mcpServerResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("unmarshal list: %v", err)
+	}
+	if len(listed) != 1 || listed[0].Config.Name != "docs" || listed[0].Status.State != mcp.StateConnecting {
+		t.Fatalf("unexpected listed servers %+v", listed)
+	}
+
+	rec = call(http.MethodPost, "/api/mcp/servers/docs/reconnect", "docs", "", s.handleReconnectMCPServer)
+	var status mcp.ServerStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("unmarshal status: %v", err)
+	}
+	if rec.Code != http.StatusOK || status.State != mcp.StateError || status.LastError == "" {
+		t.Fatalf("expected unreachable server to report an error, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = call(http.MethodPut, "/api/mcp/servers/docs", "docs", `{"transport":"http","endpoint":"http://127.0.0.1:2/mcp","timeout":"5s"}`, s.handleUpdateMCPServer)
+	if rec.Code != http.StatusOK || cfg.Agents.Defaults.MCPServers[0].Timeout != "5s" {
+		t.Fatalf("expected server to be updated, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = call(http.MethodDelete, "/api/mcp/servers/docs", "docs", "", s.handleDeleteMCPServer)
+	if rec.Code != http.StatusOK || len(cfg.Agents.Defaults.MCPServers) != 0 || len(mgr.Status()) != 0 {
+		t.Fatalf("expected server to be deleted, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = call(http.MethodPost, "/api/mcp/servers/docs/reconnect", "docs", "", s.handleReconnectMCPServer)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected missing server to 404, got %d", rec.Code)
+	}
+}
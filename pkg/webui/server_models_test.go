@@ -469,3 +469,97 @@ func TestHandleClearProviderCooldown(t *testing.T) {
 		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
 	}
 }
+
+func TestHandleProbeProviderCapabilitiesStoresResult(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode upstream request: %v", err)
+		}
+		if _, ok := body["tools"]; ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"message":"tools are not supported by this model","type":"invalid_request_error"}}`))
+			return
+		}
+		if stream, _ := body["stream"].(bool); stream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("data: {\"id\":\"1\",\"model\":\"small-model\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"ok\"}}]}\n\ndata: [DONE]\n\n"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"1","model":"small-model","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer upstream.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	providers, err := providerstore.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new provider manager: %v", err)
+	}
+	if _, err := providers.Create(context.Background(), config.ProviderProfile{
+		Name:             "local",
+		ProviderKind:     "openai",
+		APIBase:          upstream.URL,
+		APIKey:           "secret",
+		Enabled:          true,
+		DefaultTestModel: "small-model",
+	}); err != nil {
+		t.Fatalf("create provider failed: %v", err)
+	}
+	models, err := modelstore.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new model manager: %v", err)
+	}
+	if _, err := models.Create(context.Background(), modelstore.ModelCatalog{
+		ModelID:      "small-model",
+		DisplayName:  "Small",
+		Capabilities: []string{"chat", "vision"},
+		Enabled:      true,
+	}); err != nil {
+		t.Fatalf("seed model failed: %v", err)
+	}
+
+	s := &Server{config: cfg, logger: log, providers: providers, entClient: client}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/providers/local/probe-capabilities", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/api/providers/:name/probe-capabilities")
+	c.SetPathValues(echo.PathValues{{Name: "name", Value: "local"}})
+
+	if err := s.handleProbeProviderCapabilities(c); err != nil {
+		t.Fatalf("handleProbeProviderCapabilities failed: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	stored, err := providers.Get(context.Background(), "local")
+	if err != nil {
+		t.Fatalf("get provider failed: %v", err)
+	}
+	caps := stored.Capabilities
+	if caps == nil || caps.Model != "small-model" || caps.ProbedAt == 0 {
+		t.Fatalf("expected stored probe result, got %+v", caps)
+	}
+	if caps.ToolCalling == nil || *caps.ToolCalling {
+		t.Fatalf("expected tool calling to be probed unsupported, got %+v", caps.ToolCalling)
+	}
+	if caps.Streaming == nil || !*caps.Streaming {
+		t.Fatalf("expected streaming to be probed supported, got %+v", caps.Streaming)
+	}
+	if caps.Vision == nil || !*caps.Vision || caps.JSONMode == nil || *caps.JSONMode {
+		t.Fatalf("expected vision/json_mode from model catalog, got vision=%v json_mode=%v", caps.Vision, caps.JSONMode)
+	}
+	if !cfg.GetProviderConfig("local").Capabilities.ToolCallingUnsupported("small-model") {
+		t.Fatal("expected the runtime provider config to see the probe result")
+	}
+}
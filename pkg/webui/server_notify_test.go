@@ -0,0 +1,109 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/notify"
+)
+
+func TestNotifyRuleHandlers_RequireManager(t *testing.T) {
+	s := &Server{}
+	e := echo.New()
+
+	handlers := map[string]func(*Server, *echo.Context) error{
+		"list":   (*Server).handleListNotifyRules,
+		"create": (*Server).handleCreateNotifyRule,
+		"update": (*Server).handleUpdateNotifyRule,
+		"delete": (*Server).handleDeleteNotifyRule,
+	}
+	for name, call := range handlers {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/notify/rules", nil), rec)
+			if err := call(s, c); err != nil {
+				t.Fatalf("handler returned error: %v", err)
+			}
+			if rec.Code != http.StatusServiceUnavailable {
+				t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+			}
+		})
+	}
+}
+
+func TestNotifyRuleHandlers_Flow(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Errorf("close ent client: %v", err)
+		}
+	})
+
+	s := &Server{
+		config:    cfg,
+		logger:    log,
+		notifyMgr: notify.New(log, nil, client),
+	}
+	e := echo.New()
+
+	invalidReq := httptest.NewRequest(http.MethodPost, "/api/notify/rules", strings.NewReader(`{"event_types":["nope"],"channel_id":"telegram","session_id":"telegram:1"}`))
+	invalidReq.Header.Set("Content-Type", "application/json")
+	invalidRec := httptest.NewRecorder()
+	if err := s.handleCreateNotifyRule(e.NewContext(invalidReq, invalidRec)); err != nil {
+		t.Fatalf("handleCreateNotifyRule failed: %v", err)
+	}
+	if invalidRec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d for an unknown event type, got %d", http.StatusBadRequest, invalidRec.Code)
+	}
+
+	createBody := `{"name":"approvals","event_types":["approval.requested"],"channel_id":"telegram","session_id":"telegram:1","enabled":true}`
+	createReq := httptest.NewRequest(http.MethodPost, "/api/notify/rules", strings.NewReader(createBody))
+	createReq.Header.Set("Content-Type", "application/json")
+	createRec := httptest.NewRecorder()
+	if err := s.handleCreateNotifyRule(e.NewContext(createReq, createRec)); err != nil {
+		t.Fatalf("handleCreateNotifyRule failed: %v", err)
+	}
+	if createRec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, createRec.Code, createRec.Body.String())
+	}
+	var created struct {
+		Rule *notify.Rule `json:"rule"`
+	}
+	if err := json.Unmarshal(createRec.Body.Bytes(), &created); err != nil || created.Rule == nil {
+		t.Fatalf("unexpected create response %s (%v)", createRec.Body.String(), err)
+	}
+
+	listRec := httptest.NewRecorder()
+	if err := s.handleListNotifyRules(e.NewContext(httptest.NewRequest(http.MethodGet, "/api/notify/rules", nil), listRec)); err != nil {
+		t.Fatalf("handleListNotifyRules failed: %v", err)
+	}
+	var rules []notify.Rule
+	if err := json.Unmarshal(listRec.Body.Bytes(), &rules); err != nil {
+		t.Fatalf("unmarshal list response: %v", err)
+	}
+	if len(rules) != 1 || rules[0].Name != "approvals" || rules[0].EventTypes[0] != "approval.requested" {
+		t.Fatalf("unexpected rules %+v", rules)
+	}
+
+	deleteRec := httptest.NewRecorder()
+	deleteCtx := e.NewContext(httptest.NewRequest(http.MethodDelete, "/api/notify/rules/missing", nil), deleteRec)
+	deleteCtx.SetPath("/api/notify/rules/:id")
+	deleteCtx.SetPathValues(echo.PathValues{{Name: "id", Value: "missing"}})
+	if err := s.handleDeleteNotifyRule(deleteCtx); err != nil {
+		t.Fatalf("handleDeleteNotifyRule failed: %v", err)
+	}
+	if deleteRec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d for an unknown rule, got %d", http.StatusNotFound, deleteRec.Code)
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/labstack/echo/v5"
 
+	"nekobot/pkg/approval"
 	"nekobot/pkg/config"
 	"nekobot/pkg/permissionrules"
 )
@@ -170,3 +171,69 @@ func TestHandleCreatePermissionRuleRejectsInvalidInput(t *testing.T) {
 		t.Fatalf("expected status 400 for invalid action, got %d: %s", rec.Code, rec.Body.String())
 	}
 }
+
+func TestHandleApprovalDryRunReportsMatchingRules(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	rules, err := permissionrules.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new permission rule manager: %v", err)
+	}
+	if _, err := rules.Create(context.Background(), permissionrules.Rule{
+		ToolName: "spawn",
+		Action:   permissionrules.ActionAsk,
+		Priority: 10,
+		Enabled:  true,
+	}); err != nil {
+		t.Fatalf("seed permission rule failed: %v", err)
+	}
+	approvalMgr := approval.NewManager(approval.Config{
+		Mode:  approval.ModeAuto,
+		Rules: []approval.Rule{{Name: "no force push", Tool: "exec", Args: map[string]string{"command": "*push --force*"}, Action: approval.ActionDeny}},
+	})
+
+	s := &Server{config: cfg, logger: log, entClient: client, approval: approvalMgr}
+	e := echo.New()
+
+	dryRun := func(body string) map[string]json.RawMessage {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/approvals/dry-run", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		if err := s.handleApprovalDryRun(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("handleApprovalDryRun failed: %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var payload map[string]json.RawMessage
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal dry run failed: %v", err)
+		}
+		return payload
+	}
+
+	payload := dryRun(`{"tool_name":"exec","arguments":{"command":"git push --force origin main"}}`)
+	var evaluation approval.Evaluation
+	if err := json.Unmarshal(payload["approval"], &evaluation); err != nil {
+		t.Fatalf("unmarshal evaluation failed: %v", err)
+	}
+	if evaluation.Action != approval.ActionDeny || evaluation.Rule != "no force push" {
+		t.Fatalf("unexpected evaluation: %+v", evaluation)
+	}
+	if _, ok := payload["permission_rule"]; ok {
+		t.Fatal("expected no permission rule for exec")
+	}
+
+	payload = dryRun(`{"tool_name":"spawn"}`)
+	if _, ok := payload["permission_rule"]; !ok {
+		t.Fatalf("expected the spawn permission rule to be reported, got %s", payload)
+	}
+}
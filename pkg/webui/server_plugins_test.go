@@ -0,0 +1,92 @@
+package webui
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+	"nekobot/pkg/plugins"
+)
+
+func TestPluginsEndpoints(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Tools.PluginsDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	logCfg := logger.DefaultConfig()
+	logCfg.OutputPath = ""
+	log, err := logger.New(logCfg)
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	mgr := plugins.New(log, cfg)
+	t.Cleanup(func() { _ = mgr.Stop() })
+	s := &Server{config: cfg, pluginMgr: mgr}
+	e := echo.New()
+
+	call := func(req *http.Request, name string, handler func(*echo.Context) error) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		if name != "" {
+			ctx.SetPath("/api/plugins/wasm/:name")
+			ctx.SetPathValues(echo.PathValues{{Name: "name", Value: name}})
+		}
+		if err := handler(ctx); err != nil {
+			t.Fatalf("%s %s failed: %v", req.Method, req.URL.Path, err)
+		}
+		return rec
+	}
+
+	rec := call(httptest.NewRequest(http.MethodGet, "/api/plugins", nil), "", s.handleListPlugins)
+	var listed struct {
+		PluginsDir string           `json:"plugins_dir"`
+		Plugins    []plugins.Status `json:"plugins"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("unmarshal list: %v", err)
+	}
+	if rec.Code != http.StatusOK || listed.PluginsDir != cfg.Tools.PluginsDir || len(listed.Plugins) != 0 {
+		t.Fatalf("unexpected list %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "tool.zip")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	_, _ = part.Write([]byte("not a zip"))
+	_ = form.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/plugins/wasm", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec = call(req, "", s.handleInstallWasmPlugin)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "open archive") {
+		t.Fatalf("expected a bad archive to be rejected, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/plugins/wasm", strings.NewReader(`{"source":" "}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = call(req, "", s.handleInstallWasmPlugin)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "file or source is required") {
+		t.Fatalf("expected a missing source to be rejected, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = call(httptest.NewRequest(http.MethodDelete, "/api/plugins/wasm/missing", nil), "missing", s.handleUninstallWasmPlugin)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected unknown tool to 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	adminCtx := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/plugins", nil), httptest.NewRecorder())
+	if !isAdminOnlyAPI(adminCtx) {
+		t.Fatal("expected plugin routes to be admin-only")
+	}
+}
@@ -0,0 +1,79 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/usage"
+)
+
+func TestHandleGetUsageReport(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Errorf("close ent client: %v", err)
+		}
+	})
+	usageMgr, err := usage.NewManager(cfg, client)
+	if err != nil {
+		t.Fatalf("new usage manager: %v", err)
+	}
+	day := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	for _, rec := range []usage.Record{
+		{Provider: "openai", Model: "gpt-5", PromptTokens: 100, CompletionTokens: 20, CreatedAt: day},
+		{Provider: "claude", Model: "sonnet", PromptTokens: 50, CompletionTokens: 10, CreatedAt: day},
+		{Provider: "openai", Model: "gpt-5", PromptTokens: 999, CreatedAt: day.AddDate(0, 0, -10)},
+	} {
+		if err := usageMgr.Record(t.Context(), rec); err != nil {
+			t.Fatalf("record usage: %v", err)
+		}
+	}
+
+	s := &Server{config: cfg, logger: newTestLogger(t), usageMgr: usageMgr}
+	e := echo.New()
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		if err := s.handleGetUsageReport(e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)); err != nil {
+			t.Fatalf("handleGetUsageReport(%s) failed: %v", target, err)
+		}
+		return rec
+	}
+
+	rec := get("/api/reports/usage?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z&groupBy=provider")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var report usage.Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("unmarshal report: %v", err)
+	}
+	if len(report.Rows) != 2 || report.Total.PromptTokens != 150 {
+		t.Fatalf("expected only in-range records grouped by provider, got %+v", report)
+	}
+
+	rec = get("/api/reports/usage?from=2026-03-01&to=2026-03-01&groupBy=model&format=csv")
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), "text/csv") {
+		t.Fatalf("expected csv response, got %d %q", rec.Code, rec.Header().Get(echo.HeaderContentType))
+	}
+	if !strings.Contains(rec.Body.String(), "gpt-5,1,100,20,120,") {
+		t.Fatalf("unexpected csv body:\n%s", rec.Body.String())
+	}
+
+	if rec := get("/api/reports/usage?groupBy=tenant"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unsupported groupBy, got %d", rec.Code)
+	}
+	if rec := get("/api/reports/usage?from=soon"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid range, got %d", rec.Code)
+	}
+}
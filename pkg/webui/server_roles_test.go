@@ -0,0 +1,129 @@
+package webui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v5"
+
+	"nekobot/pkg/config"
+)
+
+func TestRequirePrivilegedAPIUserGatesOperatorAndViewer(t *testing.T) {
+	s := &Server{config: config.DefaultConfig(), logger: newTestLogger(t)}
+	handler := s.requirePrivilegedAPIUser()(func(c *echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		role   string
+		method string
+		path   string
+		want   int
+	}{
+		{config.RoleViewer, http.MethodGet, "/api/providers", http.StatusNoContent},
+		{config.RoleViewer, http.MethodPut, "/api/providers/openai", http.StatusForbidden},
+		{config.RoleViewer, http.MethodPost, "/api/approvals/req-1/approve", http.StatusForbidden},
+		{config.RoleViewer, http.MethodGet, "/api/channels", http.StatusForbidden},
+		{config.RoleViewer, http.MethodGet, "/api/users", http.StatusForbidden},
+		{config.RoleViewer, http.MethodPost, "/api/approvals/dry-run", http.StatusNoContent},
+		{config.RoleOperator, http.MethodPost, "/api/approvals/req-1/approve", http.StatusNoContent},
+		{config.RoleOperator, http.MethodPut, "/api/providers/openai", http.StatusNoContent},
+		{config.RoleOperator, http.MethodGet, "/api/users", http.StatusForbidden},
+		{config.RoleOperator, http.MethodPut, "/api/config", http.StatusForbidden},
+		{config.RoleAdmin, http.MethodPost, "/api/users", http.StatusNoContent},
+	}
+
+	e := echo.New()
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"uid": "u-1", "role": tt.role}))
+
+		if err := handler(c); err != nil {
+			t.Fatalf("%s %s %s: handler failed: %v", tt.role, tt.method, tt.path, err)
+		}
+		if rec.Code != tt.want {
+			t.Fatalf("%s %s %s: expected status %d, got %d: %s", tt.role, tt.method, tt.path, tt.want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestRequirePrivilegedAPIUserKeepsTenantUsersOffInstanceRoutes(t *testing.T) {
+	s := &Server{config: config.DefaultConfig(), logger: newTestLogger(t)}
+	handler := s.requirePrivilegedAPIUser()(func(c *echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		slug   string
+		method string
+		path   string
+		want   int
+	}{
+		{"team-a", http.MethodGet, "/api/channels", http.StatusForbidden},
+		{"team-a", http.MethodGet, "/api/users", http.StatusForbidden},
+		{"team-a", http.MethodPut, "/api/config", http.StatusForbidden},
+		{"team-a", http.MethodPost, "/api/providers", http.StatusNoContent},
+		{"team-a", http.MethodGet, "/api/cron/jobs", http.StatusNoContent},
+		{"default", http.MethodGet, "/api/users", http.StatusNoContent},
+	}
+
+	e := echo.New()
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"uid": "u-1", "role": config.RoleOwner, "tid": "t-" + tt.slug, "ts": tt.slug,
+		}))
+
+		if err := handler(c); err != nil {
+			t.Fatalf("%s %s %s: handler failed: %v", tt.slug, tt.method, tt.path, err)
+		}
+		if rec.Code != tt.want {
+			t.Fatalf("%s %s %s: expected status %d, got %d: %s", tt.slug, tt.method, tt.path, tt.want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestTenantWorkdirConfinesTenantUsers(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	s := &Server{config: cfg, logger: newTestLogger(t)}
+
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/api/tool-sessions", nil), httptest.NewRecorder())
+	c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"uid": "u-1", "role": config.RoleOperator, "tid": "t-a", "ts": "team-a",
+	}))
+
+	root := filepath.Join(cfg.WorkspacePath(), "tenants", "team-a")
+	got, err := s.tenantWorkdir(c, "")
+	if err != nil || got != root {
+		t.Fatalf("expected tenant workspace %q, got %q (%v)", root, got, err)
+	}
+	if got, err := s.tenantWorkdir(c, "repo"); err != nil || got != filepath.Join(root, "repo") {
+		t.Fatalf("expected relative workdir inside tenant workspace, got %q (%v)", got, err)
+	}
+	if _, err := s.tenantWorkdir(c, cfg.WorkspacePath()); err == nil {
+		t.Fatalf("expected workdir outside the tenant workspace to be rejected")
+	}
+
+	outside := filepath.Join(cfg.WorkspacePath(), "tenants", "team-b")
+	if err := os.MkdirAll(outside, 0o755); err != nil {
+		t.Fatalf("create other tenant workspace: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatalf("create symlink: %v", err)
+	}
+	for _, workdir := range []string{"escape", "escape/repo"} {
+		if _, err := s.tenantWorkdir(c, workdir); err == nil {
+			t.Fatalf("expected workdir %q through a symlink out of the tenant workspace to be rejected", workdir)
+		}
+	}
+}
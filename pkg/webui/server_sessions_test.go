@@ -1,11 +1,13 @@
 package webui
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 
@@ -17,6 +19,7 @@ import (
 	"nekobot/pkg/session"
 	"nekobot/pkg/state"
 	"nekobot/pkg/threads"
+	"nekobot/pkg/usage"
 )
 
 func TestSessionHandlers_Return503WhenManagerUnavailable(t *testing.T) {
@@ -46,6 +49,23 @@ func TestSessionHandlers_Return503WhenManagerUnavailable(t *testing.T) {
 			path:       "/api/sessions/:id",
 			pathValues: echo.PathValues{{Name: "id", Value: "s1"}},
 		},
+		{
+			name:       "archive",
+			handler:    s.handleArchiveSession,
+			method:     http.MethodPut,
+			target:     "/api/sessions/s1/archive",
+			body:       `{"archived":true}`,
+			path:       "/api/sessions/:id/archive",
+			pathValues: echo.PathValues{{Name: "id", Value: "s1"}},
+		},
+		{
+			name:       "export",
+			handler:    s.handleExportSession,
+			method:     http.MethodGet,
+			target:     "/api/sessions/s1/export",
+			path:       "/api/sessions/:id/export",
+			pathValues: echo.PathValues{{Name: "id", Value: "s1"}},
+		},
 		{
 			name:       "update-summary",
 			handler:    s.handleUpdateSessionSummary,
@@ -55,6 +75,23 @@ func TestSessionHandlers_Return503WhenManagerUnavailable(t *testing.T) {
 			path:       "/api/sessions/:id/summary",
 			pathValues: echo.PathValues{{Name: "id", Value: "s1"}},
 		},
+		{
+			name:       "update-title",
+			handler:    s.handleUpdateSessionTitle,
+			method:     http.MethodPut,
+			target:     "/api/sessions/s1/title",
+			body:       `{"title":"x"}`,
+			path:       "/api/sessions/:id/title",
+			pathValues: echo.PathValues{{Name: "id", Value: "s1"}},
+		},
+		{
+			name:       "retitle",
+			handler:    s.handleRetitleSession,
+			method:     http.MethodPost,
+			target:     "/api/sessions/s1/retitle",
+			path:       "/api/sessions/:id/retitle",
+			pathValues: echo.PathValues{{Name: "id", Value: "s1"}},
+		},
 		{
 			name:       "delete",
 			handler:    s.handleDeleteSession,
@@ -281,6 +318,72 @@ func TestSessionHandlers_EndToEndFlow(t *testing.T) {
 	}
 }
 
+func TestSessionPinHandlers(t *testing.T) {
+	cfg := config.DefaultConfig()
+	sm := session.NewManager(t.TempDir(), cfg.Sessions)
+	s := &Server{sessionMgr: sm}
+	e := echo.New()
+
+	const sessionID = "webui-pins"
+	call := func(handler func(*echo.Context) error, method, path, body string, values echo.PathValues) (*httptest.ResponseRecorder, []string) {
+		t.Helper()
+		req := httptest.NewRequest(method, "/api/sessions/"+sessionID+"/pins", strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath(path)
+		c.SetPathValues(values)
+		if err := handler(c); err != nil {
+			t.Fatalf("handler failed: %v", err)
+		}
+		var payload struct {
+			Pins []string `json:"pins"`
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), &payload)
+		return rec, payload.Pins
+	}
+	idValue := echo.PathValues{{Name: "id", Value: sessionID}}
+
+	rec, pins := call(s.handleAddSessionPin, http.MethodPost, "/api/sessions/:id/pins", `{"text":"Always answer in English."}`, idValue)
+	if rec.Code != http.StatusOK || len(pins) != 1 || pins[0] != "Always answer in English." {
+		t.Fatalf("unexpected add response %d: %s", rec.Code, rec.Body.String())
+	}
+	call(s.handleAddSessionPin, http.MethodPost, "/api/sessions/:id/pins", `{"text":"Codename kestrel."}`, idValue)
+
+	rec, _ = call(s.handleAddSessionPin, http.MethodPost, "/api/sessions/:id/pins", `{"text":"  "}`, idValue)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for empty pin, got %d", rec.Code)
+	}
+
+	rec, pins = call(s.handleListSessionPins, http.MethodGet, "/api/sessions/:id/pins", "", idValue)
+	if rec.Code != http.StatusOK || len(pins) != 2 {
+		t.Fatalf("unexpected list response %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec, pins = call(s.handleDeleteSessionPin, http.MethodDelete, "/api/sessions/:id/pins/:index", "", echo.PathValues{{Name: "id", Value: sessionID}, {Name: "index", Value: "1"}})
+	if rec.Code != http.StatusOK || len(pins) != 1 || pins[0] != "Codename kestrel." {
+		t.Fatalf("unexpected delete response %d: %s", rec.Code, rec.Body.String())
+	}
+	rec, _ = call(s.handleDeleteSessionPin, http.MethodDelete, "/api/sessions/:id/pins/:index", "", echo.PathValues{{Name: "id", Value: sessionID}, {Name: "index", Value: "9"}})
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing pin, got %d", rec.Code)
+	}
+
+	rec, _ = call(s.handleClearSessionPins, http.MethodDelete, "/api/sessions/:id/pins", "", idValue)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected clear response %d: %s", rec.Code, rec.Body.String())
+	}
+	sess, err := sm.GetExisting(sessionID)
+	if err != nil {
+		t.Fatalf("GetExisting failed: %v", err)
+	}
+	if got := sess.GetPins(); len(got) != 0 {
+		t.Fatalf("expected pins cleared, got %#v", got)
+	}
+}
+
 func TestSessionHandlers_NotFoundBehavior(t *testing.T) {
 	cfg := config.DefaultConfig()
 	sm := session.NewManager(t.TempDir(), cfg.Sessions)
@@ -535,6 +638,57 @@ func TestHandleCleanupSessions(t *testing.T) {
 	assertStatusPayload(t, rec.Body.Bytes(), "cleaned")
 }
 
+func TestSessionTitleHandlers(t *testing.T) {
+	cfg := config.DefaultConfig()
+	sm := session.NewManager(t.TempDir(), cfg.Sessions)
+	s := &Server{sessionMgr: sm}
+	e := echo.New()
+
+	const sessionID = "webui-title"
+	sess, err := sm.GetWithSource(sessionID, session.SourceWebUI)
+	if err != nil {
+		t.Fatalf("create session failed: %v", err)
+	}
+	sess.AddMessage(agent.Message{Role: "user", Content: "plan a trip"})
+
+	call := func(handler func(*echo.Context) error, method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, strings.Replace(path, ":id", sessionID, 1), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath(path)
+		c.SetPathValues(echo.PathValues{{Name: "id", Value: sessionID}})
+		if err := handler(c); err != nil {
+			t.Fatalf("handler failed: %v", err)
+		}
+		return rec
+	}
+
+	rec := call(s.handleUpdateSessionTitle, http.MethodPut, "/api/sessions/:id/title", `{"title":"  Trip planning  "}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	listRec := call(s.handleListSessions, http.MethodGet, "/api/sessions", "")
+	var listed []sessionSummaryResponse
+	if err := json.Unmarshal(listRec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("unmarshal list response failed: %v", err)
+	}
+	if len(listed) != 1 || listed[0].Title != "Trip planning" {
+		t.Fatalf("expected title in session list, got %#v", listed)
+	}
+
+	// Regeneration needs the agent; without it the manual title stays put.
+	rec = call(s.handleRetitleSession, http.MethodPost, "/api/sessions/:id/retitle", "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+	if got := sess.GetTitle(); got != "Trip planning" {
+		t.Fatalf("expected manual title to be kept, got %q", got)
+	}
+}
+
 func assertSessionSummaryShape(t *testing.T, payload map[string]json.RawMessage, wantID string, wantSummary string, wantCount int) {
 	t.Helper()
 
@@ -628,3 +782,138 @@ func assertErrorPayload(t *testing.T, body []byte) {
 		t.Fatalf("expected non-empty error payload, got %s", string(body))
 	}
 }
+
+func TestHandleExportSessionRendersTranscriptWithUsage(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Usage.Enabled = true
+	client := newTestEntClient(t, cfg)
+	usageMgr, err := usage.NewManager(cfg, client)
+	if err != nil {
+		t.Fatalf("new usage manager: %v", err)
+	}
+	sessionID := "telegram:42"
+	if err := usageMgr.Record(context.Background(), usage.Record{
+		Provider: "openai", Model: "gpt-5", SessionID: sessionID, PromptTokens: 10, CompletionTokens: 5,
+	}); err != nil {
+		t.Fatalf("record usage: %v", err)
+	}
+
+	sessionMgr := session.NewManager(t.TempDir(), cfg.Sessions)
+	if err := sessionMgr.SaveJSONL(sessionID, []session.Message{
+		{Role: "user", Content: "hello"},
+		{Role: "assistant", Content: "hi there"},
+	}, map[string]interface{}{"title": "Greeting"}); err != nil {
+		t.Fatalf("save session: %v", err)
+	}
+	s := &Server{config: cfg, logger: newTestLogger(t), sessionMgr: sessionMgr, usageMgr: usageMgr}
+	e := echo.New()
+
+	export := func(id, format string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/sessions/x/export?format="+format, nil)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetPath("/api/sessions/:id/export")
+		ctx.SetPathValues(echo.PathValues{{Name: "id", Value: id}})
+		if err := s.handleExportSession(ctx); err != nil {
+			t.Fatalf("export handler failed: %v", err)
+		}
+		return rec
+	}
+
+	rec := export(sessionID, "markdown")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Disposition"); !strings.Contains(got, "telegram_42.md") {
+		t.Fatalf("unexpected content disposition %q", got)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "# Greeting") || !strings.Contains(body, "- Providers: openai") || !strings.Contains(body, "15 total over 1 requests") {
+		t.Fatalf("unexpected markdown export:\n%s", body)
+	}
+
+	rec = export(sessionID, "jsonl")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"type":"usage"`) {
+		t.Fatalf("unexpected jsonl export %d:\n%s", rec.Code, rec.Body.String())
+	}
+	if rec := export(sessionID, "pdf"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unsupported format, got %d", rec.Code)
+	}
+	if rec := export("missing", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing session, got %d", rec.Code)
+	}
+}
+
+func TestHandleListSessionsFiltersByChannelAndArchive(t *testing.T) {
+	cfg := config.DefaultConfig()
+	sessionMgr := session.NewManager(t.TempDir(), cfg.Sessions)
+	for _, seed := range []struct{ id, source, title string }{
+		{"telegram:42", session.SourceChannels, "Disk usage"},
+		{"telegram:43", session.SourceChannels, "Weather"},
+		{"cli:local", session.SourceCLI, "Refactor"},
+	} {
+		sess, err := sessionMgr.GetWithSource(seed.id, seed.source)
+		if err != nil {
+			t.Fatalf("create session %s: %v", seed.id, err)
+		}
+		sess.AddMessage(session.Message{Role: "user", Content: "hi"})
+		sess.SetTitle(seed.title)
+	}
+	s := &Server{config: cfg, sessionMgr: sessionMgr}
+	e := echo.New()
+
+	list := func(query string) []sessionSummaryResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/sessions?"+query, nil)
+		rec := httptest.NewRecorder()
+		if err := s.handleListSessions(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("list handler failed: %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200 for %q, got %d: %s", query, rec.Code, rec.Body.String())
+		}
+		var out []sessionSummaryResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+			t.Fatalf("unmarshal list: %v", err)
+		}
+		return out
+	}
+	ids := func(items []sessionSummaryResponse) []string {
+		out := make([]string, 0, len(items))
+		for _, item := range items {
+			out = append(out, item.ID)
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	if got := ids(list("channel=telegram")); strings.Join(got, ",") != "telegram:42,telegram:43" {
+		t.Fatalf("unexpected telegram sessions: %v", got)
+	}
+	if got := list("q=refac"); len(got) != 1 || got[0].ID != "cli:local" || got[0].Channel != "cli" || got[0].Source != session.SourceCLI {
+		t.Fatalf("unexpected search result: %+v", got)
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/sessions/telegram:43/archive", strings.NewReader(`{"archived":true}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetPath("/api/sessions/:id/archive")
+	ctx.SetPathValues(echo.PathValues{{Name: "id", Value: "telegram:43"}})
+	if err := s.handleArchiveSession(ctx); err != nil {
+		t.Fatalf("archive handler failed: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 from archive, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if got := ids(list("")); strings.Join(got, ",") != "cli:local,telegram:42" {
+		t.Fatalf("archived session should be hidden by default: %v", got)
+	}
+	if got := list("archived=true"); len(got) != 1 || got[0].ID != "telegram:43" || !got[0].Archived {
+		t.Fatalf("unexpected archived listing: %+v", got)
+	}
+	if got := list("archived=all"); len(got) != 3 {
+		t.Fatalf("expected all 3 sessions, got %d", len(got))
+	}
+}
@@ -0,0 +1,88 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+
+	"nekobot/pkg/skills"
+)
+
+func TestSkillsIndexHandlers(t *testing.T) {
+	tmpDir := t.TempDir()
+	mgr := skills.NewManager(newTestLogger(t), filepath.Join(tmpDir, "skills"), false)
+	s := &Server{skillsMgr: mgr}
+	e := echo.New()
+
+	call := func(req *http.Request, handler func(*echo.Context) error) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		if err := handler(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("%s %s failed: %v", req.Method, req.URL.Path, err)
+		}
+		return rec
+	}
+
+	rec := call(httptest.NewRequest(http.MethodGet, "/api/skills-index", nil), s.handleListSkillsIndex)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"configured":false`) {
+		t.Fatalf("expected unconfigured index, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	indexPath := filepath.Join(tmpDir, "index.json")
+	index := `{"skills":[{"id":"deployer","repo":"acme/deployer","permissions":{"exec":true,"tools":["k8s_apply"]},"versions":[
+		{"version":"v1.0.0"},
+		{"version":"v1.2.0","changelog":"Rollback support"}
+	]}]}`
+	if err := os.WriteFile(indexPath, []byte(index), 0o644); err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+	mgr.SetIndex(indexPath, true)
+
+	rec = call(httptest.NewRequest(http.MethodGet, "/api/skills-index", nil), s.handleListSkillsIndex)
+	var listed struct {
+		Configured bool `json:"configured"`
+		IndexOnly  bool `json:"index_only"`
+		Skills     []struct {
+			ID          string   `json:"id"`
+			Permissions []string `json:"permissions"`
+			Versions    []struct {
+				Version   string `json:"version"`
+				Changelog string `json:"changelog"`
+			} `json:"versions"`
+		} `json:"skills"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("unmarshal index: %v", err)
+	}
+	if !listed.Configured || !listed.IndexOnly || len(listed.Skills) != 1 {
+		t.Fatalf("unexpected index response: %s", rec.Body.String())
+	}
+	entry := listed.Skills[0]
+	if strings.Join(entry.Permissions, ",") != "exec,tool:k8s_apply" || entry.Versions[0].Changelog != "Rollback support" {
+		t.Fatalf("unexpected index entry: %+v", entry)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/skills-index/install", strings.NewReader(`{"id":"unknown"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = call(req, s.handleInstallIndexedSkill)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected unknown skill to 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/skills-index/install", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = call(req, s.handleInstallIndexedSkill)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected missing id to be rejected, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if !isAdminOnlyAPI(e.NewContext(httptest.NewRequest(http.MethodPost, "/api/skills-index/install", nil), httptest.NewRecorder())) {
+		t.Fatal("expected skill installs to be admin-only")
+	}
+}
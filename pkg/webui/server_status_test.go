@@ -21,6 +21,7 @@ import (
 	"nekobot/pkg/accountbindings"
 	"nekobot/pkg/agent"
 	"nekobot/pkg/approval"
+	"nekobot/pkg/backup"
 	"nekobot/pkg/channelaccounts"
 	"nekobot/pkg/config"
 	"nekobot/pkg/cron"
@@ -331,6 +332,38 @@ func TestHandleStatus_IncludesRecentCronJobsWithoutTaskSnapshots(t *testing.T) {
 	}
 }
 
+func TestHandleStatus_IncludesBackupStatus(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.Backup.Enabled = true
+	cfg.Backup.Dir = filepath.Join(t.TempDir(), "backups")
+
+	s := &Server{
+		config:    cfg,
+		startedAt: time.Now(),
+		taskStore: tasks.NewStore(),
+		backupMgr: backup.New(cfg, newTestLogger(t), nil),
+	}
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/status", nil), rec)
+	if err := s.handleStatus(ctx); err != nil {
+		t.Fatalf("handleStatus failed: %v", err)
+	}
+
+	var payload struct {
+		Backup *backup.Status `json:"backup"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal status payload failed: %v", err)
+	}
+	if payload.Backup == nil || !payload.Backup.Enabled || payload.Backup.Target != "local" || payload.Backup.Location != cfg.Backup.Dir {
+		t.Fatalf("unexpected backup status: %+v", payload.Backup)
+	}
+}
+
 func TestHandleStatus_IncludesAgentDefinition(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.Storage.DBDir = t.TempDir()
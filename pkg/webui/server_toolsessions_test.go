@@ -2,22 +2,30 @@ package webui
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v5"
 
+	"nekobot/pkg/approval"
 	"nekobot/pkg/config"
 	"nekobot/pkg/execenv"
 	"nekobot/pkg/process"
 	"nekobot/pkg/runtimeagents"
+	"nekobot/pkg/session"
 	"nekobot/pkg/toolsessions"
 )
 
@@ -590,6 +598,94 @@ func TestHandleSpawnToolSessionPassesMetadataToExecenv(t *testing.T) {
 	}
 }
 
+func TestHandleSpawnToolSessionFromChatSeedsWorkdir(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() { _ = client.Close() })
+
+	toolMgr, err := toolsessions.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new tool session manager: %v", err)
+	}
+	sessionMgr := session.NewManager(t.TempDir(), cfg.Sessions)
+	chat, err := sessionMgr.GetWithSource(webUIChatSessionID("alice"), session.SourceWebUI)
+	if err != nil {
+		t.Fatalf("create chat session: %v", err)
+	}
+	chat.AddMessage(session.Message{Role: "user", Content: "fix my server"})
+	chat.AddMessage(session.Message{Role: "assistant", Content: "Try this:\n```go main.go\npackage main\n```\nand\n```sh\ngo run .\n```"})
+	if _, err := sessionMgr.GetWithSource(webUIChatSessionID("bob"), session.SourceWebUI); err != nil {
+		t.Fatalf("create other chat session: %v", err)
+	}
+
+	preparer := &captureWebUITestPreparer{}
+	pm := process.NewManager(log)
+	pm.SetPreparer(preparer)
+	server := &Server{
+		config:     cfg,
+		logger:     log,
+		toolSess:   toolMgr,
+		processMgr: pm,
+		sessionMgr: sessionMgr,
+	}
+	e := echo.New()
+	spawn := func(username, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/tool-sessions/from-chat", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		if err := server.handleSpawnToolSessionFromChat(newAuthedContext(e, req, rec, username)); err != nil {
+			t.Fatalf("from-chat handler failed: %v", err)
+		}
+		return rec
+	}
+
+	rec := spawn("alice", `{"tool":"codex","command":"sleep 5","message_indexes":[1],"files":[{"path":"notes/todo.md","content":"- ship it"}]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		Session     toolsessions.Session `json:"session"`
+		SeededFiles []string             `json:"seeded_files"`
+	}
+	decodeJSON(t, rec.Body.Bytes(), &payload)
+	t.Cleanup(func() { _ = pm.Reset(payload.Session.ID) })
+
+	if strings.Join(payload.SeededFiles, ",") != "main.go,snippet-1.sh,notes/todo.md" {
+		t.Fatalf("unexpected seeded files: %v", payload.SeededFiles)
+	}
+	workdir := payload.Session.Workdir
+	if !strings.HasPrefix(workdir, filepath.Join(cfg.WorkspacePath(), "tool-workdirs")) {
+		t.Fatalf("expected a fresh workdir under the workspace, got %q", workdir)
+	}
+	for name, want := range map[string]string{"main.go": "package main", "snippet-1.sh": "go run .", "notes/todo.md": "- ship it"} {
+		data, err := os.ReadFile(filepath.Join(workdir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("read seeded %s: %v", name, err)
+		}
+		if string(data) != want {
+			t.Fatalf("seeded %s = %q, want %q", name, data, want)
+		}
+	}
+	if preparer.last.SessionID != payload.Session.ID || preparer.last.Workdir != workdir {
+		t.Fatalf("expected the process to start in the seeded workdir, got %+v", preparer.last)
+	}
+
+	// Message indexes resolve against the caller's own chat only.
+	if rec := spawn("bob", `{"tool":"codex","command":"sleep 5","message_indexes":[1]}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected out-of-range index for another user's chat, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := spawn("alice", `{"tool":"codex","command":"sleep 5","files":[{"path":"../escape.txt","content":"x"}]}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected path traversal to be rejected, got %d", rec.Code)
+	}
+	if rec := spawn("alice", `{"tool":"codex","command":"sleep 5"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected empty selection to be rejected, got %d", rec.Code)
+	}
+}
+
 func TestHandleRestartToolSessionPersistsLaunchMetadata(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.Storage.DBDir = t.TempDir()
@@ -906,8 +1002,8 @@ func TestHandleListToolSessionRuntimeTransports(t *testing.T) {
 		} `json:"items"`
 	}
 	decodeJSON(t, rec.Body.Bytes(), &payload)
-	if len(payload.Items) != 2 {
-		t.Fatalf("expected 2 transport entries, got %+v", payload.Items)
+	if len(payload.Items) != 3 {
+		t.Fatalf("expected 3 transport entries, got %+v", payload.Items)
 	}
 	seen := map[string]bool{}
 	for _, item := range payload.Items {
@@ -924,8 +1020,12 @@ func TestHandleListToolSessionRuntimeTransports(t *testing.T) {
 			if item.Available != runtimeagents.TransportByName(runtimeagents.TransportZellij).Available() {
 				t.Fatalf("unexpected zellij availability %+v", item)
 			}
-			if !item.IsDefault {
-				t.Fatalf("expected zellij to be default when configured: %+v", item)
+			if item.IsDefault != item.Available {
+				t.Fatalf("expected configured zellij to be default only when installed: %+v", item)
+			}
+		case runtimeagents.TransportNative:
+			if !item.Available {
+				t.Fatalf("expected the native transport to always be available: %+v", item)
 			}
 		default:
 			t.Fatalf("unexpected transport entry %+v", item)
@@ -1430,3 +1530,771 @@ func TestMemberToolSessionTokenIsScopedToOneSession(t *testing.T) {
 		t.Fatalf("expected status %d, got %d: %s", http.StatusForbidden, forbiddenRec.Code, forbiddenRec.Body.String())
 	}
 }
+
+func newToolSessionApprovalTestServer(t *testing.T) (*Server, *captureWebUITestPreparer, *process.Manager) {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.ToolSessions.RequireApproval = true
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() { _ = client.Close() })
+
+	toolMgr, err := toolsessions.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new tool session manager: %v", err)
+	}
+	store, err := approval.NewEntStore(client)
+	if err != nil {
+		t.Fatalf("new approval store: %v", err)
+	}
+	approvals, err := approval.NewManagerWithStore(context.Background(), approval.Config{Mode: approval.ModeAuto}, store)
+	if err != nil {
+		t.Fatalf("new approval manager: %v", err)
+	}
+	preparer := &captureWebUITestPreparer{}
+	pm := process.NewManager(log)
+	pm.SetPreparer(preparer)
+	server := &Server{
+		config:     cfg,
+		logger:     log,
+		toolSess:   toolMgr,
+		processMgr: pm,
+		approval:   approvals,
+		entClient:  client,
+	}
+	server.registerApprovalHandlers()
+	return server, preparer, pm
+}
+
+func newRoleContext(e *echo.Echo, req *http.Request, rec *httptest.ResponseRecorder, username, role string) *echo.Context {
+	ctx := e.NewContext(req, rec)
+	ctx.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  username,
+		"role": role,
+	}))
+	return ctx
+}
+
+func spawnToolSessionForApproval(t *testing.T, server *Server) string {
+	t.Helper()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/tool-sessions/spawn", strings.NewReader(
+		`{"tool":"codex","command":"sleep 5","workdir":"`+server.config.WorkspacePath()+`","access_mode":"permanent","access_password":"hunter2"}`,
+	))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	ctx := newRoleContext(e, req, rec, "alice", config.RoleMember)
+	ctx.SetPath("/api/tool-sessions/spawn")
+	if err := server.handleSpawnToolSession(ctx); err != nil {
+		t.Fatalf("spawn handler failed: %v", err)
+	}
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		Status    string `json:"status"`
+		RequestID string `json:"request_id"`
+	}
+	decodeJSON(t, rec.Body.Bytes(), &payload)
+	if payload.Status != "pending" || payload.RequestID == "" {
+		t.Fatalf("expected pending approval, got %+v", payload)
+	}
+
+	pending := server.approval.GetPending()
+	if len(pending) != 1 || pending[0].ToolName != toolSessionSpawnApprovalTool {
+		t.Fatalf("expected one spawn approval request, got %+v", pending)
+	}
+	if got := pending[0].RequestedBy(); got != "alice" {
+		t.Fatalf("expected requester recorded, got %q", got)
+	}
+	if got, _ := pending[0].Arguments["command"].(string); got != "sleep 5" {
+		t.Fatalf("expected command recorded, got %q", got)
+	}
+	if strings.Contains(fmt.Sprint(pending[0].Arguments), "hunter2") {
+		t.Fatalf("expected access password kept out of the approval request, got %v", pending[0].Arguments)
+	}
+	return payload.RequestID
+}
+
+func decideToolSessionApproval(t *testing.T, server *Server, requestID, action, username, role string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/approvals/"+requestID+"/"+action, strings.NewReader(`{"reason":"not now"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	ctx := newRoleContext(e, req, rec, username, role)
+	ctx.SetPath("/api/approvals/:id/" + action)
+	ctx.SetPathValues(echo.PathValues{{Name: "id", Value: requestID}})
+	handler := server.handleApproveRequest
+	if action == "deny" {
+		handler = server.handleDenyRequest
+	}
+	if err := handler(ctx); err != nil {
+		t.Fatalf("%s handler failed: %v", action, err)
+	}
+	return rec
+}
+
+func fetchToolSessionSpawnResult(t *testing.T, server *Server, requestID, username string) (*httptest.ResponseRecorder, toolSessionSpawnResult) {
+	t.Helper()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/tool-sessions/spawn-requests/"+requestID, nil)
+	rec := httptest.NewRecorder()
+	ctx := newRoleContext(e, req, rec, username, config.RoleMember)
+	ctx.SetPath("/api/tool-sessions/spawn-requests/:id")
+	ctx.SetPathValues(echo.PathValues{{Name: "id", Value: requestID}})
+	if err := server.handleGetToolSessionSpawnRequest(ctx); err != nil {
+		t.Fatalf("spawn request handler failed: %v", err)
+	}
+	var result toolSessionSpawnResult
+	if rec.Code == http.StatusOK {
+		decodeJSON(t, rec.Body.Bytes(), &result)
+	}
+	return rec, result
+}
+
+func assertNoToolSessions(t *testing.T, server *Server, preparer *captureWebUITestPreparer) {
+	t.Helper()
+
+	sessions, err := server.toolSess.ListSessions(context.Background(), toolsessions.ListSessionsInput{})
+	if err != nil {
+		t.Fatalf("list sessions: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Fatalf("expected no tool session before approval, got %d", len(sessions))
+	}
+	if preparer.last.SessionID != "" {
+		t.Fatalf("expected no process start, got %+v", preparer.last)
+	}
+}
+
+func TestHandleSpawnToolSessionWaitsForApproval(t *testing.T) {
+	server, preparer, pm := newToolSessionApprovalTestServer(t)
+
+	requestID := spawnToolSessionForApproval(t, server)
+	assertNoToolSessions(t, server, preparer)
+
+	rec := decideToolSessionApproval(t, server, requestID, "approve", "admin", config.RoleAdmin)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "access_password") {
+		t.Fatalf("expected the approver not to receive access details, got %s", rec.Body.String())
+	}
+
+	if rec, _ := fetchToolSessionSpawnResult(t, server, requestID, "bob"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected other users not to see the spawn result, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec, result := fetchToolSessionSpawnResult(t, server, requestID, "alice")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if result.Status != approval.Approved || result.Session == nil || result.Error != "" {
+		t.Fatalf("expected approved spawn with session, got %s", rec.Body.String())
+	}
+	if result.AccessPassword == "" || result.AccessURL == "" {
+		t.Fatalf("expected the requester to receive access details, got %s", rec.Body.String())
+	}
+	if preparer.last.SessionID != result.Session.ID {
+		t.Fatalf("expected process started for %q, got %q", result.Session.ID, preparer.last.SessionID)
+	}
+	if result.Session.Owner != "alice" {
+		t.Fatalf("expected session owned by requester, got %q", result.Session.Owner)
+	}
+	record, _ := result.Session.Metadata["spawn_approval"].(map[string]interface{})
+	if record["requested_by"] != "alice" || record["approved_by"] != "admin" || record["request_id"] != requestID {
+		t.Fatalf("expected approval record in metadata, got %#v", result.Session.Metadata["spawn_approval"])
+	}
+	if _, again := fetchToolSessionSpawnResult(t, server, requestID, "alice"); again.AccessPassword != "" {
+		t.Fatal("expected the access password to be handed out once")
+	}
+	if err := pm.Reset(result.Session.ID); err != nil {
+		t.Fatalf("reset spawned process: %v", err)
+	}
+}
+
+func TestToolSessionSpawnApprovalRequiresAnotherAdmin(t *testing.T) {
+	server, preparer, _ := newToolSessionApprovalTestServer(t)
+
+	requestID := spawnToolSessionForApproval(t, server)
+	if rec := decideToolSessionApproval(t, server, requestID, "approve", "carol", config.RoleOperator); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected operators to be refused, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := decideToolSessionApproval(t, server, requestID, "approve", "alice", config.RoleAdmin); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected self-approval to be refused, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if decision, _ := server.approval.GetDecision(requestID); decision != approval.Pending {
+		t.Fatalf("expected request still pending, got %q", decision)
+	}
+	assertNoToolSessions(t, server, preparer)
+}
+
+func TestHandleSpawnToolSessionRejectedOnDeny(t *testing.T) {
+	server, preparer, _ := newToolSessionApprovalTestServer(t)
+
+	requestID := spawnToolSessionForApproval(t, server)
+	rec := decideToolSessionApproval(t, server, requestID, "deny", "admin", config.RoleAdmin)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if decision, _ := server.approval.GetDecision(requestID); decision != approval.Denied {
+		t.Fatalf("expected denied decision, got %q", decision)
+	}
+	if _, result := fetchToolSessionSpawnResult(t, server, requestID, "alice"); result.Status != approval.Denied || result.Reason != "not now" {
+		t.Fatalf("expected the requester to see the denial, got %+v", result)
+	}
+
+	rec = decideToolSessionApproval(t, server, requestID, "approve", "admin", config.RoleAdmin)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected approve after deny to conflict, got %d: %s", rec.Code, rec.Body.String())
+	}
+	assertNoToolSessions(t, server, preparer)
+}
+
+func TestHandleSpawnToolSessionExpiresWithoutApproval(t *testing.T) {
+	server, preparer, _ := newToolSessionApprovalTestServer(t)
+	server.config.ToolSessions.ApprovalTimeoutSeconds = 1
+
+	requestID := spawnToolSessionForApproval(t, server)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if decision, _ := server.approval.GetDecision(requestID); decision == approval.Expired {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the spawn request to expire")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	rec := decideToolSessionApproval(t, server, requestID, "approve", "admin", config.RoleAdmin)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected approve after timeout to conflict, got %d: %s", rec.Code, rec.Body.String())
+	}
+	assertNoToolSessions(t, server, preparer)
+}
+
+func TestChatToolSessionSpawnSeedsWorkdirOnlyOnceApproved(t *testing.T) {
+	server, preparer, pm := newToolSessionApprovalTestServer(t)
+	server.sessionMgr = session.NewManager(t.TempDir(), server.config.Sessions)
+	if _, err := server.sessionMgr.GetWithSource(webUIChatSessionID("alice"), session.SourceWebUI); err != nil {
+		t.Fatalf("create chat session: %v", err)
+	}
+	seedRoot := filepath.Join(server.config.TenantWorkspacePath("team-a"), "tool-workdirs")
+
+	e := echo.New()
+	spawn := func() string {
+		req := httptest.NewRequest(http.MethodPost, "/api/tool-sessions/from-chat", strings.NewReader(
+			`{"tool":"codex","command":"sleep 5","files":[{"path":"main.go","content":"package main"}]}`,
+		))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": "alice", "uid": "u-alice", "role": config.RoleMember, "tid": "t-a", "ts": "team-a",
+		}))
+		if err := server.handleSpawnToolSessionFromChat(ctx); err != nil {
+			t.Fatalf("from-chat handler failed: %v", err)
+		}
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, rec.Code, rec.Body.String())
+		}
+		var payload struct {
+			RequestID string `json:"request_id"`
+		}
+		decodeJSON(t, rec.Body.Bytes(), &payload)
+		return payload.RequestID
+	}
+	assertNoSeedWorkdirs := func() {
+		t.Helper()
+		if entries, err := os.ReadDir(seedRoot); err == nil && len(entries) > 0 {
+			t.Fatalf("expected no seeded workdirs, found %d", len(entries))
+		}
+	}
+
+	denied := spawn()
+	assertNoSeedWorkdirs()
+	if rec := decideToolSessionApproval(t, server, denied, "deny", "admin", config.RoleAdmin); rec.Code != http.StatusOK {
+		t.Fatalf("expected deny to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	assertNoSeedWorkdirs()
+	assertNoToolSessions(t, server, preparer)
+
+	approved := spawn()
+	req, ok := server.approval.GetRequest(approved)
+	if !ok {
+		t.Fatalf("expected pending request %s", approved)
+	}
+	stored, err := toolSessionSpawnFromRequest(*req)
+	if err != nil {
+		t.Fatalf("decode stored spawn: %v", err)
+	}
+	if stored.Requester.UserID != "u-alice" || stored.Requester.TenantSlug != "team-a" {
+		t.Fatalf("expected the requester's identity stored with the spawn, got %+v", stored.Requester)
+	}
+	if rec := decideToolSessionApproval(t, server, approved, "approve", "admin", config.RoleAdmin); rec.Code != http.StatusOK {
+		t.Fatalf("expected approve to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	t.Cleanup(func() { _ = pm.Reset(preparer.last.SessionID) })
+	data, err := os.ReadFile(filepath.Join(preparer.last.Workdir, "main.go"))
+	if err != nil || string(data) != "package main" {
+		t.Fatalf("expected the approved spawn to seed its workdir, got %q (%v)", data, err)
+	}
+	if !strings.HasPrefix(preparer.last.Workdir, seedRoot) {
+		t.Fatalf("expected the workdir under %s, got %q", seedRoot, preparer.last.Workdir)
+	}
+}
+
+func TestToolSessionSpawnApprovalSurvivesRestart(t *testing.T) {
+	server, preparer, pm := newToolSessionApprovalTestServer(t)
+	requestID := spawnToolSessionForApproval(t, server)
+
+	// A new process reloads the request with its spawn from the store, and
+	// approving it through the manager, as the gateway API and chat
+	// commands do, launches it.
+	store, err := approval.NewEntStore(server.entClient)
+	if err != nil {
+		t.Fatalf("new approval store: %v", err)
+	}
+	reloaded, err := approval.NewManagerWithStore(context.Background(), approval.Config{Mode: approval.ModeAuto}, store)
+	if err != nil {
+		t.Fatalf("reload approval manager: %v", err)
+	}
+	restarted := &Server{
+		config:     server.config,
+		logger:     server.logger,
+		toolSess:   server.toolSess,
+		processMgr: server.processMgr,
+		approval:   reloaded,
+	}
+	restarted.registerApprovalHandlers()
+	if err := reloaded.ApproveAs(requestID, "admin"); err != nil {
+		t.Fatalf("approve reloaded request: %v", err)
+	}
+
+	_, result := fetchToolSessionSpawnResult(t, restarted, requestID, "alice")
+	if result.Session == nil || result.Error != "" {
+		t.Fatalf("expected the reloaded spawn to launch, got %+v", result)
+	}
+	if preparer.last.SessionID != result.Session.ID || preparer.last.Workdir != server.config.WorkspacePath() {
+		t.Fatalf("expected the stored spawn to start, got %+v", preparer.last)
+	}
+	if err := pm.Reset(result.Session.ID); err != nil {
+		t.Fatalf("reset spawned process: %v", err)
+	}
+}
+
+func createApprovalTestToolSession(t *testing.T, server *Server) *toolsessions.Session {
+	t.Helper()
+
+	sess, err := server.toolSess.CreateSession(context.Background(), toolsessions.CreateSessionInput{
+		Owner:   "alice",
+		Source:  toolsessions.SourceWebUI,
+		Tool:    "codex",
+		Title:   "Gated Session",
+		Command: "sleep 5",
+		Workdir: server.config.WorkspacePath(),
+		State:   toolsessions.StateRunning,
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	return sess
+}
+
+func TestHandleRestartToolSessionWaitsForApproval(t *testing.T) {
+	server, preparer, pm := newToolSessionApprovalTestServer(t)
+	sess := createApprovalTestToolSession(t, server)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/tool-sessions/"+sess.ID+"/restart", strings.NewReader(`{"command":"sleep 7"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	ctx := newRoleContext(e, req, rec, "alice", config.RoleMember)
+	ctx.SetPath("/api/tool-sessions/:id/restart")
+	ctx.SetPathValues(echo.PathValues{{Name: "id", Value: sess.ID}})
+	if err := server.handleRestartToolSession(ctx); err != nil {
+		t.Fatalf("restart handler failed: %v", err)
+	}
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+	if preparer.last.SessionID != "" {
+		t.Fatalf("expected no process start before approval, got %+v", preparer.last)
+	}
+	var pending struct {
+		RequestID string `json:"request_id"`
+	}
+	decodeJSON(t, rec.Body.Bytes(), &pending)
+
+	rec = decideToolSessionApproval(t, server, pending.RequestID, "approve", "admin", config.RoleAdmin)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if preparer.last.SessionID != sess.ID || !strings.Contains(preparer.last.Command, "sleep 7") {
+		t.Fatalf("expected the approved restart to run the new command, got %+v", preparer.last)
+	}
+	if err := pm.Reset(sess.ID); err != nil {
+		t.Fatalf("reset restarted process: %v", err)
+	}
+}
+
+func TestHandleUpdateToolSessionCommandChangeWaitsForApproval(t *testing.T) {
+	server, _, _ := newToolSessionApprovalTestServer(t)
+	sess := createApprovalTestToolSession(t, server)
+
+	update := func(body string) *httptest.ResponseRecorder {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, "/api/tool-sessions/"+sess.ID, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		ctx := newRoleContext(e, req, rec, "alice", config.RoleMember)
+		ctx.SetPath("/api/tool-sessions/:id")
+		ctx.SetPathValues(echo.PathValues{{Name: "id", Value: sess.ID}})
+		if err := server.handleUpdateToolSession(ctx); err != nil {
+			t.Fatalf("update handler failed: %v", err)
+		}
+		return rec
+	}
+
+	if rec := update(`{"title":"Renamed"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected updates keeping the command to apply, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec := update(`{"command":"rm -rf /tmp/x"}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected command change to wait for approval, got %d: %s", rec.Code, rec.Body.String())
+	}
+	current, err := server.toolSess.GetSession(context.Background(), sess.ID)
+	if err != nil {
+		t.Fatalf("get session: %v", err)
+	}
+	if current.Command != "sleep 5" || current.Title != "Renamed" {
+		t.Fatalf("expected only the title to change, got %q / %q", current.Command, current.Title)
+	}
+}
+
+type toolSessionStreamEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+func parseToolSessionStreamEvents(t *testing.T, body string) []toolSessionStreamEvent {
+	t.Helper()
+	var events []toolSessionStreamEvent
+	for _, block := range strings.Split(body, "\n\n") {
+		var event toolSessionStreamEvent
+		var data []string
+		for _, line := range strings.Split(block, "\n") {
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				event.ID = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				event.Event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = append(data, strings.TrimPrefix(line, "data: "))
+			}
+		}
+		if event.Event == "" {
+			continue
+		}
+		event.Data = strings.Join(data, "\n")
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestHandleToolSessionStreamSendsOutputFromOffsetUntilExit(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() { _ = client.Close() })
+
+	toolMgr, err := toolsessions.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new tool session manager: %v", err)
+	}
+	server := &Server{
+		config:     cfg,
+		logger:     log,
+		toolSess:   toolMgr,
+		processMgr: process.NewManager(log),
+	}
+	e := echo.New()
+
+	command := "printf 'first\\n'; sleep 0.5; printf 'second\\n'"
+	sess, err := toolMgr.CreateSession(context.Background(), toolsessions.CreateSessionInput{
+		Owner:   "alice",
+		Source:  toolsessions.SourceWebUI,
+		Tool:    "shell",
+		Command: command,
+		Workdir: cfg.WorkspacePath(),
+		State:   toolsessions.StateRunning,
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if err := server.processMgr.Start(context.Background(), sess.ID, command, cfg.WorkspacePath()); err != nil {
+		t.Fatalf("start process: %v", err)
+	}
+
+	stream := func(query, lastEventID string) []toolSessionStreamEvent {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/tool-sessions/"+sess.ID+"/stream"+query, nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		rec := httptest.NewRecorder()
+		ctx := newAuthedContext(e, req, rec, "alice")
+		ctx.SetPath("/api/tool-sessions/:id/stream")
+		ctx.SetPathValues(echo.PathValues{{Name: "id", Value: sess.ID}})
+
+		done := make(chan error, 1)
+		go func() { done <- server.handleToolSessionStream(ctx) }()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("stream handler failed: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("stream did not end after the process exited")
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+			t.Fatalf("expected event stream, got %q", got)
+		}
+		return parseToolSessionStreamEvents(t, rec.Body.String())
+	}
+	decodeOutput := func(events []toolSessionStreamEvent) string {
+		t.Helper()
+		var out strings.Builder
+		for _, event := range events {
+			if event.Event != "output" {
+				continue
+			}
+			decoded, err := base64.StdEncoding.DecodeString(event.Data)
+			if err != nil {
+				t.Fatalf("decode output event %q: %v", event.Data, err)
+			}
+			out.Write(decoded)
+		}
+		return out.String()
+	}
+
+	events := stream("", "")
+	full := decodeOutput(events)
+	if !strings.Contains(full, "first") || !strings.Contains(full, "second") {
+		t.Fatalf("expected full output, got %q", full)
+	}
+	last := events[len(events)-1]
+	if last.Event != "exit" || last.Data != `{"exit_code":0}` {
+		t.Fatalf("expected stream to end with an exit event, got %+v", events)
+	}
+	waitForSessionState(t, toolMgr, sess.ID, toolsessions.StateTerminated)
+
+	// Resuming after the first output event replays only what followed it.
+	first := events[0]
+	if first.Event != "output" || first.ID == "" {
+		t.Fatalf("expected an output event with a cursor id first, got %+v", first)
+	}
+	firstData, err := base64.StdEncoding.DecodeString(first.Data)
+	if err != nil {
+		t.Fatalf("decode first output: %v", err)
+	}
+	resumed := decodeOutput(stream("", first.ID))
+	if resumed != strings.TrimPrefix(full, string(firstData)) {
+		t.Fatalf("expected output after cursor %s, got %q (full %q)", first.ID, resumed, full)
+	}
+
+	_, total, err := server.processMgr.GetOutput(sess.ID, 0, 500)
+	if err != nil {
+		t.Fatalf("get output: %v", err)
+	}
+	events = stream("?offset="+strconv.Itoa(total)+"&encoding=raw", "")
+	if len(events) != 1 || events[0].Event != "exit" {
+		t.Fatalf("expected only the exit event past the end of output, got %+v", events)
+	}
+}
+
+func TestToolSessionHandlersStopForOtherUsersSessions(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() { _ = client.Close() })
+
+	toolMgr, err := toolsessions.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new tool session manager: %v", err)
+	}
+	server := &Server{config: cfg, logger: log, toolSess: toolMgr, processMgr: process.NewManager(log)}
+	sess, err := toolMgr.CreateSession(context.Background(), toolsessions.CreateSessionInput{
+		Owner: "alice",
+		Tool:  "codex",
+		State: toolsessions.StateRunning,
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/tool-sessions/"+sess.ID+"/terminate", strings.NewReader(`{"reason":"mine now"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	ctx := newAuthedContext(e, req, rec, "bob")
+	ctx.SetPath("/api/tool-sessions/:id/terminate")
+	ctx.SetPathValues(echo.PathValues{{Name: "id", Value: sess.ID}})
+	if err := server.handleTerminateToolSession(ctx); err != nil {
+		t.Fatalf("terminate handler failed: %v", err)
+	}
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusForbidden, rec.Code, rec.Body.String())
+	}
+	current, err := toolMgr.GetSession(context.Background(), sess.ID)
+	if err != nil {
+		t.Fatalf("get session: %v", err)
+	}
+	if current.State != toolsessions.StateRunning {
+		t.Fatalf("expected the refused request to leave the session running, got %q", current.State)
+	}
+}
+
+func TestToolSessionSpectatorLinkStreamsReadOnly(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Storage.DBDir = t.TempDir()
+	cfg.Agents.Defaults.Workspace = t.TempDir()
+	cfg.WebUI.PublicBaseURL = "https://neko.example.com"
+
+	log := newTestLogger(t)
+	client := newTestEntClient(t, cfg)
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Errorf("close ent client: %v", err)
+		}
+	})
+	toolMgr, err := toolsessions.NewManager(cfg, log, client)
+	if err != nil {
+		t.Fatalf("new tool session manager: %v", err)
+	}
+	server := &Server{
+		config:     cfg,
+		logger:     log,
+		toolSess:   toolMgr,
+		processMgr: process.NewManager(log),
+	}
+	sess, err := toolMgr.CreateSession(context.Background(), toolsessions.CreateSessionInput{
+		Owner: "alice",
+		Tool:  "codex",
+		State: toolsessions.StateRunning,
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	e := echo.New()
+	updateSpectator := func(username, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/tool-sessions/"+sess.ID+"/spectator", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		ctx := newAuthedContext(e, req, rec, username)
+		ctx.SetPath("/api/tool-sessions/:id/spectator")
+		ctx.SetPathValues(echo.PathValues{{Name: "id", Value: sess.ID}})
+		if err := server.handleUpdateToolSessionSpectator(ctx); err != nil {
+			t.Fatalf("spectator handler failed: %v", err)
+		}
+		return rec
+	}
+
+	if rec := updateSpectator("bob", `{"enabled":true}`); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected bob to be refused, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if current, err := toolMgr.GetSession(context.Background(), sess.ID); err != nil || current.SpectatorEnabled {
+		t.Fatalf("expected the refused request to leave the link disabled, got %+v (%v)", current, err)
+	}
+
+	rec := updateSpectator("alice", `{"enabled":true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		SpectatorEnabled bool   `json:"spectator_enabled"`
+		SpectatorURL     string `json:"spectator_url"`
+	}
+	decodeJSON(t, rec.Body.Bytes(), &payload)
+	if !payload.SpectatorEnabled || !strings.HasPrefix(payload.SpectatorURL, "https://neko.example.com/spectate?") {
+		t.Fatalf("unexpected spectator response %+v", payload)
+	}
+	link, err := url.Parse(payload.SpectatorURL)
+	if err != nil {
+		t.Fatalf("parse spectator url: %v", err)
+	}
+	token := link.Query().Get("token")
+
+	wsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = server.handleToolSessionWS(e.NewContext(r, w))
+	}))
+	t.Cleanup(wsServer.Close)
+	wsURL := "ws" + strings.TrimPrefix(wsServer.URL, "http") + "/api/tool-sessions/ws?session_id=" + sess.ID
+
+	if _, resp, err := websocket.DefaultDialer.Dial(wsURL+"&spectator_token=wrong", nil); err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected a wrong spectator token to be refused, got %v", err)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL+"&spectator_token="+url.QueryEscape(token), nil)
+	if err != nil {
+		t.Fatalf("dial spectator ws: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var ready toolWSResponse
+	if err := conn.ReadJSON(&ready); err != nil {
+		t.Fatalf("read ready: %v", err)
+	}
+	if ready.Type != "ready" || !ready.ReadOnly {
+		t.Fatalf("expected a read-only ready event, got %+v", ready)
+	}
+	if current, err := toolMgr.GetSession(context.Background(), sess.ID); err != nil || current.Viewers != 1 {
+		t.Fatalf("expected 1 viewer, got %+v (%v)", current, err)
+	}
+
+	if err := conn.WriteJSON(map[string]string{"type": "kill"}); err != nil {
+		t.Fatalf("write kill: %v", err)
+	}
+	for {
+		var msg toolWSResponse
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("read ws message: %v", err)
+		}
+		if msg.Type == "error" {
+			if !strings.Contains(msg.Message, "read-only") {
+				t.Fatalf("unexpected error message %q", msg.Message)
+			}
+			break
+		}
+	}
+	if current, err := toolMgr.GetSession(context.Background(), sess.ID); err != nil || current.State != toolsessions.StateRunning {
+		t.Fatalf("expected the spectator kill to be ignored, got %+v (%v)", current, err)
+	}
+
+	_ = conn.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for toolMgr.ViewerCount(sess.ID) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the viewer to be released, got %d", toolMgr.ViewerCount(sess.ID))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,274 @@
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/fileutil"
+)
+
+// FS is a workspace file store. Names are slash-separated and relative to
+// the workspace root.
+type FS interface {
+	// Location describes the store for logs and status.
+	Location() string
+	ReadFile(ctx context.Context, name string) ([]byte, error)
+	WriteFile(ctx context.Context, name string, data []byte) error
+	Remove(ctx context.Context, name string) error
+	// List returns the files at or under name; a missing name lists nothing.
+	List(ctx context.Context, name string) ([]FileInfo, error)
+}
+
+// FileInfo describes one file of an FS.
+type FileInfo struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+	// Hash is the hex MD5 of the content, empty when the store cannot tell
+	// without reading the file.
+	Hash string
+}
+
+// ErrNotExist is returned for files an FS does not hold.
+var ErrNotExist = fs.ErrNotExist
+
+// NewFS returns the workspace backend configured in cfg: the local
+// directory root for the local type, or an S3 bucket.
+func NewFS(cfg config.WorkspaceBackendConfig, root string) (FS, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Type)) {
+	case "", "local":
+		return NewLocalFS(root), nil
+	case "s3":
+		return NewS3FS(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unsupported workspace backend %q", cfg.Type)
+	}
+}
+
+// LocalFS is an FS over a local directory.
+type LocalFS struct {
+	root string
+}
+
+// NewLocalFS creates an FS rooted at dir.
+func NewLocalFS(dir string) *LocalFS {
+	return &LocalFS{root: dir}
+}
+
+func (l *LocalFS) Location() string { return l.root }
+
+func (l *LocalFS) path(name string) (string, error) {
+	clean := path.Clean("/" + name)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid workspace path %q", name)
+	}
+	return filepath.Join(l.root, filepath.FromSlash(clean[1:])), nil
+}
+
+func (l *LocalFS) ReadFile(_ context.Context, name string) ([]byte, error) {
+	p, err := l.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(p)
+}
+
+func (l *LocalFS) WriteFile(_ context.Context, name string, data []byte) error {
+	p, err := l.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return fileutil.WriteFileAtomic(p, data, 0o644)
+}
+
+func (l *LocalFS) Remove(_ context.Context, name string) error {
+	p, err := l.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (l *LocalFS) List(_ context.Context, name string) ([]FileInfo, error) {
+	root, err := l.path(name)
+	if err != nil {
+		return nil, err
+	}
+	var files []FileInfo
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == root {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hash, err := fileMD5(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(l.root, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, FileInfo{Name: filepath.ToSlash(rel), Size: info.Size(), ModTime: info.ModTime(), Hash: hash})
+		return nil
+	})
+	return files, err
+}
+
+func fileMD5(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func contentMD5(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// S3FS is an FS over a key prefix of an S3-compatible bucket.
+type S3FS struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3FS connects to the bucket in cfg. No request is made until the FS
+// is used.
+func NewS3FS(cfg config.S3Config) (*S3FS, error) {
+	endpoint := strings.TrimSpace(cfg.Endpoint)
+	endpoint = strings.TrimPrefix(strings.TrimPrefix(endpoint, "https://"), "http://")
+	endpoint = strings.TrimRight(endpoint, "/")
+	bucket := strings.TrimSpace(cfg.Bucket)
+	if endpoint == "" || bucket == "" {
+		return nil, fmt.Errorf("s3 endpoint and bucket are required for the s3 workspace backend")
+	}
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(strings.TrimSpace(cfg.AccessKey), strings.TrimSpace(cfg.SecretKey), ""),
+		Secure: !cfg.Insecure,
+		Region: strings.TrimSpace(cfg.Region),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create s3 client: %w", err)
+	}
+	prefix := strings.TrimLeft(strings.TrimSpace(cfg.Prefix), "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &S3FS{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+func (s *S3FS) Location() string {
+	return "s3://" + s.bucket + "/" + s.prefix
+}
+
+func (s *S3FS) key(name string) (string, error) {
+	clean := path.Clean("/" + name)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid workspace path %q", name)
+	}
+	return s.prefix + clean[1:], nil
+}
+
+func (s *S3FS) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, fmt.Errorf("%s: %w", name, ErrNotExist)
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *S3FS) WriteFile(ctx context.Context, name string, data []byte) error {
+	key, err := s.key(name)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(ctx, s.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{})
+	return err
+}
+
+func (s *S3FS) Remove(ctx context.Context, name string) error {
+	key, err := s.key(name)
+	if err != nil {
+		return err
+	}
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *S3FS) List(ctx context.Context, name string) ([]FileInfo, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return nil, err
+	}
+	var files []FileInfo
+	for _, prefix := range []string{key, key + "/"} {
+		for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+			if obj.Err != nil {
+				return nil, obj.Err
+			}
+			// The bare key prefix also matches siblings such as memory.bak.
+			if prefix == key && obj.Key != key {
+				continue
+			}
+			hash := strings.Trim(obj.ETag, `"`)
+			if strings.Contains(hash, "-") {
+				hash = "" // Multipart ETags are not content hashes.
+			}
+			files = append(files, FileInfo{
+				Name:    strings.TrimPrefix(obj.Key, s.prefix),
+				Size:    obj.Size,
+				ModTime: obj.LastModified,
+				Hash:    hash,
+			})
+		}
+	}
+	return files, nil
+}
@@ -2,6 +2,8 @@ package workspace
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -12,7 +14,9 @@ import (
 // Module provides workspace functionality.
 var Module = fx.Module("workspace",
 	fx.Provide(ProvideManager),
+	fx.Provide(ProvideSyncer),
 	fx.Invoke(InitializeWorkspace),
+	fx.Invoke(registerSyncer),
 )
 
 // ProvideManager creates a workspace manager from configuration.
@@ -41,3 +45,34 @@ func InitializeWorkspace(
 		},
 	})
 }
+
+// ProvideSyncer creates the mirror to the configured workspace backend, or
+// nil for the local backend.
+func ProvideSyncer(cfg *config.Config, log *logger.Logger) (*Syncer, error) {
+	backend := cfg.Agents.Defaults.WorkspaceBackend
+	if t := strings.ToLower(strings.TrimSpace(backend.Type)); t == "" || t == "local" {
+		return nil, nil
+	}
+	remote, err := NewFS(backend, cfg.WorkspacePath())
+	if err != nil {
+		return nil, err
+	}
+	interval := time.Duration(backend.SyncIntervalSeconds) * time.Second
+	return NewSyncer(cfg.WorkspacePath(), remote, backend.Paths, interval, log), nil
+}
+
+// registerSyncer pulls the workspace backend after the workspace is
+// initialized and pushes pending changes on shutdown.
+func registerSyncer(lc fx.Lifecycle, syncer *Syncer) {
+	if syncer == nil {
+		return
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return syncer.Start(ctx)
+		},
+		OnStop: func(ctx context.Context) error {
+			return syncer.Stop(ctx)
+		},
+	})
+}
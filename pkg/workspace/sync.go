@@ -0,0 +1,268 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"nekobot/pkg/logger"
+)
+
+const defaultSyncInterval = 30 * time.Second
+
+// SyncStatus reports the workspace backend mirror.
+type SyncStatus struct {
+	Backend    string     `json:"backend"`
+	Location   string     `json:"location"`
+	Paths      []string   `json:"paths"`
+	Files      int        `json:"files"`
+	LastPullAt *time.Time `json:"last_pull_at,omitempty"`
+	LastPushAt *time.Time `json:"last_push_at,omitempty"`
+	LastError  string     `json:"last_error,omitempty"`
+}
+
+// Syncer mirrors workspace paths between the local workspace, which acts as
+// a cache, and a remote FS. Pull copies remote files down on start; Push
+// uploads local changes and deletions. One gateway should own a remote
+// prefix at a time: concurrent writers overwrite each other.
+type Syncer struct {
+	local    *LocalFS
+	remote   FS
+	paths    []string
+	interval time.Duration
+	log      *logger.Logger
+
+	mu     sync.Mutex        // serializes Pull and Push
+	synced map[string]string // Hash the remote holds, by file name
+	pulled bool
+
+	statusMu sync.Mutex
+	lastPull time.Time
+	lastPush time.Time
+	lastErr  string
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewSyncer mirrors paths, relative to workspaceDir, to remote. A zero
+// interval pushes every 30 seconds.
+func NewSyncer(workspaceDir string, remote FS, paths []string, interval time.Duration, log *logger.Logger) *Syncer {
+	if interval <= 0 {
+		interval = defaultSyncInterval
+	}
+	cleaned := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if p = path.Clean(strings.TrimSpace(p)); p != "." && p != "" {
+			cleaned = append(cleaned, p)
+		}
+	}
+	return &Syncer{
+		local:    NewLocalFS(workspaceDir),
+		remote:   remote,
+		paths:    cleaned,
+		interval: interval,
+		log:      log,
+		synced:   make(map[string]string),
+	}
+}
+
+// Pull copies remote files that differ from the local ones into the
+// workspace. Local files missing remotely are kept and pushed later.
+func (s *Syncer) Pull(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := 0
+	for _, p := range s.paths {
+		remoteFiles, err := s.remote.List(ctx, p)
+		if err != nil {
+			return copied, s.fail("pull", fmt.Errorf("list %s: %w", p, err))
+		}
+		localFiles, err := s.local.List(ctx, p)
+		if err != nil {
+			return copied, s.fail("pull", fmt.Errorf("list local %s: %w", p, err))
+		}
+		localHashes := make(map[string]string, len(localFiles))
+		for _, f := range localFiles {
+			localHashes[f.Name] = f.Hash
+		}
+		for _, f := range remoteFiles {
+			if f.Hash != "" && f.Hash == localHashes[f.Name] {
+				s.synced[f.Name] = f.Hash
+				continue
+			}
+			data, err := s.remote.ReadFile(ctx, f.Name)
+			if err != nil {
+				return copied, s.fail("pull", fmt.Errorf("read %s: %w", f.Name, err))
+			}
+			if err := s.local.WriteFile(ctx, f.Name, data); err != nil {
+				return copied, s.fail("pull", fmt.Errorf("write %s: %w", f.Name, err))
+			}
+			s.synced[f.Name] = contentMD5(data)
+			copied++
+		}
+	}
+	s.pulled = true
+	s.succeed(&s.lastPull)
+	return copied, nil
+}
+
+// Push uploads local files changed since the last sync and removes remote
+// files deleted locally. It does nothing until a Pull has succeeded, so an
+// unreachable backend at startup cannot overwrite remote memory with a
+// fresh workspace.
+func (s *Syncer) Push(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.pulled {
+		return 0, nil
+	}
+
+	changed := 0
+	seen := make(map[string]struct{})
+	for _, p := range s.paths {
+		files, err := s.local.List(ctx, p)
+		if err != nil {
+			return changed, s.fail("push", fmt.Errorf("list local %s: %w", p, err))
+		}
+		for _, f := range files {
+			seen[f.Name] = struct{}{}
+			if s.synced[f.Name] == f.Hash {
+				continue
+			}
+			data, err := s.local.ReadFile(ctx, f.Name)
+			if err != nil {
+				return changed, s.fail("push", fmt.Errorf("read %s: %w", f.Name, err))
+			}
+			if err := s.remote.WriteFile(ctx, f.Name, data); err != nil {
+				return changed, s.fail("push", fmt.Errorf("upload %s: %w", f.Name, err))
+			}
+			s.synced[f.Name] = contentMD5(data)
+			changed++
+		}
+	}
+	for name := range s.synced {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		if err := s.remote.Remove(ctx, name); err != nil {
+			return changed, s.fail("push", fmt.Errorf("remove %s: %w", name, err))
+		}
+		delete(s.synced, name)
+		changed++
+	}
+	s.succeed(&s.lastPush)
+	return changed, nil
+}
+
+// Start pulls the workspace paths, then pushes changes every interval in
+// the background. A failed first pull is retried before any push.
+func (s *Syncer) Start(ctx context.Context) error {
+	if n, err := s.Pull(ctx); err != nil {
+		s.log.Warn("Workspace backend pull failed; retrying before any push",
+			zap.String("location", s.remote.Location()), zap.Error(err))
+	} else {
+		s.log.Info("Workspace pulled from backend",
+			zap.String("location", s.remote.Location()), zap.Int("files", n))
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-loopCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.sync(loopCtx)
+		}
+	}()
+	return nil
+}
+
+func (s *Syncer) sync(ctx context.Context) {
+	s.mu.Lock()
+	pulled := s.pulled
+	s.mu.Unlock()
+	if !pulled {
+		if n, err := s.Pull(ctx); err == nil {
+			s.log.Info("Workspace pulled from backend", zap.Int("files", n))
+		}
+		return
+	}
+	if _, err := s.Push(ctx); err != nil && ctx.Err() == nil {
+		s.log.Warn("Workspace backend push failed", zap.Error(err))
+	}
+}
+
+// Stop ends the background loop and pushes pending changes.
+func (s *Syncer) Stop(ctx context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	if _, err := s.Push(ctx); err != nil {
+		s.log.Warn("Final workspace backend push failed", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Status reports the last sync times and error.
+func (s *Syncer) Status() SyncStatus {
+	s.mu.Lock()
+	files := len(s.synced)
+	s.mu.Unlock()
+
+	status := SyncStatus{
+		Backend:  "s3",
+		Location: s.remote.Location(),
+		Paths:    append([]string(nil), s.paths...),
+		Files:    files,
+	}
+	if _, ok := s.remote.(*LocalFS); ok {
+		status.Backend = "local"
+	}
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	status.LastError = s.lastErr
+	if !s.lastPull.IsZero() {
+		t := s.lastPull
+		status.LastPullAt = &t
+	}
+	if !s.lastPush.IsZero() {
+		t := s.lastPush
+		status.LastPushAt = &t
+	}
+	return status
+}
+
+func (s *Syncer) fail(op string, err error) error {
+	err = fmt.Errorf("workspace %s: %w", op, err)
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	s.statusMu.Lock()
+	s.lastErr = err.Error()
+	s.statusMu.Unlock()
+	return err
+}
+
+func (s *Syncer) succeed(at *time.Time) {
+	s.statusMu.Lock()
+	*at = time.Now()
+	s.lastErr = ""
+	s.statusMu.Unlock()
+}
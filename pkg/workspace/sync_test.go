@@ -0,0 +1,155 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nekobot/pkg/config"
+	"nekobot/pkg/logger"
+)
+
+type unreachableFS struct{ *LocalFS }
+
+func (unreachableFS) List(context.Context, string) ([]FileInfo, error) {
+	return nil, errors.New("connection refused")
+}
+
+func newSyncTestLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+	log, err := logger.New(&logger.Config{Level: logger.LevelError})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return log
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestSyncerPullsThenPushesChanges(t *testing.T) {
+	ctx := context.Background()
+	local, remoteDir := t.TempDir(), t.TempDir()
+	writeTestFile(t, filepath.Join(remoteDir, "MEMORY.md"), "likes tea")
+	writeTestFile(t, filepath.Join(remoteDir, "memory", "sessions", "s1.md"), "export")
+	writeTestFile(t, filepath.Join(remoteDir, "memory.bak"), "not synced")
+	writeTestFile(t, filepath.Join(local, "memory", "2026-10-18.md"), "fresh log")
+	writeTestFile(t, filepath.Join(local, "notes.md"), "not synced")
+
+	syncer := NewSyncer(local, NewLocalFS(remoteDir), []string{"MEMORY.md", "memory"}, 0, newSyncTestLogger(t))
+	pulled, err := syncer.Pull(ctx)
+	if err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+	if pulled != 2 || readTestFile(t, filepath.Join(local, "MEMORY.md")) != "likes tea" {
+		t.Fatalf("unexpected pull of %d files", pulled)
+	}
+	if _, err := os.Stat(filepath.Join(local, "memory.bak")); !os.IsNotExist(err) {
+		t.Fatalf("expected memory.bak to stay remote, got %v", err)
+	}
+
+	writeTestFile(t, filepath.Join(local, "MEMORY.md"), "likes coffee")
+	if err := os.Remove(filepath.Join(local, "memory", "sessions", "s1.md")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	pushed, err := syncer.Push(ctx)
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	// MEMORY.md changed, the daily log is new and the export was deleted.
+	if pushed != 3 {
+		t.Fatalf("expected 3 changes pushed, got %d", pushed)
+	}
+	if got := readTestFile(t, filepath.Join(remoteDir, "MEMORY.md")); got != "likes coffee" {
+		t.Fatalf("remote MEMORY.md = %q", got)
+	}
+	if got := readTestFile(t, filepath.Join(remoteDir, "memory", "2026-10-18.md")); got != "fresh log" {
+		t.Fatalf("remote daily log = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(remoteDir, "memory", "sessions", "s1.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected deleted export to be removed remotely, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(remoteDir, "notes.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected unsynced path to stay local, got %v", err)
+	}
+
+	if pushed, err = syncer.Push(ctx); err != nil || pushed != 0 {
+		t.Fatalf("expected an idle push, got %d (%v)", pushed, err)
+	}
+	status := syncer.Status()
+	if status.Backend != "local" || status.Files != 2 || status.LastPullAt == nil || status.LastPushAt == nil || status.LastError != "" {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
+
+func TestSyncerDoesNotPushBeforePull(t *testing.T) {
+	ctx := context.Background()
+	local, remoteDir := t.TempDir(), t.TempDir()
+	writeTestFile(t, filepath.Join(remoteDir, "MEMORY.md"), "likes tea")
+	writeTestFile(t, filepath.Join(local, "MEMORY.md"), "template")
+
+	syncer := NewSyncer(local, unreachableFS{NewLocalFS(remoteDir)}, []string{"MEMORY.md"}, 0, newSyncTestLogger(t))
+	if _, err := syncer.Pull(ctx); err == nil {
+		t.Fatal("expected pull to fail")
+	}
+	if pushed, err := syncer.Push(ctx); err != nil || pushed != 0 {
+		t.Fatalf("expected no push before a pull, got %d (%v)", pushed, err)
+	}
+	if got := readTestFile(t, filepath.Join(remoteDir, "MEMORY.md")); got != "likes tea" {
+		t.Fatalf("remote memory was overwritten: %q", got)
+	}
+	if status := syncer.Status(); status.LastError == "" {
+		t.Fatalf("expected the pull error in status, got %+v", status)
+	}
+}
+
+func TestNewFSSelectsBackend(t *testing.T) {
+	dir := t.TempDir()
+	fsys, err := NewFS(config.WorkspaceBackendConfig{}, dir)
+	if err != nil || fsys.Location() != dir {
+		t.Fatalf("expected local FS at %s, got %v (%v)", dir, fsys, err)
+	}
+	if _, err := NewFS(config.WorkspaceBackendConfig{Type: "s3"}, dir); err == nil {
+		t.Fatal("expected s3 backend without bucket to fail")
+	}
+	fsys, err = NewFS(config.WorkspaceBackendConfig{Type: "s3", S3: config.S3Config{Endpoint: "minio:9000", Bucket: "ws", Prefix: "bot"}}, dir)
+	if err != nil || fsys.Location() != "s3://ws/bot/" {
+		t.Fatalf("unexpected s3 FS %v (%v)", fsys, err)
+	}
+	if _, err := NewFS(config.WorkspaceBackendConfig{Type: "ftp"}, dir); err == nil {
+		t.Fatal("expected unknown backend to fail")
+	}
+}
+
+func TestLocalFSRejectsEscapes(t *testing.T) {
+	root := t.TempDir()
+	fsys := NewLocalFS(root)
+	if err := fsys.WriteFile(context.Background(), "../outside.md", []byte("x")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	// Clean roots the name, so the file lands inside the workspace.
+	if _, err := os.Stat(filepath.Join(root, "outside.md")); err != nil {
+		t.Fatalf("expected file inside the root: %v", err)
+	}
+	if _, err := fsys.ReadFile(context.Background(), "/"); err == nil {
+		t.Fatal("expected the root itself to be rejected")
+	}
+}